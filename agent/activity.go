@@ -0,0 +1,128 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/clistat"
+	"github.com/coder/quartz"
+)
+
+// procNetDev lists the bytes sent and received by each network interface.
+const procNetDev = "/proc/net/dev"
+
+// activitySampler samples the CPU and network usage of the workspace. The
+// samples are reported with the agent stats, so coderd can consider a
+// workspace active while it runs jobs without any open sessions.
+type activitySampler struct {
+	logger slog.Logger
+	clock  quartz.Clock
+	fs     afero.Fs
+	// cpu returns the number of CPU cores currently in use. It is nil if CPU
+	// usage cannot be measured.
+	cpu func() (float64, error)
+
+	lastNetworkBytes int64
+	lastNetworkAt    time.Time
+}
+
+func newActivitySampler(logger slog.Logger, clock quartz.Clock, fs afero.Fs) *activitySampler {
+	s := &activitySampler{
+		logger: logger,
+		clock:  clock,
+		fs:     fs,
+	}
+	statter, err := clistat.New()
+	if err != nil {
+		logger.Warn(context.Background(), "unable to measure cpu usage for activity", slog.Error(err))
+		return s
+	}
+	s.cpu = func() (float64, error) {
+		// Prefer the usage of the container, since the host may be shared
+		// with other workspaces.
+		if ok, _ := statter.IsContainerized(); ok {
+			res, err := statter.ContainerCPU()
+			if err != nil {
+				return 0, err
+			}
+			if res != nil {
+				return res.Used, nil
+			}
+		}
+		res, err := statter.HostCPU()
+		if err != nil {
+			return 0, err
+		}
+		return res.Used, nil
+	}
+	return s
+}
+
+// update samples the workspace and sets the activity metrics.
+func (s *activitySampler) update(ctx context.Context, m *agentMetrics) {
+	if s.cpu != nil {
+		cores, err := s.cpu()
+		if err != nil {
+			s.logger.Debug(ctx, "sample cpu usage", slog.Error(err))
+		} else {
+			m.cpuUsageCores.Set(cores)
+		}
+	}
+
+	now := s.clock.Now()
+	total, err := s.networkBytes()
+	if err != nil {
+		s.logger.Debug(ctx, "sample network usage", slog.Error(err))
+		return
+	}
+	// The first sample has nothing to compare against, and counters are
+	// reset when interfaces are recreated.
+	if !s.lastNetworkAt.IsZero() && total >= s.lastNetworkBytes {
+		if elapsed := now.Sub(s.lastNetworkAt).Seconds(); elapsed > 0 {
+			m.networkBytesPerSecond.Set(float64(total-s.lastNetworkBytes) / elapsed)
+		}
+	}
+	s.lastNetworkBytes = total
+	s.lastNetworkAt = now
+}
+
+// networkBytes returns the total number of bytes sent and received on all
+// network interfaces except loopback.
+func (s *activitySampler) networkBytes() (int64, error) {
+	data, err := afero.ReadFile(s.fs, procNetDev)
+	if err != nil {
+		return 0, xerrors.Errorf("read %s: %w", procNetDev, err)
+	}
+
+	var total int64
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		iface, counters, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.TrimSpace(iface) == "lo" {
+			// Header lines have no interface name.
+			continue
+		}
+		// The first eight fields are receive counters and the next eight
+		// are transmit counters. Bytes are the first of each.
+		fields := strings.Fields(counters)
+		if len(fields) < 9 {
+			return 0, xerrors.Errorf("unexpected format of interface %q", strings.TrimSpace(iface))
+		}
+		for _, field := range []string{fields[0], fields[8]} {
+			n, err := strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				return 0, xerrors.Errorf("parse bytes of interface %q: %w", strings.TrimSpace(iface), err)
+			}
+			total += n
+		}
+	}
+	return total, scanner.Err()
+}
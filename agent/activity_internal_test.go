@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestActivitySampler(t *testing.T) {
+	t.Parallel()
+
+	writeNetDev := func(t *testing.T, fs afero.Fs, eth0Rx, eth0Tx int64) {
+		t.Helper()
+		data := fmt.Sprintf(`Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 999999     100    0    0    0     0          0         0   999999     100    0    0    0     0       0          0
+  eth0: %d     10    0    0    0     0          0         0   %d     10    0    0    0     0       0          0
+`, eth0Rx, eth0Tx)
+		require.NoError(t, afero.WriteFile(fs, procNetDev, []byte(data), 0o644))
+	}
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	fs := afero.NewMemMapFs()
+	clock := quartz.NewMock(t)
+	metrics := newAgentMetrics(prometheus.NewRegistry())
+	sampler := &activitySampler{
+		logger: testutil.Logger(t),
+		clock:  clock,
+		fs:     fs,
+		cpu: func() (float64, error) {
+			return 1.5, nil
+		},
+	}
+
+	writeNetDev(t, fs, 1000, 1000)
+	sampler.update(ctx, metrics)
+	require.InDelta(t, 1.5, promtestutil.ToFloat64(metrics.cpuUsageCores), 0.001)
+	// The first sample has nothing to compare against.
+	require.Zero(t, promtestutil.ToFloat64(metrics.networkBytesPerSecond))
+
+	// Loopback traffic is ignored.
+	writeNetDev(t, fs, 4000, 8000)
+	clock.Advance(10 * time.Second)
+	sampler.update(ctx, metrics)
+	require.InDelta(t, 1000, promtestutil.ToFloat64(metrics.networkBytesPerSecond), 0.001)
+
+	// Reset counters are skipped.
+	writeNetDev(t, fs, 0, 0)
+	clock.Advance(10 * time.Second)
+	sampler.update(ctx, metrics)
+	require.InDelta(t, 1000, promtestutil.ToFloat64(metrics.networkBytesPerSecond), 0.001)
+
+	writeNetDev(t, fs, 500, 0)
+	clock.Advance(10 * time.Second)
+	sampler.update(ctx, metrics)
+	require.InDelta(t, 50, promtestutil.ToFloat64(metrics.networkBytesPerSecond), 0.001)
+}
@@ -39,6 +39,9 @@ import (
 	"github.com/coder/clistat"
 	"github.com/coder/coder/v2/agent/agentcontainers"
 	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/agent/agentlogforward"
+	"github.com/coder/coder/v2/agent/agentmetadatapush"
+	"github.com/coder/coder/v2/agent/agentproc"
 	"github.com/coder/coder/v2/agent/agentscripts"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/proto"
@@ -92,11 +95,20 @@ type Options struct {
 	Devcontainers                bool
 	DevcontainerAPIOptions       []agentcontainers.Option // Enable Devcontainers for these to be effective.
 	Clock                        quartz.Clock
+	// LogForwarder, if set, receives the startup and script logs in addition
+	// to coderd.
+	LogForwarder *agentlogforward.Forwarder
+	// MetadataPush, if set, holds metadata items pushed by processes in the
+	// workspace. They are reported along with the metadata of the template.
+	MetadataPush *agentmetadatapush.Server
+	// ProcessPriority, if set, adjusts the priority of processes that match
+	// the process priority rules of the template.
+	ProcessPriority *agentproc.Manager
 }
 
 type Client interface {
-	ConnectRPC26(ctx context.Context) (
-		proto.DRPCAgentClient26, tailnetproto.DRPCTailnetClient26, error,
+	ConnectRPC27(ctx context.Context) (
+		proto.DRPCAgentClient27, tailnetproto.DRPCTailnetClient27, error,
 	)
 	RewriteDERPMap(derpMap *tailcfg.DERPMap)
 }
@@ -188,10 +200,14 @@ func New(options Options) Agent {
 		sshMaxTimeout:                      options.SSHMaxTimeout,
 		subsystems:                         options.Subsystems,
 		logSender:                          agentsdk.NewLogSender(options.Logger),
+		logForwarder:                       options.LogForwarder,
+		metadataPush:                       options.MetadataPush,
+		processPriority:                    options.ProcessPriority,
 		blockFileTransfer:                  options.BlockFileTransfer,
 
 		prometheusRegistry: prometheusRegistry,
 		metrics:            newAgentMetrics(prometheusRegistry),
+		activity:           newActivitySampler(options.Logger.Named("activity"), options.Clock, options.Filesystem),
 		execer:             options.Execer,
 
 		devcontainers:       options.Devcontainers,
@@ -269,13 +285,18 @@ type agent struct {
 	reportConnectionsMu     sync.Mutex
 	reportConnections       []*proto.ReportConnectionRequest
 
-	logSender *agentsdk.LogSender
+	logSender    *agentsdk.LogSender
+	logForwarder *agentlogforward.Forwarder
+	metadataPush *agentmetadatapush.Server
+	// processPriority may be nil.
+	processPriority *agentproc.Manager
 
 	prometheusRegistry *prometheus.Registry
 	// metrics are prometheus registered metrics that will be collected and
 	// labeled in Coder with the agent + workspace.
-	metrics *agentMetrics
-	execer  agentexec.Execer
+	metrics  *agentMetrics
+	activity *activitySampler
+	execer   agentexec.Execer
 
 	devcontainers       bool
 	containerAPIOptions []agentcontainers.Option
@@ -329,7 +350,7 @@ func (a *agent) init() {
 		SSHServer:   sshSrv,
 		Filesystem:  a.filesystem,
 		GetScriptLogger: func(logSourceID uuid.UUID) agentscripts.ScriptLogger {
-			return a.logSender.GetScriptLogger(logSourceID)
+			return a.scriptLogger(logSourceID)
 		},
 	})
 	// Register runner metrics. If the prom registry is nil, the metrics
@@ -340,7 +361,7 @@ func (a *agent) init() {
 		agentcontainers.WithExecer(a.execer),
 		agentcontainers.WithCommandEnv(a.sshServer.CommandEnv),
 		agentcontainers.WithScriptLogger(func(logSourceID uuid.UUID) agentcontainers.ScriptLogger {
-			return a.logSender.GetScriptLogger(logSourceID)
+			return a.scriptLogger(logSourceID)
 		}),
 	}
 	containerAPIOpts = append(containerAPIOpts, a.containerAPIOptions...)
@@ -362,6 +383,27 @@ func (a *agent) init() {
 	go a.runLoop()
 }
 
+// scriptLogger returns the logger of the scripts of a log source. The logs
+// are sent to coderd, and to the log forwarder if there is one.
+func (a *agent) scriptLogger(logSourceID uuid.UUID) agentscripts.ScriptLogger {
+	logger := a.logSender.GetScriptLogger(logSourceID)
+	if a.logForwarder == nil {
+		return logger
+	}
+	return forwardingScriptLogger{ScriptLogger: logger, forwarder: a.logForwarder, logSourceID: logSourceID}
+}
+
+type forwardingScriptLogger struct {
+	agentsdk.ScriptLogger
+	forwarder   *agentlogforward.Forwarder
+	logSourceID uuid.UUID
+}
+
+func (l forwardingScriptLogger) Send(ctx context.Context, logs ...agentsdk.Log) error {
+	l.forwarder.Enqueue(l.logSourceID, logs...)
+	return l.ScriptLogger.Send(ctx, logs...)
+}
+
 // runLoop attempts to start the agent in a retry loop.
 // Coder may be offline temporarily, a connection issue
 // may be happening, but regardless after the intermittent
@@ -472,7 +514,7 @@ func (t *trySingleflight) Do(key string, fn func()) {
 	fn()
 }
 
-func (a *agent) reportMetadata(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+func (a *agent) reportMetadata(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 	tickerDone := make(chan struct{})
 	collectDone := make(chan struct{})
 	ctx, cancel := context.WithCancel(ctx)
@@ -633,6 +675,10 @@ func (a *agent) reportMetadata(ctx context.Context, aAPI proto.DRPCAgentClient26
 		reportTimeout   = 30 * time.Second
 		reportError     = make(chan error, 1)
 		reportInFlight  = false
+		// pushedCollectedAts are the collection times of the pushed
+		// metadata items that were last reported, so unchanged items are
+		// not reported again.
+		pushedCollectedAts = make(map[string]time.Time)
 	)
 
 	for {
@@ -653,6 +699,15 @@ func (a *agent) reportMetadata(ctx context.Context, aAPI proto.DRPCAgentClient26
 			a.logger.Debug(ctx, logMsg)
 			reportInFlight = false
 		case <-report:
+			if a.metadataPush != nil {
+				for key, result := range a.metadataPush.Items() {
+					if collectedAt, ok := pushedCollectedAts[key]; ok && collectedAt.Equal(result.CollectedAt) {
+						continue
+					}
+					pushedCollectedAts[key] = result.CollectedAt
+					updatedMetadata[agentsdk.PushedMetadataKeyPrefix+key] = &result
+				}
+			}
 			if len(updatedMetadata) == 0 {
 				continue
 			}
@@ -687,7 +742,7 @@ func (a *agent) reportMetadata(ctx context.Context, aAPI proto.DRPCAgentClient26
 
 // reportLifecycle reports the current lifecycle state once. All state
 // changes are reported in order.
-func (a *agent) reportLifecycle(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+func (a *agent) reportLifecycle(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 	for {
 		select {
 		case <-a.lifecycleUpdate:
@@ -767,7 +822,7 @@ func (a *agent) setLifecycle(state codersdk.WorkspaceAgentLifecycle) {
 }
 
 // reportConnectionsLoop reports connections to the agent for auditing.
-func (a *agent) reportConnectionsLoop(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+func (a *agent) reportConnectionsLoop(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 	for {
 		select {
 		case <-a.reportConnectionsUpdate:
@@ -887,7 +942,7 @@ func (a *agent) reportConnection(id uuid.UUID, connectionType proto.Connection_T
 // fetchServiceBannerLoop fetches the service banner on an interval.  It will
 // not be fetched immediately; the expectation is that it is primed elsewhere
 // (and must be done before the session actually starts).
-func (a *agent) fetchServiceBannerLoop(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+func (a *agent) fetchServiceBannerLoop(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 	ticker := time.NewTicker(a.announcementBannersRefreshInterval)
 	defer ticker.Stop()
 	for {
@@ -923,7 +978,7 @@ func (a *agent) run() (retErr error) {
 	a.sessionToken.Store(&sessionToken)
 
 	// ConnectRPC returns the dRPC connection we use for the Agent and Tailnet v2+ APIs
-	aAPI, tAPI, err := a.client.ConnectRPC26(a.hardCtx)
+	aAPI, tAPI, err := a.client.ConnectRPC27(a.hardCtx)
 	if err != nil {
 		return err
 	}
@@ -940,7 +995,7 @@ func (a *agent) run() (retErr error) {
 	connMan := newAPIConnRoutineManager(a.gracefulCtx, a.hardCtx, a.logger, aAPI, tAPI)
 
 	connMan.startAgentAPI("init notification banners", gracefulShutdownBehaviorStop,
-		func(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+		func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 			bannersProto, err := aAPI.GetAnnouncementBanners(ctx, &proto.GetAnnouncementBannersRequest{})
 			if err != nil {
 				return xerrors.Errorf("fetch service banner: %w", err)
@@ -957,7 +1012,7 @@ func (a *agent) run() (retErr error) {
 	// sending logs gets gracefulShutdownBehaviorRemain because we want to send logs generated by
 	// shutdown scripts.
 	connMan.startAgentAPI("send logs", gracefulShutdownBehaviorRemain,
-		func(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+		func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 			err := a.logSender.SendLoop(ctx, aAPI)
 			if xerrors.Is(err, agentsdk.ErrLogLimitExceeded) {
 				// we don't want this error to tear down the API connection and propagate to the
@@ -976,7 +1031,7 @@ func (a *agent) run() (retErr error) {
 	connMan.startAgentAPI("report metadata", gracefulShutdownBehaviorStop, a.reportMetadata)
 
 	// resources monitor can cease as soon as we start gracefully shutting down.
-	connMan.startAgentAPI("resources monitor", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+	connMan.startAgentAPI("resources monitor", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 		logger := a.logger.Named("resources_monitor")
 		clk := quartz.NewReal()
 		config, err := aAPI.GetResourcesMonitoringConfiguration(ctx, &proto.GetResourcesMonitoringConfigurationRequest{})
@@ -984,6 +1039,19 @@ func (a *agent) run() (retErr error) {
 			return xerrors.Errorf("failed to get resources monitoring configuration: %w", err)
 		}
 
+		// The home directory is always reported so that users can see its
+		// usage, even when the template doesn't monitor it.
+		if home, err := userHomeDir(); err != nil {
+			logger.Warn(ctx, "unable to resolve home directory, its usage won't be reported", slog.Error(err))
+		} else if !slices.ContainsFunc(config.Volumes, func(volume *proto.GetResourcesMonitoringConfigurationResponse_Volume) bool {
+			return volume.Path == home
+		}) {
+			config.Volumes = append(config.Volumes, &proto.GetResourcesMonitoringConfigurationResponse_Volume{
+				Enabled: true,
+				Path:    home,
+			})
+		}
+
 		statfetcher, err := clistat.New()
 		if err != nil {
 			return xerrors.Errorf("failed to create resources fetcher: %w", err)
@@ -1023,7 +1091,7 @@ func (a *agent) run() (retErr error) {
 	connMan.startAgentAPI("handle manifest", gracefulShutdownBehaviorStop, a.handleManifest(manifestOK))
 
 	connMan.startAgentAPI("app health reporter", gracefulShutdownBehaviorStop,
-		func(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+		func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 			if err := manifestOK.wait(ctx); err != nil {
 				return xerrors.Errorf("no manifest: %w", err)
 			}
@@ -1056,7 +1124,7 @@ func (a *agent) run() (retErr error) {
 
 	connMan.startAgentAPI("fetch service banner loop", gracefulShutdownBehaviorStop, a.fetchServiceBannerLoop)
 
-	connMan.startAgentAPI("stats report loop", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+	connMan.startAgentAPI("stats report loop", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 		if err := networkOK.wait(ctx); err != nil {
 			return xerrors.Errorf("no network: %w", err)
 		}
@@ -1071,8 +1139,8 @@ func (a *agent) run() (retErr error) {
 }
 
 // handleManifest returns a function that fetches and processes the manifest
-func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
-	return func(ctx context.Context, aAPI proto.DRPCAgentClient26) error {
+func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
+	return func(ctx context.Context, aAPI proto.DRPCAgentClient27) error {
 		var (
 			sentResult = false
 			err        error
@@ -1135,6 +1203,9 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 		}
 
 		oldManifest := a.manifest.Swap(&manifest)
+		if a.logForwarder != nil {
+			a.logForwarder.SetManifest(manifest)
+		}
 		manifestOK.complete(nil)
 		sentResult = true
 
@@ -1235,7 +1306,7 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 
 func (a *agent) createDevcontainer(
 	ctx context.Context,
-	aAPI proto.DRPCAgentClient26,
+	aAPI proto.DRPCAgentClient27,
 	dc codersdk.WorkspaceAgentDevcontainer,
 	script codersdk.WorkspaceAgentScript,
 ) (err error) {
@@ -1267,8 +1338,8 @@ func (a *agent) createDevcontainer(
 
 // createOrUpdateNetwork waits for the manifest to be set using manifestOK, then creates or updates
 // the tailnet using the information in the manifest
-func (a *agent) createOrUpdateNetwork(manifestOK, networkOK *checkpoint) func(context.Context, proto.DRPCAgentClient26) error {
-	return func(ctx context.Context, aAPI proto.DRPCAgentClient26) (retErr error) {
+func (a *agent) createOrUpdateNetwork(manifestOK, networkOK *checkpoint) func(context.Context, proto.DRPCAgentClient27) error {
+	return func(ctx context.Context, aAPI proto.DRPCAgentClient27) (retErr error) {
 		if err := manifestOK.wait(ctx); err != nil {
 			return xerrors.Errorf("no manifest: %w", err)
 		}
@@ -1292,6 +1363,12 @@ func (a *agent) createOrUpdateNetwork(manifestOK, networkOK *checkpoint) func(co
 				manifest.DERPMap,
 				manifest.DERPForceWebSockets,
 				manifest.DisableDirectConnections,
+				// Servers older than API v2.7 leave these unset, and
+				// tailnet keeps its defaults for zero values.
+				manifest.NetcheckInterval,
+				manifest.WireguardMTU,
+				manifest.WireguardKeepaliveInterval,
+				manifest.WireguardHandshakeTimeout,
 				keySeed,
 			)
 			if err != nil {
@@ -1376,6 +1453,12 @@ func (a *agent) updateCommandEnv(current []string) (updated []string, err error)
 		envs["VSCODE_PROXY_URI"] = manifest.VSCodePortProxyURI
 	}
 
+	// Processes in the workspace can push metadata to the agent.
+	if a.metadataPush != nil {
+		envs["CODER_AGENT_METADATA_SOCKET"] = a.metadataPush.SocketPath()
+		envs["CODER_AGENT_METADATA_TOKEN"] = a.metadataPush.Token()
+	}
+
 	// Allow any of the current env to override what we defined above.
 	for _, env := range current {
 		parts := strings.SplitN(env, "=", 2)
@@ -1445,6 +1528,9 @@ func (a *agent) createTailnet(
 	agentID uuid.UUID,
 	derpMap *tailcfg.DERPMap,
 	derpForceWebSockets, disableDirectConnections bool,
+	netcheckInterval time.Duration,
+	wireguardMTU int,
+	wireguardKeepaliveInterval, wireguardHandshakeTimeout time.Duration,
 	keySeed int64,
 ) (_ *tailnet.Conn, err error) {
 	// Inject `CODER_AGENT_HEADER` into the DERP header.
@@ -1463,6 +1549,10 @@ func (a *agent) createTailnet(
 		Logger:              a.logger.Named("net.tailnet"),
 		ListenPort:          a.tailnetListenPort,
 		BlockEndpoints:      disableDirectConnections,
+		NetcheckInterval:    netcheckInterval,
+		MTU:                 wireguardMTU,
+		KeepaliveInterval:   wireguardKeepaliveInterval,
+		HandshakeTimeout:    wireguardHandshakeTimeout,
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("create tailnet: %w", err)
@@ -1771,6 +1861,7 @@ func (a *agent) Collect(ctx context.Context, networkStats map[netlogtype.Connect
 	// currentConnections behaves like a hypothetical `GaugeFuncVec` and is only set at collection time.
 	a.metrics.currentConnections.WithLabelValues("p2p").Set(float64(p2pConns))
 	a.metrics.currentConnections.WithLabelValues("derp").Set(float64(derpConns))
+	a.activity.update(ctx, a.metrics)
 	metricsCtx, cancelFunc := context.WithTimeout(ctx, 5*time.Second)
 	defer cancelFunc()
 	a.logger.Debug(ctx, "collecting agent metrics for stats")
@@ -2047,7 +2138,7 @@ const (
 
 type apiConnRoutineManager struct {
 	logger    slog.Logger
-	aAPI      proto.DRPCAgentClient26
+	aAPI      proto.DRPCAgentClient27
 	tAPI      tailnetproto.DRPCTailnetClient24
 	eg        *errgroup.Group
 	stopCtx   context.Context
@@ -2056,7 +2147,7 @@ type apiConnRoutineManager struct {
 
 func newAPIConnRoutineManager(
 	gracefulCtx, hardCtx context.Context, logger slog.Logger,
-	aAPI proto.DRPCAgentClient26, tAPI tailnetproto.DRPCTailnetClient24,
+	aAPI proto.DRPCAgentClient27, tAPI tailnetproto.DRPCTailnetClient24,
 ) *apiConnRoutineManager {
 	// routines that remain in operation during graceful shutdown use the remainCtx.  They'll still
 	// exit if the errgroup hits an error, which usually means a problem with the conn.
@@ -2089,7 +2180,7 @@ func newAPIConnRoutineManager(
 // but for Tailnet.
 func (a *apiConnRoutineManager) startAgentAPI(
 	name string, behavior gracefulShutdownBehavior,
-	f func(context.Context, proto.DRPCAgentClient26) error,
+	f func(context.Context, proto.DRPCAgentClient27) error,
 ) {
 	logger := a.logger.With(slog.F("name", name))
 	var ctx context.Context
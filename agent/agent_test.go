@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -49,6 +50,8 @@ import (
 
 	"github.com/coder/coder/v2/agent"
 	"github.com/coder/coder/v2/agent/agentcontainers"
+	"github.com/coder/coder/v2/agent/agentmetadatapush"
+	"github.com/coder/coder/v2/agent/agentproc"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/agenttest"
 	"github.com/coder/coder/v2/agent/proto"
@@ -1360,6 +1363,87 @@ func TestAgent_Metadata(t *testing.T) {
 			t.Fatalf("expected metadata to be collected again")
 		}
 	})
+
+	t.Run("Pushed", func(t *testing.T) {
+		t.Parallel()
+
+		metadataPush, err := agentmetadatapush.New(agentmetadatapush.Options{
+			Logger: testutil.Logger(t),
+		})
+		require.NoError(t, err)
+		//nolint:dogsled
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, opts *agent.Options) {
+			opts.ReportMetadataInterval = testutil.IntervalFast
+			opts.MetadataPush = metadataPush
+		})
+
+		req := httptest.NewRequest(http.MethodPut, "/api/v0/metadata/build", strings.NewReader(`{"value":"42%"}`))
+		req.Header.Set("Authorization", "Bearer "+metadataPush.Token())
+		rw := httptest.NewRecorder()
+		metadataPush.Handler().ServeHTTP(rw, req)
+		require.Equal(t, http.StatusNoContent, rw.Code)
+
+		require.Eventually(t, func() bool {
+			md, ok := client.GetMetadata()[agentsdk.PushedMetadataKeyPrefix+"build"]
+			return ok && md.Value == "42%"
+		}, testutil.WaitShort, testutil.IntervalFast/2)
+	})
+}
+
+func TestAgent_ManagedProcesses(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:dogsled
+		conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+		resp, err := conn.ManagedProcesses(ctx)
+		require.NoError(t, err)
+		require.False(t, resp.Enabled)
+		require.Empty(t, resp.Processes)
+	})
+
+	t.Run("Rules", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS != "linux" {
+			t.Skip("process priority management is only supported on linux")
+		}
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// The argument makes the command line unique among the processes
+		// of the host.
+		cmd := exec.CommandContext(ctx, "sleep", "31415")
+		require.NoError(t, cmd.Start())
+		t.Cleanup(func() {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+		})
+
+		rules, err := agentproc.ParseRules(`[{"pattern": "sleep 31415", "nice": 12, "oom_score_adj": 900}]`)
+		require.NoError(t, err)
+		manager := agentproc.New(agentproc.Options{
+			Logger: testutil.Logger(t),
+			Rules:  rules,
+		})
+		require.NoError(t, manager.Scan(ctx))
+
+		//nolint:dogsled
+		conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, opts *agent.Options) {
+			opts.ProcessPriority = manager
+		})
+		resp, err := conn.ManagedProcesses(ctx)
+		require.NoError(t, err)
+		require.True(t, resp.Enabled)
+		require.Len(t, resp.Rules, 1)
+		require.Len(t, resp.Processes, 1)
+		require.EqualValues(t, cmd.Process.Pid, resp.Processes[0].PID)
+		require.Equal(t, "sleep", resp.Processes[0].Name)
+		require.Empty(t, resp.Processes[0].Error)
+		require.Equal(t, 12, resp.Processes[0].Nice)
+		require.Equal(t, 900, resp.Processes[0].OOMScoreAdj)
+	})
 }
 
 func TestAgentMetadata_Timing(t *testing.T) {
@@ -3304,6 +3388,12 @@ func TestAgent_Metrics_SSH(t *testing.T) {
 			Type:  proto.Stats_Metric_COUNTER,
 			Value: 0,
 		},
+		{
+			Name: "coderd_agentstats_cpu_usage_cores",
+			Type: proto.Stats_Metric_GAUGE,
+			// The workspace may be busy with anything.
+			Value: math.MaxFloat64,
+		},
 		{
 			Name:  "coderd_agentstats_currently_reachable_peers",
 			Type:  proto.Stats_Metric_GAUGE,
@@ -3326,6 +3416,11 @@ func TestAgent_Metrics_SSH(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:  "coderd_agentstats_network_bytes_per_second",
+			Type:  proto.Stats_Metric_GAUGE,
+			Value: math.MaxFloat64,
+		},
 		{
 			Name:  "coderd_agentstats_startup_script_seconds",
 			Type:  proto.Stats_Metric_GAUGE,
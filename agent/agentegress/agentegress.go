@@ -0,0 +1,288 @@
+// Package agentegress enforces the egress policy of a workspace's template on
+// the workspace host. Outbound connections to destinations the policy does
+// not allow are blocked by the host firewall and reported to coderd, which
+// records them in the audit log.
+package agentegress
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/quartz"
+)
+
+// refreshInterval is how often the policy is fetched, its domains are
+// resolved again and blocked connections are reported.
+const refreshInterval = time.Minute
+
+// maxPendingViolations bounds the violations kept while coderd cannot be
+// reached.
+const maxPendingViolations = 10 * agentsdk.MaxEgressViolationsPerReport
+
+// Client fetches the egress policy and reports violations to coderd.
+type Client interface {
+	EgressPolicy(ctx context.Context) (agentsdk.EgressPolicy, error)
+	ReportEgressViolations(ctx context.Context, req agentsdk.ReportEgressViolationsRequest) error
+}
+
+// Firewall restricts the outbound connections of the workspace host.
+type Firewall interface {
+	// Apply blocks outbound connections to destinations outside of the
+	// allowed prefixes, replacing any restrictions applied before. Loopback
+	// traffic, DNS and replies to inbound connections are always allowed.
+	Apply(ctx context.Context, allowed []netip.Prefix) error
+	// Violations returns the connections that were blocked since the last
+	// call.
+	Violations(ctx context.Context) ([]agentsdk.EgressViolation, error)
+	// Remove lifts the restrictions.
+	Remove(ctx context.Context) error
+}
+
+// Resolver resolves the domains of the policy. *net.Resolver implements it.
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+type Options struct {
+	Logger slog.Logger
+	Client Client
+	// CoderURL is the URL of the deployment. Its addresses are always
+	// allowed so the agent stays connected.
+	CoderURL *url.URL
+	// Firewall defaults to the firewall of the platform: nftables on Linux
+	// and Windows Firewall on Windows.
+	Firewall Firewall
+	Resolver Resolver
+	Clock    quartz.Clock
+}
+
+// Enforcer keeps the firewall of the workspace host in line with the egress
+// policy of the template.
+type Enforcer struct {
+	logger   slog.Logger
+	client   Client
+	coderURL *url.URL
+	resolver Resolver
+	clock    quartz.Clock
+
+	mu       sync.Mutex
+	firewall Firewall
+	// resolved caches the addresses of each domain, so a failed lookup does
+	// not block a destination that was reachable before.
+	resolved  map[string][]netip.Addr
+	applied   []netip.Prefix
+	enforcing bool
+	cleaned   bool
+	pending   []agentsdk.EgressViolation
+}
+
+func New(opts Options) *Enforcer {
+	if opts.Resolver == nil {
+		opts.Resolver = net.DefaultResolver
+	}
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+	return &Enforcer{
+		logger:   opts.Logger.Named("egress"),
+		client:   opts.Client,
+		coderURL: opts.CoderURL,
+		resolver: opts.Resolver,
+		clock:    opts.Clock,
+		firewall: opts.Firewall,
+		resolved: map[string][]netip.Addr{},
+	}
+}
+
+// Run enforces the policy until the context is canceled. Restrictions are
+// left in place when it returns, so connections stay blocked while the agent
+// restarts.
+func (e *Enforcer) Run(ctx context.Context) {
+	refresh := func() error {
+		err := e.Refresh(ctx)
+		if err != nil && ctx.Err() == nil {
+			e.logger.Error(ctx, "enforce egress policy", slog.Error(err))
+		}
+		return nil
+	}
+	_ = refresh()
+	tkr := e.clock.TickerFunc(ctx, refreshInterval, refresh, "agentegress", "refresh")
+	_ = tkr.Wait()
+}
+
+// Refresh fetches the policy, updates the firewall if the allowed
+// destinations changed and reports blocked connections.
+func (e *Enforcer) Refresh(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	policy, err := e.client.EgressPolicy(ctx)
+	if err != nil {
+		return xerrors.Errorf("fetch egress policy: %w", err)
+	}
+
+	if !policy.Enforced {
+		switch {
+		case e.enforcing:
+			err = e.firewall.Remove(ctx)
+			if err != nil {
+				return xerrors.Errorf("remove egress restrictions: %w", err)
+			}
+			e.logger.Info(ctx, "egress restrictions lifted")
+			e.enforcing = false
+			e.applied = nil
+		case !e.cleaned:
+			// Restrictions outlive the agent, so remove any left behind
+			// in case the policy was deleted while the agent was down.
+			e.cleaned = true
+			if e.platformFirewall() == nil {
+				err = e.firewall.Remove(ctx)
+				if err != nil {
+					e.logger.Debug(ctx, "remove stale egress restrictions", slog.Error(err))
+				}
+			}
+		}
+		return nil
+	}
+
+	err = e.platformFirewall()
+	if err != nil {
+		return xerrors.Errorf("the template restricts egress, but it cannot be enforced: %w", err)
+	}
+
+	allowed := e.allowed(ctx, policy)
+	if !e.enforcing || !slices.Equal(allowed, e.applied) {
+		err = e.firewall.Apply(ctx, allowed)
+		if err != nil {
+			return xerrors.Errorf("apply egress restrictions: %w", err)
+		}
+		e.logger.Info(ctx, "egress restrictions applied", slog.F("allowed", len(allowed)))
+		e.enforcing = true
+		e.applied = allowed
+	}
+
+	return e.report(ctx)
+}
+
+// platformFirewall sets up the firewall of the platform unless one was
+// configured.
+func (e *Enforcer) platformFirewall() error {
+	if e.firewall != nil {
+		return nil
+	}
+	firewall, err := newFirewall(e.logger)
+	if err != nil {
+		return err
+	}
+	e.firewall = firewall
+	return nil
+}
+
+// allowed returns the prefixes allowed by the policy, sorted and without
+// duplicates.
+func (e *Enforcer) allowed(ctx context.Context, policy agentsdk.EgressPolicy) []netip.Prefix {
+	var allowed []netip.Prefix
+	for _, raw := range policy.AllowedCIDRs {
+		prefix, err := netip.ParsePrefix(raw)
+		if err != nil {
+			e.logger.Warn(ctx, "skip invalid egress cidr", slog.F("cidr", raw), slog.Error(err))
+			continue
+		}
+		allowed = append(allowed, prefix.Masked())
+	}
+
+	domains := slices.Clone(policy.AllowedDomains)
+	if e.coderURL != nil {
+		host := e.coderURL.Hostname()
+		if addr, err := netip.ParseAddr(host); err == nil {
+			allowed = append(allowed, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+		} else if host != "" {
+			domains = append(domains, host)
+		}
+	}
+	for _, domain := range domains {
+		for _, addr := range e.resolve(ctx, domain) {
+			allowed = append(allowed, netip.PrefixFrom(addr, addr.BitLen()))
+		}
+	}
+
+	slices.SortFunc(allowed, func(a, b netip.Prefix) int {
+		if c := a.Addr().Compare(b.Addr()); c != 0 {
+			return c
+		}
+		return a.Bits() - b.Bits()
+	})
+	return slices.Compact(allowed)
+}
+
+// resolve returns the addresses of the domain, or the addresses it resolved
+// to before if the lookup fails.
+func (e *Enforcer) resolve(ctx context.Context, domain string) []netip.Addr {
+	addrs, err := e.resolver.LookupNetIP(ctx, "ip", domain)
+	if err != nil {
+		e.logger.Warn(ctx, "resolve egress domain", slog.F("domain", domain), slog.Error(err))
+		return e.resolved[domain]
+	}
+	for i, addr := range addrs {
+		addrs[i] = addr.Unmap()
+	}
+	e.resolved[domain] = addrs
+	return addrs
+}
+
+// report sends the blocked connections to coderd. Repeated connections to
+// the same destination are reported once. Violations that could not be
+// reported are retried on the next refresh.
+func (e *Enforcer) report(ctx context.Context) error {
+	violations, err := e.firewall.Violations(ctx)
+	if err != nil {
+		return xerrors.Errorf("get egress violations: %w", err)
+	}
+
+	type destination struct {
+		addr     string
+		port     uint16
+		protocol string
+	}
+	seen := map[destination]int{}
+	for i, v := range e.pending {
+		seen[destination{v.Destination, v.Port, v.Protocol}] = i
+	}
+	for _, v := range violations {
+		key := destination{v.Destination, v.Port, v.Protocol}
+		if i, ok := seen[key]; ok {
+			if v.BlockedAt.Before(e.pending[i].BlockedAt) {
+				e.pending[i].BlockedAt = v.BlockedAt
+			}
+			continue
+		}
+		seen[key] = len(e.pending)
+		e.pending = append(e.pending, v)
+	}
+	if dropped := len(e.pending) - maxPendingViolations; dropped > 0 {
+		e.logger.Warn(ctx, "dropping unreported egress violations", slog.F("count", dropped))
+		e.pending = slices.Clone(e.pending[dropped:])
+	}
+
+	for len(e.pending) > 0 {
+		batch := e.pending[:min(len(e.pending), agentsdk.MaxEgressViolationsPerReport)]
+		err = e.client.ReportEgressViolations(ctx, agentsdk.ReportEgressViolationsRequest{
+			Violations: batch,
+		})
+		if err != nil {
+			return xerrors.Errorf("report egress violations: %w", err)
+		}
+		e.pending = e.pending[len(batch):]
+	}
+	e.pending = nil
+	return nil
+}
@@ -0,0 +1,239 @@
+package agentegress_test
+
+import (
+	"context"
+	"net/netip"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent/agentegress"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestEnforcer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NotEnforced", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := &fakeClient{}
+		firewall := &fakeFirewall{}
+		e := newEnforcer(t, client, firewall, fakeResolver{})
+
+		require.NoError(t, e.Refresh(ctx))
+		require.Nil(t, firewall.allowed)
+		// Restrictions left behind by a previous agent are removed once.
+		require.Equal(t, 1, firewall.removed)
+		require.NoError(t, e.Refresh(ctx))
+		require.Equal(t, 1, firewall.removed)
+	})
+
+	t.Run("Enforced", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := &fakeClient{policy: agentsdk.EgressPolicy{
+			Enforced:       true,
+			AllowedCIDRs:   []string{"10.0.0.0/8", "10.1.0.0/16"},
+			AllowedDomains: []string{"github.com"},
+		}}
+		firewall := &fakeFirewall{}
+		e := newEnforcer(t, client, firewall, fakeResolver{
+			"github.com":        {netip.MustParseAddr("140.82.112.3"), netip.MustParseAddr("::ffff:140.82.112.4")},
+			"coder.example.com": {netip.MustParseAddr("203.0.113.10")},
+		})
+
+		require.NoError(t, e.Refresh(ctx))
+		require.Equal(t, []netip.Prefix{
+			netip.MustParsePrefix("10.0.0.0/8"),
+			netip.MustParsePrefix("10.1.0.0/16"),
+			netip.MustParsePrefix("140.82.112.3/32"),
+			netip.MustParsePrefix("140.82.112.4/32"),
+			netip.MustParsePrefix("203.0.113.10/32"),
+		}, firewall.allowed)
+		require.Equal(t, 1, firewall.applied)
+
+		// Unchanged destinations are not applied again.
+		require.NoError(t, e.Refresh(ctx))
+		require.Equal(t, 1, firewall.applied)
+
+		client.setPolicy(agentsdk.EgressPolicy{})
+		require.NoError(t, e.Refresh(ctx))
+		require.Equal(t, 1, firewall.removed)
+	})
+
+	t.Run("ResolveFailure", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := &fakeClient{policy: agentsdk.EgressPolicy{
+			Enforced:       true,
+			AllowedDomains: []string{"github.com"},
+		}}
+		firewall := &fakeFirewall{}
+		resolver := fakeResolver{"github.com": {netip.MustParseAddr("140.82.112.3")}}
+		e := newEnforcer(t, client, firewall, resolver)
+
+		require.NoError(t, e.Refresh(ctx))
+		delete(resolver, "github.com")
+		// The addresses resolved before stay allowed.
+		require.NoError(t, e.Refresh(ctx))
+		require.Equal(t, []netip.Prefix{netip.MustParsePrefix("140.82.112.3/32")}, firewall.allowed)
+		require.Equal(t, 1, firewall.applied)
+	})
+
+	t.Run("Violations", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := &fakeClient{
+			policy:    agentsdk.EgressPolicy{Enforced: true},
+			reportErr: xerrors.New("unavailable"),
+		}
+		now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+		firewall := &fakeFirewall{violations: []agentsdk.EgressViolation{
+			{Destination: "203.0.113.7", Port: 443, Protocol: "tcp", BlockedAt: now},
+			{Destination: "203.0.113.7", Port: 443, Protocol: "tcp", BlockedAt: now.Add(-time.Minute)},
+			{Destination: "203.0.113.8", Port: 53, Protocol: "udp", BlockedAt: now},
+		}}
+		e := newEnforcer(t, client, firewall, fakeResolver{})
+
+		require.Error(t, e.Refresh(ctx))
+
+		// Violations are retried once coderd can be reached.
+		client.mu.Lock()
+		client.reportErr = nil
+		client.mu.Unlock()
+		require.NoError(t, e.Refresh(ctx))
+		require.Equal(t, []agentsdk.EgressViolation{
+			{Destination: "203.0.113.7", Port: 443, Protocol: "tcp", BlockedAt: now.Add(-time.Minute)},
+			{Destination: "203.0.113.8", Port: 53, Protocol: "udp", BlockedAt: now},
+		}, client.reported)
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		mClock := quartz.NewMock(t)
+		trap := mClock.Trap().TickerFunc("agentegress", "refresh")
+		defer trap.Close()
+		client := &fakeClient{policy: agentsdk.EgressPolicy{Enforced: true}}
+		firewall := &fakeFirewall{}
+		e := agentegress.New(agentegress.Options{
+			Logger:   slogtest.Make(t, nil),
+			Client:   client,
+			Firewall: firewall,
+			Resolver: fakeResolver{},
+			Clock:    mClock,
+		})
+
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			e.Run(ctx)
+		}()
+		trap.MustWait(ctx).MustRelease(ctx)
+		require.Equal(t, 1, firewall.appliedCount())
+
+		client.setPolicy(agentsdk.EgressPolicy{Enforced: true, AllowedCIDRs: []string{"10.0.0.0/8"}})
+		mClock.Advance(time.Minute).MustWait(ctx)
+		require.Equal(t, 2, firewall.appliedCount())
+
+		cancel()
+		<-done
+	})
+}
+
+func newEnforcer(t *testing.T, client *fakeClient, firewall *fakeFirewall, resolver fakeResolver) *agentegress.Enforcer {
+	t.Helper()
+	return agentegress.New(agentegress.Options{
+		Logger:   slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}),
+		Client:   client,
+		CoderURL: &url.URL{Scheme: "https", Host: "coder.example.com"},
+		Firewall: firewall,
+		Resolver: resolver,
+		Clock:    quartz.NewMock(t),
+	})
+}
+
+type fakeClient struct {
+	mu        sync.Mutex
+	policy    agentsdk.EgressPolicy
+	reportErr error
+	reported  []agentsdk.EgressViolation
+}
+
+func (c *fakeClient) setPolicy(policy agentsdk.EgressPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.policy = policy
+}
+
+func (c *fakeClient) EgressPolicy(context.Context) (agentsdk.EgressPolicy, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.policy, nil
+}
+
+func (c *fakeClient) ReportEgressViolations(_ context.Context, req agentsdk.ReportEgressViolationsRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.reportErr != nil {
+		return c.reportErr
+	}
+	c.reported = append(c.reported, req.Violations...)
+	return nil
+}
+
+type fakeFirewall struct {
+	mu         sync.Mutex
+	allowed    []netip.Prefix
+	applied    int
+	removed    int
+	violations []agentsdk.EgressViolation
+}
+
+func (f *fakeFirewall) appliedCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.applied
+}
+
+func (f *fakeFirewall) Apply(_ context.Context, allowed []netip.Prefix) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.allowed = allowed
+	f.applied++
+	return nil
+}
+
+func (f *fakeFirewall) Violations(context.Context) ([]agentsdk.EgressViolation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	violations := f.violations
+	f.violations = nil
+	return violations, nil
+}
+
+func (f *fakeFirewall) Remove(context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.removed++
+	return nil
+}
+
+type fakeResolver map[string][]netip.Addr
+
+func (r fakeResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, xerrors.Errorf("no such host %q", host)
+	}
+	return append([]netip.Addr(nil), addrs...), nil
+}
@@ -0,0 +1,68 @@
+package agentegress
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+func TestNFTRuleset(t *testing.T) {
+	t.Parallel()
+
+	ruleset := nftRuleset([]netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("140.82.112.3/32"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	})
+	require.Contains(t, ruleset, "elements = { 10.0.0.0/8, 140.82.112.3/32 }")
+	require.Contains(t, ruleset, "elements = { 2001:db8::/32 }")
+	require.Contains(t, ruleset, "table inet coder_egress\ndelete table inet coder_egress\n")
+
+	// Sets without elements must not declare any.
+	ruleset = nftRuleset(nil)
+	require.NotContains(t, ruleset, "elements")
+}
+
+func TestParseNFTSet(t *testing.T) {
+	t.Parallel()
+
+	elements, err := parseNFTSet([]byte(`{"nftables": [
+		{"metainfo": {"version": "1.0.9", "json_schema_version": 1}},
+		{"set": {"family": "inet", "name": "denied_v4", "table": "coder_egress", "type": ["ipv4_addr", "inet_proto", "inet_service"], "flags": ["timeout", "dynamic"], "timeout": 3600, "elem": [
+			{"elem": {"val": {"concat": ["203.0.113.7", "tcp", 443]}, "timeout": 3600, "expires": 3540}},
+			{"concat": ["203.0.113.8", 17, "53"]}
+		]}}
+	]}`))
+	require.NoError(t, err)
+	require.Equal(t, []nftElement{
+		{addr: netip.MustParseAddr("203.0.113.7"), protocol: "tcp", port: 443, age: time.Minute},
+		{addr: netip.MustParseAddr("203.0.113.8"), protocol: "udp", port: 53},
+	}, elements)
+	require.Equal(t, "203.0.113.7 . tcp . 443", elements[0].literal())
+
+	elements, err = parseNFTSet([]byte(`{"nftables": [{"set": {"name": "denied_v6"}}]}`))
+	require.NoError(t, err)
+	require.Empty(t, elements)
+}
+
+func TestParseWindowsFirewallLog(t *testing.T) {
+	t.Parallel()
+
+	violations := parseWindowsFirewallLog([]byte(`#Version: 1.5
+#Software: Microsoft Windows Firewall
+#Fields: date time action protocol src-ip dst-ip src-port dst-port size tcpflags tcpsyn tcpack tcpwin icmptype icmpcode info path pid
+2024-06-01 12:00:00 DROP TCP 10.0.0.5 203.0.113.7 51234 443 0 - 0 0 0 - - - SEND 4242
+2024-06-01 12:00:01 DROP UDP 10.0.0.5 203.0.113.8 51235 123 0 - - - - - - - SEND
+2024-06-01 12:00:02 DROP TCP 203.0.113.9 10.0.0.5 51236 22 0 - 0 0 0 - - - RECEIVE
+2024-06-01 12:00:03 DROP ICMP 10.0.0.5 203.0.113.7 - - 0 - - - - 8 0 - SEND
+2024-06-01 12:00:04 ALLOW TCP 10.0.0.5 140.82.112.3 51237 443 0 - 0 0 0 - - - SEND
+`), time.UTC)
+	require.Equal(t, []agentsdk.EgressViolation{
+		{Destination: "203.0.113.7", Port: 443, Protocol: "tcp", BlockedAt: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{Destination: "203.0.113.8", Port: 123, Protocol: "udp", BlockedAt: time.Date(2024, 6, 1, 12, 0, 1, 0, time.UTC)},
+	}, violations)
+}
@@ -0,0 +1,7 @@
+package agentegress
+
+import "cdr.dev/slog"
+
+func newFirewall(slog.Logger) (Firewall, error) {
+	return newNFTables()
+}
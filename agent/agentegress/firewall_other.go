@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package agentegress
+
+import (
+	"runtime"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+)
+
+func newFirewall(slog.Logger) (Firewall, error) {
+	return nil, xerrors.Errorf("egress policies are not supported on %s", runtime.GOOS)
+}
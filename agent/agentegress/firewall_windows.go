@@ -0,0 +1,13 @@
+package agentegress
+
+import (
+	"os"
+	"path/filepath"
+
+	"cdr.dev/slog"
+)
+
+func newFirewall(slog.Logger) (Firewall, error) {
+	logPath := filepath.Join(os.Getenv("SystemRoot"), "System32", "LogFiles", "Firewall", "coder-egress.log")
+	return newWindowsFirewall(logPath)
+}
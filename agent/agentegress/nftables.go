@@ -0,0 +1,283 @@
+package agentegress
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/netip"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/quartz"
+)
+
+// nftTable is the nftables table holding the egress restrictions. It is
+// replaced as a whole, so rules of other tables are left untouched.
+const nftTable = "coder_egress"
+
+// nftDeniedSets record blocked connections for an hour, which is plenty of
+// time for the agent to pick them up.
+var nftDeniedSets = []string{"denied_v4", "denied_v6"}
+
+// nftables enforces egress restrictions with the nft command line tool,
+// which requires the agent to run as root or with CAP_NET_ADMIN.
+type nftables struct {
+	clock quartz.Clock
+	path  string
+}
+
+func newNFTables() (*nftables, error) {
+	path, err := exec.LookPath("nft")
+	if err != nil {
+		return nil, xerrors.Errorf("nftables is not installed: %w", err)
+	}
+	return &nftables{
+		clock: quartz.NewReal(),
+		path:  path,
+	}, nil
+}
+
+func (n *nftables) Apply(ctx context.Context, allowed []netip.Prefix) error {
+	_, err := n.run(ctx, nftRuleset(allowed), "-f", "-")
+	return err
+}
+
+func (n *nftables) Remove(ctx context.Context) error {
+	// Declaring the table first makes deleting it succeed if it does not
+	// exist.
+	_, err := n.run(ctx, fmt.Sprintf("table inet %[1]s\ndelete table inet %[1]s\n", nftTable), "-f", "-")
+	return err
+}
+
+func (n *nftables) Violations(ctx context.Context) ([]agentsdk.EgressViolation, error) {
+	var violations []agentsdk.EgressViolation
+	for _, set := range nftDeniedSets {
+		out, err := n.run(ctx, "", "-j", "list", "set", "inet", nftTable, set)
+		if err != nil {
+			return nil, err
+		}
+		elements, err := parseNFTSet(out)
+		if err != nil {
+			return nil, xerrors.Errorf("parse set %s: %w", set, err)
+		}
+		if len(elements) == 0 {
+			continue
+		}
+
+		// Only delete the elements that were listed, so connections
+		// blocked in the meantime are picked up next time.
+		literals := make([]string, 0, len(elements))
+		now := n.clock.Now()
+		for _, element := range elements {
+			literals = append(literals, element.literal())
+			violations = append(violations, element.violation(now))
+		}
+		_, err = n.run(ctx, fmt.Sprintf("delete element inet %s %s { %s }\n", nftTable, set, strings.Join(literals, ", ")), "-f", "-")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return violations, nil
+}
+
+func (n *nftables) run(ctx context.Context, stdin string, args ...string) ([]byte, error) {
+	//nolint:gosec // The path is resolved from PATH and the arguments are fixed.
+	cmd := exec.CommandContext(ctx, n.path, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return nil, xerrors.Errorf("nft %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// nftRuleset returns an nftables script that replaces the egress table with
+// one that only allows connections to the allowed prefixes. Connections that
+// are blocked are added to the denied sets.
+func nftRuleset(allowed []netip.Prefix) string {
+	var v4, v6 []string
+	for _, prefix := range allowed {
+		if prefix.Addr().Is4() {
+			v4 = append(v4, prefix.String())
+		} else {
+			v6 = append(v6, prefix.String())
+		}
+	}
+	elements := func(prefixes []string) string {
+		if len(prefixes) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("\t\telements = { %s }\n", strings.Join(prefixes, ", "))
+	}
+
+	var b strings.Builder
+	// Declaring the table before deleting it makes the script work whether
+	// or not the table exists. The script is applied atomically.
+	_, _ = fmt.Fprintf(&b, "table inet %[1]s\ndelete table inet %[1]s\n", nftTable)
+	_, _ = fmt.Fprintf(&b, "table inet %s {\n", nftTable)
+	_, _ = fmt.Fprintf(&b, "\tset allowed_v4 {\n\t\ttype ipv4_addr\n\t\tflags interval\n\t\tauto-merge\n%s\t}\n", elements(v4))
+	_, _ = fmt.Fprintf(&b, "\tset allowed_v6 {\n\t\ttype ipv6_addr\n\t\tflags interval\n\t\tauto-merge\n%s\t}\n", elements(v6))
+	_, _ = b.WriteString("\tset denied_v4 {\n\t\ttype ipv4_addr . inet_proto . inet_service\n\t\tsize 65535\n\t\tflags dynamic,timeout\n\t\ttimeout 1h\n\t}\n")
+	_, _ = b.WriteString("\tset denied_v6 {\n\t\ttype ipv6_addr . inet_proto . inet_service\n\t\tsize 65535\n\t\tflags dynamic,timeout\n\t\ttimeout 1h\n\t}\n")
+	_, _ = b.WriteString(`	chain egress {
+		ip daddr @allowed_v4 accept
+		ip6 daddr @allowed_v6 accept
+		meta l4proto { tcp, udp } add @denied_v4 { ip daddr . meta l4proto . th dport }
+		meta l4proto { tcp, udp } add @denied_v6 { ip6 daddr . meta l4proto . th dport }
+		reject
+	}
+	chain output {
+		type filter hook output priority filter; policy accept;
+		oifname "lo" accept
+		ct state established,related accept
+		meta l4proto { tcp, udp } th dport 53 accept
+		jump egress
+	}
+	chain forward {
+		type filter hook forward priority filter; policy accept;
+		ct state established,related accept
+		ct status dnat accept
+		meta l4proto { tcp, udp } th dport 53 accept
+		jump egress
+	}
+}
+`)
+	return b.String()
+}
+
+// nftElement is an element of a denied set.
+type nftElement struct {
+	addr     netip.Addr
+	protocol string
+	port     uint16
+	// age is how long ago the element was added, if known.
+	age time.Duration
+}
+
+func (e nftElement) literal() string {
+	return fmt.Sprintf("%s . %s . %d", e.addr, e.protocol, e.port)
+}
+
+func (e nftElement) violation(now time.Time) agentsdk.EgressViolation {
+	return agentsdk.EgressViolation{
+		Destination: e.addr.String(),
+		Port:        e.port,
+		Protocol:    e.protocol,
+		BlockedAt:   now.Add(-e.age),
+	}
+}
+
+// parseNFTSet parses the elements of a denied set from the output of
+// "nft -j list set".
+func parseNFTSet(data []byte) ([]nftElement, error) {
+	var out struct {
+		Nftables []struct {
+			Set *struct {
+				Elem []json.RawMessage `json:"elem"`
+			} `json:"set"`
+		} `json:"nftables"`
+	}
+	err := json.Unmarshal(data, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	var elements []nftElement
+	for _, object := range out.Nftables {
+		if object.Set == nil {
+			continue
+		}
+		for _, raw := range object.Set.Elem {
+			// Elements of sets with timeouts are wrapped in an object
+			// that holds the timeout and when the element expires.
+			var wrapped struct {
+				Elem *struct {
+					Val     json.RawMessage `json:"val"`
+					Timeout int64           `json:"timeout"`
+					Expires int64           `json:"expires"`
+				} `json:"elem"`
+			}
+			err = json.Unmarshal(raw, &wrapped)
+			if err != nil {
+				return nil, err
+			}
+			val := raw
+			var age time.Duration
+			if wrapped.Elem != nil {
+				val = wrapped.Elem.Val
+				if wrapped.Elem.Timeout > wrapped.Elem.Expires {
+					age = time.Duration(wrapped.Elem.Timeout-wrapped.Elem.Expires) * time.Second
+				}
+			}
+
+			element, err := parseNFTConcat(val)
+			if err != nil {
+				return nil, err
+			}
+			element.age = age
+			elements = append(elements, element)
+		}
+	}
+	return elements, nil
+}
+
+// parseNFTConcat parses an "address . protocol . port" element. nft prints
+// protocols and ports either by name or by number.
+func parseNFTConcat(data json.RawMessage) (nftElement, error) {
+	var val struct {
+		Concat []any `json:"concat"`
+	}
+	err := json.Unmarshal(data, &val)
+	if err != nil {
+		return nftElement{}, err
+	}
+	if len(val.Concat) != 3 {
+		return nftElement{}, xerrors.Errorf("expected 3 values, got %d", len(val.Concat))
+	}
+
+	var element nftElement
+	rawAddr, _ := val.Concat[0].(string)
+	element.addr, err = netip.ParseAddr(rawAddr)
+	if err != nil {
+		return nftElement{}, xerrors.Errorf("parse address: %w", err)
+	}
+	switch protocol := val.Concat[1].(type) {
+	case string:
+		element.protocol = protocol
+	case float64:
+		switch protocol {
+		case 6:
+			element.protocol = "tcp"
+		case 17:
+			element.protocol = "udp"
+		default:
+			element.protocol = strconv.Itoa(int(protocol))
+		}
+	default:
+		return nftElement{}, xerrors.Errorf("unexpected protocol %v", val.Concat[1])
+	}
+	switch port := val.Concat[2].(type) {
+	case float64:
+		element.port = uint16(port)
+	case string:
+		parsed, err := net.LookupPort(element.protocol, port)
+		if err != nil {
+			return nftElement{}, xerrors.Errorf("parse port: %w", err)
+		}
+		//nolint:gosec // LookupPort returns ports in the uint16 range.
+		element.port = uint16(parsed)
+	default:
+		return nftElement{}, xerrors.Errorf("unexpected port %v", val.Concat[2])
+	}
+	return element, nil
+}
@@ -0,0 +1,167 @@
+package agentegress
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// windowsFirewallGroup groups the rules created by the agent.
+const windowsFirewallGroup = "Coder egress"
+
+// windowsFirewall enforces egress restrictions with Windows Firewall, which
+// is built on the Windows Filtering Platform. Outbound connections are
+// blocked by default and allowed to the allowed prefixes by a rule. Blocked
+// connections are read from the firewall log.
+type windowsFirewall struct {
+	logPath string
+	// logOffset is how much of the log has been read.
+	logOffset int64
+}
+
+func newWindowsFirewall(logPath string) (*windowsFirewall, error) {
+	_, err := exec.LookPath("powershell.exe")
+	if err != nil {
+		return nil, xerrors.Errorf("powershell is not installed: %w", err)
+	}
+	return &windowsFirewall{
+		logPath: logPath,
+	}, nil
+}
+
+func (w *windowsFirewall) Apply(ctx context.Context, allowed []netip.Prefix) error {
+	// Skip the part of the log written before the restrictions changed.
+	if info, err := os.Stat(w.logPath); err == nil {
+		w.logOffset = info.Size()
+	}
+	return w.run(ctx, windowsFirewallScript(allowed, w.logPath))
+}
+
+func (w *windowsFirewall) Remove(ctx context.Context) error {
+	// Only restore the default outbound action if the agent changed it.
+	return w.run(ctx, fmt.Sprintf(`$ErrorActionPreference = 'Stop'
+if (Get-NetFirewallRule -Group '%[1]s' -ErrorAction SilentlyContinue) {
+	Remove-NetFirewallRule -Group '%[1]s'
+	Set-NetFirewallProfile -All -DefaultOutboundAction Allow -LogBlocked False
+}
+`, windowsFirewallGroup))
+}
+
+func (w *windowsFirewall) Violations(context.Context) ([]agentsdk.EgressViolation, error) {
+	f, err := os.Open(w.logPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("open firewall log: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, xerrors.Errorf("stat firewall log: %w", err)
+	}
+	// The log is moved aside once it reaches its maximum size.
+	if info.Size() < w.logOffset {
+		w.logOffset = 0
+	}
+	_, err = f.Seek(w.logOffset, io.SeekStart)
+	if err != nil {
+		return nil, xerrors.Errorf("seek firewall log: %w", err)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, xerrors.Errorf("read firewall log: %w", err)
+	}
+	// Leave a partially written line for the next call.
+	end := bytes.LastIndexByte(data, '\n') + 1
+	w.logOffset += int64(end)
+	return parseWindowsFirewallLog(data[:end], time.Local), nil
+}
+
+func (*windowsFirewall) run(ctx context.Context, script string) error {
+	cmd := exec.CommandContext(ctx, "powershell.exe", "-NoLogo", "-NoProfile", "-NonInteractive", "-Command", "-")
+	cmd.Stdin = strings.NewReader(script)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return xerrors.Errorf("configure windows firewall: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// windowsFirewallScript returns a PowerShell script that replaces the rules
+// of the agent with ones that only allow connections to the allowed
+// prefixes, and blocks other outbound connections by default.
+func windowsFirewallScript(allowed []netip.Prefix, logPath string) string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "$ErrorActionPreference = 'Stop'\n")
+	_, _ = fmt.Fprintf(&b, "Remove-NetFirewallRule -Group '%s' -ErrorAction SilentlyContinue\n", windowsFirewallGroup)
+	rule := func(name, args string) {
+		_, _ = fmt.Fprintf(&b, "New-NetFirewallRule -Group '%s' -DisplayName '%s: %s' -Direction Outbound -Action Allow %s | Out-Null\n", windowsFirewallGroup, windowsFirewallGroup, name, args)
+	}
+	rule("loopback", "-RemoteAddress @('127.0.0.0/8', '::1')")
+	rule("DNS over UDP", "-Protocol UDP -RemotePort 53")
+	rule("DNS over TCP", "-Protocol TCP -RemotePort 53")
+	if len(allowed) > 0 {
+		addresses := make([]string, 0, len(allowed))
+		for _, prefix := range allowed {
+			addresses = append(addresses, "'"+prefix.String()+"'")
+		}
+		rule("allowed destinations", fmt.Sprintf("-RemoteAddress @(%s)", strings.Join(addresses, ", ")))
+	}
+	_, _ = fmt.Fprintf(&b, "Set-NetFirewallProfile -All -DefaultOutboundAction Block -LogBlocked True -LogFileName '%s' -LogMaxSizeKilobytes 4096\n", strings.ReplaceAll(logPath, "'", "''"))
+	return b.String()
+}
+
+// parseWindowsFirewallLog returns the outbound connections dropped in a
+// Windows Firewall log, whose lines have the format:
+//
+//	date time action protocol src-ip dst-ip src-port dst-port size tcpflags tcpsyn tcpack tcpwin icmptype icmpcode info path [pid]
+func parseWindowsFirewallLog(data []byte, loc *time.Location) []agentsdk.EgressViolation {
+	var violations []agentsdk.EgressViolation
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 17 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if fields[2] != "DROP" || fields[16] != "SEND" {
+			continue
+		}
+		protocol := strings.ToLower(fields[3])
+		if protocol != "tcp" && protocol != "udp" {
+			continue
+		}
+		addr, err := netip.ParseAddr(fields[5])
+		if err != nil {
+			continue
+		}
+		port, err := strconv.ParseUint(fields[7], 10, 16)
+		if err != nil {
+			continue
+		}
+		blockedAt, err := time.ParseInLocation(time.DateTime, fields[0]+" "+fields[1], loc)
+		if err != nil {
+			continue
+		}
+		violations = append(violations, agentsdk.EgressViolation{
+			Destination: addr.String(),
+			Port:        uint16(port),
+			Protocol:    protocol,
+			BlockedAt:   blockedAt,
+		})
+	}
+	return violations
+}
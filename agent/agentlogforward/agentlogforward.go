@@ -0,0 +1,295 @@
+// Package agentlogforward forwards the startup and script logs of a workspace
+// agent, and optionally the log files of applications in the workspace, to an
+// external log aggregator such as Loki, CloudWatch or an OpenTelemetry
+// collector. Every entry is labeled with the workspace, its owner and the
+// agent, so dev environment logs can be searched next to the rest of an
+// organization's logs.
+package agentlogforward
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/quartz"
+)
+
+const (
+	// flushInterval is how often pending entries are exported and
+	// application log files are read.
+	flushInterval = time.Second
+	// maxBatchSize is the most entries exported at once.
+	maxBatchSize = 1000
+	// maxPendingEntries bounds the entries kept while the aggregator cannot
+	// be reached. The oldest entries are dropped first.
+	maxPendingEntries = 10 * maxBatchSize
+	exportTimeout     = 10 * time.Second
+)
+
+// Entry is a single log line.
+type Entry struct {
+	Time   time.Time
+	Level  codersdk.LogLevel
+	Output string
+	// Source is the name of the script, or the path of the application log
+	// file, that produced the entry.
+	Source string
+}
+
+// Labels identify the workspace agent entries come from.
+type Labels struct {
+	Owner     string
+	Workspace string
+	Agent     string
+}
+
+// Map returns the labels of an entry of source.
+func (l Labels) Map(source string) map[string]string {
+	return map[string]string{
+		"owner":     l.Owner,
+		"workspace": l.Workspace,
+		"agent":     l.Agent,
+		"source":    source,
+	}
+}
+
+// Exporter sends entries to a log aggregator.
+type Exporter interface {
+	// Export sends the entries, which are ordered by time. Entries are
+	// exported again when an error is returned.
+	Export(ctx context.Context, labels Labels, entries []Entry) error
+	Close() error
+}
+
+// NewExporter returns the exporter for the aggregator at rawURL. The scheme
+// of the URL selects the aggregator:
+//
+//	loki://[user:password@]host[:port][/path][?insecure=true&tenant=<id>]
+//	cloudwatch://<log-group>?region=<region>[&stream=<name>&endpoint=<url>]
+//	otlp://host[:port][?insecure=true]
+func NewExporter(ctx context.Context, rawURL string) (Exporter, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, xerrors.Errorf("parse log forward url: %w", err)
+	}
+	switch u.Scheme {
+	case "loki":
+		return newLokiExporter(http.DefaultClient, u)
+	case "cloudwatch":
+		return newCloudWatchExporterFromURL(ctx, u)
+	case "otlp":
+		return newOTLPExporter(u)
+	default:
+		return nil, xerrors.Errorf("unsupported log forward url scheme %q, must be \"loki\", \"cloudwatch\" or \"otlp\"", u.Scheme)
+	}
+}
+
+type Options struct {
+	Logger   slog.Logger
+	Exporter Exporter
+	// AppLogPaths are glob patterns of application log files. Lines
+	// appended to matching files are forwarded with the path as source.
+	AppLogPaths []string
+	Clock       quartz.Clock
+}
+
+// Forwarder buffers the logs of the agent and exports them in batches.
+type Forwarder struct {
+	logger      slog.Logger
+	exporter    Exporter
+	appLogPaths []string
+	clock       quartz.Clock
+
+	mu sync.Mutex
+	// labels are nil until the manifest is received, entries are kept
+	// until then.
+	labels  *Labels
+	sources map[uuid.UUID]string
+	pending []pendingEntry
+	dropped int
+	failing bool
+	tails   *tailer
+
+	// exportMu serializes exports, so entries are exported in order.
+	exportMu sync.Mutex
+}
+
+func New(opts Options) *Forwarder {
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+	return &Forwarder{
+		logger:      opts.Logger.Named("logforward"),
+		exporter:    opts.Exporter,
+		appLogPaths: opts.AppLogPaths,
+		clock:       opts.Clock,
+		sources:     map[uuid.UUID]string{},
+		tails:       newTailer(),
+	}
+}
+
+// SetManifest labels the entries with the workspace of the agent and names
+// the log sources after the scripts of the manifest.
+func (f *Forwarder) SetManifest(manifest agentsdk.Manifest) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.labels = &Labels{
+		Owner:     manifest.OwnerName,
+		Workspace: manifest.WorkspaceName,
+		Agent:     manifest.AgentName,
+	}
+	for _, script := range manifest.Scripts {
+		if script.DisplayName != "" {
+			f.sources[script.LogSourceID] = script.DisplayName
+		}
+	}
+}
+
+// pendingEntry is an entry waiting for export. Script logs may arrive before
+// the manifest, so their source is only named when they are exported.
+type pendingEntry struct {
+	Entry
+	logSourceID uuid.UUID
+	script      bool
+}
+
+// Enqueue queues the logs of a log source for export.
+func (f *Forwarder) Enqueue(logSourceID uuid.UUID, logs ...agentsdk.Log) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entries := make([]pendingEntry, 0, len(logs))
+	for _, log := range logs {
+		entries = append(entries, pendingEntry{
+			Entry: Entry{
+				Time:   log.CreatedAt,
+				Level:  log.Level,
+				Output: log.Output,
+			},
+			logSourceID: logSourceID,
+			script:      true,
+		})
+	}
+	f.enqueueLocked(entries)
+}
+
+// sourceLocked names the source of a script log.
+func (f *Forwarder) sourceLocked(logSourceID uuid.UUID) string {
+	if source, ok := f.sources[logSourceID]; ok {
+		return source
+	}
+	if logSourceID == agentsdk.ExternalLogSourceID {
+		return "external"
+	}
+	return "agent"
+}
+
+func (f *Forwarder) enqueueLocked(entries []pendingEntry) {
+	f.pending = append(f.pending, entries...)
+	if over := len(f.pending) - maxPendingEntries; over > 0 {
+		f.pending = f.pending[over:]
+		f.dropped += over
+	}
+}
+
+// Run reads the application log files and exports pending entries until the
+// context is canceled.
+func (f *Forwarder) Run(ctx context.Context) {
+	tkr := f.clock.TickerFunc(ctx, flushInterval, func() error {
+		f.ReadAppLogs()
+		err := f.Flush(ctx)
+		if err != nil && ctx.Err() == nil {
+			f.logger.Debug(ctx, "forward logs", slog.Error(err))
+		}
+		return nil
+	}, "agentlogforward", "flush")
+	_ = tkr.Wait()
+}
+
+// ReadAppLogs queues the lines appended to the application log files since
+// they were last read.
+func (f *Forwarder) ReadAppLogs() {
+	if len(f.appLogPaths) == 0 {
+		return
+	}
+	entries := f.tails.read(f.logger, f.appLogPaths, f.clock.Now())
+	if len(entries) == 0 {
+		return
+	}
+	pending := make([]pendingEntry, 0, len(entries))
+	for _, entry := range entries {
+		pending = append(pending, pendingEntry{Entry: entry})
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.enqueueLocked(pending)
+}
+
+// Flush exports the pending entries. Nothing is exported before the
+// manifest is set.
+func (f *Forwarder) Flush(ctx context.Context) error {
+	f.exportMu.Lock()
+	defer f.exportMu.Unlock()
+
+	for {
+		f.mu.Lock()
+		if f.labels == nil || len(f.pending) == 0 {
+			f.mu.Unlock()
+			return nil
+		}
+		labels := *f.labels
+		batch := make([]Entry, 0, min(len(f.pending), maxBatchSize))
+		for _, entry := range f.pending[:cap(batch)] {
+			if entry.script {
+				entry.Source = f.sourceLocked(entry.logSourceID)
+			}
+			batch = append(batch, entry.Entry)
+		}
+		droppedBefore := f.dropped
+		f.mu.Unlock()
+
+		exportCtx, cancel := context.WithTimeout(ctx, exportTimeout)
+		err := f.exporter.Export(exportCtx, labels, batch)
+		cancel()
+
+		f.mu.Lock()
+		if err != nil {
+			if !f.failing {
+				f.logger.Warn(ctx, "failed to forward logs, retrying", slog.Error(err))
+			}
+			f.failing = true
+			f.mu.Unlock()
+			return xerrors.Errorf("export logs: %w", err)
+		}
+		if f.failing {
+			f.logger.Info(ctx, "forwarding logs again")
+		}
+		f.failing = false
+		// Entries dropped while exporting were the oldest, so they were
+		// part of the batch.
+		exported := max(len(batch)-(f.dropped-droppedBefore), 0)
+		f.pending = f.pending[exported:]
+		if f.dropped > 0 {
+			f.logger.Warn(ctx, "dropped logs that could not be forwarded", slog.F("count", f.dropped))
+			f.dropped = 0
+		}
+		f.mu.Unlock()
+	}
+}
+
+// Close exports the remaining entries and closes the exporter.
+func (f *Forwarder) Close(ctx context.Context) error {
+	f.ReadAppLogs()
+	err := f.Flush(ctx)
+	if cerr := f.exporter.Close(); cerr != nil && err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,234 @@
+package agentlogforward
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// cloudWatchMaxBatchBytes is the most bytes PutLogEvents accepts, each
+	// event counts its message plus cloudWatchEventOverhead bytes.
+	cloudWatchMaxBatchBytes = 1 << 20
+	cloudWatchEventOverhead = 26
+	// cloudWatchMaxEventBytes is the longest message of an event.
+	cloudWatchMaxEventBytes = 256 << 10
+)
+
+// cloudWatchExporter puts entries into a log group of CloudWatch Logs.
+// Requests are signed with the credentials from the default AWS credential
+// chain. Every agent writes to its own log stream, and every event is a JSON
+// object with the labels of the entry, so CloudWatch Logs Insights can query
+// them.
+type cloudWatchExporter struct {
+	client      *http.Client
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	region      string
+	endpoint    string
+	group       string
+	// stream defaults to <owner>/<workspace>/<agent>.
+	stream string
+
+	mu      sync.Mutex
+	created map[string]bool
+}
+
+func newCloudWatchExporterFromURL(ctx context.Context, u *url.URL) (*cloudWatchExporter, error) {
+	if u.Host == "" {
+		return nil, xerrors.New("cloudwatch log forward url must contain a log group")
+	}
+	query := u.Query()
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(query.Get("region")))
+	if err != nil {
+		return nil, xerrors.Errorf("load aws config: %w", err)
+	}
+	if cfg.Region == "" {
+		return nil, xerrors.New("cloudwatch log forward url must set the region query parameter")
+	}
+	// Log group names may contain slashes, e.g. /coder/workspaces.
+	group := u.Host + strings.TrimSuffix(u.Path, "/")
+	return newCloudWatchExporter(http.DefaultClient, cfg.Credentials, cfg.Region, group, query.Get("stream"), query.Get("endpoint")), nil
+}
+
+func newCloudWatchExporter(client *http.Client, credentials aws.CredentialsProvider, region, group, stream, endpoint string) *cloudWatchExporter {
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://logs.%s.amazonaws.com/", region)
+	}
+	return &cloudWatchExporter{
+		client:      client,
+		credentials: credentials,
+		signer:      v4.NewSigner(),
+		region:      region,
+		endpoint:    endpoint,
+		group:       group,
+		stream:      stream,
+		created:     map[string]bool{},
+	}
+}
+
+type cloudWatchEvent struct {
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+type cloudWatchMessage struct {
+	Owner     string `json:"owner"`
+	Workspace string `json:"workspace"`
+	Agent     string `json:"agent"`
+	Source    string `json:"source"`
+	Level     string `json:"level"`
+	Output    string `json:"output"`
+}
+
+func (e *cloudWatchExporter) Export(ctx context.Context, labels Labels, entries []Entry) error {
+	stream := e.stream
+	if stream == "" {
+		stream = strings.Join([]string{labels.Owner, labels.Workspace, labels.Agent}, "/")
+	}
+	err := e.createStream(ctx, stream)
+	if err != nil {
+		return err
+	}
+
+	var (
+		events []cloudWatchEvent
+		size   int
+	)
+	for _, entry := range entries {
+		output := entry.Output
+		if len(output) > cloudWatchMaxEventBytes/2 {
+			output = output[:cloudWatchMaxEventBytes/2]
+		}
+		message, err := json.Marshal(cloudWatchMessage{
+			Owner:     labels.Owner,
+			Workspace: labels.Workspace,
+			Agent:     labels.Agent,
+			Source:    entry.Source,
+			Level:     string(entry.Level),
+			Output:    output,
+		})
+		if err != nil {
+			return xerrors.Errorf("marshal event: %w", err)
+		}
+		eventSize := len(message) + cloudWatchEventOverhead
+		if size+eventSize > cloudWatchMaxBatchBytes {
+			err = e.putLogEvents(ctx, stream, events)
+			if err != nil {
+				return err
+			}
+			events, size = nil, 0
+		}
+		events = append(events, cloudWatchEvent{
+			Timestamp: entry.Time.UnixMilli(),
+			Message:   string(message),
+		})
+		size += eventSize
+	}
+	return e.putLogEvents(ctx, stream, events)
+}
+
+func (e *cloudWatchExporter) createStream(ctx context.Context, stream string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.created[stream] {
+		return nil
+	}
+	err := e.do(ctx, "CreateLogStream", map[string]any{
+		"logGroupName":  e.group,
+		"logStreamName": stream,
+	})
+	var apiErr *cloudWatchError
+	if err != nil && !(xerrors.As(err, &apiErr) && apiErr.isType("ResourceAlreadyExistsException")) {
+		return xerrors.Errorf("create log stream: %w", err)
+	}
+	e.created[stream] = true
+	return nil
+}
+
+func (e *cloudWatchExporter) putLogEvents(ctx context.Context, stream string, events []cloudWatchEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	err := e.do(ctx, "PutLogEvents", map[string]any{
+		"logGroupName":  e.group,
+		"logStreamName": stream,
+		"logEvents":     events,
+	})
+	if err != nil {
+		return xerrors.Errorf("put log events: %w", err)
+	}
+	return nil
+}
+
+// cloudWatchError is an error returned by the CloudWatch Logs API.
+type cloudWatchError struct {
+	StatusCode int
+	Type       string `json:"__type"`
+	Message    string `json:"message"`
+}
+
+func (e *cloudWatchError) Error() string {
+	return fmt.Sprintf("%s (status %d): %s", e.Type, e.StatusCode, e.Message)
+}
+
+// isType reports whether the error is of type, which the API may prefix with
+// a namespace.
+func (e *cloudWatchError) isType(t string) bool {
+	return e.Type == t || strings.HasSuffix(e.Type, "#"+t)
+}
+
+func (e *cloudWatchExporter) do(ctx context.Context, action string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return xerrors.Errorf("marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "Logs_20140328."+action)
+
+	creds, err := e.credentials.Retrieve(ctx)
+	if err != nil {
+		return xerrors.Errorf("retrieve aws credentials: %w", err)
+	}
+	hash := sha256.Sum256(payload)
+	err = e.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "logs", e.region, time.Now())
+	if err != nil {
+		return xerrors.Errorf("sign request: %w", err)
+	}
+	res, err := e.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("%s: %w", action, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+	apiErr := &cloudWatchError{StatusCode: res.StatusCode}
+	if json.Unmarshal(msg, apiErr) != nil || apiErr.Type == "" {
+		return xerrors.Errorf("%s: %s", action, responseError(res.StatusCode, msg))
+	}
+	return apiErr
+}
+
+func (*cloudWatchExporter) Close() error {
+	return nil
+}
@@ -0,0 +1,140 @@
+package agentlogforward
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+var (
+	testLabels  = Labels{Owner: "alice", Workspace: "dev", Agent: "main"}
+	testEntries = []Entry{
+		{Time: time.Unix(1, 0), Level: codersdk.LogLevelInfo, Output: "one", Source: "Install tools"},
+		{Time: time.Unix(2, 0), Level: codersdk.LogLevelError, Output: "two", Source: "Install tools"},
+		{Time: time.Unix(3, 0), Level: codersdk.LogLevelInfo, Output: "three", Source: "Install tools"},
+	}
+)
+
+func TestLokiExporter(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	var (
+		mu     sync.Mutex
+		req    *http.Request
+		body   lokiPushRequest
+		status = http.StatusNoContent
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		req = r
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(status)
+		if status != http.StatusNoContent {
+			_, _ = w.Write([]byte("rate limited"))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(strings.Replace(srv.URL, "http://", "loki://bob:secret@", 1) + "/prefix?insecure=true&tenant=coder")
+	require.NoError(t, err)
+	e, err := newLokiExporter(srv.Client(), u)
+	require.NoError(t, err)
+	require.NoError(t, e.Export(ctx, testLabels, testEntries))
+
+	mu.Lock()
+	require.Equal(t, "/prefix/loki/api/v1/push", req.URL.Path)
+	require.Equal(t, "coder", req.Header.Get("X-Scope-OrgID"))
+	username, password, ok := req.BasicAuth()
+	require.True(t, ok)
+	require.Equal(t, "bob", username)
+	require.Equal(t, "secret", password)
+	require.Equal(t, []lokiStream{{
+		Stream: map[string]string{"owner": "alice", "workspace": "dev", "agent": "main", "source": "Install tools", "level": "info"},
+		Values: [][2]string{{"1000000000", "one"}, {"3000000000", "three"}},
+	}, {
+		Stream: map[string]string{"owner": "alice", "workspace": "dev", "agent": "main", "source": "Install tools", "level": "error"},
+		Values: [][2]string{{"2000000000", "two"}},
+	}}, body.Streams)
+
+	status = http.StatusTooManyRequests
+	mu.Unlock()
+	err = e.Export(ctx, testLabels, testEntries)
+	require.ErrorContains(t, err, "unexpected status code 429: rate limited")
+}
+
+func TestCloudWatchExporter(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	var (
+		mu      sync.Mutex
+		actions []string
+		events  []cloudWatchEvent
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		action := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "Logs_20140328.")
+		actions = append(actions, action)
+		if !strings.Contains(r.Header.Get("Authorization"), "/us-east-1/logs/aws4_request") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			LogGroupName  string            `json:"logGroupName"`
+			LogStreamName string            `json:"logStreamName"`
+			LogEvents     []cloudWatchEvent `json:"logEvents"`
+		}
+		_ = json.Unmarshal(body, &req)
+		if req.LogGroupName != "/coder/workspaces" || req.LogStreamName != "alice/dev/main" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch action {
+		case "CreateLogStream":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"com.amazonaws.logs#ResourceAlreadyExistsException","message":"The specified log stream already exists"}`))
+		case "PutLogEvents":
+			events = append(events, req.LogEvents...)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	creds := credentials.NewStaticCredentialsProvider("AKID", "SECRET", "")
+	e := newCloudWatchExporter(srv.Client(), creds, "us-east-1", "/coder/workspaces", "", srv.URL)
+	require.NoError(t, e.Export(ctx, testLabels, testEntries))
+	require.NoError(t, e.Export(ctx, testLabels, testEntries[:1]))
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The log stream is only created once.
+	require.Equal(t, []string{"CreateLogStream", "PutLogEvents", "PutLogEvents"}, actions)
+	require.Len(t, events, 4)
+	require.Equal(t, int64(1000), events[0].Timestamp)
+	var message cloudWatchMessage
+	require.NoError(t, json.Unmarshal([]byte(events[1].Message), &message))
+	require.Equal(t, cloudWatchMessage{
+		Owner:     "alice",
+		Workspace: "dev",
+		Agent:     "main",
+		Source:    "Install tools",
+		Level:     "error",
+		Output:    "two",
+	}, message)
+}
@@ -0,0 +1,122 @@
+package agentlogforward
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	"golang.org/x/xerrors"
+)
+
+// lokiPushPath is the path of the Loki push API.
+const lokiPushPath = "/loki/api/v1/push"
+
+// lokiExporter pushes entries to Loki. Every source and level of the agent
+// is a stream.
+type lokiExporter struct {
+	client   *http.Client
+	endpoint string
+	tenant   string
+	username string
+	password string
+}
+
+func newLokiExporter(client *http.Client, u *url.URL) (*lokiExporter, error) {
+	if u.Host == "" {
+		return nil, xerrors.New("loki log forward url must contain a host")
+	}
+	query := u.Query()
+	endpoint := url.URL{
+		Scheme: "https",
+		Host:   u.Host,
+		Path:   path.Join("/", u.Path, lokiPushPath),
+	}
+	if insecure, _ := strconv.ParseBool(query.Get("insecure")); insecure {
+		endpoint.Scheme = "http"
+	}
+	e := &lokiExporter{
+		client:   client,
+		endpoint: endpoint.String(),
+		tenant:   query.Get("tenant"),
+	}
+	if u.User != nil {
+		e.username = u.User.Username()
+		e.password, _ = u.User.Password()
+	}
+	return e, nil
+}
+
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	// Values are pairs of the time in nanoseconds and the line.
+	Values [][2]string `json:"values"`
+}
+
+func (e *lokiExporter) Export(ctx context.Context, labels Labels, entries []Entry) error {
+	type streamKey struct{ source, level string }
+	var req lokiPushRequest
+	streams := map[streamKey]int{}
+	for _, entry := range entries {
+		key := streamKey{source: entry.Source, level: string(entry.Level)}
+		i, ok := streams[key]
+		if !ok {
+			stream := labels.Map(entry.Source)
+			stream["level"] = string(entry.Level)
+			i = len(req.Streams)
+			streams[key] = i
+			req.Streams = append(req.Streams, lokiStream{Stream: stream})
+		}
+		req.Streams[i].Values = append(req.Streams[i].Values, [2]string{
+			strconv.FormatInt(entry.Time.UnixNano(), 10),
+			entry.Output,
+		})
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return xerrors.Errorf("marshal loki push request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create loki push request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if e.tenant != "" {
+		httpReq.Header.Set("X-Scope-OrgID", e.tenant)
+	}
+	if e.username != "" {
+		httpReq.SetBasicAuth(e.username, e.password)
+	}
+	res, err := e.client.Do(httpReq)
+	if err != nil {
+		return xerrors.Errorf("push to loki: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return xerrors.Errorf("push to loki: %s", responseError(res.StatusCode, msg))
+	}
+	return nil
+}
+
+func (*lokiExporter) Close() error {
+	return nil
+}
+
+// responseError describes an unexpected response of an aggregator.
+func responseError(status int, body []byte) string {
+	if len(body) == 0 {
+		return fmt.Sprintf("unexpected status code %d", status)
+	}
+	return fmt.Sprintf("unexpected status code %d: %s", status, bytes.TrimSpace(body))
+}
@@ -0,0 +1,113 @@
+package agentlogforward
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"strconv"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const defaultOTLPPort = "4317"
+
+// otlpExporter exports entries to an OpenTelemetry collector over gRPC. The
+// workspace is described by the resource attributes coder.workspace.owner,
+// coder.workspace.name and coder.agent.name, and the source of an entry by
+// the coder.log.source attribute.
+type otlpExporter struct {
+	conn     *grpc.ClientConn
+	client   collogspb.LogsServiceClient
+	hostname string
+}
+
+func newOTLPExporter(u *url.URL) (*otlpExporter, error) {
+	if u.Host == "" {
+		return nil, xerrors.New("otlp log forward url must contain a host")
+	}
+	address := u.Host
+	if u.Port() == "" {
+		address += ":" + defaultOTLPPort
+	}
+	creds := credentials.NewClientTLSFromCert(nil, "")
+	if insecureTransport, _ := strconv.ParseBool(u.Query().Get("insecure")); insecureTransport {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, xerrors.Errorf("create otlp client: %w", err)
+	}
+	hostname, _ := os.Hostname()
+	return &otlpExporter{
+		conn:     conn,
+		client:   collogspb.NewLogsServiceClient(conn),
+		hostname: hostname,
+	}, nil
+}
+
+func (e *otlpExporter) Export(ctx context.Context, labels Labels, entries []Entry) error {
+	attrs := []*commonpb.KeyValue{
+		otlpAttribute("service.name", "coder-agent"),
+		otlpAttribute("coder.workspace.owner", labels.Owner),
+		otlpAttribute("coder.workspace.name", labels.Workspace),
+		otlpAttribute("coder.agent.name", labels.Agent),
+	}
+	if e.hostname != "" {
+		attrs = append(attrs, otlpAttribute("host.name", e.hostname))
+	}
+	records := make([]*logspb.LogRecord, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, &logspb.LogRecord{
+			TimeUnixNano:   uint64(entry.Time.UnixNano()), //nolint:gosec // Log times are after 1970.
+			SeverityNumber: otlpSeverity(entry.Level),
+			SeverityText:   string(entry.Level),
+			Body:           &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: entry.Output}},
+			Attributes:     []*commonpb.KeyValue{otlpAttribute("coder.log.source", entry.Source)},
+		})
+	}
+	_, err := e.client.Export(ctx, &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: &resourcepb.Resource{Attributes: attrs},
+			ScopeLogs: []*logspb.ScopeLogs{{
+				Scope:      &commonpb.InstrumentationScope{Name: "github.com/coder/coder/v2/agent/agentlogforward"},
+				LogRecords: records,
+			}},
+		}},
+	})
+	if err != nil {
+		return xerrors.Errorf("export to otlp collector: %w", err)
+	}
+	return nil
+}
+
+func (e *otlpExporter) Close() error {
+	return e.conn.Close()
+}
+
+func otlpSeverity(level codersdk.LogLevel) logspb.SeverityNumber {
+	switch level {
+	case codersdk.LogLevelTrace:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_TRACE
+	case codersdk.LogLevelDebug:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG
+	case codersdk.LogLevelWarn:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+	case codersdk.LogLevelError:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR
+	default:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO
+	}
+}
+
+func otlpAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
@@ -0,0 +1,148 @@
+package agentlogforward
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// maxReadPerFile is the most bytes read from a file at once, so a
+	// chatty application cannot hold up the other files.
+	maxReadPerFile = 1 << 20
+	// maxLineLength is the length at which lines without a newline are
+	// forwarded anyway.
+	maxLineLength = 64 << 10
+)
+
+// tailer follows application log files like `tail -F`.
+type tailer struct {
+	mu sync.Mutex
+	// started is set after the first read. Files that exist when the agent
+	// starts are followed from their end, so restarting the agent does not
+	// forward the same lines again.
+	started bool
+	files   map[string]*tailedFile
+}
+
+type tailedFile struct {
+	info    os.FileInfo
+	offset  int64
+	partial []byte
+}
+
+func newTailer() *tailer {
+	return &tailer{files: map[string]*tailedFile{}}
+}
+
+// read returns the lines appended to the files matching patterns since the
+// last read.
+func (t *tailer) read(logger slog.Logger, patterns []string, now time.Time) []Entry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seen := map[string]struct{}{}
+	var entries []Entry
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			logger.Warn(context.Background(), "invalid app log path", slog.F("pattern", pattern), slog.Error(err))
+			continue
+		}
+		for _, path := range matches {
+			if _, ok := seen[path]; ok {
+				continue
+			}
+			seen[path] = struct{}{}
+			lines, err := t.readFile(path)
+			if err != nil {
+				logger.Debug(context.Background(), "read app log", slog.F("path", path), slog.Error(err))
+			}
+			for _, line := range lines {
+				entries = append(entries, Entry{
+					Time:   now,
+					Level:  codersdk.LogLevelInfo,
+					Output: line,
+					Source: path,
+				})
+			}
+		}
+	}
+	// Forget files that were removed, so they are read from the start if
+	// they are created again.
+	for path := range t.files {
+		if _, ok := seen[path]; !ok {
+			delete(t.files, path)
+		}
+	}
+	t.started = true
+	return entries
+}
+
+func (t *tailer) readFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, nil
+	}
+
+	file, ok := t.files[path]
+	switch {
+	case !ok:
+		file = &tailedFile{info: info}
+		if !t.started {
+			file.offset = info.Size()
+		}
+		t.files[path] = file
+	case !os.SameFile(file.info, info) || info.Size() < file.offset:
+		// The file was rotated or truncated.
+		file.offset = 0
+		file.partial = nil
+	}
+	file.info = info
+	if info.Size() == file.offset {
+		return nil, nil
+	}
+
+	buf := make([]byte, min(info.Size()-file.offset, maxReadPerFile))
+	n, err := f.ReadAt(buf, file.offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	file.offset += int64(n)
+	data := append(file.partial, buf[:n]...)
+
+	var lines []string
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		if line := strings.TrimSuffix(string(data[:i]), "\r"); line != "" {
+			lines = append(lines, line)
+		}
+		data = data[i+1:]
+	}
+	if len(data) >= maxLineLength {
+		lines = append(lines, string(data))
+		data = nil
+	}
+	file.partial = bytes.Clone(data)
+	return lines, nil
+}
@@ -0,0 +1,223 @@
+// Package agentmetadatapush serves a local API on a unix socket where
+// processes in the workspace push metadata items and status lines. The agent
+// reports them to coderd together with the metadata defined in the template.
+package agentmetadatapush
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/cryptorand"
+	"github.com/coder/quartz"
+)
+
+const (
+	// StatusKey is the key of the metadata item that holds the latest status
+	// line.
+	StatusKey = "status"
+
+	// maxValueLen matches the length coderd accepts for metadata values.
+	maxValueLen = 2048
+)
+
+var keyRegex = regexp.MustCompile(`^[a-z0-9_][a-z0-9_.-]{0,63}$`)
+
+// PushMetadataRequest sets the value of a metadata item.
+type PushMetadataRequest struct {
+	Value string `json:"value"`
+	Error string `json:"error"`
+}
+
+// PushStatusRequest sets the status line of the workspace.
+type PushStatusRequest struct {
+	Message string `json:"message"`
+}
+
+type Options struct {
+	Logger slog.Logger
+	// SocketPath is the path of the unix socket the API listens on. Only the
+	// user of the agent can connect to it.
+	SocketPath string
+	Clock      quartz.Clock
+}
+
+// Server holds the metadata items pushed by processes in the workspace.
+type Server struct {
+	logger     slog.Logger
+	socketPath string
+	token      string
+	clock      quartz.Clock
+
+	mu    sync.Mutex
+	items map[string]codersdk.WorkspaceAgentMetadataResult
+}
+
+func New(opts Options) (*Server, error) {
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+	token, err := cryptorand.String(32)
+	if err != nil {
+		return nil, xerrors.Errorf("generate token: %w", err)
+	}
+	return &Server{
+		logger:     opts.Logger.Named("metadata-push"),
+		socketPath: opts.SocketPath,
+		token:      token,
+		clock:      opts.Clock,
+		items:      make(map[string]codersdk.WorkspaceAgentMetadataResult),
+	}, nil
+}
+
+// SocketPath is the path of the unix socket of the API.
+func (s *Server) SocketPath() string {
+	return s.socketPath
+}
+
+// Token must be sent in the Authorization header of all requests to the
+// API. Processes started by the agent receive it in their environment.
+func (s *Server) Token() string {
+	return s.token
+}
+
+// Serve listens on the unix socket until the context is canceled.
+func (s *Server) Serve(ctx context.Context) error {
+	// A socket left behind by a previous agent would fail the listen.
+	err := os.Remove(s.socketPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return xerrors.Errorf("remove stale socket: %w", err)
+	}
+	listener, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return xerrors.Errorf("listen on %q: %w", s.socketPath, err)
+	}
+	err = os.Chmod(s.socketPath, 0o600)
+	if err != nil {
+		_ = listener.Close()
+		return xerrors.Errorf("restrict socket permissions: %w", err)
+	}
+
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	s.logger.Info(ctx, "serving metadata push API", slog.F("socket", s.socketPath))
+	err = srv.Serve(listener)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// Handler serves the API. Requests must be authenticated with the token.
+func (s *Server) Handler() http.Handler {
+	r := chi.NewRouter()
+	r.Use(s.authenticate)
+	r.Put("/api/v0/metadata/{key}", s.putMetadata)
+	r.Post("/api/v0/status", s.postStatus)
+	return r
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			httpapi.Write(r.Context(), rw, http.StatusUnauthorized, codersdk.Response{
+				Message: "Missing or invalid token.",
+				Detail:  "Send the value of CODER_AGENT_METADATA_TOKEN as a bearer token.",
+			})
+			return
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
+
+func (s *Server) putMetadata(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key := chi.URLParam(r, "key")
+	if !keyRegex.MatchString(key) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid metadata key %q.", key),
+			Detail:  "Keys are up to 64 lowercase letters, digits, underscores, dots and dashes.",
+		})
+		return
+	}
+
+	var req PushMetadataRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	s.push(ctx, rw, key, req.Value, req.Error)
+}
+
+func (s *Server) postStatus(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req PushStatusRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	// Only the last line of a multi-line message is kept, so tools can
+	// forward their output line by line or all at once.
+	lines := strings.Split(strings.TrimRight(req.Message, "\r\n"), "\n")
+	s.push(ctx, rw, StatusKey, strings.TrimSpace(lines[len(lines)-1]), "")
+}
+
+func (s *Server) push(ctx context.Context, rw http.ResponseWriter, key, value, errorMessage string) {
+	if len(value) > maxValueLen || len(errorMessage) > maxValueLen {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Values and errors must be at most %d bytes.", maxValueLen),
+		})
+		return
+	}
+
+	s.mu.Lock()
+	_, exists := s.items[key]
+	if !exists && len(s.items) >= agentsdk.MaxPushedMetadata {
+		s.mu.Unlock()
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("At most %d metadata items can be pushed.", agentsdk.MaxPushedMetadata),
+		})
+		return
+	}
+	s.items[key] = codersdk.WorkspaceAgentMetadataResult{
+		CollectedAt: s.clock.Now(),
+		Value:       value,
+		Error:       errorMessage,
+	}
+	s.mu.Unlock()
+
+	s.logger.Debug(ctx, "metadata pushed", slog.F("key", key))
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// Items returns the pushed metadata items by key. The collection time of an
+// item changes whenever it is pushed again.
+func (s *Server) Items() map[string]codersdk.WorkspaceAgentMetadataResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.items)
+}
@@ -0,0 +1,169 @@
+package agentmetadatapush_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent/agentmetadatapush"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(t *testing.T) (*agentmetadatapush.Server, *quartz.Mock) {
+		clock := quartz.NewMock(t)
+		srv, err := agentmetadatapush.New(agentmetadatapush.Options{
+			Logger: slogtest.Make(t, nil),
+			Clock:  clock,
+		})
+		require.NoError(t, err)
+		return srv, clock
+	}
+	do := func(t *testing.T, srv *agentmetadatapush.Server, token, method, path string, body any) *http.Response {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(method, path, bytes.NewReader(data))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rw := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rw, req)
+		return rw.Result()
+	}
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		t.Parallel()
+		srv, _ := newServer(t)
+
+		res := do(t, srv, "", http.MethodPut, "/api/v0/metadata/build", agentmetadatapush.PushMetadataRequest{Value: "1"})
+		defer res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+		res = do(t, srv, "wrong", http.MethodPut, "/api/v0/metadata/build", agentmetadatapush.PushMetadataRequest{Value: "1"})
+		defer res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		require.Empty(t, srv.Items())
+	})
+
+	t.Run("PushMetadata", func(t *testing.T) {
+		t.Parallel()
+		srv, clock := newServer(t)
+
+		res := do(t, srv, srv.Token(), http.MethodPut, "/api/v0/metadata/build", agentmetadatapush.PushMetadataRequest{Value: "42%"})
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+		items := srv.Items()
+		require.Len(t, items, 1)
+		require.Equal(t, "42%", items["build"].Value)
+		require.Equal(t, clock.Now(), items["build"].CollectedAt)
+
+		res = do(t, srv, srv.Token(), http.MethodPut, "/api/v0/metadata/Not%20Valid", agentmetadatapush.PushMetadataRequest{Value: "1"})
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("PushStatus", func(t *testing.T) {
+		t.Parallel()
+		srv, _ := newServer(t)
+
+		// Only the last line of the message is kept.
+		res := do(t, srv, srv.Token(), http.MethodPost, "/api/v0/status", agentmetadatapush.PushStatusRequest{Message: "Compiling\nLinking\n"})
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+		require.Equal(t, "Linking", srv.Items()[agentmetadatapush.StatusKey].Value)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		t.Parallel()
+		srv, _ := newServer(t)
+
+		for i := 0; i < agentsdk.MaxPushedMetadata; i++ {
+			res := do(t, srv, srv.Token(), http.MethodPut, fmt.Sprintf("/api/v0/metadata/item%d", i), agentmetadatapush.PushMetadataRequest{Value: "1"})
+			_ = res.Body.Close()
+			require.Equal(t, http.StatusNoContent, res.StatusCode)
+		}
+		res := do(t, srv, srv.Token(), http.MethodPut, "/api/v0/metadata/extra", agentmetadatapush.PushMetadataRequest{Value: "1"})
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		// Existing items can still be updated.
+		res = do(t, srv, srv.Token(), http.MethodPut, "/api/v0/metadata/item0", agentmetadatapush.PushMetadataRequest{Value: "2"})
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+		require.Equal(t, "2", srv.Items()["item0"].Value)
+	})
+}
+
+func TestServe(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("unix socket permissions are not supported on Windows")
+	}
+
+	// Unix socket paths are limited in length, so the test directory can't
+	// be used on all platforms.
+	dir, err := os.MkdirTemp("", "metadatapush")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, "agent.sock")
+	// A socket left behind by a previous agent is replaced.
+	require.NoError(t, os.WriteFile(socketPath, nil, 0o600))
+
+	srv, err := agentmetadatapush.New(agentmetadatapush.Options{
+		Logger:     slogtest.Make(t, nil),
+		SocketPath: socketPath,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(testutil.Context(t, testutil.WaitShort))
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ctx)
+	}()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+	require.Eventually(t, func() bool {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://agent/api/v0/status", bytes.NewReader([]byte(`{"message":"ready"}`)))
+		if err != nil {
+			return false
+		}
+		req.Header.Set("Authorization", "Bearer "+srv.Token())
+		res, err := client.Do(req)
+		if err != nil {
+			return false
+		}
+		_ = res.Body.Close()
+		return res.StatusCode == http.StatusNoContent
+	}, testutil.WaitShort, testutil.IntervalFast)
+	require.Equal(t, "ready", srv.Items()[agentmetadatapush.StatusKey].Value)
+
+	info, err := os.Stat(socketPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,282 @@
+// Package agentproc adjusts the CPU and OOM killer priority of processes in
+// the workspace according to the process priority rules of the template, so
+// resource-heavy processes such as language servers can be deprioritized
+// without custom scripts.
+package agentproc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/quartz"
+)
+
+// EnvProcPrioRules is the environment variable that holds the process
+// priority rules of the template, as a JSON array of
+// codersdk.WorkspaceAgentProcessPriorityRule.
+const EnvProcPrioRules = "CODER_PROC_PRIO_RULES"
+
+// scanInterval is how often processes are listed and their priority
+// adjusted.
+const scanInterval = 10 * time.Second
+
+// Process is a process running in the workspace.
+type Process struct {
+	PID  int32
+	Name string
+	// CmdLine is the command line of the process, with the arguments
+	// separated by spaces.
+	CmdLine string
+}
+
+// System lists the processes of the workspace and adjusts their priority.
+type System interface {
+	Processes() ([]Process, error)
+	// Nice returns the niceness of the process, from -20 to 19.
+	Nice(pid int32) (int, error)
+	SetNice(pid int32, nice int) error
+	OOMScoreAdj(pid int32) (int, error)
+	SetOOMScoreAdj(pid int32, score int) error
+}
+
+// Rule is a process priority rule with its compiled pattern.
+type Rule struct {
+	codersdk.WorkspaceAgentProcessPriorityRule
+	pattern *regexp.Regexp
+}
+
+// Matches returns true if the pattern of the rule matches the name or the
+// command line of the process.
+func (r Rule) Matches(p Process) bool {
+	return r.pattern.MatchString(p.Name) || r.pattern.MatchString(p.CmdLine)
+}
+
+// ParseRules parses the rules in the format of EnvProcPrioRules.
+func ParseRules(raw string) ([]Rule, error) {
+	var sdkRules []codersdk.WorkspaceAgentProcessPriorityRule
+	err := json.Unmarshal([]byte(raw), &sdkRules)
+	if err != nil {
+		return nil, xerrors.Errorf("decode rules: %w", err)
+	}
+	rules := make([]Rule, 0, len(sdkRules))
+	for i, rule := range sdkRules {
+		if rule.Pattern == "" {
+			return nil, xerrors.Errorf("rule %d: pattern is required", i)
+		}
+		if rule.Nice == nil && rule.OOMScoreAdj == nil {
+			return nil, xerrors.Errorf("rule %d: nice or oom_score_adj is required", i)
+		}
+		if rule.Nice != nil && (*rule.Nice < -20 || *rule.Nice > 19) {
+			return nil, xerrors.Errorf("rule %d: nice must be between -20 and 19", i)
+		}
+		if rule.OOMScoreAdj != nil && (*rule.OOMScoreAdj < -1000 || *rule.OOMScoreAdj > 1000) {
+			return nil, xerrors.Errorf("rule %d: oom_score_adj must be between -1000 and 1000", i)
+		}
+		rules = append(rules, Rule{
+			WorkspaceAgentProcessPriorityRule: rule,
+			pattern:                           globRegexp(rule.Pattern),
+		})
+	}
+	return rules, nil
+}
+
+// globRegexp compiles a glob in which "*" matches any characters, including
+// slashes, and "?" matches a single character.
+func globRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+type Options struct {
+	Logger slog.Logger
+	Rules  []Rule
+	// System defaults to the processes of the host, which is only supported
+	// on Linux.
+	System System
+	Clock  quartz.Clock
+}
+
+// Manager periodically applies the rules to the processes of the workspace,
+// and keeps track of the processes it manages.
+type Manager struct {
+	logger slog.Logger
+	rules  []Rule
+	system System
+	clock  quartz.Clock
+	// self is the PID of the agent, which is never adjusted.
+	self int32
+
+	mu        sync.Mutex
+	processes map[int32]codersdk.WorkspaceAgentManagedProcess
+}
+
+func New(opts Options) *Manager {
+	if opts.System == nil {
+		opts.System = hostSystem{}
+	}
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+	return &Manager{
+		logger: opts.Logger.Named("proc-prio"),
+		rules:  opts.Rules,
+		system: opts.System,
+		clock:  opts.Clock,
+		//nolint:gosec // PIDs fit in an int32.
+		self:      int32(os.Getpid()),
+		processes: map[int32]codersdk.WorkspaceAgentManagedProcess{},
+	}
+}
+
+// Run applies the rules until the context is canceled.
+func (m *Manager) Run(ctx context.Context) {
+	scan := func() error {
+		err := m.Scan(ctx)
+		if err != nil && ctx.Err() == nil {
+			m.logger.Error(ctx, "manage process priority", slog.Error(err))
+		}
+		return nil
+	}
+	_ = scan()
+	tkr := m.clock.TickerFunc(ctx, scanInterval, scan, "agentproc", "scan")
+	_ = tkr.Wait()
+}
+
+// Scan applies the rules to the processes that are running. The first rule
+// that matches a process applies to it. Processes that exited are no longer
+// tracked.
+func (m *Manager) Scan(ctx context.Context) error {
+	procs, err := m.system.Processes()
+	if err != nil {
+		return xerrors.Errorf("list processes: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	processes := make(map[int32]codersdk.WorkspaceAgentManagedProcess, len(m.processes))
+	for _, proc := range procs {
+		if proc.PID == m.self {
+			continue
+		}
+		idx := slices.IndexFunc(m.rules, func(r Rule) bool { return r.Matches(proc) })
+		if idx < 0 {
+			continue
+		}
+		rule := m.rules[idx]
+
+		managed, ok := m.processes[proc.PID]
+		if !ok || managed.Name != proc.Name || managed.Pattern != rule.Pattern {
+			// The PID is new, or was reused by another process.
+			managed = codersdk.WorkspaceAgentManagedProcess{
+				PID:       proc.PID,
+				Name:      proc.Name,
+				CmdLine:   proc.CmdLine,
+				Pattern:   rule.Pattern,
+				ManagedAt: m.clock.Now(),
+			}
+		}
+		managed.Error = ""
+		err := m.apply(rule, &managed)
+		if err != nil {
+			if !ok || m.processes[proc.PID].Error != err.Error() {
+				m.logger.Warn(ctx, "adjust process priority",
+					slog.F("pid", proc.PID),
+					slog.F("name", proc.Name),
+					slog.F("pattern", rule.Pattern),
+					slog.Error(err),
+				)
+			}
+			managed.Error = err.Error()
+		}
+		processes[proc.PID] = managed
+	}
+	m.processes = processes
+	return nil
+}
+
+// apply adjusts the priority of the process if it differs from the rule, and
+// records its current priority.
+func (m *Manager) apply(rule Rule, managed *codersdk.WorkspaceAgentManagedProcess) error {
+	var errs []error
+	nice, err := m.system.Nice(managed.PID)
+	if err != nil {
+		errs = append(errs, xerrors.Errorf("get niceness: %w", err))
+	} else {
+		if rule.Nice != nil && nice != *rule.Nice {
+			err = m.system.SetNice(managed.PID, *rule.Nice)
+			if err != nil {
+				errs = append(errs, xerrors.Errorf("set niceness to %d: %w", *rule.Nice, err))
+			} else {
+				nice = *rule.Nice
+			}
+		}
+		managed.Nice = nice
+	}
+
+	score, err := m.system.OOMScoreAdj(managed.PID)
+	if err != nil {
+		errs = append(errs, xerrors.Errorf("get oom_score_adj: %w", err))
+	} else {
+		if rule.OOMScoreAdj != nil && score != *rule.OOMScoreAdj {
+			err = m.system.SetOOMScoreAdj(managed.PID, *rule.OOMScoreAdj)
+			if err != nil {
+				errs = append(errs, xerrors.Errorf("set oom_score_adj to %d: %w", *rule.OOMScoreAdj, err))
+			} else {
+				score = *rule.OOMScoreAdj
+			}
+		}
+		managed.OOMScoreAdj = score
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// Rules returns the rules of the manager.
+func (m *Manager) Rules() []codersdk.WorkspaceAgentProcessPriorityRule {
+	rules := make([]codersdk.WorkspaceAgentProcessPriorityRule, 0, len(m.rules))
+	for _, rule := range m.rules {
+		rules = append(rules, rule.WorkspaceAgentProcessPriorityRule)
+	}
+	return rules
+}
+
+// Processes returns the processes that matched a rule in the last scan,
+// sorted by PID.
+func (m *Manager) Processes() []codersdk.WorkspaceAgentManagedProcess {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	processes := make([]codersdk.WorkspaceAgentManagedProcess, 0, len(m.processes))
+	for _, p := range m.processes {
+		processes = append(processes, p)
+	}
+	slices.SortFunc(processes, func(a, b codersdk.WorkspaceAgentManagedProcess) int {
+		return int(a.PID - b.PID)
+	})
+	return processes
+}
@@ -0,0 +1,185 @@
+package agentproc_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent/agentproc"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestParseRules(t *testing.T) {
+	t.Parallel()
+
+	rules, err := agentproc.ParseRules(`[{"pattern": "gopls", "nice": 10}, {"pattern": "*tsserver.js*", "nice": 5, "oom_score_adj": 800}]`)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Equal(t, "gopls", rules[0].Pattern)
+	require.Equal(t, 10, *rules[0].Nice)
+	require.Nil(t, rules[0].OOMScoreAdj)
+	require.Equal(t, 800, *rules[1].OOMScoreAdj)
+
+	for _, raw := range []string{
+		`{"pattern": "gopls"}`,
+		`[{"nice": 10}]`,
+		`[{"pattern": "gopls"}]`,
+		`[{"pattern": "gopls", "nice": 20}]`,
+		`[{"pattern": "gopls", "oom_score_adj": -1001}]`,
+	} {
+		_, err := agentproc.ParseRules(raw)
+		require.Error(t, err, raw)
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	t.Parallel()
+
+	rules, err := agentproc.ParseRules(`[{"pattern": "*tsserver.js*", "nice": 5}, {"pattern": "rust-analyzer", "nice": 5}, {"pattern": "python3.?", "nice": 5}]`)
+	require.NoError(t, err)
+
+	tsserver := agentproc.Process{Name: "node", CmdLine: "/usr/bin/node /home/coder/.vscode-server/tsserver.js --serverMode partialSemantic"}
+	require.True(t, rules[0].Matches(tsserver))
+	require.False(t, rules[1].Matches(tsserver))
+	require.True(t, rules[1].Matches(agentproc.Process{Name: "rust-analyzer", CmdLine: "/home/coder/.cargo/bin/rust-analyzer"}))
+	require.False(t, rules[1].Matches(agentproc.Process{Name: "rust-analyzer-proc-macro-srv", CmdLine: "rust-analyzer-proc-macro-srv"}))
+	require.True(t, rules[2].Matches(agentproc.Process{Name: "python3.9", CmdLine: "python3.9 -m pylsp"}))
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	clock := quartz.NewMock(t)
+	sys := newFakeSystem()
+	sys.add(agentproc.Process{PID: 10, Name: "gopls", CmdLine: "gopls serve"}, 0, 0)
+	sys.add(agentproc.Process{PID: 11, Name: "node", CmdLine: "node tsserver.js"}, 0, 0)
+	sys.add(agentproc.Process{PID: 12, Name: "bash", CmdLine: "bash"}, 0, 0)
+
+	rules, err := agentproc.ParseRules(`[{"pattern": "gopls", "nice": 10, "oom_score_adj": 900}, {"pattern": "*tsserver*", "nice": -5}, {"pattern": "*", "nice": 1}]`)
+	require.NoError(t, err)
+	m := agentproc.New(agentproc.Options{
+		Logger: logger,
+		Rules:  rules,
+		System: sys,
+		Clock:  clock,
+	})
+
+	// Raising the priority of the tsserver is not permitted.
+	sys.failSetNice[11] = xerrors.New("permission denied")
+	require.NoError(t, m.Scan(ctx))
+
+	// Only the first rule that matches a process applies to it.
+	processes := m.Processes()
+	require.Len(t, processes, 3)
+	require.Equal(t, codersdk.WorkspaceAgentManagedProcess{
+		PID:         10,
+		Name:        "gopls",
+		CmdLine:     "gopls serve",
+		Pattern:     "gopls",
+		Nice:        10,
+		OOMScoreAdj: 900,
+		ManagedAt:   clock.Now(),
+	}, processes[0])
+	require.Equal(t, 10, sys.nice[10])
+	require.Equal(t, 900, sys.oom[10])
+	require.EqualValues(t, 11, processes[1].PID)
+	require.Equal(t, 0, processes[1].Nice)
+	require.Contains(t, processes[1].Error, "permission denied")
+	require.Equal(t, "*", processes[2].Pattern)
+	require.Equal(t, 1, sys.nice[12])
+
+	// Processes are adjusted again if their priority changes, and are no
+	// longer tracked once they exit.
+	firstManagedAt := clock.Now()
+	clock.Advance(testutil.WaitShort)
+	sys.nice[10] = 0
+	sys.remove(11)
+	require.NoError(t, m.Scan(ctx))
+	processes = m.Processes()
+	require.Len(t, processes, 2)
+	require.Equal(t, 10, processes[0].Nice)
+	require.Equal(t, firstManagedAt, processes[0].ManagedAt)
+
+	require.Equal(t, []codersdk.WorkspaceAgentProcessPriorityRule{
+		rules[0].WorkspaceAgentProcessPriorityRule,
+		rules[1].WorkspaceAgentProcessPriorityRule,
+		rules[2].WorkspaceAgentProcessPriorityRule,
+	}, m.Rules())
+}
+
+type fakeSystem struct {
+	mu          sync.Mutex
+	procs       []agentproc.Process
+	nice        map[int32]int
+	oom         map[int32]int
+	failSetNice map[int32]error
+}
+
+func newFakeSystem() *fakeSystem {
+	return &fakeSystem{
+		nice:        map[int32]int{},
+		oom:         map[int32]int{},
+		failSetNice: map[int32]error{},
+	}
+}
+
+func (s *fakeSystem) add(p agentproc.Process, nice, oom int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.procs = append(s.procs, p)
+	s.nice[p.PID] = nice
+	s.oom[p.PID] = oom
+}
+
+func (s *fakeSystem) remove(pid int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, p := range s.procs {
+		if p.PID == pid {
+			s.procs = append(s.procs[:i], s.procs[i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *fakeSystem) Processes() ([]agentproc.Process, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]agentproc.Process(nil), s.procs...), nil
+}
+
+func (s *fakeSystem) Nice(pid int32) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.nice[pid], nil
+}
+
+func (s *fakeSystem) SetNice(pid int32, nice int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failSetNice[pid]; err != nil {
+		return err
+	}
+	s.nice[pid] = nice
+	return nil
+}
+
+func (s *fakeSystem) OOMScoreAdj(pid int32) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.oom[pid], nil
+}
+
+func (s *fakeSystem) SetOOMScoreAdj(pid int32, score int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.oom[pid] = score
+	return nil
+}
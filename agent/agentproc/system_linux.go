@@ -0,0 +1,72 @@
+//go:build linux
+
+package agentproc
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+)
+
+// hostSystem reads the processes of the host from /proc.
+type hostSystem struct{}
+
+func (hostSystem) Processes() ([]Process, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, xerrors.Errorf("read /proc: %w", err)
+	}
+	var procs []Process
+	for _, entry := range entries {
+		pid, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		// Processes can exit at any time, and kernel threads have no
+		// command line, so both are skipped.
+		cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+		if err != nil || len(cmdline) == 0 {
+			continue
+		}
+		comm, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+		if err != nil {
+			continue
+		}
+		procs = append(procs, Process{
+			PID:     int32(pid),
+			Name:    strings.TrimSpace(string(comm)),
+			CmdLine: strings.TrimSpace(string(bytes.ReplaceAll(cmdline, []byte{0}, []byte{' '}))),
+		})
+	}
+	return procs, nil
+}
+
+func (hostSystem) Nice(pid int32) (int, error) {
+	prio, err := unix.Getpriority(unix.PRIO_PROCESS, int(pid))
+	if err != nil {
+		return 0, err
+	}
+	// See https://linux.die.net/man/2/setpriority#Notes
+	return 20 - prio, nil
+}
+
+func (hostSystem) SetNice(pid int32, nice int) error {
+	return unix.Setpriority(unix.PRIO_PROCESS, int(pid), nice)
+}
+
+func (hostSystem) OOMScoreAdj(pid int32) (int, error) {
+	raw, err := os.ReadFile(fmt.Sprintf("/proc/%d/oom_score_adj", pid))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(raw)))
+}
+
+func (hostSystem) SetOOMScoreAdj(pid int32, score int) error {
+	return os.WriteFile(fmt.Sprintf("/proc/%d/oom_score_adj", pid), []byte(strconv.Itoa(score)), 0o600)
+}
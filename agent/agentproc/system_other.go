@@ -0,0 +1,34 @@
+//go:build !linux
+
+package agentproc
+
+import (
+	"runtime"
+
+	"golang.org/x/xerrors"
+)
+
+// hostSystem is not supported outside of Linux.
+type hostSystem struct{}
+
+var errUnsupported = xerrors.Errorf("process priority management is not supported on %s", runtime.GOOS)
+
+func (hostSystem) Processes() ([]Process, error) {
+	return nil, errUnsupported
+}
+
+func (hostSystem) Nice(int32) (int, error) {
+	return 0, errUnsupported
+}
+
+func (hostSystem) SetNice(int32, int) error {
+	return errUnsupported
+}
+
+func (hostSystem) OOMScoreAdj(int32) (int, error) {
+	return 0, errUnsupported
+}
+
+func (hostSystem) SetOOMScoreAdj(int32, int) error {
+	return errUnsupported
+}
@@ -0,0 +1,120 @@
+// Package agenttoken rotates the session token of the agent on the schedule
+// of the deployment. Coderd keeps accepting the previous token for a grace
+// period, so requests in flight and processes that read it before the
+// rotation keep working for a while.
+package agenttoken
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/util/tokenfile"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/quartz"
+)
+
+const (
+	// checkInterval is how often the schedule is fetched again while
+	// rotation is disabled.
+	checkInterval = time.Hour
+	// retryInterval is how long to wait after a failed request.
+	retryInterval = time.Minute
+)
+
+// Client fetches the rotation schedule and rotates the token.
+// *agentsdk.Client implements it.
+type Client interface {
+	TokenRotation(ctx context.Context) (agentsdk.TokenRotation, error)
+	RotateToken(ctx context.Context) (agentsdk.RotateTokenResponse, error)
+	SetSessionToken(token string)
+}
+
+type Options struct {
+	Logger slog.Logger
+	Client Client
+	// TokenFile is written with every new token so the agent can
+	// authenticate after it restarts. It is empty when the token is not read
+	// from a file.
+	TokenFile string
+	Clock     quartz.Clock
+}
+
+// Rotator exchanges the session token of the agent for a fresh one when it
+// is due.
+type Rotator struct {
+	logger    slog.Logger
+	client    Client
+	tokenFile string
+	clock     quartz.Clock
+}
+
+func New(opts Options) *Rotator {
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+	return &Rotator{
+		logger:    opts.Logger.Named("token-rotation"),
+		client:    opts.Client,
+		tokenFile: opts.TokenFile,
+		clock:     opts.Clock,
+	}
+}
+
+// Run rotates the token whenever it is due until the context is canceled.
+func (r *Rotator) Run(ctx context.Context) {
+	for {
+		wait, err := r.next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			r.logger.Warn(ctx, "rotate agent token", slog.Error(err))
+			wait = retryInterval
+		}
+
+		tmr := r.clock.NewTimer(wait, "agenttoken", "wait")
+		select {
+		case <-ctx.Done():
+			tmr.Stop()
+			return
+		case <-tmr.C:
+		}
+	}
+}
+
+// next rotates the token if it is due and returns how long to wait before
+// checking again.
+func (r *Rotator) next(ctx context.Context) (time.Duration, error) {
+	rotation, err := r.client.TokenRotation(ctx)
+	if err != nil {
+		return 0, xerrors.Errorf("get token rotation: %w", err)
+	}
+	if !rotation.Enabled {
+		return checkInterval, nil
+	}
+	if wait := rotation.NextRotationAt.Sub(r.clock.Now()); wait > 0 {
+		return wait, nil
+	}
+
+	resp, err := r.client.RotateToken(ctx)
+	if err != nil {
+		return 0, xerrors.Errorf("rotate token: %w", err)
+	}
+	// The file is written first, so a restart right after the rotation
+	// does not read a token that is about to expire.
+	if r.tokenFile != "" {
+		err = tokenfile.Write(r.tokenFile, resp.SessionToken)
+		if err != nil {
+			return 0, xerrors.Errorf("write token file: %w", err)
+		}
+	}
+	r.client.SetSessionToken(resp.SessionToken)
+	r.logger.Info(ctx, "rotated agent token",
+		slog.F("previous_token_expires_at", resp.PreviousTokenExpiresAt),
+		slog.F("next_rotation_at", resp.NextRotationAt),
+	)
+	return resp.NextRotationAt.Sub(r.clock.Now()), nil
+}
@@ -0,0 +1,140 @@
+package agenttoken_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent/agenttoken"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestRotator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		mClock := quartz.NewMock(t)
+		trap := mClock.Trap().NewTimer("agenttoken", "wait")
+		defer trap.Close()
+		client := &fakeClient{clock: mClock}
+
+		r := agenttoken.New(agenttoken.Options{
+			Logger: slogtest.Make(t, nil),
+			Client: client,
+			Clock:  mClock,
+		})
+		go r.Run(ctx)
+
+		call := trap.MustWait(ctx)
+		call.MustRelease(ctx)
+		require.Equal(t, time.Hour, call.Duration)
+		require.Zero(t, client.rotations())
+	})
+
+	t.Run("Rotate", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		mClock := quartz.NewMock(t)
+		trap := mClock.Trap().NewTimer("agenttoken", "wait")
+		defer trap.Close()
+		client := &fakeClient{
+			clock:          mClock,
+			enabled:        true,
+			interval:       24 * time.Hour,
+			nextRotationAt: mClock.Now().Add(time.Hour),
+			token:          "initial",
+		}
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(tokenFile, []byte("initial"), 0o600))
+
+		r := agenttoken.New(agenttoken.Options{
+			Logger:    slogtest.Make(t, nil),
+			Client:    client,
+			TokenFile: tokenFile,
+			Clock:     mClock,
+		})
+		go r.Run(ctx)
+
+		// The token is not rotated before it is due.
+		call := trap.MustWait(ctx)
+		call.MustRelease(ctx)
+		require.Equal(t, time.Hour, call.Duration)
+		require.Zero(t, client.rotations())
+
+		mClock.Advance(time.Hour).MustWait(ctx)
+		call = trap.MustWait(ctx)
+		call.MustRelease(ctx)
+		require.Equal(t, 24*time.Hour, call.Duration)
+		require.Equal(t, 1, client.rotations())
+		require.Equal(t, "rotated-1", client.sessionToken())
+
+		data, err := os.ReadFile(tokenFile)
+		require.NoError(t, err)
+		require.Equal(t, "rotated-1", string(data))
+	})
+}
+
+type fakeClient struct {
+	clock    quartz.Clock
+	enabled  bool
+	interval time.Duration
+
+	mu             sync.Mutex
+	nextRotationAt time.Time
+	token          string
+	rotated        int
+}
+
+func (c *fakeClient) TokenRotation(context.Context) (agentsdk.TokenRotation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled {
+		return agentsdk.TokenRotation{}, nil
+	}
+	return agentsdk.TokenRotation{
+		Enabled:        true,
+		NextRotationAt: c.nextRotationAt,
+	}, nil
+}
+
+func (c *fakeClient) RotateToken(context.Context) (agentsdk.RotateTokenResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rotated++
+	now := c.clock.Now()
+	c.nextRotationAt = now.Add(c.interval)
+	return agentsdk.RotateTokenResponse{
+		SessionToken:           fmt.Sprintf("rotated-%d", c.rotated),
+		PreviousTokenExpiresAt: now.Add(10 * time.Minute),
+		NextRotationAt:         c.nextRotationAt,
+	}, nil
+}
+
+func (c *fakeClient) SetSessionToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+}
+
+func (c *fakeClient) rotations() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rotated
+}
+
+func (c *fakeClient) sessionToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
@@ -58,8 +58,11 @@ func (a *agent) apiHandler() http.Handler {
 	promHandler := PrometheusMetricsHandler(a.prometheusRegistry, a.logger)
 
 	r.Get("/api/v0/listening-ports", lp.handler)
+	r.Get("/api/v0/managed-processes", a.HandleManagedProcesses)
 	r.Get("/api/v0/netcheck", a.HandleNetcheck)
 	r.Post("/api/v0/list-directory", a.HandleLS)
+	r.Post("/api/v0/archive", a.HandleArchive)
+	r.Post("/api/v0/archive/restore", a.HandleRestoreArchive)
 	r.Get("/debug/logs", a.HandleHTTPDebugLogs)
 	r.Get("/debug/magicsock", a.HandleHTTPDebugMagicsock)
 	r.Get("/debug/magicsock/debug-logging/{state}", a.HandleHTTPMagicsockDebugLoggingState)
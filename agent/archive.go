@@ -0,0 +1,239 @@
+package agent
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+// HandleArchive writes a gzipped tar archive of the requested paths, e.g. to
+// archive a dormant workspace before it is deleted.
+func (a *agent) HandleArchive(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req workspacesdk.AgentArchiveRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get user home directory.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/gzip")
+	rw.WriteHeader(http.StatusOK)
+	err = writeArchive(a.filesystem, rw, archivePaths(home, req.Paths))
+	if err != nil {
+		a.logger.Error(ctx, "write archive", slog.F("paths", req.Paths), slog.Error(err))
+		// The status was already written, so abort the response to make sure
+		// the truncated archive isn't mistaken for a complete one.
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// HandleRestoreArchive extracts a gzipped tar archive written by
+// HandleArchive, overwriting existing files.
+func (a *agent) HandleRestoreArchive(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	err := restoreArchive(a.filesystem, r.Body)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to restore archive.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// archivePaths returns the absolute paths to archive. Relative paths are
+// relative to home, and paths inside other paths are dropped so files are only
+// archived once.
+func archivePaths(home string, paths []string) []string {
+	resolved := make([]string, 0, len(paths))
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if p == "~" || strings.HasPrefix(p, "~/") {
+			p = strings.TrimPrefix(strings.TrimPrefix(p, "~"), "/")
+		}
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(home, p)
+		}
+		resolved = append(resolved, filepath.Clean(p))
+	}
+	slices.Sort(resolved)
+
+	out := make([]string, 0, len(resolved))
+	for _, p := range resolved {
+		if len(out) > 0 {
+			last := out[len(out)-1]
+			if p == last || strings.HasPrefix(p, strings.TrimSuffix(last, string(filepath.Separator))+string(filepath.Separator)) {
+				continue
+			}
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// writeArchive writes a gzipped tar archive of the given absolute paths.
+// Paths that do not exist are skipped. Symlinks are archived as links and
+// are not followed.
+func writeArchive(afs afero.Fs, w io.Writer, paths []string) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	for _, root := range paths {
+		err := afero.Walk(afs, root, func(path string, info fs.FileInfo, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+
+			var link string
+			if info.Mode()&fs.ModeSymlink != 0 {
+				reader, ok := afs.(afero.LinkReader)
+				if !ok {
+					return nil
+				}
+				link, err = reader.ReadlinkIfPossible(path)
+				if err != nil {
+					return xerrors.Errorf("read link %q: %w", path, err)
+				}
+			} else if !info.Mode().IsRegular() && !info.IsDir() {
+				// Sockets, pipes and devices can't be restored.
+				return nil
+			}
+
+			hdr, err := tar.FileInfoHeader(info, link)
+			if err != nil {
+				return xerrors.Errorf("tar header for %q: %w", path, err)
+			}
+			hdr.Name = strings.TrimPrefix(filepath.ToSlash(path), "/")
+			if info.IsDir() {
+				hdr.Name += "/"
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return xerrors.Errorf("write tar header for %q: %w", path, err)
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+
+			f, err := afs.Open(path)
+			if err != nil {
+				return xerrors.Errorf("open %q: %w", path, err)
+			}
+			defer f.Close()
+			_, err = io.CopyN(tw, f, hdr.Size)
+			if err != nil {
+				return xerrors.Errorf("copy %q: %w", path, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return xerrors.Errorf("close tar writer: %w", err)
+	}
+	if err := gw.Close(); err != nil {
+		return xerrors.Errorf("close gzip writer: %w", err)
+	}
+	return nil
+}
+
+// restoreArchive extracts a gzipped tar archive written by writeArchive.
+func restoreArchive(afs afero.Fs, r io.Reader) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return xerrors.Errorf("read gzip: %w", err)
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("read tar: %w", err)
+		}
+
+		// Archives contain absolute paths without the leading slash.
+		path := filepath.FromSlash(filepath.Clean("/" + hdr.Name))
+		mode := hdr.FileInfo().Mode().Perm()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := afs.MkdirAll(path, mode); err != nil {
+				return xerrors.Errorf("create directory %q: %w", path, err)
+			}
+		case tar.TypeReg:
+			if err := afs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return xerrors.Errorf("create directory %q: %w", filepath.Dir(path), err)
+			}
+			f, err := afs.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+			if err != nil {
+				return xerrors.Errorf("create %q: %w", path, err)
+			}
+			_, err = io.Copy(f, tr)
+			closeErr := f.Close()
+			if err != nil {
+				return xerrors.Errorf("write %q: %w", path, err)
+			}
+			if closeErr != nil {
+				return xerrors.Errorf("close %q: %w", path, closeErr)
+			}
+		case tar.TypeSymlink:
+			linker, ok := afs.(afero.Linker)
+			if !ok {
+				continue
+			}
+			if err := afs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return xerrors.Errorf("create directory %q: %w", filepath.Dir(path), err)
+			}
+			if err := afs.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return xerrors.Errorf("remove %q: %w", path, err)
+			}
+			if err := linker.SymlinkIfPossible(hdr.Linkname, path); err != nil {
+				return xerrors.Errorf("create link %q: %w", path, err)
+			}
+			continue
+		default:
+			continue
+		}
+		if err := afs.Chtimes(path, hdr.ModTime, hdr.ModTime); err != nil {
+			return xerrors.Errorf("set times of %q: %w", path, err)
+		}
+	}
+}
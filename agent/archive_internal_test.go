@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchivePaths(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{
+		"/home/coder/.config",
+		"/home/coder/project",
+		"/var/lib/data",
+	}, archivePaths("/home/coder", []string{
+		"project",
+		"~/.config",
+		"/var/lib/data",
+		"/var/lib/data/cache",
+		"project/",
+		"  ",
+	}))
+	require.Equal(t, []string{"/home/coder"}, archivePaths("/home/coder", []string{"~", ".config"}))
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	src := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(src, "/home/coder/project/main.go", []byte("package main"), 0o644))
+	require.NoError(t, afero.WriteFile(src, "/home/coder/project/.git/HEAD", []byte("ref: refs/heads/main"), 0o600))
+	require.NoError(t, src.MkdirAll("/home/coder/project/empty", 0o700))
+	require.NoError(t, afero.WriteFile(src, "/home/coder/other.txt", []byte("not archived"), 0o644))
+
+	var buf bytes.Buffer
+	err := writeArchive(src, &buf, []string{"/home/coder/project", "/home/coder/missing"})
+	require.NoError(t, err)
+
+	dst := afero.NewMemMapFs()
+	// Existing files are overwritten.
+	require.NoError(t, afero.WriteFile(dst, "/home/coder/project/main.go", []byte("stale"), 0o644))
+	require.NoError(t, restoreArchive(dst, &buf))
+
+	data, err := afero.ReadFile(dst, "/home/coder/project/main.go")
+	require.NoError(t, err)
+	require.Equal(t, "package main", string(data))
+	info, err := dst.Stat("/home/coder/project/.git/HEAD")
+	require.NoError(t, err)
+	require.EqualValues(t, 0o600, info.Mode().Perm())
+	info, err = dst.Stat("/home/coder/project/empty")
+	require.NoError(t, err)
+	require.True(t, info.IsDir())
+	_, err = dst.Stat("/home/coder/other.txt")
+	require.ErrorIs(t, err, afero.ErrFileNotFound)
+
+	require.Error(t, restoreArchive(dst, bytes.NewReader([]byte("not an archive"))))
+}
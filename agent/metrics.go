@@ -20,6 +20,10 @@ type agentMetrics struct {
 	// took to run. This is reported once per agent.
 	startupScriptSeconds *prometheus.GaugeVec
 	currentConnections   *prometheus.GaugeVec
+	// cpuUsageCores and networkBytesPerSecond measure the resource usage of
+	// the workspace, so coderd can consider it active without sessions.
+	cpuUsageCores         prometheus.Gauge
+	networkBytesPerSecond prometheus.Gauge
 }
 
 func newAgentMetrics(registerer prometheus.Registerer) *agentMetrics {
@@ -54,11 +58,29 @@ func newAgentMetrics(registerer prometheus.Registerer) *agentMetrics {
 	}, []string{"connection_type"})
 	registerer.MustRegister(currentConnections)
 
+	cpuUsageCores := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "cpu_usage_cores",
+		Help:      "The number of CPU cores the workspace is using.",
+	})
+	registerer.MustRegister(cpuUsageCores)
+
+	networkBytesPerSecond := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "network_bytes_per_second",
+		Help:      "The number of bytes per second the workspace sends and receives on its network interfaces.",
+	})
+	registerer.MustRegister(networkBytesPerSecond)
+
 	return &agentMetrics{
 		connectionsTotal:      connectionsTotal,
 		reconnectingPTYErrors: reconnectingPTYErrors,
 		startupScriptSeconds:  startupScriptSeconds,
 		currentConnections:    currentConnections,
+		cpuUsageCores:         cpuUsageCores,
+		networkBytesPerSecond: networkBytesPerSecond,
 	}
 }
 
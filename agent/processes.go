@@ -0,0 +1,23 @@
+package agent
+
+import (
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// HandleManagedProcesses lists the processes whose priority the agent
+// adjusts according to the process priority rules of the template.
+func (a *agent) HandleManagedProcesses(rw http.ResponseWriter, r *http.Request) {
+	resp := codersdk.WorkspaceAgentManagedProcessesResponse{
+		Rules:     []codersdk.WorkspaceAgentProcessPriorityRule{},
+		Processes: []codersdk.WorkspaceAgentManagedProcess{},
+	}
+	if a.processPriority != nil {
+		resp.Enabled = true
+		resp.Rules = a.processPriority.Rules()
+		resp.Processes = a.processPriority.Processes()
+	}
+	httpapi.Write(r.Context(), rw, http.StatusOK, resp)
+}
@@ -0,0 +1,22 @@
+package proto
+
+const (
+	// MetricCPUUsageCores is the name of the agent metric with the number of
+	// CPU cores the workspace was using when stats were collected.
+	MetricCPUUsageCores = "coderd_agentstats_cpu_usage_cores"
+	// MetricNetworkBytesPerSecond is the name of the agent metric with the
+	// number of bytes per second the workspace sent and received on its
+	// network interfaces since stats were last collected.
+	MetricNetworkBytesPerSecond = "coderd_agentstats_network_bytes_per_second"
+)
+
+// MetricValue returns the value of the unlabeled metric with the given name.
+// The boolean is false if the stats do not include the metric.
+func (x *Stats) MetricValue(name string) (float64, bool) {
+	for _, m := range x.GetMetrics() {
+		if m.GetName() == name && len(m.GetLabels()) == 0 {
+			return m.GetValue(), true
+		}
+	}
+	return 0, false
+}
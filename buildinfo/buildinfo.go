@@ -27,6 +27,9 @@ var (
 	// Updated by buildinfo_site.go on start.
 	site bool
 
+	// Updated by buildinfo_fips.go on start.
+	fips bool
+
 	// Injected with ldflags at build, see scripts/build_go.sh
 	tag  string
 	agpl string // either "true" or "false", ldflags does not support bools
@@ -112,6 +115,12 @@ func IsBoringCrypto() bool {
 	return boringcrypto
 }
 
+// IsFIPS returns true if this is a FIPS build, which always runs in FIPS
+// mode.
+func IsFIPS() bool {
+	return fips
+}
+
 // ExternalURL returns a URL referencing the current Coder version.
 // For production builds, this will link directly to a release.
 // For development builds, this will link to a commit.
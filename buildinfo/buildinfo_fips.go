@@ -0,0 +1,7 @@
+//go:build fips
+
+package buildinfo
+
+func init() {
+	fips = true
+}
@@ -29,13 +29,19 @@ import (
 
 	"github.com/coder/coder/v2/agent"
 	"github.com/coder/coder/v2/agent/agentcontainers"
+	"github.com/coder/coder/v2/agent/agentegress"
 	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/agent/agentlogforward"
+	"github.com/coder/coder/v2/agent/agentmetadatapush"
+	"github.com/coder/coder/v2/agent/agentproc"
 	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/agent/agenttoken"
 	"github.com/coder/coder/v2/agent/reaper"
 	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/cli/clilog"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/codersdk/clientcert"
 )
 
 func (r *RootCmd) workspaceAgent() *serpent.Command {
@@ -56,12 +62,18 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 		agentHeaderCommand  string
 		agentHeader         []string
 		devcontainers       bool
+		logForwardURL       string
+		logForwardAppPaths  []string
+		metadataPushSocket  string
 	)
 	cmd := &serpent.Command{
 		Use:   "agent",
 		Short: `Starts the Coder workspace agent.`,
 		// This command isn't useful to manually execute.
 		Hidden: true,
+		Children: []*serpent.Command{
+			r.workspaceAgentAdopt(),
+		},
 		Handler: func(inv *serpent.Invocation) error {
 			ctx, cancel := context.WithCancelCause(inv.Context())
 			defer func() {
@@ -186,6 +198,10 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 			// with large payloads can take a bit. e.g. startup scripts
 			// may take a while to insert.
 			client.SDK.HTTPClient.Timeout = 30 * time.Second
+			// Present a client certificate from the internal CA of the
+			// deployment, if it has one enabled.
+			certRenewer := clientcert.New(logger, client.IssueCertificate)
+			client.SDK.HTTPClient.Transport = certRenewer.Transport(client.SDK.HTTPClient.Transport)
 			// Attach header transport so we process --agent-header and
 			// --agent-header-command flags
 			headerTransport, err := headerTransport(ctx, r.agentURL, agentHeader, agentHeaderCommand)
@@ -216,11 +232,13 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 			// This is abstracted to allow for the same looping condition
 			// regardless of instance identity auth type.
 			var exchangeToken func(context.Context) (agentsdk.AuthenticateResponse, error)
+			// tokenFile is set when the token is read from a file.
+			var tokenFile string
 			switch auth {
 			case "token":
 				token, _ := inv.ParsedFlags().GetString(varAgentToken)
 				if token == "" {
-					tokenFile, _ := inv.ParsedFlags().GetString(varAgentTokenFile)
+					tokenFile, _ = inv.ParsedFlags().GetString(varAgentTokenFile)
 					if tokenFile != "" {
 						tokenBytes, err := os.ReadFile(tokenFile)
 						if err != nil {
@@ -274,6 +292,72 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 				}
 			}
 
+			// Instance identity auth only sets the session token once the
+			// agent has exchanged it, so renewal is retried until then.
+			go certRenewer.Run(ctx)
+
+			// Enforce the egress policy of the template, if it has one.
+			// The coderd URL stays reachable so the agent can connect.
+			egressEnforcer := agentegress.New(agentegress.Options{
+				Logger:   logger,
+				Client:   client,
+				CoderURL: r.agentURL,
+			})
+			go egressEnforcer.Run(ctx)
+
+			var logForwarder *agentlogforward.Forwarder
+			if logForwardURL != "" {
+				exporter, err := agentlogforward.NewExporter(ctx, logForwardURL)
+				if err != nil {
+					return xerrors.Errorf("create log forward exporter: %w", err)
+				}
+				logForwarder = agentlogforward.New(agentlogforward.Options{
+					Logger:      logger,
+					Exporter:    exporter,
+					AppLogPaths: logForwardAppPaths,
+				})
+				go logForwarder.Run(ctx)
+				defer func() {
+					// The agent context is canceled by now, give the
+					// remaining logs some time to be exported.
+					closeCtx, closeCancel := context.WithTimeout(context.Background(), 10*time.Second)
+					defer closeCancel()
+					if err := logForwarder.Close(closeCtx); err != nil {
+						logger.Warn(closeCtx, "failed to forward remaining logs", slog.Error(err))
+					}
+				}()
+			}
+
+			// Processes in the workspace push metadata over a unix socket.
+			// The agent works without it, so failures are only logged.
+			if metadataPushSocket == "" {
+				metadataPushSocket = filepath.Join(os.TempDir(), "coder-agent-metadata.sock")
+			}
+			metadataPush, err := agentmetadatapush.New(agentmetadatapush.Options{
+				Logger:     logger,
+				SocketPath: metadataPushSocket,
+			})
+			if err != nil {
+				return xerrors.Errorf("create metadata push server: %w", err)
+			}
+			go func() {
+				if err := metadataPush.Serve(ctx); err != nil {
+					logger.Warn(ctx, "failed to serve metadata push API", slog.Error(err))
+				}
+			}()
+
+			// Rotate the token only if the agent can still authenticate
+			// after a restart: tokens from the environment cannot be
+			// updated, while instance identity is exchanged again.
+			if auth != "token" || tokenFile != "" {
+				tokenRotator := agenttoken.New(agenttoken.Options{
+					Logger:    logger,
+					Client:    client,
+					TokenFile: tokenFile,
+				})
+				go tokenRotator.Run(ctx)
+			}
+
 			executablePath, err := os.Executable()
 			if err != nil {
 				return xerrors.Errorf("getting os executable: %w", err)
@@ -320,6 +404,32 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 				return xerrors.Errorf("create agent execer: %w", err)
 			}
 
+			// Invalid rules are logged rather than failing the agent, since
+			// a typo in the template would otherwise break every workspace.
+			var processPriority *agentproc.Manager
+			if rawRules := os.Getenv(agentproc.EnvProcPrioRules); rawRules != "" {
+				rules, err := agentproc.ParseRules(rawRules)
+				switch {
+				case err != nil:
+					logger.Error(ctx, "invalid process priority rules",
+						slog.F("env_var", agentproc.EnvProcPrioRules),
+						slog.Error(err),
+					)
+				case runtime.GOOS != "linux":
+					logger.Warn(ctx, "process priority rules are only supported on linux",
+						slog.F("env_var", agentproc.EnvProcPrioRules),
+						slog.F("os", runtime.GOOS),
+					)
+				default:
+					logger.Info(ctx, "process priority rules enabled", slog.F("rules", len(rules)))
+					processPriority = agentproc.New(agentproc.Options{
+						Logger: logger,
+						Rules:  rules,
+					})
+					go processPriority.Run(ctx)
+				}
+			}
+
 			if devcontainers {
 				logger.Info(ctx, "agent devcontainer detection enabled")
 			} else {
@@ -365,6 +475,9 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 					DevcontainerAPIOptions: []agentcontainers.Option{
 						agentcontainers.WithSubAgentURL(r.agentURL.String()),
 					},
+					LogForwarder:    logForwarder,
+					MetadataPush:    metadataPush,
+					ProcessPriority: processPriority,
 				})
 
 				promHandler := agent.PrometheusMetricsHandler(prometheusRegistry, logger)
@@ -510,6 +623,27 @@ func (r *RootCmd) workspaceAgent() *serpent.Command {
 			Description: "Allow the agent to automatically detect running devcontainers.",
 			Value:       serpent.BoolOf(&devcontainers),
 		},
+		{
+			Flag:    "log-forward-url",
+			Env:     "CODER_AGENT_LOG_FORWARD_URL",
+			Default: "",
+			Description: "Forward startup and script logs to a log aggregator, labeled with the workspace, owner and agent. " +
+				"Supported URLs are loki://host:3100, cloudwatch://<log-group>?region=<region> and otlp://host:4317. " +
+				"Add ?insecure=true to Loki and OTLP URLs to connect without TLS.",
+			Value: serpent.StringOf(&logForwardURL),
+		},
+		{
+			Flag:        "log-forward-app-paths",
+			Env:         "CODER_AGENT_LOG_FORWARD_APP_PATHS",
+			Description: "Glob patterns of application log files to forward as well, e.g. /var/log/myapp/*.log. Requires --log-forward-url.",
+			Value:       serpent.StringArrayOf(&logForwardAppPaths),
+		},
+		{
+			Flag:        "metadata-push-socket",
+			Env:         "CODER_AGENT_METADATA_PUSH_SOCKET",
+			Description: "Path of the unix socket where processes in the workspace push metadata items and status lines. Defaults to coder-agent-metadata.sock in the temporary directory.",
+			Value:       serpent.StringOf(&metadataPushSocket),
+		},
 	}
 
 	return cmd
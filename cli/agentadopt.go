@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) workspaceAgentAdopt() *serpent.Command {
+	var (
+		adoptionToken string
+		hostname      string
+	)
+	cmd := &serpent.Command{
+		Use:   "adopt",
+		Short: "Adopt this machine as a workspace.",
+		Long: "Exchanges a workspace adoption token for a workspace bound to this machine. " +
+			"The machine is not managed by a provisioner, so the workspace can only be deleted, " +
+			"which leaves the machine running. Run `coder agent` with the printed token afterwards.",
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			if r.agentURL == nil || r.agentURL.String() == "" {
+				return xerrors.Errorf("%s must be set", envAgentURL)
+			}
+			if hostname == "" {
+				hostname, _ = os.Hostname()
+			}
+
+			client := agentsdk.New(r.agentURL)
+			resp, err := client.AdoptWorkspace(ctx, agentsdk.AdoptWorkspaceRequest{
+				Token:           adoptionToken,
+				Hostname:        hostname,
+				OperatingSystem: runtime.GOOS,
+				Architecture:    runtime.GOARCH,
+			})
+			if err != nil {
+				return xerrors.Errorf("adopt machine: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Adopted this machine as workspace %s.\n", cliui.Keyword(resp.WorkspaceName))
+			if r.agentTokenFile == "" {
+				_, _ = fmt.Fprintf(inv.Stdout, "Start the agent with:\n\n  %s=%s %s=%s coder agent\n",
+					envAgentURL, r.agentURL, envAgentToken, resp.SessionToken)
+				return nil
+			}
+			err = os.MkdirAll(filepath.Dir(r.agentTokenFile), 0o700)
+			if err != nil {
+				return xerrors.Errorf("create token file directory: %w", err)
+			}
+			err = os.WriteFile(r.agentTokenFile, []byte(resp.SessionToken), 0o600)
+			if err != nil {
+				return xerrors.Errorf("write token file %q: %w", r.agentTokenFile, err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Wrote the agent token to %s. Start the agent with:\n\n  %s=%s %s=%s coder agent\n",
+				r.agentTokenFile, envAgentURL, r.agentURL, envAgentTokenFile, r.agentTokenFile)
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "token",
+			Env:         "CODER_AGENT_ADOPTION_TOKEN",
+			Description: "The workspace adoption token.",
+			Value:       serpent.StringOf(&adoptionToken),
+			Required:    true,
+		},
+		{
+			Flag:        "hostname",
+			Env:         "CODER_AGENT_ADOPTION_HOSTNAME",
+			Description: "The name of the machine shown in the workspace. Defaults to the hostname.",
+			Value:       serpent.StringOf(&hostname),
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/serpent"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) archives() *serpent.Command {
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "archives",
+		Short:       "Manage the archives of deleted dormant workspaces",
+		Long: "Templates can archive paths of dormant workspaces to object storage before the workspaces are auto-deleted. " +
+			"Archives are kept for the retention period of the deployment, and can be restored into other workspaces.\n" + FormatExamples(
+			Example{
+				Description: "List the archives of your deleted workspaces",
+				Command:     "coder archives list",
+			},
+			Example{
+				Description: "Restore an archive into a running workspace",
+				Command:     "coder archives restore 2bd61b0e-5a1f-4a3e-9e4a-3a8d4e2c7f10 my-workspace",
+			},
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.archivesList(),
+			r.archivesRestore(),
+		},
+	}
+	return cmd
+}
+
+type archiveListRow struct {
+	// For JSON format:
+	codersdk.WorkspaceArchive `table:"-"`
+
+	// For table format:
+	ID        string    `json:"-" table:"id"`
+	Workspace string    `json:"-" table:"workspace,default_sort"`
+	Paths     []string  `json:"-" table:"paths"`
+	Size      string    `json:"-" table:"size"`
+	Error     string    `json:"-" table:"error"`
+	CreatedAt time.Time `json:"-" table:"created at"`
+	ExpiresAt time.Time `json:"-" table:"expires at"`
+}
+
+func archiveListRowFromArchive(archive codersdk.WorkspaceArchive) archiveListRow {
+	return archiveListRow{
+		WorkspaceArchive: archive,
+		ID:               archive.ID.String(),
+		Workspace:        archive.WorkspaceName,
+		Paths:            archive.Paths,
+		Size:             humanize.IBytes(uint64(archive.SizeBytes)),
+		Error:            archive.Error,
+		CreatedAt:        archive.CreatedAt,
+		ExpiresAt:        archive.ExpiresAt,
+	}
+}
+
+func (r *RootCmd) archivesList() *serpent.Command {
+	var (
+		user      string
+		formatter = cliui.NewOutputFormatter(
+			cliui.TableFormat([]archiveListRow{}, []string{"id", "workspace", "paths", "size", "error", "expires at"}),
+			cliui.JSONFormat(),
+		)
+	)
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the archives of deleted dormant workspaces",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			archives, err := client.WorkspaceArchives(inv.Context(), user)
+			if err != nil {
+				return xerrors.Errorf("list workspace archives: %w", err)
+			}
+
+			if len(archives) == 0 {
+				cliui.Infof(inv.Stdout, "No workspace archives found.\n")
+			}
+
+			rows := make([]archiveListRow, len(archives))
+			for i, archive := range archives {
+				rows[i] = archiveListRowFromArchive(archive)
+			}
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "user",
+			Description: "The user whose archives to list.",
+			Default:     codersdk.Me,
+			Value:       serpent.StringOf(&user),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) archivesRestore() *serpent.Command {
+	var agentName string
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "restore <archive-id> <workspace>",
+		Short: "Restore an archive into a running workspace, overwriting existing files",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			archiveID, err := uuid.Parse(inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("invalid archive ID %q: %w", inv.Args[0], err)
+			}
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[1])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			err = client.RestoreWorkspaceArchive(inv.Context(), workspace.ID, codersdk.RestoreWorkspaceArchiveRequest{
+				ArchiveID: archiveID,
+				AgentName: agentName,
+			})
+			if err != nil {
+				return xerrors.Errorf("restore workspace archive: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Restored archive %s into workspace %q.\n", archiveID, workspace.Name)
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "agent",
+			Description: "The name of the agent to restore the archive with. Defaults to the first agent of the workspace.",
+			Value:       serpent.StringOf(&agentName),
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,49 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+)
+
+func TestArchives(t *testing.T) {
+	t.Parallel()
+
+	var (
+		client, db           = coderdtest.NewWithDatabase(t, nil)
+		owner                = coderdtest.CreateFirstUser(t, client)
+		memberClient, member = coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ws                   = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{OwnerID: member.ID, OrganizationID: owner.OrganizationID}).Do()
+		archive              = dbgen.WorkspaceArchive(t, db, database.WorkspaceArchive{
+			WorkspaceID:    ws.Workspace.ID,
+			WorkspaceName:  ws.Workspace.Name,
+			OwnerID:        member.ID,
+			OrganizationID: owner.OrganizationID,
+			TemplateID:     ws.Workspace.TemplateID,
+			Paths:          []string{"~/project"},
+		})
+	)
+
+	inv, root := clitest.New(t, "archives", "list")
+	clitest.SetupConfig(t, memberClient, root)
+	var buf bytes.Buffer
+	inv.Stdout = &buf
+	err := inv.Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), archive.ID.String())
+	require.Contains(t, buf.String(), ws.Workspace.Name)
+	require.Contains(t, buf.String(), "~/project")
+
+	// Archives can't be restored unless archiving is enabled.
+	inv, root = clitest.New(t, "archives", "restore", archive.ID.String(), ws.Workspace.Name)
+	clitest.SetupConfig(t, memberClient, root)
+	err = inv.Run()
+	require.ErrorContains(t, err, "not enabled")
+}
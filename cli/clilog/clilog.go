@@ -29,6 +29,8 @@ type (
 		Stackdriver string
 		Trace       bool
 		Verbose     bool
+		// Sinks are additional log destinations with their own levels.
+		Sinks []codersdk.LogSink
 	}
 )
 
@@ -70,6 +72,12 @@ func WithTrace() Option {
 	}
 }
 
+func WithSinks(sinks ...codersdk.LogSink) Option {
+	return func(b *Builder) {
+		b.Sinks = sinks
+	}
+}
+
 func WithVerbose() Option {
 	return func(b *Builder) {
 		b.Verbose = true
@@ -84,6 +92,7 @@ func FromDeploymentValues(vals *codersdk.DeploymentValues) Option {
 		b.Stackdriver = vals.Logging.Stackdriver.Value()
 		b.Trace = vals.Trace.Enable.Value()
 		b.Verbose = vals.Verbose.Value()
+		b.Sinks = vals.Logging.Sinks.Value
 	}
 }
 
@@ -142,7 +151,7 @@ func (b *Builder) Build(inv *serpent.Invocation) (log slog.Logger, closeLog func
 	}
 
 	// User should log to null device if they don't want logs.
-	if len(sinks) == 0 {
+	if len(sinks) == 0 && len(b.Sinks) == 0 {
 		return slog.Logger{}, noopClose, xerrors.New("no loggers provided, use /dev/null to disable logging")
 	}
 
@@ -159,7 +168,25 @@ func (b *Builder) Build(inv *serpent.Invocation) (log slog.Logger, closeLog func
 		level = slog.LevelDebug
 	}
 
-	return inv.Logger.AppendSinks(filter).Leveled(level), func() {
+	// Additional sinks have their own levels, so the logger lets through the
+	// lowest of them and every sink drops what is below its own level.
+	loggerLevel := level
+	for i, cfg := range b.Sinks {
+		sink, sinkLevel, closeSink, err := buildSink(inv.Context(), cfg, level)
+		if err != nil {
+			return slog.Logger{}, noopClose, xerrors.Errorf("build log sink %d (%s): %w", i, cfg.Type, err)
+		}
+		closers = append(closers, closeSink)
+		filter.next = append(filter.next, &levelSink{next: sink, level: sinkLevel})
+		loggerLevel = min(loggerLevel, sinkLevel)
+	}
+	if loggerLevel < level {
+		for i := range sinks {
+			filter.next[i] = &levelSink{next: sinks[i], level: level}
+		}
+	}
+
+	return inv.Logger.AppendSinks(filter).Leveled(loggerLevel), func() {
 		for _, closer := range closers {
 			_ = closer()
 		}
@@ -1,7 +1,9 @@
 package clilog_test
 
 import (
+	"context"
 	"encoding/json"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,6 +16,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
 )
 
 func TestBuilder(t *testing.T) {
@@ -89,6 +94,74 @@ func TestBuilder(t *testing.T) {
 		assertLogsJSON(t, tempFile, debug, debugLog, info, infoLog, warn, warnLog, debug, filterLog)
 	})
 
+	t.Run("WithSinks", func(t *testing.T) {
+		t.Parallel()
+
+		humanFile := filepath.Join(t.TempDir(), "test.log")
+		debugFile := filepath.Join(t.TempDir(), "debug.log")
+		warnFile := filepath.Join(t.TempDir(), "warn.json")
+		cmd := &serpent.Command{
+			Use: "test",
+			Handler: testHandler(t,
+				clilog.WithHuman(humanFile),
+				clilog.WithSinks(
+					codersdk.LogSink{Type: codersdk.LogSinkTypeFile, Path: debugFile, Level: "debug"},
+					codersdk.LogSink{Type: codersdk.LogSinkTypeFile, Path: warnFile, Level: "warn", Format: "json"},
+				),
+			),
+		}
+		err := cmd.Invoke().Run()
+		require.NoError(t, err)
+		// Sinks with a lower level don't affect the other log locations.
+		assertLogs(t, humanFile, infoLog, warnLog)
+		assertLogs(t, debugFile, debugLog, infoLog, warnLog, filterLog)
+		assertLogsJSON(t, warnFile, warn, warnLog)
+	})
+
+	t.Run("WithInvalidSink", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := &serpent.Command{
+			Use: "test",
+			Handler: testHandler(t,
+				clilog.WithSinks(codersdk.LogSink{Type: codersdk.LogSinkTypeFile}),
+			),
+		}
+		err := cmd.Invoke().Run()
+		require.ErrorContains(t, err, "file sinks require a path")
+	})
+
+	t.Run("WithOTLPSink", func(t *testing.T) {
+		t.Parallel()
+
+		collector := &fakeLogsCollector{records: make(chan *logspb.LogRecord, 10)}
+		srv := grpc.NewServer()
+		collogspb.RegisterLogsServiceServer(srv, collector)
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		go func() { _ = srv.Serve(l) }()
+		t.Cleanup(srv.Stop)
+
+		cmd := &serpent.Command{
+			Use: "test",
+			Handler: testHandler(t,
+				clilog.WithSinks(codersdk.LogSink{
+					Type:     codersdk.LogSinkTypeOTLP,
+					Address:  l.Addr().String(),
+					Insecure: true,
+					Level:    "warn",
+				}),
+			),
+		}
+		err = cmd.Invoke().Run()
+		require.NoError(t, err)
+		// Closing the logger exports the remaining records.
+		require.Len(t, collector.records, 1)
+		record := <-collector.records
+		require.Equal(t, warnLog, record.GetBody().GetStringValue())
+		require.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_WARN, record.GetSeverityNumber())
+	})
+
 	t.Run("FromDeploymentValues", func(t *testing.T) {
 		t.Parallel()
 
@@ -156,6 +229,22 @@ var (
 	filterLog = "this is an important debug message you want to see"
 )
 
+type fakeLogsCollector struct {
+	collogspb.UnimplementedLogsServiceServer
+	records chan *logspb.LogRecord
+}
+
+func (c *fakeLogsCollector) Export(_ context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	for _, rl := range req.GetResourceLogs() {
+		for _, sl := range rl.GetScopeLogs() {
+			for _, record := range sl.GetLogRecords() {
+				c.records <- record
+			}
+		}
+	}
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
 func testHandler(t testing.TB, opts ...clilog.Option) serpent.HandlerFunc {
 	t.Helper()
 
@@ -0,0 +1,209 @@
+package clilog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"cdr.dev/slog"
+)
+
+const (
+	defaultOTLPAddress = "localhost:4317"
+	// otlpBatchSize is the most records exported at once.
+	otlpBatchSize = 512
+	// otlpBufferSize is the most records waiting to be exported. Records are
+	// dropped when the collector cannot keep up, so logging never blocks.
+	otlpBufferSize    = 4096
+	otlpFlushInterval = time.Second
+	otlpExportTimeout = 10 * time.Second
+)
+
+// otlpSink exports entries to the OpenTelemetry collector at address over
+// gRPC. Entries are exported in batches in the background.
+func otlpSink(ctx context.Context, address string, insecureTransport bool) (slog.Sink, func() error, error) {
+	if address == "" {
+		address = defaultOTLPAddress
+	}
+	creds := credentials.NewClientTLSFromCert(nil, "")
+	if insecureTransport {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, nil, xerrors.Errorf("create otlp client: %w", err)
+	}
+
+	attrs := []*commonpb.KeyValue{otlpAttribute("service.name", "coder")}
+	if hostname, err := os.Hostname(); err == nil {
+		attrs = append(attrs, otlpAttribute("host.name", hostname))
+	}
+	s := &otlpExporterSink{
+		client:   collogspb.NewLogsServiceClient(conn),
+		conn:     conn,
+		resource: &resourcepb.Resource{Attributes: attrs},
+		records:  make(chan *logspb.LogRecord, otlpBufferSize),
+		done:     make(chan struct{}),
+	}
+	go s.run(context.WithoutCancel(ctx))
+	return s, s.close, nil
+}
+
+type otlpExporterSink struct {
+	client   collogspb.LogsServiceClient
+	conn     *grpc.ClientConn
+	resource *resourcepb.Resource
+	done     chan struct{}
+
+	mu      sync.Mutex
+	closed  bool
+	records chan *logspb.LogRecord
+}
+
+func (s *otlpExporterSink) LogEntry(_ context.Context, ent slog.SinkEntry) {
+	record := otlpRecord(ent)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.records <- record:
+	default:
+	}
+}
+
+func (*otlpExporterSink) Sync() {}
+
+func (s *otlpExporterSink) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+	batch := make([]*logspb.LogRecord, 0, otlpBatchSize)
+	for {
+		select {
+		case record, ok := <-s.records:
+			if !ok {
+				s.export(ctx, batch)
+				return
+			}
+			batch = append(batch, record)
+			if len(batch) < otlpBatchSize {
+				continue
+			}
+		case <-ticker.C:
+		}
+		s.export(ctx, batch)
+		batch = batch[:0]
+	}
+}
+
+func (s *otlpExporterSink) export(ctx context.Context, records []*logspb.LogRecord) {
+	if len(records) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, otlpExportTimeout)
+	defer cancel()
+	// Errors are dropped, there is nowhere to log them to.
+	_, _ = s.client.Export(ctx, &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: s.resource,
+			ScopeLogs: []*logspb.ScopeLogs{{
+				Scope:      &commonpb.InstrumentationScope{Name: "github.com/coder/coder/v2/cli/clilog"},
+				LogRecords: records,
+			}},
+		}},
+	})
+}
+
+// close exports the remaining records and closes the connection.
+func (s *otlpExporterSink) close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.records)
+	}
+	s.mu.Unlock()
+	<-s.done
+	return s.conn.Close()
+}
+
+func otlpRecord(ent slog.SinkEntry) *logspb.LogRecord {
+	attrs := make([]*commonpb.KeyValue, 0, len(ent.Fields)+2)
+	if len(ent.LoggerNames) > 0 {
+		attrs = append(attrs, otlpAttribute("logger.name", strings.Join(ent.LoggerNames, ".")))
+	}
+	if ent.File != "" {
+		attrs = append(attrs, otlpAttribute("code.filepath", fmt.Sprintf("%s:%d", ent.File, ent.Line)))
+	}
+	for _, field := range ent.Fields {
+		attrs = append(attrs, &commonpb.KeyValue{Key: field.Name, Value: otlpValue(field.Value)})
+	}
+
+	record := &logspb.LogRecord{
+		TimeUnixNano:   uint64(ent.Time.UnixNano()), //nolint:gosec // Log times are after 1970.
+		SeverityNumber: otlpSeverity(ent.Level),
+		SeverityText:   ent.Level.String(),
+		Body:           &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: ent.Message}},
+		Attributes:     attrs,
+	}
+	if ent.SpanContext.IsValid() {
+		traceID, spanID := ent.SpanContext.TraceID(), ent.SpanContext.SpanID()
+		record.TraceId = traceID[:]
+		record.SpanId = spanID[:]
+	}
+	return record
+}
+
+func otlpSeverity(level slog.Level) logspb.SeverityNumber {
+	switch level {
+	case slog.LevelDebug:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG
+	case slog.LevelInfo:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO
+	case slog.LevelWarn:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+	case slog.LevelError:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR
+	default:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_FATAL
+	}
+}
+
+func otlpAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: otlpValue(value)}
+}
+
+func otlpValue(v any) *commonpb.AnyValue {
+	switch v := v.(type) {
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v}}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v}}
+	case int:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case int32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case int64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v}}
+	case float64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v}}
+	case error:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.Error()}}
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: fmt.Sprint(v)}}
+	}
+}
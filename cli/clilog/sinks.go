@@ -0,0 +1,121 @@
+package clilog
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"cdr.dev/slog/sloggers/slogjson"
+	"cdr.dev/slog/sloggers/slogstackdriver"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	defaultSinkMaxSizeMB  = 100
+	defaultSinkMaxBackups = 3
+)
+
+// buildSink creates the sink for cfg and returns it with its level and a
+// function that closes it. defaultLevel applies if cfg has no level.
+func buildSink(ctx context.Context, cfg codersdk.LogSink, defaultLevel slog.Level) (slog.Sink, slog.Level, func() error, error) {
+	level := defaultLevel
+	if cfg.Level != "" {
+		var err error
+		level, err = parseLevel(cfg.Level)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+	}
+	format, err := sinkFormat(cfg.Format)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	var (
+		sink      slog.Sink
+		closeSink func() error
+	)
+	switch cfg.Type {
+	case codersdk.LogSinkTypeFile:
+		if cfg.Path == "" {
+			return nil, 0, nil, xerrors.New("file sinks require a path")
+		}
+		maxSize := cfg.MaxSizeMB
+		if maxSize == 0 {
+			maxSize = defaultSinkMaxSizeMB
+		}
+		maxBackups := cfg.MaxBackups
+		if maxBackups == 0 {
+			maxBackups = defaultSinkMaxBackups
+		}
+		w := &LumberjackWriteCloseFixer{Writer: &lumberjack.Logger{
+			Filename:   cfg.Path,
+			MaxSize:    maxSize,
+			MaxBackups: maxBackups,
+			MaxAge:     cfg.MaxAgeDays,
+		}}
+		sink, closeSink = format(w), w.Close
+	case codersdk.LogSinkTypeSyslog:
+		sink, closeSink, err = syslogSink(cfg.Address, format)
+	case codersdk.LogSinkTypeJournald:
+		sink, closeSink, err = journaldSink()
+	case codersdk.LogSinkTypeOTLP:
+		sink, closeSink, err = otlpSink(ctx, cfg.Address, cfg.Insecure)
+	default:
+		return nil, 0, nil, xerrors.Errorf("unknown sink type %q, must be one of file, syslog, journald or otlp", cfg.Type)
+	}
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return sink, level, closeSink, nil
+}
+
+func parseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, xerrors.Errorf("unknown level %q, must be one of debug, info, warn or error", level)
+	}
+}
+
+func sinkFormat(format string) (func(io.Writer) slog.Sink, error) {
+	switch format {
+	case "", "human":
+		return sloghuman.Sink, nil
+	case "json":
+		return slogjson.Sink, nil
+	case "stackdriver":
+		return slogstackdriver.Sink, nil
+	default:
+		return nil, xerrors.Errorf("unknown format %q, must be one of human, json or stackdriver", format)
+	}
+}
+
+// levelSink drops entries below level.
+type levelSink struct {
+	next  slog.Sink
+	level slog.Level
+}
+
+func (s *levelSink) LogEntry(ctx context.Context, ent slog.SinkEntry) {
+	if ent.Level < s.level {
+		return
+	}
+	s.next.LogEntry(ctx, ent)
+}
+
+func (s *levelSink) Sync() {
+	s.next.Sync()
+}
@@ -0,0 +1,137 @@
+//go:build !windows
+
+package clilog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/syslog"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-systemd/journal"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+)
+
+// syslogSink sends entries to the syslog server at address, e.g.
+// udp://syslog:514, or to the local syslog daemon if address is empty.
+func syslogSink(address string, format func(io.Writer) slog.Sink) (slog.Sink, func() error, error) {
+	var network, raddr string
+	if address != "" {
+		u, err := url.Parse(address)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("parse syslog address: %w", err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, nil, xerrors.Errorf("syslog address %q must be of the form udp://host:port or tcp://host:port", address)
+		}
+		network, raddr = u.Scheme, u.Host
+	}
+	w, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_DAEMON, "coder")
+	if err != nil {
+		return nil, nil, xerrors.Errorf("dial syslog: %w", err)
+	}
+	return &syslogWriterSink{w: w, format: format}, w.Close, nil
+}
+
+type syslogWriterSink struct {
+	w      *syslog.Writer
+	format func(io.Writer) slog.Sink
+}
+
+func (s *syslogWriterSink) LogEntry(ctx context.Context, ent slog.SinkEntry) {
+	var buf bytes.Buffer
+	s.format(&buf).LogEntry(ctx, ent)
+	msg := strings.TrimSuffix(buf.String(), "\n")
+	// Errors are dropped, there is nowhere to log them to.
+	switch ent.Level {
+	case slog.LevelDebug:
+		_ = s.w.Debug(msg)
+	case slog.LevelInfo:
+		_ = s.w.Info(msg)
+	case slog.LevelWarn:
+		_ = s.w.Warning(msg)
+	case slog.LevelError:
+		_ = s.w.Err(msg)
+	case slog.LevelCritical:
+		_ = s.w.Crit(msg)
+	default:
+		_ = s.w.Alert(msg)
+	}
+}
+
+func (*syslogWriterSink) Sync() {}
+
+// journaldSink sends entries to the local systemd journal with their fields
+// as journal fields.
+func journaldSink() (slog.Sink, func() error, error) {
+	if !journal.Enabled() {
+		return nil, nil, xerrors.New("the systemd journal is not available")
+	}
+	return journaldWriterSink{}, func() error { return nil }, nil
+}
+
+type journaldWriterSink struct{}
+
+func (journaldWriterSink) LogEntry(_ context.Context, ent slog.SinkEntry) {
+	vars := map[string]string{
+		"SYSLOG_IDENTIFIER": "coder",
+		"CODE_FILE":         ent.File,
+		"CODE_LINE":         strconv.Itoa(ent.Line),
+		"CODE_FUNC":         ent.Func,
+	}
+	if len(ent.LoggerNames) > 0 {
+		vars["LOGGER"] = strings.Join(ent.LoggerNames, ".")
+	}
+	if ent.SpanContext.IsValid() {
+		vars["TRACE_ID"] = ent.SpanContext.TraceID().String()
+		vars["SPAN_ID"] = ent.SpanContext.SpanID().String()
+	}
+	for _, field := range ent.Fields {
+		if name := journaldFieldName(field.Name); name != "" {
+			vars[name] = fmt.Sprint(field.Value)
+		}
+	}
+
+	var priority journal.Priority
+	switch ent.Level {
+	case slog.LevelDebug:
+		priority = journal.PriDebug
+	case slog.LevelInfo:
+		priority = journal.PriInfo
+	case slog.LevelWarn:
+		priority = journal.PriWarning
+	case slog.LevelError:
+		priority = journal.PriErr
+	case slog.LevelCritical:
+		priority = journal.PriCrit
+	default:
+		priority = journal.PriAlert
+	}
+	// Errors are dropped, there is nowhere to log them to.
+	_ = journal.Send(ent.Message, priority, vars)
+}
+
+func (journaldWriterSink) Sync() {}
+
+// journaldFieldName converts a field name to a journal field name, which may
+// only contain uppercase letters, digits and underscores, and must not start
+// with an underscore.
+func journaldFieldName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	return strings.TrimLeft(name, "_")
+}
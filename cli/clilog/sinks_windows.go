@@ -0,0 +1,17 @@
+package clilog
+
+import (
+	"io"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+)
+
+func syslogSink(string, func(io.Writer) slog.Sink) (slog.Sink, func() error, error) {
+	return nil, nil, xerrors.New("syslog is not supported on Windows")
+}
+
+func journaldSink() (slog.Sink, func() error, error) {
+	return nil, nil, xerrors.New("journald is not supported on Windows")
+}
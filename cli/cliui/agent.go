@@ -355,6 +355,7 @@ type ConnDiags struct {
 	ConnInfo           workspacesdk.AgentConnectionInfo
 	PingP2P            bool
 	DisableDirect      bool
+	HTTPSFallback      bool
 	LocalNetInfo       *tailcfg.NetInfo
 	LocalInterfaces    *healthsdk.InterfacesReport
 	AgentNetcheck      *healthsdk.AgentNetcheckReport
@@ -426,6 +427,14 @@ func (d ConnDiags) splitDiagnostics() (general, client, agent []string) {
 		}
 	}
 
+	if d.HTTPSFallback {
+		general = append(general,
+			fmt.Sprintf("❗ UDP is blocked on your network, so all traffic is relayed over HTTPS\n   %s#https-only-fallback", d.TroubleshootingURL))
+		if !d.Verbose {
+			return general, client, agent
+		}
+	}
+
 	if !d.ConnInfo.DERPMap.HasSTUN() {
 		general = append(general,
 			fmt.Sprintf("❗ The DERP map is not configured to use STUN\n   %s#no-stun-servers", d.TroubleshootingURL))
@@ -693,6 +693,19 @@ func TestConnDiagnostics(t *testing.T) {
 				`❗ Your Coder administrator has blocked direct connections`,
 			},
 		},
+		{
+			name: "HTTPSFallback",
+			diags: cliui.ConnDiags{
+				ConnInfo: workspacesdk.AgentConnectionInfo{
+					DERPMap:       &tailcfg.DERPMap{},
+					HTTPSFallback: true,
+				},
+				HTTPSFallback: true,
+			},
+			want: []string{
+				`❗ UDP is blocked on your network, so all traffic is relayed over HTTPS`,
+			},
+		},
 		{
 			name: "NoStun",
 			diags: cliui.ConnDiags{
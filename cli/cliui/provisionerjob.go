@@ -27,6 +27,9 @@ func WorkspaceBuild(ctx context.Context, writer io.Writer, client *codersdk.Clie
 		Logs: func() (<-chan codersdk.ProvisionerJobLog, io.Closer, error) {
 			return client.WorkspaceBuildLogsAfter(ctx, build, 0)
 		},
+		FetchQueue: func() (codersdk.WorkspaceBuildQueue, error) {
+			return client.WorkspaceBuildQueue(ctx, build)
+		},
 	})
 }
 
@@ -34,6 +37,9 @@ type ProvisionerJobOptions struct {
 	Fetch  func() (codersdk.ProvisionerJob, error)
 	Cancel func() error
 	Logs   func() (<-chan codersdk.ProvisionerJobLog, io.Closer, error)
+	// FetchQueue optionally returns the queue of a pending job, which is used
+	// to show how many provisioners can run it and when it should start.
+	FetchQueue func() (codersdk.WorkspaceBuildQueue, error)
 
 	FetchInterval time.Duration
 	// Verbose determines whether debug and trace logs will be shown.
@@ -59,6 +65,14 @@ const (
 	ProvisioningStateRunning = "Running"
 )
 
+// queueETA formats the time until a queued job is expected to start.
+func queueETA(d time.Duration) string {
+	if d < time.Minute {
+		return "starting soon"
+	}
+	return "ETA ~" + strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+}
+
 // ProvisionerJob renders a provisioner job with interactive cancellation.
 func ProvisionerJob(ctx context.Context, wr io.Writer, opts ProvisionerJobOptions) error {
 	if opts.FetchInterval == 0 {
@@ -71,6 +85,7 @@ func ProvisionerJob(ctx context.Context, wr io.Writer, opts ProvisionerJobOption
 		currentStage          = ProvisioningStateQueued
 		currentStageStartedAt = time.Now().UTC()
 		currentQueuePos       = -1
+		currentQueue          *codersdk.WorkspaceBuildQueue
 
 		errChan  = make(chan error, 1)
 		job      codersdk.ProvisionerJob
@@ -89,6 +104,12 @@ func ProvisionerJob(ctx context.Context, wr io.Writer, opts ProvisionerJobOption
 			} else {
 				queuePos = fmt.Sprintf("position: %d", currentQueuePos)
 			}
+			if currentQueue != nil {
+				queuePos += fmt.Sprintf(", provisioners: %d", currentQueue.MatchedProvisioners.Available)
+				if currentQueue.EstimatedStartAt != nil {
+					queuePos += ", " + queueETA(time.Until(*currentQueue.EstimatedStartAt))
+				}
+			}
 
 			out = pretty.Sprintf(DefaultStyles.Warn, "%s (%s)", currentStage, queuePos)
 		}
@@ -126,6 +147,15 @@ func ProvisionerJob(ctx context.Context, wr io.Writer, opts ProvisionerJobOption
 			initialState := currentQueuePos == -1
 
 			currentQueuePos = job.QueuePosition
+			currentQueue = nil
+			if opts.FetchQueue != nil && currentQueuePos > 0 {
+				// The queue is only informational, so older servers that
+				// don't support it are ignored.
+				queue, err := opts.FetchQueue()
+				if err == nil {
+					currentQueue = &queue
+				}
+			}
 			// Print an update when the queue position changes, but:
 			//   - not initially, because the stage is printed at startup
 			//   - not when we're first in the queue, because it's redundant
@@ -12,11 +12,13 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/testutil"
 
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/pty/ptytest"
 	"github.com/coder/serpent"
@@ -104,6 +106,7 @@ func TestProvisionerJob(t *testing.T) {
 		tests := []struct {
 			name     string
 			queuePos int
+			queue    *codersdk.WorkspaceBuildQueue
 			expected string
 		}{
 			{
@@ -121,6 +124,15 @@ func TestProvisionerJob(t *testing.T) {
 				queuePos: 4,
 				expected: fmt.Sprintf(`%s %s$`, stage, regexp.QuoteMeta("(position: 4)")),
 			},
+			{
+				name:     "estimate",
+				queuePos: 4,
+				queue: &codersdk.WorkspaceBuildQueue{
+					MatchedProvisioners: codersdk.MatchedProvisioners{Count: 2, Available: 2},
+					EstimatedStartAt:    ptr.Ref(time.Now().Add(5*time.Minute + 10*time.Second)),
+				},
+				expected: fmt.Sprintf(`%s %s$`, stage, regexp.QuoteMeta("(position: 4, provisioners: 2, ETA ~5m)")),
+			},
 		}
 
 		for _, tc := range tests {
@@ -131,6 +143,7 @@ func TestProvisionerJob(t *testing.T) {
 				test.JobMutex.Lock()
 				test.Job.QueuePosition = tc.queuePos
 				test.Job.QueueSize = tc.queuePos
+				test.Queue = tc.queue
 				test.JobMutex.Unlock()
 
 				ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
@@ -207,18 +220,25 @@ func TestProvisionerJob(t *testing.T) {
 type provisionerJobTest struct {
 	Next     chan struct{}
 	Job      *codersdk.ProvisionerJob
+	Queue    *codersdk.WorkspaceBuildQueue
 	JobMutex *sync.Mutex
 	Logs     chan codersdk.ProvisionerJobLog
 	PTY      *ptytest.PTY
 }
 
-func newProvisionerJob(t *testing.T) provisionerJobTest {
+func newProvisionerJob(t *testing.T) *provisionerJobTest {
 	job := &codersdk.ProvisionerJob{
 		Status:    codersdk.ProvisionerJobPending,
 		CreatedAt: dbtime.Now(),
 	}
 	jobLock := sync.Mutex{}
 	logs := make(chan codersdk.ProvisionerJobLog, 1)
+	test := &provisionerJobTest{
+		Next:     make(chan struct{}),
+		Job:      job,
+		JobMutex: &jobLock,
+		Logs:     logs,
+	}
 	cmd := &serpent.Command{
 		Handler: func(inv *serpent.Invocation) error {
 			return cliui.ProvisionerJob(inv.Context(), inv.Stdout, cliui.ProvisionerJobOptions{
@@ -228,6 +248,14 @@ func newProvisionerJob(t *testing.T) provisionerJobTest {
 					defer jobLock.Unlock()
 					return *job, nil
 				},
+				FetchQueue: func() (codersdk.WorkspaceBuildQueue, error) {
+					jobLock.Lock()
+					defer jobLock.Unlock()
+					if test.Queue == nil {
+						return codersdk.WorkspaceBuildQueue{}, xerrors.New("no queue")
+					}
+					return *test.Queue, nil
+				},
 				Cancel: func() error {
 					return nil
 				},
@@ -254,13 +282,8 @@ func newProvisionerJob(t *testing.T) provisionerJobTest {
 	t.Cleanup(func() {
 		<-done
 	})
-	return provisionerJobTest{
-		Next:     make(chan struct{}),
-		Job:      job,
-		JobMutex: &jobLock,
-		Logs:     logs,
-		PTY:      ptty,
-	}
+	test.PTY = ptty
+	return test
 }
 
 type closeFunc func() error
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) clone() *serpent.Command {
+	var (
+		copyData  bool
+		agentName string
+	)
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "clone <workspace> <name>",
+		Short:       "Create a workspace from the template version and parameters of another workspace",
+		Long: "Creates a workspace with the template version and parameter values of the latest build of a workspace, " +
+			"e.g. to test risky changes without touching the original. With --copy-data, the data paths declared by " +
+			"the template are copied from the original workspace once the clone is running.\n" + FormatExamples(
+			Example{
+				Description: "Clone a workspace including its data",
+				Command:     "coder clone my-workspace my-experiment --copy-data",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			out := inv.Stdout
+
+			source, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			if copyData && source.LatestBuild.Status != codersdk.WorkspaceStatusRunning {
+				return xerrors.Errorf("workspace %q must be running to copy its data", source.Name)
+			}
+
+			workspace, err := client.CloneWorkspace(ctx, source.ID, codersdk.CloneWorkspaceRequest{
+				Name: inv.Args[1],
+			})
+			if err != nil {
+				return xerrors.Errorf("clone workspace: %w", err)
+			}
+			err = cliui.WorkspaceBuild(ctx, out, client, workspace.LatestBuild.ID)
+			if err != nil {
+				return xerrors.Errorf("watch build: %w", err)
+			}
+
+			if copyData {
+				err = copyWorkspaceData(ctx, inv, client, source, workspace, agentName)
+				if err != nil {
+					return err
+				}
+			}
+
+			_, _ = fmt.Fprintf(out,
+				"\nThe %s workspace has been cloned from %s at %s!\n",
+				pretty.Sprint(cliui.DefaultStyles.Keyword, workspace.Name),
+				pretty.Sprint(cliui.DefaultStyles.Keyword, source.Name),
+				cliui.Timestamp(time.Now()),
+			)
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "copy-data",
+			Description: "Copy the data paths declared by the template from the original workspace. Both workspaces must be running.",
+			Value:       serpent.BoolOf(&copyData),
+		},
+		{
+			Flag:        "agent",
+			Description: "The name of the agent to copy the data with. Defaults to the first agent of the workspace.",
+			Value:       serpent.StringOf(&agentName),
+		},
+	}
+	return cmd
+}
+
+// copyWorkspaceData waits for the agent of the cloned workspace to be ready,
+// since its startup script may create the data paths, and then copies them.
+func copyWorkspaceData(ctx context.Context, inv *serpent.Invocation, client *codersdk.Client, source, workspace codersdk.Workspace, agentName string) error {
+	workspace, err := client.Workspace(ctx, workspace.ID)
+	if err != nil {
+		return xerrors.Errorf("get workspace: %w", err)
+	}
+	agent, _, err := getWorkspaceAgent(workspace, agentName)
+	if err != nil {
+		return err
+	}
+	err = cliui.Agent(ctx, inv.Stderr, agent.ID, cliui.AgentOptions{
+		Fetch: client.WorkspaceAgent,
+		Wait:  true,
+	})
+	if err != nil {
+		return xerrors.Errorf("wait for agent: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(inv.Stdout, "Copying data from %s...\n", pretty.Sprint(cliui.DefaultStyles.Keyword, source.Name))
+	err = client.CopyWorkspaceData(ctx, workspace.ID, codersdk.CopyWorkspaceDataRequest{
+		SourceWorkspaceID: source.ID,
+		AgentName:         agent.Name,
+	})
+	if err != nil {
+		return xerrors.Errorf("copy workspace data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package cli_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version1 := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version1.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version1.ID)
+	workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	// The clone keeps the version of the original, not the active one.
+	version2 := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, nil, template.ID)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version2.ID)
+	coderdtest.UpdateActiveTemplateVersion(t, client, template.ID, version2.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "clone", workspace.Name, "experiment")
+	clitest.SetupConfig(t, member, root)
+
+	pty := ptytest.New(t).Attach(inv)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- inv.WithContext(ctx).Run()
+	}()
+	pty.ExpectMatch("workspace has been cloned")
+
+	err := <-done
+	require.NoError(t, err, "execute failed")
+
+	clone, err := member.WorkspaceByOwnerAndName(ctx, "me", "experiment", codersdk.WorkspaceOptions{})
+	require.NoError(t, err)
+	require.Equal(t, version1.ID, clone.LatestBuild.TemplateVersionID)
+}
@@ -32,6 +32,8 @@ func (r *RootCmd) create() *serpent.Command {
 		parameterFlags     workspaceParameterFlags
 		autoUpdates        string
 		copyParametersFrom string
+		labels             []string
+		ephemeral          bool
 		// Organization context is only required if more than 1 template
 		// shares the same name across multiple organizations.
 		orgContext = NewOrganizationContext()
@@ -286,6 +288,11 @@ func (r *RootCmd) create() *serpent.Command {
 				return err
 			}
 
+			workspaceLabels, err := parseWorkspaceLabels(labels)
+			if err != nil {
+				return err
+			}
+
 			var ttlMillis *int64
 			if stopAfter > 0 {
 				ttlMillis = ptr.Ref(stopAfter.Milliseconds())
@@ -298,6 +305,8 @@ func (r *RootCmd) create() *serpent.Command {
 				TTLMillis:           ttlMillis,
 				RichParameterValues: richParameters,
 				AutomaticUpdates:    codersdk.AutomaticUpdates(autoUpdates),
+				Labels:              workspaceLabels,
+				Ephemeral:           ephemeral,
 			})
 			if err != nil {
 				return xerrors.Errorf("create workspace: %w", err)
@@ -358,6 +367,18 @@ func (r *RootCmd) create() *serpent.Command {
 			Description: "Specify the source workspace name to copy parameters from.",
 			Value:       serpent.StringOf(&copyParametersFrom),
 		},
+		serpent.Option{
+			Flag:        "label",
+			Env:         "CODER_WORKSPACE_LABELS",
+			Description: "Specify labels of the workspace, in the format key=value.",
+			Value:       serpent.StringArrayOf(&labels),
+		},
+		serpent.Option{
+			Flag:        "ephemeral",
+			Env:         "CODER_WORKSPACE_EPHEMERAL",
+			Description: "Create an ephemeral workspace, which is deleted instead of stopped and only consumes quota while it is running.",
+			Value:       serpent.BoolOf(&ephemeral),
+		},
 		cliui.SkipPromptOption(),
 	)
 	cmd.Options = append(cmd.Options, parameterFlags.cliParameters()...)
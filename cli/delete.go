@@ -55,9 +55,7 @@ func (r *RootCmd) deleteWorkspace() *serpent.Command {
 				ProvisionerState: state,
 				Orphan:           orphan,
 			}
-			if prov.provisionerLogDebug {
-				req.LogLevel = codersdk.ProvisionerLogLevelDebug
-			}
+			prov.apply(inv, &req)
 			build, err := client.CreateWorkspaceBuild(inv.Context(), workspace.ID, req)
 			if err != nil {
 				return err
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/kubecontroller"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) workspaceControllerCommand() *serpent.Command {
+	var (
+		client         = new(codersdk.Client)
+		namespace      string
+		resyncInterval time.Duration
+		kubeAPIURL     string
+		kubeTokenFile  string
+		kubeCAFile     string
+	)
+	cmd := &serpent.Command{
+		Use:   "workspace-controller",
+		Short: "Reconcile Workspace custom resources in a Kubernetes cluster against Coder.",
+		Long: "Watches Workspace resources of the coder.com/v1alpha1 API group and creates, starts, stops and deletes " +
+			"the workspaces they declare. Run it in the cluster with a service account that can read the resources and " +
+			"update their finalizers and status.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			logger := inv.Logger.AppendSinks(sloghuman.Sink(inv.Stderr))
+
+			config := kubecontroller.KubeConfig{
+				Host:      kubeAPIURL,
+				TokenPath: kubeTokenFile,
+				CAPath:    kubeCAFile,
+			}
+			if config.Host == "" {
+				inCluster, err := kubecontroller.InClusterConfig()
+				if err != nil {
+					return xerrors.Errorf("%w, set --kube-api-url", err)
+				}
+				config.Host = inCluster.Host
+				if config.TokenPath == "" {
+					config.TokenPath = inCluster.TokenPath
+				}
+				if config.CAPath == "" {
+					config.CAPath = inCluster.CAPath
+				}
+			}
+			config.Namespace = namespace
+
+			kube, err := kubecontroller.NewKubeClient(config)
+			if err != nil {
+				return xerrors.Errorf("create kubernetes client: %w", err)
+			}
+			controller, err := kubecontroller.New(kubecontroller.Options{
+				Logger:         logger.Named("workspace_controller"),
+				Client:         client,
+				Kube:           kube,
+				ResyncInterval: resyncInterval,
+			})
+			if err != nil {
+				return xerrors.Errorf("create controller: %w", err)
+			}
+			logger.Info(ctx, "reconciling workspace resources", slog.F("namespace", namespace))
+			return controller.Run(ctx)
+		},
+		Options: serpent.OptionSet{
+			{
+				Flag:        "namespace",
+				Env:         "CODER_WORKSPACE_CONTROLLER_NAMESPACE",
+				Description: "Only reconcile resources in this namespace. All namespaces are watched if empty.",
+				Value:       serpent.StringOf(&namespace),
+			},
+			{
+				Flag:        "resync-interval",
+				Env:         "CODER_WORKSPACE_CONTROLLER_RESYNC_INTERVAL",
+				Description: "How often all resources are reconciled, even if they did not change.",
+				Default:     kubecontroller.DefaultResyncInterval.String(),
+				Value:       serpent.DurationOf(&resyncInterval),
+			},
+			{
+				Flag:        "kube-api-url",
+				Env:         "CODER_WORKSPACE_CONTROLLER_KUBE_API_URL",
+				Description: "The URL of the Kubernetes API. Defaults to the API of the cluster the controller runs in.",
+				Value:       serpent.StringOf(&kubeAPIURL),
+			},
+			{
+				Flag:        "kube-token-file",
+				Env:         "CODER_WORKSPACE_CONTROLLER_KUBE_TOKEN_FILE",
+				Description: "The file with the bearer token for the Kubernetes API. Defaults to the token of the pod service account.",
+				Value:       serpent.StringOf(&kubeTokenFile),
+			},
+			{
+				Flag:        "kube-ca-file",
+				Env:         "CODER_WORKSPACE_CONTROLLER_KUBE_CA_FILE",
+				Description: "The CA bundle of the Kubernetes API. Defaults to the CA of the pod service account.",
+				Value:       serpent.StringOf(&kubeCAFile),
+			},
+		},
+	}
+	return cmd
+}
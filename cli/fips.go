@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"crypto/fips140"
+	"crypto/tls"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// fipsCipherSuites are the TLS 1.2 cipher suites approved by NIST SP 800-52.
+// TLS 1.3 cipher suites are not configurable, and Go only negotiates approved
+// ones in FIPS mode.
+var fipsCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// ValidateFIPS returns an error that lists every setting requiring
+// cryptography that is not FIPS 140-3 approved, if FIPS mode is enabled.
+// FIPS builds always enable FIPS mode.
+func ValidateFIPS(vals *codersdk.DeploymentValues) error {
+	if buildinfo.IsFIPS() {
+		vals.FIPS = true
+	}
+	if !vals.FIPS.Value() {
+		return nil
+	}
+	violations := fipsViolations(vals, fips140.Enabled())
+	if len(violations) == 0 {
+		return nil
+	}
+	var report strings.Builder
+	_, _ = fmt.Fprintf(&report, "FIPS mode is enabled, but %d setting(s) are not FIPS 140-3 compliant:", len(violations))
+	for _, violation := range violations {
+		_, _ = fmt.Fprintf(&report, "\n  - %s", violation)
+	}
+	return xerrors.New(report.String())
+}
+
+// fipsViolations describes the settings that are not FIPS 140-3 compliant.
+func fipsViolations(vals *codersdk.DeploymentValues, moduleEnabled bool) []string {
+	var violations []string
+	if !moduleEnabled {
+		violations = append(violations, "GODEBUG: the Go Cryptographic Module is not in FIPS 140-3 mode. Use a FIPS build of Coder or set GODEBUG=fips140=on.")
+	}
+
+	switch minVersion := vals.TLS.MinVersion.String(); minVersion {
+	case "tls12", "tls13":
+	default:
+		violations = append(violations, fmt.Sprintf("--tls-min-version (CODER_TLS_MIN_VERSION): %q is not approved, use \"tls12\" or \"tls13\".", minVersion))
+	}
+
+	var unapproved []string
+	for _, name := range vals.TLS.SupportedCiphers.Value() {
+		approved := slices.ContainsFunc(fipsCipherSuites, func(id uint16) bool {
+			return strings.EqualFold(tls.CipherSuiteName(id), name)
+		})
+		if !approved {
+			unapproved = append(unapproved, name)
+		}
+	}
+	if len(unapproved) > 0 {
+		approved := make([]string, 0, len(fipsCipherSuites))
+		for _, id := range fipsCipherSuites {
+			approved = append(approved, tls.CipherSuiteName(id))
+		}
+		violations = append(violations, fmt.Sprintf("--tls-ciphers (CODER_TLS_CIPHERS): %s are not approved, use any of %s.", strings.Join(unapproved, ", "), strings.Join(approved, ", ")))
+	}
+
+	if vals.TLS.AllowInsecureCiphers.Value() {
+		violations = append(violations, "--tls-allow-insecure-ciphers (CODER_TLS_ALLOW_INSECURE_CIPHERS): insecure cipher suites are not approved.")
+	}
+	return violations
+}
+
+// fipsApprovedCipherSuites returns the approved cipher suites among the given
+// ones.
+func fipsApprovedCipherSuites(ids []uint16) []uint16 {
+	return slices.DeleteFunc(slices.Clone(ids), func(id uint16) bool {
+		return !slices.Contains(fipsCipherSuites, id)
+	})
+}
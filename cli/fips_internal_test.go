@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+func Test_fipsViolations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Compliant", func(t *testing.T) {
+		t.Parallel()
+		vals := &codersdk.DeploymentValues{}
+		vals.TLS.MinVersion = "tls12"
+		vals.TLS.SupportedCiphers = serpent.StringArray{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}
+		require.Empty(t, fipsViolations(vals, true))
+	})
+
+	t.Run("NonCompliant", func(t *testing.T) {
+		t.Parallel()
+		vals := &codersdk.DeploymentValues{}
+		vals.TLS.MinVersion = "tls10"
+		vals.TLS.SupportedCiphers = serpent.StringArray{
+			"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+			"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+			"TLS_RSA_WITH_AES_128_CBC_SHA",
+		}
+		vals.TLS.AllowInsecureCiphers = true
+
+		violations := fipsViolations(vals, false)
+		require.Len(t, violations, 4)
+		require.Contains(t, violations[0], "GODEBUG")
+		require.Contains(t, violations[1], "--tls-min-version")
+		require.Contains(t, violations[2], "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256, TLS_RSA_WITH_AES_128_CBC_SHA are not approved")
+		require.Contains(t, violations[3], "--tls-allow-insecure-ciphers")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		vals := &codersdk.DeploymentValues{}
+		vals.TLS.MinVersion = "tls10"
+		require.NoError(t, ValidateFIPS(vals))
+	})
+}
+
+func Test_fipsApprovedCipherSuites(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []uint16{
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	}, fipsApprovedCipherSuites([]uint16{
+		tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+	}))
+}
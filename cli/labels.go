@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/serpent"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) labels() *serpent.Command {
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "labels",
+		Short:       "Manage the labels of a workspace",
+		Long: "Labels are free-form key/value pairs used to group workspaces, e.g. by project or cost center. " +
+			"Workspaces can be searched by label with \"label:<key>[=<value>]\".\n" + FormatExamples(
+			Example{
+				Description: "Label a workspace",
+				Command:     "coder labels set my-workspace team=platform cost-center=eng-1234",
+			},
+			Example{
+				Description: "List the workspaces with a label",
+				Command:     `coder list --search "label:team=platform"`,
+			},
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.labelsSet(),
+			r.labelsUnset(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) labelsSet() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "set <workspace> <key=value>...",
+		Short: "Add labels to a workspace, or change the values of existing labels",
+		Middleware: serpent.Chain(
+			serpent.RequireRangeArgs(2, -1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			labels, err := parseWorkspaceLabels(inv.Args[1:])
+			if err != nil {
+				return err
+			}
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			newLabels := maps.Clone(workspace.Labels)
+			if newLabels == nil {
+				newLabels = map[string]string{}
+			}
+			maps.Copy(newLabels, labels)
+			err = client.UpdateWorkspaceLabels(inv.Context(), workspace.ID, codersdk.UpdateWorkspaceLabelsRequest{
+				Labels: newLabels,
+			})
+			if err != nil {
+				return xerrors.Errorf("update workspace labels: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Workspace %q labeled %s.\n", workspace.Name, formatWorkspaceLabels(newLabels))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) labelsUnset() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "unset <workspace> <key>...",
+		Short: "Remove labels from a workspace",
+		Middleware: serpent.Chain(
+			serpent.RequireRangeArgs(2, -1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			newLabels := maps.Clone(workspace.Labels)
+			for _, key := range inv.Args[1:] {
+				if _, ok := newLabels[key]; !ok {
+					return xerrors.Errorf("workspace %q has no label %q", workspace.Name, key)
+				}
+				delete(newLabels, key)
+			}
+			err = client.UpdateWorkspaceLabels(inv.Context(), workspace.ID, codersdk.UpdateWorkspaceLabelsRequest{
+				Labels: newLabels,
+			})
+			if err != nil {
+				return xerrors.Errorf("update workspace labels: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Removed %d label(s) from workspace %q.\n", len(inv.Args)-1, workspace.Name)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// parseWorkspaceLabels parses labels given as key=value pairs.
+func parseWorkspaceLabels(pairs []string) (map[string]string, error) {
+	labels := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, xerrors.Errorf("label %q must be in the format key=value", pair)
+		}
+		if err := codersdk.WorkspaceLabelKeyValid(key); err != nil {
+			return nil, xerrors.Errorf("label key %q %w", key, err)
+		}
+		if err := codersdk.WorkspaceLabelValueValid(value); err != nil {
+			return nil, xerrors.Errorf("value of label %q %w", key, err)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// formatWorkspaceLabels formats labels as comma-separated key=value pairs,
+// sorted by key.
+func formatWorkspaceLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,58 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+)
+
+func TestLabels(t *testing.T) {
+	t.Parallel()
+
+	var (
+		client, db           = coderdtest.NewWithDatabase(t, nil)
+		owner                = coderdtest.CreateFirstUser(t, client)
+		memberClient, member = coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ws                   = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{OwnerID: member.ID, OrganizationID: owner.OrganizationID}).Do()
+	)
+
+	inv, root := clitest.New(t, "labels", "set", ws.Workspace.Name, "team=platform", "cost-center=eng-1234")
+	clitest.SetupConfig(t, memberClient, root)
+	var buf bytes.Buffer
+	inv.Stdout = &buf
+	err := inv.Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "cost-center=eng-1234,team=platform")
+
+	updated := coderdtest.MustWorkspace(t, memberClient, ws.Workspace.ID)
+	require.Equal(t, map[string]string{"team": "platform", "cost-center": "eng-1234"}, updated.Labels)
+
+	// Labels are listed, and workspaces can be searched by label.
+	inv, root = clitest.New(t, "list", "--search", "label:team=platform", "--column", "workspace,labels")
+	clitest.SetupConfig(t, memberClient, root)
+	buf.Reset()
+	inv.Stdout = &buf
+	err = inv.Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), ws.Workspace.Name)
+	require.Contains(t, buf.String(), "cost-center=eng-1234,team=platform")
+
+	inv, root = clitest.New(t, "labels", "unset", ws.Workspace.Name, "team")
+	clitest.SetupConfig(t, memberClient, root)
+	inv.Stdout = &buf
+	err = inv.Run()
+	require.NoError(t, err)
+	updated = coderdtest.MustWorkspace(t, memberClient, ws.Workspace.ID)
+	require.Equal(t, map[string]string{"cost-center": "eng-1234"}, updated.Labels)
+
+	inv, root = clitest.New(t, "labels", "set", ws.Workspace.Name, "team")
+	clitest.SetupConfig(t, memberClient, root)
+	err = inv.Run()
+	require.ErrorContains(t, err, "must be in the format key=value")
+}
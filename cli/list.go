@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -38,6 +39,7 @@ type workspaceListRow struct {
 	StopsAfter       string    `json:"-" table:"stops after"`
 	StopsNext        string    `json:"-" table:"stops next"`
 	DailyCost        string    `json:"-" table:"daily cost"`
+	Labels           string    `json:"-" table:"labels"`
 }
 
 func workspaceListRowFromWorkspace(now time.Time, workspace codersdk.Workspace) workspaceListRow {
@@ -72,12 +74,14 @@ func workspaceListRowFromWorkspace(now time.Time, workspace codersdk.Workspace)
 		StopsAfter:       schedRow.StopsAfter,
 		StopsNext:        schedRow.StopsNext,
 		DailyCost:        strconv.Itoa(int(workspace.LatestBuild.DailyCost)),
+		Labels:           formatWorkspaceLabels(workspace.Labels),
 	}
 }
 
 func (r *RootCmd) list() *serpent.Command {
 	var (
 		filter    cliui.WorkspaceFilter
+		allOrgs   bool
 		formatter = cliui.NewOutputFormatter(
 			cliui.TableFormat(
 				[]workspaceListRow{},
@@ -107,7 +111,17 @@ func (r *RootCmd) list() *serpent.Command {
 			r.InitClient(client),
 		),
 		Handler: func(inv *serpent.Invocation) error {
-			res, err := queryConvertWorkspaces(inv.Context(), client, filter.Filter(), workspaceListRowFromWorkspace)
+			workspaceFilter := filter.Filter()
+			if allOrgs {
+				// Workspaces are listed across all organizations the user
+				// can read, so this lists the workspaces of all users and
+				// shows which organization they belong to.
+				workspaceFilter = codersdk.WorkspaceFilter{}
+				if !inv.ParsedFlags().Changed("column") {
+					_ = inv.ParsedFlags().Set("column", "organization name")
+				}
+			}
+			res, err := queryConvertWorkspaces(inv.Context(), client, workspaceFilter, workspaceListRowFromWorkspace)
 			if err != nil {
 				return err
 			}
@@ -130,6 +144,11 @@ func (r *RootCmd) list() *serpent.Command {
 		},
 	}
 	filter.AttachOptions(&cmd.Options)
+	cmd.Options = append(cmd.Options, serpent.Option{
+		Flag:        "all-orgs",
+		Description: "List the workspaces of all users in all organizations, including the organization column.",
+		Value:       serpent.BoolOf(&allOrgs),
+	})
 	formatter.AttachOptions(&cmd.Options)
 	return cmd
 }
@@ -151,3 +170,30 @@ func queryConvertWorkspaces[T any](ctx context.Context, client *codersdk.Client,
 	}
 	return converted, nil
 }
+
+// searchWorkspaces returns the workspaces matching the query once the user
+// confirmed the action on all of them. It's used by the commands that target
+// workspaces in bulk, e.g. by label.
+func searchWorkspaces(inv *serpent.Invocation, client *codersdk.Client, query, action string) ([]codersdk.Workspace, error) {
+	res, err := client.Workspaces(inv.Context(), codersdk.WorkspaceFilter{FilterQuery: query})
+	if err != nil {
+		return nil, xerrors.Errorf("query workspaces: %w", err)
+	}
+	if len(res.Workspaces) == 0 {
+		return nil, xerrors.Errorf("no workspaces match %q", query)
+	}
+
+	names := make([]string, 0, len(res.Workspaces))
+	for _, workspace := range res.Workspaces {
+		names = append(names, workspace.OwnerName+"/"+workspace.Name)
+	}
+	_, _ = fmt.Fprintf(inv.Stdout, "Workspaces matching %q: %s\n", query, cliui.Keyword(strings.Join(names, ", ")))
+	_, err = cliui.Prompt(inv, cliui.PromptOptions{
+		Text:      fmt.Sprintf("%s %d workspace(s)?", action, len(res.Workspaces)),
+		IsConfirm: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Workspaces, nil
+}
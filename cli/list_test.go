@@ -75,6 +75,29 @@ func TestList(t *testing.T) {
 		require.Len(t, workspaces, 1)
 	})
 
+	t.Run("AllOrgs", func(t *testing.T) {
+		t.Parallel()
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        memberUser.ID,
+		}).WithAgent().Do()
+
+		inv, root := clitest.New(t, "list", "--all-orgs")
+		//nolint:gocritic // listing the workspaces of other users requires an owner
+		clitest.SetupConfig(t, client, root)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		out := bytes.NewBuffer(nil)
+		inv.Stdout = out
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "ORGANIZATION NAME")
+		require.Contains(t, out.String(), r.Workspace.Name)
+	})
+
 	t.Run("NoWorkspacesJSON", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, nil)
@@ -112,6 +112,12 @@ func (r *RootCmd) loginWithPassword(
 	if err != nil {
 		return xerrors.Errorf("login with password: %w", err)
 	}
+	if resp.MFA != nil {
+		// Security keys can only be used from a browser, so the session token
+		// must be copied from there.
+		return xerrors.Errorf("your account requires a WebAuthn security key: log in with a browser at %s, then run %q with the session token",
+			client.URL.JoinPath("/cli-auth").String(), "coder login --token")
+	}
 
 	sessionToken := resp.SessionToken
 	config := r.createConfig()
@@ -1,12 +1,17 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 
 	"golang.org/x/xerrors"
 
 	"github.com/coder/serpent"
 
+	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -14,7 +19,7 @@ func (r *RootCmd) notifications() *serpent.Command {
 	cmd := &serpent.Command{
 		Use:   "notifications",
 		Short: "Manage Coder notifications",
-		Long: "Administrators can use these commands to change notification settings.\n" + FormatExamples(
+		Long: "Administrators can use these commands to change notification settings. Users can change which notifications they receive.\n" + FormatExamples(
 			Example{
 				Description: "Pause Coder notifications. Administrators can temporarily stop notifiers from dispatching messages in case of the target outage (for example: unavailable SMTP server or Webhook not responding).",
 				Command:     "coder notifications pause",
@@ -27,6 +32,14 @@ func (r *RootCmd) notifications() *serpent.Command {
 				Description: "Send a test notification. Administrators can use this to verify the notification target settings.",
 				Command:     "coder notifications test",
 			},
+			Example{
+				Description: "Stop receiving emails about workspace builds, while still receiving them in the inbox.",
+				Command:     "coder notifications preferences set builds disabled --method smtp",
+			},
+			Example{
+				Description: "Print the notifications of your inbox as they arrive.",
+				Command:     "coder notifications watch",
+			},
 		),
 		Aliases: []string{"notification"},
 		Handler: func(inv *serpent.Invocation) error {
@@ -36,6 +49,8 @@ func (r *RootCmd) notifications() *serpent.Command {
 			r.pauseNotifications(),
 			r.resumeNotifications(),
 			r.testNotifications(),
+			r.notificationPreferences(),
+			r.watchNotifications(),
 		},
 	}
 	return cmd
@@ -109,3 +124,308 @@ func (r *RootCmd) testNotifications() *serpent.Command {
 	}
 	return cmd
 }
+
+func (r *RootCmd) notificationPreferences() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "preferences",
+		Short: "Manage which notifications you receive",
+		Long: "Notifications are grouped into categories, which can be disabled entirely or only on some notification methods.\n" +
+			"Categories: " + strings.Join(notificationCategoryNames(), ", ") + ".",
+		Aliases: []string{"prefs"},
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.listNotificationPreferences(),
+			r.setNotificationPreferences(),
+		},
+	}
+	return cmd
+}
+
+type notificationCategoryPreferenceRow struct {
+	Category codersdk.NotificationCategory `json:"category" table:"category,default_sort"`
+	Method   string                        `json:"method" table:"method"`
+	Enabled  bool                          `json:"enabled" table:"enabled"`
+}
+
+func (r *RootCmd) listNotificationPreferences() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]notificationCategoryPreferenceRow{}, []string{"category", "method", "enabled"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List your notification preferences",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			methods, err := notificationMethods(ctx, client)
+			if err != nil {
+				return err
+			}
+			prefs, err := client.GetUserNotificationCategoryPreferences(ctx, codersdk.Me)
+			if err != nil {
+				return xerrors.Errorf("get notification preferences: %w", err)
+			}
+
+			rows := make([]notificationCategoryPreferenceRow, 0, len(codersdk.NotificationCategories)*len(methods))
+			for _, category := range codersdk.NotificationCategories {
+				for _, method := range methods {
+					disabled := slices.ContainsFunc(prefs, func(pref codersdk.NotificationCategoryPreference) bool {
+						return pref.Category == category && pref.Method == method && pref.Disabled
+					})
+					rows = append(rows, notificationCategoryPreferenceRow{
+						Category: category,
+						Method:   method,
+						Enabled:  !disabled,
+					})
+				}
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) setNotificationPreferences() *serpent.Command {
+	var methods []string
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "set <category> <enabled|disabled>",
+		Short: "Enable or disable the notifications of a category",
+		Long: FormatExamples(
+			Example{
+				Description: "Stop receiving notifications about dormant workspaces",
+				Command:     "coder notifications preferences set dormancy disabled",
+			},
+			Example{
+				Description: "Receive emails about template updates again",
+				Command:     "coder notifications preferences set template_updates enabled --method smtp",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			category := codersdk.NotificationCategory(inv.Args[0])
+			if !slices.Contains(codersdk.NotificationCategories, category) {
+				return xerrors.Errorf("unknown notification category %q, must be one of: %s", category, strings.Join(notificationCategoryNames(), ", "))
+			}
+			var disabled bool
+			switch inv.Args[1] {
+			case "enabled":
+			case "disabled":
+				disabled = true
+			default:
+				return xerrors.Errorf("the state must be \"enabled\" or \"disabled\", got %q", inv.Args[1])
+			}
+
+			if len(methods) == 0 {
+				var err error
+				methods, err = notificationMethods(ctx, client)
+				if err != nil {
+					return err
+				}
+			}
+
+			req := codersdk.UpdateUserNotificationCategoryPreferences{
+				Preferences: make([]codersdk.UpdateNotificationCategoryPreference, 0, len(methods)),
+			}
+			for _, method := range methods {
+				req.Preferences = append(req.Preferences, codersdk.UpdateNotificationCategoryPreference{
+					Category: category,
+					Method:   method,
+					Disabled: disabled,
+				})
+			}
+			if _, err := client.UpdateUserNotificationCategoryPreferences(ctx, codersdk.Me, req); err != nil {
+				return xerrors.Errorf("update notification preferences: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stderr, "Notifications of the %q category are now %s on: %s.\n", category, inv.Args[1], strings.Join(methods, ", "))
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "method",
+			Description: "The notification methods to change the preference of, e.g. smtp, webhook or inbox. Defaults to all methods.",
+			Value:       serpent.StringArrayOf(&methods),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) watchNotifications() *serpent.Command {
+	var (
+		categories       []string
+		templates        []string
+		readStatusEvents bool
+		output           string
+	)
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "watch",
+		Short: "Print the notifications of your inbox as they arrive",
+		Long: FormatExamples(
+			Example{
+				Description: "Watch the notifications about dormant workspaces",
+				Command:     "coder notifications watch --category dormancy",
+			},
+			Example{
+				Description: "Stream notifications and read status changes as JSON lines",
+				Command:     "coder notifications watch --read-status-events --output json",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			for _, category := range categories {
+				if !slices.Contains(codersdk.NotificationCategories, codersdk.NotificationCategory(category)) {
+					return xerrors.Errorf("unknown notification category %q, must be one of: %s", category, strings.Join(notificationCategoryNames(), ", "))
+				}
+			}
+
+			req := codersdk.WatchInboxNotificationsRequest{
+				Categories:       strings.Join(categories, ","),
+				Templates:        strings.Join(templates, ","),
+				ReadStatusEvents: readStatusEvents,
+			}
+			if output == "text" {
+				req.Format = "plaintext"
+			}
+			events, closer, err := client.WatchInboxNotifications(ctx, req)
+			if err != nil {
+				return xerrors.Errorf("watch notifications: %w", err)
+			}
+			defer closer.Close()
+
+			if output == "text" {
+				_, _ = fmt.Fprintln(inv.Stderr, "Watching for notifications, press Ctrl+C to stop...")
+			}
+			enc := json.NewEncoder(inv.Stdout)
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case event, ok := <-events:
+					if !ok {
+						if ctx.Err() != nil {
+							return nil
+						}
+						return xerrors.New("the connection to the notifications watch was closed")
+					}
+					if output == "json" {
+						if err := enc.Encode(event); err != nil {
+							return err
+						}
+						continue
+					}
+					_, _ = fmt.Fprintln(inv.Stdout, formatInboxNotificationEvent(event))
+				}
+			}
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "category",
+			Description: "Only print the notifications of these categories.",
+			Value:       serpent.StringArrayOf(&categories),
+		},
+		{
+			Flag:        "template",
+			Description: "Only print the notifications of these notification template IDs.",
+			Value:       serpent.StringArrayOf(&templates),
+		},
+		{
+			Flag:        "read-status-events",
+			Description: "Also print when notifications are marked as read or unread, e.g. from the dashboard.",
+			Value:       serpent.BoolOf(&readStatusEvents),
+		},
+		{
+			Flag:          "output",
+			FlagShorthand: "o",
+			Description:   "Output format, json prints one JSON object per line.",
+			Default:       "text",
+			Value:         serpent.EnumOf(&output, "text", "json"),
+		},
+	}
+	return cmd
+}
+
+// formatInboxNotificationEvent renders a message of the inbox notifications
+// watch for the terminal.
+func formatInboxNotificationEvent(event codersdk.GetInboxNotificationResponse) string {
+	if event.Kind == codersdk.InboxNotificationEventKindReadStatus && event.ReadStatus != nil {
+		state := "unread"
+		if event.ReadStatus.IsRead {
+			state = "read"
+		}
+		var what string
+		switch {
+		case len(event.ReadStatus.IDs) > 0:
+			what = fmt.Sprintf("%d notification(s)", len(event.ReadStatus.IDs))
+		case len(event.ReadStatus.Categories) > 0:
+			names := make([]string, 0, len(event.ReadStatus.Categories))
+			for _, category := range event.ReadStatus.Categories {
+				names = append(names, string(category))
+			}
+			what = "Notifications of " + strings.Join(names, ", ")
+		default:
+			what = "All notifications"
+		}
+		return fmt.Sprintf("%s marked as %s (%d unread)", what, state, event.UnreadCount)
+	}
+
+	notif := event.Notification
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "%s %s (%d unread)", cliui.Timestamp(notif.CreatedAt), cliui.Bold(notif.Title), event.UnreadCount)
+	if content := strings.TrimSpace(notif.Content); content != "" {
+		for _, line := range strings.Split(content, "\n") {
+			_, _ = fmt.Fprintf(&b, "\n  %s", line)
+		}
+	}
+	for _, action := range notif.Actions {
+		_, _ = fmt.Fprintf(&b, "\n  %s: %s", action.Label, action.URL)
+	}
+	return b.String()
+}
+
+// notificationMethods returns every method notifications can be delivered
+// on, including the inbox.
+func notificationMethods(ctx context.Context, client *codersdk.Client) ([]string, error) {
+	resp, err := client.GetNotificationDispatchMethods(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("get notification methods: %w", err)
+	}
+	return append(resp.AvailableNotificationMethods, "inbox"), nil
+}
+
+func notificationCategoryNames() []string {
+	names := make([]string, 0, len(codersdk.NotificationCategories))
+	for _, category := range codersdk.NotificationCategories {
+		names = append(names, string(category))
+	}
+	return names
+}
@@ -7,14 +7,18 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/dispatch"
 	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
+	"github.com/coder/coder/v2/coderd/notifications/types"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/pty/ptytest"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -166,3 +170,107 @@ func TestNotificationsTest(t *testing.T) {
 		require.Len(t, sent, 0)
 	})
 }
+
+func TestNotificationPreferences(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SetAndList", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		// Given: A member user.
+		ownerClient := coderdtest.New(t, createOpts(t))
+		ownerUser := coderdtest.CreateFirstUser(t, ownerClient)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, ownerClient, ownerUser.OrganizationID)
+
+		// When: The member disables emails about workspace builds.
+		inv, root := clitest.New(t, "notifications", "preferences", "set", "builds", "disabled", "--method", "smtp")
+		clitest.SetupConfig(t, memberClient, root)
+		require.NoError(t, inv.WithContext(ctx).Run())
+
+		// Then: only the email delivery of build notifications is disabled.
+		prefs, err := memberClient.GetUserNotificationCategoryPreferences(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, prefs, 1)
+		require.Equal(t, codersdk.NotificationCategoryBuilds, prefs[0].Category)
+		require.Equal(t, "smtp", prefs[0].Method)
+		require.True(t, prefs[0].Disabled)
+
+		// When: The member lists their preferences.
+		buf := new(bytes.Buffer)
+		inv, root = clitest.New(t, "notifications", "preferences", "list", "--output", "json")
+		inv.Stdout = buf
+		clitest.SetupConfig(t, memberClient, root)
+		require.NoError(t, inv.WithContext(ctx).Run())
+
+		// Then: every category and method is listed.
+		var rows []struct {
+			Category string `json:"category"`
+			Method   string `json:"method"`
+			Enabled  bool   `json:"enabled"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &rows))
+		require.NotEmpty(t, rows)
+		for _, row := range rows {
+			require.Equal(t, !(row.Category == "builds" && row.Method == "smtp"), row.Enabled, "%s on %s", row.Category, row.Method)
+		}
+	})
+
+	t.Run("UnknownCategory", func(t *testing.T) {
+		t.Parallel()
+
+		ownerClient := coderdtest.New(t, createOpts(t))
+		_ = coderdtest.CreateFirstUser(t, ownerClient)
+
+		inv, root := clitest.New(t, "notifications", "preferences", "set", "pigeons", "disabled")
+		clitest.SetupConfig(t, ownerClient, root)
+		require.ErrorContains(t, inv.Run(), `unknown notification category "pigeons"`)
+	})
+}
+
+func TestNotificationsWatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+
+	// Given: A member user watching their notifications.
+	ownerClient, _, api := coderdtest.NewWithAPI(t, createOpts(t))
+	ownerUser := coderdtest.CreateFirstUser(t, ownerClient)
+	memberClient, member := coderdtest.CreateAnotherUser(t, ownerClient, ownerUser.OrganizationID)
+
+	inv, root := clitest.New(t, "notifications", "watch", "--category", "builds", "--read-status-events")
+	clitest.SetupConfig(t, memberClient, root)
+	pty := ptytest.New(t).Attach(inv)
+	clitest.Start(t, inv.WithContext(ctx))
+	pty.ExpectMatch("Watching for notifications")
+
+	// When: Notifications of different categories are delivered to the inbox.
+	inboxHandler := dispatch.NewInboxHandler(testutil.Logger(t), api.Database, api.Pubsub)
+	for _, n := range []struct {
+		templateID uuid.UUID
+		title      string
+	}{
+		{notifications.TemplateWorkspaceDormant, "Workspace dormant"},
+		{notifications.TemplateWorkspaceDeleted, "Workspace deleted"},
+	} {
+		dispatchFunc, err := inboxHandler.Dispatcher(types.MessagePayload{
+			UserID:                 member.ID.String(),
+			NotificationTemplateID: n.templateID.String(),
+		}, n.title, "Your workspace **dev** changed.", nil)
+		require.NoError(t, err)
+		_, err = dispatchFunc(ctx, uuid.New())
+		require.NoError(t, err)
+	}
+
+	// Then: only the notification of the watched category is printed, as
+	// plain text.
+	pty.ExpectMatch("Workspace deleted (2 unread)")
+	pty.ExpectMatch("Your workspace dev changed.")
+
+	// When: The notifications are marked as read.
+	require.NoError(t, memberClient.MarkAllInboxNotificationsAsRead(ctx))
+
+	// Then: the read status change is printed.
+	pty.ExpectMatch("All notifications marked as read (0 unread)")
+}
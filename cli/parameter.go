@@ -145,9 +145,12 @@ func parseParameterMapFile(parameterFile string) (map[string]string, error) {
 	return parameterMap, nil
 }
 
-// buildFlags contains options relating to troubleshooting provisioner jobs.
+// buildFlags contains options relating to troubleshooting provisioner jobs
+// and to recording why a build was started.
 type buildFlags struct {
 	provisionerLogDebug bool
+	reason              string
+	ciJobURL            string
 }
 
 func (bf *buildFlags) cliOptions() []serpent.Option {
@@ -160,5 +163,47 @@ This is useful for troubleshooting build issues.`,
 			Value:  serpent.BoolOf(&bf.provisionerLogDebug),
 			Hidden: true,
 		},
+		{
+			Flag:        "reason",
+			Env:         "CODER_BUILD_REASON",
+			Description: "Why the build is being started, shown in build listings and audit logs.",
+			Value:       serpent.StringOf(&bf.reason),
+		},
+		{
+			Flag:        "ci-job-url",
+			Env:         "CODER_CI_JOB_URL",
+			Description: "The URL of the CI job starting the build. Detected automatically in GitHub Actions, GitLab CI and Jenkins.",
+			Value:       serpent.StringOf(&bf.ciJobURL),
+		},
+	}
+}
+
+// apply sets the options of the flags on a build request.
+func (bf *buildFlags) apply(inv *serpent.Invocation, req *codersdk.CreateWorkspaceBuildRequest) {
+	if bf.provisionerLogDebug {
+		req.LogLevel = codersdk.ProvisionerLogLevelDebug
+	}
+	req.ReasonMessage = bf.reason
+	req.CIJobURL = bf.ciJobURL
+	if req.CIJobURL == "" {
+		req.CIJobURL = detectCIJobURL(inv.Environ)
+	}
+}
+
+// detectCIJobURL returns the URL of the CI job the CLI is running in, if it
+// can be determined from the environment.
+func detectCIJobURL(env serpent.Environ) string {
+	// GitHub Actions
+	if runID := env.Get("GITHUB_RUN_ID"); runID != "" && env.Get("GITHUB_SERVER_URL") != "" && env.Get("GITHUB_REPOSITORY") != "" {
+		return fmt.Sprintf("%s/%s/actions/runs/%s", env.Get("GITHUB_SERVER_URL"), env.Get("GITHUB_REPOSITORY"), runID)
+	}
+	// GitLab CI
+	if u := env.Get("CI_JOB_URL"); u != "" {
+		return u
+	}
+	// Jenkins
+	if u := env.Get("BUILD_URL"); u != "" && env.Get("JENKINS_URL") != "" {
+		return u
 	}
+	return ""
 }
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/coder/serpent"
 )
 
 func TestCreateParameterMapFromFile(t *testing.T) {
@@ -58,6 +60,55 @@ func TestCreateParameterMapFromFile(t *testing.T) {
 	})
 }
 
+func TestDetectCIJobURL(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{name: "None", env: map[string]string{}, expected: ""},
+		{
+			name: "GitHubActions",
+			env: map[string]string{
+				"GITHUB_SERVER_URL": "https://github.com",
+				"GITHUB_REPOSITORY": "coder/coder",
+				"GITHUB_RUN_ID":     "123",
+			},
+			expected: "https://github.com/coder/coder/actions/runs/123",
+		},
+		{
+			name:     "GitLabCI",
+			env:      map[string]string{"CI_JOB_URL": "https://gitlab.com/coder/coder/-/jobs/456"},
+			expected: "https://gitlab.com/coder/coder/-/jobs/456",
+		},
+		{
+			name: "Jenkins",
+			env: map[string]string{
+				"JENKINS_URL": "https://jenkins.example.com/",
+				"BUILD_URL":   "https://jenkins.example.com/job/coder/7/",
+			},
+			expected: "https://jenkins.example.com/job/coder/7/",
+		},
+		{
+			// BUILD_URL alone is too generic to be attributed to Jenkins.
+			name:     "BuildURLOnly",
+			env:      map[string]string{"BUILD_URL": "https://example.com"},
+			expected: "",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var env serpent.Environ
+			for k, v := range tc.env {
+				env.Set(k, v)
+			}
+			assert.Equal(t, tc.expected, detectCIJobURL(env))
+		})
+	}
+}
+
 // Need this for Windows because of a known issue with Go:
 // https://github.com/golang/go/issues/52986
 func removeTmpDirUntilSuccess(t *testing.T, tempDir string) {
@@ -159,6 +159,7 @@ func (r *RootCmd) ping() *serpent.Command {
 			ni := conn.GetNetInfo()
 			connDiags := cliui.ConnDiags{
 				DisableDirect:      r.disableDirect,
+				HTTPSFallback:      conn.UsingHTTPSFallback(),
 				LocalNetInfo:       ni,
 				Verbose:            r.verbose,
 				PingP2P:            didP2p,
@@ -0,0 +1,336 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
+	"tailscale.com/net/socks5"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) proxy() *serpent.Command {
+	return &serpent.Command{
+		Use:   "proxy",
+		Short: "Run a local proxy that routes traffic through a workspace",
+		Long: "Run a local SOCKS5 or HTTP CONNECT proxy. Connections made through the proxy " +
+			"are opened from inside the workspace, so hostnames and addresses are resolved " +
+			"and reached the same way they would be from a shell in the workspace.",
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.proxySOCKS5(),
+			r.proxyHTTP(),
+		},
+	}
+}
+
+// proxyFlags are shared by every proxy subcommand.
+type proxyFlags struct {
+	workspace        string
+	address          string
+	disableAutostart bool
+}
+
+func (f *proxyFlags) options(defaultAddress string) serpent.OptionSet {
+	return serpent.OptionSet{
+		{
+			Flag:          "workspace",
+			FlagShorthand: "w",
+			Env:           "CODER_PROXY_WORKSPACE",
+			Description:   "The workspace to route traffic through, optionally followed by an agent name (workspace.agent).",
+			Required:      true,
+			Value:         serpent.StringOf(&f.workspace),
+		},
+		{
+			Flag:        "address",
+			Env:         "CODER_PROXY_ADDRESS",
+			Description: "The local address the proxy listens on.",
+			Default:     defaultAddress,
+			Value:       serpent.StringOf(&f.address),
+		},
+		sshDisableAutostartOption(serpent.BoolOf(&f.disableAutostart)),
+	}
+}
+
+func (r *RootCmd) proxySOCKS5() *serpent.Command {
+	var (
+		flags            proxyFlags
+		appearanceConfig codersdk.AppearanceConfig
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:         "socks5",
+		Short:       "Run a local SOCKS5 proxy that routes traffic through a workspace.",
+		Annotations: workspaceCommand,
+		Long: "Use a socks5h:// proxy URL so that hostnames are resolved inside the workspace.\n" + FormatExamples(
+			Example{
+				Description: "Start a proxy for a workspace",
+				Command:     "coder proxy socks5 --workspace my-workspace",
+			},
+			Example{
+				Description: "Reach a service listening on port 8080 inside the workspace",
+				Command:     "curl --proxy socks5h://127.0.0.1:1080 http://localhost:8080",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+			initAppearance(client, &appearanceConfig),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			return r.runWorkspaceProxy(inv, client, flags, appearanceConfig, "socks5", func(ctx context.Context, l net.Listener, dial proxyDialFunc, logger slog.Logger) error {
+				srv := &socks5.Server{
+					Logf: func(format string, args ...any) {
+						logger.Debug(ctx, fmt.Sprintf(format, args...))
+					},
+					Dialer: dial,
+				}
+				return srv.Serve(l)
+			})
+		},
+	}
+	cmd.Options = flags.options("127.0.0.1:1080")
+	return cmd
+}
+
+func (r *RootCmd) proxyHTTP() *serpent.Command {
+	var (
+		flags            proxyFlags
+		appearanceConfig codersdk.AppearanceConfig
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:         "http",
+		Short:       "Run a local HTTP proxy that routes traffic through a workspace.",
+		Annotations: workspaceCommand,
+		Long: "Both CONNECT tunnels and plain HTTP requests with an absolute URL are supported.\n" + FormatExamples(
+			Example{
+				Description: "Start a proxy for a workspace",
+				Command:     "coder proxy http --workspace my-workspace",
+			},
+			Example{
+				Description: "Reach a host on the workspace's network",
+				Command:     "https_proxy=http://127.0.0.1:3128 curl https://internal.example.com",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+			initAppearance(client, &appearanceConfig),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			return r.runWorkspaceProxy(inv, client, flags, appearanceConfig, "http", func(ctx context.Context, l net.Listener, dial proxyDialFunc, logger slog.Logger) error {
+				srv := &http.Server{
+					Handler:           newHTTPProxyHandler(dial, logger),
+					BaseContext:       func(net.Listener) context.Context { return ctx },
+					ReadHeaderTimeout: 30 * time.Second,
+				}
+				return srv.Serve(l)
+			})
+		},
+	}
+	cmd.Options = flags.options("127.0.0.1:3128")
+	return cmd
+}
+
+// proxyDialFunc opens a connection to addr from inside the workspace.
+type proxyDialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// runWorkspaceProxy connects to the workspace agent and hands serve a listener
+// and a dialer that tunnels connections through the agent's SSH server. The
+// agent permits direct-tcpip to any host, so addresses are resolved and dialed
+// from the workspace's network namespace.
+func (r *RootCmd) runWorkspaceProxy(
+	inv *serpent.Invocation,
+	client *codersdk.Client,
+	flags proxyFlags,
+	appearanceConfig codersdk.AppearanceConfig,
+	kind string,
+	serve func(ctx context.Context, l net.Listener, dial proxyDialFunc, logger slog.Logger) error,
+) error {
+	ctx, cancel := inv.SignalNotifyContext(inv.Context(), StopSignals...)
+	defer cancel()
+
+	workspace, workspaceAgent, _, err := getWorkspaceAndAgent(ctx, inv, client, !flags.disableAutostart, flags.workspace)
+	if err != nil {
+		return err
+	}
+	if workspace.LatestBuild.Transition != codersdk.WorkspaceTransitionStart {
+		return xerrors.New("workspace must be in start transition to proxy")
+	}
+	if workspace.LatestBuild.Job.CompletedAt == nil {
+		err = cliui.WorkspaceBuild(ctx, inv.Stderr, client, workspace.LatestBuild.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = cliui.Agent(ctx, inv.Stderr, workspaceAgent.ID, cliui.AgentOptions{
+		Fetch:   client.WorkspaceAgent,
+		Wait:    false,
+		DocsURL: appearanceConfig.DocsURL,
+	})
+	if err != nil {
+		return xerrors.Errorf("await agent: %w", err)
+	}
+
+	opts := &workspacesdk.DialAgentOptions{}
+	logger := inv.Logger
+	if r.verbose {
+		logger = logger.AppendSinks(sloghuman.Sink(inv.Stderr)).Leveled(slog.LevelDebug)
+		opts.Logger = logger
+	}
+	if r.disableDirect {
+		_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
+		opts.BlockEndpoints = true
+	}
+	if !r.disableNetworkTelemetry {
+		opts.EnableTelemetry = true
+	}
+	conn, err := workspacesdk.New(client).DialAgent(ctx, workspaceAgent.ID, opts)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if !conn.AwaitReachable(ctx) {
+		return xerrors.Errorf("await agent reachable: %w", ctx.Err())
+	}
+	sshClient, err := conn.SSHClient(ctx)
+	if err != nil {
+		return xerrors.Errorf("create ssh client: %w", err)
+	}
+	defer sshClient.Close()
+
+	l, err := inv.Net.Listen("tcp", flags.address)
+	if err != nil {
+		return xerrors.Errorf("listen %q: %w", flags.address, err)
+	}
+	defer l.Close()
+
+	stopUpdating := client.UpdateWorkspaceUsageContext(ctx, workspace.ID)
+	defer stopUpdating()
+
+	go func() {
+		<-ctx.Done()
+		_ = l.Close()
+	}()
+
+	_, _ = fmt.Fprintf(inv.Stderr, "Proxying %s://%s through workspace %q\n", kind, l.Addr(), workspace.Name)
+	_, _ = fmt.Fprintln(inv.Stderr, "Ready!")
+	err = serve(ctx, l, sshProxyDialer(sshClient, logger), logger)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+func sshProxyDialer(sshClient *ssh.Client, logger slog.Logger) proxyDialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if network != "tcp" {
+			return nil, xerrors.Errorf("unsupported network %q", network)
+		}
+		logger.Debug(ctx, "proxying connection", slog.F("addr", addr))
+		c, err := sshClient.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, xerrors.Errorf("dial %q in workspace: %w", addr, err)
+		}
+		return c, nil
+	}
+}
+
+// httpProxyHandler is a forward proxy that tunnels CONNECT requests and
+// relays absolute-form HTTP requests.
+type httpProxyHandler struct {
+	dial    proxyDialFunc
+	logger  slog.Logger
+	forward *httputil.ReverseProxy
+}
+
+func newHTTPProxyHandler(dial proxyDialFunc, logger slog.Logger) *httpProxyHandler {
+	return &httpProxyHandler{
+		dial:   dial,
+		logger: logger,
+		forward: &httputil.ReverseProxy{
+			// The outgoing request already carries the absolute URL the
+			// client asked for; Rewrite only strips hop-by-hop headers.
+			Rewrite: func(*httputil.ProxyRequest) {},
+			Transport: &http.Transport{
+				DialContext:     dial,
+				MaxIdleConns:    10,
+				IdleConnTimeout: 90 * time.Second,
+			},
+			ErrorHandler: func(rw http.ResponseWriter, _ *http.Request, err error) {
+				logger.Debug(context.Background(), "proxy request failed", slog.Error(err))
+				http.Error(rw, err.Error(), http.StatusBadGateway)
+			},
+		},
+	}
+}
+
+func (h *httpProxyHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		h.serveConnect(rw, r)
+		return
+	}
+	if !r.URL.IsAbs() || r.URL.Scheme != "http" {
+		http.Error(rw, "only CONNECT and absolute http:// requests are supported", http.StatusBadRequest)
+		return
+	}
+	h.forward.ServeHTTP(rw, r)
+}
+
+func (h *httpProxyHandler) serveConnect(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	remote, err := h.dial(ctx, "tcp", r.Host)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadGateway)
+		return
+	}
+	hijacker, ok := rw.(http.Hijacker)
+	if !ok {
+		_ = remote.Close()
+		http.Error(rw, "connection cannot be hijacked", http.StatusInternalServerError)
+		return
+	}
+	local, buf, err := hijacker.Hijack()
+	if err != nil {
+		_ = remote.Close()
+		h.logger.Debug(ctx, "hijack connection", slog.Error(err))
+		return
+	}
+	_, err = local.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+	if err != nil {
+		_ = local.Close()
+		_ = remote.Close()
+		return
+	}
+	// Bytes the client sent after the CONNECT request may already be
+	// buffered.
+	agentssh.Bicopy(ctx, &bufferedConn{Conn: local, r: buf.Reader}, remote)
+}
+
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
@@ -0,0 +1,105 @@
+package cli_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/proxy"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestProxy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		client, db         = coderdtest.NewWithDatabase(t, nil)
+		admin              = coderdtest.CreateFirstUser(t, client)
+		member, memberUser = coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		workspace          = runAgent(t, client, memberUser.ID, db)
+	)
+
+	// startProxy runs `coder proxy <kind>` on an in-process network and
+	// returns it once the proxy is ready.
+	startProxy := func(ctx context.Context, t *testing.T, kind, address string) *testutil.InProcNet {
+		inv, root := clitest.New(t, "proxy", kind, "--workspace", workspace.Name, "--address", address)
+		clitest.SetupConfig(t, member, root)
+		pty := ptytest.New(t)
+		inv.Stdin = pty.Input()
+		inv.Stdout = pty.Output()
+		inv.Stderr = pty.Output()
+		iNet := testutil.NewInProcNet()
+		inv.Net = iNet
+		errC := make(chan error, 1)
+		go func() {
+			errC <- inv.WithContext(ctx).Run()
+		}()
+		t.Cleanup(func() {
+			_ = testutil.TryReceive(context.Background(), t, errC)
+		})
+		pty.ExpectMatchContext(ctx, "Ready!")
+		return iNet
+	}
+
+	t.Run("SOCKS5", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		remotePort := setupTestListener(t, localTCPListener(t))
+
+		iNet := startProxy(ctx, t, "socks5", "127.0.0.1:1080")
+		dialer, err := proxy.SOCKS5("tcp", "127.0.0.1:1080", nil, inProcDialer{ctx: ctx, net: iNet})
+		require.NoError(t, err)
+		conn, err := dialer.Dial("tcp", "localhost:"+remotePort)
+		require.NoError(t, err)
+		defer conn.Close()
+		testDial(t, conn)
+	})
+
+	t.Run("HTTPConnect", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		remotePort := setupTestListener(t, localTCPListener(t))
+
+		iNet := startProxy(ctx, t, "http", "127.0.0.1:3128")
+		conn, err := iNet.Dial(ctx, testutil.NewAddr("tcp", "127.0.0.1:3128"))
+		require.NoError(t, err)
+		defer conn.Close()
+		target := "127.0.0.1:" + remotePort
+		_, err = fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", target, target)
+		require.NoError(t, err)
+		br := bufio.NewReader(conn)
+		res, err := http.ReadResponse(br, nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Zero(t, br.Buffered())
+		testDial(t, conn)
+	})
+}
+
+// localTCPListener listens on a random local port, standing in for a
+// service running in the workspace.
+func localTCPListener(t *testing.T) net.Listener {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	return l
+}
+
+type inProcDialer struct {
+	ctx context.Context
+	net *testutil.InProcNet
+}
+
+func (d inProcDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.net.Dial(d.ctx, testutil.NewAddr(network, addr))
+}
@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"sync"
+	"syscall"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) rdp() *serpent.Command {
+	var (
+		remotePort       int64
+		localPort        int64
+		username         string
+		noOpen           bool
+		disableAutostart bool
+		appearanceConfig codersdk.AppearanceConfig
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "rdp <workspace>",
+		Short:       "Connect to a workspace with a local Remote Desktop (RDP) client.",
+		Long: "Forwards the RDP port of the workspace to a loopback address and launches the local " +
+			"RDP client against it. The connection stays open until the client exits or the command is interrupted.\n" + FormatExamples(
+			Example{
+				Description: "Open an RDP session to a Windows workspace",
+				Command:     "coder rdp <workspace>",
+			},
+			Example{
+				Description: "Forward RDP on a fixed local port without launching a client",
+				Command:     "coder rdp <workspace> --local-port 3399 --no-open",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+			initAppearance(client, &appearanceConfig),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			if remotePort <= 0 || remotePort > 65535 {
+				return xerrors.Errorf("invalid RDP port %d", remotePort)
+			}
+			if localPort < 0 || localPort > 65535 {
+				return xerrors.Errorf("invalid local port %d", localPort)
+			}
+
+			workspace, workspaceAgent, _, err := getWorkspaceAndAgent(ctx, inv, client, !disableAutostart, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			if workspace.LatestBuild.Transition != codersdk.WorkspaceTransitionStart {
+				return xerrors.New("workspace must be in start transition to connect with RDP")
+			}
+			if workspace.LatestBuild.Job.CompletedAt == nil {
+				err = cliui.WorkspaceBuild(ctx, inv.Stderr, client, workspace.LatestBuild.ID)
+				if err != nil {
+					return err
+				}
+			}
+
+			err = cliui.Agent(ctx, inv.Stderr, workspaceAgent.ID, cliui.AgentOptions{
+				Fetch:   client.WorkspaceAgent,
+				Wait:    false,
+				DocsURL: appearanceConfig.DocsURL,
+			})
+			if err != nil {
+				return xerrors.Errorf("await agent: %w", err)
+			}
+
+			opts := &workspacesdk.DialAgentOptions{}
+
+			logger := inv.Logger
+			if r.verbose {
+				opts.Logger = logger.AppendSinks(sloghuman.Sink(inv.Stdout)).Leveled(slog.LevelDebug)
+			}
+			if r.disableDirect {
+				_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
+				opts.BlockEndpoints = true
+			}
+			if !r.disableNetworkTelemetry {
+				opts.EnableTelemetry = true
+			}
+			conn, err := workspacesdk.New(client).DialAgent(ctx, workspaceAgent.ID, opts)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			// The listener is only ever bound to loopback, so that the
+			// workspace isn't exposed to the local network.
+			wg := new(sync.WaitGroup)
+			l, err := listenAndPortForward(ctx, inv, conn, wg, portForwardSpec{
+				network:    "tcp",
+				listenHost: ipv4Loopback,
+				listenPort: uint16(localPort),
+				dialPort:   uint16(remotePort),
+			}, logger)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = l.Close()
+				wg.Wait()
+			}()
+
+			stopUpdating := client.UpdateWorkspaceUsageContext(ctx, workspace.ID)
+			defer stopUpdating()
+
+			conn.AwaitReachable(ctx)
+			addr := l.Addr().String()
+			_, _ = fmt.Fprintf(inv.Stdout, "RDP is available at %s\n", addr)
+
+			// Inside a workspace there is no local RDP client to launch.
+			if inv.Environ.Get("CODER") == "true" {
+				noOpen = true
+			}
+
+			clientDone := make(chan error, 1)
+			if !noOpen {
+				name, args, waitForExit, err := rdpClientCommand(runtime.GOOS, addr, username)
+				if err != nil {
+					cliui.Warnf(inv.Stderr, "%s\nConnect your RDP client to %s instead.", err, addr)
+				} else {
+					// nolint:gosec // The command is chosen from a fixed set of RDP clients.
+					clientCmd := exec.CommandContext(ctx, name, args...)
+					err = clientCmd.Start()
+					if err != nil {
+						return xerrors.Errorf("start RDP client %q: %w", name, err)
+					}
+					_, _ = fmt.Fprintf(inv.Stderr, "Launched %s\n", name)
+					go func() {
+						err := clientCmd.Wait()
+						if waitForExit {
+							clientDone <- err
+						}
+					}()
+				}
+			}
+			_, _ = fmt.Fprintln(inv.Stderr, "Press Ctrl+C to close the connection.")
+
+			sigs := make(chan os.Signal, 1)
+			signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+			defer signal.Stop(sigs)
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case sig := <-sigs:
+				logger.Debug(ctx, "received signal", slog.F("signal", sig))
+				_, _ = fmt.Fprintln(inv.Stderr, "\nReceived signal, closing the RDP connection")
+				return nil
+			case err := <-clientDone:
+				if err != nil {
+					return xerrors.Errorf("RDP client: %w", err)
+				}
+				return nil
+			}
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "port",
+			Env:         "CODER_RDP_PORT",
+			Description: "The port the RDP server listens on in the workspace.",
+			Default:     fmt.Sprint(codersdk.DefaultRDPPort),
+			Value:       serpent.Int64Of(&remotePort),
+		},
+		{
+			Flag:        "local-port",
+			Env:         "CODER_RDP_LOCAL_PORT",
+			Description: "The loopback port to forward RDP on. A random port is used by default.",
+			Default:     "0",
+			Value:       serpent.Int64Of(&localPort),
+		},
+		{
+			Flag:          "username",
+			FlagShorthand: "u",
+			Env:           "CODER_RDP_USERNAME",
+			Description:   "The username to pass to the RDP client, if it supports it.",
+			Value:         serpent.StringOf(&username),
+		},
+		{
+			Flag:        "no-open",
+			Env:         "CODER_RDP_NO_OPEN",
+			Description: "Only forward the RDP port and print its address, without launching the RDP client.",
+			Value:       serpent.BoolOf(&noOpen),
+		},
+		sshDisableAutostartOption(serpent.BoolOf(&disableAutostart)),
+	}
+
+	return cmd
+}
+
+// rdpClientCommand returns the command that launches the local RDP client for
+// the operating system against addr. waitForExit is true if the command runs
+// for as long as the RDP session, and false if it only hands the session off
+// to another application.
+func rdpClientCommand(goos, addr, username string) (name string, args []string, waitForExit bool, err error) {
+	switch goos {
+	case "windows":
+		// mstsc doesn't accept a username on the command line.
+		return "mstsc", []string{"/v:" + addr}, true, nil
+	case "darwin":
+		// Microsoft's Remote Desktop (Windows App) handles rdp:// URLs.
+		u := "rdp://full%20address=s:" + addr
+		if username != "" {
+			u += "&username=s:" + username
+		}
+		return "open", []string{u}, false, nil
+	default:
+		for _, freerdp := range []string{"xfreerdp3", "xfreerdp", "wlfreerdp"} {
+			if _, err := exec.LookPath(freerdp); err != nil {
+				continue
+			}
+			args := []string{"/v:" + addr}
+			if username != "" {
+				args = append(args, "/u:"+username)
+			}
+			return freerdp, args, true, nil
+		}
+		if _, err := exec.LookPath("remmina"); err == nil {
+			u := "rdp://" + addr
+			if username != "" {
+				u = "rdp://" + username + "@" + addr
+			}
+			return "remmina", []string{"-c", u}, true, nil
+		}
+		return "", nil, false, xerrors.New("no supported RDP client was found, install FreeRDP or Remmina to launch it automatically")
+	}
+}
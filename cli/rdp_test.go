@@ -0,0 +1,58 @@
+package cli_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestRDP(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	admin := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+	workspace := runAgent(t, client, memberUser.ID, db)
+
+	// Emulate the RDP server of the workspace.
+	remoteLis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err, "create TCP listener")
+	remotePort := setupTestListener(t, remoteLis)
+
+	inv, root := clitest.New(t, "rdp", workspace.Name, "--port", remotePort, "--local-port", "3399", "--no-open")
+	clitest.SetupConfig(t, member, root)
+	pty := ptytest.New(t)
+	inv.Stdin = pty.Input()
+	inv.Stdout = pty.Output()
+	inv.Stderr = pty.Output()
+
+	iNet := testutil.NewInProcNet()
+	inv.Net = iNet
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	errC := make(chan error)
+	go func() {
+		errC <- inv.WithContext(ctx).Run()
+	}()
+	pty.ExpectMatchContext(ctx, "RDP is available at 127.0.0.1:3399")
+	pty.ExpectMatchContext(ctx, "Press Ctrl+C")
+
+	dialCtx, dialCancel := context.WithTimeout(ctx, testutil.WaitShort)
+	defer dialCancel()
+	c, err := iNet.Dial(dialCtx, testutil.NewAddr("tcp", "127.0.0.1:3399"))
+	require.NoError(t, err, "open connection to local RDP listener")
+	defer c.Close()
+	testDial(t, c)
+
+	cancel()
+	err = <-errC
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const readinessConditionsHelp = `Conditions are "agent[:<agent>]", "app:[<agent>/]<slug>" or "script:[<agent>/]<display name>", separated by commas.`
+
+// parseReadinessConditions parses comma-separated workspace readiness
+// conditions, e.g. "app:code-server,script:Install dependencies".
+func parseReadinessConditions(specs ...string) ([]codersdk.WorkspaceReadinessCondition, error) {
+	var conditions []codersdk.WorkspaceReadinessCondition
+	for _, spec := range specs {
+		for _, s := range strings.Split(spec, ",") {
+			if strings.TrimSpace(s) == "" {
+				continue
+			}
+			cond, err := codersdk.ParseWorkspaceReadinessCondition(s)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, cond)
+		}
+	}
+	return conditions, nil
+}
+
+// waitForReadiness waits until the readiness conditions of the workspace are
+// satisfied, printing what is still pending while it waits. An error is
+// returned if any condition fails.
+func waitForReadiness(ctx context.Context, w io.Writer, client *codersdk.Client, workspaceID uuid.UUID, conditions []codersdk.WorkspaceReadinessCondition) error {
+	printed := map[string]string{}
+	for {
+		readiness, err := client.WorkspaceReadiness(ctx, workspaceID, codersdk.WorkspaceReadinessRequest{
+			Conditions: conditions,
+		})
+		if err != nil {
+			return xerrors.Errorf("wait for workspace readiness: %w", err)
+		}
+
+		var failed []string
+		for _, state := range readiness.Conditions {
+			switch state.Status {
+			case codersdk.WorkspaceReadinessConditionStatusFailed:
+				failed = append(failed, fmt.Sprintf("%s: %s", state.Condition, state.Message))
+			case codersdk.WorkspaceReadinessConditionStatusPending:
+				if printed[state.Condition] != state.Message {
+					printed[state.Condition] = state.Message
+					_, _ = fmt.Fprintf(w, "Waiting for %s: %s\n", cliui.Keyword(state.Condition), state.Message)
+				}
+			}
+		}
+		if readiness.Failed {
+			return xerrors.Errorf("workspace readiness conditions failed:\n%s", strings.Join(failed, "\n"))
+		}
+		if readiness.Ready {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
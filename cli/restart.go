@@ -54,9 +54,7 @@ func (r *RootCmd) restart() *serpent.Command {
 			wbr := codersdk.CreateWorkspaceBuildRequest{
 				Transition: codersdk.WorkspaceTransitionStop,
 			}
-			if bflags.provisionerLogDebug {
-				wbr.LogLevel = codersdk.ProvisionerLogLevelDebug
-			}
+			bflags.apply(inv, &wbr)
 			build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, wbr)
 			if err != nil {
 				return err
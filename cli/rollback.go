@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) rollback() *serpent.Command {
+	var bflags buildFlags
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "rollback <workspace>",
+		Short:       "Roll back a workspace to its last successful build",
+		Long: "Starts the workspace with the template version and parameter values of its last successful " +
+			"build before the latest start. If the workspace is running, it will be stopped first.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{cliui.SkipPromptOption()},
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			out := inv.Stdout
+
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      "Roll back workspace?",
+				IsConfirm: true,
+			})
+			if err != nil {
+				return err
+			}
+
+			// Stop the workspace first, like "coder update", in case the
+			// template specifies ignore_changes.
+			if workspace.LatestBuild.Transition == codersdk.WorkspaceTransitionStart {
+				build, err := stopWorkspace(inv, client, workspace, bflags)
+				if err != nil {
+					return xerrors.Errorf("stop workspace: %w", err)
+				}
+				if err := cliui.WorkspaceBuild(ctx, out, client, build.ID); err != nil {
+					return xerrors.Errorf("wait for stop: %w", err)
+				}
+			}
+
+			var buildReq codersdk.CreateWorkspaceBuildRequest
+			bflags.apply(inv, &buildReq)
+			req := codersdk.RollbackWorkspaceRequest{
+				LogLevel:      buildReq.LogLevel,
+				ReasonMessage: buildReq.ReasonMessage,
+				CIJobURL:      buildReq.CIJobURL,
+			}
+			build, err := client.RollbackWorkspace(ctx, workspace.ID, req)
+			if err != nil {
+				return xerrors.Errorf("roll back workspace: %w", err)
+			}
+
+			err = cliui.WorkspaceBuild(ctx, out, client, build.ID)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(out,
+				"\nThe %s workspace has been rolled back to template version %s at %s!\n",
+				pretty.Sprint(cliui.DefaultStyles.Keyword, workspace.Name),
+				pretty.Sprint(cliui.DefaultStyles.Keyword, build.TemplateVersionName),
+				cliui.Timestamp(time.Now()),
+			)
+			return nil
+		},
+	}
+
+	cmd.Options = append(cmd.Options, bflags.cliOptions()...)
+
+	return cmd
+}
@@ -97,25 +97,34 @@ func (r *RootCmd) CoreSubcommands() []*serpent.Command {
 		r.notifications(),
 		r.organizations(),
 		r.portForward(),
+		r.proxy(),
 		r.publickey(),
 		r.resetPassword(),
+		r.secrets(),
 		r.state(),
 		r.templates(),
 		r.tokens(),
 		r.users(),
+		r.variableSets(),
+		r.vault(),
 		r.version(defaultVersionInfo),
 
 		// Workspace Commands
+		r.archives(),
 		r.autoupdate(),
+		r.clone(),
 		r.configSSH(),
 		r.create(),
 		r.deleteWorkspace(),
 		r.favorite(),
+		r.labels(),
 		r.list(),
 		r.open(),
 		r.ping(),
+		r.rdp(),
 		r.rename(),
 		r.restart(),
+		r.rollback(),
 		r.schedules(),
 		r.show(),
 		r.speedtest(),
@@ -637,6 +646,7 @@ func (r *RootCmd) HeaderTransport(ctx context.Context, serverURL *url.URL) (*cod
 func (r *RootCmd) configureClient(ctx context.Context, client *codersdk.Client, serverURL *url.URL, inv *serpent.Invocation) error {
 	transport := http.DefaultTransport
 	transport = wrapTransportWithTelemetryHeader(transport, inv)
+	transport = wrapTransportWithBuildVersionHeader(transport, buildinfo.Version())
 	if !r.noVersionCheck {
 		transport = wrapTransportWithVersionMismatchCheck(transport, inv, buildinfo.Version(), func(ctx context.Context) (codersdk.BuildInfoResponse, error) {
 			// Create a new client without any wrapped transport
@@ -1371,6 +1381,15 @@ func wrapTransportWithTelemetryHeader(transport http.RoundTripper, inv *serpent.
 	})
 }
 
+// wrapTransportWithBuildVersionHeader sends the version of the CLI with every
+// request, so deployments can report and enforce the versions of CLIs.
+func wrapTransportWithBuildVersionHeader(transport http.RoundTripper, version string) http.RoundTripper {
+	return roundTripper(func(req *http.Request) (*http.Response, error) {
+		req.Header.Set(codersdk.BuildVersionHeader, version)
+		return transport.RoundTrip(req)
+	})
+}
+
 type roundTripper func(req *http.Request) (*http.Response, error)
 
 func (r roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -50,13 +50,21 @@ When enabling scheduled stop, enter a duration in one of the following formats:
   * The new stop time is calculated from *now*.
   * The new stop time must be at least 30 minutes in the future.
   * The workspace template may restrict the maximum workspace runtime.
+`
+	schedulePauseDescriptionLong = `Pauses the automatic stop, dormancy and deletion of a workspace until a date.
+  * The date is either YYYY-MM-DD, meaning the start of that day in your
+    local timezone, or an RFC 3339 timestamp.
+  * The deployment may limit how long a workspace can be paused for.
+  * Automatic start is not paused.
+  * When the pause ends, the workspace counts as used at that time, so it
+    does not become dormant right away.
 `
 )
 
 func (r *RootCmd) schedules() *serpent.Command {
 	scheduleCmd := &serpent.Command{
 		Annotations: workspaceCommand,
-		Use:         "schedule { show | start | stop | extend } <workspace>",
+		Use:         "schedule { show | start | stop | extend | pause | resume } <workspace>",
 		Short:       "Schedule automated start and stop times for workspaces",
 		Handler: func(inv *serpent.Invocation) error {
 			return inv.Command.HelpHandler(inv)
@@ -66,6 +74,8 @@ func (r *RootCmd) schedules() *serpent.Command {
 			r.scheduleStart(),
 			r.scheduleStop(),
 			r.scheduleExtend(),
+			r.schedulePause(),
+			r.scheduleResume(),
 		},
 	}
 
@@ -137,29 +147,41 @@ func (r *RootCmd) scheduleShow() *serpent.Command {
 }
 
 func (r *RootCmd) scheduleStart() *serpent.Command {
+	var search string
 	client := new(codersdk.Client)
 	cmd := &serpent.Command{
-		Use: "start <workspace-name> { <start-time> [day-of-week] [location] | manual }",
+		Use: "start { <workspace-name> | --search <query> } { <start-time> [day-of-week] [location] | manual }",
 		Long: scheduleStartDescriptionLong + "\n" + FormatExamples(
 			Example{
 				Description: "Set the workspace to start at 9:30am (in Dublin) from Monday to Friday",
 				Command:     "coder schedule start my-workspace 9:30AM Mon-Fri Europe/Dublin",
 			},
+			Example{
+				Description: "Set all workspaces labeled team=platform to start at 9:30am every day",
+				Command:     `coder schedule start --search "label:team=platform" 9:30AM`,
+			},
 		),
 		Short: "Edit workspace start schedule",
 		Middleware: serpent.Chain(
-			serpent.RequireRangeArgs(2, 4),
+			serpent.RequireRangeArgs(1, 4),
 			r.InitClient(client),
 		),
+		Options: serpent.OptionSet{
+			scheduleSearchOption(&search),
+			cliui.SkipPromptOption(),
+		},
 		Handler: func(inv *serpent.Invocation) error {
-			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			workspaces, args, err := scheduleTargets(inv, client, search)
 			if err != nil {
 				return err
 			}
+			if len(args) == 0 || len(args) > 3 {
+				return xerrors.New("expected a start time and optional day-of-week and location, or manual")
+			}
 
 			var schedStr *string
-			if inv.Args[1] != "manual" {
-				sched, err := parseCLISchedule(inv.Args[1:]...)
+			if args[0] != "manual" {
+				sched, err := parseCLISchedule(args...)
 				if err != nil {
 					return err
 				}
@@ -167,18 +189,20 @@ func (r *RootCmd) scheduleStart() *serpent.Command {
 				schedStr = ptr.Ref(sched.String())
 			}
 
-			err = client.UpdateWorkspaceAutostart(inv.Context(), workspace.ID, codersdk.UpdateWorkspaceAutostartRequest{
-				Schedule: schedStr,
-			})
-			if err != nil {
-				return err
-			}
+			for i, workspace := range workspaces {
+				err = client.UpdateWorkspaceAutostart(inv.Context(), workspace.ID, codersdk.UpdateWorkspaceAutostartRequest{
+					Schedule: schedStr,
+				})
+				if err != nil {
+					return xerrors.Errorf("update workspace %s/%s: %w", workspace.OwnerName, workspace.Name, err)
+				}
 
-			updated, err := namedWorkspace(inv.Context(), client, inv.Args[0])
-			if err != nil {
-				return err
+				workspaces[i], err = client.Workspace(inv.Context(), workspace.ID)
+				if err != nil {
+					return err
+				}
 			}
-			return displaySchedule(updated, inv.Stdout)
+			return displaySchedules(workspaces, inv.Stdout)
 		},
 	}
 
@@ -186,49 +210,94 @@ func (r *RootCmd) scheduleStart() *serpent.Command {
 }
 
 func (r *RootCmd) scheduleStop() *serpent.Command {
+	var search string
 	client := new(codersdk.Client)
 	return &serpent.Command{
-		Use: "stop <workspace-name> { <duration> | manual }",
+		Use: "stop { <workspace-name> | --search <query> } { <duration> | manual }",
 		Long: scheduleStopDescriptionLong + "\n" + FormatExamples(
 			Example{
-				Command: "coder schedule stop my-workspace 2h30m",
+				Description: "Stop the workspace 2 hours and 30 minutes after it started",
+				Command:     "coder schedule stop my-workspace 2h30m",
+			},
+			Example{
+				Description: "Stop all workspaces labeled team=platform after 8 hours",
+				Command:     `coder schedule stop --search "label:team=platform" 8h`,
 			},
 		),
 		Short: "Edit workspace stop schedule",
 		Middleware: serpent.Chain(
-			serpent.RequireNArgs(2),
+			serpent.RequireRangeArgs(1, 2),
 			r.InitClient(client),
 		),
+		Options: serpent.OptionSet{
+			scheduleSearchOption(&search),
+			cliui.SkipPromptOption(),
+		},
 		Handler: func(inv *serpent.Invocation) error {
-			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			workspaces, args, err := scheduleTargets(inv, client, search)
 			if err != nil {
 				return err
 			}
+			if len(args) != 1 {
+				return xerrors.New("expected a duration or manual")
+			}
 
 			var durMillis *int64
-			if inv.Args[1] != "manual" {
-				dur, err := parseDuration(inv.Args[1])
+			if args[0] != "manual" {
+				dur, err := parseDuration(args[0])
 				if err != nil {
 					return err
 				}
 				durMillis = ptr.Ref(dur.Milliseconds())
 			}
 
-			if err := client.UpdateWorkspaceTTL(inv.Context(), workspace.ID, codersdk.UpdateWorkspaceTTLRequest{
-				TTLMillis: durMillis,
-			}); err != nil {
-				return err
-			}
+			for i, workspace := range workspaces {
+				if err := client.UpdateWorkspaceTTL(inv.Context(), workspace.ID, codersdk.UpdateWorkspaceTTLRequest{
+					TTLMillis: durMillis,
+				}); err != nil {
+					return xerrors.Errorf("update workspace %s/%s: %w", workspace.OwnerName, workspace.Name, err)
+				}
 
-			updated, err := namedWorkspace(inv.Context(), client, inv.Args[0])
-			if err != nil {
-				return err
+				workspaces[i], err = client.Workspace(inv.Context(), workspace.ID)
+				if err != nil {
+					return err
+				}
 			}
-			return displaySchedule(updated, inv.Stdout)
+			return displaySchedules(workspaces, inv.Stdout)
 		},
 	}
 }
 
+func scheduleSearchOption(search *string) serpent.Option {
+	return serpent.Option{
+		Flag:        "search",
+		Description: "Apply the schedule to every workspace matching the query instead of a named workspace, e.g. \"label:team=platform\".",
+		Value:       serpent.StringOf(search),
+	}
+}
+
+// scheduleTargets returns the workspaces that a schedule command applies to,
+// and the arguments that remain for the schedule. Without a search query, the
+// first argument names the workspace.
+func scheduleTargets(inv *serpent.Invocation, client *codersdk.Client, search string) ([]codersdk.Workspace, []string, error) {
+	if search == "" {
+		if len(inv.Args) < 2 {
+			return nil, nil, xerrors.New("expected a workspace name or --search")
+		}
+		workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		return []codersdk.Workspace{workspace}, inv.Args[1:], nil
+	}
+
+	workspaces, err := searchWorkspaces(inv, client, search, "Update the schedule of")
+	if err != nil {
+		return nil, nil, err
+	}
+	return workspaces, inv.Args, nil
+}
+
 func (r *RootCmd) scheduleExtend() *serpent.Command {
 	client := new(codersdk.Client)
 	extendCmd := &serpent.Command{
@@ -285,8 +354,91 @@ func (r *RootCmd) scheduleExtend() *serpent.Command {
 	return extendCmd
 }
 
+func (r *RootCmd) schedulePause() *serpent.Command {
+	var until string
+	client := new(codersdk.Client)
+	pauseCmd := &serpent.Command{
+		Use:   "pause <workspace-name> --until <date>",
+		Short: "Pause the automatic stop, dormancy and deletion of a workspace until a given date.",
+		Long: schedulePauseDescriptionLong + "\n" + FormatExamples(
+			Example{
+				Command: "coder schedule pause my-workspace --until 2026-11-02",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{
+			{
+				Flag:        "until",
+				Description: "The date the pause ends, as YYYY-MM-DD or an RFC 3339 timestamp.",
+				Value:       serpent.StringOf(&until),
+				Required:    true,
+			},
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			loc, err := tz.TimezoneIANA()
+			if err != nil {
+				loc = time.UTC // best effort
+			}
+			pausedUntil, err := parseDate(until, loc)
+			if err != nil {
+				return err
+			}
+
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			pause, err := client.PutWorkspaceSchedulePause(inv.Context(), workspace.ID, codersdk.PutWorkspaceSchedulePauseRequest{
+				PausedUntil: pausedUntil,
+			})
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "The schedule of workspace %q is paused until %s.\n", workspace.Name, timeDisplay(ptr.NilToEmpty(pause.PausedUntil)))
+			return nil
+		},
+	}
+	return pauseCmd
+}
+
+func (r *RootCmd) scheduleResume() *serpent.Command {
+	client := new(codersdk.Client)
+	resumeCmd := &serpent.Command{
+		Use:   "resume <workspace-name>",
+		Short: "Resume the paused schedule of a workspace.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			if err := client.DeleteWorkspaceSchedulePause(inv.Context(), workspace.ID); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "The schedule of workspace %q is resumed.\n", workspace.Name)
+			return nil
+		},
+	}
+	return resumeCmd
+}
+
 func displaySchedule(ws codersdk.Workspace, out io.Writer) error {
-	rows := []workspaceListRow{workspaceListRowFromWorkspace(time.Now(), ws)}
+	return displaySchedules([]codersdk.Workspace{ws}, out)
+}
+
+func displaySchedules(workspaces []codersdk.Workspace, out io.Writer) error {
+	rows := make([]workspaceListRow, 0, len(workspaces))
+	for _, ws := range workspaces {
+		rows = append(rows, workspaceListRowFromWorkspace(time.Now(), ws))
+	}
 	rendered, err := cliui.DisplayTable(rows, "workspace", []string{
 		"workspace", "starts at", "starts next", "stops after", "stops next",
 	})
@@ -17,6 +17,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/schedule/cron"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/coderd/util/tz"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/pty/ptytest"
@@ -328,6 +329,38 @@ func TestScheduleModify(t *testing.T) {
 		// Then: the updated schedule should be shown
 		pty.ExpectMatch(ws[0].OwnerName + "/" + ws[0].Name)
 	})
+
+	t.Run("SetStopByLabel", func(t *testing.T) {
+		ctx := testutil.Context(t, testutil.WaitShort)
+		for _, w := range ws[2:] {
+			err := ownerClient.UpdateWorkspaceLabels(ctx, w.ID, codersdk.UpdateWorkspaceLabelsRequest{
+				Labels: map[string]string{"team": "platform"},
+			})
+			require.NoError(t, err)
+		}
+
+		// When: we set the stop schedule of the labeled workspaces
+		inv, root := clitest.New(t,
+			"schedule", "stop", "--search", "label:team=platform", "2h", "--yes",
+		)
+		//nolint:gocritic // these workspaces are not owned by the same user
+		clitest.SetupConfig(t, ownerClient, root)
+		pty := ptytest.New(t).Attach(inv)
+		require.NoError(t, inv.Run())
+
+		// Then: the schedule of every labeled workspace should be updated
+		pty.ExpectMatch(ws[2].OwnerName + "/" + ws[2].Name)
+		pty.ExpectMatch(ws[3].OwnerName + "/" + ws[3].Name)
+		for _, w := range ws {
+			updated, err := ownerClient.Workspace(ctx, w.ID)
+			require.NoError(t, err)
+			if w.ID == ws[2].ID || w.ID == ws[3].ID {
+				require.Equal(t, (2 * time.Hour).Milliseconds(), *updated.TTLMillis)
+			} else {
+				require.NotEqual(t, (2 * time.Hour).Milliseconds(), ptr.NilToEmpty(updated.TTLMillis))
+			}
+		}
+	})
 }
 
 //nolint:paralleltest // t.Setenv
@@ -373,3 +406,44 @@ func TestScheduleOverride(t *testing.T) {
 		})
 	}
 }
+
+//nolint:paralleltest // t.Setenv
+func TestSchedulePause(t *testing.T) {
+	// Set timezone to Asia/Kolkata to surface any timezone-related bugs.
+	t.Setenv("TZ", "Asia/Kolkata")
+	loc, err := tz.TimezoneIANA()
+	require.NoError(t, err)
+	sched, err := cron.Weekly("CRON_TZ=Europe/Dublin 30 7 * * Mon-Fri")
+	require.NoError(t, err, "invalid schedule")
+	_, memberClient, _, ws := setupTestSchedule(t, sched)
+	workspaceName := ws[2].OwnerName + "/" + ws[2].Name
+
+	// When: we pause the schedule until a date
+	until := time.Now().In(loc).AddDate(0, 0, 14)
+	inv, root := clitest.New(t,
+		"schedule", "pause", workspaceName, "--until", until.Format(time.DateOnly),
+	)
+	clitest.SetupConfig(t, memberClient, root)
+	pty := ptytest.New(t).Attach(inv)
+	require.NoError(t, inv.Run())
+
+	// Then: the pause ends at the start of that day in the local timezone
+	expected := time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, loc)
+	pty.ExpectMatch("paused until " + expected.Format(time.RFC3339))
+	pause, err := memberClient.WorkspaceSchedulePause(context.Background(), ws[2].ID)
+	require.NoError(t, err)
+	require.NotNil(t, pause.PausedUntil)
+	require.True(t, expected.Equal(*pause.PausedUntil))
+
+	// When: we resume the schedule
+	inv, root = clitest.New(t, "schedule", "resume", workspaceName)
+	clitest.SetupConfig(t, memberClient, root)
+	pty = ptytest.New(t).Attach(inv)
+	require.NoError(t, inv.Run())
+
+	// Then: the schedule is no longer paused
+	pty.ExpectMatch("is resumed")
+	pause, err = memberClient.WorkspaceSchedulePause(context.Background(), ws[2].ID)
+	require.NoError(t, err)
+	require.Nil(t, pause.PausedUntil)
+}
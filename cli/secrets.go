@@ -0,0 +1,437 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) secrets() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "secrets",
+		Short: "Manage user and template secrets",
+		Long: "Secrets are stored encrypted and are never returned by the API. " +
+			"User secrets with an environment variable name can be loaded inside your workspaces, " +
+			"and template secrets are passed to workspace builds as Terraform variables.\n" + FormatExamples(
+			Example{
+				Description: "Create a secret that is exposed as $GITHUB_TOKEN in your workspaces",
+				Command:     "coder secrets create github_token --env GITHUB_TOKEN < token.txt",
+			},
+			Example{
+				Description: "Pass a secret to builds of a template as the Terraform variable \"db_password\"",
+				Command:     "coder secrets create db_password --template my-template",
+			},
+			Example{
+				Description: "Load your secrets into the current shell inside a workspace",
+				Command:     "eval \"$(coder secrets env)\"",
+			},
+		),
+		Aliases: []string{"secret"},
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.secretsCreate(),
+			r.secretsDelete(),
+			r.secretsEnv(),
+			r.secretsList(),
+			r.secretsUpdate(),
+		},
+	}
+	return cmd
+}
+
+type secretListRow struct {
+	Name        string    `json:"name" table:"name,default_sort"`
+	Description string    `json:"description" table:"description"`
+	EnvName     string    `json:"env_name,omitempty" table:"env name"`
+	UpdatedAt   time.Time `json:"updated_at" table:"updated at"`
+}
+
+// secretsTemplateOption returns the option used to target the secrets of a
+// template instead of the current user's secrets.
+func secretsTemplateOption(template *string) serpent.Option {
+	return serpent.Option{
+		Flag:          "template",
+		FlagShorthand: "t",
+		Description:   "Manage the secrets of this template instead of your own.",
+		Value:         serpent.StringOf(template),
+	}
+}
+
+// secretsTemplateID resolves the template selected with --template.
+func secretsTemplateID(inv *serpent.Invocation, client *codersdk.Client, orgContext *OrganizationContext, name string) (uuid.UUID, error) {
+	organization, err := orgContext.Selected(inv, client)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get current organization: %w", err)
+	}
+	template, err := client.TemplateByName(inv.Context(), organization.ID, name)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get template by name: %w", err)
+	}
+	return template.ID, nil
+}
+
+// readSecretValue returns the value passed with --value or, if unset, reads
+// it from stdin. Values are read from stdin by default so they don't end up
+// in shell history.
+func readSecretValue(inv *serpent.Invocation, value string) (string, error) {
+	if value != "" {
+		return value, nil
+	}
+	if isTTYIn(inv) {
+		return cliui.Prompt(inv, cliui.PromptOptions{
+			Text:   "Secret value:",
+			Secret: true,
+		})
+	}
+	data, err := io.ReadAll(inv.Stdin)
+	if err != nil {
+		return "", xerrors.Errorf("read secret value from stdin: %w", err)
+	}
+	value = strings.TrimSuffix(string(data), "\n")
+	if value == "" {
+		return "", xerrors.New("secret value must not be empty")
+	}
+	return value, nil
+}
+
+func (r *RootCmd) secretsList() *serpent.Command {
+	var (
+		template   string
+		orgContext = NewOrganizationContext()
+		formatter  = cliui.NewOutputFormatter(
+			cliui.TableFormat([]secretListRow{}, []string{"name", "description", "env name", "updated at"}),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List secrets",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			var rows []secretListRow
+			if template != "" {
+				templateID, err := secretsTemplateID(inv, client, orgContext, template)
+				if err != nil {
+					return err
+				}
+				secrets, err := client.TemplateSecrets(inv.Context(), templateID)
+				if err != nil {
+					return xerrors.Errorf("list template secrets: %w", err)
+				}
+				for _, secret := range secrets {
+					rows = append(rows, secretListRow{
+						Name:        secret.Name,
+						Description: secret.Description,
+						UpdatedAt:   secret.UpdatedAt,
+					})
+				}
+			} else {
+				secrets, err := client.UserSecrets(inv.Context(), codersdk.Me)
+				if err != nil {
+					return xerrors.Errorf("list secrets: %w", err)
+				}
+				for _, secret := range secrets {
+					rows = append(rows, secretListRow{
+						Name:        secret.Name,
+						Description: secret.Description,
+						EnvName:     secret.EnvName,
+						UpdatedAt:   secret.UpdatedAt,
+					})
+				}
+			}
+
+			if len(rows) == 0 {
+				cliui.Infof(inv.Stderr, "No secrets found.")
+				return nil
+			}
+
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+		Options: serpent.OptionSet{secretsTemplateOption(&template)},
+	}
+	orgContext.AttachOptions(cmd)
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) secretsCreate() *serpent.Command {
+	var (
+		template    string
+		description string
+		envName     string
+		value       string
+		orgContext  = NewOrganizationContext()
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "create <name>",
+		Short: "Create a secret",
+		Long:  "Create a secret. The value is read from stdin unless --value is set.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			name := inv.Args[0]
+			if template != "" && envName != "" {
+				return xerrors.New("--env can only be set on user secrets")
+			}
+			var err error
+			value, err = readSecretValue(inv, value)
+			if err != nil {
+				return err
+			}
+
+			if template != "" {
+				templateID, err := secretsTemplateID(inv, client, orgContext, template)
+				if err != nil {
+					return err
+				}
+				_, err = client.CreateTemplateSecret(inv.Context(), templateID, codersdk.CreateTemplateSecretRequest{
+					Name:        name,
+					Description: description,
+					Value:       value,
+				})
+				if err != nil {
+					return xerrors.Errorf("create template secret: %w", err)
+				}
+				_, _ = fmt.Fprintf(inv.Stdout, "Secret %s has been created on template %s.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, name), pretty.Sprint(cliui.DefaultStyles.Keyword, template))
+				return nil
+			}
+
+			_, err = client.CreateUserSecret(inv.Context(), codersdk.Me, codersdk.CreateUserSecretRequest{
+				Name:        name,
+				Description: description,
+				Value:       value,
+				EnvName:     envName,
+			})
+			if err != nil {
+				return xerrors.Errorf("create secret: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Secret %s has been created.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, name))
+			return nil
+		},
+		Options: serpent.OptionSet{
+			secretsTemplateOption(&template),
+			{
+				Flag:        "description",
+				Description: "A description of the secret.",
+				Value:       serpent.StringOf(&description),
+			},
+			{
+				Flag:        "env",
+				Description: "Expose the secret as this environment variable in your workspaces. Only applies to user secrets.",
+				Value:       serpent.StringOf(&envName),
+			},
+			{
+				Flag:        "value",
+				Description: "The secret value. Read from stdin when not set.",
+				Value:       serpent.StringOf(&value),
+			},
+		},
+	}
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
+
+func (r *RootCmd) secretsUpdate() *serpent.Command {
+	var (
+		template    string
+		description string
+		envName     string
+		value       string
+		orgContext  = NewOrganizationContext()
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "update <name>",
+		Short: "Update a secret",
+		Long:  "Update a secret. Only the flags that are set are changed; pass --value - to read a new value from stdin.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			name := inv.Args[0]
+			flags := inv.ParsedFlags()
+			if template != "" && flags.Changed("env") {
+				return xerrors.New("--env can only be set on user secrets")
+			}
+
+			var descriptionPtr, valuePtr *string
+			if flags.Changed("description") {
+				descriptionPtr = &description
+			}
+			if flags.Changed("value") {
+				if value == "-" {
+					value = ""
+				}
+				var err error
+				value, err = readSecretValue(inv, value)
+				if err != nil {
+					return err
+				}
+				valuePtr = &value
+			}
+
+			if template != "" {
+				templateID, err := secretsTemplateID(inv, client, orgContext, template)
+				if err != nil {
+					return err
+				}
+				_, err = client.UpdateTemplateSecret(inv.Context(), templateID, name, codersdk.UpdateTemplateSecretRequest{
+					Description: descriptionPtr,
+					Value:       valuePtr,
+				})
+				if err != nil {
+					return xerrors.Errorf("update template secret: %w", err)
+				}
+				_, _ = fmt.Fprintf(inv.Stdout, "Secret %s has been updated on template %s.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, name), pretty.Sprint(cliui.DefaultStyles.Keyword, template))
+				return nil
+			}
+
+			req := codersdk.UpdateUserSecretRequest{
+				Description: descriptionPtr,
+				Value:       valuePtr,
+			}
+			if flags.Changed("env") {
+				req.EnvName = &envName
+			}
+			_, err := client.UpdateUserSecret(inv.Context(), codersdk.Me, name, req)
+			if err != nil {
+				return xerrors.Errorf("update secret: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Secret %s has been updated.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, name))
+			return nil
+		},
+		Options: serpent.OptionSet{
+			secretsTemplateOption(&template),
+			{
+				Flag:        "description",
+				Description: "A description of the secret.",
+				Value:       serpent.StringOf(&description),
+			},
+			{
+				Flag:        "env",
+				Description: "Expose the secret as this environment variable in your workspaces. Pass an empty value to stop exposing it. Only applies to user secrets.",
+				Value:       serpent.StringOf(&envName),
+			},
+			{
+				Flag:        "value",
+				Description: "The new secret value. Pass \"-\" to read it from stdin.",
+				Value:       serpent.StringOf(&value),
+			},
+		},
+	}
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
+
+func (r *RootCmd) secretsDelete() *serpent.Command {
+	var (
+		template   string
+		orgContext = NewOrganizationContext()
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "delete <name>",
+		Short: "Delete a secret",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			name := inv.Args[0]
+			if template != "" {
+				templateID, err := secretsTemplateID(inv, client, orgContext, template)
+				if err != nil {
+					return err
+				}
+				err = client.DeleteTemplateSecret(inv.Context(), templateID, name)
+				if err != nil {
+					return xerrors.Errorf("delete template secret: %w", err)
+				}
+				_, _ = fmt.Fprintf(inv.Stdout, "Secret %s has been deleted from template %s.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, name), pretty.Sprint(cliui.DefaultStyles.Keyword, template))
+				return nil
+			}
+
+			err := client.DeleteUserSecret(inv.Context(), codersdk.Me, name)
+			if err != nil {
+				return xerrors.Errorf("delete secret: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Secret %s has been deleted.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, name))
+			return nil
+		},
+		Options: serpent.OptionSet{secretsTemplateOption(&template)},
+	}
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
+
+func (r *RootCmd) secretsEnv() *serpent.Command {
+	return &serpent.Command{
+		Use:   "env",
+		Short: "Print your secrets as shell environment variable exports",
+		Long: "Print the secrets that have an environment variable name as shell exports. " +
+			"This command must be run from inside a workspace, and every secret it prints is recorded in the audit log.\n" + FormatExamples(
+			Example{
+				Description: "Load your secrets into the current shell",
+				Command:     "eval \"$(coder secrets env)\"",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			if r.agentToken == "" {
+				_, _ = fmt.Fprint(inv.Stderr, pretty.Sprintf(headLineStyle(), "No agent token found, this command must be run from inside a running workspace.\n"))
+				return xerrors.Errorf("agent token not found")
+			}
+
+			client, err := r.tryCreateAgentClient()
+			if err != nil {
+				return xerrors.Errorf("create agent client: %w", err)
+			}
+
+			secrets, err := client.Secrets(ctx)
+			if err != nil {
+				return xerrors.Errorf("get secrets: %w", err)
+			}
+			for _, secret := range secrets {
+				_, err = fmt.Fprintf(inv.Stdout, "export %s=%s\n", secret.EnvName, shellQuote(secret.Value))
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// shellQuote wraps s in single quotes so it is interpreted literally by
+// POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,70 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSecrets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("UserSecrets", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "secrets", "create", "github_token", "--env", "GITHUB_TOKEN", "--value", "hunter2")
+		clitest.SetupConfig(t, member, root)
+		require.NoError(t, inv.WithContext(ctx).Run())
+
+		secrets, err := member.UserSecrets(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		require.Equal(t, "GITHUB_TOKEN", secrets[0].EnvName)
+
+		inv, root = clitest.New(t, "secrets", "list")
+		clitest.SetupConfig(t, member, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		require.NoError(t, inv.WithContext(ctx).Run())
+		require.Contains(t, buf.String(), "github_token")
+		require.NotContains(t, buf.String(), "hunter2")
+
+		inv, root = clitest.New(t, "secrets", "delete", "github_token")
+		clitest.SetupConfig(t, member, root)
+		require.NoError(t, inv.WithContext(ctx).Run())
+
+		secrets, err = member.UserSecrets(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Empty(t, secrets)
+	})
+
+	t.Run("TemplateSecrets", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "secrets", "create", "db_password", "--template", template.Name, "--value", "hunter2")
+		//nolint:gocritic // Template secrets require template admin.
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.WithContext(ctx).Run())
+
+		secrets, err := client.TemplateSecrets(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		require.Equal(t, "db_password", secrets[0].Name)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
 	"database/sql"
@@ -21,9 +22,11 @@ import (
 	"net/http/pprof"
 	"net/url"
 	"os"
+	"os/signal"
 	"os/user"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -34,6 +37,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/coreos/go-systemd/daemon"
+	"github.com/crewjam/saml"
 	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
 	"github.com/google/go-github/v43/github"
 	"github.com/google/uuid"
@@ -73,7 +77,9 @@ import (
 	"github.com/coder/coder/v2/cli/cliutil"
 	"github.com/coder/coder/v2/cli/config"
 	"github.com/coder/coder/v2/coderd"
+	"github.com/coder/coder/v2/coderd/alerting"
 	"github.com/coder/coder/v2/coderd/autobuild"
+	"github.com/coder/coder/v2/coderd/configreload"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/awsiamrds"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
@@ -81,23 +87,34 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbpurge"
 	"github.com/coder/coder/v2/coderd/database/migrations"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/deploymentsettings"
 	"github.com/coder/coder/v2/coderd/devtunnel"
+	"github.com/coder/coder/v2/coderd/diagnostics"
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/internalca"
 	"github.com/coder/coder/v2/coderd/jobreaper"
+	"github.com/coder/coder/v2/coderd/networkzone"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/oauthpki"
+	"github.com/coder/coder/v2/coderd/objectstore"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics/insights"
 	"github.com/coder/coder/v2/coderd/promoauth"
+	"github.com/coder/coder/v2/coderd/samlauth"
 	"github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/coderd/telemetry"
+	"github.com/coder/coder/v2/coderd/templatepolicy"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/updatecheck"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	stringutil "github.com/coder/coder/v2/coderd/util/strings"
+	"github.com/coder/coder/v2/coderd/vault"
+	"github.com/coder/coder/v2/coderd/versionpolicy"
+	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
 	"github.com/coder/coder/v2/coderd/workspacestats"
 	"github.com/coder/coder/v2/codersdk"
@@ -207,6 +224,68 @@ func createOIDCConfig(ctx context.Context, logger slog.Logger, vals *codersdk.De
 	}, nil
 }
 
+func createSAMLConfig(ctx context.Context, vals *codersdk.DeploymentValues, httpClient *http.Client) (*coderd.SAMLConfig, error) {
+	var (
+		metadata *saml.EntityDescriptor
+		err      error
+	)
+	switch {
+	case vals.SAML.IDPMetadataURL != "" && vals.SAML.IDPMetadataFile != "":
+		return nil, xerrors.Errorf("cannot specify both saml idp metadata url and saml idp metadata file")
+	case vals.SAML.IDPMetadataURL != "":
+		metadata, err = samlauth.FetchMetadata(ctx, httpClient, vals.SAML.IDPMetadataURL.String())
+		if err != nil {
+			return nil, xerrors.Errorf("fetch saml idp metadata: %w", err)
+		}
+	default:
+		data, err := os.ReadFile(vals.SAML.IDPMetadataFile.String())
+		if err != nil {
+			return nil, xerrors.Errorf("read saml idp metadata file: %w", err)
+		}
+		metadata, err = samlauth.ParseMetadata(data)
+		if err != nil {
+			return nil, xerrors.Errorf("parse saml idp metadata file: %w", err)
+		}
+	}
+
+	opts := samlauth.ServiceProviderOptions{
+		AccessURL:   vals.AccessURL.Value(),
+		EntityID:    vals.SAML.EntityID.String(),
+		IDPMetadata: metadata,
+		HTTPClient:  httpClient,
+	}
+	if vals.SAML.CertFile != "" || vals.SAML.KeyFile != "" {
+		keyPair, err := tls.LoadX509KeyPair(vals.SAML.CertFile.String(), vals.SAML.KeyFile.String())
+		if err != nil {
+			return nil, xerrors.Errorf("load saml certificate and key: %w", err)
+		}
+		key, ok := keyPair.PrivateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, xerrors.Errorf("saml key must be an RSA private key, got %T", keyPair.PrivateKey)
+		}
+		opts.Certificate, err = x509.ParseCertificate(keyPair.Certificate[0])
+		if err != nil {
+			return nil, xerrors.Errorf("parse saml certificate: %w", err)
+		}
+		opts.Key = key
+	}
+	sp, err := samlauth.NewServiceProvider(opts)
+	if err != nil {
+		return nil, xerrors.Errorf("create saml service provider: %w", err)
+	}
+
+	return &coderd.SAMLConfig{
+		ServiceProvider:   sp,
+		EmailDomain:       vals.SAML.EmailDomain,
+		AllowSignups:      vals.SAML.AllowSignups.Value(),
+		UsernameAttribute: vals.SAML.UsernameAttribute.String(),
+		EmailAttribute:    vals.SAML.EmailAttribute.String(),
+		NameAttribute:     vals.SAML.NameAttribute.String(),
+		SignInText:        vals.SAML.SignInText.String(),
+		IconURL:           vals.SAML.IconURL.String(),
+	}, nil
+}
+
 func afterCtx(ctx context.Context, fn func()) {
 	go func() {
 		<-ctx.Done()
@@ -241,6 +320,12 @@ func enablePrometheus(
 	}
 	afterCtx(ctx, closeWorkspacesFunc)
 
+	closeProvisionerJobsFunc, err := prometheusmetrics.ProvisionerJobs(ctx, options.Logger.Named("provisioner_jobs_metrics"), options.PrometheusRegistry, options.Database, 0)
+	if err != nil {
+		return nil, xerrors.Errorf("register provisioner jobs prometheus metric: %w", err)
+	}
+	afterCtx(ctx, closeProvisionerJobsFunc)
+
 	insightsMetricsCollector, err := insights.NewMetricsCollector(options.Database, options.Logger, 0, 0)
 	if err != nil {
 		return nil, xerrors.Errorf("unable to initialize insights metrics collector: %w", err)
@@ -314,6 +399,11 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			ctx, cancel := context.WithCancel(inv.Context())
 			defer cancel()
 
+			// Keep the values as parsed, before values are derived from
+			// them, to tell which options changed when the config file is
+			// reloaded.
+			parsedVals := configreload.CopyValues(vals)
+
 			if vals.Config != "" {
 				cliui.Warnf(inv.Stderr, "YAML support is experimental and offers no compatibility guarantees.")
 			}
@@ -342,6 +432,15 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				!(vals.AccessURL.Scheme == "http" || vals.AccessURL.Scheme == "https") {
 				return xerrors.Errorf("access-url must include a scheme (e.g. 'http://' or 'https://)")
 			}
+			if vals.PathAppAccessURL.String() != "" &&
+				!(vals.PathAppAccessURL.Scheme == "http" || vals.PathAppAccessURL.Scheme == "https") {
+				return xerrors.Errorf("path-app-access-url must include a scheme (e.g. 'http://' or 'https://)")
+			}
+
+			err := ValidateFIPS(vals)
+			if err != nil {
+				return err
+			}
 
 			// Disable rate limits if the `--dangerous-disable-rate-limits` flag
 			// was specified.
@@ -359,6 +458,9 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				return xerrors.Errorf("make logger: %w", err)
 			}
 			defer logCloser()
+			// Keep recent logs in memory for support bundles.
+			logBuffer := diagnostics.NewLogBuffer(diagnostics.DefaultLogBufferSize)
+			logger = logger.AppendSinks(sloghuman.Sink(logBuffer))
 
 			// This line is helpful in tests.
 			logger.Debug(ctx, "started debug logging")
@@ -374,7 +476,12 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			//
 			// To get out of a graceful shutdown, the user can send
 			// SIGQUIT with ctrl+\ or SIGKILL with `kill -9`.
-			stopCtx, stopCancel := signalNotifyContext(ctx, inv, StopSignalsNoInterrupt...)
+			//
+			// Reload signals reload the config file instead.
+			stopSignals := slices.DeleteFunc(slices.Clone(StopSignalsNoInterrupt), func(sig os.Signal) bool {
+				return slices.Contains(ReloadSignals, sig)
+			})
+			stopCtx, stopCancel := signalNotifyContext(ctx, inv, stopSignals...)
 			defer stopCancel()
 			interruptCtx, interruptCancel := signalNotifyContext(ctx, inv, InterruptSignals...)
 			defer interruptCancel()
@@ -568,14 +675,58 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				defaultRegion = nil
 			}
 
-			derpMap, err := tailnet.NewDERPMap(
-				ctx, defaultRegion, vals.DERP.Server.STUNAddresses,
-				vals.DERP.Config.URL.String(), vals.DERP.Config.Path.String(),
-				vals.DERP.Config.BlockDirect.Value(),
-			)
+			if mtu := vals.Wireguard.MTU.Value(); mtu != 0 && (mtu < tailnet.MinMTU || mtu > tailnet.MaxMTU) {
+				return xerrors.Errorf("--wireguard-mtu must be between %d and %d", tailnet.MinMTU, tailnet.MaxMTU)
+			}
+
+			buildDERPMap := func(ctx context.Context) (*tailcfg.DERPMap, error) {
+				derpMap, err := tailnet.NewDERPMap(
+					ctx, defaultRegion, vals.DERP.Server.STUNAddresses,
+					vals.DERP.Config.URL.String(), vals.DERP.Config.Path.String(),
+					vals.DERP.Config.BlockDirect.Value(),
+				)
+				if err != nil {
+					return nil, err
+				}
+				if !vals.DERP.Config.BlockDirect.Value() {
+					err = tailnet.AddRegionSTUNNodes(derpMap, vals.DERP.Config.RegionSTUNAddresses.Value())
+					if err != nil {
+						return nil, xerrors.Errorf("add region STUN addresses: %w", err)
+					}
+				}
+				return derpMap, nil
+			}
+			derpMap, err := buildDERPMap(ctx)
 			if err != nil {
 				return xerrors.Errorf("create derp map: %w", err)
 			}
+			var derpMapFn func() *tailcfg.DERPMap
+			if vals.DERP.Config.URL.String() != "" && vals.DERP.Config.URLRefreshInterval.Value() > 0 {
+				derpMapPoller := tailnet.NewDERPMapPoller(
+					ctx, logger.Named("derpmap"), quartz.NewReal(), derpMap,
+					vals.DERP.Config.URLRefreshInterval.Value(), buildDERPMap,
+				)
+				defer derpMapPoller.Close()
+				derpMapFn = derpMapPoller.DERPMap
+			}
+
+			autostartHolidays, err := schedule.NewAutostartHolidays(
+				logger.Named("autostart_holidays"), quartz.NewReal(),
+				vals.AutostartHolidays.Value(), vals.AutostartHolidayCalendarURL.Value(),
+			)
+			if err != nil {
+				return xerrors.Errorf("parse autostart holidays: %w", err)
+			}
+
+			networkZones, err := networkzone.New(vals.NetworkZones.Value)
+			if err != nil {
+				return xerrors.Errorf("parse network zones: %w", err)
+			}
+
+			versionPolicy, err := versionpolicy.New(vals.MinAgentVersion.Value(), vals.MinCLIVersion.Value())
+			if err != nil {
+				return xerrors.Errorf("parse version policy: %w", err)
+			}
 
 			appHostname := vals.WildcardAccessURL.String()
 			var appHostnameRegex *regexp.Regexp
@@ -628,13 +779,29 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 					vals.WorkspaceHostnameSuffix.String())
 			}
 
+			var pathAppAccessURL *url.URL
+			if vals.PathAppAccessURL.String() != "" {
+				pathAppAccessURL = vals.PathAppAccessURL.Value()
+				if appurl.HostnamesMatch(pathAppAccessURL.Host, vals.AccessURL.Host) {
+					return xerrors.Errorf("path-app-access-url %q must use a different hostname than the access URL", pathAppAccessURL.String())
+				}
+				if vals.DisablePathApps.Value() {
+					cliui.Warnf(inv.Stderr, "--path-app-access-url has no effect because path-based apps are disabled with --disable-path-apps.\n")
+				}
+			} else if vals.PathAppSubdomains.Value() {
+				return xerrors.Errorf("path-app-subdomains requires a path-app-access-url")
+			}
+
 			options := &coderd.Options{
 				AccessURL:                   vals.AccessURL.Value(),
+				PathAppAccessURL:            pathAppAccessURL,
 				AppHostname:                 appHostname,
 				AppHostnameRegex:            appHostnameRegex,
 				Logger:                      logger.Named("coderd"),
+				LogBuffer:                   logBuffer,
 				Database:                    nil,
 				BaseDERPMap:                 derpMap,
+				BaseDERPMapFn:               derpMapFn,
 				Pubsub:                      nil,
 				CacheDir:                    cacheDir,
 				GoogleTokenValidator:        googleTokenValidator,
@@ -657,6 +824,9 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				HTTPClient:                  httpClient,
 				TemplateScheduleStore:       &atomic.Pointer[schedule.TemplateScheduleStore]{},
 				UserQuietHoursScheduleStore: &atomic.Pointer[schedule.UserQuietHoursScheduleStore]{},
+				AutostartHolidays:           autostartHolidays,
+				NetworkZones:                networkZones,
+				VersionPolicy:               &versionPolicy,
 				SSHConfig: codersdk.SSHConfigResponse{
 					HostnamePrefix:   vals.SSHConfig.DeploymentName.String(),
 					SSHConfigOptions: configSSHOptions,
@@ -721,6 +891,23 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				options.OIDCConfig = oc
 			}
 
+			if vals.SAML.IDPMetadataURL != "" || vals.SAML.IDPMetadataFile != "" {
+				sc, err := createSAMLConfig(ctx, vals, httpClient)
+				if err != nil {
+					return xerrors.Errorf("create saml config: %w", err)
+				}
+				options.SAMLConfig = sc
+			}
+
+			if policyFiles := vals.Provisioner.TemplatePolicyFiles.Value(); len(policyFiles) > 0 {
+				engine, err := templatepolicy.Load(ctx, policyFiles)
+				if err != nil {
+					return xerrors.Errorf("load template policies: %w", err)
+				}
+				options.TemplatePolicy = engine
+				logger.Info(ctx, "template policies loaded", slog.F("paths", policyFiles))
+			}
+
 			// We'll read from this channel in the select below that tracks shutdown.  If it remains
 			// nil, that case of the select will just never fire, but it's important not to have a
 			// "bare" read on this channel.
@@ -789,6 +976,21 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				return xerrors.Errorf("set deployment id: %w", err)
 			}
 
+			if vals.SettingsFile.Value() != "" {
+				manifest, err := deploymentsettings.ReadFile(vals.SettingsFile.Value())
+				if err != nil {
+					return xerrors.Errorf("read settings file: %w", err)
+				}
+				applied, err := deploymentsettings.Apply(ctx, options.Database, manifest)
+				if err != nil {
+					return xerrors.Errorf("apply settings file: %w", err)
+				}
+				if len(applied) > 0 {
+					logger.Info(ctx, "applied settings file", slog.F("path", vals.SettingsFile.Value()), slog.F("settings", applied))
+				}
+				options.DeploymentSettings = &manifest
+			}
+
 			// Manage push notifications.
 			experiments := coderd.ReadExperiments(options.Logger, options.DeploymentValues.Experiments.Value())
 			if experiments.Enabled(codersdk.ExperimentWebPush) {
@@ -845,6 +1047,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				URL:              vals.Telemetry.URL.Value(),
 				Tunnel:           tunnel != nil,
 				DeploymentConfig: deploymentConfigWithoutSecrets,
+				Categories:       slice.StringEnums[codersdk.TelemetryCategory](vals.Telemetry.Categories.Value()),
 				ParseLicenseJWT: func(lic *telemetry.License) error {
 					// This will be nil when running in AGPL-only mode.
 					if options.ParseLicenseClaims == nil {
@@ -919,6 +1122,21 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			if err != nil {
 				return xerrors.Errorf("failed to instantiate notification store enqueuer: %w", err)
 			}
+			// Route notifications according to each user's quiet hours schedule. The schedule store is only set once the
+			// API has been created, and may be replaced by the enterprise one.
+			enqueuer.WithQuietHours(func(ctx context.Context, userID uuid.UUID) (*cron.Schedule, error) {
+				store := options.UserQuietHoursScheduleStore.Load()
+				if store == nil {
+					// nolint:nilnil // Users don't have quiet hours until the store is set.
+					return nil, nil
+				}
+				// nolint:gocritic // The enqueuer needs to read the user's quiet hours schedule.
+				opts, err := (*store).Get(dbauthz.AsSystemRestricted(ctx), options.Database, userID)
+				if err != nil {
+					return nil, err
+				}
+				return opts.Schedule, nil
+			})
 			options.NotificationsEnqueuer = enqueuer
 
 			// The notification manager is responsible for:
@@ -936,6 +1154,88 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			notificationReportGenerator := reports.NewReportGenerator(ctx, logger.Named("notifications.report_generator"), options.Database, options.NotificationsEnqueuer, quartz.NewReal())
 			defer notificationReportGenerator.Close()
 
+			// Send deployment health alerts to the configured incident management services.
+			if alertingCfg := vals.Alerting; alertingCfg.Enabled() {
+				hostname, err := os.Hostname()
+				if err != nil {
+					hostname = "unknown"
+				}
+				alerter := alerting.New(ctx, alerting.Options{
+					Logger:   logger.Named("alerting"),
+					Interval: alertingCfg.Interval.Value(),
+					Source:   vals.AccessURL.String(),
+					Senders:  alertingSenders(alertingCfg),
+				})
+				defer alerter.Close()
+				alerter.RegisterCheck("database_latency", alerting.DatabaseLatencyCheck(options.Database, hostname, alertingCfg.DatabaseLatencyThreshold.Value()))
+				alerter.RegisterCheck("provisioner_queue", alerting.ProvisionerQueueCheck(options.Database, alertingCfg.ProvisionerQueueThreshold.Value()))
+				alerter.RegisterCheck("replicas", alerting.ReplicasCheck(options.Database, time.Minute))
+				options.Alerter = alerter
+			}
+
+			if vaultCfg := vals.Vault; vaultCfg.Enabled() {
+				issuer, err := vault.New(vault.Options{
+					Logger:              logger.Named("vault"),
+					Address:             vaultCfg.Address.String(),
+					Namespace:           vaultCfg.Namespace.String(),
+					AuthMethod:          vault.AuthMethod(vaultCfg.AuthMethod),
+					AuthMountPath:       vaultCfg.AuthMountPath.String(),
+					Token:               vaultCfg.Token.String(),
+					AppRoleRoleID:       vaultCfg.AppRoleRoleID.String(),
+					AppRoleSecretID:     vaultCfg.AppRoleSecretID.String(),
+					KubernetesRole:      vaultCfg.KubernetesRole.String(),
+					KubernetesTokenPath: vaultCfg.KubernetesTokenPath.String(),
+					TokenRole:           vaultCfg.TokenRole.String(),
+					Policies:            vaultCfg.TokenPolicies.Value(),
+					TTL:                 vaultCfg.TokenTTL.Value(),
+				})
+				if err != nil {
+					return xerrors.Errorf("configure vault: %w", err)
+				}
+				options.VaultIssuer = issuer
+			}
+
+			if internalCACfg := vals.InternalCA; internalCACfg.Enable.Value() {
+				ca, err := internalca.New(ctx, internalca.Options{
+					Logger:         logger,
+					Database:       options.Database,
+					Registerer:     options.PrometheusRegistry,
+					CertificateTTL: internalCACfg.CertificateTTL.Value(),
+				})
+				if err != nil {
+					return xerrors.Errorf("create internal ca: %w", err)
+				}
+				defer ca.Close()
+				if httpServers.TLSConfig != nil {
+					ca.ConfigureServerTLS(httpServers.TLSConfig)
+				}
+				options.InternalCA = ca
+			} else if internalCACfg.RequireMTLS.Value() {
+				return xerrors.New("--internal-ca-require-mtls requires --internal-ca-enable")
+			}
+			if vals.InternalCA.RequireMTLS.Value() && httpServers.TLSConfig == nil {
+				return xerrors.New("--internal-ca-require-mtls requires TLS to be enabled")
+			}
+
+			if archiveURL := vals.DormantWorkspaceArchiveURL.String(); archiveURL != "" {
+				options.WorkspaceArchiveStore, err = objectstore.NewStore(ctx, archiveURL)
+				if err != nil {
+					return xerrors.Errorf("create dormant workspace archive store: %w", err)
+				}
+			}
+			if fileStorageURL := vals.FileStorageURL.String(); fileStorageURL != "" {
+				options.FileStore, err = objectstore.NewStore(ctx, fileStorageURL)
+				if err != nil {
+					return xerrors.Errorf("create file store: %w", err)
+				}
+			}
+
+			if vals.Config != "" {
+				options.ReloadDeploymentValues = func() (*codersdk.DeploymentValues, error) {
+					return configreload.ReadConfigFile(vals.Config.String(), opts, parsedVals)
+				}
+			}
+
 			// We use a separate coderAPICloser so the Enterprise API
 			// can have its own close functions. This is cleaner
 			// than abstracting the Coder API itself.
@@ -943,6 +1243,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			if err != nil {
 				return xerrors.Errorf("create coder API: %w", err)
 			}
+			go reloadConfigOnSignal(ctx, logger, coderAPI)
 
 			if vals.Prometheus.Enable {
 				// Agent metrics require reference to the tailnet coordinator, so must be initiated after Coder API.
@@ -1043,7 +1344,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			// the request is not to a local IP.
 			var handler http.Handler = coderAPI.RootHandler
 			if vals.RedirectToAccessURL {
-				handler = redirectToAccessURL(handler, vals.AccessURL.Value(), tunnel != nil, appHostnameRegex)
+				handler = redirectToAccessURL(handler, vals.AccessURL.Value(), pathAppAccessURL, vals.PathAppSubdomains.Value(), tunnel != nil, appHostnameRegex)
 			}
 
 			// ReadHeaderTimeout is purposefully not enabled. It caused some
@@ -1102,18 +1403,25 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			defer autobuildTicker.Stop()
 			autobuildExecutor := autobuild.NewExecutor(
 				ctx, options.Database, options.Pubsub, coderAPI.FileCache, options.PrometheusRegistry, coderAPI.TemplateScheduleStore, &coderAPI.Auditor, coderAPI.AccessControlStore, logger, autobuildTicker.C, options.NotificationsEnqueuer, coderAPI.Experiments)
+			if coderAPI.WorkspaceArchiver != nil {
+				autobuildExecutor.WithArchiver(coderAPI.WorkspaceArchiver)
+			}
 			autobuildExecutor.Run()
 
 			jobReaperTicker := time.NewTicker(vals.JobReaperDetectorInterval.Value())
 			defer jobReaperTicker.Stop()
-			jobReaper := jobreaper.New(ctx, options.Database, options.Pubsub, logger, jobReaperTicker.C)
+			jobReaper := jobreaper.New(ctx, options.Database, options.Pubsub, logger, jobReaperTicker.C).
+				WithOrphanedJobs(jobreaper.OrphanedJobOptions{
+					Timeout:     vals.Provisioner.OrphanedJobTimeout.Value(),
+					Requeue:     vals.Provisioner.OrphanedJobAction == codersdk.OrphanedJobActionRequeue,
+					MaxRequeues: vals.Provisioner.OrphanedJobMaxRequeues.Value(),
+				}, options.PrometheusRegistry)
 			jobReaper.Start()
 			defer jobReaper.Close()
 
 			waitForProvisionerJobs := false
-			// Currently there is no way to ask the server to shut
-			// itself down, so any exit signal will result in a non-zero
-			// exit of the server.
+			// Any exit signal, including a restart requested through the
+			// API, will result in a non-zero exit of the server.
 			var exitErr error
 			select {
 			case <-stopCtx.Done():
@@ -1123,13 +1431,17 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			case <-interruptCtx.Done():
 				exitErr = interruptCtx.Err()
 				_, _ = io.WriteString(inv.Stdout, cliui.Bold("Interrupt caught, gracefully exiting. Use ctrl+\\ to force quit\n"))
+			case <-coderAPI.ShutdownRequested():
+				exitErr = errRestartRequested
+				waitForProvisionerJobs = true
+				_, _ = io.WriteString(inv.Stdout, cliui.Bold("Restart requested, waiting for provisioner jobs to complete and gracefully exiting\n"))
 			case <-tunnelDone:
 				exitErr = xerrors.New("dev tunnel closed unexpectedly")
 			case <-pubsubWatchdogTimeout:
 				exitErr = xerrors.New("pubsub Watchdog timed out")
 			case exitErr = <-errCh:
 			}
-			if exitErr != nil && !xerrors.Is(exitErr, context.Canceled) {
+			if exitErr != nil && !xerrors.Is(exitErr, context.Canceled) && !xerrors.Is(exitErr, errRestartRequested) {
 				cliui.Errorf(inv.Stderr, "Unexpected error, shutting down server: %s\n", exitErr)
 			}
 
@@ -1292,6 +1604,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 	}
 
 	createAdminUserCmd := r.newCreateAdminUserCommand()
+	postgresCmd := r.newPostgresCommand()
 	regenerateVapidKeypairCmd := r.newRegenerateVapidKeypairCommand()
 
 	rawURLOpt := serpent.Option{
@@ -1306,7 +1619,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 
 	serverCmd.Children = append(
 		serverCmd.Children,
-		createAdminUserCmd, postgresBuiltinURLCmd, postgresBuiltinServeCmd, regenerateVapidKeypairCmd,
+		createAdminUserCmd, postgresCmd, postgresBuiltinURLCmd, postgresBuiltinServeCmd, regenerateVapidKeypairCmd,
 	)
 
 	return serverCmd
@@ -1322,6 +1635,20 @@ func templateHelpers(options *coderd.Options) map[string]any {
 	}
 }
 
+// alertingSenders returns a sender for every incident management service
+// configured in the deployment.
+func alertingSenders(cfg codersdk.AlertingConfig) []alerting.Sender {
+	client := &http.Client{Timeout: 30 * time.Second}
+	var senders []alerting.Sender
+	if cfg.PagerDutyRoutingKey != "" {
+		senders = append(senders, alerting.NewPagerDuty(client, alerting.PagerDutyEventsURL, cfg.PagerDutyRoutingKey.String()))
+	}
+	if cfg.OpsgenieAPIKey != "" {
+		senders = append(senders, alerting.NewOpsgenie(client, cfg.OpsgenieAPIURL.String(), cfg.OpsgenieAPIKey.String()))
+	}
+	return senders
+}
+
 // writeConfigMW will prevent the main command from running if the write-config
 // flag is set. Instead, it will marshal the command options to YAML and write
 // them to stdout.
@@ -1377,6 +1704,11 @@ func IsLocalURL(ctx context.Context, u *url.URL) (bool, error) {
 	return false, nil
 }
 
+// errRestartRequested is returned when the server shuts down for a restart. The
+// server exits with an error, so that it's started again by supervisors that
+// only restart on failure.
+var errRestartRequested = xerrors.New("restart requested")
+
 func shutdownWithTimeout(shutdown func(context.Context) error, timeout time.Duration) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
@@ -2153,27 +2485,20 @@ func startBuiltinPostgres(ctx context.Context, cfg config.Root, logger slog.Logg
 		return "", nil, xerrors.Errorf("parse postgres port: %w", err)
 	}
 
-	cachePath := filepath.Join(cfg.PostgresPath(), "cache")
-	if customCacheDir != "" {
-		cachePath = filepath.Join(customCacheDir, "postgres")
+	// The binaries must match the version the data directory was created
+	// with, otherwise embedded-postgres wipes it and initializes a new one.
+	major, err := builtinPostgresMajorVersion(filepath.Join(cfg.PostgresPath(), "data"))
+	if err != nil {
+		return "", nil, err
 	}
-	stdlibLogger := slog.Stdlib(ctx, logger.Named("postgres"), slog.LevelDebug)
-	ep := embeddedpostgres.NewDatabase(
-		embeddedpostgres.DefaultConfig().
-			Version(embeddedpostgres.V13).
-			BinariesPath(filepath.Join(cfg.PostgresPath(), "bin")).
-			// Default BinaryRepositoryURL repo1.maven.org is flaky.
-			BinaryRepositoryURL("https://repo.maven.apache.org/maven2").
-			DataPath(filepath.Join(cfg.PostgresPath(), "data")).
-			RuntimePath(filepath.Join(cfg.PostgresPath(), "runtime")).
-			CachePath(cachePath).
-			Username("coder").
-			Password(pgPassword).
-			Database("coder").
-			Encoding("UTF8").
-			Port(uint32(pgPort)).
-			Logger(stdlibLogger.Writer()),
-	)
+	if major == 0 {
+		major = defaultBuiltinPostgresMajorVersion
+	}
+	pgConfig, err := builtinPostgresConfig(ctx, cfg, logger, customCacheDir, major, pgPassword, uint32(pgPort))
+	if err != nil {
+		return "", nil, err
+	}
+	ep := embeddedpostgres.NewDatabase(pgConfig)
 	err = ep.Start()
 	if err != nil {
 		return "", nil, xerrors.Errorf("Failed to start built-in PostgreSQL. Optionally, specify an external deployment with `--postgres-url`: %w", err)
@@ -2181,6 +2506,34 @@ func startBuiltinPostgres(ctx context.Context, cfg config.Root, logger slog.Logg
 	return connectionURL, ep.Stop, nil
 }
 
+// builtinPostgresConfig returns the configuration of the built-in PostgreSQL
+// deployment running the given major version.
+func builtinPostgresConfig(ctx context.Context, cfg config.Root, logger slog.Logger, customCacheDir string, major int, password string, port uint32) (embeddedpostgres.Config, error) {
+	version, ok := builtinPostgresVersions[major]
+	if !ok {
+		return embeddedpostgres.Config{}, xerrors.Errorf("The built-in PostgreSQL data in %q is from PostgreSQL %d, which is not supported. Supported versions are %s.", filepath.Join(cfg.PostgresPath(), "data"), major, supportedBuiltinPostgresVersions())
+	}
+	cachePath := filepath.Join(cfg.PostgresPath(), "cache")
+	if customCacheDir != "" {
+		cachePath = filepath.Join(customCacheDir, "postgres")
+	}
+	stdlibLogger := slog.Stdlib(ctx, logger.Named("postgres"), slog.LevelDebug)
+	return embeddedpostgres.DefaultConfig().
+		Version(version).
+		BinariesPath(builtinPostgresBinariesPath(cfg, major)).
+		// Default BinaryRepositoryURL repo1.maven.org is flaky.
+		BinaryRepositoryURL("https://repo.maven.apache.org/maven2").
+		DataPath(filepath.Join(cfg.PostgresPath(), "data")).
+		RuntimePath(filepath.Join(cfg.PostgresPath(), "runtime")).
+		CachePath(cachePath).
+		Username("coder").
+		Password(password).
+		Database("coder").
+		Encoding("UTF8").
+		Port(port).
+		Logger(stdlibLogger.Writer()), nil
+}
+
 func ConfigureHTTPClient(ctx context.Context, clientCertFile, clientKeyFile string, tlsClientCAFile string) (context.Context, *http.Client, error) {
 	if clientCertFile != "" && clientKeyFile != "" {
 		certificates, err := loadCertificates([]string{clientCertFile}, []string{clientKeyFile})
@@ -2208,7 +2561,7 @@ func ConfigureHTTPClient(ctx context.Context, clientCertFile, clientKeyFile stri
 }
 
 // nolint:revive
-func redirectToAccessURL(handler http.Handler, accessURL *url.URL, tunnel bool, appHostnameRegex *regexp.Regexp) http.Handler {
+func redirectToAccessURL(handler http.Handler, accessURL, pathAppAccessURL *url.URL, pathAppSubdomains, tunnel bool, appHostnameRegex *regexp.Regexp) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		redirect := func() {
 			http.Redirect(w, r, accessURL.String(), http.StatusTemporaryRedirect)
@@ -2256,6 +2609,11 @@ func redirectToAccessURL(handler http.Handler, accessURL *url.URL, tunnel bool,
 			return
 		}
 
+		if pathAppAccessURL != nil && (r.Host == pathAppAccessURL.Host || (pathAppSubdomains && workspaceapps.IsPathAppSubdomain(r.Host, pathAppAccessURL))) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
 		redirect()
 	})
 }
@@ -2557,6 +2915,9 @@ func ConfigureHTTPServers(logger slog.Logger, inv *serpent.Invocation, cfg *code
 		if err != nil {
 			return nil, xerrors.Errorf("configure tls: %w", err)
 		}
+		if cfg.FIPS {
+			tlsConfig.CipherSuites = fipsApprovedCipherSuites(tlsConfig.CipherSuites)
+		}
 		httpsListenerInner, err := net.Listen("tcp", cfg.TLS.Address.String())
 		if err != nil {
 			return nil, err
@@ -2760,6 +3121,41 @@ func escapePostgresURLUserInfo(v string) (string, error) {
 	return v, nil
 }
 
+// reloadConfigOnSignal reloads the deployment config when the server receives
+// one of ReloadSignals.
+func reloadConfigOnSignal(ctx context.Context, logger slog.Logger, api *coderd.API) {
+	if len(ReloadSignals) == 0 {
+		return
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, ReloadSignals...)
+	defer signal.Stop(sigs)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigs:
+		}
+		if api.Options.ReloadDeploymentValues == nil {
+			logger.Warn(ctx, "ignoring reload signal, the server was not started with a config file")
+			continue
+		}
+		vals, err := api.Options.ReloadDeploymentValues()
+		if err != nil {
+			logger.Error(ctx, "read deployment config", slog.Error(err))
+			continue
+		}
+		reload, err := api.ConfigReloader.Reload(ctx, vals)
+		if err != nil {
+			logger.Error(ctx, "reload deployment config", slog.Error(err))
+			continue
+		}
+		logger.Info(ctx, "reloading deployment config",
+			slog.F("reload_id", reload.ID), slog.F("changed_options", len(reload.Options)))
+	}
+}
+
 func signalNotifyContext(ctx context.Context, inv *serpent.Invocation, sig ...os.Signal) (context.Context, context.CancelFunc) {
 	// On Windows, some of our signal functions lack support.
 	// If we pass in no signals, we should just return the context as-is.
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/pflag"
@@ -372,3 +374,31 @@ func TestEscapePostgresURLUserInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestBuiltinPostgresMajorVersion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Uninitialized", func(t *testing.T) {
+		t.Parallel()
+		major, err := builtinPostgresMajorVersion(filepath.Join(t.TempDir(), "data"))
+		require.NoError(t, err)
+		require.Zero(t, major)
+	})
+
+	t.Run("Initialized", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "PG_VERSION"), []byte("16\n"), 0o600))
+		major, err := builtinPostgresMajorVersion(dir)
+		require.NoError(t, err)
+		require.Equal(t, 16, major)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "PG_VERSION"), []byte("sixteen"), 0o600))
+		_, err := builtinPostgresMajorVersion(dir)
+		require.Error(t, err)
+	})
+}
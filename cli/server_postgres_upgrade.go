@@ -0,0 +1,495 @@
+//go:build !slim
+
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"github.com/lib/pq"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/cli/config"
+	"github.com/coder/coder/v2/coderd/database/migrations"
+	"github.com/coder/serpent"
+)
+
+// builtinPostgresVersions are the major versions of PostgreSQL the built-in
+// deployment can run.
+var builtinPostgresVersions = map[int]embeddedpostgres.PostgresVersion{
+	13: embeddedpostgres.V13,
+	14: embeddedpostgres.V14,
+	15: embeddedpostgres.V15,
+	16: embeddedpostgres.V16,
+}
+
+const (
+	// defaultBuiltinPostgresMajorVersion is the version new built-in
+	// deployments are created with.
+	defaultBuiltinPostgresMajorVersion = 13
+	// latestBuiltinPostgresMajorVersion is the version `coder server postgres
+	// upgrade` upgrades to by default.
+	latestBuiltinPostgresMajorVersion = 16
+)
+
+func supportedBuiltinPostgresVersions() string {
+	versions := make([]string, 0, len(builtinPostgresVersions))
+	for major := range builtinPostgresVersions {
+		versions = append(versions, strconv.Itoa(major))
+	}
+	slices.Sort(versions)
+	return strings.Join(versions, ", ")
+}
+
+// builtinPostgresMajorVersion returns the major version of PostgreSQL that
+// created the data directory, or zero if it hasn't been initialized.
+func builtinPostgresMajorVersion(dataPath string) (int, error) {
+	raw, err := os.ReadFile(filepath.Join(dataPath, "PG_VERSION"))
+	if xerrors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, xerrors.Errorf("read built-in PostgreSQL version: %w", err)
+	}
+	major, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return 0, xerrors.Errorf("parse built-in PostgreSQL version %q: %w", raw, err)
+	}
+	return major, nil
+}
+
+// builtinPostgresBinariesPath returns where the binaries of a major version are
+// extracted. PostgreSQL 13 keeps the original location so existing deployments
+// don't download it again.
+func builtinPostgresBinariesPath(cfg config.Root, major int) string {
+	if major == 13 {
+		return filepath.Join(cfg.PostgresPath(), "bin")
+	}
+	return filepath.Join(cfg.PostgresPath(), fmt.Sprintf("bin-%d", major))
+}
+
+func (r *RootCmd) newPostgresCommand() *serpent.Command {
+	return &serpent.Command{
+		Use:   "postgres",
+		Short: "Manage the built-in PostgreSQL deployment.",
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.newPostgresUpgradeCommand(),
+		},
+	}
+}
+
+func (r *RootCmd) newPostgresUpgradeCommand() *serpent.Command {
+	var targetVersion int64
+	cmd := &serpent.Command{
+		Use:   "upgrade",
+		Short: "Upgrade the built-in PostgreSQL deployment to a newer major version.",
+		Long: "Coder must be stopped while the upgrade runs. The data is copied into a new " +
+			"data directory, and the current one is kept as a backup so the upgrade can be " +
+			"rolled back.\n\n" + FormatExamples(
+			Example{
+				Description: "Upgrade the built-in PostgreSQL deployment to the latest supported version",
+				Command:     "coder server postgres upgrade",
+			},
+		),
+		Options: serpent.OptionSet{
+			{
+				Flag:        "target-version",
+				Env:         "CODER_PG_UPGRADE_TARGET_VERSION",
+				Description: "The major version of PostgreSQL to upgrade to.",
+				Default:     strconv.Itoa(latestBuiltinPostgresMajorVersion),
+				Value:       serpent.Int64Of(&targetVersion),
+			},
+			cliui.SkipPromptOption(),
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			ctx, cancel := inv.SignalNotifyContext(inv.Context(), StopSignals...)
+			defer cancel()
+
+			cfg := r.createConfig()
+			logger := inv.Logger.AppendSinks(sloghuman.Sink(inv.Stderr))
+			if r.verbose {
+				logger = logger.Leveled(slog.LevelDebug)
+			}
+
+			dataPath := filepath.Join(cfg.PostgresPath(), "data")
+			current, err := builtinPostgresMajorVersion(dataPath)
+			if err != nil {
+				return err
+			}
+			if current == 0 {
+				return xerrors.Errorf("No built-in PostgreSQL data was found in %q.", dataPath)
+			}
+			target := int(targetVersion)
+			if _, ok := builtinPostgresVersions[target]; !ok {
+				return xerrors.Errorf("PostgreSQL %d is not supported. Supported versions are %s.", target, supportedBuiltinPostgresVersions())
+			}
+			if target == current {
+				cliui.Infof(inv.Stdout, "The built-in PostgreSQL deployment is already running PostgreSQL %d.", current)
+				return nil
+			}
+			if target < current {
+				return xerrors.Errorf("The built-in PostgreSQL deployment is running PostgreSQL %d and cannot be downgraded to %d.", current, target)
+			}
+			err = ensureBuiltinPostgresStopped(cfg)
+			if err != nil {
+				return err
+			}
+
+			newDataPath := filepath.Join(cfg.PostgresPath(), fmt.Sprintf("data-%d-upgrade", target))
+			backupPath := filepath.Join(cfg.PostgresPath(), fmt.Sprintf("data-%d-backup-%s", current, time.Now().Format("20060102150405")))
+
+			cliui.Infof(inv.Stdout, "This will upgrade the built-in PostgreSQL deployment in %q from PostgreSQL %d to %d.", cfg.PostgresPath(), current, target)
+			cliui.Infof(inv.Stdout, "The current data is kept in %q. Make sure there is enough free disk space for a second copy of the database.", backupPath)
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      "Upgrade the built-in PostgreSQL deployment?",
+				IsConfirm: true,
+			})
+			if err != nil {
+				return err
+			}
+
+			// Remove what's left of a previous attempt.
+			err = os.RemoveAll(newDataPath)
+			if err != nil {
+				return xerrors.Errorf("remove %q: %w", newDataPath, err)
+			}
+			err = upgradeBuiltinPostgres(ctx, inv.Stdout, logger, cfg, target, newDataPath)
+			if err != nil {
+				_ = os.RemoveAll(newDataPath)
+				return xerrors.Errorf("Upgrade failed, the built-in PostgreSQL data in %q was not changed: %w", dataPath, err)
+			}
+
+			err = os.Rename(dataPath, backupPath)
+			if err != nil {
+				_ = os.RemoveAll(newDataPath)
+				return xerrors.Errorf("Upgrade failed, move %q to %q: %w", dataPath, backupPath, err)
+			}
+			err = os.Rename(newDataPath, dataPath)
+			if err != nil {
+				if rerr := os.Rename(backupPath, dataPath); rerr != nil {
+					return xerrors.Errorf("Upgrade failed, move %q to %q: %w. Restoring the backup also failed, move %q to %q manually: %s", newDataPath, dataPath, err, backupPath, dataPath, rerr)
+				}
+				_ = os.RemoveAll(newDataPath)
+				return xerrors.Errorf("Upgrade failed, move %q to %q: %w", newDataPath, dataPath, err)
+			}
+
+			cliui.Infof(inv.Stdout, "The built-in PostgreSQL deployment was upgraded to PostgreSQL %d. Start coder server to use it.", target)
+			cliui.Infof(inv.Stdout, "To roll back, stop coder server, remove %q and move %q to %q.", dataPath, backupPath, dataPath)
+			cliui.Infof(inv.Stdout, "Once you've verified the deployment, delete %q to free up disk space.", backupPath)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// ensureBuiltinPostgresStopped returns an error if the built-in PostgreSQL
+// deployment is listening on its port, e.g. because coder server is running.
+func ensureBuiltinPostgresStopped(cfg config.Root) error {
+	port, err := cfg.PostgresPort().Read()
+	if err != nil {
+		// The port is created the first time the deployment starts.
+		return nil
+	}
+	ln, err := net.Listen("tcp", net.JoinHostPort("localhost", port))
+	if err != nil {
+		return xerrors.Errorf("The built-in PostgreSQL deployment appears to be running on port %s. Stop coder server and try again.", port)
+	}
+	_ = ln.Close()
+	return nil
+}
+
+// upgradeBuiltinPostgres initializes a data directory for the target major
+// version and copies the built-in database into it. The current data directory
+// isn't modified.
+//
+// pg_upgrade isn't used because the PostgreSQL binaries embedded-postgres
+// downloads only include initdb, pg_ctl and postgres, so there is no pg_upgrade
+// (or pg_dump) to run. Instead the data is copied table by table. The new
+// database is migrated with the same migrations, so the schemas match, and the
+// copy is verified by comparing row counts. Sequences are copied separately.
+func upgradeBuiltinPostgres(ctx context.Context, w io.Writer, logger slog.Logger, cfg config.Root, target int, newDataPath string) error {
+	cliui.Infof(w, "Starting the built-in PostgreSQL deployment...")
+	srcURL, closeSrc, err := startBuiltinPostgres(ctx, cfg, logger, "")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = closeSrc()
+	}()
+	// The schemas only match if the database is up to date with this version
+	// of Coder.
+	src, err := ConnectToPostgres(ctx, logger, "postgres", srcURL, nil)
+	if err != nil {
+		return xerrors.Errorf("connect to the built-in PostgreSQL deployment, start coder server once to migrate it if this is a newer version of Coder: %w", err)
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+
+	cliui.Infof(w, "Initializing PostgreSQL %d in %q...", target, newDataPath)
+	password, err := cfg.PostgresPassword().Read()
+	if err != nil {
+		return xerrors.Errorf("read postgres password: %w", err)
+	}
+	port, err := freeLocalPort()
+	if err != nil {
+		return err
+	}
+	dstConfig, err := builtinPostgresConfig(ctx, cfg, logger, "", target, password, port)
+	if err != nil {
+		return err
+	}
+	dst := embeddedpostgres.NewDatabase(dstConfig.
+		DataPath(newDataPath).
+		RuntimePath(filepath.Join(cfg.PostgresPath(), "runtime-upgrade")))
+	err = dst.Start()
+	if err != nil {
+		return xerrors.Errorf("start PostgreSQL %d: %w", target, err)
+	}
+	defer func() {
+		_ = dst.Stop()
+	}()
+	dstDB, err := ConnectToPostgres(ctx, logger, "postgres", fmt.Sprintf("postgres://coder@localhost:%d/coder?sslmode=disable&password=%s", port, password), migrations.Up)
+	if err != nil {
+		return xerrors.Errorf("connect to PostgreSQL %d: %w", target, err)
+	}
+	defer func() {
+		_ = dstDB.Close()
+	}()
+
+	cliui.Infof(w, "Copying data...")
+	err = copyPostgresData(ctx, logger, src, dstDB)
+	if err != nil {
+		return err
+	}
+
+	// Both clusters must be shut down cleanly before the data directories are
+	// swapped.
+	_ = dstDB.Close()
+	err = dst.Stop()
+	if err != nil {
+		return xerrors.Errorf("stop PostgreSQL %d: %w", target, err)
+	}
+	_ = src.Close()
+	err = closeSrc()
+	if err != nil {
+		return xerrors.Errorf("stop the built-in PostgreSQL deployment: %w", err)
+	}
+	return nil
+}
+
+func freeLocalPort() (uint32, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, xerrors.Errorf("find a free port: %w", err)
+	}
+	defer ln.Close()
+	tcpAddr, ok := ln.Addr().(*net.TCPAddr)
+	if !ok {
+		return 0, xerrors.Errorf("unexpected address type %T", ln.Addr())
+	}
+	return uint32(tcpAddr.Port), nil //nolint:gosec // Ports fit in a uint32.
+}
+
+// copyPostgresData replaces the tables and sequences in the public schema of
+// dst with the ones in src. Both databases must have the same schema.
+func copyPostgresData(ctx context.Context, logger slog.Logger, src, dst *sql.DB) error {
+	tables, err := postgresTables(ctx, src)
+	if err != nil {
+		return err
+	}
+
+	tx, err := dst.BeginTx(ctx, nil)
+	if err != nil {
+		return xerrors.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	// Triggers and foreign keys are skipped, the rows are copied as they are.
+	_, err = tx.ExecContext(ctx, "SET LOCAL session_replication_role = replica")
+	if err != nil {
+		return xerrors.Errorf("disable triggers: %w", err)
+	}
+	// Migrations insert rows of their own, which are replaced by the copy.
+	quoted := make([]string, 0, len(tables))
+	for _, table := range tables {
+		quoted = append(quoted, pq.QuoteIdentifier(table))
+	}
+	_, err = tx.ExecContext(ctx, "TRUNCATE "+strings.Join(quoted, ", ")+" RESTART IDENTITY CASCADE")
+	if err != nil {
+		return xerrors.Errorf("truncate tables: %w", err)
+	}
+	counts := make(map[string]int64, len(tables))
+	for _, table := range tables {
+		count, err := copyPostgresTable(ctx, src, tx, table)
+		if err != nil {
+			return xerrors.Errorf("copy table %q: %w", table, err)
+		}
+		counts[table] = count
+		logger.Debug(ctx, "copied table", slog.F("table", table), slog.F("rows", count))
+	}
+	err = copyPostgresSequences(ctx, src, tx)
+	if err != nil {
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return xerrors.Errorf("commit: %w", err)
+	}
+
+	for _, table := range tables {
+		var count int64
+		err = dst.QueryRowContext(ctx, "SELECT count(*) FROM "+pq.QuoteIdentifier(table)).Scan(&count)
+		if err != nil {
+			return xerrors.Errorf("count rows of %q: %w", table, err)
+		}
+		if count != counts[table] {
+			return xerrors.Errorf("table %q has %d rows after the copy, expected %d", table, count, counts[table])
+		}
+	}
+	// Statistics aren't copied, collect them so queries are planned well.
+	_, err = dst.ExecContext(ctx, "ANALYZE")
+	if err != nil {
+		return xerrors.Errorf("analyze: %w", err)
+	}
+	return nil
+}
+
+func postgresTables(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT c.relname FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relkind = 'r'
+		ORDER BY c.relname`)
+	if err != nil {
+		return nil, xerrors.Errorf("list tables: %w", err)
+	}
+	defer rows.Close()
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, xerrors.Errorf("scan table: %w", err)
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+// copyPostgresTable copies the rows of a table and returns how many were
+// copied. Values are transferred as text, which PostgreSQL converts back to
+// the column type.
+func copyPostgresTable(ctx context.Context, src *sql.DB, tx *sql.Tx, table string) (int64, error) {
+	// Generated columns are computed by the destination.
+	colRows, err := src.QueryContext(ctx, `
+		SELECT attname FROM pg_attribute
+		WHERE attrelid = $1::regclass AND attnum > 0 AND NOT attisdropped AND attgenerated = ''
+		ORDER BY attnum`, pq.QuoteIdentifier(table))
+	if err != nil {
+		return 0, xerrors.Errorf("list columns: %w", err)
+	}
+	var columns []string
+	for colRows.Next() {
+		var column string
+		if err := colRows.Scan(&column); err != nil {
+			_ = colRows.Close()
+			return 0, xerrors.Errorf("scan column: %w", err)
+		}
+		columns = append(columns, column)
+	}
+	_ = colRows.Close()
+	if err := colRows.Err(); err != nil {
+		return 0, xerrors.Errorf("list columns: %w", err)
+	}
+
+	selects := make([]string, 0, len(columns))
+	for _, column := range columns {
+		selects = append(selects, pq.QuoteIdentifier(column)+"::text")
+	}
+	rows, err := src.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s", strings.Join(selects, ", "), pq.QuoteIdentifier(table)))
+	if err != nil {
+		return 0, xerrors.Errorf("select rows: %w", err)
+	}
+	defer rows.Close()
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(table, columns...))
+	if err != nil {
+		return 0, xerrors.Errorf("prepare copy: %w", err)
+	}
+	defer stmt.Close()
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	var count int64
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return 0, xerrors.Errorf("scan row: %w", err)
+		}
+		args := make([]any, len(values))
+		for i, value := range values {
+			if value.Valid {
+				args[i] = value.String
+			}
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return 0, xerrors.Errorf("copy row: %w", err)
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, xerrors.Errorf("select rows: %w", err)
+	}
+	// Flush the copy.
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return 0, xerrors.Errorf("copy rows: %w", err)
+	}
+	return count, nil
+}
+
+func copyPostgresSequences(ctx context.Context, src *sql.DB, tx *sql.Tx) error {
+	rows, err := src.QueryContext(ctx, `SELECT sequencename, last_value FROM pg_sequences WHERE schemaname = 'public'`)
+	if err != nil {
+		return xerrors.Errorf("list sequences: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			name      string
+			lastValue sql.NullInt64
+		)
+		if err := rows.Scan(&name, &lastValue); err != nil {
+			return xerrors.Errorf("scan sequence: %w", err)
+		}
+		if lastValue.Valid {
+			_, err = tx.ExecContext(ctx, "SELECT setval($1::regclass, $2, true)", pq.QuoteIdentifier(name), lastValue.Int64)
+		} else {
+			// The sequence hasn't been used yet.
+			_, err = tx.ExecContext(ctx, "ALTER SEQUENCE "+pq.QuoteIdentifier(name)+" RESTART")
+		}
+		if err != nil {
+			return xerrors.Errorf("restore sequence %q: %w", name, err)
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,113 @@
+//go:build !slim
+
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestCopyPostgresData(t *testing.T) {
+	t.Parallel()
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("this test requires postgres")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	logger := testutil.Logger(t)
+
+	// Both databases are migrated, like the built-in database and the one the
+	// upgrade initializes.
+	src := openMigratedDB(t)
+	dst := openMigratedDB(t)
+
+	store := database.New(src)
+	org := dbgen.Organization(t, store, database.Organization{})
+	for range 3 {
+		user := dbgen.User(t, store, database.User{})
+		dbgen.OrganizationMember(t, store, database.OrganizationMember{
+			OrganizationID: org.ID,
+			UserID:         user.ID,
+		})
+	}
+	// Region IDs come from a sequence.
+	for range 2 {
+		_, _ = dbgen.WorkspaceProxy(t, store, database.WorkspaceProxy{})
+	}
+	// Sequences are copied even when the rows that used them are gone.
+	_, err := src.ExecContext(ctx, "SELECT nextval('licenses_id_seq')")
+	require.NoError(t, err)
+	_, err = src.ExecContext(ctx, "SELECT nextval('licenses_id_seq')")
+	require.NoError(t, err)
+
+	err = copyPostgresData(ctx, logger, src, dst)
+	require.NoError(t, err)
+
+	wantCounts := postgresRowCounts(ctx, t, src)
+	require.Equal(t, wantCounts, postgresRowCounts(ctx, t, dst))
+	require.EqualValues(t, 3, wantCounts["users"])
+	require.EqualValues(t, 2, wantCounts["workspace_proxies"])
+
+	wantSequences := postgresSequenceValues(ctx, t, src)
+	require.Equal(t, wantSequences, postgresSequenceValues(ctx, t, dst))
+	require.EqualValues(t, 2, wantSequences["licenses_id_seq"])
+
+	// New rows continue where the original database left off.
+	var srcNext, dstNext int64
+	require.NoError(t, src.QueryRowContext(ctx, "SELECT nextval('workspace_proxies_region_id_seq')").Scan(&srcNext))
+	require.NoError(t, dst.QueryRowContext(ctx, "SELECT nextval('workspace_proxies_region_id_seq')").Scan(&dstNext))
+	require.Equal(t, srcNext, dstNext)
+}
+
+func openMigratedDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dbURL, err := dbtestutil.Open(t)
+	require.NoError(t, err)
+	db, err := sql.Open("postgres", dbURL)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	return db
+}
+
+func postgresRowCounts(ctx context.Context, t *testing.T, db *sql.DB) map[string]int64 {
+	t.Helper()
+	tables, err := postgresTables(ctx, db)
+	require.NoError(t, err)
+	counts := make(map[string]int64, len(tables))
+	for _, table := range tables {
+		var count int64
+		err := db.QueryRowContext(ctx, "SELECT count(*) FROM "+pq.QuoteIdentifier(table)).Scan(&count)
+		require.NoError(t, err)
+		counts[table] = count
+	}
+	return counts
+}
+
+func postgresSequenceValues(ctx context.Context, t *testing.T, db *sql.DB) map[string]int64 {
+	t.Helper()
+	rows, err := db.QueryContext(ctx, "SELECT sequencename, coalesce(last_value, 0) FROM pg_sequences WHERE schemaname = 'public'")
+	require.NoError(t, err)
+	defer rows.Close()
+	values := map[string]int64{}
+	for rows.Next() {
+		var (
+			name  string
+			value int64
+		)
+		require.NoError(t, rows.Scan(&name, &value))
+		values[name] = value
+	}
+	require.NoError(t, rows.Err())
+	return values
+}
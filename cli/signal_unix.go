@@ -27,3 +27,9 @@ var StopSignalsNoInterrupt = []os.Signal{
 var InterruptSignals = []os.Signal{
 	os.Interrupt,
 }
+
+// ReloadSignals is the list of signals that make the server reload its
+// configuration.
+var ReloadSignals = []os.Signal{
+	syscall.SIGHUP,
+}
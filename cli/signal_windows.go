@@ -15,3 +15,5 @@ var StopSignalsNoInterrupt = []os.Signal{}
 var InterruptSignals = []os.Signal{
 	os.Interrupt,
 }
+
+var ReloadSignals = []os.Signal{}
@@ -238,7 +238,10 @@ func (r *RootCmd) ssh() *serpent.Command {
 			}
 
 			// Select the startup script behavior based on template configuration or flags.
-			var wait bool
+			var (
+				wait                bool
+				readinessConditions []codersdk.WorkspaceReadinessCondition
+			)
 			switch waitEnum {
 			case "yes":
 				wait = true
@@ -252,7 +255,12 @@ func (r *RootCmd) ssh() *serpent.Command {
 					}
 				}
 			default:
-				return xerrors.Errorf("unknown wait value %q", waitEnum)
+				// Anything else is a list of readiness conditions to
+				// wait for once the agent is connected.
+				readinessConditions, err = parseReadinessConditions(waitEnum)
+				if err != nil {
+					return xerrors.Errorf("unknown wait value %q: %w", waitEnum, err)
+				}
 			}
 			// The `--no-wait` flag is deprecated, but for now, check it.
 			if noWait {
@@ -296,6 +304,15 @@ func (r *RootCmd) ssh() *serpent.Command {
 				}
 				return err
 			}
+			if len(readinessConditions) > 0 {
+				err = waitForReadiness(ctx, inv.Stderr, client, workspace.ID, readinessConditions)
+				if err != nil {
+					if xerrors.Is(err, context.Canceled) {
+						return cliui.ErrCanceled
+					}
+					return err
+				}
+			}
 
 			// If we're in stdio mode, check to see if we can use Coder Connect.
 			// We don't support Coder Connect over non-stdio coder ssh yet.
@@ -612,9 +629,9 @@ func (r *RootCmd) ssh() *serpent.Command {
 	waitOption := serpent.Option{
 		Flag:        "wait",
 		Env:         "CODER_SSH_WAIT",
-		Description: "Specifies whether or not to wait for the startup script to finish executing. Auto means that the agent startup script behavior configured in the workspace template is used.",
+		Description: "Specifies whether or not to wait for the startup script to finish executing. Auto means that the agent startup script behavior configured in the workspace template is used. Readiness conditions can be given instead to wait for specific agents, apps or scripts, e.g. app:code-server. " + readinessConditionsHelp,
 		Default:     "auto",
-		Value:       serpent.EnumOf(&waitEnum, "yes", "no", "auto"),
+		Value:       serpent.StringOf(&waitEnum),
 	}
 	cmd.Options = serpent.OptionSet{
 		{
@@ -869,6 +886,16 @@ func getWorkspaceAndAgent(ctx context.Context, inv *serpent.Invocation, client *
 				)
 		}
 
+		// The template and the user can disable starting workspaces on connect.
+		err = client.CheckStartOnConnect(ctx, workspace)
+		if errors.Is(err, codersdk.ErrStartOnConnectDisabled) {
+			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, nil,
+				xerrors.Errorf("workspace %q is stopped and was not started: %w. Start it with \"coder start %s/%s\"", workspace.Name, err, workspace.OwnerName, workspace.Name)
+		}
+		if err != nil {
+			return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, nil, xerrors.Errorf("check start on connect: %w", err)
+		}
+
 		// Start workspace based on the last build parameters.
 		// It's possible for a workspace build to fail due to the template requiring starting
 		// workspaces with the active version.
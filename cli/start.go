@@ -166,9 +166,7 @@ func buildWorkspaceStartRequest(inv *serpent.Invocation, client *codersdk.Client
 		RichParameterValues: buildParameters,
 		TemplateVersionID:   version,
 	}
-	if buildFlags.provisionerLogDebug {
-		wbr.LogLevel = codersdk.ProvisionerLogLevelDebug
-	}
+	buildFlags.apply(inv, &wbr)
 
 	return wbr, nil
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"time"
 
+	"golang.org/x/xerrors"
+
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/cli/cliutil"
 	"github.com/coder/coder/v2/codersdk"
@@ -11,49 +13,79 @@ import (
 )
 
 func (r *RootCmd) stop() *serpent.Command {
-	var bflags buildFlags
+	var (
+		bflags buildFlags
+		search string
+	)
 	client := new(codersdk.Client)
 	cmd := &serpent.Command{
 		Annotations: workspaceCommand,
-		Use:         "stop <workspace>",
+		Use:         "stop { <workspace> | --search <query> }",
 		Short:       "Stop a workspace",
+		Long: FormatExamples(
+			Example{
+				Description: "Stop all running workspaces labeled team=platform",
+				Command:     `coder stop --search "label:team=platform status:running"`,
+			},
+		),
 		Middleware: serpent.Chain(
-			serpent.RequireNArgs(1),
+			serpent.RequireRangeArgs(0, 1),
 			r.InitClient(client),
 		),
 		Options: serpent.OptionSet{
 			cliui.SkipPromptOption(),
+			{
+				Flag:        "search",
+				Description: "Stop every workspace matching the query instead of a named workspace, e.g. \"label:team=platform\".",
+				Value:       serpent.StringOf(&search),
+			},
 		},
 		Handler: func(inv *serpent.Invocation) error {
-			_, err := cliui.Prompt(inv, cliui.PromptOptions{
-				Text:      "Confirm stop workspace?",
-				IsConfirm: true,
-			})
-			if err != nil {
-				return err
+			if (search == "") == (len(inv.Args) == 0) {
+				return xerrors.New("expected either a workspace name or --search")
 			}
 
-			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
-			if err != nil {
-				return err
-			}
+			var workspaces []codersdk.Workspace
+			if search != "" {
+				var err error
+				workspaces, err = searchWorkspaces(inv, client, search, "Confirm stop")
+				if err != nil {
+					return err
+				}
+			} else {
+				_, err := cliui.Prompt(inv, cliui.PromptOptions{
+					Text:      "Confirm stop workspace?",
+					IsConfirm: true,
+				})
+				if err != nil {
+					return err
+				}
 
-			build, err := stopWorkspace(inv, client, workspace, bflags)
-			if err != nil {
-				return err
+				workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+				if err != nil {
+					return err
+				}
+				workspaces = append(workspaces, workspace)
 			}
 
-			err = cliui.WorkspaceBuild(inv.Context(), inv.Stdout, client, build.ID)
-			if err != nil {
-				return err
-			}
+			for _, workspace := range workspaces {
+				build, err := stopWorkspace(inv, client, workspace, bflags)
+				if err != nil {
+					return xerrors.Errorf("stop workspace %s/%s: %w", workspace.OwnerName, workspace.Name, err)
+				}
 
-			_, _ = fmt.Fprintf(
-				inv.Stdout,
-				"\nThe %s workspace has been stopped at %s!\n",
-				cliui.Keyword(workspace.Name),
-				cliui.Timestamp(time.Now()),
-			)
+				err = cliui.WorkspaceBuild(inv.Context(), inv.Stdout, client, build.ID)
+				if err != nil {
+					return err
+				}
+
+				_, _ = fmt.Fprintf(
+					inv.Stdout,
+					"\nThe %s workspace has been stopped at %s!\n",
+					cliui.Keyword(workspace.Name),
+					cliui.Timestamp(time.Now()),
+				)
+			}
 			return nil
 		},
 	}
@@ -80,8 +112,6 @@ func stopWorkspace(inv *serpent.Invocation, client *codersdk.Client, workspace c
 	wbr := codersdk.CreateWorkspaceBuildRequest{
 		Transition: codersdk.WorkspaceTransitionStop,
 	}
-	if bflags.provisionerLogDebug {
-		wbr.LogLevel = codersdk.ProvisionerLogLevelDebug
-	}
+	bflags.apply(inv, &wbr)
 	return client.CreateWorkspaceBuild(inv.Context(), workspace.ID, wbr)
 }
@@ -23,6 +23,7 @@ import (
 	"cdr.dev/slog/sloggers/sloghuman"
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/healthsdk"
 	"github.com/coder/coder/v2/support"
 	"github.com/coder/serpent"
 )
@@ -52,6 +53,7 @@ var supportBundleBlurb = cliui.Bold("This will collect the following information
   - Agent network diagnostics
   - Agent logs
   - License status
+  - Replica profiles, recent logs, and network state (with --include-profiles)
 ` + cliui.Bold("Note: ") +
 	cliui.Wrap("While we try to sanitize sensitive data from support bundles, we cannot guarantee that they do not contain information that you or your organization may consider sensitive.\n") +
 	cliui.Bold("Please confirm that you will:\n") +
@@ -62,6 +64,7 @@ var supportBundleBlurb = cliui.Bold("This will collect the following information
 func (r *RootCmd) supportBundle() *serpent.Command {
 	var outputPath string
 	var coderURLOverride string
+	var includeProfiles bool
 	client := new(codersdk.Client)
 	cmd := &serpent.Command{
 		Use:   "bundle <workspace> [<agent>]",
@@ -100,6 +103,7 @@ func (r *RootCmd) supportBundle() *serpent.Command {
 				slog.F("slim", vi.Slim),
 				slog.F("agpl", vi.AGPL),
 				slog.F("boring_crypto", vi.BoringCrypto),
+				slog.F("fips", vi.FIPS),
 			)
 			cliLog.Debug(inv.Context(), "invocation", slog.F("args", strings.Join(os.Args, " ")))
 
@@ -178,9 +182,10 @@ func (r *RootCmd) supportBundle() *serpent.Command {
 			deps := support.Deps{
 				Client: client,
 				// Support adds a sink so we don't need to supply one ourselves.
-				Log:         clientLog,
-				WorkspaceID: wsID,
-				AgentID:     agtID,
+				Log:             clientLog,
+				WorkspaceID:     wsID,
+				AgentID:         agtID,
+				IncludeProfiles: includeProfiles,
 			}
 
 			bun, err := support.Run(inv.Context(), &deps)
@@ -216,6 +221,12 @@ func (r *RootCmd) supportBundle() *serpent.Command {
 			Description: "Override the URL to your Coder deployment. This may be useful, for example, if you need to troubleshoot a specific Coder replica.",
 			Value:       serpent.StringOf(&coderURLOverride),
 		},
+		{
+			Flag:        "include-profiles",
+			Env:         "CODER_SUPPORT_BUNDLE_INCLUDE_PROFILES",
+			Description: "Capture goroutine, heap, and CPU profiles, recent logs, health reports, and tailnet debug state from every replica. Requires owner permissions and takes at least 10 seconds per replica.",
+			Value:       serpent.BoolOf(&includeProfiles),
+		},
 	}
 
 	return cmd
@@ -334,12 +345,62 @@ func writeBundle(src *support.Bundle, dest *zip.Writer) error {
 			return xerrors.Errorf("write file %q in archive: %w", k, err)
 		}
 	}
+	for _, replica := range src.Replicas {
+		if err := writeReplica(replica, dest); err != nil {
+			return xerrors.Errorf("write replica %s: %w", replica.Replica.ID, err)
+		}
+	}
 	if err := dest.Close(); err != nil {
 		return xerrors.Errorf("close zip file: %w", err)
 	}
 	return nil
 }
 
+// writeReplica writes the information captured from a replica to its own
+// directory, since hostnames of replicas are not necessarily unique.
+func writeReplica(src support.Replica, dest *zip.Writer) error {
+	dir := "replicas/" + src.Replica.ID.String() + "/"
+	files := map[string][]byte{
+		"coordinator_debug.html": []byte(src.CoordinatorDebug),
+		"tailnet_debug.html":     []byte(src.TailnetDebug),
+	}
+	// Profiles and logs are written to their own files instead.
+	var diagnostics *healthsdk.ReplicaDiagnostics
+	if src.Diagnostics != nil {
+		d := *src.Diagnostics
+		d.Profiles = nil
+		d.Logs = ""
+		diagnostics = &d
+	}
+	for k, v := range map[string]any{
+		"replica.json":     src.Replica,
+		"health.json":      src.HealthReport,
+		"diagnostics.json": diagnostics,
+	} {
+		bs, err := json.MarshalIndent(v, "", "    ")
+		if err != nil {
+			return xerrors.Errorf("encode %q: %w", k, err)
+		}
+		files[k] = bs
+	}
+	if src.Diagnostics != nil {
+		files["logs.txt"] = []byte(src.Diagnostics.Logs)
+		for name, profile := range src.Diagnostics.Profiles {
+			files[name+".pprof"] = profile
+		}
+	}
+	for k, v := range files {
+		f, err := dest.Create(dir + k)
+		if err != nil {
+			return xerrors.Errorf("create file %q in archive: %w", dir+k, err)
+		}
+		if _, err := f.Write(v); err != nil {
+			return xerrors.Errorf("write file %q in archive: %w", dir+k, err)
+		}
+	}
+	return nil
+}
+
 func humanizeAgentLogs(ls []codersdk.WorkspaceAgentLog) string {
 	var buf bytes.Buffer
 	tw := tabwriter.NewWriter(&buf, 0, 2, 1, ' ', 0)
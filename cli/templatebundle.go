@@ -0,0 +1,404 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) templateBundle() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "bundle",
+		Short: "Manage self-contained template bundles for deployments without internet access",
+		Long: "Template bundles contain the source of a template along with the modules and providers it requires, so that it can be imported by deployments without internet access.\n" + FormatExamples(
+			Example{
+				Description: "Create a bundle of the template in the current directory",
+				Command:     "coder templates bundle create --version v1",
+			},
+			Example{
+				Description: "Push the bundle to the template registry of the organization",
+				Command:     "coder templates bundle push app-v1.tar",
+			},
+			Example{
+				Description: "Pull the bundle from the template registry of the organization",
+				Command:     "coder templates bundle pull app@v1",
+			},
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.templateBundleCreate(),
+			r.templateBundleExtract(),
+			r.templateBundleList(),
+			r.templateBundlePull(),
+			r.templateBundlePush(),
+		},
+	}
+
+	return cmd
+}
+
+func (*RootCmd) templateBundleCreate() *serpent.Command {
+	var (
+		name      string
+		version   string
+		output    string
+		platforms []string
+	)
+	cmd := &serpent.Command{
+		Use:   "create [directory]",
+		Short: "Create a bundle of a template, including the modules and providers it requires",
+		Long:  "Terraform must be installed to download the modules and providers of the template. Modules are installed into the .terraform directory of the template.",
+		Middleware: serpent.Chain(
+			serpent.RequireRangeArgs(0, 1),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			directory := "."
+			if len(inv.Args) > 0 {
+				directory = inv.Args[0]
+			}
+			directory, err := filepath.Abs(directory)
+			if err != nil {
+				return err
+			}
+
+			if name == "" {
+				name = filepath.Base(directory)
+			}
+			if err := codersdk.NameValid(name); err != nil {
+				return xerrors.Errorf("bundle name %q is invalid: %w", name, err)
+			}
+			if err := codersdk.TemplateVersionNameValid(version); err != nil {
+				return xerrors.Errorf("bundle version %q is invalid: %w", version, err)
+			}
+			if output == "" {
+				output = fmt.Sprintf("%s-%s.tar", name, version)
+			}
+
+			terraformPath, err := exec.LookPath("terraform")
+			if err != nil {
+				return xerrors.Errorf("terraform is required to download the modules and providers of the template: %w", err)
+			}
+
+			cliui.Info(inv.Stderr, "Downloading the modules of the template...")
+			err = runTerraform(inv, terraformPath, directory, "get")
+			if err != nil {
+				return xerrors.Errorf("download modules: %w", err)
+			}
+
+			providersDir, err := os.MkdirTemp("", "coder-template-bundle-")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(providersDir)
+			cliui.Info(inv.Stderr, "Downloading the providers of the template...")
+			args := []string{"providers", "mirror"}
+			for _, platform := range platforms {
+				args = append(args, "-platform="+platform)
+			}
+			err = runTerraform(inv, terraformPath, directory, append(args, providersDir)...)
+			if err != nil {
+				return xerrors.Errorf("download providers: %w", err)
+			}
+
+			providers, err := provisionersdk.ReadBundleProviders(providersDir)
+			if err != nil {
+				return xerrors.Errorf("read providers: %w", err)
+			}
+			modules, err := provisionersdk.ReadBundleModules(directory)
+			if err != nil {
+				return xerrors.Errorf("read modules: %w", err)
+			}
+
+			file, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			err = provisionersdk.WriteBundle(file, inv.Logger, provisionersdk.BundleManifest{
+				Name:      name,
+				Version:   version,
+				CreatedAt: time.Now().UTC(),
+				Providers: providers,
+				Modules:   modules,
+			}, directory, providersDir)
+			if err != nil {
+				return xerrors.Errorf("write bundle: %w", err)
+			}
+			if err := file.Close(); err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Created bundle %s with %d provider(s) and %d module(s) at %s\n",
+				cliui.Keyword(name+"@"+version), len(providers), len(modules), output)
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "name",
+			Description: "Specify the name of the bundle. Defaults to the name of the template directory.",
+			Value:       serpent.StringOf(&name),
+		},
+		{
+			Flag:        "version",
+			Description: "Specify the version of the bundle.",
+			Required:    true,
+			Value:       serpent.StringOf(&version),
+		},
+		{
+			Flag:          "output",
+			FlagShorthand: "o",
+			Description:   "Specify the path to write the bundle to. Defaults to <name>-<version>.tar.",
+			Value:         serpent.StringOf(&output),
+		},
+		{
+			Flag:        "platform",
+			Description: "Specify the platforms, as os_arch, of the provisioners that will use the providers of the bundle.",
+			Default:     "linux_amd64",
+			Value:       serpent.StringArrayOf(&platforms),
+		},
+	}
+	return cmd
+}
+
+func runTerraform(inv *serpent.Invocation, terraformPath, directory string, args ...string) error {
+	cmd := exec.CommandContext(inv.Context(), terraformPath, args...)
+	cmd.Dir = directory
+	cmd.Stdout = inv.Stderr
+	cmd.Stderr = inv.Stderr
+	return cmd.Run()
+}
+
+func (*RootCmd) templateBundleExtract() *serpent.Command {
+	var (
+		template     bool
+		providersDir string
+	)
+	cmd := &serpent.Command{
+		Use:   "extract <file>",
+		Short: "Extract the template or the providers of a bundle",
+		Long: FormatExamples(
+			Example{
+				Description: "Write the template of a bundle to a template archive",
+				Command:     "coder templates bundle extract app-v1.tar --template > app.tar",
+			},
+			Example{
+				Description: "Push the template archive as a new version of the template",
+				Command:     "coder templates push app -d - < app.tar",
+			},
+			Example{
+				Description: "Extract the providers of a bundle into a filesystem mirror of the provisioners",
+				Command:     "coder templates bundle extract app-v1.tar --providers-directory /mirror",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			if template == (providersDir != "") {
+				return xerrors.New("either --template or --providers-directory must be provided")
+			}
+
+			data, err := os.ReadFile(inv.Args[0])
+			if err != nil {
+				return err
+			}
+			if _, err := provisionersdk.ReadBundleManifest(bytes.NewReader(data)); err != nil {
+				return xerrors.Errorf("read bundle: %w", err)
+			}
+
+			if template {
+				return provisionersdk.BundleTemplateArchive(inv.Stdout, bytes.NewReader(data))
+			}
+			err = provisionersdk.ExtractBundleProviders(providersDir, bytes.NewReader(data))
+			if err != nil {
+				return xerrors.Errorf("extract providers: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Extracted the providers of the bundle to %s\n", providersDir)
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "template",
+			Description: "Write the template of the bundle, including its modules, as a tar archive to stdout.",
+			Value:       serpent.BoolOf(&template),
+		},
+		{
+			Flag:        "providers-directory",
+			Description: "Extract the providers of the bundle into the directory.",
+			Value:       serpent.StringOf(&providersDir),
+		},
+	}
+	return cmd
+}
+
+type templateBundleTableRow struct {
+	// Used by json format:
+	TemplateBundle codersdk.TemplateBundle
+
+	// Used by table format:
+	Name      string `json:"-" table:"name,default_sort"`
+	Version   string `json:"-" table:"version"`
+	Providers int    `json:"-" table:"providers"`
+	Modules   int    `json:"-" table:"modules"`
+	Size      int64  `json:"-" table:"size"`
+	CreatedBy string `json:"-" table:"created by"`
+	CreatedAt string `json:"-" table:"created at"`
+}
+
+func (r *RootCmd) templateBundleList() *serpent.Command {
+	var (
+		formatter = cliui.NewOutputFormatter(
+			cliui.TableFormat([]templateBundleTableRow{}, []string{"name", "version", "providers", "modules", "created by", "created at"}),
+			cliui.JSONFormat(),
+		)
+		orgContext = NewOrganizationContext()
+	)
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list",
+		Short:   "List the template bundles in the template registry of the organization",
+		Aliases: []string{"ls"},
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			organization, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return err
+			}
+
+			bundles, err := client.TemplateBundles(inv.Context(), organization.ID)
+			if err != nil {
+				return err
+			}
+			if len(bundles) == 0 {
+				cliui.Info(inv.Stderr, "No template bundles found! Push one with "+cliui.Code("coder templates bundle push <file>"))
+				return nil
+			}
+
+			rows := make([]templateBundleTableRow, 0, len(bundles))
+			for _, bundle := range bundles {
+				rows = append(rows, templateBundleTableRow{
+					TemplateBundle: bundle,
+					Name:           bundle.Name,
+					Version:        bundle.Version,
+					Providers:      len(bundle.Providers),
+					Modules:        len(bundle.Modules),
+					Size:           bundle.Size,
+					CreatedBy:      bundle.CreatedBy.Username,
+					CreatedAt:      bundle.CreatedAt.Format("January 2, 2006"),
+				})
+			}
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
+
+func (r *RootCmd) templateBundlePull() *serpent.Command {
+	orgContext := NewOrganizationContext()
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "pull <name>@<version> [destination]",
+		Short: "Pull a template bundle from the template registry of the organization",
+		Middleware: serpent.Chain(
+			serpent.RequireRangeArgs(1, 2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			name, version, ok := strings.Cut(inv.Args[0], "@")
+			if !ok || name == "" || version == "" {
+				return xerrors.Errorf("bundle %q must be formatted as <name>@<version>", inv.Args[0])
+			}
+			dest := fmt.Sprintf("%s-%s.tar", name, version)
+			if len(inv.Args) > 1 {
+				dest = inv.Args[1]
+			}
+
+			organization, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return err
+			}
+
+			data, err := client.DownloadTemplateBundle(inv.Context(), organization.ID, name, version)
+			if err != nil {
+				return xerrors.Errorf("pull template bundle: %w", err)
+			}
+			err = os.WriteFile(dest, data, 0o600)
+			if err != nil {
+				return xerrors.Errorf("write template bundle: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Pulled template bundle %s to %s\n", cliui.Keyword(name+"@"+version), dest)
+			return nil
+		},
+	}
+
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
+
+func (r *RootCmd) templateBundlePush() *serpent.Command {
+	orgContext := NewOrganizationContext()
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "push <file>",
+		Short: "Push a template bundle to the template registry of the organization",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			organization, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return err
+			}
+
+			file, err := os.Open(inv.Args[0])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			bundle, err := client.UploadTemplateBundle(inv.Context(), organization.ID, file)
+			if err != nil {
+				return xerrors.Errorf("push template bundle: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Pushed template bundle %s with %d provider(s) and %d module(s)\n",
+				cliui.Keyword(bundle.Name+"@"+bundle.Version), len(bundle.Providers), len(bundle.Modules))
+			return nil
+		},
+	}
+
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
@@ -0,0 +1,168 @@
+package cli_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateBundle(t *testing.T) {
+	t.Parallel()
+
+	// writeBundle writes a bundle as created by `coder templates bundle
+	// create`, without requiring terraform.
+	writeBundle := func(t *testing.T, name, version string) string {
+		t.Helper()
+
+		templateDir := t.TempDir()
+		err := os.WriteFile(filepath.Join(templateDir, "main.tf"), []byte(`resource "null_resource" "example" {}`), 0o600)
+		require.NoError(t, err)
+		providersDir := filepath.Join(t.TempDir(), "registry.terraform.io", "hashicorp", "null")
+		err = os.MkdirAll(providersDir, 0o755)
+		require.NoError(t, err)
+		err = os.WriteFile(filepath.Join(providersDir, "terraform-provider-null_3.2.4_linux_amd64.zip"), []byte("provider"), 0o600)
+		require.NoError(t, err)
+		providersDir = filepath.Dir(filepath.Dir(filepath.Dir(providersDir)))
+		providers, err := provisionersdk.ReadBundleProviders(providersDir)
+		require.NoError(t, err)
+
+		bundlePath := filepath.Join(t.TempDir(), "bundle.tar")
+		file, err := os.Create(bundlePath)
+		require.NoError(t, err)
+		defer file.Close()
+		err = provisionersdk.WriteBundle(file, slogtest.Make(t, nil), provisionersdk.BundleManifest{
+			Name:      name,
+			Version:   version,
+			CreatedAt: time.Now(),
+			Providers: providers,
+			Modules:   []provisionersdk.BundleModule{},
+		}, templateDir, providersDir)
+		require.NoError(t, err)
+		return bundlePath
+	}
+
+	t.Run("PushPullExtract", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+		bundlePath := writeBundle(t, "example", "v1")
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, "templates", "bundle", "push", bundlePath)
+		clitest.SetupConfig(t, templateAdmin, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "with 1 provider(s) and 0 module(s)")
+
+		// Bundles can't be overwritten.
+		inv, root = clitest.New(t, "templates", "bundle", "push", bundlePath)
+		clitest.SetupConfig(t, templateAdmin, root)
+		err = inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "already exists")
+
+		inv, root = clitest.New(t, "templates", "bundle", "list", "--output", "json")
+		clitest.SetupConfig(t, templateAdmin, root)
+		stdout.Reset()
+		inv.Stdout = &stdout
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		var bundles []codersdk.TemplateBundle
+		err = json.Unmarshal(stdout.Bytes(), &bundles)
+		require.NoError(t, err)
+		require.Len(t, bundles, 1)
+		require.Equal(t, "example", bundles[0].Name)
+		require.Equal(t, "v1", bundles[0].Version)
+		require.Equal(t, []codersdk.TemplateBundleProvider{{
+			Source:    "registry.terraform.io/hashicorp/null",
+			Version:   "3.2.4",
+			Platforms: []string{"linux_amd64"},
+		}}, bundles[0].Providers)
+
+		pulledPath := filepath.Join(t.TempDir(), "pulled.tar")
+		inv, root = clitest.New(t, "templates", "bundle", "pull", "example@v1", pulledPath)
+		clitest.SetupConfig(t, templateAdmin, root)
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		pushed, err := os.ReadFile(bundlePath)
+		require.NoError(t, err)
+		pulled, err := os.ReadFile(pulledPath)
+		require.NoError(t, err)
+		require.Equal(t, pushed, pulled)
+
+		inv, _ = clitest.New(t, "templates", "bundle", "extract", pulledPath, "--template")
+		stdout.Reset()
+		inv.Stdout = &stdout
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		header, err := tar.NewReader(&stdout).Next()
+		require.NoError(t, err)
+		require.Equal(t, "main.tf", header.Name)
+
+		providersDir := t.TempDir()
+		inv, _ = clitest.New(t, "templates", "bundle", "extract", pulledPath, "--providers-directory", providersDir)
+		inv.Stdout = io.Discard
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.FileExists(t, filepath.Join(providersDir, "registry.terraform.io", "hashicorp", "null", "terraform-provider-null_3.2.4_linux_amd64.zip"))
+	})
+
+	t.Run("MemberCannotPush", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		bundlePath := writeBundle(t, "example", "v1")
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, "templates", "bundle", "push", bundlePath)
+		clitest.SetupConfig(t, member, root)
+		err := inv.WithContext(ctx).Run()
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidBundle", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+
+		// A template archive without a manifest isn't a bundle.
+		templateDir := t.TempDir()
+		err := os.WriteFile(filepath.Join(templateDir, "main.tf"), []byte(""), 0o600)
+		require.NoError(t, err)
+		bundlePath := filepath.Join(t.TempDir(), "bundle.tar")
+		file, err := os.Create(bundlePath)
+		require.NoError(t, err)
+		err = provisionersdk.Tar(file, slogtest.Make(t, nil), templateDir, provisionersdk.TemplateArchiveLimit)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, "templates", "bundle", "push", bundlePath)
+		clitest.SetupConfig(t, templateAdmin, root)
+		err = inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "Invalid template bundle")
+	})
+}
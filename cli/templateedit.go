@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"math"
 	"net/http"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/coder/serpent"
 
 	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -35,6 +37,15 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 		requireActiveVersion           bool
 		deprecationMessage             string
 		disableEveryone                bool
+		wireguardMTU                   int64
+		wireguardKeepaliveInterval     time.Duration
+		wireguardHandshakeTimeout      time.Duration
+		activationApprovalsRequired    int64
+		autostartHolidays              []string
+		autostartHolidayCalendarURL    string
+		dormantArchivePaths            []string
+		parameterValidationURL         string
+		allowStartOnConnect            bool
 		orgContext                     = NewOrganizationContext()
 	)
 	client := new(codersdk.Client)
@@ -162,6 +173,60 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 				deprecated = &deprecationMessage
 			}
 
+			var (
+				wgMTU               *int32
+				wgKeepaliveInterval *int64
+				wgHandshakeTimeout  *int64
+			)
+			if userSetOption(inv, "wireguard-mtu") {
+				if wireguardMTU < 0 || wireguardMTU > math.MaxInt32 {
+					return xerrors.Errorf("invalid --wireguard-mtu %d", wireguardMTU)
+				}
+				wgMTU = ptr.Ref(int32(wireguardMTU))
+			}
+			if userSetOption(inv, "wireguard-keepalive-interval") {
+				wgKeepaliveInterval = ptr.Ref(wireguardKeepaliveInterval.Milliseconds())
+			}
+			if userSetOption(inv, "wireguard-handshake-timeout") {
+				wgHandshakeTimeout = ptr.Ref(wireguardHandshakeTimeout.Milliseconds())
+			}
+
+			var approvalsRequired *int32
+			if userSetOption(inv, "activation-approvals-required") {
+				if activationApprovalsRequired < 0 || activationApprovalsRequired > math.MaxInt32 {
+					return xerrors.Errorf("invalid --activation-approvals-required %d", activationApprovalsRequired)
+				}
+				approvalsRequired = ptr.Ref(int32(activationApprovalsRequired))
+			}
+
+			var holidays *[]string
+			if userSetOption(inv, "autostart-holidays") {
+				holidays = &autostartHolidays
+				if len(autostartHolidays) == 1 && autostartHolidays[0] == "none" {
+					holidays = &[]string{}
+				}
+			}
+			var holidayCalendarURL *string
+			if userSetOption(inv, "autostart-holiday-calendar-url") {
+				holidayCalendarURL = &autostartHolidayCalendarURL
+			}
+
+			var archivePaths *[]string
+			if userSetOption(inv, "dormant-archive-paths") {
+				archivePaths = &dormantArchivePaths
+				if len(dormantArchivePaths) == 1 && dormantArchivePaths[0] == "none" {
+					archivePaths = &[]string{}
+				}
+			}
+			var validationURL *string
+			if userSetOption(inv, "parameter-validation-url") {
+				validationURL = &parameterValidationURL
+			}
+			var startOnConnect *bool
+			if userSetOption(inv, "allow-start-on-connect") {
+				startOnConnect = &allowStartOnConnect
+			}
+
 			var disableEveryoneGroup bool
 			if userSetOption(inv, "private") {
 				disableEveryoneGroup = disableEveryone
@@ -181,15 +246,24 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 				AutostartRequirement: &codersdk.TemplateAutostartRequirement{
 					DaysOfWeek: autostartRequirementDaysOfWeek,
 				},
-				FailureTTLMillis:               failureTTL.Milliseconds(),
-				TimeTilDormantMillis:           dormancyThreshold.Milliseconds(),
-				TimeTilDormantAutoDeleteMillis: dormancyAutoDeletion.Milliseconds(),
-				AllowUserCancelWorkspaceJobs:   allowUserCancelWorkspaceJobs,
-				AllowUserAutostart:             allowUserAutostart,
-				AllowUserAutostop:              allowUserAutostop,
-				RequireActiveVersion:           requireActiveVersion,
-				DeprecationMessage:             deprecated,
-				DisableEveryoneGroupAccess:     disableEveryoneGroup,
+				FailureTTLMillis:                 failureTTL.Milliseconds(),
+				TimeTilDormantMillis:             dormancyThreshold.Milliseconds(),
+				TimeTilDormantAutoDeleteMillis:   dormancyAutoDeletion.Milliseconds(),
+				AllowUserCancelWorkspaceJobs:     allowUserCancelWorkspaceJobs,
+				AllowUserAutostart:               allowUserAutostart,
+				AllowUserAutostop:                allowUserAutostop,
+				RequireActiveVersion:             requireActiveVersion,
+				DeprecationMessage:               deprecated,
+				DisableEveryoneGroupAccess:       disableEveryoneGroup,
+				WireguardMTU:                     wgMTU,
+				WireguardKeepaliveIntervalMillis: wgKeepaliveInterval,
+				WireguardHandshakeTimeoutMillis:  wgHandshakeTimeout,
+				ActivationApprovalsRequired:      approvalsRequired,
+				AutostartHolidays:                holidays,
+				AutostartHolidayCalendarURL:      holidayCalendarURL,
+				DormantArchivePaths:              archivePaths,
+				ParameterValidationURL:           validationURL,
+				AllowStartOnConnect:              startOnConnect,
 			}
 
 			_, err = client.UpdateTemplateMeta(inv.Context(), template.ID, req)
@@ -302,6 +376,52 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 			Value:   serpent.BoolOf(&disableEveryone),
 			Default: "false",
 		},
+		{
+			Flag:        "wireguard-mtu",
+			Description: "Override the deployment WireGuard MTU for workspaces created from this template. Must be at least 1280. Pass 0 to use the deployment value.",
+			Value:       serpent.Int64Of(&wireguardMTU),
+		},
+		{
+			Flag:        "wireguard-keepalive-interval",
+			Description: "Override the deployment WireGuard keepalive interval for workspaces created from this template. Pass 0 to use the deployment value.",
+			Value:       serpent.DurationOf(&wireguardKeepaliveInterval),
+		},
+		{
+			Flag:        "wireguard-handshake-timeout",
+			Description: "Override the deployment WireGuard handshake timeout for workspaces created from this template. Pass 0 to use the deployment value.",
+			Value:       serpent.DurationOf(&wireguardHandshakeTimeout),
+		},
+		{
+			Flag:        "activation-approvals-required",
+			Description: "The number of reviewers that must approve a template version before it becomes the active version. Pass 0 to activate versions immediately.",
+			Value:       serpent.Int64Of(&activationApprovalsRequired),
+		},
+		{
+			Flag:        "autostart-holidays",
+			Description: "Dates formatted as YYYY-MM-DD on which workspaces created from this template are not autostarted, in addition to the deployment's holidays. Pass \"none\" to clear the holidays.",
+			Value:       serpent.StringArrayOf(&autostartHolidays),
+		},
+		{
+			Flag:        "autostart-holiday-calendar-url",
+			Description: "URL of an iCalendar feed whose events are treated as autostart holidays for workspaces created from this template. Pass an empty string to remove the calendar.",
+			Value:       serpent.StringOf(&autostartHolidayCalendarURL),
+		},
+		{
+			Flag:        "dormant-archive-paths",
+			Description: "Paths in workspaces created from this template to archive before dormant workspaces are auto-deleted. Relative paths are relative to the home directory of the workspace agent. Archiving must be enabled on the deployment. Pass \"none\" to delete dormant workspaces without archiving them.",
+			Value:       serpent.StringArrayOf(&dormantArchivePaths),
+		},
+		{
+			Flag:        "parameter-validation-url",
+			Description: "URL of a webhook that validates the parameter values of workspaces created or updated from this template. Builds are rejected with the message returned by the webhook. Pass an empty string to remove the webhook.",
+			Value:       serpent.StringOf(&parameterValidationURL),
+		},
+		{
+			Flag:        "allow-start-on-connect",
+			Description: "Allow clients like coder ssh and IDE extensions to start stopped workspaces of this template when connecting to them. Users can disable it in their connection settings.",
+			Default:     "true",
+			Value:       serpent.BoolOf(&allowStartOnConnect),
+		},
 		cliui.SkipPromptOption(),
 	}
 	orgContext.AttachOptions(cmd)
@@ -836,6 +836,42 @@ func TestTemplateEdit(t *testing.T) {
 		require.Error(t, err)
 		require.ErrorContains(t, err, "appears to be an AGPL deployment")
 	})
+	t.Run("AutostartHolidays", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		inv, root := clitest.New(t,
+			"templates", "edit", template.Name,
+			"--autostart-holidays", "2025-12-25,2025-12-26",
+			"--autostart-holiday-calendar-url", "https://example.com/holidays.ics",
+		)
+		clitest.SetupConfig(t, client, root)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		updated, err := client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2025-12-25", "2025-12-26"}, updated.AutostartHolidays)
+		assert.Equal(t, "https://example.com/holidays.ics", updated.AutostartHolidayCalendarURL)
+
+		// "none" clears the holidays and other flags are left unchanged.
+		inv, root = clitest.New(t, "templates", "edit", template.Name, "--autostart-holidays", "none")
+		clitest.SetupConfig(t, client, root)
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		updated, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		assert.Empty(t, updated.AutostartHolidays)
+		assert.Equal(t, "https://example.com/holidays.ics", updated.AutostartHolidayCalendarURL)
+	})
 	t.Run("DefaultValues", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
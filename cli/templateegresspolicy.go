@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+type templateEgressPolicyRow struct {
+	Type        string `json:"type" table:"type"`
+	Destination string `json:"destination" table:"destination,default_sort"`
+}
+
+func (r *RootCmd) templateEgressPolicy() *serpent.Command {
+	var (
+		allowedCIDRs   []string
+		allowedDomains []string
+		remove         bool
+		orgContext     = NewOrganizationContext()
+		formatter      = cliui.NewOutputFormatter(
+			cliui.TableFormat([]templateEgressPolicyRow{}, []string{"type", "destination"}),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "egress-policy <template>",
+		Short: "Show or change the egress policy of a template",
+		Long: "While a template has an egress policy, workspace agents block outbound connections " +
+			"from the workspace host to destinations the policy does not allow. Blocked connections " +
+			"are recorded in the audit log.\n" + FormatExamples(
+			Example{
+				Description: "Only allow workspaces to connect to the internal network and GitHub",
+				Command:     "coder templates egress-policy my-template --allow-cidr 10.0.0.0/8 --allow-domain github.com",
+			},
+			Example{
+				Description: "Show the egress policy of a template",
+				Command:     "coder templates egress-policy my-template",
+			},
+			Example{
+				Description: "Lift the egress restrictions of a template",
+				Command:     "coder templates egress-policy my-template --remove",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			organization, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+			template, err := client.TemplateByName(ctx, organization.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+			setting := len(allowedCIDRs) > 0 || len(allowedDomains) > 0
+
+			switch {
+			case remove && setting:
+				return xerrors.New("--remove cannot be combined with --allow-cidr or --allow-domain")
+			case remove:
+				err = client.DeleteTemplateEgressPolicy(ctx, template.ID)
+				if err != nil {
+					return xerrors.Errorf("delete egress policy: %w", err)
+				}
+				_, _ = fmt.Fprintf(inv.Stdout, "Removed the egress policy of template %s.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, template.Name))
+				return nil
+			case setting:
+				_, err = client.UpdateTemplateEgressPolicy(ctx, template.ID, codersdk.UpdateTemplateEgressPolicyRequest{
+					AllowedCIDRs:   allowedCIDRs,
+					AllowedDomains: allowedDomains,
+				})
+				if err != nil {
+					return xerrors.Errorf("update egress policy: %w", err)
+				}
+				_, _ = fmt.Fprintf(inv.Stdout, "Updated the egress policy of template %s. Running workspaces apply it within a minute.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, template.Name))
+				return nil
+			}
+
+			policy, err := client.TemplateEgressPolicy(ctx, template.ID)
+			if cerr, ok := codersdk.AsError(err); ok && cerr.StatusCode() == http.StatusNotFound {
+				cliui.Infof(inv.Stderr, "Template %s does not restrict egress.", template.Name)
+				return nil
+			}
+			if err != nil {
+				return xerrors.Errorf("get egress policy: %w", err)
+			}
+			rows := make([]templateEgressPolicyRow, 0, len(policy.AllowedCIDRs)+len(policy.AllowedDomains))
+			for _, cidr := range policy.AllowedCIDRs {
+				rows = append(rows, templateEgressPolicyRow{Type: "cidr", Destination: cidr})
+			}
+			for _, domain := range policy.AllowedDomains {
+				rows = append(rows, templateEgressPolicyRow{Type: "domain", Destination: domain})
+			}
+			if len(rows) == 0 {
+				cliui.Infof(inv.Stderr, "Template %s blocks all egress except to this deployment.", template.Name)
+				return nil
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+		Options: serpent.OptionSet{
+			{
+				Flag:        "allow-cidr",
+				Description: "Replace the policy with one that allows connections to this IP address or CIDR range. Can be repeated.",
+				Value:       serpent.StringArrayOf(&allowedCIDRs),
+			},
+			{
+				Flag:        "allow-domain",
+				Description: "Replace the policy with one that allows connections to the addresses of this domain. Can be repeated.",
+				Value:       serpent.StringArrayOf(&allowedDomains),
+			},
+			{
+				Flag:        "remove",
+				Description: "Remove the egress policy, which lifts the restrictions.",
+				Value:       serpent.BoolOf(&remove),
+			},
+		},
+	}
+	orgContext.AttachOptions(cmd)
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
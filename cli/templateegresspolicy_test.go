@@ -0,0 +1,54 @@
+package cli_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateEgressPolicy(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "templates", "egress-policy", template.Name, "--allow-cidr", "10.0.0.0/8", "--allow-domain", "github.com")
+	//nolint:gocritic // Egress policies require template admin.
+	clitest.SetupConfig(t, client, root)
+	require.NoError(t, inv.WithContext(ctx).Run())
+
+	policy, err := client.TemplateEgressPolicy(ctx, template.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.0/8"}, policy.AllowedCIDRs)
+	require.Equal(t, []string{"github.com"}, policy.AllowedDomains)
+
+	inv, root = clitest.New(t, "templates", "egress-policy", template.Name)
+	//nolint:gocritic // Egress policies require template admin.
+	clitest.SetupConfig(t, client, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	require.NoError(t, inv.WithContext(ctx).Run())
+	require.Contains(t, buf.String(), "10.0.0.0/8")
+	require.Contains(t, buf.String(), "github.com")
+
+	inv, root = clitest.New(t, "templates", "egress-policy", template.Name, "--remove")
+	//nolint:gocritic // Egress policies require template admin.
+	clitest.SetupConfig(t, client, root)
+	require.NoError(t, inv.WithContext(ctx).Run())
+
+	_, err = client.TemplateEgressPolicy(ctx, template.ID)
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+}
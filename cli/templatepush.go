@@ -199,6 +199,9 @@ func (r *RootCmd) templatePush() *serpent.Command {
 				if err != nil {
 					return err
 				}
+				if template.ActivationApprovalsRequired > 0 {
+					_, _ = fmt.Fprintf(inv.Stdout, "Requested the activation of the version, it will be activated once approved by %d template admin(s).\n", template.ActivationApprovalsRequired)
+				}
 			}
 
 			_, _ = fmt.Fprintf(inv.Stdout, "Updated version at %s!\n", pretty.Sprint(cliui.DefaultStyles.DateTimeStamp, time.Now().Format(time.Stamp)))
@@ -29,12 +29,16 @@ func (r *RootCmd) templates() *serpent.Command {
 		Children: []*serpent.Command{
 			r.templateCreate(),
 			r.templateEdit(),
+			r.templateEgressPolicy(),
+			r.templateStats(),
 			r.templateInit(),
 			r.templateList(),
 			r.templatePush(),
 			r.templateVersions(),
 			r.templateDelete(),
 			r.templatePull(),
+			r.templateTest(),
+			r.templateBundle(),
 			r.archiveTemplateVersions(),
 		},
 	}
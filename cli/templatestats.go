@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+type templateStatsIntervalRow struct {
+	Interval    string `table:"interval,nosort"`
+	Succeeded   int64  `table:"succeeded"`
+	Failed      int64  `table:"failed"`
+	Canceled    int64  `table:"canceled"`
+	FailureRate string `table:"failure rate"`
+}
+
+type templateStatsStageRow struct {
+	Stage  string `table:"stage,nosort"`
+	Builds int64  `table:"builds"`
+	P50    string `table:"p50"`
+	P95    string `table:"p95"`
+}
+
+type templateStatsFailureRow struct {
+	Builds     int64  `table:"builds,nosort"`
+	LastFailed string `table:"last failed"`
+	Error      string `table:"error"`
+}
+
+// templateStatsMaxErrorLength is the length at which error patterns are
+// truncated in the text output.
+const templateStatsMaxErrorLength = 100
+
+func (r *RootCmd) templateStats() *serpent.Command {
+	var (
+		days       int64
+		interval   string
+		orgContext = NewOrganizationContext()
+		formatter  = cliui.NewOutputFormatter(
+			cliui.ChangeFormatterData(cliui.TextFormat(), func(data any) (any, error) {
+				resp, ok := data.(codersdk.TemplateBuildInsightsResponse)
+				if !ok {
+					return nil, xerrors.Errorf("expected codersdk.TemplateBuildInsightsResponse, got %T", data)
+				}
+				return formatTemplateStats(resp)
+			}),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "stats <template>",
+		Short: "Show build success rates, durations and failures of a template",
+		Long: "Show how many workspace builds of a template succeeded and failed over time, the median and " +
+			"95th percentile duration of each build stage, and the most common build failures. Use it to " +
+			"spot regressions after pushing a new template version.\n" + FormatExamples(
+			Example{
+				Description: "Show the build stats of a template for the last 30 days",
+				Command:     "coder templates stats my-template",
+			},
+			Example{
+				Description: "Show weekly build stats for the last 12 weeks",
+				Command:     "coder templates stats my-template --days 84 --interval week",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			if days < 1 {
+				return xerrors.New("--days must be at least 1")
+			}
+			organization, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+			template, err := client.TemplateByName(ctx, organization.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+
+			// Insights are reported in whole days, except for today which
+			// may end at the next full hour.
+			now := time.Now().UTC()
+			y, m, d := now.Date()
+			today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+			startTime := today.AddDate(0, 0, -int(days))
+			endTime := now.Truncate(time.Hour).Add(time.Hour)
+			if codersdk.InsightsReportInterval(interval) == codersdk.InsightsReportIntervalWeek {
+				// Weekly reports must consist of whole weeks.
+				weeks := (days + 6) / 7
+				endTime = today
+				startTime = today.AddDate(0, 0, -7*int(weeks))
+			}
+
+			resp, err := client.TemplateBuildInsights(ctx, codersdk.TemplateBuildInsightsRequest{
+				TemplateID: template.ID,
+				StartTime:  startTime,
+				EndTime:    endTime,
+				Interval:   codersdk.InsightsReportInterval(interval),
+			})
+			if err != nil {
+				return xerrors.Errorf("get template build insights: %w", err)
+			}
+
+			out, err := formatter.Format(ctx, resp)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+		Options: serpent.OptionSet{
+			{
+				Flag:        "days",
+				Description: "Number of days of builds to include.",
+				Default:     "30",
+				Value:       serpent.Int64Of(&days),
+			},
+			{
+				Flag:        "interval",
+				Description: "Interval to report build counts for.",
+				Default:     string(codersdk.InsightsReportIntervalDay),
+				Value: serpent.EnumOf(&interval,
+					string(codersdk.InsightsReportIntervalDay),
+					string(codersdk.InsightsReportIntervalWeek),
+				),
+			},
+		},
+	}
+	orgContext.AttachOptions(cmd)
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func formatTemplateStats(resp codersdk.TemplateBuildInsightsResponse) (string, error) {
+	var sb strings.Builder
+
+	intervals := make([]templateStatsIntervalRow, 0, len(resp.IntervalReports))
+	for _, report := range resp.IntervalReports {
+		rate := "-"
+		if report.SucceededBuilds+report.FailedBuilds > 0 {
+			rate = fmt.Sprintf("%.1f%%", report.FailureRate*100)
+		}
+		intervals = append(intervals, templateStatsIntervalRow{
+			Interval:    report.StartTime.Format(time.DateOnly),
+			Succeeded:   report.SucceededBuilds,
+			Failed:      report.FailedBuilds,
+			Canceled:    report.CanceledBuilds,
+			FailureRate: rate,
+		})
+	}
+	table, err := cliui.DisplayTable(intervals, "", nil)
+	if err != nil {
+		return "", xerrors.Errorf("display builds table: %w", err)
+	}
+	_, _ = fmt.Fprintf(&sb, "%s\n%s\n", pretty.Sprint(cliui.DefaultStyles.Keyword, "Builds"), table)
+
+	_, _ = fmt.Fprintf(&sb, "\n%s\n", pretty.Sprint(cliui.DefaultStyles.Keyword, "Durations of successful builds"))
+	if len(resp.StageDurations) == 0 {
+		_, _ = fmt.Fprintln(&sb, "No successful builds.")
+	} else {
+		stages := make([]templateStatsStageRow, 0, len(resp.StageDurations))
+		for _, stage := range resp.StageDurations {
+			stages = append(stages, templateStatsStageRow{
+				Stage:  string(stage.Stage),
+				Builds: stage.Builds,
+				P50:    templateStatsDuration(stage.P50Seconds),
+				P95:    templateStatsDuration(stage.P95Seconds),
+			})
+		}
+		table, err = cliui.DisplayTable(stages, "", nil)
+		if err != nil {
+			return "", xerrors.Errorf("display durations table: %w", err)
+		}
+		_, _ = fmt.Fprintln(&sb, table)
+	}
+
+	_, _ = fmt.Fprintf(&sb, "\n%s\n", pretty.Sprint(cliui.DefaultStyles.Keyword, "Most common failures"))
+	if len(resp.FailureGroups) == 0 {
+		_, _ = fmt.Fprint(&sb, "No failed builds.")
+	} else {
+		failures := make([]templateStatsFailureRow, 0, len(resp.FailureGroups))
+		for _, group := range resp.FailureGroups {
+			pattern := group.Pattern
+			if len(pattern) > templateStatsMaxErrorLength {
+				pattern = pattern[:templateStatsMaxErrorLength-3] + "..."
+			}
+			failures = append(failures, templateStatsFailureRow{
+				Builds:     group.Builds,
+				LastFailed: group.LastFailedAt.Local().Format(time.DateTime),
+				Error:      pattern,
+			})
+		}
+		table, err = cliui.DisplayTable(failures, "", nil)
+		if err != nil {
+			return "", xerrors.Errorf("display failures table: %w", err)
+		}
+		_, _ = fmt.Fprint(&sb, table)
+	}
+
+	return sb.String(), nil
+}
+
+func templateStatsDuration(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second).String()
+}
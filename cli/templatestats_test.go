@@ -0,0 +1,58 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateStats(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	t.Run("Text", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, "templates", "stats", template.Name, "--days", "7")
+		//nolint:gocritic // Template insights require template admin.
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		require.NoError(t, inv.WithContext(ctx).Run())
+		require.Contains(t, buf.String(), "FAILURE RATE")
+		require.Contains(t, buf.String(), "total")
+		require.Contains(t, buf.String(), "No failed builds.")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, "templates", "stats", template.Name, "--interval", "week", "--output", "json")
+		//nolint:gocritic // Template insights require template admin.
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		require.NoError(t, inv.WithContext(ctx).Run())
+
+		var resp codersdk.TemplateBuildInsightsResponse
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &resp))
+		require.Equal(t, template.ID, resp.TemplateID)
+		require.Len(t, resp.IntervalReports, 5)
+	})
+}
@@ -0,0 +1,411 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/cli/cliutil"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+const (
+	templateTestAgentsConnected  = "agents-connected"
+	templateTestScriptsSucceeded = "scripts-succeeded"
+	templateTestAppsHealthy      = "apps-healthy"
+)
+
+var templateTestAssertions = []string{
+	templateTestAgentsConnected,
+	templateTestScriptsSucceeded,
+	templateTestAppsHealthy,
+}
+
+func (r *RootCmd) templateTest() *serpent.Command {
+	var (
+		provisioner          string
+		workdir              string
+		variablesFile        string
+		commandLineVariables []string
+		provisionerTags      []string
+		uploadFlags          templateUploadFlags
+		parameterFlags       workspaceParameterFlags
+		assertions           []string
+		timeout              time.Duration
+		keep                 bool
+		orgContext           = NewOrganizationContext()
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "test [template]",
+		Short: "Test a template by building an ephemeral workspace from the current directory",
+		Long: "The template is uploaded to a temporary template, or as an inactive version of the given template. " +
+			"A workspace is built from it and the assertions are checked, then the workspace and the temporary template are deleted. " +
+			"The command exits with a non-zero status if the build or any assertion fails.\n" + FormatExamples(
+			Example{
+				Description: "Test the template in the current directory from CI",
+				Command:     "coder templates test --yes --parameter region=us-east",
+			},
+			Example{
+				Description: "Only check that the agents connect",
+				Command:     "coder templates test my-template --yes --assert agents-connected",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireRangeArgs(0, 1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) (err error) {
+			ctx := inv.Context()
+			uploadFlags.setWorkdir(workdir)
+
+			organization, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return err
+			}
+
+			var template *codersdk.Template
+			if len(inv.Args) > 0 {
+				existing, err := client.TemplateByName(ctx, organization.ID, inv.Args[0])
+				if err != nil {
+					return xerrors.Errorf("get template %q: %w", inv.Args[0], err)
+				}
+				template = &existing
+			}
+
+			tags, err := ParseProvisionerTags(provisionerTags)
+			if err != nil {
+				return err
+			}
+
+			err = uploadFlags.checkForLockfile(inv)
+			if err != nil {
+				return xerrors.Errorf("check for lockfile: %w", err)
+			}
+
+			var varsFiles []string
+			if !uploadFlags.stdin(inv) {
+				varsFiles, err = codersdk.DiscoverVarsFiles(uploadFlags.directory)
+				if err != nil {
+					return err
+				}
+			}
+			userVariableValues, err := codersdk.ParseUserVariableValues(
+				varsFiles,
+				variablesFile,
+				commandLineVariables)
+			if err != nil {
+				return err
+			}
+
+			cliBuildParameters, err := asWorkspaceBuildParameters(parameterFlags.richParameters)
+			if err != nil {
+				return xerrors.Errorf("can't parse given parameter values: %w", err)
+			}
+			cliBuildParameterDefaults, err := asWorkspaceBuildParameters(parameterFlags.richParameterDefaults)
+			if err != nil {
+				return xerrors.Errorf("can't parse given parameter defaults: %w", err)
+			}
+
+			resp, err := uploadFlags.upload(inv, client)
+			if err != nil {
+				return err
+			}
+
+			// Resources are named with the same random suffix so that they
+			// can be matched up if the test is interrupted.
+			suffix, err := cryptorand.StringCharset(cryptorand.Lower+cryptorand.Numeric, 8)
+			if err != nil {
+				return xerrors.Errorf("generate name: %w", err)
+			}
+			name := "test-" + suffix
+
+			version, err := createValidTemplateVersion(inv, createValidTemplateVersionArgs{
+				Name:               name,
+				Message:            uploadFlags.templateMessage(inv),
+				Client:             client,
+				Organization:       organization,
+				Provisioner:        codersdk.ProvisionerType(provisioner),
+				FileID:             resp.ID,
+				Template:           template,
+				ProvisionerTags:    tags,
+				UserVariableValues: userVariableValues,
+			})
+			if err != nil {
+				return err
+			}
+
+			// Teardown runs even if the test is interrupted, so it must not
+			// use the invocation context.
+			teardownCtx := context.WithoutCancel(ctx)
+			if template == nil {
+				created, err := client.CreateTemplate(ctx, organization.ID, codersdk.CreateTemplateRequest{
+					Name:        name,
+					DisplayName: "Template test " + suffix,
+					VersionID:   version.ID,
+				})
+				if err != nil {
+					return xerrors.Errorf("create temporary template: %w", err)
+				}
+				defer func() {
+					if keep {
+						cliui.Infof(inv.Stderr, "Keeping the temporary template %s.", cliui.Keyword(created.Name))
+						return
+					}
+					if deleteErr := client.DeleteTemplate(teardownCtx, created.ID); deleteErr != nil {
+						err = errors.Join(err, xerrors.Errorf("delete temporary template: %w", deleteErr))
+					}
+				}()
+			}
+
+			richParameters, err := prepWorkspaceBuild(inv, client, prepWorkspaceBuildArgs{
+				Action:            WorkspaceCreate,
+				TemplateVersionID: version.ID,
+				NewWorkspaceName:  name,
+
+				RichParameterFile:     parameterFlags.richParameterFile,
+				RichParameters:        cliBuildParameters,
+				RichParameterDefaults: cliBuildParameterDefaults,
+			})
+			if err != nil {
+				return xerrors.Errorf("prepare build: %w", err)
+			}
+
+			workspace, err := client.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+				TemplateVersionID:   version.ID,
+				Name:                name,
+				RichParameterValues: richParameters,
+			})
+			if err != nil {
+				return xerrors.Errorf("create workspace: %w", err)
+			}
+			defer func() {
+				if keep {
+					cliui.Infof(inv.Stderr, "Keeping the workspace %s.", cliui.Keyword(workspace.Name))
+					return
+				}
+				if deleteErr := deleteTemplateTestWorkspace(teardownCtx, inv, client, workspace); deleteErr != nil {
+					err = errors.Join(err, deleteErr)
+				}
+			}()
+			cliutil.WarnMatchedProvisioners(inv.Stderr, workspace.LatestBuild.MatchedProvisioners, workspace.LatestBuild.Job)
+
+			err = cliui.WorkspaceBuild(ctx, inv.Stdout, client, workspace.LatestBuild.ID)
+			if err != nil {
+				return xerrors.Errorf("watch build: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stdout, "\nChecking assertions...")
+			results, err := waitTemplateTestAssertions(ctx, client, workspace.ID, assertions, timeout)
+			if err != nil {
+				return err
+			}
+			var failed int
+			for _, result := range results {
+				if result.passed {
+					_, _ = fmt.Fprintf(inv.Stdout, "✔ %s\n", result.assertion)
+					continue
+				}
+				failed++
+				_, _ = fmt.Fprintf(inv.Stdout, "%s %s: %s\n", pretty.Sprint(cliui.DefaultStyles.Error, "✘"), result.assertion, result.detail)
+			}
+			if failed > 0 {
+				return xerrors.Errorf("%d of %d assertions failed", failed, len(results))
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "\nAll assertions passed at %s!\n", cliui.Timestamp(time.Now()))
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "test.provisioner",
+			Description: "Customize the provisioner backend.",
+			Default:     "terraform",
+			Value:       serpent.StringOf(&provisioner),
+			// This is for testing!
+			Hidden: true,
+		},
+		{
+			Flag:        "test.workdir",
+			Description: "Customize the working directory.",
+			Default:     "",
+			Value:       serpent.StringOf(&workdir),
+			// This is for testing!
+			Hidden: true,
+		},
+		{
+			Flag:        "variables-file",
+			Description: "Specify a file path with values for Terraform-managed variables.",
+			Value:       serpent.StringOf(&variablesFile),
+		},
+		{
+			Flag:        "variable",
+			Description: "Specify a set of values for Terraform-managed variables.",
+			Value:       serpent.StringArrayOf(&commandLineVariables),
+		},
+		{
+			Flag:        "var",
+			Description: "Alias of --variable.",
+			Value:       serpent.StringArrayOf(&commandLineVariables),
+		},
+		{
+			Flag:        "provisioner-tag",
+			Description: "Specify a set of tags to target provisioner daemons.",
+			Value:       serpent.StringArrayOf(&provisionerTags),
+		},
+		{
+			Flag:        "assert",
+			Env:         "CODER_TEMPLATE_TEST_ASSERT",
+			Description: "Assertions to check once the workspace is built.",
+			Default:     "agents-connected,scripts-succeeded,apps-healthy",
+			Value:       serpent.EnumArrayOf(&assertions, templateTestAssertions...),
+		},
+		{
+			Flag:        "timeout",
+			Env:         "CODER_TEMPLATE_TEST_TIMEOUT",
+			Description: "How long to wait for the assertions to pass once the workspace is built.",
+			Default:     "10m",
+			Value:       serpent.DurationOf(&timeout),
+		},
+		{
+			Flag:        "keep",
+			Description: "Keep the workspace and the temporary template after the test, for debugging.",
+			Value:       serpent.BoolOf(&keep),
+		},
+		cliui.SkipPromptOption(),
+	}
+	cmd.Options = append(cmd.Options, uploadFlags.options()...)
+	cmd.Options = append(cmd.Options, parameterFlags.cliParameters()...)
+	cmd.Options = append(cmd.Options, parameterFlags.cliParameterDefaults()...)
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
+
+type templateTestResult struct {
+	assertion string
+	passed    bool
+	// final is set when the assertion can no longer change, e.g. when a
+	// startup script failed.
+	final  bool
+	detail string
+}
+
+// waitTemplateTestAssertions polls the workspace until every assertion has
+// passed or failed, or until the timeout.
+func waitTemplateTestAssertions(ctx context.Context, client *codersdk.Client, workspaceID uuid.UUID, assertions []string, timeout time.Duration) ([]templateTestResult, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		workspace, err := client.Workspace(ctx, workspaceID)
+		if err != nil {
+			return nil, xerrors.Errorf("get workspace: %w", err)
+		}
+		results := make([]templateTestResult, 0, len(assertions))
+		done := true
+		for _, assertion := range assertions {
+			result := checkTemplateTestAssertion(assertion, workspace)
+			done = done && (result.passed || result.final)
+			results = append(results, result)
+		}
+		if done {
+			return results, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline.C:
+			for i := range results {
+				if !results[i].passed && !results[i].final {
+					results[i].detail = fmt.Sprintf("timed out after %s: %s", timeout, results[i].detail)
+				}
+			}
+			return results, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkTemplateTestAssertion checks a single assertion against the current
+// state of the workspace.
+func checkTemplateTestAssertion(assertion string, workspace codersdk.Workspace) templateTestResult {
+	result := templateTestResult{assertion: assertion, passed: true}
+	for _, resource := range workspace.LatestBuild.Resources {
+		for _, agent := range resource.Agents {
+			switch assertion {
+			case templateTestAgentsConnected:
+				if agent.Status != codersdk.WorkspaceAgentConnected {
+					result.passed = false
+					result.detail = fmt.Sprintf("agent %q is %s", agent.Name, agent.Status)
+				}
+			case templateTestScriptsSucceeded:
+				if slices.Contains([]codersdk.WorkspaceAgentLifecycle{
+					codersdk.WorkspaceAgentLifecycleStartError,
+					codersdk.WorkspaceAgentLifecycleStartTimeout,
+				}, agent.LifecycleState) {
+					return templateTestResult{
+						assertion: assertion,
+						final:     true,
+						detail:    fmt.Sprintf("startup scripts of agent %q failed: %s", agent.Name, agent.LifecycleState),
+					}
+				}
+				if agent.LifecycleState != codersdk.WorkspaceAgentLifecycleReady {
+					result.passed = false
+					result.detail = fmt.Sprintf("agent %q is %s", agent.Name, agent.LifecycleState)
+				}
+			case templateTestAppsHealthy:
+				for _, app := range agent.Apps {
+					if app.Health != codersdk.WorkspaceAppHealthDisabled && app.Health != codersdk.WorkspaceAppHealthHealthy {
+						result.passed = false
+						result.detail = fmt.Sprintf("app %q of agent %q is %s", app.Slug, agent.Name, app.Health)
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+// deleteTemplateTestWorkspace deletes the workspace built by the test and
+// waits for its resources to be destroyed.
+func deleteTemplateTestWorkspace(ctx context.Context, inv *serpent.Invocation, client *codersdk.Client, workspace codersdk.Workspace) error {
+	_, _ = fmt.Fprintln(inv.Stdout, "\nDeleting the workspace...")
+	req := codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionDelete,
+	}
+	build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, req)
+	var apiErr *codersdk.Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode() == http.StatusConflict {
+		// The start build is still running because the test was
+		// interrupted, so cancel it before deleting the workspace.
+		err = client.CancelWorkspaceBuild(ctx, workspace.LatestBuild.ID, codersdk.CancelWorkspaceBuildParams{})
+		if err != nil {
+			return xerrors.Errorf("cancel workspace build: %w", err)
+		}
+		_ = cliui.WorkspaceBuild(ctx, inv.Stdout, client, workspace.LatestBuild.ID)
+		build, err = client.CreateWorkspaceBuild(ctx, workspace.ID, req)
+	}
+	if err != nil {
+		return xerrors.Errorf("delete workspace: %w", err)
+	}
+	err = cliui.WorkspaceBuild(ctx, inv.Stdout, client, build.ID)
+	if err != nil {
+		return xerrors.Errorf("watch delete build: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package cli_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateTest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+
+		source := clitest.CreateTemplateVersionSource(t, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.PlanComplete,
+			ProvisionApply: echo.ApplyComplete,
+		})
+		inv, root := clitest.New(t, "templates", "test", "--directory", source, "--test.provisioner", string(database.ProvisionerTypeEcho), "--yes")
+		clitest.SetupConfig(t, templateAdmin, root)
+		pty := ptytest.New(t).Attach(inv)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		w := clitest.StartWithWaiter(t, inv.WithContext(ctx))
+		pty.ExpectMatchContext(ctx, "All assertions passed")
+		w.RequireSuccess()
+
+		// The temporary template and its workspace are deleted.
+		templates, err := client.Templates(ctx, codersdk.TemplateFilter{})
+		require.NoError(t, err)
+		require.Empty(t, templates)
+		workspaces, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Empty(t, workspaces.Workspaces)
+	})
+
+	t.Run("AgentNeverConnects", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		source := clitest.CreateTemplateVersionSource(t, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.PlanComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(uuid.NewString()),
+		})
+		inv, root := clitest.New(t, "templates", "test", template.Name, "--directory", source, "--test.provisioner", string(database.ProvisionerTypeEcho), "--yes",
+			"--assert", "agents-connected", "--timeout", "1s")
+		clitest.SetupConfig(t, templateAdmin, root)
+		pty := ptytest.New(t).Attach(inv)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		w := clitest.StartWithWaiter(t, inv.WithContext(ctx))
+		pty.ExpectMatchContext(ctx, "agents-connected: timed out")
+		w.RequireError()
+
+		// The version is tested without being activated, and the workspace
+		// is deleted.
+		template, err := client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, template.ActiveVersionID)
+		workspaces, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Empty(t, workspaces.Workspaces)
+	})
+}
@@ -207,6 +207,10 @@ func (r *RootCmd) templateVersionsPromote() *serpent.Command {
 			if err != nil {
 				return xerrors.Errorf("update active template version: %w", err)
 			}
+			if template.ActivationApprovalsRequired > 0 && template.ActiveVersionID != version.ID {
+				_, _ = fmt.Fprintf(inv.Stdout, "Requested the activation of version %q for template %q, it will be activated once approved by %d template admin(s)\n", templateVersionName, templateName, template.ActivationApprovalsRequired)
+				return nil
+			}
 
 			_, _ = fmt.Fprintf(inv.Stdout, "Successfully promoted version %q to active for template %q\n", templateVersionName, templateName)
 			return nil
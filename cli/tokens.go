@@ -138,6 +138,13 @@ type tokenListRow struct {
 	ExpiresAt time.Time `json:"-" table:"expires at"`
 	CreatedAt time.Time `json:"-" table:"created at"`
 	Owner     string    `json:"-" table:"owner"`
+
+	// Only set when listing sessions.
+	LoginType         codersdk.LoginType `json:"-" table:"login type"`
+	IPAddress         string             `json:"ip_address,omitempty" table:"ip address"`
+	UserAgent         string             `json:"user_agent,omitempty" table:"user agent"`
+	DeviceFingerprint string             `json:"device_fingerprint,omitempty" table:"device"`
+	Current           bool               `json:"current,omitempty" table:"current"`
 }
 
 func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
@@ -152,15 +159,35 @@ func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
 	}
 }
 
+func tokenListRowFromSession(session codersdk.APISession) tokenListRow {
+	return tokenListRow{
+		APIKey:            session.APIKey,
+		ID:                session.ID,
+		LastUsed:          session.LastUsed,
+		ExpiresAt:         session.ExpiresAt,
+		CreatedAt:         session.CreatedAt,
+		LoginType:         session.LoginType,
+		IPAddress:         session.IPAddress,
+		UserAgent:         session.UserAgent,
+		DeviceFingerprint: session.DeviceFingerprint,
+		Current:           session.Current,
+	}
+}
+
 func (r *RootCmd) listTokens() *serpent.Command {
 	// we only display the 'owner' column if the --all argument is passed in
 	defaultCols := []string{"id", "name", "last used", "expires at", "created at"}
 	if slices.Contains(os.Args, "-a") || slices.Contains(os.Args, "--all") {
 		defaultCols = append(defaultCols, "owner")
 	}
+	// sessions have no name, but show the device that logged in.
+	if slices.Contains(os.Args, "--sessions") {
+		defaultCols = []string{"id", "login type", "ip address", "user agent", "last used", "expires at", "current"}
+	}
 
 	var (
 		all           bool
+		sessions      bool
 		displayTokens []tokenListRow
 		formatter     = cliui.NewOutputFormatter(
 			cliui.TableFormat([]tokenListRow{}, defaultCols),
@@ -178,24 +205,44 @@ func (r *RootCmd) listTokens() *serpent.Command {
 			r.InitClient(client),
 		),
 		Handler: func(inv *serpent.Invocation) error {
-			tokens, err := client.Tokens(inv.Context(), codersdk.Me, codersdk.TokensFilter{
-				IncludeAll: all,
-			})
-			if err != nil {
-				return xerrors.Errorf("list tokens: %w", err)
-			}
+			if sessions {
+				if all {
+					return xerrors.New("--all cannot be used with --sessions")
+				}
+				apiSessions, err := client.APISessions(inv.Context(), codersdk.Me)
+				if err != nil {
+					return xerrors.Errorf("list sessions: %w", err)
+				}
+				if len(apiSessions) == 0 {
+					cliui.Infof(
+						inv.Stdout,
+						"No sessions found.\n",
+					)
+				}
+				displayTokens = make([]tokenListRow, len(apiSessions))
+				for i, session := range apiSessions {
+					displayTokens[i] = tokenListRowFromSession(session)
+				}
+			} else {
+				tokens, err := client.Tokens(inv.Context(), codersdk.Me, codersdk.TokensFilter{
+					IncludeAll: all,
+				})
+				if err != nil {
+					return xerrors.Errorf("list tokens: %w", err)
+				}
 
-			if len(tokens) == 0 {
-				cliui.Infof(
-					inv.Stdout,
-					"No tokens found.\n",
-				)
-			}
+				if len(tokens) == 0 {
+					cliui.Infof(
+						inv.Stdout,
+						"No tokens found.\n",
+					)
+				}
 
-			displayTokens = make([]tokenListRow, len(tokens))
+				displayTokens = make([]tokenListRow, len(tokens))
 
-			for i, token := range tokens {
-				displayTokens[i] = tokenListRowFromToken(token)
+				for i, token := range tokens {
+					displayTokens[i] = tokenListRowFromToken(token)
+				}
 			}
 
 			out, err := formatter.Format(inv.Context(), displayTokens)
@@ -215,6 +262,11 @@ func (r *RootCmd) listTokens() *serpent.Command {
 			Description:   "Specifies whether all users' tokens will be listed or not (must have Owner role to see all tokens).",
 			Value:         serpent.BoolOf(&all),
 		},
+		{
+			Flag:        "sessions",
+			Description: "List your login sessions instead of tokens, including the IP address and device that created them.",
+			Value:       serpent.BoolOf(&sessions),
+		},
 	}
 
 	formatter.AttachOptions(&cmd.Options)
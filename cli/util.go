@@ -254,6 +254,18 @@ func extendedParseDuration(raw string) (time.Duration, error) {
 	return time.Duration(d), nil
 }
 
+// parseDate parses either a date (YYYY-MM-DD), interpreted as the start of
+// that day in loc, or an RFC 3339 timestamp.
+func parseDate(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, s, loc); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, xerrors.Errorf("invalid date %q: expected YYYY-MM-DD or an RFC 3339 timestamp", s)
+}
+
 // parseTime attempts to parse a time (no date) from the given string using a number of layouts.
 func parseTime(s string) (time.Time, error) {
 	// Try a number of possible layouts.
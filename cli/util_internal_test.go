@@ -83,6 +83,36 @@ func TestExtendedParseDuration(t *testing.T) {
 	}
 }
 
+func TestParseDate(t *testing.T) {
+	t.Parallel()
+
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	for _, testCase := range []struct {
+		Date       string
+		Expected   time.Time
+		ExpectedOk bool
+	}{
+		{"2026-11-02", time.Date(2026, 11, 2, 0, 0, 0, 0, loc), true},
+		{"2026-11-02T09:30:00Z", time.Date(2026, 11, 2, 9, 30, 0, 0, time.UTC), true},
+		{"2026-11-02T09:30:00+01:00", time.Date(2026, 11, 2, 8, 30, 0, 0, time.UTC), true},
+		{"02/11/2026", time.Time{}, false},
+		{"2026-13-02", time.Time{}, false},
+		{"", time.Time{}, false},
+	} {
+		t.Run(testCase.Date, func(t *testing.T) {
+			t.Parallel()
+			actual, err := parseDate(testCase.Date, loc)
+			if testCase.ExpectedOk {
+				require.NoError(t, err)
+				assert.True(t, testCase.Expected.Equal(actual), "expected %s, got %s", testCase.Expected, actual)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
 func TestRelative(t *testing.T) {
 	t.Parallel()
 	assert.Equal(t, relative(time.Minute), "in 1m")
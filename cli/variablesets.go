@@ -0,0 +1,422 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) variableSets() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "variable-sets",
+		Short: "Manage Terraform variable values shared by templates",
+		Long: "Variable sets are named sets of Terraform variable values, such as the IDs of shared infrastructure. " +
+			"The values of the sets attached to a template are passed to its provisioner jobs, " +
+			"overriding the values set on the template version.\n" + FormatExamples(
+			Example{
+				Description: "Create a variable set with a secret variable",
+				Command:     "coder variable-sets create aws --var vpc_id=vpc-1 --secret-var token",
+			},
+			Example{
+				Description: "Pass the variables of the set to the builds of a template",
+				Command:     "coder variable-sets attach aws --template my-template",
+			},
+		),
+		Aliases: []string{"variable-set"},
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.variableSetsAttach(),
+			r.variableSetsCreate(),
+			r.variableSetsDelete(),
+			r.variableSetsDetach(),
+			r.variableSetsList(),
+			r.variableSetsShow(),
+			r.variableSetsUpdate(),
+		},
+	}
+	return cmd
+}
+
+type variableSetListRow struct {
+	Name        string    `json:"name" table:"name,default_sort"`
+	Description string    `json:"description" table:"description"`
+	Variables   int       `json:"variables" table:"variables"`
+	UpdatedAt   time.Time `json:"updated_at" table:"updated at"`
+}
+
+type variableSetVariableRow struct {
+	Name   string `json:"name" table:"name,default_sort"`
+	Value  string `json:"value" table:"value"`
+	Secret bool   `json:"secret" table:"secret"`
+}
+
+// variableSetVariableOptions returns the options used to set the variables
+// of a variable set.
+func variableSetVariableOptions(vars, secretVars *[]string) serpent.OptionSet {
+	return serpent.OptionSet{
+		{
+			Flag:        "var",
+			Description: "A variable of the set, in the format name=value. Can be specified multiple times.",
+			Value:       serpent.StringArrayOf(vars),
+		},
+		{
+			Flag: "secret-var",
+			Description: "A secret variable of the set, in the format name=value. The value is read from stdin " +
+				"when only the name is given. Can be specified multiple times.",
+			Value: serpent.StringArrayOf(secretVars),
+		},
+	}
+}
+
+// parseVariableSetVariables parses the variables passed with --var and
+// --secret-var.
+func parseVariableSetVariables(inv *serpent.Invocation, vars, secretVars []string) ([]codersdk.VariableSetVariable, error) {
+	parsed := make([]codersdk.VariableSetVariable, 0, len(vars)+len(secretVars))
+	for _, v := range vars {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok {
+			return nil, xerrors.Errorf("variable %q must be in the format name=value", v)
+		}
+		parsed = append(parsed, codersdk.VariableSetVariable{Name: name, Value: value})
+	}
+	for _, v := range secretVars {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok {
+			var err error
+			value, err = readSecretValue(inv, "")
+			if err != nil {
+				return nil, xerrors.Errorf("read value of %q: %w", name, err)
+			}
+		}
+		parsed = append(parsed, codersdk.VariableSetVariable{Name: name, Value: value, Secret: true})
+	}
+	return parsed, nil
+}
+
+func (r *RootCmd) variableSetsList() *serpent.Command {
+	var (
+		template   string
+		orgContext = NewOrganizationContext()
+		formatter  = cliui.NewOutputFormatter(
+			cliui.TableFormat([]variableSetListRow{}, []string{"name", "description", "variables", "updated at"}),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List variable sets",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			var (
+				sets []codersdk.VariableSet
+				err  error
+			)
+			if template != "" {
+				templateID, err := secretsTemplateID(inv, client, orgContext, template)
+				if err != nil {
+					return err
+				}
+				sets, err = client.TemplateVariableSets(inv.Context(), templateID)
+				if err != nil {
+					return xerrors.Errorf("list template variable sets: %w", err)
+				}
+			} else {
+				sets, err = client.VariableSets(inv.Context())
+				if err != nil {
+					return xerrors.Errorf("list variable sets: %w", err)
+				}
+			}
+
+			if len(sets) == 0 {
+				cliui.Infof(inv.Stderr, "No variable sets found.")
+				return nil
+			}
+
+			rows := make([]variableSetListRow, 0, len(sets))
+			for _, set := range sets {
+				rows = append(rows, variableSetListRow{
+					Name:        set.Name,
+					Description: set.Description,
+					Variables:   len(set.Variables),
+					UpdatedAt:   set.UpdatedAt,
+				})
+			}
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+		Options: serpent.OptionSet{
+			{
+				Flag:          "template",
+				FlagShorthand: "t",
+				Description:   "Only list the variable sets attached to this template.",
+				Value:         serpent.StringOf(&template),
+			},
+		},
+	}
+	orgContext.AttachOptions(cmd)
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) variableSetsShow() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]variableSetVariableRow{}, []string{"name", "value", "secret"}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "show <name>",
+		Short: "Show the variables of a variable set",
+		Long:  "Show the variables of a variable set. The values of secret variables are not shown.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			set, err := client.VariableSet(inv.Context(), inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get variable set: %w", err)
+			}
+			if len(set.Variables) == 0 {
+				cliui.Infof(inv.Stderr, "Variable set %s has no variables.", set.Name)
+				return nil
+			}
+
+			rows := make([]variableSetVariableRow, 0, len(set.Variables))
+			for _, v := range set.Variables {
+				rows = append(rows, variableSetVariableRow(v))
+			}
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) variableSetsCreate() *serpent.Command {
+	var (
+		description string
+		vars        []string
+		secretVars  []string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "create <name>",
+		Short: "Create a variable set",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			name := inv.Args[0]
+			variables, err := parseVariableSetVariables(inv, vars, secretVars)
+			if err != nil {
+				return err
+			}
+			_, err = client.CreateVariableSet(inv.Context(), codersdk.CreateVariableSetRequest{
+				Name:        name,
+				Description: description,
+				Variables:   variables,
+			})
+			if err != nil {
+				return xerrors.Errorf("create variable set: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Variable set %s has been created.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, name))
+			return nil
+		},
+		Options: append(serpent.OptionSet{
+			{
+				Flag:        "description",
+				Description: "A description of the variable set.",
+				Value:       serpent.StringOf(&description),
+			},
+		}, variableSetVariableOptions(&vars, &secretVars)...),
+	}
+	return cmd
+}
+
+func (r *RootCmd) variableSetsUpdate() *serpent.Command {
+	var (
+		description string
+		vars        []string
+		secretVars  []string
+		unset       []string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "update <name>",
+		Short: "Update a variable set",
+		Long:  "Update a variable set. Variables that are passed are set, or replaced if they exist. Other variables are left unchanged unless they are removed with --unset.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			name := inv.Args[0]
+			variables, err := parseVariableSetVariables(inv, vars, secretVars)
+			if err != nil {
+				return err
+			}
+			req := codersdk.UpdateVariableSetRequest{
+				Variables:       variables,
+				RemoveVariables: unset,
+			}
+			if inv.ParsedFlags().Changed("description") {
+				req.Description = &description
+			}
+			_, err = client.UpdateVariableSet(inv.Context(), name, req)
+			if err != nil {
+				return xerrors.Errorf("update variable set: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Variable set %s has been updated.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, name))
+			return nil
+		},
+		Options: append(serpent.OptionSet{
+			{
+				Flag:        "description",
+				Description: "A description of the variable set.",
+				Value:       serpent.StringOf(&description),
+			},
+			{
+				Flag:        "unset",
+				Description: "The name of a variable to remove from the set. Can be specified multiple times.",
+				Value:       serpent.StringArrayOf(&unset),
+			},
+		}, variableSetVariableOptions(&vars, &secretVars)...),
+	}
+	return cmd
+}
+
+func (r *RootCmd) variableSetsDelete() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "delete <name>",
+		Short: "Delete a variable set and detach it from all templates",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			name := inv.Args[0]
+			_, err := cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Delete variable set %s? Templates it is attached to will no longer receive its values.", pretty.Sprint(cliui.DefaultStyles.Keyword, name)),
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+			err = client.DeleteVariableSet(inv.Context(), name)
+			if err != nil {
+				return xerrors.Errorf("delete variable set: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Variable set %s has been deleted.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, name))
+			return nil
+		},
+		Options: serpent.OptionSet{cliui.SkipPromptOption()},
+	}
+	return cmd
+}
+
+func (r *RootCmd) variableSetsAttach() *serpent.Command {
+	var (
+		template   string
+		orgContext = NewOrganizationContext()
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "attach <name>",
+		Short: "Attach a variable set to a template",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			name := inv.Args[0]
+			templateID, err := secretsTemplateID(inv, client, orgContext, template)
+			if err != nil {
+				return err
+			}
+			err = client.AttachTemplateVariableSet(inv.Context(), templateID, name)
+			if err != nil {
+				return xerrors.Errorf("attach variable set: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Variable set %s has been attached to template %s.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, name), pretty.Sprint(cliui.DefaultStyles.Keyword, template))
+			return nil
+		},
+		Options: serpent.OptionSet{
+			{
+				Flag:          "template",
+				FlagShorthand: "t",
+				Description:   "The template to attach the variable set to.",
+				Value:         serpent.StringOf(&template),
+				Required:      true,
+			},
+		},
+	}
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
+
+func (r *RootCmd) variableSetsDetach() *serpent.Command {
+	var (
+		template   string
+		orgContext = NewOrganizationContext()
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "detach <name>",
+		Short: "Detach a variable set from a template",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			name := inv.Args[0]
+			templateID, err := secretsTemplateID(inv, client, orgContext, template)
+			if err != nil {
+				return err
+			}
+			err = client.DetachTemplateVariableSet(inv.Context(), templateID, name)
+			if err != nil {
+				return xerrors.Errorf("detach variable set: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Variable set %s has been detached from template %s.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, name), pretty.Sprint(cliui.DefaultStyles.Keyword, template))
+			return nil
+		},
+		Options: serpent.OptionSet{
+			{
+				Flag:          "template",
+				FlagShorthand: "t",
+				Description:   "The template to detach the variable set from.",
+				Value:         serpent.StringOf(&template),
+				Required:      true,
+			},
+		},
+	}
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
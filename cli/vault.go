@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) vault() *serpent.Command {
+	return &serpent.Command{
+		Use:   "vault",
+		Short: "Access HashiCorp Vault from inside a workspace",
+		Long:  "Request HashiCorp Vault tokens scoped to the workspace owner. These commands must be run from inside a running workspace.",
+		Handler: func(i *serpent.Invocation) error {
+			return i.Command.HelpHandler(i)
+		},
+		Children: []*serpent.Command{
+			r.vaultToken(),
+			r.vaultTokenHelper(),
+		},
+	}
+}
+
+func (r *RootCmd) vaultToken() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.ChangeFormatterData(cliui.TextFormat(), func(data any) (any, error) {
+			token, ok := data.(agentsdk.VaultToken)
+			if !ok {
+				return nil, xerrors.Errorf("expected agentsdk.VaultToken, got %T", data)
+			}
+			return token.Token, nil
+		}),
+		cliui.JSONFormat(),
+	)
+	cmd := &serpent.Command{
+		Use:   "token",
+		Short: "Print a Vault token for the workspace owner",
+		Long: "Print a Vault token scoped to the workspace owner. Tokens are short-lived " +
+			"and renewed by Coder, so request a new token instead of storing it.\n" + FormatExamples(
+			Example{
+				Description: "Authenticate the Vault CLI in the current shell",
+				Command:     "export VAULT_TOKEN=\"$(coder vault token)\"",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			token, err := r.fetchVaultToken(inv)
+			if err != nil {
+				return err
+			}
+			out, err := formatter.Format(inv.Context(), token)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) vaultTokenHelper() *serpent.Command {
+	return &serpent.Command{
+		Use:   "token-helper <get|store|erase>",
+		Short: "Act as a Vault CLI token helper",
+		Long: "Implement the Vault CLI token helper protocol, so every vault command " +
+			"uses a fresh token for the workspace owner. Tokens cannot be stored or erased, " +
+			"so store and erase are ignored.\n" + FormatExamples(
+			Example{
+				Description: "Wrapper script to set as token_helper in ~/.vault",
+				Command:     "#!/bin/sh\nexec coder vault token-helper \"$@\"",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			switch inv.Args[0] {
+			case "get":
+				token, err := r.fetchVaultToken(inv)
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprint(inv.Stdout, token.Token)
+				return err
+			case "store":
+				// The Vault CLI passes the token on stdin, which must be
+				// drained.
+				_, _ = io.Copy(io.Discard, inv.Stdin)
+				return nil
+			case "erase":
+				return nil
+			default:
+				return xerrors.Errorf("unknown token helper operation %q", inv.Args[0])
+			}
+		},
+	}
+}
+
+func (r *RootCmd) fetchVaultToken(inv *serpent.Invocation) (agentsdk.VaultToken, error) {
+	if r.agentToken == "" {
+		_, _ = fmt.Fprint(inv.Stderr, pretty.Sprintf(headLineStyle(), "No agent token found, this command must be run from inside a running workspace.\n"))
+		return agentsdk.VaultToken{}, xerrors.Errorf("agent token not found")
+	}
+	client, err := r.tryCreateAgentClient()
+	if err != nil {
+		return agentsdk.VaultToken{}, xerrors.Errorf("create agent client: %w", err)
+	}
+	token, err := client.VaultToken(inv.Context())
+	if err != nil {
+		return agentsdk.VaultToken{}, xerrors.Errorf("get vault token: %w", err)
+	}
+	return token, nil
+}
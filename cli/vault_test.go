@@ -0,0 +1,77 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestVault(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(t *testing.T) string {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v2/workspaceagents/me/vault-token", r.URL.Path)
+			httpapi.Write(context.Background(), w, http.StatusOK, agentsdk.VaultToken{
+				Address: "https://vault.example.com",
+				Token:   "hvs.bananas",
+			})
+		}))
+		t.Cleanup(srv.Close)
+		return srv.URL
+	}
+
+	t.Run("Token", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		inv, _ := clitest.New(t, "--agent-url", newServer(t), "--agent-token", "foo", "vault", "token")
+		var buf bytes.Buffer
+		inv.Stdout = &buf
+		require.NoError(t, inv.WithContext(ctx).Run())
+		require.Equal(t, "hvs.bananas\n", buf.String())
+	})
+
+	t.Run("TokenJSON", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		inv, _ := clitest.New(t, "--agent-url", newServer(t), "--agent-token", "foo", "vault", "token", "--output", "json")
+		var buf bytes.Buffer
+		inv.Stdout = &buf
+		require.NoError(t, inv.WithContext(ctx).Run())
+		require.Contains(t, buf.String(), `"address": "https://vault.example.com"`)
+	})
+
+	t.Run("TokenHelper", func(t *testing.T) {
+		t.Parallel()
+		url := newServer(t)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		inv, _ := clitest.New(t, "--agent-url", url, "--agent-token", "foo", "vault", "token-helper", "get")
+		var buf bytes.Buffer
+		inv.Stdout = &buf
+		require.NoError(t, inv.WithContext(ctx).Run())
+		require.Equal(t, "hvs.bananas", buf.String())
+
+		inv, _ = clitest.New(t, "--agent-url", url, "--agent-token", "foo", "vault", "token-helper", "store")
+		inv.Stdin = strings.NewReader("hvs.other")
+		require.NoError(t, inv.WithContext(ctx).Run())
+	})
+
+	t.Run("NoAgentToken", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		inv, _ := clitest.New(t, "vault", "token")
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "agent token not found")
+	})
+}
@@ -21,6 +21,7 @@ type versionInfo struct {
 	Slim         bool      `json:"slim"`
 	AGPL         bool      `json:"agpl"`
 	BoringCrypto bool      `json:"boring_crypto"`
+	FIPS         bool      `json:"fips"`
 }
 
 // String() implements Stringer
@@ -34,6 +35,9 @@ func (vi versionInfo) String() string {
 	if vi.BoringCrypto {
 		_, _ = str.WriteString(" BoringCrypto")
 	}
+	if vi.FIPS {
+		_, _ = str.WriteString(" FIPS")
+	}
 
 	if !vi.BuildTime.IsZero() {
 		_, _ = str.WriteString(" " + vi.BuildTime.Format(time.UnixDate))
@@ -57,6 +61,7 @@ func defaultVersionInfo() *versionInfo {
 		Slim:         buildinfo.IsSlim(),
 		AGPL:         buildinfo.IsAGPL(),
 		BoringCrypto: buildinfo.IsBoringCrypto(),
+		FIPS:         buildinfo.IsFIPS(),
 	}
 }
 
@@ -26,7 +26,8 @@ Full build of Coder, supports the server subcommand.
   "external_url": "https://github.com/coder/coder",
   "slim": false,
   "agpl": false,
-  "boring_crypto": false
+  "boring_crypto": false,
+  "fips": false
 }
 `
 	for _, tt := range []struct {
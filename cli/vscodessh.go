@@ -38,6 +38,7 @@ func (r *RootCmd) vscodeSSH() *serpent.Command {
 		networkInfoDir      string
 		networkInfoInterval time.Duration
 		waitEnum            string
+		startOnConnect      bool
 	)
 	cmd := &serpent.Command{
 		// A SSH config entry is added by the VS Code extension that
@@ -98,11 +99,10 @@ func (r *RootCmd) vscodeSSH() *serpent.Command {
 				name += "." + parts[3]
 			}
 
-			// Set autostart to false because it's assumed the VS Code extension
-			// will call this command after the workspace is started.
-			autostart := false
-
-			workspace, workspaceAgent, _, err := getWorkspaceAndAgent(ctx, inv, client, autostart, fmt.Sprintf("%s/%s", owner, name))
+			// By default it's assumed the VS Code extension will call this
+			// command after the workspace is started. Extensions that leave
+			// starting the workspace to this command pass --start-on-connect.
+			workspace, workspaceAgent, _, err := getWorkspaceAndAgent(ctx, inv, client, startOnConnect, fmt.Sprintf("%s/%s", owner, name))
 			if err != nil {
 				return xerrors.Errorf("find workspace and agent: %w", err)
 			}
@@ -241,6 +241,11 @@ func (r *RootCmd) vscodeSSH() *serpent.Command {
 			Default:     "auto",
 			Value:       serpent.EnumOf(&waitEnum, "yes", "no", "auto"),
 		},
+		{
+			Flag:        "start-on-connect",
+			Description: "Start the workspace if it is stopped, unless the template or the connection settings of the user disable it.",
+			Value:       serpent.BoolOf(&startOnConnect),
+		},
 	}
 	return cmd
 }
@@ -0,0 +1,188 @@
+package coderd
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw/loggermw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/wsjson"
+	"github.com/coder/websocket"
+)
+
+const (
+	// activityFeedDefaultLimit is the page size of the activity feed when no
+	// limit is given, since the feed spans several tables.
+	activityFeedDefaultLimit = 100
+	// activityFeedPollInterval is how often watchers check for new events.
+	// Events come from audit logs, builds and agents on every replica, so
+	// the database is the only place to see them all.
+	activityFeedPollInterval = 2 * time.Second
+	// activityFeedWatchBatch is the most events read per poll.
+	activityFeedWatchBatch = 100
+)
+
+// @Summary Get activity feed
+// @Description Returns audit events, workspace builds and workspace agent
+// @Description lifecycle transitions as a single feed, newest first.
+// @ID get-activity-feed
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Audit
+// @Param types query string false "Comma-separated event types: audit, build, lifecycle"
+// @Param organization_id query string false "Organization ID" format(uuid)
+// @Param workspace_id query string false "Workspace ID" format(uuid)
+// @Param user_id query string false "User ID" format(uuid)
+// @Param after_id query string false "Return events older than this event" format(uuid)
+// @Param limit query int false "Page limit"
+// @Success 200 {object} codersdk.ActivityFeedResponse
+// @Router /activity [get]
+func (api *API) activityFeed(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vals := r.URL.Query()
+	p := httpapi.NewQueryParamParser()
+	params := parseActivityFeedFilter(p, vals)
+	params.AfterID = p.UUID(vals, uuid.Nil, "after_id")
+	params.LimitOpt = p.PositiveInt32(vals, activityFeedDefaultLimit, "limit")
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	rows, err := api.Database.GetActivityEvents(ctx, params)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.ActivityFeedResponse{
+		Events: db2sdk.List(rows, db2sdk.ActivityEvent),
+	})
+}
+
+// @Summary Watch activity feed
+// @Description Streams the activity feed events that happen after the
+// @Description connection is opened, oldest first, over a websocket.
+// @ID watch-activity-feed
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Audit
+// @Param types query string false "Comma-separated event types: audit, build, lifecycle"
+// @Param organization_id query string false "Organization ID" format(uuid)
+// @Param workspace_id query string false "Workspace ID" format(uuid)
+// @Param user_id query string false "User ID" format(uuid)
+// @Success 200 {object} codersdk.ActivityEvent
+// @Router /activity/watch [get]
+func (api *API) watchActivityFeed(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vals := r.URL.Query()
+	p := httpapi.NewQueryParamParser()
+	params := parseActivityFeedFilter(p, vals)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	// The newest event is the cursor, so only later events are sent.
+	latest := params
+	latest.LimitOpt = 1
+	rows, err := api.Database.GetActivityEvents(ctx, latest)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if len(rows) > 0 {
+		params.NewerThanID = rows[0].ID
+	}
+
+	conn, err := websocket.Accept(rw, r, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to upgrade connection to websocket.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	go httpapi.Heartbeat(ctx, conn)
+	defer conn.Close(websocket.StatusNormalClosure, "connection closed")
+
+	encoder := wsjson.NewEncoder[codersdk.ActivityEvent](conn, websocket.MessageText)
+	defer encoder.Close(websocket.StatusNormalClosure)
+
+	// Log the request immediately instead of after it completes.
+	if rl := loggermw.RequestLoggerFromContext(ctx); rl != nil {
+		rl.WriteLog(ctx, http.StatusAccepted)
+	}
+
+	ticker := api.Clock.NewTicker(activityFeedPollInterval, "activityFeed", "watch")
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			batch := params
+			batch.LimitOpt = activityFeedWatchBatch
+			rows, err := api.Database.GetActivityEvents(ctx, batch)
+			if err != nil {
+				if ctx.Err() == nil {
+					api.Logger.Error(ctx, "failed to get activity events", slog.Error(err))
+				}
+				return
+			}
+			if params.NewerThanID == uuid.Nil {
+				// Without a cursor the feed is newest first.
+				slices.Reverse(rows)
+			}
+			for _, row := range rows {
+				if err := encoder.Encode(db2sdk.ActivityEvent(row)); err != nil {
+					return
+				}
+				params.NewerThanID = row.ID
+			}
+			if len(rows) < activityFeedWatchBatch {
+				break
+			}
+		}
+	}
+}
+
+// parseActivityFeedFilter parses the filters shared by the activity feed
+// endpoints.
+func parseActivityFeedFilter(p *httpapi.QueryParamParser, vals url.Values) database.GetActivityEventsParams {
+	types := httpapi.ParseCustomList(p, vals, []codersdk.ActivityEventType{}, "types", httpapi.ParseEnum[codersdk.ActivityEventType])
+	return database.GetActivityEventsParams{
+		Types:          db2sdk.List(types, func(t codersdk.ActivityEventType) string { return string(t) }),
+		OrganizationID: p.UUID(vals, uuid.Nil, "organization_id"),
+		WorkspaceID:    p.UUID(vals, uuid.Nil, "workspace_id"),
+		UserID:         p.UUID(vals, uuid.Nil, "user_id"),
+	}
+}
@@ -0,0 +1,156 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestActivityFeed(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        owner.UserID,
+		}).WithAgent().Do()
+		agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		err = db.UpdateWorkspaceAgentLifecycleStateByID(ctx, database.UpdateWorkspaceAgentLifecycleStateByIDParams{
+			ID:             agents[0].ID,
+			LifecycleState: database.WorkspaceAgentLifecycleStateReady,
+		})
+		require.NoError(t, err)
+		err = client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+			ResourceID:     owner.UserID,
+			OrganizationID: owner.OrganizationID,
+		})
+		require.NoError(t, err)
+
+		feed, err := client.ActivityFeed(ctx, codersdk.ActivityFeedFilter{}, codersdk.Pagination{})
+		require.NoError(t, err)
+		require.Len(t, feed.Events, 3)
+		types := map[codersdk.ActivityEventType]codersdk.ActivityEvent{}
+		for i, event := range feed.Events {
+			if i > 0 {
+				require.False(t, event.Time.After(feed.Events[i-1].Time), "events are newest first")
+			}
+			types[event.Type] = event
+		}
+		require.Equal(t, r.Build.ID, types[codersdk.ActivityEventTypeBuild].ResourceID)
+		require.Equal(t, string(database.WorkspaceTransitionStart), types[codersdk.ActivityEventTypeBuild].Action)
+		require.Equal(t, &r.Workspace.ID, types[codersdk.ActivityEventTypeBuild].WorkspaceID)
+		require.Equal(t, agents[0].ID, types[codersdk.ActivityEventTypeLifecycle].ResourceID)
+		require.Equal(t, string(codersdk.WorkspaceAgentLifecycleReady), types[codersdk.ActivityEventTypeLifecycle].Action)
+		require.Equal(t, owner.UserID, types[codersdk.ActivityEventTypeLifecycle].UserID)
+		require.Equal(t, owner.UserID, types[codersdk.ActivityEventTypeAudit].ResourceID)
+
+		// Filters.
+		feed, err = client.ActivityFeed(ctx, codersdk.ActivityFeedFilter{
+			Types: []codersdk.ActivityEventType{codersdk.ActivityEventTypeBuild, codersdk.ActivityEventTypeLifecycle},
+		}, codersdk.Pagination{})
+		require.NoError(t, err)
+		require.Len(t, feed.Events, 2)
+		feed, err = client.ActivityFeed(ctx, codersdk.ActivityFeedFilter{WorkspaceID: r.Workspace.ID}, codersdk.Pagination{})
+		require.NoError(t, err)
+		require.Len(t, feed.Events, 2)
+		feed, err = client.ActivityFeed(ctx, codersdk.ActivityFeedFilter{OrganizationID: uuid.New()}, codersdk.Pagination{})
+		require.NoError(t, err)
+		require.Empty(t, feed.Events)
+
+		// Cursor pagination.
+		var paged []codersdk.ActivityEvent
+		page := codersdk.Pagination{Limit: 1}
+		for {
+			feed, err = client.ActivityFeed(ctx, codersdk.ActivityFeedFilter{}, page)
+			require.NoError(t, err)
+			if len(feed.Events) == 0 {
+				break
+			}
+			require.Len(t, feed.Events, 1)
+			paged = append(paged, feed.Events[0])
+			page.AfterID = feed.Events[0].ID
+		}
+		require.Len(t, paged, 3)
+		require.Equal(t, types[paged[0].Type], paged[0])
+	})
+
+	t.Run("InvalidType", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		_, err := client.ActivityFeed(ctx, codersdk.ActivityFeedFilter{
+			Types: []codersdk.ActivityEventType{"unknown"},
+		}, codersdk.Pagination{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		_, err := member.ActivityFeed(ctx, codersdk.ActivityFeedFilter{}, codersdk.Pagination{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		clock := quartz.NewMock(t)
+		trap := clock.Trap().NewTicker("activityFeed", "watch")
+		defer trap.Close()
+		client := coderdtest.New(t, &coderdtest.Options{Clock: clock})
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		// Events from before the connection are not streamed.
+		err := client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+			ResourceID:     owner.UserID,
+			OrganizationID: owner.OrganizationID,
+		})
+		require.NoError(t, err)
+
+		events, closer, err := client.WatchActivityFeed(ctx, codersdk.ActivityFeedFilter{
+			Types: []codersdk.ActivityEventType{codersdk.ActivityEventTypeAudit},
+		})
+		require.NoError(t, err)
+		defer closer.Close()
+		trap.MustWait(ctx).MustRelease(ctx)
+
+		resourceID := uuid.New()
+		err = client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+			ResourceID:     resourceID,
+			OrganizationID: owner.OrganizationID,
+		})
+		require.NoError(t, err)
+		clock.Advance(2 * time.Second).MustWait(ctx)
+
+		event := testutil.RequireReceive(ctx, t, events)
+		require.Equal(t, codersdk.ActivityEventTypeAudit, event.Type)
+		require.Equal(t, resourceID, event.ResourceID)
+	})
+}
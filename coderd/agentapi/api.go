@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/xerrors"
 	"storj.io/drpc/drpcmux"
 	"storj.io/drpc/drpcserver"
@@ -79,19 +80,30 @@ type Options struct {
 	PublishWorkspaceUpdateFn          func(ctx context.Context, userID uuid.UUID, event wspubsub.WorkspaceEvent)
 	PublishWorkspaceAgentLogsUpdateFn func(ctx context.Context, workspaceAgentID uuid.UUID, msg agentsdk.LogsNotifyMessage)
 	NetworkTelemetryHandler           func(batch []*tailnetproto.TelemetryEvent)
+	TracerProvider                    trace.TracerProvider
 
 	AccessURL                 *url.URL
 	AppHostname               string
 	AgentStatsRefreshInterval time.Duration
 	DisableDirectConnections  bool
 	DerpForceWebSockets       bool
+	NetcheckInterval          time.Duration
+	Wireguard                 codersdk.WireguardConfig
 	DerpMapUpdateFrequency    time.Duration
 	ExternalAuthConfigs       []*externalauth.Config
-	Experiments               codersdk.Experiments
+	Experiments               Experiments
 
 	UpdateAgentMetricsFn func(ctx context.Context, labels prometheusmetrics.AgentMetricLabels, metrics []*agentproto.Stats_Metric)
 }
 
+// Experiments reports whether an experiment is enabled for the actor of the
+// context, which is the workspace owner for agent requests. It is satisfied by
+// *experimentflags.Evaluator, so flags changed at runtime apply without
+// reconnecting the agent.
+type Experiments interface {
+	EnabledFor(ctx context.Context, ex codersdk.Experiment) bool
+}
+
 func New(opts Options) *API {
 	if opts.Clock == nil {
 		opts.Clock = quartz.NewReal()
@@ -108,6 +120,8 @@ func New(opts Options) *API {
 		ExternalAuthConfigs:      opts.ExternalAuthConfigs,
 		DisableDirectConnections: opts.DisableDirectConnections,
 		DerpForceWebSockets:      opts.DerpForceWebSockets,
+		NetcheckInterval:         opts.NetcheckInterval,
+		Wireguard:                opts.Wireguard,
 		AgentFn:                  api.agent,
 		Database:                 opts.Database,
 		DerpMapFn:                opts.DerpMapFn,
@@ -153,6 +167,9 @@ func New(opts Options) *API {
 		Log:                      opts.Log,
 		PublishWorkspaceUpdateFn: api.publishWorkspaceUpdate,
 	}
+	if opts.TracerProvider != nil {
+		api.LifecycleAPI.Tracer = opts.TracerProvider.Tracer(tracing.TracerName)
+	}
 
 	api.AppsAPI = &AppsAPI{
 		AgentFn:                  api.agent,
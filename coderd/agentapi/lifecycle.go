@@ -3,10 +3,14 @@ package agentapi
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/mod/semver"
 	"golang.org/x/xerrors"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -14,7 +18,9 @@ import (
 	"cdr.dev/slog"
 	agentproto "github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/wspubsub"
 )
 
@@ -30,6 +36,9 @@ type LifecycleAPI struct {
 	Database                 database.Store
 	Log                      slog.Logger
 	PublishWorkspaceUpdateFn func(context.Context, *database.WorkspaceAgent, wspubsub.WorkspaceEventKind) error
+	// Tracer records the startup of the agent as part of the trace of the
+	// build that created it. Optional.
+	Tracer trace.Tracer
 
 	TimeNowFn func() time.Time // defaults to dbtime.Now()
 }
@@ -125,9 +134,98 @@ func (a *LifecycleAPI) UpdateLifecycle(ctx context.Context, req *agentproto.Upda
 		}
 	}
 
+	if a.Tracer != nil && readyAt.Valid && !workspaceAgent.ReadyAt.Valid {
+		err = a.traceStartup(ctx, workspaceAgent, lifecycleState, startedAt.Time, readyAt.Time)
+		if err != nil {
+			logger.Warn(ctx, "failed to trace workspace agent startup", slog.Error(err))
+		}
+	}
+
 	return req.Lifecycle, nil
 }
 
+// traceStartup records the startup of the agent in the trace of the build
+// that created it, so the trace of a build covers everything from the API
+// request until the workspace is ready. The spans are only recorded when the
+// build was traced.
+func (a *LifecycleAPI) traceStartup(ctx context.Context, workspaceAgent database.WorkspaceAgent, state database.WorkspaceAgentLifecycleState, startedAt, readyAt time.Time) error {
+	//nolint:gocritic // The agent is not allowed to read the build job.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	resource, err := a.Database.GetWorkspaceResourceByID(sysCtx, workspaceAgent.ResourceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace resource: %w", err)
+	}
+	job, err := a.Database.GetProvisionerJobByID(sysCtx, resource.JobID)
+	if err != nil {
+		return xerrors.Errorf("get provisioner job: %w", err)
+	}
+	if !job.TraceMetadata.Valid {
+		return nil
+	}
+	var metadata map[string]string
+	err = json.Unmarshal(job.TraceMetadata.RawMessage, &metadata)
+	if err != nil {
+		return xerrors.Errorf("unmarshal trace metadata: %w", err)
+	}
+	// The spans belong to the trace of the build, not to the RPC of the
+	// agent, so they are started from a context without its span.
+	traceCtx := tracing.MetadataToContext(context.Background(), metadata)
+	if !trace.SpanContextFromContext(traceCtx).IsValid() {
+		return nil
+	}
+	build, err := a.Database.GetWorkspaceBuildByJobID(sysCtx, job.ID)
+	if err != nil {
+		return xerrors.Errorf("get workspace build: %w", err)
+	}
+	timings, err := a.Database.GetWorkspaceAgentScriptTimingsByBuildID(sysCtx, build.ID)
+	if err != nil {
+		return xerrors.Errorf("get workspace agent script timings: %w", err)
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("workspace_id", a.WorkspaceID.String()),
+		attribute.String("workspace_build_id", build.ID.String()),
+		attribute.String("agent_id", workspaceAgent.ID.String()),
+		attribute.String("agent_name", workspaceAgent.Name),
+	}
+
+	if job.CompletedAt.Valid && workspaceAgent.FirstConnectedAt.Valid {
+		_, span := a.Tracer.Start(traceCtx, "workspace agent connect",
+			trace.WithTimestamp(job.CompletedAt.Time),
+			trace.WithAttributes(attrs...),
+		)
+		span.End(trace.WithTimestamp(workspaceAgent.FirstConnectedAt.Time))
+	}
+
+	startupCtx, span := a.Tracer.Start(traceCtx, "workspace agent startup",
+		trace.WithTimestamp(startedAt),
+		trace.WithAttributes(append(attrs, attribute.String("lifecycle_state", string(state)))...),
+	)
+	if state != database.WorkspaceAgentLifecycleStateReady {
+		span.SetStatus(codes.Error, string(state))
+	}
+	for _, timing := range timings {
+		if timing.WorkspaceAgentID != workspaceAgent.ID || timing.Stage != database.WorkspaceAgentScriptTimingStageStart {
+			continue
+		}
+		_, scriptSpan := a.Tracer.Start(startupCtx, "workspace agent script",
+			trace.WithTimestamp(timing.StartedAt),
+			trace.WithAttributes(
+				attribute.String("script_id", timing.ScriptID.String()),
+				attribute.String("script_name", timing.DisplayName),
+				attribute.String("status", string(timing.Status)),
+				attribute.Int("exit_code", int(timing.ExitCode)),
+			),
+		)
+		if timing.Status != database.WorkspaceAgentScriptTimingStatusOk {
+			scriptSpan.SetStatus(codes.Error, string(timing.Status))
+		}
+		scriptSpan.End(trace.WithTimestamp(timing.EndedAt))
+	}
+	span.End(trace.WithTimestamp(readyAt))
+	return nil
+}
+
 func (a *LifecycleAPI) UpdateStartup(ctx context.Context, req *agentproto.UpdateStartupRequest) (*agentproto.Startup, error) {
 	apiVersion, ok := ctx.Value(contextKeyAPIVersion{}).(string)
 	if !ok {
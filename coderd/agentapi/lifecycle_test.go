@@ -3,13 +3,21 @@ package agentapi_test
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/mock/gomock"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -18,6 +26,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbmock"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/wspubsub"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -170,6 +179,103 @@ func TestUpdateLifecycle(t *testing.T) {
 		require.True(t, publishCalled)
 	})
 
+	t.Run("TraceStartup", func(t *testing.T) {
+		t.Parallel()
+
+		// Trace metadata is read with the global propagator, which the server
+		// configures on startup.
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		traceID := trace.TraceID{1}
+		buildCtx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     trace.SpanID{1},
+			TraceFlags: trace.FlagsSampled,
+		}))
+		traceMetadata, err := json.Marshal(tracing.MetadataFromContext(buildCtx))
+		require.NoError(t, err)
+
+		var (
+			resourceID = uuid.New()
+			job        = database.ProvisionerJob{
+				ID:            uuid.New(),
+				CompletedAt:   sql.NullTime{Valid: true, Time: someTime.Add(-time.Minute)},
+				TraceMetadata: pqtype.NullRawMessage{Valid: true, RawMessage: traceMetadata},
+			}
+			build = database.WorkspaceBuild{ID: uuid.New(), JobID: job.ID}
+			agent = agentStarting
+		)
+		agent.Name = "dev"
+		agent.ResourceID = resourceID
+		agent.FirstConnectedAt = sql.NullTime{Valid: true, Time: someTime.Add(-time.Second)}
+
+		dbM := dbmock.NewMockStore(gomock.NewController(t))
+		dbM.EXPECT().UpdateWorkspaceAgentLifecycleStateByID(gomock.Any(), gomock.Any()).Return(nil)
+		dbM.EXPECT().GetWorkspaceResourceByID(gomock.Any(), resourceID).Return(database.WorkspaceResource{ID: resourceID, JobID: job.ID}, nil)
+		dbM.EXPECT().GetProvisionerJobByID(gomock.Any(), job.ID).Return(job, nil)
+		dbM.EXPECT().GetWorkspaceBuildByJobID(gomock.Any(), job.ID).Return(build, nil)
+		dbM.EXPECT().GetWorkspaceAgentScriptTimingsByBuildID(gomock.Any(), build.ID).Return([]database.GetWorkspaceAgentScriptTimingsByBuildIDRow{
+			{
+				ScriptID:         uuid.New(),
+				StartedAt:        someTime,
+				EndedAt:          someTime.Add(time.Second),
+				Stage:            database.WorkspaceAgentScriptTimingStageStart,
+				Status:           database.WorkspaceAgentScriptTimingStatusExitFailure,
+				ExitCode:         1,
+				DisplayName:      "Startup Script",
+				WorkspaceAgentID: agent.ID,
+			},
+			{
+				// Scripts of other agents are not part of this startup.
+				ScriptID:         uuid.New(),
+				StartedAt:        someTime,
+				EndedAt:          someTime.Add(time.Second),
+				Stage:            database.WorkspaceAgentScriptTimingStageStart,
+				Status:           database.WorkspaceAgentScriptTimingStatusOk,
+				WorkspaceAgentID: uuid.New(),
+			},
+		}, nil)
+
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		api := &agentapi.LifecycleAPI{
+			AgentFn: func(ctx context.Context) (database.WorkspaceAgent, error) {
+				return agent, nil
+			},
+			WorkspaceID: workspaceID,
+			Database:    dbM,
+			Log:         testutil.Logger(t),
+			Tracer:      provider.Tracer("test"),
+		}
+
+		_, err = api.UpdateLifecycle(context.Background(), &agentproto.UpdateLifecycleRequest{
+			Lifecycle: &agentproto.Lifecycle{
+				State:     agentproto.Lifecycle_START_ERROR,
+				ChangedAt: timestamppb.New(now),
+			},
+		})
+		require.NoError(t, err)
+
+		spans := make(map[string]sdktrace.ReadOnlySpan)
+		for _, span := range recorder.Ended() {
+			require.Equal(t, traceID, span.SpanContext().TraceID())
+			spans[span.Name()] = span
+		}
+		require.Len(t, spans, 3)
+
+		connect := spans["workspace agent connect"]
+		require.Equal(t, job.CompletedAt.Time, connect.StartTime())
+		require.Equal(t, agent.FirstConnectedAt.Time, connect.EndTime())
+
+		startup := spans["workspace agent startup"]
+		require.Equal(t, agent.StartedAt.Time, startup.StartTime())
+		require.Equal(t, now, startup.EndTime())
+		require.Equal(t, codes.Error, startup.Status().Code)
+
+		script := spans["workspace agent script"]
+		require.Equal(t, startup.SpanContext().SpanID(), script.Parent().SpanID())
+		require.Equal(t, codes.Error, script.Status().Code)
+	})
+
 	t.Run("NoTimeSpecified", func(t *testing.T) {
 		t.Parallel()
 
@@ -30,6 +30,8 @@ type ManifestAPI struct {
 	ExternalAuthConfigs      []*externalauth.Config
 	DisableDirectConnections bool
 	DerpForceWebSockets      bool
+	NetcheckInterval         time.Duration
+	Wireguard                codersdk.WireguardConfig
 	WorkspaceID              uuid.UUID
 
 	AgentFn   func(context.Context) (database.WorkspaceAgent, error)
@@ -47,6 +49,7 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		scripts       []database.WorkspaceAgentScript
 		metadata      []database.WorkspaceAgentMetadatum
 		workspace     database.Workspace
+		template      database.Template
 		devcontainers []database.WorkspaceAgentDevcontainer
 	)
 
@@ -75,7 +78,12 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		if err != nil {
 			return xerrors.Errorf("getting workspace by id: %w", err)
 		}
-		return err
+		// nolint:gocritic // This is necessary to fetch the template's WireGuard settings!
+		template, err = a.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+		if err != nil {
+			return xerrors.Errorf("getting template by id: %w", err)
+		}
+		return nil
 	})
 	eg.Go(func() (err error) {
 		devcontainers, err = a.Database.GetWorkspaceAgentDevcontainersByAgentID(ctx, workspaceAgent.ID)
@@ -120,6 +128,8 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		parentID = workspaceAgent.ParentID.UUID[:]
 	}
 
+	wgMTU, wgKeepaliveInterval, wgHandshakeTimeout := db2sdk.WireguardSettings(a.Wireguard, template)
+
 	return &agentproto.Manifest{
 		AgentId:                  workspaceAgent.ID[:],
 		AgentName:                workspaceAgent.Name,
@@ -133,7 +143,12 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		MotdPath:                 workspaceAgent.MOTDFile,
 		DisableDirectConnections: a.DisableDirectConnections,
 		DerpForceWebsockets:      a.DerpForceWebSockets,
-		ParentId:                 parentID,
+		NetcheckInterval:         durationpb.New(a.NetcheckInterval),
+		// #nosec G115 - Safe conversion as MTU values are validated to be at most 65536
+		WireguardMtu:               int32(wgMTU),
+		WireguardKeepaliveInterval: durationpb.New(wgKeepaliveInterval),
+		WireguardHandshakeTimeout:  durationpb.New(wgHandshakeTimeout),
+		ParentId:                   parentID,
 
 		DerpMap:       tailnet.DERPMapToProto(a.DerpMapFn()),
 		Scripts:       dbAgentScriptsToProto(scripts),
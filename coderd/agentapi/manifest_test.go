@@ -16,6 +16,8 @@ import (
 	"google.golang.org/protobuf/types/known/durationpb"
 	"tailscale.com/tailcfg"
 
+	"github.com/coder/serpent"
+
 	agentproto "github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/coderd/agentapi"
 	"github.com/coder/coder/v2/coderd/database"
@@ -45,8 +47,13 @@ func TestGetManifest(t *testing.T) {
 			ID:       uuid.New(),
 			Username: "cool-user",
 		}
+		template = database.Template{
+			ID:           uuid.New(),
+			WireguardMTU: 1420,
+		}
 		workspace = database.Workspace{
 			ID:            uuid.New(),
+			TemplateID:    template.ID,
 			OwnerID:       owner.ID,
 			OwnerUsername: owner.Username,
 			Name:          "cool-workspace",
@@ -320,6 +327,11 @@ func TestGetManifest(t *testing.T) {
 			},
 			DisableDirectConnections: true,
 			DerpForceWebSockets:      true,
+			NetcheckInterval:         time.Minute,
+			Wireguard: codersdk.WireguardConfig{
+				MTU:               1280,
+				KeepaliveInterval: serpent.Duration(10 * time.Second),
+			},
 
 			AgentFn: func(ctx context.Context) (database.WorkspaceAgent, error) {
 				return agent, nil
@@ -337,6 +349,7 @@ func TestGetManifest(t *testing.T) {
 		}).Return(metadata, nil)
 		mDB.EXPECT().GetWorkspaceAgentDevcontainersByAgentID(gomock.Any(), agent.ID).Return(devcontainers, nil)
 		mDB.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
+		mDB.EXPECT().GetTemplateByID(gomock.Any(), template.ID).Return(template, nil)
 
 		got, err := api.GetManifest(context.Background(), &agentproto.GetManifestRequest{})
 		require.NoError(t, err)
@@ -355,6 +368,11 @@ func TestGetManifest(t *testing.T) {
 			MotdPath:                 agent.MOTDFile,
 			DisableDirectConnections: true,
 			DerpForceWebsockets:      true,
+			NetcheckInterval:         durationpb.New(time.Minute),
+			// The template overrides the MTU, but not the keepalive interval.
+			WireguardMtu:               1420,
+			WireguardKeepaliveInterval: durationpb.New(10 * time.Second),
+			WireguardHandshakeTimeout:  durationpb.New(0),
 			// tailnet.DERPMapToProto() is extensively tested elsewhere, so it's
 			// not necessary to manually recreate a big DERP map here like we
 			// did for apps and metadata.
@@ -387,6 +405,11 @@ func TestGetManifest(t *testing.T) {
 			},
 			DisableDirectConnections: true,
 			DerpForceWebSockets:      true,
+			NetcheckInterval:         time.Minute,
+			Wireguard: codersdk.WireguardConfig{
+				MTU:               1280,
+				KeepaliveInterval: serpent.Duration(10 * time.Second),
+			},
 
 			AgentFn: func(ctx context.Context) (database.WorkspaceAgent, error) {
 				return childAgent, nil
@@ -404,24 +427,29 @@ func TestGetManifest(t *testing.T) {
 		}).Return([]database.WorkspaceAgentMetadatum{}, nil)
 		mDB.EXPECT().GetWorkspaceAgentDevcontainersByAgentID(gomock.Any(), childAgent.ID).Return([]database.WorkspaceAgentDevcontainer{}, nil)
 		mDB.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
+		mDB.EXPECT().GetTemplateByID(gomock.Any(), template.ID).Return(template, nil)
 
 		got, err := api.GetManifest(context.Background(), &agentproto.GetManifestRequest{})
 		require.NoError(t, err)
 
 		expected := &agentproto.Manifest{
-			AgentId:                  childAgent.ID[:],
-			AgentName:                childAgent.Name,
-			ParentId:                 agent.ID[:],
-			OwnerUsername:            owner.Username,
-			WorkspaceId:              workspace.ID[:],
-			WorkspaceName:            workspace.Name,
-			GitAuthConfigs:           2, // two "enhanced" external auth configs
-			EnvironmentVariables:     nil,
-			Directory:                childAgent.Directory,
-			VsCodePortProxyUri:       fmt.Sprintf("https://{{port}}--%s--%s--%s--apps.example.com", childAgent.Name, workspace.Name, owner.Username),
-			MotdPath:                 childAgent.MOTDFile,
-			DisableDirectConnections: true,
-			DerpForceWebsockets:      true,
+			AgentId:                    childAgent.ID[:],
+			AgentName:                  childAgent.Name,
+			ParentId:                   agent.ID[:],
+			OwnerUsername:              owner.Username,
+			WorkspaceId:                workspace.ID[:],
+			WorkspaceName:              workspace.Name,
+			GitAuthConfigs:             2, // two "enhanced" external auth configs
+			EnvironmentVariables:       nil,
+			Directory:                  childAgent.Directory,
+			VsCodePortProxyUri:         fmt.Sprintf("https://{{port}}--%s--%s--%s--apps.example.com", childAgent.Name, workspace.Name, owner.Username),
+			MotdPath:                   childAgent.MOTDFile,
+			DisableDirectConnections:   true,
+			DerpForceWebsockets:        true,
+			NetcheckInterval:           durationpb.New(time.Minute),
+			WireguardMtu:               1420,
+			WireguardKeepaliveInterval: durationpb.New(10 * time.Second),
+			WireguardHandshakeTimeout:  durationpb.New(0),
 			// tailnet.DERPMapToProto() is extensively tested elsewhere, so it's
 			// not necessary to manually recreate a big DERP map here like we
 			// did for apps and metadata.
@@ -450,6 +478,11 @@ func TestGetManifest(t *testing.T) {
 			},
 			DisableDirectConnections: true,
 			DerpForceWebSockets:      true,
+			NetcheckInterval:         time.Minute,
+			Wireguard: codersdk.WireguardConfig{
+				MTU:               1280,
+				KeepaliveInterval: serpent.Duration(10 * time.Second),
+			},
 
 			AgentFn: func(ctx context.Context) (database.WorkspaceAgent, error) {
 				return agent, nil
@@ -467,23 +500,28 @@ func TestGetManifest(t *testing.T) {
 		}).Return(metadata, nil)
 		mDB.EXPECT().GetWorkspaceAgentDevcontainersByAgentID(gomock.Any(), agent.ID).Return(devcontainers, nil)
 		mDB.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
+		mDB.EXPECT().GetTemplateByID(gomock.Any(), template.ID).Return(template, nil)
 
 		got, err := api.GetManifest(context.Background(), &agentproto.GetManifestRequest{})
 		require.NoError(t, err)
 
 		expected := &agentproto.Manifest{
-			AgentId:                  agent.ID[:],
-			AgentName:                agent.Name,
-			OwnerUsername:            owner.Username,
-			WorkspaceId:              workspace.ID[:],
-			WorkspaceName:            workspace.Name,
-			GitAuthConfigs:           2, // two "enhanced" external auth configs
-			EnvironmentVariables:     expectedEnvVars,
-			Directory:                agent.Directory,
-			VsCodePortProxyUri:       "", // empty with no AppHost
-			MotdPath:                 agent.MOTDFile,
-			DisableDirectConnections: true,
-			DerpForceWebsockets:      true,
+			AgentId:                    agent.ID[:],
+			AgentName:                  agent.Name,
+			OwnerUsername:              owner.Username,
+			WorkspaceId:                workspace.ID[:],
+			WorkspaceName:              workspace.Name,
+			GitAuthConfigs:             2, // two "enhanced" external auth configs
+			EnvironmentVariables:       expectedEnvVars,
+			Directory:                  agent.Directory,
+			VsCodePortProxyUri:         "", // empty with no AppHost
+			MotdPath:                   agent.MOTDFile,
+			DisableDirectConnections:   true,
+			DerpForceWebsockets:        true,
+			NetcheckInterval:           durationpb.New(time.Minute),
+			WireguardMtu:               1420,
+			WireguardKeepaliveInterval: durationpb.New(10 * time.Second),
+			WireguardHandshakeTimeout:  durationpb.New(0),
 			// tailnet.DERPMapToProto() is extensively tested elsewhere, so it's
 			// not necessary to manually recreate a big DERP map here like we
 			// did for apps and metadata.
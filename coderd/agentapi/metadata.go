@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +15,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
 )
 
 type MetadataAPI struct {
@@ -107,6 +109,25 @@ func (a *MetadataAPI) BatchUpdateMetadata(ctx context.Context, req *agentproto.B
 		)
 	}
 
+	// Metadata pushed by processes in the workspace is not defined by the
+	// template, so it is created the first time it is reported.
+	pushed := database.InsertWorkspaceAgentPushedMetadataParams{
+		WorkspaceAgentID: workspaceAgent.ID,
+		MaxPushed:        agentsdk.MaxPushedMetadata,
+	}
+	for _, key := range dbUpdate.Key {
+		if displayName, ok := strings.CutPrefix(key, agentsdk.PushedMetadataKeyPrefix); ok {
+			pushed.Key = append(pushed.Key, key)
+			pushed.DisplayName = append(pushed.DisplayName, displayName)
+		}
+	}
+	if len(pushed.Key) > 0 {
+		err = a.Database.InsertWorkspaceAgentPushedMetadata(ctx, pushed)
+		if err != nil {
+			return nil, xerrors.Errorf("insert pushed workspace agent metadata in database: %w", err)
+		}
+	}
+
 	err = a.Database.UpdateWorkspaceAgentMetadata(ctx, dbUpdate)
 	if err != nil {
 		return nil, xerrors.Errorf("update workspace agent metadata in database: %w", err)
@@ -18,6 +18,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbmock"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -272,4 +273,60 @@ func TestBatchUpdateMetadata(t *testing.T) {
 			Keys: []string{req.Metadata[0].Key, req.Metadata[1].Key, req.Metadata[2].Key},
 		}, gotEvent)
 	})
+	t.Run("Pushed", func(t *testing.T) {
+		t.Parallel()
+
+		dbM := dbmock.NewMockStore(gomock.NewController(t))
+		pub := &fakePublisher{}
+
+		now := dbtime.Now()
+		req := &agentproto.BatchUpdateMetadataRequest{
+			Metadata: []*agentproto.Metadata{
+				{
+					Key: "template key",
+					Result: &agentproto.WorkspaceAgentMetadata_Result{
+						Value: "template value",
+					},
+				},
+				{
+					Key: agentsdk.PushedMetadataKeyPrefix + "build",
+					Result: &agentproto.WorkspaceAgentMetadata_Result{
+						Value: "42%",
+					},
+				},
+			},
+		}
+
+		// Only the pushed metadata is created.
+		dbM.EXPECT().InsertWorkspaceAgentPushedMetadata(gomock.Any(), database.InsertWorkspaceAgentPushedMetadataParams{
+			WorkspaceAgentID: agent.ID,
+			Key:              []string{req.Metadata[1].Key},
+			DisplayName:      []string{"build"},
+			MaxPushed:        agentsdk.MaxPushedMetadata,
+		}).Return(nil)
+		dbM.EXPECT().UpdateWorkspaceAgentMetadata(gomock.Any(), database.UpdateWorkspaceAgentMetadataParams{
+			WorkspaceAgentID: agent.ID,
+			Key:              []string{req.Metadata[0].Key, req.Metadata[1].Key},
+			Value:            []string{req.Metadata[0].Result.Value, req.Metadata[1].Result.Value},
+			Error:            []string{"", ""},
+			CollectedAt:      []time.Time{now, now},
+		}).Return(nil)
+
+		api := &agentapi.MetadataAPI{
+			AgentFn: func(context.Context) (database.WorkspaceAgent, error) {
+				return agent, nil
+			},
+			Database: dbM,
+			Pubsub:   pub,
+			Log:      testutil.Logger(t),
+			TimeNowFn: func() time.Time {
+				return now
+			},
+		}
+
+		resp, err := api.BatchUpdateMetadata(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, &agentproto.BatchUpdateMetadataResponse{}, resp)
+		require.Len(t, pub.publishes, 1)
+	})
 }
@@ -85,6 +85,10 @@ func (a *ResourcesMonitoringAPI) PushResourcesMonitoringUsage(ctx context.Contex
 		err = errors.Join(err, xerrors.Errorf("monitor volume: %w", volumeErr))
 	}
 
+	if usageErr := a.recordVolumeUsage(ctx, req.Datapoints); usageErr != nil {
+		err = errors.Join(err, xerrors.Errorf("record volume usage: %w", usageErr))
+	}
+
 	return &proto.PushResourcesMonitoringUsageResponse{}, err
 }
 
@@ -260,3 +264,37 @@ func (a *ResourcesMonitoringAPI) monitorVolumes(ctx context.Context, datapoints
 
 	return nil
 }
+
+// recordVolumeUsage stores the most recent usage of every volume the agent
+// reported, including volumes that aren't monitored, so that it can be shown
+// to users.
+func (a *ResourcesMonitoringAPI) recordVolumeUsage(ctx context.Context, datapoints []*proto.PushResourcesMonitoringUsageRequest_Datapoint) error {
+	if len(datapoints) == 0 {
+		return nil
+	}
+	// Datapoints are ordered from oldest to newest.
+	latest := datapoints[len(datapoints)-1]
+	collectedAt := a.Clock.Now()
+	if latest.CollectedAt != nil {
+		collectedAt = latest.CollectedAt.AsTime()
+	}
+
+	for _, volume := range latest.Volumes {
+		if volume == nil || volume.Total <= 0 {
+			continue
+		}
+
+		//nolint:gocritic // We need to be able to record volume usage here.
+		if err := a.Database.UpsertWorkspaceAgentVolumeUsage(dbauthz.AsResourceMonitor(ctx), database.UpsertWorkspaceAgentVolumeUsageParams{
+			AgentID:     a.AgentID,
+			Path:        volume.Volume,
+			TotalBytes:  volume.Total,
+			UsedBytes:   volume.Used,
+			CollectedAt: dbtime.Time(collectedAt),
+		}); err != nil {
+			return xerrors.Errorf("upsert volume usage %q: %w", volume.Volume, err)
+		}
+	}
+
+	return nil
+}
@@ -938,3 +938,49 @@ func requireVolumeData(t *testing.T, notif *notificationstest.FakeNotification)
 
 	return volumesData.([]map[string]any)
 }
+
+func TestVolumeUsage(t *testing.T) {
+	t.Parallel()
+
+	api, _, clock, _ := resourceMonitorAPI(t)
+
+	// Given: a monitored volume
+	dbgen.WorkspaceAgentVolumeResourceMonitor(t, api.Database, database.WorkspaceAgentVolumeResourceMonitor{
+		AgentID:   api.AgentID,
+		Path:      "/var/lib/docker",
+		State:     database.WorkspaceAgentMonitorStateOK,
+		Threshold: 80,
+	})
+
+	// When: the agent reports the monitored volume and its home directory
+	_, err := api.PushResourcesMonitoringUsage(context.Background(), &agentproto.PushResourcesMonitoringUsageRequest{
+		Datapoints: []*agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+			{
+				CollectedAt: timestamppb.New(clock.Now().Add(-10 * time.Second)),
+				Volumes: []*agentproto.PushResourcesMonitoringUsageRequest_Datapoint_VolumeUsage{
+					{Volume: "/home/coder", Used: 1, Total: 10},
+					{Volume: "/var/lib/docker", Used: 2, Total: 10},
+				},
+			},
+			{
+				CollectedAt: timestamppb.New(clock.Now()),
+				Volumes: []*agentproto.PushResourcesMonitoringUsageRequest_Datapoint_VolumeUsage{
+					{Volume: "/home/coder", Used: 3, Total: 10},
+					{Volume: "/var/lib/docker", Used: 4, Total: 10},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// Then: the most recent usage of both volumes is recorded
+	usage, err := api.Database.GetWorkspaceAgentVolumeUsageByAgentID(context.Background(), api.AgentID)
+	require.NoError(t, err)
+	require.Len(t, usage, 2)
+	require.Equal(t, "/home/coder", usage[0].Path)
+	require.EqualValues(t, 3, usage[0].UsedBytes)
+	require.EqualValues(t, 10, usage[0].TotalBytes)
+	require.Equal(t, "/var/lib/docker", usage[1].Path)
+	require.EqualValues(t, 4, usage[1].UsedBytes)
+	require.WithinDuration(t, clock.Now(), usage[1].CollectedAt, time.Second)
+}
@@ -248,6 +248,88 @@ func TestUpdateStates(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("ResourceActivity", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			now                   = dbtime.Now()
+			dbM                   = dbmock.NewMockStore(gomock.NewController(t))
+			ps                    = pubsub.NewInMemory()
+			templateScheduleStore = schedule.MockTemplateScheduleStore{
+				GetFn: func(context.Context, database.Store, uuid.UUID) (schedule.TemplateScheduleOptions, error) {
+					panic("should not be called")
+				},
+				SetFn: func(context.Context, database.Store, database.Template, schedule.TemplateScheduleOptions) (database.Template, error) {
+					panic("not implemented")
+				},
+			}
+			batcher = &workspacestatstest.StatsBatcher{}
+			tickCh  = make(chan time.Time)
+			flushCh = make(chan int, 1)
+			wut     = workspacestats.NewTracker(dbM,
+				workspacestats.TrackerWithTickFlush(tickCh, flushCh),
+			)
+		)
+		api := agentapi.StatsAPI{
+			AgentFn: func(context.Context) (database.WorkspaceAgent, error) {
+				return agent, nil
+			},
+			Database: dbM,
+			StatsReporter: workspacestats.NewReporter(workspacestats.ReporterOptions{
+				Database:              dbM,
+				Pubsub:                ps,
+				UsageTracker:          wut,
+				StatsBatcher:          batcher,
+				TemplateScheduleStore: templateScheduleStorePtr(templateScheduleStore),
+				ResourceActivity: workspacestats.ResourceActivityThresholds{
+					CPUCores:              0.5,
+					NetworkBytesPerSecond: 1024,
+				},
+			}),
+			AgentStatsRefreshInterval: 10 * time.Second,
+			TimeNowFn: func() time.Time {
+				return now
+			},
+		}
+		defer wut.Close()
+
+		statsWithUsage := func(cpu, network float64) *agentproto.UpdateStatsRequest {
+			return &agentproto.UpdateStatsRequest{
+				Stats: &agentproto.Stats{
+					ConnectionsByProto: map[string]int64{},
+					Metrics: []*agentproto.Stats_Metric{
+						{Name: agentproto.MetricCPUUsageCores, Type: agentproto.Stats_Metric_GAUGE, Value: cpu},
+						{Name: agentproto.MetricNetworkBytesPerSecond, Type: agentproto.Stats_Metric_GAUGE, Value: network},
+					},
+				},
+			}
+		}
+
+		// An idle workspace without connections is not bumped.
+		dbM.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agent.ID).Return(workspace, nil)
+		_, err := api.UpdateStats(context.Background(), statsWithUsage(0.1, 100))
+		require.NoError(t, err)
+
+		// A busy workspace is bumped without any connections.
+		dbM.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agent.ID).Return(workspace, nil).Times(2)
+		dbM.EXPECT().ActivityBumpWorkspace(gomock.Any(), database.ActivityBumpWorkspaceParams{
+			WorkspaceID:   workspace.ID,
+			NextAutostart: time.Time{}.UTC(),
+		}).Return(nil).Times(2)
+		_, err = api.UpdateStats(context.Background(), statsWithUsage(2, 0))
+		require.NoError(t, err)
+		_, err = api.UpdateStats(context.Background(), statsWithUsage(0, 4096))
+		require.NoError(t, err)
+
+		dbM.EXPECT().BatchUpdateWorkspaceLastUsedAt(gomock.Any(), database.BatchUpdateWorkspaceLastUsedAtParams{
+			IDs:        []uuid.UUID{workspace.ID},
+			LastUsedAt: now,
+		}).Return(nil)
+		tickCh <- now
+		count := <-flushCh
+		require.Equal(t, 1, count, "expected one flush with one id")
+	})
+
 	t.Run("NoStats", func(t *testing.T) {
 		t.Parallel()
 
@@ -478,7 +560,7 @@ func TestUpdateStates(t *testing.T) {
 			TimeNowFn: func() time.Time {
 				return now
 			},
-			Experiments: codersdk.Experiments{
+			Experiments: staticExperiments{
 				codersdk.ExperimentWorkspaceUsage,
 			},
 		}
@@ -548,3 +630,9 @@ func templateScheduleStorePtr(store schedule.TemplateScheduleStore) *atomic.Poin
 	ptr.Store(&store)
 	return &ptr
 }
+
+type staticExperiments codersdk.Experiments
+
+func (e staticExperiments) EnabledFor(_ context.Context, ex codersdk.Experiment) bool {
+	return codersdk.Experiments(e).Enabled(ex)
+}
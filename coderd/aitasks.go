@@ -1,13 +1,29 @@
 package coderd
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -61,3 +77,430 @@ func (api *API) aiTasksPrompts(rw http.ResponseWriter, r *http.Request) {
 		Prompts: promptsByBuildID,
 	})
 }
+
+// aiTaskQueueInterval is how often queued AI tasks are checked for free slots.
+// Queued tasks are also checked as soon as a task is queued or canceled.
+const aiTaskQueueInterval = 15 * time.Second
+
+// This endpoint is experimental and not guaranteed to be stable, so we're not
+// generating public-facing documentation for it.
+func (api *API) postAITask(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		apiKey  = httpmw.APIKey(r)
+		auditor = api.Auditor.Load()
+	)
+
+	var req codersdk.CreateAITaskRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	templateVersion, err := api.Database.GetTemplateVersionByID(ctx, req.TemplateVersionID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Template version %q doesn't exist.", req.TemplateVersionID),
+			Validations: []codersdk.ValidationError{{
+				Field:  "template_version_id",
+				Detail: "template version not found",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !templateVersion.HasAITask.Bool {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Template version does not define an AI task.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "template_version_id",
+				Detail: "Use a template version with a coder_ai_task resource.",
+			}},
+		})
+		return
+	}
+
+	if limit := api.DeploymentValues.AITasksMaxConcurrentPerUser.Value(); limit > 0 {
+		queue, err := api.Database.GetAITaskQueueEntriesByOwnerID(ctx, apiKey.UserID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching AI task queue.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		queued := 0
+		for _, entry := range queue {
+			if entry.Status == database.AITaskQueueStatusQueued {
+				queued++
+			}
+		}
+		// Tasks of other templates count towards the limit as well, even if
+		// the user can't read them anymore.
+		//nolint:gocritic // The user can't necessarily read all their workspaces.
+		running, err := api.Database.CountRunningAITasksByOwnerID(dbauthz.AsSystemRestricted(ctx), apiKey.UserID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error counting running AI tasks.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		// Tasks are started in order, so a new task waits behind the queued
+		// ones even if a slot is free.
+		if queued > 0 || running >= limit {
+			api.queueAITask(rw, r, templateVersion, req, queued)
+			return
+		}
+	}
+
+	user, err := api.Database.GetUserByID(ctx, apiKey.UserID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	owner := workspaceOwner{
+		ID:        user.ID,
+		Username:  user.Username,
+		AvatarURL: user.AvatarURL,
+	}
+
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+		Audit:   *auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+		AdditionalFields: audit.AdditionalFields{
+			WorkspaceOwner: owner.Username,
+		},
+		OrganizationID: templateVersion.OrganizationID,
+	})
+	defer commitAudit()
+
+	workspace, ok := createWorkspaceInternal(ctx, aReq, apiKey.UserID, api, owner, aiTaskWorkspaceRequest(req), rw, r, nil)
+	if !ok {
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.CreateAITaskResponse{
+		Workspace: &workspace,
+	})
+}
+
+// queueAITask adds the task to the end of the queue of the user. queued is the
+// number of tasks ahead of it.
+func (api *API) queueAITask(rw http.ResponseWriter, r *http.Request, templateVersion database.TemplateVersion, req codersdk.CreateAITaskRequest, queued int) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+	)
+
+	// Check what creating the workspace would check now, so the task is less
+	// likely to fail when it leaves the queue.
+	if templateVersion.TemplateID.Valid {
+		template, err := api.Database.GetTemplateByID(ctx, templateVersion.TemplateID.UUID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if !api.Authorize(r, policy.ActionUse, template) {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: "Unauthorized access to use the template.",
+			})
+			return
+		}
+	}
+	_, err := api.Database.GetWorkspaceByOwnerIDAndName(ctx, database.GetWorkspaceByOwnerIDAndNameParams{
+		OwnerID: apiKey.UserID,
+		Name:    req.Name,
+	})
+	if err == nil {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Workspace %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: fmt.Sprintf("Internal error fetching workspace by name %q.", req.Name),
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	now := dbtime.Now()
+	entry, err := api.Database.InsertAITaskQueueEntry(ctx, database.InsertAITaskQueueEntryParams{
+		ID:                uuid.New(),
+		OwnerID:           apiKey.UserID,
+		OrganizationID:    templateVersion.OrganizationID,
+		TemplateVersionID: templateVersion.ID,
+		TemplateVersionPresetID: uuid.NullUUID{
+			UUID:  req.TemplateVersionPresetID,
+			Valid: req.TemplateVersionPresetID != uuid.Nil,
+		},
+		Name:      req.Name,
+		Prompt:    req.Prompt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Unauthorized to create workspace.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error queueing AI task.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.notifyAITaskQueue()
+
+	converted := convertAITaskQueueEntry(entry, queued+1)
+	httpapi.Write(ctx, rw, http.StatusAccepted, codersdk.CreateAITaskResponse{
+		QueueEntry: &converted,
+	})
+}
+
+// This endpoint is experimental and not guaranteed to be stable, so we're not
+// generating public-facing documentation for it.
+func (api *API) aiTaskQueue(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+	)
+
+	entries, err := api.Database.GetAITaskQueueEntriesByOwnerID(ctx, apiKey.UserID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching AI task queue.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.AITaskQueueEntry, 0, len(entries))
+	position := 0
+	for _, entry := range entries {
+		if entry.Status == database.AITaskQueueStatusQueued {
+			position++
+			converted = append(converted, convertAITaskQueueEntry(entry, position))
+			continue
+		}
+		converted = append(converted, convertAITaskQueueEntry(entry, 0))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// This endpoint is experimental and not guaranteed to be stable, so we're not
+// generating public-facing documentation for it.
+func (api *API) deleteAITaskQueueEntry(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, ok := httpmw.ParseUUIDParam(rw, r, "entry")
+	if !ok {
+		return
+	}
+
+	err := api.Database.DeleteAITaskQueueEntryByID(ctx, id)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error canceling AI task.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.notifyAITaskQueue()
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// notifyAITaskQueue makes the queue processor check the queue without waiting
+// for the next interval.
+func (api *API) notifyAITaskQueue() {
+	select {
+	case api.aiTaskQueueNotify <- struct{}{}:
+	default:
+	}
+}
+
+// runAITaskQueue starts queued AI tasks in the order they were queued, as
+// running tasks of their owners stop.
+func (api *API) runAITaskQueue(ctx context.Context) {
+	defer close(api.aiTaskQueueDone)
+
+	ticker := api.Clock.NewTicker(aiTaskQueueInterval, "aiTaskQueue")
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-api.aiTaskQueueNotify:
+		}
+		if err := api.processAITaskQueue(ctx); err != nil && ctx.Err() == nil {
+			api.Logger.Error(ctx, "failed to process AI task queue", slog.Error(err))
+		}
+	}
+}
+
+func (api *API) processAITaskQueue(ctx context.Context) error {
+	//nolint:gocritic // The queue processor reads the queues of all users.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	ownerIDs, err := api.Database.GetAITaskQueueOwnerIDs(sysCtx)
+	if err != nil {
+		return xerrors.Errorf("get owners with queued tasks: %w", err)
+	}
+
+	limit := api.DeploymentValues.AITasksMaxConcurrentPerUser.Value()
+	for _, ownerID := range ownerIDs {
+		entries, err := api.Database.GetAITaskQueueEntriesByOwnerID(sysCtx, ownerID)
+		if err != nil {
+			return xerrors.Errorf("get queued tasks of %s: %w", ownerID, err)
+		}
+		running, err := api.Database.CountRunningAITasksByOwnerID(sysCtx, ownerID)
+		if err != nil {
+			return xerrors.Errorf("count running tasks of %s: %w", ownerID, err)
+		}
+		for _, entry := range entries {
+			if entry.Status != database.AITaskQueueStatusQueued {
+				continue
+			}
+			// The limit may have been removed since the tasks were queued.
+			if limit > 0 && running >= limit {
+				break
+			}
+			startErr := api.startQueuedAITask(ctx, entry)
+			if startErr == nil {
+				running++
+				continue
+			}
+			api.Logger.Warn(ctx, "failed to start queued AI task",
+				slog.F("task_id", entry.ID), slog.F("owner_id", ownerID), slog.Error(startErr))
+			err = api.Database.UpdateAITaskQueueEntryFailed(sysCtx, database.UpdateAITaskQueueEntryFailedParams{
+				ID:        entry.ID,
+				Error:     startErr.Error(),
+				UpdatedAt: dbtime.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("mark task %s failed: %w", entry.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// startQueuedAITask creates the workspace of a queued task on behalf of its
+// owner, as if the owner created it, and removes the task from the queue.
+func (api *API) startQueuedAITask(ctx context.Context, entry database.AITaskQueue) error {
+	//nolint:gocritic // The owner is not authenticated, their roles are read as the system.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	subject, _, err := httpmw.UserRBACSubject(sysCtx, api.Database, entry.OwnerID, rbac.ScopeAll)
+	if err != nil {
+		return xerrors.Errorf("get owner roles: %w", err)
+	}
+	user, err := api.Database.GetUserByID(sysCtx, entry.OwnerID)
+	if err != nil {
+		return xerrors.Errorf("get owner: %w", err)
+	}
+	owner := workspaceOwner{
+		ID:        user.ID,
+		Username:  user.Username,
+		AvatarURL: user.AvatarURL,
+	}
+
+	// Workspaces are created by request handlers, so the creation is recorded
+	// like a request of the owner.
+	ctx = dbauthz.As(ctx, subject)
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, "/api/experimental/aitasks", nil)
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	recorder := httptest.NewRecorder()
+	rw := &tracing.StatusWriter{ResponseWriter: recorder}
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+		Audit:   *api.Auditor.Load(),
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+		AdditionalFields: audit.AdditionalFields{
+			WorkspaceOwner: owner.Username,
+		},
+		OrganizationID: entry.OrganizationID,
+	})
+	aReq.UserID = entry.OwnerID
+	defer commitAudit()
+
+	req := codersdk.CreateAITaskRequest{
+		TemplateVersionID: entry.TemplateVersionID,
+		Name:              entry.Name,
+		Prompt:            entry.Prompt,
+	}
+	if entry.TemplateVersionPresetID.Valid {
+		req.TemplateVersionPresetID = entry.TemplateVersionPresetID.UUID
+	}
+	_, ok := createWorkspaceInternal(ctx, aReq, entry.OwnerID, api, owner, aiTaskWorkspaceRequest(req), rw, r,
+		func(db database.Store, _ database.Workspace, _ bool) error {
+			return db.DeleteAITaskQueueEntryByID(ctx, entry.ID)
+		})
+	if !ok {
+		var resp codersdk.Response
+		if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil || resp.Message == "" {
+			return xerrors.Errorf("create workspace: status %d", recorder.Code)
+		}
+		if resp.Detail != "" {
+			return xerrors.Errorf("%s %s", resp.Message, resp.Detail)
+		}
+		return xerrors.New(resp.Message)
+	}
+	return nil
+}
+
+func aiTaskWorkspaceRequest(req codersdk.CreateAITaskRequest) codersdk.CreateWorkspaceRequest {
+	return codersdk.CreateWorkspaceRequest{
+		TemplateVersionID:       req.TemplateVersionID,
+		TemplateVersionPresetID: req.TemplateVersionPresetID,
+		Name:                    req.Name,
+		RichParameterValues: []codersdk.WorkspaceBuildParameter{{
+			Name:  codersdk.AITaskPromptParameterName,
+			Value: req.Prompt,
+		}},
+	}
+}
+
+func convertAITaskQueueEntry(entry database.AITaskQueue, position int) codersdk.AITaskQueueEntry {
+	converted := codersdk.AITaskQueueEntry{
+		ID:                entry.ID,
+		OwnerID:           entry.OwnerID,
+		OrganizationID:    entry.OrganizationID,
+		TemplateVersionID: entry.TemplateVersionID,
+		Name:              entry.Name,
+		Prompt:            entry.Prompt,
+		Status:            codersdk.AITaskQueueEntryStatus(entry.Status),
+		Position:          position,
+		Error:             entry.Error,
+		CreatedAt:         entry.CreatedAt,
+	}
+	if entry.TemplateVersionPresetID.Valid {
+		converted.TemplateVersionPresetID = &entry.TemplateVersionPresetID.UUID
+	}
+	return converted
+}
@@ -1,17 +1,21 @@
 package coderd_test
 
 import (
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
 )
 
 func TestAITasksPrompts(t *testing.T) {
@@ -139,3 +143,121 @@ func TestAITasksPrompts(t *testing.T) {
 		require.Empty(t, prompts.Prompts)
 	})
 }
+
+func TestAITaskQueue(t *testing.T) {
+	t.Parallel()
+
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("This test requires postgres")
+	}
+
+	clock := quartz.NewMock(t)
+	dv := coderdtest.DeploymentValues(t)
+	dv.AITasksMaxConcurrentPerUser = 1
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		DeploymentValues:         dv,
+		Clock:                    clock,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	experimentalClient := codersdk.NewExperimentalClient(client)
+
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					Parameters: []*proto.RichParameter{{
+						Name: codersdk.AITaskPromptParameterName,
+						Type: "string",
+					}},
+					HasAiTasks: true,
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	_ = coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	createTask := func(name string) codersdk.CreateAITaskResponse {
+		res, err := experimentalClient.CreateAITask(ctx, codersdk.CreateAITaskRequest{
+			TemplateVersionID: version.ID,
+			Name:              name,
+			Prompt:            "Fix the " + name,
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	// The first task starts right away.
+	first := createTask("first")
+	require.NotNil(t, first.Workspace)
+	require.Nil(t, first.QueueEntry)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, first.Workspace.LatestBuild.ID)
+
+	// The next tasks wait for the first one to stop.
+	second := createTask("second")
+	require.Nil(t, second.Workspace)
+	require.NotNil(t, second.QueueEntry)
+	require.Equal(t, 1, second.QueueEntry.Position)
+	third := createTask("third")
+	require.NotNil(t, third.QueueEntry)
+	require.Equal(t, 2, third.QueueEntry.Position)
+
+	// Names of queued tasks are checked when they are queued.
+	_, err := experimentalClient.CreateAITask(ctx, codersdk.CreateAITaskRequest{
+		TemplateVersionID: version.ID,
+		Name:              "first",
+		Prompt:            "Again",
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+	queue, err := experimentalClient.AITaskQueue(ctx)
+	require.NoError(t, err)
+	require.Len(t, queue, 2)
+	require.Equal(t, second.QueueEntry.ID, queue[0].ID)
+	require.Equal(t, 1, queue[0].Position)
+	require.Equal(t, third.QueueEntry.ID, queue[1].ID)
+	require.Equal(t, 2, queue[1].Position)
+
+	// Canceling a task moves up the tasks behind it.
+	err = experimentalClient.CancelAITaskQueueEntry(ctx, second.QueueEntry.ID)
+	require.NoError(t, err)
+	queue, err = experimentalClient.AITaskQueue(ctx)
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	require.Equal(t, third.QueueEntry.ID, queue[0].ID)
+	require.Equal(t, 1, queue[0].Position)
+
+	// Stopping the first task starts the next one.
+	build := coderdtest.CreateWorkspaceBuild(t, client, *first.Workspace, database.WorkspaceTransitionStop)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+	require.Eventually(t, func() bool {
+		clock.Advance(time.Minute)
+		queue, err := experimentalClient.AITaskQueue(ctx)
+		return err == nil && len(queue) == 0
+	}, testutil.WaitLong, testutil.IntervalMedium)
+	workspace, err := client.WorkspaceByOwnerAndName(ctx, codersdk.Me, "third", codersdk.WorkspaceOptions{})
+	require.NoError(t, err)
+	prompts, err := experimentalClient.AITaskPrompts(ctx, []uuid.UUID{workspace.LatestBuild.ID})
+	require.NoError(t, err)
+	require.Equal(t, "Fix the third", prompts.Prompts[workspace.LatestBuild.ID.String()])
+}
+
+func TestAITaskQueueCancel(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	err := codersdk.NewExperimentalClient(member).CancelAITaskQueueEntry(ctx, uuid.New())
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
@@ -0,0 +1,225 @@
+// Package alerting evaluates the health of a deployment and forwards alerts to
+// incident management services such as PagerDuty and Opsgenie.
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/quartz"
+
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+)
+
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityError    Severity = "error"
+	SeverityWarning  Severity = "warning"
+	SeverityInfo     Severity = "info"
+)
+
+// Alert describes a deployment condition which is currently firing.
+type Alert struct {
+	// Key identifies the condition within its check. Alerts with the same key
+	// are deduplicated, and the alert is resolved once a check stops returning
+	// it.
+	Key      string
+	Summary  string
+	Severity Severity
+	Details  map[string]any
+}
+
+// Event is an alert which is sent to an incident management service.
+type Event struct {
+	// DedupKey uniquely identifies the condition across the deployment.
+	DedupKey string
+	// Source identifies the deployment which fired the alert.
+	Source string
+	Alert
+}
+
+// Check evaluates a deployment condition and returns the alerts which are
+// currently firing.
+type Check func(ctx context.Context, now time.Time) ([]Alert, error)
+
+// Sender delivers alert events to an incident management service. Triggering
+// or resolving the same DedupKey more than once must be idempotent, as every
+// replica of a deployment evaluates the checks independently.
+type Sender interface {
+	Trigger(ctx context.Context, event Event) error
+	Resolve(ctx context.Context, event Event) error
+}
+
+type Options struct {
+	Logger slog.Logger
+	Clock  quartz.Clock
+	// Interval is how often the checks are evaluated.
+	Interval time.Duration
+	// Source identifies the deployment, e.g. by its access URL.
+	Source  string
+	Senders []Sender
+}
+
+// Alerter periodically evaluates the registered checks and triggers or
+// resolves alerts as their conditions change.
+type Alerter struct {
+	logger  slog.Logger
+	clock   quartz.Clock
+	source  string
+	senders []Sender
+
+	mu     sync.Mutex
+	checks map[string]Check
+	// firing holds the alerts which were triggered, keyed by check name and
+	// alert key.
+	firing map[string]map[string]Alert
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New starts an Alerter which evaluates its checks every interval until it is
+// closed.
+func New(ctx context.Context, opts Options) *Alerter {
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	//nolint:gocritic // The alerter evaluates deployment-wide state without direct user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	a := &Alerter{
+		logger:  opts.Logger,
+		clock:   opts.Clock,
+		source:  opts.Source,
+		senders: opts.Senders,
+		checks:  make(map[string]Check),
+		firing:  make(map[string]map[string]Alert),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		defer close(a.done)
+		tkr := a.clock.TickerFunc(ctx, opts.Interval, func() error {
+			a.evaluate(ctx)
+			return nil
+		}, "alerting")
+		_ = tkr.Wait()
+	}()
+	return a
+}
+
+// RegisterCheck adds a check which is evaluated on every interval. Registering
+// a check with an existing name replaces it.
+func (a *Alerter) RegisterCheck(name string, check Check) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.checks[name] = check
+}
+
+func (a *Alerter) Close() error {
+	a.cancel()
+	<-a.done
+	return nil
+}
+
+func (a *Alerter) evaluate(ctx context.Context) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.clock.Now()
+	names := make([]string, 0, len(a.checks))
+	for name := range a.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		alerts, err := a.checks[name](ctx, now)
+		if err != nil {
+			// Keep the current state of the check's alerts, as we do not know
+			// whether their conditions have changed.
+			a.logger.Warn(ctx, "evaluate alerting check", slog.F("check", name), slog.Error(err))
+			continue
+		}
+
+		previous := a.firing[name]
+		current := make(map[string]Alert, len(alerts))
+		for _, alert := range alerts {
+			prev, wasFiring := previous[alert.Key]
+			current[alert.Key] = alert
+			// Only trigger new alerts and alerts which changed severity, the
+			// services keep track of ongoing incidents.
+			if wasFiring && prev.Severity == alert.Severity {
+				continue
+			}
+			if err := a.send(ctx, a.event(name, alert), Sender.Trigger); err != nil {
+				a.logger.Warn(ctx, "trigger alert", slog.F("check", name), slog.F("key", alert.Key), slog.Error(err))
+				// Keep the previous state so the trigger is retried on the next
+				// evaluation.
+				if wasFiring {
+					current[alert.Key] = prev
+				} else {
+					delete(current, alert.Key)
+				}
+			}
+		}
+		for key, alert := range previous {
+			if _, ok := current[key]; ok {
+				continue
+			}
+			if err := a.send(ctx, a.event(name, alert), Sender.Resolve); err != nil {
+				a.logger.Warn(ctx, "resolve alert", slog.F("check", name), slog.F("key", key), slog.Error(err))
+				// Try again on the next evaluation.
+				current[key] = alert
+			}
+		}
+		a.firing[name] = current
+	}
+}
+
+func (a *Alerter) event(check string, alert Alert) Event {
+	return Event{
+		DedupKey: fmt.Sprintf("coder:%s:%s:%s", a.source, check, alert.Key),
+		Source:   a.source,
+		Alert:    alert,
+	}
+}
+
+func (a *Alerter) send(ctx context.Context, event Event, fn func(Sender, context.Context, Event) error) error {
+	var errs error
+	for _, sender := range a.senders {
+		errs = errors.Join(errs, fn(sender, ctx, event))
+	}
+	return errs
+}
+
+// do sends the request and returns an error unless the service accepted it.
+func do(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		// Grab the start of the body, it usually explains why the request was
+		// rejected.
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return xerrors.Errorf("unexpected status code %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
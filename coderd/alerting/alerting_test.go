@@ -0,0 +1,239 @@
+package alerting_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/quartz"
+
+	"github.com/coder/coder/v2/coderd/alerting"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestAlerter(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().TickerFunc("alerting")
+	defer trap.Close()
+
+	sender := &fakeSender{}
+	uut := alerting.New(ctx, alerting.Options{
+		Logger:   logger,
+		Clock:    mClock,
+		Interval: time.Minute,
+		Source:   "https://coder.example.com",
+		Senders:  []alerting.Sender{sender},
+	})
+	defer uut.Close()
+
+	var (
+		mu       sync.Mutex
+		alerts   []alerting.Alert
+		checkErr error
+	)
+	uut.RegisterCheck("test", func(context.Context, time.Time) ([]alerting.Alert, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return alerts, checkErr
+	})
+	set := func(a []alerting.Alert, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		alerts, checkErr = a, err
+	}
+	tick := func() {
+		mClock.Advance(time.Minute).MustWait(ctx)
+	}
+
+	trap.MustWait(ctx).MustRelease(ctx)
+
+	const dedupKey = "coder:https://coder.example.com:test:db"
+
+	// A new alert is triggered.
+	set([]alerting.Alert{{Key: "db", Summary: "slow", Severity: alerting.SeverityWarning}}, nil)
+	tick()
+	require.Equal(t, []string{"trigger " + dedupKey + " warning"}, sender.take())
+
+	// An ongoing alert is not triggered again.
+	tick()
+	require.Empty(t, sender.take())
+
+	// An alert which changes severity is triggered again.
+	set([]alerting.Alert{{Key: "db", Summary: "down", Severity: alerting.SeverityCritical}}, nil)
+	tick()
+	require.Equal(t, []string{"trigger " + dedupKey + " critical"}, sender.take())
+
+	// A failing check keeps its alerts firing.
+	set(nil, xerrors.New("boom"))
+	tick()
+	require.Empty(t, sender.take())
+
+	// An alert which is no longer returned is resolved.
+	set(nil, nil)
+	tick()
+	require.Equal(t, []string{"resolve " + dedupKey + " critical"}, sender.take())
+
+	// A failed trigger is retried on the next evaluation.
+	sender.setErr(xerrors.New("unavailable"))
+	set([]alerting.Alert{{Key: "db", Summary: "slow", Severity: alerting.SeverityWarning}}, nil)
+	tick()
+	require.Equal(t, []string{"trigger " + dedupKey + " warning"}, sender.take())
+	sender.setErr(nil)
+	tick()
+	require.Equal(t, []string{"trigger " + dedupKey + " warning"}, sender.take())
+}
+
+func TestPagerDuty(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	events := make(chan map[string]any, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		events <- event
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	pd := alerting.NewPagerDuty(srv.Client(), srv.URL, "routing-key")
+	event := alerting.Event{
+		DedupKey: "coder:source:test:db",
+		Source:   "source",
+		Alert: alerting.Alert{
+			Key:      "db",
+			Summary:  "Database is slow",
+			Severity: alerting.SeverityWarning,
+			Details:  map[string]any{"latency_ms": 150},
+		},
+	}
+
+	require.NoError(t, pd.Trigger(ctx, event))
+	trigger := testutil.TryReceive(ctx, t, events)
+	require.Equal(t, "routing-key", trigger["routing_key"])
+	require.Equal(t, "trigger", trigger["event_action"])
+	require.Equal(t, event.DedupKey, trigger["dedup_key"])
+	payload, ok := trigger["payload"].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, "Database is slow", payload["summary"])
+	require.Equal(t, "source", payload["source"])
+	require.Equal(t, "warning", payload["severity"])
+	require.Equal(t, map[string]any{"latency_ms": float64(150)}, payload["custom_details"])
+
+	require.NoError(t, pd.Resolve(ctx, event))
+	resolve := testutil.TryReceive(ctx, t, events)
+	require.Equal(t, "resolve", resolve["event_action"])
+	require.Equal(t, event.DedupKey, resolve["dedup_key"])
+	require.NotContains(t, resolve, "payload")
+}
+
+func TestPagerDutyRejected(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":"invalid event"}`))
+	}))
+	defer srv.Close()
+
+	pd := alerting.NewPagerDuty(srv.Client(), srv.URL, "routing-key")
+	err := pd.Trigger(ctx, alerting.Event{DedupKey: "key"})
+	require.ErrorContains(t, err, "unexpected status code 400")
+	require.ErrorContains(t, err, "invalid event")
+}
+
+func TestOpsgenie(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		path  string
+		query string
+		body  map[string]any
+	}
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	requests := make(chan request, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GenieKey api-key", r.Header.Get("Authorization"))
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests <- request{path: r.URL.Path, query: r.URL.RawQuery, body: body}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	og := alerting.NewOpsgenie(srv.Client(), srv.URL+"/", "api-key")
+	event := alerting.Event{
+		DedupKey: "coder:source:test:db",
+		Source:   "source",
+		Alert: alerting.Alert{
+			Key:      "db",
+			Summary:  "Database is unreachable",
+			Severity: alerting.SeverityCritical,
+			Details:  map[string]any{"replica": "coder-0"},
+		},
+	}
+
+	require.NoError(t, og.Trigger(ctx, event))
+	trigger := testutil.TryReceive(ctx, t, requests)
+	require.Equal(t, "/v2/alerts", trigger.path)
+	require.Equal(t, "Database is unreachable", trigger.body["message"])
+	require.Equal(t, event.DedupKey, trigger.body["alias"])
+	require.Equal(t, "P1", trigger.body["priority"])
+	require.Equal(t, map[string]any{"replica": "coder-0"}, trigger.body["details"])
+
+	require.NoError(t, og.Resolve(ctx, event))
+	resolve := testutil.TryReceive(ctx, t, requests)
+	require.Equal(t, "/v2/alerts/"+event.DedupKey+"/close", resolve.path)
+	require.Equal(t, "identifierType=alias", resolve.query)
+	require.Equal(t, "source", resolve.body["source"])
+}
+
+type fakeSender struct {
+	mu    sync.Mutex
+	err   error
+	calls []string
+}
+
+func (f *fakeSender) Trigger(_ context.Context, event alerting.Event) error {
+	return f.record("trigger", event)
+}
+
+func (f *fakeSender) Resolve(_ context.Context, event alerting.Event) error {
+	return f.record("resolve", event)
+}
+
+func (f *fakeSender) record(action string, event alerting.Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, action+" "+event.DedupKey+" "+string(event.Severity))
+	return f.err
+}
+
+func (f *fakeSender) setErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *fakeSender) take() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := f.calls
+	f.calls = nil
+	return calls
+}
@@ -0,0 +1,116 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+)
+
+// DatabaseLatencyCheck fires when the median latency of pinging the database
+// from this replica exceeds the threshold, or when the database cannot be
+// reached at all.
+func DatabaseLatencyCheck(db database.Store, replica string, threshold time.Duration) Check {
+	return func(ctx context.Context, _ time.Time) ([]Alert, error) {
+		const pingCount = 3
+		pings := make([]time.Duration, 0, pingCount)
+		for i := 0; i < pingCount; i++ {
+			latency, err := db.Ping(ctx)
+			if err != nil {
+				return []Alert{{
+					Key:      replica,
+					Summary:  fmt.Sprintf("Replica %s cannot reach the database: %s", replica, err),
+					Severity: SeverityCritical,
+					Details:  map[string]any{"replica": replica},
+				}}, nil
+			}
+			pings = append(pings, latency)
+		}
+		slices.Sort(pings)
+
+		latency := pings[pingCount/2]
+		if latency <= threshold {
+			return nil, nil
+		}
+		return []Alert{{
+			Key:      replica,
+			Summary:  fmt.Sprintf("Database latency from replica %s is %s, above the threshold of %s", replica, latency.Round(time.Millisecond), threshold),
+			Severity: SeverityWarning,
+			Details: map[string]any{
+				"replica":      replica,
+				"latency_ms":   latency.Milliseconds(),
+				"threshold_ms": threshold.Milliseconds(),
+			},
+		}}, nil
+	}
+}
+
+// ProvisionerQueueCheck fires when at least threshold provisioner jobs are
+// waiting to be acquired by a provisioner daemon.
+func ProvisionerQueueCheck(db database.Store, threshold int64) Check {
+	return func(ctx context.Context, _ time.Time) ([]Alert, error) {
+		if threshold <= 0 {
+			return nil, nil
+		}
+		pending, err := db.GetPendingProvisionerJobsCount(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("get pending provisioner jobs count: %w", err)
+		}
+		if pending < threshold {
+			return nil, nil
+		}
+		return []Alert{{
+			Key:      "saturated",
+			Summary:  fmt.Sprintf("%d provisioner jobs are waiting for a provisioner daemon", pending),
+			Severity: SeverityWarning,
+			Details: map[string]any{
+				"pending_jobs": pending,
+				"threshold":    threshold,
+			},
+		}}, nil
+	}
+}
+
+// ReplicasCheck fires for every replica which reports an error, such as being
+// unable to reach its peers, or which has not reported in for longer than
+// staleAfter. Stale replicas are eventually removed from the database, which
+// resolves their alerts.
+func ReplicasCheck(db database.Store, staleAfter time.Duration) Check {
+	return func(ctx context.Context, now time.Time) ([]Alert, error) {
+		replicas, err := db.GetReplicasUpdatedAfter(ctx, now.Add(-24*time.Hour))
+		if err != nil {
+			return nil, xerrors.Errorf("get replicas: %w", err)
+		}
+
+		var alerts []Alert
+		for _, replica := range replicas {
+			details := map[string]any{
+				"replica_id": replica.ID.String(),
+				"hostname":   replica.Hostname,
+				"updated_at": replica.UpdatedAt,
+			}
+			switch {
+			case replica.UpdatedAt.Before(now.Add(-staleAfter)):
+				alerts = append(alerts, Alert{
+					Key:      replica.ID.String(),
+					Summary:  fmt.Sprintf("Replica %s has not reported in since %s", replica.Hostname, replica.UpdatedAt.UTC().Format(time.RFC3339)),
+					Severity: SeverityError,
+					Details:  details,
+				})
+			case replica.Error != "":
+				details["error"] = replica.Error
+				alerts = append(alerts, Alert{
+					Key:      replica.ID.String(),
+					Summary:  fmt.Sprintf("Replica %s is unhealthy: %s", replica.Hostname, replica.Error),
+					Severity: SeverityError,
+					Details:  details,
+				})
+			}
+		}
+		return alerts, nil
+	}
+}
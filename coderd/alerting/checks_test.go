@@ -0,0 +1,104 @@
+package alerting_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/coder/coder/v2/coderd/alerting"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestDatabaseLatencyCheck(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Healthy", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmock.NewMockStore(gomock.NewController(t))
+		db.EXPECT().Ping(gomock.Any()).Return(10*time.Millisecond, nil).Times(3)
+
+		alerts, err := alerting.DatabaseLatencyCheck(db, "coder-0", 100*time.Millisecond)(ctx, time.Now())
+		require.NoError(t, err)
+		require.Empty(t, alerts)
+	})
+
+	t.Run("Slow", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmock.NewMockStore(gomock.NewController(t))
+		// A single slow ping does not fire the alert, the median does.
+		db.EXPECT().Ping(gomock.Any()).Return(time.Second, nil)
+		db.EXPECT().Ping(gomock.Any()).Return(200*time.Millisecond, nil).Times(2)
+
+		alerts, err := alerting.DatabaseLatencyCheck(db, "coder-0", 100*time.Millisecond)(ctx, time.Now())
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		require.Equal(t, "coder-0", alerts[0].Key)
+		require.Equal(t, alerting.SeverityWarning, alerts[0].Severity)
+		require.EqualValues(t, 200, alerts[0].Details["latency_ms"])
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmock.NewMockStore(gomock.NewController(t))
+		db.EXPECT().Ping(gomock.Any()).Return(time.Duration(0), context.DeadlineExceeded)
+
+		alerts, err := alerting.DatabaseLatencyCheck(db, "coder-0", 100*time.Millisecond)(ctx, time.Now())
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		require.Equal(t, alerting.SeverityCritical, alerts[0].Severity)
+	})
+}
+
+func TestProvisionerQueueCheck(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	db := dbmock.NewMockStore(gomock.NewController(t))
+	db.EXPECT().GetPendingProvisionerJobsCount(gomock.Any()).Return(int64(5), nil)
+	db.EXPECT().GetPendingProvisionerJobsCount(gomock.Any()).Return(int64(20), nil)
+
+	check := alerting.ProvisionerQueueCheck(db, 20)
+	alerts, err := check(ctx, time.Now())
+	require.NoError(t, err)
+	require.Empty(t, alerts)
+
+	alerts, err = check(ctx, time.Now())
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	require.Equal(t, "saturated", alerts[0].Key)
+
+	// A threshold of zero disables the check without querying the database.
+	alerts, err = alerting.ProvisionerQueueCheck(db, 0)(ctx, time.Now())
+	require.NoError(t, err)
+	require.Empty(t, alerts)
+}
+
+func TestReplicasCheck(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	now := time.Now()
+	healthy := database.Replica{ID: uuid.New(), Hostname: "healthy", UpdatedAt: now}
+	stale := database.Replica{ID: uuid.New(), Hostname: "stale", UpdatedAt: now.Add(-5 * time.Minute)}
+	broken := database.Replica{ID: uuid.New(), Hostname: "broken", UpdatedAt: now, Error: "peer unreachable"}
+
+	db := dbmock.NewMockStore(gomock.NewController(t))
+	db.EXPECT().GetReplicasUpdatedAfter(gomock.Any(), now.Add(-24*time.Hour)).
+		Return([]database.Replica{healthy, stale, broken}, nil)
+
+	alerts, err := alerting.ReplicasCheck(db, time.Minute)(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+	require.Equal(t, stale.ID.String(), alerts[0].Key)
+	require.Equal(t, broken.ID.String(), alerts[1].Key)
+	require.Equal(t, "peer unreachable", alerts[1].Details["error"])
+}
@@ -0,0 +1,92 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	cstrings "github.com/coder/coder/v2/coderd/util/strings"
+)
+
+// Opsgenie sends alerts to Opsgenie through its Alert API. Alerts are
+// deduplicated by their alias.
+type Opsgenie struct {
+	client *http.Client
+	apiURL string
+	apiKey string
+}
+
+type opsgenieAlert struct {
+	Message  string            `json:"message"`
+	Alias    string            `json:"alias"`
+	Source   string            `json:"source"`
+	Priority string            `json:"priority"`
+	Tags     []string          `json:"tags"`
+	Details  map[string]string `json:"details,omitempty"`
+}
+
+type opsgenieClose struct {
+	Source string `json:"source"`
+	Note   string `json:"note"`
+}
+
+func NewOpsgenie(client *http.Client, apiURL, apiKey string) *Opsgenie {
+	return &Opsgenie{client: client, apiURL: strings.TrimSuffix(apiURL, "/"), apiKey: apiKey}
+}
+
+func (o *Opsgenie) Trigger(ctx context.Context, event Event) error {
+	details := make(map[string]string, len(event.Details))
+	for k, v := range event.Details {
+		details[k] = fmt.Sprint(v)
+	}
+	return o.send(ctx, "/v2/alerts", opsgenieAlert{
+		Message:  cstrings.Truncate(event.Summary, 130, cstrings.TruncateWithEllipsis),
+		Alias:    cstrings.Truncate(event.DedupKey, 512, cstrings.TruncateWithEllipsis),
+		Source:   event.Source,
+		Priority: opsgeniePriority(event.Severity),
+		Tags:     []string{"coder"},
+		Details:  details,
+	})
+}
+
+func (o *Opsgenie) Resolve(ctx context.Context, event Event) error {
+	path := "/v2/alerts/" + url.PathEscape(cstrings.Truncate(event.DedupKey, 512, cstrings.TruncateWithEllipsis)) + "/close?identifierType=alias"
+	return o.send(ctx, path, opsgenieClose{
+		Source: event.Source,
+		Note:   "The condition is no longer firing.",
+	})
+}
+
+func (o *Opsgenie) send(ctx context.Context, path string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return xerrors.Errorf("marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.apiURL+path, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+o.apiKey)
+
+	return do(o.client, req)
+}
+
+func opsgeniePriority(severity Severity) string {
+	switch severity {
+	case SeverityCritical:
+		return "P1"
+	case SeverityError:
+		return "P2"
+	case SeverityWarning:
+		return "P3"
+	default:
+		return "P5"
+	}
+}
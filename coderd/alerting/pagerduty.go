@@ -0,0 +1,79 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"golang.org/x/xerrors"
+
+	cstrings "github.com/coder/coder/v2/coderd/util/strings"
+)
+
+// PagerDutyEventsURL is the endpoint of the PagerDuty Events API v2.
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDuty sends alerts to a PagerDuty service through the Events API v2.
+// Alerts are deduplicated into incidents by their dedup key.
+type PagerDuty struct {
+	client     *http.Client
+	eventsURL  string
+	routingKey string
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      Severity       `json:"severity"`
+	Component     string         `json:"component"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+func NewPagerDuty(client *http.Client, eventsURL, routingKey string) *PagerDuty {
+	return &PagerDuty{client: client, eventsURL: eventsURL, routingKey: routingKey}
+}
+
+func (p *PagerDuty) Trigger(ctx context.Context, event Event) error {
+	return p.send(ctx, pagerDutyEvent{
+		RoutingKey:  p.routingKey,
+		EventAction: "trigger",
+		DedupKey:    event.DedupKey,
+		Payload: &pagerDutyPayload{
+			Summary:       cstrings.Truncate(event.Summary, 1024, cstrings.TruncateWithEllipsis),
+			Source:        event.Source,
+			Severity:      event.Severity,
+			Component:     "coder",
+			CustomDetails: event.Details,
+		},
+	})
+}
+
+func (p *PagerDuty) Resolve(ctx context.Context, event Event) error {
+	return p.send(ctx, pagerDutyEvent{
+		RoutingKey:  p.routingKey,
+		EventAction: "resolve",
+		DedupKey:    event.DedupKey,
+	})
+}
+
+func (p *PagerDuty) send(ctx context.Context, event pagerDutyEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return xerrors.Errorf("marshal event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.eventsURL, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return do(p.client, req)
+}
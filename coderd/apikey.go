@@ -15,6 +15,7 @@ import (
 	"github.com/coder/coder/v2/coderd/apikey"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
@@ -60,6 +61,13 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 	if scope != "" {
 		scope = database.APIKeyScope(createToken.Scope)
 	}
+	if scope == database.APIKeyScopeWorkspaceConnect {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Tokens with scope %q must be created for a workspace.", scope),
+			Detail:  "Use the workspace credentials endpoint instead.",
+		})
+		return
+	}
 
 	tokenName := namesgenerator.GetRandomName(1)
 
@@ -68,11 +76,13 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	params := apikey.CreateParams{
-		UserID:          user.ID,
-		LoginType:       database.LoginTypeToken,
-		DefaultLifetime: api.DeploymentValues.Sessions.DefaultTokenDuration.Value(),
-		Scope:           scope,
-		TokenName:       tokenName,
+		UserID:            user.ID,
+		LoginType:         database.LoginTypeToken,
+		DefaultLifetime:   api.DeploymentValues.Sessions.DefaultTokenDuration.Value(),
+		Scope:             scope,
+		TokenName:         tokenName,
+		UserAgent:         r.UserAgent(),
+		DeviceFingerprint: apikey.DeviceFingerprint(r.Header),
 	}
 
 	if createToken.Lifetime != 0 {
@@ -125,10 +135,12 @@ func (api *API) postAPIKey(rw http.ResponseWriter, r *http.Request) {
 	user := httpmw.UserParam(r)
 
 	cookie, _, err := api.createAPIKey(ctx, apikey.CreateParams{
-		UserID:          user.ID,
-		DefaultLifetime: api.DeploymentValues.Sessions.DefaultTokenDuration.Value(),
-		LoginType:       database.LoginTypePassword,
-		RemoteAddr:      r.RemoteAddr,
+		UserID:            user.ID,
+		DefaultLifetime:   api.DeploymentValues.Sessions.DefaultTokenDuration.Value(),
+		LoginType:         database.LoginTypePassword,
+		RemoteAddr:        r.RemoteAddr,
+		UserAgent:         r.UserAgent(),
+		DeviceFingerprint: apikey.DeviceFingerprint(r.Header),
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -330,6 +342,108 @@ func (api *API) deleteAPIKey(rw http.ResponseWriter, r *http.Request) {
 	rw.WriteHeader(http.StatusNoContent)
 }
 
+// @Summary Get user sessions
+// @ID get-user-sessions
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.APISession
+// @Router /users/{user}/sessions [get]
+func (api *API) apiSessions(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		user    = httpmw.UserParam(r)
+		current = httpmw.APIKey(r)
+	)
+
+	keys, err := api.Database.GetAPIKeySessionsByUserID(ctx, database.GetAPIKeySessionsByUserIDParams{
+		UserID: user.ID,
+		Now:    dbtime.Now(),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching sessions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	sessions := make([]codersdk.APISession, 0, len(keys))
+	for _, key := range keys {
+		session := codersdk.APISession{
+			APIKey:            convertAPIKey(key),
+			UserAgent:         key.UserAgent,
+			DeviceFingerprint: key.DeviceFingerprint,
+			Current:           key.ID == current.ID,
+		}
+		if key.IPAddress.Valid {
+			session.IPAddress = key.IPAddress.IPNet.IP.String()
+		}
+		sessions = append(sessions, session)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, sessions)
+}
+
+// @Summary Delete user session
+// @ID delete-user-session
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param keyid path string true "Key ID"
+// @Success 204
+// @Router /users/{user}/sessions/{keyid} [delete]
+func (api *API) deleteAPISession(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		keyID             = chi.URLParam(r, "keyid")
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	key, err := api.Database.GetAPIKeyByID(ctx, keyID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching session.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Tokens are managed with the keys endpoints.
+	if key.UserID != user.ID || !key.IsSession() {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	aReq.Old = key
+
+	err = api.Database.DeleteAPIKeyByID(ctx, keyID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting session.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
 // @Summary Get token config
 // @ID get-token-config
 // @Security CoderSessionToken
@@ -413,6 +527,19 @@ func (api *API) createAPIKey(ctx context.Context, params apikey.CreateParams) (*
 		return nil, nil, xerrors.Errorf("insert API key: %w", err)
 	}
 
+	if maxSessions := api.DeploymentValues.Sessions.MaximumSessionsPerUser.Value(); maxSessions > 0 && newkey.IsSession() {
+		// Evict the least recently used sessions so the new one fits.
+		//nolint:gocritic // The caller may not be allowed to delete the other sessions of the user.
+		err = api.Database.DeleteExcessAPIKeySessionsByUserID(dbauthz.AsSystemRestricted(ctx), database.DeleteExcessAPIKeySessionsByUserIDParams{
+			UserID:      newkey.UserID,
+			Now:         dbtime.Now(),
+			MaxSessions: int32(maxSessions), //nolint:gosec // Bounded by the deployment config.
+		})
+		if err != nil {
+			return nil, nil, xerrors.Errorf("delete excess sessions: %w", err)
+		}
+	}
+
 	api.Telemetry.Report(&telemetry.Snapshot{
 		APIKeys: []telemetry.APIKey{telemetry.ConvertAPIKey(newkey)},
 	})
@@ -2,8 +2,11 @@ package apikey
 
 import (
 	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -28,6 +31,46 @@ type CreateParams struct {
 	Scope           database.APIKeyScope
 	TokenName       string
 	RemoteAddr      string
+	// UserAgent and DeviceFingerprint describe the client that created the
+	// key, so users can tell their sessions apart.
+	UserAgent         string
+	DeviceFingerprint string
+	// CreatedAt is when the user authenticated. Keys derived from another
+	// key keep its creation time, so that limits on the age of sessions also
+	// apply to them. Defaults to now.
+	CreatedAt time.Time
+	// WorkspaceID is the workspace that a key with the workspace_connect
+	// scope is limited to. It must be set for that scope only.
+	WorkspaceID uuid.NullUUID
+}
+
+// maxUserAgentLength bounds the stored User-Agent header, since clients
+// control its length.
+const maxUserAgentLength = 512
+
+// fingerprintHeaders are the request headers that are stable for a browser or
+// CLI installation, but tend to differ between devices.
+var fingerprintHeaders = []string{
+	"User-Agent",
+	"Accept-Language",
+	"Sec-CH-UA",
+	"Sec-CH-UA-Mobile",
+	"Sec-CH-UA-Platform",
+}
+
+// DeviceFingerprint returns a short hash identifying the device that sent the
+// request headers. It is not a security boundary, since clients control every
+// header, and is empty when none of the headers are set.
+func DeviceFingerprint(header http.Header) string {
+	var values []string
+	for _, name := range fingerprintHeaders {
+		values = append(values, header.Get(name))
+	}
+	if strings.Join(values, "") == "" {
+		return ""
+	}
+	hashed := sha256.Sum256([]byte(strings.Join(values, "\n")))
+	return hex.EncodeToString(hashed[:8])
 }
 
 // Generate generates an API key, returning the key as a string as well as the
@@ -68,12 +111,29 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 	}
 	switch scope {
 	case database.APIKeyScopeAll, database.APIKeyScopeApplicationConnect:
+		if params.WorkspaceID.Valid {
+			return database.InsertAPIKeyParams{}, "", xerrors.Errorf("API keys with scope %q can't be limited to a workspace", scope)
+		}
+	case database.APIKeyScopeWorkspaceConnect:
+		if !params.WorkspaceID.Valid {
+			return database.InsertAPIKeyParams{}, "", xerrors.Errorf("API keys with scope %q must be limited to a workspace", scope)
+		}
 	default:
 		return database.InsertAPIKeyParams{}, "", xerrors.Errorf("invalid API key scope: %q", scope)
 	}
 
 	token := fmt.Sprintf("%s-%s", keyID, keySecret)
 
+	createdAt := dbtime.Now()
+	if !params.CreatedAt.IsZero() {
+		createdAt = dbtime.Time(params.CreatedAt)
+	}
+
+	userAgent := params.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
 	return database.InsertAPIKeyParams{
 		ID:              keyID,
 		UserID:          params.UserID,
@@ -87,13 +147,16 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 			Valid: true,
 		},
 		// Make sure in UTC time for common time zone
-		ExpiresAt:    params.ExpiresAt.UTC(),
-		CreatedAt:    dbtime.Now(),
-		UpdatedAt:    dbtime.Now(),
-		HashedSecret: hashed[:],
-		LoginType:    params.LoginType,
-		Scope:        scope,
-		TokenName:    params.TokenName,
+		ExpiresAt:         params.ExpiresAt.UTC(),
+		CreatedAt:         createdAt,
+		UpdatedAt:         dbtime.Now(),
+		HashedSecret:      hashed[:],
+		LoginType:         params.LoginType,
+		Scope:             scope,
+		TokenName:         params.TokenName,
+		UserAgent:         userAgent,
+		DeviceFingerprint: params.DeviceFingerprint,
+		WorkspaceID:       params.WorkspaceID,
 	}, token, nil
 }
 
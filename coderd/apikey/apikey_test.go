@@ -2,6 +2,7 @@ package apikey_test
 
 import (
 	"crypto/sha256"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -104,6 +105,28 @@ func TestGenerate(t *testing.T) {
 				Scope:           "",
 			},
 		},
+		{
+			name: "Device",
+			params: apikey.CreateParams{
+				UserID:            uuid.New(),
+				LoginType:         database.LoginTypePassword,
+				DefaultLifetime:   time.Hour,
+				RemoteAddr:        "1.2.3.4",
+				UserAgent:         "Mozilla/5.0 (X11; Linux x86_64)",
+				DeviceFingerprint: "0123456789abcdef",
+			},
+		},
+		{
+			name: "CreatedAt",
+			params: apikey.CreateParams{
+				UserID:          uuid.New(),
+				LoginType:       database.LoginTypePassword,
+				DefaultLifetime: time.Hour,
+				RemoteAddr:      "1.2.3.4",
+				Scope:           database.APIKeyScopeApplicationConnect,
+				CreatedAt:       dbtime.Now().Add(-time.Hour),
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -130,7 +153,11 @@ func TestGenerate(t *testing.T) {
 			assert.ElementsMatch(t, hashed, key.HashedSecret)
 
 			assert.Equal(t, tc.params.UserID, key.UserID)
-			assert.WithinDuration(t, dbtime.Now(), key.CreatedAt, time.Second*5)
+			if !tc.params.CreatedAt.IsZero() {
+				assert.Equal(t, tc.params.CreatedAt, key.CreatedAt)
+			} else {
+				assert.WithinDuration(t, dbtime.Now(), key.CreatedAt, time.Second*5)
+			}
 			assert.WithinDuration(t, dbtime.Now(), key.UpdatedAt, time.Second*5)
 
 			switch {
@@ -170,6 +197,25 @@ func TestGenerate(t *testing.T) {
 			if tc.params.LoginType != "" {
 				assert.Equal(t, tc.params.LoginType, key.LoginType)
 			}
+			assert.Equal(t, tc.params.UserAgent, key.UserAgent)
+			assert.Equal(t, tc.params.DeviceFingerprint, key.DeviceFingerprint)
 		})
 	}
 }
+
+func TestDeviceFingerprint(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, apikey.DeviceFingerprint(http.Header{}))
+
+	firefox := http.Header{}
+	firefox.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0")
+	firefox.Set("Accept-Language", "en-US")
+	fingerprint := apikey.DeviceFingerprint(firefox)
+	require.Len(t, fingerprint, 16)
+	require.Equal(t, fingerprint, apikey.DeviceFingerprint(firefox.Clone()))
+
+	other := firefox.Clone()
+	other.Set("Accept-Language", "de-DE")
+	require.NotEqual(t, fingerprint, apikey.DeviceFingerprint(other))
+}
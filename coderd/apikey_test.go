@@ -351,3 +351,83 @@ func TestAPIKey_SetDefault(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, dc.Sessions.DefaultTokenDuration.Value().Seconds(), apiKey1.LifetimeSeconds)
 }
+
+func TestAPISessions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ListAndDelete", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		other, err := client.CreateAPIKey(ctx, codersdk.Me)
+		require.NoError(t, err)
+		otherID := strings.Split(other.Key, "-")[0]
+		// Tokens are not sessions.
+		_, err = client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		require.NoError(t, err)
+
+		sessions, err := client.APISessions(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+		for _, session := range sessions {
+			require.Equal(t, session.ID != otherID, session.Current)
+			require.NotEmpty(t, session.UserAgent)
+			require.NotEmpty(t, session.DeviceFingerprint)
+		}
+
+		err = client.DeleteAPISession(ctx, codersdk.Me, otherID)
+		require.NoError(t, err)
+		sessions, err = client.APISessions(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		require.True(t, sessions[0].Current)
+	})
+
+	t.Run("DeleteToken", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		token, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		require.NoError(t, err)
+
+		err = client.DeleteAPISession(ctx, codersdk.Me, strings.Split(token.Key, "-")[0])
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("MaximumSessions", func(t *testing.T) {
+		t.Parallel()
+		dc := coderdtest.DeploymentValues(t)
+		dc.Sessions.MaximumSessionsPerUser = 2
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues: dc,
+		})
+		first := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		anonymous := codersdk.New(client.URL)
+		for range 2 {
+			_, err := anonymous.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+				Email:    user.Email,
+				Password: coderdtest.FirstUserParams.Password,
+			})
+			require.NoError(t, err)
+		}
+
+		sessions, err := client.APISessions(ctx, user.ID.String())
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+
+		// The least recently used session was revoked.
+		_, err = member.User(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+}
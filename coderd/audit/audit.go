@@ -22,6 +22,10 @@ type AdditionalFields struct {
 	BuildReason    database.BuildReason `json:"build_reason"`
 	WorkspaceOwner string               `json:"workspace_owner"`
 	WorkspaceID    uuid.UUID            `json:"workspace_id"`
+
+	BuildReasonMessage  string `json:"build_reason_message,omitempty"`
+	InitiatorAPIKeyName string `json:"initiator_api_key_name,omitempty"`
+	InitiatorCIJobURL   string `json:"initiator_ci_job_url,omitempty"`
 }
 
 func NewNop() Auditor {
@@ -33,7 +33,14 @@ type Auditable interface {
 		idpsync.GroupSyncSettings |
 		idpsync.RoleSyncSettings |
 		database.WorkspaceAgent |
-		database.WorkspaceApp
+		database.WorkspaceApp |
+		database.AuditableTemplateVersionActivationRequest |
+		database.UserSecret |
+		database.TemplateSecret |
+		database.TemplateEgressPolicy |
+		database.WorkspacePortShareLink |
+		database.VariableSet |
+		database.TemplateVersionRollout
 }
 
 // Map is a map of changed fields in an audited resource. It maps field names to
@@ -11,6 +11,7 @@ type BackgroundSubsystem string
 
 const (
 	BackgroundSubsystemDormancy BackgroundSubsystem = "dormancy"
+	BackgroundSubsystemLDAPSync BackgroundSubsystem = "ldap_sync"
 )
 
 func BackgroundTaskFields(subsystem BackgroundSubsystem) map[string]string {
@@ -137,6 +137,20 @@ func ResourceTarget[T Auditable](tgt T) string {
 		return typed.Name
 	case database.WorkspaceApp:
 		return typed.Slug
+	case database.AuditableTemplateVersionActivationRequest:
+		return typed.TemplateVersionName
+	case database.UserSecret:
+		return typed.Name
+	case database.TemplateSecret:
+		return typed.Name
+	case database.TemplateEgressPolicy:
+		return typed.TemplateID.String()
+	case database.WorkspacePortShareLink:
+		return fmt.Sprintf("%s:%d", typed.AgentName, typed.Port)
+	case database.VariableSet:
+		return typed.Name
+	case database.TemplateVersionRollout:
+		return typed.TemplateID.String()
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceTarget", tgt))
 	}
@@ -203,6 +217,20 @@ func ResourceID[T Auditable](tgt T) uuid.UUID {
 		return typed.ID
 	case database.WorkspaceApp:
 		return typed.ID
+	case database.AuditableTemplateVersionActivationRequest:
+		return typed.TemplateVersionID
+	case database.UserSecret:
+		return typed.ID
+	case database.TemplateSecret:
+		return typed.ID
+	case database.TemplateEgressPolicy:
+		return typed.TemplateID
+	case database.WorkspacePortShareLink:
+		return typed.ID
+	case database.VariableSet:
+		return typed.ID
+	case database.TemplateVersionRollout:
+		return typed.TemplateID
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceID", tgt))
 	}
@@ -260,6 +288,20 @@ func ResourceType[T Auditable](tgt T) database.ResourceType {
 		return database.ResourceTypeWorkspaceAgent
 	case database.WorkspaceApp:
 		return database.ResourceTypeWorkspaceApp
+	case database.AuditableTemplateVersionActivationRequest:
+		return database.ResourceTypeTemplateVersionActivationRequest
+	case database.UserSecret:
+		return database.ResourceTypeUserSecret
+	case database.TemplateSecret:
+		return database.ResourceTypeTemplateSecret
+	case database.TemplateEgressPolicy:
+		return database.ResourceTypeTemplateEgressPolicy
+	case database.WorkspacePortShareLink:
+		return database.ResourceTypeWorkspacePortShareLink
+	case database.VariableSet:
+		return database.ResourceTypeVariableSet
+	case database.TemplateVersionRollout:
+		return database.ResourceTypeTemplateVersionRollout
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceType", typed))
 	}
@@ -320,6 +362,20 @@ func ResourceRequiresOrgID[T Auditable]() bool {
 		return true
 	case database.WorkspaceApp:
 		return true
+	case database.AuditableTemplateVersionActivationRequest:
+		return true
+	case database.UserSecret:
+		return false
+	case database.TemplateSecret:
+		return true
+	case database.TemplateEgressPolicy:
+		return true
+	case database.WorkspacePortShareLink:
+		return true
+	case database.VariableSet:
+		return false
+	case database.TemplateVersionRollout:
+		return true
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceRequiresOrgID", tgt))
 	}
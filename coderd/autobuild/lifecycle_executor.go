@@ -3,6 +3,7 @@ package autobuild
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"slices"
@@ -29,6 +30,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/coderd/workspacearchive"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/codersdk"
 )
@@ -49,14 +51,48 @@ type Executor struct {
 	notificationsEnqueuer notifications.Enqueuer
 	reg                   prometheus.Registerer
 	experiments           codersdk.Experiments
+	archiver              Archiver
 
 	metrics executorMetrics
 }
 
+// Archiver archives the persistent paths of dormant workspaces before they
+// are deleted.
+type Archiver interface {
+	// Archive starts archiving the paths of a running workspace. The outcome
+	// is recorded as a workspace archive, and workspaces are not deleted
+	// until an archive was recorded since they became dormant.
+	Archive(ctx context.Context, ws database.Workspace, paths []string) error
+}
+
 type executorMetrics struct {
-	autobuildExecutionDuration prometheus.Histogram
+	autobuildExecutionDuration  prometheus.Histogram
+	workspaceEvaluationDuration prometheus.Histogram
+	workspacesEvaluated         prometheus.Counter
+	transitionsEnqueued         *prometheus.CounterVec
+	workspacesSkipped           *prometheus.CounterVec
+	workspaceEvaluationErrors   prometheus.Counter
 }
 
+// Reasons a workspace returned by GetWorkspacesEligibleForTransition was not
+// transitioned, used as the reason label of
+// coderd_lifecycle_workspaces_skipped_total.
+const (
+	// skipReasonLocked means another replica is evaluating the workspace.
+	skipReasonLocked = "locked"
+	// skipReasonNotEligible means no transition was due once the workspace
+	// was evaluated again inside the transaction.
+	skipReasonNotEligible = "not_eligible"
+	// skipReasonSchedulePaused means the owner paused the schedule.
+	skipReasonSchedulePaused = "schedule_paused"
+	// skipReasonAwaitingBuild means a build must finish before the dormant
+	// workspace can be archived.
+	skipReasonAwaitingBuild = "awaiting_build"
+	// skipReasonAwaitingArchive means the dormant workspace is deleted once
+	// its persistent paths are archived.
+	skipReasonAwaitingArchive = "awaiting_archive"
+)
+
 // Stats contains information about one run of Executor.
 type Stats struct {
 	Transitions map[uuid.UUID]database.WorkspaceTransition
@@ -89,6 +125,39 @@ func NewExecutor(ctx context.Context, db database.Store, ps pubsub.Pubsub, fc *f
 				Help:      "Duration of each autobuild execution.",
 				Buckets:   prometheus.DefBuckets,
 			}),
+			workspaceEvaluationDuration: factory.NewHistogram(prometheus.HistogramOpts{
+				Namespace: "coderd",
+				Subsystem: "lifecycle",
+				Name:      "workspace_evaluation_duration_seconds",
+				Help:      "Duration of the evaluation of a single workspace, including enqueueing its transition.",
+				Buckets:   prometheus.DefBuckets,
+			}),
+			workspacesEvaluated: factory.NewCounter(prometheus.CounterOpts{
+				Namespace: "coderd",
+				Subsystem: "lifecycle",
+				Name:      "workspaces_evaluated_total",
+				Help:      "The number of workspaces evaluated for autostart, autostop, dormancy and deletion.",
+			}),
+			// The transition is "none" when a workspace is marked
+			// dormant without being stopped.
+			transitionsEnqueued: factory.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "coderd",
+				Subsystem: "lifecycle",
+				Name:      "transitions_total",
+				Help:      "The number of workspace transitions enqueued by the lifecycle executor, by transition and build reason.",
+			}, []string{"transition", "reason"}),
+			workspacesSkipped: factory.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "coderd",
+				Subsystem: "lifecycle",
+				Name:      "workspaces_skipped_total",
+				Help:      "The number of evaluated workspaces that were not transitioned, by reason.",
+			}, []string{"reason"}),
+			workspaceEvaluationErrors: factory.NewCounter(prometheus.CounterOpts{
+				Namespace: "coderd",
+				Subsystem: "lifecycle",
+				Name:      "workspace_evaluation_errors_total",
+				Help:      "The number of workspace evaluations that failed.",
+			}),
 		},
 	}
 	return le
@@ -101,6 +170,13 @@ func (e *Executor) WithStatsChannel(ch chan<- Stats) *Executor {
 	return e
 }
 
+// WithArchiver will cause Executor to archive the dormant archive paths of
+// templates before auto-deleting workspaces.
+func (e *Executor) WithArchiver(a Archiver) *Executor {
+	e.archiver = a
+	return e
+}
+
 // Run will cause executor to start or stop workspaces on every
 // tick from its channel. It will stop when its context is Done, or when
 // its channel is closed.
@@ -188,6 +264,12 @@ func (e *Executor) runOnce(t time.Time) Stats {
 		)
 
 		eg.Go(func() error {
+			start := time.Now()
+			e.metrics.workspacesEvaluated.Inc()
+			defer func() {
+				e.metrics.workspaceEvaluationDuration.Observe(time.Since(start).Seconds())
+			}()
+
 			err := func() error {
 				var (
 					job                   *database.ProvisionerJob
@@ -198,6 +280,12 @@ func (e *Executor) runOnce(t time.Time) Stats {
 					ws                    database.Workspace
 					tmpl                  database.Template
 					didAutoUpdate         bool
+					shouldArchive         bool
+					// skipReason, transition and buildReason are
+					// recorded in the metrics once the transaction commits.
+					skipReason  string
+					transition  database.WorkspaceTransition
+					buildReason database.BuildReason
 				)
 				err := e.db.InTx(func(tx database.Store) error {
 					var err error
@@ -208,6 +296,7 @@ func (e *Executor) runOnce(t time.Time) Stats {
 					}
 					if !ok {
 						log.Debug(e.ctx, "unable to acquire lock for workspace, skipping")
+						skipReason = skipReasonLocked
 						return nil
 					}
 
@@ -234,6 +323,17 @@ func (e *Executor) runOnce(t time.Time) Stats {
 						return xerrors.Errorf("get latest provisioner job: %w", err)
 					}
 
+					// A paused schedule suspends autostop, dormancy and auto-delete,
+					// e.g. while the owner of the workspace is on leave.
+					var pausedUntil time.Time
+					pause, err := tx.GetWorkspaceSchedulePauseByWorkspaceID(e.ctx, ws.ID)
+					if err != nil && !errors.Is(err, sql.ErrNoRows) {
+						return xerrors.Errorf("get workspace schedule pause: %w", err)
+					}
+					if err == nil {
+						pausedUntil = pause.PausedUntil
+					}
+
 					templateSchedule, err := (*(e.templateScheduleStore.Load())).Get(e.ctx, tx, ws.TemplateID)
 					if err != nil {
 						return xerrors.Errorf("get template scheduling options: %w", err)
@@ -268,15 +368,61 @@ func (e *Executor) runOnce(t time.Time) Stats {
 
 					accessControl := (*(e.accessControlStore.Load())).GetTemplateAccessControl(tmpl)
 
-					nextTransition, reason, err := getNextTransition(user, ws, latestBuild, latestJob, templateSchedule, currentTick)
+					// The end of a pause counts as workspace activity, so that
+					// workspaces don't become dormant as soon as their pause ends.
+					scheduleWs := ws
+					if pausedUntil.After(scheduleWs.LastUsedAt) {
+						scheduleWs.LastUsedAt = pausedUntil
+					}
+
+					nextTransition, reason, err := getNextTransition(user, scheduleWs, latestBuild, latestJob, templateSchedule, currentTick)
 					if err != nil {
 						log.Debug(e.ctx, "skipping workspace", slog.Error(err))
 						// err is used to indicate that a workspace is not eligible
 						// so returning nil here is ok although ultimately the distinction
 						// doesn't matter since the transaction is  read-only up to
 						// this point.
+						skipReason = skipReasonNotEligible
 						return nil
 					}
+					if pausedUntil.After(currentTick) && reason != database.BuildReasonAutostart {
+						log.Debug(e.ctx, "skipping workspace, schedule paused",
+							slog.F("reason", reason),
+							slog.F("paused_until", pausedUntil),
+						)
+						skipReason = skipReasonSchedulePaused
+						return nil
+					}
+
+					// Archive the persistent paths of dormant workspaces before
+					// deleting them. The agent streams the archive, so stopped
+					// workspaces are started first.
+					if reason == database.BuildReasonAutodelete && e.archiver != nil && len(tmpl.DormantArchivePaths) > 0 {
+						archived, err := archivedSinceDormant(e.ctx, tx, ws)
+						if err != nil {
+							return err
+						}
+						if !archived {
+							startedForArchive, err := startedForArchive(e.ctx, tx, ws, latestBuild)
+							if err != nil {
+								return err
+							}
+							switch {
+							case !latestJob.Finished():
+								log.Debug(e.ctx, "skipping workspace, waiting for build to finish before archiving")
+								skipReason = skipReasonAwaitingBuild
+								return nil
+							case latestBuild.Transition == database.WorkspaceTransitionStop &&
+								latestJob.JobStatus == database.ProvisionerJobStatusSucceeded &&
+								!startedForArchive:
+								nextTransition, reason = database.WorkspaceTransitionStart, database.BuildReasonAutoarchive
+							default:
+								shouldArchive = true
+								skipReason = skipReasonAwaitingArchive
+								return nil
+							}
+						}
+					}
 
 					if nextTransition != "" {
 						builder := wsbuilder.New(ws, nextTransition).
@@ -341,6 +487,7 @@ func (e *Executor) runOnce(t time.Time) Stats {
 						)
 					}
 
+					transition, buildReason = nextTransition, reason
 					if nextTransition == "" {
 						return nil
 					}
@@ -362,6 +509,18 @@ func (e *Executor) runOnce(t time.Time) Stats {
 					Isolation:    sql.LevelRepeatableRead,
 					TxIdentifier: "lifecycle",
 				})
+				if err == nil {
+					switch {
+					case skipReason != "":
+						e.metrics.workspacesSkipped.WithLabelValues(skipReason).Inc()
+					case buildReason != "":
+						transitionLabel := string(transition)
+						if transitionLabel == "" {
+							transitionLabel = "none"
+						}
+						e.metrics.transitionsEnqueued.WithLabelValues(transitionLabel, string(buildReason)).Inc()
+					}
+				}
 				if auditLog != nil {
 					// If the transition didn't succeed then updating the workspace
 					// to indicate dormant didn't either.
@@ -406,6 +565,16 @@ func (e *Executor) runOnce(t time.Time) Stats {
 						return xerrors.Errorf("post provisioner job to pubsub: %w", err)
 					}
 				}
+				if shouldArchive {
+					err = e.archiver.Archive(e.ctx, ws, tmpl.DormantArchivePaths)
+					if errors.Is(err, workspacearchive.ErrAgentNotReady) {
+						log.Debug(e.ctx, "waiting for agent to become ready before archiving")
+						err = nil
+					}
+					if err != nil {
+						return xerrors.Errorf("archive workspace: %w", err)
+					}
+				}
 				if shouldNotifyDormancy {
 					dormantTime := dbtime.Now().Add(time.Duration(tmpl.TimeTilDormant))
 					_, err = e.notificationsEnqueuer.Enqueue(
@@ -431,6 +600,7 @@ func (e *Executor) runOnce(t time.Time) Stats {
 			}()
 			if err != nil && !xerrors.Is(err, context.Canceled) {
 				log.Error(e.ctx, "failed to transition workspace", slog.Error(err))
+				e.metrics.workspaceEvaluationErrors.Inc()
 				statsMu.Lock()
 				stats.Errors[wsID] = err
 				statsMu.Unlock()
@@ -594,6 +764,36 @@ func isEligibleForFailedStop(build database.WorkspaceBuild, job database.Provisi
 		currentTick.Sub(job.CompletedAt.Time) > templateSchedule.FailureTTL
 }
 
+// archivedSinceDormant returns true if an archive, successful or not, was
+// recorded for the workspace since it became dormant.
+func archivedSinceDormant(ctx context.Context, db database.Store, ws database.Workspace) (bool, error) {
+	archive, err := db.GetLatestWorkspaceArchiveByWorkspaceID(ctx, ws.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, xerrors.Errorf("get latest workspace archive: %w", err)
+	}
+	return !archive.CreatedAt.Before(ws.DormantAt.Time), nil
+}
+
+// startedForArchive returns true if the build before the latest build started
+// the workspace for archiving, e.g. because the start failed and the workspace
+// was stopped again. The workspace is not started again to avoid a loop.
+func startedForArchive(ctx context.Context, db database.Store, ws database.Workspace, latestBuild database.WorkspaceBuild) (bool, error) {
+	if latestBuild.BuildNumber <= 1 {
+		return false, nil
+	}
+	previous, err := db.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams{
+		WorkspaceID: ws.ID,
+		BuildNumber: latestBuild.BuildNumber - 1,
+	})
+	if err != nil {
+		return false, xerrors.Errorf("get previous workspace build: %w", err)
+	}
+	return previous.Reason == database.BuildReasonAutoarchive && !previous.CreatedAt.Before(ws.DormantAt.Time), nil
+}
+
 type auditParams struct {
 	Old     database.WorkspaceTable
 	New     database.WorkspaceTable
@@ -13,6 +13,7 @@ import (
 	"github.com/coder/quartz"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
@@ -22,14 +23,18 @@ import (
 
 	"github.com/coder/coder/v2/coderd/autobuild"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/coderdtest/promhelp"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
+	"github.com/coder/coder/v2/coderd/objectstore"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/workspacearchive"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionersdk/proto"
@@ -496,6 +501,101 @@ func TestExecutorAutostopExtend(t *testing.T) {
 	assert.Equal(t, database.WorkspaceTransitionStop, stats.Transitions[workspace.ID])
 }
 
+func TestExecutorAutostopSchedulePaused(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan autobuild.Stats)
+		client  = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:          tickCh,
+			IncludeProvisionerDaemon: true,
+			AutobuildStats:           statsCh,
+		})
+		// Given: we have a user with a workspace
+		workspace = mustProvisionWorkspace(t, client)
+	)
+	// Given: workspace is running
+	require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+	require.NotZero(t, workspace.LatestBuild.Deadline)
+
+	// Given: the schedule of the workspace is paused past the deadline
+	pausedUntil := workspace.LatestBuild.Deadline.Time.Add(time.Hour)
+	_, err := client.PutWorkspaceSchedulePause(ctx, workspace.ID, codersdk.PutWorkspaceSchedulePauseRequest{
+		PausedUntil: pausedUntil,
+	})
+	require.NoError(t, err)
+
+	// When: the autobuild executor ticks after the deadline, and after the
+	// pause ends:
+	go func() {
+		tickCh <- workspace.LatestBuild.Deadline.Time.Add(time.Minute)
+		tickCh <- pausedUntil.Add(time.Minute)
+		close(tickCh)
+	}()
+
+	// Then: the workspace should not be stopped while paused
+	stats := <-statsCh
+	assert.Len(t, stats.Errors, 0)
+	assert.Len(t, stats.Transitions, 0)
+
+	// Then: the workspace should be stopped once the pause ends
+	stats = <-statsCh
+	assert.Len(t, stats.Errors, 0)
+	assert.Len(t, stats.Transitions, 1)
+	assert.Equal(t, database.WorkspaceTransitionStop, stats.Transitions[workspace.ID])
+}
+
+func TestExecutorMetrics(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan autobuild.Stats)
+		reg     = prometheus.NewRegistry()
+		client  = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:          tickCh,
+			IncludeProvisionerDaemon: true,
+			AutobuildStats:           statsCh,
+			AutobuildRegistry:        reg,
+		})
+		// Given: we have a user with a workspace
+		workspace = mustProvisionWorkspace(t, client)
+	)
+	// Given: the schedule of the workspace is paused past the deadline
+	pausedUntil := workspace.LatestBuild.Deadline.Time.Add(time.Hour)
+	_, err := client.PutWorkspaceSchedulePause(ctx, workspace.ID, codersdk.PutWorkspaceSchedulePauseRequest{
+		PausedUntil: pausedUntil,
+	})
+	require.NoError(t, err)
+
+	go func() {
+		tickCh <- workspace.LatestBuild.Deadline.Time.Add(time.Minute)
+		tickCh <- pausedUntil.Add(time.Minute)
+		close(tickCh)
+	}()
+
+	// Then: the workspace is skipped while its schedule is paused
+	stats := <-statsCh
+	assert.Len(t, stats.Transitions, 0)
+	require.Equal(t, 1, promhelp.CounterValue(t, reg, "coderd_lifecycle_workspaces_evaluated_total", nil))
+	require.Equal(t, 1, promhelp.CounterValue(t, reg, "coderd_lifecycle_workspaces_skipped_total", prometheus.Labels{
+		"reason": "schedule_paused",
+	}))
+
+	// Then: the stop is counted once the pause ends
+	stats = <-statsCh
+	assert.Len(t, stats.Transitions, 1)
+	require.Equal(t, 2, promhelp.CounterValue(t, reg, "coderd_lifecycle_workspaces_evaluated_total", nil))
+	require.Equal(t, 1, promhelp.CounterValue(t, reg, "coderd_lifecycle_transitions_total", prometheus.Labels{
+		"transition": "stop",
+		"reason":     "autostop",
+	}))
+	require.EqualValues(t, 2, promhelp.HistogramValue(t, reg, "coderd_lifecycle_workspace_evaluation_duration_seconds", nil).GetSampleCount())
+}
+
 func TestExecutorAutostopAlreadyStopped(t *testing.T) {
 	t.Parallel()
 
@@ -903,6 +1003,46 @@ func TestExecutorAutostartTemplateDisabled(t *testing.T) {
 	assert.Len(t, stats.Transitions, 0)
 }
 
+func TestExecutorAutostartHoliday(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sched   = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan autobuild.Stats)
+		now     = time.Now().UTC()
+		dv      = coderdtest.DeploymentValues(t)
+	)
+	// Given: today and tomorrow are deployment holidays
+	require.NoError(t, dv.AutostartHolidays.Set(now.Format(schedule.HolidayDateFormat)))
+	require.NoError(t, dv.AutostartHolidays.Append(now.AddDate(0, 0, 1).Format(schedule.HolidayDateFormat)))
+	var (
+		client = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:          tickCh,
+			IncludeProvisionerDaemon: true,
+			AutobuildStats:           statsCh,
+			DeploymentValues:         dv,
+		})
+		// Given: we have a user with a workspace that has autostart enabled
+		workspace = mustProvisionWorkspace(t, client, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.AutostartSchedule = ptr.Ref(sched.String())
+		})
+	)
+	// Given: workspace is stopped
+	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
+
+	// When: the autobuild executor ticks after the scheduled time
+	go func() {
+		tickCh <- sched.Next(workspace.LatestBuild.CreatedAt)
+		close(tickCh)
+	}()
+
+	// Then: nothing should happen
+	stats := <-statsCh
+	assert.Len(t, stats.Errors, 0)
+	assert.Len(t, stats.Transitions, 0)
+}
+
 func TestExecutorAutostopTemplateDisabled(t *testing.T) {
 	t.Parallel()
 
@@ -1120,6 +1260,97 @@ func TestExecutorInactiveWorkspace(t *testing.T) {
 	})
 }
 
+func TestExecutorDormantArchive(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = testutil.Context(t, testutil.WaitLong)
+		db, ps   = dbtestutil.NewDB(t)
+		tickCh   = make(chan time.Time)
+		statsCh  = make(chan autobuild.Stats)
+		archiver = newTestArchiver(t, db)
+		client   = coderdtest.New(t, &coderdtest.Options{
+			Database:                 db,
+			Pubsub:                   ps,
+			AutobuildTicker:          tickCh,
+			IncludeProvisionerDaemon: true,
+			AutobuildStats:           statsCh,
+			AutobuildArchiver:        archiver,
+			TemplateScheduleStore: schedule.MockTemplateScheduleStore{
+				GetFn: func(_ context.Context, _ database.Store, _ uuid.UUID) (schedule.TemplateScheduleOptions, error) {
+					return schedule.TemplateScheduleOptions{
+						TimeTilDormantAutoDelete: time.Minute,
+					}, nil
+				},
+			},
+		})
+		// Given: we have a user with a workspace
+		workspace = mustProvisionWorkspace(t, client)
+	)
+	// Given: the template archives a path before auto-deleting dormant
+	// workspaces
+	_, err := client.UpdateTemplateMeta(ctx, workspace.TemplateID, codersdk.UpdateTemplateMeta{
+		DormantArchivePaths: &[]string{"~/project"},
+	})
+	require.NoError(t, err)
+	tmpl, err := db.GetTemplateByID(ctx, workspace.TemplateID)
+	require.NoError(t, err)
+	err = db.UpdateTemplateScheduleByID(ctx, database.UpdateTemplateScheduleByIDParams{
+		ID:                       tmpl.ID,
+		UpdatedAt:                tmpl.UpdatedAt,
+		AllowUserAutostart:       tmpl.AllowUserAutostart,
+		AllowUserAutostop:        tmpl.AllowUserAutostop,
+		DefaultTTL:               tmpl.DefaultTTL,
+		ActivityBump:             tmpl.ActivityBump,
+		AutostartBlockDaysOfWeek: tmpl.AutostartBlockDaysOfWeek,
+		TimeTilDormantAutoDelete: int64(time.Minute),
+	})
+	require.NoError(t, err)
+
+	// Given: the workspace is stopped and has been dormant for longer than
+	// the auto-delete threshold
+	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop)
+	_, err = db.UpdateWorkspaceDormantDeletingAt(ctx, database.UpdateWorkspaceDormantDeletingAtParams{
+		ID:        workspace.ID,
+		DormantAt: sql.NullTime{Time: dbtime.Now().Add(-time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+
+	// When: the autobuild executor ticks
+	tickCh <- time.Now()
+
+	// Then: the workspace is started to archive it
+	stats := testutil.RequireReceive(ctx, t, statsCh)
+	require.Len(t, stats.Errors, 0)
+	require.Equal(t, database.WorkspaceTransitionStart, stats.Transitions[workspace.ID])
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.EqualValues(t, database.BuildReasonAutoarchive, workspace.LatestBuild.Reason)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	// When: the autobuild executor ticks again
+	tickCh <- time.Now()
+
+	// Then: the workspace is archived, which fails since it has no agents,
+	// and is not deleted yet
+	stats = testutil.RequireReceive(ctx, t, statsCh)
+	require.Len(t, stats.Errors, 0)
+	require.Len(t, stats.Transitions, 0)
+	archives, err := client.WorkspaceArchives(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Len(t, archives, 1)
+	require.Equal(t, workspace.ID, archives[0].WorkspaceID)
+	require.Equal(t, []string{"~/project"}, archives[0].Paths)
+	require.Equal(t, "workspace has no agents", archives[0].Error)
+
+	// When: the autobuild executor ticks once more
+	tickCh <- time.Now()
+
+	// Then: the workspace is deleted
+	stats = testutil.RequireReceive(ctx, t, statsCh)
+	require.Len(t, stats.Errors, 0)
+	require.Equal(t, database.WorkspaceTransitionDelete, stats.Transitions[workspace.ID])
+}
+
 func TestNotifications(t *testing.T) {
 	t.Parallel()
 
@@ -1566,6 +1797,20 @@ func mustProvisionWorkspaceWithParameters(t *testing.T, client *codersdk.Client,
 	return coderdtest.MustWorkspace(t, client, ws.ID)
 }
 
+func newTestArchiver(t *testing.T, db database.Store) *workspacearchive.Archiver {
+	t.Helper()
+	store, err := objectstore.NewDirStore(t.TempDir())
+	require.NoError(t, err)
+	archiver := workspacearchive.New(context.Background(), workspacearchive.Options{
+		Database:  db,
+		Store:     store,
+		Logger:    slogtest.Make(t, nil),
+		Retention: time.Hour,
+	})
+	t.Cleanup(func() { _ = archiver.Close() })
+	return archiver
+}
+
 func mustSchedule(t *testing.T, s string) *cron.Schedule {
 	t.Helper()
 	sched, err := cron.Weekly(s)
@@ -35,7 +35,6 @@ import (
 	"storj.io/drpc/drpcmux"
 	"storj.io/drpc/drpcserver"
 	"tailscale.com/derp"
-	"tailscale.com/derp/derphttp"
 	"tailscale.com/tailcfg"
 	"tailscale.com/types/key"
 	"tailscale.com/util/singleflight"
@@ -46,15 +45,19 @@ import (
 
 	"github.com/coder/coder/v2/codersdk/drpcsdk"
 
+	"github.com/coder/coder/v2/coderd/configreload"
+	"github.com/coder/coder/v2/coderd/connectionquality"
 	"github.com/coder/coder/v2/coderd/cryptokeys"
 	"github.com/coder/coder/v2/coderd/entitlements"
 	"github.com/coder/coder/v2/coderd/files"
 	"github.com/coder/coder/v2/coderd/idpsync"
+	"github.com/coder/coder/v2/coderd/internalca"
 	"github.com/coder/coder/v2/coderd/runtimeconfig"
 	"github.com/coder/coder/v2/coderd/webpush"
 
 	agentproto "github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/coderd/alerting"
 	_ "github.com/coder/coder/v2/coderd/apidoc" // Used for swagger docs.
 	"github.com/coder/coder/v2/coderd/appearance"
 	"github.com/coder/coder/v2/coderd/audit"
@@ -64,6 +67,8 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbrollup"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/diagnostics"
+	"github.com/coder/coder/v2/coderd/experimentflags"
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/healthcheck"
@@ -72,7 +77,10 @@ import (
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/httpmw/loggermw"
 	"github.com/coder/coder/v2/coderd/metricscache"
+	"github.com/coder/coder/v2/coderd/networkzone"
 	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/objectstore"
+	"github.com/coder/coder/v2/coderd/parameteroptions"
 	"github.com/coder/coder/v2/coderd/portsharing"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
@@ -82,11 +90,15 @@ import (
 	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
+	"github.com/coder/coder/v2/coderd/templatepolicy"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/updatecheck"
 	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/coderd/vault"
+	"github.com/coder/coder/v2/coderd/versionpolicy"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
+	"github.com/coder/coder/v2/coderd/workspacearchive"
 	"github.com/coder/coder/v2/coderd/workspacestats"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/healthsdk"
@@ -136,6 +148,10 @@ var expDERPOnce = sync.Once{}
 // Options are requires parameters for Coder to start.
 type Options struct {
 	AccessURL *url.URL
+	// PathAppAccessURL is an optional URL that path-based apps are served from
+	// instead of AccessURL. It must use a different hostname than AccessURL so
+	// that path apps run on a separate origin from the dashboard.
+	PathAppAccessURL *url.URL
 	// AppHostname should be the wildcard hostname to use for workspace
 	// applications INCLUDING the asterisk, (optional) suffix and leading dot.
 	// It will use the same scheme and port number as the access URL.
@@ -162,12 +178,14 @@ type Options struct {
 	GoogleTokenValidator           *idtoken.Validator
 	GithubOAuth2Config             *GithubOAuth2Config
 	OIDCConfig                     *OIDCConfig
+	SAMLConfig                     *SAMLConfig
 	PrometheusRegistry             *prometheus.Registry
 	StrictTransportSecurityCfg     httpmw.HSTSConfig
 	SSHKeygenAlgorithm             gitsshkey.Algorithm
 	Telemetry                      telemetry.Reporter
 	TracerProvider                 trace.TracerProvider
 	ExternalAuthConfigs            []*externalauth.Config
+	TemplatePolicy                 *templatepolicy.Engine
 	RealIPConfig                   *httpmw.RealIPConfig
 	TrialGenerator                 func(ctx context.Context, body codersdk.LicensorTrialRequest) error
 	// RefreshEntitlements is used to set correct entitlements after creating first user and generating trial license.
@@ -187,7 +205,10 @@ type Options struct {
 	DERPServer         *derp.Server
 	// BaseDERPMap is used as the base DERP map for all clients and agents.
 	// Proxies are added to this list.
-	BaseDERPMap                    *tailcfg.DERPMap
+	BaseDERPMap *tailcfg.DERPMap
+	// BaseDERPMapFn, if set, is called to get the current base DERP map instead
+	// of using BaseDERPMap. This allows the base map to be reloaded at runtime.
+	BaseDERPMapFn                  func() *tailcfg.DERPMap
 	DERPMapUpdateFrequency         time.Duration
 	NetworkTelemetryBatchFrequency time.Duration
 	NetworkTelemetryBatchMaxSize   int
@@ -198,6 +219,19 @@ type Options struct {
 	// CoordinatorResumeTokenProvider is used to provide and validate resume
 	// tokens issued by and passed to the coordinator DRPC API.
 	CoordinatorResumeTokenProvider tailnet.ResumeTokenProvider
+	// AutostartHolidays are applied to every template schedule store. If nil,
+	// they are read from the deployment values.
+	AutostartHolidays *schedule.AutostartHolidays
+	// NetworkZones restrict the source addresses of API requests. If nil,
+	// they are read from the deployment values.
+	NetworkZones networkzone.Zones
+	// VersionPolicy holds the minimum versions of agents and CLIs. If nil, it
+	// is read from the deployment values.
+	VersionPolicy *versionpolicy.Policy
+
+	// LogBuffer holds the recent logs of this replica for support bundles.
+	// If nil, diagnostics do not include logs.
+	LogBuffer *diagnostics.LogBuffer
 
 	HealthcheckFunc              func(ctx context.Context, apiKey string) *healthsdk.HealthcheckReport
 	HealthcheckTimeout           time.Duration
@@ -256,6 +290,28 @@ type Options struct {
 	WorkspaceUsageTracker *workspacestats.UsageTracker
 	// NotificationsEnqueuer handles enqueueing notifications for delivery by SMTP, webhook, etc.
 	NotificationsEnqueuer notifications.Enqueuer
+	// Alerter sends deployment health alerts to incident management services.
+	// It is nil when alerting is not configured.
+	Alerter *alerting.Alerter
+	// VaultIssuer issues Vault tokens to workspace agents. It is nil when
+	// the Vault integration is not configured.
+	VaultIssuer *vault.Issuer
+	// InternalCA issues client certificates to agents, workspace proxies
+	// and provisioner daemons. It is nil when the internal CA is disabled.
+	InternalCA *internalca.CA
+	// WorkspaceArchiveStore stores the archives of dormant workspaces which
+	// are auto-deleted. It is nil when archiving is not configured.
+	WorkspaceArchiveStore objectstore.Store
+	// FileStore stores the contents of uploaded files, such as template
+	// source archives. Files are stored in the database when it is nil.
+	FileStore objectstore.Store
+	// ReloadDeploymentValues reads the deployment configuration again, e.g.
+	// from the config file. Options that did not change keep their running
+	// values. Reloading the configuration is disabled if it is nil.
+	ReloadDeploymentValues func() (*codersdk.DeploymentValues, error)
+	// DeploymentSettings is the settings manifest read from the settings
+	// file. It is nil if no settings file is configured.
+	DeploymentSettings *codersdk.DeploymentSettingsManifest
 
 	// IDPSync holds all configured values for syncing external IDP users into Coder.
 	IDPSync idpsync.IDPSync
@@ -333,6 +389,10 @@ func New(options *Options) *API {
 		options.AccessControlStore.Store(&tacs)
 	}
 
+	if options.FileStore != nil {
+		options.Database = files.WithObjectStore(options.Database, options.FileStore)
+	}
+
 	options.Database = dbauthz.New(
 		options.Database,
 		options.Authorizer,
@@ -409,8 +469,34 @@ func New(options *Options) *API {
 	if options.TemplateScheduleStore == nil {
 		options.TemplateScheduleStore = &atomic.Pointer[schedule.TemplateScheduleStore]{}
 	}
+	if options.AutostartHolidays == nil {
+		holidays, err := schedule.NewAutostartHolidays(
+			options.Logger.Named("autostart_holidays"),
+			options.Clock,
+			options.DeploymentValues.AutostartHolidays.Value(),
+			options.DeploymentValues.AutostartHolidayCalendarURL.Value(),
+		)
+		if err != nil {
+			panic(xerrors.Errorf("parse autostart holidays: %w", err))
+		}
+		options.AutostartHolidays = holidays
+	}
+	if options.NetworkZones == nil {
+		zones, err := networkzone.New(options.DeploymentValues.NetworkZones.Value)
+		if err != nil {
+			panic(xerrors.Errorf("parse network zones: %w", err))
+		}
+		options.NetworkZones = zones
+	}
+	if options.VersionPolicy == nil {
+		versionPolicy, err := versionpolicy.New(options.DeploymentValues.MinAgentVersion.Value(), options.DeploymentValues.MinCLIVersion.Value())
+		if err != nil {
+			panic(xerrors.Errorf("parse version policy: %w", err))
+		}
+		options.VersionPolicy = &versionPolicy
+	}
 	if options.TemplateScheduleStore.Load() == nil {
-		v := schedule.NewAGPLTemplateScheduleStore()
+		v := schedule.NewHolidayTemplateScheduleStore(schedule.NewAGPLTemplateScheduleStore(), options.AutostartHolidays)
 		options.TemplateScheduleStore.Store(&v)
 	}
 	if options.UserQuietHoursScheduleStore == nil {
@@ -577,11 +663,21 @@ func New(options *Options) *API {
 		Experiments:                 experiments,
 		WebpushDispatcher:           options.WebPushDispatcher,
 		healthCheckGroup:            &singleflight.Group[string, *healthsdk.HealthcheckReport]{},
+		shutdownRequested:           make(chan struct{}),
 		Acquirer: provisionerdserver.NewAcquirer(
 			ctx,
 			options.Logger.Named("acquirer"),
 			options.Database,
 			options.Pubsub,
+			provisionerdserver.BuildConcurrencyLimits(
+				options.DeploymentValues.Provisioner.MaxBuildsPerUser.Value(),
+				options.DeploymentValues.Provisioner.MaxBuildsPerTemplate.Value(),
+			),
+			provisionerdserver.OrganizationJobLimits(
+				options.DeploymentValues.Provisioner.MaxConcurrentJobs.Value(),
+				options.DeploymentValues.Provisioner.MaxJobsPerOrganization.Value(),
+				options.DeploymentValues.Provisioner.ReservedJobsPerOrganization.Value(),
+			),
 		),
 		dbRolluper: options.DatabaseRolluper,
 	}
@@ -629,7 +725,20 @@ func New(options *Options) *API {
 		Logger:            options.Logger.Named("site"),
 		HideAITasks:       options.DeploymentValues.HideAITasks.Value(),
 	})
-	api.SiteHandler.Experiments.Store(&experiments)
+	api.ExperimentFlags, err = experimentflags.New(api.ctx, experimentflags.Options{
+		Logger:   options.Logger.Named("experimentflags"),
+		Database: options.Database,
+		Pubsub:   options.Pubsub,
+		Startup:  experiments,
+	})
+	if err != nil {
+		panic("failed to start experiment flags: " + err.Error())
+	}
+	siteExperiments := api.ExperimentFlags.Deployment()
+	api.SiteHandler.Experiments.Store(&siteExperiments)
+	api.ExperimentFlags.OnChange(func(deployment codersdk.Experiments) {
+		api.SiteHandler.Experiments.Store(&deployment)
+	})
 
 	if options.UpdateCheckOptions != nil {
 		api.updateChecker = updatecheck.New(
@@ -711,9 +820,73 @@ func New(options *Options) *API {
 		panic("failed to setup server tailnet: " + err.Error())
 	}
 	api.agentProvider = stn
+	if options.WorkspaceArchiveStore != nil {
+		api.WorkspaceArchiver = workspacearchive.New(api.ctx, workspacearchive.Options{
+			Database: options.Database,
+			Store:    options.WorkspaceArchiveStore,
+			Logger:   options.Logger.Named("workspacearchive"),
+			DialAgent: func(ctx context.Context, agentID uuid.UUID) (workspacearchive.AgentConn, func(), error) {
+				conn, release, err := api.agentProvider.AgentConn(ctx, agentID)
+				if err != nil {
+					return nil, nil, err
+				}
+				return conn, release, nil
+			},
+			Retention:                      options.DeploymentValues.DormantWorkspaceArchiveRetention.Value(),
+			AgentInactiveDisconnectTimeout: options.AgentInactiveDisconnectTimeout,
+		})
+	}
+	if options.FileStore != nil {
+		api.fileObjectCollector = files.CollectObjects(api.ctx, options.Logger.Named("fileobjects"), options.Database, options.FileStore, options.Clock)
+	}
+	api.ConfigReloader, err = configreload.New(api.ctx, configreload.Options{
+		Logger:    options.Logger.Named("configreload"),
+		Pubsub:    options.Pubsub,
+		ReplicaID: api.ID,
+		Values:    options.DeploymentValues,
+	})
+	if err != nil {
+		panic("failed to start deployment config reloader: " + err.Error())
+	}
+	if s, ok := options.IDPSync.(interface {
+		SetLegacyGroupMapping(mapping map[string]string)
+	}); ok {
+		api.ConfigReloader.Register("OIDC Group Mapping", func(_ context.Context, vals *codersdk.DeploymentValues) error {
+			s.SetLegacyGroupMapping(vals.OIDC.GroupMapping.Value)
+			return nil
+		})
+	}
+	api.aiTaskQueueNotify = make(chan struct{}, 1)
+	api.aiTaskQueueDone = make(chan struct{})
+	go api.runAITaskQueue(api.ctx)
+	api.ParameterOptionsFetcher = parameteroptions.NewFetcher(
+		options.Logger.Named("parameteroptions"),
+		options.Clock,
+		options.HTTPClient,
+	)
 	if options.DeploymentValues.Prometheus.Enable {
 		options.PrometheusRegistry.MustRegister(stn)
 	}
+	api.healthMonitor = healthcheck.NewMonitor(api.ctx, healthcheck.MonitorOptions{
+		ReplicaID: api.ID,
+		Database:  options.Database,
+		Logger:    options.Logger.Named("healthmonitor"),
+		Interval:  options.HealthcheckRefresh,
+		Check: func(ctx context.Context) *healthsdk.HealthcheckReport {
+			ctx, cancel := context.WithTimeout(ctx, options.HealthcheckTimeout)
+			defer cancel()
+			// Without an API key the websocket check uses the
+			// unauthenticated echo endpoint.
+			return api.HealthcheckFunc(ctx, "")
+		},
+	})
+	if options.DeploymentValues.Prometheus.Enable {
+		options.PrometheusRegistry.MustRegister(api.healthMonitor)
+	}
+	api.connectionQuality = connectionquality.NewTracker(options.Database, options.Logger.Named("connectionquality"), quartz.NewReal(), connectionquality.DefaultStaleAfter)
+	if options.DeploymentValues.Prometheus.Enable {
+		options.PrometheusRegistry.MustRegister(api.connectionQuality)
+	}
 	api.NetworkTelemetryBatcher = tailnet.NewNetworkTelemetryBatcher(
 		quartz.NewReal(),
 		api.Options.NetworkTelemetryBatchFrequency,
@@ -744,7 +917,11 @@ func New(options *Options) *API {
 		StatsBatcher:          options.StatsBatcher,
 		UsageTracker:          options.WorkspaceUsageTracker,
 		UpdateAgentMetricsFn:  options.UpdateAgentMetrics,
-		AppStatBatchSize:      workspaceapps.DefaultStatsDBReporterBatchSize,
+		ResourceActivity: workspacestats.ResourceActivityThresholds{
+			CPUCores:              options.DeploymentValues.WorkspaceActivityCPUThreshold.Value(),
+			NetworkBytesPerSecond: options.DeploymentValues.WorkspaceActivityNetworkThreshold.Value(),
+		},
+		AppStatBatchSize: workspaceapps.DefaultStatsDBReporterBatchSize,
 	})
 	workspaceAppsLogger := options.Logger.Named("workspaceapps")
 	if options.WorkspaceAppsStatsCollectorOptions.Logger == nil {
@@ -759,7 +936,7 @@ func New(options *Options) *API {
 		Logger: workspaceAppsLogger,
 
 		DashboardURL:  api.AccessURL,
-		AccessURL:     api.AccessURL,
+		AccessURL:     api.PathAppURL(),
 		Hostname:      api.AppHostname,
 		HostnameRegex: api.AppHostnameRegex,
 		RealIPConfig:  options.RealIPConfig,
@@ -769,7 +946,10 @@ func New(options *Options) *API {
 		StatsCollector:      workspaceapps.NewStatsCollector(options.WorkspaceAppsStatsCollectorOptions),
 
 		DisablePathApps:          options.DeploymentValues.DisablePathApps.Value(),
+		PathAppSubdomains:        api.pathAppSubdomains(),
+		PathAppSubdomainKeycache: options.AppSigningKeyCache,
 		Cookies:                  options.DeploymentValues.HTTPCookies,
+		DesktopClipboardPolicy:   codersdk.DesktopClipboardPolicy(options.DeploymentValues.WebDesktopClipboard),
 		APIKeyEncryptionKeycache: options.AppEncryptionKeyCache,
 	}
 
@@ -782,6 +962,9 @@ func New(options *Options) *API {
 		Optional:                      false,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
+		NetworkZones:                  options.NetworkZones,
+		NetworkZoneDenied:             api.auditNetworkZoneViolation,
+		ClientVersion:                 api.checkClientVersion,
 		Logger:                        options.Logger,
 	})
 	// Same as above but it redirects to the login page.
@@ -793,6 +976,9 @@ func New(options *Options) *API {
 		Optional:                      false,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
+		NetworkZones:                  options.NetworkZones,
+		NetworkZoneDenied:             api.auditNetworkZoneViolation,
+		ClientVersion:                 api.checkClientVersion,
 		Logger:                        options.Logger,
 	})
 	// Same as the first but it's optional.
@@ -804,6 +990,9 @@ func New(options *Options) *API {
 		Optional:                      true,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
+		NetworkZones:                  options.NetworkZones,
+		NetworkZoneDenied:             api.auditNetworkZoneViolation,
+		ClientVersion:                 api.checkClientVersion,
 		Logger:                        options.Logger,
 	})
 
@@ -814,11 +1003,20 @@ func New(options *Options) *API {
 
 	// API rate limit middleware. The counter is local and not shared between
 	// replicas or instances of this middleware.
-	apiRateLimiter := httpmw.RateLimit(options.APIRateLimit, time.Minute)
+	reloadableAPIRateLimit := httpmw.NewReloadableRateLimit(options.APIRateLimit, time.Minute)
+	apiRateLimiter := reloadableAPIRateLimit.Handler
+	api.ConfigReloader.Register("API Rate Limit", func(_ context.Context, vals *codersdk.DeploymentValues) error {
+		count := int(vals.RateLimit.API.Value())
+		if vals.RateLimit.DisableAll {
+			count = -1
+		}
+		reloadableAPIRateLimit.SetCount(count)
+		return nil
+	})
 
 	// Register DERP on expvar HTTP handler, which we serve below in the router, c.f. expvar.Handler()
-	// These are the metrics the DERP server exposes.
-	// TODO: export via prometheus
+	// These are the metrics the DERP server exposes. A subset of them is also
+	// exported via Prometheus, see tailnet.DERPServerMetrics.
 	expDERPOnce.Do(func() {
 		// We need to do this via a global Once because expvar registry is global and panics if we
 		// register multiple times.  In production there is only one Coderd and one DERP server per
@@ -848,6 +1046,8 @@ func New(options *Options) *API {
 				next.ServeHTTP(w, r)
 			})
 		},
+		// Only path-based apps are served from the path app access URL.
+		api.pathAppAccessURLMW,
 		// SubdomainAppMW checks if the first subdomain is a valid app URL. If
 		// it is, it will serve that application.
 		//
@@ -872,7 +1072,15 @@ func New(options *Options) *API {
 	// we do not override subdomain app routes.
 	r.Get("/latency-check", tracing.StatusWriterMiddleware(prometheusMW(LatencyCheck())).ServeHTTP)
 
-	r.Get("/healthz", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte("OK")) })
+	r.Get("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		// Load balancers stop sending requests to draining replicas.
+		if api.draining.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("Draining"))
+			return
+		}
+		_, _ = w.Write([]byte("OK"))
+	})
 
 	// Attach workspace apps routes.
 	r.Group(func(r chi.Router) {
@@ -881,8 +1089,12 @@ func New(options *Options) *API {
 	})
 
 	if options.DERPServer != nil {
-		derpHandler := derphttp.Handler(api.DERPServer)
-		derpHandler, api.derpCloseFunc = tailnet.WithWebsocketSupport(api.DERPServer, derpHandler)
+		api.derpServerMetrics = tailnet.NewDERPServerMetrics(api.DERPServer, quartz.NewReal())
+		if options.DeploymentValues.Prometheus.Enable {
+			options.PrometheusRegistry.MustRegister(api.derpServerMetrics)
+		}
+		var derpHandler http.Handler
+		derpHandler, api.derpCloseFunc = api.derpServerMetrics.Handler()
 
 		r.Route("/derp", func(r chi.Router) {
 			r.Get("/", derpHandler.ServeHTTP)
@@ -923,7 +1135,7 @@ func New(options *Options) *API {
 	// logging into Coder with an external OAuth2 provider.
 	r.Route("/oauth2", func(r chi.Router) {
 		r.Use(
-			httpmw.RequireExperimentWithDevBypass(api.Experiments, codersdk.ExperimentOAuth2),
+			httpmw.RequireExperimentWithDevBypass(api.ExperimentFlags, codersdk.ExperimentOAuth2),
 		)
 		r.Route("/authorize", func(r chi.Router) {
 			r.Use(
@@ -952,6 +1164,28 @@ func New(options *Options) *API {
 			r.Post("/", api.postOAuth2ProviderAppToken())
 		})
 
+		// RFC 8628 Device Authorization Grant. Devices are unauthenticated,
+		// while users approve them in the browser.
+		r.Route("/device", func(r chi.Router) {
+			r.With(
+				httpmw.AsAuthzSystem(httpmw.ExtractOAuth2ProviderAppWithOAuth2Errors(options.Database)),
+			).Post("/", api.postOAuth2DeviceAuthorization())
+			r.Route("/verify", func(r chi.Router) {
+				r.Use(apiKeyMiddlewareRedirect)
+				r.Get("/", api.getOAuth2DeviceVerification())
+				r.Post("/", api.postOAuth2DeviceVerification())
+			})
+		})
+		// RFC 7662 Token Introspection and RFC 7009 Token Revocation. Clients
+		// authenticate with their credentials instead of an API key.
+		r.Group(func(r chi.Router) {
+			r.Use(
+				httpmw.AsAuthzSystem(httpmw.ExtractOAuth2ProviderAppWithOAuth2Errors(options.Database)),
+			)
+			r.Post("/introspect", api.postOAuth2TokenIntrospection())
+			r.Post("/revoke", api.postOAuth2TokenRevocation())
+		})
+
 		// RFC 7591 Dynamic Client Registration - Public endpoint
 		r.Post("/register", api.postOAuth2ClientRegistration())
 
@@ -971,11 +1205,16 @@ func New(options *Options) *API {
 	r.Route("/api/experimental", func(r chi.Router) {
 		r.Use(apiKeyMiddleware)
 		r.Route("/aitasks", func(r chi.Router) {
+			r.Post("/", api.postAITask)
 			r.Get("/prompts", api.aiTasksPrompts)
+			r.Route("/queue", func(r chi.Router) {
+				r.Get("/", api.aiTaskQueue)
+				r.Delete("/{entry}", api.deleteAITaskQueueEntry)
+			})
 		})
 		r.Route("/mcp", func(r chi.Router) {
 			r.Use(
-				httpmw.RequireExperimentWithDevBypass(api.Experiments, codersdk.ExperimentOAuth2, codersdk.ExperimentMCPServerHTTP),
+				httpmw.RequireExperimentWithDevBypass(api.ExperimentFlags, codersdk.ExperimentOAuth2, codersdk.ExperimentMCPServerHTTP),
 			)
 			// MCP HTTP transport endpoint with mandatory authentication
 			r.Mount("/http", api.mcpHTTPHandler())
@@ -997,10 +1236,13 @@ func New(options *Options) *API {
 		r.Post("/csp/reports", api.logReportCSPViolations)
 
 		r.Get("/buildinfo", buildInfoHandler(buildInfo))
+		// The health monitor checks websockets without a session token.
+		r.Get("/healthz/ws", (&healthcheck.WebsocketEchoServer{Limited: true}).ServeHTTP)
 		// /regions is overridden in the enterprise version
 		r.Group(func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Get("/regions", api.regions)
+			r.Get("/regions/probe-plan", api.regionProbePlan)
 		})
 		r.Route("/derp-map", func(r chi.Router) {
 			// r.Use(apiKeyMiddleware)
@@ -1009,14 +1251,37 @@ func New(options *Options) *API {
 		r.Route("/deployment", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Get("/config", api.deploymentValues)
+			r.Route("/config/reload", func(r chi.Router) {
+				r.Get("/", api.deploymentConfigReload)
+				r.Post("/", api.postDeploymentConfigReload)
+			})
+			r.Get("/settings/drift", api.deploymentSettingsDrift)
+			r.Get("/version-skew", api.deploymentVersionSkew)
 			r.Get("/stats", api.deploymentStats)
+			r.Get("/naming-violations", api.deploymentNamingViolations)
+			r.Get("/telemetry", api.deploymentTelemetry)
 			r.Get("/ssh", api.sshConfig)
 		})
+		r.Route("/variable-sets", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.variableSets)
+			r.Post("/", api.postVariableSet)
+			r.Get("/{variableset}", api.variableSet)
+			r.Patch("/{variableset}", api.patchVariableSet)
+			r.Delete("/{variableset}", api.deleteVariableSet)
+		})
 		r.Route("/experiments", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Get("/available", handleExperimentsAvailable)
+			r.Get("/evaluation", api.handleExperimentsEvaluation)
+			r.Get("/flags", api.experimentFlags)
+			r.Put("/flags", api.putExperimentFlags)
 			r.Get("/", api.handleExperimentsGet)
 		})
+		r.Route("/rpc", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.publicAPIRPC)
+		})
 		r.Get("/updatecheck", api.updateCheck)
 		r.Route("/audit", func(r chi.Router) {
 			r.Use(
@@ -1045,6 +1310,11 @@ func New(options *Options) *API {
 			r.Get("/", api.auditLogs)
 			r.Post("/testgenerate", api.generateFakeAuditLog)
 		})
+		r.Route("/activity", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.activityFeed)
+			r.Get("/watch", api.watchActivityFeed)
+		})
 		r.Route("/files", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -1092,6 +1362,14 @@ func New(options *Options) *API {
 						})
 					})
 				})
+				r.Route("/templatebundles", func(r chi.Router) {
+					r.Get("/", api.templateBundlesByOrganization)
+					r.Post("/", api.postTemplateBundle)
+					r.Route("/{templatebundlename}/{templatebundleversion}", func(r chi.Router) {
+						r.Get("/", api.templateBundle)
+						r.Delete("/", api.deleteTemplateBundle)
+					})
+				})
 				r.Get("/paginated-members", api.paginatedMembers)
 				r.Route("/members", func(r chi.Router) {
 					r.Get("/", api.listMembers)
@@ -1121,15 +1399,35 @@ func New(options *Options) *API {
 						})
 					})
 				})
+				r.Route("/mfa", func(r chi.Router) {
+					r.Get("/", api.organizationMFASettings)
+					r.Patch("/", api.patchOrganizationMFASettings)
+				})
 				r.Route("/provisionerdaemons", func(r chi.Router) {
 					r.Get("/", api.provisionerDaemons)
 				})
 				r.Route("/provisionerjobs", func(r chi.Router) {
 					r.Get("/{job}", api.provisionerJob)
+					r.Get("/logs", api.searchProvisionerJobLogs)
 					r.Get("/", api.provisionerJobs)
 				})
+				r.Route("/prebuilds", func(r chi.Router) {
+					r.Post("/claim", api.postPrebuiltWorkspaceClaim)
+					r.With(
+						httpmw.ExtractWorkspaceParam(options.Database),
+					).Post("/claim/{workspace}/release", api.postPrebuiltWorkspaceRelease)
+				})
+				r.Route("/review-workspaces", func(r chi.Router) {
+					r.Get("/", api.reviewWorkspace)
+					r.Put("/", api.putReviewWorkspace)
+					r.Delete("/", api.deleteReviewWorkspace)
+				})
 			})
 		})
+		r.Route("/members", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.crossOrganizationMembers)
+		})
 		r.Route("/templates", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -1150,6 +1448,51 @@ func New(options *Options) *API {
 					r.Patch("/", api.patchActiveTemplateVersion)
 					r.Get("/{templateversionname}", api.templateVersionByName)
 				})
+				r.Route("/activation-reviewers", func(r chi.Router) {
+					r.Get("/", api.templateActivationReviewers)
+					r.Put("/", api.putTemplateActivationReviewers)
+				})
+				r.Route("/rollout", func(r chi.Router) {
+					r.Get("/", api.templateVersionRollout)
+					r.Post("/", api.postTemplateVersionRollout)
+					r.Delete("/", api.deleteTemplateVersionRollout)
+				})
+				r.Route("/parameter-option-sources", func(r chi.Router) {
+					r.Get("/", api.templateParameterOptionSources)
+					r.Route("/{parameter}", func(r chi.Router) {
+						r.Put("/", api.putTemplateParameterOptionSource)
+						r.Delete("/", api.deleteTemplateParameterOptionSource)
+						r.Get("/options", api.templateParameterOptions)
+					})
+				})
+				r.Route("/app-restrictions", func(r chi.Router) {
+					r.Get("/", api.templateAppRestrictions)
+					r.Route("/{app}", func(r chi.Router) {
+						r.Put("/", api.putTemplateAppRestriction)
+						r.Delete("/", api.deleteTemplateAppRestriction)
+					})
+				})
+				r.Route("/app-session-policies", func(r chi.Router) {
+					r.Get("/", api.templateAppSessionPolicies)
+					r.Put("/", api.putTemplateAppSessionPolicy)
+					r.Delete("/", api.deleteTemplateAppSessionPolicy)
+				})
+				r.Route("/egress-policy", func(r chi.Router) {
+					r.Get("/", api.templateEgressPolicy)
+					r.Put("/", api.putTemplateEgressPolicy)
+					r.Delete("/", api.deleteTemplateEgressPolicy)
+				})
+				r.Route("/secrets", func(r chi.Router) {
+					r.Get("/", api.templateSecrets)
+					r.Post("/", api.postTemplateSecret)
+					r.Patch("/{secretname}", api.patchTemplateSecret)
+					r.Delete("/{secretname}", api.deleteTemplateSecret)
+				})
+				r.Route("/variable-sets", func(r chi.Router) {
+					r.Get("/", api.templateVariableSets)
+					r.Put("/{variableset}", api.putTemplateVariableSet)
+					r.Delete("/{variableset}", api.deleteTemplateVariableSet)
+				})
 			})
 		})
 
@@ -1173,6 +1516,11 @@ func New(options *Options) *API {
 			r.Get("/presets", api.templateVersionPresets)
 			r.Get("/resources", api.templateVersionResources)
 			r.Get("/logs", api.templateVersionLogs)
+			r.Get("/diff", api.templateVersionDiff)
+			r.Route("/activation-request", func(r chi.Router) {
+				r.Get("/", api.templateVersionActivationRequest)
+				r.Post("/reviews", api.postTemplateVersionActivationReview)
+			})
 			r.Route("/dry-run", func(r chi.Router) {
 				r.Post("/", api.postTemplateVersionDryRun)
 				r.Get("/{jobID}", api.templateVersionDryRun)
@@ -1202,6 +1550,8 @@ func New(options *Options) *API {
 				// This value is intentionally increased during tests.
 				r.Use(httpmw.RateLimit(options.LoginRateLimit, time.Minute))
 				r.Post("/login", api.postLogin)
+				r.Post("/login/webauthn", api.postLoginWebAuthn)
+				r.Post("/login/webauthn/enroll", api.postLoginWebAuthnEnroll)
 				r.Post("/otp/request", api.postRequestOneTimePasscode)
 				r.Post("/validate-password", api.validateUserPassword)
 				r.Post("/otp/change-password", api.postChangePasswordWithOneTimePasscode)
@@ -1220,6 +1570,13 @@ func New(options *Options) *API {
 					)
 					r.Get("/", api.userOIDC)
 				})
+				r.Route("/saml", func(r chi.Router) {
+					r.Get("/metadata", api.userSAMLMetadata)
+					r.Get("/login", api.userSAMLLogin)
+					r.Post("/acs", api.userSAMLACS)
+					r.Get("/slo", api.userSAMLSLO)
+					r.Post("/slo", api.userSAMLSLO)
+				})
 			})
 			r.Group(func(r chi.Router) {
 				r.Use(
@@ -1228,6 +1585,7 @@ func New(options *Options) *API {
 				r.Post("/", api.postUser)
 				r.Get("/", api.users)
 				r.Post("/logout", api.postLogout)
+				r.Get("/saml/logout", api.userSAMLLogout)
 				// These routes query information about site wide roles.
 				r.Route("/roles", func(r chi.Router) {
 					r.Get("/", api.AssignableSiteRoles)
@@ -1239,6 +1597,7 @@ func New(options *Options) *API {
 						// organization member. This endpoint should match the authz story of
 						// postWorkspacesByOrganization
 						r.Post("/workspaces", api.postUserWorkspaces)
+						r.Post("/workspace-adoptions", api.postUserWorkspaceAdoption)
 						r.Route("/workspace/{workspacename}", func(r chi.Router) {
 							r.Get("/", api.workspaceByOwnerAndName)
 							r.Get("/builds/{buildnumber}", api.workspaceBuildByBuildNumber)
@@ -1260,6 +1619,13 @@ func New(options *Options) *API {
 						})
 						r.Get("/appearance", api.userAppearanceSettings)
 						r.Put("/appearance", api.putUserAppearanceSettings)
+						r.Get("/connection-settings", api.userConnectionSettings)
+						r.Put("/connection-settings", api.putUserConnectionSettings)
+						r.Route("/proxy-preference", func(r chi.Router) {
+							r.Get("/", api.userProxyPreference)
+							r.Put("/", api.putUserProxyPreference)
+							r.Post("/latencies", api.postUserProxyLatencies)
+						})
 						r.Route("/password", func(r chi.Router) {
 							r.Use(httpmw.RateLimit(options.LoginRateLimit, time.Minute))
 							r.Put("/", api.putUserPassword)
@@ -1283,6 +1649,10 @@ func New(options *Options) *API {
 								r.Delete("/", api.deleteAPIKey)
 							})
 						})
+						r.Route("/sessions", func(r chi.Router) {
+							r.Get("/", api.apiSessions)
+							r.Delete("/{keyid}", api.deleteAPISession)
+						})
 
 						r.Route("/organizations", func(r chi.Router) {
 							r.Get("/", api.organizationsByUser)
@@ -1291,11 +1661,31 @@ func New(options *Options) *API {
 
 						r.Get("/gitsshkey", api.gitSSHKey)
 						r.Put("/gitsshkey", api.regenerateGitSSHKey)
+						r.Get("/workspace-archives", api.workspaceArchivesByUser)
+						r.Route("/secrets", func(r chi.Router) {
+							r.Get("/", api.userSecrets)
+							r.Post("/", api.postUserSecret)
+							r.Patch("/{secretname}", api.patchUserSecret)
+							r.Delete("/{secretname}", api.deleteUserSecret)
+						})
 						r.Route("/notifications", func(r chi.Router) {
 							r.Route("/preferences", func(r chi.Router) {
 								r.Get("/", api.userNotificationPreferences)
 								r.Put("/", api.putUserNotificationPreferences)
+								r.Get("/categories", api.userNotificationCategoryPreferences)
+								r.Put("/categories", api.putUserNotificationCategoryPreferences)
 							})
+							r.Route("/settings", func(r chi.Router) {
+								r.Get("/", api.userNotificationSettings)
+								r.Put("/", api.putUserNotificationSettings)
+							})
+						})
+						r.Route("/webauthn", func(r chi.Router) {
+							r.Post("/challenge", api.postWebAuthnChallenge)
+							r.Get("/credentials", api.webAuthnCredentials)
+							r.Post("/credentials", api.postWebAuthnCredential)
+							r.Delete("/credentials/{webauthncredential}", api.deleteWebAuthnCredential)
+							r.Post("/recovery-codes", api.postMFARecoveryCodes)
 						})
 						r.Route("/webpush", func(r chi.Router) {
 							r.Post("/subscription", api.postUserWebpushSubscription)
@@ -1310,6 +1700,7 @@ func New(options *Options) *API {
 			r.Post("/azure-instance-identity", api.postWorkspaceAuthAzureInstanceIdentity)
 			r.Post("/aws-instance-identity", api.postWorkspaceAuthAWSInstanceIdentity)
 			r.Post("/google-instance-identity", api.postWorkspaceAuthGoogleInstanceIdentity)
+			r.Post("/adopt", api.postWorkspaceAgentAdopt)
 			r.With(
 				apiKeyMiddlewareOptional,
 				httpmw.ExtractWorkspaceProxy(httpmw.ExtractWorkspaceProxyConfig{
@@ -1320,15 +1711,29 @@ func New(options *Options) *API {
 			).Get("/connection", api.workspaceAgentConnectionGeneric)
 			r.Route("/me", func(r chi.Router) {
 				r.Use(workspaceAgentInfo)
-				r.Get("/rpc", api.workspaceAgentRPC)
-				r.Patch("/logs", api.patchWorkspaceAgentLogs)
-				r.Patch("/app-status", api.patchWorkspaceAgentAppStatus)
-				// Deprecated: Required to support legacy agents
-				r.Get("/gitauth", api.workspaceAgentsGitAuth)
-				r.Get("/external-auth", api.workspaceAgentsExternalAuth)
-				r.Get("/gitsshkey", api.agentGitSSHKey)
-				r.Post("/log-source", api.workspaceAgentPostLogSource)
-				r.Get("/reinit", api.workspaceAgentReinit)
+				// Agents without a certificate must be able to request
+				// one when mTLS is enforced.
+				r.Post("/certificate", api.workspaceAgentIssueCertificate)
+				r.Group(func(r chi.Router) {
+					r.Use(api.RequireClientCertificateMW(internalca.KindAgent, func(r *http.Request) uuid.UUID {
+						return httpmw.WorkspaceAgent(r).ID
+					}))
+					r.Get("/rpc", api.workspaceAgentRPC)
+					r.Patch("/logs", api.patchWorkspaceAgentLogs)
+					r.Patch("/app-status", api.patchWorkspaceAgentAppStatus)
+					// Deprecated: Required to support legacy agents
+					r.Get("/gitauth", api.workspaceAgentsGitAuth)
+					r.Get("/external-auth", api.workspaceAgentsExternalAuth)
+					r.Get("/gitsshkey", api.agentGitSSHKey)
+					r.Get("/secrets", api.workspaceAgentSecrets)
+					r.Get("/vault-token", api.workspaceAgentVaultToken)
+					r.Get("/egress-policy", api.workspaceAgentEgressPolicy)
+					r.Post("/egress-violations", api.workspaceAgentReportEgressViolations)
+					r.Get("/token-rotation", api.workspaceAgentTokenRotation)
+					r.Post("/token-rotation", api.workspaceAgentRotateToken)
+					r.Post("/log-source", api.workspaceAgentPostLogSource)
+					r.Get("/reinit", api.workspaceAgentReinit)
+				})
 			})
 			r.Route("/{workspaceagent}", func(r chi.Router) {
 				r.Use(
@@ -1349,12 +1754,15 @@ func New(options *Options) *API {
 				r.Get("/startup-logs", api.workspaceAgentLogsDeprecated)
 				r.Get("/logs", api.workspaceAgentLogs)
 				r.Get("/listening-ports", api.workspaceAgentListeningPorts)
+				r.Get("/disk-usage", api.workspaceAgentDiskUsage)
+				r.Get("/managed-processes", api.workspaceAgentManagedProcesses)
+				r.Get("/desktop", api.workspaceAgentDesktop)
 				r.Get("/connection", api.workspaceAgentConnection)
 				r.Get("/containers", api.workspaceAgentListContainers)
 				r.Post("/containers/devcontainers/{devcontainer}/recreate", api.workspaceAgentRecreateDevcontainer)
 				r.Get("/coordinate", api.workspaceAgentClientCoordinate)
 
-				// PTY is part of workspaceAppServer.
+				// PTY and the desktop VNC stream are part of workspaceAppServer.
 			})
 		})
 		r.Route("/workspaces", func(r chi.Router) {
@@ -1372,14 +1780,25 @@ func New(options *Options) *API {
 					r.Get("/", api.workspaceBuilds)
 					r.Post("/", api.postWorkspaceBuilds)
 				})
+				r.Post("/rollback", api.postWorkspaceRollback)
 				r.Route("/autostart", func(r chi.Router) {
 					r.Put("/", api.putWorkspaceAutostart)
 				})
 				r.Route("/ttl", func(r chi.Router) {
 					r.Put("/", api.putWorkspaceTTL)
 				})
+				r.Put("/labels", api.putWorkspaceLabels)
+				r.Post("/restore-archive", api.postWorkspaceRestoreArchive)
+				r.Post("/clone", api.postWorkspaceClone)
+				r.Post("/copy-data", api.postWorkspaceCopyData)
+				r.Route("/schedule-pause", func(r chi.Router) {
+					r.Get("/", api.workspaceSchedulePause)
+					r.Put("/", api.putWorkspaceSchedulePause)
+					r.Delete("/", api.deleteWorkspaceSchedulePause)
+				})
 				r.Get("/watch", api.watchWorkspaceSSE)
 				r.Get("/watch-ws", api.watchWorkspaceWS)
+				r.Get("/readiness", api.workspaceReadiness)
 				r.Put("/extend", api.putExtendWorkspace)
 				r.Post("/usage", api.postWorkspaceUsage)
 				r.Put("/dormant", api.putWorkspaceDormant)
@@ -1392,7 +1811,13 @@ func New(options *Options) *API {
 					r.Post("/", api.postWorkspaceAgentPortShare)
 					r.Delete("/", api.deleteWorkspaceAgentPortShare)
 				})
+				r.Route("/port-share-links", func(r chi.Router) {
+					r.Get("/", api.workspacePortShareLinks)
+					r.Post("/", api.postWorkspacePortShareLink)
+					r.Delete("/{link}", api.deleteWorkspacePortShareLink)
+				})
 				r.Get("/timings", api.workspaceTimings)
+				r.Post("/credentials", api.postWorkspaceCredential)
 			})
 		})
 		r.Route("/workspacebuilds/{workspacebuild}", func(r chi.Router) {
@@ -1405,10 +1830,17 @@ func New(options *Options) *API {
 			r.Patch("/cancel", api.patchCancelWorkspaceBuild)
 			r.Get("/logs", api.workspaceBuildLogs)
 			r.Get("/parameters", api.workspaceBuildParameters)
+			r.Get("/queue", api.workspaceBuildQueue)
 			r.Get("/resources", api.workspaceBuildResourcesDeprecated)
 			r.Get("/state", api.workspaceBuildState)
 			r.Get("/timings", api.workspaceBuildTimings)
 		})
+		r.Route("/review-workspaces/webhooks", func(r chi.Router) {
+			// Webhooks are authenticated with the review workspaces webhook
+			// secret instead of an API key.
+			r.Post("/github", api.postReviewWorkspacesGitHubWebhook)
+			r.Post("/gitlab", api.postReviewWorkspacesGitLabWebhook)
+		})
 		r.Route("/authcheck", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Post("/", api.checkAuthorization)
@@ -1435,6 +1867,9 @@ func New(options *Options) *API {
 			r.Get("/user-status-counts", api.insightsUserStatusCounts)
 			r.Get("/user-latency", api.insightsUserLatency)
 			r.Get("/templates", api.insightsTemplates)
+			r.Get("/template-builds", api.insightsTemplateBuilds)
+			r.Get("/workspace-usage", api.insightsWorkspaceUsage)
+			r.Get("/connection-quality", api.insightsConnectionQuality)
 		})
 		r.Route("/debug", func(r chi.Router) {
 			r.Use(
@@ -1454,8 +1889,10 @@ func New(options *Options) *API {
 
 			r.Get("/coordinator", api.debugCoordinator)
 			r.Get("/tailnet", api.debugTailnet)
+			r.Get("/diagnostics", api.debugDiagnostics)
 			r.Route("/health", func(r chi.Router) {
 				r.Get("/", api.debugDeploymentHealth)
+				r.Get("/scores", api.deploymentHealthScores)
 				r.Route("/settings", func(r chi.Router) {
 					r.Get("/", api.deploymentHealthSettings)
 					r.Put("/", api.putDeploymentHealthSettings)
@@ -1469,6 +1906,7 @@ func New(options *Options) *API {
 			if options.DERPServer != nil {
 				r.Route("/derp", func(r chi.Router) {
 					r.Get("/traffic", options.DERPServer.ServeDebugTraffic)
+					r.Get("/sessions", api.debugDERPSessions)
 				})
 			}
 			r.Method("GET", "/expvar", expvar.Handler()) // contains DERP metrics as well as cmdline and memstats
@@ -1477,7 +1915,7 @@ func New(options *Options) *API {
 		r.Route("/oauth2-provider", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
-				httpmw.RequireExperimentWithDevBypass(api.Experiments, codersdk.ExperimentOAuth2),
+				httpmw.RequireExperimentWithDevBypass(api.ExperimentFlags, codersdk.ExperimentOAuth2),
 			)
 			r.Route("/apps", func(r chi.Router) {
 				r.Get("/", api.oAuth2ProviderApps())
@@ -1506,6 +1944,8 @@ func New(options *Options) *API {
 			r.Route("/inbox", func(r chi.Router) {
 				r.Get("/", api.listInboxNotifications)
 				r.Put("/mark-all-as-read", api.markAllInboxNotificationsAsRead)
+				r.Put("/read-status", api.updateInboxNotificationsReadStatus)
+				r.Get("/unread-counts", api.inboxNotificationUnreadCounts)
 				r.Get("/watch", api.watchInboxNotifications)
 				r.Put("/{id}/read-status", api.updateInboxNotificationReadStatus)
 			})
@@ -1580,6 +2020,17 @@ func New(options *Options) *API {
 					AppHost: appurl.ConvertAppHostForCSP(api.AccessURL.Host, api.AppHostname),
 				},
 			}
+			if api.PathAppAccessURL != nil {
+				// Path apps may be embedded in the dashboard.
+				appHost := api.PathAppAccessURL.Host
+				if api.pathAppSubdomains() {
+					appHost = "*." + appHost
+				}
+				proxies = append(proxies, &proxyhealth.ProxyHost{
+					Host:    api.PathAppAccessURL.Host,
+					AppHost: appHost,
+				})
+			}
 			if f := api.WorkspaceProxyHostsFn.Load(); f != nil {
 				proxies = append(proxies, (*f)()...)
 			}
@@ -1613,9 +2064,13 @@ type API struct {
 	ID                                uuid.UUID
 	Auditor                           atomic.Pointer[audit.Auditor]
 	WorkspaceClientCoordinateOverride atomic.Pointer[func(rw http.ResponseWriter) bool]
-	TailnetCoordinator                atomic.Pointer[tailnet.Coordinator]
-	NetworkTelemetryBatcher           *tailnet.NetworkTelemetryBatcher
-	TailnetClientService              *tailnet.ClientService
+	// TailnetCoordinatorRedirect is used by Enterprise code to send
+	// coordination requests for an agent to the replica that owns it. It
+	// returns true if it has written a response.
+	TailnetCoordinatorRedirect atomic.Pointer[func(rw http.ResponseWriter, r *http.Request, agentID uuid.UUID) bool]
+	TailnetCoordinator         atomic.Pointer[tailnet.Coordinator]
+	NetworkTelemetryBatcher    *tailnet.NetworkTelemetryBatcher
+	TailnetClientService       *tailnet.ClientService
 	// WebpushDispatcher is a way to send notifications to users via Web Push.
 	WebpushDispatcher webpush.Dispatcher
 	QuotaCommitter    atomic.Pointer[proto.QuotaCommitter]
@@ -1623,6 +2078,10 @@ type API struct {
 	// WorkspaceProxyHostsFn returns the hosts of healthy workspace proxies
 	// for header reasons.
 	WorkspaceProxyHostsFn atomic.Pointer[func() []*proxyhealth.ProxyHost]
+	// RegionsFn returns the regions that serve workspace apps and terminals.
+	// Enterprise sets it to include workspace proxies, otherwise only the
+	// primary region is used.
+	RegionsFn atomic.Pointer[func(ctx context.Context) ([]codersdk.Region, error)]
 	// TemplateScheduleStore is a pointer to an atomic pointer because this is
 	// passed to another struct, and we want them all to be the same reference.
 	TemplateScheduleStore *atomic.Pointer[schedule.TemplateScheduleStore]
@@ -1654,19 +2113,41 @@ type API struct {
 	WebsocketWaitMutex sync.Mutex
 	WebsocketWaitGroup sync.WaitGroup
 	derpCloseFunc      func()
+	derpServerMetrics  *tailnet.DERPServerMetrics
+	connectionQuality  *connectionquality.Tracker
 
 	metricsCache          *metricscache.Cache
 	updateChecker         *updatecheck.Checker
 	WorkspaceAppsProvider workspaceapps.SignedTokenProvider
 	workspaceAppServer    *workspaceapps.Server
 	agentProvider         workspaceapps.AgentProvider
-
-	// Experiments contains the list of experiments currently enabled.
+	// WorkspaceArchiver archives dormant workspaces before they are
+	// auto-deleted. It is nil when archiving is not configured.
+	WorkspaceArchiver *workspacearchive.Archiver
+	// fileObjectCollector deletes unreferenced files from the FileStore. It
+	// is nil when files are stored in the database.
+	fileObjectCollector io.Closer
+	// ConfigReloader applies the hot-reloadable deployment options when the
+	// deployment configuration is reloaded on any replica.
+	ConfigReloader *configreload.Reloader
+	// ParameterOptionsFetcher fetches and caches the options of template
+	// parameters that are sourced from APIs.
+	ParameterOptionsFetcher *parameteroptions.Fetcher
+
+	// Experiments contains the list of experiments enabled at startup.
 	// This is used to gate features that are not yet ready for production.
+	// Use ExperimentFlags to check experiments that can be changed at
+	// runtime.
 	Experiments codersdk.Experiments
+	// ExperimentFlags evaluates experiments against the startup experiments
+	// and the flags set at runtime, for the deployment or for an actor.
+	ExperimentFlags *experimentflags.Evaluator
 
 	healthCheckGroup *singleflight.Group[string, *healthsdk.HealthcheckReport]
 	healthCheckCache atomic.Pointer[healthsdk.HealthcheckReport]
+	// healthMonitor runs healthchecks periodically and records their
+	// scores.
+	healthMonitor *healthcheck.Monitor
 
 	statsReporter *workspacestats.Reporter
 
@@ -1674,6 +2155,20 @@ type API struct {
 	// dbRolluper rolls up template usage stats from raw agent and app
 	// stats. This is used to provide insights in the WebUI.
 	dbRolluper *dbrollup.Rolluper
+
+	// cliVersionsSeen throttles how often the CLI version of each user is
+	// written to the database.
+	cliVersionsMu   sync.Mutex
+	cliVersionsSeen map[uuid.UUID]seenCLIVersion
+
+	// aiTaskQueueNotify wakes up the processor of queued AI tasks.
+	aiTaskQueueNotify chan struct{}
+	aiTaskQueueDone   chan struct{}
+
+	// draining is set while the replica drains before it shuts down, e.g.
+	// for a restart.
+	draining          atomic.Bool
+	shutdownRequested chan struct{}
 }
 
 // Close waits for all WebSocket connections to drain before returning.
@@ -1703,12 +2198,22 @@ func (api *API) Close() error {
 		api.Logger.Warn(api.ctx, "websocket shutdown timed out after 10 seconds")
 	}
 
+	<-api.aiTaskQueueDone
 	api.dbRolluper.Close()
 	api.metricsCache.Close()
+	_ = api.healthMonitor.Close()
 	if api.updateChecker != nil {
 		api.updateChecker.Close()
 	}
 	_ = api.workspaceAppServer.Close()
+	if api.WorkspaceArchiver != nil {
+		_ = api.WorkspaceArchiver.Close()
+	}
+	if api.fileObjectCollector != nil {
+		_ = api.fileObjectCollector.Close()
+	}
+	_ = api.ConfigReloader.Close()
+	_ = api.ExperimentFlags.Close()
 	_ = api.agentProvider.Close()
 	if api.derpCloseFunc != nil {
 		api.derpCloseFunc()
@@ -1857,6 +2362,7 @@ func (api *API) CreateInMemoryTaggedProvisionerDaemon(dialCtx context.Context, n
 		provisionerdserver.Options{
 			OIDCConfig:          api.OIDCConfig,
 			ExternalAuthConfigs: api.ExternalAuthConfigs,
+			TemplatePolicy:      api.TemplatePolicy,
 			Clock:               api.Clock,
 		},
 		api.NotificationsEnqueuer,
@@ -1906,9 +2412,18 @@ func (api *API) CreateInMemoryTaggedProvisionerDaemon(dialCtx context.Context, n
 func (api *API) DERPMap() *tailcfg.DERPMap {
 	fn := api.DERPMapper.Load()
 	if fn != nil {
-		return (*fn)(api.Options.BaseDERPMap)
+		return (*fn)(api.BaseDERPMap())
 	}
 
+	return api.BaseDERPMap()
+}
+
+// BaseDERPMap returns the current base DERP map before any proxy regions are
+// added to it.
+func (api *API) BaseDERPMap() *tailcfg.DERPMap {
+	if api.Options.BaseDERPMapFn != nil {
+		return api.Options.BaseDERPMapFn()
+	}
 	return api.Options.BaseDERPMap
 }
 
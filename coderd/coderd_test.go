@@ -11,6 +11,7 @@ import (
 	"strings"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/google/uuid"
@@ -33,6 +34,7 @@ import (
 	"github.com/coder/coder/v2/tailnet"
 	tailnetproto "github.com/coder/coder/v2/tailnet/proto"
 	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
 )
 
 // updateGoldenFiles is a flag that can be set to update golden files.
@@ -260,6 +262,33 @@ func TestHealthz(t *testing.T) {
 	assert.Equal(t, "OK", string(body))
 }
 
+func TestHealthzDraining(t *testing.T) {
+	t.Parallel()
+	clock := quartz.NewMock(t)
+	trap := clock.Trap().NewTimer("coderd", "drain")
+	defer trap.Close()
+	client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{
+		Clock: clock,
+	})
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	api.Drain(time.Minute)
+	trap.MustWait(ctx).MustRelease(ctx)
+
+	res, err := client.Request(ctx, http.MethodGet, "/healthz", nil)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	select {
+	case <-api.ShutdownRequested():
+		t.Fatal("shutdown requested before the drain timeout")
+	default:
+	}
+
+	clock.Advance(time.Minute).MustWait(ctx)
+	testutil.TryReceive(ctx, t, api.ShutdownRequested())
+}
+
 func TestSwagger(t *testing.T) {
 	t.Parallel()
 
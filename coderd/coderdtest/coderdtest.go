@@ -68,12 +68,15 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbrollup"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/diagnostics"
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/internalca"
 	"github.com/coder/coder/v2/coderd/jobreaper"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
+	"github.com/coder/coder/v2/coderd/objectstore"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/runtimeconfig"
@@ -81,6 +84,7 @@ import (
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/updatecheck"
 	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/vault"
 	"github.com/coder/coder/v2/coderd/webpush"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
@@ -106,6 +110,7 @@ type Options struct {
 	// server's URL. Setting this may result in unexpected behavior (especially
 	// with running agents).
 	AccessURL                      *url.URL
+	PathAppAccessURL               *url.URL
 	AppHostname                    string
 	AWSCertificates                awsidentity.Certificates
 	Authorizer                     rbac.Authorizer
@@ -113,10 +118,16 @@ type Options struct {
 	GithubOAuth2Config             *coderd.GithubOAuth2Config
 	RealIPConfig                   *httpmw.RealIPConfig
 	OIDCConfig                     *coderd.OIDCConfig
+	SAMLConfig                     *coderd.SAMLConfig
 	GoogleTokenValidator           *idtoken.Validator
 	SSHKeygenAlgorithm             gitsshkey.Algorithm
 	AutobuildTicker                <-chan time.Time
 	AutobuildStats                 chan<- autobuild.Stats
+	AutobuildArchiver              autobuild.Archiver
+	AutobuildRegistry              prometheus.Registerer // Receives the metrics of the lifecycle executor.
+	WorkspaceArchiveStore          objectstore.Store
+	VaultIssuer                    *vault.Issuer
+	InternalCA                     *internalca.CA
 	Auditor                        audit.Auditor
 	TLSCertificates                []tls.Certificate
 	ExternalAuthConfigs            []*externalauth.Config
@@ -129,6 +140,7 @@ type Options struct {
 	HealthcheckFunc    func(ctx context.Context, apiKey string) *healthsdk.HealthcheckReport
 	HealthcheckTimeout time.Duration
 	HealthcheckRefresh time.Duration
+	LogBuffer          *diagnostics.LogBuffer
 
 	// All rate limits default to -1 (unlimited) in tests if not set.
 	APIRateLimit   int
@@ -242,6 +254,9 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 		options.AutobuildTicker = ticker
 		t.Cleanup(func() { close(ticker) })
 	}
+	if options.AutobuildRegistry == nil {
+		options.AutobuildRegistry = prometheus.NewRegistry()
+	}
 	if options.AutobuildStats != nil {
 		t.Cleanup(func() {
 			close(options.AutobuildStats)
@@ -344,9 +359,20 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 		options.OneTimePasscodeValidityPeriod = testutil.WaitLong
 	}
 
+	autostartHolidaysClock := options.Clock
+	if autostartHolidaysClock == nil {
+		autostartHolidaysClock = quartz.NewReal()
+	}
+	autostartHolidays, err := schedule.NewAutostartHolidays(
+		options.Logger.Named("autostart_holidays"),
+		autostartHolidaysClock,
+		options.DeploymentValues.AutostartHolidays.Value(),
+		options.DeploymentValues.AutostartHolidayCalendarURL.Value(),
+	)
+	require.NoError(t, err, "parse autostart holidays")
 	var templateScheduleStore atomic.Pointer[schedule.TemplateScheduleStore]
 	if options.TemplateScheduleStore == nil {
-		options.TemplateScheduleStore = schedule.NewAGPLTemplateScheduleStore()
+		options.TemplateScheduleStore = schedule.NewHolidayTemplateScheduleStore(schedule.NewAGPLTemplateScheduleStore(), autostartHolidays)
 	}
 	templateScheduleStore.Store(&options.TemplateScheduleStore)
 
@@ -363,7 +389,7 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 		options.Database,
 		options.Pubsub,
 		files.New(prometheus.NewRegistry(), options.Authorizer),
-		prometheus.NewRegistry(),
+		options.AutobuildRegistry,
 		&templateScheduleStore,
 		&auditor,
 		accessControlStore,
@@ -372,6 +398,9 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 		options.NotificationsEnqueuer,
 		experiments,
 	).WithStatsChannel(options.AutobuildStats)
+	if options.AutobuildArchiver != nil {
+		lifecycleExecutor.WithArchiver(options.AutobuildArchiver)
+	}
 	lifecycleExecutor.Run()
 
 	jobReaperTicker := time.NewTicker(options.DeploymentValues.JobReaperDetectorInterval.Value())
@@ -533,6 +562,7 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			// agents are not marked as disconnected during slow tests.
 			AgentInactiveDisconnectTimeout: testutil.WaitShort,
 			AccessURL:                      accessURL,
+			PathAppAccessURL:               options.PathAppAccessURL,
 			AppHostname:                    options.AppHostname,
 			AppHostnameRegex:               appHostnameRegex,
 			Logger:                         *options.Logger,
@@ -548,6 +578,7 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			GithubOAuth2Config:                 options.GithubOAuth2Config,
 			RealIPConfig:                       options.RealIPConfig,
 			OIDCConfig:                         options.OIDCConfig,
+			SAMLConfig:                         options.SAMLConfig,
 			GoogleTokenValidator:               options.GoogleTokenValidator,
 			SSHKeygenAlgorithm:                 options.SSHKeygenAlgorithm,
 			DERPServer:                         derpServer,
@@ -576,6 +607,7 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			HealthcheckFunc:                    options.HealthcheckFunc,
 			HealthcheckTimeout:                 options.HealthcheckTimeout,
 			HealthcheckRefresh:                 options.HealthcheckRefresh,
+			LogBuffer:                          options.LogBuffer,
 			StatsBatcher:                       options.StatsBatcher,
 			WorkspaceAppsStatsCollectorOptions: options.WorkspaceAppsStatsCollectorOptions,
 			AllowWorkspaceRenames:              options.AllowWorkspaceRenames,
@@ -585,8 +617,12 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			NotificationsEnqueuer:              options.NotificationsEnqueuer,
 			OneTimePasscodeValidityPeriod:      options.OneTimePasscodeValidityPeriod,
 			Clock:                              options.Clock,
+			AutostartHolidays:                  autostartHolidays,
 			AppEncryptionKeyCache:              options.APIKeyEncryptionCache,
 			OIDCConvertKeyCache:                options.OIDCConvertKeyCache,
+			WorkspaceArchiveStore:              options.WorkspaceArchiveStore,
+			VaultIssuer:                        options.VaultIssuer,
+			InternalCA:                         options.InternalCA,
 		}
 }
 
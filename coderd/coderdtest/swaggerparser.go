@@ -307,6 +307,7 @@ func assertSecurityDefined(t *testing.T, comment SwaggerComment) {
 
 	if comment.router == "/updatecheck" ||
 		comment.router == "/buildinfo" ||
+		comment.router == "/healthz/ws" ||
 		comment.router == "/" ||
 		comment.router == "/users/login" ||
 		comment.router == "/users/otp/request" ||
@@ -0,0 +1,410 @@
+// Package configreload applies changes of hot-reloadable deployment options on
+// all replicas without restarting them.
+package configreload
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/pflag"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"cdr.dev/slog"
+	"github.com/coder/quartz"
+	"github.com/coder/serpent"
+
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// eventReload is the pubsub channel reloads and their results are published
+// on. Both go through the same channel so that replicas receive the results
+// of a reload after the reload itself.
+const eventReload = "deployment_config_reload"
+
+const (
+	messageTypeReload = "reload"
+	messageTypeResult = "result"
+)
+
+// ApplyFunc applies the new value of a hot-reloadable option. vals are the
+// deployment values with all reloaded options applied. It must not retain
+// vals, and should return quickly, e.g. by swapping the settings a subsystem
+// reads.
+type ApplyFunc func(ctx context.Context, vals *codersdk.DeploymentValues) error
+
+type Options struct {
+	Logger    slog.Logger
+	Pubsub    pubsub.Pubsub
+	Clock     quartz.Clock
+	ReplicaID uuid.UUID
+	// Values are the values the replica was started with. They are copied,
+	// not modified.
+	Values *codersdk.DeploymentValues
+}
+
+type message struct {
+	Type        string    `json:"type"`
+	ReloadID    uuid.UUID `json:"reload_id"`
+	ReplicaID   uuid.UUID `json:"replica_id"`
+	RequestedAt time.Time `json:"requested_at,omitempty"`
+	// Values are the new values of the changed hot-reloadable options,
+	// by option name.
+	Values          map[string]json.RawMessage `json:"values,omitempty"`
+	RestartRequired []string                   `json:"restart_required,omitempty"`
+	// Errors are the options the replica failed to apply. Options of the
+	// reload that are missing were applied.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// Reloader compares reloaded deployment values with the current ones, and
+// publishes the changed hot-reloadable options to all replicas, which apply
+// them with the registered ApplyFuncs.
+type Reloader struct {
+	opts Options
+	ctx  context.Context
+
+	mu       sync.Mutex
+	appliers map[string][]ApplyFunc
+	// current are the values with all reloads applied.
+	current *codersdk.DeploymentValues
+	latest  *codersdk.DeploymentConfigReload
+
+	cancel      context.CancelFunc
+	unsubscribe func()
+}
+
+// New starts applying the reloads published by any replica until it is
+// closed.
+func New(ctx context.Context, opts Options) (*Reloader, error) {
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	r := &Reloader{
+		opts:     opts,
+		ctx:      ctx,
+		appliers: make(map[string][]ApplyFunc),
+		current:  CopyValues(opts.Values),
+		cancel:   cancel,
+	}
+	unsubscribe, err := opts.Pubsub.Subscribe(eventReload, r.handleMessage)
+	if err != nil {
+		cancel()
+		return nil, xerrors.Errorf("subscribe to deployment config reloads: %w", err)
+	}
+	r.unsubscribe = unsubscribe
+	return r, nil
+}
+
+// Register calls apply when the option with the given name is reloaded. The
+// option must be hot-reloadable.
+func (r *Reloader) Register(name string, apply ApplyFunc) {
+	opt := r.current.Options().ByName(name)
+	if opt == nil {
+		panic("developer error: unknown deployment option " + name)
+	}
+	if !codersdk.IsHotReloadableDeploymentOption(*opt) {
+		panic("developer error: deployment option " + name + " is not hot-reloadable")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.appliers[name] = append(r.appliers[name], apply)
+}
+
+// Reload compares vals with the current values and publishes the changes to
+// all replicas. Changed options that are not hot-reloadable are reported as
+// requiring a restart. The replicas apply the changes asynchronously, Latest
+// returns how they did.
+func (r *Reloader) Reload(_ context.Context, vals *codersdk.DeploymentValues) (codersdk.DeploymentConfigReload, error) {
+	r.mu.Lock()
+	current := r.current.Options()
+	r.mu.Unlock()
+
+	msg := message{
+		Type:        messageTypeReload,
+		ReloadID:    uuid.New(),
+		ReplicaID:   r.opts.ReplicaID,
+		RequestedAt: dbtime.Time(r.opts.Clock.Now()),
+		Values:      make(map[string]json.RawMessage),
+	}
+	for _, opt := range vals.Options() {
+		old := current.ByName(opt.Name)
+		if old == nil || old.Value.String() == opt.Value.String() {
+			continue
+		}
+		if !codersdk.IsHotReloadableDeploymentOption(opt) {
+			msg.RestartRequired = append(msg.RestartRequired, opt.Name)
+			continue
+		}
+		raw, err := json.Marshal(opt.Value)
+		if err != nil {
+			return codersdk.DeploymentConfigReload{}, xerrors.Errorf("encode %q: %w", opt.Name, err)
+		}
+		msg.Values[opt.Name] = raw
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return codersdk.DeploymentConfigReload{}, xerrors.Errorf("encode reload: %w", err)
+	}
+	err = r.opts.Pubsub.Publish(eventReload, data)
+	if err != nil {
+		return codersdk.DeploymentConfigReload{}, xerrors.Errorf("publish reload: %w", err)
+	}
+	return newReload(msg), nil
+}
+
+// Latest returns the latest reload published since the replica started.
+func (r *Reloader) Latest() (codersdk.DeploymentConfigReload, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.latest == nil {
+		return codersdk.DeploymentConfigReload{}, false
+	}
+	latest := *r.latest
+	latest.Options = make([]codersdk.DeploymentConfigReloadOption, len(r.latest.Options))
+	for i, opt := range r.latest.Options {
+		opt.Replicas = slices.Clone(opt.Replicas)
+		latest.Options[i] = opt
+	}
+	return latest, true
+}
+
+// Close stops applying reloads.
+func (r *Reloader) Close() error {
+	r.cancel()
+	r.unsubscribe()
+	return nil
+}
+
+func (r *Reloader) handleMessage(_ context.Context, data []byte) {
+	var msg message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		r.opts.Logger.Warn(r.ctx, "decode deployment config reload", slog.Error(err))
+		return
+	}
+
+	switch msg.Type {
+	case messageTypeReload:
+		errs := r.apply(msg)
+		result := message{
+			Type:      messageTypeResult,
+			ReloadID:  msg.ReloadID,
+			ReplicaID: r.opts.ReplicaID,
+			Errors:    errs,
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			r.opts.Logger.Error(r.ctx, "encode deployment config reload result", slog.Error(err))
+			return
+		}
+		if err := r.opts.Pubsub.Publish(eventReload, data); err != nil {
+			r.opts.Logger.Warn(r.ctx, "publish deployment config reload result", slog.Error(err))
+		}
+	case messageTypeResult:
+		r.recordResult(msg)
+	}
+}
+
+// apply applies the values of a reload and returns the options it failed to
+// apply. Failed options keep their current values.
+func (r *Reloader) apply(msg message) map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reload := newReload(msg)
+	r.latest = &reload
+
+	next := CopyValues(r.current)
+	nextOpts := next.Options()
+	errs := make(map[string]string)
+	for name, raw := range msg.Values {
+		opt := nextOpts.ByName(name)
+		if opt == nil {
+			// The replica runs a version without the option.
+			errs[name] = "unknown option"
+			continue
+		}
+		if !codersdk.IsHotReloadableDeploymentOption(*opt) {
+			errs[name] = "option is not hot-reloadable, restart the replica to apply it"
+			continue
+		}
+		if err := setJSON(opt.Value, raw); err != nil {
+			errs[name] = err.Error()
+			continue
+		}
+	}
+
+	for name := range msg.Values {
+		if _, failed := errs[name]; failed {
+			continue
+		}
+		for _, apply := range r.appliers[name] {
+			if err := apply(r.ctx, next); err != nil {
+				errs[name] = err.Error()
+				break
+			}
+		}
+	}
+
+	// Keep the current values of the options that failed, so that they are
+	// reported as changed by the next reload.
+	currentOpts := r.current.Options()
+	for name := range errs {
+		opt := nextOpts.ByName(name)
+		if old := currentOpts.ByName(name); opt != nil && old != nil {
+			copyValue(opt.Value, old.Value)
+		}
+	}
+	r.current = next
+
+	if len(errs) > 0 {
+		r.opts.Logger.Warn(r.ctx, "failed to apply reloaded deployment options",
+			slog.F("reload_id", msg.ReloadID), slog.F("errors", errs))
+	} else if len(msg.Values) > 0 {
+		r.opts.Logger.Info(r.ctx, "applied reloaded deployment options",
+			slog.F("reload_id", msg.ReloadID), slog.F("options", len(msg.Values)))
+	}
+	return errs
+}
+
+func (r *Reloader) recordResult(msg message) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.latest == nil || r.latest.ID != msg.ReloadID {
+		return
+	}
+	for i, opt := range r.latest.Options {
+		if opt.RestartRequired {
+			continue
+		}
+		r.latest.Options[i].Replicas = append(opt.Replicas, codersdk.DeploymentConfigReloadReplica{
+			ReplicaID: msg.ReplicaID,
+			Error:     msg.Errors[opt.Name],
+		})
+	}
+}
+
+func newReload(msg message) codersdk.DeploymentConfigReload {
+	reload := codersdk.DeploymentConfigReload{
+		ID:          msg.ReloadID,
+		RequestedAt: msg.RequestedAt,
+		ReplicaID:   msg.ReplicaID,
+		Options:     []codersdk.DeploymentConfigReloadOption{},
+	}
+	for name := range msg.Values {
+		reload.Options = append(reload.Options, codersdk.DeploymentConfigReloadOption{
+			Name:     name,
+			Replicas: []codersdk.DeploymentConfigReloadReplica{},
+		})
+	}
+	for _, name := range msg.RestartRequired {
+		reload.Options = append(reload.Options, codersdk.DeploymentConfigReloadOption{
+			Name:            name,
+			RestartRequired: true,
+			Replicas:        []codersdk.DeploymentConfigReloadReplica{},
+		})
+	}
+	sort.Slice(reload.Options, func(i, j int) bool {
+		return reload.Options[i].Name < reload.Options[j].Name
+	})
+	return reload
+}
+
+// CopyValues returns a copy of vals. Values of options are copied shallowly,
+// which is safe since they are replaced, never modified, on reload.
+func CopyValues(vals *codersdk.DeploymentValues) *codersdk.DeploymentValues {
+	cpy := new(codersdk.DeploymentValues)
+	dst := cpy.Options()
+	for i, opt := range vals.Options() {
+		copyValue(dst[i].Value, opt.Value)
+	}
+	return cpy
+}
+
+// ReadConfigFile reads the deployment values from the config file at path
+// again. running are the options of the running server, parsed are its values
+// as parsed at startup. Options that were set with flags or environment
+// variables keep their running values, since those cannot change while the
+// server runs. So do options whose value in the file did not change since
+// startup, since the server may have derived their running values from other
+// options.
+func ReadConfigFile(path string, running serpent.OptionSet, parsed *codersdk.DeploymentValues) (*codersdk.DeploymentValues, error) {
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("read config file: %w", err)
+	}
+	var n yaml.Node
+	err = yaml.Unmarshal(byt, &n)
+	if err != nil {
+		return nil, xerrors.Errorf("decode config file: %w", err)
+	}
+
+	vals := new(codersdk.DeploymentValues)
+	opts := vals.Options()
+	parsedOpts := parsed.Options()
+	if len(opts) != len(running) {
+		return nil, xerrors.Errorf("running options do not match deployment options")
+	}
+	fixed := func(src serpent.ValueSource) bool {
+		return src == serpent.ValueSourceFlag || src == serpent.ValueSourceEnv
+	}
+	for i := range opts {
+		if fixed(running[i].ValueSource) {
+			opts[i].ValueSource = running[i].ValueSource
+		}
+	}
+	err = opts.UnmarshalYAML(&n)
+	if err != nil {
+		return nil, xerrors.Errorf("apply config file: %w", err)
+	}
+	err = opts.SetDefaults()
+	if err != nil {
+		return nil, xerrors.Errorf("set defaults: %w", err)
+	}
+	for i := range opts {
+		if fixed(opts[i].ValueSource) || opts[i].Value.String() == parsedOpts[i].Value.String() {
+			copyValue(opts[i].Value, running[i].Value)
+		}
+	}
+	return vals, nil
+}
+
+func copyValue(dst, src any) {
+	reflect.ValueOf(underlying(dst)).Elem().Set(reflect.ValueOf(underlying(src)).Elem())
+}
+
+// underlying unwraps values wrapped by serpent.Validate, which point to the
+// value in the deployment values.
+func underlying(value any) any {
+	for {
+		u, ok := value.(interface{ Underlying() pflag.Value })
+		if !ok {
+			return value
+		}
+		value = u.Underlying()
+	}
+}
+
+// setJSON replaces the value with the decoded JSON. Decoding into a new value
+// ensures that e.g. map keys that were removed do not survive.
+func setJSON(value any, raw json.RawMessage) error {
+	value = underlying(value)
+	fresh := reflect.New(reflect.TypeOf(value).Elem())
+	if err := json.Unmarshal(raw, fresh.Interface()); err != nil {
+		return xerrors.Errorf("decode value: %w", err)
+	}
+	reflect.ValueOf(value).Elem().Set(fresh.Elem())
+	return nil
+}
@@ -0,0 +1,160 @@
+package configreload_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/serpent"
+
+	"github.com/coder/coder/v2/coderd/configreload"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestHotReloadableOptionsAreNotSecret(t *testing.T) {
+	t.Parallel()
+
+	// Reloaded values are sent to all replicas through pubsub.
+	for _, opt := range new(codersdk.DeploymentValues).Options() {
+		if codersdk.IsHotReloadableDeploymentOption(opt) {
+			require.False(t, codersdk.IsSecretDeploymentOption(opt), opt.Name)
+		}
+	}
+}
+
+func TestReloader(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	logger := slogtest.Make(t, nil)
+	ps := pubsub.NewInMemory()
+
+	vals := new(codersdk.DeploymentValues)
+	opts := vals.Options()
+	require.NoError(t, opts.SetDefaults())
+
+	newReplica := func(apply configreload.ApplyFunc) *configreload.Reloader {
+		r, err := configreload.New(ctx, configreload.Options{
+			Logger:    logger,
+			Pubsub:    ps,
+			ReplicaID: uuid.New(),
+			Values:    vals,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = r.Close() })
+		r.Register("OIDC Group Mapping", apply)
+		return r
+	}
+
+	applied := make(chan map[string]string, 1)
+	first := newReplica(func(_ context.Context, vals *codersdk.DeploymentValues) error {
+		applied <- vals.OIDC.GroupMapping.Value
+		return nil
+	})
+	second := newReplica(func(context.Context, *codersdk.DeploymentValues) error {
+		return xerrors.New("boom")
+	})
+
+	reloaded := configreload.CopyValues(vals)
+	reloaded.OIDC.GroupMapping.Value = map[string]string{"admins": "platform"}
+	require.NoError(t, reloaded.AccessURL.Set("https://coder.example.com"))
+
+	reload, err := first.Reload(ctx, reloaded)
+	require.NoError(t, err)
+	require.Len(t, reload.Options, 2)
+	require.Equal(t, "Access URL", reload.Options[0].Name)
+	require.True(t, reload.Options[0].RestartRequired)
+	require.Equal(t, "OIDC Group Mapping", reload.Options[1].Name)
+	require.False(t, reload.Options[1].RestartRequired)
+
+	require.Equal(t, map[string]string{"admins": "platform"}, testutil.RequireReceive(ctx, t, applied))
+	// The values the replicas were started with are not modified.
+	require.Empty(t, vals.OIDC.GroupMapping.Value)
+
+	// Both replicas report their results to all replicas.
+	for _, r := range []*configreload.Reloader{first, second} {
+		require.Eventually(t, func() bool {
+			latest, ok := r.Latest()
+			return ok && latest.ID == reload.ID && len(latest.Options[1].Replicas) == 2
+		}, testutil.WaitShort, testutil.IntervalFast)
+	}
+	latest, _ := first.Latest()
+	require.Empty(t, latest.Options[0].Replicas)
+	var errs []string
+	for _, replica := range latest.Options[1].Replicas {
+		errs = append(errs, replica.Error)
+	}
+	require.ElementsMatch(t, []string{"", "boom"}, errs)
+
+	// The second replica kept the old value, so reloading the same values
+	// again retries it.
+	reload, err = second.Reload(ctx, reloaded)
+	require.NoError(t, err)
+	require.Len(t, reload.Options, 2)
+	reload, err = first.Reload(ctx, reloaded)
+	require.NoError(t, err)
+	require.Len(t, reload.Options, 1)
+	require.True(t, reload.Options[0].RestartRequired)
+}
+
+func TestReadConfigFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "coder.yaml")
+	writeConfig := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	writeConfig(`
+oidc:
+  groupMapping:
+    admins: platform
+networking:
+  accessURL: https://coder.example.com
+`)
+
+	// Parse the values the way the server does at startup.
+	vals := new(codersdk.DeploymentValues)
+	opts := vals.Options()
+	require.NoError(t, opts.ParseEnv([]serpent.EnvVar{
+		{Name: "CODER_WILDCARD_ACCESS_URL", Value: "*.env.example.com"},
+	}))
+	readFile := func() {
+		byt, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var n yaml.Node
+		require.NoError(t, yaml.Unmarshal(byt, &n))
+		require.NoError(t, opts.UnmarshalYAML(&n))
+	}
+	readFile()
+	require.NoError(t, opts.SetDefaults())
+	parsed := configreload.CopyValues(vals)
+	// The server derives some values after parsing.
+	require.NoError(t, vals.HTTPAddress.Set("127.0.0.1:1234"))
+
+	writeConfig(`
+oidc:
+  groupMapping:
+    developers: engineering
+networking:
+  accessURL: https://coder.example.com
+  wildcardAccessURL: "*.file.example.com"
+`)
+	reloaded, err := configreload.ReadConfigFile(path, opts, parsed)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"developers": "engineering"}, reloaded.OIDC.GroupMapping.Value)
+	// Options set by environment variables or flags are not changed by the
+	// file.
+	require.Equal(t, "*.env.example.com", reloaded.WildcardAccessURL.String())
+	// Unchanged options keep their running values.
+	require.Equal(t, "127.0.0.1:1234", reloaded.HTTPAddress.String())
+	require.Equal(t, "https://coder.example.com", reloaded.AccessURL.String())
+}
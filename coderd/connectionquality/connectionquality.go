@@ -0,0 +1,297 @@
+// Package connectionquality aggregates the network telemetry reported by
+// workspace connections into a deployment-wide view of how many sessions are
+// direct (P2P) and how many are stuck on DERP relays.
+package connectionquality
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/tailnet/proto"
+	"github.com/coder/quartz"
+)
+
+// DefaultStaleAfter is how long a connection is remembered without receiving
+// any telemetry for it. Clients send a disconnect event when they close
+// cleanly, so this only applies to clients that vanish. dbpurge deletes
+// connections older than this.
+const DefaultStaleAfter = 24 * time.Hour
+
+// collectTimeout bounds the database queries of a Prometheus scrape.
+const collectTimeout = 10 * time.Second
+
+var (
+	connectionsDesc = prometheus.NewDesc(
+		"coderd_network_connections",
+		"The number of active workspace connections by client type and connection type (p2p, derp or unknown).",
+		[]string{"client_type", "connection_type"}, nil,
+	)
+	latencyDesc = prometheus.NewDesc(
+		"coderd_network_connection_latency_seconds",
+		"The median latency of active workspace connections by client type and connection type.",
+		[]string{"client_type", "connection_type"}, nil,
+	)
+	relayFallbacksDesc = prometheus.NewDesc(
+		"coderd_network_relay_fallbacks_total",
+		"The total number of workspace connections that fell back from P2P to a DERP relay.",
+		[]string{"client_type"}, nil,
+	)
+	handshakeRetriesDesc = prometheus.NewDesc(
+		"coderd_network_handshake_retries_total",
+		"The total number of WireGuard handshakes that workspace connections had to retry.",
+		[]string{"client_type"}, nil,
+	)
+)
+
+// Tracker records the latest known state of every workspace connection that
+// reports network telemetry. The state is kept in the database, so every
+// replica reports on the connections of the whole deployment.
+type Tracker struct {
+	db         database.Store
+	log        slog.Logger
+	clock      quartz.Clock
+	staleAfter time.Duration
+}
+
+var _ prometheus.Collector = &Tracker{}
+
+func NewTracker(db database.Store, log slog.Logger, clock quartz.Clock, staleAfter time.Duration) *Tracker {
+	if staleAfter <= 0 {
+		staleAfter = DefaultStaleAfter
+	}
+	return &Tracker{
+		db:         db,
+		log:        log,
+		clock:      clock,
+		staleAfter: staleAfter,
+	}
+}
+
+// Handle records a batch of network telemetry events. Failures are logged,
+// since telemetry is best effort.
+func (t *Tracker) Handle(ctx context.Context, batch []*proto.TelemetryEvent) {
+	//nolint:gocritic // Network telemetry is reported by clients and agents, not users.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	for _, event := range batch {
+		if err := t.handleEvent(ctx, event); err != nil {
+			t.log.Warn(ctx, "failed to record connection quality", slog.Error(err))
+		}
+	}
+}
+
+func (t *Tracker) handleEvent(ctx context.Context, event *proto.TelemetryEvent) error {
+	if event.GetNodeIdRemote() == 0 {
+		// Not connected to a peer, so there's nothing to attribute.
+		return nil
+	}
+	clientType := clientTypeName(event.GetClientType())
+	// Node IDs are unsigned, they are stored bit for bit in a bigint.
+	// #nosec G115
+	nodeIDSelf, nodeIDRemote := int64(event.GetNodeIdSelf()), int64(event.GetNodeIdRemote())
+
+	if event.GetStatus() == proto.TelemetryEvent_DISCONNECTED {
+		err := t.db.DeleteNetworkConnectionQuality(ctx, database.DeleteNetworkConnectionQualityParams{
+			ClientType:   clientType,
+			NodeIDSelf:   nodeIDSelf,
+			NodeIDRemote: nodeIDRemote,
+		})
+		if err != nil {
+			return xerrors.Errorf("delete connection: %w", err)
+		}
+		return nil
+	}
+
+	return t.db.InTx(func(tx database.Store) error {
+		conn, err := tx.GetNetworkConnectionQuality(ctx, database.GetNetworkConnectionQualityParams{
+			ClientType:   clientType,
+			NodeIDSelf:   nodeIDSelf,
+			NodeIDRemote: nodeIDRemote,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			conn = database.NetworkConnectionQuality{
+				ConnectionType: string(codersdk.NetworkConnectionTypeUnknown),
+			}
+		} else if err != nil {
+			return xerrors.Errorf("get connection: %w", err)
+		}
+
+		var fallbacks int64
+		switch {
+		case event.GetP2PLatency() != nil:
+			conn.ConnectionType = string(codersdk.NetworkConnectionTypeP2P)
+			conn.LatencyMS = latencyMS(event.GetP2PLatency().AsDuration())
+		case event.GetDerpLatency() != nil:
+			if conn.ConnectionType == string(codersdk.NetworkConnectionTypeP2P) {
+				fallbacks = 1
+			}
+			conn.ConnectionType = string(codersdk.NetworkConnectionTypeDERP)
+			conn.LatencyMS = latencyMS(event.GetDerpLatency().AsDuration())
+		}
+		// Clients report the retries over the lifetime of the connection, only
+		// the ones we haven't seen yet are added to the totals.
+		var retries int64
+		if reported := int64(event.GetHandshakeRetries()); reported > conn.HandshakeRetries {
+			retries = reported - conn.HandshakeRetries
+			conn.HandshakeRetries = reported
+		}
+
+		err = tx.UpsertNetworkConnectionQuality(ctx, database.UpsertNetworkConnectionQualityParams{
+			ClientType:       clientType,
+			NodeIDSelf:       nodeIDSelf,
+			NodeIDRemote:     nodeIDRemote,
+			ConnectionType:   conn.ConnectionType,
+			LatencyMS:        conn.LatencyMS,
+			HandshakeRetries: conn.HandshakeRetries,
+			UpdatedAt:        dbtime.Time(t.clock.Now()),
+		})
+		if err != nil {
+			return xerrors.Errorf("upsert connection: %w", err)
+		}
+		if fallbacks == 0 && retries == 0 {
+			return nil
+		}
+		err = tx.IncrementNetworkConnectionQualityCounters(ctx, database.IncrementNetworkConnectionQualityCountersParams{
+			ClientType:       clientType,
+			RelayFallbacks:   fallbacks,
+			HandshakeRetries: retries,
+		})
+		if err != nil {
+			return xerrors.Errorf("increment counters: %w", err)
+		}
+		return nil
+	}, nil)
+}
+
+type summary struct {
+	counts    map[codersdk.NetworkConnectionType]int64
+	latencies map[codersdk.NetworkConnectionType]float64
+	fallbacks int64
+	retries   int64
+}
+
+// summarize groups the connections that reported recently by client type.
+func (t *Tracker) summarize(ctx context.Context) (map[string]*summary, error) {
+	//nolint:gocritic // The caller is authorized to read deployment insights.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	rows, err := t.db.GetNetworkConnectionQualitySummary(ctx, dbtime.Time(t.clock.Now().Add(-t.staleAfter)))
+	if err != nil {
+		return nil, xerrors.Errorf("get connections: %w", err)
+	}
+	counters, err := t.db.GetNetworkConnectionQualityCounters(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("get counters: %w", err)
+	}
+
+	summaries := make(map[string]*summary)
+	get := func(clientType string) *summary {
+		s, ok := summaries[clientType]
+		if !ok {
+			s = &summary{
+				counts:    make(map[codersdk.NetworkConnectionType]int64),
+				latencies: make(map[codersdk.NetworkConnectionType]float64),
+			}
+			summaries[clientType] = s
+		}
+		return s
+	}
+	for _, row := range rows {
+		s := get(row.ClientType)
+		connectionType := codersdk.NetworkConnectionType(row.ConnectionType)
+		s.counts[connectionType] = row.Connections
+		if connectionType != codersdk.NetworkConnectionTypeUnknown {
+			s.latencies[connectionType] = row.LatencyMedianMS
+		}
+	}
+	for _, counter := range counters {
+		s := get(counter.ClientType)
+		s.fallbacks = counter.RelayFallbacks
+		s.retries = counter.HandshakeRetries
+	}
+	return summaries, nil
+}
+
+// Report returns the current connection quality of the deployment.
+func (t *Tracker) Report(ctx context.Context) (codersdk.ConnectionQualityResponse, error) {
+	summaries, err := t.summarize(ctx)
+	if err != nil {
+		return codersdk.ConnectionQualityResponse{}, err
+	}
+	res := codersdk.ConnectionQualityResponse{
+		GeneratedAt: t.clock.Now(),
+		ClientTypes: make([]codersdk.ConnectionQualityClientType, 0, len(summaries)),
+	}
+	for clientType, s := range summaries {
+		ct := codersdk.ConnectionQualityClientType{
+			ClientType:          clientType,
+			P2PConnections:      s.counts[codersdk.NetworkConnectionTypeP2P],
+			DERPConnections:     s.counts[codersdk.NetworkConnectionTypeDERP],
+			UnknownConnections:  s.counts[codersdk.NetworkConnectionTypeUnknown],
+			RelayFallbacks:      s.fallbacks,
+			HandshakeRetries:    s.retries,
+			P2PLatencyMedianMS:  s.latencies[codersdk.NetworkConnectionTypeP2P],
+			DERPLatencyMedianMS: s.latencies[codersdk.NetworkConnectionTypeDERP],
+		}
+		ct.Connections = ct.P2PConnections + ct.DERPConnections + ct.UnknownConnections
+		res.ClientTypes = append(res.ClientTypes, ct)
+	}
+	slices.SortFunc(res.ClientTypes, func(a, b codersdk.ConnectionQualityClientType) int {
+		return strings.Compare(a.ClientType, b.ClientType)
+	})
+	return res, nil
+}
+
+func (*Tracker) Describe(descs chan<- *prometheus.Desc) {
+	descs <- connectionsDesc
+	descs <- latencyDesc
+	descs <- relayFallbacksDesc
+	descs <- handshakeRetriesDesc
+}
+
+func (t *Tracker) Collect(metrics chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+	summaries, err := t.summarize(ctx)
+	if err != nil {
+		t.log.Warn(ctx, "failed to collect connection quality metrics", slog.Error(err))
+		return
+	}
+	for clientType, s := range summaries {
+		for _, connectionType := range []codersdk.NetworkConnectionType{
+			codersdk.NetworkConnectionTypeP2P,
+			codersdk.NetworkConnectionTypeDERP,
+			codersdk.NetworkConnectionTypeUnknown,
+		} {
+			metrics <- prometheus.MustNewConstMetric(connectionsDesc, prometheus.GaugeValue,
+				float64(s.counts[connectionType]), clientType, string(connectionType))
+			if latency, ok := s.latencies[connectionType]; ok {
+				metrics <- prometheus.MustNewConstMetric(latencyDesc, prometheus.GaugeValue,
+					latency/1000, clientType, string(connectionType))
+			}
+		}
+		metrics <- prometheus.MustNewConstMetric(relayFallbacksDesc, prometheus.CounterValue,
+			float64(s.fallbacks), clientType)
+		metrics <- prometheus.MustNewConstMetric(handshakeRetriesDesc, prometheus.CounterValue,
+			float64(s.retries), clientType)
+	}
+}
+
+func clientTypeName(clientType proto.TelemetryEvent_ClientType) string {
+	return strings.ToLower(clientType.String())
+}
+
+func latencyMS(latency time.Duration) sql.NullFloat64 {
+	return sql.NullFloat64{Float64: float64(latency) / float64(time.Millisecond), Valid: true}
+}
@@ -0,0 +1,180 @@
+package connectionquality_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/coder/coder/v2/coderd/connectionquality"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/tailnet/proto"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+
+	event := func(self, remote uint64, mut func(e *proto.TelemetryEvent)) *proto.TelemetryEvent {
+		e := &proto.TelemetryEvent{
+			ClientType:   proto.TelemetryEvent_CLI,
+			Status:       proto.TelemetryEvent_CONNECTED,
+			NodeIdSelf:   self,
+			NodeIdRemote: remote,
+		}
+		if mut != nil {
+			mut(e)
+		}
+		return e
+	}
+	p2p := func(latency time.Duration) func(e *proto.TelemetryEvent) {
+		return func(e *proto.TelemetryEvent) { e.P2PLatency = durationpb.New(latency) }
+	}
+	derp := func(latency time.Duration) func(e *proto.TelemetryEvent) {
+		return func(e *proto.TelemetryEvent) { e.DerpLatency = durationpb.New(latency) }
+	}
+
+	t.Run("Report", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, _ := dbtestutil.NewDB(t)
+		mClock := quartz.NewMock(t)
+		tracker := connectionquality.NewTracker(db, testutil.Logger(t), mClock, time.Hour)
+		tracker.Handle(ctx, []*proto.TelemetryEvent{
+			event(1, 10, nil),
+			event(1, 10, p2p(10*time.Millisecond)),
+			event(2, 10, p2p(30*time.Millisecond)),
+			event(3, 10, derp(100*time.Millisecond)),
+			// Unknown, no latency measured yet.
+			event(4, 10, nil),
+			// Not connected to a peer, ignored.
+			event(5, 0, nil),
+			// Falls back from P2P to DERP.
+			event(6, 10, p2p(5*time.Millisecond)),
+			event(6, 10, derp(50*time.Millisecond)),
+			// Connects and disconnects.
+			event(7, 10, p2p(time.Millisecond)),
+			event(7, 10, func(e *proto.TelemetryEvent) { e.Status = proto.TelemetryEvent_DISCONNECTED }),
+			event(8, 10, func(e *proto.TelemetryEvent) { e.ClientType = proto.TelemetryEvent_AGENT }),
+		})
+
+		report, err := tracker.Report(ctx)
+		require.NoError(t, err)
+		require.Equal(t, mClock.Now(), report.GeneratedAt)
+		require.Equal(t, []codersdk.ConnectionQualityClientType{
+			{
+				ClientType:         "agent",
+				Connections:        1,
+				UnknownConnections: 1,
+			},
+			{
+				ClientType:          "cli",
+				Connections:         5,
+				P2PConnections:      2,
+				DERPConnections:     2,
+				UnknownConnections:  1,
+				RelayFallbacks:      1,
+				P2PLatencyMedianMS:  20,
+				DERPLatencyMedianMS: 75,
+			},
+		}, report.ClientTypes)
+	})
+
+	t.Run("HandshakeRetries", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, _ := dbtestutil.NewDB(t)
+		tracker := connectionquality.NewTracker(db, testutil.Logger(t), quartz.NewMock(t), time.Hour)
+		retries := func(n uint32) func(e *proto.TelemetryEvent) {
+			return func(e *proto.TelemetryEvent) { e.HandshakeRetries = n }
+		}
+		tracker.Handle(ctx, []*proto.TelemetryEvent{
+			event(1, 10, retries(2)),
+			// Clients report the total of the connection, so only one more
+			// retry happened.
+			event(1, 10, retries(3)),
+			event(1, 10, retries(3)),
+			event(2, 10, retries(1)),
+		})
+
+		report, err := tracker.Report(ctx)
+		require.NoError(t, err)
+		require.Len(t, report.ClientTypes, 1)
+		require.EqualValues(t, 4, report.ClientTypes[0].HandshakeRetries)
+	})
+
+	t.Run("Replicas", func(t *testing.T) {
+		t.Parallel()
+
+		// Telemetry of the same connection may reach different replicas, and
+		// every replica reports on the whole deployment.
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, _ := dbtestutil.NewDB(t)
+		mClock := quartz.NewMock(t)
+		first := connectionquality.NewTracker(db, testutil.Logger(t), mClock, time.Hour)
+		second := connectionquality.NewTracker(db, testutil.Logger(t), mClock, time.Hour)
+		first.Handle(ctx, []*proto.TelemetryEvent{event(1, 10, p2p(5*time.Millisecond))})
+		second.Handle(ctx, []*proto.TelemetryEvent{
+			event(1, 10, derp(50*time.Millisecond)),
+			event(2, 10, p2p(time.Millisecond)),
+		})
+
+		for _, tracker := range []*connectionquality.Tracker{first, second} {
+			report, err := tracker.Report(ctx)
+			require.NoError(t, err)
+			require.Len(t, report.ClientTypes, 1)
+			require.EqualValues(t, 2, report.ClientTypes[0].Connections)
+			require.EqualValues(t, 1, report.ClientTypes[0].DERPConnections)
+			require.EqualValues(t, 1, report.ClientTypes[0].RelayFallbacks)
+		}
+	})
+
+	t.Run("Stale", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, _ := dbtestutil.NewDB(t)
+		mClock := quartz.NewMock(t)
+		tracker := connectionquality.NewTracker(db, testutil.Logger(t), mClock, time.Hour)
+		tracker.Handle(ctx, []*proto.TelemetryEvent{event(1, 10, p2p(time.Millisecond))})
+		mClock.Advance(30 * time.Minute)
+		tracker.Handle(ctx, []*proto.TelemetryEvent{event(2, 10, p2p(time.Millisecond))})
+		mClock.Advance(31 * time.Minute)
+
+		report, err := tracker.Report(ctx)
+		require.NoError(t, err)
+		require.Len(t, report.ClientTypes, 1)
+		require.EqualValues(t, 1, report.ClientTypes[0].Connections)
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, _ := dbtestutil.NewDB(t)
+		tracker := connectionquality.NewTracker(db, testutil.Logger(t), quartz.NewMock(t), time.Hour)
+		tracker.Handle(ctx, []*proto.TelemetryEvent{
+			event(1, 10, p2p(10*time.Millisecond)),
+			event(2, 10, derp(100*time.Millisecond)),
+		})
+
+		registry := prometheus.NewRegistry()
+		require.NoError(t, registry.Register(tracker))
+		// p2p, derp and unknown connection counts, two latencies, the
+		// fallback and the handshake retry counters.
+		require.Equal(t, 7, promtestutil.CollectAndCount(tracker))
+		require.NoError(t, promtestutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP coderd_network_relay_fallbacks_total The total number of workspace connections that fell back from P2P to a DERP relay.
+# TYPE coderd_network_relay_fallbacks_total counter
+coderd_network_relay_fallbacks_total{client_type="cli"} 0
+`), "coderd_network_relay_fallbacks_total"))
+	})
+}
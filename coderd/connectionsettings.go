@@ -0,0 +1,80 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get user connection settings
+// @ID get-user-connection-settings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.UserConnectionSettings
+// @Router /users/{user}/connection-settings [get]
+func (api *API) userConnectionSettings(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	startOnConnect, err := api.Database.GetUserStartOnConnect(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to retrieve user connection settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.UserConnectionSettings{
+		// Starting on connect is enabled until the user disables it.
+		StartWorkspaceOnConnect: startOnConnect != "false",
+	})
+}
+
+// @Summary Update user connection settings
+// @ID update-user-connection-settings
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param request body codersdk.UserConnectionSettings true "Settings"
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.UserConnectionSettings
+// @Router /users/{user}/connection-settings [put]
+func (api *API) putUserConnectionSettings(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	var settings codersdk.UserConnectionSettings
+	if !httpapi.Read(ctx, rw, r, &settings) {
+		return
+	}
+
+	updated, err := api.Database.UpdateUserStartOnConnect(ctx, database.UpdateUserStartOnConnectParams{
+		UserID:         user.ID,
+		StartOnConnect: strconv.FormatBool(settings.StartWorkspaceOnConnect),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to update user connection settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.UserConnectionSettings{
+		StartWorkspaceOnConnect: updated.Value != "false",
+	})
+}
@@ -0,0 +1,115 @@
+package coderd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserConnectionSettings(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	otherClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	// Starting on connect is enabled by default.
+	settings, err := memberClient.UserConnectionSettings(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.True(t, settings.StartWorkspaceOnConnect)
+
+	settings, err = memberClient.UpdateUserConnectionSettings(ctx, codersdk.Me, codersdk.UserConnectionSettings{
+		StartWorkspaceOnConnect: false,
+	})
+	require.NoError(t, err)
+	require.False(t, settings.StartWorkspaceOnConnect)
+
+	settings, err = memberClient.UserConnectionSettings(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.False(t, settings.StartWorkspaceOnConnect)
+
+	// Members can't change the settings of other users.
+	member, err := memberClient.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	_, err = otherClient.UpdateUserConnectionSettings(ctx, member.ID.String(), codersdk.UserConnectionSettings{
+		StartWorkspaceOnConnect: true,
+	})
+	require.Error(t, err)
+}
+
+func TestStartWorkspaceOnConnect(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.Template, codersdk.Workspace) {
+		t.Helper()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		build := coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStop)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+		return client, template, workspace
+	}
+
+	t.Run("Start", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, _, workspace := setup(t)
+
+		var logs int
+		workspace, err := client.StartWorkspaceOnConnect(ctx, workspace, codersdk.StartOnConnectOptions{
+			Logs: func(codersdk.ProvisionerJobLog) { logs++ },
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, workspace.LatestBuild.Status)
+		require.Positive(t, logs)
+
+		// Running workspaces are returned as is.
+		running, err := client.StartWorkspaceOnConnect(ctx, workspace, codersdk.StartOnConnectOptions{})
+		require.NoError(t, err)
+		require.Equal(t, workspace.LatestBuild.ID, running.LatestBuild.ID)
+	})
+
+	t.Run("DisabledByTemplate", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, template, workspace := setup(t)
+		template, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			AllowStartOnConnect: ptr.Ref(false),
+		})
+		require.NoError(t, err)
+		require.False(t, template.AllowStartOnConnect)
+
+		_, err = client.StartWorkspaceOnConnect(ctx, workspace, codersdk.StartOnConnectOptions{})
+		require.ErrorIs(t, err, codersdk.ErrStartOnConnectDisabled)
+	})
+
+	t.Run("DisabledByUser", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, _, workspace := setup(t)
+		_, err := client.UpdateUserConnectionSettings(ctx, codersdk.Me, codersdk.UserConnectionSettings{
+			StartWorkspaceOnConnect: false,
+		})
+		require.NoError(t, err)
+
+		_, err = client.StartWorkspaceOnConnect(ctx, workspace, codersdk.StartOnConnectOptions{})
+		require.ErrorIs(t, err, codersdk.ErrStartOnConnectDisabled)
+	})
+}
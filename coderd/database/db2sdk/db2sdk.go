@@ -62,6 +62,39 @@ func ExternalAuths(auths []database.ExternalAuthLink, meta map[string]ExternalAu
 	return out
 }
 
+func UserSecret(secret database.UserSecret) codersdk.UserSecret {
+	return codersdk.UserSecret{
+		ID:          secret.ID,
+		UserID:      secret.UserID,
+		Name:        secret.Name,
+		Description: secret.Description,
+		EnvName:     secret.EnvName,
+		CreatedAt:   secret.CreatedAt,
+		UpdatedAt:   secret.UpdatedAt,
+	}
+}
+
+func TemplateSecret(secret database.TemplateSecret) codersdk.TemplateSecret {
+	return codersdk.TemplateSecret{
+		ID:          secret.ID,
+		TemplateID:  secret.TemplateID,
+		Name:        secret.Name,
+		Description: secret.Description,
+		CreatedAt:   secret.CreatedAt,
+		UpdatedAt:   secret.UpdatedAt,
+	}
+}
+
+func TemplateEgressPolicy(policy database.TemplateEgressPolicy) codersdk.TemplateEgressPolicy {
+	return codersdk.TemplateEgressPolicy{
+		TemplateID:     policy.TemplateID,
+		AllowedCIDRs:   policy.AllowedCidrs,
+		AllowedDomains: policy.AllowedDomains,
+		CreatedAt:      policy.CreatedAt,
+		UpdatedAt:      policy.UpdatedAt,
+	}
+}
+
 func ExternalAuth(auth database.ExternalAuthLink, meta ExternalAuthMeta) codersdk.ExternalAuthLink {
 	return codersdk.ExternalAuthLink{
 		ProviderID:      auth.ProviderID,
@@ -344,6 +377,66 @@ func templateVersionParameterOptions(rawOptions json.RawMessage) ([]codersdk.Tem
 	return options, nil
 }
 
+func UserWorkspaceUsage(row database.GetUserWorkspaceUsageRow) codersdk.UserWorkspaceUsage {
+	return codersdk.UserWorkspaceUsage{
+		UserID:              row.UserID,
+		Username:            row.Username,
+		Name:                row.Name,
+		Email:               row.Email,
+		Workspaces:          row.Workspaces,
+		RuntimeHours:        row.RuntimeHours,
+		Cost:                row.Cost,
+		DimensionHours:      workspaceUsageDimensionHours(row.Dimensions, row.DimensionHours),
+		LastSeenAt:          row.LastSeenAt,
+		LastWorkspaceUsedAt: row.LastWorkspaceUsedAt,
+	}
+}
+
+func GroupWorkspaceUsage(row database.GetGroupWorkspaceUsageRow) codersdk.GroupWorkspaceUsage {
+	return codersdk.GroupWorkspaceUsage{
+		GroupID:             row.GroupID,
+		GroupName:           row.GroupName,
+		GroupDisplayName:    row.GroupDisplayName,
+		OrganizationID:      row.OrganizationID,
+		OrganizationName:    row.OrganizationName,
+		Users:               row.Users,
+		Workspaces:          row.Workspaces,
+		RuntimeHours:        row.RuntimeHours,
+		Cost:                row.Cost,
+		DimensionHours:      workspaceUsageDimensionHours(row.Dimensions, row.DimensionHours),
+		LastWorkspaceUsedAt: row.LastWorkspaceUsedAt,
+	}
+}
+
+func workspaceUsageDimensionHours(dimensions []string, hours []float64) map[string]float64 {
+	m := make(map[string]float64, len(dimensions))
+	for i, dimension := range dimensions {
+		if i < len(hours) {
+			m[dimension] = hours[i]
+		}
+	}
+	return m
+}
+
+func ActivityEvent(row database.GetActivityEventsRow) codersdk.ActivityEvent {
+	event := codersdk.ActivityEvent{
+		ID:             row.ID,
+		Time:           row.CreatedAt,
+		Type:           codersdk.ActivityEventType(row.Type),
+		OrganizationID: row.OrganizationID,
+		UserID:         row.UserID,
+		ResourceType:   row.ResourceType,
+		ResourceID:     row.ResourceID,
+		ResourceName:   row.ResourceName,
+		Action:         row.Action,
+		Status:         row.Status,
+	}
+	if row.WorkspaceID.Valid {
+		event.WorkspaceID = &row.WorkspaceID.UUID
+	}
+	return event
+}
+
 func TemplateVersionParameterOptionFromPreview(option *previewtypes.ParameterOption) codersdk.TemplateVersionParameterOption {
 	return codersdk.TemplateVersionParameterOption{
 		Name:        option.Name,
@@ -366,8 +459,9 @@ func OAuth2ProviderApp(accessURL *url.URL, dbApp database.OAuth2ProviderApp) cod
 			Token: accessURL.ResolveReference(&url.URL{
 				Path: "/oauth2/tokens",
 			}).String(),
-			// We do not currently support DeviceAuth.
-			DeviceAuth: "",
+			DeviceAuth: accessURL.ResolveReference(&url.URL{
+				Path: "/oauth2/device",
+			}).String(),
 		},
 	}
 }
@@ -402,6 +496,25 @@ func WorkspaceAgentEnvironment(workspaceAgent database.WorkspaceAgent) (map[stri
 	return envs, nil
 }
 
+// WireguardSettings returns the WireGuard settings for workspaces created from
+// the given template. Non-zero template overrides take precedence over the
+// deployment values.
+func WireguardSettings(deployment codersdk.WireguardConfig, template database.Template) (mtu int, keepaliveInterval, handshakeTimeout time.Duration) {
+	mtu = int(deployment.MTU.Value())
+	if template.WireguardMTU > 0 {
+		mtu = int(template.WireguardMTU)
+	}
+	keepaliveInterval = deployment.KeepaliveInterval.Value()
+	if template.WireguardKeepaliveInterval > 0 {
+		keepaliveInterval = time.Duration(template.WireguardKeepaliveInterval)
+	}
+	handshakeTimeout = deployment.HandshakeTimeout.Value()
+	if template.WireguardHandshakeTimeout > 0 {
+		handshakeTimeout = time.Duration(template.WireguardHandshakeTimeout)
+	}
+	return mtu, keepaliveInterval, handshakeTimeout
+}
+
 func WorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator,
 	dbAgent database.WorkspaceAgent, apps []codersdk.WorkspaceApp, scripts []codersdk.WorkspaceAgentScript, logSources []codersdk.WorkspaceAgentLogSource,
 	agentInactiveDisconnectTimeout time.Duration, agentFallbackTroubleshootingURL string,
@@ -237,7 +237,7 @@ var (
 					rbac.ResourceTemplate.Type:            {policy.ActionRead, policy.ActionUpdate},
 					rbac.ResourceUser.Type:                {policy.ActionRead},
 					rbac.ResourceWorkspace.Type:           {policy.ActionDelete, policy.ActionRead, policy.ActionUpdate, policy.ActionWorkspaceStart, policy.ActionWorkspaceStop},
-					rbac.ResourceWorkspaceDormant.Type:    {policy.ActionDelete, policy.ActionRead, policy.ActionUpdate, policy.ActionWorkspaceStop},
+					rbac.ResourceWorkspaceDormant.Type:    {policy.ActionDelete, policy.ActionRead, policy.ActionUpdate, policy.ActionWorkspaceStart, policy.ActionWorkspaceStop},
 				}),
 				Org:  map[string][]rbac.Permission{},
 				User: []rbac.Permission{},
@@ -1077,6 +1077,90 @@ func (q *querier) authorizeTemplateInsights(ctx context.Context, templateIDs []u
 	return nil
 }
 
+// authorizeTemplateVersionActivation authorizes the action against the template
+// of the version whose activation is requested or reviewed.
+func (q *querier) authorizeTemplateVersionActivation(ctx context.Context, action policy.Action, templateVersionID uuid.UUID) error {
+	tv, err := q.db.GetTemplateVersionByID(ctx, templateVersionID)
+	if err != nil {
+		return xerrors.Errorf("get template version by id: %w", err)
+	}
+	template, err := q.db.GetTemplateByID(ctx, tv.TemplateID.UUID)
+	if err != nil {
+		return xerrors.Errorf("get template by id: %w", err)
+	}
+	return q.authorizeContext(ctx, action, template)
+}
+
+// authorizeTemplateActivation authorizes the action against the template
+// whose active version or activation reviewers are changed.
+func (q *querier) authorizeTemplateActivation(ctx context.Context, action policy.Action, templateID uuid.UUID) error {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return xerrors.Errorf("get template by id: %w", err)
+	}
+	return q.authorizeContext(ctx, action, template)
+}
+
+// authorizeTemplateVersionRollout authorizes the action against the template
+// being rolled out.
+func (q *querier) authorizeTemplateVersionRollout(ctx context.Context, action policy.Action, templateID uuid.UUID) error {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return xerrors.Errorf("get template by id: %w", err)
+	}
+	return q.authorizeContext(ctx, action, template)
+}
+
+// authorizeTemplateEgressPolicy authorizes the action against the template
+// the egress policy belongs to.
+func (q *querier) authorizeTemplateEgressPolicy(ctx context.Context, action policy.Action, templateID uuid.UUID) error {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return xerrors.Errorf("get template by id: %w", err)
+	}
+	return q.authorizeContext(ctx, action, template)
+}
+
+// authorizeTemplateParameterOptionSource authorizes the action against the
+// template whose parameter options are sourced from an API.
+func (q *querier) authorizeTemplateParameterOptionSource(ctx context.Context, action policy.Action, templateID uuid.UUID) error {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return xerrors.Errorf("get template by id: %w", err)
+	}
+	return q.authorizeContext(ctx, action, template)
+}
+
+// authorizeTemplateAppRestriction authorizes the action against the template
+// whose apps are restricted.
+func (q *querier) authorizeTemplateAppRestriction(ctx context.Context, action policy.Action, templateID uuid.UUID) error {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return xerrors.Errorf("get template by id: %w", err)
+	}
+	return q.authorizeContext(ctx, action, template)
+}
+
+// authorizeTemplateAppSessionPolicy authorizes the action against the template
+// whose app sessions are limited.
+func (q *querier) authorizeTemplateAppSessionPolicy(ctx context.Context, action policy.Action, templateID uuid.UUID) error {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return xerrors.Errorf("get template by id: %w", err)
+	}
+	return q.authorizeContext(ctx, action, template)
+}
+
+// authorizeTemplateSecret authorizes access to the secrets of a template.
+// Secret values are only available to users that can update the template.
+func (q *querier) authorizeTemplateSecret(ctx context.Context, templateID uuid.UUID) error {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return xerrors.Errorf("get template by id: %w", err)
+	}
+	return q.authorizeContext(ctx, policy.ActionUpdate, template)
+}
+
 // customRoleEscalationCheck checks to make sure the caller has every permission they are adding
 // to a custom role. This prevents permission escalation.
 func (q *querier) customRoleEscalationCheck(ctx context.Context, actor rbac.Subject, perm rbac.Permission, object rbac.Object) error {
@@ -1228,6 +1312,13 @@ func (q *querier) ActivityBumpWorkspace(ctx context.Context, arg database.Activi
 	return update(q.log, q.auth, fetch, q.db.ActivityBumpWorkspace)(ctx, arg)
 }
 
+func (q *querier) AddWorkspacePortShareLinkBytesTransferred(ctx context.Context, arg database.AddWorkspacePortShareLinkBytesTransferredParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.AddWorkspacePortShareLinkBytesTransferred(ctx, arg)
+}
+
 func (q *querier) AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid.UUID, error) {
 	// Although this technically only reads users, only system-related functions should be
 	// allowed to call this.
@@ -1278,6 +1369,17 @@ func (q *querier) BulkMarkNotificationMessagesSent(ctx context.Context, arg data
 	return q.db.BulkMarkNotificationMessagesSent(ctx, arg)
 }
 
+func (q *querier) CancelPendingTemplateVersionActivationRequests(ctx context.Context, arg database.CancelPendingTemplateVersionActivationRequestsParams) error {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return xerrors.Errorf("get template by id: %w", err)
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return err
+	}
+	return q.db.CancelPendingTemplateVersionActivationRequests(ctx, arg)
+}
+
 func (q *querier) ClaimPrebuiltWorkspace(ctx context.Context, arg database.ClaimPrebuiltWorkspaceParams) (database.ClaimPrebuiltWorkspaceRow, error) {
 	empty := database.ClaimPrebuiltWorkspaceRow{}
 
@@ -1340,6 +1442,13 @@ func (q *querier) CountAuditLogs(ctx context.Context, arg database.CountAuditLog
 	return q.db.CountAuthorizedAuditLogs(ctx, arg, prep)
 }
 
+func (q *querier) CountExternalFilesByHash(ctx context.Context, hash string) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.CountExternalFilesByHash(ctx, hash)
+}
+
 func (q *querier) CountInProgressPrebuilds(ctx context.Context) ([]database.CountInProgressPrebuildsRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceWorkspace.All()); err != nil {
 		return nil, err
@@ -1347,6 +1456,20 @@ func (q *querier) CountInProgressPrebuilds(ctx context.Context) ([]database.Coun
 	return q.db.CountInProgressPrebuilds(ctx)
 }
 
+func (q *querier) CountRunningAITasksByOwnerID(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.CountRunningAITasksByOwnerID(ctx, ownerID)
+}
+
+func (q *querier) CountUnreadInboxNotificationsByCategory(ctx context.Context, userID uuid.UUID) ([]database.CountUnreadInboxNotificationsByCategoryRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceInboxNotification.WithOwner(userID.String())); err != nil {
+		return nil, err
+	}
+	return q.db.CountUnreadInboxNotificationsByCategory(ctx, userID)
+}
+
 func (q *querier) CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceInboxNotification.WithOwner(userID.String())); err != nil {
 		return 0, err
@@ -1367,6 +1490,10 @@ func (q *querier) CustomRoles(ctx context.Context, arg database.CustomRolesParam
 	return q.db.CustomRoles(ctx, arg)
 }
 
+func (q *querier) DeleteAITaskQueueEntryByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetAITaskQueueEntryByID, q.db.DeleteAITaskQueueEntryByID)(ctx, id)
+}
+
 func (q *querier) DeleteAPIKeyByID(ctx context.Context, id string) error {
 	return deleteQ(q.log, q.auth, q.db.GetAPIKeyByID, q.db.DeleteAPIKeyByID)(ctx, id)
 }
@@ -1381,6 +1508,15 @@ func (q *querier) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) e
 	return q.db.DeleteAPIKeysByUserID(ctx, userID)
 }
 
+func (q *querier) DeleteAPIKeysByUserIDLoginType(ctx context.Context, arg database.DeleteAPIKeysByUserIDLoginTypeParams) error {
+	err := q.authorizeContext(ctx, policy.ActionDelete,
+		rbac.ResourceApiKey.WithOwner(arg.UserID.String()))
+	if err != nil {
+		return err
+	}
+	return q.db.DeleteAPIKeysByUserIDLoginType(ctx, arg)
+}
+
 func (q *querier) DeleteAllTailnetClientSubscriptions(ctx context.Context, arg database.DeleteAllTailnetClientSubscriptionsParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceTailnetCoordinator); err != nil {
 		return err
@@ -1412,6 +1548,18 @@ func (q *querier) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context, u
 	return q.db.DeleteApplicationConnectAPIKeysByUserID(ctx, userID)
 }
 
+func (q *querier) DeleteAuditLogLegalHoldByID(ctx context.Context, id uuid.UUID) error {
+	hold, err := q.db.GetAuditLogLegalHoldByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	// Legal holds are managed by whoever can update the organization.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceOrganization.WithID(hold.OrganizationID).InOrg(hold.OrganizationID)); err != nil {
+		return err
+	}
+	return q.db.DeleteAuditLogLegalHoldByID(ctx, id)
+}
+
 func (q *querier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceTailnetCoordinator); err != nil {
 		return err
@@ -1437,6 +1585,29 @@ func (q *querier) DeleteCustomRole(ctx context.Context, arg database.DeleteCusto
 	return q.db.DeleteCustomRole(ctx, arg)
 }
 
+func (q *querier) DeleteExcessAPIKeySessionsByUserID(ctx context.Context, arg database.DeleteExcessAPIKeySessionsByUserIDParams) error {
+	err := q.authorizeContext(ctx, policy.ActionDelete,
+		rbac.ResourceApiKey.WithOwner(arg.UserID.String()))
+	if err != nil {
+		return err
+	}
+	return q.db.DeleteExcessAPIKeySessionsByUserID(ctx, arg)
+}
+
+func (q *querier) DeleteExpiredWebAuthnChallenges(ctx context.Context, now time.Time) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteExpiredWebAuthnChallenges(ctx, now)
+}
+
+func (q *querier) DeleteExpiredWorkspacePortShareLinks(ctx context.Context, now time.Time) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteExpiredWorkspacePortShareLinks(ctx, now)
+}
+
 func (q *querier) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	return fetchAndExec(q.log, q.auth, policy.ActionUpdatePersonal, func(ctx context.Context, arg database.DeleteExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 		//nolint:gosimple
@@ -1460,6 +1631,11 @@ func (q *querier) DeleteGroupMemberFromGroup(ctx context.Context, arg database.D
 	return update(q.log, q.auth, fetch, q.db.DeleteGroupMemberFromGroup)(ctx, arg)
 }
 
+func (q *querier) DeleteGroupQuotaAllowances(ctx context.Context, groupID uuid.UUID) error {
+	// Changing the quota allowances counts as updating a group.
+	return update(q.log, q.auth, q.db.GetGroupByID, q.db.DeleteGroupQuotaAllowances)(ctx, groupID)
+}
+
 func (q *querier) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	err := deleteQ(q.log, q.auth, q.db.GetLicenseByID, func(ctx context.Context, id int32) error {
 		_, err := q.db.DeleteLicense(ctx, id)
@@ -1471,6 +1647,20 @@ func (q *querier) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	return id, nil
 }
 
+func (q *querier) DeleteLicenseSeatReservation(ctx context.Context, groupID uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceLicense); err != nil {
+		return err
+	}
+	return q.db.DeleteLicenseSeatReservation(ctx, groupID)
+}
+
+func (q *querier) DeleteNetworkConnectionQuality(ctx context.Context, arg database.DeleteNetworkConnectionQualityParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteNetworkConnectionQuality(ctx, arg)
+}
+
 func (q *querier) DeleteOAuth2ProviderAppByClientID(ctx context.Context, id uuid.UUID) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceOauth2App); err != nil {
 		return err
@@ -1504,6 +1694,13 @@ func (q *querier) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context
 	return q.db.DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx, arg)
 }
 
+func (q *querier) DeleteOAuth2ProviderAppDeviceCodeByID(ctx context.Context, id uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceOauth2AppCodeToken); err != nil {
+		return err
+	}
+	return q.db.DeleteOAuth2ProviderAppDeviceCodeByID(ctx, id)
+}
+
 func (q *querier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceOauth2AppSecret); err != nil {
 		return err
@@ -1519,6 +1716,27 @@ func (q *querier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Contex
 	return q.db.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, arg)
 }
 
+func (q *querier) DeleteOldAuditLogs(ctx context.Context, arg database.DeleteOldAuditLogsParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteOldAuditLogs(ctx, arg)
+}
+
+func (q *querier) DeleteOldHealthScores(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldHealthScores(ctx, beforeTime)
+}
+
+func (q *querier) DeleteOldNetworkConnectionQuality(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldNetworkConnectionQuality(ctx, beforeTime)
+}
+
 func (q *querier) DeleteOldNotificationMessages(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceNotificationMessage); err != nil {
 		return err
@@ -1579,6 +1797,31 @@ func (q *querier) DeleteRuntimeConfig(ctx context.Context, key string) error {
 	return q.db.DeleteRuntimeConfig(ctx, key)
 }
 
+func (q *querier) DeleteSCIMGroupMembers(ctx context.Context, arg database.DeleteSCIMGroupMembersParams) error {
+	// Changing the SCIM membership of a group counts as updating the group.
+	fetch := func(ctx context.Context, arg database.DeleteSCIMGroupMembersParams) (database.Group, error) {
+		return q.db.GetGroupByID(ctx, arg.GroupID)
+	}
+	return update(q.log, q.auth, fetch, q.db.DeleteSCIMGroupMembers)(ctx, arg)
+}
+
+func (q *querier) DeleteSCIMNestedGroups(ctx context.Context, arg database.DeleteSCIMNestedGroupsParams) error {
+	// Changing the SCIM membership of a group counts as updating the group.
+	fetch := func(ctx context.Context, arg database.DeleteSCIMNestedGroupsParams) (database.Group, error) {
+		return q.db.GetGroupByID(ctx, arg.GroupID)
+	}
+	return update(q.log, q.auth, fetch, q.db.DeleteSCIMNestedGroups)(ctx, arg)
+}
+
+func (q *querier) DeleteStaleAPIKeySessionsByUserID(ctx context.Context, arg database.DeleteStaleAPIKeySessionsByUserIDParams) error {
+	err := q.authorizeContext(ctx, policy.ActionDelete,
+		rbac.ResourceApiKey.WithOwner(arg.UserID.String()))
+	if err != nil {
+		return err
+	}
+	return q.db.DeleteStaleAPIKeySessionsByUserID(ctx, arg)
+}
+
 func (q *querier) DeleteTailnetAgent(ctx context.Context, arg database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceTailnetCoordinator); err != nil {
 		return database.DeleteTailnetAgentRow{}, err
@@ -1614,6 +1857,104 @@ func (q *querier) DeleteTailnetTunnel(ctx context.Context, arg database.DeleteTa
 	return q.db.DeleteTailnetTunnel(ctx, arg)
 }
 
+func (q *querier) DeleteTemplateAppRestriction(ctx context.Context, arg database.DeleteTemplateAppRestrictionParams) error {
+	if err := q.authorizeTemplateAppRestriction(ctx, policy.ActionUpdate, arg.TemplateID); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateAppRestriction(ctx, arg)
+}
+
+func (q *querier) DeleteTemplateAppSessionPolicy(ctx context.Context, arg database.DeleteTemplateAppSessionPolicyParams) error {
+	if err := q.authorizeTemplateAppSessionPolicy(ctx, policy.ActionUpdate, arg.TemplateID); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateAppSessionPolicy(ctx, arg)
+}
+
+func (q *querier) DeleteTemplateBundleByName(ctx context.Context, arg database.DeleteTemplateBundleByNameParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceTemplate.InOrg(arg.OrganizationID)); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateBundleByName(ctx, arg)
+}
+
+func (q *querier) DeleteTemplateEgressPolicy(ctx context.Context, templateID uuid.UUID) error {
+	if err := q.authorizeTemplateEgressPolicy(ctx, policy.ActionUpdate, templateID); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateEgressPolicy(ctx, templateID)
+}
+
+func (q *querier) DeleteTemplateParameterOptionSource(ctx context.Context, arg database.DeleteTemplateParameterOptionSourceParams) error {
+	if err := q.authorizeTemplateParameterOptionSource(ctx, policy.ActionUpdate, arg.TemplateID); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateParameterOptionSource(ctx, arg)
+}
+
+func (q *querier) DeleteTemplateSecret(ctx context.Context, arg database.DeleteTemplateSecretParams) error {
+	if err := q.authorizeTemplateSecret(ctx, arg.TemplateID); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateSecret(ctx, arg)
+}
+
+func (q *querier) DeleteTemplateVariableSet(ctx context.Context, arg database.DeleteTemplateVariableSetParams) error {
+	if err := q.authorizeTemplateSecret(ctx, arg.TemplateID); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateVariableSet(ctx, arg)
+}
+
+func (q *querier) DeleteTemplateVersionActivationReviews(ctx context.Context, templateVersionID uuid.UUID) error {
+	if err := q.authorizeTemplateVersionActivation(ctx, policy.ActionUpdate, templateVersionID); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateVersionActivationReviews(ctx, templateVersionID)
+}
+
+func (q *querier) DeleteUnreferencedExternalFiles(ctx context.Context, createdBefore time.Time) ([]string, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.DeleteUnreferencedExternalFiles(ctx, createdBefore)
+}
+
+func (q *querier) DeleteUserMFARecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, rbac.ResourceUserObject(userID)); err != nil {
+		return err
+	}
+	return q.db.DeleteUserMFARecoveryCodes(ctx, userID)
+}
+
+func (q *querier) DeleteUserSecret(ctx context.Context, arg database.DeleteUserSecretParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, rbac.ResourceUser.WithID(arg.UserID).WithOwner(arg.UserID.String())); err != nil {
+		return err
+	}
+	return q.db.DeleteUserSecret(ctx, arg)
+}
+
+func (q *querier) DeleteUserWebAuthnCredential(ctx context.Context, id uuid.UUID) error {
+	return fetchAndExec(q.log, q.auth, policy.ActionUpdatePersonal, q.db.GetUserWebAuthnCredentialByID, q.db.DeleteUserWebAuthnCredential)(ctx, id)
+}
+
+// Challenges are only used by the WebAuthn ceremonies, which identify the
+// user by the challenge itself.
+
+func (q *querier) DeleteVariableSet(ctx context.Context, id uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
+		return err
+	}
+	return q.db.DeleteVariableSet(ctx, id)
+}
+
+func (q *querier) DeleteWebAuthnChallengeByID(ctx context.Context, id uuid.UUID) (database.WebAuthnChallenge, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return database.WebAuthnChallenge{}, err
+	}
+	return q.db.DeleteWebAuthnChallengeByID(ctx, id)
+}
+
 func (q *querier) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceWebpushSubscription.WithOwner(arg.UserID.String())); err != nil {
 		return err
@@ -1655,6 +1996,55 @@ func (q *querier) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context,
 	return q.db.DeleteWorkspaceAgentPortSharesByTemplate(ctx, templateID)
 }
 
+func (q *querier) DeleteWorkspaceArchiveByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetWorkspaceArchiveByID, q.db.DeleteWorkspaceArchiveByID)(ctx, id)
+}
+
+func (q *querier) DeleteWorkspaceLabelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	fetch := func(ctx context.Context, workspaceID uuid.UUID) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, workspaceID)
+	}
+	return update(q.log, q.auth, fetch, q.db.DeleteWorkspaceLabelsByWorkspaceID)(ctx, workspaceID)
+}
+
+func (q *querier) DeleteWorkspacePortShareLink(ctx context.Context, id uuid.UUID) error {
+	link, err := q.db.GetWorkspacePortShareLinkByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	workspace, err := q.db.GetWorkspaceByID(ctx, link.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	// Deleting a share link is more akin to updating the workspace.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.DeleteWorkspacePortShareLink(ctx, id)
+}
+
+func (q *querier) DeleteWorkspacePortShareLinksByTemplate(ctx context.Context, templateID uuid.UUID) error {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return err
+	}
+
+	return q.db.DeleteWorkspacePortShareLinksByTemplate(ctx, templateID)
+}
+
+func (q *querier) DeleteWorkspaceSchedulePause(ctx context.Context, workspaceID uuid.UUID) error {
+	fetch := func(ctx context.Context, workspaceID uuid.UUID) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, workspaceID)
+	}
+	return update(q.log, q.auth, fetch, q.db.DeleteWorkspaceSchedulePause)(ctx, workspaceID)
+}
+
 func (q *querier) DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID) error {
 	workspace, err := q.db.GetWorkspaceByAgentID(ctx, id)
 	if err != nil {
@@ -1746,6 +2136,28 @@ func (q *querier) FetchVolumesResourceMonitorsUpdatedAfter(ctx context.Context,
 	return q.db.FetchVolumesResourceMonitorsUpdatedAfter(ctx, updatedAt)
 }
 
+func (q *querier) FinishTemplateVersionRollout(ctx context.Context, arg database.FinishTemplateVersionRolloutParams) error {
+	if err := q.authorizeTemplateVersionRollout(ctx, policy.ActionUpdate, arg.TemplateID); err != nil {
+		return err
+	}
+	return q.db.FinishTemplateVersionRollout(ctx, arg)
+}
+
+func (q *querier) GetAITaskQueueEntriesByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]database.AITaskQueue, error) {
+	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetAITaskQueueEntriesByOwnerID)(ctx, ownerID)
+}
+
+func (q *querier) GetAITaskQueueEntryByID(ctx context.Context, id uuid.UUID) (database.AITaskQueue, error) {
+	return fetch(q.log, q.auth, q.db.GetAITaskQueueEntryByID)(ctx, id)
+}
+
+func (q *querier) GetAITaskQueueOwnerIDs(ctx context.Context) ([]uuid.UUID, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetAITaskQueueOwnerIDs(ctx)
+}
+
 func (q *querier) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	return fetch(q.log, q.auth, q.db.GetAPIKeyByID)(ctx, id)
 }
@@ -1754,6 +2166,10 @@ func (q *querier) GetAPIKeyByName(ctx context.Context, arg database.GetAPIKeyByN
 	return fetch(q.log, q.auth, q.db.GetAPIKeyByName)(ctx, arg)
 }
 
+func (q *querier) GetAPIKeySessionsByUserID(ctx context.Context, arg database.GetAPIKeySessionsByUserIDParams) ([]database.APIKey, error) {
+	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetAPIKeySessionsByUserID)(ctx, arg)
+}
+
 func (q *querier) GetAPIKeysByLoginType(ctx context.Context, loginType database.LoginType) ([]database.APIKey, error) {
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetAPIKeysByLoginType)(ctx, loginType)
 }
@@ -1788,6 +2204,19 @@ func (q *querier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, temp
 	return q.db.GetActiveWorkspaceBuildsByTemplateID(ctx, templateID)
 }
 
+func (q *querier) GetActivityEvents(ctx context.Context, arg database.GetActivityEventsParams) ([]database.GetActivityEventsRow, error) {
+	// The feed contains audit events, so reading it requires reading the
+	// audit logs of the deployment, or of the organization it is limited to.
+	object := rbac.ResourceAuditLog
+	if arg.OrganizationID != uuid.Nil {
+		object = object.InOrg(arg.OrganizationID)
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, object); err != nil {
+		return nil, err
+	}
+	return q.db.GetActivityEvents(ctx, arg)
+}
+
 func (q *querier) GetAllTailnetAgents(ctx context.Context) ([]database.TailnetAgent, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceTailnetCoordinator); err != nil {
 		return []database.TailnetAgent{}, err
@@ -1833,6 +2262,24 @@ func (q *querier) GetApplicationName(ctx context.Context) (string, error) {
 	return q.db.GetApplicationName(ctx)
 }
 
+func (q *querier) GetAuditLogLegalHoldByID(ctx context.Context, id uuid.UUID) (database.AuditLogLegalHold, error) {
+	hold, err := q.db.GetAuditLogLegalHoldByID(ctx, id)
+	if err != nil {
+		return database.AuditLogLegalHold{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceAuditLog.InOrg(hold.OrganizationID)); err != nil {
+		return database.AuditLogLegalHold{}, err
+	}
+	return hold, nil
+}
+
+func (q *querier) GetAuditLogLegalHoldsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.AuditLogLegalHold, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceAuditLog.InOrg(organizationID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetAuditLogLegalHoldsByOrganizationID(ctx, organizationID)
+}
+
 func (q *querier) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	// Shortcut if the user is an owner. The SQL filter is noticeable,
 	// and this is an easy win for owners. Which is the common case.
@@ -1934,10 +2381,22 @@ func (q *querier) GetDeploymentWorkspaceStats(ctx context.Context) (database.Get
 	return q.db.GetDeploymentWorkspaceStats(ctx)
 }
 
+func (q *querier) GetDueNotificationDigestMessages(ctx context.Context, now time.Time) ([]database.GetDueNotificationDigestMessagesRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationMessage); err != nil {
+		return nil, err
+	}
+	return q.db.GetDueNotificationDigestMessages(ctx, now)
+}
+
 func (q *querier) GetEligibleProvisionerDaemonsByProvisionerJobIDs(ctx context.Context, provisionerJobIDs []uuid.UUID) ([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow, error) {
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetEligibleProvisionerDaemonsByProvisionerJobIDs)(ctx, provisionerJobIDs)
 }
 
+func (q *querier) GetExperimentFlags(ctx context.Context) (string, error) {
+	// No authz checks, experiments are evaluated for every actor.
+	return q.db.GetExperimentFlags(ctx)
+}
+
 func (q *querier) GetExternalAuthLink(ctx context.Context, arg database.GetExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	return fetchWithAction(q.log, q.auth, policy.ActionReadPersonal, q.db.GetExternalAuthLink)(ctx, arg)
 }
@@ -2046,6 +2505,24 @@ func (q *querier) GetGroupMembersCountByGroupID(ctx context.Context, arg databas
 	return memberCount, nil
 }
 
+func (q *querier) GetGroupQuotaAllowances(ctx context.Context, groupID uuid.UUID) ([]database.GroupQuotaAllowance, error) {
+	if _, err := q.GetGroupByID(ctx, groupID); err != nil { // AuthZ check
+		return nil, err
+	}
+	return q.db.GetGroupQuotaAllowances(ctx, groupID)
+}
+
+func (q *querier) GetGroupWorkspaceUsage(ctx context.Context, arg database.GetGroupWorkspaceUsageParams) ([]database.GetGroupWorkspaceUsageRow, error) {
+	// The report spans all workspaces, users and groups of the deployment.
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentStats); err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceGroup); err != nil {
+		return nil, err
+	}
+	return q.db.GetGroupWorkspaceUsage(ctx, arg)
+}
+
 func (q *querier) GetGroups(ctx context.Context, arg database.GetGroupsParams) ([]database.GetGroupsRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err == nil {
 		// Optimize this query for system users as it is used in telemetry.
@@ -2058,6 +2535,13 @@ func (q *querier) GetGroups(ctx context.Context, arg database.GetGroupsParams) (
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetGroups)(ctx, arg)
 }
 
+func (q *querier) GetHealthScores(ctx context.Context, arg database.GetHealthScoresParams) ([]database.HealthScore, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDebugInfo); err != nil {
+		return nil, err
+	}
+	return q.db.GetHealthScores(ctx, arg)
+}
+
 func (q *querier) GetHealthSettings(ctx context.Context) (string, error) {
 	// No authz checks
 	return q.db.GetHealthSettings(ctx)
@@ -2071,6 +2555,20 @@ func (q *querier) GetInboxNotificationsByUserID(ctx context.Context, userID data
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetInboxNotificationsByUserID)(ctx, userID)
 }
 
+func (q *querier) GetInternalCA(ctx context.Context) (string, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return "", err
+	}
+	return q.db.GetInternalCA(ctx)
+}
+
+func (q *querier) GetLDAPUsers(ctx context.Context) ([]database.LDAPUser, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetLDAPUsers(ctx)
+}
+
 func (q *querier) GetLastUpdateCheck(ctx context.Context) (string, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return "", err
@@ -2092,6 +2590,10 @@ func (q *querier) GetLatestWorkspaceAppStatusesByWorkspaceIDs(ctx context.Contex
 	return q.db.GetLatestWorkspaceAppStatusesByWorkspaceIDs(ctx, ids)
 }
 
+func (q *querier) GetLatestWorkspaceArchiveByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceArchive, error) {
+	return fetch(q.log, q.auth, q.db.GetLatestWorkspaceArchiveByWorkspaceID)(ctx, workspaceID)
+}
+
 func (q *querier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
 	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
 		return database.WorkspaceBuild{}, err
@@ -2123,6 +2625,13 @@ func (q *querier) GetLicenseByID(ctx context.Context, id int32) (database.Licens
 	return fetch(q.log, q.auth, q.db.GetLicenseByID)(ctx, id)
 }
 
+func (q *querier) GetLicenseSeatReservations(ctx context.Context) ([]database.GetLicenseSeatReservationsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceLicense); err != nil {
+		return nil, err
+	}
+	return q.db.GetLicenseSeatReservations(ctx)
+}
+
 func (q *querier) GetLicenses(ctx context.Context) ([]database.License, error) {
 	fetch := func(ctx context.Context, _ interface{}) ([]database.License, error) {
 		return q.db.GetLicenses(ctx)
@@ -2135,6 +2644,27 @@ func (q *querier) GetLogoURL(ctx context.Context) (string, error) {
 	return q.db.GetLogoURL(ctx)
 }
 
+func (q *querier) GetNetworkConnectionQuality(ctx context.Context, arg database.GetNetworkConnectionQualityParams) (database.NetworkConnectionQuality, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.NetworkConnectionQuality{}, err
+	}
+	return q.db.GetNetworkConnectionQuality(ctx, arg)
+}
+
+func (q *querier) GetNetworkConnectionQualityCounters(ctx context.Context) ([]database.NetworkConnectionQualityCounter, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetNetworkConnectionQualityCounters(ctx)
+}
+
+func (q *querier) GetNetworkConnectionQualitySummary(ctx context.Context, updatedAfter time.Time) ([]database.GetNetworkConnectionQualitySummaryRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetNetworkConnectionQualitySummary(ctx, updatedAfter)
+}
+
 func (q *querier) GetNotificationMessagesByStatus(ctx context.Context, arg database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationMessage); err != nil {
 		return nil, err
@@ -2207,6 +2737,14 @@ func (q *querier) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPr
 	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppCodeByPrefix)(ctx, secretPrefix)
 }
 
+func (q *querier) GetOAuth2ProviderAppDeviceCodeByPrefix(ctx context.Context, deviceCodePrefix []byte) (database.OAuth2ProviderAppDeviceCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppDeviceCodeByPrefix)(ctx, deviceCodePrefix)
+}
+
+func (q *querier) GetOAuth2ProviderAppDeviceCodeByUserCode(ctx context.Context, userCode string) (database.OAuth2ProviderAppDeviceCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppDeviceCodeByUserCode)(ctx, userCode)
+}
+
 func (q *querier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceOauth2AppSecret); err != nil {
 		return database.OAuth2ProviderAppSecret{}, err
@@ -2274,6 +2812,13 @@ func (q *querier) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	return q.db.GetOAuthSigningKey(ctx)
 }
 
+func (q *querier) GetOrganizationAuditSettings(ctx context.Context, organizationID uuid.UUID) (database.OrganizationAuditSetting, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceAuditLog.InOrg(organizationID)); err != nil {
+		return database.OrganizationAuditSetting{}, err
+	}
+	return q.db.GetOrganizationAuditSettings(ctx, organizationID)
+}
+
 func (q *querier) GetOrganizationByID(ctx context.Context, id uuid.UUID) (database.Organization, error) {
 	return fetch(q.log, q.auth, q.db.GetOrganizationByID)(ctx, id)
 }
@@ -2288,6 +2833,13 @@ func (q *querier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetOrganizationIDsByMemberIDs)(ctx, ids)
 }
 
+func (q *querier) GetOrganizationMFASettings(ctx context.Context, organizationID uuid.UUID) (database.OrganizationMFASetting, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceOrganization.WithID(organizationID).InOrg(organizationID)); err != nil {
+		return database.OrganizationMFASetting{}, err
+	}
+	return q.db.GetOrganizationMFASettings(ctx, organizationID)
+}
+
 func (q *querier) GetOrganizationResourceCountByID(ctx context.Context, organizationID uuid.UUID) (database.GetOrganizationResourceCountByIDRow, error) {
 	// Can read org members
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceOrganizationMember.InOrg(organizationID)); err != nil {
@@ -2317,6 +2869,13 @@ func (q *querier) GetOrganizationResourceCountByID(ctx context.Context, organiza
 	return q.db.GetOrganizationResourceCountByID(ctx, organizationID)
 }
 
+func (q *querier) GetOrganizationSettingOverrides(ctx context.Context, organizationID uuid.UUID) (database.OrganizationSettingOverride, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceOrganization.WithID(organizationID).InOrg(organizationID)); err != nil {
+		return database.OrganizationSettingOverride{}, err
+	}
+	return q.db.GetOrganizationSettingOverrides(ctx, organizationID)
+}
+
 func (q *querier) GetOrganizations(ctx context.Context, args database.GetOrganizationsParams) ([]database.Organization, error) {
 	fetch := func(ctx context.Context, _ interface{}) ([]database.Organization, error) {
 		return q.db.GetOrganizations(ctx, args)
@@ -2328,6 +2887,13 @@ func (q *querier) GetOrganizationsByUserID(ctx context.Context, userID database.
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetOrganizationsByUserID)(ctx, userID)
 }
 
+func (q *querier) GetOrphanedProvisionerJobs(ctx context.Context, arg database.GetOrphanedProvisionerJobsParams) ([]database.GetOrphanedProvisionerJobsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceProvisionerJobs); err != nil {
+		return nil, err
+	}
+	return q.db.GetOrphanedProvisionerJobs(ctx, arg)
+}
+
 func (q *querier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
 	version, err := q.db.GetTemplateVersionByJobID(ctx, jobID)
 	if err != nil {
@@ -2349,6 +2915,13 @@ func (q *querier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUI
 	return q.db.GetParameterSchemasByJobID(ctx, jobID)
 }
 
+func (q *querier) GetPendingProvisionerJobsCount(ctx context.Context) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceProvisionerJobs); err != nil {
+		return 0, err
+	}
+	return q.db.GetPendingProvisionerJobsCount(ctx)
+}
+
 func (q *querier) GetPrebuildMetrics(ctx context.Context) ([]database.GetPrebuildMetricsRow, error) {
 	// GetPrebuildMetrics returns metrics related to prebuilt workspaces,
 	// such as the number of created and failed prebuilt workspaces.
@@ -2486,6 +3059,13 @@ func (q *querier) GetProvisionerJobByIDForUpdate(ctx context.Context, id uuid.UU
 	return job, nil
 }
 
+func (q *querier) GetProvisionerJobCountsByOrganization(ctx context.Context) ([]database.GetProvisionerJobCountsByOrganizationRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceProvisionerJobs); err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobCountsByOrganization(ctx)
+}
+
 func (q *querier) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
 	_, err := q.GetProvisionerJobByID(ctx, jobID)
 	if err != nil {
@@ -2574,6 +3154,27 @@ func (q *querier) GetQuotaConsumedForUser(ctx context.Context, params database.G
 	return q.db.GetQuotaConsumedForUser(ctx, params)
 }
 
+func (q *querier) GetQuotaDimensionAllowancesForUser(ctx context.Context, arg database.GetQuotaDimensionAllowancesForUserParams) ([]database.GetQuotaDimensionAllowancesForUserRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetQuotaDimensionAllowancesForUser(ctx, arg)
+}
+
+func (q *querier) GetQuotaDimensionsConsumedForGroup(ctx context.Context, groupID uuid.UUID) ([]database.GetQuotaDimensionsConsumedForGroupRow, error) {
+	if _, err := q.GetGroupByID(ctx, groupID); err != nil { // AuthZ check
+		return nil, err
+	}
+	return q.db.GetQuotaDimensionsConsumedForGroup(ctx, groupID)
+}
+
+func (q *querier) GetQuotaDimensionsConsumedForUser(ctx context.Context, arg database.GetQuotaDimensionsConsumedForUserParams) ([]database.GetQuotaDimensionsConsumedForUserRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceUserObject(arg.OwnerID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetQuotaDimensionsConsumedForUser(ctx, arg)
+}
+
 func (q *querier) GetReplicaByID(ctx context.Context, id uuid.UUID) (database.Replica, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return database.Replica{}, err
@@ -2588,6 +3189,32 @@ func (q *querier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Ti
 	return q.db.GetReplicasUpdatedAfter(ctx, updatedAt)
 }
 
+func (q *querier) GetReviewWorkspace(ctx context.Context, arg database.GetReviewWorkspaceParams) (database.ReviewWorkspace, error) {
+	reviewWorkspace, err := q.db.GetReviewWorkspace(ctx, arg)
+	if err != nil {
+		return database.ReviewWorkspace{}, err
+	}
+	// Authorized if the caller can read the workspace.
+	if _, err := q.GetWorkspaceByID(ctx, reviewWorkspace.WorkspaceID); err != nil {
+		return database.ReviewWorkspace{}, err
+	}
+	return reviewWorkspace, nil
+}
+
+func (q *querier) GetReviewWorkspacesByRepositoryAndBranch(ctx context.Context, arg database.GetReviewWorkspacesByRepositoryAndBranchParams) ([]database.ReviewWorkspace, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetReviewWorkspacesByRepositoryAndBranch(ctx, arg)
+}
+
+func (q *querier) GetRollbackWorkspaceBuild(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return database.WorkspaceBuild{}, err
+	}
+	return q.db.GetRollbackWorkspaceBuild(ctx, workspaceID)
+}
+
 func (q *querier) GetRunningPrebuiltWorkspaces(ctx context.Context) ([]database.GetRunningPrebuiltWorkspacesRow, error) {
 	// This query returns only prebuilt workspaces, but we decided to require permissions for all workspaces.
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceWorkspace.All()); err != nil {
@@ -2611,6 +3238,20 @@ func (q *querier) GetRuntimeConfig(ctx context.Context, key string) (string, err
 	return q.db.GetRuntimeConfig(ctx, key)
 }
 
+func (q *querier) GetSCIMGroupMembers(ctx context.Context, organizationID uuid.UUID) ([]database.SCIMGroupMember, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceGroupMember.InOrg(organizationID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetSCIMGroupMembers(ctx, organizationID)
+}
+
+func (q *querier) GetSCIMNestedGroups(ctx context.Context, organizationID uuid.UUID) ([]database.SCIMNestedGroup, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceGroup.InOrg(organizationID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetSCIMNestedGroups(ctx, organizationID)
+}
+
 func (q *querier) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]database.TailnetAgent, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceTailnetCoordinator); err != nil {
 		return nil, err
@@ -2660,6 +3301,21 @@ func (q *querier) GetTelemetryItems(ctx context.Context) ([]database.TelemetryIt
 	return q.db.GetTelemetryItems(ctx)
 }
 
+func (q *querier) GetTemplateActivationForUpdate(ctx context.Context, id uuid.UUID) (database.GetTemplateActivationForUpdateRow, error) {
+	// The template is locked to change its active version.
+	if err := q.authorizeTemplateActivation(ctx, policy.ActionUpdate, id); err != nil {
+		return database.GetTemplateActivationForUpdateRow{}, err
+	}
+	return q.db.GetTemplateActivationForUpdate(ctx, id)
+}
+
+func (q *querier) GetTemplateActivationReviewers(ctx context.Context, templateID uuid.UUID) ([]database.GetTemplateActivationReviewersRow, error) {
+	if err := q.authorizeTemplateActivation(ctx, policy.ActionRead, templateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateActivationReviewers(ctx, templateID)
+}
+
 func (q *querier) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
 		return nil, err
@@ -2675,6 +3331,27 @@ func (q *querier) GetTemplateAppInsightsByTemplate(ctx context.Context, arg data
 	return q.db.GetTemplateAppInsightsByTemplate(ctx, arg)
 }
 
+func (q *querier) GetTemplateAppRestrictions(ctx context.Context, templateID uuid.UUID) ([]database.TemplateAppRestriction, error) {
+	if err := q.authorizeTemplateAppRestriction(ctx, policy.ActionRead, templateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateAppRestrictions(ctx, templateID)
+}
+
+func (q *querier) GetTemplateAppRestrictionsByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.TemplateAppRestriction, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateAppRestrictionsByTemplateIDs(ctx, templateIds)
+}
+
+func (q *querier) GetTemplateAppSessionPolicies(ctx context.Context, templateID uuid.UUID) ([]database.TemplateAppSessionPolicy, error) {
+	if err := q.authorizeTemplateAppSessionPolicy(ctx, policy.ActionRead, templateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateAppSessionPolicies(ctx, templateID)
+}
+
 // Only used by metrics cache.
 func (q *querier) GetTemplateAverageBuildTime(ctx context.Context, arg database.GetTemplateAverageBuildTimeParams) (database.GetTemplateAverageBuildTimeRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
@@ -2683,6 +3360,41 @@ func (q *querier) GetTemplateAverageBuildTime(ctx context.Context, arg database.
 	return q.db.GetTemplateAverageBuildTime(ctx, arg)
 }
 
+func (q *querier) GetTemplateBuildFailures(ctx context.Context, arg database.GetTemplateBuildFailuresParams) ([]database.GetTemplateBuildFailuresRow, error) {
+	if err := q.authorizeTemplateInsights(ctx, []uuid.UUID{arg.TemplateID}); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateBuildFailures(ctx, arg)
+}
+
+func (q *querier) GetTemplateBuildInsightsByInterval(ctx context.Context, arg database.GetTemplateBuildInsightsByIntervalParams) ([]database.GetTemplateBuildInsightsByIntervalRow, error) {
+	if err := q.authorizeTemplateInsights(ctx, []uuid.UUID{arg.TemplateID}); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateBuildInsightsByInterval(ctx, arg)
+}
+
+func (q *querier) GetTemplateBuildStageDurations(ctx context.Context, arg database.GetTemplateBuildStageDurationsParams) ([]database.GetTemplateBuildStageDurationsRow, error) {
+	if err := q.authorizeTemplateInsights(ctx, []uuid.UUID{arg.TemplateID}); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateBuildStageDurations(ctx, arg)
+}
+
+func (q *querier) GetTemplateBundleByName(ctx context.Context, arg database.GetTemplateBundleByNameParams) (database.TemplateBundle, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceTemplate.InOrg(arg.OrganizationID)); err != nil {
+		return database.TemplateBundle{}, err
+	}
+	return q.db.GetTemplateBundleByName(ctx, arg)
+}
+
+func (q *querier) GetTemplateBundlesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.GetTemplateBundlesByOrganizationIDRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceTemplate.InOrg(organizationID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateBundlesByOrganizationID(ctx, organizationID)
+}
+
 func (q *querier) GetTemplateByID(ctx context.Context, id uuid.UUID) (database.Template, error) {
 	return fetch(q.log, q.auth, q.db.GetTemplateByID)(ctx, id)
 }
@@ -2699,6 +3411,13 @@ func (q *querier) GetTemplateDAUs(ctx context.Context, arg database.GetTemplateD
 	return q.db.GetTemplateDAUs(ctx, arg)
 }
 
+func (q *querier) GetTemplateEgressPolicyByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateEgressPolicy, error) {
+	if err := q.authorizeTemplateEgressPolicy(ctx, policy.ActionRead, templateID); err != nil {
+		return database.TemplateEgressPolicy{}, err
+	}
+	return q.db.GetTemplateEgressPolicyByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetTemplateInsights(ctx context.Context, arg database.GetTemplateInsightsParams) (database.GetTemplateInsightsRow, error) {
 	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
 		return database.GetTemplateInsightsRow{}, err
@@ -2725,7 +3444,21 @@ func (q *querier) GetTemplateParameterInsights(ctx context.Context, arg database
 	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
 		return nil, err
 	}
-	return q.db.GetTemplateParameterInsights(ctx, arg)
+	return q.db.GetTemplateParameterInsights(ctx, arg)
+}
+
+func (q *querier) GetTemplateParameterOptionSource(ctx context.Context, arg database.GetTemplateParameterOptionSourceParams) (database.TemplateParameterOptionSource, error) {
+	if err := q.authorizeTemplateParameterOptionSource(ctx, policy.ActionRead, arg.TemplateID); err != nil {
+		return database.TemplateParameterOptionSource{}, err
+	}
+	return q.db.GetTemplateParameterOptionSource(ctx, arg)
+}
+
+func (q *querier) GetTemplateParameterOptionSources(ctx context.Context, templateID uuid.UUID) ([]database.TemplateParameterOptionSource, error) {
+	if err := q.authorizeTemplateParameterOptionSource(ctx, policy.ActionRead, templateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateParameterOptionSources(ctx, templateID)
 }
 
 func (q *querier) GetTemplatePresetsWithPrebuilds(ctx context.Context, templateID uuid.NullUUID) ([]database.GetTemplatePresetsWithPrebuildsRow, error) {
@@ -2737,6 +3470,20 @@ func (q *querier) GetTemplatePresetsWithPrebuilds(ctx context.Context, templateI
 	return q.db.GetTemplatePresetsWithPrebuilds(ctx, templateID)
 }
 
+func (q *querier) GetTemplateSecretByTemplateIDAndName(ctx context.Context, arg database.GetTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
+	if err := q.authorizeTemplateSecret(ctx, arg.TemplateID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return q.db.GetTemplateSecretByTemplateIDAndName(ctx, arg)
+}
+
+func (q *querier) GetTemplateSecretsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateSecret, error) {
+	if err := q.authorizeTemplateSecret(ctx, templateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateSecretsByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
 		return nil, err
@@ -2744,6 +3491,20 @@ func (q *querier) GetTemplateUsageStats(ctx context.Context, arg database.GetTem
 	return q.db.GetTemplateUsageStats(ctx, arg)
 }
 
+func (q *querier) GetTemplateVersionActivationRequestByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) (database.GetTemplateVersionActivationRequestByTemplateVersionIDRow, error) {
+	if err := q.authorizeTemplateVersionActivation(ctx, policy.ActionRead, templateVersionID); err != nil {
+		return database.GetTemplateVersionActivationRequestByTemplateVersionIDRow{}, err
+	}
+	return q.db.GetTemplateVersionActivationRequestByTemplateVersionID(ctx, templateVersionID)
+}
+
+func (q *querier) GetTemplateVersionActivationReviews(ctx context.Context, templateVersionID uuid.UUID) ([]database.GetTemplateVersionActivationReviewsRow, error) {
+	if err := q.authorizeTemplateVersionActivation(ctx, policy.ActionRead, templateVersionID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateVersionActivationReviews(ctx, templateVersionID)
+}
+
 func (q *querier) GetTemplateVersionByID(ctx context.Context, tvid uuid.UUID) (database.TemplateVersion, error) {
 	tv, err := q.db.GetTemplateVersionByID(ctx, tvid)
 	if err != nil {
@@ -2819,6 +3580,29 @@ func (q *querier) GetTemplateVersionParameters(ctx context.Context, templateVers
 	return q.db.GetTemplateVersionParameters(ctx, templateVersionID)
 }
 
+func (q *querier) GetTemplateVersionRolloutBuildStats(ctx context.Context, templateID uuid.UUID) (database.GetTemplateVersionRolloutBuildStatsRow, error) {
+	if err := q.authorizeTemplateVersionRollout(ctx, policy.ActionRead, templateID); err != nil {
+		return database.GetTemplateVersionRolloutBuildStatsRow{}, err
+	}
+	return q.db.GetTemplateVersionRolloutBuildStats(ctx, templateID)
+}
+
+func (q *querier) GetTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateVersionRollout, error) {
+	if err := q.authorizeTemplateVersionRollout(ctx, policy.ActionRead, templateID); err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	return q.db.GetTemplateVersionRolloutByTemplateID(ctx, templateID)
+}
+
+func (q *querier) GetTemplateVersionRolloutTargetsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetTemplateVersionRolloutTargetsByWorkspaceIDsRow, error) {
+	// This function is a system function, like fetching the latest builds of
+	// workspaces, so that workspaces can be listed efficiently.
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateVersionRolloutTargetsByWorkspaceIDs(ctx, ids)
+}
+
 func (q *querier) GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (database.TemplateVersionTerraformValue, error) {
 	// The template_version_terraform_values table should follow the same access
 	// control as the template_version table. Rather than reimplement the checks,
@@ -2960,6 +3744,13 @@ func (q *querier) GetUserByID(ctx context.Context, id uuid.UUID) (database.User,
 	return fetch(q.log, q.auth, q.db.GetUserByID)(ctx, id)
 }
 
+func (q *querier) GetUserCLIVersions(ctx context.Context, seenAfter time.Time) ([]database.GetUserCLIVersionsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentConfig); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserCLIVersions(ctx, seenAfter)
+}
+
 func (q *querier) GetUserCount(ctx context.Context, includeSystem bool) (int64, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return 0, err
@@ -3010,6 +3801,24 @@ func (q *querier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([
 	return q.db.GetUserLinksByUserID(ctx, userID)
 }
 
+func (q *querier) GetUserNotificationCategoryPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationCategoryPreference, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationPreference.WithOwner(userID.String())); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserNotificationCategoryPreferences(ctx, userID)
+}
+
+func (q *querier) GetUserNotificationDigest(ctx context.Context, userID uuid.UUID) (string, error) {
+	u, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, u); err != nil {
+		return "", err
+	}
+	return q.db.GetUserNotificationDigest(ctx, userID)
+}
+
 func (q *querier) GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationPreference.WithOwner(userID.String())); err != nil {
 		return nil, err
@@ -3017,6 +3826,53 @@ func (q *querier) GetUserNotificationPreferences(ctx context.Context, userID uui
 	return q.db.GetUserNotificationPreferences(ctx, userID)
 }
 
+func (q *querier) GetUserProxyLatencies(ctx context.Context, userID uuid.UUID) (string, error) {
+	u, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, u); err != nil {
+		return "", err
+	}
+	return q.db.GetUserProxyLatencies(ctx, userID)
+}
+
+func (q *querier) GetUserProxyPreference(ctx context.Context, userID uuid.UUID) (string, error) {
+	u, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, u); err != nil {
+		return "", err
+	}
+	return q.db.GetUserProxyPreference(ctx, userID)
+}
+
+func (q *querier) GetUserSecretByUserIDAndName(ctx context.Context, arg database.GetUserSecretByUserIDAndNameParams) (database.UserSecret, error) {
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, rbac.ResourceUser.WithID(arg.UserID).WithOwner(arg.UserID.String())); err != nil {
+		return database.UserSecret{}, err
+	}
+	return q.db.GetUserSecretByUserIDAndName(ctx, arg)
+}
+
+func (q *querier) GetUserSecretsByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserSecret, error) {
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, rbac.ResourceUser.WithID(userID).WithOwner(userID.String())); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserSecretsByUserID(ctx, userID)
+}
+
+func (q *querier) GetUserStartOnConnect(ctx context.Context, userID uuid.UUID) (string, error) {
+	u, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, u); err != nil {
+		return "", err
+	}
+	return q.db.GetUserStartOnConnect(ctx, userID)
+}
+
 func (q *querier) GetUserStatusCounts(ctx context.Context, arg database.GetUserStatusCountsParams) ([]database.GetUserStatusCountsRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceUser); err != nil {
 		return nil, err
@@ -3046,6 +3902,28 @@ func (q *querier) GetUserThemePreference(ctx context.Context, userID uuid.UUID)
 	return q.db.GetUserThemePreference(ctx, userID)
 }
 
+func (q *querier) GetUserWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (database.UserWebAuthnCredential, error) {
+	return fetchWithAction(q.log, q.auth, policy.ActionReadPersonal, q.db.GetUserWebAuthnCredentialByCredentialID)(ctx, credentialID)
+}
+
+func (q *querier) GetUserWebAuthnCredentialByID(ctx context.Context, id uuid.UUID) (database.UserWebAuthnCredential, error) {
+	return fetchWithAction(q.log, q.auth, policy.ActionReadPersonal, q.db.GetUserWebAuthnCredentialByID)(ctx, id)
+}
+
+func (q *querier) GetUserWebAuthnCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserWebAuthnCredential, error) {
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, rbac.ResourceUserObject(userID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserWebAuthnCredentialsByUserID(ctx, userID)
+}
+
+func (q *querier) GetUserWebAuthnRequired(ctx context.Context, userID uuid.UUID) (bool, error) {
+	if err := q.authorizeContext(ctx, policy.ActionReadPersonal, rbac.ResourceUserObject(userID)); err != nil {
+		return false, err
+	}
+	return q.db.GetUserWebAuthnRequired(ctx, userID)
+}
+
 func (q *querier) GetUserWorkspaceBuildParameters(ctx context.Context, params database.GetUserWorkspaceBuildParametersParams) ([]database.GetUserWorkspaceBuildParametersRow, error) {
 	u, err := q.db.GetUserByID(ctx, params.OwnerID)
 	if err != nil {
@@ -3061,6 +3939,17 @@ func (q *querier) GetUserWorkspaceBuildParameters(ctx context.Context, params da
 	return q.db.GetUserWorkspaceBuildParameters(ctx, params)
 }
 
+func (q *querier) GetUserWorkspaceUsage(ctx context.Context, arg database.GetUserWorkspaceUsageParams) ([]database.GetUserWorkspaceUsageRow, error) {
+	// The report spans all workspaces and users of the deployment.
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentStats); err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceUser); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserWorkspaceUsage(ctx, arg)
+}
+
 func (q *querier) GetUsers(ctx context.Context, arg database.GetUsersParams) ([]database.GetUsersRow, error) {
 	// This does the filtering in SQL.
 	prep, err := prepareSQLFilter(ctx, q.auth, policy.ActionRead, rbac.ResourceUser.Type)
@@ -3082,6 +3971,31 @@ func (q *querier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]databas
 	return q.db.GetUsersByIDs(ctx, ids)
 }
 
+func (q *querier) GetVariableSetByName(ctx context.Context, name string) (database.VariableSet, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentConfig); err != nil {
+		return database.VariableSet{}, err
+	}
+	return q.db.GetVariableSetByName(ctx, name)
+}
+
+func (q *querier) GetVariableSets(ctx context.Context) ([]database.VariableSet, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentConfig); err != nil {
+		return nil, err
+	}
+	return q.db.GetVariableSets(ctx)
+}
+
+// GetVariableSetsByTemplateID returns the variable sets attached to a
+// template, including their values, so it's authorized like the secrets of
+// the template.
+
+func (q *querier) GetVariableSetsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.VariableSet, error) {
+	if err := q.authorizeTemplateSecret(ctx, templateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetVariableSetsByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceWebpushSubscription.WithOwner(userID.String())); err != nil {
 		return nil, err
@@ -3096,6 +4010,14 @@ func (q *querier) GetWebpushVAPIDKeys(ctx context.Context) (database.GetWebpushV
 	return q.db.GetWebpushVAPIDKeys(ctx)
 }
 
+func (q *querier) GetWorkspaceAdoptionByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAdoption, error) {
+	return fetch(q.log, q.auth, q.db.GetWorkspaceAdoptionByID)(ctx, id)
+}
+
+func (q *querier) GetWorkspaceAdoptionByWorkspaceID(ctx context.Context, workspaceID uuid.NullUUID) (database.WorkspaceAdoption, error) {
+	return fetch(q.log, q.auth, q.db.GetWorkspaceAdoptionByWorkspaceID)(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Context, authToken uuid.UUID) (database.GetWorkspaceAgentAndLatestBuildByAuthTokenRow, error) {
 	// This is a system function
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
@@ -3208,6 +4130,14 @@ func (q *querier) GetWorkspaceAgentStatsAndLabels(ctx context.Context, createdAf
 	return q.db.GetWorkspaceAgentStatsAndLabels(ctx, createdAfter)
 }
 
+func (q *querier) GetWorkspaceAgentTokenRotationByAgentID(ctx context.Context, agentID uuid.UUID) (database.WorkspaceAgentTokenRotation, error) {
+	_, err := q.GetWorkspaceAgentByID(ctx, agentID)
+	if err != nil {
+		return database.WorkspaceAgentTokenRotation{}, err
+	}
+	return q.db.GetWorkspaceAgentTokenRotationByAgentID(ctx, agentID)
+}
+
 func (q *querier) GetWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentUsageStatsRow, error) {
 	return q.db.GetWorkspaceAgentUsageStats(ctx, createdAt)
 }
@@ -3216,6 +4146,27 @@ func (q *querier) GetWorkspaceAgentUsageStatsAndLabels(ctx context.Context, crea
 	return q.db.GetWorkspaceAgentUsageStatsAndLabels(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceAgentVersionsInLatestBuilds(ctx context.Context) ([]database.GetWorkspaceAgentVersionsInLatestBuildsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentConfig); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentVersionsInLatestBuilds(ctx)
+}
+
+func (q *querier) GetWorkspaceAgentVolumeUsageByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentVolumeUsage, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.authorizeContext(ctx, policy.ActionRead, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.db.GetWorkspaceAgentVolumeUsageByAgentID(ctx, agentID)
+}
+
 func (q *querier) GetWorkspaceAgentsByParentID(ctx context.Context, parentID uuid.UUID) ([]database.WorkspaceAgent, error) {
 	workspace, err := q.db.GetWorkspaceByAgentID(ctx, parentID)
 	if err != nil {
@@ -3302,6 +4253,21 @@ func (q *querier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt ti
 	return q.db.GetWorkspaceAppsCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceArchiveByID(ctx context.Context, id uuid.UUID) (database.WorkspaceArchive, error) {
+	return fetch(q.log, q.auth, q.db.GetWorkspaceArchiveByID)(ctx, id)
+}
+
+func (q *querier) GetWorkspaceArchivesByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]database.WorkspaceArchive, error) {
+	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetWorkspaceArchivesByOwnerID)(ctx, ownerID)
+}
+
+func (q *querier) GetWorkspaceArchivesExpiredBefore(ctx context.Context, now time.Time) ([]database.WorkspaceArchive, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceArchivesExpiredBefore(ctx, now)
+}
+
 func (q *querier) GetWorkspaceBuildByID(ctx context.Context, buildID uuid.UUID) (database.WorkspaceBuild, error) {
 	build, err := q.db.GetWorkspaceBuildByID(ctx, buildID)
 	if err != nil {
@@ -3353,6 +4319,14 @@ func (q *querier) GetWorkspaceBuildParametersByBuildIDs(ctx context.Context, wor
 	return q.db.GetAuthorizedWorkspaceBuildParametersByBuildIDs(ctx, workspaceBuildIDs, prep)
 }
 
+func (q *querier) GetWorkspaceBuildQuotaCosts(ctx context.Context, workspaceBuildID uuid.UUID) ([]database.WorkspaceBuildQuotaCost, error) {
+	// If we can read the build, we can read its costs.
+	if _, err := q.GetWorkspaceBuildByID(ctx, workspaceBuildID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceBuildQuotaCosts(ctx, workspaceBuildID)
+}
+
 func (q *querier) GetWorkspaceBuildStatsByTemplates(ctx context.Context, since time.Time) ([]database.GetWorkspaceBuildStatsByTemplatesRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -3397,6 +4371,15 @@ func (q *querier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceApp
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByWorkspaceAppID)(ctx, workspaceAppID)
 }
 
+func (q *querier) GetWorkspaceLabelsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceLabel, error) {
+	// This function is a system function, like fetching the latest builds of
+	// workspaces, so that workspaces can be listed efficiently.
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceLabelsByWorkspaceIDs(ctx, ids)
+}
+
 func (q *querier) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -3411,6 +4394,36 @@ func (q *querier) GetWorkspaceModulesCreatedAfter(ctx context.Context, createdAt
 	return q.db.GetWorkspaceModulesCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspacePortShareLinkByID(ctx context.Context, id uuid.UUID) (database.WorkspacePortShareLink, error) {
+	link, err := q.db.GetWorkspacePortShareLinkByID(ctx, id)
+	if err != nil {
+		return database.WorkspacePortShareLink{}, err
+	}
+	workspace, err := q.db.GetWorkspaceByID(ctx, link.WorkspaceID)
+	if err != nil {
+		return database.WorkspacePortShareLink{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionRead, workspace); err != nil {
+		return database.WorkspacePortShareLink{}, err
+	}
+
+	return link, nil
+}
+
+func (q *querier) GetWorkspacePortShareLinksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspacePortShareLink, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionRead, workspace); err != nil {
+		return nil, err
+	}
+
+	return q.db.GetWorkspacePortShareLinksByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	return fetchWithPostFilter(q.auth, policy.ActionRead, func(ctx context.Context, _ interface{}) ([]database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxies(ctx)
@@ -3432,6 +4445,21 @@ func (q *querier) GetWorkspaceProxyByName(ctx context.Context, name string) (dat
 	return fetch(q.log, q.auth, q.db.GetWorkspaceProxyByName)(ctx, name)
 }
 
+func (q *querier) GetWorkspaceProxyTokenRotationByProxyID(ctx context.Context, proxyID uuid.UUID) (database.WorkspaceProxyTokenRotation, error) {
+	_, err := q.GetWorkspaceProxyByID(ctx, proxyID)
+	if err != nil {
+		return database.WorkspaceProxyTokenRotation{}, err
+	}
+	return q.db.GetWorkspaceProxyTokenRotationByProxyID(ctx, proxyID)
+}
+
+func (q *querier) GetWorkspaceProxyTokenRotations(ctx context.Context) ([]database.WorkspaceProxyTokenRotation, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceWorkspaceProxy); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceProxyTokenRotations(ctx)
+}
+
 func (q *querier) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (database.WorkspaceResource, error) {
 	// TODO: Optimize this
 	resource, err := q.db.GetWorkspaceResourceByID(ctx, id)
@@ -3525,6 +4553,19 @@ func (q *querier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, created
 	return q.db.GetWorkspaceResourcesCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceSchedulePauseByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceSchedulePause, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return database.WorkspaceSchedulePause{}, err
+	}
+
+	// Reading the schedule pause of a workspace is akin to reading the workspace.
+	if err := q.authorizeContext(ctx, policy.ActionRead, workspace); err != nil {
+		return database.WorkspaceSchedulePause{}, err
+	}
+	return q.db.GetWorkspaceSchedulePauseByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIDs []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -3559,11 +4600,30 @@ func (q *querier) GetWorkspacesEligibleForTransition(ctx context.Context, now ti
 	return q.db.GetWorkspacesEligibleForTransition(ctx, now)
 }
 
+func (q *querier) HaltTemplateVersionRollout(ctx context.Context, arg database.HaltTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	if err := q.authorizeTemplateVersionRollout(ctx, policy.ActionUpdate, arg.TemplateID); err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	return q.db.HaltTemplateVersionRollout(ctx, arg)
+}
+
 func (q *querier) HasTemplateVersionsWithAITask(ctx context.Context) (bool, error) {
 	// Anyone can call HasTemplateVersionsWithAITask.
 	return q.db.HasTemplateVersionsWithAITask(ctx)
 }
 
+func (q *querier) IncrementNetworkConnectionQualityCounters(ctx context.Context, arg database.IncrementNetworkConnectionQualityCountersParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.IncrementNetworkConnectionQualityCounters(ctx, arg)
+}
+
+func (q *querier) InsertAITaskQueueEntry(ctx context.Context, arg database.InsertAITaskQueueEntryParams) (database.AITaskQueue, error) {
+	obj := rbac.ResourceWorkspace.InOrg(arg.OrganizationID).WithOwner(arg.OwnerID.String())
+	return insert(q.log, q.auth, obj, q.db.InsertAITaskQueueEntry)(ctx, arg)
+}
+
 func (q *querier) InsertAPIKey(ctx context.Context, arg database.InsertAPIKeyParams) (database.APIKey, error) {
 	return insert(q.log, q.auth,
 		rbac.ResourceApiKey.WithOwner(arg.UserID.String()),
@@ -3579,6 +4639,13 @@ func (q *querier) InsertAuditLog(ctx context.Context, arg database.InsertAuditLo
 	return insert(q.log, q.auth, rbac.ResourceAuditLog, q.db.InsertAuditLog)(ctx, arg)
 }
 
+func (q *querier) InsertAuditLogLegalHold(ctx context.Context, arg database.InsertAuditLogLegalHoldParams) (database.AuditLogLegalHold, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceOrganization.WithID(arg.OrganizationID).InOrg(arg.OrganizationID)); err != nil {
+		return database.AuditLogLegalHold{}, err
+	}
+	return q.db.InsertAuditLogLegalHold(ctx, arg)
+}
+
 func (q *querier) InsertCryptoKey(ctx context.Context, arg database.InsertCryptoKeyParams) (database.CryptoKey, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceCryptoKey); err != nil {
 		return database.CryptoKey{}, err
@@ -3655,6 +4722,20 @@ func (q *querier) InsertGroupMember(ctx context.Context, arg database.InsertGrou
 	return update(q.log, q.auth, fetch, q.db.InsertGroupMember)(ctx, arg)
 }
 
+func (q *querier) InsertGroupQuotaAllowances(ctx context.Context, arg database.InsertGroupQuotaAllowancesParams) error {
+	fetch := func(ctx context.Context, arg database.InsertGroupQuotaAllowancesParams) (database.Group, error) {
+		return q.db.GetGroupByID(ctx, arg.GroupID)
+	}
+	return update(q.log, q.auth, fetch, q.db.InsertGroupQuotaAllowances)(ctx, arg)
+}
+
+func (q *querier) InsertHealthScore(ctx context.Context, arg database.InsertHealthScoreParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertHealthScore(ctx, arg)
+}
+
 func (q *querier) InsertInboxNotification(ctx context.Context, arg database.InsertInboxNotificationParams) (database.InboxNotification, error) {
 	return insert(q.log, q.auth, rbac.ResourceInboxNotification.WithOwner(arg.UserID.String()), q.db.InsertInboxNotification)(ctx, arg)
 }
@@ -3696,6 +4777,13 @@ func (q *querier) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.
 	return q.db.InsertOAuth2ProviderAppCode(ctx, arg)
 }
 
+func (q *querier) InsertOAuth2ProviderAppDeviceCode(ctx context.Context, arg database.InsertOAuth2ProviderAppDeviceCodeParams) (database.OAuth2ProviderAppDeviceCode, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceOauth2AppCodeToken); err != nil {
+		return database.OAuth2ProviderAppDeviceCode{}, err
+	}
+	return q.db.InsertOAuth2ProviderAppDeviceCode(ctx, arg)
+}
+
 func (q *querier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceOauth2AppSecret); err != nil {
 		return database.OAuth2ProviderAppSecret{}, err
@@ -3771,6 +4859,13 @@ func (q *querier) InsertProvisionerJobLogs(ctx context.Context, arg database.Ins
 	return q.db.InsertProvisionerJobLogs(ctx, arg)
 }
 
+func (q *querier) InsertProvisionerJobRequeue(ctx context.Context, arg database.InsertProvisionerJobRequeueParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceProvisionerJobs); err != nil {
+		return err
+	}
+	return q.db.InsertProvisionerJobRequeue(ctx, arg)
+}
+
 func (q *querier) InsertProvisionerJobTimings(ctx context.Context, arg database.InsertProvisionerJobTimingsParams) ([]database.ProvisionerJobTiming, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceProvisionerJobs); err != nil {
 		return nil, err
@@ -3789,6 +4884,22 @@ func (q *querier) InsertReplica(ctx context.Context, arg database.InsertReplicaP
 	return q.db.InsertReplica(ctx, arg)
 }
 
+func (q *querier) InsertSCIMGroupMembers(ctx context.Context, arg database.InsertSCIMGroupMembersParams) error {
+	// Changing the SCIM membership of a group counts as updating the group.
+	fetch := func(ctx context.Context, arg database.InsertSCIMGroupMembersParams) (database.Group, error) {
+		return q.db.GetGroupByID(ctx, arg.GroupID)
+	}
+	return update(q.log, q.auth, fetch, q.db.InsertSCIMGroupMembers)(ctx, arg)
+}
+
+func (q *querier) InsertSCIMNestedGroups(ctx context.Context, arg database.InsertSCIMNestedGroupsParams) error {
+	// Changing the SCIM membership of a group counts as updating the group.
+	fetch := func(ctx context.Context, arg database.InsertSCIMNestedGroupsParams) (database.Group, error) {
+		return q.db.GetGroupByID(ctx, arg.GroupID)
+	}
+	return update(q.log, q.auth, fetch, q.db.InsertSCIMNestedGroups)(ctx, arg)
+}
+
 func (q *querier) InsertTelemetryItemIfNotExists(ctx context.Context, arg database.InsertTelemetryItemIfNotExistsParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -3804,6 +4915,27 @@ func (q *querier) InsertTemplate(ctx context.Context, arg database.InsertTemplat
 	return q.db.InsertTemplate(ctx, arg)
 }
 
+func (q *querier) InsertTemplateBundle(ctx context.Context, arg database.InsertTemplateBundleParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceTemplate.InOrg(arg.OrganizationID)); err != nil {
+		return err
+	}
+	return q.db.InsertTemplateBundle(ctx, arg)
+}
+
+func (q *querier) InsertTemplateSecret(ctx context.Context, arg database.InsertTemplateSecretParams) (database.TemplateSecret, error) {
+	if err := q.authorizeTemplateSecret(ctx, arg.TemplateID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return q.db.InsertTemplateSecret(ctx, arg)
+}
+
+func (q *querier) InsertTemplateVariableSet(ctx context.Context, arg database.InsertTemplateVariableSetParams) error {
+	if err := q.authorizeTemplateSecret(ctx, arg.TemplateID); err != nil {
+		return err
+	}
+	return q.db.InsertTemplateVariableSet(ctx, arg)
+}
+
 func (q *querier) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
 	if !arg.TemplateID.Valid {
 		// Making a new template version is the same permission as creating a new template.
@@ -3889,7 +5021,32 @@ func (q *querier) InsertUserLink(ctx context.Context, arg database.InsertUserLin
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceUserObject(arg.UserID)); err != nil {
 		return database.UserLink{}, err
 	}
-	return q.db.InsertUserLink(ctx, arg)
+	return q.db.InsertUserLink(ctx, arg)
+}
+
+func (q *querier) InsertUserMFARecoveryCode(ctx context.Context, arg database.InsertUserMFARecoveryCodeParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return err
+	}
+	return q.db.InsertUserMFARecoveryCode(ctx, arg)
+}
+
+func (q *querier) InsertUserSecret(ctx context.Context, arg database.InsertUserSecretParams) (database.UserSecret, error) {
+	return insertWithAction(q.log, q.auth, rbac.ResourceUser.WithID(arg.UserID).WithOwner(arg.UserID.String()), policy.ActionUpdatePersonal, q.db.InsertUserSecret)(ctx, arg)
+}
+
+func (q *querier) InsertUserWebAuthnCredential(ctx context.Context, arg database.InsertUserWebAuthnCredentialParams) (database.UserWebAuthnCredential, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return database.UserWebAuthnCredential{}, err
+	}
+	return q.db.InsertUserWebAuthnCredential(ctx, arg)
+}
+
+func (q *querier) InsertVariableSet(ctx context.Context, arg database.InsertVariableSetParams) (database.VariableSet, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
+		return database.VariableSet{}, err
+	}
+	return q.db.InsertVariableSet(ctx, arg)
 }
 
 func (q *querier) InsertVolumeResourceMonitor(ctx context.Context, arg database.InsertVolumeResourceMonitorParams) (database.WorkspaceAgentVolumeResourceMonitor, error) {
@@ -3900,6 +5057,13 @@ func (q *querier) InsertVolumeResourceMonitor(ctx context.Context, arg database.
 	return q.db.InsertVolumeResourceMonitor(ctx, arg)
 }
 
+func (q *querier) InsertWebAuthnChallenge(ctx context.Context, arg database.InsertWebAuthnChallengeParams) (database.WebAuthnChallenge, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.WebAuthnChallenge{}, err
+	}
+	return q.db.InsertWebAuthnChallenge(ctx, arg)
+}
+
 func (q *querier) InsertWebpushSubscription(ctx context.Context, arg database.InsertWebpushSubscriptionParams) (database.WebpushSubscription, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceWebpushSubscription.WithOwner(arg.UserID.String())); err != nil {
 		return database.WebpushSubscription{}, err
@@ -3920,6 +5084,17 @@ func (q *querier) InsertWorkspace(ctx context.Context, arg database.InsertWorksp
 	return insert(q.log, q.auth, obj, q.db.InsertWorkspace)(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAdoption(ctx context.Context, arg database.InsertWorkspaceAdoptionParams) (database.WorkspaceAdoption, error) {
+	// Adopting a machine creates a workspace for the owner.
+	obj := rbac.ResourceWorkspace.
+		InOrg(arg.OrganizationID).
+		WithOwner(arg.OwnerID.String())
+	if err := q.authorizeContext(ctx, policy.ActionCreate, obj); err != nil {
+		return database.WorkspaceAdoption{}, err
+	}
+	return q.db.InsertWorkspaceAdoption(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgent(ctx context.Context, arg database.InsertWorkspaceAgentParams) (database.WorkspaceAgent, error) {
 	// NOTE(DanielleMaywood):
 	// Currently, the only way to link a Resource back to a Workspace is by following this chain:
@@ -3969,6 +5144,20 @@ func (q *querier) InsertWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.InsertWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentPushedMetadata(ctx context.Context, arg database.InsertWorkspaceAgentPushedMetadataParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
+	if err != nil {
+		return err
+	}
+
+	err = q.authorizeContext(ctx, policy.ActionUpdate, workspace)
+	if err != nil {
+		return err
+	}
+
+	return q.db.InsertWorkspaceAgentPushedMetadata(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg database.InsertWorkspaceAgentScriptTimingsParams) (database.WorkspaceAgentScriptTiming, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.WorkspaceAgentScriptTiming{}, err
@@ -4005,6 +5194,18 @@ func (q *querier) InsertWorkspaceAppStatus(ctx context.Context, arg database.Ins
 	return q.db.InsertWorkspaceAppStatus(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceArchive(ctx context.Context, arg database.InsertWorkspaceArchiveParams) (database.WorkspaceArchive, error) {
+	// Archiving a workspace is akin to updating it.
+	obj := rbac.ResourceWorkspaceDormant.
+		WithID(arg.WorkspaceID).
+		InOrg(arg.OrganizationID).
+		WithOwner(arg.OwnerID.String())
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, obj); err != nil {
+		return database.WorkspaceArchive{}, err
+	}
+	return q.db.InsertWorkspaceArchive(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceBuild(ctx context.Context, arg database.InsertWorkspaceBuildParams) error {
 	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
 	if err != nil {
@@ -4067,6 +5268,20 @@ func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg databa
 	return q.db.InsertWorkspaceBuildParameters(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceBuildQuotaCosts(ctx context.Context, arg database.InsertWorkspaceBuildQuotaCostsParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertWorkspaceBuildQuotaCosts(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceLabels(ctx context.Context, arg database.InsertWorkspaceLabelsParams) error {
+	fetch := func(ctx context.Context, arg database.InsertWorkspaceLabelsParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	}
+	return update(q.log, q.auth, fetch, q.db.InsertWorkspaceLabels)(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceModule(ctx context.Context, arg database.InsertWorkspaceModuleParams) (database.WorkspaceModule, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.WorkspaceModule{}, err
@@ -4074,6 +5289,20 @@ func (q *querier) InsertWorkspaceModule(ctx context.Context, arg database.Insert
 	return q.db.InsertWorkspaceModule(ctx, arg)
 }
 
+func (q *querier) InsertWorkspacePortShareLink(ctx context.Context, arg database.InsertWorkspacePortShareLinkParams) (database.WorkspacePortShareLink, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspacePortShareLink{}, err
+	}
+
+	// Sharing a port is more akin to updating the workspace.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return database.WorkspacePortShareLink{}, err
+	}
+
+	return q.db.InsertWorkspacePortShareLink(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceProxy(ctx context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	return insert(q.log, q.auth, rbac.ResourceWorkspaceProxy, q.db.InsertWorkspaceProxy)(ctx, arg)
 }
@@ -4195,6 +5424,13 @@ func (q *querier) RemoveUserFromGroups(ctx context.Context, arg database.RemoveU
 	return q.db.RemoveUserFromGroups(ctx, arg)
 }
 
+func (q *querier) RequeueProvisionerJob(ctx context.Context, arg database.RequeueProvisionerJobParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceProvisionerJobs); err != nil {
+		return err
+	}
+	return q.db.RequeueProvisionerJob(ctx, arg)
+}
+
 func (q *querier) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -4202,6 +5438,46 @@ func (q *querier) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string)
 	return q.db.RevokeDBCryptKey(ctx, activeKeyDigest)
 }
 
+func (q *querier) RotateWorkspaceAgentAuthToken(ctx context.Context, arg database.RotateWorkspaceAgentAuthTokenParams) (database.WorkspaceAgentTokenRotation, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return database.WorkspaceAgentTokenRotation{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceAgentTokenRotation{}, err
+	}
+
+	return q.db.RotateWorkspaceAgentAuthToken(ctx, arg)
+}
+
+func (q *querier) RotateWorkspaceProxyToken(ctx context.Context, arg database.RotateWorkspaceProxyTokenParams) (database.WorkspaceProxyTokenRotation, error) {
+	proxy, err := q.db.GetWorkspaceProxyByID(ctx, arg.ProxyID)
+	if err != nil {
+		return database.WorkspaceProxyTokenRotation{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, proxy); err != nil {
+		return database.WorkspaceProxyTokenRotation{}, err
+	}
+
+	return q.db.RotateWorkspaceProxyToken(ctx, arg)
+}
+
+func (q *querier) SearchProvisionerJobLogs(ctx context.Context, arg database.SearchProvisionerJobLogsParams) ([]database.SearchProvisionerJobLogsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceProvisionerJobs.InOrg(arg.OrganizationID)); err != nil {
+		return nil, err
+	}
+	return q.db.SearchProvisionerJobLogs(ctx, arg)
+}
+
+func (q *querier) StartTemplateVersionRollout(ctx context.Context, arg database.StartTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	if err := q.authorizeTemplateVersionRollout(ctx, policy.ActionUpdate, arg.TemplateID); err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	return q.db.StartTemplateVersionRollout(ctx, arg)
+}
+
 func (q *querier) TryAcquireLock(ctx context.Context, id int64) (bool, error) {
 	return q.db.TryAcquireLock(ctx, id)
 }
@@ -4229,6 +5505,13 @@ func (q *querier) UnfavoriteWorkspace(ctx context.Context, id uuid.UUID) error {
 	return update(q.log, q.auth, fetch, q.db.UnfavoriteWorkspace)(ctx, id)
 }
 
+func (q *querier) UpdateAITaskQueueEntryFailed(ctx context.Context, arg database.UpdateAITaskQueueEntryFailedParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateAITaskQueueEntryFailed(ctx, arg)
+}
+
 func (q *querier) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateAPIKeyByIDParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateAPIKeyByIDParams) (database.APIKey, error) {
 		return q.db.GetAPIKeyByID(ctx, arg.ID)
@@ -4310,6 +5593,13 @@ func (q *querier) UpdateInboxNotificationReadStatus(ctx context.Context, args da
 	return update(q.log, q.auth, fetchFunc, q.db.UpdateInboxNotificationReadStatus)(ctx, args)
 }
 
+func (q *querier) UpdateInboxNotificationsReadStatus(ctx context.Context, arg database.UpdateInboxNotificationsReadStatusParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceInboxNotification.WithOwner(arg.UserID.String())); err != nil {
+		return 0, err
+	}
+	return q.db.UpdateInboxNotificationsReadStatus(ctx, arg)
+}
+
 func (q *querier) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
 	// Authorized fetch will check that the actor has read access to the org member since the org member is returned.
 	member, err := database.ExpectOne(q.OrganizationMembers(ctx, database.OrganizationMembersParams{
@@ -4375,6 +5665,23 @@ func (q *querier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.
 	return q.db.UpdateOAuth2ProviderAppByID(ctx, arg)
 }
 
+func (q *querier) UpdateOAuth2ProviderAppDeviceCodeLastPolledAt(ctx context.Context, arg database.UpdateOAuth2ProviderAppDeviceCodeLastPolledAtParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateOAuth2ProviderAppDeviceCodeLastPolledAt(ctx, arg)
+}
+
+func (q *querier) UpdateOAuth2ProviderAppDeviceCodeUserID(ctx context.Context, arg database.UpdateOAuth2ProviderAppDeviceCodeUserIDParams) (database.OAuth2ProviderAppDeviceCode, error) {
+	// Approving a device code is equivalent to creating an authorization code
+	// for the user.
+	if err := q.authorizeContext(ctx, policy.ActionCreate,
+		rbac.ResourceOauth2AppCodeToken.WithOwner(arg.UserID.UUID.String())); err != nil {
+		return database.OAuth2ProviderAppDeviceCode{}, err
+	}
+	return q.db.UpdateOAuth2ProviderAppDeviceCodeUserID(ctx, arg)
+}
+
 func (q *querier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceOauth2AppSecret); err != nil {
 		return database.OAuth2ProviderAppSecret{}, err
@@ -4545,6 +5852,13 @@ func (q *querier) UpdateTemplateAccessControlByID(ctx context.Context, arg datab
 	return update(q.log, q.auth, fetch, q.db.UpdateTemplateAccessControlByID)(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateActivationReviewers(ctx context.Context, arg database.UpdateTemplateActivationReviewersParams) error {
+	if err := q.authorizeTemplateActivation(ctx, policy.ActionUpdate, arg.TemplateID); err != nil {
+		return err
+	}
+	return q.db.UpdateTemplateActivationReviewers(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateActiveVersionByID(ctx context.Context, arg database.UpdateTemplateActiveVersionByIDParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateActiveVersionByIDParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.ID)
@@ -4564,6 +5878,21 @@ func (q *querier) UpdateTemplateMetaByID(ctx context.Context, arg database.Updat
 	return update(q.log, q.auth, fetch, q.db.UpdateTemplateMetaByID)(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateOrganizationByID(ctx context.Context, arg database.UpdateTemplateOrganizationByIDParams) error {
+	template, err := q.db.GetTemplateByID(ctx, arg.ID)
+	if err != nil {
+		return xerrors.Errorf("get template by id: %w", err)
+	}
+	// The template leaves its organization, and is created in the new one.
+	if err := q.authorizeContext(ctx, policy.ActionDelete, template); err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceTemplate.InOrg(arg.OrganizationID)); err != nil {
+		return err
+	}
+	return q.db.UpdateTemplateOrganizationByID(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateScheduleByID(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.ID)
@@ -4571,6 +5900,13 @@ func (q *querier) UpdateTemplateScheduleByID(ctx context.Context, arg database.U
 	return update(q.log, q.auth, fetch, q.db.UpdateTemplateScheduleByID)(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateSecret(ctx context.Context, arg database.UpdateTemplateSecretParams) (database.TemplateSecret, error) {
+	if err := q.authorizeTemplateSecret(ctx, arg.TemplateID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return q.db.UpdateTemplateSecret(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateVersionAITaskByJobID(ctx context.Context, arg database.UpdateTemplateVersionAITaskByJobIDParams) error {
 	// An actor is allowed to update the template version AI task flag if they are authorized to update the template.
 	tv, err := q.db.GetTemplateVersionByJobID(ctx, arg.JobID)
@@ -4593,6 +5929,13 @@ func (q *querier) UpdateTemplateVersionAITaskByJobID(ctx context.Context, arg da
 	return q.db.UpdateTemplateVersionAITaskByJobID(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateVersionActivationRequestStatus(ctx context.Context, arg database.UpdateTemplateVersionActivationRequestStatusParams) (database.TemplateVersionActivationRequest, error) {
+	if err := q.authorizeTemplateVersionActivation(ctx, policy.ActionUpdate, arg.TemplateVersionID); err != nil {
+		return database.TemplateVersionActivationRequest{}, err
+	}
+	return q.db.UpdateTemplateVersionActivationRequestStatus(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateVersionByID(ctx context.Context, arg database.UpdateTemplateVersionByIDParams) error {
 	// An actor is allowed to update the template version if they are authorized to update the template.
 	tv, err := q.db.GetTemplateVersionByID(ctx, arg.ID)
@@ -4745,6 +6088,24 @@ func (q *querier) UpdateUserLoginType(ctx context.Context, arg database.UpdateUs
 	return q.db.UpdateUserLoginType(ctx, arg)
 }
 
+func (q *querier) UpdateUserNotificationCategoryPreferences(ctx context.Context, arg database.UpdateUserNotificationCategoryPreferencesParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceNotificationPreference.WithOwner(arg.UserID.String())); err != nil {
+		return -1, err
+	}
+	return q.db.UpdateUserNotificationCategoryPreferences(ctx, arg)
+}
+
+func (q *querier) UpdateUserNotificationDigest(ctx context.Context, arg database.UpdateUserNotificationDigestParams) (database.UserConfig, error) {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return database.UserConfig{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, u); err != nil {
+		return database.UserConfig{}, err
+	}
+	return q.db.UpdateUserNotificationDigest(ctx, arg)
+}
+
 func (q *querier) UpdateUserNotificationPreferences(ctx context.Context, arg database.UpdateUserNotificationPreferencesParams) (int64, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceNotificationPreference.WithOwner(arg.UserID.String())); err != nil {
 		return -1, err
@@ -4763,6 +6124,28 @@ func (q *querier) UpdateUserProfile(ctx context.Context, arg database.UpdateUser
 	return q.db.UpdateUserProfile(ctx, arg)
 }
 
+func (q *querier) UpdateUserProxyLatencies(ctx context.Context, arg database.UpdateUserProxyLatenciesParams) (database.UserConfig, error) {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return database.UserConfig{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, u); err != nil {
+		return database.UserConfig{}, err
+	}
+	return q.db.UpdateUserProxyLatencies(ctx, arg)
+}
+
+func (q *querier) UpdateUserProxyPreference(ctx context.Context, arg database.UpdateUserProxyPreferenceParams) (database.UserConfig, error) {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return database.UserConfig{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, u); err != nil {
+		return database.UserConfig{}, err
+	}
+	return q.db.UpdateUserProxyPreference(ctx, arg)
+}
+
 func (q *querier) UpdateUserQuietHoursSchedule(ctx context.Context, arg database.UpdateUserQuietHoursScheduleParams) (database.User, error) {
 	u, err := q.db.GetUserByID(ctx, arg.ID)
 	if err != nil {
@@ -4797,6 +6180,24 @@ func (q *querier) UpdateUserRoles(ctx context.Context, arg database.UpdateUserRo
 	return q.db.UpdateUserRoles(ctx, arg)
 }
 
+func (q *querier) UpdateUserSecret(ctx context.Context, arg database.UpdateUserSecretParams) (database.UserSecret, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, rbac.ResourceUser.WithID(arg.UserID).WithOwner(arg.UserID.String())); err != nil {
+		return database.UserSecret{}, err
+	}
+	return q.db.UpdateUserSecret(ctx, arg)
+}
+
+func (q *querier) UpdateUserStartOnConnect(ctx context.Context, arg database.UpdateUserStartOnConnectParams) (database.UserConfig, error) {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return database.UserConfig{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, u); err != nil {
+		return database.UserConfig{}, err
+	}
+	return q.db.UpdateUserStartOnConnect(ctx, arg)
+}
+
 func (q *querier) UpdateUserStatus(ctx context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
 	fetch := func(ctx context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
 		return q.db.GetUserByID(ctx, arg.ID)
@@ -4826,6 +6227,20 @@ func (q *querier) UpdateUserThemePreference(ctx context.Context, arg database.Up
 	return q.db.UpdateUserThemePreference(ctx, arg)
 }
 
+func (q *querier) UpdateUserWebAuthnCredentialSignCount(ctx context.Context, arg database.UpdateUserWebAuthnCredentialSignCountParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateUserWebAuthnCredentialSignCountParams) (database.UserWebAuthnCredential, error) {
+		return q.db.GetUserWebAuthnCredentialByID(ctx, arg.ID)
+	}
+	return fetchAndExec(q.log, q.auth, policy.ActionUpdatePersonal, fetch, q.db.UpdateUserWebAuthnCredentialSignCount)(ctx, arg)
+}
+
+func (q *querier) UpdateVariableSet(ctx context.Context, arg database.UpdateVariableSetParams) (database.VariableSet, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
+		return database.VariableSet{}, err
+	}
+	return q.db.UpdateVariableSet(ctx, arg)
+}
+
 func (q *querier) UpdateVolumeResourceMonitor(ctx context.Context, arg database.UpdateVolumeResourceMonitorParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceWorkspaceAgentResourceMonitor); err != nil {
 		return err
@@ -4845,6 +6260,13 @@ func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorksp
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspace)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceAdoptionAdopted(ctx context.Context, arg database.UpdateWorkspaceAdoptionAdoptedParams) (database.WorkspaceAdoption, error) {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceAdoptionAdoptedParams) (database.WorkspaceAdoption, error) {
+		return q.db.GetWorkspaceAdoptionByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspaceAdoptionAdopted)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -5067,6 +6489,25 @@ func (q *querier) UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.Cont
 	return q.db.UpdateWorkspacesDormantDeletingAtByTemplateID(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspacesOrganizationByTemplateID(ctx context.Context, arg database.UpdateWorkspacesOrganizationByTemplateIDParams) error {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return xerrors.Errorf("get template by id: %w", err)
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return err
+	}
+	// The workspaces belong to many users, so the actor must be able to
+	// manage every workspace in both organizations.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceWorkspace.InOrg(template.OrganizationID)); err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceWorkspace.InOrg(arg.OrganizationID)); err != nil {
+		return err
+	}
+	return q.db.UpdateWorkspacesOrganizationByTemplateID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspacesTTLByTemplateID(ctx context.Context, arg database.UpdateWorkspacesTTLByTemplateIDParams) error {
 	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
 	if err != nil {
@@ -5113,6 +6554,13 @@ func (q *querier) UpsertDefaultProxy(ctx context.Context, arg database.UpsertDef
 	return q.db.UpsertDefaultProxy(ctx, arg)
 }
 
+func (q *querier) UpsertExperimentFlags(ctx context.Context, value string) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
+		return err
+	}
+	return q.db.UpsertExperimentFlags(ctx, value)
+}
+
 func (q *querier) UpsertHealthSettings(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
 		return err
@@ -5120,6 +6568,20 @@ func (q *querier) UpsertHealthSettings(ctx context.Context, value string) error
 	return q.db.UpsertHealthSettings(ctx, value)
 }
 
+func (q *querier) UpsertInternalCA(ctx context.Context, value string) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertInternalCA(ctx, value)
+}
+
+func (q *querier) UpsertLDAPUser(ctx context.Context, arg database.UpsertLDAPUserParams) (database.LDAPUser, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return database.LDAPUser{}, err
+	}
+	return q.db.UpsertLDAPUser(ctx, arg)
+}
+
 func (q *querier) UpsertLastUpdateCheck(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -5127,6 +6589,15 @@ func (q *querier) UpsertLastUpdateCheck(ctx context.Context, value string) error
 	return q.db.UpsertLastUpdateCheck(ctx, value)
 }
 
+func (q *querier) UpsertLicenseSeatReservation(ctx context.Context, arg database.UpsertLicenseSeatReservationParams) (database.LicenseSeatReservation, error) {
+	// Licenses have no update action, so changing a reservation also requires
+	// create.
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceLicense); err != nil {
+		return database.LicenseSeatReservation{}, err
+	}
+	return q.db.UpsertLicenseSeatReservation(ctx, arg)
+}
+
 func (q *querier) UpsertLogoURL(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
 		return err
@@ -5134,6 +6605,13 @@ func (q *querier) UpsertLogoURL(ctx context.Context, value string) error {
 	return q.db.UpsertLogoURL(ctx, value)
 }
 
+func (q *querier) UpsertNetworkConnectionQuality(ctx context.Context, arg database.UpsertNetworkConnectionQualityParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertNetworkConnectionQuality(ctx, arg)
+}
+
 func (q *querier) UpsertNotificationReportGeneratorLog(ctx context.Context, arg database.UpsertNotificationReportGeneratorLogParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -5162,6 +6640,27 @@ func (q *querier) UpsertOAuthSigningKey(ctx context.Context, value string) error
 	return q.db.UpsertOAuthSigningKey(ctx, value)
 }
 
+func (q *querier) UpsertOrganizationAuditSettings(ctx context.Context, arg database.UpsertOrganizationAuditSettingsParams) (database.OrganizationAuditSetting, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceOrganization.WithID(arg.OrganizationID).InOrg(arg.OrganizationID)); err != nil {
+		return database.OrganizationAuditSetting{}, err
+	}
+	return q.db.UpsertOrganizationAuditSettings(ctx, arg)
+}
+
+func (q *querier) UpsertOrganizationMFASettings(ctx context.Context, arg database.UpsertOrganizationMFASettingsParams) (database.OrganizationMFASetting, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceOrganization.WithID(arg.OrganizationID).InOrg(arg.OrganizationID)); err != nil {
+		return database.OrganizationMFASetting{}, err
+	}
+	return q.db.UpsertOrganizationMFASettings(ctx, arg)
+}
+
+func (q *querier) UpsertOrganizationSettingOverrides(ctx context.Context, arg database.UpsertOrganizationSettingOverridesParams) (database.OrganizationSettingOverride, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceOrganization.WithID(arg.OrganizationID).InOrg(arg.OrganizationID)); err != nil {
+		return database.OrganizationSettingOverride{}, err
+	}
+	return q.db.UpsertOrganizationSettingOverrides(ctx, arg)
+}
+
 func (q *querier) UpsertPrebuildsSettings(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
 		return err
@@ -5180,6 +6679,17 @@ func (q *querier) UpsertProvisionerDaemon(ctx context.Context, arg database.Upse
 	return q.db.UpsertProvisionerDaemon(ctx, arg)
 }
 
+func (q *querier) UpsertReviewWorkspace(ctx context.Context, arg database.UpsertReviewWorkspaceParams) (database.ReviewWorkspace, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.ReviewWorkspace{}, xerrors.Errorf("get workspace by id: %w", err)
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return database.ReviewWorkspace{}, err
+	}
+	return q.db.UpsertReviewWorkspace(ctx, arg)
+}
+
 func (q *querier) UpsertRuntimeConfig(ctx context.Context, arg database.UpsertRuntimeConfigParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -5236,6 +6746,34 @@ func (q *querier) UpsertTelemetryItem(ctx context.Context, arg database.UpsertTe
 	return q.db.UpsertTelemetryItem(ctx, arg)
 }
 
+func (q *querier) UpsertTemplateAppRestriction(ctx context.Context, arg database.UpsertTemplateAppRestrictionParams) (database.TemplateAppRestriction, error) {
+	if err := q.authorizeTemplateAppRestriction(ctx, policy.ActionUpdate, arg.TemplateID); err != nil {
+		return database.TemplateAppRestriction{}, err
+	}
+	return q.db.UpsertTemplateAppRestriction(ctx, arg)
+}
+
+func (q *querier) UpsertTemplateAppSessionPolicy(ctx context.Context, arg database.UpsertTemplateAppSessionPolicyParams) (database.TemplateAppSessionPolicy, error) {
+	if err := q.authorizeTemplateAppSessionPolicy(ctx, policy.ActionUpdate, arg.TemplateID); err != nil {
+		return database.TemplateAppSessionPolicy{}, err
+	}
+	return q.db.UpsertTemplateAppSessionPolicy(ctx, arg)
+}
+
+func (q *querier) UpsertTemplateEgressPolicy(ctx context.Context, arg database.UpsertTemplateEgressPolicyParams) (database.TemplateEgressPolicy, error) {
+	if err := q.authorizeTemplateEgressPolicy(ctx, policy.ActionUpdate, arg.TemplateID); err != nil {
+		return database.TemplateEgressPolicy{}, err
+	}
+	return q.db.UpsertTemplateEgressPolicy(ctx, arg)
+}
+
+func (q *querier) UpsertTemplateParameterOptionSource(ctx context.Context, arg database.UpsertTemplateParameterOptionSourceParams) (database.TemplateParameterOptionSource, error) {
+	if err := q.authorizeTemplateParameterOptionSource(ctx, policy.ActionUpdate, arg.TemplateID); err != nil {
+		return database.TemplateParameterOptionSource{}, err
+	}
+	return q.db.UpsertTemplateParameterOptionSource(ctx, arg)
+}
+
 func (q *querier) UpsertTemplateUsageStats(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -5243,6 +6781,34 @@ func (q *querier) UpsertTemplateUsageStats(ctx context.Context) error {
 	return q.db.UpsertTemplateUsageStats(ctx)
 }
 
+func (q *querier) UpsertTemplateVersionActivationRequest(ctx context.Context, arg database.UpsertTemplateVersionActivationRequestParams) (database.TemplateVersionActivationRequest, error) {
+	if err := q.authorizeTemplateVersionActivation(ctx, policy.ActionUpdate, arg.TemplateVersionID); err != nil {
+		return database.TemplateVersionActivationRequest{}, err
+	}
+	return q.db.UpsertTemplateVersionActivationRequest(ctx, arg)
+}
+
+func (q *querier) UpsertTemplateVersionActivationReview(ctx context.Context, arg database.UpsertTemplateVersionActivationReviewParams) (database.TemplateVersionActivationReview, error) {
+	if err := q.authorizeTemplateVersionActivation(ctx, policy.ActionUpdate, arg.TemplateVersionID); err != nil {
+		return database.TemplateVersionActivationReview{}, err
+	}
+	return q.db.UpsertTemplateVersionActivationReview(ctx, arg)
+}
+
+func (q *querier) UpsertTemplateVersionRollout(ctx context.Context, arg database.UpsertTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	if err := q.authorizeTemplateVersionRollout(ctx, policy.ActionUpdate, arg.TemplateID); err != nil {
+		return database.TemplateVersionRollout{}, err
+	}
+	return q.db.UpsertTemplateVersionRollout(ctx, arg)
+}
+
+func (q *querier) UpsertUserCLIVersion(ctx context.Context, arg database.UpsertUserCLIVersionParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertUserCLIVersion(ctx, arg)
+}
+
 func (q *querier) UpsertWebpushVAPIDKeys(ctx context.Context, arg database.UpsertWebpushVAPIDKeysParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
 		return err
@@ -5264,6 +6830,14 @@ func (q *querier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg databas
 	return q.db.UpsertWorkspaceAgentPortShare(ctx, arg)
 }
 
+func (q *querier) UpsertWorkspaceAgentVolumeUsage(ctx context.Context, arg database.UpsertWorkspaceAgentVolumeUsageParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceWorkspaceAgentResourceMonitor); err != nil {
+		return err
+	}
+
+	return q.db.UpsertWorkspaceAgentVolumeUsage(ctx, arg)
+}
+
 func (q *querier) UpsertWorkspaceApp(ctx context.Context, arg database.UpsertWorkspaceAppParams) (database.WorkspaceApp, error) {
 	// NOTE(DanielleMaywood):
 	// It is possible for there to exist an agent without a workspace.
@@ -5288,6 +6862,25 @@ func (q *querier) UpsertWorkspaceAppAuditSession(ctx context.Context, arg databa
 	return q.db.UpsertWorkspaceAppAuditSession(ctx, arg)
 }
 
+func (q *querier) UpsertWorkspaceSchedulePause(ctx context.Context, arg database.UpsertWorkspaceSchedulePauseParams) (database.WorkspaceSchedulePause, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceSchedulePause{}, err
+	}
+
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceSchedulePause{}, err
+	}
+	return q.db.UpsertWorkspaceSchedulePause(ctx, arg)
+}
+
+func (q *querier) UseUserMFARecoveryCode(ctx context.Context, arg database.UseUserMFARecoveryCodeParams) (database.UserMFARecoveryCode, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdatePersonal, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return database.UserMFARecoveryCode{}, err
+	}
+	return q.db.UseUserMFARecoveryCode(ctx, arg)
+}
+
 func (q *querier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, _ rbac.PreparedAuthorized) ([]database.Template, error) {
 	// TODO Delete this function, all GetTemplates should be authorized. For now just call getTemplates on the authz querier.
 	return q.GetTemplatesWithFilter(ctx, arg)
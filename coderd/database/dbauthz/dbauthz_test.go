@@ -249,6 +249,15 @@ func (s *MethodTestSuite) TestAPIKey() {
 			Asserts(keyA, policy.ActionRead, keyB, policy.ActionRead).
 			Returns(slice.New(keyA, keyB))
 	}))
+	s.Run("GetAPIKeySessionsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		session, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID, LoginType: database.LoginTypePassword})
+		_, _ = dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID, LoginType: database.LoginTypeToken, TokenName: "token"})
+
+		check.Args(database.GetAPIKeySessionsByUserIDParams{UserID: u.ID, Now: dbtime.Now()}).
+			Asserts(session, policy.ActionRead).
+			Returns(slice.New(session))
+	}))
 	s.Run("GetAPIKeysLastUsedAfter", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		a, _ := dbgen.APIKey(s.T(), db, database.APIKey{LastUsed: time.Now().Add(time.Hour)})
@@ -301,6 +310,168 @@ func (s *MethodTestSuite) TestAPIKey() {
 			rbac.ResourceUserObject(a.UserID), policy.ActionReadPersonal,
 			rbac.ResourceUserObject(b.UserID), policy.ActionReadPersonal)
 	}))
+	s.Run("InsertUserSecret", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserSecretParams{
+			ID:        uuid.New(),
+			UserID:    u.ID,
+			Name:      "npm-token",
+			Value:     "secret",
+			EnvName:   "NPM_TOKEN",
+			CreatedAt: dbtime.Now(),
+			UpdatedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal)
+	}))
+	s.Run("GetUserSecretsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		secret := dbgen.UserSecret(s.T(), db, database.UserSecret{UserID: u.ID})
+		check.Args(u.ID).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionReadPersonal).Returns([]database.UserSecret{secret})
+	}))
+	s.Run("GetUserSecretByUserIDAndName", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		secret := dbgen.UserSecret(s.T(), db, database.UserSecret{UserID: u.ID})
+		check.Args(database.GetUserSecretByUserIDAndNameParams{
+			UserID: u.ID,
+			Name:   secret.Name,
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionReadPersonal).Returns(secret)
+	}))
+	s.Run("UpdateUserSecret", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		secret := dbgen.UserSecret(s.T(), db, database.UserSecret{UserID: u.ID})
+		check.Args(database.UpdateUserSecretParams{
+			UserID:    u.ID,
+			Name:      secret.Name,
+			Value:     "rotated",
+			UpdatedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal)
+	}))
+	s.Run("DeleteUserSecret", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		secret := dbgen.UserSecret(s.T(), db, database.UserSecret{UserID: u.ID})
+		check.Args(database.DeleteUserSecretParams{
+			UserID: u.ID,
+			Name:   secret.Name,
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal).Returns()
+	}))
+}
+
+func (s *MethodTestSuite) TestWebAuthn() {
+	s.Run("GetUserWebAuthnCredentialsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		credential := dbgen.UserWebAuthnCredential(s.T(), db, database.UserWebAuthnCredential{UserID: u.ID})
+		check.Args(u.ID).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionReadPersonal).Returns([]database.UserWebAuthnCredential{credential})
+	}))
+	s.Run("GetUserWebAuthnCredentialByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		credential := dbgen.UserWebAuthnCredential(s.T(), db, database.UserWebAuthnCredential{UserID: u.ID})
+		check.Args(credential.ID).Asserts(credential, policy.ActionReadPersonal).Returns(credential)
+	}))
+	s.Run("GetUserWebAuthnCredentialByCredentialID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		credential := dbgen.UserWebAuthnCredential(s.T(), db, database.UserWebAuthnCredential{UserID: u.ID})
+		check.Args(credential.CredentialID).Asserts(credential, policy.ActionReadPersonal).Returns(credential)
+	}))
+	s.Run("InsertUserWebAuthnCredential", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserWebAuthnCredentialParams{
+			ID:           uuid.New(),
+			UserID:       u.ID,
+			Name:         "YubiKey",
+			CredentialID: []byte("credential"),
+			PublicKey:    []byte("public-key"),
+			CreatedAt:    dbtime.Now(),
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal)
+	}))
+	s.Run("UpdateUserWebAuthnCredentialSignCount", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		credential := dbgen.UserWebAuthnCredential(s.T(), db, database.UserWebAuthnCredential{UserID: u.ID})
+		check.Args(database.UpdateUserWebAuthnCredentialSignCountParams{
+			ID:         credential.ID,
+			SignCount:  1,
+			LastUsedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(credential, policy.ActionUpdatePersonal).Returns()
+	}))
+	s.Run("DeleteUserWebAuthnCredential", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		credential := dbgen.UserWebAuthnCredential(s.T(), db, database.UserWebAuthnCredential{UserID: u.ID})
+		check.Args(credential.ID).Asserts(credential, policy.ActionUpdatePersonal).Returns()
+	}))
+	s.Run("InsertWebAuthnChallenge", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertWebAuthnChallengeParams{
+			ID:        uuid.New(),
+			UserID:    u.ID,
+			Purpose:   database.WebAuthnChallengePurposeLogin,
+			Challenge: []byte("challenge"),
+			CreatedAt: dbtime.Now(),
+			ExpiresAt: dbtime.Now().Add(time.Minute),
+		}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
+	s.Run("DeleteWebAuthnChallengeByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		challenge := dbgen.WebAuthnChallenge(s.T(), db, database.WebAuthnChallenge{UserID: u.ID})
+		check.Args(challenge.ID).Asserts(rbac.ResourceSystem, policy.ActionDelete).Returns(challenge)
+	}))
+	s.Run("DeleteExpiredWebAuthnChallenges", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionDelete).Returns()
+	}))
+	s.Run("DeleteExpiredWorkspacePortShareLinks", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionDelete).Returns()
+	}))
+	s.Run("AddWorkspacePortShareLinkBytesTransferred", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.AddWorkspacePortShareLinkBytesTransferredParams{
+			ID:    uuid.New(),
+			Bytes: 1024,
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate).Returns()
+	}))
+	s.Run("InsertUserMFARecoveryCode", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserMFARecoveryCodeParams{
+			UserID:     u.ID,
+			HashedCode: []byte("hash"),
+			CreatedAt:  dbtime.Now(),
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal).Returns()
+	}))
+	s.Run("UseUserMFARecoveryCode", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		err := db.InsertUserMFARecoveryCode(context.Background(), database.InsertUserMFARecoveryCodeParams{
+			UserID:     u.ID,
+			HashedCode: []byte("hash"),
+			CreatedAt:  dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UseUserMFARecoveryCodeParams{
+			UserID:     u.ID,
+			HashedCode: []byte("hash"),
+			UsedAt:     sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal)
+	}))
+	s.Run("DeleteUserMFARecoveryCodes", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionUpdatePersonal).Returns()
+	}))
+	s.Run("GetUserWebAuthnRequired", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceUserObject(u.ID), policy.ActionReadPersonal).Returns(false)
+	}))
+	s.Run("GetOrganizationMFASettings", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		_, err := db.UpsertOrganizationMFASettings(context.Background(), database.UpsertOrganizationMFASettingsParams{
+			OrganizationID:  o.ID,
+			RequireWebAuthn: true,
+			UpdatedAt:       dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(o.ID).Asserts(rbac.ResourceOrganization.WithID(o.ID).InOrg(o.ID), policy.ActionRead)
+	}))
+	s.Run("UpsertOrganizationMFASettings", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.UpsertOrganizationMFASettingsParams{
+			OrganizationID:  o.ID,
+			RequireWebAuthn: true,
+			UpdatedAt:       dbtime.Now(),
+		}).Asserts(rbac.ResourceOrganization.WithID(o.ID).InOrg(o.ID), policy.ActionUpdate)
+	}))
 }
 
 func (s *MethodTestSuite) TestAuditLogs() {
@@ -319,6 +490,19 @@ func (s *MethodTestSuite) TestAuditLogs() {
 			LimitOpt: 10,
 		}).Asserts(rbac.ResourceAuditLog, policy.ActionRead).WithNotAuthorized("nil")
 	}))
+	s.Run("GetActivityEvents", s.Subtest(func(db database.Store, check *expects) {
+		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		check.Args(database.GetActivityEventsParams{
+			LimitOpt: 10,
+		}).Asserts(rbac.ResourceAuditLog, policy.ActionRead)
+	}))
+	s.Run("OrgScoped/GetActivityEvents", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.GetActivityEventsParams{
+			OrganizationID: o.ID,
+			LimitOpt:       10,
+		}).Asserts(rbac.ResourceAuditLog.InOrg(o.ID), policy.ActionRead)
+	}))
 	s.Run("GetAuthorizedAuditLogsOffset", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
@@ -337,9 +521,81 @@ func (s *MethodTestSuite) TestAuditLogs() {
 		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
 		check.Args(database.CountAuditLogsParams{}, emptyPreparedAuthorized{}).Asserts(rbac.ResourceAuditLog, policy.ActionRead)
 	}))
+	s.Run("DeleteOldAuditLogs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.DeleteOldAuditLogsParams{Now: dbtime.Now(), LimitCount: 100}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("GetOrganizationAuditSettings", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		_, err := db.UpsertOrganizationAuditSettings(context.Background(), database.UpsertOrganizationAuditSettingsParams{
+			OrganizationID: o.ID,
+			RetentionDays:  30,
+			UpdatedAt:      dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(o.ID).Asserts(rbac.ResourceAuditLog.InOrg(o.ID), policy.ActionRead)
+	}))
+	s.Run("UpsertOrganizationAuditSettings", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.UpsertOrganizationAuditSettingsParams{
+			OrganizationID: o.ID,
+			RetentionDays:  30,
+			UpdatedAt:      dbtime.Now(),
+		}).Asserts(o, policy.ActionUpdate)
+	}))
+	s.Run("GetAuditLogLegalHoldsByOrganizationID", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(o.ID).Asserts(rbac.ResourceAuditLog.InOrg(o.ID), policy.ActionRead)
+	}))
+	s.Run("InsertAuditLogLegalHold", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertAuditLogLegalHoldParams{
+			ID:             uuid.New(),
+			OrganizationID: o.ID,
+			UserID:         uuid.NullUUID{UUID: u.ID, Valid: true},
+			Reason:         "litigation",
+			CreatedBy:      u.ID,
+			CreatedAt:      dbtime.Now(),
+		}).Asserts(o, policy.ActionUpdate)
+	}))
+	s.Run("GetAuditLogLegalHoldByID", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{})
+		hold, err := db.InsertAuditLogLegalHold(context.Background(), database.InsertAuditLogLegalHoldParams{
+			ID:             uuid.New(),
+			OrganizationID: o.ID,
+			UserID:         uuid.NullUUID{UUID: u.ID, Valid: true},
+			Reason:         "litigation",
+			CreatedBy:      u.ID,
+			CreatedAt:      dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(hold.ID).Asserts(rbac.ResourceAuditLog.InOrg(o.ID), policy.ActionRead).Returns(hold)
+	}))
+	s.Run("DeleteAuditLogLegalHoldByID", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{})
+		hold, err := db.InsertAuditLogLegalHold(context.Background(), database.InsertAuditLogLegalHoldParams{
+			ID:             uuid.New(),
+			OrganizationID: o.ID,
+			UserID:         uuid.NullUUID{UUID: u.ID, Valid: true},
+			Reason:         "litigation",
+			CreatedBy:      u.ID,
+			CreatedAt:      dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(hold.ID).Asserts(o, policy.ActionUpdate).Returns()
+	}))
 }
 
 func (s *MethodTestSuite) TestFile() {
+	s.Run("CountExternalFilesByHash", s.Subtest(func(db database.Store, check *expects) {
+		f := dbgen.File(s.T(), db, database.File{External: true})
+		check.Args(f.Hash).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(int64(1))
+	}))
+	s.Run("DeleteUnreferencedExternalFiles", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
 	s.Run("GetFileByHashAndCreator", s.Subtest(func(db database.Store, check *expects) {
 		f := dbgen.File(s.T(), db, database.File{})
 		check.Args(database.GetFileByHashAndCreatorParams{
@@ -462,6 +718,30 @@ func (s *MethodTestSuite) TestGroup() {
 			GroupID: g.ID,
 		}).Asserts(g, policy.ActionUpdate).Returns()
 	}))
+	s.Run("InsertGroupQuotaAllowances", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		g := dbgen.Group(s.T(), db, database.Group{})
+		check.Args(database.InsertGroupQuotaAllowancesParams{
+			GroupID:    g.ID,
+			Dimensions: []string{"cpu"},
+			Allowances: []int64{8},
+		}).Asserts(g, policy.ActionUpdate).Returns()
+	}))
+	s.Run("DeleteGroupQuotaAllowances", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		g := dbgen.Group(s.T(), db, database.Group{})
+		check.Args(g.ID).Asserts(g, policy.ActionUpdate).Returns()
+	}))
+	s.Run("GetGroupQuotaAllowances", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		g := dbgen.Group(s.T(), db, database.Group{})
+		check.Args(g.ID).Asserts(g, policy.ActionRead).Returns([]database.GroupQuotaAllowance{})
+	}))
+	s.Run("GetQuotaDimensionsConsumedForGroup", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		g := dbgen.Group(s.T(), db, database.Group{})
+		check.Args(g.ID).Asserts(g, policy.ActionRead).Returns([]database.GetQuotaDimensionsConsumedForGroupRow{})
+	}))
 	s.Run("InsertUserGroupsByName", s.Subtest(func(db database.Store, check *expects) {
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		u1 := dbgen.User(s.T(), db, database.User{})
@@ -517,6 +797,46 @@ func (s *MethodTestSuite) TestGroup() {
 			ID: g.ID,
 		}).Asserts(g, policy.ActionUpdate)
 	}))
+	s.Run("GetSCIMGroupMembers", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(o.ID).Asserts(rbac.ResourceGroupMember.InOrg(o.ID), policy.ActionRead)
+	}))
+	s.Run("GetSCIMNestedGroups", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(o.ID).Asserts(rbac.ResourceGroup.InOrg(o.ID), policy.ActionRead)
+	}))
+	s.Run("InsertSCIMGroupMembers", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		g := dbgen.Group(s.T(), db, database.Group{})
+		check.Args(database.InsertSCIMGroupMembersParams{
+			GroupID: g.ID,
+			UserIds: []uuid.UUID{uuid.New()},
+		}).Asserts(g, policy.ActionUpdate).Returns()
+	}))
+	s.Run("DeleteSCIMGroupMembers", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		g := dbgen.Group(s.T(), db, database.Group{})
+		check.Args(database.DeleteSCIMGroupMembersParams{
+			GroupID: g.ID,
+			UserIds: []uuid.UUID{uuid.New()},
+		}).Asserts(g, policy.ActionUpdate).Returns()
+	}))
+	s.Run("InsertSCIMNestedGroups", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		g := dbgen.Group(s.T(), db, database.Group{})
+		check.Args(database.InsertSCIMNestedGroupsParams{
+			GroupID:        g.ID,
+			MemberGroupIds: []uuid.UUID{uuid.New()},
+		}).Asserts(g, policy.ActionUpdate).Returns()
+	}))
+	s.Run("DeleteSCIMNestedGroups", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		g := dbgen.Group(s.T(), db, database.Group{})
+		check.Args(database.DeleteSCIMNestedGroupsParams{
+			GroupID:        g.ID,
+			MemberGroupIds: []uuid.UUID{uuid.New()},
+		}).Asserts(g, policy.ActionUpdate).Returns()
+	}))
 }
 
 func (s *MethodTestSuite) TestProvisionerJob() {
@@ -761,6 +1081,35 @@ func (s *MethodTestSuite) TestLicense() {
 	s.Run("UpsertAnnouncementBanners", s.Subtest(func(db database.Store, check *expects) {
 		check.Args("value").Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate)
 	}))
+	s.Run("GetVariableSets", s.Subtest(func(db database.Store, check *expects) {
+		set := dbgen.VariableSet(s.T(), db, database.VariableSet{})
+		check.Args().Asserts(rbac.ResourceDeploymentConfig, policy.ActionRead).Returns([]database.VariableSet{set})
+	}))
+	s.Run("GetVariableSetByName", s.Subtest(func(db database.Store, check *expects) {
+		set := dbgen.VariableSet(s.T(), db, database.VariableSet{})
+		check.Args(set.Name).Asserts(rbac.ResourceDeploymentConfig, policy.ActionRead).Returns(set)
+	}))
+	s.Run("InsertVariableSet", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertVariableSetParams{
+			ID:        uuid.New(),
+			Name:      "aws-network",
+			Variables: "[]",
+			CreatedAt: dbtime.Now(),
+			UpdatedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate)
+	}))
+	s.Run("UpdateVariableSet", s.Subtest(func(db database.Store, check *expects) {
+		set := dbgen.VariableSet(s.T(), db, database.VariableSet{})
+		check.Args(database.UpdateVariableSetParams{
+			ID:        set.ID,
+			Variables: `[{"name":"vpc_id","value":"vpc-1","secret":false}]`,
+			UpdatedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate)
+	}))
+	s.Run("DeleteVariableSet", s.Subtest(func(db database.Store, check *expects) {
+		set := dbgen.VariableSet(s.T(), db, database.VariableSet{})
+		check.Args(set.ID).Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate).Returns()
+	}))
 	s.Run("GetLicenseByID", s.Subtest(func(db database.Store, check *expects) {
 		l, err := db.InsertLicense(context.Background(), database.InsertLicenseParams{
 			UUID: uuid.New(),
@@ -775,6 +1124,19 @@ func (s *MethodTestSuite) TestLicense() {
 		require.NoError(s.T(), err)
 		check.Args(l.ID).Asserts(l, policy.ActionDelete)
 	}))
+	s.Run("GetLicenseSeatReservations", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceLicense, policy.ActionRead)
+	}))
+	s.Run("UpsertLicenseSeatReservation", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		check.Args(database.UpsertLicenseSeatReservationParams{
+			GroupID: uuid.New(),
+			Seats:   5,
+		}).Asserts(rbac.ResourceLicense, policy.ActionCreate)
+	}))
+	s.Run("DeleteLicenseSeatReservation", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(uuid.New()).Asserts(rbac.ResourceLicense, policy.ActionDelete).Returns()
+	}))
 	s.Run("GetDeploymentID", s.Subtest(func(db database.Store, check *expects) {
 		db.InsertDeploymentID(context.Background(), "value")
 		check.Args().Asserts().Returns("value")
@@ -1120,6 +1482,26 @@ func (s *MethodTestSuite) TestOrganization() {
 				rbac.ResourceAssignOrgRole.InOrg(o.ID), policy.ActionUnassign, // org-admin
 			).Returns(out)
 	}))
+	s.Run("GetOrganizationSettingOverrides", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		_, err := db.UpsertOrganizationSettingOverrides(context.Background(), database.UpsertOrganizationSettingOverridesParams{
+			OrganizationID:                  o.ID,
+			DefaultTTL:                      sql.NullInt64{Int64: int64(8 * time.Hour), Valid: true},
+			DisabledNotificationTemplateIDs: []uuid.UUID{},
+			UpdatedAt:                       dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(o.ID).Asserts(rbac.ResourceOrganization.WithID(o.ID).InOrg(o.ID), policy.ActionRead)
+	}))
+	s.Run("UpsertOrganizationSettingOverrides", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.UpsertOrganizationSettingOverridesParams{
+			OrganizationID:                  o.ID,
+			AllowedExternalAuthProviders:    []string{"github"},
+			DisabledNotificationTemplateIDs: []uuid.UUID{},
+			UpdatedAt:                       dbtime.Now(),
+		}).Asserts(rbac.ResourceOrganization.WithID(o.ID).InOrg(o.ID), policy.ActionUpdate)
+	}))
 }
 
 func (s *MethodTestSuite) TestWorkspaceProxy() {
@@ -1160,6 +1542,31 @@ func (s *MethodTestSuite) TestWorkspaceProxy() {
 		p2, _ := dbgen.WorkspaceProxy(s.T(), db, database.WorkspaceProxy{})
 		check.Args().Asserts(p1, policy.ActionRead, p2, policy.ActionRead).Returns(slice.New(p1, p2))
 	}))
+	s.Run("RotateWorkspaceProxyToken", s.Subtest(func(db database.Store, check *expects) {
+		p, _ := dbgen.WorkspaceProxy(s.T(), db, database.WorkspaceProxy{})
+		check.Args(database.RotateWorkspaceProxyTokenParams{
+			ProxyID:                   p.ID,
+			NewTokenHashedSecret:      []byte("new"),
+			RotatedAt:                 dbtime.Now(),
+			PreviousTokenHashedSecret: p.TokenHashedSecret,
+			PreviousTokenExpiresAt:    dbtime.Now().Add(time.Hour),
+		}).Asserts(p, policy.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceProxyTokenRotationByProxyID", s.Subtest(func(db database.Store, check *expects) {
+		p, _ := dbgen.WorkspaceProxy(s.T(), db, database.WorkspaceProxy{})
+		rotation, err := db.RotateWorkspaceProxyToken(context.Background(), database.RotateWorkspaceProxyTokenParams{
+			ProxyID:                   p.ID,
+			NewTokenHashedSecret:      []byte("new"),
+			RotatedAt:                 dbtime.Now(),
+			PreviousTokenHashedSecret: p.TokenHashedSecret,
+			PreviousTokenExpiresAt:    dbtime.Now().Add(time.Hour),
+		})
+		require.NoError(s.T(), err)
+		check.Args(p.ID).Asserts(p, policy.ActionRead).Returns(rotation)
+	}))
+	s.Run("GetWorkspaceProxyTokenRotations", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceWorkspaceProxy, policy.ActionRead)
+	}))
 }
 
 func (s *MethodTestSuite) TestTemplate() {
@@ -1276,85 +1683,513 @@ func (s *MethodTestSuite) TestTemplate() {
 		})
 		check.Args(tv.ID).Asserts(t1, policy.ActionRead).Returns([]database.TemplateVersionWorkspaceTag{wt1})
 	}))
-	s.Run("GetTemplateGroupRoles", s.Subtest(func(db database.Store, check *expects) {
-		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
-		t1 := dbgen.Template(s.T(), db, database.Template{})
-		check.Args(t1.ID).Asserts(t1, policy.ActionUpdate)
-	}))
-	s.Run("GetTemplateUserRoles", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("UpsertTemplateVersionActivationRequest", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		t1 := dbgen.Template(s.T(), db, database.Template{})
-		check.Args(t1.ID).Asserts(t1, policy.ActionUpdate)
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		check.Args(database.UpsertTemplateVersionActivationRequestParams{
+			TemplateVersionID: tv.ID,
+			TemplateID:        t1.ID,
+			RequestedBy:       uuid.New(),
+			ApprovalsRequired: 1,
+			CreatedAt:         dbtime.Now(),
+		}).Asserts(t1, policy.ActionUpdate)
 	}))
-	s.Run("GetTemplateVersionByID", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetTemplateVersionActivationRequestByTemplateVersionID", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		u := dbgen.User(s.T(), db, database.User{})
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
 			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
 		})
-		check.Args(tv.ID).Asserts(t1, policy.ActionRead).Returns(tv)
+		_, err := db.UpsertTemplateVersionActivationRequest(context.Background(), database.UpsertTemplateVersionActivationRequestParams{
+			TemplateVersionID: tv.ID,
+			TemplateID:        t1.ID,
+			RequestedBy:       u.ID,
+			ApprovalsRequired: 1,
+			CreatedAt:         dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(tv.ID).Asserts(t1, policy.ActionRead)
 	}))
-	s.Run("GetTemplateVersionsByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("UpdateTemplateVersionActivationRequestStatus", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		t1 := dbgen.Template(s.T(), db, database.Template{})
-		a := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
 			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
 		})
-		b := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
-			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		_, err := db.UpsertTemplateVersionActivationRequest(context.Background(), database.UpsertTemplateVersionActivationRequestParams{
+			TemplateVersionID: tv.ID,
+			TemplateID:        t1.ID,
+			RequestedBy:       uuid.New(),
+			ApprovalsRequired: 1,
+			CreatedAt:         dbtime.Now(),
 		})
-		check.Args(database.GetTemplateVersionsByTemplateIDParams{
-			TemplateID: t1.ID,
-		}).Asserts(t1, policy.ActionRead).
-			Returns(slice.New(a, b))
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateTemplateVersionActivationRequestStatusParams{
+			TemplateVersionID: tv.ID,
+			Status:            database.TemplateVersionActivationRequestStatusApproved,
+			UpdatedAt:         dbtime.Now(),
+		}).Asserts(t1, policy.ActionUpdate)
 	}))
-	s.Run("GetTemplateVersionsCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("CancelPendingTemplateVersionActivationRequests", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
-		now := time.Now()
 		t1 := dbgen.Template(s.T(), db, database.Template{})
-		_ = dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+		check.Args(database.CancelPendingTemplateVersionActivationRequestsParams{
+			TemplateID:      t1.ID,
+			ActiveVersionID: uuid.New(),
+			UpdatedAt:       dbtime.Now(),
+		}).Asserts(t1, policy.ActionUpdate).Returns()
+	}))
+	s.Run("UpsertTemplateVersionActivationReview", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
 			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
-			CreatedAt:  now.Add(-time.Hour),
 		})
-		_ = dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+		check.Args(database.UpsertTemplateVersionActivationReviewParams{
+			TemplateVersionID: tv.ID,
+			ReviewerID:        uuid.New(),
+			Approved:          true,
+			CreatedAt:         dbtime.Now(),
+		}).Asserts(t1, policy.ActionUpdate)
+	}))
+	s.Run("GetTemplateVersionActivationReviews", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
 			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
-			CreatedAt:  now.Add(-2 * time.Hour),
 		})
-		check.Args(now.Add(-time.Hour)).Asserts(rbac.ResourceTemplate.All(), policy.ActionRead)
+		check.Args(tv.ID).Asserts(t1, policy.ActionRead)
 	}))
-	s.Run("GetTemplatesWithFilter", s.Subtest(func(db database.Store, check *expects) {
-		o := dbgen.Organization(s.T(), db, database.Organization{})
-		u := dbgen.User(s.T(), db, database.User{})
-		a := dbgen.Template(s.T(), db, database.Template{
-			OrganizationID: o.ID,
-			CreatedBy:      u.ID,
+	s.Run("DeleteTemplateVersionActivationReviews", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
 		})
-		// No asserts because SQLFilter.
-		check.Args(database.GetTemplatesWithFilterParams{}).
-			Asserts().Returns(slice.New(a))
+		check.Args(tv.ID).Asserts(t1, policy.ActionUpdate).Returns()
 	}))
-	s.Run("GetAuthorizedTemplates", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetTemplateActivationForUpdate", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
-		a := dbgen.Template(s.T(), db, database.Template{})
-		// No asserts because SQLFilter.
-		check.Args(database.GetTemplatesWithFilterParams{}, emptyPreparedAuthorized{}).
-			Asserts().
-			Returns(slice.New(a))
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(t1.ID).Asserts(t1, policy.ActionUpdate)
 	}))
-	s.Run("InsertTemplate", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetTemplateActivationReviewers", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
-		orgID := uuid.New()
-		check.Args(database.InsertTemplateParams{
-			Provisioner:         "echo",
-			OrganizationID:      orgID,
-			MaxPortSharingLevel: database.AppSharingLevelOwner,
-		}).Asserts(rbac.ResourceTemplate.InOrg(orgID), policy.ActionCreate)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(t1.ID).Asserts(t1, policy.ActionRead)
 	}))
-	s.Run("InsertTemplateVersion", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("UpdateTemplateActivationReviewers", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		t1 := dbgen.Template(s.T(), db, database.Template{})
-		check.Args(database.InsertTemplateVersionParams{
-			TemplateID:     uuid.NullUUID{UUID: t1.ID, Valid: true},
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateTemplateActivationReviewersParams{
+			TemplateID: t1.ID,
+			UserIds:    []uuid.UUID{u.ID},
+		}).Asserts(t1, policy.ActionUpdate).Returns()
+	}))
+	s.Run("UpsertTemplateVersionRollout", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpsertTemplateVersionRolloutParams{
+			TemplateID:              t1.ID,
+			TemplateVersionID:       uuid.New(),
+			CreatedBy:               uuid.New(),
+			Percentage:              10,
+			GroupIds:                []uuid.UUID{},
+			FailureThresholdPercent: 20,
+			MinimumBuilds:           5,
+			Status:                  database.TemplateVersionRolloutStatusInProgress,
+			CreatedAt:               dbtime.Now(),
+		}).Asserts(t1, policy.ActionUpdate)
+	}))
+	s.Run("GetTemplateVersionRolloutByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		_, err := db.UpsertTemplateVersionRollout(context.Background(), database.UpsertTemplateVersionRolloutParams{
+			TemplateID:              t1.ID,
+			TemplateVersionID:       uuid.New(),
+			CreatedBy:               uuid.New(),
+			Percentage:              10,
+			GroupIds:                []uuid.UUID{},
+			FailureThresholdPercent: 20,
+			MinimumBuilds:           5,
+			Status:                  database.TemplateVersionRolloutStatusInProgress,
+			CreatedAt:               dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(t1.ID).Asserts(t1, policy.ActionRead)
+	}))
+	s.Run("HaltTemplateVersionRollout", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		rollout, err := db.UpsertTemplateVersionRollout(context.Background(), database.UpsertTemplateVersionRolloutParams{
+			TemplateID:              t1.ID,
+			TemplateVersionID:       uuid.New(),
+			CreatedBy:               uuid.New(),
+			Percentage:              10,
+			GroupIds:                []uuid.UUID{},
+			FailureThresholdPercent: 20,
+			MinimumBuilds:           5,
+			Status:                  database.TemplateVersionRolloutStatusInProgress,
+			CreatedAt:               dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.HaltTemplateVersionRolloutParams{
+			TemplateID:        t1.ID,
+			TemplateVersionID: rollout.TemplateVersionID,
+			UpdatedAt:         dbtime.Now(),
+		}).Asserts(t1, policy.ActionUpdate)
+	}))
+	s.Run("StartTemplateVersionRollout", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		rollout, err := db.UpsertTemplateVersionRollout(context.Background(), database.UpsertTemplateVersionRolloutParams{
+			TemplateID:              t1.ID,
+			TemplateVersionID:       uuid.New(),
+			CreatedBy:               uuid.New(),
+			Percentage:              10,
+			GroupIds:                []uuid.UUID{},
+			FailureThresholdPercent: 20,
+			MinimumBuilds:           5,
+			Status:                  database.TemplateVersionRolloutStatusPendingApproval,
+			CreatedAt:               dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.StartTemplateVersionRolloutParams{
+			TemplateID:        t1.ID,
+			TemplateVersionID: rollout.TemplateVersionID,
+			UpdatedAt:         dbtime.Now(),
+		}).Asserts(t1, policy.ActionUpdate)
+	}))
+	s.Run("FinishTemplateVersionRollout", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.FinishTemplateVersionRolloutParams{
+			TemplateID:      t1.ID,
+			ActiveVersionID: uuid.New(),
+			UpdatedAt:       dbtime.Now(),
+		}).Asserts(t1, policy.ActionUpdate).Returns()
+	}))
+	s.Run("GetTemplateVersionRolloutBuildStats", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(t1.ID).Asserts(t1, policy.ActionRead)
+	}))
+	s.Run("UpsertTemplateParameterOptionSource", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpsertTemplateParameterOptionSourceParams{
+			TemplateID:    t1.ID,
+			ParameterName: "gpu_pool",
+			Url:           "https://inventory.example.com/gpu-pools",
+			CacheTTL:      int64(time.Minute),
+			UpdatedAt:     dbtime.Now(),
+		}).Asserts(t1, policy.ActionUpdate)
+	}))
+	s.Run("GetTemplateParameterOptionSources", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(t1.ID).Asserts(t1, policy.ActionRead)
+	}))
+	s.Run("GetTemplateParameterOptionSource", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		_, err := db.UpsertTemplateParameterOptionSource(context.Background(), database.UpsertTemplateParameterOptionSourceParams{
+			TemplateID:    t1.ID,
+			ParameterName: "gpu_pool",
+			Url:           "https://inventory.example.com/gpu-pools",
+			CacheTTL:      int64(time.Minute),
+			UpdatedAt:     dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.GetTemplateParameterOptionSourceParams{
+			TemplateID:    t1.ID,
+			ParameterName: "gpu_pool",
+		}).Asserts(t1, policy.ActionRead)
+	}))
+	s.Run("DeleteTemplateParameterOptionSource", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.DeleteTemplateParameterOptionSourceParams{
+			TemplateID:    t1.ID,
+			ParameterName: "gpu_pool",
+		}).Asserts(t1, policy.ActionUpdate).Returns()
+	}))
+	s.Run("UpsertTemplateAppRestriction", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpsertTemplateAppRestrictionParams{
+			TemplateID: t1.ID,
+			AppSlug:    "debug",
+			GroupIds:   []uuid.UUID{},
+			Roles:      []string{"owner"},
+			UpdatedAt:  dbtime.Now(),
+		}).Asserts(t1, policy.ActionUpdate)
+	}))
+	s.Run("GetTemplateAppRestrictions", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(t1.ID).Asserts(t1, policy.ActionRead)
+	}))
+	s.Run("DeleteTemplateAppRestriction", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.DeleteTemplateAppRestrictionParams{
+			TemplateID: t1.ID,
+			AppSlug:    "debug",
+		}).Asserts(t1, policy.ActionUpdate).Returns()
+	}))
+	s.Run("UpsertTemplateAppSessionPolicy", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpsertTemplateAppSessionPolicyParams{
+			TemplateID:         t1.ID,
+			AppSlug:            "debug",
+			MaxSessionDuration: int64(8 * time.Hour),
+			UpdatedAt:          dbtime.Now(),
+		}).Asserts(t1, policy.ActionUpdate)
+	}))
+	s.Run("GetTemplateAppSessionPolicies", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(t1.ID).Asserts(t1, policy.ActionRead)
+	}))
+	s.Run("DeleteTemplateAppSessionPolicy", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.DeleteTemplateAppSessionPolicyParams{
+			TemplateID: t1.ID,
+			AppSlug:    "debug",
+		}).Asserts(t1, policy.ActionUpdate).Returns()
+	}))
+	s.Run("UpsertTemplateEgressPolicy", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpsertTemplateEgressPolicyParams{
+			TemplateID:     t1.ID,
+			AllowedCidrs:   []string{"10.0.0.0/8"},
+			AllowedDomains: []string{"github.com"},
+			UpdatedAt:      dbtime.Now(),
+		}).Asserts(t1, policy.ActionUpdate)
+	}))
+	s.Run("GetTemplateEgressPolicyByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		p := dbgen.TemplateEgressPolicy(s.T(), db, database.TemplateEgressPolicy{TemplateID: t1.ID})
+		check.Args(t1.ID).Asserts(t1, policy.ActionRead).Returns(p)
+	}))
+	s.Run("DeleteTemplateEgressPolicy", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(t1.ID).Asserts(t1, policy.ActionUpdate).Returns()
+	}))
+	s.Run("InsertTemplateSecret", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.InsertTemplateSecretParams{
+			ID:         uuid.New(),
+			TemplateID: t1.ID,
+			Name:       "cloud_api_key",
+			Value:      "secret",
+			CreatedAt:  dbtime.Now(),
+			UpdatedAt:  dbtime.Now(),
+		}).Asserts(t1, policy.ActionUpdate)
+	}))
+	s.Run("GetTemplateSecretsByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		secret := dbgen.TemplateSecret(s.T(), db, database.TemplateSecret{TemplateID: t1.ID})
+		check.Args(t1.ID).Asserts(t1, policy.ActionUpdate).Returns([]database.TemplateSecret{secret})
+	}))
+	s.Run("GetTemplateSecretByTemplateIDAndName", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		secret := dbgen.TemplateSecret(s.T(), db, database.TemplateSecret{TemplateID: t1.ID})
+		check.Args(database.GetTemplateSecretByTemplateIDAndNameParams{
+			TemplateID: t1.ID,
+			Name:       secret.Name,
+		}).Asserts(t1, policy.ActionUpdate).Returns(secret)
+	}))
+	s.Run("UpdateTemplateSecret", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		secret := dbgen.TemplateSecret(s.T(), db, database.TemplateSecret{TemplateID: t1.ID})
+		check.Args(database.UpdateTemplateSecretParams{
+			TemplateID: t1.ID,
+			Name:       secret.Name,
+			Value:      "rotated",
+			UpdatedAt:  dbtime.Now(),
+		}).Asserts(t1, policy.ActionUpdate)
+	}))
+	s.Run("DeleteTemplateSecret", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		secret := dbgen.TemplateSecret(s.T(), db, database.TemplateSecret{TemplateID: t1.ID})
+		check.Args(database.DeleteTemplateSecretParams{
+			TemplateID: t1.ID,
+			Name:       secret.Name,
+		}).Asserts(t1, policy.ActionUpdate).Returns()
+	}))
+	s.Run("GetVariableSetsByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		set := dbgen.VariableSet(s.T(), db, database.VariableSet{})
+		err := db.InsertTemplateVariableSet(context.Background(), database.InsertTemplateVariableSetParams{
+			TemplateID:    t1.ID,
+			VariableSetID: set.ID,
+			CreatedAt:     dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(t1.ID).Asserts(t1, policy.ActionUpdate).Returns([]database.VariableSet{set})
+	}))
+	s.Run("InsertTemplateVariableSet", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		set := dbgen.VariableSet(s.T(), db, database.VariableSet{})
+		check.Args(database.InsertTemplateVariableSetParams{
+			TemplateID:    t1.ID,
+			VariableSetID: set.ID,
+			CreatedAt:     dbtime.Now(),
+		}).Asserts(t1, policy.ActionUpdate).Returns()
+	}))
+	s.Run("DeleteTemplateVariableSet", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		set := dbgen.VariableSet(s.T(), db, database.VariableSet{})
+		check.Args(database.DeleteTemplateVariableSetParams{
+			TemplateID:    t1.ID,
+			VariableSetID: set.ID,
+		}).Asserts(t1, policy.ActionUpdate).Returns()
+	}))
+	s.Run("InsertTemplateBundle", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		orgID := uuid.New()
+		check.Args(database.InsertTemplateBundleParams{
+			ID:             uuid.New(),
+			OrganizationID: orgID,
+			Name:           "docker",
+			Version:        "v1",
+			Manifest:       json.RawMessage("{}"),
+			Data:           []byte{},
+			CreatedBy:      uuid.New(),
+			CreatedAt:      dbtime.Now(),
+		}).Asserts(rbac.ResourceTemplate.InOrg(orgID), policy.ActionCreate).Returns()
+	}))
+	s.Run("GetTemplateBundlesByOrganizationID", s.Subtest(func(db database.Store, check *expects) {
+		orgID := uuid.New()
+		check.Args(orgID).Asserts(rbac.ResourceTemplate.InOrg(orgID), policy.ActionRead)
+	}))
+	s.Run("GetTemplateBundleByName", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		orgID := uuid.New()
+		err := db.InsertTemplateBundle(context.Background(), database.InsertTemplateBundleParams{
+			ID:             uuid.New(),
+			OrganizationID: orgID,
+			Name:           "docker",
+			Version:        "v1",
+			Manifest:       json.RawMessage("{}"),
+			Data:           []byte{},
+			CreatedBy:      uuid.New(),
+			CreatedAt:      dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.GetTemplateBundleByNameParams{
+			OrganizationID: orgID,
+			Name:           "docker",
+			Version:        "v1",
+		}).Asserts(rbac.ResourceTemplate.InOrg(orgID), policy.ActionRead)
+	}))
+	s.Run("DeleteTemplateBundleByName", s.Subtest(func(db database.Store, check *expects) {
+		orgID := uuid.New()
+		check.Args(database.DeleteTemplateBundleByNameParams{
+			OrganizationID: orgID,
+			Name:           "docker",
+			Version:        "v1",
+		}).Asserts(rbac.ResourceTemplate.InOrg(orgID), policy.ActionDelete).Returns()
+	}))
+	s.Run("GetTemplateGroupRoles", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(t1.ID).Asserts(t1, policy.ActionUpdate)
+	}))
+	s.Run("GetTemplateUserRoles", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(t1.ID).Asserts(t1, policy.ActionUpdate)
+	}))
+	s.Run("GetTemplateVersionByID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		check.Args(tv.ID).Asserts(t1, policy.ActionRead).Returns(tv)
+	}))
+	s.Run("GetTemplateVersionsByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		a := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		b := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		check.Args(database.GetTemplateVersionsByTemplateIDParams{
+			TemplateID: t1.ID,
+		}).Asserts(t1, policy.ActionRead).
+			Returns(slice.New(a, b))
+	}))
+	s.Run("GetTemplateVersionsCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		now := time.Now()
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		_ = dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+			CreatedAt:  now.Add(-time.Hour),
+		})
+		_ = dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+			CreatedAt:  now.Add(-2 * time.Hour),
+		})
+		check.Args(now.Add(-time.Hour)).Asserts(rbac.ResourceTemplate.All(), policy.ActionRead)
+	}))
+	s.Run("GetTemplatesWithFilter", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{})
+		a := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		// No asserts because SQLFilter.
+		check.Args(database.GetTemplatesWithFilterParams{}).
+			Asserts().Returns(slice.New(a))
+	}))
+	s.Run("GetAuthorizedTemplates", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		a := dbgen.Template(s.T(), db, database.Template{})
+		// No asserts because SQLFilter.
+		check.Args(database.GetTemplatesWithFilterParams{}, emptyPreparedAuthorized{}).
+			Asserts().
+			Returns(slice.New(a))
+	}))
+	s.Run("InsertTemplate", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		orgID := uuid.New()
+		check.Args(database.InsertTemplateParams{
+			Provisioner:         "echo",
+			OrganizationID:      orgID,
+			MaxPortSharingLevel: database.AppSharingLevelOwner,
+		}).Asserts(rbac.ResourceTemplate.InOrg(orgID), policy.ActionCreate)
+	}))
+	s.Run("InsertTemplateVersion", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.InsertTemplateVersionParams{
+			TemplateID:     uuid.NullUUID{UUID: t1.ID, Valid: true},
 			OrganizationID: t1.OrganizationID,
 		}).Asserts(t1, policy.ActionRead, t1, policy.ActionCreate)
 	}))
@@ -1394,6 +2229,15 @@ func (s *MethodTestSuite) TestTemplate() {
 			ID: t1.ID,
 		}).Asserts(t1, policy.ActionUpdate)
 	}))
+	s.Run("UpdateTemplateOrganizationByID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.UpdateTemplateOrganizationByIDParams{
+			ID:             t1.ID,
+			OrganizationID: o.ID,
+		}).Asserts(t1, policy.ActionDelete, rbac.ResourceTemplate.InOrg(o.ID), policy.ActionCreate)
+	}))
 	s.Run("UpdateTemplateScheduleByID", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		t1 := dbgen.Template(s.T(), db, database.Template{})
@@ -1433,6 +2277,19 @@ func (s *MethodTestSuite) TestTemplate() {
 			TemplateID: t1.ID,
 		}).Asserts(t1, policy.ActionUpdate)
 	}))
+	s.Run("UpdateWorkspacesOrganizationByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.UpdateWorkspacesOrganizationByTemplateIDParams{
+			TemplateID:     t1.ID,
+			OrganizationID: o.ID,
+		}).Asserts(
+			t1, policy.ActionUpdate,
+			rbac.ResourceWorkspace.InOrg(t1.OrganizationID), policy.ActionUpdate,
+			rbac.ResourceWorkspace.InOrg(o.ID), policy.ActionCreate,
+		)
+	}))
 	s.Run("UpdateWorkspacesTTLByTemplateID", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		t1 := dbgen.Template(s.T(), db, database.Template{})
@@ -1536,6 +2393,19 @@ func (s *MethodTestSuite) TestTemplate() {
 			EndTime:      dbtime.Now(),
 		}).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
 	}))
+	s.Run("GetTemplateBuildInsightsByInterval", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetTemplateBuildInsightsByIntervalParams{
+			IntervalDays: 7,
+			StartTime:    dbtime.Now().Add(-time.Hour * 24 * 7),
+			EndTime:      dbtime.Now(),
+		}).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
+	}))
+	s.Run("GetTemplateBuildStageDurations", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetTemplateBuildStageDurationsParams{}).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
+	}))
+	s.Run("GetTemplateBuildFailures", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetTemplateBuildFailuresParams{}).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
+	}))
 	s.Run("GetTemplateInsightsByTemplate", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.GetTemplateInsightsByTemplateParams{}).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
 	}))
@@ -1567,6 +2437,28 @@ func (s *MethodTestSuite) TestUser() {
 		u := dbgen.User(s.T(), db, database.User{})
 		check.Args(u.ID).Asserts(rbac.ResourceApiKey.WithOwner(u.ID.String()), policy.ActionDelete).Returns()
 	}))
+	s.Run("DeleteAPIKeysByUserIDLoginType", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.DeleteAPIKeysByUserIDLoginTypeParams{
+			UserID:    u.ID,
+			LoginType: database.LoginTypeSaml,
+		}).Asserts(rbac.ResourceApiKey.WithOwner(u.ID.String()), policy.ActionDelete).Returns()
+	}))
+	s.Run("DeleteExcessAPIKeySessionsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.DeleteExcessAPIKeySessionsByUserIDParams{
+			UserID:      u.ID,
+			Now:         dbtime.Now(),
+			MaxSessions: 1,
+		}).Asserts(rbac.ResourceApiKey.WithOwner(u.ID.String()), policy.ActionDelete).Returns()
+	}))
+	s.Run("DeleteStaleAPIKeySessionsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.DeleteStaleAPIKeySessionsByUserIDParams{
+			UserID:        u.ID,
+			CreatedBefore: dbtime.Now(),
+		}).Asserts(rbac.ResourceApiKey.WithOwner(u.ID.String()), policy.ActionDelete).Returns()
+	}))
 	s.Run("GetQuotaAllowanceForUser", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		check.Args(database.GetQuotaAllowanceForUserParams{
@@ -1581,6 +2473,20 @@ func (s *MethodTestSuite) TestUser() {
 			OrganizationID: uuid.New(),
 		}).Asserts(u, policy.ActionRead).Returns(int64(0))
 	}))
+	s.Run("GetQuotaDimensionAllowancesForUser", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.GetQuotaDimensionAllowancesForUserParams{
+			UserID:         u.ID,
+			OrganizationID: uuid.New(),
+		}).Asserts(u, policy.ActionRead).Returns([]database.GetQuotaDimensionAllowancesForUserRow{})
+	}))
+	s.Run("GetQuotaDimensionsConsumedForUser", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.GetQuotaDimensionsConsumedForUserParams{
+			OwnerID:        u.ID,
+			OrganizationID: uuid.New(),
+		}).Asserts(u, policy.ActionRead).Returns([]database.GetQuotaDimensionsConsumedForUserRow{})
+	}))
 	s.Run("GetUserByEmailOrUsername", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		check.Args(database.GetUserByEmailOrUsernameParams{
@@ -1621,6 +2527,15 @@ func (s *MethodTestSuite) TestUser() {
 			LoginType: database.LoginTypeOIDC,
 		}).Asserts(u, policy.ActionUpdate)
 	}))
+	s.Run("UpsertLDAPUser", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertLDAPUserParams{
+			UserID:     u.ID,
+			ExternalID: "external-id",
+			Dn:         "CN=Test,DC=example,DC=com",
+			UpdatedAt:  dbtime.Now(),
+		}).Asserts(u, policy.ActionUpdate)
+	}))
 	s.Run("UpdateUserDeletedByID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		check.Args(u.ID).Asserts(u, policy.ActionDelete).Returns()
@@ -1701,12 +2616,97 @@ func (s *MethodTestSuite) TestUser() {
 			ThemePreference: uc.Value,
 		}).Asserts(u, policy.ActionUpdatePersonal).Returns(uc)
 	}))
-	s.Run("GetUserTerminalFont", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetUserNotificationDigest", s.Subtest(func(db database.Store, check *expects) {
 		ctx := context.Background()
 		u := dbgen.User(s.T(), db, database.User{})
-		db.UpdateUserTerminalFont(ctx, database.UpdateUserTerminalFontParams{
-			UserID:       u.ID,
-			TerminalFont: "ibm-plex-mono",
+		db.UpdateUserNotificationDigest(ctx, database.UpdateUserNotificationDigestParams{
+			UserID:             u.ID,
+			NotificationDigest: "true",
+		})
+		check.Args(u.ID).Asserts(u, policy.ActionReadPersonal).Returns("true")
+	}))
+	s.Run("UpdateUserNotificationDigest", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		uc := database.UserConfig{
+			UserID: u.ID,
+			Key:    "notification_digest",
+			Value:  "true",
+		}
+		check.Args(database.UpdateUserNotificationDigestParams{
+			UserID:             u.ID,
+			NotificationDigest: uc.Value,
+		}).Asserts(u, policy.ActionUpdatePersonal).Returns(uc)
+	}))
+	s.Run("GetUserStartOnConnect", s.Subtest(func(db database.Store, check *expects) {
+		ctx := context.Background()
+		u := dbgen.User(s.T(), db, database.User{})
+		db.UpdateUserStartOnConnect(ctx, database.UpdateUserStartOnConnectParams{
+			UserID:         u.ID,
+			StartOnConnect: "false",
+		})
+		check.Args(u.ID).Asserts(u, policy.ActionReadPersonal).Returns("false")
+	}))
+	s.Run("UpdateUserStartOnConnect", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		uc := database.UserConfig{
+			UserID: u.ID,
+			Key:    "start_on_connect",
+			Value:  "false",
+		}
+		check.Args(database.UpdateUserStartOnConnectParams{
+			UserID:         u.ID,
+			StartOnConnect: uc.Value,
+		}).Asserts(u, policy.ActionUpdatePersonal).Returns(uc)
+	}))
+	s.Run("GetUserProxyPreference", s.Subtest(func(db database.Store, check *expects) {
+		ctx := context.Background()
+		u := dbgen.User(s.T(), db, database.User{})
+		regionID := uuid.NewString()
+		db.UpdateUserProxyPreference(ctx, database.UpdateUserProxyPreferenceParams{
+			UserID:          u.ID,
+			ProxyPreference: regionID,
+		})
+		check.Args(u.ID).Asserts(u, policy.ActionReadPersonal).Returns(regionID)
+	}))
+	s.Run("UpdateUserProxyPreference", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		uc := database.UserConfig{
+			UserID: u.ID,
+			Key:    "proxy_preference",
+			Value:  uuid.NewString(),
+		}
+		check.Args(database.UpdateUserProxyPreferenceParams{
+			UserID:          u.ID,
+			ProxyPreference: uc.Value,
+		}).Asserts(u, policy.ActionUpdatePersonal).Returns(uc)
+	}))
+	s.Run("GetUserProxyLatencies", s.Subtest(func(db database.Store, check *expects) {
+		ctx := context.Background()
+		u := dbgen.User(s.T(), db, database.User{})
+		db.UpdateUserProxyLatencies(ctx, database.UpdateUserProxyLatenciesParams{
+			UserID:         u.ID,
+			ProxyLatencies: "[]",
+		})
+		check.Args(u.ID).Asserts(u, policy.ActionReadPersonal).Returns("[]")
+	}))
+	s.Run("UpdateUserProxyLatencies", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		uc := database.UserConfig{
+			UserID: u.ID,
+			Key:    "proxy_latencies",
+			Value:  "[]",
+		}
+		check.Args(database.UpdateUserProxyLatenciesParams{
+			UserID:         u.ID,
+			ProxyLatencies: uc.Value,
+		}).Asserts(u, policy.ActionUpdatePersonal).Returns(uc)
+	}))
+	s.Run("GetUserTerminalFont", s.Subtest(func(db database.Store, check *expects) {
+		ctx := context.Background()
+		u := dbgen.User(s.T(), db, database.User{})
+		db.UpdateUserTerminalFont(ctx, database.UpdateUserTerminalFontParams{
+			UserID:       u.ID,
+			TerminalFont: "ibm-plex-mono",
 		})
 		check.Args(u.ID).Asserts(u, policy.ActionReadPersonal).Returns("ibm-plex-mono")
 	}))
@@ -1954,6 +2954,18 @@ func (s *MethodTestSuite) TestUser() {
 			rbac.ResourceTemplate.InOrg(orgID), policy.ActionRead,
 		)
 	}))
+	s.Run("GetUserWorkspaceUsage", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetUserWorkspaceUsageParams{
+			StartTime: time.Now().Add(-time.Hour * 24 * 7),
+			EndTime:   time.Now(),
+		}).Asserts(rbac.ResourceDeploymentStats, policy.ActionRead, rbac.ResourceUser, policy.ActionRead)
+	}))
+	s.Run("GetGroupWorkspaceUsage", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetGroupWorkspaceUsageParams{
+			StartTime: time.Now().Add(-time.Hour * 24 * 7),
+			EndTime:   time.Now(),
+		}).Asserts(rbac.ResourceDeploymentStats, policy.ActionRead, rbac.ResourceGroup, policy.ActionRead)
+	}))
 	s.Run("GetUserStatusCounts", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.GetUserStatusCountsParams{
 			StartTime: time.Now().Add(-time.Hour * 24 * 30),
@@ -2057,6 +3069,50 @@ func (s *MethodTestSuite) TestWorkspace() {
 		})
 		check.Args(w.ID).Asserts(w, policy.ActionRead).Returns(b)
 	}))
+	s.Run("GetRollbackWorkspaceBuild", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type:        database.ProvisionerJobTypeWorkspaceBuild,
+			StartedAt:   sql.NullTime{Time: dbtime.Now(), Valid: true},
+			CompletedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+			Transition:        database.WorkspaceTransitionStart,
+			BuildNumber:       1,
+		})
+		failedJob := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type:        database.ProvisionerJobTypeWorkspaceBuild,
+			StartedAt:   sql.NullTime{Time: dbtime.Now(), Valid: true},
+			CompletedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+			Error:       sql.NullString{String: "failed", Valid: true},
+		})
+		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             failedJob.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+			Transition:        database.WorkspaceTransitionStart,
+			BuildNumber:       2,
+		})
+		check.Args(w.ID).Asserts(w, policy.ActionRead).Returns(b)
+	}))
 	s.Run("GetWorkspaceAgentByID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -2245,6 +3301,40 @@ func (s *MethodTestSuite) TestWorkspace() {
 			LifecycleState: database.WorkspaceAgentLifecycleStateCreated,
 		}).Asserts(w, policy.ActionUpdate).Returns()
 	}))
+	s.Run("InsertWorkspaceAgentPushedMetadata", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.InsertWorkspaceAgentPushedMetadataParams{
+			WorkspaceAgentID: agt.ID,
+			Key:              []string{},
+			DisplayName:      []string{},
+			MaxPushed:        1,
+		}).Asserts(w, policy.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateWorkspaceAgentMetadata", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -2342,6 +3432,78 @@ func (s *MethodTestSuite) TestWorkspace() {
 			},
 		}).Asserts(w, policy.ActionUpdate).Returns()
 	}))
+	s.Run("RotateWorkspaceAgentAuthToken", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.RotateWorkspaceAgentAuthTokenParams{
+			NewAuthToken:               uuid.New(),
+			AgentID:                    agt.ID,
+			PreviousAuthToken:          agt.AuthToken,
+			PreviousAuthTokenExpiresAt: dbtime.Now().Add(time.Minute),
+			RotatedAt:                  dbtime.Now(),
+		}).Asserts(w, policy.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceAgentTokenRotationByAgentID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		rotation, err := db.RotateWorkspaceAgentAuthToken(context.Background(), database.RotateWorkspaceAgentAuthTokenParams{
+			NewAuthToken:               uuid.New(),
+			AgentID:                    agt.ID,
+			PreviousAuthToken:          agt.AuthToken,
+			PreviousAuthTokenExpiresAt: dbtime.Now().Add(time.Minute),
+			RotatedAt:                  dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(agt.ID).Asserts(w, policy.ActionRead).Returns(rotation)
+	}))
 	s.Run("GetWorkspaceAgentLogsAfter", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -2552,6 +3714,34 @@ func (s *MethodTestSuite) TestWorkspace() {
 		check.Args(build.ID).Asserts(ws, policy.ActionRead).
 			Returns([]database.WorkspaceBuildParameter{})
 	}))
+	s.Run("GetWorkspaceBuildQuotaCosts", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       ws.ID,
+			TemplateVersionID: tv.ID,
+		})
+		check.Args(build.ID).Asserts(ws, policy.ActionRead).
+			Returns([]database.WorkspaceBuildQuotaCost{})
+	}))
 	s.Run("GetWorkspaceBuildsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -3238,58 +4428,491 @@ func (s *MethodTestSuite) TestWorkspace() {
 			CreatedBy:      u.ID,
 		})
 		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
-			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		app := dbgen.WorkspaceApp(s.T(), db, database.WorkspaceApp{AgentID: agt.ID})
+		check.Args(app.ID).Asserts(w, policy.ActionRead)
+	}))
+	s.Run("ActivityBumpWorkspace", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		check.Args(database.ActivityBumpWorkspaceParams{
+			WorkspaceID: w.ID,
+		}).Asserts(w, policy.ActionUpdate).Returns()
+	}))
+	s.Run("FavoriteWorkspace", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		check.Args(w.ID).Asserts(w, policy.ActionUpdate).Returns()
+	}))
+	s.Run("DeleteWorkspaceLabelsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		check.Args(w.ID).Asserts(w, policy.ActionUpdate).Returns()
+	}))
+	s.Run("InsertWorkspaceLabels", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		check.Args(database.InsertWorkspaceLabelsParams{
+			WorkspaceID: w.ID,
+			Keys:        []string{"team"},
+			Values:      []string{"platform"},
+		}).Asserts(w, policy.ActionUpdate).Returns()
+	}))
+	s.Run("UpsertWorkspaceSchedulePause", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		check.Args(database.UpsertWorkspaceSchedulePauseParams{
+			WorkspaceID: w.ID,
+			PausedUntil: dbtime.Now().Add(time.Hour),
+			CreatedAt:   dbtime.Now(),
+			CreatedBy:   u.ID,
+		}).Asserts(w, policy.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceSchedulePauseByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		pause, err := db.UpsertWorkspaceSchedulePause(context.Background(), database.UpsertWorkspaceSchedulePauseParams{
+			WorkspaceID: w.ID,
+			PausedUntil: dbtime.Now().Add(time.Hour),
+			CreatedAt:   dbtime.Now(),
+			CreatedBy:   u.ID,
+		})
+		require.NoError(s.T(), err)
+		check.Args(w.ID).Asserts(w, policy.ActionRead).Returns(pause)
+	}))
+	s.Run("DeleteWorkspaceSchedulePause", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		check.Args(w.ID).Asserts(w, policy.ActionUpdate).Returns()
+	}))
+	s.Run("InsertWorkspaceArchive", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		check.Args(database.InsertWorkspaceArchiveParams{
+			ID:             uuid.New(),
+			WorkspaceID:    w.ID,
+			OwnerID:        u.ID,
+			OrganizationID: o.ID,
+			TemplateID:     tpl.ID,
+			Paths:          []string{"/home/coder"},
+			CreatedAt:      dbtime.Now(),
+			ExpiresAt:      dbtime.Now().Add(time.Hour),
+		}).Asserts(rbac.ResourceWorkspaceDormant.WithID(w.ID).InOrg(o.ID).WithOwner(u.ID.String()), policy.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceArchiveByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		archive := dbgen.WorkspaceArchive(s.T(), db, database.WorkspaceArchive{
+			WorkspaceID:    w.ID,
+			OwnerID:        u.ID,
+			OrganizationID: o.ID,
+			TemplateID:     tpl.ID,
+		})
+		check.Args(archive.ID).Asserts(archive, policy.ActionRead).Returns(archive)
+	}))
+	s.Run("GetLatestWorkspaceArchiveByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		archive := dbgen.WorkspaceArchive(s.T(), db, database.WorkspaceArchive{
+			WorkspaceID:    w.ID,
+			OwnerID:        u.ID,
+			OrganizationID: o.ID,
+			TemplateID:     tpl.ID,
+		})
+		check.Args(w.ID).Asserts(archive, policy.ActionRead).Returns(archive)
+	}))
+	s.Run("GetWorkspaceArchivesByOwnerID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		archive := dbgen.WorkspaceArchive(s.T(), db, database.WorkspaceArchive{
+			WorkspaceID:    w.ID,
+			OwnerID:        u.ID,
+			OrganizationID: o.ID,
+			TemplateID:     tpl.ID,
+		})
+		check.Args(u.ID).Asserts(archive, policy.ActionRead).Returns([]database.WorkspaceArchive{archive})
+	}))
+	s.Run("GetWorkspaceArchivesExpiredBefore", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("DeleteWorkspaceArchiveByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		archive := dbgen.WorkspaceArchive(s.T(), db, database.WorkspaceArchive{
+			WorkspaceID:    w.ID,
+			OwnerID:        u.ID,
+			OrganizationID: o.ID,
+			TemplateID:     tpl.ID,
+		})
+		check.Args(archive.ID).Asserts(archive, policy.ActionDelete).Returns()
+	}))
+	s.Run("InsertWorkspaceAdoption", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		check.Args(database.InsertWorkspaceAdoptionParams{
+			ID:             uuid.New(),
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+			TemplateID:     tpl.ID,
+			WorkspaceName:  "adopted",
+			HashedSecret:   []byte("secret"),
+			CreatedBy:      u.ID,
+			CreatedAt:      dbtime.Now(),
+			ExpiresAt:      dbtime.Now().Add(time.Hour),
+		}).Asserts(rbac.ResourceWorkspace.InOrg(o.ID).WithOwner(u.ID.String()), policy.ActionCreate)
+	}))
+	s.Run("GetWorkspaceAdoptionByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		adoption, err := db.InsertWorkspaceAdoption(context.Background(), database.InsertWorkspaceAdoptionParams{
+			ID:             uuid.New(),
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+			TemplateID:     tpl.ID,
+			WorkspaceName:  "adopted",
+			HashedSecret:   []byte("secret"),
+			CreatedBy:      u.ID,
+			CreatedAt:      dbtime.Now(),
+			ExpiresAt:      dbtime.Now().Add(time.Hour),
+		})
+		require.NoError(s.T(), err)
+		check.Args(adoption.ID).Asserts(adoption, policy.ActionRead).Returns(adoption)
+	}))
+	s.Run("GetWorkspaceAdoptionByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		adoption, err := db.InsertWorkspaceAdoption(context.Background(), database.InsertWorkspaceAdoptionParams{
+			ID:             uuid.New(),
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+			TemplateID:     tpl.ID,
+			WorkspaceName:  "adopted",
+			HashedSecret:   []byte("secret"),
+			CreatedBy:      u.ID,
+			CreatedAt:      dbtime.Now(),
+			ExpiresAt:      dbtime.Now().Add(time.Hour),
+		})
+		require.NoError(s.T(), err)
+		adoption, err = db.UpdateWorkspaceAdoptionAdopted(context.Background(), database.UpdateWorkspaceAdoptionAdoptedParams{
+			ID:          adoption.ID,
+			WorkspaceID: uuid.NullUUID{UUID: w.ID, Valid: true},
+			Hostname:    "build-box",
+			AdoptedAt:   sql.NullTime{Time: dbtime.Now(), Valid: true},
+		})
+		require.NoError(s.T(), err)
+		check.Args(uuid.NullUUID{UUID: w.ID, Valid: true}).Asserts(adoption, policy.ActionRead).Returns(adoption)
+	}))
+	s.Run("UpdateWorkspaceAdoptionAdopted", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		adoption, err := db.InsertWorkspaceAdoption(context.Background(), database.InsertWorkspaceAdoptionParams{
+			ID:             uuid.New(),
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+			TemplateID:     tpl.ID,
+			WorkspaceName:  "adopted",
+			HashedSecret:   []byte("secret"),
+			CreatedBy:      u.ID,
+			CreatedAt:      dbtime.Now(),
+			ExpiresAt:      dbtime.Now().Add(time.Hour),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateWorkspaceAdoptionAdoptedParams{
+			ID:          adoption.ID,
+			WorkspaceID: uuid.NullUUID{UUID: w.ID, Valid: true},
+			Hostname:    "build-box",
+			AdoptedAt:   sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(adoption, policy.ActionUpdate)
+	}))
+	s.Run("InsertAITaskQueueEntry", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		check.Args(database.InsertAITaskQueueEntryParams{
+			ID:                uuid.New(),
+			OwnerID:           u.ID,
+			OrganizationID:    o.ID,
+			TemplateVersionID: tv.ID,
+			Name:              "fix-tests",
+			Prompt:            "Fix the failing tests.",
+			CreatedAt:         dbtime.Now(),
+			UpdatedAt:         dbtime.Now(),
+		}).Asserts(rbac.ResourceWorkspace.InOrg(o.ID).WithOwner(u.ID.String()), policy.ActionCreate)
+	}))
+	s.Run("GetAITaskQueueEntryByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		entry, err := db.InsertAITaskQueueEntry(context.Background(), database.InsertAITaskQueueEntryParams{
+			ID:                uuid.New(),
+			OwnerID:           u.ID,
+			OrganizationID:    o.ID,
+			TemplateVersionID: tv.ID,
+			Name:              "fix-tests",
+			Prompt:            "Fix the failing tests.",
+			CreatedAt:         dbtime.Now(),
+			UpdatedAt:         dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(entry.ID).Asserts(entry, policy.ActionRead).Returns(entry)
+	}))
+	s.Run("GetAITaskQueueEntriesByOwnerID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		entry, err := db.InsertAITaskQueueEntry(context.Background(), database.InsertAITaskQueueEntryParams{
+			ID:                uuid.New(),
+			OwnerID:           u.ID,
+			OrganizationID:    o.ID,
+			TemplateVersionID: tv.ID,
+			Name:              "fix-tests",
+			Prompt:            "Fix the failing tests.",
+			CreatedAt:         dbtime.Now(),
+			UpdatedAt:         dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(u.ID).Asserts(entry, policy.ActionRead).Returns([]database.AITaskQueue{entry})
+	}))
+	s.Run("DeleteAITaskQueueEntryByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		entry, err := db.InsertAITaskQueueEntry(context.Background(), database.InsertAITaskQueueEntryParams{
+			ID:                uuid.New(),
+			OwnerID:           u.ID,
+			OrganizationID:    o.ID,
+			TemplateVersionID: tv.ID,
+			Name:              "fix-tests",
+			Prompt:            "Fix the failing tests.",
+			CreatedAt:         dbtime.Now(),
+			UpdatedAt:         dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(entry.ID).Asserts(entry, policy.ActionDelete).Returns()
+	}))
+	s.Run("UpdateAITaskQueueEntryFailed", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
 			OrganizationID: o.ID,
 			CreatedBy:      u.ID,
 		})
-		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
-			TemplateID:     tpl.ID,
-			OrganizationID: o.ID,
-			OwnerID:        u.ID,
-		})
-		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
-			Type: database.ProvisionerJobTypeWorkspaceBuild,
-		})
-		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
-			JobID:             j.ID,
-			WorkspaceID:       w.ID,
+		entry, err := db.InsertAITaskQueueEntry(context.Background(), database.InsertAITaskQueueEntryParams{
+			ID:                uuid.New(),
+			OwnerID:           u.ID,
+			OrganizationID:    o.ID,
 			TemplateVersionID: tv.ID,
+			Name:              "fix-tests",
+			Prompt:            "Fix the failing tests.",
+			CreatedAt:         dbtime.Now(),
+			UpdatedAt:         dbtime.Now(),
 		})
-		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
-		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		app := dbgen.WorkspaceApp(s.T(), db, database.WorkspaceApp{AgentID: agt.ID})
-		check.Args(app.ID).Asserts(w, policy.ActionRead)
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateAITaskQueueEntryFailedParams{
+			ID:        entry.ID,
+			Error:     "Template version is archived.",
+			UpdatedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate).Returns()
 	}))
-	s.Run("ActivityBumpWorkspace", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetAITaskQueueOwnerIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("CountRunningAITasksByOwnerID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(int64(0))
+	}))
+	s.Run("UnfavoriteWorkspace", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		tpl := dbgen.Template(s.T(), db, database.Template{
 			OrganizationID: o.ID,
 			CreatedBy:      u.ID,
 		})
-		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
-			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
-			OrganizationID: o.ID,
-			CreatedBy:      u.ID,
-		})
 		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
 			TemplateID:     tpl.ID,
 			OrganizationID: o.ID,
 			OwnerID:        u.ID,
 		})
-		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
-			Type: database.ProvisionerJobTypeWorkspaceBuild,
-		})
-		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
-			JobID:             j.ID,
-			WorkspaceID:       w.ID,
-			TemplateVersionID: tv.ID,
-		})
-		check.Args(database.ActivityBumpWorkspaceParams{
-			WorkspaceID: w.ID,
-		}).Asserts(w, policy.ActionUpdate).Returns()
+		check.Args(w.ID).Asserts(w, policy.ActionUpdate).Returns()
 	}))
-	s.Run("FavoriteWorkspace", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetReviewWorkspace", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		tpl := dbgen.Template(s.T(), db, database.Template{
@@ -3301,9 +4924,23 @@ func (s *MethodTestSuite) TestWorkspace() {
 			OrganizationID: o.ID,
 			OwnerID:        u.ID,
 		})
-		check.Args(w.ID).Asserts(w, policy.ActionUpdate).Returns()
+		rw := dbgen.ReviewWorkspace(s.T(), db, database.ReviewWorkspace{
+			WorkspaceID:    w.ID,
+			OrganizationID: o.ID,
+		})
+		check.Args(database.GetReviewWorkspaceParams{
+			OrganizationID: o.ID,
+			Repository:     rw.Repository,
+			Branch:         rw.Branch,
+		}).Asserts(w, policy.ActionRead).Returns(rw)
 	}))
-	s.Run("UnfavoriteWorkspace", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetReviewWorkspacesByRepositoryAndBranch", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetReviewWorkspacesByRepositoryAndBranchParams{
+			Repository: "coder/coder",
+			Branch:     "main",
+		}).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("UpsertReviewWorkspace", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		tpl := dbgen.Template(s.T(), db, database.Template{
@@ -3315,7 +4952,13 @@ func (s *MethodTestSuite) TestWorkspace() {
 			OrganizationID: o.ID,
 			OwnerID:        u.ID,
 		})
-		check.Args(w.ID).Asserts(w, policy.ActionUpdate).Returns()
+		check.Args(database.UpsertReviewWorkspaceParams{
+			WorkspaceID:    w.ID,
+			OrganizationID: o.ID,
+			Repository:     "coder/coder",
+			Branch:         "main",
+			CreatedAt:      dbtime.Now(),
+		}).Asserts(w, policy.ActionUpdate)
 	}))
 	s.Run("GetWorkspaceAgentDevcontainersByAgentID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
@@ -3455,6 +5098,90 @@ func (s *MethodTestSuite) TestWorkspacePortSharing() {
 		_ = dbgen.WorkspaceAgentPortShare(s.T(), db, database.WorkspaceAgentPortShare{WorkspaceID: ws.ID})
 		check.Args(tpl.ID).Asserts(tpl, policy.ActionUpdate).Returns()
 	}))
+	s.Run("InsertWorkspacePortShareLink", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OwnerID:        u.ID,
+			OrganizationID: org.ID,
+			TemplateID:     tpl.ID,
+		})
+		check.Args(database.InsertWorkspacePortShareLinkParams{
+			ID:           uuid.New(),
+			WorkspaceID:  ws.ID,
+			AgentName:    "main",
+			Port:         8080,
+			Protocol:     database.PortShareProtocolHttp,
+			HashedSecret: []byte("secret"),
+			ExpiresAt:    dbtime.Now().Add(time.Hour),
+			CreatedBy:    u.ID,
+			CreatedAt:    dbtime.Now(),
+		}).Asserts(ws, policy.ActionUpdate)
+	}))
+	s.Run("GetWorkspacePortShareLinkByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OwnerID:        u.ID,
+			OrganizationID: org.ID,
+			TemplateID:     tpl.ID,
+		})
+		link := dbgen.WorkspacePortShareLink(s.T(), db, database.WorkspacePortShareLink{WorkspaceID: ws.ID, CreatedBy: u.ID})
+		check.Args(link.ID).Asserts(ws, policy.ActionRead).Returns(link)
+	}))
+	s.Run("GetWorkspacePortShareLinksByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OwnerID:        u.ID,
+			OrganizationID: org.ID,
+			TemplateID:     tpl.ID,
+		})
+		link := dbgen.WorkspacePortShareLink(s.T(), db, database.WorkspacePortShareLink{WorkspaceID: ws.ID, CreatedBy: u.ID})
+		check.Args(ws.ID).Asserts(ws, policy.ActionRead).Returns([]database.WorkspacePortShareLink{link})
+	}))
+	s.Run("DeleteWorkspacePortShareLink", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OwnerID:        u.ID,
+			OrganizationID: org.ID,
+			TemplateID:     tpl.ID,
+		})
+		link := dbgen.WorkspacePortShareLink(s.T(), db, database.WorkspacePortShareLink{WorkspaceID: ws.ID, CreatedBy: u.ID})
+		check.Args(link.ID).Asserts(ws, policy.ActionUpdate).Returns()
+	}))
+	s.Run("DeleteWorkspacePortShareLinksByTemplate", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OwnerID:        u.ID,
+			OrganizationID: org.ID,
+			TemplateID:     tpl.ID,
+		})
+		_ = dbgen.WorkspacePortShareLink(s.T(), db, database.WorkspacePortShareLink{WorkspaceID: ws.ID, CreatedBy: u.ID})
+		check.Args(tpl.ID).Asserts(tpl, policy.ActionUpdate).Returns()
+	}))
 }
 
 func (s *MethodTestSuite) TestProvisionerKeys() {
@@ -3867,6 +5594,20 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("GetLatestWorkspaceAppStatusesByWorkspaceIDs", s.Subtest(func(db database.Store, check *expects) {
 		check.Args([]uuid.UUID{}).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
+	s.Run("UpsertUserCLIVersion", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertUserCLIVersionParams{
+			UserID:     u.ID,
+			Version:    "v2.20.0",
+			LastSeenAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate).Returns()
+	}))
+	s.Run("GetUserCLIVersions", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Time{}).Asserts(rbac.ResourceDeploymentConfig, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceAgentVersionsInLatestBuilds", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceDeploymentConfig, policy.ActionRead)
+	}))
 	s.Run("GetWorkspaceAppStatusesByAppIDs", s.Subtest(func(db database.Store, check *expects) {
 		check.Args([]uuid.UUID{}).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
@@ -3876,9 +5617,22 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID})
 		check.Args([]uuid.UUID{ws.ID}).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(slice.New(b))
 	}))
+	s.Run("GetTemplateVersionRolloutTargetsByWorkspaceIDs", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		check.Args([]uuid.UUID{ws.ID}).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceLabelsByWorkspaceIDs", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
+		check.Args([]uuid.UUID{ws.ID}).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
 	s.Run("UpsertDefaultProxy", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.UpsertDefaultProxyParams{}).Asserts(rbac.ResourceSystem, policy.ActionUpdate).Returns()
 	}))
+	s.Run("GetLDAPUsers", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
 	s.Run("GetUserLinkByLinkedID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		l := dbgen.UserLink(s.T(), db, database.UserLink{UserID: u.ID})
@@ -3936,6 +5690,67 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		require.NoError(s.T(), err)
 		check.Args(time.Now().Add(time.Hour)).Asserts(rbac.ResourceSystem, policy.ActionDelete)
 	}))
+	s.Run("InsertHealthScore", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertHealthScoreParams{
+			ReplicaID: uuid.New(),
+			CreatedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
+	s.Run("GetHealthScores", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetHealthScoresParams{
+			StartTime: dbtime.Now().Add(-time.Hour),
+			EndTime:   dbtime.Now(),
+		}).Asserts(rbac.ResourceDebugInfo, policy.ActionRead)
+	}))
+	s.Run("DeleteOldHealthScores", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("UpsertNetworkConnectionQuality", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpsertNetworkConnectionQualityParams{
+			ClientType:     "cli",
+			NodeIDSelf:     1,
+			NodeIDRemote:   2,
+			ConnectionType: "p2p",
+			UpdatedAt:      dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("GetNetworkConnectionQuality", s.Subtest(func(db database.Store, check *expects) {
+		err := db.UpsertNetworkConnectionQuality(context.Background(), database.UpsertNetworkConnectionQualityParams{
+			ClientType:     "cli",
+			NodeIDSelf:     1,
+			NodeIDRemote:   2,
+			ConnectionType: "p2p",
+			UpdatedAt:      dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.GetNetworkConnectionQualityParams{
+			ClientType:   "cli",
+			NodeIDSelf:   1,
+			NodeIDRemote: 2,
+		}).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("DeleteNetworkConnectionQuality", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.DeleteNetworkConnectionQualityParams{
+			ClientType:   "cli",
+			NodeIDSelf:   1,
+			NodeIDRemote: 2,
+		}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("DeleteOldNetworkConnectionQuality", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("GetNetworkConnectionQualitySummary", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now().Add(-time.Hour)).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
+	s.Run("IncrementNetworkConnectionQualityCounters", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.IncrementNetworkConnectionQualityCountersParams{
+			ClientType:     "cli",
+			RelayFallbacks: 1,
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("GetNetworkConnectionQualityCounters", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
 	s.Run("GetReplicasUpdatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		_, err := db.InsertReplica(context.Background(), database.InsertReplicaParams{ID: uuid.New(), UpdatedAt: time.Now()})
 		require.NoError(s.T(), err)
@@ -3959,6 +5774,15 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			DailyCost: 10,
 		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
 	}))
+	s.Run("InsertWorkspaceBuildQuotaCosts", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{})
+		check.Args(database.InsertWorkspaceBuildQuotaCostsParams{
+			WorkspaceBuildID: b.ID,
+			Dimensions:       []string{"cpu"},
+			Costs:            []int64{2},
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
 	s.Run("UpdateWorkspaceBuildProvisionerStateByID", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{})
@@ -4008,6 +5832,14 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		_ = dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{CreatedAt: time.Now().Add(-time.Hour)})
 		check.Args(time.Now()).Asserts(rbac.ResourceProvisionerJobs, policy.ActionRead)
 	}))
+	s.Run("GetPendingProvisionerJobsCount", s.Subtest(func(db database.Store, check *expects) {
+		_ = dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
+		check.Args().Asserts(rbac.ResourceProvisionerJobs, policy.ActionRead)
+	}))
+	s.Run("GetProvisionerJobCountsByOrganization", s.Subtest(func(db database.Store, check *expects) {
+		_ = dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
+		check.Args().Asserts(rbac.ResourceProvisionerJobs, policy.ActionRead)
+	}))
 	s.Run("GetTemplateVersionsByIDs", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		t1 := dbgen.Template(s.T(), db, database.Template{})
@@ -4218,6 +6050,20 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			ID: j.ID,
 		}).Asserts(rbac.ResourceProvisionerJobs, policy.ActionUpdate)
 	}))
+	s.Run("RequeueProvisionerJob", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
+		check.Args(database.RequeueProvisionerJobParams{
+			ID:        j.ID,
+			UpdatedAt: time.Now(),
+		}).Asserts(rbac.ResourceProvisionerJobs, policy.ActionUpdate)
+	}))
+	s.Run("InsertProvisionerJobRequeue", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
+		check.Args(database.InsertProvisionerJobRequeueParams{
+			JobID:     j.ID,
+			CreatedAt: time.Now(),
+		}).Asserts(rbac.ResourceProvisionerJobs, policy.ActionUpdate)
+	}))
 	s.Run("UpdateProvisionerJobByID", s.Subtest(func(db database.Store, check *expects) {
 		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
 		check.Args(database.UpdateProvisionerJobByIDParams{
@@ -4241,6 +6087,13 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			JobID: j.ID,
 		}).Asserts( /* rbac.ResourceProvisionerJobs, policy.ActionUpdate */ )
 	}))
+	s.Run("SearchProvisionerJobLogs", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.SearchProvisionerJobLogsParams{
+			OrganizationID: o.ID,
+			Search:         "error",
+		}).Asserts(rbac.ResourceProvisionerJobs.InOrg(o.ID), policy.ActionRead)
+	}))
 	s.Run("InsertProvisionerJobTimings", s.Subtest(func(db database.Store, check *expects) {
 		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
 		check.Args(database.InsertProvisionerJobTimingsParams{
@@ -4350,6 +6203,12 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("UpsertAppSecurityKey", s.Subtest(func(db database.Store, check *expects) {
 		check.Args("foo").Asserts(rbac.ResourceSystem, policy.ActionUpdate)
 	}))
+	s.Run("GetInternalCA", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead).ErrorsWithPG(sql.ErrNoRows)
+	}))
+	s.Run("UpsertInternalCA", s.Subtest(func(db database.Store, check *expects) {
+		check.Args("{}").Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
 	s.Run("GetApplicationName", s.Subtest(func(db database.Store, check *expects) {
 		db.UpsertApplicationName(context.Background(), "foo")
 		check.Args().Asserts()
@@ -4363,6 +6222,12 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("UpsertHealthSettings", s.Subtest(func(db database.Store, check *expects) {
 		check.Args("foo").Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate)
 	}))
+	s.Run("GetExperimentFlags", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts()
+	}))
+	s.Run("UpsertExperimentFlags", s.Subtest(func(db database.Store, check *expects) {
+		check.Args("foo").Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate)
+	}))
 	s.Run("GetNotificationsSettings", s.Subtest(func(db database.Store, check *expects) {
 		check.Args().Asserts()
 	}))
@@ -4384,6 +6249,9 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("GetProvisionerJobsToBeReaped", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.GetProvisionerJobsToBeReapedParams{}).Asserts(rbac.ResourceProvisionerJobs, policy.ActionRead)
 	}))
+	s.Run("GetOrphanedProvisionerJobs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetOrphanedProvisionerJobsParams{}).Asserts(rbac.ResourceProvisionerJobs, policy.ActionRead)
+	}))
 	s.Run("UpsertOAuthSigningKey", s.Subtest(func(db database.Store, check *expects) {
 		check.Args("foo").Asserts(rbac.ResourceSystem, policy.ActionUpdate)
 	}))
@@ -4451,6 +6319,9 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			ErrorsWithInMemDB(sql.ErrNoRows).
 			Returns([]database.UpdateInactiveUsersToDormantRow{})
 	}))
+	s.Run("GetTemplateAppRestrictionsByTemplateIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
 	s.Run("GetWorkspaceUniqueOwnerCountByTemplateIDs", s.Subtest(func(db database.Store, check *expects) {
 		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
@@ -4719,6 +6590,9 @@ func (s *MethodTestSuite) TestNotifications() {
 			Limit:  10,
 		}).Asserts(rbac.ResourceNotificationMessage, policy.ActionRead)
 	}))
+	s.Run("GetDueNotificationDigestMessages", s.Subtest(func(_ database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceNotificationMessage, policy.ActionRead)
+	}))
 
 	// webpush subscriptions
 	s.Run("GetWebpushSubscriptionsByUserID", s.Subtest(func(db database.Store, check *expects) {
@@ -4786,6 +6660,20 @@ func (s *MethodTestSuite) TestNotifications() {
 			Disableds:               []bool{true, false},
 		}).Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionUpdate)
 	}))
+	s.Run("GetUserNotificationCategoryPreferences", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		check.Args(user.ID).
+			Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionRead)
+	}))
+	s.Run("UpdateUserNotificationCategoryPreferences", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateUserNotificationCategoryPreferencesParams{
+			UserID:     user.ID,
+			Categories: []database.NotificationCategory{database.NotificationCategoryBuilds, database.NotificationCategoryDormancy},
+			Methods:    []database.NotificationMethod{database.NotificationMethodSmtp, database.NotificationMethodInbox},
+			Disableds:  []bool{true, false},
+		}).Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionUpdate)
+	}))
 
 	s.Run("GetInboxNotificationsByUserID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
@@ -4876,6 +6764,23 @@ func (s *MethodTestSuite) TestNotifications() {
 		check.Args(u.ID).Asserts(rbac.ResourceInboxNotification.WithOwner(u.ID.String()), policy.ActionRead).Returns(int64(1))
 	}))
 
+	s.Run("CountUnreadInboxNotificationsByCategory", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+
+		_ = dbgen.NotificationInbox(s.T(), db, database.InsertInboxNotificationParams{
+			ID:         uuid.New(),
+			UserID:     u.ID,
+			TemplateID: notifications.TemplateWorkspaceAutoUpdated,
+			Targets:    []uuid.UUID{u.ID, notifications.TemplateWorkspaceAutoUpdated},
+			Title:      "test title",
+			Content:    "test content notification",
+			Icon:       "https://coder.com/favicon.ico",
+			Actions:    json.RawMessage("{}"),
+		})
+
+		check.Args(u.ID).Asserts(rbac.ResourceInboxNotification.WithOwner(u.ID.String()), policy.ActionRead)
+	}))
+
 	s.Run("InsertInboxNotification", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 
@@ -4922,6 +6827,27 @@ func (s *MethodTestSuite) TestNotifications() {
 		}).Asserts(rbac.ResourceInboxNotification.WithID(notifID).WithOwner(u.ID.String()), policy.ActionUpdate)
 	}))
 
+	s.Run("UpdateInboxNotificationsReadStatus", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+
+		notif := dbgen.NotificationInbox(s.T(), db, database.InsertInboxNotificationParams{
+			ID:         uuid.New(),
+			UserID:     u.ID,
+			TemplateID: notifications.TemplateWorkspaceAutoUpdated,
+			Targets:    []uuid.UUID{u.ID, notifications.TemplateWorkspaceAutoUpdated},
+			Title:      "test title",
+			Content:    "test content notification",
+			Icon:       "https://coder.com/favicon.ico",
+			Actions:    json.RawMessage("{}"),
+		})
+
+		check.Args(database.UpdateInboxNotificationsReadStatusParams{
+			UserID: u.ID,
+			IDs:    []uuid.UUID{notif.ID},
+			ReadAt: sql.NullTime{Time: dbtestutil.NowInDefaultTimezone(), Valid: true},
+		}).Asserts(rbac.ResourceInboxNotification.WithOwner(u.ID.String()), policy.ActionUpdate).Returns(int64(1))
+	}))
+
 	s.Run("MarkAllInboxNotificationsAsRead", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 
@@ -5425,6 +7351,59 @@ func (s *MethodTestSuite) TestOAuth2ProviderAppCodes() {
 	}))
 }
 
+func (s *MethodTestSuite) TestOAuth2ProviderAppDeviceCodes() {
+	s.Run("GetOAuth2ProviderAppDeviceCodeByPrefix", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppDeviceCode(s.T(), db, database.OAuth2ProviderAppDeviceCode{
+			AppID: app.ID,
+		})
+		check.Args(code.DeviceCodePrefix).Asserts(code, policy.ActionRead).Returns(code)
+	}))
+	s.Run("GetOAuth2ProviderAppDeviceCodeByUserCode", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppDeviceCode(s.T(), db, database.OAuth2ProviderAppDeviceCode{
+			AppID:  app.ID,
+			UserID: uuid.NullUUID{UUID: user.ID, Valid: true},
+		})
+		check.Args(code.UserCode).Asserts(code, policy.ActionRead).Returns(code)
+	}))
+	s.Run("InsertOAuth2ProviderAppDeviceCode", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderAppDeviceCodeParams{
+			AppID: app.ID,
+		}).Asserts(rbac.ResourceOauth2AppCodeToken, policy.ActionCreate)
+	}))
+	s.Run("UpdateOAuth2ProviderAppDeviceCodeUserID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppDeviceCode(s.T(), db, database.OAuth2ProviderAppDeviceCode{
+			AppID: app.ID,
+		})
+		check.Args(database.UpdateOAuth2ProviderAppDeviceCodeUserIDParams{
+			ID:     code.ID,
+			UserID: uuid.NullUUID{UUID: user.ID, Valid: true},
+		}).Asserts(rbac.ResourceOauth2AppCodeToken.WithOwner(user.ID.String()), policy.ActionCreate)
+	}))
+	s.Run("UpdateOAuth2ProviderAppDeviceCodeLastPolledAt", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppDeviceCode(s.T(), db, database.OAuth2ProviderAppDeviceCode{
+			AppID: app.ID,
+		})
+		check.Args(database.UpdateOAuth2ProviderAppDeviceCodeLastPolledAtParams{
+			ID:           code.ID,
+			LastPolledAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("DeleteOAuth2ProviderAppDeviceCodeByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppDeviceCode(s.T(), db, database.OAuth2ProviderAppDeviceCode{
+			AppID: app.ID,
+		})
+		check.Args(code.ID).Asserts(rbac.ResourceOauth2AppCodeToken, policy.ActionDelete)
+	}))
+}
+
 func (s *MethodTestSuite) TestOAuth2ProviderAppTokens() {
 	s.Run("InsertOAuth2ProviderAppToken", s.Subtest(func(db database.Store, check *expects) {
 		user := dbgen.User(s.T(), db, database.User{})
@@ -5608,6 +7587,36 @@ func (s *MethodTestSuite) TestResourcesMonitor() {
 
 		check.Args(agt.ID).Asserts(w, policy.ActionRead).Returns(monitors)
 	}))
+
+	s.Run("UpsertWorkspaceAgentVolumeUsage", s.Subtest(func(db database.Store, check *expects) {
+		agt, _ := createAgent(s.T(), db)
+
+		check.Args(database.UpsertWorkspaceAgentVolumeUsageParams{
+			AgentID:     agt.ID,
+			Path:        "/home/coder",
+			TotalBytes:  100,
+			UsedBytes:   50,
+			CollectedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionUpdate)
+	}))
+
+	s.Run("GetWorkspaceAgentVolumeUsageByAgentID", s.Subtest(func(db database.Store, check *expects) {
+		agt, w := createAgent(s.T(), db)
+
+		err := db.UpsertWorkspaceAgentVolumeUsage(context.Background(), database.UpsertWorkspaceAgentVolumeUsageParams{
+			AgentID:     agt.ID,
+			Path:        "/home/coder",
+			TotalBytes:  100,
+			UsedBytes:   50,
+			CollectedAt: dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+
+		usage, err := db.GetWorkspaceAgentVolumeUsageByAgentID(context.Background(), agt.ID)
+		require.NoError(s.T(), err)
+
+		check.Args(agt.ID).Asserts(w, policy.ActionRead).Returns(usage)
+	}))
 }
 
 func (s *MethodTestSuite) TestResourcesProvisionerdserver() {
@@ -128,17 +128,19 @@ func APIKey(t testing.TB, db database.Store, seed database.APIKey) (key database
 	key, err := db.InsertAPIKey(genCtx, database.InsertAPIKeyParams{
 		ID: takeFirst(seed.ID, id),
 		// 0 defaults to 86400 at the db layer
-		LifetimeSeconds: takeFirst(seed.LifetimeSeconds, 0),
-		HashedSecret:    takeFirstSlice(seed.HashedSecret, hashed[:]),
-		IPAddress:       ip,
-		UserID:          takeFirst(seed.UserID, uuid.New()),
-		LastUsed:        takeFirst(seed.LastUsed, dbtime.Now()),
-		ExpiresAt:       takeFirst(seed.ExpiresAt, dbtime.Now().Add(time.Hour)),
-		CreatedAt:       takeFirst(seed.CreatedAt, dbtime.Now()),
-		UpdatedAt:       takeFirst(seed.UpdatedAt, dbtime.Now()),
-		LoginType:       takeFirst(seed.LoginType, database.LoginTypePassword),
-		Scope:           takeFirst(seed.Scope, database.APIKeyScopeAll),
-		TokenName:       takeFirst(seed.TokenName),
+		LifetimeSeconds:   takeFirst(seed.LifetimeSeconds, 0),
+		HashedSecret:      takeFirstSlice(seed.HashedSecret, hashed[:]),
+		IPAddress:         ip,
+		UserID:            takeFirst(seed.UserID, uuid.New()),
+		LastUsed:          takeFirst(seed.LastUsed, dbtime.Now()),
+		ExpiresAt:         takeFirst(seed.ExpiresAt, dbtime.Now().Add(time.Hour)),
+		CreatedAt:         takeFirst(seed.CreatedAt, dbtime.Now()),
+		UpdatedAt:         takeFirst(seed.UpdatedAt, dbtime.Now()),
+		LoginType:         takeFirst(seed.LoginType, database.LoginTypePassword),
+		Scope:             takeFirst(seed.Scope, database.APIKeyScopeAll),
+		TokenName:         takeFirst(seed.TokenName),
+		UserAgent:         takeFirst(seed.UserAgent),
+		DeviceFingerprint: takeFirst(seed.DeviceFingerprint),
 	})
 	require.NoError(t, err, "insert api key")
 	return key, fmt.Sprintf("%s-%s", key.ID, secret)
@@ -156,6 +158,24 @@ func WorkspaceAgentPortShare(t testing.TB, db database.Store, orig database.Work
 	return ps
 }
 
+func WorkspacePortShareLink(t testing.TB, db database.Store, orig database.WorkspacePortShareLink) database.WorkspacePortShareLink {
+	link, err := db.InsertWorkspacePortShareLink(genCtx, database.InsertWorkspacePortShareLinkParams{
+		ID:                  takeFirst(orig.ID, uuid.New()),
+		WorkspaceID:         takeFirst(orig.WorkspaceID, uuid.New()),
+		AgentName:           takeFirst(orig.AgentName, testutil.GetRandomName(t)),
+		Port:                takeFirst(orig.Port, 8080),
+		Protocol:            takeFirst(orig.Protocol, database.PortShareProtocolHttp),
+		HashedSecret:        takeFirstSlice(orig.HashedSecret, []byte("secret")),
+		HashedPassword:      orig.HashedPassword,
+		BandwidthLimitBytes: orig.BandwidthLimitBytes,
+		ExpiresAt:           takeFirst(orig.ExpiresAt, dbtime.Now().Add(time.Hour)),
+		CreatedBy:           takeFirst(orig.CreatedBy, uuid.New()),
+		CreatedAt:           takeFirst(orig.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert workspace port share link")
+	return link
+}
+
 func WorkspaceAgent(t testing.TB, db database.Store, orig database.WorkspaceAgent) database.WorkspaceAgent {
 	agt, err := db.InsertWorkspaceAgent(genCtx, database.InsertWorkspaceAgentParams{
 		ID:         takeFirst(orig.ID, uuid.New()),
@@ -357,6 +377,7 @@ func Workspace(t testing.TB, db database.Store, orig database.WorkspaceTable) da
 		Ttl:               orig.Ttl,
 		AutomaticUpdates:  takeFirst(orig.AutomaticUpdates, database.AutomaticUpdatesNever),
 		NextStartAt:       orig.NextStartAt,
+		Ephemeral:         orig.Ephemeral,
 	})
 	require.NoError(t, err, "insert workspace")
 	if orig.Deleted {
@@ -392,19 +413,22 @@ func WorkspaceBuild(t testing.TB, db database.Store, orig database.WorkspaceBuil
 	var build database.WorkspaceBuild
 	err := db.InTx(func(db database.Store) error {
 		err := db.InsertWorkspaceBuild(genCtx, database.InsertWorkspaceBuildParams{
-			ID:                buildID,
-			CreatedAt:         takeFirst(orig.CreatedAt, dbtime.Now()),
-			UpdatedAt:         takeFirst(orig.UpdatedAt, dbtime.Now()),
-			WorkspaceID:       takeFirst(orig.WorkspaceID, uuid.New()),
-			TemplateVersionID: takeFirst(orig.TemplateVersionID, uuid.New()),
-			BuildNumber:       takeFirst(orig.BuildNumber, 1),
-			Transition:        takeFirst(orig.Transition, database.WorkspaceTransitionStart),
-			InitiatorID:       takeFirst(orig.InitiatorID, uuid.New()),
-			JobID:             jobID,
-			ProvisionerState:  takeFirstSlice(orig.ProvisionerState, []byte{}),
-			Deadline:          takeFirst(orig.Deadline, dbtime.Now().Add(time.Hour)),
-			MaxDeadline:       takeFirst(orig.MaxDeadline, time.Time{}),
-			Reason:            takeFirst(orig.Reason, database.BuildReasonInitiator),
+			ID:                  buildID,
+			CreatedAt:           takeFirst(orig.CreatedAt, dbtime.Now()),
+			UpdatedAt:           takeFirst(orig.UpdatedAt, dbtime.Now()),
+			WorkspaceID:         takeFirst(orig.WorkspaceID, uuid.New()),
+			TemplateVersionID:   takeFirst(orig.TemplateVersionID, uuid.New()),
+			BuildNumber:         takeFirst(orig.BuildNumber, 1),
+			Transition:          takeFirst(orig.Transition, database.WorkspaceTransitionStart),
+			InitiatorID:         takeFirst(orig.InitiatorID, uuid.New()),
+			JobID:               jobID,
+			ProvisionerState:    takeFirstSlice(orig.ProvisionerState, []byte{}),
+			Deadline:            takeFirst(orig.Deadline, dbtime.Now().Add(time.Hour)),
+			MaxDeadline:         takeFirst(orig.MaxDeadline, time.Time{}),
+			Reason:              takeFirst(orig.Reason, database.BuildReasonInitiator),
+			ReasonMessage:       orig.ReasonMessage,
+			InitiatorAPIKeyName: orig.InitiatorAPIKeyName,
+			InitiatorCIJobURL:   orig.InitiatorCIJobURL,
 			TemplateVersionPresetID: takeFirst(orig.TemplateVersionPresetID, uuid.NullUUID{
 				UUID:  uuid.UUID{},
 				Valid: false,
@@ -848,6 +872,38 @@ func WorkspaceAppStat(t testing.TB, db database.Store, orig database.WorkspaceAp
 	return scheme
 }
 
+func WorkspaceArchive(t testing.TB, db database.Store, orig database.WorkspaceArchive) database.WorkspaceArchive {
+	id := takeFirst(orig.ID, uuid.New())
+	archive, err := db.InsertWorkspaceArchive(genCtx, database.InsertWorkspaceArchiveParams{
+		ID:             id,
+		WorkspaceID:    takeFirst(orig.WorkspaceID, uuid.New()),
+		WorkspaceName:  takeFirst(orig.WorkspaceName, testutil.GetRandomName(t)),
+		OwnerID:        takeFirst(orig.OwnerID, uuid.New()),
+		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
+		TemplateID:     takeFirst(orig.TemplateID, uuid.New()),
+		Paths:          takeFirstSlice(orig.Paths, []string{"/home/coder"}),
+		ObjectKey:      takeFirst(orig.ObjectKey, id.String()+".tar.gz"),
+		SizeBytes:      takeFirst(orig.SizeBytes, 1024),
+		Error:          orig.Error,
+		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
+		ExpiresAt:      takeFirst(orig.ExpiresAt, dbtime.Now().Add(30*24*time.Hour)),
+	})
+	require.NoError(t, err, "insert workspace archive")
+	return archive
+}
+
+func ReviewWorkspace(t testing.TB, db database.Store, orig database.ReviewWorkspace) database.ReviewWorkspace {
+	reviewWorkspace, err := db.UpsertReviewWorkspace(genCtx, database.UpsertReviewWorkspaceParams{
+		WorkspaceID:    takeFirst(orig.WorkspaceID, uuid.New()),
+		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
+		Repository:     takeFirst(orig.Repository, "coder/coder"),
+		Branch:         takeFirst(orig.Branch, testutil.GetRandomName(t)),
+		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert review workspace")
+	return reviewWorkspace
+}
+
 func WorkspaceResource(t testing.TB, db database.Store, orig database.WorkspaceResource) database.WorkspaceResource {
 	resource, err := db.InsertWorkspaceResource(genCtx, database.InsertWorkspaceResourceParams{
 		ID:         takeFirst(orig.ID, uuid.New()),
@@ -935,6 +991,7 @@ func File(t testing.TB, db database.Store, orig database.File) database.File {
 		CreatedBy: takeFirst(orig.CreatedBy, uuid.New()),
 		Mimetype:  takeFirst(orig.Mimetype, "application/x-tar"),
 		Data:      takeFirstSlice(orig.Data, []byte{}),
+		External:  orig.External,
 	})
 	require.NoError(t, err, "insert file")
 	return file
@@ -957,6 +1014,89 @@ func UserLink(t testing.TB, db database.Store, orig database.UserLink) database.
 	return link
 }
 
+func UserSecret(t testing.TB, db database.Store, orig database.UserSecret) database.UserSecret {
+	secret, err := db.InsertUserSecret(genCtx, database.InsertUserSecretParams{
+		ID:          takeFirst(orig.ID, uuid.New()),
+		UserID:      takeFirst(orig.UserID, uuid.New()),
+		Name:        takeFirst(orig.Name, testutil.GetRandomName(t)),
+		Description: takeFirst(orig.Description),
+		Value:       takeFirst(orig.Value, uuid.NewString()),
+		ValueKeyID:  takeFirst(orig.ValueKeyID, sql.NullString{}),
+		EnvName:     takeFirst(orig.EnvName),
+		CreatedAt:   takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:   takeFirst(orig.UpdatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert user secret")
+	return secret
+}
+
+func UserWebAuthnCredential(t testing.TB, db database.Store, orig database.UserWebAuthnCredential) database.UserWebAuthnCredential {
+	credential, err := db.InsertUserWebAuthnCredential(genCtx, database.InsertUserWebAuthnCredentialParams{
+		ID:           takeFirst(orig.ID, uuid.New()),
+		UserID:       takeFirst(orig.UserID, uuid.New()),
+		Name:         takeFirst(orig.Name, testutil.GetRandomName(t)),
+		CredentialID: takeFirstSlice(orig.CredentialID, []byte(uuid.NewString())),
+		PublicKey:    takeFirstSlice(orig.PublicKey, []byte("public-key")),
+		SignCount:    takeFirst(orig.SignCount),
+		CreatedAt:    takeFirst(orig.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert user webauthn credential")
+	return credential
+}
+
+func WebAuthnChallenge(t testing.TB, db database.Store, orig database.WebAuthnChallenge) database.WebAuthnChallenge {
+	challenge, err := db.InsertWebAuthnChallenge(genCtx, database.InsertWebAuthnChallengeParams{
+		ID:        takeFirst(orig.ID, uuid.New()),
+		UserID:    takeFirst(orig.UserID, uuid.New()),
+		Purpose:   takeFirst(orig.Purpose, database.WebAuthnChallengePurposeLogin),
+		Challenge: takeFirstSlice(orig.Challenge, []byte(uuid.NewString())),
+		CreatedAt: takeFirst(orig.CreatedAt, dbtime.Now()),
+		ExpiresAt: takeFirst(orig.ExpiresAt, dbtime.Now().Add(5*time.Minute)),
+	})
+	require.NoError(t, err, "insert webauthn challenge")
+	return challenge
+}
+
+func TemplateSecret(t testing.TB, db database.Store, orig database.TemplateSecret) database.TemplateSecret {
+	secret, err := db.InsertTemplateSecret(genCtx, database.InsertTemplateSecretParams{
+		ID:          takeFirst(orig.ID, uuid.New()),
+		TemplateID:  takeFirst(orig.TemplateID, uuid.New()),
+		Name:        takeFirst(orig.Name, testutil.GetRandomName(t)),
+		Description: takeFirst(orig.Description),
+		Value:       takeFirst(orig.Value, uuid.NewString()),
+		ValueKeyID:  takeFirst(orig.ValueKeyID, sql.NullString{}),
+		CreatedAt:   takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:   takeFirst(orig.UpdatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert template secret")
+	return secret
+}
+
+func VariableSet(t testing.TB, db database.Store, orig database.VariableSet) database.VariableSet {
+	set, err := db.InsertVariableSet(genCtx, database.InsertVariableSetParams{
+		ID:             takeFirst(orig.ID, uuid.New()),
+		Name:           takeFirst(orig.Name, testutil.GetRandomName(t)),
+		Description:    takeFirst(orig.Description),
+		Variables:      takeFirst(orig.Variables, "[]"),
+		VariablesKeyID: takeFirst(orig.VariablesKeyID, sql.NullString{}),
+		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:      takeFirst(orig.UpdatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert variable set")
+	return set
+}
+
+func TemplateEgressPolicy(t testing.TB, db database.Store, orig database.TemplateEgressPolicy) database.TemplateEgressPolicy {
+	egressPolicy, err := db.UpsertTemplateEgressPolicy(genCtx, database.UpsertTemplateEgressPolicyParams{
+		TemplateID:     takeFirst(orig.TemplateID, uuid.New()),
+		AllowedCidrs:   takeFirstSlice(orig.AllowedCidrs, []string{"10.0.0.0/8"}),
+		AllowedDomains: takeFirstSlice(orig.AllowedDomains, []string{"github.com"}),
+		UpdatedAt:      takeFirst(orig.UpdatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "upsert template egress policy")
+	return egressPolicy
+}
+
 func ExternalAuthLink(t testing.TB, db database.Store, orig database.ExternalAuthLink) database.ExternalAuthLink {
 	msg := takeFirst(&orig.OAuthExtra, &pqtype.NullRawMessage{})
 	link, err := db.InsertExternalAuthLink(genCtx, database.InsertExternalAuthLinkParams{
@@ -1212,6 +1352,28 @@ func OAuth2ProviderAppCode(t testing.TB, db database.Store, seed database.OAuth2
 	return code
 }
 
+func OAuth2ProviderAppDeviceCode(t testing.TB, db database.Store, seed database.OAuth2ProviderAppDeviceCode) database.OAuth2ProviderAppDeviceCode {
+	code, err := db.InsertOAuth2ProviderAppDeviceCode(genCtx, database.InsertOAuth2ProviderAppDeviceCodeParams{
+		ID:               takeFirst(seed.ID, uuid.New()),
+		CreatedAt:        takeFirst(seed.CreatedAt, dbtime.Now()),
+		ExpiresAt:        takeFirst(seed.ExpiresAt, dbtime.Now().Add(10*time.Minute)),
+		DeviceCodePrefix: takeFirstSlice(seed.DeviceCodePrefix, []byte("prefix")),
+		DeviceCodeHash:   takeFirstSlice(seed.DeviceCodeHash, []byte("hashed-secret")),
+		UserCode:         takeFirst(seed.UserCode, "BCDFGHJK"),
+		AppID:            takeFirst(seed.AppID, uuid.New()),
+		ResourceUri:      seed.ResourceUri,
+	})
+	require.NoError(t, err, "insert oauth2 app device code")
+	if seed.UserID.Valid {
+		code, err = db.UpdateOAuth2ProviderAppDeviceCodeUserID(genCtx, database.UpdateOAuth2ProviderAppDeviceCodeUserIDParams{
+			ID:     code.ID,
+			UserID: seed.UserID,
+		})
+		require.NoError(t, err, "approve oauth2 app device code")
+	}
+	return code
+}
+
 func OAuth2ProviderAppToken(t testing.TB, db database.Store, seed database.OAuth2ProviderAppToken) database.OAuth2ProviderAppToken {
 	token, err := db.InsertOAuth2ProviderAppToken(genCtx, database.InsertOAuth2ProviderAppTokenParams{
 		ID:          takeFirst(seed.ID, uuid.New()),
@@ -117,6 +117,13 @@ func (m queryMetricsStore) ActivityBumpWorkspace(ctx context.Context, arg databa
 	return r0
 }
 
+func (m queryMetricsStore) AddWorkspacePortShareLinkBytesTransferred(ctx context.Context, arg database.AddWorkspacePortShareLinkBytesTransferredParams) error {
+	start := time.Now()
+	r0 := m.s.AddWorkspacePortShareLinkBytesTransferred(ctx, arg)
+	m.queryLatencies.WithLabelValues("AddWorkspacePortShareLinkBytesTransferred").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid.UUID, error) {
 	start := time.Now()
 	r0, r1 := m.s.AllUserIDs(ctx, includeSystem)
@@ -159,6 +166,13 @@ func (m queryMetricsStore) BulkMarkNotificationMessagesSent(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) CancelPendingTemplateVersionActivationRequests(ctx context.Context, arg database.CancelPendingTemplateVersionActivationRequestsParams) error {
+	start := time.Now()
+	r0 := m.s.CancelPendingTemplateVersionActivationRequests(ctx, arg)
+	m.queryLatencies.WithLabelValues("CancelPendingTemplateVersionActivationRequests").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) ClaimPrebuiltWorkspace(ctx context.Context, arg database.ClaimPrebuiltWorkspaceParams) (database.ClaimPrebuiltWorkspaceRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.ClaimPrebuiltWorkspace(ctx, arg)
@@ -194,6 +208,13 @@ func (m queryMetricsStore) CountAuditLogs(ctx context.Context, arg database.Coun
 	return r0, r1
 }
 
+func (m queryMetricsStore) CountExternalFilesByHash(ctx context.Context, hash string) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.CountExternalFilesByHash(ctx, hash)
+	m.queryLatencies.WithLabelValues("CountExternalFilesByHash").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) CountInProgressPrebuilds(ctx context.Context) ([]database.CountInProgressPrebuildsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.CountInProgressPrebuilds(ctx)
@@ -201,6 +222,20 @@ func (m queryMetricsStore) CountInProgressPrebuilds(ctx context.Context) ([]data
 	return r0, r1
 }
 
+func (m queryMetricsStore) CountRunningAITasksByOwnerID(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.CountRunningAITasksByOwnerID(ctx, ownerID)
+	m.queryLatencies.WithLabelValues("CountRunningAITasksByOwnerID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) CountUnreadInboxNotificationsByCategory(ctx context.Context, userID uuid.UUID) ([]database.CountUnreadInboxNotificationsByCategoryRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.CountUnreadInboxNotificationsByCategory(ctx, userID)
+	m.queryLatencies.WithLabelValues("CountUnreadInboxNotificationsByCategory").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
 	start := time.Now()
 	r0, r1 := m.s.CountUnreadInboxNotificationsByUserID(ctx, userID)
@@ -215,6 +250,13 @@ func (m queryMetricsStore) CustomRoles(ctx context.Context, arg database.CustomR
 	return r0, r1
 }
 
+func (m queryMetricsStore) DeleteAITaskQueueEntryByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteAITaskQueueEntryByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteAITaskQueueEntryByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteAPIKeyByID(ctx context.Context, id string) error {
 	start := time.Now()
 	err := m.s.DeleteAPIKeyByID(ctx, id)
@@ -229,6 +271,13 @@ func (m queryMetricsStore) DeleteAPIKeysByUserID(ctx context.Context, userID uui
 	return err
 }
 
+func (m queryMetricsStore) DeleteAPIKeysByUserIDLoginType(ctx context.Context, arg database.DeleteAPIKeysByUserIDLoginTypeParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteAPIKeysByUserIDLoginType(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteAPIKeysByUserIDLoginType").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteAllTailnetClientSubscriptions(ctx context.Context, arg database.DeleteAllTailnetClientSubscriptionsParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteAllTailnetClientSubscriptions(ctx, arg)
@@ -257,6 +306,13 @@ func (m queryMetricsStore) DeleteApplicationConnectAPIKeysByUserID(ctx context.C
 	return err
 }
 
+func (m queryMetricsStore) DeleteAuditLogLegalHoldByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteAuditLogLegalHoldByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteAuditLogLegalHoldByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteCoordinator(ctx, id)
@@ -278,6 +334,27 @@ func (m queryMetricsStore) DeleteCustomRole(ctx context.Context, arg database.De
 	return r0
 }
 
+func (m queryMetricsStore) DeleteExcessAPIKeySessionsByUserID(ctx context.Context, arg database.DeleteExcessAPIKeySessionsByUserIDParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteExcessAPIKeySessionsByUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteExcessAPIKeySessionsByUserID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteExpiredWebAuthnChallenges(ctx context.Context, now time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteExpiredWebAuthnChallenges(ctx, now)
+	m.queryLatencies.WithLabelValues("DeleteExpiredWebAuthnChallenges").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteExpiredWorkspacePortShareLinks(ctx context.Context, now time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteExpiredWorkspacePortShareLinks(ctx, now)
+	m.queryLatencies.WithLabelValues("DeleteExpiredWorkspacePortShareLinks").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteExternalAuthLink(ctx, arg)
@@ -306,6 +383,13 @@ func (m queryMetricsStore) DeleteGroupMemberFromGroup(ctx context.Context, arg d
 	return err
 }
 
+func (m queryMetricsStore) DeleteGroupQuotaAllowances(ctx context.Context, groupID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteGroupQuotaAllowances(ctx, groupID)
+	m.queryLatencies.WithLabelValues("DeleteGroupQuotaAllowances").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	start := time.Now()
 	licenseID, err := m.s.DeleteLicense(ctx, id)
@@ -313,6 +397,20 @@ func (m queryMetricsStore) DeleteLicense(ctx context.Context, id int32) (int32,
 	return licenseID, err
 }
 
+func (m queryMetricsStore) DeleteLicenseSeatReservation(ctx context.Context, groupID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteLicenseSeatReservation(ctx, groupID)
+	m.queryLatencies.WithLabelValues("DeleteLicenseSeatReservation").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteNetworkConnectionQuality(ctx context.Context, arg database.DeleteNetworkConnectionQualityParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteNetworkConnectionQuality(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteNetworkConnectionQuality").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteOAuth2ProviderAppByClientID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteOAuth2ProviderAppByClientID(ctx, id)
@@ -341,6 +439,13 @@ func (m queryMetricsStore) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx contex
 	return r0
 }
 
+func (m queryMetricsStore) DeleteOAuth2ProviderAppDeviceCodeByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteOAuth2ProviderAppDeviceCodeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppDeviceCodeByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteOAuth2ProviderAppSecretByID(ctx, id)
@@ -355,6 +460,27 @@ func (m queryMetricsStore) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx conte
 	return r0
 }
 
+func (m queryMetricsStore) DeleteOldAuditLogs(ctx context.Context, arg database.DeleteOldAuditLogsParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteOldAuditLogs(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOldAuditLogs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) DeleteOldHealthScores(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldHealthScores(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteOldHealthScores").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteOldNetworkConnectionQuality(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldNetworkConnectionQuality(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteOldNetworkConnectionQuality").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteOldNotificationMessages(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DeleteOldNotificationMessages(ctx)
@@ -411,6 +537,27 @@ func (m queryMetricsStore) DeleteRuntimeConfig(ctx context.Context, key string)
 	return r0
 }
 
+func (m queryMetricsStore) DeleteSCIMGroupMembers(ctx context.Context, arg database.DeleteSCIMGroupMembersParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteSCIMGroupMembers(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteSCIMGroupMembers").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteSCIMNestedGroups(ctx context.Context, arg database.DeleteSCIMNestedGroupsParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteSCIMNestedGroups(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteSCIMNestedGroups").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteStaleAPIKeySessionsByUserID(ctx context.Context, arg database.DeleteStaleAPIKeySessionsByUserIDParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteStaleAPIKeySessionsByUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteStaleAPIKeySessionsByUserID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteTailnetAgent(ctx context.Context, arg database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.DeleteTailnetAgent(ctx, arg)
@@ -446,6 +593,104 @@ func (m queryMetricsStore) DeleteTailnetTunnel(ctx context.Context, arg database
 	return r0, r1
 }
 
+func (m queryMetricsStore) DeleteTemplateAppRestriction(ctx context.Context, arg database.DeleteTemplateAppRestrictionParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplateAppRestriction(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteTemplateAppRestriction").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteTemplateAppSessionPolicy(ctx context.Context, arg database.DeleteTemplateAppSessionPolicyParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplateAppSessionPolicy(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteTemplateAppSessionPolicy").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteTemplateBundleByName(ctx context.Context, arg database.DeleteTemplateBundleByNameParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplateBundleByName(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteTemplateBundleByName").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteTemplateEgressPolicy(ctx context.Context, templateID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplateEgressPolicy(ctx, templateID)
+	m.queryLatencies.WithLabelValues("DeleteTemplateEgressPolicy").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteTemplateParameterOptionSource(ctx context.Context, arg database.DeleteTemplateParameterOptionSourceParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplateParameterOptionSource(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteTemplateParameterOptionSource").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteTemplateSecret(ctx context.Context, arg database.DeleteTemplateSecretParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplateSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteTemplateSecret").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteTemplateVariableSet(ctx context.Context, arg database.DeleteTemplateVariableSetParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplateVariableSet(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteTemplateVariableSet").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteTemplateVersionActivationReviews(ctx context.Context, templateVersionID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplateVersionActivationReviews(ctx, templateVersionID)
+	m.queryLatencies.WithLabelValues("DeleteTemplateVersionActivationReviews").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteUnreferencedExternalFiles(ctx context.Context, createdBefore time.Time) ([]string, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteUnreferencedExternalFiles(ctx, createdBefore)
+	m.queryLatencies.WithLabelValues("DeleteUnreferencedExternalFiles").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) DeleteUserMFARecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserMFARecoveryCodes(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserMFARecoveryCodes").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteUserSecret(ctx context.Context, arg database.DeleteUserSecretParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteUserSecret").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteUserWebAuthnCredential(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserWebAuthnCredential(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteUserWebAuthnCredential").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteVariableSet(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteVariableSet(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteVariableSet").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWebAuthnChallengeByID(ctx context.Context, id uuid.UUID) (database.WebAuthnChallenge, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteWebAuthnChallengeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWebAuthnChallengeByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx, arg)
@@ -474,6 +719,41 @@ func (m queryMetricsStore) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.
 	return r0
 }
 
+func (m queryMetricsStore) DeleteWorkspaceArchiveByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceArchiveByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceArchiveByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspaceLabelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceLabelsByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceLabelsByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspacePortShareLink(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspacePortShareLink(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWorkspacePortShareLink").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspacePortShareLinksByTemplate(ctx context.Context, templateID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspacePortShareLinksByTemplate(ctx, templateID)
+	m.queryLatencies.WithLabelValues("DeleteWorkspacePortShareLinksByTemplate").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspaceSchedulePause(ctx context.Context, workspaceID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceSchedulePause(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceSchedulePause").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteWorkspaceSubAgentByID(ctx, id)
@@ -537,6 +817,34 @@ func (m queryMetricsStore) FetchVolumesResourceMonitorsUpdatedAfter(ctx context.
 	return r0, r1
 }
 
+func (m queryMetricsStore) FinishTemplateVersionRollout(ctx context.Context, arg database.FinishTemplateVersionRolloutParams) error {
+	start := time.Now()
+	r0 := m.s.FinishTemplateVersionRollout(ctx, arg)
+	m.queryLatencies.WithLabelValues("FinishTemplateVersionRollout").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) GetAITaskQueueEntriesByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]database.AITaskQueue, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAITaskQueueEntriesByOwnerID(ctx, ownerID)
+	m.queryLatencies.WithLabelValues("GetAITaskQueueEntriesByOwnerID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetAITaskQueueEntryByID(ctx context.Context, id uuid.UUID) (database.AITaskQueue, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAITaskQueueEntryByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetAITaskQueueEntryByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetAITaskQueueOwnerIDs(ctx context.Context) ([]uuid.UUID, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAITaskQueueOwnerIDs(ctx)
+	m.queryLatencies.WithLabelValues("GetAITaskQueueOwnerIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	start := time.Now()
 	apiKey, err := m.s.GetAPIKeyByID(ctx, id)
@@ -551,6 +859,13 @@ func (m queryMetricsStore) GetAPIKeyByName(ctx context.Context, arg database.Get
 	return apiKey, err
 }
 
+func (m queryMetricsStore) GetAPIKeySessionsByUserID(ctx context.Context, arg database.GetAPIKeySessionsByUserIDParams) ([]database.APIKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAPIKeySessionsByUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetAPIKeySessionsByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetAPIKeysByLoginType(ctx context.Context, loginType database.LoginType) ([]database.APIKey, error) {
 	start := time.Now()
 	apiKeys, err := m.s.GetAPIKeysByLoginType(ctx, loginType)
@@ -593,6 +908,13 @@ func (m queryMetricsStore) GetActiveWorkspaceBuildsByTemplateID(ctx context.Cont
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetActivityEvents(ctx context.Context, arg database.GetActivityEventsParams) ([]database.GetActivityEventsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetActivityEvents(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetActivityEvents").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetAllTailnetAgents(ctx context.Context) ([]database.TailnetAgent, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetAllTailnetAgents(ctx)
@@ -642,6 +964,20 @@ func (m queryMetricsStore) GetApplicationName(ctx context.Context) (string, erro
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetAuditLogLegalHoldByID(ctx context.Context, id uuid.UUID) (database.AuditLogLegalHold, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuditLogLegalHoldByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetAuditLogLegalHoldByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetAuditLogLegalHoldsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.AuditLogLegalHold, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuditLogLegalHoldsByOrganizationID(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetAuditLogLegalHoldsByOrganizationID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	start := time.Now()
 	rows, err := m.s.GetAuditLogsOffset(ctx, arg)
@@ -747,6 +1083,13 @@ func (m queryMetricsStore) GetDeploymentWorkspaceStats(ctx context.Context) (dat
 	return row, err
 }
 
+func (m queryMetricsStore) GetDueNotificationDigestMessages(ctx context.Context, now time.Time) ([]database.GetDueNotificationDigestMessagesRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetDueNotificationDigestMessages(ctx, now)
+	m.queryLatencies.WithLabelValues("GetDueNotificationDigestMessages").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetEligibleProvisionerDaemonsByProvisionerJobIDs(ctx context.Context, provisionerJobIds []uuid.UUID) ([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetEligibleProvisionerDaemonsByProvisionerJobIDs(ctx, provisionerJobIds)
@@ -754,6 +1097,13 @@ func (m queryMetricsStore) GetEligibleProvisionerDaemonsByProvisionerJobIDs(ctx
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetExperimentFlags(ctx context.Context) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetExperimentFlags(ctx)
+	m.queryLatencies.WithLabelValues("GetExperimentFlags").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetExternalAuthLink(ctx context.Context, arg database.GetExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	start := time.Now()
 	link, err := m.s.GetExternalAuthLink(ctx, arg)
@@ -852,6 +1202,20 @@ func (m queryMetricsStore) GetGroupMembersCountByGroupID(ctx context.Context, ar
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetGroupQuotaAllowances(ctx context.Context, groupID uuid.UUID) ([]database.GroupQuotaAllowance, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetGroupQuotaAllowances(ctx, groupID)
+	m.queryLatencies.WithLabelValues("GetGroupQuotaAllowances").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetGroupWorkspaceUsage(ctx context.Context, arg database.GetGroupWorkspaceUsageParams) ([]database.GetGroupWorkspaceUsageRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetGroupWorkspaceUsage(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetGroupWorkspaceUsage").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetGroups(ctx context.Context, arg database.GetGroupsParams) ([]database.GetGroupsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetGroups(ctx, arg)
@@ -859,6 +1223,13 @@ func (m queryMetricsStore) GetGroups(ctx context.Context, arg database.GetGroups
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetHealthScores(ctx context.Context, arg database.GetHealthScoresParams) ([]database.HealthScore, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetHealthScores(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetHealthScores").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetHealthSettings(ctx context.Context) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetHealthSettings(ctx)
@@ -880,6 +1251,20 @@ func (m queryMetricsStore) GetInboxNotificationsByUserID(ctx context.Context, us
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetInternalCA(ctx context.Context) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetInternalCA(ctx)
+	m.queryLatencies.WithLabelValues("GetInternalCA").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetLDAPUsers(ctx context.Context) ([]database.LDAPUser, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLDAPUsers(ctx)
+	m.queryLatencies.WithLabelValues("GetLDAPUsers").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetLastUpdateCheck(ctx context.Context) (string, error) {
 	start := time.Now()
 	version, err := m.s.GetLastUpdateCheck(ctx)
@@ -901,6 +1286,13 @@ func (m queryMetricsStore) GetLatestWorkspaceAppStatusesByWorkspaceIDs(ctx conte
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetLatestWorkspaceArchiveByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceArchive, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLatestWorkspaceArchiveByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetLatestWorkspaceArchiveByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
 	start := time.Now()
 	build, err := m.s.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspaceID)
@@ -929,6 +1321,13 @@ func (m queryMetricsStore) GetLicenseByID(ctx context.Context, id int32) (databa
 	return license, err
 }
 
+func (m queryMetricsStore) GetLicenseSeatReservations(ctx context.Context) ([]database.GetLicenseSeatReservationsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLicenseSeatReservations(ctx)
+	m.queryLatencies.WithLabelValues("GetLicenseSeatReservations").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetLicenses(ctx context.Context) ([]database.License, error) {
 	start := time.Now()
 	licenses, err := m.s.GetLicenses(ctx)
@@ -943,6 +1342,27 @@ func (m queryMetricsStore) GetLogoURL(ctx context.Context) (string, error) {
 	return url, err
 }
 
+func (m queryMetricsStore) GetNetworkConnectionQuality(ctx context.Context, arg database.GetNetworkConnectionQualityParams) (database.NetworkConnectionQuality, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNetworkConnectionQuality(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetNetworkConnectionQuality").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetNetworkConnectionQualityCounters(ctx context.Context) ([]database.NetworkConnectionQualityCounter, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNetworkConnectionQualityCounters(ctx)
+	m.queryLatencies.WithLabelValues("GetNetworkConnectionQualityCounters").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetNetworkConnectionQualitySummary(ctx context.Context, updatedAfter time.Time) ([]database.GetNetworkConnectionQualitySummaryRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNetworkConnectionQualitySummary(ctx, updatedAfter)
+	m.queryLatencies.WithLabelValues("GetNetworkConnectionQualitySummary").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetNotificationMessagesByStatus(ctx context.Context, arg database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetNotificationMessagesByStatus(ctx, arg)
@@ -1020,6 +1440,20 @@ func (m queryMetricsStore) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetOAuth2ProviderAppDeviceCodeByPrefix(ctx context.Context, deviceCodePrefix []byte) (database.OAuth2ProviderAppDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppDeviceCodeByPrefix(ctx, deviceCodePrefix)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppDeviceCodeByPrefix").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetOAuth2ProviderAppDeviceCodeByUserCode(ctx context.Context, userCode string) (database.OAuth2ProviderAppDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppDeviceCodeByUserCode(ctx, userCode)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppDeviceCodeByUserCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderAppSecretByID(ctx, id)
@@ -1076,6 +1510,13 @@ func (m queryMetricsStore) GetOAuthSigningKey(ctx context.Context) (string, erro
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetOrganizationAuditSettings(ctx context.Context, organizationID uuid.UUID) (database.OrganizationAuditSetting, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOrganizationAuditSettings(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetOrganizationAuditSettings").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetOrganizationByID(ctx context.Context, id uuid.UUID) (database.Organization, error) {
 	start := time.Now()
 	organization, err := m.s.GetOrganizationByID(ctx, id)
@@ -1097,6 +1538,13 @@ func (m queryMetricsStore) GetOrganizationIDsByMemberIDs(ctx context.Context, id
 	return organizations, err
 }
 
+func (m queryMetricsStore) GetOrganizationMFASettings(ctx context.Context, organizationID uuid.UUID) (database.OrganizationMFASetting, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOrganizationMFASettings(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetOrganizationMFASettings").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetOrganizationResourceCountByID(ctx context.Context, organizationID uuid.UUID) (database.GetOrganizationResourceCountByIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOrganizationResourceCountByID(ctx, organizationID)
@@ -1104,6 +1552,13 @@ func (m queryMetricsStore) GetOrganizationResourceCountByID(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetOrganizationSettingOverrides(ctx context.Context, organizationID uuid.UUID) (database.OrganizationSettingOverride, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOrganizationSettingOverrides(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetOrganizationSettingOverrides").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetOrganizations(ctx context.Context, args database.GetOrganizationsParams) ([]database.Organization, error) {
 	start := time.Now()
 	organizations, err := m.s.GetOrganizations(ctx, args)
@@ -1118,6 +1573,13 @@ func (m queryMetricsStore) GetOrganizationsByUserID(ctx context.Context, userID
 	return organizations, err
 }
 
+func (m queryMetricsStore) GetOrphanedProvisionerJobs(ctx context.Context, arg database.GetOrphanedProvisionerJobsParams) ([]database.GetOrphanedProvisionerJobsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOrphanedProvisionerJobs(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetOrphanedProvisionerJobs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
 	start := time.Now()
 	schemas, err := m.s.GetParameterSchemasByJobID(ctx, jobID)
@@ -1125,6 +1587,13 @@ func (m queryMetricsStore) GetParameterSchemasByJobID(ctx context.Context, jobID
 	return schemas, err
 }
 
+func (m queryMetricsStore) GetPendingProvisionerJobsCount(ctx context.Context) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPendingProvisionerJobsCount(ctx)
+	m.queryLatencies.WithLabelValues("GetPendingProvisionerJobsCount").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetPrebuildMetrics(ctx context.Context) ([]database.GetPrebuildMetricsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetPrebuildMetrics(ctx)
@@ -1230,6 +1699,13 @@ func (m queryMetricsStore) GetProvisionerJobByIDForUpdate(ctx context.Context, i
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetProvisionerJobCountsByOrganization(ctx context.Context) ([]database.GetProvisionerJobCountsByOrganizationRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobCountsByOrganization(ctx)
+	m.queryLatencies.WithLabelValues("GetProvisionerJobCountsByOrganization").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerJobTimingsByJobID(ctx, jobID)
@@ -1314,6 +1790,27 @@ func (m queryMetricsStore) GetQuotaConsumedForUser(ctx context.Context, ownerID
 	return consumed, err
 }
 
+func (m queryMetricsStore) GetQuotaDimensionAllowancesForUser(ctx context.Context, arg database.GetQuotaDimensionAllowancesForUserParams) ([]database.GetQuotaDimensionAllowancesForUserRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetQuotaDimensionAllowancesForUser(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetQuotaDimensionAllowancesForUser").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetQuotaDimensionsConsumedForGroup(ctx context.Context, groupID uuid.UUID) ([]database.GetQuotaDimensionsConsumedForGroupRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetQuotaDimensionsConsumedForGroup(ctx, groupID)
+	m.queryLatencies.WithLabelValues("GetQuotaDimensionsConsumedForGroup").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetQuotaDimensionsConsumedForUser(ctx context.Context, arg database.GetQuotaDimensionsConsumedForUserParams) ([]database.GetQuotaDimensionsConsumedForUserRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetQuotaDimensionsConsumedForUser(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetQuotaDimensionsConsumedForUser").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetReplicaByID(ctx context.Context, id uuid.UUID) (database.Replica, error) {
 	start := time.Now()
 	replica, err := m.s.GetReplicaByID(ctx, id)
@@ -1328,6 +1825,27 @@ func (m queryMetricsStore) GetReplicasUpdatedAfter(ctx context.Context, updatedA
 	return replicas, err
 }
 
+func (m queryMetricsStore) GetReviewWorkspace(ctx context.Context, arg database.GetReviewWorkspaceParams) (database.ReviewWorkspace, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetReviewWorkspace(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetReviewWorkspace").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetReviewWorkspacesByRepositoryAndBranch(ctx context.Context, arg database.GetReviewWorkspacesByRepositoryAndBranchParams) ([]database.ReviewWorkspace, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetReviewWorkspacesByRepositoryAndBranch(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetReviewWorkspacesByRepositoryAndBranch").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetRollbackWorkspaceBuild(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetRollbackWorkspaceBuild(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetRollbackWorkspaceBuild").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetRunningPrebuiltWorkspaces(ctx context.Context) ([]database.GetRunningPrebuiltWorkspacesRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetRunningPrebuiltWorkspaces(ctx)
@@ -1349,6 +1867,20 @@ func (m queryMetricsStore) GetRuntimeConfig(ctx context.Context, key string) (st
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetSCIMGroupMembers(ctx context.Context, organizationID uuid.UUID) ([]database.SCIMGroupMember, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSCIMGroupMembers(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetSCIMGroupMembers").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetSCIMNestedGroups(ctx context.Context, organizationID uuid.UUID) ([]database.SCIMNestedGroup, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSCIMNestedGroups(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetSCIMNestedGroups").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]database.TailnetAgent, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTailnetAgents(ctx, id)
@@ -1398,6 +1930,20 @@ func (m queryMetricsStore) GetTelemetryItems(ctx context.Context) ([]database.Te
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplateActivationForUpdate(ctx context.Context, id uuid.UUID) (database.GetTemplateActivationForUpdateRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateActivationForUpdate(ctx, id)
+	m.queryLatencies.WithLabelValues("GetTemplateActivationForUpdate").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateActivationReviewers(ctx context.Context, templateID uuid.UUID) ([]database.GetTemplateActivationReviewersRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateActivationReviewers(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateActivationReviewers").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateAppInsights(ctx, arg)
@@ -1412,6 +1958,27 @@ func (m queryMetricsStore) GetTemplateAppInsightsByTemplate(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplateAppRestrictions(ctx context.Context, templateID uuid.UUID) ([]database.TemplateAppRestriction, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateAppRestrictions(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateAppRestrictions").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateAppRestrictionsByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.TemplateAppRestriction, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateAppRestrictionsByTemplateIDs(ctx, templateIds)
+	m.queryLatencies.WithLabelValues("GetTemplateAppRestrictionsByTemplateIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateAppSessionPolicies(ctx context.Context, templateID uuid.UUID) ([]database.TemplateAppSessionPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateAppSessionPolicies(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateAppSessionPolicies").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateAverageBuildTime(ctx context.Context, arg database.GetTemplateAverageBuildTimeParams) (database.GetTemplateAverageBuildTimeRow, error) {
 	start := time.Now()
 	buildTime, err := m.s.GetTemplateAverageBuildTime(ctx, arg)
@@ -1419,6 +1986,41 @@ func (m queryMetricsStore) GetTemplateAverageBuildTime(ctx context.Context, arg
 	return buildTime, err
 }
 
+func (m queryMetricsStore) GetTemplateBuildFailures(ctx context.Context, arg database.GetTemplateBuildFailuresParams) ([]database.GetTemplateBuildFailuresRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateBuildFailures(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateBuildFailures").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateBuildInsightsByInterval(ctx context.Context, arg database.GetTemplateBuildInsightsByIntervalParams) ([]database.GetTemplateBuildInsightsByIntervalRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateBuildInsightsByInterval(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateBuildInsightsByInterval").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateBuildStageDurations(ctx context.Context, arg database.GetTemplateBuildStageDurationsParams) ([]database.GetTemplateBuildStageDurationsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateBuildStageDurations(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateBuildStageDurations").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateBundleByName(ctx context.Context, arg database.GetTemplateBundleByNameParams) (database.TemplateBundle, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateBundleByName(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateBundleByName").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateBundlesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.GetTemplateBundlesByOrganizationIDRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateBundlesByOrganizationID(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetTemplateBundlesByOrganizationID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateByID(ctx context.Context, id uuid.UUID) (database.Template, error) {
 	start := time.Now()
 	template, err := m.s.GetTemplateByID(ctx, id)
@@ -1440,6 +2042,13 @@ func (m queryMetricsStore) GetTemplateDAUs(ctx context.Context, arg database.Get
 	return daus, err
 }
 
+func (m queryMetricsStore) GetTemplateEgressPolicyByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateEgressPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateEgressPolicyByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateEgressPolicyByTemplateID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateInsights(ctx context.Context, arg database.GetTemplateInsightsParams) (database.GetTemplateInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateInsights(ctx, arg)
@@ -1468,6 +2077,20 @@ func (m queryMetricsStore) GetTemplateParameterInsights(ctx context.Context, arg
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplateParameterOptionSource(ctx context.Context, arg database.GetTemplateParameterOptionSourceParams) (database.TemplateParameterOptionSource, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateParameterOptionSource(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateParameterOptionSource").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateParameterOptionSources(ctx context.Context, templateID uuid.UUID) ([]database.TemplateParameterOptionSource, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateParameterOptionSources(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateParameterOptionSources").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplatePresetsWithPrebuilds(ctx context.Context, templateID uuid.NullUUID) ([]database.GetTemplatePresetsWithPrebuildsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplatePresetsWithPrebuilds(ctx, templateID)
@@ -1475,6 +2098,20 @@ func (m queryMetricsStore) GetTemplatePresetsWithPrebuilds(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplateSecretByTemplateIDAndName(ctx context.Context, arg database.GetTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateSecretByTemplateIDAndName(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateSecretByTemplateIDAndName").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateSecretsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateSecretsByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateSecretsByTemplateID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateUsageStats(ctx, arg)
@@ -1482,6 +2119,20 @@ func (m queryMetricsStore) GetTemplateUsageStats(ctx context.Context, arg databa
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplateVersionActivationRequestByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) (database.GetTemplateVersionActivationRequestByTemplateVersionIDRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionActivationRequestByTemplateVersionID(ctx, templateVersionID)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionActivationRequestByTemplateVersionID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateVersionActivationReviews(ctx context.Context, templateVersionID uuid.UUID) ([]database.GetTemplateVersionActivationReviewsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionActivationReviews(ctx, templateVersionID)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionActivationReviews").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (database.TemplateVersion, error) {
 	start := time.Now()
 	version, err := m.s.GetTemplateVersionByID(ctx, id)
@@ -1510,6 +2161,27 @@ func (m queryMetricsStore) GetTemplateVersionParameters(ctx context.Context, tem
 	return parameters, err
 }
 
+func (m queryMetricsStore) GetTemplateVersionRolloutBuildStats(ctx context.Context, templateID uuid.UUID) (database.GetTemplateVersionRolloutBuildStatsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionRolloutBuildStats(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionRolloutBuildStats").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateVersionRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionRolloutByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionRolloutByTemplateID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateVersionRolloutTargetsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetTemplateVersionRolloutTargetsByWorkspaceIDsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionRolloutTargetsByWorkspaceIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionRolloutTargetsByWorkspaceIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (database.TemplateVersionTerraformValue, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateVersionTerraformValues(ctx, templateVersionID)
@@ -1594,6 +2266,13 @@ func (m queryMetricsStore) GetUserByID(ctx context.Context, id uuid.UUID) (datab
 	return user, err
 }
 
+func (m queryMetricsStore) GetUserCLIVersions(ctx context.Context, seenAfter time.Time) ([]database.GetUserCLIVersionsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserCLIVersions(ctx, seenAfter)
+	m.queryLatencies.WithLabelValues("GetUserCLIVersions").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUserCount(ctx context.Context, includeSystem bool) (int64, error) {
 	start := time.Now()
 	count, err := m.s.GetUserCount(ctx, includeSystem)
@@ -1629,6 +2308,20 @@ func (m queryMetricsStore) GetUserLinksByUserID(ctx context.Context, userID uuid
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetUserNotificationCategoryPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationCategoryPreference, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserNotificationCategoryPreferences(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserNotificationCategoryPreferences").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserNotificationDigest(ctx context.Context, userID uuid.UUID) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserNotificationDigest(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserNotificationDigest").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserNotificationPreferences(ctx, userID)
@@ -1636,6 +2329,41 @@ func (m queryMetricsStore) GetUserNotificationPreferences(ctx context.Context, u
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetUserProxyLatencies(ctx context.Context, userID uuid.UUID) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserProxyLatencies(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserProxyLatencies").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserProxyPreference(ctx context.Context, userID uuid.UUID) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserProxyPreference(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserProxyPreference").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserSecretByUserIDAndName(ctx context.Context, arg database.GetUserSecretByUserIDAndNameParams) (database.UserSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserSecretByUserIDAndName(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUserSecretByUserIDAndName").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserSecretsByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserSecretsByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserSecretsByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserStartOnConnect(ctx context.Context, userID uuid.UUID) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserStartOnConnect(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserStartOnConnect").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUserStatusCounts(ctx context.Context, arg database.GetUserStatusCountsParams) ([]database.GetUserStatusCountsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserStatusCounts(ctx, arg)
@@ -1657,6 +2385,34 @@ func (m queryMetricsStore) GetUserThemePreference(ctx context.Context, userID uu
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetUserWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (database.UserWebAuthnCredential, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserWebAuthnCredentialByCredentialID(ctx, credentialID)
+	m.queryLatencies.WithLabelValues("GetUserWebAuthnCredentialByCredentialID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserWebAuthnCredentialByID(ctx context.Context, id uuid.UUID) (database.UserWebAuthnCredential, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserWebAuthnCredentialByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetUserWebAuthnCredentialByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserWebAuthnCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserWebAuthnCredential, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserWebAuthnCredentialsByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserWebAuthnCredentialsByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUserWebAuthnRequired(ctx context.Context, userID uuid.UUID) (bool, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserWebAuthnRequired(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserWebAuthnRequired").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUserWorkspaceBuildParameters(ctx context.Context, ownerID database.GetUserWorkspaceBuildParametersParams) ([]database.GetUserWorkspaceBuildParametersRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserWorkspaceBuildParameters(ctx, ownerID)
@@ -1664,6 +2420,13 @@ func (m queryMetricsStore) GetUserWorkspaceBuildParameters(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetUserWorkspaceUsage(ctx context.Context, arg database.GetUserWorkspaceUsageParams) ([]database.GetUserWorkspaceUsageRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserWorkspaceUsage(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUserWorkspaceUsage").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUsers(ctx context.Context, arg database.GetUsersParams) ([]database.GetUsersRow, error) {
 	start := time.Now()
 	users, err := m.s.GetUsers(ctx, arg)
@@ -1678,6 +2441,27 @@ func (m queryMetricsStore) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) (
 	return users, err
 }
 
+func (m queryMetricsStore) GetVariableSetByName(ctx context.Context, name string) (database.VariableSet, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetVariableSetByName(ctx, name)
+	m.queryLatencies.WithLabelValues("GetVariableSetByName").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetVariableSets(ctx context.Context) ([]database.VariableSet, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetVariableSets(ctx)
+	m.queryLatencies.WithLabelValues("GetVariableSets").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetVariableSetsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.VariableSet, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetVariableSetsByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetVariableSetsByTemplateID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWebpushSubscriptionsByUserID(ctx, userID)
@@ -1692,6 +2476,20 @@ func (m queryMetricsStore) GetWebpushVAPIDKeys(ctx context.Context) (database.Ge
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceAdoptionByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAdoption, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAdoptionByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAdoptionByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceAdoptionByWorkspaceID(ctx context.Context, workspaceID uuid.NullUUID) (database.WorkspaceAdoption, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAdoptionByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAdoptionByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Context, authToken uuid.UUID) (database.GetWorkspaceAgentAndLatestBuildByAuthTokenRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentAndLatestBuildByAuthToken(ctx, authToken)
@@ -1783,6 +2581,13 @@ func (m queryMetricsStore) GetWorkspaceAgentStatsAndLabels(ctx context.Context,
 	return stats, err
 }
 
+func (m queryMetricsStore) GetWorkspaceAgentTokenRotationByAgentID(ctx context.Context, agentID uuid.UUID) (database.WorkspaceAgentTokenRotation, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentTokenRotationByAgentID(ctx, agentID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentTokenRotationByAgentID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentUsageStatsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentUsageStats(ctx, createdAt)
@@ -1797,6 +2602,20 @@ func (m queryMetricsStore) GetWorkspaceAgentUsageStatsAndLabels(ctx context.Cont
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceAgentVersionsInLatestBuilds(ctx context.Context) ([]database.GetWorkspaceAgentVersionsInLatestBuildsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentVersionsInLatestBuilds(ctx)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentVersionsInLatestBuilds").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceAgentVolumeUsageByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentVolumeUsage, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentVolumeUsageByAgentID(ctx, agentID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentVolumeUsageByAgentID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentsByParentID(ctx context.Context, dollar_1 uuid.UUID) ([]database.WorkspaceAgent, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentsByParentID(ctx, dollar_1)
@@ -1867,6 +2686,27 @@ func (m queryMetricsStore) GetWorkspaceAppsCreatedAfter(ctx context.Context, cre
 	return apps, err
 }
 
+func (m queryMetricsStore) GetWorkspaceArchiveByID(ctx context.Context, id uuid.UUID) (database.WorkspaceArchive, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceArchiveByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceArchiveByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceArchivesByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]database.WorkspaceArchive, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceArchivesByOwnerID(ctx, ownerID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceArchivesByOwnerID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceArchivesExpiredBefore(ctx context.Context, now time.Time) ([]database.WorkspaceArchive, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceArchivesExpiredBefore(ctx, now)
+	m.queryLatencies.WithLabelValues("GetWorkspaceArchivesExpiredBefore").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBuild, error) {
 	start := time.Now()
 	build, err := m.s.GetWorkspaceBuildByID(ctx, id)
@@ -1902,6 +2742,13 @@ func (m queryMetricsStore) GetWorkspaceBuildParametersByBuildIDs(ctx context.Con
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceBuildQuotaCosts(ctx context.Context, workspaceBuildID uuid.UUID) ([]database.WorkspaceBuildQuotaCost, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBuildQuotaCosts(ctx, workspaceBuildID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceBuildQuotaCosts").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceBuildStatsByTemplates(ctx context.Context, since time.Time) ([]database.GetWorkspaceBuildStatsByTemplatesRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceBuildStatsByTemplates(ctx, since)
@@ -1958,6 +2805,13 @@ func (m queryMetricsStore) GetWorkspaceByWorkspaceAppID(ctx context.Context, wor
 	return workspace, err
 }
 
+func (m queryMetricsStore) GetWorkspaceLabelsByWorkspaceIDs(ctx context.Context, workspaceIds []uuid.UUID) ([]database.WorkspaceLabel, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceLabelsByWorkspaceIDs(ctx, workspaceIds)
+	m.queryLatencies.WithLabelValues("GetWorkspaceLabelsByWorkspaceIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceModulesByJobID(ctx, jobID)
@@ -1972,6 +2826,20 @@ func (m queryMetricsStore) GetWorkspaceModulesCreatedAfter(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspacePortShareLinkByID(ctx context.Context, id uuid.UUID) (database.WorkspacePortShareLink, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacePortShareLinkByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspacePortShareLinkByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspacePortShareLinksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspacePortShareLink, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacePortShareLinksByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspacePortShareLinksByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxies, err := m.s.GetWorkspaceProxies(ctx)
@@ -2000,6 +2868,20 @@ func (m queryMetricsStore) GetWorkspaceProxyByName(ctx context.Context, name str
 	return proxy, err
 }
 
+func (m queryMetricsStore) GetWorkspaceProxyTokenRotationByProxyID(ctx context.Context, proxyID uuid.UUID) (database.WorkspaceProxyTokenRotation, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceProxyTokenRotationByProxyID(ctx, proxyID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceProxyTokenRotationByProxyID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceProxyTokenRotations(ctx context.Context) ([]database.WorkspaceProxyTokenRotation, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceProxyTokenRotations(ctx)
+	m.queryLatencies.WithLabelValues("GetWorkspaceProxyTokenRotations").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (database.WorkspaceResource, error) {
 	start := time.Now()
 	resource, err := m.s.GetWorkspaceResourceByID(ctx, id)
@@ -2042,6 +2924,13 @@ func (m queryMetricsStore) GetWorkspaceResourcesCreatedAfter(ctx context.Context
 	return resources, err
 }
 
+func (m queryMetricsStore) GetWorkspaceSchedulePauseByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceSchedulePause, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSchedulePauseByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSchedulePauseByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx, templateIds)
@@ -2077,6 +2966,13 @@ func (m queryMetricsStore) GetWorkspacesEligibleForTransition(ctx context.Contex
 	return workspaces, err
 }
 
+func (m queryMetricsStore) HaltTemplateVersionRollout(ctx context.Context, arg database.HaltTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.HaltTemplateVersionRollout(ctx, arg)
+	m.queryLatencies.WithLabelValues("HaltTemplateVersionRollout").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) HasTemplateVersionsWithAITask(ctx context.Context) (bool, error) {
 	start := time.Now()
 	r0, r1 := m.s.HasTemplateVersionsWithAITask(ctx)
@@ -2084,6 +2980,20 @@ func (m queryMetricsStore) HasTemplateVersionsWithAITask(ctx context.Context) (b
 	return r0, r1
 }
 
+func (m queryMetricsStore) IncrementNetworkConnectionQualityCounters(ctx context.Context, arg database.IncrementNetworkConnectionQualityCountersParams) error {
+	start := time.Now()
+	r0 := m.s.IncrementNetworkConnectionQualityCounters(ctx, arg)
+	m.queryLatencies.WithLabelValues("IncrementNetworkConnectionQualityCounters").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) InsertAITaskQueueEntry(ctx context.Context, arg database.InsertAITaskQueueEntryParams) (database.AITaskQueue, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertAITaskQueueEntry(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertAITaskQueueEntry").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertAPIKey(ctx context.Context, arg database.InsertAPIKeyParams) (database.APIKey, error) {
 	start := time.Now()
 	key, err := m.s.InsertAPIKey(ctx, arg)
@@ -2105,6 +3015,13 @@ func (m queryMetricsStore) InsertAuditLog(ctx context.Context, arg database.Inse
 	return log, err
 }
 
+func (m queryMetricsStore) InsertAuditLogLegalHold(ctx context.Context, arg database.InsertAuditLogLegalHoldParams) (database.AuditLogLegalHold, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertAuditLogLegalHold(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertAuditLogLegalHold").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertCryptoKey(ctx context.Context, arg database.InsertCryptoKeyParams) (database.CryptoKey, error) {
 	start := time.Now()
 	key, err := m.s.InsertCryptoKey(ctx, arg)
@@ -2175,6 +3092,20 @@ func (m queryMetricsStore) InsertGroupMember(ctx context.Context, arg database.I
 	return err
 }
 
+func (m queryMetricsStore) InsertGroupQuotaAllowances(ctx context.Context, arg database.InsertGroupQuotaAllowancesParams) error {
+	start := time.Now()
+	r0 := m.s.InsertGroupQuotaAllowances(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertGroupQuotaAllowances").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) InsertHealthScore(ctx context.Context, arg database.InsertHealthScoreParams) error {
+	start := time.Now()
+	r0 := m.s.InsertHealthScore(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertHealthScore").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) InsertInboxNotification(ctx context.Context, arg database.InsertInboxNotificationParams) (database.InboxNotification, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertInboxNotification(ctx, arg)
@@ -2217,6 +3148,13 @@ func (m queryMetricsStore) InsertOAuth2ProviderAppCode(ctx context.Context, arg
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertOAuth2ProviderAppDeviceCode(ctx context.Context, arg database.InsertOAuth2ProviderAppDeviceCodeParams) (database.OAuth2ProviderAppDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppDeviceCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppDeviceCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertOAuth2ProviderAppSecret(ctx, arg)
@@ -2280,6 +3218,13 @@ func (m queryMetricsStore) InsertProvisionerJobLogs(ctx context.Context, arg dat
 	return logs, err
 }
 
+func (m queryMetricsStore) InsertProvisionerJobRequeue(ctx context.Context, arg database.InsertProvisionerJobRequeueParams) error {
+	start := time.Now()
+	r0 := m.s.InsertProvisionerJobRequeue(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertProvisionerJobRequeue").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) InsertProvisionerJobTimings(ctx context.Context, arg database.InsertProvisionerJobTimingsParams) ([]database.ProvisionerJobTiming, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertProvisionerJobTimings(ctx, arg)
@@ -2301,6 +3246,20 @@ func (m queryMetricsStore) InsertReplica(ctx context.Context, arg database.Inser
 	return replica, err
 }
 
+func (m queryMetricsStore) InsertSCIMGroupMembers(ctx context.Context, arg database.InsertSCIMGroupMembersParams) error {
+	start := time.Now()
+	r0 := m.s.InsertSCIMGroupMembers(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertSCIMGroupMembers").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) InsertSCIMNestedGroups(ctx context.Context, arg database.InsertSCIMNestedGroupsParams) error {
+	start := time.Now()
+	r0 := m.s.InsertSCIMNestedGroups(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertSCIMNestedGroups").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) InsertTelemetryItemIfNotExists(ctx context.Context, arg database.InsertTelemetryItemIfNotExistsParams) error {
 	start := time.Now()
 	r0 := m.s.InsertTelemetryItemIfNotExists(ctx, arg)
@@ -2315,6 +3274,27 @@ func (m queryMetricsStore) InsertTemplate(ctx context.Context, arg database.Inse
 	return err
 }
 
+func (m queryMetricsStore) InsertTemplateBundle(ctx context.Context, arg database.InsertTemplateBundleParams) error {
+	start := time.Now()
+	r0 := m.s.InsertTemplateBundle(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateBundle").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) InsertTemplateSecret(ctx context.Context, arg database.InsertTemplateSecretParams) (database.TemplateSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplateSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertTemplateVariableSet(ctx context.Context, arg database.InsertTemplateVariableSetParams) error {
+	start := time.Now()
+	r0 := m.s.InsertTemplateVariableSet(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateVariableSet").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
 	start := time.Now()
 	err := m.s.InsertTemplateVersion(ctx, arg)
@@ -2378,6 +3358,34 @@ func (m queryMetricsStore) InsertUserLink(ctx context.Context, arg database.Inse
 	return link, err
 }
 
+func (m queryMetricsStore) InsertUserMFARecoveryCode(ctx context.Context, arg database.InsertUserMFARecoveryCodeParams) error {
+	start := time.Now()
+	r0 := m.s.InsertUserMFARecoveryCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertUserMFARecoveryCode").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) InsertUserSecret(ctx context.Context, arg database.InsertUserSecretParams) (database.UserSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertUserSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertUserSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertUserWebAuthnCredential(ctx context.Context, arg database.InsertUserWebAuthnCredentialParams) (database.UserWebAuthnCredential, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertUserWebAuthnCredential(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertUserWebAuthnCredential").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertVariableSet(ctx context.Context, arg database.InsertVariableSetParams) (database.VariableSet, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertVariableSet(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertVariableSet").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertVolumeResourceMonitor(ctx context.Context, arg database.InsertVolumeResourceMonitorParams) (database.WorkspaceAgentVolumeResourceMonitor, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertVolumeResourceMonitor(ctx, arg)
@@ -2385,6 +3393,13 @@ func (m queryMetricsStore) InsertVolumeResourceMonitor(ctx context.Context, arg
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertWebAuthnChallenge(ctx context.Context, arg database.InsertWebAuthnChallengeParams) (database.WebAuthnChallenge, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWebAuthnChallenge(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWebAuthnChallenge").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertWebpushSubscription(ctx context.Context, arg database.InsertWebpushSubscriptionParams) (database.WebpushSubscription, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWebpushSubscription(ctx, arg)
@@ -2399,6 +3414,13 @@ func (m queryMetricsStore) InsertWorkspace(ctx context.Context, arg database.Ins
 	return workspace, err
 }
 
+func (m queryMetricsStore) InsertWorkspaceAdoption(ctx context.Context, arg database.InsertWorkspaceAdoptionParams) (database.WorkspaceAdoption, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAdoption(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAdoption").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertWorkspaceAgent(ctx context.Context, arg database.InsertWorkspaceAgentParams) (database.WorkspaceAgent, error) {
 	start := time.Now()
 	agent, err := m.s.InsertWorkspaceAgent(ctx, arg)
@@ -2434,6 +3456,13 @@ func (m queryMetricsStore) InsertWorkspaceAgentMetadata(ctx context.Context, arg
 	return err
 }
 
+func (m queryMetricsStore) InsertWorkspaceAgentPushedMetadata(ctx context.Context, arg database.InsertWorkspaceAgentPushedMetadataParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspaceAgentPushedMetadata(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentPushedMetadata").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg database.InsertWorkspaceAgentScriptTimingsParams) (database.WorkspaceAgentScriptTiming, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAgentScriptTimings(ctx, arg)
@@ -2469,6 +3498,13 @@ func (m queryMetricsStore) InsertWorkspaceAppStatus(ctx context.Context, arg dat
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertWorkspaceArchive(ctx context.Context, arg database.InsertWorkspaceArchiveParams) (database.WorkspaceArchive, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceArchive(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceArchive").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertWorkspaceBuild(ctx context.Context, arg database.InsertWorkspaceBuildParams) error {
 	start := time.Now()
 	err := m.s.InsertWorkspaceBuild(ctx, arg)
@@ -2483,6 +3519,20 @@ func (m queryMetricsStore) InsertWorkspaceBuildParameters(ctx context.Context, a
 	return err
 }
 
+func (m queryMetricsStore) InsertWorkspaceBuildQuotaCosts(ctx context.Context, arg database.InsertWorkspaceBuildQuotaCostsParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspaceBuildQuotaCosts(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceBuildQuotaCosts").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) InsertWorkspaceLabels(ctx context.Context, arg database.InsertWorkspaceLabelsParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspaceLabels(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceLabels").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) InsertWorkspaceModule(ctx context.Context, arg database.InsertWorkspaceModuleParams) (database.WorkspaceModule, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceModule(ctx, arg)
@@ -2490,6 +3540,13 @@ func (m queryMetricsStore) InsertWorkspaceModule(ctx context.Context, arg databa
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertWorkspacePortShareLink(ctx context.Context, arg database.InsertWorkspacePortShareLinkParams) (database.WorkspacePortShareLink, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspacePortShareLink(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspacePortShareLink").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertWorkspaceProxy(ctx context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.InsertWorkspaceProxy(ctx, arg)
@@ -2595,6 +3652,13 @@ func (m queryMetricsStore) RemoveUserFromGroups(ctx context.Context, arg databas
 	return r0, r1
 }
 
+func (m queryMetricsStore) RequeueProvisionerJob(ctx context.Context, arg database.RequeueProvisionerJobParams) error {
+	start := time.Now()
+	r0 := m.s.RequeueProvisionerJob(ctx, arg)
+	m.queryLatencies.WithLabelValues("RequeueProvisionerJob").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
 	start := time.Now()
 	r0 := m.s.RevokeDBCryptKey(ctx, activeKeyDigest)
@@ -2602,6 +3666,34 @@ func (m queryMetricsStore) RevokeDBCryptKey(ctx context.Context, activeKeyDigest
 	return r0
 }
 
+func (m queryMetricsStore) RotateWorkspaceAgentAuthToken(ctx context.Context, arg database.RotateWorkspaceAgentAuthTokenParams) (database.WorkspaceAgentTokenRotation, error) {
+	start := time.Now()
+	r0, r1 := m.s.RotateWorkspaceAgentAuthToken(ctx, arg)
+	m.queryLatencies.WithLabelValues("RotateWorkspaceAgentAuthToken").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) RotateWorkspaceProxyToken(ctx context.Context, arg database.RotateWorkspaceProxyTokenParams) (database.WorkspaceProxyTokenRotation, error) {
+	start := time.Now()
+	r0, r1 := m.s.RotateWorkspaceProxyToken(ctx, arg)
+	m.queryLatencies.WithLabelValues("RotateWorkspaceProxyToken").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) SearchProvisionerJobLogs(ctx context.Context, arg database.SearchProvisionerJobLogsParams) ([]database.SearchProvisionerJobLogsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.SearchProvisionerJobLogs(ctx, arg)
+	m.queryLatencies.WithLabelValues("SearchProvisionerJobLogs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) StartTemplateVersionRollout(ctx context.Context, arg database.StartTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.StartTemplateVersionRollout(ctx, arg)
+	m.queryLatencies.WithLabelValues("StartTemplateVersionRollout").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock int64) (bool, error) {
 	start := time.Now()
 	ok, err := m.s.TryAcquireLock(ctx, pgTryAdvisoryXactLock)
@@ -2623,6 +3715,13 @@ func (m queryMetricsStore) UnfavoriteWorkspace(ctx context.Context, arg uuid.UUI
 	return r0
 }
 
+func (m queryMetricsStore) UpdateAITaskQueueEntryFailed(ctx context.Context, arg database.UpdateAITaskQueueEntryFailedParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateAITaskQueueEntryFailed(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateAITaskQueueEntryFailed").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateAPIKeyByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateAPIKeyByID(ctx, arg)
@@ -2686,6 +3785,13 @@ func (m queryMetricsStore) UpdateInboxNotificationReadStatus(ctx context.Context
 	return r0
 }
 
+func (m queryMetricsStore) UpdateInboxNotificationsReadStatus(ctx context.Context, arg database.UpdateInboxNotificationsReadStatusParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateInboxNotificationsReadStatus(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateInboxNotificationsReadStatus").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
 	start := time.Now()
 	member, err := m.s.UpdateMemberRoles(ctx, arg)
@@ -2721,6 +3827,20 @@ func (m queryMetricsStore) UpdateOAuth2ProviderAppByID(ctx context.Context, arg
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpdateOAuth2ProviderAppDeviceCodeLastPolledAt(ctx context.Context, arg database.UpdateOAuth2ProviderAppDeviceCodeLastPolledAtParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateOAuth2ProviderAppDeviceCodeLastPolledAt(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppDeviceCodeLastPolledAt").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) UpdateOAuth2ProviderAppDeviceCodeUserID(ctx context.Context, arg database.UpdateOAuth2ProviderAppDeviceCodeUserIDParams) (database.OAuth2ProviderAppDeviceCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateOAuth2ProviderAppDeviceCodeUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppDeviceCodeUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateOAuth2ProviderAppSecretByID(ctx, arg)
@@ -2812,6 +3932,13 @@ func (m queryMetricsStore) UpdateTemplateAccessControlByID(ctx context.Context,
 	return r0
 }
 
+func (m queryMetricsStore) UpdateTemplateActivationReviewers(ctx context.Context, arg database.UpdateTemplateActivationReviewersParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateTemplateActivationReviewers(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateActivationReviewers").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpdateTemplateActiveVersionByID(ctx context.Context, arg database.UpdateTemplateActiveVersionByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateActiveVersionByID(ctx, arg)
@@ -2833,6 +3960,13 @@ func (m queryMetricsStore) UpdateTemplateMetaByID(ctx context.Context, arg datab
 	return err
 }
 
+func (m queryMetricsStore) UpdateTemplateOrganizationByID(ctx context.Context, arg database.UpdateTemplateOrganizationByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateTemplateOrganizationByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateOrganizationByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpdateTemplateScheduleByID(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateScheduleByID(ctx, arg)
@@ -2840,6 +3974,13 @@ func (m queryMetricsStore) UpdateTemplateScheduleByID(ctx context.Context, arg d
 	return err
 }
 
+func (m queryMetricsStore) UpdateTemplateSecret(ctx context.Context, arg database.UpdateTemplateSecretParams) (database.TemplateSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateTemplateSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateTemplateVersionAITaskByJobID(ctx context.Context, arg database.UpdateTemplateVersionAITaskByJobIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateTemplateVersionAITaskByJobID(ctx, arg)
@@ -2847,6 +3988,13 @@ func (m queryMetricsStore) UpdateTemplateVersionAITaskByJobID(ctx context.Contex
 	return r0
 }
 
+func (m queryMetricsStore) UpdateTemplateVersionActivationRequestStatus(ctx context.Context, arg database.UpdateTemplateVersionActivationRequestStatusParams) (database.TemplateVersionActivationRequest, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateTemplateVersionActivationRequestStatus(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateVersionActivationRequestStatus").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateTemplateVersionByID(ctx context.Context, arg database.UpdateTemplateVersionByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateVersionByID(ctx, arg)
@@ -2931,6 +4079,20 @@ func (m queryMetricsStore) UpdateUserLoginType(ctx context.Context, arg database
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpdateUserNotificationCategoryPreferences(ctx context.Context, arg database.UpdateUserNotificationCategoryPreferencesParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserNotificationCategoryPreferences(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserNotificationCategoryPreferences").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateUserNotificationDigest(ctx context.Context, arg database.UpdateUserNotificationDigestParams) (database.UserConfig, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserNotificationDigest(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserNotificationDigest").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateUserNotificationPreferences(ctx context.Context, arg database.UpdateUserNotificationPreferencesParams) (int64, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateUserNotificationPreferences(ctx, arg)
@@ -2945,6 +4107,20 @@ func (m queryMetricsStore) UpdateUserProfile(ctx context.Context, arg database.U
 	return user, err
 }
 
+func (m queryMetricsStore) UpdateUserProxyLatencies(ctx context.Context, arg database.UpdateUserProxyLatenciesParams) (database.UserConfig, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserProxyLatencies(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserProxyLatencies").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateUserProxyPreference(ctx context.Context, arg database.UpdateUserProxyPreferenceParams) (database.UserConfig, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserProxyPreference(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserProxyPreference").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateUserQuietHoursSchedule(ctx context.Context, arg database.UpdateUserQuietHoursScheduleParams) (database.User, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateUserQuietHoursSchedule(ctx, arg)
@@ -2959,6 +4135,20 @@ func (m queryMetricsStore) UpdateUserRoles(ctx context.Context, arg database.Upd
 	return user, err
 }
 
+func (m queryMetricsStore) UpdateUserSecret(ctx context.Context, arg database.UpdateUserSecretParams) (database.UserSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpdateUserStartOnConnect(ctx context.Context, arg database.UpdateUserStartOnConnectParams) (database.UserConfig, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserStartOnConnect(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserStartOnConnect").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateUserStatus(ctx context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
 	start := time.Now()
 	user, err := m.s.UpdateUserStatus(ctx, arg)
@@ -2980,6 +4170,20 @@ func (m queryMetricsStore) UpdateUserThemePreference(ctx context.Context, arg da
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpdateUserWebAuthnCredentialSignCount(ctx context.Context, arg database.UpdateUserWebAuthnCredentialSignCountParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateUserWebAuthnCredentialSignCount(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserWebAuthnCredentialSignCount").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) UpdateVariableSet(ctx context.Context, arg database.UpdateVariableSetParams) (database.VariableSet, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateVariableSet(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateVariableSet").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateVolumeResourceMonitor(ctx context.Context, arg database.UpdateVolumeResourceMonitorParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateVolumeResourceMonitor(ctx, arg)
@@ -2994,6 +4198,13 @@ func (m queryMetricsStore) UpdateWorkspace(ctx context.Context, arg database.Upd
 	return workspace, err
 }
 
+func (m queryMetricsStore) UpdateWorkspaceAdoptionAdopted(ctx context.Context, arg database.UpdateWorkspaceAdoptionAdoptedParams) (database.WorkspaceAdoption, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceAdoptionAdopted(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceAdoptionAdopted").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentConnectionByID(ctx, arg)
@@ -3134,6 +4345,13 @@ func (m queryMetricsStore) UpdateWorkspacesDormantDeletingAtByTemplateID(ctx con
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpdateWorkspacesOrganizationByTemplateID(ctx context.Context, arg database.UpdateWorkspacesOrganizationByTemplateIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspacesOrganizationByTemplateID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspacesOrganizationByTemplateID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpdateWorkspacesTTLByTemplateID(ctx context.Context, arg database.UpdateWorkspacesTTLByTemplateIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspacesTTLByTemplateID(ctx, arg)
@@ -3176,6 +4394,13 @@ func (m queryMetricsStore) UpsertDefaultProxy(ctx context.Context, arg database.
 	return r0
 }
 
+func (m queryMetricsStore) UpsertExperimentFlags(ctx context.Context, value string) error {
+	start := time.Now()
+	r0 := m.s.UpsertExperimentFlags(ctx, value)
+	m.queryLatencies.WithLabelValues("UpsertExperimentFlags").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpsertHealthSettings(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertHealthSettings(ctx, value)
@@ -3183,6 +4408,20 @@ func (m queryMetricsStore) UpsertHealthSettings(ctx context.Context, value strin
 	return r0
 }
 
+func (m queryMetricsStore) UpsertInternalCA(ctx context.Context, value string) error {
+	start := time.Now()
+	r0 := m.s.UpsertInternalCA(ctx, value)
+	m.queryLatencies.WithLabelValues("UpsertInternalCA").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) UpsertLDAPUser(ctx context.Context, arg database.UpsertLDAPUserParams) (database.LDAPUser, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertLDAPUser(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertLDAPUser").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertLastUpdateCheck(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertLastUpdateCheck(ctx, value)
@@ -3190,6 +4429,13 @@ func (m queryMetricsStore) UpsertLastUpdateCheck(ctx context.Context, value stri
 	return r0
 }
 
+func (m queryMetricsStore) UpsertLicenseSeatReservation(ctx context.Context, arg database.UpsertLicenseSeatReservationParams) (database.LicenseSeatReservation, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertLicenseSeatReservation(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertLicenseSeatReservation").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertLogoURL(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertLogoURL(ctx, value)
@@ -3197,6 +4443,13 @@ func (m queryMetricsStore) UpsertLogoURL(ctx context.Context, value string) erro
 	return r0
 }
 
+func (m queryMetricsStore) UpsertNetworkConnectionQuality(ctx context.Context, arg database.UpsertNetworkConnectionQualityParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertNetworkConnectionQuality(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertNetworkConnectionQuality").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpsertNotificationReportGeneratorLog(ctx context.Context, arg database.UpsertNotificationReportGeneratorLogParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertNotificationReportGeneratorLog(ctx, arg)
@@ -3225,6 +4478,27 @@ func (m queryMetricsStore) UpsertOAuthSigningKey(ctx context.Context, value stri
 	return r0
 }
 
+func (m queryMetricsStore) UpsertOrganizationAuditSettings(ctx context.Context, arg database.UpsertOrganizationAuditSettingsParams) (database.OrganizationAuditSetting, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertOrganizationAuditSettings(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertOrganizationAuditSettings").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertOrganizationMFASettings(ctx context.Context, arg database.UpsertOrganizationMFASettingsParams) (database.OrganizationMFASetting, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertOrganizationMFASettings(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertOrganizationMFASettings").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertOrganizationSettingOverrides(ctx context.Context, arg database.UpsertOrganizationSettingOverridesParams) (database.OrganizationSettingOverride, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertOrganizationSettingOverrides(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertOrganizationSettingOverrides").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertPrebuildsSettings(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertPrebuildsSettings(ctx, value)
@@ -3239,6 +4513,13 @@ func (m queryMetricsStore) UpsertProvisionerDaemon(ctx context.Context, arg data
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpsertReviewWorkspace(ctx context.Context, arg database.UpsertReviewWorkspaceParams) (database.ReviewWorkspace, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertReviewWorkspace(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertReviewWorkspace").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertRuntimeConfig(ctx context.Context, arg database.UpsertRuntimeConfigParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertRuntimeConfig(ctx, arg)
@@ -3295,6 +4576,34 @@ func (m queryMetricsStore) UpsertTelemetryItem(ctx context.Context, arg database
 	return r0
 }
 
+func (m queryMetricsStore) UpsertTemplateAppRestriction(ctx context.Context, arg database.UpsertTemplateAppRestrictionParams) (database.TemplateAppRestriction, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTemplateAppRestriction(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertTemplateAppRestriction").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertTemplateAppSessionPolicy(ctx context.Context, arg database.UpsertTemplateAppSessionPolicyParams) (database.TemplateAppSessionPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTemplateAppSessionPolicy(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertTemplateAppSessionPolicy").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertTemplateEgressPolicy(ctx context.Context, arg database.UpsertTemplateEgressPolicyParams) (database.TemplateEgressPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTemplateEgressPolicy(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertTemplateEgressPolicy").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertTemplateParameterOptionSource(ctx context.Context, arg database.UpsertTemplateParameterOptionSourceParams) (database.TemplateParameterOptionSource, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTemplateParameterOptionSource(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertTemplateParameterOptionSource").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertTemplateUsageStats(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.UpsertTemplateUsageStats(ctx)
@@ -3302,6 +4611,34 @@ func (m queryMetricsStore) UpsertTemplateUsageStats(ctx context.Context) error {
 	return r0
 }
 
+func (m queryMetricsStore) UpsertTemplateVersionActivationRequest(ctx context.Context, arg database.UpsertTemplateVersionActivationRequestParams) (database.TemplateVersionActivationRequest, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTemplateVersionActivationRequest(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertTemplateVersionActivationRequest").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertTemplateVersionActivationReview(ctx context.Context, arg database.UpsertTemplateVersionActivationReviewParams) (database.TemplateVersionActivationReview, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTemplateVersionActivationReview(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertTemplateVersionActivationReview").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertTemplateVersionRollout(ctx context.Context, arg database.UpsertTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTemplateVersionRollout(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertTemplateVersionRollout").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) UpsertUserCLIVersion(ctx context.Context, arg database.UpsertUserCLIVersionParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertUserCLIVersion(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserCLIVersion").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpsertWebpushVAPIDKeys(ctx context.Context, arg database.UpsertWebpushVAPIDKeysParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertWebpushVAPIDKeys(ctx, arg)
@@ -3316,6 +4653,13 @@ func (m queryMetricsStore) UpsertWorkspaceAgentPortShare(ctx context.Context, ar
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpsertWorkspaceAgentVolumeUsage(ctx context.Context, arg database.UpsertWorkspaceAgentVolumeUsageParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceAgentVolumeUsage(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceAgentVolumeUsage").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpsertWorkspaceApp(ctx context.Context, arg database.UpsertWorkspaceAppParams) (database.WorkspaceApp, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertWorkspaceApp(ctx, arg)
@@ -3330,6 +4674,20 @@ func (m queryMetricsStore) UpsertWorkspaceAppAuditSession(ctx context.Context, a
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpsertWorkspaceSchedulePause(ctx context.Context, arg database.UpsertWorkspaceSchedulePauseParams) (database.WorkspaceSchedulePause, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertWorkspaceSchedulePause(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceSchedulePause").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) UseUserMFARecoveryCode(ctx context.Context, arg database.UseUserMFARecoveryCodeParams) (database.UserMFARecoveryCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.UseUserMFARecoveryCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("UseUserMFARecoveryCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	start := time.Now()
 	templates, err := m.s.GetAuthorizedTemplates(ctx, arg, prepared)
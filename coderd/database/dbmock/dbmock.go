@@ -103,6 +103,20 @@ func (mr *MockStoreMockRecorder) ActivityBumpWorkspace(ctx, arg any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivityBumpWorkspace", reflect.TypeOf((*MockStore)(nil).ActivityBumpWorkspace), ctx, arg)
 }
 
+// AddWorkspacePortShareLinkBytesTransferred mocks base method.
+func (m *MockStore) AddWorkspacePortShareLinkBytesTransferred(ctx context.Context, arg database.AddWorkspacePortShareLinkBytesTransferredParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWorkspacePortShareLinkBytesTransferred", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWorkspacePortShareLinkBytesTransferred indicates an expected call of AddWorkspacePortShareLinkBytesTransferred.
+func (mr *MockStoreMockRecorder) AddWorkspacePortShareLinkBytesTransferred(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWorkspacePortShareLinkBytesTransferred", reflect.TypeOf((*MockStore)(nil).AddWorkspacePortShareLinkBytesTransferred), ctx, arg)
+}
+
 // AllUserIDs mocks base method.
 func (m *MockStore) AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid.UUID, error) {
 	m.ctrl.T.Helper()
@@ -191,6 +205,20 @@ func (mr *MockStoreMockRecorder) BulkMarkNotificationMessagesSent(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkMarkNotificationMessagesSent", reflect.TypeOf((*MockStore)(nil).BulkMarkNotificationMessagesSent), ctx, arg)
 }
 
+// CancelPendingTemplateVersionActivationRequests mocks base method.
+func (m *MockStore) CancelPendingTemplateVersionActivationRequests(ctx context.Context, arg database.CancelPendingTemplateVersionActivationRequestsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelPendingTemplateVersionActivationRequests", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelPendingTemplateVersionActivationRequests indicates an expected call of CancelPendingTemplateVersionActivationRequests.
+func (mr *MockStoreMockRecorder) CancelPendingTemplateVersionActivationRequests(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelPendingTemplateVersionActivationRequests", reflect.TypeOf((*MockStore)(nil).CancelPendingTemplateVersionActivationRequests), ctx, arg)
+}
+
 // ClaimPrebuiltWorkspace mocks base method.
 func (m *MockStore) ClaimPrebuiltWorkspace(ctx context.Context, arg database.ClaimPrebuiltWorkspaceParams) (database.ClaimPrebuiltWorkspaceRow, error) {
 	m.ctrl.T.Helper()
@@ -278,6 +306,21 @@ func (mr *MockStoreMockRecorder) CountAuthorizedAuditLogs(ctx, arg, prepared any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAuthorizedAuditLogs", reflect.TypeOf((*MockStore)(nil).CountAuthorizedAuditLogs), ctx, arg, prepared)
 }
 
+// CountExternalFilesByHash mocks base method.
+func (m *MockStore) CountExternalFilesByHash(ctx context.Context, hash string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountExternalFilesByHash", ctx, hash)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountExternalFilesByHash indicates an expected call of CountExternalFilesByHash.
+func (mr *MockStoreMockRecorder) CountExternalFilesByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountExternalFilesByHash", reflect.TypeOf((*MockStore)(nil).CountExternalFilesByHash), ctx, hash)
+}
+
 // CountInProgressPrebuilds mocks base method.
 func (m *MockStore) CountInProgressPrebuilds(ctx context.Context) ([]database.CountInProgressPrebuildsRow, error) {
 	m.ctrl.T.Helper()
@@ -293,6 +336,36 @@ func (mr *MockStoreMockRecorder) CountInProgressPrebuilds(ctx any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountInProgressPrebuilds", reflect.TypeOf((*MockStore)(nil).CountInProgressPrebuilds), ctx)
 }
 
+// CountRunningAITasksByOwnerID mocks base method.
+func (m *MockStore) CountRunningAITasksByOwnerID(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRunningAITasksByOwnerID", ctx, ownerID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRunningAITasksByOwnerID indicates an expected call of CountRunningAITasksByOwnerID.
+func (mr *MockStoreMockRecorder) CountRunningAITasksByOwnerID(ctx, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRunningAITasksByOwnerID", reflect.TypeOf((*MockStore)(nil).CountRunningAITasksByOwnerID), ctx, ownerID)
+}
+
+// CountUnreadInboxNotificationsByCategory mocks base method.
+func (m *MockStore) CountUnreadInboxNotificationsByCategory(ctx context.Context, userID uuid.UUID) ([]database.CountUnreadInboxNotificationsByCategoryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadInboxNotificationsByCategory", ctx, userID)
+	ret0, _ := ret[0].([]database.CountUnreadInboxNotificationsByCategoryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadInboxNotificationsByCategory indicates an expected call of CountUnreadInboxNotificationsByCategory.
+func (mr *MockStoreMockRecorder) CountUnreadInboxNotificationsByCategory(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadInboxNotificationsByCategory", reflect.TypeOf((*MockStore)(nil).CountUnreadInboxNotificationsByCategory), ctx, userID)
+}
+
 // CountUnreadInboxNotificationsByUserID mocks base method.
 func (m *MockStore) CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
 	m.ctrl.T.Helper()
@@ -323,6 +396,20 @@ func (mr *MockStoreMockRecorder) CustomRoles(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CustomRoles", reflect.TypeOf((*MockStore)(nil).CustomRoles), ctx, arg)
 }
 
+// DeleteAITaskQueueEntryByID mocks base method.
+func (m *MockStore) DeleteAITaskQueueEntryByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAITaskQueueEntryByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAITaskQueueEntryByID indicates an expected call of DeleteAITaskQueueEntryByID.
+func (mr *MockStoreMockRecorder) DeleteAITaskQueueEntryByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAITaskQueueEntryByID", reflect.TypeOf((*MockStore)(nil).DeleteAITaskQueueEntryByID), ctx, id)
+}
+
 // DeleteAPIKeyByID mocks base method.
 func (m *MockStore) DeleteAPIKeyByID(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -351,6 +438,20 @@ func (mr *MockStoreMockRecorder) DeleteAPIKeysByUserID(ctx, userID any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIKeysByUserID", reflect.TypeOf((*MockStore)(nil).DeleteAPIKeysByUserID), ctx, userID)
 }
 
+// DeleteAPIKeysByUserIDLoginType mocks base method.
+func (m *MockStore) DeleteAPIKeysByUserIDLoginType(ctx context.Context, arg database.DeleteAPIKeysByUserIDLoginTypeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAPIKeysByUserIDLoginType", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAPIKeysByUserIDLoginType indicates an expected call of DeleteAPIKeysByUserIDLoginType.
+func (mr *MockStoreMockRecorder) DeleteAPIKeysByUserIDLoginType(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIKeysByUserIDLoginType", reflect.TypeOf((*MockStore)(nil).DeleteAPIKeysByUserIDLoginType), ctx, arg)
+}
+
 // DeleteAllTailnetClientSubscriptions mocks base method.
 func (m *MockStore) DeleteAllTailnetClientSubscriptions(ctx context.Context, arg database.DeleteAllTailnetClientSubscriptionsParams) error {
 	m.ctrl.T.Helper()
@@ -407,6 +508,20 @@ func (mr *MockStoreMockRecorder) DeleteApplicationConnectAPIKeysByUserID(ctx, us
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApplicationConnectAPIKeysByUserID", reflect.TypeOf((*MockStore)(nil).DeleteApplicationConnectAPIKeysByUserID), ctx, userID)
 }
 
+// DeleteAuditLogLegalHoldByID mocks base method.
+func (m *MockStore) DeleteAuditLogLegalHoldByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuditLogLegalHoldByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAuditLogLegalHoldByID indicates an expected call of DeleteAuditLogLegalHoldByID.
+func (mr *MockStoreMockRecorder) DeleteAuditLogLegalHoldByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuditLogLegalHoldByID", reflect.TypeOf((*MockStore)(nil).DeleteAuditLogLegalHoldByID), ctx, id)
+}
+
 // DeleteCoordinator mocks base method.
 func (m *MockStore) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -450,6 +565,48 @@ func (mr *MockStoreMockRecorder) DeleteCustomRole(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCustomRole", reflect.TypeOf((*MockStore)(nil).DeleteCustomRole), ctx, arg)
 }
 
+// DeleteExcessAPIKeySessionsByUserID mocks base method.
+func (m *MockStore) DeleteExcessAPIKeySessionsByUserID(ctx context.Context, arg database.DeleteExcessAPIKeySessionsByUserIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExcessAPIKeySessionsByUserID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExcessAPIKeySessionsByUserID indicates an expected call of DeleteExcessAPIKeySessionsByUserID.
+func (mr *MockStoreMockRecorder) DeleteExcessAPIKeySessionsByUserID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExcessAPIKeySessionsByUserID", reflect.TypeOf((*MockStore)(nil).DeleteExcessAPIKeySessionsByUserID), ctx, arg)
+}
+
+// DeleteExpiredWebAuthnChallenges mocks base method.
+func (m *MockStore) DeleteExpiredWebAuthnChallenges(ctx context.Context, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredWebAuthnChallenges", ctx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredWebAuthnChallenges indicates an expected call of DeleteExpiredWebAuthnChallenges.
+func (mr *MockStoreMockRecorder) DeleteExpiredWebAuthnChallenges(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredWebAuthnChallenges", reflect.TypeOf((*MockStore)(nil).DeleteExpiredWebAuthnChallenges), ctx, now)
+}
+
+// DeleteExpiredWorkspacePortShareLinks mocks base method.
+func (m *MockStore) DeleteExpiredWorkspacePortShareLinks(ctx context.Context, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredWorkspacePortShareLinks", ctx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredWorkspacePortShareLinks indicates an expected call of DeleteExpiredWorkspacePortShareLinks.
+func (mr *MockStoreMockRecorder) DeleteExpiredWorkspacePortShareLinks(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredWorkspacePortShareLinks", reflect.TypeOf((*MockStore)(nil).DeleteExpiredWorkspacePortShareLinks), ctx, now)
+}
+
 // DeleteExternalAuthLink mocks base method.
 func (m *MockStore) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	m.ctrl.T.Helper()
@@ -506,6 +663,20 @@ func (mr *MockStoreMockRecorder) DeleteGroupMemberFromGroup(ctx, arg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroupMemberFromGroup", reflect.TypeOf((*MockStore)(nil).DeleteGroupMemberFromGroup), ctx, arg)
 }
 
+// DeleteGroupQuotaAllowances mocks base method.
+func (m *MockStore) DeleteGroupQuotaAllowances(ctx context.Context, groupID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGroupQuotaAllowances", ctx, groupID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGroupQuotaAllowances indicates an expected call of DeleteGroupQuotaAllowances.
+func (mr *MockStoreMockRecorder) DeleteGroupQuotaAllowances(ctx, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroupQuotaAllowances", reflect.TypeOf((*MockStore)(nil).DeleteGroupQuotaAllowances), ctx, groupID)
+}
+
 // DeleteLicense mocks base method.
 func (m *MockStore) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	m.ctrl.T.Helper()
@@ -521,6 +692,34 @@ func (mr *MockStoreMockRecorder) DeleteLicense(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLicense", reflect.TypeOf((*MockStore)(nil).DeleteLicense), ctx, id)
 }
 
+// DeleteLicenseSeatReservation mocks base method.
+func (m *MockStore) DeleteLicenseSeatReservation(ctx context.Context, groupID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLicenseSeatReservation", ctx, groupID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLicenseSeatReservation indicates an expected call of DeleteLicenseSeatReservation.
+func (mr *MockStoreMockRecorder) DeleteLicenseSeatReservation(ctx, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLicenseSeatReservation", reflect.TypeOf((*MockStore)(nil).DeleteLicenseSeatReservation), ctx, groupID)
+}
+
+// DeleteNetworkConnectionQuality mocks base method.
+func (m *MockStore) DeleteNetworkConnectionQuality(ctx context.Context, arg database.DeleteNetworkConnectionQualityParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNetworkConnectionQuality", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNetworkConnectionQuality indicates an expected call of DeleteNetworkConnectionQuality.
+func (mr *MockStoreMockRecorder) DeleteNetworkConnectionQuality(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNetworkConnectionQuality", reflect.TypeOf((*MockStore)(nil).DeleteNetworkConnectionQuality), ctx, arg)
+}
+
 // DeleteOAuth2ProviderAppByClientID mocks base method.
 func (m *MockStore) DeleteOAuth2ProviderAppByClientID(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -577,6 +776,20 @@ func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppCodesByAppAndUserID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppCodesByAppAndUserID), ctx, arg)
 }
 
+// DeleteOAuth2ProviderAppDeviceCodeByID mocks base method.
+func (m *MockStore) DeleteOAuth2ProviderAppDeviceCodeByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuth2ProviderAppDeviceCodeByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOAuth2ProviderAppDeviceCodeByID indicates an expected call of DeleteOAuth2ProviderAppDeviceCodeByID.
+func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppDeviceCodeByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppDeviceCodeByID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppDeviceCodeByID), ctx, id)
+}
+
 // DeleteOAuth2ProviderAppSecretByID mocks base method.
 func (m *MockStore) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -605,6 +818,49 @@ func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppTokensByAppAndUserID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppTokensByAppAndUserID), ctx, arg)
 }
 
+// DeleteOldAuditLogs mocks base method.
+func (m *MockStore) DeleteOldAuditLogs(ctx context.Context, arg database.DeleteOldAuditLogsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldAuditLogs", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOldAuditLogs indicates an expected call of DeleteOldAuditLogs.
+func (mr *MockStoreMockRecorder) DeleteOldAuditLogs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldAuditLogs", reflect.TypeOf((*MockStore)(nil).DeleteOldAuditLogs), ctx, arg)
+}
+
+// DeleteOldHealthScores mocks base method.
+func (m *MockStore) DeleteOldHealthScores(ctx context.Context, beforeTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldHealthScores", ctx, beforeTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldHealthScores indicates an expected call of DeleteOldHealthScores.
+func (mr *MockStoreMockRecorder) DeleteOldHealthScores(ctx, beforeTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldHealthScores", reflect.TypeOf((*MockStore)(nil).DeleteOldHealthScores), ctx, beforeTime)
+}
+
+// DeleteOldNetworkConnectionQuality mocks base method.
+func (m *MockStore) DeleteOldNetworkConnectionQuality(ctx context.Context, beforeTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldNetworkConnectionQuality", ctx, beforeTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldNetworkConnectionQuality indicates an expected call of DeleteOldNetworkConnectionQuality.
+func (mr *MockStoreMockRecorder) DeleteOldNetworkConnectionQuality(ctx, beforeTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldNetworkConnectionQuality", reflect.TypeOf((*MockStore)(nil).DeleteOldNetworkConnectionQuality), ctx, beforeTime)
+}
+
 // DeleteOldNotificationMessages mocks base method.
 func (m *MockStore) DeleteOldNotificationMessages(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -717,6 +973,48 @@ func (mr *MockStoreMockRecorder) DeleteRuntimeConfig(ctx, key any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRuntimeConfig", reflect.TypeOf((*MockStore)(nil).DeleteRuntimeConfig), ctx, key)
 }
 
+// DeleteSCIMGroupMembers mocks base method.
+func (m *MockStore) DeleteSCIMGroupMembers(ctx context.Context, arg database.DeleteSCIMGroupMembersParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSCIMGroupMembers", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSCIMGroupMembers indicates an expected call of DeleteSCIMGroupMembers.
+func (mr *MockStoreMockRecorder) DeleteSCIMGroupMembers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSCIMGroupMembers", reflect.TypeOf((*MockStore)(nil).DeleteSCIMGroupMembers), ctx, arg)
+}
+
+// DeleteSCIMNestedGroups mocks base method.
+func (m *MockStore) DeleteSCIMNestedGroups(ctx context.Context, arg database.DeleteSCIMNestedGroupsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSCIMNestedGroups", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSCIMNestedGroups indicates an expected call of DeleteSCIMNestedGroups.
+func (mr *MockStoreMockRecorder) DeleteSCIMNestedGroups(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSCIMNestedGroups", reflect.TypeOf((*MockStore)(nil).DeleteSCIMNestedGroups), ctx, arg)
+}
+
+// DeleteStaleAPIKeySessionsByUserID mocks base method.
+func (m *MockStore) DeleteStaleAPIKeySessionsByUserID(ctx context.Context, arg database.DeleteStaleAPIKeySessionsByUserIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStaleAPIKeySessionsByUserID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStaleAPIKeySessionsByUserID indicates an expected call of DeleteStaleAPIKeySessionsByUserID.
+func (mr *MockStoreMockRecorder) DeleteStaleAPIKeySessionsByUserID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStaleAPIKeySessionsByUserID", reflect.TypeOf((*MockStore)(nil).DeleteStaleAPIKeySessionsByUserID), ctx, arg)
+}
+
 // DeleteTailnetAgent mocks base method.
 func (m *MockStore) DeleteTailnetAgent(ctx context.Context, arg database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	m.ctrl.T.Helper()
@@ -791,171 +1089,439 @@ func (mr *MockStoreMockRecorder) DeleteTailnetTunnel(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTailnetTunnel", reflect.TypeOf((*MockStore)(nil).DeleteTailnetTunnel), ctx, arg)
 }
 
-// DeleteWebpushSubscriptionByUserIDAndEndpoint mocks base method.
-func (m *MockStore) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
+// DeleteTemplateAppRestriction mocks base method.
+func (m *MockStore) DeleteTemplateAppRestriction(ctx context.Context, arg database.DeleteTemplateAppRestrictionParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteWebpushSubscriptionByUserIDAndEndpoint", ctx, arg)
+	ret := m.ctrl.Call(m, "DeleteTemplateAppRestriction", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteWebpushSubscriptionByUserIDAndEndpoint indicates an expected call of DeleteWebpushSubscriptionByUserIDAndEndpoint.
-func (mr *MockStoreMockRecorder) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx, arg any) *gomock.Call {
+// DeleteTemplateAppRestriction indicates an expected call of DeleteTemplateAppRestriction.
+func (mr *MockStoreMockRecorder) DeleteTemplateAppRestriction(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebpushSubscriptionByUserIDAndEndpoint", reflect.TypeOf((*MockStore)(nil).DeleteWebpushSubscriptionByUserIDAndEndpoint), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateAppRestriction", reflect.TypeOf((*MockStore)(nil).DeleteTemplateAppRestriction), ctx, arg)
 }
 
-// DeleteWebpushSubscriptions mocks base method.
-func (m *MockStore) DeleteWebpushSubscriptions(ctx context.Context, ids []uuid.UUID) error {
+// DeleteTemplateAppSessionPolicy mocks base method.
+func (m *MockStore) DeleteTemplateAppSessionPolicy(ctx context.Context, arg database.DeleteTemplateAppSessionPolicyParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteWebpushSubscriptions", ctx, ids)
+	ret := m.ctrl.Call(m, "DeleteTemplateAppSessionPolicy", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteWebpushSubscriptions indicates an expected call of DeleteWebpushSubscriptions.
-func (mr *MockStoreMockRecorder) DeleteWebpushSubscriptions(ctx, ids any) *gomock.Call {
+// DeleteTemplateAppSessionPolicy indicates an expected call of DeleteTemplateAppSessionPolicy.
+func (mr *MockStoreMockRecorder) DeleteTemplateAppSessionPolicy(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebpushSubscriptions", reflect.TypeOf((*MockStore)(nil).DeleteWebpushSubscriptions), ctx, ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateAppSessionPolicy", reflect.TypeOf((*MockStore)(nil).DeleteTemplateAppSessionPolicy), ctx, arg)
 }
 
-// DeleteWorkspaceAgentPortShare mocks base method.
-func (m *MockStore) DeleteWorkspaceAgentPortShare(ctx context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
+// DeleteTemplateBundleByName mocks base method.
+func (m *MockStore) DeleteTemplateBundleByName(ctx context.Context, arg database.DeleteTemplateBundleByNameParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteWorkspaceAgentPortShare", ctx, arg)
+	ret := m.ctrl.Call(m, "DeleteTemplateBundleByName", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteWorkspaceAgentPortShare indicates an expected call of DeleteWorkspaceAgentPortShare.
-func (mr *MockStoreMockRecorder) DeleteWorkspaceAgentPortShare(ctx, arg any) *gomock.Call {
+// DeleteTemplateBundleByName indicates an expected call of DeleteTemplateBundleByName.
+func (mr *MockStoreMockRecorder) DeleteTemplateBundleByName(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAgentPortShare", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAgentPortShare), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateBundleByName", reflect.TypeOf((*MockStore)(nil).DeleteTemplateBundleByName), ctx, arg)
 }
 
-// DeleteWorkspaceAgentPortSharesByTemplate mocks base method.
-func (m *MockStore) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context, templateID uuid.UUID) error {
+// DeleteTemplateEgressPolicy mocks base method.
+func (m *MockStore) DeleteTemplateEgressPolicy(ctx context.Context, templateID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteWorkspaceAgentPortSharesByTemplate", ctx, templateID)
+	ret := m.ctrl.Call(m, "DeleteTemplateEgressPolicy", ctx, templateID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteWorkspaceAgentPortSharesByTemplate indicates an expected call of DeleteWorkspaceAgentPortSharesByTemplate.
-func (mr *MockStoreMockRecorder) DeleteWorkspaceAgentPortSharesByTemplate(ctx, templateID any) *gomock.Call {
+// DeleteTemplateEgressPolicy indicates an expected call of DeleteTemplateEgressPolicy.
+func (mr *MockStoreMockRecorder) DeleteTemplateEgressPolicy(ctx, templateID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAgentPortSharesByTemplate", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAgentPortSharesByTemplate), ctx, templateID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateEgressPolicy", reflect.TypeOf((*MockStore)(nil).DeleteTemplateEgressPolicy), ctx, templateID)
 }
 
-// DeleteWorkspaceSubAgentByID mocks base method.
-func (m *MockStore) DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID) error {
+// DeleteTemplateParameterOptionSource mocks base method.
+func (m *MockStore) DeleteTemplateParameterOptionSource(ctx context.Context, arg database.DeleteTemplateParameterOptionSourceParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteWorkspaceSubAgentByID", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteTemplateParameterOptionSource", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteWorkspaceSubAgentByID indicates an expected call of DeleteWorkspaceSubAgentByID.
-func (mr *MockStoreMockRecorder) DeleteWorkspaceSubAgentByID(ctx, id any) *gomock.Call {
+// DeleteTemplateParameterOptionSource indicates an expected call of DeleteTemplateParameterOptionSource.
+func (mr *MockStoreMockRecorder) DeleteTemplateParameterOptionSource(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceSubAgentByID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceSubAgentByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateParameterOptionSource", reflect.TypeOf((*MockStore)(nil).DeleteTemplateParameterOptionSource), ctx, arg)
 }
 
-// DisableForeignKeysAndTriggers mocks base method.
-func (m *MockStore) DisableForeignKeysAndTriggers(ctx context.Context) error {
+// DeleteTemplateSecret mocks base method.
+func (m *MockStore) DeleteTemplateSecret(ctx context.Context, arg database.DeleteTemplateSecretParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DisableForeignKeysAndTriggers", ctx)
+	ret := m.ctrl.Call(m, "DeleteTemplateSecret", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DisableForeignKeysAndTriggers indicates an expected call of DisableForeignKeysAndTriggers.
-func (mr *MockStoreMockRecorder) DisableForeignKeysAndTriggers(ctx any) *gomock.Call {
+// DeleteTemplateSecret indicates an expected call of DeleteTemplateSecret.
+func (mr *MockStoreMockRecorder) DeleteTemplateSecret(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableForeignKeysAndTriggers", reflect.TypeOf((*MockStore)(nil).DisableForeignKeysAndTriggers), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateSecret", reflect.TypeOf((*MockStore)(nil).DeleteTemplateSecret), ctx, arg)
 }
 
-// EnqueueNotificationMessage mocks base method.
-func (m *MockStore) EnqueueNotificationMessage(ctx context.Context, arg database.EnqueueNotificationMessageParams) error {
+// DeleteTemplateVariableSet mocks base method.
+func (m *MockStore) DeleteTemplateVariableSet(ctx context.Context, arg database.DeleteTemplateVariableSetParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EnqueueNotificationMessage", ctx, arg)
+	ret := m.ctrl.Call(m, "DeleteTemplateVariableSet", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// EnqueueNotificationMessage indicates an expected call of EnqueueNotificationMessage.
-func (mr *MockStoreMockRecorder) EnqueueNotificationMessage(ctx, arg any) *gomock.Call {
+// DeleteTemplateVariableSet indicates an expected call of DeleteTemplateVariableSet.
+func (mr *MockStoreMockRecorder) DeleteTemplateVariableSet(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueNotificationMessage", reflect.TypeOf((*MockStore)(nil).EnqueueNotificationMessage), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateVariableSet", reflect.TypeOf((*MockStore)(nil).DeleteTemplateVariableSet), ctx, arg)
 }
 
-// FavoriteWorkspace mocks base method.
-func (m *MockStore) FavoriteWorkspace(ctx context.Context, id uuid.UUID) error {
+// DeleteTemplateVersionActivationReviews mocks base method.
+func (m *MockStore) DeleteTemplateVersionActivationReviews(ctx context.Context, templateVersionID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FavoriteWorkspace", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteTemplateVersionActivationReviews", ctx, templateVersionID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// FavoriteWorkspace indicates an expected call of FavoriteWorkspace.
-func (mr *MockStoreMockRecorder) FavoriteWorkspace(ctx, id any) *gomock.Call {
+// DeleteTemplateVersionActivationReviews indicates an expected call of DeleteTemplateVersionActivationReviews.
+func (mr *MockStoreMockRecorder) DeleteTemplateVersionActivationReviews(ctx, templateVersionID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteWorkspace", reflect.TypeOf((*MockStore)(nil).FavoriteWorkspace), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateVersionActivationReviews", reflect.TypeOf((*MockStore)(nil).DeleteTemplateVersionActivationReviews), ctx, templateVersionID)
 }
 
-// FetchMemoryResourceMonitorsByAgentID mocks base method.
-func (m *MockStore) FetchMemoryResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) (database.WorkspaceAgentMemoryResourceMonitor, error) {
+// DeleteUnreferencedExternalFiles mocks base method.
+func (m *MockStore) DeleteUnreferencedExternalFiles(ctx context.Context, createdBefore time.Time) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchMemoryResourceMonitorsByAgentID", ctx, agentID)
-	ret0, _ := ret[0].(database.WorkspaceAgentMemoryResourceMonitor)
+	ret := m.ctrl.Call(m, "DeleteUnreferencedExternalFiles", ctx, createdBefore)
+	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FetchMemoryResourceMonitorsByAgentID indicates an expected call of FetchMemoryResourceMonitorsByAgentID.
-func (mr *MockStoreMockRecorder) FetchMemoryResourceMonitorsByAgentID(ctx, agentID any) *gomock.Call {
+// DeleteUnreferencedExternalFiles indicates an expected call of DeleteUnreferencedExternalFiles.
+func (mr *MockStoreMockRecorder) DeleteUnreferencedExternalFiles(ctx, createdBefore any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchMemoryResourceMonitorsByAgentID", reflect.TypeOf((*MockStore)(nil).FetchMemoryResourceMonitorsByAgentID), ctx, agentID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUnreferencedExternalFiles", reflect.TypeOf((*MockStore)(nil).DeleteUnreferencedExternalFiles), ctx, createdBefore)
 }
 
-// FetchMemoryResourceMonitorsUpdatedAfter mocks base method.
-func (m *MockStore) FetchMemoryResourceMonitorsUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]database.WorkspaceAgentMemoryResourceMonitor, error) {
+// DeleteUserMFARecoveryCodes mocks base method.
+func (m *MockStore) DeleteUserMFARecoveryCodes(ctx context.Context, userID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchMemoryResourceMonitorsUpdatedAfter", ctx, updatedAt)
-	ret0, _ := ret[0].([]database.WorkspaceAgentMemoryResourceMonitor)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "DeleteUserMFARecoveryCodes", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// FetchMemoryResourceMonitorsUpdatedAfter indicates an expected call of FetchMemoryResourceMonitorsUpdatedAfter.
-func (mr *MockStoreMockRecorder) FetchMemoryResourceMonitorsUpdatedAfter(ctx, updatedAt any) *gomock.Call {
+// DeleteUserMFARecoveryCodes indicates an expected call of DeleteUserMFARecoveryCodes.
+func (mr *MockStoreMockRecorder) DeleteUserMFARecoveryCodes(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchMemoryResourceMonitorsUpdatedAfter", reflect.TypeOf((*MockStore)(nil).FetchMemoryResourceMonitorsUpdatedAfter), ctx, updatedAt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserMFARecoveryCodes", reflect.TypeOf((*MockStore)(nil).DeleteUserMFARecoveryCodes), ctx, userID)
 }
 
-// FetchNewMessageMetadata mocks base method.
-func (m *MockStore) FetchNewMessageMetadata(ctx context.Context, arg database.FetchNewMessageMetadataParams) (database.FetchNewMessageMetadataRow, error) {
+// DeleteUserSecret mocks base method.
+func (m *MockStore) DeleteUserSecret(ctx context.Context, arg database.DeleteUserSecretParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchNewMessageMetadata", ctx, arg)
-	ret0, _ := ret[0].(database.FetchNewMessageMetadataRow)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "DeleteUserSecret", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// FetchNewMessageMetadata indicates an expected call of FetchNewMessageMetadata.
-func (mr *MockStoreMockRecorder) FetchNewMessageMetadata(ctx, arg any) *gomock.Call {
+// DeleteUserSecret indicates an expected call of DeleteUserSecret.
+func (mr *MockStoreMockRecorder) DeleteUserSecret(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchNewMessageMetadata", reflect.TypeOf((*MockStore)(nil).FetchNewMessageMetadata), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSecret", reflect.TypeOf((*MockStore)(nil).DeleteUserSecret), ctx, arg)
 }
 
-// FetchVolumesResourceMonitorsByAgentID mocks base method.
-func (m *MockStore) FetchVolumesResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentVolumeResourceMonitor, error) {
+// DeleteUserWebAuthnCredential mocks base method.
+func (m *MockStore) DeleteUserWebAuthnCredential(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchVolumesResourceMonitorsByAgentID", ctx, agentID)
-	ret0, _ := ret[0].([]database.WorkspaceAgentVolumeResourceMonitor)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
+	ret := m.ctrl.Call(m, "DeleteUserWebAuthnCredential", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserWebAuthnCredential indicates an expected call of DeleteUserWebAuthnCredential.
+func (mr *MockStoreMockRecorder) DeleteUserWebAuthnCredential(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserWebAuthnCredential", reflect.TypeOf((*MockStore)(nil).DeleteUserWebAuthnCredential), ctx, id)
+}
+
+// DeleteVariableSet mocks base method.
+func (m *MockStore) DeleteVariableSet(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVariableSet", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVariableSet indicates an expected call of DeleteVariableSet.
+func (mr *MockStoreMockRecorder) DeleteVariableSet(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVariableSet", reflect.TypeOf((*MockStore)(nil).DeleteVariableSet), ctx, id)
+}
+
+// DeleteWebAuthnChallengeByID mocks base method.
+func (m *MockStore) DeleteWebAuthnChallengeByID(ctx context.Context, id uuid.UUID) (database.WebAuthnChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebAuthnChallengeByID", ctx, id)
+	ret0, _ := ret[0].(database.WebAuthnChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebAuthnChallengeByID indicates an expected call of DeleteWebAuthnChallengeByID.
+func (mr *MockStoreMockRecorder) DeleteWebAuthnChallengeByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebAuthnChallengeByID", reflect.TypeOf((*MockStore)(nil).DeleteWebAuthnChallengeByID), ctx, id)
+}
+
+// DeleteWebpushSubscriptionByUserIDAndEndpoint mocks base method.
+func (m *MockStore) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebpushSubscriptionByUserIDAndEndpoint", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebpushSubscriptionByUserIDAndEndpoint indicates an expected call of DeleteWebpushSubscriptionByUserIDAndEndpoint.
+func (mr *MockStoreMockRecorder) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebpushSubscriptionByUserIDAndEndpoint", reflect.TypeOf((*MockStore)(nil).DeleteWebpushSubscriptionByUserIDAndEndpoint), ctx, arg)
+}
+
+// DeleteWebpushSubscriptions mocks base method.
+func (m *MockStore) DeleteWebpushSubscriptions(ctx context.Context, ids []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebpushSubscriptions", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebpushSubscriptions indicates an expected call of DeleteWebpushSubscriptions.
+func (mr *MockStoreMockRecorder) DeleteWebpushSubscriptions(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebpushSubscriptions", reflect.TypeOf((*MockStore)(nil).DeleteWebpushSubscriptions), ctx, ids)
+}
+
+// DeleteWorkspaceAgentPortShare mocks base method.
+func (m *MockStore) DeleteWorkspaceAgentPortShare(ctx context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceAgentPortShare", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceAgentPortShare indicates an expected call of DeleteWorkspaceAgentPortShare.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceAgentPortShare(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAgentPortShare", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAgentPortShare), ctx, arg)
+}
+
+// DeleteWorkspaceAgentPortSharesByTemplate mocks base method.
+func (m *MockStore) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context, templateID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceAgentPortSharesByTemplate", ctx, templateID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceAgentPortSharesByTemplate indicates an expected call of DeleteWorkspaceAgentPortSharesByTemplate.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceAgentPortSharesByTemplate(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAgentPortSharesByTemplate", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAgentPortSharesByTemplate), ctx, templateID)
+}
+
+// DeleteWorkspaceArchiveByID mocks base method.
+func (m *MockStore) DeleteWorkspaceArchiveByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceArchiveByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceArchiveByID indicates an expected call of DeleteWorkspaceArchiveByID.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceArchiveByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceArchiveByID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceArchiveByID), ctx, id)
+}
+
+// DeleteWorkspaceLabelsByWorkspaceID mocks base method.
+func (m *MockStore) DeleteWorkspaceLabelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceLabelsByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceLabelsByWorkspaceID indicates an expected call of DeleteWorkspaceLabelsByWorkspaceID.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceLabelsByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceLabelsByWorkspaceID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceLabelsByWorkspaceID), ctx, workspaceID)
+}
+
+// DeleteWorkspacePortShareLink mocks base method.
+func (m *MockStore) DeleteWorkspacePortShareLink(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspacePortShareLink", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspacePortShareLink indicates an expected call of DeleteWorkspacePortShareLink.
+func (mr *MockStoreMockRecorder) DeleteWorkspacePortShareLink(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspacePortShareLink", reflect.TypeOf((*MockStore)(nil).DeleteWorkspacePortShareLink), ctx, id)
+}
+
+// DeleteWorkspacePortShareLinksByTemplate mocks base method.
+func (m *MockStore) DeleteWorkspacePortShareLinksByTemplate(ctx context.Context, templateID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspacePortShareLinksByTemplate", ctx, templateID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspacePortShareLinksByTemplate indicates an expected call of DeleteWorkspacePortShareLinksByTemplate.
+func (mr *MockStoreMockRecorder) DeleteWorkspacePortShareLinksByTemplate(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspacePortShareLinksByTemplate", reflect.TypeOf((*MockStore)(nil).DeleteWorkspacePortShareLinksByTemplate), ctx, templateID)
+}
+
+// DeleteWorkspaceSchedulePause mocks base method.
+func (m *MockStore) DeleteWorkspaceSchedulePause(ctx context.Context, workspaceID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceSchedulePause", ctx, workspaceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceSchedulePause indicates an expected call of DeleteWorkspaceSchedulePause.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceSchedulePause(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceSchedulePause", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceSchedulePause), ctx, workspaceID)
+}
+
+// DeleteWorkspaceSubAgentByID mocks base method.
+func (m *MockStore) DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceSubAgentByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceSubAgentByID indicates an expected call of DeleteWorkspaceSubAgentByID.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceSubAgentByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceSubAgentByID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceSubAgentByID), ctx, id)
+}
+
+// DisableForeignKeysAndTriggers mocks base method.
+func (m *MockStore) DisableForeignKeysAndTriggers(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableForeignKeysAndTriggers", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableForeignKeysAndTriggers indicates an expected call of DisableForeignKeysAndTriggers.
+func (mr *MockStoreMockRecorder) DisableForeignKeysAndTriggers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableForeignKeysAndTriggers", reflect.TypeOf((*MockStore)(nil).DisableForeignKeysAndTriggers), ctx)
+}
+
+// EnqueueNotificationMessage mocks base method.
+func (m *MockStore) EnqueueNotificationMessage(ctx context.Context, arg database.EnqueueNotificationMessageParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueNotificationMessage", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueNotificationMessage indicates an expected call of EnqueueNotificationMessage.
+func (mr *MockStoreMockRecorder) EnqueueNotificationMessage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueNotificationMessage", reflect.TypeOf((*MockStore)(nil).EnqueueNotificationMessage), ctx, arg)
+}
+
+// FavoriteWorkspace mocks base method.
+func (m *MockStore) FavoriteWorkspace(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FavoriteWorkspace", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FavoriteWorkspace indicates an expected call of FavoriteWorkspace.
+func (mr *MockStoreMockRecorder) FavoriteWorkspace(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteWorkspace", reflect.TypeOf((*MockStore)(nil).FavoriteWorkspace), ctx, id)
+}
+
+// FetchMemoryResourceMonitorsByAgentID mocks base method.
+func (m *MockStore) FetchMemoryResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) (database.WorkspaceAgentMemoryResourceMonitor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchMemoryResourceMonitorsByAgentID", ctx, agentID)
+	ret0, _ := ret[0].(database.WorkspaceAgentMemoryResourceMonitor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchMemoryResourceMonitorsByAgentID indicates an expected call of FetchMemoryResourceMonitorsByAgentID.
+func (mr *MockStoreMockRecorder) FetchMemoryResourceMonitorsByAgentID(ctx, agentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchMemoryResourceMonitorsByAgentID", reflect.TypeOf((*MockStore)(nil).FetchMemoryResourceMonitorsByAgentID), ctx, agentID)
+}
+
+// FetchMemoryResourceMonitorsUpdatedAfter mocks base method.
+func (m *MockStore) FetchMemoryResourceMonitorsUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]database.WorkspaceAgentMemoryResourceMonitor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchMemoryResourceMonitorsUpdatedAfter", ctx, updatedAt)
+	ret0, _ := ret[0].([]database.WorkspaceAgentMemoryResourceMonitor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchMemoryResourceMonitorsUpdatedAfter indicates an expected call of FetchMemoryResourceMonitorsUpdatedAfter.
+func (mr *MockStoreMockRecorder) FetchMemoryResourceMonitorsUpdatedAfter(ctx, updatedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchMemoryResourceMonitorsUpdatedAfter", reflect.TypeOf((*MockStore)(nil).FetchMemoryResourceMonitorsUpdatedAfter), ctx, updatedAt)
+}
+
+// FetchNewMessageMetadata mocks base method.
+func (m *MockStore) FetchNewMessageMetadata(ctx context.Context, arg database.FetchNewMessageMetadataParams) (database.FetchNewMessageMetadataRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchNewMessageMetadata", ctx, arg)
+	ret0, _ := ret[0].(database.FetchNewMessageMetadataRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchNewMessageMetadata indicates an expected call of FetchNewMessageMetadata.
+func (mr *MockStoreMockRecorder) FetchNewMessageMetadata(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchNewMessageMetadata", reflect.TypeOf((*MockStore)(nil).FetchNewMessageMetadata), ctx, arg)
+}
+
+// FetchVolumesResourceMonitorsByAgentID mocks base method.
+func (m *MockStore) FetchVolumesResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentVolumeResourceMonitor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchVolumesResourceMonitorsByAgentID", ctx, agentID)
+	ret0, _ := ret[0].([]database.WorkspaceAgentVolumeResourceMonitor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
 
 // FetchVolumesResourceMonitorsByAgentID indicates an expected call of FetchVolumesResourceMonitorsByAgentID.
 func (mr *MockStoreMockRecorder) FetchVolumesResourceMonitorsByAgentID(ctx, agentID any) *gomock.Call {
@@ -978,6 +1544,65 @@ func (mr *MockStoreMockRecorder) FetchVolumesResourceMonitorsUpdatedAfter(ctx, u
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchVolumesResourceMonitorsUpdatedAfter", reflect.TypeOf((*MockStore)(nil).FetchVolumesResourceMonitorsUpdatedAfter), ctx, updatedAt)
 }
 
+// FinishTemplateVersionRollout mocks base method.
+func (m *MockStore) FinishTemplateVersionRollout(ctx context.Context, arg database.FinishTemplateVersionRolloutParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishTemplateVersionRollout", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishTemplateVersionRollout indicates an expected call of FinishTemplateVersionRollout.
+func (mr *MockStoreMockRecorder) FinishTemplateVersionRollout(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishTemplateVersionRollout", reflect.TypeOf((*MockStore)(nil).FinishTemplateVersionRollout), ctx, arg)
+}
+
+// GetAITaskQueueEntriesByOwnerID mocks base method.
+func (m *MockStore) GetAITaskQueueEntriesByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]database.AITaskQueue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAITaskQueueEntriesByOwnerID", ctx, ownerID)
+	ret0, _ := ret[0].([]database.AITaskQueue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAITaskQueueEntriesByOwnerID indicates an expected call of GetAITaskQueueEntriesByOwnerID.
+func (mr *MockStoreMockRecorder) GetAITaskQueueEntriesByOwnerID(ctx, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAITaskQueueEntriesByOwnerID", reflect.TypeOf((*MockStore)(nil).GetAITaskQueueEntriesByOwnerID), ctx, ownerID)
+}
+
+// GetAITaskQueueEntryByID mocks base method.
+func (m *MockStore) GetAITaskQueueEntryByID(ctx context.Context, id uuid.UUID) (database.AITaskQueue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAITaskQueueEntryByID", ctx, id)
+	ret0, _ := ret[0].(database.AITaskQueue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAITaskQueueEntryByID indicates an expected call of GetAITaskQueueEntryByID.
+func (mr *MockStoreMockRecorder) GetAITaskQueueEntryByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAITaskQueueEntryByID", reflect.TypeOf((*MockStore)(nil).GetAITaskQueueEntryByID), ctx, id)
+}
+
+// GetAITaskQueueOwnerIDs mocks base method.
+func (m *MockStore) GetAITaskQueueOwnerIDs(ctx context.Context) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAITaskQueueOwnerIDs", ctx)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAITaskQueueOwnerIDs indicates an expected call of GetAITaskQueueOwnerIDs.
+func (mr *MockStoreMockRecorder) GetAITaskQueueOwnerIDs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAITaskQueueOwnerIDs", reflect.TypeOf((*MockStore)(nil).GetAITaskQueueOwnerIDs), ctx)
+}
+
 // GetAPIKeyByID mocks base method.
 func (m *MockStore) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	m.ctrl.T.Helper()
@@ -1008,6 +1633,21 @@ func (mr *MockStoreMockRecorder) GetAPIKeyByName(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByName", reflect.TypeOf((*MockStore)(nil).GetAPIKeyByName), ctx, arg)
 }
 
+// GetAPIKeySessionsByUserID mocks base method.
+func (m *MockStore) GetAPIKeySessionsByUserID(ctx context.Context, arg database.GetAPIKeySessionsByUserIDParams) ([]database.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeySessionsByUserID", ctx, arg)
+	ret0, _ := ret[0].([]database.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeySessionsByUserID indicates an expected call of GetAPIKeySessionsByUserID.
+func (mr *MockStoreMockRecorder) GetAPIKeySessionsByUserID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeySessionsByUserID", reflect.TypeOf((*MockStore)(nil).GetAPIKeySessionsByUserID), ctx, arg)
+}
+
 // GetAPIKeysByLoginType mocks base method.
 func (m *MockStore) GetAPIKeysByLoginType(ctx context.Context, loginType database.LoginType) ([]database.APIKey, error) {
 	m.ctrl.T.Helper()
@@ -1098,6 +1738,21 @@ func (mr *MockStoreMockRecorder) GetActiveWorkspaceBuildsByTemplateID(ctx, templ
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveWorkspaceBuildsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetActiveWorkspaceBuildsByTemplateID), ctx, templateID)
 }
 
+// GetActivityEvents mocks base method.
+func (m *MockStore) GetActivityEvents(ctx context.Context, arg database.GetActivityEventsParams) ([]database.GetActivityEventsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActivityEvents", ctx, arg)
+	ret0, _ := ret[0].([]database.GetActivityEventsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActivityEvents indicates an expected call of GetActivityEvents.
+func (mr *MockStoreMockRecorder) GetActivityEvents(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActivityEvents", reflect.TypeOf((*MockStore)(nil).GetActivityEvents), ctx, arg)
+}
+
 // GetAllTailnetAgents mocks base method.
 func (m *MockStore) GetAllTailnetAgents(ctx context.Context) ([]database.TailnetAgent, error) {
 	m.ctrl.T.Helper()
@@ -1173,34 +1828,64 @@ func (mr *MockStoreMockRecorder) GetAnnouncementBanners(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnnouncementBanners", reflect.TypeOf((*MockStore)(nil).GetAnnouncementBanners), ctx)
 }
 
-// GetAppSecurityKey mocks base method.
-func (m *MockStore) GetAppSecurityKey(ctx context.Context) (string, error) {
+// GetAppSecurityKey mocks base method.
+func (m *MockStore) GetAppSecurityKey(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppSecurityKey", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppSecurityKey indicates an expected call of GetAppSecurityKey.
+func (mr *MockStoreMockRecorder) GetAppSecurityKey(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppSecurityKey", reflect.TypeOf((*MockStore)(nil).GetAppSecurityKey), ctx)
+}
+
+// GetApplicationName mocks base method.
+func (m *MockStore) GetApplicationName(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplicationName", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApplicationName indicates an expected call of GetApplicationName.
+func (mr *MockStoreMockRecorder) GetApplicationName(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicationName", reflect.TypeOf((*MockStore)(nil).GetApplicationName), ctx)
+}
+
+// GetAuditLogLegalHoldByID mocks base method.
+func (m *MockStore) GetAuditLogLegalHoldByID(ctx context.Context, id uuid.UUID) (database.AuditLogLegalHold, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAppSecurityKey", ctx)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetAuditLogLegalHoldByID", ctx, id)
+	ret0, _ := ret[0].(database.AuditLogLegalHold)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAppSecurityKey indicates an expected call of GetAppSecurityKey.
-func (mr *MockStoreMockRecorder) GetAppSecurityKey(ctx any) *gomock.Call {
+// GetAuditLogLegalHoldByID indicates an expected call of GetAuditLogLegalHoldByID.
+func (mr *MockStoreMockRecorder) GetAuditLogLegalHoldByID(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppSecurityKey", reflect.TypeOf((*MockStore)(nil).GetAppSecurityKey), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogLegalHoldByID", reflect.TypeOf((*MockStore)(nil).GetAuditLogLegalHoldByID), ctx, id)
 }
 
-// GetApplicationName mocks base method.
-func (m *MockStore) GetApplicationName(ctx context.Context) (string, error) {
+// GetAuditLogLegalHoldsByOrganizationID mocks base method.
+func (m *MockStore) GetAuditLogLegalHoldsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.AuditLogLegalHold, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetApplicationName", ctx)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetAuditLogLegalHoldsByOrganizationID", ctx, organizationID)
+	ret0, _ := ret[0].([]database.AuditLogLegalHold)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetApplicationName indicates an expected call of GetApplicationName.
-func (mr *MockStoreMockRecorder) GetApplicationName(ctx any) *gomock.Call {
+// GetAuditLogLegalHoldsByOrganizationID indicates an expected call of GetAuditLogLegalHoldsByOrganizationID.
+func (mr *MockStoreMockRecorder) GetAuditLogLegalHoldsByOrganizationID(ctx, organizationID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicationName", reflect.TypeOf((*MockStore)(nil).GetApplicationName), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogLegalHoldsByOrganizationID", reflect.TypeOf((*MockStore)(nil).GetAuditLogLegalHoldsByOrganizationID), ctx, organizationID)
 }
 
 // GetAuditLogsOffset mocks base method.
@@ -1518,6 +2203,21 @@ func (mr *MockStoreMockRecorder) GetDeploymentWorkspaceStats(ctx any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeploymentWorkspaceStats", reflect.TypeOf((*MockStore)(nil).GetDeploymentWorkspaceStats), ctx)
 }
 
+// GetDueNotificationDigestMessages mocks base method.
+func (m *MockStore) GetDueNotificationDigestMessages(ctx context.Context, now time.Time) ([]database.GetDueNotificationDigestMessagesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueNotificationDigestMessages", ctx, now)
+	ret0, _ := ret[0].([]database.GetDueNotificationDigestMessagesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueNotificationDigestMessages indicates an expected call of GetDueNotificationDigestMessages.
+func (mr *MockStoreMockRecorder) GetDueNotificationDigestMessages(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueNotificationDigestMessages", reflect.TypeOf((*MockStore)(nil).GetDueNotificationDigestMessages), ctx, now)
+}
+
 // GetEligibleProvisionerDaemonsByProvisionerJobIDs mocks base method.
 func (m *MockStore) GetEligibleProvisionerDaemonsByProvisionerJobIDs(ctx context.Context, provisionerJobIds []uuid.UUID) ([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow, error) {
 	m.ctrl.T.Helper()
@@ -1533,6 +2233,21 @@ func (mr *MockStoreMockRecorder) GetEligibleProvisionerDaemonsByProvisionerJobID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEligibleProvisionerDaemonsByProvisionerJobIDs", reflect.TypeOf((*MockStore)(nil).GetEligibleProvisionerDaemonsByProvisionerJobIDs), ctx, provisionerJobIds)
 }
 
+// GetExperimentFlags mocks base method.
+func (m *MockStore) GetExperimentFlags(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExperimentFlags", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExperimentFlags indicates an expected call of GetExperimentFlags.
+func (mr *MockStoreMockRecorder) GetExperimentFlags(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExperimentFlags", reflect.TypeOf((*MockStore)(nil).GetExperimentFlags), ctx)
+}
+
 // GetExternalAuthLink mocks base method.
 func (m *MockStore) GetExternalAuthLink(ctx context.Context, arg database.GetExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	m.ctrl.T.Helper()
@@ -1743,6 +2458,36 @@ func (mr *MockStoreMockRecorder) GetGroupMembersCountByGroupID(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupMembersCountByGroupID", reflect.TypeOf((*MockStore)(nil).GetGroupMembersCountByGroupID), ctx, arg)
 }
 
+// GetGroupQuotaAllowances mocks base method.
+func (m *MockStore) GetGroupQuotaAllowances(ctx context.Context, groupID uuid.UUID) ([]database.GroupQuotaAllowance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupQuotaAllowances", ctx, groupID)
+	ret0, _ := ret[0].([]database.GroupQuotaAllowance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupQuotaAllowances indicates an expected call of GetGroupQuotaAllowances.
+func (mr *MockStoreMockRecorder) GetGroupQuotaAllowances(ctx, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupQuotaAllowances", reflect.TypeOf((*MockStore)(nil).GetGroupQuotaAllowances), ctx, groupID)
+}
+
+// GetGroupWorkspaceUsage mocks base method.
+func (m *MockStore) GetGroupWorkspaceUsage(ctx context.Context, arg database.GetGroupWorkspaceUsageParams) ([]database.GetGroupWorkspaceUsageRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupWorkspaceUsage", ctx, arg)
+	ret0, _ := ret[0].([]database.GetGroupWorkspaceUsageRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupWorkspaceUsage indicates an expected call of GetGroupWorkspaceUsage.
+func (mr *MockStoreMockRecorder) GetGroupWorkspaceUsage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupWorkspaceUsage", reflect.TypeOf((*MockStore)(nil).GetGroupWorkspaceUsage), ctx, arg)
+}
+
 // GetGroups mocks base method.
 func (m *MockStore) GetGroups(ctx context.Context, arg database.GetGroupsParams) ([]database.GetGroupsRow, error) {
 	m.ctrl.T.Helper()
@@ -1758,6 +2503,21 @@ func (mr *MockStoreMockRecorder) GetGroups(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroups", reflect.TypeOf((*MockStore)(nil).GetGroups), ctx, arg)
 }
 
+// GetHealthScores mocks base method.
+func (m *MockStore) GetHealthScores(ctx context.Context, arg database.GetHealthScoresParams) ([]database.HealthScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHealthScores", ctx, arg)
+	ret0, _ := ret[0].([]database.HealthScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHealthScores indicates an expected call of GetHealthScores.
+func (mr *MockStoreMockRecorder) GetHealthScores(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHealthScores", reflect.TypeOf((*MockStore)(nil).GetHealthScores), ctx, arg)
+}
+
 // GetHealthSettings mocks base method.
 func (m *MockStore) GetHealthSettings(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -1803,6 +2563,36 @@ func (mr *MockStoreMockRecorder) GetInboxNotificationsByUserID(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInboxNotificationsByUserID", reflect.TypeOf((*MockStore)(nil).GetInboxNotificationsByUserID), ctx, arg)
 }
 
+// GetInternalCA mocks base method.
+func (m *MockStore) GetInternalCA(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInternalCA", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInternalCA indicates an expected call of GetInternalCA.
+func (mr *MockStoreMockRecorder) GetInternalCA(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInternalCA", reflect.TypeOf((*MockStore)(nil).GetInternalCA), ctx)
+}
+
+// GetLDAPUsers mocks base method.
+func (m *MockStore) GetLDAPUsers(ctx context.Context) ([]database.LDAPUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLDAPUsers", ctx)
+	ret0, _ := ret[0].([]database.LDAPUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLDAPUsers indicates an expected call of GetLDAPUsers.
+func (mr *MockStoreMockRecorder) GetLDAPUsers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLDAPUsers", reflect.TypeOf((*MockStore)(nil).GetLDAPUsers), ctx)
+}
+
 // GetLastUpdateCheck mocks base method.
 func (m *MockStore) GetLastUpdateCheck(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -1848,6 +2638,21 @@ func (mr *MockStoreMockRecorder) GetLatestWorkspaceAppStatusesByWorkspaceIDs(ctx
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestWorkspaceAppStatusesByWorkspaceIDs", reflect.TypeOf((*MockStore)(nil).GetLatestWorkspaceAppStatusesByWorkspaceIDs), ctx, ids)
 }
 
+// GetLatestWorkspaceArchiveByWorkspaceID mocks base method.
+func (m *MockStore) GetLatestWorkspaceArchiveByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestWorkspaceArchiveByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(database.WorkspaceArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestWorkspaceArchiveByWorkspaceID indicates an expected call of GetLatestWorkspaceArchiveByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetLatestWorkspaceArchiveByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestWorkspaceArchiveByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetLatestWorkspaceArchiveByWorkspaceID), ctx, workspaceID)
+}
+
 // GetLatestWorkspaceBuildByWorkspaceID mocks base method.
 func (m *MockStore) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
 	m.ctrl.T.Helper()
@@ -1908,6 +2713,21 @@ func (mr *MockStoreMockRecorder) GetLicenseByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenseByID", reflect.TypeOf((*MockStore)(nil).GetLicenseByID), ctx, id)
 }
 
+// GetLicenseSeatReservations mocks base method.
+func (m *MockStore) GetLicenseSeatReservations(ctx context.Context) ([]database.GetLicenseSeatReservationsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLicenseSeatReservations", ctx)
+	ret0, _ := ret[0].([]database.GetLicenseSeatReservationsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLicenseSeatReservations indicates an expected call of GetLicenseSeatReservations.
+func (mr *MockStoreMockRecorder) GetLicenseSeatReservations(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenseSeatReservations", reflect.TypeOf((*MockStore)(nil).GetLicenseSeatReservations), ctx)
+}
+
 // GetLicenses mocks base method.
 func (m *MockStore) GetLicenses(ctx context.Context) ([]database.License, error) {
 	m.ctrl.T.Helper()
@@ -1938,6 +2758,51 @@ func (mr *MockStoreMockRecorder) GetLogoURL(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogoURL", reflect.TypeOf((*MockStore)(nil).GetLogoURL), ctx)
 }
 
+// GetNetworkConnectionQuality mocks base method.
+func (m *MockStore) GetNetworkConnectionQuality(ctx context.Context, arg database.GetNetworkConnectionQualityParams) (database.NetworkConnectionQuality, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNetworkConnectionQuality", ctx, arg)
+	ret0, _ := ret[0].(database.NetworkConnectionQuality)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNetworkConnectionQuality indicates an expected call of GetNetworkConnectionQuality.
+func (mr *MockStoreMockRecorder) GetNetworkConnectionQuality(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetworkConnectionQuality", reflect.TypeOf((*MockStore)(nil).GetNetworkConnectionQuality), ctx, arg)
+}
+
+// GetNetworkConnectionQualityCounters mocks base method.
+func (m *MockStore) GetNetworkConnectionQualityCounters(ctx context.Context) ([]database.NetworkConnectionQualityCounter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNetworkConnectionQualityCounters", ctx)
+	ret0, _ := ret[0].([]database.NetworkConnectionQualityCounter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNetworkConnectionQualityCounters indicates an expected call of GetNetworkConnectionQualityCounters.
+func (mr *MockStoreMockRecorder) GetNetworkConnectionQualityCounters(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetworkConnectionQualityCounters", reflect.TypeOf((*MockStore)(nil).GetNetworkConnectionQualityCounters), ctx)
+}
+
+// GetNetworkConnectionQualitySummary mocks base method.
+func (m *MockStore) GetNetworkConnectionQualitySummary(ctx context.Context, updatedAfter time.Time) ([]database.GetNetworkConnectionQualitySummaryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNetworkConnectionQualitySummary", ctx, updatedAfter)
+	ret0, _ := ret[0].([]database.GetNetworkConnectionQualitySummaryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNetworkConnectionQualitySummary indicates an expected call of GetNetworkConnectionQualitySummary.
+func (mr *MockStoreMockRecorder) GetNetworkConnectionQualitySummary(ctx, updatedAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetworkConnectionQualitySummary", reflect.TypeOf((*MockStore)(nil).GetNetworkConnectionQualitySummary), ctx, updatedAfter)
+}
+
 // GetNotificationMessagesByStatus mocks base method.
 func (m *MockStore) GetNotificationMessagesByStatus(ctx context.Context, arg database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
 	m.ctrl.T.Helper()
@@ -2103,6 +2968,36 @@ func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppCodeByPrefix(ctx, secretPre
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppCodeByPrefix", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppCodeByPrefix), ctx, secretPrefix)
 }
 
+// GetOAuth2ProviderAppDeviceCodeByPrefix mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppDeviceCodeByPrefix(ctx context.Context, deviceCodePrefix []byte) (database.OAuth2ProviderAppDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppDeviceCodeByPrefix", ctx, deviceCodePrefix)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuth2ProviderAppDeviceCodeByPrefix indicates an expected call of GetOAuth2ProviderAppDeviceCodeByPrefix.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppDeviceCodeByPrefix(ctx, deviceCodePrefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppDeviceCodeByPrefix", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppDeviceCodeByPrefix), ctx, deviceCodePrefix)
+}
+
+// GetOAuth2ProviderAppDeviceCodeByUserCode mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppDeviceCodeByUserCode(ctx context.Context, userCode string) (database.OAuth2ProviderAppDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppDeviceCodeByUserCode", ctx, userCode)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuth2ProviderAppDeviceCodeByUserCode indicates an expected call of GetOAuth2ProviderAppDeviceCodeByUserCode.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppDeviceCodeByUserCode(ctx, userCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppDeviceCodeByUserCode", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppDeviceCodeByUserCode), ctx, userCode)
+}
+
 // GetOAuth2ProviderAppSecretByID mocks base method.
 func (m *MockStore) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
 	m.ctrl.T.Helper()
@@ -2223,6 +3118,21 @@ func (mr *MockStoreMockRecorder) GetOAuthSigningKey(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthSigningKey", reflect.TypeOf((*MockStore)(nil).GetOAuthSigningKey), ctx)
 }
 
+// GetOrganizationAuditSettings mocks base method.
+func (m *MockStore) GetOrganizationAuditSettings(ctx context.Context, organizationID uuid.UUID) (database.OrganizationAuditSetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationAuditSettings", ctx, organizationID)
+	ret0, _ := ret[0].(database.OrganizationAuditSetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationAuditSettings indicates an expected call of GetOrganizationAuditSettings.
+func (mr *MockStoreMockRecorder) GetOrganizationAuditSettings(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationAuditSettings", reflect.TypeOf((*MockStore)(nil).GetOrganizationAuditSettings), ctx, organizationID)
+}
+
 // GetOrganizationByID mocks base method.
 func (m *MockStore) GetOrganizationByID(ctx context.Context, id uuid.UUID) (database.Organization, error) {
 	m.ctrl.T.Helper()
@@ -2268,6 +3178,21 @@ func (mr *MockStoreMockRecorder) GetOrganizationIDsByMemberIDs(ctx, ids any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationIDsByMemberIDs", reflect.TypeOf((*MockStore)(nil).GetOrganizationIDsByMemberIDs), ctx, ids)
 }
 
+// GetOrganizationMFASettings mocks base method.
+func (m *MockStore) GetOrganizationMFASettings(ctx context.Context, organizationID uuid.UUID) (database.OrganizationMFASetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationMFASettings", ctx, organizationID)
+	ret0, _ := ret[0].(database.OrganizationMFASetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationMFASettings indicates an expected call of GetOrganizationMFASettings.
+func (mr *MockStoreMockRecorder) GetOrganizationMFASettings(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationMFASettings", reflect.TypeOf((*MockStore)(nil).GetOrganizationMFASettings), ctx, organizationID)
+}
+
 // GetOrganizationResourceCountByID mocks base method.
 func (m *MockStore) GetOrganizationResourceCountByID(ctx context.Context, organizationID uuid.UUID) (database.GetOrganizationResourceCountByIDRow, error) {
 	m.ctrl.T.Helper()
@@ -2283,6 +3208,21 @@ func (mr *MockStoreMockRecorder) GetOrganizationResourceCountByID(ctx, organizat
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationResourceCountByID", reflect.TypeOf((*MockStore)(nil).GetOrganizationResourceCountByID), ctx, organizationID)
 }
 
+// GetOrganizationSettingOverrides mocks base method.
+func (m *MockStore) GetOrganizationSettingOverrides(ctx context.Context, organizationID uuid.UUID) (database.OrganizationSettingOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationSettingOverrides", ctx, organizationID)
+	ret0, _ := ret[0].(database.OrganizationSettingOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationSettingOverrides indicates an expected call of GetOrganizationSettingOverrides.
+func (mr *MockStoreMockRecorder) GetOrganizationSettingOverrides(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationSettingOverrides", reflect.TypeOf((*MockStore)(nil).GetOrganizationSettingOverrides), ctx, organizationID)
+}
+
 // GetOrganizations mocks base method.
 func (m *MockStore) GetOrganizations(ctx context.Context, arg database.GetOrganizationsParams) ([]database.Organization, error) {
 	m.ctrl.T.Helper()
@@ -2313,6 +3253,21 @@ func (mr *MockStoreMockRecorder) GetOrganizationsByUserID(ctx, arg any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationsByUserID", reflect.TypeOf((*MockStore)(nil).GetOrganizationsByUserID), ctx, arg)
 }
 
+// GetOrphanedProvisionerJobs mocks base method.
+func (m *MockStore) GetOrphanedProvisionerJobs(ctx context.Context, arg database.GetOrphanedProvisionerJobsParams) ([]database.GetOrphanedProvisionerJobsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrphanedProvisionerJobs", ctx, arg)
+	ret0, _ := ret[0].([]database.GetOrphanedProvisionerJobsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrphanedProvisionerJobs indicates an expected call of GetOrphanedProvisionerJobs.
+func (mr *MockStoreMockRecorder) GetOrphanedProvisionerJobs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrphanedProvisionerJobs", reflect.TypeOf((*MockStore)(nil).GetOrphanedProvisionerJobs), ctx, arg)
+}
+
 // GetParameterSchemasByJobID mocks base method.
 func (m *MockStore) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
 	m.ctrl.T.Helper()
@@ -2328,6 +3283,21 @@ func (mr *MockStoreMockRecorder) GetParameterSchemasByJobID(ctx, jobID any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParameterSchemasByJobID", reflect.TypeOf((*MockStore)(nil).GetParameterSchemasByJobID), ctx, jobID)
 }
 
+// GetPendingProvisionerJobsCount mocks base method.
+func (m *MockStore) GetPendingProvisionerJobsCount(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingProvisionerJobsCount", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingProvisionerJobsCount indicates an expected call of GetPendingProvisionerJobsCount.
+func (mr *MockStoreMockRecorder) GetPendingProvisionerJobsCount(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingProvisionerJobsCount", reflect.TypeOf((*MockStore)(nil).GetPendingProvisionerJobsCount), ctx)
+}
+
 // GetPrebuildMetrics mocks base method.
 func (m *MockStore) GetPrebuildMetrics(ctx context.Context) ([]database.GetPrebuildMetricsRow, error) {
 	m.ctrl.T.Helper()
@@ -2553,6 +3523,21 @@ func (mr *MockStoreMockRecorder) GetProvisionerJobByIDForUpdate(ctx, id any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobByIDForUpdate", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobByIDForUpdate), ctx, id)
 }
 
+// GetProvisionerJobCountsByOrganization mocks base method.
+func (m *MockStore) GetProvisionerJobCountsByOrganization(ctx context.Context) ([]database.GetProvisionerJobCountsByOrganizationRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobCountsByOrganization", ctx)
+	ret0, _ := ret[0].([]database.GetProvisionerJobCountsByOrganizationRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobCountsByOrganization indicates an expected call of GetProvisionerJobCountsByOrganization.
+func (mr *MockStoreMockRecorder) GetProvisionerJobCountsByOrganization(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobCountsByOrganization", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobCountsByOrganization), ctx)
+}
+
 // GetProvisionerJobTimingsByJobID mocks base method.
 func (m *MockStore) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
 	m.ctrl.T.Helper()
@@ -2733,6 +3718,51 @@ func (mr *MockStoreMockRecorder) GetQuotaConsumedForUser(ctx, arg any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaConsumedForUser", reflect.TypeOf((*MockStore)(nil).GetQuotaConsumedForUser), ctx, arg)
 }
 
+// GetQuotaDimensionAllowancesForUser mocks base method.
+func (m *MockStore) GetQuotaDimensionAllowancesForUser(ctx context.Context, arg database.GetQuotaDimensionAllowancesForUserParams) ([]database.GetQuotaDimensionAllowancesForUserRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuotaDimensionAllowancesForUser", ctx, arg)
+	ret0, _ := ret[0].([]database.GetQuotaDimensionAllowancesForUserRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuotaDimensionAllowancesForUser indicates an expected call of GetQuotaDimensionAllowancesForUser.
+func (mr *MockStoreMockRecorder) GetQuotaDimensionAllowancesForUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaDimensionAllowancesForUser", reflect.TypeOf((*MockStore)(nil).GetQuotaDimensionAllowancesForUser), ctx, arg)
+}
+
+// GetQuotaDimensionsConsumedForGroup mocks base method.
+func (m *MockStore) GetQuotaDimensionsConsumedForGroup(ctx context.Context, groupID uuid.UUID) ([]database.GetQuotaDimensionsConsumedForGroupRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuotaDimensionsConsumedForGroup", ctx, groupID)
+	ret0, _ := ret[0].([]database.GetQuotaDimensionsConsumedForGroupRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuotaDimensionsConsumedForGroup indicates an expected call of GetQuotaDimensionsConsumedForGroup.
+func (mr *MockStoreMockRecorder) GetQuotaDimensionsConsumedForGroup(ctx, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaDimensionsConsumedForGroup", reflect.TypeOf((*MockStore)(nil).GetQuotaDimensionsConsumedForGroup), ctx, groupID)
+}
+
+// GetQuotaDimensionsConsumedForUser mocks base method.
+func (m *MockStore) GetQuotaDimensionsConsumedForUser(ctx context.Context, arg database.GetQuotaDimensionsConsumedForUserParams) ([]database.GetQuotaDimensionsConsumedForUserRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuotaDimensionsConsumedForUser", ctx, arg)
+	ret0, _ := ret[0].([]database.GetQuotaDimensionsConsumedForUserRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuotaDimensionsConsumedForUser indicates an expected call of GetQuotaDimensionsConsumedForUser.
+func (mr *MockStoreMockRecorder) GetQuotaDimensionsConsumedForUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaDimensionsConsumedForUser", reflect.TypeOf((*MockStore)(nil).GetQuotaDimensionsConsumedForUser), ctx, arg)
+}
+
 // GetReplicaByID mocks base method.
 func (m *MockStore) GetReplicaByID(ctx context.Context, id uuid.UUID) (database.Replica, error) {
 	m.ctrl.T.Helper()
@@ -2763,6 +3793,51 @@ func (mr *MockStoreMockRecorder) GetReplicasUpdatedAfter(ctx, updatedAt any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicasUpdatedAfter", reflect.TypeOf((*MockStore)(nil).GetReplicasUpdatedAfter), ctx, updatedAt)
 }
 
+// GetReviewWorkspace mocks base method.
+func (m *MockStore) GetReviewWorkspace(ctx context.Context, arg database.GetReviewWorkspaceParams) (database.ReviewWorkspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewWorkspace", ctx, arg)
+	ret0, _ := ret[0].(database.ReviewWorkspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewWorkspace indicates an expected call of GetReviewWorkspace.
+func (mr *MockStoreMockRecorder) GetReviewWorkspace(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewWorkspace", reflect.TypeOf((*MockStore)(nil).GetReviewWorkspace), ctx, arg)
+}
+
+// GetReviewWorkspacesByRepositoryAndBranch mocks base method.
+func (m *MockStore) GetReviewWorkspacesByRepositoryAndBranch(ctx context.Context, arg database.GetReviewWorkspacesByRepositoryAndBranchParams) ([]database.ReviewWorkspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewWorkspacesByRepositoryAndBranch", ctx, arg)
+	ret0, _ := ret[0].([]database.ReviewWorkspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewWorkspacesByRepositoryAndBranch indicates an expected call of GetReviewWorkspacesByRepositoryAndBranch.
+func (mr *MockStoreMockRecorder) GetReviewWorkspacesByRepositoryAndBranch(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewWorkspacesByRepositoryAndBranch", reflect.TypeOf((*MockStore)(nil).GetReviewWorkspacesByRepositoryAndBranch), ctx, arg)
+}
+
+// GetRollbackWorkspaceBuild mocks base method.
+func (m *MockStore) GetRollbackWorkspaceBuild(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRollbackWorkspaceBuild", ctx, workspaceID)
+	ret0, _ := ret[0].(database.WorkspaceBuild)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRollbackWorkspaceBuild indicates an expected call of GetRollbackWorkspaceBuild.
+func (mr *MockStoreMockRecorder) GetRollbackWorkspaceBuild(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRollbackWorkspaceBuild", reflect.TypeOf((*MockStore)(nil).GetRollbackWorkspaceBuild), ctx, workspaceID)
+}
+
 // GetRunningPrebuiltWorkspaces mocks base method.
 func (m *MockStore) GetRunningPrebuiltWorkspaces(ctx context.Context) ([]database.GetRunningPrebuiltWorkspacesRow, error) {
 	m.ctrl.T.Helper()
@@ -2778,34 +3853,64 @@ func (mr *MockStoreMockRecorder) GetRunningPrebuiltWorkspaces(ctx any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunningPrebuiltWorkspaces", reflect.TypeOf((*MockStore)(nil).GetRunningPrebuiltWorkspaces), ctx)
 }
 
-// GetRunningPrebuiltWorkspacesOptimized mocks base method.
-func (m *MockStore) GetRunningPrebuiltWorkspacesOptimized(ctx context.Context) ([]database.GetRunningPrebuiltWorkspacesOptimizedRow, error) {
+// GetRunningPrebuiltWorkspacesOptimized mocks base method.
+func (m *MockStore) GetRunningPrebuiltWorkspacesOptimized(ctx context.Context) ([]database.GetRunningPrebuiltWorkspacesOptimizedRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRunningPrebuiltWorkspacesOptimized", ctx)
+	ret0, _ := ret[0].([]database.GetRunningPrebuiltWorkspacesOptimizedRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRunningPrebuiltWorkspacesOptimized indicates an expected call of GetRunningPrebuiltWorkspacesOptimized.
+func (mr *MockStoreMockRecorder) GetRunningPrebuiltWorkspacesOptimized(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunningPrebuiltWorkspacesOptimized", reflect.TypeOf((*MockStore)(nil).GetRunningPrebuiltWorkspacesOptimized), ctx)
+}
+
+// GetRuntimeConfig mocks base method.
+func (m *MockStore) GetRuntimeConfig(ctx context.Context, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRuntimeConfig", ctx, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRuntimeConfig indicates an expected call of GetRuntimeConfig.
+func (mr *MockStoreMockRecorder) GetRuntimeConfig(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuntimeConfig", reflect.TypeOf((*MockStore)(nil).GetRuntimeConfig), ctx, key)
+}
+
+// GetSCIMGroupMembers mocks base method.
+func (m *MockStore) GetSCIMGroupMembers(ctx context.Context, organizationID uuid.UUID) ([]database.SCIMGroupMember, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRunningPrebuiltWorkspacesOptimized", ctx)
-	ret0, _ := ret[0].([]database.GetRunningPrebuiltWorkspacesOptimizedRow)
+	ret := m.ctrl.Call(m, "GetSCIMGroupMembers", ctx, organizationID)
+	ret0, _ := ret[0].([]database.SCIMGroupMember)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRunningPrebuiltWorkspacesOptimized indicates an expected call of GetRunningPrebuiltWorkspacesOptimized.
-func (mr *MockStoreMockRecorder) GetRunningPrebuiltWorkspacesOptimized(ctx any) *gomock.Call {
+// GetSCIMGroupMembers indicates an expected call of GetSCIMGroupMembers.
+func (mr *MockStoreMockRecorder) GetSCIMGroupMembers(ctx, organizationID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunningPrebuiltWorkspacesOptimized", reflect.TypeOf((*MockStore)(nil).GetRunningPrebuiltWorkspacesOptimized), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSCIMGroupMembers", reflect.TypeOf((*MockStore)(nil).GetSCIMGroupMembers), ctx, organizationID)
 }
 
-// GetRuntimeConfig mocks base method.
-func (m *MockStore) GetRuntimeConfig(ctx context.Context, key string) (string, error) {
+// GetSCIMNestedGroups mocks base method.
+func (m *MockStore) GetSCIMNestedGroups(ctx context.Context, organizationID uuid.UUID) ([]database.SCIMNestedGroup, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRuntimeConfig", ctx, key)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetSCIMNestedGroups", ctx, organizationID)
+	ret0, _ := ret[0].([]database.SCIMNestedGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRuntimeConfig indicates an expected call of GetRuntimeConfig.
-func (mr *MockStoreMockRecorder) GetRuntimeConfig(ctx, key any) *gomock.Call {
+// GetSCIMNestedGroups indicates an expected call of GetSCIMNestedGroups.
+func (mr *MockStoreMockRecorder) GetSCIMNestedGroups(ctx, organizationID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuntimeConfig", reflect.TypeOf((*MockStore)(nil).GetRuntimeConfig), ctx, key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSCIMNestedGroups", reflect.TypeOf((*MockStore)(nil).GetSCIMNestedGroups), ctx, organizationID)
 }
 
 // GetTailnetAgents mocks base method.
@@ -2913,6 +4018,36 @@ func (mr *MockStoreMockRecorder) GetTelemetryItems(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTelemetryItems", reflect.TypeOf((*MockStore)(nil).GetTelemetryItems), ctx)
 }
 
+// GetTemplateActivationForUpdate mocks base method.
+func (m *MockStore) GetTemplateActivationForUpdate(ctx context.Context, id uuid.UUID) (database.GetTemplateActivationForUpdateRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateActivationForUpdate", ctx, id)
+	ret0, _ := ret[0].(database.GetTemplateActivationForUpdateRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateActivationForUpdate indicates an expected call of GetTemplateActivationForUpdate.
+func (mr *MockStoreMockRecorder) GetTemplateActivationForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateActivationForUpdate", reflect.TypeOf((*MockStore)(nil).GetTemplateActivationForUpdate), ctx, id)
+}
+
+// GetTemplateActivationReviewers mocks base method.
+func (m *MockStore) GetTemplateActivationReviewers(ctx context.Context, templateID uuid.UUID) ([]database.GetTemplateActivationReviewersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateActivationReviewers", ctx, templateID)
+	ret0, _ := ret[0].([]database.GetTemplateActivationReviewersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateActivationReviewers indicates an expected call of GetTemplateActivationReviewers.
+func (mr *MockStoreMockRecorder) GetTemplateActivationReviewers(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateActivationReviewers", reflect.TypeOf((*MockStore)(nil).GetTemplateActivationReviewers), ctx, templateID)
+}
+
 // GetTemplateAppInsights mocks base method.
 func (m *MockStore) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	m.ctrl.T.Helper()
@@ -2943,6 +4078,51 @@ func (mr *MockStoreMockRecorder) GetTemplateAppInsightsByTemplate(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateAppInsightsByTemplate", reflect.TypeOf((*MockStore)(nil).GetTemplateAppInsightsByTemplate), ctx, arg)
 }
 
+// GetTemplateAppRestrictions mocks base method.
+func (m *MockStore) GetTemplateAppRestrictions(ctx context.Context, templateID uuid.UUID) ([]database.TemplateAppRestriction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateAppRestrictions", ctx, templateID)
+	ret0, _ := ret[0].([]database.TemplateAppRestriction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateAppRestrictions indicates an expected call of GetTemplateAppRestrictions.
+func (mr *MockStoreMockRecorder) GetTemplateAppRestrictions(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateAppRestrictions", reflect.TypeOf((*MockStore)(nil).GetTemplateAppRestrictions), ctx, templateID)
+}
+
+// GetTemplateAppRestrictionsByTemplateIDs mocks base method.
+func (m *MockStore) GetTemplateAppRestrictionsByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.TemplateAppRestriction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateAppRestrictionsByTemplateIDs", ctx, templateIds)
+	ret0, _ := ret[0].([]database.TemplateAppRestriction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateAppRestrictionsByTemplateIDs indicates an expected call of GetTemplateAppRestrictionsByTemplateIDs.
+func (mr *MockStoreMockRecorder) GetTemplateAppRestrictionsByTemplateIDs(ctx, templateIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateAppRestrictionsByTemplateIDs", reflect.TypeOf((*MockStore)(nil).GetTemplateAppRestrictionsByTemplateIDs), ctx, templateIds)
+}
+
+// GetTemplateAppSessionPolicies mocks base method.
+func (m *MockStore) GetTemplateAppSessionPolicies(ctx context.Context, templateID uuid.UUID) ([]database.TemplateAppSessionPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateAppSessionPolicies", ctx, templateID)
+	ret0, _ := ret[0].([]database.TemplateAppSessionPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateAppSessionPolicies indicates an expected call of GetTemplateAppSessionPolicies.
+func (mr *MockStoreMockRecorder) GetTemplateAppSessionPolicies(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateAppSessionPolicies", reflect.TypeOf((*MockStore)(nil).GetTemplateAppSessionPolicies), ctx, templateID)
+}
+
 // GetTemplateAverageBuildTime mocks base method.
 func (m *MockStore) GetTemplateAverageBuildTime(ctx context.Context, arg database.GetTemplateAverageBuildTimeParams) (database.GetTemplateAverageBuildTimeRow, error) {
 	m.ctrl.T.Helper()
@@ -2958,6 +4138,81 @@ func (mr *MockStoreMockRecorder) GetTemplateAverageBuildTime(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateAverageBuildTime", reflect.TypeOf((*MockStore)(nil).GetTemplateAverageBuildTime), ctx, arg)
 }
 
+// GetTemplateBuildFailures mocks base method.
+func (m *MockStore) GetTemplateBuildFailures(ctx context.Context, arg database.GetTemplateBuildFailuresParams) ([]database.GetTemplateBuildFailuresRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateBuildFailures", ctx, arg)
+	ret0, _ := ret[0].([]database.GetTemplateBuildFailuresRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateBuildFailures indicates an expected call of GetTemplateBuildFailures.
+func (mr *MockStoreMockRecorder) GetTemplateBuildFailures(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateBuildFailures", reflect.TypeOf((*MockStore)(nil).GetTemplateBuildFailures), ctx, arg)
+}
+
+// GetTemplateBuildInsightsByInterval mocks base method.
+func (m *MockStore) GetTemplateBuildInsightsByInterval(ctx context.Context, arg database.GetTemplateBuildInsightsByIntervalParams) ([]database.GetTemplateBuildInsightsByIntervalRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateBuildInsightsByInterval", ctx, arg)
+	ret0, _ := ret[0].([]database.GetTemplateBuildInsightsByIntervalRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateBuildInsightsByInterval indicates an expected call of GetTemplateBuildInsightsByInterval.
+func (mr *MockStoreMockRecorder) GetTemplateBuildInsightsByInterval(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateBuildInsightsByInterval", reflect.TypeOf((*MockStore)(nil).GetTemplateBuildInsightsByInterval), ctx, arg)
+}
+
+// GetTemplateBuildStageDurations mocks base method.
+func (m *MockStore) GetTemplateBuildStageDurations(ctx context.Context, arg database.GetTemplateBuildStageDurationsParams) ([]database.GetTemplateBuildStageDurationsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateBuildStageDurations", ctx, arg)
+	ret0, _ := ret[0].([]database.GetTemplateBuildStageDurationsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateBuildStageDurations indicates an expected call of GetTemplateBuildStageDurations.
+func (mr *MockStoreMockRecorder) GetTemplateBuildStageDurations(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateBuildStageDurations", reflect.TypeOf((*MockStore)(nil).GetTemplateBuildStageDurations), ctx, arg)
+}
+
+// GetTemplateBundleByName mocks base method.
+func (m *MockStore) GetTemplateBundleByName(ctx context.Context, arg database.GetTemplateBundleByNameParams) (database.TemplateBundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateBundleByName", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateBundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateBundleByName indicates an expected call of GetTemplateBundleByName.
+func (mr *MockStoreMockRecorder) GetTemplateBundleByName(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateBundleByName", reflect.TypeOf((*MockStore)(nil).GetTemplateBundleByName), ctx, arg)
+}
+
+// GetTemplateBundlesByOrganizationID mocks base method.
+func (m *MockStore) GetTemplateBundlesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.GetTemplateBundlesByOrganizationIDRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateBundlesByOrganizationID", ctx, organizationID)
+	ret0, _ := ret[0].([]database.GetTemplateBundlesByOrganizationIDRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateBundlesByOrganizationID indicates an expected call of GetTemplateBundlesByOrganizationID.
+func (mr *MockStoreMockRecorder) GetTemplateBundlesByOrganizationID(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateBundlesByOrganizationID", reflect.TypeOf((*MockStore)(nil).GetTemplateBundlesByOrganizationID), ctx, organizationID)
+}
+
 // GetTemplateByID mocks base method.
 func (m *MockStore) GetTemplateByID(ctx context.Context, id uuid.UUID) (database.Template, error) {
 	m.ctrl.T.Helper()
@@ -3003,6 +4258,21 @@ func (mr *MockStoreMockRecorder) GetTemplateDAUs(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateDAUs", reflect.TypeOf((*MockStore)(nil).GetTemplateDAUs), ctx, arg)
 }
 
+// GetTemplateEgressPolicyByTemplateID mocks base method.
+func (m *MockStore) GetTemplateEgressPolicyByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateEgressPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateEgressPolicyByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].(database.TemplateEgressPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateEgressPolicyByTemplateID indicates an expected call of GetTemplateEgressPolicyByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplateEgressPolicyByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateEgressPolicyByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplateEgressPolicyByTemplateID), ctx, templateID)
+}
+
 // GetTemplateGroupRoles mocks base method.
 func (m *MockStore) GetTemplateGroupRoles(ctx context.Context, id uuid.UUID) ([]database.TemplateGroup, error) {
 	m.ctrl.T.Helper()
@@ -3078,6 +4348,36 @@ func (mr *MockStoreMockRecorder) GetTemplateParameterInsights(ctx, arg any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateParameterInsights", reflect.TypeOf((*MockStore)(nil).GetTemplateParameterInsights), ctx, arg)
 }
 
+// GetTemplateParameterOptionSource mocks base method.
+func (m *MockStore) GetTemplateParameterOptionSource(ctx context.Context, arg database.GetTemplateParameterOptionSourceParams) (database.TemplateParameterOptionSource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateParameterOptionSource", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateParameterOptionSource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateParameterOptionSource indicates an expected call of GetTemplateParameterOptionSource.
+func (mr *MockStoreMockRecorder) GetTemplateParameterOptionSource(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateParameterOptionSource", reflect.TypeOf((*MockStore)(nil).GetTemplateParameterOptionSource), ctx, arg)
+}
+
+// GetTemplateParameterOptionSources mocks base method.
+func (m *MockStore) GetTemplateParameterOptionSources(ctx context.Context, templateID uuid.UUID) ([]database.TemplateParameterOptionSource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateParameterOptionSources", ctx, templateID)
+	ret0, _ := ret[0].([]database.TemplateParameterOptionSource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateParameterOptionSources indicates an expected call of GetTemplateParameterOptionSources.
+func (mr *MockStoreMockRecorder) GetTemplateParameterOptionSources(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateParameterOptionSources", reflect.TypeOf((*MockStore)(nil).GetTemplateParameterOptionSources), ctx, templateID)
+}
+
 // GetTemplatePresetsWithPrebuilds mocks base method.
 func (m *MockStore) GetTemplatePresetsWithPrebuilds(ctx context.Context, templateID uuid.NullUUID) ([]database.GetTemplatePresetsWithPrebuildsRow, error) {
 	m.ctrl.T.Helper()
@@ -3093,6 +4393,36 @@ func (mr *MockStoreMockRecorder) GetTemplatePresetsWithPrebuilds(ctx, templateID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplatePresetsWithPrebuilds", reflect.TypeOf((*MockStore)(nil).GetTemplatePresetsWithPrebuilds), ctx, templateID)
 }
 
+// GetTemplateSecretByTemplateIDAndName mocks base method.
+func (m *MockStore) GetTemplateSecretByTemplateIDAndName(ctx context.Context, arg database.GetTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateSecretByTemplateIDAndName", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateSecretByTemplateIDAndName indicates an expected call of GetTemplateSecretByTemplateIDAndName.
+func (mr *MockStoreMockRecorder) GetTemplateSecretByTemplateIDAndName(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateSecretByTemplateIDAndName", reflect.TypeOf((*MockStore)(nil).GetTemplateSecretByTemplateIDAndName), ctx, arg)
+}
+
+// GetTemplateSecretsByTemplateID mocks base method.
+func (m *MockStore) GetTemplateSecretsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateSecretsByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].([]database.TemplateSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateSecretsByTemplateID indicates an expected call of GetTemplateSecretsByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplateSecretsByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateSecretsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplateSecretsByTemplateID), ctx, templateID)
+}
+
 // GetTemplateUsageStats mocks base method.
 func (m *MockStore) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	m.ctrl.T.Helper()
@@ -3123,6 +4453,36 @@ func (mr *MockStoreMockRecorder) GetTemplateUserRoles(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateUserRoles", reflect.TypeOf((*MockStore)(nil).GetTemplateUserRoles), ctx, id)
 }
 
+// GetTemplateVersionActivationRequestByTemplateVersionID mocks base method.
+func (m *MockStore) GetTemplateVersionActivationRequestByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) (database.GetTemplateVersionActivationRequestByTemplateVersionIDRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionActivationRequestByTemplateVersionID", ctx, templateVersionID)
+	ret0, _ := ret[0].(database.GetTemplateVersionActivationRequestByTemplateVersionIDRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionActivationRequestByTemplateVersionID indicates an expected call of GetTemplateVersionActivationRequestByTemplateVersionID.
+func (mr *MockStoreMockRecorder) GetTemplateVersionActivationRequestByTemplateVersionID(ctx, templateVersionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionActivationRequestByTemplateVersionID", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionActivationRequestByTemplateVersionID), ctx, templateVersionID)
+}
+
+// GetTemplateVersionActivationReviews mocks base method.
+func (m *MockStore) GetTemplateVersionActivationReviews(ctx context.Context, templateVersionID uuid.UUID) ([]database.GetTemplateVersionActivationReviewsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionActivationReviews", ctx, templateVersionID)
+	ret0, _ := ret[0].([]database.GetTemplateVersionActivationReviewsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionActivationReviews indicates an expected call of GetTemplateVersionActivationReviews.
+func (mr *MockStoreMockRecorder) GetTemplateVersionActivationReviews(ctx, templateVersionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionActivationReviews", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionActivationReviews), ctx, templateVersionID)
+}
+
 // GetTemplateVersionByID mocks base method.
 func (m *MockStore) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (database.TemplateVersion, error) {
 	m.ctrl.T.Helper()
@@ -3183,6 +4543,51 @@ func (mr *MockStoreMockRecorder) GetTemplateVersionParameters(ctx, templateVersi
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionParameters", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionParameters), ctx, templateVersionID)
 }
 
+// GetTemplateVersionRolloutBuildStats mocks base method.
+func (m *MockStore) GetTemplateVersionRolloutBuildStats(ctx context.Context, templateID uuid.UUID) (database.GetTemplateVersionRolloutBuildStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionRolloutBuildStats", ctx, templateID)
+	ret0, _ := ret[0].(database.GetTemplateVersionRolloutBuildStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionRolloutBuildStats indicates an expected call of GetTemplateVersionRolloutBuildStats.
+func (mr *MockStoreMockRecorder) GetTemplateVersionRolloutBuildStats(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionRolloutBuildStats", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionRolloutBuildStats), ctx, templateID)
+}
+
+// GetTemplateVersionRolloutByTemplateID mocks base method.
+func (m *MockStore) GetTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateVersionRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionRolloutByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].(database.TemplateVersionRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionRolloutByTemplateID indicates an expected call of GetTemplateVersionRolloutByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplateVersionRolloutByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionRolloutByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionRolloutByTemplateID), ctx, templateID)
+}
+
+// GetTemplateVersionRolloutTargetsByWorkspaceIDs mocks base method.
+func (m *MockStore) GetTemplateVersionRolloutTargetsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetTemplateVersionRolloutTargetsByWorkspaceIDsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionRolloutTargetsByWorkspaceIDs", ctx, ids)
+	ret0, _ := ret[0].([]database.GetTemplateVersionRolloutTargetsByWorkspaceIDsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionRolloutTargetsByWorkspaceIDs indicates an expected call of GetTemplateVersionRolloutTargetsByWorkspaceIDs.
+func (mr *MockStoreMockRecorder) GetTemplateVersionRolloutTargetsByWorkspaceIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionRolloutTargetsByWorkspaceIDs", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionRolloutTargetsByWorkspaceIDs), ctx, ids)
+}
+
 // GetTemplateVersionTerraformValues mocks base method.
 func (m *MockStore) GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (database.TemplateVersionTerraformValue, error) {
 	m.ctrl.T.Helper()
@@ -3363,6 +4768,21 @@ func (mr *MockStoreMockRecorder) GetUserByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockStore)(nil).GetUserByID), ctx, id)
 }
 
+// GetUserCLIVersions mocks base method.
+func (m *MockStore) GetUserCLIVersions(ctx context.Context, seenAfter time.Time) ([]database.GetUserCLIVersionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserCLIVersions", ctx, seenAfter)
+	ret0, _ := ret[0].([]database.GetUserCLIVersionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserCLIVersions indicates an expected call of GetUserCLIVersions.
+func (mr *MockStoreMockRecorder) GetUserCLIVersions(ctx, seenAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCLIVersions", reflect.TypeOf((*MockStore)(nil).GetUserCLIVersions), ctx, seenAfter)
+}
+
 // GetUserCount mocks base method.
 func (m *MockStore) GetUserCount(ctx context.Context, includeSystem bool) (int64, error) {
 	m.ctrl.T.Helper()
@@ -3420,37 +4840,142 @@ func (m *MockStore) GetUserLinkByUserIDLoginType(ctx context.Context, arg databa
 // GetUserLinkByUserIDLoginType indicates an expected call of GetUserLinkByUserIDLoginType.
 func (mr *MockStoreMockRecorder) GetUserLinkByUserIDLoginType(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLinkByUserIDLoginType", reflect.TypeOf((*MockStore)(nil).GetUserLinkByUserIDLoginType), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLinkByUserIDLoginType", reflect.TypeOf((*MockStore)(nil).GetUserLinkByUserIDLoginType), ctx, arg)
+}
+
+// GetUserLinksByUserID mocks base method.
+func (m *MockStore) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserLinksByUserID", ctx, userID)
+	ret0, _ := ret[0].([]database.UserLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserLinksByUserID indicates an expected call of GetUserLinksByUserID.
+func (mr *MockStoreMockRecorder) GetUserLinksByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLinksByUserID", reflect.TypeOf((*MockStore)(nil).GetUserLinksByUserID), ctx, userID)
+}
+
+// GetUserNotificationCategoryPreferences mocks base method.
+func (m *MockStore) GetUserNotificationCategoryPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationCategoryPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserNotificationCategoryPreferences", ctx, userID)
+	ret0, _ := ret[0].([]database.NotificationCategoryPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserNotificationCategoryPreferences indicates an expected call of GetUserNotificationCategoryPreferences.
+func (mr *MockStoreMockRecorder) GetUserNotificationCategoryPreferences(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNotificationCategoryPreferences", reflect.TypeOf((*MockStore)(nil).GetUserNotificationCategoryPreferences), ctx, userID)
+}
+
+// GetUserNotificationDigest mocks base method.
+func (m *MockStore) GetUserNotificationDigest(ctx context.Context, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserNotificationDigest", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserNotificationDigest indicates an expected call of GetUserNotificationDigest.
+func (mr *MockStoreMockRecorder) GetUserNotificationDigest(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNotificationDigest", reflect.TypeOf((*MockStore)(nil).GetUserNotificationDigest), ctx, userID)
+}
+
+// GetUserNotificationPreferences mocks base method.
+func (m *MockStore) GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserNotificationPreferences", ctx, userID)
+	ret0, _ := ret[0].([]database.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserNotificationPreferences indicates an expected call of GetUserNotificationPreferences.
+func (mr *MockStoreMockRecorder) GetUserNotificationPreferences(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNotificationPreferences", reflect.TypeOf((*MockStore)(nil).GetUserNotificationPreferences), ctx, userID)
+}
+
+// GetUserProxyLatencies mocks base method.
+func (m *MockStore) GetUserProxyLatencies(ctx context.Context, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserProxyLatencies", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserProxyLatencies indicates an expected call of GetUserProxyLatencies.
+func (mr *MockStoreMockRecorder) GetUserProxyLatencies(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserProxyLatencies", reflect.TypeOf((*MockStore)(nil).GetUserProxyLatencies), ctx, userID)
+}
+
+// GetUserProxyPreference mocks base method.
+func (m *MockStore) GetUserProxyPreference(ctx context.Context, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserProxyPreference", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserProxyPreference indicates an expected call of GetUserProxyPreference.
+func (mr *MockStoreMockRecorder) GetUserProxyPreference(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserProxyPreference", reflect.TypeOf((*MockStore)(nil).GetUserProxyPreference), ctx, userID)
+}
+
+// GetUserSecretByUserIDAndName mocks base method.
+func (m *MockStore) GetUserSecretByUserIDAndName(ctx context.Context, arg database.GetUserSecretByUserIDAndNameParams) (database.UserSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSecretByUserIDAndName", ctx, arg)
+	ret0, _ := ret[0].(database.UserSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSecretByUserIDAndName indicates an expected call of GetUserSecretByUserIDAndName.
+func (mr *MockStoreMockRecorder) GetUserSecretByUserIDAndName(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSecretByUserIDAndName", reflect.TypeOf((*MockStore)(nil).GetUserSecretByUserIDAndName), ctx, arg)
 }
 
-// GetUserLinksByUserID mocks base method.
-func (m *MockStore) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserLink, error) {
+// GetUserSecretsByUserID mocks base method.
+func (m *MockStore) GetUserSecretsByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserSecret, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserLinksByUserID", ctx, userID)
-	ret0, _ := ret[0].([]database.UserLink)
+	ret := m.ctrl.Call(m, "GetUserSecretsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]database.UserSecret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserLinksByUserID indicates an expected call of GetUserLinksByUserID.
-func (mr *MockStoreMockRecorder) GetUserLinksByUserID(ctx, userID any) *gomock.Call {
+// GetUserSecretsByUserID indicates an expected call of GetUserSecretsByUserID.
+func (mr *MockStoreMockRecorder) GetUserSecretsByUserID(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLinksByUserID", reflect.TypeOf((*MockStore)(nil).GetUserLinksByUserID), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSecretsByUserID", reflect.TypeOf((*MockStore)(nil).GetUserSecretsByUserID), ctx, userID)
 }
 
-// GetUserNotificationPreferences mocks base method.
-func (m *MockStore) GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+// GetUserStartOnConnect mocks base method.
+func (m *MockStore) GetUserStartOnConnect(ctx context.Context, userID uuid.UUID) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserNotificationPreferences", ctx, userID)
-	ret0, _ := ret[0].([]database.NotificationPreference)
+	ret := m.ctrl.Call(m, "GetUserStartOnConnect", ctx, userID)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserNotificationPreferences indicates an expected call of GetUserNotificationPreferences.
-func (mr *MockStoreMockRecorder) GetUserNotificationPreferences(ctx, userID any) *gomock.Call {
+// GetUserStartOnConnect indicates an expected call of GetUserStartOnConnect.
+func (mr *MockStoreMockRecorder) GetUserStartOnConnect(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNotificationPreferences", reflect.TypeOf((*MockStore)(nil).GetUserNotificationPreferences), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserStartOnConnect", reflect.TypeOf((*MockStore)(nil).GetUserStartOnConnect), ctx, userID)
 }
 
 // GetUserStatusCounts mocks base method.
@@ -3498,6 +5023,66 @@ func (mr *MockStoreMockRecorder) GetUserThemePreference(ctx, userID any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserThemePreference", reflect.TypeOf((*MockStore)(nil).GetUserThemePreference), ctx, userID)
 }
 
+// GetUserWebAuthnCredentialByCredentialID mocks base method.
+func (m *MockStore) GetUserWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (database.UserWebAuthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserWebAuthnCredentialByCredentialID", ctx, credentialID)
+	ret0, _ := ret[0].(database.UserWebAuthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserWebAuthnCredentialByCredentialID indicates an expected call of GetUserWebAuthnCredentialByCredentialID.
+func (mr *MockStoreMockRecorder) GetUserWebAuthnCredentialByCredentialID(ctx, credentialID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWebAuthnCredentialByCredentialID", reflect.TypeOf((*MockStore)(nil).GetUserWebAuthnCredentialByCredentialID), ctx, credentialID)
+}
+
+// GetUserWebAuthnCredentialByID mocks base method.
+func (m *MockStore) GetUserWebAuthnCredentialByID(ctx context.Context, id uuid.UUID) (database.UserWebAuthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserWebAuthnCredentialByID", ctx, id)
+	ret0, _ := ret[0].(database.UserWebAuthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserWebAuthnCredentialByID indicates an expected call of GetUserWebAuthnCredentialByID.
+func (mr *MockStoreMockRecorder) GetUserWebAuthnCredentialByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWebAuthnCredentialByID", reflect.TypeOf((*MockStore)(nil).GetUserWebAuthnCredentialByID), ctx, id)
+}
+
+// GetUserWebAuthnCredentialsByUserID mocks base method.
+func (m *MockStore) GetUserWebAuthnCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserWebAuthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserWebAuthnCredentialsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]database.UserWebAuthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserWebAuthnCredentialsByUserID indicates an expected call of GetUserWebAuthnCredentialsByUserID.
+func (mr *MockStoreMockRecorder) GetUserWebAuthnCredentialsByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWebAuthnCredentialsByUserID", reflect.TypeOf((*MockStore)(nil).GetUserWebAuthnCredentialsByUserID), ctx, userID)
+}
+
+// GetUserWebAuthnRequired mocks base method.
+func (m *MockStore) GetUserWebAuthnRequired(ctx context.Context, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserWebAuthnRequired", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserWebAuthnRequired indicates an expected call of GetUserWebAuthnRequired.
+func (mr *MockStoreMockRecorder) GetUserWebAuthnRequired(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWebAuthnRequired", reflect.TypeOf((*MockStore)(nil).GetUserWebAuthnRequired), ctx, userID)
+}
+
 // GetUserWorkspaceBuildParameters mocks base method.
 func (m *MockStore) GetUserWorkspaceBuildParameters(ctx context.Context, arg database.GetUserWorkspaceBuildParametersParams) ([]database.GetUserWorkspaceBuildParametersRow, error) {
 	m.ctrl.T.Helper()
@@ -3513,6 +5098,21 @@ func (mr *MockStoreMockRecorder) GetUserWorkspaceBuildParameters(ctx, arg any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWorkspaceBuildParameters", reflect.TypeOf((*MockStore)(nil).GetUserWorkspaceBuildParameters), ctx, arg)
 }
 
+// GetUserWorkspaceUsage mocks base method.
+func (m *MockStore) GetUserWorkspaceUsage(ctx context.Context, arg database.GetUserWorkspaceUsageParams) ([]database.GetUserWorkspaceUsageRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserWorkspaceUsage", ctx, arg)
+	ret0, _ := ret[0].([]database.GetUserWorkspaceUsageRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserWorkspaceUsage indicates an expected call of GetUserWorkspaceUsage.
+func (mr *MockStoreMockRecorder) GetUserWorkspaceUsage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWorkspaceUsage", reflect.TypeOf((*MockStore)(nil).GetUserWorkspaceUsage), ctx, arg)
+}
+
 // GetUsers mocks base method.
 func (m *MockStore) GetUsers(ctx context.Context, arg database.GetUsersParams) ([]database.GetUsersRow, error) {
 	m.ctrl.T.Helper()
@@ -3543,6 +5143,51 @@ func (mr *MockStoreMockRecorder) GetUsersByIDs(ctx, ids any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByIDs", reflect.TypeOf((*MockStore)(nil).GetUsersByIDs), ctx, ids)
 }
 
+// GetVariableSetByName mocks base method.
+func (m *MockStore) GetVariableSetByName(ctx context.Context, name string) (database.VariableSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariableSetByName", ctx, name)
+	ret0, _ := ret[0].(database.VariableSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVariableSetByName indicates an expected call of GetVariableSetByName.
+func (mr *MockStoreMockRecorder) GetVariableSetByName(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariableSetByName", reflect.TypeOf((*MockStore)(nil).GetVariableSetByName), ctx, name)
+}
+
+// GetVariableSets mocks base method.
+func (m *MockStore) GetVariableSets(ctx context.Context) ([]database.VariableSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariableSets", ctx)
+	ret0, _ := ret[0].([]database.VariableSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVariableSets indicates an expected call of GetVariableSets.
+func (mr *MockStoreMockRecorder) GetVariableSets(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariableSets", reflect.TypeOf((*MockStore)(nil).GetVariableSets), ctx)
+}
+
+// GetVariableSetsByTemplateID mocks base method.
+func (m *MockStore) GetVariableSetsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.VariableSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariableSetsByTemplateID", ctx, templateID)
+	ret0, _ := ret[0].([]database.VariableSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVariableSetsByTemplateID indicates an expected call of GetVariableSetsByTemplateID.
+func (mr *MockStoreMockRecorder) GetVariableSetsByTemplateID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariableSetsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetVariableSetsByTemplateID), ctx, templateID)
+}
+
 // GetWebpushSubscriptionsByUserID mocks base method.
 func (m *MockStore) GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	m.ctrl.T.Helper()
@@ -3573,6 +5218,36 @@ func (mr *MockStoreMockRecorder) GetWebpushVAPIDKeys(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebpushVAPIDKeys", reflect.TypeOf((*MockStore)(nil).GetWebpushVAPIDKeys), ctx)
 }
 
+// GetWorkspaceAdoptionByID mocks base method.
+func (m *MockStore) GetWorkspaceAdoptionByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAdoption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAdoptionByID", ctx, id)
+	ret0, _ := ret[0].(database.WorkspaceAdoption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAdoptionByID indicates an expected call of GetWorkspaceAdoptionByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAdoptionByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAdoptionByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAdoptionByID), ctx, id)
+}
+
+// GetWorkspaceAdoptionByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceAdoptionByWorkspaceID(ctx context.Context, workspaceID uuid.NullUUID) (database.WorkspaceAdoption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAdoptionByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(database.WorkspaceAdoption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAdoptionByWorkspaceID indicates an expected call of GetWorkspaceAdoptionByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAdoptionByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAdoptionByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAdoptionByWorkspaceID), ctx, workspaceID)
+}
+
 // GetWorkspaceAgentAndLatestBuildByAuthToken mocks base method.
 func (m *MockStore) GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Context, authToken uuid.UUID) (database.GetWorkspaceAgentAndLatestBuildByAuthTokenRow, error) {
 	m.ctrl.T.Helper()
@@ -3768,6 +5443,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentStatsAndLabels(ctx, createdAt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentStatsAndLabels", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentStatsAndLabels), ctx, createdAt)
 }
 
+// GetWorkspaceAgentTokenRotationByAgentID mocks base method.
+func (m *MockStore) GetWorkspaceAgentTokenRotationByAgentID(ctx context.Context, agentID uuid.UUID) (database.WorkspaceAgentTokenRotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentTokenRotationByAgentID", ctx, agentID)
+	ret0, _ := ret[0].(database.WorkspaceAgentTokenRotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentTokenRotationByAgentID indicates an expected call of GetWorkspaceAgentTokenRotationByAgentID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentTokenRotationByAgentID(ctx, agentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentTokenRotationByAgentID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentTokenRotationByAgentID), ctx, agentID)
+}
+
 // GetWorkspaceAgentUsageStats mocks base method.
 func (m *MockStore) GetWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentUsageStatsRow, error) {
 	m.ctrl.T.Helper()
@@ -3798,6 +5488,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentUsageStatsAndLabels(ctx, creat
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentUsageStatsAndLabels", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentUsageStatsAndLabels), ctx, createdAt)
 }
 
+// GetWorkspaceAgentVersionsInLatestBuilds mocks base method.
+func (m *MockStore) GetWorkspaceAgentVersionsInLatestBuilds(ctx context.Context) ([]database.GetWorkspaceAgentVersionsInLatestBuildsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentVersionsInLatestBuilds", ctx)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentVersionsInLatestBuildsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentVersionsInLatestBuilds indicates an expected call of GetWorkspaceAgentVersionsInLatestBuilds.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentVersionsInLatestBuilds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentVersionsInLatestBuilds", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentVersionsInLatestBuilds), ctx)
+}
+
+// GetWorkspaceAgentVolumeUsageByAgentID mocks base method.
+func (m *MockStore) GetWorkspaceAgentVolumeUsageByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentVolumeUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentVolumeUsageByAgentID", ctx, agentID)
+	ret0, _ := ret[0].([]database.WorkspaceAgentVolumeUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentVolumeUsageByAgentID indicates an expected call of GetWorkspaceAgentVolumeUsageByAgentID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentVolumeUsageByAgentID(ctx, agentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentVolumeUsageByAgentID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentVolumeUsageByAgentID), ctx, agentID)
+}
+
 // GetWorkspaceAgentsByParentID mocks base method.
 func (m *MockStore) GetWorkspaceAgentsByParentID(ctx context.Context, parentID uuid.UUID) ([]database.WorkspaceAgent, error) {
 	m.ctrl.T.Helper()
@@ -3948,6 +5668,51 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAppsCreatedAfter(ctx, createdAt any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAppsCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAppsCreatedAfter), ctx, createdAt)
 }
 
+// GetWorkspaceArchiveByID mocks base method.
+func (m *MockStore) GetWorkspaceArchiveByID(ctx context.Context, id uuid.UUID) (database.WorkspaceArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceArchiveByID", ctx, id)
+	ret0, _ := ret[0].(database.WorkspaceArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceArchiveByID indicates an expected call of GetWorkspaceArchiveByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceArchiveByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceArchiveByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceArchiveByID), ctx, id)
+}
+
+// GetWorkspaceArchivesByOwnerID mocks base method.
+func (m *MockStore) GetWorkspaceArchivesByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]database.WorkspaceArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceArchivesByOwnerID", ctx, ownerID)
+	ret0, _ := ret[0].([]database.WorkspaceArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceArchivesByOwnerID indicates an expected call of GetWorkspaceArchivesByOwnerID.
+func (mr *MockStoreMockRecorder) GetWorkspaceArchivesByOwnerID(ctx, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceArchivesByOwnerID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceArchivesByOwnerID), ctx, ownerID)
+}
+
+// GetWorkspaceArchivesExpiredBefore mocks base method.
+func (m *MockStore) GetWorkspaceArchivesExpiredBefore(ctx context.Context, now time.Time) ([]database.WorkspaceArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceArchivesExpiredBefore", ctx, now)
+	ret0, _ := ret[0].([]database.WorkspaceArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceArchivesExpiredBefore indicates an expected call of GetWorkspaceArchivesExpiredBefore.
+func (mr *MockStoreMockRecorder) GetWorkspaceArchivesExpiredBefore(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceArchivesExpiredBefore", reflect.TypeOf((*MockStore)(nil).GetWorkspaceArchivesExpiredBefore), ctx, now)
+}
+
 // GetWorkspaceBuildByID mocks base method.
 func (m *MockStore) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBuild, error) {
 	m.ctrl.T.Helper()
@@ -4023,6 +5788,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceBuildParametersByBuildIDs(ctx, work
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildParametersByBuildIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildParametersByBuildIDs), ctx, workspaceBuildIds)
 }
 
+// GetWorkspaceBuildQuotaCosts mocks base method.
+func (m *MockStore) GetWorkspaceBuildQuotaCosts(ctx context.Context, workspaceBuildID uuid.UUID) ([]database.WorkspaceBuildQuotaCost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildQuotaCosts", ctx, workspaceBuildID)
+	ret0, _ := ret[0].([]database.WorkspaceBuildQuotaCost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBuildQuotaCosts indicates an expected call of GetWorkspaceBuildQuotaCosts.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildQuotaCosts(ctx, workspaceBuildID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildQuotaCosts", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildQuotaCosts), ctx, workspaceBuildID)
+}
+
 // GetWorkspaceBuildStatsByTemplates mocks base method.
 func (m *MockStore) GetWorkspaceBuildStatsByTemplates(ctx context.Context, since time.Time) ([]database.GetWorkspaceBuildStatsByTemplatesRow, error) {
 	m.ctrl.T.Helper()
@@ -4143,6 +5923,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceByWorkspaceAppID(ctx, workspaceAppI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByWorkspaceAppID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceByWorkspaceAppID), ctx, workspaceAppID)
 }
 
+// GetWorkspaceLabelsByWorkspaceIDs mocks base method.
+func (m *MockStore) GetWorkspaceLabelsByWorkspaceIDs(ctx context.Context, workspaceIds []uuid.UUID) ([]database.WorkspaceLabel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceLabelsByWorkspaceIDs", ctx, workspaceIds)
+	ret0, _ := ret[0].([]database.WorkspaceLabel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceLabelsByWorkspaceIDs indicates an expected call of GetWorkspaceLabelsByWorkspaceIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceLabelsByWorkspaceIDs(ctx, workspaceIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceLabelsByWorkspaceIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceLabelsByWorkspaceIDs), ctx, workspaceIds)
+}
+
 // GetWorkspaceModulesByJobID mocks base method.
 func (m *MockStore) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	m.ctrl.T.Helper()
@@ -4173,6 +5968,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceModulesCreatedAfter(ctx, createdAt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceModulesCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceModulesCreatedAfter), ctx, createdAt)
 }
 
+// GetWorkspacePortShareLinkByID mocks base method.
+func (m *MockStore) GetWorkspacePortShareLinkByID(ctx context.Context, id uuid.UUID) (database.WorkspacePortShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspacePortShareLinkByID", ctx, id)
+	ret0, _ := ret[0].(database.WorkspacePortShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspacePortShareLinkByID indicates an expected call of GetWorkspacePortShareLinkByID.
+func (mr *MockStoreMockRecorder) GetWorkspacePortShareLinkByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacePortShareLinkByID", reflect.TypeOf((*MockStore)(nil).GetWorkspacePortShareLinkByID), ctx, id)
+}
+
+// GetWorkspacePortShareLinksByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspacePortShareLinksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspacePortShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspacePortShareLinksByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].([]database.WorkspacePortShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspacePortShareLinksByWorkspaceID indicates an expected call of GetWorkspacePortShareLinksByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspacePortShareLinksByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacePortShareLinksByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspacePortShareLinksByWorkspaceID), ctx, workspaceID)
+}
+
 // GetWorkspaceProxies mocks base method.
 func (m *MockStore) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -4233,6 +6058,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceProxyByName(ctx, name any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceProxyByName", reflect.TypeOf((*MockStore)(nil).GetWorkspaceProxyByName), ctx, name)
 }
 
+// GetWorkspaceProxyTokenRotationByProxyID mocks base method.
+func (m *MockStore) GetWorkspaceProxyTokenRotationByProxyID(ctx context.Context, proxyID uuid.UUID) (database.WorkspaceProxyTokenRotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceProxyTokenRotationByProxyID", ctx, proxyID)
+	ret0, _ := ret[0].(database.WorkspaceProxyTokenRotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceProxyTokenRotationByProxyID indicates an expected call of GetWorkspaceProxyTokenRotationByProxyID.
+func (mr *MockStoreMockRecorder) GetWorkspaceProxyTokenRotationByProxyID(ctx, proxyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceProxyTokenRotationByProxyID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceProxyTokenRotationByProxyID), ctx, proxyID)
+}
+
+// GetWorkspaceProxyTokenRotations mocks base method.
+func (m *MockStore) GetWorkspaceProxyTokenRotations(ctx context.Context) ([]database.WorkspaceProxyTokenRotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceProxyTokenRotations", ctx)
+	ret0, _ := ret[0].([]database.WorkspaceProxyTokenRotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceProxyTokenRotations indicates an expected call of GetWorkspaceProxyTokenRotations.
+func (mr *MockStoreMockRecorder) GetWorkspaceProxyTokenRotations(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceProxyTokenRotations", reflect.TypeOf((*MockStore)(nil).GetWorkspaceProxyTokenRotations), ctx)
+}
+
 // GetWorkspaceResourceByID mocks base method.
 func (m *MockStore) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (database.WorkspaceResource, error) {
 	m.ctrl.T.Helper()
@@ -4323,6 +6178,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceResourcesCreatedAfter(ctx, createdA
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceResourcesCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceResourcesCreatedAfter), ctx, createdAt)
 }
 
+// GetWorkspaceSchedulePauseByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceSchedulePauseByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceSchedulePause, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSchedulePauseByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].(database.WorkspaceSchedulePause)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSchedulePauseByWorkspaceID indicates an expected call of GetWorkspaceSchedulePauseByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSchedulePauseByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSchedulePauseByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSchedulePauseByWorkspaceID), ctx, workspaceID)
+}
+
 // GetWorkspaceUniqueOwnerCountByTemplateIDs mocks base method.
 func (m *MockStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	m.ctrl.T.Helper()
@@ -4398,6 +6268,21 @@ func (mr *MockStoreMockRecorder) GetWorkspacesEligibleForTransition(ctx, now any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacesEligibleForTransition", reflect.TypeOf((*MockStore)(nil).GetWorkspacesEligibleForTransition), ctx, now)
 }
 
+// HaltTemplateVersionRollout mocks base method.
+func (m *MockStore) HaltTemplateVersionRollout(ctx context.Context, arg database.HaltTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HaltTemplateVersionRollout", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateVersionRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HaltTemplateVersionRollout indicates an expected call of HaltTemplateVersionRollout.
+func (mr *MockStoreMockRecorder) HaltTemplateVersionRollout(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HaltTemplateVersionRollout", reflect.TypeOf((*MockStore)(nil).HaltTemplateVersionRollout), ctx, arg)
+}
+
 // HasTemplateVersionsWithAITask mocks base method.
 func (m *MockStore) HasTemplateVersionsWithAITask(ctx context.Context) (bool, error) {
 	m.ctrl.T.Helper()
@@ -4427,6 +6312,35 @@ func (mr *MockStoreMockRecorder) InTx(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTx", reflect.TypeOf((*MockStore)(nil).InTx), arg0, arg1)
 }
 
+// IncrementNetworkConnectionQualityCounters mocks base method.
+func (m *MockStore) IncrementNetworkConnectionQualityCounters(ctx context.Context, arg database.IncrementNetworkConnectionQualityCountersParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementNetworkConnectionQualityCounters", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementNetworkConnectionQualityCounters indicates an expected call of IncrementNetworkConnectionQualityCounters.
+func (mr *MockStoreMockRecorder) IncrementNetworkConnectionQualityCounters(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementNetworkConnectionQualityCounters", reflect.TypeOf((*MockStore)(nil).IncrementNetworkConnectionQualityCounters), ctx, arg)
+}
+
+// InsertAITaskQueueEntry mocks base method.
+func (m *MockStore) InsertAITaskQueueEntry(ctx context.Context, arg database.InsertAITaskQueueEntryParams) (database.AITaskQueue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAITaskQueueEntry", ctx, arg)
+	ret0, _ := ret[0].(database.AITaskQueue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAITaskQueueEntry indicates an expected call of InsertAITaskQueueEntry.
+func (mr *MockStoreMockRecorder) InsertAITaskQueueEntry(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAITaskQueueEntry", reflect.TypeOf((*MockStore)(nil).InsertAITaskQueueEntry), ctx, arg)
+}
+
 // InsertAPIKey mocks base method.
 func (m *MockStore) InsertAPIKey(ctx context.Context, arg database.InsertAPIKeyParams) (database.APIKey, error) {
 	m.ctrl.T.Helper()
@@ -4472,6 +6386,21 @@ func (mr *MockStoreMockRecorder) InsertAuditLog(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditLog", reflect.TypeOf((*MockStore)(nil).InsertAuditLog), ctx, arg)
 }
 
+// InsertAuditLogLegalHold mocks base method.
+func (m *MockStore) InsertAuditLogLegalHold(ctx context.Context, arg database.InsertAuditLogLegalHoldParams) (database.AuditLogLegalHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAuditLogLegalHold", ctx, arg)
+	ret0, _ := ret[0].(database.AuditLogLegalHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAuditLogLegalHold indicates an expected call of InsertAuditLogLegalHold.
+func (mr *MockStoreMockRecorder) InsertAuditLogLegalHold(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditLogLegalHold", reflect.TypeOf((*MockStore)(nil).InsertAuditLogLegalHold), ctx, arg)
+}
+
 // InsertCryptoKey mocks base method.
 func (m *MockStore) InsertCryptoKey(ctx context.Context, arg database.InsertCryptoKeyParams) (database.CryptoKey, error) {
 	m.ctrl.T.Helper()
@@ -4598,24 +6527,52 @@ func (m *MockStore) InsertGroup(ctx context.Context, arg database.InsertGroupPar
 	return ret0, ret1
 }
 
-// InsertGroup indicates an expected call of InsertGroup.
-func (mr *MockStoreMockRecorder) InsertGroup(ctx, arg any) *gomock.Call {
+// InsertGroup indicates an expected call of InsertGroup.
+func (mr *MockStoreMockRecorder) InsertGroup(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertGroup", reflect.TypeOf((*MockStore)(nil).InsertGroup), ctx, arg)
+}
+
+// InsertGroupMember mocks base method.
+func (m *MockStore) InsertGroupMember(ctx context.Context, arg database.InsertGroupMemberParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertGroupMember", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertGroupMember indicates an expected call of InsertGroupMember.
+func (mr *MockStoreMockRecorder) InsertGroupMember(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertGroupMember", reflect.TypeOf((*MockStore)(nil).InsertGroupMember), ctx, arg)
+}
+
+// InsertGroupQuotaAllowances mocks base method.
+func (m *MockStore) InsertGroupQuotaAllowances(ctx context.Context, arg database.InsertGroupQuotaAllowancesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertGroupQuotaAllowances", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertGroupQuotaAllowances indicates an expected call of InsertGroupQuotaAllowances.
+func (mr *MockStoreMockRecorder) InsertGroupQuotaAllowances(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertGroup", reflect.TypeOf((*MockStore)(nil).InsertGroup), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertGroupQuotaAllowances", reflect.TypeOf((*MockStore)(nil).InsertGroupQuotaAllowances), ctx, arg)
 }
 
-// InsertGroupMember mocks base method.
-func (m *MockStore) InsertGroupMember(ctx context.Context, arg database.InsertGroupMemberParams) error {
+// InsertHealthScore mocks base method.
+func (m *MockStore) InsertHealthScore(ctx context.Context, arg database.InsertHealthScoreParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertGroupMember", ctx, arg)
+	ret := m.ctrl.Call(m, "InsertHealthScore", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// InsertGroupMember indicates an expected call of InsertGroupMember.
-func (mr *MockStoreMockRecorder) InsertGroupMember(ctx, arg any) *gomock.Call {
+// InsertHealthScore indicates an expected call of InsertHealthScore.
+func (mr *MockStoreMockRecorder) InsertHealthScore(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertGroupMember", reflect.TypeOf((*MockStore)(nil).InsertGroupMember), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertHealthScore", reflect.TypeOf((*MockStore)(nil).InsertHealthScore), ctx, arg)
 }
 
 // InsertInboxNotification mocks base method.
@@ -4708,6 +6665,21 @@ func (mr *MockStoreMockRecorder) InsertOAuth2ProviderAppCode(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2ProviderAppCode", reflect.TypeOf((*MockStore)(nil).InsertOAuth2ProviderAppCode), ctx, arg)
 }
 
+// InsertOAuth2ProviderAppDeviceCode mocks base method.
+func (m *MockStore) InsertOAuth2ProviderAppDeviceCode(ctx context.Context, arg database.InsertOAuth2ProviderAppDeviceCodeParams) (database.OAuth2ProviderAppDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOAuth2ProviderAppDeviceCode", ctx, arg)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertOAuth2ProviderAppDeviceCode indicates an expected call of InsertOAuth2ProviderAppDeviceCode.
+func (mr *MockStoreMockRecorder) InsertOAuth2ProviderAppDeviceCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2ProviderAppDeviceCode", reflect.TypeOf((*MockStore)(nil).InsertOAuth2ProviderAppDeviceCode), ctx, arg)
+}
+
 // InsertOAuth2ProviderAppSecret mocks base method.
 func (m *MockStore) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
 	m.ctrl.T.Helper()
@@ -4843,6 +6815,20 @@ func (mr *MockStoreMockRecorder) InsertProvisionerJobLogs(ctx, arg any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerJobLogs", reflect.TypeOf((*MockStore)(nil).InsertProvisionerJobLogs), ctx, arg)
 }
 
+// InsertProvisionerJobRequeue mocks base method.
+func (m *MockStore) InsertProvisionerJobRequeue(ctx context.Context, arg database.InsertProvisionerJobRequeueParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertProvisionerJobRequeue", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertProvisionerJobRequeue indicates an expected call of InsertProvisionerJobRequeue.
+func (mr *MockStoreMockRecorder) InsertProvisionerJobRequeue(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerJobRequeue", reflect.TypeOf((*MockStore)(nil).InsertProvisionerJobRequeue), ctx, arg)
+}
+
 // InsertProvisionerJobTimings mocks base method.
 func (m *MockStore) InsertProvisionerJobTimings(ctx context.Context, arg database.InsertProvisionerJobTimingsParams) ([]database.ProvisionerJobTiming, error) {
 	m.ctrl.T.Helper()
@@ -4888,6 +6874,34 @@ func (mr *MockStoreMockRecorder) InsertReplica(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertReplica", reflect.TypeOf((*MockStore)(nil).InsertReplica), ctx, arg)
 }
 
+// InsertSCIMGroupMembers mocks base method.
+func (m *MockStore) InsertSCIMGroupMembers(ctx context.Context, arg database.InsertSCIMGroupMembersParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSCIMGroupMembers", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertSCIMGroupMembers indicates an expected call of InsertSCIMGroupMembers.
+func (mr *MockStoreMockRecorder) InsertSCIMGroupMembers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSCIMGroupMembers", reflect.TypeOf((*MockStore)(nil).InsertSCIMGroupMembers), ctx, arg)
+}
+
+// InsertSCIMNestedGroups mocks base method.
+func (m *MockStore) InsertSCIMNestedGroups(ctx context.Context, arg database.InsertSCIMNestedGroupsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSCIMNestedGroups", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertSCIMNestedGroups indicates an expected call of InsertSCIMNestedGroups.
+func (mr *MockStoreMockRecorder) InsertSCIMNestedGroups(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSCIMNestedGroups", reflect.TypeOf((*MockStore)(nil).InsertSCIMNestedGroups), ctx, arg)
+}
+
 // InsertTelemetryItemIfNotExists mocks base method.
 func (m *MockStore) InsertTelemetryItemIfNotExists(ctx context.Context, arg database.InsertTelemetryItemIfNotExistsParams) error {
 	m.ctrl.T.Helper()
@@ -4916,6 +6930,49 @@ func (mr *MockStoreMockRecorder) InsertTemplate(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplate", reflect.TypeOf((*MockStore)(nil).InsertTemplate), ctx, arg)
 }
 
+// InsertTemplateBundle mocks base method.
+func (m *MockStore) InsertTemplateBundle(ctx context.Context, arg database.InsertTemplateBundleParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateBundle", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertTemplateBundle indicates an expected call of InsertTemplateBundle.
+func (mr *MockStoreMockRecorder) InsertTemplateBundle(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateBundle", reflect.TypeOf((*MockStore)(nil).InsertTemplateBundle), ctx, arg)
+}
+
+// InsertTemplateSecret mocks base method.
+func (m *MockStore) InsertTemplateSecret(ctx context.Context, arg database.InsertTemplateSecretParams) (database.TemplateSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateSecret", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplateSecret indicates an expected call of InsertTemplateSecret.
+func (mr *MockStoreMockRecorder) InsertTemplateSecret(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateSecret", reflect.TypeOf((*MockStore)(nil).InsertTemplateSecret), ctx, arg)
+}
+
+// InsertTemplateVariableSet mocks base method.
+func (m *MockStore) InsertTemplateVariableSet(ctx context.Context, arg database.InsertTemplateVariableSetParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateVariableSet", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertTemplateVariableSet indicates an expected call of InsertTemplateVariableSet.
+func (mr *MockStoreMockRecorder) InsertTemplateVariableSet(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVariableSet", reflect.TypeOf((*MockStore)(nil).InsertTemplateVariableSet), ctx, arg)
+}
+
 // InsertTemplateVersion mocks base method.
 func (m *MockStore) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
 	m.ctrl.T.Helper()
@@ -5048,6 +7105,65 @@ func (mr *MockStoreMockRecorder) InsertUserLink(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserLink", reflect.TypeOf((*MockStore)(nil).InsertUserLink), ctx, arg)
 }
 
+// InsertUserMFARecoveryCode mocks base method.
+func (m *MockStore) InsertUserMFARecoveryCode(ctx context.Context, arg database.InsertUserMFARecoveryCodeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserMFARecoveryCode", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertUserMFARecoveryCode indicates an expected call of InsertUserMFARecoveryCode.
+func (mr *MockStoreMockRecorder) InsertUserMFARecoveryCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserMFARecoveryCode", reflect.TypeOf((*MockStore)(nil).InsertUserMFARecoveryCode), ctx, arg)
+}
+
+// InsertUserSecret mocks base method.
+func (m *MockStore) InsertUserSecret(ctx context.Context, arg database.InsertUserSecretParams) (database.UserSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserSecret", ctx, arg)
+	ret0, _ := ret[0].(database.UserSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertUserSecret indicates an expected call of InsertUserSecret.
+func (mr *MockStoreMockRecorder) InsertUserSecret(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserSecret", reflect.TypeOf((*MockStore)(nil).InsertUserSecret), ctx, arg)
+}
+
+// InsertUserWebAuthnCredential mocks base method.
+func (m *MockStore) InsertUserWebAuthnCredential(ctx context.Context, arg database.InsertUserWebAuthnCredentialParams) (database.UserWebAuthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserWebAuthnCredential", ctx, arg)
+	ret0, _ := ret[0].(database.UserWebAuthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertUserWebAuthnCredential indicates an expected call of InsertUserWebAuthnCredential.
+func (mr *MockStoreMockRecorder) InsertUserWebAuthnCredential(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserWebAuthnCredential", reflect.TypeOf((*MockStore)(nil).InsertUserWebAuthnCredential), ctx, arg)
+}
+
+// InsertVariableSet mocks base method.
+func (m *MockStore) InsertVariableSet(ctx context.Context, arg database.InsertVariableSetParams) (database.VariableSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertVariableSet", ctx, arg)
+	ret0, _ := ret[0].(database.VariableSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertVariableSet indicates an expected call of InsertVariableSet.
+func (mr *MockStoreMockRecorder) InsertVariableSet(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertVariableSet", reflect.TypeOf((*MockStore)(nil).InsertVariableSet), ctx, arg)
+}
+
 // InsertVolumeResourceMonitor mocks base method.
 func (m *MockStore) InsertVolumeResourceMonitor(ctx context.Context, arg database.InsertVolumeResourceMonitorParams) (database.WorkspaceAgentVolumeResourceMonitor, error) {
 	m.ctrl.T.Helper()
@@ -5063,6 +7179,21 @@ func (mr *MockStoreMockRecorder) InsertVolumeResourceMonitor(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertVolumeResourceMonitor", reflect.TypeOf((*MockStore)(nil).InsertVolumeResourceMonitor), ctx, arg)
 }
 
+// InsertWebAuthnChallenge mocks base method.
+func (m *MockStore) InsertWebAuthnChallenge(ctx context.Context, arg database.InsertWebAuthnChallengeParams) (database.WebAuthnChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebAuthnChallenge", ctx, arg)
+	ret0, _ := ret[0].(database.WebAuthnChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebAuthnChallenge indicates an expected call of InsertWebAuthnChallenge.
+func (mr *MockStoreMockRecorder) InsertWebAuthnChallenge(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebAuthnChallenge", reflect.TypeOf((*MockStore)(nil).InsertWebAuthnChallenge), ctx, arg)
+}
+
 // InsertWebpushSubscription mocks base method.
 func (m *MockStore) InsertWebpushSubscription(ctx context.Context, arg database.InsertWebpushSubscriptionParams) (database.WebpushSubscription, error) {
 	m.ctrl.T.Helper()
@@ -5093,6 +7224,21 @@ func (mr *MockStoreMockRecorder) InsertWorkspace(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspace", reflect.TypeOf((*MockStore)(nil).InsertWorkspace), ctx, arg)
 }
 
+// InsertWorkspaceAdoption mocks base method.
+func (m *MockStore) InsertWorkspaceAdoption(ctx context.Context, arg database.InsertWorkspaceAdoptionParams) (database.WorkspaceAdoption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAdoption", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceAdoption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceAdoption indicates an expected call of InsertWorkspaceAdoption.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAdoption(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAdoption", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAdoption), ctx, arg)
+}
+
 // InsertWorkspaceAgent mocks base method.
 func (m *MockStore) InsertWorkspaceAgent(ctx context.Context, arg database.InsertWorkspaceAgentParams) (database.WorkspaceAgent, error) {
 	m.ctrl.T.Helper()
@@ -5167,6 +7313,20 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceAgentMetadata(ctx, arg any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentMetadata), ctx, arg)
 }
 
+// InsertWorkspaceAgentPushedMetadata mocks base method.
+func (m *MockStore) InsertWorkspaceAgentPushedMetadata(ctx context.Context, arg database.InsertWorkspaceAgentPushedMetadataParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentPushedMetadata", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceAgentPushedMetadata indicates an expected call of InsertWorkspaceAgentPushedMetadata.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentPushedMetadata(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentPushedMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentPushedMetadata), ctx, arg)
+}
+
 // InsertWorkspaceAgentScriptTimings mocks base method.
 func (m *MockStore) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg database.InsertWorkspaceAgentScriptTimingsParams) (database.WorkspaceAgentScriptTiming, error) {
 	m.ctrl.T.Helper()
@@ -5240,6 +7400,21 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceAppStatus(ctx, arg any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAppStatus", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAppStatus), ctx, arg)
 }
 
+// InsertWorkspaceArchive mocks base method.
+func (m *MockStore) InsertWorkspaceArchive(ctx context.Context, arg database.InsertWorkspaceArchiveParams) (database.WorkspaceArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceArchive", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceArchive indicates an expected call of InsertWorkspaceArchive.
+func (mr *MockStoreMockRecorder) InsertWorkspaceArchive(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceArchive", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceArchive), ctx, arg)
+}
+
 // InsertWorkspaceBuild mocks base method.
 func (m *MockStore) InsertWorkspaceBuild(ctx context.Context, arg database.InsertWorkspaceBuildParams) error {
 	m.ctrl.T.Helper()
@@ -5268,6 +7443,34 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceBuildParameters(ctx, arg any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildParameters", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildParameters), ctx, arg)
 }
 
+// InsertWorkspaceBuildQuotaCosts mocks base method.
+func (m *MockStore) InsertWorkspaceBuildQuotaCosts(ctx context.Context, arg database.InsertWorkspaceBuildQuotaCostsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceBuildQuotaCosts", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceBuildQuotaCosts indicates an expected call of InsertWorkspaceBuildQuotaCosts.
+func (mr *MockStoreMockRecorder) InsertWorkspaceBuildQuotaCosts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildQuotaCosts", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildQuotaCosts), ctx, arg)
+}
+
+// InsertWorkspaceLabels mocks base method.
+func (m *MockStore) InsertWorkspaceLabels(ctx context.Context, arg database.InsertWorkspaceLabelsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceLabels", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceLabels indicates an expected call of InsertWorkspaceLabels.
+func (mr *MockStoreMockRecorder) InsertWorkspaceLabels(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceLabels", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceLabels), ctx, arg)
+}
+
 // InsertWorkspaceModule mocks base method.
 func (m *MockStore) InsertWorkspaceModule(ctx context.Context, arg database.InsertWorkspaceModuleParams) (database.WorkspaceModule, error) {
 	m.ctrl.T.Helper()
@@ -5283,6 +7486,21 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceModule(ctx, arg any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceModule", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceModule), ctx, arg)
 }
 
+// InsertWorkspacePortShareLink mocks base method.
+func (m *MockStore) InsertWorkspacePortShareLink(ctx context.Context, arg database.InsertWorkspacePortShareLinkParams) (database.WorkspacePortShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspacePortShareLink", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspacePortShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspacePortShareLink indicates an expected call of InsertWorkspacePortShareLink.
+func (mr *MockStoreMockRecorder) InsertWorkspacePortShareLink(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspacePortShareLink", reflect.TypeOf((*MockStore)(nil).InsertWorkspacePortShareLink), ctx, arg)
+}
+
 // InsertWorkspaceProxy mocks base method.
 func (m *MockStore) InsertWorkspaceProxy(ctx context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -5535,6 +7753,20 @@ func (mr *MockStoreMockRecorder) RemoveUserFromGroups(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserFromGroups", reflect.TypeOf((*MockStore)(nil).RemoveUserFromGroups), ctx, arg)
 }
 
+// RequeueProvisionerJob mocks base method.
+func (m *MockStore) RequeueProvisionerJob(ctx context.Context, arg database.RequeueProvisionerJobParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueProvisionerJob", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueProvisionerJob indicates an expected call of RequeueProvisionerJob.
+func (mr *MockStoreMockRecorder) RequeueProvisionerJob(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueProvisionerJob", reflect.TypeOf((*MockStore)(nil).RequeueProvisionerJob), ctx, arg)
+}
+
 // RevokeDBCryptKey mocks base method.
 func (m *MockStore) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
 	m.ctrl.T.Helper()
@@ -5549,6 +7781,66 @@ func (mr *MockStoreMockRecorder) RevokeDBCryptKey(ctx, activeKeyDigest any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeDBCryptKey", reflect.TypeOf((*MockStore)(nil).RevokeDBCryptKey), ctx, activeKeyDigest)
 }
 
+// RotateWorkspaceAgentAuthToken mocks base method.
+func (m *MockStore) RotateWorkspaceAgentAuthToken(ctx context.Context, arg database.RotateWorkspaceAgentAuthTokenParams) (database.WorkspaceAgentTokenRotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateWorkspaceAgentAuthToken", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceAgentTokenRotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateWorkspaceAgentAuthToken indicates an expected call of RotateWorkspaceAgentAuthToken.
+func (mr *MockStoreMockRecorder) RotateWorkspaceAgentAuthToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateWorkspaceAgentAuthToken", reflect.TypeOf((*MockStore)(nil).RotateWorkspaceAgentAuthToken), ctx, arg)
+}
+
+// RotateWorkspaceProxyToken mocks base method.
+func (m *MockStore) RotateWorkspaceProxyToken(ctx context.Context, arg database.RotateWorkspaceProxyTokenParams) (database.WorkspaceProxyTokenRotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateWorkspaceProxyToken", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceProxyTokenRotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateWorkspaceProxyToken indicates an expected call of RotateWorkspaceProxyToken.
+func (mr *MockStoreMockRecorder) RotateWorkspaceProxyToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateWorkspaceProxyToken", reflect.TypeOf((*MockStore)(nil).RotateWorkspaceProxyToken), ctx, arg)
+}
+
+// SearchProvisionerJobLogs mocks base method.
+func (m *MockStore) SearchProvisionerJobLogs(ctx context.Context, arg database.SearchProvisionerJobLogsParams) ([]database.SearchProvisionerJobLogsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchProvisionerJobLogs", ctx, arg)
+	ret0, _ := ret[0].([]database.SearchProvisionerJobLogsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchProvisionerJobLogs indicates an expected call of SearchProvisionerJobLogs.
+func (mr *MockStoreMockRecorder) SearchProvisionerJobLogs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchProvisionerJobLogs", reflect.TypeOf((*MockStore)(nil).SearchProvisionerJobLogs), ctx, arg)
+}
+
+// StartTemplateVersionRollout mocks base method.
+func (m *MockStore) StartTemplateVersionRollout(ctx context.Context, arg database.StartTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartTemplateVersionRollout", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateVersionRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartTemplateVersionRollout indicates an expected call of StartTemplateVersionRollout.
+func (mr *MockStoreMockRecorder) StartTemplateVersionRollout(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartTemplateVersionRollout", reflect.TypeOf((*MockStore)(nil).StartTemplateVersionRollout), ctx, arg)
+}
+
 // TryAcquireLock mocks base method.
 func (m *MockStore) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock int64) (bool, error) {
 	m.ctrl.T.Helper()
@@ -5592,6 +7884,20 @@ func (mr *MockStoreMockRecorder) UnfavoriteWorkspace(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfavoriteWorkspace", reflect.TypeOf((*MockStore)(nil).UnfavoriteWorkspace), ctx, id)
 }
 
+// UpdateAITaskQueueEntryFailed mocks base method.
+func (m *MockStore) UpdateAITaskQueueEntryFailed(ctx context.Context, arg database.UpdateAITaskQueueEntryFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAITaskQueueEntryFailed", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAITaskQueueEntryFailed indicates an expected call of UpdateAITaskQueueEntryFailed.
+func (mr *MockStoreMockRecorder) UpdateAITaskQueueEntryFailed(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAITaskQueueEntryFailed", reflect.TypeOf((*MockStore)(nil).UpdateAITaskQueueEntryFailed), ctx, arg)
+}
+
 // UpdateAPIKeyByID mocks base method.
 func (m *MockStore) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateAPIKeyByIDParams) error {
 	m.ctrl.T.Helper()
@@ -5724,6 +8030,21 @@ func (mr *MockStoreMockRecorder) UpdateInboxNotificationReadStatus(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInboxNotificationReadStatus", reflect.TypeOf((*MockStore)(nil).UpdateInboxNotificationReadStatus), ctx, arg)
 }
 
+// UpdateInboxNotificationsReadStatus mocks base method.
+func (m *MockStore) UpdateInboxNotificationsReadStatus(ctx context.Context, arg database.UpdateInboxNotificationsReadStatusParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInboxNotificationsReadStatus", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInboxNotificationsReadStatus indicates an expected call of UpdateInboxNotificationsReadStatus.
+func (mr *MockStoreMockRecorder) UpdateInboxNotificationsReadStatus(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInboxNotificationsReadStatus", reflect.TypeOf((*MockStore)(nil).UpdateInboxNotificationsReadStatus), ctx, arg)
+}
+
 // UpdateMemberRoles mocks base method.
 func (m *MockStore) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
 	m.ctrl.T.Helper()
@@ -5798,6 +8119,35 @@ func (mr *MockStoreMockRecorder) UpdateOAuth2ProviderAppByID(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2ProviderAppByID", reflect.TypeOf((*MockStore)(nil).UpdateOAuth2ProviderAppByID), ctx, arg)
 }
 
+// UpdateOAuth2ProviderAppDeviceCodeLastPolledAt mocks base method.
+func (m *MockStore) UpdateOAuth2ProviderAppDeviceCodeLastPolledAt(ctx context.Context, arg database.UpdateOAuth2ProviderAppDeviceCodeLastPolledAtParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOAuth2ProviderAppDeviceCodeLastPolledAt", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOAuth2ProviderAppDeviceCodeLastPolledAt indicates an expected call of UpdateOAuth2ProviderAppDeviceCodeLastPolledAt.
+func (mr *MockStoreMockRecorder) UpdateOAuth2ProviderAppDeviceCodeLastPolledAt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2ProviderAppDeviceCodeLastPolledAt", reflect.TypeOf((*MockStore)(nil).UpdateOAuth2ProviderAppDeviceCodeLastPolledAt), ctx, arg)
+}
+
+// UpdateOAuth2ProviderAppDeviceCodeUserID mocks base method.
+func (m *MockStore) UpdateOAuth2ProviderAppDeviceCodeUserID(ctx context.Context, arg database.UpdateOAuth2ProviderAppDeviceCodeUserIDParams) (database.OAuth2ProviderAppDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOAuth2ProviderAppDeviceCodeUserID", ctx, arg)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOAuth2ProviderAppDeviceCodeUserID indicates an expected call of UpdateOAuth2ProviderAppDeviceCodeUserID.
+func (mr *MockStoreMockRecorder) UpdateOAuth2ProviderAppDeviceCodeUserID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2ProviderAppDeviceCodeUserID", reflect.TypeOf((*MockStore)(nil).UpdateOAuth2ProviderAppDeviceCodeUserID), ctx, arg)
+}
+
 // UpdateOAuth2ProviderAppSecretByID mocks base method.
 func (m *MockStore) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
 	m.ctrl.T.Helper()
@@ -5983,6 +8333,20 @@ func (mr *MockStoreMockRecorder) UpdateTemplateAccessControlByID(ctx, arg any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateAccessControlByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateAccessControlByID), ctx, arg)
 }
 
+// UpdateTemplateActivationReviewers mocks base method.
+func (m *MockStore) UpdateTemplateActivationReviewers(ctx context.Context, arg database.UpdateTemplateActivationReviewersParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateActivationReviewers", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateActivationReviewers indicates an expected call of UpdateTemplateActivationReviewers.
+func (mr *MockStoreMockRecorder) UpdateTemplateActivationReviewers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateActivationReviewers", reflect.TypeOf((*MockStore)(nil).UpdateTemplateActivationReviewers), ctx, arg)
+}
+
 // UpdateTemplateActiveVersionByID mocks base method.
 func (m *MockStore) UpdateTemplateActiveVersionByID(ctx context.Context, arg database.UpdateTemplateActiveVersionByIDParams) error {
 	m.ctrl.T.Helper()
@@ -6025,6 +8389,20 @@ func (mr *MockStoreMockRecorder) UpdateTemplateMetaByID(ctx, arg any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateMetaByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateMetaByID), ctx, arg)
 }
 
+// UpdateTemplateOrganizationByID mocks base method.
+func (m *MockStore) UpdateTemplateOrganizationByID(ctx context.Context, arg database.UpdateTemplateOrganizationByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateOrganizationByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateOrganizationByID indicates an expected call of UpdateTemplateOrganizationByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateOrganizationByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateOrganizationByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateOrganizationByID), ctx, arg)
+}
+
 // UpdateTemplateScheduleByID mocks base method.
 func (m *MockStore) UpdateTemplateScheduleByID(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) error {
 	m.ctrl.T.Helper()
@@ -6033,10 +8411,25 @@ func (m *MockStore) UpdateTemplateScheduleByID(ctx context.Context, arg database
 	return ret0
 }
 
-// UpdateTemplateScheduleByID indicates an expected call of UpdateTemplateScheduleByID.
-func (mr *MockStoreMockRecorder) UpdateTemplateScheduleByID(ctx, arg any) *gomock.Call {
+// UpdateTemplateScheduleByID indicates an expected call of UpdateTemplateScheduleByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateScheduleByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateScheduleByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateScheduleByID), ctx, arg)
+}
+
+// UpdateTemplateSecret mocks base method.
+func (m *MockStore) UpdateTemplateSecret(ctx context.Context, arg database.UpdateTemplateSecretParams) (database.TemplateSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateSecret", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTemplateSecret indicates an expected call of UpdateTemplateSecret.
+func (mr *MockStoreMockRecorder) UpdateTemplateSecret(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateScheduleByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateScheduleByID), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateSecret", reflect.TypeOf((*MockStore)(nil).UpdateTemplateSecret), ctx, arg)
 }
 
 // UpdateTemplateVersionAITaskByJobID mocks base method.
@@ -6053,6 +8446,21 @@ func (mr *MockStoreMockRecorder) UpdateTemplateVersionAITaskByJobID(ctx, arg any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionAITaskByJobID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionAITaskByJobID), ctx, arg)
 }
 
+// UpdateTemplateVersionActivationRequestStatus mocks base method.
+func (m *MockStore) UpdateTemplateVersionActivationRequestStatus(ctx context.Context, arg database.UpdateTemplateVersionActivationRequestStatusParams) (database.TemplateVersionActivationRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateVersionActivationRequestStatus", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateVersionActivationRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTemplateVersionActivationRequestStatus indicates an expected call of UpdateTemplateVersionActivationRequestStatus.
+func (mr *MockStoreMockRecorder) UpdateTemplateVersionActivationRequestStatus(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionActivationRequestStatus", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionActivationRequestStatus), ctx, arg)
+}
+
 // UpdateTemplateVersionByID mocks base method.
 func (m *MockStore) UpdateTemplateVersionByID(ctx context.Context, arg database.UpdateTemplateVersionByIDParams) error {
 	m.ctrl.T.Helper()
@@ -6225,6 +8633,36 @@ func (mr *MockStoreMockRecorder) UpdateUserLoginType(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserLoginType", reflect.TypeOf((*MockStore)(nil).UpdateUserLoginType), ctx, arg)
 }
 
+// UpdateUserNotificationCategoryPreferences mocks base method.
+func (m *MockStore) UpdateUserNotificationCategoryPreferences(ctx context.Context, arg database.UpdateUserNotificationCategoryPreferencesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserNotificationCategoryPreferences", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserNotificationCategoryPreferences indicates an expected call of UpdateUserNotificationCategoryPreferences.
+func (mr *MockStoreMockRecorder) UpdateUserNotificationCategoryPreferences(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserNotificationCategoryPreferences", reflect.TypeOf((*MockStore)(nil).UpdateUserNotificationCategoryPreferences), ctx, arg)
+}
+
+// UpdateUserNotificationDigest mocks base method.
+func (m *MockStore) UpdateUserNotificationDigest(ctx context.Context, arg database.UpdateUserNotificationDigestParams) (database.UserConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserNotificationDigest", ctx, arg)
+	ret0, _ := ret[0].(database.UserConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserNotificationDigest indicates an expected call of UpdateUserNotificationDigest.
+func (mr *MockStoreMockRecorder) UpdateUserNotificationDigest(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserNotificationDigest", reflect.TypeOf((*MockStore)(nil).UpdateUserNotificationDigest), ctx, arg)
+}
+
 // UpdateUserNotificationPreferences mocks base method.
 func (m *MockStore) UpdateUserNotificationPreferences(ctx context.Context, arg database.UpdateUserNotificationPreferencesParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -6255,6 +8693,36 @@ func (mr *MockStoreMockRecorder) UpdateUserProfile(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserProfile", reflect.TypeOf((*MockStore)(nil).UpdateUserProfile), ctx, arg)
 }
 
+// UpdateUserProxyLatencies mocks base method.
+func (m *MockStore) UpdateUserProxyLatencies(ctx context.Context, arg database.UpdateUserProxyLatenciesParams) (database.UserConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserProxyLatencies", ctx, arg)
+	ret0, _ := ret[0].(database.UserConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserProxyLatencies indicates an expected call of UpdateUserProxyLatencies.
+func (mr *MockStoreMockRecorder) UpdateUserProxyLatencies(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserProxyLatencies", reflect.TypeOf((*MockStore)(nil).UpdateUserProxyLatencies), ctx, arg)
+}
+
+// UpdateUserProxyPreference mocks base method.
+func (m *MockStore) UpdateUserProxyPreference(ctx context.Context, arg database.UpdateUserProxyPreferenceParams) (database.UserConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserProxyPreference", ctx, arg)
+	ret0, _ := ret[0].(database.UserConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserProxyPreference indicates an expected call of UpdateUserProxyPreference.
+func (mr *MockStoreMockRecorder) UpdateUserProxyPreference(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserProxyPreference", reflect.TypeOf((*MockStore)(nil).UpdateUserProxyPreference), ctx, arg)
+}
+
 // UpdateUserQuietHoursSchedule mocks base method.
 func (m *MockStore) UpdateUserQuietHoursSchedule(ctx context.Context, arg database.UpdateUserQuietHoursScheduleParams) (database.User, error) {
 	m.ctrl.T.Helper()
@@ -6285,6 +8753,36 @@ func (mr *MockStoreMockRecorder) UpdateUserRoles(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRoles", reflect.TypeOf((*MockStore)(nil).UpdateUserRoles), ctx, arg)
 }
 
+// UpdateUserSecret mocks base method.
+func (m *MockStore) UpdateUserSecret(ctx context.Context, arg database.UpdateUserSecretParams) (database.UserSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserSecret", ctx, arg)
+	ret0, _ := ret[0].(database.UserSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserSecret indicates an expected call of UpdateUserSecret.
+func (mr *MockStoreMockRecorder) UpdateUserSecret(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserSecret", reflect.TypeOf((*MockStore)(nil).UpdateUserSecret), ctx, arg)
+}
+
+// UpdateUserStartOnConnect mocks base method.
+func (m *MockStore) UpdateUserStartOnConnect(ctx context.Context, arg database.UpdateUserStartOnConnectParams) (database.UserConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserStartOnConnect", ctx, arg)
+	ret0, _ := ret[0].(database.UserConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserStartOnConnect indicates an expected call of UpdateUserStartOnConnect.
+func (mr *MockStoreMockRecorder) UpdateUserStartOnConnect(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserStartOnConnect", reflect.TypeOf((*MockStore)(nil).UpdateUserStartOnConnect), ctx, arg)
+}
+
 // UpdateUserStatus mocks base method.
 func (m *MockStore) UpdateUserStatus(ctx context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
 	m.ctrl.T.Helper()
@@ -6330,6 +8828,35 @@ func (mr *MockStoreMockRecorder) UpdateUserThemePreference(ctx, arg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserThemePreference", reflect.TypeOf((*MockStore)(nil).UpdateUserThemePreference), ctx, arg)
 }
 
+// UpdateUserWebAuthnCredentialSignCount mocks base method.
+func (m *MockStore) UpdateUserWebAuthnCredentialSignCount(ctx context.Context, arg database.UpdateUserWebAuthnCredentialSignCountParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserWebAuthnCredentialSignCount", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserWebAuthnCredentialSignCount indicates an expected call of UpdateUserWebAuthnCredentialSignCount.
+func (mr *MockStoreMockRecorder) UpdateUserWebAuthnCredentialSignCount(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserWebAuthnCredentialSignCount", reflect.TypeOf((*MockStore)(nil).UpdateUserWebAuthnCredentialSignCount), ctx, arg)
+}
+
+// UpdateVariableSet mocks base method.
+func (m *MockStore) UpdateVariableSet(ctx context.Context, arg database.UpdateVariableSetParams) (database.VariableSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVariableSet", ctx, arg)
+	ret0, _ := ret[0].(database.VariableSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVariableSet indicates an expected call of UpdateVariableSet.
+func (mr *MockStoreMockRecorder) UpdateVariableSet(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVariableSet", reflect.TypeOf((*MockStore)(nil).UpdateVariableSet), ctx, arg)
+}
+
 // UpdateVolumeResourceMonitor mocks base method.
 func (m *MockStore) UpdateVolumeResourceMonitor(ctx context.Context, arg database.UpdateVolumeResourceMonitorParams) error {
 	m.ctrl.T.Helper()
@@ -6359,6 +8886,21 @@ func (mr *MockStoreMockRecorder) UpdateWorkspace(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspace", reflect.TypeOf((*MockStore)(nil).UpdateWorkspace), ctx, arg)
 }
 
+// UpdateWorkspaceAdoptionAdopted mocks base method.
+func (m *MockStore) UpdateWorkspaceAdoptionAdopted(ctx context.Context, arg database.UpdateWorkspaceAdoptionAdoptedParams) (database.WorkspaceAdoption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceAdoptionAdopted", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceAdoption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceAdoptionAdopted indicates an expected call of UpdateWorkspaceAdoptionAdopted.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceAdoptionAdopted(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAdoptionAdopted", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAdoptionAdopted), ctx, arg)
+}
+
 // UpdateWorkspaceAgentConnectionByID mocks base method.
 func (m *MockStore) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	m.ctrl.T.Helper()
@@ -6642,6 +9184,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspacesDormantDeletingAtByTemplateID(c
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspacesDormantDeletingAtByTemplateID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspacesDormantDeletingAtByTemplateID), ctx, arg)
 }
 
+// UpdateWorkspacesOrganizationByTemplateID mocks base method.
+func (m *MockStore) UpdateWorkspacesOrganizationByTemplateID(ctx context.Context, arg database.UpdateWorkspacesOrganizationByTemplateIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspacesOrganizationByTemplateID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspacesOrganizationByTemplateID indicates an expected call of UpdateWorkspacesOrganizationByTemplateID.
+func (mr *MockStoreMockRecorder) UpdateWorkspacesOrganizationByTemplateID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspacesOrganizationByTemplateID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspacesOrganizationByTemplateID), ctx, arg)
+}
+
 // UpdateWorkspacesTTLByTemplateID mocks base method.
 func (m *MockStore) UpdateWorkspacesTTLByTemplateID(ctx context.Context, arg database.UpdateWorkspacesTTLByTemplateIDParams) error {
 	m.ctrl.T.Helper()
@@ -6726,6 +9282,20 @@ func (mr *MockStoreMockRecorder) UpsertDefaultProxy(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertDefaultProxy", reflect.TypeOf((*MockStore)(nil).UpsertDefaultProxy), ctx, arg)
 }
 
+// UpsertExperimentFlags mocks base method.
+func (m *MockStore) UpsertExperimentFlags(ctx context.Context, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertExperimentFlags", ctx, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertExperimentFlags indicates an expected call of UpsertExperimentFlags.
+func (mr *MockStoreMockRecorder) UpsertExperimentFlags(ctx, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertExperimentFlags", reflect.TypeOf((*MockStore)(nil).UpsertExperimentFlags), ctx, value)
+}
+
 // UpsertHealthSettings mocks base method.
 func (m *MockStore) UpsertHealthSettings(ctx context.Context, value string) error {
 	m.ctrl.T.Helper()
@@ -6740,6 +9310,35 @@ func (mr *MockStoreMockRecorder) UpsertHealthSettings(ctx, value any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertHealthSettings", reflect.TypeOf((*MockStore)(nil).UpsertHealthSettings), ctx, value)
 }
 
+// UpsertInternalCA mocks base method.
+func (m *MockStore) UpsertInternalCA(ctx context.Context, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertInternalCA", ctx, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertInternalCA indicates an expected call of UpsertInternalCA.
+func (mr *MockStoreMockRecorder) UpsertInternalCA(ctx, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertInternalCA", reflect.TypeOf((*MockStore)(nil).UpsertInternalCA), ctx, value)
+}
+
+// UpsertLDAPUser mocks base method.
+func (m *MockStore) UpsertLDAPUser(ctx context.Context, arg database.UpsertLDAPUserParams) (database.LDAPUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertLDAPUser", ctx, arg)
+	ret0, _ := ret[0].(database.LDAPUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertLDAPUser indicates an expected call of UpsertLDAPUser.
+func (mr *MockStoreMockRecorder) UpsertLDAPUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLDAPUser", reflect.TypeOf((*MockStore)(nil).UpsertLDAPUser), ctx, arg)
+}
+
 // UpsertLastUpdateCheck mocks base method.
 func (m *MockStore) UpsertLastUpdateCheck(ctx context.Context, value string) error {
 	m.ctrl.T.Helper()
@@ -6754,6 +9353,21 @@ func (mr *MockStoreMockRecorder) UpsertLastUpdateCheck(ctx, value any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLastUpdateCheck", reflect.TypeOf((*MockStore)(nil).UpsertLastUpdateCheck), ctx, value)
 }
 
+// UpsertLicenseSeatReservation mocks base method.
+func (m *MockStore) UpsertLicenseSeatReservation(ctx context.Context, arg database.UpsertLicenseSeatReservationParams) (database.LicenseSeatReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertLicenseSeatReservation", ctx, arg)
+	ret0, _ := ret[0].(database.LicenseSeatReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertLicenseSeatReservation indicates an expected call of UpsertLicenseSeatReservation.
+func (mr *MockStoreMockRecorder) UpsertLicenseSeatReservation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLicenseSeatReservation", reflect.TypeOf((*MockStore)(nil).UpsertLicenseSeatReservation), ctx, arg)
+}
+
 // UpsertLogoURL mocks base method.
 func (m *MockStore) UpsertLogoURL(ctx context.Context, value string) error {
 	m.ctrl.T.Helper()
@@ -6768,6 +9382,20 @@ func (mr *MockStoreMockRecorder) UpsertLogoURL(ctx, value any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLogoURL", reflect.TypeOf((*MockStore)(nil).UpsertLogoURL), ctx, value)
 }
 
+// UpsertNetworkConnectionQuality mocks base method.
+func (m *MockStore) UpsertNetworkConnectionQuality(ctx context.Context, arg database.UpsertNetworkConnectionQualityParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNetworkConnectionQuality", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertNetworkConnectionQuality indicates an expected call of UpsertNetworkConnectionQuality.
+func (mr *MockStoreMockRecorder) UpsertNetworkConnectionQuality(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNetworkConnectionQuality", reflect.TypeOf((*MockStore)(nil).UpsertNetworkConnectionQuality), ctx, arg)
+}
+
 // UpsertNotificationReportGeneratorLog mocks base method.
 func (m *MockStore) UpsertNotificationReportGeneratorLog(ctx context.Context, arg database.UpsertNotificationReportGeneratorLogParams) error {
 	m.ctrl.T.Helper()
@@ -6824,6 +9452,51 @@ func (mr *MockStoreMockRecorder) UpsertOAuthSigningKey(ctx, value any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOAuthSigningKey", reflect.TypeOf((*MockStore)(nil).UpsertOAuthSigningKey), ctx, value)
 }
 
+// UpsertOrganizationAuditSettings mocks base method.
+func (m *MockStore) UpsertOrganizationAuditSettings(ctx context.Context, arg database.UpsertOrganizationAuditSettingsParams) (database.OrganizationAuditSetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertOrganizationAuditSettings", ctx, arg)
+	ret0, _ := ret[0].(database.OrganizationAuditSetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertOrganizationAuditSettings indicates an expected call of UpsertOrganizationAuditSettings.
+func (mr *MockStoreMockRecorder) UpsertOrganizationAuditSettings(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOrganizationAuditSettings", reflect.TypeOf((*MockStore)(nil).UpsertOrganizationAuditSettings), ctx, arg)
+}
+
+// UpsertOrganizationMFASettings mocks base method.
+func (m *MockStore) UpsertOrganizationMFASettings(ctx context.Context, arg database.UpsertOrganizationMFASettingsParams) (database.OrganizationMFASetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertOrganizationMFASettings", ctx, arg)
+	ret0, _ := ret[0].(database.OrganizationMFASetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertOrganizationMFASettings indicates an expected call of UpsertOrganizationMFASettings.
+func (mr *MockStoreMockRecorder) UpsertOrganizationMFASettings(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOrganizationMFASettings", reflect.TypeOf((*MockStore)(nil).UpsertOrganizationMFASettings), ctx, arg)
+}
+
+// UpsertOrganizationSettingOverrides mocks base method.
+func (m *MockStore) UpsertOrganizationSettingOverrides(ctx context.Context, arg database.UpsertOrganizationSettingOverridesParams) (database.OrganizationSettingOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertOrganizationSettingOverrides", ctx, arg)
+	ret0, _ := ret[0].(database.OrganizationSettingOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertOrganizationSettingOverrides indicates an expected call of UpsertOrganizationSettingOverrides.
+func (mr *MockStoreMockRecorder) UpsertOrganizationSettingOverrides(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOrganizationSettingOverrides", reflect.TypeOf((*MockStore)(nil).UpsertOrganizationSettingOverrides), ctx, arg)
+}
+
 // UpsertPrebuildsSettings mocks base method.
 func (m *MockStore) UpsertPrebuildsSettings(ctx context.Context, value string) error {
 	m.ctrl.T.Helper()
@@ -6853,6 +9526,21 @@ func (mr *MockStoreMockRecorder) UpsertProvisionerDaemon(ctx, arg any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertProvisionerDaemon", reflect.TypeOf((*MockStore)(nil).UpsertProvisionerDaemon), ctx, arg)
 }
 
+// UpsertReviewWorkspace mocks base method.
+func (m *MockStore) UpsertReviewWorkspace(ctx context.Context, arg database.UpsertReviewWorkspaceParams) (database.ReviewWorkspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertReviewWorkspace", ctx, arg)
+	ret0, _ := ret[0].(database.ReviewWorkspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertReviewWorkspace indicates an expected call of UpsertReviewWorkspace.
+func (mr *MockStoreMockRecorder) UpsertReviewWorkspace(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertReviewWorkspace", reflect.TypeOf((*MockStore)(nil).UpsertReviewWorkspace), ctx, arg)
+}
+
 // UpsertRuntimeConfig mocks base method.
 func (m *MockStore) UpsertRuntimeConfig(ctx context.Context, arg database.UpsertRuntimeConfigParams) error {
 	m.ctrl.T.Helper()
@@ -6970,6 +9658,66 @@ func (mr *MockStoreMockRecorder) UpsertTelemetryItem(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTelemetryItem", reflect.TypeOf((*MockStore)(nil).UpsertTelemetryItem), ctx, arg)
 }
 
+// UpsertTemplateAppRestriction mocks base method.
+func (m *MockStore) UpsertTemplateAppRestriction(ctx context.Context, arg database.UpsertTemplateAppRestrictionParams) (database.TemplateAppRestriction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateAppRestriction", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateAppRestriction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTemplateAppRestriction indicates an expected call of UpsertTemplateAppRestriction.
+func (mr *MockStoreMockRecorder) UpsertTemplateAppRestriction(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateAppRestriction", reflect.TypeOf((*MockStore)(nil).UpsertTemplateAppRestriction), ctx, arg)
+}
+
+// UpsertTemplateAppSessionPolicy mocks base method.
+func (m *MockStore) UpsertTemplateAppSessionPolicy(ctx context.Context, arg database.UpsertTemplateAppSessionPolicyParams) (database.TemplateAppSessionPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateAppSessionPolicy", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateAppSessionPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTemplateAppSessionPolicy indicates an expected call of UpsertTemplateAppSessionPolicy.
+func (mr *MockStoreMockRecorder) UpsertTemplateAppSessionPolicy(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateAppSessionPolicy", reflect.TypeOf((*MockStore)(nil).UpsertTemplateAppSessionPolicy), ctx, arg)
+}
+
+// UpsertTemplateEgressPolicy mocks base method.
+func (m *MockStore) UpsertTemplateEgressPolicy(ctx context.Context, arg database.UpsertTemplateEgressPolicyParams) (database.TemplateEgressPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateEgressPolicy", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateEgressPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTemplateEgressPolicy indicates an expected call of UpsertTemplateEgressPolicy.
+func (mr *MockStoreMockRecorder) UpsertTemplateEgressPolicy(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateEgressPolicy", reflect.TypeOf((*MockStore)(nil).UpsertTemplateEgressPolicy), ctx, arg)
+}
+
+// UpsertTemplateParameterOptionSource mocks base method.
+func (m *MockStore) UpsertTemplateParameterOptionSource(ctx context.Context, arg database.UpsertTemplateParameterOptionSourceParams) (database.TemplateParameterOptionSource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateParameterOptionSource", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateParameterOptionSource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTemplateParameterOptionSource indicates an expected call of UpsertTemplateParameterOptionSource.
+func (mr *MockStoreMockRecorder) UpsertTemplateParameterOptionSource(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateParameterOptionSource", reflect.TypeOf((*MockStore)(nil).UpsertTemplateParameterOptionSource), ctx, arg)
+}
+
 // UpsertTemplateUsageStats mocks base method.
 func (m *MockStore) UpsertTemplateUsageStats(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -6984,6 +9732,65 @@ func (mr *MockStoreMockRecorder) UpsertTemplateUsageStats(ctx any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateUsageStats", reflect.TypeOf((*MockStore)(nil).UpsertTemplateUsageStats), ctx)
 }
 
+// UpsertTemplateVersionActivationRequest mocks base method.
+func (m *MockStore) UpsertTemplateVersionActivationRequest(ctx context.Context, arg database.UpsertTemplateVersionActivationRequestParams) (database.TemplateVersionActivationRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateVersionActivationRequest", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateVersionActivationRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTemplateVersionActivationRequest indicates an expected call of UpsertTemplateVersionActivationRequest.
+func (mr *MockStoreMockRecorder) UpsertTemplateVersionActivationRequest(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateVersionActivationRequest", reflect.TypeOf((*MockStore)(nil).UpsertTemplateVersionActivationRequest), ctx, arg)
+}
+
+// UpsertTemplateVersionActivationReview mocks base method.
+func (m *MockStore) UpsertTemplateVersionActivationReview(ctx context.Context, arg database.UpsertTemplateVersionActivationReviewParams) (database.TemplateVersionActivationReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateVersionActivationReview", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateVersionActivationReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTemplateVersionActivationReview indicates an expected call of UpsertTemplateVersionActivationReview.
+func (mr *MockStoreMockRecorder) UpsertTemplateVersionActivationReview(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateVersionActivationReview", reflect.TypeOf((*MockStore)(nil).UpsertTemplateVersionActivationReview), ctx, arg)
+}
+
+// UpsertTemplateVersionRollout mocks base method.
+func (m *MockStore) UpsertTemplateVersionRollout(ctx context.Context, arg database.UpsertTemplateVersionRolloutParams) (database.TemplateVersionRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateVersionRollout", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateVersionRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTemplateVersionRollout indicates an expected call of UpsertTemplateVersionRollout.
+func (mr *MockStoreMockRecorder) UpsertTemplateVersionRollout(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateVersionRollout", reflect.TypeOf((*MockStore)(nil).UpsertTemplateVersionRollout), ctx, arg)
+}
+
+// UpsertUserCLIVersion mocks base method.
+func (m *MockStore) UpsertUserCLIVersion(ctx context.Context, arg database.UpsertUserCLIVersionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserCLIVersion", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertUserCLIVersion indicates an expected call of UpsertUserCLIVersion.
+func (mr *MockStoreMockRecorder) UpsertUserCLIVersion(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserCLIVersion", reflect.TypeOf((*MockStore)(nil).UpsertUserCLIVersion), ctx, arg)
+}
+
 // UpsertWebpushVAPIDKeys mocks base method.
 func (m *MockStore) UpsertWebpushVAPIDKeys(ctx context.Context, arg database.UpsertWebpushVAPIDKeysParams) error {
 	m.ctrl.T.Helper()
@@ -7013,6 +9820,20 @@ func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentPortShare(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentPortShare", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentPortShare), ctx, arg)
 }
 
+// UpsertWorkspaceAgentVolumeUsage mocks base method.
+func (m *MockStore) UpsertWorkspaceAgentVolumeUsage(ctx context.Context, arg database.UpsertWorkspaceAgentVolumeUsageParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceAgentVolumeUsage", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceAgentVolumeUsage indicates an expected call of UpsertWorkspaceAgentVolumeUsage.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentVolumeUsage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentVolumeUsage", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentVolumeUsage), ctx, arg)
+}
+
 // UpsertWorkspaceApp mocks base method.
 func (m *MockStore) UpsertWorkspaceApp(ctx context.Context, arg database.UpsertWorkspaceAppParams) (database.WorkspaceApp, error) {
 	m.ctrl.T.Helper()
@@ -7043,6 +9864,36 @@ func (mr *MockStoreMockRecorder) UpsertWorkspaceAppAuditSession(ctx, arg any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAppAuditSession", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAppAuditSession), ctx, arg)
 }
 
+// UpsertWorkspaceSchedulePause mocks base method.
+func (m *MockStore) UpsertWorkspaceSchedulePause(ctx context.Context, arg database.UpsertWorkspaceSchedulePauseParams) (database.WorkspaceSchedulePause, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceSchedulePause", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceSchedulePause)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkspaceSchedulePause indicates an expected call of UpsertWorkspaceSchedulePause.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceSchedulePause(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceSchedulePause", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceSchedulePause), ctx, arg)
+}
+
+// UseUserMFARecoveryCode mocks base method.
+func (m *MockStore) UseUserMFARecoveryCode(ctx context.Context, arg database.UseUserMFARecoveryCodeParams) (database.UserMFARecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseUserMFARecoveryCode", ctx, arg)
+	ret0, _ := ret[0].(database.UserMFARecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseUserMFARecoveryCode indicates an expected call of UseUserMFARecoveryCode.
+func (mr *MockStoreMockRecorder) UseUserMFARecoveryCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseUserMFARecoveryCode", reflect.TypeOf((*MockStore)(nil).UseUserMFARecoveryCode), ctx, arg)
+}
+
 // Wrappers mocks base method.
 func (m *MockStore) Wrappers() []string {
 	m.ctrl.T.Helper()
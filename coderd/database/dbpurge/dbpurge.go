@@ -9,6 +9,7 @@ import (
 
 	"cdr.dev/slog"
 
+	"github.com/coder/coder/v2/coderd/connectionquality"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
@@ -18,6 +19,13 @@ import (
 const (
 	delay          = 10 * time.Minute
 	maxAgentLogAge = 7 * 24 * time.Hour
+	// maxHealthScoreAge is how long health scores are kept to compute
+	// availability over a month.
+	maxHealthScoreAge = 31 * 24 * time.Hour
+	// auditLogPurgeLimit bounds the number of audit logs deleted per tick, so
+	// that enabling retention on a large backlog does not hold the purge
+	// transaction for long.
+	auditLogPurgeLimit = 10000
 )
 
 // New creates a new periodically purging database instance.
@@ -62,8 +70,32 @@ func New(ctx context.Context, logger slog.Logger, db database.Store, clk quartz.
 			if err := tx.DeleteOldNotificationMessages(ctx); err != nil {
 				return xerrors.Errorf("failed to delete old notification messages: %w", err)
 			}
+			if err := tx.DeleteExpiredWebAuthnChallenges(ctx, start); err != nil {
+				return xerrors.Errorf("failed to delete expired webauthn challenges: %w", err)
+			}
+			if err := tx.DeleteExpiredWorkspacePortShareLinks(ctx, start); err != nil {
+				return xerrors.Errorf("failed to delete expired workspace port share links: %w", err)
+			}
+			if err := tx.DeleteOldHealthScores(ctx, start.Add(-maxHealthScoreAge)); err != nil {
+				return xerrors.Errorf("failed to delete old health scores: %w", err)
+			}
+			if err := tx.DeleteOldNetworkConnectionQuality(ctx, start.Add(-connectionquality.DefaultStaleAfter)); err != nil {
+				return xerrors.Errorf("failed to delete old network connection quality: %w", err)
+			}
+			// Audit logs are only purged for organizations with a retention
+			// period, and never when they are under a legal hold.
+			purgedAuditLogs, err := tx.DeleteOldAuditLogs(ctx, database.DeleteOldAuditLogsParams{
+				Now:        start,
+				LimitCount: auditLogPurgeLimit,
+			})
+			if err != nil {
+				return xerrors.Errorf("failed to delete old audit logs: %w", err)
+			}
 
-			logger.Debug(ctx, "purged old database entries", slog.F("duration", clk.Since(start)))
+			logger.Debug(ctx, "purged old database entries",
+				slog.F("duration", clk.Since(start)),
+				slog.F("audit_logs", purgedAuditLogs),
+			)
 
 			return nil
 		}, database.DefaultTXOptions().WithID("db_purge")); err != nil {
@@ -490,3 +490,67 @@ func containsProvisionerDaemon(daemons []database.ProvisionerDaemon, name string
 		return d.Name == name
 	})
 }
+
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestDeleteOldAuditLogs(t *testing.T) {
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+	old := now.AddDate(0, 0, -31)
+	recent := now.AddDate(0, 0, -29)
+
+	db, _ := dbtestutil.NewDB(t, dbtestutil.WithDumpOnFailure())
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+
+	// Org A keeps audit logs for 30 days, org B keeps them forever.
+	orgA := dbgen.Organization(t, db, database.Organization{})
+	orgB := dbgen.Organization(t, db, database.Organization{})
+	_, err := db.UpsertOrganizationAuditSettings(ctx, database.UpsertOrganizationAuditSettingsParams{
+		OrganizationID: orgA.ID,
+		RetentionDays:  30,
+		UpdatedAt:      now,
+	})
+	require.NoError(t, err)
+
+	user := dbgen.User(t, db, database.User{})
+	heldUser := dbgen.User(t, db, database.User{})
+	_, err = db.InsertAuditLogLegalHold(ctx, database.InsertAuditLogLegalHoldParams{
+		ID:             uuid.New(),
+		OrganizationID: orgA.ID,
+		UserID:         uuid.NullUUID{UUID: heldUser.ID, Valid: true},
+		Reason:         "litigation",
+		CreatedBy:      user.ID,
+		CreatedAt:      now,
+	})
+	require.NoError(t, err)
+
+	expired := dbgen.AuditLog(t, db, database.AuditLog{OrganizationID: orgA.ID, UserID: user.ID, Time: old})
+	retained := dbgen.AuditLog(t, db, database.AuditLog{OrganizationID: orgA.ID, UserID: user.ID, Time: recent})
+	held := dbgen.AuditLog(t, db, database.AuditLog{OrganizationID: orgA.ID, UserID: heldUser.ID, Time: old})
+	heldResource := dbgen.AuditLog(t, db, database.AuditLog{
+		OrganizationID: orgA.ID,
+		UserID:         user.ID,
+		ResourceType:   database.ResourceTypeUser,
+		ResourceID:     heldUser.ID,
+		Time:           old,
+	})
+	noRetention := dbgen.AuditLog(t, db, database.AuditLog{OrganizationID: orgB.ID, UserID: user.ID, Time: old})
+
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, clk)
+	defer closer.Close()
+	<-done // doTick() has now run.
+
+	logs, err := db.GetAuditLogsOffset(ctx, database.GetAuditLogsOffsetParams{})
+	require.NoError(t, err)
+	ids := make([]uuid.UUID, 0, len(logs))
+	for _, log := range logs {
+		ids = append(ids, log.AuditLog.ID)
+	}
+	require.NotContains(t, ids, expired.ID)
+	require.Contains(t, ids, retained.ID)
+	require.Contains(t, ids, held.ID)
+	require.Contains(t, ids, heldResource.ID)
+	require.Contains(t, ids, noRetention.ID)
+}
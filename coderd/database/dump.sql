@@ -10,9 +10,15 @@ CREATE TYPE agent_key_scope_enum AS ENUM (
     'no_user_data'
 );
 
+CREATE TYPE ai_task_queue_status AS ENUM (
+    'queued',
+    'failed'
+);
+
 CREATE TYPE api_key_scope AS ENUM (
     'all',
-    'application_connect'
+    'application_connect',
+    'workspace_connect'
 );
 
 CREATE TYPE app_sharing_level AS ENUM (
@@ -35,7 +41,10 @@ CREATE TYPE audit_action AS ENUM (
     'connect',
     'disconnect',
     'open',
-    'close'
+    'close',
+    'read',
+    'egress_violation',
+    'network_zone_violation'
 );
 
 CREATE TYPE automatic_updates AS ENUM (
@@ -49,7 +58,8 @@ CREATE TYPE build_reason AS ENUM (
     'autostop',
     'dormancy',
     'failedstop',
-    'autodelete'
+    'autodelete',
+    'autoarchive'
 );
 
 CREATE TYPE crypto_key_feature AS ENUM (
@@ -69,7 +79,9 @@ CREATE TYPE display_app AS ENUM (
 
 CREATE TYPE group_source AS ENUM (
     'user',
-    'oidc'
+    'oidc',
+    'scim',
+    'ldap'
 );
 
 CREATE TYPE inbox_notification_read_status AS ENUM (
@@ -97,7 +109,8 @@ CREATE TYPE login_type AS ENUM (
     'oidc',
     'token',
     'none',
-    'oauth2_provider_app'
+    'oauth2_provider_app',
+    'saml'
 );
 
 COMMENT ON TYPE login_type IS 'Specifies the method of authentication. "none" is a special case in which no authentication method is allowed.';
@@ -107,6 +120,13 @@ CREATE TYPE name_organization_pair AS (
 	organization_id uuid
 );
 
+CREATE TYPE notification_category AS ENUM (
+    'builds',
+    'dormancy',
+    'account',
+    'template_updates'
+);
+
 CREATE TYPE notification_message_status AS ENUM (
     'pending',
     'leased',
@@ -120,7 +140,15 @@ CREATE TYPE notification_message_status AS ENUM (
 CREATE TYPE notification_method AS ENUM (
     'smtp',
     'webhook',
-    'inbox'
+    'inbox',
+    'slack',
+    'teams'
+);
+
+CREATE TYPE notification_priority AS ENUM (
+    'low',
+    'normal',
+    'high'
 );
 
 CREATE TYPE notification_template_kind AS ENUM (
@@ -243,7 +271,14 @@ CREATE TYPE resource_type AS ENUM (
     'idp_sync_settings_role',
     'workspace_agent',
     'workspace_app',
-    'prebuilds_settings'
+    'prebuilds_settings',
+    'template_version_activation_request',
+    'user_secret',
+    'template_secret',
+    'template_egress_policy',
+    'workspace_port_share_link',
+    'variable_set',
+    'template_version_rollout'
 );
 
 CREATE TYPE startup_script_behavior AS ENUM (
@@ -260,6 +295,21 @@ CREATE TYPE tailnet_status AS ENUM (
     'lost'
 );
 
+CREATE TYPE template_version_activation_request_status AS ENUM (
+    'pending',
+    'approved',
+    'rejected',
+    'canceled'
+);
+
+CREATE TYPE template_version_rollout_status AS ENUM (
+    'in_progress',
+    'halted',
+    'completed',
+    'canceled',
+    'pending_approval'
+);
+
 CREATE TYPE user_status AS ENUM (
     'active',
     'suspended',
@@ -268,6 +318,14 @@ CREATE TYPE user_status AS ENUM (
 
 COMMENT ON TYPE user_status IS 'Defines the users status: active, dormant, or suspended.';
 
+CREATE TYPE webauthn_challenge_purpose AS ENUM (
+    'registration',
+    'login',
+    'enrollment'
+);
+
+COMMENT ON TYPE webauthn_challenge_purpose IS 'registration challenges add a credential for a signed in user, login challenges complete a password login with an existing credential, and enrollment challenges complete a password login by adding the first credential of a user.';
+
 CREATE TYPE workspace_agent_lifecycle_state AS ENUM (
     'created',
     'starting',
@@ -450,6 +508,8 @@ BEGIN
 	--  * the user has disabled this notification.
 	--  * the notification template is disabled by default and hasn't
 	--    been explicitly enabled by the user.
+	--  * the organization of a targeted workspace or template has disabled
+	--    this notification.
 	IF EXISTS (
 		SELECT 1 FROM notification_templates
 		LEFT JOIN notification_preferences
@@ -462,6 +522,14 @@ BEGIN
 			-- Case 2: The template is disabled by default AND the user hasn't enabled it
 			(notification_templates.enabled_by_default = FALSE AND notification_preferences.notification_template_id IS NULL)
 		)
+	) OR EXISTS (
+		SELECT 1 FROM organization_setting_overrides
+		WHERE NEW.notification_template_id = ANY(organization_setting_overrides.disabled_notification_template_ids)
+		AND organization_setting_overrides.organization_id IN (
+			SELECT organization_id FROM workspaces WHERE id = ANY(NEW.targets)
+			UNION
+			SELECT organization_id FROM templates WHERE id = ANY(NEW.targets)
+		)
 	) THEN
 		RAISE EXCEPTION 'cannot enqueue message: notification is not enabled';
 	END IF;
@@ -656,6 +724,24 @@ BEGIN
 END;
 $$;
 
+CREATE FUNCTION record_workspace_agent_lifecycle_event() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+	IF OLD.lifecycle_state IS DISTINCT FROM NEW.lifecycle_state THEN
+		INSERT INTO workspace_agent_lifecycle_events (
+			workspace_agent_id,
+			lifecycle_state
+		) VALUES (
+			NEW.id,
+			NEW.lifecycle_state
+		);
+	END IF;
+
+	RETURN NEW;
+END;
+$$;
+
 CREATE FUNCTION remove_organization_member_role() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
@@ -788,6 +874,24 @@ BEGIN
 END;
 $$;
 
+CREATE TABLE ai_task_queue (
+    id uuid NOT NULL,
+    owner_id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    template_version_id uuid NOT NULL,
+    template_version_preset_id uuid,
+    name text NOT NULL,
+    prompt text NOT NULL,
+    status ai_task_queue_status DEFAULT 'queued'::ai_task_queue_status NOT NULL,
+    error text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE ai_task_queue IS 'AI tasks that wait for a running task of their owner to stop. Tasks are removed from the queue when their workspace is created.';
+
+COMMENT ON COLUMN ai_task_queue.error IS 'Why the workspace of the task could not be created, if the status is failed.';
+
 CREATE TABLE api_keys (
     id text NOT NULL,
     hashed_secret bytea NOT NULL,
@@ -800,11 +904,33 @@ CREATE TABLE api_keys (
     lifetime_seconds bigint DEFAULT 86400 NOT NULL,
     ip_address inet DEFAULT '0.0.0.0'::inet NOT NULL,
     scope api_key_scope DEFAULT 'all'::api_key_scope NOT NULL,
-    token_name text DEFAULT ''::text NOT NULL
+    token_name text DEFAULT ''::text NOT NULL,
+    user_agent text DEFAULT ''::text NOT NULL,
+    device_fingerprint text DEFAULT ''::text NOT NULL,
+    workspace_id uuid
 );
 
 COMMENT ON COLUMN api_keys.hashed_secret IS 'hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.';
 
+COMMENT ON COLUMN api_keys.user_agent IS 'The User-Agent header of the request that created the key.';
+
+COMMENT ON COLUMN api_keys.device_fingerprint IS 'A hash of the request headers that identify the device that created the key, used to tell sessions apart.';
+
+COMMENT ON COLUMN api_keys.workspace_id IS 'The workspace that keys with the workspace_connect scope can connect to. Keys with other scopes are not limited to a workspace.';
+
+CREATE TABLE audit_log_legal_holds (
+    id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    user_id uuid,
+    workspace_id uuid,
+    reason text NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    CONSTRAINT audit_log_legal_holds_target CHECK (((user_id IS NULL) <> (workspace_id IS NULL)))
+);
+
+COMMENT ON TABLE audit_log_legal_holds IS 'Audit logs of the held user or workspace are never purged, regardless of the retention period of the organization.';
+
 CREATE TABLE audit_logs (
     id uuid NOT NULL,
     "time" timestamp with time zone NOT NULL,
@@ -896,9 +1022,12 @@ CREATE TABLE files (
     created_by uuid NOT NULL,
     mimetype character varying(64) NOT NULL,
     data bytea NOT NULL,
-    id uuid DEFAULT gen_random_uuid() NOT NULL
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    external boolean DEFAULT false NOT NULL
 );
 
+COMMENT ON COLUMN files.external IS 'Whether the contents of the file are kept in object storage under the hash of the file, instead of in the data column.';
+
 CREATE TABLE gitsshkeys (
     user_id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -912,6 +1041,15 @@ CREATE TABLE group_members (
     group_id uuid NOT NULL
 );
 
+CREATE TABLE group_quota_allowances (
+    group_id uuid NOT NULL,
+    dimension text NOT NULL,
+    allowance bigint NOT NULL,
+    CONSTRAINT group_quota_allowances_allowance_check CHECK ((allowance >= 0))
+);
+
+COMMENT ON TABLE group_quota_allowances IS 'Allowances of quota dimensions other than the daily cost, such as cpu or memory. A dimension is only enforced in an organization if a group of the organization has an allowance for it.';
+
 CREATE TABLE groups (
     id uuid NOT NULL,
     name text NOT NULL,
@@ -926,6 +1064,23 @@ COMMENT ON COLUMN groups.display_name IS 'Display name is a custom, human-friend
 
 COMMENT ON COLUMN groups.source IS 'Source indicates how the group was created. It can be created by a user manually, or through some system process like OIDC group sync.';
 
+CREATE TABLE organization_audit_settings (
+    organization_id uuid NOT NULL,
+    retention_days integer DEFAULT 0 NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    CONSTRAINT organization_audit_settings_retention_days_check CHECK ((retention_days >= 0))
+);
+
+COMMENT ON COLUMN organization_audit_settings.retention_days IS 'Number of days the audit logs of the organization are kept for. Zero keeps them forever.';
+
+CREATE TABLE organization_mfa_settings (
+    organization_id uuid NOT NULL,
+    require_webauthn boolean DEFAULT false NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON COLUMN organization_mfa_settings.require_webauthn IS 'Members of the organization that log in with a password must use a WebAuthn credential as a second factor.';
+
 CREATE TABLE organization_members (
     user_id uuid NOT NULL,
     organization_id uuid NOT NULL,
@@ -1004,6 +1159,20 @@ CREATE VIEW group_members_expanded AS
 
 COMMENT ON VIEW group_members_expanded IS 'Joins group members with user information, organization ID, group name. Includes both regular group members and organization members (as part of the "Everyone" group).';
 
+CREATE TABLE health_scores (
+    replica_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    score integer NOT NULL,
+    database_score integer NOT NULL,
+    database_latency_ms bigint NOT NULL,
+    derp_score integer NOT NULL,
+    provisioner_daemons_score integer NOT NULL,
+    replicas_score integer NOT NULL,
+    websocket_score integer NOT NULL
+);
+
+COMMENT ON TABLE health_scores IS 'Scores of the health checks that each replica runs periodically. Scores range from 0 (failing) to 100 (healthy).';
+
 CREATE TABLE inbox_notifications (
     id uuid NOT NULL,
     user_id uuid NOT NULL,
@@ -1026,6 +1195,28 @@ CREATE TABLE jfrog_xray_scans (
     results_url text DEFAULT ''::text NOT NULL
 );
 
+CREATE TABLE ldap_users (
+    user_id uuid NOT NULL,
+    external_id text NOT NULL,
+    dn text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE ldap_users IS 'Users provisioned or linked by LDAP sync. Linked users are suspended when the directory stops returning them.';
+
+COMMENT ON COLUMN ldap_users.external_id IS 'Value of the directory attribute that identifies the user, such as objectGUID or entryUUID. Unlike the DN, it does not change when the user is renamed or moved.';
+
+CREATE TABLE license_seat_reservations (
+    group_id uuid NOT NULL,
+    seats integer NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    CONSTRAINT license_seat_reservations_seats_check CHECK ((seats > 0))
+);
+
+COMMENT ON TABLE license_seat_reservations IS 'Licensed user seats reserved for the members of a group. Reservations are only used to forecast when the seats run out.';
+
 CREATE TABLE licenses (
     id integer NOT NULL,
     uploaded_at timestamp with time zone NOT NULL,
@@ -1046,6 +1237,41 @@ CREATE SEQUENCE licenses_id_seq
 
 ALTER SEQUENCE licenses_id_seq OWNED BY licenses.id;
 
+CREATE TABLE network_connection_quality (
+    client_type text NOT NULL,
+    node_id_self bigint NOT NULL,
+    node_id_remote bigint NOT NULL,
+    connection_type text NOT NULL,
+    latency_ms double precision,
+    handshake_retries bigint DEFAULT 0 NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE network_connection_quality IS 'Latest network telemetry of every workspace connection, reported by clients and agents to any replica.';
+
+COMMENT ON COLUMN network_connection_quality.node_id_self IS 'Tailnet node ID of the reporting side. Node IDs are unsigned 64-bit integers stored bit for bit.';
+
+COMMENT ON COLUMN network_connection_quality.handshake_retries IS 'Number of WireGuard handshakes the reporting side had to retry over the lifetime of the connection.';
+
+CREATE TABLE network_connection_quality_counters (
+    client_type text NOT NULL,
+    relay_fallbacks bigint DEFAULT 0 NOT NULL,
+    handshake_retries bigint DEFAULT 0 NOT NULL
+);
+
+COMMENT ON TABLE network_connection_quality_counters IS 'Deployment-wide totals of connections falling back from P2P to a DERP relay and of WireGuard handshake retries, by client type.';
+
+CREATE TABLE notification_category_preferences (
+    user_id uuid NOT NULL,
+    category notification_category NOT NULL,
+    method notification_method NOT NULL,
+    disabled boolean DEFAULT false NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+COMMENT ON TABLE notification_category_preferences IS 'Per-user toggles for delivering the notifications of a category on a notification method.';
+
 CREATE TABLE notification_messages (
     id uuid NOT NULL,
     notification_template_id uuid NOT NULL,
@@ -1062,11 +1288,17 @@ CREATE TABLE notification_messages (
     leased_until timestamp with time zone,
     next_retry_after timestamp with time zone,
     queued_seconds double precision,
-    dedupe_hash text
+    dedupe_hash text,
+    deliver_after timestamp with time zone,
+    digest boolean DEFAULT false NOT NULL
 );
 
 COMMENT ON COLUMN notification_messages.dedupe_hash IS 'Auto-generated by insert/update trigger, used to prevent duplicate notifications from being enqueued on the same day';
 
+COMMENT ON COLUMN notification_messages.deliver_after IS 'The message will not be dispatched before this time, used to hold messages back until the end of the user''s quiet hours.';
+
+COMMENT ON COLUMN notification_messages.digest IS 'The message is batched into the user''s next notification digest instead of being dispatched on its own.';
+
 CREATE TABLE notification_preferences (
     user_id uuid NOT NULL,
     notification_template_id uuid NOT NULL,
@@ -1091,13 +1323,19 @@ CREATE TABLE notification_templates (
     "group" text,
     method notification_method,
     kind notification_template_kind DEFAULT 'system'::notification_template_kind NOT NULL,
-    enabled_by_default boolean DEFAULT true NOT NULL
+    enabled_by_default boolean DEFAULT true NOT NULL,
+    priority notification_priority DEFAULT 'normal'::notification_priority NOT NULL,
+    category notification_category
 );
 
 COMMENT ON TABLE notification_templates IS 'Templates from which to create notification messages.';
 
 COMMENT ON COLUMN notification_templates.method IS 'NULL defers to the deployment-level method';
 
+COMMENT ON COLUMN notification_templates.priority IS 'Low priority notifications can be batched into digests, high priority notifications are delivered during quiet hours.';
+
+COMMENT ON COLUMN notification_templates.category IS 'Users can disable all notifications of a category, or only their delivery on some methods. NULL templates can only be disabled individually.';
+
 CREATE TABLE oauth2_provider_app_codes (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -1119,6 +1357,29 @@ COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge IS 'PKCE code challen
 
 COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge_method IS 'PKCE challenge method (S256)';
 
+CREATE TABLE oauth2_provider_app_device_codes (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    device_code_prefix bytea NOT NULL,
+    device_code_hash bytea NOT NULL,
+    user_code text NOT NULL,
+    app_id uuid NOT NULL,
+    user_id uuid,
+    resource_uri text,
+    last_polled_at timestamp with time zone
+);
+
+COMMENT ON TABLE oauth2_provider_app_device_codes IS 'RFC 8628 device codes. Devices poll the token endpoint with the device code until a user approves the user code.';
+
+COMMENT ON COLUMN oauth2_provider_app_device_codes.user_code IS 'The short code users enter to approve the device, without separators.';
+
+COMMENT ON COLUMN oauth2_provider_app_device_codes.user_id IS 'The user who approved the device, null while pending.';
+
+COMMENT ON COLUMN oauth2_provider_app_device_codes.resource_uri IS 'RFC 8707 resource parameter for audience restriction';
+
+COMMENT ON COLUMN oauth2_provider_app_device_codes.last_polled_at IS 'Used to tell devices polling too often to slow down.';
+
 CREATE TABLE oauth2_provider_app_secrets (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -1220,6 +1481,34 @@ COMMENT ON COLUMN oauth2_provider_apps.registration_access_token IS 'RFC 7592: H
 
 COMMENT ON COLUMN oauth2_provider_apps.registration_client_uri IS 'RFC 7592: URI for client configuration endpoint';
 
+CREATE TABLE organization_setting_overrides (
+    organization_id uuid NOT NULL,
+    default_ttl bigint,
+    default_quiet_hours_schedule text,
+    allowed_external_auth_providers text[],
+    disabled_notification_template_ids uuid[] DEFAULT '{}'::uuid[] NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    provisioner_max_jobs integer,
+    provisioner_reserved_jobs integer,
+    CONSTRAINT organization_setting_overrides_default_ttl_check CHECK ((default_ttl >= 0)),
+    CONSTRAINT organization_setting_overrides_provisioner_max_jobs_check CHECK ((provisioner_max_jobs >= 0)),
+    CONSTRAINT organization_setting_overrides_provisioner_reserved_jobs_check CHECK ((provisioner_reserved_jobs >= 0))
+);
+
+COMMENT ON TABLE organization_setting_overrides IS 'Overrides of deployment settings for a single organization. NULL columns inherit the deployment setting.';
+
+COMMENT ON COLUMN organization_setting_overrides.default_ttl IS 'Default autostop in nanoseconds for templates created in the organization without one.';
+
+COMMENT ON COLUMN organization_setting_overrides.default_quiet_hours_schedule IS 'Quiet hours schedule used for workspaces in the organization whose owner has not set their own schedule.';
+
+COMMENT ON COLUMN organization_setting_overrides.allowed_external_auth_providers IS 'IDs of the external auth providers templates in the organization may require.';
+
+COMMENT ON COLUMN organization_setting_overrides.disabled_notification_template_ids IS 'Notification templates that are never sent for workspaces and templates in the organization.';
+
+COMMENT ON COLUMN organization_setting_overrides.provisioner_max_jobs IS 'Maximum number of provisioner jobs of the organization that may run concurrently. 0 means unlimited.';
+
+COMMENT ON COLUMN organization_setting_overrides.provisioner_reserved_jobs IS 'Number of concurrent provisioner jobs reserved for the organization out of the shared capacity of the deployment.';
+
 CREATE TABLE organizations (
     id uuid NOT NULL,
     name text NOT NULL,
@@ -1299,6 +1588,16 @@ CREATE SEQUENCE provisioner_job_logs_id_seq
 
 ALTER SEQUENCE provisioner_job_logs_id_seq OWNED BY provisioner_job_logs.id;
 
+CREATE TABLE provisioner_job_requeues (
+    job_id uuid NOT NULL,
+    worker_id uuid,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE provisioner_job_requeues IS 'Records each time a running provisioner job was returned to the queue because its provisioner daemon stopped sending heartbeats.';
+
+COMMENT ON COLUMN provisioner_job_requeues.worker_id IS 'The ID of the provisioner daemon that was running the job when it was requeued.';
+
 CREATE VIEW provisioner_job_stats AS
 SELECT
     NULL::uuid AS job_id,
@@ -1389,6 +1688,34 @@ CREATE TABLE replicas (
     "primary" boolean DEFAULT true NOT NULL
 );
 
+CREATE TABLE review_workspaces (
+    workspace_id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    repository text NOT NULL,
+    branch text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE review_workspaces IS 'Workspaces created by CI for a branch of a repository. They are deleted when the branch is deleted.';
+
+COMMENT ON COLUMN review_workspaces.repository IS 'Lowercased full name of the repository, e.g. "coder/coder".';
+
+CREATE TABLE scim_group_members (
+    group_id uuid NOT NULL,
+    user_id uuid NOT NULL
+);
+
+COMMENT ON TABLE scim_group_members IS 'Users the identity provider assigned to a SCIM group. Group members are derived from this, and from scim_nested_groups when nested groups are flattened.';
+
+CREATE TABLE scim_nested_groups (
+    group_id uuid NOT NULL,
+    member_group_id uuid NOT NULL,
+    CONSTRAINT scim_nested_groups_not_self CHECK ((group_id <> member_group_id))
+);
+
+COMMENT ON TABLE scim_nested_groups IS 'Groups the identity provider assigned as members of a SCIM group.';
+
 CREATE TABLE site_configs (
     key character varying(256) NOT NULL,
     value text NOT NULL
@@ -1444,6 +1771,105 @@ CREATE TABLE telemetry_items (
     updated_at timestamp with time zone DEFAULT now() NOT NULL
 );
 
+CREATE TABLE template_activation_reviewers (
+    template_id uuid NOT NULL,
+    user_id uuid NOT NULL
+);
+
+COMMENT ON TABLE template_activation_reviewers IS 'Users designated to review the activation requests of template versions. When a template has none, users that can update the template review them.';
+
+CREATE TABLE template_app_restrictions (
+    template_id uuid NOT NULL,
+    app_slug text NOT NULL,
+    group_ids uuid[] DEFAULT '{}'::uuid[] NOT NULL,
+    roles text[] DEFAULT '{}'::text[] NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_app_restrictions IS 'Workspace apps of a template that are only visible and accessible to members of some groups or users with some roles.';
+
+COMMENT ON COLUMN template_app_restrictions.group_ids IS 'The groups whose members can access the app.';
+
+COMMENT ON COLUMN template_app_restrictions.roles IS 'The names of the site roles and roles of the template organization whose users can access the app.';
+
+CREATE TABLE template_app_session_policies (
+    template_id uuid NOT NULL,
+    app_slug text NOT NULL,
+    max_session_duration bigint NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    CONSTRAINT template_app_session_policies_max_session_duration_check CHECK ((max_session_duration > 0))
+);
+
+COMMENT ON TABLE template_app_session_policies IS 'How long after authenticating users can access the workspace apps of a template before they must authenticate again.';
+
+COMMENT ON COLUMN template_app_session_policies.app_slug IS 'The slug of the app the policy applies to. The policy with an empty slug applies to all apps, ports and terminals of the template without their own policy.';
+
+COMMENT ON COLUMN template_app_session_policies.max_session_duration IS 'The maximum time since the user authenticated, in nanoseconds.';
+
+CREATE TABLE template_bundles (
+    id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    name text NOT NULL,
+    version text NOT NULL,
+    hash character varying(64) NOT NULL,
+    manifest jsonb NOT NULL,
+    data bytea NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_bundles IS 'Self-contained template archives, including the modules and providers they require, that can be pushed to and pulled from a deployment without internet access.';
+
+COMMENT ON COLUMN template_bundles.manifest IS 'The manifest of the bundle, describing the providers and modules it contains.';
+
+CREATE TABLE template_egress_policies (
+    template_id uuid NOT NULL,
+    allowed_cidrs text[] DEFAULT '{}'::text[] NOT NULL,
+    allowed_domains text[] DEFAULT '{}'::text[] NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_egress_policies IS 'Destinations the workspaces of a template may connect to. Agents block all other outbound traffic on the workspace host while a template has a policy.';
+
+COMMENT ON COLUMN template_egress_policies.allowed_cidrs IS 'IP addresses and CIDR ranges workspaces may connect to.';
+
+COMMENT ON COLUMN template_egress_policies.allowed_domains IS 'Domain names workspaces may connect to. Agents resolve them periodically and allow the resulting addresses.';
+
+CREATE TABLE template_parameter_option_sources (
+    template_id uuid NOT NULL,
+    parameter_name text NOT NULL,
+    url text NOT NULL,
+    auth_header_name text DEFAULT ''::text NOT NULL,
+    auth_header_value text DEFAULT ''::text NOT NULL,
+    cache_ttl bigint NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_parameter_option_sources IS 'APIs from which the options of template parameters are fetched when the parameter form is rendered, instead of being defined in the template.';
+
+COMMENT ON COLUMN template_parameter_option_sources.auth_header_value IS 'The value of the header that coderd adds to requests to the API. It is never returned to users.';
+
+COMMENT ON COLUMN template_parameter_option_sources.cache_ttl IS 'How long fetched options are cached, in nanoseconds.';
+
+CREATE TABLE template_secrets (
+    id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    name text NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    value text NOT NULL,
+    value_key_id text,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_secrets IS 'Secrets of a template. They are passed to workspace builds as the value of the Terraform variable with the same name.';
+
+COMMENT ON COLUMN template_secrets.value_key_id IS 'The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted';
+
 CREATE TABLE template_usage_stats (
     start_time timestamp with time zone NOT NULL,
     end_time timestamp with time zone NOT NULL,
@@ -1485,6 +1911,38 @@ COMMENT ON COLUMN template_usage_stats.jetbrains_mins IS 'Total minutes the user
 
 COMMENT ON COLUMN template_usage_stats.app_usage_mins IS 'Object with app names as keys and total minutes used as values. Null means no app usage was recorded.';
 
+CREATE TABLE template_variable_sets (
+    template_id uuid NOT NULL,
+    variable_set_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_variable_sets IS 'Variable sets attached to a template. Their values are passed to the provisioner jobs of the template.';
+
+CREATE TABLE template_version_activation_requests (
+    template_version_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    requested_by uuid NOT NULL,
+    status template_version_activation_request_status DEFAULT 'pending'::template_version_activation_request_status NOT NULL,
+    approvals_required integer NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_version_activation_requests IS 'Requests to make a template version active, for templates which require approvals before activating a version.';
+
+COMMENT ON COLUMN template_version_activation_requests.approvals_required IS 'The number of approvals the template required when the activation was requested.';
+
+CREATE TABLE template_version_activation_reviews (
+    template_version_id uuid NOT NULL,
+    reviewer_id uuid NOT NULL,
+    approved boolean NOT NULL,
+    comment text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_version_activation_reviews IS 'Approvals and rejections of template version activation requests.';
+
 CREATE TABLE template_version_parameters (
     template_version_id uuid NOT NULL,
     name text NOT NULL,
@@ -1567,6 +2025,32 @@ CREATE TABLE template_version_presets (
     is_default boolean DEFAULT false NOT NULL
 );
 
+CREATE TABLE template_version_rollouts (
+    template_id uuid NOT NULL,
+    template_version_id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    percentage integer NOT NULL,
+    group_ids uuid[] DEFAULT '{}'::uuid[] NOT NULL,
+    failure_threshold_percent integer NOT NULL,
+    minimum_builds integer NOT NULL,
+    status template_version_rollout_status DEFAULT 'in_progress'::template_version_rollout_status NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    CONSTRAINT template_version_rollouts_failure_threshold_percent_check CHECK (((failure_threshold_percent >= 0) AND (failure_threshold_percent <= 100))),
+    CONSTRAINT template_version_rollouts_minimum_builds_check CHECK ((minimum_builds > 0)),
+    CONSTRAINT template_version_rollouts_percentage_check CHECK (((percentage >= 0) AND (percentage <= 100)))
+);
+
+COMMENT ON TABLE template_version_rollouts IS 'Gradual rollouts of template versions to a cohort of workspaces before the version is made active for every workspace. A template has at most one rollout, starting a new one replaces the previous one.';
+
+COMMENT ON COLUMN template_version_rollouts.percentage IS 'The percentage of the workspaces of the template that are built with the rolled out version.';
+
+COMMENT ON COLUMN template_version_rollouts.group_ids IS 'The groups whose members'' workspaces are built with the rolled out version, regardless of the percentage.';
+
+COMMENT ON COLUMN template_version_rollouts.failure_threshold_percent IS 'The rollout is halted once the percentage of failed builds of the rolled out version exceeds this threshold.';
+
+COMMENT ON COLUMN template_version_rollouts.minimum_builds IS 'The number of builds of the rolled out version required before the failure threshold is evaluated.';
+
 CREATE TABLE template_version_terraform_values (
     template_version_id uuid NOT NULL,
     updated_at timestamp with time zone DEFAULT now() NOT NULL,
@@ -1690,7 +2174,16 @@ CREATE TABLE templates (
     deprecated text DEFAULT ''::text NOT NULL,
     activity_bump bigint DEFAULT '3600000000000'::bigint NOT NULL,
     max_port_sharing_level app_sharing_level DEFAULT 'owner'::app_sharing_level NOT NULL,
-    use_classic_parameter_flow boolean DEFAULT true NOT NULL
+    use_classic_parameter_flow boolean DEFAULT true NOT NULL,
+    wireguard_mtu integer DEFAULT 0 NOT NULL,
+    wireguard_keepalive_interval bigint DEFAULT 0 NOT NULL,
+    wireguard_handshake_timeout bigint DEFAULT 0 NOT NULL,
+    activation_approvals_required integer DEFAULT 0 NOT NULL,
+    autostart_holidays text[] DEFAULT '{}'::text[] NOT NULL,
+    autostart_holiday_calendar_url text DEFAULT ''::text NOT NULL,
+    dormant_archive_paths text[] DEFAULT '{}'::text[] NOT NULL,
+    parameter_validation_url text DEFAULT ''::text NOT NULL,
+    allow_start_on_connect boolean DEFAULT true NOT NULL
 );
 
 COMMENT ON COLUMN templates.default_ttl IS 'The default duration for autostop for workspaces created from this template.';
@@ -1713,6 +2206,24 @@ COMMENT ON COLUMN templates.deprecated IS 'If set to a non empty string, the tem
 
 COMMENT ON COLUMN templates.use_classic_parameter_flow IS 'Determines whether to default to the dynamic parameter creation flow for this template or continue using the legacy classic parameter creation flow.This is a template wide setting, the template admin can revert to the classic flow if there are any issues. An escape hatch is required, as workspace creation is a core workflow and cannot break. This column will be removed when the dynamic parameter creation flow is stable.';
 
+COMMENT ON COLUMN templates.wireguard_mtu IS 'Overrides the deployment WireGuard MTU for workspaces created from this template. 0 uses the deployment value.';
+
+COMMENT ON COLUMN templates.wireguard_keepalive_interval IS 'Overrides the deployment WireGuard keepalive interval, in nanoseconds. 0 uses the deployment value.';
+
+COMMENT ON COLUMN templates.wireguard_handshake_timeout IS 'Overrides the deployment WireGuard handshake timeout, in nanoseconds. 0 uses the deployment value.';
+
+COMMENT ON COLUMN templates.activation_approvals_required IS 'The number of reviewer approvals required before a template version can be made active. 0 disables the approval gate.';
+
+COMMENT ON COLUMN templates.autostart_holidays IS 'Dates formatted as YYYY-MM-DD on which workspaces of this template are not autostarted.';
+
+COMMENT ON COLUMN templates.autostart_holiday_calendar_url IS 'URL of an iCalendar feed whose events are treated as autostart holidays. Empty disables the calendar.';
+
+COMMENT ON COLUMN templates.dormant_archive_paths IS 'Paths in workspaces of this template that are archived to object storage before dormant workspaces are auto-deleted. Relative paths are relative to the home directory of the agent.';
+
+COMMENT ON COLUMN templates.parameter_validation_url IS 'URL of a webhook that validates the parameter values of workspaces created or updated from this template. Empty disables the webhook.';
+
+COMMENT ON COLUMN templates.allow_start_on_connect IS 'Allow clients such as coder ssh and IDE extensions to start stopped workspaces of this template when connecting to them.';
+
 CREATE VIEW template_with_names AS
  SELECT templates.id,
     templates.created_at,
@@ -1743,6 +2254,15 @@ CREATE VIEW template_with_names AS
     templates.activity_bump,
     templates.max_port_sharing_level,
     templates.use_classic_parameter_flow,
+    templates.wireguard_mtu,
+    templates.wireguard_keepalive_interval,
+    templates.wireguard_handshake_timeout,
+    templates.activation_approvals_required,
+    templates.autostart_holidays,
+    templates.autostart_holiday_calendar_url,
+    templates.dormant_archive_paths,
+    templates.parameter_validation_url,
+    templates.allow_start_on_connect,
     COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS created_by_username,
     COALESCE(visible_users.name, ''::text) AS created_by_name,
@@ -1755,6 +2275,14 @@ CREATE VIEW template_with_names AS
 
 COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
 
+CREATE TABLE user_cli_versions (
+    user_id uuid NOT NULL,
+    version text NOT NULL,
+    last_seen_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_cli_versions IS 'The version of the CLI each user last made an API request with.';
+
 CREATE TABLE user_configs (
     user_id uuid NOT NULL,
     key character varying(256) NOT NULL,
@@ -1787,6 +2315,33 @@ COMMENT ON COLUMN user_links.oauth_refresh_token_key_id IS 'The ID of the key us
 
 COMMENT ON COLUMN user_links.claims IS 'Claims from the IDP for the linked user. Includes both id_token and userinfo claims. ';
 
+CREATE TABLE user_mfa_recovery_codes (
+    user_id uuid NOT NULL,
+    hashed_code bytea NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    used_at timestamp with time zone
+);
+
+COMMENT ON TABLE user_mfa_recovery_codes IS 'Single use codes that replace a WebAuthn credential when completing a password login.';
+
+CREATE TABLE user_secrets (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    name text NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    value text NOT NULL,
+    value_key_id text,
+    env_name text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_secrets IS 'Secrets owned by a user. Secrets with an environment variable name can be read by the agents of the user''s workspaces.';
+
+COMMENT ON COLUMN user_secrets.value_key_id IS 'The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted';
+
+COMMENT ON COLUMN user_secrets.env_name IS 'The name of the environment variable the secret is exposed as in workspaces. Empty if it is not exposed.';
+
 CREATE TABLE user_status_changes (
     id uuid DEFAULT gen_random_uuid() NOT NULL,
     user_id uuid NOT NULL,
@@ -1796,6 +2351,50 @@ CREATE TABLE user_status_changes (
 
 COMMENT ON TABLE user_status_changes IS 'Tracks the history of user status changes';
 
+CREATE TABLE user_webauthn_credentials (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    name text NOT NULL,
+    credential_id bytea NOT NULL,
+    public_key bytea NOT NULL,
+    sign_count bigint DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    last_used_at timestamp with time zone
+);
+
+COMMENT ON TABLE user_webauthn_credentials IS 'WebAuthn credentials, such as security keys and passkeys, used as a second factor for password logins.';
+
+COMMENT ON COLUMN user_webauthn_credentials.public_key IS 'The COSE encoded public key of the credential.';
+
+COMMENT ON COLUMN user_webauthn_credentials.sign_count IS 'The last signature counter reported by the authenticator, used to detect cloned authenticators.';
+
+CREATE TABLE variable_sets (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    variables text NOT NULL,
+    variables_key_id text,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE variable_sets IS 'Named sets of Terraform variable values that can be attached to templates.';
+
+COMMENT ON COLUMN variable_sets.variables IS 'A JSON array of the variables in the set, including their values.';
+
+COMMENT ON COLUMN variable_sets.variables_key_id IS 'The ID of the key used to encrypt the variables. If this is NULL, the variables are not encrypted';
+
+CREATE TABLE webauthn_challenges (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    purpose webauthn_challenge_purpose NOT NULL,
+    challenge bytea NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE webauthn_challenges IS 'Pending WebAuthn ceremonies. Challenges are deleted when they are used.';
+
 CREATE TABLE webpush_subscriptions (
     id uuid DEFAULT gen_random_uuid() NOT NULL,
     user_id uuid NOT NULL,
@@ -1805,6 +2404,25 @@ CREATE TABLE webpush_subscriptions (
     endpoint_auth_key text NOT NULL
 );
 
+CREATE TABLE workspace_adoptions (
+    id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    owner_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    workspace_name text NOT NULL,
+    hashed_secret bytea NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    workspace_id uuid,
+    hostname text DEFAULT ''::text NOT NULL,
+    adopted_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_adoptions IS 'Machines that are not managed by a provisioner and were adopted as workspaces. A row is created with a one-time token that the machine exchanges for a workspace and an agent token.';
+
+COMMENT ON COLUMN workspace_adoptions.workspace_id IS 'The workspace created when the machine was adopted. NULL until the token is used.';
+
 CREATE TABLE workspace_agent_devcontainers (
     id uuid NOT NULL,
     workspace_agent_id uuid NOT NULL,
@@ -1828,6 +2446,15 @@ COMMENT ON COLUMN workspace_agent_devcontainers.config_path IS 'Path to devconta
 
 COMMENT ON COLUMN workspace_agent_devcontainers.name IS 'The name of the Dev Container.';
 
+CREATE TABLE workspace_agent_lifecycle_events (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    workspace_agent_id uuid NOT NULL,
+    lifecycle_state workspace_agent_lifecycle_state NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_lifecycle_events IS 'Tracks the history of workspace agent lifecycle state transitions';
+
 CREATE TABLE workspace_agent_log_sources (
     workspace_agent_id uuid NOT NULL,
     id uuid NOT NULL,
@@ -1932,6 +2559,15 @@ CREATE TABLE workspace_agent_stats (
     usage boolean DEFAULT false NOT NULL
 );
 
+CREATE TABLE workspace_agent_token_rotations (
+    agent_id uuid NOT NULL,
+    previous_auth_token uuid NOT NULL,
+    previous_auth_token_expires_at timestamp with time zone NOT NULL,
+    rotated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_token_rotations IS 'The last auth token rotation of each workspace agent. The previous token keeps authenticating the agent until it expires.';
+
 CREATE TABLE workspace_agent_volume_resource_monitors (
     agent_id uuid NOT NULL,
     enabled boolean NOT NULL,
@@ -1943,6 +2579,16 @@ CREATE TABLE workspace_agent_volume_resource_monitors (
     debounced_until timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
 );
 
+CREATE TABLE workspace_agent_volume_usage (
+    agent_id uuid NOT NULL,
+    path text NOT NULL,
+    total_bytes bigint NOT NULL,
+    used_bytes bigint NOT NULL,
+    collected_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_volume_usage IS 'The most recent filesystem usage reported by workspace agents for their home directory and monitored volumes.';
+
 CREATE TABLE workspace_agents (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -2120,6 +2766,29 @@ CREATE TABLE workspace_apps (
 
 COMMENT ON COLUMN workspace_apps.display_order IS 'Specifies the order in which to display agent app in user interfaces.';
 
+CREATE TABLE workspace_archives (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    workspace_name text NOT NULL,
+    owner_id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    paths text[] NOT NULL,
+    object_key text NOT NULL,
+    size_bytes bigint NOT NULL,
+    error text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_archives IS 'Archives of the persistent paths of dormant workspaces taken before the workspaces are auto-deleted.';
+
+COMMENT ON COLUMN workspace_archives.object_key IS 'Key of the archive in the archive store. Empty if the archive failed.';
+
+COMMENT ON COLUMN workspace_archives.error IS 'Why the archive failed. Empty if the archive succeeded.';
+
+COMMENT ON COLUMN workspace_archives.expires_at IS 'The archive is deleted from the archive store after this time.';
+
 COMMENT ON COLUMN workspace_apps.hidden IS 'Determines if the app is not shown in user interfaces.';
 
 CREATE TABLE workspace_build_parameters (
@@ -2132,6 +2801,14 @@ COMMENT ON COLUMN workspace_build_parameters.name IS 'Parameter name';
 
 COMMENT ON COLUMN workspace_build_parameters.value IS 'Parameter value';
 
+CREATE TABLE workspace_build_quota_costs (
+    workspace_build_id uuid NOT NULL,
+    dimension text NOT NULL,
+    cost bigint NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_quota_costs IS 'Costs of a workspace build in quota dimensions other than the daily cost.';
+
 CREATE TABLE workspace_builds (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -2150,9 +2827,18 @@ CREATE TABLE workspace_builds (
     template_version_preset_id uuid,
     has_ai_task boolean,
     ai_task_sidebar_app_id uuid,
+    reason_message text DEFAULT ''::text NOT NULL,
+    initiator_api_key_name text DEFAULT ''::text NOT NULL,
+    initiator_ci_job_url text DEFAULT ''::text NOT NULL,
     CONSTRAINT workspace_builds_ai_task_sidebar_app_id_required CHECK (((((has_ai_task IS NULL) OR (has_ai_task = false)) AND (ai_task_sidebar_app_id IS NULL)) OR ((has_ai_task = true) AND (ai_task_sidebar_app_id IS NOT NULL))))
 );
 
+COMMENT ON COLUMN workspace_builds.reason_message IS 'Free-form explanation of why the build was started, given by the initiator.';
+
+COMMENT ON COLUMN workspace_builds.initiator_api_key_name IS 'The name of the API token the build was started with, if any.';
+
+COMMENT ON COLUMN workspace_builds.initiator_ci_job_url IS 'The URL of the CI job that started the build, if any.';
+
 CREATE VIEW workspace_build_with_user AS
  SELECT workspace_builds.id,
     workspace_builds.created_at,
@@ -2171,6 +2857,9 @@ CREATE VIEW workspace_build_with_user AS
     workspace_builds.template_version_preset_id,
     workspace_builds.has_ai_task,
     workspace_builds.ai_task_sidebar_app_id,
+    workspace_builds.reason_message,
+    workspace_builds.initiator_api_key_name,
+    workspace_builds.initiator_ci_job_url,
     COALESCE(visible_users.avatar_url, ''::text) AS initiator_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS initiator_by_username,
     COALESCE(visible_users.name, ''::text) AS initiator_by_name
@@ -2179,6 +2868,14 @@ CREATE VIEW workspace_build_with_user AS
 
 COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
 
+CREATE TABLE workspace_labels (
+    workspace_id uuid NOT NULL,
+    key text NOT NULL,
+    value text NOT NULL
+);
+
+COMMENT ON TABLE workspace_labels IS 'Free-form key/value labels used to group workspaces, e.g. by project or cost center, and to target them in searches and bulk operations.';
+
 CREATE TABLE workspaces (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -2195,11 +2892,14 @@ CREATE TABLE workspaces (
     deleting_at timestamp with time zone,
     automatic_updates automatic_updates DEFAULT 'never'::automatic_updates NOT NULL,
     favorite boolean DEFAULT false NOT NULL,
-    next_start_at timestamp with time zone
+    next_start_at timestamp with time zone,
+    ephemeral boolean DEFAULT false NOT NULL
 );
 
 COMMENT ON COLUMN workspaces.favorite IS 'Favorite is true if the workspace owner has favorited the workspace.';
 
+COMMENT ON COLUMN workspaces.ephemeral IS 'Ephemeral workspaces are deleted instead of stopped. Their quota is only consumed while they are running.';
+
 CREATE VIEW workspace_latest_builds AS
  SELECT latest_build.id,
     latest_build.workspace_id,
@@ -2236,6 +2936,28 @@ CREATE TABLE workspace_modules (
     created_at timestamp with time zone NOT NULL
 );
 
+CREATE TABLE workspace_port_share_links (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    agent_name text NOT NULL,
+    port integer NOT NULL,
+    protocol port_share_protocol DEFAULT 'http'::port_share_protocol NOT NULL,
+    hashed_secret bytea NOT NULL,
+    hashed_password text DEFAULT ''::text NOT NULL,
+    bandwidth_limit_bytes bigint DEFAULT 0 NOT NULL,
+    bytes_transferred bigint DEFAULT 0 NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    CONSTRAINT workspace_port_share_links_bandwidth_limit_bytes_check CHECK ((bandwidth_limit_bytes >= 0))
+);
+
+COMMENT ON TABLE workspace_port_share_links IS 'Time-limited links that give anyone access to a workspace port, optionally protected by a password.';
+
+COMMENT ON COLUMN workspace_port_share_links.hashed_password IS 'The hashed password of the link, or empty if the link does not require a password.';
+
+COMMENT ON COLUMN workspace_port_share_links.bandwidth_limit_bytes IS 'The number of bytes that can be transferred through the link, or 0 if it is not limited.';
+
 CREATE VIEW workspace_prebuild_builds AS
  SELECT workspace_builds.id,
     workspace_builds.workspace_id,
@@ -2340,6 +3062,15 @@ CREATE SEQUENCE workspace_proxies_region_id_seq
 
 ALTER SEQUENCE workspace_proxies_region_id_seq OWNED BY workspace_proxies.region_id;
 
+CREATE TABLE workspace_proxy_token_rotations (
+    proxy_id uuid NOT NULL,
+    previous_token_hashed_secret bytea NOT NULL,
+    previous_token_expires_at timestamp with time zone NOT NULL,
+    rotated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_proxy_token_rotations IS 'The last token rotation of each workspace proxy. The previous token keeps authenticating the proxy until it expires.';
+
 CREATE TABLE workspace_resource_metadata (
     workspace_resource_id uuid NOT NULL,
     key character varying(1024) NOT NULL,
@@ -2357,6 +3088,17 @@ CREATE SEQUENCE workspace_resource_metadata_id_seq
 
 ALTER SEQUENCE workspace_resource_metadata_id_seq OWNED BY workspace_resource_metadata.id;
 
+CREATE TABLE workspace_schedule_pauses (
+    workspace_id uuid NOT NULL,
+    paused_until timestamp with time zone NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    created_by uuid NOT NULL
+);
+
+COMMENT ON TABLE workspace_schedule_pauses IS 'Bounded pauses of the autostop, dormancy and auto-delete schedules of workspaces, e.g. while their owners are on leave.';
+
+COMMENT ON COLUMN workspace_schedule_pauses.paused_until IS 'The pause ends at this time. Expired pauses are kept so the end of the pause can count as workspace activity for dormancy.';
+
 CREATE VIEW workspaces_expanded AS
  SELECT workspaces.id,
     workspaces.created_at,
@@ -2374,6 +3116,7 @@ CREATE VIEW workspaces_expanded AS
     workspaces.automatic_updates,
     workspaces.favorite,
     workspaces.next_start_at,
+    workspaces.ephemeral,
     visible_users.avatar_url AS owner_avatar_url,
     visible_users.username AS owner_username,
     visible_users.name AS owner_name,
@@ -2407,9 +3150,15 @@ ALTER TABLE ONLY workspace_resource_metadata ALTER COLUMN id SET DEFAULT nextval
 ALTER TABLE ONLY workspace_agent_stats
     ADD CONSTRAINT agent_stats_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY ai_task_queue
+    ADD CONSTRAINT ai_task_queue_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY audit_log_legal_holds
+    ADD CONSTRAINT audit_log_legal_holds_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY audit_logs
     ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
 
@@ -2443,24 +3192,48 @@ ALTER TABLE ONLY gitsshkeys
 ALTER TABLE ONLY group_members
     ADD CONSTRAINT group_members_user_id_group_id_key UNIQUE (user_id, group_id);
 
+ALTER TABLE ONLY group_quota_allowances
+    ADD CONSTRAINT group_quota_allowances_pkey PRIMARY KEY (group_id, dimension);
+
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_name_organization_id_key UNIQUE (name, organization_id);
 
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY health_scores
+    ADD CONSTRAINT health_scores_pkey PRIMARY KEY (replica_id, created_at);
+
 ALTER TABLE ONLY inbox_notifications
     ADD CONSTRAINT inbox_notifications_pkey PRIMARY KEY (id);
 
 ALTER TABLE ONLY jfrog_xray_scans
     ADD CONSTRAINT jfrog_xray_scans_pkey PRIMARY KEY (agent_id, workspace_id);
 
+ALTER TABLE ONLY ldap_users
+    ADD CONSTRAINT ldap_users_external_id_key UNIQUE (external_id);
+
+ALTER TABLE ONLY ldap_users
+    ADD CONSTRAINT ldap_users_pkey PRIMARY KEY (user_id);
+
+ALTER TABLE ONLY license_seat_reservations
+    ADD CONSTRAINT license_seat_reservations_pkey PRIMARY KEY (group_id);
+
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_jwt_key UNIQUE (jwt);
 
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY network_connection_quality_counters
+    ADD CONSTRAINT network_connection_quality_counters_pkey PRIMARY KEY (client_type);
+
+ALTER TABLE ONLY network_connection_quality
+    ADD CONSTRAINT network_connection_quality_pkey PRIMARY KEY (client_type, node_id_self, node_id_remote);
+
+ALTER TABLE ONLY notification_category_preferences
+    ADD CONSTRAINT notification_category_preferences_pkey PRIMARY KEY (user_id, category, method);
+
 ALTER TABLE ONLY notification_messages
     ADD CONSTRAINT notification_messages_pkey PRIMARY KEY (id);
 
@@ -2482,6 +3255,15 @@ ALTER TABLE ONLY oauth2_provider_app_codes
 ALTER TABLE ONLY oauth2_provider_app_codes
     ADD CONSTRAINT oauth2_provider_app_codes_secret_prefix_key UNIQUE (secret_prefix);
 
+ALTER TABLE ONLY oauth2_provider_app_device_codes
+    ADD CONSTRAINT oauth2_provider_app_device_codes_device_code_prefix_key UNIQUE (device_code_prefix);
+
+ALTER TABLE ONLY oauth2_provider_app_device_codes
+    ADD CONSTRAINT oauth2_provider_app_device_codes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_app_device_codes
+    ADD CONSTRAINT oauth2_provider_app_device_codes_user_code_key UNIQUE (user_code);
+
 ALTER TABLE ONLY oauth2_provider_app_secrets
     ADD CONSTRAINT oauth2_provider_app_secrets_pkey PRIMARY KEY (id);
 
@@ -2497,9 +3279,18 @@ ALTER TABLE ONLY oauth2_provider_app_tokens
 ALTER TABLE ONLY oauth2_provider_apps
     ADD CONSTRAINT oauth2_provider_apps_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY organization_audit_settings
+    ADD CONSTRAINT organization_audit_settings_pkey PRIMARY KEY (organization_id);
+
+ALTER TABLE ONLY organization_mfa_settings
+    ADD CONSTRAINT organization_mfa_settings_pkey PRIMARY KEY (organization_id);
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id);
 
+ALTER TABLE ONLY organization_setting_overrides
+    ADD CONSTRAINT organization_setting_overrides_pkey PRIMARY KEY (organization_id);
+
 ALTER TABLE ONLY organizations
     ADD CONSTRAINT organizations_pkey PRIMARY KEY (id);
 
@@ -2527,6 +3318,18 @@ ALTER TABLE ONLY provisioner_jobs
 ALTER TABLE ONLY provisioner_keys
     ADD CONSTRAINT provisioner_keys_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY review_workspaces
+    ADD CONSTRAINT review_workspaces_organization_id_repository_branch_key UNIQUE (organization_id, repository, branch);
+
+ALTER TABLE ONLY review_workspaces
+    ADD CONSTRAINT review_workspaces_pkey PRIMARY KEY (workspace_id);
+
+ALTER TABLE ONLY scim_group_members
+    ADD CONSTRAINT scim_group_members_pkey PRIMARY KEY (group_id, user_id);
+
+ALTER TABLE ONLY scim_nested_groups
+    ADD CONSTRAINT scim_nested_groups_pkey PRIMARY KEY (group_id, member_group_id);
+
 ALTER TABLE ONLY site_configs
     ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 
@@ -2551,9 +3354,45 @@ ALTER TABLE ONLY tailnet_tunnels
 ALTER TABLE ONLY telemetry_items
     ADD CONSTRAINT telemetry_items_pkey PRIMARY KEY (key);
 
+ALTER TABLE ONLY template_activation_reviewers
+    ADD CONSTRAINT template_activation_reviewers_pkey PRIMARY KEY (template_id, user_id);
+
+ALTER TABLE ONLY template_app_restrictions
+    ADD CONSTRAINT template_app_restrictions_pkey PRIMARY KEY (template_id, app_slug);
+
+ALTER TABLE ONLY template_app_session_policies
+    ADD CONSTRAINT template_app_session_policies_pkey PRIMARY KEY (template_id, app_slug);
+
+ALTER TABLE ONLY template_bundles
+    ADD CONSTRAINT template_bundles_organization_id_name_version_key UNIQUE (organization_id, name, version);
+
+ALTER TABLE ONLY template_bundles
+    ADD CONSTRAINT template_bundles_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY template_egress_policies
+    ADD CONSTRAINT template_egress_policies_pkey PRIMARY KEY (template_id);
+
+ALTER TABLE ONLY template_parameter_option_sources
+    ADD CONSTRAINT template_parameter_option_sources_pkey PRIMARY KEY (template_id, parameter_name);
+
+ALTER TABLE ONLY template_secrets
+    ADD CONSTRAINT template_secrets_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY template_secrets
+    ADD CONSTRAINT template_secrets_template_id_name_key UNIQUE (template_id, name);
+
 ALTER TABLE ONLY template_usage_stats
     ADD CONSTRAINT template_usage_stats_pkey PRIMARY KEY (start_time, template_id, user_id);
 
+ALTER TABLE ONLY template_variable_sets
+    ADD CONSTRAINT template_variable_sets_pkey PRIMARY KEY (template_id, variable_set_id);
+
+ALTER TABLE ONLY template_version_activation_requests
+    ADD CONSTRAINT template_version_activation_requests_pkey PRIMARY KEY (template_version_id);
+
+ALTER TABLE ONLY template_version_activation_reviews
+    ADD CONSTRAINT template_version_activation_reviews_pkey PRIMARY KEY (template_version_id, reviewer_id);
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_name_key UNIQUE (template_version_id, name);
 
@@ -2566,6 +3405,9 @@ ALTER TABLE ONLY template_version_preset_prebuild_schedules
 ALTER TABLE ONLY template_version_presets
     ADD CONSTRAINT template_version_presets_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY template_version_rollouts
+    ADD CONSTRAINT template_version_rollouts_pkey PRIMARY KEY (template_id);
+
 ALTER TABLE ONLY template_version_terraform_values
     ADD CONSTRAINT template_version_terraform_values_template_version_id_key UNIQUE (template_version_id);
 
@@ -2584,6 +3426,9 @@ ALTER TABLE ONLY template_versions
 ALTER TABLE ONLY templates
     ADD CONSTRAINT templates_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY user_cli_versions
+    ADD CONSTRAINT user_cli_versions_pkey PRIMARY KEY (user_id);
+
 ALTER TABLE ONLY user_configs
     ADD CONSTRAINT user_configs_pkey PRIMARY KEY (user_id, key);
 
@@ -2593,18 +3438,48 @@ ALTER TABLE ONLY user_deleted
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type);
 
+ALTER TABLE ONLY user_mfa_recovery_codes
+    ADD CONSTRAINT user_mfa_recovery_codes_pkey PRIMARY KEY (user_id, hashed_code);
+
+ALTER TABLE ONLY user_secrets
+    ADD CONSTRAINT user_secrets_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY user_secrets
+    ADD CONSTRAINT user_secrets_user_id_name_key UNIQUE (user_id, name);
+
 ALTER TABLE ONLY user_status_changes
     ADD CONSTRAINT user_status_changes_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY user_webauthn_credentials
+    ADD CONSTRAINT user_webauthn_credentials_credential_id_key UNIQUE (credential_id);
+
+ALTER TABLE ONLY user_webauthn_credentials
+    ADD CONSTRAINT user_webauthn_credentials_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY users
     ADD CONSTRAINT users_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY variable_sets
+    ADD CONSTRAINT variable_sets_name_key UNIQUE (name);
+
+ALTER TABLE ONLY variable_sets
+    ADD CONSTRAINT variable_sets_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY webauthn_challenges
+    ADD CONSTRAINT webauthn_challenges_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY webpush_subscriptions
     ADD CONSTRAINT webpush_subscriptions_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_adoptions
+    ADD CONSTRAINT workspace_adoptions_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_agent_devcontainers
     ADD CONSTRAINT workspace_agent_devcontainers_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_agent_lifecycle_events
+    ADD CONSTRAINT workspace_agent_lifecycle_events_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_agent_log_sources
     ADD CONSTRAINT workspace_agent_log_sources_pkey PRIMARY KEY (workspace_agent_id, id);
 
@@ -2626,9 +3501,15 @@ ALTER TABLE ONLY workspace_agent_scripts
 ALTER TABLE ONLY workspace_agent_logs
     ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_agent_token_rotations
+    ADD CONSTRAINT workspace_agent_token_rotations_pkey PRIMARY KEY (agent_id);
+
 ALTER TABLE ONLY workspace_agent_volume_resource_monitors
     ADD CONSTRAINT workspace_agent_volume_resource_monitors_pkey PRIMARY KEY (agent_id, path);
 
+ALTER TABLE ONLY workspace_agent_volume_usage
+    ADD CONSTRAINT workspace_agent_volume_usage_pkey PRIMARY KEY (agent_id, path);
+
 ALTER TABLE ONLY workspace_agents
     ADD CONSTRAINT workspace_agents_pkey PRIMARY KEY (id);
 
@@ -2653,9 +3534,15 @@ ALTER TABLE ONLY workspace_apps
 ALTER TABLE ONLY workspace_apps
     ADD CONSTRAINT workspace_apps_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_archives
+    ADD CONSTRAINT workspace_archives_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_build_parameters
     ADD CONSTRAINT workspace_build_parameters_workspace_build_id_name_key UNIQUE (workspace_build_id, name);
 
+ALTER TABLE ONLY workspace_build_quota_costs
+    ADD CONSTRAINT workspace_build_quota_costs_pkey PRIMARY KEY (workspace_build_id, dimension);
+
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_job_id_key UNIQUE (job_id);
 
@@ -2665,12 +3552,21 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
 
+ALTER TABLE ONLY workspace_labels
+    ADD CONSTRAINT workspace_labels_pkey PRIMARY KEY (workspace_id, key);
+
+ALTER TABLE ONLY workspace_port_share_links
+    ADD CONSTRAINT workspace_port_share_links_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_proxies
     ADD CONSTRAINT workspace_proxies_pkey PRIMARY KEY (id);
 
 ALTER TABLE ONLY workspace_proxies
     ADD CONSTRAINT workspace_proxies_region_id_unique UNIQUE (region_id);
 
+ALTER TABLE ONLY workspace_proxy_token_rotations
+    ADD CONSTRAINT workspace_proxy_token_rotations_pkey PRIMARY KEY (proxy_id);
+
 ALTER TABLE ONLY workspace_resource_metadata
     ADD CONSTRAINT workspace_resource_metadata_name UNIQUE (workspace_resource_id, key);
 
@@ -2680,9 +3576,16 @@ ALTER TABLE ONLY workspace_resource_metadata
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_schedule_pauses
+    ADD CONSTRAINT workspace_schedule_pauses_pkey PRIMARY KEY (workspace_id);
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
 
+CREATE INDEX ai_task_queue_owner_id_created_at_idx ON ai_task_queue USING btree (owner_id, created_at);
+
+CREATE INDEX audit_log_legal_holds_organization_id_idx ON audit_log_legal_holds USING btree (organization_id);
+
 CREATE INDEX idx_agent_stats_created_at ON workspace_agent_stats USING btree (created_at);
 
 CREATE INDEX idx_agent_stats_user_id ON workspace_agent_stats USING btree (user_id);
@@ -2697,6 +3600,8 @@ CREATE INDEX idx_audit_log_resource_id ON audit_logs USING btree (resource_id);
 
 CREATE INDEX idx_audit_log_user_id ON audit_logs USING btree (user_id);
 
+CREATE INDEX idx_audit_logs_search ON audit_logs USING gin (to_tsvector('simple'::regconfig, ((((resource_target || ' '::text) || (diff)::text) || ' '::text) || (additional_fields)::text)));
+
 CREATE INDEX idx_audit_logs_time_desc ON audit_logs USING btree ("time" DESC);
 
 CREATE INDEX idx_custom_roles_id ON custom_roles USING btree (id);
@@ -2719,6 +3624,8 @@ CREATE UNIQUE INDEX idx_provisioner_daemons_org_name_owner_key ON provisioner_da
 
 COMMENT ON INDEX idx_provisioner_daemons_org_name_owner_key IS 'Allow unique provisioner daemon names by organization and user';
 
+CREATE INDEX idx_provisioner_job_logs_output_search ON provisioner_job_logs USING gin (to_tsvector('simple'::regconfig, (output)::text));
+
 CREATE INDEX idx_provisioner_jobs_status ON provisioner_jobs USING btree (job_status);
 
 CREATE INDEX idx_tailnet_agents_coordinator ON tailnet_agents USING btree (coordinator_id);
@@ -2731,8 +3638,12 @@ CREATE INDEX idx_tailnet_tunnels_dst_id ON tailnet_tunnels USING hash (dst_id);
 
 CREATE INDEX idx_tailnet_tunnels_src_id ON tailnet_tunnels USING hash (src_id);
 
+CREATE INDEX idx_template_version_activation_requests_template_id ON template_version_activation_requests USING btree (template_id);
+
 CREATE UNIQUE INDEX idx_template_version_presets_default ON template_version_presets USING btree (template_version_id) WHERE (is_default = true);
 
+CREATE INDEX idx_template_version_rollouts_template_version_id ON template_version_rollouts USING btree (template_version_id);
+
 CREATE INDEX idx_template_versions_has_ai_task ON template_versions USING btree (has_ai_task);
 
 CREATE UNIQUE INDEX idx_unique_preset_name ON template_version_presets USING btree (name, template_version_id);
@@ -2745,14 +3656,22 @@ CREATE UNIQUE INDEX idx_users_email ON users USING btree (email) WHERE (deleted
 
 CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (deleted = false);
 
+CREATE INDEX idx_workspace_agent_lifecycle_events_created_at ON workspace_agent_lifecycle_events USING btree (created_at DESC);
+
 CREATE INDEX idx_workspace_app_statuses_workspace_id_created_at ON workspace_app_statuses USING btree (workspace_id, created_at DESC);
 
+CREATE INDEX idx_workspace_builds_created_at ON workspace_builds USING btree (created_at DESC);
+
+CREATE INDEX network_connection_quality_updated_at_idx ON network_connection_quality USING btree (updated_at);
+
 CREATE UNIQUE INDEX notification_messages_dedupe_hash_idx ON notification_messages USING btree (dedupe_hash);
 
 CREATE UNIQUE INDEX organizations_single_default_org ON organizations USING btree (is_default) WHERE (is_default = true);
 
 CREATE INDEX provisioner_job_logs_id_job_id_idx ON provisioner_job_logs USING btree (job_id, id);
 
+CREATE INDEX provisioner_job_requeues_job_id_idx ON provisioner_job_requeues USING btree (job_id);
+
 CREATE INDEX provisioner_jobs_started_at_idx ON provisioner_jobs USING btree (started_at) WHERE (started_at IS NULL);
 
 CREATE UNIQUE INDEX provisioner_keys_organization_id_name_idx ON provisioner_keys USING btree (organization_id, lower((name)::text));
@@ -2765,14 +3684,24 @@ CREATE UNIQUE INDEX template_usage_stats_start_time_template_id_user_id_idx ON t
 
 COMMENT ON INDEX template_usage_stats_start_time_template_id_user_id_idx IS 'Index for primary key.';
 
+CREATE INDEX template_variable_sets_variable_set_id_idx ON template_variable_sets USING btree (variable_set_id);
+
 CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree (organization_id, lower((name)::text)) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX user_links_linked_id_login_type_idx ON user_links USING btree (linked_id, login_type) WHERE (linked_id <> ''::text);
 
+CREATE UNIQUE INDEX user_secrets_user_id_env_name_idx ON user_secrets USING btree (user_id, env_name) WHERE (env_name <> ''::text);
+
+CREATE INDEX user_webauthn_credentials_user_id_idx ON user_webauthn_credentials USING btree (user_id);
+
 CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
 
+CREATE INDEX webauthn_challenges_expires_at_idx ON webauthn_challenges USING btree (expires_at);
+
+CREATE UNIQUE INDEX workspace_adoptions_workspace_id_idx ON workspace_adoptions USING btree (workspace_id);
+
 CREATE INDEX workspace_agent_devcontainers_workspace_agent_id ON workspace_agent_devcontainers USING btree (workspace_agent_id);
 
 COMMENT ON INDEX workspace_agent_devcontainers_workspace_agent_id IS 'Workspace agent foreign key and query index';
@@ -2783,6 +3712,8 @@ COMMENT ON INDEX workspace_agent_scripts_workspace_agent_id_idx IS 'Foreign key
 
 CREATE INDEX workspace_agent_startup_logs_id_agent_id_idx ON workspace_agent_logs USING btree (agent_id, id);
 
+CREATE INDEX workspace_agent_token_rotations_previous_auth_token_idx ON workspace_agent_token_rotations USING btree (previous_auth_token);
+
 CREATE INDEX workspace_agent_stats_template_id_created_at_user_id_idx ON workspace_agent_stats USING btree (template_id, created_at, user_id) INCLUDE (session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, connection_median_latency_ms) WHERE (connection_count > 0);
 
 COMMENT ON INDEX workspace_agent_stats_template_id_created_at_user_id_idx IS 'Support index for template insights endpoint to build interval reports faster.';
@@ -2797,10 +3728,20 @@ COMMENT ON INDEX workspace_app_audit_sessions_unique_index IS 'Unique index to e
 
 CREATE INDEX workspace_app_stats_workspace_id_idx ON workspace_app_stats USING btree (workspace_id);
 
+CREATE INDEX workspace_archives_expires_at_idx ON workspace_archives USING btree (expires_at);
+
+CREATE INDEX workspace_archives_owner_id_idx ON workspace_archives USING btree (owner_id);
+
+CREATE INDEX workspace_archives_workspace_id_created_at_idx ON workspace_archives USING btree (workspace_id, created_at DESC);
+
+CREATE INDEX workspace_labels_key_value_idx ON workspace_labels USING btree (key, value);
+
 CREATE INDEX workspace_modules_created_at_idx ON workspace_modules USING btree (created_at);
 
 CREATE INDEX workspace_next_start_at_idx ON workspaces USING btree (next_start_at) WHERE (deleted = false);
 
+CREATE INDEX workspace_port_share_links_workspace_id_idx ON workspace_port_share_links USING btree (workspace_id);
+
 CREATE UNIQUE INDEX workspace_proxies_lower_name_idx ON workspace_proxies USING btree (lower(name)) WHERE (deleted = false);
 
 CREATE INDEX workspace_resources_job_id_idx ON workspace_resources USING btree (job_id);
@@ -2897,15 +3838,44 @@ CREATE TRIGGER update_notification_message_dedupe_hash BEFORE INSERT OR UPDATE O
 
 CREATE TRIGGER user_status_change_trigger AFTER INSERT OR UPDATE ON users FOR EACH ROW EXECUTE FUNCTION record_user_status_change();
 
+CREATE TRIGGER workspace_agent_lifecycle_event_trigger AFTER UPDATE OF lifecycle_state ON workspace_agents FOR EACH ROW EXECUTE FUNCTION record_workspace_agent_lifecycle_event();
+
 CREATE TRIGGER workspace_agent_name_unique_trigger BEFORE INSERT OR UPDATE OF name, resource_id ON workspace_agents FOR EACH ROW EXECUTE FUNCTION check_workspace_agent_name_unique();
 
 COMMENT ON TRIGGER workspace_agent_name_unique_trigger ON workspace_agents IS 'Use a trigger instead of a unique constraint because existing data may violate
 the uniqueness requirement. A trigger allows us to enforce uniqueness going
 forward without requiring a migration to clean up historical data.';
 
+ALTER TABLE ONLY ai_task_queue
+    ADD CONSTRAINT ai_task_queue_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY ai_task_queue
+    ADD CONSTRAINT ai_task_queue_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY ai_task_queue
+    ADD CONSTRAINT ai_task_queue_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY ai_task_queue
+    ADD CONSTRAINT ai_task_queue_template_version_preset_id_fkey FOREIGN KEY (template_version_preset_id) REFERENCES template_version_presets(id) ON DELETE SET NULL;
+
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY api_keys
+    ADD CONSTRAINT api_keys_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY audit_log_legal_holds
+    ADD CONSTRAINT audit_log_legal_holds_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id);
+
+ALTER TABLE ONLY audit_log_legal_holds
+    ADD CONSTRAINT audit_log_legal_holds_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY audit_log_legal_holds
+    ADD CONSTRAINT audit_log_legal_holds_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY audit_log_legal_holds
+    ADD CONSTRAINT audit_log_legal_holds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY crypto_keys
     ADD CONSTRAINT crypto_keys_secret_key_id_fkey FOREIGN KEY (secret_key_id) REFERENCES dbcrypt_keys(active_key_digest);
 
@@ -2927,6 +3897,9 @@ ALTER TABLE ONLY group_members
 ALTER TABLE ONLY group_members
     ADD CONSTRAINT group_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY group_quota_allowances
+    ADD CONSTRAINT group_quota_allowances_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
@@ -2942,6 +3915,15 @@ ALTER TABLE ONLY jfrog_xray_scans
 ALTER TABLE ONLY jfrog_xray_scans
     ADD CONSTRAINT jfrog_xray_scans_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY ldap_users
+    ADD CONSTRAINT ldap_users_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY license_seat_reservations
+    ADD CONSTRAINT license_seat_reservations_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY notification_category_preferences
+    ADD CONSTRAINT notification_category_preferences_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY notification_messages
     ADD CONSTRAINT notification_messages_notification_template_id_fkey FOREIGN KEY (notification_template_id) REFERENCES notification_templates(id) ON DELETE CASCADE;
 
@@ -2960,6 +3942,12 @@ ALTER TABLE ONLY oauth2_provider_app_codes
 ALTER TABLE ONLY oauth2_provider_app_codes
     ADD CONSTRAINT oauth2_provider_app_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY oauth2_provider_app_device_codes
+    ADD CONSTRAINT oauth2_provider_app_device_codes_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_device_codes
+    ADD CONSTRAINT oauth2_provider_app_device_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY oauth2_provider_app_secrets
     ADD CONSTRAINT oauth2_provider_app_secrets_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
 
@@ -2969,12 +3957,21 @@ ALTER TABLE ONLY oauth2_provider_app_tokens
 ALTER TABLE ONLY oauth2_provider_app_tokens
     ADD CONSTRAINT oauth2_provider_app_tokens_app_secret_id_fkey FOREIGN KEY (app_secret_id) REFERENCES oauth2_provider_app_secrets(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY organization_audit_settings
+    ADD CONSTRAINT organization_audit_settings_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY organization_mfa_settings
+    ADD CONSTRAINT organization_mfa_settings_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_organization_id_uuid_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY organization_setting_overrides
+    ADD CONSTRAINT organization_setting_overrides_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY parameter_schemas
     ADD CONSTRAINT parameter_schemas_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
@@ -2987,6 +3984,9 @@ ALTER TABLE ONLY provisioner_daemons
 ALTER TABLE ONLY provisioner_job_logs
     ADD CONSTRAINT provisioner_job_logs_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY provisioner_job_requeues
+    ADD CONSTRAINT provisioner_job_requeues_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY provisioner_job_timings
     ADD CONSTRAINT provisioner_job_timings_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
@@ -2996,6 +3996,24 @@ ALTER TABLE ONLY provisioner_jobs
 ALTER TABLE ONLY provisioner_keys
     ADD CONSTRAINT provisioner_keys_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY review_workspaces
+    ADD CONSTRAINT review_workspaces_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY review_workspaces
+    ADD CONSTRAINT review_workspaces_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY scim_group_members
+    ADD CONSTRAINT scim_group_members_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY scim_group_members
+    ADD CONSTRAINT scim_group_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY scim_nested_groups
+    ADD CONSTRAINT scim_nested_groups_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY scim_nested_groups
+    ADD CONSTRAINT scim_nested_groups_member_group_id_fkey FOREIGN KEY (member_group_id) REFERENCES groups(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY tailnet_agents
     ADD CONSTRAINT tailnet_agents_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 
@@ -3011,6 +4029,57 @@ ALTER TABLE ONLY tailnet_peers
 ALTER TABLE ONLY tailnet_tunnels
     ADD CONSTRAINT tailnet_tunnels_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_activation_reviewers
+    ADD CONSTRAINT template_activation_reviewers_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_activation_reviewers
+    ADD CONSTRAINT template_activation_reviewers_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_app_restrictions
+    ADD CONSTRAINT template_app_restrictions_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_app_session_policies
+    ADD CONSTRAINT template_app_session_policies_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_bundles
+    ADD CONSTRAINT template_bundles_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_bundles
+    ADD CONSTRAINT template_bundles_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_egress_policies
+    ADD CONSTRAINT template_egress_policies_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_parameter_option_sources
+    ADD CONSTRAINT template_parameter_option_sources_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_secrets
+    ADD CONSTRAINT template_secrets_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_secrets
+    ADD CONSTRAINT template_secrets_value_key_id_fkey FOREIGN KEY (value_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
+ALTER TABLE ONLY template_variable_sets
+    ADD CONSTRAINT template_variable_sets_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_variable_sets
+    ADD CONSTRAINT template_variable_sets_variable_set_id_fkey FOREIGN KEY (variable_set_id) REFERENCES variable_sets(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_activation_requests
+    ADD CONSTRAINT template_version_activation_requests_requested_by_fkey FOREIGN KEY (requested_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_activation_requests
+    ADD CONSTRAINT template_version_activation_requests_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_activation_requests
+    ADD CONSTRAINT template_version_activation_requests_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_activation_reviews
+    ADD CONSTRAINT template_version_activation_reviews_reviewer_id_fkey FOREIGN KEY (reviewer_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_activation_reviews
+    ADD CONSTRAINT template_version_activation_reviews_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_version_activation_requests(template_version_id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
@@ -3023,6 +4092,15 @@ ALTER TABLE ONLY template_version_preset_prebuild_schedules
 ALTER TABLE ONLY template_version_presets
     ADD CONSTRAINT template_version_presets_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_version_rollouts
+    ADD CONSTRAINT template_version_rollouts_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_rollouts
+    ADD CONSTRAINT template_version_rollouts_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_rollouts
+    ADD CONSTRAINT template_version_rollouts_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_terraform_values
     ADD CONSTRAINT template_version_terraform_values_cached_module_files_fkey FOREIGN KEY (cached_module_files) REFERENCES files(id);
 
@@ -3050,6 +4128,9 @@ ALTER TABLE ONLY templates
 ALTER TABLE ONLY templates
     ADD CONSTRAINT templates_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY user_cli_versions
+    ADD CONSTRAINT user_cli_versions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY user_configs
     ADD CONSTRAINT user_configs_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
@@ -3065,15 +4146,51 @@ ALTER TABLE ONLY user_links
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY user_mfa_recovery_codes
+    ADD CONSTRAINT user_mfa_recovery_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_secrets
+    ADD CONSTRAINT user_secrets_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_secrets
+    ADD CONSTRAINT user_secrets_value_key_id_fkey FOREIGN KEY (value_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
 ALTER TABLE ONLY user_status_changes
     ADD CONSTRAINT user_status_changes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
 
+ALTER TABLE ONLY user_webauthn_credentials
+    ADD CONSTRAINT user_webauthn_credentials_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY variable_sets
+    ADD CONSTRAINT variable_sets_variables_key_id_fkey FOREIGN KEY (variables_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
+ALTER TABLE ONLY webauthn_challenges
+    ADD CONSTRAINT webauthn_challenges_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY webpush_subscriptions
     ADD CONSTRAINT webpush_subscriptions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_adoptions
+    ADD CONSTRAINT workspace_adoptions_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_adoptions
+    ADD CONSTRAINT workspace_adoptions_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_adoptions
+    ADD CONSTRAINT workspace_adoptions_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_adoptions
+    ADD CONSTRAINT workspace_adoptions_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_adoptions
+    ADD CONSTRAINT workspace_adoptions_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_devcontainers
     ADD CONSTRAINT workspace_agent_devcontainers_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_lifecycle_events
+    ADD CONSTRAINT workspace_agent_lifecycle_events_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_log_sources
     ADD CONSTRAINT workspace_agent_log_sources_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
@@ -3095,9 +4212,15 @@ ALTER TABLE ONLY workspace_agent_scripts
 ALTER TABLE ONLY workspace_agent_logs
     ADD CONSTRAINT workspace_agent_startup_logs_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_token_rotations
+    ADD CONSTRAINT workspace_agent_token_rotations_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_volume_resource_monitors
     ADD CONSTRAINT workspace_agent_volume_resource_monitors_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_volume_usage
+    ADD CONSTRAINT workspace_agent_volume_usage_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agents
     ADD CONSTRAINT workspace_agents_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
@@ -3128,9 +4251,15 @@ ALTER TABLE ONLY workspace_app_statuses
 ALTER TABLE ONLY workspace_apps
     ADD CONSTRAINT workspace_apps_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_archives
+    ADD CONSTRAINT workspace_archives_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_build_parameters
     ADD CONSTRAINT workspace_build_parameters_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_build_quota_costs
+    ADD CONSTRAINT workspace_build_quota_costs_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_ai_task_sidebar_app_id_fkey FOREIGN KEY (ai_task_sidebar_app_id) REFERENCES workspace_apps(id);
 
@@ -3146,15 +4275,33 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_labels
+    ADD CONSTRAINT workspace_labels_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_modules
     ADD CONSTRAINT workspace_modules_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_port_share_links
+    ADD CONSTRAINT workspace_port_share_links_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_port_share_links
+    ADD CONSTRAINT workspace_port_share_links_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_proxy_token_rotations
+    ADD CONSTRAINT workspace_proxy_token_rotations_proxy_id_fkey FOREIGN KEY (proxy_id) REFERENCES workspace_proxies(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_resource_metadata
     ADD CONSTRAINT workspace_resource_metadata_workspace_resource_id_fkey FOREIGN KEY (workspace_resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_schedule_pauses
+    ADD CONSTRAINT workspace_schedule_pauses_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_schedule_pauses
+    ADD CONSTRAINT workspace_schedule_pauses_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT;
 
@@ -6,7 +6,16 @@ type ForeignKeyConstraint string
 
 // ForeignKeyConstraint enums.
 const (
+	ForeignKeyAiTaskQueueOrganizationID                           ForeignKeyConstraint = "ai_task_queue_organization_id_fkey"                              // ALTER TABLE ONLY ai_task_queue ADD CONSTRAINT ai_task_queue_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyAiTaskQueueOwnerID                                  ForeignKeyConstraint = "ai_task_queue_owner_id_fkey"                                     // ALTER TABLE ONLY ai_task_queue ADD CONSTRAINT ai_task_queue_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyAiTaskQueueTemplateVersionID                        ForeignKeyConstraint = "ai_task_queue_template_version_id_fkey"                          // ALTER TABLE ONLY ai_task_queue ADD CONSTRAINT ai_task_queue_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+	ForeignKeyAiTaskQueueTemplateVersionPresetID                  ForeignKeyConstraint = "ai_task_queue_template_version_preset_id_fkey"                   // ALTER TABLE ONLY ai_task_queue ADD CONSTRAINT ai_task_queue_template_version_preset_id_fkey FOREIGN KEY (template_version_preset_id) REFERENCES template_version_presets(id) ON DELETE SET NULL;
 	ForeignKeyAPIKeysUserIDUUID                                   ForeignKeyConstraint = "api_keys_user_id_uuid_fkey"                                      // ALTER TABLE ONLY api_keys ADD CONSTRAINT api_keys_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyAPIKeysWorkspaceID                                  ForeignKeyConstraint = "api_keys_workspace_id_fkey"                                      // ALTER TABLE ONLY api_keys ADD CONSTRAINT api_keys_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+	ForeignKeyAuditLogLegalHoldsCreatedBy                         ForeignKeyConstraint = "audit_log_legal_holds_created_by_fkey"                           // ALTER TABLE ONLY audit_log_legal_holds ADD CONSTRAINT audit_log_legal_holds_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id);
+	ForeignKeyAuditLogLegalHoldsOrganizationID                    ForeignKeyConstraint = "audit_log_legal_holds_organization_id_fkey"                      // ALTER TABLE ONLY audit_log_legal_holds ADD CONSTRAINT audit_log_legal_holds_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyAuditLogLegalHoldsUserID                            ForeignKeyConstraint = "audit_log_legal_holds_user_id_fkey"                              // ALTER TABLE ONLY audit_log_legal_holds ADD CONSTRAINT audit_log_legal_holds_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyAuditLogLegalHoldsWorkspaceID                       ForeignKeyConstraint = "audit_log_legal_holds_workspace_id_fkey"                         // ALTER TABLE ONLY audit_log_legal_holds ADD CONSTRAINT audit_log_legal_holds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 	ForeignKeyCryptoKeysSecretKeyID                               ForeignKeyConstraint = "crypto_keys_secret_key_id_fkey"                                  // ALTER TABLE ONLY crypto_keys ADD CONSTRAINT crypto_keys_secret_key_id_fkey FOREIGN KEY (secret_key_id) REFERENCES dbcrypt_keys(active_key_digest);
 	ForeignKeyFkOauth2ProviderAppTokensUserID                     ForeignKeyConstraint = "fk_oauth2_provider_app_tokens_user_id"                           // ALTER TABLE ONLY oauth2_provider_app_tokens ADD CONSTRAINT fk_oauth2_provider_app_tokens_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyGitAuthLinksOauthAccessTokenKeyID                   ForeignKeyConstraint = "git_auth_links_oauth_access_token_key_id_fkey"                   // ALTER TABLE ONLY external_auth_links ADD CONSTRAINT git_auth_links_oauth_access_token_key_id_fkey FOREIGN KEY (oauth_access_token_key_id) REFERENCES dbcrypt_keys(active_key_digest);
@@ -14,38 +23,74 @@ const (
 	ForeignKeyGitSSHKeysUserID                                    ForeignKeyConstraint = "gitsshkeys_user_id_fkey"                                         // ALTER TABLE ONLY gitsshkeys ADD CONSTRAINT gitsshkeys_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
 	ForeignKeyGroupMembersGroupID                                 ForeignKeyConstraint = "group_members_group_id_fkey"                                     // ALTER TABLE ONLY group_members ADD CONSTRAINT group_members_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
 	ForeignKeyGroupMembersUserID                                  ForeignKeyConstraint = "group_members_user_id_fkey"                                      // ALTER TABLE ONLY group_members ADD CONSTRAINT group_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyGroupQuotaAllowancesGroupID                         ForeignKeyConstraint = "group_quota_allowances_group_id_fkey"                            // ALTER TABLE ONLY group_quota_allowances ADD CONSTRAINT group_quota_allowances_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
 	ForeignKeyGroupsOrganizationID                                ForeignKeyConstraint = "groups_organization_id_fkey"                                     // ALTER TABLE ONLY groups ADD CONSTRAINT groups_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 	ForeignKeyInboxNotificationsTemplateID                        ForeignKeyConstraint = "inbox_notifications_template_id_fkey"                            // ALTER TABLE ONLY inbox_notifications ADD CONSTRAINT inbox_notifications_template_id_fkey FOREIGN KEY (template_id) REFERENCES notification_templates(id) ON DELETE CASCADE;
 	ForeignKeyInboxNotificationsUserID                            ForeignKeyConstraint = "inbox_notifications_user_id_fkey"                                // ALTER TABLE ONLY inbox_notifications ADD CONSTRAINT inbox_notifications_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyJfrogXrayScansAgentID                               ForeignKeyConstraint = "jfrog_xray_scans_agent_id_fkey"                                  // ALTER TABLE ONLY jfrog_xray_scans ADD CONSTRAINT jfrog_xray_scans_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyJfrogXrayScansWorkspaceID                           ForeignKeyConstraint = "jfrog_xray_scans_workspace_id_fkey"                              // ALTER TABLE ONLY jfrog_xray_scans ADD CONSTRAINT jfrog_xray_scans_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+	ForeignKeyLdapUsersUserID                                     ForeignKeyConstraint = "ldap_users_user_id_fkey"                                         // ALTER TABLE ONLY ldap_users ADD CONSTRAINT ldap_users_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyLicenseSeatReservationsGroupID                      ForeignKeyConstraint = "license_seat_reservations_group_id_fkey"                         // ALTER TABLE ONLY license_seat_reservations ADD CONSTRAINT license_seat_reservations_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+	ForeignKeyNotificationCategoryPreferencesUserID               ForeignKeyConstraint = "notification_category_preferences_user_id_fkey"                  // ALTER TABLE ONLY notification_category_preferences ADD CONSTRAINT notification_category_preferences_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyNotificationMessagesNotificationTemplateID          ForeignKeyConstraint = "notification_messages_notification_template_id_fkey"             // ALTER TABLE ONLY notification_messages ADD CONSTRAINT notification_messages_notification_template_id_fkey FOREIGN KEY (notification_template_id) REFERENCES notification_templates(id) ON DELETE CASCADE;
 	ForeignKeyNotificationMessagesUserID                          ForeignKeyConstraint = "notification_messages_user_id_fkey"                              // ALTER TABLE ONLY notification_messages ADD CONSTRAINT notification_messages_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyNotificationPreferencesNotificationTemplateID       ForeignKeyConstraint = "notification_preferences_notification_template_id_fkey"          // ALTER TABLE ONLY notification_preferences ADD CONSTRAINT notification_preferences_notification_template_id_fkey FOREIGN KEY (notification_template_id) REFERENCES notification_templates(id) ON DELETE CASCADE;
 	ForeignKeyNotificationPreferencesUserID                       ForeignKeyConstraint = "notification_preferences_user_id_fkey"                           // ALTER TABLE ONLY notification_preferences ADD CONSTRAINT notification_preferences_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyOauth2ProviderAppCodesAppID                         ForeignKeyConstraint = "oauth2_provider_app_codes_app_id_fkey"                           // ALTER TABLE ONLY oauth2_provider_app_codes ADD CONSTRAINT oauth2_provider_app_codes_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
 	ForeignKeyOauth2ProviderAppCodesUserID                        ForeignKeyConstraint = "oauth2_provider_app_codes_user_id_fkey"                          // ALTER TABLE ONLY oauth2_provider_app_codes ADD CONSTRAINT oauth2_provider_app_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyOauth2ProviderAppDeviceCodesAppID                   ForeignKeyConstraint = "oauth2_provider_app_device_codes_app_id_fkey"                    // ALTER TABLE ONLY oauth2_provider_app_device_codes ADD CONSTRAINT oauth2_provider_app_device_codes_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+	ForeignKeyOauth2ProviderAppDeviceCodesUserID                  ForeignKeyConstraint = "oauth2_provider_app_device_codes_user_id_fkey"                   // ALTER TABLE ONLY oauth2_provider_app_device_codes ADD CONSTRAINT oauth2_provider_app_device_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyOauth2ProviderAppSecretsAppID                       ForeignKeyConstraint = "oauth2_provider_app_secrets_app_id_fkey"                         // ALTER TABLE ONLY oauth2_provider_app_secrets ADD CONSTRAINT oauth2_provider_app_secrets_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
 	ForeignKeyOauth2ProviderAppTokensAPIKeyID                     ForeignKeyConstraint = "oauth2_provider_app_tokens_api_key_id_fkey"                      // ALTER TABLE ONLY oauth2_provider_app_tokens ADD CONSTRAINT oauth2_provider_app_tokens_api_key_id_fkey FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE CASCADE;
 	ForeignKeyOauth2ProviderAppTokensAppSecretID                  ForeignKeyConstraint = "oauth2_provider_app_tokens_app_secret_id_fkey"                   // ALTER TABLE ONLY oauth2_provider_app_tokens ADD CONSTRAINT oauth2_provider_app_tokens_app_secret_id_fkey FOREIGN KEY (app_secret_id) REFERENCES oauth2_provider_app_secrets(id) ON DELETE CASCADE;
+	ForeignKeyOrganizationAuditSettingsOrganizationID             ForeignKeyConstraint = "organization_audit_settings_organization_id_fkey"                // ALTER TABLE ONLY organization_audit_settings ADD CONSTRAINT organization_audit_settings_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyOrganizationMfaSettingsOrganizationID               ForeignKeyConstraint = "organization_mfa_settings_organization_id_fkey"                  // ALTER TABLE ONLY organization_mfa_settings ADD CONSTRAINT organization_mfa_settings_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 	ForeignKeyOrganizationMembersOrganizationIDUUID               ForeignKeyConstraint = "organization_members_organization_id_uuid_fkey"                  // ALTER TABLE ONLY organization_members ADD CONSTRAINT organization_members_organization_id_uuid_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 	ForeignKeyOrganizationMembersUserIDUUID                       ForeignKeyConstraint = "organization_members_user_id_uuid_fkey"                          // ALTER TABLE ONLY organization_members ADD CONSTRAINT organization_members_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyOrganizationSettingOverridesOrganizationID          ForeignKeyConstraint = "organization_setting_overrides_organization_id_fkey"             // ALTER TABLE ONLY organization_setting_overrides ADD CONSTRAINT organization_setting_overrides_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 	ForeignKeyParameterSchemasJobID                               ForeignKeyConstraint = "parameter_schemas_job_id_fkey"                                   // ALTER TABLE ONLY parameter_schemas ADD CONSTRAINT parameter_schemas_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 	ForeignKeyProvisionerDaemonsKeyID                             ForeignKeyConstraint = "provisioner_daemons_key_id_fkey"                                 // ALTER TABLE ONLY provisioner_daemons ADD CONSTRAINT provisioner_daemons_key_id_fkey FOREIGN KEY (key_id) REFERENCES provisioner_keys(id) ON DELETE CASCADE;
 	ForeignKeyProvisionerDaemonsOrganizationID                    ForeignKeyConstraint = "provisioner_daemons_organization_id_fkey"                        // ALTER TABLE ONLY provisioner_daemons ADD CONSTRAINT provisioner_daemons_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 	ForeignKeyProvisionerJobLogsJobID                             ForeignKeyConstraint = "provisioner_job_logs_job_id_fkey"                                // ALTER TABLE ONLY provisioner_job_logs ADD CONSTRAINT provisioner_job_logs_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+	ForeignKeyProvisionerJobRequeuesJobID                         ForeignKeyConstraint = "provisioner_job_requeues_job_id_fkey"                            // ALTER TABLE ONLY provisioner_job_requeues ADD CONSTRAINT provisioner_job_requeues_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 	ForeignKeyProvisionerJobTimingsJobID                          ForeignKeyConstraint = "provisioner_job_timings_job_id_fkey"                             // ALTER TABLE ONLY provisioner_job_timings ADD CONSTRAINT provisioner_job_timings_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 	ForeignKeyProvisionerJobsOrganizationID                       ForeignKeyConstraint = "provisioner_jobs_organization_id_fkey"                           // ALTER TABLE ONLY provisioner_jobs ADD CONSTRAINT provisioner_jobs_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 	ForeignKeyProvisionerKeysOrganizationID                       ForeignKeyConstraint = "provisioner_keys_organization_id_fkey"                           // ALTER TABLE ONLY provisioner_keys ADD CONSTRAINT provisioner_keys_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyReviewWorkspacesOrganizationID                      ForeignKeyConstraint = "review_workspaces_organization_id_fkey"                          // ALTER TABLE ONLY review_workspaces ADD CONSTRAINT review_workspaces_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyReviewWorkspacesWorkspaceID                         ForeignKeyConstraint = "review_workspaces_workspace_id_fkey"                             // ALTER TABLE ONLY review_workspaces ADD CONSTRAINT review_workspaces_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+	ForeignKeyScimGroupMembersGroupID                             ForeignKeyConstraint = "scim_group_members_group_id_fkey"                                // ALTER TABLE ONLY scim_group_members ADD CONSTRAINT scim_group_members_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+	ForeignKeyScimGroupMembersUserID                              ForeignKeyConstraint = "scim_group_members_user_id_fkey"                                 // ALTER TABLE ONLY scim_group_members ADD CONSTRAINT scim_group_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyScimNestedGroupsGroupID                             ForeignKeyConstraint = "scim_nested_groups_group_id_fkey"                                // ALTER TABLE ONLY scim_nested_groups ADD CONSTRAINT scim_nested_groups_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+	ForeignKeyScimNestedGroupsMemberGroupID                       ForeignKeyConstraint = "scim_nested_groups_member_group_id_fkey"                         // ALTER TABLE ONLY scim_nested_groups ADD CONSTRAINT scim_nested_groups_member_group_id_fkey FOREIGN KEY (member_group_id) REFERENCES groups(id) ON DELETE CASCADE;
 	ForeignKeyTailnetAgentsCoordinatorID                          ForeignKeyConstraint = "tailnet_agents_coordinator_id_fkey"                              // ALTER TABLE ONLY tailnet_agents ADD CONSTRAINT tailnet_agents_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 	ForeignKeyTailnetClientSubscriptionsCoordinatorID             ForeignKeyConstraint = "tailnet_client_subscriptions_coordinator_id_fkey"                // ALTER TABLE ONLY tailnet_client_subscriptions ADD CONSTRAINT tailnet_client_subscriptions_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 	ForeignKeyTailnetClientsCoordinatorID                         ForeignKeyConstraint = "tailnet_clients_coordinator_id_fkey"                             // ALTER TABLE ONLY tailnet_clients ADD CONSTRAINT tailnet_clients_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 	ForeignKeyTailnetPeersCoordinatorID                           ForeignKeyConstraint = "tailnet_peers_coordinator_id_fkey"                               // ALTER TABLE ONLY tailnet_peers ADD CONSTRAINT tailnet_peers_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 	ForeignKeyTailnetTunnelsCoordinatorID                         ForeignKeyConstraint = "tailnet_tunnels_coordinator_id_fkey"                             // ALTER TABLE ONLY tailnet_tunnels ADD CONSTRAINT tailnet_tunnels_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
+	ForeignKeyTemplateActivationReviewersTemplateID               ForeignKeyConstraint = "template_activation_reviewers_template_id_fkey"                  // ALTER TABLE ONLY template_activation_reviewers ADD CONSTRAINT template_activation_reviewers_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+	ForeignKeyTemplateActivationReviewersUserID                   ForeignKeyConstraint = "template_activation_reviewers_user_id_fkey"                      // ALTER TABLE ONLY template_activation_reviewers ADD CONSTRAINT template_activation_reviewers_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyTemplateAppRestrictionsTemplateID                   ForeignKeyConstraint = "template_app_restrictions_template_id_fkey"                      // ALTER TABLE ONLY template_app_restrictions ADD CONSTRAINT template_app_restrictions_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+	ForeignKeyTemplateAppSessionPoliciesTemplateID                ForeignKeyConstraint = "template_app_session_policies_template_id_fkey"                  // ALTER TABLE ONLY template_app_session_policies ADD CONSTRAINT template_app_session_policies_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+	ForeignKeyTemplateBundlesCreatedBy                            ForeignKeyConstraint = "template_bundles_created_by_fkey"                                // ALTER TABLE ONLY template_bundles ADD CONSTRAINT template_bundles_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyTemplateBundlesOrganizationID                       ForeignKeyConstraint = "template_bundles_organization_id_fkey"                           // ALTER TABLE ONLY template_bundles ADD CONSTRAINT template_bundles_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyTemplateEgressPoliciesTemplateID                    ForeignKeyConstraint = "template_egress_policies_template_id_fkey"                       // ALTER TABLE ONLY template_egress_policies ADD CONSTRAINT template_egress_policies_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+	ForeignKeyTemplateParameterOptionSourcesTemplateID            ForeignKeyConstraint = "template_parameter_option_sources_template_id_fkey"              // ALTER TABLE ONLY template_parameter_option_sources ADD CONSTRAINT template_parameter_option_sources_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+	ForeignKeyTemplateSecretsTemplateID                           ForeignKeyConstraint = "template_secrets_template_id_fkey"                               // ALTER TABLE ONLY template_secrets ADD CONSTRAINT template_secrets_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+	ForeignKeyTemplateSecretsValueKeyID                           ForeignKeyConstraint = "template_secrets_value_key_id_fkey"                              // ALTER TABLE ONLY template_secrets ADD CONSTRAINT template_secrets_value_key_id_fkey FOREIGN KEY (value_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+	ForeignKeyTemplateVariableSetsTemplateID                      ForeignKeyConstraint = "template_variable_sets_template_id_fkey"                         // ALTER TABLE ONLY template_variable_sets ADD CONSTRAINT template_variable_sets_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+	ForeignKeyTemplateVariableSetsVariableSetID                   ForeignKeyConstraint = "template_variable_sets_variable_set_id_fkey"                     // ALTER TABLE ONLY template_variable_sets ADD CONSTRAINT template_variable_sets_variable_set_id_fkey FOREIGN KEY (variable_set_id) REFERENCES variable_sets(id) ON DELETE CASCADE;
+	ForeignKeyTemplateVersionActivationRequestsRequestedBy        ForeignKeyConstraint = "template_version_activation_requests_requested_by_fkey"          // ALTER TABLE ONLY template_version_activation_requests ADD CONSTRAINT template_version_activation_requests_requested_by_fkey FOREIGN KEY (requested_by) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyTemplateVersionActivationRequestsTemplateID         ForeignKeyConstraint = "template_version_activation_requests_template_id_fkey"           // ALTER TABLE ONLY template_version_activation_requests ADD CONSTRAINT template_version_activation_requests_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+	ForeignKeyTemplateVersionActivationRequestsTemplateVersionID  ForeignKeyConstraint = "template_version_activation_requests_template_version_id_fkey"   // ALTER TABLE ONLY template_version_activation_requests ADD CONSTRAINT template_version_activation_requests_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+	ForeignKeyTemplateVersionActivationReviewsReviewerID          ForeignKeyConstraint = "template_version_activation_reviews_reviewer_id_fkey"            // ALTER TABLE ONLY template_version_activation_reviews ADD CONSTRAINT template_version_activation_reviews_reviewer_id_fkey FOREIGN KEY (reviewer_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyTemplateVersionActivationReviewsTemplateVersionID   ForeignKeyConstraint = "template_version_activation_reviews_template_version_id_fkey"    // ALTER TABLE ONLY template_version_activation_reviews ADD CONSTRAINT template_version_activation_reviews_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_version_activation_requests(template_version_id) ON DELETE CASCADE;
 	ForeignKeyTemplateVersionParametersTemplateVersionID          ForeignKeyConstraint = "template_version_parameters_template_version_id_fkey"            // ALTER TABLE ONLY template_version_parameters ADD CONSTRAINT template_version_parameters_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 	ForeignKeyTemplateVersionPresetParametTemplateVersionPresetID ForeignKeyConstraint = "template_version_preset_paramet_template_version_preset_id_fkey" // ALTER TABLE ONLY template_version_preset_parameters ADD CONSTRAINT template_version_preset_paramet_template_version_preset_id_fkey FOREIGN KEY (template_version_preset_id) REFERENCES template_version_presets(id) ON DELETE CASCADE;
 	ForeignKeyTemplateVersionPresetPrebuildSchedulesPresetID      ForeignKeyConstraint = "template_version_preset_prebuild_schedules_preset_id_fkey"       // ALTER TABLE ONLY template_version_preset_prebuild_schedules ADD CONSTRAINT template_version_preset_prebuild_schedules_preset_id_fkey FOREIGN KEY (preset_id) REFERENCES template_version_presets(id) ON DELETE CASCADE;
 	ForeignKeyTemplateVersionPresetsTemplateVersionID             ForeignKeyConstraint = "template_version_presets_template_version_id_fkey"               // ALTER TABLE ONLY template_version_presets ADD CONSTRAINT template_version_presets_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+	ForeignKeyTemplateVersionRolloutsCreatedBy                    ForeignKeyConstraint = "template_version_rollouts_created_by_fkey"                       // ALTER TABLE ONLY template_version_rollouts ADD CONSTRAINT template_version_rollouts_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyTemplateVersionRolloutsTemplateID                   ForeignKeyConstraint = "template_version_rollouts_template_id_fkey"                      // ALTER TABLE ONLY template_version_rollouts ADD CONSTRAINT template_version_rollouts_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+	ForeignKeyTemplateVersionRolloutsTemplateVersionID            ForeignKeyConstraint = "template_version_rollouts_template_version_id_fkey"              // ALTER TABLE ONLY template_version_rollouts ADD CONSTRAINT template_version_rollouts_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 	ForeignKeyTemplateVersionTerraformValuesCachedModuleFiles     ForeignKeyConstraint = "template_version_terraform_values_cached_module_files_fkey"      // ALTER TABLE ONLY template_version_terraform_values ADD CONSTRAINT template_version_terraform_values_cached_module_files_fkey FOREIGN KEY (cached_module_files) REFERENCES files(id);
 	ForeignKeyTemplateVersionTerraformValuesTemplateVersionID     ForeignKeyConstraint = "template_version_terraform_values_template_version_id_fkey"      // ALTER TABLE ONLY template_version_terraform_values ADD CONSTRAINT template_version_terraform_values_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 	ForeignKeyTemplateVersionVariablesTemplateVersionID           ForeignKeyConstraint = "template_version_variables_template_version_id_fkey"             // ALTER TABLE ONLY template_version_variables ADD CONSTRAINT template_version_variables_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
@@ -55,14 +100,27 @@ const (
 	ForeignKeyTemplateVersionsTemplateID                          ForeignKeyConstraint = "template_versions_template_id_fkey"                              // ALTER TABLE ONLY template_versions ADD CONSTRAINT template_versions_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
 	ForeignKeyTemplatesCreatedBy                                  ForeignKeyConstraint = "templates_created_by_fkey"                                       // ALTER TABLE ONLY templates ADD CONSTRAINT templates_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE RESTRICT;
 	ForeignKeyTemplatesOrganizationID                             ForeignKeyConstraint = "templates_organization_id_fkey"                                  // ALTER TABLE ONLY templates ADD CONSTRAINT templates_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyUserCliVersionsUserID                               ForeignKeyConstraint = "user_cli_versions_user_id_fkey"                                  // ALTER TABLE ONLY user_cli_versions ADD CONSTRAINT user_cli_versions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyUserConfigsUserID                                   ForeignKeyConstraint = "user_configs_user_id_fkey"                                       // ALTER TABLE ONLY user_configs ADD CONSTRAINT user_configs_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyUserDeletedUserID                                   ForeignKeyConstraint = "user_deleted_user_id_fkey"                                       // ALTER TABLE ONLY user_deleted ADD CONSTRAINT user_deleted_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
 	ForeignKeyUserLinksOauthAccessTokenKeyID                      ForeignKeyConstraint = "user_links_oauth_access_token_key_id_fkey"                       // ALTER TABLE ONLY user_links ADD CONSTRAINT user_links_oauth_access_token_key_id_fkey FOREIGN KEY (oauth_access_token_key_id) REFERENCES dbcrypt_keys(active_key_digest);
 	ForeignKeyUserLinksOauthRefreshTokenKeyID                     ForeignKeyConstraint = "user_links_oauth_refresh_token_key_id_fkey"                      // ALTER TABLE ONLY user_links ADD CONSTRAINT user_links_oauth_refresh_token_key_id_fkey FOREIGN KEY (oauth_refresh_token_key_id) REFERENCES dbcrypt_keys(active_key_digest);
 	ForeignKeyUserLinksUserID                                     ForeignKeyConstraint = "user_links_user_id_fkey"                                         // ALTER TABLE ONLY user_links ADD CONSTRAINT user_links_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyUserMfaRecoveryCodesUserID                          ForeignKeyConstraint = "user_mfa_recovery_codes_user_id_fkey"                            // ALTER TABLE ONLY user_mfa_recovery_codes ADD CONSTRAINT user_mfa_recovery_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyUserSecretsUserID                                   ForeignKeyConstraint = "user_secrets_user_id_fkey"                                       // ALTER TABLE ONLY user_secrets ADD CONSTRAINT user_secrets_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyUserSecretsValueKeyID                               ForeignKeyConstraint = "user_secrets_value_key_id_fkey"                                  // ALTER TABLE ONLY user_secrets ADD CONSTRAINT user_secrets_value_key_id_fkey FOREIGN KEY (value_key_id) REFERENCES dbcrypt_keys(active_key_digest);
 	ForeignKeyUserStatusChangesUserID                             ForeignKeyConstraint = "user_status_changes_user_id_fkey"                                // ALTER TABLE ONLY user_status_changes ADD CONSTRAINT user_status_changes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
+	ForeignKeyUserWebauthnCredentialsUserID                       ForeignKeyConstraint = "user_webauthn_credentials_user_id_fkey"                          // ALTER TABLE ONLY user_webauthn_credentials ADD CONSTRAINT user_webauthn_credentials_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyVariableSetsVariablesKeyID                          ForeignKeyConstraint = "variable_sets_variables_key_id_fkey"                             // ALTER TABLE ONLY variable_sets ADD CONSTRAINT variable_sets_variables_key_id_fkey FOREIGN KEY (variables_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+	ForeignKeyWebauthnChallengesUserID                            ForeignKeyConstraint = "webauthn_challenges_user_id_fkey"                                // ALTER TABLE ONLY webauthn_challenges ADD CONSTRAINT webauthn_challenges_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 	ForeignKeyWebpushSubscriptionsUserID                          ForeignKeyConstraint = "webpush_subscriptions_user_id_fkey"                              // ALTER TABLE ONLY webpush_subscriptions ADD CONSTRAINT webpush_subscriptions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceAdoptionsCreatedBy                         ForeignKeyConstraint = "workspace_adoptions_created_by_fkey"                             // ALTER TABLE ONLY workspace_adoptions ADD CONSTRAINT workspace_adoptions_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceAdoptionsOrganizationID                    ForeignKeyConstraint = "workspace_adoptions_organization_id_fkey"                        // ALTER TABLE ONLY workspace_adoptions ADD CONSTRAINT workspace_adoptions_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceAdoptionsOwnerID                           ForeignKeyConstraint = "workspace_adoptions_owner_id_fkey"                               // ALTER TABLE ONLY workspace_adoptions ADD CONSTRAINT workspace_adoptions_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceAdoptionsTemplateID                        ForeignKeyConstraint = "workspace_adoptions_template_id_fkey"                            // ALTER TABLE ONLY workspace_adoptions ADD CONSTRAINT workspace_adoptions_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceAdoptionsWorkspaceID                       ForeignKeyConstraint = "workspace_adoptions_workspace_id_fkey"                           // ALTER TABLE ONLY workspace_adoptions ADD CONSTRAINT workspace_adoptions_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentDevcontainersWorkspaceAgentID         ForeignKeyConstraint = "workspace_agent_devcontainers_workspace_agent_id_fkey"           // ALTER TABLE ONLY workspace_agent_devcontainers ADD CONSTRAINT workspace_agent_devcontainers_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceAgentLifecycleEventsWorkspaceAgentID       ForeignKeyConstraint = "workspace_agent_lifecycle_events_workspace_agent_id_fkey"        // ALTER TABLE ONLY workspace_agent_lifecycle_events ADD CONSTRAINT workspace_agent_lifecycle_events_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentLogSourcesWorkspaceAgentID            ForeignKeyConstraint = "workspace_agent_log_sources_workspace_agent_id_fkey"             // ALTER TABLE ONLY workspace_agent_log_sources ADD CONSTRAINT workspace_agent_log_sources_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentMemoryResourceMonitorsAgentID         ForeignKeyConstraint = "workspace_agent_memory_resource_monitors_agent_id_fkey"          // ALTER TABLE ONLY workspace_agent_memory_resource_monitors ADD CONSTRAINT workspace_agent_memory_resource_monitors_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentMetadataWorkspaceAgentID              ForeignKeyConstraint = "workspace_agent_metadata_workspace_agent_id_fkey"                // ALTER TABLE ONLY workspace_agent_metadata ADD CONSTRAINT workspace_agent_metadata_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
@@ -70,7 +128,9 @@ const (
 	ForeignKeyWorkspaceAgentScriptTimingsScriptID                 ForeignKeyConstraint = "workspace_agent_script_timings_script_id_fkey"                   // ALTER TABLE ONLY workspace_agent_script_timings ADD CONSTRAINT workspace_agent_script_timings_script_id_fkey FOREIGN KEY (script_id) REFERENCES workspace_agent_scripts(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentScriptsWorkspaceAgentID               ForeignKeyConstraint = "workspace_agent_scripts_workspace_agent_id_fkey"                 // ALTER TABLE ONLY workspace_agent_scripts ADD CONSTRAINT workspace_agent_scripts_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentStartupLogsAgentID                    ForeignKeyConstraint = "workspace_agent_startup_logs_agent_id_fkey"                      // ALTER TABLE ONLY workspace_agent_logs ADD CONSTRAINT workspace_agent_startup_logs_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceAgentTokenRotationsAgentID                 ForeignKeyConstraint = "workspace_agent_token_rotations_agent_id_fkey"                   // ALTER TABLE ONLY workspace_agent_token_rotations ADD CONSTRAINT workspace_agent_token_rotations_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentVolumeResourceMonitorsAgentID         ForeignKeyConstraint = "workspace_agent_volume_resource_monitors_agent_id_fkey"          // ALTER TABLE ONLY workspace_agent_volume_resource_monitors ADD CONSTRAINT workspace_agent_volume_resource_monitors_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceAgentVolumeUsageAgentID                    ForeignKeyConstraint = "workspace_agent_volume_usage_agent_id_fkey"                      // ALTER TABLE ONLY workspace_agent_volume_usage ADD CONSTRAINT workspace_agent_volume_usage_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentsParentID                             ForeignKeyConstraint = "workspace_agents_parent_id_fkey"                                 // ALTER TABLE ONLY workspace_agents ADD CONSTRAINT workspace_agents_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAgentsResourceID                           ForeignKeyConstraint = "workspace_agents_resource_id_fkey"                               // ALTER TABLE ONLY workspace_agents ADD CONSTRAINT workspace_agents_resource_id_fkey FOREIGN KEY (resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceAppAuditSessionsAgentID                    ForeignKeyConstraint = "workspace_app_audit_sessions_agent_id_fkey"                      // ALTER TABLE ONLY workspace_app_audit_sessions ADD CONSTRAINT workspace_app_audit_sessions_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
@@ -81,15 +141,23 @@ const (
 	ForeignKeyWorkspaceAppStatusesAppID                           ForeignKeyConstraint = "workspace_app_statuses_app_id_fkey"                              // ALTER TABLE ONLY workspace_app_statuses ADD CONSTRAINT workspace_app_statuses_app_id_fkey FOREIGN KEY (app_id) REFERENCES workspace_apps(id);
 	ForeignKeyWorkspaceAppStatusesWorkspaceID                     ForeignKeyConstraint = "workspace_app_statuses_workspace_id_fkey"                        // ALTER TABLE ONLY workspace_app_statuses ADD CONSTRAINT workspace_app_statuses_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id);
 	ForeignKeyWorkspaceAppsAgentID                                ForeignKeyConstraint = "workspace_apps_agent_id_fkey"                                    // ALTER TABLE ONLY workspace_apps ADD CONSTRAINT workspace_apps_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceArchivesWorkspaceID                        ForeignKeyConstraint = "workspace_archives_workspace_id_fkey"                            // ALTER TABLE ONLY workspace_archives ADD CONSTRAINT workspace_archives_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceBuildParametersWorkspaceBuildID            ForeignKeyConstraint = "workspace_build_parameters_workspace_build_id_fkey"              // ALTER TABLE ONLY workspace_build_parameters ADD CONSTRAINT workspace_build_parameters_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceBuildQuotaCostsWorkspaceBuildID            ForeignKeyConstraint = "workspace_build_quota_costs_workspace_build_id_fkey"             // ALTER TABLE ONLY workspace_build_quota_costs ADD CONSTRAINT workspace_build_quota_costs_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceBuildsAiTaskSidebarAppID                   ForeignKeyConstraint = "workspace_builds_ai_task_sidebar_app_id_fkey"                    // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_ai_task_sidebar_app_id_fkey FOREIGN KEY (ai_task_sidebar_app_id) REFERENCES workspace_apps(id);
 	ForeignKeyWorkspaceBuildsJobID                                ForeignKeyConstraint = "workspace_builds_job_id_fkey"                                    // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceBuildsTemplateVersionID                    ForeignKeyConstraint = "workspace_builds_template_version_id_fkey"                       // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceBuildsTemplateVersionPresetID              ForeignKeyConstraint = "workspace_builds_template_version_preset_id_fkey"                // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_template_version_preset_id_fkey FOREIGN KEY (template_version_preset_id) REFERENCES template_version_presets(id) ON DELETE SET NULL;
 	ForeignKeyWorkspaceBuildsWorkspaceID                          ForeignKeyConstraint = "workspace_builds_workspace_id_fkey"                              // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceLabelsWorkspaceID                          ForeignKeyConstraint = "workspace_labels_workspace_id_fkey"                              // ALTER TABLE ONLY workspace_labels ADD CONSTRAINT workspace_labels_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceModulesJobID                               ForeignKeyConstraint = "workspace_modules_job_id_fkey"                                   // ALTER TABLE ONLY workspace_modules ADD CONSTRAINT workspace_modules_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+	ForeignKeyWorkspacePortShareLinksCreatedBy                    ForeignKeyConstraint = "workspace_port_share_links_created_by_fkey"                      // ALTER TABLE ONLY workspace_port_share_links ADD CONSTRAINT workspace_port_share_links_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyWorkspacePortShareLinksWorkspaceID                  ForeignKeyConstraint = "workspace_port_share_links_workspace_id_fkey"                    // ALTER TABLE ONLY workspace_port_share_links ADD CONSTRAINT workspace_port_share_links_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceProxyTokenRotationsProxyID                 ForeignKeyConstraint = "workspace_proxy_token_rotations_proxy_id_fkey"                   // ALTER TABLE ONLY workspace_proxy_token_rotations ADD CONSTRAINT workspace_proxy_token_rotations_proxy_id_fkey FOREIGN KEY (proxy_id) REFERENCES workspace_proxies(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceResourceMetadataWorkspaceResourceID        ForeignKeyConstraint = "workspace_resource_metadata_workspace_resource_id_fkey"          // ALTER TABLE ONLY workspace_resource_metadata ADD CONSTRAINT workspace_resource_metadata_workspace_resource_id_fkey FOREIGN KEY (workspace_resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 	ForeignKeyWorkspaceResourcesJobID                             ForeignKeyConstraint = "workspace_resources_job_id_fkey"                                 // ALTER TABLE ONLY workspace_resources ADD CONSTRAINT workspace_resources_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceSchedulePausesCreatedBy                    ForeignKeyConstraint = "workspace_schedule_pauses_created_by_fkey"                       // ALTER TABLE ONLY workspace_schedule_pauses ADD CONSTRAINT workspace_schedule_pauses_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+	ForeignKeyWorkspaceSchedulePausesWorkspaceID                  ForeignKeyConstraint = "workspace_schedule_pauses_workspace_id_fkey"                     // ALTER TABLE ONLY workspace_schedule_pauses ADD CONSTRAINT workspace_schedule_pauses_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 	ForeignKeyWorkspacesOrganizationID                            ForeignKeyConstraint = "workspaces_organization_id_fkey"                                 // ALTER TABLE ONLY workspaces ADD CONSTRAINT workspaces_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT;
 	ForeignKeyWorkspacesOwnerID                                   ForeignKeyConstraint = "workspaces_owner_id_fkey"                                        // ALTER TABLE ONLY workspaces ADD CONSTRAINT workspaces_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE RESTRICT;
 	ForeignKeyWorkspacesTemplateID                                ForeignKeyConstraint = "workspaces_template_id_fkey"                                     // ALTER TABLE ONLY workspaces ADD CONSTRAINT workspaces_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE RESTRICT;
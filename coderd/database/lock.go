@@ -13,6 +13,9 @@ const (
 	LockIDNotificationsReportGenerator
 	LockIDCryptoKeyRotation
 	LockIDReconcilePrebuilds
+	LockIDLDAPSync
+	LockIDInternalCARotation
+	LockIDProvisionerJobAdmission
 )
 
 // GenLockID generates a unique and consistent lock ID from a given string.
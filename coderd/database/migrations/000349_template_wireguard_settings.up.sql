@@ -0,0 +1,59 @@
+-- Drop the view that depends on the templates table
+DROP VIEW template_with_names;
+
+ALTER TABLE templates
+	ADD COLUMN wireguard_mtu integer NOT NULL DEFAULT 0,
+	ADD COLUMN wireguard_keepalive_interval bigint NOT NULL DEFAULT 0,
+	ADD COLUMN wireguard_handshake_timeout bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN templates.wireguard_mtu IS 'Overrides the deployment WireGuard MTU for workspaces created from this template. 0 uses the deployment value.';
+
+COMMENT ON COLUMN templates.wireguard_keepalive_interval IS 'Overrides the deployment WireGuard keepalive interval, in nanoseconds. 0 uses the deployment value.';
+
+COMMENT ON COLUMN templates.wireguard_handshake_timeout IS 'Overrides the deployment WireGuard handshake timeout, in nanoseconds. 0 uses the deployment value.';
+
+-- Recreate the template_with_names view
+CREATE VIEW template_with_names AS
+	SELECT templates.id,
+		templates.created_at,
+		templates.updated_at,
+		templates.organization_id,
+		templates.deleted,
+		templates.name,
+		templates.provisioner,
+		templates.active_version_id,
+		templates.description,
+		templates.default_ttl,
+		templates.created_by,
+		templates.icon,
+		templates.user_acl,
+		templates.group_acl,
+		templates.display_name,
+		templates.allow_user_cancel_workspace_jobs,
+		templates.allow_user_autostart,
+		templates.allow_user_autostop,
+		templates.failure_ttl,
+		templates.time_til_dormant,
+		templates.time_til_dormant_autodelete,
+		templates.autostop_requirement_days_of_week,
+		templates.autostop_requirement_weeks,
+		templates.autostart_block_days_of_week,
+		templates.require_active_version,
+		templates.deprecated,
+		templates.activity_bump,
+		templates.max_port_sharing_level,
+		templates.use_classic_parameter_flow,
+		templates.wireguard_mtu,
+		templates.wireguard_keepalive_interval,
+		templates.wireguard_handshake_timeout,
+		COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
+		COALESCE(visible_users.username, ''::text) AS created_by_username,
+		COALESCE(visible_users.name, ''::text) AS created_by_name,
+		COALESCE(organizations.name, ''::text) AS organization_name,
+		COALESCE(organizations.display_name, ''::text) AS organization_display_name,
+		COALESCE(organizations.icon, ''::text) AS organization_icon
+	FROM ((templates
+	  LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
+	  LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
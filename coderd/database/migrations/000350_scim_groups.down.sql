@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS scim_nested_groups;
+DROP TABLE IF EXISTS scim_group_members;
+
+-- Enum values can't be dropped, so 'scim' remains in group_source.
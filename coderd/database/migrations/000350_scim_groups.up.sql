@@ -0,0 +1,18 @@
+ALTER TYPE group_source ADD VALUE IF NOT EXISTS 'scim';
+
+CREATE TABLE scim_group_members (
+	group_id uuid NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+	user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	PRIMARY KEY (group_id, user_id)
+);
+
+COMMENT ON TABLE scim_group_members IS 'Users the identity provider assigned to a SCIM group. Group members are derived from this, and from scim_nested_groups when nested groups are flattened.';
+
+CREATE TABLE scim_nested_groups (
+	group_id uuid NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+	member_group_id uuid NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+	PRIMARY KEY (group_id, member_group_id),
+	CONSTRAINT scim_nested_groups_not_self CHECK (group_id != member_group_id)
+);
+
+COMMENT ON TABLE scim_nested_groups IS 'Groups the identity provider assigned as members of a SCIM group.';
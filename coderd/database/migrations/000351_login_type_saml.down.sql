@@ -0,0 +1,2 @@
+-- It is not possible to drop enum values from enum types, so the UP on
+-- login_type has "IF NOT EXISTS".
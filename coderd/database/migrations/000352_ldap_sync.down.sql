@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS ldap_users;
+
+-- Enum values can't be dropped, so 'ldap' remains in group_source.
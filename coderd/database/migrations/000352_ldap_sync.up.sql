@@ -0,0 +1,13 @@
+ALTER TYPE group_source ADD VALUE IF NOT EXISTS 'ldap';
+
+CREATE TABLE ldap_users (
+	user_id uuid NOT NULL PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+	external_id text NOT NULL UNIQUE,
+	dn text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE ldap_users IS 'Users provisioned or linked by LDAP sync. Linked users are suspended when the directory stops returning them.';
+
+COMMENT ON COLUMN ldap_users.external_id IS 'Value of the directory attribute that identifies the user, such as objectGUID or entryUUID. Unlike the DN, it does not change when the user is renamed or moved.';
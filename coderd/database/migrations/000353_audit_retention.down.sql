@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS audit_log_legal_holds;
+
+DROP TABLE IF EXISTS organization_audit_settings;
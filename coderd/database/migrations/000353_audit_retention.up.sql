@@ -0,0 +1,22 @@
+CREATE TABLE organization_audit_settings (
+	organization_id uuid NOT NULL PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+	retention_days integer NOT NULL DEFAULT 0 CHECK (retention_days >= 0),
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON COLUMN organization_audit_settings.retention_days IS 'Number of days the audit logs of the organization are kept for. Zero keeps them forever.';
+
+CREATE TABLE audit_log_legal_holds (
+	id uuid NOT NULL PRIMARY KEY,
+	organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+	user_id uuid REFERENCES users(id) ON DELETE CASCADE,
+	workspace_id uuid REFERENCES workspaces(id) ON DELETE CASCADE,
+	reason text NOT NULL,
+	created_by uuid NOT NULL REFERENCES users(id),
+	created_at timestamp with time zone NOT NULL,
+	CONSTRAINT audit_log_legal_holds_target CHECK ((user_id IS NULL) <> (workspace_id IS NULL))
+);
+
+COMMENT ON TABLE audit_log_legal_holds IS 'Audit logs of the held user or workspace are never purged, regardless of the retention period of the organization.';
+
+CREATE INDEX audit_log_legal_holds_organization_id_idx ON audit_log_legal_holds USING btree (organization_id);
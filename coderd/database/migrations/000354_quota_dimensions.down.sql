@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS workspace_build_quota_costs;
+
+DROP TABLE IF EXISTS group_quota_allowances;
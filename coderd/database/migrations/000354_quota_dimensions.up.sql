@@ -0,0 +1,17 @@
+CREATE TABLE group_quota_allowances (
+	group_id uuid NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+	dimension text NOT NULL,
+	allowance bigint NOT NULL CHECK (allowance >= 0),
+	PRIMARY KEY (group_id, dimension)
+);
+
+COMMENT ON TABLE group_quota_allowances IS 'Allowances of quota dimensions other than the daily cost, such as cpu or memory. A dimension is only enforced in an organization if a group of the organization has an allowance for it.';
+
+CREATE TABLE workspace_build_quota_costs (
+	workspace_build_id uuid NOT NULL REFERENCES workspace_builds(id) ON DELETE CASCADE,
+	dimension text NOT NULL,
+	cost bigint NOT NULL,
+	PRIMARY KEY (workspace_build_id, dimension)
+);
+
+COMMENT ON TABLE workspace_build_quota_costs IS 'Costs of a workspace build in quota dimensions other than the daily cost.';
@@ -0,0 +1,8 @@
+CREATE TABLE license_seat_reservations (
+	group_id uuid NOT NULL PRIMARY KEY REFERENCES groups(id) ON DELETE CASCADE,
+	seats integer NOT NULL CHECK (seats > 0),
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE license_seat_reservations IS 'Licensed user seats reserved for the members of a group. Reservations are only used to forecast when the seats run out.';
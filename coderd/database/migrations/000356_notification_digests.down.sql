@@ -0,0 +1,10 @@
+DELETE FROM notification_templates WHERE id = '12f54ee2-d775-48b2-9d28-42991868bad4';
+
+ALTER TABLE notification_messages
+	DROP COLUMN IF EXISTS deliver_after,
+	DROP COLUMN IF EXISTS digest;
+
+ALTER TABLE notification_templates
+	DROP COLUMN IF EXISTS priority;
+
+DROP TYPE IF EXISTS notification_priority;
@@ -0,0 +1,51 @@
+CREATE TYPE notification_priority AS ENUM (
+	'low',
+	'normal',
+	'high'
+);
+
+ALTER TABLE notification_templates
+	ADD COLUMN priority notification_priority NOT NULL DEFAULT 'normal'::notification_priority;
+
+COMMENT ON COLUMN notification_templates.priority IS 'Low priority notifications can be batched into digests, high priority notifications are delivered during quiet hours.';
+
+ALTER TABLE notification_messages
+	ADD COLUMN deliver_after timestamp with time zone,
+	ADD COLUMN digest boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN notification_messages.deliver_after IS 'The message will not be dispatched before this time, used to hold messages back until the end of the user''s quiet hours.';
+
+COMMENT ON COLUMN notification_messages.digest IS 'The message is batched into the user''s next notification digest instead of being dispatched on its own.';
+
+UPDATE notification_templates
+SET priority = 'low'::notification_priority
+WHERE id IN (
+	'281fdf73-c6d6-4cbb-8ff5-888baf8a2fff', -- Workspace Created
+	'd089fe7b-d5c5-4c0c-aaf5-689859f7d392', -- Workspace Manually Updated
+	'c34a0c09-0704-4cac-bd1c-0c0146811c2b', -- Workspace Updated Automatically
+	'f40fae84-55a2-42cd-99fa-b41c1ca64894'  -- Template Deprecated
+);
+
+UPDATE notification_templates
+SET priority = 'high'::notification_priority
+WHERE id IN (
+	'62f86a30-2330-4b61-a26d-311ff3b608cf', -- One-Time Passcode
+	'c425f63e-716a-4bf4-ae24-78348f706c3f'  -- Troubleshooting Notification
+);
+
+INSERT INTO notification_templates
+	(id, name, title_template, body_template, "group", actions)
+VALUES ('12f54ee2-d775-48b2-9d28-42991868bad4',
+		'Notification Digest',
+		E'Your notification digest',
+		E'You received {{len .Data.notifications}} notification{{if gt (len .Data.notifications) 1}}s{{end}} since your last digest:
+{{range $notification := .Data.notifications}}
+- **{{$notification.title}}** ({{$notification.created_at}})
+{{end}}',
+		'Notification Events',
+		'[
+			{
+				"label": "View notification settings",
+				"url": "{{base_url}}/settings/notifications"
+			}
+		]'::jsonb);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS notification_category_preferences;
+
+ALTER TABLE notification_templates
+	DROP COLUMN IF EXISTS category;
+
+DROP TYPE IF EXISTS notification_category;
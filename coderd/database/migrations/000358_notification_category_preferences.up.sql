@@ -0,0 +1,63 @@
+CREATE TYPE notification_category AS ENUM (
+	'builds',
+	'dormancy',
+	'account',
+	'template_updates'
+);
+
+ALTER TABLE notification_templates
+	ADD COLUMN category notification_category;
+
+COMMENT ON COLUMN notification_templates.category IS 'Users can disable all notifications of a category, or only their delivery on some methods. NULL templates can only be disabled individually.';
+
+UPDATE notification_templates
+SET category = 'builds'::notification_category
+WHERE id IN (
+	'281fdf73-c6d6-4cbb-8ff5-888baf8a2fff', -- Workspace Created
+	'd089fe7b-d5c5-4c0c-aaf5-689859f7d392', -- Workspace Manually Updated
+	'f517da0b-cdc9-410f-ab89-a86107c420ed', -- Workspace Deleted
+	'381df2a9-c0c0-4749-420f-80a9280c66f9', -- Workspace Autobuild Failed
+	'c34a0c09-0704-4cac-bd1c-0c0146811c2b', -- Workspace Updated Automatically
+	'2faeee0f-26cb-4e96-821c-85ccb9f71513', -- Workspace Manual Build Failed
+	'89d9745a-816e-4695-a17f-3d0a229e2b8d', -- Prebuilt Workspace Resource Replaced
+	'34a20db2-e9cc-4a93-b0e4-8569699d7a00'  -- Report: Workspace Builds Failed For Template
+);
+
+UPDATE notification_templates
+SET category = 'dormancy'::notification_category
+WHERE id IN (
+	'0ea69165-ec14-4314-91f1-69566ac3c5a0', -- Workspace Marked as Dormant
+	'51ce2fdf-c9ca-4be1-8d70-628674f9bc42'  -- Workspace Marked for Deletion
+);
+
+-- The One-Time Passcode is deliberately left out, users must not be able to
+-- lock themselves out of resetting their password.
+UPDATE notification_templates
+SET category = 'account'::notification_category
+WHERE id IN (
+	'4e19c0ac-94e1-4532-9515-d1801aa283b2', -- User account created
+	'f44d9314-ad03-4bc8-95d0-5cad491da6b6', -- User account deleted
+	'b02ddd82-4733-4d02-a2d7-c36f3598997d', -- User account suspended
+	'9f5af851-8408-4e73-a7a1-c6502ba46689', -- User account activated
+	'6a2f0609-9b69-4d36-a989-9f5925b6cbff', -- Your account has been suspended
+	'1a6a6bea-ee0a-43e2-9e7c-eabdb53730e4'  -- Your account has been activated
+);
+
+UPDATE notification_templates
+SET category = 'template_updates'::notification_category
+WHERE id IN (
+	'29a09665-2a4c-403f-9648-54301670e7be', -- Template Deleted
+	'f40fae84-55a2-42cd-99fa-b41c1ca64894'  -- Template Deprecated
+);
+
+CREATE TABLE notification_category_preferences (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	category notification_category NOT NULL,
+	method notification_method NOT NULL,
+	disabled boolean NOT NULL DEFAULT false,
+	created_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (user_id, category, method)
+);
+
+COMMENT ON TABLE notification_category_preferences IS 'Per-user toggles for delivering the notifications of a category on a notification method.';
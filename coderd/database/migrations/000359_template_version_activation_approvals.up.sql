@@ -0,0 +1,107 @@
+-- Drop the view that depends on the templates table
+DROP VIEW template_with_names;
+
+ALTER TABLE templates
+	ADD COLUMN activation_approvals_required integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN templates.activation_approvals_required IS 'The number of reviewer approvals required before a template version can be made active. 0 disables the approval gate.';
+
+-- Recreate the template_with_names view
+CREATE VIEW template_with_names AS
+	SELECT templates.id,
+		templates.created_at,
+		templates.updated_at,
+		templates.organization_id,
+		templates.deleted,
+		templates.name,
+		templates.provisioner,
+		templates.active_version_id,
+		templates.description,
+		templates.default_ttl,
+		templates.created_by,
+		templates.icon,
+		templates.user_acl,
+		templates.group_acl,
+		templates.display_name,
+		templates.allow_user_cancel_workspace_jobs,
+		templates.allow_user_autostart,
+		templates.allow_user_autostop,
+		templates.failure_ttl,
+		templates.time_til_dormant,
+		templates.time_til_dormant_autodelete,
+		templates.autostop_requirement_days_of_week,
+		templates.autostop_requirement_weeks,
+		templates.autostart_block_days_of_week,
+		templates.require_active_version,
+		templates.deprecated,
+		templates.activity_bump,
+		templates.max_port_sharing_level,
+		templates.use_classic_parameter_flow,
+		templates.wireguard_mtu,
+		templates.wireguard_keepalive_interval,
+		templates.wireguard_handshake_timeout,
+		templates.activation_approvals_required,
+		COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
+		COALESCE(visible_users.username, ''::text) AS created_by_username,
+		COALESCE(visible_users.name, ''::text) AS created_by_name,
+		COALESCE(organizations.name, ''::text) AS organization_name,
+		COALESCE(organizations.display_name, ''::text) AS organization_display_name,
+		COALESCE(organizations.icon, ''::text) AS organization_icon
+	FROM ((templates
+	  LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
+	  LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
+
+CREATE TYPE template_version_activation_request_status AS ENUM (
+	'pending',
+	'approved',
+	'rejected',
+	'canceled'
+);
+
+CREATE TABLE template_version_activation_requests (
+	template_version_id uuid NOT NULL PRIMARY KEY REFERENCES template_versions (id) ON DELETE CASCADE,
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	requested_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	status template_version_activation_request_status NOT NULL DEFAULT 'pending'::template_version_activation_request_status,
+	approvals_required integer NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_version_activation_requests IS 'Requests to make a template version active, for templates which require approvals before activating a version.';
+
+COMMENT ON COLUMN template_version_activation_requests.approvals_required IS 'The number of approvals the template required when the activation was requested.';
+
+CREATE INDEX idx_template_version_activation_requests_template_id ON template_version_activation_requests USING btree (template_id);
+
+CREATE TABLE template_version_activation_reviews (
+	template_version_id uuid NOT NULL REFERENCES template_version_activation_requests (template_version_id) ON DELETE CASCADE,
+	reviewer_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	approved boolean NOT NULL,
+	comment text NOT NULL DEFAULT ''::text,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (template_version_id, reviewer_id)
+);
+
+COMMENT ON TABLE template_version_activation_reviews IS 'Approvals and rejections of template version activation requests.';
+
+-- Allow template version activation requests to be audited.
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'template_version_activation_request';
+
+INSERT INTO notification_templates
+	(id, name, title_template, body_template, "group", actions, category)
+VALUES ('79490ae9-2604-43ee-81eb-d840f20ed32a',
+		'Template Version Activation Requested',
+		E'Review requested for template \'{{.Labels.template}}\' version \'{{.Labels.version}}\'',
+		E'**{{.Labels.requester}}** requested to make version **{{.Labels.version}}** the active version of the template **{{.Labels.template}}**.\n\n' ||
+		E'The version is activated once it has been approved by {{.Labels.approvals_required}} reviewer{{if ne .Labels.approvals_required "1"}}s{{end}}.',
+		'Template Events',
+		'[
+			{
+				"label": "Review template version",
+				"url": "{{base_url}}/templates/{{.Labels.organization}}/{{.Labels.template}}/versions/{{.Labels.version}}"
+			}
+		]'::jsonb,
+		'template_updates'::notification_category);
@@ -0,0 +1,4 @@
+DELETE FROM notification_templates WHERE id = '4d10fb57-6760-46ea-b231-5682af398e02';
+
+DROP TABLE IF EXISTS template_version_rollouts;
+DROP TYPE IF EXISTS template_version_rollout_status;
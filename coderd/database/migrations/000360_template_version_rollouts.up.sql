@@ -0,0 +1,47 @@
+CREATE TYPE template_version_rollout_status AS ENUM (
+	'in_progress',
+	'halted',
+	'completed',
+	'canceled'
+);
+
+CREATE TABLE template_version_rollouts (
+	template_id uuid NOT NULL PRIMARY KEY REFERENCES templates (id) ON DELETE CASCADE,
+	template_version_id uuid NOT NULL REFERENCES template_versions (id) ON DELETE CASCADE,
+	created_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	percentage integer NOT NULL CHECK (percentage >= 0 AND percentage <= 100),
+	group_ids uuid[] NOT NULL DEFAULT '{}'::uuid[],
+	failure_threshold_percent integer NOT NULL CHECK (failure_threshold_percent >= 0 AND failure_threshold_percent <= 100),
+	minimum_builds integer NOT NULL CHECK (minimum_builds > 0),
+	status template_version_rollout_status NOT NULL DEFAULT 'in_progress'::template_version_rollout_status,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_version_rollouts IS 'Gradual rollouts of template versions to a cohort of workspaces before the version is made active for every workspace. A template has at most one rollout, starting a new one replaces the previous one.';
+
+COMMENT ON COLUMN template_version_rollouts.percentage IS 'The percentage of the workspaces of the template that are built with the rolled out version.';
+
+COMMENT ON COLUMN template_version_rollouts.group_ids IS 'The groups whose members'' workspaces are built with the rolled out version, regardless of the percentage.';
+
+COMMENT ON COLUMN template_version_rollouts.failure_threshold_percent IS 'The rollout is halted once the percentage of failed builds of the rolled out version exceeds this threshold.';
+
+COMMENT ON COLUMN template_version_rollouts.minimum_builds IS 'The number of builds of the rolled out version required before the failure threshold is evaluated.';
+
+CREATE INDEX idx_template_version_rollouts_template_version_id ON template_version_rollouts USING btree (template_version_id);
+
+INSERT INTO notification_templates
+	(id, name, title_template, body_template, "group", actions, category)
+VALUES ('4d10fb57-6760-46ea-b231-5682af398e02',
+		'Template Version Rollout Halted',
+		E'Rollout of template \'{{.Labels.template}}\' version \'{{.Labels.version}}\' halted',
+		E'The rollout of version **{{.Labels.version}}** of the template **{{.Labels.template}}** was halted because {{.Labels.failed_builds}} of its {{.Labels.total_builds}} workspace builds failed, exceeding the failure threshold of {{.Labels.failure_threshold}}%.\n\n' ||
+		E'Workspaces are built with the active version of the template until the rollout is started again.',
+		'Template Events',
+		'[
+			{
+				"label": "View template version",
+				"url": "{{base_url}}/templates/{{.Labels.organization}}/{{.Labels.template}}/versions/{{.Labels.version}}"
+			}
+		]'::jsonb,
+		'template_updates'::notification_category);
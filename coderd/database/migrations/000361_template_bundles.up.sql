@@ -0,0 +1,16 @@
+CREATE TABLE template_bundles (
+	id uuid NOT NULL,
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	version text NOT NULL,
+	hash character varying(64) NOT NULL,
+	manifest jsonb NOT NULL,
+	data bytea NOT NULL,
+	created_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE (organization_id, name, version)
+);
+
+COMMENT ON TABLE template_bundles IS 'Self-contained template archives, including the modules and providers they require, that can be pushed to and pulled from a deployment without internet access.';
+COMMENT ON COLUMN template_bundles.manifest IS 'The manifest of the bundle, describing the providers and modules it contains.';
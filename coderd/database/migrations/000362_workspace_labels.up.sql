@@ -0,0 +1,10 @@
+CREATE TABLE workspace_labels (
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	key text NOT NULL,
+	value text NOT NULL,
+	PRIMARY KEY (workspace_id, key)
+);
+
+CREATE INDEX workspace_labels_key_value_idx ON workspace_labels (key, value);
+
+COMMENT ON TABLE workspace_labels IS 'Free-form key/value labels used to group workspaces, e.g. by project or cost center, and to target them in searches and bulk operations.';
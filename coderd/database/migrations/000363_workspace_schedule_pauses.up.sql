@@ -0,0 +1,9 @@
+CREATE TABLE workspace_schedule_pauses (
+	workspace_id uuid NOT NULL PRIMARY KEY REFERENCES workspaces (id) ON DELETE CASCADE,
+	paused_until timestamp with time zone NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	created_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE workspace_schedule_pauses IS 'Bounded pauses of the autostop, dormancy and auto-delete schedules of workspaces, e.g. while their owners are on leave.';
+COMMENT ON COLUMN workspace_schedule_pauses.paused_until IS 'The pause ends at this time. Expired pauses are kept so the end of the pause can count as workspace activity for dormancy.';
@@ -0,0 +1,85 @@
+ALTER TYPE build_reason ADD VALUE IF NOT EXISTS 'autoarchive';
+
+-- Drop the view that depends on the templates table
+DROP VIEW template_with_names;
+
+ALTER TABLE templates
+	ADD COLUMN dormant_archive_paths text[] NOT NULL DEFAULT '{}'::text[];
+
+COMMENT ON COLUMN templates.dormant_archive_paths IS 'Paths in workspaces of this template that are archived to object storage before dormant workspaces are auto-deleted. Relative paths are relative to the home directory of the agent.';
+
+-- Recreate the template_with_names view
+CREATE VIEW template_with_names AS
+	SELECT templates.id,
+		templates.created_at,
+		templates.updated_at,
+		templates.organization_id,
+		templates.deleted,
+		templates.name,
+		templates.provisioner,
+		templates.active_version_id,
+		templates.description,
+		templates.default_ttl,
+		templates.created_by,
+		templates.icon,
+		templates.user_acl,
+		templates.group_acl,
+		templates.display_name,
+		templates.allow_user_cancel_workspace_jobs,
+		templates.allow_user_autostart,
+		templates.allow_user_autostop,
+		templates.failure_ttl,
+		templates.time_til_dormant,
+		templates.time_til_dormant_autodelete,
+		templates.autostop_requirement_days_of_week,
+		templates.autostop_requirement_weeks,
+		templates.autostart_block_days_of_week,
+		templates.require_active_version,
+		templates.deprecated,
+		templates.activity_bump,
+		templates.max_port_sharing_level,
+		templates.use_classic_parameter_flow,
+		templates.wireguard_mtu,
+		templates.wireguard_keepalive_interval,
+		templates.wireguard_handshake_timeout,
+		templates.activation_approvals_required,
+		templates.autostart_holidays,
+		templates.autostart_holiday_calendar_url,
+		templates.dormant_archive_paths,
+		COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
+		COALESCE(visible_users.username, ''::text) AS created_by_username,
+		COALESCE(visible_users.name, ''::text) AS created_by_name,
+		COALESCE(organizations.name, ''::text) AS organization_name,
+		COALESCE(organizations.display_name, ''::text) AS organization_display_name,
+		COALESCE(organizations.icon, ''::text) AS organization_icon
+	FROM ((templates
+	  LEFT JOIN visible_users ON ((templates.created_by = visible_users.id)))
+	  LEFT JOIN organizations ON ((templates.organization_id = organizations.id)));
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
+
+CREATE TABLE workspace_archives (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	workspace_name text NOT NULL,
+	owner_id uuid NOT NULL,
+	organization_id uuid NOT NULL,
+	template_id uuid NOT NULL,
+	paths text[] NOT NULL,
+	object_key text NOT NULL,
+	size_bytes bigint NOT NULL,
+	error text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_archives IS 'Archives of the persistent paths of dormant workspaces taken before the workspaces are auto-deleted.';
+COMMENT ON COLUMN workspace_archives.object_key IS 'Key of the archive in the archive store. Empty if the archive failed.';
+COMMENT ON COLUMN workspace_archives.error IS 'Why the archive failed. Empty if the archive succeeded.';
+COMMENT ON COLUMN workspace_archives.expires_at IS 'The archive is deleted from the archive store after this time.';
+
+CREATE INDEX workspace_archives_workspace_id_created_at_idx ON workspace_archives USING btree (workspace_id, created_at DESC);
+
+CREATE INDEX workspace_archives_owner_id_idx ON workspace_archives USING btree (owner_id);
+
+CREATE INDEX workspace_archives_expires_at_idx ON workspace_archives USING btree (expires_at);
@@ -0,0 +1,17 @@
+CREATE TABLE template_parameter_option_sources (
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	parameter_name text NOT NULL,
+	url text NOT NULL,
+	auth_header_name text NOT NULL DEFAULT ''::text,
+	auth_header_value text NOT NULL DEFAULT ''::text,
+	cache_ttl bigint NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (template_id, parameter_name)
+);
+
+COMMENT ON TABLE template_parameter_option_sources IS 'APIs from which the options of template parameters are fetched when the parameter form is rendered, instead of being defined in the template.';
+
+COMMENT ON COLUMN template_parameter_option_sources.auth_header_value IS 'The value of the header that coderd adds to requests to the API. It is never returned to users.';
+
+COMMENT ON COLUMN template_parameter_option_sources.cache_ttl IS 'How long fetched options are cached, in nanoseconds.';
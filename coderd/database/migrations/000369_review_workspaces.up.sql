@@ -0,0 +1,12 @@
+CREATE TABLE review_workspaces (
+	workspace_id uuid NOT NULL PRIMARY KEY REFERENCES workspaces (id) ON DELETE CASCADE,
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	repository text NOT NULL,
+	branch text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	UNIQUE (organization_id, repository, branch)
+);
+
+COMMENT ON TABLE review_workspaces IS 'Workspaces created by CI for a branch of a repository. They are deleted when the branch is deleted.';
+COMMENT ON COLUMN review_workspaces.repository IS 'Lowercased full name of the repository, e.g. "coder/coder".';
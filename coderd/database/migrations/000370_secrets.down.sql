@@ -0,0 +1,4 @@
+-- It's not possible to drop enum values from enum types, so the up migration has "IF NOT EXISTS".
+
+DROP TABLE IF EXISTS template_secrets;
+DROP TABLE IF EXISTS user_secrets;
@@ -0,0 +1,39 @@
+CREATE TABLE user_secrets (
+	id uuid NOT NULL PRIMARY KEY,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	description text NOT NULL DEFAULT '',
+	value text NOT NULL,
+	value_key_id text REFERENCES dbcrypt_keys (active_key_digest),
+	env_name text NOT NULL DEFAULT '',
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	UNIQUE (user_id, name)
+);
+
+CREATE UNIQUE INDEX user_secrets_user_id_env_name_idx ON user_secrets (user_id, env_name) WHERE env_name != '';
+
+COMMENT ON TABLE user_secrets IS 'Secrets owned by a user. Secrets with an environment variable name can be read by the agents of the user''s workspaces.';
+COMMENT ON COLUMN user_secrets.value_key_id IS 'The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted';
+COMMENT ON COLUMN user_secrets.env_name IS 'The name of the environment variable the secret is exposed as in workspaces. Empty if it is not exposed.';
+
+CREATE TABLE template_secrets (
+	id uuid NOT NULL PRIMARY KEY,
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	description text NOT NULL DEFAULT '',
+	value text NOT NULL,
+	value_key_id text REFERENCES dbcrypt_keys (active_key_digest),
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	UNIQUE (template_id, name)
+);
+
+COMMENT ON TABLE template_secrets IS 'Secrets of a template. They are passed to workspace builds as the value of the Terraform variable with the same name.';
+COMMENT ON COLUMN template_secrets.value_key_id IS 'The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted';
+
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'user_secret';
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'template_secret';
+
+-- Reading a secret's value is audited.
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'read';
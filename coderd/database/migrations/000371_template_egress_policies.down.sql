@@ -0,0 +1,3 @@
+-- It's not possible to drop enum values from enum types, so the up migration has "IF NOT EXISTS".
+
+DROP TABLE IF EXISTS template_egress_policies;
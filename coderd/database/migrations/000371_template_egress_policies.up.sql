@@ -0,0 +1,16 @@
+CREATE TABLE template_egress_policies (
+	template_id uuid NOT NULL PRIMARY KEY REFERENCES templates (id) ON DELETE CASCADE,
+	allowed_cidrs text[] NOT NULL DEFAULT '{}'::text[],
+	allowed_domains text[] NOT NULL DEFAULT '{}'::text[],
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_egress_policies IS 'Destinations the workspaces of a template may connect to. Agents block all other outbound traffic on the workspace host while a template has a policy.';
+COMMENT ON COLUMN template_egress_policies.allowed_cidrs IS 'IP addresses and CIDR ranges workspaces may connect to.';
+COMMENT ON COLUMN template_egress_policies.allowed_domains IS 'Domain names workspaces may connect to. Agents resolve them periodically and allow the resulting addresses.';
+
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'template_egress_policy';
+
+-- Connections blocked by an egress policy are reported by agents and audited.
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'egress_violation';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS oauth2_provider_app_device_codes;
@@ -0,0 +1,18 @@
+CREATE TABLE oauth2_provider_app_device_codes (
+	id uuid NOT NULL PRIMARY KEY,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	device_code_prefix bytea NOT NULL UNIQUE,
+	device_code_hash bytea NOT NULL,
+	user_code text NOT NULL UNIQUE,
+	app_id uuid NOT NULL REFERENCES oauth2_provider_apps (id) ON DELETE CASCADE,
+	user_id uuid REFERENCES users (id) ON DELETE CASCADE,
+	resource_uri text,
+	last_polled_at timestamp with time zone
+);
+
+COMMENT ON TABLE oauth2_provider_app_device_codes IS 'RFC 8628 device codes. Devices poll the token endpoint with the device code until a user approves the user code.';
+COMMENT ON COLUMN oauth2_provider_app_device_codes.user_code IS 'The short code users enter to approve the device, without separators.';
+COMMENT ON COLUMN oauth2_provider_app_device_codes.user_id IS 'The user who approved the device, null while pending.';
+COMMENT ON COLUMN oauth2_provider_app_device_codes.resource_uri IS 'RFC 8707 resource parameter for audience restriction';
+COMMENT ON COLUMN oauth2_provider_app_device_codes.last_polled_at IS 'Used to tell devices polling too often to slow down.';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS organization_mfa_settings;
+DROP TABLE IF EXISTS user_mfa_recovery_codes;
+DROP TABLE IF EXISTS user_webauthn_credentials;
+DROP TABLE IF EXISTS webauthn_challenges;
+DROP TYPE IF EXISTS webauthn_challenge_purpose;
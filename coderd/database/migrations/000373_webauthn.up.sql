@@ -0,0 +1,55 @@
+CREATE TYPE webauthn_challenge_purpose AS ENUM (
+	'registration',
+	'login',
+	'enrollment'
+);
+
+COMMENT ON TYPE webauthn_challenge_purpose IS 'registration challenges add a credential for a signed in user, login challenges complete a password login with an existing credential, and enrollment challenges complete a password login by adding the first credential of a user.';
+
+CREATE TABLE webauthn_challenges (
+	id uuid NOT NULL PRIMARY KEY,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	purpose webauthn_challenge_purpose NOT NULL,
+	challenge bytea NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE webauthn_challenges IS 'Pending WebAuthn ceremonies. Challenges are deleted when they are used.';
+
+CREATE INDEX webauthn_challenges_expires_at_idx ON webauthn_challenges USING btree (expires_at);
+
+CREATE TABLE user_webauthn_credentials (
+	id uuid NOT NULL PRIMARY KEY,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	credential_id bytea NOT NULL UNIQUE,
+	public_key bytea NOT NULL,
+	sign_count bigint NOT NULL DEFAULT 0,
+	created_at timestamp with time zone NOT NULL,
+	last_used_at timestamp with time zone
+);
+
+COMMENT ON TABLE user_webauthn_credentials IS 'WebAuthn credentials, such as security keys and passkeys, used as a second factor for password logins.';
+COMMENT ON COLUMN user_webauthn_credentials.public_key IS 'The COSE encoded public key of the credential.';
+COMMENT ON COLUMN user_webauthn_credentials.sign_count IS 'The last signature counter reported by the authenticator, used to detect cloned authenticators.';
+
+CREATE INDEX user_webauthn_credentials_user_id_idx ON user_webauthn_credentials USING btree (user_id);
+
+CREATE TABLE user_mfa_recovery_codes (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	hashed_code bytea NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	used_at timestamp with time zone,
+	PRIMARY KEY (user_id, hashed_code)
+);
+
+COMMENT ON TABLE user_mfa_recovery_codes IS 'Single use codes that replace a WebAuthn credential when completing a password login.';
+
+CREATE TABLE organization_mfa_settings (
+	organization_id uuid NOT NULL PRIMARY KEY REFERENCES organizations (id) ON DELETE CASCADE,
+	require_webauthn boolean NOT NULL DEFAULT false,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON COLUMN organization_mfa_settings.require_webauthn IS 'Members of the organization that log in with a password must use a WebAuthn credential as a second factor.';
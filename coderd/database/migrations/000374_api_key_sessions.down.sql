@@ -0,0 +1,3 @@
+ALTER TABLE api_keys
+	DROP COLUMN IF EXISTS device_fingerprint,
+	DROP COLUMN IF EXISTS user_agent;
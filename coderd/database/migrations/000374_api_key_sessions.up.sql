@@ -0,0 +1,9 @@
+ALTER TABLE api_keys
+	ADD COLUMN user_agent text NOT NULL DEFAULT '',
+	ADD COLUMN device_fingerprint text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN api_keys.user_agent
+IS 'The User-Agent header of the request that created the key.';
+
+COMMENT ON COLUMN api_keys.device_fingerprint
+IS 'A hash of the request headers that identify the device that created the key, used to tell sessions apart.';
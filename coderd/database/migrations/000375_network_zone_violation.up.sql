@@ -0,0 +1,2 @@
+-- Requests rejected by a network zone are audited.
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'network_zone_violation';
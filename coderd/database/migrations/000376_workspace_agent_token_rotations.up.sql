@@ -0,0 +1,10 @@
+CREATE TABLE workspace_agent_token_rotations (
+	agent_id uuid NOT NULL PRIMARY KEY REFERENCES workspace_agents (id) ON DELETE CASCADE,
+	previous_auth_token uuid NOT NULL,
+	previous_auth_token_expires_at timestamp with time zone NOT NULL,
+	rotated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_token_rotations IS 'The last auth token rotation of each workspace agent. The previous token keeps authenticating the agent until it expires.';
+
+CREATE INDEX workspace_agent_token_rotations_previous_auth_token_idx ON workspace_agent_token_rotations USING btree (previous_auth_token);
@@ -0,0 +1,16 @@
+CREATE TABLE health_scores (
+	replica_id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	score integer NOT NULL,
+	database_score integer NOT NULL,
+	database_latency_ms bigint NOT NULL,
+	derp_score integer NOT NULL,
+	provisioner_daemons_score integer NOT NULL,
+	replicas_score integer NOT NULL,
+	websocket_score integer NOT NULL,
+	PRIMARY KEY (replica_id, created_at)
+);
+
+COMMENT ON TABLE health_scores IS 'Scores of the health checks that each replica runs periodically. Scores range from 0 (failing) to 100 (healthy).';
+
+CREATE INDEX health_scores_created_at_idx ON health_scores USING btree (created_at);
@@ -0,0 +1 @@
+DELETE FROM notification_templates WHERE id = '306a35f5-b7e7-47e9-a84e-5962ab97fbf0';
@@ -0,0 +1,23 @@
+INSERT INTO notification_templates
+	(id, name, title_template, body_template, "group", actions, enabled_by_default)
+VALUES ('306a35f5-b7e7-47e9-a84e-5962ab97fbf0',
+		'Report: Workspace Usage',
+		E'Workspace usage report',
+		E'Workspaces ran for **{{.Data.runtime_hours}} hours** and accrued **{{.Data.cost}}** quota credits between {{.Data.start_date}} and {{.Data.end_date}}.\n\n' ||
+		E'**Top users:**\n' ||
+		E'{{range $user := .Data.users}}\n- **{{$user.username}}**: {{$user.runtime_hours}} hours, {{$user.cost}} credits\n{{end}}\n' ||
+		E'**Top groups:**\n' ||
+		E'{{range $group := .Data.groups}}\n- **{{$group.name}}** ({{$group.organization}}): {{$group.runtime_hours}} hours, {{$group.cost}} credits\n{{end}}\n' ||
+		E'Download the full reports for chargeback and capacity planning.',
+		'Workspace Events',
+		'[
+			{
+				"label": "Download users CSV",
+				"url": "{{base_url}}/api/v2/insights/workspace-usage?group_by=user&format=csv&start_time={{.Data.start_time}}&end_time={{.Data.end_time}}"
+			},
+			{
+				"label": "Download groups CSV",
+				"url": "{{base_url}}/api/v2/insights/workspace-usage?group_by=group&format=csv&start_time={{.Data.start_time}}&end_time={{.Data.end_time}}"
+			}
+		]'::jsonb,
+		FALSE);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_workspace_builds_created_at;
+
+DROP TRIGGER IF EXISTS workspace_agent_lifecycle_event_trigger ON workspace_agents;
+
+DROP FUNCTION IF EXISTS record_workspace_agent_lifecycle_event();
+
+DROP TABLE IF EXISTS workspace_agent_lifecycle_events;
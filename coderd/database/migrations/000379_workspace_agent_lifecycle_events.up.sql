@@ -0,0 +1,34 @@
+CREATE TABLE workspace_agent_lifecycle_events (
+	id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	workspace_agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	lifecycle_state workspace_agent_lifecycle_state NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE workspace_agent_lifecycle_events IS 'Tracks the history of workspace agent lifecycle state transitions';
+
+CREATE INDEX idx_workspace_agent_lifecycle_events_created_at ON workspace_agent_lifecycle_events USING btree (created_at DESC);
+
+CREATE OR REPLACE FUNCTION record_workspace_agent_lifecycle_event() RETURNS trigger AS $$
+BEGIN
+	IF OLD.lifecycle_state IS DISTINCT FROM NEW.lifecycle_state THEN
+		INSERT INTO workspace_agent_lifecycle_events (
+			workspace_agent_id,
+			lifecycle_state
+		) VALUES (
+			NEW.id,
+			NEW.lifecycle_state
+		);
+	END IF;
+
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workspace_agent_lifecycle_event_trigger
+	AFTER UPDATE OF lifecycle_state ON workspace_agents
+	FOR EACH ROW
+	EXECUTE FUNCTION record_workspace_agent_lifecycle_event();
+
+-- The activity feed pages builds by creation time.
+CREATE INDEX idx_workspace_builds_created_at ON workspace_builds USING btree (created_at DESC);
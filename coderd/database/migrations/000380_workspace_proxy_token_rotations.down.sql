@@ -0,0 +1 @@
+DROP TABLE IF EXISTS workspace_proxy_token_rotations;
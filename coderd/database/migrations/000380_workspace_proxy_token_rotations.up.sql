@@ -0,0 +1,8 @@
+CREATE TABLE workspace_proxy_token_rotations (
+	proxy_id uuid NOT NULL PRIMARY KEY REFERENCES workspace_proxies (id) ON DELETE CASCADE,
+	previous_token_hashed_secret bytea NOT NULL,
+	previous_token_expires_at timestamp with time zone NOT NULL,
+	rotated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_proxy_token_rotations IS 'The last token rotation of each workspace proxy. The previous token keeps authenticating the proxy until it expires.';
@@ -0,0 +1,20 @@
+CREATE TABLE workspace_adoptions (
+	id uuid NOT NULL PRIMARY KEY,
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	owner_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	workspace_name text NOT NULL,
+	hashed_secret bytea NOT NULL,
+	created_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	workspace_id uuid REFERENCES workspaces (id) ON DELETE CASCADE,
+	hostname text NOT NULL DEFAULT '',
+	adopted_at timestamp with time zone
+);
+
+CREATE UNIQUE INDEX workspace_adoptions_workspace_id_idx ON workspace_adoptions USING btree (workspace_id);
+
+COMMENT ON TABLE workspace_adoptions IS 'Machines that are not managed by a provisioner and were adopted as workspaces. A row is created with a one-time token that the machine exchanges for a workspace and an agent token.';
+
+COMMENT ON COLUMN workspace_adoptions.workspace_id IS 'The workspace created when the machine was adopted. NULL until the token is used.';
@@ -0,0 +1,56 @@
+CREATE TABLE organization_setting_overrides (
+	organization_id uuid NOT NULL PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+	default_ttl bigint CHECK (default_ttl >= 0),
+	default_quiet_hours_schedule text,
+	allowed_external_auth_providers text[],
+	disabled_notification_template_ids uuid[] NOT NULL DEFAULT '{}',
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE organization_setting_overrides IS 'Overrides of deployment settings for a single organization. NULL columns inherit the deployment setting.';
+
+COMMENT ON COLUMN organization_setting_overrides.default_ttl IS 'Default autostop in nanoseconds for templates created in the organization without one.';
+
+COMMENT ON COLUMN organization_setting_overrides.default_quiet_hours_schedule IS 'Quiet hours schedule used for workspaces in the organization whose owner has not set their own schedule.';
+
+COMMENT ON COLUMN organization_setting_overrides.allowed_external_auth_providers IS 'IDs of the external auth providers templates in the organization may require.';
+
+COMMENT ON COLUMN organization_setting_overrides.disabled_notification_template_ids IS 'Notification templates that are never sent for workspaces and templates in the organization.';
+
+CREATE OR REPLACE FUNCTION inhibit_enqueue_if_disabled()
+	RETURNS TRIGGER AS
+$$
+BEGIN
+	-- Fail the insertion if one of the following:
+	--  * the user has disabled this notification.
+	--  * the notification template is disabled by default and hasn't
+	--    been explicitly enabled by the user.
+	--  * the organization of a targeted workspace or template has disabled
+	--    this notification.
+	IF EXISTS (
+		SELECT 1 FROM notification_templates
+		LEFT JOIN notification_preferences
+			ON  notification_preferences.notification_template_id = notification_templates.id
+			AND notification_preferences.user_id = NEW.user_id
+		WHERE notification_templates.id = NEW.notification_template_id AND (
+			-- Case 1: The user has explicitly disabled this template
+			notification_preferences.disabled = TRUE
+			OR
+			-- Case 2: The template is disabled by default AND the user hasn't enabled it
+			(notification_templates.enabled_by_default = FALSE AND notification_preferences.notification_template_id IS NULL)
+		)
+	) OR EXISTS (
+		SELECT 1 FROM organization_setting_overrides
+		WHERE NEW.notification_template_id = ANY(organization_setting_overrides.disabled_notification_template_ids)
+		AND organization_setting_overrides.organization_id IN (
+			SELECT organization_id FROM workspaces WHERE id = ANY(NEW.targets)
+			UNION
+			SELECT organization_id FROM templates WHERE id = ANY(NEW.targets)
+		)
+	) THEN
+		RAISE EXCEPTION 'cannot enqueue message: notification is not enabled';
+	END IF;
+
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
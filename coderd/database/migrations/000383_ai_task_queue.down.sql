@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS ai_task_queue;
+
+DROP TYPE IF EXISTS ai_task_queue_status;
@@ -0,0 +1,24 @@
+CREATE TYPE ai_task_queue_status AS ENUM (
+	'queued',
+	'failed'
+);
+
+CREATE TABLE ai_task_queue (
+	id uuid NOT NULL PRIMARY KEY,
+	owner_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+	template_version_id uuid NOT NULL REFERENCES template_versions(id) ON DELETE CASCADE,
+	template_version_preset_id uuid REFERENCES template_version_presets(id) ON DELETE SET NULL,
+	name text NOT NULL,
+	prompt text NOT NULL,
+	status ai_task_queue_status NOT NULL DEFAULT 'queued',
+	error text NOT NULL DEFAULT '',
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE ai_task_queue IS 'AI tasks that wait for a running task of their owner to stop. Tasks are removed from the queue when their workspace is created.';
+
+COMMENT ON COLUMN ai_task_queue.error IS 'Why the workspace of the task could not be created, if the status is failed.';
+
+CREATE INDEX ai_task_queue_owner_id_created_at_idx ON ai_task_queue USING btree (owner_id, created_at);
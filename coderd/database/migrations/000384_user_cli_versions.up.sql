@@ -0,0 +1,7 @@
+CREATE TABLE user_cli_versions (
+	user_id uuid NOT NULL PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+	version text NOT NULL,
+	last_seen_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_cli_versions IS 'The version of the CLI each user last made an API request with.';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS template_app_restrictions;
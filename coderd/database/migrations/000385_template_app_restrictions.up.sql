@@ -0,0 +1,15 @@
+CREATE TABLE template_app_restrictions (
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	app_slug text NOT NULL,
+	group_ids uuid[] NOT NULL DEFAULT '{}'::uuid[],
+	roles text[] NOT NULL DEFAULT '{}'::text[],
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (template_id, app_slug)
+);
+
+COMMENT ON TABLE template_app_restrictions IS 'Workspace apps of a template that are only visible and accessible to members of some groups or users with some roles.';
+
+COMMENT ON COLUMN template_app_restrictions.group_ids IS 'The groups whose members can access the app.';
+
+COMMENT ON COLUMN template_app_restrictions.roles IS 'The names of the site roles and roles of the template organization whose users can access the app.';
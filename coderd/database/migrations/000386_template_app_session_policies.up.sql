@@ -0,0 +1,14 @@
+CREATE TABLE template_app_session_policies (
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	app_slug text NOT NULL,
+	max_session_duration bigint NOT NULL CHECK (max_session_duration > 0),
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (template_id, app_slug)
+);
+
+COMMENT ON TABLE template_app_session_policies IS 'How long after authenticating users can access the workspace apps of a template before they must authenticate again.';
+
+COMMENT ON COLUMN template_app_session_policies.app_slug IS 'The slug of the app the policy applies to. The policy with an empty slug applies to all apps, ports and terminals of the template without their own policy.';
+
+COMMENT ON COLUMN template_app_session_policies.max_session_duration IS 'The maximum time since the user authenticated, in nanoseconds.';
@@ -0,0 +1,24 @@
+CREATE TABLE workspace_port_share_links (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	agent_name text NOT NULL,
+	port integer NOT NULL,
+	protocol port_share_protocol NOT NULL DEFAULT 'http'::port_share_protocol,
+	hashed_secret bytea NOT NULL,
+	hashed_password text NOT NULL DEFAULT '',
+	bandwidth_limit_bytes bigint NOT NULL DEFAULT 0 CHECK (bandwidth_limit_bytes >= 0),
+	bytes_transferred bigint NOT NULL DEFAULT 0,
+	expires_at timestamp with time zone NOT NULL,
+	created_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX workspace_port_share_links_workspace_id_idx ON workspace_port_share_links USING btree (workspace_id);
+
+COMMENT ON TABLE workspace_port_share_links IS 'Time-limited links that give anyone access to a workspace port, optionally protected by a password.';
+
+COMMENT ON COLUMN workspace_port_share_links.hashed_password IS 'The hashed password of the link, or empty if the link does not require a password.';
+
+COMMENT ON COLUMN workspace_port_share_links.bandwidth_limit_bytes IS 'The number of bytes that can be transferred through the link, or 0 if it is not limited.';
+
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'workspace_port_share_link';
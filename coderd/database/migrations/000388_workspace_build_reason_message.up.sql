@@ -0,0 +1,55 @@
+ALTER TABLE workspace_builds
+	ADD COLUMN reason_message text NOT NULL DEFAULT '',
+	ADD COLUMN initiator_api_key_name text NOT NULL DEFAULT '',
+	ADD COLUMN initiator_ci_job_url text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN workspace_builds.reason_message IS 'Free-form explanation of why the build was started, given by the initiator.';
+
+COMMENT ON COLUMN workspace_builds.initiator_api_key_name IS 'The name of the API token the build was started with, if any.';
+
+COMMENT ON COLUMN workspace_builds.initiator_ci_job_url IS 'The URL of the CI job that started the build, if any.';
+
+DROP VIEW workspace_build_with_user;
+
+CREATE VIEW workspace_build_with_user AS
+SELECT
+    workspace_builds.id,
+    workspace_builds.created_at,
+    workspace_builds.updated_at,
+    workspace_builds.workspace_id,
+    workspace_builds.template_version_id,
+    workspace_builds.build_number,
+    workspace_builds.transition,
+    workspace_builds.initiator_id,
+    workspace_builds.provisioner_state,
+    workspace_builds.job_id,
+    workspace_builds.deadline,
+    workspace_builds.reason,
+    workspace_builds.daily_cost,
+    workspace_builds.max_deadline,
+    workspace_builds.template_version_preset_id,
+    workspace_builds.has_ai_task,
+    workspace_builds.ai_task_sidebar_app_id,
+    workspace_builds.reason_message,
+    workspace_builds.initiator_api_key_name,
+    workspace_builds.initiator_ci_job_url,
+    COALESCE(
+        visible_users.avatar_url,
+        '' :: text
+    ) AS initiator_by_avatar_url,
+    COALESCE(
+        visible_users.username,
+        '' :: text
+    ) AS initiator_by_username,
+    COALESCE(visible_users.name, '' :: text) AS initiator_by_name
+FROM
+    (
+        workspace_builds
+        LEFT JOIN visible_users ON (
+            (
+                workspace_builds.initiator_id = visible_users.id
+            )
+        )
+    );
+
+COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
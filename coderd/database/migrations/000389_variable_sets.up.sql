@@ -0,0 +1,26 @@
+CREATE TABLE variable_sets (
+	id uuid NOT NULL PRIMARY KEY,
+	name text NOT NULL UNIQUE,
+	description text NOT NULL DEFAULT '',
+	variables text NOT NULL,
+	variables_key_id text REFERENCES dbcrypt_keys (active_key_digest),
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE variable_sets IS 'Named sets of Terraform variable values that can be attached to templates.';
+COMMENT ON COLUMN variable_sets.variables IS 'A JSON array of the variables in the set, including their values.';
+COMMENT ON COLUMN variable_sets.variables_key_id IS 'The ID of the key used to encrypt the variables. If this is NULL, the variables are not encrypted';
+
+CREATE TABLE template_variable_sets (
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	variable_set_id uuid NOT NULL REFERENCES variable_sets (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (template_id, variable_set_id)
+);
+
+CREATE INDEX template_variable_sets_variable_set_id_idx ON template_variable_sets USING btree (variable_set_id);
+
+COMMENT ON TABLE template_variable_sets IS 'Variable sets attached to a template. Their values are passed to the provisioner jobs of the template.';
+
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'variable_set';
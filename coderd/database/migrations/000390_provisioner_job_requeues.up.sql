@@ -0,0 +1,10 @@
+CREATE TABLE provisioner_job_requeues (
+	job_id uuid NOT NULL REFERENCES provisioner_jobs (id) ON DELETE CASCADE,
+	worker_id uuid,
+	created_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX provisioner_job_requeues_job_id_idx ON provisioner_job_requeues USING btree (job_id);
+
+COMMENT ON TABLE provisioner_job_requeues IS 'Records each time a running provisioner job was returned to the queue because its provisioner daemon stopped sending heartbeats.';
+COMMENT ON COLUMN provisioner_job_requeues.worker_id IS 'The ID of the provisioner daemon that was running the job when it was requeued.';
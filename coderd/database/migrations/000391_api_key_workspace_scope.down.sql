@@ -0,0 +1,5 @@
+-- It's not possible to drop enum values from enum types, so the up migration has "IF NOT EXISTS".
+
+DELETE FROM api_keys WHERE workspace_id IS NOT NULL;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS workspace_id;
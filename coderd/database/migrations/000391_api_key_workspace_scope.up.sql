@@ -0,0 +1,5 @@
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'workspace_connect';
+
+ALTER TABLE api_keys ADD COLUMN workspace_id uuid REFERENCES workspaces (id) ON DELETE CASCADE;
+
+COMMENT ON COLUMN api_keys.workspace_id IS 'The workspace that keys with the workspace_connect scope can connect to. Keys with other scopes are not limited to a workspace.';
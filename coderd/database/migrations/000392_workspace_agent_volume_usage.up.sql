@@ -0,0 +1,10 @@
+CREATE TABLE workspace_agent_volume_usage (
+	agent_id uuid NOT NULL REFERENCES workspace_agents (id) ON DELETE CASCADE,
+	path text NOT NULL,
+	total_bytes bigint NOT NULL,
+	used_bytes bigint NOT NULL,
+	collected_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (agent_id, path)
+);
+
+COMMENT ON TABLE workspace_agent_volume_usage IS 'The most recent filesystem usage reported by workspace agents for their home directory and monitored volumes.';
@@ -0,0 +1,3 @@
+ALTER TABLE files ADD COLUMN external boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN files.external IS 'Whether the contents of the file are kept in object storage under the hash of the file, instead of in the data column.';
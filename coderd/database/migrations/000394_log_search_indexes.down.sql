@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_provisioner_job_logs_output_search;
+
+DROP INDEX IF EXISTS idx_audit_logs_search;
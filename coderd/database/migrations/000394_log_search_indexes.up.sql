@@ -0,0 +1,10 @@
+-- Full-text indexes for searching audit logs and provisioner job logs. The
+-- 'simple' configuration is used so that identifiers, such as template and
+-- resource names, are matched without stemming.
+CREATE INDEX idx_audit_logs_search ON audit_logs USING gin (
+	to_tsvector('simple', resource_target || ' ' || diff::text || ' ' || additional_fields::text)
+);
+
+CREATE INDEX idx_provisioner_job_logs_output_search ON provisioner_job_logs USING gin (
+	to_tsvector('simple', output::text)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE organization_setting_overrides
+	DROP COLUMN provisioner_max_jobs,
+	DROP COLUMN provisioner_reserved_jobs;
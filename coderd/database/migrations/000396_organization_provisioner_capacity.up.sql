@@ -0,0 +1,7 @@
+ALTER TABLE organization_setting_overrides
+	ADD COLUMN provisioner_max_jobs integer CHECK (provisioner_max_jobs >= 0),
+	ADD COLUMN provisioner_reserved_jobs integer CHECK (provisioner_reserved_jobs >= 0);
+
+COMMENT ON COLUMN organization_setting_overrides.provisioner_max_jobs IS 'Maximum number of provisioner jobs of the organization that may run concurrently. 0 means unlimited.';
+
+COMMENT ON COLUMN organization_setting_overrides.provisioner_reserved_jobs IS 'Number of concurrent provisioner jobs reserved for the organization out of the shared capacity of the deployment.';
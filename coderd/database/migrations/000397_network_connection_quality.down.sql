@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS network_connection_quality_counters;
+DROP TABLE IF EXISTS network_connection_quality;
@@ -0,0 +1,26 @@
+CREATE TABLE network_connection_quality (
+	client_type text NOT NULL,
+	node_id_self bigint NOT NULL,
+	node_id_remote bigint NOT NULL,
+	connection_type text NOT NULL,
+	latency_ms double precision,
+	handshake_retries bigint NOT NULL DEFAULT 0,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (client_type, node_id_self, node_id_remote)
+);
+
+COMMENT ON TABLE network_connection_quality IS 'Latest network telemetry of every workspace connection, reported by clients and agents to any replica.';
+
+COMMENT ON COLUMN network_connection_quality.node_id_self IS 'Tailnet node ID of the reporting side. Node IDs are unsigned 64-bit integers stored bit for bit.';
+
+COMMENT ON COLUMN network_connection_quality.handshake_retries IS 'Number of WireGuard handshakes the reporting side had to retry over the lifetime of the connection.';
+
+CREATE INDEX network_connection_quality_updated_at_idx ON network_connection_quality USING btree (updated_at);
+
+CREATE TABLE network_connection_quality_counters (
+	client_type text PRIMARY KEY,
+	relay_fallbacks bigint NOT NULL DEFAULT 0,
+	handshake_retries bigint NOT NULL DEFAULT 0
+);
+
+COMMENT ON TABLE network_connection_quality_counters IS 'Deployment-wide totals of connections falling back from P2P to a DERP relay and of WireGuard handshake retries, by client type.';
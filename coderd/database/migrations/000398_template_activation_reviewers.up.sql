@@ -0,0 +1,7 @@
+CREATE TABLE template_activation_reviewers (
+	template_id uuid NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+	user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	PRIMARY KEY (template_id, user_id)
+);
+
+COMMENT ON TABLE template_activation_reviewers IS 'Users designated to review the activation requests of template versions. When a template has none, users that can update the template review them.';
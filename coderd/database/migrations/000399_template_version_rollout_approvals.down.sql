@@ -0,0 +1 @@
+-- It's not possible to drop enum values from enum types, so the up migration has "IF NOT EXISTS".
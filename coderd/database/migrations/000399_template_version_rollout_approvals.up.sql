@@ -0,0 +1,5 @@
+-- Rollouts of templates that require approvals to activate versions wait for
+-- the activation of their version to be approved before they start.
+ALTER TYPE template_version_rollout_status ADD VALUE IF NOT EXISTS 'pending_approval';
+
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'template_version_rollout';
@@ -0,0 +1,11 @@
+INSERT INTO groups (id, name, organization_id, source)
+SELECT 'e5c8bdb6-1f4a-4b7a-9b0c-6f3d2a1c9e01', 'scim-parent', id, 'scim' FROM organizations LIMIT 1;
+
+INSERT INTO groups (id, name, organization_id, source)
+SELECT 'e5c8bdb6-1f4a-4b7a-9b0c-6f3d2a1c9e02', 'scim-child', id, 'scim' FROM organizations LIMIT 1;
+
+INSERT INTO scim_group_members (group_id, user_id)
+SELECT 'e5c8bdb6-1f4a-4b7a-9b0c-6f3d2a1c9e02', id FROM users LIMIT 1;
+
+INSERT INTO scim_nested_groups (group_id, member_group_id)
+VALUES ('e5c8bdb6-1f4a-4b7a-9b0c-6f3d2a1c9e01', 'e5c8bdb6-1f4a-4b7a-9b0c-6f3d2a1c9e02');
@@ -0,0 +1,5 @@
+INSERT INTO ldap_users (user_id, external_id, dn, created_at, updated_at)
+SELECT id, 'c0f1f1d2-9a52-4c3e-8d7b-2f6a1b3e4d50', 'CN=Fixture,OU=Users,DC=example,DC=com', now(), now() FROM users LIMIT 1;
+
+INSERT INTO groups (id, name, organization_id, source)
+SELECT 'f2a9c3d1-4b6e-4f8a-9c1d-7e5b3a2f1c01', 'ldap-engineering', id, 'ldap' FROM organizations LIMIT 1;
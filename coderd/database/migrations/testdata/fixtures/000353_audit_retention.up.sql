@@ -0,0 +1,5 @@
+INSERT INTO organization_audit_settings (organization_id, retention_days, updated_at)
+SELECT id, 365, now() FROM organizations LIMIT 1;
+
+INSERT INTO audit_log_legal_holds (id, organization_id, user_id, reason, created_by, created_at)
+SELECT 'a7d3c1e2-5b4f-4e6a-8c9d-1f2e3a4b5c60', organization_id, user_id, 'Fixture', user_id, now() FROM organization_members LIMIT 1;
@@ -0,0 +1,5 @@
+INSERT INTO group_quota_allowances (group_id, dimension, allowance)
+SELECT id, 'cpu', 16 FROM groups LIMIT 1;
+
+INSERT INTO workspace_build_quota_costs (workspace_build_id, dimension, cost)
+SELECT id, 'cpu', 4 FROM workspace_builds LIMIT 1;
@@ -0,0 +1,2 @@
+INSERT INTO license_seat_reservations (group_id, seats, created_at, updated_at)
+SELECT id, 10, NOW(), NOW() FROM groups LIMIT 1;
@@ -0,0 +1,4 @@
+INSERT INTO notification_messages (id, notification_template_id, user_id, method, created_by, payload, deliver_after, digest)
+VALUES (
+	gen_random_uuid(), 'a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11', 'fc1511ef-4fcf-4a3b-98a1-8df64160e35a', 'smtp'::notification_method, 'test', '{}', NOW(), true
+);
@@ -0,0 +1,4 @@
+INSERT INTO notification_category_preferences (user_id, category, method, disabled)
+VALUES (
+	'fc1511ef-4fcf-4a3b-98a1-8df64160e35a', 'builds'::notification_category, 'smtp'::notification_method, true
+);
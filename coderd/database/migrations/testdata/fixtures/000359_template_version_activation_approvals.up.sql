@@ -0,0 +1,9 @@
+INSERT INTO template_version_activation_requests (template_version_id, template_id, requested_by, status, approvals_required, created_at, updated_at)
+VALUES (
+	'af58bd62-428c-4c33-849b-d43a3be07d93', '6b298946-7a4f-47ac-9158-b03b08740a41', '6c353aac-20de-467b-bdfb-3c30a37adcd2', 'pending'::template_version_activation_request_status, 2, '2025-02-07 07:46:19.514782 +00:00', '2025-02-07 07:46:19.514782 +00:00'
+);
+
+INSERT INTO template_version_activation_reviews (template_version_id, reviewer_id, approved, comment, created_at)
+VALUES (
+	'af58bd62-428c-4c33-849b-d43a3be07d93', 'fc1511ef-4fcf-4a3b-98a1-8df64160e35a', true, 'Looks good to me.', '2025-02-07 07:46:19.514782 +00:00'
+);
@@ -0,0 +1,4 @@
+INSERT INTO template_version_rollouts (template_id, template_version_id, created_by, percentage, group_ids, failure_threshold_percent, minimum_builds, status, created_at, updated_at)
+VALUES (
+	'6b298946-7a4f-47ac-9158-b03b08740a41', 'af58bd62-428c-4c33-849b-d43a3be07d93', 'fc1511ef-4fcf-4a3b-98a1-8df64160e35a', 10, '{}', 20, 5, 'in_progress'::template_version_rollout_status, '2025-02-07 07:46:19.514782 +00:00', '2025-02-07 07:46:19.514782 +00:00'
+);
@@ -0,0 +1,4 @@
+INSERT INTO template_bundles (id, organization_id, name, version, hash, manifest, data, created_by, created_at)
+VALUES (
+	'0c0a4fb4-9ab4-4a44-a36a-1b3b9d0e4f7e', 'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1', 'docker', 'v1', 'e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855', '{"format_version": 1, "name": "docker", "version": "v1", "providers": [], "modules": []}', '\x', 'fc1511ef-4fcf-4a3b-98a1-8df64160e35a', '2025-02-07 07:46:19.514782 +00:00'
+);
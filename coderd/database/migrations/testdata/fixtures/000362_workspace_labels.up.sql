@@ -0,0 +1,4 @@
+INSERT INTO workspace_labels (workspace_id, key, value)
+VALUES
+	('3a9a1feb-e89d-457c-9d53-ac751b198ebe', 'team', 'platform'),
+	('3a9a1feb-e89d-457c-9d53-ac751b198ebe', 'cost-center', 'eng-1234');
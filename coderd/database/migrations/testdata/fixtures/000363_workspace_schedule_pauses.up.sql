@@ -0,0 +1,3 @@
+INSERT INTO workspace_schedule_pauses (workspace_id, paused_until, created_at, created_by)
+VALUES
+	('3a9a1feb-e89d-457c-9d53-ac751b198ebe', '2024-07-01 00:00:00+00', '2024-06-01 00:00:00+00', '30095c71-380b-457a-8995-97b8ee6e5307');
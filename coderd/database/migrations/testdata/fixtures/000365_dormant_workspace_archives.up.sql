@@ -0,0 +1,3 @@
+INSERT INTO workspace_archives (id, workspace_id, workspace_name, owner_id, organization_id, template_id, paths, object_key, size_bytes, error, created_at, expires_at)
+VALUES
+	('b6c2e8a4-1f0d-4f6e-9c1a-2d4b6f8a0c3e', '3a9a1feb-e89d-457c-9d53-ac751b198ebe', 'workspace', '30095c71-380b-457a-8995-97b8ee6e5307', 'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1', '4cc1f466-f326-477e-8762-9d0c6781fc56', '{"/home/coder"}', 'b6c2e8a4-1f0d-4f6e-9c1a-2d4b6f8a0c3e.tar.gz', 1024, '', '2024-06-01 00:00:00+00', '2024-07-01 00:00:00+00');
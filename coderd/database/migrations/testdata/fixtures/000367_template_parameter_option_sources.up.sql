@@ -0,0 +1,4 @@
+INSERT INTO template_parameter_option_sources (template_id, parameter_name, url, auth_header_name, auth_header_value, cache_ttl, created_at, updated_at)
+VALUES (
+	'6b298946-7a4f-47ac-9158-b03b08740a41', 'gpu_pool', 'https://inventory.example.com/gpu-pools', 'Authorization', 'Bearer secret', 300000000000, '2025-02-07 07:46:19.514782 +00:00', '2025-02-07 07:46:19.514782 +00:00'
+);
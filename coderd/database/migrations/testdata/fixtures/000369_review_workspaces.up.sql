@@ -0,0 +1,3 @@
+INSERT INTO review_workspaces (workspace_id, organization_id, repository, branch, created_at, updated_at)
+VALUES
+	('b90547be-8870-4d68-8184-e8b2242b7c01', 'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1', 'coder/coder', 'feature/review', '2024-06-01 00:00:00+00', '2024-06-01 00:00:00+00');
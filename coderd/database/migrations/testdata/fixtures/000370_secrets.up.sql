@@ -0,0 +1,7 @@
+INSERT INTO user_secrets (id, user_id, name, description, value, env_name, created_at, updated_at)
+VALUES
+	('4a8f0e2c-7bb1-4f0a-9d3e-2f6f1b6d3c01', '30095c71-380b-457a-8995-97b8ee6e5307', 'npm-token', 'Token for the private registry', 'secret', 'NPM_TOKEN', '2024-06-01 00:00:00+00', '2024-06-01 00:00:00+00');
+
+INSERT INTO template_secrets (id, template_id, name, description, value, created_at, updated_at)
+VALUES
+	('9b2c6d1e-3f4a-4e5b-8c7d-1a2b3c4d5e02', '4cc1f466-f326-477e-8762-9d0c6781fc56', 'cloud_api_key', 'API key of the cloud provider', 'secret', '2024-06-01 00:00:00+00', '2024-06-01 00:00:00+00');
@@ -0,0 +1,3 @@
+INSERT INTO template_egress_policies (template_id, allowed_cidrs, allowed_domains, created_at, updated_at)
+VALUES
+	('4cc1f466-f326-477e-8762-9d0c6781fc56', '{10.0.0.0/8}', '{github.com,registry.npmjs.org}', '2024-06-01 00:00:00+00', '2024-06-01 00:00:00+00');
@@ -0,0 +1,3 @@
+INSERT INTO oauth2_provider_app_device_codes (id, created_at, expires_at, device_code_prefix, device_code_hash, user_code, app_id)
+VALUES
+	('c4f2a8f1-2b6e-4d3a-9c1e-5f8b7a6d4e21', '2024-06-01 00:00:00+00', '2024-06-01 00:10:00+00', CAST('prefix' AS bytea), CAST('hash' AS bytea), 'BCDFGHJK', 'a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11');
@@ -0,0 +1,14 @@
+INSERT INTO webauthn_challenges (id, user_id, purpose, challenge, created_at, expires_at)
+VALUES
+	('5d1e7c2a-8b3f-4a6e-9c0d-2e4f6a8b0c13', '30095c71-380b-457a-8995-97b8ee6e5307', 'login', CAST('challenge' AS bytea), '2024-06-01 00:00:00+00', '2024-06-01 00:05:00+00');
+
+INSERT INTO user_webauthn_credentials (id, user_id, name, credential_id, public_key, sign_count, created_at)
+VALUES
+	('8e2f4a6c-1d3b-4c5e-a7f9-0b2d4e6f8a24', '30095c71-380b-457a-8995-97b8ee6e5307', 'YubiKey', CAST('credential' AS bytea), CAST('key' AS bytea), 0, '2024-06-01 00:00:00+00');
+
+INSERT INTO user_mfa_recovery_codes (user_id, hashed_code, created_at)
+VALUES
+	('30095c71-380b-457a-8995-97b8ee6e5307', CAST('hash' AS bytea), '2024-06-01 00:00:00+00');
+
+INSERT INTO organization_mfa_settings (organization_id, require_webauthn, updated_at)
+SELECT id, true, now() FROM organizations LIMIT 1;
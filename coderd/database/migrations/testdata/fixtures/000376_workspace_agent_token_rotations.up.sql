@@ -0,0 +1,3 @@
+INSERT INTO workspace_agent_token_rotations (agent_id, previous_auth_token, previous_auth_token_expires_at, rotated_at)
+VALUES
+	('45e89705-e09d-4850-bcec-f9a937f5d78d', 'f1d2b9a4-3c1e-4a55-9a0b-6f1f4f0f2c7e', '2024-06-01 00:10:00+00', '2024-06-01 00:00:00+00');
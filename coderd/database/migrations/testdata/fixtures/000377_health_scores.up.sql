@@ -0,0 +1,3 @@
+INSERT INTO health_scores (replica_id, created_at, score, database_score, database_latency_ms, derp_score, provisioner_daemons_score, replicas_score, websocket_score)
+VALUES
+	('7b4f3d6c-0c6b-4a47-9d3e-3e8f5d8a9c21', '2024-06-01 00:00:00+00', 90, 100, 12, 50, 100, 100, 100);
@@ -0,0 +1,3 @@
+INSERT INTO workspace_agent_lifecycle_events (workspace_agent_id, lifecycle_state, created_at)
+VALUES
+	('45e89705-e09d-4850-bcec-f9a937f5d78d', 'ready', '2024-06-01 00:00:00+00');
@@ -0,0 +1,3 @@
+INSERT INTO workspace_proxy_token_rotations (proxy_id, previous_token_hashed_secret, previous_token_expires_at, rotated_at)
+VALUES
+	('cf8ede8c-ff47-441f-a738-d92e4e34a657', 'def456'::bytea, '2023-03-30 13:00:00+00', '2023-03-30 12:00:00+00');
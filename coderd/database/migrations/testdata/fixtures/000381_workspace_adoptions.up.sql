@@ -0,0 +1,3 @@
+INSERT INTO workspace_adoptions (id, organization_id, owner_id, template_id, workspace_name, hashed_secret, created_by, created_at, expires_at, workspace_id, hostname, adopted_at)
+VALUES
+	('6a2a1b44-3b2e-4a3c-9c1f-7f1d2c3e4b5a', 'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1', '0ed9befc-4911-4ccf-a8e2-559bf72daa94', '4cc1f466-f326-477e-8762-9d0c6781fc56', 'test1', 'abc123'::bytea, '0ed9befc-4911-4ccf-a8e2-559bf72daa94', '2024-06-01 00:00:00+00', '2024-06-02 00:00:00+00', 'b90547be-8870-4d68-8184-e8b2242b7c01', 'build-box', '2024-06-01 01:00:00+00');
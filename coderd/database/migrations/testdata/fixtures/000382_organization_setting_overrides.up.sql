@@ -0,0 +1,2 @@
+INSERT INTO organization_setting_overrides (organization_id, default_ttl, allowed_external_auth_providers, updated_at)
+SELECT id, 28800000000000, '{github}', now() FROM organizations LIMIT 1;
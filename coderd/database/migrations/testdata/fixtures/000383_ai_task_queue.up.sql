@@ -0,0 +1,3 @@
+INSERT INTO ai_task_queue (id, owner_id, organization_id, template_version_id, name, prompt, status, error, created_at, updated_at)
+VALUES
+	('0c1a9e1e-5d3b-4f0e-9b6a-2f4e8d7c6b5a', '0ed9befc-4911-4ccf-a8e2-559bf72daa94', 'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1', '920baba5-4c64-4686-8b7d-d1bef5683eae', 'fix-tests', 'Fix the failing tests.', 'queued', '', '2024-06-01 00:00:00+00', '2024-06-01 00:00:00+00');
@@ -0,0 +1,3 @@
+INSERT INTO user_cli_versions (user_id, version, last_seen_at)
+VALUES
+	('0ed9befc-4911-4ccf-a8e2-559bf72daa94', 'v2.20.0', '2024-06-01 00:00:00+00');
@@ -0,0 +1,4 @@
+INSERT INTO template_app_restrictions (template_id, app_slug, group_ids, roles, created_at, updated_at)
+VALUES (
+	'6b298946-7a4f-47ac-9158-b03b08740a41', 'debug', '{}', '{owner}', '2025-02-07 07:46:19.514782 +00:00', '2025-02-07 07:46:19.514782 +00:00'
+);
@@ -0,0 +1,4 @@
+INSERT INTO template_app_session_policies (template_id, app_slug, max_session_duration, created_at, updated_at)
+VALUES (
+	'6b298946-7a4f-47ac-9158-b03b08740a41', 'debug', 28800000000000, '2025-02-07 07:46:19.514782 +00:00', '2025-02-07 07:46:19.514782 +00:00'
+);
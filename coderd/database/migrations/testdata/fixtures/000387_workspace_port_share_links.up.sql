@@ -0,0 +1,4 @@
+INSERT INTO workspace_port_share_links (id, workspace_id, agent_name, port, protocol, hashed_secret, hashed_password, bandwidth_limit_bytes, bytes_transferred, expires_at, created_by, created_at)
+VALUES (
+	'd9a4f1c2-5b6e-4c7d-8e9f-0a1b2c3d4e5f', '3a9a1feb-e89d-457c-9d53-ac751b198ebe', 'main', 8080, 'http', '\xdeadbeef', '', 1073741824, 0, '2025-02-08 07:46:19.514782 +00:00', '30095c71-380b-457a-8995-97b8ee6e5307', '2025-02-07 07:46:19.514782 +00:00'
+);
@@ -0,0 +1,7 @@
+INSERT INTO variable_sets (id, name, description, variables, created_at, updated_at)
+VALUES
+	('6f1d2c3b-4a5e-4f60-8b7c-9d0e1f2a3b04', 'aws-network', 'Shared VPC of the development account', '[{"name":"vpc_id","value":"vpc-0123456789","secret":false}]', '2024-06-01 00:00:00+00', '2024-06-01 00:00:00+00');
+
+INSERT INTO template_variable_sets (template_id, variable_set_id, created_at)
+VALUES
+	('4cc1f466-f326-477e-8762-9d0c6781fc56', '6f1d2c3b-4a5e-4f60-8b7c-9d0e1f2a3b04', '2024-06-01 00:00:00+00');
@@ -0,0 +1,3 @@
+INSERT INTO provisioner_job_requeues (job_id, worker_id, created_at)
+VALUES
+	('424a58cb-61d6-4627-9907-613c396c4a38', '22c3662c-60eb-408b-99e1-85b447c2b7e5', '2022-11-02 13:06:05+02');
@@ -0,0 +1,26 @@
+INSERT INTO api_keys (
+	id,
+	hashed_secret,
+	user_id,
+	last_used,
+	expires_at,
+	created_at,
+	updated_at,
+	login_type,
+	lifetime_seconds,
+	scope,
+	workspace_id
+)
+VALUES (
+	'wsConnect1',
+	'\x8dc4b0a2f1b4dc2f4d5d1c5f9b4b0bd0e1f7f1a7e8de6b5c0d2b6a9e4c3f1a2b',
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'2025-01-01 00:00:00+00',
+	'2025-01-01 01:00:00+00',
+	'2025-01-01 00:00:00+00',
+	'2025-01-01 00:00:00+00',
+	'token',
+	3600,
+	'workspace_connect',
+	'3a9a1feb-e89d-457c-9d53-ac751b198ebe'
+);
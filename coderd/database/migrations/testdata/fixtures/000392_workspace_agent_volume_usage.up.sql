@@ -0,0 +1,15 @@
+INSERT INTO
+	workspace_agent_volume_usage (
+		agent_id,
+		path,
+		total_bytes,
+		used_bytes,
+		collected_at
+	)
+	VALUES (
+		'45e89705-e09d-4850-bcec-f9a937f5d78d',
+		'/home/coder',
+		107374182400,
+		53687091200,
+		'2025-01-01 00:00:00+00'
+	);
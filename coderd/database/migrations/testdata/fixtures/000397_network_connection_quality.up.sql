@@ -0,0 +1,7 @@
+INSERT INTO network_connection_quality (client_type, node_id_self, node_id_remote, connection_type, latency_ms, handshake_retries, updated_at)
+VALUES
+	('cli', 1, 10, 'p2p', 12.5, 1, '2024-06-01 00:00:00+00');
+
+INSERT INTO network_connection_quality_counters (client_type, relay_fallbacks, handshake_retries)
+VALUES
+	('cli', 2, 1);
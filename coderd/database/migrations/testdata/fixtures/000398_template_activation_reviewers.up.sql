@@ -0,0 +1,4 @@
+INSERT INTO template_activation_reviewers (template_id, user_id)
+VALUES (
+	'6b298946-7a4f-47ac-9158-b03b08740a41', 'fc1511ef-4fcf-4a3b-98a1-8df64160e35a'
+);
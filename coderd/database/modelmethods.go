@@ -75,6 +75,32 @@ func (m OrganizationMember) Auditable(username string) AuditableOrganizationMemb
 	}
 }
 
+type AuditableTemplateVersionActivationRequest struct {
+	TemplateVersionActivationRequest
+	TemplateVersionName string                            `json:"template_version_name"`
+	Reviews             []TemplateVersionActivationReview `json:"reviews"`
+}
+
+// Auditable returns an object that can be used in audit logs.
+// Covers both the request and its reviews.
+func (r TemplateVersionActivationRequest) Auditable(templateVersionName string, reviews []GetTemplateVersionActivationReviewsRow) AuditableTemplateVersionActivationRequest {
+	reviewsTable := make([]TemplateVersionActivationReview, len(reviews))
+	for i, review := range reviews {
+		reviewsTable[i] = TemplateVersionActivationReview{
+			TemplateVersionID: review.TemplateVersionID,
+			ReviewerID:        review.ReviewerID,
+			Approved:          review.Approved,
+			Comment:           review.Comment,
+			CreatedAt:         review.CreatedAt,
+		}
+	}
+	return AuditableTemplateVersionActivationRequest{
+		TemplateVersionActivationRequest: r,
+		TemplateVersionName:              templateVersionName,
+		Reviews:                          reviewsTable,
+	}
+}
+
 type AuditableGroup struct {
 	Group
 	Members []GroupMemberTable `json:"members"`
@@ -123,6 +149,8 @@ func (s APIKeyScope) ToRBAC() rbac.ScopeName {
 		return rbac.ScopeAll
 	case APIKeyScopeApplicationConnect:
 		return rbac.ScopeApplicationConnect
+	case APIKeyScopeWorkspaceConnect:
+		return rbac.ScopeWorkspaceConnect
 	default:
 		panic("developer error: unknown scope type " + string(s))
 	}
@@ -133,6 +161,27 @@ func (k APIKey) RBACObject() rbac.Object {
 		WithOwner(k.UserID.String())
 }
 
+// IsSession returns whether the key was created by logging in. This matches
+// the keys returned by GetAPIKeySessionsByUserID.
+func (k APIKey) IsSession() bool {
+	return k.isLogin() && k.Scope == APIKeyScopeAll
+}
+
+// IsSessionOrAppKey returns whether the key is a session or the key of a
+// workspace app derived from one. This matches the keys deleted by
+// DeleteStaleAPIKeySessionsByUserID.
+func (k APIKey) IsSessionOrAppKey() bool {
+	return k.isLogin() && (k.Scope == APIKeyScopeAll || k.Scope == APIKeyScopeApplicationConnect)
+}
+
+// isLogin returns whether the key was created by logging in or derived from
+// such a key, rather than being an API token.
+func (k APIKey) isLogin() bool {
+	return k.LoginType != LoginTypeToken &&
+		k.LoginType != LoginTypeOAuth2ProviderApp &&
+		k.TokenName == ""
+}
+
 func (t Template) RBACObject() rbac.Object {
 	return rbac.ResourceTemplate.WithID(t.ID).
 		InOrg(t.OrganizationID).
@@ -229,6 +278,7 @@ func (w Workspace) WorkspaceTable() WorkspaceTable {
 		AutomaticUpdates:  w.AutomaticUpdates,
 		Favorite:          w.Favorite,
 		NextStartAt:       w.NextStartAt,
+		Ephemeral:         w.Ephemeral,
 	}
 }
 
@@ -236,6 +286,26 @@ func (w Workspace) RBACObject() rbac.Object {
 	return w.WorkspaceTable().RBACObject()
 }
 
+// RBACObject returns the object of the workspace the machine is adopted as.
+// The workspace does not exist until the machine is adopted.
+func (a WorkspaceAdoption) RBACObject() rbac.Object {
+	obj := rbac.ResourceWorkspace.
+		InOrg(a.OrganizationID).
+		WithOwner(a.OwnerID.String())
+	if a.WorkspaceID.Valid {
+		obj = obj.WithID(a.WorkspaceID.UUID)
+	}
+	return obj
+}
+
+// RBACObject returns the object of the workspace the queued task becomes.
+// The workspace does not exist until the task leaves the queue.
+func (t AITaskQueue) RBACObject() rbac.Object {
+	return rbac.ResourceWorkspace.
+		InOrg(t.OrganizationID).
+		WithOwner(t.OwnerID.String())
+}
+
 // IsPrebuild returns true if the workspace is a prebuild workspace.
 // A workspace is considered a prebuild if its owner is the prebuild system user.
 func (w Workspace) IsPrebuild() bool {
@@ -271,6 +341,15 @@ func (w WorkspaceTable) DormantRBAC() rbac.Object {
 		WithOwner(w.OwnerID.String())
 }
 
+// RBACObject returns the object of the workspace the archive was taken of.
+// Workspaces are always dormant when they are archived.
+func (a WorkspaceArchive) RBACObject() rbac.Object {
+	return rbac.ResourceWorkspaceDormant.
+		WithID(a.WorkspaceID).
+		InOrg(a.OrganizationID).
+		WithOwner(a.OwnerID.String())
+}
+
 // IsPrebuild returns true if the workspace is a prebuild workspace.
 // A workspace is considered a prebuild if its owner is the prebuild system user.
 func (w WorkspaceTable) IsPrebuild() bool {
@@ -366,6 +445,9 @@ func (u GetUsersRow) RBACObject() rbac.Object {
 func (u GitSSHKey) RBACObject() rbac.Object        { return rbac.ResourceUserObject(u.UserID) }
 func (u ExternalAuthLink) RBACObject() rbac.Object { return rbac.ResourceUserObject(u.UserID) }
 func (u UserLink) RBACObject() rbac.Object         { return rbac.ResourceUserObject(u.UserID) }
+func (c UserWebAuthnCredential) RBACObject() rbac.Object {
+	return rbac.ResourceUserObject(c.UserID)
+}
 
 func (u ExternalAuthLink) OAuthToken() *oauth2.Token {
 	return &oauth2.Token{
@@ -383,6 +465,14 @@ func (c OAuth2ProviderAppCode) RBACObject() rbac.Object {
 	return rbac.ResourceOauth2AppCodeToken.WithOwner(c.UserID.String())
 }
 
+func (c OAuth2ProviderAppDeviceCode) RBACObject() rbac.Object {
+	// Pending device codes are not owned by anyone yet.
+	if !c.UserID.Valid {
+		return rbac.ResourceOauth2AppCodeToken.WithID(c.ID)
+	}
+	return rbac.ResourceOauth2AppCodeToken.WithOwner(c.UserID.UUID.String()).WithID(c.ID)
+}
+
 func (t OAuth2ProviderAppToken) RBACObject() rbac.Object {
 	return rbac.ResourceOauth2AppCodeToken.WithOwner(t.UserID.String()).WithID(t.ID)
 }
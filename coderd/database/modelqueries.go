@@ -119,6 +119,15 @@ func (q *sqlQuerier) GetAuthorizedTemplates(ctx context.Context, arg GetTemplate
 			&i.ActivityBump,
 			&i.MaxPortSharingLevel,
 			&i.UseClassicParameterFlow,
+			&i.WireguardMTU,
+			&i.WireguardKeepaliveInterval,
+			&i.WireguardHandshakeTimeout,
+			&i.ActivationApprovalsRequired,
+			pq.Array(&i.AutostartHolidays),
+			&i.AutostartHolidayCalendarURL,
+			pq.Array(&i.DormantArchivePaths),
+			&i.ParameterValidationURL,
+			&i.AllowStartOnConnect,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.CreatedByName,
@@ -267,6 +276,8 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 		arg.LastUsedAfter,
 		arg.UsingActive,
 		arg.HasAITask,
+		pq.Array(arg.LabelKeys),
+		pq.Array(arg.LabelValues),
 		arg.RequesterID,
 		arg.Offset,
 		arg.Limit,
@@ -296,6 +307,7 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 			&i.AutomaticUpdates,
 			&i.Favorite,
 			&i.NextStartAt,
+			&i.Ephemeral,
 			&i.OwnerAvatarUrl,
 			&i.OwnerUsername,
 			&i.OwnerName,
@@ -508,6 +520,7 @@ func (q *sqlQuerier) GetAuthorizedAuditLogsOffset(ctx context.Context, arg GetAu
 		arg.DateTo,
 		arg.BuildReason,
 		arg.RequestID,
+		arg.Search,
 		arg.OffsetOpt,
 		arg.LimitOpt,
 	)
@@ -591,6 +604,7 @@ func (q *sqlQuerier) CountAuthorizedAuditLogs(ctx context.Context, arg CountAudi
 		arg.DateTo,
 		arg.BuildReason,
 		arg.RequestID,
+		arg.Search,
 	)
 	if err != nil {
 		return 0, err
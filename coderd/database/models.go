@@ -16,11 +16,70 @@ import (
 	"github.com/sqlc-dev/pqtype"
 )
 
+type AITaskQueueStatus string
+
+const (
+	AITaskQueueStatusQueued AITaskQueueStatus = "queued"
+	AITaskQueueStatusFailed AITaskQueueStatus = "failed"
+)
+
+func (e *AITaskQueueStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AITaskQueueStatus(s)
+	case string:
+		*e = AITaskQueueStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AITaskQueueStatus: %T", src)
+	}
+	return nil
+}
+
+type NullAITaskQueueStatus struct {
+	AITaskQueueStatus AITaskQueueStatus `json:"ai_task_queue_status"`
+	Valid             bool              `json:"valid"` // Valid is true if AITaskQueueStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAITaskQueueStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.AITaskQueueStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AITaskQueueStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAITaskQueueStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AITaskQueueStatus), nil
+}
+
+func (e AITaskQueueStatus) Valid() bool {
+	switch e {
+	case AITaskQueueStatusQueued,
+		AITaskQueueStatusFailed:
+		return true
+	}
+	return false
+}
+
+func AllAITaskQueueStatusValues() []AITaskQueueStatus {
+	return []AITaskQueueStatus{
+		AITaskQueueStatusQueued,
+		AITaskQueueStatusFailed,
+	}
+}
+
 type APIKeyScope string
 
 const (
 	APIKeyScopeAll                APIKeyScope = "all"
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	APIKeyScopeWorkspaceConnect   APIKeyScope = "workspace_connect"
 )
 
 func (e *APIKeyScope) Scan(src interface{}) error {
@@ -61,7 +120,8 @@ func (ns NullAPIKeyScope) Value() (driver.Value, error) {
 func (e APIKeyScope) Valid() bool {
 	switch e {
 	case APIKeyScopeAll,
-		APIKeyScopeApplicationConnect:
+		APIKeyScopeApplicationConnect,
+		APIKeyScopeWorkspaceConnect:
 		return true
 	}
 	return false
@@ -71,6 +131,7 @@ func AllAPIKeyScopeValues() []APIKeyScope {
 	return []APIKeyScope{
 		APIKeyScopeAll,
 		APIKeyScopeApplicationConnect,
+		APIKeyScopeWorkspaceConnect,
 	}
 }
 
@@ -212,6 +273,9 @@ const (
 	AuditActionDisconnect           AuditAction = "disconnect"
 	AuditActionOpen                 AuditAction = "open"
 	AuditActionClose                AuditAction = "close"
+	AuditActionRead                 AuditAction = "read"
+	AuditActionEgressViolation      AuditAction = "egress_violation"
+	AuditActionNetworkZoneViolation AuditAction = "network_zone_violation"
 )
 
 func (e *AuditAction) Scan(src interface{}) error {
@@ -263,7 +327,10 @@ func (e AuditAction) Valid() bool {
 		AuditActionConnect,
 		AuditActionDisconnect,
 		AuditActionOpen,
-		AuditActionClose:
+		AuditActionClose,
+		AuditActionRead,
+		AuditActionEgressViolation,
+		AuditActionNetworkZoneViolation:
 		return true
 	}
 	return false
@@ -284,6 +351,9 @@ func AllAuditActionValues() []AuditAction {
 		AuditActionDisconnect,
 		AuditActionOpen,
 		AuditActionClose,
+		AuditActionRead,
+		AuditActionEgressViolation,
+		AuditActionNetworkZoneViolation,
 	}
 }
 
@@ -348,12 +418,13 @@ func AllAutomaticUpdatesValues() []AutomaticUpdates {
 type BuildReason string
 
 const (
-	BuildReasonInitiator  BuildReason = "initiator"
-	BuildReasonAutostart  BuildReason = "autostart"
-	BuildReasonAutostop   BuildReason = "autostop"
-	BuildReasonDormancy   BuildReason = "dormancy"
-	BuildReasonFailedstop BuildReason = "failedstop"
-	BuildReasonAutodelete BuildReason = "autodelete"
+	BuildReasonInitiator   BuildReason = "initiator"
+	BuildReasonAutostart   BuildReason = "autostart"
+	BuildReasonAutostop    BuildReason = "autostop"
+	BuildReasonDormancy    BuildReason = "dormancy"
+	BuildReasonFailedstop  BuildReason = "failedstop"
+	BuildReasonAutodelete  BuildReason = "autodelete"
+	BuildReasonAutoarchive BuildReason = "autoarchive"
 )
 
 func (e *BuildReason) Scan(src interface{}) error {
@@ -398,7 +469,8 @@ func (e BuildReason) Valid() bool {
 		BuildReasonAutostop,
 		BuildReasonDormancy,
 		BuildReasonFailedstop,
-		BuildReasonAutodelete:
+		BuildReasonAutodelete,
+		BuildReasonAutoarchive:
 		return true
 	}
 	return false
@@ -412,6 +484,7 @@ func AllBuildReasonValues() []BuildReason {
 		BuildReasonDormancy,
 		BuildReasonFailedstop,
 		BuildReasonAutodelete,
+		BuildReasonAutoarchive,
 	}
 }
 
@@ -551,6 +624,8 @@ type GroupSource string
 const (
 	GroupSourceUser GroupSource = "user"
 	GroupSourceOidc GroupSource = "oidc"
+	GroupSourceScim GroupSource = "scim"
+	GroupSourceLdap GroupSource = "ldap"
 )
 
 func (e *GroupSource) Scan(src interface{}) error {
@@ -591,7 +666,9 @@ func (ns NullGroupSource) Value() (driver.Value, error) {
 func (e GroupSource) Valid() bool {
 	switch e {
 	case GroupSourceUser,
-		GroupSourceOidc:
+		GroupSourceOidc,
+		GroupSourceScim,
+		GroupSourceLdap:
 		return true
 	}
 	return false
@@ -601,6 +678,8 @@ func AllGroupSourceValues() []GroupSource {
 	return []GroupSource{
 		GroupSourceUser,
 		GroupSourceOidc,
+		GroupSourceScim,
+		GroupSourceLdap,
 	}
 }
 
@@ -800,6 +879,7 @@ const (
 	LoginTypeToken             LoginType = "token"
 	LoginTypeNone              LoginType = "none"
 	LoginTypeOAuth2ProviderApp LoginType = "oauth2_provider_app"
+	LoginTypeSaml              LoginType = "saml"
 )
 
 func (e *LoginType) Scan(src interface{}) error {
@@ -844,7 +924,8 @@ func (e LoginType) Valid() bool {
 		LoginTypeOIDC,
 		LoginTypeToken,
 		LoginTypeNone,
-		LoginTypeOAuth2ProviderApp:
+		LoginTypeOAuth2ProviderApp,
+		LoginTypeSaml:
 		return true
 	}
 	return false
@@ -858,6 +939,71 @@ func AllLoginTypeValues() []LoginType {
 		LoginTypeToken,
 		LoginTypeNone,
 		LoginTypeOAuth2ProviderApp,
+		LoginTypeSaml,
+	}
+}
+
+type NotificationCategory string
+
+const (
+	NotificationCategoryBuilds          NotificationCategory = "builds"
+	NotificationCategoryDormancy        NotificationCategory = "dormancy"
+	NotificationCategoryAccount         NotificationCategory = "account"
+	NotificationCategoryTemplateUpdates NotificationCategory = "template_updates"
+)
+
+func (e *NotificationCategory) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationCategory(s)
+	case string:
+		*e = NotificationCategory(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationCategory: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationCategory struct {
+	NotificationCategory NotificationCategory `json:"notification_category"`
+	Valid                bool                 `json:"valid"` // Valid is true if NotificationCategory is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationCategory) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationCategory, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationCategory.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationCategory) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationCategory), nil
+}
+
+func (e NotificationCategory) Valid() bool {
+	switch e {
+	case NotificationCategoryBuilds,
+		NotificationCategoryDormancy,
+		NotificationCategoryAccount,
+		NotificationCategoryTemplateUpdates:
+		return true
+	}
+	return false
+}
+
+func AllNotificationCategoryValues() []NotificationCategory {
+	return []NotificationCategory{
+		NotificationCategoryBuilds,
+		NotificationCategoryDormancy,
+		NotificationCategoryAccount,
+		NotificationCategoryTemplateUpdates,
 	}
 }
 
@@ -940,6 +1086,8 @@ const (
 	NotificationMethodSmtp    NotificationMethod = "smtp"
 	NotificationMethodWebhook NotificationMethod = "webhook"
 	NotificationMethodInbox   NotificationMethod = "inbox"
+	NotificationMethodSlack   NotificationMethod = "slack"
+	NotificationMethodTeams   NotificationMethod = "teams"
 )
 
 func (e *NotificationMethod) Scan(src interface{}) error {
@@ -981,7 +1129,9 @@ func (e NotificationMethod) Valid() bool {
 	switch e {
 	case NotificationMethodSmtp,
 		NotificationMethodWebhook,
-		NotificationMethodInbox:
+		NotificationMethodInbox,
+		NotificationMethodSlack,
+		NotificationMethodTeams:
 		return true
 	}
 	return false
@@ -992,6 +1142,69 @@ func AllNotificationMethodValues() []NotificationMethod {
 		NotificationMethodSmtp,
 		NotificationMethodWebhook,
 		NotificationMethodInbox,
+		NotificationMethodSlack,
+		NotificationMethodTeams,
+	}
+}
+
+type NotificationPriority string
+
+const (
+	NotificationPriorityLow    NotificationPriority = "low"
+	NotificationPriorityNormal NotificationPriority = "normal"
+	NotificationPriorityHigh   NotificationPriority = "high"
+)
+
+func (e *NotificationPriority) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationPriority(s)
+	case string:
+		*e = NotificationPriority(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationPriority: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationPriority struct {
+	NotificationPriority NotificationPriority `json:"notification_priority"`
+	Valid                bool                 `json:"valid"` // Valid is true if NotificationPriority is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationPriority) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationPriority, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationPriority.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationPriority) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationPriority), nil
+}
+
+func (e NotificationPriority) Valid() bool {
+	switch e {
+	case NotificationPriorityLow,
+		NotificationPriorityNormal,
+		NotificationPriorityHigh:
+		return true
+	}
+	return false
+}
+
+func AllNotificationPriorityValues() []NotificationPriority {
+	return []NotificationPriority{
+		NotificationPriorityLow,
+		NotificationPriorityNormal,
+		NotificationPriorityHigh,
 	}
 }
 
@@ -1870,31 +2083,38 @@ func AllProvisionerTypeValues() []ProvisionerType {
 type ResourceType string
 
 const (
-	ResourceTypeOrganization                ResourceType = "organization"
-	ResourceTypeTemplate                    ResourceType = "template"
-	ResourceTypeTemplateVersion             ResourceType = "template_version"
-	ResourceTypeUser                        ResourceType = "user"
-	ResourceTypeWorkspace                   ResourceType = "workspace"
-	ResourceTypeGitSshKey                   ResourceType = "git_ssh_key"
-	ResourceTypeApiKey                      ResourceType = "api_key"
-	ResourceTypeGroup                       ResourceType = "group"
-	ResourceTypeWorkspaceBuild              ResourceType = "workspace_build"
-	ResourceTypeLicense                     ResourceType = "license"
-	ResourceTypeWorkspaceProxy              ResourceType = "workspace_proxy"
-	ResourceTypeConvertLogin                ResourceType = "convert_login"
-	ResourceTypeHealthSettings              ResourceType = "health_settings"
-	ResourceTypeOauth2ProviderApp           ResourceType = "oauth2_provider_app"
-	ResourceTypeOauth2ProviderAppSecret     ResourceType = "oauth2_provider_app_secret"
-	ResourceTypeCustomRole                  ResourceType = "custom_role"
-	ResourceTypeOrganizationMember          ResourceType = "organization_member"
-	ResourceTypeNotificationsSettings       ResourceType = "notifications_settings"
-	ResourceTypeNotificationTemplate        ResourceType = "notification_template"
-	ResourceTypeIdpSyncSettingsOrganization ResourceType = "idp_sync_settings_organization"
-	ResourceTypeIdpSyncSettingsGroup        ResourceType = "idp_sync_settings_group"
-	ResourceTypeIdpSyncSettingsRole         ResourceType = "idp_sync_settings_role"
-	ResourceTypeWorkspaceAgent              ResourceType = "workspace_agent"
-	ResourceTypeWorkspaceApp                ResourceType = "workspace_app"
-	ResourceTypePrebuildsSettings           ResourceType = "prebuilds_settings"
+	ResourceTypeOrganization                     ResourceType = "organization"
+	ResourceTypeTemplate                         ResourceType = "template"
+	ResourceTypeTemplateVersion                  ResourceType = "template_version"
+	ResourceTypeUser                             ResourceType = "user"
+	ResourceTypeWorkspace                        ResourceType = "workspace"
+	ResourceTypeGitSshKey                        ResourceType = "git_ssh_key"
+	ResourceTypeApiKey                           ResourceType = "api_key"
+	ResourceTypeGroup                            ResourceType = "group"
+	ResourceTypeWorkspaceBuild                   ResourceType = "workspace_build"
+	ResourceTypeLicense                          ResourceType = "license"
+	ResourceTypeWorkspaceProxy                   ResourceType = "workspace_proxy"
+	ResourceTypeConvertLogin                     ResourceType = "convert_login"
+	ResourceTypeHealthSettings                   ResourceType = "health_settings"
+	ResourceTypeOauth2ProviderApp                ResourceType = "oauth2_provider_app"
+	ResourceTypeOauth2ProviderAppSecret          ResourceType = "oauth2_provider_app_secret"
+	ResourceTypeCustomRole                       ResourceType = "custom_role"
+	ResourceTypeOrganizationMember               ResourceType = "organization_member"
+	ResourceTypeNotificationsSettings            ResourceType = "notifications_settings"
+	ResourceTypeNotificationTemplate             ResourceType = "notification_template"
+	ResourceTypeIdpSyncSettingsOrganization      ResourceType = "idp_sync_settings_organization"
+	ResourceTypeIdpSyncSettingsGroup             ResourceType = "idp_sync_settings_group"
+	ResourceTypeIdpSyncSettingsRole              ResourceType = "idp_sync_settings_role"
+	ResourceTypeWorkspaceAgent                   ResourceType = "workspace_agent"
+	ResourceTypeWorkspaceApp                     ResourceType = "workspace_app"
+	ResourceTypePrebuildsSettings                ResourceType = "prebuilds_settings"
+	ResourceTypeTemplateVersionActivationRequest ResourceType = "template_version_activation_request"
+	ResourceTypeUserSecret                       ResourceType = "user_secret"
+	ResourceTypeTemplateSecret                   ResourceType = "template_secret"
+	ResourceTypeTemplateEgressPolicy             ResourceType = "template_egress_policy"
+	ResourceTypeWorkspacePortShareLink           ResourceType = "workspace_port_share_link"
+	ResourceTypeVariableSet                      ResourceType = "variable_set"
+	ResourceTypeTemplateVersionRollout           ResourceType = "template_version_rollout"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -1958,7 +2178,14 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeIdpSyncSettingsRole,
 		ResourceTypeWorkspaceAgent,
 		ResourceTypeWorkspaceApp,
-		ResourceTypePrebuildsSettings:
+		ResourceTypePrebuildsSettings,
+		ResourceTypeTemplateVersionActivationRequest,
+		ResourceTypeUserSecret,
+		ResourceTypeTemplateSecret,
+		ResourceTypeTemplateEgressPolicy,
+		ResourceTypeWorkspacePortShareLink,
+		ResourceTypeVariableSet,
+		ResourceTypeTemplateVersionRollout:
 		return true
 	}
 	return false
@@ -1991,6 +2218,13 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeWorkspaceAgent,
 		ResourceTypeWorkspaceApp,
 		ResourceTypePrebuildsSettings,
+		ResourceTypeTemplateVersionActivationRequest,
+		ResourceTypeUserSecret,
+		ResourceTypeTemplateSecret,
+		ResourceTypeTemplateEgressPolicy,
+		ResourceTypeWorkspacePortShareLink,
+		ResourceTypeVariableSet,
+		ResourceTypeTemplateVersionRollout,
 	}
 }
 
@@ -2110,6 +2344,137 @@ func AllTailnetStatusValues() []TailnetStatus {
 	}
 }
 
+type TemplateVersionActivationRequestStatus string
+
+const (
+	TemplateVersionActivationRequestStatusPending  TemplateVersionActivationRequestStatus = "pending"
+	TemplateVersionActivationRequestStatusApproved TemplateVersionActivationRequestStatus = "approved"
+	TemplateVersionActivationRequestStatusRejected TemplateVersionActivationRequestStatus = "rejected"
+	TemplateVersionActivationRequestStatusCanceled TemplateVersionActivationRequestStatus = "canceled"
+)
+
+func (e *TemplateVersionActivationRequestStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TemplateVersionActivationRequestStatus(s)
+	case string:
+		*e = TemplateVersionActivationRequestStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TemplateVersionActivationRequestStatus: %T", src)
+	}
+	return nil
+}
+
+type NullTemplateVersionActivationRequestStatus struct {
+	TemplateVersionActivationRequestStatus TemplateVersionActivationRequestStatus `json:"template_version_activation_request_status"`
+	Valid                                  bool                                   `json:"valid"` // Valid is true if TemplateVersionActivationRequestStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTemplateVersionActivationRequestStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.TemplateVersionActivationRequestStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TemplateVersionActivationRequestStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTemplateVersionActivationRequestStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TemplateVersionActivationRequestStatus), nil
+}
+
+func (e TemplateVersionActivationRequestStatus) Valid() bool {
+	switch e {
+	case TemplateVersionActivationRequestStatusPending,
+		TemplateVersionActivationRequestStatusApproved,
+		TemplateVersionActivationRequestStatusRejected,
+		TemplateVersionActivationRequestStatusCanceled:
+		return true
+	}
+	return false
+}
+
+func AllTemplateVersionActivationRequestStatusValues() []TemplateVersionActivationRequestStatus {
+	return []TemplateVersionActivationRequestStatus{
+		TemplateVersionActivationRequestStatusPending,
+		TemplateVersionActivationRequestStatusApproved,
+		TemplateVersionActivationRequestStatusRejected,
+		TemplateVersionActivationRequestStatusCanceled,
+	}
+}
+
+type TemplateVersionRolloutStatus string
+
+const (
+	TemplateVersionRolloutStatusInProgress      TemplateVersionRolloutStatus = "in_progress"
+	TemplateVersionRolloutStatusHalted          TemplateVersionRolloutStatus = "halted"
+	TemplateVersionRolloutStatusCompleted       TemplateVersionRolloutStatus = "completed"
+	TemplateVersionRolloutStatusCanceled        TemplateVersionRolloutStatus = "canceled"
+	TemplateVersionRolloutStatusPendingApproval TemplateVersionRolloutStatus = "pending_approval"
+)
+
+func (e *TemplateVersionRolloutStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TemplateVersionRolloutStatus(s)
+	case string:
+		*e = TemplateVersionRolloutStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TemplateVersionRolloutStatus: %T", src)
+	}
+	return nil
+}
+
+type NullTemplateVersionRolloutStatus struct {
+	TemplateVersionRolloutStatus TemplateVersionRolloutStatus `json:"template_version_rollout_status"`
+	Valid                        bool                         `json:"valid"` // Valid is true if TemplateVersionRolloutStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTemplateVersionRolloutStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.TemplateVersionRolloutStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TemplateVersionRolloutStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTemplateVersionRolloutStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TemplateVersionRolloutStatus), nil
+}
+
+func (e TemplateVersionRolloutStatus) Valid() bool {
+	switch e {
+	case TemplateVersionRolloutStatusInProgress,
+		TemplateVersionRolloutStatusHalted,
+		TemplateVersionRolloutStatusCompleted,
+		TemplateVersionRolloutStatusCanceled,
+		TemplateVersionRolloutStatusPendingApproval:
+		return true
+	}
+	return false
+}
+
+func AllTemplateVersionRolloutStatusValues() []TemplateVersionRolloutStatus {
+	return []TemplateVersionRolloutStatus{
+		TemplateVersionRolloutStatusInProgress,
+		TemplateVersionRolloutStatusHalted,
+		TemplateVersionRolloutStatusCompleted,
+		TemplateVersionRolloutStatusCanceled,
+		TemplateVersionRolloutStatusPendingApproval,
+	}
+}
+
 // Defines the users status: active, dormant, or suspended.
 type UserStatus string
 
@@ -2172,6 +2537,68 @@ func AllUserStatusValues() []UserStatus {
 	}
 }
 
+// registration challenges add a credential for a signed in user, login challenges complete a password login with an existing credential, and enrollment challenges complete a password login by adding the first credential of a user.
+type WebAuthnChallengePurpose string
+
+const (
+	WebAuthnChallengePurposeRegistration WebAuthnChallengePurpose = "registration"
+	WebAuthnChallengePurposeLogin        WebAuthnChallengePurpose = "login"
+	WebAuthnChallengePurposeEnrollment   WebAuthnChallengePurpose = "enrollment"
+)
+
+func (e *WebAuthnChallengePurpose) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebAuthnChallengePurpose(s)
+	case string:
+		*e = WebAuthnChallengePurpose(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebAuthnChallengePurpose: %T", src)
+	}
+	return nil
+}
+
+type NullWebAuthnChallengePurpose struct {
+	WebAuthnChallengePurpose WebAuthnChallengePurpose `json:"webauthn_challenge_purpose"`
+	Valid                    bool                     `json:"valid"` // Valid is true if WebAuthnChallengePurpose is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebAuthnChallengePurpose) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebAuthnChallengePurpose, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebAuthnChallengePurpose.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebAuthnChallengePurpose) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebAuthnChallengePurpose), nil
+}
+
+func (e WebAuthnChallengePurpose) Valid() bool {
+	switch e {
+	case WebAuthnChallengePurposeRegistration,
+		WebAuthnChallengePurposeLogin,
+		WebAuthnChallengePurposeEnrollment:
+		return true
+	}
+	return false
+}
+
+func AllWebAuthnChallengePurposeValues() []WebAuthnChallengePurpose {
+	return []WebAuthnChallengePurpose{
+		WebAuthnChallengePurposeRegistration,
+		WebAuthnChallengePurposeLogin,
+		WebAuthnChallengePurposeEnrollment,
+	}
+}
+
 type WorkspaceAgentLifecycleState string
 
 const (
@@ -2750,6 +3177,22 @@ func AllWorkspaceTransitionValues() []WorkspaceTransition {
 	}
 }
 
+// AI tasks that wait for a running task of their owner to stop. Tasks are removed from the queue when their workspace is created.
+type AITaskQueue struct {
+	ID                      uuid.UUID         `db:"id" json:"id"`
+	OwnerID                 uuid.UUID         `db:"owner_id" json:"owner_id"`
+	OrganizationID          uuid.UUID         `db:"organization_id" json:"organization_id"`
+	TemplateVersionID       uuid.UUID         `db:"template_version_id" json:"template_version_id"`
+	TemplateVersionPresetID uuid.NullUUID     `db:"template_version_preset_id" json:"template_version_preset_id"`
+	Name                    string            `db:"name" json:"name"`
+	Prompt                  string            `db:"prompt" json:"prompt"`
+	Status                  AITaskQueueStatus `db:"status" json:"status"`
+	// Why the workspace of the task could not be created, if the status is failed.
+	Error     string    `db:"error" json:"error"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type APIKey struct {
 	ID string `db:"id" json:"id"`
 	// hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.
@@ -2764,6 +3207,12 @@ type APIKey struct {
 	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
 	TokenName       string      `db:"token_name" json:"token_name"`
+	// The User-Agent header of the request that created the key.
+	UserAgent string `db:"user_agent" json:"user_agent"`
+	// A hash of the request headers that identify the device that created the key, used to tell sessions apart.
+	DeviceFingerprint string `db:"device_fingerprint" json:"device_fingerprint"`
+	// The workspace that keys with the workspace_connect scope can connect to. Keys with other scopes are not limited to a workspace.
+	WorkspaceID uuid.NullUUID `db:"workspace_id" json:"workspace_id"`
 }
 
 type AuditLog struct {
@@ -2784,6 +3233,17 @@ type AuditLog struct {
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
 }
 
+// Audit logs of the held user or workspace are never purged, regardless of the retention period of the organization.
+type AuditLogLegalHold struct {
+	ID             uuid.UUID     `db:"id" json:"id"`
+	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
+	UserID         uuid.NullUUID `db:"user_id" json:"user_id"`
+	WorkspaceID    uuid.NullUUID `db:"workspace_id" json:"workspace_id"`
+	Reason         string        `db:"reason" json:"reason"`
+	CreatedBy      uuid.UUID     `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+}
+
 type CryptoKey struct {
 	Feature     CryptoKeyFeature `db:"feature" json:"feature"`
 	Sequence    int32            `db:"sequence" json:"sequence"`
@@ -2846,6 +3306,8 @@ type File struct {
 	Mimetype  string    `db:"mimetype" json:"mimetype"`
 	Data      []byte    `db:"data" json:"data"`
 	ID        uuid.UUID `db:"id" json:"id"`
+	// Whether the contents of the file are kept in object storage under the hash of the file, instead of in the data column.
+	External bool `db:"external" json:"external"`
 }
 
 type GitSSHKey struct {
@@ -2896,6 +3358,26 @@ type GroupMemberTable struct {
 	GroupID uuid.UUID `db:"group_id" json:"group_id"`
 }
 
+// Allowances of quota dimensions other than the daily cost, such as cpu or memory. A dimension is only enforced in an organization if a group of the organization has an allowance for it.
+type GroupQuotaAllowance struct {
+	GroupID   uuid.UUID `db:"group_id" json:"group_id"`
+	Dimension string    `db:"dimension" json:"dimension"`
+	Allowance int64     `db:"allowance" json:"allowance"`
+}
+
+// Scores of the health checks that each replica runs periodically. Scores range from 0 (failing) to 100 (healthy).
+type HealthScore struct {
+	ReplicaID               uuid.UUID `db:"replica_id" json:"replica_id"`
+	CreatedAt               time.Time `db:"created_at" json:"created_at"`
+	Score                   int32     `db:"score" json:"score"`
+	DatabaseScore           int32     `db:"database_score" json:"database_score"`
+	DatabaseLatencyMS       int64     `db:"database_latency_ms" json:"database_latency_ms"`
+	DERPScore               int32     `db:"derp_score" json:"derp_score"`
+	ProvisionerDaemonsScore int32     `db:"provisioner_daemons_score" json:"provisioner_daemons_score"`
+	ReplicasScore           int32     `db:"replicas_score" json:"replicas_score"`
+	WebsocketScore          int32     `db:"websocket_score" json:"websocket_score"`
+}
+
 type InboxNotification struct {
 	ID         uuid.UUID       `db:"id" json:"id"`
 	UserID     uuid.UUID       `db:"user_id" json:"user_id"`
@@ -2918,6 +3400,24 @@ type JfrogXrayScan struct {
 	ResultsUrl  string    `db:"results_url" json:"results_url"`
 }
 
+// Users provisioned or linked by LDAP sync. Linked users are suspended when the directory stops returning them.
+type LDAPUser struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	// Value of the directory attribute that identifies the user, such as objectGUID or entryUUID. Unlike the DN, it does not change when the user is renamed or moved.
+	ExternalID string    `db:"external_id" json:"external_id"`
+	Dn         string    `db:"dn" json:"dn"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Licensed user seats reserved for the members of a group. Reservations are only used to forecast when the seats run out.
+type LicenseSeatReservation struct {
+	GroupID   uuid.UUID `db:"group_id" json:"group_id"`
+	Seats     int32     `db:"seats" json:"seats"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type License struct {
 	ID         int32     `db:"id" json:"id"`
 	UploadedAt time.Time `db:"uploaded_at" json:"uploaded_at"`
@@ -2927,6 +3427,36 @@ type License struct {
 	UUID uuid.UUID `db:"uuid" json:"uuid"`
 }
 
+// Per-user toggles for delivering the notifications of a category on a notification method.
+// Latest network telemetry of every workspace connection, reported by clients and agents to any replica.
+type NetworkConnectionQuality struct {
+	ClientType string `db:"client_type" json:"client_type"`
+	// Tailnet node ID of the reporting side. Node IDs are unsigned 64-bit integers stored bit for bit.
+	NodeIDSelf     int64           `db:"node_id_self" json:"node_id_self"`
+	NodeIDRemote   int64           `db:"node_id_remote" json:"node_id_remote"`
+	ConnectionType string          `db:"connection_type" json:"connection_type"`
+	LatencyMS      sql.NullFloat64 `db:"latency_ms" json:"latency_ms"`
+	// Number of WireGuard handshakes the reporting side had to retry over the lifetime of the connection.
+	HandshakeRetries int64     `db:"handshake_retries" json:"handshake_retries"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Deployment-wide totals of connections falling back from P2P to a DERP relay and of WireGuard handshake retries, by client type.
+type NetworkConnectionQualityCounter struct {
+	ClientType       string `db:"client_type" json:"client_type"`
+	RelayFallbacks   int64  `db:"relay_fallbacks" json:"relay_fallbacks"`
+	HandshakeRetries int64  `db:"handshake_retries" json:"handshake_retries"`
+}
+
+type NotificationCategoryPreference struct {
+	UserID    uuid.UUID            `db:"user_id" json:"user_id"`
+	Category  NotificationCategory `db:"category" json:"category"`
+	Method    NotificationMethod   `db:"method" json:"method"`
+	Disabled  bool                 `db:"disabled" json:"disabled"`
+	CreatedAt time.Time            `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time            `db:"updated_at" json:"updated_at"`
+}
+
 type NotificationMessage struct {
 	ID                     uuid.UUID                 `db:"id" json:"id"`
 	NotificationTemplateID uuid.UUID                 `db:"notification_template_id" json:"notification_template_id"`
@@ -2945,6 +3475,10 @@ type NotificationMessage struct {
 	QueuedSeconds          sql.NullFloat64           `db:"queued_seconds" json:"queued_seconds"`
 	// Auto-generated by insert/update trigger, used to prevent duplicate notifications from being enqueued on the same day
 	DedupeHash sql.NullString `db:"dedupe_hash" json:"dedupe_hash"`
+	// The message will not be dispatched before this time, used to hold messages back until the end of the user's quiet hours.
+	DeliverAfter sql.NullTime `db:"deliver_after" json:"deliver_after"`
+	// The message is batched into the user's next notification digest instead of being dispatched on its own.
+	Digest bool `db:"digest" json:"digest"`
 }
 
 type NotificationPreference struct {
@@ -2973,6 +3507,10 @@ type NotificationTemplate struct {
 	Method           NullNotificationMethod   `db:"method" json:"method"`
 	Kind             NotificationTemplateKind `db:"kind" json:"kind"`
 	EnabledByDefault bool                     `db:"enabled_by_default" json:"enabled_by_default"`
+	// Low priority notifications can be batched into digests, high priority notifications are delivered during quiet hours.
+	Priority NotificationPriority `db:"priority" json:"priority"`
+	// Users can disable all notifications of a category, or only their delivery on some methods. NULL templates can only be disabled individually.
+	Category NullNotificationCategory `db:"category" json:"category"`
 }
 
 // A table used to configure apps that can use Coder as an OAuth2 provider, the reverse of what we are calling external authentication.
@@ -3042,6 +3580,23 @@ type OAuth2ProviderAppCode struct {
 	CodeChallengeMethod sql.NullString `db:"code_challenge_method" json:"code_challenge_method"`
 }
 
+type OAuth2ProviderAppDeviceCode struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt        time.Time `db:"expires_at" json:"expires_at"`
+	DeviceCodePrefix []byte    `db:"device_code_prefix" json:"device_code_prefix"`
+	DeviceCodeHash   []byte    `db:"device_code_hash" json:"device_code_hash"`
+	// The short code users enter to approve the device, without separators.
+	UserCode string    `db:"user_code" json:"user_code"`
+	AppID    uuid.UUID `db:"app_id" json:"app_id"`
+	// The user who approved the device, null while pending.
+	UserID uuid.NullUUID `db:"user_id" json:"user_id"`
+	// RFC 8707 resource parameter for audience restriction
+	ResourceUri sql.NullString `db:"resource_uri" json:"resource_uri"`
+	// Used to tell devices polling too often to slow down.
+	LastPolledAt sql.NullTime `db:"last_polled_at" json:"last_polled_at"`
+}
+
 type OAuth2ProviderAppSecret struct {
 	ID           uuid.UUID    `db:"id" json:"id"`
 	CreatedAt    time.Time    `db:"created_at" json:"created_at"`
@@ -3080,6 +3635,20 @@ type Organization struct {
 	Deleted     bool      `db:"deleted" json:"deleted"`
 }
 
+type OrganizationAuditSetting struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	// Number of days the audit logs of the organization are kept for. Zero keeps them forever.
+	RetentionDays int32     `db:"retention_days" json:"retention_days"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type OrganizationMFASetting struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	// Members of the organization that log in with a password must use a WebAuthn credential as a second factor.
+	RequireWebAuthn bool      `db:"require_webauthn" json:"require_webauthn"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type OrganizationMember struct {
 	UserID         uuid.UUID `db:"user_id" json:"user_id"`
 	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
@@ -3088,6 +3657,24 @@ type OrganizationMember struct {
 	Roles          []string  `db:"roles" json:"roles"`
 }
 
+// Overrides of deployment settings for a single organization. NULL columns inherit the deployment setting.
+type OrganizationSettingOverride struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	// Default autostop in nanoseconds for templates created in the organization without one.
+	DefaultTTL sql.NullInt64 `db:"default_ttl" json:"default_ttl"`
+	// Quiet hours schedule used for workspaces in the organization whose owner has not set their own schedule.
+	DefaultQuietHoursSchedule sql.NullString `db:"default_quiet_hours_schedule" json:"default_quiet_hours_schedule"`
+	// IDs of the external auth providers templates in the organization may require.
+	AllowedExternalAuthProviders []string `db:"allowed_external_auth_providers" json:"allowed_external_auth_providers"`
+	// Notification templates that are never sent for workspaces and templates in the organization.
+	DisabledNotificationTemplateIDs []uuid.UUID `db:"disabled_notification_template_ids" json:"disabled_notification_template_ids"`
+	UpdatedAt                       time.Time   `db:"updated_at" json:"updated_at"`
+	// Maximum number of provisioner jobs of the organization that may run concurrently. 0 means unlimited.
+	ProvisionerMaxJobs sql.NullInt32 `db:"provisioner_max_jobs" json:"provisioner_max_jobs"`
+	// Number of concurrent provisioner jobs reserved for the organization out of the shared capacity of the deployment.
+	ProvisionerReservedJobs sql.NullInt32 `db:"provisioner_reserved_jobs" json:"provisioner_reserved_jobs"`
+}
+
 type ParameterSchema struct {
 	ID                       uuid.UUID                  `db:"id" json:"id"`
 	CreatedAt                time.Time                  `db:"created_at" json:"created_at"`
@@ -3168,6 +3755,14 @@ type ProvisionerJobLog struct {
 	ID        int64     `db:"id" json:"id"`
 }
 
+// Records each time a running provisioner job was returned to the queue because its provisioner daemon stopped sending heartbeats.
+type ProvisionerJobRequeue struct {
+	JobID uuid.UUID `db:"job_id" json:"job_id"`
+	// The ID of the provisioner daemon that was running the job when it was requeued.
+	WorkerID  uuid.NullUUID `db:"worker_id" json:"worker_id"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+}
+
 type ProvisionerJobStat struct {
 	JobID          uuid.UUID            `db:"job_id" json:"job_id"`
 	JobStatus      ProvisionerJobStatus `db:"job_status" json:"job_status"`
@@ -3219,6 +3814,29 @@ type Replica struct {
 	Primary         bool         `db:"primary" json:"primary"`
 }
 
+// Workspaces created by CI for a branch of a repository. They are deleted when the branch is deleted.
+type ReviewWorkspace struct {
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	// Lowercased full name of the repository, e.g. "coder/coder".
+	Repository string    `db:"repository" json:"repository"`
+	Branch     string    `db:"branch" json:"branch"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Users the identity provider assigned to a SCIM group. Group members are derived from this, and from scim_nested_groups when nested groups are flattened.
+type SCIMGroupMember struct {
+	GroupID uuid.UUID `db:"group_id" json:"group_id"`
+	UserID  uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+// Groups the identity provider assigned as members of a SCIM group.
+type SCIMNestedGroup struct {
+	GroupID       uuid.UUID `db:"group_id" json:"group_id"`
+	MemberGroupID uuid.UUID `db:"member_group_id" json:"member_group_id"`
+}
+
 type SiteConfig struct {
 	Key   string `db:"key" json:"key"`
 	Value string `db:"value" json:"value"`
@@ -3304,6 +3922,15 @@ type Template struct {
 	ActivityBump                  int64           `db:"activity_bump" json:"activity_bump"`
 	MaxPortSharingLevel           AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
 	UseClassicParameterFlow       bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	WireguardMTU                  int32           `db:"wireguard_mtu" json:"wireguard_mtu"`
+	WireguardKeepaliveInterval    int64           `db:"wireguard_keepalive_interval" json:"wireguard_keepalive_interval"`
+	WireguardHandshakeTimeout     int64           `db:"wireguard_handshake_timeout" json:"wireguard_handshake_timeout"`
+	ActivationApprovalsRequired   int32           `db:"activation_approvals_required" json:"activation_approvals_required"`
+	AutostartHolidays             []string        `db:"autostart_holidays" json:"autostart_holidays"`
+	AutostartHolidayCalendarURL   string          `db:"autostart_holiday_calendar_url" json:"autostart_holiday_calendar_url"`
+	DormantArchivePaths           []string        `db:"dormant_archive_paths" json:"dormant_archive_paths"`
+	ParameterValidationURL        string          `db:"parameter_validation_url" json:"parameter_validation_url"`
+	AllowStartOnConnect           bool            `db:"allow_start_on_connect" json:"allow_start_on_connect"`
 	CreatedByAvatarURL            string          `db:"created_by_avatar_url" json:"created_by_avatar_url"`
 	CreatedByUsername             string          `db:"created_by_username" json:"created_by_username"`
 	CreatedByName                 string          `db:"created_by_name" json:"created_by_name"`
@@ -3312,6 +3939,87 @@ type Template struct {
 	OrganizationIcon              string          `db:"organization_icon" json:"organization_icon"`
 }
 
+// Users designated to review the activation requests of template versions. When a template has none, users that can update the template review them.
+type TemplateActivationReviewer struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+// Workspace apps of a template that are only visible and accessible to members of some groups or users with some roles.
+type TemplateAppRestriction struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	AppSlug    string    `db:"app_slug" json:"app_slug"`
+	// The groups whose members can access the app.
+	GroupIds []uuid.UUID `db:"group_ids" json:"group_ids"`
+	// The names of the site roles and roles of the template organization whose users can access the app.
+	Roles     []string  `db:"roles" json:"roles"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// How long after authenticating users can access the workspace apps of a template before they must authenticate again.
+type TemplateAppSessionPolicy struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	// The slug of the app the policy applies to. The policy with an empty slug applies to all apps, ports and terminals of the template without their own policy.
+	AppSlug string `db:"app_slug" json:"app_slug"`
+	// The maximum time since the user authenticated, in nanoseconds.
+	MaxSessionDuration int64     `db:"max_session_duration" json:"max_session_duration"`
+	CreatedAt          time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Self-contained template archives, including the modules and providers they require, that can be pushed to and pulled from a deployment without internet access.
+type TemplateBundle struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+	Version        string    `db:"version" json:"version"`
+	Hash           string    `db:"hash" json:"hash"`
+	// The manifest of the bundle, describing the providers and modules it contains.
+	Manifest  json.RawMessage `db:"manifest" json:"manifest"`
+	Data      []byte          `db:"data" json:"data"`
+	CreatedBy uuid.UUID       `db:"created_by" json:"created_by"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+// Destinations the workspaces of a template may connect to. Agents block all other outbound traffic on the workspace host while a template has a policy.
+type TemplateEgressPolicy struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	// IP addresses and CIDR ranges workspaces may connect to.
+	AllowedCidrs []string `db:"allowed_cidrs" json:"allowed_cidrs"`
+	// Domain names workspaces may connect to. Agents resolve them periodically and allow the resulting addresses.
+	AllowedDomains []string  `db:"allowed_domains" json:"allowed_domains"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// APIs from which the options of template parameters are fetched when the parameter form is rendered, instead of being defined in the template.
+type TemplateParameterOptionSource struct {
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	ParameterName  string    `db:"parameter_name" json:"parameter_name"`
+	Url            string    `db:"url" json:"url"`
+	AuthHeaderName string    `db:"auth_header_name" json:"auth_header_name"`
+	// The value of the header that coderd adds to requests to the API. It is never returned to users.
+	AuthHeaderValue string `db:"auth_header_value" json:"auth_header_value"`
+	// How long fetched options are cached, in nanoseconds.
+	CacheTTL  int64     `db:"cache_ttl" json:"cache_ttl"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Secrets of a template. They are passed to workspace builds as the value of the Terraform variable with the same name.
+type TemplateSecret struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	TemplateID  uuid.UUID `db:"template_id" json:"template_id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	Value       string    `db:"value" json:"value"`
+	// The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted
+	ValueKeyID sql.NullString `db:"value_key_id" json:"value_key_id"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at" json:"updated_at"`
+}
+
 type TemplateTable struct {
 	ID              uuid.UUID       `db:"id" json:"id"`
 	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
@@ -3352,6 +4060,24 @@ type TemplateTable struct {
 	MaxPortSharingLevel AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
 	// Determines whether to default to the dynamic parameter creation flow for this template or continue using the legacy classic parameter creation flow.This is a template wide setting, the template admin can revert to the classic flow if there are any issues. An escape hatch is required, as workspace creation is a core workflow and cannot break. This column will be removed when the dynamic parameter creation flow is stable.
 	UseClassicParameterFlow bool `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	// Overrides the deployment WireGuard MTU for workspaces created from this template. 0 uses the deployment value.
+	WireguardMTU int32 `db:"wireguard_mtu" json:"wireguard_mtu"`
+	// Overrides the deployment WireGuard keepalive interval, in nanoseconds. 0 uses the deployment value.
+	WireguardKeepaliveInterval int64 `db:"wireguard_keepalive_interval" json:"wireguard_keepalive_interval"`
+	// Overrides the deployment WireGuard handshake timeout, in nanoseconds. 0 uses the deployment value.
+	WireguardHandshakeTimeout int64 `db:"wireguard_handshake_timeout" json:"wireguard_handshake_timeout"`
+	// The number of reviewer approvals required before a template version can be made active. 0 disables the approval gate.
+	ActivationApprovalsRequired int32 `db:"activation_approvals_required" json:"activation_approvals_required"`
+	// Dates formatted as YYYY-MM-DD on which workspaces of this template are not autostarted.
+	AutostartHolidays []string `db:"autostart_holidays" json:"autostart_holidays"`
+	// URL of an iCalendar feed whose events are treated as autostart holidays. Empty disables the calendar.
+	AutostartHolidayCalendarURL string `db:"autostart_holiday_calendar_url" json:"autostart_holiday_calendar_url"`
+	// Paths in workspaces of this template that are archived to object storage before dormant workspaces are auto-deleted. Relative paths are relative to the home directory of the agent.
+	DormantArchivePaths []string `db:"dormant_archive_paths" json:"dormant_archive_paths"`
+	// URL of a webhook that validates the parameter values of workspaces created or updated from this template. Empty disables the webhook.
+	ParameterValidationURL string `db:"parameter_validation_url" json:"parameter_validation_url"`
+	// Allow clients such as coder ssh and IDE extensions to start stopped workspaces of this template when connecting to them.
+	AllowStartOnConnect bool `db:"allow_start_on_connect" json:"allow_start_on_connect"`
 }
 
 // Records aggregated usage statistics for templates/users. All usage is rounded up to the nearest minute.
@@ -3382,6 +4108,13 @@ type TemplateUsageStat struct {
 	AppUsageMins StringMapOfInt `db:"app_usage_mins" json:"app_usage_mins"`
 }
 
+// Variable sets attached to a template. Their values are passed to the provisioner jobs of the template.
+type TemplateVariableSet struct {
+	TemplateID    uuid.UUID `db:"template_id" json:"template_id"`
+	VariableSetID uuid.UUID `db:"variable_set_id" json:"variable_set_id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
+
 // Joins in the username + avatar url of the created by user.
 type TemplateVersion struct {
 	ID                    uuid.UUID       `db:"id" json:"id"`
@@ -3403,6 +4136,27 @@ type TemplateVersion struct {
 	CreatedByName         string          `db:"created_by_name" json:"created_by_name"`
 }
 
+// Requests to make a template version active, for templates which require approvals before activating a version.
+type TemplateVersionActivationRequest struct {
+	TemplateVersionID uuid.UUID                              `db:"template_version_id" json:"template_version_id"`
+	TemplateID        uuid.UUID                              `db:"template_id" json:"template_id"`
+	RequestedBy       uuid.UUID                              `db:"requested_by" json:"requested_by"`
+	Status            TemplateVersionActivationRequestStatus `db:"status" json:"status"`
+	// The number of approvals the template required when the activation was requested.
+	ApprovalsRequired int32     `db:"approvals_required" json:"approvals_required"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Approvals and rejections of template version activation requests.
+type TemplateVersionActivationReview struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	ReviewerID        uuid.UUID `db:"reviewer_id" json:"reviewer_id"`
+	Approved          bool      `db:"approved" json:"approved"`
+	Comment           string    `db:"comment" json:"comment"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+}
+
 type TemplateVersionParameter struct {
 	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
 	// Parameter name
@@ -3467,6 +4221,24 @@ type TemplateVersionPresetPrebuildSchedule struct {
 	DesiredInstances int32     `db:"desired_instances" json:"desired_instances"`
 }
 
+// Gradual rollouts of template versions to a cohort of workspaces before the version is made active for every workspace. A template has at most one rollout, starting a new one replaces the previous one.
+type TemplateVersionRollout struct {
+	TemplateID        uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	CreatedBy         uuid.UUID `db:"created_by" json:"created_by"`
+	// The percentage of the workspaces of the template that are built with the rolled out version.
+	Percentage int32 `db:"percentage" json:"percentage"`
+	// The groups whose members' workspaces are built with the rolled out version, regardless of the percentage.
+	GroupIds []uuid.UUID `db:"group_ids" json:"group_ids"`
+	// The rollout is halted once the percentage of failed builds of the rolled out version exceeds this threshold.
+	FailureThresholdPercent int32 `db:"failure_threshold_percent" json:"failure_threshold_percent"`
+	// The number of builds of the rolled out version required before the failure threshold is evaluated.
+	MinimumBuilds int32                        `db:"minimum_builds" json:"minimum_builds"`
+	Status        TemplateVersionRolloutStatus `db:"status" json:"status"`
+	CreatedAt     time.Time                    `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time                    `db:"updated_at" json:"updated_at"`
+}
+
 type TemplateVersionTable struct {
 	ID             uuid.UUID     `db:"id" json:"id"`
 	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
@@ -3546,6 +4318,13 @@ type User struct {
 	IsSystem bool `db:"is_system" json:"is_system"`
 }
 
+// The version of the CLI each user last made an API request with.
+type UserCLIVersion struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	Version    string    `db:"version" json:"version"`
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+}
+
 type UserConfig struct {
 	UserID uuid.UUID `db:"user_id" json:"user_id"`
 	Key    string    `db:"key" json:"key"`
@@ -3574,6 +4353,29 @@ type UserLink struct {
 	Claims UserLinkClaims `db:"claims" json:"claims"`
 }
 
+// Single use codes that replace a WebAuthn credential when completing a password login.
+type UserMFARecoveryCode struct {
+	UserID     uuid.UUID    `db:"user_id" json:"user_id"`
+	HashedCode []byte       `db:"hashed_code" json:"hashed_code"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+	UsedAt     sql.NullTime `db:"used_at" json:"used_at"`
+}
+
+// Secrets owned by a user. Secrets with an environment variable name can be read by the agents of the user's workspaces.
+type UserSecret struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	Value       string    `db:"value" json:"value"`
+	// The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted
+	ValueKeyID sql.NullString `db:"value_key_id" json:"value_key_id"`
+	// The name of the environment variable the secret is exposed as in workspaces. Empty if it is not exposed.
+	EnvName   string    `db:"env_name" json:"env_name"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
 // Tracks the history of user status changes
 type UserStatusChange struct {
 	ID        uuid.UUID  `db:"id" json:"id"`
@@ -3582,6 +4384,33 @@ type UserStatusChange struct {
 	ChangedAt time.Time  `db:"changed_at" json:"changed_at"`
 }
 
+// WebAuthn credentials, such as security keys and passkeys, used as a second factor for password logins.
+type UserWebAuthnCredential struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	Name         string    `db:"name" json:"name"`
+	CredentialID []byte    `db:"credential_id" json:"credential_id"`
+	// The COSE encoded public key of the credential.
+	PublicKey []byte `db:"public_key" json:"public_key"`
+	// The last signature counter reported by the authenticator, used to detect cloned authenticators.
+	SignCount  int64        `db:"sign_count" json:"sign_count"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+}
+
+// Named sets of Terraform variable values that can be attached to templates.
+type VariableSet struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	// A JSON array of the variables in the set, including their values.
+	Variables string `db:"variables" json:"variables"`
+	// The ID of the key used to encrypt the variables. If this is NULL, the variables are not encrypted
+	VariablesKeyID sql.NullString `db:"variables_key_id" json:"variables_key_id"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+}
+
 // Visible fields of users are allowed to be joined with other tables for including context of other resources.
 type VisibleUser struct {
 	ID        uuid.UUID `db:"id" json:"id"`
@@ -3590,6 +4419,16 @@ type VisibleUser struct {
 	AvatarURL string    `db:"avatar_url" json:"avatar_url"`
 }
 
+// Pending WebAuthn ceremonies. Challenges are deleted when they are used.
+type WebAuthnChallenge struct {
+	ID        uuid.UUID                `db:"id" json:"id"`
+	UserID    uuid.UUID                `db:"user_id" json:"user_id"`
+	Purpose   WebAuthnChallengePurpose `db:"purpose" json:"purpose"`
+	Challenge []byte                   `db:"challenge" json:"challenge"`
+	CreatedAt time.Time                `db:"created_at" json:"created_at"`
+	ExpiresAt time.Time                `db:"expires_at" json:"expires_at"`
+}
+
 type WebpushSubscription struct {
 	ID                uuid.UUID `db:"id" json:"id"`
 	UserID            uuid.UUID `db:"user_id" json:"user_id"`
@@ -3617,6 +4456,7 @@ type Workspace struct {
 	AutomaticUpdates        AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
 	Favorite                bool             `db:"favorite" json:"favorite"`
 	NextStartAt             sql.NullTime     `db:"next_start_at" json:"next_start_at"`
+	Ephemeral               bool             `db:"ephemeral" json:"ephemeral"`
 	OwnerAvatarUrl          string           `db:"owner_avatar_url" json:"owner_avatar_url"`
 	OwnerUsername           string           `db:"owner_username" json:"owner_username"`
 	OwnerName               string           `db:"owner_name" json:"owner_name"`
@@ -3630,6 +4470,23 @@ type Workspace struct {
 	TemplateDescription     string           `db:"template_description" json:"template_description"`
 }
 
+// Machines that are not managed by a provisioner and were adopted as workspaces. A row is created with a one-time token that the machine exchanges for a workspace and an agent token.
+type WorkspaceAdoption struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	WorkspaceName  string    `db:"workspace_name" json:"workspace_name"`
+	HashedSecret   []byte    `db:"hashed_secret" json:"hashed_secret"`
+	CreatedBy      uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt      time.Time `db:"expires_at" json:"expires_at"`
+	// The workspace created when the machine was adopted. NULL until the token is used.
+	WorkspaceID uuid.NullUUID `db:"workspace_id" json:"workspace_id"`
+	Hostname    string        `db:"hostname" json:"hostname"`
+	AdoptedAt   sql.NullTime  `db:"adopted_at" json:"adopted_at"`
+}
+
 type WorkspaceAgent struct {
 	ID                   uuid.UUID             `db:"id" json:"id"`
 	CreatedAt            time.Time             `db:"created_at" json:"created_at"`
@@ -3705,6 +4562,14 @@ type WorkspaceAgentLog struct {
 	LogSourceID uuid.UUID `db:"log_source_id" json:"log_source_id"`
 }
 
+// Tracks the history of workspace agent lifecycle state transitions
+type WorkspaceAgentLifecycleEvent struct {
+	ID               uuid.UUID                    `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID                    `db:"workspace_agent_id" json:"workspace_agent_id"`
+	LifecycleState   WorkspaceAgentLifecycleState `db:"lifecycle_state" json:"lifecycle_state"`
+	CreatedAt        time.Time                    `db:"created_at" json:"created_at"`
+}
+
 type WorkspaceAgentLogSource struct {
 	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
 	ID               uuid.UUID `db:"id" json:"id"`
@@ -3790,6 +4655,14 @@ type WorkspaceAgentStat struct {
 	Usage                       bool            `db:"usage" json:"usage"`
 }
 
+// The last auth token rotation of each workspace agent. The previous token keeps authenticating the agent until it expires.
+type WorkspaceAgentTokenRotation struct {
+	AgentID                    uuid.UUID `db:"agent_id" json:"agent_id"`
+	PreviousAuthToken          uuid.UUID `db:"previous_auth_token" json:"previous_auth_token"`
+	PreviousAuthTokenExpiresAt time.Time `db:"previous_auth_token_expires_at" json:"previous_auth_token_expires_at"`
+	RotatedAt                  time.Time `db:"rotated_at" json:"rotated_at"`
+}
+
 type WorkspaceAgentVolumeResourceMonitor struct {
 	AgentID        uuid.UUID                  `db:"agent_id" json:"agent_id"`
 	Enabled        bool                       `db:"enabled" json:"enabled"`
@@ -3801,6 +4674,15 @@ type WorkspaceAgentVolumeResourceMonitor struct {
 	DebouncedUntil time.Time                  `db:"debounced_until" json:"debounced_until"`
 }
 
+// The most recent filesystem usage reported by workspace agents for their home directory and monitored volumes.
+type WorkspaceAgentVolumeUsage struct {
+	AgentID     uuid.UUID `db:"agent_id" json:"agent_id"`
+	Path        string    `db:"path" json:"path"`
+	TotalBytes  int64     `db:"total_bytes" json:"total_bytes"`
+	UsedBytes   int64     `db:"used_bytes" json:"used_bytes"`
+	CollectedAt time.Time `db:"collected_at" json:"collected_at"`
+}
+
 type WorkspaceApp struct {
 	ID                   uuid.UUID          `db:"id" json:"id"`
 	CreatedAt            time.Time          `db:"created_at" json:"created_at"`
@@ -3883,6 +4765,25 @@ type WorkspaceAppStatus struct {
 	Uri         sql.NullString          `db:"uri" json:"uri"`
 }
 
+// Archives of the persistent paths of dormant workspaces taken before the workspaces are auto-deleted.
+type WorkspaceArchive struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName  string    `db:"workspace_name" json:"workspace_name"`
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	Paths          []string  `db:"paths" json:"paths"`
+	// Key of the archive in the archive store. Empty if the archive failed.
+	ObjectKey string `db:"object_key" json:"object_key"`
+	SizeBytes int64  `db:"size_bytes" json:"size_bytes"`
+	// Why the archive failed. Empty if the archive succeeded.
+	Error     string    `db:"error" json:"error"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	// The archive is deleted from the archive store after this time.
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+}
+
 // Joins in the username + avatar url of the initiated by user.
 type WorkspaceBuild struct {
 	ID                      uuid.UUID           `db:"id" json:"id"`
@@ -3902,6 +4803,9 @@ type WorkspaceBuild struct {
 	TemplateVersionPresetID uuid.NullUUID       `db:"template_version_preset_id" json:"template_version_preset_id"`
 	HasAITask               sql.NullBool        `db:"has_ai_task" json:"has_ai_task"`
 	AITaskSidebarAppID      uuid.NullUUID       `db:"ai_task_sidebar_app_id" json:"ai_task_sidebar_app_id"`
+	ReasonMessage           string              `db:"reason_message" json:"reason_message"`
+	InitiatorAPIKeyName     string              `db:"initiator_api_key_name" json:"initiator_api_key_name"`
+	InitiatorCIJobURL       string              `db:"initiator_ci_job_url" json:"initiator_ci_job_url"`
 	InitiatorByAvatarUrl    string              `db:"initiator_by_avatar_url" json:"initiator_by_avatar_url"`
 	InitiatorByUsername     string              `db:"initiator_by_username" json:"initiator_by_username"`
 	InitiatorByName         string              `db:"initiator_by_name" json:"initiator_by_name"`
@@ -3915,6 +4819,13 @@ type WorkspaceBuildParameter struct {
 	Value string `db:"value" json:"value"`
 }
 
+// Costs of a workspace build in quota dimensions other than the daily cost.
+type WorkspaceBuildQuotaCost struct {
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	Dimension        string    `db:"dimension" json:"dimension"`
+	Cost             int64     `db:"cost" json:"cost"`
+}
+
 type WorkspaceBuildTable struct {
 	ID                      uuid.UUID           `db:"id" json:"id"`
 	CreatedAt               time.Time           `db:"created_at" json:"created_at"`
@@ -3933,6 +4844,19 @@ type WorkspaceBuildTable struct {
 	TemplateVersionPresetID uuid.NullUUID       `db:"template_version_preset_id" json:"template_version_preset_id"`
 	HasAITask               sql.NullBool        `db:"has_ai_task" json:"has_ai_task"`
 	AITaskSidebarAppID      uuid.NullUUID       `db:"ai_task_sidebar_app_id" json:"ai_task_sidebar_app_id"`
+	// Free-form explanation of why the build was started, given by the initiator.
+	ReasonMessage string `db:"reason_message" json:"reason_message"`
+	// The name of the API token the build was started with, if any.
+	InitiatorAPIKeyName string `db:"initiator_api_key_name" json:"initiator_api_key_name"`
+	// The URL of the CI job that started the build, if any.
+	InitiatorCIJobURL string `db:"initiator_ci_job_url" json:"initiator_ci_job_url"`
+}
+
+// Free-form key/value labels used to group workspaces, e.g. by project or cost center, and to target them in searches and bulk operations.
+type WorkspaceLabel struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Key         string    `db:"key" json:"key"`
+	Value       string    `db:"value" json:"value"`
 }
 
 type WorkspaceLatestBuild struct {
@@ -3956,6 +4880,24 @@ type WorkspaceModule struct {
 	CreatedAt  time.Time           `db:"created_at" json:"created_at"`
 }
 
+// Time-limited links that give anyone access to a workspace port, optionally protected by a password.
+type WorkspacePortShareLink struct {
+	ID           uuid.UUID         `db:"id" json:"id"`
+	WorkspaceID  uuid.UUID         `db:"workspace_id" json:"workspace_id"`
+	AgentName    string            `db:"agent_name" json:"agent_name"`
+	Port         int32             `db:"port" json:"port"`
+	Protocol     PortShareProtocol `db:"protocol" json:"protocol"`
+	HashedSecret []byte            `db:"hashed_secret" json:"hashed_secret"`
+	// The hashed password of the link, or empty if the link does not require a password.
+	HashedPassword string `db:"hashed_password" json:"hashed_password"`
+	// The number of bytes that can be transferred through the link, or 0 if it is not limited.
+	BandwidthLimitBytes int64     `db:"bandwidth_limit_bytes" json:"bandwidth_limit_bytes"`
+	BytesTransferred    int64     `db:"bytes_transferred" json:"bytes_transferred"`
+	ExpiresAt           time.Time `db:"expires_at" json:"expires_at"`
+	CreatedBy           uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+}
+
 type WorkspacePrebuild struct {
 	ID              uuid.UUID     `db:"id" json:"id"`
 	Name            string        `db:"name" json:"name"`
@@ -3998,6 +4940,14 @@ type WorkspaceProxy struct {
 	Version  string `db:"version" json:"version"`
 }
 
+// The last token rotation of each workspace proxy. The previous token keeps authenticating the proxy until it expires.
+type WorkspaceProxyTokenRotation struct {
+	ProxyID                   uuid.UUID `db:"proxy_id" json:"proxy_id"`
+	PreviousTokenHashedSecret []byte    `db:"previous_token_hashed_secret" json:"previous_token_hashed_secret"`
+	PreviousTokenExpiresAt    time.Time `db:"previous_token_expires_at" json:"previous_token_expires_at"`
+	RotatedAt                 time.Time `db:"rotated_at" json:"rotated_at"`
+}
+
 type WorkspaceResource struct {
 	ID           uuid.UUID           `db:"id" json:"id"`
 	CreatedAt    time.Time           `db:"created_at" json:"created_at"`
@@ -4020,6 +4970,15 @@ type WorkspaceResourceMetadatum struct {
 	ID                  int64          `db:"id" json:"id"`
 }
 
+// Bounded pauses of the autostop, dormancy and auto-delete schedules of workspaces, e.g. while their owners are on leave.
+type WorkspaceSchedulePause struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// The pause ends at this time. Expired pauses are kept so the end of the pause can count as workspace activity for dormancy.
+	PausedUntil time.Time `db:"paused_until" json:"paused_until"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	CreatedBy   uuid.UUID `db:"created_by" json:"created_by"`
+}
+
 type WorkspaceTable struct {
 	ID                uuid.UUID        `db:"id" json:"id"`
 	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
@@ -4038,4 +4997,6 @@ type WorkspaceTable struct {
 	// Favorite is true if the workspace owner has favorited the workspace.
 	Favorite    bool         `db:"favorite" json:"favorite"`
 	NextStartAt sql.NullTime `db:"next_start_at" json:"next_start_at"`
+	// Ephemeral workspaces are deleted instead of stopped. Their quota is only consumed while they are running.
+	Ephemeral bool `db:"ephemeral" json:"ephemeral"`
 }
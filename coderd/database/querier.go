@@ -36,6 +36,18 @@ type sqlcQuerier interface {
 	// SKIP LOCKED is used to jump over locked rows. This prevents
 	// multiple provisioners from acquiring the same jobs. See:
 	// https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
+	//
+	// Workspace builds are skipped, and so stay queued, while the workspace
+	// owner or template already has the maximum number of running builds.
+	//
+	// No job is acquired while the organization has the maximum number of
+	// running jobs, or while the rest of the shared capacity of the deployment
+	// is reserved for other organizations.
+	//
+	// The running jobs are counted without locking them, so two concurrent calls
+	// could both admit a job into the last free slot. Callers serialize admission
+	// by holding an advisory lock for the transaction, see
+	// provisionerdserver.Acquirer.
 	AcquireProvisionerJob(ctx context.Context, arg AcquireProvisionerJobParams) (ProvisionerJob, error)
 	// Bumps the workspace deadline by the template's configured "activity_bump"
 	// duration (default 1h). If the workspace bump will cross an autostart
@@ -49,6 +61,7 @@ type sqlcQuerier interface {
 	// We only bump if workspace shutdown is manual.
 	// We only bump when 5% of the deadline has elapsed.
 	ActivityBumpWorkspace(ctx context.Context, arg ActivityBumpWorkspaceParams) error
+	AddWorkspacePortShareLinkBytesTransferred(ctx context.Context, arg AddWorkspacePortShareLinkBytesTransferredParams) error
 	// AllUserIDs returns all UserIDs regardless of user status or deletion.
 	AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid.UUID, error)
 	// Archiving templates is a soft delete action, so is reversible.
@@ -61,18 +74,34 @@ type sqlcQuerier interface {
 	BatchUpdateWorkspaceNextStartAt(ctx context.Context, arg BatchUpdateWorkspaceNextStartAtParams) error
 	BulkMarkNotificationMessagesFailed(ctx context.Context, arg BulkMarkNotificationMessagesFailedParams) (int64, error)
 	BulkMarkNotificationMessagesSent(ctx context.Context, arg BulkMarkNotificationMessagesSentParams) (int64, error)
+	// Cancels the pending activation requests of a template once another version
+	// has been made active.
+	CancelPendingTemplateVersionActivationRequests(ctx context.Context, arg CancelPendingTemplateVersionActivationRequestsParams) error
 	ClaimPrebuiltWorkspace(ctx context.Context, arg ClaimPrebuiltWorkspaceParams) (ClaimPrebuiltWorkspaceRow, error)
 	CleanTailnetCoordinators(ctx context.Context) error
 	CleanTailnetLostPeers(ctx context.Context) error
 	CleanTailnetTunnels(ctx context.Context) error
 	CountAuditLogs(ctx context.Context, arg CountAuditLogsParams) (int64, error)
+	// Counts the files that share the object storage contents of a hash.
+	CountExternalFilesByHash(ctx context.Context, hash string) (int64, error)
 	// CountInProgressPrebuilds returns the number of in-progress prebuilds, grouped by preset ID and transition.
 	// Prebuild considered in-progress if it's in the "starting", "stopping", or "deleting" state.
 	CountInProgressPrebuilds(ctx context.Context) ([]CountInProgressPrebuildsRow, error)
+	// Counts the workspaces of the owner whose latest build starts an AI task and
+	// has not failed or been canceled.
+	CountRunningAITasksByOwnerID(ctx context.Context, ownerID uuid.UUID) (int64, error)
+	// Counts the unread inbox notifications of a user by the category of their
+	// template. Notifications whose template has no category are counted with a
+	// NULL category.
+	CountUnreadInboxNotificationsByCategory(ctx context.Context, userID uuid.UUID) ([]CountUnreadInboxNotificationsByCategoryRow, error)
 	CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 	CustomRoles(ctx context.Context, arg CustomRolesParams) ([]CustomRole, error)
+	DeleteAITaskQueueEntryByID(ctx context.Context, id uuid.UUID) error
 	DeleteAPIKeyByID(ctx context.Context, id string) error
 	DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error
+	// Deletes the sessions a user created by logging in with the given login
+	// type. API tokens are kept.
+	DeleteAPIKeysByUserIDLoginType(ctx context.Context, arg DeleteAPIKeysByUserIDLoginTypeParams) error
 	DeleteAllTailnetClientSubscriptions(ctx context.Context, arg DeleteAllTailnetClientSubscriptionsParams) error
 	DeleteAllTailnetTunnels(ctx context.Context, arg DeleteAllTailnetTunnelsParams) error
 	// Deletes all existing webpush subscriptions.
@@ -81,20 +110,39 @@ type sqlcQuerier interface {
 	// be recreated.
 	DeleteAllWebpushSubscriptions(ctx context.Context) error
 	DeleteApplicationConnectAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error
+	DeleteAuditLogLegalHoldByID(ctx context.Context, id uuid.UUID) error
 	DeleteCoordinator(ctx context.Context, id uuid.UUID) error
 	DeleteCryptoKey(ctx context.Context, arg DeleteCryptoKeyParams) (CryptoKey, error)
 	DeleteCustomRole(ctx context.Context, arg DeleteCustomRoleParams) error
+	// Deletes the least recently used sessions of a user, keeping at most
+	// @max_sessions. Sessions are matched like in GetAPIKeySessionsByUserID.
+	DeleteExcessAPIKeySessionsByUserID(ctx context.Context, arg DeleteExcessAPIKeySessionsByUserIDParams) error
+	DeleteExpiredWebAuthnChallenges(ctx context.Context, now time.Time) error
+	DeleteExpiredWorkspacePortShareLinks(ctx context.Context, now time.Time) error
 	DeleteExternalAuthLink(ctx context.Context, arg DeleteExternalAuthLinkParams) error
 	DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error
 	DeleteGroupByID(ctx context.Context, id uuid.UUID) error
 	DeleteGroupMemberFromGroup(ctx context.Context, arg DeleteGroupMemberFromGroupParams) error
+	DeleteGroupQuotaAllowances(ctx context.Context, groupID uuid.UUID) error
 	DeleteLicense(ctx context.Context, id int32) (int32, error)
+	DeleteLicenseSeatReservation(ctx context.Context, groupID uuid.UUID) error
+	DeleteNetworkConnectionQuality(ctx context.Context, arg DeleteNetworkConnectionQualityParams) error
 	DeleteOAuth2ProviderAppByClientID(ctx context.Context, id uuid.UUID) error
 	DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error
 	DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) error
 	DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error
+	DeleteOAuth2ProviderAppDeviceCodeByID(ctx context.Context, id uuid.UUID) error
 	DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error
 	DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error
+	// Deletes up to limit_count audit logs that are older than the retention
+	// period of their organization. Audit logs of users and workspaces under a
+	// legal hold are kept. Organizations without a retention period keep their
+	// audit logs forever.
+	DeleteOldAuditLogs(ctx context.Context, arg DeleteOldAuditLogsParams) (int64, error)
+	DeleteOldHealthScores(ctx context.Context, beforeTime time.Time) error
+	// Connections send a disconnect event when they close cleanly, this removes
+	// the ones that vanished without one.
+	DeleteOldNetworkConnectionQuality(ctx context.Context, beforeTime time.Time) error
 	// Delete all notification messages which have not been updated for over a week.
 	DeleteOldNotificationMessages(ctx context.Context) error
 	// Delete provisioner daemons that have been created at least a week ago
@@ -111,15 +159,46 @@ type sqlcQuerier interface {
 	DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error
 	DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error
 	DeleteRuntimeConfig(ctx context.Context, key string) error
+	DeleteSCIMGroupMembers(ctx context.Context, arg DeleteSCIMGroupMembersParams) error
+	DeleteSCIMNestedGroups(ctx context.Context, arg DeleteSCIMNestedGroupsParams) error
+	// Deletes the sessions of a user that were created before @created_before,
+	// including the app keys derived from them, to make the user authenticate
+	// again.
+	DeleteStaleAPIKeySessionsByUserID(ctx context.Context, arg DeleteStaleAPIKeySessionsByUserIDParams) error
 	DeleteTailnetAgent(ctx context.Context, arg DeleteTailnetAgentParams) (DeleteTailnetAgentRow, error)
 	DeleteTailnetClient(ctx context.Context, arg DeleteTailnetClientParams) (DeleteTailnetClientRow, error)
 	DeleteTailnetClientSubscription(ctx context.Context, arg DeleteTailnetClientSubscriptionParams) error
 	DeleteTailnetPeer(ctx context.Context, arg DeleteTailnetPeerParams) (DeleteTailnetPeerRow, error)
 	DeleteTailnetTunnel(ctx context.Context, arg DeleteTailnetTunnelParams) (DeleteTailnetTunnelRow, error)
+	DeleteTemplateAppRestriction(ctx context.Context, arg DeleteTemplateAppRestrictionParams) error
+	DeleteTemplateAppSessionPolicy(ctx context.Context, arg DeleteTemplateAppSessionPolicyParams) error
+	DeleteTemplateBundleByName(ctx context.Context, arg DeleteTemplateBundleByNameParams) error
+	DeleteTemplateEgressPolicy(ctx context.Context, templateID uuid.UUID) error
+	DeleteTemplateParameterOptionSource(ctx context.Context, arg DeleteTemplateParameterOptionSourceParams) error
+	DeleteTemplateSecret(ctx context.Context, arg DeleteTemplateSecretParams) error
+	DeleteTemplateVariableSet(ctx context.Context, arg DeleteTemplateVariableSetParams) error
+	DeleteTemplateVersionActivationReviews(ctx context.Context, templateVersionID uuid.UUID) error
+	// Deletes files in object storage that were created before the given time
+	// and are not used by a provisioner job or as cached modules. Returns the
+	// hashes of the deleted files, so that their contents can be deleted once no
+	// other file shares them.
+	DeleteUnreferencedExternalFiles(ctx context.Context, createdBefore time.Time) ([]string, error)
+	DeleteUserMFARecoveryCodes(ctx context.Context, userID uuid.UUID) error
+	DeleteUserSecret(ctx context.Context, arg DeleteUserSecretParams) error
+	DeleteUserWebAuthnCredential(ctx context.Context, id uuid.UUID) error
+	DeleteVariableSet(ctx context.Context, id uuid.UUID) error
+	// Returns the deleted challenge, so that each challenge can only be used once
+	// even by concurrent requests.
+	DeleteWebAuthnChallengeByID(ctx context.Context, id uuid.UUID) (WebAuthnChallenge, error)
 	DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg DeleteWebpushSubscriptionByUserIDAndEndpointParams) error
 	DeleteWebpushSubscriptions(ctx context.Context, ids []uuid.UUID) error
 	DeleteWorkspaceAgentPortShare(ctx context.Context, arg DeleteWorkspaceAgentPortShareParams) error
 	DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context, templateID uuid.UUID) error
+	DeleteWorkspaceArchiveByID(ctx context.Context, id uuid.UUID) error
+	DeleteWorkspaceLabelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error
+	DeleteWorkspacePortShareLink(ctx context.Context, id uuid.UUID) error
+	DeleteWorkspacePortShareLinksByTemplate(ctx context.Context, templateID uuid.UUID) error
+	DeleteWorkspaceSchedulePause(ctx context.Context, workspaceID uuid.UUID) error
 	DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UUID) error
 	// Disable foreign keys and triggers for all tables.
 	// Deprecated: disable foreign keys was created to aid in migrating off
@@ -133,15 +212,31 @@ type sqlcQuerier interface {
 	FetchNewMessageMetadata(ctx context.Context, arg FetchNewMessageMetadataParams) (FetchNewMessageMetadataRow, error)
 	FetchVolumesResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceAgentVolumeResourceMonitor, error)
 	FetchVolumesResourceMonitorsUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]WorkspaceAgentVolumeResourceMonitor, error)
+	// Completes the rollout of a template once its version has been made active,
+	// or cancels it if another version has been made active.
+	FinishTemplateVersionRollout(ctx context.Context, arg FinishTemplateVersionRolloutParams) error
+	// Returns the queued and failed tasks of the owner, oldest first.
+	GetAITaskQueueEntriesByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]AITaskQueue, error)
+	GetAITaskQueueEntryByID(ctx context.Context, id uuid.UUID) (AITaskQueue, error)
+	// Returns the owners that have queued tasks.
+	GetAITaskQueueOwnerIDs(ctx context.Context) ([]uuid.UUID, error)
 	GetAPIKeyByID(ctx context.Context, id string) (APIKey, error)
 	// there is no unique constraint on empty token names
 	GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNameParams) (APIKey, error)
+	// Sessions are the unexpired keys a user created by logging in. API tokens,
+	// workspace owner tokens and the keys of workspace apps are excluded.
+	GetAPIKeySessionsByUserID(ctx context.Context, arg GetAPIKeySessionsByUserIDParams) ([]APIKey, error)
 	GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error)
 	GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUserIDParams) ([]APIKey, error)
 	GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error)
 	GetActivePresetPrebuildSchedules(ctx context.Context) ([]TemplateVersionPresetPrebuildSchedule, error)
 	GetActiveUserCount(ctx context.Context, includeSystem bool) (int64, error)
 	GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspaceBuild, error)
+	// GetActivityEvents returns audit events, workspace builds and workspace agent
+	// lifecycle transitions as a single feed, newest first. When @newer_than_id is
+	// set, only the events after that event are returned, oldest first, so a
+	// stream can resume from the last event it saw.
+	GetActivityEvents(ctx context.Context, arg GetActivityEventsParams) ([]GetActivityEventsRow, error)
 	GetAllTailnetAgents(ctx context.Context) ([]TailnetAgent, error)
 	// For PG Coordinator HTMLDebug
 	GetAllTailnetCoordinators(ctx context.Context) ([]TailnetCoordinator, error)
@@ -150,6 +245,8 @@ type sqlcQuerier interface {
 	GetAnnouncementBanners(ctx context.Context) (string, error)
 	GetAppSecurityKey(ctx context.Context) (string, error)
 	GetApplicationName(ctx context.Context) (string, error)
+	GetAuditLogLegalHoldByID(ctx context.Context, id uuid.UUID) (AuditLogLegalHold, error)
+	GetAuditLogLegalHoldsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]AuditLogLegalHold, error)
 	// GetAuditLogsBefore retrieves `row_limit` number of audit logs before the provided
 	// ID.
 	GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOffsetParams) ([]GetAuditLogsOffsetRow, error)
@@ -169,7 +266,11 @@ type sqlcQuerier interface {
 	GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAt time.Time) (GetDeploymentWorkspaceAgentStatsRow, error)
 	GetDeploymentWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) (GetDeploymentWorkspaceAgentUsageStatsRow, error)
 	GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploymentWorkspaceStatsRow, error)
+	// Fetches the pending messages which are due to be batched into their user's
+	// notification digest, ordered by user.
+	GetDueNotificationDigestMessages(ctx context.Context, now time.Time) ([]GetDueNotificationDigestMessagesRow, error)
 	GetEligibleProvisionerDaemonsByProvisionerJobIDs(ctx context.Context, provisionerJobIds []uuid.UUID) ([]GetEligibleProvisionerDaemonsByProvisionerJobIDsRow, error)
+	GetExperimentFlags(ctx context.Context) (string, error)
 	GetExternalAuthLink(ctx context.Context, arg GetExternalAuthLinkParams) (ExternalAuthLink, error)
 	GetExternalAuthLinksByUserID(ctx context.Context, userID uuid.UUID) ([]ExternalAuthLink, error)
 	GetFailedWorkspaceBuildsByTemplateID(ctx context.Context, arg GetFailedWorkspaceBuildsByTemplateIDParams) ([]GetFailedWorkspaceBuildsByTemplateIDRow, error)
@@ -182,6 +283,7 @@ type sqlcQuerier interface {
 	// param user_id: The user ID
 	// param templates: The template IDs to filter by - the template_id = ANY(@templates::UUID[]) condition checks if the template_id is in the @templates array
 	// param targets: The target IDs to filter by - the targets @> COALESCE(@targets, ARRAY[]::UUID[]) condition checks if the targets array (from the DB) contains all the elements in the @targets array
+	// param categories: The categories to filter by - only notifications whose template belongs to one of the categories are returned
 	// param read_status: The read status to filter by - can be any of 'ALL', 'UNREAD', 'READ'
 	// param created_at_opt: The created_at timestamp to filter by. This parameter is usd for pagination - it fetches notifications created before the specified timestamp if it is not the zero value
 	// param limit_opt: The limit of notifications to fetch. If the limit is not specified, it defaults to 25
@@ -195,7 +297,17 @@ type sqlcQuerier interface {
 	// count even if the caller does not have read access to ResourceGroupMember.
 	// They only need ResourceGroup read access.
 	GetGroupMembersCountByGroupID(ctx context.Context, arg GetGroupMembersCountByGroupIDParams) (int64, error)
+	GetGroupQuotaAllowances(ctx context.Context, groupID uuid.UUID) ([]GroupQuotaAllowance, error)
+	// GetGroupWorkspaceUsage returns, for every group with members who owned a
+	// running workspace in the organization of the group between start and end
+	// time, the number of those members and of their workspaces that ran, the
+	// hours those workspaces ran for and the quota credits they accrued. Users are
+	// counted in every group they are a member of. Keep the CTEs in sync with
+	// GetUserWorkspaceUsage.
+	GetGroupWorkspaceUsage(ctx context.Context, arg GetGroupWorkspaceUsageParams) ([]GetGroupWorkspaceUsageRow, error)
 	GetGroups(ctx context.Context, arg GetGroupsParams) ([]GetGroupsRow, error)
+	// Returns the scores of all replicas recorded in [@start_time, @end_time).
+	GetHealthScores(ctx context.Context, arg GetHealthScoresParams) ([]HealthScore, error)
 	GetHealthSettings(ctx context.Context) (string, error)
 	GetInboxNotificationByID(ctx context.Context, id uuid.UUID) (InboxNotification, error)
 	// Fetches inbox notifications for a user filtered by templates and targets
@@ -204,15 +316,26 @@ type sqlcQuerier interface {
 	// param created_at_opt: The created_at timestamp to filter by. This parameter is usd for pagination - it fetches notifications created before the specified timestamp if it is not the zero value
 	// param limit_opt: The limit of notifications to fetch. If the limit is not specified, it defaults to 25
 	GetInboxNotificationsByUserID(ctx context.Context, arg GetInboxNotificationsByUserIDParams) ([]InboxNotification, error)
+	GetInternalCA(ctx context.Context) (string, error)
+	GetLDAPUsers(ctx context.Context) ([]LDAPUser, error)
 	GetLastUpdateCheck(ctx context.Context) (string, error)
 	GetLatestCryptoKeyByFeature(ctx context.Context, feature CryptoKeyFeature) (CryptoKey, error)
 	GetLatestWorkspaceAppStatusesByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAppStatus, error)
+	GetLatestWorkspaceArchiveByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceArchive, error)
 	GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceBuild, error)
 	GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceBuild, error)
 	GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceBuild, error)
 	GetLicenseByID(ctx context.Context, id int32) (License, error)
+	// Returns the seat reservations with the number of active members of each
+	// group, which occupy the reserved seats first.
+	GetLicenseSeatReservations(ctx context.Context) ([]GetLicenseSeatReservationsRow, error)
 	GetLicenses(ctx context.Context) ([]License, error)
 	GetLogoURL(ctx context.Context) (string, error)
+	GetNetworkConnectionQuality(ctx context.Context, arg GetNetworkConnectionQualityParams) (NetworkConnectionQuality, error)
+	GetNetworkConnectionQualityCounters(ctx context.Context) ([]NetworkConnectionQualityCounter, error)
+	// Groups the connections updated since @updated_after by client and connection
+	// type, with the median latency of each group.
+	GetNetworkConnectionQualitySummary(ctx context.Context, updatedAfter time.Time) ([]GetNetworkConnectionQualitySummaryRow, error)
 	GetNotificationMessagesByStatus(ctx context.Context, arg GetNotificationMessagesByStatusParams) ([]NotificationMessage, error)
 	// Fetch the notification report generator log indicating recent activity.
 	GetNotificationReportGeneratorLogByTemplate(ctx context.Context, templateID uuid.UUID) (NotificationReportGeneratorLog, error)
@@ -226,6 +349,8 @@ type sqlcQuerier interface {
 	GetOAuth2ProviderAppByRegistrationToken(ctx context.Context, registrationAccessToken sql.NullString) (OAuth2ProviderApp, error)
 	GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error)
 	GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix []byte) (OAuth2ProviderAppCode, error)
+	GetOAuth2ProviderAppDeviceCodeByPrefix(ctx context.Context, deviceCodePrefix []byte) (OAuth2ProviderAppDeviceCode, error)
+	GetOAuth2ProviderAppDeviceCodeByUserCode(ctx context.Context, userCode string) (OAuth2ProviderAppDeviceCode, error)
 	GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppSecret, error)
 	GetOAuth2ProviderAppSecretByPrefix(ctx context.Context, secretPrefix []byte) (OAuth2ProviderAppSecret, error)
 	GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]OAuth2ProviderAppSecret, error)
@@ -234,13 +359,24 @@ type sqlcQuerier interface {
 	GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error)
 	GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]GetOAuth2ProviderAppsByUserIDRow, error)
 	GetOAuthSigningKey(ctx context.Context) (string, error)
+	GetOrganizationAuditSettings(ctx context.Context, organizationID uuid.UUID) (OrganizationAuditSetting, error)
 	GetOrganizationByID(ctx context.Context, id uuid.UUID) (Organization, error)
 	GetOrganizationByName(ctx context.Context, arg GetOrganizationByNameParams) (Organization, error)
 	GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error)
+	GetOrganizationMFASettings(ctx context.Context, organizationID uuid.UUID) (OrganizationMFASetting, error)
 	GetOrganizationResourceCountByID(ctx context.Context, organizationID uuid.UUID) (GetOrganizationResourceCountByIDRow, error)
+	GetOrganizationSettingOverrides(ctx context.Context, organizationID uuid.UUID) (OrganizationSettingOverride, error)
 	GetOrganizations(ctx context.Context, arg GetOrganizationsParams) ([]Organization, error)
 	GetOrganizationsByUserID(ctx context.Context, arg GetOrganizationsByUserIDParams) ([]Organization, error)
+	// Returns running jobs whose provisioner daemon has not sent a heartbeat since
+	// @stale_since, or no longer exists, along with the number of times each job
+	// has already been requeued.
+	// To avoid repeatedly attempting to handle the same jobs, we randomly order and limit to @max_jobs.
+	GetOrphanedProvisionerJobs(ctx context.Context, arg GetOrphanedProvisionerJobsParams) ([]GetOrphanedProvisionerJobsRow, error)
 	GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]ParameterSchema, error)
+	// Returns the number of provisioner jobs which are waiting to be acquired by a
+	// provisioner daemon.
+	GetPendingProvisionerJobsCount(ctx context.Context) (int64, error)
 	GetPrebuildMetrics(ctx context.Context) ([]GetPrebuildMetricsRow, error)
 	GetPrebuildsSettings(ctx context.Context) (string, error)
 	GetPresetByID(ctx context.Context, presetID uuid.UUID) (GetPresetByIDRow, error)
@@ -282,6 +418,9 @@ type sqlcQuerier interface {
 	// Gets a single provisioner job by ID for update.
 	// This is used to securely reap jobs that have been hung/pending for a long time.
 	GetProvisionerJobByIDForUpdate(ctx context.Context, id uuid.UUID) (ProvisionerJob, error)
+	// Returns the number of provisioner jobs of every organization which are
+	// waiting to be acquired by a provisioner daemon, and which are running.
+	GetProvisionerJobCountsByOrganization(ctx context.Context) ([]GetProvisionerJobCountsByOrganizationRow, error)
 	GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]ProvisionerJobTiming, error)
 	GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]ProvisionerJob, error)
 	GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, arg GetProvisionerJobsByIDsWithQueuePositionParams) ([]GetProvisionerJobsByIDsWithQueuePositionRow, error)
@@ -295,11 +434,33 @@ type sqlcQuerier interface {
 	GetProvisionerLogsAfterID(ctx context.Context, arg GetProvisionerLogsAfterIDParams) ([]ProvisionerJobLog, error)
 	GetQuotaAllowanceForUser(ctx context.Context, arg GetQuotaAllowanceForUserParams) (int64, error)
 	GetQuotaConsumedForUser(ctx context.Context, arg GetQuotaConsumedForUserParams) (int64, error)
+	// Returns every quota dimension that has an allowance in the organization,
+	// with the sum of the allowances of the groups the user is a member of.
+	// Dimensions without any allowance in the organization are not enforced.
+	GetQuotaDimensionAllowancesForUser(ctx context.Context, arg GetQuotaDimensionAllowancesForUserParams) ([]GetQuotaDimensionAllowancesForUserRow, error)
+	// Returns the quota consumed by the workspaces of all members of the group
+	// in the organization of the group.
+	GetQuotaDimensionsConsumedForGroup(ctx context.Context, groupID uuid.UUID) ([]GetQuotaDimensionsConsumedForGroupRow, error)
+	GetQuotaDimensionsConsumedForUser(ctx context.Context, arg GetQuotaDimensionsConsumedForUserParams) ([]GetQuotaDimensionsConsumedForUserRow, error)
 	GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error)
 	GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error)
+	GetReviewWorkspace(ctx context.Context, arg GetReviewWorkspaceParams) (ReviewWorkspace, error)
+	// Returns the review workspaces for a branch in every organization. Used when
+	// a webhook reports that the branch has been deleted.
+	GetReviewWorkspacesByRepositoryAndBranch(ctx context.Context, arg GetReviewWorkspacesByRepositoryAndBranchParams) ([]ReviewWorkspace, error)
+	// Returns the most recent successful start build of a workspace that was
+	// created before its latest start build. Rolling back a workspace starts it
+	// with the template version and parameters of this build.
+	GetRollbackWorkspaceBuild(ctx context.Context, workspaceID uuid.UUID) (WorkspaceBuild, error)
 	GetRunningPrebuiltWorkspaces(ctx context.Context) ([]GetRunningPrebuiltWorkspacesRow, error)
 	GetRunningPrebuiltWorkspacesOptimized(ctx context.Context) ([]GetRunningPrebuiltWorkspacesOptimizedRow, error)
 	GetRuntimeConfig(ctx context.Context, key string) (string, error)
+	// Returns the users the identity provider assigned to each SCIM group in the
+	// organization.
+	GetSCIMGroupMembers(ctx context.Context, organizationID uuid.UUID) ([]SCIMGroupMember, error)
+	// Returns the groups the identity provider assigned as members of each SCIM
+	// group in the organization.
+	GetSCIMNestedGroups(ctx context.Context, organizationID uuid.UUID) ([]SCIMNestedGroup, error)
 	GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]TailnetAgent, error)
 	GetTailnetClientsForAgent(ctx context.Context, agentID uuid.UUID) ([]TailnetClient, error)
 	GetTailnetPeers(ctx context.Context, id uuid.UUID) ([]TailnetPeer, error)
@@ -307,6 +468,10 @@ type sqlcQuerier interface {
 	GetTailnetTunnelPeerIDs(ctx context.Context, srcID uuid.UUID) ([]GetTailnetTunnelPeerIDsRow, error)
 	GetTelemetryItem(ctx context.Context, key string) (TelemetryItem, error)
 	GetTelemetryItems(ctx context.Context) ([]TelemetryItem, error)
+	// Locks the template while its active version is changed, so that the
+	// approvals it requires can't change until the transaction ends.
+	GetTemplateActivationForUpdate(ctx context.Context, id uuid.UUID) (GetTemplateActivationForUpdateRow, error)
+	GetTemplateActivationReviewers(ctx context.Context, templateID uuid.UUID) ([]GetTemplateActivationReviewersRow, error)
 	// GetTemplateAppInsights returns the aggregate usage of each app in a given
 	// timeframe. The result can be filtered on template_ids, meaning only user data
 	// from workspaces based on those templates will be included.
@@ -314,10 +479,36 @@ type sqlcQuerier interface {
 	// GetTemplateAppInsightsByTemplate is used for Prometheus metrics. Keep
 	// in sync with GetTemplateAppInsights and UpsertTemplateUsageStats.
 	GetTemplateAppInsightsByTemplate(ctx context.Context, arg GetTemplateAppInsightsByTemplateParams) ([]GetTemplateAppInsightsByTemplateRow, error)
+	GetTemplateAppRestrictions(ctx context.Context, templateID uuid.UUID) ([]TemplateAppRestriction, error)
+	// Used to hide and deny access to the restricted apps of workspaces of
+	// multiple templates at once.
+	GetTemplateAppRestrictionsByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]TemplateAppRestriction, error)
+	GetTemplateAppSessionPolicies(ctx context.Context, templateID uuid.UUID) ([]TemplateAppSessionPolicy, error)
 	GetTemplateAverageBuildTime(ctx context.Context, arg GetTemplateAverageBuildTimeParams) (GetTemplateAverageBuildTimeRow, error)
+	// GetTemplateBuildFailures returns the distinct error messages of the failed
+	// workspace builds of a template that completed between start and end time,
+	// along with the number of builds that failed with each message and when the
+	// last one completed.
+	GetTemplateBuildFailures(ctx context.Context, arg GetTemplateBuildFailuresParams) ([]GetTemplateBuildFailuresRow, error)
+	// GetTemplateBuildInsightsByInterval returns the number of succeeded, failed
+	// and canceled workspace builds of a template for all intervals between start
+	// and end time. Builds are counted in the interval their job completed in. If
+	// end time is a partial interval, it will be included in the results and that
+	// interval will be shorter than a full one.
+	GetTemplateBuildInsightsByInterval(ctx context.Context, arg GetTemplateBuildInsightsByIntervalParams) ([]GetTemplateBuildInsightsByIntervalRow, error)
+	// GetTemplateBuildStageDurations returns the median and 95th percentile
+	// duration in seconds of the successful workspace builds of a template that
+	// completed between start and end time. The "total" stage covers the whole
+	// provisioner job, the other stages are the provisioner job timing stages
+	// (init, plan, graph and apply).
+	GetTemplateBuildStageDurations(ctx context.Context, arg GetTemplateBuildStageDurationsParams) ([]GetTemplateBuildStageDurationsRow, error)
+	GetTemplateBundleByName(ctx context.Context, arg GetTemplateBundleByNameParams) (TemplateBundle, error)
+	// Omits the data of the bundles, which is only fetched when a bundle is pulled.
+	GetTemplateBundlesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]GetTemplateBundlesByOrganizationIDRow, error)
 	GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error)
 	GetTemplateByOrganizationAndName(ctx context.Context, arg GetTemplateByOrganizationAndNameParams) (Template, error)
 	GetTemplateDAUs(ctx context.Context, arg GetTemplateDAUsParams) ([]GetTemplateDAUsRow, error)
+	GetTemplateEgressPolicyByTemplateID(ctx context.Context, templateID uuid.UUID) (TemplateEgressPolicy, error)
 	// GetTemplateInsights returns the aggregate user-produced usage of all
 	// workspaces in a given timeframe. The template IDs, active users, and
 	// usage_seconds all reflect any usage in the template, including apps.
@@ -341,15 +532,31 @@ type sqlcQuerier interface {
 	// created in the timeframe and return the aggregate usage counts of parameter
 	// values.
 	GetTemplateParameterInsights(ctx context.Context, arg GetTemplateParameterInsightsParams) ([]GetTemplateParameterInsightsRow, error)
+	GetTemplateParameterOptionSource(ctx context.Context, arg GetTemplateParameterOptionSourceParams) (TemplateParameterOptionSource, error)
+	GetTemplateParameterOptionSources(ctx context.Context, templateID uuid.UUID) ([]TemplateParameterOptionSource, error)
 	// GetTemplatePresetsWithPrebuilds retrieves template versions with configured presets and prebuilds.
 	// It also returns the number of desired instances for each preset.
 	// If template_id is specified, only template versions associated with that template will be returned.
 	GetTemplatePresetsWithPrebuilds(ctx context.Context, templateID uuid.NullUUID) ([]GetTemplatePresetsWithPrebuildsRow, error)
+	GetTemplateSecretByTemplateIDAndName(ctx context.Context, arg GetTemplateSecretByTemplateIDAndNameParams) (TemplateSecret, error)
+	GetTemplateSecretsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateSecret, error)
 	GetTemplateUsageStats(ctx context.Context, arg GetTemplateUsageStatsParams) ([]TemplateUsageStat, error)
+	GetTemplateVersionActivationRequestByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) (GetTemplateVersionActivationRequestByTemplateVersionIDRow, error)
+	GetTemplateVersionActivationReviews(ctx context.Context, templateVersionID uuid.UUID) ([]GetTemplateVersionActivationReviewsRow, error)
 	GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error)
 	GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error)
 	GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg GetTemplateVersionByTemplateIDAndNameParams) (TemplateVersion, error)
 	GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error)
+	// Counts the finished start builds of the rolled out version since the rollout
+	// was started.
+	GetTemplateVersionRolloutBuildStats(ctx context.Context, templateID uuid.UUID) (GetTemplateVersionRolloutBuildStatsRow, error)
+	GetTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (TemplateVersionRollout, error)
+	// Returns the template version that workspaces in the cohort of an in progress
+	// rollout are built with instead of the active version of their template.
+	// Workspaces are part of the cohort if their owner is a member of one of the
+	// groups of the rollout, or if they fall within its percentage. Hashing the
+	// workspace ID keeps the cohort stable as the percentage is increased.
+	GetTemplateVersionRolloutTargetsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]GetTemplateVersionRolloutTargetsByWorkspaceIDsRow, error)
 	GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (TemplateVersionTerraformValue, error)
 	GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error)
 	GetTemplateVersionWorkspaceTags(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionWorkspaceTag, error)
@@ -369,6 +576,9 @@ type sqlcQuerier interface {
 	GetUserActivityInsights(ctx context.Context, arg GetUserActivityInsightsParams) ([]GetUserActivityInsightsRow, error)
 	GetUserByEmailOrUsername(ctx context.Context, arg GetUserByEmailOrUsernameParams) (User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
+	// Returns the CLI version each user last made a request with, if the request
+	// was made after the given time.
+	GetUserCLIVersions(ctx context.Context, seenAfter time.Time) ([]GetUserCLIVersionsRow, error)
 	GetUserCount(ctx context.Context, includeSystem bool) (int64, error)
 	// GetUserLatencyInsights returns the median and 95th percentile connection
 	// latency that users have experienced. The result can be filtered on
@@ -378,7 +588,18 @@ type sqlcQuerier interface {
 	GetUserLinkByLinkedID(ctx context.Context, linkedID string) (UserLink, error)
 	GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error)
 	GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]UserLink, error)
+	GetUserNotificationCategoryPreferences(ctx context.Context, userID uuid.UUID) ([]NotificationCategoryPreference, error)
+	GetUserNotificationDigest(ctx context.Context, userID uuid.UUID) (string, error)
 	GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]NotificationPreference, error)
+	// GetUserProxyLatencies returns the JSON encoded latencies the user's client
+	// last measured to each region.
+	GetUserProxyLatencies(ctx context.Context, userID uuid.UUID) (string, error)
+	// GetUserProxyPreference returns the ID of the region the user pinned for
+	// workspace app and terminal traffic. An empty value selects automatically.
+	GetUserProxyPreference(ctx context.Context, userID uuid.UUID) (string, error)
+	GetUserSecretByUserIDAndName(ctx context.Context, arg GetUserSecretByUserIDAndNameParams) (UserSecret, error)
+	GetUserSecretsByUserID(ctx context.Context, userID uuid.UUID) ([]UserSecret, error)
+	GetUserStartOnConnect(ctx context.Context, userID uuid.UUID) (string, error)
 	// GetUserStatusCounts returns the count of users in each status over time.
 	// The time range is inclusively defined by the start_time and end_time parameters.
 	//
@@ -394,15 +615,35 @@ type sqlcQuerier interface {
 	GetUserStatusCounts(ctx context.Context, arg GetUserStatusCountsParams) ([]GetUserStatusCountsRow, error)
 	GetUserTerminalFont(ctx context.Context, userID uuid.UUID) (string, error)
 	GetUserThemePreference(ctx context.Context, userID uuid.UUID) (string, error)
+	GetUserWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (UserWebAuthnCredential, error)
+	GetUserWebAuthnCredentialByID(ctx context.Context, id uuid.UUID) (UserWebAuthnCredential, error)
+	GetUserWebAuthnCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]UserWebAuthnCredential, error)
+	// Returns true if any organization the user is a member of requires a WebAuthn
+	// credential for password logins.
+	GetUserWebAuthnRequired(ctx context.Context, userID uuid.UUID) (bool, error)
 	GetUserWorkspaceBuildParameters(ctx context.Context, arg GetUserWorkspaceBuildParametersParams) ([]GetUserWorkspaceBuildParametersRow, error)
+	// GetUserWorkspaceUsage returns, for every user who owned a running workspace
+	// between start and end time, the number of their workspaces that ran, the
+	// hours those workspaces ran for and the quota credits they accrued. A
+	// workspace runs from the completion of a successful start build until its
+	// next build, and accrues the daily cost of the start build prorated over that
+	// time. The hours of the quota dimensions are the costs of the start build in
+	// each dimension multiplied by the hours the workspaces ran, e.g. CPU hours.
+	// Keep the CTEs in sync with GetGroupWorkspaceUsage.
+	GetUserWorkspaceUsage(ctx context.Context, arg GetUserWorkspaceUsageParams) ([]GetUserWorkspaceUsageRow, error)
 	// This will never return deleted users.
 	GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error)
 	// This shouldn't check for deleted, because it's frequently used
 	// to look up references to actions. eg. a user could build a workspace
 	// for another user, then be deleted... we still want them to appear!
 	GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error)
+	GetVariableSetByName(ctx context.Context, name string) (VariableSet, error)
+	GetVariableSets(ctx context.Context) ([]VariableSet, error)
+	GetVariableSetsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]VariableSet, error)
 	GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]WebpushSubscription, error)
 	GetWebpushVAPIDKeys(ctx context.Context) (GetWebpushVAPIDKeysRow, error)
+	GetWorkspaceAdoptionByID(ctx context.Context, id uuid.UUID) (WorkspaceAdoption, error)
+	GetWorkspaceAdoptionByWorkspaceID(ctx context.Context, workspaceID uuid.NullUUID) (WorkspaceAdoption, error)
 	GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Context, authToken uuid.UUID) (GetWorkspaceAgentAndLatestBuildByAuthTokenRow, error)
 	GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (WorkspaceAgent, error)
 	GetWorkspaceAgentByInstanceID(ctx context.Context, authInstanceID string) (WorkspaceAgent, error)
@@ -416,9 +657,14 @@ type sqlcQuerier interface {
 	GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentScript, error)
 	GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsRow, error)
 	GetWorkspaceAgentStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsAndLabelsRow, error)
+	GetWorkspaceAgentTokenRotationByAgentID(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentTokenRotation, error)
 	// `minute_buckets` could return 0 rows if there are no usage stats since `created_at`.
 	GetWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentUsageStatsRow, error)
 	GetWorkspaceAgentUsageStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentUsageStatsAndLabelsRow, error)
+	// Returns the version every agent in the latest build of a workspace reported
+	// when it last started. Agents that never connected are omitted.
+	GetWorkspaceAgentVersionsInLatestBuilds(ctx context.Context) ([]GetWorkspaceAgentVersionsInLatestBuildsRow, error)
+	GetWorkspaceAgentVolumeUsageByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceAgentVolumeUsage, error)
 	GetWorkspaceAgentsByParentID(ctx context.Context, parentID uuid.UUID) ([]WorkspaceAgent, error)
 	GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgent, error)
 	GetWorkspaceAgentsByWorkspaceAndBuildNumber(ctx context.Context, arg GetWorkspaceAgentsByWorkspaceAndBuildNumberParams) ([]WorkspaceAgent, error)
@@ -429,11 +675,15 @@ type sqlcQuerier interface {
 	GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceApp, error)
 	GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceApp, error)
 	GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceApp, error)
+	GetWorkspaceArchiveByID(ctx context.Context, id uuid.UUID) (WorkspaceArchive, error)
+	GetWorkspaceArchivesByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]WorkspaceArchive, error)
+	GetWorkspaceArchivesExpiredBefore(ctx context.Context, now time.Time) ([]WorkspaceArchive, error)
 	GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error)
 	GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UUID) (WorkspaceBuild, error)
 	GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Context, arg GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams) (WorkspaceBuild, error)
 	GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]WorkspaceBuildParameter, error)
 	GetWorkspaceBuildParametersByBuildIDs(ctx context.Context, workspaceBuildIds []uuid.UUID) ([]WorkspaceBuildParameter, error)
+	GetWorkspaceBuildQuotaCosts(ctx context.Context, workspaceBuildID uuid.UUID) ([]WorkspaceBuildQuotaCost, error)
 	GetWorkspaceBuildStatsByTemplates(ctx context.Context, since time.Time) ([]GetWorkspaceBuildStatsByTemplatesRow, error)
 	GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg GetWorkspaceBuildsByWorkspaceIDParams) ([]WorkspaceBuild, error)
 	GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceBuild, error)
@@ -442,8 +692,11 @@ type sqlcQuerier interface {
 	GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWorkspaceByOwnerIDAndNameParams) (Workspace, error)
 	GetWorkspaceByResourceID(ctx context.Context, resourceID uuid.UUID) (Workspace, error)
 	GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (Workspace, error)
+	GetWorkspaceLabelsByWorkspaceIDs(ctx context.Context, workspaceIds []uuid.UUID) ([]WorkspaceLabel, error)
 	GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]WorkspaceModule, error)
 	GetWorkspaceModulesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceModule, error)
+	GetWorkspacePortShareLinkByID(ctx context.Context, id uuid.UUID) (WorkspacePortShareLink, error)
+	GetWorkspacePortShareLinksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspacePortShareLink, error)
 	GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error)
 	// Finds a workspace proxy that has an access URL or app hostname that matches
 	// the provided hostname. This is to check if a hostname matches any workspace
@@ -455,12 +708,15 @@ type sqlcQuerier interface {
 	GetWorkspaceProxyByHostname(ctx context.Context, arg GetWorkspaceProxyByHostnameParams) (WorkspaceProxy, error)
 	GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (WorkspaceProxy, error)
 	GetWorkspaceProxyByName(ctx context.Context, name string) (WorkspaceProxy, error)
+	GetWorkspaceProxyTokenRotationByProxyID(ctx context.Context, proxyID uuid.UUID) (WorkspaceProxyTokenRotation, error)
+	GetWorkspaceProxyTokenRotations(ctx context.Context) ([]WorkspaceProxyTokenRotation, error)
 	GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (WorkspaceResource, error)
 	GetWorkspaceResourceMetadataByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceResourceMetadatum, error)
 	GetWorkspaceResourceMetadataCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResourceMetadatum, error)
 	GetWorkspaceResourcesByJobID(ctx context.Context, jobID uuid.UUID) ([]WorkspaceResource, error)
 	GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceResource, error)
 	GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResource, error)
+	GetWorkspaceSchedulePauseByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceSchedulePause, error)
 	GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error)
 	// build_params is used to filter by build parameters if present.
 	// It has to be a CTE because the set returning function 'unnest' cannot
@@ -469,14 +725,20 @@ type sqlcQuerier interface {
 	GetWorkspacesAndAgentsByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]GetWorkspacesAndAgentsByOwnerIDRow, error)
 	GetWorkspacesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspaceTable, error)
 	GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]GetWorkspacesEligibleForTransitionRow, error)
+	// Only halts the rollout if it's still in progress, so that concurrent
+	// failures halt it once.
+	HaltTemplateVersionRollout(ctx context.Context, arg HaltTemplateVersionRolloutParams) (TemplateVersionRollout, error)
 	// Determines if the template versions table has any rows with has_ai_task = TRUE.
 	HasTemplateVersionsWithAITask(ctx context.Context) (bool, error)
+	IncrementNetworkConnectionQualityCounters(ctx context.Context, arg IncrementNetworkConnectionQualityCountersParams) error
+	InsertAITaskQueueEntry(ctx context.Context, arg InsertAITaskQueueEntryParams) (AITaskQueue, error)
 	InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error)
 	// We use the organization_id as the id
 	// for simplicity since all users is
 	// every member of the org.
 	InsertAllUsersGroup(ctx context.Context, organizationID uuid.UUID) (Group, error)
 	InsertAuditLog(ctx context.Context, arg InsertAuditLogParams) (AuditLog, error)
+	InsertAuditLogLegalHold(ctx context.Context, arg InsertAuditLogLegalHoldParams) (AuditLogLegalHold, error)
 	InsertCryptoKey(ctx context.Context, arg InsertCryptoKeyParams) (CryptoKey, error)
 	InsertCustomRole(ctx context.Context, arg InsertCustomRoleParams) (CustomRole, error)
 	InsertDBCryptKey(ctx context.Context, arg InsertDBCryptKeyParams) error
@@ -487,6 +749,8 @@ type sqlcQuerier interface {
 	InsertGitSSHKey(ctx context.Context, arg InsertGitSSHKeyParams) (GitSSHKey, error)
 	InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error)
 	InsertGroupMember(ctx context.Context, arg InsertGroupMemberParams) error
+	InsertGroupQuotaAllowances(ctx context.Context, arg InsertGroupQuotaAllowancesParams) error
+	InsertHealthScore(ctx context.Context, arg InsertHealthScoreParams) error
 	InsertInboxNotification(ctx context.Context, arg InsertInboxNotificationParams) (InboxNotification, error)
 	InsertLicense(ctx context.Context, arg InsertLicenseParams) (License, error)
 	InsertMemoryResourceMonitor(ctx context.Context, arg InsertMemoryResourceMonitorParams) (WorkspaceAgentMemoryResourceMonitor, error)
@@ -497,6 +761,7 @@ type sqlcQuerier interface {
 	InsertMissingGroups(ctx context.Context, arg InsertMissingGroupsParams) ([]Group, error)
 	InsertOAuth2ProviderApp(ctx context.Context, arg InsertOAuth2ProviderAppParams) (OAuth2ProviderApp, error)
 	InsertOAuth2ProviderAppCode(ctx context.Context, arg InsertOAuth2ProviderAppCodeParams) (OAuth2ProviderAppCode, error)
+	InsertOAuth2ProviderAppDeviceCode(ctx context.Context, arg InsertOAuth2ProviderAppDeviceCodeParams) (OAuth2ProviderAppDeviceCode, error)
 	InsertOAuth2ProviderAppSecret(ctx context.Context, arg InsertOAuth2ProviderAppSecretParams) (OAuth2ProviderAppSecret, error)
 	InsertOAuth2ProviderAppToken(ctx context.Context, arg InsertOAuth2ProviderAppTokenParams) (OAuth2ProviderAppToken, error)
 	InsertOrganization(ctx context.Context, arg InsertOrganizationParams) (Organization, error)
@@ -506,11 +771,19 @@ type sqlcQuerier interface {
 	InsertPresetPrebuildSchedule(ctx context.Context, arg InsertPresetPrebuildScheduleParams) (TemplateVersionPresetPrebuildSchedule, error)
 	InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error)
 	InsertProvisionerJobLogs(ctx context.Context, arg InsertProvisionerJobLogsParams) ([]ProvisionerJobLog, error)
+	InsertProvisionerJobRequeue(ctx context.Context, arg InsertProvisionerJobRequeueParams) error
 	InsertProvisionerJobTimings(ctx context.Context, arg InsertProvisionerJobTimingsParams) ([]ProvisionerJobTiming, error)
 	InsertProvisionerKey(ctx context.Context, arg InsertProvisionerKeyParams) (ProvisionerKey, error)
 	InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error)
+	// If there is a conflict, the user is already a member.
+	InsertSCIMGroupMembers(ctx context.Context, arg InsertSCIMGroupMembersParams) error
+	// If there is a conflict, the group is already a member.
+	InsertSCIMNestedGroups(ctx context.Context, arg InsertSCIMNestedGroupsParams) error
 	InsertTelemetryItemIfNotExists(ctx context.Context, arg InsertTelemetryItemIfNotExistsParams) error
 	InsertTemplate(ctx context.Context, arg InsertTemplateParams) error
+	InsertTemplateBundle(ctx context.Context, arg InsertTemplateBundleParams) error
+	InsertTemplateSecret(ctx context.Context, arg InsertTemplateSecretParams) (TemplateSecret, error)
+	InsertTemplateVariableSet(ctx context.Context, arg InsertTemplateVariableSetParams) error
 	InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error
 	InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error)
 	InsertTemplateVersionTerraformValuesByJobID(ctx context.Context, arg InsertTemplateVersionTerraformValuesByJobIDParams) error
@@ -523,22 +796,36 @@ type sqlcQuerier interface {
 	// InsertUserGroupsByName adds a user to all provided groups, if they exist.
 	InsertUserGroupsByName(ctx context.Context, arg InsertUserGroupsByNameParams) error
 	InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error)
+	InsertUserMFARecoveryCode(ctx context.Context, arg InsertUserMFARecoveryCodeParams) error
+	InsertUserSecret(ctx context.Context, arg InsertUserSecretParams) (UserSecret, error)
+	InsertUserWebAuthnCredential(ctx context.Context, arg InsertUserWebAuthnCredentialParams) (UserWebAuthnCredential, error)
+	InsertVariableSet(ctx context.Context, arg InsertVariableSetParams) (VariableSet, error)
 	InsertVolumeResourceMonitor(ctx context.Context, arg InsertVolumeResourceMonitorParams) (WorkspaceAgentVolumeResourceMonitor, error)
+	InsertWebAuthnChallenge(ctx context.Context, arg InsertWebAuthnChallengeParams) (WebAuthnChallenge, error)
 	InsertWebpushSubscription(ctx context.Context, arg InsertWebpushSubscriptionParams) (WebpushSubscription, error)
 	InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (WorkspaceTable, error)
+	InsertWorkspaceAdoption(ctx context.Context, arg InsertWorkspaceAdoptionParams) (WorkspaceAdoption, error)
 	InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspaceAgentParams) (WorkspaceAgent, error)
 	InsertWorkspaceAgentDevcontainers(ctx context.Context, arg InsertWorkspaceAgentDevcontainersParams) ([]WorkspaceAgentDevcontainer, error)
 	InsertWorkspaceAgentLogSources(ctx context.Context, arg InsertWorkspaceAgentLogSourcesParams) ([]WorkspaceAgentLogSource, error)
 	InsertWorkspaceAgentLogs(ctx context.Context, arg InsertWorkspaceAgentLogsParams) ([]WorkspaceAgentLog, error)
 	InsertWorkspaceAgentMetadata(ctx context.Context, arg InsertWorkspaceAgentMetadataParams) error
+	// Inserts the metadata items pushed by processes in the workspace that are
+	// reported for the first time. They are ordered after the metadata of the
+	// template. Items beyond max_pushed per agent are ignored.
+	InsertWorkspaceAgentPushedMetadata(ctx context.Context, arg InsertWorkspaceAgentPushedMetadataParams) error
 	InsertWorkspaceAgentScriptTimings(ctx context.Context, arg InsertWorkspaceAgentScriptTimingsParams) (WorkspaceAgentScriptTiming, error)
 	InsertWorkspaceAgentScripts(ctx context.Context, arg InsertWorkspaceAgentScriptsParams) ([]WorkspaceAgentScript, error)
 	InsertWorkspaceAgentStats(ctx context.Context, arg InsertWorkspaceAgentStatsParams) error
 	InsertWorkspaceAppStats(ctx context.Context, arg InsertWorkspaceAppStatsParams) error
 	InsertWorkspaceAppStatus(ctx context.Context, arg InsertWorkspaceAppStatusParams) (WorkspaceAppStatus, error)
+	InsertWorkspaceArchive(ctx context.Context, arg InsertWorkspaceArchiveParams) (WorkspaceArchive, error)
 	InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error
 	InsertWorkspaceBuildParameters(ctx context.Context, arg InsertWorkspaceBuildParametersParams) error
+	InsertWorkspaceBuildQuotaCosts(ctx context.Context, arg InsertWorkspaceBuildQuotaCostsParams) error
+	InsertWorkspaceLabels(ctx context.Context, arg InsertWorkspaceLabelsParams) error
 	InsertWorkspaceModule(ctx context.Context, arg InsertWorkspaceModuleParams) (WorkspaceModule, error)
+	InsertWorkspacePortShareLink(ctx context.Context, arg InsertWorkspacePortShareLinkParams) (WorkspacePortShareLink, error)
 	InsertWorkspaceProxy(ctx context.Context, arg InsertWorkspaceProxyParams) (WorkspaceProxy, error)
 	InsertWorkspaceResource(ctx context.Context, arg InsertWorkspaceResourceParams) (WorkspaceResource, error)
 	InsertWorkspaceResourceMetadata(ctx context.Context, arg InsertWorkspaceResourceMetadataParams) ([]WorkspaceResourceMetadatum, error)
@@ -560,7 +847,26 @@ type sqlcQuerier interface {
 	RegisterWorkspaceProxy(ctx context.Context, arg RegisterWorkspaceProxyParams) (WorkspaceProxy, error)
 	RemoveUserFromAllGroups(ctx context.Context, userID uuid.UUID) error
 	RemoveUserFromGroups(ctx context.Context, arg RemoveUserFromGroupsParams) ([]uuid.UUID, error)
+	// Returns a running job to the queue so that another provisioner daemon can
+	// acquire it.
+	RequeueProvisionerJob(ctx context.Context, arg RequeueProvisionerJobParams) error
 	RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error
+	// Replaces the auth token of the agent if it is still @previous_auth_token.
+	// The previous token keeps authenticating the agent until
+	// @previous_auth_token_expires_at. No row is returned if the token was
+	// already rotated.
+	RotateWorkspaceAgentAuthToken(ctx context.Context, arg RotateWorkspaceAgentAuthTokenParams) (WorkspaceAgentTokenRotation, error)
+	// Replaces the token of the proxy if it is still
+	// @previous_token_hashed_secret. The previous token keeps authenticating the
+	// proxy until @previous_token_expires_at. No row is returned if the token was
+	// already rotated.
+	RotateWorkspaceProxyToken(ctx context.Context, arg RotateWorkspaceProxyTokenParams) (WorkspaceProxyTokenRotation, error)
+	// Full-text search over the logs of the provisioner jobs in an organization,
+	// ordered from newest to oldest.
+	SearchProvisionerJobLogs(ctx context.Context, arg SearchProvisionerJobLogsParams) ([]SearchProvisionerJobLogsRow, error)
+	// Starts a rollout that was pending the approval of the activation of its
+	// version.
+	StartTemplateVersionRollout(ctx context.Context, arg StartTemplateVersionRolloutParams) (TemplateVersionRollout, error)
 	// Non blocking lock. Returns true if the lock was acquired, false otherwise.
 	//
 	// This must be called from within a transaction. The lock will be automatically
@@ -569,6 +875,7 @@ type sqlcQuerier interface {
 	// This will always work regardless of the current state of the template version.
 	UnarchiveTemplateVersion(ctx context.Context, arg UnarchiveTemplateVersionParams) error
 	UnfavoriteWorkspace(ctx context.Context, id uuid.UUID) error
+	UpdateAITaskQueueEntryFailed(ctx context.Context, arg UpdateAITaskQueueEntryFailedParams) error
 	UpdateAPIKeyByID(ctx context.Context, arg UpdateAPIKeyByIDParams) error
 	UpdateCryptoKeyDeletesAt(ctx context.Context, arg UpdateCryptoKeyDeletesAtParams) (CryptoKey, error)
 	UpdateCustomRole(ctx context.Context, arg UpdateCustomRoleParams) (CustomRole, error)
@@ -578,11 +885,20 @@ type sqlcQuerier interface {
 	UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDParams) (Group, error)
 	UpdateInactiveUsersToDormant(ctx context.Context, arg UpdateInactiveUsersToDormantParams) ([]UpdateInactiveUsersToDormantRow, error)
 	UpdateInboxNotificationReadStatus(ctx context.Context, arg UpdateInboxNotificationReadStatusParams) error
+	// Marks the inbox notifications of a user as read, or unread if read_at is
+	// NULL. Notifications can be selected by ID, by the category of their
+	// template, or both. Notifications that already have the requested read
+	// status are left untouched so they keep their original read_at.
+	UpdateInboxNotificationsReadStatus(ctx context.Context, arg UpdateInboxNotificationsReadStatusParams) (int64, error)
 	UpdateMemberRoles(ctx context.Context, arg UpdateMemberRolesParams) (OrganizationMember, error)
 	UpdateMemoryResourceMonitor(ctx context.Context, arg UpdateMemoryResourceMonitorParams) error
 	UpdateNotificationTemplateMethodByID(ctx context.Context, arg UpdateNotificationTemplateMethodByIDParams) (NotificationTemplate, error)
 	UpdateOAuth2ProviderAppByClientID(ctx context.Context, arg UpdateOAuth2ProviderAppByClientIDParams) (OAuth2ProviderApp, error)
 	UpdateOAuth2ProviderAppByID(ctx context.Context, arg UpdateOAuth2ProviderAppByIDParams) (OAuth2ProviderApp, error)
+	UpdateOAuth2ProviderAppDeviceCodeLastPolledAt(ctx context.Context, arg UpdateOAuth2ProviderAppDeviceCodeLastPolledAtParams) error
+	// Approves a pending device code. Returns no rows if the code was already
+	// approved.
+	UpdateOAuth2ProviderAppDeviceCodeUserID(ctx context.Context, arg UpdateOAuth2ProviderAppDeviceCodeUserIDParams) (OAuth2ProviderAppDeviceCode, error)
 	UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg UpdateOAuth2ProviderAppSecretByIDParams) (OAuth2ProviderAppSecret, error)
 	UpdateOrganization(ctx context.Context, arg UpdateOrganizationParams) (Organization, error)
 	UpdateOrganizationDeletedByID(ctx context.Context, arg UpdateOrganizationDeletedByIDParams) error
@@ -596,11 +912,18 @@ type sqlcQuerier interface {
 	UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg UpdateTailnetPeerStatusByCoordinatorParams) error
 	UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error
 	UpdateTemplateAccessControlByID(ctx context.Context, arg UpdateTemplateAccessControlByIDParams) error
+	// Replaces the designated reviewers of a template.
+	UpdateTemplateActivationReviewers(ctx context.Context, arg UpdateTemplateActivationReviewersParams) error
 	UpdateTemplateActiveVersionByID(ctx context.Context, arg UpdateTemplateActiveVersionByIDParams) error
 	UpdateTemplateDeletedByID(ctx context.Context, arg UpdateTemplateDeletedByIDParams) error
 	UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error
+	// Moves a template, its versions and the provisioner jobs that imported the
+	// versions to another organization.
+	UpdateTemplateOrganizationByID(ctx context.Context, arg UpdateTemplateOrganizationByIDParams) error
 	UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) error
+	UpdateTemplateSecret(ctx context.Context, arg UpdateTemplateSecretParams) (TemplateSecret, error)
 	UpdateTemplateVersionAITaskByJobID(ctx context.Context, arg UpdateTemplateVersionAITaskByJobIDParams) error
+	UpdateTemplateVersionActivationRequestStatus(ctx context.Context, arg UpdateTemplateVersionActivationRequestStatusParams) (TemplateVersionActivationRequest, error)
 	UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error
 	UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error
 	UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.Context, arg UpdateTemplateVersionExternalAuthProvidersByJobIDParams) error
@@ -613,15 +936,26 @@ type sqlcQuerier interface {
 	UpdateUserLink(ctx context.Context, arg UpdateUserLinkParams) (UserLink, error)
 	UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinkedIDParams) (UserLink, error)
 	UpdateUserLoginType(ctx context.Context, arg UpdateUserLoginTypeParams) (User, error)
+	UpdateUserNotificationCategoryPreferences(ctx context.Context, arg UpdateUserNotificationCategoryPreferencesParams) (int64, error)
+	UpdateUserNotificationDigest(ctx context.Context, arg UpdateUserNotificationDigestParams) (UserConfig, error)
 	UpdateUserNotificationPreferences(ctx context.Context, arg UpdateUserNotificationPreferencesParams) (int64, error)
 	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
+	UpdateUserProxyLatencies(ctx context.Context, arg UpdateUserProxyLatenciesParams) (UserConfig, error)
+	UpdateUserProxyPreference(ctx context.Context, arg UpdateUserProxyPreferenceParams) (UserConfig, error)
 	UpdateUserQuietHoursSchedule(ctx context.Context, arg UpdateUserQuietHoursScheduleParams) (User, error)
 	UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error)
+	UpdateUserSecret(ctx context.Context, arg UpdateUserSecretParams) (UserSecret, error)
+	UpdateUserStartOnConnect(ctx context.Context, arg UpdateUserStartOnConnectParams) (UserConfig, error)
 	UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error)
 	UpdateUserTerminalFont(ctx context.Context, arg UpdateUserTerminalFontParams) (UserConfig, error)
 	UpdateUserThemePreference(ctx context.Context, arg UpdateUserThemePreferenceParams) (UserConfig, error)
+	UpdateUserWebAuthnCredentialSignCount(ctx context.Context, arg UpdateUserWebAuthnCredentialSignCountParams) error
+	UpdateVariableSet(ctx context.Context, arg UpdateVariableSetParams) (VariableSet, error)
 	UpdateVolumeResourceMonitor(ctx context.Context, arg UpdateVolumeResourceMonitorParams) error
 	UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) (WorkspaceTable, error)
+	// Binds the adoption to the workspace created for the machine. No row is
+	// returned if the token was already used or expired.
+	UpdateWorkspaceAdoptionAdopted(ctx context.Context, arg UpdateWorkspaceAdoptionAdoptedParams) (WorkspaceAdoption, error)
 	UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg UpdateWorkspaceAgentConnectionByIDParams) error
 	UpdateWorkspaceAgentLifecycleStateByID(ctx context.Context, arg UpdateWorkspaceAgentLifecycleStateByIDParams) error
 	UpdateWorkspaceAgentLogOverflowByID(ctx context.Context, arg UpdateWorkspaceAgentLogOverflowByIDParams) error
@@ -643,6 +977,10 @@ type sqlcQuerier interface {
 	UpdateWorkspaceProxyDeleted(ctx context.Context, arg UpdateWorkspaceProxyDeletedParams) error
 	UpdateWorkspaceTTL(ctx context.Context, arg UpdateWorkspaceTTLParams) error
 	UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.Context, arg UpdateWorkspacesDormantDeletingAtByTemplateIDParams) ([]WorkspaceTable, error)
+	// Moves the workspaces of a template, and the provisioner jobs of their
+	// builds, to another organization. Deleted workspaces are moved as well so
+	// that they stay in the organization of their template.
+	UpdateWorkspacesOrganizationByTemplateID(ctx context.Context, arg UpdateWorkspacesOrganizationByTemplateIDParams) error
 	UpdateWorkspacesTTLByTemplateID(ctx context.Context, arg UpdateWorkspacesTTLByTemplateIDParams) error
 	UpsertAnnouncementBanners(ctx context.Context, value string) error
 	UpsertAppSecurityKey(ctx context.Context, value string) error
@@ -652,16 +990,27 @@ type sqlcQuerier interface {
 	// So we need to store it's configuration here for display purposes.
 	// The functional values are immutable and controlled implicitly.
 	UpsertDefaultProxy(ctx context.Context, arg UpsertDefaultProxyParams) error
+	UpsertExperimentFlags(ctx context.Context, value string) error
 	UpsertHealthSettings(ctx context.Context, value string) error
+	UpsertInternalCA(ctx context.Context, value string) error
+	UpsertLDAPUser(ctx context.Context, arg UpsertLDAPUserParams) (LDAPUser, error)
 	UpsertLastUpdateCheck(ctx context.Context, value string) error
+	UpsertLicenseSeatReservation(ctx context.Context, arg UpsertLicenseSeatReservationParams) (LicenseSeatReservation, error)
 	UpsertLogoURL(ctx context.Context, value string) error
+	UpsertNetworkConnectionQuality(ctx context.Context, arg UpsertNetworkConnectionQualityParams) error
 	// Insert or update notification report generator logs with recent activity.
 	UpsertNotificationReportGeneratorLog(ctx context.Context, arg UpsertNotificationReportGeneratorLogParams) error
 	UpsertNotificationsSettings(ctx context.Context, value string) error
 	UpsertOAuth2GithubDefaultEligible(ctx context.Context, eligible bool) error
 	UpsertOAuthSigningKey(ctx context.Context, value string) error
+	UpsertOrganizationAuditSettings(ctx context.Context, arg UpsertOrganizationAuditSettingsParams) (OrganizationAuditSetting, error)
+	UpsertOrganizationMFASettings(ctx context.Context, arg UpsertOrganizationMFASettingsParams) (OrganizationMFASetting, error)
+	UpsertOrganizationSettingOverrides(ctx context.Context, arg UpsertOrganizationSettingOverridesParams) (OrganizationSettingOverride, error)
 	UpsertPrebuildsSettings(ctx context.Context, value string) error
 	UpsertProvisionerDaemon(ctx context.Context, arg UpsertProvisionerDaemonParams) (ProvisionerDaemon, error)
+	// The branch may only be moved to another workspace once the workspace it
+	// currently points at has been deleted. No rows are returned otherwise.
+	UpsertReviewWorkspace(ctx context.Context, arg UpsertReviewWorkspaceParams) (ReviewWorkspace, error)
 	UpsertRuntimeConfig(ctx context.Context, arg UpsertRuntimeConfigParams) error
 	UpsertTailnetAgent(ctx context.Context, arg UpsertTailnetAgentParams) (TailnetAgent, error)
 	UpsertTailnetClient(ctx context.Context, arg UpsertTailnetClientParams) (TailnetClient, error)
@@ -670,19 +1019,38 @@ type sqlcQuerier interface {
 	UpsertTailnetPeer(ctx context.Context, arg UpsertTailnetPeerParams) (TailnetPeer, error)
 	UpsertTailnetTunnel(ctx context.Context, arg UpsertTailnetTunnelParams) (TailnetTunnel, error)
 	UpsertTelemetryItem(ctx context.Context, arg UpsertTelemetryItemParams) error
+	UpsertTemplateAppRestriction(ctx context.Context, arg UpsertTemplateAppRestrictionParams) (TemplateAppRestriction, error)
+	UpsertTemplateAppSessionPolicy(ctx context.Context, arg UpsertTemplateAppSessionPolicyParams) (TemplateAppSessionPolicy, error)
+	UpsertTemplateEgressPolicy(ctx context.Context, arg UpsertTemplateEgressPolicyParams) (TemplateEgressPolicy, error)
+	UpsertTemplateParameterOptionSource(ctx context.Context, arg UpsertTemplateParameterOptionSourceParams) (TemplateParameterOptionSource, error)
 	// This query aggregates the workspace_agent_stats and workspace_app_stats data
 	// into a single table for efficient storage and querying. Half-hour buckets are
 	// used to store the data, and the minutes are summed for each user and template
 	// combination. The result is stored in the template_usage_stats table.
 	UpsertTemplateUsageStats(ctx context.Context) error
+	// Requesting the activation of a version again, for example after it was
+	// rejected, resets the request to pending.
+	UpsertTemplateVersionActivationRequest(ctx context.Context, arg UpsertTemplateVersionActivationRequestParams) (TemplateVersionActivationRequest, error)
+	// A reviewer can change their mind, only their latest review counts.
+	UpsertTemplateVersionActivationReview(ctx context.Context, arg UpsertTemplateVersionActivationReviewParams) (TemplateVersionActivationReview, error)
+	// Starting a rollout replaces the previous rollout of the template, which also
+	// restarts the evaluation of the failure threshold. Rollouts of versions whose
+	// activation hasn't been approved yet are created pending approval.
+	UpsertTemplateVersionRollout(ctx context.Context, arg UpsertTemplateVersionRolloutParams) (TemplateVersionRollout, error)
+	UpsertUserCLIVersion(ctx context.Context, arg UpsertUserCLIVersionParams) error
 	UpsertWebpushVAPIDKeys(ctx context.Context, arg UpsertWebpushVAPIDKeysParams) error
 	UpsertWorkspaceAgentPortShare(ctx context.Context, arg UpsertWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error)
+	UpsertWorkspaceAgentVolumeUsage(ctx context.Context, arg UpsertWorkspaceAgentVolumeUsageParams) error
 	UpsertWorkspaceApp(ctx context.Context, arg UpsertWorkspaceAppParams) (WorkspaceApp, error)
 	//
 	// The returned boolean, new_or_stale, can be used to deduce if a new session
 	// was started. This means that a new row was inserted (no previous session) or
-	// the updated_at is older than stale interval.
+	// the updated_at is older than stale interval. Sessions that started before
+	// authenticated_after are also stale, so that re-authenticating starts a new
+	// session.
 	UpsertWorkspaceAppAuditSession(ctx context.Context, arg UpsertWorkspaceAppAuditSessionParams) (bool, error)
+	UpsertWorkspaceSchedulePause(ctx context.Context, arg UpsertWorkspaceSchedulePauseParams) (WorkspaceSchedulePause, error)
+	UseUserMFARecoveryCode(ctx context.Context, arg UseUserMFARecoveryCodeParams) (UserMFARecoveryCode, error)
 }
 
 var _ sqlcQuerier = (*sqlQuerier)(nil)
@@ -1319,6 +1319,166 @@ func TestQueuePosition(t *testing.T) {
 	}
 }
 
+func TestAcquireProvisionerJobBuildLimits(t *testing.T) {
+	t.Parallel()
+
+	db, _ := dbtestutil.NewDB(t)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	org := dbgen.Organization(t, db, database.Organization{})
+	user1 := dbgen.User(t, db, database.User{})
+	user2 := dbgen.User(t, db, database.User{})
+	template1 := dbgen.Template(t, db, database.Template{OrganizationID: org.ID, CreatedBy: user1.ID})
+	template2 := dbgen.Template(t, db, database.Template{OrganizationID: org.ID, CreatedBy: user1.ID})
+
+	build := func(owner database.User, template database.Template, started bool) database.ProvisionerJob {
+		version := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			OrganizationID: org.ID,
+			TemplateID:     uuid.NullUUID{UUID: template.ID, Valid: true},
+			CreatedBy:      owner.ID,
+		})
+		workspace := dbgen.Workspace(t, db, database.WorkspaceTable{
+			OwnerID:        owner.ID,
+			OrganizationID: org.ID,
+			TemplateID:     template.ID,
+		})
+		job := database.ProvisionerJob{
+			OrganizationID: org.ID,
+			Type:           database.ProvisionerJobTypeWorkspaceBuild,
+			Tags:           database.StringMap{},
+		}
+		if started {
+			job.StartedAt = sql.NullTime{Time: dbtime.Now(), Valid: true}
+		}
+		job = dbgen.ProvisionerJob(t, db, nil, job)
+		dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       workspace.ID,
+			TemplateVersionID: version.ID,
+			JobID:             job.ID,
+		})
+		// Jobs are acquired in the order they were created.
+		time.Sleep(time.Millisecond)
+		return job
+	}
+	acquire := func(perUser, perTemplate int32) (database.ProvisionerJob, error) {
+		return db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			OrganizationID:       org.ID,
+			StartedAt:            sql.NullTime{Time: dbtime.Now(), Valid: true},
+			Types:                database.AllProvisionerTypeValues(),
+			WorkerID:             uuid.NullUUID{UUID: uuid.New(), Valid: true},
+			ProvisionerTags:      json.RawMessage("{}"),
+			MaxBuildsPerUser:     perUser,
+			MaxBuildsPerTemplate: perTemplate,
+		})
+	}
+
+	_ = build(user1, template1, true)
+	user1Template1 := build(user1, template1, false)
+	user1Template2 := build(user1, template2, false)
+	user2Template1 := build(user2, template1, false)
+	_ = build(user2, template2, false)
+
+	// user1 is at the limit, so their builds stay queued.
+	job, err := acquire(1, 0)
+	require.NoError(t, err)
+	require.Equal(t, user2Template1.ID, job.ID)
+	_, err = acquire(1, 0)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// template1 now has two running builds.
+	job, err = acquire(2, 2)
+	require.NoError(t, err)
+	require.Equal(t, user1Template2.ID, job.ID)
+
+	job, err = acquire(0, 0)
+	require.NoError(t, err)
+	require.Equal(t, user1Template1.ID, job.ID)
+}
+
+func TestAcquireProvisionerJobOrganizationLimits(t *testing.T) {
+	t.Parallel()
+
+	db, _ := dbtestutil.NewDB(t)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	orgA := dbgen.Organization(t, db, database.Organization{})
+	orgB := dbgen.Organization(t, db, database.Organization{})
+
+	job := func(org database.Organization, started bool) database.ProvisionerJob {
+		job := database.ProvisionerJob{
+			OrganizationID: org.ID,
+			Type:           database.ProvisionerJobTypeTemplateVersionImport,
+			Tags:           database.StringMap{},
+		}
+		if started {
+			job.StartedAt = sql.NullTime{Time: dbtime.Now(), Valid: true}
+		}
+		job = dbgen.ProvisionerJob(t, db, nil, job)
+		// Jobs are acquired in the order they were created.
+		time.Sleep(time.Millisecond)
+		return job
+	}
+	acquire := func(org database.Organization, maxConcurrent int32) (database.ProvisionerJob, error) {
+		return db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			OrganizationID:    org.ID,
+			StartedAt:         sql.NullTime{Time: dbtime.Now(), Valid: true},
+			Types:             database.AllProvisionerTypeValues(),
+			WorkerID:          uuid.NullUUID{UUID: uuid.New(), Valid: true},
+			ProvisionerTags:   json.RawMessage("{}"),
+			MaxConcurrentJobs: maxConcurrent,
+		})
+	}
+	override := func(org database.Organization, maxJobs, reservedJobs sql.NullInt32) {
+		_, err := db.UpsertOrganizationSettingOverrides(ctx, database.UpsertOrganizationSettingOverridesParams{
+			OrganizationID:                  org.ID,
+			DisabledNotificationTemplateIDs: []uuid.UUID{},
+			ProvisionerMaxJobs:              maxJobs,
+			ProvisionerReservedJobs:         reservedJobs,
+			UpdatedAt:                       dbtime.Now(),
+		})
+		require.NoError(t, err)
+	}
+
+	_ = job(orgA, true)
+	_ = job(orgA, true)
+	orgAJob := job(orgA, false)
+	_ = job(orgA, false)
+	orgBJob := job(orgB, false)
+	override(orgB, sql.NullInt32{}, sql.NullInt32{Int32: 1, Valid: true})
+
+	// The last slot of the shared capacity is reserved for orgB.
+	_, err := acquire(orgA, 3)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	acquired, err := acquire(orgB, 3)
+	require.NoError(t, err)
+	require.Equal(t, orgBJob.ID, acquired.ID)
+	_, err = acquire(orgA, 3)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// orgB uses its reservation, so the rest of the capacity is shared.
+	acquired, err = acquire(orgA, 4)
+	require.NoError(t, err)
+	require.Equal(t, orgAJob.ID, acquired.ID)
+
+	// orgA is at its own limit.
+	override(orgA, sql.NullInt32{Int32: 3, Valid: true}, sql.NullInt32{})
+	_, err = acquire(orgA, 0)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	counts, err := db.GetProvisionerJobCountsByOrganization(ctx)
+	require.NoError(t, err)
+	for _, count := range counts {
+		switch count.OrganizationID {
+		case orgA.ID:
+			require.EqualValues(t, 1, count.PendingJobs)
+			require.EqualValues(t, 3, count.RunningJobs)
+		case orgB.ID:
+			require.EqualValues(t, 0, count.PendingJobs)
+			require.EqualValues(t, 1, count.RunningJobs)
+		}
+	}
+}
+
 func TestUserLastSeenFilter(t *testing.T) {
 	t.Parallel()
 	if testing.Short() {
@@ -15,6 +15,210 @@ import (
 	"github.com/sqlc-dev/pqtype"
 )
 
+const getActivityEvents = `-- name: GetActivityEvents :many
+WITH events AS (
+	SELECT
+		audit_logs.id,
+		audit_logs.time AS created_at,
+		'audit'::text AS type,
+		audit_logs.organization_id,
+		audit_logs.user_id,
+		CASE audit_logs.resource_type
+			WHEN 'workspace' THEN audit_logs.resource_id
+			WHEN 'workspace_build' THEN (audit_logs.additional_fields ->> 'workspace_id')::uuid
+		END AS workspace_id,
+		audit_logs.resource_type::text AS resource_type,
+		audit_logs.resource_id,
+		audit_logs.resource_target AS resource_name,
+		audit_logs.action::text AS action,
+		audit_logs.status_code::text AS status
+	FROM
+		audit_logs
+	UNION ALL
+	SELECT
+		workspace_builds.id,
+		workspace_builds.created_at,
+		'build'::text AS type,
+		workspaces.organization_id,
+		workspace_builds.initiator_id AS user_id,
+		workspace_builds.workspace_id,
+		'workspace_build'::text AS resource_type,
+		workspace_builds.id AS resource_id,
+		workspaces.name AS resource_name,
+		workspace_builds.transition::text AS action,
+		provisioner_jobs.job_status::text AS status
+	FROM
+		workspace_builds
+	JOIN
+		workspaces ON workspaces.id = workspace_builds.workspace_id
+	JOIN
+		provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+	UNION ALL
+	SELECT
+		workspace_agent_lifecycle_events.id,
+		workspace_agent_lifecycle_events.created_at,
+		'lifecycle'::text AS type,
+		workspaces.organization_id,
+		workspaces.owner_id AS user_id,
+		workspaces.id AS workspace_id,
+		'workspace_agent'::text AS resource_type,
+		workspace_agents.id AS resource_id,
+		workspace_agents.name AS resource_name,
+		workspace_agent_lifecycle_events.lifecycle_state::text AS action,
+		''::text AS status
+	FROM
+		workspace_agent_lifecycle_events
+	JOIN
+		workspace_agents ON workspace_agents.id = workspace_agent_lifecycle_events.workspace_agent_id
+	JOIN
+		workspace_resources ON workspace_resources.id = workspace_agents.resource_id
+	JOIN
+		workspace_builds ON workspace_builds.job_id = workspace_resources.job_id
+	JOIN
+		workspaces ON workspaces.id = workspace_builds.workspace_id
+)
+SELECT
+	events.id::uuid AS id,
+	events.created_at::timestamptz AS created_at,
+	events.type::text AS type,
+	events.organization_id::uuid AS organization_id,
+	events.user_id::uuid AS user_id,
+	events.workspace_id,
+	events.resource_type::text AS resource_type,
+	events.resource_id::uuid AS resource_id,
+	events.resource_name::text AS resource_name,
+	events.action::text AS action,
+	events.status::text AS status
+FROM
+	events
+WHERE
+	CASE
+		WHEN cardinality($1 :: text[]) > 0 THEN
+			events.type = ANY($1 :: text[])
+		ELSE true
+	END
+	AND CASE
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			events.organization_id = $2
+		ELSE true
+	END
+	AND CASE
+		WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			events.workspace_id = $3
+		ELSE true
+	END
+	AND CASE
+		WHEN $4 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			events.user_id = $4
+		ELSE true
+	END
+	AND CASE
+		-- The pagination cursor is the last ID of the previous page. The feed
+		-- is ordered by time, so select all events before the cursor.
+		WHEN $5 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			(events.created_at, events.id) < (
+				SELECT
+					cursor.created_at, cursor.id
+				FROM
+					events AS cursor
+				WHERE
+					cursor.id = $5
+			)
+		ELSE true
+	END
+	AND CASE
+		WHEN $6 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			(events.created_at, events.id) > (
+				SELECT
+					cursor.created_at, cursor.id
+				FROM
+					events AS cursor
+				WHERE
+					cursor.id = $6
+			)
+		ELSE true
+	END
+ORDER BY
+	CASE WHEN $6 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN events.created_at END ASC,
+	CASE WHEN $6 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN events.id END ASC,
+	events.created_at DESC,
+	events.id DESC
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($7 :: int, 0)
+`
+
+type GetActivityEventsParams struct {
+	Types          []string  `db:"types" json:"types"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	AfterID        uuid.UUID `db:"after_id" json:"after_id"`
+	NewerThanID    uuid.UUID `db:"newer_than_id" json:"newer_than_id"`
+	LimitOpt       int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+type GetActivityEventsRow struct {
+	ID             uuid.UUID     `db:"id" json:"id"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+	Type           string        `db:"type" json:"type"`
+	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
+	UserID         uuid.UUID     `db:"user_id" json:"user_id"`
+	WorkspaceID    uuid.NullUUID `db:"workspace_id" json:"workspace_id"`
+	ResourceType   string        `db:"resource_type" json:"resource_type"`
+	ResourceID     uuid.UUID     `db:"resource_id" json:"resource_id"`
+	ResourceName   string        `db:"resource_name" json:"resource_name"`
+	Action         string        `db:"action" json:"action"`
+	Status         string        `db:"status" json:"status"`
+}
+
+// GetActivityEvents returns audit events, workspace builds and workspace agent
+// lifecycle transitions as a single feed, newest first. When @newer_than_id is
+// set, only the events after that event are returned, oldest first, so a
+// stream can resume from the last event it saw.
+func (q *sqlQuerier) GetActivityEvents(ctx context.Context, arg GetActivityEventsParams) ([]GetActivityEventsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getActivityEvents,
+		pq.Array(arg.Types),
+		arg.OrganizationID,
+		arg.WorkspaceID,
+		arg.UserID,
+		arg.AfterID,
+		arg.NewerThanID,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetActivityEventsRow
+	for rows.Next() {
+		var i GetActivityEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.Type,
+			&i.OrganizationID,
+			&i.UserID,
+			&i.WorkspaceID,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.ResourceName,
+			&i.Action,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const activityBumpWorkspace = `-- name: ActivityBumpWorkspace :exec
 WITH latest AS (
 	SELECT
@@ -107,143 +311,155 @@ func (q *sqlQuerier) ActivityBumpWorkspace(ctx context.Context, arg ActivityBump
 	return err
 }
 
-const deleteAPIKeyByID = `-- name: DeleteAPIKeyByID :exec
-DELETE FROM
-	api_keys
+const countRunningAITasksByOwnerID = `-- name: CountRunningAITasksByOwnerID :one
+SELECT
+	COUNT(*)
+FROM
+	workspaces w
+JOIN LATERAL (
+	SELECT
+		tv.has_ai_task,
+		wb.transition,
+		pj.job_status
+	FROM
+		workspace_builds wb
+	JOIN
+		template_versions tv ON tv.id = wb.template_version_id
+	JOIN
+		provisioner_jobs pj ON pj.id = wb.job_id
+	WHERE
+		wb.workspace_id = w.id
+	ORDER BY
+		wb.build_number DESC
+	LIMIT 1
+) latest_build ON TRUE
 WHERE
-	id = $1
+	w.owner_id = $1
+	AND NOT w.deleted
+	AND latest_build.has_ai_task
+	AND latest_build.transition = 'start'::workspace_transition
+	AND latest_build.job_status IN ('pending', 'running', 'succeeded')
 `
 
-func (q *sqlQuerier) DeleteAPIKeyByID(ctx context.Context, id string) error {
-	_, err := q.db.ExecContext(ctx, deleteAPIKeyByID, id)
-	return err
+// Counts the workspaces of the owner whose latest build starts an AI task and
+// has not failed or been canceled.
+func (q *sqlQuerier) CountRunningAITasksByOwnerID(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRunningAITasksByOwnerID, ownerID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-const deleteAPIKeysByUserID = `-- name: DeleteAPIKeysByUserID :exec
+const deleteAITaskQueueEntryByID = `-- name: DeleteAITaskQueueEntryByID :exec
 DELETE FROM
-	api_keys
+	ai_task_queue
 WHERE
-	user_id = $1
+	id = $1
 `
 
-func (q *sqlQuerier) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteAPIKeysByUserID, userID)
+func (q *sqlQuerier) DeleteAITaskQueueEntryByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteAITaskQueueEntryByID, id)
 	return err
 }
 
-const deleteApplicationConnectAPIKeysByUserID = `-- name: DeleteApplicationConnectAPIKeysByUserID :exec
-DELETE FROM
-	api_keys
+const getAITaskQueueEntriesByOwnerID = `-- name: GetAITaskQueueEntriesByOwnerID :many
+SELECT
+	id, owner_id, organization_id, template_version_id, template_version_preset_id, name, prompt, status, error, created_at, updated_at
+FROM
+	ai_task_queue
 WHERE
-	user_id = $1 AND
-	scope = 'application_connect'::api_key_scope
+	owner_id = $1
+ORDER BY
+	created_at, id
 `
 
-func (q *sqlQuerier) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteApplicationConnectAPIKeysByUserID, userID)
-	return err
+// Returns the queued and failed tasks of the owner, oldest first.
+func (q *sqlQuerier) GetAITaskQueueEntriesByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]AITaskQueue, error) {
+	rows, err := q.db.QueryContext(ctx, getAITaskQueueEntriesByOwnerID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AITaskQueue
+	for rows.Next() {
+		var i AITaskQueue
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.OrganizationID,
+			&i.TemplateVersionID,
+			&i.TemplateVersionPresetID,
+			&i.Name,
+			&i.Prompt,
+			&i.Status,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getAPIKeyByID = `-- name: GetAPIKeyByID :one
+const getAITaskQueueEntryByID = `-- name: GetAITaskQueueEntryByID :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, owner_id, organization_id, template_version_id, template_version_preset_id, name, prompt, status, error, created_at, updated_at
 FROM
-	api_keys
+	ai_task_queue
 WHERE
 	id = $1
-LIMIT
-	1
 `
 
-func (q *sqlQuerier) GetAPIKeyByID(ctx context.Context, id string) (APIKey, error) {
-	row := q.db.QueryRowContext(ctx, getAPIKeyByID, id)
-	var i APIKey
+func (q *sqlQuerier) GetAITaskQueueEntryByID(ctx context.Context, id uuid.UUID) (AITaskQueue, error) {
+	row := q.db.QueryRowContext(ctx, getAITaskQueueEntryByID, id)
+	var i AITaskQueue
 	err := row.Scan(
 		&i.ID,
-		&i.HashedSecret,
-		&i.UserID,
-		&i.LastUsed,
-		&i.ExpiresAt,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateVersionID,
+		&i.TemplateVersionPresetID,
+		&i.Name,
+		&i.Prompt,
+		&i.Status,
+		&i.Error,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.LoginType,
-		&i.LifetimeSeconds,
-		&i.IPAddress,
-		&i.Scope,
-		&i.TokenName,
 	)
 	return i, err
 }
 
-const getAPIKeyByName = `-- name: GetAPIKeyByName :one
-SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+const getAITaskQueueOwnerIDs = `-- name: GetAITaskQueueOwnerIDs :many
+SELECT DISTINCT
+	owner_id
 FROM
-	api_keys
+	ai_task_queue
 WHERE
-	user_id = $1 AND
-	token_name = $2 AND
-	token_name != ''
-LIMIT
-	1
-`
-
-type GetAPIKeyByNameParams struct {
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	TokenName string    `db:"token_name" json:"token_name"`
-}
-
-// there is no unique constraint on empty token names
-func (q *sqlQuerier) GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNameParams) (APIKey, error) {
-	row := q.db.QueryRowContext(ctx, getAPIKeyByName, arg.UserID, arg.TokenName)
-	var i APIKey
-	err := row.Scan(
-		&i.ID,
-		&i.HashedSecret,
-		&i.UserID,
-		&i.LastUsed,
-		&i.ExpiresAt,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.LoginType,
-		&i.LifetimeSeconds,
-		&i.IPAddress,
-		&i.Scope,
-		&i.TokenName,
-	)
-	return i, err
-}
-
-const getAPIKeysByLoginType = `-- name: GetAPIKeysByLoginType :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1
+	status = 'queued'::ai_task_queue_status
 `
 
-func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error) {
-	rows, err := q.db.QueryContext(ctx, getAPIKeysByLoginType, loginType)
+// Returns the owners that have queued tasks.
+func (q *sqlQuerier) GetAITaskQueueOwnerIDs(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getAITaskQueueOwnerIDs)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []APIKey
+	var items []uuid.UUID
 	for rows.Next() {
-		var i APIKey
-		if err := rows.Scan(
-			&i.ID,
-			&i.HashedSecret,
-			&i.UserID,
-			&i.LastUsed,
-			&i.ExpiresAt,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.LoginType,
-			&i.LifetimeSeconds,
-			&i.IPAddress,
-			&i.Scope,
-			&i.TokenName,
-		); err != nil {
+		var owner_id uuid.UUID
+		if err := rows.Scan(&owner_id); err != nil {
 			return nil, err
 		}
-		items = append(items, i)
+		items = append(items, owner_id)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -254,17 +470,305 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 	return items, nil
 }
 
-const getAPIKeysByUserID = `-- name: GetAPIKeysByUserID :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1 AND user_id = $2
+const insertAITaskQueueEntry = `-- name: InsertAITaskQueueEntry :one
+INSERT INTO ai_task_queue (
+	id,
+	owner_id,
+	organization_id,
+	template_version_id,
+	template_version_preset_id,
+	name,
+	prompt,
+	created_at,
+	updated_at
+)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, owner_id, organization_id, template_version_id, template_version_preset_id, name, prompt, status, error, created_at, updated_at
 `
 
-type GetAPIKeysByUserIDParams struct {
-	LoginType LoginType `db:"login_type" json:"login_type"`
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+type InsertAITaskQueueEntryParams struct {
+	ID                      uuid.UUID     `db:"id" json:"id"`
+	OwnerID                 uuid.UUID     `db:"owner_id" json:"owner_id"`
+	OrganizationID          uuid.UUID     `db:"organization_id" json:"organization_id"`
+	TemplateVersionID       uuid.UUID     `db:"template_version_id" json:"template_version_id"`
+	TemplateVersionPresetID uuid.NullUUID `db:"template_version_preset_id" json:"template_version_preset_id"`
+	Name                    string        `db:"name" json:"name"`
+	Prompt                  string        `db:"prompt" json:"prompt"`
+	CreatedAt               time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt               time.Time     `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUserIDParams) ([]APIKey, error) {
-	rows, err := q.db.QueryContext(ctx, getAPIKeysByUserID, arg.LoginType, arg.UserID)
+func (q *sqlQuerier) InsertAITaskQueueEntry(ctx context.Context, arg InsertAITaskQueueEntryParams) (AITaskQueue, error) {
+	row := q.db.QueryRowContext(ctx, insertAITaskQueueEntry,
+		arg.ID,
+		arg.OwnerID,
+		arg.OrganizationID,
+		arg.TemplateVersionID,
+		arg.TemplateVersionPresetID,
+		arg.Name,
+		arg.Prompt,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i AITaskQueue
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateVersionID,
+		&i.TemplateVersionPresetID,
+		&i.Name,
+		&i.Prompt,
+		&i.Status,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateAITaskQueueEntryFailed = `-- name: UpdateAITaskQueueEntryFailed :exec
+UPDATE
+	ai_task_queue
+SET
+	status = 'failed'::ai_task_queue_status,
+	error = $1,
+	updated_at = $2
+WHERE
+	id = $3
+`
+
+type UpdateAITaskQueueEntryFailedParams struct {
+	Error     string    `db:"error" json:"error"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID        uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateAITaskQueueEntryFailed(ctx context.Context, arg UpdateAITaskQueueEntryFailedParams) error {
+	_, err := q.db.ExecContext(ctx, updateAITaskQueueEntryFailed, arg.Error, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const deleteAPIKeyByID = `-- name: DeleteAPIKeyByID :exec
+DELETE FROM
+	api_keys
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteAPIKeyByID(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteAPIKeyByID, id)
+	return err
+}
+
+const deleteAPIKeysByUserID = `-- name: DeleteAPIKeysByUserID :exec
+DELETE FROM
+	api_keys
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteAPIKeysByUserID, userID)
+	return err
+}
+
+const deleteAPIKeysByUserIDLoginType = `-- name: DeleteAPIKeysByUserIDLoginType :exec
+DELETE FROM
+	api_keys
+WHERE
+	user_id = $1 AND
+	login_type = $2
+`
+
+type DeleteAPIKeysByUserIDLoginTypeParams struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	LoginType LoginType `db:"login_type" json:"login_type"`
+}
+
+// Deletes the sessions a user created by logging in with the given login
+// type. API tokens are kept.
+func (q *sqlQuerier) DeleteAPIKeysByUserIDLoginType(ctx context.Context, arg DeleteAPIKeysByUserIDLoginTypeParams) error {
+	_, err := q.db.ExecContext(ctx, deleteAPIKeysByUserIDLoginType, arg.UserID, arg.LoginType)
+	return err
+}
+
+const deleteApplicationConnectAPIKeysByUserID = `-- name: DeleteApplicationConnectAPIKeysByUserID :exec
+DELETE FROM
+	api_keys
+WHERE
+	user_id = $1 AND
+	scope = 'application_connect'::api_key_scope
+`
+
+func (q *sqlQuerier) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteApplicationConnectAPIKeysByUserID, userID)
+	return err
+}
+
+const deleteExcessAPIKeySessionsByUserID = `-- name: DeleteExcessAPIKeySessionsByUserID :exec
+DELETE FROM
+	api_keys
+WHERE
+	id IN (
+		SELECT
+			id
+		FROM
+			api_keys
+		WHERE
+			user_id = $1 AND
+			login_type NOT IN ('token'::login_type, 'oauth2_provider_app'::login_type) AND
+			scope = 'all'::api_key_scope AND
+			token_name = '' AND
+			expires_at > $2
+		ORDER BY
+			GREATEST(last_used, created_at) DESC
+		OFFSET
+			$3
+	)
+`
+
+type DeleteExcessAPIKeySessionsByUserIDParams struct {
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	Now         time.Time `db:"now" json:"now"`
+	MaxSessions int32     `db:"max_sessions" json:"max_sessions"`
+}
+
+// Deletes the least recently used sessions of a user, keeping at most
+// @max_sessions. Sessions are matched like in GetAPIKeySessionsByUserID.
+func (q *sqlQuerier) DeleteExcessAPIKeySessionsByUserID(ctx context.Context, arg DeleteExcessAPIKeySessionsByUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, deleteExcessAPIKeySessionsByUserID, arg.UserID, arg.Now, arg.MaxSessions)
+	return err
+}
+
+const deleteStaleAPIKeySessionsByUserID = `-- name: DeleteStaleAPIKeySessionsByUserID :exec
+DELETE FROM
+	api_keys
+WHERE
+	user_id = $1 AND
+	login_type NOT IN ('token'::login_type, 'oauth2_provider_app'::login_type) AND
+	scope IN ('all'::api_key_scope, 'application_connect'::api_key_scope) AND
+	token_name = '' AND
+	created_at < $2
+`
+
+type DeleteStaleAPIKeySessionsByUserIDParams struct {
+	UserID        uuid.UUID `db:"user_id" json:"user_id"`
+	CreatedBefore time.Time `db:"created_before" json:"created_before"`
+}
+
+// Deletes the sessions of a user that were created before @created_before,
+// including the app keys derived from them, to make the user authenticate
+// again.
+func (q *sqlQuerier) DeleteStaleAPIKeySessionsByUserID(ctx context.Context, arg DeleteStaleAPIKeySessionsByUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, deleteStaleAPIKeySessionsByUserID, arg.UserID, arg.CreatedBefore)
+	return err
+}
+
+const getAPIKeyByID = `-- name: GetAPIKeyByID :one
+SELECT
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, user_agent, device_fingerprint, workspace_id
+FROM
+	api_keys
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetAPIKeyByID(ctx context.Context, id string) (APIKey, error) {
+	row := q.db.QueryRowContext(ctx, getAPIKeyByID, id)
+	var i APIKey
+	err := row.Scan(
+		&i.ID,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.LastUsed,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LoginType,
+		&i.LifetimeSeconds,
+		&i.IPAddress,
+		&i.Scope,
+		&i.TokenName,
+		&i.UserAgent,
+		&i.DeviceFingerprint,
+		&i.WorkspaceID,
+	)
+	return i, err
+}
+
+const getAPIKeyByName = `-- name: GetAPIKeyByName :one
+SELECT
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, user_agent, device_fingerprint, workspace_id
+FROM
+	api_keys
+WHERE
+	user_id = $1 AND
+	token_name = $2 AND
+	token_name != ''
+LIMIT
+	1
+`
+
+type GetAPIKeyByNameParams struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	TokenName string    `db:"token_name" json:"token_name"`
+}
+
+// there is no unique constraint on empty token names
+func (q *sqlQuerier) GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNameParams) (APIKey, error) {
+	row := q.db.QueryRowContext(ctx, getAPIKeyByName, arg.UserID, arg.TokenName)
+	var i APIKey
+	err := row.Scan(
+		&i.ID,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.LastUsed,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LoginType,
+		&i.LifetimeSeconds,
+		&i.IPAddress,
+		&i.Scope,
+		&i.TokenName,
+		&i.UserAgent,
+		&i.DeviceFingerprint,
+		&i.WorkspaceID,
+	)
+	return i, err
+}
+
+const getAPIKeySessionsByUserID = `-- name: GetAPIKeySessionsByUserID :many
+SELECT
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, user_agent, device_fingerprint, workspace_id
+FROM
+	api_keys
+WHERE
+	user_id = $1 AND
+	login_type NOT IN ('token'::login_type, 'oauth2_provider_app'::login_type) AND
+	scope = 'all'::api_key_scope AND
+	token_name = '' AND
+	expires_at > $2
+ORDER BY
+	GREATEST(last_used, created_at) DESC
+`
+
+type GetAPIKeySessionsByUserIDParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Now    time.Time `db:"now" json:"now"`
+}
+
+// Sessions are the unexpired keys a user created by logging in. API tokens,
+// workspace owner tokens and the keys of workspace apps are excluded.
+func (q *sqlQuerier) GetAPIKeySessionsByUserID(ctx context.Context, arg GetAPIKeySessionsByUserIDParams) ([]APIKey, error) {
+	rows, err := q.db.QueryContext(ctx, getAPIKeySessionsByUserID,
+		arg.UserID,
+		arg.Now,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -285,6 +789,9 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.UserAgent,
+			&i.DeviceFingerprint,
+			&i.WorkspaceID,
 		); err != nil {
 			return nil, err
 		}
@@ -299,12 +806,12 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 	return items, nil
 }
 
-const getAPIKeysLastUsedAfter = `-- name: GetAPIKeysLastUsedAfter :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE last_used > $1
+const getAPIKeysByLoginType = `-- name: GetAPIKeysByLoginType :many
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, user_agent, device_fingerprint, workspace_id FROM api_keys WHERE login_type = $1
 `
 
-func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error) {
-	rows, err := q.db.QueryContext(ctx, getAPIKeysLastUsedAfter, lastUsed)
+func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error) {
+	rows, err := q.db.QueryContext(ctx, getAPIKeysByLoginType, loginType)
 	if err != nil {
 		return nil, err
 	}
@@ -325,6 +832,9 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.UserAgent,
+			&i.DeviceFingerprint,
+			&i.WorkspaceID,
 		); err != nil {
 			return nil, err
 		}
@@ -339,65 +849,165 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 	return items, nil
 }
 
-const insertAPIKey = `-- name: InsertAPIKey :one
-INSERT INTO
-	api_keys (
-		id,
-		lifetime_seconds,
-		hashed_secret,
-		ip_address,
-		user_id,
-		last_used,
-		expires_at,
-		created_at,
-		updated_at,
-		login_type,
-		scope,
-		token_name
-	)
-VALUES
-	($1,
-	 -- If the lifetime is set to 0, default to 24hrs
-	 CASE $2::bigint
-	     WHEN 0 THEN 86400
-		 ELSE $2::bigint
-	 END
-	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+const getAPIKeysByUserID = `-- name: GetAPIKeysByUserID :many
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, user_agent, device_fingerprint, workspace_id FROM api_keys WHERE login_type = $1 AND user_id = $2
 `
 
-type InsertAPIKeyParams struct {
-	ID              string      `db:"id" json:"id"`
-	LifetimeSeconds int64       `db:"lifetime_seconds" json:"lifetime_seconds"`
-	HashedSecret    []byte      `db:"hashed_secret" json:"hashed_secret"`
-	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
-	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
-	LastUsed        time.Time   `db:"last_used" json:"last_used"`
-	ExpiresAt       time.Time   `db:"expires_at" json:"expires_at"`
-	CreatedAt       time.Time   `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
-	LoginType       LoginType   `db:"login_type" json:"login_type"`
-	Scope           APIKeyScope `db:"scope" json:"scope"`
-	TokenName       string      `db:"token_name" json:"token_name"`
+type GetAPIKeysByUserIDParams struct {
+	LoginType LoginType `db:"login_type" json:"login_type"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
 }
 
-func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error) {
-	row := q.db.QueryRowContext(ctx, insertAPIKey,
-		arg.ID,
-		arg.LifetimeSeconds,
-		arg.HashedSecret,
-		arg.IPAddress,
-		arg.UserID,
-		arg.LastUsed,
-		arg.ExpiresAt,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-		arg.LoginType,
-		arg.Scope,
-		arg.TokenName,
-	)
-	var i APIKey
-	err := row.Scan(
-		&i.ID,
+func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUserIDParams) ([]APIKey, error) {
+	rows, err := q.db.QueryContext(ctx, getAPIKeysByUserID, arg.LoginType, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []APIKey
+	for rows.Next() {
+		var i APIKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.HashedSecret,
+			&i.UserID,
+			&i.LastUsed,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LoginType,
+			&i.LifetimeSeconds,
+			&i.IPAddress,
+			&i.Scope,
+			&i.TokenName,
+			&i.UserAgent,
+			&i.DeviceFingerprint,
+			&i.WorkspaceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAPIKeysLastUsedAfter = `-- name: GetAPIKeysLastUsedAfter :many
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, user_agent, device_fingerprint, workspace_id FROM api_keys WHERE last_used > $1
+`
+
+func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error) {
+	rows, err := q.db.QueryContext(ctx, getAPIKeysLastUsedAfter, lastUsed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []APIKey
+	for rows.Next() {
+		var i APIKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.HashedSecret,
+			&i.UserID,
+			&i.LastUsed,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LoginType,
+			&i.LifetimeSeconds,
+			&i.IPAddress,
+			&i.Scope,
+			&i.TokenName,
+			&i.UserAgent,
+			&i.DeviceFingerprint,
+			&i.WorkspaceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertAPIKey = `-- name: InsertAPIKey :one
+INSERT INTO
+	api_keys (
+		id,
+		lifetime_seconds,
+		hashed_secret,
+		ip_address,
+		user_id,
+		last_used,
+		expires_at,
+		created_at,
+		updated_at,
+		login_type,
+		scope,
+		token_name,
+		user_agent,
+		device_fingerprint,
+		workspace_id
+	)
+VALUES
+	($1,
+	 -- If the lifetime is set to 0, default to 24hrs
+	 CASE $2::bigint
+	     WHEN 0 THEN 86400
+		 ELSE $2::bigint
+	 END
+	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, user_agent, device_fingerprint, workspace_id
+`
+
+type InsertAPIKeyParams struct {
+	ID                string        `db:"id" json:"id"`
+	LifetimeSeconds   int64         `db:"lifetime_seconds" json:"lifetime_seconds"`
+	HashedSecret      []byte        `db:"hashed_secret" json:"hashed_secret"`
+	IPAddress         pqtype.Inet   `db:"ip_address" json:"ip_address"`
+	UserID            uuid.UUID     `db:"user_id" json:"user_id"`
+	LastUsed          time.Time     `db:"last_used" json:"last_used"`
+	ExpiresAt         time.Time     `db:"expires_at" json:"expires_at"`
+	CreatedAt         time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time     `db:"updated_at" json:"updated_at"`
+	LoginType         LoginType     `db:"login_type" json:"login_type"`
+	Scope             APIKeyScope   `db:"scope" json:"scope"`
+	TokenName         string        `db:"token_name" json:"token_name"`
+	UserAgent         string        `db:"user_agent" json:"user_agent"`
+	DeviceFingerprint string        `db:"device_fingerprint" json:"device_fingerprint"`
+	WorkspaceID       uuid.NullUUID `db:"workspace_id" json:"workspace_id"`
+}
+
+func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error) {
+	row := q.db.QueryRowContext(ctx, insertAPIKey,
+		arg.ID,
+		arg.LifetimeSeconds,
+		arg.HashedSecret,
+		arg.IPAddress,
+		arg.UserID,
+		arg.LastUsed,
+		arg.ExpiresAt,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.LoginType,
+		arg.Scope,
+		arg.TokenName,
+		arg.UserAgent,
+		arg.DeviceFingerprint,
+		arg.WorkspaceID,
+	)
+	var i APIKey
+	err := row.Scan(
+		&i.ID,
 		&i.HashedSecret,
 		&i.UserID,
 		&i.LastUsed,
@@ -409,6 +1019,9 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.UserAgent,
+		&i.DeviceFingerprint,
+		&i.WorkspaceID,
 	)
 	return i, err
 }
@@ -527,6 +1140,11 @@ WHERE
 		WHEN $12::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN audit_logs.request_id = $12
 		ELSE true
 	END
+	-- Filter by full-text search over the target, diff and additional fields
+	AND CASE
+		WHEN $13::text != '' THEN to_tsvector('simple', audit_logs.resource_target || ' ' || audit_logs.diff::text || ' ' || audit_logs.additional_fields::text) @@ websearch_to_tsquery('simple', $13)
+		ELSE true
+	END
 	-- Authorize Filter clause will be injected below in CountAuthorizedAuditLogs
 	-- @authorize_filter
 `
@@ -544,6 +1162,7 @@ type CountAuditLogsParams struct {
 	DateTo         time.Time `db:"date_to" json:"date_to"`
 	BuildReason    string    `db:"build_reason" json:"build_reason"`
 	RequestID      uuid.UUID `db:"request_id" json:"request_id"`
+	Search         string    `db:"search" json:"search"`
 }
 
 func (q *sqlQuerier) CountAuditLogs(ctx context.Context, arg CountAuditLogsParams) (int64, error) {
@@ -560,6 +1179,7 @@ func (q *sqlQuerier) CountAuditLogs(ctx context.Context, arg CountAuditLogsParam
 		arg.DateTo,
 		arg.BuildReason,
 		arg.RequestID,
+		arg.Search,
 	)
 	var count int64
 	err := row.Scan(&count)
@@ -669,13 +1289,18 @@ WHERE
 		WHEN $12::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN audit_logs.request_id = $12
 		ELSE true
 	END
+	-- Filter by full-text search over the target, diff and additional fields
+	AND CASE
+		WHEN $13::text != '' THEN to_tsvector('simple', audit_logs.resource_target || ' ' || audit_logs.diff::text || ' ' || audit_logs.additional_fields::text) @@ websearch_to_tsquery('simple', $13)
+		ELSE true
+	END
 	-- Authorize Filter clause will be injected below in GetAuthorizedAuditLogsOffset
 	-- @authorize_filter
 ORDER BY "time" DESC
 LIMIT -- a limit of 0 means "no limit". The audit log table is unbounded
 	-- in size, and is expected to be quite large. Implement a default
 	-- limit of 100 to prevent accidental excessively large queries.
-	COALESCE(NULLIF($14::int, 0), 100) OFFSET $13
+	COALESCE(NULLIF($15::int, 0), 100) OFFSET $14
 `
 
 type GetAuditLogsOffsetParams struct {
@@ -691,6 +1316,7 @@ type GetAuditLogsOffsetParams struct {
 	DateTo         time.Time `db:"date_to" json:"date_to"`
 	BuildReason    string    `db:"build_reason" json:"build_reason"`
 	RequestID      uuid.UUID `db:"request_id" json:"request_id"`
+	Search         string    `db:"search" json:"search"`
 	OffsetOpt      int32     `db:"offset_opt" json:"offset_opt"`
 	LimitOpt       int32     `db:"limit_opt" json:"limit_opt"`
 }
@@ -730,6 +1356,7 @@ func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOff
 		arg.DateTo,
 		arg.BuildReason,
 		arg.RequestID,
+		arg.Search,
 		arg.OffsetOpt,
 		arg.LimitOpt,
 	)
@@ -880,80 +1507,124 @@ func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParam
 	return i, err
 }
 
-const deleteCryptoKey = `-- name: DeleteCryptoKey :one
-UPDATE crypto_keys
-SET secret = NULL, secret_key_id = NULL
-WHERE feature = $1 AND sequence = $2 RETURNING feature, sequence, secret, secret_key_id, starts_at, deletes_at
+const deleteAuditLogLegalHoldByID = `-- name: DeleteAuditLogLegalHoldByID :exec
+DELETE FROM
+	audit_log_legal_holds
+WHERE
+	id = $1
 `
 
-type DeleteCryptoKeyParams struct {
-	Feature  CryptoKeyFeature `db:"feature" json:"feature"`
-	Sequence int32            `db:"sequence" json:"sequence"`
+func (q *sqlQuerier) DeleteAuditLogLegalHoldByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteAuditLogLegalHoldByID, id)
+	return err
 }
 
-func (q *sqlQuerier) DeleteCryptoKey(ctx context.Context, arg DeleteCryptoKeyParams) (CryptoKey, error) {
-	row := q.db.QueryRowContext(ctx, deleteCryptoKey, arg.Feature, arg.Sequence)
-	var i CryptoKey
-	err := row.Scan(
-		&i.Feature,
-		&i.Sequence,
-		&i.Secret,
-		&i.SecretKeyID,
-		&i.StartsAt,
-		&i.DeletesAt,
+const deleteOldAuditLogs = `-- name: DeleteOldAuditLogs :execrows
+DELETE FROM
+	audit_logs
+WHERE
+	id IN (
+		SELECT
+			audit_logs.id
+		FROM
+			audit_logs
+			JOIN organization_audit_settings ON organization_audit_settings.organization_id = audit_logs.organization_id
+		WHERE
+			organization_audit_settings.retention_days > 0
+			AND audit_logs.time < $1::timestamptz - make_interval(days => organization_audit_settings.retention_days)
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					audit_log_legal_holds
+				WHERE
+					audit_log_legal_holds.organization_id = audit_logs.organization_id
+					AND (
+						audit_log_legal_holds.user_id = audit_logs.user_id
+						OR (audit_logs.resource_type = 'user' AND audit_log_legal_holds.user_id = audit_logs.resource_id)
+						OR (audit_logs.resource_type = 'workspace' AND audit_log_legal_holds.workspace_id = audit_logs.resource_id)
+						OR (
+							audit_logs.resource_type = 'workspace_build'
+							AND audit_log_legal_holds.workspace_id = (
+								SELECT workspace_id FROM workspace_builds WHERE workspace_builds.id = audit_logs.resource_id
+							)
+						)
+					)
+			)
+		LIMIT
+			$2::int
 	)
-	return i, err
-}
-
-const getCryptoKeyByFeatureAndSequence = `-- name: GetCryptoKeyByFeatureAndSequence :one
-SELECT feature, sequence, secret, secret_key_id, starts_at, deletes_at
-FROM crypto_keys
-WHERE feature = $1
-  AND sequence = $2
-  AND secret IS NOT NULL
 `
 
-type GetCryptoKeyByFeatureAndSequenceParams struct {
-	Feature  CryptoKeyFeature `db:"feature" json:"feature"`
-	Sequence int32            `db:"sequence" json:"sequence"`
+type DeleteOldAuditLogsParams struct {
+	Now        time.Time `db:"now" json:"now"`
+	LimitCount int32     `db:"limit_count" json:"limit_count"`
 }
 
-func (q *sqlQuerier) GetCryptoKeyByFeatureAndSequence(ctx context.Context, arg GetCryptoKeyByFeatureAndSequenceParams) (CryptoKey, error) {
-	row := q.db.QueryRowContext(ctx, getCryptoKeyByFeatureAndSequence, arg.Feature, arg.Sequence)
-	var i CryptoKey
+// Deletes up to limit_count audit logs that are older than the retention
+// period of their organization. Audit logs of users and workspaces under a
+// legal hold are kept. Organizations without a retention period keep their
+// audit logs forever.
+func (q *sqlQuerier) DeleteOldAuditLogs(ctx context.Context, arg DeleteOldAuditLogsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldAuditLogs, arg.Now, arg.LimitCount)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getAuditLogLegalHoldByID = `-- name: GetAuditLogLegalHoldByID :one
+SELECT
+	id, organization_id, user_id, workspace_id, reason, created_by, created_at
+FROM
+	audit_log_legal_holds
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetAuditLogLegalHoldByID(ctx context.Context, id uuid.UUID) (AuditLogLegalHold, error) {
+	row := q.db.QueryRowContext(ctx, getAuditLogLegalHoldByID, id)
+	var i AuditLogLegalHold
 	err := row.Scan(
-		&i.Feature,
-		&i.Sequence,
-		&i.Secret,
-		&i.SecretKeyID,
-		&i.StartsAt,
-		&i.DeletesAt,
+		&i.ID,
+		&i.OrganizationID,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.Reason,
+		&i.CreatedBy,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
-const getCryptoKeys = `-- name: GetCryptoKeys :many
-SELECT feature, sequence, secret, secret_key_id, starts_at, deletes_at
-FROM crypto_keys
-WHERE secret IS NOT NULL
+const getAuditLogLegalHoldsByOrganizationID = `-- name: GetAuditLogLegalHoldsByOrganizationID :many
+SELECT
+	id, organization_id, user_id, workspace_id, reason, created_by, created_at
+FROM
+	audit_log_legal_holds
+WHERE
+	organization_id = $1
+ORDER BY
+	created_at ASC
 `
 
-func (q *sqlQuerier) GetCryptoKeys(ctx context.Context) ([]CryptoKey, error) {
-	rows, err := q.db.QueryContext(ctx, getCryptoKeys)
+func (q *sqlQuerier) GetAuditLogLegalHoldsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]AuditLogLegalHold, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogLegalHoldsByOrganizationID, organizationID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []CryptoKey
+	var items []AuditLogLegalHold
 	for rows.Next() {
-		var i CryptoKey
+		var i AuditLogLegalHold
 		if err := rows.Scan(
-			&i.Feature,
-			&i.Sequence,
-			&i.Secret,
-			&i.SecretKeyID,
-			&i.StartsAt,
-			&i.DeletesAt,
+			&i.ID,
+			&i.OrganizationID,
+			&i.UserID,
+			&i.WorkspaceID,
+			&i.Reason,
+			&i.CreatedBy,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -968,25 +1639,194 @@ func (q *sqlQuerier) GetCryptoKeys(ctx context.Context) ([]CryptoKey, error) {
 	return items, nil
 }
 
-const getCryptoKeysByFeature = `-- name: GetCryptoKeysByFeature :many
-SELECT feature, sequence, secret, secret_key_id, starts_at, deletes_at
-FROM crypto_keys
-WHERE feature = $1
-AND secret IS NOT NULL
-ORDER BY sequence DESC
+const getOrganizationAuditSettings = `-- name: GetOrganizationAuditSettings :one
+SELECT
+	organization_id, retention_days, updated_at
+FROM
+	organization_audit_settings
+WHERE
+	organization_id = $1
 `
 
-func (q *sqlQuerier) GetCryptoKeysByFeature(ctx context.Context, feature CryptoKeyFeature) ([]CryptoKey, error) {
-	rows, err := q.db.QueryContext(ctx, getCryptoKeysByFeature, feature)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []CryptoKey
-	for rows.Next() {
-		var i CryptoKey
-		if err := rows.Scan(
-			&i.Feature,
+func (q *sqlQuerier) GetOrganizationAuditSettings(ctx context.Context, organizationID uuid.UUID) (OrganizationAuditSetting, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationAuditSettings, organizationID)
+	var i OrganizationAuditSetting
+	err := row.Scan(&i.OrganizationID, &i.RetentionDays, &i.UpdatedAt)
+	return i, err
+}
+
+const insertAuditLogLegalHold = `-- name: InsertAuditLogLegalHold :one
+INSERT INTO
+	audit_log_legal_holds (id, organization_id, user_id, workspace_id, reason, created_by, created_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, organization_id, user_id, workspace_id, reason, created_by, created_at
+`
+
+type InsertAuditLogLegalHoldParams struct {
+	ID             uuid.UUID     `db:"id" json:"id"`
+	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
+	UserID         uuid.NullUUID `db:"user_id" json:"user_id"`
+	WorkspaceID    uuid.NullUUID `db:"workspace_id" json:"workspace_id"`
+	Reason         string        `db:"reason" json:"reason"`
+	CreatedBy      uuid.UUID     `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertAuditLogLegalHold(ctx context.Context, arg InsertAuditLogLegalHoldParams) (AuditLogLegalHold, error) {
+	row := q.db.QueryRowContext(ctx, insertAuditLogLegalHold,
+		arg.ID,
+		arg.OrganizationID,
+		arg.UserID,
+		arg.WorkspaceID,
+		arg.Reason,
+		arg.CreatedBy,
+		arg.CreatedAt,
+	)
+	var i AuditLogLegalHold
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.Reason,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const upsertOrganizationAuditSettings = `-- name: UpsertOrganizationAuditSettings :one
+INSERT INTO
+	organization_audit_settings (organization_id, retention_days, updated_at)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (organization_id) DO UPDATE SET
+	retention_days = $2,
+	updated_at = $3
+RETURNING organization_id, retention_days, updated_at
+`
+
+type UpsertOrganizationAuditSettingsParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	RetentionDays  int32     `db:"retention_days" json:"retention_days"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertOrganizationAuditSettings(ctx context.Context, arg UpsertOrganizationAuditSettingsParams) (OrganizationAuditSetting, error) {
+	row := q.db.QueryRowContext(ctx, upsertOrganizationAuditSettings, arg.OrganizationID, arg.RetentionDays, arg.UpdatedAt)
+	var i OrganizationAuditSetting
+	err := row.Scan(&i.OrganizationID, &i.RetentionDays, &i.UpdatedAt)
+	return i, err
+}
+
+const deleteCryptoKey = `-- name: DeleteCryptoKey :one
+UPDATE crypto_keys
+SET secret = NULL, secret_key_id = NULL
+WHERE feature = $1 AND sequence = $2 RETURNING feature, sequence, secret, secret_key_id, starts_at, deletes_at
+`
+
+type DeleteCryptoKeyParams struct {
+	Feature  CryptoKeyFeature `db:"feature" json:"feature"`
+	Sequence int32            `db:"sequence" json:"sequence"`
+}
+
+func (q *sqlQuerier) DeleteCryptoKey(ctx context.Context, arg DeleteCryptoKeyParams) (CryptoKey, error) {
+	row := q.db.QueryRowContext(ctx, deleteCryptoKey, arg.Feature, arg.Sequence)
+	var i CryptoKey
+	err := row.Scan(
+		&i.Feature,
+		&i.Sequence,
+		&i.Secret,
+		&i.SecretKeyID,
+		&i.StartsAt,
+		&i.DeletesAt,
+	)
+	return i, err
+}
+
+const getCryptoKeyByFeatureAndSequence = `-- name: GetCryptoKeyByFeatureAndSequence :one
+SELECT feature, sequence, secret, secret_key_id, starts_at, deletes_at
+FROM crypto_keys
+WHERE feature = $1
+  AND sequence = $2
+  AND secret IS NOT NULL
+`
+
+type GetCryptoKeyByFeatureAndSequenceParams struct {
+	Feature  CryptoKeyFeature `db:"feature" json:"feature"`
+	Sequence int32            `db:"sequence" json:"sequence"`
+}
+
+func (q *sqlQuerier) GetCryptoKeyByFeatureAndSequence(ctx context.Context, arg GetCryptoKeyByFeatureAndSequenceParams) (CryptoKey, error) {
+	row := q.db.QueryRowContext(ctx, getCryptoKeyByFeatureAndSequence, arg.Feature, arg.Sequence)
+	var i CryptoKey
+	err := row.Scan(
+		&i.Feature,
+		&i.Sequence,
+		&i.Secret,
+		&i.SecretKeyID,
+		&i.StartsAt,
+		&i.DeletesAt,
+	)
+	return i, err
+}
+
+const getCryptoKeys = `-- name: GetCryptoKeys :many
+SELECT feature, sequence, secret, secret_key_id, starts_at, deletes_at
+FROM crypto_keys
+WHERE secret IS NOT NULL
+`
+
+func (q *sqlQuerier) GetCryptoKeys(ctx context.Context) ([]CryptoKey, error) {
+	rows, err := q.db.QueryContext(ctx, getCryptoKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CryptoKey
+	for rows.Next() {
+		var i CryptoKey
+		if err := rows.Scan(
+			&i.Feature,
+			&i.Sequence,
+			&i.Secret,
+			&i.SecretKeyID,
+			&i.StartsAt,
+			&i.DeletesAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCryptoKeysByFeature = `-- name: GetCryptoKeysByFeature :many
+SELECT feature, sequence, secret, secret_key_id, starts_at, deletes_at
+FROM crypto_keys
+WHERE feature = $1
+AND secret IS NOT NULL
+ORDER BY sequence DESC
+`
+
+func (q *sqlQuerier) GetCryptoKeysByFeature(ctx context.Context, feature CryptoKeyFeature) ([]CryptoKey, error) {
+	rows, err := q.db.QueryContext(ctx, getCryptoKeysByFeature, feature)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CryptoKey
+	for rows.Next() {
+		var i CryptoKey
+		if err := rows.Scan(
+			&i.Feature,
 			&i.Sequence,
 			&i.Secret,
 			&i.SecretKeyID,
@@ -1399,9 +2239,76 @@ func (q *sqlQuerier) UpdateExternalAuthLinkRefreshToken(ctx context.Context, arg
 	return err
 }
 
+const countExternalFilesByHash = `-- name: CountExternalFilesByHash :one
+SELECT
+	COUNT(*)
+FROM
+	files
+WHERE
+	hash = $1
+	AND external
+`
+
+// Counts the files that share the object storage contents of a hash.
+func (q *sqlQuerier) CountExternalFilesByHash(ctx context.Context, hash string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countExternalFilesByHash, hash)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteUnreferencedExternalFiles = `-- name: DeleteUnreferencedExternalFiles :many
+WITH deleted AS (
+	DELETE FROM
+		files
+	WHERE
+		external
+		AND created_at < $1
+		AND NOT EXISTS (
+			SELECT 1 FROM provisioner_jobs WHERE provisioner_jobs.file_id = files.id
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM template_version_terraform_values WHERE template_version_terraform_values.cached_module_files = files.id
+		)
+	RETURNING
+		hash
+)
+SELECT DISTINCT
+	hash
+FROM
+	deleted
+`
+
+// Deletes files in object storage that were created before the given time
+// and are not used by a provisioner job or as cached modules. Returns the
+// hashes of the deleted files, so that their contents can be deleted once no
+// other file shares them.
+func (q *sqlQuerier) DeleteUnreferencedExternalFiles(ctx context.Context, createdBefore time.Time) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, deleteUnreferencedExternalFiles, createdBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		items = append(items, hash)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getFileByHashAndCreator = `-- name: GetFileByHashAndCreator :one
 SELECT
-	hash, created_at, created_by, mimetype, data, id
+	hash, created_at, created_by, mimetype, data, id, external
 FROM
 	files
 WHERE
@@ -1427,13 +2334,14 @@ func (q *sqlQuerier) GetFileByHashAndCreator(ctx context.Context, arg GetFileByH
 		&i.Mimetype,
 		&i.Data,
 		&i.ID,
+		&i.External,
 	)
 	return i, err
 }
 
 const getFileByID = `-- name: GetFileByID :one
 SELECT
-	hash, created_at, created_by, mimetype, data, id
+	hash, created_at, created_by, mimetype, data, id, external
 FROM
 	files
 WHERE
@@ -1452,6 +2360,7 @@ func (q *sqlQuerier) GetFileByID(ctx context.Context, id uuid.UUID) (File, error
 		&i.Mimetype,
 		&i.Data,
 		&i.ID,
+		&i.External,
 	)
 	return i, err
 }
@@ -1551,9 +2460,9 @@ func (q *sqlQuerier) GetFileTemplates(ctx context.Context, fileID uuid.UUID) ([]
 
 const insertFile = `-- name: InsertFile :one
 INSERT INTO
-	files (id, hash, created_at, created_by, mimetype, "data")
+	files (id, hash, created_at, created_by, mimetype, "data", external)
 VALUES
-	($1, $2, $3, $4, $5, $6) RETURNING hash, created_at, created_by, mimetype, data, id
+	($1, $2, $3, $4, $5, $6) RETURNING hash, created_at, created_by, mimetype, data, id, external
 `
 
 type InsertFileParams struct {
@@ -1563,6 +2472,7 @@ type InsertFileParams struct {
 	CreatedBy uuid.UUID `db:"created_by" json:"created_by"`
 	Mimetype  string    `db:"mimetype" json:"mimetype"`
 	Data      []byte    `db:"data" json:"data"`
+	External  bool      `db:"external" json:"external"`
 }
 
 func (q *sqlQuerier) InsertFile(ctx context.Context, arg InsertFileParams) (File, error) {
@@ -1573,6 +2483,7 @@ func (q *sqlQuerier) InsertFile(ctx context.Context, arg InsertFileParams) (File
 		arg.CreatedBy,
 		arg.Mimetype,
 		arg.Data,
+		arg.External,
 	)
 	var i File
 	err := row.Scan(
@@ -1582,6 +2493,7 @@ func (q *sqlQuerier) InsertFile(ctx context.Context, arg InsertFileParams) (File
 		&i.Mimetype,
 		&i.Data,
 		&i.ID,
+		&i.External,
 	)
 	return i, err
 }
@@ -2339,23 +3251,288 @@ func (q *sqlQuerier) UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDPar
 	return i, err
 }
 
-const getTemplateAppInsights = `-- name: GetTemplateAppInsights :many
+const deleteOldHealthScores = `-- name: DeleteOldHealthScores :exec
+DELETE FROM
+	health_scores
+WHERE
+	created_at < $1::timestamptz
+`
+
+func (q *sqlQuerier) DeleteOldHealthScores(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldHealthScores, beforeTime)
+	return err
+}
+
+const getHealthScores = `-- name: GetHealthScores :many
+SELECT
+	replica_id, created_at, score, database_score, database_latency_ms, derp_score, provisioner_daemons_score, replicas_score, websocket_score
+FROM
+	health_scores
+WHERE
+	created_at >= $1::timestamptz
+	AND created_at < $2::timestamptz
+ORDER BY
+	created_at ASC, replica_id ASC
+`
+
+type GetHealthScoresParams struct {
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+}
+
+// Returns the scores of all replicas recorded in [@start_time, @end_time).
+func (q *sqlQuerier) GetHealthScores(ctx context.Context, arg GetHealthScoresParams) ([]HealthScore, error) {
+	rows, err := q.db.QueryContext(ctx, getHealthScores, arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HealthScore
+	for rows.Next() {
+		var i HealthScore
+		if err := rows.Scan(
+			&i.ReplicaID,
+			&i.CreatedAt,
+			&i.Score,
+			&i.DatabaseScore,
+			&i.DatabaseLatencyMS,
+			&i.DERPScore,
+			&i.ProvisionerDaemonsScore,
+			&i.ReplicasScore,
+			&i.WebsocketScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertHealthScore = `-- name: InsertHealthScore :exec
+INSERT INTO
+	health_scores (replica_id, created_at, score, database_score, database_latency_ms, derp_score, provisioner_daemons_score, replicas_score, websocket_score)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9)
+`
+
+type InsertHealthScoreParams struct {
+	ReplicaID               uuid.UUID `db:"replica_id" json:"replica_id"`
+	CreatedAt               time.Time `db:"created_at" json:"created_at"`
+	Score                   int32     `db:"score" json:"score"`
+	DatabaseScore           int32     `db:"database_score" json:"database_score"`
+	DatabaseLatencyMS       int64     `db:"database_latency_ms" json:"database_latency_ms"`
+	DERPScore               int32     `db:"derp_score" json:"derp_score"`
+	ProvisionerDaemonsScore int32     `db:"provisioner_daemons_score" json:"provisioner_daemons_score"`
+	ReplicasScore           int32     `db:"replicas_score" json:"replicas_score"`
+	WebsocketScore          int32     `db:"websocket_score" json:"websocket_score"`
+}
+
+func (q *sqlQuerier) InsertHealthScore(ctx context.Context, arg InsertHealthScoreParams) error {
+	_, err := q.db.ExecContext(ctx, insertHealthScore, arg.ReplicaID, arg.CreatedAt, arg.Score, arg.DatabaseScore, arg.DatabaseLatencyMS, arg.DERPScore, arg.ProvisionerDaemonsScore, arg.ReplicasScore, arg.WebsocketScore)
+	return err
+}
+
+const getGroupWorkspaceUsage = `-- name: GetGroupWorkspaceUsage :many
 WITH
-	-- Create a list of all unique apps by template, this is used to
-	-- filter out irrelevant template usage stats.
-	apps AS (
-		SELECT DISTINCT ON (ws.template_id, app.slug)
-			ws.template_id,
-			app.slug,
-			app.display_name,
-			app.icon
+	builds AS (
+		SELECT
+			wb.id,
+			wb.workspace_id,
+			wb.transition,
+			wb.daily_cost,
+			pj.job_status,
+			pj.completed_at,
+			LEAD(wb.created_at) OVER (PARTITION BY wb.workspace_id ORDER BY wb.build_number) AS next_build_at
 		FROM
-			workspaces ws
+			workspace_builds AS wb
 		JOIN
-			workspace_builds AS build
+			provisioner_jobs AS pj
 		ON
-			build.workspace_id = ws.id
-		JOIN
+			pj.id = wb.job_id
+		WHERE
+			wb.created_at < $1::timestamptz
+	),
+	runs AS (
+		SELECT
+			builds.id AS workspace_build_id,
+			builds.workspace_id,
+			builds.daily_cost,
+			EXTRACT(EPOCH FROM (
+				LEAST(COALESCE(builds.next_build_at, NOW()), $1::timestamptz)
+				- GREATEST(builds.completed_at, $2::timestamptz)
+			))::float AS seconds
+		FROM
+			builds
+		WHERE
+			builds.transition = 'start'
+			AND builds.job_status = 'succeeded'
+			AND builds.completed_at < $1::timestamptz
+			AND (builds.next_build_at IS NULL OR builds.next_build_at > $2::timestamptz)
+	),
+	workspace_runs AS (
+		SELECT
+			workspaces.owner_id,
+			workspaces.organization_id,
+			workspaces.last_used_at,
+			runs.workspace_build_id,
+			runs.workspace_id,
+			runs.daily_cost,
+			runs.seconds
+		FROM
+			runs
+		JOIN
+			workspaces
+		ON
+			workspaces.id = runs.workspace_id
+		WHERE
+			runs.seconds > 0
+			-- The system user responsible for prebuilds.
+			AND workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+	),
+	dimension_hours AS (
+		SELECT
+			members.group_id,
+			costs.dimension,
+			(SUM(costs.cost * workspace_runs.seconds) / 3600)::float AS hours
+		FROM
+			workspace_runs
+		JOIN
+			group_members_expanded AS members
+		ON
+			members.user_id = workspace_runs.owner_id
+			AND members.organization_id = workspace_runs.organization_id
+		JOIN
+			workspace_build_quota_costs AS costs
+		ON
+			costs.workspace_build_id = workspace_runs.workspace_build_id
+		GROUP BY
+			members.group_id, costs.dimension
+	)
+
+SELECT
+	groups.id AS group_id,
+	groups.name AS group_name,
+	groups.display_name AS group_display_name,
+	groups.organization_id,
+	organizations.name AS organization_name,
+	COUNT(DISTINCT workspace_runs.owner_id) AS users,
+	COUNT(DISTINCT workspace_runs.workspace_id) AS workspaces,
+	(SUM(workspace_runs.seconds) / 3600)::float AS runtime_hours,
+	(SUM(workspace_runs.daily_cost * workspace_runs.seconds) / 86400)::float AS cost,
+	MAX(workspace_runs.last_used_at)::timestamptz AS last_workspace_used_at,
+	ARRAY(
+		SELECT dimension_hours.dimension FROM dimension_hours
+		WHERE dimension_hours.group_id = groups.id ORDER BY dimension_hours.dimension
+	)::text[] AS dimensions,
+	ARRAY(
+		SELECT dimension_hours.hours FROM dimension_hours
+		WHERE dimension_hours.group_id = groups.id ORDER BY dimension_hours.dimension
+	)::float[] AS dimension_hours
+FROM
+	groups
+JOIN
+	organizations
+ON
+	organizations.id = groups.organization_id
+JOIN
+	group_members_expanded
+ON
+	group_members_expanded.group_id = groups.id
+JOIN
+	workspace_runs
+ON
+	workspace_runs.owner_id = group_members_expanded.user_id
+	AND workspace_runs.organization_id = groups.organization_id
+GROUP BY
+	groups.id, organizations.name
+ORDER BY
+	cost DESC, runtime_hours DESC, groups.name;`
+
+type GetGroupWorkspaceUsageParams struct {
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+	StartTime time.Time `db:"start_time" json:"start_time"`
+}
+
+type GetGroupWorkspaceUsageRow struct {
+	GroupID             uuid.UUID `db:"group_id" json:"group_id"`
+	GroupName           string    `db:"group_name" json:"group_name"`
+	GroupDisplayName    string    `db:"group_display_name" json:"group_display_name"`
+	OrganizationID      uuid.UUID `db:"organization_id" json:"organization_id"`
+	OrganizationName    string    `db:"organization_name" json:"organization_name"`
+	Users               int64     `db:"users" json:"users"`
+	Workspaces          int64     `db:"workspaces" json:"workspaces"`
+	RuntimeHours        float64   `db:"runtime_hours" json:"runtime_hours"`
+	Cost                float64   `db:"cost" json:"cost"`
+	LastWorkspaceUsedAt time.Time `db:"last_workspace_used_at" json:"last_workspace_used_at"`
+	Dimensions          []string  `db:"dimensions" json:"dimensions"`
+	DimensionHours      []float64 `db:"dimension_hours" json:"dimension_hours"`
+}
+
+// GetGroupWorkspaceUsage returns, for every group with members who owned a
+// running workspace in the organization of the group between start and end
+// time, the number of those members and of their workspaces that ran, the
+// hours those workspaces ran for and the quota credits they accrued. Users are
+// counted in every group they are a member of. Keep the CTEs in sync with
+// GetUserWorkspaceUsage.
+func (q *sqlQuerier) GetGroupWorkspaceUsage(ctx context.Context, arg GetGroupWorkspaceUsageParams) ([]GetGroupWorkspaceUsageRow, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupWorkspaceUsage, arg.EndTime, arg.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetGroupWorkspaceUsageRow
+	for rows.Next() {
+		var i GetGroupWorkspaceUsageRow
+		if err := rows.Scan(
+			&i.GroupID,
+			&i.GroupName,
+			&i.GroupDisplayName,
+			&i.OrganizationID,
+			&i.OrganizationName,
+			&i.Users,
+			&i.Workspaces,
+			&i.RuntimeHours,
+			&i.Cost,
+			&i.LastWorkspaceUsedAt,
+			pq.Array(&i.Dimensions),
+			pq.Array(&i.DimensionHours),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateAppInsights = `-- name: GetTemplateAppInsights :many
+WITH
+	-- Create a list of all unique apps by template, this is used to
+	-- filter out irrelevant template usage stats.
+	apps AS (
+		SELECT DISTINCT ON (ws.template_id, app.slug)
+			ws.template_id,
+			app.slug,
+			app.display_name,
+			app.icon
+		FROM
+			workspaces ws
+		JOIN
+			workspace_builds AS build
+		ON
+			build.workspace_id = ws.id
+		JOIN
 			workspace_resources AS resource
 		ON
 			resource.job_id = build.job_id
@@ -2642,6 +3819,290 @@ func (q *sqlQuerier) GetTemplateAppInsightsByTemplate(ctx context.Context, arg G
 	return items, nil
 }
 
+const getTemplateBuildFailures = `-- name: GetTemplateBuildFailures :many
+SELECT
+	COALESCE(pj.error, '')::text AS error,
+	COUNT(*) AS builds,
+	MAX(pj.completed_at)::timestamptz AS last_failed_at
+FROM
+	workspace_builds AS wb
+JOIN
+	template_versions AS tv
+ON
+	tv.id = wb.template_version_id
+JOIN
+	provisioner_jobs AS pj
+ON
+	pj.id = wb.job_id
+WHERE
+	tv.template_id = $1
+	AND pj.job_status = 'failed'
+	AND pj.completed_at >= $2::timestamptz
+	AND pj.completed_at < $3::timestamptz
+GROUP BY
+	COALESCE(pj.error, '')
+ORDER BY
+	builds DESC, last_failed_at DESC
+LIMIT
+	$4::int
+`
+
+type GetTemplateBuildFailuresParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	StartTime  time.Time `db:"start_time" json:"start_time"`
+	EndTime    time.Time `db:"end_time" json:"end_time"`
+	LimitCount int32     `db:"limit_count" json:"limit_count"`
+}
+
+type GetTemplateBuildFailuresRow struct {
+	Error        string    `db:"error" json:"error"`
+	Builds       int64     `db:"builds" json:"builds"`
+	LastFailedAt time.Time `db:"last_failed_at" json:"last_failed_at"`
+}
+
+// GetTemplateBuildFailures returns the distinct error messages of the failed
+// workspace builds of a template that completed between start and end time,
+// along with the number of builds that failed with each message and when the
+// last one completed.
+func (q *sqlQuerier) GetTemplateBuildFailures(ctx context.Context, arg GetTemplateBuildFailuresParams) ([]GetTemplateBuildFailuresRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateBuildFailures,
+		arg.TemplateID,
+		arg.StartTime,
+		arg.EndTime,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateBuildFailuresRow
+	for rows.Next() {
+		var i GetTemplateBuildFailuresRow
+		if err := rows.Scan(&i.Error, &i.Builds, &i.LastFailedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateBuildInsightsByInterval = `-- name: GetTemplateBuildInsightsByInterval :many
+WITH
+	ts AS (
+		SELECT
+			d::timestamptz AS from_,
+			LEAST(
+				(d::timestamptz + ($1::int || ' day')::interval)::timestamptz,
+				$2::timestamptz
+			)::timestamptz AS to_
+		FROM
+			generate_series(
+				$3::timestamptz,
+				-- Subtract 1 μs to avoid creating an extra series.
+				($2::timestamptz) - '1 microsecond'::interval,
+				($1::int || ' day')::interval
+			) AS d
+	),
+	builds AS (
+		SELECT
+			pj.job_status,
+			pj.completed_at
+		FROM
+			workspace_builds AS wb
+		JOIN
+			template_versions AS tv
+		ON
+			tv.id = wb.template_version_id
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			tv.template_id = $4
+			AND pj.completed_at >= $3::timestamptz
+			AND pj.completed_at < $2::timestamptz
+	)
+
+SELECT
+	ts.from_ AS start_time,
+	ts.to_ AS end_time,
+	COUNT(builds.completed_at) FILTER (WHERE builds.job_status = 'succeeded') AS succeeded_builds,
+	COUNT(builds.completed_at) FILTER (WHERE builds.job_status = 'failed') AS failed_builds,
+	COUNT(builds.completed_at) FILTER (WHERE builds.job_status = 'canceled') AS canceled_builds
+FROM
+	ts
+LEFT JOIN
+	builds
+ON
+	builds.completed_at >= ts.from_
+	AND builds.completed_at < ts.to_
+GROUP BY
+	ts.from_, ts.to_
+ORDER BY
+	ts.from_
+`
+
+type GetTemplateBuildInsightsByIntervalParams struct {
+	IntervalDays int32     `db:"interval_days" json:"interval_days"`
+	EndTime      time.Time `db:"end_time" json:"end_time"`
+	StartTime    time.Time `db:"start_time" json:"start_time"`
+	TemplateID   uuid.UUID `db:"template_id" json:"template_id"`
+}
+
+type GetTemplateBuildInsightsByIntervalRow struct {
+	StartTime       time.Time `db:"start_time" json:"start_time"`
+	EndTime         time.Time `db:"end_time" json:"end_time"`
+	SucceededBuilds int64     `db:"succeeded_builds" json:"succeeded_builds"`
+	FailedBuilds    int64     `db:"failed_builds" json:"failed_builds"`
+	CanceledBuilds  int64     `db:"canceled_builds" json:"canceled_builds"`
+}
+
+// GetTemplateBuildInsightsByInterval returns the number of succeeded, failed
+// and canceled workspace builds of a template for all intervals between start
+// and end time. Builds are counted in the interval their job completed in. If
+// end time is a partial interval, it will be included in the results and that
+// interval will be shorter than a full one.
+func (q *sqlQuerier) GetTemplateBuildInsightsByInterval(ctx context.Context, arg GetTemplateBuildInsightsByIntervalParams) ([]GetTemplateBuildInsightsByIntervalRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateBuildInsightsByInterval,
+		arg.IntervalDays,
+		arg.EndTime,
+		arg.StartTime,
+		arg.TemplateID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateBuildInsightsByIntervalRow
+	for rows.Next() {
+		var i GetTemplateBuildInsightsByIntervalRow
+		if err := rows.Scan(
+			&i.StartTime,
+			&i.EndTime,
+			&i.SucceededBuilds,
+			&i.FailedBuilds,
+			&i.CanceledBuilds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateBuildStageDurations = `-- name: GetTemplateBuildStageDurations :many
+WITH
+	builds AS (
+		SELECT
+			pj.id AS job_id,
+			pj.started_at,
+			pj.completed_at
+		FROM
+			workspace_builds AS wb
+		JOIN
+			template_versions AS tv
+		ON
+			tv.id = wb.template_version_id
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			tv.template_id = $1
+			AND pj.job_status = 'succeeded'
+			AND pj.started_at IS NOT NULL
+			AND pj.completed_at >= $2::timestamptz
+			AND pj.completed_at < $3::timestamptz
+	),
+	durations AS (
+		SELECT
+			'total'::text AS stage,
+			EXTRACT(EPOCH FROM (builds.completed_at - builds.started_at))::float AS seconds
+		FROM
+			builds
+		UNION ALL
+		SELECT
+			pjt.stage::text AS stage,
+			EXTRACT(EPOCH FROM (MAX(pjt.ended_at) - MIN(pjt.started_at)))::float AS seconds
+		FROM
+			provisioner_job_timings AS pjt
+		JOIN
+			builds
+		ON
+			builds.job_id = pjt.job_id
+		GROUP BY
+			pjt.job_id, pjt.stage
+	)
+
+SELECT
+	durations.stage::text AS stage,
+	COUNT(*) AS builds,
+	PERCENTILE_DISC(0.5) WITHIN GROUP (ORDER BY durations.seconds)::float AS p50_seconds,
+	PERCENTILE_DISC(0.95) WITHIN GROUP (ORDER BY durations.seconds)::float AS p95_seconds
+FROM
+	durations
+GROUP BY
+	durations.stage
+`
+
+type GetTemplateBuildStageDurationsParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	StartTime  time.Time `db:"start_time" json:"start_time"`
+	EndTime    time.Time `db:"end_time" json:"end_time"`
+}
+
+type GetTemplateBuildStageDurationsRow struct {
+	Stage      string  `db:"stage" json:"stage"`
+	Builds     int64   `db:"builds" json:"builds"`
+	P50Seconds float64 `db:"p50_seconds" json:"p50_seconds"`
+	P95Seconds float64 `db:"p95_seconds" json:"p95_seconds"`
+}
+
+// GetTemplateBuildStageDurations returns the median and 95th percentile
+// duration in seconds of the successful workspace builds of a template that
+// completed between start and end time. The "total" stage covers the whole
+// provisioner job, the other stages are the provisioner job timing stages
+// (init, plan, graph and apply).
+func (q *sqlQuerier) GetTemplateBuildStageDurations(ctx context.Context, arg GetTemplateBuildStageDurationsParams) ([]GetTemplateBuildStageDurationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateBuildStageDurations, arg.TemplateID, arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateBuildStageDurationsRow
+	for rows.Next() {
+		var i GetTemplateBuildStageDurationsRow
+		if err := rows.Scan(
+			&i.Stage,
+			&i.Builds,
+			&i.P50Seconds,
+			&i.P95Seconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTemplateInsights = `-- name: GetTemplateInsights :one
 WITH
 	insights AS (
@@ -3421,26 +4882,185 @@ func (q *sqlQuerier) GetUserStatusCounts(ctx context.Context, arg GetUserStatusC
 	return items, nil
 }
 
-const upsertTemplateUsageStats = `-- name: UpsertTemplateUsageStats :exec
+const getUserWorkspaceUsage = `-- name: GetUserWorkspaceUsage :many
 WITH
-	latest_start AS (
+	builds AS (
 		SELECT
-			-- Truncate to hour so that we always look at even ranges of data.
-			date_trunc('hour', COALESCE(
-				MAX(start_time) - '1 hour'::interval,
-				-- Fallback when there are no template usage stats yet.
-				-- App stats can exist before this, but not agent stats,
-				-- limit the lookback to avoid inconsistency.
-				(SELECT MIN(created_at) FROM workspace_agent_stats)
-			)) AS t
+			wb.id,
+			wb.workspace_id,
+			wb.transition,
+			wb.daily_cost,
+			pj.job_status,
+			pj.completed_at,
+			LEAD(wb.created_at) OVER (PARTITION BY wb.workspace_id ORDER BY wb.build_number) AS next_build_at
 		FROM
-			template_usage_stats
+			workspace_builds AS wb
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			-- Builds created after the end time only end runs that last
+			-- until the end time anyway.
+			wb.created_at < $1::timestamptz
 	),
-	workspace_app_stat_buckets AS (
+	runs AS (
 		SELECT
-			-- Truncate the minute to the nearest half hour, this is the bucket size
-			-- for the data.
-			date_trunc('hour', s.minute_bucket) + trunc(date_part('minute', s.minute_bucket) / 30) * 30 * '1 minute'::interval AS time_bucket,
+			builds.id AS workspace_build_id,
+			builds.workspace_id,
+			builds.daily_cost,
+			EXTRACT(EPOCH FROM (
+				LEAST(COALESCE(builds.next_build_at, NOW()), $1::timestamptz)
+				- GREATEST(builds.completed_at, $2::timestamptz)
+			))::float AS seconds
+		FROM
+			builds
+		WHERE
+			builds.transition = 'start'
+			AND builds.job_status = 'succeeded'
+			AND builds.completed_at < $1::timestamptz
+			AND (builds.next_build_at IS NULL OR builds.next_build_at > $2::timestamptz)
+	),
+	dimension_hours AS (
+		SELECT
+			workspaces.owner_id,
+			costs.dimension,
+			(SUM(costs.cost * runs.seconds) / 3600)::float AS hours
+		FROM
+			runs
+		JOIN
+			workspaces
+		ON
+			workspaces.id = runs.workspace_id
+		JOIN
+			workspace_build_quota_costs AS costs
+		ON
+			costs.workspace_build_id = runs.workspace_build_id
+		WHERE
+			runs.seconds > 0
+		GROUP BY
+			workspaces.owner_id, costs.dimension
+	)
+
+SELECT
+	users.id AS user_id,
+	users.username,
+	users.name,
+	users.email,
+	users.last_seen_at,
+	COUNT(DISTINCT runs.workspace_id) AS workspaces,
+	(SUM(runs.seconds) / 3600)::float AS runtime_hours,
+	(SUM(runs.daily_cost * runs.seconds) / 86400)::float AS cost,
+	MAX(workspaces.last_used_at)::timestamptz AS last_workspace_used_at,
+	ARRAY(
+		SELECT dimension_hours.dimension FROM dimension_hours
+		WHERE dimension_hours.owner_id = users.id ORDER BY dimension_hours.dimension
+	)::text[] AS dimensions,
+	ARRAY(
+		SELECT dimension_hours.hours FROM dimension_hours
+		WHERE dimension_hours.owner_id = users.id ORDER BY dimension_hours.dimension
+	)::float[] AS dimension_hours
+FROM
+	runs
+JOIN
+	workspaces
+ON
+	workspaces.id = runs.workspace_id
+JOIN
+	users
+ON
+	users.id = workspaces.owner_id
+WHERE
+	runs.seconds > 0
+	-- The system user responsible for prebuilds.
+	AND workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+GROUP BY
+	users.id
+ORDER BY
+	cost DESC, runtime_hours DESC, users.username;`
+
+type GetUserWorkspaceUsageParams struct {
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+	StartTime time.Time `db:"start_time" json:"start_time"`
+}
+
+type GetUserWorkspaceUsageRow struct {
+	UserID              uuid.UUID `db:"user_id" json:"user_id"`
+	Username            string    `db:"username" json:"username"`
+	Name                string    `db:"name" json:"name"`
+	Email               string    `db:"email" json:"email"`
+	LastSeenAt          time.Time `db:"last_seen_at" json:"last_seen_at"`
+	Workspaces          int64     `db:"workspaces" json:"workspaces"`
+	RuntimeHours        float64   `db:"runtime_hours" json:"runtime_hours"`
+	Cost                float64   `db:"cost" json:"cost"`
+	LastWorkspaceUsedAt time.Time `db:"last_workspace_used_at" json:"last_workspace_used_at"`
+	Dimensions          []string  `db:"dimensions" json:"dimensions"`
+	DimensionHours      []float64 `db:"dimension_hours" json:"dimension_hours"`
+}
+
+// GetUserWorkspaceUsage returns, for every user who owned a running workspace
+// between start and end time, the number of their workspaces that ran, the
+// hours those workspaces ran for and the quota credits they accrued. A
+// workspace runs from the completion of a successful start build until its
+// next build, and accrues the daily cost of the start build prorated over that
+// time. The hours of the quota dimensions are the costs of the start build in
+// each dimension multiplied by the hours the workspaces ran, e.g. CPU hours.
+// Keep the CTEs in sync with GetGroupWorkspaceUsage.
+func (q *sqlQuerier) GetUserWorkspaceUsage(ctx context.Context, arg GetUserWorkspaceUsageParams) ([]GetUserWorkspaceUsageRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserWorkspaceUsage, arg.EndTime, arg.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserWorkspaceUsageRow
+	for rows.Next() {
+		var i GetUserWorkspaceUsageRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.Name,
+			&i.Email,
+			&i.LastSeenAt,
+			&i.Workspaces,
+			&i.RuntimeHours,
+			&i.Cost,
+			&i.LastWorkspaceUsedAt,
+			pq.Array(&i.Dimensions),
+			pq.Array(&i.DimensionHours),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertTemplateUsageStats = `-- name: UpsertTemplateUsageStats :exec
+WITH
+	latest_start AS (
+		SELECT
+			-- Truncate to hour so that we always look at even ranges of data.
+			date_trunc('hour', COALESCE(
+				MAX(start_time) - '1 hour'::interval,
+				-- Fallback when there are no template usage stats yet.
+				-- App stats can exist before this, but not agent stats,
+				-- limit the lookback to avoid inconsistency.
+				(SELECT MIN(created_at) FROM workspace_agent_stats)
+			)) AS t
+		FROM
+			template_usage_stats
+	),
+	workspace_app_stat_buckets AS (
+		SELECT
+			-- Truncate the minute to the nearest half hour, this is the bucket size
+			-- for the data.
+			date_trunc('hour', s.minute_bucket) + trunc(date_part('minute', s.minute_bucket) / 30) * 30 * '1 minute'::interval AS time_bucket,
 			w.template_id,
 			was.user_id,
 			-- Both app stats and agent stats track web terminal usage, but
@@ -3684,6 +5304,79 @@ func (q *sqlQuerier) UpsertTemplateUsageStats(ctx context.Context) error {
 	return err
 }
 
+const getLDAPUsers = `-- name: GetLDAPUsers :many
+SELECT
+	user_id, external_id, dn, created_at, updated_at
+FROM
+	ldap_users
+`
+
+func (q *sqlQuerier) GetLDAPUsers(ctx context.Context) ([]LDAPUser, error) {
+	rows, err := q.db.QueryContext(ctx, getLDAPUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LDAPUser
+	for rows.Next() {
+		var i LDAPUser
+		if err := rows.Scan(
+			&i.UserID,
+			&i.ExternalID,
+			&i.Dn,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertLDAPUser = `-- name: UpsertLDAPUser :one
+INSERT INTO
+	ldap_users (user_id, external_id, dn, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $4)
+ON CONFLICT (user_id) DO UPDATE SET
+	external_id = $2,
+	dn = $3,
+	updated_at = $4
+RETURNING user_id, external_id, dn, created_at, updated_at
+`
+
+type UpsertLDAPUserParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	ExternalID string    `db:"external_id" json:"external_id"`
+	Dn         string    `db:"dn" json:"dn"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertLDAPUser(ctx context.Context, arg UpsertLDAPUserParams) (LDAPUser, error) {
+	row := q.db.QueryRowContext(ctx, upsertLDAPUser,
+		arg.UserID,
+		arg.ExternalID,
+		arg.Dn,
+		arg.UpdatedAt,
+	)
+	var i LDAPUser
+	err := row.Scan(
+		&i.UserID,
+		&i.ExternalID,
+		&i.Dn,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const deleteLicense = `-- name: DeleteLicense :one
 DELETE
 FROM licenses
@@ -3697,6 +5390,18 @@ func (q *sqlQuerier) DeleteLicense(ctx context.Context, id int32) (int32, error)
 	return id, err
 }
 
+const deleteLicenseSeatReservation = `-- name: DeleteLicenseSeatReservation :exec
+DELETE FROM
+	license_seat_reservations
+WHERE
+	group_id = $1
+`
+
+func (q *sqlQuerier) DeleteLicenseSeatReservation(ctx context.Context, groupID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteLicenseSeatReservation, groupID)
+	return err
+}
+
 const getLicenseByID = `-- name: GetLicenseByID :one
 SELECT
 	id, uploaded_at, jwt, exp, uuid
@@ -3721,6 +5426,72 @@ func (q *sqlQuerier) GetLicenseByID(ctx context.Context, id int32) (License, err
 	return i, err
 }
 
+const getLicenseSeatReservations = `-- name: GetLicenseSeatReservations :many
+SELECT
+	license_seat_reservations.group_id, license_seat_reservations.seats, license_seat_reservations.created_at, license_seat_reservations.updated_at,
+	groups.name AS group_name,
+	groups.organization_id,
+	(
+		SELECT
+			COUNT(*)
+		FROM
+			group_members_expanded
+		WHERE
+			group_members_expanded.group_id = license_seat_reservations.group_id AND
+			group_members_expanded.user_status = 'active'::user_status AND
+			NOT group_members_expanded.user_is_system
+	)::bigint AS active_members
+FROM
+	license_seat_reservations
+INNER JOIN groups ON
+	groups.id = license_seat_reservations.group_id
+ORDER BY
+	groups.name
+`
+
+type GetLicenseSeatReservationsRow struct {
+	GroupID        uuid.UUID `db:"group_id" json:"group_id"`
+	Seats          int32     `db:"seats" json:"seats"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+	GroupName      string    `db:"group_name" json:"group_name"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	ActiveMembers  int64     `db:"active_members" json:"active_members"`
+}
+
+// Returns the seat reservations with the number of active members of each
+// group, which occupy the reserved seats first.
+func (q *sqlQuerier) GetLicenseSeatReservations(ctx context.Context) ([]GetLicenseSeatReservationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getLicenseSeatReservations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLicenseSeatReservationsRow
+	for rows.Next() {
+		var i GetLicenseSeatReservationsRow
+		if err := rows.Scan(
+			&i.GroupID,
+			&i.Seats,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.GroupName,
+			&i.OrganizationID,
+			&i.ActiveMembers,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLicenses = `-- name: GetLicenses :many
 SELECT id, uploaded_at, jwt, exp, uuid
 FROM licenses
@@ -3829,6 +5600,41 @@ func (q *sqlQuerier) InsertLicense(ctx context.Context, arg InsertLicenseParams)
 	return i, err
 }
 
+const upsertLicenseSeatReservation = `-- name: UpsertLicenseSeatReservation :one
+INSERT INTO
+	license_seat_reservations (group_id, seats, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (group_id) DO UPDATE SET
+	seats = EXCLUDED.seats,
+	updated_at = EXCLUDED.updated_at
+RETURNING group_id, seats, created_at, updated_at
+`
+
+type UpsertLicenseSeatReservationParams struct {
+	GroupID   uuid.UUID `db:"group_id" json:"group_id"`
+	Seats     int32     `db:"seats" json:"seats"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertLicenseSeatReservation(ctx context.Context, arg UpsertLicenseSeatReservationParams) (LicenseSeatReservation, error) {
+	row := q.db.QueryRowContext(ctx, upsertLicenseSeatReservation,
+		arg.GroupID,
+		arg.Seats,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i LicenseSeatReservation
+	err := row.Scan(
+		&i.GroupID,
+		&i.Seats,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const acquireLock = `-- name: AcquireLock :exec
 SELECT pg_advisory_xact_lock($1)
 `
@@ -3857,78 +5663,290 @@ func (q *sqlQuerier) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock i
 	return pg_try_advisory_xact_lock, err
 }
 
-const acquireNotificationMessages = `-- name: AcquireNotificationMessages :many
-WITH acquired AS (
-    UPDATE
-        notification_messages
-            SET queued_seconds = GREATEST(0, EXTRACT(EPOCH FROM (NOW() - updated_at)))::FLOAT,
-                updated_at = NOW(),
-                status = 'leased'::notification_message_status,
-                status_reason = 'Leased by notifier ' || $1::uuid,
-                leased_until = NOW() + CONCAT($2::int, ' seconds')::interval
-            WHERE id IN (SELECT nm.id
-                         FROM notification_messages AS nm
-                         WHERE (
-                             (
-                                 -- message is in acquirable states
-                                 nm.status IN (
-                                               'pending'::notification_message_status,
-                                               'temporary_failure'::notification_message_status
-                                     )
-                                 )
-                                 -- or somehow the message was left in leased for longer than its lease period
-                                 OR (
-                                 nm.status = 'leased'::notification_message_status
-                                     AND nm.leased_until < NOW()
-                                 )
-                             )
-                           AND (
-                             -- exclude all messages which have exceeded the max attempts; these will be purged later
-                             nm.attempt_count IS NULL OR nm.attempt_count < $3::int
-                             )
-                           -- if set, do not retry until we've exceeded the wait time
-                           AND (
-                             CASE
-                                 WHEN nm.next_retry_after IS NOT NULL THEN nm.next_retry_after < NOW()
-                                 ELSE true
-                                 END
-                             )
-                         ORDER BY nm.created_at ASC
-                                  -- Ensure that multiple concurrent readers cannot retrieve the same rows
-                             FOR UPDATE OF nm
-                                 SKIP LOCKED
-                         LIMIT $4)
-            RETURNING id, notification_template_id, user_id, method, status, status_reason, created_by, payload, attempt_count, targets, created_at, updated_at, leased_until, next_retry_after, queued_seconds, dedupe_hash)
-SELECT
-    -- message
-    nm.id,
-    nm.payload,
-    nm.method,
-    nm.attempt_count::int                                                 AS attempt_count,
-    nm.queued_seconds::float                                              AS queued_seconds,
-    -- template
-    nt.id                                                                 AS template_id,
-    nt.title_template,
-    nt.body_template,
-    -- preferences
-    (CASE WHEN np.disabled IS NULL THEN false ELSE np.disabled END)::bool AS disabled
-FROM acquired nm
-         JOIN notification_templates nt ON nm.notification_template_id = nt.id
-         LEFT JOIN notification_preferences AS np
-                   ON (np.user_id = nm.user_id AND np.notification_template_id = nm.notification_template_id)
+const deleteNetworkConnectionQuality = `-- name: DeleteNetworkConnectionQuality :exec
+DELETE FROM
+	network_connection_quality
+WHERE
+	client_type = $1
+	AND node_id_self = $2
+	AND node_id_remote = $3
 `
 
-type AcquireNotificationMessagesParams struct {
-	NotifierID      uuid.UUID `db:"notifier_id" json:"notifier_id"`
-	LeaseSeconds    int32     `db:"lease_seconds" json:"lease_seconds"`
-	MaxAttemptCount int32     `db:"max_attempt_count" json:"max_attempt_count"`
-	Count           int32     `db:"count" json:"count"`
+type DeleteNetworkConnectionQualityParams struct {
+	ClientType   string `db:"client_type" json:"client_type"`
+	NodeIDSelf   int64  `db:"node_id_self" json:"node_id_self"`
+	NodeIDRemote int64  `db:"node_id_remote" json:"node_id_remote"`
 }
 
-type AcquireNotificationMessagesRow struct {
-	ID            uuid.UUID          `db:"id" json:"id"`
-	Payload       json.RawMessage    `db:"payload" json:"payload"`
-	Method        NotificationMethod `db:"method" json:"method"`
+func (q *sqlQuerier) DeleteNetworkConnectionQuality(ctx context.Context, arg DeleteNetworkConnectionQualityParams) error {
+	_, err := q.db.ExecContext(ctx, deleteNetworkConnectionQuality, arg.ClientType, arg.NodeIDSelf, arg.NodeIDRemote)
+	return err
+}
+
+const deleteOldNetworkConnectionQuality = `-- name: DeleteOldNetworkConnectionQuality :exec
+DELETE FROM
+	network_connection_quality
+WHERE
+	updated_at < $1::timestamptz
+`
+
+// Connections send a disconnect event when they close cleanly, this removes
+// the ones that vanished without one.
+func (q *sqlQuerier) DeleteOldNetworkConnectionQuality(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldNetworkConnectionQuality, beforeTime)
+	return err
+}
+
+const getNetworkConnectionQuality = `-- name: GetNetworkConnectionQuality :one
+SELECT
+	client_type, node_id_self, node_id_remote, connection_type, latency_ms, handshake_retries, updated_at
+FROM
+	network_connection_quality
+WHERE
+	client_type = $1
+	AND node_id_self = $2
+	AND node_id_remote = $3
+FOR UPDATE
+`
+
+type GetNetworkConnectionQualityParams struct {
+	ClientType   string `db:"client_type" json:"client_type"`
+	NodeIDSelf   int64  `db:"node_id_self" json:"node_id_self"`
+	NodeIDRemote int64  `db:"node_id_remote" json:"node_id_remote"`
+}
+
+func (q *sqlQuerier) GetNetworkConnectionQuality(ctx context.Context, arg GetNetworkConnectionQualityParams) (NetworkConnectionQuality, error) {
+	row := q.db.QueryRowContext(ctx, getNetworkConnectionQuality, arg.ClientType, arg.NodeIDSelf, arg.NodeIDRemote)
+	var i NetworkConnectionQuality
+	err := row.Scan(
+		&i.ClientType,
+		&i.NodeIDSelf,
+		&i.NodeIDRemote,
+		&i.ConnectionType,
+		&i.LatencyMS,
+		&i.HandshakeRetries,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getNetworkConnectionQualityCounters = `-- name: GetNetworkConnectionQualityCounters :many
+SELECT
+	client_type, relay_fallbacks, handshake_retries
+FROM
+	network_connection_quality_counters
+ORDER BY
+	client_type
+`
+
+func (q *sqlQuerier) GetNetworkConnectionQualityCounters(ctx context.Context) ([]NetworkConnectionQualityCounter, error) {
+	rows, err := q.db.QueryContext(ctx, getNetworkConnectionQualityCounters)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NetworkConnectionQualityCounter
+	for rows.Next() {
+		var i NetworkConnectionQualityCounter
+		if err := rows.Scan(&i.ClientType, &i.RelayFallbacks, &i.HandshakeRetries); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNetworkConnectionQualitySummary = `-- name: GetNetworkConnectionQualitySummary :many
+SELECT
+	client_type,
+	connection_type,
+	COUNT(*)::bigint AS connections,
+	COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY latency_ms), 0)::double precision AS latency_median_ms
+FROM
+	network_connection_quality
+WHERE
+	updated_at >= $1::timestamptz
+GROUP BY
+	client_type, connection_type
+ORDER BY
+	client_type, connection_type
+`
+
+type GetNetworkConnectionQualitySummaryRow struct {
+	ClientType      string  `db:"client_type" json:"client_type"`
+	ConnectionType  string  `db:"connection_type" json:"connection_type"`
+	Connections     int64   `db:"connections" json:"connections"`
+	LatencyMedianMS float64 `db:"latency_median_ms" json:"latency_median_ms"`
+}
+
+// Groups the connections updated since @updated_after by client and connection
+// type, with the median latency of each group.
+func (q *sqlQuerier) GetNetworkConnectionQualitySummary(ctx context.Context, updatedAfter time.Time) ([]GetNetworkConnectionQualitySummaryRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNetworkConnectionQualitySummary, updatedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNetworkConnectionQualitySummaryRow
+	for rows.Next() {
+		var i GetNetworkConnectionQualitySummaryRow
+		if err := rows.Scan(
+			&i.ClientType,
+			&i.ConnectionType,
+			&i.Connections,
+			&i.LatencyMedianMS,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const incrementNetworkConnectionQualityCounters = `-- name: IncrementNetworkConnectionQualityCounters :exec
+INSERT INTO
+	network_connection_quality_counters (client_type, relay_fallbacks, handshake_retries)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (client_type) DO UPDATE SET
+	relay_fallbacks = network_connection_quality_counters.relay_fallbacks + EXCLUDED.relay_fallbacks,
+	handshake_retries = network_connection_quality_counters.handshake_retries + EXCLUDED.handshake_retries
+`
+
+type IncrementNetworkConnectionQualityCountersParams struct {
+	ClientType       string `db:"client_type" json:"client_type"`
+	RelayFallbacks   int64  `db:"relay_fallbacks" json:"relay_fallbacks"`
+	HandshakeRetries int64  `db:"handshake_retries" json:"handshake_retries"`
+}
+
+func (q *sqlQuerier) IncrementNetworkConnectionQualityCounters(ctx context.Context, arg IncrementNetworkConnectionQualityCountersParams) error {
+	_, err := q.db.ExecContext(ctx, incrementNetworkConnectionQualityCounters, arg.ClientType, arg.RelayFallbacks, arg.HandshakeRetries)
+	return err
+}
+
+const upsertNetworkConnectionQuality = `-- name: UpsertNetworkConnectionQuality :exec
+INSERT INTO
+	network_connection_quality (client_type, node_id_self, node_id_remote, connection_type, latency_ms, handshake_retries, updated_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (client_type, node_id_self, node_id_remote) DO UPDATE SET
+	connection_type = EXCLUDED.connection_type,
+	latency_ms = EXCLUDED.latency_ms,
+	handshake_retries = EXCLUDED.handshake_retries,
+	updated_at = EXCLUDED.updated_at
+`
+
+type UpsertNetworkConnectionQualityParams struct {
+	ClientType       string          `db:"client_type" json:"client_type"`
+	NodeIDSelf       int64           `db:"node_id_self" json:"node_id_self"`
+	NodeIDRemote     int64           `db:"node_id_remote" json:"node_id_remote"`
+	ConnectionType   string          `db:"connection_type" json:"connection_type"`
+	LatencyMS        sql.NullFloat64 `db:"latency_ms" json:"latency_ms"`
+	HandshakeRetries int64           `db:"handshake_retries" json:"handshake_retries"`
+	UpdatedAt        time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertNetworkConnectionQuality(ctx context.Context, arg UpsertNetworkConnectionQualityParams) error {
+	_, err := q.db.ExecContext(ctx, upsertNetworkConnectionQuality,
+		arg.ClientType,
+		arg.NodeIDSelf,
+		arg.NodeIDRemote,
+		arg.ConnectionType,
+		arg.LatencyMS,
+		arg.HandshakeRetries,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const acquireNotificationMessages = `-- name: AcquireNotificationMessages :many
+WITH acquired AS (
+    UPDATE
+        notification_messages
+            SET queued_seconds = GREATEST(0, EXTRACT(EPOCH FROM (NOW() - updated_at)))::FLOAT,
+                updated_at = NOW(),
+                status = 'leased'::notification_message_status,
+                status_reason = 'Leased by notifier ' || $1::uuid,
+                leased_until = NOW() + CONCAT($2::int, ' seconds')::interval
+            WHERE id IN (SELECT nm.id
+                         FROM notification_messages AS nm
+                         WHERE (
+                             (
+                                 -- message is in acquirable states
+                                 nm.status IN (
+                                               'pending'::notification_message_status,
+                                               'temporary_failure'::notification_message_status
+                                     )
+                                 )
+                                 -- or somehow the message was left in leased for longer than its lease period
+                                 OR (
+                                 nm.status = 'leased'::notification_message_status
+                                     AND nm.leased_until < NOW()
+                                 )
+                             )
+                           AND (
+                             -- exclude all messages which have exceeded the max attempts; these will be purged later
+                             nm.attempt_count IS NULL OR nm.attempt_count < $3::int
+                             )
+                           -- if set, do not retry until we've exceeded the wait time
+                           AND (
+                             CASE
+                                 WHEN nm.next_retry_after IS NOT NULL THEN nm.next_retry_after < NOW()
+                                 ELSE true
+                                 END
+                             )
+                           -- if set, do not dispatch until the message is no longer held back (e.g. during quiet hours)
+                           AND (nm.deliver_after IS NULL OR nm.deliver_after <= NOW())
+                           -- messages batched into a digest are never dispatched on their own
+                           AND NOT nm.digest
+                         ORDER BY nm.created_at ASC
+                                  -- Ensure that multiple concurrent readers cannot retrieve the same rows
+                             FOR UPDATE OF nm
+                                 SKIP LOCKED
+                         LIMIT $4)
+            RETURNING id, notification_template_id, user_id, method, status, status_reason, created_by, payload, attempt_count, targets, created_at, updated_at, leased_until, next_retry_after, queued_seconds, dedupe_hash, deliver_after, digest)
+SELECT
+    -- message
+    nm.id,
+    nm.payload,
+    nm.method,
+    nm.attempt_count::int                                                 AS attempt_count,
+    nm.queued_seconds::float                                              AS queued_seconds,
+    -- template
+    nt.id                                                                 AS template_id,
+    nt.title_template,
+    nt.body_template,
+    -- preferences
+    (CASE WHEN np.disabled IS NULL THEN false ELSE np.disabled END)::bool AS disabled
+FROM acquired nm
+         JOIN notification_templates nt ON nm.notification_template_id = nt.id
+         LEFT JOIN notification_preferences AS np
+                   ON (np.user_id = nm.user_id AND np.notification_template_id = nm.notification_template_id)
+`
+
+type AcquireNotificationMessagesParams struct {
+	NotifierID      uuid.UUID `db:"notifier_id" json:"notifier_id"`
+	LeaseSeconds    int32     `db:"lease_seconds" json:"lease_seconds"`
+	MaxAttemptCount int32     `db:"max_attempt_count" json:"max_attempt_count"`
+	Count           int32     `db:"count" json:"count"`
+}
+
+type AcquireNotificationMessagesRow struct {
+	ID            uuid.UUID          `db:"id" json:"id"`
+	Payload       json.RawMessage    `db:"payload" json:"payload"`
+	Method        NotificationMethod `db:"method" json:"method"`
 	AttemptCount  int32              `db:"attempt_count" json:"attempt_count"`
 	QueuedSeconds float64            `db:"queued_seconds" json:"queued_seconds"`
 	TemplateID    uuid.UUID          `db:"template_id" json:"template_id"`
@@ -4111,7 +6129,7 @@ func (q *sqlQuerier) DeleteWebpushSubscriptions(ctx context.Context, ids []uuid.
 }
 
 const enqueueNotificationMessage = `-- name: EnqueueNotificationMessage :exec
-INSERT INTO notification_messages (id, notification_template_id, user_id, method, payload, targets, created_by, created_at)
+INSERT INTO notification_messages (id, notification_template_id, user_id, method, payload, targets, created_by, created_at, deliver_after, digest)
 VALUES ($1,
         $2,
         $3,
@@ -4119,7 +6137,9 @@ VALUES ($1,
         $5::jsonb,
         $6,
         $7,
-        $8)
+        $8,
+        $9,
+        $10)
 `
 
 type EnqueueNotificationMessageParams struct {
@@ -4131,6 +6151,8 @@ type EnqueueNotificationMessageParams struct {
 	Targets                []uuid.UUID        `db:"targets" json:"targets"`
 	CreatedBy              string             `db:"created_by" json:"created_by"`
 	CreatedAt              time.Time          `db:"created_at" json:"created_at"`
+	DeliverAfter           sql.NullTime       `db:"deliver_after" json:"deliver_after"`
+	Digest                 bool               `db:"digest" json:"digest"`
 }
 
 func (q *sqlQuerier) EnqueueNotificationMessage(ctx context.Context, arg EnqueueNotificationMessageParams) error {
@@ -4143,6 +6165,8 @@ func (q *sqlQuerier) EnqueueNotificationMessage(ctx context.Context, arg Enqueue
 		pq.Array(arg.Targets),
 		arg.CreatedBy,
 		arg.CreatedAt,
+		arg.DeliverAfter,
+		arg.Digest,
 	)
 	return err
 }
@@ -4155,7 +6179,18 @@ SELECT nt.name                                                    AS notificatio
        u.id                                                       AS user_id,
        u.email                                                    AS user_email,
        COALESCE(NULLIF(u.name, ''), NULLIF(u.username, ''))::text AS user_name,
-       u.username                                                 AS user_username
+       u.username                                                 AS user_username,
+       nt.priority                                                AS priority,
+       EXISTS (SELECT 1
+               FROM user_configs uc
+               WHERE uc.user_id = u.id
+                 AND uc.key = 'notification_digest'
+                 AND uc.value = 'true')::bool                     AS digest_enabled,
+       ARRAY(SELECT ncp.method
+             FROM notification_category_preferences ncp
+             WHERE ncp.user_id = u.id
+               AND ncp.category = nt.category
+               AND ncp.disabled)::notification_method[]           AS disabled_methods
 FROM notification_templates nt,
      users u
 WHERE nt.id = $1
@@ -4176,6 +6211,9 @@ type FetchNewMessageMetadataRow struct {
 	UserEmail              string                 `db:"user_email" json:"user_email"`
 	UserName               string                 `db:"user_name" json:"user_name"`
 	UserUsername           string                 `db:"user_username" json:"user_username"`
+	Priority               NotificationPriority   `db:"priority" json:"priority"`
+	DigestEnabled          bool                   `db:"digest_enabled" json:"digest_enabled"`
+	DisabledMethods        []NotificationMethod   `db:"disabled_methods" json:"disabled_methods"`
 }
 
 // This is used to build up the notification_message's JSON payload.
@@ -4191,12 +6229,68 @@ func (q *sqlQuerier) FetchNewMessageMetadata(ctx context.Context, arg FetchNewMe
 		&i.UserEmail,
 		&i.UserName,
 		&i.UserUsername,
+		&i.Priority,
+		&i.DigestEnabled,
+		pq.Array(&i.DisabledMethods),
 	)
 	return i, err
 }
 
+const getDueNotificationDigestMessages = `-- name: GetDueNotificationDigestMessages :many
+SELECT nm.id,
+       nm.user_id,
+       nm.payload,
+       nm.created_at,
+       nt.title_template
+FROM notification_messages nm
+         JOIN notification_templates nt ON nm.notification_template_id = nt.id
+WHERE nm.digest
+  AND nm.status = 'pending'::notification_message_status
+  AND nm.deliver_after <= $1::timestamptz
+ORDER BY nm.user_id, nm.created_at ASC
+`
+
+type GetDueNotificationDigestMessagesRow struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	UserID        uuid.UUID       `db:"user_id" json:"user_id"`
+	Payload       json.RawMessage `db:"payload" json:"payload"`
+	CreatedAt     time.Time       `db:"created_at" json:"created_at"`
+	TitleTemplate string          `db:"title_template" json:"title_template"`
+}
+
+// Fetches the pending messages which are due to be batched into their user's
+// notification digest, ordered by user.
+func (q *sqlQuerier) GetDueNotificationDigestMessages(ctx context.Context, now time.Time) ([]GetDueNotificationDigestMessagesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDueNotificationDigestMessages, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDueNotificationDigestMessagesRow
+	for rows.Next() {
+		var i GetDueNotificationDigestMessagesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.TitleTemplate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNotificationMessagesByStatus = `-- name: GetNotificationMessagesByStatus :many
-SELECT id, notification_template_id, user_id, method, status, status_reason, created_by, payload, attempt_count, targets, created_at, updated_at, leased_until, next_retry_after, queued_seconds, dedupe_hash
+SELECT id, notification_template_id, user_id, method, status, status_reason, created_by, payload, attempt_count, targets, created_at, updated_at, leased_until, next_retry_after, queued_seconds, dedupe_hash, deliver_after, digest
 FROM notification_messages
 WHERE status = $1
 LIMIT $2::int
@@ -4233,6 +6327,8 @@ func (q *sqlQuerier) GetNotificationMessagesByStatus(ctx context.Context, arg Ge
 			&i.NextRetryAfter,
 			&i.QueuedSeconds,
 			&i.DedupeHash,
+			&i.DeliverAfter,
+			&i.Digest,
 		); err != nil {
 			return nil, err
 		}
@@ -4265,7 +6361,7 @@ func (q *sqlQuerier) GetNotificationReportGeneratorLogByTemplate(ctx context.Con
 }
 
 const getNotificationTemplateByID = `-- name: GetNotificationTemplateByID :one
-SELECT id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default
+SELECT id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default, priority, category
 FROM notification_templates
 WHERE id = $1::uuid
 `
@@ -4283,12 +6379,14 @@ func (q *sqlQuerier) GetNotificationTemplateByID(ctx context.Context, id uuid.UU
 		&i.Method,
 		&i.Kind,
 		&i.EnabledByDefault,
+		&i.Priority,
+		&i.Category,
 	)
 	return i, err
 }
 
 const getNotificationTemplatesByKind = `-- name: GetNotificationTemplatesByKind :many
-SELECT id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default
+SELECT id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default, priority, category
 FROM notification_templates
 WHERE kind = $1::notification_template_kind
 ORDER BY name ASC
@@ -4313,6 +6411,45 @@ func (q *sqlQuerier) GetNotificationTemplatesByKind(ctx context.Context, kind No
 			&i.Method,
 			&i.Kind,
 			&i.EnabledByDefault,
+			&i.Priority,
+			&i.Category,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserNotificationCategoryPreferences = `-- name: GetUserNotificationCategoryPreferences :many
+SELECT user_id, category, method, disabled, created_at, updated_at
+FROM notification_category_preferences
+WHERE user_id = $1::uuid
+ORDER BY category, method
+`
+
+func (q *sqlQuerier) GetUserNotificationCategoryPreferences(ctx context.Context, userID uuid.UUID) ([]NotificationCategoryPreference, error) {
+	rows, err := q.db.QueryContext(ctx, getUserNotificationCategoryPreferences, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationCategoryPreference
+	for rows.Next() {
+		var i NotificationCategoryPreference
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Category,
+			&i.Method,
+			&i.Disabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -4436,7 +6573,7 @@ const updateNotificationTemplateMethodByID = `-- name: UpdateNotificationTemplat
 UPDATE notification_templates
 SET method = $1::notification_method
 WHERE id = $2::uuid
-RETURNING id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default
+RETURNING id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default, priority, category
 `
 
 type UpdateNotificationTemplateMethodByIDParams struct {
@@ -4457,10 +6594,44 @@ func (q *sqlQuerier) UpdateNotificationTemplateMethodByID(ctx context.Context, a
 		&i.Method,
 		&i.Kind,
 		&i.EnabledByDefault,
+		&i.Priority,
+		&i.Category,
 	)
 	return i, err
 }
 
+const updateUserNotificationCategoryPreferences = `-- name: UpdateUserNotificationCategoryPreferences :execrows
+INSERT
+INTO notification_category_preferences (user_id, category, method, disabled)
+SELECT $1::uuid, new_values.category, new_values.method, new_values.disabled
+FROM (SELECT UNNEST($2::notification_category[]) AS category,
+             UNNEST($3::notification_method[])      AS method,
+             UNNEST($4::bool[])                   AS disabled) AS new_values
+ON CONFLICT (user_id, category, method) DO UPDATE
+    SET disabled   = EXCLUDED.disabled,
+        updated_at = CURRENT_TIMESTAMP
+`
+
+type UpdateUserNotificationCategoryPreferencesParams struct {
+	UserID     uuid.UUID              `db:"user_id" json:"user_id"`
+	Categories []NotificationCategory `db:"categories" json:"categories"`
+	Methods    []NotificationMethod   `db:"methods" json:"methods"`
+	Disableds  []bool                 `db:"disableds" json:"disableds"`
+}
+
+func (q *sqlQuerier) UpdateUserNotificationCategoryPreferences(ctx context.Context, arg UpdateUserNotificationCategoryPreferencesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserNotificationCategoryPreferences,
+		arg.UserID,
+		pq.Array(arg.Categories),
+		pq.Array(arg.Methods),
+		pq.Array(arg.Disableds),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateUserNotificationPreferences = `-- name: UpdateUserNotificationPreferences :execrows
 INSERT
 INTO notification_preferences (user_id, notification_template_id, disabled)
@@ -4503,11 +6674,58 @@ func (q *sqlQuerier) UpsertNotificationReportGeneratorLog(ctx context.Context, a
 	return err
 }
 
-const countUnreadInboxNotificationsByUserID = `-- name: CountUnreadInboxNotificationsByUserID :one
-SELECT COUNT(*) FROM inbox_notifications WHERE user_id = $1 AND read_at IS NULL
+const countUnreadInboxNotificationsByCategory = `-- name: CountUnreadInboxNotificationsByCategory :many
+SELECT
+	notification_templates.category,
+	COUNT(*) AS unread_count
+FROM
+	inbox_notifications
+	LEFT JOIN notification_templates ON notification_templates.id = inbox_notifications.template_id
+WHERE
+	inbox_notifications.user_id = $1 AND
+	inbox_notifications.read_at IS NULL
+GROUP BY
+	notification_templates.category
+ORDER BY
+	notification_templates.category
 `
 
-func (q *sqlQuerier) CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+type CountUnreadInboxNotificationsByCategoryRow struct {
+	Category    NullNotificationCategory `db:"category" json:"category"`
+	UnreadCount int64                    `db:"unread_count" json:"unread_count"`
+}
+
+// Counts the unread inbox notifications of a user by the category of their
+// template. Notifications whose template has no category are counted with a
+// NULL category.
+func (q *sqlQuerier) CountUnreadInboxNotificationsByCategory(ctx context.Context, userID uuid.UUID) ([]CountUnreadInboxNotificationsByCategoryRow, error) {
+	rows, err := q.db.QueryContext(ctx, countUnreadInboxNotificationsByCategory, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountUnreadInboxNotificationsByCategoryRow
+	for rows.Next() {
+		var i CountUnreadInboxNotificationsByCategoryRow
+		if err := rows.Scan(&i.Category, &i.UnreadCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countUnreadInboxNotificationsByUserID = `-- name: CountUnreadInboxNotificationsByUserID :one
+SELECT COUNT(*) FROM inbox_notifications WHERE user_id = $1 AND read_at IS NULL
+`
+
+func (q *sqlQuerier) CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
 	row := q.db.QueryRowContext(ctx, countUnreadInboxNotificationsByUserID, userID)
 	var count int64
 	err := row.Scan(&count)
@@ -4519,16 +6737,18 @@ SELECT id, user_id, template_id, targets, title, content, icon, actions, read_at
 	user_id = $1 AND
 	($2::UUID[] IS NULL OR template_id = ANY($2::UUID[])) AND
 	($3::UUID[] IS NULL OR targets @> $3::UUID[]) AND
-	($4::inbox_notification_read_status = 'all' OR ($4::inbox_notification_read_status = 'unread' AND read_at IS NULL) OR ($4::inbox_notification_read_status = 'read' AND read_at IS NOT NULL)) AND
-	($5::TIMESTAMPTZ = '0001-01-01 00:00:00Z' OR created_at < $5::TIMESTAMPTZ)
+	($4::notification_category[] IS NULL OR template_id IN (SELECT id FROM notification_templates WHERE category = ANY($4::notification_category[]))) AND
+	($5::inbox_notification_read_status = 'all' OR ($5::inbox_notification_read_status = 'unread' AND read_at IS NULL) OR ($5::inbox_notification_read_status = 'read' AND read_at IS NOT NULL)) AND
+	($6::TIMESTAMPTZ = '0001-01-01 00:00:00Z' OR created_at < $6::TIMESTAMPTZ)
 	ORDER BY created_at DESC
-	LIMIT (COALESCE(NULLIF($6 :: INT, 0), 25))
+	LIMIT (COALESCE(NULLIF($7 :: INT, 0), 25))
 `
 
 type GetFilteredInboxNotificationsByUserIDParams struct {
 	UserID       uuid.UUID                   `db:"user_id" json:"user_id"`
 	Templates    []uuid.UUID                 `db:"templates" json:"templates"`
 	Targets      []uuid.UUID                 `db:"targets" json:"targets"`
+	Categories   []NotificationCategory      `db:"categories" json:"categories"`
 	ReadStatus   InboxNotificationReadStatus `db:"read_status" json:"read_status"`
 	CreatedAtOpt time.Time                   `db:"created_at_opt" json:"created_at_opt"`
 	LimitOpt     int32                       `db:"limit_opt" json:"limit_opt"`
@@ -4538,6 +6758,7 @@ type GetFilteredInboxNotificationsByUserIDParams struct {
 // param user_id: The user ID
 // param templates: The template IDs to filter by - the template_id = ANY(@templates::UUID[]) condition checks if the template_id is in the @templates array
 // param targets: The target IDs to filter by - the targets @> COALESCE(@targets, ARRAY[]::UUID[]) condition checks if the targets array (from the DB) contains all the elements in the @targets array
+// param categories: The categories to filter by - only notifications whose template belongs to one of the categories are returned
 // param read_status: The read status to filter by - can be any of 'ALL', 'UNREAD', 'READ'
 // param created_at_opt: The created_at timestamp to filter by. This parameter is usd for pagination - it fetches notifications created before the specified timestamp if it is not the zero value
 // param limit_opt: The limit of notifications to fetch. If the limit is not specified, it defaults to 25
@@ -4546,6 +6767,7 @@ func (q *sqlQuerier) GetFilteredInboxNotificationsByUserID(ctx context.Context,
 		arg.UserID,
 		pq.Array(arg.Templates),
 		pq.Array(arg.Targets),
+		pq.Array(arg.Categories),
 		arg.ReadStatus,
 		arg.CreatedAtOpt,
 		arg.LimitOpt,
@@ -4759,6 +6981,42 @@ func (q *sqlQuerier) UpdateInboxNotificationReadStatus(ctx context.Context, arg
 	return err
 }
 
+const updateInboxNotificationsReadStatus = `-- name: UpdateInboxNotificationsReadStatus :execrows
+UPDATE
+	inbox_notifications
+SET
+	read_at = $1::timestamptz
+WHERE
+	user_id = $2 AND
+	($3::UUID[] IS NULL OR id = ANY($3::UUID[])) AND
+	($4::notification_category[] IS NULL OR template_id IN (SELECT id FROM notification_templates WHERE category = ANY($4::notification_category[]))) AND
+	(read_at IS NULL) = ($1::timestamptz IS NOT NULL)
+`
+
+type UpdateInboxNotificationsReadStatusParams struct {
+	ReadAt     sql.NullTime           `db:"read_at" json:"read_at"`
+	UserID     uuid.UUID              `db:"user_id" json:"user_id"`
+	IDs        []uuid.UUID            `db:"ids" json:"ids"`
+	Categories []NotificationCategory `db:"categories" json:"categories"`
+}
+
+// Marks the inbox notifications of a user as read, or unread if read_at is
+// NULL. Notifications can be selected by ID, by the category of their
+// template, or both. Notifications that already have the requested read
+// status are left untouched so they keep their original read_at.
+func (q *sqlQuerier) UpdateInboxNotificationsReadStatus(ctx context.Context, arg UpdateInboxNotificationsReadStatusParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateInboxNotificationsReadStatus,
+		arg.ReadAt,
+		arg.UserID,
+		pq.Array(arg.IDs),
+		pq.Array(arg.Categories),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteOAuth2ProviderAppByClientID = `-- name: DeleteOAuth2ProviderAppByClientID :exec
 DELETE FROM oauth2_provider_apps WHERE id = $1
 `
@@ -4800,6 +7058,15 @@ func (q *sqlQuerier) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Cont
 	return err
 }
 
+const deleteOAuth2ProviderAppDeviceCodeByID = `-- name: DeleteOAuth2ProviderAppDeviceCodeByID :exec
+DELETE FROM oauth2_provider_app_device_codes WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppDeviceCodeByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppDeviceCodeByID, id)
+	return err
+}
+
 const deleteOAuth2ProviderAppSecretByID = `-- name: DeleteOAuth2ProviderAppSecretByID :exec
 DELETE FROM oauth2_provider_app_secrets WHERE id = $1
 `
@@ -4990,6 +7257,50 @@ func (q *sqlQuerier) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secre
 	return i, err
 }
 
+const getOAuth2ProviderAppDeviceCodeByPrefix = `-- name: GetOAuth2ProviderAppDeviceCodeByPrefix :one
+SELECT id, created_at, expires_at, device_code_prefix, device_code_hash, user_code, app_id, user_id, resource_uri, last_polled_at FROM oauth2_provider_app_device_codes WHERE device_code_prefix = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppDeviceCodeByPrefix(ctx context.Context, deviceCodePrefix []byte) (OAuth2ProviderAppDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppDeviceCodeByPrefix, deviceCodePrefix)
+	var i OAuth2ProviderAppDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.DeviceCodePrefix,
+		&i.DeviceCodeHash,
+		&i.UserCode,
+		&i.AppID,
+		&i.UserID,
+		&i.ResourceUri,
+		&i.LastPolledAt,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppDeviceCodeByUserCode = `-- name: GetOAuth2ProviderAppDeviceCodeByUserCode :one
+SELECT id, created_at, expires_at, device_code_prefix, device_code_hash, user_code, app_id, user_id, resource_uri, last_polled_at FROM oauth2_provider_app_device_codes WHERE user_code = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppDeviceCodeByUserCode(ctx context.Context, userCode string) (OAuth2ProviderAppDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppDeviceCodeByUserCode, userCode)
+	var i OAuth2ProviderAppDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.DeviceCodePrefix,
+		&i.DeviceCodeHash,
+		&i.UserCode,
+		&i.AppID,
+		&i.UserID,
+		&i.ResourceUri,
+		&i.LastPolledAt,
+	)
+	return i, err
+}
+
 const getOAuth2ProviderAppSecretByID = `-- name: GetOAuth2ProviderAppSecretByID :one
 SELECT id, created_at, last_used_at, hashed_secret, display_secret, app_id, secret_prefix FROM oauth2_provider_app_secrets WHERE id = $1
 `
@@ -5446,6 +7757,66 @@ func (q *sqlQuerier) InsertOAuth2ProviderAppCode(ctx context.Context, arg Insert
 	return i, err
 }
 
+const insertOAuth2ProviderAppDeviceCode = `-- name: InsertOAuth2ProviderAppDeviceCode :one
+INSERT INTO oauth2_provider_app_device_codes (
+    id,
+    created_at,
+    expires_at,
+    device_code_prefix,
+    device_code_hash,
+    user_code,
+    app_id,
+    resource_uri
+) VALUES(
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+) RETURNING id, created_at, expires_at, device_code_prefix, device_code_hash, user_code, app_id, user_id, resource_uri, last_polled_at
+`
+
+type InsertOAuth2ProviderAppDeviceCodeParams struct {
+	ID               uuid.UUID      `db:"id" json:"id"`
+	CreatedAt        time.Time      `db:"created_at" json:"created_at"`
+	ExpiresAt        time.Time      `db:"expires_at" json:"expires_at"`
+	DeviceCodePrefix []byte         `db:"device_code_prefix" json:"device_code_prefix"`
+	DeviceCodeHash   []byte         `db:"device_code_hash" json:"device_code_hash"`
+	UserCode         string         `db:"user_code" json:"user_code"`
+	AppID            uuid.UUID      `db:"app_id" json:"app_id"`
+	ResourceUri      sql.NullString `db:"resource_uri" json:"resource_uri"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderAppDeviceCode(ctx context.Context, arg InsertOAuth2ProviderAppDeviceCodeParams) (OAuth2ProviderAppDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppDeviceCode,
+		arg.ID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.DeviceCodePrefix,
+		arg.DeviceCodeHash,
+		arg.UserCode,
+		arg.AppID,
+		arg.ResourceUri,
+	)
+	var i OAuth2ProviderAppDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.DeviceCodePrefix,
+		&i.DeviceCodeHash,
+		&i.UserCode,
+		&i.AppID,
+		&i.UserID,
+		&i.ResourceUri,
+		&i.LastPolledAt,
+	)
+	return i, err
+}
+
 const insertOAuth2ProviderAppSecret = `-- name: InsertOAuth2ProviderAppSecret :one
 INSERT INTO oauth2_provider_app_secrets (
     id,
@@ -5771,6 +8142,53 @@ func (q *sqlQuerier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg Update
 	return i, err
 }
 
+const updateOAuth2ProviderAppDeviceCodeLastPolledAt = `-- name: UpdateOAuth2ProviderAppDeviceCodeLastPolledAt :exec
+UPDATE oauth2_provider_app_device_codes SET
+    last_polled_at = $2
+WHERE id = $1
+`
+
+type UpdateOAuth2ProviderAppDeviceCodeLastPolledAtParams struct {
+	ID           uuid.UUID    `db:"id" json:"id"`
+	LastPolledAt sql.NullTime `db:"last_polled_at" json:"last_polled_at"`
+}
+
+func (q *sqlQuerier) UpdateOAuth2ProviderAppDeviceCodeLastPolledAt(ctx context.Context, arg UpdateOAuth2ProviderAppDeviceCodeLastPolledAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateOAuth2ProviderAppDeviceCodeLastPolledAt, arg.ID, arg.LastPolledAt)
+	return err
+}
+
+const updateOAuth2ProviderAppDeviceCodeUserID = `-- name: UpdateOAuth2ProviderAppDeviceCodeUserID :one
+UPDATE oauth2_provider_app_device_codes SET
+    user_id = $2
+WHERE id = $1 AND user_id IS NULL RETURNING id, created_at, expires_at, device_code_prefix, device_code_hash, user_code, app_id, user_id, resource_uri, last_polled_at
+`
+
+type UpdateOAuth2ProviderAppDeviceCodeUserIDParams struct {
+	ID     uuid.UUID     `db:"id" json:"id"`
+	UserID uuid.NullUUID `db:"user_id" json:"user_id"`
+}
+
+// Approves a pending device code. Returns no rows if the code was already
+// approved.
+func (q *sqlQuerier) UpdateOAuth2ProviderAppDeviceCodeUserID(ctx context.Context, arg UpdateOAuth2ProviderAppDeviceCodeUserIDParams) (OAuth2ProviderAppDeviceCode, error) {
+	row := q.db.QueryRowContext(ctx, updateOAuth2ProviderAppDeviceCodeUserID, arg.ID, arg.UserID)
+	var i OAuth2ProviderAppDeviceCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.DeviceCodePrefix,
+		&i.DeviceCodeHash,
+		&i.UserCode,
+		&i.AppID,
+		&i.UserID,
+		&i.ResourceUri,
+		&i.LastPolledAt,
+	)
+	return i, err
+}
+
 const updateOAuth2ProviderAppSecretByID = `-- name: UpdateOAuth2ProviderAppSecretByID :one
 UPDATE oauth2_provider_app_secrets SET
     last_used_at = $2
@@ -6467,6 +8885,92 @@ func (q *sqlQuerier) UpdateOrganizationDeletedByID(ctx context.Context, arg Upda
 	return err
 }
 
+const getOrganizationSettingOverrides = `-- name: GetOrganizationSettingOverrides :one
+SELECT
+	organization_id, default_ttl, default_quiet_hours_schedule, allowed_external_auth_providers, disabled_notification_template_ids, updated_at, provisioner_max_jobs, provisioner_reserved_jobs
+FROM
+	organization_setting_overrides
+WHERE
+	organization_id = $1
+`
+
+func (q *sqlQuerier) GetOrganizationSettingOverrides(ctx context.Context, organizationID uuid.UUID) (OrganizationSettingOverride, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationSettingOverrides, organizationID)
+	var i OrganizationSettingOverride
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.DefaultTTL,
+		&i.DefaultQuietHoursSchedule,
+		pq.Array(&i.AllowedExternalAuthProviders),
+		pq.Array(&i.DisabledNotificationTemplateIDs),
+		&i.UpdatedAt,
+		&i.ProvisionerMaxJobs,
+		&i.ProvisionerReservedJobs,
+	)
+	return i, err
+}
+
+const upsertOrganizationSettingOverrides = `-- name: UpsertOrganizationSettingOverrides :one
+INSERT INTO
+	organization_setting_overrides (
+		organization_id,
+		default_ttl,
+		default_quiet_hours_schedule,
+		allowed_external_auth_providers,
+		disabled_notification_template_ids,
+		provisioner_max_jobs,
+		provisioner_reserved_jobs,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (organization_id) DO UPDATE SET
+	default_ttl = $2,
+	default_quiet_hours_schedule = $3,
+	allowed_external_auth_providers = $4,
+	disabled_notification_template_ids = $5,
+	provisioner_max_jobs = $6,
+	provisioner_reserved_jobs = $7,
+	updated_at = $8
+RETURNING organization_id, default_ttl, default_quiet_hours_schedule, allowed_external_auth_providers, disabled_notification_template_ids, updated_at, provisioner_max_jobs, provisioner_reserved_jobs
+`
+
+type UpsertOrganizationSettingOverridesParams struct {
+	OrganizationID                  uuid.UUID      `db:"organization_id" json:"organization_id"`
+	DefaultTTL                      sql.NullInt64  `db:"default_ttl" json:"default_ttl"`
+	DefaultQuietHoursSchedule       sql.NullString `db:"default_quiet_hours_schedule" json:"default_quiet_hours_schedule"`
+	AllowedExternalAuthProviders    []string       `db:"allowed_external_auth_providers" json:"allowed_external_auth_providers"`
+	DisabledNotificationTemplateIDs []uuid.UUID    `db:"disabled_notification_template_ids" json:"disabled_notification_template_ids"`
+	ProvisionerMaxJobs              sql.NullInt32  `db:"provisioner_max_jobs" json:"provisioner_max_jobs"`
+	ProvisionerReservedJobs         sql.NullInt32  `db:"provisioner_reserved_jobs" json:"provisioner_reserved_jobs"`
+	UpdatedAt                       time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertOrganizationSettingOverrides(ctx context.Context, arg UpsertOrganizationSettingOverridesParams) (OrganizationSettingOverride, error) {
+	row := q.db.QueryRowContext(ctx, upsertOrganizationSettingOverrides,
+		arg.OrganizationID,
+		arg.DefaultTTL,
+		arg.DefaultQuietHoursSchedule,
+		pq.Array(arg.AllowedExternalAuthProviders),
+		pq.Array(arg.DisabledNotificationTemplateIDs),
+		arg.ProvisionerMaxJobs,
+		arg.ProvisionerReservedJobs,
+		arg.UpdatedAt,
+	)
+	var i OrganizationSettingOverride
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.DefaultTTL,
+		&i.DefaultQuietHoursSchedule,
+		pq.Array(&i.AllowedExternalAuthProviders),
+		pq.Array(&i.DisabledNotificationTemplateIDs),
+		&i.UpdatedAt,
+		&i.ProvisionerMaxJobs,
+		&i.ProvisionerReservedJobs,
+	)
+	return i, err
+}
+
 const getParameterSchemasByJobID = `-- name: GetParameterSchemasByJobID :many
 SELECT
 	id, created_at, job_id, name, description, default_source_scheme, default_source_value, allow_override_source, default_destination_scheme, allow_override_destination, default_refresh, redisplay_value, validation_error, validation_condition, validation_type_system, validation_value_type, index
@@ -6523,6 +9027,7 @@ const claimPrebuiltWorkspace = `-- name: ClaimPrebuiltWorkspace :one
 UPDATE workspaces w
 SET owner_id   = $1::uuid,
 	name       = $2::text,
+	ephemeral  = $3::boolean,
 	updated_at = NOW()
 WHERE w.id IN (
 	SELECT p.id
@@ -6534,7 +9039,7 @@ WHERE w.id IN (
 		-- The prebuilds system should never try to claim a prebuild for an inactive template version.
 		-- Nevertheless, this filter is here as a defensive measure:
 		AND b.template_version_id = t.active_version_id
-		AND p.current_preset_id = $3::uuid
+		AND p.current_preset_id = $4::uuid
 		AND p.ready
 		AND NOT t.deleted
 	LIMIT 1 FOR UPDATE OF p SKIP LOCKED -- Ensure that a concurrent request will not select the same prebuild.
@@ -6545,6 +9050,7 @@ RETURNING w.id, w.name
 type ClaimPrebuiltWorkspaceParams struct {
 	NewUserID uuid.UUID `db:"new_user_id" json:"new_user_id"`
 	NewName   string    `db:"new_name" json:"new_name"`
+	Ephemeral bool      `db:"ephemeral" json:"ephemeral"`
 	PresetID  uuid.UUID `db:"preset_id" json:"preset_id"`
 }
 
@@ -6554,7 +9060,12 @@ type ClaimPrebuiltWorkspaceRow struct {
 }
 
 func (q *sqlQuerier) ClaimPrebuiltWorkspace(ctx context.Context, arg ClaimPrebuiltWorkspaceParams) (ClaimPrebuiltWorkspaceRow, error) {
-	row := q.db.QueryRowContext(ctx, claimPrebuiltWorkspace, arg.NewUserID, arg.NewName, arg.PresetID)
+	row := q.db.QueryRowContext(ctx, claimPrebuiltWorkspace,
+		arg.NewUserID,
+		arg.NewName,
+		arg.Ephemeral,
+		arg.PresetID,
+	)
 	var i ClaimPrebuiltWorkspaceRow
 	err := row.Scan(&i.ID, &i.Name)
 	return i, err
@@ -7985,6 +10496,124 @@ func (q *sqlQuerier) InsertProvisionerJobLogs(ctx context.Context, arg InsertPro
 	return items, nil
 }
 
+const searchProvisionerJobLogs = `-- name: SearchProvisionerJobLogs :many
+SELECT
+	provisioner_job_logs.job_id, provisioner_job_logs.created_at, provisioner_job_logs.source, provisioner_job_logs.level, provisioner_job_logs.stage, provisioner_job_logs.output, provisioner_job_logs.id,
+	provisioner_jobs.type AS job_type,
+	COALESCE(workspace_builds.id, '00000000-0000-0000-0000-000000000000'::uuid) AS workspace_build_id,
+	COALESCE(workspace_builds.workspace_id, '00000000-0000-0000-0000-000000000000'::uuid) AS workspace_id,
+	COALESCE(template_versions.id, '00000000-0000-0000-0000-000000000000'::uuid) AS template_version_id,
+	COALESCE(template_versions.template_id, '00000000-0000-0000-0000-000000000000'::uuid) AS template_id
+FROM
+	provisioner_job_logs
+	JOIN provisioner_jobs ON provisioner_jobs.id = provisioner_job_logs.job_id
+	LEFT JOIN workspace_builds ON workspace_builds.job_id = provisioner_jobs.id
+	-- Template version imports belong to their template version, and
+	-- workspace builds to the template version that they build.
+	LEFT JOIN template_versions ON template_versions.job_id = provisioner_jobs.id
+		OR template_versions.id = workspace_builds.template_version_id
+WHERE
+	provisioner_jobs.organization_id = $1
+	AND to_tsvector('simple', provisioner_job_logs.output::text) @@ websearch_to_tsquery('simple', $2::text)
+	-- Filter by job type
+	AND CASE
+		WHEN $3::text != '' THEN provisioner_jobs.type = $3::provisioner_job_type
+		ELSE true
+	END
+	-- Filter by template_id
+	AND CASE
+		WHEN $4::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN template_versions.template_id = $4
+		ELSE true
+	END
+	-- Filter by workspace_id
+	AND CASE
+		WHEN $5::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN workspace_builds.workspace_id = $5
+		ELSE true
+	END
+	-- Filter by date_from
+	AND CASE
+		WHEN $6::timestamp with time zone != '0001-01-01 00:00:00Z' THEN provisioner_job_logs.created_at >= $6
+		ELSE true
+	END
+	-- Filter by date_to
+	AND CASE
+		WHEN $7::timestamp with time zone != '0001-01-01 00:00:00Z' THEN provisioner_job_logs.created_at <= $7
+		ELSE true
+	END
+ORDER BY
+	provisioner_job_logs.created_at DESC,
+	provisioner_job_logs.id DESC
+LIMIT
+	COALESCE(NULLIF($8::int, 0), 100)
+`
+
+type SearchProvisionerJobLogsParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Search         string    `db:"search" json:"search"`
+	JobType        string    `db:"job_type" json:"job_type"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	DateFrom       time.Time `db:"date_from" json:"date_from"`
+	DateTo         time.Time `db:"date_to" json:"date_to"`
+	LimitOpt       int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+type SearchProvisionerJobLogsRow struct {
+	ProvisionerJobLog ProvisionerJobLog  `db:"provisioner_job_log" json:"provisioner_job_log"`
+	JobType           ProvisionerJobType `db:"job_type" json:"job_type"`
+	WorkspaceBuildID  uuid.UUID          `db:"workspace_build_id" json:"workspace_build_id"`
+	WorkspaceID       uuid.UUID          `db:"workspace_id" json:"workspace_id"`
+	TemplateVersionID uuid.UUID          `db:"template_version_id" json:"template_version_id"`
+	TemplateID        uuid.UUID          `db:"template_id" json:"template_id"`
+}
+
+// Full-text search over the logs of the provisioner jobs in an organization,
+// ordered from newest to oldest.
+func (q *sqlQuerier) SearchProvisionerJobLogs(ctx context.Context, arg SearchProvisionerJobLogsParams) ([]SearchProvisionerJobLogsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchProvisionerJobLogs,
+		arg.OrganizationID,
+		arg.Search,
+		arg.JobType,
+		arg.TemplateID,
+		arg.WorkspaceID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchProvisionerJobLogsRow
+	for rows.Next() {
+		var i SearchProvisionerJobLogsRow
+		if err := rows.Scan(
+			&i.ProvisionerJobLog.JobID,
+			&i.ProvisionerJobLog.CreatedAt,
+			&i.ProvisionerJobLog.Source,
+			&i.ProvisionerJobLog.Level,
+			&i.ProvisionerJobLog.Stage,
+			&i.ProvisionerJobLog.Output,
+			&i.ProvisionerJobLog.ID,
+			&i.JobType,
+			&i.WorkspaceBuildID,
+			&i.WorkspaceID,
+			&i.TemplateVersionID,
+			&i.TemplateID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const acquireProvisionerJob = `-- name: AcquireProvisionerJob :one
 UPDATE
 	provisioner_jobs
@@ -8006,6 +10635,91 @@ WHERE
 			-- elsewhere, we use the tagset type, but here we use jsonb for backward compatibility
 			-- they are aliases and the code that calls this query already relies on a different type
 			AND provisioner_tagset_contains($5 :: jsonb, potential_job.tags :: jsonb)
+			-- Queue workspace builds while their owner or template is at the
+			-- concurrent build limit. A limit of 0 disables it.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					workspace_builds AS pending_build
+					JOIN workspaces AS pending_workspace ON pending_workspace.id = pending_build.workspace_id
+				WHERE
+					pending_build.job_id = potential_job.id
+					AND (
+						(
+							$6 :: integer > 0
+							AND (
+								SELECT
+									COUNT(*)
+								FROM
+									workspace_builds AS running_build
+									JOIN provisioner_jobs AS running_job ON running_job.id = running_build.job_id
+									JOIN workspaces AS running_workspace ON running_workspace.id = running_build.workspace_id
+								WHERE
+									running_job.started_at IS NOT NULL
+									AND running_job.completed_at IS NULL
+									AND running_workspace.owner_id = pending_workspace.owner_id
+							) >= $6 :: integer
+						)
+						OR (
+							$7 :: integer > 0
+							AND (
+								SELECT
+									COUNT(*)
+								FROM
+									workspace_builds AS running_build
+									JOIN provisioner_jobs AS running_job ON running_job.id = running_build.job_id
+									JOIN workspaces AS running_workspace ON running_workspace.id = running_build.workspace_id
+								WHERE
+									running_job.started_at IS NOT NULL
+									AND running_job.completed_at IS NULL
+									AND running_workspace.template_id = pending_workspace.template_id
+							) >= $7 :: integer
+						)
+					)
+			)
+			-- Queue the jobs of organizations at their concurrent job limit.
+			-- When the shared capacity is limited, also queue them while the
+			-- organization is past its reservation and the capacity left is
+			-- reserved by other organizations. Limits of 0 disable them.
+			AND (
+				SELECT
+					(usage.max_jobs = 0 OR usage.running_jobs < usage.max_jobs)
+					AND (
+						$8 :: integer = 0
+						OR usage.running_jobs < usage.reserved_jobs
+						OR usage.total_running_jobs + usage.reserved_by_others < $8 :: integer
+					)
+				FROM
+					(
+						SELECT
+							COALESCE(SUM(org_usage.running_jobs) FILTER (WHERE org_usage.id = $3), 0) AS running_jobs,
+							COALESCE(MAX(org_usage.max_jobs) FILTER (WHERE org_usage.id = $3), 0) AS max_jobs,
+							COALESCE(MAX(org_usage.reserved_jobs) FILTER (WHERE org_usage.id = $3), 0) AS reserved_jobs,
+							COALESCE(SUM(org_usage.running_jobs), 0) AS total_running_jobs,
+							COALESCE(SUM(GREATEST(org_usage.reserved_jobs - org_usage.running_jobs, 0)) FILTER (WHERE org_usage.id != $3), 0) AS reserved_by_others
+						FROM
+							(
+								SELECT
+									organizations.id,
+									COALESCE(organization_setting_overrides.provisioner_max_jobs, $9 :: integer) AS max_jobs,
+									COALESCE(organization_setting_overrides.provisioner_reserved_jobs, $10 :: integer) AS reserved_jobs,
+									(
+										SELECT
+											COUNT(*)
+										FROM
+											provisioner_jobs AS running_job
+										WHERE
+											running_job.organization_id = organizations.id
+											AND running_job.started_at IS NOT NULL
+											AND running_job.completed_at IS NULL
+									) AS running_jobs
+								FROM
+									organizations
+									LEFT JOIN organization_setting_overrides ON organization_setting_overrides.organization_id = organizations.id
+							) AS org_usage
+					) AS usage
+			)
 		ORDER BY
 			potential_job.created_at
 		FOR UPDATE
@@ -8016,11 +10730,16 @@ WHERE
 `
 
 type AcquireProvisionerJobParams struct {
-	StartedAt       sql.NullTime      `db:"started_at" json:"started_at"`
-	WorkerID        uuid.NullUUID     `db:"worker_id" json:"worker_id"`
-	OrganizationID  uuid.UUID         `db:"organization_id" json:"organization_id"`
-	Types           []ProvisionerType `db:"types" json:"types"`
-	ProvisionerTags json.RawMessage   `db:"provisioner_tags" json:"provisioner_tags"`
+	StartedAt                   sql.NullTime      `db:"started_at" json:"started_at"`
+	WorkerID                    uuid.NullUUID     `db:"worker_id" json:"worker_id"`
+	OrganizationID              uuid.UUID         `db:"organization_id" json:"organization_id"`
+	Types                       []ProvisionerType `db:"types" json:"types"`
+	ProvisionerTags             json.RawMessage   `db:"provisioner_tags" json:"provisioner_tags"`
+	MaxBuildsPerUser            int32             `db:"max_builds_per_user" json:"max_builds_per_user"`
+	MaxBuildsPerTemplate        int32             `db:"max_builds_per_template" json:"max_builds_per_template"`
+	MaxConcurrentJobs           int32             `db:"max_concurrent_jobs" json:"max_concurrent_jobs"`
+	MaxJobsPerOrganization      int32             `db:"max_jobs_per_organization" json:"max_jobs_per_organization"`
+	ReservedJobsPerOrganization int32             `db:"reserved_jobs_per_organization" json:"reserved_jobs_per_organization"`
 }
 
 // Acquires the lock for a single job that isn't started, completed,
@@ -8029,6 +10748,18 @@ type AcquireProvisionerJobParams struct {
 // SKIP LOCKED is used to jump over locked rows. This prevents
 // multiple provisioners from acquiring the same jobs. See:
 // https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
+//
+// Workspace builds are skipped, and so stay queued, while the workspace
+// owner or template already has the maximum number of running builds.
+//
+// No job is acquired while the organization has the maximum number of
+// running jobs, or while the rest of the shared capacity of the deployment
+// is reserved for other organizations.
+//
+// The running jobs are counted without locking them, so two concurrent calls
+// could both admit a job into the last free slot. Callers serialize admission
+// by holding an advisory lock for the transaction, see
+// provisionerdserver.Acquirer.
 func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvisionerJobParams) (ProvisionerJob, error) {
 	row := q.db.QueryRowContext(ctx, acquireProvisionerJob,
 		arg.StartedAt,
@@ -8036,6 +10767,11 @@ func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvi
 		arg.OrganizationID,
 		pq.Array(arg.Types),
 		arg.ProvisionerTags,
+		arg.MaxBuildsPerUser,
+		arg.MaxBuildsPerTemplate,
+		arg.MaxConcurrentJobs,
+		arg.MaxJobsPerOrganization,
+		arg.ReservedJobsPerOrganization,
 	)
 	var i ProvisionerJob
 	err := row.Scan(
@@ -8062,13 +10798,172 @@ func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvi
 	return i, err
 }
 
-const getProvisionerJobByID = `-- name: GetProvisionerJobByID :one
+const getOrphanedProvisionerJobs = `-- name: GetOrphanedProvisionerJobs :many
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	provisioner_jobs.id, provisioner_jobs.created_at, provisioner_jobs.updated_at, provisioner_jobs.started_at, provisioner_jobs.canceled_at, provisioner_jobs.completed_at, provisioner_jobs.error, provisioner_jobs.organization_id, provisioner_jobs.initiator_id, provisioner_jobs.provisioner, provisioner_jobs.storage_method, provisioner_jobs.type, provisioner_jobs.input, provisioner_jobs.worker_id, provisioner_jobs.file_id, provisioner_jobs.tags, provisioner_jobs.error_code, provisioner_jobs.trace_metadata, provisioner_jobs.job_status,
+	(
+		SELECT
+			COUNT(*)
+		FROM
+			provisioner_job_requeues
+		WHERE
+			provisioner_job_requeues.job_id = provisioner_jobs.id
+	) AS requeue_count
 FROM
 	provisioner_jobs
+	LEFT JOIN provisioner_daemons ON provisioner_daemons.id = provisioner_jobs.worker_id
 WHERE
-	id = $1
+	provisioner_jobs.updated_at < $1
+	AND provisioner_jobs.started_at < $1
+	AND provisioner_jobs.completed_at IS NULL
+	AND (
+		provisioner_daemons.id IS NULL
+		OR provisioner_daemons.last_seen_at IS NULL
+		OR provisioner_daemons.last_seen_at < $1
+	)
+ORDER BY random()
+LIMIT $2
+`
+
+type GetOrphanedProvisionerJobsParams struct {
+	StaleSince time.Time `db:"stale_since" json:"stale_since"`
+	MaxJobs    int32     `db:"max_jobs" json:"max_jobs"`
+}
+
+type GetOrphanedProvisionerJobsRow struct {
+	ProvisionerJob ProvisionerJob `db:"provisioner_job" json:"provisioner_job"`
+	RequeueCount   int64          `db:"requeue_count" json:"requeue_count"`
+}
+
+// Returns running jobs whose provisioner daemon has not sent a heartbeat since
+// @stale_since, or no longer exists, along with the number of times each job
+// has already been requeued.
+// To avoid repeatedly attempting to handle the same jobs, we randomly order and limit to @max_jobs.
+func (q *sqlQuerier) GetOrphanedProvisionerJobs(ctx context.Context, arg GetOrphanedProvisionerJobsParams) ([]GetOrphanedProvisionerJobsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOrphanedProvisionerJobs, arg.StaleSince, arg.MaxJobs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOrphanedProvisionerJobsRow
+	for rows.Next() {
+		var i GetOrphanedProvisionerJobsRow
+		if err := rows.Scan(
+			&i.ProvisionerJob.ID,
+			&i.ProvisionerJob.CreatedAt,
+			&i.ProvisionerJob.UpdatedAt,
+			&i.ProvisionerJob.StartedAt,
+			&i.ProvisionerJob.CanceledAt,
+			&i.ProvisionerJob.CompletedAt,
+			&i.ProvisionerJob.Error,
+			&i.ProvisionerJob.OrganizationID,
+			&i.ProvisionerJob.InitiatorID,
+			&i.ProvisionerJob.Provisioner,
+			&i.ProvisionerJob.StorageMethod,
+			&i.ProvisionerJob.Type,
+			&i.ProvisionerJob.Input,
+			&i.ProvisionerJob.WorkerID,
+			&i.ProvisionerJob.FileID,
+			&i.ProvisionerJob.Tags,
+			&i.ProvisionerJob.ErrorCode,
+			&i.ProvisionerJob.TraceMetadata,
+			&i.ProvisionerJob.JobStatus,
+			&i.RequeueCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPendingProvisionerJobsCount = `-- name: GetPendingProvisionerJobsCount :one
+SELECT
+	COUNT(*)
+FROM
+	provisioner_jobs
+WHERE
+	started_at IS NULL
+	AND canceled_at IS NULL
+	AND completed_at IS NULL
+`
+
+// Returns the number of provisioner jobs which are waiting to be acquired by a
+// provisioner daemon.
+func (q *sqlQuerier) GetPendingProvisionerJobsCount(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getPendingProvisionerJobsCount)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getProvisionerJobCountsByOrganization = `-- name: GetProvisionerJobCountsByOrganization :many
+SELECT
+	organizations.id AS organization_id,
+	organizations.name AS organization_name,
+	COUNT(provisioner_jobs.id) FILTER (WHERE provisioner_jobs.started_at IS NULL) AS pending_jobs,
+	COUNT(provisioner_jobs.id) FILTER (WHERE provisioner_jobs.started_at IS NOT NULL) AS running_jobs
+FROM
+	organizations
+	LEFT JOIN provisioner_jobs ON provisioner_jobs.organization_id = organizations.id
+		AND provisioner_jobs.completed_at IS NULL
+		AND (provisioner_jobs.started_at IS NOT NULL OR provisioner_jobs.canceled_at IS NULL)
+GROUP BY
+	organizations.id
+ORDER BY
+	organizations.name
+`
+
+type GetProvisionerJobCountsByOrganizationRow struct {
+	OrganizationID   uuid.UUID `db:"organization_id" json:"organization_id"`
+	OrganizationName string    `db:"organization_name" json:"organization_name"`
+	PendingJobs      int64     `db:"pending_jobs" json:"pending_jobs"`
+	RunningJobs      int64     `db:"running_jobs" json:"running_jobs"`
+}
+
+// Returns the number of provisioner jobs of every organization which are
+// waiting to be acquired by a provisioner daemon, and which are running.
+func (q *sqlQuerier) GetProvisionerJobCountsByOrganization(ctx context.Context) ([]GetProvisionerJobCountsByOrganizationRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobCountsByOrganization)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProvisionerJobCountsByOrganizationRow
+	for rows.Next() {
+		var i GetProvisionerJobCountsByOrganizationRow
+		if err := rows.Scan(
+			&i.OrganizationID,
+			&i.OrganizationName,
+			&i.PendingJobs,
+			&i.RunningJobs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProvisionerJobByID = `-- name: GetProvisionerJobByID :one
+SELECT
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+FROM
+	provisioner_jobs
+WHERE
+	id = $1
 `
 
 func (q *sqlQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (ProvisionerJob, error) {
@@ -8734,6 +11629,24 @@ func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisi
 	return i, err
 }
 
+const insertProvisionerJobRequeue = `-- name: InsertProvisionerJobRequeue :exec
+INSERT INTO
+	provisioner_job_requeues (job_id, worker_id, created_at)
+VALUES
+	($1, $2, $3)
+`
+
+type InsertProvisionerJobRequeueParams struct {
+	JobID     uuid.UUID     `db:"job_id" json:"job_id"`
+	WorkerID  uuid.NullUUID `db:"worker_id" json:"worker_id"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertProvisionerJobRequeue(ctx context.Context, arg InsertProvisionerJobRequeueParams) error {
+	_, err := q.db.ExecContext(ctx, insertProvisionerJobRequeue, arg.JobID, arg.WorkerID, arg.CreatedAt)
+	return err
+}
+
 const insertProvisionerJobTimings = `-- name: InsertProvisionerJobTimings :many
 INSERT INTO provisioner_job_timings (job_id, started_at, ended_at, stage, source, action, resource)
 SELECT
@@ -8796,6 +11709,29 @@ func (q *sqlQuerier) InsertProvisionerJobTimings(ctx context.Context, arg Insert
 	return items, nil
 }
 
+const requeueProvisionerJob = `-- name: RequeueProvisionerJob :exec
+UPDATE
+	provisioner_jobs
+SET
+	started_at = NULL,
+	worker_id = NULL,
+	updated_at = $1
+WHERE
+	id = $2
+`
+
+type RequeueProvisionerJobParams struct {
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID        uuid.UUID `db:"id" json:"id"`
+}
+
+// Returns a running job to the queue so that another provisioner daemon can
+// acquire it.
+func (q *sqlQuerier) RequeueProvisionerJob(ctx context.Context, arg RequeueProvisionerJobParams) error {
+	_, err := q.db.ExecContext(ctx, requeueProvisionerJob, arg.UpdatedAt, arg.ID)
+	return err
+}
+
 const updateProvisionerJobByID = `-- name: UpdateProvisionerJobByID :exec
 UPDATE
 	provisioner_jobs
@@ -9495,6 +12431,52 @@ func (q *sqlQuerier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg Update
 	return err
 }
 
+const deleteGroupQuotaAllowances = `-- name: DeleteGroupQuotaAllowances :exec
+DELETE FROM
+	group_quota_allowances
+WHERE
+	group_id = $1
+`
+
+func (q *sqlQuerier) DeleteGroupQuotaAllowances(ctx context.Context, groupID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupQuotaAllowances, groupID)
+	return err
+}
+
+const getGroupQuotaAllowances = `-- name: GetGroupQuotaAllowances :many
+SELECT
+	group_id, dimension, allowance
+FROM
+	group_quota_allowances
+WHERE
+	group_id = $1
+ORDER BY
+	dimension
+`
+
+func (q *sqlQuerier) GetGroupQuotaAllowances(ctx context.Context, groupID uuid.UUID) ([]GroupQuotaAllowance, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupQuotaAllowances, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GroupQuotaAllowance
+	for rows.Next() {
+		var i GroupQuotaAllowance
+		if err := rows.Scan(&i.GroupID, &i.Dimension, &i.Allowance); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getQuotaAllowanceForUser = `-- name: GetQuotaAllowanceForUser :one
 SELECT
 	coalesce(SUM(groups.quota_allowance), 0)::BIGINT
@@ -9528,7 +12510,9 @@ WITH latest_builds AS (
 SELECT
 	DISTINCT ON
 	(wb.workspace_id) wb.workspace_id,
-	wb.daily_cost
+	wb.daily_cost,
+	wb.transition,
+	workspaces.ephemeral
 FROM
 	workspace_builds wb
  -- This INNER JOIN prevents a seq scan of the workspace_builds table.
@@ -9550,6 +12534,9 @@ SELECT
 	coalesce(SUM(daily_cost), 0)::BIGINT
 FROM
 	latest_builds
+WHERE
+	-- Ephemeral workspaces only consume quota while they are running.
+	NOT latest_builds.ephemeral OR latest_builds.transition = 'start'
 `
 
 type GetQuotaConsumedForUserParams struct {
@@ -9564,66 +12551,48 @@ func (q *sqlQuerier) GetQuotaConsumedForUser(ctx context.Context, arg GetQuotaCo
 	return column_1, err
 }
 
-const deleteReplicasUpdatedBefore = `-- name: DeleteReplicasUpdatedBefore :exec
-DELETE FROM replicas WHERE updated_at < $1
+const getQuotaDimensionAllowancesForUser = `-- name: GetQuotaDimensionAllowancesForUser :many
+SELECT
+	group_quota_allowances.dimension,
+	coalesce(SUM(group_quota_allowances.allowance) FILTER (WHERE members.user_id IS NOT NULL), 0)::BIGINT AS allowance
+FROM
+	group_quota_allowances
+INNER JOIN groups ON
+	group_quota_allowances.group_id = groups.id
+LEFT JOIN group_members_expanded AS members ON
+	members.group_id = group_quota_allowances.group_id AND
+	members.user_id = $1
+WHERE
+	groups.organization_id = $2
+GROUP BY
+	group_quota_allowances.dimension
+ORDER BY
+	group_quota_allowances.dimension
 `
 
-func (q *sqlQuerier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
-	_, err := q.db.ExecContext(ctx, deleteReplicasUpdatedBefore, updatedAt)
-	return err
+type GetQuotaDimensionAllowancesForUserParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
 }
 
-const getReplicaByID = `-- name: GetReplicaByID :one
-SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE id = $1
-`
-
-func (q *sqlQuerier) GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error) {
-	row := q.db.QueryRowContext(ctx, getReplicaByID, id)
-	var i Replica
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.StartedAt,
-		&i.StoppedAt,
-		&i.UpdatedAt,
-		&i.Hostname,
-		&i.RegionID,
-		&i.RelayAddress,
-		&i.DatabaseLatency,
-		&i.Version,
-		&i.Error,
-		&i.Primary,
-	)
-	return i, err
+type GetQuotaDimensionAllowancesForUserRow struct {
+	Dimension string `db:"dimension" json:"dimension"`
+	Allowance int64  `db:"allowance" json:"allowance"`
 }
 
-const getReplicasUpdatedAfter = `-- name: GetReplicasUpdatedAfter :many
-SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE updated_at > $1 AND stopped_at IS NULL
-`
-
-func (q *sqlQuerier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error) {
-	rows, err := q.db.QueryContext(ctx, getReplicasUpdatedAfter, updatedAt)
+// Returns every quota dimension that has an allowance in the organization,
+// with the sum of the allowances of the groups the user is a member of.
+// Dimensions without any allowance in the organization are not enforced.
+func (q *sqlQuerier) GetQuotaDimensionAllowancesForUser(ctx context.Context, arg GetQuotaDimensionAllowancesForUserParams) ([]GetQuotaDimensionAllowancesForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getQuotaDimensionAllowancesForUser, arg.UserID, arg.OrganizationID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Replica
+	var items []GetQuotaDimensionAllowancesForUserRow
 	for rows.Next() {
-		var i Replica
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.StartedAt,
-			&i.StoppedAt,
-			&i.UpdatedAt,
-			&i.Hostname,
-			&i.RegionID,
-			&i.RelayAddress,
-			&i.DatabaseLatency,
-			&i.Version,
-			&i.Error,
-			&i.Primary,
-		); err != nil {
+		var i GetQuotaDimensionAllowancesForUserRow
+		if err := rows.Scan(&i.Dimension, &i.Allowance); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -9637,35 +12606,317 @@ func (q *sqlQuerier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time
 	return items, nil
 }
 
-const insertReplica = `-- name: InsertReplica :one
-INSERT INTO replicas (
-    id,
-    created_at,
-    started_at,
-    updated_at,
-    hostname,
-    region_id,
-    relay_address,
-    version,
-    database_latency,
-	"primary"
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary"
+const getQuotaDimensionsConsumedForGroup = `-- name: GetQuotaDimensionsConsumedForGroup :many
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(wb.workspace_id) wb.workspace_id,
+	wb.id,
+	wb.transition,
+	workspaces.ephemeral
+FROM
+	workspace_builds wb
+INNER JOIN
+	workspaces on wb.workspace_id = workspaces.id
+INNER JOIN group_members_expanded AS members ON
+	members.user_id = workspaces.owner_id AND
+	members.organization_id = workspaces.organization_id
+WHERE
+	NOT workspaces.deleted AND
+	members.group_id = $1
+ORDER BY
+	wb.workspace_id,
+	wb.build_number DESC
+)
+SELECT
+	costs.dimension,
+	SUM(costs.cost)::BIGINT AS consumed
+FROM
+	latest_builds
+INNER JOIN workspace_build_quota_costs AS costs ON
+	costs.workspace_build_id = latest_builds.id
+WHERE
+	NOT latest_builds.ephemeral OR latest_builds.transition = 'start'
+GROUP BY
+	costs.dimension
+ORDER BY
+	costs.dimension
 `
 
-type InsertReplicaParams struct {
-	ID              uuid.UUID `db:"id" json:"id"`
-	CreatedAt       time.Time `db:"created_at" json:"created_at"`
-	StartedAt       time.Time `db:"started_at" json:"started_at"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
-	Hostname        string    `db:"hostname" json:"hostname"`
-	RegionID        int32     `db:"region_id" json:"region_id"`
-	RelayAddress    string    `db:"relay_address" json:"relay_address"`
-	Version         string    `db:"version" json:"version"`
-	DatabaseLatency int32     `db:"database_latency" json:"database_latency"`
-	Primary         bool      `db:"primary" json:"primary"`
+type GetQuotaDimensionsConsumedForGroupRow struct {
+	Dimension string `db:"dimension" json:"dimension"`
+	Consumed  int64  `db:"consumed" json:"consumed"`
 }
 
-func (q *sqlQuerier) InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error) {
+// Returns the quota consumed by the workspaces of all members of the group
+// in the organization of the group.
+func (q *sqlQuerier) GetQuotaDimensionsConsumedForGroup(ctx context.Context, groupID uuid.UUID) ([]GetQuotaDimensionsConsumedForGroupRow, error) {
+	rows, err := q.db.QueryContext(ctx, getQuotaDimensionsConsumedForGroup, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetQuotaDimensionsConsumedForGroupRow
+	for rows.Next() {
+		var i GetQuotaDimensionsConsumedForGroupRow
+		if err := rows.Scan(&i.Dimension, &i.Consumed); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getQuotaDimensionsConsumedForUser = `-- name: GetQuotaDimensionsConsumedForUser :many
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(wb.workspace_id) wb.workspace_id,
+	wb.id,
+	wb.transition,
+	workspaces.ephemeral
+FROM
+	workspace_builds wb
+INNER JOIN
+	workspaces on wb.workspace_id = workspaces.id
+WHERE
+	NOT workspaces.deleted AND
+	workspaces.owner_id = $1 AND
+	workspaces.organization_id = $2
+ORDER BY
+	wb.workspace_id,
+	wb.build_number DESC
+)
+SELECT
+	costs.dimension,
+	SUM(costs.cost)::BIGINT AS consumed
+FROM
+	latest_builds
+INNER JOIN workspace_build_quota_costs AS costs ON
+	costs.workspace_build_id = latest_builds.id
+WHERE
+	NOT latest_builds.ephemeral OR latest_builds.transition = 'start'
+GROUP BY
+	costs.dimension
+ORDER BY
+	costs.dimension
+`
+
+type GetQuotaDimensionsConsumedForUserParams struct {
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+type GetQuotaDimensionsConsumedForUserRow struct {
+	Dimension string `db:"dimension" json:"dimension"`
+	Consumed  int64  `db:"consumed" json:"consumed"`
+}
+
+func (q *sqlQuerier) GetQuotaDimensionsConsumedForUser(ctx context.Context, arg GetQuotaDimensionsConsumedForUserParams) ([]GetQuotaDimensionsConsumedForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getQuotaDimensionsConsumedForUser, arg.OwnerID, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetQuotaDimensionsConsumedForUserRow
+	for rows.Next() {
+		var i GetQuotaDimensionsConsumedForUserRow
+		if err := rows.Scan(&i.Dimension, &i.Consumed); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceBuildQuotaCosts = `-- name: GetWorkspaceBuildQuotaCosts :many
+SELECT
+	workspace_build_id, dimension, cost
+FROM
+	workspace_build_quota_costs
+WHERE
+	workspace_build_id = $1
+ORDER BY
+	dimension
+`
+
+func (q *sqlQuerier) GetWorkspaceBuildQuotaCosts(ctx context.Context, workspaceBuildID uuid.UUID) ([]WorkspaceBuildQuotaCost, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildQuotaCosts, workspaceBuildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceBuildQuotaCost
+	for rows.Next() {
+		var i WorkspaceBuildQuotaCost
+		if err := rows.Scan(&i.WorkspaceBuildID, &i.Dimension, &i.Cost); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertGroupQuotaAllowances = `-- name: InsertGroupQuotaAllowances :exec
+INSERT INTO
+	group_quota_allowances (group_id, dimension, allowance)
+SELECT
+	$1,
+	unnest($2 :: TEXT[]),
+	unnest($3 :: BIGINT[])
+`
+
+type InsertGroupQuotaAllowancesParams struct {
+	GroupID    uuid.UUID `db:"group_id" json:"group_id"`
+	Dimensions []string  `db:"dimensions" json:"dimensions"`
+	Allowances []int64   `db:"allowances" json:"allowances"`
+}
+
+func (q *sqlQuerier) InsertGroupQuotaAllowances(ctx context.Context, arg InsertGroupQuotaAllowancesParams) error {
+	_, err := q.db.ExecContext(ctx, insertGroupQuotaAllowances, arg.GroupID, pq.Array(arg.Dimensions), pq.Array(arg.Allowances))
+	return err
+}
+
+const insertWorkspaceBuildQuotaCosts = `-- name: InsertWorkspaceBuildQuotaCosts :exec
+INSERT INTO
+	workspace_build_quota_costs (workspace_build_id, dimension, cost)
+SELECT
+	$1,
+	unnest($2 :: TEXT[]),
+	unnest($3 :: BIGINT[])
+`
+
+type InsertWorkspaceBuildQuotaCostsParams struct {
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	Dimensions       []string  `db:"dimensions" json:"dimensions"`
+	Costs            []int64   `db:"costs" json:"costs"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceBuildQuotaCosts(ctx context.Context, arg InsertWorkspaceBuildQuotaCostsParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceBuildQuotaCosts, arg.WorkspaceBuildID, pq.Array(arg.Dimensions), pq.Array(arg.Costs))
+	return err
+}
+
+const deleteReplicasUpdatedBefore = `-- name: DeleteReplicasUpdatedBefore :exec
+DELETE FROM replicas WHERE updated_at < $1
+`
+
+func (q *sqlQuerier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteReplicasUpdatedBefore, updatedAt)
+	return err
+}
+
+const getReplicaByID = `-- name: GetReplicaByID :one
+SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE id = $1
+`
+
+func (q *sqlQuerier) GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error) {
+	row := q.db.QueryRowContext(ctx, getReplicaByID, id)
+	var i Replica
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.UpdatedAt,
+		&i.Hostname,
+		&i.RegionID,
+		&i.RelayAddress,
+		&i.DatabaseLatency,
+		&i.Version,
+		&i.Error,
+		&i.Primary,
+	)
+	return i, err
+}
+
+const getReplicasUpdatedAfter = `-- name: GetReplicasUpdatedAfter :many
+SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE updated_at > $1 AND stopped_at IS NULL
+`
+
+func (q *sqlQuerier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error) {
+	rows, err := q.db.QueryContext(ctx, getReplicasUpdatedAfter, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Replica
+	for rows.Next() {
+		var i Replica
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.StartedAt,
+			&i.StoppedAt,
+			&i.UpdatedAt,
+			&i.Hostname,
+			&i.RegionID,
+			&i.RelayAddress,
+			&i.DatabaseLatency,
+			&i.Version,
+			&i.Error,
+			&i.Primary,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertReplica = `-- name: InsertReplica :one
+INSERT INTO replicas (
+    id,
+    created_at,
+    started_at,
+    updated_at,
+    hostname,
+    region_id,
+    relay_address,
+    version,
+    database_latency,
+	"primary"
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary"
+`
+
+type InsertReplicaParams struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	StartedAt       time.Time `db:"started_at" json:"started_at"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	Hostname        string    `db:"hostname" json:"hostname"`
+	RegionID        int32     `db:"region_id" json:"region_id"`
+	RelayAddress    string    `db:"relay_address" json:"relay_address"`
+	Version         string    `db:"version" json:"version"`
+	DatabaseLatency int32     `db:"database_latency" json:"database_latency"`
+	Primary         bool      `db:"primary" json:"primary"`
+}
+
+func (q *sqlQuerier) InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error) {
 	row := q.db.QueryRowContext(ctx, insertReplica,
 		arg.ID,
 		arg.CreatedAt,
@@ -9757,51 +13008,187 @@ func (q *sqlQuerier) UpdateReplica(ctx context.Context, arg UpdateReplicaParams)
 	return i, err
 }
 
-const customRoles = `-- name: CustomRoles :many
+const getReviewWorkspace = `-- name: GetReviewWorkspace :one
 SELECT
-	name, display_name, site_permissions, org_permissions, user_permissions, created_at, updated_at, organization_id, id
+	review_workspaces.workspace_id, review_workspaces.organization_id, review_workspaces.repository, review_workspaces.branch, review_workspaces.created_at, review_workspaces.updated_at
 FROM
-	custom_roles
+	review_workspaces
+INNER JOIN
+	workspaces ON workspaces.id = review_workspaces.workspace_id
 WHERE
-	true
-	-- @lookup_roles will filter for exact (role_name, org_id) pairs
-	-- To do this manually in SQL, you can construct an array and cast it:
-	-- cast(ARRAY[('customrole','ece79dac-926e-44ca-9790-2ff7c5eb6e0c')] AS name_organization_pair[])
-	AND CASE WHEN array_length($1 :: name_organization_pair[], 1) > 0  THEN
-		-- Using 'coalesce' to avoid troubles with null literals being an empty string.
-		(name, coalesce(organization_id, '00000000-0000-0000-0000-000000000000' ::uuid)) = ANY ($1::name_organization_pair[])
-	ELSE true
-	END
-	-- This allows fetching all roles, or just site wide roles
-	AND CASE WHEN $2 :: boolean  THEN
-		organization_id IS null
-	ELSE true
-	END
-	-- Allows fetching all roles to a particular organization
-	AND CASE WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid  THEN
-		organization_id = $3
-	ELSE true
-	END
+	review_workspaces.organization_id = $1
+	AND review_workspaces.repository = $2
+	AND review_workspaces.branch = $3
+	AND NOT workspaces.deleted
 `
 
-type CustomRolesParams struct {
-	LookupRoles     []NameOrganizationPair `db:"lookup_roles" json:"lookup_roles"`
-	ExcludeOrgRoles bool                   `db:"exclude_org_roles" json:"exclude_org_roles"`
-	OrganizationID  uuid.UUID              `db:"organization_id" json:"organization_id"`
+type GetReviewWorkspaceParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Repository     string    `db:"repository" json:"repository"`
+	Branch         string    `db:"branch" json:"branch"`
 }
 
-func (q *sqlQuerier) CustomRoles(ctx context.Context, arg CustomRolesParams) ([]CustomRole, error) {
-	rows, err := q.db.QueryContext(ctx, customRoles, pq.Array(arg.LookupRoles), arg.ExcludeOrgRoles, arg.OrganizationID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []CustomRole
-	for rows.Next() {
-		var i CustomRole
-		if err := rows.Scan(
-			&i.Name,
-			&i.DisplayName,
+func (q *sqlQuerier) GetReviewWorkspace(ctx context.Context, arg GetReviewWorkspaceParams) (ReviewWorkspace, error) {
+	row := q.db.QueryRowContext(ctx, getReviewWorkspace, arg.OrganizationID, arg.Repository, arg.Branch)
+	var i ReviewWorkspace
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.OrganizationID,
+		&i.Repository,
+		&i.Branch,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getReviewWorkspacesByRepositoryAndBranch = `-- name: GetReviewWorkspacesByRepositoryAndBranch :many
+SELECT
+	review_workspaces.workspace_id, review_workspaces.organization_id, review_workspaces.repository, review_workspaces.branch, review_workspaces.created_at, review_workspaces.updated_at
+FROM
+	review_workspaces
+INNER JOIN
+	workspaces ON workspaces.id = review_workspaces.workspace_id
+WHERE
+	review_workspaces.repository = $1
+	AND review_workspaces.branch = $2
+	AND NOT workspaces.deleted
+`
+
+type GetReviewWorkspacesByRepositoryAndBranchParams struct {
+	Repository string `db:"repository" json:"repository"`
+	Branch     string `db:"branch" json:"branch"`
+}
+
+// Returns the review workspaces for a branch in every organization. Used when
+// a webhook reports that the branch has been deleted.
+func (q *sqlQuerier) GetReviewWorkspacesByRepositoryAndBranch(ctx context.Context, arg GetReviewWorkspacesByRepositoryAndBranchParams) ([]ReviewWorkspace, error) {
+	rows, err := q.db.QueryContext(ctx, getReviewWorkspacesByRepositoryAndBranch, arg.Repository, arg.Branch)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReviewWorkspace
+	for rows.Next() {
+		var i ReviewWorkspace
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.OrganizationID,
+			&i.Repository,
+			&i.Branch,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertReviewWorkspace = `-- name: UpsertReviewWorkspace :one
+INSERT INTO
+	review_workspaces (
+		workspace_id,
+		organization_id,
+		repository,
+		branch,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $5)
+ON CONFLICT (organization_id, repository, branch) DO UPDATE
+SET
+	workspace_id = EXCLUDED.workspace_id,
+	updated_at = EXCLUDED.updated_at
+WHERE
+	review_workspaces.workspace_id = EXCLUDED.workspace_id
+	OR review_workspaces.workspace_id IN (SELECT id FROM workspaces WHERE deleted)
+RETURNING workspace_id, organization_id, repository, branch, created_at, updated_at
+`
+
+type UpsertReviewWorkspaceParams struct {
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Repository     string    `db:"repository" json:"repository"`
+	Branch         string    `db:"branch" json:"branch"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+// The branch may only be moved to another workspace once the workspace it
+// currently points at has been deleted. No rows are returned otherwise.
+func (q *sqlQuerier) UpsertReviewWorkspace(ctx context.Context, arg UpsertReviewWorkspaceParams) (ReviewWorkspace, error) {
+	row := q.db.QueryRowContext(ctx, upsertReviewWorkspace,
+		arg.WorkspaceID,
+		arg.OrganizationID,
+		arg.Repository,
+		arg.Branch,
+		arg.CreatedAt,
+	)
+	var i ReviewWorkspace
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.OrganizationID,
+		&i.Repository,
+		&i.Branch,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const customRoles = `-- name: CustomRoles :many
+SELECT
+	name, display_name, site_permissions, org_permissions, user_permissions, created_at, updated_at, organization_id, id
+FROM
+	custom_roles
+WHERE
+	true
+	-- @lookup_roles will filter for exact (role_name, org_id) pairs
+	-- To do this manually in SQL, you can construct an array and cast it:
+	-- cast(ARRAY[('customrole','ece79dac-926e-44ca-9790-2ff7c5eb6e0c')] AS name_organization_pair[])
+	AND CASE WHEN array_length($1 :: name_organization_pair[], 1) > 0  THEN
+		-- Using 'coalesce' to avoid troubles with null literals being an empty string.
+		(name, coalesce(organization_id, '00000000-0000-0000-0000-000000000000' ::uuid)) = ANY ($1::name_organization_pair[])
+	ELSE true
+	END
+	-- This allows fetching all roles, or just site wide roles
+	AND CASE WHEN $2 :: boolean  THEN
+		organization_id IS null
+	ELSE true
+	END
+	-- Allows fetching all roles to a particular organization
+	AND CASE WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid  THEN
+		organization_id = $3
+	ELSE true
+	END
+`
+
+type CustomRolesParams struct {
+	LookupRoles     []NameOrganizationPair `db:"lookup_roles" json:"lookup_roles"`
+	ExcludeOrgRoles bool                   `db:"exclude_org_roles" json:"exclude_org_roles"`
+	OrganizationID  uuid.UUID              `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) CustomRoles(ctx context.Context, arg CustomRolesParams) ([]CustomRole, error) {
+	rows, err := q.db.QueryContext(ctx, customRoles, pq.Array(arg.LookupRoles), arg.ExcludeOrgRoles, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CustomRole
+	for rows.Next() {
+		var i CustomRole
+		if err := rows.Scan(
+			&i.Name,
+			&i.DisplayName,
 			&i.SitePermissions,
 			&i.OrgPermissions,
 			&i.UserPermissions,
@@ -9948,6 +13335,154 @@ func (q *sqlQuerier) UpdateCustomRole(ctx context.Context, arg UpdateCustomRoleP
 	return i, err
 }
 
+const deleteSCIMGroupMembers = `-- name: DeleteSCIMGroupMembers :exec
+DELETE FROM
+	scim_group_members
+WHERE
+	group_id = $1 AND
+	user_id = ANY($2 :: uuid[])
+`
+
+type DeleteSCIMGroupMembersParams struct {
+	GroupID uuid.UUID   `db:"group_id" json:"group_id"`
+	UserIds []uuid.UUID `db:"user_ids" json:"user_ids"`
+}
+
+func (q *sqlQuerier) DeleteSCIMGroupMembers(ctx context.Context, arg DeleteSCIMGroupMembersParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSCIMGroupMembers, arg.GroupID, pq.Array(arg.UserIds))
+	return err
+}
+
+const deleteSCIMNestedGroups = `-- name: DeleteSCIMNestedGroups :exec
+DELETE FROM
+	scim_nested_groups
+WHERE
+	group_id = $1 AND
+	member_group_id = ANY($2 :: uuid[])
+`
+
+type DeleteSCIMNestedGroupsParams struct {
+	GroupID        uuid.UUID   `db:"group_id" json:"group_id"`
+	MemberGroupIds []uuid.UUID `db:"member_group_ids" json:"member_group_ids"`
+}
+
+func (q *sqlQuerier) DeleteSCIMNestedGroups(ctx context.Context, arg DeleteSCIMNestedGroupsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSCIMNestedGroups, arg.GroupID, pq.Array(arg.MemberGroupIds))
+	return err
+}
+
+const getSCIMGroupMembers = `-- name: GetSCIMGroupMembers :many
+SELECT
+	scim_group_members.group_id, scim_group_members.user_id
+FROM
+	scim_group_members
+INNER JOIN
+	groups ON groups.id = scim_group_members.group_id
+WHERE
+	groups.organization_id = $1
+`
+
+// Returns the users the identity provider assigned to each SCIM group in the
+// organization.
+func (q *sqlQuerier) GetSCIMGroupMembers(ctx context.Context, organizationID uuid.UUID) ([]SCIMGroupMember, error) {
+	rows, err := q.db.QueryContext(ctx, getSCIMGroupMembers, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SCIMGroupMember
+	for rows.Next() {
+		var i SCIMGroupMember
+		if err := rows.Scan(&i.GroupID, &i.UserID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSCIMNestedGroups = `-- name: GetSCIMNestedGroups :many
+SELECT
+	scim_nested_groups.group_id, scim_nested_groups.member_group_id
+FROM
+	scim_nested_groups
+INNER JOIN
+	groups ON groups.id = scim_nested_groups.group_id
+WHERE
+	groups.organization_id = $1
+`
+
+// Returns the groups the identity provider assigned as members of each SCIM
+// group in the organization.
+func (q *sqlQuerier) GetSCIMNestedGroups(ctx context.Context, organizationID uuid.UUID) ([]SCIMNestedGroup, error) {
+	rows, err := q.db.QueryContext(ctx, getSCIMNestedGroups, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SCIMNestedGroup
+	for rows.Next() {
+		var i SCIMNestedGroup
+		if err := rows.Scan(&i.GroupID, &i.MemberGroupID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertSCIMGroupMembers = `-- name: InsertSCIMGroupMembers :exec
+INSERT INTO
+	scim_group_members (group_id, user_id)
+SELECT
+	$1,
+	unnest($2 :: uuid[])
+ON CONFLICT DO NOTHING
+`
+
+type InsertSCIMGroupMembersParams struct {
+	GroupID uuid.UUID   `db:"group_id" json:"group_id"`
+	UserIds []uuid.UUID `db:"user_ids" json:"user_ids"`
+}
+
+// If there is a conflict, the user is already a member.
+func (q *sqlQuerier) InsertSCIMGroupMembers(ctx context.Context, arg InsertSCIMGroupMembersParams) error {
+	_, err := q.db.ExecContext(ctx, insertSCIMGroupMembers, arg.GroupID, pq.Array(arg.UserIds))
+	return err
+}
+
+const insertSCIMNestedGroups = `-- name: InsertSCIMNestedGroups :exec
+INSERT INTO
+	scim_nested_groups (group_id, member_group_id)
+SELECT
+	$1,
+	unnest($2 :: uuid[])
+ON CONFLICT DO NOTHING
+`
+
+type InsertSCIMNestedGroupsParams struct {
+	GroupID        uuid.UUID   `db:"group_id" json:"group_id"`
+	MemberGroupIds []uuid.UUID `db:"member_group_ids" json:"member_group_ids"`
+}
+
+// If there is a conflict, the group is already a member.
+func (q *sqlQuerier) InsertSCIMNestedGroups(ctx context.Context, arg InsertSCIMNestedGroupsParams) error {
+	_, err := q.db.ExecContext(ctx, insertSCIMNestedGroups, arg.GroupID, pq.Array(arg.MemberGroupIds))
+	return err
+}
+
 const deleteRuntimeConfig = `-- name: DeleteRuntimeConfig :exec
 DELETE FROM site_configs
 WHERE site_configs.key = $1
@@ -10042,6 +13577,18 @@ func (q *sqlQuerier) GetDeploymentID(ctx context.Context) (string, error) {
 	return value, err
 }
 
+const getExperimentFlags = `-- name: GetExperimentFlags :one
+SELECT
+	COALESCE((SELECT value FROM site_configs WHERE key = 'experiment_flags'), '{}') :: text AS experiment_flags
+`
+
+func (q *sqlQuerier) GetExperimentFlags(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getExperimentFlags)
+	var experiment_flags string
+	err := row.Scan(&experiment_flags)
+	return experiment_flags, err
+}
+
 const getHealthSettings = `-- name: GetHealthSettings :one
 SELECT
 	COALESCE((SELECT value FROM site_configs WHERE key = 'health_settings'), '{}') :: text AS health_settings
@@ -10054,6 +13601,17 @@ func (q *sqlQuerier) GetHealthSettings(ctx context.Context) (string, error) {
 	return health_settings, err
 }
 
+const getInternalCA = `-- name: GetInternalCA :one
+SELECT value FROM site_configs WHERE key = 'internal_ca'
+`
+
+func (q *sqlQuerier) GetInternalCA(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getInternalCA)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
 const getLastUpdateCheck = `-- name: GetLastUpdateCheck :one
 SELECT value FROM site_configs WHERE key = 'last_update_check'
 `
@@ -10238,6 +13796,16 @@ func (q *sqlQuerier) UpsertDefaultProxy(ctx context.Context, arg UpsertDefaultPr
 	return err
 }
 
+const upsertExperimentFlags = `-- name: UpsertExperimentFlags :exec
+INSERT INTO site_configs (key, value) VALUES ('experiment_flags', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'experiment_flags'
+`
+
+func (q *sqlQuerier) UpsertExperimentFlags(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertExperimentFlags, value)
+	return err
+}
+
 const upsertHealthSettings = `-- name: UpsertHealthSettings :exec
 INSERT INTO site_configs (key, value) VALUES ('health_settings', $1)
 ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'health_settings'
@@ -10248,6 +13816,16 @@ func (q *sqlQuerier) UpsertHealthSettings(ctx context.Context, value string) err
 	return err
 }
 
+const upsertInternalCA = `-- name: UpsertInternalCA :exec
+INSERT INTO site_configs (key, value) VALUES ('internal_ca', $1)
+ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'internal_ca'
+`
+
+func (q *sqlQuerier) UpsertInternalCA(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertInternalCA, value)
+	return err
+}
+
 const upsertLastUpdateCheck = `-- name: UpsertLastUpdateCheck :exec
 INSERT INTO site_configs (key, value) VALUES ('last_update_check', $1)
 ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'last_update_check'
@@ -11175,232 +14753,202 @@ func (q *sqlQuerier) UpsertTelemetryItem(ctx context.Context, arg UpsertTelemetr
 	return err
 }
 
-const getTemplateAverageBuildTime = `-- name: GetTemplateAverageBuildTime :one
-WITH build_times AS (
+const deleteTemplateAppRestriction = `-- name: DeleteTemplateAppRestriction :exec
+DELETE FROM
+	template_app_restrictions
+WHERE
+	template_id = $1
+	AND app_slug = $2
+`
+
+type DeleteTemplateAppRestrictionParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	AppSlug    string    `db:"app_slug" json:"app_slug"`
+}
+
+func (q *sqlQuerier) DeleteTemplateAppRestriction(ctx context.Context, arg DeleteTemplateAppRestrictionParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateAppRestriction, arg.TemplateID, arg.AppSlug)
+	return err
+}
+
+const getTemplateAppRestrictions = `-- name: GetTemplateAppRestrictions :many
 SELECT
-	EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at))::FLOAT AS exec_time_sec,
-	workspace_builds.transition
+	template_id, app_slug, group_ids, roles, created_at, updated_at
 FROM
-	workspace_builds
-JOIN template_versions ON
-	workspace_builds.template_version_id = template_versions.id
-JOIN provisioner_jobs pj ON
-	workspace_builds.job_id = pj.id
+	template_app_restrictions
 WHERE
-	template_versions.template_id = $1 AND
-		(pj.completed_at IS NOT NULL) AND (pj.started_at IS NOT NULL) AND
-		(pj.started_at > $2) AND
-		(pj.canceled_at IS NULL) AND
-		((pj.error IS NULL) OR (pj.error = ''))
+	template_id = $1
 ORDER BY
-	workspace_builds.created_at DESC
-)
-SELECT
-	-- Postgres offers no clear way to DRY this short of a function or other
-	-- complexities.
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_50,
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_50,
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_50,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_95,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_95,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_95
-FROM build_times
+	app_slug ASC
 `
 
-type GetTemplateAverageBuildTimeParams struct {
-	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
-	StartTime  sql.NullTime  `db:"start_time" json:"start_time"`
-}
-
-type GetTemplateAverageBuildTimeRow struct {
-	Start50  float64 `db:"start_50" json:"start_50"`
-	Stop50   float64 `db:"stop_50" json:"stop_50"`
-	Delete50 float64 `db:"delete_50" json:"delete_50"`
-	Start95  float64 `db:"start_95" json:"start_95"`
-	Stop95   float64 `db:"stop_95" json:"stop_95"`
-	Delete95 float64 `db:"delete_95" json:"delete_95"`
-}
-
-func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTemplateAverageBuildTimeParams) (GetTemplateAverageBuildTimeRow, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateAverageBuildTime, arg.TemplateID, arg.StartTime)
-	var i GetTemplateAverageBuildTimeRow
-	err := row.Scan(
-		&i.Start50,
-		&i.Stop50,
-		&i.Delete50,
-		&i.Start95,
-		&i.Stop95,
-		&i.Delete95,
-	)
-	return i, err
+func (q *sqlQuerier) GetTemplateAppRestrictions(ctx context.Context, templateID uuid.UUID) ([]TemplateAppRestriction, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateAppRestrictions, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateAppRestriction
+	for rows.Next() {
+		var i TemplateAppRestriction
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.AppSlug,
+			pq.Array(&i.GroupIds),
+			pq.Array(&i.Roles),
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getTemplateByID = `-- name: GetTemplateByID :one
+const getTemplateAppRestrictionsByTemplateIDs = `-- name: GetTemplateAppRestrictionsByTemplateIDs :many
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
+	template_id, app_slug, group_ids, roles, created_at, updated_at
 FROM
-	template_with_names
+	template_app_restrictions
 WHERE
-	id = $1
-LIMIT
-	1
+	template_id = ANY($1 :: uuid[])
 `
 
-func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateByID, id)
-	var i Template
+// Used to hide and deny access to the restricted apps of workspaces of
+// multiple templates at once.
+func (q *sqlQuerier) GetTemplateAppRestrictionsByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]TemplateAppRestriction, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateAppRestrictionsByTemplateIDs, pq.Array(templateIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateAppRestriction
+	for rows.Next() {
+		var i TemplateAppRestriction
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.AppSlug,
+			pq.Array(&i.GroupIds),
+			pq.Array(&i.Roles),
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertTemplateAppRestriction = `-- name: UpsertTemplateAppRestriction :one
+INSERT INTO template_app_restrictions (
+	template_id,
+	app_slug,
+	group_ids,
+	roles,
+	created_at,
+	updated_at
+)
+VALUES (
+	$1,
+	$2,
+	$3 :: uuid[],
+	$4 :: text[],
+	$5,
+	$5
+)
+ON CONFLICT (template_id, app_slug) DO UPDATE SET
+	group_ids = EXCLUDED.group_ids,
+	roles = EXCLUDED.roles,
+	updated_at = EXCLUDED.updated_at
+RETURNING template_id, app_slug, group_ids, roles, created_at, updated_at
+`
+
+type UpsertTemplateAppRestrictionParams struct {
+	TemplateID uuid.UUID   `db:"template_id" json:"template_id"`
+	AppSlug    string      `db:"app_slug" json:"app_slug"`
+	GroupIds   []uuid.UUID `db:"group_ids" json:"group_ids"`
+	Roles      []string    `db:"roles" json:"roles"`
+	UpdatedAt  time.Time   `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertTemplateAppRestriction(ctx context.Context, arg UpsertTemplateAppRestrictionParams) (TemplateAppRestriction, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateAppRestriction,
+		arg.TemplateID,
+		arg.AppSlug,
+		pq.Array(arg.GroupIds),
+		pq.Array(arg.Roles),
+		arg.UpdatedAt,
+	)
+	var i TemplateAppRestriction
 	err := row.Scan(
-		&i.ID,
+		&i.TemplateID,
+		&i.AppSlug,
+		pq.Array(&i.GroupIds),
+		pq.Array(&i.Roles),
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.OrganizationID,
-		&i.Deleted,
-		&i.Name,
-		&i.Provisioner,
-		&i.ActiveVersionID,
-		&i.Description,
-		&i.DefaultTTL,
-		&i.CreatedBy,
-		&i.Icon,
-		&i.UserACL,
-		&i.GroupACL,
-		&i.DisplayName,
-		&i.AllowUserCancelWorkspaceJobs,
-		&i.AllowUserAutostart,
-		&i.AllowUserAutostop,
-		&i.FailureTTL,
-		&i.TimeTilDormant,
-		&i.TimeTilDormantAutoDelete,
-		&i.AutostopRequirementDaysOfWeek,
-		&i.AutostopRequirementWeeks,
-		&i.AutostartBlockDaysOfWeek,
-		&i.RequireActiveVersion,
-		&i.Deprecated,
-		&i.ActivityBump,
-		&i.MaxPortSharingLevel,
-		&i.UseClassicParameterFlow,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
-		&i.CreatedByName,
-		&i.OrganizationName,
-		&i.OrganizationDisplayName,
-		&i.OrganizationIcon,
 	)
 	return i, err
 }
 
-const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
-SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
-FROM
-	template_with_names AS templates
+const deleteTemplateAppSessionPolicy = `-- name: DeleteTemplateAppSessionPolicy :exec
+DELETE FROM
+	template_app_session_policies
 WHERE
-	organization_id = $1
-	AND deleted = $2
-	AND LOWER("name") = LOWER($3)
-LIMIT
-	1
+	template_id = $1
+	AND app_slug = $2
 `
 
-type GetTemplateByOrganizationAndNameParams struct {
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	Deleted        bool      `db:"deleted" json:"deleted"`
-	Name           string    `db:"name" json:"name"`
+type DeleteTemplateAppSessionPolicyParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	AppSlug    string    `db:"app_slug" json:"app_slug"`
 }
 
-func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg GetTemplateByOrganizationAndNameParams) (Template, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateByOrganizationAndName, arg.OrganizationID, arg.Deleted, arg.Name)
-	var i Template
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.OrganizationID,
-		&i.Deleted,
-		&i.Name,
-		&i.Provisioner,
-		&i.ActiveVersionID,
-		&i.Description,
-		&i.DefaultTTL,
-		&i.CreatedBy,
-		&i.Icon,
-		&i.UserACL,
-		&i.GroupACL,
-		&i.DisplayName,
-		&i.AllowUserCancelWorkspaceJobs,
-		&i.AllowUserAutostart,
-		&i.AllowUserAutostop,
-		&i.FailureTTL,
-		&i.TimeTilDormant,
-		&i.TimeTilDormantAutoDelete,
-		&i.AutostopRequirementDaysOfWeek,
-		&i.AutostopRequirementWeeks,
-		&i.AutostartBlockDaysOfWeek,
-		&i.RequireActiveVersion,
-		&i.Deprecated,
-		&i.ActivityBump,
-		&i.MaxPortSharingLevel,
-		&i.UseClassicParameterFlow,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
-		&i.CreatedByName,
-		&i.OrganizationName,
-		&i.OrganizationDisplayName,
-		&i.OrganizationIcon,
-	)
-	return i, err
+func (q *sqlQuerier) DeleteTemplateAppSessionPolicy(ctx context.Context, arg DeleteTemplateAppSessionPolicyParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateAppSessionPolicy, arg.TemplateID, arg.AppSlug)
+	return err
 }
 
-const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon FROM template_with_names AS templates
-ORDER BY (name, id) ASC
+const getTemplateAppSessionPolicies = `-- name: GetTemplateAppSessionPolicies :many
+SELECT
+	template_id, app_slug, max_session_duration, created_at, updated_at
+FROM
+	template_app_session_policies
+WHERE
+	template_id = $1
+ORDER BY
+	app_slug ASC
 `
 
-func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplates)
+func (q *sqlQuerier) GetTemplateAppSessionPolicies(ctx context.Context, templateID uuid.UUID) ([]TemplateAppSessionPolicy, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateAppSessionPolicies, templateID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Template
+	var items []TemplateAppSessionPolicy
 	for rows.Next() {
-		var i Template
+		var i TemplateAppSessionPolicy
 		if err := rows.Scan(
-			&i.ID,
+			&i.TemplateID,
+			&i.AppSlug,
+			&i.MaxSessionDuration,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.OrganizationID,
-			&i.Deleted,
-			&i.Name,
-			&i.Provisioner,
-			&i.ActiveVersionID,
-			&i.Description,
-			&i.DefaultTTL,
-			&i.CreatedBy,
-			&i.Icon,
-			&i.UserACL,
-			&i.GroupACL,
-			&i.DisplayName,
-			&i.AllowUserCancelWorkspaceJobs,
-			&i.AllowUserAutostart,
-			&i.AllowUserAutostop,
-			&i.FailureTTL,
-			&i.TimeTilDormant,
-			&i.TimeTilDormantAutoDelete,
-			&i.AutostopRequirementDaysOfWeek,
-			&i.AutostopRequirementWeeks,
-			&i.AutostartBlockDaysOfWeek,
-			&i.RequireActiveVersion,
-			&i.Deprecated,
-			&i.ActivityBump,
-			&i.MaxPortSharingLevel,
-			&i.UseClassicParameterFlow,
-			&i.CreatedByAvatarURL,
-			&i.CreatedByUsername,
-			&i.CreatedByName,
-			&i.OrganizationName,
-			&i.OrganizationDisplayName,
-			&i.OrganizationIcon,
 		); err != nil {
 			return nil, err
 		}
@@ -11415,125 +14963,166 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 	return items, nil
 }
 
-const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
-SELECT
-	t.id, t.created_at, t.updated_at, t.organization_id, t.deleted, t.name, t.provisioner, t.active_version_id, t.description, t.default_ttl, t.created_by, t.icon, t.user_acl, t.group_acl, t.display_name, t.allow_user_cancel_workspace_jobs, t.allow_user_autostart, t.allow_user_autostop, t.failure_ttl, t.time_til_dormant, t.time_til_dormant_autodelete, t.autostop_requirement_days_of_week, t.autostop_requirement_weeks, t.autostart_block_days_of_week, t.require_active_version, t.deprecated, t.activity_bump, t.max_port_sharing_level, t.use_classic_parameter_flow, t.created_by_avatar_url, t.created_by_username, t.created_by_name, t.organization_name, t.organization_display_name, t.organization_icon
-FROM
-	template_with_names AS t
-LEFT JOIN
-	template_versions tv ON t.active_version_id = tv.id
-WHERE
-	-- Optionally include deleted templates
-	t.deleted = $1
-	-- Filter by organization_id
-	AND CASE
-		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
-			t.organization_id = $2
-		ELSE true
-	END
-	-- Filter by exact name
-	AND CASE
-		WHEN $3 :: text != '' THEN
-			LOWER(t.name) = LOWER($3)
-		ELSE true
-	END
-	-- Filter by name, matching on substring
-	AND CASE
-		WHEN $4 :: text != '' THEN
-			lower(t.name) ILIKE '%' || lower($4) || '%'
-		ELSE true
-	END
-	-- Filter by ids
-	AND CASE
-		WHEN array_length($5 :: uuid[], 1) > 0 THEN
-			t.id = ANY($5)
-		ELSE true
-	END
-	-- Filter by deprecated
-	AND CASE
-		WHEN $6 :: boolean IS NOT NULL THEN
-			CASE
-				WHEN $6 :: boolean THEN
-					t.deprecated != ''
-				ELSE
-					t.deprecated = ''
-			END
-		ELSE true
-	END
-	-- Filter by has_ai_task in latest version
-	AND CASE
-		WHEN $7 :: boolean IS NOT NULL THEN
-			tv.has_ai_task = $7 :: boolean
-		ELSE true
-	END
-  -- Authorize Filter clause will be injected below in GetAuthorizedTemplates
-  -- @authorize_filter
-ORDER BY (t.name, t.id) ASC
+const upsertTemplateAppSessionPolicy = `-- name: UpsertTemplateAppSessionPolicy :one
+INSERT INTO template_app_session_policies (
+	template_id,
+	app_slug,
+	max_session_duration,
+	created_at,
+	updated_at
+)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$4
+)
+ON CONFLICT (template_id, app_slug) DO UPDATE SET
+	max_session_duration = EXCLUDED.max_session_duration,
+	updated_at = EXCLUDED.updated_at
+RETURNING template_id, app_slug, max_session_duration, created_at, updated_at
 `
 
-type GetTemplatesWithFilterParams struct {
-	Deleted        bool         `db:"deleted" json:"deleted"`
-	OrganizationID uuid.UUID    `db:"organization_id" json:"organization_id"`
-	ExactName      string       `db:"exact_name" json:"exact_name"`
-	FuzzyName      string       `db:"fuzzy_name" json:"fuzzy_name"`
-	IDs            []uuid.UUID  `db:"ids" json:"ids"`
-	Deprecated     sql.NullBool `db:"deprecated" json:"deprecated"`
-	HasAITask      sql.NullBool `db:"has_ai_task" json:"has_ai_task"`
+type UpsertTemplateAppSessionPolicyParams struct {
+	TemplateID         uuid.UUID `db:"template_id" json:"template_id"`
+	AppSlug            string    `db:"app_slug" json:"app_slug"`
+	MaxSessionDuration int64     `db:"max_session_duration" json:"max_session_duration"`
+	UpdatedAt          time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplatesWithFilterParams) ([]Template, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplatesWithFilter,
-		arg.Deleted,
-		arg.OrganizationID,
-		arg.ExactName,
-		arg.FuzzyName,
-		pq.Array(arg.IDs),
-		arg.Deprecated,
-		arg.HasAITask,
+func (q *sqlQuerier) UpsertTemplateAppSessionPolicy(ctx context.Context, arg UpsertTemplateAppSessionPolicyParams) (TemplateAppSessionPolicy, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateAppSessionPolicy,
+		arg.TemplateID,
+		arg.AppSlug,
+		arg.MaxSessionDuration,
+		arg.UpdatedAt,
+	)
+	var i TemplateAppSessionPolicy
+	err := row.Scan(
+		&i.TemplateID,
+		&i.AppSlug,
+		&i.MaxSessionDuration,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteTemplateBundleByName = `-- name: DeleteTemplateBundleByName :exec
+DELETE FROM
+	template_bundles
+WHERE
+	organization_id = $1
+	AND name = $2
+	AND version = $3
+`
+
+type DeleteTemplateBundleByNameParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+	Version        string    `db:"version" json:"version"`
+}
+
+func (q *sqlQuerier) DeleteTemplateBundleByName(ctx context.Context, arg DeleteTemplateBundleByNameParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateBundleByName, arg.OrganizationID, arg.Name, arg.Version)
+	return err
+}
+
+const getTemplateBundleByName = `-- name: GetTemplateBundleByName :one
+SELECT
+	id, organization_id, name, version, hash, manifest, data, created_by, created_at
+FROM
+	template_bundles
+WHERE
+	organization_id = $1
+	AND name = $2
+	AND version = $3
+`
+
+type GetTemplateBundleByNameParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+	Version        string    `db:"version" json:"version"`
+}
+
+func (q *sqlQuerier) GetTemplateBundleByName(ctx context.Context, arg GetTemplateBundleByNameParams) (TemplateBundle, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateBundleByName, arg.OrganizationID, arg.Name, arg.Version)
+	var i TemplateBundle
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Version,
+		&i.Hash,
+		&i.Manifest,
+		&i.Data,
+		&i.CreatedBy,
+		&i.CreatedAt,
 	)
+	return i, err
+}
+
+const getTemplateBundlesByOrganizationID = `-- name: GetTemplateBundlesByOrganizationID :many
+SELECT
+	template_bundles.id,
+	template_bundles.organization_id,
+	template_bundles.name,
+	template_bundles.version,
+	template_bundles.hash,
+	template_bundles.manifest,
+	octet_length(template_bundles.data)::bigint AS size,
+	template_bundles.created_by,
+	template_bundles.created_at,
+	users.username AS created_by_username,
+	users.avatar_url AS created_by_avatar_url
+FROM
+	template_bundles
+JOIN
+	users ON users.id = template_bundles.created_by
+WHERE
+	template_bundles.organization_id = $1
+ORDER BY
+	template_bundles.name ASC,
+	template_bundles.created_at DESC
+`
+
+type GetTemplateBundlesByOrganizationIDRow struct {
+	ID                 uuid.UUID       `db:"id" json:"id"`
+	OrganizationID     uuid.UUID       `db:"organization_id" json:"organization_id"`
+	Name               string          `db:"name" json:"name"`
+	Version            string          `db:"version" json:"version"`
+	Hash               string          `db:"hash" json:"hash"`
+	Manifest           json.RawMessage `db:"manifest" json:"manifest"`
+	Size               int64           `db:"size" json:"size"`
+	CreatedBy          uuid.UUID       `db:"created_by" json:"created_by"`
+	CreatedAt          time.Time       `db:"created_at" json:"created_at"`
+	CreatedByUsername  string          `db:"created_by_username" json:"created_by_username"`
+	CreatedByAvatarURL string          `db:"created_by_avatar_url" json:"created_by_avatar_url"`
+}
+
+// Omits the data of the bundles, which is only fetched when a bundle is pulled.
+func (q *sqlQuerier) GetTemplateBundlesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]GetTemplateBundlesByOrganizationIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateBundlesByOrganizationID, organizationID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Template
+	var items []GetTemplateBundlesByOrganizationIDRow
 	for rows.Next() {
-		var i Template
+		var i GetTemplateBundlesByOrganizationIDRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
 			&i.OrganizationID,
-			&i.Deleted,
 			&i.Name,
-			&i.Provisioner,
-			&i.ActiveVersionID,
-			&i.Description,
-			&i.DefaultTTL,
+			&i.Version,
+			&i.Hash,
+			&i.Manifest,
+			&i.Size,
 			&i.CreatedBy,
-			&i.Icon,
-			&i.UserACL,
-			&i.GroupACL,
-			&i.DisplayName,
-			&i.AllowUserCancelWorkspaceJobs,
-			&i.AllowUserAutostart,
-			&i.AllowUserAutostop,
-			&i.FailureTTL,
-			&i.TimeTilDormant,
-			&i.TimeTilDormantAutoDelete,
-			&i.AutostopRequirementDaysOfWeek,
-			&i.AutostopRequirementWeeks,
-			&i.AutostartBlockDaysOfWeek,
-			&i.RequireActiveVersion,
-			&i.Deprecated,
-			&i.ActivityBump,
-			&i.MaxPortSharingLevel,
-			&i.UseClassicParameterFlow,
-			&i.CreatedByAvatarURL,
+			&i.CreatedAt,
 			&i.CreatedByUsername,
-			&i.CreatedByName,
-			&i.OrganizationName,
-			&i.OrganizationDisplayName,
-			&i.OrganizationIcon,
+			&i.CreatedByAvatarURL,
 		); err != nil {
 			return nil, err
 		}
@@ -11548,285 +15137,214 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 	return items, nil
 }
 
-const insertTemplate = `-- name: InsertTemplate :exec
-INSERT INTO
-	templates (
-		id,
-		created_at,
-		updated_at,
-		organization_id,
-		"name",
-		provisioner,
-		active_version_id,
-		description,
-		created_by,
-		icon,
-		user_acl,
-		group_acl,
-		display_name,
-		allow_user_cancel_workspace_jobs,
-		max_port_sharing_level,
-		use_classic_parameter_flow
-	)
-VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+const insertTemplateBundle = `-- name: InsertTemplateBundle :exec
+INSERT INTO template_bundles (
+	id,
+	organization_id,
+	name,
+	version,
+	hash,
+	manifest,
+	data,
+	created_by,
+	created_at
+)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7,
+	$8,
+	$9
+)
 `
 
-type InsertTemplateParams struct {
-	ID                           uuid.UUID       `db:"id" json:"id"`
-	CreatedAt                    time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt                    time.Time       `db:"updated_at" json:"updated_at"`
-	OrganizationID               uuid.UUID       `db:"organization_id" json:"organization_id"`
-	Name                         string          `db:"name" json:"name"`
-	Provisioner                  ProvisionerType `db:"provisioner" json:"provisioner"`
-	ActiveVersionID              uuid.UUID       `db:"active_version_id" json:"active_version_id"`
-	Description                  string          `db:"description" json:"description"`
-	CreatedBy                    uuid.UUID       `db:"created_by" json:"created_by"`
-	Icon                         string          `db:"icon" json:"icon"`
-	UserACL                      TemplateACL     `db:"user_acl" json:"user_acl"`
-	GroupACL                     TemplateACL     `db:"group_acl" json:"group_acl"`
-	DisplayName                  string          `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
-	MaxPortSharingLevel          AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
-	UseClassicParameterFlow      bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+type InsertTemplateBundleParams struct {
+	ID             uuid.UUID       `db:"id" json:"id"`
+	OrganizationID uuid.UUID       `db:"organization_id" json:"organization_id"`
+	Name           string          `db:"name" json:"name"`
+	Version        string          `db:"version" json:"version"`
+	Hash           string          `db:"hash" json:"hash"`
+	Manifest       json.RawMessage `db:"manifest" json:"manifest"`
+	Data           []byte          `db:"data" json:"data"`
+	CreatedBy      uuid.UUID       `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time       `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParams) error {
-	_, err := q.db.ExecContext(ctx, insertTemplate,
+func (q *sqlQuerier) InsertTemplateBundle(ctx context.Context, arg InsertTemplateBundleParams) error {
+	_, err := q.db.ExecContext(ctx, insertTemplateBundle,
 		arg.ID,
-		arg.CreatedAt,
-		arg.UpdatedAt,
 		arg.OrganizationID,
 		arg.Name,
-		arg.Provisioner,
-		arg.ActiveVersionID,
-		arg.Description,
+		arg.Version,
+		arg.Hash,
+		arg.Manifest,
+		arg.Data,
 		arg.CreatedBy,
-		arg.Icon,
-		arg.UserACL,
-		arg.GroupACL,
-		arg.DisplayName,
-		arg.AllowUserCancelWorkspaceJobs,
-		arg.MaxPortSharingLevel,
-		arg.UseClassicParameterFlow,
+		arg.CreatedAt,
 	)
 	return err
 }
 
-const updateTemplateACLByID = `-- name: UpdateTemplateACLByID :exec
-UPDATE
-	templates
-SET
-	group_acl = $1,
-	user_acl = $2
+const deleteTemplateEgressPolicy = `-- name: DeleteTemplateEgressPolicy :exec
+DELETE FROM
+	template_egress_policies
 WHERE
-	id = $3
+	template_id = $1
 `
 
-type UpdateTemplateACLByIDParams struct {
-	GroupACL TemplateACL `db:"group_acl" json:"group_acl"`
-	UserACL  TemplateACL `db:"user_acl" json:"user_acl"`
-	ID       uuid.UUID   `db:"id" json:"id"`
-}
-
-func (q *sqlQuerier) UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateACLByID, arg.GroupACL, arg.UserACL, arg.ID)
+func (q *sqlQuerier) DeleteTemplateEgressPolicy(ctx context.Context, templateID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateEgressPolicy, templateID)
 	return err
 }
 
-const updateTemplateAccessControlByID = `-- name: UpdateTemplateAccessControlByID :exec
-UPDATE
-	templates
-SET
-	require_active_version = $2,
-	deprecated = $3
+const getTemplateEgressPolicyByTemplateID = `-- name: GetTemplateEgressPolicyByTemplateID :one
+SELECT
+	template_id, allowed_cidrs, allowed_domains, created_at, updated_at
+FROM
+	template_egress_policies
 WHERE
-	id = $1
+	template_id = $1
 `
 
-type UpdateTemplateAccessControlByIDParams struct {
-	ID                   uuid.UUID `db:"id" json:"id"`
-	RequireActiveVersion bool      `db:"require_active_version" json:"require_active_version"`
-	Deprecated           string    `db:"deprecated" json:"deprecated"`
-}
-
-func (q *sqlQuerier) UpdateTemplateAccessControlByID(ctx context.Context, arg UpdateTemplateAccessControlByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateAccessControlByID, arg.ID, arg.RequireActiveVersion, arg.Deprecated)
-	return err
+func (q *sqlQuerier) GetTemplateEgressPolicyByTemplateID(ctx context.Context, templateID uuid.UUID) (TemplateEgressPolicy, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateEgressPolicyByTemplateID, templateID)
+	var i TemplateEgressPolicy
+	err := row.Scan(
+		&i.TemplateID,
+		pq.Array(&i.AllowedCidrs),
+		pq.Array(&i.AllowedDomains),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const updateTemplateActiveVersionByID = `-- name: UpdateTemplateActiveVersionByID :exec
-UPDATE
-	templates
+const upsertTemplateEgressPolicy = `-- name: UpsertTemplateEgressPolicy :one
+INSERT INTO
+	template_egress_policies (
+		template_id,
+		allowed_cidrs,
+		allowed_domains,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2 :: text[], $3 :: text[], $4, $4)
+ON CONFLICT (template_id) DO UPDATE
 SET
-	active_version_id = $2,
-	updated_at = $3
-WHERE
-	id = $1
+	allowed_cidrs = $2 :: text[],
+	allowed_domains = $3 :: text[],
+	updated_at = $4
+RETURNING template_id, allowed_cidrs, allowed_domains, created_at, updated_at
 `
 
-type UpdateTemplateActiveVersionByIDParams struct {
-	ID              uuid.UUID `db:"id" json:"id"`
-	ActiveVersionID uuid.UUID `db:"active_version_id" json:"active_version_id"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+type UpsertTemplateEgressPolicyParams struct {
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	AllowedCidrs   []string  `db:"allowed_cidrs" json:"allowed_cidrs"`
+	AllowedDomains []string  `db:"allowed_domains" json:"allowed_domains"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) UpdateTemplateActiveVersionByID(ctx context.Context, arg UpdateTemplateActiveVersionByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateActiveVersionByID, arg.ID, arg.ActiveVersionID, arg.UpdatedAt)
-	return err
+func (q *sqlQuerier) UpsertTemplateEgressPolicy(ctx context.Context, arg UpsertTemplateEgressPolicyParams) (TemplateEgressPolicy, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateEgressPolicy,
+		arg.TemplateID,
+		pq.Array(arg.AllowedCidrs),
+		pq.Array(arg.AllowedDomains),
+		arg.UpdatedAt,
+	)
+	var i TemplateEgressPolicy
+	err := row.Scan(
+		&i.TemplateID,
+		pq.Array(&i.AllowedCidrs),
+		pq.Array(&i.AllowedDomains),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const updateTemplateDeletedByID = `-- name: UpdateTemplateDeletedByID :exec
-UPDATE
-	templates
-SET
-	deleted = $2,
-	updated_at = $3
+const deleteTemplateParameterOptionSource = `-- name: DeleteTemplateParameterOptionSource :exec
+DELETE FROM
+	template_parameter_option_sources
 WHERE
-	id = $1
+	template_id = $1
+	AND parameter_name = $2
 `
 
-type UpdateTemplateDeletedByIDParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	Deleted   bool      `db:"deleted" json:"deleted"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+type DeleteTemplateParameterOptionSourceParams struct {
+	TemplateID    uuid.UUID `db:"template_id" json:"template_id"`
+	ParameterName string    `db:"parameter_name" json:"parameter_name"`
 }
 
-func (q *sqlQuerier) UpdateTemplateDeletedByID(ctx context.Context, arg UpdateTemplateDeletedByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateDeletedByID, arg.ID, arg.Deleted, arg.UpdatedAt)
+func (q *sqlQuerier) DeleteTemplateParameterOptionSource(ctx context.Context, arg DeleteTemplateParameterOptionSourceParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateParameterOptionSource, arg.TemplateID, arg.ParameterName)
 	return err
 }
 
-const updateTemplateMetaByID = `-- name: UpdateTemplateMetaByID :exec
-UPDATE
-	templates
-SET
-	updated_at = $2,
-	description = $3,
-	name = $4,
-	icon = $5,
-	display_name = $6,
-	allow_user_cancel_workspace_jobs = $7,
-	group_acl = $8,
-	max_port_sharing_level = $9,
-	use_classic_parameter_flow = $10
+const getTemplateParameterOptionSource = `-- name: GetTemplateParameterOptionSource :one
+SELECT
+	template_id, parameter_name, url, auth_header_name, auth_header_value, cache_ttl, created_at, updated_at
+FROM
+	template_parameter_option_sources
 WHERE
-	id = $1
+	template_id = $1
+	AND parameter_name = $2
 `
 
-type UpdateTemplateMetaByIDParams struct {
-	ID                           uuid.UUID       `db:"id" json:"id"`
-	UpdatedAt                    time.Time       `db:"updated_at" json:"updated_at"`
-	Description                  string          `db:"description" json:"description"`
-	Name                         string          `db:"name" json:"name"`
-	Icon                         string          `db:"icon" json:"icon"`
-	DisplayName                  string          `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
-	GroupACL                     TemplateACL     `db:"group_acl" json:"group_acl"`
-	MaxPortSharingLevel          AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
-	UseClassicParameterFlow      bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+type GetTemplateParameterOptionSourceParams struct {
+	TemplateID    uuid.UUID `db:"template_id" json:"template_id"`
+	ParameterName string    `db:"parameter_name" json:"parameter_name"`
 }
 
-func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateMetaByID,
-		arg.ID,
-		arg.UpdatedAt,
-		arg.Description,
-		arg.Name,
-		arg.Icon,
-		arg.DisplayName,
-		arg.AllowUserCancelWorkspaceJobs,
-		arg.GroupACL,
-		arg.MaxPortSharingLevel,
-		arg.UseClassicParameterFlow,
+func (q *sqlQuerier) GetTemplateParameterOptionSource(ctx context.Context, arg GetTemplateParameterOptionSourceParams) (TemplateParameterOptionSource, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateParameterOptionSource, arg.TemplateID, arg.ParameterName)
+	var i TemplateParameterOptionSource
+	err := row.Scan(
+		&i.TemplateID,
+		&i.ParameterName,
+		&i.Url,
+		&i.AuthHeaderName,
+		&i.AuthHeaderValue,
+		&i.CacheTTL,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
-	return err
+	return i, err
 }
 
-const updateTemplateScheduleByID = `-- name: UpdateTemplateScheduleByID :exec
-UPDATE
-	templates
-SET
-	updated_at = $2,
-	allow_user_autostart = $3,
-	allow_user_autostop = $4,
-	default_ttl = $5,
-	activity_bump = $6,
-	autostop_requirement_days_of_week = $7,
-	autostop_requirement_weeks = $8,
-	autostart_block_days_of_week = $9,
-	failure_ttl = $10,
-	time_til_dormant = $11,
-	time_til_dormant_autodelete = $12
+const getTemplateParameterOptionSources = `-- name: GetTemplateParameterOptionSources :many
+SELECT
+	template_id, parameter_name, url, auth_header_name, auth_header_value, cache_ttl, created_at, updated_at
+FROM
+	template_parameter_option_sources
 WHERE
-	id = $1
-`
-
-type UpdateTemplateScheduleByIDParams struct {
-	ID                            uuid.UUID `db:"id" json:"id"`
-	UpdatedAt                     time.Time `db:"updated_at" json:"updated_at"`
-	AllowUserAutostart            bool      `db:"allow_user_autostart" json:"allow_user_autostart"`
-	AllowUserAutostop             bool      `db:"allow_user_autostop" json:"allow_user_autostop"`
-	DefaultTTL                    int64     `db:"default_ttl" json:"default_ttl"`
-	ActivityBump                  int64     `db:"activity_bump" json:"activity_bump"`
-	AutostopRequirementDaysOfWeek int16     `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
-	AutostopRequirementWeeks      int64     `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
-	AutostartBlockDaysOfWeek      int16     `db:"autostart_block_days_of_week" json:"autostart_block_days_of_week"`
-	FailureTTL                    int64     `db:"failure_ttl" json:"failure_ttl"`
-	TimeTilDormant                int64     `db:"time_til_dormant" json:"time_til_dormant"`
-	TimeTilDormantAutoDelete      int64     `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
-}
-
-func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateScheduleByID,
-		arg.ID,
-		arg.UpdatedAt,
-		arg.AllowUserAutostart,
-		arg.AllowUserAutostop,
-		arg.DefaultTTL,
-		arg.ActivityBump,
-		arg.AutostopRequirementDaysOfWeek,
-		arg.AutostopRequirementWeeks,
-		arg.AutostartBlockDaysOfWeek,
-		arg.FailureTTL,
-		arg.TimeTilDormant,
-		arg.TimeTilDormantAutoDelete,
-	)
-	return err
-}
-
-const getTemplateVersionParameters = `-- name: GetTemplateVersionParameters :many
-SELECT template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral, form_type FROM template_version_parameters WHERE template_version_id = $1 ORDER BY display_order ASC, LOWER(name) ASC
+	template_id = $1
+ORDER BY
+	parameter_name ASC
 `
 
-func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionParameters, templateVersionID)
+func (q *sqlQuerier) GetTemplateParameterOptionSources(ctx context.Context, templateID uuid.UUID) ([]TemplateParameterOptionSource, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateParameterOptionSources, templateID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersionParameter
+	var items []TemplateParameterOptionSource
 	for rows.Next() {
-		var i TemplateVersionParameter
+		var i TemplateParameterOptionSource
 		if err := rows.Scan(
-			&i.TemplateVersionID,
-			&i.Name,
-			&i.Description,
-			&i.Type,
-			&i.Mutable,
-			&i.DefaultValue,
-			&i.Icon,
-			&i.Options,
-			&i.ValidationRegex,
-			&i.ValidationMin,
-			&i.ValidationMax,
-			&i.ValidationError,
-			&i.ValidationMonotonic,
-			&i.Required,
-			&i.DisplayName,
-			&i.DisplayOrder,
-			&i.Ephemeral,
-			&i.FormType,
+			&i.TemplateID,
+			&i.ParameterName,
+			&i.Url,
+			&i.AuthHeaderName,
+			&i.AuthHeaderValue,
+			&i.CacheTTL,
+			&i.CreatedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -11841,423 +15359,348 @@ func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateV
 	return items, nil
 }
 
-const insertTemplateVersionParameter = `-- name: InsertTemplateVersionParameter :one
-INSERT INTO
-    template_version_parameters (
-        template_version_id,
-        name,
-        description,
-        type,
-        form_type,
-        mutable,
-        default_value,
-        icon,
-        options,
-        validation_regex,
-        validation_min,
-        validation_max,
-        validation_error,
-        validation_monotonic,
-        required,
-        display_name,
-        display_order,
-        ephemeral
-    )
-VALUES
-    (
-        $1,
-        $2,
-        $3,
-        $4,
-        $5,
-        $6,
-        $7,
-        $8,
-        $9,
-        $10,
-        $11,
-        $12,
-        $13,
-        $14,
-        $15,
-        $16,
-        $17,
-        $18
-    ) RETURNING template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral, form_type
+const upsertTemplateParameterOptionSource = `-- name: UpsertTemplateParameterOptionSource :one
+INSERT INTO template_parameter_option_sources (
+	template_id,
+	parameter_name,
+	url,
+	auth_header_name,
+	auth_header_value,
+	cache_ttl,
+	created_at,
+	updated_at
+)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7,
+	$7
+)
+ON CONFLICT (template_id, parameter_name) DO UPDATE SET
+	url = EXCLUDED.url,
+	auth_header_name = EXCLUDED.auth_header_name,
+	auth_header_value = EXCLUDED.auth_header_value,
+	cache_ttl = EXCLUDED.cache_ttl,
+	updated_at = EXCLUDED.updated_at
+RETURNING template_id, parameter_name, url, auth_header_name, auth_header_value, cache_ttl, created_at, updated_at
 `
 
-type InsertTemplateVersionParameterParams struct {
-	TemplateVersionID   uuid.UUID         `db:"template_version_id" json:"template_version_id"`
-	Name                string            `db:"name" json:"name"`
-	Description         string            `db:"description" json:"description"`
-	Type                string            `db:"type" json:"type"`
-	FormType            ParameterFormType `db:"form_type" json:"form_type"`
-	Mutable             bool              `db:"mutable" json:"mutable"`
-	DefaultValue        string            `db:"default_value" json:"default_value"`
-	Icon                string            `db:"icon" json:"icon"`
-	Options             json.RawMessage   `db:"options" json:"options"`
-	ValidationRegex     string            `db:"validation_regex" json:"validation_regex"`
-	ValidationMin       sql.NullInt32     `db:"validation_min" json:"validation_min"`
-	ValidationMax       sql.NullInt32     `db:"validation_max" json:"validation_max"`
-	ValidationError     string            `db:"validation_error" json:"validation_error"`
-	ValidationMonotonic string            `db:"validation_monotonic" json:"validation_monotonic"`
-	Required            bool              `db:"required" json:"required"`
-	DisplayName         string            `db:"display_name" json:"display_name"`
-	DisplayOrder        int32             `db:"display_order" json:"display_order"`
-	Ephemeral           bool              `db:"ephemeral" json:"ephemeral"`
+type UpsertTemplateParameterOptionSourceParams struct {
+	TemplateID      uuid.UUID `db:"template_id" json:"template_id"`
+	ParameterName   string    `db:"parameter_name" json:"parameter_name"`
+	Url             string    `db:"url" json:"url"`
+	AuthHeaderName  string    `db:"auth_header_name" json:"auth_header_name"`
+	AuthHeaderValue string    `db:"auth_header_value" json:"auth_header_value"`
+	CacheTTL        int64     `db:"cache_ttl" json:"cache_ttl"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error) {
-	row := q.db.QueryRowContext(ctx, insertTemplateVersionParameter,
-		arg.TemplateVersionID,
-		arg.Name,
-		arg.Description,
-		arg.Type,
-		arg.FormType,
-		arg.Mutable,
-		arg.DefaultValue,
-		arg.Icon,
-		arg.Options,
-		arg.ValidationRegex,
-		arg.ValidationMin,
-		arg.ValidationMax,
-		arg.ValidationError,
-		arg.ValidationMonotonic,
-		arg.Required,
-		arg.DisplayName,
-		arg.DisplayOrder,
-		arg.Ephemeral,
+func (q *sqlQuerier) UpsertTemplateParameterOptionSource(ctx context.Context, arg UpsertTemplateParameterOptionSourceParams) (TemplateParameterOptionSource, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateParameterOptionSource,
+		arg.TemplateID,
+		arg.ParameterName,
+		arg.Url,
+		arg.AuthHeaderName,
+		arg.AuthHeaderValue,
+		arg.CacheTTL,
+		arg.UpdatedAt,
 	)
-	var i TemplateVersionParameter
+	var i TemplateParameterOptionSource
 	err := row.Scan(
-		&i.TemplateVersionID,
-		&i.Name,
-		&i.Description,
-		&i.Type,
-		&i.Mutable,
-		&i.DefaultValue,
-		&i.Icon,
-		&i.Options,
-		&i.ValidationRegex,
-		&i.ValidationMin,
-		&i.ValidationMax,
-		&i.ValidationError,
-		&i.ValidationMonotonic,
-		&i.Required,
-		&i.DisplayName,
-		&i.DisplayOrder,
-		&i.Ephemeral,
-		&i.FormType,
+		&i.TemplateID,
+		&i.ParameterName,
+		&i.Url,
+		&i.AuthHeaderName,
+		&i.AuthHeaderValue,
+		&i.CacheTTL,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const archiveUnusedTemplateVersions = `-- name: ArchiveUnusedTemplateVersions :many
-UPDATE
-	template_versions
-SET
-	archived = true,
-	updated_at = $1
+const getTemplateActivationForUpdate = `-- name: GetTemplateActivationForUpdate :one
+SELECT
+	active_version_id,
+	activation_approvals_required
 FROM
-	-- Archive all versions that are returned from this query.
-	(
-		SELECT
-			scoped_template_versions.id
-		FROM
-			-- Scope an archive to a single template and ignore already archived template versions
-			(
-				SELECT
-					id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task
-				FROM
-					template_versions
-				WHERE
-					template_versions.template_id = $2 :: uuid
-					AND
-					archived = false
-					AND
-					-- This allows archiving a specific template version.
-					CASE
-						WHEN $3::uuid  != '00000000-0000-0000-0000-000000000000'::uuid THEN
-							template_versions.id = $3 :: uuid
-						ELSE
-							true
-						END
-			) AS scoped_template_versions
-			LEFT JOIN
-				provisioner_jobs ON scoped_template_versions.job_id = provisioner_jobs.id
-			LEFT JOIN
-				templates ON scoped_template_versions.template_id = templates.id
-		WHERE
-		  -- Actively used template versions (meaning the latest build is using
-		  -- the version) are never archived. A "restart" command on the workspace,
-		  -- even if failed, would use the version. So it cannot be archived until
-		  -- the build is outdated.
-		NOT EXISTS (
-			-- Return all "used" versions, where "used" is defined as being
-			-- used by a latest workspace build.
-			SELECT template_version_id FROM (
-				SELECT
-					DISTINCT ON (workspace_id) template_version_id, transition
-				FROM
-					workspace_builds
-				ORDER BY workspace_id, build_number DESC
-				) AS used_versions
-			WHERE
-				used_versions.transition != 'delete'
-				AND
-				scoped_template_versions.id = used_versions.template_version_id
-		)
-		-- Also never archive the active template version
-		AND active_version_id != scoped_template_versions.id
-		AND CASE
-			-- Optionally, only archive versions that match a given
-			-- job status like 'failed'.
-			WHEN $4 :: provisioner_job_status IS NOT NULL THEN
-				provisioner_jobs.job_status = $4 :: provisioner_job_status
-			ELSE
-				true
-		END
-		-- Pending or running jobs should not be archived, as they are "in progress"
-		AND provisioner_jobs.job_status != 'running'
-		AND provisioner_jobs.job_status != 'pending'
-	) AS archived_versions
+	templates
 WHERE
-	template_versions.id IN (archived_versions.id)
-RETURNING template_versions.id
+	id = $1
+FOR UPDATE
 `
 
-type ArchiveUnusedTemplateVersionsParams struct {
-	UpdatedAt         time.Time                `db:"updated_at" json:"updated_at"`
-	TemplateID        uuid.UUID                `db:"template_id" json:"template_id"`
-	TemplateVersionID uuid.UUID                `db:"template_version_id" json:"template_version_id"`
-	JobStatus         NullProvisionerJobStatus `db:"job_status" json:"job_status"`
+type GetTemplateActivationForUpdateRow struct {
+	ActiveVersionID             uuid.UUID `db:"active_version_id" json:"active_version_id"`
+	ActivationApprovalsRequired int32     `db:"activation_approvals_required" json:"activation_approvals_required"`
 }
 
-// Archiving templates is a soft delete action, so is reversible.
-// Archiving prevents the version from being used and discovered
-// by listing.
-// Only unused template versions will be archived, which are any versions not
-// referenced by the latest build of a workspace.
-func (q *sqlQuerier) ArchiveUnusedTemplateVersions(ctx context.Context, arg ArchiveUnusedTemplateVersionsParams) ([]uuid.UUID, error) {
-	rows, err := q.db.QueryContext(ctx, archiveUnusedTemplateVersions,
-		arg.UpdatedAt,
-		arg.TemplateID,
-		arg.TemplateVersionID,
-		arg.JobStatus,
-	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []uuid.UUID
-	for rows.Next() {
-		var id uuid.UUID
-		if err := rows.Scan(&id); err != nil {
-			return nil, err
-		}
-		items = append(items, id)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+// Locks the template while its active version is changed, so that the
+// approvals it requires can't change until the transaction ends.
+func (q *sqlQuerier) GetTemplateActivationForUpdate(ctx context.Context, id uuid.UUID) (GetTemplateActivationForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateActivationForUpdate, id)
+	var i GetTemplateActivationForUpdateRow
+	err := row.Scan(&i.ActiveVersionID, &i.ActivationApprovalsRequired)
+	return i, err
 }
 
-const getPreviousTemplateVersion = `-- name: GetPreviousTemplateVersion :one
+const getTemplateAverageBuildTime = `-- name: GetTemplateAverageBuildTime :one
+WITH build_times AS (
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, created_by_avatar_url, created_by_username, created_by_name
+	EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at))::FLOAT AS exec_time_sec,
+	workspace_builds.transition
 FROM
-	template_version_with_user AS template_versions
+	workspace_builds
+JOIN template_versions ON
+	workspace_builds.template_version_id = template_versions.id
+JOIN provisioner_jobs pj ON
+	workspace_builds.job_id = pj.id
 WHERE
-	created_at < (
-		SELECT created_at
-		FROM template_version_with_user AS tv
-		WHERE tv.organization_id = $1 AND tv.name = $2 AND tv.template_id = $3
-	)
-	AND organization_id = $1
-	AND template_id = $3
-ORDER BY created_at DESC
-LIMIT 1
+	template_versions.template_id = $1 AND
+		(pj.completed_at IS NOT NULL) AND (pj.started_at IS NOT NULL) AND
+		(pj.started_at > $2) AND
+		(pj.canceled_at IS NULL) AND
+		((pj.error IS NULL) OR (pj.error = ''))
+ORDER BY
+	workspace_builds.created_at DESC
+)
+SELECT
+	-- Postgres offers no clear way to DRY this short of a function or other
+	-- complexities.
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_50,
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_50,
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_50,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_95,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_95,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_95
+FROM build_times
 `
 
-type GetPreviousTemplateVersionParams struct {
-	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
-	Name           string        `db:"name" json:"name"`
-	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
+type GetTemplateAverageBuildTimeParams struct {
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	StartTime  sql.NullTime  `db:"start_time" json:"start_time"`
 }
 
-func (q *sqlQuerier) GetPreviousTemplateVersion(ctx context.Context, arg GetPreviousTemplateVersionParams) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getPreviousTemplateVersion, arg.OrganizationID, arg.Name, arg.TemplateID)
-	var i TemplateVersion
+type GetTemplateAverageBuildTimeRow struct {
+	Start50  float64 `db:"start_50" json:"start_50"`
+	Stop50   float64 `db:"stop_50" json:"stop_50"`
+	Delete50 float64 `db:"delete_50" json:"delete_50"`
+	Start95  float64 `db:"start_95" json:"start_95"`
+	Stop95   float64 `db:"stop_95" json:"stop_95"`
+	Delete95 float64 `db:"delete_95" json:"delete_95"`
+}
+
+func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTemplateAverageBuildTimeParams) (GetTemplateAverageBuildTimeRow, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateAverageBuildTime, arg.TemplateID, arg.StartTime)
+	var i GetTemplateAverageBuildTimeRow
 	err := row.Scan(
-		&i.ID,
-		&i.TemplateID,
-		&i.OrganizationID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.Readme,
-		&i.JobID,
-		&i.CreatedBy,
-		&i.ExternalAuthProviders,
-		&i.Message,
-		&i.Archived,
-		&i.SourceExampleID,
-		&i.HasAITask,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
-		&i.CreatedByName,
+		&i.Start50,
+		&i.Stop50,
+		&i.Delete50,
+		&i.Start95,
+		&i.Stop95,
+		&i.Delete95,
 	)
 	return i, err
 }
 
-const getTemplateVersionByID = `-- name: GetTemplateVersionByID :one
+const getTemplateByID = `-- name: GetTemplateByID :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, created_by_avatar_url, created_by_username, created_by_name
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, wireguard_mtu, wireguard_keepalive_interval, wireguard_handshake_timeout, activation_approvals_required, autostart_holidays, autostart_holiday_calendar_url, dormant_archive_paths, parameter_validation_url, allow_start_on_connect, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
 FROM
-	template_version_with_user AS template_versions
+	template_with_names
 WHERE
 	id = $1
+LIMIT
+	1
 `
 
-func (q *sqlQuerier) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateVersionByID, id)
-	var i TemplateVersion
-	err := row.Scan(
-		&i.ID,
-		&i.TemplateID,
-		&i.OrganizationID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.Readme,
-		&i.JobID,
-		&i.CreatedBy,
-		&i.ExternalAuthProviders,
-		&i.Message,
-		&i.Archived,
-		&i.SourceExampleID,
-		&i.HasAITask,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
-		&i.CreatedByName,
-	)
-	return i, err
-}
-
-const getTemplateVersionByJobID = `-- name: GetTemplateVersionByJobID :one
-SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, created_by_avatar_url, created_by_username, created_by_name
-FROM
-	template_version_with_user AS template_versions
-WHERE
-	job_id = $1
-`
-
-func (q *sqlQuerier) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateVersionByJobID, jobID)
-	var i TemplateVersion
+func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateByID, id)
+	var i Template
 	err := row.Scan(
 		&i.ID,
-		&i.TemplateID,
-		&i.OrganizationID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.OrganizationID,
+		&i.Deleted,
 		&i.Name,
-		&i.Readme,
-		&i.JobID,
+		&i.Provisioner,
+		&i.ActiveVersionID,
+		&i.Description,
+		&i.DefaultTTL,
 		&i.CreatedBy,
-		&i.ExternalAuthProviders,
-		&i.Message,
-		&i.Archived,
-		&i.SourceExampleID,
-		&i.HasAITask,
+		&i.Icon,
+		&i.UserACL,
+		&i.GroupACL,
+		&i.DisplayName,
+		&i.AllowUserCancelWorkspaceJobs,
+		&i.AllowUserAutostart,
+		&i.AllowUserAutostop,
+		&i.FailureTTL,
+		&i.TimeTilDormant,
+		&i.TimeTilDormantAutoDelete,
+		&i.AutostopRequirementDaysOfWeek,
+		&i.AutostopRequirementWeeks,
+		&i.AutostartBlockDaysOfWeek,
+		&i.RequireActiveVersion,
+		&i.Deprecated,
+		&i.ActivityBump,
+		&i.MaxPortSharingLevel,
+		&i.UseClassicParameterFlow,
+		&i.WireguardMTU,
+		&i.WireguardKeepaliveInterval,
+		&i.WireguardHandshakeTimeout,
+		&i.ActivationApprovalsRequired,
+		pq.Array(&i.AutostartHolidays),
+		&i.AutostartHolidayCalendarURL,
+		pq.Array(&i.DormantArchivePaths),
+		&i.ParameterValidationURL,
+		&i.AllowStartOnConnect,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 		&i.CreatedByName,
+		&i.OrganizationName,
+		&i.OrganizationDisplayName,
+		&i.OrganizationIcon,
 	)
 	return i, err
 }
 
-const getTemplateVersionByTemplateIDAndName = `-- name: GetTemplateVersionByTemplateIDAndName :one
+const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, created_by_avatar_url, created_by_username, created_by_name
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, wireguard_mtu, wireguard_keepalive_interval, wireguard_handshake_timeout, activation_approvals_required, autostart_holidays, autostart_holiday_calendar_url, dormant_archive_paths, parameter_validation_url, allow_start_on_connect, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
 FROM
-	template_version_with_user AS template_versions
+	template_with_names AS templates
 WHERE
-	template_id = $1
-	AND "name" = $2
+	organization_id = $1
+	AND deleted = $2
+	AND LOWER("name") = LOWER($3)
+LIMIT
+	1
 `
 
-type GetTemplateVersionByTemplateIDAndNameParams struct {
-	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
-	Name       string        `db:"name" json:"name"`
+type GetTemplateByOrganizationAndNameParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Deleted        bool      `db:"deleted" json:"deleted"`
+	Name           string    `db:"name" json:"name"`
 }
 
-func (q *sqlQuerier) GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg GetTemplateVersionByTemplateIDAndNameParams) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateVersionByTemplateIDAndName, arg.TemplateID, arg.Name)
-	var i TemplateVersion
+func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg GetTemplateByOrganizationAndNameParams) (Template, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateByOrganizationAndName, arg.OrganizationID, arg.Deleted, arg.Name)
+	var i Template
 	err := row.Scan(
 		&i.ID,
-		&i.TemplateID,
-		&i.OrganizationID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.OrganizationID,
+		&i.Deleted,
 		&i.Name,
-		&i.Readme,
-		&i.JobID,
+		&i.Provisioner,
+		&i.ActiveVersionID,
+		&i.Description,
+		&i.DefaultTTL,
 		&i.CreatedBy,
-		&i.ExternalAuthProviders,
-		&i.Message,
-		&i.Archived,
-		&i.SourceExampleID,
-		&i.HasAITask,
+		&i.Icon,
+		&i.UserACL,
+		&i.GroupACL,
+		&i.DisplayName,
+		&i.AllowUserCancelWorkspaceJobs,
+		&i.AllowUserAutostart,
+		&i.AllowUserAutostop,
+		&i.FailureTTL,
+		&i.TimeTilDormant,
+		&i.TimeTilDormantAutoDelete,
+		&i.AutostopRequirementDaysOfWeek,
+		&i.AutostopRequirementWeeks,
+		&i.AutostartBlockDaysOfWeek,
+		&i.RequireActiveVersion,
+		&i.Deprecated,
+		&i.ActivityBump,
+		&i.MaxPortSharingLevel,
+		&i.UseClassicParameterFlow,
+		&i.WireguardMTU,
+		&i.WireguardKeepaliveInterval,
+		&i.WireguardHandshakeTimeout,
+		&i.ActivationApprovalsRequired,
+		pq.Array(&i.AutostartHolidays),
+		&i.AutostartHolidayCalendarURL,
+		pq.Array(&i.DormantArchivePaths),
+		&i.ParameterValidationURL,
+		&i.AllowStartOnConnect,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 		&i.CreatedByName,
+		&i.OrganizationName,
+		&i.OrganizationDisplayName,
+		&i.OrganizationIcon,
 	)
 	return i, err
 }
 
-const getTemplateVersionsByIDs = `-- name: GetTemplateVersionsByIDs :many
-SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, created_by_avatar_url, created_by_username, created_by_name
-FROM
-	template_version_with_user AS template_versions
-WHERE
-	id = ANY($1 :: uuid [ ])
+const getTemplates = `-- name: GetTemplates :many
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, wireguard_mtu, wireguard_keepalive_interval, wireguard_handshake_timeout, activation_approvals_required, autostart_holidays, autostart_holiday_calendar_url, dormant_archive_paths, parameter_validation_url, allow_start_on_connect, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon FROM template_with_names AS templates
+ORDER BY (name, id) ASC
 `
 
-func (q *sqlQuerier) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UUID) ([]TemplateVersion, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByIDs, pq.Array(ids))
+func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplates)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersion
+	var items []Template
 	for rows.Next() {
-		var i TemplateVersion
+		var i Template
 		if err := rows.Scan(
 			&i.ID,
-			&i.TemplateID,
-			&i.OrganizationID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
+			&i.Deleted,
 			&i.Name,
-			&i.Readme,
-			&i.JobID,
+			&i.Provisioner,
+			&i.ActiveVersionID,
+			&i.Description,
+			&i.DefaultTTL,
 			&i.CreatedBy,
-			&i.ExternalAuthProviders,
-			&i.Message,
-			&i.Archived,
-			&i.SourceExampleID,
-			&i.HasAITask,
+			&i.Icon,
+			&i.UserACL,
+			&i.GroupACL,
+			&i.DisplayName,
+			&i.AllowUserCancelWorkspaceJobs,
+			&i.AllowUserAutostart,
+			&i.AllowUserAutostop,
+			&i.FailureTTL,
+			&i.TimeTilDormant,
+			&i.TimeTilDormantAutoDelete,
+			&i.AutostopRequirementDaysOfWeek,
+			&i.AutostopRequirementWeeks,
+			&i.AutostartBlockDaysOfWeek,
+			&i.RequireActiveVersion,
+			&i.Deprecated,
+			&i.ActivityBump,
+			&i.MaxPortSharingLevel,
+			&i.UseClassicParameterFlow,
+			&i.WireguardMTU,
+			&i.WireguardKeepaliveInterval,
+			&i.WireguardHandshakeTimeout,
+			&i.ActivationApprovalsRequired,
+			pq.Array(&i.AutostartHolidays),
+			&i.AutostartHolidayCalendarURL,
+			pq.Array(&i.DormantArchivePaths),
+			&i.ParameterValidationURL,
+			&i.AllowStartOnConnect,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.CreatedByName,
+			&i.OrganizationName,
+			&i.OrganizationDisplayName,
+			&i.OrganizationIcon,
 		); err != nil {
 			return nil, err
 		}
@@ -12272,134 +15715,134 @@ func (q *sqlQuerier) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UU
 	return items, nil
 }
 
-const getTemplateVersionsByTemplateID = `-- name: GetTemplateVersionsByTemplateID :many
+const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, created_by_avatar_url, created_by_username, created_by_name
+	t.id, t.created_at, t.updated_at, t.organization_id, t.deleted, t.name, t.provisioner, t.active_version_id, t.description, t.default_ttl, t.created_by, t.icon, t.user_acl, t.group_acl, t.display_name, t.allow_user_cancel_workspace_jobs, t.allow_user_autostart, t.allow_user_autostop, t.failure_ttl, t.time_til_dormant, t.time_til_dormant_autodelete, t.autostop_requirement_days_of_week, t.autostop_requirement_weeks, t.autostart_block_days_of_week, t.require_active_version, t.deprecated, t.activity_bump, t.max_port_sharing_level, t.use_classic_parameter_flow, t.wireguard_mtu, t.wireguard_keepalive_interval, t.wireguard_handshake_timeout, t.activation_approvals_required, t.autostart_holidays, t.autostart_holiday_calendar_url, t.dormant_archive_paths, t.parameter_validation_url, t.allow_start_on_connect, t.created_by_avatar_url, t.created_by_username, t.created_by_name, t.organization_name, t.organization_display_name, t.organization_icon
 FROM
-	template_version_with_user AS template_versions
+	template_with_names AS t
+LEFT JOIN
+	template_versions tv ON t.active_version_id = tv.id
 WHERE
-	template_id = $1 :: uuid
-	  AND CASE
-		-- If no filter is provided, default to returning ALL template versions.
-		-- The called should always provide a filter if they want to omit
-		-- archived versions.
-		WHEN $2 :: boolean IS NULL THEN true
-		ELSE template_versions.archived = $2 :: boolean
+	-- Optionally include deleted templates
+	t.deleted = $1
+	-- Filter by organization_id
+	AND CASE
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			t.organization_id = $2
+		ELSE true
 	END
+	-- Filter by exact name
 	AND CASE
-		-- This allows using the last element on a page as effectively a cursor.
-		-- This is an important option for scripts that need to paginate without
-		-- duplicating or missing data.
-		WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
-			-- The pagination cursor is the last ID of the previous page.
-			-- The query is ordered by the created_at field, so select all
-			-- rows after the cursor.
-			(created_at, id) > (
-				SELECT
-					created_at, id
-				FROM
-					template_versions
-				WHERE
-					id = $3
-			)
-		)
+		WHEN $3 :: text != '' THEN
+			LOWER(t.name) = LOWER($3)
 		ELSE true
 	END
-ORDER BY
-    -- Deterministic and consistent ordering of all rows, even if they share
-    -- a timestamp. This is to ensure consistent pagination.
-	(created_at, id) ASC OFFSET $4
-LIMIT
-	-- A null limit means "no limit", so 0 means return all
-	NULLIF($5 :: int, 0)
-`
-
-type GetTemplateVersionsByTemplateIDParams struct {
-	TemplateID uuid.UUID    `db:"template_id" json:"template_id"`
-	Archived   sql.NullBool `db:"archived" json:"archived"`
-	AfterID    uuid.UUID    `db:"after_id" json:"after_id"`
-	OffsetOpt  int32        `db:"offset_opt" json:"offset_opt"`
-	LimitOpt   int32        `db:"limit_opt" json:"limit_opt"`
-}
-
-func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg GetTemplateVersionsByTemplateIDParams) ([]TemplateVersion, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByTemplateID,
-		arg.TemplateID,
-		arg.Archived,
-		arg.AfterID,
-		arg.OffsetOpt,
-		arg.LimitOpt,
-	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []TemplateVersion
-	for rows.Next() {
-		var i TemplateVersion
-		if err := rows.Scan(
-			&i.ID,
-			&i.TemplateID,
-			&i.OrganizationID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Name,
-			&i.Readme,
-			&i.JobID,
-			&i.CreatedBy,
-			&i.ExternalAuthProviders,
-			&i.Message,
-			&i.Archived,
-			&i.SourceExampleID,
-			&i.HasAITask,
-			&i.CreatedByAvatarURL,
-			&i.CreatedByUsername,
-			&i.CreatedByName,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const getTemplateVersionsCreatedAfter = `-- name: GetTemplateVersionsCreatedAfter :many
-SELECT id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, created_by_avatar_url, created_by_username, created_by_name FROM template_version_with_user AS template_versions WHERE created_at > $1
+	-- Filter by name, matching on substring
+	AND CASE
+		WHEN $4 :: text != '' THEN
+			lower(t.name) ILIKE '%' || lower($4) || '%'
+		ELSE true
+	END
+	-- Filter by ids
+	AND CASE
+		WHEN array_length($5 :: uuid[], 1) > 0 THEN
+			t.id = ANY($5)
+		ELSE true
+	END
+	-- Filter by deprecated
+	AND CASE
+		WHEN $6 :: boolean IS NOT NULL THEN
+			CASE
+				WHEN $6 :: boolean THEN
+					t.deprecated != ''
+				ELSE
+					t.deprecated = ''
+			END
+		ELSE true
+	END
+	-- Filter by has_ai_task in latest version
+	AND CASE
+		WHEN $7 :: boolean IS NOT NULL THEN
+			tv.has_ai_task = $7 :: boolean
+		ELSE true
+	END
+  -- Authorize Filter clause will be injected below in GetAuthorizedTemplates
+  -- @authorize_filter
+ORDER BY (t.name, t.id) ASC
 `
 
-func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]TemplateVersion, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionsCreatedAfter, createdAt)
+type GetTemplatesWithFilterParams struct {
+	Deleted        bool         `db:"deleted" json:"deleted"`
+	OrganizationID uuid.UUID    `db:"organization_id" json:"organization_id"`
+	ExactName      string       `db:"exact_name" json:"exact_name"`
+	FuzzyName      string       `db:"fuzzy_name" json:"fuzzy_name"`
+	IDs            []uuid.UUID  `db:"ids" json:"ids"`
+	Deprecated     sql.NullBool `db:"deprecated" json:"deprecated"`
+	HasAITask      sql.NullBool `db:"has_ai_task" json:"has_ai_task"`
+}
+
+func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplatesWithFilterParams) ([]Template, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplatesWithFilter,
+		arg.Deleted,
+		arg.OrganizationID,
+		arg.ExactName,
+		arg.FuzzyName,
+		pq.Array(arg.IDs),
+		arg.Deprecated,
+		arg.HasAITask,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersion
+	var items []Template
 	for rows.Next() {
-		var i TemplateVersion
+		var i Template
 		if err := rows.Scan(
 			&i.ID,
-			&i.TemplateID,
-			&i.OrganizationID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
+			&i.Deleted,
 			&i.Name,
-			&i.Readme,
-			&i.JobID,
+			&i.Provisioner,
+			&i.ActiveVersionID,
+			&i.Description,
+			&i.DefaultTTL,
 			&i.CreatedBy,
-			&i.ExternalAuthProviders,
-			&i.Message,
-			&i.Archived,
-			&i.SourceExampleID,
-			&i.HasAITask,
+			&i.Icon,
+			&i.UserACL,
+			&i.GroupACL,
+			&i.DisplayName,
+			&i.AllowUserCancelWorkspaceJobs,
+			&i.AllowUserAutostart,
+			&i.AllowUserAutostop,
+			&i.FailureTTL,
+			&i.TimeTilDormant,
+			&i.TimeTilDormantAutoDelete,
+			&i.AutostopRequirementDaysOfWeek,
+			&i.AutostopRequirementWeeks,
+			&i.AutostartBlockDaysOfWeek,
+			&i.RequireActiveVersion,
+			&i.Deprecated,
+			&i.ActivityBump,
+			&i.MaxPortSharingLevel,
+			&i.UseClassicParameterFlow,
+			&i.WireguardMTU,
+			&i.WireguardKeepaliveInterval,
+			&i.WireguardHandshakeTimeout,
+			&i.ActivationApprovalsRequired,
+			pq.Array(&i.AutostartHolidays),
+			&i.AutostartHolidayCalendarURL,
+			pq.Array(&i.DormantArchivePaths),
+			&i.ParameterValidationURL,
+			&i.AllowStartOnConnect,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.CreatedByName,
+			&i.OrganizationName,
+			&i.OrganizationDisplayName,
+			&i.OrganizationIcon,
 		); err != nil {
 			return nil, err
 		}
@@ -12414,265 +15857,410 @@ func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, create
 	return items, nil
 }
 
-const hasTemplateVersionsWithAITask = `-- name: HasTemplateVersionsWithAITask :one
-SELECT EXISTS (SELECT 1 FROM template_versions WHERE has_ai_task = TRUE)
-`
-
-// Determines if the template versions table has any rows with has_ai_task = TRUE.
-func (q *sqlQuerier) HasTemplateVersionsWithAITask(ctx context.Context) (bool, error) {
-	row := q.db.QueryRowContext(ctx, hasTemplateVersionsWithAITask)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
-}
-
-const insertTemplateVersion = `-- name: InsertTemplateVersion :exec
+const insertTemplate = `-- name: InsertTemplate :exec
 INSERT INTO
-	template_versions (
+	templates (
 		id,
-		template_id,
-		organization_id,
 		created_at,
 		updated_at,
+		organization_id,
 		"name",
-		message,
-		readme,
-		job_id,
+		provisioner,
+		active_version_id,
+		description,
 		created_by,
-		source_example_id
+		icon,
+		user_acl,
+		group_acl,
+		display_name,
+		allow_user_cancel_workspace_jobs,
+		max_port_sharing_level,
+		use_classic_parameter_flow
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 `
 
-type InsertTemplateVersionParams struct {
-	ID              uuid.UUID      `db:"id" json:"id"`
-	TemplateID      uuid.NullUUID  `db:"template_id" json:"template_id"`
-	OrganizationID  uuid.UUID      `db:"organization_id" json:"organization_id"`
-	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
-	Name            string         `db:"name" json:"name"`
-	Message         string         `db:"message" json:"message"`
-	Readme          string         `db:"readme" json:"readme"`
-	JobID           uuid.UUID      `db:"job_id" json:"job_id"`
-	CreatedBy       uuid.UUID      `db:"created_by" json:"created_by"`
-	SourceExampleID sql.NullString `db:"source_example_id" json:"source_example_id"`
+type InsertTemplateParams struct {
+	ID                           uuid.UUID       `db:"id" json:"id"`
+	CreatedAt                    time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt                    time.Time       `db:"updated_at" json:"updated_at"`
+	OrganizationID               uuid.UUID       `db:"organization_id" json:"organization_id"`
+	Name                         string          `db:"name" json:"name"`
+	Provisioner                  ProvisionerType `db:"provisioner" json:"provisioner"`
+	ActiveVersionID              uuid.UUID       `db:"active_version_id" json:"active_version_id"`
+	Description                  string          `db:"description" json:"description"`
+	CreatedBy                    uuid.UUID       `db:"created_by" json:"created_by"`
+	Icon                         string          `db:"icon" json:"icon"`
+	UserACL                      TemplateACL     `db:"user_acl" json:"user_acl"`
+	GroupACL                     TemplateACL     `db:"group_acl" json:"group_acl"`
+	DisplayName                  string          `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	MaxPortSharingLevel          AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	UseClassicParameterFlow      bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
 }
 
-func (q *sqlQuerier) InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error {
-	_, err := q.db.ExecContext(ctx, insertTemplateVersion,
+func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParams) error {
+	_, err := q.db.ExecContext(ctx, insertTemplate,
 		arg.ID,
-		arg.TemplateID,
-		arg.OrganizationID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.OrganizationID,
 		arg.Name,
-		arg.Message,
-		arg.Readme,
-		arg.JobID,
+		arg.Provisioner,
+		arg.ActiveVersionID,
+		arg.Description,
 		arg.CreatedBy,
-		arg.SourceExampleID,
+		arg.Icon,
+		arg.UserACL,
+		arg.GroupACL,
+		arg.DisplayName,
+		arg.AllowUserCancelWorkspaceJobs,
+		arg.MaxPortSharingLevel,
+		arg.UseClassicParameterFlow,
 	)
 	return err
 }
 
-const unarchiveTemplateVersion = `-- name: UnarchiveTemplateVersion :exec
+const updateTemplateACLByID = `-- name: UpdateTemplateACLByID :exec
 UPDATE
-	template_versions
+	templates
 SET
-	archived = false,
-	updated_at = $1
+	group_acl = $1,
+	user_acl = $2
 WHERE
-		id = $2
+	id = $3
 `
 
-type UnarchiveTemplateVersionParams struct {
-	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
-	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+type UpdateTemplateACLByIDParams struct {
+	GroupACL TemplateACL `db:"group_acl" json:"group_acl"`
+	UserACL  TemplateACL `db:"user_acl" json:"user_acl"`
+	ID       uuid.UUID   `db:"id" json:"id"`
 }
 
-// This will always work regardless of the current state of the template version.
-func (q *sqlQuerier) UnarchiveTemplateVersion(ctx context.Context, arg UnarchiveTemplateVersionParams) error {
-	_, err := q.db.ExecContext(ctx, unarchiveTemplateVersion, arg.UpdatedAt, arg.TemplateVersionID)
+func (q *sqlQuerier) UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateACLByID, arg.GroupACL, arg.UserACL, arg.ID)
 	return err
 }
 
-const updateTemplateVersionAITaskByJobID = `-- name: UpdateTemplateVersionAITaskByJobID :exec
+const updateTemplateAccessControlByID = `-- name: UpdateTemplateAccessControlByID :exec
 UPDATE
-	template_versions
+	templates
 SET
-	has_ai_task = $2,
-	updated_at = $3
+	require_active_version = $2,
+	deprecated = $3
 WHERE
-	job_id = $1
+	id = $1
 `
 
-type UpdateTemplateVersionAITaskByJobIDParams struct {
-	JobID     uuid.UUID    `db:"job_id" json:"job_id"`
-	HasAITask sql.NullBool `db:"has_ai_task" json:"has_ai_task"`
-	UpdatedAt time.Time    `db:"updated_at" json:"updated_at"`
+type UpdateTemplateAccessControlByIDParams struct {
+	ID                   uuid.UUID `db:"id" json:"id"`
+	RequireActiveVersion bool      `db:"require_active_version" json:"require_active_version"`
+	Deprecated           string    `db:"deprecated" json:"deprecated"`
 }
 
-func (q *sqlQuerier) UpdateTemplateVersionAITaskByJobID(ctx context.Context, arg UpdateTemplateVersionAITaskByJobIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionAITaskByJobID, arg.JobID, arg.HasAITask, arg.UpdatedAt)
+func (q *sqlQuerier) UpdateTemplateAccessControlByID(ctx context.Context, arg UpdateTemplateAccessControlByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateAccessControlByID, arg.ID, arg.RequireActiveVersion, arg.Deprecated)
 	return err
 }
 
-const updateTemplateVersionByID = `-- name: UpdateTemplateVersionByID :exec
+const updateTemplateActiveVersionByID = `-- name: UpdateTemplateActiveVersionByID :exec
 UPDATE
-	template_versions
+	templates
 SET
-	template_id = $2,
-	updated_at = $3,
-	name = $4,
-	message = $5
+	active_version_id = $2,
+	updated_at = $3
 WHERE
 	id = $1
 `
 
-type UpdateTemplateVersionByIDParams struct {
-	ID         uuid.UUID     `db:"id" json:"id"`
-	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
-	UpdatedAt  time.Time     `db:"updated_at" json:"updated_at"`
-	Name       string        `db:"name" json:"name"`
-	Message    string        `db:"message" json:"message"`
+type UpdateTemplateActiveVersionByIDParams struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	ActiveVersionID uuid.UUID `db:"active_version_id" json:"active_version_id"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionByID,
-		arg.ID,
-		arg.TemplateID,
-		arg.UpdatedAt,
-		arg.Name,
-		arg.Message,
-	)
+func (q *sqlQuerier) UpdateTemplateActiveVersionByID(ctx context.Context, arg UpdateTemplateActiveVersionByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateActiveVersionByID, arg.ID, arg.ActiveVersionID, arg.UpdatedAt)
 	return err
 }
 
-const updateTemplateVersionDescriptionByJobID = `-- name: UpdateTemplateVersionDescriptionByJobID :exec
+const updateTemplateDeletedByID = `-- name: UpdateTemplateDeletedByID :exec
 UPDATE
-	template_versions
+	templates
 SET
-	readme = $2,
+	deleted = $2,
 	updated_at = $3
 WHERE
-	job_id = $1
+	id = $1
 `
 
-type UpdateTemplateVersionDescriptionByJobIDParams struct {
-	JobID     uuid.UUID `db:"job_id" json:"job_id"`
-	Readme    string    `db:"readme" json:"readme"`
+type UpdateTemplateDeletedByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Deleted   bool      `db:"deleted" json:"deleted"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionDescriptionByJobID, arg.JobID, arg.Readme, arg.UpdatedAt)
+func (q *sqlQuerier) UpdateTemplateDeletedByID(ctx context.Context, arg UpdateTemplateDeletedByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateDeletedByID, arg.ID, arg.Deleted, arg.UpdatedAt)
 	return err
 }
 
-const updateTemplateVersionExternalAuthProvidersByJobID = `-- name: UpdateTemplateVersionExternalAuthProvidersByJobID :exec
+const updateTemplateMetaByID = `-- name: UpdateTemplateMetaByID :exec
 UPDATE
-	template_versions
+	templates
 SET
-	external_auth_providers = $2,
-	updated_at = $3
+	updated_at = $2,
+	description = $3,
+	name = $4,
+	icon = $5,
+	display_name = $6,
+	allow_user_cancel_workspace_jobs = $7,
+	group_acl = $8,
+	max_port_sharing_level = $9,
+	use_classic_parameter_flow = $10,
+	wireguard_mtu = $11,
+	wireguard_keepalive_interval = $12,
+	wireguard_handshake_timeout = $13,
+	activation_approvals_required = $14,
+	autostart_holidays = $15,
+	autostart_holiday_calendar_url = $16,
+	dormant_archive_paths = $17,
+	parameter_validation_url = $18,
+	allow_start_on_connect = $19
 WHERE
-	job_id = $1
+	id = $1
 `
 
-type UpdateTemplateVersionExternalAuthProvidersByJobIDParams struct {
-	JobID                 uuid.UUID       `db:"job_id" json:"job_id"`
-	ExternalAuthProviders json.RawMessage `db:"external_auth_providers" json:"external_auth_providers"`
-	UpdatedAt             time.Time       `db:"updated_at" json:"updated_at"`
+type UpdateTemplateMetaByIDParams struct {
+	ID                           uuid.UUID       `db:"id" json:"id"`
+	UpdatedAt                    time.Time       `db:"updated_at" json:"updated_at"`
+	Description                  string          `db:"description" json:"description"`
+	Name                         string          `db:"name" json:"name"`
+	Icon                         string          `db:"icon" json:"icon"`
+	DisplayName                  string          `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	GroupACL                     TemplateACL     `db:"group_acl" json:"group_acl"`
+	MaxPortSharingLevel          AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	UseClassicParameterFlow      bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	WireguardMTU                 int32           `db:"wireguard_mtu" json:"wireguard_mtu"`
+	WireguardKeepaliveInterval   int64           `db:"wireguard_keepalive_interval" json:"wireguard_keepalive_interval"`
+	WireguardHandshakeTimeout    int64           `db:"wireguard_handshake_timeout" json:"wireguard_handshake_timeout"`
+	ActivationApprovalsRequired  int32           `db:"activation_approvals_required" json:"activation_approvals_required"`
+	AutostartHolidays            []string        `db:"autostart_holidays" json:"autostart_holidays"`
+	AutostartHolidayCalendarURL  string          `db:"autostart_holiday_calendar_url" json:"autostart_holiday_calendar_url"`
+	DormantArchivePaths          []string        `db:"dormant_archive_paths" json:"dormant_archive_paths"`
+	ParameterValidationURL       string          `db:"parameter_validation_url" json:"parameter_validation_url"`
+	AllowStartOnConnect          bool            `db:"allow_start_on_connect" json:"allow_start_on_connect"`
 }
 
-func (q *sqlQuerier) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.Context, arg UpdateTemplateVersionExternalAuthProvidersByJobIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionExternalAuthProvidersByJobID, arg.JobID, arg.ExternalAuthProviders, arg.UpdatedAt)
+func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateMetaByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.Description,
+		arg.Name,
+		arg.Icon,
+		arg.DisplayName,
+		arg.AllowUserCancelWorkspaceJobs,
+		arg.GroupACL,
+		arg.MaxPortSharingLevel,
+		arg.UseClassicParameterFlow,
+		arg.WireguardMTU,
+		arg.WireguardKeepaliveInterval,
+		arg.WireguardHandshakeTimeout,
+		arg.ActivationApprovalsRequired,
+		pq.Array(arg.AutostartHolidays),
+		arg.AutostartHolidayCalendarURL,
+		pq.Array(arg.DormantArchivePaths),
+		arg.ParameterValidationURL,
+		arg.AllowStartOnConnect,
+	)
 	return err
 }
 
-const getTemplateVersionTerraformValues = `-- name: GetTemplateVersionTerraformValues :one
-SELECT
-	template_version_terraform_values.template_version_id, template_version_terraform_values.updated_at, template_version_terraform_values.cached_plan, template_version_terraform_values.cached_module_files, template_version_terraform_values.provisionerd_version
-FROM
-	template_version_terraform_values
+const updateTemplateOrganizationByID = `-- name: UpdateTemplateOrganizationByID :exec
+WITH versions AS (
+	UPDATE
+		template_versions
+	SET
+		organization_id = $1,
+		updated_at = $2
+	WHERE
+		template_id = $3
+	RETURNING
+		job_id
+), jobs AS (
+	UPDATE
+		provisioner_jobs
+	SET
+		organization_id = $1,
+		updated_at = $2
+	WHERE
+		id IN (SELECT job_id FROM versions)
+)
+UPDATE
+	templates
+SET
+	organization_id = $1,
+	group_acl = $4,
+	user_acl = $5,
+	updated_at = $2
 WHERE
-	template_version_terraform_values.template_version_id = $1
+	id = $3
 `
 
-func (q *sqlQuerier) GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (TemplateVersionTerraformValue, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateVersionTerraformValues, templateVersionID)
-	var i TemplateVersionTerraformValue
-	err := row.Scan(
-		&i.TemplateVersionID,
-		&i.UpdatedAt,
-		&i.CachedPlan,
-		&i.CachedModuleFiles,
-		&i.ProvisionerdVersion,
-	)
-	return i, err
+type UpdateTemplateOrganizationByIDParams struct {
+	OrganizationID uuid.UUID   `db:"organization_id" json:"organization_id"`
+	UpdatedAt      time.Time   `db:"updated_at" json:"updated_at"`
+	ID             uuid.UUID   `db:"id" json:"id"`
+	GroupACL       TemplateACL `db:"group_acl" json:"group_acl"`
+	UserACL        TemplateACL `db:"user_acl" json:"user_acl"`
 }
 
-const insertTemplateVersionTerraformValuesByJobID = `-- name: InsertTemplateVersionTerraformValuesByJobID :exec
-INSERT INTO
-	template_version_terraform_values (
-		template_version_id,
-		cached_plan,
-		cached_module_files,
-		updated_at,
-	    provisionerd_version
-	)
-VALUES
-	(
-		(select id from template_versions where job_id = $1),
-		$2,
-		$3,
-		$4,
-		$5
+// Moves a template, its versions and the provisioner jobs that imported the
+// versions to another organization.
+func (q *sqlQuerier) UpdateTemplateOrganizationByID(ctx context.Context, arg UpdateTemplateOrganizationByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateOrganizationByID,
+		arg.OrganizationID,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.GroupACL,
+		arg.UserACL,
 	)
+	return err
+}
+
+const updateTemplateScheduleByID = `-- name: UpdateTemplateScheduleByID :exec
+UPDATE
+	templates
+SET
+	updated_at = $2,
+	allow_user_autostart = $3,
+	allow_user_autostop = $4,
+	default_ttl = $5,
+	activity_bump = $6,
+	autostop_requirement_days_of_week = $7,
+	autostop_requirement_weeks = $8,
+	autostart_block_days_of_week = $9,
+	failure_ttl = $10,
+	time_til_dormant = $11,
+	time_til_dormant_autodelete = $12
+WHERE
+	id = $1
 `
 
-type InsertTemplateVersionTerraformValuesByJobIDParams struct {
-	JobID               uuid.UUID       `db:"job_id" json:"job_id"`
-	CachedPlan          json.RawMessage `db:"cached_plan" json:"cached_plan"`
-	CachedModuleFiles   uuid.NullUUID   `db:"cached_module_files" json:"cached_module_files"`
-	UpdatedAt           time.Time       `db:"updated_at" json:"updated_at"`
-	ProvisionerdVersion string          `db:"provisionerd_version" json:"provisionerd_version"`
+type UpdateTemplateScheduleByIDParams struct {
+	ID                            uuid.UUID `db:"id" json:"id"`
+	UpdatedAt                     time.Time `db:"updated_at" json:"updated_at"`
+	AllowUserAutostart            bool      `db:"allow_user_autostart" json:"allow_user_autostart"`
+	AllowUserAutostop             bool      `db:"allow_user_autostop" json:"allow_user_autostop"`
+	DefaultTTL                    int64     `db:"default_ttl" json:"default_ttl"`
+	ActivityBump                  int64     `db:"activity_bump" json:"activity_bump"`
+	AutostopRequirementDaysOfWeek int16     `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
+	AutostopRequirementWeeks      int64     `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
+	AutostartBlockDaysOfWeek      int16     `db:"autostart_block_days_of_week" json:"autostart_block_days_of_week"`
+	FailureTTL                    int64     `db:"failure_ttl" json:"failure_ttl"`
+	TimeTilDormant                int64     `db:"time_til_dormant" json:"time_til_dormant"`
+	TimeTilDormantAutoDelete      int64     `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
 }
 
-func (q *sqlQuerier) InsertTemplateVersionTerraformValuesByJobID(ctx context.Context, arg InsertTemplateVersionTerraformValuesByJobIDParams) error {
-	_, err := q.db.ExecContext(ctx, insertTemplateVersionTerraformValuesByJobID,
-		arg.JobID,
-		arg.CachedPlan,
-		arg.CachedModuleFiles,
+func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateScheduleByID,
+		arg.ID,
 		arg.UpdatedAt,
-		arg.ProvisionerdVersion,
+		arg.AllowUserAutostart,
+		arg.AllowUserAutostop,
+		arg.DefaultTTL,
+		arg.ActivityBump,
+		arg.AutostopRequirementDaysOfWeek,
+		arg.AutostopRequirementWeeks,
+		arg.AutostartBlockDaysOfWeek,
+		arg.FailureTTL,
+		arg.TimeTilDormant,
+		arg.TimeTilDormantAutoDelete,
 	)
 	return err
 }
 
-const getTemplateVersionVariables = `-- name: GetTemplateVersionVariables :many
-SELECT template_version_id, name, description, type, value, default_value, required, sensitive FROM template_version_variables WHERE template_version_id = $1
+const deleteTemplateSecret = `-- name: DeleteTemplateSecret :exec
+DELETE FROM
+	template_secrets
+WHERE
+	template_id = $1
+	AND name = $2
 `
 
-func (q *sqlQuerier) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionVariables, templateVersionID)
+type DeleteTemplateSecretParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	Name       string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) DeleteTemplateSecret(ctx context.Context, arg DeleteTemplateSecretParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateSecret, arg.TemplateID, arg.Name)
+	return err
+}
+
+const getTemplateSecretByTemplateIDAndName = `-- name: GetTemplateSecretByTemplateIDAndName :one
+SELECT
+	id, template_id, name, description, value, value_key_id, created_at, updated_at
+FROM
+	template_secrets
+WHERE
+	template_id = $1
+	AND name = $2
+`
+
+type GetTemplateSecretByTemplateIDAndNameParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	Name       string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetTemplateSecretByTemplateIDAndName(ctx context.Context, arg GetTemplateSecretByTemplateIDAndNameParams) (TemplateSecret, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateSecretByTemplateIDAndName, arg.TemplateID, arg.Name)
+	var i TemplateSecret
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTemplateSecretsByTemplateID = `-- name: GetTemplateSecretsByTemplateID :many
+SELECT
+	id, template_id, name, description, value, value_key_id, created_at, updated_at
+FROM
+	template_secrets
+WHERE
+	template_id = $1
+ORDER BY
+	name ASC
+`
+
+func (q *sqlQuerier) GetTemplateSecretsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateSecret, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateSecretsByTemplateID, templateID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersionVariable
+	var items []TemplateSecret
 	for rows.Next() {
-		var i TemplateVersionVariable
+		var i TemplateSecret
 		if err := rows.Scan(
-			&i.TemplateVersionID,
+			&i.ID,
+			&i.TemplateID,
 			&i.Name,
 			&i.Description,
-			&i.Type,
 			&i.Value,
-			&i.DefaultValue,
-			&i.Required,
-			&i.Sensitive,
+			&i.ValueKeyID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -12687,233 +16275,181 @@ func (q *sqlQuerier) GetTemplateVersionVariables(ctx context.Context, templateVe
 	return items, nil
 }
 
-const insertTemplateVersionVariable = `-- name: InsertTemplateVersionVariable :one
+const insertTemplateSecret = `-- name: InsertTemplateSecret :one
 INSERT INTO
-    template_version_variables (
-        template_version_id,
-        name,
-        description,
-        type,
-        value,
-        default_value,
-        required,
-        sensitive
-    )
+	template_secrets (
+		id,
+		template_id,
+		name,
+		description,
+		value,
+		value_key_id,
+		created_at,
+		updated_at
+	)
 VALUES
-    (
-        $1,
-        $2,
-        $3,
-        $4,
-        $5,
-        $6,
-        $7,
-        $8
-    ) RETURNING template_version_id, name, description, type, value, default_value, required, sensitive
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, template_id, name, description, value, value_key_id, created_at, updated_at
 `
 
-type InsertTemplateVersionVariableParams struct {
-	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
-	Name              string    `db:"name" json:"name"`
-	Description       string    `db:"description" json:"description"`
-	Type              string    `db:"type" json:"type"`
-	Value             string    `db:"value" json:"value"`
-	DefaultValue      string    `db:"default_value" json:"default_value"`
-	Required          bool      `db:"required" json:"required"`
-	Sensitive         bool      `db:"sensitive" json:"sensitive"`
+type InsertTemplateSecretParams struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	TemplateID  uuid.UUID      `db:"template_id" json:"template_id"`
+	Name        string         `db:"name" json:"name"`
+	Description string         `db:"description" json:"description"`
+	Value       string         `db:"value" json:"value"`
+	ValueKeyID  sql.NullString `db:"value_key_id" json:"value_key_id"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error) {
-	row := q.db.QueryRowContext(ctx, insertTemplateVersionVariable,
-		arg.TemplateVersionID,
+func (q *sqlQuerier) InsertTemplateSecret(ctx context.Context, arg InsertTemplateSecretParams) (TemplateSecret, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateSecret,
+		arg.ID,
+		arg.TemplateID,
 		arg.Name,
 		arg.Description,
-		arg.Type,
 		arg.Value,
-		arg.DefaultValue,
-		arg.Required,
-		arg.Sensitive,
+		arg.ValueKeyID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
 	)
-	var i TemplateVersionVariable
+	var i TemplateSecret
 	err := row.Scan(
-		&i.TemplateVersionID,
+		&i.ID,
+		&i.TemplateID,
 		&i.Name,
 		&i.Description,
-		&i.Type,
 		&i.Value,
-		&i.DefaultValue,
-		&i.Required,
-		&i.Sensitive,
+		&i.ValueKeyID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getTemplateVersionWorkspaceTags = `-- name: GetTemplateVersionWorkspaceTags :many
-SELECT template_version_id, key, value FROM template_version_workspace_tags WHERE template_version_id = $1 ORDER BY LOWER(key) ASC
-`
-
-func (q *sqlQuerier) GetTemplateVersionWorkspaceTags(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionWorkspaceTag, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionWorkspaceTags, templateVersionID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []TemplateVersionWorkspaceTag
-	for rows.Next() {
-		var i TemplateVersionWorkspaceTag
-		if err := rows.Scan(&i.TemplateVersionID, &i.Key, &i.Value); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const insertTemplateVersionWorkspaceTag = `-- name: InsertTemplateVersionWorkspaceTag :one
-INSERT INTO
-    template_version_workspace_tags (
-        template_version_id,
-        key,
-        value
-    )
-VALUES
-    (
-        $1,
-        $2,
-        $3
-    ) RETURNING template_version_id, key, value
+const updateTemplateSecret = `-- name: UpdateTemplateSecret :one
+UPDATE
+	template_secrets
+SET
+	description = $1,
+	value = $2,
+	value_key_id = $3,
+	updated_at = $4
+WHERE
+	template_id = $5
+	AND name = $6
+RETURNING id, template_id, name, description, value, value_key_id, created_at, updated_at
 `
 
-type InsertTemplateVersionWorkspaceTagParams struct {
-	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
-	Key               string    `db:"key" json:"key"`
-	Value             string    `db:"value" json:"value"`
+type UpdateTemplateSecretParams struct {
+	Description string         `db:"description" json:"description"`
+	Value       string         `db:"value" json:"value"`
+	ValueKeyID  sql.NullString `db:"value_key_id" json:"value_key_id"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	TemplateID  uuid.UUID      `db:"template_id" json:"template_id"`
+	Name        string         `db:"name" json:"name"`
 }
 
-func (q *sqlQuerier) InsertTemplateVersionWorkspaceTag(ctx context.Context, arg InsertTemplateVersionWorkspaceTagParams) (TemplateVersionWorkspaceTag, error) {
-	row := q.db.QueryRowContext(ctx, insertTemplateVersionWorkspaceTag, arg.TemplateVersionID, arg.Key, arg.Value)
-	var i TemplateVersionWorkspaceTag
-	err := row.Scan(&i.TemplateVersionID, &i.Key, &i.Value)
+func (q *sqlQuerier) UpdateTemplateSecret(ctx context.Context, arg UpdateTemplateSecretParams) (TemplateSecret, error) {
+	row := q.db.QueryRowContext(ctx, updateTemplateSecret,
+		arg.Description,
+		arg.Value,
+		arg.ValueKeyID,
+		arg.UpdatedAt,
+		arg.TemplateID,
+		arg.Name,
+	)
+	var i TemplateSecret
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
 	return i, err
 }
 
-const disableForeignKeysAndTriggers = `-- name: DisableForeignKeysAndTriggers :exec
-DO $$
-DECLARE
-    table_record record;
-BEGIN
-    FOR table_record IN 
-        SELECT table_schema, table_name 
-        FROM information_schema.tables 
-        WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
-        AND table_type = 'BASE TABLE'
-    LOOP
-        EXECUTE format('ALTER TABLE %I.%I DISABLE TRIGGER ALL', 
-                    table_record.table_schema, 
-                    table_record.table_name);
-    END LOOP;
-END;
-$$
+const cancelPendingTemplateVersionActivationRequests = `-- name: CancelPendingTemplateVersionActivationRequests :exec
+UPDATE
+	template_version_activation_requests
+SET
+	status = 'canceled'::template_version_activation_request_status,
+	updated_at = $1
+WHERE
+	template_id = $2
+	AND template_version_id != $3
+	AND status = 'pending'::template_version_activation_request_status
 `
 
-// Disable foreign keys and triggers for all tables.
-// Deprecated: disable foreign keys was created to aid in migrating off
-// of the test-only in-memory database. Do not use this in new code.
-func (q *sqlQuerier) DisableForeignKeysAndTriggers(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, disableForeignKeysAndTriggers)
+type CancelPendingTemplateVersionActivationRequestsParams struct {
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	TemplateID      uuid.UUID `db:"template_id" json:"template_id"`
+	ActiveVersionID uuid.UUID `db:"active_version_id" json:"active_version_id"`
+}
+
+// Cancels the pending activation requests of a template once another version
+// has been made active.
+func (q *sqlQuerier) CancelPendingTemplateVersionActivationRequests(ctx context.Context, arg CancelPendingTemplateVersionActivationRequestsParams) error {
+	_, err := q.db.ExecContext(ctx, cancelPendingTemplateVersionActivationRequests, arg.UpdatedAt, arg.TemplateID, arg.ActiveVersionID)
 	return err
 }
 
-const getUserLinkByLinkedID = `-- name: GetUserLinkByLinkedID :one
-SELECT
-	user_links.user_id, user_links.login_type, user_links.linked_id, user_links.oauth_access_token, user_links.oauth_refresh_token, user_links.oauth_expiry, user_links.oauth_access_token_key_id, user_links.oauth_refresh_token_key_id, user_links.claims
-FROM
-	user_links
-INNER JOIN
-	users ON user_links.user_id = users.id
+const deleteTemplateVersionActivationReviews = `-- name: DeleteTemplateVersionActivationReviews :exec
+DELETE FROM
+	template_version_activation_reviews
 WHERE
-	linked_id = $1
-	AND
-	deleted = false
+	template_version_id = $1
 `
 
-func (q *sqlQuerier) GetUserLinkByLinkedID(ctx context.Context, linkedID string) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, getUserLinkByLinkedID, linkedID)
-	var i UserLink
-	err := row.Scan(
-		&i.UserID,
-		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.Claims,
-	)
-	return i, err
+func (q *sqlQuerier) DeleteTemplateVersionActivationReviews(ctx context.Context, templateVersionID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateVersionActivationReviews, templateVersionID)
+	return err
 }
 
-const getUserLinkByUserIDLoginType = `-- name: GetUserLinkByUserIDLoginType :one
+const getTemplateActivationReviewers = `-- name: GetTemplateActivationReviewers :many
 SELECT
-	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
+	template_activation_reviewers.template_id,
+	template_activation_reviewers.user_id,
+	visible_users.username,
+	visible_users.name,
+	visible_users.avatar_url
 FROM
-	user_links
+	template_activation_reviewers
+JOIN
+	visible_users ON visible_users.id = template_activation_reviewers.user_id
 WHERE
-	user_id = $1 AND login_type = $2
+	template_activation_reviewers.template_id = $1
+ORDER BY
+	visible_users.username ASC
 `
 
-type GetUserLinkByUserIDLoginTypeParams struct {
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	LoginType LoginType `db:"login_type" json:"login_type"`
-}
-
-func (q *sqlQuerier) GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, getUserLinkByUserIDLoginType, arg.UserID, arg.LoginType)
-	var i UserLink
-	err := row.Scan(
-		&i.UserID,
-		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.Claims,
-	)
-	return i, err
+type GetTemplateActivationReviewersRow struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	Username   string    `db:"username" json:"username"`
+	Name       string    `db:"name" json:"name"`
+	AvatarURL  string    `db:"avatar_url" json:"avatar_url"`
 }
 
-const getUserLinksByUserID = `-- name: GetUserLinksByUserID :many
-SELECT user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims FROM user_links WHERE user_id = $1
-`
-
-func (q *sqlQuerier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]UserLink, error) {
-	rows, err := q.db.QueryContext(ctx, getUserLinksByUserID, userID)
+func (q *sqlQuerier) GetTemplateActivationReviewers(ctx context.Context, templateID uuid.UUID) ([]GetTemplateActivationReviewersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateActivationReviewers, templateID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []UserLink
+	var items []GetTemplateActivationReviewersRow
 	for rows.Next() {
-		var i UserLink
+		var i GetTemplateActivationReviewersRow
 		if err := rows.Scan(
+			&i.TemplateID,
 			&i.UserID,
-			&i.LoginType,
-			&i.LinkedID,
-			&i.OAuthAccessToken,
-			&i.OAuthRefreshToken,
-			&i.OAuthExpiry,
-			&i.OAuthAccessTokenKeyID,
-			&i.OAuthRefreshTokenKeyID,
-			&i.Claims,
+			&i.Username,
+			&i.Name,
+			&i.AvatarURL,
 		); err != nil {
 			return nil, err
 		}
@@ -12928,113 +16464,100 @@ func (q *sqlQuerier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID)
 	return items, nil
 }
 
-const insertUserLink = `-- name: InsertUserLink :one
-INSERT INTO
-	user_links (
-		user_id,
-		login_type,
-		linked_id,
-		oauth_access_token,
-		oauth_access_token_key_id,
-		oauth_refresh_token,
-		oauth_refresh_token_key_id,
-		oauth_expiry,
-		claims
-	)
-VALUES
-	( $1, $2, $3, $4, $5, $6, $7, $8, $9 ) RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
+const getTemplateVersionActivationRequestByTemplateVersionID = `-- name: GetTemplateVersionActivationRequestByTemplateVersionID :one
+SELECT
+	template_version_activation_requests.template_version_id, template_version_activation_requests.template_id, template_version_activation_requests.requested_by, template_version_activation_requests.status, template_version_activation_requests.approvals_required, template_version_activation_requests.created_at, template_version_activation_requests.updated_at,
+	visible_users.username AS requested_by_username,
+	visible_users.name AS requested_by_name,
+	visible_users.avatar_url AS requested_by_avatar_url
+FROM
+	template_version_activation_requests
+JOIN
+	visible_users ON visible_users.id = template_version_activation_requests.requested_by
+WHERE
+	template_version_activation_requests.template_version_id = $1
 `
 
-type InsertUserLinkParams struct {
-	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
-	LoginType              LoginType      `db:"login_type" json:"login_type"`
-	LinkedID               string         `db:"linked_id" json:"linked_id"`
-	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
-	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
-	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
-	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
-	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
-	Claims                 UserLinkClaims `db:"claims" json:"claims"`
+type GetTemplateVersionActivationRequestByTemplateVersionIDRow struct {
+	TemplateVersionID    uuid.UUID                              `db:"template_version_id" json:"template_version_id"`
+	TemplateID           uuid.UUID                              `db:"template_id" json:"template_id"`
+	RequestedBy          uuid.UUID                              `db:"requested_by" json:"requested_by"`
+	Status               TemplateVersionActivationRequestStatus `db:"status" json:"status"`
+	ApprovalsRequired    int32                                  `db:"approvals_required" json:"approvals_required"`
+	CreatedAt            time.Time                              `db:"created_at" json:"created_at"`
+	UpdatedAt            time.Time                              `db:"updated_at" json:"updated_at"`
+	RequestedByUsername  string                                 `db:"requested_by_username" json:"requested_by_username"`
+	RequestedByName      string                                 `db:"requested_by_name" json:"requested_by_name"`
+	RequestedByAvatarURL string                                 `db:"requested_by_avatar_url" json:"requested_by_avatar_url"`
 }
 
-func (q *sqlQuerier) InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, insertUserLink,
-		arg.UserID,
-		arg.LoginType,
-		arg.LinkedID,
-		arg.OAuthAccessToken,
-		arg.OAuthAccessTokenKeyID,
-		arg.OAuthRefreshToken,
-		arg.OAuthRefreshTokenKeyID,
-		arg.OAuthExpiry,
-		arg.Claims,
-	)
-	var i UserLink
+func (q *sqlQuerier) GetTemplateVersionActivationRequestByTemplateVersionID(ctx context.Context, templateVersionID uuid.UUID) (GetTemplateVersionActivationRequestByTemplateVersionIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionActivationRequestByTemplateVersionID, templateVersionID)
+	var i GetTemplateVersionActivationRequestByTemplateVersionIDRow
 	err := row.Scan(
-		&i.UserID,
-		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.Claims,
+		&i.TemplateVersionID,
+		&i.TemplateID,
+		&i.RequestedBy,
+		&i.Status,
+		&i.ApprovalsRequired,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RequestedByUsername,
+		&i.RequestedByName,
+		&i.RequestedByAvatarURL,
 	)
 	return i, err
 }
 
-const oIDCClaimFieldValues = `-- name: OIDCClaimFieldValues :many
+const getTemplateVersionActivationReviews = `-- name: GetTemplateVersionActivationReviews :many
 SELECT
-	-- DISTINCT to remove duplicates
-	DISTINCT jsonb_array_elements_text(CASE
-		-- When the type is an array, filter out any non-string elements.
-		-- This is to keep the return type consistent.
-		WHEN jsonb_typeof(claims->'merged_claims'->$1::text) = 'array' THEN
-			(
-				SELECT
-					jsonb_agg(element)
-				FROM
-					jsonb_array_elements(claims->'merged_claims'->$1::text) AS element
-				WHERE
-					-- Filtering out non-string elements
-					jsonb_typeof(element) = 'string'
-			)
-		-- Some IDPs return a single string instead of an array of strings.
-		WHEN jsonb_typeof(claims->'merged_claims'->$1::text) = 'string' THEN
-			jsonb_build_array(claims->'merged_claims'->$1::text)
-	END)
+	template_version_activation_reviews.template_version_id, template_version_activation_reviews.reviewer_id, template_version_activation_reviews.approved, template_version_activation_reviews.comment, template_version_activation_reviews.created_at,
+	visible_users.username AS reviewer_username,
+	visible_users.name AS reviewer_name,
+	visible_users.avatar_url AS reviewer_avatar_url
 FROM
-	user_links
+	template_version_activation_reviews
+JOIN
+	visible_users ON visible_users.id = template_version_activation_reviews.reviewer_id
 WHERE
-	-- IDP sync only supports string and array (of string) types
-	jsonb_typeof(claims->'merged_claims'->$1::text) = ANY(ARRAY['string', 'array'])
-	AND login_type = 'oidc'
-	AND CASE
-		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid  THEN
-			user_links.user_id = ANY(SELECT organization_members.user_id FROM organization_members WHERE organization_id = $2)
-		ELSE true
-	END
+	template_version_activation_reviews.template_version_id = $1
+ORDER BY
+	template_version_activation_reviews.created_at ASC
 `
 
-type OIDCClaimFieldValuesParams struct {
-	ClaimField     string    `db:"claim_field" json:"claim_field"`
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+type GetTemplateVersionActivationReviewsRow struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	ReviewerID        uuid.UUID `db:"reviewer_id" json:"reviewer_id"`
+	Approved          bool      `db:"approved" json:"approved"`
+	Comment           string    `db:"comment" json:"comment"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	ReviewerUsername  string    `db:"reviewer_username" json:"reviewer_username"`
+	ReviewerName      string    `db:"reviewer_name" json:"reviewer_name"`
+	ReviewerAvatarURL string    `db:"reviewer_avatar_url" json:"reviewer_avatar_url"`
 }
 
-func (q *sqlQuerier) OIDCClaimFieldValues(ctx context.Context, arg OIDCClaimFieldValuesParams) ([]string, error) {
-	rows, err := q.db.QueryContext(ctx, oIDCClaimFieldValues, arg.ClaimField, arg.OrganizationID)
+func (q *sqlQuerier) GetTemplateVersionActivationReviews(ctx context.Context, templateVersionID uuid.UUID) ([]GetTemplateVersionActivationReviewsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionActivationReviews, templateVersionID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []string
+	var items []GetTemplateVersionActivationReviewsRow
 	for rows.Next() {
-		var jsonb_array_elements_text string
-		if err := rows.Scan(&jsonb_array_elements_text); err != nil {
+		var i GetTemplateVersionActivationReviewsRow
+		if err := rows.Scan(
+			&i.TemplateVersionID,
+			&i.ReviewerID,
+			&i.Approved,
+			&i.Comment,
+			&i.CreatedAt,
+			&i.ReviewerUsername,
+			&i.ReviewerName,
+			&i.ReviewerAvatarURL,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, jsonb_array_elements_text)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -13045,39 +16568,209 @@ func (q *sqlQuerier) OIDCClaimFieldValues(ctx context.Context, arg OIDCClaimFiel
 	return items, nil
 }
 
-const oIDCClaimFields = `-- name: OIDCClaimFields :many
+const updateTemplateActivationReviewers = `-- name: UpdateTemplateActivationReviewers :exec
+WITH deleted AS (
+	DELETE FROM
+		template_activation_reviewers
+	WHERE
+		template_id = $1
+		AND NOT (user_id = ANY($2 :: uuid[]))
+)
+INSERT INTO template_activation_reviewers (template_id, user_id)
 SELECT
-	DISTINCT jsonb_object_keys(claims->'merged_claims')
-FROM
-	user_links
+	$1,
+	unnest($2 :: uuid[])
+ON CONFLICT DO NOTHING
+`
+
+type UpdateTemplateActivationReviewersParams struct {
+	TemplateID uuid.UUID   `db:"template_id" json:"template_id"`
+	UserIds    []uuid.UUID `db:"user_ids" json:"user_ids"`
+}
+
+// Replaces the designated reviewers of a template.
+func (q *sqlQuerier) UpdateTemplateActivationReviewers(ctx context.Context, arg UpdateTemplateActivationReviewersParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateActivationReviewers, arg.TemplateID, pq.Array(arg.UserIds))
+	return err
+}
+
+const updateTemplateVersionActivationRequestStatus = `-- name: UpdateTemplateVersionActivationRequestStatus :one
+UPDATE
+	template_version_activation_requests
+SET
+	status = $1,
+	updated_at = $2
 WHERE
-    -- Only return rows where the top level key exists
-	claims ? 'merged_claims' AND
-    -- 'null' is the default value for the id_token_claims field
-	-- jsonb 'null' is not the same as SQL NULL. Strip these out.
-	jsonb_typeof(claims->'merged_claims') != 'null' AND
-	login_type = 'oidc'
-	AND CASE WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid  THEN
-		user_links.user_id = ANY(SELECT organization_members.user_id FROM organization_members WHERE organization_id = $1)
-		ELSE true
-	END
+	template_version_id = $3
+RETURNING template_version_id, template_id, requested_by, status, approvals_required, created_at, updated_at
 `
 
-// OIDCClaimFields returns a list of distinct keys in the the merged_claims fields.
-// This query is used to generate the list of available sync fields for idp sync settings.
-func (q *sqlQuerier) OIDCClaimFields(ctx context.Context, organizationID uuid.UUID) ([]string, error) {
-	rows, err := q.db.QueryContext(ctx, oIDCClaimFields, organizationID)
+type UpdateTemplateVersionActivationRequestStatusParams struct {
+	Status            TemplateVersionActivationRequestStatus `db:"status" json:"status"`
+	UpdatedAt         time.Time                              `db:"updated_at" json:"updated_at"`
+	TemplateVersionID uuid.UUID                              `db:"template_version_id" json:"template_version_id"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionActivationRequestStatus(ctx context.Context, arg UpdateTemplateVersionActivationRequestStatusParams) (TemplateVersionActivationRequest, error) {
+	row := q.db.QueryRowContext(ctx, updateTemplateVersionActivationRequestStatus, arg.Status, arg.UpdatedAt, arg.TemplateVersionID)
+	var i TemplateVersionActivationRequest
+	err := row.Scan(
+		&i.TemplateVersionID,
+		&i.TemplateID,
+		&i.RequestedBy,
+		&i.Status,
+		&i.ApprovalsRequired,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertTemplateVersionActivationRequest = `-- name: UpsertTemplateVersionActivationRequest :one
+INSERT INTO template_version_activation_requests (
+	template_version_id,
+	template_id,
+	requested_by,
+	status,
+	approvals_required,
+	created_at,
+	updated_at
+)
+VALUES (
+	$1,
+	$2,
+	$3,
+	'pending'::template_version_activation_request_status,
+	$4,
+	$5,
+	$5
+)
+ON CONFLICT (template_version_id) DO UPDATE SET
+	requested_by = EXCLUDED.requested_by,
+	status = EXCLUDED.status,
+	approvals_required = EXCLUDED.approvals_required,
+	created_at = EXCLUDED.created_at,
+	updated_at = EXCLUDED.updated_at
+RETURNING template_version_id, template_id, requested_by, status, approvals_required, created_at, updated_at
+`
+
+type UpsertTemplateVersionActivationRequestParams struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	TemplateID        uuid.UUID `db:"template_id" json:"template_id"`
+	RequestedBy       uuid.UUID `db:"requested_by" json:"requested_by"`
+	ApprovalsRequired int32     `db:"approvals_required" json:"approvals_required"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+}
+
+// Requesting the activation of a version again, for example after it was
+// rejected, resets the request to pending.
+func (q *sqlQuerier) UpsertTemplateVersionActivationRequest(ctx context.Context, arg UpsertTemplateVersionActivationRequestParams) (TemplateVersionActivationRequest, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateVersionActivationRequest,
+		arg.TemplateVersionID,
+		arg.TemplateID,
+		arg.RequestedBy,
+		arg.ApprovalsRequired,
+		arg.CreatedAt,
+	)
+	var i TemplateVersionActivationRequest
+	err := row.Scan(
+		&i.TemplateVersionID,
+		&i.TemplateID,
+		&i.RequestedBy,
+		&i.Status,
+		&i.ApprovalsRequired,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertTemplateVersionActivationReview = `-- name: UpsertTemplateVersionActivationReview :one
+INSERT INTO template_version_activation_reviews (
+	template_version_id,
+	reviewer_id,
+	approved,
+	comment,
+	created_at
+)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5
+)
+ON CONFLICT (template_version_id, reviewer_id) DO UPDATE SET
+	approved = EXCLUDED.approved,
+	comment = EXCLUDED.comment,
+	created_at = EXCLUDED.created_at
+RETURNING template_version_id, reviewer_id, approved, comment, created_at
+`
+
+type UpsertTemplateVersionActivationReviewParams struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	ReviewerID        uuid.UUID `db:"reviewer_id" json:"reviewer_id"`
+	Approved          bool      `db:"approved" json:"approved"`
+	Comment           string    `db:"comment" json:"comment"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+}
+
+// A reviewer can change their mind, only their latest review counts.
+func (q *sqlQuerier) UpsertTemplateVersionActivationReview(ctx context.Context, arg UpsertTemplateVersionActivationReviewParams) (TemplateVersionActivationReview, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateVersionActivationReview,
+		arg.TemplateVersionID,
+		arg.ReviewerID,
+		arg.Approved,
+		arg.Comment,
+		arg.CreatedAt,
+	)
+	var i TemplateVersionActivationReview
+	err := row.Scan(
+		&i.TemplateVersionID,
+		&i.ReviewerID,
+		&i.Approved,
+		&i.Comment,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getTemplateVersionParameters = `-- name: GetTemplateVersionParameters :many
+SELECT template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral, form_type FROM template_version_parameters WHERE template_version_id = $1 ORDER BY display_order ASC, LOWER(name) ASC
+`
+
+func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionParameters, templateVersionID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []string
+	var items []TemplateVersionParameter
 	for rows.Next() {
-		var jsonb_object_keys string
-		if err := rows.Scan(&jsonb_object_keys); err != nil {
+		var i TemplateVersionParameter
+		if err := rows.Scan(
+			&i.TemplateVersionID,
+			&i.Name,
+			&i.Description,
+			&i.Type,
+			&i.Mutable,
+			&i.DefaultValue,
+			&i.Icon,
+			&i.Options,
+			&i.ValidationRegex,
+			&i.ValidationMin,
+			&i.ValidationMax,
+			&i.ValidationError,
+			&i.ValidationMonotonic,
+			&i.Required,
+			&i.DisplayName,
+			&i.DisplayOrder,
+			&i.Ephemeral,
+			&i.FormType,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, jsonb_object_keys)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -13088,221 +16781,2758 @@ func (q *sqlQuerier) OIDCClaimFields(ctx context.Context, organizationID uuid.UU
 	return items, nil
 }
 
-const updateUserLink = `-- name: UpdateUserLink :one
+const insertTemplateVersionParameter = `-- name: InsertTemplateVersionParameter :one
+INSERT INTO
+    template_version_parameters (
+        template_version_id,
+        name,
+        description,
+        type,
+        form_type,
+        mutable,
+        default_value,
+        icon,
+        options,
+        validation_regex,
+        validation_min,
+        validation_max,
+        validation_error,
+        validation_monotonic,
+        required,
+        display_name,
+        display_order,
+        ephemeral
+    )
+VALUES
+    (
+        $1,
+        $2,
+        $3,
+        $4,
+        $5,
+        $6,
+        $7,
+        $8,
+        $9,
+        $10,
+        $11,
+        $12,
+        $13,
+        $14,
+        $15,
+        $16,
+        $17,
+        $18
+    ) RETURNING template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral, form_type
+`
+
+type InsertTemplateVersionParameterParams struct {
+	TemplateVersionID   uuid.UUID         `db:"template_version_id" json:"template_version_id"`
+	Name                string            `db:"name" json:"name"`
+	Description         string            `db:"description" json:"description"`
+	Type                string            `db:"type" json:"type"`
+	FormType            ParameterFormType `db:"form_type" json:"form_type"`
+	Mutable             bool              `db:"mutable" json:"mutable"`
+	DefaultValue        string            `db:"default_value" json:"default_value"`
+	Icon                string            `db:"icon" json:"icon"`
+	Options             json.RawMessage   `db:"options" json:"options"`
+	ValidationRegex     string            `db:"validation_regex" json:"validation_regex"`
+	ValidationMin       sql.NullInt32     `db:"validation_min" json:"validation_min"`
+	ValidationMax       sql.NullInt32     `db:"validation_max" json:"validation_max"`
+	ValidationError     string            `db:"validation_error" json:"validation_error"`
+	ValidationMonotonic string            `db:"validation_monotonic" json:"validation_monotonic"`
+	Required            bool              `db:"required" json:"required"`
+	DisplayName         string            `db:"display_name" json:"display_name"`
+	DisplayOrder        int32             `db:"display_order" json:"display_order"`
+	Ephemeral           bool              `db:"ephemeral" json:"ephemeral"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionParameter,
+		arg.TemplateVersionID,
+		arg.Name,
+		arg.Description,
+		arg.Type,
+		arg.FormType,
+		arg.Mutable,
+		arg.DefaultValue,
+		arg.Icon,
+		arg.Options,
+		arg.ValidationRegex,
+		arg.ValidationMin,
+		arg.ValidationMax,
+		arg.ValidationError,
+		arg.ValidationMonotonic,
+		arg.Required,
+		arg.DisplayName,
+		arg.DisplayOrder,
+		arg.Ephemeral,
+	)
+	var i TemplateVersionParameter
+	err := row.Scan(
+		&i.TemplateVersionID,
+		&i.Name,
+		&i.Description,
+		&i.Type,
+		&i.Mutable,
+		&i.DefaultValue,
+		&i.Icon,
+		&i.Options,
+		&i.ValidationRegex,
+		&i.ValidationMin,
+		&i.ValidationMax,
+		&i.ValidationError,
+		&i.ValidationMonotonic,
+		&i.Required,
+		&i.DisplayName,
+		&i.DisplayOrder,
+		&i.Ephemeral,
+		&i.FormType,
+	)
+	return i, err
+}
+
+const finishTemplateVersionRollout = `-- name: FinishTemplateVersionRollout :exec
+UPDATE
+	template_version_rollouts
+SET
+	status = CASE
+		WHEN template_version_id = $1 THEN 'completed'::template_version_rollout_status
+		ELSE 'canceled'::template_version_rollout_status
+	END,
+	updated_at = $2
+WHERE
+	template_id = $3
+	AND status IN ('in_progress'::template_version_rollout_status, 'halted'::template_version_rollout_status, 'pending_approval'::template_version_rollout_status)
+`
+
+type FinishTemplateVersionRolloutParams struct {
+	ActiveVersionID uuid.UUID `db:"active_version_id" json:"active_version_id"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	TemplateID      uuid.UUID `db:"template_id" json:"template_id"`
+}
+
+// Completes the rollout of a template once its version has been made active,
+// or cancels it if another version has been made active.
+func (q *sqlQuerier) FinishTemplateVersionRollout(ctx context.Context, arg FinishTemplateVersionRolloutParams) error {
+	_, err := q.db.ExecContext(ctx, finishTemplateVersionRollout, arg.ActiveVersionID, arg.UpdatedAt, arg.TemplateID)
+	return err
+}
+
+const getTemplateVersionRolloutBuildStats = `-- name: GetTemplateVersionRolloutBuildStats :one
+SELECT
+	COUNT(*) AS total_builds,
+	COUNT(*) FILTER (WHERE provisioner_jobs.job_status = 'failed'::provisioner_job_status) AS failed_builds
+FROM
+	workspace_builds
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+JOIN
+	template_version_rollouts ON template_version_rollouts.template_version_id = workspace_builds.template_version_id
+WHERE
+	template_version_rollouts.template_id = $1
+	AND workspace_builds.transition = 'start'::workspace_transition
+	AND workspace_builds.created_at >= template_version_rollouts.created_at
+	AND provisioner_jobs.job_status IN ('succeeded'::provisioner_job_status, 'failed'::provisioner_job_status)
+`
+
+type GetTemplateVersionRolloutBuildStatsRow struct {
+	TotalBuilds  int64 `db:"total_builds" json:"total_builds"`
+	FailedBuilds int64 `db:"failed_builds" json:"failed_builds"`
+}
+
+// Counts the finished start builds of the rolled out version since the rollout
+// was started.
+func (q *sqlQuerier) GetTemplateVersionRolloutBuildStats(ctx context.Context, templateID uuid.UUID) (GetTemplateVersionRolloutBuildStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionRolloutBuildStats, templateID)
+	var i GetTemplateVersionRolloutBuildStatsRow
+	err := row.Scan(&i.TotalBuilds, &i.FailedBuilds)
+	return i, err
+}
+
+const getTemplateVersionRolloutByTemplateID = `-- name: GetTemplateVersionRolloutByTemplateID :one
+SELECT
+	template_id, template_version_id, created_by, percentage, group_ids, failure_threshold_percent, minimum_builds, status, created_at, updated_at
+FROM
+	template_version_rollouts
+WHERE
+	template_id = $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionRolloutByTemplateID(ctx context.Context, templateID uuid.UUID) (TemplateVersionRollout, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionRolloutByTemplateID, templateID)
+	var i TemplateVersionRollout
+	err := row.Scan(
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.CreatedBy,
+		&i.Percentage,
+		pq.Array(&i.GroupIds),
+		&i.FailureThresholdPercent,
+		&i.MinimumBuilds,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTemplateVersionRolloutTargetsByWorkspaceIDs = `-- name: GetTemplateVersionRolloutTargetsByWorkspaceIDs :many
+SELECT
+	workspaces.id AS workspace_id,
+	template_version_rollouts.template_version_id
+FROM
+	workspaces
+JOIN
+	template_version_rollouts ON template_version_rollouts.template_id = workspaces.template_id
+WHERE
+	workspaces.id = ANY($1 :: uuid[])
+	AND template_version_rollouts.status = 'in_progress'::template_version_rollout_status
+	AND (
+		abs(hashtext(workspaces.id::text)::bigint) % 100 < template_version_rollouts.percentage
+		OR EXISTS (
+			SELECT
+				1
+			FROM
+				group_members_expanded
+			WHERE
+				group_members_expanded.user_id = workspaces.owner_id
+				AND group_members_expanded.group_id = ANY(template_version_rollouts.group_ids)
+		)
+	)
+`
+
+type GetTemplateVersionRolloutTargetsByWorkspaceIDsRow struct {
+	WorkspaceID       uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+}
+
+// Returns the template version that workspaces in the cohort of an in progress
+// rollout are built with instead of the active version of their template.
+// Workspaces are part of the cohort if their owner is a member of one of the
+// groups of the rollout, or if they fall within its percentage. Hashing the
+// workspace ID keeps the cohort stable as the percentage is increased.
+func (q *sqlQuerier) GetTemplateVersionRolloutTargetsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]GetTemplateVersionRolloutTargetsByWorkspaceIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionRolloutTargetsByWorkspaceIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateVersionRolloutTargetsByWorkspaceIDsRow
+	for rows.Next() {
+		var i GetTemplateVersionRolloutTargetsByWorkspaceIDsRow
+		if err := rows.Scan(&i.WorkspaceID, &i.TemplateVersionID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const haltTemplateVersionRollout = `-- name: HaltTemplateVersionRollout :one
+UPDATE
+	template_version_rollouts
+SET
+	status = 'halted'::template_version_rollout_status,
+	updated_at = $1
+WHERE
+	template_id = $2
+	AND template_version_id = $3
+	AND status = 'in_progress'::template_version_rollout_status
+RETURNING template_id, template_version_id, created_by, percentage, group_ids, failure_threshold_percent, minimum_builds, status, created_at, updated_at
+`
+
+type HaltTemplateVersionRolloutParams struct {
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+	TemplateID        uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+}
+
+// Only halts the rollout if it's still in progress, so that concurrent
+// failures halt it once.
+func (q *sqlQuerier) HaltTemplateVersionRollout(ctx context.Context, arg HaltTemplateVersionRolloutParams) (TemplateVersionRollout, error) {
+	row := q.db.QueryRowContext(ctx, haltTemplateVersionRollout, arg.UpdatedAt, arg.TemplateID, arg.TemplateVersionID)
+	var i TemplateVersionRollout
+	err := row.Scan(
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.CreatedBy,
+		&i.Percentage,
+		pq.Array(&i.GroupIds),
+		&i.FailureThresholdPercent,
+		&i.MinimumBuilds,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const startTemplateVersionRollout = `-- name: StartTemplateVersionRollout :one
+UPDATE
+	template_version_rollouts
+SET
+	status = 'in_progress'::template_version_rollout_status,
+	updated_at = $1
+WHERE
+	template_id = $2
+	AND template_version_id = $3
+	AND status = 'pending_approval'::template_version_rollout_status
+RETURNING template_id, template_version_id, created_by, percentage, group_ids, failure_threshold_percent, minimum_builds, status, created_at, updated_at
+`
+
+type StartTemplateVersionRolloutParams struct {
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+	TemplateID        uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+}
+
+// Starts a rollout that was pending the approval of the activation of its
+// version.
+func (q *sqlQuerier) StartTemplateVersionRollout(ctx context.Context, arg StartTemplateVersionRolloutParams) (TemplateVersionRollout, error) {
+	row := q.db.QueryRowContext(ctx, startTemplateVersionRollout, arg.UpdatedAt, arg.TemplateID, arg.TemplateVersionID)
+	var i TemplateVersionRollout
+	err := row.Scan(
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.CreatedBy,
+		&i.Percentage,
+		pq.Array(&i.GroupIds),
+		&i.FailureThresholdPercent,
+		&i.MinimumBuilds,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertTemplateVersionRollout = `-- name: UpsertTemplateVersionRollout :one
+INSERT INTO template_version_rollouts (
+	template_id,
+	template_version_id,
+	created_by,
+	percentage,
+	group_ids,
+	failure_threshold_percent,
+	minimum_builds,
+	status,
+	created_at,
+	updated_at
+)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5 :: uuid[],
+	$6,
+	$7,
+	$8,
+	$9,
+	$9
+)
+ON CONFLICT (template_id) DO UPDATE SET
+	template_version_id = EXCLUDED.template_version_id,
+	created_by = EXCLUDED.created_by,
+	percentage = EXCLUDED.percentage,
+	group_ids = EXCLUDED.group_ids,
+	failure_threshold_percent = EXCLUDED.failure_threshold_percent,
+	minimum_builds = EXCLUDED.minimum_builds,
+	status = EXCLUDED.status,
+	created_at = EXCLUDED.created_at,
+	updated_at = EXCLUDED.updated_at
+RETURNING template_id, template_version_id, created_by, percentage, group_ids, failure_threshold_percent, minimum_builds, status, created_at, updated_at
+`
+
+type UpsertTemplateVersionRolloutParams struct {
+	TemplateID              uuid.UUID                    `db:"template_id" json:"template_id"`
+	TemplateVersionID       uuid.UUID                    `db:"template_version_id" json:"template_version_id"`
+	CreatedBy               uuid.UUID                    `db:"created_by" json:"created_by"`
+	Percentage              int32                        `db:"percentage" json:"percentage"`
+	GroupIds                []uuid.UUID                  `db:"group_ids" json:"group_ids"`
+	FailureThresholdPercent int32                        `db:"failure_threshold_percent" json:"failure_threshold_percent"`
+	MinimumBuilds           int32                        `db:"minimum_builds" json:"minimum_builds"`
+	Status                  TemplateVersionRolloutStatus `db:"status" json:"status"`
+	CreatedAt               time.Time                    `db:"created_at" json:"created_at"`
+}
+
+// Starting a rollout replaces the previous rollout of the template, which also
+// restarts the evaluation of the failure threshold. Rollouts of versions whose
+// activation hasn't been approved yet are created pending approval.
+func (q *sqlQuerier) UpsertTemplateVersionRollout(ctx context.Context, arg UpsertTemplateVersionRolloutParams) (TemplateVersionRollout, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateVersionRollout,
+		arg.TemplateID,
+		arg.TemplateVersionID,
+		arg.CreatedBy,
+		arg.Percentage,
+		pq.Array(arg.GroupIds),
+		arg.FailureThresholdPercent,
+		arg.MinimumBuilds,
+		arg.Status,
+		arg.CreatedAt,
+	)
+	var i TemplateVersionRollout
+	err := row.Scan(
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.CreatedBy,
+		&i.Percentage,
+		pq.Array(&i.GroupIds),
+		&i.FailureThresholdPercent,
+		&i.MinimumBuilds,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const archiveUnusedTemplateVersions = `-- name: ArchiveUnusedTemplateVersions :many
+UPDATE
+	template_versions
+SET
+	archived = true,
+	updated_at = $1
+FROM
+	-- Archive all versions that are returned from this query.
+	(
+		SELECT
+			scoped_template_versions.id
+		FROM
+			-- Scope an archive to a single template and ignore already archived template versions
+			(
+				SELECT
+					id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task
+				FROM
+					template_versions
+				WHERE
+					template_versions.template_id = $2 :: uuid
+					AND
+					archived = false
+					AND
+					-- This allows archiving a specific template version.
+					CASE
+						WHEN $3::uuid  != '00000000-0000-0000-0000-000000000000'::uuid THEN
+							template_versions.id = $3 :: uuid
+						ELSE
+							true
+						END
+			) AS scoped_template_versions
+			LEFT JOIN
+				provisioner_jobs ON scoped_template_versions.job_id = provisioner_jobs.id
+			LEFT JOIN
+				templates ON scoped_template_versions.template_id = templates.id
+		WHERE
+		  -- Actively used template versions (meaning the latest build is using
+		  -- the version) are never archived. A "restart" command on the workspace,
+		  -- even if failed, would use the version. So it cannot be archived until
+		  -- the build is outdated.
+		NOT EXISTS (
+			-- Return all "used" versions, where "used" is defined as being
+			-- used by a latest workspace build.
+			SELECT template_version_id FROM (
+				SELECT
+					DISTINCT ON (workspace_id) template_version_id, transition
+				FROM
+					workspace_builds
+				ORDER BY workspace_id, build_number DESC
+				) AS used_versions
+			WHERE
+				used_versions.transition != 'delete'
+				AND
+				scoped_template_versions.id = used_versions.template_version_id
+		)
+		-- Also never archive the active template version
+		AND active_version_id != scoped_template_versions.id
+		AND CASE
+			-- Optionally, only archive versions that match a given
+			-- job status like 'failed'.
+			WHEN $4 :: provisioner_job_status IS NOT NULL THEN
+				provisioner_jobs.job_status = $4 :: provisioner_job_status
+			ELSE
+				true
+		END
+		-- Pending or running jobs should not be archived, as they are "in progress"
+		AND provisioner_jobs.job_status != 'running'
+		AND provisioner_jobs.job_status != 'pending'
+	) AS archived_versions
+WHERE
+	template_versions.id IN (archived_versions.id)
+RETURNING template_versions.id
+`
+
+type ArchiveUnusedTemplateVersionsParams struct {
+	UpdatedAt         time.Time                `db:"updated_at" json:"updated_at"`
+	TemplateID        uuid.UUID                `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID                `db:"template_version_id" json:"template_version_id"`
+	JobStatus         NullProvisionerJobStatus `db:"job_status" json:"job_status"`
+}
+
+// Archiving templates is a soft delete action, so is reversible.
+// Archiving prevents the version from being used and discovered
+// by listing.
+// Only unused template versions will be archived, which are any versions not
+// referenced by the latest build of a workspace.
+func (q *sqlQuerier) ArchiveUnusedTemplateVersions(ctx context.Context, arg ArchiveUnusedTemplateVersionsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, archiveUnusedTemplateVersions,
+		arg.UpdatedAt,
+		arg.TemplateID,
+		arg.TemplateVersionID,
+		arg.JobStatus,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPreviousTemplateVersion = `-- name: GetPreviousTemplateVersion :one
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, created_by_avatar_url, created_by_username, created_by_name
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	created_at < (
+		SELECT created_at
+		FROM template_version_with_user AS tv
+		WHERE tv.organization_id = $1 AND tv.name = $2 AND tv.template_id = $3
+	)
+	AND organization_id = $1
+	AND template_id = $3
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+type GetPreviousTemplateVersionParams struct {
+	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
+	Name           string        `db:"name" json:"name"`
+	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
+}
+
+func (q *sqlQuerier) GetPreviousTemplateVersion(ctx context.Context, arg GetPreviousTemplateVersionParams) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getPreviousTemplateVersion, arg.OrganizationID, arg.Name, arg.TemplateID)
+	var i TemplateVersion
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Readme,
+		&i.JobID,
+		&i.CreatedBy,
+		&i.ExternalAuthProviders,
+		&i.Message,
+		&i.Archived,
+		&i.SourceExampleID,
+		&i.HasAITask,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+		&i.CreatedByName,
+	)
+	return i, err
+}
+
+const getTemplateVersionByID = `-- name: GetTemplateVersionByID :one
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, created_by_avatar_url, created_by_username, created_by_name
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionByID, id)
+	var i TemplateVersion
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Readme,
+		&i.JobID,
+		&i.CreatedBy,
+		&i.ExternalAuthProviders,
+		&i.Message,
+		&i.Archived,
+		&i.SourceExampleID,
+		&i.HasAITask,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+		&i.CreatedByName,
+	)
+	return i, err
+}
+
+const getTemplateVersionByJobID = `-- name: GetTemplateVersionByJobID :one
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, created_by_avatar_url, created_by_username, created_by_name
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	job_id = $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionByJobID, jobID)
+	var i TemplateVersion
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Readme,
+		&i.JobID,
+		&i.CreatedBy,
+		&i.ExternalAuthProviders,
+		&i.Message,
+		&i.Archived,
+		&i.SourceExampleID,
+		&i.HasAITask,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+		&i.CreatedByName,
+	)
+	return i, err
+}
+
+const getTemplateVersionByTemplateIDAndName = `-- name: GetTemplateVersionByTemplateIDAndName :one
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, created_by_avatar_url, created_by_username, created_by_name
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	template_id = $1
+	AND "name" = $2
+`
+
+type GetTemplateVersionByTemplateIDAndNameParams struct {
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	Name       string        `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg GetTemplateVersionByTemplateIDAndNameParams) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionByTemplateIDAndName, arg.TemplateID, arg.Name)
+	var i TemplateVersion
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Readme,
+		&i.JobID,
+		&i.CreatedBy,
+		&i.ExternalAuthProviders,
+		&i.Message,
+		&i.Archived,
+		&i.SourceExampleID,
+		&i.HasAITask,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+		&i.CreatedByName,
+	)
+	return i, err
+}
+
+const getTemplateVersionsByIDs = `-- name: GetTemplateVersionsByIDs :many
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, created_by_avatar_url, created_by_username, created_by_name
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	id = ANY($1 :: uuid [ ])
+`
+
+func (q *sqlQuerier) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UUID) ([]TemplateVersion, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersion
+	for rows.Next() {
+		var i TemplateVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.OrganizationID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Readme,
+			&i.JobID,
+			&i.CreatedBy,
+			&i.ExternalAuthProviders,
+			&i.Message,
+			&i.Archived,
+			&i.SourceExampleID,
+			&i.HasAITask,
+			&i.CreatedByAvatarURL,
+			&i.CreatedByUsername,
+			&i.CreatedByName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateVersionsByTemplateID = `-- name: GetTemplateVersionsByTemplateID :many
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, created_by_avatar_url, created_by_username, created_by_name
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	template_id = $1 :: uuid
+	  AND CASE
+		-- If no filter is provided, default to returning ALL template versions.
+		-- The called should always provide a filter if they want to omit
+		-- archived versions.
+		WHEN $2 :: boolean IS NULL THEN true
+		ELSE template_versions.archived = $2 :: boolean
+	END
+	AND CASE
+		-- This allows using the last element on a page as effectively a cursor.
+		-- This is an important option for scripts that need to paginate without
+		-- duplicating or missing data.
+		WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			-- The pagination cursor is the last ID of the previous page.
+			-- The query is ordered by the created_at field, so select all
+			-- rows after the cursor.
+			(created_at, id) > (
+				SELECT
+					created_at, id
+				FROM
+					template_versions
+				WHERE
+					id = $3
+			)
+		)
+		ELSE true
+	END
+ORDER BY
+    -- Deterministic and consistent ordering of all rows, even if they share
+    -- a timestamp. This is to ensure consistent pagination.
+	(created_at, id) ASC OFFSET $4
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($5 :: int, 0)
+`
+
+type GetTemplateVersionsByTemplateIDParams struct {
+	TemplateID uuid.UUID    `db:"template_id" json:"template_id"`
+	Archived   sql.NullBool `db:"archived" json:"archived"`
+	AfterID    uuid.UUID    `db:"after_id" json:"after_id"`
+	OffsetOpt  int32        `db:"offset_opt" json:"offset_opt"`
+	LimitOpt   int32        `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg GetTemplateVersionsByTemplateIDParams) ([]TemplateVersion, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByTemplateID,
+		arg.TemplateID,
+		arg.Archived,
+		arg.AfterID,
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersion
+	for rows.Next() {
+		var i TemplateVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.OrganizationID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Readme,
+			&i.JobID,
+			&i.CreatedBy,
+			&i.ExternalAuthProviders,
+			&i.Message,
+			&i.Archived,
+			&i.SourceExampleID,
+			&i.HasAITask,
+			&i.CreatedByAvatarURL,
+			&i.CreatedByUsername,
+			&i.CreatedByName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateVersionsCreatedAfter = `-- name: GetTemplateVersionsCreatedAfter :many
+SELECT id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, source_example_id, has_ai_task, created_by_avatar_url, created_by_username, created_by_name FROM template_version_with_user AS template_versions WHERE created_at > $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]TemplateVersion, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionsCreatedAfter, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersion
+	for rows.Next() {
+		var i TemplateVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.OrganizationID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Readme,
+			&i.JobID,
+			&i.CreatedBy,
+			&i.ExternalAuthProviders,
+			&i.Message,
+			&i.Archived,
+			&i.SourceExampleID,
+			&i.HasAITask,
+			&i.CreatedByAvatarURL,
+			&i.CreatedByUsername,
+			&i.CreatedByName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const hasTemplateVersionsWithAITask = `-- name: HasTemplateVersionsWithAITask :one
+SELECT EXISTS (SELECT 1 FROM template_versions WHERE has_ai_task = TRUE)
+`
+
+// Determines if the template versions table has any rows with has_ai_task = TRUE.
+func (q *sqlQuerier) HasTemplateVersionsWithAITask(ctx context.Context) (bool, error) {
+	row := q.db.QueryRowContext(ctx, hasTemplateVersionsWithAITask)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const insertTemplateVersion = `-- name: InsertTemplateVersion :exec
+INSERT INTO
+	template_versions (
+		id,
+		template_id,
+		organization_id,
+		created_at,
+		updated_at,
+		"name",
+		message,
+		readme,
+		job_id,
+		created_by,
+		source_example_id
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+`
+
+type InsertTemplateVersionParams struct {
+	ID              uuid.UUID      `db:"id" json:"id"`
+	TemplateID      uuid.NullUUID  `db:"template_id" json:"template_id"`
+	OrganizationID  uuid.UUID      `db:"organization_id" json:"organization_id"`
+	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
+	Name            string         `db:"name" json:"name"`
+	Message         string         `db:"message" json:"message"`
+	Readme          string         `db:"readme" json:"readme"`
+	JobID           uuid.UUID      `db:"job_id" json:"job_id"`
+	CreatedBy       uuid.UUID      `db:"created_by" json:"created_by"`
+	SourceExampleID sql.NullString `db:"source_example_id" json:"source_example_id"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error {
+	_, err := q.db.ExecContext(ctx, insertTemplateVersion,
+		arg.ID,
+		arg.TemplateID,
+		arg.OrganizationID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Message,
+		arg.Readme,
+		arg.JobID,
+		arg.CreatedBy,
+		arg.SourceExampleID,
+	)
+	return err
+}
+
+const unarchiveTemplateVersion = `-- name: UnarchiveTemplateVersion :exec
+UPDATE
+	template_versions
+SET
+	archived = false,
+	updated_at = $1
+WHERE
+		id = $2
+`
+
+type UnarchiveTemplateVersionParams struct {
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+}
+
+// This will always work regardless of the current state of the template version.
+func (q *sqlQuerier) UnarchiveTemplateVersion(ctx context.Context, arg UnarchiveTemplateVersionParams) error {
+	_, err := q.db.ExecContext(ctx, unarchiveTemplateVersion, arg.UpdatedAt, arg.TemplateVersionID)
+	return err
+}
+
+const updateTemplateVersionAITaskByJobID = `-- name: UpdateTemplateVersionAITaskByJobID :exec
+UPDATE
+	template_versions
+SET
+	has_ai_task = $2,
+	updated_at = $3
+WHERE
+	job_id = $1
+`
+
+type UpdateTemplateVersionAITaskByJobIDParams struct {
+	JobID     uuid.UUID    `db:"job_id" json:"job_id"`
+	HasAITask sql.NullBool `db:"has_ai_task" json:"has_ai_task"`
+	UpdatedAt time.Time    `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionAITaskByJobID(ctx context.Context, arg UpdateTemplateVersionAITaskByJobIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionAITaskByJobID, arg.JobID, arg.HasAITask, arg.UpdatedAt)
+	return err
+}
+
+const updateTemplateVersionByID = `-- name: UpdateTemplateVersionByID :exec
+UPDATE
+	template_versions
+SET
+	template_id = $2,
+	updated_at = $3,
+	name = $4,
+	message = $5
+WHERE
+	id = $1
+`
+
+type UpdateTemplateVersionByIDParams struct {
+	ID         uuid.UUID     `db:"id" json:"id"`
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	UpdatedAt  time.Time     `db:"updated_at" json:"updated_at"`
+	Name       string        `db:"name" json:"name"`
+	Message    string        `db:"message" json:"message"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionByID,
+		arg.ID,
+		arg.TemplateID,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Message,
+	)
+	return err
+}
+
+const updateTemplateVersionDescriptionByJobID = `-- name: UpdateTemplateVersionDescriptionByJobID :exec
+UPDATE
+	template_versions
+SET
+	readme = $2,
+	updated_at = $3
+WHERE
+	job_id = $1
+`
+
+type UpdateTemplateVersionDescriptionByJobIDParams struct {
+	JobID     uuid.UUID `db:"job_id" json:"job_id"`
+	Readme    string    `db:"readme" json:"readme"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionDescriptionByJobID, arg.JobID, arg.Readme, arg.UpdatedAt)
+	return err
+}
+
+const updateTemplateVersionExternalAuthProvidersByJobID = `-- name: UpdateTemplateVersionExternalAuthProvidersByJobID :exec
+UPDATE
+	template_versions
+SET
+	external_auth_providers = $2,
+	updated_at = $3
+WHERE
+	job_id = $1
+`
+
+type UpdateTemplateVersionExternalAuthProvidersByJobIDParams struct {
+	JobID                 uuid.UUID       `db:"job_id" json:"job_id"`
+	ExternalAuthProviders json.RawMessage `db:"external_auth_providers" json:"external_auth_providers"`
+	UpdatedAt             time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.Context, arg UpdateTemplateVersionExternalAuthProvidersByJobIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionExternalAuthProvidersByJobID, arg.JobID, arg.ExternalAuthProviders, arg.UpdatedAt)
+	return err
+}
+
+const getTemplateVersionTerraformValues = `-- name: GetTemplateVersionTerraformValues :one
+SELECT
+	template_version_terraform_values.template_version_id, template_version_terraform_values.updated_at, template_version_terraform_values.cached_plan, template_version_terraform_values.cached_module_files, template_version_terraform_values.provisionerd_version
+FROM
+	template_version_terraform_values
+WHERE
+	template_version_terraform_values.template_version_id = $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionTerraformValues(ctx context.Context, templateVersionID uuid.UUID) (TemplateVersionTerraformValue, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionTerraformValues, templateVersionID)
+	var i TemplateVersionTerraformValue
+	err := row.Scan(
+		&i.TemplateVersionID,
+		&i.UpdatedAt,
+		&i.CachedPlan,
+		&i.CachedModuleFiles,
+		&i.ProvisionerdVersion,
+	)
+	return i, err
+}
+
+const insertTemplateVersionTerraformValuesByJobID = `-- name: InsertTemplateVersionTerraformValuesByJobID :exec
+INSERT INTO
+	template_version_terraform_values (
+		template_version_id,
+		cached_plan,
+		cached_module_files,
+		updated_at,
+	    provisionerd_version
+	)
+VALUES
+	(
+		(select id from template_versions where job_id = $1),
+		$2,
+		$3,
+		$4,
+		$5
+	)
+`
+
+type InsertTemplateVersionTerraformValuesByJobIDParams struct {
+	JobID               uuid.UUID       `db:"job_id" json:"job_id"`
+	CachedPlan          json.RawMessage `db:"cached_plan" json:"cached_plan"`
+	CachedModuleFiles   uuid.NullUUID   `db:"cached_module_files" json:"cached_module_files"`
+	UpdatedAt           time.Time       `db:"updated_at" json:"updated_at"`
+	ProvisionerdVersion string          `db:"provisionerd_version" json:"provisionerd_version"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionTerraformValuesByJobID(ctx context.Context, arg InsertTemplateVersionTerraformValuesByJobIDParams) error {
+	_, err := q.db.ExecContext(ctx, insertTemplateVersionTerraformValuesByJobID,
+		arg.JobID,
+		arg.CachedPlan,
+		arg.CachedModuleFiles,
+		arg.UpdatedAt,
+		arg.ProvisionerdVersion,
+	)
+	return err
+}
+
+const getTemplateVersionVariables = `-- name: GetTemplateVersionVariables :many
+SELECT template_version_id, name, description, type, value, default_value, required, sensitive FROM template_version_variables WHERE template_version_id = $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionVariables, templateVersionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersionVariable
+	for rows.Next() {
+		var i TemplateVersionVariable
+		if err := rows.Scan(
+			&i.TemplateVersionID,
+			&i.Name,
+			&i.Description,
+			&i.Type,
+			&i.Value,
+			&i.DefaultValue,
+			&i.Required,
+			&i.Sensitive,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplateVersionVariable = `-- name: InsertTemplateVersionVariable :one
+INSERT INTO
+    template_version_variables (
+        template_version_id,
+        name,
+        description,
+        type,
+        value,
+        default_value,
+        required,
+        sensitive
+    )
+VALUES
+    (
+        $1,
+        $2,
+        $3,
+        $4,
+        $5,
+        $6,
+        $7,
+        $8
+    ) RETURNING template_version_id, name, description, type, value, default_value, required, sensitive
+`
+
+type InsertTemplateVersionVariableParams struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	Name              string    `db:"name" json:"name"`
+	Description       string    `db:"description" json:"description"`
+	Type              string    `db:"type" json:"type"`
+	Value             string    `db:"value" json:"value"`
+	DefaultValue      string    `db:"default_value" json:"default_value"`
+	Required          bool      `db:"required" json:"required"`
+	Sensitive         bool      `db:"sensitive" json:"sensitive"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionVariable,
+		arg.TemplateVersionID,
+		arg.Name,
+		arg.Description,
+		arg.Type,
+		arg.Value,
+		arg.DefaultValue,
+		arg.Required,
+		arg.Sensitive,
+	)
+	var i TemplateVersionVariable
+	err := row.Scan(
+		&i.TemplateVersionID,
+		&i.Name,
+		&i.Description,
+		&i.Type,
+		&i.Value,
+		&i.DefaultValue,
+		&i.Required,
+		&i.Sensitive,
+	)
+	return i, err
+}
+
+const getTemplateVersionWorkspaceTags = `-- name: GetTemplateVersionWorkspaceTags :many
+SELECT template_version_id, key, value FROM template_version_workspace_tags WHERE template_version_id = $1 ORDER BY LOWER(key) ASC
+`
+
+func (q *sqlQuerier) GetTemplateVersionWorkspaceTags(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionWorkspaceTag, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionWorkspaceTags, templateVersionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersionWorkspaceTag
+	for rows.Next() {
+		var i TemplateVersionWorkspaceTag
+		if err := rows.Scan(&i.TemplateVersionID, &i.Key, &i.Value); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplateVersionWorkspaceTag = `-- name: InsertTemplateVersionWorkspaceTag :one
+INSERT INTO
+    template_version_workspace_tags (
+        template_version_id,
+        key,
+        value
+    )
+VALUES
+    (
+        $1,
+        $2,
+        $3
+    ) RETURNING template_version_id, key, value
+`
+
+type InsertTemplateVersionWorkspaceTagParams struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	Key               string    `db:"key" json:"key"`
+	Value             string    `db:"value" json:"value"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionWorkspaceTag(ctx context.Context, arg InsertTemplateVersionWorkspaceTagParams) (TemplateVersionWorkspaceTag, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionWorkspaceTag, arg.TemplateVersionID, arg.Key, arg.Value)
+	var i TemplateVersionWorkspaceTag
+	err := row.Scan(&i.TemplateVersionID, &i.Key, &i.Value)
+	return i, err
+}
+
+const disableForeignKeysAndTriggers = `-- name: DisableForeignKeysAndTriggers :exec
+DO $$
+DECLARE
+    table_record record;
+BEGIN
+    FOR table_record IN 
+        SELECT table_schema, table_name 
+        FROM information_schema.tables 
+        WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
+        AND table_type = 'BASE TABLE'
+    LOOP
+        EXECUTE format('ALTER TABLE %I.%I DISABLE TRIGGER ALL', 
+                    table_record.table_schema, 
+                    table_record.table_name);
+    END LOOP;
+END;
+$$
+`
+
+// Disable foreign keys and triggers for all tables.
+// Deprecated: disable foreign keys was created to aid in migrating off
+// of the test-only in-memory database. Do not use this in new code.
+func (q *sqlQuerier) DisableForeignKeysAndTriggers(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, disableForeignKeysAndTriggers)
+	return err
+}
+
+const getUserLinkByLinkedID = `-- name: GetUserLinkByLinkedID :one
+SELECT
+	user_links.user_id, user_links.login_type, user_links.linked_id, user_links.oauth_access_token, user_links.oauth_refresh_token, user_links.oauth_expiry, user_links.oauth_access_token_key_id, user_links.oauth_refresh_token_key_id, user_links.claims
+FROM
+	user_links
+INNER JOIN
+	users ON user_links.user_id = users.id
+WHERE
+	linked_id = $1
+	AND
+	deleted = false
+`
+
+func (q *sqlQuerier) GetUserLinkByLinkedID(ctx context.Context, linkedID string) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, getUserLinkByLinkedID, linkedID)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.Claims,
+	)
+	return i, err
+}
+
+const getUserLinkByUserIDLoginType = `-- name: GetUserLinkByUserIDLoginType :one
+SELECT
+	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
+FROM
+	user_links
+WHERE
+	user_id = $1 AND login_type = $2
+`
+
+type GetUserLinkByUserIDLoginTypeParams struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	LoginType LoginType `db:"login_type" json:"login_type"`
+}
+
+func (q *sqlQuerier) GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, getUserLinkByUserIDLoginType, arg.UserID, arg.LoginType)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.Claims,
+	)
+	return i, err
+}
+
+const getUserLinksByUserID = `-- name: GetUserLinksByUserID :many
+SELECT user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims FROM user_links WHERE user_id = $1
+`
+
+func (q *sqlQuerier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]UserLink, error) {
+	rows, err := q.db.QueryContext(ctx, getUserLinksByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserLink
+	for rows.Next() {
+		var i UserLink
+		if err := rows.Scan(
+			&i.UserID,
+			&i.LoginType,
+			&i.LinkedID,
+			&i.OAuthAccessToken,
+			&i.OAuthRefreshToken,
+			&i.OAuthExpiry,
+			&i.OAuthAccessTokenKeyID,
+			&i.OAuthRefreshTokenKeyID,
+			&i.Claims,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertUserLink = `-- name: InsertUserLink :one
+INSERT INTO
+	user_links (
+		user_id,
+		login_type,
+		linked_id,
+		oauth_access_token,
+		oauth_access_token_key_id,
+		oauth_refresh_token,
+		oauth_refresh_token_key_id,
+		oauth_expiry,
+		claims
+	)
+VALUES
+	( $1, $2, $3, $4, $5, $6, $7, $8, $9 ) RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
+`
+
+type InsertUserLinkParams struct {
+	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
+	LoginType              LoginType      `db:"login_type" json:"login_type"`
+	LinkedID               string         `db:"linked_id" json:"linked_id"`
+	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
+	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
+	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
+	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
+	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
+	Claims                 UserLinkClaims `db:"claims" json:"claims"`
+}
+
+func (q *sqlQuerier) InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, insertUserLink,
+		arg.UserID,
+		arg.LoginType,
+		arg.LinkedID,
+		arg.OAuthAccessToken,
+		arg.OAuthAccessTokenKeyID,
+		arg.OAuthRefreshToken,
+		arg.OAuthRefreshTokenKeyID,
+		arg.OAuthExpiry,
+		arg.Claims,
+	)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.Claims,
+	)
+	return i, err
+}
+
+const oIDCClaimFieldValues = `-- name: OIDCClaimFieldValues :many
+SELECT
+	-- DISTINCT to remove duplicates
+	DISTINCT jsonb_array_elements_text(CASE
+		-- When the type is an array, filter out any non-string elements.
+		-- This is to keep the return type consistent.
+		WHEN jsonb_typeof(claims->'merged_claims'->$1::text) = 'array' THEN
+			(
+				SELECT
+					jsonb_agg(element)
+				FROM
+					jsonb_array_elements(claims->'merged_claims'->$1::text) AS element
+				WHERE
+					-- Filtering out non-string elements
+					jsonb_typeof(element) = 'string'
+			)
+		-- Some IDPs return a single string instead of an array of strings.
+		WHEN jsonb_typeof(claims->'merged_claims'->$1::text) = 'string' THEN
+			jsonb_build_array(claims->'merged_claims'->$1::text)
+	END)
+FROM
+	user_links
+WHERE
+	-- IDP sync only supports string and array (of string) types
+	jsonb_typeof(claims->'merged_claims'->$1::text) = ANY(ARRAY['string', 'array'])
+	AND login_type = 'oidc'
+	AND CASE
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid  THEN
+			user_links.user_id = ANY(SELECT organization_members.user_id FROM organization_members WHERE organization_id = $2)
+		ELSE true
+	END
+`
+
+type OIDCClaimFieldValuesParams struct {
+	ClaimField     string    `db:"claim_field" json:"claim_field"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) OIDCClaimFieldValues(ctx context.Context, arg OIDCClaimFieldValuesParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, oIDCClaimFieldValues, arg.ClaimField, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var jsonb_array_elements_text string
+		if err := rows.Scan(&jsonb_array_elements_text); err != nil {
+			return nil, err
+		}
+		items = append(items, jsonb_array_elements_text)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const oIDCClaimFields = `-- name: OIDCClaimFields :many
+SELECT
+	DISTINCT jsonb_object_keys(claims->'merged_claims')
+FROM
+	user_links
+WHERE
+    -- Only return rows where the top level key exists
+	claims ? 'merged_claims' AND
+    -- 'null' is the default value for the id_token_claims field
+	-- jsonb 'null' is not the same as SQL NULL. Strip these out.
+	jsonb_typeof(claims->'merged_claims') != 'null' AND
+	login_type = 'oidc'
+	AND CASE WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid  THEN
+		user_links.user_id = ANY(SELECT organization_members.user_id FROM organization_members WHERE organization_id = $1)
+		ELSE true
+	END
+`
+
+// OIDCClaimFields returns a list of distinct keys in the the merged_claims fields.
+// This query is used to generate the list of available sync fields for idp sync settings.
+func (q *sqlQuerier) OIDCClaimFields(ctx context.Context, organizationID uuid.UUID) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, oIDCClaimFields, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var jsonb_object_keys string
+		if err := rows.Scan(&jsonb_object_keys); err != nil {
+			return nil, err
+		}
+		items = append(items, jsonb_object_keys)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUserLink = `-- name: UpdateUserLink :one
+UPDATE
+	user_links
+SET
+	oauth_access_token = $1,
+	oauth_access_token_key_id = $2,
+	oauth_refresh_token = $3,
+	oauth_refresh_token_key_id = $4,
+	oauth_expiry = $5,
+	claims = $6
+WHERE
+	user_id = $7 AND login_type = $8 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
+`
+
+type UpdateUserLinkParams struct {
+	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
+	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
+	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
+	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
+	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
+	Claims                 UserLinkClaims `db:"claims" json:"claims"`
+	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
+	LoginType              LoginType      `db:"login_type" json:"login_type"`
+}
+
+func (q *sqlQuerier) UpdateUserLink(ctx context.Context, arg UpdateUserLinkParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLink,
+		arg.OAuthAccessToken,
+		arg.OAuthAccessTokenKeyID,
+		arg.OAuthRefreshToken,
+		arg.OAuthRefreshTokenKeyID,
+		arg.OAuthExpiry,
+		arg.Claims,
+		arg.UserID,
+		arg.LoginType,
+	)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.Claims,
+	)
+	return i, err
+}
+
+const updateUserLinkedID = `-- name: UpdateUserLinkedID :one
+UPDATE
+	user_links
+SET
+	linked_id = $1
+WHERE
+	user_id = $2 AND login_type = $3 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
+`
+
+type UpdateUserLinkedIDParams struct {
+	LinkedID  string    `db:"linked_id" json:"linked_id"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	LoginType LoginType `db:"login_type" json:"login_type"`
+}
+
+func (q *sqlQuerier) UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinkedIDParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLinkedID, arg.LinkedID, arg.UserID, arg.LoginType)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.Claims,
+	)
+	return i, err
+}
+
+const getUserCLIVersions = `-- name: GetUserCLIVersions :many
+SELECT
+	user_cli_versions.user_id,
+	users.username,
+	user_cli_versions.version,
+	user_cli_versions.last_seen_at
+FROM
+	user_cli_versions
+JOIN
+	users ON users.id = user_cli_versions.user_id
+WHERE
+	users.deleted = false
+	AND user_cli_versions.last_seen_at > $1 :: timestamptz
+ORDER BY
+	users.username
+`
+
+type GetUserCLIVersionsRow struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	Username   string    `db:"username" json:"username"`
+	Version    string    `db:"version" json:"version"`
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+}
+
+// Returns the CLI version each user last made a request with, if the request
+// was made after the given time.
+func (q *sqlQuerier) GetUserCLIVersions(ctx context.Context, seenAfter time.Time) ([]GetUserCLIVersionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserCLIVersions, seenAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserCLIVersionsRow
+	for rows.Next() {
+		var i GetUserCLIVersionsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.Version,
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertUserCLIVersion = `-- name: UpsertUserCLIVersion :exec
+INSERT INTO
+	user_cli_versions (user_id, version, last_seen_at)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE SET
+	version = $2,
+	last_seen_at = $3
+`
+
+type UpsertUserCLIVersionParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	Version    string    `db:"version" json:"version"`
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+}
+
+func (q *sqlQuerier) UpsertUserCLIVersion(ctx context.Context, arg UpsertUserCLIVersionParams) error {
+	_, err := q.db.ExecContext(ctx, upsertUserCLIVersion, arg.UserID, arg.Version, arg.LastSeenAt)
+	return err
+}
+
+const allUserIDs = `-- name: AllUserIDs :many
+SELECT DISTINCT id FROM USERS
+	WHERE CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
+`
+
+// AllUserIDs returns all UserIDs regardless of user status or deletion.
+func (q *sqlQuerier) AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, allUserIDs, includeSystem)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveUserCount = `-- name: GetActiveUserCount :one
+SELECT
+	COUNT(*)
+FROM
+	users
+WHERE
+	status = 'active'::user_status AND deleted = false
+	AND CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
+`
+
+func (q *sqlQuerier) GetActiveUserCount(ctx context.Context, includeSystem bool) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getActiveUserCount, includeSystem)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getAuthorizationUserRoles = `-- name: GetAuthorizationUserRoles :one
+SELECT
+	-- username and email are returned just to help for logging purposes
+	-- status is used to enforce 'suspended' users, as all roles are ignored
+	--	when suspended.
+	id, username, status, email,
+	-- All user roles, including their org roles.
+	array_cat(
+		-- All users are members
+		array_append(users.rbac_roles, 'member'),
+		(
+			SELECT
+				-- The roles are returned as a flat array, org scoped and site side.
+				-- Concatenating the organization id scopes the organization roles.
+				array_agg(org_roles || ':' || organization_members.organization_id::text)
+			FROM
+				organization_members,
+				-- All org_members get the organization-member role for their orgs
+				unnest(
+					array_append(roles, 'organization-member')
+				) AS org_roles
+			WHERE
+				user_id = users.id
+		)
+	) :: text[] AS roles,
+	-- All groups the user is in.
+	(
+		SELECT
+			array_agg(
+				group_members.group_id :: text
+			)
+		FROM
+			group_members
+		WHERE
+			user_id = users.id
+	) :: text[] AS groups
+FROM
+	users
+WHERE
+	id = $1
+`
+
+type GetAuthorizationUserRolesRow struct {
+	ID       uuid.UUID  `db:"id" json:"id"`
+	Username string     `db:"username" json:"username"`
+	Status   UserStatus `db:"status" json:"status"`
+	Email    string     `db:"email" json:"email"`
+	Roles    []string   `db:"roles" json:"roles"`
+	Groups   []string   `db:"groups" json:"groups"`
+}
+
+// This function returns roles for authorization purposes. Implied member roles
+// are included.
+func (q *sqlQuerier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (GetAuthorizationUserRolesRow, error) {
+	row := q.db.QueryRowContext(ctx, getAuthorizationUserRoles, userID)
+	var i GetAuthorizationUserRolesRow
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Status,
+		&i.Email,
+		pq.Array(&i.Roles),
+		pq.Array(&i.Groups),
+	)
+	return i, err
+}
+
+const getUserByEmailOrUsername = `-- name: GetUserByEmailOrUsername :one
+SELECT
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
+FROM
+	users
+WHERE
+	(LOWER(username) = LOWER($1) OR LOWER(email) = LOWER($2)) AND
+	deleted = false
+LIMIT
+	1
+`
+
+type GetUserByEmailOrUsernameParams struct {
+	Username string `db:"username" json:"username"`
+	Email    string `db:"email" json:"email"`
+}
+
+func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserByEmailOrUsernameParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmailOrUsername, arg.Username, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.GithubComUserID,
+		&i.HashedOneTimePasscode,
+		&i.OneTimePasscodeExpiresAt,
+		&i.IsSystem,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
+FROM
+	users
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.GithubComUserID,
+		&i.HashedOneTimePasscode,
+		&i.OneTimePasscodeExpiresAt,
+		&i.IsSystem,
+	)
+	return i, err
+}
+
+const getUserCount = `-- name: GetUserCount :one
+SELECT
+	COUNT(*)
+FROM
+	users
+WHERE
+	deleted = false
+  	AND CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
+`
+
+func (q *sqlQuerier) GetUserCount(ctx context.Context, includeSystem bool) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getUserCount, includeSystem)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getUserNotificationDigest = `-- name: GetUserNotificationDigest :one
+SELECT
+	value as notification_digest
+FROM
+	user_configs
+WHERE
+	user_id = $1
+	AND key = 'notification_digest'
+`
+
+func (q *sqlQuerier) GetUserNotificationDigest(ctx context.Context, userID uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserNotificationDigest, userID)
+	var notification_digest string
+	err := row.Scan(&notification_digest)
+	return notification_digest, err
+}
+
+const getUserProxyLatencies = `-- name: GetUserProxyLatencies :one
+SELECT
+	value as proxy_latencies
+FROM
+	user_configs
+WHERE
+	user_id = $1
+	AND key = 'proxy_latencies'
+`
+
+func (q *sqlQuerier) GetUserProxyLatencies(ctx context.Context, userID uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserProxyLatencies, userID)
+	var proxy_latencies string
+	err := row.Scan(&proxy_latencies)
+	return proxy_latencies, err
+}
+
+const getUserProxyPreference = `-- name: GetUserProxyPreference :one
+SELECT
+	value as proxy_preference
+FROM
+	user_configs
+WHERE
+	user_id = $1
+	AND key = 'proxy_preference'
+`
+
+func (q *sqlQuerier) GetUserProxyPreference(ctx context.Context, userID uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserProxyPreference, userID)
+	var proxy_preference string
+	err := row.Scan(&proxy_preference)
+	return proxy_preference, err
+}
+
+const getUserStartOnConnect = `-- name: GetUserStartOnConnect :one
+SELECT
+	value as start_on_connect
+FROM
+	user_configs
+WHERE
+	user_id = $1
+	AND key = 'start_on_connect'
+`
+
+func (q *sqlQuerier) GetUserStartOnConnect(ctx context.Context, userID uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserStartOnConnect, userID)
+	var start_on_connect string
+	err := row.Scan(&start_on_connect)
+	return start_on_connect, err
+}
+
+const getUserTerminalFont = `-- name: GetUserTerminalFont :one
+SELECT
+	value as terminal_font
+FROM
+	user_configs
+WHERE
+	user_id = $1
+	AND key = 'terminal_font'
+`
+
+func (q *sqlQuerier) GetUserTerminalFont(ctx context.Context, userID uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserTerminalFont, userID)
+	var terminal_font string
+	err := row.Scan(&terminal_font)
+	return terminal_font, err
+}
+
+const getUserThemePreference = `-- name: GetUserThemePreference :one
+SELECT
+	value as theme_preference
+FROM
+	user_configs
+WHERE
+	user_id = $1
+	AND key = 'theme_preference'
+`
+
+func (q *sqlQuerier) GetUserThemePreference(ctx context.Context, userID uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserThemePreference, userID)
+	var theme_preference string
+	err := row.Scan(&theme_preference)
+	return theme_preference, err
+}
+
+const getUsers = `-- name: GetUsers :many
+SELECT
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, COUNT(*) OVER() AS count
+FROM
+	users
+WHERE
+	users.deleted = false
+	AND CASE
+		-- This allows using the last element on a page as effectively a cursor.
+		-- This is an important option for scripts that need to paginate without
+		-- duplicating or missing data.
+		WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			-- The pagination cursor is the last ID of the previous page.
+			-- The query is ordered by the username field, so select all
+			-- rows after the cursor.
+			(LOWER(username)) > (
+				SELECT
+					LOWER(username)
+				FROM
+					users
+				WHERE
+					id = $1
+			)
+		)
+		ELSE true
+	END
+	-- Start filters
+	-- Filter by name, email or username
+	AND CASE
+		WHEN $2 :: text != '' THEN (
+			email ILIKE concat('%', $2, '%')
+			OR username ILIKE concat('%', $2, '%')
+		)
+		ELSE true
+	END
+	-- Filter by status
+	AND CASE
+		-- @status needs to be a text because it can be empty, If it was
+		-- user_status enum, it would not.
+		WHEN cardinality($3 :: user_status[]) > 0 THEN
+			status = ANY($3 :: user_status[])
+		ELSE true
+	END
+	-- Filter by rbac_roles
+	AND CASE
+		-- @rbac_role allows filtering by rbac roles. If 'member' is included, show everyone, as
+		-- everyone is a member.
+		WHEN cardinality($4 :: text[]) > 0 AND 'member' != ANY($4 :: text[]) THEN
+			rbac_roles && $4 :: text[]
+		ELSE true
+	END
+	-- Filter by last_seen
+	AND CASE
+		WHEN $5 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			last_seen_at <= $5
+		ELSE true
+	END
+	AND CASE
+		WHEN $6 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			last_seen_at >= $6
+		ELSE true
+	END
+	-- Filter by created_at
+	AND CASE
+		WHEN $7 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			created_at <= $7
+		ELSE true
+	END
+	AND CASE
+		WHEN $8 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			created_at >= $8
+		ELSE true
+	END
+  	AND CASE
+  	    WHEN $9::bool THEN TRUE
+  	    ELSE
+			is_system = false
+	END
+	AND CASE
+		WHEN $10 :: bigint != 0 THEN
+			github_com_user_id = $10
+		ELSE true
+	END
+	-- Filter by login_type
+	AND CASE
+		WHEN cardinality($11 :: login_type[]) > 0 THEN
+			login_type = ANY($11 :: login_type[])
+		ELSE true
+	END
+	-- End of filters
+
+	-- Authorize Filter clause will be injected below in GetAuthorizedUsers
+	-- @authorize_filter
+ORDER BY
+	-- Deterministic and consistent ordering of all users. This is to ensure consistent pagination.
+	LOWER(username) ASC OFFSET $12
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($13 :: int, 0)
+`
+
+type GetUsersParams struct {
+	AfterID         uuid.UUID    `db:"after_id" json:"after_id"`
+	Search          string       `db:"search" json:"search"`
+	Status          []UserStatus `db:"status" json:"status"`
+	RbacRole        []string     `db:"rbac_role" json:"rbac_role"`
+	LastSeenBefore  time.Time    `db:"last_seen_before" json:"last_seen_before"`
+	LastSeenAfter   time.Time    `db:"last_seen_after" json:"last_seen_after"`
+	CreatedBefore   time.Time    `db:"created_before" json:"created_before"`
+	CreatedAfter    time.Time    `db:"created_after" json:"created_after"`
+	IncludeSystem   bool         `db:"include_system" json:"include_system"`
+	GithubComUserID int64        `db:"github_com_user_id" json:"github_com_user_id"`
+	LoginType       []LoginType  `db:"login_type" json:"login_type"`
+	OffsetOpt       int32        `db:"offset_opt" json:"offset_opt"`
+	LimitOpt        int32        `db:"limit_opt" json:"limit_opt"`
+}
+
+type GetUsersRow struct {
+	ID                       uuid.UUID      `db:"id" json:"id"`
+	Email                    string         `db:"email" json:"email"`
+	Username                 string         `db:"username" json:"username"`
+	HashedPassword           []byte         `db:"hashed_password" json:"hashed_password"`
+	CreatedAt                time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt                time.Time      `db:"updated_at" json:"updated_at"`
+	Status                   UserStatus     `db:"status" json:"status"`
+	RBACRoles                pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
+	LoginType                LoginType      `db:"login_type" json:"login_type"`
+	AvatarURL                string         `db:"avatar_url" json:"avatar_url"`
+	Deleted                  bool           `db:"deleted" json:"deleted"`
+	LastSeenAt               time.Time      `db:"last_seen_at" json:"last_seen_at"`
+	QuietHoursSchedule       string         `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+	Name                     string         `db:"name" json:"name"`
+	GithubComUserID          sql.NullInt64  `db:"github_com_user_id" json:"github_com_user_id"`
+	HashedOneTimePasscode    []byte         `db:"hashed_one_time_passcode" json:"hashed_one_time_passcode"`
+	OneTimePasscodeExpiresAt sql.NullTime   `db:"one_time_passcode_expires_at" json:"one_time_passcode_expires_at"`
+	IsSystem                 bool           `db:"is_system" json:"is_system"`
+	Count                    int64          `db:"count" json:"count"`
+}
+
+// This will never return deleted users.
+func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUsers,
+		arg.AfterID,
+		arg.Search,
+		pq.Array(arg.Status),
+		pq.Array(arg.RbacRole),
+		arg.LastSeenBefore,
+		arg.LastSeenAfter,
+		arg.CreatedBefore,
+		arg.CreatedAfter,
+		arg.IncludeSystem,
+		arg.GithubComUserID,
+		pq.Array(arg.LoginType),
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUsersRow
+	for rows.Next() {
+		var i GetUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RBACRoles,
+			&i.LoginType,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.LastSeenAt,
+			&i.QuietHoursSchedule,
+			&i.Name,
+			&i.GithubComUserID,
+			&i.HashedOneTimePasscode,
+			&i.OneTimePasscodeExpiresAt,
+			&i.IsSystem,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsersByIDs = `-- name: GetUsersByIDs :many
+SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system FROM users WHERE id = ANY($1 :: uuid [ ])
+`
+
+// This shouldn't check for deleted, because it's frequently used
+// to look up references to actions. eg. a user could build a workspace
+// for another user, then be deleted... we still want them to appear!
+func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsersByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RBACRoles,
+			&i.LoginType,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.LastSeenAt,
+			&i.QuietHoursSchedule,
+			&i.Name,
+			&i.GithubComUserID,
+			&i.HashedOneTimePasscode,
+			&i.OneTimePasscodeExpiresAt,
+			&i.IsSystem,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertUser = `-- name: InsertUser :one
+INSERT INTO
+	users (
+		id,
+		email,
+		username,
+		name,
+		hashed_password,
+		created_at,
+		updated_at,
+		rbac_roles,
+		login_type,
+		status
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9,
+		-- if the status passed in is empty, fallback to dormant, which is what
+		-- we were doing before.
+		COALESCE(NULLIF($10::text, '')::user_status, 'dormant'::user_status)
+	) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
+`
+
+type InsertUserParams struct {
+	ID             uuid.UUID      `db:"id" json:"id"`
+	Email          string         `db:"email" json:"email"`
+	Username       string         `db:"username" json:"username"`
+	Name           string         `db:"name" json:"name"`
+	HashedPassword []byte         `db:"hashed_password" json:"hashed_password"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+	RBACRoles      pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
+	LoginType      LoginType      `db:"login_type" json:"login_type"`
+	Status         string         `db:"status" json:"status"`
+}
+
+func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, insertUser,
+		arg.ID,
+		arg.Email,
+		arg.Username,
+		arg.Name,
+		arg.HashedPassword,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.RBACRoles,
+		arg.LoginType,
+		arg.Status,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.GithubComUserID,
+		&i.HashedOneTimePasscode,
+		&i.OneTimePasscodeExpiresAt,
+		&i.IsSystem,
+	)
+	return i, err
+}
+
+const updateInactiveUsersToDormant = `-- name: UpdateInactiveUsersToDormant :many
+UPDATE
+    users
+SET
+    status = 'dormant'::user_status,
+    updated_at = $1
+WHERE
+    last_seen_at < $2 :: timestamp
+    AND status = 'active'::user_status
+		AND NOT is_system
+RETURNING id, email, username, last_seen_at
+`
+
+type UpdateInactiveUsersToDormantParams struct {
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+	LastSeenAfter time.Time `db:"last_seen_after" json:"last_seen_after"`
+}
+
+type UpdateInactiveUsersToDormantRow struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	Email      string    `db:"email" json:"email"`
+	Username   string    `db:"username" json:"username"`
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+}
+
+func (q *sqlQuerier) UpdateInactiveUsersToDormant(ctx context.Context, arg UpdateInactiveUsersToDormantParams) ([]UpdateInactiveUsersToDormantRow, error) {
+	rows, err := q.db.QueryContext(ctx, updateInactiveUsersToDormant, arg.UpdatedAt, arg.LastSeenAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UpdateInactiveUsersToDormantRow
+	for rows.Next() {
+		var i UpdateInactiveUsersToDormantRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUserDeletedByID = `-- name: UpdateUserDeletedByID :exec
+UPDATE
+	users
+SET
+	deleted = true
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) UpdateUserDeletedByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, updateUserDeletedByID, id)
+	return err
+}
+
+const updateUserGithubComUserID = `-- name: UpdateUserGithubComUserID :exec
+UPDATE
+	users
+SET
+	github_com_user_id = $2
+WHERE
+	id = $1
+`
+
+type UpdateUserGithubComUserIDParams struct {
+	ID              uuid.UUID     `db:"id" json:"id"`
+	GithubComUserID sql.NullInt64 `db:"github_com_user_id" json:"github_com_user_id"`
+}
+
+func (q *sqlQuerier) UpdateUserGithubComUserID(ctx context.Context, arg UpdateUserGithubComUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserGithubComUserID, arg.ID, arg.GithubComUserID)
+	return err
+}
+
+const updateUserHashedOneTimePasscode = `-- name: UpdateUserHashedOneTimePasscode :exec
+UPDATE
+    users
+SET
+    hashed_one_time_passcode = $2,
+    one_time_passcode_expires_at = $3
+WHERE
+    id = $1
+`
+
+type UpdateUserHashedOneTimePasscodeParams struct {
+	ID                       uuid.UUID    `db:"id" json:"id"`
+	HashedOneTimePasscode    []byte       `db:"hashed_one_time_passcode" json:"hashed_one_time_passcode"`
+	OneTimePasscodeExpiresAt sql.NullTime `db:"one_time_passcode_expires_at" json:"one_time_passcode_expires_at"`
+}
+
+func (q *sqlQuerier) UpdateUserHashedOneTimePasscode(ctx context.Context, arg UpdateUserHashedOneTimePasscodeParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserHashedOneTimePasscode, arg.ID, arg.HashedOneTimePasscode, arg.OneTimePasscodeExpiresAt)
+	return err
+}
+
+const updateUserHashedPassword = `-- name: UpdateUserHashedPassword :exec
+UPDATE
+	users
+SET
+	hashed_password = $2,
+	hashed_one_time_passcode = NULL,
+	one_time_passcode_expires_at = NULL
+WHERE
+	id = $1
+`
+
+type UpdateUserHashedPasswordParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	HashedPassword []byte    `db:"hashed_password" json:"hashed_password"`
+}
+
+func (q *sqlQuerier) UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserHashedPassword, arg.ID, arg.HashedPassword)
+	return err
+}
+
+const updateUserLastSeenAt = `-- name: UpdateUserLastSeenAt :one
+UPDATE
+	users
+SET
+	last_seen_at = $2,
+	updated_at = $3
+WHERE
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
+`
+
+type UpdateUserLastSeenAtParams struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLastSeenAtParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLastSeenAt, arg.ID, arg.LastSeenAt, arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.GithubComUserID,
+		&i.HashedOneTimePasscode,
+		&i.OneTimePasscodeExpiresAt,
+		&i.IsSystem,
+	)
+	return i, err
+}
+
+const updateUserLoginType = `-- name: UpdateUserLoginType :one
+UPDATE
+	users
+SET
+	login_type = $1,
+	hashed_password = CASE WHEN $1 = 'password' :: login_type THEN
+		users.hashed_password
+	ELSE
+		-- If the login type is not password, then the password should be
+        -- cleared.
+		'':: bytea
+	END
+WHERE
+	id = $2
+	AND NOT is_system
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
+`
+
+type UpdateUserLoginTypeParams struct {
+	NewLoginType LoginType `db:"new_login_type" json:"new_login_type"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) UpdateUserLoginType(ctx context.Context, arg UpdateUserLoginTypeParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLoginType, arg.NewLoginType, arg.UserID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.GithubComUserID,
+		&i.HashedOneTimePasscode,
+		&i.OneTimePasscodeExpiresAt,
+		&i.IsSystem,
+	)
+	return i, err
+}
+
+const updateUserNotificationDigest = `-- name: UpdateUserNotificationDigest :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'notification_digest', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'notification_digest'
+RETURNING user_id, key, value
+`
+
+type UpdateUserNotificationDigestParams struct {
+	UserID             uuid.UUID `db:"user_id" json:"user_id"`
+	NotificationDigest string    `db:"notification_digest" json:"notification_digest"`
+}
+
+func (q *sqlQuerier) UpdateUserNotificationDigest(ctx context.Context, arg UpdateUserNotificationDigestParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserNotificationDigest, arg.UserID, arg.NotificationDigest)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
 UPDATE
-	user_links
+	users
 SET
-	oauth_access_token = $1,
-	oauth_access_token_key_id = $2,
-	oauth_refresh_token = $3,
-	oauth_refresh_token_key_id = $4,
-	oauth_expiry = $5,
-	claims = $6
+	email = $2,
+	username = $3,
+	avatar_url = $4,
+	updated_at = $5,
+	name = $6
 WHERE
-	user_id = $7 AND login_type = $8 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
+	id = $1
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
 `
 
-type UpdateUserLinkParams struct {
-	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
-	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
-	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
-	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
-	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
-	Claims                 UserLinkClaims `db:"claims" json:"claims"`
-	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
-	LoginType              LoginType      `db:"login_type" json:"login_type"`
+type UpdateUserProfileParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Email     string    `db:"email" json:"email"`
+	Username  string    `db:"username" json:"username"`
+	AvatarURL string    `db:"avatar_url" json:"avatar_url"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	Name      string    `db:"name" json:"name"`
 }
 
-func (q *sqlQuerier) UpdateUserLink(ctx context.Context, arg UpdateUserLinkParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLink,
-		arg.OAuthAccessToken,
-		arg.OAuthAccessTokenKeyID,
-		arg.OAuthRefreshToken,
-		arg.OAuthRefreshTokenKeyID,
-		arg.OAuthExpiry,
-		arg.Claims,
-		arg.UserID,
-		arg.LoginType,
+func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserProfile,
+		arg.ID,
+		arg.Email,
+		arg.Username,
+		arg.AvatarURL,
+		arg.UpdatedAt,
+		arg.Name,
 	)
-	var i UserLink
+	var i User
 	err := row.Scan(
-		&i.UserID,
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
 		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.Claims,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.GithubComUserID,
+		&i.HashedOneTimePasscode,
+		&i.OneTimePasscodeExpiresAt,
+		&i.IsSystem,
 	)
 	return i, err
 }
 
-const updateUserLinkedID = `-- name: UpdateUserLinkedID :one
-UPDATE
-	user_links
+const updateUserProxyLatencies = `-- name: UpdateUserProxyLatencies :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'proxy_latencies', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
 SET
-	linked_id = $1
-WHERE
-	user_id = $2 AND login_type = $3 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, claims
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'proxy_latencies'
+RETURNING user_id, key, value
 `
 
-type UpdateUserLinkedIDParams struct {
-	LinkedID  string    `db:"linked_id" json:"linked_id"`
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	LoginType LoginType `db:"login_type" json:"login_type"`
+type UpdateUserProxyLatenciesParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	ProxyLatencies string    `db:"proxy_latencies" json:"proxy_latencies"`
 }
 
-func (q *sqlQuerier) UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinkedIDParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLinkedID, arg.LinkedID, arg.UserID, arg.LoginType)
-	var i UserLink
-	err := row.Scan(
-		&i.UserID,
-		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.Claims,
-	)
+func (q *sqlQuerier) UpdateUserProxyLatencies(ctx context.Context, arg UpdateUserProxyLatenciesParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserProxyLatencies, arg.UserID, arg.ProxyLatencies)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
 	return i, err
 }
 
-const allUserIDs = `-- name: AllUserIDs :many
-SELECT DISTINCT id FROM USERS
-	WHERE CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
+const updateUserProxyPreference = `-- name: UpdateUserProxyPreference :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'proxy_preference', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'proxy_preference'
+RETURNING user_id, key, value
 `
 
-// AllUserIDs returns all UserIDs regardless of user status or deletion.
-func (q *sqlQuerier) AllUserIDs(ctx context.Context, includeSystem bool) ([]uuid.UUID, error) {
-	rows, err := q.db.QueryContext(ctx, allUserIDs, includeSystem)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []uuid.UUID
-	for rows.Next() {
-		var id uuid.UUID
-		if err := rows.Scan(&id); err != nil {
-			return nil, err
-		}
-		items = append(items, id)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type UpdateUserProxyPreferenceParams struct {
+	UserID          uuid.UUID `db:"user_id" json:"user_id"`
+	ProxyPreference string    `db:"proxy_preference" json:"proxy_preference"`
 }
 
-const getActiveUserCount = `-- name: GetActiveUserCount :one
-SELECT
-	COUNT(*)
-FROM
-	users
-WHERE
-	status = 'active'::user_status AND deleted = false
-	AND CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
-`
-
-func (q *sqlQuerier) GetActiveUserCount(ctx context.Context, includeSystem bool) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getActiveUserCount, includeSystem)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+func (q *sqlQuerier) UpdateUserProxyPreference(ctx context.Context, arg UpdateUserProxyPreferenceParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserProxyPreference, arg.UserID, arg.ProxyPreference)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
 }
 
-const getAuthorizationUserRoles = `-- name: GetAuthorizationUserRoles :one
-SELECT
-	-- username and email are returned just to help for logging purposes
-	-- status is used to enforce 'suspended' users, as all roles are ignored
-	--	when suspended.
-	id, username, status, email,
-	-- All user roles, including their org roles.
-	array_cat(
-		-- All users are members
-		array_append(users.rbac_roles, 'member'),
-		(
-			SELECT
-				-- The roles are returned as a flat array, org scoped and site side.
-				-- Concatenating the organization id scopes the organization roles.
-				array_agg(org_roles || ':' || organization_members.organization_id::text)
-			FROM
-				organization_members,
-				-- All org_members get the organization-member role for their orgs
-				unnest(
-					array_append(roles, 'organization-member')
-				) AS org_roles
-			WHERE
-				user_id = users.id
-		)
-	) :: text[] AS roles,
-	-- All groups the user is in.
-	(
-		SELECT
-			array_agg(
-				group_members.group_id :: text
-			)
-		FROM
-			group_members
-		WHERE
-			user_id = users.id
-	) :: text[] AS groups
-FROM
+const updateUserQuietHoursSchedule = `-- name: UpdateUserQuietHoursSchedule :one
+UPDATE
 	users
+SET
+	quiet_hours_schedule = $2
 WHERE
 	id = $1
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
 `
 
-type GetAuthorizationUserRolesRow struct {
-	ID       uuid.UUID  `db:"id" json:"id"`
-	Username string     `db:"username" json:"username"`
-	Status   UserStatus `db:"status" json:"status"`
-	Email    string     `db:"email" json:"email"`
-	Roles    []string   `db:"roles" json:"roles"`
-	Groups   []string   `db:"groups" json:"groups"`
+type UpdateUserQuietHoursScheduleParams struct {
+	ID                 uuid.UUID `db:"id" json:"id"`
+	QuietHoursSchedule string    `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
 }
 
-// This function returns roles for authorization purposes. Implied member roles
-// are included.
-func (q *sqlQuerier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (GetAuthorizationUserRolesRow, error) {
-	row := q.db.QueryRowContext(ctx, getAuthorizationUserRoles, userID)
-	var i GetAuthorizationUserRolesRow
+func (q *sqlQuerier) UpdateUserQuietHoursSchedule(ctx context.Context, arg UpdateUserQuietHoursScheduleParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserQuietHoursSchedule, arg.ID, arg.QuietHoursSchedule)
+	var i User
 	err := row.Scan(
 		&i.ID,
+		&i.Email,
 		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 		&i.Status,
-		&i.Email,
-		pq.Array(&i.Roles),
-		pq.Array(&i.Groups),
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.GithubComUserID,
+		&i.HashedOneTimePasscode,
+		&i.OneTimePasscodeExpiresAt,
+		&i.IsSystem,
 	)
 	return i, err
 }
 
-const getUserByEmailOrUsername = `-- name: GetUserByEmailOrUsername :one
-SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
-FROM
+const updateUserRoles = `-- name: UpdateUserRoles :one
+UPDATE
 	users
+SET
+	-- Remove all duplicates from the roles.
+	rbac_roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
 WHERE
-	(LOWER(username) = LOWER($1) OR LOWER(email) = LOWER($2)) AND
-	deleted = false
-LIMIT
-	1
+	id = $2
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
 `
 
-type GetUserByEmailOrUsernameParams struct {
-	Username string `db:"username" json:"username"`
-	Email    string `db:"email" json:"email"`
+type UpdateUserRolesParams struct {
+	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
+	ID           uuid.UUID `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserByEmailOrUsernameParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByEmailOrUsername, arg.Username, arg.Email)
+func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserRoles, pq.Array(arg.GrantedRoles), arg.ID)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -13327,19 +19557,51 @@ func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserBy
 	return i, err
 }
 
-const getUserByID = `-- name: GetUserByID :one
-SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
-FROM
+const updateUserStartOnConnect = `-- name: UpdateUserStartOnConnect :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'start_on_connect', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'start_on_connect'
+RETURNING user_id, key, value
+`
+
+type UpdateUserStartOnConnectParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	StartOnConnect string    `db:"start_on_connect" json:"start_on_connect"`
+}
+
+func (q *sqlQuerier) UpdateUserStartOnConnect(ctx context.Context, arg UpdateUserStartOnConnectParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserStartOnConnect, arg.UserID, arg.StartOnConnect)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
+}
+
+const updateUserStatus = `-- name: UpdateUserStatus :one
+UPDATE
 	users
+SET
+	status = $2,
+	updated_at = $3
 WHERE
-	id = $1
-LIMIT
-	1
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
 `
 
-func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByID, id)
+type UpdateUserStatusParams struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	Status    UserStatus `db:"status" json:"status"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -13364,240 +19626,340 @@ func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error
 	return i, err
 }
 
-const getUserCount = `-- name: GetUserCount :one
-SELECT
-	COUNT(*)
-FROM
-	users
+const updateUserTerminalFont = `-- name: UpdateUserTerminalFont :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'terminal_font', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'terminal_font'
+RETURNING user_id, key, value
+`
+
+type UpdateUserTerminalFontParams struct {
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	TerminalFont string    `db:"terminal_font" json:"terminal_font"`
+}
+
+func (q *sqlQuerier) UpdateUserTerminalFont(ctx context.Context, arg UpdateUserTerminalFontParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserTerminalFont, arg.UserID, arg.TerminalFont)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
+}
+
+const updateUserThemePreference = `-- name: UpdateUserThemePreference :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	($1, 'theme_preference', $2)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = $2
+WHERE user_configs.user_id = $1
+	AND user_configs.key = 'theme_preference'
+RETURNING user_id, key, value
+`
+
+type UpdateUserThemePreferenceParams struct {
+	UserID          uuid.UUID `db:"user_id" json:"user_id"`
+	ThemePreference string    `db:"theme_preference" json:"theme_preference"`
+}
+
+func (q *sqlQuerier) UpdateUserThemePreference(ctx context.Context, arg UpdateUserThemePreferenceParams) (UserConfig, error) {
+	row := q.db.QueryRowContext(ctx, updateUserThemePreference, arg.UserID, arg.ThemePreference)
+	var i UserConfig
+	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+	return i, err
+}
+
+const deleteUserSecret = `-- name: DeleteUserSecret :exec
+DELETE FROM
+	user_secrets
+WHERE
+	user_id = $1
+	AND name = $2
+`
+
+type DeleteUserSecretParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Name   string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) DeleteUserSecret(ctx context.Context, arg DeleteUserSecretParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUserSecret, arg.UserID, arg.Name)
+	return err
+}
+
+const getUserSecretByUserIDAndName = `-- name: GetUserSecretByUserIDAndName :one
+SELECT
+	id, user_id, name, description, value, value_key_id, env_name, created_at, updated_at
+FROM
+	user_secrets
+WHERE
+	user_id = $1
+	AND name = $2
+`
+
+type GetUserSecretByUserIDAndNameParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Name   string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetUserSecretByUserIDAndName(ctx context.Context, arg GetUserSecretByUserIDAndNameParams) (UserSecret, error) {
+	row := q.db.QueryRowContext(ctx, getUserSecretByUserIDAndName, arg.UserID, arg.Name)
+	var i UserSecret
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.EnvName,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserSecretsByUserID = `-- name: GetUserSecretsByUserID :many
+SELECT
+	id, user_id, name, description, value, value_key_id, env_name, created_at, updated_at
+FROM
+	user_secrets
+WHERE
+	user_id = $1
+ORDER BY
+	name ASC
+`
+
+func (q *sqlQuerier) GetUserSecretsByUserID(ctx context.Context, userID uuid.UUID) ([]UserSecret, error) {
+	rows, err := q.db.QueryContext(ctx, getUserSecretsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserSecret
+	for rows.Next() {
+		var i UserSecret
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Description,
+			&i.Value,
+			&i.ValueKeyID,
+			&i.EnvName,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertUserSecret = `-- name: InsertUserSecret :one
+INSERT INTO
+	user_secrets (
+		id,
+		user_id,
+		name,
+		description,
+		value,
+		value_key_id,
+		env_name,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, user_id, name, description, value, value_key_id, env_name, created_at, updated_at
+`
+
+type InsertUserSecretParams struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	UserID      uuid.UUID      `db:"user_id" json:"user_id"`
+	Name        string         `db:"name" json:"name"`
+	Description string         `db:"description" json:"description"`
+	Value       string         `db:"value" json:"value"`
+	ValueKeyID  sql.NullString `db:"value_key_id" json:"value_key_id"`
+	EnvName     string         `db:"env_name" json:"env_name"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) InsertUserSecret(ctx context.Context, arg InsertUserSecretParams) (UserSecret, error) {
+	row := q.db.QueryRowContext(ctx, insertUserSecret,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.Description,
+		arg.Value,
+		arg.ValueKeyID,
+		arg.EnvName,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i UserSecret
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.EnvName,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateUserSecret = `-- name: UpdateUserSecret :one
+UPDATE
+	user_secrets
+SET
+	description = $1,
+	value = $2,
+	value_key_id = $3,
+	env_name = $4,
+	updated_at = $5
+WHERE
+	user_id = $6
+	AND name = $7
+RETURNING id, user_id, name, description, value, value_key_id, env_name, created_at, updated_at
+`
+
+type UpdateUserSecretParams struct {
+	Description string         `db:"description" json:"description"`
+	Value       string         `db:"value" json:"value"`
+	ValueKeyID  sql.NullString `db:"value_key_id" json:"value_key_id"`
+	EnvName     string         `db:"env_name" json:"env_name"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	UserID      uuid.UUID      `db:"user_id" json:"user_id"`
+	Name        string         `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) UpdateUserSecret(ctx context.Context, arg UpdateUserSecretParams) (UserSecret, error) {
+	row := q.db.QueryRowContext(ctx, updateUserSecret,
+		arg.Description,
+		arg.Value,
+		arg.ValueKeyID,
+		arg.EnvName,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.Name,
+	)
+	var i UserSecret
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.EnvName,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteTemplateVariableSet = `-- name: DeleteTemplateVariableSet :exec
+DELETE FROM
+	template_variable_sets
 WHERE
-	deleted = false
-  	AND CASE WHEN $1::bool THEN TRUE ELSE is_system = false END
+	template_id = $1
+	AND variable_set_id = $2
 `
 
-func (q *sqlQuerier) GetUserCount(ctx context.Context, includeSystem bool) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getUserCount, includeSystem)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+type DeleteTemplateVariableSetParams struct {
+	TemplateID    uuid.UUID `db:"template_id" json:"template_id"`
+	VariableSetID uuid.UUID `db:"variable_set_id" json:"variable_set_id"`
 }
 
-const getUserTerminalFont = `-- name: GetUserTerminalFont :one
-SELECT
-	value as terminal_font
-FROM
-	user_configs
+func (q *sqlQuerier) DeleteTemplateVariableSet(ctx context.Context, arg DeleteTemplateVariableSetParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateVariableSet, arg.TemplateID, arg.VariableSetID)
+	return err
+}
+
+const deleteVariableSet = `-- name: DeleteVariableSet :exec
+DELETE FROM
+	variable_sets
 WHERE
-	user_id = $1
-	AND key = 'terminal_font'
+	id = $1
 `
 
-func (q *sqlQuerier) GetUserTerminalFont(ctx context.Context, userID uuid.UUID) (string, error) {
-	row := q.db.QueryRowContext(ctx, getUserTerminalFont, userID)
-	var terminal_font string
-	err := row.Scan(&terminal_font)
-	return terminal_font, err
+func (q *sqlQuerier) DeleteVariableSet(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteVariableSet, id)
+	return err
 }
 
-const getUserThemePreference = `-- name: GetUserThemePreference :one
+const getVariableSetByName = `-- name: GetVariableSetByName :one
 SELECT
-	value as theme_preference
+	id, name, description, variables, variables_key_id, created_at, updated_at
 FROM
-	user_configs
+	variable_sets
 WHERE
-	user_id = $1
-	AND key = 'theme_preference'
+	name = $1
 `
 
-func (q *sqlQuerier) GetUserThemePreference(ctx context.Context, userID uuid.UUID) (string, error) {
-	row := q.db.QueryRowContext(ctx, getUserThemePreference, userID)
-	var theme_preference string
-	err := row.Scan(&theme_preference)
-	return theme_preference, err
+func (q *sqlQuerier) GetVariableSetByName(ctx context.Context, name string) (VariableSet, error) {
+	row := q.db.QueryRowContext(ctx, getVariableSetByName, name)
+	var i VariableSet
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Variables,
+		&i.VariablesKeyID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const getUsers = `-- name: GetUsers :many
+const getVariableSets = `-- name: GetVariableSets :many
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system, COUNT(*) OVER() AS count
+	id, name, description, variables, variables_key_id, created_at, updated_at
 FROM
-	users
-WHERE
-	users.deleted = false
-	AND CASE
-		-- This allows using the last element on a page as effectively a cursor.
-		-- This is an important option for scripts that need to paginate without
-		-- duplicating or missing data.
-		WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
-			-- The pagination cursor is the last ID of the previous page.
-			-- The query is ordered by the username field, so select all
-			-- rows after the cursor.
-			(LOWER(username)) > (
-				SELECT
-					LOWER(username)
-				FROM
-					users
-				WHERE
-					id = $1
-			)
-		)
-		ELSE true
-	END
-	-- Start filters
-	-- Filter by name, email or username
-	AND CASE
-		WHEN $2 :: text != '' THEN (
-			email ILIKE concat('%', $2, '%')
-			OR username ILIKE concat('%', $2, '%')
-		)
-		ELSE true
-	END
-	-- Filter by status
-	AND CASE
-		-- @status needs to be a text because it can be empty, If it was
-		-- user_status enum, it would not.
-		WHEN cardinality($3 :: user_status[]) > 0 THEN
-			status = ANY($3 :: user_status[])
-		ELSE true
-	END
-	-- Filter by rbac_roles
-	AND CASE
-		-- @rbac_role allows filtering by rbac roles. If 'member' is included, show everyone, as
-		-- everyone is a member.
-		WHEN cardinality($4 :: text[]) > 0 AND 'member' != ANY($4 :: text[]) THEN
-			rbac_roles && $4 :: text[]
-		ELSE true
-	END
-	-- Filter by last_seen
-	AND CASE
-		WHEN $5 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
-			last_seen_at <= $5
-		ELSE true
-	END
-	AND CASE
-		WHEN $6 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
-			last_seen_at >= $6
-		ELSE true
-	END
-	-- Filter by created_at
-	AND CASE
-		WHEN $7 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
-			created_at <= $7
-		ELSE true
-	END
-	AND CASE
-		WHEN $8 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
-			created_at >= $8
-		ELSE true
-	END
-  	AND CASE
-  	    WHEN $9::bool THEN TRUE
-  	    ELSE
-			is_system = false
-	END
-	AND CASE
-		WHEN $10 :: bigint != 0 THEN
-			github_com_user_id = $10
-		ELSE true
-	END
-	-- Filter by login_type
-	AND CASE
-		WHEN cardinality($11 :: login_type[]) > 0 THEN
-			login_type = ANY($11 :: login_type[])
-		ELSE true
-	END
-	-- End of filters
-
-	-- Authorize Filter clause will be injected below in GetAuthorizedUsers
-	-- @authorize_filter
+	variable_sets
 ORDER BY
-	-- Deterministic and consistent ordering of all users. This is to ensure consistent pagination.
-	LOWER(username) ASC OFFSET $12
-LIMIT
-	-- A null limit means "no limit", so 0 means return all
-	NULLIF($13 :: int, 0)
+	name ASC
 `
 
-type GetUsersParams struct {
-	AfterID         uuid.UUID    `db:"after_id" json:"after_id"`
-	Search          string       `db:"search" json:"search"`
-	Status          []UserStatus `db:"status" json:"status"`
-	RbacRole        []string     `db:"rbac_role" json:"rbac_role"`
-	LastSeenBefore  time.Time    `db:"last_seen_before" json:"last_seen_before"`
-	LastSeenAfter   time.Time    `db:"last_seen_after" json:"last_seen_after"`
-	CreatedBefore   time.Time    `db:"created_before" json:"created_before"`
-	CreatedAfter    time.Time    `db:"created_after" json:"created_after"`
-	IncludeSystem   bool         `db:"include_system" json:"include_system"`
-	GithubComUserID int64        `db:"github_com_user_id" json:"github_com_user_id"`
-	LoginType       []LoginType  `db:"login_type" json:"login_type"`
-	OffsetOpt       int32        `db:"offset_opt" json:"offset_opt"`
-	LimitOpt        int32        `db:"limit_opt" json:"limit_opt"`
-}
-
-type GetUsersRow struct {
-	ID                       uuid.UUID      `db:"id" json:"id"`
-	Email                    string         `db:"email" json:"email"`
-	Username                 string         `db:"username" json:"username"`
-	HashedPassword           []byte         `db:"hashed_password" json:"hashed_password"`
-	CreatedAt                time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt                time.Time      `db:"updated_at" json:"updated_at"`
-	Status                   UserStatus     `db:"status" json:"status"`
-	RBACRoles                pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
-	LoginType                LoginType      `db:"login_type" json:"login_type"`
-	AvatarURL                string         `db:"avatar_url" json:"avatar_url"`
-	Deleted                  bool           `db:"deleted" json:"deleted"`
-	LastSeenAt               time.Time      `db:"last_seen_at" json:"last_seen_at"`
-	QuietHoursSchedule       string         `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
-	Name                     string         `db:"name" json:"name"`
-	GithubComUserID          sql.NullInt64  `db:"github_com_user_id" json:"github_com_user_id"`
-	HashedOneTimePasscode    []byte         `db:"hashed_one_time_passcode" json:"hashed_one_time_passcode"`
-	OneTimePasscodeExpiresAt sql.NullTime   `db:"one_time_passcode_expires_at" json:"one_time_passcode_expires_at"`
-	IsSystem                 bool           `db:"is_system" json:"is_system"`
-	Count                    int64          `db:"count" json:"count"`
-}
-
-// This will never return deleted users.
-func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error) {
-	rows, err := q.db.QueryContext(ctx, getUsers,
-		arg.AfterID,
-		arg.Search,
-		pq.Array(arg.Status),
-		pq.Array(arg.RbacRole),
-		arg.LastSeenBefore,
-		arg.LastSeenAfter,
-		arg.CreatedBefore,
-		arg.CreatedAfter,
-		arg.IncludeSystem,
-		arg.GithubComUserID,
-		pq.Array(arg.LoginType),
-		arg.OffsetOpt,
-		arg.LimitOpt,
-	)
+func (q *sqlQuerier) GetVariableSets(ctx context.Context) ([]VariableSet, error) {
+	rows, err := q.db.QueryContext(ctx, getVariableSets)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUsersRow
+	var items []VariableSet
 	for rows.Next() {
-		var i GetUsersRow
+		var i VariableSet
 		if err := rows.Scan(
 			&i.ID,
-			&i.Email,
-			&i.Username,
-			&i.HashedPassword,
+			&i.Name,
+			&i.Description,
+			&i.Variables,
+			&i.VariablesKeyID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.Status,
-			&i.RBACRoles,
-			&i.LoginType,
-			&i.AvatarURL,
-			&i.Deleted,
-			&i.LastSeenAt,
-			&i.QuietHoursSchedule,
-			&i.Name,
-			&i.GithubComUserID,
-			&i.HashedOneTimePasscode,
-			&i.OneTimePasscodeExpiresAt,
-			&i.IsSystem,
-			&i.Count,
 		); err != nil {
 			return nil, err
 		}
@@ -13612,41 +19974,36 @@ func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUse
 	return items, nil
 }
 
-const getUsersByIDs = `-- name: GetUsersByIDs :many
-SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system FROM users WHERE id = ANY($1 :: uuid [ ])
+const getVariableSetsByTemplateID = `-- name: GetVariableSetsByTemplateID :many
+SELECT
+	variable_sets.id, variable_sets.name, variable_sets.description, variable_sets.variables, variable_sets.variables_key_id, variable_sets.created_at, variable_sets.updated_at
+FROM
+	variable_sets
+INNER JOIN
+	template_variable_sets ON template_variable_sets.variable_set_id = variable_sets.id
+WHERE
+	template_variable_sets.template_id = $1
+ORDER BY
+	variable_sets.name ASC
 `
 
-// This shouldn't check for deleted, because it's frequently used
-// to look up references to actions. eg. a user could build a workspace
-// for another user, then be deleted... we still want them to appear!
-func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, getUsersByIDs, pq.Array(ids))
+func (q *sqlQuerier) GetVariableSetsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]VariableSet, error) {
+	rows, err := q.db.QueryContext(ctx, getVariableSetsByTemplateID, templateID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []User
+	var items []VariableSet
 	for rows.Next() {
-		var i User
+		var i VariableSet
 		if err := rows.Scan(
 			&i.ID,
-			&i.Email,
-			&i.Username,
-			&i.HashedPassword,
+			&i.Name,
+			&i.Description,
+			&i.Variables,
+			&i.VariablesKeyID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.Status,
-			&i.RBACRoles,
-			&i.LoginType,
-			&i.AvatarURL,
-			&i.Deleted,
-			&i.LastSeenAt,
-			&i.QuietHoursSchedule,
-			&i.Name,
-			&i.GithubComUserID,
-			&i.HashedOneTimePasscode,
-			&i.OneTimePasscodeExpiresAt,
-			&i.IsSystem,
 		); err != nil {
 			return nil, err
 		}
@@ -13661,117 +20018,146 @@ func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User
 	return items, nil
 }
 
-const insertUser = `-- name: InsertUser :one
+const insertTemplateVariableSet = `-- name: InsertTemplateVariableSet :exec
+INSERT INTO
+	template_variable_sets (
+		template_id,
+		variable_set_id,
+		created_at
+	)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (template_id, variable_set_id) DO NOTHING
+`
+
+type InsertTemplateVariableSetParams struct {
+	TemplateID    uuid.UUID `db:"template_id" json:"template_id"`
+	VariableSetID uuid.UUID `db:"variable_set_id" json:"variable_set_id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertTemplateVariableSet(ctx context.Context, arg InsertTemplateVariableSetParams) error {
+	_, err := q.db.ExecContext(ctx, insertTemplateVariableSet, arg.TemplateID, arg.VariableSetID, arg.CreatedAt)
+	return err
+}
+
+const insertVariableSet = `-- name: InsertVariableSet :one
 INSERT INTO
-	users (
+	variable_sets (
 		id,
-		email,
-		username,
 		name,
-		hashed_password,
+		description,
+		variables,
+		variables_key_id,
 		created_at,
-		updated_at,
-		rbac_roles,
-		login_type,
-		status
+		updated_at
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9,
-		-- if the status passed in is empty, fallback to dormant, which is what
-		-- we were doing before.
-		COALESCE(NULLIF($10::text, '')::user_status, 'dormant'::user_status)
-	) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, name, description, variables, variables_key_id, created_at, updated_at
 `
 
-type InsertUserParams struct {
+type InsertVariableSetParams struct {
 	ID             uuid.UUID      `db:"id" json:"id"`
-	Email          string         `db:"email" json:"email"`
-	Username       string         `db:"username" json:"username"`
 	Name           string         `db:"name" json:"name"`
-	HashedPassword []byte         `db:"hashed_password" json:"hashed_password"`
+	Description    string         `db:"description" json:"description"`
+	Variables      string         `db:"variables" json:"variables"`
+	VariablesKeyID sql.NullString `db:"variables_key_id" json:"variables_key_id"`
 	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
-	RBACRoles      pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
-	LoginType      LoginType      `db:"login_type" json:"login_type"`
-	Status         string         `db:"status" json:"status"`
 }
 
-func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, insertUser,
+func (q *sqlQuerier) InsertVariableSet(ctx context.Context, arg InsertVariableSetParams) (VariableSet, error) {
+	row := q.db.QueryRowContext(ctx, insertVariableSet,
 		arg.ID,
-		arg.Email,
-		arg.Username,
 		arg.Name,
-		arg.HashedPassword,
+		arg.Description,
+		arg.Variables,
+		arg.VariablesKeyID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
-		arg.RBACRoles,
-		arg.LoginType,
-		arg.Status,
 	)
-	var i User
+	var i VariableSet
 	err := row.Scan(
 		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
+		&i.Name,
+		&i.Description,
+		&i.Variables,
+		&i.VariablesKeyID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.Name,
-		&i.GithubComUserID,
-		&i.HashedOneTimePasscode,
-		&i.OneTimePasscodeExpiresAt,
-		&i.IsSystem,
 	)
 	return i, err
 }
 
-const updateInactiveUsersToDormant = `-- name: UpdateInactiveUsersToDormant :many
+const updateVariableSet = `-- name: UpdateVariableSet :one
 UPDATE
-    users
+	variable_sets
 SET
-    status = 'dormant'::user_status,
-    updated_at = $1
+	description = $1,
+	variables = $2,
+	variables_key_id = $3,
+	updated_at = $4
 WHERE
-    last_seen_at < $2 :: timestamp
-    AND status = 'active'::user_status
-		AND NOT is_system
-RETURNING id, email, username, last_seen_at
+	id = $5
+RETURNING id, name, description, variables, variables_key_id, created_at, updated_at
 `
 
-type UpdateInactiveUsersToDormantParams struct {
-	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
-	LastSeenAfter time.Time `db:"last_seen_after" json:"last_seen_after"`
+type UpdateVariableSetParams struct {
+	Description    string         `db:"description" json:"description"`
+	Variables      string         `db:"variables" json:"variables"`
+	VariablesKeyID sql.NullString `db:"variables_key_id" json:"variables_key_id"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+	ID             uuid.UUID      `db:"id" json:"id"`
 }
 
-type UpdateInactiveUsersToDormantRow struct {
-	ID         uuid.UUID `db:"id" json:"id"`
-	Email      string    `db:"email" json:"email"`
-	Username   string    `db:"username" json:"username"`
-	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+func (q *sqlQuerier) UpdateVariableSet(ctx context.Context, arg UpdateVariableSetParams) (VariableSet, error) {
+	row := q.db.QueryRowContext(ctx, updateVariableSet,
+		arg.Description,
+		arg.Variables,
+		arg.VariablesKeyID,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i VariableSet
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Variables,
+		&i.VariablesKeyID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-func (q *sqlQuerier) UpdateInactiveUsersToDormant(ctx context.Context, arg UpdateInactiveUsersToDormantParams) ([]UpdateInactiveUsersToDormantRow, error) {
-	rows, err := q.db.QueryContext(ctx, updateInactiveUsersToDormant, arg.UpdatedAt, arg.LastSeenAfter)
+const getWorkspaceAgentDevcontainersByAgentID = `-- name: GetWorkspaceAgentDevcontainersByAgentID :many
+SELECT
+	id, workspace_agent_id, created_at, workspace_folder, config_path, name
+FROM
+	workspace_agent_devcontainers
+WHERE
+	workspace_agent_id = $1
+ORDER BY
+	created_at, id
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentDevcontainer, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentDevcontainersByAgentID, workspaceAgentID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []UpdateInactiveUsersToDormantRow
+	var items []WorkspaceAgentDevcontainer
 	for rows.Next() {
-		var i UpdateInactiveUsersToDormantRow
+		var i WorkspaceAgentDevcontainer
 		if err := rows.Scan(
 			&i.ID,
-			&i.Email,
-			&i.Username,
-			&i.LastSeenAt,
+			&i.WorkspaceAgentID,
+			&i.CreatedAt,
+			&i.WorkspaceFolder,
+			&i.ConfigPath,
+			&i.Name,
 		); err != nil {
 			return nil, err
 		}
@@ -13786,448 +20172,530 @@ func (q *sqlQuerier) UpdateInactiveUsersToDormant(ctx context.Context, arg Updat
 	return items, nil
 }
 
-const updateUserDeletedByID = `-- name: UpdateUserDeletedByID :exec
-UPDATE
-	users
-SET
-	deleted = true
+const deleteExpiredWebAuthnChallenges = `-- name: DeleteExpiredWebAuthnChallenges :exec
+DELETE FROM
+	webauthn_challenges
 WHERE
-	id = $1
+	expires_at < $1
 `
 
-func (q *sqlQuerier) UpdateUserDeletedByID(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, updateUserDeletedByID, id)
+func (q *sqlQuerier) DeleteExpiredWebAuthnChallenges(ctx context.Context, now time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredWebAuthnChallenges, now)
 	return err
 }
 
-const updateUserGithubComUserID = `-- name: UpdateUserGithubComUserID :exec
-UPDATE
-	users
-SET
-	github_com_user_id = $2
+const deleteUserMFARecoveryCodes = `-- name: DeleteUserMFARecoveryCodes :exec
+DELETE FROM
+	user_mfa_recovery_codes
 WHERE
-	id = $1
+	user_id = $1
 `
 
-type UpdateUserGithubComUserIDParams struct {
-	ID              uuid.UUID     `db:"id" json:"id"`
-	GithubComUserID sql.NullInt64 `db:"github_com_user_id" json:"github_com_user_id"`
+func (q *sqlQuerier) DeleteUserMFARecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserMFARecoveryCodes, userID)
+	return err
 }
 
-func (q *sqlQuerier) UpdateUserGithubComUserID(ctx context.Context, arg UpdateUserGithubComUserIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserGithubComUserID, arg.ID, arg.GithubComUserID)
+const deleteUserWebAuthnCredential = `-- name: DeleteUserWebAuthnCredential :exec
+DELETE FROM
+	user_webauthn_credentials
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteUserWebAuthnCredential(ctx context.Context, iD uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserWebAuthnCredential, iD)
 	return err
 }
 
-const updateUserHashedOneTimePasscode = `-- name: UpdateUserHashedOneTimePasscode :exec
-UPDATE
-    users
-SET
-    hashed_one_time_passcode = $2,
-    one_time_passcode_expires_at = $3
+const deleteWebAuthnChallengeByID = `-- name: DeleteWebAuthnChallengeByID :one
+DELETE FROM
+	webauthn_challenges
 WHERE
-    id = $1
+	id = $1
+RETURNING id, user_id, purpose, challenge, created_at, expires_at
 `
 
-type UpdateUserHashedOneTimePasscodeParams struct {
-	ID                       uuid.UUID    `db:"id" json:"id"`
-	HashedOneTimePasscode    []byte       `db:"hashed_one_time_passcode" json:"hashed_one_time_passcode"`
-	OneTimePasscodeExpiresAt sql.NullTime `db:"one_time_passcode_expires_at" json:"one_time_passcode_expires_at"`
+// Returns the deleted challenge, so that each challenge can only be used once
+// even by concurrent requests.
+func (q *sqlQuerier) DeleteWebAuthnChallengeByID(ctx context.Context, iD uuid.UUID) (WebAuthnChallenge, error) {
+	row := q.db.QueryRowContext(ctx, deleteWebAuthnChallengeByID, iD)
+	var i WebAuthnChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Purpose,
+		&i.Challenge,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
 }
 
-func (q *sqlQuerier) UpdateUserHashedOneTimePasscode(ctx context.Context, arg UpdateUserHashedOneTimePasscodeParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserHashedOneTimePasscode, arg.ID, arg.HashedOneTimePasscode, arg.OneTimePasscodeExpiresAt)
-	return err
+const getOrganizationMFASettings = `-- name: GetOrganizationMFASettings :one
+SELECT
+	organization_id, require_webauthn, updated_at
+FROM
+	organization_mfa_settings
+WHERE
+	organization_id = $1
+`
+
+func (q *sqlQuerier) GetOrganizationMFASettings(ctx context.Context, organizationID uuid.UUID) (OrganizationMFASetting, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationMFASettings, organizationID)
+	var i OrganizationMFASetting
+	err := row.Scan(&i.OrganizationID, &i.RequireWebAuthn, &i.UpdatedAt)
+	return i, err
 }
 
-const updateUserHashedPassword = `-- name: UpdateUserHashedPassword :exec
-UPDATE
-	users
-SET
-	hashed_password = $2,
-	hashed_one_time_passcode = NULL,
-	one_time_passcode_expires_at = NULL
+const getUserWebAuthnCredentialByCredentialID = `-- name: GetUserWebAuthnCredentialByCredentialID :one
+SELECT
+	id, user_id, name, credential_id, public_key, sign_count, created_at, last_used_at
+FROM
+	user_webauthn_credentials
+WHERE
+	credential_id = $1
+`
+
+func (q *sqlQuerier) GetUserWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (UserWebAuthnCredential, error) {
+	row := q.db.QueryRowContext(ctx, getUserWebAuthnCredentialByCredentialID, credentialID)
+	var i UserWebAuthnCredential
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CredentialID,
+		&i.PublicKey,
+		&i.SignCount,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const getUserWebAuthnCredentialByID = `-- name: GetUserWebAuthnCredentialByID :one
+SELECT
+	id, user_id, name, credential_id, public_key, sign_count, created_at, last_used_at
+FROM
+	user_webauthn_credentials
 WHERE
 	id = $1
 `
 
-type UpdateUserHashedPasswordParams struct {
-	ID             uuid.UUID `db:"id" json:"id"`
-	HashedPassword []byte    `db:"hashed_password" json:"hashed_password"`
+func (q *sqlQuerier) GetUserWebAuthnCredentialByID(ctx context.Context, iD uuid.UUID) (UserWebAuthnCredential, error) {
+	row := q.db.QueryRowContext(ctx, getUserWebAuthnCredentialByID, iD)
+	var i UserWebAuthnCredential
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CredentialID,
+		&i.PublicKey,
+		&i.SignCount,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const getUserWebAuthnCredentialsByUserID = `-- name: GetUserWebAuthnCredentialsByUserID :many
+SELECT
+	id, user_id, name, credential_id, public_key, sign_count, created_at, last_used_at
+FROM
+	user_webauthn_credentials
+WHERE
+	user_id = $1
+ORDER BY
+	created_at ASC
+`
+
+func (q *sqlQuerier) GetUserWebAuthnCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]UserWebAuthnCredential, error) {
+	rows, err := q.db.QueryContext(ctx, getUserWebAuthnCredentialsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserWebAuthnCredential
+	for rows.Next() {
+		var i UserWebAuthnCredential
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CredentialID,
+			&i.PublicKey,
+			&i.SignCount,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserWebAuthnRequired = `-- name: GetUserWebAuthnRequired :one
+SELECT
+	EXISTS (
+		SELECT
+			1
+		FROM
+			organization_mfa_settings
+		JOIN
+			organization_members
+		ON
+			organization_members.organization_id = organization_mfa_settings.organization_id
+		WHERE
+			organization_members.user_id = $1
+			AND organization_mfa_settings.require_webauthn
+	)
+`
+
+// Returns true if any organization the user is a member of requires a WebAuthn
+// credential for password logins.
+func (q *sqlQuerier) GetUserWebAuthnRequired(ctx context.Context, userID uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, getUserWebAuthnRequired, userID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const insertUserMFARecoveryCode = `-- name: InsertUserMFARecoveryCode :exec
+INSERT INTO
+	user_mfa_recovery_codes (user_id, hashed_code, created_at)
+VALUES
+	($1, $2, $3)
+`
+
+type InsertUserMFARecoveryCodeParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	HashedCode []byte    `db:"hashed_code" json:"hashed_code"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserHashedPassword, arg.ID, arg.HashedPassword)
+func (q *sqlQuerier) InsertUserMFARecoveryCode(ctx context.Context, arg InsertUserMFARecoveryCodeParams) error {
+	_, err := q.db.ExecContext(ctx, insertUserMFARecoveryCode, arg.UserID, arg.HashedCode, arg.CreatedAt)
 	return err
 }
 
-const updateUserLastSeenAt = `-- name: UpdateUserLastSeenAt :one
-UPDATE
-	users
-SET
-	last_seen_at = $2,
-	updated_at = $3
-WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
+const insertUserWebAuthnCredential = `-- name: InsertUserWebAuthnCredential :one
+INSERT INTO
+	user_webauthn_credentials (id, user_id, name, credential_id, public_key, sign_count, created_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, user_id, name, credential_id, public_key, sign_count, created_at, last_used_at
 `
 
-type UpdateUserLastSeenAtParams struct {
-	ID         uuid.UUID `db:"id" json:"id"`
-	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+type InsertUserWebAuthnCredentialParams struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	Name         string    `db:"name" json:"name"`
+	CredentialID []byte    `db:"credential_id" json:"credential_id"`
+	PublicKey    []byte    `db:"public_key" json:"public_key"`
+	SignCount    int64     `db:"sign_count" json:"sign_count"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLastSeenAtParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLastSeenAt, arg.ID, arg.LastSeenAt, arg.UpdatedAt)
-	var i User
+func (q *sqlQuerier) InsertUserWebAuthnCredential(ctx context.Context, arg InsertUserWebAuthnCredentialParams) (UserWebAuthnCredential, error) {
+	row := q.db.QueryRowContext(ctx, insertUserWebAuthnCredential,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.CredentialID,
+		arg.PublicKey,
+		arg.SignCount,
+		arg.CreatedAt,
+	)
+	var i UserWebAuthnCredential
 	err := row.Scan(
 		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
+		&i.UserID,
 		&i.Name,
-		&i.GithubComUserID,
-		&i.HashedOneTimePasscode,
-		&i.OneTimePasscodeExpiresAt,
-		&i.IsSystem,
+		&i.CredentialID,
+		&i.PublicKey,
+		&i.SignCount,
+		&i.CreatedAt,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
 
-const updateUserLoginType = `-- name: UpdateUserLoginType :one
-UPDATE
-	users
-SET
-	login_type = $1,
-	hashed_password = CASE WHEN $1 = 'password' :: login_type THEN
-		users.hashed_password
-	ELSE
-		-- If the login type is not password, then the password should be
-        -- cleared.
-		'':: bytea
-	END
-WHERE
-	id = $2
-	AND NOT is_system
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
+const insertWebAuthnChallenge = `-- name: InsertWebAuthnChallenge :one
+INSERT INTO
+	webauthn_challenges (id, user_id, purpose, challenge, created_at, expires_at)
+VALUES
+	($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, purpose, challenge, created_at, expires_at
 `
 
-type UpdateUserLoginTypeParams struct {
-	NewLoginType LoginType `db:"new_login_type" json:"new_login_type"`
-	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+type InsertWebAuthnChallengeParams struct {
+	ID        uuid.UUID                `db:"id" json:"id"`
+	UserID    uuid.UUID                `db:"user_id" json:"user_id"`
+	Purpose   WebAuthnChallengePurpose `db:"purpose" json:"purpose"`
+	Challenge []byte                   `db:"challenge" json:"challenge"`
+	CreatedAt time.Time                `db:"created_at" json:"created_at"`
+	ExpiresAt time.Time                `db:"expires_at" json:"expires_at"`
 }
 
-func (q *sqlQuerier) UpdateUserLoginType(ctx context.Context, arg UpdateUserLoginTypeParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLoginType, arg.NewLoginType, arg.UserID)
-	var i User
+func (q *sqlQuerier) InsertWebAuthnChallenge(ctx context.Context, arg InsertWebAuthnChallengeParams) (WebAuthnChallenge, error) {
+	row := q.db.QueryRowContext(ctx, insertWebAuthnChallenge,
+		arg.ID,
+		arg.UserID,
+		arg.Purpose,
+		arg.Challenge,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	var i WebAuthnChallenge
 	err := row.Scan(
 		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
+		&i.UserID,
+		&i.Purpose,
+		&i.Challenge,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.Name,
-		&i.GithubComUserID,
-		&i.HashedOneTimePasscode,
-		&i.OneTimePasscodeExpiresAt,
-		&i.IsSystem,
+		&i.ExpiresAt,
 	)
 	return i, err
 }
 
-const updateUserProfile = `-- name: UpdateUserProfile :one
+const updateUserWebAuthnCredentialSignCount = `-- name: UpdateUserWebAuthnCredentialSignCount :exec
 UPDATE
-	users
+	user_webauthn_credentials
 SET
-	email = $2,
-	username = $3,
-	avatar_url = $4,
-	updated_at = $5,
-	name = $6
+	sign_count = $1,
+	last_used_at = $2
 WHERE
-	id = $1
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
+	id = $3
 `
 
-type UpdateUserProfileParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	Email     string    `db:"email" json:"email"`
-	Username  string    `db:"username" json:"username"`
-	AvatarURL string    `db:"avatar_url" json:"avatar_url"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
-	Name      string    `db:"name" json:"name"`
+type UpdateUserWebAuthnCredentialSignCountParams struct {
+	SignCount  int64        `db:"sign_count" json:"sign_count"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	ID         uuid.UUID    `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserProfile,
-		arg.ID,
-		arg.Email,
-		arg.Username,
-		arg.AvatarURL,
-		arg.UpdatedAt,
-		arg.Name,
-	)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.Name,
-		&i.GithubComUserID,
-		&i.HashedOneTimePasscode,
-		&i.OneTimePasscodeExpiresAt,
-		&i.IsSystem,
-	)
-	return i, err
+func (q *sqlQuerier) UpdateUserWebAuthnCredentialSignCount(ctx context.Context, arg UpdateUserWebAuthnCredentialSignCountParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserWebAuthnCredentialSignCount, arg.SignCount, arg.LastUsedAt, arg.ID)
+	return err
 }
 
-const updateUserQuietHoursSchedule = `-- name: UpdateUserQuietHoursSchedule :one
-UPDATE
-	users
-SET
-	quiet_hours_schedule = $2
-WHERE
-	id = $1
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
+const upsertOrganizationMFASettings = `-- name: UpsertOrganizationMFASettings :one
+INSERT INTO
+	organization_mfa_settings (organization_id, require_webauthn, updated_at)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (organization_id) DO UPDATE SET
+	require_webauthn = $2,
+	updated_at = $3
+RETURNING organization_id, require_webauthn, updated_at
 `
 
-type UpdateUserQuietHoursScheduleParams struct {
-	ID                 uuid.UUID `db:"id" json:"id"`
-	QuietHoursSchedule string    `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+type UpsertOrganizationMFASettingsParams struct {
+	OrganizationID  uuid.UUID `db:"organization_id" json:"organization_id"`
+	RequireWebAuthn bool      `db:"require_webauthn" json:"require_webauthn"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) UpdateUserQuietHoursSchedule(ctx context.Context, arg UpdateUserQuietHoursScheduleParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserQuietHoursSchedule, arg.ID, arg.QuietHoursSchedule)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.Name,
-		&i.GithubComUserID,
-		&i.HashedOneTimePasscode,
-		&i.OneTimePasscodeExpiresAt,
-		&i.IsSystem,
-	)
+func (q *sqlQuerier) UpsertOrganizationMFASettings(ctx context.Context, arg UpsertOrganizationMFASettingsParams) (OrganizationMFASetting, error) {
+	row := q.db.QueryRowContext(ctx, upsertOrganizationMFASettings, arg.OrganizationID, arg.RequireWebAuthn, arg.UpdatedAt)
+	var i OrganizationMFASetting
+	err := row.Scan(&i.OrganizationID, &i.RequireWebAuthn, &i.UpdatedAt)
 	return i, err
 }
 
-const updateUserRoles = `-- name: UpdateUserRoles :one
+const useUserMFARecoveryCode = `-- name: UseUserMFARecoveryCode :one
 UPDATE
-	users
+	user_mfa_recovery_codes
 SET
-	-- Remove all duplicates from the roles.
-	rbac_roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
+	used_at = $1
 WHERE
-	id = $2
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
+	user_id = $2
+	AND hashed_code = $3
+	AND used_at IS NULL
+RETURNING user_id, hashed_code, created_at, used_at
 `
 
-type UpdateUserRolesParams struct {
-	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
-	ID           uuid.UUID `db:"id" json:"id"`
+type UseUserMFARecoveryCodeParams struct {
+	UsedAt     sql.NullTime `db:"used_at" json:"used_at"`
+	UserID     uuid.UUID    `db:"user_id" json:"user_id"`
+	HashedCode []byte       `db:"hashed_code" json:"hashed_code"`
 }
 
-func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserRoles, pq.Array(arg.GrantedRoles), arg.ID)
-	var i User
+func (q *sqlQuerier) UseUserMFARecoveryCode(ctx context.Context, arg UseUserMFARecoveryCodeParams) (UserMFARecoveryCode, error) {
+	row := q.db.QueryRowContext(ctx, useUserMFARecoveryCode, arg.UsedAt, arg.UserID, arg.HashedCode)
+	var i UserMFARecoveryCode
 	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
+		&i.UserID,
+		&i.HashedCode,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.Name,
-		&i.GithubComUserID,
-		&i.HashedOneTimePasscode,
-		&i.OneTimePasscodeExpiresAt,
-		&i.IsSystem,
+		&i.UsedAt,
 	)
 	return i, err
 }
 
-const updateUserStatus = `-- name: UpdateUserStatus :one
-UPDATE
-	users
-SET
-	status = $2,
-	updated_at = $3
+const getWorkspaceAdoptionByID = `-- name: GetWorkspaceAdoptionByID :one
+SELECT
+	id, organization_id, owner_id, template_id, workspace_name, hashed_secret, created_by, created_at, expires_at, workspace_id, hostname, adopted_at
+FROM
+	workspace_adoptions
 WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, name, github_com_user_id, hashed_one_time_passcode, one_time_passcode_expires_at, is_system
+	id = $1
 `
 
-type UpdateUserStatusParams struct {
-	ID        uuid.UUID  `db:"id" json:"id"`
-	Status    UserStatus `db:"status" json:"status"`
-	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+func (q *sqlQuerier) GetWorkspaceAdoptionByID(ctx context.Context, id uuid.UUID) (WorkspaceAdoption, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAdoptionByID, id)
+	var i WorkspaceAdoption
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.OwnerID,
+		&i.TemplateID,
+		&i.WorkspaceName,
+		&i.HashedSecret,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.WorkspaceID,
+		&i.Hostname,
+		&i.AdoptedAt,
+	)
+	return i, err
 }
 
-func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
-	var i User
+const getWorkspaceAdoptionByWorkspaceID = `-- name: GetWorkspaceAdoptionByWorkspaceID :one
+SELECT
+	id, organization_id, owner_id, template_id, workspace_name, hashed_secret, created_by, created_at, expires_at, workspace_id, hostname, adopted_at
+FROM
+	workspace_adoptions
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceAdoptionByWorkspaceID(ctx context.Context, workspaceID uuid.NullUUID) (WorkspaceAdoption, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAdoptionByWorkspaceID, workspaceID)
+	var i WorkspaceAdoption
 	err := row.Scan(
 		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
+		&i.OrganizationID,
+		&i.OwnerID,
+		&i.TemplateID,
+		&i.WorkspaceName,
+		&i.HashedSecret,
+		&i.CreatedBy,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.Name,
-		&i.GithubComUserID,
-		&i.HashedOneTimePasscode,
-		&i.OneTimePasscodeExpiresAt,
-		&i.IsSystem,
+		&i.ExpiresAt,
+		&i.WorkspaceID,
+		&i.Hostname,
+		&i.AdoptedAt,
 	)
 	return i, err
 }
 
-const updateUserTerminalFont = `-- name: UpdateUserTerminalFont :one
+const insertWorkspaceAdoption = `-- name: InsertWorkspaceAdoption :one
 INSERT INTO
-	user_configs (user_id, key, value)
+	workspace_adoptions (id, organization_id, owner_id, template_id, workspace_name, hashed_secret, created_by, created_at, expires_at)
 VALUES
-	($1, 'terminal_font', $2)
-ON CONFLICT
-	ON CONSTRAINT user_configs_pkey
-DO UPDATE
-SET
-	value = $2
-WHERE user_configs.user_id = $1
-	AND user_configs.key = 'terminal_font'
-RETURNING user_id, key, value
+	($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, organization_id, owner_id, template_id, workspace_name, hashed_secret, created_by, created_at, expires_at, workspace_id, hostname, adopted_at
 `
 
-type UpdateUserTerminalFontParams struct {
-	UserID       uuid.UUID `db:"user_id" json:"user_id"`
-	TerminalFont string    `db:"terminal_font" json:"terminal_font"`
+type InsertWorkspaceAdoptionParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	WorkspaceName  string    `db:"workspace_name" json:"workspace_name"`
+	HashedSecret   []byte    `db:"hashed_secret" json:"hashed_secret"`
+	CreatedBy      uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt      time.Time `db:"expires_at" json:"expires_at"`
 }
 
-func (q *sqlQuerier) UpdateUserTerminalFont(ctx context.Context, arg UpdateUserTerminalFontParams) (UserConfig, error) {
-	row := q.db.QueryRowContext(ctx, updateUserTerminalFont, arg.UserID, arg.TerminalFont)
-	var i UserConfig
-	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+func (q *sqlQuerier) InsertWorkspaceAdoption(ctx context.Context, arg InsertWorkspaceAdoptionParams) (WorkspaceAdoption, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAdoption,
+		arg.ID,
+		arg.OrganizationID,
+		arg.OwnerID,
+		arg.TemplateID,
+		arg.WorkspaceName,
+		arg.HashedSecret,
+		arg.CreatedBy,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	var i WorkspaceAdoption
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.OwnerID,
+		&i.TemplateID,
+		&i.WorkspaceName,
+		&i.HashedSecret,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.WorkspaceID,
+		&i.Hostname,
+		&i.AdoptedAt,
+	)
 	return i, err
 }
 
-const updateUserThemePreference = `-- name: UpdateUserThemePreference :one
-INSERT INTO
-	user_configs (user_id, key, value)
-VALUES
-	($1, 'theme_preference', $2)
-ON CONFLICT
-	ON CONSTRAINT user_configs_pkey
-DO UPDATE
+const updateWorkspaceAdoptionAdopted = `-- name: UpdateWorkspaceAdoptionAdopted :one
+UPDATE
+	workspace_adoptions
 SET
-	value = $2
-WHERE user_configs.user_id = $1
-	AND user_configs.key = 'theme_preference'
-RETURNING user_id, key, value
+	workspace_id = $1,
+	hostname = $2,
+	adopted_at = $3
+WHERE
+	id = $4
+	AND workspace_id IS NULL
+	AND expires_at > $3
+RETURNING id, organization_id, owner_id, template_id, workspace_name, hashed_secret, created_by, created_at, expires_at, workspace_id, hostname, adopted_at
 `
 
-type UpdateUserThemePreferenceParams struct {
-	UserID          uuid.UUID `db:"user_id" json:"user_id"`
-	ThemePreference string    `db:"theme_preference" json:"theme_preference"`
+type UpdateWorkspaceAdoptionAdoptedParams struct {
+	WorkspaceID uuid.NullUUID `db:"workspace_id" json:"workspace_id"`
+	Hostname    string        `db:"hostname" json:"hostname"`
+	AdoptedAt   sql.NullTime  `db:"adopted_at" json:"adopted_at"`
+	ID          uuid.UUID     `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateUserThemePreference(ctx context.Context, arg UpdateUserThemePreferenceParams) (UserConfig, error) {
-	row := q.db.QueryRowContext(ctx, updateUserThemePreference, arg.UserID, arg.ThemePreference)
-	var i UserConfig
-	err := row.Scan(&i.UserID, &i.Key, &i.Value)
+// Binds the adoption to the workspace created for the machine. No row is
+// returned if the token was already used or expired.
+func (q *sqlQuerier) UpdateWorkspaceAdoptionAdopted(ctx context.Context, arg UpdateWorkspaceAdoptionAdoptedParams) (WorkspaceAdoption, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceAdoptionAdopted,
+		arg.WorkspaceID,
+		arg.Hostname,
+		arg.AdoptedAt,
+		arg.ID,
+	)
+	var i WorkspaceAdoption
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.OwnerID,
+		&i.TemplateID,
+		&i.WorkspaceName,
+		&i.HashedSecret,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.WorkspaceID,
+		&i.Hostname,
+		&i.AdoptedAt,
+	)
 	return i, err
 }
 
-const getWorkspaceAgentDevcontainersByAgentID = `-- name: GetWorkspaceAgentDevcontainersByAgentID :many
-SELECT
-	id, workspace_agent_id, created_at, workspace_folder, config_path, name
-FROM
-	workspace_agent_devcontainers
-WHERE
-	workspace_agent_id = $1
-ORDER BY
-	created_at, id
-`
-
-func (q *sqlQuerier) GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentDevcontainer, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentDevcontainersByAgentID, workspaceAgentID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []WorkspaceAgentDevcontainer
-	for rows.Next() {
-		var i WorkspaceAgentDevcontainer
-		if err := rows.Scan(
-			&i.ID,
-			&i.WorkspaceAgentID,
-			&i.CreatedAt,
-			&i.WorkspaceFolder,
-			&i.ConfigPath,
-			&i.Name,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const insertWorkspaceAgentDevcontainers = `-- name: InsertWorkspaceAgentDevcontainers :many
 INSERT INTO
 	workspace_agent_devcontainers (workspace_agent_id, created_at, id, name, workspace_folder, config_path)
@@ -14616,6 +21084,46 @@ func (q *sqlQuerier) FetchVolumesResourceMonitorsUpdatedAfter(ctx context.Contex
 	return items, nil
 }
 
+const getWorkspaceAgentVolumeUsageByAgentID = `-- name: GetWorkspaceAgentVolumeUsageByAgentID :many
+SELECT
+	agent_id, path, total_bytes, used_bytes, collected_at
+FROM
+	workspace_agent_volume_usage
+WHERE
+	agent_id = $1
+ORDER BY
+	path
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentVolumeUsageByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceAgentVolumeUsage, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentVolumeUsageByAgentID, agentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentVolumeUsage
+	for rows.Next() {
+		var i WorkspaceAgentVolumeUsage
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.Path,
+			&i.TotalBytes,
+			&i.UsedBytes,
+			&i.CollectedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertMemoryResourceMonitor = `-- name: InsertMemoryResourceMonitor :one
 INSERT INTO
 	workspace_agent_memory_resource_monitors (
@@ -14772,6 +21280,43 @@ func (q *sqlQuerier) UpdateVolumeResourceMonitor(ctx context.Context, arg Update
 	return err
 }
 
+const upsertWorkspaceAgentVolumeUsage = `-- name: UpsertWorkspaceAgentVolumeUsage :exec
+INSERT INTO
+	workspace_agent_volume_usage (
+		agent_id,
+		path,
+		total_bytes,
+		used_bytes,
+		collected_at
+	)
+VALUES
+	($1, $2, $3, $4, $5)
+ON CONFLICT (agent_id, path) DO UPDATE
+SET
+	total_bytes = EXCLUDED.total_bytes,
+	used_bytes = EXCLUDED.used_bytes,
+	collected_at = EXCLUDED.collected_at
+`
+
+type UpsertWorkspaceAgentVolumeUsageParams struct {
+	AgentID     uuid.UUID `db:"agent_id" json:"agent_id"`
+	Path        string    `db:"path" json:"path"`
+	TotalBytes  int64     `db:"total_bytes" json:"total_bytes"`
+	UsedBytes   int64     `db:"used_bytes" json:"used_bytes"`
+	CollectedAt time.Time `db:"collected_at" json:"collected_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceAgentVolumeUsage(ctx context.Context, arg UpsertWorkspaceAgentVolumeUsageParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceAgentVolumeUsage,
+		arg.AgentID,
+		arg.Path,
+		arg.TotalBytes,
+		arg.UsedBytes,
+		arg.CollectedAt,
+	)
+	return err
+}
+
 const deleteOldWorkspaceAgentLogs = `-- name: DeleteOldWorkspaceAgentLogs :exec
 WITH
 	latest_builds AS (
@@ -14841,9 +21386,9 @@ func (q *sqlQuerier) DeleteWorkspaceSubAgentByID(ctx context.Context, id uuid.UU
 
 const getWorkspaceAgentAndLatestBuildByAuthToken = `-- name: GetWorkspaceAgentAndLatestBuildByAuthToken :one
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.ephemeral,
 	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope, workspace_agents.deleted,
-	workspace_build_with_user.id, workspace_build_with_user.created_at, workspace_build_with_user.updated_at, workspace_build_with_user.workspace_id, workspace_build_with_user.template_version_id, workspace_build_with_user.build_number, workspace_build_with_user.transition, workspace_build_with_user.initiator_id, workspace_build_with_user.provisioner_state, workspace_build_with_user.job_id, workspace_build_with_user.deadline, workspace_build_with_user.reason, workspace_build_with_user.daily_cost, workspace_build_with_user.max_deadline, workspace_build_with_user.template_version_preset_id, workspace_build_with_user.has_ai_task, workspace_build_with_user.ai_task_sidebar_app_id, workspace_build_with_user.initiator_by_avatar_url, workspace_build_with_user.initiator_by_username, workspace_build_with_user.initiator_by_name
+	workspace_build_with_user.id, workspace_build_with_user.created_at, workspace_build_with_user.updated_at, workspace_build_with_user.workspace_id, workspace_build_with_user.template_version_id, workspace_build_with_user.build_number, workspace_build_with_user.transition, workspace_build_with_user.initiator_id, workspace_build_with_user.provisioner_state, workspace_build_with_user.job_id, workspace_build_with_user.deadline, workspace_build_with_user.reason, workspace_build_with_user.daily_cost, workspace_build_with_user.max_deadline, workspace_build_with_user.template_version_preset_id, workspace_build_with_user.has_ai_task, workspace_build_with_user.ai_task_sidebar_app_id, workspace_build_with_user.reason_message, workspace_build_with_user.initiator_api_key_name, workspace_build_with_user.initiator_ci_job_url, workspace_build_with_user.initiator_by_avatar_url, workspace_build_with_user.initiator_by_username, workspace_build_with_user.initiator_by_name
 FROM
 	workspace_agents
 JOIN
@@ -14860,7 +21405,19 @@ ON
 	workspace_build_with_user.workspace_id = workspaces.id
 WHERE
 	-- This should only match 1 agent, so 1 returned row or 0.
-	workspace_agents.auth_token = $1::uuid
+	(
+		workspace_agents.auth_token = $1::uuid
+		-- Rotated tokens are accepted until their grace period ends.
+		OR workspace_agents.id = (
+			SELECT
+				agent_id
+			FROM
+				workspace_agent_token_rotations
+			WHERE
+				previous_auth_token = $1::uuid
+				AND previous_auth_token_expires_at > NOW()
+		)
+	)
 	AND workspaces.deleted = FALSE
 	-- Filter out deleted sub agents.
 	AND workspace_agents.deleted = FALSE
@@ -14903,6 +21460,7 @@ func (q *sqlQuerier) GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Cont
 		&i.WorkspaceTable.AutomaticUpdates,
 		&i.WorkspaceTable.Favorite,
 		&i.WorkspaceTable.NextStartAt,
+		&i.WorkspaceTable.Ephemeral,
 		&i.WorkspaceAgent.ID,
 		&i.WorkspaceAgent.CreatedAt,
 		&i.WorkspaceAgent.UpdatedAt,
@@ -14954,6 +21512,9 @@ func (q *sqlQuerier) GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Cont
 		&i.WorkspaceBuild.TemplateVersionPresetID,
 		&i.WorkspaceBuild.HasAITask,
 		&i.WorkspaceBuild.AITaskSidebarAppID,
+		&i.WorkspaceBuild.ReasonMessage,
+		&i.WorkspaceBuild.InitiatorAPIKeyName,
+		&i.WorkspaceBuild.InitiatorCIJobURL,
 		&i.WorkspaceBuild.InitiatorByAvatarUrl,
 		&i.WorkspaceBuild.InitiatorByUsername,
 		&i.WorkspaceBuild.InitiatorByName,
@@ -15249,25 +21810,103 @@ type GetWorkspaceAgentScriptTimingsByBuildIDRow struct {
 	WorkspaceAgentName string                           `db:"workspace_agent_name" json:"workspace_agent_name"`
 }
 
-func (q *sqlQuerier) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]GetWorkspaceAgentScriptTimingsByBuildIDRow, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentScriptTimingsByBuildID, id)
+func (q *sqlQuerier) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]GetWorkspaceAgentScriptTimingsByBuildIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentScriptTimingsByBuildID, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentScriptTimingsByBuildIDRow
+	for rows.Next() {
+		var i GetWorkspaceAgentScriptTimingsByBuildIDRow
+		if err := rows.Scan(
+			&i.ScriptID,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.ExitCode,
+			&i.Stage,
+			&i.Status,
+			&i.DisplayName,
+			&i.WorkspaceAgentID,
+			&i.WorkspaceAgentName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentVersionsInLatestBuilds = `-- name: GetWorkspaceAgentVersionsInLatestBuilds :many
+SELECT
+	workspace_agents.id AS agent_id,
+	workspace_agents.name AS agent_name,
+	workspace_agents.version,
+	workspaces.id AS workspace_id,
+	workspaces.name AS workspace_name,
+	workspaces.owner_id,
+	users.username AS owner_username
+FROM
+	workspace_agents
+JOIN
+	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
+JOIN
+	workspace_builds ON workspace_resources.job_id = workspace_builds.job_id
+JOIN
+	workspaces ON workspace_builds.workspace_id = workspaces.id
+JOIN
+	users ON workspaces.owner_id = users.id
+WHERE
+	workspaces.deleted = FALSE
+	AND workspace_agents.deleted = FALSE
+	AND workspace_agents.version != ''
+	AND workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds AS wb
+		WHERE
+			wb.workspace_id = workspaces.id
+	)
+ORDER BY
+	users.username, workspaces.name, workspace_agents.name
+`
+
+type GetWorkspaceAgentVersionsInLatestBuildsRow struct {
+	AgentID       uuid.UUID `db:"agent_id" json:"agent_id"`
+	AgentName     string    `db:"agent_name" json:"agent_name"`
+	Version       string    `db:"version" json:"version"`
+	WorkspaceID   uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName string    `db:"workspace_name" json:"workspace_name"`
+	OwnerID       uuid.UUID `db:"owner_id" json:"owner_id"`
+	OwnerUsername string    `db:"owner_username" json:"owner_username"`
+}
+
+// Returns the version every agent in the latest build of a workspace reported
+// when it last started. Agents that never connected are omitted.
+func (q *sqlQuerier) GetWorkspaceAgentVersionsInLatestBuilds(ctx context.Context) ([]GetWorkspaceAgentVersionsInLatestBuildsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentVersionsInLatestBuilds)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetWorkspaceAgentScriptTimingsByBuildIDRow
+	var items []GetWorkspaceAgentVersionsInLatestBuildsRow
 	for rows.Next() {
-		var i GetWorkspaceAgentScriptTimingsByBuildIDRow
+		var i GetWorkspaceAgentVersionsInLatestBuildsRow
 		if err := rows.Scan(
-			&i.ScriptID,
-			&i.StartedAt,
-			&i.EndedAt,
-			&i.ExitCode,
-			&i.Stage,
-			&i.Status,
-			&i.DisplayName,
-			&i.WorkspaceAgentID,
-			&i.WorkspaceAgentName,
+			&i.AgentID,
+			&i.AgentName,
+			&i.Version,
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.OwnerID,
+			&i.OwnerUsername,
 		); err != nil {
 			return nil, err
 		}
@@ -15914,6 +22553,85 @@ func (q *sqlQuerier) InsertWorkspaceAgentMetadata(ctx context.Context, arg Inser
 	return err
 }
 
+const insertWorkspaceAgentPushedMetadata = `-- name: InsertWorkspaceAgentPushedMetadata :exec
+-- Inserts the metadata items pushed by processes in the workspace that are
+-- reported for the first time. They are ordered after the metadata of the
+-- template. Items beyond max_pushed per agent are ignored.
+WITH pushed AS (
+	SELECT
+		unnest($1::text[]) AS key,
+		unnest($2::text[]) AS display_name
+), existing AS (
+	SELECT
+		COALESCE(MAX(display_order), 0) AS max_display_order,
+		COUNT(*) FILTER (WHERE key LIKE 'push:%') AS pushed_count
+	FROM
+		workspace_agent_metadata
+	WHERE
+		workspace_agent_id = $3::uuid
+), new AS (
+	SELECT
+		p.key,
+		p.display_name,
+		row_number() OVER (ORDER BY p.key) AS n
+	FROM
+		pushed p
+	WHERE
+		NOT EXISTS (
+			SELECT
+				1
+			FROM
+				workspace_agent_metadata wam
+			WHERE
+				wam.workspace_agent_id = $3::uuid
+				AND wam.key = p.key
+		)
+)
+INSERT INTO
+	workspace_agent_metadata (
+		workspace_agent_id,
+		display_name,
+		key,
+		script,
+		timeout,
+		interval,
+		display_order
+	)
+SELECT
+	$3::uuid,
+	new.display_name,
+	new.key,
+	'',
+	0,
+	0,
+	existing.max_display_order + new.n
+FROM
+	new, existing
+WHERE
+	existing.pushed_count + new.n <= $4::int
+ON CONFLICT (workspace_agent_id, key) DO NOTHING
+`
+
+type InsertWorkspaceAgentPushedMetadataParams struct {
+	Key              []string  `db:"key" json:"key"`
+	DisplayName      []string  `db:"display_name" json:"display_name"`
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	MaxPushed        int32     `db:"max_pushed" json:"max_pushed"`
+}
+
+// Inserts the metadata items pushed by processes in the workspace that are
+// reported for the first time. They are ordered after the metadata of the
+// template. Items beyond max_pushed per agent are ignored.
+func (q *sqlQuerier) InsertWorkspaceAgentPushedMetadata(ctx context.Context, arg InsertWorkspaceAgentPushedMetadataParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentPushedMetadata,
+		pq.Array(arg.Key),
+		pq.Array(arg.DisplayName),
+		arg.WorkspaceAgentID,
+		arg.MaxPushed,
+	)
+	return err
+}
+
 const insertWorkspaceAgentScriptTimings = `-- name: InsertWorkspaceAgentScriptTimings :one
 INSERT INTO
     workspace_agent_script_timings (
@@ -16876,6 +23594,82 @@ func (q *sqlQuerier) InsertWorkspaceAgentStats(ctx context.Context, arg InsertWo
 	return err
 }
 
+const getWorkspaceAgentTokenRotationByAgentID = `-- name: GetWorkspaceAgentTokenRotationByAgentID :one
+SELECT
+	agent_id, previous_auth_token, previous_auth_token_expires_at, rotated_at
+FROM
+	workspace_agent_token_rotations
+WHERE
+	agent_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentTokenRotationByAgentID(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentTokenRotation, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentTokenRotationByAgentID, agentID)
+	var i WorkspaceAgentTokenRotation
+	err := row.Scan(
+		&i.AgentID,
+		&i.PreviousAuthToken,
+		&i.PreviousAuthTokenExpiresAt,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const rotateWorkspaceAgentAuthToken = `-- name: RotateWorkspaceAgentAuthToken :one
+WITH rotated AS (
+	UPDATE
+		workspace_agents
+	SET
+		auth_token = $1::uuid
+	WHERE
+		id = $2::uuid
+		AND auth_token = $3::uuid
+	RETURNING
+		id
+)
+INSERT INTO
+	workspace_agent_token_rotations (agent_id, previous_auth_token, previous_auth_token_expires_at, rotated_at)
+SELECT
+	rotated.id, $3::uuid, $4::timestamptz, $5::timestamptz
+FROM
+	rotated
+ON CONFLICT (agent_id) DO UPDATE SET
+	previous_auth_token = EXCLUDED.previous_auth_token,
+	previous_auth_token_expires_at = EXCLUDED.previous_auth_token_expires_at,
+	rotated_at = EXCLUDED.rotated_at
+RETURNING agent_id, previous_auth_token, previous_auth_token_expires_at, rotated_at
+`
+
+type RotateWorkspaceAgentAuthTokenParams struct {
+	NewAuthToken               uuid.UUID `db:"new_auth_token" json:"new_auth_token"`
+	AgentID                    uuid.UUID `db:"agent_id" json:"agent_id"`
+	PreviousAuthToken          uuid.UUID `db:"previous_auth_token" json:"previous_auth_token"`
+	PreviousAuthTokenExpiresAt time.Time `db:"previous_auth_token_expires_at" json:"previous_auth_token_expires_at"`
+	RotatedAt                  time.Time `db:"rotated_at" json:"rotated_at"`
+}
+
+// Replaces the auth token of the agent if it is still @previous_auth_token.
+// The previous token keeps authenticating the agent until
+// @previous_auth_token_expires_at. No row is returned if the token was
+// already rotated.
+func (q *sqlQuerier) RotateWorkspaceAgentAuthToken(ctx context.Context, arg RotateWorkspaceAgentAuthTokenParams) (WorkspaceAgentTokenRotation, error) {
+	row := q.db.QueryRowContext(ctx, rotateWorkspaceAgentAuthToken,
+		arg.NewAuthToken,
+		arg.AgentID,
+		arg.PreviousAuthToken,
+		arg.PreviousAuthTokenExpiresAt,
+		arg.RotatedAt,
+	)
+	var i WorkspaceAgentTokenRotation
+	err := row.Scan(
+		&i.AgentID,
+		&i.PreviousAuthToken,
+		&i.PreviousAuthTokenExpiresAt,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
 const upsertWorkspaceAppAuditSession = `-- name: UpsertWorkspaceAppAuditSession :one
 INSERT INTO
 	workspace_app_audit_sessions (
@@ -16911,11 +23705,13 @@ DO
 		-- ID is used to know if session was reset on upsert.
 		id = CASE
 			WHEN workspace_app_audit_sessions.updated_at > NOW() - ($11::bigint || ' ms')::interval
+				AND workspace_app_audit_sessions.started_at >= $12::timestamptz
 			THEN workspace_app_audit_sessions.id
 			ELSE EXCLUDED.id
 		END,
 		started_at = CASE
 			WHEN workspace_app_audit_sessions.updated_at > NOW() - ($11::bigint || ' ms')::interval
+				AND workspace_app_audit_sessions.started_at >= $12::timestamptz
 			THEN workspace_app_audit_sessions.started_at
 			ELSE EXCLUDED.started_at
 		END,
@@ -16925,22 +23721,25 @@ RETURNING
 `
 
 type UpsertWorkspaceAppAuditSessionParams struct {
-	ID              uuid.UUID `db:"id" json:"id"`
-	AgentID         uuid.UUID `db:"agent_id" json:"agent_id"`
-	AppID           uuid.UUID `db:"app_id" json:"app_id"`
-	UserID          uuid.UUID `db:"user_id" json:"user_id"`
-	Ip              string    `db:"ip" json:"ip"`
-	UserAgent       string    `db:"user_agent" json:"user_agent"`
-	SlugOrPort      string    `db:"slug_or_port" json:"slug_or_port"`
-	StatusCode      int32     `db:"status_code" json:"status_code"`
-	StartedAt       time.Time `db:"started_at" json:"started_at"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
-	StaleIntervalMS int64     `db:"stale_interval_ms" json:"stale_interval_ms"`
+	ID                 uuid.UUID `db:"id" json:"id"`
+	AgentID            uuid.UUID `db:"agent_id" json:"agent_id"`
+	AppID              uuid.UUID `db:"app_id" json:"app_id"`
+	UserID             uuid.UUID `db:"user_id" json:"user_id"`
+	Ip                 string    `db:"ip" json:"ip"`
+	UserAgent          string    `db:"user_agent" json:"user_agent"`
+	SlugOrPort         string    `db:"slug_or_port" json:"slug_or_port"`
+	StatusCode         int32     `db:"status_code" json:"status_code"`
+	StartedAt          time.Time `db:"started_at" json:"started_at"`
+	UpdatedAt          time.Time `db:"updated_at" json:"updated_at"`
+	StaleIntervalMS    int64     `db:"stale_interval_ms" json:"stale_interval_ms"`
+	AuthenticatedAfter time.Time `db:"authenticated_after" json:"authenticated_after"`
 }
 
 // The returned boolean, new_or_stale, can be used to deduce if a new session
 // was started. This means that a new row was inserted (no previous session) or
-// the updated_at is older than stale interval.
+// the updated_at is older than stale interval. Sessions that started before
+// authenticated_after are also stale, so that re-authenticating starts a new
+// session.
 func (q *sqlQuerier) UpsertWorkspaceAppAuditSession(ctx context.Context, arg UpsertWorkspaceAppAuditSessionParams) (bool, error) {
 	row := q.db.QueryRowContext(ctx, upsertWorkspaceAppAuditSession,
 		arg.ID,
@@ -16954,6 +23753,7 @@ func (q *sqlQuerier) UpsertWorkspaceAppAuditSession(ctx context.Context, arg Ups
 		arg.StartedAt,
 		arg.UpdatedAt,
 		arg.StaleIntervalMS,
+		arg.AuthenticatedAfter,
 	)
 	var new_or_stale bool
 	err := row.Scan(&new_or_stale)
@@ -17452,7 +24252,241 @@ func (q *sqlQuerier) InsertWorkspaceAppStats(ctx context.Context, arg InsertWork
 		pq.Array(arg.SessionEndedAt),
 		pq.Array(arg.Requests),
 	)
-	return err
+	return err
+}
+
+const deleteWorkspaceArchiveByID = `-- name: DeleteWorkspaceArchiveByID :exec
+DELETE FROM
+	workspace_archives
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceArchiveByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceArchiveByID, id)
+	return err
+}
+
+const getLatestWorkspaceArchiveByWorkspaceID = `-- name: GetLatestWorkspaceArchiveByWorkspaceID :one
+SELECT
+	id, workspace_id, workspace_name, owner_id, organization_id, template_id, paths, object_key, size_bytes, error, created_at, expires_at
+FROM
+	workspace_archives
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at DESC
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetLatestWorkspaceArchiveByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceArchive, error) {
+	row := q.db.QueryRowContext(ctx, getLatestWorkspaceArchiveByWorkspaceID, workspaceID)
+	var i WorkspaceArchive
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.WorkspaceName,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		pq.Array(&i.Paths),
+		&i.ObjectKey,
+		&i.SizeBytes,
+		&i.Error,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getWorkspaceArchiveByID = `-- name: GetWorkspaceArchiveByID :one
+SELECT
+	id, workspace_id, workspace_name, owner_id, organization_id, template_id, paths, object_key, size_bytes, error, created_at, expires_at
+FROM
+	workspace_archives
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceArchiveByID(ctx context.Context, id uuid.UUID) (WorkspaceArchive, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceArchiveByID, id)
+	var i WorkspaceArchive
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.WorkspaceName,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		pq.Array(&i.Paths),
+		&i.ObjectKey,
+		&i.SizeBytes,
+		&i.Error,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getWorkspaceArchivesByOwnerID = `-- name: GetWorkspaceArchivesByOwnerID :many
+SELECT
+	id, workspace_id, workspace_name, owner_id, organization_id, template_id, paths, object_key, size_bytes, error, created_at, expires_at
+FROM
+	workspace_archives
+WHERE
+	owner_id = $1
+ORDER BY
+	created_at DESC
+`
+
+func (q *sqlQuerier) GetWorkspaceArchivesByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]WorkspaceArchive, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceArchivesByOwnerID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceArchive
+	for rows.Next() {
+		var i WorkspaceArchive
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.OwnerID,
+			&i.OrganizationID,
+			&i.TemplateID,
+			pq.Array(&i.Paths),
+			&i.ObjectKey,
+			&i.SizeBytes,
+			&i.Error,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceArchivesExpiredBefore = `-- name: GetWorkspaceArchivesExpiredBefore :many
+SELECT
+	id, workspace_id, workspace_name, owner_id, organization_id, template_id, paths, object_key, size_bytes, error, created_at, expires_at
+FROM
+	workspace_archives
+WHERE
+	expires_at < $1 :: timestamptz
+`
+
+func (q *sqlQuerier) GetWorkspaceArchivesExpiredBefore(ctx context.Context, now time.Time) ([]WorkspaceArchive, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceArchivesExpiredBefore, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceArchive
+	for rows.Next() {
+		var i WorkspaceArchive
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.OwnerID,
+			&i.OrganizationID,
+			&i.TemplateID,
+			pq.Array(&i.Paths),
+			&i.ObjectKey,
+			&i.SizeBytes,
+			&i.Error,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceArchive = `-- name: InsertWorkspaceArchive :one
+INSERT INTO
+	workspace_archives (
+		id,
+		workspace_id,
+		workspace_name,
+		owner_id,
+		organization_id,
+		template_id,
+		paths,
+		object_key,
+		size_bytes,
+		error,
+		created_at,
+		expires_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, workspace_id, workspace_name, owner_id, organization_id, template_id, paths, object_key, size_bytes, error, created_at, expires_at
+`
+
+type InsertWorkspaceArchiveParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName  string    `db:"workspace_name" json:"workspace_name"`
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	Paths          []string  `db:"paths" json:"paths"`
+	ObjectKey      string    `db:"object_key" json:"object_key"`
+	SizeBytes      int64     `db:"size_bytes" json:"size_bytes"`
+	Error          string    `db:"error" json:"error"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt      time.Time `db:"expires_at" json:"expires_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceArchive(ctx context.Context, arg InsertWorkspaceArchiveParams) (WorkspaceArchive, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceArchive,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.WorkspaceName,
+		arg.OwnerID,
+		arg.OrganizationID,
+		arg.TemplateID,
+		pq.Array(arg.Paths),
+		arg.ObjectKey,
+		arg.SizeBytes,
+		arg.Error,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	var i WorkspaceArchive
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.WorkspaceName,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		pq.Array(&i.Paths),
+		&i.ObjectKey,
+		&i.SizeBytes,
+		&i.Error,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
 }
 
 const getUserWorkspaceBuildParameters = `-- name: GetUserWorkspaceBuildParameters :many
@@ -17608,7 +24642,7 @@ func (q *sqlQuerier) InsertWorkspaceBuildParameters(ctx context.Context, arg Ins
 }
 
 const getActiveWorkspaceBuildsByTemplateID = `-- name: GetActiveWorkspaceBuildsByTemplateID :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.template_version_preset_id, wb.has_ai_task, wb.ai_task_sidebar_app_id, wb.initiator_by_avatar_url, wb.initiator_by_username, wb.initiator_by_name
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.template_version_preset_id, wb.has_ai_task, wb.ai_task_sidebar_app_id, wb.reason_message, wb.initiator_api_key_name, wb.initiator_ci_job_url, wb.initiator_by_avatar_url, wb.initiator_by_username, wb.initiator_by_name
 FROM (
     SELECT
         workspace_id, MAX(build_number) as max_build_number
@@ -17665,6 +24699,9 @@ func (q *sqlQuerier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, t
 			&i.TemplateVersionPresetID,
 			&i.HasAITask,
 			&i.AITaskSidebarAppID,
+			&i.ReasonMessage,
+			&i.InitiatorAPIKeyName,
+			&i.InitiatorCIJobURL,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 			&i.InitiatorByName,
@@ -17764,7 +24801,7 @@ func (q *sqlQuerier) GetFailedWorkspaceBuildsByTemplateID(ctx context.Context, a
 
 const getLatestWorkspaceBuildByWorkspaceID = `-- name: GetLatestWorkspaceBuildByWorkspaceID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, reason_message, initiator_api_key_name, initiator_ci_job_url, initiator_by_avatar_url, initiator_by_username, initiator_by_name
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -17796,6 +24833,9 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, w
 		&i.TemplateVersionPresetID,
 		&i.HasAITask,
 		&i.AITaskSidebarAppID,
+		&i.ReasonMessage,
+		&i.InitiatorAPIKeyName,
+		&i.InitiatorCIJobURL,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 		&i.InitiatorByName,
@@ -17804,7 +24844,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, w
 }
 
 const getLatestWorkspaceBuilds = `-- name: GetLatestWorkspaceBuilds :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.template_version_preset_id, wb.has_ai_task, wb.ai_task_sidebar_app_id, wb.initiator_by_avatar_url, wb.initiator_by_username, wb.initiator_by_name
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.template_version_preset_id, wb.has_ai_task, wb.ai_task_sidebar_app_id, wb.reason_message, wb.initiator_api_key_name, wb.initiator_ci_job_url, wb.initiator_by_avatar_url, wb.initiator_by_username, wb.initiator_by_name
 FROM (
     SELECT
         workspace_id, MAX(build_number) as max_build_number
@@ -17845,6 +24885,9 @@ func (q *sqlQuerier) GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceB
 			&i.TemplateVersionPresetID,
 			&i.HasAITask,
 			&i.AITaskSidebarAppID,
+			&i.ReasonMessage,
+			&i.InitiatorAPIKeyName,
+			&i.InitiatorCIJobURL,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 			&i.InitiatorByName,
@@ -17863,7 +24906,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceB
 }
 
 const getLatestWorkspaceBuildsByWorkspaceIDs = `-- name: GetLatestWorkspaceBuildsByWorkspaceIDs :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.template_version_preset_id, wb.has_ai_task, wb.ai_task_sidebar_app_id, wb.initiator_by_avatar_url, wb.initiator_by_username, wb.initiator_by_name
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.template_version_preset_id, wb.has_ai_task, wb.ai_task_sidebar_app_id, wb.reason_message, wb.initiator_api_key_name, wb.initiator_ci_job_url, wb.initiator_by_avatar_url, wb.initiator_by_username, wb.initiator_by_name
 FROM (
     SELECT
         workspace_id, MAX(build_number) as max_build_number
@@ -17906,6 +24949,9 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context,
 			&i.TemplateVersionPresetID,
 			&i.HasAITask,
 			&i.AITaskSidebarAppID,
+			&i.ReasonMessage,
+			&i.InitiatorAPIKeyName,
+			&i.InitiatorCIJobURL,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 			&i.InitiatorByName,
@@ -17923,9 +24969,69 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context,
 	return items, nil
 }
 
+const getRollbackWorkspaceBuild = `-- name: GetRollbackWorkspaceBuild :one
+SELECT
+	wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.template_version_preset_id, wb.has_ai_task, wb.ai_task_sidebar_app_id, wb.reason_message, wb.initiator_api_key_name, wb.initiator_ci_job_url, wb.initiator_by_avatar_url, wb.initiator_by_username, wb.initiator_by_name
+FROM
+	workspace_build_with_user AS wb
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = wb.job_id
+WHERE
+	wb.workspace_id = $1
+	AND wb.build_number < (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds
+		WHERE
+			workspace_builds.workspace_id = $1
+			AND workspace_builds.transition = 'start'::workspace_transition
+	)
+	AND wb.transition = 'start'::workspace_transition
+	AND provisioner_jobs.job_status = 'succeeded'::provisioner_job_status
+ORDER BY
+	wb.build_number DESC
+LIMIT
+	1
+`
+
+// Returns the most recent successful start build of a workspace that was
+// created before its latest start build. Rolling back a workspace starts it
+// with the template version and parameters of this build.
+func (q *sqlQuerier) GetRollbackWorkspaceBuild(ctx context.Context, workspaceID uuid.UUID) (WorkspaceBuild, error) {
+	row := q.db.QueryRowContext(ctx, getRollbackWorkspaceBuild, workspaceID)
+	var i WorkspaceBuild
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.TemplateVersionID,
+		&i.BuildNumber,
+		&i.Transition,
+		&i.InitiatorID,
+		&i.ProvisionerState,
+		&i.JobID,
+		&i.Deadline,
+		&i.Reason,
+		&i.DailyCost,
+		&i.MaxDeadline,
+		&i.TemplateVersionPresetID,
+		&i.HasAITask,
+		&i.AITaskSidebarAppID,
+		&i.ReasonMessage,
+		&i.InitiatorAPIKeyName,
+		&i.InitiatorCIJobURL,
+		&i.InitiatorByAvatarUrl,
+		&i.InitiatorByUsername,
+		&i.InitiatorByName,
+	)
+	return i, err
+}
+
 const getWorkspaceBuildByID = `-- name: GetWorkspaceBuildByID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, reason_message, initiator_api_key_name, initiator_ci_job_url, initiator_by_avatar_url, initiator_by_username, initiator_by_name
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -17955,6 +25061,9 @@ func (q *sqlQuerier) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (W
 		&i.TemplateVersionPresetID,
 		&i.HasAITask,
 		&i.AITaskSidebarAppID,
+		&i.ReasonMessage,
+		&i.InitiatorAPIKeyName,
+		&i.InitiatorCIJobURL,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 		&i.InitiatorByName,
@@ -17964,7 +25073,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (W
 
 const getWorkspaceBuildByJobID = `-- name: GetWorkspaceBuildByJobID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, reason_message, initiator_api_key_name, initiator_ci_job_url, initiator_by_avatar_url, initiator_by_username, initiator_by_name
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -17994,6 +25103,9 @@ func (q *sqlQuerier) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UU
 		&i.TemplateVersionPresetID,
 		&i.HasAITask,
 		&i.AITaskSidebarAppID,
+		&i.ReasonMessage,
+		&i.InitiatorAPIKeyName,
+		&i.InitiatorCIJobURL,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 		&i.InitiatorByName,
@@ -18003,7 +25115,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UU
 
 const getWorkspaceBuildByWorkspaceIDAndBuildNumber = `-- name: GetWorkspaceBuildByWorkspaceIDAndBuildNumber :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, reason_message, initiator_api_key_name, initiator_ci_job_url, initiator_by_avatar_url, initiator_by_username, initiator_by_name
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -18037,6 +25149,9 @@ func (q *sqlQuerier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Co
 		&i.TemplateVersionPresetID,
 		&i.HasAITask,
 		&i.AITaskSidebarAppID,
+		&i.ReasonMessage,
+		&i.InitiatorAPIKeyName,
+		&i.InitiatorCIJobURL,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 		&i.InitiatorByName,
@@ -18113,7 +25228,7 @@ func (q *sqlQuerier) GetWorkspaceBuildStatsByTemplates(ctx context.Context, sinc
 
 const getWorkspaceBuildsByWorkspaceID = `-- name: GetWorkspaceBuildsByWorkspaceID :many
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_by_avatar_url, initiator_by_username, initiator_by_name
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, reason_message, initiator_api_key_name, initiator_ci_job_url, initiator_by_avatar_url, initiator_by_username, initiator_by_name
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -18186,6 +25301,9 @@ func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg Ge
 			&i.TemplateVersionPresetID,
 			&i.HasAITask,
 			&i.AITaskSidebarAppID,
+			&i.ReasonMessage,
+			&i.InitiatorAPIKeyName,
+			&i.InitiatorCIJobURL,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 			&i.InitiatorByName,
@@ -18204,7 +25322,7 @@ func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg Ge
 }
 
 const getWorkspaceBuildsCreatedAfter = `-- name: GetWorkspaceBuildsCreatedAfter :many
-SELECT id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, initiator_by_avatar_url, initiator_by_username, initiator_by_name FROM workspace_build_with_user WHERE created_at > $1
+SELECT id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, template_version_preset_id, has_ai_task, ai_task_sidebar_app_id, reason_message, initiator_api_key_name, initiator_ci_job_url, initiator_by_avatar_url, initiator_by_username, initiator_by_name FROM workspace_build_with_user WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceBuild, error) {
@@ -18234,6 +25352,9 @@ func (q *sqlQuerier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, created
 			&i.TemplateVersionPresetID,
 			&i.HasAITask,
 			&i.AITaskSidebarAppID,
+			&i.ReasonMessage,
+			&i.InitiatorAPIKeyName,
+			&i.InitiatorCIJobURL,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 			&i.InitiatorByName,
@@ -18267,10 +25388,13 @@ INSERT INTO
 		deadline,
 		max_deadline,
 		reason,
-		template_version_preset_id
+		template_version_preset_id,
+		reason_message,
+		initiator_api_key_name,
+		initiator_ci_job_url
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 `
 
 type InsertWorkspaceBuildParams struct {
@@ -18288,6 +25412,9 @@ type InsertWorkspaceBuildParams struct {
 	MaxDeadline             time.Time           `db:"max_deadline" json:"max_deadline"`
 	Reason                  BuildReason         `db:"reason" json:"reason"`
 	TemplateVersionPresetID uuid.NullUUID       `db:"template_version_preset_id" json:"template_version_preset_id"`
+	ReasonMessage           string              `db:"reason_message" json:"reason_message"`
+	InitiatorAPIKeyName     string              `db:"initiator_api_key_name" json:"initiator_api_key_name"`
+	InitiatorCIJobURL       string              `db:"initiator_ci_job_url" json:"initiator_ci_job_url"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error {
@@ -18306,6 +25433,9 @@ func (q *sqlQuerier) InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspa
 		arg.MaxDeadline,
 		arg.Reason,
 		arg.TemplateVersionPresetID,
+		arg.ReasonMessage,
+		arg.InitiatorAPIKeyName,
+		arg.InitiatorCIJobURL,
 	)
 	return err
 }
@@ -18392,42 +25522,321 @@ SET
 WHERE id = $3::uuid
 `
 
-type UpdateWorkspaceBuildProvisionerStateByIDParams struct {
-	ProvisionerState []byte    `db:"provisioner_state" json:"provisioner_state"`
-	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
-	ID               uuid.UUID `db:"id" json:"id"`
+type UpdateWorkspaceBuildProvisionerStateByIDParams struct {
+	ProvisionerState []byte    `db:"provisioner_state" json:"provisioner_state"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+	ID               uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg UpdateWorkspaceBuildProvisionerStateByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildProvisionerStateByID, arg.ProvisionerState, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const deleteWorkspaceLabelsByWorkspaceID = `-- name: DeleteWorkspaceLabelsByWorkspaceID :exec
+DELETE FROM
+	workspace_labels
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceLabelsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceLabelsByWorkspaceID, workspaceID)
+	return err
+}
+
+const getWorkspaceLabelsByWorkspaceIDs = `-- name: GetWorkspaceLabelsByWorkspaceIDs :many
+SELECT
+	workspace_id, key, value
+FROM
+	workspace_labels
+WHERE
+	workspace_id = ANY($1 :: uuid[])
+ORDER BY
+	workspace_id, key
+`
+
+func (q *sqlQuerier) GetWorkspaceLabelsByWorkspaceIDs(ctx context.Context, workspaceIds []uuid.UUID) ([]WorkspaceLabel, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceLabelsByWorkspaceIDs, pq.Array(workspaceIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceLabel
+	for rows.Next() {
+		var i WorkspaceLabel
+		if err := rows.Scan(&i.WorkspaceID, &i.Key, &i.Value); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceLabels = `-- name: InsertWorkspaceLabels :exec
+INSERT INTO
+	workspace_labels (workspace_id, key, value)
+SELECT
+	$1,
+	unnest($2 :: text[]),
+	unnest($3 :: text[])
+`
+
+type InsertWorkspaceLabelsParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Keys        []string  `db:"keys" json:"keys"`
+	Values      []string  `db:"values" json:"values"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceLabels(ctx context.Context, arg InsertWorkspaceLabelsParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceLabels, arg.WorkspaceID, pq.Array(arg.Keys), pq.Array(arg.Values))
+	return err
+}
+
+const getWorkspaceModulesByJobID = `-- name: GetWorkspaceModulesByJobID :many
+SELECT
+	id, job_id, transition, source, version, key, created_at
+FROM
+	workspace_modules
+WHERE
+	job_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]WorkspaceModule, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceModulesByJobID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceModule
+	for rows.Next() {
+		var i WorkspaceModule
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobID,
+			&i.Transition,
+			&i.Source,
+			&i.Version,
+			&i.Key,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceModulesCreatedAfter = `-- name: GetWorkspaceModulesCreatedAfter :many
+SELECT id, job_id, transition, source, version, key, created_at FROM workspace_modules WHERE created_at > $1
+`
+
+func (q *sqlQuerier) GetWorkspaceModulesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceModule, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceModulesCreatedAfter, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceModule
+	for rows.Next() {
+		var i WorkspaceModule
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobID,
+			&i.Transition,
+			&i.Source,
+			&i.Version,
+			&i.Key,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceModule = `-- name: InsertWorkspaceModule :one
+INSERT INTO
+	workspace_modules (id, job_id, transition, source, version, key, created_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, job_id, transition, source, version, key, created_at
+`
+
+type InsertWorkspaceModuleParams struct {
+	ID         uuid.UUID           `db:"id" json:"id"`
+	JobID      uuid.UUID           `db:"job_id" json:"job_id"`
+	Transition WorkspaceTransition `db:"transition" json:"transition"`
+	Source     string              `db:"source" json:"source"`
+	Version    string              `db:"version" json:"version"`
+	Key        string              `db:"key" json:"key"`
+	CreatedAt  time.Time           `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceModule(ctx context.Context, arg InsertWorkspaceModuleParams) (WorkspaceModule, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceModule,
+		arg.ID,
+		arg.JobID,
+		arg.Transition,
+		arg.Source,
+		arg.Version,
+		arg.Key,
+		arg.CreatedAt,
+	)
+	var i WorkspaceModule
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.Transition,
+		&i.Source,
+		&i.Version,
+		&i.Key,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const addWorkspacePortShareLinkBytesTransferred = `-- name: AddWorkspacePortShareLinkBytesTransferred :exec
+UPDATE
+	workspace_port_share_links
+SET
+	bytes_transferred = bytes_transferred + $1
+WHERE
+	id = $2
+`
+
+type AddWorkspacePortShareLinkBytesTransferredParams struct {
+	Bytes int64     `db:"bytes" json:"bytes"`
+	ID    uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) AddWorkspacePortShareLinkBytesTransferred(ctx context.Context, arg AddWorkspacePortShareLinkBytesTransferredParams) error {
+	_, err := q.db.ExecContext(ctx, addWorkspacePortShareLinkBytesTransferred, arg.Bytes, arg.ID)
+	return err
+}
+
+const deleteExpiredWorkspacePortShareLinks = `-- name: DeleteExpiredWorkspacePortShareLinks :exec
+DELETE FROM
+	workspace_port_share_links
+WHERE
+	expires_at < $1
+`
+
+func (q *sqlQuerier) DeleteExpiredWorkspacePortShareLinks(ctx context.Context, now time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredWorkspacePortShareLinks, now)
+	return err
+}
+
+const deleteWorkspacePortShareLink = `-- name: DeleteWorkspacePortShareLink :exec
+DELETE FROM
+	workspace_port_share_links
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspacePortShareLink(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspacePortShareLink, id)
+	return err
+}
+
+const deleteWorkspacePortShareLinksByTemplate = `-- name: DeleteWorkspacePortShareLinksByTemplate :exec
+DELETE FROM
+	workspace_port_share_links
+WHERE
+	workspace_id IN (
+		SELECT
+			id
+		FROM
+			workspaces
+		WHERE
+			template_id = $1
+	)
+`
+
+func (q *sqlQuerier) DeleteWorkspacePortShareLinksByTemplate(ctx context.Context, templateID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspacePortShareLinksByTemplate, templateID)
+	return err
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg UpdateWorkspaceBuildProvisionerStateByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildProvisionerStateByID, arg.ProvisionerState, arg.UpdatedAt, arg.ID)
-	return err
+const getWorkspacePortShareLinkByID = `-- name: GetWorkspacePortShareLinkByID :one
+SELECT
+	id, workspace_id, agent_name, port, protocol, hashed_secret, hashed_password, bandwidth_limit_bytes, bytes_transferred, expires_at, created_by, created_at
+FROM
+	workspace_port_share_links
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWorkspacePortShareLinkByID(ctx context.Context, id uuid.UUID) (WorkspacePortShareLink, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspacePortShareLinkByID, id)
+	var i WorkspacePortShareLink
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.Protocol,
+		&i.HashedSecret,
+		&i.HashedPassword,
+		&i.BandwidthLimitBytes,
+		&i.BytesTransferred,
+		&i.ExpiresAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
-const getWorkspaceModulesByJobID = `-- name: GetWorkspaceModulesByJobID :many
+const getWorkspacePortShareLinksByWorkspaceID = `-- name: GetWorkspacePortShareLinksByWorkspaceID :many
 SELECT
-	id, job_id, transition, source, version, key, created_at
+	id, workspace_id, agent_name, port, protocol, hashed_secret, hashed_password, bandwidth_limit_bytes, bytes_transferred, expires_at, created_by, created_at
 FROM
-	workspace_modules
+	workspace_port_share_links
 WHERE
-	job_id = $1
+	workspace_id = $1
+ORDER BY
+	created_at DESC
 `
 
-func (q *sqlQuerier) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]WorkspaceModule, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceModulesByJobID, jobID)
+func (q *sqlQuerier) GetWorkspacePortShareLinksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspacePortShareLink, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacePortShareLinksByWorkspaceID, workspaceID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceModule
+	var items []WorkspacePortShareLink
 	for rows.Next() {
-		var i WorkspaceModule
+		var i WorkspacePortShareLink
 		if err := rows.Scan(
 			&i.ID,
-			&i.JobID,
-			&i.Transition,
-			&i.Source,
-			&i.Version,
-			&i.Key,
+			&i.WorkspaceID,
+			&i.AgentName,
+			&i.Port,
+			&i.Protocol,
+			&i.HashedSecret,
+			&i.HashedPassword,
+			&i.BandwidthLimitBytes,
+			&i.BytesTransferred,
+			&i.ExpiresAt,
+			&i.CreatedBy,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -18443,27 +25852,124 @@ func (q *sqlQuerier) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.
 	return items, nil
 }
 
-const getWorkspaceModulesCreatedAfter = `-- name: GetWorkspaceModulesCreatedAfter :many
-SELECT id, job_id, transition, source, version, key, created_at FROM workspace_modules WHERE created_at > $1
+const insertWorkspacePortShareLink = `-- name: InsertWorkspacePortShareLink :one
+INSERT INTO workspace_port_share_links (
+	id,
+	workspace_id,
+	agent_name,
+	port,
+	protocol,
+	hashed_secret,
+	hashed_password,
+	bandwidth_limit_bytes,
+	expires_at,
+	created_by,
+	created_at
+)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7,
+	$8,
+	$9,
+	$10,
+	$11
+)
+RETURNING id, workspace_id, agent_name, port, protocol, hashed_secret, hashed_password, bandwidth_limit_bytes, bytes_transferred, expires_at, created_by, created_at
 `
 
-func (q *sqlQuerier) GetWorkspaceModulesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceModule, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceModulesCreatedAfter, createdAt)
+type InsertWorkspacePortShareLinkParams struct {
+	ID                  uuid.UUID         `db:"id" json:"id"`
+	WorkspaceID         uuid.UUID         `db:"workspace_id" json:"workspace_id"`
+	AgentName           string            `db:"agent_name" json:"agent_name"`
+	Port                int32             `db:"port" json:"port"`
+	Protocol            PortShareProtocol `db:"protocol" json:"protocol"`
+	HashedSecret        []byte            `db:"hashed_secret" json:"hashed_secret"`
+	HashedPassword      string            `db:"hashed_password" json:"hashed_password"`
+	BandwidthLimitBytes int64             `db:"bandwidth_limit_bytes" json:"bandwidth_limit_bytes"`
+	ExpiresAt           time.Time         `db:"expires_at" json:"expires_at"`
+	CreatedBy           uuid.UUID         `db:"created_by" json:"created_by"`
+	CreatedAt           time.Time         `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspacePortShareLink(ctx context.Context, arg InsertWorkspacePortShareLinkParams) (WorkspacePortShareLink, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspacePortShareLink,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.AgentName,
+		arg.Port,
+		arg.Protocol,
+		arg.HashedSecret,
+		arg.HashedPassword,
+		arg.BandwidthLimitBytes,
+		arg.ExpiresAt,
+		arg.CreatedBy,
+		arg.CreatedAt,
+	)
+	var i WorkspacePortShareLink
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.Protocol,
+		&i.HashedSecret,
+		&i.HashedPassword,
+		&i.BandwidthLimitBytes,
+		&i.BytesTransferred,
+		&i.ExpiresAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceProxyTokenRotationByProxyID = `-- name: GetWorkspaceProxyTokenRotationByProxyID :one
+SELECT
+	proxy_id, previous_token_hashed_secret, previous_token_expires_at, rotated_at
+FROM
+	workspace_proxy_token_rotations
+WHERE
+	proxy_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceProxyTokenRotationByProxyID(ctx context.Context, proxyID uuid.UUID) (WorkspaceProxyTokenRotation, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceProxyTokenRotationByProxyID, proxyID)
+	var i WorkspaceProxyTokenRotation
+	err := row.Scan(
+		&i.ProxyID,
+		&i.PreviousTokenHashedSecret,
+		&i.PreviousTokenExpiresAt,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceProxyTokenRotations = `-- name: GetWorkspaceProxyTokenRotations :many
+SELECT
+	proxy_id, previous_token_hashed_secret, previous_token_expires_at, rotated_at
+FROM
+	workspace_proxy_token_rotations
+`
+
+func (q *sqlQuerier) GetWorkspaceProxyTokenRotations(ctx context.Context) ([]WorkspaceProxyTokenRotation, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceProxyTokenRotations)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceModule
+	var items []WorkspaceProxyTokenRotation
 	for rows.Next() {
-		var i WorkspaceModule
+		var i WorkspaceProxyTokenRotation
 		if err := rows.Scan(
-			&i.ID,
-			&i.JobID,
-			&i.Transition,
-			&i.Source,
-			&i.Version,
-			&i.Key,
-			&i.CreatedAt,
+			&i.ProxyID,
+			&i.PreviousTokenHashedSecret,
+			&i.PreviousTokenExpiresAt,
+			&i.RotatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -18478,42 +25984,58 @@ func (q *sqlQuerier) GetWorkspaceModulesCreatedAfter(ctx context.Context, create
 	return items, nil
 }
 
-const insertWorkspaceModule = `-- name: InsertWorkspaceModule :one
+const rotateWorkspaceProxyToken = `-- name: RotateWorkspaceProxyToken :one
+WITH rotated AS (
+	UPDATE
+		workspace_proxies
+	SET
+		token_hashed_secret = $1::bytea,
+		updated_at = $2::timestamptz
+	WHERE
+		id = $3::uuid
+		AND token_hashed_secret = $4::bytea
+	RETURNING
+		id
+)
 INSERT INTO
-	workspace_modules (id, job_id, transition, source, version, key, created_at)
-VALUES
-	($1, $2, $3, $4, $5, $6, $7) RETURNING id, job_id, transition, source, version, key, created_at
-`
-
-type InsertWorkspaceModuleParams struct {
-	ID         uuid.UUID           `db:"id" json:"id"`
-	JobID      uuid.UUID           `db:"job_id" json:"job_id"`
-	Transition WorkspaceTransition `db:"transition" json:"transition"`
-	Source     string              `db:"source" json:"source"`
-	Version    string              `db:"version" json:"version"`
-	Key        string              `db:"key" json:"key"`
-	CreatedAt  time.Time           `db:"created_at" json:"created_at"`
-}
-
-func (q *sqlQuerier) InsertWorkspaceModule(ctx context.Context, arg InsertWorkspaceModuleParams) (WorkspaceModule, error) {
-	row := q.db.QueryRowContext(ctx, insertWorkspaceModule,
-		arg.ID,
-		arg.JobID,
-		arg.Transition,
-		arg.Source,
-		arg.Version,
-		arg.Key,
-		arg.CreatedAt,
-	)
-	var i WorkspaceModule
+	workspace_proxy_token_rotations (proxy_id, previous_token_hashed_secret, previous_token_expires_at, rotated_at)
+SELECT
+	rotated.id, $4::bytea, $5::timestamptz, $2::timestamptz
+FROM
+	rotated
+ON CONFLICT (proxy_id) DO UPDATE SET
+	previous_token_hashed_secret = EXCLUDED.previous_token_hashed_secret,
+	previous_token_expires_at = EXCLUDED.previous_token_expires_at,
+	rotated_at = EXCLUDED.rotated_at
+RETURNING proxy_id, previous_token_hashed_secret, previous_token_expires_at, rotated_at
+`
+
+type RotateWorkspaceProxyTokenParams struct {
+	NewTokenHashedSecret      []byte    `db:"new_token_hashed_secret" json:"new_token_hashed_secret"`
+	RotatedAt                 time.Time `db:"rotated_at" json:"rotated_at"`
+	ProxyID                   uuid.UUID `db:"proxy_id" json:"proxy_id"`
+	PreviousTokenHashedSecret []byte    `db:"previous_token_hashed_secret" json:"previous_token_hashed_secret"`
+	PreviousTokenExpiresAt    time.Time `db:"previous_token_expires_at" json:"previous_token_expires_at"`
+}
+
+// Replaces the token of the proxy if it is still
+// @previous_token_hashed_secret. The previous token keeps authenticating the
+// proxy until @previous_token_expires_at. No row is returned if the token was
+// already rotated.
+func (q *sqlQuerier) RotateWorkspaceProxyToken(ctx context.Context, arg RotateWorkspaceProxyTokenParams) (WorkspaceProxyTokenRotation, error) {
+	row := q.db.QueryRowContext(ctx, rotateWorkspaceProxyToken,
+		arg.NewTokenHashedSecret,
+		arg.RotatedAt,
+		arg.ProxyID,
+		arg.PreviousTokenHashedSecret,
+		arg.PreviousTokenExpiresAt,
+	)
+	var i WorkspaceProxyTokenRotation
 	err := row.Scan(
-		&i.ID,
-		&i.JobID,
-		&i.Transition,
-		&i.Source,
-		&i.Version,
-		&i.Key,
-		&i.CreatedAt,
+		&i.ProxyID,
+		&i.PreviousTokenHashedSecret,
+		&i.PreviousTokenExpiresAt,
+		&i.RotatedAt,
 	)
 	return i, err
 }
@@ -18993,7 +26515,7 @@ func (q *sqlQuerier) GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploy
 
 const getWorkspaceByAgentID = `-- name: GetWorkspaceByAgentID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, ephemeral, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
 FROM
 	workspaces_expanded as workspaces
 WHERE
@@ -19041,6 +26563,7 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.Ephemeral,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OwnerName,
@@ -19058,7 +26581,7 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 
 const getWorkspaceByID = `-- name: GetWorkspaceByID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, ephemeral, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
 FROM
 	workspaces_expanded
 WHERE
@@ -19087,6 +26610,7 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.Ephemeral,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OwnerName,
@@ -19104,7 +26628,7 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 
 const getWorkspaceByOwnerIDAndName = `-- name: GetWorkspaceByOwnerIDAndName :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, ephemeral, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
 FROM
 	workspaces_expanded as workspaces
 WHERE
@@ -19140,6 +26664,7 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.Ephemeral,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OwnerName,
@@ -19157,7 +26682,7 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 
 const getWorkspaceByResourceID = `-- name: GetWorkspaceByResourceID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, ephemeral, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
 FROM
 	workspaces_expanded as workspaces
 WHERE
@@ -19200,6 +26725,7 @@ func (q *sqlQuerier) GetWorkspaceByResourceID(ctx context.Context, resourceID uu
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.Ephemeral,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OwnerName,
@@ -19217,7 +26743,7 @@ func (q *sqlQuerier) GetWorkspaceByResourceID(ctx context.Context, resourceID uu
 
 const getWorkspaceByWorkspaceAppID = `-- name: GetWorkspaceByWorkspaceAppID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, ephemeral, owner_avatar_url, owner_username, owner_name, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
 FROM
 	workspaces_expanded as workspaces
 WHERE
@@ -19272,6 +26798,7 @@ func (q *sqlQuerier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspace
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.Ephemeral,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OwnerName,
@@ -19332,7 +26859,7 @@ SELECT
 ),
 filtered_workspaces AS (
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.owner_avatar_url, workspaces.owner_username, workspaces.owner_name, workspaces.organization_name, workspaces.organization_display_name, workspaces.organization_icon, workspaces.organization_description, workspaces.template_name, workspaces.template_display_name, workspaces.template_icon, workspaces.template_description,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.ephemeral, workspaces.owner_avatar_url, workspaces.owner_username, workspaces.owner_name, workspaces.organization_name, workspaces.organization_display_name, workspaces.organization_icon, workspaces.organization_description, workspaces.template_name, workspaces.template_display_name, workspaces.template_icon, workspaces.template_description,
 	latest_build.template_version_id,
 	latest_build.template_version_name,
 	latest_build.completed_at as latest_build_completed_at,
@@ -19380,7 +26907,7 @@ LEFT JOIN LATERAL (
 ) latest_build ON TRUE
 LEFT JOIN LATERAL (
 	SELECT
-		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow
+		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, wireguard_mtu, wireguard_keepalive_interval, wireguard_handshake_timeout, activation_approvals_required, autostart_holidays, autostart_holiday_calendar_url, dormant_archive_paths, parameter_validation_url, allow_start_on_connect
 	FROM
 		templates
 	WHERE
@@ -19593,16 +27120,39 @@ WHERE
 			)) = ($19 :: boolean)
 		ELSE true
 	END
+	-- Filter by labels
+	-- @label_keys and @label_values are matched by array index. A workspace
+	-- must have every label, and an empty value matches any value of the key.
+	AND CASE WHEN array_length($20 :: text[], 1) > 0 THEN
+		NOT EXISTS (
+			SELECT
+				1
+			FROM
+				unnest($20 :: text[], $21 :: text[]) AS label_filter(key, value)
+			WHERE
+				NOT EXISTS (
+					SELECT
+						1
+					FROM
+						workspace_labels
+					WHERE
+						workspace_labels.workspace_id = workspaces.id AND
+						workspace_labels.key = label_filter.key AND
+						(label_filter.value = '' OR workspace_labels.value = label_filter.value)
+				)
+		)
+		ELSE true
+	END
 	-- Authorize Filter clause will be injected below in GetAuthorizedWorkspaces
 	-- @authorize_filter
 ), filtered_workspaces_order AS (
 	SELECT
-		fw.id, fw.created_at, fw.updated_at, fw.owner_id, fw.organization_id, fw.template_id, fw.deleted, fw.name, fw.autostart_schedule, fw.ttl, fw.last_used_at, fw.dormant_at, fw.deleting_at, fw.automatic_updates, fw.favorite, fw.next_start_at, fw.owner_avatar_url, fw.owner_username, fw.owner_name, fw.organization_name, fw.organization_display_name, fw.organization_icon, fw.organization_description, fw.template_name, fw.template_display_name, fw.template_icon, fw.template_description, fw.template_version_id, fw.template_version_name, fw.latest_build_completed_at, fw.latest_build_canceled_at, fw.latest_build_error, fw.latest_build_transition, fw.latest_build_status, fw.latest_build_has_ai_task
+		fw.id, fw.created_at, fw.updated_at, fw.owner_id, fw.organization_id, fw.template_id, fw.deleted, fw.name, fw.autostart_schedule, fw.ttl, fw.last_used_at, fw.dormant_at, fw.deleting_at, fw.automatic_updates, fw.favorite, fw.next_start_at, fw.ephemeral, fw.owner_avatar_url, fw.owner_username, fw.owner_name, fw.organization_name, fw.organization_display_name, fw.organization_icon, fw.organization_description, fw.template_name, fw.template_display_name, fw.template_icon, fw.template_description, fw.template_version_id, fw.template_version_name, fw.latest_build_completed_at, fw.latest_build_canceled_at, fw.latest_build_error, fw.latest_build_transition, fw.latest_build_status, fw.latest_build_has_ai_task
 	FROM
 		filtered_workspaces fw
 	ORDER BY
 		-- To ensure that 'favorite' workspaces show up first in the list only for their owner.
-		CASE WHEN owner_id = $20 AND favorite THEN 0 ELSE 1 END ASC,
+		CASE WHEN owner_id = $22 AND favorite THEN 0 ELSE 1 END ASC,
 		(latest_build_completed_at IS NOT NULL AND
 			latest_build_canceled_at IS NULL AND
 			latest_build_error IS NULL AND
@@ -19611,14 +27161,14 @@ WHERE
 		LOWER(name) ASC
 	LIMIT
 		CASE
-			WHEN $22 :: integer > 0 THEN
-				$22
+			WHEN $24 :: integer > 0 THEN
+				$24
 		END
 	OFFSET
-		$21
+		$23
 ), filtered_workspaces_order_with_summary AS (
 	SELECT
-		fwo.id, fwo.created_at, fwo.updated_at, fwo.owner_id, fwo.organization_id, fwo.template_id, fwo.deleted, fwo.name, fwo.autostart_schedule, fwo.ttl, fwo.last_used_at, fwo.dormant_at, fwo.deleting_at, fwo.automatic_updates, fwo.favorite, fwo.next_start_at, fwo.owner_avatar_url, fwo.owner_username, fwo.owner_name, fwo.organization_name, fwo.organization_display_name, fwo.organization_icon, fwo.organization_description, fwo.template_name, fwo.template_display_name, fwo.template_icon, fwo.template_description, fwo.template_version_id, fwo.template_version_name, fwo.latest_build_completed_at, fwo.latest_build_canceled_at, fwo.latest_build_error, fwo.latest_build_transition, fwo.latest_build_status, fwo.latest_build_has_ai_task
+		fwo.id, fwo.created_at, fwo.updated_at, fwo.owner_id, fwo.organization_id, fwo.template_id, fwo.deleted, fwo.name, fwo.autostart_schedule, fwo.ttl, fwo.last_used_at, fwo.dormant_at, fwo.deleting_at, fwo.automatic_updates, fwo.favorite, fwo.next_start_at, fwo.ephemeral, fwo.owner_avatar_url, fwo.owner_username, fwo.owner_name, fwo.organization_name, fwo.organization_display_name, fwo.organization_icon, fwo.organization_description, fwo.template_name, fwo.template_display_name, fwo.template_icon, fwo.template_description, fwo.template_version_id, fwo.template_version_name, fwo.latest_build_completed_at, fwo.latest_build_canceled_at, fwo.latest_build_error, fwo.latest_build_transition, fwo.latest_build_status, fwo.latest_build_has_ai_task
 	FROM
 		filtered_workspaces_order fwo
 	-- Return a technical summary row with total count of workspaces.
@@ -19641,6 +27191,7 @@ WHERE
 		'never'::automatic_updates, -- automatic_updates
 		false, -- favorite
 		'0001-01-01 00:00:00+00'::timestamptz, -- next_start_at
+		false, -- ephemeral
 		'', -- owner_avatar_url
 		'', -- owner_username
 		'', -- owner_name
@@ -19662,7 +27213,7 @@ WHERE
 		'unknown'::provisioner_job_status, -- latest_build_status
 		false -- latest_build_has_ai_task
 	WHERE
-		$23 :: boolean = true
+		$25 :: boolean = true
 ), total_count AS (
 	SELECT
 		count(*) AS count
@@ -19670,7 +27221,7 @@ WHERE
 		filtered_workspaces
 )
 SELECT
-	fwos.id, fwos.created_at, fwos.updated_at, fwos.owner_id, fwos.organization_id, fwos.template_id, fwos.deleted, fwos.name, fwos.autostart_schedule, fwos.ttl, fwos.last_used_at, fwos.dormant_at, fwos.deleting_at, fwos.automatic_updates, fwos.favorite, fwos.next_start_at, fwos.owner_avatar_url, fwos.owner_username, fwos.owner_name, fwos.organization_name, fwos.organization_display_name, fwos.organization_icon, fwos.organization_description, fwos.template_name, fwos.template_display_name, fwos.template_icon, fwos.template_description, fwos.template_version_id, fwos.template_version_name, fwos.latest_build_completed_at, fwos.latest_build_canceled_at, fwos.latest_build_error, fwos.latest_build_transition, fwos.latest_build_status, fwos.latest_build_has_ai_task,
+	fwos.id, fwos.created_at, fwos.updated_at, fwos.owner_id, fwos.organization_id, fwos.template_id, fwos.deleted, fwos.name, fwos.autostart_schedule, fwos.ttl, fwos.last_used_at, fwos.dormant_at, fwos.deleting_at, fwos.automatic_updates, fwos.favorite, fwos.next_start_at, fwos.ephemeral, fwos.owner_avatar_url, fwos.owner_username, fwos.owner_name, fwos.organization_name, fwos.organization_display_name, fwos.organization_icon, fwos.organization_description, fwos.template_name, fwos.template_display_name, fwos.template_icon, fwos.template_description, fwos.template_version_id, fwos.template_version_name, fwos.latest_build_completed_at, fwos.latest_build_canceled_at, fwos.latest_build_error, fwos.latest_build_transition, fwos.latest_build_status, fwos.latest_build_has_ai_task,
 	tc.count
 FROM
 	filtered_workspaces_order_with_summary fwos
@@ -19698,6 +27249,8 @@ type GetWorkspacesParams struct {
 	LastUsedAfter                         time.Time    `db:"last_used_after" json:"last_used_after"`
 	UsingActive                           sql.NullBool `db:"using_active" json:"using_active"`
 	HasAITask                             sql.NullBool `db:"has_ai_task" json:"has_ai_task"`
+	LabelKeys                             []string     `db:"label_keys" json:"label_keys"`
+	LabelValues                           []string     `db:"label_values" json:"label_values"`
 	RequesterID                           uuid.UUID    `db:"requester_id" json:"requester_id"`
 	Offset                                int32        `db:"offset_" json:"offset_"`
 	Limit                                 int32        `db:"limit_" json:"limit_"`
@@ -19721,6 +27274,7 @@ type GetWorkspacesRow struct {
 	AutomaticUpdates        AutomaticUpdates     `db:"automatic_updates" json:"automatic_updates"`
 	Favorite                bool                 `db:"favorite" json:"favorite"`
 	NextStartAt             sql.NullTime         `db:"next_start_at" json:"next_start_at"`
+	Ephemeral               bool                 `db:"ephemeral" json:"ephemeral"`
 	OwnerAvatarUrl          string               `db:"owner_avatar_url" json:"owner_avatar_url"`
 	OwnerUsername           string               `db:"owner_username" json:"owner_username"`
 	OwnerName               string               `db:"owner_name" json:"owner_name"`
@@ -19767,6 +27321,8 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 		arg.LastUsedAfter,
 		arg.UsingActive,
 		arg.HasAITask,
+		pq.Array(arg.LabelKeys),
+		pq.Array(arg.LabelValues),
 		arg.RequesterID,
 		arg.Offset,
 		arg.Limit,
@@ -19796,6 +27352,7 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 			&i.AutomaticUpdates,
 			&i.Favorite,
 			&i.NextStartAt,
+			&i.Ephemeral,
 			&i.OwnerAvatarUrl,
 			&i.OwnerUsername,
 			&i.OwnerName,
@@ -19910,7 +27467,7 @@ func (q *sqlQuerier) GetWorkspacesAndAgentsByOwnerID(ctx context.Context, ownerI
 }
 
 const getWorkspacesByTemplateID = `-- name: GetWorkspacesByTemplateID :many
-SELECT id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at FROM workspaces WHERE template_id = $1 AND deleted = false
+SELECT id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, ephemeral FROM workspaces WHERE template_id = $1 AND deleted = false
 `
 
 func (q *sqlQuerier) GetWorkspacesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspaceTable, error) {
@@ -19939,6 +27496,7 @@ func (q *sqlQuerier) GetWorkspacesByTemplateID(ctx context.Context, templateID u
 			&i.AutomaticUpdates,
 			&i.Favorite,
 			&i.NextStartAt,
+			&i.Ephemeral,
 		); err != nil {
 			return nil, err
 		}
@@ -20123,10 +27681,11 @@ INSERT INTO
 		ttl,
 		last_used_at,
 		automatic_updates,
-		next_start_at
+		next_start_at,
+		ephemeral
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, ephemeral
 `
 
 type InsertWorkspaceParams struct {
@@ -20142,6 +27701,7 @@ type InsertWorkspaceParams struct {
 	LastUsedAt        time.Time        `db:"last_used_at" json:"last_used_at"`
 	AutomaticUpdates  AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
 	NextStartAt       sql.NullTime     `db:"next_start_at" json:"next_start_at"`
+	Ephemeral         bool             `db:"ephemeral" json:"ephemeral"`
 }
 
 func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (WorkspaceTable, error) {
@@ -20158,6 +27718,7 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		arg.LastUsedAt,
 		arg.AutomaticUpdates,
 		arg.NextStartAt,
+		arg.Ephemeral,
 	)
 	var i WorkspaceTable
 	err := row.Scan(
@@ -20177,6 +27738,7 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.Ephemeral,
 	)
 	return i, err
 }
@@ -20216,7 +27778,7 @@ SET
 WHERE
 	id = $1
 	AND deleted = false
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, ephemeral
 `
 
 type UpdateWorkspaceParams struct {
@@ -20244,6 +27806,7 @@ func (q *sqlQuerier) UpdateWorkspace(ctx context.Context, arg UpdateWorkspacePar
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.Ephemeral,
 	)
 	return i, err
 }
@@ -20332,7 +27895,7 @@ WHERE
     workspaces.id = $1
     AND templates.id = workspaces.template_id
 RETURNING
-    workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at
+    workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.ephemeral
 `
 
 type UpdateWorkspaceDormantDeletingAtParams struct {
@@ -20360,6 +27923,7 @@ func (q *sqlQuerier) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg U
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.Ephemeral,
 	)
 	return i, err
 }
@@ -20434,7 +27998,7 @@ WHERE
     template_id = $3
 AND
     dormant_at IS NOT NULL
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, ephemeral
 `
 
 type UpdateWorkspacesDormantDeletingAtByTemplateIDParams struct {
@@ -20469,6 +28033,7 @@ func (q *sqlQuerier) UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.C
 			&i.AutomaticUpdates,
 			&i.Favorite,
 			&i.NextStartAt,
+			&i.Ephemeral,
 		); err != nil {
 			return nil, err
 		}
@@ -20483,6 +28048,44 @@ func (q *sqlQuerier) UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.C
 	return items, nil
 }
 
+const updateWorkspacesOrganizationByTemplateID = `-- name: UpdateWorkspacesOrganizationByTemplateID :exec
+WITH moved AS (
+	UPDATE
+		workspaces
+	SET
+		organization_id = $1,
+		updated_at = $2
+	WHERE
+		template_id = $3
+	RETURNING
+		id
+)
+UPDATE
+	provisioner_jobs
+SET
+	organization_id = $1,
+	updated_at = $2
+FROM
+	workspace_builds
+WHERE
+	workspace_builds.job_id = provisioner_jobs.id
+	AND workspace_builds.workspace_id IN (SELECT id FROM moved)
+`
+
+type UpdateWorkspacesOrganizationByTemplateIDParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+}
+
+// Moves the workspaces of a template, and the provisioner jobs of their
+// builds, to another organization. Deleted workspaces are moved as well so
+// that they stay in the organization of their template.
+func (q *sqlQuerier) UpdateWorkspacesOrganizationByTemplateID(ctx context.Context, arg UpdateWorkspacesOrganizationByTemplateIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspacesOrganizationByTemplateID, arg.OrganizationID, arg.UpdatedAt, arg.TemplateID)
+	return err
+}
+
 const updateWorkspacesTTLByTemplateID = `-- name: UpdateWorkspacesTTLByTemplateID :exec
 UPDATE
 		workspaces
@@ -20502,6 +28105,87 @@ func (q *sqlQuerier) UpdateWorkspacesTTLByTemplateID(ctx context.Context, arg Up
 	return err
 }
 
+const deleteWorkspaceSchedulePause = `-- name: DeleteWorkspaceSchedulePause :exec
+DELETE FROM
+	workspace_schedule_pauses
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceSchedulePause(ctx context.Context, workspaceID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceSchedulePause, workspaceID)
+	return err
+}
+
+const getWorkspaceSchedulePauseByWorkspaceID = `-- name: GetWorkspaceSchedulePauseByWorkspaceID :one
+SELECT
+	workspace_id, paused_until, created_at, created_by
+FROM
+	workspace_schedule_pauses
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceSchedulePauseByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceSchedulePause, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceSchedulePauseByWorkspaceID, workspaceID)
+	var i WorkspaceSchedulePause
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.PausedUntil,
+		&i.CreatedAt,
+		&i.CreatedBy,
+	)
+	return i, err
+}
+
+const upsertWorkspaceSchedulePause = `-- name: UpsertWorkspaceSchedulePause :one
+INSERT INTO
+	workspace_schedule_pauses (
+		workspace_id,
+		paused_until,
+		created_at,
+		created_by
+	)
+VALUES (
+	$1,
+	$2,
+	$3,
+	$4
+)
+ON CONFLICT (
+	workspace_id
+)
+DO UPDATE SET
+	paused_until = $2,
+	created_at = $3,
+	created_by = $4
+RETURNING workspace_id, paused_until, created_at, created_by
+`
+
+type UpsertWorkspaceSchedulePauseParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	PausedUntil time.Time `db:"paused_until" json:"paused_until"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	CreatedBy   uuid.UUID `db:"created_by" json:"created_by"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceSchedulePause(ctx context.Context, arg UpsertWorkspaceSchedulePauseParams) (WorkspaceSchedulePause, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceSchedulePause,
+		arg.WorkspaceID,
+		arg.PausedUntil,
+		arg.CreatedAt,
+		arg.CreatedBy,
+	)
+	var i WorkspaceSchedulePause
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.PausedUntil,
+		&i.CreatedAt,
+		&i.CreatedBy,
+	)
+	return i, err
+}
+
 const getWorkspaceAgentScriptsByAgentIDs = `-- name: GetWorkspaceAgentScriptsByAgentIDs :many
 SELECT workspace_agent_id, log_source_id, log_path, created_at, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, display_name, id FROM workspace_agent_scripts WHERE workspace_agent_id = ANY($1 :: uuid [ ])
 `
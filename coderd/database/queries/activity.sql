@@ -0,0 +1,135 @@
+-- name: GetActivityEvents :many
+-- GetActivityEvents returns audit events, workspace builds and workspace agent
+-- lifecycle transitions as a single feed, newest first. When @newer_than_id is
+-- set, only the events after that event are returned, oldest first, so a
+-- stream can resume from the last event it saw.
+WITH events AS (
+	SELECT
+		audit_logs.id,
+		audit_logs.time AS created_at,
+		'audit'::text AS type,
+		audit_logs.organization_id,
+		audit_logs.user_id,
+		CASE audit_logs.resource_type
+			WHEN 'workspace' THEN audit_logs.resource_id
+			WHEN 'workspace_build' THEN (audit_logs.additional_fields ->> 'workspace_id')::uuid
+		END AS workspace_id,
+		audit_logs.resource_type::text AS resource_type,
+		audit_logs.resource_id,
+		audit_logs.resource_target AS resource_name,
+		audit_logs.action::text AS action,
+		audit_logs.status_code::text AS status
+	FROM
+		audit_logs
+	UNION ALL
+	SELECT
+		workspace_builds.id,
+		workspace_builds.created_at,
+		'build'::text AS type,
+		workspaces.organization_id,
+		workspace_builds.initiator_id AS user_id,
+		workspace_builds.workspace_id,
+		'workspace_build'::text AS resource_type,
+		workspace_builds.id AS resource_id,
+		workspaces.name AS resource_name,
+		workspace_builds.transition::text AS action,
+		provisioner_jobs.job_status::text AS status
+	FROM
+		workspace_builds
+	JOIN
+		workspaces ON workspaces.id = workspace_builds.workspace_id
+	JOIN
+		provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+	UNION ALL
+	SELECT
+		workspace_agent_lifecycle_events.id,
+		workspace_agent_lifecycle_events.created_at,
+		'lifecycle'::text AS type,
+		workspaces.organization_id,
+		workspaces.owner_id AS user_id,
+		workspaces.id AS workspace_id,
+		'workspace_agent'::text AS resource_type,
+		workspace_agents.id AS resource_id,
+		workspace_agents.name AS resource_name,
+		workspace_agent_lifecycle_events.lifecycle_state::text AS action,
+		''::text AS status
+	FROM
+		workspace_agent_lifecycle_events
+	JOIN
+		workspace_agents ON workspace_agents.id = workspace_agent_lifecycle_events.workspace_agent_id
+	JOIN
+		workspace_resources ON workspace_resources.id = workspace_agents.resource_id
+	JOIN
+		workspace_builds ON workspace_builds.job_id = workspace_resources.job_id
+	JOIN
+		workspaces ON workspaces.id = workspace_builds.workspace_id
+)
+SELECT
+	events.id::uuid AS id,
+	events.created_at::timestamptz AS created_at,
+	events.type::text AS type,
+	events.organization_id::uuid AS organization_id,
+	events.user_id::uuid AS user_id,
+	events.workspace_id,
+	events.resource_type::text AS resource_type,
+	events.resource_id::uuid AS resource_id,
+	events.resource_name::text AS resource_name,
+	events.action::text AS action,
+	events.status::text AS status
+FROM
+	events
+WHERE
+	CASE
+		WHEN cardinality(@types :: text[]) > 0 THEN
+			events.type = ANY(@types :: text[])
+		ELSE true
+	END
+	AND CASE
+		WHEN @organization_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			events.organization_id = @organization_id
+		ELSE true
+	END
+	AND CASE
+		WHEN @workspace_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			events.workspace_id = @workspace_id
+		ELSE true
+	END
+	AND CASE
+		WHEN @user_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			events.user_id = @user_id
+		ELSE true
+	END
+	AND CASE
+		-- The pagination cursor is the last ID of the previous page. The feed
+		-- is ordered by time, so select all events before the cursor.
+		WHEN @after_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			(events.created_at, events.id) < (
+				SELECT
+					cursor.created_at, cursor.id
+				FROM
+					events AS cursor
+				WHERE
+					cursor.id = @after_id
+			)
+		ELSE true
+	END
+	AND CASE
+		WHEN @newer_than_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			(events.created_at, events.id) > (
+				SELECT
+					cursor.created_at, cursor.id
+				FROM
+					events AS cursor
+				WHERE
+					cursor.id = @newer_than_id
+			)
+		ELSE true
+	END
+ORDER BY
+	CASE WHEN @newer_than_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN events.created_at END ASC,
+	CASE WHEN @newer_than_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN events.id END ASC,
+	events.created_at DESC,
+	events.id DESC
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
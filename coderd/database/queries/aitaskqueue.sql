@@ -0,0 +1,90 @@
+-- name: InsertAITaskQueueEntry :one
+INSERT INTO ai_task_queue (
+	id,
+	owner_id,
+	organization_id,
+	template_version_id,
+	template_version_preset_id,
+	name,
+	prompt,
+	created_at,
+	updated_at
+)
+VALUES
+	(@id, @owner_id, @organization_id, @template_version_id, @template_version_preset_id, @name, @prompt, @created_at, @updated_at)
+RETURNING *;
+
+-- name: GetAITaskQueueEntryByID :one
+SELECT
+	*
+FROM
+	ai_task_queue
+WHERE
+	id = @id;
+
+-- name: GetAITaskQueueEntriesByOwnerID :many
+-- Returns the queued and failed tasks of the owner, oldest first.
+SELECT
+	*
+FROM
+	ai_task_queue
+WHERE
+	owner_id = @owner_id
+ORDER BY
+	created_at, id;
+
+-- name: GetAITaskQueueOwnerIDs :many
+-- Returns the owners that have queued tasks.
+SELECT DISTINCT
+	owner_id
+FROM
+	ai_task_queue
+WHERE
+	status = 'queued'::ai_task_queue_status;
+
+-- name: UpdateAITaskQueueEntryFailed :exec
+UPDATE
+	ai_task_queue
+SET
+	status = 'failed'::ai_task_queue_status,
+	error = @error,
+	updated_at = @updated_at
+WHERE
+	id = @id;
+
+-- name: DeleteAITaskQueueEntryByID :exec
+DELETE FROM
+	ai_task_queue
+WHERE
+	id = @id;
+
+-- name: CountRunningAITasksByOwnerID :one
+-- Counts the workspaces of the owner whose latest build starts an AI task and
+-- has not failed or been canceled.
+SELECT
+	COUNT(*)
+FROM
+	workspaces w
+JOIN LATERAL (
+	SELECT
+		tv.has_ai_task,
+		wb.transition,
+		pj.job_status
+	FROM
+		workspace_builds wb
+	JOIN
+		template_versions tv ON tv.id = wb.template_version_id
+	JOIN
+		provisioner_jobs pj ON pj.id = wb.job_id
+	WHERE
+		wb.workspace_id = w.id
+	ORDER BY
+		wb.build_number DESC
+	LIMIT 1
+) latest_build ON TRUE
+WHERE
+	w.owner_id = @owner_id
+	AND NOT w.deleted
+	AND latest_build.has_ai_task
+	AND latest_build.transition = 'start'::workspace_transition
+	AND latest_build.job_status IN ('pending', 'running', 'succeeded');
@@ -30,6 +30,22 @@ SELECT * FROM api_keys WHERE login_type = $1;
 -- name: GetAPIKeysByUserID :many
 SELECT * FROM api_keys WHERE login_type = $1 AND user_id = $2;
 
+-- name: GetAPIKeySessionsByUserID :many
+-- Sessions are the unexpired keys a user created by logging in. API tokens,
+-- workspace owner tokens and the keys of workspace apps are excluded.
+SELECT
+	*
+FROM
+	api_keys
+WHERE
+	user_id = @user_id AND
+	login_type NOT IN ('token'::login_type, 'oauth2_provider_app'::login_type) AND
+	scope = 'all'::api_key_scope AND
+	token_name = '' AND
+	expires_at > @now
+ORDER BY
+	GREATEST(last_used, created_at) DESC;
+
 -- name: InsertAPIKey :one
 INSERT INTO
 	api_keys (
@@ -44,7 +60,10 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		user_agent,
+		device_fingerprint,
+		workspace_id
 	)
 VALUES
 	(@id,
@@ -53,7 +72,7 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE @lifetime_seconds::bigint
 	 END
-	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scope, @token_name) RETURNING *;
+	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scope, @token_name, @user_agent, @device_fingerprint, @workspace_id) RETURNING *;
 
 -- name: UpdateAPIKeyByID :exec
 UPDATE
@@ -83,3 +102,48 @@ DELETE FROM
 	api_keys
 WHERE
 	user_id = $1;
+
+-- name: DeleteAPIKeysByUserIDLoginType :exec
+-- Deletes the sessions a user created by logging in with the given login
+-- type. API tokens are kept.
+DELETE FROM
+	api_keys
+WHERE
+	user_id = @user_id AND
+	login_type = @login_type;
+
+-- name: DeleteExcessAPIKeySessionsByUserID :exec
+-- Deletes the least recently used sessions of a user, keeping at most
+-- @max_sessions. Sessions are matched like in GetAPIKeySessionsByUserID.
+DELETE FROM
+	api_keys
+WHERE
+	id IN (
+		SELECT
+			id
+		FROM
+			api_keys
+		WHERE
+			user_id = @user_id AND
+			login_type NOT IN ('token'::login_type, 'oauth2_provider_app'::login_type) AND
+			scope = 'all'::api_key_scope AND
+			token_name = '' AND
+			expires_at > @now
+		ORDER BY
+			GREATEST(last_used, created_at) DESC
+		OFFSET
+			@max_sessions
+	);
+
+-- name: DeleteStaleAPIKeySessionsByUserID :exec
+-- Deletes the sessions of a user that were created before @created_before,
+-- including the app keys derived from them, to make the user authenticate
+-- again.
+DELETE FROM
+	api_keys
+WHERE
+	user_id = @user_id AND
+	login_type NOT IN ('token'::login_type, 'oauth2_provider_app'::login_type) AND
+	scope IN ('all'::api_key_scope, 'application_connect'::api_key_scope) AND
+	token_name = '' AND
+	created_at < @created_before;
@@ -103,6 +103,11 @@ WHERE
 		WHEN @request_id::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN audit_logs.request_id = @request_id
 		ELSE true
 	END
+	-- Filter by full-text search over the target, diff and additional fields
+	AND CASE
+		WHEN @search::text != '' THEN to_tsvector('simple', audit_logs.resource_target || ' ' || audit_logs.diff::text || ' ' || audit_logs.additional_fields::text) @@ websearch_to_tsquery('simple', @search)
+		ELSE true
+	END
 	-- Authorize Filter clause will be injected below in GetAuthorizedAuditLogsOffset
 	-- @authorize_filter
 ORDER BY "time" DESC
@@ -234,6 +239,11 @@ WHERE
 		WHEN @request_id::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN audit_logs.request_id = @request_id
 		ELSE true
 	END
+	-- Filter by full-text search over the target, diff and additional fields
+	AND CASE
+		WHEN @search::text != '' THEN to_tsvector('simple', audit_logs.resource_target || ' ' || audit_logs.diff::text || ' ' || audit_logs.additional_fields::text) @@ websearch_to_tsquery('simple', @search)
+		ELSE true
+	END
 	-- Authorize Filter clause will be injected below in CountAuthorizedAuditLogs
 	-- @authorize_filter
 ;
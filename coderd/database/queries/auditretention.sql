@@ -0,0 +1,88 @@
+-- name: GetOrganizationAuditSettings :one
+SELECT
+	*
+FROM
+	organization_audit_settings
+WHERE
+	organization_id = @organization_id;
+
+-- name: UpsertOrganizationAuditSettings :one
+INSERT INTO
+	organization_audit_settings (organization_id, retention_days, updated_at)
+VALUES
+	(@organization_id, @retention_days, @updated_at)
+ON CONFLICT (organization_id) DO UPDATE SET
+	retention_days = @retention_days,
+	updated_at = @updated_at
+RETURNING *;
+
+-- name: GetAuditLogLegalHoldsByOrganizationID :many
+SELECT
+	*
+FROM
+	audit_log_legal_holds
+WHERE
+	organization_id = @organization_id
+ORDER BY
+	created_at ASC;
+
+-- name: GetAuditLogLegalHoldByID :one
+SELECT
+	*
+FROM
+	audit_log_legal_holds
+WHERE
+	id = @id;
+
+-- name: InsertAuditLogLegalHold :one
+INSERT INTO
+	audit_log_legal_holds (id, organization_id, user_id, workspace_id, reason, created_by, created_at)
+VALUES
+	(@id, @organization_id, @user_id, @workspace_id, @reason, @created_by, @created_at)
+RETURNING *;
+
+-- name: DeleteAuditLogLegalHoldByID :exec
+DELETE FROM
+	audit_log_legal_holds
+WHERE
+	id = @id;
+
+-- name: DeleteOldAuditLogs :execrows
+-- Deletes up to limit_count audit logs that are older than the retention
+-- period of their organization. Audit logs of users and workspaces under a
+-- legal hold are kept. Organizations without a retention period keep their
+-- audit logs forever.
+DELETE FROM
+	audit_logs
+WHERE
+	id IN (
+		SELECT
+			audit_logs.id
+		FROM
+			audit_logs
+			JOIN organization_audit_settings ON organization_audit_settings.organization_id = audit_logs.organization_id
+		WHERE
+			organization_audit_settings.retention_days > 0
+			AND audit_logs.time < @now::timestamptz - make_interval(days => organization_audit_settings.retention_days)
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					audit_log_legal_holds
+				WHERE
+					audit_log_legal_holds.organization_id = audit_logs.organization_id
+					AND (
+						audit_log_legal_holds.user_id = audit_logs.user_id
+						OR (audit_logs.resource_type = 'user' AND audit_log_legal_holds.user_id = audit_logs.resource_id)
+						OR (audit_logs.resource_type = 'workspace' AND audit_log_legal_holds.workspace_id = audit_logs.resource_id)
+						OR (
+							audit_logs.resource_type = 'workspace_build'
+							AND audit_log_legal_holds.workspace_id = (
+								SELECT workspace_id FROM workspace_builds WHERE workspace_builds.id = audit_logs.resource_id
+							)
+						)
+					)
+			)
+		LIMIT
+			@limit_count::int
+	);
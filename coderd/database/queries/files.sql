@@ -40,9 +40,44 @@ LIMIT
 
 -- name: InsertFile :one
 INSERT INTO
-	files (id, hash, created_at, created_by, mimetype, "data")
+	files (id, hash, created_at, created_by, mimetype, "data", external)
 VALUES
-	($1, $2, $3, $4, $5, $6) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: CountExternalFilesByHash :one
+-- Counts the files that share the object storage contents of a hash.
+SELECT
+	COUNT(*)
+FROM
+	files
+WHERE
+	hash = $1
+	AND external;
+
+-- name: DeleteUnreferencedExternalFiles :many
+-- Deletes files in object storage that were created before the given time
+-- and are not used by a provisioner job or as cached modules. Returns the
+-- hashes of the deleted files, so that their contents can be deleted once no
+-- other file shares them.
+WITH deleted AS (
+	DELETE FROM
+		files
+	WHERE
+		external
+		AND created_at < @created_before
+		AND NOT EXISTS (
+			SELECT 1 FROM provisioner_jobs WHERE provisioner_jobs.file_id = files.id
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM template_version_terraform_values WHERE template_version_terraform_values.cached_module_files = files.id
+		)
+	RETURNING
+		hash
+)
+SELECT DISTINCT
+	hash
+FROM
+	deleted;
 
 -- name: GetFileTemplates :many
 -- Get all templates that use a file.
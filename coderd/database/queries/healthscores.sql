@@ -0,0 +1,23 @@
+-- name: InsertHealthScore :exec
+INSERT INTO
+	health_scores (replica_id, created_at, score, database_score, database_latency_ms, derp_score, provisioner_daemons_score, replicas_score, websocket_score)
+VALUES
+	(@replica_id, @created_at, @score, @database_score, @database_latency_ms, @derp_score, @provisioner_daemons_score, @replicas_score, @websocket_score);
+
+-- name: GetHealthScores :many
+-- Returns the scores of all replicas recorded in [@start_time, @end_time).
+SELECT
+	*
+FROM
+	health_scores
+WHERE
+	created_at >= @start_time::timestamptz
+	AND created_at < @end_time::timestamptz
+ORDER BY
+	created_at ASC, replica_id ASC;
+
+-- name: DeleteOldHealthScores :exec
+DELETE FROM
+	health_scores
+WHERE
+	created_at < @before_time::timestamptz;
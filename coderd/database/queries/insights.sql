@@ -451,6 +451,156 @@ ON
 GROUP BY
 	ts.from_, ts.to_;
 
+-- name: GetTemplateBuildInsightsByInterval :many
+-- GetTemplateBuildInsightsByInterval returns the number of succeeded, failed
+-- and canceled workspace builds of a template for all intervals between start
+-- and end time. Builds are counted in the interval their job completed in. If
+-- end time is a partial interval, it will be included in the results and that
+-- interval will be shorter than a full one.
+WITH
+	ts AS (
+		SELECT
+			d::timestamptz AS from_,
+			LEAST(
+				(d::timestamptz + (@interval_days::int || ' day')::interval)::timestamptz,
+				@end_time::timestamptz
+			)::timestamptz AS to_
+		FROM
+			generate_series(
+				@start_time::timestamptz,
+				-- Subtract 1 μs to avoid creating an extra series.
+				(@end_time::timestamptz) - '1 microsecond'::interval,
+				(@interval_days::int || ' day')::interval
+			) AS d
+	),
+	builds AS (
+		SELECT
+			pj.job_status,
+			pj.completed_at
+		FROM
+			workspace_builds AS wb
+		JOIN
+			template_versions AS tv
+		ON
+			tv.id = wb.template_version_id
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			tv.template_id = @template_id
+			AND pj.completed_at >= @start_time::timestamptz
+			AND pj.completed_at < @end_time::timestamptz
+	)
+
+SELECT
+	ts.from_ AS start_time,
+	ts.to_ AS end_time,
+	COUNT(builds.completed_at) FILTER (WHERE builds.job_status = 'succeeded') AS succeeded_builds,
+	COUNT(builds.completed_at) FILTER (WHERE builds.job_status = 'failed') AS failed_builds,
+	COUNT(builds.completed_at) FILTER (WHERE builds.job_status = 'canceled') AS canceled_builds
+FROM
+	ts
+LEFT JOIN
+	builds
+ON
+	builds.completed_at >= ts.from_
+	AND builds.completed_at < ts.to_
+GROUP BY
+	ts.from_, ts.to_
+ORDER BY
+	ts.from_;
+
+-- name: GetTemplateBuildStageDurations :many
+-- GetTemplateBuildStageDurations returns the median and 95th percentile
+-- duration in seconds of the successful workspace builds of a template that
+-- completed between start and end time. The "total" stage covers the whole
+-- provisioner job, the other stages are the provisioner job timing stages
+-- (init, plan, graph and apply).
+WITH
+	builds AS (
+		SELECT
+			pj.id AS job_id,
+			pj.started_at,
+			pj.completed_at
+		FROM
+			workspace_builds AS wb
+		JOIN
+			template_versions AS tv
+		ON
+			tv.id = wb.template_version_id
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			tv.template_id = @template_id
+			AND pj.job_status = 'succeeded'
+			AND pj.started_at IS NOT NULL
+			AND pj.completed_at >= @start_time::timestamptz
+			AND pj.completed_at < @end_time::timestamptz
+	),
+	durations AS (
+		SELECT
+			'total'::text AS stage,
+			EXTRACT(EPOCH FROM (builds.completed_at - builds.started_at))::float AS seconds
+		FROM
+			builds
+		UNION ALL
+		SELECT
+			pjt.stage::text AS stage,
+			EXTRACT(EPOCH FROM (MAX(pjt.ended_at) - MIN(pjt.started_at)))::float AS seconds
+		FROM
+			provisioner_job_timings AS pjt
+		JOIN
+			builds
+		ON
+			builds.job_id = pjt.job_id
+		GROUP BY
+			pjt.job_id, pjt.stage
+	)
+
+SELECT
+	durations.stage::text AS stage,
+	COUNT(*) AS builds,
+	PERCENTILE_DISC(0.5) WITHIN GROUP (ORDER BY durations.seconds)::float AS p50_seconds,
+	PERCENTILE_DISC(0.95) WITHIN GROUP (ORDER BY durations.seconds)::float AS p95_seconds
+FROM
+	durations
+GROUP BY
+	durations.stage;
+
+-- name: GetTemplateBuildFailures :many
+-- GetTemplateBuildFailures returns the distinct error messages of the failed
+-- workspace builds of a template that completed between start and end time,
+-- along with the number of builds that failed with each message and when the
+-- last one completed.
+SELECT
+	COALESCE(pj.error, '')::text AS error,
+	COUNT(*) AS builds,
+	MAX(pj.completed_at)::timestamptz AS last_failed_at
+FROM
+	workspace_builds AS wb
+JOIN
+	template_versions AS tv
+ON
+	tv.id = wb.template_version_id
+JOIN
+	provisioner_jobs AS pj
+ON
+	pj.id = wb.job_id
+WHERE
+	tv.template_id = @template_id
+	AND pj.job_status = 'failed'
+	AND pj.completed_at >= @start_time::timestamptz
+	AND pj.completed_at < @end_time::timestamptz
+GROUP BY
+	COALESCE(pj.error, '')
+ORDER BY
+	builds DESC, last_failed_at DESC
+LIMIT
+	@limit_count::int;
+
 -- name: GetTemplateUsageStats :many
 SELECT
 	*
@@ -888,3 +1038,230 @@ FROM ranked_status_change_per_user_per_date rscpupd
 CROSS JOIN statuses
 GROUP BY rscpupd.date, statuses.new_status
 ORDER BY rscpupd.date;
+
+-- name: GetUserWorkspaceUsage :many
+-- GetUserWorkspaceUsage returns, for every user who owned a running workspace
+-- between start and end time, the number of their workspaces that ran, the
+-- hours those workspaces ran for and the quota credits they accrued. A
+-- workspace runs from the completion of a successful start build until its
+-- next build, and accrues the daily cost of the start build prorated over that
+-- time. The hours of the quota dimensions are the costs of the start build in
+-- each dimension multiplied by the hours the workspaces ran, e.g. CPU hours.
+-- Keep the CTEs in sync with GetGroupWorkspaceUsage.
+WITH
+	builds AS (
+		SELECT
+			wb.id,
+			wb.workspace_id,
+			wb.transition,
+			wb.daily_cost,
+			pj.job_status,
+			pj.completed_at,
+			LEAD(wb.created_at) OVER (PARTITION BY wb.workspace_id ORDER BY wb.build_number) AS next_build_at
+		FROM
+			workspace_builds AS wb
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			-- Builds created after the end time only end runs that last
+			-- until the end time anyway.
+			wb.created_at < @end_time::timestamptz
+	),
+	runs AS (
+		SELECT
+			builds.id AS workspace_build_id,
+			builds.workspace_id,
+			builds.daily_cost,
+			EXTRACT(EPOCH FROM (
+				LEAST(COALESCE(builds.next_build_at, NOW()), @end_time::timestamptz)
+				- GREATEST(builds.completed_at, @start_time::timestamptz)
+			))::float AS seconds
+		FROM
+			builds
+		WHERE
+			builds.transition = 'start'
+			AND builds.job_status = 'succeeded'
+			AND builds.completed_at < @end_time::timestamptz
+			AND (builds.next_build_at IS NULL OR builds.next_build_at > @start_time::timestamptz)
+	),
+	dimension_hours AS (
+		SELECT
+			workspaces.owner_id,
+			costs.dimension,
+			(SUM(costs.cost * runs.seconds) / 3600)::float AS hours
+		FROM
+			runs
+		JOIN
+			workspaces
+		ON
+			workspaces.id = runs.workspace_id
+		JOIN
+			workspace_build_quota_costs AS costs
+		ON
+			costs.workspace_build_id = runs.workspace_build_id
+		WHERE
+			runs.seconds > 0
+		GROUP BY
+			workspaces.owner_id, costs.dimension
+	)
+
+SELECT
+	users.id AS user_id,
+	users.username,
+	users.name,
+	users.email,
+	users.last_seen_at,
+	COUNT(DISTINCT runs.workspace_id) AS workspaces,
+	(SUM(runs.seconds) / 3600)::float AS runtime_hours,
+	(SUM(runs.daily_cost * runs.seconds) / 86400)::float AS cost,
+	MAX(workspaces.last_used_at)::timestamptz AS last_workspace_used_at,
+	ARRAY(
+		SELECT dimension_hours.dimension FROM dimension_hours
+		WHERE dimension_hours.owner_id = users.id ORDER BY dimension_hours.dimension
+	)::text[] AS dimensions,
+	ARRAY(
+		SELECT dimension_hours.hours FROM dimension_hours
+		WHERE dimension_hours.owner_id = users.id ORDER BY dimension_hours.dimension
+	)::float[] AS dimension_hours
+FROM
+	runs
+JOIN
+	workspaces
+ON
+	workspaces.id = runs.workspace_id
+JOIN
+	users
+ON
+	users.id = workspaces.owner_id
+WHERE
+	runs.seconds > 0
+	-- The system user responsible for prebuilds.
+	AND workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+GROUP BY
+	users.id
+ORDER BY
+	cost DESC, runtime_hours DESC, users.username;
+
+-- name: GetGroupWorkspaceUsage :many
+-- GetGroupWorkspaceUsage returns, for every group with members who owned a
+-- running workspace in the organization of the group between start and end
+-- time, the number of those members and of their workspaces that ran, the
+-- hours those workspaces ran for and the quota credits they accrued. Users are
+-- counted in every group they are a member of. Keep the CTEs in sync with
+-- GetUserWorkspaceUsage.
+WITH
+	builds AS (
+		SELECT
+			wb.id,
+			wb.workspace_id,
+			wb.transition,
+			wb.daily_cost,
+			pj.job_status,
+			pj.completed_at,
+			LEAD(wb.created_at) OVER (PARTITION BY wb.workspace_id ORDER BY wb.build_number) AS next_build_at
+		FROM
+			workspace_builds AS wb
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			wb.created_at < @end_time::timestamptz
+	),
+	runs AS (
+		SELECT
+			builds.id AS workspace_build_id,
+			builds.workspace_id,
+			builds.daily_cost,
+			EXTRACT(EPOCH FROM (
+				LEAST(COALESCE(builds.next_build_at, NOW()), @end_time::timestamptz)
+				- GREATEST(builds.completed_at, @start_time::timestamptz)
+			))::float AS seconds
+		FROM
+			builds
+		WHERE
+			builds.transition = 'start'
+			AND builds.job_status = 'succeeded'
+			AND builds.completed_at < @end_time::timestamptz
+			AND (builds.next_build_at IS NULL OR builds.next_build_at > @start_time::timestamptz)
+	),
+	workspace_runs AS (
+		SELECT
+			workspaces.owner_id,
+			workspaces.organization_id,
+			workspaces.last_used_at,
+			runs.workspace_build_id,
+			runs.workspace_id,
+			runs.daily_cost,
+			runs.seconds
+		FROM
+			runs
+		JOIN
+			workspaces
+		ON
+			workspaces.id = runs.workspace_id
+		WHERE
+			runs.seconds > 0
+			-- The system user responsible for prebuilds.
+			AND workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+	),
+	dimension_hours AS (
+		SELECT
+			members.group_id,
+			costs.dimension,
+			(SUM(costs.cost * workspace_runs.seconds) / 3600)::float AS hours
+		FROM
+			workspace_runs
+		JOIN
+			group_members_expanded AS members
+		ON
+			members.user_id = workspace_runs.owner_id
+			AND members.organization_id = workspace_runs.organization_id
+		JOIN
+			workspace_build_quota_costs AS costs
+		ON
+			costs.workspace_build_id = workspace_runs.workspace_build_id
+		GROUP BY
+			members.group_id, costs.dimension
+	)
+
+SELECT
+	groups.id AS group_id,
+	groups.name AS group_name,
+	groups.display_name AS group_display_name,
+	groups.organization_id,
+	organizations.name AS organization_name,
+	COUNT(DISTINCT workspace_runs.owner_id) AS users,
+	COUNT(DISTINCT workspace_runs.workspace_id) AS workspaces,
+	(SUM(workspace_runs.seconds) / 3600)::float AS runtime_hours,
+	(SUM(workspace_runs.daily_cost * workspace_runs.seconds) / 86400)::float AS cost,
+	MAX(workspace_runs.last_used_at)::timestamptz AS last_workspace_used_at,
+	ARRAY(
+		SELECT dimension_hours.dimension FROM dimension_hours
+		WHERE dimension_hours.group_id = groups.id ORDER BY dimension_hours.dimension
+	)::text[] AS dimensions,
+	ARRAY(
+		SELECT dimension_hours.hours FROM dimension_hours
+		WHERE dimension_hours.group_id = groups.id ORDER BY dimension_hours.dimension
+	)::float[] AS dimension_hours
+FROM
+	groups
+JOIN
+	organizations
+ON
+	organizations.id = groups.organization_id
+JOIN
+	group_members_expanded
+ON
+	group_members_expanded.group_id = groups.id
+JOIN
+	workspace_runs
+ON
+	workspace_runs.owner_id = group_members_expanded.user_id
+	AND workspace_runs.organization_id = groups.organization_id
+GROUP BY
+	groups.id, organizations.name
+ORDER BY
+	cost DESC, runtime_hours DESC, groups.name;
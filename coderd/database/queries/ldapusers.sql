@@ -0,0 +1,16 @@
+-- name: GetLDAPUsers :many
+SELECT
+	*
+FROM
+	ldap_users;
+
+-- name: UpsertLDAPUser :one
+INSERT INTO
+	ldap_users (user_id, external_id, dn, created_at, updated_at)
+VALUES
+	(@user_id, @external_id, @dn, @updated_at, @updated_at)
+ON CONFLICT (user_id) DO UPDATE SET
+	external_id = @external_id,
+	dn = @dn,
+	updated_at = @updated_at
+RETURNING *;
@@ -35,3 +35,43 @@ DELETE
 FROM licenses
 WHERE id = $1
 RETURNING id;
+
+-- name: GetLicenseSeatReservations :many
+-- Returns the seat reservations with the number of active members of each
+-- group, which occupy the reserved seats first.
+SELECT
+	license_seat_reservations.*,
+	groups.name AS group_name,
+	groups.organization_id,
+	(
+		SELECT
+			COUNT(*)
+		FROM
+			group_members_expanded
+		WHERE
+			group_members_expanded.group_id = license_seat_reservations.group_id AND
+			group_members_expanded.user_status = 'active'::user_status AND
+			NOT group_members_expanded.user_is_system
+	)::bigint AS active_members
+FROM
+	license_seat_reservations
+INNER JOIN groups ON
+	groups.id = license_seat_reservations.group_id
+ORDER BY
+	groups.name;
+
+-- name: UpsertLicenseSeatReservation :one
+INSERT INTO
+	license_seat_reservations (group_id, seats, created_at, updated_at)
+VALUES
+	(@group_id, @seats, @created_at, @updated_at)
+ON CONFLICT (group_id) DO UPDATE SET
+	seats = EXCLUDED.seats,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteLicenseSeatReservation :exec
+DELETE FROM
+	license_seat_reservations
+WHERE
+	group_id = @group_id;
@@ -0,0 +1,71 @@
+-- name: GetNetworkConnectionQuality :one
+SELECT
+	*
+FROM
+	network_connection_quality
+WHERE
+	client_type = @client_type
+	AND node_id_self = @node_id_self
+	AND node_id_remote = @node_id_remote
+FOR UPDATE;
+
+-- name: UpsertNetworkConnectionQuality :exec
+INSERT INTO
+	network_connection_quality (client_type, node_id_self, node_id_remote, connection_type, latency_ms, handshake_retries, updated_at)
+VALUES
+	(@client_type, @node_id_self, @node_id_remote, @connection_type, @latency_ms, @handshake_retries, @updated_at)
+ON CONFLICT (client_type, node_id_self, node_id_remote) DO UPDATE SET
+	connection_type = EXCLUDED.connection_type,
+	latency_ms = EXCLUDED.latency_ms,
+	handshake_retries = EXCLUDED.handshake_retries,
+	updated_at = EXCLUDED.updated_at;
+
+-- name: DeleteNetworkConnectionQuality :exec
+DELETE FROM
+	network_connection_quality
+WHERE
+	client_type = @client_type
+	AND node_id_self = @node_id_self
+	AND node_id_remote = @node_id_remote;
+
+-- name: DeleteOldNetworkConnectionQuality :exec
+-- Connections send a disconnect event when they close cleanly, this removes
+-- the ones that vanished without one.
+DELETE FROM
+	network_connection_quality
+WHERE
+	updated_at < @before_time::timestamptz;
+
+-- name: GetNetworkConnectionQualitySummary :many
+-- Groups the connections updated since @updated_after by client and connection
+-- type, with the median latency of each group.
+SELECT
+	client_type,
+	connection_type,
+	COUNT(*)::bigint AS connections,
+	COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY latency_ms), 0)::double precision AS latency_median_ms
+FROM
+	network_connection_quality
+WHERE
+	updated_at >= @updated_after::timestamptz
+GROUP BY
+	client_type, connection_type
+ORDER BY
+	client_type, connection_type;
+
+-- name: IncrementNetworkConnectionQualityCounters :exec
+INSERT INTO
+	network_connection_quality_counters (client_type, relay_fallbacks, handshake_retries)
+VALUES
+	(@client_type, @relay_fallbacks, @handshake_retries)
+ON CONFLICT (client_type) DO UPDATE SET
+	relay_fallbacks = network_connection_quality_counters.relay_fallbacks + EXCLUDED.relay_fallbacks,
+	handshake_retries = network_connection_quality_counters.handshake_retries + EXCLUDED.handshake_retries;
+
+-- name: GetNetworkConnectionQualityCounters :many
+SELECT
+	*
+FROM
+	network_connection_quality_counters
+ORDER BY
+	client_type;
@@ -7,14 +7,25 @@ SELECT nt.name                                                    AS notificatio
        u.id                                                       AS user_id,
        u.email                                                    AS user_email,
        COALESCE(NULLIF(u.name, ''), NULLIF(u.username, ''))::text AS user_name,
-       u.username                                                 AS user_username
+       u.username                                                 AS user_username,
+       nt.priority                                                AS priority,
+       EXISTS (SELECT 1
+               FROM user_configs uc
+               WHERE uc.user_id = u.id
+                 AND uc.key = 'notification_digest'
+                 AND uc.value = 'true')::bool                     AS digest_enabled,
+       ARRAY(SELECT ncp.method
+             FROM notification_category_preferences ncp
+             WHERE ncp.user_id = u.id
+               AND ncp.category = nt.category
+               AND ncp.disabled)::notification_method[]           AS disabled_methods
 FROM notification_templates nt,
      users u
 WHERE nt.id = @notification_template_id
   AND u.id = @user_id;
 
 -- name: EnqueueNotificationMessage :exec
-INSERT INTO notification_messages (id, notification_template_id, user_id, method, payload, targets, created_by, created_at)
+INSERT INTO notification_messages (id, notification_template_id, user_id, method, payload, targets, created_by, created_at, deliver_after, digest)
 VALUES (@id,
         @notification_template_id,
         @user_id,
@@ -22,7 +33,9 @@ VALUES (@id,
         @payload::jsonb,
         @targets,
         @created_by,
-        @created_at);
+        @created_at,
+        @deliver_after,
+        @digest);
 
 -- Acquires the lease for a given count of notification messages, to enable concurrent dequeuing and subsequent sending.
 -- Only rows that aren't already leased (or ones which are leased but have exceeded their lease period) are returned.
@@ -71,6 +84,10 @@ WITH acquired AS (
                                  ELSE true
                                  END
                              )
+                           -- if set, do not dispatch until the message is no longer held back (e.g. during quiet hours)
+                           AND (nm.deliver_after IS NULL OR nm.deliver_after <= NOW())
+                           -- messages batched into a digest are never dispatched on their own
+                           AND NOT nm.digest
                          ORDER BY nm.created_at ASC
                                   -- Ensure that multiple concurrent readers cannot retrieve the same rows
                              FOR UPDATE OF nm
@@ -128,6 +145,21 @@ FROM (SELECT UNNEST(@ids::uuid[])             AS id,
          AS new_values
 WHERE notification_messages.id = new_values.id;
 
+-- name: GetDueNotificationDigestMessages :many
+-- Fetches the pending messages which are due to be batched into their user's
+-- notification digest, ordered by user.
+SELECT nm.id,
+       nm.user_id,
+       nm.payload,
+       nm.created_at,
+       nt.title_template
+FROM notification_messages nm
+         JOIN notification_templates nt ON nm.notification_template_id = nt.id
+WHERE nm.digest
+  AND nm.status = 'pending'::notification_message_status
+  AND nm.deliver_after <= @now::timestamptz
+ORDER BY nm.user_id, nm.created_at ASC;
+
 -- Delete all notification messages which have not been updated for over a week.
 -- name: DeleteOldNotificationMessages :exec
 DELETE
@@ -158,6 +190,23 @@ ON CONFLICT (user_id, notification_template_id) DO UPDATE
     SET disabled   = EXCLUDED.disabled,
         updated_at = CURRENT_TIMESTAMP;
 
+-- name: GetUserNotificationCategoryPreferences :many
+SELECT *
+FROM notification_category_preferences
+WHERE user_id = @user_id::uuid
+ORDER BY category, method;
+
+-- name: UpdateUserNotificationCategoryPreferences :execrows
+INSERT
+INTO notification_category_preferences (user_id, category, method, disabled)
+SELECT @user_id::uuid, new_values.category, new_values.method, new_values.disabled
+FROM (SELECT UNNEST(@categories::notification_category[]) AS category,
+             UNNEST(@methods::notification_method[])      AS method,
+             UNNEST(@disableds::bool[])                   AS disabled) AS new_values
+ON CONFLICT (user_id, category, method) DO UPDATE
+    SET disabled   = EXCLUDED.disabled,
+        updated_at = CURRENT_TIMESTAMP;
+
 -- name: UpdateNotificationTemplateMethodByID :one
 UPDATE notification_templates
 SET method = sqlc.narg('method')::notification_method
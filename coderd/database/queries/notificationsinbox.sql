@@ -16,6 +16,7 @@ SELECT * FROM inbox_notifications WHERE
 -- param user_id: The user ID
 -- param templates: The template IDs to filter by - the template_id = ANY(@templates::UUID[]) condition checks if the template_id is in the @templates array
 -- param targets: The target IDs to filter by - the targets @> COALESCE(@targets, ARRAY[]::UUID[]) condition checks if the targets array (from the DB) contains all the elements in the @targets array
+-- param categories: The categories to filter by - only notifications whose template belongs to one of the categories are returned
 -- param read_status: The read status to filter by - can be any of 'ALL', 'UNREAD', 'READ'
 -- param created_at_opt: The created_at timestamp to filter by. This parameter is usd for pagination - it fetches notifications created before the specified timestamp if it is not the zero value
 -- param limit_opt: The limit of notifications to fetch. If the limit is not specified, it defaults to 25
@@ -23,6 +24,7 @@ SELECT * FROM inbox_notifications WHERE
 	user_id = @user_id AND
 	(@templates::UUID[] IS NULL OR template_id = ANY(@templates::UUID[])) AND
 	(@targets::UUID[] IS NULL OR targets @> @targets::UUID[]) AND
+	(@categories::notification_category[] IS NULL OR template_id IN (SELECT id FROM notification_templates WHERE category = ANY(@categories::notification_category[]))) AND
 	(@read_status::inbox_notification_read_status = 'all' OR (@read_status::inbox_notification_read_status = 'unread' AND read_at IS NULL) OR (@read_status::inbox_notification_read_status = 'read' AND read_at IS NOT NULL)) AND
 	(@created_at_opt::TIMESTAMPTZ = '0001-01-01 00:00:00Z' OR created_at < @created_at_opt::TIMESTAMPTZ)
 	ORDER BY created_at DESC
@@ -31,6 +33,24 @@ SELECT * FROM inbox_notifications WHERE
 -- name: GetInboxNotificationByID :one
 SELECT * FROM inbox_notifications WHERE id = $1;
 
+-- name: CountUnreadInboxNotificationsByCategory :many
+-- Counts the unread inbox notifications of a user by the category of their
+-- template. Notifications whose template has no category are counted with a
+-- NULL category.
+SELECT
+	notification_templates.category,
+	COUNT(*) AS unread_count
+FROM
+	inbox_notifications
+	LEFT JOIN notification_templates ON notification_templates.id = inbox_notifications.template_id
+WHERE
+	inbox_notifications.user_id = @user_id AND
+	inbox_notifications.read_at IS NULL
+GROUP BY
+	notification_templates.category
+ORDER BY
+	notification_templates.category;
+
 -- name: CountUnreadInboxNotificationsByUserID :one
 SELECT COUNT(*) FROM inbox_notifications WHERE user_id = $1 AND read_at IS NULL;
 
@@ -65,3 +85,18 @@ SET
 	read_at = $1
 WHERE
 	user_id = $2 and read_at IS NULL;
+
+-- name: UpdateInboxNotificationsReadStatus :execrows
+-- Marks the inbox notifications of a user as read, or unread if read_at is
+-- NULL. Notifications can be selected by ID, by the category of their
+-- template, or both. Notifications that already have the requested read
+-- status are left untouched so they keep their original read_at.
+UPDATE
+	inbox_notifications
+SET
+	read_at = sqlc.narg('read_at')::timestamptz
+WHERE
+	user_id = @user_id AND
+	(@ids::UUID[] IS NULL OR id = ANY(@ids::UUID[])) AND
+	(@categories::notification_category[] IS NULL OR template_id IN (SELECT id FROM notification_templates WHERE category = ANY(@categories::notification_category[]))) AND
+	(read_at IS NULL) = (sqlc.narg('read_at')::timestamptz IS NOT NULL);
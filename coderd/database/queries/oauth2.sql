@@ -160,6 +160,48 @@ DELETE FROM oauth2_provider_app_codes WHERE id = $1;
 -- name: DeleteOAuth2ProviderAppCodesByAppAndUserID :exec
 DELETE FROM oauth2_provider_app_codes WHERE app_id = $1 AND user_id = $2;
 
+-- name: GetOAuth2ProviderAppDeviceCodeByPrefix :one
+SELECT * FROM oauth2_provider_app_device_codes WHERE device_code_prefix = $1;
+
+-- name: GetOAuth2ProviderAppDeviceCodeByUserCode :one
+SELECT * FROM oauth2_provider_app_device_codes WHERE user_code = $1;
+
+-- name: InsertOAuth2ProviderAppDeviceCode :one
+INSERT INTO oauth2_provider_app_device_codes (
+    id,
+    created_at,
+    expires_at,
+    device_code_prefix,
+    device_code_hash,
+    user_code,
+    app_id,
+    resource_uri
+) VALUES(
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+) RETURNING *;
+
+-- name: UpdateOAuth2ProviderAppDeviceCodeUserID :one
+-- Approves a pending device code. Returns no rows if the code was already
+-- approved.
+UPDATE oauth2_provider_app_device_codes SET
+    user_id = $2
+WHERE id = $1 AND user_id IS NULL RETURNING *;
+
+-- name: UpdateOAuth2ProviderAppDeviceCodeLastPolledAt :exec
+UPDATE oauth2_provider_app_device_codes SET
+    last_polled_at = $2
+WHERE id = $1;
+
+-- name: DeleteOAuth2ProviderAppDeviceCodeByID :exec
+DELETE FROM oauth2_provider_app_device_codes WHERE id = $1;
+
 -- name: InsertOAuth2ProviderAppToken :one
 INSERT INTO oauth2_provider_app_tokens (
     id,
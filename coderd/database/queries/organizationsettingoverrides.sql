@@ -0,0 +1,31 @@
+-- name: GetOrganizationSettingOverrides :one
+SELECT
+	*
+FROM
+	organization_setting_overrides
+WHERE
+	organization_id = @organization_id;
+
+-- name: UpsertOrganizationSettingOverrides :one
+INSERT INTO
+	organization_setting_overrides (
+		organization_id,
+		default_ttl,
+		default_quiet_hours_schedule,
+		allowed_external_auth_providers,
+		disabled_notification_template_ids,
+		provisioner_max_jobs,
+		provisioner_reserved_jobs,
+		updated_at
+	)
+VALUES
+	(@organization_id, @default_ttl, @default_quiet_hours_schedule, @allowed_external_auth_providers, @disabled_notification_template_ids, @provisioner_max_jobs, @provisioner_reserved_jobs, @updated_at)
+ON CONFLICT (organization_id) DO UPDATE SET
+	default_ttl = @default_ttl,
+	default_quiet_hours_schedule = @default_quiet_hours_schedule,
+	allowed_external_auth_providers = @allowed_external_auth_providers,
+	disabled_notification_template_ids = @disabled_notification_template_ids,
+	provisioner_max_jobs = @provisioner_max_jobs,
+	provisioner_reserved_jobs = @provisioner_reserved_jobs,
+	updated_at = @updated_at
+RETURNING *;
@@ -2,6 +2,7 @@
 UPDATE workspaces w
 SET owner_id   = @new_user_id::uuid,
 	name       = @new_name::text,
+	ephemeral  = @ephemeral::boolean,
 	updated_at = NOW()
 WHERE w.id IN (
 	SELECT p.id
@@ -19,3 +19,55 @@ SELECT
 	unnest(@level :: log_level [ ]) AS LEVEL,
 	unnest(@stage :: VARCHAR(128) [ ]) AS stage,
 	unnest(@output :: VARCHAR(1024) [ ]) AS output RETURNING *;
+
+-- name: SearchProvisionerJobLogs :many
+-- Full-text search over the logs of the provisioner jobs in an organization,
+-- ordered from newest to oldest.
+SELECT
+	sqlc.embed(provisioner_job_logs),
+	provisioner_jobs.type AS job_type,
+	COALESCE(workspace_builds.id, '00000000-0000-0000-0000-000000000000'::uuid) AS workspace_build_id,
+	COALESCE(workspace_builds.workspace_id, '00000000-0000-0000-0000-000000000000'::uuid) AS workspace_id,
+	COALESCE(template_versions.id, '00000000-0000-0000-0000-000000000000'::uuid) AS template_version_id,
+	COALESCE(template_versions.template_id, '00000000-0000-0000-0000-000000000000'::uuid) AS template_id
+FROM
+	provisioner_job_logs
+	JOIN provisioner_jobs ON provisioner_jobs.id = provisioner_job_logs.job_id
+	LEFT JOIN workspace_builds ON workspace_builds.job_id = provisioner_jobs.id
+	-- Template version imports belong to their template version, and
+	-- workspace builds to the template version that they build.
+	LEFT JOIN template_versions ON template_versions.job_id = provisioner_jobs.id
+		OR template_versions.id = workspace_builds.template_version_id
+WHERE
+	provisioner_jobs.organization_id = @organization_id
+	AND to_tsvector('simple', provisioner_job_logs.output::text) @@ websearch_to_tsquery('simple', @search::text)
+	-- Filter by job type
+	AND CASE
+		WHEN @job_type::text != '' THEN provisioner_jobs.type = @job_type::provisioner_job_type
+		ELSE true
+	END
+	-- Filter by template_id
+	AND CASE
+		WHEN @template_id::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN template_versions.template_id = @template_id
+		ELSE true
+	END
+	-- Filter by workspace_id
+	AND CASE
+		WHEN @workspace_id::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN workspace_builds.workspace_id = @workspace_id
+		ELSE true
+	END
+	-- Filter by date_from
+	AND CASE
+		WHEN @date_from::timestamp with time zone != '0001-01-01 00:00:00Z' THEN provisioner_job_logs.created_at >= @date_from
+		ELSE true
+	END
+	-- Filter by date_to
+	AND CASE
+		WHEN @date_to::timestamp with time zone != '0001-01-01 00:00:00Z' THEN provisioner_job_logs.created_at <= @date_to
+		ELSE true
+	END
+ORDER BY
+	provisioner_job_logs.created_at DESC,
+	provisioner_job_logs.id DESC
+LIMIT
+	COALESCE(NULLIF(@limit_opt::int, 0), 100);
@@ -4,6 +4,18 @@
 -- SKIP LOCKED is used to jump over locked rows. This prevents
 -- multiple provisioners from acquiring the same jobs. See:
 -- https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
+--
+-- Workspace builds are skipped, and so stay queued, while the workspace
+-- owner or template already has the maximum number of running builds.
+--
+-- No job is acquired while the organization has the maximum number of
+-- running jobs, or while the rest of the shared capacity of the deployment
+-- is reserved for other organizations.
+--
+-- The running jobs are counted without locking them, so two concurrent calls
+-- could both admit a job into the last free slot. Callers serialize admission
+-- by holding an advisory lock for the transaction, see
+-- provisionerdserver.Acquirer.
 -- name: AcquireProvisionerJob :one
 UPDATE
 	provisioner_jobs
@@ -25,6 +37,91 @@ WHERE
 			-- elsewhere, we use the tagset type, but here we use jsonb for backward compatibility
 			-- they are aliases and the code that calls this query already relies on a different type
 			AND provisioner_tagset_contains(@provisioner_tags :: jsonb, potential_job.tags :: jsonb)
+			-- Queue workspace builds while their owner or template is at the
+			-- concurrent build limit. A limit of 0 disables it.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					workspace_builds AS pending_build
+					JOIN workspaces AS pending_workspace ON pending_workspace.id = pending_build.workspace_id
+				WHERE
+					pending_build.job_id = potential_job.id
+					AND (
+						(
+							@max_builds_per_user :: integer > 0
+							AND (
+								SELECT
+									COUNT(*)
+								FROM
+									workspace_builds AS running_build
+									JOIN provisioner_jobs AS running_job ON running_job.id = running_build.job_id
+									JOIN workspaces AS running_workspace ON running_workspace.id = running_build.workspace_id
+								WHERE
+									running_job.started_at IS NOT NULL
+									AND running_job.completed_at IS NULL
+									AND running_workspace.owner_id = pending_workspace.owner_id
+							) >= @max_builds_per_user :: integer
+						)
+						OR (
+							@max_builds_per_template :: integer > 0
+							AND (
+								SELECT
+									COUNT(*)
+								FROM
+									workspace_builds AS running_build
+									JOIN provisioner_jobs AS running_job ON running_job.id = running_build.job_id
+									JOIN workspaces AS running_workspace ON running_workspace.id = running_build.workspace_id
+								WHERE
+									running_job.started_at IS NOT NULL
+									AND running_job.completed_at IS NULL
+									AND running_workspace.template_id = pending_workspace.template_id
+							) >= @max_builds_per_template :: integer
+						)
+					)
+			)
+			-- Queue the jobs of organizations at their concurrent job limit.
+			-- When the shared capacity is limited, also queue them while the
+			-- organization is past its reservation and the capacity left is
+			-- reserved by other organizations. Limits of 0 disable them.
+			AND (
+				SELECT
+					(usage.max_jobs = 0 OR usage.running_jobs < usage.max_jobs)
+					AND (
+						@max_concurrent_jobs :: integer = 0
+						OR usage.running_jobs < usage.reserved_jobs
+						OR usage.total_running_jobs + usage.reserved_by_others < @max_concurrent_jobs :: integer
+					)
+				FROM
+					(
+						SELECT
+							COALESCE(SUM(org_usage.running_jobs) FILTER (WHERE org_usage.id = @organization_id), 0) AS running_jobs,
+							COALESCE(MAX(org_usage.max_jobs) FILTER (WHERE org_usage.id = @organization_id), 0) AS max_jobs,
+							COALESCE(MAX(org_usage.reserved_jobs) FILTER (WHERE org_usage.id = @organization_id), 0) AS reserved_jobs,
+							COALESCE(SUM(org_usage.running_jobs), 0) AS total_running_jobs,
+							COALESCE(SUM(GREATEST(org_usage.reserved_jobs - org_usage.running_jobs, 0)) FILTER (WHERE org_usage.id != @organization_id), 0) AS reserved_by_others
+						FROM
+							(
+								SELECT
+									organizations.id,
+									COALESCE(organization_setting_overrides.provisioner_max_jobs, @max_jobs_per_organization :: integer) AS max_jobs,
+									COALESCE(organization_setting_overrides.provisioner_reserved_jobs, @reserved_jobs_per_organization :: integer) AS reserved_jobs,
+									(
+										SELECT
+											COUNT(*)
+										FROM
+											provisioner_jobs AS running_job
+										WHERE
+											running_job.organization_id = organizations.id
+											AND running_job.started_at IS NOT NULL
+											AND running_job.completed_at IS NULL
+									) AS running_jobs
+								FROM
+									organizations
+									LEFT JOIN organization_setting_overrides ON organization_setting_overrides.organization_id = organizations.id
+							) AS org_usage
+					) AS usage
+			)
 		ORDER BY
 			potential_job.created_at
 		FOR UPDATE
@@ -33,6 +130,36 @@ WHERE
 			1
 	) RETURNING *;
 
+-- name: GetPendingProvisionerJobsCount :one
+-- Returns the number of provisioner jobs which are waiting to be acquired by a
+-- provisioner daemon.
+SELECT
+	COUNT(*)
+FROM
+	provisioner_jobs
+WHERE
+	started_at IS NULL
+	AND canceled_at IS NULL
+	AND completed_at IS NULL;
+
+-- name: GetProvisionerJobCountsByOrganization :many
+-- Returns the number of provisioner jobs of every organization which are
+-- waiting to be acquired by a provisioner daemon, and which are running.
+SELECT
+	organizations.id AS organization_id,
+	organizations.name AS organization_name,
+	COUNT(provisioner_jobs.id) FILTER (WHERE provisioner_jobs.started_at IS NULL) AS pending_jobs,
+	COUNT(provisioner_jobs.id) FILTER (WHERE provisioner_jobs.started_at IS NOT NULL) AS running_jobs
+FROM
+	organizations
+	LEFT JOIN provisioner_jobs ON provisioner_jobs.organization_id = organizations.id
+		AND provisioner_jobs.completed_at IS NULL
+		AND (provisioner_jobs.started_at IS NOT NULL OR provisioner_jobs.canceled_at IS NULL)
+GROUP BY
+	organizations.id
+ORDER BY
+	organizations.name;
+
 -- name: GetProvisionerJobByID :one
 SELECT
 	*
@@ -329,3 +456,51 @@ RETURNING *;
 SELECT * FROM provisioner_job_timings
 WHERE job_id = $1
 ORDER BY started_at ASC;
+
+-- name: GetOrphanedProvisionerJobs :many
+-- Returns running jobs whose provisioner daemon has not sent a heartbeat since
+-- @stale_since, or no longer exists, along with the number of times each job
+-- has already been requeued.
+SELECT
+	sqlc.embed(provisioner_jobs),
+	(
+		SELECT
+			COUNT(*)
+		FROM
+			provisioner_job_requeues
+		WHERE
+			provisioner_job_requeues.job_id = provisioner_jobs.id
+	) AS requeue_count
+FROM
+	provisioner_jobs
+	LEFT JOIN provisioner_daemons ON provisioner_daemons.id = provisioner_jobs.worker_id
+WHERE
+	provisioner_jobs.updated_at < @stale_since
+	AND provisioner_jobs.started_at < @stale_since
+	AND provisioner_jobs.completed_at IS NULL
+	AND (
+		provisioner_daemons.id IS NULL
+		OR provisioner_daemons.last_seen_at IS NULL
+		OR provisioner_daemons.last_seen_at < @stale_since
+	)
+-- To avoid repeatedly attempting to handle the same jobs, we randomly order and limit to @max_jobs.
+ORDER BY random()
+LIMIT @max_jobs;
+
+-- name: RequeueProvisionerJob :exec
+-- Returns a running job to the queue so that another provisioner daemon can
+-- acquire it.
+UPDATE
+	provisioner_jobs
+SET
+	started_at = NULL,
+	worker_id = NULL,
+	updated_at = @updated_at
+WHERE
+	id = @id;
+
+-- name: InsertProvisionerJobRequeue :exec
+INSERT INTO
+	provisioner_job_requeues (job_id, worker_id, created_at)
+VALUES
+	($1, $2, $3);
@@ -19,7 +19,9 @@ WITH latest_builds AS (
 SELECT
 	DISTINCT ON
 	(wb.workspace_id) wb.workspace_id,
-	wb.daily_cost
+	wb.daily_cost,
+	wb.transition,
+	workspaces.ephemeral
 FROM
 	workspace_builds wb
  -- This INNER JOIN prevents a seq scan of the workspace_builds table.
@@ -41,4 +43,150 @@ SELECT
 	coalesce(SUM(daily_cost), 0)::BIGINT
 FROM
 	latest_builds
+WHERE
+	-- Ephemeral workspaces only consume quota while they are running.
+	NOT latest_builds.ephemeral OR latest_builds.transition = 'start'
+;
+
+-- name: GetQuotaDimensionAllowancesForUser :many
+-- Returns every quota dimension that has an allowance in the organization,
+-- with the sum of the allowances of the groups the user is a member of.
+-- Dimensions without any allowance in the organization are not enforced.
+SELECT
+	group_quota_allowances.dimension,
+	coalesce(SUM(group_quota_allowances.allowance) FILTER (WHERE members.user_id IS NOT NULL), 0)::BIGINT AS allowance
+FROM
+	group_quota_allowances
+INNER JOIN groups ON
+	group_quota_allowances.group_id = groups.id
+LEFT JOIN group_members_expanded AS members ON
+	members.group_id = group_quota_allowances.group_id AND
+	members.user_id = @user_id
+WHERE
+	groups.organization_id = @organization_id
+GROUP BY
+	group_quota_allowances.dimension
+ORDER BY
+	group_quota_allowances.dimension
+;
+
+-- name: GetQuotaDimensionsConsumedForUser :many
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(wb.workspace_id) wb.workspace_id,
+	wb.id,
+	wb.transition,
+	workspaces.ephemeral
+FROM
+	workspace_builds wb
+INNER JOIN
+	workspaces on wb.workspace_id = workspaces.id
+WHERE
+	NOT workspaces.deleted AND
+	workspaces.owner_id = @owner_id AND
+	workspaces.organization_id = @organization_id
+ORDER BY
+	wb.workspace_id,
+	wb.build_number DESC
+)
+SELECT
+	costs.dimension,
+	SUM(costs.cost)::BIGINT AS consumed
+FROM
+	latest_builds
+INNER JOIN workspace_build_quota_costs AS costs ON
+	costs.workspace_build_id = latest_builds.id
+WHERE
+	NOT latest_builds.ephemeral OR latest_builds.transition = 'start'
+GROUP BY
+	costs.dimension
+ORDER BY
+	costs.dimension
+;
+
+-- name: GetQuotaDimensionsConsumedForGroup :many
+-- Returns the quota consumed by the workspaces of all members of the group
+-- in the organization of the group.
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(wb.workspace_id) wb.workspace_id,
+	wb.id,
+	wb.transition,
+	workspaces.ephemeral
+FROM
+	workspace_builds wb
+INNER JOIN
+	workspaces on wb.workspace_id = workspaces.id
+INNER JOIN group_members_expanded AS members ON
+	members.user_id = workspaces.owner_id AND
+	members.organization_id = workspaces.organization_id
+WHERE
+	NOT workspaces.deleted AND
+	members.group_id = @group_id
+ORDER BY
+	wb.workspace_id,
+	wb.build_number DESC
+)
+SELECT
+	costs.dimension,
+	SUM(costs.cost)::BIGINT AS consumed
+FROM
+	latest_builds
+INNER JOIN workspace_build_quota_costs AS costs ON
+	costs.workspace_build_id = latest_builds.id
+WHERE
+	NOT latest_builds.ephemeral OR latest_builds.transition = 'start'
+GROUP BY
+	costs.dimension
+ORDER BY
+	costs.dimension
+;
+
+-- name: GetGroupQuotaAllowances :many
+SELECT
+	*
+FROM
+	group_quota_allowances
+WHERE
+	group_id = @group_id
+ORDER BY
+	dimension
+;
+
+-- name: DeleteGroupQuotaAllowances :exec
+DELETE FROM
+	group_quota_allowances
+WHERE
+	group_id = @group_id
+;
+
+-- name: InsertGroupQuotaAllowances :exec
+INSERT INTO
+	group_quota_allowances (group_id, dimension, allowance)
+SELECT
+	@group_id,
+	unnest(@dimensions :: TEXT[]),
+	unnest(@allowances :: BIGINT[])
+;
+
+-- name: GetWorkspaceBuildQuotaCosts :many
+SELECT
+	*
+FROM
+	workspace_build_quota_costs
+WHERE
+	workspace_build_id = @workspace_build_id
+ORDER BY
+	dimension
+;
+
+-- name: InsertWorkspaceBuildQuotaCosts :exec
+INSERT INTO
+	workspace_build_quota_costs (workspace_build_id, dimension, cost)
+SELECT
+	@workspace_build_id,
+	unnest(@dimensions :: TEXT[]),
+	unnest(@costs :: BIGINT[])
 ;
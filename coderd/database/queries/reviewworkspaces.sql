@@ -0,0 +1,49 @@
+-- name: GetReviewWorkspace :one
+SELECT
+	review_workspaces.*
+FROM
+	review_workspaces
+INNER JOIN
+	workspaces ON workspaces.id = review_workspaces.workspace_id
+WHERE
+	review_workspaces.organization_id = @organization_id
+	AND review_workspaces.repository = @repository
+	AND review_workspaces.branch = @branch
+	AND NOT workspaces.deleted;
+
+-- name: GetReviewWorkspacesByRepositoryAndBranch :many
+-- Returns the review workspaces for a branch in every organization. Used when
+-- a webhook reports that the branch has been deleted.
+SELECT
+	review_workspaces.*
+FROM
+	review_workspaces
+INNER JOIN
+	workspaces ON workspaces.id = review_workspaces.workspace_id
+WHERE
+	review_workspaces.repository = @repository
+	AND review_workspaces.branch = @branch
+	AND NOT workspaces.deleted;
+
+-- name: UpsertReviewWorkspace :one
+-- The branch may only be moved to another workspace once the workspace it
+-- currently points at has been deleted. No rows are returned otherwise.
+INSERT INTO
+	review_workspaces (
+		workspace_id,
+		organization_id,
+		repository,
+		branch,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $5)
+ON CONFLICT (organization_id, repository, branch) DO UPDATE
+SET
+	workspace_id = EXCLUDED.workspace_id,
+	updated_at = EXCLUDED.updated_at
+WHERE
+	review_workspaces.workspace_id = EXCLUDED.workspace_id
+	OR review_workspaces.workspace_id IN (SELECT id FROM workspaces WHERE deleted)
+RETURNING *;
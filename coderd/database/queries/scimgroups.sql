@@ -0,0 +1,55 @@
+-- name: GetSCIMGroupMembers :many
+-- Returns the users the identity provider assigned to each SCIM group in the
+-- organization.
+SELECT
+	scim_group_members.*
+FROM
+	scim_group_members
+INNER JOIN
+	groups ON groups.id = scim_group_members.group_id
+WHERE
+	groups.organization_id = @organization_id;
+
+-- name: GetSCIMNestedGroups :many
+-- Returns the groups the identity provider assigned as members of each SCIM
+-- group in the organization.
+SELECT
+	scim_nested_groups.*
+FROM
+	scim_nested_groups
+INNER JOIN
+	groups ON groups.id = scim_nested_groups.group_id
+WHERE
+	groups.organization_id = @organization_id;
+
+-- name: InsertSCIMGroupMembers :exec
+INSERT INTO
+	scim_group_members (group_id, user_id)
+SELECT
+	@group_id,
+	unnest(@user_ids :: uuid[])
+-- If there is a conflict, the user is already a member.
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteSCIMGroupMembers :exec
+DELETE FROM
+	scim_group_members
+WHERE
+	group_id = @group_id AND
+	user_id = ANY(@user_ids :: uuid[]);
+
+-- name: InsertSCIMNestedGroups :exec
+INSERT INTO
+	scim_nested_groups (group_id, member_group_id)
+SELECT
+	@group_id,
+	unnest(@member_group_ids :: uuid[])
+-- If there is a conflict, the group is already a member.
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteSCIMNestedGroups :exec
+DELETE FROM
+	scim_nested_groups
+WHERE
+	group_id = @group_id AND
+	member_group_id = ANY(@member_group_ids :: uuid[]);
@@ -78,6 +78,13 @@ SELECT value FROM site_configs WHERE key = 'coordinator_resume_token_signing_key
 INSERT INTO site_configs (key, value) VALUES ('coordinator_resume_token_signing_key', $1)
 ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'coordinator_resume_token_signing_key';
 
+-- name: GetInternalCA :one
+SELECT value FROM site_configs WHERE key = 'internal_ca';
+
+-- name: UpsertInternalCA :exec
+INSERT INTO site_configs (key, value) VALUES ('internal_ca', $1)
+ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'internal_ca';
+
 -- name: GetHealthSettings :one
 SELECT
 	COALESCE((SELECT value FROM site_configs WHERE key = 'health_settings'), '{}') :: text AS health_settings
@@ -87,6 +94,15 @@ SELECT
 INSERT INTO site_configs (key, value) VALUES ('health_settings', $1)
 ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'health_settings';
 
+-- name: GetExperimentFlags :one
+SELECT
+	COALESCE((SELECT value FROM site_configs WHERE key = 'experiment_flags'), '{}') :: text AS experiment_flags
+;
+
+-- name: UpsertExperimentFlags :exec
+INSERT INTO site_configs (key, value) VALUES ('experiment_flags', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'experiment_flags';
+
 -- name: GetNotificationsSettings :one
 SELECT
 	COALESCE((SELECT value FROM site_configs WHERE key = 'notifications_settings'), '{}') :: text AS notifications_settings
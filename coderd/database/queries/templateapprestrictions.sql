@@ -0,0 +1,49 @@
+-- name: GetTemplateAppRestrictions :many
+SELECT
+	*
+FROM
+	template_app_restrictions
+WHERE
+	template_id = @template_id
+ORDER BY
+	app_slug ASC;
+
+-- name: GetTemplateAppRestrictionsByTemplateIDs :many
+-- Used to hide and deny access to the restricted apps of workspaces of
+-- multiple templates at once.
+SELECT
+	*
+FROM
+	template_app_restrictions
+WHERE
+	template_id = ANY(@template_ids :: uuid[]);
+
+-- name: UpsertTemplateAppRestriction :one
+INSERT INTO template_app_restrictions (
+	template_id,
+	app_slug,
+	group_ids,
+	roles,
+	created_at,
+	updated_at
+)
+VALUES (
+	@template_id,
+	@app_slug,
+	@group_ids :: uuid[],
+	@roles :: text[],
+	@updated_at,
+	@updated_at
+)
+ON CONFLICT (template_id, app_slug) DO UPDATE SET
+	group_ids = EXCLUDED.group_ids,
+	roles = EXCLUDED.roles,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteTemplateAppRestriction :exec
+DELETE FROM
+	template_app_restrictions
+WHERE
+	template_id = @template_id
+	AND app_slug = @app_slug;
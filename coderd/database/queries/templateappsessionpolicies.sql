@@ -0,0 +1,36 @@
+-- name: GetTemplateAppSessionPolicies :many
+SELECT
+	*
+FROM
+	template_app_session_policies
+WHERE
+	template_id = @template_id
+ORDER BY
+	app_slug ASC;
+
+-- name: UpsertTemplateAppSessionPolicy :one
+INSERT INTO template_app_session_policies (
+	template_id,
+	app_slug,
+	max_session_duration,
+	created_at,
+	updated_at
+)
+VALUES (
+	@template_id,
+	@app_slug,
+	@max_session_duration,
+	@updated_at,
+	@updated_at
+)
+ON CONFLICT (template_id, app_slug) DO UPDATE SET
+	max_session_duration = EXCLUDED.max_session_duration,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteTemplateAppSessionPolicy :exec
+DELETE FROM
+	template_app_session_policies
+WHERE
+	template_id = @template_id
+	AND app_slug = @app_slug;
@@ -0,0 +1,65 @@
+-- name: InsertTemplateBundle :exec
+INSERT INTO template_bundles (
+	id,
+	organization_id,
+	name,
+	version,
+	hash,
+	manifest,
+	data,
+	created_by,
+	created_at
+)
+VALUES (
+	@id,
+	@organization_id,
+	@name,
+	@version,
+	@hash,
+	@manifest,
+	@data,
+	@created_by,
+	@created_at
+);
+
+-- name: GetTemplateBundlesByOrganizationID :many
+-- Omits the data of the bundles, which is only fetched when a bundle is pulled.
+SELECT
+	template_bundles.id,
+	template_bundles.organization_id,
+	template_bundles.name,
+	template_bundles.version,
+	template_bundles.hash,
+	template_bundles.manifest,
+	octet_length(template_bundles.data)::bigint AS size,
+	template_bundles.created_by,
+	template_bundles.created_at,
+	users.username AS created_by_username,
+	users.avatar_url AS created_by_avatar_url
+FROM
+	template_bundles
+JOIN
+	users ON users.id = template_bundles.created_by
+WHERE
+	template_bundles.organization_id = @organization_id
+ORDER BY
+	template_bundles.name ASC,
+	template_bundles.created_at DESC;
+
+-- name: GetTemplateBundleByName :one
+SELECT
+	*
+FROM
+	template_bundles
+WHERE
+	organization_id = @organization_id
+	AND name = @name
+	AND version = @version;
+
+-- name: DeleteTemplateBundleByName :exec
+DELETE FROM
+	template_bundles
+WHERE
+	organization_id = @organization_id
+	AND name = @name
+	AND version = @version;
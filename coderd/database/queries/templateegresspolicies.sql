@@ -0,0 +1,31 @@
+-- name: GetTemplateEgressPolicyByTemplateID :one
+SELECT
+	*
+FROM
+	template_egress_policies
+WHERE
+	template_id = @template_id;
+
+-- name: UpsertTemplateEgressPolicy :one
+INSERT INTO
+	template_egress_policies (
+		template_id,
+		allowed_cidrs,
+		allowed_domains,
+		created_at,
+		updated_at
+	)
+VALUES
+	(@template_id, @allowed_cidrs :: text[], @allowed_domains :: text[], @updated_at, @updated_at)
+ON CONFLICT (template_id) DO UPDATE
+SET
+	allowed_cidrs = @allowed_cidrs :: text[],
+	allowed_domains = @allowed_domains :: text[],
+	updated_at = @updated_at
+RETURNING *;
+
+-- name: DeleteTemplateEgressPolicy :exec
+DELETE FROM
+	template_egress_policies
+WHERE
+	template_id = @template_id;
@@ -0,0 +1,54 @@
+-- name: GetTemplateParameterOptionSources :many
+SELECT
+	*
+FROM
+	template_parameter_option_sources
+WHERE
+	template_id = @template_id
+ORDER BY
+	parameter_name ASC;
+
+-- name: GetTemplateParameterOptionSource :one
+SELECT
+	*
+FROM
+	template_parameter_option_sources
+WHERE
+	template_id = @template_id
+	AND parameter_name = @parameter_name;
+
+-- name: UpsertTemplateParameterOptionSource :one
+INSERT INTO template_parameter_option_sources (
+	template_id,
+	parameter_name,
+	url,
+	auth_header_name,
+	auth_header_value,
+	cache_ttl,
+	created_at,
+	updated_at
+)
+VALUES (
+	@template_id,
+	@parameter_name,
+	@url,
+	@auth_header_name,
+	@auth_header_value,
+	@cache_ttl,
+	@updated_at,
+	@updated_at
+)
+ON CONFLICT (template_id, parameter_name) DO UPDATE SET
+	url = EXCLUDED.url,
+	auth_header_name = EXCLUDED.auth_header_name,
+	auth_header_value = EXCLUDED.auth_header_value,
+	cache_ttl = EXCLUDED.cache_ttl,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteTemplateParameterOptionSource :exec
+DELETE FROM
+	template_parameter_option_sources
+WHERE
+	template_id = @template_id
+	AND parameter_name = @parameter_name;
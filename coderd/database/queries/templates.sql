@@ -134,11 +134,53 @@ SET
 	allow_user_cancel_workspace_jobs = $7,
 	group_acl = $8,
 	max_port_sharing_level = $9,
-	use_classic_parameter_flow = $10
+	use_classic_parameter_flow = $10,
+	wireguard_mtu = $11,
+	wireguard_keepalive_interval = $12,
+	wireguard_handshake_timeout = $13,
+	activation_approvals_required = $14,
+	autostart_holidays = $15,
+	autostart_holiday_calendar_url = $16,
+	dormant_archive_paths = $17,
+	parameter_validation_url = $18,
+	allow_start_on_connect = $19
 WHERE
 	id = $1
 ;
 
+-- name: UpdateTemplateOrganizationByID :exec
+-- Moves a template, its versions and the provisioner jobs that imported the
+-- versions to another organization.
+WITH versions AS (
+	UPDATE
+		template_versions
+	SET
+		organization_id = @organization_id,
+		updated_at = @updated_at
+	WHERE
+		template_id = @id
+	RETURNING
+		job_id
+), jobs AS (
+	UPDATE
+		provisioner_jobs
+	SET
+		organization_id = @organization_id,
+		updated_at = @updated_at
+	WHERE
+		id IN (SELECT job_id FROM versions)
+)
+UPDATE
+	templates
+SET
+	organization_id = @organization_id,
+	group_acl = @group_acl,
+	user_acl = @user_acl,
+	updated_at = @updated_at
+WHERE
+	id = @id
+;
+
 -- name: UpdateTemplateScheduleByID :exec
 UPDATE
 	templates
@@ -209,3 +251,15 @@ SET
 WHERE
 	id = $1
 ;
+
+-- name: GetTemplateActivationForUpdate :one
+-- Locks the template while its active version is changed, so that the
+-- approvals it requires can't change until the transaction ends.
+SELECT
+	active_version_id,
+	activation_approvals_required
+FROM
+	templates
+WHERE
+	id = @id
+FOR UPDATE;
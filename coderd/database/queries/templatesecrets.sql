@@ -0,0 +1,53 @@
+-- name: GetTemplateSecretsByTemplateID :many
+SELECT
+	*
+FROM
+	template_secrets
+WHERE
+	template_id = @template_id
+ORDER BY
+	name ASC;
+
+-- name: GetTemplateSecretByTemplateIDAndName :one
+SELECT
+	*
+FROM
+	template_secrets
+WHERE
+	template_id = @template_id
+	AND name = @name;
+
+-- name: InsertTemplateSecret :one
+INSERT INTO
+	template_secrets (
+		id,
+		template_id,
+		name,
+		description,
+		value,
+		value_key_id,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
+
+-- name: UpdateTemplateSecret :one
+UPDATE
+	template_secrets
+SET
+	description = @description,
+	value = @value,
+	value_key_id = @value_key_id,
+	updated_at = @updated_at
+WHERE
+	template_id = @template_id
+	AND name = @name
+RETURNING *;
+
+-- name: DeleteTemplateSecret :exec
+DELETE FROM
+	template_secrets
+WHERE
+	template_id = @template_id
+	AND name = @name;
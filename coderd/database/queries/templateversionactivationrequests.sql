@@ -0,0 +1,138 @@
+-- name: UpsertTemplateVersionActivationRequest :one
+-- Requesting the activation of a version again, for example after it was
+-- rejected, resets the request to pending.
+INSERT INTO template_version_activation_requests (
+	template_version_id,
+	template_id,
+	requested_by,
+	status,
+	approvals_required,
+	created_at,
+	updated_at
+)
+VALUES (
+	@template_version_id,
+	@template_id,
+	@requested_by,
+	'pending'::template_version_activation_request_status,
+	@approvals_required,
+	@created_at,
+	@created_at
+)
+ON CONFLICT (template_version_id) DO UPDATE SET
+	requested_by = EXCLUDED.requested_by,
+	status = EXCLUDED.status,
+	approvals_required = EXCLUDED.approvals_required,
+	created_at = EXCLUDED.created_at,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: GetTemplateVersionActivationRequestByTemplateVersionID :one
+SELECT
+	template_version_activation_requests.*,
+	visible_users.username AS requested_by_username,
+	visible_users.name AS requested_by_name,
+	visible_users.avatar_url AS requested_by_avatar_url
+FROM
+	template_version_activation_requests
+JOIN
+	visible_users ON visible_users.id = template_version_activation_requests.requested_by
+WHERE
+	template_version_activation_requests.template_version_id = @template_version_id;
+
+-- name: UpdateTemplateVersionActivationRequestStatus :one
+UPDATE
+	template_version_activation_requests
+SET
+	status = @status,
+	updated_at = @updated_at
+WHERE
+	template_version_id = @template_version_id
+RETURNING *;
+
+-- name: CancelPendingTemplateVersionActivationRequests :exec
+-- Cancels the pending activation requests of a template once another version
+-- has been made active.
+UPDATE
+	template_version_activation_requests
+SET
+	status = 'canceled'::template_version_activation_request_status,
+	updated_at = @updated_at
+WHERE
+	template_id = @template_id
+	AND template_version_id != @active_version_id
+	AND status = 'pending'::template_version_activation_request_status;
+
+-- name: UpsertTemplateVersionActivationReview :one
+-- A reviewer can change their mind, only their latest review counts.
+INSERT INTO template_version_activation_reviews (
+	template_version_id,
+	reviewer_id,
+	approved,
+	comment,
+	created_at
+)
+VALUES (
+	@template_version_id,
+	@reviewer_id,
+	@approved,
+	@comment,
+	@created_at
+)
+ON CONFLICT (template_version_id, reviewer_id) DO UPDATE SET
+	approved = EXCLUDED.approved,
+	comment = EXCLUDED.comment,
+	created_at = EXCLUDED.created_at
+RETURNING *;
+
+-- name: GetTemplateVersionActivationReviews :many
+SELECT
+	template_version_activation_reviews.*,
+	visible_users.username AS reviewer_username,
+	visible_users.name AS reviewer_name,
+	visible_users.avatar_url AS reviewer_avatar_url
+FROM
+	template_version_activation_reviews
+JOIN
+	visible_users ON visible_users.id = template_version_activation_reviews.reviewer_id
+WHERE
+	template_version_activation_reviews.template_version_id = @template_version_id
+ORDER BY
+	template_version_activation_reviews.created_at ASC;
+
+-- name: DeleteTemplateVersionActivationReviews :exec
+DELETE FROM
+	template_version_activation_reviews
+WHERE
+	template_version_id = @template_version_id;
+
+-- name: GetTemplateActivationReviewers :many
+SELECT
+	template_activation_reviewers.template_id,
+	template_activation_reviewers.user_id,
+	visible_users.username,
+	visible_users.name,
+	visible_users.avatar_url
+FROM
+	template_activation_reviewers
+JOIN
+	visible_users ON visible_users.id = template_activation_reviewers.user_id
+WHERE
+	template_activation_reviewers.template_id = @template_id
+ORDER BY
+	visible_users.username ASC;
+
+-- name: UpdateTemplateActivationReviewers :exec
+-- Replaces the designated reviewers of a template.
+WITH deleted AS (
+	DELETE FROM
+		template_activation_reviewers
+	WHERE
+		template_id = @template_id
+		AND NOT (user_id = ANY(@user_ids :: uuid[]))
+)
+INSERT INTO template_activation_reviewers (template_id, user_id)
+SELECT
+	@template_id,
+	unnest(@user_ids :: uuid[])
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,137 @@
+-- name: UpsertTemplateVersionRollout :one
+-- Starting a rollout replaces the previous rollout of the template, which also
+-- restarts the evaluation of the failure threshold. Rollouts of versions whose
+-- activation hasn't been approved yet are created pending approval.
+INSERT INTO template_version_rollouts (
+	template_id,
+	template_version_id,
+	created_by,
+	percentage,
+	group_ids,
+	failure_threshold_percent,
+	minimum_builds,
+	status,
+	created_at,
+	updated_at
+)
+VALUES (
+	@template_id,
+	@template_version_id,
+	@created_by,
+	@percentage,
+	@group_ids :: uuid[],
+	@failure_threshold_percent,
+	@minimum_builds,
+	@status,
+	@created_at,
+	@created_at
+)
+ON CONFLICT (template_id) DO UPDATE SET
+	template_version_id = EXCLUDED.template_version_id,
+	created_by = EXCLUDED.created_by,
+	percentage = EXCLUDED.percentage,
+	group_ids = EXCLUDED.group_ids,
+	failure_threshold_percent = EXCLUDED.failure_threshold_percent,
+	minimum_builds = EXCLUDED.minimum_builds,
+	status = EXCLUDED.status,
+	created_at = EXCLUDED.created_at,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: GetTemplateVersionRolloutByTemplateID :one
+SELECT
+	*
+FROM
+	template_version_rollouts
+WHERE
+	template_id = @template_id;
+
+-- name: FinishTemplateVersionRollout :exec
+-- Completes the rollout of a template once its version has been made active,
+-- or cancels it if another version has been made active.
+UPDATE
+	template_version_rollouts
+SET
+	status = CASE
+		WHEN template_version_id = @active_version_id THEN 'completed'::template_version_rollout_status
+		ELSE 'canceled'::template_version_rollout_status
+	END,
+	updated_at = @updated_at
+WHERE
+	template_id = @template_id
+	AND status IN ('in_progress'::template_version_rollout_status, 'halted'::template_version_rollout_status, 'pending_approval'::template_version_rollout_status);
+
+-- name: StartTemplateVersionRollout :one
+-- Starts a rollout that was pending the approval of the activation of its
+-- version.
+UPDATE
+	template_version_rollouts
+SET
+	status = 'in_progress'::template_version_rollout_status,
+	updated_at = @updated_at
+WHERE
+	template_id = @template_id
+	AND template_version_id = @template_version_id
+	AND status = 'pending_approval'::template_version_rollout_status
+RETURNING *;
+
+-- name: HaltTemplateVersionRollout :one
+-- Only halts the rollout if it's still in progress, so that concurrent
+-- failures halt it once.
+UPDATE
+	template_version_rollouts
+SET
+	status = 'halted'::template_version_rollout_status,
+	updated_at = @updated_at
+WHERE
+	template_id = @template_id
+	AND template_version_id = @template_version_id
+	AND status = 'in_progress'::template_version_rollout_status
+RETURNING *;
+
+-- name: GetTemplateVersionRolloutBuildStats :one
+-- Counts the finished start builds of the rolled out version since the rollout
+-- was started.
+SELECT
+	COUNT(*) AS total_builds,
+	COUNT(*) FILTER (WHERE provisioner_jobs.job_status = 'failed'::provisioner_job_status) AS failed_builds
+FROM
+	workspace_builds
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+JOIN
+	template_version_rollouts ON template_version_rollouts.template_version_id = workspace_builds.template_version_id
+WHERE
+	template_version_rollouts.template_id = @template_id
+	AND workspace_builds.transition = 'start'::workspace_transition
+	AND workspace_builds.created_at >= template_version_rollouts.created_at
+	AND provisioner_jobs.job_status IN ('succeeded'::provisioner_job_status, 'failed'::provisioner_job_status);
+
+-- name: GetTemplateVersionRolloutTargetsByWorkspaceIDs :many
+-- Returns the template version that workspaces in the cohort of an in progress
+-- rollout are built with instead of the active version of their template.
+-- Workspaces are part of the cohort if their owner is a member of one of the
+-- groups of the rollout, or if they fall within its percentage. Hashing the
+-- workspace ID keeps the cohort stable as the percentage is increased.
+SELECT
+	workspaces.id AS workspace_id,
+	template_version_rollouts.template_version_id
+FROM
+	workspaces
+JOIN
+	template_version_rollouts ON template_version_rollouts.template_id = workspaces.template_id
+WHERE
+	workspaces.id = ANY(@ids :: uuid[])
+	AND template_version_rollouts.status = 'in_progress'::template_version_rollout_status
+	AND (
+		abs(hashtext(workspaces.id::text)::bigint) % 100 < template_version_rollouts.percentage
+		OR EXISTS (
+			SELECT
+				1
+			FROM
+				group_members_expanded
+			WHERE
+				group_members_expanded.user_id = workspaces.owner_id
+				AND group_members_expanded.group_id = ANY(template_version_rollouts.group_ids)
+		)
+	);
@@ -0,0 +1,26 @@
+-- name: UpsertUserCLIVersion :exec
+INSERT INTO
+	user_cli_versions (user_id, version, last_seen_at)
+VALUES
+	(@user_id, @version, @last_seen_at)
+ON CONFLICT (user_id) DO UPDATE SET
+	version = @version,
+	last_seen_at = @last_seen_at;
+
+-- name: GetUserCLIVersions :many
+-- Returns the CLI version each user last made a request with, if the request
+-- was made after the given time.
+SELECT
+	user_cli_versions.user_id,
+	users.username,
+	user_cli_versions.version,
+	user_cli_versions.last_seen_at
+FROM
+	user_cli_versions
+JOIN
+	users ON users.id = user_cli_versions.user_id
+WHERE
+	users.deleted = false
+	AND user_cli_versions.last_seen_at > @seen_after :: timestamptz
+ORDER BY
+	users.username;
@@ -148,6 +148,102 @@ WHERE user_configs.user_id = @user_id
 	AND user_configs.key = 'terminal_font'
 RETURNING *;
 
+-- name: GetUserNotificationDigest :one
+SELECT
+	value as notification_digest
+FROM
+	user_configs
+WHERE
+	user_id = @user_id
+	AND key = 'notification_digest';
+
+-- name: UpdateUserNotificationDigest :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	(@user_id, 'notification_digest', @notification_digest)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = @notification_digest
+WHERE user_configs.user_id = @user_id
+	AND user_configs.key = 'notification_digest'
+RETURNING *;
+
+-- name: GetUserStartOnConnect :one
+SELECT
+	value as start_on_connect
+FROM
+	user_configs
+WHERE
+	user_id = @user_id
+	AND key = 'start_on_connect';
+
+-- name: UpdateUserStartOnConnect :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	(@user_id, 'start_on_connect', @start_on_connect)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = @start_on_connect
+WHERE user_configs.user_id = @user_id
+	AND user_configs.key = 'start_on_connect'
+RETURNING *;
+
+-- name: GetUserProxyPreference :one
+-- GetUserProxyPreference returns the ID of the region the user pinned for
+-- workspace app and terminal traffic. An empty value selects automatically.
+SELECT
+	value as proxy_preference
+FROM
+	user_configs
+WHERE
+	user_id = @user_id
+	AND key = 'proxy_preference';
+
+-- name: UpdateUserProxyPreference :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	(@user_id, 'proxy_preference', @proxy_preference)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = @proxy_preference
+WHERE user_configs.user_id = @user_id
+	AND user_configs.key = 'proxy_preference'
+RETURNING *;
+
+-- name: GetUserProxyLatencies :one
+-- GetUserProxyLatencies returns the JSON encoded latencies the user's client
+-- last measured to each region.
+SELECT
+	value as proxy_latencies
+FROM
+	user_configs
+WHERE
+	user_id = @user_id
+	AND key = 'proxy_latencies';
+
+-- name: UpdateUserProxyLatencies :one
+INSERT INTO
+	user_configs (user_id, key, value)
+VALUES
+	(@user_id, 'proxy_latencies', @proxy_latencies)
+ON CONFLICT
+	ON CONSTRAINT user_configs_pkey
+DO UPDATE
+SET
+	value = @proxy_latencies
+WHERE user_configs.user_id = @user_id
+	AND user_configs.key = 'proxy_latencies'
+RETURNING *;
+
 -- name: UpdateUserRoles :one
 UPDATE
 	users
@@ -0,0 +1,55 @@
+-- name: GetUserSecretsByUserID :many
+SELECT
+	*
+FROM
+	user_secrets
+WHERE
+	user_id = @user_id
+ORDER BY
+	name ASC;
+
+-- name: GetUserSecretByUserIDAndName :one
+SELECT
+	*
+FROM
+	user_secrets
+WHERE
+	user_id = @user_id
+	AND name = @name;
+
+-- name: InsertUserSecret :one
+INSERT INTO
+	user_secrets (
+		id,
+		user_id,
+		name,
+		description,
+		value,
+		value_key_id,
+		env_name,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
+
+-- name: UpdateUserSecret :one
+UPDATE
+	user_secrets
+SET
+	description = @description,
+	value = @value,
+	value_key_id = @value_key_id,
+	env_name = @env_name,
+	updated_at = @updated_at
+WHERE
+	user_id = @user_id
+	AND name = @name
+RETURNING *;
+
+-- name: DeleteUserSecret :exec
+DELETE FROM
+	user_secrets
+WHERE
+	user_id = @user_id
+	AND name = @name;
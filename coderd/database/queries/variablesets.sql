@@ -0,0 +1,77 @@
+-- name: GetVariableSets :many
+SELECT
+	*
+FROM
+	variable_sets
+ORDER BY
+	name ASC;
+
+-- name: GetVariableSetByName :one
+SELECT
+	*
+FROM
+	variable_sets
+WHERE
+	name = @name;
+
+-- name: InsertVariableSet :one
+INSERT INTO
+	variable_sets (
+		id,
+		name,
+		description,
+		variables,
+		variables_key_id,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: UpdateVariableSet :one
+UPDATE
+	variable_sets
+SET
+	description = @description,
+	variables = @variables,
+	variables_key_id = @variables_key_id,
+	updated_at = @updated_at
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: DeleteVariableSet :exec
+DELETE FROM
+	variable_sets
+WHERE
+	id = @id;
+
+-- name: GetVariableSetsByTemplateID :many
+SELECT
+	variable_sets.*
+FROM
+	variable_sets
+INNER JOIN
+	template_variable_sets ON template_variable_sets.variable_set_id = variable_sets.id
+WHERE
+	template_variable_sets.template_id = @template_id
+ORDER BY
+	variable_sets.name ASC;
+
+-- name: InsertTemplateVariableSet :exec
+INSERT INTO
+	template_variable_sets (
+		template_id,
+		variable_set_id,
+		created_at
+	)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (template_id, variable_set_id) DO NOTHING;
+
+-- name: DeleteTemplateVariableSet :exec
+DELETE FROM
+	template_variable_sets
+WHERE
+	template_id = @template_id
+	AND variable_set_id = @variable_set_id;
@@ -0,0 +1,128 @@
+-- name: GetUserWebAuthnCredentialsByUserID :many
+SELECT
+	*
+FROM
+	user_webauthn_credentials
+WHERE
+	user_id = @user_id
+ORDER BY
+	created_at ASC;
+
+-- name: GetUserWebAuthnCredentialByID :one
+SELECT
+	*
+FROM
+	user_webauthn_credentials
+WHERE
+	id = @id;
+
+-- name: GetUserWebAuthnCredentialByCredentialID :one
+SELECT
+	*
+FROM
+	user_webauthn_credentials
+WHERE
+	credential_id = @credential_id;
+
+-- name: InsertUserWebAuthnCredential :one
+INSERT INTO
+	user_webauthn_credentials (id, user_id, name, credential_id, public_key, sign_count, created_at)
+VALUES
+	(@id, @user_id, @name, @credential_id, @public_key, @sign_count, @created_at)
+RETURNING *;
+
+-- name: UpdateUserWebAuthnCredentialSignCount :exec
+UPDATE
+	user_webauthn_credentials
+SET
+	sign_count = @sign_count,
+	last_used_at = @last_used_at
+WHERE
+	id = @id;
+
+-- name: DeleteUserWebAuthnCredential :exec
+DELETE FROM
+	user_webauthn_credentials
+WHERE
+	id = @id;
+
+-- name: InsertWebAuthnChallenge :one
+INSERT INTO
+	webauthn_challenges (id, user_id, purpose, challenge, created_at, expires_at)
+VALUES
+	(@id, @user_id, @purpose, @challenge, @created_at, @expires_at)
+RETURNING *;
+
+-- name: DeleteWebAuthnChallengeByID :one
+-- Returns the deleted challenge, so that each challenge can only be used once
+-- even by concurrent requests.
+DELETE FROM
+	webauthn_challenges
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: DeleteExpiredWebAuthnChallenges :exec
+DELETE FROM
+	webauthn_challenges
+WHERE
+	expires_at < @now;
+
+-- name: InsertUserMFARecoveryCode :exec
+INSERT INTO
+	user_mfa_recovery_codes (user_id, hashed_code, created_at)
+VALUES
+	(@user_id, @hashed_code, @created_at);
+
+-- name: DeleteUserMFARecoveryCodes :exec
+DELETE FROM
+	user_mfa_recovery_codes
+WHERE
+	user_id = @user_id;
+
+-- name: UseUserMFARecoveryCode :one
+UPDATE
+	user_mfa_recovery_codes
+SET
+	used_at = @used_at
+WHERE
+	user_id = @user_id
+	AND hashed_code = @hashed_code
+	AND used_at IS NULL
+RETURNING *;
+
+-- name: GetOrganizationMFASettings :one
+SELECT
+	*
+FROM
+	organization_mfa_settings
+WHERE
+	organization_id = @organization_id;
+
+-- name: UpsertOrganizationMFASettings :one
+INSERT INTO
+	organization_mfa_settings (organization_id, require_webauthn, updated_at)
+VALUES
+	(@organization_id, @require_webauthn, @updated_at)
+ON CONFLICT (organization_id) DO UPDATE SET
+	require_webauthn = @require_webauthn,
+	updated_at = @updated_at
+RETURNING *;
+
+-- name: GetUserWebAuthnRequired :one
+-- Returns true if any organization the user is a member of requires a WebAuthn
+-- credential for password logins.
+SELECT
+	EXISTS (
+		SELECT
+			1
+		FROM
+			organization_mfa_settings
+		JOIN
+			organization_members
+		ON
+			organization_members.organization_id = organization_mfa_settings.organization_id
+		WHERE
+			organization_members.user_id = @user_id
+			AND organization_mfa_settings.require_webauthn
+	);
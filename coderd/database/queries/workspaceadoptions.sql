@@ -0,0 +1,37 @@
+-- name: GetWorkspaceAdoptionByID :one
+SELECT
+	*
+FROM
+	workspace_adoptions
+WHERE
+	id = @id;
+
+-- name: GetWorkspaceAdoptionByWorkspaceID :one
+SELECT
+	*
+FROM
+	workspace_adoptions
+WHERE
+	workspace_id = @workspace_id;
+
+-- name: InsertWorkspaceAdoption :one
+INSERT INTO
+	workspace_adoptions (id, organization_id, owner_id, template_id, workspace_name, hashed_secret, created_by, created_at, expires_at)
+VALUES
+	(@id, @organization_id, @owner_id, @template_id, @workspace_name, @hashed_secret, @created_by, @created_at, @expires_at)
+RETURNING *;
+
+-- name: UpdateWorkspaceAdoptionAdopted :one
+-- Binds the adoption to the workspace created for the machine. No row is
+-- returned if the token was already used or expired.
+UPDATE
+	workspace_adoptions
+SET
+	workspace_id = @workspace_id,
+	hostname = @hostname,
+	adopted_at = @adopted_at
+WHERE
+	id = @id
+	AND workspace_id IS NULL
+	AND expires_at > @adopted_at
+RETURNING *;
@@ -76,3 +76,30 @@ SET
 		debounced_until = $5
 WHERE
 		agent_id = $1 AND path = $2;
+
+-- name: UpsertWorkspaceAgentVolumeUsage :exec
+INSERT INTO
+	workspace_agent_volume_usage (
+		agent_id,
+		path,
+		total_bytes,
+		used_bytes,
+		collected_at
+	)
+VALUES
+	($1, $2, $3, $4, $5)
+ON CONFLICT (agent_id, path) DO UPDATE
+SET
+	total_bytes = EXCLUDED.total_bytes,
+	used_bytes = EXCLUDED.used_bytes,
+	collected_at = EXCLUDED.collected_at;
+
+-- name: GetWorkspaceAgentVolumeUsageByAgentID :many
+SELECT
+	*
+FROM
+	workspace_agent_volume_usage
+WHERE
+	agent_id = $1
+ORDER BY
+	path;
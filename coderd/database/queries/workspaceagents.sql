@@ -122,6 +122,64 @@ INSERT INTO
 VALUES
 	($1, $2, $3, $4, $5, $6, $7);
 
+-- name: InsertWorkspaceAgentPushedMetadata :exec
+-- Inserts the metadata items pushed by processes in the workspace that are
+-- reported for the first time. They are ordered after the metadata of the
+-- template. Items beyond max_pushed per agent are ignored.
+WITH pushed AS (
+	SELECT
+		unnest(sqlc.arg('key')::text[]) AS key,
+		unnest(sqlc.arg('display_name')::text[]) AS display_name
+), existing AS (
+	SELECT
+		COALESCE(MAX(display_order), 0) AS max_display_order,
+		COUNT(*) FILTER (WHERE key LIKE 'push:%') AS pushed_count
+	FROM
+		workspace_agent_metadata
+	WHERE
+		workspace_agent_id = sqlc.arg('workspace_agent_id')::uuid
+), new AS (
+	SELECT
+		p.key,
+		p.display_name,
+		row_number() OVER (ORDER BY p.key) AS n
+	FROM
+		pushed p
+	WHERE
+		NOT EXISTS (
+			SELECT
+				1
+			FROM
+				workspace_agent_metadata wam
+			WHERE
+				wam.workspace_agent_id = sqlc.arg('workspace_agent_id')::uuid
+				AND wam.key = p.key
+		)
+)
+INSERT INTO
+	workspace_agent_metadata (
+		workspace_agent_id,
+		display_name,
+		key,
+		script,
+		timeout,
+		interval,
+		display_order
+	)
+SELECT
+	sqlc.arg('workspace_agent_id')::uuid,
+	new.display_name,
+	new.key,
+	'',
+	0,
+	0,
+	existing.max_display_order + new.n
+FROM
+	new, existing
+WHERE
+	existing.pushed_count + new.n <= sqlc.arg('max_pushed')::int
+ON CONFLICT (workspace_agent_id, key) DO NOTHING;
+
 -- name: UpdateWorkspaceAgentMetadata :exec
 WITH metadata AS (
 	SELECT
@@ -302,7 +360,19 @@ ON
 	workspace_build_with_user.workspace_id = workspaces.id
 WHERE
 	-- This should only match 1 agent, so 1 returned row or 0.
-	workspace_agents.auth_token = @auth_token::uuid
+	(
+		workspace_agents.auth_token = @auth_token::uuid
+		-- Rotated tokens are accepted until their grace period ends.
+		OR workspace_agents.id = (
+			SELECT
+				agent_id
+			FROM
+				workspace_agent_token_rotations
+			WHERE
+				previous_auth_token = @auth_token::uuid
+				AND previous_auth_token_expires_at > NOW()
+		)
+	)
 	AND workspaces.deleted = FALSE
 	-- Filter out deleted sub agents.
 	AND workspace_agents.deleted = FALSE
@@ -365,3 +435,39 @@ WHERE
 	id = $1
 	AND parent_id IS NOT NULL
 	AND deleted = FALSE;
+
+-- name: GetWorkspaceAgentVersionsInLatestBuilds :many
+-- Returns the version every agent in the latest build of a workspace reported
+-- when it last started. Agents that never connected are omitted.
+SELECT
+	workspace_agents.id AS agent_id,
+	workspace_agents.name AS agent_name,
+	workspace_agents.version,
+	workspaces.id AS workspace_id,
+	workspaces.name AS workspace_name,
+	workspaces.owner_id,
+	users.username AS owner_username
+FROM
+	workspace_agents
+JOIN
+	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
+JOIN
+	workspace_builds ON workspace_resources.job_id = workspace_builds.job_id
+JOIN
+	workspaces ON workspace_builds.workspace_id = workspaces.id
+JOIN
+	users ON workspaces.owner_id = users.id
+WHERE
+	workspaces.deleted = FALSE
+	AND workspace_agents.deleted = FALSE
+	AND workspace_agents.version != ''
+	AND workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds AS wb
+		WHERE
+			wb.workspace_id = workspaces.id
+	)
+ORDER BY
+	users.username, workspaces.name, workspace_agents.name;
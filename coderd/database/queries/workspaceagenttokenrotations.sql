@@ -0,0 +1,35 @@
+-- name: GetWorkspaceAgentTokenRotationByAgentID :one
+SELECT
+	*
+FROM
+	workspace_agent_token_rotations
+WHERE
+	agent_id = @agent_id;
+
+-- name: RotateWorkspaceAgentAuthToken :one
+-- Replaces the auth token of the agent if it is still @previous_auth_token.
+-- The previous token keeps authenticating the agent until
+-- @previous_auth_token_expires_at. No row is returned if the token was
+-- already rotated.
+WITH rotated AS (
+	UPDATE
+		workspace_agents
+	SET
+		auth_token = @new_auth_token::uuid
+	WHERE
+		id = @agent_id::uuid
+		AND auth_token = @previous_auth_token::uuid
+	RETURNING
+		id
+)
+INSERT INTO
+	workspace_agent_token_rotations (agent_id, previous_auth_token, previous_auth_token_expires_at, rotated_at)
+SELECT
+	rotated.id, @previous_auth_token::uuid, @previous_auth_token_expires_at::timestamptz, @rotated_at::timestamptz
+FROM
+	rotated
+ON CONFLICT (agent_id) DO UPDATE SET
+	previous_auth_token = EXCLUDED.previous_auth_token,
+	previous_auth_token_expires_at = EXCLUDED.previous_auth_token_expires_at,
+	rotated_at = EXCLUDED.rotated_at
+RETURNING *;
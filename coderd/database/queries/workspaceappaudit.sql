@@ -2,7 +2,9 @@
 --
 -- The returned boolean, new_or_stale, can be used to deduce if a new session
 -- was started. This means that a new row was inserted (no previous session) or
--- the updated_at is older than stale interval.
+-- the updated_at is older than stale interval. Sessions that started before
+-- authenticated_after are also stale, so that re-authenticating starts a new
+-- session.
 INSERT INTO
 	workspace_app_audit_sessions (
 		id,
@@ -37,11 +39,13 @@ DO
 		-- ID is used to know if session was reset on upsert.
 		id = CASE
 			WHEN workspace_app_audit_sessions.updated_at > NOW() - (@stale_interval_ms::bigint || ' ms')::interval
+				AND workspace_app_audit_sessions.started_at >= @authenticated_after::timestamptz
 			THEN workspace_app_audit_sessions.id
 			ELSE EXCLUDED.id
 		END,
 		started_at = CASE
 			WHEN workspace_app_audit_sessions.updated_at > NOW() - (@stale_interval_ms::bigint || ' ms')::interval
+				AND workspace_app_audit_sessions.started_at >= @authenticated_after::timestamptz
 			THEN workspace_app_audit_sessions.started_at
 			ELSE EXCLUDED.started_at
 		END,
@@ -0,0 +1,62 @@
+-- name: InsertWorkspaceArchive :one
+INSERT INTO
+	workspace_archives (
+		id,
+		workspace_id,
+		workspace_name,
+		owner_id,
+		organization_id,
+		template_id,
+		paths,
+		object_key,
+		size_bytes,
+		error,
+		created_at,
+		expires_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING *;
+
+-- name: GetWorkspaceArchiveByID :one
+SELECT
+	*
+FROM
+	workspace_archives
+WHERE
+	id = $1;
+
+-- name: GetLatestWorkspaceArchiveByWorkspaceID :one
+SELECT
+	*
+FROM
+	workspace_archives
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at DESC
+LIMIT
+	1;
+
+-- name: GetWorkspaceArchivesByOwnerID :many
+SELECT
+	*
+FROM
+	workspace_archives
+WHERE
+	owner_id = $1
+ORDER BY
+	created_at DESC;
+
+-- name: GetWorkspaceArchivesExpiredBefore :many
+SELECT
+	*
+FROM
+	workspace_archives
+WHERE
+	expires_at < @now :: timestamptz;
+
+-- name: DeleteWorkspaceArchiveByID :exec
+DELETE FROM
+	workspace_archives
+WHERE
+	id = $1;
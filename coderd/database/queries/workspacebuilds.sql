@@ -75,6 +75,34 @@ ORDER BY
 LIMIT
 	1;
 
+-- name: GetRollbackWorkspaceBuild :one
+-- Returns the most recent successful start build of a workspace that was
+-- created before its latest start build. Rolling back a workspace starts it
+-- with the template version and parameters of this build.
+SELECT
+	wb.*
+FROM
+	workspace_build_with_user AS wb
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = wb.job_id
+WHERE
+	wb.workspace_id = @workspace_id
+	AND wb.build_number < (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds
+		WHERE
+			workspace_builds.workspace_id = @workspace_id
+			AND workspace_builds.transition = 'start'::workspace_transition
+	)
+	AND wb.transition = 'start'::workspace_transition
+	AND provisioner_jobs.job_status = 'succeeded'::provisioner_job_status
+ORDER BY
+	wb.build_number DESC
+LIMIT
+	1;
+
 -- name: GetLatestWorkspaceBuildsByWorkspaceIDs :many
 SELECT wb.*
 FROM (
@@ -121,10 +149,13 @@ INSERT INTO
 		deadline,
 		max_deadline,
 		reason,
-		template_version_preset_id
+		template_version_preset_id,
+		reason_message,
+		initiator_api_key_name,
+		initiator_ci_job_url
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17);
 
 -- name: UpdateWorkspaceBuildCostByID :exec
 UPDATE
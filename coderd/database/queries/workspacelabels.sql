@@ -0,0 +1,23 @@
+-- name: GetWorkspaceLabelsByWorkspaceIDs :many
+SELECT
+	*
+FROM
+	workspace_labels
+WHERE
+	workspace_id = ANY(@workspace_ids :: uuid[])
+ORDER BY
+	workspace_id, key;
+
+-- name: DeleteWorkspaceLabelsByWorkspaceID :exec
+DELETE FROM
+	workspace_labels
+WHERE
+	workspace_id = @workspace_id;
+
+-- name: InsertWorkspaceLabels :exec
+INSERT INTO
+	workspace_labels (workspace_id, key, value)
+SELECT
+	@workspace_id,
+	unnest(@keys :: text[]),
+	unnest(@values :: text[]);
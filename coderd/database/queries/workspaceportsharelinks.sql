@@ -0,0 +1,79 @@
+-- name: InsertWorkspacePortShareLink :one
+INSERT INTO workspace_port_share_links (
+	id,
+	workspace_id,
+	agent_name,
+	port,
+	protocol,
+	hashed_secret,
+	hashed_password,
+	bandwidth_limit_bytes,
+	expires_at,
+	created_by,
+	created_at
+)
+VALUES (
+	@id,
+	@workspace_id,
+	@agent_name,
+	@port,
+	@protocol,
+	@hashed_secret,
+	@hashed_password,
+	@bandwidth_limit_bytes,
+	@expires_at,
+	@created_by,
+	@created_at
+)
+RETURNING *;
+
+-- name: GetWorkspacePortShareLinkByID :one
+SELECT
+	*
+FROM
+	workspace_port_share_links
+WHERE
+	id = @id;
+
+-- name: GetWorkspacePortShareLinksByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_port_share_links
+WHERE
+	workspace_id = @workspace_id
+ORDER BY
+	created_at DESC;
+
+-- name: AddWorkspacePortShareLinkBytesTransferred :exec
+UPDATE
+	workspace_port_share_links
+SET
+	bytes_transferred = bytes_transferred + @bytes
+WHERE
+	id = @id;
+
+-- name: DeleteWorkspacePortShareLink :exec
+DELETE FROM
+	workspace_port_share_links
+WHERE
+	id = @id;
+
+-- name: DeleteWorkspacePortShareLinksByTemplate :exec
+DELETE FROM
+	workspace_port_share_links
+WHERE
+	workspace_id IN (
+		SELECT
+			id
+		FROM
+			workspaces
+		WHERE
+			template_id = @template_id
+	);
+
+-- name: DeleteExpiredWorkspacePortShareLinks :exec
+DELETE FROM
+	workspace_port_share_links
+WHERE
+	expires_at < @now;
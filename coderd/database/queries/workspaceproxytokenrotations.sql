@@ -0,0 +1,42 @@
+-- name: GetWorkspaceProxyTokenRotationByProxyID :one
+SELECT
+	*
+FROM
+	workspace_proxy_token_rotations
+WHERE
+	proxy_id = @proxy_id;
+
+-- name: GetWorkspaceProxyTokenRotations :many
+SELECT
+	*
+FROM
+	workspace_proxy_token_rotations;
+
+-- name: RotateWorkspaceProxyToken :one
+-- Replaces the token of the proxy if it is still
+-- @previous_token_hashed_secret. The previous token keeps authenticating the
+-- proxy until @previous_token_expires_at. No row is returned if the token was
+-- already rotated.
+WITH rotated AS (
+	UPDATE
+		workspace_proxies
+	SET
+		token_hashed_secret = @new_token_hashed_secret::bytea,
+		updated_at = @rotated_at::timestamptz
+	WHERE
+		id = @proxy_id::uuid
+		AND token_hashed_secret = @previous_token_hashed_secret::bytea
+	RETURNING
+		id
+)
+INSERT INTO
+	workspace_proxy_token_rotations (proxy_id, previous_token_hashed_secret, previous_token_expires_at, rotated_at)
+SELECT
+	rotated.id, @previous_token_hashed_secret::bytea, @previous_token_expires_at::timestamptz, @rotated_at::timestamptz
+FROM
+	rotated
+ON CONFLICT (proxy_id) DO UPDATE SET
+	previous_token_hashed_secret = EXCLUDED.previous_token_hashed_secret,
+	previous_token_expires_at = EXCLUDED.previous_token_expires_at,
+	rotated_at = EXCLUDED.rotated_at
+RETURNING *;
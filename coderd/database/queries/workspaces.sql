@@ -370,6 +370,29 @@ WHERE
 			)) = (sqlc.narg('has_ai_task') :: boolean)
 		ELSE true
 	END
+	-- Filter by labels
+	-- @label_keys and @label_values are matched by array index. A workspace
+	-- must have every label, and an empty value matches any value of the key.
+	AND CASE WHEN array_length(@label_keys :: text[], 1) > 0 THEN
+		NOT EXISTS (
+			SELECT
+				1
+			FROM
+				unnest(@label_keys :: text[], @label_values :: text[]) AS label_filter(key, value)
+			WHERE
+				NOT EXISTS (
+					SELECT
+						1
+					FROM
+						workspace_labels
+					WHERE
+						workspace_labels.workspace_id = workspaces.id AND
+						workspace_labels.key = label_filter.key AND
+						(label_filter.value = '' OR workspace_labels.value = label_filter.value)
+				)
+		)
+		ELSE true
+	END
 	-- Authorize Filter clause will be injected below in GetAuthorizedWorkspaces
 	-- @authorize_filter
 ), filtered_workspaces_order AS (
@@ -418,6 +441,7 @@ WHERE
 		'never'::automatic_updates, -- automatic_updates
 		false, -- favorite
 		'0001-01-01 00:00:00+00'::timestamptz, -- next_start_at
+		false, -- ephemeral
 		'', -- owner_avatar_url
 		'', -- owner_username
 		'', -- owner_name
@@ -486,10 +510,11 @@ INSERT INTO
 		ttl,
 		last_used_at,
 		automatic_updates,
-		next_start_at
+		next_start_at,
+		ephemeral
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING *;
 
 -- name: UpdateWorkspaceDeletedByID :exec
 UPDATE
@@ -807,6 +832,33 @@ AND
     dormant_at IS NOT NULL
 RETURNING *;
 
+-- name: UpdateWorkspacesOrganizationByTemplateID :exec
+-- Moves the workspaces of a template, and the provisioner jobs of their
+-- builds, to another organization. Deleted workspaces are moved as well so
+-- that they stay in the organization of their template.
+WITH moved AS (
+	UPDATE
+		workspaces
+	SET
+		organization_id = @organization_id,
+		updated_at = @updated_at
+	WHERE
+		template_id = @template_id
+	RETURNING
+		id
+)
+UPDATE
+	provisioner_jobs
+SET
+	organization_id = @organization_id,
+	updated_at = @updated_at
+FROM
+	workspace_builds
+WHERE
+	workspace_builds.job_id = provisioner_jobs.id
+	AND workspace_builds.workspace_id IN (SELECT id FROM moved)
+;
+
 -- name: UpdateTemplateWorkspacesLastUsedAt :exec
 UPDATE workspaces
 SET
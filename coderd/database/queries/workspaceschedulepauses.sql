@@ -0,0 +1,36 @@
+-- name: GetWorkspaceSchedulePauseByWorkspaceID :one
+SELECT
+	*
+FROM
+	workspace_schedule_pauses
+WHERE
+	workspace_id = @workspace_id;
+
+-- name: UpsertWorkspaceSchedulePause :one
+INSERT INTO
+	workspace_schedule_pauses (
+		workspace_id,
+		paused_until,
+		created_at,
+		created_by
+	)
+VALUES (
+	@workspace_id,
+	@paused_until,
+	@created_at,
+	@created_by
+)
+ON CONFLICT (
+	workspace_id
+)
+DO UPDATE SET
+	paused_until = @paused_until,
+	created_at = @created_at,
+	created_by = @created_by
+RETURNING *;
+
+-- name: DeleteWorkspaceSchedulePause :exec
+DELETE FROM
+	workspace_schedule_pauses
+WHERE
+	workspace_id = @workspace_id;
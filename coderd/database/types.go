@@ -237,3 +237,34 @@ func (a *UserLinkClaims) Scan(src interface{}) error {
 func (a UserLinkClaims) Value() (driver.Value, error) {
 	return json.Marshal(a)
 }
+
+// VariableSetVariable is a Terraform variable value of a variable set. The
+// variables of a set are stored as a JSON array in a single column so they
+// can be encrypted together.
+type VariableSetVariable struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Secret bool   `json:"secret"`
+}
+
+// ParseVariableSetVariables parses the variables column of a variable set.
+func ParseVariableSetVariables(variables string) ([]VariableSetVariable, error) {
+	var vars []VariableSetVariable
+	if err := json.Unmarshal([]byte(variables), &vars); err != nil {
+		return nil, xerrors.Errorf("unmarshal variable set variables: %w", err)
+	}
+	return vars, nil
+}
+
+// FormatVariableSetVariables formats variables for the variables column of a
+// variable set.
+func FormatVariableSetVariables(vars []VariableSetVariable) (string, error) {
+	if vars == nil {
+		vars = []VariableSetVariable{}
+	}
+	b, err := json.Marshal(vars)
+	if err != nil {
+		return "", xerrors.Errorf("marshal variable set variables: %w", err)
+	}
+	return string(b), nil
+}
@@ -7,7 +7,9 @@ type UniqueConstraint string
 // UniqueConstraint enums.
 const (
 	UniqueAgentStatsPkey                                      UniqueConstraint = "agent_stats_pkey"                                                // ALTER TABLE ONLY workspace_agent_stats ADD CONSTRAINT agent_stats_pkey PRIMARY KEY (id);
+	UniqueAiTaskQueuePkey                                     UniqueConstraint = "ai_task_queue_pkey"                                              // ALTER TABLE ONLY ai_task_queue ADD CONSTRAINT ai_task_queue_pkey PRIMARY KEY (id);
 	UniqueAPIKeysPkey                                         UniqueConstraint = "api_keys_pkey"                                                   // ALTER TABLE ONLY api_keys ADD CONSTRAINT api_keys_pkey PRIMARY KEY (id);
+	UniqueAuditLogLegalHoldsPkey                              UniqueConstraint = "audit_log_legal_holds_pkey"                                      // ALTER TABLE ONLY audit_log_legal_holds ADD CONSTRAINT audit_log_legal_holds_pkey PRIMARY KEY (id);
 	UniqueAuditLogsPkey                                       UniqueConstraint = "audit_logs_pkey"                                                 // ALTER TABLE ONLY audit_logs ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
 	UniqueCryptoKeysPkey                                      UniqueConstraint = "crypto_keys_pkey"                                                // ALTER TABLE ONLY crypto_keys ADD CONSTRAINT crypto_keys_pkey PRIMARY KEY (feature, sequence);
 	UniqueCustomRolesUniqueKey                                UniqueConstraint = "custom_roles_unique_key"                                         // ALTER TABLE ONLY custom_roles ADD CONSTRAINT custom_roles_unique_key UNIQUE (name, organization_id);
@@ -19,12 +21,18 @@ const (
 	UniqueGitAuthLinksProviderIDUserIDKey                     UniqueConstraint = "git_auth_links_provider_id_user_id_key"                          // ALTER TABLE ONLY external_auth_links ADD CONSTRAINT git_auth_links_provider_id_user_id_key UNIQUE (provider_id, user_id);
 	UniqueGitSSHKeysPkey                                      UniqueConstraint = "gitsshkeys_pkey"                                                 // ALTER TABLE ONLY gitsshkeys ADD CONSTRAINT gitsshkeys_pkey PRIMARY KEY (user_id);
 	UniqueGroupMembersUserIDGroupIDKey                        UniqueConstraint = "group_members_user_id_group_id_key"                              // ALTER TABLE ONLY group_members ADD CONSTRAINT group_members_user_id_group_id_key UNIQUE (user_id, group_id);
+	UniqueGroupQuotaAllowancesPkey                            UniqueConstraint = "group_quota_allowances_pkey"                                     // ALTER TABLE ONLY group_quota_allowances ADD CONSTRAINT group_quota_allowances_pkey PRIMARY KEY (group_id, dimension);
 	UniqueGroupsNameOrganizationIDKey                         UniqueConstraint = "groups_name_organization_id_key"                                 // ALTER TABLE ONLY groups ADD CONSTRAINT groups_name_organization_id_key UNIQUE (name, organization_id);
 	UniqueGroupsPkey                                          UniqueConstraint = "groups_pkey"                                                     // ALTER TABLE ONLY groups ADD CONSTRAINT groups_pkey PRIMARY KEY (id);
+	UniqueHealthScoresPkey                                    UniqueConstraint = "health_scores_pkey"                                              // ALTER TABLE ONLY health_scores ADD CONSTRAINT health_scores_pkey PRIMARY KEY (replica_id, created_at);
 	UniqueInboxNotificationsPkey                              UniqueConstraint = "inbox_notifications_pkey"                                        // ALTER TABLE ONLY inbox_notifications ADD CONSTRAINT inbox_notifications_pkey PRIMARY KEY (id);
 	UniqueJfrogXrayScansPkey                                  UniqueConstraint = "jfrog_xray_scans_pkey"                                           // ALTER TABLE ONLY jfrog_xray_scans ADD CONSTRAINT jfrog_xray_scans_pkey PRIMARY KEY (agent_id, workspace_id);
+	UniqueLdapUsersExternalIDKey                              UniqueConstraint = "ldap_users_external_id_key"                                      // ALTER TABLE ONLY ldap_users ADD CONSTRAINT ldap_users_external_id_key UNIQUE (external_id);
+	UniqueLdapUsersPkey                                       UniqueConstraint = "ldap_users_pkey"                                                 // ALTER TABLE ONLY ldap_users ADD CONSTRAINT ldap_users_pkey PRIMARY KEY (user_id);
+	UniqueLicenseSeatReservationsPkey                         UniqueConstraint = "license_seat_reservations_pkey"                                  // ALTER TABLE ONLY license_seat_reservations ADD CONSTRAINT license_seat_reservations_pkey PRIMARY KEY (group_id);
 	UniqueLicensesJWTKey                                      UniqueConstraint = "licenses_jwt_key"                                                // ALTER TABLE ONLY licenses ADD CONSTRAINT licenses_jwt_key UNIQUE (jwt);
 	UniqueLicensesPkey                                        UniqueConstraint = "licenses_pkey"                                                   // ALTER TABLE ONLY licenses ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
+	UniqueNotificationCategoryPreferencesPkey                 UniqueConstraint = "notification_category_preferences_pkey"                          // ALTER TABLE ONLY notification_category_preferences ADD CONSTRAINT notification_category_preferences_pkey PRIMARY KEY (user_id, category, method);
 	UniqueNotificationMessagesPkey                            UniqueConstraint = "notification_messages_pkey"                                      // ALTER TABLE ONLY notification_messages ADD CONSTRAINT notification_messages_pkey PRIMARY KEY (id);
 	UniqueNotificationPreferencesPkey                         UniqueConstraint = "notification_preferences_pkey"                                   // ALTER TABLE ONLY notification_preferences ADD CONSTRAINT notification_preferences_pkey PRIMARY KEY (user_id, notification_template_id);
 	UniqueNotificationReportGeneratorLogsPkey                 UniqueConstraint = "notification_report_generator_logs_pkey"                         // ALTER TABLE ONLY notification_report_generator_logs ADD CONSTRAINT notification_report_generator_logs_pkey PRIMARY KEY (notification_template_id);
@@ -32,12 +40,18 @@ const (
 	UniqueNotificationTemplatesPkey                           UniqueConstraint = "notification_templates_pkey"                                     // ALTER TABLE ONLY notification_templates ADD CONSTRAINT notification_templates_pkey PRIMARY KEY (id);
 	UniqueOauth2ProviderAppCodesPkey                          UniqueConstraint = "oauth2_provider_app_codes_pkey"                                  // ALTER TABLE ONLY oauth2_provider_app_codes ADD CONSTRAINT oauth2_provider_app_codes_pkey PRIMARY KEY (id);
 	UniqueOauth2ProviderAppCodesSecretPrefixKey               UniqueConstraint = "oauth2_provider_app_codes_secret_prefix_key"                     // ALTER TABLE ONLY oauth2_provider_app_codes ADD CONSTRAINT oauth2_provider_app_codes_secret_prefix_key UNIQUE (secret_prefix);
+	UniqueOauth2ProviderAppDeviceCodesDeviceCodePrefixKey     UniqueConstraint = "oauth2_provider_app_device_codes_device_code_prefix_key"         // ALTER TABLE ONLY oauth2_provider_app_device_codes ADD CONSTRAINT oauth2_provider_app_device_codes_device_code_prefix_key UNIQUE (device_code_prefix);
+	UniqueOauth2ProviderAppDeviceCodesPkey                    UniqueConstraint = "oauth2_provider_app_device_codes_pkey"                           // ALTER TABLE ONLY oauth2_provider_app_device_codes ADD CONSTRAINT oauth2_provider_app_device_codes_pkey PRIMARY KEY (id);
+	UniqueOauth2ProviderAppDeviceCodesUserCodeKey             UniqueConstraint = "oauth2_provider_app_device_codes_user_code_key"                  // ALTER TABLE ONLY oauth2_provider_app_device_codes ADD CONSTRAINT oauth2_provider_app_device_codes_user_code_key UNIQUE (user_code);
 	UniqueOauth2ProviderAppSecretsPkey                        UniqueConstraint = "oauth2_provider_app_secrets_pkey"                                // ALTER TABLE ONLY oauth2_provider_app_secrets ADD CONSTRAINT oauth2_provider_app_secrets_pkey PRIMARY KEY (id);
 	UniqueOauth2ProviderAppSecretsSecretPrefixKey             UniqueConstraint = "oauth2_provider_app_secrets_secret_prefix_key"                   // ALTER TABLE ONLY oauth2_provider_app_secrets ADD CONSTRAINT oauth2_provider_app_secrets_secret_prefix_key UNIQUE (secret_prefix);
 	UniqueOauth2ProviderAppTokensHashPrefixKey                UniqueConstraint = "oauth2_provider_app_tokens_hash_prefix_key"                      // ALTER TABLE ONLY oauth2_provider_app_tokens ADD CONSTRAINT oauth2_provider_app_tokens_hash_prefix_key UNIQUE (hash_prefix);
 	UniqueOauth2ProviderAppTokensPkey                         UniqueConstraint = "oauth2_provider_app_tokens_pkey"                                 // ALTER TABLE ONLY oauth2_provider_app_tokens ADD CONSTRAINT oauth2_provider_app_tokens_pkey PRIMARY KEY (id);
 	UniqueOauth2ProviderAppsPkey                              UniqueConstraint = "oauth2_provider_apps_pkey"                                       // ALTER TABLE ONLY oauth2_provider_apps ADD CONSTRAINT oauth2_provider_apps_pkey PRIMARY KEY (id);
+	UniqueOrganizationAuditSettingsPkey                       UniqueConstraint = "organization_audit_settings_pkey"                                // ALTER TABLE ONLY organization_audit_settings ADD CONSTRAINT organization_audit_settings_pkey PRIMARY KEY (organization_id);
+	UniqueOrganizationMfaSettingsPkey                         UniqueConstraint = "organization_mfa_settings_pkey"                                  // ALTER TABLE ONLY organization_mfa_settings ADD CONSTRAINT organization_mfa_settings_pkey PRIMARY KEY (organization_id);
 	UniqueOrganizationMembersPkey                             UniqueConstraint = "organization_members_pkey"                                       // ALTER TABLE ONLY organization_members ADD CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id);
+	UniqueOrganizationSettingOverridesPkey                    UniqueConstraint = "organization_setting_overrides_pkey"                             // ALTER TABLE ONLY organization_setting_overrides ADD CONSTRAINT organization_setting_overrides_pkey PRIMARY KEY (organization_id);
 	UniqueOrganizationsPkey                                   UniqueConstraint = "organizations_pkey"                                              // ALTER TABLE ONLY organizations ADD CONSTRAINT organizations_pkey PRIMARY KEY (id);
 	UniqueParameterSchemasJobIDNameKey                        UniqueConstraint = "parameter_schemas_job_id_name_key"                               // ALTER TABLE ONLY parameter_schemas ADD CONSTRAINT parameter_schemas_job_id_name_key UNIQUE (job_id, name);
 	UniqueParameterSchemasPkey                                UniqueConstraint = "parameter_schemas_pkey"                                          // ALTER TABLE ONLY parameter_schemas ADD CONSTRAINT parameter_schemas_pkey PRIMARY KEY (id);
@@ -47,6 +61,10 @@ const (
 	UniqueProvisionerJobLogsPkey                              UniqueConstraint = "provisioner_job_logs_pkey"                                       // ALTER TABLE ONLY provisioner_job_logs ADD CONSTRAINT provisioner_job_logs_pkey PRIMARY KEY (id);
 	UniqueProvisionerJobsPkey                                 UniqueConstraint = "provisioner_jobs_pkey"                                           // ALTER TABLE ONLY provisioner_jobs ADD CONSTRAINT provisioner_jobs_pkey PRIMARY KEY (id);
 	UniqueProvisionerKeysPkey                                 UniqueConstraint = "provisioner_keys_pkey"                                           // ALTER TABLE ONLY provisioner_keys ADD CONSTRAINT provisioner_keys_pkey PRIMARY KEY (id);
+	UniqueReviewWorkspacesOrganizationIDRepositoryBranchKey   UniqueConstraint = "review_workspaces_organization_id_repository_branch_key"         // ALTER TABLE ONLY review_workspaces ADD CONSTRAINT review_workspaces_organization_id_repository_branch_key UNIQUE (organization_id, repository, branch);
+	UniqueReviewWorkspacesPkey                                UniqueConstraint = "review_workspaces_pkey"                                          // ALTER TABLE ONLY review_workspaces ADD CONSTRAINT review_workspaces_pkey PRIMARY KEY (workspace_id);
+	UniqueScimGroupMembersPkey                                UniqueConstraint = "scim_group_members_pkey"                                         // ALTER TABLE ONLY scim_group_members ADD CONSTRAINT scim_group_members_pkey PRIMARY KEY (group_id, user_id);
+	UniqueScimNestedGroupsPkey                                UniqueConstraint = "scim_nested_groups_pkey"                                         // ALTER TABLE ONLY scim_nested_groups ADD CONSTRAINT scim_nested_groups_pkey PRIMARY KEY (group_id, member_group_id);
 	UniqueSiteConfigsKeyKey                                   UniqueConstraint = "site_configs_key_key"                                            // ALTER TABLE ONLY site_configs ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 	UniqueTailnetAgentsPkey                                   UniqueConstraint = "tailnet_agents_pkey"                                             // ALTER TABLE ONLY tailnet_agents ADD CONSTRAINT tailnet_agents_pkey PRIMARY KEY (id, coordinator_id);
 	UniqueTailnetClientSubscriptionsPkey                      UniqueConstraint = "tailnet_client_subscriptions_pkey"                               // ALTER TABLE ONLY tailnet_client_subscriptions ADD CONSTRAINT tailnet_client_subscriptions_pkey PRIMARY KEY (client_id, coordinator_id, agent_id);
@@ -55,24 +73,48 @@ const (
 	UniqueTailnetPeersPkey                                    UniqueConstraint = "tailnet_peers_pkey"                                              // ALTER TABLE ONLY tailnet_peers ADD CONSTRAINT tailnet_peers_pkey PRIMARY KEY (id, coordinator_id);
 	UniqueTailnetTunnelsPkey                                  UniqueConstraint = "tailnet_tunnels_pkey"                                            // ALTER TABLE ONLY tailnet_tunnels ADD CONSTRAINT tailnet_tunnels_pkey PRIMARY KEY (coordinator_id, src_id, dst_id);
 	UniqueTelemetryItemsPkey                                  UniqueConstraint = "telemetry_items_pkey"                                            // ALTER TABLE ONLY telemetry_items ADD CONSTRAINT telemetry_items_pkey PRIMARY KEY (key);
+	UniqueTemplateActivationReviewersPkey                     UniqueConstraint = "template_activation_reviewers_pkey"                              // ALTER TABLE ONLY template_activation_reviewers ADD CONSTRAINT template_activation_reviewers_pkey PRIMARY KEY (template_id, user_id);
+	UniqueTemplateAppRestrictionsPkey                         UniqueConstraint = "template_app_restrictions_pkey"                                  // ALTER TABLE ONLY template_app_restrictions ADD CONSTRAINT template_app_restrictions_pkey PRIMARY KEY (template_id, app_slug);
+	UniqueTemplateAppSessionPoliciesPkey                      UniqueConstraint = "template_app_session_policies_pkey"                              // ALTER TABLE ONLY template_app_session_policies ADD CONSTRAINT template_app_session_policies_pkey PRIMARY KEY (template_id, app_slug);
+	UniqueTemplateBundlesOrganizationIDNameVersionKey         UniqueConstraint = "template_bundles_organization_id_name_version_key"               // ALTER TABLE ONLY template_bundles ADD CONSTRAINT template_bundles_organization_id_name_version_key UNIQUE (organization_id, name, version);
+	UniqueTemplateBundlesPkey                                 UniqueConstraint = "template_bundles_pkey"                                           // ALTER TABLE ONLY template_bundles ADD CONSTRAINT template_bundles_pkey PRIMARY KEY (id);
+	UniqueTemplateEgressPoliciesPkey                          UniqueConstraint = "template_egress_policies_pkey"                                   // ALTER TABLE ONLY template_egress_policies ADD CONSTRAINT template_egress_policies_pkey PRIMARY KEY (template_id);
+	UniqueTemplateParameterOptionSourcesPkey                  UniqueConstraint = "template_parameter_option_sources_pkey"                          // ALTER TABLE ONLY template_parameter_option_sources ADD CONSTRAINT template_parameter_option_sources_pkey PRIMARY KEY (template_id, parameter_name);
+	UniqueTemplateSecretsPkey                                 UniqueConstraint = "template_secrets_pkey"                                           // ALTER TABLE ONLY template_secrets ADD CONSTRAINT template_secrets_pkey PRIMARY KEY (id);
+	UniqueTemplateSecretsTemplateIDNameKey                    UniqueConstraint = "template_secrets_template_id_name_key"                           // ALTER TABLE ONLY template_secrets ADD CONSTRAINT template_secrets_template_id_name_key UNIQUE (template_id, name);
 	UniqueTemplateUsageStatsPkey                              UniqueConstraint = "template_usage_stats_pkey"                                       // ALTER TABLE ONLY template_usage_stats ADD CONSTRAINT template_usage_stats_pkey PRIMARY KEY (start_time, template_id, user_id);
+	UniqueTemplateVariableSetsPkey                            UniqueConstraint = "template_variable_sets_pkey"                                     // ALTER TABLE ONLY template_variable_sets ADD CONSTRAINT template_variable_sets_pkey PRIMARY KEY (template_id, variable_set_id);
+	UniqueTemplateVersionActivationRequestsPkey               UniqueConstraint = "template_version_activation_requests_pkey"                       // ALTER TABLE ONLY template_version_activation_requests ADD CONSTRAINT template_version_activation_requests_pkey PRIMARY KEY (template_version_id);
+	UniqueTemplateVersionActivationReviewsPkey                UniqueConstraint = "template_version_activation_reviews_pkey"                        // ALTER TABLE ONLY template_version_activation_reviews ADD CONSTRAINT template_version_activation_reviews_pkey PRIMARY KEY (template_version_id, reviewer_id);
 	UniqueTemplateVersionParametersTemplateVersionIDNameKey   UniqueConstraint = "template_version_parameters_template_version_id_name_key"        // ALTER TABLE ONLY template_version_parameters ADD CONSTRAINT template_version_parameters_template_version_id_name_key UNIQUE (template_version_id, name);
 	UniqueTemplateVersionPresetParametersPkey                 UniqueConstraint = "template_version_preset_parameters_pkey"                         // ALTER TABLE ONLY template_version_preset_parameters ADD CONSTRAINT template_version_preset_parameters_pkey PRIMARY KEY (id);
 	UniqueTemplateVersionPresetPrebuildSchedulesPkey          UniqueConstraint = "template_version_preset_prebuild_schedules_pkey"                 // ALTER TABLE ONLY template_version_preset_prebuild_schedules ADD CONSTRAINT template_version_preset_prebuild_schedules_pkey PRIMARY KEY (id);
 	UniqueTemplateVersionPresetsPkey                          UniqueConstraint = "template_version_presets_pkey"                                   // ALTER TABLE ONLY template_version_presets ADD CONSTRAINT template_version_presets_pkey PRIMARY KEY (id);
+	UniqueTemplateVersionRolloutsPkey                         UniqueConstraint = "template_version_rollouts_pkey"                                  // ALTER TABLE ONLY template_version_rollouts ADD CONSTRAINT template_version_rollouts_pkey PRIMARY KEY (template_id);
 	UniqueTemplateVersionTerraformValuesTemplateVersionIDKey  UniqueConstraint = "template_version_terraform_values_template_version_id_key"       // ALTER TABLE ONLY template_version_terraform_values ADD CONSTRAINT template_version_terraform_values_template_version_id_key UNIQUE (template_version_id);
 	UniqueTemplateVersionVariablesTemplateVersionIDNameKey    UniqueConstraint = "template_version_variables_template_version_id_name_key"         // ALTER TABLE ONLY template_version_variables ADD CONSTRAINT template_version_variables_template_version_id_name_key UNIQUE (template_version_id, name);
 	UniqueTemplateVersionWorkspaceTagsTemplateVersionIDKeyKey UniqueConstraint = "template_version_workspace_tags_template_version_id_key_key"     // ALTER TABLE ONLY template_version_workspace_tags ADD CONSTRAINT template_version_workspace_tags_template_version_id_key_key UNIQUE (template_version_id, key);
 	UniqueTemplateVersionsPkey                                UniqueConstraint = "template_versions_pkey"                                          // ALTER TABLE ONLY template_versions ADD CONSTRAINT template_versions_pkey PRIMARY KEY (id);
 	UniqueTemplateVersionsTemplateIDNameKey                   UniqueConstraint = "template_versions_template_id_name_key"                          // ALTER TABLE ONLY template_versions ADD CONSTRAINT template_versions_template_id_name_key UNIQUE (template_id, name);
 	UniqueTemplatesPkey                                       UniqueConstraint = "templates_pkey"                                                  // ALTER TABLE ONLY templates ADD CONSTRAINT templates_pkey PRIMARY KEY (id);
+	UniqueUserCliVersionsPkey                                 UniqueConstraint = "user_cli_versions_pkey"                                          // ALTER TABLE ONLY user_cli_versions ADD CONSTRAINT user_cli_versions_pkey PRIMARY KEY (user_id);
 	UniqueUserConfigsPkey                                     UniqueConstraint = "user_configs_pkey"                                               // ALTER TABLE ONLY user_configs ADD CONSTRAINT user_configs_pkey PRIMARY KEY (user_id, key);
 	UniqueUserDeletedPkey                                     UniqueConstraint = "user_deleted_pkey"                                               // ALTER TABLE ONLY user_deleted ADD CONSTRAINT user_deleted_pkey PRIMARY KEY (id);
 	UniqueUserLinksPkey                                       UniqueConstraint = "user_links_pkey"                                                 // ALTER TABLE ONLY user_links ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type);
+	UniqueUserMfaRecoveryCodesPkey                            UniqueConstraint = "user_mfa_recovery_codes_pkey"                                    // ALTER TABLE ONLY user_mfa_recovery_codes ADD CONSTRAINT user_mfa_recovery_codes_pkey PRIMARY KEY (user_id, hashed_code);
+	UniqueUserSecretsPkey                                     UniqueConstraint = "user_secrets_pkey"                                               // ALTER TABLE ONLY user_secrets ADD CONSTRAINT user_secrets_pkey PRIMARY KEY (id);
+	UniqueUserSecretsUserIDNameKey                            UniqueConstraint = "user_secrets_user_id_name_key"                                   // ALTER TABLE ONLY user_secrets ADD CONSTRAINT user_secrets_user_id_name_key UNIQUE (user_id, name);
 	UniqueUserStatusChangesPkey                               UniqueConstraint = "user_status_changes_pkey"                                        // ALTER TABLE ONLY user_status_changes ADD CONSTRAINT user_status_changes_pkey PRIMARY KEY (id);
+	UniqueUserWebauthnCredentialsCredentialIDKey              UniqueConstraint = "user_webauthn_credentials_credential_id_key"                     // ALTER TABLE ONLY user_webauthn_credentials ADD CONSTRAINT user_webauthn_credentials_credential_id_key UNIQUE (credential_id);
+	UniqueUserWebauthnCredentialsPkey                         UniqueConstraint = "user_webauthn_credentials_pkey"                                  // ALTER TABLE ONLY user_webauthn_credentials ADD CONSTRAINT user_webauthn_credentials_pkey PRIMARY KEY (id);
+	UniqueVariableSetsNameKey                                 UniqueConstraint = "variable_sets_name_key"                                          // ALTER TABLE ONLY variable_sets ADD CONSTRAINT variable_sets_name_key UNIQUE (name);
+	UniqueVariableSetsPkey                                    UniqueConstraint = "variable_sets_pkey"                                              // ALTER TABLE ONLY variable_sets ADD CONSTRAINT variable_sets_pkey PRIMARY KEY (id);
 	UniqueUsersPkey                                           UniqueConstraint = "users_pkey"                                                      // ALTER TABLE ONLY users ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+	UniqueWebauthnChallengesPkey                              UniqueConstraint = "webauthn_challenges_pkey"                                        // ALTER TABLE ONLY webauthn_challenges ADD CONSTRAINT webauthn_challenges_pkey PRIMARY KEY (id);
 	UniqueWebpushSubscriptionsPkey                            UniqueConstraint = "webpush_subscriptions_pkey"                                      // ALTER TABLE ONLY webpush_subscriptions ADD CONSTRAINT webpush_subscriptions_pkey PRIMARY KEY (id);
+	UniqueWorkspaceAdoptionsPkey                              UniqueConstraint = "workspace_adoptions_pkey"                                        // ALTER TABLE ONLY workspace_adoptions ADD CONSTRAINT workspace_adoptions_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAgentDevcontainersPkey                     UniqueConstraint = "workspace_agent_devcontainers_pkey"                              // ALTER TABLE ONLY workspace_agent_devcontainers ADD CONSTRAINT workspace_agent_devcontainers_pkey PRIMARY KEY (id);
+	UniqueWorkspaceAgentLifecycleEventsPkey                   UniqueConstraint = "workspace_agent_lifecycle_events_pkey"                           // ALTER TABLE ONLY workspace_agent_lifecycle_events ADD CONSTRAINT workspace_agent_lifecycle_events_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAgentLogSourcesPkey                        UniqueConstraint = "workspace_agent_log_sources_pkey"                                // ALTER TABLE ONLY workspace_agent_log_sources ADD CONSTRAINT workspace_agent_log_sources_pkey PRIMARY KEY (workspace_agent_id, id);
 	UniqueWorkspaceAgentMemoryResourceMonitorsPkey            UniqueConstraint = "workspace_agent_memory_resource_monitors_pkey"                   // ALTER TABLE ONLY workspace_agent_memory_resource_monitors ADD CONSTRAINT workspace_agent_memory_resource_monitors_pkey PRIMARY KEY (agent_id);
 	UniqueWorkspaceAgentMetadataPkey                          UniqueConstraint = "workspace_agent_metadata_pkey"                                   // ALTER TABLE ONLY workspace_agent_metadata ADD CONSTRAINT workspace_agent_metadata_pkey PRIMARY KEY (workspace_agent_id, key);
@@ -80,7 +122,9 @@ const (
 	UniqueWorkspaceAgentScriptTimingsScriptIDStartedAtKey     UniqueConstraint = "workspace_agent_script_timings_script_id_started_at_key"         // ALTER TABLE ONLY workspace_agent_script_timings ADD CONSTRAINT workspace_agent_script_timings_script_id_started_at_key UNIQUE (script_id, started_at);
 	UniqueWorkspaceAgentScriptsIDKey                          UniqueConstraint = "workspace_agent_scripts_id_key"                                  // ALTER TABLE ONLY workspace_agent_scripts ADD CONSTRAINT workspace_agent_scripts_id_key UNIQUE (id);
 	UniqueWorkspaceAgentStartupLogsPkey                       UniqueConstraint = "workspace_agent_startup_logs_pkey"                               // ALTER TABLE ONLY workspace_agent_logs ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
+	UniqueWorkspaceAgentTokenRotationsPkey                    UniqueConstraint = "workspace_agent_token_rotations_pkey"                            // ALTER TABLE ONLY workspace_agent_token_rotations ADD CONSTRAINT workspace_agent_token_rotations_pkey PRIMARY KEY (agent_id);
 	UniqueWorkspaceAgentVolumeResourceMonitorsPkey            UniqueConstraint = "workspace_agent_volume_resource_monitors_pkey"                   // ALTER TABLE ONLY workspace_agent_volume_resource_monitors ADD CONSTRAINT workspace_agent_volume_resource_monitors_pkey PRIMARY KEY (agent_id, path);
+	UniqueWorkspaceAgentVolumeUsagePkey                       UniqueConstraint = "workspace_agent_volume_usage_pkey"                               // ALTER TABLE ONLY workspace_agent_volume_usage ADD CONSTRAINT workspace_agent_volume_usage_pkey PRIMARY KEY (agent_id, path);
 	UniqueWorkspaceAgentsPkey                                 UniqueConstraint = "workspace_agents_pkey"                                           // ALTER TABLE ONLY workspace_agents ADD CONSTRAINT workspace_agents_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAppAuditSessionsAgentIDAppIDUserIDIpUseKey UniqueConstraint = "workspace_app_audit_sessions_agent_id_app_id_user_id_ip_use_key" // ALTER TABLE ONLY workspace_app_audit_sessions ADD CONSTRAINT workspace_app_audit_sessions_agent_id_app_id_user_id_ip_use_key UNIQUE (agent_id, app_id, user_id, ip, user_agent, slug_or_port, status_code);
 	UniqueWorkspaceAppAuditSessionsPkey                       UniqueConstraint = "workspace_app_audit_sessions_pkey"                               // ALTER TABLE ONLY workspace_app_audit_sessions ADD CONSTRAINT workspace_app_audit_sessions_pkey PRIMARY KEY (id);
@@ -89,15 +133,21 @@ const (
 	UniqueWorkspaceAppStatusesPkey                            UniqueConstraint = "workspace_app_statuses_pkey"                                     // ALTER TABLE ONLY workspace_app_statuses ADD CONSTRAINT workspace_app_statuses_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAppsAgentIDSlugIndex                       UniqueConstraint = "workspace_apps_agent_id_slug_idx"                                // ALTER TABLE ONLY workspace_apps ADD CONSTRAINT workspace_apps_agent_id_slug_idx UNIQUE (agent_id, slug);
 	UniqueWorkspaceAppsPkey                                   UniqueConstraint = "workspace_apps_pkey"                                             // ALTER TABLE ONLY workspace_apps ADD CONSTRAINT workspace_apps_pkey PRIMARY KEY (id);
+	UniqueWorkspaceArchivesPkey                               UniqueConstraint = "workspace_archives_pkey"                                         // ALTER TABLE ONLY workspace_archives ADD CONSTRAINT workspace_archives_pkey PRIMARY KEY (id);
 	UniqueWorkspaceBuildParametersWorkspaceBuildIDNameKey     UniqueConstraint = "workspace_build_parameters_workspace_build_id_name_key"          // ALTER TABLE ONLY workspace_build_parameters ADD CONSTRAINT workspace_build_parameters_workspace_build_id_name_key UNIQUE (workspace_build_id, name);
+	UniqueWorkspaceBuildQuotaCostsPkey                        UniqueConstraint = "workspace_build_quota_costs_pkey"                                // ALTER TABLE ONLY workspace_build_quota_costs ADD CONSTRAINT workspace_build_quota_costs_pkey PRIMARY KEY (workspace_build_id, dimension);
 	UniqueWorkspaceBuildsJobIDKey                             UniqueConstraint = "workspace_builds_job_id_key"                                     // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_job_id_key UNIQUE (job_id);
 	UniqueWorkspaceBuildsPkey                                 UniqueConstraint = "workspace_builds_pkey"                                           // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_pkey PRIMARY KEY (id);
 	UniqueWorkspaceBuildsWorkspaceIDBuildNumberKey            UniqueConstraint = "workspace_builds_workspace_id_build_number_key"                  // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
+	UniqueWorkspaceLabelsPkey                                 UniqueConstraint = "workspace_labels_pkey"                                           // ALTER TABLE ONLY workspace_labels ADD CONSTRAINT workspace_labels_pkey PRIMARY KEY (workspace_id, key);
+	UniqueWorkspacePortShareLinksPkey                         UniqueConstraint = "workspace_port_share_links_pkey"                                 // ALTER TABLE ONLY workspace_port_share_links ADD CONSTRAINT workspace_port_share_links_pkey PRIMARY KEY (id);
 	UniqueWorkspaceProxiesPkey                                UniqueConstraint = "workspace_proxies_pkey"                                          // ALTER TABLE ONLY workspace_proxies ADD CONSTRAINT workspace_proxies_pkey PRIMARY KEY (id);
 	UniqueWorkspaceProxiesRegionIDUnique                      UniqueConstraint = "workspace_proxies_region_id_unique"                              // ALTER TABLE ONLY workspace_proxies ADD CONSTRAINT workspace_proxies_region_id_unique UNIQUE (region_id);
+	UniqueWorkspaceProxyTokenRotationsPkey                    UniqueConstraint = "workspace_proxy_token_rotations_pkey"                            // ALTER TABLE ONLY workspace_proxy_token_rotations ADD CONSTRAINT workspace_proxy_token_rotations_pkey PRIMARY KEY (proxy_id);
 	UniqueWorkspaceResourceMetadataName                       UniqueConstraint = "workspace_resource_metadata_name"                                // ALTER TABLE ONLY workspace_resource_metadata ADD CONSTRAINT workspace_resource_metadata_name UNIQUE (workspace_resource_id, key);
 	UniqueWorkspaceResourceMetadataPkey                       UniqueConstraint = "workspace_resource_metadata_pkey"                                // ALTER TABLE ONLY workspace_resource_metadata ADD CONSTRAINT workspace_resource_metadata_pkey PRIMARY KEY (id);
 	UniqueWorkspaceResourcesPkey                              UniqueConstraint = "workspace_resources_pkey"                                        // ALTER TABLE ONLY workspace_resources ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
+	UniqueWorkspaceSchedulePausesPkey                         UniqueConstraint = "workspace_schedule_pauses_pkey"                                  // ALTER TABLE ONLY workspace_schedule_pauses ADD CONSTRAINT workspace_schedule_pauses_pkey PRIMARY KEY (workspace_id);
 	UniqueWorkspacesPkey                                      UniqueConstraint = "workspaces_pkey"                                                 // ALTER TABLE ONLY workspaces ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
 	UniqueIndexAPIKeyName                                     UniqueConstraint = "idx_api_key_name"                                                // CREATE UNIQUE INDEX idx_api_key_name ON api_keys USING btree (user_id, token_name) WHERE (login_type = 'token'::login_type);
 	UniqueIndexCustomRolesNameLower                           UniqueConstraint = "idx_custom_roles_name_lower"                                     // CREATE UNIQUE INDEX idx_custom_roles_name_lower ON custom_roles USING btree (lower(name));
@@ -113,8 +163,10 @@ const (
 	UniqueTemplateUsageStatsStartTimeTemplateIDUserIDIndex    UniqueConstraint = "template_usage_stats_start_time_template_id_user_id_idx"         // CREATE UNIQUE INDEX template_usage_stats_start_time_template_id_user_id_idx ON template_usage_stats USING btree (start_time, template_id, user_id);
 	UniqueTemplatesOrganizationIDNameIndex                    UniqueConstraint = "templates_organization_id_name_idx"                              // CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree (organization_id, lower((name)::text)) WHERE (deleted = false);
 	UniqueUserLinksLinkedIDLoginTypeIndex                     UniqueConstraint = "user_links_linked_id_login_type_idx"                             // CREATE UNIQUE INDEX user_links_linked_id_login_type_idx ON user_links USING btree (linked_id, login_type) WHERE (linked_id <> ''::text);
+	UniqueUserSecretsUserIDEnvNameIndex                       UniqueConstraint = "user_secrets_user_id_env_name_idx"                               // CREATE UNIQUE INDEX user_secrets_user_id_env_name_idx ON user_secrets USING btree (user_id, env_name) WHERE (env_name <> ''::text);
 	UniqueUsersEmailLowerIndex                                UniqueConstraint = "users_email_lower_idx"                                           // CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE (deleted = false);
 	UniqueUsersUsernameLowerIndex                             UniqueConstraint = "users_username_lower_idx"                                        // CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
+	UniqueWorkspaceAdoptionsWorkspaceIDIndex                  UniqueConstraint = "workspace_adoptions_workspace_id_idx"                            // CREATE UNIQUE INDEX workspace_adoptions_workspace_id_idx ON workspace_adoptions USING btree (workspace_id);
 	UniqueWorkspaceAppAuditSessionsUniqueIndex                UniqueConstraint = "workspace_app_audit_sessions_unique_index"                       // CREATE UNIQUE INDEX workspace_app_audit_sessions_unique_index ON workspace_app_audit_sessions USING btree (agent_id, app_id, user_id, ip, user_agent, slug_or_port, status_code);
 	UniqueWorkspaceProxiesLowerNameIndex                      UniqueConstraint = "workspace_proxies_lower_name_idx"                                // CREATE UNIQUE INDEX workspace_proxies_lower_name_idx ON workspace_proxies USING btree (lower(name)) WHERE (deleted = false);
 	UniqueWorkspacesOwnerIDLowerIndex                         UniqueConstraint = "workspaces_owner_id_lower_idx"                                   // CREATE UNIQUE INDEX workspaces_owner_id_lower_idx ON workspaces USING btree (owner_id, lower((name)::text)) WHERE (deleted = false);
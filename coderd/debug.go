@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"slices"
 	"time"
 
@@ -16,6 +17,8 @@ import (
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/diagnostics"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
@@ -47,6 +50,61 @@ func (api *API) debugTailnet(rw http.ResponseWriter, r *http.Request) {
 	api.agentProvider.ServeHTTPDebug(rw, r)
 }
 
+// maxCPUProfileDuration is the longest CPU profile that can be requested.
+// The request blocks while the profile is captured.
+const maxCPUProfileDuration = time.Minute
+
+// @Summary Debug Info Replica Diagnostics
+// @ID debug-info-replica-diagnostics
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Debug
+// @Param cpu_seconds query int false "Duration of the CPU profile in seconds, 0 skips it. Defaults to 10."
+// @Success 200 {object} healthsdk.ReplicaDiagnostics
+// @Router /debug/diagnostics [get]
+func (api *API) debugDiagnostics(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	cpuSeconds := p.Int(vals, 10, "cpu_seconds")
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+	cpuDuration := time.Duration(cpuSeconds) * time.Second
+	if cpuDuration < 0 || cpuDuration > maxCPUProfileDuration {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Query parameter \"cpu_seconds\" must be between 0 and %d.", int(maxCPUProfileDuration.Seconds())),
+		})
+		return
+	}
+
+	res := healthsdk.ReplicaDiagnostics{
+		ReplicaID:  api.ID,
+		CapturedAt: dbtime.Now(),
+		Errors:     []string{},
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		res.Errors = append(res.Errors, fmt.Sprintf("get hostname: %s", err))
+	}
+	res.Hostname = hostname
+	if api.Options.LogBuffer != nil {
+		res.Logs = api.Options.LogBuffer.String()
+	}
+	profiles, errs := diagnostics.CaptureProfiles(ctx, cpuDuration)
+	res.Profiles = profiles
+	for _, err := range errs {
+		res.Errors = append(res.Errors, err.Error())
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
+
 // @Summary Debug Info Deployment Health
 // @ID debug-info-deployment-health
 // @Security CoderSessionToken
@@ -151,6 +209,76 @@ func formatHealthcheck(ctx context.Context, rw http.ResponseWriter, r *http.Requ
 	}
 }
 
+// maxHealthScoresRange is the longest time range of health scores that can
+// be requested at once. Older scores are purged anyway.
+const maxHealthScoresRange = 31 * 24 * time.Hour
+
+// @Summary Get health scores
+// @ID get-health-scores
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Debug
+// @Param start query string false "Start time (RFC3339), defaults to 24 hours before end" format(date-time)
+// @Param end query string false "End time (RFC3339), defaults to now" format(date-time)
+// @Success 200 {array} healthsdk.HealthScore
+// @Router /debug/health/scores [get]
+func (api *API) deploymentHealthScores(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	end := p.Time(vals, dbtime.Now(), "end", time.RFC3339)
+	start := p.Time(vals, end.Add(-24*time.Hour), "start", time.RFC3339)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+	if !start.Before(end) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameter \"start\" must be before \"end\".",
+		})
+		return
+	}
+	if end.Sub(start) > maxHealthScoresRange {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("The time range must not be longer than %d days.", int(maxHealthScoresRange.Hours()/24)),
+		})
+		return
+	}
+
+	rows, err := api.Database.GetHealthScores(ctx, database.GetHealthScoresParams{
+		StartTime: start,
+		EndTime:   end,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching health scores.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	scores := make([]healthsdk.HealthScore, 0, len(rows))
+	for _, row := range rows {
+		scores = append(scores, healthsdk.HealthScore{
+			ReplicaID:          row.ReplicaID,
+			Time:               row.CreatedAt,
+			Score:              int(row.Score),
+			Database:           int(row.DatabaseScore),
+			DatabaseLatencyMS:  row.DatabaseLatencyMS,
+			DERP:               int(row.DERPScore),
+			ProvisionerDaemons: int(row.ProvisionerDaemonsScore),
+			Replicas:           int(row.ReplicasScore),
+			Websocket:          int(row.WebsocketScore),
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, scores)
+}
+
 // @Summary Get health settings
 // @ID get-health-settings
 // @Security CoderSessionToken
@@ -290,6 +418,15 @@ func validateHealthSettings(settings healthsdk.HealthSettings) error {
 // @x-apidocgen {"skip": true}
 func _debugws(http.ResponseWriter, *http.Request) {} //nolint:unused
 
+// @Summary Health monitor websocket test
+// @ID health-monitor-websocket-test
+// @Produce json
+// @Tags Debug
+// @Success 201 {object} codersdk.Response
+// @Router /healthz/ws [get]
+// @x-apidocgen {"skip": true}
+func _healthzws(http.ResponseWriter, *http.Request) {} //nolint:unused
+
 // @Summary Debug DERP traffic
 // @ID debug-derp-traffic
 // @Security CoderSessionToken
@@ -300,6 +437,34 @@ func _debugws(http.ResponseWriter, *http.Request) {} //nolint:unused
 // @x-apidocgen {"skip": true}
 func _debugDERPTraffic(http.ResponseWriter, *http.Request) {} //nolint:unused
 
+// @Summary Debug DERP sessions
+// @ID debug-derp-sessions
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Debug
+// @Success 200 {array} codersdk.DERPSession
+// @Router /debug/derp/sessions [get]
+func (api *API) debugDERPSessions(rw http.ResponseWriter, r *http.Request) {
+	sessions := api.derpServerMetrics.Sessions()
+	res := make([]codersdk.DERPSession, 0, len(sessions))
+	for _, session := range sessions {
+		nodeKey := ""
+		if !session.NodeKey.IsZero() {
+			nodeKey = session.NodeKey.String()
+		}
+		res = append(res, codersdk.DERPSession{
+			ID:            session.ID,
+			NodeKey:       nodeKey,
+			RemoteAddr:    session.RemoteAddr,
+			WebSocket:     session.WebSocket,
+			ConnectedAt:   session.ConnectedAt,
+			BytesReceived: session.BytesReceived,
+			BytesSent:     session.BytesSent,
+		})
+	}
+	httpapi.Write(r.Context(), rw, http.StatusOK, res)
+}
+
 // @Summary Debug expvar
 // @ID debug-expvar
 // @Security CoderSessionToken
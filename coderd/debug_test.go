@@ -9,12 +9,18 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"cdr.dev/slog/sloggers/slogtest"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/diagnostics"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/healthsdk"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -361,6 +367,76 @@ func TestHealthSettings(t *testing.T) {
 	})
 }
 
+func TestHealthScores(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	adminClient, db := coderdtest.NewWithDatabase(t, nil)
+	_ = coderdtest.CreateFirstUser(t, adminClient)
+
+	now := dbtime.Now()
+	for _, createdAt := range []time.Time{now.Add(-48 * time.Hour), now.Add(-time.Hour)} {
+		//nolint:gocritic // Health scores are recorded by the system.
+		err := db.InsertHealthScore(dbauthz.AsSystemRestricted(ctx), database.InsertHealthScoreParams{
+			ReplicaID:         uuid.New(),
+			CreatedAt:         createdAt,
+			Score:             90,
+			DatabaseScore:     100,
+			DatabaseLatencyMS: 5,
+			DERPScore:         50,
+		})
+		require.NoError(t, err)
+	}
+
+	// By default, the scores of the last day are returned.
+	scores, err := healthsdk.New(adminClient).HealthScores(ctx, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, scores, 1)
+	require.Equal(t, 90, scores[0].Score)
+	require.Equal(t, 50, scores[0].DERP)
+	require.EqualValues(t, 5, scores[0].DatabaseLatencyMS)
+
+	scores, err = healthsdk.New(adminClient).HealthScores(ctx, now.Add(-72*time.Hour), now)
+	require.NoError(t, err)
+	require.Len(t, scores, 2)
+
+	_, err = healthsdk.New(adminClient).HealthScores(ctx, now, now.Add(-time.Hour))
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
+
+func TestDebugDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	logBuffer := diagnostics.NewLogBuffer(diagnostics.DefaultLogBufferSize)
+	_, _ = logBuffer.Write([]byte("hello from the replica\n"))
+	adminClient := coderdtest.New(t, &coderdtest.Options{
+		LogBuffer: logBuffer,
+	})
+	admin := coderdtest.CreateFirstUser(t, adminClient)
+
+	diag, err := healthsdk.New(adminClient).DebugDiagnostics(ctx, 0)
+	require.NoError(t, err)
+	require.Empty(t, diag.Errors)
+	require.NotEqual(t, uuid.Nil, diag.ReplicaID)
+	require.NotEmpty(t, diag.Profiles[diagnostics.ProfileGoroutine])
+	require.NotEmpty(t, diag.Profiles[diagnostics.ProfileHeap])
+	require.NotContains(t, diag.Profiles, diagnostics.ProfileCPU)
+	require.Equal(t, "hello from the replica\n", diag.Logs)
+
+	_, err = healthsdk.New(adminClient).DebugDiagnostics(ctx, time.Hour)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	memberClient, _ := coderdtest.CreateAnotherUser(t, adminClient, admin.OrganizationID)
+	_, err = healthsdk.New(memberClient).DebugDiagnostics(ctx, 0)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+}
+
 func TestDebugWebsocket(t *testing.T) {
 	t.Parallel()
 
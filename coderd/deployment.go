@@ -3,6 +3,7 @@ package coderd
 import (
 	"net/http"
 
+	"github.com/coder/coder/v2/coderd/deploymentsettings"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
@@ -14,7 +15,9 @@ import (
 // @Security CoderSessionToken
 // @Produce json
 // @Tags General
+// @Param If-None-Match header string false "Only return the resource if it no longer has this ETag"
 // @Success 200 {object} codersdk.DeploymentConfig
+// @Success 304
 // @Router /deployment/config [get]
 func (api *API) deploymentValues(rw http.ResponseWriter, r *http.Request) {
 	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentConfig) {
@@ -28,8 +31,8 @@ func (api *API) deploymentValues(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(
-		r.Context(), rw, http.StatusOK,
+	httpapi.WriteCacheable(
+		r.Context(), rw, r,
 		codersdk.DeploymentConfig{
 			Values:  values,
 			Options: api.DeploymentOptions,
@@ -37,6 +40,111 @@ func (api *API) deploymentValues(rw http.ResponseWriter, r *http.Request) {
 	)
 }
 
+// @Summary Reload deployment config
+// @Description Reads the configuration file of the replica that serves the
+// @Description request again. Changed hot-reloadable options are applied on
+// @Description all replicas, other changed options require a restart.
+// @ID reload-deployment-config
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 202 {object} codersdk.DeploymentConfigReload
+// @Router /deployment/config/reload [post]
+func (api *API) postDeploymentConfigReload(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, policy.ActionUpdate, rbac.ResourceDeploymentConfig) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if api.Options.ReloadDeploymentValues == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Reloading the deployment config is not supported by this deployment.",
+		})
+		return
+	}
+
+	vals, err := api.Options.ReloadDeploymentValues()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to read the deployment config.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	reload, err := api.ConfigReloader.Reload(ctx, vals)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reloading deployment config.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusAccepted, reload)
+}
+
+// @Summary Get latest deployment config reload
+// @Description Returns which options changed in the latest reload of the
+// @Description deployment config and which replicas applied them.
+// @ID get-latest-deployment-config-reload
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.DeploymentConfigReload
+// @Router /deployment/config/reload [get]
+func (api *API) deploymentConfigReload(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentConfig) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	reload, ok := api.ConfigReloader.Latest()
+	if !ok {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "The deployment config has not been reloaded since the replica started.",
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, reload)
+}
+
+// @Summary Get deployment settings drift
+// @Description Returns the settings whose current values differ from the
+// @Description settings file the deployment was started with.
+// @ID get-deployment-settings-drift
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.DeploymentSettingsDrift
+// @Router /deployment/settings/drift [get]
+func (api *API) deploymentSettingsDrift(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentConfig) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if api.Options.DeploymentSettings == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "No settings file is configured.",
+			Detail:  "Start the server with --settings-file to manage deployment settings declaratively.",
+		})
+		return
+	}
+
+	drift, err := deploymentsettings.Drift(ctx, api.Database, *api.Options.DeploymentSettings)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error comparing deployment settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.DeploymentSettingsDrift{
+		Path:  api.DeploymentValues.SettingsFile.Value(),
+		Drift: drift,
+	})
+}
+
 // @Summary Get deployment stats
 // @ID get-deployment-stats
 // @Security CoderSessionToken
@@ -2,12 +2,14 @@ package coderd_test
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -62,3 +64,33 @@ func TestDeploymentStats(t *testing.T) {
 		return err == nil
 	}, testutil.IntervalMedium), "failed to get deployment stats in time")
 }
+
+func TestDeploymentValuesNotModified(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	res, err := client.Request(ctx, http.MethodGet, "/api/v2/deployment/config", nil)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	etag := res.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	res, err = client.Request(ctx, http.MethodGet, "/api/v2/deployment/config", nil, func(r *http.Request) {
+		r.Header.Set("If-None-Match", etag)
+	})
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusNotModified, res.StatusCode)
+
+	// Clients with a response cache see the cached config.
+	client.ResponseCache = codersdk.NewResponseCache(0)
+	first, err := client.DeploymentConfig(ctx)
+	require.NoError(t, err)
+	second, err := client.DeploymentConfig(ctx)
+	require.NoError(t, err)
+	require.Equal(t, first.Values.AccessURL.String(), second.Values.AccessURL.String())
+	require.Equal(t, 1, client.ResponseCache.Len())
+}
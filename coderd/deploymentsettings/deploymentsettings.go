@@ -0,0 +1,210 @@
+// Package deploymentsettings applies a declarative manifest of
+// runtime-editable deployment settings and reports drift between the manifest
+// and the current settings.
+package deploymentsettings
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"os"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// ReadFile reads a settings manifest. Unknown settings are rejected, so typos
+// do not silently leave a setting unmanaged.
+func ReadFile(path string) (codersdk.DeploymentSettingsManifest, error) {
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		return codersdk.DeploymentSettingsManifest{}, xerrors.Errorf("read settings file: %w", err)
+	}
+	var manifest codersdk.DeploymentSettingsManifest
+	dec := yaml.NewDecoder(bytes.NewReader(byt))
+	dec.KnownFields(true)
+	err = dec.Decode(&manifest)
+	if err != nil && !xerrors.Is(err, io.EOF) {
+		return codersdk.DeploymentSettingsManifest{}, xerrors.Errorf("decode settings file: %w", err)
+	}
+	return manifest, nil
+}
+
+// setting is a single setting managed by the manifest.
+type setting struct {
+	name  string
+	value any
+	get   func(ctx context.Context, db database.Store) (any, error)
+	set   func(ctx context.Context, db database.Store) error
+}
+
+func managedSettings(m codersdk.DeploymentSettingsManifest) []setting {
+	var settings []setting
+	if a := m.Appearance; a != nil {
+		if a.ApplicationName != nil {
+			settings = append(settings, setting{
+				name:  "appearance.application_name",
+				value: *a.ApplicationName,
+				get: func(ctx context.Context, db database.Store) (any, error) {
+					return ignoreNoRows(db.GetApplicationName(ctx))
+				},
+				set: func(ctx context.Context, db database.Store) error {
+					return db.UpsertApplicationName(ctx, *a.ApplicationName)
+				},
+			})
+		}
+		if a.LogoURL != nil {
+			settings = append(settings, setting{
+				name:  "appearance.logo_url",
+				value: *a.LogoURL,
+				get: func(ctx context.Context, db database.Store) (any, error) {
+					return ignoreNoRows(db.GetLogoURL(ctx))
+				},
+				set: func(ctx context.Context, db database.Store) error {
+					return db.UpsertLogoURL(ctx, *a.LogoURL)
+				},
+			})
+		}
+		if a.AnnouncementBanners != nil {
+			settings = append(settings, setting{
+				name:  "appearance.announcement_banners",
+				value: a.AnnouncementBanners,
+				get: func(ctx context.Context, db database.Store) (any, error) {
+					raw, err := ignoreNoRows(db.GetAnnouncementBanners(ctx))
+					if err != nil {
+						return nil, err
+					}
+					banners := []codersdk.BannerConfig{}
+					if raw != "" {
+						err = json.Unmarshal([]byte(raw), &banners)
+						if err != nil {
+							return nil, xerrors.Errorf("decode announcement banners: %w", err)
+						}
+					}
+					return banners, nil
+				},
+				set: func(ctx context.Context, db database.Store) error {
+					raw, err := json.Marshal(a.AnnouncementBanners)
+					if err != nil {
+						return err
+					}
+					return db.UpsertAnnouncementBanners(ctx, string(raw))
+				},
+			})
+		}
+	}
+	if n := m.Notifications; n != nil && n.NotifierPaused != nil {
+		settings = append(settings, setting{
+			name:  "notifications.notifier_paused",
+			value: *n.NotifierPaused,
+			get: func(ctx context.Context, db database.Store) (any, error) {
+				var current codersdk.NotificationsSettings
+				err := getJSON(ctx, db.GetNotificationsSettings, &current)
+				return current.NotifierPaused, err
+			},
+			set: func(ctx context.Context, db database.Store) error {
+				return setJSON(ctx, db.UpsertNotificationsSettings, codersdk.NotificationsSettings{
+					NotifierPaused: *n.NotifierPaused,
+				})
+			},
+		})
+	}
+	if p := m.Prebuilds; p != nil && p.ReconciliationPaused != nil {
+		settings = append(settings, setting{
+			name:  "prebuilds.reconciliation_paused",
+			value: *p.ReconciliationPaused,
+			get: func(ctx context.Context, db database.Store) (any, error) {
+				var current codersdk.PrebuildsSettings
+				err := getJSON(ctx, db.GetPrebuildsSettings, &current)
+				return current.ReconciliationPaused, err
+			},
+			set: func(ctx context.Context, db database.Store) error {
+				return setJSON(ctx, db.UpsertPrebuildsSettings, codersdk.PrebuildsSettings{
+					ReconciliationPaused: *p.ReconciliationPaused,
+				})
+			},
+		})
+	}
+	return settings
+}
+
+// Drift returns the settings whose current values differ from the manifest.
+func Drift(ctx context.Context, db database.Store, m codersdk.DeploymentSettingsManifest) ([]codersdk.DeploymentSettingDrift, error) {
+	drift := []codersdk.DeploymentSettingDrift{}
+	for _, s := range managedSettings(m) {
+		current, err := s.get(ctx, db)
+		if err != nil {
+			return nil, xerrors.Errorf("get %s: %w", s.name, err)
+		}
+		want, err := json.Marshal(s.value)
+		if err != nil {
+			return nil, xerrors.Errorf("encode %s: %w", s.name, err)
+		}
+		got, err := json.Marshal(current)
+		if err != nil {
+			return nil, xerrors.Errorf("encode current %s: %w", s.name, err)
+		}
+		if !bytes.Equal(want, got) {
+			drift = append(drift, codersdk.DeploymentSettingDrift{
+				Setting:  s.name,
+				Manifest: string(want),
+				Current:  string(got),
+			})
+		}
+	}
+	return drift, nil
+}
+
+// Apply updates the settings that drifted from the manifest. Settings that
+// already match are not written, so applying the same manifest again is a
+// no-op. It returns the names of the updated settings.
+func Apply(ctx context.Context, db database.Store, m codersdk.DeploymentSettingsManifest) ([]string, error) {
+	drift, err := Drift(ctx, db, m)
+	if err != nil {
+		return nil, err
+	}
+	drifted := make(map[string]bool, len(drift))
+	for _, d := range drift {
+		drifted[d.Setting] = true
+	}
+	var applied []string
+	for _, s := range managedSettings(m) {
+		if !drifted[s.name] {
+			continue
+		}
+		err := s.set(ctx, db)
+		if err != nil {
+			return applied, xerrors.Errorf("set %s: %w", s.name, err)
+		}
+		applied = append(applied, s.name)
+	}
+	return applied, nil
+}
+
+func ignoreNoRows(value string, err error) (string, error) {
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return value, err
+}
+
+func getJSON(ctx context.Context, get func(context.Context) (string, error), v any) error {
+	raw, err := get(ctx)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(raw), v)
+}
+
+func setJSON(ctx context.Context, set func(context.Context, string) error, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return set(ctx, string(raw))
+}
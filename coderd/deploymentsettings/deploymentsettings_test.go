@@ -0,0 +1,100 @@
+package deploymentsettings_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/coderd/deploymentsettings"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestReadFile(t *testing.T) {
+	t.Parallel()
+
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "settings.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		manifest, err := deploymentsettings.ReadFile(write(t, `
+appearance:
+  application_name: Acme
+  announcement_banners: []
+notifications:
+  notifier_paused: false
+`))
+		require.NoError(t, err)
+		require.Equal(t, "Acme", *manifest.Appearance.ApplicationName)
+		require.Nil(t, manifest.Appearance.LogoURL)
+		// An empty list is managed and removes all banners.
+		require.NotNil(t, manifest.Appearance.AnnouncementBanners)
+		require.False(t, *manifest.Notifications.NotifierPaused)
+		require.Nil(t, manifest.Prebuilds)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+		manifest, err := deploymentsettings.ReadFile(write(t, ""))
+		require.NoError(t, err)
+		require.Equal(t, codersdk.DeploymentSettingsManifest{}, manifest)
+	})
+
+	t.Run("UnknownSetting", func(t *testing.T) {
+		t.Parallel()
+		_, err := deploymentsettings.ReadFile(write(t, `
+appearance:
+  application_nam: Acme
+`))
+		require.ErrorContains(t, err, "application_nam")
+	})
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	db := dbmock.NewMockStore(gomock.NewController(t))
+
+	name := "Acme"
+	paused := true
+	manifest := codersdk.DeploymentSettingsManifest{
+		Appearance: &codersdk.DeploymentSettingsAppearance{
+			ApplicationName: &name,
+			AnnouncementBanners: []codersdk.BannerConfig{
+				{Enabled: true, Message: "Maintenance tonight"},
+			},
+		},
+		Notifications: &codersdk.DeploymentSettingsNotifications{
+			NotifierPaused: &paused,
+		},
+	}
+
+	// The application name was never set and the notifier is running, but
+	// the banners already match.
+	db.EXPECT().GetApplicationName(gomock.Any()).Return("", sql.ErrNoRows).AnyTimes()
+	db.EXPECT().GetAnnouncementBanners(gomock.Any()).Return(`[{"enabled":true,"message":"Maintenance tonight"}]`, nil).AnyTimes()
+	db.EXPECT().GetNotificationsSettings(gomock.Any()).Return(`{}`, nil).AnyTimes()
+
+	drift, err := deploymentsettings.Drift(ctx, db, manifest)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.DeploymentSettingDrift{
+		{Setting: "appearance.application_name", Manifest: `"Acme"`, Current: `""`},
+		{Setting: "notifications.notifier_paused", Manifest: `true`, Current: `false`},
+	}, drift)
+
+	db.EXPECT().UpsertApplicationName(gomock.Any(), "Acme").Return(nil)
+	db.EXPECT().UpsertNotificationsSettings(gomock.Any(), `{"notifier_paused":true}`).Return(nil)
+	applied, err := deploymentsettings.Apply(ctx, db, manifest)
+	require.NoError(t, err)
+	require.Equal(t, []string{"appearance.application_name", "notifications.notifier_paused"}, applied)
+}
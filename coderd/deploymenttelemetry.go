@@ -0,0 +1,43 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get deployment telemetry
+// @Description Returns the telemetry payloads that were recently sent by the
+// @Description replica that serves the request, exactly as they were sent.
+// @ID get-deployment-telemetry
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.TelemetryReport
+// @Router /deployment/telemetry [get]
+func (api *API) deploymentTelemetry(rw http.ResponseWriter, r *http.Request) {
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentConfig) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	categories := codersdk.TelemetryCategories()
+	if selected := api.DeploymentValues.Telemetry.Categories.Value(); len(selected) > 0 {
+		categories = slice.StringEnums[codersdk.TelemetryCategory](selected)
+	}
+	payloads := api.Telemetry.RecentPayloads()
+	if payloads == nil {
+		payloads = []codersdk.TelemetryPayload{}
+	}
+
+	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.TelemetryReport{
+		Enabled:    api.Telemetry.Enabled(),
+		URL:        api.DeploymentValues.Telemetry.URL.String(),
+		Categories: categories,
+		Payloads:   payloads,
+	})
+}
@@ -0,0 +1,37 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestDeploymentTelemetry(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.Telemetry.Categories = []string{string(codersdk.TelemetryCategoryUsage)}
+	client := coderdtest.New(t, &coderdtest.Options{
+		DeploymentValues: dv,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	report, err := client.DeploymentTelemetry(ctx)
+	require.NoError(t, err)
+	require.False(t, report.Enabled)
+	require.Equal(t, []codersdk.TelemetryCategory{codersdk.TelemetryCategoryUsage}, report.Categories)
+	require.Empty(t, report.Payloads)
+
+	_, err = member.DeploymentTelemetry(ctx)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+}
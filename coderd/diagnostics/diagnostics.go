@@ -0,0 +1,69 @@
+// Package diagnostics captures runtime diagnostics of a running replica,
+// such as pprof profiles and recent logs, for support bundles.
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// Names of the profiles captured by CaptureProfiles.
+const (
+	ProfileGoroutine = "goroutine"
+	ProfileHeap      = "heap"
+	ProfileCPU       = "cpu"
+)
+
+// CaptureProfiles captures the goroutine and heap profiles of the process
+// and, unless cpuDuration is zero, a CPU profile over cpuDuration. Profiles
+// are in the gzipped protobuf format read by `go tool pprof`. Profiles that
+// cannot be captured are left out and their errors are returned.
+func CaptureProfiles(ctx context.Context, cpuDuration time.Duration) (map[string][]byte, []error) {
+	var (
+		profiles = map[string][]byte{}
+		errs     []error
+	)
+	for _, name := range []string{ProfileGoroutine, ProfileHeap} {
+		var buf bytes.Buffer
+		err := pprof.Lookup(name).WriteTo(&buf, 0)
+		if err != nil {
+			errs = append(errs, xerrors.Errorf("write %s profile: %w", name, err))
+			continue
+		}
+		profiles[name] = buf.Bytes()
+	}
+
+	if cpuDuration > 0 {
+		cpu, err := captureCPUProfile(ctx, cpuDuration)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			profiles[ProfileCPU] = cpu
+		}
+	}
+	return profiles, errs
+}
+
+func captureCPUProfile(ctx context.Context, duration time.Duration) ([]byte, error) {
+	var buf bytes.Buffer
+	// This fails if a CPU profile is already running, for example one
+	// requested through the pprof server.
+	err := pprof.StartCPUProfile(&buf)
+	if err != nil {
+		return nil, xerrors.Errorf("start cpu profile: %w", err)
+	}
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		pprof.StopCPUProfile()
+		return nil, xerrors.Errorf("capture cpu profile: %w", ctx.Err())
+	case <-timer.C:
+	}
+	pprof.StopCPUProfile()
+	return buf.Bytes(), nil
+}
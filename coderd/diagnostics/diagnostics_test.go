@@ -0,0 +1,35 @@
+package diagnostics_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/diagnostics"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestLogBuffer(t *testing.T) {
+	t.Parallel()
+
+	buf := diagnostics.NewLogBuffer(16)
+	_, err := buf.Write([]byte("first\n"))
+	require.NoError(t, err)
+	require.Equal(t, "first\n", buf.String())
+
+	// Once the buffer wraps around, the partial line is dropped.
+	_, err = buf.Write([]byte("second\nthird\n"))
+	require.NoError(t, err)
+	require.Equal(t, "second\nthird\n", buf.String())
+}
+
+func TestCaptureProfiles(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	profiles, errs := diagnostics.CaptureProfiles(ctx, 0)
+	require.Empty(t, errs)
+	require.NotEmpty(t, profiles[diagnostics.ProfileGoroutine])
+	require.NotEmpty(t, profiles[diagnostics.ProfileHeap])
+	require.NotContains(t, profiles, diagnostics.ProfileCPU)
+}
@@ -0,0 +1,50 @@
+package diagnostics
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/armon/circbuf"
+)
+
+// DefaultLogBufferSize is the number of bytes of recent logs kept by a
+// replica for support bundles.
+const DefaultLogBufferSize = 1 << 20
+
+// LogBuffer keeps the most recent logs of a replica in memory, so they can
+// be included in support bundles without access to the log files. It is
+// safe for concurrent use.
+type LogBuffer struct {
+	mu  sync.Mutex
+	buf *circbuf.Buffer
+}
+
+// NewLogBuffer creates a buffer that holds the last size bytes written to it.
+func NewLogBuffer(size int64) *LogBuffer {
+	buf, err := circbuf.NewBuffer(size)
+	if err != nil {
+		// circbuf only fails for sizes that are not positive.
+		panic("developer error: invalid log buffer size")
+	}
+	return &LogBuffer{buf: buf}
+}
+
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// String returns the buffered logs. Once the buffer has wrapped around, the
+// partially overwritten first line is dropped.
+func (b *LogBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	logs := b.buf.Bytes()
+	if b.buf.TotalWritten() > b.buf.Size() {
+		if i := bytes.IndexByte(logs, '\n'); i >= 0 {
+			logs = logs[i+1:]
+		}
+	}
+	return string(logs)
+}
@@ -0,0 +1,33 @@
+package coderd
+
+import (
+	"time"
+
+	"cdr.dev/slog"
+)
+
+// Drain makes /healthz fail, so that load balancers stop sending requests to
+// the replica, and requests the server to shut down once the timeout expires.
+// The server is expected to be started again by its supervisor.
+func (api *API) Drain(timeout time.Duration) {
+	if api.draining.Swap(true) {
+		return
+	}
+	api.Logger.Info(api.ctx, "draining replica before shutting down", slog.F("timeout", timeout))
+	go func() {
+		timer := api.Clock.NewTimer(timeout, "coderd", "drain")
+		defer timer.Stop()
+		select {
+		case <-api.ctx.Done():
+			return
+		case <-timer.C:
+		}
+		close(api.shutdownRequested)
+	}()
+}
+
+// ShutdownRequested is closed once the replica has drained and should shut
+// down.
+func (api *API) ShutdownRequested() <-chan struct{} {
+	return api.shutdownRequested
+}
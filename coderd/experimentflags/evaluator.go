@@ -0,0 +1,322 @@
+// Package experimentflags evaluates experiments at runtime. Experiments are
+// enabled at startup with --experiments, and can be enabled or disabled for
+// the whole deployment, or for organizations and groups, with flags stored in
+// the database. Flag changes are applied by all replicas without a restart.
+package experimentflags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// eventFlagsUpdated is published when the flags are updated. Replicas reload
+// the flags from the database when they receive it.
+const eventFlagsUpdated = "experiment_flags_updated"
+
+type Options struct {
+	Logger   slog.Logger
+	Database database.Store
+	Pubsub   pubsub.Pubsub
+	// Startup are the experiments enabled with --experiments. They apply
+	// when no flag does.
+	Startup codersdk.Experiments
+}
+
+// Target is what targeted flags are matched against.
+type Target struct {
+	OrganizationIDs []uuid.UUID
+	GroupIDs        []uuid.UUID
+}
+
+// TargetFromSubject returns the organizations and groups the subject is a
+// member of.
+func TargetFromSubject(subject rbac.Subject) Target {
+	var target Target
+	if subject.Roles != nil {
+		for _, role := range subject.Roles.Names() {
+			if role.Name == rbac.RoleOrgMember() && role.OrganizationID != uuid.Nil {
+				target.OrganizationIDs = append(target.OrganizationIDs, role.OrganizationID)
+			}
+		}
+	}
+	for _, group := range subject.Groups {
+		id, err := uuid.Parse(group)
+		if err != nil {
+			continue
+		}
+		target.GroupIDs = append(target.GroupIDs, id)
+	}
+	return target
+}
+
+// Evaluator evaluates experiments against the startup experiments and the
+// flags of the deployment.
+type Evaluator struct {
+	opts  Options
+	flags atomic.Pointer[codersdk.ExperimentFlags]
+
+	mu        sync.Mutex
+	onChanges []func(deployment codersdk.Experiments)
+
+	unsubscribe func()
+}
+
+// New loads the flags of the deployment and reloads them whenever they are
+// updated on any replica, until the evaluator is closed.
+func New(ctx context.Context, opts Options) (*Evaluator, error) {
+	e := &Evaluator{opts: opts}
+	e.flags.Store(&codersdk.ExperimentFlags{})
+	unsubscribe, err := opts.Pubsub.Subscribe(eventFlagsUpdated, func(ctx context.Context, _ []byte) {
+		if err := e.reload(ctx); err != nil {
+			e.opts.Logger.Error(ctx, "reload experiment flags", slog.Error(err))
+		}
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("subscribe to experiment flag updates: %w", err)
+	}
+	e.unsubscribe = unsubscribe
+	if err := e.reload(ctx); err != nil {
+		// The startup experiments still apply, so this is not fatal.
+		e.opts.Logger.Error(ctx, "load experiment flags", slog.Error(err))
+	}
+	return e, nil
+}
+
+// OnChange calls fn with the experiments enabled for the whole deployment
+// whenever the flags are reloaded.
+func (e *Evaluator) OnChange(fn func(deployment codersdk.Experiments)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.onChanges = append(e.onChanges, fn)
+}
+
+// Flags returns the current flags.
+func (e *Evaluator) Flags() codersdk.ExperimentFlags {
+	flags := e.flags.Load()
+	if flags.Flags == nil {
+		return codersdk.ExperimentFlags{Flags: []codersdk.ExperimentFlag{}}
+	}
+	return codersdk.ExperimentFlags{Flags: slices.Clone(flags.Flags)}
+}
+
+// Update validates and stores the flags, and notifies all replicas. The
+// context must carry an actor that may update the deployment config.
+func (e *Evaluator) Update(ctx context.Context, flags codersdk.ExperimentFlags) error {
+	if flags.Flags == nil {
+		flags.Flags = []codersdk.ExperimentFlag{}
+	}
+	if errs := Validate(flags); len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	raw, err := json.Marshal(flags)
+	if err != nil {
+		return xerrors.Errorf("encode experiment flags: %w", err)
+	}
+	err = e.opts.Database.UpsertExperimentFlags(ctx, string(raw))
+	if err != nil {
+		return xerrors.Errorf("store experiment flags: %w", err)
+	}
+	e.set(flags)
+	err = e.opts.Pubsub.Publish(eventFlagsUpdated, nil)
+	if err != nil {
+		// The flags are stored, other replicas pick them up on restart.
+		e.opts.Logger.Warn(ctx, "publish experiment flags update", slog.Error(err))
+	}
+	return nil
+}
+
+// Enabled returns whether the experiment is enabled for the whole deployment,
+// i.e. ignoring targeted flags. It is used where there is no actor to target,
+// and satisfies the interface of httpmw.RequireExperiment.
+func (e *Evaluator) Enabled(ex codersdk.Experiment) bool {
+	return e.Evaluate(ex, Target{}).Enabled
+}
+
+// Deployment returns the experiments enabled for the whole deployment.
+func (e *Evaluator) Deployment() codersdk.Experiments {
+	return e.Experiments(Target{})
+}
+
+// Experiments returns the experiments enabled for the target.
+func (e *Evaluator) Experiments(target Target) codersdk.Experiments {
+	exps := codersdk.Experiments{}
+	for _, ev := range e.EvaluateAll(target) {
+		if ev.Enabled {
+			exps = append(exps, ev.Experiment)
+		}
+	}
+	return exps
+}
+
+// EnabledFor returns whether the experiment is enabled for the actor of the
+// context. Without an actor, only flags without targets apply.
+func (e *Evaluator) EnabledFor(ctx context.Context, ex codersdk.Experiment) bool {
+	return e.Evaluate(ex, targetFromContext(ctx)).Enabled
+}
+
+// ExperimentsFor returns the experiments enabled for the actor of the
+// context.
+func (e *Evaluator) ExperimentsFor(ctx context.Context) codersdk.Experiments {
+	return e.Experiments(targetFromContext(ctx))
+}
+
+func targetFromContext(ctx context.Context) Target {
+	actor, ok := dbauthz.ActorFromContext(ctx)
+	if !ok {
+		return Target{}
+	}
+	return TargetFromSubject(actor)
+}
+
+// EvaluateAll evaluates the known experiments, and any other experiment
+// enabled at startup, for the target.
+func (e *Evaluator) EvaluateAll(target Target) []codersdk.ExperimentEvaluation {
+	exps := slices.Clone(codersdk.ExperimentsKnown)
+	for _, ex := range e.opts.Startup {
+		if !slices.Contains(exps, ex) {
+			exps = append(exps, ex)
+		}
+	}
+	evs := make([]codersdk.ExperimentEvaluation, 0, len(exps))
+	for _, ex := range exps {
+		evs = append(evs, e.Evaluate(ex, target))
+	}
+	return evs
+}
+
+// Evaluate returns whether the experiment is enabled for the target, and why.
+// The first targeted flag matching the target applies, then the flag without
+// targets, then the startup experiments.
+func (e *Evaluator) Evaluate(ex codersdk.Experiment, target Target) codersdk.ExperimentEvaluation {
+	flags := e.flags.Load()
+	for _, flag := range flags.Flags {
+		if flag.Experiment != ex || !flag.Targeted() {
+			continue
+		}
+		for _, id := range flag.GroupIDs {
+			if slices.Contains(target.GroupIDs, id) {
+				return codersdk.ExperimentEvaluation{
+					Experiment: ex,
+					Enabled:    flag.Enabled,
+					Source:     codersdk.ExperimentEvaluationSourceTargetedFlag,
+					GroupID:    &id,
+				}
+			}
+		}
+		for _, id := range flag.OrganizationIDs {
+			if slices.Contains(target.OrganizationIDs, id) {
+				return codersdk.ExperimentEvaluation{
+					Experiment:     ex,
+					Enabled:        flag.Enabled,
+					Source:         codersdk.ExperimentEvaluationSourceTargetedFlag,
+					OrganizationID: &id,
+				}
+			}
+		}
+	}
+	for _, flag := range flags.Flags {
+		if flag.Experiment == ex && !flag.Targeted() {
+			return codersdk.ExperimentEvaluation{
+				Experiment: ex,
+				Enabled:    flag.Enabled,
+				Source:     codersdk.ExperimentEvaluationSourceFlag,
+			}
+		}
+	}
+	if e.opts.Startup.Enabled(ex) {
+		return codersdk.ExperimentEvaluation{
+			Experiment: ex,
+			Enabled:    true,
+			Source:     codersdk.ExperimentEvaluationSourceStartup,
+		}
+	}
+	return codersdk.ExperimentEvaluation{
+		Experiment: ex,
+		Enabled:    false,
+		Source:     codersdk.ExperimentEvaluationSourceDefault,
+	}
+}
+
+// Close stops reloading the flags.
+func (e *Evaluator) Close() error {
+	e.unsubscribe()
+	return nil
+}
+
+func (e *Evaluator) reload(ctx context.Context) error {
+	raw, err := e.opts.Database.GetExperimentFlags(ctx)
+	if err != nil {
+		return xerrors.Errorf("get experiment flags: %w", err)
+	}
+	var flags codersdk.ExperimentFlags
+	err = json.Unmarshal([]byte(raw), &flags)
+	if err != nil {
+		return xerrors.Errorf("decode experiment flags: %w", err)
+	}
+	e.set(flags)
+	return nil
+}
+
+func (e *Evaluator) set(flags codersdk.ExperimentFlags) {
+	e.flags.Store(&flags)
+
+	e.mu.Lock()
+	onChanges := slices.Clone(e.onChanges)
+	e.mu.Unlock()
+	deployment := e.Deployment()
+	for _, fn := range onChanges {
+		fn(deployment)
+	}
+}
+
+// ValidationError is returned by Update when the flags are invalid.
+type ValidationError struct {
+	Errors []codersdk.ValidationError
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid experiment flags: %s", e.Errors[0].Detail)
+}
+
+// Validate returns the problems with the flags. Flags must name known
+// experiments, and an experiment may only have one flag without targets.
+func Validate(flags codersdk.ExperimentFlags) []codersdk.ValidationError {
+	var errs []codersdk.ValidationError
+	untargeted := make(map[codersdk.Experiment]bool)
+	for i, flag := range flags.Flags {
+		field := fmt.Sprintf("flags[%d]", i)
+		if !slices.Contains(codersdk.ExperimentsKnown, flag.Experiment) {
+			errs = append(errs, codersdk.ValidationError{
+				Field:  field + ".experiment",
+				Detail: fmt.Sprintf("unknown experiment %q", flag.Experiment),
+			})
+			continue
+		}
+		if flag.Targeted() {
+			continue
+		}
+		if untargeted[flag.Experiment] {
+			errs = append(errs, codersdk.ValidationError{
+				Field:  field,
+				Detail: fmt.Sprintf("experiment %q has more than one flag without organizations or groups", flag.Experiment),
+			})
+		}
+		untargeted[flag.Experiment] = true
+	}
+	return errs
+}
@@ -0,0 +1,161 @@
+package experimentflags_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/experimentflags"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	groupID := uuid.New()
+	otherOrgID := uuid.New()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	db := dbmock.NewMockStore(gomock.NewController(t))
+	db.EXPECT().GetExperimentFlags(gomock.Any()).Return(`{"flags":[
+		{"experiment":"example","enabled":false,"organization_ids":["`+otherOrgID.String()+`"]},
+		{"experiment":"example","enabled":true,"group_ids":["`+groupID.String()+`"]},
+		{"experiment":"web-push","enabled":true},
+		{"experiment":"oauth2","enabled":false}
+	]}`, nil)
+	e, err := experimentflags.New(ctx, experimentflags.Options{
+		Logger:   slogtest.Make(t, nil),
+		Database: db,
+		Pubsub:   pubsub.NewInMemory(),
+		Startup:  codersdk.Experiments{codersdk.ExperimentOAuth2, codersdk.ExperimentNotifications},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = e.Close() })
+
+	member := experimentflags.Target{OrganizationIDs: []uuid.UUID{orgID}, GroupIDs: []uuid.UUID{groupID}}
+
+	ev := e.Evaluate(codersdk.ExperimentExample, member)
+	require.True(t, ev.Enabled)
+	require.Equal(t, codersdk.ExperimentEvaluationSourceTargetedFlag, ev.Source)
+	require.Equal(t, &groupID, ev.GroupID)
+
+	ev = e.Evaluate(codersdk.ExperimentExample, experimentflags.Target{OrganizationIDs: []uuid.UUID{otherOrgID}, GroupIDs: []uuid.UUID{groupID}})
+	require.False(t, ev.Enabled, "the first matching targeted flag applies")
+	require.Equal(t, &otherOrgID, ev.OrganizationID)
+
+	ev = e.Evaluate(codersdk.ExperimentExample, experimentflags.Target{})
+	require.False(t, ev.Enabled)
+	require.Equal(t, codersdk.ExperimentEvaluationSourceDefault, ev.Source)
+
+	ev = e.Evaluate(codersdk.ExperimentWebPush, member)
+	require.True(t, ev.Enabled)
+	require.Equal(t, codersdk.ExperimentEvaluationSourceFlag, ev.Source)
+
+	ev = e.Evaluate(codersdk.ExperimentOAuth2, member)
+	require.False(t, ev.Enabled, "flags override startup experiments")
+	require.Equal(t, codersdk.ExperimentEvaluationSourceFlag, ev.Source)
+
+	ev = e.Evaluate(codersdk.ExperimentNotifications, member)
+	require.True(t, ev.Enabled)
+	require.Equal(t, codersdk.ExperimentEvaluationSourceStartup, ev.Source)
+
+	require.ElementsMatch(t, codersdk.Experiments{codersdk.ExperimentWebPush, codersdk.ExperimentNotifications}, e.Deployment())
+	require.ElementsMatch(t, codersdk.Experiments{codersdk.ExperimentExample, codersdk.ExperimentWebPush, codersdk.ExperimentNotifications}, e.Experiments(member))
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	logger := slogtest.Make(t, nil)
+	ps := pubsub.NewInMemory()
+
+	var stored string
+	db := dbmock.NewMockStore(gomock.NewController(t))
+	db.EXPECT().GetExperimentFlags(gomock.Any()).DoAndReturn(func(context.Context) (string, error) {
+		if stored == "" {
+			return "{}", nil
+		}
+		return stored, nil
+	}).AnyTimes()
+	db.EXPECT().UpsertExperimentFlags(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, value string) error {
+		stored = value
+		return nil
+	}).AnyTimes()
+
+	newReplica := func() *experimentflags.Evaluator {
+		e, err := experimentflags.New(ctx, experimentflags.Options{
+			Logger:   logger,
+			Database: db,
+			Pubsub:   ps,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = e.Close() })
+		return e
+	}
+	first := newReplica()
+	second := newReplica()
+
+	changed := make(chan codersdk.Experiments, 1)
+	second.OnChange(func(deployment codersdk.Experiments) {
+		changed <- deployment
+	})
+
+	err := first.Update(ctx, codersdk.ExperimentFlags{Flags: []codersdk.ExperimentFlag{
+		{Experiment: codersdk.ExperimentExample, Enabled: true},
+	}})
+	require.NoError(t, err)
+	require.True(t, first.Enabled(codersdk.ExperimentExample))
+
+	// The other replica reloads the flags.
+	deployment := testutil.TryReceive(ctx, t, changed)
+	require.Equal(t, codersdk.Experiments{codersdk.ExperimentExample}, deployment)
+	require.True(t, second.Enabled(codersdk.ExperimentExample))
+
+	err = first.Update(ctx, codersdk.ExperimentFlags{Flags: []codersdk.ExperimentFlag{
+		{Experiment: "unknown", Enabled: true},
+	}})
+	var validationErr *experimentflags.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, "flags[0].experiment", validationErr.Errors[0].Field)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	errs := experimentflags.Validate(codersdk.ExperimentFlags{Flags: []codersdk.ExperimentFlag{
+		{Experiment: codersdk.ExperimentExample, Enabled: true},
+		{Experiment: codersdk.ExperimentExample, Enabled: true, OrganizationIDs: []uuid.UUID{uuid.New()}},
+		{Experiment: codersdk.ExperimentExample, Enabled: false},
+	}})
+	require.Len(t, errs, 1)
+	require.Equal(t, "flags[2]", errs[0].Field)
+}
+
+func TestTargetFromSubject(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	groupID := uuid.New()
+	target := experimentflags.TargetFromSubject(rbac.Subject{
+		ID: uuid.NewString(),
+		Roles: rbac.RoleIdentifiers{
+			rbac.RoleMember(),
+			rbac.ScopedRoleOrgMember(orgID),
+			rbac.ScopedRoleOrgAdmin(orgID),
+		},
+		Groups: []string{groupID.String()},
+	})
+	require.Equal(t, []uuid.UUID{orgID}, target.OrganizationIDs)
+	require.Equal(t, []uuid.UUID{groupID}, target.GroupIDs)
+}
@@ -1,13 +1,23 @@
 package coderd
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/experimentflags"
 	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/codersdk"
 )
 
 // @Summary Get enabled experiments
+// @Description Returns the experiments enabled for the authenticated user,
+// @Description including experiments enabled for their organizations and
+// @Description groups.
 // @ID get-enabled-experiments
 // @Security CoderSessionToken
 // @Produce json
@@ -16,7 +26,8 @@ import (
 // @Router /experiments [get]
 func (api *API) handleExperimentsGet(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	httpapi.Write(ctx, rw, http.StatusOK, api.Experiments)
+	target := experimentflags.TargetFromSubject(httpmw.UserAuthorization(ctx))
+	httpapi.Write(ctx, rw, http.StatusOK, api.ExperimentFlags.Experiments(target))
 }
 
 // @Summary Get safe experiments
@@ -32,3 +43,87 @@ func handleExperimentsAvailable(rw http.ResponseWriter, r *http.Request) {
 		Safe: codersdk.ExperimentsSafe,
 	})
 }
+
+// @Summary Get experiment evaluation
+// @Description Returns whether each experiment is enabled for the
+// @Description authenticated user, and which flag or setting decided it.
+// @ID get-experiment-evaluation
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.ExperimentEvaluationResponse
+// @Router /experiments/evaluation [get]
+func (api *API) handleExperimentsEvaluation(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+	target := experimentflags.TargetFromSubject(httpmw.UserAuthorization(ctx))
+	resp := codersdk.ExperimentEvaluationResponse{
+		UserID:          apiKey.UserID,
+		OrganizationIDs: target.OrganizationIDs,
+		GroupIDs:        target.GroupIDs,
+		Evaluations:     api.ExperimentFlags.EvaluateAll(target),
+	}
+	if resp.OrganizationIDs == nil {
+		resp.OrganizationIDs = []uuid.UUID{}
+	}
+	if resp.GroupIDs == nil {
+		resp.GroupIDs = []uuid.UUID{}
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Get experiment flags
+// @ID get-experiment-flags
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.ExperimentFlags
+// @Router /experiments/flags [get]
+func (api *API) experimentFlags(rw http.ResponseWriter, r *http.Request) {
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentConfig) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	httpapi.Write(r.Context(), rw, http.StatusOK, api.ExperimentFlags.Flags())
+}
+
+// @Summary Update experiment flags
+// @Description Replaces the experiment flags of the deployment. The flags
+// @Description apply to all replicas without a restart.
+// @ID update-experiment-flags
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags General
+// @Param request body codersdk.ExperimentFlags true "Experiment flags"
+// @Success 200 {object} codersdk.ExperimentFlags
+// @Router /experiments/flags [put]
+func (api *API) putExperimentFlags(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, policy.ActionUpdate, rbac.ResourceDeploymentConfig) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var flags codersdk.ExperimentFlags
+	if !httpapi.Read(ctx, rw, r, &flags) {
+		return
+	}
+	err := api.ExperimentFlags.Update(ctx, flags)
+	if err != nil {
+		var validationErr *experimentflags.ValidationError
+		if errors.As(err, &validationErr) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     "Invalid experiment flags.",
+				Validations: validationErr.Errors,
+			})
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating experiment flags.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, api.ExperimentFlags.Flags())
+}
@@ -2,8 +2,10 @@ package coderd_test
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
@@ -133,4 +135,66 @@ func Test_Experiments(t *testing.T) {
 		require.NotNil(t, experiments)
 		require.ElementsMatch(t, codersdk.ExperimentsSafe, experiments.Safe)
 	})
+	t.Run("flags", func(t *testing.T) {
+		t.Parallel()
+		cfg := coderdtest.DeploymentValues(t)
+		cfg.Experiments = []string{string(codersdk.ExperimentNotifications)}
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues: cfg,
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		// Members cannot manage flags.
+		_, err := memberClient.ExperimentFlags(ctx)
+		var sdkError *codersdk.Error
+		require.ErrorAs(t, err, &sdkError)
+		require.Equal(t, http.StatusForbidden, sdkError.StatusCode())
+
+		// Flags must name known experiments.
+		_, err = client.UpdateExperimentFlags(ctx, codersdk.ExperimentFlags{Flags: []codersdk.ExperimentFlag{
+			{Experiment: "unknown", Enabled: true},
+		}})
+		require.ErrorAs(t, err, &sdkError)
+		require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+
+		// Disable an experiment enabled at startup, and enable another one
+		// for the organization only.
+		flags, err := client.UpdateExperimentFlags(ctx, codersdk.ExperimentFlags{Flags: []codersdk.ExperimentFlag{
+			{Experiment: codersdk.ExperimentNotifications, Enabled: false},
+			{Experiment: codersdk.ExperimentExample, Enabled: true, OrganizationIDs: []uuid.UUID{owner.OrganizationID}},
+		}})
+		require.NoError(t, err)
+		require.Len(t, flags.Flags, 2)
+
+		got, err := client.ExperimentFlags(ctx)
+		require.NoError(t, err)
+		require.Equal(t, flags, got)
+
+		experiments, err := memberClient.Experiments(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, codersdk.Experiments{codersdk.ExperimentExample}, experiments)
+
+		evaluation, err := memberClient.ExperimentEvaluation(ctx)
+		require.NoError(t, err)
+		require.Equal(t, member.ID, evaluation.UserID)
+		require.Contains(t, evaluation.OrganizationIDs, owner.OrganizationID)
+		for _, ev := range evaluation.Evaluations {
+			switch ev.Experiment {
+			case codersdk.ExperimentExample:
+				require.True(t, ev.Enabled)
+				require.Equal(t, codersdk.ExperimentEvaluationSourceTargetedFlag, ev.Source)
+				require.Equal(t, &owner.OrganizationID, ev.OrganizationID)
+			case codersdk.ExperimentNotifications:
+				require.False(t, ev.Enabled)
+				require.Equal(t, codersdk.ExperimentEvaluationSourceFlag, ev.Source)
+			default:
+				require.False(t, ev.Enabled)
+				require.Equal(t, codersdk.ExperimentEvaluationSourceDefault, ev.Source)
+			}
+		}
+	})
 }
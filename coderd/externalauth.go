@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/sqlc-dev/pqtype"
 	"golang.org/x/sync/errgroup"
@@ -404,11 +405,14 @@ func ExternalAuthConfig(cfg *externalauth.Config) codersdk.ExternalAuthLinkProvi
 	}
 }
 
+// uriFromURL returns the path and query of the URL, so that redirects stay
+// on the deployment. Leading slashes are collapsed, otherwise a path such as
+// "//example.com" would be followed as a protocol-relative URL.
 func uriFromURL(u string) string {
 	uri, err := url.Parse(u)
 	if err != nil {
 		return "/"
 	}
 
-	return uri.RequestURI()
+	return "/" + strings.TrimLeft(uri.RequestURI(), "/")
 }
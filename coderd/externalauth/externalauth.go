@@ -451,7 +451,10 @@ func (c *DeviceAuth) ExchangeDeviceCode(ctx context.Context, deviceCode string)
 		return nil, err
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
+	// GitHub responds with a 200 while authorization is pending, but the
+	// spec and other providers (e.g. GitLab) respond with a 400.
+	// See: https://datatracker.ietf.org/doc/html/rfc8628#section-3.5
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest {
 		return nil, codersdk.ReadBodyAsError(resp)
 	}
 	var body ExchangeDeviceCodeResponse
@@ -462,6 +465,9 @@ func (c *DeviceAuth) ExchangeDeviceCode(ctx context.Context, deviceCode string)
 	if body.Error != "" {
 		return nil, xerrors.New(body.Error)
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("status %d: unexpected device code exchange response", resp.StatusCode)
+	}
 	// If expiresIn is 0, then the token never expires.
 	expires := dbtime.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
 	if body.ExpiresIn == 0 {
@@ -494,7 +500,7 @@ func (c *DeviceAuth) formatDeviceCodeURL() (string, error) {
 	}
 	cod.RawQuery = url.Values{
 		"client_id": {c.ClientID},
-		"scope":     c.Scopes,
+		"scope":     {strings.Join(c.Scopes, " ")},
 	}.Encode()
 	return cod.String(), nil
 }
@@ -740,6 +746,8 @@ func gitlabDefaults(config *codersdk.ExternalAuthConfig) codersdk.ExternalAuthCo
 		DisplayIcon: "/icon/gitlab.svg",
 		Regex:       `^(https?://)?gitlab\.com(/.*)?$`,
 		Scopes:      []string{"write_repository"},
+		// GitLab 17.2+ supports the device authorization grant.
+		DeviceCodeURL: "https://gitlab.com/oauth/authorize_device",
 	}
 
 	if config.AuthURL == "" || config.AuthURL == cloud.AuthURL {
@@ -755,13 +763,14 @@ func gitlabDefaults(config *codersdk.ExternalAuthConfig) codersdk.ExternalAuthCo
 
 	// At this point, assume it is self-hosted and use the AuthURL
 	return codersdk.ExternalAuthConfig{
-		DisplayName: cloud.DisplayName,
-		Scopes:      cloud.Scopes,
-		DisplayIcon: cloud.DisplayIcon,
-		AuthURL:     au.ResolveReference(&url.URL{Path: "/oauth/authorize"}).String(),
-		TokenURL:    au.ResolveReference(&url.URL{Path: "/oauth/token"}).String(),
-		ValidateURL: au.ResolveReference(&url.URL{Path: "/oauth/token/info"}).String(),
-		Regex:       fmt.Sprintf(`^(https?://)?%s(/.*)?$`, strings.ReplaceAll(au.Host, ".", `\.`)),
+		DisplayName:   cloud.DisplayName,
+		Scopes:        cloud.Scopes,
+		DisplayIcon:   cloud.DisplayIcon,
+		AuthURL:       au.ResolveReference(&url.URL{Path: "/oauth/authorize"}).String(),
+		TokenURL:      au.ResolveReference(&url.URL{Path: "/oauth/token"}).String(),
+		ValidateURL:   au.ResolveReference(&url.URL{Path: "/oauth/token/info"}).String(),
+		DeviceCodeURL: au.ResolveReference(&url.URL{Path: "/oauth/authorize_device"}).String(),
+		Regex:         fmt.Sprintf(`^(https?://)?%s(/.*)?$`, strings.ReplaceAll(au.Host, ".", `\.`)),
 	}
 }
 
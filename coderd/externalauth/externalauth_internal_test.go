@@ -14,15 +14,16 @@ func TestGitlabDefaults(t *testing.T) {
 	// The default cloud setup. Copying this here as hard coded
 	// values.
 	cloud := codersdk.ExternalAuthConfig{
-		Type:        string(codersdk.EnhancedExternalAuthProviderGitLab),
-		ID:          string(codersdk.EnhancedExternalAuthProviderGitLab),
-		AuthURL:     "https://gitlab.com/oauth/authorize",
-		TokenURL:    "https://gitlab.com/oauth/token",
-		ValidateURL: "https://gitlab.com/oauth/token/info",
-		DisplayName: "GitLab",
-		DisplayIcon: "/icon/gitlab.svg",
-		Regex:       `^(https?://)?gitlab\.com(/.*)?$`,
-		Scopes:      []string{"write_repository"},
+		Type:          string(codersdk.EnhancedExternalAuthProviderGitLab),
+		ID:            string(codersdk.EnhancedExternalAuthProviderGitLab),
+		AuthURL:       "https://gitlab.com/oauth/authorize",
+		TokenURL:      "https://gitlab.com/oauth/token",
+		ValidateURL:   "https://gitlab.com/oauth/token/info",
+		DisplayName:   "GitLab",
+		DisplayIcon:   "/icon/gitlab.svg",
+		Regex:         `^(https?://)?gitlab\.com(/.*)?$`,
+		Scopes:        []string{"write_repository"},
+		DeviceCodeURL: "https://gitlab.com/oauth/authorize_device",
 	}
 
 	tests := []struct {
@@ -79,6 +80,7 @@ func TestGitlabDefaults(t *testing.T) {
 				config.AuthURL = "https://gitlab.company.org/oauth/authorize?foo=bar"
 				config.ValidateURL = "https://gitlab.company.org/oauth/token/info"
 				config.TokenURL = "https://gitlab.company.org/oauth/token"
+				config.DeviceCodeURL = "https://gitlab.company.org/oauth/authorize_device"
 				config.Regex = `^(https?://)?gitlab\.company\.org(/.*)?$`
 			},
 		},
@@ -97,6 +99,7 @@ func TestGitlabDefaults(t *testing.T) {
 				config.AuthURL = "https://auth.com/auth"
 				config.ValidateURL = "https://validate.com/validate"
 				config.TokenURL = "https://token.com/token"
+				config.DeviceCodeURL = "https://auth.com/oauth/authorize_device"
 				config.Regex = `random`
 			},
 		},
@@ -410,6 +410,30 @@ func TestExchangeWithClientSecret(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestExchangeDeviceCode(t *testing.T) {
+	t.Parallel()
+
+	// GitLab follows the spec and responds with a 400 while the
+	// authorization is pending.
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:device_code", r.URL.Query().Get("grant_type"))
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(rw).Encode(externalauth.ExchangeDeviceCodeResponse{
+			Error:            "authorization_pending",
+			ErrorDescription: "The authorization request is still pending",
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	device := &externalauth.DeviceAuth{
+		ClientID: "id",
+		TokenURL: srv.URL,
+	}
+	_, err := device.ExchangeDeviceCode(testutil.Context(t, testutil.WaitShort), "code")
+	require.EqualError(t, err, "authorization_pending")
+}
+
 func TestConvertYAML(t *testing.T) {
 	t.Parallel()
 
@@ -456,7 +480,8 @@ func TestConvertYAML(t *testing.T) {
 	}, {
 		Name: "NoDeviceURL",
 		Input: []codersdk.ExternalAuthConfig{{
-			Type:         string(codersdk.EnhancedExternalAuthProviderGitLab),
+			// Bitbucket does not support the device flow.
+			Type:         string(codersdk.EnhancedExternalAuthProviderBitBucketCloud),
 			ClientID:     "example",
 			ClientSecret: "example",
 			DeviceFlow:   true,
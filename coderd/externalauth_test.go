@@ -27,6 +27,7 @@ import (
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
 	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -472,6 +473,53 @@ func TestExternalAuthCallback(t *testing.T) {
 		require.NoError(t, err)
 		require.True(t, strings.HasSuffix(token.URL, fmt.Sprintf("/external-auth/%s", "github")), token.URL)
 	})
+	t.Run("PrefersTemplateProvider", func(t *testing.T) {
+		t.Parallel()
+		// Both providers match the URL, so the one required by the
+		// template is used.
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			ExternalAuthConfigs: []*externalauth.Config{{
+				InstrumentedOAuth2Config: &testutil.OAuth2Config{},
+				ID:                       "gitlab-read",
+				Regex:                    regexp.MustCompile(`gitlab\.com`),
+				Type:                     codersdk.EnhancedExternalAuthProviderGitLab.String(),
+			}, {
+				InstrumentedOAuth2Config: &testutil.OAuth2Config{},
+				ID:                       "gitlab",
+				Regex:                    regexp.MustCompile(`gitlab\.com`),
+				Type:                     codersdk.EnhancedExternalAuthProviderGitLab.String(),
+			}},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionPlan: []*proto.Response{{
+				Type: &proto.Response_Plan{
+					Plan: &proto.PlanComplete{
+						ExternalAuthProviders: []*proto.ExternalAuthProviderResource{{
+							Id:       "gitlab-read",
+							Optional: true,
+						}},
+					},
+				},
+			}},
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		token, err := agentClient.ExternalAuth(context.Background(), agentsdk.ExternalAuthRequest{
+			Match: "gitlab.com/asd/asd",
+		})
+		require.NoError(t, err)
+		require.True(t, strings.HasSuffix(token.URL, "/external-auth/gitlab-read"), token.URL)
+	})
 	t.Run("UnauthorizedCallback", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{
@@ -0,0 +1,195 @@
+package files
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/objectstore"
+	"github.com/coder/quartz"
+)
+
+const (
+	// objectCollectInterval is how often unreferenced files are removed from
+	// object storage.
+	objectCollectInterval = time.Hour
+	// unreferencedFileGracePeriod is how long an uploaded file is kept before
+	// it is used by a template version or workspace build. Uploads are
+	// followed immediately by the request that uses them, so files older than
+	// this are abandoned.
+	unreferencedFileGracePeriod = 7 * 24 * time.Hour
+)
+
+// WithObjectStore returns a database.Store that keeps the contents of new
+// files in objects instead of the database. Objects are addressed by the hash
+// of the file, so files with the same contents share a single object. Files
+// inserted before object storage was configured are still read from the
+// database.
+func WithObjectStore(db database.Store, objects objectstore.Store) database.Store {
+	return &objectStoreDB{
+		Store:   db,
+		objects: objects,
+	}
+}
+
+type objectStoreDB struct {
+	database.Store
+	objects objectstore.Store
+}
+
+func (db *objectStoreDB) InTx(function func(database.Store) error, txOpts *database.TxOptions) error {
+	return db.Store.InTx(func(s database.Store) error {
+		return function(&objectStoreDB{
+			Store:   s,
+			objects: db.objects,
+		})
+	}, txOpts)
+}
+
+func (db *objectStoreDB) GetFileByID(ctx context.Context, id uuid.UUID) (database.File, error) {
+	file, err := db.Store.GetFileByID(ctx, id)
+	if err != nil {
+		return database.File{}, err
+	}
+	return db.loadData(ctx, file)
+}
+
+func (db *objectStoreDB) GetFileByHashAndCreator(ctx context.Context, arg database.GetFileByHashAndCreatorParams) (database.File, error) {
+	file, err := db.Store.GetFileByHashAndCreator(ctx, arg)
+	if err != nil {
+		return database.File{}, err
+	}
+	return db.loadData(ctx, file)
+}
+
+func (db *objectStoreDB) InsertFile(ctx context.Context, arg database.InsertFileParams) (database.File, error) {
+	data := arg.Data
+	arg.Data = []byte{}
+	arg.External = true
+
+	var file database.File
+	err := db.InTx(func(tx database.Store) error {
+		// Hold the lock of the hash until the file is committed, so that
+		// garbage collection does not delete the object it shares.
+		err := tx.AcquireLock(ctx, fileObjectLockID(arg.Hash))
+		if err != nil {
+			return xerrors.Errorf("acquire file object lock: %w", err)
+		}
+		count, err := tx.CountExternalFilesByHash(ctx, arg.Hash)
+		if err != nil {
+			return xerrors.Errorf("count files with hash: %w", err)
+		}
+		if count == 0 {
+			_, err = db.objects.Put(ctx, arg.Hash, bytes.NewReader(data))
+			if err != nil {
+				return xerrors.Errorf("put file object: %w", err)
+			}
+		}
+		file, err = tx.InsertFile(ctx, arg)
+		return err
+	}, nil)
+	if err != nil {
+		return database.File{}, err
+	}
+	file.Data = data
+	return file, nil
+}
+
+func (db *objectStoreDB) loadData(ctx context.Context, file database.File) (database.File, error) {
+	if !file.External {
+		return file, nil
+	}
+	r, err := db.objects.Get(ctx, file.Hash)
+	if err != nil {
+		return database.File{}, xerrors.Errorf("get file object: %w", err)
+	}
+	defer r.Close()
+	file.Data, err = io.ReadAll(r)
+	if err != nil {
+		return database.File{}, xerrors.Errorf("read file object: %w", err)
+	}
+	return file, nil
+}
+
+// fileObjectLockID returns the advisory lock that guards the object of a
+// hash.
+func fileObjectLockID(hash string) int64 {
+	return database.GenLockID("file-object:" + hash)
+}
+
+// CollectObjects periodically deletes files in object storage that are no
+// longer used by a template version or workspace build, and deletes their
+// objects once no file shares them.
+// It is the caller's responsibility to call Close on the returned instance.
+func CollectObjects(ctx context.Context, logger slog.Logger, db database.Store, objects objectstore.Store, clk quartz.Clock) io.Closer {
+	closed := make(chan struct{})
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // The system collects unreferenced files without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	ticker := clk.NewTicker(objectCollectInterval, "files", "collectObjects")
+	go func() {
+		defer close(closed)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case tick := <-ticker.C:
+				if err := collectObjects(ctx, db, objects, tick); err != nil && ctx.Err() == nil {
+					logger.Error(ctx, "failed to collect unreferenced file objects", slog.Error(err))
+				}
+			}
+		}
+	}()
+	return &objectCollector{
+		cancel: cancelFunc,
+		closed: closed,
+	}
+}
+
+func collectObjects(ctx context.Context, db database.Store, objects objectstore.Store, now time.Time) error {
+	hashes, err := db.DeleteUnreferencedExternalFiles(ctx, now.Add(-unreferencedFileGracePeriod))
+	if err != nil {
+		return xerrors.Errorf("delete unreferenced files: %w", err)
+	}
+	for _, hash := range hashes {
+		err := db.InTx(func(tx database.Store) error {
+			err := tx.AcquireLock(ctx, fileObjectLockID(hash))
+			if err != nil {
+				return xerrors.Errorf("acquire file object lock: %w", err)
+			}
+			count, err := tx.CountExternalFilesByHash(ctx, hash)
+			if err != nil {
+				return xerrors.Errorf("count files with hash: %w", err)
+			}
+			if count > 0 {
+				return nil
+			}
+			return objects.Delete(ctx, hash)
+		}, nil)
+		if err != nil {
+			return xerrors.Errorf("delete object %q: %w", hash, err)
+		}
+	}
+	return nil
+}
+
+type objectCollector struct {
+	cancel context.CancelFunc
+	closed chan struct{}
+}
+
+func (c *objectCollector) Close() error {
+	c.cancel()
+	<-c.closed
+	return nil
+}
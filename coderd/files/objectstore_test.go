@@ -0,0 +1,119 @@
+package files_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/files"
+	"github.com/coder/coder/v2/coderd/objectstore"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestWithObjectStore(t *testing.T) {
+	t.Parallel()
+
+	rawDB, _ := dbtestutil.NewDB(t)
+	dir := t.TempDir()
+	objects, err := objectstore.NewDirStore(dir)
+	require.NoError(t, err)
+	db := files.WithObjectStore(rawDB, objects)
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	data := []byte("template source")
+	first := dbgen.File(t, db, database.File{Hash: hashOf(data), Data: data})
+	second := dbgen.File(t, db, database.File{Hash: hashOf(data), Data: data})
+	require.Equal(t, data, first.Data)
+
+	// The contents are stored once, under the hash, and not in the
+	// database.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, hashOf(data), entries[0].Name())
+	raw, err := rawDB.GetFileByID(ctx, first.ID)
+	require.NoError(t, err)
+	require.True(t, raw.External)
+	require.Empty(t, raw.Data)
+
+	for _, id := range []uuid.UUID{first.ID, second.ID} {
+		file, err := db.GetFileByID(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, data, file.Data)
+	}
+	file, err := db.GetFileByHashAndCreator(ctx, database.GetFileByHashAndCreatorParams{
+		Hash:      first.Hash,
+		CreatedBy: first.CreatedBy,
+	})
+	require.NoError(t, err)
+	require.Equal(t, data, file.Data)
+
+	// Files stored before object storage was configured are read from the
+	// database.
+	legacy := dbgen.File(t, rawDB, database.File{Hash: hashOf([]byte("legacy")), Data: []byte("legacy")})
+	file, err = db.GetFileByID(ctx, legacy.ID)
+	require.NoError(t, err)
+	require.Equal(t, []byte("legacy"), file.Data)
+}
+
+func TestCollectObjects(t *testing.T) {
+	t.Parallel()
+
+	rawDB, _ := dbtestutil.NewDB(t)
+	dir := t.TempDir()
+	objects, err := objectstore.NewDirStore(dir)
+	require.NoError(t, err)
+	db := files.WithObjectStore(rawDB, objects)
+	ctx := testutil.Context(t, testutil.WaitLong)
+	clk := quartz.NewMock(t)
+	clk.Set(dbtime.Now())
+	old := clk.Now().Add(-8 * 24 * time.Hour)
+
+	// An abandoned upload, an upload that is used by a provisioner job, an
+	// upload that is still recent, and an abandoned upload that shares its
+	// contents with a used upload.
+	abandoned := dbgen.File(t, db, database.File{Hash: hashOf([]byte("abandoned")), Data: []byte("abandoned"), CreatedAt: old})
+	used := dbgen.File(t, db, database.File{Hash: hashOf([]byte("used")), Data: []byte("used"), CreatedAt: old})
+	_ = dbgen.ProvisionerJob(t, rawDB, nil, database.ProvisionerJob{FileID: used.ID})
+	recent := dbgen.File(t, db, database.File{Hash: hashOf([]byte("recent")), Data: []byte("recent")})
+	shared := dbgen.File(t, db, database.File{Hash: used.Hash, Data: []byte("used"), CreatedAt: old})
+
+	trap := clk.Trap().NewTicker("files", "collectObjects")
+	defer trap.Close()
+	collector := files.CollectObjects(ctx, slogtest.Make(t, nil), db, objects, clk)
+	defer collector.Close()
+	trap.MustWait(ctx).MustRelease(ctx)
+	clk.Advance(time.Hour).MustWait(ctx)
+
+	testutil.Eventually(ctx, t, func(context.Context) bool {
+		_, err := os.Stat(filepath.Join(dir, abandoned.Hash))
+		return os.IsNotExist(err)
+	}, testutil.IntervalFast)
+
+	_, err = rawDB.GetFileByID(ctx, shared.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	for _, file := range []database.File{used, recent} {
+		got, err := db.GetFileByID(ctx, file.ID)
+		require.NoError(t, err)
+		require.Equal(t, file.Data, got.Data)
+	}
+}
+
+func hashOf(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
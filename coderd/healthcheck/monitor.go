@@ -0,0 +1,169 @@
+package healthcheck
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk/healthsdk"
+	"github.com/coder/quartz"
+)
+
+// replicaStaleAfter is how long a replica may go without updating its row
+// before it is no longer considered running. Replicas update every few
+// seconds.
+const replicaStaleAfter = time.Minute
+
+var (
+	scoreDesc = prometheus.NewDesc(
+		"coderd_health_score",
+		"The score of the last healthcheck from 0 (failing) to 100 (healthy) by component.",
+		[]string{"component"}, nil,
+	)
+	databaseLatencyDesc = prometheus.NewDesc(
+		"coderd_health_database_latency_seconds",
+		"The database latency measured by the last healthcheck.",
+		nil, nil,
+	)
+)
+
+type MonitorOptions struct {
+	// ReplicaID identifies the replica that runs the healthchecks.
+	ReplicaID uuid.UUID
+	Database  database.Store
+	Logger    slog.Logger
+	Clock     quartz.Clock
+	// Interval is the time between two healthchecks.
+	Interval time.Duration
+	// Check runs a healthcheck.
+	Check func(ctx context.Context) *healthsdk.HealthcheckReport
+}
+
+// Monitor runs healthchecks periodically and stores their scores, so the
+// health of the deployment can be queried over time. The score of the last
+// healthcheck is exported as Prometheus metrics.
+type Monitor struct {
+	opts   MonitorOptions
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu    sync.Mutex
+	score *healthsdk.HealthScore
+}
+
+// NewMonitor starts a monitor. The first healthcheck runs after one interval.
+// It is the caller's responsibility to call Close on the returned monitor.
+func NewMonitor(ctx context.Context, opts MonitorOptions) *Monitor {
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+	//nolint:gocritic // The monitor records the health of the deployment without user input.
+	ctx, cancel := context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	m := &Monitor{
+		opts:   opts,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go m.run(ctx)
+	return m
+}
+
+func (m *Monitor) run(ctx context.Context) {
+	defer close(m.done)
+
+	ticker := m.opts.Clock.NewTicker(m.opts.Interval, "healthcheck", "monitor")
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := m.check(ctx)
+		if err != nil && ctx.Err() == nil {
+			m.opts.Logger.Warn(ctx, "failed to record health score", slog.Error(err))
+		}
+	}
+}
+
+func (m *Monitor) check(ctx context.Context) error {
+	report := m.opts.Check(ctx)
+	if report == nil {
+		return xerrors.New("nil report from healthcheck")
+	}
+	replicas, err := m.opts.Database.GetReplicasUpdatedAfter(ctx, m.opts.Clock.Now().Add(-replicaStaleAfter))
+	if err != nil {
+		return xerrors.Errorf("get replicas: %w", err)
+	}
+
+	score := Score(report, replicas)
+	score.ReplicaID = m.opts.ReplicaID
+	score.Time = dbtime.Time(m.opts.Clock.Now())
+	m.mu.Lock()
+	m.score = &score
+	m.mu.Unlock()
+
+	err = m.opts.Database.InsertHealthScore(ctx, database.InsertHealthScoreParams{
+		ReplicaID:               score.ReplicaID,
+		CreatedAt:               score.Time,
+		Score:                   dbScore(score.Score),
+		DatabaseScore:           dbScore(score.Database),
+		DatabaseLatencyMS:       score.DatabaseLatencyMS,
+		DERPScore:               dbScore(score.DERP),
+		ProvisionerDaemonsScore: dbScore(score.ProvisionerDaemons),
+		ReplicasScore:           dbScore(score.Replicas),
+		WebsocketScore:          dbScore(score.Websocket),
+	})
+	if err != nil {
+		return xerrors.Errorf("insert health score: %w", err)
+	}
+	return nil
+}
+
+// dbScore converts a score for the database.
+func dbScore(score int) int32 {
+	return int32(score) //nolint:gosec // Scores range from 0 to 100.
+}
+
+// Close stops the monitor and waits for a running healthcheck to finish.
+func (m *Monitor) Close() error {
+	m.cancel()
+	<-m.done
+	return nil
+}
+
+func (*Monitor) Describe(descs chan<- *prometheus.Desc) {
+	descs <- scoreDesc
+	descs <- databaseLatencyDesc
+}
+
+func (m *Monitor) Collect(metrics chan<- prometheus.Metric) {
+	m.mu.Lock()
+	score := m.score
+	m.mu.Unlock()
+	// Nothing is exported before the first healthcheck, so a restart does
+	// not look like an outage.
+	if score == nil {
+		return
+	}
+
+	for component, value := range map[string]int{
+		"overall":             score.Score,
+		"database":            score.Database,
+		"derp":                score.DERP,
+		"provisioner_daemons": score.ProvisionerDaemons,
+		"replicas":            score.Replicas,
+		"websocket":           score.Websocket,
+	} {
+		metrics <- prometheus.MustNewConstMetric(scoreDesc, prometheus.GaugeValue, float64(value), component)
+	}
+	metrics <- prometheus.MustNewConstMetric(databaseLatencyDesc, prometheus.GaugeValue, (time.Duration(score.DatabaseLatencyMS) * time.Millisecond).Seconds())
+}
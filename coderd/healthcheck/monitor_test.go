@@ -0,0 +1,86 @@
+package healthcheck_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/coder/coder/v2/coderd/coderdtest/promhelp"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/coderd/healthcheck"
+	"github.com/coder/coder/v2/coderd/healthcheck/health"
+	"github.com/coder/coder/v2/codersdk/healthsdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestMonitor(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().NewTicker("healthcheck", "monitor")
+	defer trap.Close()
+
+	replicaID := uuid.New()
+	inserted := make(chan database.InsertHealthScoreParams, 1)
+	db := dbmock.NewMockStore(gomock.NewController(t))
+	db.EXPECT().GetReplicasUpdatedAfter(gomock.Any(), gomock.Any()).Return([]database.Replica{
+		{ID: replicaID},
+		{ID: uuid.New(), Error: "failed to dial peer"},
+	}, nil)
+	db.EXPECT().InsertHealthScore(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, arg database.InsertHealthScoreParams) error {
+		inserted <- arg
+		return nil
+	})
+
+	monitor := healthcheck.NewMonitor(ctx, healthcheck.MonitorOptions{
+		ReplicaID: replicaID,
+		Database:  db,
+		Logger:    testutil.Logger(t),
+		Clock:     mClock,
+		Interval:  time.Minute,
+		Check: func(context.Context) *healthsdk.HealthcheckReport {
+			report := &healthsdk.HealthcheckReport{}
+			report.Database.Severity = health.SeverityOK
+			report.Database.LatencyMS = 12
+			report.DERP.Severity = health.SeverityWarning
+			report.ProvisionerDaemons.Severity = health.SeverityOK
+			report.Websocket.Severity = health.SeverityError
+			return report
+		},
+	})
+	defer monitor.Close()
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(monitor)
+
+	trap.MustWait(ctx).MustRelease(ctx)
+	// Nothing is exported before the first healthcheck.
+	metrics, err := reg.Gather()
+	require.NoError(t, err)
+	require.Empty(t, metrics)
+
+	mClock.Advance(time.Minute).MustWait(ctx)
+	arg := testutil.TryReceive(ctx, t, inserted)
+	require.Equal(t, database.InsertHealthScoreParams{
+		ReplicaID:               replicaID,
+		CreatedAt:               arg.CreatedAt,
+		Score:                   60,
+		DatabaseScore:           100,
+		DatabaseLatencyMS:       12,
+		DERPScore:               50,
+		ProvisionerDaemonsScore: 100,
+		ReplicasScore:           50,
+		WebsocketScore:          0,
+	}, arg)
+
+	require.Equal(t, 60, promhelp.GaugeValue(t, reg, "coderd_health_score", prometheus.Labels{"component": "overall"}))
+	require.Equal(t, 50, promhelp.GaugeValue(t, reg, "coderd_health_score", prometheus.Labels{"component": "derp"}))
+	require.Equal(t, 0, promhelp.GaugeValue(t, reg, "coderd_health_score", prometheus.Labels{"component": "websocket"}))
+}
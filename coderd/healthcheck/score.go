@@ -0,0 +1,57 @@
+package healthcheck
+
+import (
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/healthcheck/health"
+	"github.com/coder/coder/v2/codersdk/healthsdk"
+)
+
+const (
+	scoreHealthy = 100
+	scoreWarning = 50
+	scoreFailing = 0
+)
+
+// Score converts a report into scores from 0 (failing) to 100 (healthy), so
+// the health of the deployment can be tracked over time. replicas are the
+// replicas that are currently running; replicas that report an error, for
+// example because they cannot reach their peers, lower the score.
+func Score(report *healthsdk.HealthcheckReport, replicas []database.Replica) healthsdk.HealthScore {
+	score := healthsdk.HealthScore{
+		Time:               report.Time,
+		Database:           severityScore(report.Database.Severity),
+		DatabaseLatencyMS:  report.Database.LatencyMS,
+		DERP:               severityScore(report.DERP.Severity),
+		ProvisionerDaemons: severityScore(report.ProvisionerDaemons.Severity),
+		Replicas:           replicasScore(replicas),
+		Websocket:          severityScore(report.Websocket.Severity),
+	}
+	score.Score = (score.Database + score.DERP + score.ProvisionerDaemons + score.Replicas + score.Websocket) / 5
+	return score
+}
+
+func severityScore(severity health.Severity) int {
+	switch severity {
+	case health.SeverityOK:
+		return scoreHealthy
+	case health.SeverityWarning:
+		return scoreWarning
+	default:
+		return scoreFailing
+	}
+}
+
+// replicasScore is the share of replicas without errors. Deployments without
+// replicas, for example because they run a single coderd, are healthy.
+func replicasScore(replicas []database.Replica) int {
+	if len(replicas) == 0 {
+		return scoreHealthy
+	}
+	healthy := 0
+	for _, replica := range replicas {
+		if replica.Error == "" {
+			healthy++
+		}
+	}
+	return healthy * scoreHealthy / len(replicas)
+}
@@ -18,7 +18,12 @@ import (
 
 type WebsocketReport healthsdk.WebsocketReport
 
+// websocketCheckMessages is the number of messages echoed by the check.
+const websocketCheckMessages = 3
+
 type WebsocketReportOptions struct {
+	// APIKey authenticates the check. Without it, the check uses the
+	// unauthenticated echo endpoint that only echoes a few messages.
 	APIKey     string
 	AccessURL  *url.URL
 	HTTPClient *http.Client
@@ -34,7 +39,11 @@ func (r *WebsocketReport) Run(ctx context.Context, opts *WebsocketReportOptions)
 	r.Warnings = []health.Message{}
 	r.Dismissed = opts.Dismissed
 
-	u, err := opts.AccessURL.Parse("/api/v2/debug/ws")
+	path := "/api/v2/debug/ws"
+	if opts.APIKey == "" {
+		path = "/api/v2/healthz/ws"
+	}
+	u, err := opts.AccessURL.Parse(path)
 	if err != nil {
 		r.Error = convertError(xerrors.Errorf("parse access url: %w", err))
 		r.Severity = health.SeverityError
@@ -71,7 +80,7 @@ func (r *WebsocketReport) Run(ctx context.Context, opts *WebsocketReportOptions)
 	}
 	defer c.Close(websocket.StatusGoingAway, "goodbye")
 
-	for i := 0; i < 3; i++ {
+	for i := 0; i < websocketCheckMessages; i++ {
 		msg := strconv.Itoa(i)
 		err := c.Write(ctx, websocket.MessageText, []byte(msg))
 		if err != nil {
@@ -107,6 +116,9 @@ func (r *WebsocketReport) Run(ctx context.Context, opts *WebsocketReportOptions)
 type WebsocketEchoServer struct {
 	Error error
 	Code  int
+	// Limited closes the connection once it echoed the messages of a check,
+	// so that the server can be exposed without authentication.
+	Limited bool
 }
 
 func (s *WebsocketEchoServer) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
@@ -148,7 +160,7 @@ func (s *WebsocketEchoServer) ServeHTTP(rw http.ResponseWriter, r *http.Request)
 		return err
 	}
 
-	for {
+	for i := 0; !s.Limited || i < websocketCheckMessages; i++ {
 		err := echo()
 		if err != nil {
 			return
@@ -41,6 +41,33 @@ func TestWebsocket(t *testing.T) {
 		require.Nil(t, wsReport.Error)
 	})
 
+	t.Run("Unauthenticated", func(t *testing.T) {
+		t.Parallel()
+
+		var path string
+		echo := &healthcheck.WebsocketEchoServer{Limited: true}
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			echo.ServeHTTP(rw, r)
+		}))
+		defer srv.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+		defer cancel()
+
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+
+		wsReport := healthcheck.WebsocketReport{}
+		wsReport.Run(ctx, &healthcheck.WebsocketReportOptions{
+			AccessURL:  u,
+			HTTPClient: srv.Client(),
+		})
+
+		require.Nil(t, wsReport.Error)
+		require.Equal(t, "/api/v2/healthz/ws", path)
+	})
+
 	t.Run("Error", func(t *testing.T) {
 		t.Parallel()
 
@@ -23,9 +23,12 @@ func StripCoderCookies(header string) string {
 		if name == codersdk.SessionTokenCookie ||
 			name == codersdk.OAuth2StateCookie ||
 			name == codersdk.OAuth2RedirectCookie ||
+			name == codersdk.SAMLRequestIDCookie ||
+			name == codersdk.SAMLRedirectCookie ||
 			name == codersdk.PathAppSessionTokenCookie ||
 			name == codersdk.SubdomainAppSessionTokenCookie ||
-			name == codersdk.SignedAppTokenCookie {
+			name == codersdk.SignedAppTokenCookie ||
+			name == codersdk.PortShareLinkCookie {
 			continue
 		}
 		cookies = append(cookies, part)
@@ -25,6 +25,9 @@ func TestStripCoderCookies(t *testing.T) {
 	}, {
 		"coder_session_token=ok; oauth_state=wow; oauth_redirect=/",
 		"",
+	}, {
+		"coder_port_share_link=secret; wow=test",
+		"wow=test",
 	}} {
 		t.Run(tc.Input, func(t *testing.T) {
 			t.Parallel()
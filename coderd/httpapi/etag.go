@@ -0,0 +1,91 @@
+package httpapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// SetETag sets the entity tag of the resource in the response. It must be
+// called before the response is written.
+func SetETag(rw http.ResponseWriter, etag string) {
+	rw.Header().Set("ETag", etag)
+}
+
+// IfMatch checks the If-Match header of the request against the current
+// entity tag of the resource. If it does not match, it writes a
+// 412 Precondition Failed response and returns false. Requests without the
+// header always match.
+func IfMatch(ctx context.Context, rw http.ResponseWriter, r *http.Request, etag string) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	SetETag(rw, etag)
+	Write(ctx, rw, http.StatusPreconditionFailed, codersdk.Response{
+		Message: "The resource was changed since it was read.",
+		Detail:  "Fetch the resource again and retry with its current ETag.",
+	})
+	return false
+}
+
+// WriteCacheable writes a 200 OK response like Write, with an entity tag
+// derived from its content. If the If-None-Match header of the request has the
+// tag, it writes 304 Not Modified without a body instead, so clients that poll
+// the resource only download it when it changed.
+func WriteCacheable(ctx context.Context, rw http.ResponseWriter, r *http.Request, response interface{}) {
+	_, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	SetAuthzCheckRecorderHeader(ctx, rw)
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		InternalServerError(rw, err)
+		return
+	}
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	SetETag(rw, etag)
+	// Responses depend on the actor, and must be revalidated before they are
+	// reused.
+	rw.Header().Set("Cache-Control", "private, no-cache")
+	if ifNoneMatch(r, etag) {
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	rw.WriteHeader(http.StatusOK)
+	// We can't really do much about these errors, it's probably due to a
+	// dropped connection.
+	_, _ = rw.Write(append(data, '\n'))
+}
+
+// ifNoneMatch returns whether the If-None-Match header of the request has the
+// entity tag. Unlike If-Match, it uses the weak comparison.
+func ifNoneMatch(r *http.Request, etag string) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package httpapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestIfMatch(t *testing.T) {
+	t.Parallel()
+
+	const etag = `"abc"`
+	for _, tc := range []struct {
+		name    string
+		header  string
+		matches bool
+	}{
+		{name: "NoHeader", header: "", matches: true},
+		{name: "Match", header: `"abc"`, matches: true},
+		{name: "Any", header: "*", matches: true},
+		{name: "List", header: `"def", "abc"`, matches: true},
+		{name: "Mismatch", header: `"def"`, matches: false},
+		{name: "Weak", header: `W/"abc"`, matches: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.Context(t, testutil.WaitShort)
+			rw := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, "/", nil)
+			if tc.header != "" {
+				r.Header.Set("If-Match", tc.header)
+			}
+
+			require.Equal(t, tc.matches, httpapi.IfMatch(ctx, rw, r, etag))
+			if !tc.matches {
+				require.Equal(t, http.StatusPreconditionFailed, rw.Code)
+				require.Equal(t, etag, rw.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestWriteCacheable(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	response := map[string]string{"name": "coder"}
+
+	rw := httptest.NewRecorder()
+	httpapi.WriteCacheable(ctx, rw, httptest.NewRequest(http.MethodGet, "/", nil), response)
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.JSONEq(t, `{"name":"coder"}`, rw.Body.String())
+	etag := rw.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	for _, header := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("If-None-Match", header)
+		rw = httptest.NewRecorder()
+		httpapi.WriteCacheable(ctx, rw, r, response)
+		require.Equal(t, http.StatusNotModified, rw.Code, header)
+		require.Empty(t, rw.Body.String())
+		require.Equal(t, etag, rw.Header().Get("ETag"))
+	}
+
+	// A changed response has another tag.
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-None-Match", etag)
+	rw = httptest.NewRecorder()
+	httpapi.WriteCacheable(ctx, rw, r, map[string]string{"name": "changed"})
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.NotEqual(t, etag, rw.Header().Get("ETag"))
+}
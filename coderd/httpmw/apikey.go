@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"strings"
 	"time"
@@ -24,6 +25,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/networkzone"
 	"github.com/coder/coder/v2/coderd/promoauth"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/rolestore"
@@ -113,6 +115,17 @@ type ExtractAPIKeyConfig struct {
 	// a user is authenticated to prevent additional CLI invocations.
 	PostAuthAdditionalHeadersFunc func(a rbac.Subject, header http.Header)
 
+	// NetworkZones rejects requests from members of a zone that come from
+	// outside of it. NetworkZoneDenied is called for every rejected request,
+	// e.g. to audit it.
+	NetworkZones      networkzone.Zones
+	NetworkZoneDenied func(r *http.Request, actor rbac.Subject, zone networkzone.Zone)
+
+	// ClientVersion is called with the version that CLIs send in the
+	// codersdk.BuildVersionHeader once the user is authenticated. The request
+	// is rejected with the returned response if it is not nil.
+	ClientVersion func(r *http.Request, userID uuid.UUID, version string) *codersdk.Response
+
 	// Logger is used for logging middleware operations.
 	Logger slog.Logger
 }
@@ -450,7 +463,19 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 	// If the key is valid, we also fetch the user roles and status.
 	// The roles are used for RBAC authorize checks, and the status
 	// is to block 'suspended' users from accessing the platform.
-	actor, userStatus, err := UserRBACSubject(ctx, cfg.DB, key.UserID, rbac.ScopeName(key.Scope))
+	var scope rbac.ExpandableScope = rbac.ScopeName(key.Scope)
+	if key.Scope == database.APIKeyScopeWorkspaceConnect {
+		workspaceScope, err := workspaceConnectScope(ctx, cfg.DB, *key)
+		if err != nil {
+			return write(http.StatusUnauthorized, codersdk.Response{
+				Message: SignedOutErrorMessage,
+				Detail:  err.Error(),
+			})
+		}
+		scope = workspaceScope
+	}
+
+	actor, userStatus, err := UserRBACSubject(ctx, cfg.DB, key.UserID, scope)
 	if err != nil {
 		return write(http.StatusUnauthorized, codersdk.Response{
 			Message: internalErrorMessage,
@@ -480,6 +505,27 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		})
 	}
 
+	if len(cfg.NetworkZones) > 0 {
+		// Requests without a valid source address are not in any zone.
+		addr, _ := netip.ParseAddr(r.RemoteAddr)
+		if zone, denied := cfg.NetworkZones.Denied(actor, addr); denied {
+			if cfg.NetworkZoneDenied != nil {
+				cfg.NetworkZoneDenied(r, actor, zone)
+			}
+			return write(http.StatusForbidden, codersdk.Response{
+				Message: fmt.Sprintf("Requests from %s are not allowed by the %q network zone.", r.RemoteAddr, zone.Name),
+			})
+		}
+	}
+
+	if cfg.ClientVersion != nil {
+		if version := r.Header.Get(codersdk.BuildVersionHeader); version != "" {
+			if resp := cfg.ClientVersion(r, key.UserID, version); resp != nil {
+				return write(http.StatusUpgradeRequired, *resp)
+			}
+		}
+	}
+
 	if cfg.PostAuthAdditionalHeadersFunc != nil {
 		cfg.PostAuthAdditionalHeadersFunc(actor, rw.Header())
 	}
@@ -641,6 +687,32 @@ func extractExpectedAudience(r *http.Request) string {
 	return normalizeAudienceURI(audience)
 }
 
+// workspaceConnectScope returns the scope of an API key that is limited to
+// connecting to a single workspace.
+func workspaceConnectScope(ctx context.Context, db database.Store, key database.APIKey) (rbac.Scope, error) {
+	if !key.WorkspaceID.Valid {
+		return rbac.Scope{}, xerrors.New("API key is not limited to a workspace")
+	}
+	//nolint:gocritic // The scope is needed before the actor is known.
+	workspace, err := db.GetWorkspaceByID(dbauthz.AsSystemRestricted(ctx), key.WorkspaceID.UUID)
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			return rbac.Scope{}, xerrors.New("The workspace this API key is limited to no longer exists.")
+		}
+		return rbac.Scope{}, xerrors.Errorf("get workspace: %w", err)
+	}
+	if workspace.Deleted {
+		return rbac.Scope{}, xerrors.New("The workspace this API key is limited to has been deleted.")
+	}
+	return rbac.WorkspaceConnectScope(rbac.WorkspaceConnectScopeParams{
+		WorkspaceID:    workspace.ID,
+		OwnerID:        workspace.OwnerID,
+		TemplateID:     workspace.TemplateID,
+		OrganizationID: workspace.OrganizationID,
+		UserID:         key.UserID,
+	}), nil
+}
+
 // UserRBACSubject fetches a user's rbac.Subject from the database. It pulls all roles from both
 // site and organization scopes. It also pulls the groups, and the user's status.
 func UserRBACSubject(ctx context.Context, db database.Store, userID uuid.UUID, scope rbac.ExpandableScope) (rbac.Subject, database.UserStatus, error) {
@@ -26,6 +26,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/networkzone"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
@@ -742,6 +743,94 @@ func TestAPIKey(t *testing.T) {
 		require.Equal(t, "1.1.1.1", gotAPIKey.IPAddress.IPNet.IP.String())
 	})
 
+	t.Run("NetworkZone", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db, _    = dbtestutil.NewDB(t)
+			user     = dbgen.User(t, db, database.User{})
+			_, token = dbgen.APIKey(t, db, database.APIKey{
+				UserID:    user.ID,
+				ExpiresAt: dbtime.Now().AddDate(0, 0, 1),
+			})
+		)
+		zones, err := networkzone.New([]codersdk.NetworkZone{{
+			Name:  "vpn",
+			Roles: []string{rbac.RoleMember().Name},
+			CIDRs: []string{"10.0.0.0/8"},
+		}})
+		require.NoError(t, err)
+
+		var denied atomic.Int32
+		mw := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
+			DB:           db,
+			NetworkZones: zones,
+			NetworkZoneDenied: func(_ *http.Request, actor rbac.Subject, zone networkzone.Zone) {
+				assert.Equal(t, user.ID.String(), actor.ID)
+				assert.Equal(t, "vpn", zone.Name)
+				denied.Add(1)
+			},
+		})
+
+		for addr, status := range map[string]int{
+			"10.1.2.3": http.StatusOK,
+			"1.1.1.1":  http.StatusForbidden,
+		} {
+			r := httptest.NewRequest("GET", "/", nil)
+			rw := httptest.NewRecorder()
+			r.RemoteAddr = addr
+			r.Header.Set(codersdk.SessionTokenHeader, token)
+			mw(successHandler).ServeHTTP(rw, r)
+			res := rw.Result()
+			_ = res.Body.Close()
+			require.Equal(t, status, res.StatusCode, addr)
+		}
+		require.EqualValues(t, 1, denied.Load())
+	})
+
+	t.Run("ClientVersion", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db, _    = dbtestutil.NewDB(t)
+			user     = dbgen.User(t, db, database.User{})
+			_, token = dbgen.APIKey(t, db, database.APIKey{
+				UserID:    user.ID,
+				ExpiresAt: dbtime.Now().AddDate(0, 0, 1),
+			})
+		)
+
+		var checked atomic.Int32
+		mw := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
+			DB: db,
+			ClientVersion: func(_ *http.Request, userID uuid.UUID, version string) *codersdk.Response {
+				assert.Equal(t, user.ID, userID)
+				checked.Add(1)
+				if version == "v2.0.0" {
+					return &codersdk.Response{Message: "CLI version v2.0.0 is too old."}
+				}
+				return nil
+			},
+		})
+
+		// Requests without a version, e.g. from the dashboard, are not checked.
+		for version, status := range map[string]int{
+			"":        http.StatusOK,
+			"v2.30.0": http.StatusOK,
+			"v2.0.0":  http.StatusUpgradeRequired,
+		} {
+			r := httptest.NewRequest("GET", "/", nil)
+			rw := httptest.NewRecorder()
+			r.Header.Set(codersdk.SessionTokenHeader, token)
+			if version != "" {
+				r.Header.Set(codersdk.BuildVersionHeader, version)
+			}
+			mw(successHandler).ServeHTTP(rw, r)
+			res := rw.Result()
+			_ = res.Body.Close()
+			require.Equal(t, status, res.StatusCode, version)
+		}
+		require.EqualValues(t, 2, checked.Load())
+	})
+
 	t.Run("RedirectToLogin", func(t *testing.T) {
 		t.Parallel()
 		var (
@@ -58,6 +58,9 @@ func CSRF(cookieCfg codersdk.HTTPCookieConfig) func(next http.Handler) http.Hand
 		mw.ExemptRegexp(regexp.MustCompile("derp/*"))
 		// Scim
 		mw.ExemptRegexp(regexp.MustCompile("api/v2/scim/*"))
+		// SAML bindings are posted by the identity provider.
+		mw.ExemptPath("/api/v2/users/saml/acs")
+		mw.ExemptPath("/api/v2/users/saml/slo")
 		// Provisioner daemon routes
 		mw.ExemptRegexp(regexp.MustCompile("/organizations/[^/]+/provisionerdaemons/*"))
 
@@ -10,9 +10,15 @@ import (
 	"github.com/coder/coder/v2/codersdk"
 )
 
+// ExperimentChecker reports whether an experiment is enabled. It is
+// implemented by codersdk.Experiments, and by the runtime experiment flags.
+type ExperimentChecker interface {
+	Enabled(ex codersdk.Experiment) bool
+}
+
 // RequireExperiment returns middleware that checks if all required experiments are enabled.
 // If any experiment is disabled, it returns a 403 Forbidden response with details about the missing experiments.
-func RequireExperiment(experiments codersdk.Experiments, requiredExperiments ...codersdk.Experiment) func(next http.Handler) http.Handler {
+func RequireExperiment(experiments ExperimentChecker, requiredExperiments ...codersdk.Experiment) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			for _, experiment := range requiredExperiments {
@@ -47,7 +53,7 @@ func RequireExperiment(experiments codersdk.Experiments, requiredExperiments ...
 
 // RequireExperimentWithDevBypass checks if ALL the given experiments are enabled,
 // but bypasses the check in development mode (buildinfo.IsDev()).
-func RequireExperimentWithDevBypass(experiments codersdk.Experiments, requiredExperiments ...codersdk.Experiment) func(next http.Handler) http.Handler {
+func RequireExperimentWithDevBypass(experiments ExperimentChecker, requiredExperiments ...codersdk.Experiment) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if buildinfo.IsDev() {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/httprate"
@@ -70,3 +71,33 @@ func RateLimit(count int, window time.Duration) func(http.Handler) http.Handler
 		}),
 	)
 }
+
+// ReloadableRateLimit is a RateLimit whose count can be changed while it
+// serves requests, e.g. when the deployment configuration is reloaded.
+// Changing the count resets the limits of all keys.
+type ReloadableRateLimit struct {
+	window  time.Duration
+	limiter atomic.Pointer[func(http.Handler) http.Handler]
+}
+
+// NewReloadableRateLimit returns a RateLimit that can be changed with
+// SetCount.
+func NewReloadableRateLimit(count int, window time.Duration) *ReloadableRateLimit {
+	l := &ReloadableRateLimit{window: window}
+	l.SetCount(count)
+	return l
+}
+
+// SetCount replaces the rate limit with one allowing count requests per
+// window.
+func (l *ReloadableRateLimit) SetCount(count int) {
+	limiter := RateLimit(count, l.window)
+	l.limiter.Store(&limiter)
+}
+
+// Handler limits requests with the current rate limit.
+func (l *ReloadableRateLimit) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		(*l.limiter.Load())(next).ServeHTTP(rw, r)
+	})
+}
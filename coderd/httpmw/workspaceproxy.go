@@ -14,6 +14,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/codersdk"
 )
@@ -127,11 +128,20 @@ func ExtractWorkspaceProxy(opts ExtractWorkspaceProxyConfig) func(http.Handler)
 			// Do a subtle constant time comparison of the hash of the secret.
 			hashedSecret := sha256.Sum256([]byte(secret))
 			if subtle.ConstantTimeCompare(proxy.TokenHashedSecret, hashedSecret[:]) != 1 {
-				httpapi.Write(ctx, w, http.StatusUnauthorized, codersdk.Response{
-					Message: "Invalid external proxy token",
-					Detail:  "Invalid proxy token secret.",
-				})
-				return
+				// The token may have been rotated recently, in which case the
+				// previous token is accepted until it expires.
+				valid, err := previousWorkspaceProxyToken(ctx, opts.DB, proxy.ID, hashedSecret[:])
+				if err != nil {
+					httpapi.InternalServerError(w, err)
+					return
+				}
+				if !valid {
+					httpapi.Write(ctx, w, http.StatusUnauthorized, codersdk.Response{
+						Message: "Invalid external proxy token",
+						Detail:  "Invalid proxy token secret.",
+					})
+					return
+				}
 			}
 
 			ctx = r.Context()
@@ -200,3 +210,20 @@ func ExtractWorkspaceProxyParam(db database.Store, deploymentID string, fetchPri
 		})
 	}
 }
+
+// previousWorkspaceProxyToken returns whether the hashed secret is the token
+// the proxy had before its last rotation, and that token has not expired.
+func previousWorkspaceProxyToken(ctx context.Context, db database.Store, proxyID uuid.UUID, hashedSecret []byte) (bool, error) {
+	// nolint:gocritic // Get the rotation by proxy ID to check auth token
+	rotation, err := db.GetWorkspaceProxyTokenRotationByProxyID(dbauthz.AsSystemRestricted(ctx), proxyID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, xerrors.Errorf("get workspace proxy token rotation: %w", err)
+	}
+	if !dbtime.Now().Before(rotation.PreviousTokenExpiresAt) {
+		return false, nil
+	}
+	return subtle.ConstantTimeCompare(rotation.PreviousTokenHashedSecret, hashedSecret) == 1, nil
+}
@@ -2,10 +2,12 @@ package httpmw_test
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -14,6 +16,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/codersdk"
@@ -186,6 +189,49 @@ func TestExtractWorkspaceProxy(t *testing.T) {
 		defer res.Body.Close()
 		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
 	})
+
+	t.Run("RotatedToken", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db, _ = dbtestutil.NewDB(t)
+
+			proxy, secret               = dbgen.WorkspaceProxy(t, db, database.WorkspaceProxy{})
+			expiredProxy, expiredSecret = dbgen.WorkspaceProxy(t, db, database.WorkspaceProxy{})
+		)
+		newHashedSecret := sha256.Sum256([]byte("new"))
+		_, err := db.RotateWorkspaceProxyToken(context.Background(), database.RotateWorkspaceProxyTokenParams{
+			NewTokenHashedSecret:      newHashedSecret[:],
+			RotatedAt:                 dbtime.Now(),
+			ProxyID:                   proxy.ID,
+			PreviousTokenHashedSecret: proxy.TokenHashedSecret,
+			PreviousTokenExpiresAt:    dbtime.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		_, err = db.RotateWorkspaceProxyToken(context.Background(), database.RotateWorkspaceProxyTokenParams{
+			NewTokenHashedSecret:      newHashedSecret[:],
+			RotatedAt:                 dbtime.Now(),
+			ProxyID:                   expiredProxy.ID,
+			PreviousTokenHashedSecret: expiredProxy.TokenHashedSecret,
+			PreviousTokenExpiresAt:    dbtime.Now().Add(-time.Minute),
+		})
+		require.NoError(t, err)
+
+		for token, status := range map[string]int{
+			fmt.Sprintf("%s:%s", proxy.ID.String(), secret):               http.StatusOK,
+			fmt.Sprintf("%s:%s", expiredProxy.ID.String(), expiredSecret): http.StatusUnauthorized,
+		} {
+			r := httptest.NewRequest("GET", "/", nil)
+			rw := httptest.NewRecorder()
+			r.Header.Set(httpmw.WorkspaceProxyAuthTokenHeader, token)
+
+			httpmw.ExtractWorkspaceProxy(httpmw.ExtractWorkspaceProxyConfig{
+				DB: db,
+			})(successHandler).ServeHTTP(rw, r)
+			res := rw.Result()
+			_ = res.Body.Close()
+			require.Equal(t, status, res.StatusCode)
+		}
+	})
 }
 
 func TestExtractWorkspaceProxyParam(t *testing.T) {
@@ -60,7 +60,7 @@ func (s AGPLIDPSync) GroupSyncSettings(ctx context.Context, orgID uuid.UUID, db
 			if defaultOrganization.ID == orgID {
 				settings = ptr.Ref(GroupSyncSettings(codersdk.GroupSyncSettings{
 					Field:             s.Legacy.GroupField,
-					LegacyNameMapping: s.Legacy.GroupMapping,
+					LegacyNameMapping: s.legacyGroupMappingValue(),
 					RegexFilter:       s.Legacy.GroupFilter,
 					AutoCreateMissing: s.Legacy.CreateMissingGroups,
 				}))
@@ -160,7 +160,12 @@ func (s AGPLIDPSync) SyncGroups(ctx context.Context, db database.Store, user dat
 			// Now we know what groups the user should be in for a given org,
 			// determine if we have to do any group updates to sync the user's
 			// state.
-			existingGroups := userOrgs[orgID]
+			// Membership of SCIM provisioned and LDAP synced groups is owned
+			// by the SCIM client or the directory, so OIDC group sync must
+			// never remove users from them.
+			existingGroups := slice.Filter(userOrgs[orgID], func(g database.GetGroupsRow) bool {
+				return g.Group.Source != database.GroupSourceScim && g.Group.Source != database.GroupSourceLdap
+			})
 			existingGroupsTyped := db2sdk.List(existingGroups, func(f database.GetGroupsRow) ExpectedGroup {
 				return ExpectedGroup{
 					OrganizationID: orgID,
@@ -326,7 +331,8 @@ func (a ExpectedGroup) Equal(b ExpectedGroup) bool {
 func (s GroupSyncSettings) ParseClaims(orgID uuid.UUID, mergedClaims jwt.MapClaims) ([]ExpectedGroup, error) {
 	groupsRaw, ok := mergedClaims[s.Field]
 	if !ok {
-		return []ExpectedGroup{}, nil
+		// IDPs omit empty claims, so this is a user without any groups.
+		groupsRaw = []interface{}{}
 	}
 
 	parsedGroups, err := ParseStringSliceClaim(groupsRaw)
@@ -334,14 +340,18 @@ func (s GroupSyncSettings) ParseClaims(orgID uuid.UUID, mergedClaims jwt.MapClai
 		return nil, xerrors.Errorf("parse groups field, unexpected type %T: %w", groupsRaw, err)
 	}
 
-	groups := make([]ExpectedGroup, 0)
-	for _, group := range parsedGroups {
-		// Legacy group mappings happen before the regex filter.
+	// Legacy group mappings happen before the rules and the regex filter.
+	for i, group := range parsedGroups {
 		mappedGroupName, ok := s.LegacyNameMapping[group]
 		if ok {
-			group = mappedGroupName
+			parsedGroups[i] = mappedGroupName
 		}
+	}
+	// Rules can map many groups to the same name, so drop the duplicates.
+	parsedGroups = slice.Unique(ApplyRules(s.Rules, parsedGroups))
 
+	groups := make([]ExpectedGroup, 0)
+	for _, group := range parsedGroups {
 		// Only allow through groups that pass the regex
 		if s.RegexFilter != nil {
 			if !s.RegexFilter.MatchString(group) {
@@ -360,6 +370,12 @@ func (s GroupSyncSettings) ParseClaims(orgID uuid.UUID, mergedClaims jwt.MapClai
 		groups = append(groups, ExpectedGroup{OrganizationID: orgID, GroupName: &group})
 	}
 
+	if len(groups) == 0 {
+		for _, gid := range s.DefaultGroups {
+			groups = append(groups, ExpectedGroup{OrganizationID: orgID, GroupID: &gid})
+		}
+	}
+
 	return groups, nil
 }
 
@@ -188,6 +188,29 @@ func TestGroupSyncTable(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "KeepSCIMGroups",
+			GroupSettings: &codersdk.GroupSyncSettings{
+				Field:             "groups",
+				RegexFilter:       regexp.MustCompile(".*"),
+				AutoCreateMissing: false,
+			},
+			GroupNames: map[string]bool{
+				"foo":  false,
+				"goob": true,
+			},
+			// SCIM groups are managed by the SCIM client, so the user
+			// keeps them even though they are not in the claims.
+			SCIMGroupNames: map[string]bool{
+				"scim-eng": true,
+			},
+			assertGroups: &orgGroupAssert{
+				ExpectedGroupNames: []string{
+					"foo",
+					"scim-eng",
+				},
+			},
+		},
 		{
 			Name: "NoUser",
 			GroupSettings: &codersdk.GroupSyncSettings{
@@ -790,6 +813,21 @@ func SetupOrganization(t *testing.T, s *idpsync.AGPLIDPSync, db database.Store,
 			})
 		}
 	}
+	for groupName, in := range def.SCIMGroupNames {
+		groups, err := db.InsertMissingGroups(context.Background(), database.InsertMissingGroupsParams{
+			OrganizationID: org.ID,
+			Source:         database.GroupSourceScim,
+			GroupNames:     []string{groupName},
+		})
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		if in {
+			dbgen.GroupMember(t, db, database.GroupMemberTable{
+				UserID:  user.ID,
+				GroupID: groups[0].ID,
+			})
+		}
+	}
 	for groupName, in := range def.GroupNames {
 		group := dbgen.Group(t, db, database.Group{
 			Name:           groupName,
@@ -807,8 +845,10 @@ func SetupOrganization(t *testing.T, s *idpsync.AGPLIDPSync, db database.Store,
 type orgSetupDefinition struct {
 	Name string
 	// True if the user is a member of the group
-	Groups            map[uuid.UUID]bool
-	GroupNames        map[string]bool
+	Groups     map[uuid.UUID]bool
+	GroupNames map[string]bool
+	// SCIMGroupNames are created with the SCIM group source.
+	SCIMGroupNames    map[string]bool
 	OrganizationRoles []string
 	CustomRoles       []string
 	// NotMember if true will ensure the user is not a member of the organization.
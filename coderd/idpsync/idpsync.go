@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"sync/atomic"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
@@ -158,6 +159,11 @@ type SyncSettings struct {
 	Group        runtimeconfig.RuntimeEntry[*GroupSyncSettings]
 	Role         runtimeconfig.RuntimeEntry[*RoleSyncSettings]
 	Organization runtimeconfig.RuntimeEntry[*OrganizationSyncSettings]
+
+	// legacyGroupMapping replaces Legacy.GroupMapping once the deployment
+	// config is reloaded. It is nil if the settings were not created with
+	// NewAGPLSync.
+	legacyGroupMapping *atomic.Pointer[map[string]string]
 }
 
 func NewAGPLSync(logger slog.Logger, manager *runtimeconfig.Manager, settings DeploymentSyncSettings) *AGPLIDPSync {
@@ -169,10 +175,30 @@ func NewAGPLSync(logger slog.Logger, manager *runtimeconfig.Manager, settings De
 			Group:                  runtimeconfig.MustNew[*GroupSyncSettings]("group-sync-settings"),
 			Role:                   runtimeconfig.MustNew[*RoleSyncSettings]("role-sync-settings"),
 			Organization:           runtimeconfig.MustNew[*OrganizationSyncSettings]("organization-sync-settings"),
+			legacyGroupMapping:     &atomic.Pointer[map[string]string]{},
 		},
 	}
 }
 
+// SetLegacyGroupMapping replaces the OIDC group mapping of the deployment,
+// which applies to the default organization if it has no group sync
+// settings. It is used when the deployment config is reloaded.
+func (s AGPLIDPSync) SetLegacyGroupMapping(mapping map[string]string) {
+	if s.legacyGroupMapping == nil {
+		return
+	}
+	s.legacyGroupMapping.Store(&mapping)
+}
+
+func (s AGPLIDPSync) legacyGroupMappingValue() map[string]string {
+	if s.legacyGroupMapping != nil {
+		if mapping := s.legacyGroupMapping.Load(); mapping != nil {
+			return *mapping
+		}
+	}
+	return s.Legacy.GroupMapping
+}
+
 // ParseStringSliceClaim parses the claim for groups and roles, expected []string.
 //
 // Some providers like ADFS return a single string instead of an array if there
@@ -118,7 +118,7 @@ func (s AGPLIDPSync) SyncRoles(ctx context.Context, db database.Store, user data
 			}
 
 			existingRoles[orgID] = member.OrganizationMember.Roles
-			orgRoleClaims, err := s.RolesFromClaim(settings.Field, params.MergedClaims)
+			expected, err := settings.ParseClaims(orgID, params.MergedClaims)
 			if err != nil {
 				s.Logger.Error(ctx, "failed to parse roles from claim",
 					slog.F("field", settings.Field),
@@ -145,17 +145,6 @@ func (s AGPLIDPSync) SyncRoles(ctx context.Context, db database.Store, user data
 				continue
 			}
 
-			expected := make([]rbac.RoleIdentifier, 0, len(orgRoleClaims))
-			for _, role := range orgRoleClaims {
-				if mappedRoles, ok := settings.Mapping[role]; ok {
-					for _, mappedRole := range mappedRoles {
-						expected = append(expected, rbac.RoleIdentifier{OrganizationID: orgID, Name: mappedRole})
-					}
-					continue
-				}
-				expected = append(expected, rbac.RoleIdentifier{OrganizationID: orgID, Name: role})
-			}
-
 			expectedRoles[orgID] = expected
 			allExpected = append(allExpected, expected...)
 		}
@@ -261,6 +250,10 @@ func (s AGPLIDPSync) syncSiteWideRoles(ctx context.Context, tx database.Store, u
 }
 
 func (AGPLIDPSync) RolesFromClaim(field string, claims jwt.MapClaims) ([]string, error) {
+	return rolesFromClaim(field, claims)
+}
+
+func rolesFromClaim(field string, claims jwt.MapClaims) ([]string, error) {
 	rolesRow, ok := claims[field]
 	if !ok {
 		// If no claim is provided than we can assume the user is just
@@ -281,6 +274,34 @@ func (AGPLIDPSync) RolesFromClaim(field string, claims jwt.MapClaims) ([]string,
 
 type RoleSyncSettings codersdk.RoleSyncSettings
 
+// ParseClaims returns the organization roles the user is expected to have
+// given their claims. The roles are not checked to exist.
+func (s RoleSyncSettings) ParseClaims(orgID uuid.UUID, mergedClaims jwt.MapClaims) ([]rbac.RoleIdentifier, error) {
+	claimRoles, err := rolesFromClaim(s.Field, mergedClaims)
+	if err != nil {
+		return nil, err
+	}
+	claimRoles = slice.Unique(ApplyRules(s.Rules, claimRoles))
+
+	expected := make([]rbac.RoleIdentifier, 0, len(claimRoles))
+	for _, role := range claimRoles {
+		if mappedRoles, ok := s.Mapping[role]; ok {
+			for _, mappedRole := range mappedRoles {
+				expected = append(expected, rbac.RoleIdentifier{OrganizationID: orgID, Name: mappedRole})
+			}
+			continue
+		}
+		expected = append(expected, rbac.RoleIdentifier{OrganizationID: orgID, Name: role})
+	}
+
+	if len(expected) == 0 {
+		for _, role := range s.DefaultRoles {
+			expected = append(expected, rbac.RoleIdentifier{OrganizationID: orgID, Name: role})
+		}
+	}
+	return expected, nil
+}
+
 func (s *RoleSyncSettings) Set(v string) error {
 	return json.Unmarshal([]byte(v), s)
 }
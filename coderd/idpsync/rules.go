@@ -0,0 +1,46 @@
+package idpsync
+
+import (
+	"strings"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// ApplyRules transforms the claim values with the first rule that matches
+// each of them. Values that are empty after the transformation are dropped.
+func ApplyRules(rules []codersdk.IDPSyncRule, values []string) []string {
+	if len(rules) == 0 {
+		return values
+	}
+
+	transformed := make([]string, 0, len(values))
+	for _, value := range values {
+		value = applyRule(rules, value)
+		if value == "" {
+			continue
+		}
+		transformed = append(transformed, value)
+	}
+	return transformed
+}
+
+func applyRule(rules []codersdk.IDPSyncRule, value string) string {
+	for _, rule := range rules {
+		if rule.Match == nil {
+			if rule.Replacement != "" {
+				value = rule.Replacement
+			}
+			return strings.TrimPrefix(value, rule.StripPrefix)
+		}
+
+		match := rule.Match.FindStringSubmatchIndex(value)
+		if match == nil {
+			continue
+		}
+		if rule.Replacement != "" {
+			value = string(rule.Match.ExpandString(nil, rule.Replacement, value, match))
+		}
+		return strings.TrimPrefix(value, rule.StripPrefix)
+	}
+	return value
+}
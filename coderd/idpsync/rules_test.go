@@ -0,0 +1,165 @@
+package idpsync_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/idpsync"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestApplyRules(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		rules    []codersdk.IDPSyncRule
+		values   []string
+		expected []string
+	}{
+		{
+			name:     "NoRules",
+			values:   []string{"a", "b"},
+			expected: []string{"a", "b"},
+		},
+		{
+			name: "StripPrefix",
+			rules: []codersdk.IDPSyncRule{
+				{StripPrefix: "coder-"},
+			},
+			values:   []string{"coder-admins", "users"},
+			expected: []string{"admins", "users"},
+		},
+		{
+			name: "RegexReplacement",
+			rules: []codersdk.IDPSyncRule{
+				{Match: regexp.MustCompile(`^CN=([^,]+),OU=Groups`), Replacement: "$1"},
+			},
+			values:   []string{"CN=eng,OU=Groups,DC=example", "ops"},
+			expected: []string{"eng", "ops"},
+		},
+		{
+			name: "ManyToOne",
+			rules: []codersdk.IDPSyncRule{
+				{Match: regexp.MustCompile(`^(frontend|backend)-team$`), Replacement: "engineering"},
+			},
+			values:   []string{"frontend-team", "backend-team", "sales"},
+			expected: []string{"engineering", "engineering", "sales"},
+		},
+		{
+			name: "FirstMatchWins",
+			rules: []codersdk.IDPSyncRule{
+				{Match: regexp.MustCompile(`^admin-`), Replacement: "admins"},
+				{StripPrefix: "admin-"},
+			},
+			values:   []string{"admin-eu", "admin-us"},
+			expected: []string{"admins", "admins"},
+		},
+		{
+			name: "ReplacementThenStripPrefix",
+			rules: []codersdk.IDPSyncRule{
+				{Match: regexp.MustCompile(`^(?P<team>\w+)@example\.com$`), Replacement: "team-${team}", StripPrefix: "team-"},
+			},
+			values:   []string{"infra@example.com"},
+			expected: []string{"infra"},
+		},
+		{
+			name: "DropsEmpty",
+			rules: []codersdk.IDPSyncRule{
+				{StripPrefix: "coder-"},
+			},
+			values:   []string{"coder-", "dev"},
+			expected: []string{"dev"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, idpsync.ApplyRules(tc.rules, tc.values))
+		})
+	}
+}
+
+func TestGroupSyncSettingsParseClaims(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	engID := uuid.New()
+	defaultID := uuid.New()
+	settings := idpsync.GroupSyncSettings{
+		Field: "groups",
+		Rules: []codersdk.IDPSyncRule{
+			{Match: regexp.MustCompile(`^(frontend|backend)$`), Replacement: "engineering"},
+			{StripPrefix: "coder-"},
+		},
+		RegexFilter:   regexp.MustCompile(`^[a-z]+$`),
+		Mapping:       map[string][]uuid.UUID{"engineering": {engID}},
+		DefaultGroups: []uuid.UUID{defaultID},
+	}
+
+	t.Run("Rules", func(t *testing.T) {
+		t.Parallel()
+
+		groups, err := settings.ParseClaims(orgID, jwt.MapClaims{
+			"groups": []string{"frontend", "backend", "coder-ops", "Sales"},
+		})
+		require.NoError(t, err)
+		require.Len(t, groups, 2)
+		require.Equal(t, engID, *groups[0].GroupID)
+		require.Equal(t, "ops", *groups[1].GroupName)
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		t.Parallel()
+
+		groups, err := settings.ParseClaims(orgID, jwt.MapClaims{
+			"groups": []string{"Sales"},
+		})
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		require.Equal(t, defaultID, *groups[0].GroupID)
+
+		groups, err = settings.ParseClaims(orgID, jwt.MapClaims{})
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		require.Equal(t, defaultID, *groups[0].GroupID)
+	})
+}
+
+func TestRoleSyncSettingsParseClaims(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	settings := idpsync.RoleSyncSettings{
+		Field: "roles",
+		Rules: []codersdk.IDPSyncRule{
+			{Match: regexp.MustCompile(`^coder-(admin|owner)$`), Replacement: "org-admin"},
+			{StripPrefix: "coder-"},
+		},
+		Mapping: map[string][]string{
+			"org-admin": {rbac.RoleOrgAdmin()},
+		},
+		DefaultRoles: []string{rbac.RoleOrgAuditor()},
+	}
+
+	roles, err := settings.ParseClaims(orgID, jwt.MapClaims{
+		"roles": []string{"coder-admin", "coder-owner", "coder-template-admin"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []rbac.RoleIdentifier{
+		{OrganizationID: orgID, Name: rbac.RoleOrgAdmin()},
+		{OrganizationID: orgID, Name: "template-admin"},
+	}, roles)
+
+	roles, err = settings.ParseClaims(orgID, jwt.MapClaims{})
+	require.NoError(t, err)
+	require.Equal(t, []rbac.RoleIdentifier{
+		{OrganizationID: orgID, Name: rbac.RoleOrgAuditor()},
+	}, roles)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"slices"
 	"time"
@@ -53,9 +54,11 @@ var fallbackIcons = map[uuid.UUID]string{
 	notifications.TemplateUserRequestedOneTimePasscode: codersdk.InboxNotificationFallbackIconAccount,
 
 	// template related notifications
-	notifications.TemplateTemplateDeleted:             codersdk.InboxNotificationFallbackIconTemplate,
-	notifications.TemplateTemplateDeprecated:          codersdk.InboxNotificationFallbackIconTemplate,
-	notifications.TemplateWorkspaceBuildsFailedReport: codersdk.InboxNotificationFallbackIconTemplate,
+	notifications.TemplateTemplateDeleted:                    codersdk.InboxNotificationFallbackIconTemplate,
+	notifications.TemplateTemplateDeprecated:                 codersdk.InboxNotificationFallbackIconTemplate,
+	notifications.TemplateTemplateVersionActivationRequested: codersdk.InboxNotificationFallbackIconTemplate,
+	notifications.TemplateTemplateVersionRolloutHalted:       codersdk.InboxNotificationFallbackIconTemplate,
+	notifications.TemplateWorkspaceBuildsFailedReport:        codersdk.InboxNotificationFallbackIconTemplate,
 }
 
 func ensureNotificationIcon(notif codersdk.InboxNotification) codersdk.InboxNotification {
@@ -111,8 +114,10 @@ func convertInboxNotificationResponse(ctx context.Context, logger slog.Logger, n
 // @Tags Notifications
 // @Param targets query string false "Comma-separated list of target IDs to filter notifications"
 // @Param templates query string false "Comma-separated list of template IDs to filter notifications"
+// @Param categories query string false "Comma-separated list of notification categories to filter notifications"
 // @Param read_status query string false "Filter notifications by read status. Possible values: read, unread, all"
 // @Param format query string false "Define the output format for notifications title and body." enums(plaintext,markdown)
+// @Param read_status_events query bool false "Also send a message when notifications are marked as read or unread"
 // @Success 200 {object} codersdk.GetInboxNotificationResponse
 // @Router /notifications/inbox/watch [get]
 func (api *API) watchInboxNotifications(rw http.ResponseWriter, r *http.Request) {
@@ -123,10 +128,12 @@ func (api *API) watchInboxNotifications(rw http.ResponseWriter, r *http.Request)
 		ctx    = r.Context()
 		apikey = httpmw.APIKey(r)
 
-		targets    = p.UUIDs(vals, []uuid.UUID{}, "targets")
-		templates  = p.UUIDs(vals, []uuid.UUID{}, "templates")
-		readStatus = p.String(vals, "all", "read_status")
-		format     = p.String(vals, notificationFormatMarkdown, "format")
+		targets          = p.UUIDs(vals, []uuid.UUID{}, "targets")
+		templates        = p.UUIDs(vals, []uuid.UUID{}, "templates")
+		categories       = httpapi.ParseCustomList(p, vals, []database.NotificationCategory{}, "categories", httpapi.ParseEnum[database.NotificationCategory])
+		readStatus       = p.String(vals, "all", "read_status")
+		format           = p.String(vals, notificationFormatMarkdown, "format")
+		readStatusEvents = p.Boolean(vals, false, "read_status_events")
 	)
 	p.ErrorExcessParams(vals)
 	if len(p.Errors) > 0 {
@@ -148,7 +155,26 @@ func (api *API) watchInboxNotifications(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	notificationCh := make(chan codersdk.InboxNotification, 10)
+	// Notifications only reference their template, so the categories are
+	// resolved to the templates that belong to them.
+	var categoryTemplates []uuid.UUID
+	if len(categories) > 0 {
+		systemTemplates, err := api.Database.GetNotificationTemplatesByKind(ctx, database.NotificationTemplateKindSystem)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to get notification templates.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		for _, tmpl := range systemTemplates {
+			if tmpl.Category.Valid && slices.Contains(categories, tmpl.Category.NotificationCategory) {
+				categoryTemplates = append(categoryTemplates, tmpl.ID)
+			}
+		}
+	}
+
+	eventCh := make(chan pubsub.InboxNotificationEvent, 10)
 
 	closeInboxNotificationsSubscriber, err := api.Pubsub.SubscribeWithErr(pubsub.InboxNotificationForOwnerEventChannel(apikey.UserID),
 		pubsub.HandleInboxNotificationEvent(
@@ -158,42 +184,59 @@ func (api *API) watchInboxNotifications(rw http.ResponseWriter, r *http.Request)
 					return
 				}
 
-				// HandleInboxNotificationEvent cb receives all the inbox notifications - without any filters excepted the user_id.
-				// Based on query parameters defined above and filters defined by the client - we then filter out the
-				// notifications we do not want to forward and discard it.
-
-				// filter out notifications that don't match the targets
-				if len(targets) > 0 {
-					for _, target := range targets {
-						if isFound := slices.Contains(payload.InboxNotification.Targets, target); !isFound {
-							return
+				// Read status events are not filtered, they keep the unread
+				// count of the client up to date.
+				if payload.Kind == pubsub.InboxNotificationEventKindReadStatus {
+					if !readStatusEvents {
+						return
+					}
+				} else {
+					// HandleInboxNotificationEvent cb receives all the inbox notifications - without any filters excepted the user_id.
+					// Based on query parameters defined above and filters defined by the client - we then filter out the
+					// notifications we do not want to forward and discard it.
+
+					// filter out notifications that don't match the targets
+					if len(targets) > 0 {
+						for _, target := range targets {
+							if isFound := slices.Contains(payload.InboxNotification.Targets, target); !isFound {
+								return
+							}
 						}
 					}
-				}
 
-				// filter out notifications that don't match the templates
-				if len(templates) > 0 {
-					if isFound := slices.Contains(templates, payload.InboxNotification.TemplateID); !isFound {
-						return
+					// filter out notifications that don't match the templates
+					if len(templates) > 0 {
+						if isFound := slices.Contains(templates, payload.InboxNotification.TemplateID); !isFound {
+							return
+						}
 					}
-				}
 
-				// filter out notifications that don't match the read status
-				if readStatus != "" {
-					if readStatus == string(database.InboxNotificationReadStatusRead) {
-						if payload.InboxNotification.ReadAt == nil {
+					// filter out notifications that don't match the categories
+					if len(categories) > 0 {
+						if isFound := slices.Contains(categoryTemplates, payload.InboxNotification.TemplateID); !isFound {
 							return
 						}
-					} else if readStatus == string(database.InboxNotificationReadStatusUnread) {
-						if payload.InboxNotification.ReadAt != nil {
-							return
+					}
+
+					// filter out notifications that don't match the read status
+					if readStatus != "" {
+						if readStatus == string(database.InboxNotificationReadStatusRead) {
+							if payload.InboxNotification.ReadAt == nil {
+								return
+							}
+						} else if readStatus == string(database.InboxNotificationReadStatusUnread) {
+							if payload.InboxNotification.ReadAt != nil {
+								return
+							}
 						}
 					}
+
+					payload.InboxNotification = ensureNotificationIcon(payload.InboxNotification)
 				}
 
 				// keep a safe guard in case of latency to push notifications through websocket
 				select {
-				case notificationCh <- ensureNotificationIcon(payload.InboxNotification):
+				case eventCh <- payload:
 				default:
 					api.Logger.Error(ctx, "failed to push consumed notification into websocket handler, check latency")
 				}
@@ -229,15 +272,28 @@ func (api *API) watchInboxNotifications(rw http.ResponseWriter, r *http.Request)
 		select {
 		case <-ctx.Done():
 			return
-		case notif := <-notificationCh:
+		case event := <-eventCh:
 			unreadCount, err := api.Database.CountUnreadInboxNotificationsByUserID(ctx, apikey.UserID)
 			if err != nil {
 				api.Logger.Error(ctx, "failed to count unread inbox notifications", slog.Error(err))
 				return
 			}
 
+			if event.Kind == pubsub.InboxNotificationEventKindReadStatus {
+				if err := encoder.Encode(codersdk.GetInboxNotificationResponse{
+					Kind:        codersdk.InboxNotificationEventKindReadStatus,
+					ReadStatus:  event.ReadStatus,
+					UnreadCount: int(unreadCount),
+				}); err != nil {
+					api.Logger.Error(ctx, "encode notification read status", slog.Error(err))
+					return
+				}
+				continue
+			}
+
 			// By default, notifications are stored as markdown
 			// We can change the format based on parameter if required
+			notif := event.InboxNotification
 			if format == notificationFormatPlaintext {
 				notif.Title, err = markdown.PlaintextFromMarkdown(notif.Title)
 				if err != nil {
@@ -253,6 +309,7 @@ func (api *API) watchInboxNotifications(rw http.ResponseWriter, r *http.Request)
 			}
 
 			if err := encoder.Encode(codersdk.GetInboxNotificationResponse{
+				Kind:         codersdk.InboxNotificationEventKindNew,
 				Notification: notif,
 				UnreadCount:  int(unreadCount),
 			}); err != nil {
@@ -271,6 +328,7 @@ func (api *API) watchInboxNotifications(rw http.ResponseWriter, r *http.Request)
 // @Tags Notifications
 // @Param targets query string false "Comma-separated list of target IDs to filter notifications"
 // @Param templates query string false "Comma-separated list of template IDs to filter notifications"
+// @Param categories query string false "Comma-separated list of notification categories to filter notifications"
 // @Param read_status query string false "Filter notifications by read status. Possible values: read, unread, all"
 // @Param starting_before query string false "ID of the last notification from the current page. Notifications returned will be older than the associated one" format(uuid)
 // @Success 200 {object} codersdk.ListInboxNotificationsResponse
@@ -285,6 +343,7 @@ func (api *API) listInboxNotifications(rw http.ResponseWriter, r *http.Request)
 
 		targets        = p.UUIDs(vals, nil, "targets")
 		templates      = p.UUIDs(vals, nil, "templates")
+		categories     = httpapi.ParseCustomList(p, vals, nil, "categories", httpapi.ParseEnum[database.NotificationCategory])
 		readStatus     = p.String(vals, "all", "read_status")
 		startingBefore = p.UUID(vals, uuid.Nil, "starting_before")
 	)
@@ -320,6 +379,7 @@ func (api *API) listInboxNotifications(rw http.ResponseWriter, r *http.Request)
 		UserID:       apikey.UserID,
 		Templates:    templates,
 		Targets:      targets,
+		Categories:   categories,
 		ReadStatus:   database.InboxNotificationReadStatus(readStatus),
 		CreatedAtOpt: createdBefore,
 	})
@@ -416,6 +476,11 @@ func (api *API) updateInboxNotificationReadStatus(rw http.ResponseWriter, r *htt
 		return
 	}
 
+	api.publishInboxNotificationReadStatus(ctx, apikey.UserID, codersdk.InboxNotificationReadStatusChange{
+		IDs:    []uuid.UUID{notificationID},
+		IsRead: body.IsRead,
+	})
+
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.UpdateInboxNotificationReadStatusResponse{
 		Notification: convertInboxNotificationResponse(ctx, api.Logger, updatedNotification),
 		UnreadCount:  int(unreadCount),
@@ -447,5 +512,153 @@ func (api *API) markAllInboxNotificationsAsRead(rw http.ResponseWriter, r *http.
 		return
 	}
 
+	api.publishInboxNotificationReadStatus(ctx, apikey.UserID, codersdk.InboxNotificationReadStatusChange{
+		IsRead: true,
+	})
+
 	rw.WriteHeader(http.StatusNoContent)
 }
+
+// updateInboxNotificationsReadStatus changes the read status of several
+// notifications at once.
+// @Summary Update read status of multiple notifications
+// @ID update-read-status-of-multiple-notifications
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Notifications
+// @Param request body codersdk.UpdateInboxNotificationsReadStatusRequest true "Read status update"
+// @Success 200 {object} codersdk.UpdateInboxNotificationsReadStatusResponse
+// @Router /notifications/inbox/read-status [put]
+func (api *API) updateInboxNotificationsReadStatus(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		apikey = httpmw.APIKey(r)
+	)
+
+	var req codersdk.UpdateInboxNotificationsReadStatusRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if len(req.IDs) == 0 && len(req.Categories) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "At least one notification ID or category is required.",
+		})
+		return
+	}
+
+	categories := make([]database.NotificationCategory, 0, len(req.Categories))
+	for i, c := range req.Categories {
+		category := database.NotificationCategory(c)
+		if !category.Valid() {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid notification category.",
+				Validations: []codersdk.ValidationError{{
+					Field:  fmt.Sprintf("categories[%d]", i),
+					Detail: fmt.Sprintf("%q is not a valid notification category", c),
+				}},
+			})
+			return
+		}
+		categories = append(categories, category)
+	}
+
+	params := database.UpdateInboxNotificationsReadStatusParams{
+		UserID: apikey.UserID,
+	}
+	if len(req.IDs) > 0 {
+		params.IDs = req.IDs
+	}
+	if len(categories) > 0 {
+		params.Categories = categories
+	}
+	if req.IsRead {
+		params.ReadAt = sql.NullTime{Time: dbtime.Now(), Valid: true}
+	}
+	updated, err := api.Database.UpdateInboxNotificationsReadStatus(ctx, params)
+	if err != nil {
+		api.Logger.Error(ctx, "failed to update inbox notifications read status", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to update inbox notifications read status.",
+		})
+		return
+	}
+
+	unreadCount, err := api.Database.CountUnreadInboxNotificationsByUserID(ctx, apikey.UserID)
+	if err != nil {
+		api.Logger.Error(ctx, "failed to count unread inbox notifications", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to count unread inbox notifications.",
+		})
+		return
+	}
+
+	if updated > 0 {
+		api.publishInboxNotificationReadStatus(ctx, apikey.UserID, codersdk.InboxNotificationReadStatusChange{
+			IDs:        req.IDs,
+			Categories: req.Categories,
+			IsRead:     req.IsRead,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.UpdateInboxNotificationsReadStatusResponse{
+		UpdatedCount: int(updated),
+		UnreadCount:  int(unreadCount),
+	})
+}
+
+// inboxNotificationUnreadCounts returns the number of unread notifications of
+// the user, in total and by category.
+// @Summary Get unread inbox notification counts
+// @ID get-unread-inbox-notification-counts
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Notifications
+// @Success 200 {object} codersdk.InboxNotificationUnreadCountsResponse
+// @Router /notifications/inbox/unread-counts [get]
+func (api *API) inboxNotificationUnreadCounts(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		apikey = httpmw.APIKey(r)
+	)
+
+	rows, err := api.Database.CountUnreadInboxNotificationsByCategory(ctx, apikey.UserID)
+	if err != nil {
+		api.Logger.Error(ctx, "failed to count unread inbox notifications by category", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to count unread inbox notifications.",
+		})
+		return
+	}
+
+	resp := codersdk.InboxNotificationUnreadCountsResponse{
+		Categories: make([]codersdk.InboxNotificationCategoryUnreadCount, 0, len(rows)),
+	}
+	for _, row := range rows {
+		resp.UnreadCount += int(row.UnreadCount)
+		resp.Categories = append(resp.Categories, codersdk.InboxNotificationCategoryUnreadCount{
+			Category:    codersdk.NotificationCategory(row.Category.NotificationCategory),
+			UnreadCount: int(row.UnreadCount),
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// publishInboxNotificationReadStatus notifies the watches of the user that
+// notifications were marked as read or unread.
+func (api *API) publishInboxNotificationReadStatus(ctx context.Context, userID uuid.UUID, change codersdk.InboxNotificationReadStatusChange) {
+	payload, err := json.Marshal(pubsub.InboxNotificationEvent{
+		Kind:       pubsub.InboxNotificationEventKindReadStatus,
+		ReadStatus: &change,
+	})
+	if err != nil {
+		api.Logger.Error(ctx, "marshal inbox notification read status event", slog.Error(err))
+		return
+	}
+	err = api.Pubsub.Publish(pubsub.InboxNotificationForOwnerEventChannel(userID), payload)
+	if err != nil {
+		api.Logger.Warn(ctx, "publish inbox notification read status event", slog.Error(err))
+	}
+}
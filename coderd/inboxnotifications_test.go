@@ -363,6 +363,95 @@ func TestInboxNotification_Watch(t *testing.T) {
 		require.Equal(t, memberClient.ID, notif.Notification.UserID)
 		require.Equal(t, "another memory related title", notif.Notification.Title)
 	})
+
+	t.Run("OK - filters on categories", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		logger := testutil.Logger(t)
+
+		db, ps := dbtestutil.NewDB(t)
+
+		firstClient, _, _ := coderdtest.NewWithAPI(t, &coderdtest.Options{
+			Pubsub:   ps,
+			Database: db,
+		})
+		firstUser := coderdtest.CreateFirstUser(t, firstClient)
+		member, memberClient := coderdtest.CreateAnotherUser(t, firstClient, firstUser.OrganizationID)
+
+		notifs, closer, err := member.WatchInboxNotifications(ctx, codersdk.WatchInboxNotificationsRequest{
+			Categories: string(codersdk.NotificationCategoryDormancy),
+		})
+		require.NoError(t, err)
+		defer closer.Close()
+
+		inboxHandler := dispatch.NewInboxHandler(logger, db, ps)
+		for _, templateID := range []uuid.UUID{notifications.TemplateWorkspaceOutOfMemory, notifications.TemplateWorkspaceDormant} {
+			dispatchFunc, err := inboxHandler.Dispatcher(types.MessagePayload{
+				UserID:                 memberClient.ID.String(),
+				NotificationTemplateID: templateID.String(),
+			}, "notification title", "notification content", nil)
+			require.NoError(t, err)
+
+			_, err = dispatchFunc(ctx, uuid.New())
+			require.NoError(t, err)
+		}
+
+		notif := testutil.RequireReceive(ctx, t, notifs)
+		require.Equal(t, codersdk.InboxNotificationEventKindNew, notif.Kind)
+		require.Equal(t, notifications.TemplateWorkspaceDormant, notif.Notification.TemplateID)
+		require.Equal(t, 2, notif.UnreadCount)
+	})
+
+	t.Run("OK - read status events", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		logger := testutil.Logger(t)
+
+		db, ps := dbtestutil.NewDB(t)
+
+		firstClient, _, _ := coderdtest.NewWithAPI(t, &coderdtest.Options{
+			Pubsub:   ps,
+			Database: db,
+		})
+		firstUser := coderdtest.CreateFirstUser(t, firstClient)
+		member, memberClient := coderdtest.CreateAnotherUser(t, firstClient, firstUser.OrganizationID)
+
+		notifs, closer, err := member.WatchInboxNotifications(ctx, codersdk.WatchInboxNotificationsRequest{
+			ReadStatusEvents: true,
+		})
+		require.NoError(t, err)
+		defer closer.Close()
+
+		inboxHandler := dispatch.NewInboxHandler(logger, db, ps)
+		dispatchFunc, err := inboxHandler.Dispatcher(types.MessagePayload{
+			UserID:                 memberClient.ID.String(),
+			NotificationTemplateID: notifications.TemplateWorkspaceDeleted.String(),
+		}, "notification title", "notification content", nil)
+		require.NoError(t, err)
+
+		_, err = dispatchFunc(ctx, uuid.New())
+		require.NoError(t, err)
+
+		notif := testutil.RequireReceive(ctx, t, notifs)
+		require.Equal(t, codersdk.InboxNotificationEventKindNew, notif.Kind)
+		require.Equal(t, 1, notif.UnreadCount)
+
+		_, err = member.UpdateInboxNotificationsReadStatus(ctx, codersdk.UpdateInboxNotificationsReadStatusRequest{
+			IDs:    []uuid.UUID{notif.Notification.ID},
+			IsRead: true,
+		})
+		require.NoError(t, err)
+
+		event := testutil.RequireReceive(ctx, t, notifs)
+		require.Equal(t, codersdk.InboxNotificationEventKindReadStatus, event.Kind)
+		require.Equal(t, &codersdk.InboxNotificationReadStatusChange{
+			IDs:    []uuid.UUID{notif.Notification.ID},
+			IsRead: true,
+		}, event.ReadStatus)
+		require.Equal(t, 0, event.UnreadCount)
+	})
 }
 
 func TestInboxNotifications_List(t *testing.T) {
@@ -608,6 +697,50 @@ func TestInboxNotifications_List(t *testing.T) {
 		require.Equal(t, codersdk.InboxNotificationFallbackIconWorkspace, notifs.Notifications[0].Icon)
 	})
 
+	t.Run("OK with category filter", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{})
+		firstUser := coderdtest.CreateFirstUser(t, client)
+		client, member := coderdtest.CreateAnotherUser(t, client, firstUser.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for i, templateID := range []uuid.UUID{
+			notifications.TemplateWorkspaceDeleted,
+			notifications.TemplateWorkspaceDormant,
+			notifications.TemplateWorkspaceOutOfMemory,
+		} {
+			dbgen.NotificationInbox(t, api.Database, database.InsertInboxNotificationParams{
+				ID:         uuid.New(),
+				UserID:     member.ID,
+				TemplateID: templateID,
+				Title:      fmt.Sprintf("Notification %d", i),
+				Actions:    json.RawMessage("[]"),
+				Content:    fmt.Sprintf("Content of the notif %d", i),
+				CreatedAt:  dbtime.Now(),
+			})
+		}
+
+		notifs, err := client.ListInboxNotifications(ctx, codersdk.ListInboxNotificationsRequest{
+			Categories: string(codersdk.NotificationCategoryDormancy),
+		})
+		require.NoError(t, err)
+		require.Len(t, notifs.Notifications, 1)
+		require.Equal(t, notifications.TemplateWorkspaceDormant, notifs.Notifications[0].TemplateID)
+
+		notifs, err = client.ListInboxNotifications(ctx, codersdk.ListInboxNotificationsRequest{
+			Categories: "builds,dormancy",
+		})
+		require.NoError(t, err)
+		require.Len(t, notifs.Notifications, 2)
+
+		_, err = client.ListInboxNotifications(ctx, codersdk.ListInboxNotificationsRequest{
+			Categories: "unknown",
+		})
+		require.ErrorContains(t, err, `Query param "categories" has invalid value`)
+	})
+
 	t.Run("OK with target filter", func(t *testing.T) {
 		t.Parallel()
 
@@ -927,3 +1060,136 @@ func TestInboxNotifications_MarkAllAsRead(t *testing.T) {
 		require.Len(t, notifs.Notifications, 25)
 	})
 }
+
+func TestInboxNotifications_BulkReadStatus(t *testing.T) {
+	t.Parallel()
+
+	// I skip these tests specifically on windows as for now they are flaky - only on Windows.
+	// For now the idea is that the runner takes too long to insert the entries, could be worth
+	// investigating a manual Tx.
+	// see: https://github.com/coder/internal/issues/503
+	if runtime.GOOS == "windows" {
+		t.Skip("our runners are randomly taking too long to insert entries")
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+		client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{})
+		firstUser := coderdtest.CreateFirstUser(t, client)
+		client, member := coderdtest.CreateAnotherUser(t, client, firstUser.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		var ids []uuid.UUID
+		for i, templateID := range []uuid.UUID{
+			notifications.TemplateWorkspaceDeleted,
+			notifications.TemplateWorkspaceDeleted,
+			notifications.TemplateWorkspaceDormant,
+			notifications.TemplateWorkspaceOutOfMemory,
+		} {
+			notif := dbgen.NotificationInbox(t, api.Database, database.InsertInboxNotificationParams{
+				ID:         uuid.New(),
+				UserID:     member.ID,
+				TemplateID: templateID,
+				Title:      fmt.Sprintf("Notification %d", i),
+				Actions:    json.RawMessage("[]"),
+				Content:    fmt.Sprintf("Content of the notif %d", i),
+				CreatedAt:  dbtime.Now(),
+			})
+			ids = append(ids, notif.ID)
+		}
+
+		counts, err := client.InboxNotificationUnreadCounts(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 4, counts.UnreadCount)
+		require.Equal(t, []codersdk.InboxNotificationCategoryUnreadCount{
+			{Category: codersdk.NotificationCategoryBuilds, UnreadCount: 2},
+			{Category: codersdk.NotificationCategoryDormancy, UnreadCount: 1},
+			{Category: "", UnreadCount: 1},
+		}, counts.Categories)
+
+		// Mark a whole category as read.
+		resp, err := client.UpdateInboxNotificationsReadStatus(ctx, codersdk.UpdateInboxNotificationsReadStatusRequest{
+			Categories: []codersdk.NotificationCategory{codersdk.NotificationCategoryBuilds},
+			IsRead:     true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, resp.UpdatedCount)
+		require.Equal(t, 2, resp.UnreadCount)
+
+		// Notifications that are already read are left untouched.
+		resp, err = client.UpdateInboxNotificationsReadStatus(ctx, codersdk.UpdateInboxNotificationsReadStatusRequest{
+			IDs:    ids[:3],
+			IsRead: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, resp.UpdatedCount)
+		require.Equal(t, 1, resp.UnreadCount)
+
+		resp, err = client.UpdateInboxNotificationsReadStatus(ctx, codersdk.UpdateInboxNotificationsReadStatusRequest{
+			IDs: ids[:1],
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, resp.UpdatedCount)
+		require.Equal(t, 2, resp.UnreadCount)
+
+		counts, err = client.InboxNotificationUnreadCounts(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, counts.UnreadCount)
+		require.Equal(t, []codersdk.InboxNotificationCategoryUnreadCount{
+			{Category: codersdk.NotificationCategoryBuilds, UnreadCount: 1},
+			{Category: "", UnreadCount: 1},
+		}, counts.Categories)
+	})
+
+	t.Run("NOK - no notifications selected", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := coderdtest.NewWithAPI(t, &coderdtest.Options{})
+		firstUser := coderdtest.CreateFirstUser(t, client)
+		client, _ = coderdtest.CreateAnotherUser(t, client, firstUser.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateInboxNotificationsReadStatus(ctx, codersdk.UpdateInboxNotificationsReadStatusRequest{
+			IsRead: true,
+		})
+		require.ErrorContains(t, err, "At least one notification ID or category is required.")
+
+		_, err = client.UpdateInboxNotificationsReadStatus(ctx, codersdk.UpdateInboxNotificationsReadStatusRequest{
+			Categories: []codersdk.NotificationCategory{"unknown"},
+			IsRead:     true,
+		})
+		require.ErrorContains(t, err, "Invalid notification category.")
+	})
+
+	t.Run("other users are not affected", func(t *testing.T) {
+		t.Parallel()
+		client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{})
+		firstUser := coderdtest.CreateFirstUser(t, client)
+		client, _ = coderdtest.CreateAnotherUser(t, client, firstUser.OrganizationID)
+		otherClient, other := coderdtest.CreateAnotherUser(t, client, firstUser.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		notif := dbgen.NotificationInbox(t, api.Database, database.InsertInboxNotificationParams{
+			ID:         uuid.New(),
+			UserID:     other.ID,
+			TemplateID: notifications.TemplateWorkspaceDeleted,
+			Title:      "Notification",
+			Actions:    json.RawMessage("[]"),
+			Content:    "Content of the notif",
+			CreatedAt:  dbtime.Now(),
+		})
+
+		resp, err := client.UpdateInboxNotificationsReadStatus(ctx, codersdk.UpdateInboxNotificationsReadStatusRequest{
+			IDs:    []uuid.UUID{notif.ID},
+			IsRead: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 0, resp.UpdatedCount)
+
+		notifs, err := otherClient.ListInboxNotifications(ctx, codersdk.ListInboxNotificationsRequest{})
+		require.NoError(t, err)
+		require.Equal(t, 1, notifs.UnreadCount)
+	})
+}
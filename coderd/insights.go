@@ -1,11 +1,17 @@
 package coderd
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/csv"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -293,6 +299,31 @@ func (api *API) insightsUserLatency(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
+// @Summary Get insights about workspace connection quality
+// @ID get-insights-about-workspace-connection-quality
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Success 200 {object} codersdk.ConnectionQualityResponse
+// @Router /insights/connection-quality [get]
+func (api *API) insightsConnectionQuality(rw http.ResponseWriter, r *http.Request) {
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentConfig) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	ctx := r.Context()
+	report, err := api.connectionQuality.Report(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching connection quality.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, report)
+}
+
 // @Summary Get insights about user status counts
 // @ID get-insights-about-user-status-counts
 // @Security CoderSessionToken
@@ -533,6 +564,425 @@ func (api *API) insightsTemplates(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
+// @Summary Get insights about template builds
+// @ID get-insights-about-template-builds
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param template_id query string true "Template ID" format(uuid)
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param interval query string false "Interval" enums(week,day)
+// @Success 200 {object} codersdk.TemplateBuildInsightsResponse
+// @Router /insights/template-builds [get]
+func (api *API) insightsTemplateBuilds(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("template_id").
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		templateID = p.UUID(vals, uuid.Nil, "template_id")
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		intervalString  = p.String(vals, string(codersdk.InsightsReportIntervalDay), "interval")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+	interval, ok := parseInsightsInterval(ctx, rw, intervalString, startTime, endTime)
+	if !ok {
+		return
+	}
+	if interval == "" {
+		interval = codersdk.InsightsReportIntervalDay
+	}
+
+	var intervalRows []database.GetTemplateBuildInsightsByIntervalRow
+	var stageRows []database.GetTemplateBuildStageDurationsRow
+	var failureRows []database.GetTemplateBuildFailuresRow
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(3)
+
+	eg.Go(func() error {
+		var err error
+		intervalRows, err = api.Database.GetTemplateBuildInsightsByInterval(egCtx, database.GetTemplateBuildInsightsByIntervalParams{
+			TemplateID:   templateID,
+			StartTime:    startTime,
+			EndTime:      endTime,
+			IntervalDays: interval.Days(),
+		})
+		if err != nil {
+			return xerrors.Errorf("get template build insights by interval: %w", err)
+		}
+		return nil
+	})
+	eg.Go(func() error {
+		var err error
+		stageRows, err = api.Database.GetTemplateBuildStageDurations(egCtx, database.GetTemplateBuildStageDurationsParams{
+			TemplateID: templateID,
+			StartTime:  startTime,
+			EndTime:    endTime,
+		})
+		if err != nil {
+			return xerrors.Errorf("get template build stage durations: %w", err)
+		}
+		return nil
+	})
+	eg.Go(func() error {
+		var err error
+		failureRows, err = api.Database.GetTemplateBuildFailures(egCtx, database.GetTemplateBuildFailuresParams{
+			TemplateID: templateID,
+			StartTime:  startTime,
+			EndTime:    endTime,
+			LimitCount: maxTemplateBuildFailureMessages,
+		})
+		if err != nil {
+			return xerrors.Errorf("get template build failures: %w", err)
+		}
+		return nil
+	})
+
+	err := eg.Wait()
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template build insights.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := codersdk.TemplateBuildInsightsResponse{
+		TemplateID:      templateID,
+		StartTime:       startTime,
+		EndTime:         endTime,
+		IntervalReports: []codersdk.TemplateBuildInsightsIntervalReport{},
+		StageDurations:  convertTemplateBuildStageDurations(stageRows),
+		FailureGroups:   groupTemplateBuildFailures(failureRows, maxTemplateBuildFailureGroups),
+	}
+	for _, row := range intervalRows {
+		var failureRate float64
+		if completed := row.SucceededBuilds + row.FailedBuilds; completed > 0 {
+			failureRate = float64(row.FailedBuilds) / float64(completed)
+		}
+		resp.IntervalReports = append(resp.IntervalReports, codersdk.TemplateBuildInsightsIntervalReport{
+			StartTime:       row.StartTime.In(startTime.Location()),
+			EndTime:         row.EndTime.In(startTime.Location()),
+			Interval:        interval,
+			SucceededBuilds: row.SucceededBuilds,
+			FailedBuilds:    row.FailedBuilds,
+			CanceledBuilds:  row.CanceledBuilds,
+			FailureRate:     failureRate,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+const (
+	// maxTemplateBuildFailureMessages is the maximum number of distinct
+	// error messages fetched for grouping.
+	maxTemplateBuildFailureMessages = 1000
+	// maxTemplateBuildFailureGroups is the maximum number of failure groups
+	// returned by the template build insights endpoint.
+	maxTemplateBuildFailureGroups = 20
+)
+
+var templateBuildStageOrder = []codersdk.TemplateBuildStage{
+	codersdk.TemplateBuildStageTotal,
+	codersdk.TemplateBuildStageInit,
+	codersdk.TemplateBuildStagePlan,
+	codersdk.TemplateBuildStageGraph,
+	codersdk.TemplateBuildStageApply,
+}
+
+// convertTemplateBuildStageDurations converts the stage duration rows, ordered
+// as the stages run with the whole build first.
+func convertTemplateBuildStageDurations(rows []database.GetTemplateBuildStageDurationsRow) []codersdk.TemplateBuildStageDuration {
+	durations := make([]codersdk.TemplateBuildStageDuration, 0, len(rows))
+	for _, row := range rows {
+		durations = append(durations, codersdk.TemplateBuildStageDuration{
+			Stage:      codersdk.TemplateBuildStage(row.Stage),
+			Builds:     row.Builds,
+			P50Seconds: row.P50Seconds,
+			P95Seconds: row.P95Seconds,
+		})
+	}
+	slices.SortFunc(durations, func(a, b codersdk.TemplateBuildStageDuration) int {
+		return slices.Index(templateBuildStageOrder, a.Stage) - slices.Index(templateBuildStageOrder, b.Stage)
+	})
+	return durations
+}
+
+var (
+	templateBuildFailureUUIDRe   = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	templateBuildFailureQuotedRe = regexp.MustCompile(`"[^"\n]*"`)
+	templateBuildFailureHexRe    = regexp.MustCompile(`(?i)\b[0-9a-f]{7,}\b`)
+	templateBuildFailureNumberRe = regexp.MustCompile(`\b\d+(\.\d+)*\b`)
+	templateBuildFailureSpaceRe  = regexp.MustCompile(`\s+`)
+)
+
+// templateBuildFailurePattern replaces the variable parts of a build error
+// message, such as IDs, hashes, numbers and quoted values, with placeholders
+// so that similar failures can be grouped together.
+func templateBuildFailurePattern(message string) string {
+	message = templateBuildFailureUUIDRe.ReplaceAllString(message, "<uuid>")
+	message = templateBuildFailureQuotedRe.ReplaceAllString(message, `"<value>"`)
+	message = templateBuildFailureHexRe.ReplaceAllStringFunc(message, func(s string) string {
+		// Only treat words mixing digits and letters as hashes, plain numbers
+		// are handled below and plain words are kept.
+		if strings.ContainsAny(s, "0123456789") && strings.ContainsAny(strings.ToLower(s), "abcdef") {
+			return "<hex>"
+		}
+		return s
+	})
+	message = templateBuildFailureNumberRe.ReplaceAllString(message, "<n>")
+	message = templateBuildFailureSpaceRe.ReplaceAllString(message, " ")
+	return strings.TrimSpace(message)
+}
+
+// groupTemplateBuildFailures groups build failures by the pattern of their
+// error message and returns at most limit groups, most frequent first. The
+// rows must be ordered by the number of builds, descending.
+func groupTemplateBuildFailures(rows []database.GetTemplateBuildFailuresRow, limit int) []codersdk.TemplateBuildFailureGroup {
+	groups := []codersdk.TemplateBuildFailureGroup{}
+	byPattern := make(map[string]int)
+	for _, row := range rows {
+		pattern := templateBuildFailurePattern(row.Error)
+		i, ok := byPattern[pattern]
+		if !ok {
+			byPattern[pattern] = len(groups)
+			groups = append(groups, codersdk.TemplateBuildFailureGroup{
+				Pattern:      pattern,
+				Example:      row.Error,
+				Builds:       row.Builds,
+				LastFailedAt: row.LastFailedAt,
+			})
+			continue
+		}
+		groups[i].Builds += row.Builds
+		if row.LastFailedAt.After(groups[i].LastFailedAt) {
+			groups[i].LastFailedAt = row.LastFailedAt
+		}
+	}
+	slices.SortStableFunc(groups, func(a, b codersdk.TemplateBuildFailureGroup) int {
+		if a.Builds != b.Builds {
+			return int(b.Builds - a.Builds)
+		}
+		return b.LastFailedAt.Compare(a.LastFailedAt)
+	})
+	if len(groups) > limit {
+		groups = groups[:limit]
+	}
+	return groups
+}
+
+// @Summary Get workspace usage report
+// @Description Returns the runtime hours, accrued quota cost and last activity of the
+// @Description workspaces of each user or group, as JSON or as CSV.
+// @ID get-workspace-usage-report
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param group_by query string false "Group by" enums(user,group)
+// @Param format query string false "Format" enums(json,csv)
+// @Success 200 {object} codersdk.WorkspaceUsageReportResponse
+// @Router /insights/workspace-usage [get]
+func (api *API) insightsWorkspaceUsage(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		groupBy         = httpapi.ParseCustom(p, vals, codersdk.WorkspaceUsageReportGroupByUser, "group_by", httpapi.ParseEnum[codersdk.WorkspaceUsageReportGroupBy])
+		format          = httpapi.ParseCustom(p, vals, codersdk.WorkspaceUsageReportFormatJSON, "format", httpapi.ParseEnum[codersdk.WorkspaceUsageReportFormat])
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+
+	resp := codersdk.WorkspaceUsageReportResponse{
+		StartTime: startTime,
+		EndTime:   endTime,
+		GroupBy:   groupBy,
+	}
+	switch groupBy {
+	case codersdk.WorkspaceUsageReportGroupByUser:
+		rows, err := api.Database.GetUserWorkspaceUsage(ctx, database.GetUserWorkspaceUsageParams{
+			StartTime: startTime,
+			EndTime:   endTime,
+		})
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching user workspace usage.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		resp.Users = db2sdk.List(rows, db2sdk.UserWorkspaceUsage)
+	case codersdk.WorkspaceUsageReportGroupByGroup:
+		rows, err := api.Database.GetGroupWorkspaceUsage(ctx, database.GetGroupWorkspaceUsageParams{
+			StartTime: startTime,
+			EndTime:   endTime,
+		})
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching group workspace usage.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		resp.Groups = db2sdk.List(rows, db2sdk.GroupWorkspaceUsage)
+	}
+
+	if format == codersdk.WorkspaceUsageReportFormatJSON {
+		httpapi.Write(ctx, rw, http.StatusOK, resp)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := writeWorkspaceUsageCSV(&buf, resp); err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error writing workspace usage report.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	filename := fmt.Sprintf("workspace-usage-by-%s-%s-%s.csv", groupBy, startTime.Format(time.DateOnly), endTime.Format(time.DateOnly))
+	rw.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(buf.Bytes())
+}
+
+// writeWorkspaceUsageCSV writes the entries of the workspace usage report as
+// CSV with a header row. Times are formatted as RFC 3339 and left empty when
+// unknown.
+func writeWorkspaceUsageCSV(w io.Writer, resp codersdk.WorkspaceUsageReportResponse) error {
+	formatFloat := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', 2, 64)
+	}
+	formatTime := func(t time.Time) string {
+		// Unset times default to 0001-01-01 in the database.
+		if t.Year() <= 1 {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	// Every quota dimension gets a column, rows without usage in a dimension
+	// report zero hours.
+	dimensionSet := map[string]struct{}{}
+	for _, u := range resp.Users {
+		for dimension := range u.DimensionHours {
+			dimensionSet[dimension] = struct{}{}
+		}
+	}
+	for _, g := range resp.Groups {
+		for dimension := range g.DimensionHours {
+			dimensionSet[dimension] = struct{}{}
+		}
+	}
+	dimensions := slices.Sorted(maps.Keys(dimensionSet))
+	dimensionHeaders := make([]string, 0, len(dimensions))
+	for _, dimension := range dimensions {
+		dimensionHeaders = append(dimensionHeaders, dimension+"_hours")
+	}
+	dimensionHours := func(hours map[string]float64) []string {
+		values := make([]string, 0, len(dimensions))
+		for _, dimension := range dimensions {
+			values = append(values, formatFloat(hours[dimension]))
+		}
+		return values
+	}
+
+	cw := csv.NewWriter(w)
+	switch resp.GroupBy {
+	case codersdk.WorkspaceUsageReportGroupByGroup:
+		header := []string{"group_id", "group_name", "group_display_name", "organization_id", "organization_name", "users", "workspaces", "runtime_hours", "cost"}
+		header = append(header, dimensionHeaders...)
+		_ = cw.Write(append(header, "last_workspace_used_at"))
+		for _, g := range resp.Groups {
+			record := []string{
+				g.GroupID.String(),
+				g.GroupName,
+				g.GroupDisplayName,
+				g.OrganizationID.String(),
+				g.OrganizationName,
+				strconv.FormatInt(g.Users, 10),
+				strconv.FormatInt(g.Workspaces, 10),
+				formatFloat(g.RuntimeHours),
+				formatFloat(g.Cost),
+			}
+			record = append(record, dimensionHours(g.DimensionHours)...)
+			_ = cw.Write(append(record, formatTime(g.LastWorkspaceUsedAt)))
+		}
+	default:
+		header := []string{"user_id", "username", "name", "email", "workspaces", "runtime_hours", "cost"}
+		header = append(header, dimensionHeaders...)
+		_ = cw.Write(append(header, "last_seen_at", "last_workspace_used_at"))
+		for _, u := range resp.Users {
+			record := []string{
+				u.UserID.String(),
+				u.Username,
+				u.Name,
+				u.Email,
+				strconv.FormatInt(u.Workspaces, 10),
+				formatFloat(u.RuntimeHours),
+				formatFloat(u.Cost),
+			}
+			record = append(record, dimensionHours(u.DimensionHours)...)
+			_ = cw.Write(append(record, formatTime(u.LastSeenAt), formatTime(u.LastWorkspaceUsedAt)))
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
 // convertTemplateInsightsApps builds the list of builtin apps and template apps
 // from the provided database rows, builtin apps are implicitly a part of all
 // templates.
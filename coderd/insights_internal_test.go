@@ -1,14 +1,17 @@
 package coderd
 
 import (
+	"bytes"
 	"context"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -339,3 +342,133 @@ func stripTime(t time.Time) time.Time {
 	y, m, d := t.Date()
 	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
 }
+
+func Test_templateBuildFailurePattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{
+			message: "exit status 1",
+			want:    "exit status <n>",
+		},
+		{
+			message: `resource "docker_container.workspace[0]" failed: container 4f2a9c1b7e3d exited`,
+			want:    `resource "<value>" failed: container <hex> exited`,
+		},
+		{
+			message: "workspace 0e5c7d9a-3b4f-4c1e-9a8b-2d6f1e0c3a7b:\n\ttimeout after 300.5 seconds",
+			want:    "workspace <uuid>: timeout after <n> seconds",
+		},
+		{
+			message: "Error: facade decoded",
+			want:    "Error: facade decoded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, templateBuildFailurePattern(tt.message))
+		})
+	}
+}
+
+func Test_groupTemplateBuildFailures(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	rows := []database.GetTemplateBuildFailuresRow{
+		{Error: "pull image: manifest for abc1234 not found", Builds: 5, LastFailedAt: now.Add(-time.Hour)},
+		{Error: "exit status 1", Builds: 4, LastFailedAt: now.Add(-2 * time.Hour)},
+		{Error: "pull image: manifest for def5678 not found", Builds: 3, LastFailedAt: now},
+		{Error: "exit status 2", Builds: 2, LastFailedAt: now.Add(-time.Minute)},
+		{Error: "quota exceeded", Builds: 1, LastFailedAt: now},
+	}
+
+	groups := groupTemplateBuildFailures(rows, 2)
+	require.Len(t, groups, 2)
+	assert.Equal(t, codersdk.TemplateBuildFailureGroup{
+		Pattern:      "pull image: manifest for <hex> not found",
+		Example:      "pull image: manifest for abc1234 not found",
+		Builds:       8,
+		LastFailedAt: now,
+	}, groups[0])
+	assert.Equal(t, codersdk.TemplateBuildFailureGroup{
+		Pattern:      "exit status <n>",
+		Example:      "exit status 1",
+		Builds:       6,
+		LastFailedAt: now.Add(-time.Minute),
+	}, groups[1])
+}
+
+func Test_writeWorkspaceUsageCSV(t *testing.T) {
+	t.Parallel()
+
+	lastUsed := time.Date(2024, 5, 10, 12, 30, 0, 0, time.UTC)
+	userID := uuid.MustParse("0ed9befc-4911-4ccf-a8e2-559bf72daa94")
+	groupID := uuid.MustParse("4d4a0f3e-9c6f-4a22-9a9e-6a4b2f3c8f1d")
+	orgID := uuid.MustParse("703f72a1-76f6-4f89-9de6-8a3989693fe5")
+
+	t.Run("Users", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		err := writeWorkspaceUsageCSV(&buf, codersdk.WorkspaceUsageReportResponse{
+			GroupBy: codersdk.WorkspaceUsageReportGroupByUser,
+			Users: []codersdk.UserWorkspaceUsage{
+				{
+					UserID:              userID,
+					Username:            "alice",
+					Name:                "Alice, Liddell",
+					Email:               "alice@coder.com",
+					Workspaces:          2,
+					RuntimeHours:        12.345,
+					Cost:                3,
+					DimensionHours:      map[string]float64{"cpu": 49.38},
+					LastWorkspaceUsedAt: lastUsed,
+				},
+				{
+					UserID:         uuid.MustParse("1b7f5e0a-7e53-4c3c-9a5b-7d8f3c2e6a10"),
+					Username:       "bob",
+					Email:          "bob@coder.com",
+					Workspaces:     1,
+					RuntimeHours:   0.5,
+					DimensionHours: map[string]float64{"memory": 1},
+				},
+			},
+		})
+		require.NoError(t, err)
+		// Every dimension gets a column.
+		require.Equal(t, "user_id,username,name,email,workspaces,runtime_hours,cost,cpu_hours,memory_hours,last_seen_at,last_workspace_used_at\n"+
+			"0ed9befc-4911-4ccf-a8e2-559bf72daa94,alice,\"Alice, Liddell\",alice@coder.com,2,12.35,3.00,49.38,0.00,,2024-05-10T12:30:00Z\n"+
+			"1b7f5e0a-7e53-4c3c-9a5b-7d8f3c2e6a10,bob,,bob@coder.com,1,0.50,0.00,0.00,1.00,,\n", buf.String())
+	})
+
+	t.Run("Groups", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		err := writeWorkspaceUsageCSV(&buf, codersdk.WorkspaceUsageReportResponse{
+			GroupBy: codersdk.WorkspaceUsageReportGroupByGroup,
+			Groups: []codersdk.GroupWorkspaceUsage{
+				{
+					GroupID:             groupID,
+					GroupName:           "devs",
+					GroupDisplayName:    "Developers",
+					OrganizationID:      orgID,
+					OrganizationName:    "coder",
+					Users:               3,
+					Workspaces:          4,
+					RuntimeHours:        1.5,
+					Cost:                0.25,
+					LastWorkspaceUsedAt: lastUsed,
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "group_id,group_name,group_display_name,organization_id,organization_name,users,workspaces,runtime_hours,cost,last_workspace_used_at\n"+
+			"4d4a0f3e-9c6f-4a22-9a9e-6a4b2f3c8f1d,devs,Developers,703f72a1-76f6-4f89-9de6-8a3989693fe5,coder,3,4,1.50,0.25,2024-05-10T12:30:00Z\n", buf.String())
+	})
+}
@@ -2126,6 +2126,147 @@ func TestTemplateInsights_BadRequest(t *testing.T) {
 	assert.Error(t, err, "want error for bad section")
 }
 
+func TestTemplateBuildInsights(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	failing := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ApplyFailed,
+	}, func(req *codersdk.CreateTemplateVersionRequest) {
+		req.TemplateID = template.ID
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, failing.ID)
+
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	for i := 0; i < 2; i++ {
+		build := coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStart, func(req *codersdk.CreateWorkspaceBuildRequest) {
+			req.TemplateVersionID = failing.ID
+		})
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	y, m, d := time.Now().UTC().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	resp, err := client.TemplateBuildInsights(ctx, codersdk.TemplateBuildInsightsRequest{
+		TemplateID: template.ID,
+		StartTime:  today.AddDate(0, 0, -1),
+		EndTime:    time.Now().UTC().Truncate(time.Hour).Add(time.Hour),
+		Interval:   codersdk.InsightsReportIntervalDay,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.IntervalReports, 2)
+	assert.Zero(t, resp.IntervalReports[0].SucceededBuilds+resp.IntervalReports[0].FailedBuilds)
+	assert.EqualValues(t, 1, resp.IntervalReports[1].SucceededBuilds)
+	assert.EqualValues(t, 2, resp.IntervalReports[1].FailedBuilds)
+	assert.InDelta(t, 2.0/3.0, resp.IntervalReports[1].FailureRate, 0.001)
+
+	require.NotEmpty(t, resp.StageDurations)
+	assert.Equal(t, codersdk.TemplateBuildStageTotal, resp.StageDurations[0].Stage)
+	assert.EqualValues(t, 1, resp.StageDurations[0].Builds)
+
+	require.Len(t, resp.FailureGroups, 1)
+	assert.EqualValues(t, 2, resp.FailureGroups[0].Builds)
+	assert.Equal(t, "failed!", resp.FailureGroups[0].Example)
+
+	// Template build insights are only available to those who can view the
+	// template insights.
+	regular, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	_, err = regular.TemplateBuildInsights(ctx, codersdk.TemplateBuildInsightsRequest{
+		TemplateID: template.ID,
+		StartTime:  today.AddDate(0, 0, -1),
+		EndTime:    today,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
+func TestWorkspaceUsageReport(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	y, m, d := time.Now().UTC().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	req := codersdk.WorkspaceUsageReportRequest{
+		StartTime: today,
+		EndTime:   time.Now().UTC().Truncate(time.Hour).Add(time.Hour),
+		GroupBy:   codersdk.WorkspaceUsageReportGroupByUser,
+	}
+
+	t.Run("ByUser", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := client.WorkspaceUsageReport(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, resp.Users, 1)
+		assert.Equal(t, owner.UserID, resp.Users[0].UserID)
+		assert.EqualValues(t, 1, resp.Users[0].Workspaces)
+		assert.Positive(t, resp.Users[0].RuntimeHours)
+		assert.Empty(t, resp.Groups)
+	})
+
+	t.Run("ByGroup", func(t *testing.T) {
+		t.Parallel()
+
+		req := req
+		req.GroupBy = codersdk.WorkspaceUsageReportGroupByGroup
+		resp, err := client.WorkspaceUsageReport(ctx, req)
+		require.NoError(t, err)
+		// Every member of the organization is in the Everyone group.
+		require.Len(t, resp.Groups, 1)
+		assert.Equal(t, owner.OrganizationID, resp.Groups[0].GroupID)
+		assert.EqualValues(t, 1, resp.Groups[0].Users)
+		assert.EqualValues(t, 1, resp.Groups[0].Workspaces)
+		assert.Empty(t, resp.Users)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := client.WorkspaceUsageReportCSV(ctx, req)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, 2)
+		assert.True(t, strings.HasPrefix(lines[0], "user_id,username,"))
+		assert.True(t, strings.HasPrefix(lines[1], owner.UserID.String()+","))
+	})
+
+	t.Run("RegularUser", func(t *testing.T) {
+		t.Parallel()
+
+		// The report is only available to those who can view deployment
+		// stats.
+		regular, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		_, err := regular.WorkspaceUsageReport(ctx, req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
+
 func TestTemplateInsights_RBAC(t *testing.T) {
 	t.Parallel()
 
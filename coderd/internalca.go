@@ -0,0 +1,104 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/internalca"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// workspaceAgentIssueCertificate issues a short-lived client certificate to
+// the agent. Agents present it on their connections to coderd, which is
+// required when mTLS is enforced.
+//
+// @Summary Issue workspace agent client certificate
+// @ID issue-workspace-agent-client-certificate
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Agents
+// @Param request body codersdk.IssueCertificateRequest true "Certificate request"
+// @Success 200 {object} codersdk.IssuedCertificate
+// @Router /workspaceagents/me/certificate [post]
+func (api *API) workspaceAgentIssueCertificate(rw http.ResponseWriter, r *http.Request) {
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+	api.IssueInternalCertificate(rw, r, internalca.Identity{
+		Kind: internalca.KindAgent,
+		ID:   workspaceAgent.ID,
+	})
+}
+
+// IssueInternalCertificate signs the CSR in the request body for the identity
+// and writes the certificate to the response.
+func (api *API) IssueInternalCertificate(rw http.ResponseWriter, r *http.Request, identity internalca.Identity) {
+	ctx := r.Context()
+	if api.InternalCA == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "The internal CA is not enabled.",
+		})
+		return
+	}
+
+	var req codersdk.IssueCertificateRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	cert, err := api.InternalCA.Issue(ctx, []byte(req.CSR), identity)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to issue certificate.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.IssuedCertificate{
+		Certificate: string(cert.CertificatePEM),
+		CABundle:    string(cert.CABundlePEM),
+		ExpiresAt:   cert.ExpiresAt,
+	})
+}
+
+// RequireClientCertificateMW rejects requests that do not present a client
+// certificate issued by the internal CA to the identity returned by idFn. It
+// does nothing unless mTLS is enforced. It must run after the middleware
+// that authenticates the caller.
+func (api *API) RequireClientCertificateMW(kind internalca.Kind, idFn func(r *http.Request) uuid.UUID) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if api.InternalCA == nil || !api.DeploymentValues.InternalCA.RequireMTLS.Value() {
+				next.ServeHTTP(rw, r)
+				return
+			}
+
+			ctx := r.Context()
+			identity, err := api.InternalCA.VerifyRequest(r)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+					Message: "A valid client certificate is required.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			want := internalca.Identity{Kind: kind, ID: idFn(r)}
+			if identity != want {
+				api.Logger.Warn(ctx, "client certificate identity mismatch",
+					slog.F("certificate", identity.String()),
+					slog.F("expected", want.String()),
+				)
+				httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+					Message: "The client certificate was not issued to the authenticated caller.",
+				})
+				return
+			}
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
@@ -0,0 +1,523 @@
+// Package internalca implements a private certificate authority for a Coder
+// deployment. It issues short-lived client certificates to workspace agents,
+// workspace proxies and provisioner daemons so their control-plane traffic to
+// coderd can be authenticated with mutual TLS.
+//
+// The CA is generated on first start and stored in the database, so every
+// replica signs with the same key. It is rotated well before it expires, and
+// certificates signed by the previous CA are accepted until that CA expires.
+package internalca
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/quartz"
+)
+
+const (
+	// DefaultCertificateTTL is the default lifetime of issued certificates.
+	DefaultCertificateTTL = 24 * time.Hour
+	// DefaultCALifetime is the lifetime of a generated CA.
+	DefaultCALifetime = 365 * 24 * time.Hour
+	// DefaultRotateBefore is how long before the current CA expires that a
+	// new one is generated. It must be longer than the certificate TTL so
+	// clients have renewed against the new CA before the old one expires.
+	DefaultRotateBefore = 90 * 24 * time.Hour
+
+	// reloadInterval is how often the CA is reloaded from the database to
+	// pick up rotations performed by other replicas.
+	reloadInterval = 10 * time.Minute
+	// clockSkew is subtracted from NotBefore of issued certificates.
+	clockSkew = time.Minute
+	// uriScheme is the scheme of the URI SAN identifying a certificate.
+	uriScheme = "coder"
+)
+
+// Kind is the kind of component a certificate is issued to.
+type Kind string
+
+const (
+	KindAgent             Kind = "agent"
+	KindWorkspaceProxy    Kind = "workspace-proxy"
+	KindProvisionerDaemon Kind = "provisioner-daemon"
+)
+
+// Identity is the component a certificate is issued to. It is encoded in the
+// certificate as a URI SAN of the form coder://<kind>/<id>.
+type Identity struct {
+	Kind Kind
+	ID   uuid.UUID
+}
+
+func (i Identity) URI() *url.URL {
+	return &url.URL{Scheme: uriScheme, Host: string(i.Kind), Path: "/" + i.ID.String()}
+}
+
+func (i Identity) String() string {
+	return i.URI().String()
+}
+
+// IdentityFromCertificate returns the identity encoded in a certificate
+// issued by the CA. The certificate must already be verified.
+func IdentityFromCertificate(cert *x509.Certificate) (Identity, error) {
+	for _, u := range cert.URIs {
+		if u.Scheme != uriScheme {
+			continue
+		}
+		id, err := uuid.Parse(strings.TrimPrefix(u.Path, "/"))
+		if err != nil {
+			return Identity{}, xerrors.Errorf("parse identity %q: %w", u, err)
+		}
+		switch kind := Kind(u.Host); kind {
+		case KindAgent, KindWorkspaceProxy, KindProvisionerDaemon:
+			return Identity{Kind: kind, ID: id}, nil
+		default:
+			return Identity{}, xerrors.Errorf("unknown identity kind %q", kind)
+		}
+	}
+	return Identity{}, xerrors.New("certificate has no coder identity")
+}
+
+// Certificate is a client certificate issued by the CA.
+type Certificate struct {
+	// CertificatePEM is the PEM encoded client certificate.
+	CertificatePEM []byte
+	// CABundlePEM contains every CA certificate currently trusted.
+	CABundlePEM []byte
+	ExpiresAt   time.Time
+}
+
+type Options struct {
+	Logger     slog.Logger
+	Database   database.Store
+	Clock      quartz.Clock
+	Registerer prometheus.Registerer
+
+	// CertificateTTL is the lifetime of issued certificates.
+	CertificateTTL time.Duration
+	// CALifetime and RotateBefore control the rotation of the CA itself.
+	CALifetime   time.Duration
+	RotateBefore time.Duration
+}
+
+// CA issues and verifies client certificates for a deployment.
+type CA struct {
+	opts    Options
+	logger  slog.Logger
+	db      database.Store
+	clock   quartz.Clock
+	metrics *metrics
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu sync.RWMutex
+	// authorities are ordered newest first. The first entry signs new
+	// certificates.
+	authorities []authority
+	pool        *x509.CertPool
+	bundle      []byte
+}
+
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// storedCA is the JSON document stored in site_configs.
+type storedCA struct {
+	Authorities []storedAuthority `json:"authorities"`
+}
+
+type storedAuthority struct {
+	Certificate string `json:"certificate"`
+	PrivateKey  string `json:"private_key"`
+}
+
+// New loads the CA from the database, generating or rotating it if needed,
+// and starts a background loop that keeps it up to date. Close stops the
+// loop.
+func New(ctx context.Context, opts Options) (*CA, error) {
+	if opts.Database == nil {
+		return nil, xerrors.New("database is required")
+	}
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+	if opts.Registerer == nil {
+		opts.Registerer = prometheus.NewRegistry()
+	}
+	if opts.CertificateTTL <= 0 {
+		opts.CertificateTTL = DefaultCertificateTTL
+	}
+	if opts.CALifetime <= 0 {
+		opts.CALifetime = DefaultCALifetime
+	}
+	if opts.RotateBefore <= 0 {
+		opts.RotateBefore = DefaultRotateBefore
+	}
+	if opts.RotateBefore >= opts.CALifetime {
+		return nil, xerrors.New("rotate before must be shorter than the ca lifetime")
+	}
+	if opts.CertificateTTL >= opts.RotateBefore {
+		return nil, xerrors.New("certificate ttl must be shorter than the ca rotation window")
+	}
+
+	//nolint:gocritic // The CA is stored in site configs, which are system resources.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	ca := &CA{
+		opts:    opts,
+		logger:  opts.Logger.Named("internalca"),
+		db:      opts.Database,
+		clock:   opts.Clock,
+		metrics: newMetrics(opts.Registerer),
+		done:    make(chan struct{}),
+	}
+	err := ca.rotate(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("load internal ca: %w", err)
+	}
+
+	ctx, ca.cancel = context.WithCancel(ctx)
+	go ca.run(ctx)
+	return ca, nil
+}
+
+func (c *CA) run(ctx context.Context) {
+	defer close(c.done)
+	tkr := c.clock.TickerFunc(ctx, reloadInterval, func() error {
+		err := c.rotate(ctx)
+		if err != nil {
+			c.logger.Error(ctx, "rotate internal ca", slog.Error(err))
+		}
+		return nil
+	}, "internalca", "rotate")
+	_ = tkr.Wait()
+}
+
+// Close stops the background rotation loop.
+func (c *CA) Close() error {
+	c.cancel()
+	<-c.done
+	return nil
+}
+
+// rotate loads the CA from the database, dropping expired authorities and
+// generating a new one when the newest is about to expire. It holds an
+// advisory lock so only one replica rotates at a time.
+func (c *CA) rotate(ctx context.Context) error {
+	var authorities []authority
+	err := c.db.InTx(func(tx database.Store) error {
+		err := tx.AcquireLock(ctx, database.LockIDInternalCARotation)
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+
+		raw, err := tx.GetInternalCA(ctx)
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("get internal ca: %w", err)
+		}
+		authorities, err = decodeAuthorities(raw)
+		if err != nil {
+			return err
+		}
+
+		now := c.clock.Now()
+		valid := authorities[:0]
+		for _, a := range authorities {
+			if now.Before(a.cert.NotAfter) {
+				valid = append(valid, a)
+			}
+		}
+		changed := len(valid) != len(authorities)
+		authorities = valid
+
+		if len(authorities) == 0 || authorities[0].cert.NotAfter.Sub(now) < c.opts.RotateBefore {
+			a, err := c.generate(now)
+			if err != nil {
+				return xerrors.Errorf("generate ca: %w", err)
+			}
+			authorities = append([]authority{a}, authorities...)
+			changed = true
+			c.logger.Info(ctx, "generated internal ca",
+				slog.F("serial", a.cert.SerialNumber.String()),
+				slog.F("expires_at", a.cert.NotAfter),
+			)
+		}
+		if !changed {
+			return nil
+		}
+
+		encoded, err := encodeAuthorities(authorities)
+		if err != nil {
+			return err
+		}
+		err = tx.UpsertInternalCA(ctx, encoded)
+		if err != nil {
+			return xerrors.Errorf("upsert internal ca: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	pool := x509.NewCertPool()
+	var bundle []byte
+	for _, a := range authorities {
+		pool.AddCert(a.cert)
+		bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: a.cert.Raw})...)
+	}
+
+	c.mu.Lock()
+	c.authorities = authorities
+	c.pool = pool
+	c.bundle = bundle
+	c.mu.Unlock()
+
+	c.metrics.caExpiry.Set(float64(authorities[0].cert.NotAfter.Unix()))
+	return nil
+}
+
+func (c *CA) generate(now time.Time) (authority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return authority{}, xerrors.Errorf("generate key: %w", err)
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return authority{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"Coder"},
+			CommonName:   "Coder Internal CA " + serial.Text(16)[:8],
+		},
+		NotBefore:             now.Add(-clockSkew),
+		NotAfter:              now.Add(c.opts.CALifetime),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return authority{}, xerrors.Errorf("create certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return authority{}, xerrors.Errorf("parse certificate: %w", err)
+	}
+	return authority{cert: cert, key: key}, nil
+}
+
+// Issue signs the public key of a PEM encoded certificate signing request for
+// the given identity. Only the public key of the request is used; the subject
+// and extensions are set by the CA.
+func (c *CA) Issue(ctx context.Context, csrPEM []byte, identity Identity) (Certificate, error) {
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return Certificate{}, xerrors.New("csr must be a PEM encoded CERTIFICATE REQUEST")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return Certificate{}, xerrors.Errorf("parse csr: %w", err)
+	}
+	err = csr.CheckSignature()
+	if err != nil {
+		return Certificate{}, xerrors.Errorf("check csr signature: %w", err)
+	}
+
+	c.mu.RLock()
+	signer := c.authorities[0]
+	bundle := c.bundle
+	c.mu.RUnlock()
+
+	now := c.clock.Now()
+	notAfter := now.Add(c.opts.CertificateTTL)
+	if notAfter.After(signer.cert.NotAfter) {
+		notAfter = signer.cert.NotAfter
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return Certificate{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"Coder"},
+			CommonName:   identity.String(),
+		},
+		URIs:        []*url.URL{identity.URI()},
+		NotBefore:   now.Add(-clockSkew),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer.cert, csr.PublicKey, signer.key)
+	if err != nil {
+		return Certificate{}, xerrors.Errorf("create certificate: %w", err)
+	}
+
+	c.metrics.issued.WithLabelValues(string(identity.Kind)).Inc()
+	c.metrics.issuedExpiry.WithLabelValues(string(identity.Kind)).Set(float64(notAfter.Unix()))
+	c.logger.Debug(ctx, "issued client certificate",
+		slog.F("identity", identity.String()),
+		slog.F("serial", serial.String()),
+		slog.F("expires_at", notAfter),
+	)
+	return Certificate{
+		CertificatePEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		CABundlePEM:    bundle,
+		ExpiresAt:      notAfter,
+	}, nil
+}
+
+// Pool returns the pool of trusted CA certificates.
+func (c *CA) Pool() *x509.CertPool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.pool
+}
+
+// Verify verifies a client certificate chain against the CA and returns the
+// identity it was issued to.
+func (c *CA) Verify(chain []*x509.Certificate) (Identity, error) {
+	if len(chain) == 0 {
+		return Identity{}, xerrors.New("no client certificate")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		Roots:         c.Pool(),
+		Intermediates: intermediates,
+		CurrentTime:   c.clock.Now(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return Identity{}, xerrors.Errorf("verify client certificate: %w", err)
+	}
+	return IdentityFromCertificate(chain[0])
+}
+
+// VerifyRequest verifies the client certificate presented on the TLS
+// connection of a request.
+func (c *CA) VerifyRequest(r *http.Request) (Identity, error) {
+	if r.TLS == nil {
+		return Identity{}, xerrors.New("request was not made over tls")
+	}
+	return c.Verify(r.TLS.PeerCertificates)
+}
+
+// ConfigureServerTLS makes a server TLS config request client certificates
+// signed by the CA. Client certificates remain optional at the TLS layer so
+// browsers and CLI clients can still connect; handlers that require one use
+// VerifyRequest. Client CAs already set on the config are still trusted.
+func (c *CA) ConfigureServerTLS(cfg *tls.Config) {
+	base := cfg.Clone()
+	if base.ClientAuth < tls.VerifyClientCertIfGiven {
+		base.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c.mu.RLock()
+		authorities := c.authorities
+		c.mu.RUnlock()
+
+		var pool *x509.CertPool
+		if base.ClientCAs != nil {
+			pool = base.ClientCAs.Clone()
+		} else {
+			pool = x509.NewCertPool()
+		}
+		for _, a := range authorities {
+			pool.AddCert(a.cert)
+		}
+		clientCfg := base.Clone()
+		clientCfg.ClientCAs = pool
+		return clientCfg, nil
+	}
+}
+
+func decodeAuthorities(raw string) ([]authority, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var stored storedCA
+	err := json.Unmarshal([]byte(raw), &stored)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal internal ca: %w", err)
+	}
+	authorities := make([]authority, 0, len(stored.Authorities))
+	for _, s := range stored.Authorities {
+		certBlock, _ := pem.Decode([]byte(s.Certificate))
+		if certBlock == nil {
+			return nil, xerrors.New("invalid ca certificate pem")
+		}
+		cert, err := x509.ParseCertificate(certBlock.Bytes)
+		if err != nil {
+			return nil, xerrors.Errorf("parse ca certificate: %w", err)
+		}
+		keyBlock, _ := pem.Decode([]byte(s.PrivateKey))
+		if keyBlock == nil {
+			return nil, xerrors.New("invalid ca private key pem")
+		}
+		key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+		if err != nil {
+			return nil, xerrors.Errorf("parse ca private key: %w", err)
+		}
+		authorities = append(authorities, authority{cert: cert, key: key})
+	}
+	return authorities, nil
+}
+
+func encodeAuthorities(authorities []authority) (string, error) {
+	stored := storedCA{Authorities: make([]storedAuthority, 0, len(authorities))}
+	for _, a := range authorities {
+		keyDER, err := x509.MarshalECPrivateKey(a.key)
+		if err != nil {
+			return "", xerrors.Errorf("marshal ca private key: %w", err)
+		}
+		stored.Authorities = append(stored.Authorities, storedAuthority{
+			Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: a.cert.Raw})),
+			PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+		})
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return "", xerrors.Errorf("marshal internal ca: %w", err)
+	}
+	return string(data), nil
+}
+
+func randomSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, xerrors.Errorf("generate serial: %w", err)
+	}
+	return serial, nil
+}
@@ -0,0 +1,215 @@
+package internalca_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/pem"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/quartz"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/internalca"
+	cdrtestutil "github.com/coder/coder/v2/testutil"
+)
+
+func TestCA(t *testing.T) {
+	t.Parallel()
+
+	t.Run("IssueAndVerify", func(t *testing.T) {
+		t.Parallel()
+		ctx := cdrtestutil.Context(t, cdrtestutil.WaitShort)
+		mClock := quartz.NewMock(t)
+		reg := prometheus.NewRegistry()
+		db := &fakeStore{}
+
+		ca, err := internalca.New(ctx, internalca.Options{
+			Logger:         slogtest.Make(t, nil),
+			Database:       db,
+			Clock:          mClock,
+			Registerer:     reg,
+			CertificateTTL: time.Hour,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ca.Close() })
+		require.NotEmpty(t, db.value)
+
+		identity := internalca.Identity{Kind: internalca.KindAgent, ID: uuid.New()}
+		issued, err := ca.Issue(ctx, newCSR(t), identity)
+		require.NoError(t, err)
+		require.Equal(t, mClock.Now().Add(time.Hour), issued.ExpiresAt)
+
+		cert := parseCertificate(t, issued.CertificatePEM)
+		got, err := ca.Verify([]*x509.Certificate{cert})
+		require.NoError(t, err)
+		require.Equal(t, identity, got)
+
+		for _, name := range []string{
+			"coderd_internal_ca_expiry_timestamp_seconds",
+			"coderd_internal_ca_certificates_issued_total",
+			"coderd_internal_ca_issued_certificate_expiry_timestamp_seconds",
+		} {
+			count, err := testutil.GatherAndCount(reg, name)
+			require.NoError(t, err)
+			require.Equal(t, 1, count, name)
+		}
+
+		// Certificates are rejected once they expire.
+		require.NoError(t, ca.Close())
+		mClock.Set(issued.ExpiresAt.Add(time.Second))
+		_, err = ca.Verify([]*x509.Certificate{cert})
+		require.Error(t, err)
+	})
+
+	t.Run("Rotate", func(t *testing.T) {
+		t.Parallel()
+		ctx := cdrtestutil.Context(t, cdrtestutil.WaitShort)
+		mClock := quartz.NewMock(t)
+		db := &fakeStore{}
+		opts := internalca.Options{
+			Logger:         slogtest.Make(t, nil),
+			Database:       db,
+			Clock:          mClock,
+			CertificateTTL: 5 * 24 * time.Hour,
+			CALifetime:     30 * 24 * time.Hour,
+			RotateBefore:   10 * 24 * time.Hour,
+		}
+
+		first, err := internalca.New(ctx, opts)
+		require.NoError(t, err)
+		require.NoError(t, first.Close())
+		stored := db.value
+
+		// Another replica starting before the rotation window reuses the CA.
+		mClock.Set(mClock.Now().Add(19 * 24 * time.Hour))
+		second, err := internalca.New(ctx, opts)
+		require.NoError(t, err)
+		require.Equal(t, stored, db.value)
+		issued, err := second.Issue(ctx, newCSR(t), internalca.Identity{Kind: internalca.KindWorkspaceProxy, ID: uuid.New()})
+		require.NoError(t, err)
+		require.Len(t, splitPEM(t, issued.CABundlePEM), 1)
+		require.NoError(t, second.Close())
+
+		// Inside the window a new CA is generated, and certificates signed by
+		// the previous CA are still trusted.
+		mClock.Set(mClock.Now().Add(2 * 24 * time.Hour))
+		third, err := internalca.New(ctx, opts)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = third.Close() })
+		require.NotEqual(t, stored, db.value)
+
+		prev := parseCertificate(t, issued.CertificatePEM)
+		_, err = third.Verify([]*x509.Certificate{prev})
+		require.NoError(t, err)
+
+		renewed, err := third.Issue(ctx, newCSR(t), internalca.Identity{Kind: internalca.KindWorkspaceProxy, ID: uuid.New()})
+		require.NoError(t, err)
+		require.Len(t, splitPEM(t, renewed.CABundlePEM), 2)
+		cert := parseCertificate(t, renewed.CertificatePEM)
+		require.NotEqual(t, prev.Issuer.String(), cert.Issuer.String())
+	})
+
+	t.Run("InvalidCSR", func(t *testing.T) {
+		t.Parallel()
+		ctx := cdrtestutil.Context(t, cdrtestutil.WaitShort)
+		ca, err := internalca.New(ctx, internalca.Options{
+			Logger:   slogtest.Make(t, nil),
+			Database: &fakeStore{},
+			Clock:    quartz.NewMock(t),
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ca.Close() })
+
+		_, err = ca.Issue(ctx, []byte("not a csr"), internalca.Identity{Kind: internalca.KindAgent, ID: uuid.New()})
+		require.ErrorContains(t, err, "CERTIFICATE REQUEST")
+	})
+}
+
+func TestIdentityFromCertificate(t *testing.T) {
+	t.Parallel()
+
+	identity := internalca.Identity{Kind: internalca.KindProvisionerDaemon, ID: uuid.New()}
+	got, err := internalca.IdentityFromCertificate(&x509.Certificate{URIs: []*url.URL{identity.URI()}})
+	require.NoError(t, err)
+	require.Equal(t, identity, got)
+
+	_, err = internalca.IdentityFromCertificate(&x509.Certificate{})
+	require.ErrorContains(t, err, "no coder identity")
+}
+
+// fakeStore implements the queries used by the CA.
+type fakeStore struct {
+	database.Store
+
+	mu    sync.Mutex
+	value string
+}
+
+func (s *fakeStore) InTx(fn func(database.Store) error, _ *database.TxOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s)
+}
+
+func (*fakeStore) AcquireLock(context.Context, int64) error {
+	return nil
+}
+
+func (s *fakeStore) GetInternalCA(context.Context) (string, error) {
+	if s.value == "" {
+		return "", sql.ErrNoRows
+	}
+	return s.value, nil
+}
+
+func (s *fakeStore) UpsertInternalCA(_ context.Context, value string) error {
+	s.value = value
+	return nil
+}
+
+func newCSR(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "ignored"},
+	}, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+}
+
+func parseCertificate(t *testing.T, data []byte) *x509.Certificate {
+	t.Helper()
+	block, _ := pem.Decode(data)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
+
+func splitPEM(t *testing.T, data []byte) []*pem.Block {
+	t.Helper()
+	var blocks []*pem.Block
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return blocks
+		}
+		blocks = append(blocks, block)
+	}
+}
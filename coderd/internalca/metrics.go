@@ -0,0 +1,36 @@
+package internalca
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	ns        = "coderd"
+	subsystem = "internal_ca"
+
+	labelKind = "kind"
+)
+
+type metrics struct {
+	caExpiry     prometheus.Gauge
+	issued       *prometheus.CounterVec
+	issuedExpiry *prometheus.GaugeVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	return &metrics{
+		caExpiry: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "expiry_timestamp_seconds", Namespace: ns, Subsystem: subsystem,
+			Help: "The time at which the CA currently signing certificates expires, in seconds since the epoch.",
+		}),
+		issued: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "certificates_issued_total", Namespace: ns, Subsystem: subsystem,
+			Help: "The number of client certificates issued, by the kind of component they were issued to.",
+		}, []string{labelKind}),
+		issuedExpiry: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "issued_certificate_expiry_timestamp_seconds", Namespace: ns, Subsystem: subsystem,
+			Help: "The expiry of the most recently issued client certificate, by the kind of component it was issued to, in seconds since the epoch.",
+		}, []string{labelKind}),
+	}
+}
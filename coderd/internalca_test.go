@@ -0,0 +1,111 @@
+package coderd_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/internalca"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAgentIssueCertificate(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T, enable bool) *agentsdk.Client {
+		db, ps := dbtestutil.NewDB(t)
+		opts := &coderdtest.Options{
+			Database:                 db,
+			Pubsub:                   ps,
+			IncludeProvisionerDaemon: true,
+		}
+		if enable {
+			ca, err := internalca.New(testutil.Context(t, testutil.WaitShort), internalca.Options{
+				Logger:   slogtest.Make(t, nil),
+				Database: db,
+			})
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = ca.Close() })
+			opts.InternalCA = ca
+		}
+		client := coderdtest.New(t, opts)
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.PlanComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		return agentClient
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		agentClient := setup(t, true)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		issued, err := agentClient.IssueCertificate(ctx, codersdk.IssueCertificateRequest{CSR: newCSR(t)})
+		require.NoError(t, err)
+		require.NotEmpty(t, issued.CABundle)
+
+		block, _ := pem.Decode([]byte(issued.Certificate))
+		require.NotNil(t, block)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		identity, err := internalca.IdentityFromCertificate(cert)
+		require.NoError(t, err)
+		require.Equal(t, internalca.KindAgent, identity.Kind)
+	})
+
+	t.Run("NotEnabled", func(t *testing.T) {
+		t.Parallel()
+		agentClient := setup(t, false)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := agentClient.IssueCertificate(ctx, codersdk.IssueCertificateRequest{CSR: newCSR(t)})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidCSR", func(t *testing.T) {
+		t.Parallel()
+		agentClient := setup(t, true)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := agentClient.IssueCertificate(ctx, codersdk.IssueCertificateRequest{CSR: "invalid"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
+func newCSR(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
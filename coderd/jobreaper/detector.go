@@ -10,11 +10,14 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/provisionersdk"
 )
@@ -43,6 +46,15 @@ const (
 
 // jobLogMessages are written to provisioner job logs when a job is reaped
 func JobLogMessages(reapType ReapType, threshold time.Duration) []string {
+	if reapType == Orphaned {
+		return []string{
+			"",
+			"====================",
+			fmt.Sprintf("Coder: The provisioner daemon running this build has not sent a heartbeat for %.0f minutes. The build will be terminated.", threshold.Minutes()),
+			"====================",
+			"",
+		}
+	}
 	return []string{
 		"",
 		"====================",
@@ -63,8 +75,26 @@ type ReapType string
 const (
 	Pending ReapType = "pending"
 	Hung    ReapType = "hung"
+	// Orphaned jobs are running jobs whose provisioner daemon has stopped
+	// sending heartbeats.
+	Orphaned ReapType = "orphaned"
 )
 
+// OrphanedJobOptions configures how the detector handles running jobs whose
+// provisioner daemon has stopped sending heartbeats.
+type OrphanedJobOptions struct {
+	// Timeout is the duration of time since the last heartbeat of a job's
+	// provisioner daemon before the job is considered orphaned. Orphaned jobs
+	// are not detected if Timeout is zero.
+	Timeout time.Duration
+	// Requeue returns orphaned jobs to the queue so that another provisioner
+	// daemon can acquire them, instead of failing them.
+	Requeue bool
+	// MaxRequeues is the number of times a job can be requeued before it is
+	// failed.
+	MaxRequeues int64
+}
+
 // acquireLockError is returned when the detector fails to acquire a lock and
 // cancels the current run.
 type acquireLockError struct{}
@@ -97,6 +127,9 @@ type Detector struct {
 	log    slog.Logger
 	tick   <-chan time.Time
 	stats  chan<- Stats
+
+	orphaned     OrphanedJobOptions
+	orphanedJobs *prometheus.CounterVec
 }
 
 // Stats contains statistics about the last run of the detector.
@@ -104,6 +137,9 @@ type Stats struct {
 	// TerminatedJobIDs contains the IDs of all jobs that were detected as hung and
 	// terminated.
 	TerminatedJobIDs []uuid.UUID
+	// RequeuedJobIDs contains the IDs of all jobs that were detected as
+	// orphaned and returned to the queue.
+	RequeuedJobIDs []uuid.UUID
 	// Error is the fatal error that occurred during the last run of the
 	// detector, if any. Error may be set to AcquireLockError if the detector
 	// failed to acquire a lock.
@@ -135,6 +171,20 @@ func (d *Detector) WithStatsChannel(ch chan<- Stats) *Detector {
 	return d
 }
 
+// WithOrphanedJobs enables the detection of running jobs whose provisioner
+// daemon has stopped sending heartbeats. Orphaned jobs are requeued or failed
+// depending on opts, and counted in a metric registered with reg.
+func (d *Detector) WithOrphanedJobs(opts OrphanedJobOptions, reg prometheus.Registerer) *Detector {
+	d.orphaned = opts
+	d.orphanedJobs = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "provisionerd",
+		Name:      "orphaned_jobs_total",
+		Help:      "The number of running provisioner jobs that were requeued or failed because their provisioner daemon stopped sending heartbeats.",
+	}, []string{"action"})
+	return d
+}
+
 // Start will cause the detector to detect and unhang provisioner jobs on every
 // tick from its channel. It will stop when its context is Done, or when its
 // channel is closed.
@@ -186,9 +236,20 @@ func (d *Detector) run(t time.Time) Stats {
 
 	stats := Stats{
 		TerminatedJobIDs: []uuid.UUID{},
+		RequeuedJobIDs:   []uuid.UUID{},
 		Error:            nil,
 	}
 
+	// Orphaned jobs are handled first, otherwise they would be terminated as
+	// hung jobs once they haven't been updated for HungJobDuration.
+	if d.orphaned.Timeout > 0 {
+		err := d.handleOrphanedJobs(ctx, t, &stats)
+		if err != nil {
+			stats.Error = err
+			return stats
+		}
+	}
+
 	// Find all provisioner jobs to be reaped
 	jobs, err := d.db.GetProvisionerJobsToBeReaped(ctx, database.GetProvisionerJobsToBeReapedParams{
 		PendingSince: t.Add(-PendingJobDuration),
@@ -235,6 +296,168 @@ func (d *Detector) run(t time.Time) Stats {
 	return stats
 }
 
+func (d *Detector) handleOrphanedJobs(ctx context.Context, t time.Time, stats *Stats) error {
+	jobs, err := d.db.GetOrphanedProvisionerJobs(ctx, database.GetOrphanedProvisionerJobsParams{
+		StaleSince: t.Add(-d.orphaned.Timeout),
+		MaxJobs:    MaxJobsPerRun,
+	})
+	if err != nil {
+		return xerrors.Errorf("get orphaned provisioner jobs: %w", err)
+	}
+
+	for _, row := range jobs {
+		job := row.ProvisionerJob
+		log := d.log.With(slog.F("job_id", job.ID), slog.F("requeue_count", row.RequeueCount))
+
+		// Jobs that are being canceled are failed rather than requeued.
+		if d.orphaned.Requeue && !job.CanceledAt.Valid && row.RequeueCount < d.orphaned.MaxRequeues {
+			err := requeueJob(ctx, log, d.db, d.pubsub, job.ID, d.orphaned.Timeout, row.RequeueCount+1, d.orphaned.MaxRequeues)
+			if err != nil {
+				if !(xerrors.As(err, &acquireLockError{}) || xerrors.As(err, &jobIneligibleError{})) {
+					log.Error(ctx, "error requeueing orphaned provisioner job", slog.Error(err))
+				}
+				continue
+			}
+			d.orphanedJobs.WithLabelValues("requeued").Inc()
+			stats.RequeuedJobIDs = append(stats.RequeuedJobIDs, job.ID)
+			continue
+		}
+
+		err := reapJob(ctx, log, d.db, d.pubsub, &jobToReap{
+			ID:        job.ID,
+			Threshold: d.orphaned.Timeout,
+			Type:      Orphaned,
+		})
+		if err != nil {
+			if !(xerrors.As(err, &acquireLockError{}) || xerrors.As(err, &jobIneligibleError{})) {
+				log.Error(ctx, "error forcefully terminating orphaned provisioner job", slog.Error(err))
+			}
+			continue
+		}
+		d.orphanedJobs.WithLabelValues("failed").Inc()
+		stats.TerminatedJobIDs = append(stats.TerminatedJobIDs, job.ID)
+	}
+	return nil
+}
+
+// requeueJob returns a running job whose provisioner daemon has stopped
+// sending heartbeats to the queue, so that it is acquired by another
+// provisioner daemon.
+func requeueJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub.Pubsub, jobID uuid.UUID, threshold time.Duration, attempt, maxRequeues int64) error {
+	var (
+		lowestLogID int64
+		requeued    database.ProvisionerJob
+	)
+
+	err := db.InTx(func(db database.Store) error {
+		// Refetch the job while we hold the lock.
+		job, err := db.GetProvisionerJobByIDForUpdate(ctx, jobID)
+		if err != nil {
+			if xerrors.Is(err, sql.ErrNoRows) {
+				return acquireLockError{}
+			}
+			return xerrors.Errorf("get provisioner job: %w", err)
+		}
+
+		if job.CompletedAt.Valid || job.JobStatus != database.ProvisionerJobStatusRunning {
+			return jobIneligibleError{
+				Err: xerrors.Errorf("job is not running (status %s)", job.JobStatus),
+			}
+		}
+		if job.UpdatedAt.After(time.Now().Add(-threshold)) {
+			return jobIneligibleError{
+				Err: xerrors.New("job has been updated recently"),
+			}
+		}
+
+		log.Warn(ctx, "requeueing orphaned provisioner job", "attempt", attempt, "threshold", threshold)
+
+		logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
+			JobID:        job.ID,
+			CreatedAfter: 0,
+		})
+		if err != nil {
+			return xerrors.Errorf("get logs for orphaned job: %w", err)
+		}
+		logStage := ""
+		if len(logs) != 0 {
+			logStage = logs[len(logs)-1].Stage
+		}
+		if logStage == "" {
+			logStage = "Unknown"
+		}
+
+		insertParams := database.InsertProvisionerJobLogsParams{
+			JobID:     job.ID,
+			CreatedAt: nil,
+			Source:    nil,
+			Level:     nil,
+			Stage:     nil,
+			Output:    nil,
+		}
+		now := dbtime.Now()
+		for i, msg := range []string{
+			"",
+			"====================",
+			fmt.Sprintf("Coder: The provisioner daemon running this build has not sent a heartbeat for %.0f minutes. The build has been requeued (attempt %d of %d).", threshold.Minutes(), attempt, maxRequeues),
+			"====================",
+			"",
+		} {
+			insertParams.CreatedAt = append(insertParams.CreatedAt, now.Add(time.Millisecond*time.Duration(i)))
+			insertParams.Level = append(insertParams.Level, database.LogLevelWarn)
+			insertParams.Stage = append(insertParams.Stage, logStage)
+			insertParams.Source = append(insertParams.Source, database.LogSourceProvisionerDaemon)
+			insertParams.Output = append(insertParams.Output, msg)
+		}
+		newLogs, err := db.InsertProvisionerJobLogs(ctx, insertParams)
+		if err != nil {
+			return xerrors.Errorf("insert logs for orphaned job: %w", err)
+		}
+		lowestLogID = newLogs[0].ID
+
+		now = dbtime.Now()
+		err = db.RequeueProvisionerJob(ctx, database.RequeueProvisionerJobParams{
+			ID:        job.ID,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return xerrors.Errorf("requeue job: %w", err)
+		}
+		err = db.InsertProvisionerJobRequeue(ctx, database.InsertProvisionerJobRequeueParams{
+			JobID:     job.ID,
+			WorkerID:  job.WorkerID,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert job requeue: %w", err)
+		}
+		requeued = job
+		return nil
+	}, nil)
+	if err != nil {
+		return xerrors.Errorf("in tx: %w", err)
+	}
+
+	// Notify provisioner daemons waiting for jobs, and log streams that there
+	// are new logs. The job isn't done, so the log stream is kept open.
+	err = provisionerjobs.PostJob(pub, requeued)
+	if err != nil {
+		return xerrors.Errorf("post requeued job: %w", err)
+	}
+	data, err := json.Marshal(provisionersdk.ProvisionerJobLogsNotifyMessage{
+		CreatedAfter: lowestLogID - 1,
+	})
+	if err != nil {
+		return xerrors.Errorf("marshal log notification: %w", err)
+	}
+	err = pub.Publish(provisionersdk.ProvisionerJobLogsNotifyChannel(jobID), data)
+	if err != nil {
+		return xerrors.Errorf("publish log notification: %w", err)
+	}
+
+	return nil
+}
+
 func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub.Pubsub, jobToReap *jobToReap) error {
 	var lowestLogID int64
 
@@ -326,7 +549,7 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 				Valid: true,
 			},
 			Error: sql.NullString{
-				String: fmt.Sprintf("Coder: Build has been detected as %s for %.0f minutes and has been terminated by the reaper.", jobToReap.Type, jobToReap.Threshold.Minutes()),
+				String: reapErrorMessage(jobToReap),
 				Valid:  true,
 			},
 			ErrorCode: sql.NullString{
@@ -393,3 +616,11 @@ func reapJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub
 
 	return nil
 }
+
+// reapErrorMessage returns the error a reaped job is failed with.
+func reapErrorMessage(job *jobToReap) string {
+	if job.Type == Orphaned {
+		return fmt.Sprintf("Coder: The provisioner daemon running this build stopped sending heartbeats for %.0f minutes and the build has been terminated by the reaper. The provisioner daemon may have crashed or lost its connection to Coder.", job.Threshold.Minutes())
+	}
+	return fmt.Sprintf("Coder: Build has been detected as %s for %.0f minutes and has been terminated by the reaper.", job.Type, job.Threshold.Minutes())
+}
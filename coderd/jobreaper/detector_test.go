@@ -16,6 +16,7 @@ import (
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/coderdtest/promhelp"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
@@ -1028,6 +1029,95 @@ func TestDetectorMaxJobsPerRun(t *testing.T) {
 	detector.Wait()
 }
 
+func TestDetectorOrphanedJobs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = testutil.Context(t, testutil.WaitLong)
+		db, pubsub = dbtestutil.NewDB(t)
+		log        = testutil.Logger(t)
+		reg        = prometheus.NewRegistry()
+		tickCh     = make(chan time.Time)
+		statsCh    = make(chan jobreaper.Stats)
+	)
+
+	var (
+		now         = time.Now()
+		fourMinAgo  = now.Add(-time.Minute * 4)
+		tenMinAgo   = now.Add(-time.Minute * 10)
+		org         = dbgen.Organization(t, db, database.Organization{})
+		user        = dbgen.User(t, db, database.User{})
+		file        = dbgen.File(t, db, database.File{})
+		staleDaemon = dbgen.ProvisionerDaemon(t, db, database.ProvisionerDaemon{
+			OrganizationID: org.ID,
+			LastSeenAt:     sql.NullTime{Time: tenMinAgo, Valid: true},
+		})
+		healthyDaemon = dbgen.ProvisionerDaemon(t, db, database.ProvisionerDaemon{
+			OrganizationID: org.ID,
+			LastSeenAt:     sql.NullTime{Time: now, Valid: true},
+		})
+		runningJob = func(worker uuid.UUID) database.ProvisionerJob {
+			return dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
+				CreatedAt:      fourMinAgo,
+				UpdatedAt:      fourMinAgo,
+				StartedAt:      sql.NullTime{Time: fourMinAgo, Valid: true},
+				WorkerID:       uuid.NullUUID{UUID: worker, Valid: true},
+				OrganizationID: org.ID,
+				InitiatorID:    user.ID,
+				Provisioner:    database.ProvisionerTypeEcho,
+				StorageMethod:  database.ProvisionerStorageMethodFile,
+				FileID:         file.ID,
+				Type:           database.ProvisionerJobTypeTemplateVersionImport,
+				Input:          []byte("{}"),
+			})
+		}
+		requeuedJob = runningJob(staleDaemon.ID)
+		// This job has already been requeued the maximum number of times.
+		failedJob  = runningJob(staleDaemon.ID)
+		healthyJob = runningJob(healthyDaemon.ID)
+	)
+	err := db.InsertProvisionerJobRequeue(ctx, database.InsertProvisionerJobRequeueParams{
+		JobID:     failedJob.ID,
+		CreatedAt: tenMinAgo,
+	})
+	require.NoError(t, err)
+
+	detector := jobreaper.New(ctx, wrapDBAuthz(db, log), pubsub, log, tickCh).
+		WithOrphanedJobs(jobreaper.OrphanedJobOptions{
+			Timeout:     3 * time.Minute,
+			Requeue:     true,
+			MaxRequeues: 1,
+		}, reg).
+		WithStatsChannel(statsCh)
+	detector.Start()
+	tickCh <- now
+
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{requeuedJob.ID}, stats.RequeuedJobIDs)
+	require.Equal(t, []uuid.UUID{failedJob.ID}, stats.TerminatedJobIDs)
+
+	requeuedJob, err = db.GetProvisionerJobByID(ctx, requeuedJob.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.ProvisionerJobStatusPending, requeuedJob.JobStatus)
+	require.False(t, requeuedJob.WorkerID.Valid)
+
+	failedJob, err = db.GetProvisionerJobByID(ctx, failedJob.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.ProvisionerJobStatusFailed, failedJob.JobStatus)
+	require.Contains(t, failedJob.Error.String, "stopped sending heartbeats")
+
+	healthyJob, err = db.GetProvisionerJobByID(ctx, healthyJob.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.ProvisionerJobStatusRunning, healthyJob.JobStatus)
+
+	require.Equal(t, 1, promhelp.CounterValue(t, reg, "coderd_provisionerd_orphaned_jobs_total", prometheus.Labels{"action": "requeued"}))
+	require.Equal(t, 1, promhelp.CounterValue(t, reg, "coderd_provisionerd_orphaned_jobs_total", prometheus.Labels{"action": "failed"}))
+
+	detector.Close()
+	detector.Wait()
+}
+
 // wrapDBAuthz adds our Authorization/RBAC around the given database store, to
 // ensure the reaper has the right permissions to do its work.
 func wrapDBAuthz(db database.Store, logger slog.Logger) database.Store {
@@ -1,9 +1,12 @@
 package coderd
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -16,6 +19,8 @@ import (
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/searchquery"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -243,6 +248,125 @@ func (api *API) paginatedMembers(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
+// @Summary List members across organizations
+// @ID list-members-across-organizations
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param q query string false "Search query"
+// @Param limit query int false "Page limit, if 0 returns all members"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} codersdk.CrossOrganizationMembersResponse
+// @Router /members [get]
+func (api *API) crossOrganizationMembers(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	page, ok := parsePagination(rw, r)
+	if !ok {
+		return
+	}
+	filter, errs := searchquery.Members(ctx, api.Database, r.URL.Query().Get("q"))
+	if len(errs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid member search query.",
+			Validations: errs,
+		})
+		return
+	}
+
+	// Members of organizations the caller cannot read members of are
+	// filtered out.
+	rows, err := api.Database.OrganizationMembers(ctx, database.OrganizationMembersParams{
+		OrganizationID: filter.OrganizationID,
+		IncludeSystem:  false,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	orgs, err := api.Database.GetOrganizations(ctx, database.GetOrganizationsParams{})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	orgsByID := make(map[uuid.UUID]database.Organization, len(orgs))
+	for _, org := range orgs {
+		orgsByID[org.ID] = org
+	}
+
+	rows = slices.DeleteFunc(rows, func(row database.OrganizationMembersRow) bool {
+		if _, ok := orgsByID[row.OrganizationMember.OrganizationID]; !ok {
+			return true
+		}
+		return !memberMatches(filter, row)
+	})
+	slices.SortFunc(rows, func(a, b database.OrganizationMembersRow) int {
+		return cmp.Or(
+			cmp.Compare(orgsByID[a.OrganizationMember.OrganizationID].Name, orgsByID[b.OrganizationMember.OrganizationID].Name),
+			cmp.Compare(a.Username, b.Username),
+		)
+	})
+	count := len(rows)
+	rows = rows[min(page.Offset, len(rows)):]
+	if page.Limit > 0 {
+		rows = rows[:min(page.Limit, len(rows))]
+	}
+
+	members, err := convertOrganizationMembersWithUserData(ctx, api.Database, rows)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	// Emails are only included for members the caller can manage, either as
+	// an admin of the member's organization or of the member's user.
+	canManageOrg := make(map[uuid.UUID]bool)
+	resp := codersdk.CrossOrganizationMembersResponse{
+		Members: make([]codersdk.CrossOrganizationMember, 0, len(members)),
+		Count:   count,
+	}
+	for _, member := range members {
+		manage, ok := canManageOrg[member.OrganizationID]
+		if !ok {
+			manage = api.Authorize(r, policy.ActionUpdate, rbac.ResourceOrganizationMember.InOrg(member.OrganizationID))
+			canManageOrg[member.OrganizationID] = manage
+		}
+		if !manage && !api.Authorize(r, policy.ActionReadPersonal, rbac.ResourceUserObject(member.UserID)) {
+			member.Email = ""
+		}
+		org := orgsByID[member.OrganizationID]
+		resp.Members = append(resp.Members, codersdk.CrossOrganizationMember{
+			OrganizationMemberWithUserData: member,
+			OrganizationName:               org.Name,
+			OrganizationDisplayName:        org.DisplayName,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+func memberMatches(filter searchquery.MembersFilter, row database.OrganizationMembersRow) bool {
+	if filter.Search != "" {
+		matched := false
+		for _, field := range []string{row.Username, row.Name, row.Email} {
+			if strings.Contains(strings.ToLower(field), filter.Search) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(filter.Roles) > 0 {
+		roles := append(slices.Clone(row.OrganizationMember.Roles), row.GlobalRoles...)
+		if !slices.ContainsFunc(filter.Roles, func(role string) bool {
+			return slices.Contains(roles, role)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
 // @Summary Assign role to organization member
 // @ID assign-role-to-organization-member
 // @Security CoderSessionToken
@@ -332,7 +456,7 @@ func (api *API) allowChangingMemberRoles(ctx context.Context, rw http.ResponseWr
 		return false
 	}
 
-	if user.LoginType == database.LoginTypeOIDC {
+	if user.LoginType == database.LoginTypeOIDC || user.LoginType == database.LoginTypeSaml {
 		// nolint:gocritic // fetching settings
 		orgSync, err := api.IDPSync.OrganizationRoleSyncEnabled(dbauthz.AsSystemRestricted(ctx), api.Database, organization.ID)
 		if err != nil {
@@ -446,7 +570,7 @@ func convertOrganizationMembersWithUserData(ctx context.Context, db database.Sto
 // If organization sync is enabled, manual organization assignment is not allowed,
 // since all organization membership is controlled by the external IDP.
 func (api *API) manualOrganizationMembership(ctx context.Context, rw http.ResponseWriter, user database.User) bool {
-	if user.LoginType == database.LoginTypeOIDC && api.IDPSync.OrganizationSyncEnabled(ctx, api.Database) {
+	if (user.LoginType == database.LoginTypeOIDC || user.LoginType == database.LoginTypeSaml) && api.IDPSync.OrganizationSyncEnabled(ctx, api.Database) {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Organization sync is enabled for OIDC users, meaning manual organization assignment is not allowed for this user. Have the user re-login to refresh their organizations.",
 			Detail:  fmt.Sprintf("User %s is an OIDC user and organization sync is enabled. Ask an administrator to resolve the membership in your external IDP.", user.Username),
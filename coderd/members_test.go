@@ -72,3 +72,66 @@ func TestListMembers(t *testing.T) {
 func onlyIDs(u codersdk.OrganizationMemberWithUserData) uuid.UUID {
 	return u.UserID
 }
+
+func TestCrossOrganizationMembers(t *testing.T) {
+	t.Parallel()
+
+	owner := coderdtest.New(t, nil)
+	first := coderdtest.CreateFirstUser(t, owner)
+	_, member := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+	auditorClient, auditor := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID, rbac.RoleAuditor())
+
+	t.Run("Owner", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		//nolint:gocritic // testing the deployment admin view
+		resp, err := owner.CrossOrganizationMembers(ctx, codersdk.CrossOrganizationMembersRequest{})
+		require.NoError(t, err)
+		require.Equal(t, 3, resp.Count)
+		for _, m := range resp.Members {
+			require.Equal(t, first.OrganizationID, m.OrganizationID)
+			require.NotEmpty(t, m.OrganizationName)
+			require.NotEmpty(t, m.Email)
+		}
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		//nolint:gocritic // testing the deployment admin view
+		resp, err := owner.CrossOrganizationMembers(ctx, codersdk.CrossOrganizationMembersRequest{
+			SearchQuery: member.Username,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Members, 1)
+		require.Equal(t, member.ID, resp.Members[0].UserID)
+
+		//nolint:gocritic // testing the deployment admin view
+		resp, err = owner.CrossOrganizationMembers(ctx, codersdk.CrossOrganizationMembersRequest{
+			SearchQuery: "role:owner",
+			Pagination:  codersdk.Pagination{Limit: 1},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, resp.Count)
+		require.Equal(t, first.UserID, resp.Members[0].UserID)
+	})
+
+	t.Run("RedactedEmails", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		// Auditors can read all members, but not their emails.
+		resp, err := auditorClient.CrossOrganizationMembers(ctx, codersdk.CrossOrganizationMembersRequest{})
+		require.NoError(t, err)
+		require.Equal(t, 3, resp.Count)
+		for _, m := range resp.Members {
+			if m.UserID == auditor.ID {
+				require.Equal(t, auditor.Email, m.Email)
+			} else {
+				require.Empty(t, m.Email)
+			}
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// followsNamePolicy writes a validation error for field and returns false if
+// the name violates the naming policy of the deployment.
+func followsNamePolicy(ctx context.Context, rw http.ResponseWriter, namePolicy *codersdk.NamePolicy, kind, field, name string) bool {
+	err := namePolicy.Valid(name)
+	if err == nil {
+		return true
+	}
+	httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+		Message: fmt.Sprintf("%s %q does not follow the naming policy of this deployment.", kind, name),
+		Validations: []codersdk.ValidationError{{
+			Field:  field,
+			Detail: err.Error(),
+		}},
+	})
+	return false
+}
+
+// @Summary Get names violating the naming policy
+// @ID get-names-violating-the-naming-policy
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.NamingPolicyViolations
+// @Router /deployment/naming-violations [get]
+func (api *API) deploymentNamingViolations(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentConfig) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	naming := &api.DeploymentValues.Naming
+	violations := codersdk.NamingPolicyViolations{
+		Workspaces: []codersdk.NamingPolicyViolation{},
+		Users:      []codersdk.NamingPolicyViolation{},
+	}
+
+	workspaces, err := api.Database.GetWorkspaces(ctx, database.GetWorkspacesParams{})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspaces.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	for _, workspace := range workspaces {
+		// Prebuilt workspaces are renamed when they are claimed.
+		if workspace.OwnerID == database.PrebuildsSystemUserID {
+			continue
+		}
+		if err := naming.WorkspaceName.Valid(workspace.Name); err != nil {
+			violations.Workspaces = append(violations.Workspaces, codersdk.NamingPolicyViolation{
+				ID:        workspace.ID,
+				Name:      workspace.Name,
+				OwnerName: workspace.OwnerUsername,
+				Reason:    err.Error(),
+			})
+		}
+	}
+
+	users, err := api.Database.GetUsers(ctx, database.GetUsersParams{})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching users.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	for _, user := range users {
+		if err := naming.Username.Valid(user.Username); err != nil {
+			violations.Users = append(violations.Users, codersdk.NamingPolicyViolation{
+				ID:     user.ID,
+				Name:   user.Username,
+				Reason: err.Error(),
+			})
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, violations)
+}
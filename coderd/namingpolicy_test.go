@@ -0,0 +1,90 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestNamingPolicy(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		DeploymentValues:         dv,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	// Named before the policy is set.
+	legacy := coderdtest.CreateWorkspace(t, client, template.ID, func(req *codersdk.CreateWorkspaceRequest) {
+		req.Name = "Legacy-Name"
+	})
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, legacy.LatestBuild.ID)
+
+	require.NoError(t, dv.Naming.WorkspaceName.Pattern.Set("^[a-z][a-z0-9-]*$"))
+	require.NoError(t, dv.Naming.WorkspaceName.ReservedPrefixes.Set("sys-"))
+	require.NoError(t, dv.Naming.Username.ReservedPrefixes.Set("svc-"))
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	_, err := client.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+		TemplateID: template.ID,
+		Name:       "sys-dev",
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	require.Len(t, apiErr.Validations, 1)
+	require.Equal(t, "name", apiErr.Validations[0].Field)
+
+	violations, err := client.NamingPolicyViolations(ctx)
+	require.NoError(t, err)
+	require.Len(t, violations.Workspaces, 1)
+	require.Equal(t, legacy.ID, violations.Workspaces[0].ID)
+	require.Empty(t, violations.Users)
+
+	// Workspace renames are disabled, but workspaces that violate the policy
+	// can be renamed to follow it.
+	err = client.UpdateWorkspace(ctx, legacy.ID, codersdk.UpdateWorkspaceRequest{Name: "Still-Invalid"})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	err = client.UpdateWorkspace(ctx, legacy.ID, codersdk.UpdateWorkspaceRequest{Name: "renamed"})
+	require.NoError(t, err)
+	err = client.UpdateWorkspace(ctx, legacy.ID, codersdk.UpdateWorkspaceRequest{Name: "renamed-again"})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	violations, err = client.NamingPolicyViolations(ctx)
+	require.NoError(t, err)
+	require.Empty(t, violations.Workspaces)
+
+	_, err = client.CreateUserWithOrgs(ctx, codersdk.CreateUserRequestWithOrgs{
+		Email:           "bot@coder.com",
+		Username:        "svc-bot",
+		Password:        "SomeSecurePassword!",
+		OrganizationIDs: []uuid.UUID{owner.OrganizationID},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	require.Len(t, apiErr.Validations, 1)
+	require.Equal(t, "username", apiErr.Validations[0].Field)
+
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	_, err = member.UpdateUserProfile(ctx, codersdk.Me, codersdk.UpdateUserProfileRequest{Username: "svc-member"})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// Members can't list violations.
+	_, err = member.NamingPolicyViolations(ctx)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+}
@@ -0,0 +1,62 @@
+package coderd
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/networkzone"
+	"github.com/coder/coder/v2/coderd/rbac"
+)
+
+// auditNetworkZoneViolation audits a request that was rejected because it
+// came from outside a network zone of the user.
+func (api *API) auditNetworkZoneViolation(r *http.Request, actor rbac.Subject, zone networkzone.Zone) {
+	ctx := r.Context()
+	userID, err := uuid.Parse(actor.ID)
+	if err != nil {
+		api.Logger.Error(ctx, "parse actor id of network zone violation", slog.F("actor_id", actor.ID), slog.Error(err))
+		return
+	}
+	// nolint:gocritic // The request was rejected before the actor was set.
+	user, err := api.Database.GetUserByID(dbauthz.AsSystemRestricted(ctx), userID)
+	if err != nil {
+		api.Logger.Error(ctx, "get user of network zone violation", slog.F("user_id", userID), slog.Error(err))
+		return
+	}
+
+	additionalFields, err := json.Marshal(struct {
+		NetworkZone string `json:"network_zone"`
+		Method      string `json:"method"`
+		Path        string `json:"path"`
+	}{
+		NetworkZone: zone.Name,
+		Method:      r.Method,
+		Path:        r.URL.Path,
+	})
+	if err != nil {
+		api.Logger.Error(ctx, "marshal additional fields failed", slog.Error(err))
+	}
+
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.User]{
+		Audit:            *api.Auditor.Load(),
+		Log:              api.Logger,
+		UserID:           userID,
+		RequestID:        httpmw.RequestID(r),
+		Time:             dbtime.Now(),
+		Status:           http.StatusForbidden,
+		Action:           database.AuditActionNetworkZoneViolation,
+		IP:               r.RemoteAddr,
+		UserAgent:        r.UserAgent(),
+		AdditionalFields: additionalFields,
+		Old:              user,
+		New:              user,
+	})
+}
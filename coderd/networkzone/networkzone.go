@@ -0,0 +1,122 @@
+// Package networkzone restricts the source addresses that members of roles
+// and groups can make requests from.
+package networkzone
+
+import (
+	"net/netip"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Zone is a validated codersdk.NetworkZone.
+type Zone struct {
+	Name     string
+	roles    map[string]struct{}
+	groupIDs map[string]struct{}
+	prefixes []netip.Prefix
+}
+
+// Zones are the network zones of a deployment. A nil Zones allows every
+// request.
+type Zones []Zone
+
+// New validates the network zones of a deployment. CIDRs may also be single
+// IP addresses.
+func New(zones []codersdk.NetworkZone) (Zones, error) {
+	parsed := make(Zones, 0, len(zones))
+	for i, zone := range zones {
+		if zone.Name == "" {
+			return nil, xerrors.Errorf("network zone %d has no name", i)
+		}
+		if len(zone.Roles) == 0 && len(zone.GroupIDs) == 0 {
+			return nil, xerrors.Errorf("network zone %q must list at least one role or group", zone.Name)
+		}
+		if len(zone.CIDRs) == 0 {
+			return nil, xerrors.Errorf("network zone %q must list at least one cidr", zone.Name)
+		}
+
+		z := Zone{
+			Name:     zone.Name,
+			roles:    make(map[string]struct{}, len(zone.Roles)),
+			groupIDs: make(map[string]struct{}, len(zone.GroupIDs)),
+			prefixes: make([]netip.Prefix, 0, len(zone.CIDRs)),
+		}
+		for _, role := range zone.Roles {
+			z.roles[role] = struct{}{}
+		}
+		for _, groupID := range zone.GroupIDs {
+			id, err := uuid.Parse(groupID)
+			if err != nil {
+				return nil, xerrors.Errorf("network zone %q: invalid group id %q: %w", zone.Name, groupID, err)
+			}
+			z.groupIDs[id.String()] = struct{}{}
+		}
+		for _, cidr := range zone.CIDRs {
+			prefix, err := parsePrefix(cidr)
+			if err != nil {
+				return nil, xerrors.Errorf("network zone %q: %w", zone.Name, err)
+			}
+			z.prefixes = append(z.prefixes, prefix)
+		}
+		parsed = append(parsed, z)
+	}
+	return parsed, nil
+}
+
+func parsePrefix(cidr string) (netip.Prefix, error) {
+	if addr, err := netip.ParseAddr(cidr); err == nil {
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return netip.Prefix{}, xerrors.Errorf("%q is not an IP address or CIDR range", cidr)
+	}
+	return prefix.Masked(), nil
+}
+
+// Applies returns whether the actor holds one of the roles or is a member of
+// one of the groups of the zone.
+func (z Zone) Applies(actor rbac.Subject) bool {
+	for _, role := range actor.SafeRoleNames() {
+		if _, ok := z.roles[role.Name]; ok {
+			return true
+		}
+		if _, ok := z.roles[role.String()]; ok {
+			return true
+		}
+	}
+	for _, groupID := range actor.Groups {
+		if _, ok := z.groupIDs[groupID]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains returns whether the address is in one of the CIDRs of the zone.
+func (z Zone) Contains(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range z.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Denied returns the first zone that applies to the actor but does not
+// contain the address. Requests are only allowed when every zone that applies
+// to the actor contains the source address.
+func (z Zones) Denied(actor rbac.Subject, addr netip.Addr) (Zone, bool) {
+	for _, zone := range z {
+		if zone.Applies(actor) && !zone.Contains(addr) {
+			return zone, true
+		}
+	}
+	return Zone{}, false
+}
@@ -0,0 +1,110 @@
+package networkzone_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/networkzone"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		Name  string
+		Zone  codersdk.NetworkZone
+		Error string
+	}{
+		{
+			Name: "OK",
+			Zone: codersdk.NetworkZone{Name: "vpn", Roles: []string{"owner"}, CIDRs: []string{"10.0.0.0/8", "192.168.1.1"}},
+		},
+		{
+			Name:  "NoName",
+			Zone:  codersdk.NetworkZone{Roles: []string{"owner"}, CIDRs: []string{"10.0.0.0/8"}},
+			Error: "no name",
+		},
+		{
+			Name:  "NoMembers",
+			Zone:  codersdk.NetworkZone{Name: "vpn", CIDRs: []string{"10.0.0.0/8"}},
+			Error: "at least one role or group",
+		},
+		{
+			Name:  "NoCIDRs",
+			Zone:  codersdk.NetworkZone{Name: "vpn", Roles: []string{"owner"}},
+			Error: "at least one cidr",
+		},
+		{
+			Name:  "InvalidCIDR",
+			Zone:  codersdk.NetworkZone{Name: "vpn", Roles: []string{"owner"}, CIDRs: []string{"10.0.0.0/33"}},
+			Error: "not an IP address or CIDR range",
+		},
+		{
+			Name:  "InvalidGroupID",
+			Zone:  codersdk.NetworkZone{Name: "vpn", GroupIDs: []string{"admins"}, CIDRs: []string{"10.0.0.0/8"}},
+			Error: "invalid group id",
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			_, err := networkzone.New([]codersdk.NetworkZone{tc.Zone})
+			if tc.Error == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.Error)
+		})
+	}
+}
+
+func TestDenied(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	groupID := uuid.New()
+	zones, err := networkzone.New([]codersdk.NetworkZone{
+		{Name: "admins", Roles: []string{rbac.RoleOwner().Name, rbac.RoleOrgAdmin() + ":" + orgID.String()}, CIDRs: []string{"10.0.0.0/8"}},
+		{Name: "contractors", GroupIDs: []string{groupID.String()}, CIDRs: []string{"192.168.0.0/16", "10.1.2.3"}},
+	})
+	require.NoError(t, err)
+
+	var (
+		owner      = rbac.Subject{Roles: rbac.RoleIdentifiers{rbac.RoleOwner(), rbac.RoleMember()}}
+		orgAdmin   = rbac.Subject{Roles: rbac.RoleIdentifiers{rbac.ScopedRoleOrgAdmin(orgID)}}
+		otherAdmin = rbac.Subject{Roles: rbac.RoleIdentifiers{rbac.ScopedRoleOrgAdmin(uuid.New())}}
+		member     = rbac.Subject{Roles: rbac.RoleIdentifiers{rbac.RoleMember()}}
+		contractor = rbac.Subject{Roles: rbac.RoleIdentifiers{rbac.RoleMember()}, Groups: []string{groupID.String()}}
+		both       = rbac.Subject{Roles: rbac.RoleIdentifiers{rbac.RoleOwner()}, Groups: []string{groupID.String()}}
+	)
+
+	for _, tc := range []struct {
+		Name   string
+		Actor  rbac.Subject
+		Addr   string
+		Denied string
+	}{
+		{Name: "OwnerInZone", Actor: owner, Addr: "10.20.30.40"},
+		{Name: "OwnerOutsideZone", Actor: owner, Addr: "203.0.113.1", Denied: "admins"},
+		{Name: "OwnerMappedIPv6", Actor: owner, Addr: "::ffff:10.0.0.1"},
+		{Name: "OrgAdminOutsideZone", Actor: orgAdmin, Addr: "203.0.113.1", Denied: "admins"},
+		{Name: "OtherOrgAdmin", Actor: otherAdmin, Addr: "203.0.113.1"},
+		{Name: "Member", Actor: member, Addr: "203.0.113.1"},
+		{Name: "ContractorInZone", Actor: contractor, Addr: "192.168.4.4"},
+		{Name: "ContractorOutsideZone", Actor: contractor, Addr: "10.0.0.1", Denied: "contractors"},
+		// Every zone that applies must contain the address.
+		{Name: "BothInOneZone", Actor: both, Addr: "192.168.4.4", Denied: "admins"},
+		{Name: "BothInAllZones", Actor: both, Addr: "10.1.2.3"},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			zone, denied := zones.Denied(tc.Actor, netip.MustParseAddr(tc.Addr))
+			require.Equal(t, tc.Denied != "", denied)
+			require.Equal(t, tc.Denied, zone.Name)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestNetworkZones(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.NetworkZones.Value = []codersdk.NetworkZone{
+		{Name: "vpn", Roles: []string{rbac.RoleUserAdmin().Name}, CIDRs: []string{"10.0.0.0/8"}},
+		{Name: "local", Roles: []string{rbac.RoleTemplateAdmin().Name}, CIDRs: []string{"127.0.0.0/8", "::1"}},
+	}
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{
+		DeploymentValues: dv,
+		Auditor:          auditor,
+	})
+	first := coderdtest.CreateFirstUser(t, client)
+	userAdmin, userAdminUser := coderdtest.CreateAnotherUser(t, client, first.OrganizationID, rbac.RoleUserAdmin())
+	templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID, rbac.RoleTemplateAdmin())
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Test clients connect from the loopback address.
+	_, err := userAdmin.User(ctx, codersdk.Me)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	require.Contains(t, apiErr.Message, `"vpn" network zone`)
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		Action:       database.AuditActionNetworkZoneViolation,
+		ResourceType: database.ResourceTypeUser,
+		ResourceID:   userAdminUser.ID,
+		UserID:       userAdminUser.ID,
+		StatusCode:   http.StatusForbidden,
+	}))
+
+	_, err = templateAdmin.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	// Members without a zone are not restricted.
+	_, err = client.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+}
@@ -2,8 +2,12 @@ package coderd
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/google/uuid"
 
@@ -323,6 +327,178 @@ func (api *API) putUserNotificationPreferences(rw http.ResponseWriter, r *http.R
 	httpapi.Write(ctx, rw, http.StatusOK, out)
 }
 
+// @Summary Get user notification category preferences
+// @ID get-user-notification-category-preferences
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Notifications
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.NotificationCategoryPreference
+// @Router /users/{user}/notifications/preferences/categories [get]
+func (api *API) userNotificationCategoryPreferences(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	prefs, err := api.Database.GetUserNotificationCategoryPreferences(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to retrieve user notification category preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertNotificationCategoryPreferences(prefs))
+}
+
+// @Summary Update user notification category preferences
+// @ID update-user-notification-category-preferences
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Notifications
+// @Param request body codersdk.UpdateUserNotificationCategoryPreferences true "Preferences"
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.NotificationCategoryPreference
+// @Router /users/{user}/notifications/preferences/categories [put]
+func (api *API) putUserNotificationCategoryPreferences(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		logger = api.Logger.Named("notifications.preferences").With(slog.F("user_id", user.ID))
+	)
+
+	var req codersdk.UpdateUserNotificationCategoryPreferences
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	input := database.UpdateUserNotificationCategoryPreferencesParams{
+		UserID:     user.ID,
+		Categories: make([]database.NotificationCategory, 0, len(req.Preferences)),
+		Methods:    make([]database.NotificationMethod, 0, len(req.Preferences)),
+		Disableds:  make([]bool, 0, len(req.Preferences)),
+	}
+	var validErrs []codersdk.ValidationError
+	for i, pref := range req.Preferences {
+		category := database.NotificationCategory(pref.Category)
+		if !category.Valid() {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  fmt.Sprintf("preferences[%d].category", i),
+				Detail: fmt.Sprintf("%q is not a valid notification category", pref.Category),
+			})
+		}
+		method := database.NotificationMethod(pref.Method)
+		if !method.Valid() {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  fmt.Sprintf("preferences[%d].method", i),
+				Detail: fmt.Sprintf("%q is not a valid notification method", pref.Method),
+			})
+		}
+		input.Categories = append(input.Categories, category)
+		input.Methods = append(input.Methods, method)
+		input.Disableds = append(input.Disableds, pref.Disabled)
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid notification category preferences.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	updated, err := api.Database.UpdateUserNotificationCategoryPreferences(ctx, input)
+	if err != nil {
+		logger.Error(ctx, "failed to update category preferences", slog.Error(err))
+
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to update user notification category preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	logger.Info(ctx, "updated category preferences", slog.F("count", updated))
+
+	prefs, err := api.Database.GetUserNotificationCategoryPreferences(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to retrieve user notification category preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertNotificationCategoryPreferences(prefs))
+}
+
+// @Summary Get user notification settings
+// @ID get-user-notification-settings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Notifications
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.UserNotificationSettings
+// @Router /users/{user}/notifications/settings [get]
+func (api *API) userNotificationSettings(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	digest, err := api.Database.GetUserNotificationDigest(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to retrieve user notification settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.UserNotificationSettings{
+		Digest: digest == "true",
+	})
+}
+
+// @Summary Update user notification settings
+// @ID update-user-notification-settings
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Notifications
+// @Param request body codersdk.UserNotificationSettings true "Settings"
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.UserNotificationSettings
+// @Router /users/{user}/notifications/settings [put]
+func (api *API) putUserNotificationSettings(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	var settings codersdk.UserNotificationSettings
+	if !httpapi.Read(ctx, rw, r, &settings) {
+		return
+	}
+
+	updated, err := api.Database.UpdateUserNotificationDigest(ctx, database.UpdateUserNotificationDigestParams{
+		UserID:             user.ID,
+		NotificationDigest: strconv.FormatBool(settings.Digest),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to update user notification settings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.UserNotificationSettings{
+		Digest: updated.Value == "true",
+	})
+}
+
 func convertNotificationTemplates(in []database.NotificationTemplate) (out []codersdk.NotificationTemplate) {
 	for _, tmpl := range in {
 		out = append(out, codersdk.NotificationTemplate{
@@ -335,6 +511,8 @@ func convertNotificationTemplates(in []database.NotificationTemplate) (out []cod
 			Method:           string(tmpl.Method.NotificationMethod),
 			Kind:             string(tmpl.Kind),
 			EnabledByDefault: tmpl.EnabledByDefault,
+			Priority:         string(tmpl.Priority),
+			Category:         codersdk.NotificationCategory(tmpl.Category.NotificationCategory),
 		})
 	}
 
@@ -352,3 +530,16 @@ func convertNotificationPreferences(in []database.NotificationPreference) (out [
 
 	return out
 }
+
+func convertNotificationCategoryPreferences(in []database.NotificationCategoryPreference) []codersdk.NotificationCategoryPreference {
+	out := make([]codersdk.NotificationCategoryPreference, 0, len(in))
+	for _, pref := range in {
+		out = append(out, codersdk.NotificationCategoryPreference{
+			Category:  codersdk.NotificationCategory(pref.Category),
+			Method:    string(pref.Method),
+			Disabled:  pref.Disabled,
+			UpdatedAt: pref.UpdatedAt,
+		})
+	}
+	return out
+}
@@ -0,0 +1,197 @@
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	markdown "github.com/coder/coder/v2/coderd/render"
+	cstrings "github.com/coder/coder/v2/coderd/util/strings"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// slackHeaderMaxLength is the maximum length of the text in a Slack header block.
+const slackHeaderMaxLength = 150
+
+var (
+	ErrValidationNoBotToken = xerrors.New("slack bot token not defined")
+
+	slackMarkdownLink = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	slackMarkdownBold = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+)
+
+// SlackHandler dispatches notification messages as Slack direct messages. Recipients are resolved by looking up the
+// Slack user with the same email address as the Coder user.
+type SlackHandler struct {
+	cfg codersdk.NotificationsSlackConfig
+	log slog.Logger
+
+	cl *http.Client
+}
+
+// slackResponse describes the common envelope of Slack Web API responses.
+type slackResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	User  struct {
+		ID string `json:"id"`
+	} `json:"user"`
+}
+
+type slackMessage struct {
+	Channel string       `json:"channel"`
+	Text    string       `json:"text"`
+	Blocks  []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string         `json:"type"`
+	Text     *slackText     `json:"text,omitempty"`
+	Elements []slackElement `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackElement struct {
+	Type string     `json:"type"`
+	Text *slackText `json:"text"`
+	URL  string     `json:"url"`
+}
+
+func NewSlackHandler(cfg codersdk.NotificationsSlackConfig, log slog.Logger) *SlackHandler {
+	return &SlackHandler{cfg: cfg, log: log, cl: &http.Client{}}
+}
+
+func (s *SlackHandler) Dispatcher(payload types.MessagePayload, titleMarkdown, bodyMarkdown string, _ template.FuncMap) (DeliveryFunc, error) {
+	if s.cfg.BotToken.String() == "" {
+		return nil, ErrValidationNoBotToken
+	}
+	if payload.UserEmail == "" {
+		return nil, ErrValidationNoToAddress
+	}
+
+	titlePlaintext, err := markdown.PlaintextFromMarkdown(titleMarkdown)
+	if err != nil {
+		return nil, xerrors.Errorf("render title: %w", err)
+	}
+
+	msg := slackMessage{
+		Text: titlePlaintext,
+		Blocks: []slackBlock{
+			{Type: "header", Text: &slackText{Type: "plain_text", Text: cstrings.Truncate(titlePlaintext, slackHeaderMaxLength, cstrings.TruncateWithEllipsis)}},
+			{Type: "section", Text: &slackText{Type: "mrkdwn", Text: slackMarkdown(bodyMarkdown)}},
+		},
+	}
+	if len(payload.Actions) > 0 {
+		actions := slackBlock{Type: "actions"}
+		for _, action := range payload.Actions {
+			actions.Elements = append(actions.Elements, slackElement{
+				Type: "button",
+				Text: &slackText{Type: "plain_text", Text: action.Label},
+				URL:  action.URL,
+			})
+		}
+		msg.Blocks = append(msg.Blocks, actions)
+	}
+
+	return s.dispatch(payload.UserEmail, msg), nil
+}
+
+func (s *SlackHandler) dispatch(email string, msg slackMessage) DeliveryFunc {
+	return func(ctx context.Context, msgID uuid.UUID) (retryable bool, err error) {
+		// Resolve the Slack user which the direct message will be sent to.
+		query := url.Values{"email": []string{email}}
+		lookup, retryable, err := s.call(ctx, http.MethodGet, "users.lookupByEmail?"+query.Encode(), nil)
+		if err != nil {
+			return retryable, xerrors.Errorf("lookup user by email: %w", err)
+		}
+
+		// Posting to a user ID opens (or reuses) the direct message conversation with the app.
+		msg.Channel = lookup.User.ID
+		body, err := json.Marshal(msg)
+		if err != nil {
+			return false, xerrors.Errorf("marshal message: %v", err)
+		}
+		if _, retryable, err = s.call(ctx, http.MethodPost, "chat.postMessage", body); err != nil {
+			s.log.Warn(ctx, "unsuccessful delivery", slog.F("msg_id", msgID), slog.Error(err))
+			return retryable, xerrors.Errorf("post message: %w", err)
+		}
+
+		return false, nil
+	}
+}
+
+// call invokes the given Slack Web API method. The second return value indicates whether a failed call may be retried.
+func (s *SlackHandler) call(ctx context.Context, httpMethod, apiMethod string, body []byte) (*slackResponse, bool, error) {
+	endpoint := strings.TrimSuffix(s.cfg.APIURL.String(), "/") + "/" + apiMethod
+
+	// Outer context has a deadline (see CODER_NOTIFICATIONS_DISPATCH_TIMEOUT).
+	req, err := http.NewRequestWithContext(ctx, httpMethod, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, xerrors.Errorf("create HTTP request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.cfg.BotToken.String())
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+
+	resp, err := s.cl.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, true, xerrors.Errorf("request timeout: %w", err)
+		}
+
+		return nil, true, xerrors.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 > 2 {
+		return nil, true, xerrors.Errorf("non-2xx response (%d)", resp.StatusCode)
+	}
+
+	var out slackResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&out); err != nil {
+		return nil, true, xerrors.Errorf("decode response: %w", err)
+	}
+	if !out.OK {
+		return nil, slackRetryable(out.Error), xerrors.Errorf("slack error: %s", out.Error)
+	}
+	return &out, false, nil
+}
+
+// slackRetryable reports whether the given Slack API error code represents a temporary failure. Errors such as an
+// unknown user or a misconfigured token will not resolve themselves on retry.
+func slackRetryable(code string) bool {
+	switch code {
+	case "ratelimited", "internal_error", "fatal_error", "service_unavailable", "request_timeout":
+		return true
+	default:
+		return false
+	}
+}
+
+// slackMarkdown converts the subset of Markdown used by notification templates into Slack's mrkdwn format.
+func slackMarkdown(md string) string {
+	out := slackMarkdownLink.ReplaceAllStringFunc(md, func(link string) string {
+		m := slackMarkdownLink.FindStringSubmatch(link)
+		return fmt.Sprintf("<%s|%s>", m[2], m[1])
+	})
+	return slackMarkdownBold.ReplaceAllString(out, "*$1*")
+}
@@ -0,0 +1,164 @@
+package dispatch_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/serpent"
+
+	"github.com/coder/coder/v2/coderd/notifications/dispatch"
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSlack(t *testing.T) {
+	t.Parallel()
+
+	const (
+		botToken      = "xoxb-test"
+		userEmail     = "bob@coder.com"
+		slackUserID   = "U0123456"
+		titleMarkdown = "this *is* _the_ title"
+		bodyMarkdown  = "**Workspace** [dev](https://coder.com/@bob/dev) was stopped"
+	)
+
+	msgPayload := types.MessagePayload{
+		Version:          "1.0",
+		NotificationName: "test",
+		UserEmail:        userEmail,
+		Actions: []types.TemplateAction{
+			{Label: "View workspace", URL: "https://coder.com/@bob/dev"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		lookupFn func(w http.ResponseWriter)
+		postFn   func(w http.ResponseWriter)
+
+		expectSuccess   bool
+		expectRetryable bool
+		expectErr       string
+	}{
+		{
+			name: "successful",
+			lookupFn: func(w http.ResponseWriter) {
+				_, _ = w.Write([]byte(`{"ok":true,"user":{"id":"` + slackUserID + `"}}`))
+			},
+			postFn: func(w http.ResponseWriter) {
+				_, _ = w.Write([]byte(`{"ok":true}`))
+			},
+			expectSuccess: true,
+		},
+		{
+			name: "user not found",
+			lookupFn: func(w http.ResponseWriter) {
+				_, _ = w.Write([]byte(`{"ok":false,"error":"users_not_found"}`))
+			},
+			expectRetryable: false,
+			expectErr:       "users_not_found",
+		},
+		{
+			name: "rate limited",
+			lookupFn: func(w http.ResponseWriter) {
+				_, _ = w.Write([]byte(`{"ok":true,"user":{"id":"` + slackUserID + `"}}`))
+			},
+			postFn: func(w http.ResponseWriter) {
+				_, _ = w.Write([]byte(`{"ok":false,"error":"ratelimited"}`))
+			},
+			expectRetryable: true,
+			expectErr:       "ratelimited",
+		},
+		{
+			name: "non-200 response",
+			lookupFn: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			expectRetryable: true,
+			expectErr:       "non-2xx response (500)",
+		},
+	}
+
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+
+	// nolint:paralleltest // Irrelevant as of Go v1.22
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testutil.Context(t, testutil.WaitLong)
+
+			// Mock server to simulate the Slack Web API.
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "Bearer "+botToken, r.Header.Get("Authorization"))
+
+				switch r.URL.Path {
+				case "/api/users.lookupByEmail":
+					assert.Equal(t, userEmail, r.URL.Query().Get("email"))
+					tc.lookupFn(w)
+				case "/api/chat.postMessage":
+					var msg struct {
+						Channel string `json:"channel"`
+						Blocks  []struct {
+							Type string `json:"type"`
+							Text struct {
+								Text string `json:"text"`
+							} `json:"text"`
+						} `json:"blocks"`
+					}
+					assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+					assert.Equal(t, slackUserID, msg.Channel)
+					if assert.Len(t, msg.Blocks, 3) {
+						assert.Equal(t, "this is the title", msg.Blocks[0].Text.Text)
+						assert.Equal(t, "*Workspace* <https://coder.com/@bob/dev|dev> was stopped", msg.Blocks[1].Text.Text)
+						assert.Equal(t, "actions", msg.Blocks[2].Type)
+					}
+					tc.postFn(w)
+				default:
+					t.Errorf("unexpected request path %q", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			apiURL, err := url.Parse(server.URL + "/api")
+			require.NoError(t, err)
+
+			cfg := codersdk.NotificationsSlackConfig{
+				BotToken: serpent.String(botToken),
+				APIURL:   *serpent.URLOf(apiURL),
+			}
+			handler := dispatch.NewSlackHandler(cfg, logger.With(slog.F("test", tc.name)))
+			deliveryFn, err := handler.Dispatcher(msgPayload, titleMarkdown, bodyMarkdown, helpers())
+			require.NoError(t, err)
+
+			retryable, err := deliveryFn(ctx, uuid.New())
+			if tc.expectSuccess {
+				require.NoError(t, err)
+				require.False(t, retryable)
+				return
+			}
+
+			require.ErrorContains(t, err, tc.expectErr)
+			require.Equal(t, tc.expectRetryable, retryable)
+		})
+	}
+}
+
+func TestSlackNoBotToken(t *testing.T) {
+	t.Parallel()
+
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	handler := dispatch.NewSlackHandler(codersdk.NotificationsSlackConfig{}, logger)
+	_, err := handler.Dispatcher(types.MessagePayload{UserEmail: "bob@coder.com"}, "title", "body", helpers())
+	require.ErrorIs(t, err, dispatch.ErrValidationNoBotToken)
+}
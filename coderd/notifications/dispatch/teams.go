@@ -0,0 +1,131 @@
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"text/template"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	markdown "github.com/coder/coder/v2/coderd/render"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// TeamsHandler dispatches notification messages as Adaptive Cards to a Microsoft Teams incoming webhook, such as one
+// created by a Teams workflow.
+type TeamsHandler struct {
+	cfg codersdk.NotificationsTeamsConfig
+	log slog.Logger
+
+	cl *http.Client
+}
+
+// TeamsMessage describes the JSON payload delivered to the configured Teams webhook endpoint.
+type TeamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+type TeamsAttachment struct {
+	ContentType string            `json:"contentType"`
+	Content     TeamsAdaptiveCard `json:"content"`
+}
+
+type TeamsAdaptiveCard struct {
+	Schema  string           `json:"$schema"`
+	Type    string           `json:"type"`
+	Version string           `json:"version"`
+	Body    []map[string]any `json:"body"`
+	Actions []map[string]any `json:"actions,omitempty"`
+	MSTeams map[string]any   `json:"msteams,omitempty"`
+}
+
+func NewTeamsHandler(cfg codersdk.NotificationsTeamsConfig, log slog.Logger) *TeamsHandler {
+	return &TeamsHandler{cfg: cfg, log: log, cl: &http.Client{}}
+}
+
+func (t *TeamsHandler) Dispatcher(payload types.MessagePayload, titleMarkdown, bodyMarkdown string, _ template.FuncMap) (DeliveryFunc, error) {
+	if t.cfg.Endpoint.String() == "" {
+		return nil, xerrors.New("teams endpoint not defined")
+	}
+
+	titlePlaintext, err := markdown.PlaintextFromMarkdown(titleMarkdown)
+	if err != nil {
+		return nil, xerrors.Errorf("render title: %w", err)
+	}
+
+	card := TeamsAdaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []map[string]any{
+			{"type": "TextBlock", "text": titlePlaintext, "weight": "Bolder", "size": "Medium", "wrap": true},
+			// Adaptive Cards support a subset of Markdown in TextBlocks, which covers what notification templates use.
+			{"type": "TextBlock", "text": bodyMarkdown, "wrap": true},
+		},
+		MSTeams: map[string]any{"width": "Full"},
+	}
+	for _, action := range payload.Actions {
+		card.Actions = append(card.Actions, map[string]any{
+			"type":  "Action.OpenUrl",
+			"title": action.Label,
+			"url":   action.URL,
+		})
+	}
+
+	return t.dispatch(TeamsMessage{
+		Type: "message",
+		Attachments: []TeamsAttachment{
+			{ContentType: "application/vnd.microsoft.card.adaptive", Content: card},
+		},
+	}, t.cfg.Endpoint.String()), nil
+}
+
+func (t *TeamsHandler) dispatch(msg TeamsMessage, endpoint string) DeliveryFunc {
+	return func(ctx context.Context, msgID uuid.UUID) (retryable bool, err error) {
+		m, err := json.Marshal(msg)
+		if err != nil {
+			return false, xerrors.Errorf("marshal payload: %v", err)
+		}
+
+		// Outer context has a deadline (see CODER_NOTIFICATIONS_DISPATCH_TIMEOUT).
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(m))
+		if err != nil {
+			return false, xerrors.Errorf("create HTTP request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := t.cl.Do(req)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return true, xerrors.Errorf("request timeout: %w", err)
+			}
+
+			return true, xerrors.Errorf("request failed: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode/100 > 2 {
+			// Body could be quite long here, let's grab the first 512B and hope it contains useful debug info.
+			respBody := make([]byte, 512)
+			lr := io.LimitReader(resp.Body, int64(len(respBody)))
+			n, err := lr.Read(respBody)
+			if err != nil && !errors.Is(err, io.EOF) {
+				return true, xerrors.Errorf("non-2xx response (%d), read body: %w", resp.StatusCode, err)
+			}
+			t.log.Warn(ctx, "unsuccessful delivery", slog.F("status_code", resp.StatusCode),
+				slog.F("response", string(respBody[:n])), slog.F("msg_id", msgID))
+			return true, xerrors.Errorf("non-2xx response (%d)", resp.StatusCode)
+		}
+
+		return false, nil
+	}
+}
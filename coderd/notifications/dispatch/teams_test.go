@@ -0,0 +1,118 @@
+package dispatch_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/serpent"
+
+	"github.com/coder/coder/v2/coderd/notifications/dispatch"
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTeams(t *testing.T) {
+	t.Parallel()
+
+	const (
+		titleMarkdown = "this *is* _the_ title"
+		bodyMarkdown  = "**Workspace** was stopped"
+	)
+
+	msgPayload := types.MessagePayload{
+		Version:          "1.0",
+		NotificationName: "test",
+		Actions: []types.TemplateAction{
+			{Label: "View workspace", URL: "https://coder.com/@bob/dev"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		serverFn func(w http.ResponseWriter, r *http.Request)
+
+		expectSuccess   bool
+		expectRetryable bool
+		expectErr       string
+	}{
+		{
+			name: "successful",
+			serverFn: func(w http.ResponseWriter, r *http.Request) {
+				var msg dispatch.TeamsMessage
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "message", msg.Type)
+				if assert.Len(t, msg.Attachments, 1) {
+					card := msg.Attachments[0].Content
+					assert.Equal(t, "application/vnd.microsoft.card.adaptive", msg.Attachments[0].ContentType)
+					assert.Equal(t, "AdaptiveCard", card.Type)
+					if assert.Len(t, card.Body, 2) {
+						assert.Equal(t, "this is the title", card.Body[0]["text"])
+						assert.Equal(t, bodyMarkdown, card.Body[1]["text"])
+					}
+					if assert.Len(t, card.Actions, 1) {
+						assert.Equal(t, "Action.OpenUrl", card.Actions[0]["type"])
+						assert.Equal(t, "View workspace", card.Actions[0]["title"])
+						assert.Equal(t, "https://coder.com/@bob/dev", card.Actions[0]["url"])
+					}
+				}
+
+				w.WriteHeader(http.StatusAccepted)
+			},
+			expectSuccess: true,
+		},
+		{
+			name: "non-200 response",
+			serverFn: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+			},
+			expectRetryable: true,
+			expectErr:       "non-2xx response (400)",
+		},
+	}
+
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+
+	// nolint:paralleltest // Irrelevant as of Go v1.22
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testutil.Context(t, testutil.WaitLong)
+
+			// Mock server to simulate the Teams incoming webhook.
+			server := httptest.NewServer(http.HandlerFunc(tc.serverFn))
+			defer server.Close()
+
+			endpoint, err := url.Parse(server.URL)
+			require.NoError(t, err)
+
+			cfg := codersdk.NotificationsTeamsConfig{
+				Endpoint: *serpent.URLOf(endpoint),
+			}
+			handler := dispatch.NewTeamsHandler(cfg, logger.With(slog.F("test", tc.name)))
+			deliveryFn, err := handler.Dispatcher(msgPayload, titleMarkdown, bodyMarkdown, helpers())
+			require.NoError(t, err)
+
+			retryable, err := deliveryFn(ctx, uuid.New())
+			if tc.expectSuccess {
+				require.NoError(t, err)
+				require.False(t, retryable)
+				return
+			}
+
+			require.ErrorContains(t, err, tc.expectErr)
+			require.Equal(t, tc.expectRetryable, retryable)
+		})
+	}
+}
@@ -2,11 +2,13 @@ package notifications
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"slices"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -18,6 +20,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/notifications/render"
 	"github.com/coder/coder/v2/coderd/notifications/types"
+	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -47,6 +50,12 @@ type StoreEnqueuer struct {
 	helpers template.FuncMap
 	// Used to manipulate time in tests.
 	clock quartz.Clock
+
+	// quietHoursSchedule resolves the quiet hours schedule of users; if nil, no user has quiet hours.
+	quietHoursSchedule QuietHoursScheduleFunc
+	// quietHoursDuration is how long each quiet hours window lasts; if zero, messages are not held back during quiet
+	// hours.
+	quietHoursDuration time.Duration
 }
 
 // NewStoreEnqueuer creates an Enqueuer implementation which can persist notification messages in the store.
@@ -69,9 +78,17 @@ func NewStoreEnqueuer(cfg codersdk.NotificationsConfig, store Store, helpers tem
 		inboxEnabled:   cfg.Inbox.Enabled.Value(),
 		helpers:        helpers,
 		clock:          clock,
+
+		quietHoursDuration: cfg.QuietHoursDuration.Value(),
 	}, nil
 }
 
+// WithQuietHours routes messages according to the quiet hours schedule of their recipient: messages which are not high
+// priority are held back until the end of the quiet hours window, and digests are delivered once it ends.
+func (s *StoreEnqueuer) WithQuietHours(fn QuietHoursScheduleFunc) {
+	s.quietHoursSchedule = fn
+}
+
 // Enqueue queues a notification message for later delivery, assumes no structured input data.
 func (s *StoreEnqueuer) Enqueue(ctx context.Context, userID, templateID uuid.UUID, labels map[string]string, createdBy string, targets ...uuid.UUID) ([]uuid.UUID, error) {
 	return s.EnqueueWithData(ctx, userID, templateID, labels, nil, createdBy, targets...)
@@ -114,6 +131,23 @@ func (s *StoreEnqueuer) EnqueueWithData(ctx context.Context, userID, templateID
 		methods = append(methods, database.NotificationMethodInbox)
 	}
 
+	// Users can disable the delivery of a notification category on some or all of the methods.
+	if len(metadata.DisabledMethods) > 0 && len(methods) > 0 {
+		methods = slices.DeleteFunc(methods, func(method database.NotificationMethod) bool {
+			return slices.Contains(metadata.DisabledMethods, method)
+		})
+		if len(methods) == 0 {
+			return nil, ErrCannotEnqueueDisabledNotification
+		}
+	}
+
+	now := dbtime.Time(s.clock.Now().UTC())
+	deliverAfter, digestAt, err := s.route(ctx, metadata, now)
+	if err != nil {
+		s.log.Warn(ctx, "failed to route notification", slog.F("template_id", templateID), slog.F("user_id", userID), slog.Error(err))
+		return nil, xerrors.Errorf("enqueue notification (route): %w", err)
+	}
+
 	uuids := make([]uuid.UUID, 0, 2)
 	for _, method := range methods {
 		// TODO(DanielleMaywood):
@@ -123,17 +157,27 @@ func (s *StoreEnqueuer) EnqueueWithData(ctx context.Context, userID, templateID
 			continue
 		}
 
-		id := uuid.New()
-		err = s.store.EnqueueNotificationMessage(ctx, database.EnqueueNotificationMessageParams{
-			ID:                     id,
+		params := database.EnqueueNotificationMessageParams{
+			ID:                     uuid.New(),
 			UserID:                 userID,
 			NotificationTemplateID: templateID,
 			Method:                 method,
 			Payload:                input,
 			Targets:                targets,
 			CreatedBy:              createdBy,
-			CreatedAt:              dbtime.Time(s.clock.Now().UTC()),
-		})
+			CreatedAt:              now,
+		}
+		// The inbox does not page anyone, so messages are always delivered to it straight away.
+		if method != database.NotificationMethodInbox {
+			if !digestAt.IsZero() {
+				params.Digest = true
+				params.DeliverAfter = sql.NullTime{Time: digestAt, Valid: true}
+			} else if !deliverAfter.IsZero() {
+				params.DeliverAfter = sql.NullTime{Time: deliverAfter, Valid: true}
+			}
+		}
+
+		err = s.store.EnqueueNotificationMessage(ctx, params)
 		if err != nil {
 			// We have a trigger on the notification_messages table named `inhibit_enqueue_if_disabled` which prevents messages
 			// from being enqueued if the user has disabled them via notification_preferences. The trigger will fail the insertion
@@ -155,13 +199,42 @@ func (s *StoreEnqueuer) EnqueueWithData(ctx context.Context, userID, templateID
 			return nil, xerrors.Errorf("enqueue notification: %w", err)
 		}
 
-		uuids = append(uuids, id)
+		uuids = append(uuids, params.ID)
 	}
 
 	s.log.Debug(ctx, "enqueued notification", slog.F("msg_ids", uuids))
 	return uuids, nil
 }
 
+// route determines when messages for the given metadata are delivered. It returns the time until which the messages
+// are held back due to the user's quiet hours, and the time of the digest they are batched into. Either is zero if it
+// does not apply.
+func (s *StoreEnqueuer) route(ctx context.Context, metadata database.FetchNewMessageMetadataRow, now time.Time) (deliverAfter time.Time, digestAt time.Time, err error) {
+	if metadata.Priority == database.NotificationPriorityHigh {
+		return time.Time{}, time.Time{}, nil
+	}
+	digest := metadata.DigestEnabled && metadata.Priority == database.NotificationPriorityLow
+	if !digest && (s.quietHoursSchedule == nil || s.quietHoursDuration <= 0) {
+		return time.Time{}, time.Time{}, nil
+	}
+
+	var sched *cron.Schedule
+	if s.quietHoursSchedule != nil {
+		sched, err = s.quietHoursSchedule(ctx, metadata.UserID)
+		if err != nil {
+			return time.Time{}, time.Time{}, xerrors.Errorf("get quiet hours schedule: %w", err)
+		}
+	}
+
+	if digest {
+		return time.Time{}, nextDigestAt(sched, s.quietHoursDuration, now), nil
+	}
+	if end, ok := quietHoursEnd(sched, s.quietHoursDuration, now); ok {
+		return end, time.Time{}, nil
+	}
+	return time.Time{}, time.Time{}, nil
+}
+
 // buildPayload creates the payload that the notification will for variable substitution and/or routing.
 // The payload contains information about the recipient, the event that triggered the notification, and any subsequent
 // actions which can be taken by the recipient.
@@ -38,8 +38,12 @@ var (
 	TemplateTemplateDeleted    = uuid.MustParse("29a09665-2a4c-403f-9648-54301670e7be")
 	TemplateTemplateDeprecated = uuid.MustParse("f40fae84-55a2-42cd-99fa-b41c1ca64894")
 
+	TemplateTemplateVersionActivationRequested = uuid.MustParse("79490ae9-2604-43ee-81eb-d840f20ed32a")
+	TemplateTemplateVersionRolloutHalted       = uuid.MustParse("4d10fb57-6760-46ea-b231-5682af398e02")
+
 	TemplateWorkspaceBuildsFailedReport = uuid.MustParse("34a20db2-e9cc-4a93-b0e4-8569699d7a00")
 	TemplateWorkspaceResourceReplaced   = uuid.MustParse("89d9745a-816e-4695-a17f-3d0a229e2b8d")
+	TemplateWorkspaceUsageReport        = uuid.MustParse("306a35f5-b7e7-47e9-a84e-5962ab97fbf0")
 )
 
 // Prebuilds-related events
@@ -49,5 +53,6 @@ var (
 
 // Notification-related events.
 var (
-	TemplateTestNotification = uuid.MustParse("c425f63e-716a-4bf4-ae24-78348f706c3f")
+	TemplateTestNotification   = uuid.MustParse("c425f63e-716a-4bf4-ae24-78348f706c3f")
+	TemplateNotificationDigest = uuid.MustParse("12f54ee2-d775-48b2-9d28-42991868bad4")
 )
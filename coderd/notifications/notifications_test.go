@@ -48,6 +48,7 @@ import (
 	"github.com/coder/coder/v2/coderd/notifications/dispatch/smtptest"
 	"github.com/coder/coder/v2/coderd/notifications/types"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/coderd/util/syncmap"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
@@ -1124,6 +1125,83 @@ func TestNotificationTemplates_Golden(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "TemplateTemplateVersionActivationRequested",
+			id:   notifications.TemplateTemplateVersionActivationRequested,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"template":           "alpha",
+					"version":            "brave_hopper3",
+					"requester":          "rob",
+					"approvals_required": "2",
+					"organization":       "coder",
+				},
+			},
+		},
+		{
+			name: "TemplateWorkspaceUsageReport",
+			id:   notifications.TemplateWorkspaceUsageReport,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels:       map[string]string{},
+				Data: map[string]any{
+					"start_time":    "2024-10-04T00:00:00Z",
+					"end_time":      "2024-10-11T00:00:00Z",
+					"start_date":    "2024-10-04",
+					"end_date":      "2024-10-10",
+					"runtime_hours": "312.5",
+					"cost":          "1250.0",
+					"users": []map[string]any{
+						{
+							"username":      "alice",
+							"runtime_hours": "168.0",
+							"cost":          "840.0",
+						},
+						{
+							"username":      "bobby",
+							"runtime_hours": "144.5",
+							"cost":          "410.0",
+						},
+					},
+					"groups": []map[string]any{
+						{
+							"name":          "Everyone",
+							"organization":  "coder",
+							"runtime_hours": "312.5",
+							"cost":          "1250.0",
+						},
+						{
+							"name":          "Developers",
+							"organization":  "coder",
+							"runtime_hours": "168.0",
+							"cost":          "840.0",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "TemplateTemplateVersionRolloutHalted",
+			id:   notifications.TemplateTemplateVersionRolloutHalted,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"template":          "alpha",
+					"version":           "brave_hopper3",
+					"failed_builds":     "4",
+					"total_builds":      "10",
+					"failure_threshold": "25",
+					"organization":      "coder",
+				},
+			},
+		},
 		{
 			name: "TemplateWorkspaceCreated",
 			id:   notifications.TemplateWorkspaceCreated,
@@ -1227,6 +1305,28 @@ func TestNotificationTemplates_Golden(t *testing.T) {
 				Labels:       map[string]string{},
 			},
 		},
+		{
+			name: "TemplateNotificationDigest",
+			id:   notifications.TemplateNotificationDigest,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels:       map[string]string{},
+				Data: map[string]any{
+					"notifications": []map[string]any{
+						{
+							"title":      "Workspace 'bobby-workspace' has been created",
+							"created_at": "2024-10-10 14:21 UTC",
+						},
+						{
+							"title":      "Template 'alpha' has been deprecated",
+							"created_at": "2024-10-11 08:47 UTC",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "TemplateWorkspaceResourceReplaced",
 			id:   notifications.TemplateWorkspaceResourceReplaced,
@@ -1702,6 +1802,67 @@ func TestDisabledBeforeEnqueue(t *testing.T) {
 	require.ErrorIs(t, err, notifications.ErrCannotEnqueueDisabledNotification, "enqueueing did not fail with expected error")
 }
 
+// TestCategoryDisabledBeforeEnqueue ensures that notifications are not enqueued on the methods which the user disabled
+// for the notification's category.
+func TestCategoryDisabledBeforeEnqueue(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("This test requires postgres; it relies on business-logic only implemented in the database")
+	}
+
+	// nolint:gocritic // Unit test.
+	ctx := dbauthz.AsNotifier(testutil.Context(t, testutil.WaitSuperLong))
+	store, _ := dbtestutil.NewDB(t)
+	logger := testutil.Logger(t)
+
+	// GIVEN: an enqueuer which delivers on SMTP and the inbox & a sample user
+	cfg := defaultNotificationsConfig(database.NotificationMethodSmtp)
+	enq, err := notifications.NewStoreEnqueuer(cfg, store, defaultHelpers(), logger.Named("enqueuer"), quartz.NewReal())
+	require.NoError(t, err)
+	user := createSampleUser(t, store)
+
+	// WHEN: the user disables email delivery of build notifications
+	_, err = store.UpdateUserNotificationCategoryPreferences(ctx, database.UpdateUserNotificationCategoryPreferencesParams{
+		UserID:     user.ID,
+		Categories: []database.NotificationCategory{database.NotificationCategoryBuilds},
+		Methods:    []database.NotificationMethod{database.NotificationMethodSmtp},
+		Disableds:  []bool{true},
+	})
+	require.NoError(t, err, "failed to set preferences")
+
+	// THEN: build notifications are only enqueued on the inbox
+	msgIDs, err := enq.Enqueue(ctx, user.ID, notifications.TemplateWorkspaceDeleted, map[string]string{}, "test")
+	require.NoError(t, err)
+	require.Len(t, msgIDs, 1)
+	msgs, err := store.GetNotificationMessagesByStatus(ctx, database.GetNotificationMessagesByStatusParams{
+		Status: database.NotificationMessageStatusPending,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	require.Equal(t, database.NotificationMethodInbox, msgs[0].Method)
+
+	// THEN: notifications of other categories are still delivered by email
+	msgIDs, err = enq.Enqueue(ctx, user.ID, notifications.TemplateWorkspaceDormant, map[string]string{}, "test")
+	require.NoError(t, err)
+	require.Len(t, msgIDs, 2)
+
+	// WHEN: the user also disables the inbox delivery of build notifications
+	_, err = store.UpdateUserNotificationCategoryPreferences(ctx, database.UpdateUserNotificationCategoryPreferencesParams{
+		UserID:     user.ID,
+		Categories: []database.NotificationCategory{database.NotificationCategoryBuilds},
+		Methods:    []database.NotificationMethod{database.NotificationMethodInbox},
+		Disableds:  []bool{true},
+	})
+	require.NoError(t, err, "failed to set preferences")
+
+	// THEN: enqueuing build notifications fails as the whole category is disabled
+	_, err = enq.Enqueue(ctx, user.ID, notifications.TemplateWorkspaceAutoUpdated, map[string]string{}, "test")
+	require.ErrorIs(t, err, notifications.ErrCannotEnqueueDisabledNotification)
+}
+
 // TestDisabledAfterEnqueue ensures that notifications enqueued before a notification template was disabled will not be
 // sent, and will instead be marked as "inhibited".
 func TestDisabledAfterEnqueue(t *testing.T) {
@@ -2122,6 +2283,107 @@ func TestNotificationOneTimePasswordDeliveryTargets(t *testing.T) {
 	})
 }
 
+func TestNotificationQuietHoursRouting(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("This test requires postgres; it relies on the triggers in the database")
+	}
+
+	// nolint:gocritic // Unit test.
+	ctx := dbauthz.AsNotifier(testutil.Context(t, testutil.WaitSuperLong))
+	store, _ := dbtestutil.NewDB(t)
+	logger := testutil.Logger(t)
+
+	// Quiet hours run from midnight to 08:00 UTC, and it is 02:00 UTC.
+	sched, err := cron.Daily("CRON_TZ=UTC 0 0 * * *")
+	require.NoError(t, err)
+	mClock := quartz.NewMock(t)
+	mClock.Set(time.Date(2024, 1, 15, 2, 0, 0, 0, time.UTC))
+	quietUntil := time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)
+
+	cfg := defaultNotificationsConfig(database.NotificationMethodSmtp)
+	cfg.Inbox.Enabled = true
+	cfg.QuietHoursDuration = serpent.Duration(8 * time.Hour)
+	enq, err := notifications.NewStoreEnqueuer(cfg, store, defaultHelpers(), logger.Named("enqueuer"), mClock)
+	require.NoError(t, err)
+	enq.WithQuietHours(func(context.Context, uuid.UUID) (*cron.Schedule, error) {
+		return sched, nil
+	})
+
+	messages := func(t *testing.T, ids []uuid.UUID) map[database.NotificationMethod]database.NotificationMessage {
+		t.Helper()
+		msgs, err := store.GetNotificationMessagesByStatus(ctx, database.GetNotificationMessagesByStatusParams{
+			Status: database.NotificationMessageStatusPending,
+			Limit:  100,
+		})
+		require.NoError(t, err)
+		out := make(map[database.NotificationMethod]database.NotificationMessage)
+		for _, msg := range msgs {
+			if slices.Contains(ids, msg.ID) {
+				out[msg.Method] = msg
+			}
+		}
+		require.Len(t, out, len(ids))
+		return out
+	}
+
+	t.Run("NormalPriority", func(t *testing.T) {
+		t.Parallel()
+
+		user := dbgen.User(t, store, database.User{})
+		ids, err := enq.Enqueue(ctx, user.ID, notifications.TemplateWorkspaceMarkedForDeletion,
+			map[string]string{"name": "bobby-workspace", "reason": "dormant", "timeTilDormant": "24h"}, "test")
+		require.NoError(t, err)
+
+		// The email is held back until the end of quiet hours, the inbox is not.
+		msgs := messages(t, ids)
+		require.True(t, msgs[database.NotificationMethodSmtp].DeliverAfter.Valid)
+		require.True(t, quietUntil.Equal(msgs[database.NotificationMethodSmtp].DeliverAfter.Time))
+		require.False(t, msgs[database.NotificationMethodSmtp].Digest)
+		require.False(t, msgs[database.NotificationMethodInbox].DeliverAfter.Valid)
+	})
+
+	t.Run("HighPriority", func(t *testing.T) {
+		t.Parallel()
+
+		user := dbgen.User(t, store, database.User{})
+		ids, err := enq.Enqueue(ctx, user.ID, notifications.TemplateTestNotification, map[string]string{}, "test")
+		require.NoError(t, err)
+
+		msgs := messages(t, ids)
+		require.False(t, msgs[database.NotificationMethodSmtp].DeliverAfter.Valid)
+	})
+
+	t.Run("Digest", func(t *testing.T) {
+		t.Parallel()
+
+		user := dbgen.User(t, store, database.User{})
+		_, err := store.UpdateUserNotificationDigest(ctx, database.UpdateUserNotificationDigestParams{
+			UserID:             user.ID,
+			NotificationDigest: "true",
+		})
+		require.NoError(t, err)
+
+		// Low priority notifications are batched into the digest.
+		ids, err := enq.Enqueue(ctx, user.ID, notifications.TemplateWorkspaceAutoUpdated,
+			map[string]string{"name": "bobby-workspace", "template_version_name": "v2", "template_version_message": "Update docker image"}, "test")
+		require.NoError(t, err)
+		msgs := messages(t, ids)
+		require.True(t, msgs[database.NotificationMethodSmtp].Digest)
+		require.True(t, quietUntil.Equal(msgs[database.NotificationMethodSmtp].DeliverAfter.Time))
+		require.False(t, msgs[database.NotificationMethodInbox].Digest)
+
+		// Other notifications are not.
+		ids, err = enq.Enqueue(ctx, user.ID, notifications.TemplateWorkspaceDeleted,
+			map[string]string{"name": "bobby-workspace", "reason": "autodeleted due to dormancy", "initiator": "autobuild"}, "test")
+		require.NoError(t, err)
+		msgs = messages(t, ids)
+		require.False(t, msgs[database.NotificationMethodSmtp].Digest)
+	})
+}
+
 type fakeHandler struct {
 	mu                sync.RWMutex
 	succeeded, failed []string
@@ -0,0 +1,42 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/schedule/cron"
+)
+
+// QuietHoursScheduleFunc returns the daily quiet hours schedule of the given user, or nil if the user does not have
+// one.
+type QuietHoursScheduleFunc func(ctx context.Context, userID uuid.UUID) (*cron.Schedule, error)
+
+// quietHoursEnd returns the end of the quiet hours window which contains the given time. The second return value is
+// false if the time is outside a quiet hours window.
+func quietHoursEnd(sched *cron.Schedule, duration time.Duration, now time.Time) (time.Time, bool) {
+	if sched == nil || duration <= 0 {
+		return time.Time{}, false
+	}
+
+	// Quiet hours schedules are daily, so the window which may contain the given time started within the last day.
+	start := sched.Next(now.Add(-24 * time.Hour))
+	end := start.Add(duration)
+	if start.After(now) || !now.Before(end) {
+		return time.Time{}, false
+	}
+	return end, true
+}
+
+// nextDigestAt returns when the next notification digest should be delivered: at the end of the user's next quiet
+// hours window, or at midnight UTC if the user does not have a quiet hours schedule.
+func nextDigestAt(sched *cron.Schedule, duration time.Duration, now time.Time) time.Time {
+	if sched == nil {
+		return now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	}
+	if end, ok := quietHoursEnd(sched, duration, now); ok {
+		return end
+	}
+	return sched.Next(now).Add(max(duration, 0))
+}
@@ -0,0 +1,99 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/schedule/cron"
+)
+
+func TestQuietHoursEnd(t *testing.T) {
+	t.Parallel()
+
+	// Quiet hours start at 22:00 in New York, which is 03:00 UTC in January.
+	sched, err := cron.Daily("CRON_TZ=America/New_York 0 22 * * *")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		sched    *cron.Schedule
+		duration time.Duration
+		now      time.Time
+		end      time.Time
+		ok       bool
+	}{
+		{
+			name:     "NoSchedule",
+			duration: 8 * time.Hour,
+			now:      time.Date(2024, 1, 15, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "NoDuration",
+			sched: sched,
+			now:   time.Date(2024, 1, 15, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "BeforeWindow",
+			sched:    sched,
+			duration: 8 * time.Hour,
+			now:      time.Date(2024, 1, 15, 2, 59, 0, 0, time.UTC),
+		},
+		{
+			name:     "WindowStart",
+			sched:    sched,
+			duration: 8 * time.Hour,
+			now:      time.Date(2024, 1, 15, 3, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC),
+			ok:       true,
+		},
+		{
+			name:     "InWindow",
+			sched:    sched,
+			duration: 8 * time.Hour,
+			now:      time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC),
+			ok:       true,
+		},
+		{
+			name:     "AfterWindow",
+			sched:    sched,
+			duration: 8 * time.Hour,
+			now:      time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			end, ok := quietHoursEnd(tc.sched, tc.duration, tc.now)
+			require.Equal(t, tc.ok, ok)
+			require.True(t, tc.end.Equal(end), "expected %s, got %s", tc.end, end)
+		})
+	}
+}
+
+func TestNextDigestAt(t *testing.T) {
+	t.Parallel()
+
+	sched, err := cron.Daily("CRON_TZ=America/New_York 0 22 * * *")
+	require.NoError(t, err)
+
+	// Without a schedule, digests are delivered at midnight UTC.
+	next := nextDigestAt(nil, 8*time.Hour, time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC))
+	require.True(t, time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC).Equal(next), next)
+
+	// During quiet hours, the digest is delivered when they end.
+	next = nextDigestAt(sched, 8*time.Hour, time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC))
+	require.True(t, time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC).Equal(next), next)
+
+	// Otherwise, it is delivered when the next quiet hours end.
+	next = nextDigestAt(sched, 8*time.Hour, time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	require.True(t, time.Date(2024, 1, 16, 11, 0, 0, 0, time.UTC).Equal(next), next)
+
+	// Without a quiet hours duration, it is delivered when quiet hours start.
+	next = nextDigestAt(sched, 0, time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	require.True(t, time.Date(2024, 1, 16, 3, 0, 0, 0, time.UTC).Equal(next), next)
+}
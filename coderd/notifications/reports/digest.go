@@ -0,0 +1,91 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/quartz"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/render"
+	"github.com/coder/coder/v2/coderd/notifications/types"
+)
+
+// sendNotificationDigests batches the low priority messages of users who opted into digests, once their digest is
+// due, into a single notification per user. The batched messages are marked as sent.
+func sendNotificationDigests(ctx context.Context, logger slog.Logger, db database.Store, enqueuer notifications.Enqueuer, clk quartz.Clock) error {
+	now := dbtime.Time(clk.Now()).UTC()
+
+	msgs, err := db.GetDueNotificationDigestMessages(ctx, now)
+	if err != nil {
+		return xerrors.Errorf("unable to fetch due notification digest messages: %w", err)
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	// Messages are ordered by user, so each user's digest is a contiguous run.
+	for start := 0; start < len(msgs); {
+		end := start
+		for end < len(msgs) && msgs[end].UserID == msgs[start].UserID {
+			end++
+		}
+		userMsgs := msgs[start:end]
+		start = end
+
+		if _, err := enqueuer.EnqueueWithData(ctx, userMsgs[0].UserID, notifications.TemplateNotificationDigest,
+			map[string]string{},
+			buildDataForNotificationDigest(ctx, logger, userMsgs),
+			"digest_generator",
+		); err != nil && !xerrors.Is(err, notifications.ErrCannotEnqueueDisabledNotification) && !xerrors.Is(err, notifications.ErrDuplicate) {
+			logger.Warn(ctx, "failed to send a notification digest", slog.F("user_id", userMsgs[0].UserID), slog.Error(err))
+			continue
+		}
+
+		ids := make([]uuid.UUID, 0, len(userMsgs))
+		sentAts := make([]time.Time, 0, len(userMsgs))
+		for _, msg := range userMsgs {
+			ids = append(ids, msg.ID)
+			sentAts = append(sentAts, now)
+		}
+		if _, err := db.BulkMarkNotificationMessagesSent(ctx, database.BulkMarkNotificationMessagesSentParams{
+			IDs:     ids,
+			SentAts: sentAts,
+		}); err != nil {
+			return xerrors.Errorf("unable to mark digest messages as sent: %w", err)
+		}
+	}
+	return nil
+}
+
+func buildDataForNotificationDigest(ctx context.Context, logger slog.Logger, msgs []database.GetDueNotificationDigestMessagesRow) map[string]any {
+	items := make([]map[string]any, 0, len(msgs))
+	for _, msg := range msgs {
+		var payload types.MessagePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			logger.Warn(ctx, "failed to decode digest message payload", slog.F("msg_id", msg.ID), slog.Error(err))
+		}
+
+		// Titles only depend on the payload, so they can be rendered without the deployment helpers.
+		title, err := render.GoTemplate(msg.TitleTemplate, payload, nil)
+		if err != nil {
+			title = payload.NotificationName
+		}
+
+		items = append(items, map[string]any{
+			"title":      title,
+			"created_at": msg.CreatedAt.UTC().Format("2006-01-02 15:04 MST"),
+		})
+	}
+
+	return map[string]any{
+		"notifications": items,
+	}
+}
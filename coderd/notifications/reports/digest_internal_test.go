@@ -0,0 +1,96 @@
+package reports
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/types"
+)
+
+func TestSendNotificationDigests(t *testing.T) {
+	t.Parallel()
+
+	// Setup
+	ctx, logger, db, _, notifEnq, clk := setup(t)
+	clk.Set(time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC))
+	now := clk.Now()
+
+	user := dbgen.User(t, db, database.User{})
+	enqueue := func(templateID uuid.UUID, labels map[string]string, deliverAfter time.Time) uuid.UUID {
+		t.Helper()
+		payload, err := json.Marshal(types.MessagePayload{
+			Labels: labels,
+		})
+		require.NoError(t, err)
+		id := uuid.New()
+		err = db.EnqueueNotificationMessage(ctx, database.EnqueueNotificationMessageParams{
+			ID:                     id,
+			NotificationTemplateID: templateID,
+			UserID:                 user.ID,
+			Method:                 database.NotificationMethodSmtp,
+			Payload:                payload,
+			CreatedBy:              "test",
+			CreatedAt:              now.Add(-time.Hour),
+			DeliverAfter:           sql.NullTime{Time: deliverAfter, Valid: true},
+			Digest:                 true,
+		})
+		require.NoError(t, err)
+		return id
+	}
+
+	// Given: two messages which are due, and one which is not.
+	due1 := enqueue(notifications.TemplateWorkspaceAutoUpdated, map[string]string{"name": "bobby-workspace"}, now.Add(-time.Minute))
+	due2 := enqueue(notifications.TemplateTemplateDeprecated, map[string]string{"template": "alpha", "message": "Use beta instead", "organization": "coder"}, now.Add(-time.Minute))
+	notDue := enqueue(notifications.TemplateWorkspaceAutoUpdated, map[string]string{"name": "other-workspace"}, now.Add(time.Hour))
+
+	// When
+	err := sendNotificationDigests(ctx, logger, db, notifEnq, clk)
+	require.NoError(t, err)
+
+	// Then: a single digest contains the due messages.
+	sent := notifEnq.Sent()
+	require.Len(t, sent, 1)
+	require.Equal(t, user.ID, sent[0].UserID)
+	require.Equal(t, notifications.TemplateNotificationDigest, sent[0].TemplateID)
+	items, ok := sent[0].Data["notifications"].([]map[string]any)
+	require.True(t, ok)
+	require.Len(t, items, 2)
+	require.Equal(t, `Workspace "bobby-workspace" updated automatically`, items[0]["title"])
+	require.Equal(t, "Template 'alpha' has been deprecated", items[1]["title"])
+
+	// Then: the due messages are marked as sent, the other one is still pending.
+	msgs, err := db.GetNotificationMessagesByStatus(ctx, database.GetNotificationMessagesByStatusParams{
+		Status: database.NotificationMessageStatusSent,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	sentIDs := make([]uuid.UUID, 0, len(msgs))
+	for _, msg := range msgs {
+		sentIDs = append(sentIDs, msg.ID)
+	}
+	require.ElementsMatch(t, []uuid.UUID{due1, due2}, sentIDs)
+
+	msgs, err = db.GetNotificationMessagesByStatus(ctx, database.GetNotificationMessagesByStatusParams{
+		Status: database.NotificationMessageStatusPending,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	require.Equal(t, notDue, msgs[0].ID)
+
+	// When: the digest generator runs again
+	notifEnq.Clear()
+	err = sendNotificationDigests(ctx, logger, db, notifEnq, clk)
+
+	// Then: nothing is sent twice.
+	require.NoError(t, err)
+	require.Empty(t, notifEnq.Sent())
+}
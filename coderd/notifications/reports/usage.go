@@ -0,0 +1,140 @@
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/quartz"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	workspaceUsageReportFrequency = 7 * 24 * time.Hour
+	// workspaceUsageReportTopEntries is the number of users and groups listed
+	// in the report, the full report can be downloaded as CSV.
+	workspaceUsageReportTopEntries = 10
+)
+
+// reportWorkspaceUsage sends the workspace usage of the last week to the
+// owners and auditors of the deployment. The notification is disabled by
+// default, so only those who opted in receive it.
+func reportWorkspaceUsage(ctx context.Context, logger slog.Logger, db database.Store, enqueuer notifications.Enqueuer, clk quartz.Clock) error {
+	now := clk.Now().UTC()
+
+	reportLog, err := db.GetNotificationReportGeneratorLogByTemplate(ctx, notifications.TemplateWorkspaceUsageReport)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("unable to read report generator log: %w", err)
+	}
+	if err == nil && reportLog.LastGeneratedAt.Add(workspaceUsageReportFrequency).After(now) {
+		return nil // report sent recently, no need to send it now
+	}
+
+	// Report whole days, the insights endpoints the report links to only
+	// accept times at midnight.
+	y, m, d := now.Date()
+	endTime := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	startTime := endTime.Add(-workspaceUsageReportFrequency)
+
+	userUsage, err := db.GetUserWorkspaceUsage(ctx, database.GetUserWorkspaceUsageParams{
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		return xerrors.Errorf("unable to fetch user workspace usage: %w", err)
+	}
+	groupUsage, err := db.GetGroupWorkspaceUsage(ctx, database.GetGroupWorkspaceUsageParams{
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		return xerrors.Errorf("unable to fetch group workspace usage: %w", err)
+	}
+
+	if len(userUsage) > 0 {
+		recipients, err := db.GetUsers(ctx, database.GetUsersParams{
+			Status:   []database.UserStatus{database.UserStatusActive},
+			RbacRole: []string{codersdk.RoleOwner, codersdk.RoleAuditor},
+		})
+		if err != nil {
+			return xerrors.Errorf("unable to fetch report recipients: %w", err)
+		}
+
+		reportData := buildDataForReportWorkspaceUsage(startTime, endTime, userUsage, groupUsage)
+		for _, recipient := range recipients {
+			if _, err := enqueuer.EnqueueWithData(ctx, recipient.ID, notifications.TemplateWorkspaceUsageReport,
+				map[string]string{},
+				reportData,
+				"report_generator",
+			); err != nil && !xerrors.Is(err, notifications.ErrCannotEnqueueDisabledNotification) {
+				logger.Warn(ctx, "failed to send a workspace usage report", slog.F("user_id", recipient.ID), slog.Error(err))
+			}
+		}
+	}
+
+	err = db.UpsertNotificationReportGeneratorLog(ctx, database.UpsertNotificationReportGeneratorLogParams{
+		NotificationTemplateID: notifications.TemplateWorkspaceUsageReport,
+		LastGeneratedAt:        dbtime.Time(now).UTC(),
+	})
+	if err != nil {
+		return xerrors.Errorf("unable to update report generator logs: %w", err)
+	}
+	return nil
+}
+
+func buildDataForReportWorkspaceUsage(startTime, endTime time.Time, userUsage []database.GetUserWorkspaceUsageRow, groupUsage []database.GetGroupWorkspaceUsageRow) map[string]any {
+	// Numbers are preformatted, template functions cannot round them.
+	formatFloat := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', 1, 64)
+	}
+
+	var runtimeHours, cost float64
+	users := []map[string]any{}
+	for i, usage := range userUsage {
+		runtimeHours += usage.RuntimeHours
+		cost += usage.Cost
+		if i < workspaceUsageReportTopEntries {
+			users = append(users, map[string]any{
+				"username":      usage.Username,
+				"runtime_hours": formatFloat(usage.RuntimeHours),
+				"cost":          formatFloat(usage.Cost),
+			})
+		}
+	}
+
+	groups := []map[string]any{}
+	for _, usage := range groupUsage {
+		if len(groups) == workspaceUsageReportTopEntries {
+			break
+		}
+		name := usage.GroupDisplayName
+		if name == "" {
+			name = usage.GroupName
+		}
+		groups = append(groups, map[string]any{
+			"name":          name,
+			"organization":  usage.OrganizationName,
+			"runtime_hours": formatFloat(usage.RuntimeHours),
+			"cost":          formatFloat(usage.Cost),
+		})
+	}
+
+	return map[string]any{
+		"start_time":    startTime.Format(time.RFC3339),
+		"end_time":      endTime.Format(time.RFC3339),
+		"start_date":    startTime.Format(time.DateOnly),
+		"end_date":      endTime.Add(-time.Nanosecond).Format(time.DateOnly),
+		"runtime_hours": formatFloat(runtimeHours),
+		"cost":          formatFloat(cost),
+		"users":         users,
+		"groups":        groups,
+	}
+}
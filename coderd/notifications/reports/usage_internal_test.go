@@ -0,0 +1,115 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/notifications"
+)
+
+func TestReportWorkspaceUsage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NoUsage_NoReport", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup
+		ctx, logger, db, _, notifEnq, clk := setup(t)
+
+		// When: the report is generated without any workspace activity
+		err := reportWorkspaceUsage(ctx, logger, db, notifEnq, clk)
+		require.NoError(t, err)
+
+		// Then: no notification is sent, but the report generator log is updated
+		require.Empty(t, notifEnq.Sent())
+		reportLog, err := db.GetNotificationReportGeneratorLogByTemplate(ctx, notifications.TemplateWorkspaceUsageReport)
+		require.NoError(t, err)
+		require.Equal(t, clk.Now().UTC().Unix(), reportLog.LastGeneratedAt.UTC().Unix())
+	})
+
+	t.Run("SentRecently_NoReport", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup
+		ctx, logger, db, _, notifEnq, clk := setup(t)
+
+		// Given: the report was generated an hour ago
+		err := db.UpsertNotificationReportGeneratorLog(ctx, database.UpsertNotificationReportGeneratorLogParams{
+			NotificationTemplateID: notifications.TemplateWorkspaceUsageReport,
+			LastGeneratedAt:        clk.Now().Add(-time.Hour).UTC(),
+		})
+		require.NoError(t, err)
+
+		// When
+		err = reportWorkspaceUsage(ctx, logger, db, notifEnq, clk)
+		require.NoError(t, err)
+
+		// Then: the log is untouched
+		require.Empty(t, notifEnq.Sent())
+		reportLog, err := db.GetNotificationReportGeneratorLogByTemplate(ctx, notifications.TemplateWorkspaceUsageReport)
+		require.NoError(t, err)
+		require.Equal(t, clk.Now().Add(-time.Hour).UTC().Unix(), reportLog.LastGeneratedAt.UTC().Unix())
+	})
+}
+
+func TestBuildDataForReportWorkspaceUsage(t *testing.T) {
+	t.Parallel()
+
+	startTime := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	endTime := startTime.AddDate(0, 0, 7)
+
+	userUsage := make([]database.GetUserWorkspaceUsageRow, 0, workspaceUsageReportTopEntries+2)
+	for i := 0; i < workspaceUsageReportTopEntries+2; i++ {
+		userUsage = append(userUsage, database.GetUserWorkspaceUsageRow{
+			UserID:       uuid.New(),
+			Username:     "user",
+			RuntimeHours: 1.5,
+			Cost:         2.5,
+		})
+	}
+	groupUsage := []database.GetGroupWorkspaceUsageRow{
+		{
+			GroupID:          uuid.New(),
+			GroupName:        "everyone",
+			OrganizationName: "coder",
+			RuntimeHours:     15,
+			Cost:             30,
+		},
+		{
+			GroupID:          uuid.New(),
+			GroupName:        "devs",
+			GroupDisplayName: "Developers",
+			OrganizationName: "coder",
+			RuntimeHours:     10.04,
+			Cost:             20.06,
+		},
+	}
+
+	data := buildDataForReportWorkspaceUsage(startTime, endTime, userUsage, groupUsage)
+
+	require.Equal(t, "2024-05-06T00:00:00Z", data["start_time"])
+	require.Equal(t, "2024-05-13T00:00:00Z", data["end_time"])
+	require.Equal(t, "2024-05-06", data["start_date"])
+	require.Equal(t, "2024-05-12", data["end_date"])
+	// Totals include the users that are not listed.
+	require.Equal(t, "18.0", data["runtime_hours"])
+	require.Equal(t, "30.0", data["cost"])
+
+	users, ok := data["users"].([]map[string]any)
+	require.True(t, ok)
+	require.Len(t, users, workspaceUsageReportTopEntries)
+	require.Equal(t, "1.5", users[0]["runtime_hours"])
+	require.Equal(t, "2.5", users[0]["cost"])
+
+	groups, ok := data["groups"].([]map[string]any)
+	require.True(t, ok)
+	require.Len(t, groups, 2)
+	require.Equal(t, "everyone", groups[0]["name"])
+	require.Equal(t, "Developers", groups[1]["name"])
+	require.Equal(t, "10.0", groups[1]["runtime_hours"])
+	require.Equal(t, "20.1", groups[1]["cost"])
+}
@@ -270,6 +270,154 @@ func TestNotificationPreferences(t *testing.T) {
 	})
 }
 
+func TestNotificationCategoryPreferences(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Modify preferences", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		api := coderdtest.New(t, createOpts(t))
+		firstUser := coderdtest.CreateFirstUser(t, api)
+
+		// Given: a member in its initial state.
+		memberClient, _ := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+
+		// Then: the member has no category preferences.
+		prefs, err := memberClient.GetUserNotificationCategoryPreferences(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Empty(t, prefs)
+
+		// When: the member disables email delivery of build and dormancy notifications.
+		prefs, err = memberClient.UpdateUserNotificationCategoryPreferences(ctx, codersdk.Me, codersdk.UpdateUserNotificationCategoryPreferences{
+			Preferences: []codersdk.UpdateNotificationCategoryPreference{
+				{Category: codersdk.NotificationCategoryBuilds, Method: string(database.NotificationMethodSmtp), Disabled: true},
+				{Category: codersdk.NotificationCategoryDormancy, Method: string(database.NotificationMethodSmtp), Disabled: true},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, prefs, 2)
+
+		// When: the member re-enables email delivery of dormancy notifications.
+		_, err = memberClient.UpdateUserNotificationCategoryPreferences(ctx, codersdk.Me, codersdk.UpdateUserNotificationCategoryPreferences{
+			Preferences: []codersdk.UpdateNotificationCategoryPreference{
+				{Category: codersdk.NotificationCategoryDormancy, Method: string(database.NotificationMethodSmtp), Disabled: false},
+			},
+		})
+		require.NoError(t, err)
+
+		// Then: the preferences are persisted.
+		prefs, err = memberClient.GetUserNotificationCategoryPreferences(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, prefs, 2)
+		require.Equal(t, codersdk.NotificationCategoryBuilds, prefs[0].Category)
+		require.True(t, prefs[0].Disabled)
+		require.Equal(t, codersdk.NotificationCategoryDormancy, prefs[1].Category)
+		require.False(t, prefs[1].Disabled)
+	})
+
+	t.Run("Invalid preferences", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		api := coderdtest.New(t, createOpts(t))
+		_ = coderdtest.CreateFirstUser(t, api)
+
+		// When: updating a preference of an unknown category and method.
+		_, err := api.UpdateUserNotificationCategoryPreferences(ctx, codersdk.Me, codersdk.UpdateUserNotificationCategoryPreferences{
+			Preferences: []codersdk.UpdateNotificationCategoryPreference{
+				{Category: "unknown", Method: "pigeon", Disabled: true},
+			},
+		})
+
+		// Then: the API should reject the request.
+		var sdkError *codersdk.Error
+		require.ErrorAs(t, err, &sdkError)
+		require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+		require.Len(t, sdkError.Validations, 2)
+	})
+
+	t.Run("Insufficient permissions", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		api := coderdtest.New(t, createOpts(t))
+		firstUser := coderdtest.CreateFirstUser(t, api)
+
+		// Given: 2 members.
+		_, member1 := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+		member2Client, _ := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+
+		// When: attempting to update the preferences of another member.
+		_, err := member2Client.UpdateUserNotificationCategoryPreferences(ctx, member1.ID.String(), codersdk.UpdateUserNotificationCategoryPreferences{
+			Preferences: []codersdk.UpdateNotificationCategoryPreference{
+				{Category: codersdk.NotificationCategoryBuilds, Method: string(database.NotificationMethodSmtp), Disabled: true},
+			},
+		})
+
+		// Then: the API should reject the request.
+		var sdkError *codersdk.Error
+		require.ErrorAs(t, err, &sdkError)
+		// NOTE: ExtractUserParam returns a 400 Bad Request instead of a 403 Forbidden, see TestNotificationPreferences.
+		require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+	})
+}
+
+func TestUserNotificationSettings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Digest", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		api := coderdtest.New(t, createOpts(t))
+		firstUser := coderdtest.CreateFirstUser(t, api)
+
+		// Given: a member in its initial state.
+		memberClient, member := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+
+		// Then: digests are disabled.
+		settings, err := memberClient.GetUserNotificationSettings(ctx, member.ID)
+		require.NoError(t, err)
+		require.False(t, settings.Digest)
+
+		// When: the member opts into digests.
+		settings, err = memberClient.UpdateUserNotificationSettings(ctx, member.ID, codersdk.UserNotificationSettings{
+			Digest: true,
+		})
+		require.NoError(t, err)
+		require.True(t, settings.Digest)
+
+		// Then: the setting is persisted.
+		settings, err = memberClient.GetUserNotificationSettings(ctx, member.ID)
+		require.NoError(t, err)
+		require.True(t, settings.Digest)
+	})
+
+	t.Run("Insufficient permissions", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		api := coderdtest.New(t, createOpts(t))
+		firstUser := coderdtest.CreateFirstUser(t, api)
+
+		// Given: 2 members.
+		_, member1 := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+		member2Client, _ := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+
+		// When: attempting to update the settings of another member.
+		_, err := member2Client.UpdateUserNotificationSettings(ctx, member1.ID, codersdk.UserNotificationSettings{
+			Digest: true,
+		})
+
+		// Then: the API should reject the request.
+		var sdkError *codersdk.Error
+		require.ErrorAs(t, err, &sdkError)
+		// NOTE: ExtractUserParam returns a 400 Bad Request instead of a 403 Forbidden, see TestNotificationPreferences.
+		require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+	})
+}
+
 func TestNotificationDispatchMethods(t *testing.T) {
 	t.Parallel()
 
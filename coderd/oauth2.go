@@ -138,10 +138,11 @@ func (api *API) postOAuth2ProviderAppAuthorize() http.HandlerFunc {
 // @ID oauth2-token-exchange
 // @Produce json
 // @Tags Enterprise
-// @Param client_id formData string false "Client ID, required if grant_type=authorization_code"
-// @Param client_secret formData string false "Client secret, required if grant_type=authorization_code"
+// @Param client_id formData string false "Client ID, required if grant_type=authorization_code or grant_type=urn:ietf:params:oauth:grant-type:device_code"
+// @Param client_secret formData string false "Client secret, required if grant_type=authorization_code or grant_type=urn:ietf:params:oauth:grant-type:device_code"
 // @Param code formData string false "Authorization code, required if grant_type=authorization_code"
 // @Param refresh_token formData string false "Refresh token, required if grant_type=refresh_token"
+// @Param device_code formData string false "Device code, required if grant_type=urn:ietf:params:oauth:grant-type:device_code"
 // @Param grant_type formData codersdk.OAuth2ProviderGrantType true "Grant type"
 // @Success 200 {object} oauth2.Token
 // @Router /oauth2/tokens [post]
@@ -160,6 +161,69 @@ func (api *API) deleteOAuth2ProviderAppTokens() http.HandlerFunc {
 	return oauth2provider.RevokeApp(api.Database)
 }
 
+// @Summary OAuth2 device authorization request (RFC 8628).
+// @ID oauth2-device-authorization-request
+// @Produce json
+// @Tags Enterprise
+// @Param client_id formData string true "Client ID"
+// @Param client_secret formData string false "Client secret"
+// @Param scope formData string false "Token scopes (currently ignored)"
+// @Param resource formData string false "Resource the token is for (RFC 8707)"
+// @Success 200 {object} codersdk.OAuth2DeviceAuthorizationResponse
+// @Router /oauth2/device [post]
+func (api *API) postOAuth2DeviceAuthorization() http.HandlerFunc {
+	return oauth2provider.DeviceAuthorization(api.Database, api.AccessURL)
+}
+
+// @Summary OAuth2 device verification (GET - show verification page).
+// @ID oauth2-device-verification-get
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param user_code query string false "User code displayed on the device"
+// @Success 200 "Returns HTML verification page"
+// @Router /oauth2/device/verify [get]
+func (api *API) getOAuth2DeviceVerification() http.HandlerFunc {
+	return oauth2provider.ShowDeviceVerificationPage(api.Database, api.AccessURL)
+}
+
+// @Summary OAuth2 device verification (POST - approve device).
+// @ID oauth2-device-verification-post
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param user_code query string true "User code displayed on the device"
+// @Success 200 "Returns HTML confirmation page"
+// @Router /oauth2/device/verify [post]
+func (api *API) postOAuth2DeviceVerification() http.HandlerFunc {
+	return oauth2provider.ProcessDeviceVerification(api.Database, api.AccessURL)
+}
+
+// @Summary OAuth2 token introspection (RFC 7662).
+// @ID oauth2-token-introspection
+// @Produce json
+// @Tags Enterprise
+// @Param client_id formData string true "Client ID"
+// @Param client_secret formData string true "Client secret"
+// @Param token formData string true "Access or refresh token"
+// @Param token_type_hint formData string false "Token type hint (ignored)"
+// @Success 200 {object} codersdk.OAuth2TokenIntrospectionResponse
+// @Router /oauth2/introspect [post]
+func (api *API) postOAuth2TokenIntrospection() http.HandlerFunc {
+	return oauth2provider.IntrospectToken(api.Database, api.AccessURL)
+}
+
+// @Summary OAuth2 token revocation (RFC 7009).
+// @ID oauth2-token-revocation
+// @Tags Enterprise
+// @Param client_id formData string true "Client ID"
+// @Param client_secret formData string true "Client secret"
+// @Param token formData string true "Access or refresh token"
+// @Param token_type_hint formData string false "Token type hint (ignored)"
+// @Success 200
+// @Router /oauth2/revoke [post]
+func (api *API) postOAuth2TokenRevocation() http.HandlerFunc {
+	return oauth2provider.RevokeToken(api.Database)
+}
+
 // @Summary OAuth2 authorization server metadata.
 // @ID oauth2-authorization-server-metadata
 // @Produce json
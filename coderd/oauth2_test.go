@@ -836,6 +836,210 @@ func TestOAuth2ProviderRevoke(t *testing.T) {
 	}
 }
 
+func TestOAuth2ProviderDeviceAuthorization(t *testing.T) {
+	t.Parallel()
+
+	ownerClient := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	client, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+	ctx := testutil.Context(t, testutil.WaitLong)
+	cfg := newOAuth2ProviderConfig(ctx, t, ownerClient, "device-authorization")
+
+	da, err := cfg.DeviceAuth(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, da.DeviceCode)
+	require.Regexp(t, `^[A-Z]{4}-[A-Z]{4}$`, da.UserCode)
+	require.Equal(t, ownerClient.URL.JoinPath("/oauth2/device/verify").String(), da.VerificationURI)
+	require.Contains(t, da.VerificationURIComplete, "user_code="+da.UserCode)
+	require.EqualValues(t, 5, da.Interval)
+
+	// The device is pending until the user approves it.
+	_, err = deviceTokenExchange(ctx, cfg, da.DeviceCode)
+	require.ErrorContains(t, err, "authorization_pending")
+
+	// Devices must respect the polling interval.
+	_, err = deviceTokenExchange(ctx, cfg, da.DeviceCode)
+	require.ErrorContains(t, err, "slow_down")
+
+	// Unknown codes cannot be approved.
+	res, err := client.Request(ctx, http.MethodPost, "/oauth2/device/verify?user_code=BBBB-BBBB", nil)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	// Users may enter the code in lowercase.
+	res, err = client.Request(ctx, http.MethodPost, "/oauth2/device/verify?user_code="+strings.ToLower(da.UserCode), nil)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	// Codes can only be approved once.
+	res, err = client.Request(ctx, http.MethodPost, "/oauth2/device/verify?user_code="+da.UserCode, nil)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	token, err := deviceTokenExchange(ctx, cfg, da.DeviceCode)
+	require.NoError(t, err)
+	require.NotEmpty(t, token.RefreshToken)
+
+	newClient := codersdk.New(ownerClient.URL)
+	newClient.SetSessionToken(token.AccessToken)
+	gotUser, err := newClient.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, gotUser.ID)
+
+	// The device code is consumed by the exchange.
+	_, err = deviceTokenExchange(ctx, cfg, da.DeviceCode)
+	require.ErrorContains(t, err, "invalid_grant")
+}
+
+func TestOAuth2ProviderTokenIntrospection(t *testing.T) {
+	t.Parallel()
+
+	ownerClient := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	client, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+	ctx := testutil.Context(t, testutil.WaitLong)
+	cfg := newOAuth2ProviderConfig(ctx, t, ownerClient, "introspection")
+	otherCfg := newOAuth2ProviderConfig(ctx, t, ownerClient, "introspection-other")
+
+	code, err := authorizationFlow(ctx, client, cfg)
+	require.NoError(t, err)
+	token, err := cfg.Exchange(ctx, code)
+	require.NoError(t, err)
+
+	introspect := func(cfg *oauth2.Config, token string) codersdk.OAuth2TokenIntrospectionResponse {
+		res, err := postOAuth2ClientForm(ctx, cfg, "/oauth2/introspect", url.Values{"token": {token}})
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var resp codersdk.OAuth2TokenIntrospectionResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&resp))
+		return resp
+	}
+
+	resp := introspect(cfg, token.AccessToken)
+	require.True(t, resp.Active)
+	require.Equal(t, cfg.ClientID, resp.ClientID)
+	require.Equal(t, user.Username, resp.Username)
+	require.Equal(t, user.ID.String(), resp.Sub)
+	require.Equal(t, "Bearer", resp.TokenType)
+	require.Greater(t, resp.Exp, time.Now().Unix())
+
+	resp = introspect(cfg, token.RefreshToken)
+	require.True(t, resp.Active)
+	require.Equal(t, user.ID.String(), resp.Sub)
+
+	// Clients cannot introspect tokens issued to other clients.
+	require.False(t, introspect(otherCfg, token.AccessToken).Active)
+	require.False(t, introspect(cfg, "invalid").Active)
+
+	// Clients must authenticate.
+	badCfg := *cfg
+	badCfg.ClientSecret = otherCfg.ClientSecret
+	res, err := postOAuth2ClientForm(ctx, &badCfg, "/oauth2/introspect", url.Values{"token": {token.AccessToken}})
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	// Revoking tokens of other clients has no effect.
+	res, err = postOAuth2ClientForm(ctx, otherCfg, "/oauth2/revoke", url.Values{"token": {token.RefreshToken}})
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.True(t, introspect(cfg, token.AccessToken).Active)
+
+	// Revoking the refresh token revokes the access token too.
+	res, err = postOAuth2ClientForm(ctx, cfg, "/oauth2/revoke", url.Values{"token": {token.RefreshToken}})
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.False(t, introspect(cfg, token.AccessToken).Active)
+	require.False(t, introspect(cfg, token.RefreshToken).Active)
+
+	newClient := codersdk.New(ownerClient.URL)
+	newClient.SetSessionToken(token.AccessToken)
+	_, err = newClient.User(ctx, codersdk.Me)
+	require.ErrorContains(t, err, "401")
+}
+
+// newOAuth2ProviderConfig creates an app with a secret and returns the config
+// for clients of the app.
+func newOAuth2ProviderConfig(ctx context.Context, t *testing.T, client *codersdk.Client, name string) *oauth2.Config {
+	t.Helper()
+
+	//nolint:gocritic // OAauth2 app management requires owner permission.
+	app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+		Name:        name,
+		CallbackURL: "http://localhost",
+	})
+	require.NoError(t, err)
+	//nolint:gocritic // OAauth2 app management requires owner permission.
+	secret, err := client.PostOAuth2ProviderAppSecret(ctx, app.ID)
+	require.NoError(t, err)
+
+	return &oauth2.Config{
+		ClientID:     app.ID.String(),
+		ClientSecret: secret.ClientSecretFull,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:       app.Endpoints.Authorization,
+			DeviceAuthURL: app.Endpoints.DeviceAuth,
+			TokenURL:      app.Endpoints.Token,
+			AuthStyle:     oauth2.AuthStyleInParams,
+		},
+		RedirectURL: app.CallbackURL,
+		Scopes:      []string{},
+	}
+}
+
+// postOAuth2ClientForm posts the form to the path relative to the token
+// endpoint, authenticating as the client.
+func postOAuth2ClientForm(ctx context.Context, cfg *oauth2.Config, path string, data url.Values) (*http.Response, error) {
+	u, err := url.Parse(cfg.Endpoint.TokenURL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path
+	data.Set("client_id", cfg.ClientID)
+	data.Set("client_secret", cfg.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return http.DefaultClient.Do(req)
+}
+
+// deviceTokenExchange polls the token endpoint once, unlike
+// cfg.DeviceAccessToken which waits for the polling interval.
+func deviceTokenExchange(ctx context.Context, cfg *oauth2.Config, deviceCode string) (*oauth2.Token, error) {
+	resp, err := postOAuth2ClientForm(ctx, cfg, "/oauth2/tokens", url.Values{
+		"grant_type":  {string(codersdk.OAuth2ProviderGrantTypeDeviceCode)},
+		"device_code": {deviceCode},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResp struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&errorResp)
+		return nil, xerrors.Errorf("oauth2: %q %q", errorResp.Error, errorResp.ErrorDescription)
+	}
+
+	var token oauth2.Token
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
 type provisionedApps struct {
 	Default   codersdk.OAuth2ProviderApp
 	NoPort    codersdk.OAuth2ProviderApp
@@ -0,0 +1,268 @@
+package oauth2provider
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+	"github.com/coder/coder/v2/site"
+)
+
+const (
+	// deviceCodeLifetime is how long users have to approve a device.
+	deviceCodeLifetime = 15 * time.Minute
+	// deviceCodePollInterval is how often devices may poll the token endpoint.
+	deviceCodePollInterval = 5 * time.Second
+	// userCodeCharset omits vowels to avoid forming words, and characters that
+	// are easily confused, as recommended by RFC 8628 section 6.1.
+	userCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength  = 8
+)
+
+// generateUserCode returns a user code without separators.
+func generateUserCode() (string, error) {
+	return cryptorand.StringCharset(userCodeCharset, userCodeLength)
+}
+
+// formatUserCode splits a user code in two halves to make it easier to read,
+// for example "BCDF-GHJK".
+func formatUserCode(code string) string {
+	if len(code) != userCodeLength {
+		return code
+	}
+	return code[:userCodeLength/2] + "-" + code[userCodeLength/2:]
+}
+
+// normalizeUserCode reverts formatUserCode and forgives users typing the code
+// in lowercase or with spaces.
+func normalizeUserCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(code))
+}
+
+// DeviceAuthorization handles POST /oauth2/device, the RFC 8628 device
+// authorization endpoint. Devices that cannot open a browser use it to get a
+// device code to poll the token endpoint with, and a user code for the user to
+// approve on another device.
+func DeviceAuthorization(db database.Store, accessURL *url.URL) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		app := httpmw.OAuth2ProviderApp(r)
+
+		err := r.ParseForm()
+		if err != nil {
+			httpapi.WriteOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_request", "Failed to parse form")
+			return
+		}
+		p := httpapi.NewQueryParamParser()
+		// TODO: Ignoring scope for now, but should look into implementing.
+		_ = p.String(r.Form, "", "client_id")
+		_ = p.String(r.Form, "", "scope")
+		clientSecret := p.String(r.Form, "", "client_secret")
+		resource := p.String(r.Form, "", "resource")
+		if err := validateResourceParameter(resource); err != nil {
+			httpapi.WriteOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_target", "The resource parameter is invalid")
+			return
+		}
+		p.ErrorExcessParams(r.Form)
+		if len(p.Errors) > 0 {
+			httpapi.WriteOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_request", p.Errors[0].Error())
+			return
+		}
+
+		// The token endpoint always authenticates the client, but confidential
+		// clients may authenticate here as well.
+		if clientSecret != "" {
+			_, err = validateClientSecret(ctx, db, app, clientSecret)
+			if errors.Is(err, errBadSecret) {
+				httpapi.WriteOAuth2Error(ctx, rw, http.StatusUnauthorized, "invalid_client", "The client credentials are invalid")
+				return
+			}
+			if err != nil {
+				httpapi.WriteOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Failed to validate client credentials")
+				return
+			}
+		}
+
+		deviceCode, err := GenerateSecret()
+		if err != nil {
+			httpapi.WriteOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Failed to generate device code")
+			return
+		}
+		userCode, err := generateUserCode()
+		if err != nil {
+			httpapi.WriteOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Failed to generate user code")
+			return
+		}
+
+		now := dbtime.Now()
+		//nolint:gocritic // There is no user yet so we must use the system.
+		dbCode, err := db.InsertOAuth2ProviderAppDeviceCode(dbauthz.AsSystemRestricted(ctx), database.InsertOAuth2ProviderAppDeviceCodeParams{
+			ID:               uuid.New(),
+			CreatedAt:        now,
+			ExpiresAt:        now.Add(deviceCodeLifetime),
+			DeviceCodePrefix: []byte(deviceCode.Prefix),
+			DeviceCodeHash:   []byte(deviceCode.Hashed),
+			UserCode:         userCode,
+			AppID:            app.ID,
+			ResourceUri:      sql.NullString{String: resource, Valid: resource != ""},
+		})
+		if err != nil {
+			httpapi.WriteOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Failed to store device code")
+			return
+		}
+
+		verificationURI := accessURL.JoinPath("/oauth2/device/verify")
+		verificationURIComplete := *verificationURI
+		verificationURIComplete.RawQuery = url.Values{"user_code": {formatUserCode(userCode)}}.Encode()
+
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.OAuth2DeviceAuthorizationResponse{
+			DeviceCode:              deviceCode.Formatted,
+			UserCode:                formatUserCode(userCode),
+			VerificationURI:         verificationURI.String(),
+			VerificationURIComplete: verificationURIComplete.String(),
+			ExpiresIn:               int64(dbCode.ExpiresAt.Sub(now).Seconds()),
+			Interval:                int64(deviceCodePollInterval.Seconds()),
+		})
+	}
+}
+
+// ShowDeviceVerificationPage handles GET /oauth2/device/verify requests. It
+// asks the user for the user code displayed on their device, then shows the
+// authorization page of the app the device belongs to.
+func ShowDeviceVerificationPage(db database.Store, accessURL *url.URL) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ua := httpmw.UserAuthorization(r.Context())
+
+		userCode := r.URL.Query().Get("user_code")
+		if userCode == "" {
+			site.RenderOAuthAllowPage(rw, r, site.RenderOAuthAllowData{
+				PromptUserCode: true,
+			})
+			return
+		}
+
+		dbCode, app, ok := pendingDeviceCode(rw, r, db, accessURL, userCode)
+		if !ok {
+			return
+		}
+
+		site.RenderOAuthAllowPage(rw, r, site.RenderOAuthAllowData{
+			AppIcon:     app.Icon,
+			AppName:     app.Name,
+			CancelURI:   accessURL.String(),
+			RedirectURI: r.URL.String(),
+			Username:    ua.FriendlyName,
+			UserCode:    formatUserCode(dbCode.UserCode),
+		})
+	}
+}
+
+// ProcessDeviceVerification handles POST /oauth2/device/verify requests to
+// approve a device. The device receives its token the next time it polls the
+// token endpoint.
+func ProcessDeviceVerification(db database.Store, accessURL *url.URL) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		apiKey := httpmw.APIKey(r)
+
+		dbCode, app, ok := pendingDeviceCode(rw, r, db, accessURL, r.URL.Query().Get("user_code"))
+		if !ok {
+			return
+		}
+
+		_, err := db.UpdateOAuth2ProviderAppDeviceCodeUserID(ctx, database.UpdateOAuth2ProviderAppDeviceCodeUserIDParams{
+			ID:     dbCode.ID,
+			UserID: uuid.NullUUID{UUID: apiKey.UserID, Valid: true},
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			renderInvalidUserCode(rw, r, accessURL)
+			return
+		}
+		if err != nil {
+			site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+				Status:       http.StatusInternalServerError,
+				Title:        "Internal Server Error",
+				Description:  "Failed to approve the device.",
+				DashboardURL: accessURL.String(),
+			})
+			return
+		}
+
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusOK,
+			HideStatus:   true,
+			Title:        fmt.Sprintf("%s is authorized", app.Name),
+			Description:  "You can close this page and return to your device.",
+			DashboardURL: accessURL.String(),
+		})
+	}
+}
+
+// pendingDeviceCode returns the device code matching the user code, and the
+// app it belongs to. It renders an error page and returns false if the code
+// does not exist, has expired, or was already approved.
+func pendingDeviceCode(rw http.ResponseWriter, r *http.Request, db database.Store, accessURL *url.URL, userCode string) (database.OAuth2ProviderAppDeviceCode, database.OAuth2ProviderApp, bool) {
+	ctx := r.Context()
+
+	dbCode, app, err := getPendingDeviceCode(ctx, db, normalizeUserCode(userCode))
+	if errors.Is(err, sql.ErrNoRows) {
+		renderInvalidUserCode(rw, r, accessURL)
+		return database.OAuth2ProviderAppDeviceCode{}, database.OAuth2ProviderApp{}, false
+	}
+	if err != nil {
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusInternalServerError,
+			Title:        "Internal Server Error",
+			Description:  "Failed to look up the code.",
+			DashboardURL: accessURL.String(),
+		})
+		return database.OAuth2ProviderAppDeviceCode{}, database.OAuth2ProviderApp{}, false
+	}
+	return dbCode, app, true
+}
+
+func getPendingDeviceCode(ctx context.Context, db database.Store, userCode string) (database.OAuth2ProviderAppDeviceCode, database.OAuth2ProviderApp, error) {
+	//nolint:gocritic // Pending device codes are not owned by the user.
+	dbCode, err := db.GetOAuth2ProviderAppDeviceCodeByUserCode(dbauthz.AsSystemRestricted(ctx), userCode)
+	if err != nil {
+		return database.OAuth2ProviderAppDeviceCode{}, database.OAuth2ProviderApp{}, err
+	}
+	if dbCode.UserID.Valid || dbCode.ExpiresAt.Before(dbtime.Now()) {
+		return database.OAuth2ProviderAppDeviceCode{}, database.OAuth2ProviderApp{}, sql.ErrNoRows
+	}
+	//nolint:gocritic // OAuth2 apps are shown to users before they authorize them.
+	app, err := db.GetOAuth2ProviderAppByID(dbauthz.AsSystemRestricted(ctx), dbCode.AppID)
+	if err != nil {
+		return database.OAuth2ProviderAppDeviceCode{}, database.OAuth2ProviderApp{}, xerrors.Errorf("get oauth2 app: %w", err)
+	}
+	return dbCode, app, nil
+}
+
+func renderInvalidUserCode(rw http.ResponseWriter, r *http.Request, accessURL *url.URL) {
+	site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+		Status:       http.StatusBadRequest,
+		Title:        "Invalid Code",
+		Description:  "The code is invalid, has expired, or was already used. Start over on your device to get a new code.",
+		DashboardURL: accessURL.String(),
+	})
+}
@@ -0,0 +1,182 @@
+package oauth2provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/userpassword"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// appToken is an access or refresh token issued to an app.
+type appToken struct {
+	token  database.OAuth2ProviderAppToken
+	apiKey database.APIKey
+	// refresh is true if the token presented was the refresh token.
+	refresh bool
+}
+
+// extractClientTokenParams parses the parameters shared by the introspection
+// and revocation endpoints, and authenticates the client.
+func extractClientTokenParams(rw http.ResponseWriter, r *http.Request, db database.Store) (string, bool) {
+	ctx := r.Context()
+	app := httpmw.OAuth2ProviderApp(r)
+
+	err := r.ParseForm()
+	if err != nil {
+		httpapi.WriteOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_request", "Failed to parse form")
+		return "", false
+	}
+	p := httpapi.NewQueryParamParser()
+	p.RequiredNotEmpty("token", "client_id", "client_secret")
+	token := p.String(r.Form, "", "token")
+	// Hints are optional, and tokens can be told apart by their format.
+	_ = p.String(r.Form, "", "token_type_hint")
+	_ = p.String(r.Form, "", "client_id")
+	clientSecret := p.String(r.Form, "", "client_secret")
+	p.ErrorExcessParams(r.Form)
+	if len(p.Errors) > 0 {
+		httpapi.WriteOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_request", p.Errors[0].Error())
+		return "", false
+	}
+
+	_, err = validateClientSecret(ctx, db, app, clientSecret)
+	if errors.Is(err, errBadSecret) {
+		httpapi.WriteOAuth2Error(ctx, rw, http.StatusUnauthorized, "invalid_client", "The client credentials are invalid")
+		return "", false
+	}
+	if err != nil {
+		httpapi.WriteOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Failed to validate client credentials")
+		return "", false
+	}
+	return token, true
+}
+
+// lookupAppToken returns the unexpired access or refresh token issued to the
+// app, or errBadToken.
+func lookupAppToken(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, token string) (appToken, error) {
+	//nolint:gocritic // The client is authenticated, but there is no user.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	var result appToken
+	if secret, err := parseFormattedSecret(token); err == nil {
+		dbToken, err := db.GetOAuth2ProviderAppTokenByPrefix(ctx, []byte(secret.prefix))
+		if errors.Is(err, sql.ErrNoRows) {
+			return appToken{}, errBadToken
+		}
+		if err != nil {
+			return appToken{}, err
+		}
+		equal, err := userpassword.Compare(string(dbToken.RefreshHash), secret.secret)
+		if err != nil {
+			return appToken{}, xerrors.Errorf("unable to compare token: %w", err)
+		}
+		if !equal || dbToken.ExpiresAt.Before(dbtime.Now()) {
+			return appToken{}, errBadToken
+		}
+		apiKey, err := db.GetAPIKeyByID(ctx, dbToken.APIKeyID)
+		if err != nil {
+			return appToken{}, xerrors.Errorf("get api key: %w", err)
+		}
+		result = appToken{token: dbToken, apiKey: apiKey, refresh: true}
+	} else {
+		keyID, keySecret, err := httpmw.SplitAPIToken(token)
+		if err != nil {
+			return appToken{}, errBadToken
+		}
+		apiKey, err := db.GetAPIKeyByID(ctx, keyID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return appToken{}, errBadToken
+		}
+		if err != nil {
+			return appToken{}, err
+		}
+		hashedSecret := sha256.Sum256([]byte(keySecret))
+		if subtle.ConstantTimeCompare(apiKey.HashedSecret, hashedSecret[:]) != 1 {
+			return appToken{}, errBadToken
+		}
+		if apiKey.LoginType != database.LoginTypeOAuth2ProviderApp || apiKey.ExpiresAt.Before(dbtime.Now()) {
+			return appToken{}, errBadToken
+		}
+		dbToken, err := db.GetOAuth2ProviderAppTokenByAPIKeyID(ctx, apiKey.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return appToken{}, errBadToken
+		}
+		if err != nil {
+			return appToken{}, err
+		}
+		result = appToken{token: dbToken, apiKey: apiKey}
+	}
+
+	// Clients must not learn about tokens issued to other clients.
+	secret, err := db.GetOAuth2ProviderAppSecretByID(ctx, result.token.AppSecretID)
+	if err != nil {
+		return appToken{}, xerrors.Errorf("get oauth2 app secret: %w", err)
+	}
+	if secret.AppID != app.ID {
+		return appToken{}, errBadToken
+	}
+	return result, nil
+}
+
+// IntrospectToken handles POST /oauth2/introspect, the RFC 7662 token
+// introspection endpoint. Apps use it to check whether a token is active and
+// which user it belongs to.
+func IntrospectToken(db database.Store, accessURL *url.URL) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		app := httpmw.OAuth2ProviderApp(r)
+
+		token, ok := extractClientTokenParams(rw, r, db)
+		if !ok {
+			return
+		}
+
+		found, err := lookupAppToken(ctx, db, app, token)
+		if errors.Is(err, errBadToken) {
+			httpapi.Write(ctx, rw, http.StatusOK, codersdk.OAuth2TokenIntrospectionResponse{Active: false})
+			return
+		}
+		if err != nil {
+			httpapi.WriteOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Failed to look up token")
+			return
+		}
+
+		//nolint:gocritic // The client is authenticated, but there is no user.
+		user, err := db.GetUserByID(dbauthz.AsSystemRestricted(ctx), found.token.UserID)
+		if err != nil {
+			httpapi.WriteOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Failed to look up token user")
+			return
+		}
+
+		resp := codersdk.OAuth2TokenIntrospectionResponse{
+			Active:   true,
+			ClientID: app.ID.String(),
+			Username: user.Username,
+			Sub:      user.ID.String(),
+			Aud:      found.token.Audience.String,
+			Iss:      accessURL.String(),
+		}
+		if found.refresh {
+			resp.Exp = found.token.ExpiresAt.Unix()
+			resp.Iat = found.token.CreatedAt.Unix()
+		} else {
+			resp.TokenType = "Bearer"
+			resp.Exp = found.apiKey.ExpiresAt.Unix()
+			resp.Iat = found.apiKey.CreatedAt.Unix()
+		}
+		httpapi.Write(ctx, rw, http.StatusOK, resp)
+	}
+}
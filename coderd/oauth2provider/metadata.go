@@ -18,11 +18,14 @@ func GetAuthorizationServerMetadata(accessURL *url.URL) http.HandlerFunc {
 			TokenEndpoint:                 accessURL.JoinPath("/oauth2/tokens").String(),
 			RegistrationEndpoint:          accessURL.JoinPath("/oauth2/register").String(), // RFC 7591
 			ResponseTypesSupported:        []string{"code"},
-			GrantTypesSupported:           []string{"authorization_code", "refresh_token"},
+			GrantTypesSupported:           []string{"authorization_code", "refresh_token", string(codersdk.OAuth2ProviderGrantTypeDeviceCode)},
 			CodeChallengeMethodsSupported: []string{"S256"},
 			// TODO: Implement scope system
 			ScopesSupported:                   []string{},
 			TokenEndpointAuthMethodsSupported: []string{"client_secret_post"},
+			DeviceAuthorizationEndpoint:       accessURL.JoinPath("/oauth2/device").String(),     // RFC 8628
+			IntrospectionEndpoint:             accessURL.JoinPath("/oauth2/introspect").String(), // RFC 7662
+			RevocationEndpoint:                accessURL.JoinPath("/oauth2/revoke").String(),     // RFC 7009
 		}
 		httpapi.Write(ctx, rw, http.StatusOK, metadata)
 	}
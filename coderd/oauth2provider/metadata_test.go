@@ -45,7 +45,11 @@ func TestOAuth2AuthorizationServerMetadata(t *testing.T) {
 	require.Contains(t, metadata.ResponseTypesSupported, "code")
 	require.Contains(t, metadata.GrantTypesSupported, "authorization_code")
 	require.Contains(t, metadata.GrantTypesSupported, "refresh_token")
+	require.Contains(t, metadata.GrantTypesSupported, "urn:ietf:params:oauth:grant-type:device_code")
 	require.Contains(t, metadata.CodeChallengeMethodsSupported, "S256")
+	require.Equal(t, serverURL.JoinPath("/oauth2/device").String(), metadata.DeviceAuthorizationEndpoint)
+	require.Equal(t, serverURL.JoinPath("/oauth2/introspect").String(), metadata.IntrospectionEndpoint)
+	require.Equal(t, serverURL.JoinPath("/oauth2/revoke").String(), metadata.RevocationEndpoint)
 }
 
 func TestOAuth2ProtectedResourceMetadata(t *testing.T) {
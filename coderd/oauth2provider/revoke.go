@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 )
@@ -42,3 +43,38 @@ func RevokeApp(db database.Store) http.HandlerFunc {
 		rw.WriteHeader(http.StatusNoContent)
 	}
 }
+
+// RevokeToken handles POST /oauth2/revoke, the RFC 7009 token revocation
+// endpoint. Revoking either the access or the refresh token revokes both.
+func RevokeToken(db database.Store) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		app := httpmw.OAuth2ProviderApp(r)
+
+		token, ok := extractClientTokenParams(rw, r, db)
+		if !ok {
+			return
+		}
+
+		found, err := lookupAppToken(ctx, db, app, token)
+		if errors.Is(err, errBadToken) {
+			// Invalid tokens are not an error, the client's goal of the token no
+			// longer being usable is already met.
+			rw.WriteHeader(http.StatusOK)
+			return
+		}
+		if err != nil {
+			httpapi.WriteOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Failed to look up token")
+			return
+		}
+
+		// Deleting the API key cascades to the refresh token.
+		//nolint:gocritic // The client is authenticated, but there is no user.
+		err = db.DeleteAPIKeyByID(dbauthz.AsSystemRestricted(ctx), found.apiKey.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			httpapi.WriteOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Failed to revoke token")
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}
+}
@@ -36,6 +36,14 @@ var (
 	errInvalidPKCE = xerrors.New("invalid code_verifier")
 	// errInvalidResource means the resource parameter validation failed.
 	errInvalidResource = xerrors.New("invalid resource parameter")
+	// errAuthorizationPending means the user has not approved the device code
+	// yet.
+	errAuthorizationPending = xerrors.New("authorization pending")
+	// errSlowDown means the device polls the token endpoint too often.
+	errSlowDown = xerrors.New("slow down")
+	// errExpiredDeviceCode means the device code expired before the user
+	// approved it.
+	errExpiredDeviceCode = xerrors.New("expired device code")
 )
 
 type tokenParams struct {
@@ -47,6 +55,7 @@ type tokenParams struct {
 	refreshToken string
 	codeVerifier string // PKCE verifier
 	resource     string // RFC 8707 resource for token binding
+	deviceCode   string // RFC 8628 device code
 }
 
 func extractTokenParams(r *http.Request, callbackURL *url.URL) (tokenParams, []codersdk.ValidationError, error) {
@@ -64,6 +73,8 @@ func extractTokenParams(r *http.Request, callbackURL *url.URL) (tokenParams, []c
 		p.RequiredNotEmpty("refresh_token")
 	case codersdk.OAuth2ProviderGrantTypeAuthorizationCode:
 		p.RequiredNotEmpty("client_secret", "client_id", "code")
+	case codersdk.OAuth2ProviderGrantTypeDeviceCode:
+		p.RequiredNotEmpty("client_secret", "client_id", "device_code")
 	}
 
 	params := tokenParams{
@@ -75,6 +86,7 @@ func extractTokenParams(r *http.Request, callbackURL *url.URL) (tokenParams, []c
 		refreshToken: p.String(vals, "", "refresh_token"),
 		codeVerifier: p.String(vals, "", "code_verifier"),
 		resource:     p.String(vals, "", "resource"),
+		deviceCode:   p.String(vals, "", "device_code"),
 	}
 	// Validate resource parameter syntax (RFC 8707): must be absolute URI without fragment
 	if err := validateResourceParameter(params.resource); err != nil {
@@ -120,7 +132,7 @@ func Tokens(db database.Store, lifetimes codersdk.SessionLifetime) http.HandlerF
 			}
 
 			// Check for missing required parameters for authorization_code grant
-			for _, field := range []string{"code", "client_id", "client_secret"} {
+			for _, field := range []string{"code", "device_code", "client_id", "client_secret"} {
 				if slices.ContainsFunc(validationErrs, func(validationError codersdk.ValidationError) bool {
 					return validationError.Field == field
 				}) {
@@ -134,13 +146,14 @@ func Tokens(db database.Store, lifetimes codersdk.SessionLifetime) http.HandlerF
 		}
 
 		var token oauth2.Token
-		//nolint:gocritic,revive // More cases will be added later.
 		switch params.grantType {
-		// TODO: Client creds, device code.
+		// TODO: Client creds.
 		case codersdk.OAuth2ProviderGrantTypeRefreshToken:
 			token, err = refreshTokenGrant(ctx, db, app, lifetimes, params)
 		case codersdk.OAuth2ProviderGrantTypeAuthorizationCode:
 			token, err = authorizationCodeGrant(ctx, db, app, lifetimes, params)
+		case codersdk.OAuth2ProviderGrantTypeDeviceCode:
+			token, err = deviceCodeGrant(ctx, db, app, lifetimes, params)
 		default:
 			// This should handle truly invalid grant types
 			httpapi.WriteOAuth2Error(ctx, rw, http.StatusBadRequest, "unsupported_grant_type", fmt.Sprintf("The grant type %q is not supported", params.grantType))
@@ -167,6 +180,19 @@ func Tokens(db database.Store, lifetimes codersdk.SessionLifetime) http.HandlerF
 			httpapi.WriteOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_grant", "The refresh token is invalid or expired")
 			return
 		}
+		// RFC 8628 section 3.5 device access token error responses.
+		if errors.Is(err, errAuthorizationPending) {
+			httpapi.WriteOAuth2Error(ctx, rw, http.StatusBadRequest, "authorization_pending", "The user has not approved the device yet")
+			return
+		}
+		if errors.Is(err, errSlowDown) {
+			httpapi.WriteOAuth2Error(ctx, rw, http.StatusBadRequest, "slow_down", fmt.Sprintf("Poll the token endpoint at most every %d seconds", int(deviceCodePollInterval.Seconds())))
+			return
+		}
+		if errors.Is(err, errExpiredDeviceCode) {
+			httpapi.WriteOAuth2Error(ctx, rw, http.StatusBadRequest, "expired_token", "The device code has expired")
+			return
+		}
 		if err != nil {
 			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 				Message: "Failed to exchange token",
@@ -181,26 +207,39 @@ func Tokens(db database.Store, lifetimes codersdk.SessionLifetime) http.HandlerF
 	}
 }
 
-func authorizationCodeGrant(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, lifetimes codersdk.SessionLifetime, params tokenParams) (oauth2.Token, error) {
-	// Validate the client secret.
-	secret, err := parseFormattedSecret(params.clientSecret)
+// validateClientSecret returns the secret of the app matching the client
+// secret, or errBadSecret.
+func validateClientSecret(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, clientSecret string) (database.OAuth2ProviderAppSecret, error) {
+	secret, err := parseFormattedSecret(clientSecret)
 	if err != nil {
-		return oauth2.Token{}, errBadSecret
+		return database.OAuth2ProviderAppSecret{}, errBadSecret
 	}
 	//nolint:gocritic // Users cannot read secrets so we must use the system.
 	dbSecret, err := db.GetOAuth2ProviderAppSecretByPrefix(dbauthz.AsSystemRestricted(ctx), []byte(secret.prefix))
 	if errors.Is(err, sql.ErrNoRows) {
-		return oauth2.Token{}, errBadSecret
+		return database.OAuth2ProviderAppSecret{}, errBadSecret
 	}
 	if err != nil {
-		return oauth2.Token{}, err
+		return database.OAuth2ProviderAppSecret{}, err
+	}
+	if dbSecret.AppID != app.ID {
+		return database.OAuth2ProviderAppSecret{}, errBadSecret
 	}
 	equal, err := userpassword.Compare(string(dbSecret.HashedSecret), secret.secret)
 	if err != nil {
-		return oauth2.Token{}, xerrors.Errorf("unable to compare secret: %w", err)
+		return database.OAuth2ProviderAppSecret{}, xerrors.Errorf("unable to compare secret: %w", err)
 	}
 	if !equal {
-		return oauth2.Token{}, errBadSecret
+		return database.OAuth2ProviderAppSecret{}, errBadSecret
+	}
+	return dbSecret, nil
+}
+
+func authorizationCodeGrant(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, lifetimes codersdk.SessionLifetime, params tokenParams) (oauth2.Token, error) {
+	// Validate the client secret.
+	dbSecret, err := validateClientSecret(ctx, db, app, params.clientSecret)
+	if err != nil {
+		return oauth2.Token{}, err
 	}
 
 	// Validate the authorization code.
@@ -216,7 +255,7 @@ func authorizationCodeGrant(ctx context.Context, db database.Store, app database
 	if err != nil {
 		return oauth2.Token{}, err
 	}
-	equal, err = userpassword.Compare(string(dbCode.HashedSecret), code.secret)
+	equal, err := userpassword.Compare(string(dbCode.HashedSecret), code.secret)
 	if err != nil {
 		return oauth2.Token{}, xerrors.Errorf("unable to compare code: %w", err)
 	}
@@ -253,17 +292,107 @@ func authorizationCodeGrant(ctx context.Context, db database.Store, app database
 		return oauth2.Token{}, errInvalidResource
 	}
 
+	return issueToken(ctx, db, app, lifetimes, dbCode.UserID, dbSecret.ID, dbCode.ResourceUri, func(ctx context.Context, tx database.Store) error {
+		err := tx.DeleteOAuth2ProviderAppCodeByID(ctx, dbCode.ID)
+		if err != nil {
+			return xerrors.Errorf("delete oauth2 app code: %w", err)
+		}
+		return nil
+	})
+}
+
+func deviceCodeGrant(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, lifetimes codersdk.SessionLifetime, params tokenParams) (oauth2.Token, error) {
+	// Validate the client secret.
+	dbSecret, err := validateClientSecret(ctx, db, app, params.clientSecret)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+
+	// Validate the device code.
+	code, err := parseFormattedSecret(params.deviceCode)
+	if err != nil {
+		return oauth2.Token{}, errBadCode
+	}
+	//nolint:gocritic // There is no user yet so we must use the system.
+	dbCode, err := db.GetOAuth2ProviderAppDeviceCodeByPrefix(dbauthz.AsSystemRestricted(ctx), []byte(code.prefix))
+	if errors.Is(err, sql.ErrNoRows) {
+		return oauth2.Token{}, errBadCode
+	}
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+	if dbCode.AppID != app.ID {
+		return oauth2.Token{}, errBadCode
+	}
+	equal, err := userpassword.Compare(string(dbCode.DeviceCodeHash), code.secret)
+	if err != nil {
+		return oauth2.Token{}, xerrors.Errorf("unable to compare device code: %w", err)
+	}
+	if !equal {
+		return oauth2.Token{}, errBadCode
+	}
+
+	now := dbtime.Now()
+	if dbCode.ExpiresAt.Before(now) {
+		//nolint:gocritic // There is no user yet so we must use the system.
+		err = db.DeleteOAuth2ProviderAppDeviceCodeByID(dbauthz.AsSystemRestricted(ctx), dbCode.ID)
+		if err != nil {
+			return oauth2.Token{}, xerrors.Errorf("delete expired device code: %w", err)
+		}
+		return oauth2.Token{}, errExpiredDeviceCode
+	}
+
+	if !dbCode.UserID.Valid {
+		// Allow some leeway since devices measure the interval from when they
+		// receive the previous response.
+		if dbCode.LastPolledAt.Valid && now.Sub(dbCode.LastPolledAt.Time) < deviceCodePollInterval-time.Second {
+			return oauth2.Token{}, errSlowDown
+		}
+		//nolint:gocritic // There is no user yet so we must use the system.
+		err = db.UpdateOAuth2ProviderAppDeviceCodeLastPolledAt(dbauthz.AsSystemRestricted(ctx), database.UpdateOAuth2ProviderAppDeviceCodeLastPolledAtParams{
+			ID:           dbCode.ID,
+			LastPolledAt: sql.NullTime{Time: now, Valid: true},
+		})
+		if err != nil {
+			return oauth2.Token{}, xerrors.Errorf("update device code last polled at: %w", err)
+		}
+		return oauth2.Token{}, errAuthorizationPending
+	}
+
+	// Verify resource parameter consistency (RFC 8707)
+	if dbCode.ResourceUri.Valid && dbCode.ResourceUri.String != "" {
+		if params.resource != dbCode.ResourceUri.String {
+			return oauth2.Token{}, errInvalidResource
+		}
+	} else if params.resource != "" {
+		return oauth2.Token{}, errInvalidResource
+	}
+
+	return issueToken(ctx, db, app, lifetimes, dbCode.UserID.UUID, dbSecret.ID, dbCode.ResourceUri, func(ctx context.Context, tx database.Store) error {
+		//nolint:gocritic // Pending device codes are not owned by the user.
+		err := tx.DeleteOAuth2ProviderAppDeviceCodeByID(dbauthz.AsSystemRestricted(ctx), dbCode.ID)
+		if err != nil {
+			return xerrors.Errorf("delete oauth2 app device code: %w", err)
+		}
+		return nil
+	})
+}
+
+// issueToken generates an access and refresh token for the user, replacing
+// the previous tokens of the user for the app. consume is called in the same
+// transaction to invalidate the grant the tokens are issued for.
+func issueToken(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, lifetimes codersdk.SessionLifetime, userID uuid.UUID, appSecretID uuid.UUID, audience sql.NullString, consume func(ctx context.Context, tx database.Store) error) (oauth2.Token, error) {
 	// Generate a refresh token.
 	refreshToken, err := GenerateSecret()
 	if err != nil {
 		return oauth2.Token{}, err
 	}
 
-	// Generate the API key we will swap for the code.
+	// Generate the API key we will swap for the grant.
 	// TODO: We are ignoring scopes for now.
-	tokenName := fmt.Sprintf("%s_%s_oauth_session_token", dbCode.UserID, app.ID)
+	tokenName := fmt.Sprintf("%s_%s_oauth_session_token", userID, app.ID)
 	key, sessionToken, err := apikey.Generate(apikey.CreateParams{
-		UserID:          dbCode.UserID,
+		UserID:          userID,
 		LoginType:       database.LoginTypeOAuth2ProviderApp,
 		DefaultLifetime: lifetimes.DefaultDuration.Value(),
 		// For now, we allow only one token per app and user at a time.
@@ -274,7 +403,7 @@ func authorizationCodeGrant(ctx context.Context, db database.Store, app database
 	}
 
 	// Grab the user roles so we can perform the exchange as the user.
-	actor, _, err := httpmw.UserRBACSubject(ctx, db, dbCode.UserID, rbac.ScopeAll)
+	actor, _, err := httpmw.UserRBACSubject(ctx, db, userID, rbac.ScopeAll)
 	if err != nil {
 		return oauth2.Token{}, xerrors.Errorf("fetch user actor: %w", err)
 	}
@@ -282,14 +411,14 @@ func authorizationCodeGrant(ctx context.Context, db database.Store, app database
 	// Do the actual token exchange in the database.
 	err = db.InTx(func(tx database.Store) error {
 		ctx := dbauthz.As(ctx, actor)
-		err = tx.DeleteOAuth2ProviderAppCodeByID(ctx, dbCode.ID)
+		err = consume(ctx, tx)
 		if err != nil {
-			return xerrors.Errorf("delete oauth2 app code: %w", err)
+			return err
 		}
 
 		// Delete the previous key, if any.
 		prevKey, err := tx.GetAPIKeyByName(ctx, database.GetAPIKeyByNameParams{
-			UserID:    dbCode.UserID,
+			UserID:    userID,
 			TokenName: tokenName,
 		})
 		if err == nil {
@@ -310,10 +439,10 @@ func authorizationCodeGrant(ctx context.Context, db database.Store, app database
 			ExpiresAt:   key.ExpiresAt,
 			HashPrefix:  []byte(refreshToken.Prefix),
 			RefreshHash: []byte(refreshToken.Hashed),
-			AppSecretID: dbSecret.ID,
+			AppSecretID: appSecretID,
 			APIKeyID:    newKey.ID,
-			UserID:      dbCode.UserID,
-			Audience:    dbCode.ResourceUri,
+			UserID:      userID,
+			Audience:    audience,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert oauth2 refresh token: %w", err)
@@ -0,0 +1,225 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// azureStorageVersion is the version of the Blob service REST API that
+// requests are made with.
+const azureStorageVersion = "2021-08-06"
+
+// azureBlobStore keeps objects in an Azure Blob Storage container. Requests
+// are authorized with the account key in AZURE_STORAGE_KEY, or with the shared
+// access signature in AZURE_STORAGE_SAS_TOKEN.
+type azureBlobStore struct {
+	client  *http.Client
+	account string
+	// key is the decoded account key. It is nil if requests are authorized
+	// with a shared access signature instead.
+	key []byte
+	sas url.Values
+	// endpoint is the base URL of the container. Object keys are appended to
+	// its path.
+	endpoint *url.URL
+	prefix   string
+}
+
+func newAzureBlobStoreFromURL(u *url.URL) (Store, error) {
+	container, prefix, _ := strings.Cut(strings.Trim(u.Path, "/"), "/")
+	if u.Host == "" || container == "" {
+		return nil, xerrors.New("azblob object storage url must contain an account and a container")
+	}
+	return newAzureBlobStore(http.DefaultClient, u.Host, container, prefix, u.Query().Get("endpoint"), os.Getenv("AZURE_STORAGE_KEY"), os.Getenv("AZURE_STORAGE_SAS_TOKEN"))
+}
+
+func newAzureBlobStore(client *http.Client, account, container, prefix, endpoint, key, sas string) (*azureBlobStore, error) {
+	store := &azureBlobStore{
+		client:  client,
+		account: account,
+		prefix:  prefix,
+	}
+	switch {
+	case key != "":
+		var err error
+		store.key, err = base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, xerrors.Errorf("decode azure storage key: %w", err)
+		}
+	case sas != "":
+		var err error
+		store.sas, err = url.ParseQuery(strings.TrimPrefix(sas, "?"))
+		if err != nil {
+			return nil, xerrors.Errorf("parse azure storage sas token: %w", err)
+		}
+	default:
+		return nil, xerrors.New("AZURE_STORAGE_KEY or AZURE_STORAGE_SAS_TOKEN must be set to use azblob object storage")
+	}
+
+	if endpoint == "" {
+		store.endpoint = &url.URL{
+			Scheme: "https",
+			Host:   fmt.Sprintf("%s.blob.core.windows.net", account),
+			Path:   "/" + container + "/",
+		}
+	} else {
+		// Custom endpoints, e.g. Azurite, use path-style addressing.
+		var err error
+		store.endpoint, err = url.Parse(endpoint)
+		if err != nil {
+			return nil, xerrors.Errorf("parse azure blob endpoint: %w", err)
+		}
+		store.endpoint.Path = path.Join("/", store.endpoint.Path, account, container) + "/"
+	}
+	return store, nil
+}
+
+func (s *azureBlobStore) do(ctx context.Context, method, key string, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	objectURL := *s.endpoint
+	objectURL.Path += path.Join(s.prefix, key)
+	if s.sas != nil {
+		objectURL.RawQuery = s.sas.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), body)
+	if err != nil {
+		return nil, xerrors.Errorf("create request: %w", err)
+	}
+	req.ContentLength = size
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("X-Ms-Date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("X-Ms-Version", azureStorageVersion)
+	if s.key != nil {
+		req.Header.Set("Authorization", fmt.Sprintf("SharedKey %s:%s", s.account, s.sign(req)))
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("%s blob: %w", method, err)
+	}
+	return res, nil
+}
+
+// sign returns the Shared Key signature of a request.
+// See https://learn.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key
+func (s *azureBlobStore) sign(req *http.Request) string {
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+	var msHeaders []string
+	for name := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-ms-") {
+			msHeaders = append(msHeaders, name)
+		}
+	}
+	sort.Strings(msHeaders)
+
+	var b strings.Builder
+	for _, value := range []string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, which is set with x-ms-date instead.
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+	} {
+		_, _ = b.WriteString(value + "\n")
+	}
+	for _, name := range msHeaders {
+		_, _ = b.WriteString(name + ":" + strings.TrimSpace(req.Header.Get(name)) + "\n")
+	}
+	_, _ = b.WriteString("/" + s.account + req.URL.EscapedPath())
+
+	mac := hmac.New(sha256.New, s.key)
+	_, _ = mac.Write([]byte(b.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *azureBlobStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	// Azure requires the content length up front, so spool the object to
+	// disk first.
+	f, err := os.CreateTemp("", "coder-object-*")
+	if err != nil {
+		return 0, xerrors.Errorf("create temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	size, err := io.Copy(f, r)
+	if err != nil {
+		return 0, xerrors.Errorf("spool object: %w", err)
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		return 0, xerrors.Errorf("seek object: %w", err)
+	}
+
+	res, err := s.do(ctx, http.MethodPut, key, f, size, http.Header{
+		"X-Ms-Blob-Type": []string{"BlockBlob"},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return 0, azureBlobError(res)
+	}
+	return size, nil
+}
+
+func (s *azureBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	res, err := s.do(ctx, http.MethodGet, key, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch res.StatusCode {
+	case http.StatusOK:
+		return res.Body, nil
+	case http.StatusNotFound:
+		_ = res.Body.Close()
+		return nil, ErrNotFound
+	default:
+		defer res.Body.Close()
+		return nil, azureBlobError(res)
+	}
+}
+
+func (s *azureBlobStore) Delete(ctx context.Context, key string) error {
+	res, err := s.do(ctx, http.MethodDelete, key, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusAccepted && res.StatusCode != http.StatusNotFound {
+		return azureBlobError(res)
+	}
+	return nil
+}
+
+func azureBlobError(res *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 4<<10))
+	return xerrors.Errorf("unexpected azure blob response %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,99 @@
+package objectstore
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestAzureBlobStore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		objects = map[string][]byte{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		authorized := strings.HasPrefix(r.Header.Get("Authorization"), "SharedKey account:") ||
+			r.URL.Query().Get("sig") == "signature"
+		if !authorized || r.Header.Get("X-Ms-Date") == "" || r.Header.Get("X-Ms-Version") == "" {
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			if r.Header.Get("X-Ms-Blob-Type") != "BlockBlob" {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = data
+			rw.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				rw.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = rw.Write(data)
+		case http.MethodDelete:
+			if _, ok := objects[r.URL.Path]; !ok {
+				rw.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(objects, r.URL.Path)
+			rw.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	key := base64.StdEncoding.EncodeToString([]byte("key"))
+	for name, store := range map[string]func() (*azureBlobStore, error){
+		"SharedKey": func() (*azureBlobStore, error) {
+			return newAzureBlobStore(srv.Client(), "account", "container", "coder/files", srv.URL, key, "")
+		},
+		"SAS": func() (*azureBlobStore, error) {
+			return newAzureBlobStore(srv.Client(), "account", "container", "coder/files", srv.URL, "", "?sv=2021-08-06&sig=signature")
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			store, err := store()
+			require.NoError(t, err)
+
+			ctx := testutil.Context(t, testutil.WaitShort)
+			size, err := store.Put(ctx, name, strings.NewReader("hello"))
+			require.NoError(t, err)
+			require.EqualValues(t, 5, size)
+			mu.Lock()
+			require.Contains(t, objects, "/account/container/coder/files/"+name)
+			mu.Unlock()
+
+			rc, err := store.Get(ctx, name)
+			require.NoError(t, err)
+			data, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			require.NoError(t, rc.Close())
+			require.Equal(t, "hello", string(data))
+
+			require.NoError(t, store.Delete(ctx, name))
+			require.NoError(t, store.Delete(ctx, name))
+			_, err = store.Get(ctx, name)
+			require.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+
+	_, err := newAzureBlobStore(srv.Client(), "account", "container", "", srv.URL, "", "")
+	require.Error(t, err)
+}
@@ -0,0 +1,171 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"golang.org/x/xerrors"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty request body.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// s3Store keeps objects in an S3 compatible bucket. Requests are signed with
+// the credentials from the default AWS credential chain.
+type s3Store struct {
+	client      *http.Client
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	region      string
+	// endpoint is the base URL of the bucket. Object keys are appended to
+	// its path.
+	endpoint *url.URL
+	prefix   string
+}
+
+func newS3StoreFromURL(ctx context.Context, u *url.URL) (Store, error) {
+	if u.Host == "" {
+		return nil, xerrors.New("s3 object storage url must contain a bucket")
+	}
+	query := u.Query()
+	region := query.Get("region")
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, xerrors.Errorf("load aws config: %w", err)
+	}
+	if cfg.Region == "" {
+		return nil, xerrors.New("s3 object storage url must set the region query parameter")
+	}
+	return newS3Store(http.DefaultClient, cfg.Credentials, cfg.Region, u.Host, strings.Trim(u.Path, "/"), query.Get("endpoint"))
+}
+
+func newS3Store(client *http.Client, credentials aws.CredentialsProvider, region, bucket, prefix, endpoint string) (*s3Store, error) {
+	var base *url.URL
+	if endpoint == "" {
+		base = &url.URL{
+			Scheme: "https",
+			Host:   fmt.Sprintf("%s.s3.%s.amazonaws.com", bucket, region),
+			Path:   "/",
+		}
+	} else {
+		// Custom endpoints, e.g. MinIO, use path-style addressing.
+		var err error
+		base, err = url.Parse(endpoint)
+		if err != nil {
+			return nil, xerrors.Errorf("parse s3 endpoint: %w", err)
+		}
+		base.Path = path.Join("/", base.Path, bucket) + "/"
+	}
+	return &s3Store{
+		client:      client,
+		credentials: credentials,
+		signer:      v4.NewSigner(),
+		region:      region,
+		endpoint:    base,
+		prefix:      prefix,
+	}, nil
+}
+
+func (s *s3Store) do(ctx context.Context, method, key string, body io.Reader, size int64, payloadHash string) (*http.Response, error) {
+	objectURL := *s.endpoint
+	objectURL.Path += path.Join(s.prefix, key)
+
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), body)
+	if err != nil {
+		return nil, xerrors.Errorf("create request: %w", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	creds, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("retrieve aws credentials: %w", err)
+	}
+	err = s.signer.SignHTTP(ctx, creds, req, payloadHash, "s3", s.region, time.Now())
+	if err != nil {
+		return nil, xerrors.Errorf("sign request: %w", err)
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("%s object: %w", method, err)
+	}
+	return res, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	// S3 requires the content length and signed payloads require the hash up
+	// front, so spool the object to disk first.
+	f, err := os.CreateTemp("", "coder-object-*")
+	if err != nil {
+		return 0, xerrors.Errorf("create temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(f, hash), r)
+	if err != nil {
+		return 0, xerrors.Errorf("spool object: %w", err)
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		return 0, xerrors.Errorf("seek object: %w", err)
+	}
+
+	res, err := s.do(ctx, http.MethodPut, key, f, size, hex.EncodeToString(hash.Sum(nil)))
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return 0, s3Error(res)
+	}
+	return size, nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	res, err := s.do(ctx, http.MethodGet, key, nil, 0, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	switch res.StatusCode {
+	case http.StatusOK:
+		return res.Body, nil
+	case http.StatusNotFound:
+		_ = res.Body.Close()
+		return nil, ErrNotFound
+	default:
+		defer res.Body.Close()
+		return nil, s3Error(res)
+	}
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	res, err := s.do(ctx, http.MethodDelete, key, nil, 0, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		return s3Error(res)
+	}
+	return nil
+}
+
+func s3Error(res *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 4<<10))
+	return xerrors.Errorf("unexpected s3 response %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+}
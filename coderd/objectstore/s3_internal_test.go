@@ -0,0 +1,79 @@
+package objectstore
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestS3Store(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		objects = map[string][]byte{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") ||
+			r.Header.Get("X-Amz-Content-Sha256") == "" {
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = data
+		case http.MethodGet:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				rw.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = rw.Write(data)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			rw.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	creds := credentials.NewStaticCredentialsProvider("access", "secret", "")
+	store, err := newS3Store(srv.Client(), creds, "us-east-1", "bucket", "coder/archives", srv.URL)
+	require.NoError(t, err)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	size, err := store.Put(ctx, "archive.tar.gz", strings.NewReader("hello"))
+	require.NoError(t, err)
+	require.EqualValues(t, 5, size)
+	mu.Lock()
+	require.Contains(t, objects, "/bucket/coder/archives/archive.tar.gz")
+	mu.Unlock()
+
+	rc, err := store.Get(ctx, "archive.tar.gz")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	require.Equal(t, "hello", string(data))
+
+	require.NoError(t, store.Delete(ctx, "archive.tar.gz"))
+	_, err = store.Get(ctx, "archive.tar.gz")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	other := credentials.NewStaticCredentialsProvider("other", "secret", "")
+	unauthorized, err := newS3Store(srv.Client(), other, "us-east-1", "bucket", "", srv.URL)
+	require.NoError(t, err)
+	_, err = unauthorized.Put(ctx, "archive.tar.gz", strings.NewReader("hello"))
+	require.ErrorContains(t, err, "403")
+}
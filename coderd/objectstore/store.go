@@ -0,0 +1,147 @@
+// Package objectstore stores objects, such as workspace archives and the
+// contents of uploaded files, outside of the database.
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// ErrNotFound is returned by a Store when an object does not exist.
+var ErrNotFound = xerrors.New("object not found")
+
+// Store persists objects. Keys are generated by the caller and never contain
+// path separators.
+type Store interface {
+	// Put stores the object read from r under key and returns its size in
+	// bytes.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Get returns a reader for the object stored under key.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key. Deleting an object that
+	// does not exist is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStore returns the Store for the given URL. Supported schemes are:
+//
+//   - file:///path/to/dir
+//   - s3://bucket/prefix, where the S3 region and a custom endpoint can be set
+//     with the region and endpoint query parameters.
+//   - gs://bucket/prefix, which accesses Google Cloud Storage through its S3
+//     compatible API with HMAC keys.
+//   - azblob://account/container/prefix, where a custom endpoint can be set
+//     with the endpoint query parameter.
+func NewStore(ctx context.Context, rawURL string) (Store, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, xerrors.Errorf("parse object storage url: %w", err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Path == "" {
+			return nil, xerrors.New("file object storage url must contain a path")
+		}
+		return NewDirStore(u.Path)
+	case "s3":
+		return newS3StoreFromURL(ctx, u)
+	case "gs":
+		query := u.Query()
+		if query.Get("endpoint") == "" {
+			query.Set("endpoint", "https://storage.googleapis.com")
+		}
+		if query.Get("region") == "" {
+			query.Set("region", "auto")
+		}
+		u.RawQuery = query.Encode()
+		return newS3StoreFromURL(ctx, u)
+	case "azblob":
+		return newAzureBlobStoreFromURL(u)
+	default:
+		return nil, xerrors.Errorf("unsupported object storage url scheme %q, must be \"file\", \"s3\", \"gs\" or \"azblob\"", u.Scheme)
+	}
+}
+
+type dirStore struct {
+	dir string
+}
+
+// NewDirStore returns a Store that keeps objects as files in dir, which is
+// created if it does not exist.
+func NewDirStore(dir string) (Store, error) {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, xerrors.Errorf("create object directory: %w", err)
+	}
+	return &dirStore{dir: dir}, nil
+}
+
+func (s *dirStore) path(key string) (string, error) {
+	if key == "" || strings.ContainsAny(key, `/\`) || key == "." || key == ".." {
+		return "", xerrors.Errorf("invalid object key %q", key)
+	}
+	return filepath.Join(s.dir, key), nil
+}
+
+func (s *dirStore) Put(_ context.Context, key string, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	// Write to a temporary file first so a partial object is never visible
+	// under the final key.
+	f, err := os.CreateTemp(s.dir, ".tmp-"+key+"-*")
+	if err != nil {
+		return 0, xerrors.Errorf("create temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	n, err := io.Copy(f, r)
+	closeErr := f.Close()
+	if err != nil {
+		return 0, xerrors.Errorf("write object: %w", err)
+	}
+	if closeErr != nil {
+		return 0, xerrors.Errorf("close object: %w", closeErr)
+	}
+	err = os.Rename(f.Name(), path)
+	if err != nil {
+		return 0, xerrors.Errorf("rename object: %w", err)
+	}
+	return n, nil
+}
+
+func (s *dirStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("open object: %w", err)
+	}
+	return f, nil
+}
+
+func (s *dirStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return xerrors.Errorf("remove object: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package objectstore_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/objectstore"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestDirStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	store, err := objectstore.NewStore(ctx, "file://"+t.TempDir()+"/archives")
+	require.NoError(t, err)
+
+	size, err := store.Put(ctx, "archive.tar.gz", strings.NewReader("hello"))
+	require.NoError(t, err)
+	require.EqualValues(t, 5, size)
+
+	rc, err := store.Get(ctx, "archive.tar.gz")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	require.Equal(t, "hello", string(data))
+
+	require.NoError(t, store.Delete(ctx, "archive.tar.gz"))
+	require.NoError(t, store.Delete(ctx, "archive.tar.gz"))
+	_, err = store.Get(ctx, "archive.tar.gz")
+	require.ErrorIs(t, err, objectstore.ErrNotFound)
+
+	_, err = store.Put(ctx, "../escape", strings.NewReader("hello"))
+	require.Error(t, err)
+}
+
+func TestNewStore(t *testing.T) {
+	t.Parallel()
+
+	for _, rawURL := range []string{
+		"ftp://bucket",
+		"file://",
+		"s3:///prefix",
+		"azblob://account",
+	} {
+		_, err := objectstore.NewStore(context.Background(), rawURL)
+		require.Error(t, err, rawURL)
+	}
+}
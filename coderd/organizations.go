@@ -47,5 +47,7 @@ func (*API) organization(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	organization := httpmw.OrganizationParam(r)
 
-	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.Organization(organization))
+	resp := db2sdk.Organization(organization)
+	httpapi.SetETag(rw, resp.ETag())
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
@@ -0,0 +1,203 @@
+// Package parameteroptions fetches the options of template parameters from
+// APIs configured by template admins, so that options such as the available
+// GPU pools don't have to be hard-coded in Terraform.
+package parameteroptions
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"tailscale.com/util/singleflight"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/quartz"
+)
+
+const (
+	// DefaultCacheTTL is how long options are cached when a source doesn't
+	// specify a cache TTL.
+	DefaultCacheTTL = 5 * time.Minute
+	// Timeout is how long a source has to respond.
+	Timeout = 10 * time.Second
+	// MaxOptions is the maximum number of options a source may return.
+	MaxOptions = 1000
+	// maxResponseSize limits how much of a source response is read.
+	maxResponseSize = 1 << 20
+)
+
+// ValidateURL returns an error if rawURL is not an HTTP or HTTPS URL.
+func ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return xerrors.Errorf("invalid parameter option source URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return xerrors.Errorf("invalid parameter option source URL %q, expected an http or https URL", rawURL)
+	}
+	return nil
+}
+
+// Options are the options fetched from a source.
+type Options struct {
+	Options   []codersdk.TemplateVersionParameterOption
+	FetchedAt time.Time
+}
+
+// Fetcher fetches parameter options from their sources and caches them for
+// the cache TTL of each source. Concurrent requests for the options of the
+// same parameter share a single request to the source.
+type Fetcher struct {
+	logger slog.Logger
+	clock  quartz.Clock
+	client *http.Client
+
+	fetchGroup singleflight.Group[cacheKey, Options]
+	mu         sync.Mutex
+	cache      map[cacheKey]cachedOptions
+}
+
+type cacheKey struct {
+	templateID    uuid.UUID
+	parameterName string
+}
+
+type cachedOptions struct {
+	options Options
+	// sourceUpdatedAt invalidates the cached options when the source is
+	// changed.
+	sourceUpdatedAt time.Time
+}
+
+// NewFetcher returns a Fetcher that calls sources with client. A nil client
+// uses http.DefaultClient.
+func NewFetcher(logger slog.Logger, clock quartz.Clock, client *http.Client) *Fetcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Fetcher{
+		logger: logger,
+		clock:  clock,
+		client: client,
+		cache:  make(map[cacheKey]cachedOptions),
+	}
+}
+
+// Fetch returns the options of the source, using the cached options if they
+// are fresh. If the source cannot be reached, stale cached options are
+// returned when there are any.
+func (f *Fetcher) Fetch(ctx context.Context, source database.TemplateParameterOptionSource) (Options, error) {
+	key := cacheKey{templateID: source.TemplateID, parameterName: source.ParameterName}
+	ttl := time.Duration(source.CacheTTL)
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	f.mu.Lock()
+	cached, ok := f.cache[key]
+	f.mu.Unlock()
+	if ok && !cached.sourceUpdatedAt.Equal(source.UpdatedAt) {
+		ok = false
+	}
+	if ok && f.clock.Since(cached.options.FetchedAt) < ttl {
+		return cached.options, nil
+	}
+
+	options, err, _ := f.fetchGroup.Do(key, func() (Options, error) {
+		// The request outlives the caller's context so that a canceled
+		// request doesn't fail every request sharing it.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), Timeout)
+		defer cancel()
+		return f.fetch(ctx, source)
+	})
+	if err != nil {
+		if ok {
+			f.logger.Warn(ctx, "fetch parameter options, using stale options",
+				slog.F("template_id", source.TemplateID),
+				slog.F("parameter", source.ParameterName),
+				slog.Error(err),
+			)
+			return cached.options, nil
+		}
+		return Options{}, err
+	}
+
+	f.mu.Lock()
+	f.cache[key] = cachedOptions{options: options, sourceUpdatedAt: source.UpdatedAt}
+	f.mu.Unlock()
+	return options, nil
+}
+
+// Forget removes the cached options of a parameter, e.g. when its source is
+// deleted.
+func (f *Fetcher) Forget(templateID uuid.UUID, parameterName string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.cache, cacheKey{templateID: templateID, parameterName: parameterName})
+}
+
+func (f *Fetcher) fetch(ctx context.Context, source database.TemplateParameterOptionSource) (Options, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.Url, nil)
+	if err != nil {
+		return Options{}, xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "coder/"+buildinfo.Version())
+	if source.AuthHeaderName != "" {
+		req.Header.Set(source.AuthHeaderName, source.AuthHeaderValue)
+	}
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return Options{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Options{}, xerrors.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	options, err := ParseOptions(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return Options{}, err
+	}
+	return Options{Options: options, FetchedAt: f.clock.Now()}, nil
+}
+
+// ParseOptions parses the response of a source. Sources respond with a JSON
+// array of options. The name of an option defaults to its value.
+func ParseOptions(r io.Reader) ([]codersdk.TemplateVersionParameterOption, error) {
+	var options []codersdk.TemplateVersionParameterOption
+	err := json.NewDecoder(r).Decode(&options)
+	if err != nil {
+		return nil, xerrors.Errorf("decode options: %w", err)
+	}
+	if len(options) > MaxOptions {
+		return nil, xerrors.Errorf("source returned %d options, the maximum is %d", len(options), MaxOptions)
+	}
+	seen := make(map[string]struct{}, len(options))
+	for i, option := range options {
+		if option.Value == "" {
+			return nil, xerrors.Errorf("option %d has no value", i)
+		}
+		if _, ok := seen[option.Value]; ok {
+			return nil, xerrors.Errorf("duplicate option value %q", option.Value)
+		}
+		seen[option.Value] = struct{}{}
+		if option.Name == "" {
+			options[i].Name = option.Value
+		}
+	}
+	if options == nil {
+		options = []codersdk.TemplateVersionParameterOption{}
+	}
+	return options, nil
+}
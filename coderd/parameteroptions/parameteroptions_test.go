@@ -0,0 +1,126 @@
+package parameteroptions_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/parameteroptions"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestValidateURL(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, parameteroptions.ValidateURL("https://inventory.example.com/gpu-pools"))
+	require.NoError(t, parameteroptions.ValidateURL("http://inventory.internal:8080"))
+	require.Error(t, parameteroptions.ValidateURL("file:///etc/passwd"))
+	require.Error(t, parameteroptions.ValidateURL("inventory.example.com"))
+}
+
+func TestParseOptions(t *testing.T) {
+	t.Parallel()
+
+	options, err := parameteroptions.ParseOptions(strings.NewReader(`[{"value":"a100"},{"name":"H100 pool","value":"h100","description":"Fast"}]`))
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.TemplateVersionParameterOption{
+		{Name: "a100", Value: "a100"},
+		{Name: "H100 pool", Value: "h100", Description: "Fast"},
+	}, options)
+
+	options, err = parameteroptions.ParseOptions(strings.NewReader(`[]`))
+	require.NoError(t, err)
+	require.Empty(t, options)
+
+	_, err = parameteroptions.ParseOptions(strings.NewReader(`[{"name":"missing value"}]`))
+	require.ErrorContains(t, err, "has no value")
+
+	_, err = parameteroptions.ParseOptions(strings.NewReader(`[{"value":"a"},{"value":"a"}]`))
+	require.ErrorContains(t, err, "duplicate option value")
+
+	_, err = parameteroptions.ParseOptions(strings.NewReader(`{"options":[]}`))
+	require.Error(t, err)
+}
+
+func TestFetcher(t *testing.T) {
+	t.Parallel()
+
+	var (
+		requests atomic.Int32
+		fail     atomic.Bool
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if fail.Load() {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = rw.Write([]byte(`[{"value":"a100"},{"value":"h100"}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clock := quartz.NewMock(t)
+	fetcher := parameteroptions.NewFetcher(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), clock, srv.Client())
+	source := database.TemplateParameterOptionSource{
+		TemplateID:      uuid.New(),
+		ParameterName:   "gpu_pool",
+		Url:             srv.URL,
+		AuthHeaderName:  "Authorization",
+		AuthHeaderValue: "Bearer secret",
+		CacheTTL:        int64(time.Minute),
+		UpdatedAt:       clock.Now(),
+	}
+
+	options, err := fetcher.Fetch(ctx, source)
+	require.NoError(t, err)
+	require.Len(t, options.Options, 2)
+	require.Equal(t, "a100", options.Options[0].Value)
+	require.EqualValues(t, 1, requests.Load())
+
+	// Fresh options are served from the cache.
+	_, err = fetcher.Fetch(ctx, source)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, requests.Load())
+
+	// Stale options are fetched again.
+	clock.Advance(time.Minute)
+	_, err = fetcher.Fetch(ctx, source)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, requests.Load())
+
+	// Stale options are used when the source fails.
+	fail.Store(true)
+	clock.Advance(time.Minute)
+	options, err = fetcher.Fetch(ctx, source)
+	require.NoError(t, err)
+	require.Len(t, options.Options, 2)
+	require.EqualValues(t, 3, requests.Load())
+
+	// Updating the source invalidates the cache.
+	fail.Store(false)
+	source.UpdatedAt = clock.Now()
+	_, err = fetcher.Fetch(ctx, source)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, requests.Load())
+
+	// Without cached options, errors are returned.
+	fetcher.Forget(source.TemplateID, source.ParameterName)
+	source.AuthHeaderValue = "Bearer wrong"
+	_, err = fetcher.Fetch(ctx, source)
+	require.ErrorContains(t, err, "unexpected status code 401")
+}
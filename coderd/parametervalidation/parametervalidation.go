@@ -0,0 +1,110 @@
+// Package parametervalidation calls the parameter validation webhooks of
+// templates. Webhooks let organizations enforce rules on parameter values,
+// such as cost center codes or allowed regions, without encoding them in
+// every template.
+package parametervalidation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// Timeout is how long a webhook has to respond.
+	Timeout = 10 * time.Second
+	// maxResponseSize limits how much of a webhook response is read.
+	maxResponseSize = 1 << 20
+	// defaultRejectedMessage is shown when a webhook rejects parameter values
+	// without a message.
+	defaultRejectedMessage = "The parameter values were rejected by the template's validation webhook."
+)
+
+// RejectedError is returned when a webhook rejects the parameter values.
+type RejectedError struct {
+	Message     string
+	Validations []codersdk.ValidationError
+}
+
+func (e *RejectedError) Error() string {
+	if len(e.Validations) == 0 {
+		return e.Message
+	}
+	details := make([]string, 0, len(e.Validations))
+	for _, validation := range e.Validations {
+		details = append(details, fmt.Sprintf("%s: %s", validation.Field, validation.Detail))
+	}
+	return fmt.Sprintf("%s (%s)", e.Message, strings.Join(details, "; "))
+}
+
+// ValidateURL returns an error if rawURL is not an HTTP or HTTPS URL.
+func ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return xerrors.Errorf("invalid parameter validation URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return xerrors.Errorf("invalid parameter validation URL %q, expected an http or https URL", rawURL)
+	}
+	return nil
+}
+
+// Validate posts the request to the webhook at rawURL. It returns a
+// *RejectedError if the webhook rejects the parameter values, and another
+// error if the webhook could not be called. A nil client uses
+// http.DefaultClient.
+func Validate(ctx context.Context, client *http.Client, rawURL string, req codersdk.ParameterValidationRequest) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return xerrors.Errorf("marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "coder/"+buildinfo.Version())
+
+	res, err := client.Do(httpReq)
+	if err != nil {
+		return xerrors.Errorf("call webhook: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return xerrors.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+
+	var resp codersdk.ParameterValidationResponse
+	err = json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(&resp)
+	if err != nil {
+		return xerrors.Errorf("decode webhook response: %w", err)
+	}
+	if resp.Valid {
+		return nil
+	}
+	rejected := &RejectedError{
+		Message:     resp.Message,
+		Validations: resp.Validations,
+	}
+	if rejected.Message == "" {
+		rejected.Message = defaultRejectedMessage
+	}
+	return rejected
+}
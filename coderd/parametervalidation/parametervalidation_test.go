@@ -0,0 +1,93 @@
+package parametervalidation_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/parametervalidation"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestValidateURL(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, parametervalidation.ValidateURL("https://example.com/validate"))
+	require.NoError(t, parametervalidation.ValidateURL("http://validator.internal:8080"))
+	require.Error(t, parametervalidation.ValidateURL("ftp://example.com"))
+	require.Error(t, parametervalidation.ValidateURL("example.com/validate"))
+	require.Error(t, parametervalidation.ValidateURL("://"))
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	req := codersdk.ParameterValidationRequest{
+		TemplateID:    uuid.New(),
+		WorkspaceName: "dev",
+		Initiator:     codersdk.ParameterValidationUser{ID: uuid.New(), Username: "alice", Email: "alice@coder.com"},
+		Parameters:    []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu-west-1"}},
+	}
+
+	webhook := func(t *testing.T, status int, resp any) string {
+		t.Helper()
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			var got codersdk.ParameterValidationRequest
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+			assert.Equal(t, req, got)
+			rw.WriteHeader(status)
+			_ = json.NewEncoder(rw).Encode(resp)
+		}))
+		t.Cleanup(srv.Close)
+		return srv.URL
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		url := webhook(t, http.StatusOK, codersdk.ParameterValidationResponse{Valid: true})
+		require.NoError(t, parametervalidation.Validate(ctx, nil, url, req))
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		url := webhook(t, http.StatusOK, codersdk.ParameterValidationResponse{
+			Message:     "Region not allowed for your team.",
+			Validations: []codersdk.ValidationError{{Field: "region", Detail: "use us-east-1"}},
+		})
+		err := parametervalidation.Validate(ctx, nil, url, req)
+		var rejected *parametervalidation.RejectedError
+		require.ErrorAs(t, err, &rejected)
+		require.Equal(t, "Region not allowed for your team.", rejected.Message)
+		require.Equal(t, "Region not allowed for your team. (region: use us-east-1)", err.Error())
+	})
+
+	t.Run("RejectedWithoutMessage", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		url := webhook(t, http.StatusOK, codersdk.ParameterValidationResponse{})
+		err := parametervalidation.Validate(ctx, nil, url, req)
+		var rejected *parametervalidation.RejectedError
+		require.ErrorAs(t, err, &rejected)
+		require.NotEmpty(t, rejected.Message)
+	})
+
+	t.Run("ServerError", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		url := webhook(t, http.StatusInternalServerError, nil)
+		err := parametervalidation.Validate(ctx, nil, url, req)
+		var rejected *parametervalidation.RejectedError
+		require.False(t, errors.As(err, &rejected))
+		require.ErrorContains(t, err, "status 500")
+	})
+}
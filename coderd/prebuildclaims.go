@@ -0,0 +1,202 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+// defaultPrebuildClaimTTL is how long a claimed prebuilt workspace is held if
+// the request does not say.
+const defaultPrebuildClaimTTL = time.Hour
+
+// @Summary Claim prebuilt workspace
+// @Description Claims a ready prebuilt workspace of a preset for the caller,
+// @Description e.g. to run a CI job in it. The workspace is ephemeral: it is
+// @Description deleted when it is released or its deadline passes, and the
+// @Description prebuilds reconciler replaces it in the pool. Fails with 409 if
+// @Description no prebuilt workspace of the preset is ready.
+// @ID claim-prebuilt-workspace
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Prebuilds
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.ClaimPrebuiltWorkspaceRequest true "Claim prebuilt workspace request"
+// @Success 201 {object} codersdk.Workspace
+// @Router /organizations/{organization}/prebuilds/claim [post]
+func (api *API) postPrebuiltWorkspaceClaim(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+		apiKey       = httpmw.APIKey(r)
+	)
+
+	var req codersdk.ClaimPrebuiltWorkspaceRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.TTLMillis == nil {
+		req.TTLMillis = ptr.Ref(defaultPrebuildClaimTTL.Milliseconds())
+	}
+	ttl, err := validWorkspaceTTLMillis(req.TTLMillis, 0)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid claim time to live.",
+			Validations: []codersdk.ValidationError{{Field: "ttl_ms", Detail: err.Error()}},
+		})
+		return
+	}
+	if req.Name == "" {
+		suffix, err := cryptorand.StringCharset(cryptorand.Human, 8)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		req.Name = "claim-" + suffix
+	}
+
+	preset, err := api.Database.GetPresetByID(ctx, req.TemplateVersionPresetID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Preset not found.",
+			Validations: []codersdk.ValidationError{{Field: "template_version_preset_id", Detail: "Preset not found."}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching preset.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if preset.OrganizationID != organization.ID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Preset %q does not belong to organization %q.", preset.Name, organization.Name),
+		})
+		return
+	}
+
+	user, err := api.Database.GetUserByID(ctx, apiKey.UserID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	owner := workspaceOwner{
+		ID:        user.ID,
+		Username:  user.Username,
+		AvatarURL: user.AvatarURL,
+	}
+
+	auditor := api.Auditor.Load()
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+		Audit:   *auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+		AdditionalFields: audit.AdditionalFields{
+			WorkspaceOwner: owner.Username,
+		},
+	})
+	defer commitAudit()
+
+	workspace, ok := createWorkspaceInternal(ctx, aReq, apiKey.UserID, api, owner, codersdk.CreateWorkspaceRequest{
+		TemplateVersionID:       preset.TemplateVersionID,
+		Name:                    req.Name,
+		TTLMillis:               req.TTLMillis,
+		TemplateVersionPresetID: preset.ID,
+		// Claimed workspaces are recycled, stopping them deletes them.
+		Ephemeral: true,
+	}, rw, r, func(db database.Store, workspace database.Workspace, claimed bool) error {
+		if !claimed {
+			// Building a new workspace would take as long as the CI job
+			// waiting for it, so the caller decides whether to retry.
+			return wsbuilder.BuildError{
+				Status:  http.StatusConflict,
+				Message: fmt.Sprintf("No prebuilt workspace of preset %q is ready to be claimed.", preset.Name),
+				Wrapped: xerrors.New("no claimable prebuilt workspace"),
+			}
+		}
+		// Prebuilt workspaces keep the time to live they were built with.
+		err := db.UpdateWorkspaceTTL(ctx, database.UpdateWorkspaceTTLParams{
+			ID:  workspace.ID,
+			Ttl: ttl,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace ttl: %w", err)
+		}
+		return nil
+	})
+	if !ok {
+		return
+	}
+	workspace.TTLMillis = ptr.Ref(time.Duration(ttl.Int64).Milliseconds())
+	httpapi.Write(ctx, rw, http.StatusCreated, workspace)
+}
+
+// @Summary Release claimed prebuilt workspace
+// @Description Deletes a workspace claimed with the claim endpoint, e.g. when
+// @Description the CI job that claimed it is done. The prebuilds reconciler
+// @Description has replaced it in the pool since it was claimed.
+// @ID release-claimed-prebuilt-workspace
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Prebuilds
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 201 {object} codersdk.WorkspaceBuild
+// @Router /organizations/{organization}/prebuilds/claim/{workspace}/release [post]
+func (api *API) postPrebuiltWorkspaceRelease(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+		workspace    = httpmw.WorkspaceParam(r)
+	)
+	if workspace.OrganizationID != organization.ID {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	// Claimed workspaces were built by the prebuilds user before they were
+	// claimed, and are ephemeral so deleting them is their only way out.
+	firstBuild, err := api.Database.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams{
+		WorkspaceID: workspace.ID,
+		BuildNumber: 1,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if workspace.IsPrebuild() || !workspace.Ephemeral || firstBuild.InitiatorID != database.PrebuildsSystemUserID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Workspace %q is not a claimed prebuilt workspace.", workspace.Name),
+		})
+		return
+	}
+
+	apiBuild, ok := api.postWorkspaceBuildsInternal(rw, r, workspace, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionDelete,
+	})
+	if !ok {
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, apiBuild)
+}
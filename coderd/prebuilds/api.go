@@ -54,6 +54,8 @@ type StateSnapshotter interface {
 }
 
 type Claimer interface {
-	Claim(ctx context.Context, userID uuid.UUID, name string, presetID uuid.UUID) (*uuid.UUID, error)
+	// Claim transfers an eligible prebuilt workspace of the preset to the
+	// user. Ephemeral marks the claimed workspace as ephemeral.
+	Claim(ctx context.Context, userID uuid.UUID, name string, presetID uuid.UUID, ephemeral bool) (*uuid.UUID, error)
 	Initiator() uuid.UUID
 }
@@ -252,6 +252,74 @@ func Workspaces(ctx context.Context, logger slog.Logger, registerer prometheus.R
 	}, nil
 }
 
+// ProvisionerJobs tracks the number of queued and running provisioner jobs by
+// organization.
+func ProvisionerJobs(ctx context.Context, logger slog.Logger, registerer prometheus.Registerer, db database.Store, duration time.Duration) (func(), error) {
+	if duration == 0 {
+		duration = defaultRefreshRate
+	}
+
+	pendingJobs := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "provisionerd",
+		Name:      "jobs_pending",
+		Help:      "The number of provisioner jobs waiting to be acquired by a provisioner daemon, by organization.",
+	}, []string{"organization_name"})
+	if err := registerer.Register(pendingJobs); err != nil {
+		return nil, err
+	}
+
+	runningJobs := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "provisionerd",
+		Name:      "jobs_running",
+		Help:      "The number of running provisioner jobs, by organization.",
+	}, []string{"organization_name"})
+	if err := registerer.Register(runningJobs); err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	// Use time.Nanosecond to force an initial tick. It will be reset to the
+	// correct duration after executing once.
+	ticker := time.NewTicker(time.Nanosecond)
+	doTick := func() {
+		defer ticker.Reset(duration)
+
+		counts, err := db.GetProvisionerJobCountsByOrganization(ctx)
+		if err != nil {
+			logger.Warn(ctx, "failed to load provisioner job counts", slog.Error(err))
+			return
+		}
+
+		pendingJobs.Reset()
+		runningJobs.Reset()
+		for _, count := range counts {
+			pendingJobs.WithLabelValues(count.OrganizationName).Set(float64(count.PendingJobs))
+			runningJobs.WithLabelValues(count.OrganizationName).Set(float64(count.RunningJobs))
+		}
+	}
+
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				doTick()
+			}
+		}
+	}()
+	return func() {
+		cancelFunc()
+		<-done
+	}, nil
+}
+
 // Agents tracks the total number of workspaces with labels on status.
 func Agents(ctx context.Context, logger slog.Logger, registerer prometheus.Registerer, db database.Store, coordinator *atomic.Pointer[tailnet.Coordinator], derpMapFn func() *tailcfg.DERPMap, agentInactiveDisconnectTimeout, duration time.Duration) (func(), error) {
 	if duration == 0 {
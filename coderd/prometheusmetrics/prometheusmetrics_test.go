@@ -371,6 +371,38 @@ func TestWorkspaceLatestBuildStatuses(t *testing.T) {
 	}
 }
 
+func TestProvisionerJobs(t *testing.T) {
+	t.Parallel()
+
+	db, _ := dbtestutil.NewDB(t)
+	org := dbgen.Organization(t, db, database.Organization{})
+	_ = dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{OrganizationID: org.ID})
+	_ = dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{OrganizationID: org.ID})
+	_ = dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+		OrganizationID: org.ID,
+		StartedAt:      sql.NullTime{Time: dbtime.Now(), Valid: true},
+	})
+
+	registry := prometheus.NewRegistry()
+	closeFunc, err := prometheusmetrics.ProvisionerJobs(context.Background(), testutil.Logger(t).Leveled(slog.LevelWarn), registry, db, testutil.IntervalFast)
+	require.NoError(t, err)
+	t.Cleanup(closeFunc)
+
+	require.Eventually(t, func() bool {
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+		found := map[string]float64{}
+		for _, m := range metrics {
+			for _, metric := range m.Metric {
+				if metric.Label[0].GetValue() == org.Name {
+					found[m.GetName()] = metric.Gauge.GetValue()
+				}
+			}
+		}
+		return found["coderd_provisionerd_jobs_pending"] == 2 && found["coderd_provisionerd_jobs_running"] == 1
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
 func TestAgents(t *testing.T) {
 	t.Parallel()
 
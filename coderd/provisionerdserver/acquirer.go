@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"math"
 	"slices"
 	"strings"
 	"sync"
@@ -49,6 +50,18 @@ type Acquirer struct {
 	mu sync.Mutex
 	q  map[dKey]domain
 
+	// maxBuildsPerUser and maxBuildsPerTemplate limit the number of running
+	// workspace builds. A limit of 0 disables it.
+	maxBuildsPerUser     int32
+	maxBuildsPerTemplate int32
+
+	// maxConcurrentJobs is the shared capacity of the deployment, and
+	// maxJobsPerOrganization and reservedJobsPerOrganization are the defaults
+	// for organizations that don't override them. A limit of 0 disables it.
+	maxConcurrentJobs           int32
+	maxJobsPerOrganization      int32
+	reservedJobsPerOrganization int32
+
 	// testing only
 	backupPollDuration time.Duration
 }
@@ -61,9 +74,34 @@ func TestingBackupPollDuration(dur time.Duration) AcquirerOption {
 	}
 }
 
+// BuildConcurrencyLimits limits the number of workspace builds of a single user
+// or template that run at once. Builds over a limit stay pending until a
+// running build completes. A limit of 0 disables it.
+func BuildConcurrencyLimits(perUser, perTemplate int64) AcquirerOption {
+	return func(a *Acquirer) {
+		a.maxBuildsPerUser = int32(min(perUser, math.MaxInt32))         //nolint:gosec // Clamped to MaxInt32.
+		a.maxBuildsPerTemplate = int32(min(perTemplate, math.MaxInt32)) //nolint:gosec // Clamped to MaxInt32.
+	}
+}
+
+// OrganizationJobLimits limits the number of jobs that run at once across all
+// organizations, and by default for a single organization. Organizations are
+// guaranteed their reserved jobs out of the shared capacity, so a burst of
+// jobs from one organization can't use up all of it. Jobs over a limit stay
+// pending until a running job completes. A limit of 0 disables it.
+func OrganizationJobLimits(maxConcurrent, perOrganization, reservedPerOrganization int64) AcquirerOption {
+	return func(a *Acquirer) {
+		a.maxConcurrentJobs = int32(min(maxConcurrent, math.MaxInt32))                     //nolint:gosec // Clamped to MaxInt32.
+		a.maxJobsPerOrganization = int32(min(perOrganization, math.MaxInt32))              //nolint:gosec // Clamped to MaxInt32.
+		a.reservedJobsPerOrganization = int32(min(reservedPerOrganization, math.MaxInt32)) //nolint:gosec // Clamped to MaxInt32.
+	}
+}
+
 // AcquirerStore is the subset of database.Store that the Acquirer needs
 type AcquirerStore interface {
+	AcquireLock(ctx context.Context, id int64) error
 	AcquireProvisionerJob(context.Context, database.AcquireProvisionerJobParams) (database.ProvisionerJob, error)
+	InTx(func(database.Store) error, *database.TxOptions) error
 }
 
 func NewAcquirer(ctx context.Context, logger slog.Logger, store AcquirerStore, ps pubsub.Pubsub,
@@ -120,7 +158,7 @@ func (a *Acquirer) AcquireJob(
 			return database.ProvisionerJob{}, err
 		case <-clearance:
 			logger.Debug(ctx, "got clearance to call database")
-			job, err := a.store.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			job, err := a.acquire(ctx, database.AcquireProvisionerJobParams{
 				OrganizationID: organization,
 				StartedAt: sql.NullTime{
 					Time:  dbtime.Now(),
@@ -130,8 +168,13 @@ func (a *Acquirer) AcquireJob(
 					UUID:  worker,
 					Valid: true,
 				},
-				Types:           pt,
-				ProvisionerTags: dbTags,
+				Types:                       pt,
+				ProvisionerTags:             dbTags,
+				MaxBuildsPerUser:            a.maxBuildsPerUser,
+				MaxBuildsPerTemplate:        a.maxBuildsPerTemplate,
+				MaxConcurrentJobs:           a.maxConcurrentJobs,
+				MaxJobsPerOrganization:      a.maxJobsPerOrganization,
+				ReservedJobsPerOrganization: a.reservedJobsPerOrganization,
 			})
 			if xerrors.Is(err, sql.ErrNoRows) {
 				logger.Debug(ctx, "no job available")
@@ -153,6 +196,36 @@ func (a *Acquirer) AcquireJob(
 	}
 }
 
+// acquire runs AcquireProvisionerJob while holding the admission lock. The
+// concurrency limits are checked by counting running jobs, and under READ
+// COMMITTED two acquisitions could otherwise both count the same free slot
+// and start a job each. The lock has to be taken in its own statement, since
+// a statement only sees rows committed before it began.
+func (a *Acquirer) acquire(ctx context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
+	var job database.ProvisionerJob
+	err := a.store.InTx(func(tx database.Store) error {
+		err := tx.AcquireLock(ctx, a.admissionLockID(arg.OrganizationID))
+		if err != nil {
+			return xerrors.Errorf("acquire admission lock: %w", err)
+		}
+		job, err = tx.AcquireProvisionerJob(ctx, arg)
+		return err
+	}, database.DefaultTXOptions().WithID("acquire_provisioner_job"))
+	return job, err
+}
+
+// admissionLockID returns the advisory lock that serializes admission of jobs.
+// The per-user limit and the shared capacity span organizations, so while
+// either is set admission is serialized across the deployment. Otherwise the
+// remaining limits (per template and per organization) only count jobs of one
+// organization, and acquisitions in different organizations don't contend.
+func (a *Acquirer) admissionLockID(organization uuid.UUID) int64 {
+	if a.maxBuildsPerUser > 0 || a.maxConcurrentJobs > 0 {
+		return database.LockIDProvisionerJobAdmission
+	}
+	return database.GenLockID("provisioner-job-admission:" + organization.String())
+}
+
 // want signals that an acquiree wants clearance to query for a job with the given dKey.
 func (a *Acquirer) want(organization uuid.UUID, pt []database.ProvisionerType, tags Tags, clearance chan<- struct{}) {
 	dk := domainKey(organization, pt, tags)
@@ -348,6 +421,14 @@ func (a *Acquirer) jobPosted(ctx context.Context, message []byte, err error) {
 
 	a.mu.Lock()
 	defer a.mu.Unlock()
+	if a.maxConcurrentJobs > 0 {
+		// The capacity is shared, so a finished job may let the queued jobs of
+		// any organization be acquired.
+		for _, d := range a.q {
+			a.clearOrPendLocked(d)
+		}
+		return
+	}
 	for _, d := range a.q {
 		if d.contains(posting) {
 			a.clearOrPendLocked(d)
@@ -256,6 +256,65 @@ func TestAcquirer_DifferentDomains(t *testing.T) {
 	acquiree0.requireCanceled(ctx)
 }
 
+// TestAcquirer_AdmissionLock tests that jobs are acquired under the admission
+// lock of their organization, or the deployment-wide lock when a limit spans
+// organizations.
+func TestAcquirer_AdmissionLock(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name string
+		opts []provisionerdserver.AcquirerOption
+		lock func(orgID uuid.UUID) int64
+	}{
+		{
+			name: "Organization",
+			opts: []provisionerdserver.AcquirerOption{
+				provisionerdserver.BuildConcurrencyLimits(0, 2),
+				provisionerdserver.OrganizationJobLimits(0, 5, 0),
+			},
+			lock: func(orgID uuid.UUID) int64 {
+				return database.GenLockID("provisioner-job-admission:" + orgID.String())
+			},
+		},
+		{
+			name: "PerUser",
+			opts: []provisionerdserver.AcquirerOption{provisionerdserver.BuildConcurrencyLimits(1, 0)},
+			lock: func(uuid.UUID) int64 { return database.LockIDProvisionerJobAdmission },
+		},
+		{
+			name: "SharedCapacity",
+			opts: []provisionerdserver.AcquirerOption{provisionerdserver.OrganizationJobLimits(10, 0, 2)},
+			lock: func(uuid.UUID) int64 { return database.LockIDProvisionerJobAdmission },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			fs := newFakeTaggedStore(t)
+			ps := pubsub.NewInMemory()
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+			defer cancel()
+			logger := testutil.Logger(t)
+
+			orgID := uuid.New()
+			jobID := uuid.New()
+			fs.jobs = []database.ProvisionerJob{
+				{ID: jobID, Provisioner: database.ProvisionerTypeEcho, Tags: database.StringMap{}},
+			}
+			uut := provisionerdserver.NewAcquirer(ctx, logger.Named("acquirer"), fs, ps, tc.opts...)
+
+			acquiree := newTestAcquiree(t, orgID, uuid.New(), []database.ProvisionerType{database.ProvisionerTypeEcho}, provisionerdserver.Tags{})
+			acquiree.startAcquire(ctx, uut)
+			job := acquiree.success(ctx)
+			require.Equal(t, jobID, job.ID)
+
+			fs.mu.Lock()
+			defer fs.mu.Unlock()
+			require.Equal(t, []int64{tc.lock(orgID)}, fs.locks)
+		})
+	}
+}
+
 func TestAcquirer_BackupPoll(t *testing.T) {
 	t.Parallel()
 	fs := newFakeOrderedStore()
@@ -563,6 +622,8 @@ func postJob(t *testing.T, ps pubsub.Pubsub, pt database.ProvisionerType, tags p
 // fakeOrderedStore is a fake store that lets tests send AcquireProvisionerJob
 // results in order over a channel, and tests for overlapped calls.
 type fakeOrderedStore struct {
+	database.Store
+
 	jobs   chan database.ProvisionerJob
 	errors chan error
 
@@ -608,6 +669,14 @@ func (s *fakeOrderedStore) AcquireProvisionerJob(
 	return job, err
 }
 
+func (*fakeOrderedStore) AcquireLock(context.Context, int64) error {
+	return nil
+}
+
+func (s *fakeOrderedStore) InTx(fn func(database.Store) error, _ *database.TxOptions) error {
+	return fn(s)
+}
+
 func (s *fakeOrderedStore) sendCtx(ctx context.Context, job database.ProvisionerJob, err error) error {
 	select {
 	case <-ctx.Done():
@@ -628,9 +697,12 @@ func (s *fakeOrderedStore) sendCtx(ctx context.Context, job database.Provisioner
 // available, and returns them to callers with the appropriate provisioner type
 // and tags. It doesn't care about the order.
 type fakeTaggedStore struct {
+	database.Store
+
 	t      *testing.T
 	mu     sync.Mutex
 	jobs   []database.ProvisionerJob
+	locks  []int64
 	params chan database.AcquireProvisionerJobParams
 }
 
@@ -675,6 +747,17 @@ jobLoop:
 	return database.ProvisionerJob{}, sql.ErrNoRows
 }
 
+func (s *fakeTaggedStore) AcquireLock(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.locks = append(s.locks, id)
+	return nil
+}
+
+func (s *fakeTaggedStore) InTx(fn func(database.Store) error, _ *database.TxOptions) error {
+	return fn(s)
+}
+
 // testAcquiree is a helper type that handles asynchronously calling AcquireJob
 // and asserting whether or not it returns, blocks, or is canceled.
 type testAcquiree struct {
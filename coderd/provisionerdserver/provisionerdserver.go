@@ -40,6 +40,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/notifications"
@@ -47,6 +48,7 @@ import (
 	"github.com/coder/coder/v2/coderd/promoauth"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
+	"github.com/coder/coder/v2/coderd/templatepolicy"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/wspubsub"
 	"github.com/coder/coder/v2/codersdk"
@@ -79,6 +81,10 @@ type Options struct {
 	OIDCConfig          promoauth.OAuth2Config
 	ExternalAuthConfigs []*externalauth.Config
 
+	// TemplatePolicy rejects template versions which violate the deployment's
+	// template policies. If nil, all template versions are accepted.
+	TemplatePolicy *templatepolicy.Engine
+
 	// Clock for testing
 	Clock quartz.Clock
 
@@ -108,6 +114,7 @@ type server struct {
 	Logger                      slog.Logger
 	Provisioners                []database.ProvisionerType
 	ExternalAuthConfigs         []*externalauth.Config
+	TemplatePolicy              *templatepolicy.Engine
 	Tags                        Tags
 	Database                    database.Store
 	Pubsub                      pubsub.Pubsub
@@ -226,6 +233,7 @@ func NewServer(
 		Logger:                      logger,
 		Provisioners:                provisioners,
 		ExternalAuthConfigs:         options.ExternalAuthConfigs,
+		TemplatePolicy:              options.TemplatePolicy,
 		Tags:                        tags,
 		Database:                    db,
 		Pubsub:                      ps,
@@ -494,6 +502,17 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("get template: %s", err))
 		}
+		templateSecrets, err := s.Database.GetTemplateSecretsByTemplateID(ctx, template.ID)
+		if err != nil {
+			return nil, failJob(fmt.Sprintf("get template secrets: %s", err))
+		}
+		setValues, err := s.templateVariableSetValues(ctx, templateVersion.TemplateID)
+		if err != nil {
+			return nil, failJob(fmt.Sprintf("get template variable sets: %s", err))
+		}
+		variableValues := applyVariableSets(asVariableValues(templateVariables), templateVariables, setValues)
+		variableValues, appliedSecrets := applyTemplateSecrets(variableValues, templateVariables, templateSecrets)
+		s.auditTemplateSecretReads(ctx, job, workspace, appliedSecrets)
 		owner, err := s.Database.GetUserByID(ctx, workspace.OwnerID)
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("get owner: %s", err))
@@ -689,7 +708,7 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 				State:                   workspaceBuild.ProvisionerState,
 				RichParameterValues:     convertRichParameterValues(workspaceBuildParameters),
 				PreviousParameterValues: convertRichParameterValues(lastWorkspaceBuildParameters),
-				VariableValues:          asVariableValues(templateVariables),
+				VariableValues:          variableValues,
 				ExternalAuthProviders:   externalAuthProviders,
 				Metadata: &sdkproto.Metadata{
 					CoderUrl:                      s.AccessURL.String(),
@@ -732,11 +751,15 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
 			return nil, failJob(fmt.Sprintf("get template version variables: %s", err))
 		}
+		setValues, err := s.templateVariableSetValues(ctx, templateVersion.TemplateID)
+		if err != nil {
+			return nil, failJob(fmt.Sprintf("get template variable sets: %s", err))
+		}
 
 		protoJob.Type = &proto.AcquiredJob_TemplateDryRun_{
 			TemplateDryRun: &proto.AcquiredJob_TemplateDryRun{
 				RichParameterValues: convertRichParameterValues(input.RichParameterValues),
-				VariableValues:      asVariableValues(templateVariables),
+				VariableValues:      applyVariableSets(asVariableValues(templateVariables), templateVariables, setValues),
 				Metadata: &sdkproto.Metadata{
 					CoderUrl:      s.AccessURL.String(),
 					WorkspaceName: input.WorkspaceName,
@@ -987,6 +1010,13 @@ func (s *server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest)
 			return nil, xerrors.Errorf("get template version by job id: %w", err)
 		}
 
+		// Variable sets attached to the template take precedence over the
+		// values of the template version, like they do in workspace builds.
+		setValues, err := s.templateVariableSetValues(ctx, templateVersion.TemplateID)
+		if err != nil {
+			return nil, xerrors.Errorf("get template variable sets: %w", err)
+		}
+
 		var variableValues []*sdkproto.VariableValue
 		var variablesWithMissingValues []string
 		for _, templateVariable := range request.TemplateVariables {
@@ -1000,15 +1030,21 @@ func (s *server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest)
 				}
 			}
 
-			if templateVariable.Required && value == "" {
+			setValue, fromSet := setValues[templateVariable.Name]
+			if templateVariable.Required && value == "" && !fromSet {
 				variablesWithMissingValues = append(variablesWithMissingValues, templateVariable.Name)
 			}
 
-			variableValues = append(variableValues, &sdkproto.VariableValue{
+			variableValue := &sdkproto.VariableValue{
 				Name:      templateVariable.Name,
 				Value:     value,
 				Sensitive: templateVariable.Sensitive,
-			})
+			}
+			if fromSet {
+				variableValue.Value = setValue.Value
+				variableValue.Sensitive = variableValue.Sensitive || setValue.Secret
+			}
+			variableValues = append(variableValues, variableValue)
 
 			_, err = s.Database.InsertTemplateVersionVariable(ctx, database.InsertTemplateVersionVariableParams{
 				TemplateVersionID: templateVersion.ID,
@@ -1136,6 +1172,7 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 		}
 
 		s.notifyWorkspaceBuildFailed(ctx, workspace, build)
+		s.haltFailingTemplateVersionRollout(ctx, workspace, build)
 
 		msg, err := json.Marshal(wspubsub.WorkspaceEvent{
 			Kind:        wspubsub.WorkspaceEventKindStateChange,
@@ -1179,6 +1216,10 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 					BuildNumber:   strconv.FormatInt(int64(build.BuildNumber), 10),
 					BuildReason:   database.BuildReason(string(build.Reason)),
 					WorkspaceID:   workspace.ID,
+
+					BuildReasonMessage:  build.ReasonMessage,
+					InitiatorAPIKeyName: build.InitiatorAPIKeyName,
+					InitiatorCIJobURL:   build.InitiatorCIJobURL,
 				}
 
 				wriBytes, err := json.Marshal(buildResourceInfo)
@@ -1215,9 +1256,44 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 		s.Logger.Error(ctx, "failed to publish end of job logs", slog.F("job_id", jobID), slog.Error(err))
 		return nil, xerrors.Errorf("publish end of job logs: %w", err)
 	}
+	s.releaseJobSlot(ctx, job)
 	return &proto.Empty{}, nil
 }
 
+// releaseJobSlot announces that a job has finished when concurrency limits
+// apply to it, so that jobs queued behind it are acquired without waiting for
+// the acquirer's backup poll.
+func (s *server) releaseJobSlot(ctx context.Context, job database.ProvisionerJob) {
+	if !s.jobSlotLimited(ctx, job) {
+		return
+	}
+	err := provisionerjobs.PostJob(s.Pubsub, job)
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to post job after releasing job slot", slog.F("job_id", job.ID), slog.Error(err))
+	}
+}
+
+// jobSlotLimited returns whether the job counted towards a concurrent build
+// or job limit.
+func (s *server) jobSlotLimited(ctx context.Context, job database.ProvisionerJob) bool {
+	limits := s.DeploymentValues.Provisioner
+	if job.Type == database.ProvisionerJobTypeWorkspaceBuild &&
+		(limits.MaxBuildsPerUser.Value() > 0 || limits.MaxBuildsPerTemplate.Value() > 0) {
+		return true
+	}
+	if limits.MaxConcurrentJobs.Value() > 0 || limits.MaxJobsPerOrganization.Value() > 0 {
+		return true
+	}
+	overrides, err := s.Database.GetOrganizationSettingOverrides(ctx, job.OrganizationID)
+	if err != nil {
+		if !xerrors.Is(err, sql.ErrNoRows) {
+			s.Logger.Warn(ctx, "failed to get organization setting overrides", slog.F("job_id", job.ID), slog.Error(err))
+		}
+		return false
+	}
+	return overrides.ProvisionerMaxJobs.Int32 > 0
+}
+
 func (s *server) notifyWorkspaceBuildFailed(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild) {
 	var reason string
 	if build.Reason.Valid() && build.Reason == database.BuildReasonInitiator {
@@ -1238,6 +1314,89 @@ func (s *server) notifyWorkspaceBuildFailed(ctx context.Context, workspace datab
 	}
 }
 
+// haltFailingTemplateVersionRollout halts the rollout of the template version
+// the workspace was built with once the share of failed builds of the version
+// exceeds the failure threshold of the rollout.
+func (s *server) haltFailingTemplateVersionRollout(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild) {
+	if build.Transition != database.WorkspaceTransitionStart {
+		return
+	}
+
+	rollout, err := s.Database.GetTemplateVersionRolloutByTemplateID(ctx, workspace.TemplateID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to fetch template version rollout", slog.Error(err))
+		return
+	}
+	if rollout.Status != database.TemplateVersionRolloutStatusInProgress || rollout.TemplateVersionID != build.TemplateVersionID {
+		return
+	}
+
+	stats, err := s.Database.GetTemplateVersionRolloutBuildStats(ctx, rollout.TemplateID)
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to fetch template version rollout build stats", slog.Error(err))
+		return
+	}
+	if stats.TotalBuilds < int64(rollout.MinimumBuilds) ||
+		stats.FailedBuilds*100 <= stats.TotalBuilds*int64(rollout.FailureThresholdPercent) {
+		return
+	}
+
+	rollout, err = s.Database.HaltTemplateVersionRollout(ctx, database.HaltTemplateVersionRolloutParams{
+		UpdatedAt:         s.timeNow(),
+		TemplateID:        rollout.TemplateID,
+		TemplateVersionID: rollout.TemplateVersionID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Another failed build already halted the rollout.
+		return
+	}
+	if err != nil {
+		s.Logger.Error(ctx, "failed to halt template version rollout", slog.Error(err))
+		return
+	}
+	s.Logger.Info(ctx, "halted template version rollout",
+		slog.F("template_id", rollout.TemplateID),
+		slog.F("template_version_id", rollout.TemplateVersionID),
+		slog.F("failed_builds", stats.FailedBuilds),
+		slog.F("total_builds", stats.TotalBuilds),
+	)
+
+	s.notifyTemplateVersionRolloutHalted(ctx, workspace, build, rollout, stats)
+}
+
+func (s *server) notifyTemplateVersionRolloutHalted(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild, rollout database.TemplateVersionRollout, stats database.GetTemplateVersionRolloutBuildStatsRow) {
+	templateAdmins, template, templateVersion, _, err := s.prepareForNotifyWorkspaceManualBuildFailed(ctx, workspace, build)
+	if err != nil {
+		s.Logger.Error(ctx, "unable to collect data for template version rollout halted notification", slog.Error(err))
+		return
+	}
+	organization, err := s.Database.GetOrganizationByID(ctx, workspace.OrganizationID)
+	if err != nil {
+		s.Logger.Error(ctx, "unable to fetch organization for template version rollout halted notification", slog.Error(err))
+		return
+	}
+
+	for _, templateAdmin := range templateAdmins {
+		if _, err := s.NotificationsEnqueuer.Enqueue(ctx, templateAdmin.ID, notifications.TemplateTemplateVersionRolloutHalted,
+			map[string]string{
+				"template":          template.Name,
+				"version":           templateVersion.Name,
+				"organization":      organization.Name,
+				"failed_builds":     strconv.FormatInt(stats.FailedBuilds, 10),
+				"total_builds":      strconv.FormatInt(stats.TotalBuilds, 10),
+				"failure_threshold": strconv.Itoa(int(rollout.FailureThresholdPercent)),
+			}, "provisionerdserver",
+			// Associate this notification with all the related entities.
+			template.ID, templateVersion.ID, template.OrganizationID,
+		); err != nil {
+			s.Logger.Warn(ctx, "failed to notify of halted template version rollout", slog.Error(err))
+		}
+	}
+}
+
 func (s *server) notifyWorkspaceManualBuildFailed(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild) {
 	templateAdmins, template, templateVersion, workspaceOwner, err := s.prepareForNotifyWorkspaceManualBuildFailed(ctx, workspace, build)
 	if err != nil {
@@ -1478,6 +1637,7 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 		return nil, xerrors.Errorf("publish end of job logs: %w", err)
 	}
 
+	s.releaseJobSlot(ctx, job)
 	s.Logger.Debug(ctx, "stage CompleteJob done", slog.F("job_id", jobID))
 	return &proto.Empty{}, nil
 }
@@ -1491,6 +1651,38 @@ func (s *server) completeTemplateImportJob(ctx context.Context, job database.Pro
 		return xerrors.Errorf("template version ID is expected: %w", err)
 	}
 
+	// Evaluate the template version against the deployment's template
+	// policies. Violations fail the import job with actionable messages.
+	var policyErr error
+	if s.TemplatePolicy != nil {
+		templateVersion, err := s.Database.GetTemplateVersionByID(ctx, input.TemplateVersionID)
+		if err != nil {
+			return xerrors.Errorf("get template version: %w", err)
+		}
+		policyErr = s.TemplatePolicy.Evaluate(ctx, templatepolicy.Input{
+			OrganizationID:      job.OrganizationID,
+			TemplateID:          templateVersion.TemplateID,
+			TemplateVersionID:   templateVersion.ID,
+			TemplateVersionName: templateVersion.Name,
+			InitiatorID:         job.InitiatorID,
+			Tags:                job.Tags,
+			Plan:                jobType.TemplateImport.Plan,
+		})
+		if policyErr != nil {
+			s.Logger.Info(ctx, "template version rejected by template policies",
+				slog.F("job_id", jobID),
+				slog.F("template_version_id", templateVersion.ID),
+				slog.Error(policyErr))
+		}
+	}
+
+	// Organizations may restrict the external auth providers that their
+	// templates can require.
+	overrides, err := s.Database.GetOrganizationSettingOverrides(ctx, job.OrganizationID)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get organization setting overrides: %w", err)
+	}
+
 	// Execute all database operations in a transaction
 	return s.Database.InTx(func(db database.Store) error {
 		now := s.timeNow()
@@ -1619,6 +1811,19 @@ func (s *server) completeTemplateImportJob(ctx context.Context, job database.Pro
 				}
 				break
 			}
+			if overrides.AllowedExternalAuthProviders != nil && !slices.Contains(overrides.AllowedExternalAuthProviders, externalAuthProvider.Id) {
+				completedError = sql.NullString{
+					String: fmt.Sprintf("external auth provider %q is not allowed in this organization", externalAuthProvider.Id),
+					Valid:  true,
+				}
+				break
+			}
+		}
+		if !completedError.Valid && policyErr != nil {
+			completedError = sql.NullString{
+				String: policyErr.Error(),
+				Valid:  true,
+			}
 		}
 
 		// Fallback to `ExternalAuthProvidersNames` if it was specified and `ExternalAuthProviders`
@@ -2052,6 +2257,10 @@ func (s *server) completeWorkspaceBuildJob(ctx context.Context, job database.Pro
 			BuildNumber:   strconv.FormatInt(int64(workspaceBuild.BuildNumber), 10),
 			BuildReason:   database.BuildReason(string(workspaceBuild.Reason)),
 			WorkspaceID:   workspace.ID,
+
+			BuildReasonMessage:  workspaceBuild.ReasonMessage,
+			InitiatorAPIKeyName: workspaceBuild.InitiatorAPIKeyName,
+			InitiatorCIJobURL:   workspaceBuild.InitiatorCIJobURL,
 		}
 
 		wriBytes, err := json.Marshal(buildResourceInfo)
@@ -2930,6 +3139,124 @@ func asVariableValues(templateVariables []database.TemplateVersionVariable) []*s
 	return apiVariableValues
 }
 
+// templateVariableSetValues returns the variable values of the variable sets
+// attached to a template by variable name. Sets are ordered by name, so if
+// several sets contain the same variable, the set whose name sorts last wins.
+func (s *server) templateVariableSetValues(ctx context.Context, templateID uuid.NullUUID) (map[string]database.VariableSetVariable, error) {
+	if !templateID.Valid {
+		return nil, nil
+	}
+	sets, err := s.Database.GetVariableSetsByTemplateID(ctx, templateID.UUID)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]database.VariableSetVariable)
+	for _, set := range sets {
+		vars, err := database.ParseVariableSetVariables(set.Variables)
+		if err != nil {
+			return nil, xerrors.Errorf("variable set %q: %w", set.Name, err)
+		}
+		for _, v := range vars {
+			values[v.Name] = v
+		}
+	}
+	return values, nil
+}
+
+// applyVariableSets overrides the values of declared template variables with
+// the values of the variable sets attached to the template. Like template
+// secrets, values that do not match a declared variable are skipped.
+func applyVariableSets(values []*sdkproto.VariableValue, templateVariables []database.TemplateVersionVariable, setValues map[string]database.VariableSetVariable) []*sdkproto.VariableValue {
+	for _, templateVariable := range templateVariables {
+		setValue, ok := setValues[templateVariable.Name]
+		if !ok {
+			continue
+		}
+		idx := slices.IndexFunc(values, func(v *sdkproto.VariableValue) bool {
+			return v.Name == templateVariable.Name
+		})
+		if idx >= 0 {
+			values[idx].Value = setValue.Value
+			values[idx].Sensitive = values[idx].Sensitive || setValue.Secret
+			continue
+		}
+		values = append(values, &sdkproto.VariableValue{
+			Name:      templateVariable.Name,
+			Value:     setValue.Value,
+			Sensitive: templateVariable.Sensitive || setValue.Secret,
+		})
+	}
+	return values
+}
+
+// applyTemplateSecrets overrides the values of declared template variables
+// with the template secrets of the same name. Secrets that do not match a
+// declared variable are skipped, as Terraform rejects undeclared variables.
+// The secrets that were applied are returned so their reads can be audited.
+func applyTemplateSecrets(values []*sdkproto.VariableValue, templateVariables []database.TemplateVersionVariable, secrets []database.TemplateSecret) ([]*sdkproto.VariableValue, []database.TemplateSecret) {
+	declared := make(map[string]struct{}, len(templateVariables))
+	for _, v := range templateVariables {
+		declared[v.Name] = struct{}{}
+	}
+
+	var applied []database.TemplateSecret
+	for _, secret := range secrets {
+		if _, ok := declared[secret.Name]; !ok {
+			continue
+		}
+		applied = append(applied, secret)
+		idx := slices.IndexFunc(values, func(v *sdkproto.VariableValue) bool {
+			return v.Name == secret.Name
+		})
+		if idx >= 0 {
+			values[idx].Value = secret.Value
+			values[idx].Sensitive = true
+			continue
+		}
+		values = append(values, &sdkproto.VariableValue{
+			Name:      secret.Name,
+			Value:     secret.Value,
+			Sensitive: true,
+		})
+	}
+	return values, applied
+}
+
+// auditTemplateSecretReads records that the given template secrets were
+// read to provision a workspace build.
+func (s *server) auditTemplateSecretReads(ctx context.Context, job database.ProvisionerJob, workspace database.Workspace, secrets []database.TemplateSecret) {
+	if len(secrets) == 0 {
+		return
+	}
+	additionalFields, err := json.Marshal(audit.AdditionalFields{
+		WorkspaceName:  workspace.Name,
+		WorkspaceOwner: workspace.OwnerUsername,
+		WorkspaceID:    workspace.ID,
+	})
+	if err != nil {
+		s.Logger.Error(ctx, "marshal additional fields for template secret read", slog.Error(err))
+		additionalFields = []byte("{}")
+	}
+	auditor := s.Auditor.Load()
+	bag := audit.BaggageFromContext(ctx)
+	for _, secret := range secrets {
+		audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.TemplateSecret]{
+			Audit:            *auditor,
+			Log:              s.Logger,
+			UserID:           job.InitiatorID,
+			OrganizationID:   workspace.OrganizationID,
+			RequestID:        job.ID,
+			IP:               bag.IP,
+			Time:             s.timeNow(),
+			Status:           http.StatusOK,
+			Action:           database.AuditActionRead,
+			Old:              secret,
+			New:              secret,
+			AdditionalFields: additionalFields,
+		})
+	}
+}
+
 func redactTemplateVariable(templateVariable *sdkproto.TemplateVariable) *sdkproto.TemplateVariable {
 	if templateVariable == nil {
 		return nil
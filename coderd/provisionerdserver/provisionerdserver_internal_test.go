@@ -13,6 +13,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -71,3 +72,74 @@ func TestObtainOIDCAccessToken(t *testing.T) {
 		require.Equal(t, "token", link.OAuthAccessToken)
 	})
 }
+
+func TestApplyTemplateSecrets(t *testing.T) {
+	t.Parallel()
+
+	templateVariables := []database.TemplateVersionVariable{
+		{Name: "region", Value: "us-east-1"},
+		{Name: "api_token", Required: true},
+		{Name: "db_password", DefaultValue: "changeme"},
+	}
+	values := asVariableValues(templateVariables)
+	secrets := []database.TemplateSecret{
+		{Name: "api_token", Value: "token"},
+		{Name: "db_password", Value: "hunter2"},
+		{Name: "undeclared", Value: "ignored"},
+	}
+
+	values, applied := applyTemplateSecrets(values, templateVariables, secrets)
+	require.Len(t, applied, 2)
+	require.Equal(t, "api_token", applied[0].Name)
+	require.Equal(t, "db_password", applied[1].Name)
+
+	byName := make(map[string]*sdkproto.VariableValue, len(values))
+	for _, v := range values {
+		byName[v.Name] = v
+	}
+	require.Len(t, byName, 3)
+	require.Equal(t, "us-east-1", byName["region"].Value)
+	require.False(t, byName["region"].Sensitive)
+	require.Equal(t, "token", byName["api_token"].Value)
+	require.True(t, byName["api_token"].Sensitive)
+	require.Equal(t, "hunter2", byName["db_password"].Value)
+	require.True(t, byName["db_password"].Sensitive)
+	require.NotContains(t, byName, "undeclared")
+}
+
+func TestApplyVariableSets(t *testing.T) {
+	t.Parallel()
+
+	templateVariables := []database.TemplateVersionVariable{
+		{Name: "region", Value: "us-east-1"},
+		{Name: "vpc_id", Required: true},
+		{Name: "db_password", DefaultValue: "changeme"},
+		{Name: "subnet_id", Value: "subnet-template"},
+	}
+	values := asVariableValues(templateVariables)
+	setValues := map[string]database.VariableSetVariable{
+		"vpc_id":      {Name: "vpc_id", Value: "vpc-123"},
+		"db_password": {Name: "db_password", Value: "hunter2", Secret: true},
+		"subnet_id":   {Name: "subnet_id", Value: "subnet-shared"},
+		"undeclared":  {Name: "undeclared", Value: "ignored"},
+	}
+
+	values = applyVariableSets(values, templateVariables, setValues)
+	// Template secrets take precedence over variable sets.
+	values, _ = applyTemplateSecrets(values, templateVariables, []database.TemplateSecret{
+		{Name: "subnet_id", Value: "subnet-secret"},
+	})
+
+	byName := make(map[string]*sdkproto.VariableValue, len(values))
+	for _, v := range values {
+		byName[v.Name] = v
+	}
+	require.Len(t, byName, 4)
+	require.Equal(t, "us-east-1", byName["region"].Value)
+	require.Equal(t, "vpc-123", byName["vpc_id"].Value)
+	require.False(t, byName["vpc_id"].Sensitive)
+	require.Equal(t, "hunter2", byName["db_password"].Value)
+	require.True(t, byName["db_password"].Sensitive)
+	require.Equal(t, "subnet-secret", byName["subnet_id"].Value)
+	require.NotContains(t, byName, "undeclared")
+}
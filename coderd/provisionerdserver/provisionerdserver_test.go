@@ -44,6 +44,7 @@ import (
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/coderd/telemetry"
+	"github.com/coder/coder/v2/coderd/templatepolicy"
 	"github.com/coder/coder/v2/coderd/wspubsub"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
@@ -1626,6 +1627,158 @@ func TestCompleteJob(t *testing.T) {
 		require.False(t, job.Error.Valid)
 	})
 
+	t.Run("TemplateImport_GitAuthNotAllowed", func(t *testing.T) {
+		t.Parallel()
+		srv, db, _, pd := setup(t, false, &overrides{
+			externalAuthConfigs: []*externalauth.Config{{
+				ID: "github",
+			}},
+		})
+		_, err := db.UpsertOrganizationSettingOverrides(ctx, database.UpsertOrganizationSettingOverridesParams{
+			OrganizationID:                  pd.OrganizationID,
+			AllowedExternalAuthProviders:    []string{"gitlab"},
+			DisabledNotificationTemplateIDs: []uuid.UUID{},
+			UpdatedAt:                       dbtime.Now(),
+		})
+		require.NoError(t, err)
+		jobID := uuid.New()
+		versionID := uuid.New()
+		err = db.InsertTemplateVersion(ctx, database.InsertTemplateVersionParams{
+			ID:             versionID,
+			JobID:          jobID,
+			OrganizationID: pd.OrganizationID,
+		})
+		require.NoError(t, err)
+		job, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+			OrganizationID: pd.OrganizationID,
+			ID:             jobID,
+			Provisioner:    database.ProvisionerTypeEcho,
+			Input:          []byte(`{"template_version_id": "` + versionID.String() + `"}`),
+			StorageMethod:  database.ProvisionerStorageMethodFile,
+			Type:           database.ProvisionerJobTypeWorkspaceBuild,
+			Tags:           pd.Tags,
+		})
+		require.NoError(t, err)
+		_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			OrganizationID: pd.OrganizationID,
+			WorkerID: uuid.NullUUID{
+				UUID:  pd.ID,
+				Valid: true,
+			},
+			Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+			StartedAt: sql.NullTime{
+				Time:  dbtime.Now(),
+				Valid: true,
+			},
+			ProvisionerTags: must(json.Marshal(job.Tags)),
+		})
+		require.NoError(t, err)
+		completeJob := func() {
+			_, err = srv.CompleteJob(ctx, &proto.CompletedJob{
+				JobId: job.ID.String(),
+				Type: &proto.CompletedJob_TemplateImport_{
+					TemplateImport: &proto.CompletedJob_TemplateImport{
+						StartResources: []*sdkproto.Resource{{
+							Name: "hello",
+							Type: "aws_instance",
+						}},
+						StopResources:         []*sdkproto.Resource{},
+						ExternalAuthProviders: []*sdkproto.ExternalAuthProviderResource{{Id: "github"}},
+						Plan:                  []byte("{}"),
+					},
+				},
+			})
+			require.NoError(t, err)
+		}
+		completeJob()
+		job, err = db.GetProvisionerJobByID(ctx, job.ID)
+		require.NoError(t, err)
+		require.Contains(t, job.Error.String, `external auth provider "github" is not allowed in this organization`)
+	})
+
+	t.Run("TemplateImport_TemplatePolicy", func(t *testing.T) {
+		t.Parallel()
+		engine, err := templatepolicy.New(ctx, map[string]string{"policy.rego": `package coder.templates
+
+deny contains msg if {
+	some r in input.plan.resource_changes
+	r.change.after.associate_public_ip_address
+	msg := sprintf("%s must not have a public IP address", [r.address])
+}
+`})
+		require.NoError(t, err)
+		srv, db, _, pd := setup(t, false, &overrides{
+			templatePolicy: engine,
+		})
+
+		for _, tc := range []struct {
+			name  string
+			plan  string
+			error string
+		}{{
+			name: "Allowed",
+			plan: `{"resource_changes":[{"address":"aws_instance.dev","change":{"after":{"associate_public_ip_address":false}}}]}`,
+		}, {
+			name:  "Denied",
+			plan:  `{"resource_changes":[{"address":"aws_instance.dev","change":{"after":{"associate_public_ip_address":true}}}]}`,
+			error: "aws_instance.dev must not have a public IP address",
+		}} {
+			jobID := uuid.New()
+			versionID := uuid.New()
+			err := db.InsertTemplateVersion(ctx, database.InsertTemplateVersionParams{
+				ID:             versionID,
+				JobID:          jobID,
+				OrganizationID: pd.OrganizationID,
+			})
+			require.NoError(t, err)
+			job, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+				OrganizationID: pd.OrganizationID,
+				ID:             jobID,
+				Provisioner:    database.ProvisionerTypeEcho,
+				Input:          []byte(`{"template_version_id": "` + versionID.String() + `"}`),
+				StorageMethod:  database.ProvisionerStorageMethodFile,
+				Type:           database.ProvisionerJobTypeTemplateVersionImport,
+				Tags:           pd.Tags,
+			})
+			require.NoError(t, err)
+			_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+				OrganizationID: pd.OrganizationID,
+				WorkerID: uuid.NullUUID{
+					UUID:  pd.ID,
+					Valid: true,
+				},
+				Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+				StartedAt: sql.NullTime{
+					Time:  dbtime.Now(),
+					Valid: true,
+				},
+				ProvisionerTags: must(json.Marshal(job.Tags)),
+			})
+			require.NoError(t, err)
+			_, err = srv.CompleteJob(ctx, &proto.CompletedJob{
+				JobId: job.ID.String(),
+				Type: &proto.CompletedJob_TemplateImport_{
+					TemplateImport: &proto.CompletedJob_TemplateImport{
+						StartResources: []*sdkproto.Resource{{
+							Name: "dev",
+							Type: "aws_instance",
+						}},
+						StopResources: []*sdkproto.Resource{},
+						Plan:          []byte(tc.plan),
+					},
+				},
+			})
+			require.NoError(t, err, tc.name)
+			job, err = db.GetProvisionerJobByID(ctx, job.ID)
+			require.NoError(t, err, tc.name)
+			if tc.error == "" {
+				require.False(t, job.Error.Valid, tc.name)
+				continue
+			}
+			require.Contains(t, job.Error.String, tc.error, tc.name)
+		}
+	})
+
 	t.Run("WorkspaceBuild", func(t *testing.T) {
 		t.Parallel()
 
@@ -3580,6 +3733,7 @@ type overrides struct {
 	ctx                         context.Context
 	deploymentValues            *codersdk.DeploymentValues
 	externalAuthConfigs         []*externalauth.Config
+	templatePolicy              *templatepolicy.Engine
 	templateScheduleStore       *atomic.Pointer[schedule.TemplateScheduleStore]
 	userQuietHoursScheduleStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
 	clock                       *quartz.Mock
@@ -3698,6 +3852,7 @@ func setup(t *testing.T, ignoreLogErrors bool, ov *overrides) (proto.DRPCProvisi
 		deploymentValues,
 		provisionerdserver.Options{
 			ExternalAuthConfigs:   externalAuthConfigs,
+			TemplatePolicy:        ov.templatePolicy,
 			Clock:                 clock,
 			OIDCConfig:            &oauth2.Config{},
 			AcquireJobLongPollDur: pollDur,
@@ -23,6 +23,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpmw/loggermw"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/searchquery"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/wsjson"
@@ -89,6 +90,80 @@ func (api *API) provisionerJobs(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(jobs, convertProvisionerJobWithQueuePosition))
 }
 
+// @Summary Search provisioner job logs
+// @ID search-provisioner-job-logs
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param q query string true "Search query"
+// @Param limit query int false "Page limit"
+// @Success 200 {array} codersdk.ProvisionerJobLogMatch
+// @Router /organizations/{organization}/provisionerjobs/logs [get]
+func (api *API) searchProvisionerJobLogs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	// Like provisioner jobs, only owners and template admins can search
+	// their logs.
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceProvisionerJobs.InOrg(org.ID)) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	qp := r.URL.Query()
+	p := httpapi.NewQueryParamParser()
+	query := p.String(qp, "", "q")
+	limit := p.PositiveInt32(qp, 100, "limit")
+	p.ErrorExcessParams(qp)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: p.Errors,
+		})
+		return
+	}
+	filter, errs := searchquery.ProvisionerJobLogs(ctx, api.Database, org.ID, query)
+	if len(errs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid search query.",
+			Validations: errs,
+		})
+		return
+	}
+	filter.LimitOpt = limit
+
+	rows, err := api.Database.SearchProvisionerJobLogs(ctx, filter)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error searching provisioner job logs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	matches := make([]codersdk.ProvisionerJobLogMatch, 0, len(rows))
+	for _, row := range rows {
+		matches = append(matches, codersdk.ProvisionerJobLogMatch{
+			Log:               convertProvisionerJobLog(row.ProvisionerJobLog),
+			JobID:             row.ProvisionerJobLog.JobID,
+			JobType:           codersdk.ProvisionerJobType(row.JobType),
+			WorkspaceID:       nilIfNilUUID(row.WorkspaceID),
+			WorkspaceBuildID:  nilIfNilUUID(row.WorkspaceBuildID),
+			TemplateID:        nilIfNilUUID(row.TemplateID),
+			TemplateVersionID: nilIfNilUUID(row.TemplateVersionID),
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, matches)
+}
+
+func nilIfNilUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
+
 // handleAuthAndFetchProvisionerJobs is an internal method shared by
 // provisionerJob and provisionerJobs. If ok is false the caller should
 // return immediately because the response has already been written.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"net/http"
 	"strconv"
 	"testing"
 	"time"
@@ -287,6 +288,86 @@ func TestProvisionerJobs(t *testing.T) {
 	})
 }
 
+func TestSearchProvisionerJobLogs(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t)
+	client := coderdtest.New(t, &coderdtest.Options{
+		Database: db,
+		Pubsub:   ps,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	templateAdminClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.ScopedRoleOrgTemplateAdmin(owner.OrganizationID))
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	insertLogs := func(jobType database.ProvisionerJobType, output ...string) database.ProvisionerJob {
+		job := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: owner.OrganizationID,
+			Type:           jobType,
+		})
+		params := database.InsertProvisionerJobLogsParams{JobID: job.ID}
+		for _, line := range output {
+			params.CreatedAt = append(params.CreatedAt, dbtime.Now())
+			params.Source = append(params.Source, database.LogSourceProvisioner)
+			params.Level = append(params.Level, database.LogLevelError)
+			params.Stage = append(params.Stage, "Planning infrastructure")
+			params.Output = append(params.Output, line)
+		}
+		_, err := db.InsertProvisionerJobLogs(context.Background(), params)
+		require.NoError(t, err)
+		return job
+	}
+	build := insertLogs(database.ProvisionerJobTypeWorkspaceBuild, "Error: dial tcp 10.0.0.1:443: connection refused", "Apply complete!")
+	imported := insertLogs(database.ProvisionerJobTypeTemplateVersionImport, "Error: connection refused by registry")
+	_ = insertLogs(database.ProvisionerJobTypeWorkspaceBuild, "Error: quota exceeded")
+
+	t.Run("Phrase", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		matches, err := templateAdminClient.SearchProvisionerJobLogs(ctx, owner.OrganizationID, codersdk.SearchProvisionerJobLogsRequest{
+			SearchQuery: `"connection refused"`,
+		})
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+		require.ElementsMatch(t, []uuid.UUID{build.ID, imported.ID}, []uuid.UUID{matches[0].JobID, matches[1].JobID})
+	})
+
+	t.Run("Type", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		matches, err := templateAdminClient.SearchProvisionerJobLogs(ctx, owner.OrganizationID, codersdk.SearchProvisionerJobLogsRequest{
+			SearchQuery: "refused type:workspace_build",
+		})
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		require.Equal(t, build.ID, matches[0].JobID)
+		require.Equal(t, codersdk.ProvisionerJobTypeWorkspaceBuild, matches[0].JobType)
+		require.Contains(t, matches[0].Log.Output, "connection refused")
+	})
+
+	t.Run("NoTerms", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		_, err := templateAdminClient.SearchProvisionerJobLogs(ctx, owner.OrganizationID, codersdk.SearchProvisionerJobLogsRequest{
+			SearchQuery: "type:workspace_build",
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+	})
+
+	t.Run("MemberDenied", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		_, err := memberClient.SearchProvisionerJobLogs(ctx, owner.OrganizationID, codersdk.SearchProvisionerJobLogsRequest{
+			SearchQuery: "refused",
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+	})
+}
+
 func TestProvisionerJobLogs(t *testing.T) {
 	t.Parallel()
 	t.Run("StreamAfterComplete", func(t *testing.T) {
@@ -0,0 +1,388 @@
+package coderd
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// regionProbeSamples is the number of requests clients send to every
+	// region. A few samples smooth out the connection setup of the first.
+	regionProbeSamples = 3
+	// regionProbeTimeout bounds every probe request. Regions this slow are
+	// not worth selecting.
+	regionProbeTimeout = 5 * time.Second
+	// regionLatencyTTL is how long a reported latency is used for selection.
+	// Clients move between networks, so old measurements are ignored in favor
+	// of server-side health.
+	regionLatencyTTL = 24 * time.Hour
+)
+
+// AppRegions returns the regions that serve workspace apps and terminals.
+func (api *API) AppRegions(ctx context.Context) ([]codersdk.Region, error) {
+	if fn := api.RegionsFn.Load(); fn != nil {
+		return (*fn)(ctx)
+	}
+	//nolint:gocritic // Regions are shown to every user.
+	region, err := api.PrimaryRegion(dbauthz.AsSystemRestricted(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return []codersdk.Region{region}, nil
+}
+
+// @Summary Get region probe plan
+// @Description Returns the healthy regions clients should measure their
+// @Description latency to before reporting it for proxy selection.
+// @ID get-region-probe-plan
+// @Security CoderSessionToken
+// @Produce json
+// @Tags WorkspaceProxies
+// @Success 200 {object} codersdk.RegionProbePlan
+// @Router /regions/probe-plan [get]
+func (api *API) regionProbePlan(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	regions, err := api.AppRegions(ctx)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	plan := codersdk.RegionProbePlan{
+		Probes:    []codersdk.RegionProbe{},
+		Samples:   regionProbeSamples,
+		TimeoutMS: regionProbeTimeout.Milliseconds(),
+	}
+	for _, region := range regions {
+		if !region.Healthy || region.PathAppURL == "" {
+			continue
+		}
+		plan.Probes = append(plan.Probes, codersdk.RegionProbe{
+			RegionID:   region.ID,
+			RegionName: region.Name,
+			URL:        strings.TrimSuffix(region.PathAppURL, "/") + "/latency-check",
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, plan)
+}
+
+// @Summary Get user proxy preference
+// @Description Returns the region the user's workspace app and terminal
+// @Description traffic should use, and why it was selected.
+// @ID get-user-proxy-preference
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.UserProxyPreference
+// @Router /users/{user}/proxy-preference [get]
+func (api *API) userProxyPreference(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	pref, err := api.readUserProxyPreference(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Error reading user proxy preference.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, pref)
+}
+
+// @Summary Update user proxy preference
+// @Description Pins the region used for the user's workspace app and
+// @Description terminal traffic. A null region selects it automatically.
+// @ID update-user-proxy-preference
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.UpdateUserProxyPreferenceRequest true "New preference"
+// @Success 200 {object} codersdk.UserProxyPreference
+// @Router /users/{user}/proxy-preference [put]
+func (api *API) putUserProxyPreference(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	var req codersdk.UpdateUserProxyPreferenceRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	value := ""
+	if req.PreferredRegionID != nil {
+		regions, err := api.AppRegions(ctx)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		if !slices.ContainsFunc(regions, func(region codersdk.Region) bool { return region.ID == *req.PreferredRegionID }) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Region %q does not exist.", *req.PreferredRegionID),
+				Validations: []codersdk.ValidationError{
+					{Field: "preferred_region_id", Detail: "must be the ID of a region"},
+				},
+			})
+			return
+		}
+		value = req.PreferredRegionID.String()
+	}
+
+	_, err := api.Database.UpdateUserProxyPreference(ctx, database.UpdateUserProxyPreferenceParams{
+		UserID:          user.ID,
+		ProxyPreference: value,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Error updating user proxy preference.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	pref, err := api.readUserProxyPreference(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Error reading user proxy preference.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, pref)
+}
+
+// @Summary Report region latencies
+// @Description Stores the latencies the user's client measured with the
+// @Description region probe plan, replacing the previous report.
+// @ID report-region-latencies
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.ReportRegionLatenciesRequest true "Measured latencies"
+// @Success 200 {object} codersdk.UserProxyPreference
+// @Router /users/{user}/proxy-preference/latencies [post]
+func (api *API) postUserProxyLatencies(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	var req codersdk.ReportRegionLatenciesRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	regions, err := api.AppRegions(ctx)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	var (
+		now         = dbtime.Now()
+		latencies   = make([]codersdk.RegionLatency, 0, len(req.Latencies))
+		validations []codersdk.ValidationError
+	)
+	for i, latency := range req.Latencies {
+		if latency.LatencyMS < 0 {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  fmt.Sprintf("latencies[%d].latency_ms", i),
+				Detail: "must not be negative",
+			})
+			continue
+		}
+		// Regions may have been deleted since the plan was served.
+		if !slices.ContainsFunc(regions, func(region codersdk.Region) bool { return region.ID == latency.RegionID }) {
+			continue
+		}
+		latency.MeasuredAt = now
+		latencies = append(latencies, latency)
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid region latencies.",
+			Validations: validations,
+		})
+		return
+	}
+
+	raw, err := json.Marshal(latencies)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	_, err = api.Database.UpdateUserProxyLatencies(ctx, database.UpdateUserProxyLatenciesParams{
+		UserID:         user.ID,
+		ProxyLatencies: string(raw),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Error updating region latencies.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	pref, err := api.readUserProxyPreference(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Error reading user proxy preference.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, pref)
+}
+
+// UserRegionLatencies returns the latencies the user's client reported that
+// are recent enough to select a region with.
+func (api *API) UserRegionLatencies(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]time.Duration, error) {
+	latencies, err := api.userRegionLatencies(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	hints := make(map[uuid.UUID]time.Duration, len(latencies))
+	for _, latency := range freshRegionLatencies(latencies, dbtime.Now()) {
+		hints[latency.RegionID] = time.Duration(latency.LatencyMS * float64(time.Millisecond))
+	}
+	return hints, nil
+}
+
+func (api *API) userRegionLatencies(ctx context.Context, userID uuid.UUID) ([]codersdk.RegionLatency, error) {
+	raw, err := api.Database.GetUserProxyLatencies(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return []codersdk.RegionLatency{}, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("get user proxy latencies: %w", err)
+	}
+	var latencies []codersdk.RegionLatency
+	if err := json.Unmarshal([]byte(raw), &latencies); err != nil {
+		// The latencies are only advisory, so a bad value must not break
+		// the preference.
+		api.Logger.Warn(ctx, "invalid user proxy latencies", slog.F("user_id", userID), slog.Error(err))
+		return []codersdk.RegionLatency{}, nil
+	}
+	return latencies, nil
+}
+
+func (api *API) readUserProxyPreference(ctx context.Context, userID uuid.UUID) (codersdk.UserProxyPreference, error) {
+	pref := codersdk.UserProxyPreference{}
+	raw, err := api.Database.GetUserProxyPreference(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return codersdk.UserProxyPreference{}, xerrors.Errorf("get user proxy preference: %w", err)
+	}
+	if id, err := uuid.Parse(raw); err == nil {
+		pref.PreferredRegionID = &id
+	}
+
+	pref.Latencies, err = api.userRegionLatencies(ctx, userID)
+	if err != nil {
+		return codersdk.UserProxyPreference{}, err
+	}
+
+	regions, err := api.AppRegions(ctx)
+	if err != nil {
+		return codersdk.UserProxyPreference{}, xerrors.Errorf("get regions: %w", err)
+	}
+	pref.SelectedRegionID, pref.Reason = selectProxyRegion(regions, pref.PreferredRegionID, pref.Latencies, dbtime.Now())
+	return pref, nil
+}
+
+// selectProxyRegion picks the region for workspace app and terminal traffic.
+// A pinned region wins while it is healthy, then the healthy region with the
+// lowest recent latency, then the primary region.
+func selectProxyRegion(regions []codersdk.Region, preferred *uuid.UUID, latencies []codersdk.RegionLatency, now time.Time) (uuid.UUID, codersdk.ProxySelectionReason) {
+	healthy := make(map[uuid.UUID]codersdk.Region, len(regions))
+	for _, region := range regions {
+		if region.Healthy {
+			healthy[region.ID] = region
+		}
+	}
+
+	if preferred != nil {
+		if _, ok := healthy[*preferred]; ok {
+			return *preferred, codersdk.ProxySelectionReasonPreference
+		}
+	}
+
+	var best *codersdk.RegionLatency
+	for _, latency := range freshRegionLatencies(latencies, now) {
+		if _, ok := healthy[latency.RegionID]; !ok {
+			continue
+		}
+		if best == nil || latency.LatencyMS < best.LatencyMS {
+			best = &latency
+		}
+	}
+	if best != nil {
+		return best.RegionID, codersdk.ProxySelectionReasonLatency
+	}
+
+	candidates := make([]codersdk.Region, 0, len(healthy))
+	for _, region := range healthy {
+		candidates = append(candidates, region)
+	}
+	if len(candidates) == 0 {
+		return uuid.Nil, codersdk.ProxySelectionReasonHealth
+	}
+	region := slices.MinFunc(candidates, func(a, b codersdk.Region) int {
+		if aPrimary, bPrimary := a.Name == "primary", b.Name == "primary"; aPrimary != bPrimary {
+			if aPrimary {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return region.ID, codersdk.ProxySelectionReasonHealth
+}
+
+func freshRegionLatencies(latencies []codersdk.RegionLatency, now time.Time) []codersdk.RegionLatency {
+	fresh := make([]codersdk.RegionLatency, 0, len(latencies))
+	for _, latency := range latencies {
+		if now.Sub(latency.MeasuredAt) <= regionLatencyTTL {
+			fresh = append(fresh, latency)
+		}
+	}
+	return fresh
+}
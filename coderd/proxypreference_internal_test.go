@@ -0,0 +1,91 @@
+package coderd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func Test_selectProxyRegion(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now         = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		primaryID   = uuid.New()
+		nearID      = uuid.New()
+		farID       = uuid.New()
+		unhealthyID = uuid.New()
+		regions     = []codersdk.Region{
+			{ID: primaryID, Name: "primary", Healthy: true},
+			{ID: nearID, Name: "near", Healthy: true},
+			{ID: farID, Name: "far", Healthy: true},
+			{ID: unhealthyID, Name: "down", Healthy: false},
+		}
+		latencies = []codersdk.RegionLatency{
+			{RegionID: primaryID, LatencyMS: 80, MeasuredAt: now},
+			{RegionID: nearID, LatencyMS: 20, MeasuredAt: now},
+			{RegionID: farID, LatencyMS: 150, MeasuredAt: now},
+			{RegionID: unhealthyID, LatencyMS: 5, MeasuredAt: now},
+		}
+	)
+
+	for _, tc := range []struct {
+		name      string
+		preferred *uuid.UUID
+		latencies []codersdk.RegionLatency
+		id        uuid.UUID
+		reason    codersdk.ProxySelectionReason
+	}{
+		{
+			name:   "NoLatencies",
+			id:     primaryID,
+			reason: codersdk.ProxySelectionReasonHealth,
+		},
+		{
+			name:      "LowestHealthyLatency",
+			latencies: latencies,
+			id:        nearID,
+			reason:    codersdk.ProxySelectionReasonLatency,
+		},
+		{
+			name:      "Preference",
+			preferred: &farID,
+			latencies: latencies,
+			id:        farID,
+			reason:    codersdk.ProxySelectionReasonPreference,
+		},
+		{
+			name:      "UnhealthyPreference",
+			preferred: &unhealthyID,
+			latencies: latencies,
+			id:        nearID,
+			reason:    codersdk.ProxySelectionReasonLatency,
+		},
+		{
+			name:      "DeletedPreference",
+			preferred: ptr.Ref(uuid.New()),
+			id:        primaryID,
+			reason:    codersdk.ProxySelectionReasonHealth,
+		},
+		{
+			name: "StaleLatencies",
+			latencies: []codersdk.RegionLatency{
+				{RegionID: farID, LatencyMS: 10, MeasuredAt: now.Add(-regionLatencyTTL - time.Minute)},
+			},
+			id:     primaryID,
+			reason: codersdk.ProxySelectionReasonHealth,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			id, reason := selectProxyRegion(regions, tc.preferred, tc.latencies, now)
+			require.Equal(t, tc.id, id)
+			require.Equal(t, tc.reason, reason)
+		})
+	}
+}
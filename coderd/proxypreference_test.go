@@ -0,0 +1,90 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserProxyPreference(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	plan, err := member.RegionProbePlan(ctx)
+	require.NoError(t, err)
+	require.Len(t, plan.Probes, 1)
+	primaryID := plan.Probes[0].RegionID
+	require.Equal(t, "primary", plan.Probes[0].RegionName)
+	require.Equal(t, client.URL.String()+"/latency-check", plan.Probes[0].URL)
+
+	// The measured latencies of the plan can be reported as they are.
+	latencies := codersdk.ProbeRegions(ctx, http.DefaultClient, plan)
+	require.Len(t, latencies, 1)
+
+	pref, err := member.UserProxyPreference(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Nil(t, pref.PreferredRegionID)
+	require.Empty(t, pref.Latencies)
+	require.Equal(t, primaryID, pref.SelectedRegionID)
+	require.Equal(t, codersdk.ProxySelectionReasonHealth, pref.Reason)
+
+	pref, err = member.ReportRegionLatencies(ctx, codersdk.Me, codersdk.ReportRegionLatenciesRequest{
+		Latencies: append(latencies, codersdk.RegionLatency{RegionID: uuid.New(), LatencyMS: 1}),
+	})
+	require.NoError(t, err)
+	// Unknown regions are dropped.
+	require.Len(t, pref.Latencies, 1)
+	require.False(t, pref.Latencies[0].MeasuredAt.IsZero())
+	require.Equal(t, primaryID, pref.SelectedRegionID)
+	require.Equal(t, codersdk.ProxySelectionReasonLatency, pref.Reason)
+
+	pref, err = member.UpdateUserProxyPreference(ctx, codersdk.Me, codersdk.UpdateUserProxyPreferenceRequest{
+		PreferredRegionID: &primaryID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, &primaryID, pref.PreferredRegionID)
+	require.Equal(t, codersdk.ProxySelectionReasonPreference, pref.Reason)
+
+	pref, err = member.UpdateUserProxyPreference(ctx, codersdk.Me, codersdk.UpdateUserProxyPreferenceRequest{})
+	require.NoError(t, err)
+	require.Nil(t, pref.PreferredRegionID)
+
+	t.Run("UnknownRegion", func(t *testing.T) {
+		t.Parallel()
+		id := uuid.New()
+		_, err := member.UpdateUserProxyPreference(ctx, codersdk.Me, codersdk.UpdateUserProxyPreferenceRequest{
+			PreferredRegionID: &id,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("NegativeLatency", func(t *testing.T) {
+		t.Parallel()
+		_, err := member.ReportRegionLatencies(ctx, codersdk.Me, codersdk.ReportRegionLatenciesRequest{
+			Latencies: []codersdk.RegionLatency{{RegionID: primaryID, LatencyMS: -1}},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		t.Parallel()
+		_, err := member.UserProxyPreference(ctx, owner.UserID.String())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
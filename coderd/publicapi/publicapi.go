@@ -0,0 +1,400 @@
+// Package publicapi implements the DRPC service in publicapi/proto. It serves
+// the read-heavy parts of the REST API to integrations that poll many
+// workspaces, streaming list results instead of paginating JSON.
+package publicapi
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"storj.io/drpc/drpcmux"
+	"storj.io/drpc/drpcserver"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/searchquery"
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/drpcsdk"
+	"github.com/coder/coder/v2/publicapi/proto"
+)
+
+const (
+	// DefaultPageSize is the number of rows fetched from the database at a
+	// time when streaming lists.
+	DefaultPageSize = 500
+	// MaxPageSize caps the page size requested by clients.
+	MaxPageSize = 5000
+	// DefaultStatsWindow is how far back agent stats are aggregated when the
+	// request does not say.
+	DefaultStatsWindow = 15 * time.Minute
+)
+
+type Options struct {
+	Log slog.Logger
+	// Database must authorize queries against the actor of the context, i.e.
+	// be wrapped with dbauthz.
+	Database                       database.Store
+	Authorizer                     rbac.Authorizer
+	AgentInactiveDisconnectTimeout time.Duration
+}
+
+// API implements proto.DRPCPublicAPIServer for the actor of the context the
+// server is served with.
+type API struct {
+	opts Options
+}
+
+var _ proto.DRPCPublicAPIServer = &API{}
+
+func New(opts Options) *API {
+	return &API{opts: opts}
+}
+
+// Serve serves the API on the listener until the context is canceled. The
+// context must carry the actor of the connection.
+func (a *API) Serve(ctx context.Context, l net.Listener) error {
+	mux := drpcmux.New()
+	err := proto.DRPCRegisterPublicAPI(mux, a)
+	if err != nil {
+		return xerrors.Errorf("register public API protocol in DRPC mux: %w", err)
+	}
+	server := drpcserver.NewWithOptions(&tracing.DRPCHandler{Handler: mux},
+		drpcserver.Options{
+			Manager: drpcsdk.DefaultDRPCOptions(nil),
+			Log: func(err error) {
+				if xerrors.Is(err, io.EOF) {
+					return
+				}
+				a.opts.Log.Debug(ctx, "drpc server error", slog.Error(err))
+			},
+		},
+	)
+	return server.Serve(ctx, l)
+}
+
+func (a *API) GetWorkspace(ctx context.Context, req *proto.GetWorkspaceRequest) (*proto.Workspace, error) {
+	id, err := uuid.FromBytes(req.Id)
+	if err != nil {
+		return nil, xerrors.Errorf("parse workspace id: %w", err)
+	}
+	rows, err := a.opts.Database.GetWorkspaces(ctx, database.GetWorkspacesParams{
+		WorkspaceIds:                          []uuid.UUID{id},
+		Limit:                                 1,
+		AgentInactiveDisconnectTimeoutSeconds: int64(a.opts.AgentInactiveDisconnectTimeout.Seconds()),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, xerrors.Errorf("workspace %s not found", id)
+	}
+	return convertWorkspace(rows[0]), nil
+}
+
+func (a *API) ListWorkspaces(req *proto.ListWorkspacesRequest, stream proto.DRPCPublicAPI_ListWorkspacesStream) error {
+	ctx := stream.Context()
+	actor, ok := dbauthz.ActorFromContext(ctx)
+	if !ok {
+		return xerrors.New("no actor in context")
+	}
+	filter, errs := searchquery.Workspaces(ctx, a.opts.Database, req.Query, codersdk.Pagination{}, a.opts.AgentInactiveDisconnectTimeout)
+	if len(errs) > 0 {
+		return xerrors.Errorf("invalid workspace search query: %s: %s", errs[0].Field, errs[0].Detail)
+	}
+	if filter.OwnerUsername == "me" {
+		ownerID, err := uuid.Parse(actor.ID)
+		if err != nil {
+			return xerrors.Errorf("parse actor id: %w", err)
+		}
+		filter.OwnerID = ownerID
+		filter.OwnerUsername = ""
+	}
+
+	return a.workspacePages(ctx, filter, req.PageSize, func(rows []database.GetWorkspacesRow) error {
+		for _, row := range rows {
+			if err := stream.Send(convertWorkspace(row)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// workspacePages calls fn with each page of the workspaces matching the
+// filter. Pages are fetched with offsets, so workspaces created or deleted
+// while paging may be skipped or sent twice.
+func (a *API) workspacePages(ctx context.Context, filter database.GetWorkspacesParams, pageSize int32, fn func([]database.GetWorkspacesRow) error) error {
+	pageSize = clampPageSize(pageSize)
+	filter.Limit = pageSize
+	filter.WithSummary = false
+	for offset := int32(0); ; offset += pageSize {
+		filter.Offset = offset
+		rows, err := a.opts.Database.GetWorkspaces(ctx, filter)
+		if err != nil {
+			return xerrors.Errorf("get workspaces: %w", err)
+		}
+		if len(rows) > 0 {
+			if err := fn(rows); err != nil {
+				return err
+			}
+		}
+		if int32(len(rows)) < pageSize {
+			return nil
+		}
+	}
+}
+
+func (a *API) ListWorkspaceBuilds(req *proto.ListWorkspaceBuildsRequest, stream proto.DRPCPublicAPI_ListWorkspaceBuildsStream) error {
+	ctx := stream.Context()
+	workspaceID, err := uuid.FromBytes(req.WorkspaceId)
+	if err != nil {
+		return xerrors.Errorf("parse workspace id: %w", err)
+	}
+	params := database.GetWorkspaceBuildsByWorkspaceIDParams{
+		WorkspaceID: workspaceID,
+		LimitOpt:    DefaultPageSize,
+	}
+	if req.Since != nil {
+		params.Since = req.Since.AsTime()
+	}
+	for {
+		builds, err := a.opts.Database.GetWorkspaceBuildsByWorkspaceID(ctx, params)
+		if err != nil {
+			return xerrors.Errorf("get workspace builds: %w", err)
+		}
+		for _, build := range builds {
+			if err := stream.Send(convertWorkspaceBuild(build)); err != nil {
+				return err
+			}
+		}
+		if len(builds) < DefaultPageSize {
+			return nil
+		}
+		params.OffsetOpt += DefaultPageSize
+	}
+}
+
+func (a *API) ListWorkspaceAgents(req *proto.ListWorkspaceAgentsRequest, stream proto.DRPCPublicAPI_ListWorkspaceAgentsStream) error {
+	ctx := stream.Context()
+	send := func(workspaceIDs []uuid.UUID) error {
+		agents, err := a.latestBuildAgents(ctx, workspaceIDs)
+		if err != nil {
+			return err
+		}
+		for _, agent := range agents {
+			if err := stream.Send(agent); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(req.WorkspaceIds) == 0 {
+		return a.workspacePages(ctx, database.GetWorkspacesParams{
+			AgentInactiveDisconnectTimeoutSeconds: int64(a.opts.AgentInactiveDisconnectTimeout.Seconds()),
+		}, DefaultPageSize, func(rows []database.GetWorkspacesRow) error {
+			workspaceIDs := make([]uuid.UUID, 0, len(rows))
+			for _, row := range rows {
+				workspaceIDs = append(workspaceIDs, row.ID)
+			}
+			return send(workspaceIDs)
+		})
+	}
+
+	workspaceIDs := make([]uuid.UUID, 0, len(req.WorkspaceIds))
+	for _, raw := range req.WorkspaceIds {
+		id, err := uuid.FromBytes(raw)
+		if err != nil {
+			return xerrors.Errorf("parse workspace id: %w", err)
+		}
+		// Fetching the workspace checks that the actor can read it.
+		if _, err := a.opts.Database.GetWorkspaceByID(ctx, id); err != nil {
+			return xerrors.Errorf("get workspace %s: %w", id, err)
+		}
+		workspaceIDs = append(workspaceIDs, id)
+	}
+	for len(workspaceIDs) > 0 {
+		n := min(len(workspaceIDs), DefaultPageSize)
+		if err := send(workspaceIDs[:n]); err != nil {
+			return err
+		}
+		workspaceIDs = workspaceIDs[n:]
+	}
+	return nil
+}
+
+// latestBuildAgents returns the agents of the latest builds of the
+// workspaces. The caller must have checked that the actor can read the
+// workspaces.
+func (a *API) latestBuildAgents(ctx context.Context, workspaceIDs []uuid.UUID) ([]*proto.WorkspaceAgent, error) {
+	// These queries have no workspace scoped authorization, and the
+	// workspaces have already been authorized.
+	//nolint:gocritic // See above.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	builds, err := a.opts.Database.GetLatestWorkspaceBuildsByWorkspaceIDs(sysCtx, workspaceIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get latest workspace builds: %w", err)
+	}
+	workspaceByJob := make(map[uuid.UUID]uuid.UUID, len(builds))
+	jobIDs := make([]uuid.UUID, 0, len(builds))
+	for _, build := range builds {
+		workspaceByJob[build.JobID] = build.WorkspaceID
+		jobIDs = append(jobIDs, build.JobID)
+	}
+	resources, err := a.opts.Database.GetWorkspaceResourcesByJobIDs(sysCtx, jobIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace resources: %w", err)
+	}
+	workspaceByResource := make(map[uuid.UUID]uuid.UUID, len(resources))
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		workspaceByResource[resource.ID] = workspaceByJob[resource.JobID]
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	agents, err := a.opts.Database.GetWorkspaceAgentsByResourceIDs(sysCtx, resourceIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace agents: %w", err)
+	}
+	converted := make([]*proto.WorkspaceAgent, 0, len(agents))
+	for _, agent := range agents {
+		converted = append(converted, convertWorkspaceAgent(agent, workspaceByResource[agent.ResourceID], a.opts.AgentInactiveDisconnectTimeout))
+	}
+	return converted, nil
+}
+
+func (a *API) ListWorkspaceAgentStats(req *proto.ListWorkspaceAgentStatsRequest, stream proto.DRPCPublicAPI_ListWorkspaceAgentStatsStream) error {
+	ctx := stream.Context()
+	actor, ok := dbauthz.ActorFromContext(ctx)
+	if !ok {
+		return xerrors.New("no actor in context")
+	}
+	// The stats of all agents are returned, so they are limited to those who
+	// can read the deployment stats.
+	err := a.opts.Authorizer.Authorize(ctx, actor, policy.ActionRead, rbac.ResourceDeploymentStats)
+	if err != nil {
+		return xerrors.Errorf("unauthorized: %w", err)
+	}
+
+	createdAfter := dbtime.Now().Add(-DefaultStatsWindow)
+	if req.CreatedAfter != nil {
+		createdAfter = req.CreatedAfter.AsTime()
+	}
+	stats, err := a.opts.Database.GetWorkspaceAgentStats(ctx, createdAfter)
+	if err != nil {
+		return xerrors.Errorf("get workspace agent stats: %w", err)
+	}
+	for _, stat := range stats {
+		if err := stream.Send(convertWorkspaceAgentStats(stat)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func clampPageSize(pageSize int32) int32 {
+	if pageSize <= 0 {
+		return DefaultPageSize
+	}
+	return min(pageSize, MaxPageSize)
+}
+
+func convertWorkspace(row database.GetWorkspacesRow) *proto.Workspace {
+	return &proto.Workspace{
+		Id:                     row.ID[:],
+		Name:                   row.Name,
+		CreatedAt:              timestamppb.New(row.CreatedAt),
+		UpdatedAt:              timestamppb.New(row.UpdatedAt),
+		OwnerId:                row.OwnerID[:],
+		OwnerUsername:          row.OwnerUsername,
+		OrganizationId:         row.OrganizationID[:],
+		OrganizationName:       row.OrganizationName,
+		TemplateId:             row.TemplateID[:],
+		TemplateName:           row.TemplateName,
+		TemplateVersionId:      row.TemplateVersionID[:],
+		LastUsedAt:             timestamppb.New(row.LastUsedAt),
+		DormantAt:              nullTime(row.DormantAt),
+		DeletingAt:             nullTime(row.DeletingAt),
+		LatestBuildTransition:  string(row.LatestBuildTransition),
+		LatestBuildStatus:      string(row.LatestBuildStatus),
+		LatestBuildCompletedAt: nullTime(row.LatestBuildCompletedAt),
+		LatestBuildError:       row.LatestBuildError.String,
+	}
+}
+
+func convertWorkspaceBuild(build database.WorkspaceBuild) *proto.WorkspaceBuild {
+	converted := &proto.WorkspaceBuild{
+		Id:                build.ID[:],
+		WorkspaceId:       build.WorkspaceID[:],
+		TemplateVersionId: build.TemplateVersionID[:],
+		BuildNumber:       build.BuildNumber,
+		Transition:        string(build.Transition),
+		Reason:            string(build.Reason),
+		InitiatorId:       build.InitiatorID[:],
+		InitiatorUsername: build.InitiatorByUsername,
+		JobId:             build.JobID[:],
+		CreatedAt:         timestamppb.New(build.CreatedAt),
+		DailyCost:         build.DailyCost,
+	}
+	if !build.Deadline.IsZero() {
+		converted.Deadline = timestamppb.New(build.Deadline)
+	}
+	if !build.MaxDeadline.IsZero() {
+		converted.MaxDeadline = timestamppb.New(build.MaxDeadline)
+	}
+	return converted
+}
+
+func convertWorkspaceAgent(agent database.WorkspaceAgent, workspaceID uuid.UUID, inactiveTimeout time.Duration) *proto.WorkspaceAgent {
+	return &proto.WorkspaceAgent{
+		Id:               agent.ID[:],
+		WorkspaceId:      workspaceID[:],
+		Name:             agent.Name,
+		Status:           string(agent.Status(inactiveTimeout).Status),
+		LifecycleState:   string(agent.LifecycleState),
+		Version:          agent.Version,
+		ApiVersion:       agent.APIVersion,
+		OperatingSystem:  agent.OperatingSystem,
+		Architecture:     agent.Architecture,
+		CreatedAt:        timestamppb.New(agent.CreatedAt),
+		FirstConnectedAt: nullTime(agent.FirstConnectedAt),
+		LastConnectedAt:  nullTime(agent.LastConnectedAt),
+		DisconnectedAt:   nullTime(agent.DisconnectedAt),
+	}
+}
+
+func convertWorkspaceAgentStats(stat database.GetWorkspaceAgentStatsRow) *proto.WorkspaceAgentStats {
+	return &proto.WorkspaceAgentStats{
+		AgentId:                     stat.AgentID[:],
+		WorkspaceId:                 stat.WorkspaceID[:],
+		TemplateId:                  stat.TemplateID[:],
+		UserId:                      stat.UserID[:],
+		AggregatedFrom:              timestamppb.New(stat.AggregatedFrom),
+		RxBytes:                     stat.WorkspaceRxBytes,
+		TxBytes:                     stat.WorkspaceTxBytes,
+		ConnectionLatencyP50Ms:      stat.WorkspaceConnectionLatency50,
+		ConnectionLatencyP95Ms:      stat.WorkspaceConnectionLatency95,
+		SessionCountVscode:          stat.SessionCountVSCode,
+		SessionCountSsh:             stat.SessionCountSSH,
+		SessionCountJetbrains:       stat.SessionCountJetBrains,
+		SessionCountReconnectingPty: stat.SessionCountReconnectingPTY,
+	}
+}
+
+func nullTime(t sql.NullTime) *timestamppb.Timestamp {
+	if !t.Valid {
+		return nil
+	}
+	return timestamppb.New(t.Time)
+}
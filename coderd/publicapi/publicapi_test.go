@@ -0,0 +1,174 @@
+package publicapi_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/coderd/publicapi"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk/drpcsdk"
+	"github.com/coder/coder/v2/publicapi/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestListWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	db := dbmock.NewMockStore(gomock.NewController(t))
+	rows := []database.GetWorkspacesRow{
+		{ID: uuid.New(), Name: "one", LatestBuildStatus: database.ProvisionerJobStatusSucceeded},
+		{ID: uuid.New(), Name: "two"},
+		{ID: uuid.New(), Name: "three"},
+	}
+	// Workspaces are fetched in pages until a short page is returned.
+	gomock.InOrder(
+		db.EXPECT().GetWorkspaces(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, arg database.GetWorkspacesParams) ([]database.GetWorkspacesRow, error) {
+			require.EqualValues(t, 2, arg.Limit)
+			require.EqualValues(t, 0, arg.Offset)
+			require.False(t, arg.WithSummary)
+			return rows[:2], nil
+		}),
+		db.EXPECT().GetWorkspaces(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, arg database.GetWorkspacesParams) ([]database.GetWorkspacesRow, error) {
+			require.EqualValues(t, 2, arg.Offset)
+			return rows[2:], nil
+		}),
+	)
+
+	client := serve(ctx, t, db, rbac.RoleIdentifiers{rbac.RoleMember()})
+	stream, err := client.ListWorkspaces(ctx, &proto.ListWorkspacesRequest{PageSize: 2})
+	require.NoError(t, err)
+	var names []string
+	for {
+		ws, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, ws.Name)
+		if ws.Name == "one" {
+			require.Equal(t, rows[0].ID[:], ws.Id)
+			require.Equal(t, string(database.ProvisionerJobStatusSucceeded), ws.LatestBuildStatus)
+			require.Nil(t, ws.LatestBuildCompletedAt)
+		}
+	}
+	require.Equal(t, []string{"one", "two", "three"}, names)
+}
+
+func TestListWorkspaceAgents(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	db := dbmock.NewMockStore(gomock.NewController(t))
+	workspaceID := uuid.New()
+	jobID := uuid.New()
+	resourceID := uuid.New()
+	agentID := uuid.New()
+
+	db.EXPECT().GetWorkspaceByID(gomock.Any(), workspaceID).Return(database.Workspace{ID: workspaceID}, nil)
+	db.EXPECT().GetLatestWorkspaceBuildsByWorkspaceIDs(gomock.Any(), []uuid.UUID{workspaceID}).Return([]database.WorkspaceBuild{
+		{WorkspaceID: workspaceID, JobID: jobID},
+	}, nil)
+	db.EXPECT().GetWorkspaceResourcesByJobIDs(gomock.Any(), []uuid.UUID{jobID}).Return([]database.WorkspaceResource{
+		{ID: resourceID, JobID: jobID},
+	}, nil)
+	db.EXPECT().GetWorkspaceAgentsByResourceIDs(gomock.Any(), []uuid.UUID{resourceID}).Return([]database.WorkspaceAgent{
+		{ID: agentID, ResourceID: resourceID, Name: "main", CreatedAt: time.Now()},
+	}, nil)
+
+	client := serve(ctx, t, db, rbac.RoleIdentifiers{rbac.RoleMember()})
+	stream, err := client.ListWorkspaceAgents(ctx, &proto.ListWorkspaceAgentsRequest{
+		WorkspaceIds: [][]byte{workspaceID[:]},
+	})
+	require.NoError(t, err)
+	agent, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, agentID[:], agent.Id)
+	require.Equal(t, workspaceID[:], agent.WorkspaceId)
+	require.Equal(t, "main", agent.Name)
+	require.Equal(t, "connecting", agent.Status)
+	_, err = stream.Recv()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestListWorkspaceAgentStats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Member", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmock.NewMockStore(gomock.NewController(t))
+		client := serve(ctx, t, db, rbac.RoleIdentifiers{rbac.RoleMember()})
+		stream, err := client.ListWorkspaceAgentStats(ctx, &proto.ListWorkspaceAgentStatsRequest{})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.ErrorContains(t, err, "unauthorized")
+	})
+
+	t.Run("Owner", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmock.NewMockStore(gomock.NewController(t))
+		agentID := uuid.New()
+		db.EXPECT().GetWorkspaceAgentStats(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, createdAfter time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
+			// Stats default to the last 15 minutes.
+			require.WithinDuration(t, time.Now().Add(-publicapi.DefaultStatsWindow), createdAfter, time.Minute)
+			return []database.GetWorkspaceAgentStatsRow{{AgentID: agentID, WorkspaceRxBytes: 42, SessionCountSSH: 2}}, nil
+		})
+		client := serve(ctx, t, db, rbac.RoleIdentifiers{rbac.RoleOwner()})
+		stream, err := client.ListWorkspaceAgentStats(ctx, &proto.ListWorkspaceAgentStatsRequest{})
+		require.NoError(t, err)
+		stats, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, agentID[:], stats.AgentId)
+		require.EqualValues(t, 42, stats.RxBytes)
+		require.EqualValues(t, 2, stats.SessionCountSsh)
+	})
+}
+
+func serve(ctx context.Context, t *testing.T, db database.Store, roles rbac.RoleIdentifiers) proto.DRPCPublicAPIClient {
+	t.Helper()
+
+	api := publicapi.New(publicapi.Options{
+		Log:                            slogtest.Make(t, nil),
+		Database:                       db,
+		Authorizer:                     rbac.NewAuthorizer(prometheus.NewRegistry()),
+		AgentInactiveDisconnectTimeout: time.Minute,
+	})
+	conn, lis := drpcsdk.MemTransportPipe()
+	t.Cleanup(func() {
+		_ = conn.Close()
+		_ = lis.Close()
+	})
+	serveCtx, cancel := context.WithCancel(dbauthz.As(ctx, rbac.Subject{
+		FriendlyName: "user",
+		ID:           uuid.NewString(),
+		Roles:        roles,
+		Scope:        rbac.ScopeAll,
+	}))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = api.Serve(serveCtx, lis)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return proto.NewDRPCPublicAPIClient(conn)
+}
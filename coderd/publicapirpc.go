@@ -0,0 +1,100 @@
+package coderd
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/hashicorp/yamux"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/publicapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/publicapi/proto"
+	"github.com/coder/websocket"
+)
+
+// @Summary Public RPC API
+// @Description Serves the DRPC service in publicapi/proto over a websocket,
+// @Description for integrations that list many workspaces, builds and agents.
+// @ID public-rpc-api
+// @Security CoderSessionToken
+// @Tags General
+// @Param version query string false "API version, e.g. 1.0"
+// @Success 101
+// @Router /rpc [get]
+func (api *API) publicAPIRPC(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+	logger := api.Logger.Named("publicapi").With(slog.F("user_id", apiKey.UserID))
+
+	version := r.URL.Query().Get("version")
+	if version == "" {
+		version = proto.CurrentVersion.String()
+	}
+	if err := proto.CurrentVersion.Validate(version); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Unknown or unsupported API version",
+			Validations: []codersdk.ValidationError{
+				{Field: "version", Detail: err.Error()},
+			},
+		})
+		return
+	}
+
+	api.WebsocketWaitMutex.Lock()
+	api.WebsocketWaitGroup.Add(1)
+	api.WebsocketWaitMutex.Unlock()
+	defer api.WebsocketWaitGroup.Done()
+
+	conn, err := websocket.Accept(rw, r, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to accept websocket.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// The request context carries the actor of the API key, which the
+	// queries of the public API are authorized against.
+	ctx, wsNetConn := codersdk.WebsocketNetConn(ctx, conn, websocket.MessageBinary)
+	defer wsNetConn.Close()
+
+	ycfg := yamux.DefaultConfig()
+	ycfg.LogOutput = nil
+	ycfg.Logger = slog.Stdlib(ctx, logger.Named("yamux"), slog.LevelInfo)
+
+	mux, err := yamux.Server(wsNetConn, ycfg)
+	if err != nil {
+		_ = conn.Close(websocket.StatusInternalError, err.Error())
+		return
+	}
+	defer mux.Close()
+
+	// Stop serving when coderd shuts down.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-api.ctx.Done():
+			cancel()
+		}
+	}()
+
+	err = publicapi.New(publicapi.Options{
+		Log:                            logger,
+		Database:                       api.Database,
+		Authorizer:                     api.Authorizer,
+		AgentInactiveDisconnectTimeout: api.AgentInactiveDisconnectTimeout,
+	}).Serve(ctx, mux)
+	if err != nil && !xerrors.Is(err, yamux.ErrSessionShutdown) && !xerrors.Is(err, io.EOF) && !xerrors.Is(err, context.Canceled) {
+		logger.Warn(ctx, "public API RPC listen error", slog.Error(err))
+		_ = conn.Close(websocket.StatusInternalError, err.Error())
+		return
+	}
+}
@@ -0,0 +1,71 @@
+package coderd_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/publicapi/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestPublicAPIRPC(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	ownerWorkspace := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        owner.UserID,
+	}).WithAgent().Do()
+	memberWorkspace := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        member.ID,
+	}).WithAgent().Do()
+
+	rpc, err := memberClient.ConnectPublicAPI(ctx)
+	require.NoError(t, err)
+
+	// Members only see their own workspaces.
+	stream, err := rpc.ListWorkspaces(ctx, &proto.ListWorkspacesRequest{})
+	require.NoError(t, err)
+	var ids [][]byte
+	for {
+		ws, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, ws.Id)
+	}
+	require.Equal(t, [][]byte{memberWorkspace.Workspace.ID[:]}, ids)
+
+	_, err = rpc.GetWorkspace(ctx, &proto.GetWorkspaceRequest{Id: ownerWorkspace.Workspace.ID[:]})
+	require.Error(t, err)
+
+	agents, err := rpc.ListWorkspaceAgents(ctx, &proto.ListWorkspaceAgentsRequest{})
+	require.NoError(t, err)
+	agent, err := agents.Recv()
+	require.NoError(t, err)
+	require.Equal(t, memberWorkspace.Workspace.ID[:], agent.WorkspaceId)
+
+	// Agent stats span all workspaces, so they need deployment stats access.
+	stats, err := rpc.ListWorkspaceAgentStats(ctx, &proto.ListWorkspaceAgentStatsRequest{})
+	require.NoError(t, err)
+	_, err = stats.Recv()
+	require.ErrorContains(t, err, "unauthorized")
+
+	ownerRPC, err := client.ConnectPublicAPI(ctx)
+	require.NoError(t, err)
+	ws, err := ownerRPC.GetWorkspace(ctx, &proto.GetWorkspaceRequest{Id: memberWorkspace.Workspace.ID[:]})
+	require.NoError(t, err)
+	require.Equal(t, member.Username, ws.OwnerUsername)
+}
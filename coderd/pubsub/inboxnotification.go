@@ -34,10 +34,13 @@ func HandleInboxNotificationEvent(cb func(ctx context.Context, payload InboxNoti
 type InboxNotificationEvent struct {
 	Kind              InboxNotificationEventKind `json:"kind"`
 	InboxNotification codersdk.InboxNotification `json:"inbox_notification"`
+	// ReadStatus is set for InboxNotificationEventKindReadStatus events.
+	ReadStatus *codersdk.InboxNotificationReadStatusChange `json:"read_status,omitempty"`
 }
 
 type InboxNotificationEventKind string
 
 const (
-	InboxNotificationEventKindNew InboxNotificationEventKind = "new"
+	InboxNotificationEventKindNew        InboxNotificationEventKind = "new"
+	InboxNotificationEventKindReadStatus InboxNotificationEventKind = "read_status"
 )
@@ -57,10 +57,59 @@ func WorkspaceAgentScope(params WorkspaceAgentScopeParams) Scope {
 	}
 }
 
+// WorkspaceConnectScopeParams are the resources that a workspace connect scope
+// allows access to.
+type WorkspaceConnectScopeParams struct {
+	WorkspaceID    uuid.UUID
+	OwnerID        uuid.UUID
+	TemplateID     uuid.UUID
+	OrganizationID uuid.UUID
+	// UserID is the user the API key belongs to, which may differ from the
+	// workspace owner.
+	UserID uuid.UUID
+}
+
+// WorkspaceConnectScope returns a scope that can only read and connect to a
+// single workspace. It is used by short-lived credentials that are handed to
+// scripts, so that a leaked credential can't be used for anything else. The
+// roles still come from the user the credential belongs to.
+func WorkspaceConnectScope(params WorkspaceConnectScopeParams) Scope {
+	if params.WorkspaceID == uuid.Nil || params.OwnerID == uuid.Nil || params.TemplateID == uuid.Nil || params.OrganizationID == uuid.Nil || params.UserID == uuid.Nil {
+		panic("all uuids must be non-nil, this is a developer error")
+	}
+
+	return Scope{
+		Role: Role{
+			Identifier:  RoleIdentifier{Name: fmt.Sprintf("Scope_%s", ScopeWorkspaceConnect)},
+			DisplayName: "Ability to connect to a single workspace",
+			Site: Permissions(map[string][]policy.Action{
+				ResourceWorkspace.Type:    {policy.ActionRead, policy.ActionSSH, policy.ActionApplicationConnect},
+				ResourceTemplate.Type:     {policy.ActionRead},
+				ResourceOrganization.Type: {policy.ActionRead},
+				ResourceUser.Type:         {policy.ActionRead},
+			}),
+			Org:  map[string][]Permission{},
+			User: []Permission{},
+		},
+		// The workspace is the only resource that can be connected to, the
+		// rest are required to look it up by name.
+		AllowIDList: []string{
+			params.WorkspaceID.String(),
+			params.OwnerID.String(),
+			params.TemplateID.String(),
+			params.OrganizationID.String(),
+			params.UserID.String(),
+		},
+	}
+}
+
 const (
 	ScopeAll                ScopeName = "all"
 	ScopeApplicationConnect ScopeName = "application_connect"
 	ScopeNoUserData         ScopeName = "no_user_data"
+	// ScopeWorkspaceConnect is not a builtin scope, since it is limited to a
+	// workspace. Use WorkspaceConnectScope to create it.
+	ScopeWorkspaceConnect ScopeName = "workspace_connect"
 )
 
 // TODO: Support passing in scopeID list for allowlisting resources.
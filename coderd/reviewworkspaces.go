@@ -0,0 +1,617 @@
+package coderd
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// maxReviewWorkspaceWebhookBytes bounds the webhook payloads that are read.
+// Branch deletion payloads are small, but push payloads list commits.
+const maxReviewWorkspaceWebhookBytes = 10 << 20
+
+// gitNullSHA is the commit a ref points at after it has been deleted.
+const gitNullSHA = "0000000000000000000000000000000000000000"
+
+// @Summary Create or rebuild review workspace
+// @Description Creates an ephemeral workspace for a branch of a repository,
+// @Description or rebuilds the existing one with the given template version
+// @Description and parameters. Intended to be called by CI on every push.
+// @ID create-or-rebuild-review-workspace
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.UpsertReviewWorkspaceRequest true "Upsert review workspace request"
+// @Success 200 {object} codersdk.ReviewWorkspace
+// @Router /organizations/{organization}/review-workspaces [put]
+func (api *API) putReviewWorkspace(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+		apiKey       = httpmw.APIKey(r)
+	)
+
+	var req codersdk.UpsertReviewWorkspaceRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	repository := strings.ToLower(req.Repository)
+
+	template, ok := requestTemplate(ctx, rw, codersdk.CreateWorkspaceRequest{
+		TemplateID:        req.TemplateID,
+		TemplateVersionID: req.TemplateVersionID,
+	}, api.Database)
+	if !ok {
+		return
+	}
+	if template.OrganizationID != organization.ID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Template %q does not belong to organization %q.", template.Name, organization.Name),
+		})
+		return
+	}
+
+	reviewWorkspace, err := api.Database.GetReviewWorkspace(ctx, database.GetReviewWorkspaceParams{
+		OrganizationID: organization.ID,
+		Repository:     repository,
+		Branch:         req.Branch,
+	})
+	if err != nil && !httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching review workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if err != nil {
+		// The branch has no review workspace yet, or it belongs to someone
+		// the caller cannot see. The latter is reported as a conflict when
+		// the branch is linked to the new workspace.
+		user, err := api.Database.GetUserByID(ctx, apiKey.UserID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching user.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		owner := workspaceOwner{
+			ID:        user.ID,
+			Username:  user.Username,
+			AvatarURL: user.AvatarURL,
+		}
+
+		auditor := api.Auditor.Load()
+		aReq, commitAudit := audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+			AdditionalFields: audit.AdditionalFields{
+				WorkspaceOwner: owner.Username,
+			},
+		})
+		defer commitAudit()
+
+		now := dbtime.Now()
+		workspace, ok := createWorkspaceInternal(ctx, aReq, apiKey.UserID, api, owner, codersdk.CreateWorkspaceRequest{
+			TemplateID:              req.TemplateID,
+			TemplateVersionID:       req.TemplateVersionID,
+			Name:                    reviewWorkspaceName(repository, req.Branch),
+			RichParameterValues:     req.RichParameterValues,
+			TemplateVersionPresetID: req.TemplateVersionPresetID,
+			// Review workspaces are torn down when the branch is deleted,
+			// so there is no reason to keep them around while stopped.
+			Ephemeral: true,
+		}, rw, r, func(db database.Store, workspace database.Workspace, _ bool) error {
+			reviewWorkspace, err = db.UpsertReviewWorkspace(ctx, database.UpsertReviewWorkspaceParams{
+				WorkspaceID:    workspace.ID,
+				OrganizationID: organization.ID,
+				Repository:     repository,
+				Branch:         req.Branch,
+				CreatedAt:      now,
+			})
+			if errors.Is(err, sql.ErrNoRows) {
+				return wsbuilder.BuildError{
+					Status:  http.StatusConflict,
+					Message: fmt.Sprintf("Branch %q of %q already has a review workspace.", req.Branch, repository),
+					Wrapped: err,
+				}
+			}
+			if err != nil {
+				return xerrors.Errorf("upsert review workspace: %w", err)
+			}
+			return nil
+		})
+		if !ok {
+			return
+		}
+
+		httpapi.Write(ctx, rw, http.StatusOK, api.convertReviewWorkspace(
+			reviewWorkspace,
+			workspace.OwnerName,
+			workspace.Name,
+			workspace.LatestBuild.ID,
+			workspace.LatestBuild.Status,
+		))
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByID(ctx, reviewWorkspace.WorkspaceID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if workspace.TemplateID != template.ID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("The review workspace of branch %q uses a different template.", req.Branch),
+			Detail:  "Delete the review workspace to recreate it from another template.",
+		})
+		return
+	}
+	versionID := req.TemplateVersionID
+	if versionID == uuid.Nil {
+		versionID = template.ActiveVersionID
+	}
+
+	build, ok := api.postWorkspaceBuildsInternal(rw, r, workspace, codersdk.CreateWorkspaceBuildRequest{
+		TemplateVersionID:       versionID,
+		Transition:              codersdk.WorkspaceTransitionStart,
+		RichParameterValues:     req.RichParameterValues,
+		TemplateVersionPresetID: req.TemplateVersionPresetID,
+	})
+	if !ok {
+		return
+	}
+
+	reviewWorkspace, err = api.Database.UpsertReviewWorkspace(ctx, database.UpsertReviewWorkspaceParams{
+		WorkspaceID:    reviewWorkspace.WorkspaceID,
+		OrganizationID: reviewWorkspace.OrganizationID,
+		Repository:     reviewWorkspace.Repository,
+		Branch:         reviewWorkspace.Branch,
+		CreatedAt:      dbtime.Now(),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating review workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertReviewWorkspace(
+		reviewWorkspace,
+		workspace.OwnerUsername,
+		workspace.Name,
+		build.ID,
+		build.Status,
+	))
+}
+
+// @Summary Get review workspace
+// @ID get-review-workspace
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param repository query string true "Full name of the repository"
+// @Param branch query string true "Branch name"
+// @Success 200 {object} codersdk.ReviewWorkspace
+// @Router /organizations/{organization}/review-workspaces [get]
+func (api *API) reviewWorkspace(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	reviewWorkspace, workspace, ok := api.reviewWorkspaceFromQuery(rw, r)
+	if !ok {
+		return
+	}
+
+	build, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching latest workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	job, err := api.Database.GetProvisionerJobByID(ctx, build.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertReviewWorkspace(
+		reviewWorkspace,
+		workspace.OwnerUsername,
+		workspace.Name,
+		build.ID,
+		codersdk.ConvertWorkspaceStatus(codersdk.ProvisionerJobStatus(job.JobStatus), codersdk.WorkspaceTransition(build.Transition)),
+	))
+}
+
+// @Summary Delete review workspace
+// @Description Starts a build that deletes the review workspace of a branch.
+// @ID delete-review-workspace
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param repository query string true "Full name of the repository"
+// @Param branch query string true "Branch name"
+// @Success 200 {object} codersdk.ReviewWorkspace
+// @Router /organizations/{organization}/review-workspaces [delete]
+func (api *API) deleteReviewWorkspace(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	reviewWorkspace, workspace, ok := api.reviewWorkspaceFromQuery(rw, r)
+	if !ok {
+		return
+	}
+
+	build, ok := api.postWorkspaceBuildsInternal(rw, r, workspace, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionDelete,
+	})
+	if !ok {
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertReviewWorkspace(
+		reviewWorkspace,
+		workspace.OwnerUsername,
+		workspace.Name,
+		build.ID,
+		build.Status,
+	))
+}
+
+func (api *API) reviewWorkspaceFromQuery(rw http.ResponseWriter, r *http.Request) (database.ReviewWorkspace, database.Workspace, bool) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+	)
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("repository").
+		RequiredNotEmpty("branch")
+	vals := r.URL.Query()
+	var (
+		repository = p.String(vals, "", "repository")
+		branch     = p.String(vals, "", "branch")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return database.ReviewWorkspace{}, database.Workspace{}, false
+	}
+
+	reviewWorkspace, err := api.Database.GetReviewWorkspace(ctx, database.GetReviewWorkspaceParams{
+		OrganizationID: organization.ID,
+		Repository:     strings.ToLower(repository),
+		Branch:         branch,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return database.ReviewWorkspace{}, database.Workspace{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching review workspace.",
+			Detail:  err.Error(),
+		})
+		return database.ReviewWorkspace{}, database.Workspace{}, false
+	}
+
+	workspace, err := api.Database.GetWorkspaceByID(ctx, reviewWorkspace.WorkspaceID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return database.ReviewWorkspace{}, database.Workspace{}, false
+	}
+	return reviewWorkspace, workspace, true
+}
+
+// @Summary Delete review workspaces of a deleted GitHub branch
+// @Description Receives GitHub "delete" events. Requests are authenticated
+// @Description with the X-Hub-Signature-256 header, which is computed with
+// @Description the review workspaces webhook secret.
+// @ID delete-review-workspaces-of-deleted-github-branch
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Success 200 {object} codersdk.Response
+// @Router /review-workspaces/webhooks/github [post]
+func (api *API) postReviewWorkspacesGitHubWebhook(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body, ok := api.readReviewWorkspacesWebhook(rw, r)
+	if !ok {
+		return
+	}
+	if !validGitHubSignature(api.DeploymentValues.ReviewWorkspacesWebhookSecret.String(), body, r.Header.Get("X-Hub-Signature-256")) {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Invalid webhook signature.",
+		})
+		return
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	if event != "delete" {
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+			Message: fmt.Sprintf("Ignored %q event.", event),
+		})
+		return
+	}
+
+	var payload struct {
+		Ref        string `json:"ref"`
+		RefType    string `json:"ref_type"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid webhook payload.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if payload.RefType != "branch" {
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+			Message: fmt.Sprintf("Ignored deletion of %s %q.", payload.RefType, payload.Ref),
+		})
+		return
+	}
+
+	api.deleteReviewWorkspacesOfBranch(rw, r, payload.Repository.FullName, payload.Ref)
+}
+
+// @Summary Delete review workspaces of a deleted GitLab branch
+// @Description Receives GitLab push events. Requests are authenticated with
+// @Description the X-Gitlab-Token header, which must be the review workspaces
+// @Description webhook secret.
+// @ID delete-review-workspaces-of-deleted-gitlab-branch
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Success 200 {object} codersdk.Response
+// @Router /review-workspaces/webhooks/gitlab [post]
+func (api *API) postReviewWorkspacesGitLabWebhook(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body, ok := api.readReviewWorkspacesWebhook(rw, r)
+	if !ok {
+		return
+	}
+	secret := api.DeploymentValues.ReviewWorkspacesWebhookSecret.String()
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(r.Header.Get("X-Gitlab-Token"))) != 1 {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Invalid webhook token.",
+		})
+		return
+	}
+
+	event := r.Header.Get("X-Gitlab-Event")
+	if event != "Push Hook" {
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+			Message: fmt.Sprintf("Ignored %q event.", event),
+		})
+		return
+	}
+
+	var payload struct {
+		Ref     string `json:"ref"`
+		After   string `json:"after"`
+		Project struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+		} `json:"project"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid webhook payload.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	branch, isBranch := strings.CutPrefix(payload.Ref, "refs/heads/")
+	if !isBranch || payload.After != gitNullSHA {
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+			Message: fmt.Sprintf("Ignored push to %q.", payload.Ref),
+		})
+		return
+	}
+
+	api.deleteReviewWorkspacesOfBranch(rw, r, payload.Project.PathWithNamespace, branch)
+}
+
+// readReviewWorkspacesWebhook reads the body of a webhook request. Webhooks
+// are rejected if no secret has been configured.
+func (api *API) readReviewWorkspacesWebhook(rw http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	ctx := r.Context()
+
+	if api.DeploymentValues.ReviewWorkspacesWebhookSecret.String() == "" {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Review workspace webhooks are not enabled.",
+			Detail:  "Set --review-workspaces-webhook-secret to enable them.",
+		})
+		return nil, false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxReviewWorkspaceWebhookBytes))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to read webhook payload.",
+			Detail:  err.Error(),
+		})
+		return nil, false
+	}
+	return body, true
+}
+
+// deleteReviewWorkspacesOfBranch starts builds that delete the review
+// workspaces of a branch in every organization. The builds are initiated by
+// the owners of the workspaces.
+func (api *API) deleteReviewWorkspacesOfBranch(rw http.ResponseWriter, r *http.Request, repository, branch string) {
+	// nolint:gocritic // Webhooks are authenticated with a shared secret, not as a user.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	reviewWorkspaces, err := api.Database.GetReviewWorkspacesByRepositoryAndBranch(ctx, database.GetReviewWorkspacesByRepositoryAndBranchParams{
+		Repository: strings.ToLower(repository),
+		Branch:     branch,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching review workspaces.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var deleted int
+	for _, reviewWorkspace := range reviewWorkspaces {
+		err := api.startReviewWorkspaceDeletion(ctx, r, reviewWorkspace)
+		if err != nil {
+			api.Logger.Error(ctx, "failed to delete review workspace",
+				slog.F("workspace_id", reviewWorkspace.WorkspaceID),
+				slog.F("repository", reviewWorkspace.Repository),
+				slog.F("branch", reviewWorkspace.Branch),
+				slog.Error(err),
+			)
+			continue
+		}
+		deleted++
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: fmt.Sprintf("Deleting %d of %d review workspaces.", deleted, len(reviewWorkspaces)),
+	})
+}
+
+func (api *API) startReviewWorkspaceDeletion(ctx context.Context, r *http.Request, reviewWorkspace database.ReviewWorkspace) error {
+	workspace, err := api.Database.GetWorkspaceByID(ctx, reviewWorkspace.WorkspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace: %w", err)
+	}
+
+	builder := wsbuilder.New(workspace, database.WorkspaceTransitionDelete).
+		Initiator(workspace.OwnerID).
+		Experiments(api.ExperimentFlags.ExperimentsFor(ctx)).
+		DeploymentValues(api.DeploymentValues)
+	_, job, _, err := builder.Build(ctx, api.Database, api.FileCache, nil, audit.WorkspaceBuildBaggageFromRequest(r))
+	if err != nil {
+		return xerrors.Errorf("build workspace: %w", err)
+	}
+
+	if err := provisionerjobs.PostJob(api.Pubsub, *job); err != nil {
+		// The job is picked up by the next provisioner poll.
+		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: workspace.ID,
+	})
+	return nil
+}
+
+func (api *API) convertReviewWorkspace(
+	reviewWorkspace database.ReviewWorkspace,
+	ownerName string,
+	workspaceName string,
+	latestBuildID uuid.UUID,
+	status codersdk.WorkspaceStatus,
+) codersdk.ReviewWorkspace {
+	return codersdk.ReviewWorkspace{
+		OrganizationID:     reviewWorkspace.OrganizationID,
+		Repository:         reviewWorkspace.Repository,
+		Branch:             reviewWorkspace.Branch,
+		WorkspaceID:        reviewWorkspace.WorkspaceID,
+		WorkspaceName:      workspaceName,
+		WorkspaceOwnerName: ownerName,
+		LatestBuildID:      latestBuildID,
+		Status:             status,
+		StatusURL:          api.AccessURL.JoinPath("@"+ownerName, workspaceName).String(),
+		CreatedAt:          reviewWorkspace.CreatedAt,
+		UpdatedAt:          reviewWorkspace.UpdatedAt,
+	}
+}
+
+// reviewWorkspaceName derives a valid workspace name from a branch. The hash
+// of the repository and branch keeps the names of branches apart that only
+// differ in characters names cannot contain.
+func reviewWorkspaceName(repository, branch string) string {
+	var slug strings.Builder
+	for _, c := range strings.ToLower(branch) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+			_, _ = slug.WriteRune(c)
+		case slug.Len() > 0 && !strings.HasSuffix(slug.String(), "-"):
+			_ = slug.WriteByte('-')
+		}
+	}
+	name := slug.String()
+	// Leave room for the hash within the 32 character limit of names.
+	if len(name) > 23 {
+		name = name[:23]
+	}
+	name = strings.TrimRight(name, "-")
+	if name == "" {
+		name = "review"
+	}
+
+	sum := sha256.Sum256([]byte(repository + "/" + branch))
+	return name + "-" + hex.EncodeToString(sum[:])[:8]
+}
+
+// validGitHubSignature reports whether signature is the "sha256=" prefixed
+// HMAC of body that GitHub sends in the X-Hub-Signature-256 header.
+func validGitHubSignature(secret string, body []byte, signature string) bool {
+	if secret == "" {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
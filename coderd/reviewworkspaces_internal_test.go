@@ -0,0 +1,52 @@
+package coderd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestReviewWorkspaceName(t *testing.T) {
+	t.Parallel()
+
+	for _, branch := range []string{
+		"main",
+		"feature/Add-Review-Workspaces",
+		"dependabot/go_modules/golang.org/x/crypto-0.31.0",
+		"---",
+		"日本語",
+	} {
+		name := reviewWorkspaceName("coder/coder", branch)
+		require.NoError(t, codersdk.NameValid(name), "branch %q", branch)
+	}
+
+	require.True(t, strings.HasPrefix(reviewWorkspaceName("coder/coder", "feature/x"), "feature-x-"))
+	require.True(t, strings.HasPrefix(reviewWorkspaceName("coder/coder", "日本語"), "review-"))
+	// Branches that only differ in characters that are dropped get
+	// different names.
+	require.NotEqual(t,
+		reviewWorkspaceName("coder/coder", "feature/x"),
+		reviewWorkspaceName("coder/coder", "feature-x"),
+	)
+}
+
+func TestValidGitHubSignature(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"ref":"main","ref_type":"branch"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	_, _ = mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	require.True(t, validGitHubSignature("secret", body, signature))
+	require.False(t, validGitHubSignature("other", body, signature))
+	require.False(t, validGitHubSignature("secret", []byte(`{}`), signature))
+	require.False(t, validGitHubSignature("secret", body, "sha256=nothex"))
+	require.False(t, validGitHubSignature("", body, signature))
+}
@@ -0,0 +1,152 @@
+package coderd_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestReviewWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CreateAndRebuild", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		created, err := member.UpsertReviewWorkspace(ctx, owner.OrganizationID, codersdk.UpsertReviewWorkspaceRequest{
+			Repository: "Coder/Coder",
+			Branch:     "feature/review",
+			TemplateID: template.ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "coder/coder", created.Repository)
+		require.Contains(t, created.StatusURL, "/@"+created.WorkspaceOwnerName+"/"+created.WorkspaceName)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, created.LatestBuildID)
+
+		workspace, err := member.Workspace(ctx, created.WorkspaceID)
+		require.NoError(t, err)
+		require.True(t, workspace.Ephemeral)
+
+		// Pushing to the branch again rebuilds the same workspace.
+		rebuilt, err := member.UpsertReviewWorkspace(ctx, owner.OrganizationID, codersdk.UpsertReviewWorkspaceRequest{
+			Repository: "coder/coder",
+			Branch:     "feature/review",
+			TemplateID: template.ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, created.WorkspaceID, rebuilt.WorkspaceID)
+		require.NotEqual(t, created.LatestBuildID, rebuilt.LatestBuildID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, rebuilt.LatestBuildID)
+
+		got, err := member.ReviewWorkspace(ctx, owner.OrganizationID, "coder/coder", "feature/review")
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, got.Status)
+	})
+
+	t.Run("OtherOwner", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		other, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		req := codersdk.UpsertReviewWorkspaceRequest{
+			Repository: "coder/coder",
+			Branch:     "feature/review",
+			TemplateID: template.ID,
+		}
+		_, err := member.UpsertReviewWorkspace(ctx, owner.OrganizationID, req)
+		require.NoError(t, err)
+
+		_, err = other.UpsertReviewWorkspace(ctx, owner.OrganizationID, req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("GitHubWebhook", func(t *testing.T) {
+		t.Parallel()
+
+		dv := coderdtest.DeploymentValues(t)
+		dv.ReviewWorkspacesWebhookSecret = "secret"
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			DeploymentValues:         dv,
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		created, err := member.UpsertReviewWorkspace(ctx, owner.OrganizationID, codersdk.UpsertReviewWorkspaceRequest{
+			Repository: "coder/coder",
+			Branch:     "feature/review",
+			TemplateID: template.ID,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, created.LatestBuildID)
+
+		body := []byte(`{"ref":"feature/review","ref_type":"branch","repository":{"full_name":"coder/coder"}}`)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		_, _ = mac.Write(body)
+		sign := func(signature string) codersdk.RequestOption {
+			return func(r *http.Request) {
+				r.Header.Set("X-GitHub-Event", "delete")
+				r.Header.Set("X-Hub-Signature-256", signature)
+			}
+		}
+
+		// Unauthenticated requests must carry a valid signature.
+		unauthed := codersdk.New(client.URL)
+		res, err := unauthed.Request(ctx, http.MethodPost, "/api/v2/review-workspaces/webhooks/github", body, sign("sha256=00"))
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+		res, err = unauthed.Request(ctx, http.MethodPost, "/api/v2/review-workspaces/webhooks/github", body, sign("sha256="+hex.EncodeToString(mac.Sum(nil))))
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		workspace, err := member.Workspace(ctx, created.WorkspaceID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceTransitionDelete, workspace.LatestBuild.Transition)
+	})
+
+	t.Run("WebhooksDisabled", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		res, err := client.Request(ctx, http.MethodPost, "/api/v2/review-workspaces/webhooks/gitlab", []byte(`{}`))
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+}
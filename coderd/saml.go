@@ -0,0 +1,542 @@
+package coderd
+
+import (
+	"database/sql"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"github.com/crewjam/saml"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/idpsync"
+	"github.com/coder/coder/v2/coderd/samlauth"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+type SAMLConfig struct {
+	ServiceProvider *saml.ServiceProvider
+	// EmailDomain are the domains to enforce when a user authenticates.
+	EmailDomain  []string
+	AllowSignups bool
+	// UsernameAttribute selects the SAML attribute to be used as the created
+	// user's username.
+	UsernameAttribute string
+	// EmailAttribute selects the SAML attribute to be used as the created
+	// user's email. The NameID is used if it is missing.
+	EmailAttribute string
+	// NameAttribute selects the SAML attribute to be used as the created
+	// user's full name.
+	NameAttribute string
+	// SignInText is the text to display on the SAML login button.
+	SignInText string
+	// IconURL points to the URL of an icon to display on the SAML login button.
+	IconURL string
+}
+
+// samlLinkedID returns the unique ID for a SAML user. The NameID is only
+// unique for a given identity provider, so the issuer is included like for
+// OIDC.
+func samlLinkedID(issuer, nameID string) string {
+	return strings.Join([]string{issuer, nameID}, "||")
+}
+
+// samlCookie applies the deployment cookie settings to cookies that must
+// survive the identity provider posting back to the assertion consumer
+// service. That is a cross-site request, so browsers only send cookies that
+// allow it.
+func (api *API) samlCookie(c *http.Cookie) *http.Cookie {
+	c = api.DeploymentValues.HTTPCookies.Apply(c)
+	if api.AccessURL.Scheme == "https" {
+		c.SameSite = http.SameSiteNoneMode
+		c.Secure = true
+	}
+	return c
+}
+
+// @Summary SAML service provider metadata
+// @ID saml-service-provider-metadata
+// @Produce application/samlmetadata+xml
+// @Tags Users
+// @Success 200
+// @Router /users/saml/metadata [get]
+func (api *API) userSAMLMetadata(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if api.SAMLConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "SAML is not configured.",
+		})
+		return
+	}
+
+	metadata, err := xml.MarshalIndent(api.SAMLConfig.ServiceProvider.Metadata(), "", "  ")
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to render SAML metadata.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.Header().Set("Content-Type", "application/samlmetadata+xml")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(metadata)
+}
+
+// @Summary SAML login
+// @ID saml-login
+// @Tags Users
+// @Param redirect query string false "Redirect after login"
+// @Success 307
+// @Router /users/saml/login [get]
+func (api *API) userSAMLLogin(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if api.SAMLConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "SAML is not configured.",
+		})
+		return
+	}
+	sp := api.SAMLConfig.ServiceProvider
+
+	req, err := sp.MakeAuthenticationRequest(sp.GetSSOBindingLocation(saml.HTTPRedirectBinding), saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to create SAML authentication request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	redirectURL, err := req.Redirect("", sp)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to create SAML authentication request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	redirect := r.URL.Query().Get("redirect")
+	if redirect != "" {
+		// Only ever redirect to a path on this deployment.
+		redirect = uriFromURL(redirect)
+	}
+	http.SetCookie(rw, api.samlCookie(&http.Cookie{
+		Name:     codersdk.SAMLRequestIDCookie,
+		Value:    req.ID,
+		Path:     "/",
+		MaxAge:   int(saml.MaxIssueDelay.Seconds()),
+		HttpOnly: true,
+	}))
+	// Redirect must always be specified, otherwise an old redirect could
+	// apply!
+	http.SetCookie(rw, api.samlCookie(&http.Cookie{
+		Name:     codersdk.SAMLRedirectCookie,
+		Value:    redirect,
+		Path:     "/",
+		MaxAge:   int(saml.MaxIssueDelay.Seconds()),
+		HttpOnly: true,
+	}))
+	http.Redirect(rw, r, redirectURL.String(), http.StatusTemporaryRedirect)
+}
+
+// @Summary SAML assertion consumer service
+// @ID saml-assertion-consumer-service
+// @Accept x-www-form-urlencoded
+// @Tags Users
+// @Success 303
+// @Router /users/saml/acs [post]
+func (api *API) userSAMLACS(rw http.ResponseWriter, r *http.Request) {
+	var (
+		// userSAMLACS is a system function.
+		//nolint:gocritic
+		ctx               = dbauthz.AsSystemRestricted(r.Context())
+		auditor           = api.Auditor.Load()
+		logger            = api.Logger.Named(userAuthLoggerName)
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	if api.SAMLConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "SAML is not configured.",
+		})
+		return
+	}
+
+	requestID, err := r.Cookie(codersdk.SAMLRequestIDCookie)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: fmt.Sprintf("Cookie %q must be provided. Start logging in from Coder, and ensure the access URL uses HTTPS.", codersdk.SAMLRequestIDCookie),
+		})
+		return
+	}
+	redirect := "/"
+	if c, err := r.Cookie(codersdk.SAMLRedirectCookie); err == nil && c.Value != "" {
+		redirect = uriFromURL(c.Value)
+	}
+	// The request can only be answered once.
+	http.SetCookie(rw, api.samlCookie(&http.Cookie{Name: codersdk.SAMLRequestIDCookie, Path: "/", MaxAge: -1}))
+	http.SetCookie(rw, api.samlCookie(&http.Cookie{Name: codersdk.SAMLRedirectCookie, Path: "/", MaxAge: -1}))
+
+	err = r.ParseForm()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to parse SAML response form.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	assertion, err := api.SAMLConfig.ServiceProvider.ParseResponse(r, []string{requestID.Value})
+	if err != nil {
+		// The details of an invalid response are kept out of the response
+		// since they can help forge one.
+		var invalid *saml.InvalidResponseError
+		if errors.As(err, &invalid) {
+			err = invalid.PrivateErr
+		}
+		logger.Warn(ctx, "saml: invalid response", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to verify SAML response.",
+		})
+		return
+	}
+	if assertion.Subject == nil || assertion.Subject.NameID == nil || assertion.Subject.NameID.Value == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "SAML assertion is missing a NameID.",
+			Detail:  "The NameID is required to uniquely identify users, so this authentication attempt is rejected.",
+		})
+		return
+	}
+	nameID := assertion.Subject.NameID.Value
+	claims := samlauth.Attributes(assertion)
+	logger.Debug(ctx, "got saml attributes",
+		slog.F("attributes", claimFields(claims)),
+		slog.F("blank", blankFields(claims)),
+	)
+
+	username := samlauth.Attribute(claims, api.SAMLConfig.UsernameAttribute)
+	email := samlauth.Attribute(claims, api.SAMLConfig.EmailAttribute)
+	if email == "" {
+		// Identity providers frequently identify users by email, so fall
+		// back to the NameID when the attribute is missing.
+		if _, err := mail.ParseAddress(nameID); err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("No email found in the SAML %q attribute or the NameID!", api.SAMLConfig.EmailAttribute),
+			})
+			return
+		}
+		email = nameID
+	}
+
+	// The username is a required property in Coder. We make a best-effort
+	// attempt at using the attributes, but if that fails we derive it from
+	// the email.
+	if codersdk.NameValid(username) != nil {
+		if username == "" {
+			username = email
+		}
+		username = codersdk.UsernameFrom(username)
+	}
+
+	if len(api.SAMLConfig.EmailDomain) > 0 && !emailDomainAllowed(email, api.SAMLConfig.EmailDomain) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Your email %q is not from an authorized domain! Please contact your administrator.", email),
+		})
+		return
+	}
+
+	name := codersdk.NormalizeRealUsername(samlauth.Attribute(claims, api.SAMLConfig.NameAttribute))
+	linkedID := samlLinkedID(assertion.Issuer.Value, nameID)
+
+	ctx = slog.With(ctx, slog.F("email", email), slog.F("username", username), slog.F("name", name))
+
+	user, link, err := findLinkedUser(ctx, api.Database, linkedID, email)
+	if err != nil {
+		logger.Error(ctx, "saml: unable to find linked user", slog.F("email", email), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to find linked user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Attributes are synced with the same settings as OIDC claims.
+	orgSync, orgSyncErr := api.IDPSync.ParseOrganizationClaims(ctx, claims)
+	if orgSyncErr != nil {
+		orgSyncErr.Write(rw, r)
+		return
+	}
+	groupSync, groupSyncErr := api.IDPSync.ParseGroupClaims(ctx, claims)
+	if groupSyncErr != nil {
+		groupSyncErr.Write(rw, r)
+		return
+	}
+	roleSync, roleSyncErr := api.IDPSync.ParseRoleClaims(ctx, claims)
+	if roleSyncErr != nil {
+		roleSyncErr.Write(rw, r)
+		return
+	}
+
+	// If a new user is authenticating for the first time
+	// the audit action is 'register', not 'login'
+	if user.ID == uuid.Nil {
+		aReq.Action = database.AuditActionRegister
+	}
+
+	params := (&oauthLoginParams{
+		User: user,
+		Link: link,
+		State: httpmw.OAuth2State{
+			// SAML has no tokens to store on the user link.
+			Token:    &oauth2.Token{},
+			Redirect: redirect,
+		},
+		LinkedID:         linkedID,
+		LoginType:        database.LoginTypeSaml,
+		AllowSignups:     api.SAMLConfig.AllowSignups,
+		Email:            email,
+		Username:         username,
+		Name:             name,
+		OrganizationSync: orgSync,
+		GroupSync:        groupSync,
+		RoleSync:         roleSync,
+		UserClaims: database.UserLinkClaims{
+			MergedClaims: claims,
+		},
+	}).SetInitAuditRequest(func(params *audit.RequestParams) (*audit.Request[database.User], func()) {
+		return audit.InitRequest[database.User](rw, params)
+	})
+	cookies, user, key, err := api.oauthLogin(r, params)
+	defer params.CommitAuditLogs()
+	if err != nil {
+		if hErr := idpsync.IsHTTPError(err); hErr != nil {
+			hErr.Write(rw, r)
+			return
+		}
+		logger.Error(ctx, "saml: login failed", slog.F("user", user.Username), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to process SAML login.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = key
+	aReq.UserID = key.UserID
+
+	for i := range cookies {
+		http.SetCookie(rw, cookies[i])
+	}
+	// The response was posted, so the browser must switch to GET.
+	http.Redirect(rw, r, redirect, http.StatusSeeOther)
+}
+
+// userSAMLLogout signs the user out of Coder, and then out of the identity
+// provider if it supports single logout.
+//
+// @Summary SAML logout
+// @ID saml-logout
+// @Security CoderSessionToken
+// @Tags Users
+// @Success 307
+// @Router /users/saml/logout [get]
+func (api *API) userSAMLLogout(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		logger            = api.Logger.Named(userAuthLoggerName)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogout,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = apiKey
+
+	http.SetCookie(rw, &http.Cookie{
+		MaxAge: -1,
+		Name:   codersdk.SessionTokenCookie,
+		Path:   "/",
+	})
+	err := api.Database.DeleteAPIKeyByID(ctx, apiKey.ID)
+	if err != nil {
+		logger.Error(ctx, "unable to delete API key", slog.F("api_key", apiKey.ID), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting API key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	err = api.Database.DeleteApplicationConnectAPIKeysByUserID(ctx, apiKey.UserID)
+	if err != nil {
+		logger.Error(ctx, "unable to invalidate subdomain app tokens", slog.F("user_id", apiKey.UserID), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting app tokens.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = database.APIKey{}
+
+	if api.SAMLConfig == nil || apiKey.LoginType != database.LoginTypeSaml {
+		http.Redirect(rw, r, "/login", http.StatusTemporaryRedirect)
+		return
+	}
+	sp := api.SAMLConfig.ServiceProvider
+	if sp.GetSLOBindingLocation(saml.HTTPRedirectBinding) == "" {
+		// The identity provider does not support single logout.
+		http.Redirect(rw, r, "/login", http.StatusTemporaryRedirect)
+		return
+	}
+
+	//nolint:gocritic // System needs to read the user link to find the NameID.
+	link, err := api.Database.GetUserLinkByUserIDLoginType(dbauthz.AsSystemRestricted(ctx), database.GetUserLinkByUserIDLoginTypeParams{
+		UserID:    apiKey.UserID,
+		LoginType: database.LoginTypeSaml,
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.Warn(ctx, "saml: unable to get user link for single logout", slog.F("user_id", apiKey.UserID), slog.Error(err))
+		}
+		http.Redirect(rw, r, "/login", http.StatusTemporaryRedirect)
+		return
+	}
+	_, nameID, ok := strings.Cut(link.LinkedID, "||")
+	if !ok {
+		http.Redirect(rw, r, "/login", http.StatusTemporaryRedirect)
+		return
+	}
+	logoutURL, err := sp.MakeRedirectLogoutRequest(nameID, "")
+	if err != nil {
+		logger.Warn(ctx, "saml: unable to create logout request", slog.F("user_id", apiKey.UserID), slog.Error(err))
+		http.Redirect(rw, r, "/login", http.StatusTemporaryRedirect)
+		return
+	}
+	http.Redirect(rw, r, logoutURL.String(), http.StatusTemporaryRedirect)
+}
+
+// userSAMLSLO is the single logout service. The identity provider sends a
+// logout request to it when the user signs out of another application, and
+// a logout response once a logout started by Coder completes.
+//
+// @Summary SAML single logout service
+// @ID saml-single-logout-service
+// @Tags Users
+// @Success 307
+// @Router /users/saml/slo [get]
+func (api *API) userSAMLSLO(rw http.ResponseWriter, r *http.Request) {
+	var (
+		// userSAMLSLO is a system function.
+		//nolint:gocritic
+		ctx    = dbauthz.AsSystemRestricted(r.Context())
+		logger = api.Logger.Named(userAuthLoggerName)
+	)
+	if api.SAMLConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "SAML is not configured.",
+		})
+		return
+	}
+	sp := api.SAMLConfig.ServiceProvider
+
+	if r.URL.Query().Get("SAMLRequest") == "" && r.FormValue("SAMLRequest") == "" {
+		// The session was deleted before redirecting to the identity
+		// provider, so the logout response only completes the redirect.
+		http.Redirect(rw, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	req, err := samlauth.ParseLogoutRequest(sp, r)
+	if err != nil {
+		logger.Warn(ctx, "saml: invalid logout request", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to verify SAML logout request.",
+		})
+		return
+	}
+
+	link, err := api.Database.GetUserLinkByLinkedID(ctx, samlLinkedID(req.Issuer.Value, req.NameID.Value))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		// The user never logged in to Coder, so there is nothing to do.
+	case err != nil:
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user link.",
+			Detail:  err.Error(),
+		})
+		return
+	case link.LoginType == database.LoginTypeSaml:
+		auditor := api.Auditor.Load()
+		aReq, commitAudit := audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogout,
+		})
+		aReq.UserID = link.UserID
+		err = api.Database.InTx(func(tx database.Store) error {
+			err := tx.DeleteAPIKeysByUserIDLoginType(ctx, database.DeleteAPIKeysByUserIDLoginTypeParams{
+				UserID:    link.UserID,
+				LoginType: database.LoginTypeSaml,
+			})
+			if err != nil {
+				return err
+			}
+			return tx.DeleteApplicationConnectAPIKeysByUserID(ctx, link.UserID)
+		}, nil)
+		if err != nil {
+			commitAudit()
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error deleting sessions.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		commitAudit()
+		logger.Info(ctx, "saml: signed out by identity provider", slog.F("user_id", link.UserID))
+	}
+
+	relayState := r.FormValue("RelayState")
+	if sp.GetSLOBindingLocation(saml.HTTPRedirectBinding) != "" {
+		responseURL, err := sp.MakeRedirectLogoutResponse(req.ID, relayState)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to create SAML logout response.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		http.Redirect(rw, r, responseURL.String(), http.StatusSeeOther)
+		return
+	}
+	form, err := sp.MakePostLogoutResponse(req.ID, relayState)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to create SAML logout response.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(form)
+}
@@ -0,0 +1,232 @@
+package coderd_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/samlauth"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserSAML(t *testing.T) {
+	t.Parallel()
+
+	// newIDP returns an identity provider that signs its responses with a
+	// new key.
+	newIDP := func(t *testing.T) *saml.IdentityProvider {
+		t.Helper()
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "idp.example.com"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		require.NoError(t, err)
+		cert, err := x509.ParseCertificate(der)
+		require.NoError(t, err)
+		metadataURL, err := url.Parse("https://idp.example.com/metadata")
+		require.NoError(t, err)
+		ssoURL, err := url.Parse("https://idp.example.com/sso")
+		require.NoError(t, err)
+		return &saml.IdentityProvider{
+			Key:         key,
+			Certificate: cert,
+			MetadataURL: *metadataURL,
+			SSOURL:      *ssoURL,
+		}
+	}
+
+	setup := func(t *testing.T) (*codersdk.Client, *saml.IdentityProvider, *saml.ServiceProvider) {
+		t.Helper()
+		idp := newIDP(t)
+		// The identity provider posts back to the access URL of the service
+		// provider, which doesn't have to be the one of the test server.
+		accessURL, err := url.Parse("https://coder.example.com")
+		require.NoError(t, err)
+		sp, err := samlauth.NewServiceProvider(samlauth.ServiceProviderOptions{
+			AccessURL:   accessURL,
+			IDPMetadata: idp.Metadata(),
+		})
+		require.NoError(t, err)
+
+		client := coderdtest.New(t, &coderdtest.Options{
+			SAMLConfig: &coderd.SAMLConfig{
+				ServiceProvider:   sp,
+				AllowSignups:      true,
+				UsernameAttribute: "uid",
+				EmailAttribute:    "mail",
+				NameAttribute:     "cn",
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		client.HTTPClient.CheckRedirect = func(_ *http.Request, _ []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+		return client, idp, sp
+	}
+
+	// login starts logging in to Coder, and returns the cookies that must be
+	// sent back to the assertion consumer service.
+	login := func(ctx context.Context, t *testing.T, client *codersdk.Client, redirect string) []*http.Cookie {
+		t.Helper()
+		u := client.URL.JoinPath("/api/v2/users/saml/login")
+		u.RawQuery = url.Values{"redirect": {redirect}}.Encode()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		require.NoError(t, err)
+		res, err := client.HTTPClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusTemporaryRedirect, res.StatusCode)
+		require.True(t, strings.HasPrefix(res.Header.Get("Location"), "https://idp.example.com/sso?SAMLRequest="))
+		return res.Cookies()
+	}
+
+	// respond returns a SAML response of the identity provider to the
+	// authentication request with the ID.
+	respond := func(t *testing.T, idp *saml.IdentityProvider, sp *saml.ServiceProvider, requestID string) string {
+		t.Helper()
+		spMetadata := sp.Metadata()
+		req := &saml.IdpAuthnRequest{
+			IDP:         idp,
+			HTTPRequest: httptest.NewRequest(http.MethodGet, "/", nil),
+			Request: saml.AuthnRequest{
+				ID:           requestID,
+				IssueInstant: saml.TimeNow(),
+			},
+			ServiceProviderMetadata: spMetadata,
+			SPSSODescriptor:         &spMetadata.SPSSODescriptors[0],
+			ACSEndpoint: &saml.IndexedEndpoint{
+				Binding:  saml.HTTPPostBinding,
+				Location: sp.AcsURL.String(),
+			},
+			Now: saml.TimeNow(),
+		}
+		err := saml.DefaultAssertionMaker{}.MakeAssertion(req, &saml.Session{
+			ID:             "session",
+			CreateTime:     saml.TimeNow(),
+			NameID:         "kyle@coder.com",
+			UserName:       "kyle",
+			UserEmail:      "kyle@coder.com",
+			UserCommonName: "Kyle Carberry",
+		})
+		require.NoError(t, err)
+		form, err := req.PostBinding()
+		require.NoError(t, err)
+		return form.SAMLResponse
+	}
+
+	acs := func(ctx context.Context, t *testing.T, client *codersdk.Client, cookies []*http.Cookie, samlResponse string) *http.Response {
+		t.Helper()
+		body := url.Values{"SAMLResponse": {samlResponse}}.Encode()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.URL.JoinPath("/api/v2/users/saml/acs").String(), strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		res, err := client.HTTPClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = res.Body.Close() })
+		return res
+	}
+
+	requestID := func(t *testing.T, cookies []*http.Cookie) string {
+		t.Helper()
+		for _, c := range cookies {
+			if c.Name == codersdk.SAMLRequestIDCookie {
+				return c.Value
+			}
+		}
+		require.FailNow(t, "missing SAML request ID cookie")
+		return ""
+	}
+
+	t.Run("SignedAssertion", func(t *testing.T) {
+		t.Parallel()
+		client, idp, sp := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		cookies := login(ctx, t, client, "/workspaces?filter=owner:me")
+		res := acs(ctx, t, client, cookies, respond(t, idp, sp, requestID(t, cookies)))
+		require.Equal(t, http.StatusSeeOther, res.StatusCode)
+		require.Equal(t, "/workspaces?filter=owner:me", res.Header.Get("Location"))
+
+		var sessionToken string
+		for _, c := range res.Cookies() {
+			if c.Name == codersdk.SessionTokenCookie {
+				sessionToken = c.Value
+			}
+		}
+		require.NotEmpty(t, sessionToken)
+		userClient := codersdk.New(client.URL)
+		userClient.SetSessionToken(sessionToken)
+		user, err := userClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, "kyle@coder.com", user.Email)
+		require.Equal(t, "kyle", user.Username)
+		require.Equal(t, "Kyle Carberry", user.Name)
+		require.Equal(t, codersdk.LoginTypeSAML, user.LoginType)
+	})
+
+	t.Run("BadSignature", func(t *testing.T) {
+		t.Parallel()
+		client, _, sp := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// The response is signed by a key that isn't in the metadata of the
+		// identity provider.
+		cookies := login(ctx, t, client, "/")
+		res := acs(ctx, t, client, cookies, respond(t, newIDP(t), sp, requestID(t, cookies)))
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.Empty(t, res.Header.Get("Location"))
+	})
+
+	t.Run("RequestIDMismatch", func(t *testing.T) {
+		t.Parallel()
+		client, idp, sp := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// The response answers another authentication request.
+		cookies := login(ctx, t, client, "/")
+		res := acs(ctx, t, client, cookies, respond(t, idp, sp, "id-"+strings.Repeat("0", 40)))
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.Empty(t, res.Header.Get("Location"))
+	})
+
+	t.Run("SanitizedRedirect", func(t *testing.T) {
+		t.Parallel()
+		client, idp, sp := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Only a path on the deployment is kept from the redirect, and it
+		// can't be followed as a protocol-relative URL.
+		cookies := login(ctx, t, client, "https://evil.example.com//evil.example.com/steal?token=1")
+		for _, c := range cookies {
+			if c.Name == codersdk.SAMLRedirectCookie {
+				require.Equal(t, "/evil.example.com/steal?token=1", c.Value)
+			}
+		}
+		res := acs(ctx, t, client, cookies, respond(t, idp, sp, requestID(t, cookies)))
+		require.Equal(t, http.StatusSeeOther, res.StatusCode)
+		require.Equal(t, "/evil.example.com/steal?token=1", res.Header.Get("Location"))
+	})
+}
@@ -0,0 +1,377 @@
+// Package samlauth configures Coder as a SAML 2.0 service provider and
+// validates the messages an identity provider sends to it.
+package samlauth
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/crewjam/saml"
+	xrv "github.com/mattermost/xml-roundtrip-validator"
+	dsig "github.com/russellhaering/goxmldsig"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// MetadataPath is where the service provider metadata is served.
+	MetadataPath = "/api/v2/users/saml/metadata"
+	// ACSPath is the assertion consumer service, which receives the identity
+	// provider's response to an authentication request.
+	ACSPath = "/api/v2/users/saml/acs"
+	// SLOPath is the single logout service.
+	SLOPath = "/api/v2/users/saml/slo"
+
+	// maxMessageSize limits the size of inflated messages sent with the
+	// HTTP-Redirect binding.
+	maxMessageSize = 1 << 20
+)
+
+// ServiceProviderOptions configures NewServiceProvider.
+type ServiceProviderOptions struct {
+	AccessURL *url.URL
+	// EntityID defaults to the URL of the service provider metadata.
+	EntityID    string
+	IDPMetadata *saml.EntityDescriptor
+	// Certificate and Key are optional. Without them requests to the
+	// identity provider are not signed and encrypted assertions are rejected.
+	Certificate *x509.Certificate
+	Key         *rsa.PrivateKey
+	HTTPClient  *http.Client
+}
+
+// NewServiceProvider returns a service provider for the Coder deployment at
+// the access URL.
+func NewServiceProvider(opts ServiceProviderOptions) (*saml.ServiceProvider, error) {
+	if opts.IDPMetadata == nil {
+		return nil, xerrors.New("identity provider metadata is required")
+	}
+	if (opts.Certificate == nil) != (opts.Key == nil) {
+		return nil, xerrors.New("the service provider certificate and key must be set together")
+	}
+	metadataURL, err := opts.AccessURL.Parse(MetadataPath)
+	if err != nil {
+		return nil, xerrors.Errorf("parse metadata url: %w", err)
+	}
+	acsURL, err := opts.AccessURL.Parse(ACSPath)
+	if err != nil {
+		return nil, xerrors.Errorf("parse acs url: %w", err)
+	}
+	sloURL, err := opts.AccessURL.Parse(SLOPath)
+	if err != nil {
+		return nil, xerrors.Errorf("parse slo url: %w", err)
+	}
+
+	sp := &saml.ServiceProvider{
+		EntityID:    opts.EntityID,
+		Key:         opts.Key,
+		Certificate: opts.Certificate,
+		HTTPClient:  opts.HTTPClient,
+		MetadataURL: *metadataURL,
+		AcsURL:      *acsURL,
+		SloURL:      *sloURL,
+		IDPMetadata: opts.IDPMetadata,
+		// Let the identity provider pick the NameID format. Persistent
+		// identifiers are preferred, since the NameID links the user.
+		AuthnNameIDFormat: saml.UnspecifiedNameIDFormat,
+		LogoutBindings:    []string{saml.HTTPRedirectBinding, saml.HTTPPostBinding},
+	}
+	if opts.Key != nil {
+		sp.SignatureMethod = dsig.RSASHA256SignatureMethod
+	}
+	return sp, nil
+}
+
+// ParseMetadata parses identity provider metadata. When the metadata
+// describes several entities, the first identity provider is used.
+func ParseMetadata(data []byte) (*saml.EntityDescriptor, error) {
+	if err := xrv.Validate(bytes.NewReader(data)); err != nil {
+		return nil, xerrors.Errorf("validate metadata: %w", err)
+	}
+
+	var entity saml.EntityDescriptor
+	err := xml.Unmarshal(data, &entity)
+	if err != nil {
+		var entities saml.EntitiesDescriptor
+		if err := xml.Unmarshal(data, &entities); err != nil {
+			return nil, xerrors.Errorf("unmarshal metadata: %w", err)
+		}
+		for _, e := range entities.EntityDescriptors {
+			if len(e.IDPSSODescriptors) > 0 {
+				entity = e
+				break
+			}
+		}
+	}
+	if len(entity.IDPSSODescriptors) == 0 {
+		return nil, xerrors.New("metadata does not describe an identity provider")
+	}
+	return &entity, nil
+}
+
+// FetchMetadata downloads and parses identity provider metadata.
+func FetchMetadata(ctx context.Context, client *http.Client, metadataURL string) (*saml.EntityDescriptor, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataURL, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("create request: %w", err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("fetch metadata: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("fetch metadata: unexpected status %s", res.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxMessageSize))
+	if err != nil {
+		return nil, xerrors.Errorf("read metadata: %w", err)
+	}
+	return ParseMetadata(data)
+}
+
+// Attributes returns the attributes of an assertion as claims, so they can
+// be used by IdP sync like OIDC claims. Every attribute is a list of strings
+// and is keyed by its name, and by its friendly name if it has one.
+func Attributes(assertion *saml.Assertion) map[string]interface{} {
+	claims := make(map[string]interface{})
+	for _, statement := range assertion.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			values := make([]interface{}, 0, len(attr.Values))
+			for _, v := range attr.Values {
+				values = append(values, v.Value)
+			}
+			claims[attr.Name] = values
+			if attr.FriendlyName != "" {
+				if _, ok := claims[attr.FriendlyName]; !ok {
+					claims[attr.FriendlyName] = values
+				}
+			}
+		}
+	}
+	return claims
+}
+
+// Attribute returns the first value of an attribute in the claims returned
+// by Attributes.
+func Attribute(claims map[string]interface{}, name string) string {
+	values, _ := claims[name].([]interface{})
+	if len(values) == 0 {
+		return ""
+	}
+	value, _ := values[0].(string)
+	return value
+}
+
+// ParseLogoutRequest validates a logout request sent by the identity
+// provider with either the HTTP-Redirect or the HTTP-POST binding.
+func ParseLogoutRequest(sp *saml.ServiceProvider, r *http.Request) (*saml.LogoutRequest, error) {
+	certs, err := idpSigningCerts(sp.IDPMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	var req *saml.LogoutRequest
+	if r.URL.Query().Get("SAMLRequest") != "" {
+		req, err = parseRedirectLogoutRequest(r.URL.RawQuery, certs)
+	} else {
+		if err := r.ParseForm(); err != nil {
+			return nil, xerrors.Errorf("parse form: %w", err)
+		}
+		req, err = parsePostLogoutRequest(r.PostForm.Get("SAMLRequest"), certs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := saml.TimeNow()
+	if req.Issuer == nil || req.Issuer.Value != sp.IDPMetadata.EntityID {
+		return nil, xerrors.Errorf("issuer is not %q", sp.IDPMetadata.EntityID)
+	}
+	if req.Destination != "" && req.Destination != sp.SloURL.String() {
+		return nil, xerrors.Errorf("destination is not %q", sp.SloURL.String())
+	}
+	if req.IssueInstant.Add(saml.MaxIssueDelay).Before(now) {
+		return nil, xerrors.Errorf("expired on %s", req.IssueInstant.Add(saml.MaxIssueDelay))
+	}
+	if req.IssueInstant.Add(-saml.MaxClockSkew).After(now) {
+		return nil, xerrors.New("issued in the future")
+	}
+	if req.NotOnOrAfter != nil && req.NotOnOrAfter.Add(saml.MaxClockSkew).Before(now) {
+		return nil, xerrors.New("expired")
+	}
+	if req.NameID == nil || req.NameID.Value == "" {
+		return nil, xerrors.New("name id is missing")
+	}
+	return req, nil
+}
+
+// parseRedirectLogoutRequest verifies the query signature of a logout
+// request sent with the HTTP-Redirect binding. The signature covers the
+// parameters exactly as they were encoded by the identity provider.
+func parseRedirectLogoutRequest(rawQuery string, certs []*x509.Certificate) (*saml.LogoutRequest, error) {
+	raw := make(map[string]string)
+	for _, part := range strings.Split(rawQuery, "&") {
+		key, value, _ := strings.Cut(part, "=")
+		if _, ok := raw[key]; !ok {
+			raw[key] = value
+		}
+	}
+	if raw["Signature"] == "" || raw["SigAlg"] == "" {
+		return nil, xerrors.New("logout request is not signed")
+	}
+
+	signed := "SAMLRequest=" + raw["SAMLRequest"]
+	if relayState, ok := raw["RelayState"]; ok {
+		signed += "&RelayState=" + relayState
+	}
+	signed += "&SigAlg=" + raw["SigAlg"]
+
+	sigAlg, err := url.QueryUnescape(raw["SigAlg"])
+	if err != nil {
+		return nil, xerrors.Errorf("unescape signature algorithm: %w", err)
+	}
+	var (
+		hash   crypto.Hash
+		digest []byte
+	)
+	switch sigAlg {
+	case dsig.RSASHA256SignatureMethod:
+		sum := sha256.Sum256([]byte(signed))
+		hash, digest = crypto.SHA256, sum[:]
+	case dsig.RSASHA512SignatureMethod:
+		sum := sha512.Sum512([]byte(signed))
+		hash, digest = crypto.SHA512, sum[:]
+	default:
+		return nil, xerrors.Errorf("unsupported signature algorithm %q", sigAlg)
+	}
+	signatureParam, err := url.QueryUnescape(raw["Signature"])
+	if err != nil {
+		return nil, xerrors.Errorf("unescape signature: %w", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(signatureParam)
+	if err != nil {
+		return nil, xerrors.Errorf("decode signature: %w", err)
+	}
+	verified := false
+	for _, cert := range certs {
+		key, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			continue
+		}
+		if rsa.VerifyPKCS1v15(key, hash, digest, signature) == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, xerrors.New("logout request signature is invalid")
+	}
+
+	encoded, err := url.QueryUnescape(raw["SAMLRequest"])
+	if err != nil {
+		return nil, xerrors.Errorf("unescape request: %w", err)
+	}
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, xerrors.Errorf("decode request: %w", err)
+	}
+	data, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(compressed)), maxMessageSize))
+	if err != nil {
+		return nil, xerrors.Errorf("inflate request: %w", err)
+	}
+	if err := xrv.Validate(bytes.NewReader(data)); err != nil {
+		return nil, xerrors.Errorf("validate request: %w", err)
+	}
+	var req saml.LogoutRequest
+	if err := xml.Unmarshal(data, &req); err != nil {
+		return nil, xerrors.Errorf("unmarshal request: %w", err)
+	}
+	return &req, nil
+}
+
+// parsePostLogoutRequest verifies the enveloped XML signature of a logout
+// request sent with the HTTP-POST binding. Only the signed element is
+// trusted.
+func parsePostLogoutRequest(encoded string, certs []*x509.Certificate) (*saml.LogoutRequest, error) {
+	if encoded == "" {
+		return nil, xerrors.New("logout request is missing")
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, xerrors.Errorf("decode request: %w", err)
+	}
+	if err := xrv.Validate(bytes.NewReader(data)); err != nil {
+		return nil, xerrors.Errorf("validate request: %w", err)
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil {
+		return nil, xerrors.Errorf("read request: %w", err)
+	}
+	if doc.Root() == nil {
+		return nil, xerrors.New("logout request is empty")
+	}
+
+	validator := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: certs})
+	validator.IdAttribute = "ID"
+	validator.Clock = dsig.NewFakeClockAt(saml.TimeNow())
+	signed, err := validator.Validate(doc.Root())
+	if err != nil {
+		return nil, xerrors.Errorf("logout request signature is invalid: %w", err)
+	}
+
+	signedDoc := etree.NewDocument()
+	signedDoc.SetRoot(signed)
+	signedData, err := signedDoc.WriteToBytes()
+	if err != nil {
+		return nil, xerrors.Errorf("write request: %w", err)
+	}
+	var req saml.LogoutRequest
+	if err := xml.Unmarshal(signedData, &req); err != nil {
+		return nil, xerrors.Errorf("unmarshal request: %w", err)
+	}
+	return &req, nil
+}
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+// idpSigningCerts returns the certificates the identity provider signs
+// messages with.
+func idpSigningCerts(metadata *saml.EntityDescriptor) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for _, descriptor := range metadata.IDPSSODescriptors {
+		for _, key := range descriptor.KeyDescriptors {
+			if key.Use != "" && key.Use != "signing" {
+				continue
+			}
+			for _, c := range key.KeyInfo.X509Data.X509Certificates {
+				der, err := base64.StdEncoding.DecodeString(whitespace.ReplaceAllString(c.Data, ""))
+				if err != nil {
+					return nil, xerrors.Errorf("decode signing certificate: %w", err)
+				}
+				cert, err := x509.ParseCertificate(der)
+				if err != nil {
+					return nil, xerrors.Errorf("parse signing certificate: %w", err)
+				}
+				certs = append(certs, cert)
+			}
+		}
+	}
+	if len(certs) == 0 {
+		return nil, xerrors.New("identity provider metadata has no signing certificate")
+	}
+	return certs, nil
+}
@@ -0,0 +1,85 @@
+package samlauth_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/crewjam/saml"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/samlauth"
+)
+
+const idpMetadata = `<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://idp.example.com/metadata">
+  <IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/sso"/>
+  </IDPSSODescriptor>
+</EntityDescriptor>`
+
+func TestParseMetadata(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Entity", func(t *testing.T) {
+		t.Parallel()
+		entity, err := samlauth.ParseMetadata([]byte(idpMetadata))
+		require.NoError(t, err)
+		require.Equal(t, "https://idp.example.com/metadata", entity.EntityID)
+	})
+
+	t.Run("Entities", func(t *testing.T) {
+		t.Parallel()
+		data := `<EntitiesDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata">` +
+			`<EntityDescriptor entityID="https://sp.example.com"><SPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol"/></EntityDescriptor>` +
+			idpMetadata + `</EntitiesDescriptor>`
+		entity, err := samlauth.ParseMetadata([]byte(data))
+		require.NoError(t, err)
+		require.Equal(t, "https://idp.example.com/metadata", entity.EntityID)
+	})
+
+	t.Run("NotIdentityProvider", func(t *testing.T) {
+		t.Parallel()
+		_, err := samlauth.ParseMetadata([]byte(`<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://sp.example.com"/>`))
+		require.ErrorContains(t, err, "does not describe an identity provider")
+	})
+}
+
+func TestNewServiceProvider(t *testing.T) {
+	t.Parallel()
+
+	entity, err := samlauth.ParseMetadata([]byte(idpMetadata))
+	require.NoError(t, err)
+	accessURL, err := url.Parse("https://coder.example.com")
+	require.NoError(t, err)
+
+	sp, err := samlauth.NewServiceProvider(samlauth.ServiceProviderOptions{
+		AccessURL:   accessURL,
+		IDPMetadata: entity,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://coder.example.com/api/v2/users/saml/acs", sp.AcsURL.String())
+	require.Equal(t, "https://coder.example.com/api/v2/users/saml/slo", sp.SloURL.String())
+
+	_, err = samlauth.NewServiceProvider(samlauth.ServiceProviderOptions{AccessURL: accessURL})
+	require.Error(t, err)
+}
+
+func TestAttributes(t *testing.T) {
+	t.Parallel()
+
+	claims := samlauth.Attributes(&saml.Assertion{
+		AttributeStatements: []saml.AttributeStatement{{
+			Attributes: []saml.Attribute{{
+				Name:         "urn:oid:0.9.2342.19200300.100.1.3",
+				FriendlyName: "mail",
+				Values:       []saml.AttributeValue{{Value: "kyle@coder.com"}},
+			}, {
+				Name:   "groups",
+				Values: []saml.AttributeValue{{Value: "admins"}, {Value: "devs"}},
+			}},
+		}},
+	})
+	require.Equal(t, "kyle@coder.com", samlauth.Attribute(claims, "mail"))
+	require.Equal(t, "kyle@coder.com", samlauth.Attribute(claims, "urn:oid:0.9.2342.19200300.100.1.3"))
+	require.Equal(t, []interface{}{"admins", "devs"}, claims["groups"])
+	require.Empty(t, samlauth.Attribute(claims, "missing"))
+}
@@ -24,23 +24,28 @@ func NextAutostart(at time.Time, wsSchedule string, templateSchedule TemplateSch
 	nextTransition := sched.Next(at).Truncate(time.Minute)
 
 	// The nextTransition is when the auto start should kick off. If it lands on a
-	// forbidden day, do not allow the auto start. We use the time location of the
-	// schedule to determine the weekday. So if "Saturday" is disallowed, the
-	// definition of "Saturday" depends on the location of the schedule.
+	// forbidden day or a holiday, do not allow the auto start. We use the time
+	// location of the schedule to determine the weekday and date. So if
+	// "Saturday" is disallowed, the definition of "Saturday" depends on the
+	// location of the schedule.
 	zonedTransition := nextTransition.In(sched.Location())
-	allowed := templateSchedule.AutostartRequirement.DaysMap()[zonedTransition.Weekday()]
+	allowed := templateSchedule.AutostartRequirement.DaysMap()[zonedTransition.Weekday()] &&
+		!templateSchedule.AutostartHolidays.Contains(zonedTransition)
 
 	return zonedTransition, allowed
 }
 
 // NextAllowedAutostart returns the next valid autostart time after 'at', based on the workspace's
-// cron schedule and the template's allowed days. It searches up to 7 days ahead to find a match.
+// cron schedule and the template's allowed days and holidays. It searches up to 7 days ahead to
+// find a match, plus a day for every holiday.
 func NextAllowedAutostart(at time.Time, wsSchedule string, templateSchedule TemplateScheduleOptions) (time.Time, error) {
 	next := at
 
 	// Our cron schedules work on a weekly basis, so to ensure we've exhausted all
 	// possible autostart times we need to check up to 7 days worth of autostarts.
-	for next.Sub(at) < 7*24*time.Hour {
+	// Each holiday can push the next autostart back by a day.
+	horizon := time.Duration(7+len(templateSchedule.AutostartHolidays)) * 24 * time.Hour
+	for next.Sub(at) < horizon {
 		var valid bool
 		next, valid = NextAutostart(next, wsSchedule, templateSchedule)
 		if valid {
@@ -38,4 +38,59 @@ func TestNextAllowedAutostart(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, time.Date(2024, time.January, 8, 9, 0, 0, 0, time.UTC), next)
 	})
+
+	t.Run("SkipsHolidays", func(t *testing.T) {
+		t.Parallel()
+
+		// 1st January 2024 is a Monday, this is 10:00AM in New York.
+		at := time.Date(2024, time.January, 1, 15, 0, 0, 0, time.UTC)
+		//  Monday-Friday 9:00AM in New York
+		sched := "CRON_TZ=America/New_York 00 09 * * 1-5"
+		holidays, err := schedule.ParseHolidays([]string{"2024-01-02", "2024-01-03"})
+		require.NoError(t, err)
+		opts := schedule.TemplateScheduleOptions{
+			AutostartRequirement: schedule.TemplateAutostartRequirement{
+				DaysOfWeek: 0b01111111,
+			},
+			AutostartHolidays: holidays,
+		}
+
+		ny, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+
+		// The next autostart on Tuesday is a holiday.
+		next, allowed := schedule.NextAutostart(at, sched, opts)
+		require.False(t, allowed)
+		require.Equal(t, time.Date(2024, time.January, 2, 9, 0, 0, 0, ny), next)
+
+		// Both holidays are skipped.
+		next, err = schedule.NextAllowedAutostart(at, sched, opts)
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2024, time.January, 4, 9, 0, 0, 0, ny), next)
+	})
+
+	t.Run("OnlyHolidays", func(t *testing.T) {
+		t.Parallel()
+
+		at := time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC)
+		sched := "CRON_TZ=UTC 00 09 * * 1-5"
+		// Block every weekday for two weeks, which is longer than the
+		// regular 7 day search horizon.
+		var dates []string
+		for day := at; day.Before(at.AddDate(0, 0, 14)); day = day.AddDate(0, 0, 1) {
+			dates = append(dates, day.Format(schedule.HolidayDateFormat))
+		}
+		holidays, err := schedule.ParseHolidays(dates)
+		require.NoError(t, err)
+		opts := schedule.TemplateScheduleOptions{
+			AutostartRequirement: schedule.TemplateAutostartRequirement{
+				DaysOfWeek: 0b01111111,
+			},
+			AutostartHolidays: holidays,
+		}
+
+		next, err := schedule.NextAllowedAutostart(at, sched, opts)
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC), next)
+	})
 }
@@ -2,6 +2,7 @@ package schedule
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -9,6 +10,8 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/coderd/tracing"
 )
 
@@ -156,6 +159,24 @@ func CalculateAutostop(ctx context.Context, params CalculateAutostopParams) (Aut
 			return autostop, xerrors.Errorf("get user quiet hours schedule options: %w", err)
 		}
 
+		// Workspaces of users without their own schedule use the default
+		// schedule of the organization, if it overrides the deployment
+		// default.
+		if userQuietHoursSchedule.Schedule != nil && !userQuietHoursSchedule.UserSet {
+			//nolint:gocritic // The settings of the organization are not visible to all callers.
+			overrides, err := db.GetOrganizationSettingOverrides(dbauthz.AsSystemRestricted(ctx), workspace.OrganizationID)
+			if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+				return autostop, xerrors.Errorf("get organization setting overrides: %w", err)
+			}
+			if overrides.DefaultQuietHoursSchedule.Valid {
+				sched, err := cron.Daily(overrides.DefaultQuietHoursSchedule.String)
+				if err != nil {
+					return autostop, xerrors.Errorf("parse organization quiet hours schedule: %w", err)
+				}
+				userQuietHoursSchedule.Schedule = sched
+			}
+		}
+
 		// If the schedule is nil, that means the deployment isn't entitled to
 		// use quiet hours. In this case, do not set a max deadline on the
 		// workspace.
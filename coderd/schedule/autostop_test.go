@@ -86,6 +86,9 @@ func TestCalculateAutoStop(t *testing.T) {
 		templateDefaultTTL          time.Duration
 		templateAutostopRequirement schedule.TemplateAutostopRequirement
 		userQuietHoursSchedule      string
+		// organizationQuietHoursSchedule overrides the default quiet hours
+		// schedule in the organization of the workspace.
+		organizationQuietHoursSchedule string
 		// workspaceTTL is usually copied from the template's TTL when the
 		// workspace is made, so it takes precedence unless
 		// templateAllowAutostop is false.
@@ -170,6 +173,21 @@ func TestCalculateAutoStop(t *testing.T) {
 			// expectedDeadline is copied from expectedMaxDeadline.
 			expectedMaxDeadline: saturdayMidnightSydney.In(time.UTC),
 		},
+		{
+			name:                           "OrganizationQuietHoursSchedule",
+			now:                            wednesdayMidnightUTC,
+			templateAllowAutostop:          true,
+			templateDefaultTTL:             0,
+			userQuietHoursSchedule:         "CRON_TZ=UTC 0 0 * * *",
+			organizationQuietHoursSchedule: sydneyQuietHours,
+			templateAutostopRequirement: schedule.TemplateAutostopRequirement{
+				DaysOfWeek: 0b00100000, // Saturday
+				Weeks:      0,          // weekly
+			},
+			workspaceTTL: 0,
+			// expectedDeadline is copied from expectedMaxDeadline.
+			expectedMaxDeadline: saturdayMidnightSydney.In(time.UTC),
+		},
 		{
 			name:                   "TemplateAutostopRequirement1HourSkip",
 			now:                    saturdayMidnightSydney.Add(-59 * time.Minute),
@@ -525,6 +543,15 @@ func TestCalculateAutoStop(t *testing.T) {
 			}
 
 			org := dbgen.Organization(t, db, database.Organization{})
+			if c.organizationQuietHoursSchedule != "" {
+				_, err := db.UpsertOrganizationSettingOverrides(ctx, database.UpsertOrganizationSettingOverridesParams{
+					OrganizationID:                  org.ID,
+					DefaultQuietHoursSchedule:       sql.NullString{String: c.organizationQuietHoursSchedule, Valid: true},
+					DisabledNotificationTemplateIDs: []uuid.UUID{},
+					UpdatedAt:                       dbtime.Now(),
+				})
+				require.NoError(t, err)
+			}
 			user := dbgen.User(t, db, database.User{
 				QuietHoursSchedule: c.userQuietHoursSchedule,
 			})
@@ -0,0 +1,402 @@
+package schedule
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"tailscale.com/util/singleflight"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/quartz"
+)
+
+const (
+	// HolidayDateFormat is the format of autostart holiday dates.
+	HolidayDateFormat = time.DateOnly
+
+	// holidayCalendarRefreshInterval is how long a fetched holiday calendar is
+	// used before it is fetched again.
+	holidayCalendarRefreshInterval = time.Hour
+	// holidayCalendarRetryInterval is how long to wait before fetching a
+	// holiday calendar again after a failed fetch.
+	holidayCalendarRetryInterval = time.Minute
+	// holidayCalendarMaxBytes limits the size of a holiday calendar.
+	holidayCalendarMaxBytes = 4 << 20
+	// holidayMaxEventDays limits the number of days a single calendar event
+	// can block, so a malformed event cannot produce an unbounded set.
+	holidayMaxEventDays = 366
+)
+
+// Holidays is a set of dates on which workspaces are not autostarted. Dates
+// have no time zone: a holiday applies to the whole day in the time zone of
+// the workspace's autostart schedule.
+type Holidays map[string]struct{}
+
+// ParseHolidays parses dates formatted as YYYY-MM-DD.
+func ParseHolidays(dates []string) (Holidays, error) {
+	holidays := make(Holidays, len(dates))
+	for _, date := range dates {
+		date = strings.TrimSpace(date)
+		if date == "" {
+			continue
+		}
+		t, err := time.Parse(HolidayDateFormat, date)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid holiday %q, expected format YYYY-MM-DD", date)
+		}
+		holidays.add(t)
+	}
+	return holidays, nil
+}
+
+// Contains returns true if the date of t, in the location of t, is a holiday.
+func (h Holidays) Contains(t time.Time) bool {
+	_, ok := h[t.Format(HolidayDateFormat)]
+	return ok
+}
+
+// Merge returns a new set containing the holidays of h and others.
+func (h Holidays) Merge(others ...Holidays) Holidays {
+	merged := make(Holidays, len(h))
+	for date := range h {
+		merged[date] = struct{}{}
+	}
+	for _, other := range others {
+		for date := range other {
+			merged[date] = struct{}{}
+		}
+	}
+	return merged
+}
+
+// Dates returns the holidays formatted as YYYY-MM-DD in ascending order.
+func (h Holidays) Dates() []string {
+	dates := make([]string, 0, len(h))
+	for date := range h {
+		dates = append(dates, date)
+	}
+	slices.Sort(dates)
+	return dates
+}
+
+func (h Holidays) add(t time.Time) {
+	h[t.Format(HolidayDateFormat)] = struct{}{}
+}
+
+// ValidateHolidayCalendarURL returns an error if rawURL is not an HTTP or
+// HTTPS URL.
+func ValidateHolidayCalendarURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return xerrors.Errorf("invalid holiday calendar URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return xerrors.Errorf("invalid holiday calendar URL %q, expected an http or https URL", rawURL)
+	}
+	return nil
+}
+
+// ParseHolidayCalendar reads an iCalendar (RFC 5545) feed and returns the
+// dates covered by its events. All-day events block every date from DTSTART
+// up to, but excluding, DTEND. Timed events block every date they touch in
+// the time zone they are defined in. Recurrence rules are not expanded, so
+// feeds must list each occurrence, as published holiday calendars do.
+func ParseHolidayCalendar(r io.Reader) (Holidays, error) {
+	lines, err := unfoldICalendarLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays := make(Holidays)
+	var (
+		inEvent    bool
+		start, end icalendarTime
+	)
+	for _, line := range lines {
+		name, params, value, ok := parseICalendarLine(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent = true
+			start, end = icalendarTime{}, icalendarTime{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if !inEvent {
+				continue
+			}
+			inEvent = false
+			if start.time.IsZero() {
+				return nil, xerrors.New("calendar event is missing DTSTART")
+			}
+			addEventDays(holidays, start, end)
+		case inEvent && name == "DTSTART":
+			start, err = parseICalendarTime(params, value)
+			if err != nil {
+				return nil, xerrors.Errorf("parse DTSTART: %w", err)
+			}
+		case inEvent && name == "DTEND":
+			end, err = parseICalendarTime(params, value)
+			if err != nil {
+				return nil, xerrors.Errorf("parse DTEND: %w", err)
+			}
+		}
+	}
+	return holidays, nil
+}
+
+// addEventDays adds the dates covered by a calendar event.
+func addEventDays(holidays Holidays, start, end icalendarTime) {
+	first := time.Date(start.time.Year(), start.time.Month(), start.time.Day(), 0, 0, 0, 0, time.UTC)
+	// Events without an end cover a single day.
+	last := first
+	if !end.time.IsZero() {
+		last = time.Date(end.time.Year(), end.time.Month(), end.time.Day(), 0, 0, 0, 0, time.UTC)
+		// The end of an event is exclusive, so an event ending at midnight
+		// does not cover that day.
+		if end.allDay || end.time.Equal(time.Date(end.time.Year(), end.time.Month(), end.time.Day(), 0, 0, 0, 0, end.time.Location())) {
+			last = last.AddDate(0, 0, -1)
+		}
+		if last.Before(first) {
+			last = first
+		}
+	}
+	for day, n := first, 0; !day.After(last) && n < holidayMaxEventDays; day, n = day.AddDate(0, 0, 1), n+1 {
+		holidays.add(day)
+	}
+}
+
+// unfoldICalendarLines splits the calendar into content lines, joining lines
+// that were folded onto continuation lines starting with whitespace.
+func unfoldICalendarLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), holidayCalendarMaxBytes)
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseICalendarLine splits a content line such as
+// "DTSTART;VALUE=DATE:20250101" into its upper-cased name, parameters and
+// value.
+func parseICalendarLine(line string) (name string, params map[string]string, value string, ok bool) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", nil, "", false
+	}
+	parts := strings.Split(head, ";")
+	params = make(map[string]string, len(parts)-1)
+	for _, param := range parts[1:] {
+		k, v, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, strings.TrimSpace(value), true
+}
+
+type icalendarTime struct {
+	time   time.Time
+	allDay bool
+}
+
+func parseICalendarTime(params map[string]string, value string) (icalendarTime, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		if err != nil {
+			return icalendarTime{}, err
+		}
+		return icalendarTime{time: t, allDay: true}, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return icalendarTime{}, err
+		}
+		return icalendarTime{time: t}, nil
+	}
+
+	loc := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		var err error
+		loc, err = time.LoadLocation(tzid)
+		if err != nil {
+			return icalendarTime{}, xerrors.Errorf("unknown time zone %q: %w", tzid, err)
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return icalendarTime{}, err
+	}
+	return icalendarTime{time: t}, nil
+}
+
+// AutostartHolidays provides the holidays on which workspaces are not
+// autostarted. It combines the deployment-wide holidays with the holidays
+// configured on each template, and caches holiday calendars fetched from
+// iCalendar URLs.
+type AutostartHolidays struct {
+	logger      slog.Logger
+	clock       quartz.Clock
+	client      *http.Client
+	holidays    Holidays
+	calendarURL string
+
+	fetchGroup singleflight.Group[string, Holidays]
+	mu         sync.Mutex
+	calendars  map[string]holidayCalendar
+}
+
+type holidayCalendar struct {
+	holidays  Holidays
+	fetchedAt time.Time
+	failed    bool
+}
+
+// NewAutostartHolidays returns the autostart holidays for a deployment with
+// the given holiday dates and holiday calendar URL. Either may be empty.
+func NewAutostartHolidays(logger slog.Logger, clock quartz.Clock, dates []string, calendarURL string) (*AutostartHolidays, error) {
+	holidays, err := ParseHolidays(dates)
+	if err != nil {
+		return nil, err
+	}
+	if calendarURL != "" {
+		if err := ValidateHolidayCalendarURL(calendarURL); err != nil {
+			return nil, err
+		}
+	}
+	return &AutostartHolidays{
+		logger:      logger,
+		clock:       clock,
+		client:      &http.Client{Timeout: 30 * time.Second},
+		holidays:    holidays,
+		calendarURL: calendarURL,
+		calendars:   make(map[string]holidayCalendar),
+	}, nil
+}
+
+// ForTemplate returns the deployment holidays merged with the holidays of
+// the template. Holiday calendars that cannot be fetched are logged and
+// skipped, using the last successfully fetched copy if there is one.
+func (h *AutostartHolidays) ForTemplate(ctx context.Context, tpl database.Template) (Holidays, error) {
+	templateHolidays, err := ParseHolidays(tpl.AutostartHolidays)
+	if err != nil {
+		return nil, xerrors.Errorf("parse template autostart holidays: %w", err)
+	}
+	holidays := h.holidays.Merge(templateHolidays)
+	for _, calendarURL := range []string{h.calendarURL, tpl.AutostartHolidayCalendarURL} {
+		if calendarURL == "" {
+			continue
+		}
+		holidays = holidays.Merge(h.calendar(ctx, calendarURL))
+	}
+	return holidays, nil
+}
+
+// calendar returns the cached holiday calendar for calendarURL, fetching it if the
+// cached copy is missing or stale.
+func (h *AutostartHolidays) calendar(ctx context.Context, calendarURL string) Holidays {
+	now := h.clock.Now()
+	h.mu.Lock()
+	cached, ok := h.calendars[calendarURL]
+	h.mu.Unlock()
+	if ok {
+		interval := holidayCalendarRefreshInterval
+		if cached.failed {
+			interval = holidayCalendarRetryInterval
+		}
+		if now.Sub(cached.fetchedAt) < interval {
+			return cached.holidays
+		}
+	}
+
+	holidays, err, _ := h.fetchGroup.Do(calendarURL, func() (Holidays, error) {
+		return h.fetchCalendar(ctx, calendarURL)
+	})
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err != nil {
+		h.logger.Warn(ctx, "fetch autostart holiday calendar", slog.F("url", calendarURL), slog.Error(err))
+		// Keep using the last successfully fetched calendar.
+		h.calendars[calendarURL] = holidayCalendar{holidays: cached.holidays, fetchedAt: now, failed: true}
+		return cached.holidays
+	}
+	h.calendars[calendarURL] = holidayCalendar{holidays: holidays, fetchedAt: now}
+	return holidays
+}
+
+func (h *AutostartHolidays) fetchCalendar(ctx context.Context, calendarURL string) (Holidays, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, calendarURL, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("create request: %w", err)
+	}
+	res, err := h.client.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return ParseHolidayCalendar(io.LimitReader(res.Body, holidayCalendarMaxBytes))
+}
+
+type holidayTemplateScheduleStore struct {
+	TemplateScheduleStore
+	holidays *AutostartHolidays
+}
+
+var _ TemplateScheduleStore = &holidayTemplateScheduleStore{}
+
+// NewHolidayTemplateScheduleStore wraps store so the options it returns
+// include the autostart holidays of the deployment and the template. If
+// holidays is nil, store is returned unchanged.
+func NewHolidayTemplateScheduleStore(store TemplateScheduleStore, holidays *AutostartHolidays) TemplateScheduleStore {
+	if holidays == nil {
+		return store
+	}
+	return &holidayTemplateScheduleStore{
+		TemplateScheduleStore: store,
+		holidays:              holidays,
+	}
+}
+
+func (s *holidayTemplateScheduleStore) Get(ctx context.Context, db database.Store, templateID uuid.UUID) (TemplateScheduleOptions, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	opts, err := s.TemplateScheduleStore.Get(ctx, db, templateID)
+	if err != nil {
+		return TemplateScheduleOptions{}, err
+	}
+
+	tpl, err := db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return TemplateScheduleOptions{}, err
+	}
+	opts.AutostartHolidays, err = s.holidays.ForTemplate(ctx, tpl)
+	if err != nil {
+		return TemplateScheduleOptions{}, err
+	}
+	return opts, nil
+}
@@ -0,0 +1,131 @@
+package schedule_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestParseHolidays(t *testing.T) {
+	t.Parallel()
+
+	holidays, err := schedule.ParseHolidays([]string{"2025-12-25", " 2025-01-01 ", "", "2025-12-25"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"2025-01-01", "2025-12-25"}, holidays.Dates())
+
+	// Dates are compared in the location of the time.
+	require.True(t, holidays.Contains(time.Date(2025, time.December, 25, 23, 0, 0, 0, time.UTC)))
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	require.False(t, holidays.Contains(time.Date(2025, time.December, 25, 23, 0, 0, 0, time.UTC).In(tokyo)))
+
+	_, err = schedule.ParseHolidays([]string{"25/12/2025"})
+	require.ErrorContains(t, err, "expected format YYYY-MM-DD")
+}
+
+func TestParseHolidayCalendar(t *testing.T) {
+	t.Parallel()
+
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20251225",
+		"DTEND;VALUE=DATE:20251227",
+		"SUMMARY:Christmas",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20260101",
+		"SUMMARY:New Year's Day with a summary that is long enough to be folded",
+		"  onto a continuation line",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;TZID=Europe/Berlin:20260406T220000",
+		"DTEND;TZID=Europe/Berlin:20260408T000000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20260501T090000Z",
+		"DTEND:20260501T170000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	holidays, err := schedule.ParseHolidayCalendar(strings.NewReader(calendar))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"2025-12-25",
+		"2025-12-26",
+		"2026-01-01",
+		"2026-04-06",
+		"2026-04-07",
+		"2026-05-01",
+	}, holidays.Dates())
+
+	_, err = schedule.ParseHolidayCalendar(strings.NewReader("BEGIN:VEVENT\r\nSUMMARY:No start\r\nEND:VEVENT\r\n"))
+	require.ErrorContains(t, err, "missing DTSTART")
+}
+
+func TestAutostartHolidays(t *testing.T) {
+	t.Parallel()
+
+	var (
+		fetches  atomic.Int64
+		calendar atomic.Value
+	)
+	calendar.Store("BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20251225\r\nEND:VEVENT\r\n")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		body, _ := calendar.Load().(string)
+		if body == "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	clock := quartz.NewMock(t)
+	holidays, err := schedule.NewAutostartHolidays(testutil.Logger(t), clock, []string{"2025-01-01"}, srv.URL)
+	require.NoError(t, err)
+
+	tpl := database.Template{AutostartHolidays: []string{"2025-07-04"}}
+	got, err := holidays.ForTemplate(ctx, tpl)
+	require.NoError(t, err)
+	require.Equal(t, []string{"2025-01-01", "2025-07-04", "2025-12-25"}, got.Dates())
+	require.EqualValues(t, 1, fetches.Load())
+
+	// The calendar is cached until it is refreshed.
+	calendar.Store("BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20251226\r\nEND:VEVENT\r\n")
+	got, err = holidays.ForTemplate(ctx, tpl)
+	require.NoError(t, err)
+	require.Equal(t, []string{"2025-01-01", "2025-07-04", "2025-12-25"}, got.Dates())
+	require.EqualValues(t, 1, fetches.Load())
+
+	clock.Advance(time.Hour)
+	got, err = holidays.ForTemplate(ctx, tpl)
+	require.NoError(t, err)
+	require.Equal(t, []string{"2025-01-01", "2025-07-04", "2025-12-26"}, got.Dates())
+	require.EqualValues(t, 2, fetches.Load())
+
+	// A failed refresh keeps the last fetched calendar.
+	calendar.Store("")
+	clock.Advance(time.Hour)
+	got, err = holidays.ForTemplate(ctx, tpl)
+	require.NoError(t, err)
+	require.Equal(t, []string{"2025-01-01", "2025-07-04", "2025-12-26"}, got.Dates())
+	require.EqualValues(t, 3, fetches.Load())
+
+	_, err = schedule.NewAutostartHolidays(testutil.Logger(t), clock, nil, "ftp://example.com/holidays.ics")
+	require.ErrorContains(t, err, "expected an http or https URL")
+}
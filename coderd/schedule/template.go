@@ -128,6 +128,9 @@ type TemplateScheduleOptions struct {
 	AutostopRequirement TemplateAutostopRequirement
 	// AutostartRequirement dictates when the workspace can be auto started.
 	AutostartRequirement TemplateAutostartRequirement
+	// AutostartHolidays are dates on which the workspace is not auto started,
+	// regardless of AutostartRequirement.
+	AutostartHolidays Holidays
 	// FailureTTL dictates the duration after which failed workspaces will be
 	// stopped automatically.
 	FailureTTL time.Duration
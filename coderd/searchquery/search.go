@@ -33,6 +33,8 @@ import (
 //   - resource_type: string (enum)
 //   - action: string (enum)
 //   - build_reason: string (enum)
+//   - search: string (full-text search over the resource target, diff and
+//     additional fields, e.g. search:"max_ttl")
 func AuditLogs(ctx context.Context, db database.Store, query string) (database.GetAuditLogsOffsetParams,
 	database.CountAuditLogsParams, []codersdk.ValidationError,
 ) {
@@ -61,6 +63,7 @@ func AuditLogs(ctx context.Context, db database.Store, query string) (database.G
 		ResourceType:   string(httpapi.ParseCustom(parser, values, "", "resource_type", httpapi.ParseEnum[database.ResourceType])),
 		Action:         string(httpapi.ParseCustom(parser, values, "", "action", httpapi.ParseEnum[database.AuditAction])),
 		BuildReason:    string(httpapi.ParseCustom(parser, values, "", "build_reason", httpapi.ParseEnum[database.BuildReason])),
+		Search:         parser.String(values, "", "search"),
 	}
 	if !filter.DateTo.IsZero() {
 		filter.DateTo = filter.DateTo.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
@@ -80,6 +83,7 @@ func AuditLogs(ctx context.Context, db database.Store, query string) (database.G
 		ResourceType:   filter.ResourceType,
 		Action:         filter.Action,
 		BuildReason:    filter.BuildReason,
+		Search:         filter.Search,
 	}
 
 	parser.ErrorExcessParams(values)
@@ -113,6 +117,36 @@ func Users(query string) (database.GetUsersParams, []codersdk.ValidationError) {
 	return filter, parser.Errors
 }
 
+// MembersFilter filters organization members listed across organizations.
+type MembersFilter struct {
+	OrganizationID uuid.UUID
+	// Search matches the username, name or email of the member.
+	Search string
+	// Roles matches members with any of the organization or site-wide roles.
+	Roles []string
+}
+
+func Members(ctx context.Context, db database.Store, query string) (MembersFilter, []codersdk.ValidationError) {
+	// Always lowercase for all searches.
+	query = strings.ToLower(query)
+	values, errors := searchTerms(query, func(term string, values url.Values) error {
+		values.Add("search", term)
+		return nil
+	})
+	if len(errors) > 0 {
+		return MembersFilter{}, errors
+	}
+
+	parser := httpapi.NewQueryParamParser()
+	filter := MembersFilter{
+		OrganizationID: parseOrganization(ctx, db, parser, values, "organization"),
+		Search:         parser.String(values, "", "search"),
+		Roles:          parser.Strings(values, []string{}, "role"),
+	}
+	parser.ErrorExcessParams(values)
+	return filter, parser.Errors
+}
+
 func Workspaces(ctx context.Context, db database.Store, query string, page codersdk.Pagination, agentInactiveDisconnectTimeout time.Duration) (database.GetWorkspacesParams, []codersdk.ValidationError) {
 	filter := database.GetWorkspacesParams{
 		AgentInactiveDisconnectTimeoutSeconds: int64(agentInactiveDisconnectTimeout.Seconds()),
@@ -202,6 +236,29 @@ func Workspaces(ctx context.Context, db database.Store, query string, page coder
 		filter.ParamValues = append(filter.ParamValues, *p.value)
 	}
 
+	// label matching takes the form of:
+	//	`label:<key>[=<value>]`
+	// If the value is omitted, then we match on the presence of the label.
+	type labelMatch struct {
+		key   string
+		value string
+	}
+	labels := httpapi.ParseCustomList(parser, values, []labelMatch{}, "label", func(v string) (labelMatch, error) {
+		key, value, _ := strings.Cut(strings.TrimSpace(v), "=")
+		if err := codersdk.WorkspaceLabelKeyValid(key); err != nil {
+			return labelMatch{}, xerrors.Errorf("query element %q: %w", v, err)
+		}
+		if err := codersdk.WorkspaceLabelValueValid(value); err != nil {
+			return labelMatch{}, xerrors.Errorf("query element %q: %w", v, err)
+		}
+		// An empty value matches any value of the label.
+		return labelMatch{key: key, value: value}, nil
+	})
+	for _, l := range labels {
+		filter.LabelKeys = append(filter.LabelKeys, l.key)
+		filter.LabelValues = append(filter.LabelValues, l.value)
+	}
+
 	parser.ErrorExcessParams(values)
 	return filter, parser.Errors
 }
@@ -233,6 +290,74 @@ func Templates(ctx context.Context, db database.Store, query string) (database.G
 	return filter, parser.Errors
 }
 
+// ProvisionerJobLogs parses a full-text search over the logs of the
+// provisioner jobs in an organization. Terms without a key are searched for,
+// and quoted terms match as a phrase.
+//
+// Supported query parameters:
+//
+//   - search: string (searched for in addition to the terms without a key)
+//   - type: string (enum)
+//   - template: string (template UUID or name)
+//   - workspace_id: UUID
+//   - date_from: string (date in format "2006-01-02")
+//   - date_to: string (date in format "2006-01-02")
+func ProvisionerJobLogs(ctx context.Context, db database.Store, organizationID uuid.UUID, query string) (database.SearchProvisionerJobLogsParams, []codersdk.ValidationError) {
+	// Always lowercase for all searches.
+	query = strings.ToLower(query)
+	var terms []string
+	values, errors := searchTerms(query, func(term string, _ url.Values) error {
+		terms = append(terms, term)
+		return nil
+	})
+	if len(errors) > 0 {
+		return database.SearchProvisionerJobLogsParams{}, errors
+	}
+
+	const dateLayout = "2006-01-02"
+	parser := httpapi.NewQueryParamParser()
+	filter := database.SearchProvisionerJobLogsParams{
+		OrganizationID: organizationID,
+		JobType:        string(httpapi.ParseCustom(parser, values, "", "type", httpapi.ParseEnum[database.ProvisionerJobType])),
+		TemplateID: httpapi.ParseCustom(parser, values, uuid.Nil, "template", func(v string) (uuid.UUID, error) {
+			if v == "" {
+				return uuid.Nil, nil
+			}
+			templateID, err := uuid.Parse(v)
+			if err == nil {
+				return templateID, nil
+			}
+			template, err := db.GetTemplateByOrganizationAndName(ctx, database.GetTemplateByOrganizationAndNameParams{
+				OrganizationID: organizationID,
+				Name:           v,
+			})
+			if err != nil {
+				return uuid.Nil, xerrors.Errorf("template %q either does not exist, or you are unauthorized to view it", v)
+			}
+			return template.ID, nil
+		}),
+		WorkspaceID: parser.UUID(values, uuid.Nil, "workspace_id"),
+		DateFrom:    parser.Time(values, time.Time{}, "date_from", dateLayout),
+		DateTo:      parser.Time(values, time.Time{}, "date_to", dateLayout),
+	}
+	if search := parser.String(values, "", "search"); search != "" {
+		terms = append(terms, search)
+	}
+	filter.Search = strings.Join(terms, " ")
+	if filter.Search == "" {
+		parser.Errors = append(parser.Errors, codersdk.ValidationError{
+			Field:  "q",
+			Detail: "Query must contain a term to search for",
+		})
+	}
+	if !filter.DateTo.IsZero() {
+		filter.DateTo = filter.DateTo.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+	}
+
+	parser.ErrorExcessParams(values)
+	return filter, parser.Errors
+}
+
 func searchTerms(query string, defaultKey func(term string, values url.Values) error) (url.Values, []codersdk.ValidationError) {
 	searchValues := make(url.Values)
 
@@ -197,6 +197,22 @@ func TestSearchWorkspace(t *testing.T) {
 				ParamValues: []string{"bar"},
 			},
 		},
+		{
+			Name:  "Labels",
+			Query: "label:team=platform label:cost-center",
+			Expected: database.GetWorkspacesParams{
+				LabelKeys:   []string{"team", "cost-center"},
+				LabelValues: []string{"platform", ""},
+			},
+		},
+		{
+			Name:  "LabelUppercase",
+			Query: "label:Team=Platform",
+			Expected: database.GetWorkspacesParams{
+				LabelKeys:   []string{"team"},
+				LabelValues: []string{"platform"},
+			},
+		},
 		{
 			Name:  "Organization",
 			Query: `organization:4fe722f0-49bc-4a90-a3eb-4ac439bfce20`,
@@ -264,6 +280,11 @@ func TestSearchWorkspace(t *testing.T) {
 			Query:                 "param:foo=",
 			ExpectedErrorContains: "omit the '=' to match",
 		},
+		{
+			Name:                  "LabelInvalidKey",
+			Query:                 "label:-team=platform",
+			ExpectedErrorContains: "must be lowercase alphanumeric",
+		},
 		{
 			Name:                  "NoPrefix",
 			Query:                 `:foo`,
@@ -383,6 +404,18 @@ func TestSearchAudit(t *testing.T) {
 			Query:                 "request_id:foo",
 			ExpectedErrorContains: "valid uuid",
 		},
+		{
+			Name:  "Search",
+			Query: `action:write search:"Max TTL"`,
+			Expected: database.GetAuditLogsOffsetParams{
+				Action: "write",
+				Search: "max ttl",
+			},
+			ExpectedCountParams: database.CountAuditLogsParams{
+				Action: "write",
+				Search: "max ttl",
+			},
+		},
 	}
 
 	for _, c := range testCases {
@@ -408,6 +441,72 @@ func TestSearchAudit(t *testing.T) {
 	}
 }
 
+func TestSearchProvisionerJobLogs(t *testing.T) {
+	t.Parallel()
+	orgID := uuid.New()
+	testCases := []struct {
+		Name                  string
+		Query                 string
+		Expected              database.SearchProvisionerJobLogsParams
+		ExpectedErrorContains string
+	}{
+		{
+			Name:  "Terms",
+			Query: `"Connection Refused" timeout`,
+			Expected: database.SearchProvisionerJobLogsParams{
+				OrganizationID: orgID,
+				Search:         `"connection refused" timeout`,
+			},
+		},
+		{
+			Name:  "Filters",
+			Query: `error type:workspace_build search:"exit code" date_from:2024-01-01`,
+			Expected: database.SearchProvisionerJobLogsParams{
+				OrganizationID: orgID,
+				Search:         "error exit code",
+				JobType:        string(database.ProvisionerJobTypeWorkspaceBuild),
+				DateFrom:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		// Failures
+		{
+			Name:                  "NoTerms",
+			Query:                 "type:workspace_build",
+			ExpectedErrorContains: "must contain a term",
+		},
+		{
+			Name:                  "InvalidType",
+			Query:                 "error type:foo",
+			ExpectedErrorContains: "not a valid value",
+		},
+		{
+			Name:                  "UnknownTemplate",
+			Query:                 "error template:missing",
+			ExpectedErrorContains: "does not exist",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			// The database is only used for a template lookup.
+			db, _ := dbtestutil.NewDB(t)
+			values, errs := searchquery.ProvisionerJobLogs(context.Background(), db, orgID, c.Query)
+			if c.ExpectedErrorContains != "" {
+				require.True(t, len(errs) > 0, "expect some errors")
+				var s strings.Builder
+				for _, err := range errs {
+					_, _ = s.WriteString(fmt.Sprintf("%s: %s\n", err.Field, err.Detail))
+				}
+				require.Contains(t, s.String(), c.ExpectedErrorContains)
+			} else {
+				require.Len(t, errs, 0, "expected no error")
+				require.Equal(t, c.Expected, values, "expected values")
+			}
+		})
+	}
+}
+
 func TestSearchUsers(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -572,6 +671,20 @@ func TestSearchUsers(t *testing.T) {
 	}
 }
 
+func TestSearchMembers(t *testing.T) {
+	t.Parallel()
+
+	filter, errs := searchquery.Members(context.Background(), nil, "Alice role:organization-admin role:owner")
+	require.Empty(t, errs)
+	require.Equal(t, searchquery.MembersFilter{
+		Search: "alice",
+		Roles:  []string{"organization-admin", "owner"},
+	}, filter)
+
+	_, errs = searchquery.Members(context.Background(), nil, "status:active")
+	require.NotEmpty(t, errs)
+}
+
 func TestSearchTemplates(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
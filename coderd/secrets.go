@@ -0,0 +1,617 @@
+package coderd
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// @Summary Get user secrets
+// @ID get-user-secrets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Secrets
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.UserSecret
+// @Router /users/{user}/secrets [get]
+func (api *API) userSecrets(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	secrets, err := api.Database.GetUserSecretsByUserID(ctx, user.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user secrets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(secrets, db2sdk.UserSecret))
+}
+
+// @Summary Create user secret
+// @ID create-user-secret
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Secrets
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.CreateUserSecretRequest true "Create secret request"
+// @Success 201 {object} codersdk.UserSecret
+// @Router /users/{user}/secrets [post]
+func (api *API) postUserSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.UserSecret](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateUserSecretRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if validErrs := validateSecretNames(req.Name, req.EnvName); len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid secret.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	now := dbtime.Now()
+	secret, err := api.Database.InsertUserSecret(ctx, database.InsertUserSecretParams{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		Name:        req.Name,
+		Description: req.Description,
+		Value:       req.Value,
+		EnvName:     req.EnvName,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if database.IsUniqueViolation(err, database.UniqueUserSecretsUserIDNameKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "A secret with this name already exists.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if database.IsUniqueViolation(err, database.UniqueUserSecretsUserIDEnvNameIndex) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "Another secret already uses this environment variable.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "env_name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating user secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = secret
+
+	httpapi.Write(ctx, rw, http.StatusCreated, db2sdk.UserSecret(secret))
+}
+
+// @Summary Update user secret
+// @ID update-user-secret
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Secrets
+// @Param user path string true "User ID, name, or me"
+// @Param secretname path string true "Secret name"
+// @Param request body codersdk.UpdateUserSecretRequest true "Update secret request"
+// @Success 200 {object} codersdk.UserSecret
+// @Router /users/{user}/secrets/{secretname} [patch]
+func (api *API) patchUserSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		name              = chi.URLParam(r, "secretname")
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.UserSecret](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.UpdateUserSecretRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	old, err := api.Database.GetUserSecretByUserIDAndName(ctx, database.GetUserSecretByUserIDAndNameParams{
+		UserID: user.ID,
+		Name:   name,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = old
+
+	params := database.UpdateUserSecretParams{
+		UserID:      user.ID,
+		Name:        old.Name,
+		Description: old.Description,
+		Value:       old.Value,
+		ValueKeyID:  old.ValueKeyID,
+		EnvName:     old.EnvName,
+		UpdatedAt:   dbtime.Now(),
+	}
+	if req.Description != nil {
+		params.Description = *req.Description
+	}
+	if req.Value != nil {
+		params.Value = *req.Value
+	}
+	if req.EnvName != nil {
+		params.EnvName = *req.EnvName
+	}
+	if validErrs := validateSecretNames(params.Name, params.EnvName); len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid secret.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	secret, err := api.Database.UpdateUserSecret(ctx, params)
+	if database.IsUniqueViolation(err, database.UniqueUserSecretsUserIDEnvNameIndex) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "Another secret already uses this environment variable.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "env_name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating user secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = secret
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.UserSecret(secret))
+}
+
+// @Summary Delete user secret
+// @ID delete-user-secret
+// @Security CoderSessionToken
+// @Tags Secrets
+// @Param user path string true "User ID, name, or me"
+// @Param secretname path string true "Secret name"
+// @Success 204
+// @Router /users/{user}/secrets/{secretname} [delete]
+func (api *API) deleteUserSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		name              = chi.URLParam(r, "secretname")
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.UserSecret](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	secret, err := api.Database.GetUserSecretByUserIDAndName(ctx, database.GetUserSecretByUserIDAndNameParams{
+		UserID: user.ID,
+		Name:   name,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = secret
+
+	err = api.Database.DeleteUserSecret(ctx, database.DeleteUserSecretParams{
+		UserID: user.ID,
+		Name:   name,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting user secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get template secrets
+// @ID get-template-secrets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Secrets
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateSecret
+// @Router /templates/{template}/secrets [get]
+func (api *API) templateSecrets(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	secrets, err := api.Database.GetTemplateSecretsByTemplateID(ctx, template.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template secrets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(secrets, db2sdk.TemplateSecret))
+}
+
+// @Summary Create template secret
+// @ID create-template-secret
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Secrets
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.CreateTemplateSecretRequest true "Create secret request"
+// @Success 201 {object} codersdk.TemplateSecret
+// @Router /templates/{template}/secrets [post]
+func (api *API) postTemplateSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		template          = httpmw.TemplateParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TemplateSecret](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionCreate,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateTemplateSecretRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if validErrs := validateSecretNames(req.Name, ""); len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid secret.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	now := dbtime.Now()
+	secret, err := api.Database.InsertTemplateSecret(ctx, database.InsertTemplateSecretParams{
+		ID:          uuid.New(),
+		TemplateID:  template.ID,
+		Name:        req.Name,
+		Description: req.Description,
+		Value:       req.Value,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err, database.UniqueTemplateSecretsTemplateIDNameKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "A secret with this name already exists.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating template secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = secret
+
+	httpapi.Write(ctx, rw, http.StatusCreated, db2sdk.TemplateSecret(secret))
+}
+
+// @Summary Update template secret
+// @ID update-template-secret
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Secrets
+// @Param template path string true "Template ID" format(uuid)
+// @Param secretname path string true "Secret name"
+// @Param request body codersdk.UpdateTemplateSecretRequest true "Update secret request"
+// @Success 200 {object} codersdk.TemplateSecret
+// @Router /templates/{template}/secrets/{secretname} [patch]
+func (api *API) patchTemplateSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		template          = httpmw.TemplateParam(r)
+		name              = chi.URLParam(r, "secretname")
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TemplateSecret](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.UpdateTemplateSecretRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	old, err := api.Database.GetTemplateSecretByTemplateIDAndName(ctx, database.GetTemplateSecretByTemplateIDAndNameParams{
+		TemplateID: template.ID,
+		Name:       name,
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = old
+
+	params := database.UpdateTemplateSecretParams{
+		TemplateID:  template.ID,
+		Name:        old.Name,
+		Description: old.Description,
+		Value:       old.Value,
+		ValueKeyID:  old.ValueKeyID,
+		UpdatedAt:   dbtime.Now(),
+	}
+	if req.Description != nil {
+		params.Description = *req.Description
+	}
+	if req.Value != nil {
+		params.Value = *req.Value
+	}
+
+	secret, err := api.Database.UpdateTemplateSecret(ctx, params)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = secret
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.TemplateSecret(secret))
+}
+
+// @Summary Delete template secret
+// @ID delete-template-secret
+// @Security CoderSessionToken
+// @Tags Secrets
+// @Param template path string true "Template ID" format(uuid)
+// @Param secretname path string true "Secret name"
+// @Success 204
+// @Router /templates/{template}/secrets/{secretname} [delete]
+func (api *API) deleteTemplateSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		template          = httpmw.TemplateParam(r)
+		name              = chi.URLParam(r, "secretname")
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TemplateSecret](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionDelete,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	secret, err := api.Database.GetTemplateSecretByTemplateIDAndName(ctx, database.GetTemplateSecretByTemplateIDAndNameParams{
+		TemplateID: template.ID,
+		Name:       name,
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = secret
+
+	err = api.Database.DeleteTemplateSecret(ctx, database.DeleteTemplateSecretParams{
+		TemplateID: template.ID,
+		Name:       name,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting template secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// workspaceAgentSecrets returns the workspace owner's secrets that are
+// exposed as environment variables. Secrets are only handed out on demand
+// and every read is audited.
+//
+// @Summary Get workspace agent secrets
+// @ID get-workspace-agent-secrets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Success 200 {array} agentsdk.SecretEnv
+// @Router /workspaceagents/me/secrets [get]
+func (api *API) workspaceAgentSecrets(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	secrets, err := api.Database.GetUserSecretsByUserID(ctx, workspace.OwnerID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user secrets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	additionalFields, err := json.Marshal(audit.AdditionalFields{
+		WorkspaceName:  workspace.Name,
+		WorkspaceID:    workspace.ID,
+		WorkspaceOwner: workspace.OwnerUsername,
+	})
+	if err != nil {
+		api.Logger.Error(ctx, "marshal additional fields failed", slog.Error(err))
+	}
+
+	auditor := *api.Auditor.Load()
+	resp := make([]agentsdk.SecretEnv, 0, len(secrets))
+	for _, secret := range secrets {
+		if secret.EnvName == "" {
+			continue
+		}
+		resp = append(resp, agentsdk.SecretEnv{
+			Name:    secret.Name,
+			EnvName: secret.EnvName,
+			Value:   secret.Value,
+		})
+		audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.UserSecret]{
+			Audit:            auditor,
+			Log:              api.Logger,
+			UserID:           workspace.OwnerID,
+			RequestID:        httpmw.RequestID(r),
+			Time:             dbtime.Now(),
+			Status:           http.StatusOK,
+			Action:           database.AuditActionRead,
+			OrganizationID:   workspace.OrganizationID,
+			IP:               r.RemoteAddr,
+			UserAgent:        r.UserAgent(),
+			AdditionalFields: additionalFields,
+			Old:              secret,
+			New:              secret,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// validateSecretNames validates the name of a secret and, when set, the
+// environment variable it is exposed as.
+func validateSecretNames(name, envName string) []codersdk.ValidationError {
+	var validErrs []codersdk.ValidationError
+	if err := codersdk.SecretNameValid(name); err != nil {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "name", Detail: err.Error()})
+	}
+	if envName != "" {
+		if err := codersdk.SecretNameValid(envName); err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "env_name", Detail: err.Error()})
+		}
+	}
+	return validErrs
+}
@@ -0,0 +1,250 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserSecrets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		secret, err := client.CreateUserSecret(ctx, codersdk.Me, codersdk.CreateUserSecretRequest{
+			Name:        "github_token",
+			Description: "GitHub token",
+			Value:       "hunter2",
+			EnvName:     "GITHUB_TOKEN",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "github_token", secret.Name)
+		require.Equal(t, "GITHUB_TOKEN", secret.EnvName)
+
+		secrets, err := client.UserSecrets(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		require.Equal(t, secret.ID, secrets[0].ID)
+
+		updated, err := client.UpdateUserSecret(ctx, codersdk.Me, secret.Name, codersdk.UpdateUserSecretRequest{
+			Description: ptr.Ref("Personal GitHub token"),
+			EnvName:     ptr.Ref(""),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Personal GitHub token", updated.Description)
+		require.Empty(t, updated.EnvName)
+
+		err = client.DeleteUserSecret(ctx, codersdk.Me, secret.Name)
+		require.NoError(t, err)
+		secrets, err = client.UserSecrets(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Empty(t, secrets)
+
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionCreate,
+			ResourceType: database.ResourceTypeUserSecret,
+			ResourceID:   secret.ID,
+		}))
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionDelete,
+			ResourceType: database.ResourceTypeUserSecret,
+			ResourceID:   secret.ID,
+		}))
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateUserSecret(ctx, codersdk.Me, codersdk.CreateUserSecretRequest{
+			Name:    "token",
+			Value:   "hunter2",
+			EnvName: "TOKEN",
+		})
+		require.NoError(t, err)
+
+		_, err = client.CreateUserSecret(ctx, codersdk.Me, codersdk.CreateUserSecretRequest{
+			Name:  "token",
+			Value: "hunter2",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		_, err = client.CreateUserSecret(ctx, codersdk.Me, codersdk.CreateUserSecretRequest{
+			Name:    "other_token",
+			Value:   "hunter2",
+			EnvName: "TOKEN",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidName", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateUserSecret(ctx, codersdk.Me, codersdk.CreateUserSecretRequest{
+			Name:  "my-token",
+			Value: "hunter2",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateUserSecret(ctx, codersdk.Me, codersdk.CreateUserSecretRequest{
+			Name:  "token",
+			Value: "hunter2",
+		})
+		require.NoError(t, err)
+
+		_, err = member.UserSecrets(ctx, owner.UserID.String())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
+
+func TestTemplateSecrets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			Auditor:                  auditor,
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		secret, err := client.CreateTemplateSecret(ctx, template.ID, codersdk.CreateTemplateSecretRequest{
+			Name:  "db_password",
+			Value: "hunter2",
+		})
+		require.NoError(t, err)
+		require.Equal(t, template.ID, secret.TemplateID)
+
+		updated, err := client.UpdateTemplateSecret(ctx, template.ID, secret.Name, codersdk.UpdateTemplateSecretRequest{
+			Value: ptr.Ref("correct-horse"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, secret.ID, updated.ID)
+
+		secrets, err := client.TemplateSecrets(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+
+		err = client.DeleteTemplateSecret(ctx, template.ID, secret.Name)
+		require.NoError(t, err)
+		secrets, err = client.TemplateSecrets(ctx, template.ID)
+		require.NoError(t, err)
+		require.Empty(t, secrets)
+
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:         database.AuditActionWrite,
+			ResourceType:   database.ResourceTypeTemplateSecret,
+			ResourceID:     secret.ID,
+			OrganizationID: owner.OrganizationID,
+		}))
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.TemplateSecrets(ctx, template.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
+func TestWorkspaceAgentSecrets(t *testing.T) {
+	t.Parallel()
+
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		Auditor:                  auditor,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	exposed, err := client.CreateUserSecret(ctx, codersdk.Me, codersdk.CreateUserSecretRequest{
+		Name:    "github_token",
+		Value:   "hunter2",
+		EnvName: "GITHUB_TOKEN",
+	})
+	require.NoError(t, err)
+	_, err = client.CreateUserSecret(ctx, codersdk.Me, codersdk.CreateUserSecretRequest{
+		Name:  "not_exposed",
+		Value: "hunter3",
+	})
+	require.NoError(t, err)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+
+	secrets, err := agentClient.Secrets(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []agentsdk.SecretEnv{{
+		Name:    "github_token",
+		EnvName: "GITHUB_TOKEN",
+		Value:   "hunter2",
+	}}, secrets)
+
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		Action:       database.AuditActionRead,
+		ResourceType: database.ResourceTypeUserSecret,
+		ResourceID:   exposed.ID,
+		UserID:       user.UserID,
+	}))
+}
@@ -0,0 +1,70 @@
+package telemetry
+
+import (
+	"slices"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// validateCategories returns an error if any of the categories is unknown.
+func validateCategories(categories []codersdk.TelemetryCategory) error {
+	for _, category := range categories {
+		if !slices.Contains(codersdk.TelemetryCategories(), category) {
+			return xerrors.Errorf("unknown telemetry category %q", category)
+		}
+	}
+	return nil
+}
+
+// reportsCategory returns whether data of the category is reported. All
+// categories are reported if none are selected.
+func reportsCategory(categories []codersdk.TelemetryCategory, category codersdk.TelemetryCategory) bool {
+	return len(categories) == 0 || slices.Contains(categories, category)
+}
+
+// filterSnapshot removes the data of categories that aren't reported from the
+// snapshot. The deployment ID and telemetry items are always reported, so the
+// telemetry server can tell when telemetry is disabled.
+func filterSnapshot(snapshot *Snapshot, categories []codersdk.TelemetryCategory) {
+	if !reportsCategory(categories, codersdk.TelemetryCategoryDeployment) {
+		snapshot.ExternalProvisioners = nil
+	}
+	if !reportsCategory(categories, codersdk.TelemetryCategoryUsers) {
+		snapshot.APIKeys = nil
+		snapshot.Users = nil
+		snapshot.Groups = nil
+		snapshot.GroupMembers = nil
+		snapshot.Organizations = nil
+	}
+	if !reportsCategory(categories, codersdk.TelemetryCategoryTemplates) {
+		snapshot.Templates = nil
+		snapshot.TemplateVersions = nil
+	}
+	if !reportsCategory(categories, codersdk.TelemetryCategoryWorkspaces) {
+		snapshot.ProvisionerJobs = nil
+		snapshot.Workspaces = nil
+		snapshot.WorkspaceBuilds = nil
+		snapshot.WorkspaceResources = nil
+		snapshot.WorkspaceResourceMetadata = nil
+		snapshot.WorkspaceModules = nil
+		snapshot.WorkspaceAgents = nil
+		snapshot.WorkspaceApps = nil
+		snapshot.WorkspaceAgentMemoryResourceMonitors = nil
+		snapshot.WorkspaceAgentVolumeResourceMonitors = nil
+		snapshot.PrebuiltWorkspaces = nil
+	}
+	if !reportsCategory(categories, codersdk.TelemetryCategoryUsage) {
+		snapshot.WorkspaceAgentStats = nil
+		snapshot.CLIInvocations = nil
+		snapshot.UserTailnetConnections = nil
+	}
+	if !reportsCategory(categories, codersdk.TelemetryCategoryNetwork) {
+		snapshot.NetworkEvents = nil
+		snapshot.WorkspaceProxies = nil
+	}
+	if !reportsCategory(categories, codersdk.TelemetryCategoryLicenses) {
+		snapshot.Licenses = nil
+	}
+}
@@ -59,8 +59,15 @@ type Options struct {
 
 	SnapshotFrequency time.Duration
 	ParseLicenseJWT   func(lic *License) error
+	// Categories are the categories of data that are reported. All
+	// categories are reported if empty.
+	Categories []codersdk.TelemetryCategory
 }
 
+// maxRecentPayloads is the number of payloads that are kept so operators can
+// inspect what is sent.
+const maxRecentPayloads = 20
+
 // New constructs a reporter for telemetry data.
 // Duplicate data will be sent, it's on the server-side to index by UUID.
 // Data is anonymized prior to being sent!
@@ -69,6 +76,9 @@ func New(options Options) (Reporter, error) {
 		// Report once every 30mins by default!
 		options.SnapshotFrequency = 30 * time.Minute
 	}
+	if err := validateCategories(options.Categories); err != nil {
+		return nil, err
+	}
 	snapshotURL, err := options.URL.Parse("/snapshot")
 	if err != nil {
 		return nil, xerrors.Errorf("parse snapshot url: %w", err)
@@ -105,6 +115,9 @@ type Reporter interface {
 	// contain just that user entry.
 	Report(snapshot *Snapshot)
 	Enabled() bool
+	// RecentPayloads returns the most recent payloads that were sent,
+	// newest first.
+	RecentPayloads() []codersdk.TelemetryPayload
 	Close()
 }
 
@@ -119,6 +132,9 @@ type remoteReporter struct {
 	snapshotURL *url.URL
 	startedAt  time.Time
 	shutdownAt *time.Time
+
+	payloadsMu     sync.Mutex
+	recentPayloads []codersdk.TelemetryPayload
 }
 
 func (r *remoteReporter) Enabled() bool {
@@ -131,32 +147,66 @@ func (r *remoteReporter) Report(snapshot *Snapshot) {
 
 func (r *remoteReporter) reportSync(snapshot *Snapshot) {
 	snapshot.DeploymentID = r.options.DeploymentID
+	filterSnapshot(snapshot, r.options.Categories)
 	data, err := json.Marshal(snapshot)
 	if err != nil {
 		r.options.Logger.Error(r.ctx, "marshal snapshot: %w", slog.Error(err))
 		return
 	}
-	req, err := http.NewRequestWithContext(r.ctx, "POST", r.snapshotURL.String(), bytes.NewReader(data))
-	if err != nil {
-		r.options.Logger.Error(r.ctx, "unable to create snapshot request", slog.Error(err))
-		return
-	}
-	req.Header.Set(VersionHeader, buildinfo.Version())
-	resp, err := http.DefaultClient.Do(req)
+	statusCode, err := r.send(codersdk.TelemetryPayloadKindSnapshot, r.snapshotURL, data)
 	if err != nil {
 		// If the request fails it's not necessarily an error.
 		// In an airgapped environment, it's fine if this fails!
 		r.options.Logger.Debug(r.ctx, "submit", slog.Error(err))
 		return
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusAccepted {
-		r.options.Logger.Debug(r.ctx, "bad response from telemetry server", slog.F("status", resp.StatusCode))
+	if statusCode != http.StatusAccepted {
+		r.options.Logger.Debug(r.ctx, "bad response from telemetry server", slog.F("status", statusCode))
 		return
 	}
 	r.options.Logger.Debug(r.ctx, "submitted snapshot")
 }
 
+// send posts a payload to the telemetry server and records it, so operators
+// can inspect exactly what is sent.
+func (r *remoteReporter) send(kind codersdk.TelemetryPayloadKind, u *url.URL, data []byte) (int, error) {
+	payload := codersdk.TelemetryPayload{
+		Kind:      kind,
+		URL:       u.String(),
+		CreatedAt: dbtime.Now(),
+		Data:      data,
+	}
+	defer func() {
+		r.payloadsMu.Lock()
+		defer r.payloadsMu.Unlock()
+		r.recentPayloads = append([]codersdk.TelemetryPayload{payload}, r.recentPayloads...)
+		if len(r.recentPayloads) > maxRecentPayloads {
+			r.recentPayloads = r.recentPayloads[:maxRecentPayloads]
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(r.ctx, "POST", u.String(), bytes.NewReader(data))
+	if err != nil {
+		payload.Error = err.Error()
+		return 0, xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set(VersionHeader, buildinfo.Version())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		payload.Error = err.Error()
+		return 0, xerrors.Errorf("perform request: %w", err)
+	}
+	defer resp.Body.Close()
+	payload.StatusCode = resp.StatusCode
+	return resp.StatusCode, nil
+}
+
+func (r *remoteReporter) RecentPayloads() []codersdk.TelemetryPayload {
+	r.payloadsMu.Lock()
+	defer r.payloadsMu.Unlock()
+	return slices.Clone(r.recentPayloads)
+}
+
 func (r *remoteReporter) Close() {
 	r.closeMutex.Lock()
 	defer r.closeMutex.Unlock()
@@ -324,7 +374,7 @@ func (r *remoteReporter) deployment() error {
 		r.options.Logger.Debug(r.ctx, "check IDP org sync", slog.Error(err))
 	}
 
-	data, err := json.Marshal(&Deployment{
+	deployment := &Deployment{
 		ID:              r.options.DeploymentID,
 		Architecture:    sysInfo.Architecture,
 		BuiltinPostgres: r.options.BuiltinPostgres,
@@ -344,22 +394,26 @@ func (r *remoteReporter) deployment() error {
 		StartedAt:       r.startedAt,
 		ShutdownAt:      r.shutdownAt,
 		IDPOrgSync:      &idpOrgSync,
-	})
-	if err != nil {
-		return xerrors.Errorf("marshal deployment: %w", err)
 	}
-	req, err := http.NewRequestWithContext(r.ctx, "POST", r.deploymentURL.String(), bytes.NewReader(data))
+	if !reportsCategory(r.options.Categories, codersdk.TelemetryCategoryDeployment) {
+		// The deployment is still registered, so that snapshots can be
+		// associated with it.
+		deployment = &Deployment{
+			ID:         r.options.DeploymentID,
+			StartedAt:  r.startedAt,
+			ShutdownAt: r.shutdownAt,
+		}
+	}
+	data, err := json.Marshal(deployment)
 	if err != nil {
-		return xerrors.Errorf("create deployment request: %w", err)
+		return xerrors.Errorf("marshal deployment: %w", err)
 	}
-	req.Header.Set(VersionHeader, buildinfo.Version())
-	resp, err := http.DefaultClient.Do(req)
+	statusCode, err := r.send(codersdk.TelemetryPayloadKindDeployment, r.deploymentURL, data)
 	if err != nil {
-		return xerrors.Errorf("perform request: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusAccepted {
-		return xerrors.Errorf("update deployment: %w", err)
+	if statusCode != http.StatusAccepted {
+		return xerrors.Errorf("update deployment: unexpected status code %d", statusCode)
 	}
 	r.options.Logger.Debug(r.ctx, "submitted deployment info")
 	return nil
@@ -1794,3 +1848,5 @@ func (*noopReporter) Enabled() bool                 { return false }
 func (*noopReporter) Close()                        {}
 func (*noopReporter) RunSnapshotter()               {}
 func (*noopReporter) ReportDisabledIfNeeded() error { return nil }
+
+func (*noopReporter) RecentPayloads() []codersdk.TelemetryPayload { return nil }
@@ -360,6 +360,51 @@ func TestTelemetryInstallSource(t *testing.T) {
 	require.Equal(t, "aws_marketplace", deployment.InstallSource)
 }
 
+func TestTelemetryCategories(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	db, _ := dbtestutil.NewDB(t)
+	user := dbgen.User(t, db, database.User{})
+	_, _ = dbgen.APIKey(t, db, database.APIKey{UserID: user.ID})
+
+	serverURL, deployments, snapshots := mockTelemetryServer(ctx, t)
+	reporter, err := telemetry.New(telemetry.Options{
+		Database:         db,
+		Logger:           testutil.Logger(t),
+		URL:              serverURL,
+		DeploymentID:     uuid.NewString(),
+		DeploymentConfig: &codersdk.DeploymentValues{},
+		Categories:       []codersdk.TelemetryCategory{codersdk.TelemetryCategoryLicenses},
+	})
+	require.NoError(t, err)
+	t.Cleanup(reporter.Close)
+
+	// Only the deployment ID is reported, without host information or config.
+	deployment := testutil.RequireReceive(ctx, t, deployments)
+	require.NotEmpty(t, deployment.ID)
+	require.Nil(t, deployment.Config)
+	require.Empty(t, deployment.OSType)
+	snapshot := testutil.RequireReceive(ctx, t, snapshots)
+	require.Empty(t, snapshot.Users)
+	require.Empty(t, snapshot.APIKeys)
+
+	// The payloads can be inspected.
+	require.Eventually(t, func() bool {
+		return len(reporter.RecentPayloads()) == 2
+	}, testutil.WaitShort, testutil.IntervalFast)
+	payloads := reporter.RecentPayloads()
+	require.Equal(t, codersdk.TelemetryPayloadKindSnapshot, payloads[0].Kind)
+	require.Equal(t, http.StatusAccepted, payloads[0].StatusCode)
+	require.Equal(t, codersdk.TelemetryPayloadKindDeployment, payloads[1].Kind)
+	require.Contains(t, string(payloads[1].Data), deployment.ID)
+
+	_, err = telemetry.New(telemetry.Options{
+		URL:        serverURL,
+		Categories: []codersdk.TelemetryCategory{"secrets"},
+	})
+	require.Error(t, err)
+}
+
 func TestTelemetryItem(t *testing.T) {
 	t.Parallel()
 	ctx := testutil.Context(t, testutil.WaitMedium)
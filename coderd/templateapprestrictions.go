@@ -0,0 +1,198 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner"
+)
+
+// @Summary Get template app restrictions
+// @ID get-template-app-restrictions
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateAppRestriction
+// @Router /templates/{template}/app-restrictions [get]
+func (api *API) templateAppRestrictions(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	restrictions, err := api.Database.GetTemplateAppRestrictions(ctx, template.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template app restrictions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(restrictions, convertTemplateAppRestriction))
+}
+
+// @Summary Create or update template app restriction
+// @ID create-or-update-template-app-restriction
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param app path string true "App slug"
+// @Param request body codersdk.UpsertTemplateAppRestrictionRequest true "App restriction request"
+// @Success 200 {object} codersdk.TemplateAppRestriction
+// @Router /templates/{template}/app-restrictions/{app} [put]
+func (api *API) putTemplateAppRestriction(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		appSlug  = chi.URLParam(r, "app")
+	)
+
+	if !api.Authorize(r, policy.ActionUpdate, template) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.UpsertTemplateAppRestrictionRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	groupIDs := slice.Unique(req.GroupIDs)
+	if groupIDs == nil {
+		groupIDs = []uuid.UUID{}
+	}
+	roles := slice.Unique(req.Roles)
+	if roles == nil {
+		roles = []string{}
+	}
+	var validErrs []codersdk.ValidationError
+	if !provisioner.AppSlugRegex.MatchString(appSlug) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "app", Detail: fmt.Sprintf("%q does not match regex %q", appSlug, provisioner.AppSlugRegex)})
+	}
+	if len(groupIDs) == 0 && len(roles) == 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "group_ids", Detail: "At least one group or role is required."})
+	}
+	for _, role := range roles {
+		if role == "" {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "roles", Detail: "Role names can't be empty."})
+			break
+		}
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to update template app restriction.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	if len(groupIDs) > 0 {
+		groups, err := api.Database.GetGroups(ctx, database.GetGroupsParams{
+			OrganizationID: template.OrganizationID,
+			GroupIds:       groupIDs,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching groups.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if len(groups) != len(groupIDs) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Groups must exist in the organization of the template.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "group_ids",
+					Detail: "One or more groups were not found.",
+				}},
+			})
+			return
+		}
+	}
+
+	restriction, err := api.Database.UpsertTemplateAppRestriction(ctx, database.UpsertTemplateAppRestrictionParams{
+		TemplateID: template.ID,
+		AppSlug:    appSlug,
+		GroupIds:   groupIDs,
+		Roles:      roles,
+		UpdatedAt:  dbtime.Now(),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template app restriction.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateAppRestriction(restriction))
+}
+
+// @Summary Delete template app restriction
+// @ID delete-template-app-restriction
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param app path string true "App slug"
+// @Success 200 {object} codersdk.Response
+// @Router /templates/{template}/app-restrictions/{app} [delete]
+func (api *API) deleteTemplateAppRestriction(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		appSlug  = chi.URLParam(r, "app")
+	)
+
+	err := api.Database.DeleteTemplateAppRestriction(ctx, database.DeleteTemplateAppRestrictionParams{
+		TemplateID: template.ID,
+		AppSlug:    appSlug,
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting template app restriction.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Template app restriction has been deleted!",
+	})
+}
+
+func convertTemplateAppRestriction(restriction database.TemplateAppRestriction) codersdk.TemplateAppRestriction {
+	return codersdk.TemplateAppRestriction{
+		TemplateID: restriction.TemplateID,
+		AppSlug:    restriction.AppSlug,
+		GroupIDs:   restriction.GroupIds,
+		Roles:      restriction.Roles,
+		CreatedAt:  restriction.CreatedAt,
+		UpdatedAt:  restriction.UpdatedAt,
+	}
+}
@@ -0,0 +1,118 @@
+package coderd_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateAppRestrictions(t *testing.T) {
+	t.Parallel()
+
+	owner, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, owner)
+	member, memberUser := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID)
+	templateAdmin, _ := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID, rbac.RoleTemplateAdmin())
+	group := dbgen.Group(t, db, database.Group{OrganizationID: user.OrganizationID})
+
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        memberUser.ID,
+	}).WithAgent(func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].Apps = []*proto.App{
+			{Slug: "code", Url: "http://localhost:8080"},
+			{Slug: "debug", Url: "http://localhost:9090"},
+		}
+		return agents
+	}).Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	restriction, err := templateAdmin.UpsertTemplateAppRestriction(ctx, r.Workspace.TemplateID, "debug", codersdk.UpsertTemplateAppRestrictionRequest{
+		GroupIDs: []uuid.UUID{group.ID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "debug", restriction.AppSlug)
+	require.Equal(t, []uuid.UUID{group.ID}, restriction.GroupIDs)
+
+	appSlugs := func(t *testing.T, client *codersdk.Client) []string {
+		t.Helper()
+		workspace, err := client.Workspace(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		agent, err := client.WorkspaceAgent(ctx, workspace.LatestBuild.Resources[0].Agents[0].ID)
+		require.NoError(t, err)
+		var slugs []string
+		for _, app := range workspace.LatestBuild.Resources[0].Agents[0].Apps {
+			slugs = append(slugs, app.Slug)
+		}
+		require.Len(t, agent.Apps, len(slugs))
+		return slugs
+	}
+	appStatus := func(t *testing.T, client *codersdk.Client, slug string) int {
+		t.Helper()
+		workspace, err := client.Workspace(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		path := fmt.Sprintf("/@%s/%s.%s/apps/%s/", memberUser.Username, workspace.Name, workspace.LatestBuild.Resources[0].Agents[0].Name, slug)
+		res, err := client.Request(ctx, http.MethodGet, path, nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		return res.StatusCode
+	}
+
+	// The owner of the workspace isn't in the group, so the app is hidden
+	// and can't be accessed.
+	require.Equal(t, []string{"code"}, appSlugs(t, member))
+	require.Equal(t, http.StatusNotFound, appStatus(t, member, "debug"))
+	// The agent isn't connected, so unrestricted apps are unavailable
+	// instead of not found.
+	require.Equal(t, http.StatusBadGateway, appStatus(t, member, "code"))
+
+	dbgen.GroupMember(t, db, database.GroupMemberTable{GroupID: group.ID, UserID: memberUser.ID})
+	require.Equal(t, []string{"code", "debug"}, appSlugs(t, member))
+	require.Equal(t, http.StatusBadGateway, appStatus(t, member, "debug"))
+
+	restrictions, err := templateAdmin.TemplateAppRestrictions(ctx, r.Workspace.TemplateID)
+	require.NoError(t, err)
+	require.Len(t, restrictions, 1)
+
+	// Restricting the app to a role replaces the groups.
+	_, err = templateAdmin.UpsertTemplateAppRestriction(ctx, r.Workspace.TemplateID, "debug", codersdk.UpsertTemplateAppRestrictionRequest{
+		Roles: []string{rbac.RoleOwner().Name},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"code"}, appSlugs(t, member))
+
+	// Members can't change the restrictions of the template.
+	_, err = member.UpsertTemplateAppRestriction(ctx, r.Workspace.TemplateID, "debug", codersdk.UpsertTemplateAppRestrictionRequest{
+		GroupIDs: []uuid.UUID{group.ID},
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	_, err = templateAdmin.UpsertTemplateAppRestriction(ctx, r.Workspace.TemplateID, "debug", codersdk.UpsertTemplateAppRestrictionRequest{})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	_, err = templateAdmin.UpsertTemplateAppRestriction(ctx, r.Workspace.TemplateID, "debug", codersdk.UpsertTemplateAppRestrictionRequest{
+		GroupIDs: []uuid.UUID{uuid.New()},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	err = templateAdmin.DeleteTemplateAppRestriction(ctx, r.Workspace.TemplateID, "debug")
+	require.NoError(t, err)
+	require.Equal(t, []string{"code", "debug"}, appSlugs(t, member))
+}
@@ -0,0 +1,155 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner"
+)
+
+// minAppSessionDuration is the shortest app session policies can allow, so
+// users aren't asked to sign in again for every request.
+const minAppSessionDuration = time.Minute
+
+// @Summary Get template app session policies
+// @ID get-template-app-session-policies
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateAppSessionPolicy
+// @Router /templates/{template}/app-session-policies [get]
+func (api *API) templateAppSessionPolicies(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	policies, err := api.Database.GetTemplateAppSessionPolicies(ctx, template.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template app session policies.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(policies, convertTemplateAppSessionPolicy))
+}
+
+// @Summary Create or update template app session policy
+// @ID create-or-update-template-app-session-policy
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.UpsertTemplateAppSessionPolicyRequest true "App session policy request"
+// @Success 200 {object} codersdk.TemplateAppSessionPolicy
+// @Router /templates/{template}/app-session-policies [put]
+func (api *API) putTemplateAppSessionPolicy(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	if !api.Authorize(r, policy.ActionUpdate, template) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.UpsertTemplateAppSessionPolicyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	maxSessionDuration := time.Duration(req.MaxSessionDurationMillis) * time.Millisecond
+	var validErrs []codersdk.ValidationError
+	if req.AppSlug != "" && !provisioner.AppSlugRegex.MatchString(req.AppSlug) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "app_slug", Detail: fmt.Sprintf("%q does not match regex %q", req.AppSlug, provisioner.AppSlugRegex)})
+	}
+	if maxSessionDuration < minAppSessionDuration {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "max_session_duration_ms", Detail: fmt.Sprintf("Must be at least %s.", minAppSessionDuration)})
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to update template app session policy.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	sessionPolicy, err := api.Database.UpsertTemplateAppSessionPolicy(ctx, database.UpsertTemplateAppSessionPolicyParams{
+		TemplateID:         template.ID,
+		AppSlug:            req.AppSlug,
+		MaxSessionDuration: int64(maxSessionDuration),
+		UpdatedAt:          dbtime.Now(),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template app session policy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateAppSessionPolicy(sessionPolicy))
+}
+
+// @Summary Delete template app session policy
+// @ID delete-template-app-session-policy
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param app_slug query string false "App slug, or empty for the policy of all apps"
+// @Success 200 {object} codersdk.Response
+// @Router /templates/{template}/app-session-policies [delete]
+func (api *API) deleteTemplateAppSessionPolicy(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	err := api.Database.DeleteTemplateAppSessionPolicy(ctx, database.DeleteTemplateAppSessionPolicyParams{
+		TemplateID: template.ID,
+		AppSlug:    r.URL.Query().Get("app_slug"),
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting template app session policy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Template app session policy has been deleted!",
+	})
+}
+
+func convertTemplateAppSessionPolicy(sessionPolicy database.TemplateAppSessionPolicy) codersdk.TemplateAppSessionPolicy {
+	return codersdk.TemplateAppSessionPolicy{
+		TemplateID:               sessionPolicy.TemplateID,
+		AppSlug:                  sessionPolicy.AppSlug,
+		MaxSessionDurationMillis: time.Duration(sessionPolicy.MaxSessionDuration).Milliseconds(),
+		CreatedAt:                sessionPolicy.CreatedAt,
+		UpdatedAt:                sessionPolicy.UpdatedAt,
+	}
+}
@@ -0,0 +1,122 @@
+package coderd_test
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateAppSessionPolicies(t *testing.T) {
+	t.Parallel()
+
+	owner, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, owner)
+	member, memberUser := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID)
+	templateAdmin, _ := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID, rbac.RoleTemplateAdmin())
+
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        memberUser.ID,
+	}).WithAgent(func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].Apps = []*proto.App{
+			{Slug: "code", Url: "http://localhost:8080"},
+			{Slug: "debug", Url: "http://localhost:9090"},
+		}
+		return agents
+	}).Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	sessionPolicy, err := templateAdmin.UpsertTemplateAppSessionPolicy(ctx, r.Workspace.TemplateID, codersdk.UpsertTemplateAppSessionPolicyRequest{
+		AppSlug:                  "debug",
+		MaxSessionDurationMillis: time.Hour.Milliseconds(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "debug", sessionPolicy.AppSlug)
+	require.Equal(t, time.Hour.Milliseconds(), sessionPolicy.MaxSessionDurationMillis)
+
+	// The member signed in two hours ago.
+	staleKey, staleToken := dbgen.APIKey(t, db, database.APIKey{
+		UserID:    memberUser.ID,
+		CreatedAt: dbtime.Now().Add(-2 * time.Hour),
+		ExpiresAt: dbtime.Now().Add(time.Hour),
+	})
+	staleClient := codersdk.New(owner.URL)
+	staleClient.SetSessionToken(staleToken)
+	staleClient.HTTPClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	appStatus := func(t *testing.T, client *codersdk.Client, slug string) int {
+		t.Helper()
+		workspace, err := member.Workspace(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		path := fmt.Sprintf("/@%s/%s.%s/apps/%s/", memberUser.Username, workspace.Name, workspace.LatestBuild.Resources[0].Agents[0].Name, slug)
+		res, err := client.Request(ctx, http.MethodGet, path, nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		return res.StatusCode
+	}
+
+	// Apps without a policy can be accessed with the old session. The agent
+	// isn't connected, so they are unavailable instead of redirecting.
+	require.Equal(t, http.StatusBadGateway, appStatus(t, staleClient, "code"))
+
+	// The old session is signed out and redirected to sign in again.
+	require.Equal(t, http.StatusSeeOther, appStatus(t, staleClient, "debug"))
+	// nolint:gocritic // Testing that the session was deleted.
+	_, err = db.GetAPIKeyByID(dbauthz.AsSystemRestricted(ctx), staleKey.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Recent sessions can access the app.
+	require.Equal(t, http.StatusBadGateway, appStatus(t, member, "debug"))
+
+	// The policy for all apps of the template applies to other apps.
+	_, err = templateAdmin.UpsertTemplateAppSessionPolicy(ctx, r.Workspace.TemplateID, codersdk.UpsertTemplateAppSessionPolicyRequest{
+		MaxSessionDurationMillis: (8 * time.Hour).Milliseconds(),
+	})
+	require.NoError(t, err)
+	policies, err := templateAdmin.TemplateAppSessionPolicies(ctx, r.Workspace.TemplateID)
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+	require.Equal(t, "", policies[0].AppSlug)
+	require.Equal(t, "debug", policies[1].AppSlug)
+
+	// Members can't change the policies of the template.
+	_, err = member.UpsertTemplateAppSessionPolicy(ctx, r.Workspace.TemplateID, codersdk.UpsertTemplateAppSessionPolicyRequest{
+		MaxSessionDurationMillis: time.Hour.Milliseconds(),
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	_, err = templateAdmin.UpsertTemplateAppSessionPolicy(ctx, r.Workspace.TemplateID, codersdk.UpsertTemplateAppSessionPolicyRequest{
+		AppSlug:                  "debug",
+		MaxSessionDurationMillis: time.Second.Milliseconds(),
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	err = templateAdmin.DeleteTemplateAppSessionPolicy(ctx, r.Workspace.TemplateID, "")
+	require.NoError(t, err)
+	err = templateAdmin.DeleteTemplateAppSessionPolicy(ctx, r.Workspace.TemplateID, "debug")
+	require.NoError(t, err)
+	policies, err = templateAdmin.TemplateAppSessionPolicies(ctx, r.Workspace.TemplateID)
+	require.NoError(t, err)
+	require.Empty(t, policies)
+}
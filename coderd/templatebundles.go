@@ -0,0 +1,313 @@
+package coderd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionersdk"
+)
+
+// @Summary Get template bundles by organization
+// @ID get-template-bundles-by-organization
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateBundle
+// @Router /organizations/{organization}/templatebundles [get]
+func (api *API) templateBundlesByOrganization(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+	)
+
+	bundles, err := api.Database.GetTemplateBundlesByOrganizationID(ctx, organization.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template bundles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiBundles := make([]codersdk.TemplateBundle, 0, len(bundles))
+	for _, bundle := range bundles {
+		apiBundle, err := convertTemplateBundle(bundle)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error converting template bundle.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		apiBundles = append(apiBundles, apiBundle)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiBundles)
+}
+
+// @Summary Push template bundle
+// @Description Swagger notice: Swagger 2.0 doesn't support file upload with a `content-type` different than `application/x-www-form-urlencoded`.
+// @ID push-template-bundle
+// @Security CoderSessionToken
+// @Produce json
+// @Accept application/x-tar
+// @Tags Templates
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param Content-Type header string true "Content-Type must be `application/x-tar`" default(application/x-tar)
+// @Param file formData file true "Template bundle, as created by `coder templates bundle create`."
+// @Success 201 {object} codersdk.TemplateBundle
+// @Router /organizations/{organization}/templatebundles [post]
+func (api *API) postTemplateBundle(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		apiKey       = httpmw.APIKey(r)
+		organization = httpmw.OrganizationParam(r)
+	)
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType != tarMimeType {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Unsupported content type header %q.", contentType),
+		})
+		return
+	}
+
+	r.Body = http.MaxBytesReader(rw, r.Body, HTTPFileMaxBytes)
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to read template bundle from request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	manifest, err := provisionersdk.ReadBundleManifest(bytes.NewReader(data))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid template bundle.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	var validErrs []codersdk.ValidationError
+	if err := codersdk.NameValid(manifest.Name); err != nil {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "name", Detail: err.Error()})
+	}
+	if err := codersdk.TemplateVersionNameValid(manifest.Version); err != nil {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "version", Detail: err.Error()})
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid template bundle manifest.",
+			Validations: validErrs,
+		})
+		return
+	}
+	rawManifest, err := json.Marshal(manifest)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error encoding template bundle manifest.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	user, err := api.Database.GetUserByID(ctx, apiKey.UserID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	hashBytes := sha256.Sum256(data)
+	bundle := database.GetTemplateBundlesByOrganizationIDRow{
+		ID:                 uuid.New(),
+		OrganizationID:     organization.ID,
+		Name:               manifest.Name,
+		Version:            manifest.Version,
+		Hash:               hex.EncodeToString(hashBytes[:]),
+		Manifest:           rawManifest,
+		Size:               int64(len(data)),
+		CreatedBy:          user.ID,
+		CreatedAt:          dbtime.Now(),
+		CreatedByUsername:  user.Username,
+		CreatedByAvatarURL: user.AvatarURL,
+	}
+	err = api.Database.InsertTemplateBundle(ctx, database.InsertTemplateBundleParams{
+		ID:             bundle.ID,
+		OrganizationID: bundle.OrganizationID,
+		Name:           bundle.Name,
+		Version:        bundle.Version,
+		Hash:           bundle.Hash,
+		Manifest:       bundle.Manifest,
+		Data:           data,
+		CreatedBy:      bundle.CreatedBy,
+		CreatedAt:      bundle.CreatedAt,
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err, database.UniqueTemplateBundlesOrganizationIDNameVersionKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Template bundle %s@%s already exists.", manifest.Name, manifest.Version),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error saving template bundle.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiBundle, err := convertTemplateBundle(bundle)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting template bundle.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, apiBundle)
+}
+
+// @Summary Pull template bundle
+// @ID pull-template-bundle
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param templatebundlename path string true "Template bundle name"
+// @Param templatebundleversion path string true "Template bundle version"
+// @Success 200
+// @Router /organizations/{organization}/templatebundles/{templatebundlename}/{templatebundleversion} [get]
+func (api *API) templateBundle(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+	)
+
+	bundle, err := api.Database.GetTemplateBundleByName(ctx, database.GetTemplateBundleByNameParams{
+		OrganizationID: organization.ID,
+		Name:           chi.URLParam(r, "templatebundlename"),
+		Version:        chi.URLParam(r, "templatebundleversion"),
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template bundle.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.Header().Set("Content-Type", tarMimeType)
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%s-%s.tar", bundle.Name, bundle.Version)))
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(bundle.Data)
+}
+
+// @Summary Delete template bundle
+// @ID delete-template-bundle
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param templatebundlename path string true "Template bundle name"
+// @Param templatebundleversion path string true "Template bundle version"
+// @Success 200 {object} codersdk.Response
+// @Router /organizations/{organization}/templatebundles/{templatebundlename}/{templatebundleversion} [delete]
+func (api *API) deleteTemplateBundle(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+	)
+
+	err := api.Database.DeleteTemplateBundleByName(ctx, database.DeleteTemplateBundleByNameParams{
+		OrganizationID: organization.ID,
+		Name:           chi.URLParam(r, "templatebundlename"),
+		Version:        chi.URLParam(r, "templatebundleversion"),
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting template bundle.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Template bundle has been deleted!",
+	})
+}
+
+func convertTemplateBundle(bundle database.GetTemplateBundlesByOrganizationIDRow) (codersdk.TemplateBundle, error) {
+	var manifest provisionersdk.BundleManifest
+	err := json.Unmarshal(bundle.Manifest, &manifest)
+	if err != nil {
+		return codersdk.TemplateBundle{}, xerrors.Errorf("unmarshal manifest: %w", err)
+	}
+
+	providers := make([]codersdk.TemplateBundleProvider, 0, len(manifest.Providers))
+	for _, provider := range manifest.Providers {
+		providers = append(providers, codersdk.TemplateBundleProvider{
+			Source:    provider.Source,
+			Version:   provider.Version,
+			Platforms: provider.Platforms,
+		})
+	}
+	modules := make([]codersdk.TemplateBundleModule, 0, len(manifest.Modules))
+	for _, module := range manifest.Modules {
+		modules = append(modules, codersdk.TemplateBundleModule{
+			Key:     module.Key,
+			Source:  module.Source,
+			Version: module.Version,
+		})
+	}
+
+	return codersdk.TemplateBundle{
+		ID:             bundle.ID,
+		OrganizationID: bundle.OrganizationID,
+		Name:           bundle.Name,
+		Version:        bundle.Version,
+		Hash:           bundle.Hash,
+		Size:           bundle.Size,
+		Providers:      providers,
+		Modules:        modules,
+		CreatedBy: codersdk.MinimalUser{
+			ID:        bundle.CreatedBy,
+			Username:  bundle.CreatedByUsername,
+			AvatarURL: bundle.CreatedByAvatarURL,
+		},
+		CreatedAt: bundle.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,363 @@
+package coderd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// @Summary Get template egress policy
+// @ID get-template-egress-policy
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateEgressPolicy
+// @Router /templates/{template}/egress-policy [get]
+func (api *API) templateEgressPolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	egressPolicy, err := api.Database.GetTemplateEgressPolicyByTemplateID(ctx, template.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "The template does not have an egress policy.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template egress policy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.TemplateEgressPolicy(egressPolicy))
+}
+
+// @Summary Update template egress policy
+// @ID update-template-egress-policy
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.UpdateTemplateEgressPolicyRequest true "Update egress policy request"
+// @Success 200 {object} codersdk.TemplateEgressPolicy
+// @Router /templates/{template}/egress-policy [put]
+func (api *API) putTemplateEgressPolicy(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		template          = httpmw.TemplateParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TemplateEgressPolicy](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.UpdateTemplateEgressPolicyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	cidrs, domains, validErrs := normalizeEgressPolicy(req)
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid egress policy.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	old, err := api.Database.GetTemplateEgressPolicyByTemplateID(ctx, template.ID)
+	if err == nil {
+		aReq.Old = old
+	} else if !httpapi.Is404Error(err) {
+		if httpapi.IsUnauthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template egress policy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	egressPolicy, err := api.Database.UpsertTemplateEgressPolicy(ctx, database.UpsertTemplateEgressPolicyParams{
+		TemplateID:     template.ID,
+		AllowedCidrs:   cidrs,
+		AllowedDomains: domains,
+		UpdatedAt:      dbtime.Now(),
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template egress policy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = egressPolicy
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.TemplateEgressPolicy(egressPolicy))
+}
+
+// @Summary Delete template egress policy
+// @ID delete-template-egress-policy
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 204
+// @Router /templates/{template}/egress-policy [delete]
+func (api *API) deleteTemplateEgressPolicy(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		template          = httpmw.TemplateParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TemplateEgressPolicy](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionDelete,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	egressPolicy, err := api.Database.GetTemplateEgressPolicyByTemplateID(ctx, template.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template egress policy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = egressPolicy
+
+	err = api.Database.DeleteTemplateEgressPolicy(ctx, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting template egress policy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// workspaceAgentEgressPolicy returns the egress policy of the workspace's
+// template. The policy is read as the system so that enforcement does not
+// depend on the permissions of the workspace owner.
+//
+// @Summary Get workspace agent egress policy
+// @ID get-workspace-agent-egress-policy
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Success 200 {object} agentsdk.EgressPolicy
+// @Router /workspaceagents/me/egress-policy [get]
+func (api *API) workspaceAgentEgressPolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	//nolint:gocritic // Agents must enforce the policy of their template.
+	egressPolicy, err := api.Database.GetTemplateEgressPolicyByTemplateID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusOK, agentsdk.EgressPolicy{
+			AllowedCIDRs:   []string{},
+			AllowedDomains: []string{},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template egress policy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.EgressPolicy{
+		Enforced:       true,
+		AllowedCIDRs:   egressPolicy.AllowedCidrs,
+		AllowedDomains: egressPolicy.AllowedDomains,
+	})
+}
+
+// workspaceAgentReportEgressViolations records connections blocked by the
+// egress policy in the audit log, attributed to the workspace owner.
+//
+// @Summary Report workspace agent egress violations
+// @ID report-workspace-agent-egress-violations
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body agentsdk.ReportEgressViolationsRequest true "Egress violations"
+// @Success 204
+// @Router /workspaceagents/me/egress-violations [post]
+func (api *API) workspaceAgentReportEgressViolations(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.ReportEgressViolationsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if len(req.Violations) > agentsdk.MaxEgressViolationsPerReport {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("At most %d violations can be reported at once.", agentsdk.MaxEgressViolationsPerReport),
+		})
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	auditor := *api.Auditor.Load()
+	for _, violation := range req.Violations {
+		additionalFields, err := json.Marshal(struct {
+			audit.AdditionalFields
+			Destination string `json:"destination"`
+			Port        uint16 `json:"port"`
+			Protocol    string `json:"protocol"`
+		}{
+			AdditionalFields: audit.AdditionalFields{
+				WorkspaceName:  workspace.Name,
+				WorkspaceID:    workspace.ID,
+				WorkspaceOwner: workspace.OwnerUsername,
+			},
+			Destination: violation.Destination,
+			Port:        violation.Port,
+			Protocol:    violation.Protocol,
+		})
+		if err != nil {
+			api.Logger.Error(ctx, "marshal additional fields failed", slog.Error(err))
+		}
+
+		blockedAt := violation.BlockedAt
+		if blockedAt.IsZero() {
+			blockedAt = dbtime.Now()
+		}
+		audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.WorkspaceAgent]{
+			Audit:            auditor,
+			Log:              api.Logger,
+			UserID:           workspace.OwnerID,
+			RequestID:        httpmw.RequestID(r),
+			Time:             blockedAt,
+			Status:           http.StatusForbidden,
+			Action:           database.AuditActionEgressViolation,
+			OrganizationID:   workspace.OrganizationID,
+			IP:               r.RemoteAddr,
+			UserAgent:        r.UserAgent(),
+			AdditionalFields: additionalFields,
+			Old:              workspaceAgent,
+			New:              workspaceAgent,
+		})
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// normalizeEgressPolicy validates the destinations of an egress policy and
+// returns them in canonical form, sorted and without duplicates.
+func normalizeEgressPolicy(req codersdk.UpdateTemplateEgressPolicyRequest) ([]string, []string, []codersdk.ValidationError) {
+	var validErrs []codersdk.ValidationError
+	cidrs := make([]string, 0, len(req.AllowedCIDRs))
+	for _, raw := range req.AllowedCIDRs {
+		prefix, err := parseEgressPrefix(strings.TrimSpace(raw))
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "allowed_cidrs",
+				Detail: fmt.Sprintf("%q is not an IP address or CIDR range", raw),
+			})
+			continue
+		}
+		cidrs = append(cidrs, prefix.String())
+	}
+
+	domains := make([]string, 0, len(req.AllowedDomains))
+	for _, raw := range req.AllowedDomains {
+		domain := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(raw)), ".")
+		if err := codersdk.EgressDomainValid(domain); err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "allowed_domains",
+				Detail: fmt.Sprintf("%q %s", raw, err.Error()),
+			})
+			continue
+		}
+		domains = append(domains, domain)
+	}
+
+	slices.Sort(cidrs)
+	slices.Sort(domains)
+	return slices.Compact(cidrs), slices.Compact(domains), validErrs
+}
+
+// parseEgressPrefix parses a CIDR range or a single IP address, which is
+// treated as a range containing only that address.
+func parseEgressPrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
@@ -0,0 +1,170 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateEgressPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			Auditor:                  auditor,
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.TemplateEgressPolicy(ctx, template.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		egressPolicy, err := client.UpdateTemplateEgressPolicy(ctx, template.ID, codersdk.UpdateTemplateEgressPolicyRequest{
+			AllowedCIDRs:   []string{"10.1.2.3/8", "192.168.1.1", "10.0.0.0/8"},
+			AllowedDomains: []string{"GitHub.com.", "registry.npmjs.org"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"10.0.0.0/8", "192.168.1.1/32"}, egressPolicy.AllowedCIDRs)
+		require.Equal(t, []string{"github.com", "registry.npmjs.org"}, egressPolicy.AllowedDomains)
+
+		got, err := client.TemplateEgressPolicy(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, egressPolicy.AllowedCIDRs, got.AllowedCIDRs)
+		require.Equal(t, egressPolicy.AllowedDomains, got.AllowedDomains)
+
+		err = client.DeleteTemplateEgressPolicy(ctx, template.ID)
+		require.NoError(t, err)
+		_, err = client.TemplateEgressPolicy(ctx, template.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:         database.AuditActionWrite,
+			ResourceType:   database.ResourceTypeTemplateEgressPolicy,
+			ResourceID:     template.ID,
+			OrganizationID: owner.OrganizationID,
+		}))
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionDelete,
+			ResourceType: database.ResourceTypeTemplateEgressPolicy,
+			ResourceID:   template.ID,
+		}))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateTemplateEgressPolicy(ctx, template.ID, codersdk.UpdateTemplateEgressPolicyRequest{
+			AllowedCIDRs:   []string{"10.0.0.0/33"},
+			AllowedDomains: []string{"*.github.com"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 2)
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.UpdateTemplateEgressPolicy(ctx, template.ID, codersdk.UpdateTemplateEgressPolicyRequest{
+			AllowedDomains: []string{"github.com"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
+func TestWorkspaceAgentEgressPolicy(t *testing.T) {
+	t.Parallel()
+
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		Auditor:                  auditor,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+
+	egressPolicy, err := agentClient.EgressPolicy(ctx)
+	require.NoError(t, err)
+	require.False(t, egressPolicy.Enforced)
+
+	_, err = client.UpdateTemplateEgressPolicy(ctx, template.ID, codersdk.UpdateTemplateEgressPolicyRequest{
+		AllowedCIDRs:   []string{"10.0.0.0/8"},
+		AllowedDomains: []string{"github.com"},
+	})
+	require.NoError(t, err)
+
+	egressPolicy, err = agentClient.EgressPolicy(ctx)
+	require.NoError(t, err)
+	require.Equal(t, agentsdk.EgressPolicy{
+		Enforced:       true,
+		AllowedCIDRs:   []string{"10.0.0.0/8"},
+		AllowedDomains: []string{"github.com"},
+	}, egressPolicy)
+
+	err = agentClient.ReportEgressViolations(ctx, agentsdk.ReportEgressViolationsRequest{
+		Violations: []agentsdk.EgressViolation{{
+			Destination: "203.0.113.7",
+			Port:        443,
+			Protocol:    "tcp",
+		}},
+	})
+	require.NoError(t, err)
+
+	workspace, err = client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		Action:       database.AuditActionEgressViolation,
+		ResourceType: database.ResourceTypeWorkspaceAgent,
+		ResourceID:   workspace.LatestBuild.Resources[0].Agents[0].ID,
+		UserID:       user.UserID,
+		StatusCode:   http.StatusForbidden,
+	}))
+}
@@ -0,0 +1,242 @@
+package coderd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/parameteroptions"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get template parameter option sources
+// @ID get-template-parameter-option-sources
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateParameterOptionSource
+// @Router /templates/{template}/parameter-option-sources [get]
+func (api *API) templateParameterOptionSources(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	// Sources are part of the configuration of the template, so only users
+	// that can update the template can see them.
+	if !api.Authorize(r, policy.ActionUpdate, template) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	sources, err := api.Database.GetTemplateParameterOptionSources(ctx, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template parameter option sources.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(sources, convertTemplateParameterOptionSource))
+}
+
+// @Summary Create or update template parameter option source
+// @ID create-or-update-template-parameter-option-source
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param parameter path string true "Parameter name"
+// @Param request body codersdk.UpsertTemplateParameterOptionSourceRequest true "Option source request"
+// @Success 200 {object} codersdk.TemplateParameterOptionSource
+// @Router /templates/{template}/parameter-option-sources/{parameter} [put]
+func (api *API) putTemplateParameterOptionSource(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx           = r.Context()
+		template      = httpmw.TemplateParam(r)
+		parameterName = chi.URLParam(r, "parameter")
+	)
+
+	if !api.Authorize(r, policy.ActionUpdate, template) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.UpsertTemplateParameterOptionSourceRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	var validErrs []codersdk.ValidationError
+	if err := parameteroptions.ValidateURL(req.URL); err != nil {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "url", Detail: err.Error()})
+	}
+	if req.CacheTTLMillis < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "cache_ttl_ms", Detail: "Must be a positive integer."})
+	}
+	if req.AuthHeaderName == "" && req.AuthHeaderValue != nil && *req.AuthHeaderValue != "" {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "auth_header_name", Detail: "Required when an auth header value is set."})
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to update template parameter option source.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	cacheTTL := parameteroptions.DefaultCacheTTL
+	if req.CacheTTLMillis > 0 {
+		cacheTTL = time.Duration(req.CacheTTLMillis) * time.Millisecond
+	}
+
+	var source database.TemplateParameterOptionSource
+	err := api.Database.InTx(func(tx database.Store) error {
+		authHeaderValue := ""
+		if req.AuthHeaderValue != nil {
+			authHeaderValue = *req.AuthHeaderValue
+		} else if req.AuthHeaderName != "" {
+			existing, err := tx.GetTemplateParameterOptionSource(ctx, database.GetTemplateParameterOptionSourceParams{
+				TemplateID:    template.ID,
+				ParameterName: parameterName,
+			})
+			if err != nil && !httpapi.Is404Error(err) {
+				return err
+			}
+			authHeaderValue = existing.AuthHeaderValue
+		}
+
+		var err error
+		source, err = tx.UpsertTemplateParameterOptionSource(ctx, database.UpsertTemplateParameterOptionSourceParams{
+			TemplateID:      template.ID,
+			ParameterName:   parameterName,
+			Url:             req.URL,
+			AuthHeaderName:  req.AuthHeaderName,
+			AuthHeaderValue: authHeaderValue,
+			CacheTTL:        int64(cacheTTL),
+			UpdatedAt:       dbtime.Now(),
+		})
+		return err
+	}, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template parameter option source.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateParameterOptionSource(source))
+}
+
+// @Summary Delete template parameter option source
+// @ID delete-template-parameter-option-source
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param parameter path string true "Parameter name"
+// @Success 200 {object} codersdk.Response
+// @Router /templates/{template}/parameter-option-sources/{parameter} [delete]
+func (api *API) deleteTemplateParameterOptionSource(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx           = r.Context()
+		template      = httpmw.TemplateParam(r)
+		parameterName = chi.URLParam(r, "parameter")
+	)
+
+	err := api.Database.DeleteTemplateParameterOptionSource(ctx, database.DeleteTemplateParameterOptionSourceParams{
+		TemplateID:    template.ID,
+		ParameterName: parameterName,
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting template parameter option source.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.ParameterOptionsFetcher.Forget(template.ID, parameterName)
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Template parameter option source has been deleted!",
+	})
+}
+
+// @Summary Get template parameter options
+// @Description Fetches the options of a template parameter from its option
+// @Description source. Options are cached for the cache TTL of the source.
+// @ID get-template-parameter-options
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param parameter path string true "Parameter name"
+// @Success 200 {object} codersdk.TemplateParameterOptions
+// @Router /templates/{template}/parameter-option-sources/{parameter}/options [get]
+func (api *API) templateParameterOptions(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx           = r.Context()
+		template      = httpmw.TemplateParam(r)
+		parameterName = chi.URLParam(r, "parameter")
+	)
+
+	source, err := api.Database.GetTemplateParameterOptionSource(ctx, database.GetTemplateParameterOptionSourceParams{
+		TemplateID:    template.ID,
+		ParameterName: parameterName,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "The parameter has no option source.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template parameter option source.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	options, err := api.ParameterOptionsFetcher.Fetch(ctx, source)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadGateway, codersdk.Response{
+			Message: "Unable to fetch parameter options from the option source.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.TemplateParameterOptions{
+		Options:   options.Options,
+		FetchedAt: options.FetchedAt,
+	})
+}
+
+func convertTemplateParameterOptionSource(source database.TemplateParameterOptionSource) codersdk.TemplateParameterOptionSource {
+	return codersdk.TemplateParameterOptionSource{
+		TemplateID:         source.TemplateID,
+		ParameterName:      source.ParameterName,
+		URL:                source.Url,
+		AuthHeaderName:     source.AuthHeaderName,
+		HasAuthHeaderValue: source.AuthHeaderValue != "",
+		CacheTTLMillis:     time.Duration(source.CacheTTL).Milliseconds(),
+		CreatedAt:          source.CreatedAt,
+		UpdatedAt:          source.UpdatedAt,
+	}
+}
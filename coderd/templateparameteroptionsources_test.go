@@ -0,0 +1,85 @@
+package coderd_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateParameterOptionSources(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Inventory-Token") != "secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = rw.Write([]byte(`[{"value":"a100","name":"NVIDIA A100"},{"value":"h100"}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.RoleTemplateAdmin())
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	authHeaderValue := "secret"
+	source, err := templateAdmin.UpsertTemplateParameterOptionSource(ctx, template.ID, "gpu_pool", codersdk.UpsertTemplateParameterOptionSourceRequest{
+		URL:             srv.URL,
+		AuthHeaderName:  "X-Inventory-Token",
+		AuthHeaderValue: &authHeaderValue,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "gpu_pool", source.ParameterName)
+	require.True(t, source.HasAuthHeaderValue)
+
+	// Omitting the auth header value keeps the existing value.
+	_, err = templateAdmin.UpsertTemplateParameterOptionSource(ctx, template.ID, "gpu_pool", codersdk.UpsertTemplateParameterOptionSourceRequest{
+		URL:            srv.URL,
+		AuthHeaderName: "X-Inventory-Token",
+		CacheTTLMillis: 1000,
+	})
+	require.NoError(t, err)
+
+	// Members can fetch the options, but not see the sources.
+	options, err := member.TemplateParameterOptions(ctx, template.ID, "gpu_pool")
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.TemplateVersionParameterOption{
+		{Name: "NVIDIA A100", Value: "a100"},
+		{Name: "h100", Value: "h100"},
+	}, options.Options)
+
+	_, err = member.TemplateParameterOptionSources(ctx, template.ID)
+	require.Error(t, err)
+
+	sources, err := templateAdmin.TemplateParameterOptionSources(ctx, template.ID)
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	require.EqualValues(t, 1000, sources[0].CacheTTLMillis)
+
+	_, err = templateAdmin.UpsertTemplateParameterOptionSource(ctx, template.ID, "gpu_pool", codersdk.UpsertTemplateParameterOptionSourceRequest{
+		URL: "file:///etc/passwd",
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	err = templateAdmin.DeleteTemplateParameterOptionSource(ctx, template.ID, "gpu_pool")
+	require.NoError(t, err)
+
+	_, err = member.TemplateParameterOptions(ctx, template.ID, "gpu_pool")
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
@@ -0,0 +1,167 @@
+// Package templatepolicy evaluates imported template versions against Rego
+// policies configured by deployment administrators.
+package templatepolicy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"golang.org/x/xerrors"
+)
+
+// Query is the rule evaluated against every imported template version. It
+// must be a set of messages, each describing a violation of the policy.
+//
+//	package coder.templates
+//
+//	deny contains msg if {
+//		some r in input.plan.resource_changes
+//		r.type == "aws_instance"
+//		r.change.after.associate_public_ip_address
+//		msg := sprintf("%s must not have a public IP address", [r.address])
+//	}
+const Query = "data.coder.templates.deny"
+
+// Input is the document available to policies as `input`.
+type Input struct {
+	OrganizationID      uuid.UUID         `json:"organization_id"`
+	TemplateID          uuid.NullUUID     `json:"template_id"`
+	TemplateVersionID   uuid.UUID         `json:"template_version_id"`
+	TemplateVersionName string            `json:"template_version_name"`
+	InitiatorID         uuid.UUID         `json:"initiator_id"`
+	Tags                map[string]string `json:"tags"`
+	// Plan is the Terraform plan of the template version in the JSON output
+	// format of `terraform show -json`. It includes the parsed configuration
+	// of the template as well as the planned resource changes.
+	Plan json.RawMessage `json:"plan"`
+}
+
+// ViolationError is returned when a template version violates one or more
+// policies.
+type ViolationError struct {
+	Violations []string
+}
+
+func (e *ViolationError) Error() string {
+	var b strings.Builder
+	_, _ = b.WriteString("template version violates the deployment's template policies:")
+	for _, v := range e.Violations {
+		_, _ = fmt.Fprintf(&b, "\n- %s", v)
+	}
+	return b.String()
+}
+
+// Engine evaluates template versions against a set of compiled policies.
+type Engine struct {
+	query rego.PreparedEvalQuery
+}
+
+// New compiles the given Rego modules, keyed by file name.
+func New(ctx context.Context, modules map[string]string) (*Engine, error) {
+	if len(modules) == 0 {
+		return nil, xerrors.New("no template policies provided")
+	}
+
+	options := []func(*rego.Rego){rego.Query(Query)}
+	for name, module := range modules {
+		options = append(options, rego.Module(name, module))
+	}
+	query, err := rego.New(options...).PrepareForEval(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("compile template policies: %w", err)
+	}
+	return &Engine{query: query}, nil
+}
+
+// Load reads and compiles the policies at the given paths. A path may refer
+// to a single file or to a directory, in which case every `.rego` file
+// directly within it is loaded.
+func Load(ctx context.Context, paths []string) (*Engine, error) {
+	modules := make(map[string]string)
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, xerrors.Errorf("stat template policy %q: %w", path, err)
+		}
+
+		files := []string{path}
+		if info.IsDir() {
+			files, err = filepath.Glob(filepath.Join(path, "*.rego"))
+			if err != nil {
+				return nil, xerrors.Errorf("list template policies in %q: %w", path, err)
+			}
+		}
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, xerrors.Errorf("read template policy %q: %w", file, err)
+			}
+			modules[file] = string(data)
+		}
+	}
+	return New(ctx, modules)
+}
+
+// Evaluate evaluates the policies against the input. It returns a
+// *ViolationError if any policy denies the template version.
+func (e *Engine) Evaluate(ctx context.Context, input Input) error {
+	if len(input.Plan) == 0 {
+		input.Plan = json.RawMessage("{}")
+	}
+	raw, err := json.Marshal(input)
+	if err != nil {
+		return xerrors.Errorf("marshal input: %w", err)
+	}
+	// Decode the input into generic values so that numbers in the plan keep
+	// their precision.
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return xerrors.Errorf("decode input: %w", err)
+	}
+
+	results, err := e.query.Eval(ctx, rego.EvalInput(doc))
+	if err != nil {
+		return xerrors.Errorf("evaluate template policies: %w", err)
+	}
+
+	var violations []string
+	for _, result := range results {
+		for _, expr := range result.Expressions {
+			values, ok := expr.Value.([]any)
+			if !ok {
+				return xerrors.Errorf("%s must be a set, got %T", Query, expr.Value)
+			}
+			for _, value := range values {
+				violations = append(violations, message(value))
+			}
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	slices.Sort(violations)
+	return &ViolationError{Violations: violations}
+}
+
+// message converts a value of the deny set into a human readable message.
+// Policies usually produce strings, but any other value is rendered as JSON.
+func message(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,107 @@
+package templatepolicy_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/templatepolicy"
+	"github.com/coder/coder/v2/testutil"
+)
+
+const policy = `package coder.templates
+
+deny contains msg if {
+	some r in input.plan.resource_changes
+	r.type == "aws_instance"
+	r.change.after.associate_public_ip_address
+	msg := sprintf("%s must not have a public IP address", [r.address])
+}
+
+deny contains msg if {
+	some r in input.plan.resource_changes
+	r.type == "aws_instance"
+	not r.change.after.tags.team
+	msg := sprintf("%s must have a \"team\" tag", [r.address])
+}
+
+deny contains msg if {
+	some r in input.plan.resource_changes
+	r.type == "aws_instance"
+	not r.change.after.instance_type in {"t3.micro", "t3.small"}
+	msg := sprintf("%s uses instance type %q, allowed: t3.micro, t3.small", [r.address, r.change.after.instance_type])
+}
+`
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	engine, err := templatepolicy.New(ctx, map[string]string{"policy.rego": policy})
+	require.NoError(t, err)
+
+	input := func(plan string) templatepolicy.Input {
+		return templatepolicy.Input{
+			OrganizationID:    uuid.New(),
+			TemplateVersionID: uuid.New(),
+			Plan:              []byte(plan),
+		}
+	}
+
+	t.Run("Allowed", func(t *testing.T) {
+		t.Parallel()
+		err := engine.Evaluate(ctx, input(`{"resource_changes":[{
+			"address":"aws_instance.dev","type":"aws_instance",
+			"change":{"after":{"instance_type":"t3.micro","tags":{"team":"platform"}}}
+		}]}`))
+		require.NoError(t, err)
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		t.Parallel()
+		err := engine.Evaluate(ctx, input(`{"resource_changes":[{
+			"address":"aws_instance.dev","type":"aws_instance",
+			"change":{"after":{"instance_type":"p4d.24xlarge","associate_public_ip_address":true}}
+		}]}`))
+		var violation *templatepolicy.ViolationError
+		require.ErrorAs(t, err, &violation)
+		require.Equal(t, []string{
+			`aws_instance.dev must have a "team" tag`,
+			`aws_instance.dev must not have a public IP address`,
+			`aws_instance.dev uses instance type "p4d.24xlarge", allowed: t3.micro, t3.small`,
+		}, violation.Violations)
+		require.Contains(t, err.Error(), "\n- aws_instance.dev must not have a public IP address")
+	})
+
+	t.Run("NoPlan", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, engine.Evaluate(ctx, input("")))
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tags.rego"), []byte(`package coder.templates
+
+deny contains "templates must be named" if input.template_version_name == ""
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a policy"), 0o600))
+
+	engine, err := templatepolicy.Load(ctx, []string{dir})
+	require.NoError(t, err)
+	err = engine.Evaluate(ctx, templatepolicy.Input{})
+	require.ErrorContains(t, err, "templates must be named")
+	require.NoError(t, engine.Evaluate(ctx, templatepolicy.Input{TemplateVersionName: "v1"}))
+
+	_, err = templatepolicy.Load(ctx, []string{filepath.Join(dir, "missing.rego")})
+	require.ErrorContains(t, err, "stat template policy")
+
+	_, err = templatepolicy.New(ctx, map[string]string{"invalid.rego": "package coder.templates\ndeny contains"})
+	require.ErrorContains(t, err, "compile template policies")
+}
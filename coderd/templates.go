@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -23,6 +25,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/parametervalidation"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/schedule"
@@ -32,6 +35,7 @@ import (
 	"github.com/coder/coder/v2/coderd/workspacestats"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/examples"
+	"github.com/coder/coder/v2/tailnet"
 )
 
 // Returns a single template.
@@ -48,7 +52,9 @@ func (api *API) template(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	template := httpmw.TemplateParam(r)
 
-	httpapi.Write(ctx, rw, http.StatusOK, api.convertTemplate(template))
+	resp := api.convertTemplate(template)
+	httpapi.SetETag(rw, resp.ETag())
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
 // @Summary Delete template by ID
@@ -57,6 +63,7 @@ func (api *API) template(rw http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Tags Templates
 // @Param template path string true "Template ID" format(uuid)
+// @Param If-Match header string false "Only apply the change if the resource still has this ETag"
 // @Success 200 {object} codersdk.Response
 // @Router /templates/{template} [delete]
 func (api *API) deleteTemplate(rw http.ResponseWriter, r *http.Request) {
@@ -76,6 +83,10 @@ func (api *API) deleteTemplate(rw http.ResponseWriter, r *http.Request) {
 	defer commitAudit()
 	aReq.Old = template
 
+	if !httpapi.IfMatch(ctx, rw, r, api.convertTemplate(template).ETag()) {
+		return
+	}
+
 	// This is just to get the workspace count, so we use a system context to
 	// return ALL workspaces. Not just workspaces the user can view.
 	// nolint:gocritic
@@ -293,6 +304,20 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 	)
 	if createTemplate.DefaultTTLMillis != nil {
 		defaultTTL = time.Duration(*createTemplate.DefaultTTLMillis) * time.Millisecond
+	} else {
+		// Use the default autostop of the organization, if it overrides the
+		// deployment default.
+		overrides, err := api.Database.GetOrganizationSettingOverrides(ctx, organization.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching organization setting overrides.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if overrides.DefaultTTL.Valid {
+			defaultTTL = time.Duration(overrides.DefaultTTL.Int64)
+		}
 	}
 	if createTemplate.ActivityBumpMillis != nil {
 		activityBump = time.Duration(*createTemplate.ActivityBumpMillis) * time.Millisecond
@@ -607,7 +632,9 @@ func (api *API) templateByOrganizationAndName(rw http.ResponseWriter, r *http.Re
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, api.convertTemplate(template))
+	resp := api.convertTemplate(template)
+	httpapi.SetETag(rw, resp.ETag())
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
 // @Summary Update template metadata by ID
@@ -616,6 +643,7 @@ func (api *API) templateByOrganizationAndName(rw http.ResponseWriter, r *http.Re
 // @Produce json
 // @Tags Templates
 // @Param template path string true "Template ID" format(uuid)
+// @Param If-Match header string false "Only apply the change if the resource still has this ETag"
 // @Success 200 {object} codersdk.Template
 // @Router /templates/{template} [patch]
 func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
@@ -648,6 +676,9 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	if !httpapi.Read(ctx, rw, r, &req) {
 		return
 	}
+	if !httpapi.IfMatch(ctx, rw, r, api.convertTemplate(template).ETag()) {
+		return
+	}
 
 	var (
 		validErrs                            []codersdk.ValidationError
@@ -725,6 +756,80 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Defaults to the existing.
+	wireguardMTU := template.WireguardMTU
+	if req.WireguardMTU != nil {
+		wireguardMTU = *req.WireguardMTU
+	}
+	if wireguardMTU != 0 && (wireguardMTU < tailnet.MinMTU || wireguardMTU > tailnet.MaxMTU) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "wireguard_mtu", Detail: fmt.Sprintf("Value must be between %d and %d.", tailnet.MinMTU, tailnet.MaxMTU)})
+	}
+	wireguardKeepaliveInterval := time.Duration(template.WireguardKeepaliveInterval)
+	if req.WireguardKeepaliveIntervalMillis != nil {
+		wireguardKeepaliveInterval = time.Duration(*req.WireguardKeepaliveIntervalMillis) * time.Millisecond
+	}
+	if wireguardKeepaliveInterval < 0 || (wireguardKeepaliveInterval > 0 && wireguardKeepaliveInterval < time.Second) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "wireguard_keepalive_interval_ms", Detail: "Value must be at least one second."})
+	}
+	wireguardHandshakeTimeout := time.Duration(template.WireguardHandshakeTimeout)
+	if req.WireguardHandshakeTimeoutMillis != nil {
+		wireguardHandshakeTimeout = time.Duration(*req.WireguardHandshakeTimeoutMillis) * time.Millisecond
+	}
+	if wireguardHandshakeTimeout < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "wireguard_handshake_timeout_ms", Detail: "Must be a positive integer."})
+	}
+	activationApprovalsRequired := template.ActivationApprovalsRequired
+	if req.ActivationApprovalsRequired != nil {
+		activationApprovalsRequired = *req.ActivationApprovalsRequired
+	}
+	if activationApprovalsRequired < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "activation_approvals_required", Detail: "Must be a positive integer."})
+	}
+	autostartHolidays := template.AutostartHolidays
+	if req.AutostartHolidays != nil {
+		holidays, err := schedule.ParseHolidays(*req.AutostartHolidays)
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "autostart_holidays", Detail: err.Error()})
+		}
+		autostartHolidays = holidays.Dates()
+	}
+	autostartHolidayCalendarURL := template.AutostartHolidayCalendarURL
+	if req.AutostartHolidayCalendarURL != nil {
+		autostartHolidayCalendarURL = *req.AutostartHolidayCalendarURL
+		if autostartHolidayCalendarURL != "" {
+			if err := schedule.ValidateHolidayCalendarURL(autostartHolidayCalendarURL); err != nil {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: "autostart_holiday_calendar_url", Detail: err.Error()})
+			}
+		}
+	}
+	dormantArchivePaths := template.DormantArchivePaths
+	if req.DormantArchivePaths != nil {
+		dormantArchivePaths = make([]string, 0, len(*req.DormantArchivePaths))
+		for _, path := range *req.DormantArchivePaths {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: "dormant_archive_paths", Detail: "Paths must not be empty."})
+				break
+			}
+			if !slices.Contains(dormantArchivePaths, path) {
+				dormantArchivePaths = append(dormantArchivePaths, path)
+			}
+		}
+	}
+	parameterValidationURL := template.ParameterValidationURL
+	if req.ParameterValidationURL != nil {
+		parameterValidationURL = *req.ParameterValidationURL
+		if parameterValidationURL != "" {
+			if err := parametervalidation.ValidateURL(parameterValidationURL); err != nil {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: "parameter_validation_url", Detail: err.Error()})
+			}
+		}
+	}
+	allowStartOnConnect := template.AllowStartOnConnect
+	if req.AllowStartOnConnect != nil {
+		allowStartOnConnect = *req.AllowStartOnConnect
+	}
+
 	if len(validErrs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message:     "Invalid request to update template metadata!",
@@ -759,6 +864,15 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			req.RequireActiveVersion == template.RequireActiveVersion &&
 			(deprecationMessage == template.Deprecated) &&
 			(classicTemplateFlow == template.UseClassicParameterFlow) &&
+			wireguardMTU == template.WireguardMTU &&
+			wireguardKeepaliveInterval == time.Duration(template.WireguardKeepaliveInterval) &&
+			wireguardHandshakeTimeout == time.Duration(template.WireguardHandshakeTimeout) &&
+			activationApprovalsRequired == template.ActivationApprovalsRequired &&
+			slices.Equal(autostartHolidays, template.AutostartHolidays) &&
+			autostartHolidayCalendarURL == template.AutostartHolidayCalendarURL &&
+			slices.Equal(dormantArchivePaths, template.DormantArchivePaths) &&
+			parameterValidationURL == template.ParameterValidationURL &&
+			allowStartOnConnect == template.AllowStartOnConnect &&
 			maxPortShareLevel == template.MaxPortSharingLevel {
 			return nil
 		}
@@ -775,6 +889,14 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 		}
 
 		if template.MaxPortSharingLevel != maxPortShareLevel {
+			// Port share links are public, so they are revoked as soon
+			// as public port sharing isn't allowed anymore.
+			if maxPortShareLevel != database.AppSharingLevelPublic {
+				err = tx.DeleteWorkspacePortShareLinksByTemplate(ctx, template.ID)
+				if err != nil {
+					return xerrors.Errorf("delete workspace port share links by template: %w", err)
+				}
+			}
 			switch maxPortShareLevel {
 			case database.AppSharingLevelOwner:
 				err = tx.DeleteWorkspaceAgentPortSharesByTemplate(ctx, template.ID)
@@ -801,6 +923,15 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			GroupACL:                     groupACL,
 			MaxPortSharingLevel:          maxPortShareLevel,
 			UseClassicParameterFlow:      classicTemplateFlow,
+			WireguardMTU:                 wireguardMTU,
+			WireguardKeepaliveInterval:   int64(wireguardKeepaliveInterval),
+			WireguardHandshakeTimeout:    int64(wireguardHandshakeTimeout),
+			ActivationApprovalsRequired:  activationApprovalsRequired,
+			AutostartHolidays:            autostartHolidays,
+			AutostartHolidayCalendarURL:  autostartHolidayCalendarURL,
+			DormantArchivePaths:          dormantArchivePaths,
+			ParameterValidationURL:       parameterValidationURL,
+			AllowStartOnConnect:          allowStartOnConnect,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template metadata: %w", err)
@@ -897,7 +1028,9 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	}
 	aReq.New = updated
 
-	httpapi.Write(ctx, rw, http.StatusOK, api.convertTemplate(updated))
+	resp := api.convertTemplate(updated)
+	httpapi.SetETag(rw, resp.ETag())
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
 func (api *API) notifyUsersOfTemplateDeprecation(ctx context.Context, template database.Template) error {
@@ -1084,6 +1217,17 @@ func (api *API) convertTemplate(
 		DeprecationMessage:      templateAccessControl.Deprecated,
 		MaxPortShareLevel:       maxPortShareLevel,
 		UseClassicParameterFlow: template.UseClassicParameterFlow,
+
+		WireguardMTU:                     template.WireguardMTU,
+		WireguardKeepaliveIntervalMillis: time.Duration(template.WireguardKeepaliveInterval).Milliseconds(),
+		WireguardHandshakeTimeoutMillis:  time.Duration(template.WireguardHandshakeTimeout).Milliseconds(),
+
+		ActivationApprovalsRequired: template.ActivationApprovalsRequired,
+		AutostartHolidays:           template.AutostartHolidays,
+		AutostartHolidayCalendarURL: template.AutostartHolidayCalendarURL,
+		DormantArchivePaths:         template.DormantArchivePaths,
+		ParameterValidationURL:      template.ParameterValidationURL,
+		AllowStartOnConnect:         template.AllowStartOnConnect,
 	}
 }
 
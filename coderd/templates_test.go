@@ -1578,6 +1578,52 @@ func TestPatchTemplateMeta(t *testing.T) {
 		require.NoError(t, err)
 		assert.False(t, updated.UseClassicParameterFlow, "expected false")
 	})
+
+	t.Run("AutostartHolidays", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Empty(t, template.AutostartHolidays)
+		require.Empty(t, template.AutostartHolidayCalendarURL)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Dates are deduplicated and sorted.
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			AutostartHolidays:           &[]string{"2025-12-25", "2025-01-01", "2025-12-25"},
+			AutostartHolidayCalendarURL: ptr.Ref("https://example.com/holidays.ics"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"2025-01-01", "2025-12-25"}, updated.AutostartHolidays)
+		require.Equal(t, "https://example.com/holidays.ics", updated.AutostartHolidayCalendarURL)
+
+		// Omitted values are unchanged.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"2025-01-01", "2025-12-25"}, updated.AutostartHolidays)
+		require.Equal(t, "https://example.com/holidays.ics", updated.AutostartHolidayCalendarURL)
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			AutostartHolidays:           &[]string{"12/25/2025"},
+			AutostartHolidayCalendarURL: ptr.Ref("file:///etc/holidays.ics"),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 2)
+
+		// Empty values clear the holidays.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			AutostartHolidays:           &[]string{},
+			AutostartHolidayCalendarURL: ptr.Ref(""),
+		})
+		require.NoError(t, err)
+		require.Empty(t, updated.AutostartHolidays)
+		require.Empty(t, updated.AutostartHolidayCalendarURL)
+	})
 }
 
 func TestDeleteTemplate(t *testing.T) {
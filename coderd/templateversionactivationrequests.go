@@ -0,0 +1,784 @@
+package coderd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/pkg/diff"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/files"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// requestTemplateVersionActivation creates a request to activate the template
+// version, which is activated once it has been approved by the number of
+// reviewers required by the template.
+func (api *API) requestTemplateVersionActivation(rw http.ResponseWriter, r *http.Request, template database.Template, version database.TemplateVersion) {
+	ctx := r.Context()
+
+	created, err := api.createTemplateVersionActivationRequest(rw, r, template, version)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error requesting template version activation.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !created {
+		httpapi.Write(ctx, rw, http.StatusAccepted, codersdk.Response{
+			Message: "The activation of this template version has already been requested and is awaiting approval.",
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusAccepted, codersdk.Response{
+		Message: fmt.Sprintf("Requested the activation of the template version, it will be activated once approved by %d reviewer(s).", template.ActivationApprovalsRequired),
+	})
+}
+
+// createTemplateVersionActivationRequest requests the activation of the
+// template version and notifies its reviewers, unless its activation is
+// already pending. It reports whether a request was created.
+func (api *API) createTemplateVersionActivationRequest(rw http.ResponseWriter, r *http.Request, template database.Template, version database.TemplateVersion) (bool, error) {
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.AuditableTemplateVersionActivationRequest](rw, &audit.RequestParams{
+			Audit:          auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionCreate,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	existing, err := api.Database.GetTemplateVersionActivationRequestByTemplateVersionID(ctx, version.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, xerrors.Errorf("get template version activation request: %w", err)
+	}
+	if err == nil && existing.Status == database.TemplateVersionActivationRequestStatusPending {
+		return false, nil
+	}
+
+	var request database.TemplateVersionActivationRequest
+	err = api.Database.InTx(func(tx database.Store) error {
+		request, err = tx.UpsertTemplateVersionActivationRequest(ctx, database.UpsertTemplateVersionActivationRequestParams{
+			TemplateVersionID: version.ID,
+			TemplateID:        template.ID,
+			RequestedBy:       apiKey.UserID,
+			ApprovalsRequired: template.ActivationApprovalsRequired,
+			CreatedAt:         dbtime.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("upsert template version activation request: %w", err)
+		}
+		// Reviews of a previous request, for example one that was rejected,
+		// don't apply to the new request.
+		err = tx.DeleteTemplateVersionActivationReviews(ctx, version.ID)
+		if err != nil {
+			return xerrors.Errorf("delete template version activation reviews: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return false, err
+	}
+	aReq.New = request.Auditable(version.Name, nil)
+
+	api.notifyTemplateVersionActivationRequested(ctx, template, version, request)
+	return true, nil
+}
+
+// templateVersionActivationApproved reports whether the activation of the
+// template version has been approved. Approved versions can be activated, or
+// rolled out, even if the template requires approvals.
+func templateVersionActivationApproved(ctx context.Context, store database.Store, versionID uuid.UUID) (bool, error) {
+	request, err := store.GetTemplateVersionActivationRequestByTemplateVersionID(ctx, versionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, xerrors.Errorf("get template version activation request: %w", err)
+	}
+	return request.Status == database.TemplateVersionActivationRequestStatusApproved, nil
+}
+
+func (api *API) notifyTemplateVersionActivationRequested(ctx context.Context, template database.Template, version database.TemplateVersion, request database.TemplateVersionActivationRequest) {
+	requester, err := api.Database.GetUserByID(ctx, request.RequestedBy)
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to fetch requester for template version activation notification", slog.F("requester_id", request.RequestedBy), slog.Error(err))
+		return
+	}
+
+	// nolint:gocritic // The requester may not be allowed to list the reviewers.
+	reviewerIDs, err := findTemplateActivationReviewers(dbauthz.AsSystemRestricted(ctx), api.Database, template.ID)
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to fetch reviewers for template version activation notification", slog.Error(err))
+		return
+	}
+
+	for _, reviewerID := range reviewerIDs {
+		// Requesters can't review their own requests.
+		if reviewerID == request.RequestedBy {
+			continue
+		}
+		// nolint:gocritic // Need notifier actor to enqueue notifications
+		if _, err := api.NotificationsEnqueuer.Enqueue(dbauthz.AsNotifier(ctx), reviewerID, notifications.TemplateTemplateVersionActivationRequested,
+			map[string]string{
+				"template":           template.Name,
+				"version":            version.Name,
+				"requester":          requester.Username,
+				"approvals_required": strconv.Itoa(int(request.ApprovalsRequired)),
+				"organization":       template.OrganizationName,
+			}, "api-templates-versions-activate",
+			// Associate this notification with all the related entities.
+			template.ID, version.ID, request.RequestedBy,
+		); err != nil {
+			api.Logger.Warn(ctx, "failed to notify of template version activation request", slog.F("reviewer_id", reviewerID), slog.Error(err))
+		}
+	}
+}
+
+// findTemplateActivationReviewers returns the users that review the
+// activation requests of the template: its designated reviewers, or the
+// template admins when none are designated.
+func findTemplateActivationReviewers(ctx context.Context, store database.Store, templateID uuid.UUID) ([]uuid.UUID, error) {
+	designated, err := store.GetTemplateActivationReviewers(ctx, templateID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template activation reviewers: %w", err)
+	}
+	if len(designated) > 0 {
+		ids := make([]uuid.UUID, 0, len(designated))
+		for _, reviewer := range designated {
+			ids = append(ids, reviewer.UserID)
+		}
+		return ids, nil
+	}
+
+	admins, err := findTemplateAdmins(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(admins))
+	for _, admin := range admins {
+		ids = append(ids, admin.ID)
+	}
+	return ids, nil
+}
+
+// @Summary Get template version activation request
+// @ID get-template-version-activation-request
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateVersionActivationRequest
+// @Router /templateversions/{templateversion}/activation-request [get]
+func (api *API) templateVersionActivationRequest(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx             = r.Context()
+		templateVersion = httpmw.TemplateVersionParam(r)
+	)
+
+	request, err := api.Database.GetTemplateVersionActivationRequestByTemplateVersionID(ctx, templateVersion.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "No activation has been requested for this template version.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version activation request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	reviews, err := api.Database.GetTemplateVersionActivationReviews(ctx, templateVersion.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version activation reviews.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersionActivationRequest(request, reviews))
+}
+
+// @Summary Review template version activation request
+// @ID review-template-version-activation-request
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Param request body codersdk.ReviewTemplateVersionActivationRequest true "Review"
+// @Success 200 {object} codersdk.TemplateVersionActivationRequest
+// @Router /templateversions/{templateversion}/activation-request/reviews [post]
+func (api *API) postTemplateVersionActivationReview(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		templateVersion   = httpmw.TemplateVersionParam(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.AuditableTemplateVersionActivationRequest](rw, &audit.RequestParams{
+			Audit:          auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: templateVersion.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.ReviewTemplateVersionActivationRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	existing, err := api.Database.GetTemplateVersionActivationRequestByTemplateVersionID(ctx, templateVersion.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "No activation has been requested for this template version.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version activation request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	template, err := api.Database.GetTemplateByID(ctx, existing.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	designated, err := api.Database.GetTemplateActivationReviewers(ctx, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template activation reviewers.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Designated reviewers don't need to be able to update the template, the
+	// review is stored on their behalf.
+	txCtx := ctx
+	if len(designated) > 0 {
+		if !slices.ContainsFunc(designated, func(reviewer database.GetTemplateActivationReviewersRow) bool {
+			return reviewer.UserID == apiKey.UserID
+		}) {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: "Only the designated reviewers of the template can review its activation requests.",
+			})
+			return
+		}
+		// nolint:gocritic // The reviewer was authorized against the designated reviewers.
+		txCtx = dbauthz.AsSystemRestricted(ctx)
+	} else if !api.Authorize(r, policy.ActionUpdate, template) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if existing.RequestedBy == apiKey.UserID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You can't review your own activation request.",
+		})
+		return
+	}
+	if existing.Status != database.TemplateVersionActivationRequestStatusPending {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("The activation request is %s and can no longer be reviewed.", existing.Status),
+		})
+		return
+	}
+	if templateVersion.Archived {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The template version is archived.",
+		})
+		return
+	}
+
+	oldReviews, err := api.Database.GetTemplateVersionActivationReviews(ctx, templateVersion.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version activation reviews.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	request := database.TemplateVersionActivationRequest{
+		TemplateVersionID: existing.TemplateVersionID,
+		TemplateID:        existing.TemplateID,
+		RequestedBy:       existing.RequestedBy,
+		Status:            existing.Status,
+		ApprovalsRequired: existing.ApprovalsRequired,
+		CreatedAt:         existing.CreatedAt,
+		UpdatedAt:         existing.UpdatedAt,
+	}
+	aReq.Old = request.Auditable(templateVersion.Name, oldReviews)
+
+	var (
+		reviews   []database.GetTemplateVersionActivationReviewsRow
+		rolledOut bool
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		_, err := tx.UpsertTemplateVersionActivationReview(txCtx, database.UpsertTemplateVersionActivationReviewParams{
+			TemplateVersionID: templateVersion.ID,
+			ReviewerID:        apiKey.UserID,
+			Approved:          req.Approved,
+			Comment:           req.Comment,
+			CreatedAt:         dbtime.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("upsert template version activation review: %w", err)
+		}
+		reviews, err = tx.GetTemplateVersionActivationReviews(txCtx, templateVersion.ID)
+		if err != nil {
+			return xerrors.Errorf("get template version activation reviews: %w", err)
+		}
+
+		status := database.TemplateVersionActivationRequestStatusPending
+		switch {
+		case !req.Approved:
+			status = database.TemplateVersionActivationRequestStatusRejected
+		case countApprovals(reviews) >= request.ApprovalsRequired:
+			status = database.TemplateVersionActivationRequestStatusApproved
+		}
+		if status == request.Status {
+			return nil
+		}
+		request, err = tx.UpdateTemplateVersionActivationRequestStatus(txCtx, database.UpdateTemplateVersionActivationRequestStatusParams{
+			Status:            status,
+			UpdatedAt:         dbtime.Now(),
+			TemplateVersionID: templateVersion.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("update template version activation request status: %w", err)
+		}
+
+		rollout, err := tx.GetTemplateVersionRolloutByTemplateID(txCtx, template.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("get template version rollout: %w", err)
+		}
+		pendingRollout := err == nil && rollout.TemplateVersionID == templateVersion.ID &&
+			rollout.Status == database.TemplateVersionRolloutStatusPendingApproval
+		if status == database.TemplateVersionActivationRequestStatusRejected && pendingRollout {
+			// The rolled out version isn't the active version, so finishing
+			// the rollout cancels it.
+			err = tx.FinishTemplateVersionRollout(txCtx, database.FinishTemplateVersionRolloutParams{
+				ActiveVersionID: template.ActiveVersionID,
+				UpdatedAt:       dbtime.Now(),
+				TemplateID:      template.ID,
+			})
+			if err != nil {
+				return xerrors.Errorf("cancel template version rollout: %w", err)
+			}
+		}
+		if status != database.TemplateVersionActivationRequestStatusApproved {
+			return nil
+		}
+		if pendingRollout {
+			// The version was approved to be rolled out, it's activated once
+			// the rollout is completed.
+			_, err = tx.StartTemplateVersionRollout(txCtx, database.StartTemplateVersionRolloutParams{
+				UpdatedAt:         dbtime.Now(),
+				TemplateID:        template.ID,
+				TemplateVersionID: templateVersion.ID,
+			})
+			if err != nil {
+				return xerrors.Errorf("start template version rollout: %w", err)
+			}
+			rolledOut = true
+			return nil
+		}
+
+		err = tx.UpdateTemplateActiveVersionByID(txCtx, database.UpdateTemplateActiveVersionByIDParams{
+			ID:              template.ID,
+			ActiveVersionID: templateVersion.ID,
+			UpdatedAt:       dbtime.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("update active version: %w", err)
+		}
+		err = tx.CancelPendingTemplateVersionActivationRequests(txCtx, database.CancelPendingTemplateVersionActivationRequestsParams{
+			UpdatedAt:       dbtime.Now(),
+			TemplateID:      template.ID,
+			ActiveVersionID: templateVersion.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("cancel pending activation requests: %w", err)
+		}
+		err = tx.FinishTemplateVersionRollout(txCtx, database.FinishTemplateVersionRolloutParams{
+			ActiveVersionID: templateVersion.ID,
+			UpdatedAt:       dbtime.Now(),
+			TemplateID:      template.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("finish template version rollout: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reviewing template version activation request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = request.Auditable(templateVersion.Name, reviews)
+
+	if request.Status == database.TemplateVersionActivationRequestStatusApproved && !rolledOut {
+		templateAReq, commitTemplateAudit := audit.InitRequest[database.Template](rw, &audit.RequestParams{
+			Audit:          auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: template.OrganizationID,
+		})
+		defer commitTemplateAudit()
+		templateAReq.Old = template
+		newTemplate := template
+		newTemplate.ActiveVersionID = templateVersion.ID
+		templateAReq.New = newTemplate
+
+		api.publishTemplateUpdate(ctx, template.ID)
+	}
+
+	existing.Status = request.Status
+	existing.UpdatedAt = request.UpdatedAt
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersionActivationRequest(existing, reviews))
+}
+
+// @Summary Get template activation reviewers
+// @ID get-template-activation-reviewers
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.MinimalUser
+// @Router /templates/{template}/activation-reviewers [get]
+func (api *API) templateActivationReviewers(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	reviewers, err := api.Database.GetTemplateActivationReviewers(ctx, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template activation reviewers.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateActivationReviewers(reviewers))
+}
+
+// @Summary Update template activation reviewers
+// @ID update-template-activation-reviewers
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.UpdateTemplateActivationReviewersRequest true "Reviewers"
+// @Success 200 {array} codersdk.MinimalUser
+// @Router /templates/{template}/activation-reviewers [put]
+func (api *API) putTemplateActivationReviewers(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	if !api.Authorize(r, policy.ActionUpdate, template) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateTemplateActivationReviewersRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.UserIDs == nil {
+		// A null array doesn't match any reviewer to remove.
+		req.UserIDs = []uuid.UUID{}
+	}
+
+	members, err := api.Database.OrganizationMembers(ctx, database.OrganizationMembersParams{
+		OrganizationID: template.OrganizationID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching organization members.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	var validErrs []codersdk.ValidationError
+	for _, userID := range req.UserIDs {
+		if !slices.ContainsFunc(members, func(member database.OrganizationMembersRow) bool {
+			return member.OrganizationMember.UserID == userID
+		}) {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "user_ids",
+				Detail: fmt.Sprintf("User %s isn't a member of the template's organization.", userID),
+			})
+		}
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid activation reviewers.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	var reviewers []database.GetTemplateActivationReviewersRow
+	err = api.Database.InTx(func(tx database.Store) error {
+		err := tx.UpdateTemplateActivationReviewers(ctx, database.UpdateTemplateActivationReviewersParams{
+			TemplateID: template.ID,
+			UserIds:    req.UserIDs,
+		})
+		if err != nil {
+			return xerrors.Errorf("update template activation reviewers: %w", err)
+		}
+		reviewers, err = tx.GetTemplateActivationReviewers(ctx, template.ID)
+		if err != nil {
+			return xerrors.Errorf("get template activation reviewers: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template activation reviewers.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateActivationReviewers(reviewers))
+}
+
+// @Summary Get template version diff against the active version
+// @ID get-template-version-diff-against-the-active-version
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateVersionDiff
+// @Router /templateversions/{templateversion}/diff [get]
+func (api *API) templateVersionDiff(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx             = r.Context()
+		templateVersion = httpmw.TemplateVersionParam(r)
+	)
+
+	if !templateVersion.TemplateID.Valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The template version isn't associated with a template.",
+		})
+		return
+	}
+	template, err := api.Database.GetTemplateByID(ctx, templateVersion.TemplateID.UUID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	activeVersion, err := api.Database.GetTemplateVersionByID(ctx, template.ActiveVersionID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching active template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	activeFS, err := api.acquireTemplateVersionFS(ctx, activeVersion)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching active template version files.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer activeFS.Close()
+	versionFS, err := api.acquireTemplateVersionFS(ctx, templateVersion)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version files.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer versionFS.Close()
+
+	unified, err := diffTemplateFiles(activeFS, versionFS)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error comparing template versions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.TemplateVersionDiff{
+		ActiveVersionID: activeVersion.ID,
+		Diff:            unified,
+	})
+}
+
+func (api *API) acquireTemplateVersionFS(ctx context.Context, version database.TemplateVersion) (*files.CloseFS, error) {
+	job, err := api.Database.GetProvisionerJobByID(ctx, version.JobID)
+	if err != nil {
+		return nil, xerrors.Errorf("get provisioner job: %w", err)
+	}
+	return api.FileCache.Acquire(ctx, api.Database, job.FileID)
+}
+
+// diffTemplateFiles returns the unified diff of the regular files of two
+// template versions. Files that only exist in one of the versions are
+// compared against an empty file.
+func diffTemplateFiles(a, b fs.FS) (string, error) {
+	aFiles, err := readTemplateFiles(a)
+	if err != nil {
+		return "", xerrors.Errorf("read files: %w", err)
+	}
+	bFiles, err := readTemplateFiles(b)
+	if err != nil {
+		return "", xerrors.Errorf("read files: %w", err)
+	}
+
+	names := make([]string, 0, len(aFiles)+len(bFiles))
+	for name := range aFiles {
+		names = append(names, name)
+	}
+	for name := range bFiles {
+		if _, ok := aFiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		aData, aOK := aFiles[name]
+		bData, bOK := bFiles[name]
+		if bytes.Equal(aData, bData) {
+			continue
+		}
+		aName, bName := "a/"+name, "b/"+name
+		if !aOK {
+			aName = "/dev/null"
+		}
+		if !bOK {
+			bName = "/dev/null"
+		}
+		err := diff.Text(aName, bName, aData, bData, &buf)
+		if err != nil {
+			return "", xerrors.Errorf("diff %q: %w", name, err)
+		}
+	}
+	return buf.String(), nil
+}
+
+func readTemplateFiles(fsys fs.FS) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		files[path] = data
+		return nil
+	})
+	return files, err
+}
+
+func countApprovals(reviews []database.GetTemplateVersionActivationReviewsRow) int32 {
+	var approvals int32
+	for _, review := range reviews {
+		if review.Approved {
+			approvals++
+		}
+	}
+	return approvals
+}
+
+func convertTemplateVersionActivationRequest(request database.GetTemplateVersionActivationRequestByTemplateVersionIDRow, reviews []database.GetTemplateVersionActivationReviewsRow) codersdk.TemplateVersionActivationRequest {
+	converted := codersdk.TemplateVersionActivationRequest{
+		TemplateVersionID: request.TemplateVersionID,
+		TemplateID:        request.TemplateID,
+		RequestedBy: codersdk.MinimalUser{
+			ID:        request.RequestedBy,
+			Username:  request.RequestedByUsername,
+			AvatarURL: request.RequestedByAvatarURL,
+		},
+		Status:            codersdk.TemplateVersionActivationRequestStatus(request.Status),
+		ApprovalsRequired: request.ApprovalsRequired,
+		Reviews:           make([]codersdk.TemplateVersionActivationReview, 0, len(reviews)),
+		CreatedAt:         request.CreatedAt,
+		UpdatedAt:         request.UpdatedAt,
+	}
+	for _, review := range reviews {
+		converted.Reviews = append(converted.Reviews, codersdk.TemplateVersionActivationReview{
+			Reviewer: codersdk.MinimalUser{
+				ID:        review.ReviewerID,
+				Username:  review.ReviewerUsername,
+				AvatarURL: review.ReviewerAvatarURL,
+			},
+			Approved:  review.Approved,
+			Comment:   review.Comment,
+			CreatedAt: review.CreatedAt,
+		})
+	}
+	return converted
+}
+
+func convertTemplateActivationReviewers(reviewers []database.GetTemplateActivationReviewersRow) []codersdk.MinimalUser {
+	converted := make([]codersdk.MinimalUser, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		converted = append(converted, codersdk.MinimalUser{
+			ID:        reviewer.UserID,
+			Username:  reviewer.Username,
+			AvatarURL: reviewer.AvatarURL,
+		})
+	}
+	return converted
+}
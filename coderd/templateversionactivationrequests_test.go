@@ -0,0 +1,225 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateVersionActivationRequest(t *testing.T) {
+	t.Parallel()
+
+	// setup creates a template requiring one approval to activate versions,
+	// and requests the activation of a new version as a template admin.
+	setup := func(t *testing.T) (owner *codersdk.Client, firstUser codersdk.CreateFirstUserResponse, author *codersdk.Client, template codersdk.Template, version codersdk.TemplateVersion) {
+		t.Helper()
+		owner = coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		firstUser = coderdtest.CreateFirstUser(t, owner)
+		author, _ = coderdtest.CreateAnotherUser(t, owner, firstUser.OrganizationID, rbac.RoleTemplateAdmin())
+
+		active := coderdtest.CreateTemplateVersion(t, author, firstUser.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, author, active.ID)
+		template = coderdtest.CreateTemplate(t, author, firstUser.OrganizationID, active.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		template, err := owner.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			ActivationApprovalsRequired: ptr.Ref[int32](1),
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, template.ActivationApprovalsRequired)
+
+		version = coderdtest.UpdateTemplateVersion(t, author, firstUser.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, author, version.ID)
+
+		// Promoting the version requests its activation instead.
+		err = author.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: version.ID,
+		})
+		require.NoError(t, err)
+		template, err = owner.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, active.ID, template.ActiveVersionID)
+
+		request, err := owner.TemplateVersionActivationRequest(ctx, version.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateVersionActivationRequestStatusPending, request.Status)
+		require.EqualValues(t, 1, request.ApprovalsRequired)
+		return owner, firstUser, author, template, version
+	}
+
+	t.Run("Approve", func(t *testing.T) {
+		t.Parallel()
+		owner, _, author, template, version := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Authors can't review their own requests.
+		_, err := author.ReviewTemplateVersionActivationRequest(ctx, version.ID, codersdk.ReviewTemplateVersionActivationRequest{
+			Approved: true,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		request, err := owner.ReviewTemplateVersionActivationRequest(ctx, version.ID, codersdk.ReviewTemplateVersionActivationRequest{
+			Approved: true,
+			Comment:  "Looks good to me.",
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateVersionActivationRequestStatusApproved, request.Status)
+		require.Len(t, request.Reviews, 1)
+		require.Equal(t, "Looks good to me.", request.Reviews[0].Comment)
+
+		template, err = owner.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, template.ActiveVersionID)
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		t.Parallel()
+		owner, _, author, template, version := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		request, err := owner.ReviewTemplateVersionActivationRequest(ctx, version.ID, codersdk.ReviewTemplateVersionActivationRequest{
+			Approved: false,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateVersionActivationRequestStatusRejected, request.Status)
+
+		updated, err := owner.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, template.ActiveVersionID, updated.ActiveVersionID)
+
+		// Rejected requests can't be approved afterwards, but the activation
+		// can be requested again.
+		_, err = owner.ReviewTemplateVersionActivationRequest(ctx, version.ID, codersdk.ReviewTemplateVersionActivationRequest{
+			Approved: true,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		err = author.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: version.ID,
+		})
+		require.NoError(t, err)
+		request, err = owner.TemplateVersionActivationRequest(ctx, version.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateVersionActivationRequestStatusPending, request.Status)
+		require.Empty(t, request.Reviews)
+	})
+
+	t.Run("DesignatedReviewers", func(t *testing.T) {
+		t.Parallel()
+		owner, firstUser, _, template, version := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		reviewer, reviewerUser := coderdtest.CreateAnotherUser(t, owner, firstUser.OrganizationID)
+		templateAdmin, _ := coderdtest.CreateAnotherUser(t, owner, firstUser.OrganizationID, rbac.RoleTemplateAdmin())
+
+		reviewers, err := owner.UpdateTemplateActivationReviewers(ctx, template.ID, codersdk.UpdateTemplateActivationReviewersRequest{
+			UserIDs: []uuid.UUID{reviewerUser.ID},
+		})
+		require.NoError(t, err)
+		require.Len(t, reviewers, 1)
+		require.Equal(t, reviewerUser.ID, reviewers[0].ID)
+
+		// Members can't designate reviewers.
+		_, err = reviewer.UpdateTemplateActivationReviewers(ctx, template.ID, codersdk.UpdateTemplateActivationReviewersRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// Template admins that aren't designated can't review the request.
+		_, err = templateAdmin.ReviewTemplateVersionActivationRequest(ctx, version.ID, codersdk.ReviewTemplateVersionActivationRequest{
+			Approved: true,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		request, err := reviewer.ReviewTemplateVersionActivationRequest(ctx, version.ID, codersdk.ReviewTemplateVersionActivationRequest{
+			Approved: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateVersionActivationRequestStatusApproved, request.Status)
+
+		template, err = owner.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, template.ActiveVersionID)
+
+		// Clearing the reviewers lets template admins review requests again.
+		reviewers, err = owner.UpdateTemplateActivationReviewers(ctx, template.ID, codersdk.UpdateTemplateActivationReviewersRequest{})
+		require.NoError(t, err)
+		require.Empty(t, reviewers)
+	})
+
+	t.Run("Rollout", func(t *testing.T) {
+		t.Parallel()
+		owner, _, author, template, version := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Rolling out a version that hasn't been approved waits for the
+		// approval of its activation.
+		rollout, err := author.StartTemplateVersionRollout(ctx, template.ID, codersdk.CreateTemplateVersionRolloutRequest{
+			TemplateVersionID: version.ID,
+			Percentage:        10,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateVersionRolloutStatusPendingApproval, rollout.Status)
+
+		_, err = owner.ReviewTemplateVersionActivationRequest(ctx, version.ID, codersdk.ReviewTemplateVersionActivationRequest{
+			Approved: true,
+		})
+		require.NoError(t, err)
+
+		// Approving the request starts the rollout instead of activating the
+		// version.
+		rollout, err = owner.TemplateVersionRollout(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateVersionRolloutStatusInProgress, rollout.Status)
+		updated, err := owner.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, template.ActiveVersionID, updated.ActiveVersionID)
+
+		// The approved version can be activated to complete the rollout.
+		err = author.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: version.ID,
+		})
+		require.NoError(t, err)
+		updated, err = owner.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, updated.ActiveVersionID)
+		rollout, err = owner.TemplateVersionRollout(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateVersionRolloutStatusCompleted, rollout.Status)
+	})
+
+	t.Run("ApprovalsNotRequired", func(t *testing.T) {
+		t.Parallel()
+		owner, _, author, template, version := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Once approvals are no longer required, versions are activated
+		// immediately.
+		_, err := owner.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			ActivationApprovalsRequired: ptr.Ref[int32](0),
+		})
+		require.NoError(t, err)
+		err = author.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: version.ID,
+		})
+		require.NoError(t, err)
+
+		template, err = owner.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, template.ActiveVersionID)
+	})
+}
@@ -0,0 +1,360 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// defaultTemplateVersionRolloutMinimumBuilds is the number of finished builds
+// of a rolled out version required before the failure threshold is evaluated,
+// when none is provided.
+const defaultTemplateVersionRolloutMinimumBuilds = 5
+
+// @Summary Get template version rollout
+// @ID get-template-version-rollout
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateVersionRollout
+// @Router /templates/{template}/rollout [get]
+func (api *API) templateVersionRollout(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	rollout, err := api.Database.GetTemplateVersionRolloutByTemplateID(ctx, template.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "No template version has been rolled out for this template.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version rollout.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiRollout, err := api.convertTemplateVersionRollout(ctx, rollout)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version rollout builds.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiRollout)
+}
+
+// @Summary Start template version rollout
+// @ID start-template-version-rollout
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.CreateTemplateVersionRolloutRequest true "Rollout request"
+// @Success 201 {object} codersdk.TemplateVersionRollout
+// @Success 202 {object} codersdk.TemplateVersionRollout
+// @Router /templates/{template}/rollout [post]
+func (api *API) postTemplateVersionRollout(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		template          = httpmw.TemplateParam(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TemplateVersionRollout](rw, &audit.RequestParams{
+			Audit:          auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionCreate,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	if !api.Authorize(r, policy.ActionUpdate, template) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.CreateTemplateVersionRolloutRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Percentage == 0 && len(req.GroupIDs) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "A rollout requires a percentage of workspaces or groups to roll out to.",
+		})
+		return
+	}
+	if req.MinimumBuilds == 0 {
+		req.MinimumBuilds = defaultTemplateVersionRolloutMinimumBuilds
+	}
+	groupIDs := slice.Unique(req.GroupIDs)
+	if groupIDs == nil {
+		groupIDs = []uuid.UUID{}
+	}
+
+	version, err := api.Database.GetTemplateVersionByID(ctx, req.TemplateVersionID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Template version not found.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if version.TemplateID.UUID != template.ID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version doesn't belong to the specified template.",
+		})
+		return
+	}
+	if version.ID == template.ActiveVersionID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version is already the active version.",
+		})
+		return
+	}
+	if version.Archived {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version is archived.",
+		})
+		return
+	}
+	job, err := api.Database.GetProvisionerJobByID(ctx, version.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version job status.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if job.JobStatus != database.ProvisionerJobStatusSucceeded {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only versions that have been built successfully can be rolled out.",
+			Detail:  fmt.Sprintf("Attempted to roll out a version with a %s build", job.JobStatus),
+		})
+		return
+	}
+	if len(groupIDs) > 0 {
+		groups, err := api.Database.GetGroups(ctx, database.GetGroupsParams{
+			OrganizationID: template.OrganizationID,
+			GroupIds:       groupIDs,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching groups.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if len(groups) != len(groupIDs) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Groups must exist in the organization of the template.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "group_ids",
+					Detail: "One or more groups were not found.",
+				}},
+			})
+			return
+		}
+	}
+
+	var (
+		rollout           database.TemplateVersionRollout
+		requestActivation bool
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		// Rolled out versions are built like active versions, so they require
+		// the same approvals. The template is locked like when activating a
+		// version.
+		activation, err := tx.GetTemplateActivationForUpdate(ctx, template.ID)
+		if err != nil {
+			return xerrors.Errorf("get template activation: %w", err)
+		}
+		status := database.TemplateVersionRolloutStatusInProgress
+		if activation.ActivationApprovalsRequired > 0 {
+			approved, err := templateVersionActivationApproved(ctx, tx, version.ID)
+			if err != nil {
+				return err
+			}
+			if !approved {
+				template.ActivationApprovalsRequired = activation.ActivationApprovalsRequired
+				status = database.TemplateVersionRolloutStatusPendingApproval
+				requestActivation = true
+			}
+		}
+
+		rollout, err = tx.UpsertTemplateVersionRollout(ctx, database.UpsertTemplateVersionRolloutParams{
+			TemplateID:              template.ID,
+			TemplateVersionID:       version.ID,
+			CreatedBy:               apiKey.UserID,
+			Percentage:              req.Percentage,
+			GroupIds:                groupIDs,
+			FailureThresholdPercent: req.FailureThresholdPercent,
+			MinimumBuilds:           req.MinimumBuilds,
+			Status:                  status,
+			CreatedAt:               dbtime.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("upsert template version rollout: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error starting template version rollout.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = rollout
+
+	statusCode := http.StatusCreated
+	if requestActivation {
+		// The rollout starts once the activation of the version has been
+		// approved.
+		_, err = api.createTemplateVersionActivationRequest(rw, r, template, version)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error requesting template version activation.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		statusCode = http.StatusAccepted
+	}
+
+	apiRollout, err := api.convertTemplateVersionRollout(ctx, rollout)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version rollout builds.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, statusCode, apiRollout)
+}
+
+// @Summary Cancel template version rollout
+// @ID cancel-template-version-rollout
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /templates/{template}/rollout [delete]
+func (api *API) deleteTemplateVersionRollout(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		template          = httpmw.TemplateParam(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TemplateVersionRollout](rw, &audit.RequestParams{
+			Audit:          auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	if !api.Authorize(r, policy.ActionUpdate, template) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var oldRollout, newRollout database.TemplateVersionRollout
+	err := api.Database.InTx(func(tx database.Store) error {
+		var err error
+		oldRollout, err = tx.GetTemplateVersionRolloutByTemplateID(ctx, template.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("get template version rollout: %w", err)
+		}
+		// The rolled out version isn't the active version, so finishing the
+		// rollout cancels it.
+		err = tx.FinishTemplateVersionRollout(ctx, database.FinishTemplateVersionRolloutParams{
+			ActiveVersionID: template.ActiveVersionID,
+			UpdatedAt:       dbtime.Now(),
+			TemplateID:      template.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("finish template version rollout: %w", err)
+		}
+		newRollout, err = tx.GetTemplateVersionRolloutByTemplateID(ctx, template.ID)
+		if err != nil {
+			return xerrors.Errorf("get template version rollout: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error canceling template version rollout.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if oldRollout.Status != newRollout.Status {
+		aReq.Old = oldRollout
+		aReq.New = newRollout
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Canceled the template version rollout.",
+	})
+}
+
+func (api *API) convertTemplateVersionRollout(ctx context.Context, rollout database.TemplateVersionRollout) (codersdk.TemplateVersionRollout, error) {
+	var stats database.GetTemplateVersionRolloutBuildStatsRow
+	if rollout.Status == database.TemplateVersionRolloutStatusInProgress || rollout.Status == database.TemplateVersionRolloutStatusHalted {
+		var err error
+		stats, err = api.Database.GetTemplateVersionRolloutBuildStats(ctx, rollout.TemplateID)
+		if err != nil {
+			return codersdk.TemplateVersionRollout{}, xerrors.Errorf("get template version rollout build stats: %w", err)
+		}
+	}
+
+	return codersdk.TemplateVersionRollout{
+		TemplateID:              rollout.TemplateID,
+		TemplateVersionID:       rollout.TemplateVersionID,
+		CreatedByID:             rollout.CreatedBy,
+		Percentage:              rollout.Percentage,
+		GroupIDs:                rollout.GroupIds,
+		FailureThresholdPercent: rollout.FailureThresholdPercent,
+		MinimumBuilds:           rollout.MinimumBuilds,
+		Status:                  codersdk.TemplateVersionRolloutStatus(rollout.Status),
+		TotalBuilds:             stats.TotalBuilds,
+		FailedBuilds:            stats.FailedBuilds,
+		CreatedAt:               rollout.CreatedAt,
+		UpdatedAt:               rollout.UpdatedAt,
+	}, nil
+}
@@ -51,7 +51,9 @@ import (
 // @Produce json
 // @Tags Templates
 // @Param templateversion path string true "Template version ID" format(uuid)
+// @Param If-None-Match header string false "Only return the resource if it no longer has this ETag"
 // @Success 200 {object} codersdk.TemplateVersion
+// @Success 304
 // @Router /templateversions/{templateversion} [get]
 func (api *API) templateVersion(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -102,7 +104,7 @@ func (api *API) templateVersion(rw http.ResponseWriter, r *http.Request) {
 		warnings = append(warnings, codersdk.TemplateVersionWarningUnsupportedWorkspaces)
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersion(templateVersion, convertProvisionerJob(jobs[0]), matchedProvisioners, warnings))
+	httpapi.WriteCacheable(ctx, rw, r, convertTemplateVersion(templateVersion, convertProvisionerJob(jobs[0]), matchedProvisioners, warnings))
 }
 
 // @Summary Patch template version by ID
@@ -1344,13 +1346,14 @@ func (api *API) setArchiveTemplateVersion(archive bool) func(rw http.ResponseWri
 // @Param request body codersdk.UpdateActiveTemplateVersion true "Modified template version"
 // @Param template path string true "Template ID" format(uuid)
 // @Success 200 {object} codersdk.Response
+// @Success 202 {object} codersdk.Response
 // @Router /templates/{template}/versions [patch]
 func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Request) {
 	var (
 		ctx               = r.Context()
 		template          = httpmw.TemplateParam(r)
 		auditor           = *api.Auditor.Load()
-		aReq, commitAudit = audit.InitRequest[database.Template](rw, &audit.RequestParams{
+		aReq, commitAudit = audit.InitRequestWithCancel[database.Template](rw, &audit.RequestParams{
 			Audit:          auditor,
 			Log:            api.Logger,
 			Request:        r,
@@ -1358,7 +1361,7 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 			OrganizationID: template.OrganizationID,
 		})
 	)
-	defer commitAudit()
+	defer commitAudit(true)
 	aReq.Old = template
 
 	var req codersdk.UpdateActiveTemplateVersion
@@ -1407,7 +1410,28 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	var requestActivation bool
 	err = api.Database.InTx(func(store database.Store) error {
+		// The approvals required are checked with the template locked, so
+		// that they can't be enabled between the check and the update.
+		activation, err := store.GetTemplateActivationForUpdate(ctx, template.ID)
+		if err != nil {
+			return xerrors.Errorf("get template activation: %w", err)
+		}
+		if activation.ActivationApprovalsRequired > 0 && activation.ActiveVersionID != version.ID {
+			// Versions approved to be rolled out can be activated to
+			// complete their rollout.
+			approved, err := templateVersionActivationApproved(ctx, store, version.ID)
+			if err != nil {
+				return err
+			}
+			if !approved {
+				template.ActivationApprovalsRequired = activation.ActivationApprovalsRequired
+				requestActivation = true
+				return nil
+			}
+		}
+
 		err = store.UpdateTemplateActiveVersionByID(ctx, database.UpdateTemplateActiveVersionByIDParams{
 			ID:              template.ID,
 			ActiveVersionID: req.ID,
@@ -1416,6 +1440,23 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 		if err != nil {
 			return xerrors.Errorf("update active version: %w", err)
 		}
+		// Pending requests to activate other versions no longer apply.
+		err = store.CancelPendingTemplateVersionActivationRequests(ctx, database.CancelPendingTemplateVersionActivationRequestsParams{
+			UpdatedAt:       dbtime.Now(),
+			TemplateID:      template.ID,
+			ActiveVersionID: req.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("cancel pending activation requests: %w", err)
+		}
+		err = store.FinishTemplateVersionRollout(ctx, database.FinishTemplateVersionRolloutParams{
+			ActiveVersionID: req.ID,
+			UpdatedAt:       dbtime.Now(),
+			TemplateID:      template.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("finish template version rollout: %w", err)
+		}
 		return nil
 	}, nil)
 	if err != nil {
@@ -1425,6 +1466,13 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 		})
 		return
 	}
+	if requestActivation {
+		// The template isn't modified until the version has been approved,
+		// the activation request is audited instead.
+		commitAudit(false)
+		api.requestTemplateVersionActivation(rw, r, template, version)
+		return
+	}
 	newTemplate := template
 	newTemplate.ActiveVersionID = req.ID
 	aReq.New = newTemplate
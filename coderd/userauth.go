@@ -111,7 +111,7 @@ func (api *API) postConvertLoginType(rw http.ResponseWriter, r *http.Request) {
 	switch req.ToType {
 	case codersdk.LoginTypeGithub, codersdk.LoginTypeOIDC:
 		// Allowed!
-	case codersdk.LoginTypeNone, codersdk.LoginTypePassword, codersdk.LoginTypeToken:
+	case codersdk.LoginTypeNone, codersdk.LoginTypePassword, codersdk.LoginTypeToken, codersdk.LoginTypeSAML:
 		// These login types are not allowed to be converted to at this time.
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: fmt.Sprintf("Cannot convert to login type %q.", req.ToType),
@@ -499,6 +499,7 @@ func (*API) validateUserPassword(rw http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Tags Authorization
 // @Param request body codersdk.LoginWithPasswordRequest true "Login request"
+// @Success 200 {object} codersdk.LoginWithPasswordResponse
 // @Success 201 {object} codersdk.LoginWithPasswordResponse
 // @Router /users/login [post]
 func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
@@ -530,29 +531,25 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	//nolint:gocritic // Creating the API key as the user instead of as system.
-	cookie, key, err := api.createAPIKey(dbauthz.As(ctx, actor), apikey.CreateParams{
-		UserID:          user.ID,
-		LoginType:       database.LoginTypePassword,
-		RemoteAddr:      r.RemoteAddr,
-		DefaultLifetime: api.DeploymentValues.Sessions.DefaultDuration.Value(),
-	})
+	// Users with a WebAuthn credential, or that are required to have one, must
+	// complete a second factor before they get a session.
+	mfa, err := api.loginMFAChallenge(ctx, user)
 	if err != nil {
-		logger.Error(ctx, "unable to create API key", slog.Error(err))
+		logger.Error(ctx, "unable to create webauthn challenge", slog.Error(err))
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Failed to create API key.",
+			Message: "Internal error.",
 			Detail:  err.Error(),
 		})
 		return
 	}
+	if mfa != nil {
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.LoginWithPasswordResponse{
+			MFA: mfa,
+		})
+		return
+	}
 
-	aReq.New = *key
-
-	http.SetCookie(rw, cookie)
-
-	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
-		SessionToken: cookie.Value,
-	})
+	api.writeLoginSession(ctx, rw, r, aReq, user, actor, nil)
 }
 
 // loginRequest will process a LoginWithPasswordRequest and return the user if
@@ -803,6 +800,14 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, r *http.Request) {
 	if api.OIDCConfig != nil {
 		iconURL = api.OIDCConfig.IconURL
 	}
+	saml := codersdk.SAMLAuthMethod{}
+	if api.SAMLConfig != nil {
+		saml = codersdk.SAMLAuthMethod{
+			AuthMethod: codersdk.AuthMethod{Enabled: true},
+			SignInText: api.SAMLConfig.SignInText,
+			IconURL:    api.SAMLConfig.IconURL,
+		}
+	}
 
 	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.AuthMethods{
 		TermsOfServiceURL: api.DeploymentValues.TermsOfServiceURL.Value(),
@@ -818,6 +823,7 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, r *http.Request) {
 			SignInText: signInText,
 			IconURL:    iconURL,
 		},
+		SAML: saml,
 	})
 }
 
@@ -1354,30 +1360,11 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		username = codersdk.UsernameFrom(username)
 	}
 
-	if len(api.OIDCConfig.EmailDomain) > 0 {
-		ok = false
-		emailSp := strings.Split(email, "@")
-		if len(emailSp) == 1 {
-			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
-				Message: fmt.Sprintf("Your email %q is not from an authorized domain! Please contact your administrator.", email),
-			})
-			return
-		}
-		userEmailDomain := emailSp[len(emailSp)-1]
-		for _, domain := range api.OIDCConfig.EmailDomain {
-			// Folks sometimes enter EmailDomain with a leading '@'.
-			domain = strings.TrimPrefix(domain, "@")
-			if strings.EqualFold(userEmailDomain, domain) {
-				ok = true
-				break
-			}
-		}
-		if !ok {
-			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
-				Message: fmt.Sprintf("Your email %q is not from an authorized domain! Please contact your administrator.", email),
-			})
-			return
-		}
+	if len(api.OIDCConfig.EmailDomain) > 0 && !emailDomainAllowed(email, api.OIDCConfig.EmailDomain) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Your email %q is not from an authorized domain! Please contact your administrator.", email),
+		})
+		return
 	}
 
 	// The 'name' is an optional property in Coder. If not specified,
@@ -1545,6 +1532,23 @@ func (api *API) userInfoClaims(ctx context.Context, rw http.ResponseWriter, stat
 }
 
 // claimFields returns the sorted list of fields in the claims map.
+// emailDomainAllowed reports whether the email is from one of the domains.
+func emailDomainAllowed(email string, domains []string) bool {
+	emailSp := strings.Split(email, "@")
+	if len(emailSp) == 1 {
+		return false
+	}
+	userEmailDomain := emailSp[len(emailSp)-1]
+	for _, domain := range domains {
+		// Folks sometimes enter EmailDomain with a leading '@'.
+		domain = strings.TrimPrefix(domain, "@")
+		if strings.EqualFold(userEmailDomain, domain) {
+			return true
+		}
+	}
+	return false
+}
+
 func claimFields(claims map[string]interface{}) []string {
 	fields := []string{}
 	for field := range claims {
@@ -1925,10 +1929,12 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 	} else {
 		//nolint:gocritic
 		cookie, newKey, err := api.createAPIKey(dbauthz.AsSystemRestricted(ctx), apikey.CreateParams{
-			UserID:          user.ID,
-			LoginType:       params.LoginType,
-			DefaultLifetime: api.DeploymentValues.Sessions.DefaultDuration.Value(),
-			RemoteAddr:      r.RemoteAddr,
+			UserID:            user.ID,
+			LoginType:         params.LoginType,
+			DefaultLifetime:   api.DeploymentValues.Sessions.DefaultDuration.Value(),
+			RemoteAddr:        r.RemoteAddr,
+			UserAgent:         r.UserAgent(),
+			DeviceFingerprint: apikey.DeviceFingerprint(r.Header),
 		})
 		if err != nil {
 			return nil, database.User{}, database.APIKey{}, xerrors.Errorf("create API key: %w", err)
@@ -1,6 +1,7 @@
 package userpassword
 
 import (
+	"crypto/pbkdf2"
 	"crypto/rand"
 	"crypto/sha256"
 	"crypto/subtle"
@@ -12,7 +13,6 @@ import (
 	"strings"
 
 	passwordvalidator "github.com/wagslane/go-password-validator"
-	"golang.org/x/crypto/pbkdf2"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/util/lazy"
@@ -101,7 +101,11 @@ func Compare(hashed string, password string) (bool, error) {
 		return false, xerrors.Errorf("decode salt: %w", err)
 	}
 
-	if subtle.ConstantTimeCompare([]byte(hashWithSaltAndIter(password, salt, iter)), []byte(hashed)) != 1 {
+	computed, err := hashWithSaltAndIter(password, salt, iter)
+	if err != nil {
+		return false, err
+	}
+	if subtle.ConstantTimeCompare([]byte(computed), []byte(hashed)) != 1 {
 		return false, nil
 	}
 
@@ -117,13 +121,18 @@ func Hash(password string) (string, error) {
 		return "", xerrors.Errorf("read random bytes for salt: %w", err)
 	}
 
-	return hashWithSaltAndIter(password, salt, defaultHashIter), nil
+	return hashWithSaltAndIter(password, salt, defaultHashIter)
 }
 
-// Produces a string representation of the hash.
-func hashWithSaltAndIter(password string, salt []byte, iter int) string {
+// Produces a string representation of the hash. The standard library
+// implementation of pbkdf2 is used because it is part of the Go Cryptographic
+// Module, so hashing stays FIPS 140-3 compliant in FIPS mode.
+func hashWithSaltAndIter(password string, salt []byte, iter int) (string, error) {
+	hash, err := pbkdf2.Key(sha256.New, password, salt, iter, hashLength)
+	if err != nil {
+		return "", xerrors.Errorf("derive key: %w", err)
+	}
 	var (
-		hash    = pbkdf2.Key([]byte(password), salt, iter, hashLength, sha256.New)
 		encHash = make([]byte, base64Encoding.EncodedLen(len(hash)))
 		encSalt = make([]byte, base64Encoding.EncodedLen(len(salt)))
 	)
@@ -131,7 +140,7 @@ func hashWithSaltAndIter(password string, salt []byte, iter int) string {
 	base64Encoding.Encode(encHash, hash)
 	base64Encoding.Encode(encSalt, salt)
 
-	return fmt.Sprintf("$%s$%d$%s$%s", hashScheme, iter, encSalt, encHash)
+	return fmt.Sprintf("$%s$%d$%s$%s", hashScheme, iter, encSalt, encHash), nil
 }
 
 // Validate checks that the plain text password meets the minimum password requirements.
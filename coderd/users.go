@@ -145,6 +145,10 @@ func (api *API) postFirstUser(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !followsNamePolicy(ctx, rw, &api.DeploymentValues.Naming.Username, "Username", "username", createUser.Username) {
+		return
+	}
+
 	err = userpassword.Validate(createUser.Password)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -391,6 +395,10 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !followsNamePolicy(ctx, rw, &api.DeploymentValues.Naming.Username, "Username", "username", req.Username) {
+		return
+	}
+
 	// TODO: @emyrk Authorize the organization create if the createUser will do that.
 
 	_, err := api.Database.GetUserByEmailOrUsername(ctx, database.GetUserByEmailOrUsernameParams{
@@ -465,6 +473,14 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 			return
 		}
 		loginType = database.LoginTypeOIDC
+	case codersdk.LoginTypeSAML:
+		if api.SAMLConfig == nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "You must configure SAML before creating SAML users.",
+			})
+			return
+		}
+		loginType = database.LoginTypeSaml
 	case codersdk.LoginTypeGithub:
 		loginType = database.LoginTypeGithub
 	default:
@@ -622,7 +638,9 @@ func (api *API) deleteUser(rw http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Tags Users
 // @Param user path string true "User ID, username, or me"
+// @Param If-None-Match header string false "Only return the resource if it no longer has this ETag"
 // @Success 200 {object} codersdk.User
+// @Success 304
 // @Router /users/{user} [get]
 func (api *API) userByName(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -636,7 +654,7 @@ func (api *API) userByName(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.User(user, organizationIDs))
+	httpapi.WriteCacheable(ctx, rw, r, db2sdk.User(user, organizationIDs))
 }
 
 // Returns recent build parameters for the signed-in user.
@@ -750,6 +768,11 @@ func (api *API) putUserProfile(rw http.ResponseWriter, r *http.Request) {
 	if !httpapi.Read(ctx, rw, r, &params) {
 		return
 	}
+	// Existing usernames are kept until they are changed.
+	if params.Username != user.Username &&
+		!followsNamePolicy(ctx, rw, &api.DeploymentValues.Naming.Username, "Username", "username", params.Username) {
+		return
+	}
 	existentUser, err := api.Database.GetUserByEmailOrUsername(ctx, database.GetUserByEmailOrUsernameParams{
 		Username: params.Username,
 	})
@@ -1276,7 +1299,7 @@ func (api *API) putUserRoles(rw http.ResponseWriter, r *http.Request) {
 	defer commitAudit()
 	aReq.Old = user
 
-	if user.LoginType == database.LoginTypeOIDC && api.IDPSync.SiteRoleSyncEnabled() {
+	if (user.LoginType == database.LoginTypeOIDC || user.LoginType == database.LoginTypeSaml) && api.IDPSync.SiteRoleSyncEnabled() {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Cannot modify roles for OIDC users when role sync is enabled.",
 			Detail:  "'User Role Field' is set in the OIDC configuration. All role changes must come from the oidc identity provider.",
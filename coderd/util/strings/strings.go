@@ -18,13 +18,30 @@ func JoinWithConjunction(s []string) string {
 	)
 }
 
+// TruncateOption changes how Truncate shortens a string.
+type TruncateOption int
+
+const (
+	// TruncateWithEllipsis replaces the last character that fits with an
+	// ellipsis when the string is truncated.
+	TruncateWithEllipsis TruncateOption = 1 << iota
+)
+
 // Truncate returns the first n characters of s.
-func Truncate(s string, n int) string {
+func Truncate(s string, n int, opts ...TruncateOption) string {
+	var options TruncateOption
+	for _, opt := range opts {
+		options |= opt
+	}
 	if n < 1 {
 		return ""
 	}
-	if len(s) <= n {
+	r := []rune(s)
+	if len(r) <= n {
 		return s
 	}
-	return s[:n]
+	if options&TruncateWithEllipsis != 0 {
+		return string(r[:n-1]) + "…"
+	}
+	return string(r[:n])
 }
@@ -21,18 +21,24 @@ func TestTruncate(t *testing.T) {
 	for _, tt := range []struct {
 		s        string
 		n        int
+		options  []strings.TruncateOption
 		expected string
 	}{
-		{"foo", 4, "foo"},
-		{"foo", 3, "foo"},
-		{"foo", 2, "fo"},
-		{"foo", 1, "f"},
-		{"foo", 0, ""},
-		{"foo", -1, ""},
+		{"foo", 4, nil, "foo"},
+		{"foo", 3, nil, "foo"},
+		{"foo", 2, nil, "fo"},
+		{"foo", 1, nil, "f"},
+		{"foo", 0, nil, ""},
+		{"foo", -1, nil, ""},
+		{"héllo", 2, nil, "hé"},
+		{"foo", 3, []strings.TruncateOption{strings.TruncateWithEllipsis}, "foo"},
+		{"foo", 2, []strings.TruncateOption{strings.TruncateWithEllipsis}, "f…"},
+		{"foo", 1, []strings.TruncateOption{strings.TruncateWithEllipsis}, "…"},
+		{"héllo", 3, []strings.TruncateOption{strings.TruncateWithEllipsis}, "hé…"},
 	} {
 		t.Run(tt.expected, func(t *testing.T) {
 			t.Parallel()
-			actual := strings.Truncate(tt.s, tt.n)
+			actual := strings.Truncate(tt.s, tt.n, tt.options...)
 			require.Equal(t, tt.expected, actual)
 		})
 	}
@@ -0,0 +1,34 @@
+// Package tokenfile writes session tokens to files that are read by other
+// processes, such as a restarted agent or sibling proxy replicas.
+package tokenfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Write replaces the token file atomically, so it is never read half
+// written. The file is only readable by its owner.
+func Write(path, token string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.WriteString(token)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	err = tmp.Chmod(0o600)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
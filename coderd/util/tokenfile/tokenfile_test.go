@@ -0,0 +1,36 @@
+package tokenfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/util/tokenfile"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	require.NoError(t, tokenfile.Write(path, "first"))
+	require.NoError(t, tokenfile.Write(path, "second"))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "second", string(b))
+
+	// The temporary files are cleaned up.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+}
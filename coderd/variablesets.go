@@ -0,0 +1,525 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get variable sets
+// @ID get-variable-sets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Success 200 {array} codersdk.VariableSet
+// @Router /variable-sets [get]
+func (api *API) variableSets(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sets, err := api.Database.GetVariableSets(ctx)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching variable sets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp, err := convertVariableSets(sets)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Get variable set by name
+// @ID get-variable-set-by-name
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param variableset path string true "Variable set name"
+// @Success 200 {object} codersdk.VariableSet
+// @Router /variable-sets/{variableset} [get]
+func (api *API) variableSet(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	set, ok := api.fetchVariableSet(rw, r)
+	if !ok {
+		return
+	}
+
+	resp, err := convertVariableSet(set)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Create variable set
+// @ID create-variable-set
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param request body codersdk.CreateVariableSetRequest true "Create variable set request"
+// @Success 201 {object} codersdk.VariableSet
+// @Router /variable-sets [post]
+func (api *API) postVariableSet(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.VariableSet](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateVariableSetRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	vars := make([]database.VariableSetVariable, 0, len(req.Variables))
+	for _, v := range req.Variables {
+		vars = append(vars, database.VariableSetVariable(v))
+	}
+	if validErrs := validateVariableSetVariables(vars); len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid variable set.",
+			Validations: validErrs,
+		})
+		return
+	}
+	sortVariableSetVariables(vars)
+	variables, err := database.FormatVariableSetVariables(vars)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	now := dbtime.Now()
+	set, err := api.Database.InsertVariableSet(ctx, database.InsertVariableSetParams{
+		ID:          uuid.New(),
+		Name:        req.Name,
+		Description: req.Description,
+		Variables:   variables,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err, database.UniqueVariableSetsNameKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "A variable set with this name already exists.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating variable set.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = set
+
+	resp, err := convertVariableSet(set)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, resp)
+}
+
+// @Summary Update variable set
+// @ID update-variable-set
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param variableset path string true "Variable set name"
+// @Param request body codersdk.UpdateVariableSetRequest true "Update variable set request"
+// @Success 200 {object} codersdk.VariableSet
+// @Router /variable-sets/{variableset} [patch]
+func (api *API) patchVariableSet(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.VariableSet](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.UpdateVariableSetRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	old, ok := api.fetchVariableSet(rw, r)
+	if !ok {
+		return
+	}
+	aReq.Old = old
+
+	vars, err := database.ParseVariableSetVariables(old.Variables)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	updates := make([]database.VariableSetVariable, 0, len(req.Variables))
+	for _, v := range req.Variables {
+		updates = append(updates, database.VariableSetVariable(v))
+	}
+	validErrs := validateVariableSetVariables(updates)
+	for _, update := range updates {
+		idx := slices.IndexFunc(vars, func(v database.VariableSetVariable) bool {
+			return v.Name == update.Name
+		})
+		if idx >= 0 {
+			vars[idx] = update
+		} else {
+			vars = append(vars, update)
+		}
+	}
+	for _, name := range req.RemoveVariables {
+		idx := slices.IndexFunc(vars, func(v database.VariableSetVariable) bool {
+			return v.Name == name
+		})
+		if idx < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "remove_variables",
+				Detail: fmt.Sprintf("Variable %q is not in the set.", name),
+			})
+			continue
+		}
+		vars = slices.Delete(vars, idx, idx+1)
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid variable set.",
+			Validations: validErrs,
+		})
+		return
+	}
+	sortVariableSetVariables(vars)
+	variables, err := database.FormatVariableSetVariables(vars)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	params := database.UpdateVariableSetParams{
+		ID:             old.ID,
+		Description:    old.Description,
+		Variables:      variables,
+		VariablesKeyID: old.VariablesKeyID,
+		UpdatedAt:      dbtime.Now(),
+	}
+	if req.Description != nil {
+		params.Description = *req.Description
+	}
+
+	set, err := api.Database.UpdateVariableSet(ctx, params)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating variable set.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = set
+
+	resp, err := convertVariableSet(set)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Delete variable set
+// @ID delete-variable-set
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param variableset path string true "Variable set name"
+// @Success 204
+// @Router /variable-sets/{variableset} [delete]
+func (api *API) deleteVariableSet(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.VariableSet](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	set, ok := api.fetchVariableSet(rw, r)
+	if !ok {
+		return
+	}
+	aReq.Old = set
+
+	err := api.Database.DeleteVariableSet(ctx, set.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting variable set.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get template variable sets
+// @ID get-template-variable-sets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.VariableSet
+// @Router /templates/{template}/variable-sets [get]
+func (api *API) templateVariableSets(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	sets, err := api.Database.GetVariableSetsByTemplateID(ctx, template.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template variable sets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp, err := convertVariableSets(sets)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// putTemplateVariableSet attaches a variable set to a template. Attaching a
+// set requires permission to read the set as well as to update the template,
+// since the template can use the values of the set.
+//
+// @Summary Attach variable set to template
+// @ID attach-variable-set-to-template
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param variableset path string true "Variable set name"
+// @Success 204
+// @Router /templates/{template}/variable-sets/{variableset} [put]
+func (api *API) putTemplateVariableSet(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	set, ok := api.fetchVariableSet(rw, r)
+	if !ok {
+		return
+	}
+
+	err := api.Database.InsertTemplateVariableSet(ctx, database.InsertTemplateVariableSetParams{
+		TemplateID:    template.ID,
+		VariableSetID: set.ID,
+		CreatedAt:     dbtime.Now(),
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error attaching variable set.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Detach variable set from template
+// @ID detach-variable-set-from-template
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param variableset path string true "Variable set name"
+// @Success 204
+// @Router /templates/{template}/variable-sets/{variableset} [delete]
+func (api *API) deleteTemplateVariableSet(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+	name := chi.URLParam(r, "variableset")
+
+	sets, err := api.Database.GetVariableSetsByTemplateID(ctx, template.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template variable sets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	idx := slices.IndexFunc(sets, func(set database.VariableSet) bool {
+		return set.Name == name
+	})
+	if idx < 0 {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	err = api.Database.DeleteTemplateVariableSet(ctx, database.DeleteTemplateVariableSetParams{
+		TemplateID:    template.ID,
+		VariableSetID: sets[idx].ID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error detaching variable set.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// fetchVariableSet fetches the variable set named in the URL, writing an
+// error response if it can't be fetched.
+func (api *API) fetchVariableSet(rw http.ResponseWriter, r *http.Request) (database.VariableSet, bool) {
+	ctx := r.Context()
+	set, err := api.Database.GetVariableSetByName(ctx, chi.URLParam(r, "variableset"))
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return database.VariableSet{}, false
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return database.VariableSet{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching variable set.",
+			Detail:  err.Error(),
+		})
+		return database.VariableSet{}, false
+	}
+	return set, true
+}
+
+// validateVariableSetVariables validates the names of variables. Variable
+// names must be unique and are restricted like secret names, since they are
+// Terraform variable names.
+func validateVariableSetVariables(vars []database.VariableSetVariable) []codersdk.ValidationError {
+	var validErrs []codersdk.ValidationError
+	seen := make(map[string]struct{}, len(vars))
+	for _, v := range vars {
+		if err := codersdk.SecretNameValid(v.Name); err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "variables",
+				Detail: fmt.Sprintf("Variable %q: %s", v.Name, err.Error()),
+			})
+			continue
+		}
+		if _, ok := seen[v.Name]; ok {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "variables",
+				Detail: fmt.Sprintf("Variable %q is specified more than once.", v.Name),
+			})
+		}
+		seen[v.Name] = struct{}{}
+	}
+	return validErrs
+}
+
+func sortVariableSetVariables(vars []database.VariableSetVariable) {
+	slices.SortFunc(vars, func(a, b database.VariableSetVariable) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+func convertVariableSets(sets []database.VariableSet) ([]codersdk.VariableSet, error) {
+	converted := make([]codersdk.VariableSet, 0, len(sets))
+	for _, set := range sets {
+		c, err := convertVariableSet(set)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, c)
+	}
+	return converted, nil
+}
+
+// convertVariableSet converts a variable set for the API. The values of
+// secret variables are omitted.
+func convertVariableSet(set database.VariableSet) (codersdk.VariableSet, error) {
+	vars, err := database.ParseVariableSetVariables(set.Variables)
+	if err != nil {
+		return codersdk.VariableSet{}, err
+	}
+	converted := codersdk.VariableSet{
+		ID:          set.ID,
+		Name:        set.Name,
+		Description: set.Description,
+		Variables:   make([]codersdk.VariableSetVariable, 0, len(vars)),
+		CreatedAt:   set.CreatedAt,
+		UpdatedAt:   set.UpdatedAt,
+	}
+	for _, v := range vars {
+		if v.Secret {
+			v.Value = ""
+		}
+		converted.Variables = append(converted.Variables, codersdk.VariableSetVariable(v))
+	}
+	return converted, nil
+}
@@ -0,0 +1,124 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestVariableSets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		set, err := client.CreateVariableSet(ctx, codersdk.CreateVariableSetRequest{
+			Name: "aws-network",
+			Variables: []codersdk.VariableSetVariable{
+				{Name: "vpc_id", Value: "vpc-123"},
+				{Name: "api_token", Value: "hunter2", Secret: true},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.VariableSetVariable{
+			{Name: "api_token", Secret: true},
+			{Name: "vpc_id", Value: "vpc-123"},
+		}, set.Variables)
+
+		_, err = client.CreateVariableSet(ctx, codersdk.CreateVariableSetRequest{Name: "aws-network"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		updated, err := client.UpdateVariableSet(ctx, set.Name, codersdk.UpdateVariableSetRequest{
+			Description:     ptr.Ref("Shared VPC"),
+			Variables:       []codersdk.VariableSetVariable{{Name: "subnet_id", Value: "subnet-456"}},
+			RemoveVariables: []string{"api_token"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Shared VPC", updated.Description)
+		require.Equal(t, []codersdk.VariableSetVariable{
+			{Name: "subnet_id", Value: "subnet-456"},
+			{Name: "vpc_id", Value: "vpc-123"},
+		}, updated.Variables)
+
+		_, err = client.UpdateVariableSet(ctx, set.Name, codersdk.UpdateVariableSetRequest{
+			Variables: []codersdk.VariableSetVariable{{Name: "not-an-identifier", Value: "x"}},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		sets, err := client.VariableSets(ctx)
+		require.NoError(t, err)
+		require.Len(t, sets, 1)
+
+		err = client.DeleteVariableSet(ctx, set.Name)
+		require.NoError(t, err)
+		sets, err = client.VariableSets(ctx)
+		require.NoError(t, err)
+		require.Empty(t, sets)
+
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionWrite,
+			ResourceType: database.ResourceTypeVariableSet,
+			ResourceID:   set.ID,
+		}))
+	})
+
+	t.Run("AttachToTemplate", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		set, err := client.CreateVariableSet(ctx, codersdk.CreateVariableSetRequest{
+			Name:      "aws-network",
+			Variables: []codersdk.VariableSetVariable{{Name: "vpc_id", Value: "vpc-123"}},
+		})
+		require.NoError(t, err)
+
+		// Template admins can't attach sets they can't read.
+		err = templateAdmin.AttachTemplateVariableSet(ctx, template.ID, set.Name)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		err = client.AttachTemplateVariableSet(ctx, template.ID, set.Name)
+		require.NoError(t, err)
+		// Attaching is idempotent.
+		err = client.AttachTemplateVariableSet(ctx, template.ID, set.Name)
+		require.NoError(t, err)
+
+		sets, err := templateAdmin.TemplateVariableSets(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, sets, 1)
+		require.Equal(t, set.ID, sets[0].ID)
+
+		err = templateAdmin.DetachTemplateVariableSet(ctx, template.ID, set.Name)
+		require.NoError(t, err)
+		sets, err = client.TemplateVariableSets(ctx, template.ID)
+		require.NoError(t, err)
+		require.Empty(t, sets)
+
+		err = client.DetachTemplateVariableSet(ctx, template.ID, set.Name)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,70 @@
+package coderd
+
+import (
+	"net/http"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/vault"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// workspaceAgentVaultToken returns a Vault token scoped to the workspace
+// owner. Tokens are shared by all workspaces of an owner and are renewed by
+// coderd while they keep being requested, so agents should request a token
+// again before the current one expires.
+//
+// @Summary Get workspace agent Vault token
+// @ID get-workspace-agent-vault-token
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Success 200 {object} agentsdk.VaultToken
+// @Router /workspaceagents/me/vault-token [get]
+func (api *API) workspaceAgentVaultToken(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	if api.VaultIssuer == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "The Vault integration is not configured.",
+		})
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	token, err := api.VaultIssuer.Token(ctx, vault.Owner{
+		ID:       workspace.OwnerID,
+		Username: workspace.OwnerUsername,
+	})
+	if err != nil {
+		api.Logger.Warn(ctx, "issue vault token",
+			slog.F("workspace_id", workspace.ID),
+			slog.F("owner_id", workspace.OwnerID),
+			slog.Error(err),
+		)
+		httpapi.Write(ctx, rw, http.StatusBadGateway, codersdk.Response{
+			Message: "Failed to issue a Vault token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.VaultToken{
+		Address:   api.VaultIssuer.Address(),
+		Namespace: api.VaultIssuer.Namespace(),
+		Token:     token.Token,
+		ExpiresAt: token.ExpiresAt,
+	})
+}
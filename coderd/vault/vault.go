@@ -0,0 +1,449 @@
+// Package vault issues short-lived HashiCorp Vault tokens to workspaces.
+// coderd authenticates to Vault with its own identity and creates a token for
+// each workspace owner from a token role, so templates do not need to embed
+// static Vault tokens.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"tailscale.com/util/singleflight"
+
+	"cdr.dev/slog"
+	"github.com/coder/quartz"
+)
+
+// AuthMethod is the method coderd uses to authenticate to Vault.
+type AuthMethod string
+
+const (
+	// AuthMethodToken uses a static Vault token. It is intended for
+	// development only.
+	AuthMethodToken AuthMethod = "token"
+	// AuthMethodAppRole logs in with an AppRole role ID and secret ID.
+	AuthMethodAppRole AuthMethod = "approle"
+	// AuthMethodKubernetes logs in with the Kubernetes service account token
+	// of the coderd pod.
+	AuthMethodKubernetes AuthMethod = "kubernetes"
+)
+
+// DefaultKubernetesTokenPath is where Kubernetes mounts the service account
+// token of a pod.
+const DefaultKubernetesTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+type Options struct {
+	Logger     slog.Logger
+	Clock      quartz.Clock
+	HTTPClient *http.Client
+
+	// Address is the base URL of the Vault server.
+	Address string
+	// Namespace is the Vault Enterprise namespace requests are sent to.
+	Namespace string
+
+	AuthMethod AuthMethod
+	// AuthMountPath is the path the auth method is mounted at. It defaults
+	// to the name of the auth method.
+	AuthMountPath string
+	// Token is used by AuthMethodToken.
+	Token string
+	// AppRoleRoleID and AppRoleSecretID are used by AuthMethodAppRole.
+	AppRoleRoleID   string
+	AppRoleSecretID string
+	// KubernetesRole and KubernetesTokenPath are used by
+	// AuthMethodKubernetes.
+	KubernetesRole      string
+	KubernetesTokenPath string
+
+	// TokenRole is the token role owner tokens are created from. The role
+	// must allow every policy rendered from Policies.
+	TokenRole string
+	// Policies are text/template strings rendered with the Owner of the
+	// token, e.g. "coder-{{.Username}}".
+	Policies []string
+	// TTL is the lifetime of owner tokens.
+	TTL time.Duration
+}
+
+// Owner is the workspace owner a token is issued for.
+type Owner struct {
+	ID       uuid.UUID
+	Username string
+}
+
+// Token is a Vault token issued for a workspace owner.
+type Token struct {
+	Token     string
+	Accessor  string
+	Renewable bool
+	ExpiresAt time.Time
+}
+
+// Issuer creates and renews Vault tokens for workspace owners. Tokens are
+// cached per owner and renewed once a third of their lifetime remains, so
+// every workspace of an owner shares the same token.
+type Issuer struct {
+	opts     Options
+	logger   slog.Logger
+	clock    quartz.Clock
+	client   *http.Client
+	policies []*template.Template
+
+	// refreshes and logins deduplicate concurrent Vault requests, so an owner
+	// gets a single token without serializing requests of other owners.
+	refreshes singleflight.Group[uuid.UUID, Token]
+	logins    singleflight.Group[string, Token]
+
+	// mu guards the fields below. It is never held during Vault requests.
+	mu        sync.Mutex
+	auth      Token
+	tokens    map[uuid.UUID]Token
+	lastEvict time.Time
+}
+
+func New(opts Options) (*Issuer, error) {
+	if opts.Address == "" {
+		return nil, xerrors.New("vault address is required")
+	}
+	if opts.TokenRole == "" {
+		return nil, xerrors.New("vault token role is required")
+	}
+	if opts.TTL <= 0 {
+		return nil, xerrors.New("vault token ttl must be positive")
+	}
+	switch opts.AuthMethod {
+	case AuthMethodToken:
+		if opts.Token == "" {
+			return nil, xerrors.New("vault token is required for the token auth method")
+		}
+	case AuthMethodAppRole:
+		if opts.AppRoleRoleID == "" || opts.AppRoleSecretID == "" {
+			return nil, xerrors.New("vault approle role id and secret id are required for the approle auth method")
+		}
+	case AuthMethodKubernetes:
+		if opts.KubernetesRole == "" {
+			return nil, xerrors.New("vault kubernetes role is required for the kubernetes auth method")
+		}
+		if opts.KubernetesTokenPath == "" {
+			opts.KubernetesTokenPath = DefaultKubernetesTokenPath
+		}
+	default:
+		return nil, xerrors.Errorf("unknown vault auth method %q", opts.AuthMethod)
+	}
+	if opts.AuthMountPath == "" {
+		opts.AuthMountPath = string(opts.AuthMethod)
+	}
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	policies := make([]*template.Template, 0, len(opts.Policies))
+	for _, p := range opts.Policies {
+		tmpl, err := template.New("policy").Option("missingkey=error").Parse(p)
+		if err != nil {
+			return nil, xerrors.Errorf("parse vault policy %q: %w", p, err)
+		}
+		policies = append(policies, tmpl)
+	}
+
+	return &Issuer{
+		opts:     opts,
+		logger:   opts.Logger,
+		clock:    opts.Clock,
+		client:   opts.HTTPClient,
+		policies: policies,
+		tokens:   make(map[uuid.UUID]Token),
+	}, nil
+}
+
+// Address returns the base URL of the Vault server.
+func (i *Issuer) Address() string {
+	return i.opts.Address
+}
+
+// Namespace returns the Vault Enterprise namespace, if any.
+func (i *Issuer) Namespace() string {
+	return i.opts.Namespace
+}
+
+// Token returns a Vault token for the owner. A cached token is returned while
+// it has more than a third of its lifetime left, after which it is renewed.
+// A new token is created when the owner has none, or when renewal fails or
+// no longer extends its lifetime.
+func (i *Issuer) Token(ctx context.Context, owner Owner) (Token, error) {
+	now := i.clock.Now()
+	i.mu.Lock()
+	i.evictExpired(now)
+	tok, ok := i.tokens[owner.ID]
+	i.mu.Unlock()
+	if ok && now.Before(tok.ExpiresAt) && !i.needsRefresh(tok, now) {
+		return tok, nil
+	}
+
+	// Concurrent requests for the same owner share one refresh. It is not
+	// tied to the context of the first caller, so canceling that request
+	// doesn't fail the others.
+	resChan := i.refreshes.DoChan(owner.ID, func() (Token, error) {
+		return i.refresh(context.WithoutCancel(ctx), owner)
+	})
+	select {
+	case <-ctx.Done():
+		return Token{}, ctx.Err()
+	case res := <-resChan:
+		return res.Val, res.Err
+	}
+}
+
+// refresh renews the cached token of the owner, or creates a new one.
+func (i *Issuer) refresh(ctx context.Context, owner Owner) (Token, error) {
+	now := i.clock.Now()
+	i.mu.Lock()
+	tok, ok := i.tokens[owner.ID]
+	i.mu.Unlock()
+
+	if ok && now.Before(tok.ExpiresAt) {
+		if !i.needsRefresh(tok, now) {
+			return tok, nil
+		}
+		if tok.Renewable {
+			renewed, err := i.renew(ctx, tok)
+			if err == nil && !i.needsRefresh(renewed, now) {
+				i.store(owner.ID, renewed)
+				return renewed, nil
+			}
+			if err != nil {
+				i.logger.Debug(ctx, "renew vault token", slog.F("owner_id", owner.ID), slog.Error(err))
+			}
+		}
+	}
+
+	tok, err := i.create(ctx, owner)
+	if err != nil {
+		i.mu.Lock()
+		delete(i.tokens, owner.ID)
+		i.mu.Unlock()
+		return Token{}, err
+	}
+	i.store(owner.ID, tok)
+	return tok, nil
+}
+
+func (i *Issuer) store(ownerID uuid.UUID, tok Token) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.tokens[ownerID] = tok
+}
+
+// evictExpired removes expired tokens from the cache, so owners that stopped
+// using their workspaces don't accumulate. The cache is swept at most every
+// third of the token lifetime. i.mu must be held.
+func (i *Issuer) evictExpired(now time.Time) {
+	if now.Sub(i.lastEvict) < i.opts.TTL/3 {
+		return
+	}
+	i.lastEvict = now
+	for id, tok := range i.tokens {
+		if !now.Before(tok.ExpiresAt) {
+			delete(i.tokens, id)
+		}
+	}
+}
+
+// needsRefresh reports whether less than a third of the token lifetime is
+// left.
+func (i *Issuer) needsRefresh(tok Token, now time.Time) bool {
+	return tok.ExpiresAt.Sub(now) < i.opts.TTL/3
+}
+
+// authToken returns the token coderd uses to talk to Vault, logging in again
+// when the current token is close to expiring.
+func (i *Issuer) authToken(ctx context.Context) (string, error) {
+	if i.opts.AuthMethod == AuthMethodToken {
+		return i.opts.Token, nil
+	}
+	i.mu.Lock()
+	auth := i.auth
+	i.mu.Unlock()
+	if i.authValid(auth, i.clock.Now()) {
+		return auth.Token, nil
+	}
+
+	auth, err, _ := i.logins.Do("", func() (Token, error) {
+		return i.login(ctx)
+	})
+	if err != nil {
+		return "", err
+	}
+	return auth.Token, nil
+}
+
+func (*Issuer) authValid(auth Token, now time.Time) bool {
+	return auth.Token != "" && (auth.ExpiresAt.IsZero() || auth.ExpiresAt.Sub(now) > time.Minute)
+}
+
+func (i *Issuer) login(ctx context.Context) (Token, error) {
+	now := i.clock.Now()
+	// Another login may have finished since the caller checked.
+	i.mu.Lock()
+	auth := i.auth
+	i.mu.Unlock()
+	if i.authValid(auth, now) {
+		return auth, nil
+	}
+
+	var body map[string]string
+	switch i.opts.AuthMethod {
+	case AuthMethodAppRole:
+		body = map[string]string{
+			"role_id":   i.opts.AppRoleRoleID,
+			"secret_id": i.opts.AppRoleSecretID,
+		}
+	case AuthMethodKubernetes:
+		// The service account token is rotated by the kubelet, so it is read
+		// on every login.
+		jwt, err := os.ReadFile(i.opts.KubernetesTokenPath)
+		if err != nil {
+			return Token{}, xerrors.Errorf("read kubernetes service account token: %w", err)
+		}
+		body = map[string]string{
+			"role": i.opts.KubernetesRole,
+			"jwt":  strings.TrimSpace(string(jwt)),
+		}
+	}
+
+	res, err := i.do(ctx, "", http.MethodPost, fmt.Sprintf("/v1/auth/%s/login", strings.Trim(i.opts.AuthMountPath, "/")), body)
+	if err != nil {
+		return Token{}, xerrors.Errorf("login to vault: %w", err)
+	}
+	auth = res.token(now)
+	i.mu.Lock()
+	i.auth = auth
+	i.mu.Unlock()
+	return auth, nil
+}
+
+func (i *Issuer) create(ctx context.Context, owner Owner) (Token, error) {
+	policies := make([]string, 0, len(i.policies))
+	for _, tmpl := range i.policies {
+		var buf bytes.Buffer
+		err := tmpl.Execute(&buf, owner)
+		if err != nil {
+			return Token{}, xerrors.Errorf("render vault policy: %w", err)
+		}
+		policies = append(policies, buf.String())
+	}
+
+	authToken, err := i.authToken(ctx)
+	if err != nil {
+		return Token{}, err
+	}
+	auth, err := i.do(ctx, authToken, http.MethodPost, "/v1/auth/token/create/"+i.opts.TokenRole, map[string]any{
+		"policies":     policies,
+		"ttl":          i.opts.TTL.String(),
+		"renewable":    true,
+		"display_name": "coder-" + owner.Username,
+		"meta": map[string]string{
+			"coder_user_id":  owner.ID.String(),
+			"coder_username": owner.Username,
+		},
+	})
+	if err != nil {
+		return Token{}, xerrors.Errorf("create vault token: %w", err)
+	}
+	return auth.token(i.clock.Now()), nil
+}
+
+func (i *Issuer) renew(ctx context.Context, tok Token) (Token, error) {
+	auth, err := i.do(ctx, tok.Token, http.MethodPost, "/v1/auth/token/renew-self", map[string]string{
+		"increment": i.opts.TTL.String(),
+	})
+	if err != nil {
+		return Token{}, xerrors.Errorf("renew vault token: %w", err)
+	}
+	renewed := auth.token(i.clock.Now())
+	if renewed.Token == "" {
+		renewed.Token = tok.Token
+	}
+	return renewed, nil
+}
+
+type authResponse struct {
+	ClientToken   string `json:"client_token"`
+	Accessor      string `json:"accessor"`
+	LeaseDuration int64  `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+func (a authResponse) token(now time.Time) Token {
+	tok := Token{
+		Token:     a.ClientToken,
+		Accessor:  a.Accessor,
+		Renewable: a.Renewable,
+	}
+	// A lease duration of zero means the token never expires.
+	if a.LeaseDuration > 0 {
+		tok.ExpiresAt = now.Add(time.Duration(a.LeaseDuration) * time.Second)
+	}
+	return tok
+}
+
+func (i *Issuer) do(ctx context.Context, token, method, path string, body any) (authResponse, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return authResponse{}, xerrors.Errorf("marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(i.opts.Address, "/")+path, bytes.NewReader(data))
+	if err != nil {
+		return authResponse{}, xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if i.opts.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", i.opts.Namespace)
+	}
+
+	res, err := i.client.Do(req)
+	if err != nil {
+		return authResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		raw, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+		if json.Unmarshal(raw, &vaultErr) == nil && len(vaultErr.Errors) > 0 {
+			return authResponse{}, xerrors.Errorf("unexpected status code %d: %s", res.StatusCode, strings.Join(vaultErr.Errors, "; "))
+		}
+		return authResponse{}, xerrors.Errorf("unexpected status code %d: %s", res.StatusCode, bytes.TrimSpace(raw))
+	}
+
+	var resp struct {
+		Auth *authResponse `json:"auth"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&resp)
+	if err != nil {
+		return authResponse{}, xerrors.Errorf("decode response: %w", err)
+	}
+	if resp.Auth == nil {
+		return authResponse{}, xerrors.New("response has no auth data")
+	}
+	return *resp.Auth, nil
+}
@@ -0,0 +1,38 @@
+package vault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/quartz"
+)
+
+func TestEvictExpired(t *testing.T) {
+	t.Parallel()
+
+	mClock := quartz.NewMock(t)
+	i := &Issuer{
+		opts:   Options{TTL: time.Hour},
+		clock:  mClock,
+		tokens: make(map[uuid.UUID]Token),
+	}
+	expiring, live := uuid.New(), uuid.New()
+	i.tokens[expiring] = Token{Token: "expiring", ExpiresAt: mClock.Now().Add(10 * time.Minute)}
+	i.tokens[live] = Token{Token: "live", ExpiresAt: mClock.Now().Add(time.Hour)}
+
+	i.evictExpired(mClock.Now())
+	require.Len(t, i.tokens, 2)
+
+	// Sweeps are throttled to every third of the token lifetime.
+	mClock.Advance(15 * time.Minute)
+	i.evictExpired(mClock.Now())
+	require.Len(t, i.tokens, 2)
+
+	mClock.Advance(10 * time.Minute)
+	i.evictExpired(mClock.Now())
+	require.Len(t, i.tokens, 1)
+	require.Contains(t, i.tokens, live)
+}
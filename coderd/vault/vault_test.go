@@ -0,0 +1,277 @@
+package vault_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/quartz"
+
+	"github.com/coder/coder/v2/coderd/vault"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestIssuer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("AppRole", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		mClock := quartz.NewMock(t)
+		srv := newFakeVault(t)
+
+		issuer, err := vault.New(vault.Options{
+			Logger:          slogtest.Make(t, nil),
+			Clock:           mClock,
+			Address:         srv.URL,
+			Namespace:       "engineering",
+			AuthMethod:      vault.AuthMethodAppRole,
+			AppRoleRoleID:   "role-id",
+			AppRoleSecretID: "secret-id",
+			TokenRole:       "coder",
+			Policies:        []string{"coder-{{.Username}}", "shared"},
+			TTL:             time.Hour,
+		})
+		require.NoError(t, err)
+
+		alice := vault.Owner{ID: uuid.New(), Username: "alice"}
+		tok, err := issuer.Token(ctx, alice)
+		require.NoError(t, err)
+		require.Equal(t, "owner-1", tok.Token)
+		require.Equal(t, mClock.Now().Add(time.Hour), tok.ExpiresAt)
+
+		srv.mu.Lock()
+		require.Equal(t, []string{"coder-alice", "shared"}, srv.created[0].Policies)
+		require.Equal(t, "alice", srv.created[0].Meta["coder_username"])
+		require.Equal(t, []string{"engineering"}, srv.namespaces)
+		srv.mu.Unlock()
+
+		// The cached token is reused while it has most of its lifetime left.
+		mClock.Advance(30 * time.Minute)
+		again, err := issuer.Token(ctx, alice)
+		require.NoError(t, err)
+		require.Equal(t, tok, again)
+
+		// Past two thirds of its lifetime the token is renewed.
+		mClock.Advance(15 * time.Minute)
+		renewed, err := issuer.Token(ctx, alice)
+		require.NoError(t, err)
+		require.Equal(t, "owner-1", renewed.Token)
+		require.Equal(t, mClock.Now().Add(time.Hour), renewed.ExpiresAt)
+
+		// Other owners get their own token.
+		bob, err := issuer.Token(ctx, vault.Owner{ID: uuid.New(), Username: "bob"})
+		require.NoError(t, err)
+		require.Equal(t, "owner-2", bob.Token)
+
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		require.Equal(t, 1, srv.logins)
+		require.Equal(t, 1, srv.renewals)
+		require.Len(t, srv.created, 2)
+	})
+
+	t.Run("RenewalExhausted", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		mClock := quartz.NewMock(t)
+		srv := newFakeVault(t)
+		// Vault caps renewals at the max TTL of the token.
+		srv.renewLease = 60
+
+		issuer, err := vault.New(vault.Options{
+			Logger:     slogtest.Make(t, nil),
+			Clock:      mClock,
+			Address:    srv.URL,
+			AuthMethod: vault.AuthMethodToken,
+			Token:      "root",
+			TokenRole:  "coder",
+			TTL:        time.Hour,
+		})
+		require.NoError(t, err)
+
+		owner := vault.Owner{ID: uuid.New(), Username: "alice"}
+		_, err = issuer.Token(ctx, owner)
+		require.NoError(t, err)
+
+		mClock.Advance(50 * time.Minute)
+		tok, err := issuer.Token(ctx, owner)
+		require.NoError(t, err)
+		require.Equal(t, "owner-2", tok.Token)
+
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		require.Zero(t, srv.logins)
+		require.Equal(t, 1, srv.renewals)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		srv := newFakeVault(t)
+		aliceGate := make(chan struct{})
+		srv.createGates = map[string]chan struct{}{"alice": aliceGate}
+
+		issuer, err := vault.New(vault.Options{
+			Logger:     slogtest.Make(t, nil),
+			Address:    srv.URL,
+			AuthMethod: vault.AuthMethodToken,
+			Token:      "root",
+			TokenRole:  "coder",
+			TTL:        time.Hour,
+		})
+		require.NoError(t, err)
+
+		alice := vault.Owner{ID: uuid.New(), Username: "alice"}
+		var wg sync.WaitGroup
+		tokens := make([]vault.Token, 5)
+		for n := range tokens {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tok, err := issuer.Token(ctx, alice)
+				assert.NoError(t, err)
+				tokens[n] = tok
+			}()
+		}
+
+		// A slow request for one owner doesn't hold up other owners.
+		bob, err := issuer.Token(ctx, vault.Owner{ID: uuid.New(), Username: "bob"})
+		require.NoError(t, err)
+		require.Equal(t, "owner-1", bob.Token)
+
+		close(aliceGate)
+		wg.Wait()
+		for _, tok := range tokens {
+			require.Equal(t, "owner-2", tok.Token)
+		}
+
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		require.Len(t, srv.created, 2)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		srv := newFakeVault(t)
+		srv.createErr = "permission denied"
+
+		issuer, err := vault.New(vault.Options{
+			Logger:     slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}),
+			Address:    srv.URL,
+			AuthMethod: vault.AuthMethodToken,
+			Token:      "root",
+			TokenRole:  "coder",
+			TTL:        time.Hour,
+		})
+		require.NoError(t, err)
+
+		_, err = issuer.Token(ctx, vault.Owner{ID: uuid.New(), Username: "alice"})
+		require.ErrorContains(t, err, "permission denied")
+	})
+
+	t.Run("InvalidOptions", func(t *testing.T) {
+		t.Parallel()
+		_, err := vault.New(vault.Options{
+			Address:    "https://vault.example.com",
+			AuthMethod: vault.AuthMethodKubernetes,
+			TokenRole:  "coder",
+			TTL:        time.Hour,
+		})
+		require.ErrorContains(t, err, "kubernetes role is required")
+
+		_, err = vault.New(vault.Options{
+			Address:    "https://vault.example.com",
+			AuthMethod: vault.AuthMethodToken,
+			Token:      "root",
+			TokenRole:  "coder",
+			Policies:   []string{"coder-{{.Username"},
+			TTL:        time.Hour,
+		})
+		require.ErrorContains(t, err, "parse vault policy")
+	})
+}
+
+type createRequest struct {
+	Policies []string          `json:"policies"`
+	Meta     map[string]string `json:"meta"`
+}
+
+type fakeVault struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	logins     int
+	renewals   int
+	created    []createRequest
+	namespaces []string
+	createErr  string
+	renewLease int
+	// createGates block token creation for an owner, by username, until
+	// the channel is closed.
+	createGates map[string]chan struct{}
+}
+
+func newFakeVault(t *testing.T) *fakeVault {
+	t.Helper()
+	f := &fakeVault{renewLease: 3600}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/auth/approle/login", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "role-id", body["role_id"])
+		assert.Equal(t, "secret-id", body["secret_id"])
+		f.logins++
+		writeAuth(w, "coderd", 7200)
+	})
+	mux.HandleFunc("POST /v1/auth/token/create/coder", func(w http.ResponseWriter, r *http.Request) {
+		var body createRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if gate, ok := f.createGates[body.Meta["coder_username"]]; ok {
+			<-gate
+		}
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.namespaces = append(f.namespaces, r.Header.Get("X-Vault-Namespace"))
+		if f.createErr != "" {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string][]string{"errors": {f.createErr}})
+			return
+		}
+		f.created = append(f.created, body)
+		writeAuth(w, fmt.Sprintf("owner-%d", len(f.created)), 3600)
+	})
+	mux.HandleFunc("POST /v1/auth/token/renew-self", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.renewals++
+		writeAuth(w, r.Header.Get("X-Vault-Token"), f.renewLease)
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func writeAuth(w http.ResponseWriter, token string, lease int) {
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"auth": map[string]any{
+			"client_token":   token,
+			"accessor":       "accessor-" + token,
+			"lease_duration": lease,
+			"renewable":      true,
+		},
+	})
+}
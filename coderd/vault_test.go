@@ -0,0 +1,96 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/vault"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAgentVaultToken(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T, issuer *vault.Issuer) *agentsdk.Client {
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			VaultIssuer:              issuer,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.PlanComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		return agentClient
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/auth/token/create/coder", r.URL.Path)
+			assert.Equal(t, "root", r.Header.Get("X-Vault-Token"))
+			var body struct {
+				Policies []string `json:"policies"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, []string{"coder-testuser"}, body.Policies)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"auth": map[string]any{
+					"client_token":   "hvs.owner",
+					"lease_duration": 3600,
+					"renewable":      true,
+				},
+			})
+		}))
+		t.Cleanup(srv.Close)
+
+		issuer, err := vault.New(vault.Options{
+			Address:    srv.URL,
+			AuthMethod: vault.AuthMethodToken,
+			Token:      "root",
+			TokenRole:  "coder",
+			Policies:   []string{"coder-{{.Username}}"},
+			TTL:        time.Hour,
+		})
+		require.NoError(t, err)
+		agentClient := setup(t, issuer)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		token, err := agentClient.VaultToken(ctx)
+		require.NoError(t, err)
+		require.Equal(t, srv.URL, token.Address)
+		require.Equal(t, "hvs.owner", token.Token)
+		require.False(t, token.ExpiresAt.IsZero())
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		t.Parallel()
+		agentClient := setup(t, nil)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := agentClient.VaultToken(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,102 @@
+// Package versionpolicy enforces the minimum versions of the workspace agents
+// and CLIs that connect to coderd.
+package versionpolicy
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/buildinfo"
+)
+
+// Policy holds the minimum versions of a deployment. Empty minimums are not
+// enforced.
+type Policy struct {
+	MinAgentVersion string
+	MinCLIVersion   string
+}
+
+// New validates the minimum versions of a deployment. Versions may omit the
+// leading "v".
+func New(minAgentVersion, minCLIVersion string) (Policy, error) {
+	var p Policy
+	for _, v := range []struct {
+		name    string
+		version string
+		dst     *string
+	}{
+		{"agent", minAgentVersion, &p.MinAgentVersion},
+		{"CLI", minCLIVersion, &p.MinCLIVersion},
+	} {
+		if v.version == "" {
+			continue
+		}
+		canonical := canonicalize(v.version)
+		if canonical == "" {
+			return Policy{}, xerrors.Errorf("minimum %s version %q is not a semantic version", v.name, v.version)
+		}
+		*v.dst = canonical
+	}
+	return p, nil
+}
+
+// OutdatedError is returned for clients that are older than the minimum
+// version of their kind.
+type OutdatedError struct {
+	// Client is "agent" or "CLI".
+	Client         string
+	Version        string
+	MinimumVersion string
+}
+
+func (e *OutdatedError) Error() string {
+	return fmt.Sprintf("%s version %s is older than the minimum version %s required by this deployment", e.Client, e.Version, e.MinimumVersion)
+}
+
+// CheckAgent returns an *OutdatedError if the agent version is older than the
+// minimum agent version.
+func (p Policy) CheckAgent(version string) error {
+	if Outdated(version, p.MinAgentVersion) {
+		return &OutdatedError{Client: "agent", Version: version, MinimumVersion: p.MinAgentVersion}
+	}
+	return nil
+}
+
+// CheckCLI returns an *OutdatedError if the CLI version is older than the
+// minimum CLI version.
+func (p Policy) CheckCLI(version string) error {
+	if Outdated(version, p.MinCLIVersion) {
+		return &OutdatedError{Client: "CLI", Version: version, MinimumVersion: p.MinCLIVersion}
+	}
+	return nil
+}
+
+// Outdated reports whether version is older than minimum. Development builds
+// and versions that can't be parsed are never outdated, so they can always
+// connect.
+func Outdated(version, minimum string) bool {
+	if minimum == "" || buildinfo.IsDevVersion(version) {
+		return false
+	}
+	version = canonicalize(version)
+	if version == "" {
+		return false
+	}
+	return semver.Compare(version, minimum) < 0
+}
+
+// Skewed reports whether the major or minor version of a client differs from
+// the server. Development builds are never skewed.
+func Skewed(version, serverVersion string) bool {
+	return !buildinfo.VersionsMatch(canonicalize(version), serverVersion)
+}
+
+func canonicalize(version string) string {
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return semver.Canonical(version)
+}
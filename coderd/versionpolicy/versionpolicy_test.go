@@ -0,0 +1,78 @@
+package versionpolicy_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/versionpolicy"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	p, err := versionpolicy.New("2.20", "v2.21.3")
+	require.NoError(t, err)
+	require.Equal(t, "v2.20.0", p.MinAgentVersion)
+	require.Equal(t, "v2.21.3", p.MinCLIVersion)
+
+	p, err = versionpolicy.New("", "")
+	require.NoError(t, err)
+	require.Empty(t, p.MinAgentVersion)
+	require.Empty(t, p.MinCLIVersion)
+
+	_, err = versionpolicy.New("latest", "")
+	require.ErrorContains(t, err, "minimum agent version")
+	_, err = versionpolicy.New("", "2.x")
+	require.ErrorContains(t, err, "minimum CLI version")
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	p, err := versionpolicy.New("v2.20.0", "v2.21.0")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		Name     string
+		Version  string
+		Outdated bool
+	}{
+		{Name: "Older", Version: "v2.19.5", Outdated: true},
+		{Name: "PreRelease", Version: "v2.20.0-rc.1", Outdated: true},
+		{Name: "Equal", Version: "v2.20.0"},
+		{Name: "Newer", Version: "v2.22.1+abcdef"},
+		{Name: "NoPrefix", Version: "2.19.0", Outdated: true},
+		{Name: "Devel", Version: "v0.0.0-devel+abcdef"},
+		{Name: "Unparsable", Version: "unknown"},
+		{Name: "Empty", Version: ""},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			err := p.CheckAgent(tc.Version)
+			if !tc.Outdated {
+				require.NoError(t, err)
+				return
+			}
+			var outdated *versionpolicy.OutdatedError
+			require.ErrorAs(t, err, &outdated)
+			require.Equal(t, "agent", outdated.Client)
+			require.Equal(t, "v2.20.0", outdated.MinimumVersion)
+		})
+	}
+
+	// The minimum versions are independent.
+	require.NoError(t, p.CheckAgent("v2.20.0"))
+	require.Error(t, p.CheckCLI("v2.20.0"))
+	require.NoError(t, versionpolicy.Policy{}.CheckCLI("v1.0.0"))
+}
+
+func TestSkewed(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, versionpolicy.Skewed("v2.20.3", "v2.20.0"))
+	require.False(t, versionpolicy.Skewed("2.20.3", "v2.20.0"))
+	require.True(t, versionpolicy.Skewed("v2.19.0", "v2.20.0"))
+	require.False(t, versionpolicy.Skewed("v2.19.0", "v0.0.0-devel"))
+}
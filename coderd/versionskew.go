@@ -0,0 +1,165 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/versionpolicy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// cliVersionWriteInterval is how often the CLI version of a user is
+	// written to the database while it doesn't change.
+	cliVersionWriteInterval = time.Hour
+	// cliVersionReportAge is how long a CLI version is reported after the
+	// last request that was made with it.
+	cliVersionReportAge = 30 * 24 * time.Hour
+)
+
+type seenCLIVersion struct {
+	version   string
+	writtenAt time.Time
+}
+
+// checkClientVersion records the version of the CLI a user made a request
+// with, and rejects the request if the CLI is older than the minimum CLI
+// version.
+func (api *API) checkClientVersion(r *http.Request, userID uuid.UUID, version string) *codersdk.Response {
+	api.recordCLIVersion(r, userID, version)
+
+	err := api.VersionPolicy.CheckCLI(version)
+	if err == nil {
+		return nil
+	}
+	upgradeMessage := api.DeploymentValues.CLIUpgradeMessage.Value()
+	if upgradeMessage == "" {
+		upgradeMessage = fmt.Sprintf("Update the CLI with: 'curl -fsSL %s/install.sh | sh'", api.AccessURL.String())
+	}
+	return &codersdk.Response{
+		Message: err.Error() + ".",
+		Detail:  upgradeMessage,
+	}
+}
+
+func (api *API) recordCLIVersion(r *http.Request, userID uuid.UUID, version string) {
+	now := dbtime.Now()
+	api.cliVersionsMu.Lock()
+	if api.cliVersionsSeen == nil {
+		api.cliVersionsSeen = make(map[uuid.UUID]seenCLIVersion)
+	}
+	seen, ok := api.cliVersionsSeen[userID]
+	if ok && seen.version == version && now.Sub(seen.writtenAt) < cliVersionWriteInterval {
+		api.cliVersionsMu.Unlock()
+		return
+	}
+	api.cliVersionsSeen[userID] = seenCLIVersion{version: version, writtenAt: now}
+	api.cliVersionsMu.Unlock()
+
+	//nolint:gocritic // The user is not in the context of the request yet.
+	err := api.Database.UpsertUserCLIVersion(dbauthz.AsSystemRestricted(r.Context()), database.UpsertUserCLIVersionParams{
+		UserID:     userID,
+		Version:    version,
+		LastSeenAt: now,
+	})
+	if err != nil {
+		api.Logger.Warn(r.Context(), "failed to record cli version",
+			slog.F("user_id", userID), slog.F("version", version), slog.Error(err))
+	}
+}
+
+// checkAgentVersion returns the response for agents that are older than the
+// minimum agent version.
+func (api *API) checkAgentVersion(version string) *codersdk.Response {
+	err := api.VersionPolicy.CheckAgent(version)
+	if err == nil {
+		return nil
+	}
+	return &codersdk.Response{
+		Message: err.Error() + ".",
+		Detail:  "Restart the workspace to download the agent from the deployment, or update the agent in the image of the template.",
+	}
+}
+
+// @Summary Get deployment version skew
+// @Description Returns the workspace agents and CLIs whose version differs
+// @Description from the version of the server.
+// @ID get-deployment-version-skew
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.VersionSkewReport
+// @Router /deployment/version-skew [get]
+func (api *API) deploymentVersionSkew(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentConfig) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	agents, err := api.Database.GetWorkspaceAgentVersionsInLatestBuilds(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching agent versions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	clis, err := api.Database.GetUserCLIVersions(ctx, dbtime.Now().Add(-cliVersionReportAge))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching CLI versions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	serverVersion := buildinfo.Version()
+	report := codersdk.VersionSkewReport{
+		ServerVersion:   serverVersion,
+		MinAgentVersion: api.VersionPolicy.MinAgentVersion,
+		MinCLIVersion:   api.VersionPolicy.MinCLIVersion,
+		Agents:          []codersdk.AgentVersionSkew{},
+		CLIs:            []codersdk.CLIVersionSkew{},
+	}
+	for _, agent := range agents {
+		outdated := versionpolicy.Outdated(agent.Version, api.VersionPolicy.MinAgentVersion)
+		if !outdated && !versionpolicy.Skewed(agent.Version, serverVersion) {
+			continue
+		}
+		report.Agents = append(report.Agents, codersdk.AgentVersionSkew{
+			WorkspaceID:    agent.WorkspaceID,
+			WorkspaceName:  agent.WorkspaceName,
+			WorkspaceOwner: agent.OwnerUsername,
+			AgentID:        agent.AgentID,
+			AgentName:      agent.AgentName,
+			Version:        agent.Version,
+			Outdated:       outdated,
+		})
+	}
+	for _, cli := range clis {
+		outdated := versionpolicy.Outdated(cli.Version, api.VersionPolicy.MinCLIVersion)
+		if !outdated && !versionpolicy.Skewed(cli.Version, serverVersion) {
+			continue
+		}
+		report.CLIs = append(report.CLIs, codersdk.CLIVersionSkew{
+			UserID:     cli.UserID,
+			Username:   cli.Username,
+			Version:    cli.Version,
+			LastSeenAt: cli.LastSeenAt,
+			Outdated:   outdated,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, report)
+}
@@ -0,0 +1,76 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestDeploymentVersionSkew(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.MinAgentVersion = "2.20.0"
+	dv.MinCLIVersion = "2.20.0"
+	dv.CLIUpgradeMessage = "Run the installer again."
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+		DeploymentValues: dv,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	withVersion := func(version string) codersdk.RequestOption {
+		return func(r *http.Request) {
+			r.Header.Set(codersdk.BuildVersionHeader, version)
+		}
+	}
+
+	// Outdated CLIs are rejected with the upgrade message.
+	res, err := member.Request(ctx, http.MethodGet, "/api/v2/users/me", nil, withVersion("v2.19.0"))
+	require.NoError(t, err)
+	apiErr := codersdk.ReadBodyAsError(res)
+	_ = res.Body.Close()
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, apiErr, &sdkErr)
+	require.Equal(t, http.StatusUpgradeRequired, sdkErr.StatusCode())
+	require.Contains(t, sdkErr.Message, "older than the minimum version v2.20.0")
+	require.Equal(t, "Run the installer again.", sdkErr.Detail)
+
+	res, err = client.Request(ctx, http.MethodGet, "/api/v2/users/me", nil, withVersion("v2.20.0"))
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	// Outdated agents are rejected before the connection is accepted.
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+	agentClient := codersdk.New(client.URL)
+	agentClient.SetSessionToken(r.AgentToken)
+	res, err = agentClient.Request(ctx, http.MethodGet, "/api/v2/workspaceagents/me/rpc", nil, withVersion("v2.10.0"))
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusUpgradeRequired, res.StatusCode)
+
+	report, err := client.DeploymentVersionSkew(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "v2.20.0", report.MinAgentVersion)
+	require.Equal(t, "v2.20.0", report.MinCLIVersion)
+	require.Len(t, report.CLIs, 1)
+	require.Equal(t, "v2.19.0", report.CLIs[0].Version)
+	require.True(t, report.CLIs[0].Outdated)
+
+	// Only deployment admins can read the report.
+	_, err = member.DeploymentVersionSkew(ctx)
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+}
@@ -0,0 +1,838 @@
+package coderd
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/apikey"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/webauthn"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+const (
+	// webAuthnChallengeLifetime bounds how long a user has to complete a
+	// ceremony with their authenticator.
+	webAuthnChallengeLifetime = 5 * time.Minute
+	mfaRecoveryCodeCount      = 10
+)
+
+func (api *API) webAuthnRelyingParty() webauthn.RelyingParty {
+	return webauthn.NewRelyingParty(api.AccessURL, "Coder")
+}
+
+// @Summary Get WebAuthn credentials by user
+// @ID get-webauthn-credentials-by-user
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.WebAuthnCredential
+// @Router /users/{user}/webauthn/credentials [get]
+func (api *API) webAuthnCredentials(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	credentials, err := api.Database.GetUserWebAuthnCredentialsByUserID(ctx, user.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching WebAuthn credentials.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := make([]codersdk.WebAuthnCredential, 0, len(credentials))
+	for _, credential := range credentials {
+		resp = append(resp, convertWebAuthnCredential(credential))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Create WebAuthn registration challenge
+// @ID create-webauthn-registration-challenge
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.WebAuthnCreationOptions
+// @Router /users/{user}/webauthn/challenge [post]
+func (api *API) postWebAuthnChallenge(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+	if !api.checkWebAuthnSelf(rw, r, user) {
+		return
+	}
+
+	credentials, err := api.Database.GetUserWebAuthnCredentialsByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching WebAuthn credentials.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	options, err := api.newWebAuthnCreationOptions(ctx, user, credentials, database.WebAuthnChallengePurposeRegistration)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating WebAuthn challenge.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, options)
+}
+
+// @Summary Create WebAuthn credential
+// @ID create-webauthn-credential
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.CreateWebAuthnCredentialRequest true "Create credential request"
+// @Success 201 {object} codersdk.CreateWebAuthnCredentialResponse
+// @Router /users/{user}/webauthn/credentials [post]
+func (api *API) postWebAuthnCredential(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+	if !api.checkWebAuthnSelf(rw, r, user) {
+		return
+	}
+
+	var req codersdk.CreateWebAuthnCredentialRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	challenge, ok := api.consumeWebAuthnChallenge(ctx, rw, req.ChallengeID, database.WebAuthnChallengePurposeRegistration)
+	if !ok {
+		return
+	}
+	if challenge.UserID != user.ID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid or expired challenge.",
+		})
+		return
+	}
+
+	credential, recoveryCodes, ok := api.registerWebAuthnCredential(ctx, rw, challenge, req)
+	if !ok {
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.CreateWebAuthnCredentialResponse{
+		Credential:    convertWebAuthnCredential(credential),
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// @Summary Delete WebAuthn credential
+// @ID delete-webauthn-credential
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param webauthncredential path string true "Credential ID" format(uuid)
+// @Success 204
+// @Router /users/{user}/webauthn/credentials/{webauthncredential} [delete]
+func (api *API) deleteWebAuthnCredential(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	id, err := uuid.Parse(chi.URLParam(r, "webauthncredential"))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid credential ID.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	credential, err := api.Database.GetUserWebAuthnCredentialByID(ctx, id)
+	if httpapi.Is404Error(err) || (err == nil && credential.UserID != user.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching WebAuthn credential.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Database.InTx(func(tx database.Store) error {
+		if err := tx.DeleteUserWebAuthnCredential(ctx, credential.ID); err != nil {
+			return xerrors.Errorf("delete credential: %w", err)
+		}
+		remaining, err := tx.GetUserWebAuthnCredentialsByUserID(ctx, user.ID)
+		if err != nil {
+			return xerrors.Errorf("get credentials: %w", err)
+		}
+		// Recovery codes replace a credential, so they are useless once the
+		// last one is gone.
+		if len(remaining) == 0 {
+			if err := tx.DeleteUserMFARecoveryCodes(ctx, user.ID); err != nil {
+				return xerrors.Errorf("delete recovery codes: %w", err)
+			}
+		}
+		return nil
+	}, nil)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting WebAuthn credential.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Regenerate MFA recovery codes
+// @ID regenerate-mfa-recovery-codes
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.MFARecoveryCodesResponse
+// @Router /users/{user}/webauthn/recovery-codes [post]
+func (api *API) postMFARecoveryCodes(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+	if !api.checkWebAuthnSelf(rw, r, user) {
+		return
+	}
+
+	var codes []string
+	err := api.Database.InTx(func(tx database.Store) error {
+		credentials, err := tx.GetUserWebAuthnCredentialsByUserID(ctx, user.ID)
+		if err != nil {
+			return xerrors.Errorf("get credentials: %w", err)
+		}
+		if len(credentials) == 0 {
+			return nil
+		}
+		codes, err = insertMFARecoveryCodes(ctx, tx, user.ID)
+		return err
+	}, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating recovery codes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if codes == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Register a WebAuthn credential before generating recovery codes.",
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.MFARecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// Completes a password login with a WebAuthn assertion or a recovery code.
+//
+// @Summary Log in user with WebAuthn
+// @ID log-in-user-with-webauthn
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.LoginWithWebAuthnRequest true "Login request"
+// @Success 201 {object} codersdk.LoginWithPasswordResponse
+// @Router /users/login/webauthn [post]
+func (api *API) postLoginWebAuthn(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	var req codersdk.LoginWithWebAuthnRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if (req.Assertion == nil) == (req.RecoveryCode == "") {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Exactly one of assertion and recovery code must be provided.",
+		})
+		return
+	}
+
+	challenge, ok := api.consumeWebAuthnChallenge(ctx, rw, req.ChallengeID, database.WebAuthnChallengePurposeLogin)
+	if !ok {
+		return
+	}
+	aReq.UserID = challenge.UserID
+
+	user, actor, ok := api.loginMFARequest(ctx, rw, challenge.UserID)
+	if !ok {
+		return
+	}
+
+	if req.RecoveryCode != "" {
+		//nolint:gocritic // The user is not logged in yet.
+		_, err := api.Database.UseUserMFARecoveryCode(dbauthz.AsSystemRestricted(ctx), database.UseUserMFARecoveryCodeParams{
+			UsedAt:     sql.NullTime{Time: dbtime.Now(), Valid: true},
+			UserID:     user.ID,
+			HashedCode: hashMFARecoveryCode(req.RecoveryCode),
+		})
+		if httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+				Message: "Incorrect recovery code.",
+			})
+			return
+		}
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+	} else if !api.verifyWebAuthnAssertion(ctx, rw, challenge, *req.Assertion) {
+		return
+	}
+
+	api.writeLoginSession(ctx, rw, r, aReq, user, actor, nil)
+}
+
+// Completes a password login by registering the first WebAuthn credential of a
+// user that is required to use one.
+//
+// @Summary Log in user with WebAuthn enrollment
+// @ID log-in-user-with-webauthn-enrollment
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.CreateWebAuthnCredentialRequest true "Enrollment request"
+// @Success 201 {object} codersdk.LoginWithPasswordResponse
+// @Router /users/login/webauthn/enroll [post]
+func (api *API) postLoginWebAuthnEnroll(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	var req codersdk.CreateWebAuthnCredentialRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	challenge, ok := api.consumeWebAuthnChallenge(ctx, rw, req.ChallengeID, database.WebAuthnChallengePurposeEnrollment)
+	if !ok {
+		return
+	}
+	aReq.UserID = challenge.UserID
+
+	user, actor, ok := api.loginMFARequest(ctx, rw, challenge.UserID)
+	if !ok {
+		return
+	}
+
+	//nolint:gocritic // The user is not logged in yet.
+	_, recoveryCodes, ok := api.registerWebAuthnCredential(dbauthz.AsSystemRestricted(ctx), rw, challenge, req)
+	if !ok {
+		return
+	}
+
+	api.writeLoginSession(ctx, rw, r, aReq, user, actor, recoveryCodes)
+}
+
+// @Summary Get organization MFA settings
+// @ID get-organization-mfa-settings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {object} codersdk.MFASettings
+// @Router /organizations/{organization}/mfa [get]
+func (api *API) organizationMFASettings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	settings, err := api.Database.GetOrganizationMFASettings(ctx, org.ID)
+	if err != nil && !httpapi.Is404Error(err) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.MFASettings{
+		RequireWebAuthn: settings.RequireWebAuthn,
+	})
+}
+
+// @Summary Update organization MFA settings
+// @ID update-organization-mfa-settings
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.MFASettings true "MFA settings"
+// @Success 200 {object} codersdk.MFASettings
+// @Router /organizations/{organization}/mfa [patch]
+func (api *API) patchOrganizationMFASettings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	var req codersdk.MFASettings
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	settings, err := api.Database.UpsertOrganizationMFASettings(ctx, database.UpsertOrganizationMFASettingsParams{
+		OrganizationID:  org.ID,
+		RequireWebAuthn: req.RequireWebAuthn,
+		UpdatedAt:       dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.MFASettings{
+		RequireWebAuthn: settings.RequireWebAuthn,
+	})
+}
+
+// checkWebAuthnSelf only allows users on password auth to manage their own
+// credentials, since registering one requires their authenticator.
+func (api *API) checkWebAuthnSelf(rw http.ResponseWriter, r *http.Request, user database.User) bool {
+	ctx := r.Context()
+	if httpmw.APIKey(r).UserID != user.ID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Users can only register WebAuthn credentials for themselves.",
+		})
+		return false
+	}
+	if user.LoginType != database.LoginTypePassword {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("WebAuthn credentials are only supported for password logins, but user is of login type %q.", user.LoginType),
+		})
+		return false
+	}
+	return true
+}
+
+// loginMFAChallenge returns the second factor challenge that replaces the
+// session token of a successful password login, or nil when the user does not
+// need one.
+func (api *API) loginMFAChallenge(ctx context.Context, user database.User) (*codersdk.LoginMFAChallenge, error) {
+	//nolint:gocritic // The user is not logged in yet.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	credentials, err := api.Database.GetUserWebAuthnCredentialsByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("get credentials: %w", err)
+	}
+	if len(credentials) > 0 {
+		options, err := api.newWebAuthnRequestOptions(ctx, user, credentials)
+		if err != nil {
+			return nil, err
+		}
+		return &codersdk.LoginMFAChallenge{Assertion: &options}, nil
+	}
+
+	required, err := api.Database.GetUserWebAuthnRequired(ctx, user.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("get webauthn required: %w", err)
+	}
+	if !required {
+		return nil, nil
+	}
+	options, err := api.newWebAuthnCreationOptions(ctx, user, nil, database.WebAuthnChallengePurposeEnrollment)
+	if err != nil {
+		return nil, err
+	}
+	return &codersdk.LoginMFAChallenge{Enrollment: &options}, nil
+}
+
+// loginMFARequest checks that the user of a second factor challenge can still
+// log in with a password, since their account may have changed after the
+// password was verified.
+func (api *API) loginMFARequest(ctx context.Context, rw http.ResponseWriter, userID uuid.UUID) (database.User, rbac.Subject, bool) {
+	//nolint:gocritic // The user is not logged in yet.
+	user, err := api.Database.GetUserByID(dbauthz.AsSystemRestricted(ctx), userID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return user, rbac.Subject{}, false
+	}
+	if api.DeploymentValues.DisablePasswordAuth {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Password authentication is disabled.",
+		})
+		return user, rbac.Subject{}, false
+	}
+	if user.Deleted || user.LoginType != database.LoginTypePassword {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Incorrect login type, attempting to use %q but user is of login type %q", database.LoginTypePassword, user.LoginType),
+		})
+		return user, rbac.Subject{}, false
+	}
+
+	subject, userStatus, err := httpmw.UserRBACSubject(ctx, api.Database, user.ID, rbac.ScopeAll)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return user, rbac.Subject{}, false
+	}
+	if userStatus != database.UserStatusActive {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: fmt.Sprintf("Your account is %s. Contact an admin to reactivate your account.", userStatus),
+		})
+		return user, rbac.Subject{}, false
+	}
+	return user, subject, true
+}
+
+// writeLoginSession creates a session for a user that passed every login
+// factor.
+func (api *API) writeLoginSession(ctx context.Context, rw http.ResponseWriter, r *http.Request, aReq *audit.Request[database.APIKey], user database.User, actor rbac.Subject, recoveryCodes []string) {
+	//nolint:gocritic // Creating the API key as the user instead of as system.
+	cookie, key, err := api.createAPIKey(dbauthz.As(ctx, actor), apikey.CreateParams{
+		UserID:            user.ID,
+		LoginType:         database.LoginTypePassword,
+		RemoteAddr:        r.RemoteAddr,
+		DefaultLifetime:   api.DeploymentValues.Sessions.DefaultDuration.Value(),
+		UserAgent:         r.UserAgent(),
+		DeviceFingerprint: apikey.DeviceFingerprint(r.Header),
+	})
+	if err != nil {
+		api.Logger.Named(userAuthLoggerName).Error(ctx, "unable to create API key", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to create API key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	aReq.New = *key
+
+	http.SetCookie(rw, cookie)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
+		SessionToken:  cookie.Value,
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+func (api *API) newWebAuthnCreationOptions(ctx context.Context, user database.User, credentials []database.UserWebAuthnCredential, purpose database.WebAuthnChallengePurpose) (codersdk.WebAuthnCreationOptions, error) {
+	challenge, err := api.insertWebAuthnChallenge(ctx, user.ID, purpose)
+	if err != nil {
+		return codersdk.WebAuthnCreationOptions{}, err
+	}
+	rp := api.webAuthnRelyingParty()
+	exclude := make([]string, 0, len(credentials))
+	for _, credential := range credentials {
+		exclude = append(exclude, webauthn.EncodeBase64(credential.CredentialID))
+	}
+	return codersdk.WebAuthnCreationOptions{
+		ChallengeID:        challenge.ID,
+		Challenge:          webauthn.EncodeBase64(challenge.Challenge),
+		RPID:               rp.ID,
+		RPName:             rp.Name,
+		UserHandle:         webauthn.EncodeBase64(user.ID[:]),
+		Username:           user.Username,
+		Algorithms:         webauthn.SupportedAlgorithms,
+		ExcludeCredentials: exclude,
+		TimeoutMS:          webAuthnChallengeLifetime.Milliseconds(),
+	}, nil
+}
+
+func (api *API) newWebAuthnRequestOptions(ctx context.Context, user database.User, credentials []database.UserWebAuthnCredential) (codersdk.WebAuthnRequestOptions, error) {
+	challenge, err := api.insertWebAuthnChallenge(ctx, user.ID, database.WebAuthnChallengePurposeLogin)
+	if err != nil {
+		return codersdk.WebAuthnRequestOptions{}, err
+	}
+	allow := make([]string, 0, len(credentials))
+	for _, credential := range credentials {
+		allow = append(allow, webauthn.EncodeBase64(credential.CredentialID))
+	}
+	return codersdk.WebAuthnRequestOptions{
+		ChallengeID:      challenge.ID,
+		Challenge:        webauthn.EncodeBase64(challenge.Challenge),
+		RPID:             api.webAuthnRelyingParty().ID,
+		AllowCredentials: allow,
+		TimeoutMS:        webAuthnChallengeLifetime.Milliseconds(),
+	}, nil
+}
+
+func (api *API) insertWebAuthnChallenge(ctx context.Context, userID uuid.UUID, purpose database.WebAuthnChallengePurpose) (database.WebAuthnChallenge, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return database.WebAuthnChallenge{}, xerrors.Errorf("generate challenge: %w", err)
+	}
+	now := dbtime.Now()
+	//nolint:gocritic // Challenges are only ever read back by the system.
+	inserted, err := api.Database.InsertWebAuthnChallenge(dbauthz.AsSystemRestricted(ctx), database.InsertWebAuthnChallengeParams{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Purpose:   purpose,
+		Challenge: challenge,
+		CreatedAt: now,
+		ExpiresAt: now.Add(webAuthnChallengeLifetime),
+	})
+	if err != nil {
+		return database.WebAuthnChallenge{}, xerrors.Errorf("insert challenge: %w", err)
+	}
+	return inserted, nil
+}
+
+// consumeWebAuthnChallenge deletes the challenge, so that it can only be
+// answered once even when the answer is wrong.
+func (api *API) consumeWebAuthnChallenge(ctx context.Context, rw http.ResponseWriter, id uuid.UUID, purpose database.WebAuthnChallengePurpose) (database.WebAuthnChallenge, bool) {
+	//nolint:gocritic // Challenges are only ever read back by the system.
+	challenge, err := api.Database.DeleteWebAuthnChallengeByID(dbauthz.AsSystemRestricted(ctx), id)
+	if err != nil && !httpapi.Is404Error(err) {
+		httpapi.InternalServerError(rw, err)
+		return challenge, false
+	}
+	if err != nil || challenge.Purpose != purpose || dbtime.Now().After(challenge.ExpiresAt) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid or expired challenge.",
+		})
+		return challenge, false
+	}
+	return challenge, true
+}
+
+// registerWebAuthnCredential verifies the attestation of a new credential and
+// stores it. Recovery codes are returned when it is the first credential of the
+// user.
+func (api *API) registerWebAuthnCredential(ctx context.Context, rw http.ResponseWriter, challenge database.WebAuthnChallenge, req codersdk.CreateWebAuthnCredentialRequest) (database.UserWebAuthnCredential, []string, bool) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 64 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid credential name.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "Must be between 1 and 64 characters.",
+			}},
+		})
+		return database.UserWebAuthnCredential{}, nil, false
+	}
+
+	clientDataJSON, err := webauthn.DecodeBase64(req.Attestation.ClientDataJSON)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid client data.",
+			Detail:  err.Error(),
+		})
+		return database.UserWebAuthnCredential{}, nil, false
+	}
+	attestationObject, err := webauthn.DecodeBase64(req.Attestation.AttestationObject)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid attestation object.",
+			Detail:  err.Error(),
+		})
+		return database.UserWebAuthnCredential{}, nil, false
+	}
+	verified, err := api.webAuthnRelyingParty().VerifyRegistration(challenge.Challenge, clientDataJSON, attestationObject)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to verify the WebAuthn credential.",
+			Detail:  err.Error(),
+		})
+		return database.UserWebAuthnCredential{}, nil, false
+	}
+
+	var (
+		credential    database.UserWebAuthnCredential
+		recoveryCodes []string
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		existing, err := tx.GetUserWebAuthnCredentialsByUserID(ctx, challenge.UserID)
+		if err != nil {
+			return xerrors.Errorf("get credentials: %w", err)
+		}
+		credential, err = tx.InsertUserWebAuthnCredential(ctx, database.InsertUserWebAuthnCredentialParams{
+			ID:           uuid.New(),
+			UserID:       challenge.UserID,
+			Name:         name,
+			CredentialID: verified.ID,
+			PublicKey:    verified.PublicKey,
+			SignCount:    int64(verified.SignCount),
+			CreatedAt:    dbtime.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if len(existing) == 0 {
+			recoveryCodes, err = insertMFARecoveryCodes(ctx, tx, challenge.UserID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil)
+	if database.IsUniqueViolation(err, database.UniqueUserWebauthnCredentialsCredentialIDKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "This credential is already registered.",
+		})
+		return database.UserWebAuthnCredential{}, nil, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating WebAuthn credential.",
+			Detail:  err.Error(),
+		})
+		return database.UserWebAuthnCredential{}, nil, false
+	}
+	return credential, recoveryCodes, true
+}
+
+// verifyWebAuthnAssertion verifies that one of the credentials of the user of
+// the challenge signed it.
+func (api *API) verifyWebAuthnAssertion(ctx context.Context, rw http.ResponseWriter, challenge database.WebAuthnChallenge, assertion codersdk.WebAuthnAssertion) bool {
+	//nolint:gocritic // The user is not logged in yet.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	unauthorized := func() bool {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Failed to verify the WebAuthn assertion.",
+		})
+		return false
+	}
+
+	credentialID, err := webauthn.DecodeBase64(assertion.CredentialID)
+	if err != nil {
+		return unauthorized()
+	}
+	credential, err := api.Database.GetUserWebAuthnCredentialByCredentialID(sysCtx, credentialID)
+	if httpapi.Is404Error(err) || (err == nil && credential.UserID != challenge.UserID) {
+		return unauthorized()
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return false
+	}
+
+	clientDataJSON, err := webauthn.DecodeBase64(assertion.ClientDataJSON)
+	if err != nil {
+		return unauthorized()
+	}
+	authData, err := webauthn.DecodeBase64(assertion.AuthenticatorData)
+	if err != nil {
+		return unauthorized()
+	}
+	signature, err := webauthn.DecodeBase64(assertion.Signature)
+	if err != nil {
+		return unauthorized()
+	}
+	signCount, err := api.webAuthnRelyingParty().VerifyAssertion(challenge.Challenge, credential.PublicKey, uint32(credential.SignCount), clientDataJSON, authData, signature)
+	if err != nil {
+		api.Logger.Named(userAuthLoggerName).Warn(ctx, "webauthn assertion failed",
+			slog.F("user_id", challenge.UserID),
+			slog.F("credential_id", credential.ID),
+			slog.Error(err),
+		)
+		return unauthorized()
+	}
+
+	err = api.Database.UpdateUserWebAuthnCredentialSignCount(sysCtx, database.UpdateUserWebAuthnCredentialSignCountParams{
+		ID:         credential.ID,
+		SignCount:  int64(signCount),
+		LastUsedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return false
+	}
+	return true
+}
+
+// insertMFARecoveryCodes replaces the recovery codes of the user. Only hashes
+// are stored, so the codes must be shown to the user right away.
+func insertMFARecoveryCodes(ctx context.Context, tx database.Store, userID uuid.UUID) ([]string, error) {
+	if err := tx.DeleteUserMFARecoveryCodes(ctx, userID); err != nil {
+		return nil, xerrors.Errorf("delete recovery codes: %w", err)
+	}
+	now := dbtime.Now()
+	codes := make([]string, 0, mfaRecoveryCodeCount)
+	for range mfaRecoveryCodeCount {
+		code, err := cryptorand.StringCharset(cryptorand.Human, 10)
+		if err != nil {
+			return nil, xerrors.Errorf("generate recovery code: %w", err)
+		}
+		code = code[:5] + "-" + code[5:]
+		err = tx.InsertUserMFARecoveryCode(ctx, database.InsertUserMFARecoveryCodeParams{
+			UserID:     userID,
+			HashedCode: hashMFARecoveryCode(code),
+			CreatedAt:  now,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("insert recovery code: %w", err)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// hashMFARecoveryCode ignores case and separators, so that codes can be typed
+// however they were written down.
+func hashMFARecoveryCode(code string) []byte {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	hashed := sha256.Sum256([]byte(code))
+	return hashed[:]
+}
+
+func convertWebAuthnCredential(credential database.UserWebAuthnCredential) codersdk.WebAuthnCredential {
+	resp := codersdk.WebAuthnCredential{
+		ID:        credential.ID,
+		UserID:    credential.UserID,
+		Name:      credential.Name,
+		CreatedAt: credential.CreatedAt,
+	}
+	if credential.LastUsedAt.Valid {
+		resp.LastUsedAt = &credential.LastUsedAt.Time
+	}
+	return resp
+}
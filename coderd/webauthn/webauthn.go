@@ -0,0 +1,399 @@
+// Package webauthn verifies the WebAuthn ceremonies of security keys and
+// passkeys used as a second factor for password logins.
+//
+// Only the subset of the specification needed for a second factor is
+// implemented. Attestation statements are not verified since any
+// authenticator is accepted, and user verification is not required since the
+// user already entered their password.
+package webauthn
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"net/url"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// ChallengeSize is the number of random bytes in a challenge.
+	ChallengeSize = 32
+
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+
+	flagUserPresent            = 0x01
+	flagAttestedCredentialData = 0x40
+
+	// COSE algorithm identifiers, from the IANA COSE Algorithms registry.
+	AlgorithmES256 = -7
+	AlgorithmEdDSA = -8
+	AlgorithmRS256 = -257
+)
+
+// SupportedAlgorithms are the COSE algorithms of the public keys that are
+// accepted, in order of preference.
+var SupportedAlgorithms = []int64{AlgorithmES256, AlgorithmEdDSA, AlgorithmRS256}
+
+// RelyingParty is the deployment that credentials are registered with.
+type RelyingParty struct {
+	// ID is the hostname credentials are scoped to.
+	ID string
+	// Name is displayed by authenticators when registering a credential.
+	Name string
+	// Origin is the origin of the pages the ceremonies run on.
+	Origin string
+}
+
+// NewRelyingParty returns the relying party of a deployment served on the
+// access URL.
+func NewRelyingParty(accessURL *url.URL, name string) RelyingParty {
+	return RelyingParty{
+		ID:     accessURL.Hostname(),
+		Name:   name,
+		Origin: accessURL.Scheme + "://" + accessURL.Host,
+	}
+}
+
+// Credential is a newly registered public key credential.
+type Credential struct {
+	ID []byte
+	// PublicKey is the COSE encoded public key of the credential.
+	PublicKey []byte
+	SignCount uint32
+}
+
+// NewChallenge returns a random challenge for a ceremony.
+func NewChallenge() ([]byte, error) {
+	challenge := make([]byte, ChallengeSize)
+	_, err := rand.Read(challenge)
+	if err != nil {
+		return nil, xerrors.Errorf("read random bytes: %w", err)
+	}
+	return challenge, nil
+}
+
+// EncodeBase64 encodes binary values the way browsers do in the JSON
+// serialization of credentials.
+func EncodeBase64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeBase64 decodes base64url values, with or without padding.
+func DecodeBase64(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(trimPadding(s))
+}
+
+func trimPadding(s string) string {
+	for len(s) > 0 && s[len(s)-1] == '=' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+func (rp RelyingParty) verifyClientData(clientDataJSON []byte, ceremony string, challenge []byte) error {
+	var data clientData
+	err := json.Unmarshal(clientDataJSON, &data)
+	if err != nil {
+		return xerrors.Errorf("parse client data: %w", err)
+	}
+	if data.Type != ceremony {
+		return xerrors.Errorf("client data has type %q, expected %q", data.Type, ceremony)
+	}
+	got, err := DecodeBase64(data.Challenge)
+	if err != nil {
+		return xerrors.Errorf("decode challenge: %w", err)
+	}
+	if subtle.ConstantTimeCompare(got, challenge) != 1 {
+		return xerrors.New("client data does not match the challenge")
+	}
+	if data.Origin != rp.Origin {
+		return xerrors.Errorf("client data has origin %q, expected %q", data.Origin, rp.Origin)
+	}
+	return nil
+}
+
+type authenticatorData struct {
+	rpIDHash  []byte
+	flags     byte
+	signCount uint32
+	// Only set when flagAttestedCredentialData is set.
+	credentialID []byte
+	publicKey    []byte
+}
+
+func parseAuthenticatorData(data []byte) (authenticatorData, error) {
+	if len(data) < 37 {
+		return authenticatorData{}, xerrors.New("authenticator data is too short")
+	}
+	parsed := authenticatorData{
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	if parsed.flags&flagAttestedCredentialData == 0 {
+		return parsed, nil
+	}
+
+	// The attested credential data is the AAGUID, the length of the
+	// credential ID, the credential ID and the COSE public key.
+	rest := data[37:]
+	if len(rest) < 18 {
+		return authenticatorData{}, xerrors.New("attested credential data is too short")
+	}
+	idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if len(rest) < idLength {
+		return authenticatorData{}, xerrors.New("credential ID is truncated")
+	}
+	parsed.credentialID = rest[:idLength]
+	rest = rest[idLength:]
+	// Extensions may follow the public key, so only the first item is read.
+	var key cbor.RawMessage
+	if _, err := cbor.UnmarshalFirst(rest, &key); err != nil {
+		return authenticatorData{}, xerrors.Errorf("parse credential public key: %w", err)
+	}
+	parsed.publicKey = key
+	return parsed, nil
+}
+
+func (rp RelyingParty) verifyAuthenticatorData(data authenticatorData) error {
+	hash := sha256.Sum256([]byte(rp.ID))
+	if !bytes.Equal(data.rpIDHash, hash[:]) {
+		return xerrors.New("authenticator data is for another relying party")
+	}
+	if data.flags&flagUserPresent == 0 {
+		return xerrors.New("user was not present")
+	}
+	return nil
+}
+
+type attestationObject struct {
+	// The attestation statement in "fmt" and "attStmt" is not verified.
+	AuthData []byte `cbor:"authData"`
+}
+
+// VerifyRegistration verifies the response of navigator.credentials.create()
+// to a registration challenge, and returns the new credential.
+func (rp RelyingParty) VerifyRegistration(challenge, clientDataJSON, rawAttestationObject []byte) (Credential, error) {
+	err := rp.verifyClientData(clientDataJSON, ceremonyCreate, challenge)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	var attestation attestationObject
+	err = cbor.Unmarshal(rawAttestationObject, &attestation)
+	if err != nil {
+		return Credential{}, xerrors.Errorf("parse attestation object: %w", err)
+	}
+	data, err := parseAuthenticatorData(attestation.AuthData)
+	if err != nil {
+		return Credential{}, err
+	}
+	err = rp.verifyAuthenticatorData(data)
+	if err != nil {
+		return Credential{}, err
+	}
+	if data.flags&flagAttestedCredentialData == 0 {
+		return Credential{}, xerrors.New("authenticator data has no attested credential")
+	}
+	if len(data.credentialID) == 0 {
+		return Credential{}, xerrors.New("credential ID is empty")
+	}
+	// Ensure the key is usable before it is stored.
+	if _, _, err := parsePublicKey(data.publicKey); err != nil {
+		return Credential{}, err
+	}
+
+	return Credential{
+		ID:        bytes.Clone(data.credentialID),
+		PublicKey: bytes.Clone(data.publicKey),
+		SignCount: data.signCount,
+	}, nil
+}
+
+// VerifyAssertion verifies the response of navigator.credentials.get() to a
+// login challenge, and returns the new signature counter of the credential.
+func (rp RelyingParty) VerifyAssertion(challenge, publicKey []byte, signCount uint32, clientDataJSON, rawAuthenticatorData, signature []byte) (uint32, error) {
+	err := rp.verifyClientData(clientDataJSON, ceremonyGet, challenge)
+	if err != nil {
+		return 0, err
+	}
+	data, err := parseAuthenticatorData(rawAuthenticatorData)
+	if err != nil {
+		return 0, err
+	}
+	err = rp.verifyAuthenticatorData(data)
+	if err != nil {
+		return 0, err
+	}
+
+	key, algorithm, err := parsePublicKey(publicKey)
+	if err != nil {
+		return 0, err
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(bytes.Clone(rawAuthenticatorData), clientDataHash[:]...)
+	err = verifySignature(key, algorithm, signed, signature)
+	if err != nil {
+		return 0, err
+	}
+
+	// Authenticators that don't implement counters always report zero. A
+	// counter that didn't increase means the credential was cloned.
+	if (data.signCount != 0 || signCount != 0) && data.signCount <= signCount {
+		return 0, xerrors.New("signature counter did not increase, the authenticator may have been cloned")
+	}
+	return data.signCount, nil
+}
+
+const (
+	coseKeyType      = 1
+	coseKeyAlgorithm = 3
+	// EC2 and OKP keys.
+	coseKeyCurve = -1
+	coseKeyX     = -2
+	coseKeyY     = -3
+	// RSA keys.
+	coseKeyN = -1
+	coseKeyE = -2
+
+	coseKeyTypeOKP = 1
+	coseKeyTypeEC2 = 2
+	coseKeyTypeRSA = 3
+
+	coseCurveP256    = 1
+	coseCurveEd25519 = 6
+)
+
+// parsePublicKey parses a COSE encoded public key.
+func parsePublicKey(raw []byte) (crypto.PublicKey, int64, error) {
+	var key map[int64]cbor.RawMessage
+	err := cbor.Unmarshal(raw, &key)
+	if err != nil {
+		return nil, 0, xerrors.Errorf("parse public key: %w", err)
+	}
+	var keyType, algorithm int64
+	if err := decodeKeyParam(key, coseKeyType, &keyType); err != nil {
+		return nil, 0, err
+	}
+	if err := decodeKeyParam(key, coseKeyAlgorithm, &algorithm); err != nil {
+		return nil, 0, err
+	}
+
+	switch {
+	case keyType == coseKeyTypeEC2 && algorithm == AlgorithmES256:
+		var curve int64
+		var x, y []byte
+		if err := decodeKeyParam(key, coseKeyCurve, &curve); err != nil {
+			return nil, 0, err
+		}
+		if err := decodeKeyParam(key, coseKeyX, &x); err != nil {
+			return nil, 0, err
+		}
+		if err := decodeKeyParam(key, coseKeyY, &y); err != nil {
+			return nil, 0, err
+		}
+		if curve != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return nil, 0, xerrors.Errorf("unsupported curve %d", curve)
+		}
+		// crypto/ecdh rejects points that are not on the curve.
+		point := append(append([]byte{0x04}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, 0, xerrors.Errorf("invalid public key: %w", err)
+		}
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, algorithm, nil
+	case keyType == coseKeyTypeOKP && algorithm == AlgorithmEdDSA:
+		var curve int64
+		var x []byte
+		if err := decodeKeyParam(key, coseKeyCurve, &curve); err != nil {
+			return nil, 0, err
+		}
+		if err := decodeKeyParam(key, coseKeyX, &x); err != nil {
+			return nil, 0, err
+		}
+		if curve != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, 0, xerrors.Errorf("unsupported curve %d", curve)
+		}
+		return ed25519.PublicKey(x), algorithm, nil
+	case keyType == coseKeyTypeRSA && algorithm == AlgorithmRS256:
+		var n, e []byte
+		if err := decodeKeyParam(key, coseKeyN, &n); err != nil {
+			return nil, 0, err
+		}
+		if err := decodeKeyParam(key, coseKeyE, &e); err != nil {
+			return nil, 0, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, 0, xerrors.New("public key exponent is too large")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(exponent.Int64()),
+		}, algorithm, nil
+	default:
+		return nil, 0, xerrors.Errorf("unsupported key type %d with algorithm %d", keyType, algorithm)
+	}
+}
+
+func decodeKeyParam(key map[int64]cbor.RawMessage, label int64, v interface{}) error {
+	raw, ok := key[label]
+	if !ok {
+		return xerrors.Errorf("public key is missing parameter %d", label)
+	}
+	err := cbor.Unmarshal(raw, v)
+	if err != nil {
+		return xerrors.Errorf("parse public key parameter %d: %w", label, err)
+	}
+	return nil
+}
+
+func verifySignature(key crypto.PublicKey, algorithm int64, signed, signature []byte) error {
+	switch algorithm {
+	case AlgorithmES256:
+		hash := sha256.Sum256(signed)
+		//nolint:forcetypeassert // parsePublicKey returns ECDSA keys for ES256.
+		if !ecdsa.VerifyASN1(key.(*ecdsa.PublicKey), hash[:], signature) {
+			return xerrors.New("invalid signature")
+		}
+	case AlgorithmEdDSA:
+		//nolint:forcetypeassert // parsePublicKey returns Ed25519 keys for EdDSA.
+		if !ed25519.Verify(key.(ed25519.PublicKey), signed, signature) {
+			return xerrors.New("invalid signature")
+		}
+	case AlgorithmRS256:
+		hash := sha256.Sum256(signed)
+		//nolint:forcetypeassert // parsePublicKey returns RSA keys for RS256.
+		err := rsa.VerifyPKCS1v15(key.(*rsa.PublicKey), crypto.SHA256, hash[:], signature)
+		if err != nil {
+			return xerrors.New("invalid signature")
+		}
+	default:
+		return xerrors.Errorf("unsupported algorithm %d", algorithm)
+	}
+	return nil
+}
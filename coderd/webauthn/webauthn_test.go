@@ -0,0 +1,127 @@
+package webauthn_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/webauthn"
+	"github.com/coder/coder/v2/coderd/webauthn/webauthntest"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestWebAuthn(t *testing.T) {
+	t.Parallel()
+
+	accessURL, err := url.Parse("https://coder.example.com:8443/path")
+	require.NoError(t, err)
+	rp := webauthn.NewRelyingParty(accessURL, "Coder")
+	require.Equal(t, "coder.example.com", rp.ID)
+	require.Equal(t, "https://coder.example.com:8443", rp.Origin)
+
+	register := func(t *testing.T, authenticator *webauthntest.Authenticator) webauthn.Credential {
+		challenge, err := webauthn.NewChallenge()
+		require.NoError(t, err)
+		attestation := authenticator.Register(t, codersdk.WebAuthnCreationOptions{
+			Challenge: webauthn.EncodeBase64(challenge),
+			RPID:      rp.ID,
+		})
+		clientDataJSON, err := webauthn.DecodeBase64(attestation.ClientDataJSON)
+		require.NoError(t, err)
+		attestationObject, err := webauthn.DecodeBase64(attestation.AttestationObject)
+		require.NoError(t, err)
+		credential, err := rp.VerifyRegistration(challenge, clientDataJSON, attestationObject)
+		require.NoError(t, err)
+		return credential
+	}
+
+	assert := func(t *testing.T, authenticator *webauthntest.Authenticator, credential webauthn.Credential, rpID string) (uint32, error) {
+		challenge, err := webauthn.NewChallenge()
+		require.NoError(t, err)
+		assertion := authenticator.Assert(t, codersdk.WebAuthnRequestOptions{
+			Challenge: webauthn.EncodeBase64(challenge),
+			RPID:      rpID,
+		})
+		clientDataJSON, err := webauthn.DecodeBase64(assertion.ClientDataJSON)
+		require.NoError(t, err)
+		authData, err := webauthn.DecodeBase64(assertion.AuthenticatorData)
+		require.NoError(t, err)
+		signature, err := webauthn.DecodeBase64(assertion.Signature)
+		require.NoError(t, err)
+		return rp.VerifyAssertion(challenge, credential.PublicKey, credential.SignCount, clientDataJSON, authData, signature)
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		authenticator := webauthntest.New(t, rp.Origin)
+		credential := register(t, authenticator)
+		require.Equal(t, authenticator.CredentialID(), webauthn.EncodeBase64(credential.ID))
+
+		signCount, err := assert(t, authenticator, credential, rp.ID)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, signCount)
+	})
+
+	t.Run("WrongChallenge", func(t *testing.T) {
+		t.Parallel()
+		authenticator := webauthntest.New(t, rp.Origin)
+		challenge, err := webauthn.NewChallenge()
+		require.NoError(t, err)
+		other, err := webauthn.NewChallenge()
+		require.NoError(t, err)
+		attestation := authenticator.Register(t, codersdk.WebAuthnCreationOptions{
+			Challenge: webauthn.EncodeBase64(other),
+			RPID:      rp.ID,
+		})
+		clientDataJSON, err := webauthn.DecodeBase64(attestation.ClientDataJSON)
+		require.NoError(t, err)
+		attestationObject, err := webauthn.DecodeBase64(attestation.AttestationObject)
+		require.NoError(t, err)
+		_, err = rp.VerifyRegistration(challenge, clientDataJSON, attestationObject)
+		require.ErrorContains(t, err, "does not match the challenge")
+	})
+
+	t.Run("WrongOrigin", func(t *testing.T) {
+		t.Parallel()
+		authenticator := webauthntest.New(t, "https://evil.example.com")
+		challenge, err := webauthn.NewChallenge()
+		require.NoError(t, err)
+		attestation := authenticator.Register(t, codersdk.WebAuthnCreationOptions{
+			Challenge: webauthn.EncodeBase64(challenge),
+			RPID:      rp.ID,
+		})
+		clientDataJSON, err := webauthn.DecodeBase64(attestation.ClientDataJSON)
+		require.NoError(t, err)
+		attestationObject, err := webauthn.DecodeBase64(attestation.AttestationObject)
+		require.NoError(t, err)
+		_, err = rp.VerifyRegistration(challenge, clientDataJSON, attestationObject)
+		require.ErrorContains(t, err, "origin")
+	})
+
+	t.Run("WrongRelyingParty", func(t *testing.T) {
+		t.Parallel()
+		authenticator := webauthntest.New(t, rp.Origin)
+		credential := register(t, authenticator)
+		_, err := assert(t, authenticator, credential, "evil.example.com")
+		require.ErrorContains(t, err, "another relying party")
+	})
+
+	t.Run("WrongKey", func(t *testing.T) {
+		t.Parallel()
+		authenticator := webauthntest.New(t, rp.Origin)
+		credential := register(t, webauthntest.New(t, rp.Origin))
+		_, err := assert(t, authenticator, credential, rp.ID)
+		require.ErrorContains(t, err, "invalid signature")
+	})
+
+	t.Run("ClonedAuthenticator", func(t *testing.T) {
+		t.Parallel()
+		authenticator := webauthntest.New(t, rp.Origin)
+		credential := register(t, authenticator)
+		credential.SignCount = 5
+		authenticator.SignCount = 3
+		_, err := assert(t, authenticator, credential, rp.ID)
+		require.ErrorContains(t, err, "cloned")
+	})
+}
@@ -0,0 +1,122 @@
+// Package webauthntest provides a virtual WebAuthn authenticator for tests.
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/webauthn"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Authenticator is a virtual security key holding a single ES256 credential.
+type Authenticator struct {
+	// Origin is reported in the client data, like a browser would.
+	Origin string
+	// SignCount is incremented on every assertion.
+	SignCount uint32
+
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+}
+
+// New returns an authenticator for pages served on the origin.
+func New(t testing.TB, origin string) *Authenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	credentialID := make([]byte, 16)
+	_, err = rand.Read(credentialID)
+	require.NoError(t, err)
+	return &Authenticator{
+		Origin:       origin,
+		key:          key,
+		credentialID: credentialID,
+	}
+}
+
+// CredentialID returns the base64url encoded ID of the credential.
+func (a *Authenticator) CredentialID() string {
+	return webauthn.EncodeBase64(a.credentialID)
+}
+
+// Register responds to a registration challenge.
+func (a *Authenticator) Register(t testing.TB, options codersdk.WebAuthnCreationOptions) codersdk.WebAuthnAttestation {
+	t.Helper()
+	clientDataJSON := a.clientData(t, "webauthn.create", options.Challenge)
+
+	ecdhKey, err := a.key.PublicKey.ECDH()
+	require.NoError(t, err)
+	// The uncompressed point is 0x04 followed by the coordinates.
+	point := ecdhKey.Bytes()
+	publicKey, err := cbor.Marshal(map[int]interface{}{
+		1:  2,  // EC2
+		3:  -7, // ES256
+		-1: 1,  // P-256
+		-2: point[1:33],
+		-3: point[33:],
+	})
+	require.NoError(t, err)
+
+	authData := a.authenticatorData(options.RPID, 0x01|0x40)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, publicKey...)
+
+	attestationObject, err := cbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": authData,
+	})
+	require.NoError(t, err)
+
+	return codersdk.WebAuthnAttestation{
+		ClientDataJSON:    webauthn.EncodeBase64(clientDataJSON),
+		AttestationObject: webauthn.EncodeBase64(attestationObject),
+	}
+}
+
+// Assert responds to a login challenge.
+func (a *Authenticator) Assert(t testing.TB, options codersdk.WebAuthnRequestOptions) codersdk.WebAuthnAssertion {
+	t.Helper()
+	a.SignCount++
+	clientDataJSON := a.clientData(t, "webauthn.get", options.Challenge)
+	authData := a.authenticatorData(options.RPID, 0x01)
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, signed[:])
+	require.NoError(t, err)
+
+	return codersdk.WebAuthnAssertion{
+		CredentialID:      a.CredentialID(),
+		ClientDataJSON:    webauthn.EncodeBase64(clientDataJSON),
+		AuthenticatorData: webauthn.EncodeBase64(authData),
+		Signature:         webauthn.EncodeBase64(signature),
+	}
+}
+
+func (a *Authenticator) clientData(t testing.TB, ceremony, challenge string) []byte {
+	clientDataJSON, err := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": challenge,
+		"origin":    a.Origin,
+	})
+	require.NoError(t, err)
+	return clientDataJSON
+}
+
+func (a *Authenticator) authenticatorData(rpID string, flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	authData := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(authData, a.SignCount)
+}
@@ -0,0 +1,301 @@
+package coderd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/webauthn/webauthntest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWebAuthn(t *testing.T) {
+	t.Parallel()
+
+	// password is the password coderdtest gives new users.
+	const password = "SomeSecurePassword!"
+
+	// setup returns an unauthenticated client and a client for a new member
+	// that logs in with a password.
+	setup := func(t *testing.T) (owner *codersdk.Client, anonymous *codersdk.Client, member *codersdk.Client, email string, authenticator *webauthntest.Authenticator) {
+		owner = coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, owner)
+		member, user := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		anonymous = codersdk.New(owner.URL)
+		authenticator = webauthntest.New(t, owner.URL.Scheme+"://"+owner.URL.Host)
+		return owner, anonymous, member, user.Email, authenticator
+	}
+
+	register := func(t *testing.T, client *codersdk.Client, authenticator *webauthntest.Authenticator) codersdk.CreateWebAuthnCredentialResponse {
+		ctx := testutil.Context(t, testutil.WaitLong)
+		options, err := client.WebAuthnCreationOptions(ctx, codersdk.Me)
+		require.NoError(t, err)
+		resp, err := client.CreateWebAuthnCredential(ctx, codersdk.Me, codersdk.CreateWebAuthnCredentialRequest{
+			ChallengeID: options.ChallengeID,
+			Name:        "YubiKey",
+			Attestation: authenticator.Register(t, options),
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Login", func(t *testing.T) {
+		t.Parallel()
+		_, anonymous, member, email, authenticator := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp := register(t, member, authenticator)
+		require.Equal(t, "YubiKey", resp.Credential.Name)
+		require.Len(t, resp.RecoveryCodes, 10)
+
+		login, err := anonymous.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    email,
+			Password: password,
+		})
+		require.NoError(t, err)
+		require.Empty(t, login.SessionToken)
+		require.NotNil(t, login.MFA)
+		require.NotNil(t, login.MFA.Assertion)
+		require.Equal(t, []string{authenticator.CredentialID()}, login.MFA.Assertion.AllowCredentials)
+
+		login, err = anonymous.LoginWithWebAuthn(ctx, codersdk.LoginWithWebAuthnRequest{
+			ChallengeID: login.MFA.Assertion.ChallengeID,
+			Assertion:   ptr.Ref(authenticator.Assert(t, *login.MFA.Assertion)),
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, login.SessionToken)
+
+		credentials, err := member.WebAuthnCredentials(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, credentials, 1)
+		require.NotNil(t, credentials[0].LastUsedAt)
+	})
+
+	t.Run("LoginHandlers", func(t *testing.T) {
+		t.Parallel()
+		owner, _, member, email, authenticator := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_ = register(t, member, authenticator)
+
+		post := func(path string, body any) *http.Response {
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, owner.URL.JoinPath(path).String(), bytes.NewReader(data))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = res.Body.Close() })
+			return res
+		}
+
+		// The password alone doesn't create a session.
+		res := post("/api/v2/users/login", codersdk.LoginWithPasswordRequest{
+			Email:    email,
+			Password: password,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Empty(t, res.Cookies())
+		var login codersdk.LoginWithPasswordResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&login))
+		require.Empty(t, login.SessionToken)
+		require.NotNil(t, login.MFA)
+		require.NotNil(t, login.MFA.Assertion)
+
+		// Signing the challenge does.
+		res = post("/api/v2/users/login/webauthn", codersdk.LoginWithWebAuthnRequest{
+			ChallengeID: login.MFA.Assertion.ChallengeID,
+			Assertion:   ptr.Ref(authenticator.Assert(t, *login.MFA.Assertion)),
+		})
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		var session *http.Cookie
+		for _, cookie := range res.Cookies() {
+			if cookie.Name == codersdk.SessionTokenCookie {
+				session = cookie
+			}
+		}
+		require.NotNil(t, session)
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&login))
+		require.Equal(t, session.Value, login.SessionToken)
+
+		// The session cookie authenticates the member, as it does in the
+		// browser.
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, owner.URL.JoinPath("/api/v2/users/me").String(), nil)
+		require.NoError(t, err)
+		req.AddCookie(session)
+		res, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var user codersdk.User
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&user))
+		require.Equal(t, email, user.Email)
+		require.Equal(t, codersdk.LoginTypePassword, user.LoginType)
+	})
+
+	t.Run("ChallengeSingleUse", func(t *testing.T) {
+		t.Parallel()
+		_, anonymous, member, email, authenticator := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_ = register(t, member, authenticator)
+
+		login, err := anonymous.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    email,
+			Password: password,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, login.MFA)
+
+		assertion := authenticator.Assert(t, *login.MFA.Assertion)
+		_, err = anonymous.LoginWithWebAuthn(ctx, codersdk.LoginWithWebAuthnRequest{
+			ChallengeID: login.MFA.Assertion.ChallengeID,
+			Assertion:   &assertion,
+		})
+		require.NoError(t, err)
+
+		_, err = anonymous.LoginWithWebAuthn(ctx, codersdk.LoginWithWebAuthnRequest{
+			ChallengeID: login.MFA.Assertion.ChallengeID,
+			Assertion:   &assertion,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("WrongAuthenticator", func(t *testing.T) {
+		t.Parallel()
+		owner, anonymous, member, email, authenticator := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_ = register(t, member, authenticator)
+
+		login, err := anonymous.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    email,
+			Password: password,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, login.MFA)
+
+		other := webauthntest.New(t, owner.URL.Scheme+"://"+owner.URL.Host)
+		_, err = anonymous.LoginWithWebAuthn(ctx, codersdk.LoginWithWebAuthnRequest{
+			ChallengeID: login.MFA.Assertion.ChallengeID,
+			Assertion:   ptr.Ref(other.Assert(t, *login.MFA.Assertion)),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("RecoveryCode", func(t *testing.T) {
+		t.Parallel()
+		_, anonymous, member, email, authenticator := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		resp := register(t, member, authenticator)
+
+		loginWithCode := func(code string) error {
+			login, err := anonymous.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+				Email:    email,
+				Password: password,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, login.MFA)
+			_, err = anonymous.LoginWithWebAuthn(ctx, codersdk.LoginWithWebAuthnRequest{
+				ChallengeID:  login.MFA.Assertion.ChallengeID,
+				RecoveryCode: code,
+			})
+			return err
+		}
+
+		require.NoError(t, loginWithCode(resp.RecoveryCodes[0]))
+		// Codes can only be used once.
+		require.Error(t, loginWithCode(resp.RecoveryCodes[0]))
+
+		codes, err := member.RegenerateMFARecoveryCodes(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, codes.RecoveryCodes, 10)
+		// Regenerating invalidates the previous codes.
+		require.Error(t, loginWithCode(resp.RecoveryCodes[1]))
+		require.NoError(t, loginWithCode(codes.RecoveryCodes[1]))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+		_, anonymous, member, email, authenticator := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		resp := register(t, member, authenticator)
+
+		err := member.DeleteWebAuthnCredential(ctx, codersdk.Me, resp.Credential.ID)
+		require.NoError(t, err)
+
+		login, err := anonymous.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    email,
+			Password: password,
+		})
+		require.NoError(t, err)
+		require.Nil(t, login.MFA)
+		require.NotEmpty(t, login.SessionToken)
+
+		_, err = member.RegenerateMFARecoveryCodes(ctx, codersdk.Me)
+		require.Error(t, err)
+	})
+
+	t.Run("OnlySelf", func(t *testing.T) {
+		t.Parallel()
+		owner, _, member, _, _ := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		_, err = owner.WebAuthnCreationOptions(ctx, memberUser.ID.String())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Enrollment", func(t *testing.T) {
+		t.Parallel()
+		owner, anonymous, member, email, authenticator := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		settings, err := owner.PatchOrganizationMFASettings(ctx, memberUser.OrganizationIDs[0], codersdk.MFASettings{
+			RequireWebAuthn: true,
+		})
+		require.NoError(t, err)
+		require.True(t, settings.RequireWebAuthn)
+
+		login, err := anonymous.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    email,
+			Password: password,
+		})
+		require.NoError(t, err)
+		require.Empty(t, login.SessionToken)
+		require.NotNil(t, login.MFA)
+		require.Nil(t, login.MFA.Assertion)
+		require.NotNil(t, login.MFA.Enrollment)
+
+		login, err = anonymous.LoginWithWebAuthnEnrollment(ctx, codersdk.CreateWebAuthnCredentialRequest{
+			ChallengeID: login.MFA.Enrollment.ChallengeID,
+			Name:        "YubiKey",
+			Attestation: authenticator.Register(t, *login.MFA.Enrollment),
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, login.SessionToken)
+		require.Len(t, login.RecoveryCodes, 10)
+
+		// The next login asks for the enrolled credential.
+		login, err = anonymous.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    email,
+			Password: password,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, login.MFA)
+		require.NotNil(t, login.MFA.Assertion)
+	})
+}
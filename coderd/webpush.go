@@ -28,7 +28,7 @@ import (
 func (api *API) postUserWebpushSubscription(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	user := httpmw.UserParam(r)
-	if !api.Experiments.Enabled(codersdk.ExperimentWebPush) {
+	if !api.ExperimentFlags.EnabledFor(ctx, codersdk.ExperimentWebPush) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -77,7 +77,7 @@ func (api *API) deleteUserWebpushSubscription(rw http.ResponseWriter, r *http.Re
 	ctx := r.Context()
 	user := httpmw.UserParam(r)
 
-	if !api.Experiments.Enabled(codersdk.ExperimentWebPush) {
+	if !api.ExperimentFlags.EnabledFor(ctx, codersdk.ExperimentWebPush) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -134,7 +134,7 @@ func (api *API) postUserPushNotificationTest(rw http.ResponseWriter, r *http.Req
 	ctx := r.Context()
 	user := httpmw.UserParam(r)
 
-	if !api.Experiments.Enabled(codersdk.ExperimentWebPush) {
+	if !api.ExperimentFlags.EnabledFor(ctx, codersdk.ExperimentWebPush) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
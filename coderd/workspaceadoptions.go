@@ -0,0 +1,427 @@
+package coderd
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/telemetry"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/cryptorand"
+	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+const (
+	// defaultWorkspaceAdoptionTTL is how long adoption tokens can be used
+	// unless the request says otherwise.
+	defaultWorkspaceAdoptionTTL = time.Hour
+	// maxWorkspaceAdoptionTTL bounds how long adoption tokens can be used.
+	maxWorkspaceAdoptionTTL = 7 * 24 * time.Hour
+
+	// adoptedResourceType is the type of the resource that represents an
+	// adopted machine.
+	adoptedResourceType = "coder_adopted_machine"
+	// adoptedAgentName is the name of the agent of an adopted machine.
+	adoptedAgentName = "main"
+)
+
+var errWorkspaceAdoptionUsed = xerrors.New("workspace adoption token already used")
+
+// @Summary Create workspace adoption token
+// @Description Creates a one-time token that adopts a machine which is not
+// @Description managed by a provisioner as a workspace owned by the user.
+// @Description The machine exchanges the token with `coder agent adopt`.
+// @ID create-workspace-adoption-token
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param user path string true "Username, UUID, or me"
+// @Param request body codersdk.CreateWorkspaceAdoptionRequest true "Create workspace adoption request"
+// @Success 201 {object} codersdk.WorkspaceAdoptionToken
+// @Router /users/{user}/workspace-adoptions [post]
+func (api *API) postUserWorkspaceAdoption(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+		mems   = httpmw.OrganizationMembersParam(r)
+	)
+
+	var req codersdk.CreateWorkspaceAdoptionRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	ttl := defaultWorkspaceAdoptionTTL
+	if req.TTLMillis != 0 {
+		ttl = time.Duration(req.TTLMillis) * time.Millisecond
+	}
+	if ttl <= 0 || ttl > maxWorkspaceAdoptionTTL {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid adoption token lifetime.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "ttl_ms",
+				Detail: fmt.Sprintf("must be positive and at most %s", maxWorkspaceAdoptionTTL),
+			}},
+		})
+		return
+	}
+
+	template, ok := requestTemplate(ctx, rw, codersdk.CreateWorkspaceRequest{TemplateID: req.TemplateID}, api.Database)
+	if !ok {
+		return
+	}
+
+	// The token creates a workspace, so it follows the same authorization as
+	// postUserWorkspaces.
+	ownerID := uuid.Nil
+	if mems.User != nil {
+		ownerID = mems.User.ID
+	} else {
+		orgIndex := slices.IndexFunc(mems.Memberships, func(mem httpmw.OrganizationMember) bool {
+			return mem.OrganizationID == template.OrganizationID
+		})
+		if orgIndex == -1 {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		ownerID = mems.Memberships[orgIndex].UserID
+	}
+	if !api.Authorize(r, policy.ActionCreate,
+		rbac.ResourceWorkspace.InOrg(template.OrganizationID).WithOwner(ownerID.String())) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if !api.Authorize(r, policy.ActionUse, template) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Unauthorized access to use the template %q.", template.Name),
+		})
+		return
+	}
+
+	if !followsNamePolicy(ctx, rw, &api.DeploymentValues.Naming.WorkspaceName, "Workspace name", "name", req.Name) {
+		return
+	}
+
+	_, err := api.Database.GetWorkspaceByOwnerIDAndName(ctx, database.GetWorkspaceByOwnerIDAndNameParams{
+		OwnerID: ownerID,
+		Name:    req.Name,
+	})
+	if err == nil {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Workspace %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: fmt.Sprintf("Internal error fetching workspace by name %q.", req.Name),
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	secret, err := cryptorand.HexString(64)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	hashedSecret := sha256.Sum256([]byte(secret))
+	now := dbtime.Now()
+	adoption, err := api.Database.InsertWorkspaceAdoption(ctx, database.InsertWorkspaceAdoptionParams{
+		ID:             uuid.New(),
+		OrganizationID: template.OrganizationID,
+		OwnerID:        ownerID,
+		TemplateID:     template.ID,
+		WorkspaceName:  req.Name,
+		HashedSecret:   hashedSecret[:],
+		CreatedBy:      apiKey.UserID,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(ttl),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.WorkspaceAdoptionToken{
+		ID:        adoption.ID,
+		Token:     fmt.Sprintf("%s:%s", adoption.ID, secret),
+		ExpiresAt: adoption.ExpiresAt,
+	})
+}
+
+// postWorkspaceAgentAdopt exchanges an adoption token for a workspace bound
+// to the machine that made the request. The workspace gets a build with an
+// already completed job and a single agent, because no provisioner manages
+// the machine.
+//
+// @Summary Adopt machine as workspace
+// @ID adopt-machine-as-workspace
+// @Accept json
+// @Produce json
+// @Tags Agents
+// @Param request body agentsdk.AdoptWorkspaceRequest true "Adopt workspace request"
+// @Success 201 {object} agentsdk.AdoptWorkspaceResponse
+// @Router /workspaceagents/adopt [post]
+func (api *API) postWorkspaceAgentAdopt(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req agentsdk.AdoptWorkspaceRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	invalidToken := func() {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Invalid workspace adoption token.",
+		})
+	}
+	rawID, secret, ok := strings.Cut(req.Token, ":")
+	if !ok {
+		invalidToken()
+		return
+	}
+	adoptionID, err := uuid.Parse(rawID)
+	if err != nil {
+		invalidToken()
+		return
+	}
+
+	// The machine has no credentials other than the adoption token.
+	//nolint:gocritic // Adopting a machine is authorized by the token.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	adoption, err := api.Database.GetWorkspaceAdoptionByID(ctx, adoptionID)
+	if httpapi.Is404Error(err) {
+		invalidToken()
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	hashedSecret := sha256.Sum256([]byte(secret))
+	if subtle.ConstantTimeCompare(adoption.HashedSecret, hashedSecret[:]) != 1 {
+		invalidToken()
+		return
+	}
+	if adoption.WorkspaceID.Valid || !dbtime.Now().Before(adoption.ExpiresAt) {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "The workspace adoption token was already used or expired.",
+		})
+		return
+	}
+
+	template, err := api.Database.GetTemplateByID(ctx, adoption.TemplateID)
+	if err != nil {
+		httpapi.InternalServerError(rw, xerrors.Errorf("get template: %w", err))
+		return
+	}
+	if template.Deleted {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Template %q has been deleted.", template.Name),
+		})
+		return
+	}
+	templateVersion, err := api.Database.GetTemplateVersionByID(ctx, template.ActiveVersionID)
+	if err != nil {
+		httpapi.InternalServerError(rw, xerrors.Errorf("get template version: %w", err))
+		return
+	}
+	templateVersionJob, err := api.Database.GetProvisionerJobByID(ctx, templateVersion.JobID)
+	if err != nil {
+		httpapi.InternalServerError(rw, xerrors.Errorf("get template version job: %w", err))
+		return
+	}
+
+	hostname := req.Hostname
+	if hostname == "" {
+		hostname = "machine"
+	}
+	agentToken := uuid.New()
+	var (
+		workspace database.WorkspaceTable
+		agent     database.WorkspaceAgent
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		now := dbtime.Now()
+		workspace, err = tx.InsertWorkspace(ctx, database.InsertWorkspaceParams{
+			ID:               uuid.New(),
+			CreatedAt:        now,
+			UpdatedAt:        now,
+			OwnerID:          adoption.OwnerID,
+			OrganizationID:   adoption.OrganizationID,
+			TemplateID:       adoption.TemplateID,
+			Name:             adoption.WorkspaceName,
+			LastUsedAt:       now,
+			AutomaticUpdates: database.AutomaticUpdatesNever,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert workspace: %w", err)
+		}
+		_, err = tx.UpdateWorkspaceAdoptionAdopted(ctx, database.UpdateWorkspaceAdoptionAdoptedParams{
+			ID:          adoption.ID,
+			WorkspaceID: uuid.NullUUID{UUID: workspace.ID, Valid: true},
+			Hostname:    req.Hostname,
+			AdoptedAt:   sql.NullTime{Time: now, Valid: true},
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return errWorkspaceAdoptionUsed
+		}
+		if err != nil {
+			return xerrors.Errorf("mark adoption adopted: %w", err)
+		}
+
+		buildID := uuid.New()
+		input, err := json.Marshal(provisionerdserver.WorkspaceProvisionJob{
+			WorkspaceBuildID: buildID,
+		})
+		if err != nil {
+			return xerrors.Errorf("marshal provision job: %w", err)
+		}
+		// The job is completed as soon as it is inserted, so no provisioner
+		// ever acquires it.
+		job, err := tx.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+			ID:             uuid.New(),
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			OrganizationID: adoption.OrganizationID,
+			InitiatorID:    adoption.CreatedBy,
+			Provisioner:    templateVersionJob.Provisioner,
+			StorageMethod:  templateVersionJob.StorageMethod,
+			FileID:         templateVersionJob.FileID,
+			Type:           database.ProvisionerJobTypeWorkspaceBuild,
+			Input:          input,
+			Tags:           templateVersionJob.Tags,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert provisioner job: %w", err)
+		}
+		err = tx.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
+			ID:          job.ID,
+			UpdatedAt:   now,
+			CompletedAt: sql.NullTime{Time: now, Valid: true},
+		})
+		if err != nil {
+			return xerrors.Errorf("complete provisioner job: %w", err)
+		}
+		err = provisionerdserver.InsertWorkspaceResource(ctx, tx, job.ID, database.WorkspaceTransitionStart, &sdkproto.Resource{
+			Name: hostname,
+			Type: adoptedResourceType,
+			Agents: []*sdkproto.Agent{{
+				Name:            adoptedAgentName,
+				Auth:            &sdkproto.Agent_Token{Token: agentToken.String()},
+				OperatingSystem: req.OperatingSystem,
+				Architecture:    req.Architecture,
+				DisplayApps: &sdkproto.DisplayApps{
+					Vscode:               true,
+					WebTerminal:          true,
+					SshHelper:            true,
+					PortForwardingHelper: true,
+				},
+			}},
+		}, &telemetry.Snapshot{})
+		if err != nil {
+			return xerrors.Errorf("insert workspace resource: %w", err)
+		}
+		// Adopted workspaces have no deadline, the machine is not stopped by
+		// Coder.
+		err = tx.InsertWorkspaceBuild(ctx, database.InsertWorkspaceBuildParams{
+			ID:                buildID,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+			WorkspaceID:       workspace.ID,
+			TemplateVersionID: templateVersion.ID,
+			BuildNumber:       1,
+			Transition:        database.WorkspaceTransitionStart,
+			InitiatorID:       adoption.CreatedBy,
+			JobID:             job.ID,
+			ProvisionerState:  []byte{},
+			Reason:            database.BuildReasonInitiator,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert workspace build: %w", err)
+		}
+		row, err := tx.GetWorkspaceAgentAndLatestBuildByAuthToken(ctx, agentToken)
+		if err != nil {
+			return xerrors.Errorf("get workspace agent: %w", err)
+		}
+		agent = row.WorkspaceAgent
+		return nil
+	}, nil)
+	if errors.Is(err, errWorkspaceAdoptionUsed) {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "The workspace adoption token was already used or expired.",
+		})
+		return
+	}
+	if database.IsUniqueViolation(err, database.UniqueWorkspacesOwnerIDLowerIndex) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Workspace %q already exists.", adoption.WorkspaceName),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	api.Logger.Info(ctx, "adopted machine as workspace",
+		slog.F("workspace_id", workspace.ID),
+		slog.F("owner_id", workspace.OwnerID),
+		slog.F("hostname", req.Hostname),
+	)
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: workspace.ID,
+	})
+
+	httpapi.Write(ctx, rw, http.StatusCreated, agentsdk.AdoptWorkspaceResponse{
+		WorkspaceID:   workspace.ID,
+		WorkspaceName: workspace.Name,
+		AgentID:       agent.ID,
+		SessionToken:  agentToken.String(),
+	})
+}
+
+// isAdoptedWorkspace returns whether the workspace is bound to a machine that
+// was adopted instead of provisioned.
+func isAdoptedWorkspace(ctx context.Context, db database.Store, workspaceID uuid.UUID) (bool, error) {
+	_, err := db.GetWorkspaceAdoptionByWorkspaceID(ctx, uuid.NullUUID{UUID: workspaceID, Valid: true})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,128 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAdoption(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, *codersdk.Client, codersdk.Template) {
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		return client, member, template
+	}
+
+	adopt := func(t *testing.T, client *codersdk.Client, token string) (agentsdk.AdoptWorkspaceResponse, error) {
+		ctx := testutil.Context(t, testutil.WaitLong)
+		return agentsdk.New(client.URL).AdoptWorkspace(ctx, agentsdk.AdoptWorkspaceRequest{
+			Token:           token,
+			Hostname:        "build-box",
+			OperatingSystem: "linux",
+			Architecture:    "amd64",
+		})
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		client, member, template := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		token, err := member.CreateWorkspaceAdoption(ctx, codersdk.Me, codersdk.CreateWorkspaceAdoptionRequest{
+			TemplateID: template.ID,
+			Name:       "adopted",
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, token.Token)
+
+		resp, err := adopt(t, client, token.Token)
+		require.NoError(t, err)
+		require.Equal(t, "adopted", resp.WorkspaceName)
+		require.NotEmpty(t, resp.SessionToken)
+
+		workspace, err := member.Workspace(ctx, resp.WorkspaceID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, workspace.LatestBuild.Status)
+		require.Len(t, workspace.LatestBuild.Resources, 1)
+		require.Equal(t, "build-box", workspace.LatestBuild.Resources[0].Name)
+		require.Len(t, workspace.LatestBuild.Resources[0].Agents, 1)
+		require.Equal(t, resp.AgentID, workspace.LatestBuild.Resources[0].Agents[0].ID)
+
+		// The token can only be used once.
+		_, err = adopt(t, client, token.Token)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("OnlyDelete", func(t *testing.T) {
+		t.Parallel()
+		client, member, template := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		token, err := member.CreateWorkspaceAdoption(ctx, codersdk.Me, codersdk.CreateWorkspaceAdoptionRequest{
+			TemplateID: template.ID,
+			Name:       "adopted",
+		})
+		require.NoError(t, err)
+		resp, err := adopt(t, client, token.Token)
+		require.NoError(t, err)
+
+		_, err = member.CreateWorkspaceBuild(ctx, resp.WorkspaceID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		build, err := member.CreateWorkspaceBuild(ctx, resp.WorkspaceID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+	})
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		t.Parallel()
+		client, member, template := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		token, err := member.CreateWorkspaceAdoption(ctx, codersdk.Me, codersdk.CreateWorkspaceAdoptionRequest{
+			TemplateID: template.ID,
+			Name:       "adopted",
+		})
+		require.NoError(t, err)
+
+		_, err = adopt(t, client, token.ID.String()+":wrong")
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("NameTaken", func(t *testing.T) {
+		t.Parallel()
+		_, member, template := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		_, err := member.CreateWorkspaceAdoption(ctx, codersdk.Me, codersdk.CreateWorkspaceAdoptionRequest{
+			TemplateID: template.ID,
+			Name:       workspace.Name,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,22 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get web desktop settings for workspace agent
+// @ID get-web-desktop-settings-for-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceAgentDesktop
+// @Router /workspaceagents/{workspaceagent}/desktop [get]
+func (api *API) workspaceAgentDesktop(rw http.ResponseWriter, r *http.Request) {
+	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.WorkspaceAgentDesktop{
+		ClipboardPolicy: codersdk.DesktopClipboardPolicy(api.DeploymentValues.WebDesktopClipboard),
+	})
+}
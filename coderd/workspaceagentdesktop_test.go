@@ -0,0 +1,83 @@
+package coderd_test
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/websocket"
+)
+
+func TestWorkspaceAgentDesktop(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.WebDesktopClipboard = string(codersdk.DesktopClipboardPolicyToWorkspace)
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+		DeploymentValues: dv,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+	_ = agenttest.New(t, client.URL, r.AgentToken)
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, r.Workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	desktop, err := client.WorkspaceAgentDesktop(ctx, agentID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.DesktopClipboardPolicyToWorkspace, desktop.ClipboardPolicy)
+
+	// Emulate the VNC server of the workspace, which greets clients with
+	// its protocol version.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = c.Write([]byte("RFB 003.008\n"))
+			_ = c.Close()
+		}
+	}()
+	tcpAddr, ok := l.Addr().(*net.TCPAddr)
+	require.True(t, ok)
+
+	u, err := client.URL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/desktop/vnc?port=%d", agentID, tcpAddr.Port))
+	require.NoError(t, err)
+	// nolint:bodyclose
+	wsConn, res, err := websocket.Dial(ctx, u.String(), &websocket.DialOptions{
+		HTTPHeader: http.Header{
+			codersdk.SessionTokenHeader: []string{client.SessionToken()},
+		},
+		Subprotocols: []string{"binary"},
+	})
+	if err != nil {
+		if res != nil && res.StatusCode != http.StatusSwitchingProtocols {
+			err = codersdk.ReadBodyAsError(res)
+		}
+		require.NoError(t, err)
+	}
+	defer wsConn.Close(websocket.StatusNormalClosure, "done")
+	require.Equal(t, "binary", wsConn.Subprotocol())
+
+	typ, msg, err := wsConn.Read(ctx)
+	require.NoError(t, err)
+	require.Equal(t, websocket.MessageBinary, typ)
+	require.Equal(t, "RFB 003.008\n", string(msg))
+}
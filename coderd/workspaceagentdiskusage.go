@@ -0,0 +1,66 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get disk usage for workspace agent
+// @ID get-disk-usage-for-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceAgentDiskUsage
+// @Router /workspaceagents/{workspaceagent}/disk-usage [get]
+func (api *API) workspaceAgentDiskUsage(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	usage, err := api.Database.GetWorkspaceAgentVolumeUsageByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching volume usage.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	monitors, err := api.Database.FetchVolumesResourceMonitorsByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching volume monitors.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceAgentDiskUsage(usage, monitors))
+}
+
+func convertWorkspaceAgentDiskUsage(usage []database.WorkspaceAgentVolumeUsage, monitors []database.WorkspaceAgentVolumeResourceMonitor) codersdk.WorkspaceAgentDiskUsage {
+	monitorsByPath := make(map[string]database.WorkspaceAgentVolumeResourceMonitor, len(monitors))
+	for _, monitor := range monitors {
+		monitorsByPath[monitor.Path] = monitor
+	}
+
+	volumes := make([]codersdk.WorkspaceAgentVolume, 0, len(usage))
+	for _, volume := range usage {
+		converted := codersdk.WorkspaceAgentVolume{
+			Path:        volume.Path,
+			TotalBytes:  volume.TotalBytes,
+			UsedBytes:   volume.UsedBytes,
+			CollectedAt: volume.CollectedAt,
+		}
+		if monitor, ok := monitorsByPath[volume.Path]; ok && monitor.Enabled {
+			threshold := monitor.Threshold
+			converted.Threshold = &threshold
+			converted.OverThreshold = monitor.State == database.WorkspaceAgentMonitorStateNOK
+		}
+		volumes = append(volumes, converted)
+	}
+	return codersdk.WorkspaceAgentDiskUsage{Volumes: volumes}
+}
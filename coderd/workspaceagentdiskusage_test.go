@@ -0,0 +1,62 @@
+package coderd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAgentDiskUsage(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        member.ID,
+	}).WithAgent().Do()
+	ctx := testutil.Context(t, testutil.WaitShort)
+	workspace, err := memberClient.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	agentID := workspace.LatestBuild.Resources[0].Agents[0].ID
+
+	// The home directory isn't monitored, but the Docker volume is.
+	dbgen.WorkspaceAgentVolumeResourceMonitor(t, db, database.WorkspaceAgentVolumeResourceMonitor{
+		AgentID:   agentID,
+		Path:      "/var/lib/docker",
+		Enabled:   true,
+		Threshold: 80,
+		State:     database.WorkspaceAgentMonitorStateNOK,
+	})
+	for _, volume := range []database.UpsertWorkspaceAgentVolumeUsageParams{
+		{AgentID: agentID, Path: "/home/coder", TotalBytes: 100, UsedBytes: 25, CollectedAt: dbtime.Now()},
+		{AgentID: agentID, Path: "/var/lib/docker", TotalBytes: 100, UsedBytes: 90, CollectedAt: dbtime.Now()},
+	} {
+		require.NoError(t, db.UpsertWorkspaceAgentVolumeUsage(ctx, volume))
+	}
+
+	usage, err := memberClient.WorkspaceAgentDiskUsage(ctx, agentID)
+	require.NoError(t, err)
+	require.Len(t, usage.Volumes, 2)
+
+	home := usage.Volumes[0]
+	require.Equal(t, "/home/coder", home.Path)
+	require.EqualValues(t, 25, home.UsedBytes)
+	require.EqualValues(t, 100, home.TotalBytes)
+	require.Nil(t, home.Threshold)
+	require.False(t, home.OverThreshold)
+
+	docker := usage.Volumes[1]
+	require.Equal(t, "/var/lib/docker", docker.Path)
+	require.NotNil(t, docker.Threshold)
+	require.EqualValues(t, 80, *docker.Threshold)
+	require.True(t, docker.OverThreshold)
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	maputil "github.com/coder/coder/v2/coderd/util/maps"
+	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/wspubsub"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
@@ -142,6 +143,15 @@ func (api *API) workspaceAgent(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	restrictions, err := workspaceapps.GetAppRestrictions(ctx, api.Database, []uuid.UUID{workspace.TemplateID})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace app restrictions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	dbApps = restrictions.FilterApps(ctx, workspace.TemplateID, workspace.OrganizationID, dbApps)
 
 	apiAgent, err := db2sdk.WorkspaceAgent(
 		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, db2sdk.Apps(dbApps, statuses, workspaceAgent, owner.Username, workspace), convertScripts(scripts), convertLogSources(logSources), api.AgentInactiveDisconnectTimeout,
@@ -995,12 +1005,31 @@ func (api *API) workspaceAgentRecreateDevcontainer(rw http.ResponseWriter, r *ht
 // @Router /workspaceagents/{workspaceagent}/connection [get]
 func (api *API) workspaceAgentConnection(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	// The caller can read the workspace, but may not be able to read the
+	// template it was created from.
+	// nolint:gocritic // This is necessary to fetch the template's WireGuard settings!
+	template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	mtu, keepaliveInterval, handshakeTimeout := db2sdk.WireguardSettings(api.DeploymentValues.Wireguard, template)
 
 	httpapi.Write(ctx, rw, http.StatusOK, workspacesdk.AgentConnectionInfo{
-		DERPMap:                  api.DERPMap(),
-		DERPForceWebSockets:      api.DeploymentValues.DERP.Config.ForceWebSockets.Value(),
-		DisableDirectConnections: api.DeploymentValues.DERP.Config.BlockDirect.Value(),
-		HostnameSuffix:           api.DeploymentValues.WorkspaceHostnameSuffix.Value(),
+		DERPMap:                    api.DERPMap(),
+		DERPForceWebSockets:        api.DeploymentValues.DERP.Config.ForceWebSockets.Value(),
+		DisableDirectConnections:   api.DeploymentValues.DERP.Config.BlockDirect.Value(),
+		HTTPSFallback:              api.DeploymentValues.DERP.Config.HTTPSFallback.Value(),
+		NetcheckInterval:           api.DeploymentValues.DERP.Config.NetcheckInterval.Value(),
+		WireguardMTU:               mtu,
+		WireguardKeepaliveInterval: keepaliveInterval,
+		WireguardHandshakeTimeout:  handshakeTimeout,
+		HostnameSuffix:             api.DeploymentValues.WorkspaceHostnameSuffix.Value(),
 	})
 }
 
@@ -1018,11 +1047,18 @@ func (api *API) workspaceAgentConnection(rw http.ResponseWriter, r *http.Request
 func (api *API) workspaceAgentConnectionGeneric(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	// Without an agent there's no template, so only the deployment's
+	// WireGuard settings apply.
 	httpapi.Write(ctx, rw, http.StatusOK, workspacesdk.AgentConnectionInfo{
-		DERPMap:                  api.DERPMap(),
-		DERPForceWebSockets:      api.DeploymentValues.DERP.Config.ForceWebSockets.Value(),
-		DisableDirectConnections: api.DeploymentValues.DERP.Config.BlockDirect.Value(),
-		HostnameSuffix:           api.DeploymentValues.WorkspaceHostnameSuffix.Value(),
+		DERPMap:                    api.DERPMap(),
+		DERPForceWebSockets:        api.DeploymentValues.DERP.Config.ForceWebSockets.Value(),
+		DisableDirectConnections:   api.DeploymentValues.DERP.Config.BlockDirect.Value(),
+		HTTPSFallback:              api.DeploymentValues.DERP.Config.HTTPSFallback.Value(),
+		NetcheckInterval:           api.DeploymentValues.DERP.Config.NetcheckInterval.Value(),
+		WireguardMTU:               int(api.DeploymentValues.Wireguard.MTU.Value()),
+		WireguardKeepaliveInterval: api.DeploymentValues.Wireguard.KeepaliveInterval.Value(),
+		WireguardHandshakeTimeout:  api.DeploymentValues.Wireguard.HandshakeTimeout.Value(),
+		HostnameSuffix:             api.DeploymentValues.WorkspaceHostnameSuffix.Value(),
 	})
 }
 
@@ -1145,6 +1181,11 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 		}
 	}
 
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+	if api.redirectToCoordinatorShard(rw, r, workspaceAgent.ID) {
+		return
+	}
+
 	version := "1.0"
 	qv := r.URL.Query().Get("version")
 	if qv != "" {
@@ -1170,7 +1211,6 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 	api.WebsocketWaitGroup.Add(1)
 	api.WebsocketWaitMutex.Unlock()
 	defer api.WebsocketWaitGroup.Done()
-	workspaceAgent := httpmw.WorkspaceAgentParam(r)
 
 	conn, err := websocket.Accept(rw, r, nil)
 	if err != nil {
@@ -1199,6 +1239,17 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 	}
 }
 
+// redirectToCoordinatorShard redirects the request to the replica that
+// coordinates the given agent, if Enterprise code has enabled sharding. It
+// returns true if a response has been written.
+func (api *API) redirectToCoordinatorShard(rw http.ResponseWriter, r *http.Request, agentID uuid.UUID) bool {
+	redirect := api.TailnetCoordinatorRedirect.Load()
+	if redirect == nil || *redirect == nil {
+		return false
+	}
+	return (*redirect)(rw, r, agentID)
+}
+
 // handleResumeToken accepts a resume_token query parameter to use the same peer ID
 func (api *API) handleResumeToken(ctx context.Context, rw http.ResponseWriter, r *http.Request) (peerID uuid.UUID, err error) {
 	peerID = uuid.New()
@@ -1671,7 +1722,13 @@ func (api *API) workspaceAgentsExternalAuth(rw http.ResponseWriter, r *http.Requ
 	// new token to be issued!
 	listen := r.URL.Query().Has("listen")
 
-	var externalAuthConfig *externalauth.Config
+	var (
+		externalAuthConfig *externalauth.Config
+		// matched contains every provider with a regex that matches the URL.
+		// Multiple providers can be configured for the same host with
+		// different scopes, in which case the template decides which is used.
+		matched []*externalauth.Config
+	)
 	for _, extAuth := range api.ExternalAuthConfigs {
 		if extAuth.ID == id {
 			externalAuthConfig = extAuth
@@ -1684,6 +1741,7 @@ func (api *API) workspaceAgentsExternalAuth(rw http.ResponseWriter, r *http.Requ
 		if !matches {
 			continue
 		}
+		matched = append(matched, extAuth)
 		externalAuthConfig = extAuth
 	}
 	if externalAuthConfig == nil {
@@ -1730,6 +1788,36 @@ func (api *API) workspaceAgentsExternalAuth(rw http.ResponseWriter, r *http.Requ
 		})
 		return
 	}
+	if len(matched) > 1 {
+		templateVersion, err := api.Database.GetTemplateVersionByID(ctx, build.TemplateVersionID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to get template version.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		var providers []database.ExternalAuthProvider
+		err = json.Unmarshal(templateVersion.ExternalAuthProviders, &providers)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to parse template version external auth providers.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		// Prefer the provider required by the template, so templates can
+		// narrow the scopes of the token used in the workspace.
+	matchedProviders:
+		for _, extAuth := range matched {
+			for _, provider := range providers {
+				if provider.ID == extAuth.ID {
+					externalAuthConfig = extAuth
+					break matchedProviders
+				}
+			}
+		}
+	}
 
 	// Pre-check if the caller can read the external auth links for the owner of the
 	// workspace. Do this up front because a sql.ErrNoRows is expected if the user is
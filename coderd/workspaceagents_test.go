@@ -2622,7 +2622,7 @@ func requireGetManifest(ctx context.Context, t testing.TB, aAPI agentproto.DRPCA
 }
 
 func postStartup(ctx context.Context, t testing.TB, client agent.Client, startup *agentproto.Startup) error {
-	aAPI, _, err := client.ConnectRPC26(ctx)
+	aAPI, _, err := client.ConnectRPC27(ctx)
 	require.NoError(t, err)
 	defer func() {
 		cErr := aAPI.DRPCConn().Close()
@@ -2760,6 +2760,11 @@ func (f *fakeTelemetryReporter) Enabled() bool {
 	return f.enabled
 }
 
+// RecentPayloads implements the telemetry.Reporter interface.
+func (*fakeTelemetryReporter) RecentPayloads() []codersdk.TelemetryPayload {
+	return nil
+}
+
 // Close implements the telemetry.Reporter interface.
 func (*fakeTelemetryReporter) Close() {}
 
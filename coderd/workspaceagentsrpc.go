@@ -60,11 +60,15 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+	if api.redirectToCoordinatorShard(rw, r, workspaceAgent.ID) {
+		return
+	}
+
 	api.WebsocketWaitMutex.Lock()
 	api.WebsocketWaitGroup.Add(1)
 	api.WebsocketWaitMutex.Unlock()
 	defer api.WebsocketWaitGroup.Done()
-	workspaceAgent := httpmw.WorkspaceAgent(r)
 	build := httpmw.LatestBuild(r)
 
 	workspace, err := api.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
@@ -82,6 +86,18 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 		slog.F("agent_name", workspaceAgent.Name),
 	)
 
+	agentVersion := r.Header.Get(codersdk.BuildVersionHeader)
+	if agentVersion == "" {
+		// Agents that don't send their version reported it when they last
+		// started.
+		agentVersion = workspaceAgent.Version
+	}
+	if resp := api.checkAgentVersion(agentVersion); resp != nil {
+		logger.Warn(ctx, "rejected outdated agent", slog.F("agent_version", agentVersion))
+		httpapi.Write(ctx, rw, http.StatusUpgradeRequired, *resp)
+		return
+	}
+
 	conn, err := websocket.Accept(rw, r, nil)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -147,15 +163,18 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 		PublishWorkspaceUpdateFn:          api.publishWorkspaceUpdate,
 		PublishWorkspaceAgentLogsUpdateFn: api.publishWorkspaceAgentLogsUpdate,
 		NetworkTelemetryHandler:           api.NetworkTelemetryBatcher.Handler,
+		TracerProvider:                    api.TracerProvider,
 
 		AccessURL:                 api.AccessURL,
 		AppHostname:               api.AppHostname,
 		AgentStatsRefreshInterval: api.AgentStatsRefreshInterval,
 		DisableDirectConnections:  api.DeploymentValues.DERP.Config.BlockDirect.Value(),
 		DerpForceWebSockets:       api.DeploymentValues.DERP.Config.ForceWebSockets.Value(),
+		NetcheckInterval:          api.DeploymentValues.DERP.Config.NetcheckInterval.Value(),
+		Wireguard:                 api.DeploymentValues.Wireguard,
 		DerpMapUpdateFrequency:    api.Options.DERPMapUpdateFrequency,
 		ExternalAuthConfigs:       api.ExternalAuthConfigs,
-		Experiments:               api.Experiments,
+		Experiments:               api.ExperimentFlags,
 
 		// Optional:
 		UpdateAgentMetricsFn: api.UpdateAgentMetrics,
@@ -177,6 +196,8 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 }
 
 func (api *API) handleNetworkTelemetry(batch []*tailnetproto.TelemetryEvent) {
+	api.connectionQuality.Handle(api.ctx, batch)
+
 	var (
 		telemetryEvents = make([]telemetry.NetworkEvent, 0, len(batch))
 		didLogErr       = false
@@ -0,0 +1,119 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// workspaceAgentTokenRotation returns when the agent should rotate its
+// token next.
+//
+// @Summary Get workspace agent token rotation
+// @ID get-workspace-agent-token-rotation
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Success 200 {object} agentsdk.TokenRotation
+// @Router /workspaceagents/me/token-rotation [get]
+func (api *API) workspaceAgentTokenRotation(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	interval := api.DeploymentValues.AgentTokenRotationInterval.Value()
+	if interval <= 0 {
+		httpapi.Write(ctx, rw, http.StatusOK, agentsdk.TokenRotation{})
+		return
+	}
+
+	// Agents that never rotated their token rotate it one interval after
+	// they were created.
+	lastRotatedAt := workspaceAgent.CreatedAt
+	rotation, err := api.Database.GetWorkspaceAgentTokenRotationByAgentID(ctx, workspaceAgent.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching agent token rotation.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err == nil {
+		lastRotatedAt = rotation.RotatedAt
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.TokenRotation{
+		Enabled:        true,
+		NextRotationAt: lastRotatedAt.Add(interval),
+	})
+}
+
+// workspaceAgentRotateToken replaces the token of the agent. The token used
+// to make the request keeps working for the grace period so that requests in
+// flight are not rejected. Requests made with a token that was already
+// rotated are refused, otherwise a leaked token could be rotated forever.
+//
+// @Summary Rotate workspace agent token
+// @ID rotate-workspace-agent-token
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Success 200 {object} agentsdk.RotateTokenResponse
+// @Router /workspaceagents/me/token-rotation [post]
+func (api *API) workspaceAgentRotateToken(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	interval := api.DeploymentValues.AgentTokenRotationInterval.Value()
+	if interval <= 0 {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Agent token rotation is not enabled.",
+		})
+		return
+	}
+
+	token, err := uuid.Parse(httpmw.APITokenFromRequest(r))
+	if err != nil || token != workspaceAgent.AuthToken {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The agent token has already been rotated.",
+		})
+		return
+	}
+
+	newToken := uuid.New()
+	now := dbtime.Now()
+	rotation, err := api.Database.RotateWorkspaceAgentAuthToken(ctx, database.RotateWorkspaceAgentAuthTokenParams{
+		NewAuthToken:               newToken,
+		AgentID:                    workspaceAgent.ID,
+		PreviousAuthToken:          workspaceAgent.AuthToken,
+		PreviousAuthTokenExpiresAt: now.Add(api.DeploymentValues.AgentTokenRotationGracePeriod.Value()),
+		RotatedAt:                  now,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The agent token has already been rotated.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error rotating agent token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.RotateTokenResponse{
+		SessionToken:           newToken.String(),
+		PreviousTokenExpiresAt: rotation.PreviousAuthTokenExpiresAt,
+		NextRotationAt:         rotation.RotatedAt.Add(interval),
+	})
+}
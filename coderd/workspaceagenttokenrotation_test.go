@@ -0,0 +1,113 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/serpent"
+)
+
+func TestWorkspaceAgentTokenRotation(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T, interval, gracePeriod time.Duration) (*codersdk.Client, string) {
+		dv := coderdtest.DeploymentValues(t)
+		dv.AgentTokenRotationInterval = serpent.Duration(interval)
+		dv.AgentTokenRotationGracePeriod = serpent.Duration(gracePeriod)
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			DeploymentValues:         dv,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.PlanComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		return client, authToken
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		client, authToken := setup(t, 0, time.Hour)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+
+		rotation, err := agentClient.TokenRotation(ctx)
+		require.NoError(t, err)
+		require.False(t, rotation.Enabled)
+
+		_, err = agentClient.RotateToken(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Rotate", func(t *testing.T) {
+		t.Parallel()
+		client, authToken := setup(t, time.Hour, time.Hour)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+
+		rotation, err := agentClient.TokenRotation(ctx)
+		require.NoError(t, err)
+		require.True(t, rotation.Enabled)
+		require.WithinDuration(t, time.Now().Add(time.Hour), rotation.NextRotationAt, time.Minute)
+
+		resp, err := agentClient.RotateToken(ctx)
+		require.NoError(t, err)
+		require.NotEqual(t, authToken, resp.SessionToken)
+		require.WithinDuration(t, time.Now().Add(time.Hour), resp.PreviousTokenExpiresAt, time.Minute)
+
+		// The previous token still authenticates the agent during the
+		// grace period, but cannot be rotated again.
+		_, err = agentClient.TokenRotation(ctx)
+		require.NoError(t, err)
+		_, err = agentClient.RotateToken(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		agentClient.SetSessionToken(resp.SessionToken)
+		rotation, err = agentClient.TokenRotation(ctx)
+		require.NoError(t, err)
+		require.WithinDuration(t, resp.NextRotationAt, rotation.NextRotationAt, time.Second)
+		_, err = agentClient.RotateToken(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("GracePeriodExpired", func(t *testing.T) {
+		t.Parallel()
+		client, authToken := setup(t, time.Hour, 0)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+
+		_, err := agentClient.RotateToken(ctx)
+		require.NoError(t, err)
+
+		_, err = agentClient.TokenRotation(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+}
@@ -80,6 +80,7 @@ func (api *API) workspaceApplicationAuth(rw http.ResponseWriter, r *http.Request
 		// Allow all hosts except primary access URL since we don't need app
 		// tokens on the primary dashboard URL.
 		AllowPrimaryAccessURL: false,
+		AllowPathAppAccessURL: true,
 		AllowPrimaryWildcard:  true,
 		AllowProxyAccessURL:   true,
 		AllowProxyWildcard:    true,
@@ -94,13 +95,14 @@ func (api *API) workspaceApplicationAuth(rw http.ResponseWriter, r *http.Request
 	if u.Scheme == "" {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Invalid redirect_uri.",
-			Detail:  "The redirect_uri query parameter must be the primary wildcard app hostname, a workspace proxy access URL or a workspace proxy wildcard app hostname.",
+			Detail:  "The redirect_uri query parameter must be the primary wildcard app hostname, the path app access URL, a workspace proxy access URL or a workspace proxy wildcard app hostname.",
 		})
 		return
 	}
 
 	// Create the application_connect-scoped API key with the same lifetime as
-	// the current session.
+	// the current session. It keeps the creation time of the session, so that
+	// app session policies measure the time since the user authenticated.
 	exp := apiKey.ExpiresAt
 	lifetimeSeconds := apiKey.LifetimeSeconds
 	if exp.IsZero() || time.Until(exp) > api.DeploymentValues.Sessions.DefaultDuration.Value() {
@@ -108,12 +110,15 @@ func (api *API) workspaceApplicationAuth(rw http.ResponseWriter, r *http.Request
 		lifetimeSeconds = int64(api.DeploymentValues.Sessions.DefaultDuration.Value().Seconds())
 	}
 	cookie, _, err := api.createAPIKey(ctx, apikey.CreateParams{
-		UserID:          apiKey.UserID,
-		LoginType:       database.LoginTypePassword,
-		DefaultLifetime: api.DeploymentValues.Sessions.DefaultDuration.Value(),
-		ExpiresAt:       exp,
-		LifetimeSeconds: lifetimeSeconds,
-		Scope:           database.APIKeyScopeApplicationConnect,
+		UserID:            apiKey.UserID,
+		LoginType:         database.LoginTypePassword,
+		DefaultLifetime:   api.DeploymentValues.Sessions.DefaultDuration.Value(),
+		ExpiresAt:         exp,
+		LifetimeSeconds:   lifetimeSeconds,
+		Scope:             database.APIKeyScopeApplicationConnect,
+		UserAgent:         r.UserAgent(),
+		DeviceFingerprint: apikey.DeviceFingerprint(r.Header),
+		CreatedAt:         apiKey.CreatedAt,
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -146,6 +151,7 @@ func (api *API) workspaceApplicationAuth(rw http.ResponseWriter, r *http.Request
 
 type ValidWorkspaceAppHostnameOpts struct {
 	AllowPrimaryAccessURL bool
+	AllowPathAppAccessURL bool
 	AllowPrimaryWildcard  bool
 	AllowProxyAccessURL   bool
 	AllowProxyWildcard    bool
@@ -165,6 +171,16 @@ func (api *API) ValidWorkspaceAppHostname(ctx context.Context, host string, opts
 		return api.AccessURL.Scheme, nil
 	}
 
+	if opts.AllowPathAppAccessURL && api.PathAppAccessURL != nil {
+		if host == api.PathAppAccessURL.Hostname() || host == api.PathAppAccessURL.Host {
+			return api.PathAppAccessURL.Scheme, nil
+		}
+		// The subdomain is verified by the app proxy once redirected.
+		if api.pathAppSubdomains() && workspaceapps.IsPathAppSubdomain(host, api.PathAppAccessURL) {
+			return api.PathAppAccessURL.Scheme, nil
+		}
+	}
+
 	if opts.AllowPrimaryWildcard && api.AppHostnameRegex != nil {
 		_, ok := appurl.ExecuteHostnamePattern(api.AppHostnameRegex, host)
 		if ok {
@@ -206,3 +222,40 @@ func (api *API) ValidWorkspaceAppHostname(ctx context.Context, host string, opts
 
 	return "", nil
 }
+
+// PathAppURL returns the base URL that path-based apps are served from on the
+// primary region.
+func (api *API) PathAppURL() *url.URL {
+	if api.PathAppAccessURL != nil {
+		return api.PathAppAccessURL
+	}
+	return api.AccessURL
+}
+
+// pathAppSubdomains reports whether path-based apps are served from signed
+// subdomains of the path app access URL.
+func (api *API) pathAppSubdomains() bool {
+	return api.PathAppAccessURL != nil && api.DeploymentValues.PathAppSubdomains.Value()
+}
+
+// pathAppAccessURLMW keeps the path app access URL, and its subdomains when
+// path apps are served from signed subdomains, from serving anything other
+// than path-based apps. Other requests are redirected to the access URL so the
+// dashboard and API never share an origin with workspace apps.
+func (api *API) pathAppAccessURLMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if api.PathAppAccessURL == nil ||
+			(!appurl.HostnamesMatch(r.Host, api.PathAppAccessURL.Host) &&
+				!(api.pathAppSubdomains() && workspaceapps.IsPathAppSubdomain(r.Host, api.PathAppAccessURL))) ||
+			strings.HasPrefix(r.URL.Path, "/@") ||
+			r.URL.Path == "/healthz" {
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		u := *api.AccessURL
+		u.Path = r.URL.Path
+		u.RawQuery = r.URL.RawQuery
+		http.Redirect(rw, r, u.String(), http.StatusTemporaryRedirect)
+	})
+}
@@ -47,6 +47,8 @@ type DBTokenProvider struct {
 	WorkspaceAgentInactiveTimeout   time.Duration
 	WorkspaceAppAuditSessionTimeout time.Duration
 	Keycache                        cryptokeys.SigningKeycache
+
+	shareLinkPasswords *shareLinkPasswordLimiter
 }
 
 var _ SignedTokenProvider = &DBTokenProvider{}
@@ -80,6 +82,7 @@ func NewDBTokenProvider(log slog.Logger,
 		WorkspaceAgentInactiveTimeout:   workspaceAgentInactiveTimeout,
 		WorkspaceAppAuditSessionTimeout: workspaceAppAuditSessionTimeout,
 		Keycache:                        signer,
+		shareLinkPasswords:              newShareLinkPasswordLimiter(),
 	}
 }
 
@@ -146,6 +149,38 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 
 	aReq.dbReq = dbReq // Update audit request.
 
+	// Apps with a session policy can only be accessed for some time after the
+	// user authenticated. Stale sessions are signed out, so that users are
+	// redirected to authenticate again below. API tokens that are too old are
+	// ignored instead, since there's no one to redirect.
+	var sessionExpiresAt time.Time
+	if apiKey != nil {
+		policies, err := GetAppSessionPolicies(ctx, p.Database, dbReq.Workspace.TemplateID)
+		if err != nil {
+			WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "get app session policies")
+			return nil, "", false
+		}
+		if maxAge, ok := policies.MaxSessionDuration(dbReq.App.Slug); ok {
+			sessionExpiresAt = apiKey.CreatedAt.Add(maxAge)
+			if !sessionExpiresAt.After(aReq.time) {
+				if apiKey.IsSessionOrAppKey() {
+					err = p.Database.DeleteStaleAPIKeySessionsByUserID(dangerousSystemCtx, database.DeleteStaleAPIKeySessionsByUserIDParams{
+						UserID:        apiKey.UserID,
+						CreatedBefore: aReq.time.Add(-maxAge),
+					})
+					if err != nil {
+						WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "delete stale sessions")
+						return nil, "", false
+					}
+				}
+				apiKey, authz, sessionExpiresAt = nil, nil, time.Time{}
+			} else {
+				aReq.sessionStartedAt = apiKey.CreatedAt
+				aReq.sessionExpiresAt = sessionExpiresAt
+			}
+		}
+	}
+
 	token.UserID = dbReq.User.ID
 	token.WorkspaceID = dbReq.Workspace.ID
 	token.AgentID = dbReq.Agent.ID
@@ -159,6 +194,21 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 		WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "verify authz")
 		return nil, "", false
 	}
+	// Visitors of port share links don't need to be signed in.
+	var shareLinkExpiresAt time.Time
+	if !authed && issueReq.ShareLinkToken != "" {
+		link, ok := p.authorizeShareLink(dangerousSystemCtx, aReq.time, rw, r, issueReq, dbReq)
+		if !ok {
+			return nil, "", false
+		}
+		token.ShareLinkID = link.ID
+		if link.BandwidthLimitBytes > 0 {
+			token.ShareLinkBandwidthRemaining = link.BandwidthLimitBytes - link.BytesTransferred
+		}
+		aReq.shareLinkID = link.ID
+		shareLinkExpiresAt = link.ExpiresAt
+		authed = true
+	}
 	if !authed {
 		if apiKey != nil {
 			// The request has a valid API key but insufficient permissions.
@@ -245,8 +295,17 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 		return nil, "", false
 	}
 
+	// Tokens never outlive the session the app policy allows, so that the
+	// policy is enforced again once it expires.
+	expiry := time.Now().Add(DefaultTokenExpiry)
+	if !sessionExpiresAt.IsZero() && sessionExpiresAt.Before(expiry) {
+		expiry = sessionExpiresAt
+	}
+	if !shareLinkExpiresAt.IsZero() && shareLinkExpiresAt.Before(expiry) {
+		expiry = shareLinkExpiresAt
+	}
 	token.RegisteredClaims = jwtutils.RegisteredClaims{
-		Expiry: jwt.NewNumericDate(time.Now().Add(DefaultTokenExpiry)),
+		Expiry: jwt.NewNumericDate(expiry),
 	}
 	// Sign the token.
 	tokenStr, err := jwtutils.Sign(ctx, p.Keycache, token)
@@ -289,6 +348,20 @@ func (p *DBTokenProvider) authorizeRequest(ctx context.Context, roles *rbac.Subj
 		sharingLevel = database.AppSharingLevelOwner
 	}
 
+	// Apps the template restricts to some groups and roles can't be accessed
+	// by anyone else, regardless of their sharing level. Ports don't have a
+	// slug and are never restricted.
+	if dbReq.App.Slug != "" {
+		restrictions, err := GetAppRestrictions(ctx, p.Database, []uuid.UUID{dbReq.Workspace.TemplateID})
+		if err != nil {
+			return false, warnings, err
+		}
+		if !restrictions.Allowed(roles, dbReq.Workspace.TemplateID, dbReq.Workspace.OrganizationID, dbReq.App.Slug) {
+			warnings = append(warnings, "the template restricts this app to some groups and roles")
+			return false, warnings, nil
+		}
+	}
+
 	// Short circuit if not authenticated.
 	if roles == nil {
 		// The user is not authenticated, so they can only access the app if it
@@ -390,6 +463,13 @@ type auditRequest struct {
 	time   time.Time
 	apiKey *database.APIKey
 	dbReq  *databaseRequest
+	// sessionStartedAt and sessionExpiresAt are set when a session policy
+	// limits access to the app.
+	sessionStartedAt time.Time
+	sessionExpiresAt time.Time
+	// shareLinkID is set when the request was authorized by a port share
+	// link.
+	shareLinkID uuid.UUID
 }
 
 // auditInitRequest creates a new audit session and audit log for the given
@@ -441,7 +521,9 @@ func (p *DBTokenProvider) auditInitRequest(ctx context.Context, w http.ResponseW
 
 		type additionalFields struct {
 			audit.AdditionalFields
-			SlugOrPort string `json:"slug_or_port,omitempty"`
+			SlugOrPort       string     `json:"slug_or_port,omitempty"`
+			SessionExpiresAt *time.Time `json:"session_expires_at,omitempty"`
+			ShareLinkID      *uuid.UUID `json:"share_link_id,omitempty"`
 		}
 		appInfo := additionalFields{
 			AdditionalFields: audit.AdditionalFields{
@@ -457,6 +539,12 @@ func (p *DBTokenProvider) auditInitRequest(ctx context.Context, w http.ResponseW
 			// If this isn't an app or a terminal, it's a port.
 			appInfo.SlugOrPort = aReq.dbReq.AppSlugOrPort
 		}
+		if !aReq.sessionExpiresAt.IsZero() {
+			appInfo.SessionExpiresAt = &aReq.sessionExpiresAt
+		}
+		if aReq.shareLinkID != uuid.Nil {
+			appInfo.ShareLinkID = &aReq.shareLinkID
+		}
 
 		// If we end up logging, ensure relevant fields are set.
 		logger := p.Logger.With(
@@ -477,6 +565,9 @@ func (p *DBTokenProvider) auditInitRequest(ctx context.Context, w http.ResponseW
 			newOrStale, err = tx.UpsertWorkspaceAppAuditSession(dangerousSystemCtx, database.UpsertWorkspaceAppAuditSessionParams{
 				// Config.
 				StaleIntervalMS: p.WorkspaceAppAuditSessionTimeout.Milliseconds(),
+				// Re-authenticating for apps with a session policy starts a
+				// new audit session.
+				AuthenticatedAfter: aReq.sessionStartedAt,
 
 				// Data.
 				ID:         uuid.New(),
@@ -0,0 +1,124 @@
+package workspaceapps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/websocket"
+)
+
+// workspaceAgentDesktopVNC proxies the VNC server of a workspace over a
+// WebSocket. This is used for the web desktop, which runs noVNC in the
+// browser.
+//
+// Access to the desktop is equivalent to access to the terminal, so it is
+// authorized like the PTY endpoint. Unless the clipboard policy allows both
+// directions, the RFB messages are parsed to drop the cut text the policy
+// doesn't allow.
+//
+// @Summary Open web desktop VNC stream to workspace agent
+// @ID open-web-desktop-vnc-stream-to-workspace-agent
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param port query int false "VNC port in the workspace, defaults to 5900"
+// @Success 101
+// @Router /workspaceagents/{workspaceagent}/desktop/vnc [get]
+func (s *Server) workspaceAgentDesktopVNC(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	s.websocketWaitMutex.Lock()
+	s.websocketWaitGroup.Add(1)
+	s.websocketWaitMutex.Unlock()
+	defer s.websocketWaitGroup.Done()
+
+	appToken, ok := ResolveRequest(rw, r, ResolveRequestOptions{
+		Logger:              s.Logger,
+		CookieCfg:           s.Cookies,
+		SignedTokenProvider: s.SignedTokenProvider,
+		DashboardURL:        s.DashboardURL,
+		PathAppBaseURL:      s.AccessURL,
+		AppHostname:         s.Hostname,
+		AppRequest: Request{
+			AccessMethod:  AccessMethodTerminal,
+			BasePath:      r.URL.Path,
+			AgentNameOrID: chi.URLParam(r, "workspaceagent"),
+		},
+		AppPath:  "",
+		AppQuery: "",
+	})
+	if !ok {
+		return
+	}
+	log := s.Logger.With(slog.F("agent_id", appToken.AgentID))
+	log.Debug(ctx, "resolved desktop request")
+
+	parser := httpapi.NewQueryParamParser()
+	port := parser.PositiveInt32(r.URL.Query(), codersdk.DefaultVNCPort, "port")
+	if len(parser.Errors) == 0 && (port == 0 || port > 65535) {
+		parser.Errors = append(parser.Errors, codersdk.ValidationError{
+			Field:  "port",
+			Detail: "Port must be between 1 and 65535.",
+		})
+	}
+	if len(parser.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: parser.Errors,
+		})
+		return
+	}
+
+	conn, err := websocket.Accept(rw, r, &websocket.AcceptOptions{
+		CompressionMode: websocket.CompressionDisabled,
+		// noVNC offers the "binary" subprotocol.
+		Subprotocols: []string{"binary"},
+		// Always allow websockets from the primary dashboard URL.
+		// Desktops are opened there and connect to the proxy.
+		OriginPatterns: []string{
+			s.DashboardURL.Host,
+			s.AccessURL.Host,
+		},
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to accept websocket.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	go httpapi.HeartbeatClose(ctx, s.Logger, cancel, conn)
+
+	ctx, wsNetConn := WebsocketNetConn(ctx, conn, websocket.MessageBinary)
+	defer wsNetConn.Close() // Also closes conn.
+
+	agentConn, release, err := s.AgentProvider.AgentConn(ctx, appToken.AgentID)
+	if err != nil {
+		log.Debug(ctx, "dial workspace agent", slog.Error(err))
+		_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("dial workspace agent: %s", err))
+		return
+	}
+	defer release()
+	log.Debug(ctx, "dialed workspace agent")
+
+	vncConn, err := agentConn.DialContext(ctx, "tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		log.Debug(ctx, "dial vnc server in workspace agent", slog.Error(err))
+		_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("dial VNC server on port %d: %s", port, err))
+		return
+	}
+	defer vncConn.Close()
+	log.Debug(ctx, "dialed vnc server", slog.F("port", port))
+
+	// App stats aren't collected for desktops, since the terminal access
+	// method would count them as web terminal usage in insights.
+	err = proxyDesktop(ctx, s.DesktopClipboardPolicy, wsNetConn, vncConn)
+	log.Debug(ctx, "desktop proxy finished", slog.Error(err))
+}
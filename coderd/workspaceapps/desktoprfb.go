@@ -0,0 +1,773 @@
+package workspaceapps
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"slices"
+	"sync/atomic"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// RFB (RFC 6143) is the protocol spoken by VNC servers. It has no way of
+// restricting the clipboard, so to enforce the clipboard policy of the
+// deployment the web desktop proxy parses the messages in each direction and
+// drops the cut text that isn't allowed. Only the parts of the protocol that
+// noVNC uses are supported: the security types and encodings the proxy can't
+// parse are removed during the negotiation.
+
+const (
+	rfbSecurityNone    = 1
+	rfbSecurityVNCAuth = 2
+
+	rfbClientSetPixelFormat           = 0
+	rfbClientSetEncodings             = 2
+	rfbClientFramebufferUpdateRequest = 3
+	rfbClientKeyEvent                 = 4
+	rfbClientPointerEvent             = 5
+	rfbClientCutText                  = 6
+	rfbClientEnableContinuousUpdates  = 150
+	rfbClientFence                    = 248
+	rfbClientXVP                      = 250
+	rfbClientSetDesktopSize           = 251
+	rfbClientQEMU                     = 255
+
+	rfbServerFramebufferUpdate      = 0
+	rfbServerSetColourMapEntries    = 1
+	rfbServerBell                   = 2
+	rfbServerCutText                = 3
+	rfbServerEndOfContinuousUpdates = 150
+	rfbServerFence                  = 248
+	rfbServerXVP                    = 250
+
+	rfbEncodingRaw                  = 0
+	rfbEncodingCopyRect             = 1
+	rfbEncodingZlib                 = 6
+	rfbEncodingZRLE                 = 16
+	rfbEncodingDesktopSize          = -223
+	rfbEncodingLastRect             = -224
+	rfbEncodingCursor               = -239
+	rfbEncodingQEMUExtendedKeyEvent = -258
+	rfbEncodingQEMULEDState         = -261
+	rfbEncodingDesktopName          = -307
+	rfbEncodingExtendedDesktopSize  = -308
+	rfbEncodingXVP                  = -309
+	rfbEncodingFence                = -312
+	rfbEncodingContinuousUpdates    = -313
+	rfbEncodingExtendedClipboard    = -1063131698
+)
+
+// rfbParsedEncodings are the encodings the proxy can parse in framebuffer
+// updates, plus the pseudo-encodings that only enable messages it can parse.
+var rfbParsedEncodings = []int32{
+	rfbEncodingRaw,
+	rfbEncodingCopyRect,
+	rfbEncodingZlib,
+	rfbEncodingZRLE,
+	rfbEncodingDesktopSize,
+	rfbEncodingLastRect,
+	rfbEncodingCursor,
+	rfbEncodingQEMUExtendedKeyEvent,
+	rfbEncodingQEMULEDState,
+	rfbEncodingDesktopName,
+	rfbEncodingExtendedDesktopSize,
+	rfbEncodingXVP,
+	rfbEncodingFence,
+	rfbEncodingContinuousUpdates,
+}
+
+// proxyDesktop copies the RFB stream between the browser and the VNC server
+// of the workspace until either side closes, enforcing the clipboard policy.
+// Both connections are closed when it returns.
+func proxyDesktop(ctx context.Context, policy codersdk.DesktopClipboardPolicy, client, server io.ReadWriteCloser) error {
+	if policy == "" || policy == codersdk.DesktopClipboardPolicyBoth {
+		agentssh.Bicopy(ctx, client, server)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer func() {
+		_ = client.Close()
+		_ = server.Close()
+	}()
+	go func() {
+		// Unblock any reads when the request is done.
+		<-ctx.Done()
+		_ = client.Close()
+		_ = server.Close()
+	}()
+
+	p := newRFBProxy(policy, client, server)
+	err := p.handshake()
+	if err != nil {
+		return xerrors.Errorf("rfb handshake: %w", err)
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- p.clientMessages()
+	}()
+	go func() {
+		if !p.filterServer() {
+			_, err := io.Copy(client, p.server.r)
+			errs <- err
+			return
+		}
+		errs <- p.serverMessages()
+	}()
+	err = <-errs
+	cancel()
+	<-errs
+	return err
+}
+
+// rfbStream reads the messages sent by one side of the connection and writes
+// the ones that are allowed to the other side.
+type rfbStream struct {
+	r   *bufio.Reader
+	w   *bufio.Writer
+	buf [32 * 1024]byte
+}
+
+// flushingReader flushes the writer of a stream before blocking on a read, so
+// that messages are batched but never held back.
+type flushingReader struct {
+	r io.Reader
+	w *bufio.Writer
+}
+
+func (f flushingReader) Read(p []byte) (int, error) {
+	err := f.w.Flush()
+	if err != nil {
+		return 0, err
+	}
+	return f.r.Read(p)
+}
+
+func newRFBStream(src io.Reader, dst io.Writer) *rfbStream {
+	w := bufio.NewWriter(dst)
+	return &rfbStream{
+		r: bufio.NewReader(flushingReader{r: src, w: w}),
+		w: w,
+	}
+}
+
+// read reads the next n bytes. The returned slice is only valid until the
+// next call.
+func (s *rfbStream) read(n int) ([]byte, error) {
+	if n > len(s.buf) {
+		return nil, xerrors.Errorf("read of %d bytes exceeds buffer", n)
+	}
+	_, err := io.ReadFull(s.r, s.buf[:n])
+	if err != nil {
+		return nil, err
+	}
+	return s.buf[:n], nil
+}
+
+func (s *rfbStream) write(b ...[]byte) error {
+	for _, p := range b {
+		_, err := s.w.Write(p)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forward copies the next n bytes to the other side.
+func (s *rfbStream) forward(n int64) error {
+	for n > 0 {
+		b, err := s.read(int(min(n, int64(len(s.buf)))))
+		if err != nil {
+			return err
+		}
+		err = s.write(b)
+		if err != nil {
+			return err
+		}
+		n -= int64(len(b))
+	}
+	return nil
+}
+
+// forwardMessage writes the header of a message and copies the next n bytes
+// of its body.
+func (s *rfbStream) forwardMessage(header []byte, n int64) error {
+	err := s.write(header)
+	if err != nil {
+		return err
+	}
+	return s.forward(n)
+}
+
+// forwardString copies a string prefixed by its 32-bit length.
+func (s *rfbStream) forwardString() error {
+	b, err := s.read(4)
+	if err != nil {
+		return err
+	}
+	n := binary.BigEndian.Uint32(b)
+	err = s.write(b)
+	if err != nil {
+		return err
+	}
+	return s.forward(int64(n))
+}
+
+func (s *rfbStream) discard(n int64) error {
+	_, err := io.CopyN(io.Discard, s.r, n)
+	return err
+}
+
+type rfbProxy struct {
+	policy codersdk.DesktopClipboardPolicy
+	// client is read from the browser and written to the VNC server, server
+	// the other way around.
+	client *rfbStream
+	server *rfbStream
+	// minorVersion is the negotiated protocol version, 3.3, 3.7 or 3.8.
+	minorVersion int
+
+	bytesPerPixel atomic.Int32
+	// updateRequested is set once the client asked for framebuffer updates,
+	// after which the pixel format can't change anymore since updates that are
+	// in flight would be parsed with the wrong format.
+	updateRequested atomic.Bool
+}
+
+func newRFBProxy(policy codersdk.DesktopClipboardPolicy, client, server io.ReadWriter) *rfbProxy {
+	return &rfbProxy{
+		policy: policy,
+		client: newRFBStream(client, server),
+		server: newRFBStream(server, client),
+	}
+}
+
+// allowToWorkspace reports whether the browser can paste into the workspace.
+func (p *rfbProxy) allowToWorkspace() bool {
+	return p.policy == codersdk.DesktopClipboardPolicyBoth || p.policy == codersdk.DesktopClipboardPolicyToWorkspace
+}
+
+// filterServer reports whether the messages of the VNC server must be parsed
+// to drop cut text, which is when copying from the workspace isn't allowed.
+func (p *rfbProxy) filterServer() bool {
+	return p.policy != codersdk.DesktopClipboardPolicyBoth && p.policy != codersdk.DesktopClipboardPolicyFromWorkspace
+}
+
+func (p *rfbProxy) handshake() error {
+	// ProtocolVersion, the client picks the version.
+	err := p.server.forward(12)
+	if err != nil {
+		return err
+	}
+	err = p.server.w.Flush()
+	if err != nil {
+		return err
+	}
+	version, err := p.client.read(12)
+	if err != nil {
+		return err
+	}
+	var major, minor int
+	_, err = fmt.Sscanf(string(version), "RFB %03d.%03d\n", &major, &minor)
+	if err != nil || major != 3 {
+		return xerrors.Errorf("invalid protocol version %q", version)
+	}
+	switch {
+	case minor >= 8:
+		p.minorVersion = 8
+	case minor == 7:
+		p.minorVersion = 7
+	default:
+		p.minorVersion = 3
+	}
+	err = p.client.write(version)
+	if err != nil {
+		return err
+	}
+	err = p.client.w.Flush()
+	if err != nil {
+		return err
+	}
+
+	securityType, err := p.negotiateSecurity()
+	if err != nil {
+		return err
+	}
+	if securityType == rfbSecurityVNCAuth {
+		// Challenge and response.
+		err = p.server.forward(16)
+		if err != nil {
+			return err
+		}
+		err = p.server.w.Flush()
+		if err != nil {
+			return err
+		}
+		err = p.client.forward(16)
+		if err != nil {
+			return err
+		}
+		err = p.client.w.Flush()
+		if err != nil {
+			return err
+		}
+	}
+	if securityType == rfbSecurityVNCAuth || p.minorVersion == 8 {
+		b, err := p.server.read(4)
+		if err != nil {
+			return err
+		}
+		result := binary.BigEndian.Uint32(b)
+		err = p.server.write(b)
+		if err != nil {
+			return err
+		}
+		if result != 0 {
+			if p.minorVersion == 8 {
+				err = p.server.forwardString()
+				if err != nil {
+					return err
+				}
+			}
+			_ = p.server.w.Flush()
+			return xerrors.New("authentication failed")
+		}
+		err = p.server.w.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	// ClientInit is the shared flag.
+	err = p.client.forward(1)
+	if err != nil {
+		return err
+	}
+	err = p.client.w.Flush()
+	if err != nil {
+		return err
+	}
+
+	// ServerInit is the framebuffer size, the pixel format and the desktop
+	// name.
+	serverInit, err := p.server.read(20)
+	if err != nil {
+		return err
+	}
+	err = p.setPixelFormat(serverInit[4:20])
+	if err != nil {
+		return err
+	}
+	err = p.server.write(serverInit)
+	if err != nil {
+		return err
+	}
+	err = p.server.forwardString()
+	if err != nil {
+		return err
+	}
+	return p.server.w.Flush()
+}
+
+// negotiateSecurity limits the security types to the ones that don't change
+// the rest of the stream, and returns the type that was picked.
+func (p *rfbProxy) negotiateSecurity() (byte, error) {
+	if p.minorVersion == 3 {
+		// The server picks the type.
+		b, err := p.server.read(4)
+		if err != nil {
+			return 0, err
+		}
+		securityType := binary.BigEndian.Uint32(b)
+		err = p.server.write(b)
+		if err != nil {
+			return 0, err
+		}
+		switch securityType {
+		case 0:
+			_ = p.server.forwardString()
+			_ = p.server.w.Flush()
+			return 0, xerrors.New("server refused the connection")
+		case rfbSecurityNone, rfbSecurityVNCAuth:
+			return byte(securityType), p.server.w.Flush()
+		default:
+			return 0, xerrors.Errorf("unsupported security type %d", securityType)
+		}
+	}
+
+	b, err := p.server.read(1)
+	if err != nil {
+		return 0, err
+	}
+	if b[0] == 0 {
+		err = p.server.write(b)
+		if err != nil {
+			return 0, err
+		}
+		_ = p.server.forwardString()
+		_ = p.server.w.Flush()
+		return 0, xerrors.New("server refused the connection")
+	}
+	types, err := p.server.read(int(b[0]))
+	if err != nil {
+		return 0, err
+	}
+	supported := slices.DeleteFunc(slices.Clone(types), func(t byte) bool {
+		return t != rfbSecurityNone && t != rfbSecurityVNCAuth
+	})
+	if len(supported) == 0 {
+		const reason = "The web desktop only supports VNC servers with no authentication or VNC password authentication."
+		_ = p.server.write([]byte{0}, binary.BigEndian.AppendUint32(nil, uint32(len(reason))), []byte(reason))
+		_ = p.server.w.Flush()
+		return 0, xerrors.Errorf("unsupported security types %v", types)
+	}
+	err = p.server.write([]byte{byte(len(supported))}, supported)
+	if err != nil {
+		return 0, err
+	}
+	err = p.server.w.Flush()
+	if err != nil {
+		return 0, err
+	}
+
+	choice, err := p.client.read(1)
+	if err != nil {
+		return 0, err
+	}
+	securityType := choice[0]
+	if !slices.Contains(supported, securityType) {
+		return 0, xerrors.Errorf("client picked security type %d that wasn't offered", securityType)
+	}
+	err = p.client.write(choice)
+	if err != nil {
+		return 0, err
+	}
+	return securityType, p.client.w.Flush()
+}
+
+func (p *rfbProxy) setPixelFormat(format []byte) error {
+	bitsPerPixel := format[0]
+	switch bitsPerPixel {
+	case 8, 16, 32:
+	default:
+		return xerrors.Errorf("invalid bits per pixel %d", bitsPerPixel)
+	}
+	p.bytesPerPixel.Store(int32(bitsPerPixel / 8))
+	return nil
+}
+
+func (p *rfbProxy) allowEncoding(encoding int32) bool {
+	// The extended clipboard negotiates its capabilities with cut text
+	// messages, which are dropped in at least one direction.
+	if encoding == rfbEncodingExtendedClipboard {
+		return false
+	}
+	if !p.filterServer() {
+		return true
+	}
+	// The JPEG quality and compression levels only configure the encoders of
+	// the server.
+	if (encoding >= -32 && encoding <= -23) || (encoding >= -256 && encoding <= -247) {
+		return true
+	}
+	return slices.Contains(rfbParsedEncodings, encoding)
+}
+
+// clientMessages forwards the messages of the browser to the VNC server.
+func (p *rfbProxy) clientMessages() error {
+	s := p.client
+	for {
+		b, err := s.read(1)
+		if err != nil {
+			return err
+		}
+		messageType := b[0]
+		header := []byte{messageType}
+
+		switch messageType {
+		case rfbClientSetPixelFormat:
+			b, err := s.read(19)
+			if err != nil {
+				return err
+			}
+			if p.filterServer() && p.updateRequested.Load() {
+				return xerrors.New("client changed the pixel format after requesting updates")
+			}
+			err = p.setPixelFormat(b[3:])
+			if err != nil {
+				return err
+			}
+			err = s.write(header, b)
+			if err != nil {
+				return err
+			}
+		case rfbClientSetEncodings:
+			b, err := s.read(3)
+			if err != nil {
+				return err
+			}
+			count := int(binary.BigEndian.Uint16(b[1:]))
+			encodings := make([]byte, 0, 4*count)
+			for range count {
+				b, err := s.read(4)
+				if err != nil {
+					return err
+				}
+				if p.allowEncoding(int32(binary.BigEndian.Uint32(b))) { //nolint:gosec // Encodings are signed.
+					encodings = append(encodings, b...)
+				}
+			}
+			header = append(header, 0)
+			header = binary.BigEndian.AppendUint16(header, uint16(len(encodings)/4)) //nolint:gosec // At most the original count.
+			err = s.write(header, encodings)
+			if err != nil {
+				return err
+			}
+		case rfbClientFramebufferUpdateRequest, rfbClientEnableContinuousUpdates:
+			p.updateRequested.Store(true)
+			err = s.forwardMessage(header, 9)
+			if err != nil {
+				return err
+			}
+		case rfbClientKeyEvent:
+			err = s.forwardMessage(header, 7)
+			if err != nil {
+				return err
+			}
+		case rfbClientPointerEvent:
+			err = s.forwardMessage(header, 5)
+			if err != nil {
+				return err
+			}
+		case rfbClientXVP:
+			err = s.forwardMessage(header, 3)
+			if err != nil {
+				return err
+			}
+		case rfbClientCutText:
+			b, err := s.read(7)
+			if err != nil {
+				return err
+			}
+			// A negative length is used by the extended clipboard.
+			length := int64(int32(binary.BigEndian.Uint32(b[3:]))) //nolint:gosec // The length is signed.
+			if length < 0 {
+				length = -length
+			}
+			if !p.allowToWorkspace() {
+				err = s.discard(length)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			err = s.write(header, b)
+			if err != nil {
+				return err
+			}
+			err = s.forward(length)
+			if err != nil {
+				return err
+			}
+		case rfbClientFence:
+			b, err := s.read(8)
+			if err != nil {
+				return err
+			}
+			err = s.write(header, b)
+			if err != nil {
+				return err
+			}
+			err = s.forward(int64(b[7]))
+			if err != nil {
+				return err
+			}
+		case rfbClientSetDesktopSize:
+			b, err := s.read(7)
+			if err != nil {
+				return err
+			}
+			err = s.write(header, b)
+			if err != nil {
+				return err
+			}
+			err = s.forward(16 * int64(b[5]))
+			if err != nil {
+				return err
+			}
+		case rfbClientQEMU:
+			b, err := s.read(1)
+			if err != nil {
+				return err
+			}
+			subtype := b[0]
+			err = s.write(header, b)
+			if err != nil {
+				return err
+			}
+			switch subtype {
+			case 0: // Extended key event.
+				err = s.forward(10)
+			case 1: // Audio.
+				b, err = s.read(2)
+				if err != nil {
+					return err
+				}
+				operation := binary.BigEndian.Uint16(b)
+				err = s.write(b)
+				if err == nil && operation == 2 {
+					// Set format.
+					err = s.forward(6)
+				}
+			default:
+				err = xerrors.Errorf("unsupported QEMU client message %d", subtype)
+			}
+			if err != nil {
+				return err
+			}
+		default:
+			return xerrors.Errorf("unsupported client message type %d", messageType)
+		}
+	}
+}
+
+// serverMessages forwards the messages of the VNC server to the browser.
+func (p *rfbProxy) serverMessages() error {
+	s := p.server
+	for {
+		b, err := s.read(1)
+		if err != nil {
+			return err
+		}
+		messageType := b[0]
+		header := []byte{messageType}
+
+		switch messageType {
+		case rfbServerFramebufferUpdate:
+			b, err := s.read(3)
+			if err != nil {
+				return err
+			}
+			// With the LastRect pseudo-encoding the number of rectangles
+			// can be unknown.
+			count := int(binary.BigEndian.Uint16(b[1:]))
+			err = s.write(header, b)
+			if err != nil {
+				return err
+			}
+			for i := 0; count == 0xffff || i < count; i++ {
+				b, err := s.read(12)
+				if err != nil {
+					return err
+				}
+				width := int64(binary.BigEndian.Uint16(b[4:]))
+				height := int64(binary.BigEndian.Uint16(b[6:]))
+				encoding := int32(binary.BigEndian.Uint32(b[8:])) //nolint:gosec // Encodings are signed.
+				err = s.write(b)
+				if err != nil {
+					return err
+				}
+				if encoding == rfbEncodingLastRect {
+					break
+				}
+				err = p.forwardRect(width, height, encoding)
+				if err != nil {
+					return err
+				}
+			}
+		case rfbServerSetColourMapEntries:
+			b, err := s.read(5)
+			if err != nil {
+				return err
+			}
+			err = s.write(header, b)
+			if err != nil {
+				return err
+			}
+			err = s.forward(6 * int64(binary.BigEndian.Uint16(b[3:])))
+			if err != nil {
+				return err
+			}
+		case rfbServerBell, rfbServerEndOfContinuousUpdates:
+			err = s.write(header)
+			if err != nil {
+				return err
+			}
+		case rfbServerCutText:
+			b, err := s.read(7)
+			if err != nil {
+				return err
+			}
+			length := int64(int32(binary.BigEndian.Uint32(b[3:]))) //nolint:gosec // The length is signed.
+			if length < 0 {
+				length = -length
+			}
+			err = s.discard(length)
+			if err != nil {
+				return err
+			}
+		case rfbServerFence:
+			b, err := s.read(8)
+			if err != nil {
+				return err
+			}
+			err = s.write(header, b)
+			if err != nil {
+				return err
+			}
+			err = s.forward(int64(b[7]))
+			if err != nil {
+				return err
+			}
+		case rfbServerXVP:
+			err = s.forwardMessage(header, 3)
+			if err != nil {
+				return err
+			}
+		default:
+			return xerrors.Errorf("unsupported server message type %d", messageType)
+		}
+	}
+}
+
+// forwardRect forwards the pixel data of a rectangle in a framebuffer update.
+func (p *rfbProxy) forwardRect(width, height int64, encoding int32) error {
+	s := p.server
+	bytesPerPixel := int64(p.bytesPerPixel.Load())
+	switch encoding {
+	case rfbEncodingRaw:
+		return s.forward(width * height * bytesPerPixel)
+	case rfbEncodingCopyRect:
+		return s.forward(4)
+	case rfbEncodingZlib, rfbEncodingZRLE, rfbEncodingDesktopName:
+		return s.forwardString()
+	case rfbEncodingCursor:
+		// The pixels followed by the bitmask.
+		return s.forward(width*height*bytesPerPixel + (width+7)/8*height)
+	case rfbEncodingExtendedDesktopSize:
+		b, err := s.read(4)
+		if err != nil {
+			return err
+		}
+		err = s.write(b)
+		if err != nil {
+			return err
+		}
+		return s.forward(16 * int64(b[0]))
+	case rfbEncodingQEMULEDState:
+		return s.forward(1)
+	case rfbEncodingDesktopSize, rfbEncodingQEMUExtendedKeyEvent:
+		return nil
+	default:
+		return xerrors.Errorf("unsupported encoding %d", encoding)
+	}
+}
@@ -0,0 +1,181 @@
+package workspaceapps
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestProxyDesktop(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		policy        codersdk.DesktopClipboardPolicy
+		encodings     []int32
+		toWorkspace   bool
+		fromWorkspace bool
+	}{
+		{
+			policy:        codersdk.DesktopClipboardPolicyNone,
+			encodings:     []int32{rfbEncodingRaw, rfbEncodingZRLE, rfbEncodingCursor},
+			toWorkspace:   false,
+			fromWorkspace: false,
+		},
+		{
+			policy:        codersdk.DesktopClipboardPolicyToWorkspace,
+			encodings:     []int32{rfbEncodingRaw, rfbEncodingZRLE, rfbEncodingCursor},
+			toWorkspace:   true,
+			fromWorkspace: false,
+		},
+		{
+			// The server messages aren't parsed, so the encodings the proxy
+			// can't parse are allowed.
+			policy:        codersdk.DesktopClipboardPolicyFromWorkspace,
+			encodings:     []int32{rfbEncodingRaw, rfbEncodingZRLE, 7, rfbEncodingCursor},
+			toWorkspace:   false,
+			fromWorkspace: true,
+		},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.Context(t, testutil.WaitShort)
+			client, server, done := startDesktopProxy(t, tc.policy)
+
+			rfbWrite(t, server, []byte("RFB 003.008\n"))
+			require.Equal(t, []byte("RFB 003.008\n"), rfbRead(t, client, 12))
+			rfbWrite(t, client, []byte("RFB 003.008\n"))
+			require.Equal(t, []byte("RFB 003.008\n"), rfbRead(t, server, 12))
+
+			// VeNCrypt isn't offered to the client.
+			rfbWrite(t, server, []byte{3, rfbSecurityVNCAuth, 19, rfbSecurityNone})
+			require.Equal(t, []byte{2, rfbSecurityVNCAuth, rfbSecurityNone}, rfbRead(t, client, 3))
+			rfbWrite(t, client, []byte{rfbSecurityNone})
+			require.Equal(t, []byte{rfbSecurityNone}, rfbRead(t, server, 1))
+			rfbWrite(t, server, []byte{0, 0, 0, 0})
+			require.Equal(t, []byte{0, 0, 0, 0}, rfbRead(t, client, 4))
+
+			rfbWrite(t, client, []byte{1})
+			require.Equal(t, []byte{1}, rfbRead(t, server, 1))
+			serverInit := []byte{0, 2, 0, 1, 32, 24, 0, 1, 0, 255, 0, 255, 0, 255, 16, 8, 0, 0, 0, 0}
+			serverInit = rfbString(serverInit, "desktop")
+			rfbWrite(t, server, serverInit)
+			require.Equal(t, serverInit, rfbRead(t, client, len(serverInit)))
+
+			// Client messages.
+			go func() {
+				setEncodings := []byte{rfbClientSetEncodings, 0, 0, 5}
+				for _, encoding := range []int32{rfbEncodingRaw, rfbEncodingZRLE, 7, rfbEncodingExtendedClipboard, rfbEncodingCursor} {
+					setEncodings = binary.BigEndian.AppendUint32(setEncodings, uint32(encoding))
+				}
+				rfbWrite(t, client, setEncodings)
+				rfbWrite(t, client, []byte{rfbClientFramebufferUpdateRequest, 0, 0, 0, 0, 0, 0, 2, 0, 1})
+				rfbWrite(t, client, rfbString([]byte{rfbClientCutText, 0, 0, 0}, "pasted"))
+				rfbWrite(t, client, []byte{rfbClientKeyEvent, 1, 0, 0, 0, 0, 0, 'a'})
+			}()
+			setEncodings := []byte{rfbClientSetEncodings, 0, 0, byte(len(tc.encodings))}
+			for _, encoding := range tc.encodings {
+				setEncodings = binary.BigEndian.AppendUint32(setEncodings, uint32(encoding))
+			}
+			require.Equal(t, setEncodings, rfbRead(t, server, len(setEncodings)))
+			require.Equal(t, []byte{rfbClientFramebufferUpdateRequest, 0, 0, 0, 0, 0, 0, 2, 0, 1}, rfbRead(t, server, 10))
+			if tc.toWorkspace {
+				cutText := rfbString([]byte{rfbClientCutText, 0, 0, 0}, "pasted")
+				require.Equal(t, cutText, rfbRead(t, server, len(cutText)))
+			}
+			require.Equal(t, []byte{rfbClientKeyEvent, 1, 0, 0, 0, 0, 0, 'a'}, rfbRead(t, server, 8))
+
+			// Server messages.
+			update := []byte{rfbServerFramebufferUpdate, 0, 0, 1, 0, 0, 0, 0, 0, 2, 0, 1, 0, 0, 0, rfbEncodingRaw}
+			update = append(update, 1, 2, 3, 4, 5, 6, 7, 8)
+			go func() {
+				rfbWrite(t, server, rfbString([]byte{rfbServerCutText, 0, 0, 0}, "copied"))
+				rfbWrite(t, server, update)
+				rfbWrite(t, server, []byte{rfbServerBell})
+			}()
+			if tc.fromWorkspace {
+				cutText := rfbString([]byte{rfbServerCutText, 0, 0, 0}, "copied")
+				require.Equal(t, cutText, rfbRead(t, client, len(cutText)))
+			}
+			require.Equal(t, update, rfbRead(t, client, len(update)))
+			require.Equal(t, []byte{rfbServerBell}, rfbRead(t, client, 1))
+
+			_ = client.Close()
+			select {
+			case <-done:
+			case <-ctx.Done():
+				t.Fatal("proxy didn't exit")
+			}
+		})
+	}
+
+	t.Run("UnsupportedSecurityTypes", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client, server, done := startDesktopProxy(t, codersdk.DesktopClipboardPolicyNone)
+
+		rfbWrite(t, server, []byte("RFB 003.008\n"))
+		rfbRead(t, client, 12)
+		rfbWrite(t, client, []byte("RFB 003.008\n"))
+		rfbRead(t, server, 12)
+		rfbWrite(t, server, []byte{1, 19})
+		require.Equal(t, []byte{0}, rfbRead(t, client, 1))
+		length := binary.BigEndian.Uint32(rfbRead(t, client, 4))
+		require.Contains(t, string(rfbRead(t, client, int(length))), "no authentication or VNC password authentication")
+
+		select {
+		case err := <-done:
+			require.ErrorContains(t, err, "unsupported security types")
+		case <-ctx.Done():
+			t.Fatal("proxy didn't exit")
+		}
+	})
+}
+
+// startDesktopProxy returns the browser and VNC server ends of a desktop
+// proxy, and a channel with the error it exits with.
+func startDesktopProxy(t *testing.T, policy codersdk.DesktopClipboardPolicy) (client net.Conn, server net.Conn, done <-chan error) {
+	t.Helper()
+	client, proxyClient := net.Pipe()
+	proxyServer, server := net.Pipe()
+	deadline := time.Now().Add(testutil.WaitShort)
+	for _, conn := range []net.Conn{client, server} {
+		require.NoError(t, conn.SetDeadline(deadline))
+		t.Cleanup(func() {
+			_ = conn.Close()
+		})
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- proxyDesktop(testutil.Context(t, testutil.WaitShort), policy, proxyClient, proxyServer)
+	}()
+	return client, server, errs
+}
+
+func rfbString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+func rfbWrite(t *testing.T, conn net.Conn, b []byte) {
+	t.Helper()
+	_, err := conn.Write(b)
+	if err != nil {
+		t.Errorf("write: %v", err)
+	}
+}
+
+func rfbRead(t *testing.T, conn net.Conn, n int) []byte {
+	t.Helper()
+	b := make([]byte, n)
+	_, err := io.ReadFull(conn, b)
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,169 @@
+package workspaceapps
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/cryptokeys"
+	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
+	"github.com/coder/coder/v2/site"
+)
+
+// PathAppSubdomainExpiry is how long a signed path app subdomain stays valid.
+// Navigating to an app after its subdomain expired redirects to a freshly
+// signed one.
+const PathAppSubdomainExpiry = 8 * time.Hour
+
+// pathAppSubdomainMACLength is the number of bytes of the HMAC kept in the
+// subdomain, so that the label fits the 63 characters allowed by DNS.
+const pathAppSubdomainMACLength = 20
+
+var pathAppSubdomainEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// pathApp identifies a path-based app by the parameters of its path.
+type pathApp struct {
+	User              string
+	WorkspaceAndAgent string
+	App               string
+}
+
+func pathAppFromRequest(r *http.Request) pathApp {
+	return pathApp{
+		User:              chi.URLParam(r, "user"),
+		WorkspaceAndAgent: chi.URLParam(r, "workspace_and_agent"),
+		App:               chi.URLParam(r, "workspaceapp"),
+	}
+}
+
+func (a pathApp) mac(key []byte, expiry int64) []byte {
+	h := hmac.New(sha256.New, key)
+	for _, s := range []string{"path-app-subdomain", a.User, a.WorkspaceAndAgent, a.App, strconv.FormatInt(expiry, 10)} {
+		_, _ = h.Write([]byte(strings.ToLower(s)))
+		_, _ = h.Write([]byte{0})
+	}
+	return h.Sum(nil)[:pathAppSubdomainMACLength]
+}
+
+// signPathAppSubdomain returns a DNS label that binds the app to its own
+// hostname until the expiry. The label is made of the ID of the signing key,
+// the expiry and the truncated HMAC of both with the app.
+func signPathAppSubdomain(ctx context.Context, keys cryptokeys.SigningKeycache, app pathApp, expiry time.Time) (string, error) {
+	id, key, err := keys.SigningKey(ctx)
+	if err != nil {
+		return "", xerrors.Errorf("get signing key: %w", err)
+	}
+	secret, ok := key.([]byte)
+	if !ok {
+		return "", xerrors.Errorf("unexpected signing key type %T", key)
+	}
+
+	exp := expiry.Unix()
+	return strings.Join([]string{
+		id,
+		strconv.FormatInt(exp, 36),
+		strings.ToLower(pathAppSubdomainEncoding.EncodeToString(app.mac(secret, exp))),
+	}, "-"), nil
+}
+
+// verifyPathAppSubdomain checks that the label was signed for the app and
+// hasn't expired.
+func verifyPathAppSubdomain(ctx context.Context, keys cryptokeys.SigningKeycache, app pathApp, label string, now time.Time) error {
+	parts := strings.Split(label, "-")
+	if len(parts) != 3 {
+		return xerrors.New("malformed subdomain")
+	}
+	exp, err := strconv.ParseInt(parts[1], 36, 64)
+	if err != nil {
+		return xerrors.Errorf("parse expiry: %w", err)
+	}
+	if !now.Before(time.Unix(exp, 0)) {
+		return xerrors.New("subdomain expired")
+	}
+	mac, err := pathAppSubdomainEncoding.DecodeString(strings.ToUpper(parts[2]))
+	if err != nil {
+		return xerrors.Errorf("decode signature: %w", err)
+	}
+
+	key, err := keys.VerifyingKey(ctx, parts[0])
+	if err != nil {
+		return xerrors.Errorf("get verifying key: %w", err)
+	}
+	secret, ok := key.([]byte)
+	if !ok {
+		return xerrors.Errorf("unexpected verifying key type %T", key)
+	}
+	if !hmac.Equal(mac, app.mac(secret, exp)) {
+		return xerrors.New("invalid signature")
+	}
+	return nil
+}
+
+// IsPathAppSubdomain reports whether the host is a subdomain of the path app
+// access URL, which is where signed path app subdomains are served.
+func IsPathAppSubdomain(host string, pathAppAccessURL *url.URL) bool {
+	_, rest, ok := strings.Cut(host, ".")
+	return ok && appurl.HostnamesMatch(rest, pathAppAccessURL.Host)
+}
+
+// resolvePathAppSubdomain makes sure that path-based apps are only served from
+// the signed subdomain of the app. Requests on any other host, or with an
+// expired subdomain, are redirected to a freshly signed subdomain. It returns
+// the base URL of the app on its subdomain.
+func (s *Server) resolvePathAppSubdomain(rw http.ResponseWriter, r *http.Request) (*url.URL, bool) {
+	ctx := r.Context()
+	app := pathAppFromRequest(r)
+
+	if IsPathAppSubdomain(r.Host, s.AccessURL) {
+		label, _, _ := strings.Cut(r.Host, ".")
+		err := verifyPathAppSubdomain(ctx, s.PathAppSubdomainKeycache, app, strings.ToLower(label), time.Now())
+		if err == nil {
+			u := *s.AccessURL
+			u.Host = r.Host
+			return &u, true
+		}
+		s.Logger.Debug(ctx, "invalid path app subdomain", slog.F("host", r.Host), slog.Error(err))
+
+		// Only navigations can follow the redirect to another origin.
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+				Status:       http.StatusBadRequest,
+				Title:        "Bad Request",
+				Description:  "The hostname of this app has expired or is invalid. Please reload the app.",
+				RetryEnabled: false,
+				DashboardURL: s.DashboardURL.String(),
+			})
+			return nil, false
+		}
+	}
+
+	label, err := signPathAppSubdomain(ctx, s.PathAppSubdomainKeycache, app, time.Now().Add(PathAppSubdomainExpiry))
+	if err != nil {
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusInternalServerError,
+			Title:        "Internal Server Error",
+			Description:  "Could not sign the hostname of the app: " + err.Error(),
+			RetryEnabled: true,
+			DashboardURL: s.DashboardURL.String(),
+		})
+		return nil, false
+	}
+
+	u := *s.AccessURL
+	u.Host = label + "." + s.AccessURL.Host
+	u.Path = r.URL.Path
+	u.RawPath = r.URL.RawPath
+	u.RawQuery = r.URL.RawQuery
+	http.Redirect(rw, r, u.String(), http.StatusTemporaryRedirect)
+	return nil, false
+}
@@ -0,0 +1,72 @@
+package workspaceapps
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/cryptokeys"
+	"github.com/coder/coder/v2/testutil"
+)
+
+type staticKeycache map[string][]byte
+
+func (k staticKeycache) SigningKey(_ context.Context) (string, interface{}, error) {
+	return "2", k["2"], nil
+}
+
+func (k staticKeycache) VerifyingKey(_ context.Context, id string) (interface{}, error) {
+	key, ok := k[id]
+	if !ok {
+		return nil, xerrors.Errorf("key %q: %w", id, cryptokeys.ErrKeyNotFound)
+	}
+	return key, nil
+}
+
+func (staticKeycache) Close() error {
+	return nil
+}
+
+func TestPathAppSubdomain(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	keys := staticKeycache{
+		"1": []byte("old-key"),
+		"2": []byte("new-key"),
+	}
+	app := pathApp{User: "Alice", WorkspaceAndAgent: "ws.main", App: "code"}
+	now := time.Now()
+
+	label, err := signPathAppSubdomain(ctx, keys, app, now.Add(PathAppSubdomainExpiry))
+	require.NoError(t, err)
+	require.LessOrEqual(t, len(label), 63)
+	require.Equal(t, strings.ToLower(label), label)
+	require.NoError(t, verifyPathAppSubdomain(ctx, keys, app, label, now))
+
+	// The path parameters are matched case-insensitively, like usernames.
+	require.NoError(t, verifyPathAppSubdomain(ctx, keys, pathApp{User: "alice", WorkspaceAndAgent: "WS.main", App: "code"}, label, now))
+
+	// Another app can't be served from the subdomain.
+	require.Error(t, verifyPathAppSubdomain(ctx, keys, pathApp{User: "alice", WorkspaceAndAgent: "ws.main", App: "terminal"}, label, now))
+	require.Error(t, verifyPathAppSubdomain(ctx, keys, pathApp{User: "bob", WorkspaceAndAgent: "ws.main", App: "code"}, label, now))
+
+	// Expired subdomains aren't valid anymore.
+	require.Error(t, verifyPathAppSubdomain(ctx, keys, app, label, now.Add(PathAppSubdomainExpiry)))
+
+	// The expiry and key are covered by the signature.
+	parts := strings.Split(label, "-")
+	for _, forged := range []string{
+		strings.Join([]string{parts[0], "zzzzzzz", parts[2]}, "-"),
+		strings.Join([]string{"1", parts[1], parts[2]}, "-"),
+		strings.Join([]string{"3", parts[1], parts[2]}, "-"),
+		strings.Join(parts[:2], "-"),
+		"",
+	} {
+		require.Error(t, verifyPathAppSubdomain(ctx, keys, app, forged, now), forged)
+	}
+}
@@ -62,6 +62,14 @@ func ResolveRequest(rw http.ResponseWriter, r *http.Request, opts ResolveRequest
 		AppPath:        opts.AppPath,
 		AppQuery:       opts.AppQuery,
 	}
+	if appReq.AccessMethod == AccessMethodSubdomain {
+		issueReq.ShareLinkToken = ShareLinkTokenFromRequest(r)
+		if issueReq.ShareLinkToken != "" {
+			// The password of port share links is provided with basic auth.
+			_, issueReq.ShareLinkPassword, _ = r.BasicAuth()
+			issueReq.ShareLinkClientIP = shareLinkClientIP(r)
+		}
+	}
 
 	token, tokenStr, ok := opts.SignedTokenProvider.Issue(r.Context(), rw, r, issueReq)
 	if !ok {
@@ -111,13 +111,23 @@ type Server struct {
 	// Subdomain apps are safer with their cookies scoped to the subdomain, and XSS
 	// calls to the dashboard are not possible due to CORs.
 	DisablePathApps bool
-	Cookies         codersdk.HTTPCookieConfig
+	// PathAppSubdomains serves every path-based app from its own signed and
+	// expiring subdomain of AccessURL, so that apps don't share an origin with
+	// each other. AccessURL must be a dedicated host with a wildcard DNS record
+	// for its subdomains.
+	PathAppSubdomains        bool
+	PathAppSubdomainKeycache cryptokeys.SigningKeycache
+	Cookies                  codersdk.HTTPCookieConfig
+	// DesktopClipboardPolicy is enforced on the VNC streams of web desktops.
+	DesktopClipboardPolicy codersdk.DesktopClipboardPolicy
 
 	AgentProvider  AgentProvider
 	StatsCollector *StatsCollector
 
 	websocketWaitMutex sync.Mutex
 	websocketWaitGroup sync.WaitGroup
+
+	shareLinkBudgets shareLinkBudgets
 }
 
 // Close waits for all reconnecting-pty WebSocket connections to drain before
@@ -149,6 +159,8 @@ func (s *Server) Attach(r chi.Router) {
 	r.Route("/@{user}/{workspace_and_agent}/apps/{workspaceapp}", servePathApps)
 
 	r.Get("/api/v2/workspaceagents/{workspaceagent}/pty", s.workspaceAgentPTY)
+	r.Get("/api/v2/workspaceagents/{workspaceagent}/desktop/vnc", s.workspaceAgentDesktopVNC)
+	r.Get("/api/v2/workspaceagents/{workspaceagent}/desktop/rdp", s.workspaceAgentDesktopRDP)
 }
 
 // handleAPIKeySmuggling is called by the proxy path and subdomain handlers to
@@ -282,6 +294,25 @@ func (s *Server) workspaceAppsProxyPath(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	pathAppBaseURL := s.AccessURL
+	if s.PathAppSubdomains {
+		var ok bool
+		pathAppBaseURL, ok = s.resolvePathAppSubdomain(rw, r)
+		if !ok {
+			return
+		}
+	} else if s.AccessURL.Host != s.DashboardURL.Host && appurl.HostnamesMatch(r.Host, s.DashboardURL.Host) {
+		// When path apps are served from a dedicated host, requests that reach
+		// the dashboard are sent there instead so the app never runs on the
+		// dashboard origin.
+		u := *s.AccessURL
+		u.Path = r.URL.Path
+		u.RawPath = r.URL.RawPath
+		u.RawQuery = r.URL.RawQuery
+		http.Redirect(rw, r, u.String(), http.StatusTemporaryRedirect)
+		return
+	}
+
 	if !s.handleAPIKeySmuggling(rw, r, AccessMethodPath) {
 		return
 	}
@@ -301,7 +332,7 @@ func (s *Server) workspaceAppsProxyPath(rw http.ResponseWriter, r *http.Request)
 		CookieCfg:           s.Cookies,
 		SignedTokenProvider: s.SignedTokenProvider,
 		DashboardURL:        s.DashboardURL,
-		PathAppBaseURL:      s.AccessURL,
+		PathAppBaseURL:      pathAppBaseURL,
 		AppHostname:         s.Hostname,
 		AppRequest: Request{
 			AccessMethod:      AccessMethodPath,
@@ -401,6 +432,9 @@ func (s *Server) HandleSubdomain(middlewares ...func(http.Handler) http.Handler)
 				if !s.handleAPIKeySmuggling(rw, r, AccessMethodSubdomain) {
 					return
 				}
+				if !s.handleShareLinkToken(rw, r) {
+					return
+				}
 
 				token, ok := ResolveRequest(rw, r, ResolveRequestOptions{
 					Logger:              s.Logger,
@@ -579,6 +613,18 @@ func (s *Server) proxyWorkspaceApp(rw http.ResponseWriter, r *http.Request, appT
 		return nil
 	}
 
+	// Visitors of port share links provide the password of the link with
+	// basic auth, which isn't meant for the app. The bytes transferred through
+	// the link are counted to enforce its bandwidth limit.
+	var shareLink shareLinkCounter
+	if appToken.ShareLinkID != uuid.Nil {
+		if _, _, ok := r.BasicAuth(); ok {
+			r.Header.Del("Authorization")
+		}
+		shareLink.remaining = s.shareLinkBudgets.get(appToken, time.Now())
+		rw, r = countShareLinkBytes(rw, r, &shareLink)
+	}
+
 	// This strips the session token from a workspace app request.
 	cookieHeaders := r.Header.Values("Cookie")
 	r.Header.Del("Cookie")
@@ -603,6 +649,7 @@ func (s *Server) proxyWorkspaceApp(rw http.ResponseWriter, r *http.Request, appT
 	defer func() {
 		// We must use defer here because ServeHTTP may panic.
 		report.SessionEndedAt = dbtime.Now()
+		report.ShareLinkBytes = shareLink.transferred.Load()
 		s.collectStats(report)
 	}()
 
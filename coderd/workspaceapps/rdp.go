@@ -0,0 +1,357 @@
+package workspaceapps
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/asn1"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/websocket"
+)
+
+// workspaceAgentDesktopRDP is an RDP gateway for browser RDP clients. Browsers
+// can't open TCP connections or do the TLS upgrade in the middle of the RDP
+// connection sequence, so the client sends an RDCleanPath request over the
+// WebSocket, and the gateway connects to the RDP server of the workspace,
+// negotiates TLS with it and relays the rest of the session. This is the
+// protocol of IronRDP and Devolutions Gateway, so their web client can be
+// pointed at this endpoint.
+//
+// Access to RDP is equivalent to access to the terminal, so it is authorized
+// like the PTY endpoint. The proxy auth token of the RDCleanPath request is
+// ignored, since the WebSocket itself is authenticated.
+//
+// @Summary Open web RDP gateway to workspace agent
+// @ID open-web-rdp-gateway-to-workspace-agent
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param port query int false "RDP port in the workspace, defaults to 3389"
+// @Success 101
+// @Router /workspaceagents/{workspaceagent}/desktop/rdp [get]
+func (s *Server) workspaceAgentDesktopRDP(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	s.websocketWaitMutex.Lock()
+	s.websocketWaitGroup.Add(1)
+	s.websocketWaitMutex.Unlock()
+	defer s.websocketWaitGroup.Done()
+
+	appToken, ok := ResolveRequest(rw, r, ResolveRequestOptions{
+		Logger:              s.Logger,
+		CookieCfg:           s.Cookies,
+		SignedTokenProvider: s.SignedTokenProvider,
+		DashboardURL:        s.DashboardURL,
+		PathAppBaseURL:      s.AccessURL,
+		AppHostname:         s.Hostname,
+		AppRequest: Request{
+			AccessMethod:  AccessMethodTerminal,
+			BasePath:      r.URL.Path,
+			AgentNameOrID: chi.URLParam(r, "workspaceagent"),
+		},
+		AppPath:  "",
+		AppQuery: "",
+	})
+	if !ok {
+		return
+	}
+	log := s.Logger.With(slog.F("agent_id", appToken.AgentID))
+	log.Debug(ctx, "resolved rdp request")
+
+	parser := httpapi.NewQueryParamParser()
+	port := parser.PositiveInt32(r.URL.Query(), codersdk.DefaultRDPPort, "port")
+	if len(parser.Errors) == 0 && (port == 0 || port > 65535) {
+		parser.Errors = append(parser.Errors, codersdk.ValidationError{
+			Field:  "port",
+			Detail: "Port must be between 1 and 65535.",
+		})
+	}
+	if len(parser.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: parser.Errors,
+		})
+		return
+	}
+
+	conn, err := websocket.Accept(rw, r, &websocket.AcceptOptions{
+		CompressionMode: websocket.CompressionDisabled,
+		// Always allow websockets from the primary dashboard URL.
+		// Desktops are opened there and connect to the proxy.
+		OriginPatterns: []string{
+			s.DashboardURL.Host,
+			s.AccessURL.Host,
+		},
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to accept websocket.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	go httpapi.HeartbeatClose(ctx, s.Logger, cancel, conn)
+
+	ctx, wsNetConn := WebsocketNetConn(ctx, conn, websocket.MessageBinary)
+	defer wsNetConn.Close() // Also closes conn.
+
+	rdpConn, err := rdCleanPathConnect(ctx, wsNetConn, func(ctx context.Context) (net.Conn, error) {
+		agentConn, release, err := s.AgentProvider.AgentConn(ctx, appToken.AgentID)
+		if err != nil {
+			return nil, xerrors.Errorf("dial workspace agent: %w", err)
+		}
+		conn, err := agentConn.DialContext(ctx, "tcp", fmt.Sprintf("127.0.0.1:%d", port))
+		if err != nil {
+			release()
+			return nil, xerrors.Errorf("dial RDP server on port %d: %w", port, err)
+		}
+		return &releaseConn{Conn: conn, release: release}, nil
+	})
+	if err != nil {
+		log.Debug(ctx, "rdp gateway connect", slog.Error(err))
+		return
+	}
+	defer rdpConn.Close()
+	log.Debug(ctx, "connected to rdp server", slog.F("port", port))
+
+	// App stats aren't collected for desktops, since the terminal access
+	// method would count them as web terminal usage in insights.
+	agentssh.Bicopy(ctx, wsNetConn, rdpConn)
+	log.Debug(ctx, "rdp gateway finished")
+}
+
+// releaseConn releases the agent connection a net.Conn was dialed with when it
+// is closed.
+type releaseConn struct {
+	net.Conn
+	release func()
+}
+
+func (c *releaseConn) Close() error {
+	err := c.Conn.Close()
+	c.release()
+	return err
+}
+
+// rdCleanPathVersion is version 1 of the RDCleanPath protocol.
+const rdCleanPathVersion = 3390
+
+// RDCleanPath error codes.
+const (
+	rdCleanPathGeneralError     = 1
+	rdCleanPathNegotiationError = 2
+)
+
+// rdCleanPathPDU is a request or response of the RDCleanPath protocol, which
+// is exchanged as DER.
+type rdCleanPathPDU struct {
+	Version           int64            `asn1:"explicit,tag:0"`
+	Error             rdCleanPathError `asn1:"optional,explicit,tag:1"`
+	Destination       string           `asn1:"optional,explicit,tag:2,utf8"`
+	ProxyAuth         string           `asn1:"optional,explicit,tag:3,utf8"`
+	ServerAuth        string           `asn1:"optional,explicit,tag:4,utf8"`
+	PreconnectionBlob string           `asn1:"optional,explicit,tag:5,utf8"`
+	X224ConnectionPDU []byte           `asn1:"optional,explicit,tag:6"`
+	ServerCertChain   [][]byte         `asn1:"optional,explicit,tag:7"`
+	ServerAddr        string           `asn1:"optional,explicit,tag:9,utf8"`
+}
+
+type rdCleanPathError struct {
+	ErrorCode      int `asn1:"explicit,tag:0"`
+	HTTPStatusCode int `asn1:"optional,explicit,tag:1"`
+	WSALastError   int `asn1:"optional,explicit,tag:2"`
+	TLSAlertCode   int `asn1:"optional,explicit,tag:3"`
+}
+
+// maxRDCleanPathPDUSize bounds the request a client can make the gateway
+// buffer. Requests only carry the X.224 connection request and a few strings.
+const maxRDCleanPathPDUSize = 64 << 10
+
+// rdCleanPathConnect reads the RDCleanPath request of the client, connects to
+// the RDP server with dial, sends the X.224 connection request of the client
+// and upgrades the connection to TLS. The response carries the X.224
+// connection confirm and the certificates of the server, which the client
+// verifies itself. The returned connection is the TLS connection to the RDP
+// server, which the rest of the session is relayed to.
+//
+// Failures after the request is read are reported to the client with an
+// RDCleanPath error.
+func rdCleanPathConnect(ctx context.Context, client io.ReadWriter, dial func(ctx context.Context) (net.Conn, error)) (net.Conn, error) {
+	der, err := readDER(client, maxRDCleanPathPDUSize)
+	if err != nil {
+		return nil, xerrors.Errorf("read rdcleanpath request: %w", err)
+	}
+	var req rdCleanPathPDU
+	rest, err := asn1.Unmarshal(der, &req)
+	if err == nil && len(rest) > 0 {
+		err = xerrors.New("trailing data")
+	}
+	if err == nil && req.Version != rdCleanPathVersion {
+		err = xerrors.Errorf("unsupported version %d", req.Version)
+	}
+	if err == nil && len(req.X224ConnectionPDU) == 0 {
+		err = xerrors.New("missing X.224 connection request")
+	}
+	if err != nil {
+		_ = writeRDCleanPathError(client, rdCleanPathError{
+			ErrorCode:      rdCleanPathGeneralError,
+			HTTPStatusCode: http.StatusBadRequest,
+		}, nil)
+		return nil, xerrors.Errorf("invalid rdcleanpath request: %w", err)
+	}
+
+	server, err := dial(ctx)
+	if err != nil {
+		_ = writeRDCleanPathError(client, rdCleanPathError{
+			ErrorCode:      rdCleanPathGeneralError,
+			HTTPStatusCode: http.StatusBadGateway,
+		}, nil)
+		return nil, err
+	}
+	// The connection is closed on failure, and handed to TLS on success.
+	success := false
+	defer func() {
+		if !success {
+			_ = server.Close()
+		}
+	}()
+
+	if req.PreconnectionBlob != "" {
+		_, err = io.WriteString(server, req.PreconnectionBlob)
+		if err != nil {
+			return nil, xerrors.Errorf("write preconnection blob: %w", err)
+		}
+	}
+	_, err = server.Write(req.X224ConnectionPDU)
+	if err != nil {
+		return nil, xerrors.Errorf("write X.224 connection request: %w", err)
+	}
+	x224Confirm, err := readTPKT(server)
+	if err != nil {
+		_ = writeRDCleanPathError(client, rdCleanPathError{
+			ErrorCode:      rdCleanPathGeneralError,
+			HTTPStatusCode: http.StatusBadGateway,
+		}, nil)
+		return nil, xerrors.Errorf("read X.224 connection confirm: %w", err)
+	}
+
+	tlsConn := tls.Client(server, &tls.Config{
+		// RDP servers usually have self-signed certificates, so the client
+		// verifies the certificate chain of the response instead.
+		InsecureSkipVerify: true, //nolint:gosec // Verified by the client.
+	})
+	err = tlsConn.HandshakeContext(ctx)
+	if err != nil {
+		// The server refuses TLS when the negotiation failed, for example
+		// because it requires another security protocol. The client reads
+		// the reason from the X.224 connection confirm.
+		_ = writeRDCleanPathError(client, rdCleanPathError{
+			ErrorCode: rdCleanPathNegotiationError,
+		}, x224Confirm)
+		return nil, xerrors.Errorf("tls handshake with rdp server: %w", err)
+	}
+
+	resp := rdCleanPathPDU{
+		Version:           rdCleanPathVersion,
+		X224ConnectionPDU: x224Confirm,
+		ServerAddr:        server.RemoteAddr().String(),
+	}
+	for _, cert := range tlsConn.ConnectionState().PeerCertificates {
+		resp.ServerCertChain = append(resp.ServerCertChain, cert.Raw)
+	}
+	der, err = asn1.Marshal(resp)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal rdcleanpath response: %w", err)
+	}
+	_, err = client.Write(der)
+	if err != nil {
+		return nil, xerrors.Errorf("write rdcleanpath response: %w", err)
+	}
+	success = true
+	return tlsConn, nil
+}
+
+func writeRDCleanPathError(client io.Writer, pduErr rdCleanPathError, x224Confirm []byte) error {
+	der, err := asn1.Marshal(rdCleanPathPDU{
+		Version:           rdCleanPathVersion,
+		Error:             pduErr,
+		X224ConnectionPDU: x224Confirm,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = client.Write(der)
+	return err
+}
+
+// readDER reads a single DER element of up to max bytes.
+func readDER(r io.Reader, maxSize int) ([]byte, error) {
+	header := make([]byte, 2)
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return nil, err
+	}
+	length := int(header[1])
+	if length&0x80 != 0 {
+		// Long form: the low bits are the number of length bytes.
+		n := length & 0x7f
+		if n == 0 || n > 4 {
+			return nil, xerrors.Errorf("invalid DER length of %d bytes", n)
+		}
+		lengthBytes := make([]byte, n)
+		_, err = io.ReadFull(r, lengthBytes)
+		if err != nil {
+			return nil, err
+		}
+		header = append(header, lengthBytes...)
+		length = 0
+		for _, b := range lengthBytes {
+			length = length<<8 | int(b)
+		}
+	}
+	if length > maxSize {
+		return nil, xerrors.Errorf("DER element of %d bytes exceeds the limit of %d bytes", length, maxSize)
+	}
+	der := make([]byte, len(header)+length)
+	copy(der, header)
+	_, err = io.ReadFull(r, der[len(header):])
+	if err != nil {
+		return nil, err
+	}
+	return der, nil
+}
+
+// readTPKT reads a TPKT packet, which frames the X.224 messages of RDP.
+func readTPKT(r io.Reader) ([]byte, error) {
+	header := make([]byte, 4)
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return nil, err
+	}
+	if header[0] != 3 {
+		return nil, xerrors.Errorf("unsupported TPKT version %d", header[0])
+	}
+	length := int(binary.BigEndian.Uint16(header[2:]))
+	if length < len(header) {
+		return nil, xerrors.Errorf("invalid TPKT length %d", length)
+	}
+	packet := make([]byte, length)
+	copy(packet, header)
+	_, err = io.ReadFull(r, packet[len(header):])
+	if err != nil {
+		return nil, err
+	}
+	return packet, nil
+}
@@ -0,0 +1,230 @@
+package workspaceapps
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestRDCleanPathConnect(t *testing.T) {
+	t.Parallel()
+
+	x224Request := []byte{3, 0, 0, 19, 14, 0xe0, 0, 0, 0, 0, 0, 1, 0, 8, 0, 3, 0, 0, 0}
+	x224Confirm := []byte{3, 0, 0, 19, 14, 0xd0, 0, 0, 0x12, 0x34, 0, 2, 0, 8, 0, 1, 0, 0, 0}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		cert := rdpTestCertificate(t)
+		client, proxyClient := rdpPipe(t)
+		proxyServer, server := rdpPipe(t)
+
+		type result struct {
+			conn net.Conn
+			err  error
+		}
+		done := make(chan result, 1)
+		go func() {
+			conn, err := rdCleanPathConnect(testutil.Context(t, testutil.WaitShort), proxyClient, func(context.Context) (net.Conn, error) {
+				return proxyServer, nil
+			})
+			done <- result{conn: conn, err: err}
+		}()
+
+		writeRDCleanPath(t, client, rdCleanPathPDU{
+			Version:           rdCleanPathVersion,
+			Destination:       "workspace:3389",
+			ProxyAuth:         "ignored",
+			PreconnectionBlob: "blob",
+			X224ConnectionPDU: x224Request,
+		})
+		require.Equal(t, []byte("blob"), rfbRead(t, server, 4))
+		require.Equal(t, x224Request, rfbRead(t, server, len(x224Request)))
+		rfbWrite(t, server, x224Confirm)
+
+		tlsServer := tls.Server(server, &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		})
+		serverErr := make(chan error, 1)
+		go func() {
+			serverErr <- tlsServer.Handshake()
+		}()
+
+		resp := readRDCleanPath(t, client)
+		require.EqualValues(t, rdCleanPathVersion, resp.Version)
+		require.Zero(t, resp.Error)
+		require.Equal(t, x224Confirm, resp.X224ConnectionPDU)
+		require.Equal(t, [][]byte{cert.Certificate[0]}, resp.ServerCertChain)
+		require.NoError(t, <-serverErr)
+
+		// The rest of the session is relayed over TLS.
+		res := <-done
+		require.NoError(t, res.err)
+		go func() {
+			_, _ = res.conn.Write([]byte("mcs"))
+		}()
+		require.Equal(t, []byte("mcs"), rfbRead(t, tlsServer, 3))
+		_ = res.conn.Close()
+	})
+
+	t.Run("InvalidVersion", func(t *testing.T) {
+		t.Parallel()
+		client, proxyClient := rdpPipe(t)
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := rdCleanPathConnect(testutil.Context(t, testutil.WaitShort), proxyClient, func(context.Context) (net.Conn, error) {
+				return nil, xerrors.New("unexpected dial")
+			})
+			done <- err
+		}()
+
+		writeRDCleanPath(t, client, rdCleanPathPDU{
+			Version:           rdCleanPathVersion - 1,
+			X224ConnectionPDU: x224Request,
+		})
+		resp := readRDCleanPath(t, client)
+		require.Equal(t, rdCleanPathGeneralError, resp.Error.ErrorCode)
+		require.Equal(t, http.StatusBadRequest, resp.Error.HTTPStatusCode)
+		require.ErrorContains(t, <-done, "unsupported version")
+	})
+
+	t.Run("DialFailed", func(t *testing.T) {
+		t.Parallel()
+		client, proxyClient := rdpPipe(t)
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := rdCleanPathConnect(testutil.Context(t, testutil.WaitShort), proxyClient, func(context.Context) (net.Conn, error) {
+				return nil, xerrors.New("connection refused")
+			})
+			done <- err
+		}()
+
+		writeRDCleanPath(t, client, rdCleanPathPDU{
+			Version:           rdCleanPathVersion,
+			X224ConnectionPDU: x224Request,
+		})
+		resp := readRDCleanPath(t, client)
+		require.Equal(t, rdCleanPathGeneralError, resp.Error.ErrorCode)
+		require.Equal(t, http.StatusBadGateway, resp.Error.HTTPStatusCode)
+		require.ErrorContains(t, <-done, "connection refused")
+	})
+
+	t.Run("NegotiationFailure", func(t *testing.T) {
+		t.Parallel()
+		client, proxyClient := rdpPipe(t)
+		proxyServer, server := rdpPipe(t)
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := rdCleanPathConnect(testutil.Context(t, testutil.WaitShort), proxyClient, func(context.Context) (net.Conn, error) {
+				return proxyServer, nil
+			})
+			done <- err
+		}()
+
+		writeRDCleanPath(t, client, rdCleanPathPDU{
+			Version:           rdCleanPathVersion,
+			X224ConnectionPDU: x224Request,
+		})
+		rfbRead(t, server, len(x224Request))
+		// The server rejects the requested protocols and closes the
+		// connection instead of starting TLS.
+		negotiationFailure := []byte{3, 0, 0, 19, 14, 0xd0, 0, 0, 0x12, 0x34, 0, 3, 0, 8, 0, 5, 0, 0, 0}
+		rfbWrite(t, server, negotiationFailure)
+		_ = server.Close()
+
+		resp := readRDCleanPath(t, client)
+		require.Equal(t, rdCleanPathNegotiationError, resp.Error.ErrorCode)
+		require.Equal(t, negotiationFailure, resp.X224ConnectionPDU)
+		require.ErrorContains(t, <-done, "tls handshake")
+	})
+}
+
+func TestReadDER(t *testing.T) {
+	t.Parallel()
+
+	// Long enough for a long-form length.
+	der, err := asn1.Marshal(rdCleanPathPDU{
+		Version:           rdCleanPathVersion,
+		X224ConnectionPDU: make([]byte, 300),
+	})
+	require.NoError(t, err)
+
+	client, server := rdpPipe(t)
+	go rfbWrite(t, client, der)
+	got, err := readDER(server, maxRDCleanPathPDUSize)
+	require.NoError(t, err)
+	require.Equal(t, der, got)
+
+	go func() {
+		// The write fails when the pipe is closed after the error.
+		_, _ = client.Write(der)
+	}()
+	_, err = readDER(server, 100)
+	require.ErrorContains(t, err, "exceeds the limit")
+}
+
+func rdpPipe(t *testing.T) (net.Conn, net.Conn) {
+	t.Helper()
+	a, b := net.Pipe()
+	deadline := time.Now().Add(testutil.WaitShort)
+	for _, conn := range []net.Conn{a, b} {
+		require.NoError(t, conn.SetDeadline(deadline))
+		t.Cleanup(func() {
+			_ = conn.Close()
+		})
+	}
+	return a, b
+}
+
+func writeRDCleanPath(t *testing.T, conn net.Conn, pdu rdCleanPathPDU) {
+	t.Helper()
+	der, err := asn1.Marshal(pdu)
+	require.NoError(t, err)
+	go rfbWrite(t, conn, der)
+}
+
+func readRDCleanPath(t *testing.T, conn net.Conn) rdCleanPathPDU {
+	t.Helper()
+	der, err := readDER(conn, maxRDCleanPathPDUSize)
+	require.NoError(t, err)
+	var pdu rdCleanPathPDU
+	_, err = asn1.Unmarshal(der, &pdu)
+	require.NoError(t, err)
+	return pdu
+}
+
+func rdpTestCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "workspace"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return tls.Certificate{
+		Certificate: [][]byte{raw},
+		PrivateKey:  key,
+	}
+}
@@ -26,7 +26,7 @@ const (
 	AccessMethodPath      AccessMethod = "path"
 	AccessMethodSubdomain AccessMethod = "subdomain"
 	// AccessMethodTerminal is special since it's not a real app and only
-	// applies to the PTY endpoint on the API.
+	// applies to the PTY and desktop VNC endpoints on the API.
 	AccessMethodTerminal AccessMethod = "terminal"
 )
 
@@ -43,6 +43,14 @@ type IssueTokenRequest struct {
 	AppQuery string `json:"app_query"`
 	// SessionToken is the session token provided by the user.
 	SessionToken string `json:"session_token"`
+	// ShareLinkToken is the token of the port share link the request was made
+	// with, if any.
+	ShareLinkToken string `json:"share_link_token,omitempty"`
+	// ShareLinkPassword is the password provided for the port share link.
+	ShareLinkPassword string `json:"share_link_password,omitempty"`
+	// ShareLinkClientIP is the IP of the visitor of the port share link, used
+	// to limit password attempts.
+	ShareLinkClientIP string `json:"share_link_client_ip,omitempty"`
 }
 
 // AppBaseURL returns the base URL of this specific app request. An error is
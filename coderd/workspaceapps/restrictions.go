@@ -0,0 +1,80 @@
+package workspaceapps
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/rbac"
+)
+
+// AppRestrictions are the restrictions of the workspace apps of templates by
+// template ID and app slug. Apps without a restriction are accessible
+// according to their sharing level.
+type AppRestrictions map[uuid.UUID]map[string]database.TemplateAppRestriction
+
+// GetAppRestrictions fetches the app restrictions of the templates.
+func GetAppRestrictions(ctx context.Context, db database.Store, templateIDs []uuid.UUID) (AppRestrictions, error) {
+	// nolint:gocritic // Restrictions apply to users that can't read the
+	// template, so they are always fetched as the system.
+	restrictions, err := db.GetTemplateAppRestrictionsByTemplateIDs(dbauthz.AsSystemRestricted(ctx), templateIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get template app restrictions: %w", err)
+	}
+	appRestrictions := AppRestrictions{}
+	for _, restriction := range restrictions {
+		if appRestrictions[restriction.TemplateID] == nil {
+			appRestrictions[restriction.TemplateID] = map[string]database.TemplateAppRestriction{}
+		}
+		appRestrictions[restriction.TemplateID][restriction.AppSlug] = restriction
+	}
+	return appRestrictions, nil
+}
+
+// Allowed returns whether the subject can access the app with the slug in
+// workspaces of the template. Subjects can access restricted apps if they are
+// a member of one of the groups of the restriction, or have one of its roles
+// either site-wide or in the organization of the template. Unauthenticated
+// subjects can't access restricted apps, even public ones.
+func (r AppRestrictions) Allowed(subject *rbac.Subject, templateID, organizationID uuid.UUID, appSlug string) bool {
+	restriction, ok := r[templateID][appSlug]
+	if !ok || appSlug == "" {
+		return true
+	}
+	if subject == nil {
+		return false
+	}
+	for _, groupID := range restriction.GroupIds {
+		if slices.Contains(subject.Groups, groupID.String()) {
+			return true
+		}
+	}
+	for _, role := range subject.Roles.Names() {
+		if role.OrganizationID != uuid.Nil && role.OrganizationID != organizationID {
+			continue
+		}
+		if slices.Contains(restriction.Roles, role.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterApps returns the apps of a workspace of the template the actor of the
+// context can access.
+func (r AppRestrictions) FilterApps(ctx context.Context, templateID, organizationID uuid.UUID, apps []database.WorkspaceApp) []database.WorkspaceApp {
+	if len(r[templateID]) == 0 {
+		return apps
+	}
+	var subject *rbac.Subject
+	if actor, ok := dbauthz.ActorFromContext(ctx); ok {
+		subject = &actor
+	}
+	return slices.DeleteFunc(slices.Clone(apps), func(app database.WorkspaceApp) bool {
+		return !r.Allowed(subject, templateID, organizationID, app.Slug)
+	})
+}
@@ -0,0 +1,60 @@
+package workspaceapps_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/workspaceapps"
+)
+
+func TestAppRestrictionsAllowed(t *testing.T) {
+	t.Parallel()
+
+	templateID := uuid.New()
+	orgID := uuid.New()
+	groupID := uuid.New()
+	restrictions := workspaceapps.AppRestrictions{
+		templateID: {
+			"debug": {
+				TemplateID: templateID,
+				AppSlug:    "debug",
+				GroupIds:   []uuid.UUID{groupID},
+				Roles:      []string{rbac.RoleOwner().Name, rbac.RoleOrgAdmin()},
+			},
+		},
+	}
+	subject := func(groups []string, roles ...rbac.RoleIdentifier) *rbac.Subject {
+		return &rbac.Subject{
+			ID:     uuid.NewString(),
+			Roles:  rbac.RoleIdentifiers(roles),
+			Groups: groups,
+		}
+	}
+
+	for _, tc := range []struct {
+		name    string
+		subject *rbac.Subject
+		slug    string
+		allowed bool
+	}{
+		{name: "Unrestricted", subject: subject(nil, rbac.RoleMember()), slug: "code", allowed: true},
+		{name: "Port", subject: nil, slug: "", allowed: true},
+		{name: "Unauthenticated", subject: nil, slug: "debug", allowed: false},
+		{name: "Member", subject: subject(nil, rbac.RoleMember()), slug: "debug", allowed: false},
+		{name: "Group", subject: subject([]string{groupID.String()}, rbac.RoleMember()), slug: "debug", allowed: true},
+		{name: "SiteRole", subject: subject(nil, rbac.RoleOwner()), slug: "debug", allowed: true},
+		{name: "OrgRole", subject: subject(nil, rbac.ScopedRoleOrgAdmin(orgID)), slug: "debug", allowed: true},
+		{name: "OtherOrgRole", subject: subject(nil, rbac.ScopedRoleOrgAdmin(uuid.New())), slug: "debug", allowed: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.allowed, restrictions.Allowed(tc.subject, templateID, orgID, tc.slug))
+		})
+	}
+
+	// Apps of other templates are never restricted.
+	require.True(t, restrictions.Allowed(nil, uuid.New(), orgID, "debug"))
+}
@@ -0,0 +1,46 @@
+package workspaceapps
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+)
+
+// AppSessionPolicies are the session policies of the workspace apps of a
+// template by app slug. The policy with an empty slug applies to all apps,
+// ports and terminals of the template without their own policy.
+type AppSessionPolicies map[string]database.TemplateAppSessionPolicy
+
+// GetAppSessionPolicies fetches the app session policies of the template.
+func GetAppSessionPolicies(ctx context.Context, db database.Store, templateID uuid.UUID) (AppSessionPolicies, error) {
+	// nolint:gocritic // Policies apply to users that can't read the template,
+	// so they are always fetched as the system.
+	policies, err := db.GetTemplateAppSessionPolicies(dbauthz.AsSystemRestricted(ctx), templateID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template app session policies: %w", err)
+	}
+	appSessionPolicies := AppSessionPolicies{}
+	for _, policy := range policies {
+		appSessionPolicies[policy.AppSlug] = policy
+	}
+	return appSessionPolicies, nil
+}
+
+// MaxSessionDuration returns how long after authenticating users can access
+// the app with the slug, or false if the time isn't limited. Ports and
+// terminals have an empty slug and only use the policy of the template.
+func (p AppSessionPolicies) MaxSessionDuration(appSlug string) (time.Duration, bool) {
+	policy, ok := p[appSlug]
+	if !ok {
+		policy, ok = p[""]
+	}
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(policy.MaxSessionDuration), true
+}
@@ -0,0 +1,44 @@
+package workspaceapps_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/workspaceapps"
+)
+
+func TestAppSessionPoliciesMaxSessionDuration(t *testing.T) {
+	t.Parallel()
+
+	policies := workspaceapps.AppSessionPolicies{
+		"": {
+			AppSlug:            "",
+			MaxSessionDuration: int64(8 * time.Hour),
+		},
+		"debug": {
+			AppSlug:            "debug",
+			MaxSessionDuration: int64(time.Hour),
+		},
+	}
+
+	maxAge, ok := policies.MaxSessionDuration("debug")
+	require.True(t, ok)
+	require.Equal(t, time.Hour, maxAge)
+
+	// Apps without their own policy, ports and terminals use the policy of the
+	// template.
+	maxAge, ok = policies.MaxSessionDuration("code")
+	require.True(t, ok)
+	require.Equal(t, 8*time.Hour, maxAge)
+	maxAge, ok = policies.MaxSessionDuration("")
+	require.True(t, ok)
+	require.Equal(t, 8*time.Hour, maxAge)
+
+	delete(policies, "")
+	_, ok = policies.MaxSessionDuration("code")
+	require.False(t, ok)
+	_, ok = workspaceapps.AppSessionPolicies{}.MaxSessionDuration("debug")
+	require.False(t, ok)
+}
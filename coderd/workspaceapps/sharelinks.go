@@ -0,0 +1,449 @@
+package workspaceapps
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/httprate"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/userpassword"
+	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+	"github.com/coder/coder/v2/site"
+)
+
+const shareLinkSecretLength = 32
+
+// Wrong passwords of port share links are limited per link and per client IP,
+// so that the password of a link can't be guessed by brute force.
+const (
+	shareLinkPasswordAttemptsPerLink = 30
+	shareLinkPasswordAttemptsPerIP   = 10
+	shareLinkPasswordAttemptWindow   = time.Minute
+)
+
+var errShareLinkBandwidthExceeded = xerrors.New("port share link bandwidth limit reached")
+
+type shareLinkPasswordLimiter struct {
+	perLink *httprate.RateLimiter
+	perIP   *httprate.RateLimiter
+}
+
+func newShareLinkPasswordLimiter() *shareLinkPasswordLimiter {
+	return &shareLinkPasswordLimiter{
+		perLink: httprate.NewRateLimiter(shareLinkPasswordAttemptsPerLink, shareLinkPasswordAttemptWindow),
+		perIP:   httprate.NewRateLimiter(shareLinkPasswordAttemptsPerIP, shareLinkPasswordAttemptWindow),
+	}
+}
+
+// limited reports whether the link or the client IP has used up its wrong
+// password attempts.
+func (l *shareLinkPasswordLimiter) limited(linkID uuid.UUID, ip string) bool {
+	for _, c := range []struct {
+		limiter *httprate.RateLimiter
+		key     string
+		limit   int
+	}{
+		{l.perLink, linkID.String(), shareLinkPasswordAttemptsPerLink},
+		{l.perIP, ip, shareLinkPasswordAttemptsPerIP},
+	} {
+		if c.key == "" {
+			continue
+		}
+		_, rate, err := c.limiter.Status(c.key)
+		if err != nil || rate >= float64(c.limit) {
+			return true
+		}
+	}
+	return false
+}
+
+// failed records a wrong password attempt.
+func (l *shareLinkPasswordLimiter) failed(linkID uuid.UUID, ip string) {
+	window := time.Now().UTC().Truncate(shareLinkPasswordAttemptWindow)
+	_ = l.perLink.Counter().IncrementBy(linkID.String(), window, 1)
+	if ip != "" {
+		_ = l.perIP.Counter().IncrementBy(ip, window, 1)
+	}
+}
+
+// shareLinkClientIP returns the IP of the visitor of a port share link, used
+// to limit password attempts.
+func shareLinkClientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
+// NewShareLinkSecret generates the secret of a port share link and its hash
+// to store in the database.
+func NewShareLinkSecret() (string, []byte, error) {
+	secret, err := cryptorand.String(shareLinkSecretLength)
+	if err != nil {
+		return "", nil, xerrors.Errorf("generate secret: %w", err)
+	}
+	return secret, hashShareLinkSecret(secret), nil
+}
+
+func hashShareLinkSecret(secret string) []byte {
+	h := sha256.Sum256([]byte(secret))
+	return h[:]
+}
+
+// ShareLinkToken returns the token of a port share link, which is passed in
+// the public URL of the link.
+func ShareLinkToken(id uuid.UUID, secret string) string {
+	return id.String() + "_" + secret
+}
+
+func parseShareLinkToken(token string) (uuid.UUID, string, error) {
+	rawID, secret, ok := strings.Cut(token, "_")
+	if !ok || secret == "" {
+		return uuid.Nil, "", xerrors.New("invalid share link token format")
+	}
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return uuid.Nil, "", xerrors.Errorf("parse share link id: %w", err)
+	}
+	return id, secret, nil
+}
+
+// ShareLinkTokenFromRequest returns the port share link token stored in the
+// cookie of the request, if any.
+func ShareLinkTokenFromRequest(r *http.Request) string {
+	cookie, err := r.Cookie(codersdk.PortShareLinkCookie)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// handleShareLinkToken is called by the subdomain handler to process the
+// token of a port share link in the query parameters. Like smuggled API keys,
+// the token is moved to a cookie, and the visitor is redirected to strip the
+// query parameter. The cookie is only set on the subdomain of the port, so
+// that the link can't be used for anything else.
+func (s *Server) handleShareLinkToken(rw http.ResponseWriter, r *http.Request) bool {
+	token := r.URL.Query().Get(codersdk.PortShareLinkQueryParameter)
+	if token == "" {
+		return true
+	}
+
+	http.SetCookie(rw, s.Cookies.Apply(&http.Cookie{
+		Name:     codersdk.PortShareLinkCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   0,
+		HttpOnly: true,
+	}))
+
+	path := r.URL.Path
+	if path == "" {
+		path = "/"
+	}
+	q := r.URL.Query()
+	q.Del(codersdk.PortShareLinkQueryParameter)
+	if rawQuery := q.Encode(); rawQuery != "" {
+		path += "?" + rawQuery
+	}
+
+	http.Redirect(rw, r, path, http.StatusSeeOther)
+	return false
+}
+
+// authorizeShareLink returns the port share link of the request if it is
+// valid for the requested port. If the link requires a password and it
+// wasn't provided, the visitor is asked for it. False is returned if the
+// request should be aborted, in which case a response has been written.
+func (p *DBTokenProvider) authorizeShareLink(ctx context.Context, now time.Time, rw http.ResponseWriter, r *http.Request, issueReq IssueTokenRequest, dbReq *databaseRequest) (*database.WorkspacePortShareLink, bool) {
+	appReq := issueReq.AppRequest
+	writeInvalid := func(msg string) {
+		WriteWorkspaceApp404(p.Logger, p.DashboardURL, rw, r, &appReq, nil, "invalid port share link: "+msg)
+	}
+
+	if !p.DeploymentValues.PortShareLinks.Value() || appReq.AccessMethod != AccessMethodSubdomain {
+		writeInvalid("port share links are disabled")
+		return nil, false
+	}
+
+	id, secret, err := parseShareLinkToken(issueReq.ShareLinkToken)
+	if err != nil {
+		writeInvalid(err.Error())
+		return nil, false
+	}
+	link, err := p.Database.GetWorkspacePortShareLinkByID(ctx, id)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		writeInvalid("link not found")
+		return nil, false
+	}
+	if err != nil {
+		WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "get port share link")
+		return nil, false
+	}
+	if subtle.ConstantTimeCompare(link.HashedSecret, hashShareLinkSecret(secret)) != 1 {
+		writeInvalid("link not found")
+		return nil, false
+	}
+
+	port, protocol, isPort := appurl.ApplicationURL{AppSlugOrPort: dbReq.AppSlugOrPort}.PortInfo()
+	if !isPort ||
+		link.WorkspaceID != dbReq.Workspace.ID ||
+		link.AgentName != dbReq.Agent.Name ||
+		uint(link.Port) != port ||
+		string(link.Protocol) != protocol {
+		writeInvalid("the link is for another port")
+		return nil, false
+	}
+
+	if !link.ExpiresAt.After(now) {
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusGone,
+			Title:        "Link Expired",
+			Description:  "This port share link has expired. Ask the owner of the workspace for a new link.",
+			RetryEnabled: false,
+			DashboardURL: p.DashboardURL.String(),
+		})
+		return nil, false
+	}
+	if link.BandwidthLimitBytes > 0 && link.BytesTransferred >= link.BandwidthLimitBytes {
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusTooManyRequests,
+			Title:        "Bandwidth Limit Reached",
+			Description:  "This port share link has transferred all the data it is allowed to. Ask the owner of the workspace for a new link.",
+			RetryEnabled: false,
+			DashboardURL: p.DashboardURL.String(),
+		})
+		return nil, false
+	}
+
+	if link.HashedPassword != "" {
+		ok := false
+		if issueReq.ShareLinkPassword != "" {
+			if p.shareLinkPasswords.limited(link.ID, issueReq.ShareLinkClientIP) {
+				rw.Header().Set("Retry-After", fmt.Sprintf("%d", int(shareLinkPasswordAttemptWindow.Seconds())))
+				site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+					Status:       http.StatusTooManyRequests,
+					Title:        "Too Many Attempts",
+					Description:  "Too many wrong passwords were tried for this port share link. Try again in a minute.",
+					RetryEnabled: true,
+					DashboardURL: p.DashboardURL.String(),
+				})
+				return nil, false
+			}
+			ok, err = userpassword.Compare(link.HashedPassword, issueReq.ShareLinkPassword)
+			if err != nil {
+				WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "compare port share link password")
+				return nil, false
+			}
+			if !ok {
+				p.shareLinkPasswords.failed(link.ID, issueReq.ShareLinkClientIP)
+			}
+		}
+		if !ok {
+			rw.Header().Set("WWW-Authenticate", `Basic realm="Coder port share link", charset="UTF-8"`)
+			site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+				Status:       http.StatusUnauthorized,
+				Title:        "Password Required",
+				Description:  "This port share link is protected by a password.",
+				RetryEnabled: true,
+				DashboardURL: p.DashboardURL.String(),
+			})
+			return nil, false
+		}
+	}
+
+	return &link, true
+}
+
+// shareLinkBudgets tracks the bandwidth left for port share links with a
+// limit, shared by all the streams of a link on this server. The bytes
+// transferred are only persisted once the app stats are reported, so the
+// budget is the lowest of the tokens issued for the link and what this server
+// used since.
+type shareLinkBudgets struct {
+	mu      sync.Mutex
+	budgets map[uuid.UUID]*shareLinkBudget
+}
+
+type shareLinkBudget struct {
+	remaining atomic.Int64
+	expiresAt time.Time
+}
+
+// get returns the budget of the link of the token, or nil if the link has no
+// bandwidth limit.
+func (b *shareLinkBudgets) get(token SignedToken, now time.Time) *atomic.Int64 {
+	if token.ShareLinkBandwidthRemaining <= 0 {
+		return nil
+	}
+	var expiresAt time.Time
+	if token.Expiry != nil {
+		expiresAt = token.Expiry.Time()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.budgets == nil {
+		b.budgets = make(map[uuid.UUID]*shareLinkBudget)
+	}
+	for id, budget := range b.budgets {
+		if !budget.expiresAt.After(now) {
+			delete(b.budgets, id)
+		}
+	}
+
+	budget, ok := b.budgets[token.ShareLinkID]
+	if !ok {
+		budget = &shareLinkBudget{}
+		budget.remaining.Store(token.ShareLinkBandwidthRemaining)
+		b.budgets[token.ShareLinkID] = budget
+	} else if token.ShareLinkBandwidthRemaining < budget.remaining.Load() {
+		budget.remaining.Store(token.ShareLinkBandwidthRemaining)
+	}
+	if expiresAt.After(budget.expiresAt) {
+		budget.expiresAt = expiresAt
+	}
+	return &budget.remaining
+}
+
+// shareLinkCounter counts the bytes of a port share link stream, and stops
+// the stream once the bandwidth of the link is used up.
+type shareLinkCounter struct {
+	transferred atomic.Int64
+	// remaining is nil if the link has no bandwidth limit.
+	remaining *atomic.Int64
+}
+
+// take reserves up to n bytes of bandwidth and returns how many may be
+// transferred.
+func (c *shareLinkCounter) take(n int) int {
+	if c.remaining == nil {
+		return n
+	}
+	for {
+		left := c.remaining.Load()
+		if left <= 0 {
+			return 0
+		}
+		taken := min(int64(n), left)
+		if c.remaining.CompareAndSwap(left, left-taken) {
+			return int(taken)
+		}
+	}
+}
+
+func (c *shareLinkCounter) exhausted() bool {
+	return c.remaining != nil && c.remaining.Load() <= 0
+}
+
+// countShareLinkBytes wraps the response writer and the request body to
+// count the bytes transferred through a port share link in both directions,
+// including over upgraded connections such as WebSockets. Writes and reads
+// fail once the bandwidth limit of the link is reached, which aborts the
+// response or closes the upgraded connection.
+func countShareLinkBytes(rw http.ResponseWriter, r *http.Request, c *shareLinkCounter) (http.ResponseWriter, *http.Request) {
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = &countingReadCloser{ReadCloser: r.Body, c: c}
+	}
+	return &countingResponseWriter{ResponseWriter: rw, c: c}, r
+}
+
+type countingResponseWriter struct {
+	http.ResponseWriter
+	c *shareLinkCounter
+}
+
+func (w *countingResponseWriter) Write(b []byte) (int, error) {
+	allowed := w.c.take(len(b))
+	if allowed == 0 && len(b) > 0 {
+		return 0, errShareLinkBandwidthExceeded
+	}
+	n, err := w.ResponseWriter.Write(b[:allowed])
+	w.c.transferred.Add(int64(n))
+	if err == nil && allowed < len(b) {
+		err = errShareLinkBandwidthExceeded
+	}
+	return n, err
+}
+
+func (w *countingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &countingConn{Conn: conn, c: w.c}, brw, nil
+}
+
+func (w *countingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+type countingConn struct {
+	net.Conn
+	c *shareLinkCounter
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	if c.c.exhausted() {
+		_ = c.Conn.Close()
+		return 0, errShareLinkBandwidthExceeded
+	}
+	n, err := c.Conn.Read(b)
+	allowed := c.c.take(n)
+	c.c.transferred.Add(int64(allowed))
+	if allowed < n {
+		_ = c.Conn.Close()
+		return allowed, errShareLinkBandwidthExceeded
+	}
+	return n, err
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	allowed := c.c.take(len(b))
+	if allowed == 0 && len(b) > 0 {
+		_ = c.Conn.Close()
+		return 0, errShareLinkBandwidthExceeded
+	}
+	n, err := c.Conn.Write(b[:allowed])
+	c.c.transferred.Add(int64(n))
+	if err == nil && allowed < len(b) {
+		_ = c.Conn.Close()
+		err = errShareLinkBandwidthExceeded
+	}
+	return n, err
+}
+
+type countingReadCloser struct {
+	io.ReadCloser
+	c *shareLinkCounter
+}
+
+func (r *countingReadCloser) Read(b []byte) (int, error) {
+	if r.c.exhausted() {
+		return 0, errShareLinkBandwidthExceeded
+	}
+	n, err := r.ReadCloser.Read(b)
+	allowed := r.c.take(n)
+	r.c.transferred.Add(int64(allowed))
+	if allowed < n {
+		return allowed, errShareLinkBandwidthExceeded
+	}
+	return n, err
+}
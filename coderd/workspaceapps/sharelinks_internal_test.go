@@ -0,0 +1,141 @@
+package workspaceapps
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/jwtutils"
+)
+
+func TestShareLinkBandwidth(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	linkToken := func(id uuid.UUID, remaining int64) SignedToken {
+		return SignedToken{
+			RegisteredClaims: jwtutils.RegisteredClaims{
+				Expiry: jwt.NewNumericDate(now.Add(time.Minute)),
+			},
+			ShareLinkID:                 id,
+			ShareLinkBandwidthRemaining: remaining,
+		}
+	}
+
+	t.Run("Response", func(t *testing.T) {
+		t.Parallel()
+		var budgets shareLinkBudgets
+		c := &shareLinkCounter{remaining: budgets.get(linkToken(uuid.New(), 10), now)}
+		rec := httptest.NewRecorder()
+		rw, _ := countShareLinkBytes(rec, httptest.NewRequest("GET", "/", nil), c)
+
+		n, err := rw.Write([]byte("123456"))
+		require.NoError(t, err)
+		require.Equal(t, 6, n)
+		n, err = rw.Write([]byte("789012"))
+		require.ErrorIs(t, err, errShareLinkBandwidthExceeded)
+		require.Equal(t, 4, n)
+		_, err = rw.Write([]byte("3"))
+		require.ErrorIs(t, err, errShareLinkBandwidthExceeded)
+
+		require.Equal(t, "1234567890", rec.Body.String())
+		require.EqualValues(t, 10, c.transferred.Load())
+	})
+
+	t.Run("RequestBody", func(t *testing.T) {
+		t.Parallel()
+		var budgets shareLinkBudgets
+		c := &shareLinkCounter{remaining: budgets.get(linkToken(uuid.New(), 4), now)}
+		_, r := countShareLinkBytes(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader("123456")), c)
+
+		body, err := io.ReadAll(r.Body)
+		require.ErrorIs(t, err, errShareLinkBandwidthExceeded)
+		require.Equal(t, "1234", string(body))
+	})
+
+	t.Run("SharedAcrossStreams", func(t *testing.T) {
+		t.Parallel()
+		var budgets shareLinkBudgets
+		id := uuid.New()
+		first := &shareLinkCounter{remaining: budgets.get(linkToken(id, 10), now)}
+		rw, _ := countShareLinkBytes(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), first)
+		_, err := rw.Write([]byte("12345678"))
+		require.NoError(t, err)
+
+		// A token issued before the bytes were reported doesn't reset the
+		// budget.
+		second := &shareLinkCounter{remaining: budgets.get(linkToken(id, 10), now)}
+		rw, _ = countShareLinkBytes(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), second)
+		n, err := rw.Write([]byte("12345678"))
+		require.ErrorIs(t, err, errShareLinkBandwidthExceeded)
+		require.Equal(t, 2, n)
+	})
+
+	t.Run("Unlimited", func(t *testing.T) {
+		t.Parallel()
+		var budgets shareLinkBudgets
+		c := &shareLinkCounter{remaining: budgets.get(linkToken(uuid.New(), 0), now)}
+		require.Nil(t, c.remaining)
+		rw, _ := countShareLinkBytes(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), c)
+		n, err := rw.Write(make([]byte, 1<<20))
+		require.NoError(t, err)
+		require.Equal(t, 1<<20, n)
+	})
+
+	t.Run("UpgradedConnection", func(t *testing.T) {
+		t.Parallel()
+		var budgets shareLinkBudgets
+		c := &shareLinkCounter{remaining: budgets.get(linkToken(uuid.New(), 5), now)}
+		client, server := net.Pipe()
+		defer client.Close()
+		conn := &countingConn{Conn: server, c: c}
+
+		go func() {
+			_, _ = client.Write([]byte("abc"))
+		}()
+		buf := make([]byte, 16)
+		n, err := conn.Read(buf)
+		require.NoError(t, err)
+		require.Equal(t, "abc", string(buf[:n]))
+
+		go func() {
+			_, _ = io.ReadAll(client)
+		}()
+		n, err = conn.Write([]byte("defg"))
+		require.ErrorIs(t, err, errShareLinkBandwidthExceeded)
+		require.Equal(t, 2, n)
+
+		// The connection is closed once the limit is reached.
+		_, err = server.Write([]byte("h"))
+		require.ErrorIs(t, err, io.ErrClosedPipe)
+	})
+}
+
+func TestShareLinkPasswordLimiter(t *testing.T) {
+	t.Parallel()
+
+	l := newShareLinkPasswordLimiter()
+	link := uuid.New()
+	for range shareLinkPasswordAttemptsPerIP {
+		require.False(t, l.limited(link, "10.0.0.1"))
+		l.failed(link, "10.0.0.1")
+	}
+	require.True(t, l.limited(link, "10.0.0.1"))
+	// Other visitors of the link can still try, until the link has used up
+	// its attempts.
+	require.False(t, l.limited(link, "10.0.0.2"))
+	require.False(t, l.limited(uuid.New(), "10.0.0.2"))
+
+	for i := range shareLinkPasswordAttemptsPerLink - shareLinkPasswordAttemptsPerIP {
+		l.failed(link, fmt.Sprintf("10.0.1.%d", i))
+	}
+	require.True(t, l.limited(link, "10.0.0.2"))
+}
@@ -31,6 +31,12 @@ type StatsReport struct {
 	SessionStartedAt time.Time    `json:"session_started_at"`
 	SessionEndedAt   time.Time    `json:"session_ended_at"` // Updated periodically while app is in use active and when the last connection is closed.
 	Requests         int          `json:"requests"`
+	// ShareLinkID is set when the session was authorized by a port share
+	// link.
+	ShareLinkID uuid.UUID `json:"share_link_id,omitempty"`
+	// ShareLinkBytes is the number of bytes transferred through the share
+	// link since the session was last reported.
+	ShareLinkBytes int64 `json:"share_link_bytes,omitempty"`
 
 	rolledUp bool // Indicates if this report has been rolled up.
 }
@@ -45,6 +51,7 @@ func newStatsReportFromSignedToken(token SignedToken) StatsReport {
 		SessionID:        uuid.New(),
 		SessionStartedAt: dbtime.Now(),
 		Requests:         1,
+		ShareLinkID:      token.ShareLinkID,
 	}
 }
 
@@ -53,7 +60,8 @@ type StatsReporter interface {
 	ReportAppStats(context.Context, []StatsReport) error
 }
 
-// This should match the database unique constraint.
+// This should match the database unique constraint, with the addition of
+// the share link so that bytes are accounted for each link.
 type statsGroupKey struct {
 	StartTimeTrunc time.Time
 	UserID         uuid.UUID
@@ -61,6 +69,7 @@ type statsGroupKey struct {
 	AgentID        uuid.UUID
 	AccessMethod   AccessMethod
 	SlugOrPort     string
+	ShareLinkID    uuid.UUID
 }
 
 func (s StatsReport) groupKey(windowSize time.Duration) statsGroupKey {
@@ -71,6 +80,7 @@ func (s StatsReport) groupKey(windowSize time.Duration) statsGroupKey {
 		AgentID:        s.AgentID,
 		AccessMethod:   s.AccessMethod,
 		SlugOrPort:     s.SlugOrPort,
+		ShareLinkID:    s.ShareLinkID,
 	}
 }
 
@@ -185,6 +195,7 @@ func (sc *StatsCollector) rollup(now time.Time) []StatsReport {
 				AgentID:          g.AgentID,
 				AccessMethod:     g.AccessMethod,
 				SlugOrPort:       g.SlugOrPort,
+				ShareLinkID:      g.ShareLinkID,
 				SessionStartedAt: g.StartTimeTrunc,
 				SessionEndedAt:   g.StartTimeTrunc.Add(sc.opts.RollupWindow),
 				Requests:         0,
@@ -200,6 +211,7 @@ func (sc *StatsCollector) rollup(now time.Time) []StatsReport {
 					rolledUp.SessionID = stat.SessionID // Borrow the first session ID, useful in tests.
 				}
 				rolledUp.Requests += stat.Requests
+				rolledUp.ShareLinkBytes += stat.ShareLinkBytes
 				rollupChanged = true
 				continue
 			}
@@ -219,12 +231,14 @@ func (sc *StatsCollector) rollup(now time.Time) []StatsReport {
 				r.SessionEndedAt = now.UTC() // Use UTC like dbtime.Now().
 			}
 			report = append(report, r) // Report it (ended or incomplete).
+			stat.ShareLinkBytes = 0    // Bytes are only reported once.
 			if stat.SessionEndedAt.IsZero() {
 				newGroup = append(newGroup, stat) // Keep it for future updates.
 			}
 		}
 		if rollupChanged {
 			report = append(report, *rolledUp)
+			rolledUp.ShareLinkBytes = 0
 		}
 
 		// Future rollups should only consider the compacted group.
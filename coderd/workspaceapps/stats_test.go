@@ -160,6 +160,51 @@ func TestStatsCollector(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:           "Share link bytes rolled up per link",
+			flushIncrement: 2*rollupWindow + time.Second,
+			flushCount:     2,
+			stats: []workspaceapps.StatsReport{
+				{
+					SessionID:        rollupUUID,
+					SessionStartedAt: start,
+					SessionEndedAt:   end,
+					Requests:         1,
+					ShareLinkID:      someUUID,
+					ShareLinkBytes:   100,
+				},
+				{
+					SessionID:        uuid.New(),
+					SessionStartedAt: start,
+					SessionEndedAt:   end,
+					Requests:         1,
+					ShareLinkID:      someUUID,
+					ShareLinkBytes:   200,
+				},
+				{
+					SessionID:        rollupUUID2,
+					SessionStartedAt: start,
+					SessionEndedAt:   end,
+					Requests:         1,
+				},
+			},
+			want: []workspaceapps.StatsReport{
+				{
+					SessionID:        rollupUUID,
+					SessionStartedAt: start,
+					SessionEndedAt:   start.Add(rollupWindow),
+					Requests:         2,
+					ShareLinkID:      someUUID,
+					ShareLinkBytes:   300,
+				},
+				{
+					SessionID:        rollupUUID2,
+					SessionStartedAt: start,
+					SessionEndedAt:   start.Add(rollupWindow),
+					Requests:         1,
+				},
+			},
+		},
 		{
 			name:           "Long sessions not rolled up but reported multiple times",
 			flushIncrement: rollupWindow + time.Second,
@@ -339,6 +384,7 @@ func TestStatsCollector(t *testing.T) {
 				assert.Equal(t, want.SessionStartedAt, got.SessionStartedAt, "session started at; i = %d", i)
 				assert.Equal(t, want.SessionEndedAt, got.SessionEndedAt, "session ended at; i = %d", i)
 				assert.Equal(t, want.Requests, got.Requests, "requests; i = %d", i)
+				assert.Equal(t, want.ShareLinkBytes, got.ShareLinkBytes, "share link bytes; i = %d", i)
 			}
 		})
 	}
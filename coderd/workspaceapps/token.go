@@ -26,6 +26,13 @@ type SignedToken struct {
 	WorkspaceID uuid.UUID `json:"workspace_id"`
 	AgentID     uuid.UUID `json:"agent_id"`
 	AppURL      string    `json:"app_url"`
+	// ShareLinkID is set when the token was issued for a port share link
+	// rather than a user.
+	ShareLinkID uuid.UUID `json:"share_link_id,omitempty"`
+	// ShareLinkBandwidthRemaining is the number of bytes the port share link
+	// could still transfer when the token was issued, or 0 if the link has no
+	// bandwidth limit.
+	ShareLinkBandwidthRemaining int64 `json:"share_link_bandwidth_remaining,omitempty"`
 }
 
 // MatchesRequest returns true if the token matches the request. Any token that
@@ -90,6 +90,7 @@ func TestWorkspaceApplicationAuth(t *testing.T) {
 	cases := []struct {
 		name             string
 		accessURL        string
+		pathAppURL       string
 		appHostname      string
 		proxyURL         string
 		proxyAppHostname string
@@ -133,6 +134,16 @@ func TestWorkspaceApplicationAuth(t *testing.T) {
 			redirectURI:      "https://something--suffix.proxy.test.coder.com/",
 			expectRedirect:   "https://something--suffix.proxy.test.coder.com/",
 		},
+		{
+			name:             "PathAppAccessURLOK",
+			accessURL:        "https://test.coder.com",
+			pathAppURL:       "https://apps.coder.com",
+			appHostname:      "*.test.coder.com",
+			proxyURL:         "https://proxy.test.coder.com",
+			proxyAppHostname: "*.proxy.test.coder.com",
+			redirectURI:      "http://apps.coder.com/@user/ws/apps/code/",
+			expectRedirect:   "https://apps.coder.com/@user/ws/apps/code/",
+		},
 		{
 			name:             "NormalizeSchemePrimaryAppHostname",
 			accessURL:        "https://test.coder.com",
@@ -188,6 +199,11 @@ func TestWorkspaceApplicationAuth(t *testing.T) {
 			logger := testutil.Logger(t)
 			accessURL, err := url.Parse(c.accessURL)
 			require.NoError(t, err)
+			var pathAppURL *url.URL
+			if c.pathAppURL != "" {
+				pathAppURL, err = url.Parse(c.pathAppURL)
+				require.NoError(t, err)
+			}
 
 			db, ps := dbtestutil.NewDB(t)
 			fetcher := &cryptokeys.DBFetcher{
@@ -201,6 +217,7 @@ func TestWorkspaceApplicationAuth(t *testing.T) {
 
 			client := coderdtest.New(t, &coderdtest.Options{
 				AccessURL:             accessURL,
+				PathAppAccessURL:      pathAppURL,
 				AppHostname:           c.appHostname,
 				Database:              db,
 				Pubsub:                ps,
@@ -267,3 +284,117 @@ func TestWorkspaceApplicationAuth(t *testing.T) {
 		})
 	}
 }
+
+func TestPathAppAccessURL(t *testing.T) {
+	t.Parallel()
+
+	pathAppURL, err := url.Parse("https://apps.coder.com")
+	require.NoError(t, err)
+	client := coderdtest.New(t, &coderdtest.Options{
+		PathAppAccessURL: pathAppURL,
+	})
+	// Disable redirects.
+	client.HTTPClient.CheckRedirect = func(_ *http.Request, _ []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	get := func(ctx context.Context, t *testing.T, host, path string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.URL.String()+path, nil)
+		require.NoError(t, err)
+		req.Host = host
+		resp, err := client.HTTPClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("AppOnAccessURL", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp := get(ctx, t, client.URL.Host, "/@user/ws/apps/code/index.html?folder=/home")
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		require.Equal(t, "https://apps.coder.com/@user/ws/apps/code/index.html?folder=/home", resp.Header.Get("Location"))
+	})
+
+	t.Run("DashboardOnPathAppAccessURL", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp := get(ctx, t, pathAppURL.Host, "/api/v2/buildinfo")
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		require.Equal(t, client.URL.String()+"/api/v2/buildinfo", resp.Header.Get("Location"))
+	})
+}
+
+func TestPathAppSubdomains(t *testing.T) {
+	t.Parallel()
+
+	pathAppURL, err := url.Parse("https://apps.coder.com")
+	require.NoError(t, err)
+	dv := coderdtest.DeploymentValues(t)
+	dv.PathAppSubdomains = true
+	client := coderdtest.New(t, &coderdtest.Options{
+		PathAppAccessURL: pathAppURL,
+		DeploymentValues: dv,
+	})
+	_ = coderdtest.CreateFirstUser(t, client)
+	// Disable redirects.
+	client.HTTPClient.CheckRedirect = func(_ *http.Request, _ []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	get := func(ctx context.Context, t *testing.T, host, path string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.URL.String()+path, nil)
+		require.NoError(t, err)
+		req.Host = host
+		req.Header.Set(codersdk.SessionTokenHeader, client.SessionToken())
+		resp, err := client.HTTPClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+	subdomainRedirect := func(t *testing.T, resp *http.Response, path string) *url.URL {
+		t.Helper()
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		loc, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, "https", loc.Scheme)
+		require.Regexp(t, `^[0-9]+-[0-9a-z]+-[a-z2-7]+\.apps\.coder\.com$`, loc.Host)
+		require.Equal(t, path, loc.RequestURI())
+		return loc
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	const appPath = "/@user/ws/apps/code/index.html?folder=/home"
+
+	// Apps are redirected from the dashboard and the path app access URL to
+	// their signed subdomain.
+	loc := subdomainRedirect(t, get(ctx, t, client.URL.Host, appPath), appPath)
+	subdomainRedirect(t, get(ctx, t, pathAppURL.Host, appPath), appPath)
+
+	// The app is served from its subdomain.
+	resp := get(ctx, t, loc.Host, appPath)
+	require.NotEqual(t, http.StatusTemporaryRedirect, resp.StatusCode)
+
+	// Another app isn't served from the subdomain, nor are forged subdomains.
+	const otherPath = "/@user/ws/apps/terminal/"
+	subdomainRedirect(t, get(ctx, t, loc.Host, otherPath), otherPath)
+	subdomainRedirect(t, get(ctx, t, "1-zzzzzz-aaaa.apps.coder.com", appPath), appPath)
+
+	// The dashboard isn't served from subdomains.
+	resp = get(ctx, t, loc.Host, "/api/v2/buildinfo")
+	require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+	require.Equal(t, client.URL.String()+"/api/v2/buildinfo", resp.Header.Get("Location"))
+
+	// API keys can be smuggled to subdomains.
+	redirectURI := "https://" + loc.Host + "/@user/ws/apps/code/"
+	resp = get(ctx, t, client.URL.Host, "/api/v2/applications/auth-redirect?redirect_uri="+url.QueryEscape(redirectURI))
+	require.Equal(t, http.StatusSeeOther, resp.StatusCode)
+	smuggled, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, loc.Host, smuggled.Host)
+	require.NotEmpty(t, smuggled.Query().Get(workspaceapps.SubdomainProxyAPIKeyParam))
+}
@@ -0,0 +1,292 @@
+// Package workspacearchive archives the persistent paths of dormant workspaces
+// to object storage before they are deleted, and restores the archives into
+// other workspaces.
+package workspacearchive
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/quartz"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/objectstore"
+)
+
+const (
+	// agentReadyTimeout is how long the agent of a workspace that was started
+	// for archiving may take to become ready before the archive is recorded
+	// as failed.
+	agentReadyTimeout = 30 * time.Minute
+	// archiveTimeout bounds how long streaming a single archive may take.
+	archiveTimeout = 2 * time.Hour
+	// purgeInterval is how often expired archives are deleted.
+	purgeInterval = time.Hour
+)
+
+// ErrAgentNotReady is returned by Archive when the workspace agent has not
+// become ready yet. Archive should be retried later.
+var ErrAgentNotReady = xerrors.New("workspace agent is not ready")
+
+// AgentConn is the subset of *workspacesdk.AgentConn used to stream archives.
+type AgentConn interface {
+	Archive(ctx context.Context, paths []string) (io.ReadCloser, error)
+	RestoreArchive(ctx context.Context, r io.Reader) error
+}
+
+// DialAgentFunc connects to a workspace agent. The returned function releases
+// the connection.
+type DialAgentFunc func(ctx context.Context, agentID uuid.UUID) (AgentConn, func(), error)
+
+type Options struct {
+	Database  database.Store
+	Store     objectstore.Store
+	Logger    slog.Logger
+	Clock     quartz.Clock
+	DialAgent DialAgentFunc
+	// Retention is how long archives are kept before they are purged.
+	Retention time.Duration
+	// AgentInactiveDisconnectTimeout is used to determine whether an agent is
+	// connected.
+	AgentInactiveDisconnectTimeout time.Duration
+}
+
+// Archiver snapshots workspaces into a Store and records the archives in the
+// database. Archives are purged once their retention period has passed.
+type Archiver struct {
+	opts Options
+	ctx  context.Context
+
+	mu sync.Mutex
+	// inProgress holds the IDs of workspaces which are currently being
+	// archived.
+	inProgress map[uuid.UUID]struct{}
+	wg         sync.WaitGroup
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New starts an Archiver which purges expired archives every hour until it is
+// closed.
+func New(ctx context.Context, opts Options) *Archiver {
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	//nolint:gocritic // The archiver acts on dormant workspaces on behalf of the system.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	a := &Archiver{
+		opts:       opts,
+		ctx:        ctx,
+		inProgress: make(map[uuid.UUID]struct{}),
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+
+	go func() {
+		defer close(a.done)
+		tkr := a.opts.Clock.TickerFunc(ctx, purgeInterval, func() error {
+			if err := a.PurgeExpired(ctx); err != nil {
+				a.opts.Logger.Warn(ctx, "purge expired workspace archives", slog.Error(err))
+			}
+			return nil
+		}, "workspacearchive", "purge")
+		_ = tkr.Wait()
+	}()
+	return a
+}
+
+// Archive starts archiving the paths of a workspace in the background, unless
+// the workspace is already being archived. The outcome is recorded as a
+// workspace archive, including failures, so callers know when to stop
+// waiting. ErrAgentNotReady is returned while the agent is still starting.
+func (a *Archiver) Archive(_ context.Context, ws database.Workspace, paths []string) error {
+	ctx := a.ctx
+
+	a.mu.Lock()
+	_, running := a.inProgress[ws.ID]
+	a.mu.Unlock()
+	if running {
+		return nil
+	}
+
+	agents, err := a.opts.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, ws.ID)
+	if err != nil {
+		return xerrors.Errorf("get workspace agents: %w", err)
+	}
+	var agent database.WorkspaceAgent
+	for _, candidate := range agents {
+		// Sub agents, e.g. dev containers, do not own the workspace's
+		// persistent paths.
+		if !candidate.ParentID.Valid {
+			agent = candidate
+			break
+		}
+	}
+	if agent.ID == uuid.Nil {
+		return a.record(ctx, ws, paths, "", 0, xerrors.New("workspace has no agents"))
+	}
+	if !a.agentReady(agent) {
+		if a.opts.Clock.Since(agent.CreatedAt) < agentReadyTimeout {
+			return ErrAgentNotReady
+		}
+		return a.record(ctx, ws, paths, "", 0, xerrors.Errorf("agent %q did not become ready within %s", agent.Name, agentReadyTimeout))
+	}
+
+	a.mu.Lock()
+	if _, running := a.inProgress[ws.ID]; running {
+		a.mu.Unlock()
+		return nil
+	}
+	a.inProgress[ws.ID] = struct{}{}
+	a.wg.Add(1)
+	a.mu.Unlock()
+
+	go func() {
+		defer a.wg.Done()
+		defer func() {
+			a.mu.Lock()
+			delete(a.inProgress, ws.ID)
+			a.mu.Unlock()
+		}()
+
+		key, size, err := a.archive(ctx, agent.ID, paths)
+		if err != nil {
+			a.opts.Logger.Warn(ctx, "archive dormant workspace",
+				slog.F("workspace_id", ws.ID), slog.F("agent_id", agent.ID), slog.Error(err))
+		}
+		if err := a.record(ctx, ws, paths, key, size, err); err != nil {
+			a.opts.Logger.Error(ctx, "record workspace archive", slog.F("workspace_id", ws.ID), slog.Error(err))
+		}
+	}()
+	return nil
+}
+
+func (a *Archiver) agentReady(agent database.WorkspaceAgent) bool {
+	if agent.Status(a.opts.AgentInactiveDisconnectTimeout).Status != database.WorkspaceAgentStatusConnected {
+		return false
+	}
+	switch agent.LifecycleState {
+	case database.WorkspaceAgentLifecycleStateCreated, database.WorkspaceAgentLifecycleStateStarting:
+		return false
+	default:
+		return true
+	}
+}
+
+func (a *Archiver) archive(ctx context.Context, agentID uuid.UUID, paths []string) (string, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, archiveTimeout)
+	defer cancel()
+
+	conn, release, err := a.opts.DialAgent(ctx, agentID)
+	if err != nil {
+		return "", 0, xerrors.Errorf("dial agent: %w", err)
+	}
+	defer release()
+
+	rc, err := conn.Archive(ctx, paths)
+	if err != nil {
+		return "", 0, xerrors.Errorf("request archive: %w", err)
+	}
+	defer rc.Close()
+
+	key := uuid.NewString() + ".tar.gz"
+	size, err := a.opts.Store.Put(ctx, key, rc)
+	if err != nil {
+		// Don't leave partial uploads behind.
+		_ = a.opts.Store.Delete(ctx, key)
+		return "", 0, xerrors.Errorf("store archive: %w", err)
+	}
+	return key, size, nil
+}
+
+func (a *Archiver) record(ctx context.Context, ws database.Workspace, paths []string, key string, size int64, archiveErr error) error {
+	now := dbtime.Time(a.opts.Clock.Now())
+	params := database.InsertWorkspaceArchiveParams{
+		ID:             uuid.New(),
+		WorkspaceID:    ws.ID,
+		WorkspaceName:  ws.Name,
+		OwnerID:        ws.OwnerID,
+		OrganizationID: ws.OrganizationID,
+		TemplateID:     ws.TemplateID,
+		Paths:          paths,
+		ObjectKey:      key,
+		SizeBytes:      size,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(a.opts.Retention),
+	}
+	if archiveErr != nil {
+		params.Error = archiveErr.Error()
+	}
+	_, err := a.opts.Database.InsertWorkspaceArchive(ctx, params)
+	if err != nil {
+		return xerrors.Errorf("insert workspace archive: %w", err)
+	}
+	return nil
+}
+
+// Restore streams a successful archive to the agent, which extracts it over
+// the existing files.
+func (a *Archiver) Restore(ctx context.Context, archive database.WorkspaceArchive, agentID uuid.UUID) error {
+	if archive.Error != "" {
+		return xerrors.Errorf("archive failed: %s", archive.Error)
+	}
+	if archive.ObjectKey == "" {
+		return xerrors.New("archive has no content")
+	}
+
+	rc, err := a.opts.Store.Get(ctx, archive.ObjectKey)
+	if err != nil {
+		return xerrors.Errorf("get archive: %w", err)
+	}
+	defer rc.Close()
+
+	conn, release, err := a.opts.DialAgent(ctx, agentID)
+	if err != nil {
+		return xerrors.Errorf("dial agent: %w", err)
+	}
+	defer release()
+
+	err = conn.RestoreArchive(ctx, rc)
+	if err != nil {
+		return xerrors.Errorf("restore archive: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpired deletes the archives whose retention period has passed.
+func (a *Archiver) PurgeExpired(ctx context.Context) error {
+	archives, err := a.opts.Database.GetWorkspaceArchivesExpiredBefore(ctx, dbtime.Time(a.opts.Clock.Now()))
+	if err != nil {
+		return xerrors.Errorf("get expired workspace archives: %w", err)
+	}
+	for _, archive := range archives {
+		if archive.ObjectKey != "" {
+			if err := a.opts.Store.Delete(ctx, archive.ObjectKey); err != nil {
+				return xerrors.Errorf("delete archive %s: %w", archive.ID, err)
+			}
+		}
+		if err := a.opts.Database.DeleteWorkspaceArchiveByID(ctx, archive.ID); err != nil {
+			return xerrors.Errorf("delete workspace archive %s: %w", archive.ID, err)
+		}
+	}
+	return nil
+}
+
+// Close stops purging archives and waits for in-progress archives to finish.
+func (a *Archiver) Close() error {
+	a.cancel()
+	<-a.done
+	a.wg.Wait()
+	return nil
+}
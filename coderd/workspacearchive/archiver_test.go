@@ -0,0 +1,171 @@
+package workspacearchive_test
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/quartz"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/objectstore"
+	"github.com/coder/coder/v2/coderd/workspacearchive"
+	"github.com/coder/coder/v2/testutil"
+)
+
+type fakeAgentConn struct {
+	archive  string
+	restored chan string
+}
+
+func (c *fakeAgentConn) Archive(context.Context, []string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(c.archive)), nil
+}
+
+func (c *fakeAgentConn) RestoreArchive(_ context.Context, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	c.restored <- string(data)
+	return nil
+}
+
+func TestArchiver(t *testing.T) {
+	t.Parallel()
+
+	now := dbtime.Now()
+	ws := database.Workspace{
+		ID:             uuid.New(),
+		Name:           "dormant",
+		OwnerID:        uuid.New(),
+		OrganizationID: uuid.New(),
+		TemplateID:     uuid.New(),
+	}
+	paths := []string{"~/project"}
+
+	setup := func(t *testing.T, db database.Store, conn *fakeAgentConn) *workspacearchive.Archiver {
+		t.Helper()
+		clock := quartz.NewMock(t)
+		clock.Set(now)
+		store, err := objectstore.NewDirStore(t.TempDir())
+		require.NoError(t, err)
+		archiver := workspacearchive.New(context.Background(), workspacearchive.Options{
+			Database: db,
+			Store:    store,
+			Logger:   slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}),
+			Clock:    clock,
+			DialAgent: func(context.Context, uuid.UUID) (workspacearchive.AgentConn, func(), error) {
+				return conn, func() {}, nil
+			},
+			Retention:                      24 * time.Hour,
+			AgentInactiveDisconnectTimeout: time.Minute,
+		})
+		t.Cleanup(func() { _ = archiver.Close() })
+		return archiver
+	}
+
+	t.Run("NoAgents", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		db := dbmock.NewMockStore(ctrl)
+		db.EXPECT().GetWorkspaceAgentsInLatestBuildByWorkspaceID(gomock.Any(), ws.ID).Return(nil, nil)
+		db.EXPECT().InsertWorkspaceArchive(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg database.InsertWorkspaceArchiveParams) (database.WorkspaceArchive, error) {
+				require.Equal(t, ws.ID, arg.WorkspaceID)
+				require.Equal(t, "workspace has no agents", arg.Error)
+				require.Empty(t, arg.ObjectKey)
+				require.Equal(t, arg.CreatedAt.Add(24*time.Hour), arg.ExpiresAt)
+				return database.WorkspaceArchive{}, nil
+			})
+
+		archiver := setup(t, db, nil)
+		require.NoError(t, archiver.Archive(context.Background(), ws, paths))
+	})
+
+	t.Run("AgentNotReady", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		db := dbmock.NewMockStore(ctrl)
+		db.EXPECT().GetWorkspaceAgentsInLatestBuildByWorkspaceID(gomock.Any(), ws.ID).Return([]database.WorkspaceAgent{{
+			ID:             uuid.New(),
+			CreatedAt:      now.Add(-time.Minute),
+			LifecycleState: database.WorkspaceAgentLifecycleStateCreated,
+		}}, nil)
+
+		archiver := setup(t, db, nil)
+		require.ErrorIs(t, archiver.Archive(context.Background(), ws, paths), workspacearchive.ErrAgentNotReady)
+	})
+
+	t.Run("AgentTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		db := dbmock.NewMockStore(ctrl)
+		db.EXPECT().GetWorkspaceAgentsInLatestBuildByWorkspaceID(gomock.Any(), ws.ID).Return([]database.WorkspaceAgent{{
+			ID:             uuid.New(),
+			Name:           "main",
+			CreatedAt:      now.Add(-time.Hour),
+			LifecycleState: database.WorkspaceAgentLifecycleStateCreated,
+		}}, nil)
+		db.EXPECT().InsertWorkspaceArchive(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg database.InsertWorkspaceArchiveParams) (database.WorkspaceArchive, error) {
+				require.Contains(t, arg.Error, "did not become ready")
+				return database.WorkspaceArchive{}, nil
+			})
+
+		archiver := setup(t, db, nil)
+		require.NoError(t, archiver.Archive(context.Background(), ws, paths))
+	})
+
+	t.Run("ArchiveAndRestore", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ctrl := gomock.NewController(t)
+		db := dbmock.NewMockStore(ctrl)
+		agentID := uuid.New()
+		db.EXPECT().GetWorkspaceAgentsInLatestBuildByWorkspaceID(gomock.Any(), ws.ID).Return([]database.WorkspaceAgent{{
+			ID:               agentID,
+			CreatedAt:        now.Add(-time.Minute),
+			FirstConnectedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+			LastConnectedAt:  sql.NullTime{Time: dbtime.Now(), Valid: true},
+			LifecycleState:   database.WorkspaceAgentLifecycleStateReady,
+		}}, nil)
+		inserted := make(chan database.InsertWorkspaceArchiveParams, 1)
+		db.EXPECT().InsertWorkspaceArchive(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg database.InsertWorkspaceArchiveParams) (database.WorkspaceArchive, error) {
+				inserted <- arg
+				return database.WorkspaceArchive{}, nil
+			})
+
+		conn := &fakeAgentConn{archive: "archive", restored: make(chan string, 1)}
+		archiver := setup(t, db, conn)
+		require.NoError(t, archiver.Archive(ctx, ws, paths))
+
+		arg := testutil.RequireReceive(ctx, t, inserted)
+		require.Empty(t, arg.Error)
+		require.NotEmpty(t, arg.ObjectKey)
+		require.EqualValues(t, len("archive"), arg.SizeBytes)
+		require.Equal(t, paths, arg.Paths)
+
+		err := archiver.Restore(ctx, database.WorkspaceArchive{ObjectKey: arg.ObjectKey}, agentID)
+		require.NoError(t, err)
+		require.Equal(t, "archive", testutil.RequireReceive(ctx, t, conn.restored))
+
+		err = archiver.Restore(ctx, database.WorkspaceArchive{Error: "failed"}, agentID)
+		require.ErrorContains(t, err, "archive failed")
+	})
+}
@@ -0,0 +1,155 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace archives by user
+// @ID get-workspace-archives-by-user
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.WorkspaceArchive
+// @Router /users/{user}/workspace-archives [get]
+func (api *API) workspaceArchivesByUser(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	archives, err := api.Database.GetWorkspaceArchivesByOwnerID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace archives.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(archives, convertWorkspaceArchive))
+}
+
+// @Summary Restore workspace archive
+// @ID restore-workspace-archive
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.RestoreWorkspaceArchiveRequest true "Restore workspace archive request"
+// @Success 204
+// @Router /workspaces/{workspace}/restore-archive [post]
+func (api *API) postWorkspaceRestoreArchive(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	var req codersdk.RestoreWorkspaceArchiveRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if api.WorkspaceArchiver == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Workspace archiving is not enabled on this deployment.",
+		})
+		return
+	}
+	// Restoring writes files into the workspace, which is equivalent to
+	// connecting to it.
+	if !api.Authorize(r, policy.ActionSSH, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	archive, err := api.Database.GetWorkspaceArchiveByID(ctx, req.ArchiveID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace archive.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if archive.Error != "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The workspace could not be archived, so the archive cannot be restored.",
+			Detail:  archive.Error,
+		})
+		return
+	}
+
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil && !httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	agent, ok := workspaceAgentByName(agents, req.AgentName)
+	if !ok {
+		detail := "The workspace has no agents."
+		if req.AgentName != "" {
+			detail = fmt.Sprintf("The workspace has no agent named %q.", req.AgentName)
+		}
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Workspace agent not found.",
+			Validations: []codersdk.ValidationError{
+				{Field: "agent_name", Detail: detail},
+			},
+		})
+		return
+	}
+	if status := agent.Status(api.AgentInactiveDisconnectTimeout).Status; status != database.WorkspaceAgentStatusConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", status, database.WorkspaceAgentStatusConnected),
+		})
+		return
+	}
+
+	err = api.WorkspaceArchiver.Restore(ctx, archive, agent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error restoring workspace archive.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// workspaceAgentByName returns the agent with the given name, or the first
+// agent that is not a sub agent if name is empty.
+func workspaceAgentByName(agents []database.WorkspaceAgent, name string) (database.WorkspaceAgent, bool) {
+	for _, agent := range agents {
+		if (name == "" && !agent.ParentID.Valid) || (name != "" && agent.Name == name) {
+			return agent, true
+		}
+	}
+	return database.WorkspaceAgent{}, false
+}
+
+func convertWorkspaceArchive(archive database.WorkspaceArchive) codersdk.WorkspaceArchive {
+	return codersdk.WorkspaceArchive{
+		ID:             archive.ID,
+		WorkspaceID:    archive.WorkspaceID,
+		WorkspaceName:  archive.WorkspaceName,
+		OwnerID:        archive.OwnerID,
+		OrganizationID: archive.OrganizationID,
+		TemplateID:     archive.TemplateID,
+		Paths:          archive.Paths,
+		SizeBytes:      archive.SizeBytes,
+		Error:          archive.Error,
+		CreatedAt:      archive.CreatedAt,
+		ExpiresAt:      archive.ExpiresAt,
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/coderd/wspubsub"
 	"github.com/coder/coder/v2/codersdk"
@@ -328,20 +329,107 @@ func (api *API) workspaceBuildByBuildNumber(rw http.ResponseWriter, r *http.Requ
 // @Router /workspaces/{workspace}/builds [post]
 func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	apiKey := httpmw.APIKey(r)
 	workspace := httpmw.WorkspaceParam(r)
 	var createBuild codersdk.CreateWorkspaceBuildRequest
 	if !httpapi.Read(ctx, rw, r, &createBuild) {
 		return
 	}
 
+	apiBuild, ok := api.postWorkspaceBuildsInternal(rw, r, workspace, createBuild)
+	if !ok {
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, apiBuild)
+}
+
+// @Summary Roll back workspace to its last successful build
+// @Description Starts the workspace with the template version and parameter
+// @Description values of its last successful start build before its latest
+// @Description start build.
+// @ID roll-back-workspace
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Builds
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.RollbackWorkspaceRequest true "Roll back workspace request"
+// @Success 201 {object} codersdk.WorkspaceBuild
+// @Router /workspaces/{workspace}/rollback [post]
+func (api *API) postWorkspaceRollback(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	var req codersdk.RollbackWorkspaceRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	target, err := api.Database.GetRollbackWorkspaceBuild(ctx, workspace.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The workspace has no previous successful build to roll back to.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching previous successful workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	parameters, err := api.Database.GetWorkspaceBuildParameters(ctx, target.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build parameters.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiBuild, ok := api.postWorkspaceBuildsInternal(rw, r, workspace, codersdk.CreateWorkspaceBuildRequest{
+		TemplateVersionID:       target.TemplateVersionID,
+		Transition:              codersdk.WorkspaceTransitionStart,
+		RichParameterValues:     db2sdk.WorkspaceBuildParameters(parameters),
+		LogLevel:                req.LogLevel,
+		TemplateVersionPresetID: target.TemplateVersionPresetID.UUID,
+		ReasonMessage:           req.ReasonMessage,
+		CIJobURL:                req.CIJobURL,
+	})
+	if !ok {
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, apiBuild)
+}
+
+// postWorkspaceBuildsInternal creates a workspace build and writes any error
+// to rw. The caller writes the build on success.
+func (api *API) postWorkspaceBuildsInternal(
+	rw http.ResponseWriter,
+	r *http.Request,
+	workspace database.Workspace,
+	createBuild codersdk.CreateWorkspaceBuildRequest,
+) (codersdk.WorkspaceBuild, bool) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+
+	// Only named API tokens are recorded, browser and CLI sessions have no
+	// meaningful name.
+	var apiKeyName string
+	if apiKey.LoginType == database.LoginTypeToken {
+		apiKeyName = apiKey.TokenName
+	}
+
 	builder := wsbuilder.New(workspace, database.WorkspaceTransition(createBuild.Transition)).
 		Initiator(apiKey.UserID).
+		ReasonMessage(createBuild.ReasonMessage).
+		InitiatorContext(apiKeyName, createBuild.CIJobURL).
 		RichParameterValues(createBuild.RichParameterValues).
 		LogLevel(string(createBuild.LogLevel)).
 		DeploymentValues(api.Options.DeploymentValues).
-		Experiments(api.Experiments).
-		TemplateVersionPresetID(createBuild.TemplateVersionPresetID)
+		Experiments(api.ExperimentFlags.ExperimentsFor(ctx)).
+		TemplateVersionPresetID(createBuild.TemplateVersionPresetID).
+		ParameterValidation(api.HTTPClient)
 
 	var (
 		previousWorkspaceBuild database.WorkspaceBuild
@@ -350,7 +438,27 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		provisionerDaemons     []database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow
 	)
 
-	err := api.Database.InTx(func(tx database.Store) error {
+	// Adopted machines are not managed by a provisioner, so their workspaces
+	// can only be deleted, which leaves the machine running.
+	adopted, err := isAdoptedWorkspace(ctx, api.Database, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace adoption.",
+			Detail:  err.Error(),
+		})
+		return codersdk.WorkspaceBuild{}, false
+	}
+	if adopted {
+		if createBuild.Transition != codersdk.WorkspaceTransitionDelete {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Adopted workspaces are managed outside of Coder and can only be deleted.",
+			})
+			return codersdk.WorkspaceBuild{}, false
+		}
+		createBuild.Orphan = true
+	}
+
+	err = api.Database.InTx(func(tx database.Store) error {
 		var err error
 
 		previousWorkspaceBuild, err = tx.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
@@ -408,7 +516,7 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 	}, nil)
 	if err != nil {
 		httperror.WriteWorkspaceBuildError(ctx, rw, err)
-		return
+		return codersdk.WorkspaceBuild{}, false
 	}
 
 	var queuePos database.GetProvisionerJobsByIDsWithQueuePositionRow
@@ -434,6 +542,10 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 				BuildReason:    workspaceBuild.Reason,
 				WorkspaceID:    workspace.ID,
 				WorkspaceOwner: workspace.OwnerName,
+
+				BuildReasonMessage:  workspaceBuild.ReasonMessage,
+				InitiatorAPIKeyName: workspaceBuild.InitiatorAPIKeyName,
+				InitiatorCIJobURL:   workspaceBuild.InitiatorCIJobURL,
 			}
 			briBytes, err := json.Marshal(buildResourceInfo)
 			if err != nil {
@@ -476,7 +588,7 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 			Message: "Internal error converting workspace build.",
 			Detail:  err.Error(),
 		})
-		return
+		return codersdk.WorkspaceBuild{}, false
 	}
 
 	// If this workspace build has a different template version ID to the previous build
@@ -503,7 +615,7 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		WorkspaceID: workspace.ID,
 	})
 
-	httpapi.Write(ctx, rw, http.StatusCreated, apiBuild)
+	return apiBuild, true
 }
 
 func (api *API) notifyWorkspaceUpdated(
@@ -831,6 +943,86 @@ func (api *API) workspaceBuildTimings(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, timings)
 }
 
+// @Summary Get workspace build queue position by ID
+// @ID get-workspace-build-queue-position-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspacebuild path string true "Workspace build ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceBuildQueue
+// @Router /workspacebuilds/{workspacebuild}/queue [get]
+func (api *API) workspaceBuildQueue(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		build     = httpmw.WorkspaceBuildParam(r)
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	jobs, err := api.Database.GetProvisionerJobsByIDsWithQueuePosition(ctx, database.GetProvisionerJobsByIDsWithQueuePositionParams{
+		IDs:             []uuid.UUID{build.JobID},
+		StaleIntervalMS: provisionerdserver.StaleInterval.Milliseconds(),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(jobs) == 0 {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  "No job found for workspace build.",
+		})
+		return
+	}
+	job := jobs[0]
+
+	queue := codersdk.WorkspaceBuildQueue{
+		Status: codersdk.ProvisionerJobStatus(job.ProvisionerJob.JobStatus),
+		Tags:   job.ProvisionerJob.Tags,
+	}
+	if job.ProvisionerJob.JobStatus != database.ProvisionerJobStatusPending {
+		httpapi.Write(ctx, rw, http.StatusOK, queue)
+		return
+	}
+
+	daemons, err := api.Database.GetEligibleProvisionerDaemonsByProvisionerJobIDs(ctx, []uuid.UUID{job.ProvisionerJob.ID})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner daemons.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	matched := make([]database.ProvisionerDaemon, 0, len(daemons))
+	for _, daemon := range daemons {
+		matched = append(matched, daemon.ProvisionerDaemon)
+	}
+
+	now := api.Clock.Now()
+	queue.Position = int(job.QueuePosition)
+	queue.Size = int(job.QueueSize)
+	queue.MatchedProvisioners = db2sdk.MatchedProvisioners(matched, now, provisionerdserver.StaleInterval)
+	buildTime := api.metricsCache.TemplateBuildTimeStats(workspace.TemplateID)[codersdk.WorkspaceTransition(build.Transition)]
+	queue.EstimatedStartAt = estimateQueueStart(now, queue.Position, queue.MatchedProvisioners, buildTime.P50)
+
+	httpapi.Write(ctx, rw, http.StatusOK, queue)
+}
+
+// estimateQueueStart estimates when the job at the given queue position
+// starts. The available provisioners take one job each per round, and each
+// round is assumed to take the median build duration of the template. It
+// returns nil when there is nothing to estimate from.
+func estimateQueueStart(now time.Time, position int, matched codersdk.MatchedProvisioners, medianMillis *int64) *time.Time {
+	if position <= 0 || matched.Available <= 0 || medianMillis == nil {
+		return nil
+	}
+	rounds := (position - 1) / matched.Available
+	startAt := now.Add(time.Duration(rounds) * time.Duration(*medianMillis) * time.Millisecond)
+	return &startAt
+}
+
 type workspaceBuildsData struct {
 	jobs               []database.GetProvisionerJobsByIDsWithQueuePositionRow
 	templateVersions   []database.TemplateVersion
@@ -952,6 +1144,11 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaceBuilds []datab
 		return workspaceBuildsData{}, err
 	}
 
+	apps, err = api.filterRestrictedBuildApps(ctx, workspaceBuilds, templateVersions, resources, agents, apps)
+	if err != nil {
+		return workspaceBuildsData{}, err
+	}
+
 	appIDs := make([]uuid.UUID, 0)
 	for _, app := range apps {
 		appIDs = append(appIDs, app.ID)
@@ -977,6 +1174,50 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaceBuilds []datab
 	}, nil
 }
 
+// filterRestrictedBuildApps removes the apps the actor of the context can't
+// access because the template of the build restricts them.
+func (api *API) filterRestrictedBuildApps(
+	ctx context.Context,
+	workspaceBuilds []database.WorkspaceBuild,
+	templateVersions []database.TemplateVersion,
+	resources []database.WorkspaceResource,
+	agents []database.WorkspaceAgent,
+	apps []database.WorkspaceApp,
+) ([]database.WorkspaceApp, error) {
+	templateIDs := make([]uuid.UUID, 0, len(templateVersions))
+	templateVersionByID := map[uuid.UUID]database.TemplateVersion{}
+	for _, templateVersion := range templateVersions {
+		templateIDs = append(templateIDs, templateVersion.TemplateID.UUID)
+		templateVersionByID[templateVersion.ID] = templateVersion
+	}
+	restrictions, err := workspaceapps.GetAppRestrictions(ctx, api.Database, templateIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(restrictions) == 0 {
+		return apps, nil
+	}
+
+	templateVersionByJobID := map[uuid.UUID]database.TemplateVersion{}
+	for _, build := range workspaceBuilds {
+		templateVersionByJobID[build.JobID] = templateVersionByID[build.TemplateVersionID]
+	}
+	jobIDByResourceID := map[uuid.UUID]uuid.UUID{}
+	for _, resource := range resources {
+		jobIDByResourceID[resource.ID] = resource.JobID
+	}
+	appsByAgentID := map[uuid.UUID][]database.WorkspaceApp{}
+	for _, app := range apps {
+		appsByAgentID[app.AgentID] = append(appsByAgentID[app.AgentID], app)
+	}
+	filtered := make([]database.WorkspaceApp, 0, len(apps))
+	for _, agent := range agents {
+		templateVersion := templateVersionByJobID[jobIDByResourceID[agent.ResourceID]]
+		filtered = append(filtered, restrictions.FilterApps(ctx, templateVersion.TemplateID.UUID, templateVersion.OrganizationID, appsByAgentID[agent.ID])...)
+	}
+	return filtered, nil
+}
+
 func (api *API) convertWorkspaceBuilds(
 	workspaceBuilds []database.WorkspaceBuild,
 	workspaces []database.Workspace,
@@ -1172,6 +1413,11 @@ func (api *API) convertWorkspaceBuild(
 		Deadline:                codersdk.NewNullTime(build.Deadline, !build.Deadline.IsZero()),
 		MaxDeadline:             codersdk.NewNullTime(build.MaxDeadline, !build.MaxDeadline.IsZero()),
 		Reason:                  codersdk.BuildReason(build.Reason),
+		ReasonMessage:           build.ReasonMessage,
+		InitiatorContext: codersdk.WorkspaceBuildInitiatorContext{
+			APIKeyName: build.InitiatorAPIKeyName,
+			CIJobURL:   build.InitiatorCIJobURL,
+		},
 		Resources:               apiResources,
 		Status:                  codersdk.ConvertWorkspaceStatus(apiJob.Status, transition),
 		DailyCost:               build.DailyCost,
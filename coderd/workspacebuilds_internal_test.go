@@ -0,0 +1,39 @@
+package coderd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestEstimateQueueStart(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	median := ptr.Ref(int64(time.Minute / time.Millisecond))
+	for _, tc := range []struct {
+		name      string
+		position  int
+		available int
+		median    *int64
+		expected  *time.Time
+	}{
+		{name: "NotQueued", position: 0, available: 1, median: median},
+		{name: "NoProvisioners", position: 1, available: 0, median: median},
+		{name: "NoBuildTimes", position: 1, available: 1},
+		{name: "Next", position: 1, available: 1, median: median, expected: &now},
+		{name: "SecondRound", position: 2, available: 1, median: median, expected: ptr.Ref(now.Add(time.Minute))},
+		{name: "SharedRound", position: 3, available: 3, median: median, expected: &now},
+		{name: "ThirdRound", position: 7, available: 3, median: median, expected: ptr.Ref(now.Add(2 * time.Minute))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			matched := codersdk.MatchedProvisioners{Count: tc.available, Available: tc.available}
+			require.Equal(t, tc.expected, estimateQueueStart(now, tc.position, matched, tc.median))
+		})
+	}
+}
@@ -1802,6 +1802,49 @@ func TestPostWorkspaceBuild(t *testing.T) {
 			assert.True(t, build.MatchedProvisioners.MostRecentlySeen.Valid)
 		}
 	})
+	t.Run("ReasonMessage", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		token, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName: "nightly-ci",
+		})
+		require.NoError(t, err)
+		tokenClient := codersdk.New(client.URL)
+		tokenClient.SetSessionToken(token.Key)
+
+		build, err := tokenClient.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition:    codersdk.WorkspaceTransitionStop,
+			ReasonMessage: "Rotate credentials",
+			CIJobURL:      "https://ci.example.com/jobs/42",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Rotate credentials", build.ReasonMessage)
+		require.Equal(t, codersdk.WorkspaceBuildInitiatorContext{
+			APIKeyName: "nightly-ci",
+			CIJobURL:   "https://ci.example.com/jobs/42",
+		}, build.InitiatorContext)
+
+		builds, err := client.WorkspaceBuilds(ctx, codersdk.WorkspaceBuildsRequest{WorkspaceID: workspace.ID})
+		require.NoError(t, err)
+		require.Equal(t, "Rotate credentials", builds[0].ReasonMessage)
+		require.Equal(t, "nightly-ci", builds[0].InitiatorContext.APIKeyName)
+
+		// Builds started with a session have no token name.
+		build, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+		})
+		require.NoError(t, err)
+		require.Empty(t, build.InitiatorContext.APIKeyName)
+	})
 }
 
 func TestWorkspaceBuildTimings(t *testing.T) {
@@ -2105,3 +2148,92 @@ func TestWorkspaceBuildTimings(t *testing.T) {
 		require.Len(t, res.AgentConnectionTimings, 5)
 	})
 }
+
+func TestWorkspaceBuildQueue(t *testing.T) {
+	t.Parallel()
+
+	db, pubsub := dbtestutil.NewDB(t)
+	ownerClient := coderdtest.New(t, &coderdtest.Options{
+		Database: db,
+		Pubsub:   pubsub,
+	})
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	client, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+
+	file := dbgen.File(t, db, database.File{
+		CreatedBy: owner.UserID,
+	})
+	versionJob := dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
+		OrganizationID: owner.OrganizationID,
+		InitiatorID:    user.ID,
+		FileID:         file.ID,
+		StartedAt:      sql.NullTime{Time: dbtime.Now(), Valid: true},
+		CompletedAt:    sql.NullTime{Time: dbtime.Now(), Valid: true},
+	})
+	version := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+		OrganizationID: owner.OrganizationID,
+		JobID:          versionJob.ID,
+		CreatedBy:      owner.UserID,
+	})
+	template := dbgen.Template(t, db, database.Template{
+		OrganizationID:  owner.OrganizationID,
+		ActiveVersionID: version.ID,
+		CreatedBy:       owner.UserID,
+	})
+
+	// Only this test's provisioner matches the tags of its jobs.
+	tags := database.StringMap{uuid.NewString(): "true"}
+	_ = dbgen.ProvisionerDaemon(t, db, database.ProvisionerDaemon{
+		OrganizationID: owner.OrganizationID,
+		Tags:           tags,
+	})
+	makeBuild := func(job database.ProvisionerJob) database.WorkspaceBuild {
+		ws := dbgen.Workspace(t, db, database.WorkspaceTable{
+			OwnerID:        user.ID,
+			OrganizationID: owner.OrganizationID,
+			TemplateID:     template.ID,
+		})
+		job.OrganizationID = owner.OrganizationID
+		if job.Tags == nil {
+			job.Tags = tags
+		}
+		job = dbgen.ProvisionerJob(t, db, pubsub, job)
+		return dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       ws.ID,
+			TemplateVersionID: version.ID,
+			InitiatorID:       owner.UserID,
+			JobID:             job.ID,
+			BuildNumber:       1,
+		})
+	}
+	first := makeBuild(database.ProvisionerJob{CreatedAt: dbtime.Now().Add(-time.Minute)})
+	second := makeBuild(database.ProvisionerJob{CreatedAt: dbtime.Now()})
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	queue, err := client.WorkspaceBuildQueue(ctx, second.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.ProvisionerJobPending, queue.Status)
+	require.Equal(t, 2, queue.Position)
+	require.Equal(t, 2, queue.Size)
+	require.Equal(t, map[string]string(tags), queue.Tags)
+	require.Equal(t, 1, queue.MatchedProvisioners.Count)
+	require.Equal(t, 1, queue.MatchedProvisioners.Available)
+	// The template has no completed builds to estimate from.
+	require.Nil(t, queue.EstimatedStartAt)
+
+	queue, err = client.WorkspaceBuildQueue(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, queue.Position)
+
+	// Running jobs have their own tags, so that acquiring them doesn't acquire
+	// the pending jobs above.
+	running := makeBuild(database.ProvisionerJob{
+		Tags:      database.StringMap{uuid.NewString(): "true"},
+		StartedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+	})
+	queue, err = client.WorkspaceBuildQueue(ctx, running.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.ProvisionerJobRunning, queue.Status)
+	require.Zero(t, queue.Position)
+	require.Zero(t, queue.MatchedProvisioners.Count)
+}
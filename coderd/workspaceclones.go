@@ -0,0 +1,252 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Clone workspace
+// @Description Creates a workspace for the caller with the template version
+// @Description and parameter values of the latest build of the workspace.
+// @Description Data is not copied, see the copy data endpoint.
+// @ID clone-workspace
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.CloneWorkspaceRequest true "Clone workspace request"
+// @Success 201 {object} codersdk.Workspace
+// @Router /workspaces/{workspace}/clone [post]
+func (api *API) postWorkspaceClone(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		source    = httpmw.WorkspaceParam(r)
+		apiKey    = httpmw.APIKey(r)
+		auditor   = api.Auditor.Load()
+		createReq codersdk.CloneWorkspaceRequest
+	)
+	if !httpapi.Read(ctx, rw, r, &createReq) {
+		return
+	}
+
+	build, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, source.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching latest workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	parameters, err := api.Database.GetWorkspaceBuildParameters(ctx, build.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build parameters.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	user, err := api.Database.GetUserByID(ctx, apiKey.UserID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	owner := workspaceOwner{
+		ID:        user.ID,
+		Username:  user.Username,
+		AvatarURL: user.AvatarURL,
+	}
+
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+		Audit:   *auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+		AdditionalFields: audit.AdditionalFields{
+			WorkspaceOwner: owner.Username,
+		},
+	})
+	defer commitAudit()
+
+	workspace, ok := createWorkspaceInternal(ctx, aReq, apiKey.UserID, api, owner, codersdk.CreateWorkspaceRequest{
+		TemplateVersionID:       build.TemplateVersionID,
+		Name:                    createReq.Name,
+		RichParameterValues:     db2sdk.WorkspaceBuildParameters(parameters),
+		TemplateVersionPresetID: build.TemplateVersionPresetID.UUID,
+	}, rw, r, nil)
+	if !ok {
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, workspace)
+}
+
+// @Summary Copy workspace data
+// @Description Copies the data paths declared by the template of the
+// @Description workspace from another workspace, overwriting existing files.
+// @Description Both workspaces must be running.
+// @ID copy-workspace-data
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.CopyWorkspaceDataRequest true "Copy workspace data request"
+// @Success 204
+// @Router /workspaces/{workspace}/copy-data [post]
+func (api *API) postWorkspaceCopyData(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	var req codersdk.CopyWorkspaceDataRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.SourceWorkspaceID == workspace.ID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "A workspace cannot copy data from itself.",
+		})
+		return
+	}
+
+	source, err := api.Database.GetWorkspaceByID(ctx, req.SourceWorkspaceID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Source workspace not found.",
+			Validations: []codersdk.ValidationError{{Field: "source_workspace_id", Detail: "Source workspace not found."}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching source workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Copying reads files from one workspace and writes them into the other,
+	// which is equivalent to connecting to both.
+	if !api.Authorize(r, policy.ActionSSH, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if !api.Authorize(r, policy.ActionSSH, source) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Source workspace not found.",
+			Validations: []codersdk.ValidationError{{Field: "source_workspace_id", Detail: "Source workspace not found."}},
+		})
+		return
+	}
+
+	template, err := api.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(template.DormantArchivePaths) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Template %q does not declare any data paths to copy.", template.Name),
+			Detail:  "Template administrators declare the paths with the dormant archive paths of the template.",
+		})
+		return
+	}
+
+	from, ok := api.copyDataAgent(rw, r, source, req.AgentName)
+	if !ok {
+		return
+	}
+	to, ok := api.copyDataAgent(rw, r, workspace, req.AgentName)
+	if !ok {
+		return
+	}
+
+	err = api.copyWorkspaceData(r, from, to, template.DormantArchivePaths)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error copying workspace data.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// copyDataAgent returns the connected agent of the workspace that data is
+// copied with, or writes an error.
+func (api *API) copyDataAgent(rw http.ResponseWriter, r *http.Request, workspace database.Workspace, name string) (database.WorkspaceAgent, bool) {
+	ctx := r.Context()
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil && !httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return database.WorkspaceAgent{}, false
+	}
+	agent, ok := workspaceAgentByName(agents, name)
+	if !ok {
+		detail := fmt.Sprintf("Workspace %q has no agents.", workspace.Name)
+		if name != "" {
+			detail = fmt.Sprintf("Workspace %q has no agent named %q.", workspace.Name, name)
+		}
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Workspace agent not found.",
+			Validations: []codersdk.ValidationError{
+				{Field: "agent_name", Detail: detail},
+			},
+		})
+		return database.WorkspaceAgent{}, false
+	}
+	if status := agent.Status(api.AgentInactiveDisconnectTimeout).Status; status != database.WorkspaceAgentStatusConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state of workspace %q is %q, it must be in the %q state.", workspace.Name, status, database.WorkspaceAgentStatusConnected),
+		})
+		return database.WorkspaceAgent{}, false
+	}
+	return agent, true
+}
+
+// copyWorkspaceData streams an archive of the paths from one agent into the
+// other, without storing it.
+func (api *API) copyWorkspaceData(r *http.Request, from, to database.WorkspaceAgent, paths []string) error {
+	ctx := r.Context()
+
+	fromConn, releaseFrom, err := api.agentProvider.AgentConn(ctx, from.ID)
+	if err != nil {
+		return xerrors.Errorf("dial source agent: %w", err)
+	}
+	defer releaseFrom()
+	toConn, releaseTo, err := api.agentProvider.AgentConn(ctx, to.ID)
+	if err != nil {
+		return xerrors.Errorf("dial agent: %w", err)
+	}
+	defer releaseTo()
+
+	archive, err := fromConn.Archive(ctx, paths)
+	if err != nil {
+		return xerrors.Errorf("archive source paths: %w", err)
+	}
+	defer archive.Close()
+
+	err = toConn.RestoreArchive(ctx, archive)
+	if err != nil {
+		return xerrors.Errorf("restore archive: %w", err)
+	}
+	return nil
+}
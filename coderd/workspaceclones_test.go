@@ -0,0 +1,63 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestCloneWorkspace(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					Parameters: []*proto.RichParameter{{
+						Name:    "region",
+						Type:    "string",
+						Mutable: true,
+					}},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, member, template.ID, func(req *codersdk.CreateWorkspaceRequest) {
+		req.RichParameterValues = []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}}
+	})
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	clone, err := member.CloneWorkspace(ctx, workspace.ID, codersdk.CloneWorkspaceRequest{
+		Name: "clone",
+	})
+	require.NoError(t, err)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, clone.LatestBuild.ID)
+
+	parameters, err := member.WorkspaceBuildParameters(ctx, clone.LatestBuild.ID)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}}, parameters)
+
+	// The template declares no data paths, so there is nothing to copy.
+	err = member.CopyWorkspaceData(ctx, clone.ID, codersdk.CopyWorkspaceDataRequest{
+		SourceWorkspaceID: workspace.ID,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
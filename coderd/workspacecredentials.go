@@ -0,0 +1,111 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/apikey"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/moby/moby/pkg/namesgenerator"
+)
+
+// @Summary Create workspace credential
+// @Description Exchanges the session token for a short-lived credential that
+// @Description can only be used to connect to the workspace.
+// @ID create-workspace-credential
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.CreateWorkspaceCredentialRequest true "Create workspace credential request"
+// @Success 201 {object} codersdk.WorkspaceCredential
+// @Router /workspaces/{workspace}/credentials [post]
+func (api *API) postWorkspaceCredential(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionCreate,
+			OrganizationID: workspace.OrganizationID,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	// Credentials can't be used to mint new credentials, otherwise they could
+	// be renewed forever.
+	if apiKey.Scope == database.APIKeyScopeWorkspaceConnect {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Workspace credentials can't be used to create other credentials.",
+		})
+		return
+	}
+	// The credential can only be used to connect to the workspace, so there's
+	// no point in handing one out to users who can't.
+	if !api.Authorize(r, policy.ActionSSH, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.CreateWorkspaceCredentialRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	lifetime := req.Lifetime
+	if lifetime == 0 {
+		lifetime = codersdk.DefaultWorkspaceCredentialLifetime
+	}
+	if lifetime > codersdk.MaxWorkspaceCredentialLifetime {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to validate create workspace credential request.",
+			Detail:  fmt.Sprintf("lifetime must be at most %v", codersdk.MaxWorkspaceCredentialLifetime),
+		})
+		return
+	}
+	if err := api.validateAPIKeyLifetime(ctx, apiKey.UserID, lifetime); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to validate create workspace credential request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	cookie, key, err := api.createAPIKey(ctx, apikey.CreateParams{
+		UserID:            apiKey.UserID,
+		LoginType:         database.LoginTypeToken,
+		ExpiresAt:         dbtime.Now().Add(lifetime),
+		LifetimeSeconds:   int64(lifetime.Seconds()),
+		Scope:             database.APIKeyScopeWorkspaceConnect,
+		TokenName:         fmt.Sprintf("%s-%s", workspace.Name, namesgenerator.GetRandomName(1)),
+		UserAgent:         r.UserAgent(),
+		DeviceFingerprint: apikey.DeviceFingerprint(r.Header),
+		WorkspaceID:       uuid.NullUUID{UUID: workspace.ID, Valid: true},
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to create workspace credential.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = *key
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.WorkspaceCredential{
+		Key:         cookie.Value,
+		WorkspaceID: workspace.ID,
+		ExpiresAt:   key.ExpiresAt,
+	})
+}
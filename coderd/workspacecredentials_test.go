@@ -0,0 +1,88 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceCredentials(t *testing.T) {
+	t.Parallel()
+
+	ownerClient, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	client, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        user.ID,
+	}).WithAgent().Do()
+	other := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        user.ID,
+	}).WithAgent().Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	credential, err := client.CreateWorkspaceCredential(ctx, r.Workspace.ID, codersdk.CreateWorkspaceCredentialRequest{})
+	require.NoError(t, err)
+	require.Equal(t, r.Workspace.ID, credential.WorkspaceID)
+	require.WithinDuration(t, time.Now().Add(codersdk.DefaultWorkspaceCredentialLifetime), credential.ExpiresAt, time.Minute)
+
+	scoped := codersdk.New(client.URL)
+	scoped.SetSessionToken(credential.Key)
+
+	// The credential can be used to look up and connect to the workspace.
+	workspace, err := scoped.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, r.Workspace.ID, workspace.ID)
+	_, err = scoped.WorkspaceByOwnerAndName(ctx, codersdk.Me, r.Workspace.Name, codersdk.WorkspaceOptions{})
+	require.NoError(t, err)
+
+	// But not for anything else.
+	_, err = scoped.Workspace(ctx, other.Workspace.ID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	_, err = scoped.CreateWorkspaceBuild(ctx, r.Workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStop,
+	})
+	require.Error(t, err)
+	_, err = scoped.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+	require.Error(t, err)
+
+	// Credentials can't be renewed with themselves.
+	_, err = scoped.CreateWorkspaceCredential(ctx, r.Workspace.ID, codersdk.CreateWorkspaceCredentialRequest{})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// Nor created through the tokens endpoint.
+	_, err = client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+		Scope: codersdk.APIKeyScopeWorkspaceConnect,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	_, err = client.CreateWorkspaceCredential(ctx, r.Workspace.ID, codersdk.CreateWorkspaceCredentialRequest{
+		Lifetime: codersdk.MaxWorkspaceCredentialLifetime + time.Hour,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// Credentials stop working once the workspace is deleted.
+	err = db.UpdateWorkspaceDeletedByID(ctx, database.UpdateWorkspaceDeletedByIDParams{
+		ID:      r.Workspace.ID,
+		Deleted: true,
+	})
+	require.NoError(t, err)
+	_, err = scoped.Workspace(ctx, r.Workspace.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+}
@@ -0,0 +1,292 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/userpassword"
+	"github.com/coder/coder/v2/coderd/workspaceapps"
+	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// maxPortShareLinkDuration is the longest a port share link can be valid for.
+const maxPortShareLinkDuration = 7 * 24 * time.Hour
+
+// @Summary Create workspace port share link
+// @ID create-workspace-port-share-link
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags PortSharing
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.CreateWorkspacePortShareLinkRequest true "Create port share link request"
+// @Success 201 {object} codersdk.WorkspacePortShareLink
+// @Router /workspaces/{workspace}/port-share-links [post]
+func (api *API) postWorkspacePortShareLink(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		apiKey            = httpmw.APIKey(r)
+		portSharer        = *api.PortSharer.Load()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspacePortShareLink](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionCreate,
+			OrganizationID: workspace.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	if !api.DeploymentValues.PortShareLinks.Value() {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Port share links are disabled.",
+			Detail:  "Port share links can be enabled with --port-share-links.",
+		})
+		return
+	}
+	if api.AppHostname == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Port share links require a wildcard access URL.",
+		})
+		return
+	}
+
+	var req codersdk.CreateWorkspacePortShareLinkRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Protocol == "" {
+		req.Protocol = codersdk.WorkspaceAgentPortShareProtocolHTTP
+	}
+
+	expiresIn := time.Duration(req.ExpiresInMillis) * time.Millisecond
+	var validErrs []codersdk.ValidationError
+	if req.Port < 9 || req.Port > 65535 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "port", Detail: "Port must be between 9 and 65535."})
+	}
+	if !req.Protocol.ValidPortProtocol() {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "protocol", Detail: "Port protocol not allowed."})
+	}
+	if expiresIn <= 0 || expiresIn > maxPortShareLinkDuration {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "expires_in_ms", Detail: fmt.Sprintf("Must be positive and at most %s.", maxPortShareLinkDuration)})
+	}
+	if req.BandwidthLimitBytes < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "bandwidth_limit_bytes", Detail: "Must not be negative."})
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to create port share link.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	template, err := api.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	// Anyone with the link can access the port, so links are only allowed
+	// where ports can be shared publicly.
+	err = portSharer.AuthorizedLevel(template, codersdk.WorkspaceAgentPortShareLevelPublic)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	found := false
+	for _, agent := range agents {
+		if agent.Name == req.AgentName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Agent not found.",
+		})
+		return
+	}
+
+	var hashedPassword string
+	if req.Password != "" {
+		hashedPassword, err = userpassword.Hash(req.Password)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+	}
+	secret, hashedSecret, err := workspaceapps.NewShareLinkSecret()
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	now := dbtime.Now()
+	link, err := api.Database.InsertWorkspacePortShareLink(ctx, database.InsertWorkspacePortShareLinkParams{
+		ID:                  uuid.New(),
+		WorkspaceID:         workspace.ID,
+		AgentName:           req.AgentName,
+		Port:                req.Port,
+		Protocol:            database.PortShareProtocol(req.Protocol),
+		HashedSecret:        hashedSecret,
+		HashedPassword:      hashedPassword,
+		BandwidthLimitBytes: req.BandwidthLimitBytes,
+		ExpiresAt:           now.Add(expiresIn),
+		CreatedBy:           apiKey.UserID,
+		CreatedAt:           now,
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating port share link.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = link
+
+	sdkLink := convertWorkspacePortShareLink(link)
+	sdkLink.URL = api.portShareLinkURL(workspace, link, secret)
+	httpapi.Write(ctx, rw, http.StatusCreated, sdkLink)
+}
+
+// @Summary Get workspace port share links
+// @ID get-workspace-port-share-links
+// @Security CoderSessionToken
+// @Produce json
+// @Tags PortSharing
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspacePortShareLink
+// @Router /workspaces/{workspace}/port-share-links [get]
+func (api *API) workspacePortShareLinks(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	links, err := api.Database.GetWorkspacePortShareLinksByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(links, convertWorkspacePortShareLink))
+}
+
+// @Summary Delete workspace port share link
+// @ID delete-workspace-port-share-link
+// @Security CoderSessionToken
+// @Tags PortSharing
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param link path string true "Port share link ID" format(uuid)
+// @Success 204
+// @Router /workspaces/{workspace}/port-share-links/{link} [delete]
+func (api *API) deleteWorkspacePortShareLink(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspacePortShareLink](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionDelete,
+			OrganizationID: workspace.OrganizationID,
+		})
+	)
+	defer commitAudit()
+
+	linkID, ok := httpmw.ParseUUIDParam(rw, r, "link")
+	if !ok {
+		return
+	}
+
+	link, err := api.Database.GetWorkspacePortShareLinkByID(ctx, linkID)
+	if httpapi.Is404Error(err) || (err == nil && link.WorkspaceID != workspace.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.Old = link
+
+	err = api.Database.DeleteWorkspacePortShareLink(ctx, link.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting port share link.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// portShareLinkURL returns the public URL of a port share link, which is the
+// subdomain URL of the port with the token of the link.
+func (api *API) portShareLinkURL(workspace database.Workspace, link database.WorkspacePortShareLink, secret string) string {
+	slugOrPort := strconv.Itoa(int(link.Port))
+	if link.Protocol == database.PortShareProtocolHttps {
+		slugOrPort += "s"
+	}
+	appHost := appurl.ApplicationURL{
+		AppSlugOrPort: slugOrPort,
+		AgentName:     link.AgentName,
+		WorkspaceName: workspace.Name,
+		Username:      workspace.OwnerUsername,
+	}
+	u := url.URL{
+		Scheme:   api.AccessURL.Scheme,
+		Host:     strings.Replace(appurl.SubdomainAppHost(api.AppHostname, api.AccessURL), "*", appHost.String(), 1),
+		Path:     "/",
+		RawQuery: url.Values{codersdk.PortShareLinkQueryParameter: {workspaceapps.ShareLinkToken(link.ID, secret)}}.Encode(),
+	}
+	return u.String()
+}
+
+func convertWorkspacePortShareLink(link database.WorkspacePortShareLink) codersdk.WorkspacePortShareLink {
+	return codersdk.WorkspacePortShareLink{
+		ID:                  link.ID,
+		WorkspaceID:         link.WorkspaceID,
+		AgentName:           link.AgentName,
+		Port:                link.Port,
+		Protocol:            codersdk.WorkspaceAgentPortShareProtocol(link.Protocol),
+		HasPassword:         link.HashedPassword != "",
+		BandwidthLimitBytes: link.BandwidthLimitBytes,
+		BytesTransferred:    link.BytesTransferred,
+		ExpiresAt:           link.ExpiresAt,
+		CreatedBy:           link.CreatedBy,
+		CreatedAt:           link.CreatedAt,
+	}
+}
@@ -0,0 +1,153 @@
+package coderd_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspacePortShareLinks(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.PortShareLinks = true
+	ownerClient, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+		DeploymentValues: dv,
+		AppHostname:      "*.test.coder.com",
+	})
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	client, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        user.ID,
+	}).WithAgent().Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(dbauthz.As(ctx, coderdtest.AuthzUserSubject(user, owner.OrganizationID)), r.Workspace.ID)
+	require.NoError(t, err)
+	agentName := agents[0].Name
+
+	link, err := client.CreateWorkspacePortShareLink(ctx, r.Workspace.ID, codersdk.CreateWorkspacePortShareLinkRequest{
+		AgentName:       agentName,
+		Port:            8080,
+		ExpiresInMillis: time.Hour.Milliseconds(),
+		Password:        "hunter2",
+	})
+	require.NoError(t, err)
+	require.True(t, link.HasPassword)
+	require.Equal(t, codersdk.WorkspaceAgentPortShareProtocolHTTP, link.Protocol)
+	require.NotEmpty(t, link.URL)
+	linkURL, err := url.Parse(link.URL)
+	require.NoError(t, err)
+	require.Contains(t, linkURL.Host, "8080--"+agentName+"--"+r.Workspace.Name+"--"+user.Username)
+
+	// The secret of the link isn't returned again.
+	links, err := client.WorkspacePortShareLinks(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	require.Equal(t, link.ID, links[0].ID)
+	require.Empty(t, links[0].URL)
+
+	// Links can't outlive the maximum duration.
+	_, err = client.CreateWorkspacePortShareLink(ctx, r.Workspace.ID, codersdk.CreateWorkspacePortShareLinkRequest{
+		AgentName:       agentName,
+		Port:            8080,
+		ExpiresInMillis: (30 * 24 * time.Hour).Milliseconds(),
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// Visitors don't need to be signed in.
+	anonymous := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	get := func(t *testing.T, path string, cookie *http.Cookie, password string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ownerClient.URL.String()+path, nil)
+		require.NoError(t, err)
+		req.Host = linkURL.Host
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		if password != "" {
+			req.SetBasicAuth("", password)
+		}
+		res, err := anonymous.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = res.Body.Close() })
+		return res
+	}
+
+	// The token of the link is moved to a cookie.
+	res := get(t, linkURL.RequestURI(), nil, "")
+	require.Equal(t, http.StatusSeeOther, res.StatusCode)
+	require.Equal(t, "/", res.Header.Get("Location"))
+	var cookie *http.Cookie
+	for _, c := range res.Cookies() {
+		if c.Name == codersdk.PortShareLinkCookie {
+			cookie = c
+		}
+	}
+	require.NotNil(t, cookie)
+
+	// Visitors are asked for the password of the link.
+	res = get(t, "/", cookie, "")
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	require.Contains(t, res.Header.Get("WWW-Authenticate"), "Basic")
+	res = get(t, "/", cookie, "wrong")
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	// The password is correct, but the agent isn't connected, so the port is
+	// unavailable.
+	res = get(t, "/", cookie, "hunter2")
+	require.Equal(t, http.StatusBadGateway, res.StatusCode)
+
+	// Revoked links can't be used anymore.
+	err = client.DeleteWorkspacePortShareLink(ctx, r.Workspace.ID, link.ID)
+	require.NoError(t, err)
+	res = get(t, "/", cookie, "hunter2")
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+	links, err = client.WorkspacePortShareLinks(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Empty(t, links)
+}
+
+func TestWorkspacePortShareLinksDisabled(t *testing.T) {
+	t.Parallel()
+
+	ownerClient, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+		AppHostname: "*.test.coder.com",
+	})
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	client, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        user.ID,
+	}).WithAgent().Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	_, err := client.CreateWorkspacePortShareLink(ctx, r.Workspace.ID, codersdk.CreateWorkspacePortShareLinkRequest{
+		AgentName:       "dev",
+		Port:            8080,
+		ExpiresInMillis: time.Hour.Milliseconds(),
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+}
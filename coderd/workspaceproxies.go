@@ -73,6 +73,7 @@ func (api *API) PrimaryWorkspaceProxy(ctx context.Context) (database.WorkspacePr
 // @Security CoderSessionToken
 // @Produce json
 // @Tags WorkspaceProxies
+// @Param Coder-Region-Latencies header string false "Comma-separated region-id=milliseconds latency hints used to pick failover regions"
 // @Success 200 {object} codersdk.RegionsResponse[codersdk.Region]
 // @Router /regions [get]
 func (api *API) regions(rw http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,283 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	defaultWorkspaceReadinessTimeout = 30 * time.Second
+	maxWorkspaceReadinessTimeout     = 2 * time.Minute
+	// workspaceReadinessPollInterval is how often readiness is re-evaluated
+	// in addition to workspace events, since agents are only considered
+	// disconnected after a timeout and no event is published for it.
+	workspaceReadinessPollInterval = 5 * time.Second
+)
+
+// @Summary Wait for workspace readiness conditions
+// @Description Long-polls until all conditions are satisfied, any condition
+// @Description fails, or the timeout is reached. Conditions are written as
+// @Description "agent[:<agent>]", "app:[<agent>/]<slug>" or
+// @Description "script:[<agent>/]<display name>".
+// @ID wait-for-workspace-readiness-conditions
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param condition query []string true "Readiness conditions" collectionFormat(multi)
+// @Param timeout query string false "How long to wait, e.g. 30s. Defaults to 30s, at most 2m."
+// @Success 200 {object} codersdk.WorkspaceReadiness
+// @Router /workspaces/{workspace}/readiness [get]
+func (api *API) workspaceReadiness(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	values := r.URL.Query()
+	parser := httpapi.NewQueryParamParser().RequiredNotEmpty("condition")
+	conditions := httpapi.ParseCustomList(parser, values, nil, "condition", codersdk.ParseWorkspaceReadinessCondition)
+	timeout := httpapi.ParseCustom(parser, values, defaultWorkspaceReadinessTimeout, "timeout", time.ParseDuration)
+	parser.ErrorExcessParams(values)
+	if len(parser.Errors) == 0 && (timeout <= 0 || timeout > maxWorkspaceReadinessTimeout) {
+		parser.Errors = append(parser.Errors, codersdk.ValidationError{
+			Field:  "timeout",
+			Detail: fmt.Sprintf("Must be positive and at most %s.", maxWorkspaceReadinessTimeout),
+		})
+	}
+	if len(parser.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: parser.Errors,
+		})
+		return
+	}
+
+	// Re-evaluate the conditions whenever the workspace changes.
+	changed := make(chan struct{}, 1)
+	cancelSubscribe, err := api.Pubsub.SubscribeWithErr(wspubsub.WorkspaceEventChannel(workspace.OwnerID),
+		wspubsub.HandleWorkspaceEvent(
+			func(_ context.Context, payload wspubsub.WorkspaceEvent, err error) {
+				if err != nil || payload.WorkspaceID != workspace.ID {
+					return
+				}
+				select {
+				case changed <- struct{}{}:
+				default:
+				}
+			}))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error subscribing to workspace events.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer cancelSubscribe()
+
+	ticker := api.Clock.NewTicker(workspaceReadinessPollInterval)
+	defer ticker.Stop()
+	timer := api.Clock.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		readiness, err := api.evaluateWorkspaceReadiness(ctx, workspace.ID, conditions)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error evaluating workspace readiness.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if readiness.Ready || readiness.Failed {
+			httpapi.Write(ctx, rw, http.StatusOK, readiness)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			httpapi.Write(ctx, rw, http.StatusOK, readiness)
+			return
+		case <-ticker.C:
+		case <-changed:
+		}
+	}
+}
+
+// evaluateWorkspaceReadiness returns the state of the readiness conditions in
+// the latest build of a workspace.
+func (api *API) evaluateWorkspaceReadiness(ctx context.Context, workspaceID uuid.UUID, conditions []codersdk.WorkspaceReadinessCondition) (codersdk.WorkspaceReadiness, error) {
+	// Until the build has succeeded, all conditions share its state.
+	build, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return codersdk.WorkspaceReadiness{}, xerrors.Errorf("get latest build: %w", err)
+	}
+	job, err := api.Database.GetProvisionerJobByID(ctx, build.JobID)
+	if err != nil {
+		return codersdk.WorkspaceReadiness{}, xerrors.Errorf("get build job: %w", err)
+	}
+	buildState := func(status codersdk.WorkspaceReadinessConditionStatus, msg string) codersdk.WorkspaceReadiness {
+		readiness := codersdk.WorkspaceReadiness{
+			Failed: status == codersdk.WorkspaceReadinessConditionStatusFailed,
+		}
+		for _, cond := range conditions {
+			readiness.Conditions = append(readiness.Conditions, codersdk.WorkspaceReadinessConditionState{
+				Condition: cond.String(),
+				Status:    status,
+				Message:   msg,
+			})
+		}
+		return readiness
+	}
+	switch {
+	case build.Transition != database.WorkspaceTransitionStart:
+		return buildState(codersdk.WorkspaceReadinessConditionStatusFailed, fmt.Sprintf("The workspace is not started, the latest build is a %s build.", build.Transition)), nil
+	case job.JobStatus == database.ProvisionerJobStatusPending || job.JobStatus == database.ProvisionerJobStatusRunning:
+		return buildState(codersdk.WorkspaceReadinessConditionStatusPending, "The workspace is building."), nil
+	case job.JobStatus != database.ProvisionerJobStatusSucceeded:
+		return buildState(codersdk.WorkspaceReadinessConditionStatusFailed, fmt.Sprintf("The workspace build is %s.", job.JobStatus)), nil
+	}
+
+	agents, err := api.Database.GetWorkspaceAgentsByWorkspaceAndBuildNumber(ctx, database.GetWorkspaceAgentsByWorkspaceAndBuildNumberParams{
+		WorkspaceID: workspaceID,
+		BuildNumber: build.BuildNumber,
+	})
+	if err != nil {
+		return codersdk.WorkspaceReadiness{}, xerrors.Errorf("get agents: %w", err)
+	}
+	agentIDs := make([]uuid.UUID, 0, len(agents))
+	for _, agent := range agents {
+		agentIDs = append(agentIDs, agent.ID)
+	}
+	apps, err := api.Database.GetWorkspaceAppsByAgentIDs(ctx, agentIDs)
+	if err != nil {
+		return codersdk.WorkspaceReadiness{}, xerrors.Errorf("get apps: %w", err)
+	}
+	scripts, err := api.Database.GetWorkspaceAgentScriptsByAgentIDs(ctx, agentIDs)
+	if err != nil {
+		return codersdk.WorkspaceReadiness{}, xerrors.Errorf("get scripts: %w", err)
+	}
+	timings, err := api.Database.GetWorkspaceAgentScriptTimingsByBuildID(ctx, build.ID)
+	if err != nil {
+		return codersdk.WorkspaceReadiness{}, xerrors.Errorf("get script timings: %w", err)
+	}
+	startTimings := make(map[uuid.UUID]database.GetWorkspaceAgentScriptTimingsByBuildIDRow)
+	for _, timing := range timings {
+		if timing.Stage == database.WorkspaceAgentScriptTimingStageStart {
+			startTimings[timing.ScriptID] = timing
+		}
+	}
+
+	readiness := codersdk.WorkspaceReadiness{Ready: true}
+	for _, cond := range conditions {
+		state := codersdk.WorkspaceReadinessConditionState{
+			Condition: cond.String(),
+			Status:    codersdk.WorkspaceReadinessConditionStatusSatisfied,
+		}
+		// merge keeps the least ready state of all the matches of the
+		// condition.
+		merge := func(status codersdk.WorkspaceReadinessConditionStatus, msg string) {
+			switch {
+			case status == codersdk.WorkspaceReadinessConditionStatusSatisfied:
+			case state.Status == codersdk.WorkspaceReadinessConditionStatusFailed:
+			case status == codersdk.WorkspaceReadinessConditionStatusFailed,
+				state.Status == codersdk.WorkspaceReadinessConditionStatusSatisfied:
+				state.Status, state.Message = status, msg
+			}
+		}
+
+		matched := false
+		for _, agent := range agents {
+			if cond.Agent != "" && cond.Agent != agent.Name {
+				continue
+			}
+			switch cond.Type {
+			case codersdk.WorkspaceReadinessConditionAgent:
+				matched = true
+				merge(api.agentReadiness(agent))
+			case codersdk.WorkspaceReadinessConditionApp:
+				for _, app := range apps {
+					if app.AgentID != agent.ID || app.Slug != cond.Name {
+						continue
+					}
+					matched = true
+					switch app.Health {
+					case database.WorkspaceAppHealthHealthy:
+					case database.WorkspaceAppHealthDisabled:
+						merge(api.agentReadiness(agent))
+					case database.WorkspaceAppHealthUnhealthy:
+						merge(codersdk.WorkspaceReadinessConditionStatusPending, fmt.Sprintf("The app %q is unhealthy.", app.Slug))
+					default:
+						merge(codersdk.WorkspaceReadinessConditionStatusPending, fmt.Sprintf("The app %q is initializing.", app.Slug))
+					}
+				}
+			case codersdk.WorkspaceReadinessConditionScript:
+				for _, script := range scripts {
+					if script.WorkspaceAgentID != agent.ID || script.DisplayName != cond.Name {
+						continue
+					}
+					matched = true
+					timing, ok := startTimings[script.ID]
+					switch {
+					case !script.RunOnStart:
+						merge(codersdk.WorkspaceReadinessConditionStatusFailed, fmt.Sprintf("The script %q doesn't run on start.", script.DisplayName))
+					case !ok:
+						merge(codersdk.WorkspaceReadinessConditionStatusPending, fmt.Sprintf("The script %q hasn't finished.", script.DisplayName))
+					case timing.Status != database.WorkspaceAgentScriptTimingStatusOk:
+						merge(codersdk.WorkspaceReadinessConditionStatusFailed, fmt.Sprintf("The script %q failed with status %s and exit code %d.", script.DisplayName, timing.Status, timing.ExitCode))
+					}
+				}
+			}
+		}
+		if !matched {
+			state.Status = codersdk.WorkspaceReadinessConditionStatusFailed
+			state.Message = fmt.Sprintf("No %s matches the condition in the workspace.", cond.Type)
+		}
+
+		switch state.Status {
+		case codersdk.WorkspaceReadinessConditionStatusFailed:
+			readiness.Failed = true
+			readiness.Ready = false
+		case codersdk.WorkspaceReadinessConditionStatusPending:
+			readiness.Ready = false
+		}
+		readiness.Conditions = append(readiness.Conditions, state)
+	}
+	return readiness, nil
+}
+
+// agentReadiness returns whether the agent is connected and has finished
+// starting.
+func (api *API) agentReadiness(agent database.WorkspaceAgent) (codersdk.WorkspaceReadinessConditionStatus, string) {
+	status := agent.Status(api.AgentInactiveDisconnectTimeout)
+	switch status.Status {
+	case database.WorkspaceAgentStatusConnected:
+	case database.WorkspaceAgentStatusTimeout:
+		return codersdk.WorkspaceReadinessConditionStatusFailed, fmt.Sprintf("The agent %q timed out connecting.", agent.Name)
+	default:
+		return codersdk.WorkspaceReadinessConditionStatusPending, fmt.Sprintf("The agent %q is %s.", agent.Name, status.Status)
+	}
+
+	switch agent.LifecycleState {
+	case database.WorkspaceAgentLifecycleStateReady:
+		return codersdk.WorkspaceReadinessConditionStatusSatisfied, ""
+	case database.WorkspaceAgentLifecycleStateCreated, database.WorkspaceAgentLifecycleStateStarting:
+		return codersdk.WorkspaceReadinessConditionStatusPending, fmt.Sprintf("The agent %q is starting.", agent.Name)
+	default:
+		return codersdk.WorkspaceReadinessConditionStatusFailed, fmt.Sprintf("The agent %q is %s.", agent.Name, agent.LifecycleState)
+	}
+}
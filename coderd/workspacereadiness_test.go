@@ -0,0 +1,56 @@
+package coderd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceReadiness(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// The agent hasn't connected yet, so the condition is still pending
+	// when the timeout is reached.
+	readiness, err := client.WorkspaceReadiness(ctx, r.Workspace.ID, codersdk.WorkspaceReadinessRequest{
+		Conditions: []codersdk.WorkspaceReadinessCondition{{Type: codersdk.WorkspaceReadinessConditionAgent}},
+		Timeout:    testutil.IntervalFast,
+	})
+	require.NoError(t, err)
+	require.False(t, readiness.Ready)
+	require.False(t, readiness.Failed)
+	require.Len(t, readiness.Conditions, 1)
+	require.Equal(t, codersdk.WorkspaceReadinessConditionStatusPending, readiness.Conditions[0].Status)
+
+	_ = agenttest.New(t, client.URL, r.AgentToken)
+	readiness, err = client.WaitForWorkspaceReadiness(ctx, r.Workspace.ID, []codersdk.WorkspaceReadinessCondition{
+		{Type: codersdk.WorkspaceReadinessConditionAgent},
+	})
+	require.NoError(t, err)
+	require.True(t, readiness.Ready)
+
+	// Conditions that don't match anything in the workspace fail.
+	readiness, err = client.WaitForWorkspaceReadiness(ctx, r.Workspace.ID, []codersdk.WorkspaceReadinessCondition{
+		{Type: codersdk.WorkspaceReadinessConditionApp, Name: "does-not-exist"},
+	})
+	require.NoError(t, err)
+	require.True(t, readiness.Failed)
+
+	_, err = client.WorkspaceReadiness(ctx, r.Workspace.ID, codersdk.WorkspaceReadinessRequest{})
+	require.Error(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"slices"
 	"strconv"
@@ -116,8 +117,10 @@ func (api *API) workspace(rw http.ResponseWriter, r *http.Request) {
 		workspace,
 		data.builds[0],
 		data.templates[0],
+		data.activeVersionID(workspace, data.templates[0]),
 		api.Options.AllowWorkspaceRenames,
 		appStatus,
+		data.labels[workspace.ID],
 	)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -137,7 +140,7 @@ func (api *API) workspace(rw http.ResponseWriter, r *http.Request) {
 // @Security CoderSessionToken
 // @Produce json
 // @Tags Workspaces
-// @Param q query string false "Search query in the format `key:value`. Available keys are: owner, template, name, status, has-agent, dormant, last_used_after, last_used_before, has-ai-task."
+// @Param q query string false "Search query in the format `key:value`. Available keys are: owner, template, name, status, has-agent, dormant, last_used_after, last_used_before, has-ai-task, label."
 // @Param limit query int false "Page limit"
 // @Param offset query int false "Page offset"
 // @Success 200 {object} codersdk.WorkspacesResponse
@@ -321,8 +324,10 @@ func (api *API) workspaceByOwnerAndName(rw http.ResponseWriter, r *http.Request)
 		workspace,
 		data.builds[0],
 		data.templates[0],
+		data.activeVersionID(workspace, data.templates[0]),
 		api.Options.AllowWorkspaceRenames,
 		appStatus,
+		data.labels[workspace.ID],
 	)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -493,10 +498,31 @@ func createWorkspace(
 	rw http.ResponseWriter,
 	r *http.Request,
 ) {
-	template, ok := requestTemplate(ctx, rw, req, api.Database)
+	w, ok := createWorkspaceInternal(ctx, auditReq, initiatorID, api, owner, req, rw, r, nil)
 	if !ok {
 		return
 	}
+	httpapi.Write(ctx, rw, http.StatusCreated, w)
+}
+
+// createWorkspaceInternal creates a workspace and writes any error to rw. If
+// afterInsert is set, it is called in the transaction that inserts the
+// workspace. claimed is whether a prebuilt workspace was claimed instead.
+func createWorkspaceInternal(
+	ctx context.Context,
+	auditReq *audit.Request[database.WorkspaceTable],
+	initiatorID uuid.UUID,
+	api *API,
+	owner workspaceOwner,
+	req codersdk.CreateWorkspaceRequest,
+	rw http.ResponseWriter,
+	r *http.Request,
+	afterInsert func(db database.Store, workspace database.Workspace, claimed bool) error,
+) (codersdk.Workspace, bool) {
+	template, ok := requestTemplate(ctx, rw, req, api.Database)
+	if !ok {
+		return codersdk.Workspace{}, false
+	}
 
 	// This is a premature auth check to avoid doing unnecessary work if the user
 	// doesn't have permission to create a workspace.
@@ -511,18 +537,22 @@ func createWorkspace(
 				"Please contact an administrator about your permissions if you feel this is an error.",
 			Validations: nil,
 		})
-		return
+		return codersdk.Workspace{}, false
 	}
 
 	// Update audit log's organization
 	auditReq.UpdateOrganizationID(template.OrganizationID)
 
+	if !followsNamePolicy(ctx, rw, &api.DeploymentValues.Naming.WorkspaceName, "Workspace name", "name", req.Name) {
+		return codersdk.Workspace{}, false
+	}
+
 	// Do this upfront to save work. If this fails, the rest of the work
 	// would be wasted.
 	if !api.Authorize(r, policy.ActionCreate,
 		rbac.ResourceWorkspace.InOrg(template.OrganizationID).WithOwner(owner.ID.String())) {
 		httpapi.ResourceNotFound(rw)
-		return
+		return codersdk.Workspace{}, false
 	}
 	// The user also needs permission to use the template. At this point they have
 	// read perms, but not necessarily "use". This is also checked in `db.InsertWorkspace`.
@@ -534,7 +564,7 @@ func createWorkspace(
 				"Please contact an administrator about your permissions if you feel this is an error.",
 			Validations: nil,
 		})
-		return
+		return codersdk.Workspace{}, false
 	}
 
 	templateAccessControl := (*(api.AccessControlStore.Load())).GetTemplateAccessControl(template)
@@ -545,7 +575,7 @@ func createWorkspace(
 			Detail:      templateAccessControl.Deprecated,
 			Validations: nil,
 		})
-		return
+		return codersdk.Workspace{}, false
 	}
 
 	dbAutostartSchedule, err := validWorkspaceSchedule(req.AutostartSchedule)
@@ -554,7 +584,7 @@ func createWorkspace(
 			Message:     "Invalid Autostart Schedule.",
 			Validations: []codersdk.ValidationError{{Field: "schedule", Detail: err.Error()}},
 		})
-		return
+		return codersdk.Workspace{}, false
 	}
 
 	templateSchedule, err := (*api.TemplateScheduleStore.Load()).Get(ctx, api.Database, template.ID)
@@ -563,7 +593,7 @@ func createWorkspace(
 			Message: "Internal error fetching template schedule.",
 			Detail:  err.Error(),
 		})
-		return
+		return codersdk.Workspace{}, false
 	}
 
 	nextStartAt := sql.NullTime{}
@@ -580,7 +610,7 @@ func createWorkspace(
 			Message:     "Invalid Workspace Time to Shutdown.",
 			Validations: []codersdk.ValidationError{{Field: "ttl_ms", Detail: err.Error()}},
 		})
-		return
+		return codersdk.Workspace{}, false
 	}
 
 	// back-compatibility: default to "never" if not included.
@@ -592,10 +622,18 @@ func createWorkspace(
 				Message:     "Invalid Workspace Automatic Updates setting.",
 				Validations: []codersdk.ValidationError{{Field: "automatic_updates", Detail: err.Error()}},
 			})
-			return
+			return codersdk.Workspace{}, false
 		}
 	}
 
+	if validErrs := validWorkspaceLabels(req.Labels); len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid workspace labels.",
+			Validations: validErrs,
+		})
+		return codersdk.Workspace{}, false
+	}
+
 	// TODO: This should be a system call as the actor might not be able to
 	// read other workspaces. Ideally we check the error on create and look for
 	// a postgres conflict error.
@@ -612,13 +650,13 @@ func createWorkspace(
 				Detail: "This value is already in use and should be unique.",
 			}},
 		})
-		return
+		return codersdk.Workspace{}, false
 	} else if !errors.Is(err, sql.ErrNoRows) {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: fmt.Sprintf("Internal error fetching workspace by name %q.", req.Name),
 			Detail:  err.Error(),
 		})
-		return
+		return codersdk.Workspace{}, false
 	}
 
 	var (
@@ -682,6 +720,7 @@ func createWorkspace(
 				// have the newly created workspace at the top of the list!
 				LastUsedAt:       dbtime.Now(),
 				AutomaticUpdates: dbAU,
+				Ephemeral:        req.Ephemeral,
 			})
 			if err != nil {
 				return xerrors.Errorf("insert workspace: %w", err)
@@ -701,13 +740,26 @@ func createWorkspace(
 			return xerrors.Errorf("get workspace by ID: %w", err)
 		}
 
+		err = insertWorkspaceLabels(ctx, db, workspace.ID, req.Labels)
+		if err != nil {
+			return err
+		}
+
+		if afterInsert != nil {
+			err = afterInsert(db, workspace, claimedWorkspace != nil)
+			if err != nil {
+				return err
+			}
+		}
+
 		builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).
 			Reason(database.BuildReasonInitiator).
 			Initiator(initiatorID).
 			ActiveVersion().
-			Experiments(api.Experiments).
+			Experiments(api.ExperimentFlags.ExperimentsFor(ctx)).
 			DeploymentValues(api.DeploymentValues).
-			RichParameterValues(req.RichParameterValues)
+			RichParameterValues(req.RichParameterValues).
+			ParameterValidation(api.HTTPClient)
 		if req.TemplateVersionID != uuid.Nil {
 			builder = builder.VersionID(req.TemplateVersionID)
 		}
@@ -731,7 +783,7 @@ func createWorkspace(
 	}, nil)
 	if err != nil {
 		httperror.WriteWorkspaceBuildError(ctx, rw, err)
-		return
+		return codersdk.Workspace{}, false
 	}
 
 	err = provisionerjobs.PostJob(api.Pubsub, *provisionerJob)
@@ -784,7 +836,7 @@ func createWorkspace(
 			Message: "Internal error converting workspace build.",
 			Detail:  err.Error(),
 		})
-		return
+		return codersdk.Workspace{}, false
 	}
 
 	w, err := convertWorkspace(
@@ -792,17 +844,21 @@ func createWorkspace(
 		workspace,
 		apiBuild,
 		template,
+		// The workspace was built with the active version, or the rolled out
+		// version if it's in the cohort of a template version rollout.
+		workspaceBuild.TemplateVersionID,
 		api.Options.AllowWorkspaceRenames,
 		codersdk.WorkspaceAppStatus{},
+		req.Labels,
 	)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error converting workspace.",
 			Detail:  err.Error(),
 		})
-		return
+		return codersdk.Workspace{}, false
 	}
-	httpapi.Write(ctx, rw, http.StatusCreated, w)
+	return w, true
 }
 
 func requestTemplate(ctx context.Context, rw http.ResponseWriter, req codersdk.CreateWorkspaceRequest, db database.Store) (database.Template, bool) {
@@ -872,7 +928,7 @@ func requestTemplate(ctx context.Context, rw http.ResponseWriter, req codersdk.C
 }
 
 func claimPrebuild(ctx context.Context, claimer prebuilds.Claimer, db database.Store, logger slog.Logger, req codersdk.CreateWorkspaceRequest, owner workspaceOwner) (*database.Workspace, error) {
-	claimedID, err := claimer.Claim(ctx, owner.ID, req.Name, req.TemplateVersionPresetID)
+	claimedID, err := claimer.Claim(ctx, owner.ID, req.Name, req.TemplateVersionPresetID, req.Ephemeral)
 	if err != nil {
 		// TODO: enhance this by clarifying whether this *specific* prebuild failed or whether there are none to claim.
 		return nil, xerrors.Errorf("claim prebuild: %w", err)
@@ -987,12 +1043,18 @@ func (api *API) patchWorkspace(rw http.ResponseWriter, r *http.Request) {
 	// patched in the future, it's enough if one changes.
 	name := workspace.Name
 	if req.Name != "" || req.Name != workspace.Name {
-		if !api.Options.AllowWorkspaceRenames {
+		// Workspaces named before the naming policy was set can always be
+		// renamed to follow it.
+		namePolicy := &api.DeploymentValues.Naming.WorkspaceName
+		if !api.Options.AllowWorkspaceRenames && namePolicy.Valid(workspace.Name) == nil {
 			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 				Message: "Workspace renames are not allowed.",
 			})
 			return
 		}
+		if !followsNamePolicy(ctx, rw, namePolicy, "Workspace name", "name", req.Name) {
+			return
+		}
 		name = req.Name
 	}
 
@@ -1225,6 +1287,240 @@ func (api *API) putWorkspaceTTL(rw http.ResponseWriter, r *http.Request) {
 	rw.WriteHeader(http.StatusNoContent)
 }
 
+// @Summary Update workspace labels by ID
+// @ID update-workspace-labels-by-id
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceLabelsRequest true "Workspace labels update request"
+// @Success 204
+// @Router /workspaces/{workspace}/labels [put]
+func (api *API) putWorkspaceLabels(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: workspace.OrganizationID,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace.WorkspaceTable()
+
+	var req codersdk.UpdateWorkspaceLabelsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if validErrs := validWorkspaceLabels(req.Labels); len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid workspace labels.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		err := tx.DeleteWorkspaceLabelsByWorkspaceID(ctx, workspace.ID)
+		if err != nil {
+			return xerrors.Errorf("delete workspace labels: %w", err)
+		}
+		return insertWorkspaceLabels(ctx, tx, workspace.ID, req.Labels)
+	}, nil)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace labels.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	aReq.New = workspace.WorkspaceTable()
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: workspace.ID,
+	})
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get workspace schedule pause by ID
+// @ID get-workspace-schedule-pause-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceSchedulePause
+// @Router /workspaces/{workspace}/schedule-pause [get]
+func (api *API) workspaceSchedulePause(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	pause, err := api.Database.GetWorkspaceSchedulePauseByWorkspaceID(ctx, workspace.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace schedule pause.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceSchedulePause(pause, dbtime.Now()))
+}
+
+// @Summary Pause workspace schedule by ID
+// @Description Pauses the autostop, dormancy and auto-delete schedules of a
+// @Description workspace until the given time.
+// @ID pause-workspace-schedule-by-id
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.PutWorkspaceSchedulePauseRequest true "Workspace schedule pause request"
+// @Success 200 {object} codersdk.WorkspaceSchedulePause
+// @Router /workspaces/{workspace}/schedule-pause [put]
+func (api *API) putWorkspaceSchedulePause(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: workspace.OrganizationID,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace.WorkspaceTable()
+
+	var req codersdk.PutWorkspaceSchedulePauseRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	maxPause := api.DeploymentValues.MaxWorkspaceSchedulePause.Value()
+	if maxPause <= 0 {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Pausing workspace schedules is disabled on this deployment.",
+		})
+		return
+	}
+	now := dbtime.Now()
+	if !req.PausedUntil.After(now) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid workspace schedule pause.",
+			Validations: []codersdk.ValidationError{
+				{Field: "paused_until", Detail: "must be in the future"},
+			},
+		})
+		return
+	}
+	if req.PausedUntil.Sub(now) > maxPause {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid workspace schedule pause.",
+			Validations: []codersdk.ValidationError{
+				{Field: "paused_until", Detail: fmt.Sprintf("must be at most %s from now", maxPause)},
+			},
+		})
+		return
+	}
+
+	pause, err := api.Database.UpsertWorkspaceSchedulePause(ctx, database.UpsertWorkspaceSchedulePauseParams{
+		WorkspaceID: workspace.ID,
+		PausedUntil: dbtime.Time(req.PausedUntil),
+		CreatedAt:   now,
+		CreatedBy:   apiKey.UserID,
+	})
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error pausing workspace schedule.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	aReq.New = workspace.WorkspaceTable()
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: workspace.ID,
+	})
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceSchedulePause(pause, now))
+}
+
+// @Summary Resume workspace schedule by ID
+// @ID resume-workspace-schedule-by-id
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 204
+// @Router /workspaces/{workspace}/schedule-pause [delete]
+func (api *API) deleteWorkspaceSchedulePause(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: workspace.OrganizationID,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace.WorkspaceTable()
+
+	err := api.Database.DeleteWorkspaceSchedulePause(ctx, workspace.ID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error resuming workspace schedule.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	aReq.New = workspace.WorkspaceTable()
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: workspace.ID,
+	})
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// convertWorkspaceSchedulePause omits pauses that have already ended.
+func convertWorkspaceSchedulePause(pause database.WorkspaceSchedulePause, now time.Time) codersdk.WorkspaceSchedulePause {
+	if !pause.PausedUntil.After(now) {
+		return codersdk.WorkspaceSchedulePause{}
+	}
+	return codersdk.WorkspaceSchedulePause{
+		PausedUntil: ptr.Ref(pause.PausedUntil),
+	}
+}
+
 // @Summary Update workspace dormancy status by id.
 // @ID update-workspace-dormancy-status-by-id
 // @Security CoderSessionToken
@@ -1369,8 +1665,10 @@ func (api *API) putWorkspaceDormant(rw http.ResponseWriter, r *http.Request) {
 		workspace,
 		data.builds[0],
 		data.templates[0],
+		data.activeVersionID(workspace, data.templates[0]),
 		api.Options.AllowWorkspaceRenames,
 		appStatus,
+		data.labels[workspace.ID],
 	)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -1495,7 +1793,7 @@ func (api *API) postWorkspaceUsage(rw http.ResponseWriter, r *http.Request) {
 
 	api.statsReporter.TrackUsage(workspace.ID)
 
-	if !api.Experiments.Enabled(codersdk.ExperimentWorkspaceUsage) {
+	if !api.ExperimentFlags.EnabledFor(r.Context(), codersdk.ExperimentWorkspaceUsage) {
 		// Continue previous behavior if the experiment is not enabled.
 		rw.WriteHeader(http.StatusNoContent)
 		return
@@ -1784,12 +2082,28 @@ func (api *API) resolveAutostart(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if build.TemplateVersionID == template.ActiveVersionID {
+	activeVersionID := template.ActiveVersionID
+	// Workspaces in the cohort of a rollout are updated to the rolled out
+	// version instead.
+	// nolint:gocritic // Resolving the rollout cohort is a system function.
+	rolloutTargets, err := api.Database.GetTemplateVersionRolloutTargetsByWorkspaceIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspace.ID})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version rollout.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(rolloutTargets) > 0 {
+		activeVersionID = rolloutTargets[0].TemplateVersionID
+	}
+
+	if build.TemplateVersionID == activeVersionID {
 		httpapi.Write(ctx, rw, http.StatusOK, codersdk.ResolveAutostartResponse{})
 		return
 	}
 
-	version, err := api.Database.GetTemplateVersionByID(ctx, template.ActiveVersionID)
+	version, err := api.Database.GetTemplateVersionByID(ctx, activeVersionID)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error fetching template version.",
@@ -1932,8 +2246,10 @@ func (api *API) watchWorkspace(
 			workspace,
 			data.builds[0],
 			data.templates[0],
+			data.activeVersionID(workspace, data.templates[0]),
 			api.Options.AllowWorkspaceRenames,
 			appStatus,
+			data.labels[workspace.ID],
 		)
 		if err != nil {
 			_ = sendEvent(codersdk.ServerSentEvent{
@@ -2046,6 +2362,21 @@ type workspaceData struct {
 	builds       []codersdk.WorkspaceBuild
 	appStatuses  []codersdk.WorkspaceAppStatus
 	allowRenames bool
+	// rolloutVersionIDs maps the workspaces in the cohort of a template
+	// version rollout to the rolled out version.
+	rolloutVersionIDs map[uuid.UUID]uuid.UUID
+	// labels maps workspaces to their labels.
+	labels map[uuid.UUID]map[string]string
+}
+
+// activeVersionID returns the version the workspace is built with when it's
+// updated. This is the active version of the template, unless the workspace
+// is in the cohort of a template version rollout.
+func (d workspaceData) activeVersionID(workspace database.Workspace, template database.Template) uuid.UUID {
+	if id, ok := d.rolloutVersionIDs[workspace.ID]; ok {
+		return id
+	}
+	return template.ActiveVersionID
 }
 
 // workspacesData only returns the data the caller can access. If the caller
@@ -2061,10 +2392,12 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 	}
 
 	var (
-		templates   []database.Template
-		builds      []database.WorkspaceBuild
-		appStatuses []database.WorkspaceAppStatus
-		eg          errgroup.Group
+		templates      []database.Template
+		builds         []database.WorkspaceBuild
+		appStatuses    []database.WorkspaceAppStatus
+		rolloutTargets []database.GetTemplateVersionRolloutTargetsByWorkspaceIDsRow
+		labels         []database.WorkspaceLabel
+		eg             errgroup.Group
 	)
 	eg.Go(func() (err error) {
 		templates, err = api.Database.GetTemplatesWithFilter(ctx, database.GetTemplatesWithFilterParams{
@@ -2093,6 +2426,24 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 		}
 		return nil
 	})
+	eg.Go(func() (err error) {
+		// This query must be run as system restricted to be efficient.
+		// nolint:gocritic
+		rolloutTargets, err = api.Database.GetTemplateVersionRolloutTargetsByWorkspaceIDs(dbauthz.AsSystemRestricted(ctx), workspaceIDs)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("get template version rollout targets: %w", err)
+		}
+		return nil
+	})
+	eg.Go(func() (err error) {
+		// This query must be run as system restricted to be efficient.
+		// nolint:gocritic
+		labels, err = api.Database.GetWorkspaceLabelsByWorkspaceIDs(dbauthz.AsSystemRestricted(ctx), workspaceIDs)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("get workspace labels: %w", err)
+		}
+		return nil
+	})
 	err := eg.Wait()
 	if err != nil {
 		return workspaceData{}, err
@@ -2121,11 +2472,25 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 		return workspaceData{}, xerrors.Errorf("convert workspace builds: %w", err)
 	}
 
+	rolloutVersionIDs := make(map[uuid.UUID]uuid.UUID, len(rolloutTargets))
+	for _, target := range rolloutTargets {
+		rolloutVersionIDs[target.WorkspaceID] = target.TemplateVersionID
+	}
+	labelsByWorkspaceID := make(map[uuid.UUID]map[string]string, len(workspaces))
+	for _, label := range labels {
+		if labelsByWorkspaceID[label.WorkspaceID] == nil {
+			labelsByWorkspaceID[label.WorkspaceID] = map[string]string{}
+		}
+		labelsByWorkspaceID[label.WorkspaceID][label.Key] = label.Value
+	}
+
 	return workspaceData{
-		templates:    templates,
-		appStatuses:  db2sdk.WorkspaceAppStatuses(appStatuses),
-		builds:       apiBuilds,
-		allowRenames: api.Options.AllowWorkspaceRenames,
+		templates:         templates,
+		appStatuses:       db2sdk.WorkspaceAppStatuses(appStatuses),
+		builds:            apiBuilds,
+		allowRenames:      api.Options.AllowWorkspaceRenames,
+		rolloutVersionIDs: rolloutVersionIDs,
+		labels:            labelsByWorkspaceID,
 	}, nil
 }
 
@@ -2165,8 +2530,10 @@ func convertWorkspaces(requesterID uuid.UUID, workspaces []database.Workspace, d
 			workspace,
 			build,
 			template,
+			data.activeVersionID(workspace, template),
 			data.allowRenames,
 			appStatus,
+			data.labels[workspace.ID],
 		)
 		if err != nil {
 			return nil, xerrors.Errorf("convert workspace: %w", err)
@@ -2182,8 +2549,10 @@ func convertWorkspace(
 	workspace database.Workspace,
 	workspaceBuild codersdk.WorkspaceBuild,
 	template database.Template,
+	activeVersionID uuid.UUID,
 	allowRenames bool,
 	latestAppStatus codersdk.WorkspaceAppStatus,
+	labels map[string]string,
 ) (codersdk.Workspace, error) {
 	if requesterID == uuid.Nil {
 		return codersdk.Workspace{}, xerrors.Errorf("developer error: requesterID cannot be uuid.Nil!")
@@ -2231,6 +2600,9 @@ func convertWorkspace(
 	if latestAppStatus.ID == uuid.Nil {
 		appStatus = nil
 	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
 	return codersdk.Workspace{
 		ID:                                   workspace.ID,
 		CreatedAt:                            workspace.CreatedAt,
@@ -2247,10 +2619,10 @@ func convertWorkspace(
 		TemplateIcon:                         workspace.TemplateIcon,
 		TemplateDisplayName:                  workspace.TemplateDisplayName,
 		TemplateAllowUserCancelWorkspaceJobs: template.AllowUserCancelWorkspaceJobs,
-		TemplateActiveVersionID:              template.ActiveVersionID,
+		TemplateActiveVersionID:              activeVersionID,
 		TemplateRequireActiveVersion:         template.RequireActiveVersion,
 		TemplateUseClassicParameterFlow:      template.UseClassicParameterFlow,
-		Outdated:                             workspaceBuild.TemplateVersionID.String() != template.ActiveVersionID.String(),
+		Outdated:                             workspaceBuild.TemplateVersionID.String() != activeVersionID.String(),
 		Name:                                 workspace.Name,
 		AutostartSchedule:                    autostartSchedule,
 		TTLMillis:                            ttlMillis,
@@ -2265,6 +2637,8 @@ func convertWorkspace(
 		AllowRenames:     allowRenames,
 		Favorite:         requesterFavorite,
 		NextStartAt:      nextStartAt,
+		Labels:           labels,
+		Ephemeral:        workspace.Ephemeral,
 	}, nil
 }
 
@@ -2330,6 +2704,57 @@ func validWorkspaceDeadline(startedAt, newDeadline time.Time) error {
 	return nil
 }
 
+// maxWorkspaceLabels is the maximum number of labels a workspace can have.
+const maxWorkspaceLabels = 64
+
+func validWorkspaceLabels(labels map[string]string) []codersdk.ValidationError {
+	if len(labels) > maxWorkspaceLabels {
+		return []codersdk.ValidationError{{
+			Field:  "labels",
+			Detail: fmt.Sprintf("Workspaces can have at most %d labels.", maxWorkspaceLabels),
+		}}
+	}
+	var validErrs []codersdk.ValidationError
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		if err := codersdk.WorkspaceLabelKeyValid(key); err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "labels",
+				Detail: fmt.Sprintf("Label key %q %s.", key, err.Error()),
+			})
+			continue
+		}
+		if err := codersdk.WorkspaceLabelValueValid(labels[key]); err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "labels",
+				Detail: fmt.Sprintf("Value of label %q %s.", key, err.Error()),
+			})
+		}
+	}
+	return validErrs
+}
+
+// insertWorkspaceLabels adds labels to a workspace. Labels must have been
+// validated with validWorkspaceLabels.
+func insertWorkspaceLabels(ctx context.Context, db database.Store, workspaceID uuid.UUID, labels map[string]string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	keys := slices.Sorted(maps.Keys(labels))
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, labels[key])
+	}
+	err := db.InsertWorkspaceLabels(ctx, database.InsertWorkspaceLabelsParams{
+		WorkspaceID: workspaceID,
+		Keys:        keys,
+		Values:      values,
+	})
+	if err != nil {
+		return xerrors.Errorf("insert workspace labels: %w", err)
+	}
+	return nil
+}
+
 func validWorkspaceSchedule(s *string) (sql.NullString, error) {
 	if ptr.NilOrEmpty(s) {
 		return sql.NullString{}, nil
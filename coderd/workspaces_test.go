@@ -4088,6 +4088,188 @@ func TestWorkspaceFavoriteUnfavorite(t *testing.T) {
 	require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
 }
 
+func TestWorkspaceLabels(t *testing.T) {
+	t.Parallel()
+
+	var (
+		client, db           = coderdtest.NewWithDatabase(t, nil)
+		owner                = coderdtest.CreateFirstUser(t, client)
+		memberClient, member = coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		wsb1                 = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{OwnerID: member.ID, OrganizationID: owner.OrganizationID}).Do()
+		wsb2                 = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{OwnerID: member.ID, OrganizationID: owner.OrganizationID}).Do()
+	)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	ws, err := memberClient.Workspace(ctx, wsb1.Workspace.ID)
+	require.NoError(t, err)
+	require.Empty(t, ws.Labels)
+
+	err = memberClient.UpdateWorkspaceLabels(ctx, wsb1.Workspace.ID, codersdk.UpdateWorkspaceLabelsRequest{
+		Labels: map[string]string{"team": "platform", "cost-center": "eng-1234"},
+	})
+	require.NoError(t, err)
+	err = memberClient.UpdateWorkspaceLabels(ctx, wsb2.Workspace.ID, codersdk.UpdateWorkspaceLabelsRequest{
+		Labels: map[string]string{"team": "data"},
+	})
+	require.NoError(t, err)
+
+	ws, err = memberClient.Workspace(ctx, wsb1.Workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"team": "platform", "cost-center": "eng-1234"}, ws.Labels)
+
+	// Workspaces can be searched by label, with or without a value.
+	res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{FilterQuery: "label:team=platform"})
+	require.NoError(t, err)
+	require.Len(t, res.Workspaces, 1)
+	require.Equal(t, wsb1.Workspace.ID, res.Workspaces[0].ID)
+	res, err = client.Workspaces(ctx, codersdk.WorkspaceFilter{FilterQuery: "label:team"})
+	require.NoError(t, err)
+	require.Len(t, res.Workspaces, 2)
+	res, err = client.Workspaces(ctx, codersdk.WorkspaceFilter{FilterQuery: "label:team label:cost-center=eng-1234"})
+	require.NoError(t, err)
+	require.Len(t, res.Workspaces, 1)
+	require.Equal(t, wsb1.Workspace.ID, res.Workspaces[0].ID)
+
+	// Updating labels replaces all existing labels.
+	err = memberClient.UpdateWorkspaceLabels(ctx, wsb1.Workspace.ID, codersdk.UpdateWorkspaceLabelsRequest{
+		Labels: map[string]string{"team": "data"},
+	})
+	require.NoError(t, err)
+	ws, err = memberClient.Workspace(ctx, wsb1.Workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"team": "data"}, ws.Labels)
+
+	err = memberClient.UpdateWorkspaceLabels(ctx, wsb1.Workspace.ID, codersdk.UpdateWorkspaceLabelsRequest{
+		Labels: map[string]string{"Team": "data"},
+	})
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+}
+
+func TestWorkspaceSchedulePause(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			client, db           = coderdtest.NewWithDatabase(t, nil)
+			owner                = coderdtest.CreateFirstUser(t, client)
+			memberClient, member = coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+			wsb                  = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{OwnerID: member.ID, OrganizationID: owner.OrganizationID}).Do()
+		)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		pause, err := memberClient.WorkspaceSchedulePause(ctx, wsb.Workspace.ID)
+		require.NoError(t, err)
+		require.Nil(t, pause.PausedUntil)
+
+		pausedUntil := dbtime.Now().Add(14 * 24 * time.Hour)
+		pause, err = memberClient.PutWorkspaceSchedulePause(ctx, wsb.Workspace.ID, codersdk.PutWorkspaceSchedulePauseRequest{
+			PausedUntil: pausedUntil,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, pause.PausedUntil)
+		require.WithinDuration(t, pausedUntil, *pause.PausedUntil, time.Second)
+
+		pause, err = memberClient.WorkspaceSchedulePause(ctx, wsb.Workspace.ID)
+		require.NoError(t, err)
+		require.NotNil(t, pause.PausedUntil)
+		require.WithinDuration(t, pausedUntil, *pause.PausedUntil, time.Second)
+
+		err = memberClient.DeleteWorkspaceSchedulePause(ctx, wsb.Workspace.ID)
+		require.NoError(t, err)
+		pause, err = memberClient.WorkspaceSchedulePause(ctx, wsb.Workspace.ID)
+		require.NoError(t, err)
+		require.Nil(t, pause.PausedUntil)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			client, db           = coderdtest.NewWithDatabase(t, nil)
+			owner                = coderdtest.CreateFirstUser(t, client)
+			memberClient, member = coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+			wsb                  = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{OwnerID: member.ID, OrganizationID: owner.OrganizationID}).Do()
+		)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, pausedUntil := range []time.Time{
+			dbtime.Now().Add(-time.Hour),
+			// The default maximum pause is 30 days.
+			dbtime.Now().Add(31 * 24 * time.Hour),
+		} {
+			_, err := memberClient.PutWorkspaceSchedulePause(ctx, wsb.Workspace.ID, codersdk.PutWorkspaceSchedulePauseRequest{
+				PausedUntil: pausedUntil,
+			})
+			var sdkErr *codersdk.Error
+			require.ErrorAs(t, err, &sdkErr)
+			require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			client, db = coderdtest.NewWithDatabase(t, &coderdtest.Options{
+				DeploymentValues: coderdtest.DeploymentValues(t, func(dv *codersdk.DeploymentValues) {
+					dv.MaxWorkspaceSchedulePause = 0
+				}),
+			})
+			owner                = coderdtest.CreateFirstUser(t, client)
+			memberClient, member = coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+			wsb                  = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{OwnerID: member.ID, OrganizationID: owner.OrganizationID}).Do()
+		)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := memberClient.PutWorkspaceSchedulePause(ctx, wsb.Workspace.ID, codersdk.PutWorkspaceSchedulePauseRequest{
+			PausedUntil: dbtime.Now().Add(time.Hour),
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+	})
+}
+
+func TestWorkspaceEphemeral(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	workspace := coderdtest.CreateWorkspace(t, memberClient, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+		cwr.Ephemeral = true
+	})
+	require.True(t, workspace.Ephemeral)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Stopping an ephemeral workspace deletes it.
+	build, err := memberClient.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStop,
+	})
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceTransitionDelete, build.Transition)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+
+	_, err = memberClient.Workspace(ctx, workspace.ID)
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusGone, sdkErr.StatusCode())
+}
+
 func TestWorkspaceUsageTracking(t *testing.T) {
 	t.Parallel()
 	t.Run("NoExperiment", func(t *testing.T) {
@@ -30,10 +30,36 @@ type ReporterOptions struct {
 	StatsBatcher          Batcher
 	UsageTracker          *UsageTracker
 	UpdateAgentMetricsFn  func(ctx context.Context, labels prometheusmetrics.AgentMetricLabels, metrics []*agentproto.Stats_Metric)
+	// ResourceActivity configures when the resource usage reported by an
+	// agent bumps workspace activity without any open sessions.
+	ResourceActivity ResourceActivityThresholds
 
 	AppStatBatchSize int
 }
 
+// ResourceActivityThresholds are the agent-reported resource usage levels at
+// which a workspace is considered active. A zero threshold is disabled.
+type ResourceActivityThresholds struct {
+	CPUCores              float64
+	NetworkBytesPerSecond int64
+}
+
+// IsActive returns true if the stats report resource usage at or above any
+// enabled threshold.
+func (t ResourceActivityThresholds) IsActive(stats *agentproto.Stats) bool {
+	if t.CPUCores > 0 {
+		if v, ok := stats.MetricValue(agentproto.MetricCPUUsageCores); ok && v >= t.CPUCores {
+			return true
+		}
+	}
+	if t.NetworkBytesPerSecond > 0 {
+		if v, ok := stats.MetricValue(agentproto.MetricNetworkBytesPerSecond); ok && v >= float64(t.NetworkBytesPerSecond) {
+			return true
+		}
+	}
+	return false
+}
+
 type Reporter struct {
 	opts ReporterOptions
 }
@@ -44,6 +70,24 @@ func NewReporter(opts ReporterOptions) *Reporter {
 
 func (r *Reporter) ReportAppStats(ctx context.Context, stats []workspaceapps.StatsReport) error {
 	err := r.opts.Database.InTx(func(tx database.Store) error {
+		// Account for the bandwidth used by port share links, which is
+		// reported once per session.
+		shareLinkBytes := make(map[uuid.UUID]int64)
+		for _, stat := range stats {
+			if stat.ShareLinkID != uuid.Nil && stat.ShareLinkBytes > 0 {
+				shareLinkBytes[stat.ShareLinkID] += stat.ShareLinkBytes
+			}
+		}
+		for id, bytes := range shareLinkBytes {
+			err := tx.AddWorkspacePortShareLinkBytesTransferred(ctx, database.AddWorkspacePortShareLinkBytesTransferredParams{
+				ID:    id,
+				Bytes: bytes,
+			})
+			if err != nil {
+				return xerrors.Errorf("add port share link bytes transferred: %w", err)
+			}
+		}
+
 		maxBatchSize := r.opts.AppStatBatchSize
 		if len(stats) < maxBatchSize {
 			maxBatchSize = len(stats)
@@ -139,13 +183,16 @@ func (r *Reporter) ReportAgentStats(ctx context.Context, now time.Time, workspac
 		}, stats.Metrics)
 	}
 
+	// workspace activity: busy workspaces are active without any sessions
+	active := r.opts.ResourceActivity.IsActive(stats)
+
 	// workspace activity: if no sessions we do not bump activity
-	if usage && stats.SessionCountVscode == 0 && stats.SessionCountJetbrains == 0 && stats.SessionCountReconnectingPty == 0 && stats.SessionCountSsh == 0 {
+	if !active && usage && stats.SessionCountVscode == 0 && stats.SessionCountJetbrains == 0 && stats.SessionCountReconnectingPty == 0 && stats.SessionCountSsh == 0 {
 		return nil
 	}
 
 	// legacy stats: if no active connections we do not bump activity
-	if !usage && stats.ConnectionCount == 0 {
+	if !active && !usage && stats.ConnectionCount == 0 {
 		return nil
 	}
 
@@ -15,6 +15,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/dynamicparameters"
 	"github.com/coder/coder/v2/coderd/files"
+	"github.com/coder/coder/v2/coderd/parametervalidation"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/provisioner/terraform/tfparse"
@@ -60,8 +61,16 @@ type Builder struct {
 	richParameterValues     []codersdk.WorkspaceBuildParameter
 	initiator               uuid.UUID
 	reason                  database.BuildReason
+	reasonMessage           string
+	initiatorAPIKeyName     string
+	initiatorCIJobURL       string
 	templateVersionPresetID uuid.UUID
 
+	// parameterValidation enables calling the template's parameter validation
+	// webhook, if it has one.
+	parameterValidation       bool
+	parameterValidationClient *http.Client
+
 	// used during build, makes function arguments less verbose
 	ctx       context.Context
 	store     database.Store
@@ -69,6 +78,7 @@ type Builder struct {
 
 	// cache of objects, so we only fetch once
 	template                             *database.Template
+	activeVersionID                      *uuid.UUID
 	templateVersion                      *database.TemplateVersion
 	templateVersionJob                   *database.ProvisionerJob
 	terraformValues                      *database.TemplateVersionTerraformValue
@@ -96,7 +106,8 @@ type Option func(Builder) Builder
 // The zero value of this struct means to use the version from the last build.  If there is no last build,
 // the build will fail.
 //
-// setting active: true means to use the active version from the template.
+// setting active: true means to use the active version from the template, or the rolled out version if the
+// workspace is in the cohort of a template version rollout.
 //
 // setting specific to a non-nil value means to use the provided template version ID.
 //
@@ -122,6 +133,10 @@ type stateTarget struct {
 }
 
 func New(w database.Workspace, t database.WorkspaceTransition) Builder {
+	// Ephemeral workspaces are never stopped, stopping them deletes them.
+	if w.Ephemeral && t == database.WorkspaceTransitionStop {
+		t = database.WorkspaceTransitionDelete
+	}
 	return Builder{workspace: w, trans: t}
 }
 
@@ -183,6 +198,22 @@ func (b Builder) Reason(r database.BuildReason) Builder {
 	return b
 }
 
+// ReasonMessage sets a free-form explanation of why the build was started.
+func (b Builder) ReasonMessage(m string) Builder {
+	// nolint: revive
+	b.reasonMessage = m
+	return b
+}
+
+// InitiatorContext records how the initiator started the build: the name of
+// the API token used and the URL of the CI job, if any.
+func (b Builder) InitiatorContext(apiKeyName, ciJobURL string) Builder {
+	// nolint: revive
+	b.initiatorAPIKeyName = apiKeyName
+	b.initiatorCIJobURL = ciJobURL
+	return b
+}
+
 func (b Builder) RichParameterValues(p []codersdk.WorkspaceBuildParameter) Builder {
 	// nolint: revive
 	b.richParameterValues = p
@@ -233,6 +264,16 @@ func (b Builder) TemplateVersionPresetID(id uuid.UUID) Builder {
 	return b
 }
 
+// ParameterValidation calls the template's parameter validation webhook, if it
+// has one, before starting a workspace with new parameter values. A nil client
+// uses http.DefaultClient.
+func (b Builder) ParameterValidation(client *http.Client) Builder {
+	// nolint: revive
+	b.parameterValidation = true
+	b.parameterValidationClient = client
+	return b
+}
+
 type BuildError struct {
 	// Status is a suitable HTTP status code
 	Status  int
@@ -370,6 +411,11 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 		return nil, nil, nil, err // already wrapped BuildError
 	}
 
+	err = b.validateParameters(template)
+	if err != nil {
+		return nil, nil, nil, err // already wrapped BuildError
+	}
+
 	now := dbtime.Now()
 	provisionerJob, err := b.store.InsertProvisionerJob(b.ctx, database.InsertProvisionerJobParams{
 		ID:             uuid.New(),
@@ -420,19 +466,22 @@ func (b *Builder) buildTx(authFunc func(action policy.Action, object rbac.Object
 	var workspaceBuild database.WorkspaceBuild
 	err = b.store.InTx(func(store database.Store) error {
 		err = store.InsertWorkspaceBuild(b.ctx, database.InsertWorkspaceBuildParams{
-			ID:                workspaceBuildID,
-			CreatedAt:         now,
-			UpdatedAt:         now,
-			WorkspaceID:       b.workspace.ID,
-			TemplateVersionID: templateVersionID,
-			BuildNumber:       buildNum,
-			ProvisionerState:  state,
-			InitiatorID:       b.initiator,
-			Transition:        b.trans,
-			JobID:             provisionerJob.ID,
-			Reason:            b.reason,
-			Deadline:          time.Time{}, // set by provisioner upon completion
-			MaxDeadline:       time.Time{}, // set by provisioner upon completion
+			ID:                  workspaceBuildID,
+			CreatedAt:           now,
+			UpdatedAt:           now,
+			WorkspaceID:         b.workspace.ID,
+			TemplateVersionID:   templateVersionID,
+			BuildNumber:         buildNum,
+			ProvisionerState:    state,
+			InitiatorID:         b.initiator,
+			Transition:          b.trans,
+			JobID:               provisionerJob.ID,
+			Reason:              b.reason,
+			ReasonMessage:       b.reasonMessage,
+			InitiatorAPIKeyName: b.initiatorAPIKeyName,
+			InitiatorCIJobURL:   b.initiatorCIJobURL,
+			Deadline:            time.Time{}, // set by provisioner upon completion
+			MaxDeadline:         time.Time{}, // set by provisioner upon completion
 			TemplateVersionPresetID: uuid.NullUUID{
 				UUID:  b.templateVersionPresetID,
 				Valid: b.templateVersionPresetID != uuid.Nil,
@@ -572,11 +621,7 @@ func (b *Builder) getTemplateVersionID() (uuid.UUID, error) {
 		return *b.version.specific, nil
 	}
 	if b.version.active {
-		t, err := b.getTemplate()
-		if err != nil {
-			return uuid.Nil, xerrors.Errorf("get template so we can get active version: %w", err)
-		}
-		return t.ActiveVersionID, nil
+		return b.getActiveVersionID()
 	}
 	// default is prior version
 	bld, err := b.getLastBuild()
@@ -586,6 +631,30 @@ func (b *Builder) getTemplateVersionID() (uuid.UUID, error) {
 	return bld.TemplateVersionID, nil
 }
 
+// getActiveVersionID returns the active version of the template, or the rolled
+// out version if the workspace is in the cohort of a template version rollout.
+func (b *Builder) getActiveVersionID() (uuid.UUID, error) {
+	if b.activeVersionID != nil {
+		return *b.activeVersionID, nil
+	}
+	t, err := b.getTemplate()
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get template so we can get active version: %w", err)
+	}
+	id := t.ActiveVersionID
+	// nolint:gocritic // The rollout is a property of the template, the
+	// initiator doesn't need to be able to read it.
+	targets, err := b.store.GetTemplateVersionRolloutTargetsByWorkspaceIDs(dbauthz.AsSystemRestricted(b.ctx), []uuid.UUID{b.workspace.ID})
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get template version rollout targets: %w", err)
+	}
+	if len(targets) > 0 {
+		id = targets[0].TemplateVersionID
+	}
+	b.activeVersionID = &id
+	return id, nil
+}
+
 func (b *Builder) getTemplateTerraformValues() (*database.TemplateVersionTerraformValue, error) {
 	if b.terraformValues != nil {
 		return b.terraformValues, nil
@@ -838,6 +907,88 @@ func (b *Builder) findNewBuildParameterValue(name string, presets []database.Tem
 	return nil
 }
 
+// validateParameters calls the template's parameter validation webhook when a
+// workspace is started with parameter values that differ from the last build.
+func (b *Builder) validateParameters(template *database.Template) error {
+	if !b.parameterValidation || template.ParameterValidationURL == "" || b.trans != database.WorkspaceTransitionStart {
+		return nil
+	}
+	names, values, err := b.getParameters()
+	if err != nil {
+		return err // already wrapped BuildError
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	lastBuildParameters, err := b.getLastBuildParameters()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch previous build parameters", err}
+	}
+	previous := make(map[string]string, len(lastBuildParameters))
+	for _, p := range lastBuildParameters {
+		previous[p.Name] = p.Value
+	}
+	changed := len(names) != len(lastBuildParameters)
+	parameters := make([]codersdk.WorkspaceBuildParameter, 0, len(names))
+	for i, name := range names {
+		if v, ok := previous[name]; !ok || v != values[i] {
+			changed = true
+		}
+		parameters = append(parameters, codersdk.WorkspaceBuildParameter{Name: name, Value: values[i]})
+	}
+	if !changed {
+		return nil
+	}
+
+	templateVersionID, err := b.getTemplateVersionID()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch template version", err}
+	}
+	// nolint:gocritic // The webhook identifies the workspace owner and the
+	// initiator, whom the caller may not be allowed to read.
+	sysCtx := dbauthz.AsSystemRestricted(b.ctx)
+	owner, err := b.store.GetUserByID(sysCtx, b.workspace.OwnerID)
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch workspace owner", err}
+	}
+	initiator := owner
+	if b.initiator != owner.ID {
+		initiator, err = b.store.GetUserByID(sysCtx, b.initiator)
+		if err != nil {
+			return BuildError{http.StatusInternalServerError, "failed to fetch build initiator", err}
+		}
+	}
+
+	err = parametervalidation.Validate(b.ctx, b.parameterValidationClient, template.ParameterValidationURL, codersdk.ParameterValidationRequest{
+		OrganizationID:    template.OrganizationID,
+		TemplateID:        template.ID,
+		TemplateName:      template.Name,
+		TemplateVersionID: templateVersionID,
+		WorkspaceID:       b.workspace.ID,
+		WorkspaceName:     b.workspace.Name,
+		Owner:             parameterValidationUser(owner),
+		Initiator:         parameterValidationUser(initiator),
+		Parameters:        parameters,
+	})
+	var rejected *parametervalidation.RejectedError
+	if xerrors.As(err, &rejected) {
+		return BuildError{http.StatusBadRequest, rejected.Message, err}
+	}
+	if err != nil {
+		return BuildError{http.StatusBadGateway, "Unable to validate parameters with the template's validation webhook", err}
+	}
+	return nil
+}
+
+func parameterValidationUser(u database.User) codersdk.ParameterValidationUser {
+	return codersdk.ParameterValidationUser{
+		ID:       u.ID,
+		Username: u.Username,
+		Name:     u.Name,
+		Email:    u.Email,
+	}
+}
+
 func (b *Builder) getLastBuildParameters() ([]database.WorkspaceBuildParameter, error) {
 	if b.lastBuildParameters != nil {
 		return *b.lastBuildParameters, nil
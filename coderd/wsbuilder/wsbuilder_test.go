@@ -233,6 +233,44 @@ func TestBuilder_Reason(t *testing.T) {
 	req.NoError(err)
 }
 
+func TestBuilder_EphemeralStop(t *testing.T) {
+	t.Parallel()
+	req := require.New(t)
+	asrt := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mDB := expectDB(t,
+		// Inputs
+		withTemplate,
+		withInactiveVersion(nil),
+		withLastBuildFound,
+		withTemplateVersionVariables(inactiveVersionID, nil),
+		withRichParameters(nil),
+		withWorkspaceTags(inactiveVersionID, nil),
+		withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+
+		// Outputs
+		expectProvisionerJob(func(_ database.InsertProvisionerJobParams) {
+		}),
+		withInTx,
+		expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+			asrt.Equal(database.WorkspaceTransitionDelete, bld.Transition)
+		}),
+		expectBuildParameters(func(_ database.InsertWorkspaceBuildParametersParams) {
+		}),
+		withBuild,
+	)
+	fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+	ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID, Ephemeral: true}
+	uut := wsbuilder.New(ws, database.WorkspaceTransitionStop)
+	// nolint: dogsled
+	_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+	req.NoError(err)
+}
+
 func TestBuilder_ActiveVersion(t *testing.T) {
 	t.Parallel()
 	req := require.New(t)
@@ -244,6 +282,7 @@ func TestBuilder_ActiveVersion(t *testing.T) {
 	mDB := expectDB(t,
 		// Inputs
 		withTemplate,
+		withNoRolloutTarget,
 		withActiveVersion(nil),
 		withLastBuildNotFound,
 		withTemplateVersionVariables(activeVersionID, nil),
@@ -277,6 +316,49 @@ func TestBuilder_ActiveVersion(t *testing.T) {
 	req.NoError(err)
 }
 
+func TestBuilder_ActiveVersionRollout(t *testing.T) {
+	t.Parallel()
+	req := require.New(t)
+	asrt := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mDB := expectDB(t,
+		// Inputs
+		withTemplate,
+		withRolloutTarget(inactiveVersionID),
+		withInactiveVersion(nil),
+		withLastBuildNotFound,
+		withTemplateVersionVariables(inactiveVersionID, nil),
+		withParameterSchemas(inactiveJobID, nil),
+		withWorkspaceTags(inactiveVersionID, nil),
+		withProvisionerDaemons([]database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow{}),
+
+		// Outputs
+		expectProvisionerJob(func(job database.InsertProvisionerJobParams) {
+			asrt.Equal(inactiveFileID, job.FileID)
+		}),
+
+		withInTx,
+		expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+			// The workspace is in the cohort of the rollout, so it's built
+			// with the rolled out version instead of the active version.
+			asrt.Equal(inactiveVersionID, bld.TemplateVersionID)
+		}),
+		expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
+		}),
+		withBuild,
+	)
+	fc := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+
+	ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+	uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).ActiveVersion()
+	// nolint: dogsled
+	_, _, _, err := uut.Build(ctx, mDB, fc, nil, audit.WorkspaceBuildBaggage{})
+	req.NoError(err)
+}
+
 func TestWorkspaceBuildWithTags(t *testing.T) {
 	t.Parallel()
 
@@ -805,6 +887,7 @@ func TestWorkspaceBuildWithPreset(t *testing.T) {
 	mDB := expectDB(t,
 		// Inputs
 		withTemplate,
+		withNoRolloutTarget,
 		withActiveVersion(nil),
 		// building workspaces using presets with different combinations of parameters
 		// is tested at the API layer, in TestWorkspace. Here, it is sufficient to
@@ -1055,6 +1138,23 @@ func withTemplate(mTx *dbmock.MockStore) {
 		}, nil)
 }
 
+func withNoRolloutTarget(mTx *dbmock.MockStore) {
+	mTx.EXPECT().GetTemplateVersionRolloutTargetsByWorkspaceIDs(gomock.Any(), []uuid.UUID{workspaceID}).
+		Times(1).
+		Return(nil, nil)
+}
+
+func withRolloutTarget(versionID uuid.UUID) func(mTx *dbmock.MockStore) {
+	return func(mTx *dbmock.MockStore) {
+		mTx.EXPECT().GetTemplateVersionRolloutTargetsByWorkspaceIDs(gomock.Any(), []uuid.UUID{workspaceID}).
+			Times(1).
+			Return([]database.GetTemplateVersionRolloutTargetsByWorkspaceIDsRow{{
+				WorkspaceID:       workspaceID,
+				TemplateVersionID: versionID,
+			}}, nil)
+	}
+}
+
 // withInTx runs the given functions on the same db mock.
 func withInTx(mTx *dbmock.MockStore) {
 	mTx.EXPECT().InTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
@@ -0,0 +1,140 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/codersdk/wsjson"
+	"github.com/coder/websocket"
+)
+
+// ActivityEventType is the kind of event in the activity feed.
+type ActivityEventType string
+
+const (
+	// ActivityEventTypeAudit is an audit log entry.
+	ActivityEventTypeAudit ActivityEventType = "audit"
+	// ActivityEventTypeBuild is a workspace build.
+	ActivityEventTypeBuild ActivityEventType = "build"
+	// ActivityEventTypeLifecycle is a lifecycle state transition of a
+	// workspace agent.
+	ActivityEventTypeLifecycle ActivityEventType = "lifecycle"
+)
+
+// ActivityEventTypes lists all activity event types.
+var ActivityEventTypes = []ActivityEventType{
+	ActivityEventTypeAudit,
+	ActivityEventTypeBuild,
+	ActivityEventTypeLifecycle,
+}
+
+func (t ActivityEventType) Valid() bool {
+	switch t {
+	case ActivityEventTypeAudit, ActivityEventTypeBuild, ActivityEventTypeLifecycle:
+		return true
+	default:
+		return false
+	}
+}
+
+// ActivityEvent is an entry of the activity feed, which combines audit logs,
+// workspace builds and workspace agent lifecycle transitions.
+type ActivityEvent struct {
+	ID             uuid.UUID         `json:"id" format:"uuid"`
+	Time           time.Time         `json:"time" format:"date-time"`
+	Type           ActivityEventType `json:"type" enums:"audit,build,lifecycle"`
+	OrganizationID uuid.UUID         `json:"organization_id" format:"uuid"`
+	// UserID is the user that caused the event: the actor of an audit log
+	// entry, the initiator of a build, or the owner of the workspace of an
+	// agent.
+	UserID uuid.UUID `json:"user_id" format:"uuid"`
+	// WorkspaceID is the workspace the event belongs to, if any.
+	WorkspaceID  *uuid.UUID `json:"workspace_id,omitempty" format:"uuid"`
+	ResourceType string     `json:"resource_type"`
+	ResourceID   uuid.UUID  `json:"resource_id" format:"uuid"`
+	ResourceName string     `json:"resource_name"`
+	// Action is the audit action, the transition of a build, or the lifecycle
+	// state an agent entered.
+	Action string `json:"action"`
+	// Status is the HTTP status code of an audit log entry, or the job status
+	// of a build.
+	Status string `json:"status,omitempty"`
+}
+
+// ActivityFeedFilter filters the activity feed. Zero values match all events.
+type ActivityFeedFilter struct {
+	Types          []ActivityEventType `json:"types,omitempty"`
+	OrganizationID uuid.UUID           `json:"organization_id,omitempty" format:"uuid"`
+	WorkspaceID    uuid.UUID           `json:"workspace_id,omitempty" format:"uuid"`
+	UserID         uuid.UUID           `json:"user_id,omitempty" format:"uuid"`
+}
+
+func (f ActivityFeedFilter) setQuery(q url.Values) {
+	if len(f.Types) > 0 {
+		types := make([]string, 0, len(f.Types))
+		for _, t := range f.Types {
+			types = append(types, string(t))
+		}
+		q.Set("types", strings.Join(types, ","))
+	}
+	if f.OrganizationID != uuid.Nil {
+		q.Set("organization_id", f.OrganizationID.String())
+	}
+	if f.WorkspaceID != uuid.Nil {
+		q.Set("workspace_id", f.WorkspaceID.String())
+	}
+	if f.UserID != uuid.Nil {
+		q.Set("user_id", f.UserID.String())
+	}
+}
+
+func (f ActivityFeedFilter) asRequestOption() RequestOption {
+	return func(r *http.Request) {
+		q := r.URL.Query()
+		f.setQuery(q)
+		r.URL.RawQuery = q.Encode()
+	}
+}
+
+// ActivityFeedResponse is a page of the activity feed, newest first. Pass the
+// ID of the last event as Pagination.AfterID to get the next page.
+type ActivityFeedResponse struct {
+	Events []ActivityEvent `json:"events"`
+}
+
+// ActivityFeed returns the activity of the deployment, newest first.
+// Pagination.Offset is not supported.
+func (c *Client) ActivityFeed(ctx context.Context, filter ActivityFeedFilter, page Pagination) (ActivityFeedResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/activity", nil, filter.asRequestOption(), page.asRequestOption())
+	if err != nil {
+		return ActivityFeedResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ActivityFeedResponse{}, ReadBodyAsError(res)
+	}
+	var resp ActivityFeedResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// WatchActivityFeed streams the events that happen after the call, oldest
+// first.
+func (c *Client) WatchActivityFeed(ctx context.Context, filter ActivityFeedFilter) (<-chan ActivityEvent, io.Closer, error) {
+	q := url.Values{}
+	filter.setQuery(q)
+	conn, err := c.Dial(ctx, "/api/v2/activity/watch?"+q.Encode(), &websocket.DialOptions{
+		CompressionMode: websocket.CompressionDisabled,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	d := wsjson.NewDecoder[ActivityEvent](conn, websocket.MessageText, c.logger)
+	return d.Chan(), d, nil
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/apiversion"
+	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/drpcsdk"
@@ -114,18 +115,22 @@ type Manifest struct {
 	// GitAuthConfigs stores the number of Git configurations
 	// the Coder deployment has. If this number is >0, we
 	// set up special configuration in the workspace.
-	GitAuthConfigs           int                                          `json:"git_auth_configs"`
-	VSCodePortProxyURI       string                                       `json:"vscode_port_proxy_uri"`
-	Apps                     []codersdk.WorkspaceApp                      `json:"apps"`
-	DERPMap                  *tailcfg.DERPMap                             `json:"derpmap"`
-	DERPForceWebSockets      bool                                         `json:"derp_force_websockets"`
-	EnvironmentVariables     map[string]string                            `json:"environment_variables"`
-	Directory                string                                       `json:"directory"`
-	MOTDFile                 string                                       `json:"motd_file"`
-	DisableDirectConnections bool                                         `json:"disable_direct_connections"`
-	Metadata                 []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
-	Scripts                  []codersdk.WorkspaceAgentScript              `json:"scripts"`
-	Devcontainers            []codersdk.WorkspaceAgentDevcontainer        `json:"devcontainers"`
+	GitAuthConfigs             int                                          `json:"git_auth_configs"`
+	VSCodePortProxyURI         string                                       `json:"vscode_port_proxy_uri"`
+	Apps                       []codersdk.WorkspaceApp                      `json:"apps"`
+	DERPMap                    *tailcfg.DERPMap                             `json:"derpmap"`
+	DERPForceWebSockets        bool                                         `json:"derp_force_websockets"`
+	EnvironmentVariables       map[string]string                            `json:"environment_variables"`
+	Directory                  string                                       `json:"directory"`
+	MOTDFile                   string                                       `json:"motd_file"`
+	DisableDirectConnections   bool                                         `json:"disable_direct_connections"`
+	NetcheckInterval           time.Duration                                `json:"netcheck_interval"`
+	WireguardMTU               int                                          `json:"wireguard_mtu"`
+	WireguardKeepaliveInterval time.Duration                                `json:"wireguard_keepalive_interval"`
+	WireguardHandshakeTimeout  time.Duration                                `json:"wireguard_handshake_timeout"`
+	Metadata                   []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
+	Scripts                    []codersdk.WorkspaceAgentScript              `json:"scripts"`
+	Devcontainers              []codersdk.WorkspaceAgentDevcontainer        `json:"devcontainers"`
 }
 
 type LogSource struct {
@@ -282,6 +287,17 @@ func (c *Client) ConnectRPC26(ctx context.Context) (
 	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
 }
 
+// ConnectRPC27 returns a dRPC client to the Agent API v2.7.
+func (c *Client) ConnectRPC27(ctx context.Context) (
+	proto.DRPCAgentClient27, tailnetproto.DRPCTailnetClient27, error,
+) {
+	conn, err := c.connectRPCVersion(ctx, apiversion.New(2, 7))
+	if err != nil {
+		return nil, nil, err
+	}
+	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
+}
+
 // ConnectRPC connects to the workspace agent API and tailnet API
 func (c *Client) ConnectRPC(ctx context.Context) (drpc.Conn, error) {
 	return c.connectRPCVersion(ctx, proto.CurrentVersion)
@@ -311,6 +327,10 @@ func (c *Client) connectRPCVersion(ctx context.Context, version *apiversion.APIV
 	// nolint:bodyclose
 	conn, res, err := websocket.Dial(ctx, rpcURL.String(), &websocket.DialOptions{
 		HTTPClient: httpClient,
+		// Deployments reject agents older than their minimum agent version.
+		HTTPHeader: http.Header{
+			codersdk.BuildVersionHeader: []string{buildinfo.Version()},
+		},
 	})
 	if err != nil {
 		if res == nil {
@@ -507,6 +527,40 @@ func (c *Client) AuthAzureInstanceIdentity(ctx context.Context) (AuthenticateRes
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// AdoptWorkspaceRequest exchanges a workspace adoption token for a
+// workspace bound to the machine.
+type AdoptWorkspaceRequest struct {
+	Token           string `json:"token" validate:"required"`
+	Hostname        string `json:"hostname"`
+	OperatingSystem string `json:"operating_system" validate:"required"`
+	Architecture    string `json:"architecture" validate:"required"`
+}
+
+// AdoptWorkspaceResponse is returned when a machine was adopted as a
+// workspace.
+type AdoptWorkspaceResponse struct {
+	WorkspaceID   uuid.UUID `json:"workspace_id" format:"uuid"`
+	WorkspaceName string    `json:"workspace_name"`
+	AgentID       uuid.UUID `json:"agent_id" format:"uuid"`
+	// SessionToken authenticates the agent running on the machine.
+	SessionToken string `json:"session_token"`
+}
+
+// AdoptWorkspace adopts the machine as a workspace. The machine is not
+// managed by a provisioner, so the workspace can only be deleted.
+func (c *Client) AdoptWorkspace(ctx context.Context, req AdoptWorkspaceRequest) (AdoptWorkspaceResponse, error) {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/adopt", req)
+	if err != nil {
+		return AdoptWorkspaceResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return AdoptWorkspaceResponse{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp AdoptWorkspaceResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // Stats records the Agent's network connection statistics for use in
 // user-facing metrics and debugging.
 type Stats struct {
@@ -705,6 +759,192 @@ func (c *Client) ExternalAuth(ctx context.Context, req ExternalAuthRequest) (Ext
 	return authResp, json.NewDecoder(res.Body).Decode(&authResp)
 }
 
+// SecretEnv is a user secret that is exposed to the workspace as an
+// environment variable.
+type SecretEnv struct {
+	Name    string `json:"name"`
+	EnvName string `json:"env_name"`
+	Value   string `json:"value"`
+}
+
+// Secrets returns the workspace owner's secrets that have an environment
+// variable name set. Every call is recorded in the audit log.
+func (c *Client) Secrets(ctx context.Context) ([]SecretEnv, error) {
+	res, err := c.SDK.Request(ctx, http.MethodGet, "/api/v2/workspaceagents/me/secrets", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+
+	var secrets []SecretEnv
+	return secrets, json.NewDecoder(res.Body).Decode(&secrets)
+}
+
+// VaultToken is a HashiCorp Vault token scoped to the workspace owner.
+type VaultToken struct {
+	// Address is the address of the Vault server.
+	Address string `json:"address"`
+	// Namespace is the Vault Enterprise namespace, if any.
+	Namespace string `json:"namespace,omitempty"`
+	Token     string `json:"token"`
+	// ExpiresAt is zero if the token does not expire.
+	ExpiresAt time.Time `json:"expires_at,omitempty" format:"date-time"`
+}
+
+// VaultToken returns a Vault token scoped to the workspace owner. Tokens are
+// renewed by coderd, so callers should request a token again before the
+// current one expires instead of caching it.
+func (c *Client) VaultToken(ctx context.Context) (VaultToken, error) {
+	res, err := c.SDK.Request(ctx, http.MethodGet, "/api/v2/workspaceagents/me/vault-token", nil)
+	if err != nil {
+		return VaultToken{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return VaultToken{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var token VaultToken
+	return token, json.NewDecoder(res.Body).Decode(&token)
+}
+
+// IssueCertificate requests a client certificate for the agent from the
+// internal CA of the deployment.
+func (c *Client) IssueCertificate(ctx context.Context, req codersdk.IssueCertificateRequest) (codersdk.IssuedCertificate, error) {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/certificate", req)
+	if err != nil {
+		return codersdk.IssuedCertificate{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return codersdk.IssuedCertificate{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var cert codersdk.IssuedCertificate
+	return cert, json.NewDecoder(res.Body).Decode(&cert)
+}
+
+// EgressPolicy is the egress policy the agent enforces on the workspace
+// host.
+type EgressPolicy struct {
+	// Enforced is false when the template does not restrict egress.
+	Enforced       bool     `json:"enforced"`
+	AllowedCIDRs   []string `json:"allowed_cidrs"`
+	AllowedDomains []string `json:"allowed_domains"`
+}
+
+// EgressPolicy returns the egress policy of the workspace's template.
+func (c *Client) EgressPolicy(ctx context.Context) (EgressPolicy, error) {
+	res, err := c.SDK.Request(ctx, http.MethodGet, "/api/v2/workspaceagents/me/egress-policy", nil)
+	if err != nil {
+		return EgressPolicy{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return EgressPolicy{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var policy EgressPolicy
+	return policy, json.NewDecoder(res.Body).Decode(&policy)
+}
+
+// EgressViolation is an outbound connection that was blocked because its
+// destination is not allowed by the egress policy.
+type EgressViolation struct {
+	Destination string    `json:"destination"`
+	Port        uint16    `json:"port"`
+	Protocol    string    `json:"protocol"`
+	BlockedAt   time.Time `json:"blocked_at" format:"date-time"`
+}
+
+// MaxEgressViolationsPerReport is the maximum number of violations accepted
+// in a single report.
+const MaxEgressViolationsPerReport = 100
+
+const (
+	// PushedMetadataKeyPrefix is prepended to the keys of metadata items that
+	// processes in the workspace push to the agent, so they never collide
+	// with the metadata defined in the template.
+	PushedMetadataKeyPrefix = "push:"
+	// MaxPushedMetadata is the maximum number of pushed metadata items of an
+	// agent.
+	MaxPushedMetadata = 32
+)
+
+type ReportEgressViolationsRequest struct {
+	Violations []EgressViolation `json:"violations"`
+}
+
+// ReportEgressViolations records blocked connections in the audit log.
+func (c *Client) ReportEgressViolations(ctx context.Context, req ReportEgressViolationsRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/egress-violations", req)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// TokenRotation describes when the agent should exchange its token for a
+// fresh one.
+type TokenRotation struct {
+	// Enabled is false when the deployment does not rotate agent tokens.
+	Enabled        bool      `json:"enabled"`
+	NextRotationAt time.Time `json:"next_rotation_at" format:"date-time"`
+}
+
+// TokenRotation returns the token rotation schedule of the agent.
+func (c *Client) TokenRotation(ctx context.Context) (TokenRotation, error) {
+	res, err := c.SDK.Request(ctx, http.MethodGet, "/api/v2/workspaceagents/me/token-rotation", nil)
+	if err != nil {
+		return TokenRotation{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return TokenRotation{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var rotation TokenRotation
+	return rotation, json.NewDecoder(res.Body).Decode(&rotation)
+}
+
+type RotateTokenResponse struct {
+	SessionToken string `json:"session_token"`
+	// PreviousTokenExpiresAt is when the token used to make the request
+	// stops being accepted.
+	PreviousTokenExpiresAt time.Time `json:"previous_token_expires_at" format:"date-time"`
+	NextRotationAt         time.Time `json:"next_rotation_at" format:"date-time"`
+}
+
+// RotateToken exchanges the session token of the client for a fresh one.
+// The caller is responsible for using the returned token.
+func (c *Client) RotateToken(ctx context.Context) (RotateTokenResponse, error) {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/token-rotation", nil)
+	if err != nil {
+		return RotateTokenResponse{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return RotateTokenResponse{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var resp RotateTokenResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // LogsNotifyChannel returns the channel name responsible for notifying
 // of new logs.
 func LogsNotifyChannel(agentID uuid.UUID) string {
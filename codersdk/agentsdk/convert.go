@@ -44,24 +44,28 @@ func ManifestFromProto(manifest *proto.Manifest) (Manifest, error) {
 		return Manifest{}, xerrors.Errorf("error converting workspace agent devcontainers: %w", err)
 	}
 	return Manifest{
-		ParentID:                 parentID,
-		AgentID:                  agentID,
-		AgentName:                manifest.AgentName,
-		OwnerName:                manifest.OwnerUsername,
-		WorkspaceID:              workspaceID,
-		WorkspaceName:            manifest.WorkspaceName,
-		Apps:                     apps,
-		Scripts:                  scripts,
-		DERPMap:                  tailnet.DERPMapFromProto(manifest.DerpMap),
-		DERPForceWebSockets:      manifest.DerpForceWebsockets,
-		GitAuthConfigs:           int(manifest.GitAuthConfigs),
-		EnvironmentVariables:     manifest.EnvironmentVariables,
-		Directory:                manifest.Directory,
-		VSCodePortProxyURI:       manifest.VsCodePortProxyUri,
-		MOTDFile:                 manifest.MotdPath,
-		DisableDirectConnections: manifest.DisableDirectConnections,
-		Metadata:                 MetadataDescriptionsFromProto(manifest.Metadata),
-		Devcontainers:            devcontainers,
+		ParentID:                   parentID,
+		AgentID:                    agentID,
+		AgentName:                  manifest.AgentName,
+		OwnerName:                  manifest.OwnerUsername,
+		WorkspaceID:                workspaceID,
+		WorkspaceName:              manifest.WorkspaceName,
+		Apps:                       apps,
+		Scripts:                    scripts,
+		DERPMap:                    tailnet.DERPMapFromProto(manifest.DerpMap),
+		DERPForceWebSockets:        manifest.DerpForceWebsockets,
+		GitAuthConfigs:             int(manifest.GitAuthConfigs),
+		EnvironmentVariables:       manifest.EnvironmentVariables,
+		Directory:                  manifest.Directory,
+		VSCodePortProxyURI:         manifest.VsCodePortProxyUri,
+		MOTDFile:                   manifest.MotdPath,
+		DisableDirectConnections:   manifest.DisableDirectConnections,
+		NetcheckInterval:           manifest.NetcheckInterval.AsDuration(),
+		WireguardMTU:               int(manifest.WireguardMtu),
+		WireguardKeepaliveInterval: manifest.WireguardKeepaliveInterval.AsDuration(),
+		WireguardHandshakeTimeout:  manifest.WireguardHandshakeTimeout.AsDuration(),
+		Metadata:                   MetadataDescriptionsFromProto(manifest.Metadata),
+		Devcontainers:              devcontainers,
 	}, nil
 }
 
@@ -85,11 +89,16 @@ func ProtoFromManifest(manifest Manifest) (*proto.Manifest, error) {
 		MotdPath:                 manifest.MOTDFile,
 		DisableDirectConnections: manifest.DisableDirectConnections,
 		DerpForceWebsockets:      manifest.DERPForceWebSockets,
-		DerpMap:                  tailnet.DERPMapToProto(manifest.DERPMap),
-		Scripts:                  ProtoFromScripts(manifest.Scripts),
-		Apps:                     apps,
-		Metadata:                 ProtoFromMetadataDescriptions(manifest.Metadata),
-		Devcontainers:            ProtoFromDevcontainers(manifest.Devcontainers),
+		NetcheckInterval:         durationpb.New(manifest.NetcheckInterval),
+		// #nosec G115 - Safe conversion as MTU values are expected to be small positive integers
+		WireguardMtu:               int32(manifest.WireguardMTU),
+		WireguardKeepaliveInterval: durationpb.New(manifest.WireguardKeepaliveInterval),
+		WireguardHandshakeTimeout:  durationpb.New(manifest.WireguardHandshakeTimeout),
+		DerpMap:                    tailnet.DERPMapToProto(manifest.DERPMap),
+		Scripts:                    ProtoFromScripts(manifest.Scripts),
+		Apps:                       apps,
+		Metadata:                   ProtoFromMetadataDescriptions(manifest.Metadata),
+		Devcontainers:              ProtoFromDevcontainers(manifest.Devcontainers),
 	}, nil
 }
 
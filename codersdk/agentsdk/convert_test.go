@@ -84,11 +84,15 @@ func TestManifest(t *testing.T) {
 				},
 			},
 		},
-		DERPForceWebSockets:      true,
-		EnvironmentVariables:     map[string]string{"FOO": "bar"},
-		Directory:                "/home/coder",
-		MOTDFile:                 "/etc/motd",
-		DisableDirectConnections: true,
+		DERPForceWebSockets:        true,
+		EnvironmentVariables:       map[string]string{"FOO": "bar"},
+		Directory:                  "/home/coder",
+		MOTDFile:                   "/etc/motd",
+		DisableDirectConnections:   true,
+		NetcheckInterval:           15 * time.Second,
+		WireguardMTU:               1420,
+		WireguardKeepaliveInterval: 10 * time.Second,
+		WireguardHandshakeTimeout:  2 * time.Second,
 		Metadata: []codersdk.WorkspaceAgentMetadataDescription{
 			{
 				DisplayName: "CPU",
@@ -159,11 +163,32 @@ func TestManifest(t *testing.T) {
 	require.Equal(t, manifest.Directory, back.Directory)
 	require.Equal(t, manifest.MOTDFile, back.MOTDFile)
 	require.Equal(t, manifest.DisableDirectConnections, back.DisableDirectConnections)
+	require.Equal(t, manifest.NetcheckInterval, back.NetcheckInterval)
+	require.Equal(t, manifest.WireguardMTU, back.WireguardMTU)
+	require.Equal(t, manifest.WireguardKeepaliveInterval, back.WireguardKeepaliveInterval)
+	require.Equal(t, manifest.WireguardHandshakeTimeout, back.WireguardHandshakeTimeout)
 	require.Equal(t, manifest.Metadata, back.Metadata)
 	require.Equal(t, manifest.Scripts, back.Scripts)
 	require.Equal(t, manifest.Devcontainers, back.Devcontainers)
 }
 
+func TestManifestFromOlderServer(t *testing.T) {
+	t.Parallel()
+	agentID := uuid.New()
+	workspaceID := uuid.New()
+	// Servers older than API v2.7 don't set the netcheck interval or the
+	// WireGuard settings, which the agent treats as the defaults.
+	back, err := agentsdk.ManifestFromProto(&proto.Manifest{
+		AgentId:     agentID[:],
+		WorkspaceId: workspaceID[:],
+	})
+	require.NoError(t, err)
+	require.Zero(t, back.NetcheckInterval)
+	require.Zero(t, back.WireguardMTU)
+	require.Zero(t, back.WireguardKeepaliveInterval)
+	require.Zero(t, back.WireguardHandshakeTimeout)
+}
+
 func TestSubsystems(t *testing.T) {
 	t.Parallel()
 	ss := []codersdk.AgentSubsystem{
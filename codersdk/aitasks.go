@@ -3,8 +3,10 @@ package codersdk
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -44,3 +46,92 @@ func (c *ExperimentalClient) AITaskPrompts(ctx context.Context, buildIDs []uuid.
 	var prompts AITasksPromptsResponse
 	return prompts, json.NewDecoder(res.Body).Decode(&prompts)
 }
+
+type AITaskQueueEntryStatus string
+
+const (
+	// AITaskQueueEntryStatusQueued tasks wait for a running task of their
+	// owner to stop.
+	AITaskQueueEntryStatusQueued AITaskQueueEntryStatus = "queued"
+	// AITaskQueueEntryStatusFailed tasks left the queue, but their workspace
+	// could not be created.
+	AITaskQueueEntryStatusFailed AITaskQueueEntryStatus = "failed"
+)
+
+type CreateAITaskRequest struct {
+	TemplateVersionID       uuid.UUID `json:"template_version_id" validate:"required" format:"uuid"`
+	TemplateVersionPresetID uuid.UUID `json:"template_version_preset_id,omitempty" format:"uuid"`
+	// Name is the name of the workspace of the task.
+	Name   string `json:"name" validate:"workspace_name,required"`
+	Prompt string `json:"prompt" validate:"required"`
+}
+
+// CreateAITaskResponse holds the workspace of the task if it was started, or
+// its queue entry if the owner already runs the maximum number of tasks.
+type CreateAITaskResponse struct {
+	Workspace  *Workspace        `json:"workspace,omitempty"`
+	QueueEntry *AITaskQueueEntry `json:"queue_entry,omitempty"`
+}
+
+// AITaskQueueEntry is a task that waits for a running task of its owner to
+// stop. The workspace of the task is created when it leaves the queue.
+type AITaskQueueEntry struct {
+	ID                      uuid.UUID              `json:"id" format:"uuid"`
+	OwnerID                 uuid.UUID              `json:"owner_id" format:"uuid"`
+	OrganizationID          uuid.UUID              `json:"organization_id" format:"uuid"`
+	TemplateVersionID       uuid.UUID              `json:"template_version_id" format:"uuid"`
+	TemplateVersionPresetID *uuid.UUID             `json:"template_version_preset_id,omitempty" format:"uuid"`
+	Name                    string                 `json:"name"`
+	Prompt                  string                 `json:"prompt"`
+	Status                  AITaskQueueEntryStatus `json:"status"`
+	// Position is the 1-based position of the task among the queued tasks of
+	// its owner. It is 0 for failed tasks.
+	Position int `json:"position"`
+	// Error is why the workspace of a failed task could not be created.
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+}
+
+// CreateAITask starts an AI task for the authenticated user, or queues it if
+// the user already runs the maximum number of tasks.
+func (c *ExperimentalClient) CreateAITask(ctx context.Context, req CreateAITaskRequest) (CreateAITaskResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/experimental/aitasks", req)
+	if err != nil {
+		return CreateAITaskResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusAccepted {
+		return CreateAITaskResponse{}, ReadBodyAsError(res)
+	}
+	var resp CreateAITaskResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// AITaskQueue returns the queued and failed tasks of the authenticated user,
+// oldest first.
+func (c *ExperimentalClient) AITaskQueue(ctx context.Context) ([]AITaskQueueEntry, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/experimental/aitasks/queue", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var entries []AITaskQueueEntry
+	return entries, json.NewDecoder(res.Body).Decode(&entries)
+}
+
+// CancelAITaskQueueEntry removes a queued task before it is started, or a
+// failed task.
+func (c *ExperimentalClient) CancelAITaskQueueEntry(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/experimental/aitasks/queue/%s", id), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -19,7 +19,7 @@ type APIKey struct {
 	CreatedAt       time.Time   `json:"created_at" validate:"required" format:"date-time"`
 	UpdatedAt       time.Time   `json:"updated_at" validate:"required" format:"date-time"`
 	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token"`
-	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect"`
+	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect,workspace_connect"`
 	TokenName       string      `json:"token_name" validate:"required"`
 	LifetimeSeconds int64       `json:"lifetime_seconds" validate:"required"`
 }
@@ -33,6 +33,7 @@ const (
 	LoginTypeGithub   LoginType = "github"
 	LoginTypeOIDC     LoginType = "oidc"
 	LoginTypeToken    LoginType = "token"
+	LoginTypeSAML     LoginType = "saml"
 	// LoginTypeNone is used if no login method is available for this user.
 	// If this is set, the user has no method of logging in.
 	// API keys can still be created by an owner and used by the user.
@@ -48,6 +49,10 @@ const (
 	// APIKeyScopeApplicationConnect is a scope that allows the user
 	// to connect to applications in a workspace.
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	// APIKeyScopeWorkspaceConnect is a scope that only allows the user to
+	// connect to a single workspace. Keys with this scope are created with
+	// CreateWorkspaceCredential.
+	APIKeyScopeWorkspaceConnect APIKeyScope = "workspace_connect"
 )
 
 type CreateTokenRequest struct {
@@ -78,6 +83,46 @@ func (c *Client) CreateToken(ctx context.Context, userID string, req CreateToken
 	return apiKey, json.NewDecoder(res.Body).Decode(&apiKey)
 }
 
+const (
+	// DefaultWorkspaceCredentialLifetime is the lifetime of a workspace
+	// credential when none is requested.
+	DefaultWorkspaceCredentialLifetime = time.Hour
+	// MaxWorkspaceCredentialLifetime is the longest a workspace credential
+	// can be valid for.
+	MaxWorkspaceCredentialLifetime = 24 * time.Hour
+)
+
+type CreateWorkspaceCredentialRequest struct {
+	// Lifetime defaults to DefaultWorkspaceCredentialLifetime.
+	Lifetime time.Duration `json:"lifetime"`
+}
+
+// WorkspaceCredential is a short-lived API key that can only be used to
+// connect to a single workspace.
+type WorkspaceCredential struct {
+	Key         string    `json:"key"`
+	WorkspaceID uuid.UUID `json:"workspace_id" format:"uuid"`
+	ExpiresAt   time.Time `json:"expires_at" format:"date-time"`
+}
+
+// CreateWorkspaceCredential exchanges the client's session token for a
+// short-lived credential that can only be used to connect to the workspace
+// provided, e.g. over SSH or to its apps. It's meant to be handed to scripts
+// and CI jobs instead of a session token.
+func (c *Client) CreateWorkspaceCredential(ctx context.Context, workspaceID uuid.UUID, req CreateWorkspaceCredentialRequest) (WorkspaceCredential, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/credentials", workspaceID), req)
+	if err != nil {
+		return WorkspaceCredential{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceCredential{}, ReadBodyAsError(res)
+	}
+
+	var credential WorkspaceCredential
+	return credential, json.NewDecoder(res.Body).Decode(&credential)
+}
+
 // CreateAPIKey generates an API key for the user ID provided.
 // CreateToken should be used over CreateAPIKey. CreateToken allows better
 // tracking of the token's usage and allows for custom expiration.
@@ -106,6 +151,17 @@ type APIKeyWithOwner struct {
 	Username string `json:"username"`
 }
 
+// APISession is an API key created by logging in, along with the device
+// that logged in.
+type APISession struct {
+	APIKey
+	IPAddress         string `json:"ip_address"`
+	UserAgent         string `json:"user_agent"`
+	DeviceFingerprint string `json:"device_fingerprint"`
+	// Current is true for the session that made the request.
+	Current bool `json:"current"`
+}
+
 type TokenConfig struct {
 	MaxTokenLifetime time.Duration `json:"max_token_lifetime"`
 }
@@ -188,3 +244,31 @@ func (c *Client) GetTokenConfig(ctx context.Context, userID string) (TokenConfig
 	tokenConfig := TokenConfig{}
 	return tokenConfig, json.NewDecoder(res.Body).Decode(&tokenConfig)
 }
+
+// APISessions lists the active login sessions of a user. API tokens are not
+// included.
+func (c *Client) APISessions(ctx context.Context, user string) ([]APISession, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/sessions", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var sessions []APISession
+	return sessions, json.NewDecoder(res.Body).Decode(&sessions)
+}
+
+// DeleteAPISession revokes a login session of a user.
+func (c *Client) DeleteAPISession(ctx context.Context, user string, id string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/sessions/%s", user, id), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -31,15 +31,24 @@ const (
 	ResourceTypeOrganization          ResourceType = "organization"
 	ResourceTypeOAuth2ProviderApp     ResourceType = "oauth2_provider_app"
 	// nolint:gosec // This is not a secret.
-	ResourceTypeOAuth2ProviderAppSecret     ResourceType = "oauth2_provider_app_secret"
-	ResourceTypeCustomRole                  ResourceType = "custom_role"
-	ResourceTypeOrganizationMember          ResourceType = "organization_member"
-	ResourceTypeNotificationTemplate        ResourceType = "notification_template"
-	ResourceTypeIdpSyncSettingsOrganization ResourceType = "idp_sync_settings_organization"
-	ResourceTypeIdpSyncSettingsGroup        ResourceType = "idp_sync_settings_group"
-	ResourceTypeIdpSyncSettingsRole         ResourceType = "idp_sync_settings_role"
-	ResourceTypeWorkspaceAgent              ResourceType = "workspace_agent"
-	ResourceTypeWorkspaceApp                ResourceType = "workspace_app"
+	ResourceTypeOAuth2ProviderAppSecret          ResourceType = "oauth2_provider_app_secret"
+	ResourceTypeCustomRole                       ResourceType = "custom_role"
+	ResourceTypeOrganizationMember               ResourceType = "organization_member"
+	ResourceTypeNotificationTemplate             ResourceType = "notification_template"
+	ResourceTypeIdpSyncSettingsOrganization      ResourceType = "idp_sync_settings_organization"
+	ResourceTypeIdpSyncSettingsGroup             ResourceType = "idp_sync_settings_group"
+	ResourceTypeIdpSyncSettingsRole              ResourceType = "idp_sync_settings_role"
+	ResourceTypeWorkspaceAgent                   ResourceType = "workspace_agent"
+	ResourceTypeWorkspaceApp                     ResourceType = "workspace_app"
+	ResourceTypeTemplateVersionActivationRequest ResourceType = "template_version_activation_request"
+	// nolint:gosec // This is not a secret.
+	ResourceTypeUserSecret ResourceType = "user_secret"
+	// nolint:gosec // This is not a secret.
+	ResourceTypeTemplateSecret         ResourceType = "template_secret"
+	ResourceTypeTemplateEgressPolicy   ResourceType = "template_egress_policy"
+	ResourceTypeWorkspacePortShareLink ResourceType = "workspace_port_share_link"
+	ResourceTypeVariableSet            ResourceType = "variable_set"
+	ResourceTypeTemplateVersionRollout ResourceType = "template_version_rollout"
 )
 
 func (r ResourceType) FriendlyString() string {
@@ -96,6 +105,20 @@ func (r ResourceType) FriendlyString() string {
 		return "workspace agent"
 	case ResourceTypeWorkspaceApp:
 		return "workspace app"
+	case ResourceTypeTemplateVersionActivationRequest:
+		return "template version activation request"
+	case ResourceTypeUserSecret:
+		return "user secret"
+	case ResourceTypeTemplateSecret:
+		return "template secret"
+	case ResourceTypeTemplateEgressPolicy:
+		return "template egress policy"
+	case ResourceTypeWorkspacePortShareLink:
+		return "workspace port share link"
+	case ResourceTypeVariableSet:
+		return "variable set"
+	case ResourceTypeTemplateVersionRollout:
+		return "template version rollout"
 	default:
 		return "unknown"
 	}
@@ -117,6 +140,13 @@ const (
 	AuditActionDisconnect           AuditAction = "disconnect"
 	AuditActionOpen                 AuditAction = "open"
 	AuditActionClose                AuditAction = "close"
+	AuditActionRead                 AuditAction = "read"
+	// AuditActionEgressViolation is recorded when an agent blocks a
+	// connection that is not allowed by the egress policy of its template.
+	AuditActionEgressViolation AuditAction = "egress_violation"
+	// AuditActionNetworkZoneViolation is recorded when a request is rejected
+	// because it comes from outside a network zone of the user.
+	AuditActionNetworkZoneViolation AuditAction = "network_zone_violation"
 )
 
 func (a AuditAction) Friendly() string {
@@ -147,6 +177,12 @@ func (a AuditAction) Friendly() string {
 		return "opened"
 	case AuditActionClose:
 		return "closed"
+	case AuditActionRead:
+		return "read"
+	case AuditActionEgressViolation:
+		return "violated the egress policy of"
+	case AuditActionNetworkZoneViolation:
+		return "was blocked by a network zone as"
 	default:
 		return "unknown"
 	}
@@ -0,0 +1,106 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// AuditSettings are the audit log settings of an organization.
+type AuditSettings struct {
+	// RetentionDays is the number of days audit logs of the organization are
+	// kept for before they are purged. Zero keeps audit logs forever.
+	RetentionDays int32 `json:"retention_days"`
+}
+
+// AuditLegalHold exempts the audit logs of a user or a workspace from being
+// purged. Exactly one of UserID and WorkspaceID is set.
+type AuditLegalHold struct {
+	ID             uuid.UUID  `json:"id" format:"uuid"`
+	OrganizationID uuid.UUID  `json:"organization_id" format:"uuid"`
+	UserID         *uuid.UUID `json:"user_id,omitempty" format:"uuid"`
+	WorkspaceID    *uuid.UUID `json:"workspace_id,omitempty" format:"uuid"`
+	Reason         string     `json:"reason"`
+	CreatedBy      uuid.UUID  `json:"created_by" format:"uuid"`
+	CreatedAt      time.Time  `json:"created_at" format:"date-time"`
+}
+
+type CreateAuditLegalHoldRequest struct {
+	UserID      *uuid.UUID `json:"user_id,omitempty" format:"uuid"`
+	WorkspaceID *uuid.UUID `json:"workspace_id,omitempty" format:"uuid"`
+	Reason      string     `json:"reason" validate:"required"`
+}
+
+func (c *Client) OrganizationAuditSettings(ctx context.Context, orgID uuid.UUID) (AuditSettings, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/settings/audit", orgID), nil)
+	if err != nil {
+		return AuditSettings{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return AuditSettings{}, ReadBodyAsError(res)
+	}
+	var resp AuditSettings
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) PatchOrganizationAuditSettings(ctx context.Context, orgID uuid.UUID, req AuditSettings) (AuditSettings, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/organizations/%s/settings/audit", orgID), req)
+	if err != nil {
+		return AuditSettings{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return AuditSettings{}, ReadBodyAsError(res)
+	}
+	var resp AuditSettings
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) AuditLegalHolds(ctx context.Context, orgID uuid.UUID) ([]AuditLegalHold, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/audit/legal-holds", orgID), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []AuditLegalHold
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) CreateAuditLegalHold(ctx context.Context, orgID uuid.UUID, req CreateAuditLegalHoldRequest) (AuditLegalHold, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/audit/legal-holds", orgID), req)
+	if err != nil {
+		return AuditLegalHold{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return AuditLegalHold{}, ReadBodyAsError(res)
+	}
+	var resp AuditLegalHold
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) DeleteAuditLegalHold(ctx context.Context, orgID, holdID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/organizations/%s/audit/legal-holds/%s", orgID, holdID), nil)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
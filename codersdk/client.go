@@ -38,6 +38,12 @@ const (
 	OAuth2StateCookie = "oauth_state"
 	// OAuth2RedirectCookie is the name of the cookie that stores the oauth2 redirect.
 	OAuth2RedirectCookie = "oauth_redirect"
+	// SAMLRequestIDCookie is the name of the cookie that stores the ID of a
+	// pending SAML authentication request.
+	SAMLRequestIDCookie = "saml_request_id"
+	// SAMLRedirectCookie is the name of the cookie that stores the redirect
+	// of a pending SAML authentication request.
+	SAMLRedirectCookie = "saml_redirect"
 
 	// PathAppSessionTokenCookie is the name of the cookie that stores an
 	// application-scoped API token on workspace proxy path app domains.
@@ -61,6 +67,15 @@ const (
 	// apps.
 	//nolint:gosec
 	SignedAppTokenQueryParameter = "coder_signed_app_token_23db1dde"
+	// PortShareLinkCookie is the name of the cookie that stores the token of
+	// a port share link on the subdomain of the shared port.
+	//nolint:gosec
+	PortShareLinkCookie = "coder_port_share_link"
+	// PortShareLinkQueryParameter is the name of the query parameter that
+	// stores the token of a port share link in its public URL. It has a random
+	// suffix to avoid conflict with user query parameters on apps.
+	//nolint:gosec
+	PortShareLinkQueryParameter = "coder_port_share_link_9c2f1a"
 
 	// BypassRatelimitHeader is the custom header to use to bypass ratelimits.
 	// Only owners can bypass rate limits. This is typically used for scale testing.
@@ -140,6 +155,10 @@ type Client struct {
 	// through DERP, regardless of the BlockEndpoints setting on each
 	// connection.
 	DisableDirectConnections bool
+
+	// ResponseCache may be set to revalidate cached responses with
+	// If-None-Match instead of downloading them again. See ResponseCache.
+	ResponseCache *ResponseCache
 }
 
 // Logger returns the logger for the client.
@@ -258,6 +277,7 @@ func (c *Client) Request(ctx context.Context, method, path string, body interfac
 	for _, opt := range opts {
 		opt(req)
 	}
+	cacheKey := c.ResponseCache.prepare(req, c.SessionToken())
 
 	span.SetAttributes(httpconv.ClientRequest(req)...)
 
@@ -291,6 +311,10 @@ func (c *Client) Request(ctx context.Context, method, path string, body interfac
 		_, _ = c.PlainLogger.Write(out)
 	}
 
+	if err != nil {
+		return nil, err
+	}
+	resp, err = c.ResponseCache.handle(cacheKey, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,198 @@
+// Package clientcert keeps a client certificate issued by the internal CA of
+// a Coder deployment up to date. Agents, workspace proxies and provisioner
+// daemons present the certificate on their connections to coderd.
+package clientcert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/quartz"
+)
+
+// retryInterval is how long to wait before retrying a failed renewal.
+const retryInterval = 30 * time.Second
+
+// IssueFunc requests a certificate from coderd.
+type IssueFunc func(ctx context.Context, req codersdk.IssueCertificateRequest) (codersdk.IssuedCertificate, error)
+
+type Option func(*Renewer)
+
+func WithClock(clock quartz.Clock) Option {
+	return func(r *Renewer) {
+		r.clock = clock
+	}
+}
+
+// Renewer requests a client certificate and renews it once two thirds of its
+// lifetime has passed. A new private key is generated for every renewal.
+type Renewer struct {
+	logger slog.Logger
+	clock  quartz.Clock
+	issue  IssueFunc
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	expiresAt time.Time
+}
+
+func New(logger slog.Logger, issue IssueFunc, opts ...Option) *Renewer {
+	r := &Renewer{
+		logger: logger.Named("clientcert"),
+		clock:  quartz.NewReal(),
+		issue:  issue,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Start requests the first certificate before returning, so it is presented
+// on the first requests to coderd, and renews it in the background until the
+// context is canceled.
+func (r *Renewer) Start(ctx context.Context) {
+	wait, ok := r.renewOnce(ctx)
+	if !ok {
+		return
+	}
+	go func() {
+		if r.sleep(ctx, wait) {
+			r.Run(ctx)
+		}
+	}()
+}
+
+// Run renews the certificate until the context is canceled. It returns early
+// if the deployment does not have the internal CA enabled.
+func (r *Renewer) Run(ctx context.Context) {
+	for {
+		wait, ok := r.renewOnce(ctx)
+		if !ok || !r.sleep(ctx, wait) {
+			return
+		}
+	}
+}
+
+// renewOnce renews the certificate and returns how long to wait before the
+// next attempt. It returns false when renewal should stop.
+func (r *Renewer) renewOnce(ctx context.Context) (time.Duration, bool) {
+	expiresAt, err := r.Renew(ctx)
+	switch {
+	case err == nil:
+		return expiresAt.Sub(r.clock.Now()) * 2 / 3, true
+	case ctx.Err() != nil:
+		return 0, false
+	case isNotFound(err):
+		r.logger.Debug(ctx, "internal ca is not enabled, client certificates will not be used")
+		return 0, false
+	default:
+		r.logger.Warn(ctx, "renew client certificate", slog.Error(err))
+		return retryInterval, true
+	}
+}
+
+func (r *Renewer) sleep(ctx context.Context, d time.Duration) bool {
+	timer := r.clock.NewTimer(d, "clientcert", "renew")
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// Renew requests a new certificate and returns when it expires.
+func (r *Renewer) Renew(ctx context.Context) (time.Time, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("generate key: %w", err)
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "coder"},
+	}, key)
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("create csr: %w", err)
+	}
+	issued, err := r.issue(ctx, codersdk.IssueCertificateRequest{
+		CSR: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})),
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	block, _ := pem.Decode([]byte(issued.Certificate))
+	if block == nil {
+		return time.Time{}, xerrors.New("issued certificate is not PEM encoded")
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("parse issued certificate: %w", err)
+	}
+
+	r.mu.Lock()
+	r.cert = &tls.Certificate{
+		Certificate: [][]byte{block.Bytes},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}
+	r.expiresAt = issued.ExpiresAt
+	r.mu.Unlock()
+
+	r.logger.Debug(ctx, "renewed client certificate", slog.F("expires_at", issued.ExpiresAt))
+	return issued.ExpiresAt, nil
+}
+
+// GetClientCertificate is suitable for tls.Config.GetClientCertificate. An
+// empty certificate is returned when none has been issued yet, so the
+// connection proceeds without one.
+func (r *Renewer) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.cert == nil || !r.clock.Now().Before(r.expiresAt) {
+		return &tls.Certificate{}, nil
+	}
+	return r.cert, nil
+}
+
+// Transport returns a copy of the transport that presents the client
+// certificate. A nil transport is treated as http.DefaultTransport. Other
+// round trippers are returned unchanged since their TLS configuration cannot
+// be modified.
+func (r *Renewer) Transport(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return rt
+	}
+	t = t.Clone()
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	t.TLSClientConfig.GetClientCertificate = r.GetClientCertificate
+	return t
+}
+
+func isNotFound(err error) bool {
+	var sdkErr *codersdk.Error
+	return errors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound
+}
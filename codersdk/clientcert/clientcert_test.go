@@ -0,0 +1,127 @@
+package clientcert_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/quartz"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/clientcert"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestRenewer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Renew", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		mClock := quartz.NewMock(t)
+		var issued atomic.Int64
+		issue := func(_ context.Context, req codersdk.IssueCertificateRequest) (codersdk.IssuedCertificate, error) {
+			issued.Add(1)
+			return sign(t, req.CSR, mClock.Now().Add(time.Hour)), nil
+		}
+		r := clientcert.New(slogtest.Make(t, nil), issue, clientcert.WithClock(mClock))
+
+		cert, err := r.GetClientCertificate(nil)
+		require.NoError(t, err)
+		require.Empty(t, cert.Certificate)
+
+		trap := mClock.Trap().NewTimer("clientcert", "renew")
+		defer trap.Close()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			r.Run(ctx)
+		}()
+
+		call := trap.MustWait(ctx)
+		require.Equal(t, 40*time.Minute, call.Duration)
+		call.MustRelease(ctx)
+		cert, err = r.GetClientCertificate(nil)
+		require.NoError(t, err)
+		require.Len(t, cert.Certificate, 1)
+		first := cert.Leaf
+
+		mClock.Advance(40 * time.Minute).MustWait(ctx)
+		trap.MustWait(ctx).MustRelease(ctx)
+		cert, err = r.GetClientCertificate(nil)
+		require.NoError(t, err)
+		require.NotEqual(t, first.SerialNumber, cert.Leaf.SerialNumber)
+		require.EqualValues(t, 2, issued.Load())
+	})
+
+	t.Run("NotEnabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		t.Cleanup(srv.Close)
+		srvURL, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		client := codersdk.New(srvURL)
+
+		r := clientcert.New(slogtest.Make(t, nil), func(ctx context.Context, req codersdk.IssueCertificateRequest) (codersdk.IssuedCertificate, error) {
+			return client.IssueProvisionerDaemonCertificate(ctx, codersdk.IssueProvisionerDaemonCertificateRequest{
+				IssueCertificateRequest: req,
+			})
+		})
+		// Run returns immediately when the deployment has no internal CA.
+		r.Run(ctx)
+		require.NoError(t, ctx.Err())
+	})
+}
+
+func sign(t *testing.T, csrPEM string, notAfter time.Time) codersdk.IssuedCertificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotAfter:              notAfter.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &key.PublicKey, key)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	block, _ := pem.Decode([]byte(csrPEM))
+	require.NotNil(t, block)
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: serial,
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, csr.PublicKey, key)
+	require.NoError(t, err)
+	return codersdk.IssuedCertificate{
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		CABundle:    string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})),
+		ExpiresAt:   notAfter,
+	}
+}
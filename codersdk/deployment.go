@@ -26,6 +26,7 @@ import (
 
 	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/coderd/agentmetrics"
+	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
 )
 
@@ -337,69 +338,121 @@ var PostgresAuthDrivers = []string{
 	string(PostgresAuthAWSIAMRDS),
 }
 
+// DesktopClipboardPolicy controls which way the clipboard is synchronized in
+// web desktop sessions.
+type DesktopClipboardPolicy string
+
+const (
+	DesktopClipboardPolicyBoth          DesktopClipboardPolicy = "both"
+	DesktopClipboardPolicyToWorkspace   DesktopClipboardPolicy = "to-workspace"
+	DesktopClipboardPolicyFromWorkspace DesktopClipboardPolicy = "from-workspace"
+	DesktopClipboardPolicyNone          DesktopClipboardPolicy = "none"
+)
+
+var DesktopClipboardPolicies = []string{
+	string(DesktopClipboardPolicyBoth),
+	string(DesktopClipboardPolicyToWorkspace),
+	string(DesktopClipboardPolicyFromWorkspace),
+	string(DesktopClipboardPolicyNone),
+}
+
 // DeploymentValues is the central configuration values the coder server.
 type DeploymentValues struct {
 	Verbose             serpent.Bool   `json:"verbose,omitempty"`
 	AccessURL           serpent.URL    `json:"access_url,omitempty"`
 	WildcardAccessURL   serpent.String `json:"wildcard_access_url,omitempty"`
+	PathAppAccessURL    serpent.URL    `json:"path_app_access_url,omitempty"`
+	PathAppSubdomains   serpent.Bool   `json:"path_app_subdomains,omitempty"`
 	DocsURL             serpent.URL    `json:"docs_url,omitempty"`
 	RedirectToAccessURL serpent.Bool   `json:"redirect_to_access_url,omitempty"`
 	// HTTPAddress is a string because it may be set to zero to disable.
-	HTTPAddress                     serpent.String                       `json:"http_address,omitempty" typescript:",notnull"`
-	AutobuildPollInterval           serpent.Duration                     `json:"autobuild_poll_interval,omitempty"`
-	JobReaperDetectorInterval       serpent.Duration                     `json:"job_hang_detector_interval,omitempty"`
-	DERP                            DERP                                 `json:"derp,omitempty" typescript:",notnull"`
-	Prometheus                      PrometheusConfig                     `json:"prometheus,omitempty" typescript:",notnull"`
-	Pprof                           PprofConfig                          `json:"pprof,omitempty" typescript:",notnull"`
-	ProxyTrustedHeaders             serpent.StringArray                  `json:"proxy_trusted_headers,omitempty" typescript:",notnull"`
-	ProxyTrustedOrigins             serpent.StringArray                  `json:"proxy_trusted_origins,omitempty" typescript:",notnull"`
-	CacheDir                        serpent.String                       `json:"cache_directory,omitempty" typescript:",notnull"`
-	EphemeralDeployment             serpent.Bool                         `json:"ephemeral_deployment,omitempty" typescript:",notnull"`
-	PostgresURL                     serpent.String                       `json:"pg_connection_url,omitempty" typescript:",notnull"`
-	PostgresAuth                    string                               `json:"pg_auth,omitempty" typescript:",notnull"`
-	OAuth2                          OAuth2Config                         `json:"oauth2,omitempty" typescript:",notnull"`
-	OIDC                            OIDCConfig                           `json:"oidc,omitempty" typescript:",notnull"`
-	Telemetry                       TelemetryConfig                      `json:"telemetry,omitempty" typescript:",notnull"`
-	TLS                             TLSConfig                            `json:"tls,omitempty" typescript:",notnull"`
-	Trace                           TraceConfig                          `json:"trace,omitempty" typescript:",notnull"`
-	HTTPCookies                     HTTPCookieConfig                     `json:"http_cookies,omitempty" typescript:",notnull"`
-	StrictTransportSecurity         serpent.Int64                        `json:"strict_transport_security,omitempty" typescript:",notnull"`
-	StrictTransportSecurityOptions  serpent.StringArray                  `json:"strict_transport_security_options,omitempty" typescript:",notnull"`
-	SSHKeygenAlgorithm              serpent.String                       `json:"ssh_keygen_algorithm,omitempty" typescript:",notnull"`
-	MetricsCacheRefreshInterval     serpent.Duration                     `json:"metrics_cache_refresh_interval,omitempty" typescript:",notnull"`
-	AgentStatRefreshInterval        serpent.Duration                     `json:"agent_stat_refresh_interval,omitempty" typescript:",notnull"`
-	AgentFallbackTroubleshootingURL serpent.URL                          `json:"agent_fallback_troubleshooting_url,omitempty" typescript:",notnull"`
-	BrowserOnly                     serpent.Bool                         `json:"browser_only,omitempty" typescript:",notnull"`
-	SCIMAPIKey                      serpent.String                       `json:"scim_api_key,omitempty" typescript:",notnull"`
-	ExternalTokenEncryptionKeys     serpent.StringArray                  `json:"external_token_encryption_keys,omitempty" typescript:",notnull"`
-	Provisioner                     ProvisionerConfig                    `json:"provisioner,omitempty" typescript:",notnull"`
-	RateLimit                       RateLimitConfig                      `json:"rate_limit,omitempty" typescript:",notnull"`
-	Experiments                     serpent.StringArray                  `json:"experiments,omitempty" typescript:",notnull"`
-	UpdateCheck                     serpent.Bool                         `json:"update_check,omitempty" typescript:",notnull"`
-	Swagger                         SwaggerConfig                        `json:"swagger,omitempty" typescript:",notnull"`
-	Logging                         LoggingConfig                        `json:"logging,omitempty" typescript:",notnull"`
-	Dangerous                       DangerousConfig                      `json:"dangerous,omitempty" typescript:",notnull"`
-	DisablePathApps                 serpent.Bool                         `json:"disable_path_apps,omitempty" typescript:",notnull"`
-	Sessions                        SessionLifetime                      `json:"session_lifetime,omitempty" typescript:",notnull"`
-	DisablePasswordAuth             serpent.Bool                         `json:"disable_password_auth,omitempty" typescript:",notnull"`
-	Support                         SupportConfig                        `json:"support,omitempty" typescript:",notnull"`
-	ExternalAuthConfigs             serpent.Struct[[]ExternalAuthConfig] `json:"external_auth,omitempty" typescript:",notnull"`
-	SSHConfig                       SSHConfig                            `json:"config_ssh,omitempty" typescript:",notnull"`
-	WgtunnelHost                    serpent.String                       `json:"wgtunnel_host,omitempty" typescript:",notnull"`
-	DisableOwnerWorkspaceExec       serpent.Bool                         `json:"disable_owner_workspace_exec,omitempty" typescript:",notnull"`
-	ProxyHealthStatusInterval       serpent.Duration                     `json:"proxy_health_status_interval,omitempty" typescript:",notnull"`
-	EnableTerraformDebugMode        serpent.Bool                         `json:"enable_terraform_debug_mode,omitempty" typescript:",notnull"`
-	UserQuietHoursSchedule          UserQuietHoursScheduleConfig         `json:"user_quiet_hours_schedule,omitempty" typescript:",notnull"`
-	WebTerminalRenderer             serpent.String                       `json:"web_terminal_renderer,omitempty" typescript:",notnull"`
-	AllowWorkspaceRenames           serpent.Bool                         `json:"allow_workspace_renames,omitempty" typescript:",notnull"`
-	Healthcheck                     HealthcheckConfig                    `json:"healthcheck,omitempty" typescript:",notnull"`
-	CLIUpgradeMessage               serpent.String                       `json:"cli_upgrade_message,omitempty" typescript:",notnull"`
-	TermsOfServiceURL               serpent.String                       `json:"terms_of_service_url,omitempty" typescript:",notnull"`
-	Notifications                   NotificationsConfig                  `json:"notifications,omitempty" typescript:",notnull"`
-	AdditionalCSPPolicy             serpent.StringArray                  `json:"additional_csp_policy,omitempty" typescript:",notnull"`
-	WorkspaceHostnameSuffix         serpent.String                       `json:"workspace_hostname_suffix,omitempty" typescript:",notnull"`
-	Prebuilds                       PrebuildsConfig                      `json:"workspace_prebuilds,omitempty" typescript:",notnull"`
-	HideAITasks                     serpent.Bool                         `json:"hide_ai_tasks,omitempty" typescript:",notnull"`
+	HTTPAddress                       serpent.String                       `json:"http_address,omitempty" typescript:",notnull"`
+	AutobuildPollInterval             serpent.Duration                     `json:"autobuild_poll_interval,omitempty"`
+	JobReaperDetectorInterval         serpent.Duration                     `json:"job_hang_detector_interval,omitempty"`
+	DERP                              DERP                                 `json:"derp,omitempty" typescript:",notnull"`
+	Prometheus                        PrometheusConfig                     `json:"prometheus,omitempty" typescript:",notnull"`
+	Pprof                             PprofConfig                          `json:"pprof,omitempty" typescript:",notnull"`
+	ProxyTrustedHeaders               serpent.StringArray                  `json:"proxy_trusted_headers,omitempty" typescript:",notnull"`
+	ProxyTrustedOrigins               serpent.StringArray                  `json:"proxy_trusted_origins,omitempty" typescript:",notnull"`
+	CacheDir                          serpent.String                       `json:"cache_directory,omitempty" typescript:",notnull"`
+	EphemeralDeployment               serpent.Bool                         `json:"ephemeral_deployment,omitempty" typescript:",notnull"`
+	PostgresURL                       serpent.String                       `json:"pg_connection_url,omitempty" typescript:",notnull"`
+	PostgresAuth                      string                               `json:"pg_auth,omitempty" typescript:",notnull"`
+	OAuth2                            OAuth2Config                         `json:"oauth2,omitempty" typescript:",notnull"`
+	OIDC                              OIDCConfig                           `json:"oidc,omitempty" typescript:",notnull"`
+	SAML                              SAMLConfig                           `json:"saml,omitempty" typescript:",notnull"`
+	LDAP                              LDAPConfig                           `json:"ldap,omitempty" typescript:",notnull"`
+	AuditStreaming                    AuditStreamingConfig                 `json:"audit_streaming,omitempty" typescript:",notnull"`
+	Telemetry                         TelemetryConfig                      `json:"telemetry,omitempty" typescript:",notnull"`
+	TLS                               TLSConfig                            `json:"tls,omitempty" typescript:",notnull"`
+	Trace                             TraceConfig                          `json:"trace,omitempty" typescript:",notnull"`
+	HTTPCookies                       HTTPCookieConfig                     `json:"http_cookies,omitempty" typescript:",notnull"`
+	StrictTransportSecurity           serpent.Int64                        `json:"strict_transport_security,omitempty" typescript:",notnull"`
+	StrictTransportSecurityOptions    serpent.StringArray                  `json:"strict_transport_security_options,omitempty" typescript:",notnull"`
+	SSHKeygenAlgorithm                serpent.String                       `json:"ssh_keygen_algorithm,omitempty" typescript:",notnull"`
+	FIPS                              serpent.Bool                         `json:"fips,omitempty" typescript:",notnull"`
+	MetricsCacheRefreshInterval       serpent.Duration                     `json:"metrics_cache_refresh_interval,omitempty" typescript:",notnull"`
+	AgentStatRefreshInterval          serpent.Duration                     `json:"agent_stat_refresh_interval,omitempty" typescript:",notnull"`
+	AgentFallbackTroubleshootingURL   serpent.URL                          `json:"agent_fallback_troubleshooting_url,omitempty" typescript:",notnull"`
+	AgentTokenRotationInterval        serpent.Duration                     `json:"agent_token_rotation_interval,omitempty" typescript:",notnull"`
+	AgentTokenRotationGracePeriod     serpent.Duration                     `json:"agent_token_rotation_grace_period,omitempty" typescript:",notnull"`
+	BrowserOnly                       serpent.Bool                         `json:"browser_only,omitempty" typescript:",notnull"`
+	Wireguard                         WireguardConfig                      `json:"wireguard,omitempty" typescript:",notnull"`
+	TailnetCoordinatorSharding        serpent.Bool                         `json:"tailnet_coordinator_sharding,omitempty" typescript:",notnull"`
+	SCIMAPIKey                        serpent.String                       `json:"scim_api_key,omitempty" typescript:",notnull"`
+	SCIMGroupsFlattenNested           serpent.Bool                         `json:"scim_groups_flatten_nested,omitempty" typescript:",notnull"`
+	SCIMGroupsDryRun                  serpent.Bool                         `json:"scim_groups_dry_run,omitempty" typescript:",notnull"`
+	ExternalTokenEncryptionKeys       serpent.StringArray                  `json:"external_token_encryption_keys,omitempty" typescript:",notnull"`
+	Provisioner                       ProvisionerConfig                    `json:"provisioner,omitempty" typescript:",notnull"`
+	RateLimit                         RateLimitConfig                      `json:"rate_limit,omitempty" typescript:",notnull"`
+	Experiments                       serpent.StringArray                  `json:"experiments,omitempty" typescript:",notnull"`
+	UpdateCheck                       serpent.Bool                         `json:"update_check,omitempty" typescript:",notnull"`
+	Swagger                           SwaggerConfig                        `json:"swagger,omitempty" typescript:",notnull"`
+	Logging                           LoggingConfig                        `json:"logging,omitempty" typescript:",notnull"`
+	Dangerous                         DangerousConfig                      `json:"dangerous,omitempty" typescript:",notnull"`
+	DisablePathApps                   serpent.Bool                         `json:"disable_path_apps,omitempty" typescript:",notnull"`
+	Sessions                          SessionLifetime                      `json:"session_lifetime,omitempty" typescript:",notnull"`
+	DisablePasswordAuth               serpent.Bool                         `json:"disable_password_auth,omitempty" typescript:",notnull"`
+	Support                           SupportConfig                        `json:"support,omitempty" typescript:",notnull"`
+	ExternalAuthConfigs               serpent.Struct[[]ExternalAuthConfig] `json:"external_auth,omitempty" typescript:",notnull"`
+	SSHConfig                         SSHConfig                            `json:"config_ssh,omitempty" typescript:",notnull"`
+	WgtunnelHost                      serpent.String                       `json:"wgtunnel_host,omitempty" typescript:",notnull"`
+	DisableOwnerWorkspaceExec         serpent.Bool                         `json:"disable_owner_workspace_exec,omitempty" typescript:",notnull"`
+	ProxyHealthStatusInterval         serpent.Duration                     `json:"proxy_health_status_interval,omitempty" typescript:",notnull"`
+	ProxyTokenRotationInterval        serpent.Duration                     `json:"proxy_token_rotation_interval,omitempty" typescript:",notnull"`
+	ProxyTokenRotationGracePeriod     serpent.Duration                     `json:"proxy_token_rotation_grace_period,omitempty" typescript:",notnull"`
+	EnableTerraformDebugMode          serpent.Bool                         `json:"enable_terraform_debug_mode,omitempty" typescript:",notnull"`
+	UserQuietHoursSchedule            UserQuietHoursScheduleConfig         `json:"user_quiet_hours_schedule,omitempty" typescript:",notnull"`
+	WebTerminalRenderer               serpent.String                       `json:"web_terminal_renderer,omitempty" typescript:",notnull"`
+	WebDesktopClipboard               string                               `json:"web_desktop_clipboard,omitempty" typescript:",notnull"`
+	AllowWorkspaceRenames             serpent.Bool                         `json:"allow_workspace_renames,omitempty" typescript:",notnull"`
+	MaxWorkspaceSchedulePause         serpent.Duration                     `json:"max_workspace_schedule_pause,omitempty" typescript:",notnull"`
+	AutostartHolidays                 serpent.StringArray                  `json:"autostart_holidays,omitempty" typescript:",notnull"`
+	AutostartHolidayCalendarURL       serpent.String                       `json:"autostart_holiday_calendar_url,omitempty" typescript:",notnull"`
+	WorkspaceActivityCPUThreshold     serpent.Float64                      `json:"workspace_activity_cpu_threshold,omitempty" typescript:",notnull"`
+	WorkspaceActivityNetworkThreshold serpent.Int64                        `json:"workspace_activity_network_threshold,omitempty" typescript:",notnull"`
+	DormantWorkspaceArchiveURL        serpent.String                       `json:"dormant_workspace_archive_url,omitempty" typescript:",notnull"`
+	DormantWorkspaceArchiveRetention  serpent.Duration                     `json:"dormant_workspace_archive_retention,omitempty" typescript:",notnull"`
+	FileStorageURL                    serpent.String                       `json:"file_storage_url,omitempty" typescript:",notnull"`
+	Healthcheck                       HealthcheckConfig                    `json:"healthcheck,omitempty" typescript:",notnull"`
+	CLIUpgradeMessage                 serpent.String                       `json:"cli_upgrade_message,omitempty" typescript:",notnull"`
+	MinAgentVersion                   serpent.String                       `json:"min_agent_version,omitempty" typescript:",notnull"`
+	MinCLIVersion                     serpent.String                       `json:"min_cli_version,omitempty" typescript:",notnull"`
+	TermsOfServiceURL                 serpent.String                       `json:"terms_of_service_url,omitempty" typescript:",notnull"`
+	Notifications                     NotificationsConfig                  `json:"notifications,omitempty" typescript:",notnull"`
+	AdditionalCSPPolicy               serpent.StringArray                  `json:"additional_csp_policy,omitempty" typescript:",notnull"`
+	WorkspaceHostnameSuffix           serpent.String                       `json:"workspace_hostname_suffix,omitempty" typescript:",notnull"`
+	Prebuilds                         PrebuildsConfig                      `json:"workspace_prebuilds,omitempty" typescript:",notnull"`
+	HideAITasks                       serpent.Bool                         `json:"hide_ai_tasks,omitempty" typescript:",notnull"`
+	AITasksMaxConcurrentPerUser       serpent.Int64                        `json:"ai_tasks_max_concurrent_per_user,omitempty" typescript:",notnull"`
+	PortShareLinks                    serpent.Bool                         `json:"port_share_links,omitempty" typescript:",notnull"`
+	Alerting                          AlertingConfig                       `json:"alerting,omitempty" typescript:",notnull"`
+	ReviewWorkspacesWebhookSecret     serpent.String                       `json:"review_workspaces_webhook_secret,omitempty" typescript:",notnull"`
+	Vault                             VaultConfig                          `json:"vault,omitempty" typescript:",notnull"`
+	InternalCA                        InternalCAConfig                     `json:"internal_ca,omitempty" typescript:",notnull"`
+	Naming                            NamingConfig                         `json:"naming,omitempty" typescript:",notnull"`
+	NetworkZones                      serpent.Struct[[]NetworkZone]        `json:"network_zones,omitempty" typescript:",notnull"`
+	SettingsFile                      serpent.String                       `json:"settings_file,omitempty" typescript:",notnull"`
 
 	Config      serpent.YAMLConfigPath `json:"config,omitempty" typescript:",notnull"`
 	WriteConfig serpent.Bool           `json:"write_config,omitempty" typescript:",notnull"`
@@ -471,6 +524,11 @@ type SessionLifetime struct {
 	MaximumTokenDuration serpent.Duration `json:"max_token_lifetime,omitempty" typescript:",notnull"`
 
 	MaximumAdminTokenDuration serpent.Duration `json:"max_admin_token_lifetime,omitempty" typescript:",notnull"`
+
+	// MaximumSessionsPerUser caps the active login sessions of each user. The
+	// least recently used sessions are revoked when a user logs in beyond it.
+	// Zero means unlimited.
+	MaximumSessionsPerUser serpent.Int64 `json:"max_sessions_per_user,omitempty" typescript:",notnull"`
 }
 
 type DERP struct {
@@ -488,10 +546,22 @@ type DERPServerConfig struct {
 }
 
 type DERPConfig struct {
-	BlockDirect     serpent.Bool   `json:"block_direct" typescript:",notnull"`
-	ForceWebSockets serpent.Bool   `json:"force_websockets" typescript:",notnull"`
-	URL             serpent.String `json:"url" typescript:",notnull"`
-	Path            serpent.String `json:"path" typescript:",notnull"`
+	BlockDirect         serpent.Bool        `json:"block_direct" typescript:",notnull"`
+	ForceWebSockets     serpent.Bool        `json:"force_websockets" typescript:",notnull"`
+	HTTPSFallback       serpent.Bool        `json:"https_fallback" typescript:",notnull"`
+	URL                 serpent.String      `json:"url" typescript:",notnull"`
+	URLRefreshInterval  serpent.Duration    `json:"url_refresh_interval" typescript:",notnull"`
+	Path                serpent.String      `json:"path" typescript:",notnull"`
+	RegionSTUNAddresses serpent.StringArray `json:"region_stun_addresses" typescript:",notnull"`
+	NetcheckInterval    serpent.Duration    `json:"netcheck_interval" typescript:",notnull"`
+}
+
+// WireguardConfig tunes the WireGuard tunnels between workspaces and clients.
+// Templates may override each of these values.
+type WireguardConfig struct {
+	MTU               serpent.Int64    `json:"mtu" typescript:",notnull"`
+	KeepaliveInterval serpent.Duration `json:"keepalive_interval" typescript:",notnull"`
+	HandshakeTimeout  serpent.Duration `json:"handshake_timeout" typescript:",notnull"`
 }
 
 type PrometheusConfig struct {
@@ -566,10 +636,64 @@ type OIDCConfig struct {
 	SkipIssuerChecks          serpent.Bool                           `json:"skip_issuer_checks" typescript:",notnull"`
 }
 
+type SAMLConfig struct {
+	// IDPMetadataURL and IDPMetadataFile are mutually exclusive sources for
+	// the identity provider's SAML metadata.
+	IDPMetadataURL    serpent.String      `json:"idp_metadata_url" typescript:",notnull"`
+	IDPMetadataFile   serpent.String      `json:"idp_metadata_file" typescript:",notnull"`
+	EntityID          serpent.String      `json:"entity_id" typescript:",notnull"`
+	CertFile          serpent.String      `json:"cert_file" typescript:",notnull"`
+	KeyFile           serpent.String      `json:"key_file" typescript:",notnull"`
+	AllowSignups      serpent.Bool        `json:"allow_signups" typescript:",notnull"`
+	EmailDomain       serpent.StringArray `json:"email_domain" typescript:",notnull"`
+	UsernameAttribute serpent.String      `json:"username_attribute" typescript:",notnull"`
+	NameAttribute     serpent.String      `json:"name_attribute" typescript:",notnull"`
+	EmailAttribute    serpent.String      `json:"email_attribute" typescript:",notnull"`
+	SignInText        serpent.String      `json:"sign_in_text" typescript:",notnull"`
+	IconURL           serpent.URL         `json:"icon_url" typescript:",notnull"`
+}
+
+// LDAPConfig configures the periodic sync of users and groups from an LDAP
+// directory, such as Active Directory.
+type LDAPConfig struct {
+	URL                  serpent.String   `json:"url" typescript:",notnull"`
+	BindDN               serpent.String   `json:"bind_dn" typescript:",notnull"`
+	BindPassword         serpent.String   `json:"bind_password" typescript:",notnull"`
+	StartTLS             serpent.Bool     `json:"start_tls" typescript:",notnull"`
+	CAFile               serpent.String   `json:"ca_file" typescript:",notnull"`
+	UserBaseDN           serpent.String   `json:"user_base_dn" typescript:",notnull"`
+	UserFilter           serpent.String   `json:"user_filter" typescript:",notnull"`
+	GroupBaseDN          serpent.String   `json:"group_base_dn" typescript:",notnull"`
+	GroupFilter          serpent.String   `json:"group_filter" typescript:",notnull"`
+	IDAttribute          serpent.String   `json:"id_attribute" typescript:",notnull"`
+	UsernameAttribute    serpent.String   `json:"username_attribute" typescript:",notnull"`
+	EmailAttribute       serpent.String   `json:"email_attribute" typescript:",notnull"`
+	NameAttribute        serpent.String   `json:"name_attribute" typescript:",notnull"`
+	GroupNameAttribute   serpent.String   `json:"group_name_attribute" typescript:",notnull"`
+	GroupMemberAttribute serpent.String   `json:"group_member_attribute" typescript:",notnull"`
+	LoginType            serpent.String   `json:"login_type" typescript:",notnull"`
+	SyncInterval         serpent.Duration `json:"sync_interval" typescript:",notnull"`
+}
+
+// AuditStreamingConfig configures the sinks that audit logs are streamed to
+// as they are written, such as a SIEM.
+type AuditStreamingConfig struct {
+	SplunkHECURL   serpent.URL         `json:"splunk_hec_url" typescript:",notnull"`
+	SplunkHECToken serpent.String      `json:"splunk_hec_token" typescript:",notnull"`
+	DatadogAPIKey  serpent.String      `json:"datadog_api_key" typescript:",notnull"`
+	DatadogSite    serpent.String      `json:"datadog_site" typescript:",notnull"`
+	WebhookURL     serpent.URL         `json:"webhook_url" typescript:",notnull"`
+	WebhookHeaders serpent.StringArray `json:"webhook_headers" typescript:",notnull"`
+	SyslogAddress  serpent.String      `json:"syslog_address" typescript:",notnull"`
+	BufferSize     serpent.Int64       `json:"buffer_size" typescript:",notnull"`
+	MaxAttempts    serpent.Int64       `json:"max_attempts" typescript:",notnull"`
+}
+
 type TelemetryConfig struct {
-	Enable serpent.Bool `json:"enable" typescript:",notnull"`
-	Trace  serpent.Bool `json:"trace" typescript:",notnull"`
-	URL    serpent.URL  `json:"url" typescript:",notnull"`
+	Enable     serpent.Bool        `json:"enable" typescript:",notnull"`
+	Trace      serpent.Bool        `json:"trace" typescript:",notnull"`
+	URL        serpent.URL         `json:"url" typescript:",notnull"`
+	Categories serpent.StringArray `json:"categories" typescript:",notnull"`
 }
 
 type TLSConfig struct {
@@ -657,8 +781,39 @@ type ProvisionerConfig struct {
 	DaemonPollJitter    serpent.Duration    `json:"daemon_poll_jitter" typescript:",notnull"`
 	ForceCancelInterval serpent.Duration    `json:"force_cancel_interval" typescript:",notnull"`
 	DaemonPSK           serpent.String      `json:"daemon_psk" typescript:",notnull"`
+	TemplatePolicyFiles serpent.StringArray `json:"template_policy_files" typescript:",notnull"`
+	// MaxBuildsPerUser is the number of workspace builds of a user's
+	// workspaces that can run at once. Further builds are queued.
+	MaxBuildsPerUser serpent.Int64 `json:"max_builds_per_user" typescript:",notnull"`
+	// MaxBuildsPerTemplate is the number of workspace builds of a template's
+	// workspaces that can run at once. Further builds are queued.
+	MaxBuildsPerTemplate serpent.Int64 `json:"max_builds_per_template" typescript:",notnull"`
+	// MaxConcurrentJobs is the number of provisioner jobs that can run at once
+	// across all organizations. Further jobs are queued.
+	MaxConcurrentJobs serpent.Int64 `json:"max_concurrent_jobs" typescript:",notnull"`
+	// MaxJobsPerOrganization is the number of provisioner jobs of an
+	// organization that can run at once, unless the organization overrides it.
+	MaxJobsPerOrganization serpent.Int64 `json:"max_jobs_per_organization" typescript:",notnull"`
+	// ReservedJobsPerOrganization is the number of jobs out of
+	// MaxConcurrentJobs reserved for each organization, unless the
+	// organization overrides it.
+	ReservedJobsPerOrganization serpent.Int64 `json:"reserved_jobs_per_organization" typescript:",notnull"`
+	// OrphanedJobTimeout is the time since the last heartbeat of the
+	// provisioner daemon running a job after which the job is orphaned.
+	OrphanedJobTimeout serpent.Duration `json:"orphaned_job_timeout" typescript:",notnull"`
+	// OrphanedJobAction is what happens to orphaned jobs, either "fail" or
+	// "requeue".
+	OrphanedJobAction string `json:"orphaned_job_action" typescript:",notnull"`
+	// OrphanedJobMaxRequeues is the number of times a job can be requeued
+	// before it is failed.
+	OrphanedJobMaxRequeues serpent.Int64 `json:"orphaned_job_max_requeues" typescript:",notnull"`
 }
 
+const (
+	OrphanedJobActionFail    = "fail"
+	OrphanedJobActionRequeue = "requeue"
+)
+
 type RateLimitConfig struct {
 	DisableAll serpent.Bool  `json:"disable_all" typescript:",notnull"`
 	API        serpent.Int64 `json:"api" typescript:",notnull"`
@@ -669,10 +824,54 @@ type SwaggerConfig struct {
 }
 
 type LoggingConfig struct {
-	Filter      serpent.StringArray `json:"log_filter" typescript:",notnull"`
-	Human       serpent.String      `json:"human" typescript:",notnull"`
-	JSON        serpent.String      `json:"json" typescript:",notnull"`
-	Stackdriver serpent.String      `json:"stackdriver" typescript:",notnull"`
+	Filter      serpent.StringArray       `json:"log_filter" typescript:",notnull"`
+	Human       serpent.String            `json:"human" typescript:",notnull"`
+	JSON        serpent.String            `json:"json" typescript:",notnull"`
+	Stackdriver serpent.String            `json:"stackdriver" typescript:",notnull"`
+	Sinks       serpent.Struct[[]LogSink] `json:"sinks,omitempty" typescript:",notnull"`
+}
+
+// LogSinkType is a destination for logs in addition to the human, JSON and
+// Stackdriver log locations.
+type LogSinkType string
+
+const (
+	// LogSinkTypeFile writes logs to a file that is rotated by size.
+	LogSinkTypeFile LogSinkType = "file"
+	// LogSinkTypeSyslog sends logs to the local syslog daemon or a remote
+	// syslog server.
+	LogSinkTypeSyslog LogSinkType = "syslog"
+	// LogSinkTypeJournald sends logs to the local systemd journal.
+	LogSinkTypeJournald LogSinkType = "journald"
+	// LogSinkTypeOTLP exports logs to an OpenTelemetry collector over gRPC.
+	LogSinkTypeOTLP LogSinkType = "otlp"
+)
+
+// LogSink configures an additional destination for logs. Each sink has its
+// own minimum level, so for example debug logs can go to a file while only
+// warnings reach syslog.
+type LogSink struct {
+	Type LogSinkType `json:"type" yaml:"type"`
+	// Level is the minimum level of logs written to the sink: debug, info,
+	// warn or error. Defaults to the level of the other log locations.
+	Level string `json:"level,omitempty" yaml:"level"`
+	// Format of file and syslog sinks: human (default), json or stackdriver.
+	Format string `json:"format,omitempty" yaml:"format"`
+	// Path of file sinks.
+	Path string `json:"path,omitempty" yaml:"path"`
+	// MaxSizeMB is the size at which file sinks are rotated. Defaults to 100.
+	MaxSizeMB int `json:"max_size_mb,omitempty" yaml:"max_size_mb"`
+	// MaxBackups is the number of rotated files that are kept. Defaults to 3.
+	MaxBackups int `json:"max_backups,omitempty" yaml:"max_backups"`
+	// MaxAgeDays deletes rotated files older than this. Zero keeps them
+	// regardless of their age.
+	MaxAgeDays int `json:"max_age_days,omitempty" yaml:"max_age_days"`
+	// Address of a remote syslog server, e.g. udp://syslog:514, or the
+	// host:port of an OTLP gRPC log receiver. Syslog sinks default to the
+	// local syslog daemon, OTLP sinks to localhost:4317.
+	Address string `json:"address,omitempty" yaml:"address"`
+	// Insecure disables TLS for OTLP sinks.
+	Insecure bool `json:"insecure,omitempty" yaml:"insecure"`
 }
 
 type DangerousConfig struct {
@@ -695,6 +894,30 @@ type HealthcheckConfig struct {
 	ThresholdDatabase serpent.Duration `json:"threshold_database" typescript:",notnull"`
 }
 
+// AlertingConfig configures the deployment health alerts which are sent to
+// incident management services.
+type AlertingConfig struct {
+	// The PagerDuty Events API v2 integration key to which alerts are sent.
+	PagerDutyRoutingKey serpent.String `json:"pagerduty_routing_key" typescript:",notnull"`
+	// The Opsgenie API key used to create and close alerts.
+	OpsgenieAPIKey serpent.String `json:"opsgenie_api_key" typescript:",notnull"`
+	// The base URL of the Opsgenie API.
+	OpsgenieAPIURL serpent.URL `json:"opsgenie_api_url" typescript:",notnull"`
+	// How often the deployment health is evaluated.
+	Interval serpent.Duration `json:"interval" typescript:",notnull"`
+	// The database latency above which an alert is fired.
+	DatabaseLatencyThreshold serpent.Duration `json:"database_latency_threshold" typescript:",notnull"`
+	// The number of pending provisioner jobs at or above which an alert is fired.
+	ProvisionerQueueThreshold serpent.Int64 `json:"provisioner_queue_threshold" typescript:",notnull"`
+	// How long before a license expires an alert is fired.
+	LicenseExpiryThreshold serpent.Duration `json:"license_expiry_threshold" typescript:",notnull"`
+}
+
+// Enabled reports whether any alert destination is configured.
+func (c *AlertingConfig) Enabled() bool {
+	return c.PagerDutyRoutingKey != "" || c.OpsgenieAPIKey != ""
+}
+
 type NotificationsConfig struct {
 	// The upper limit of attempts to send a notification.
 	MaxSendAttempts serpent.Int64 `json:"max_send_attempts" typescript:",notnull"`
@@ -723,21 +946,29 @@ type NotificationsConfig struct {
 	// How often to query the database for queued notifications.
 	FetchInterval serpent.Duration `json:"fetch_interval"`
 
-	// Which delivery method to use (available options: 'smtp', 'webhook').
+	// Which delivery method to use (available options: 'smtp', 'webhook', 'slack', 'teams').
 	Method serpent.String `json:"method"`
 	// How long to wait while a notification is being sent before giving up.
 	DispatchTimeout serpent.Duration `json:"dispatch_timeout"`
+	// How long a user's quiet hours window lasts, starting from their quiet hours schedule. Notifications which are not
+	// high priority are held back until the window ends. A value of zero disables quiet hours routing.
+	QuietHoursDuration serpent.Duration `json:"quiet_hours_duration"`
 	// SMTP settings.
 	SMTP NotificationsEmailConfig `json:"email" typescript:",notnull"`
 	// Webhook settings.
 	Webhook NotificationsWebhookConfig `json:"webhook" typescript:",notnull"`
+	// Slack settings.
+	Slack NotificationsSlackConfig `json:"slack" typescript:",notnull"`
+	// Microsoft Teams settings.
+	Teams NotificationsTeamsConfig `json:"teams" typescript:",notnull"`
 	// Inbox settings.
 	Inbox NotificationsInboxConfig `json:"inbox" typescript:",notnull"`
 }
 
 // Are either of the notification methods enabled?
 func (n *NotificationsConfig) Enabled() bool {
-	return n.SMTP.Smarthost != "" || n.Webhook.Endpoint != serpent.URL{}
+	return n.SMTP.Smarthost != "" || n.Webhook.Endpoint != serpent.URL{} ||
+		n.Slack.BotToken != "" || n.Teams.Endpoint != serpent.URL{}
 }
 
 type NotificationsInboxConfig struct {
@@ -799,6 +1030,98 @@ type NotificationsWebhookConfig struct {
 	Endpoint serpent.URL `json:"endpoint" typescript:",notnull"`
 }
 
+type NotificationsSlackConfig struct {
+	// The bot user OAuth token used to send direct messages.
+	BotToken serpent.String `json:"bot_token" typescript:",notnull"`
+	// The base URL of the Slack Web API.
+	APIURL serpent.URL `json:"api_url" typescript:",notnull"`
+}
+
+type NotificationsTeamsConfig struct {
+	// The incoming webhook URL to which Adaptive Card messages will be sent.
+	Endpoint serpent.URL `json:"endpoint" typescript:",notnull"`
+}
+
+// VaultConfig configures the HashiCorp Vault integration, which issues
+// short-lived Vault tokens to workspaces.
+type VaultConfig struct {
+	// The address of the Vault server. The integration is disabled when empty.
+	Address serpent.URL `json:"address" typescript:",notnull"`
+	// The Vault Enterprise namespace.
+	Namespace serpent.String `json:"namespace" typescript:",notnull"`
+	// The method coderd uses to authenticate to Vault.
+	AuthMethod string `json:"auth_method" typescript:",notnull"`
+	// The path the auth method is mounted at.
+	AuthMountPath serpent.String `json:"auth_mount_path" typescript:",notnull"`
+	// The Vault token used by the token auth method.
+	Token serpent.String `json:"token" typescript:",notnull"`
+	// The role ID used by the approle auth method.
+	AppRoleRoleID serpent.String `json:"approle_role_id" typescript:",notnull"`
+	// The secret ID used by the approle auth method.
+	AppRoleSecretID serpent.String `json:"approle_secret_id" typescript:",notnull"`
+	// The Vault role used by the kubernetes auth method.
+	KubernetesRole serpent.String `json:"kubernetes_role" typescript:",notnull"`
+	// The service account token used by the kubernetes auth method.
+	KubernetesTokenPath serpent.String `json:"kubernetes_token_path" typescript:",notnull"`
+	// The token role workspace owner tokens are created from.
+	TokenRole serpent.String `json:"token_role" typescript:",notnull"`
+	// The policies attached to workspace owner tokens.
+	TokenPolicies serpent.StringArray `json:"token_policies" typescript:",notnull"`
+	// The lifetime of workspace owner tokens.
+	TokenTTL serpent.Duration `json:"token_ttl" typescript:",notnull"`
+}
+
+// Enabled reports whether a Vault server is configured.
+func (c *VaultConfig) Enabled() bool {
+	return c.Address.String() != ""
+}
+
+// InternalCAConfig configures the private CA that issues client
+// certificates to agents, workspace proxies and provisioner daemons.
+// NamingConfig restricts the names of workspaces and users beyond the rules
+// every name must follow, e.g. to keep them DNS-safe or enforce conventions.
+type NamingConfig struct {
+	WorkspaceName NamePolicy `json:"workspace_name" typescript:",notnull"`
+	Username      NamePolicy `json:"username" typescript:",notnull"`
+}
+
+// NamePolicy is enforced when a name is set. Existing names that violate the
+// policy are kept until they are renamed.
+type NamePolicy struct {
+	// Pattern is a regular expression names must match. It is unanchored
+	// unless the expression is anchored.
+	Pattern serpent.Regexp `json:"pattern" typescript:",notnull"`
+	// MaxLength is the maximum length of names, 0 to not restrict it further.
+	MaxLength serpent.Int64 `json:"max_length" typescript:",notnull"`
+	// ReservedPrefixes names cannot start with, compared case-insensitively.
+	ReservedPrefixes serpent.StringArray `json:"reserved_prefixes" typescript:",notnull"`
+}
+
+// Valid returns an error describing how the name violates the policy.
+func (p *NamePolicy) Valid(name string) error {
+	if p.MaxLength.Value() > 0 && int64(len(name)) > p.MaxLength.Value() {
+		return xerrors.Errorf("must be <= %d characters", p.MaxLength.Value())
+	}
+	for _, prefix := range p.ReservedPrefixes.Value() {
+		if prefix != "" && strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) {
+			return xerrors.Errorf("must not start with the reserved prefix %q", prefix)
+		}
+	}
+	if pattern := p.Pattern.Value(); pattern.String() != "" && !pattern.MatchString(name) {
+		return xerrors.Errorf("must match the pattern %q", pattern.String())
+	}
+	return nil
+}
+
+type InternalCAConfig struct {
+	// Enable issuing client certificates.
+	Enable serpent.Bool `json:"enable" typescript:",notnull"`
+	// The lifetime of issued client certificates.
+	CertificateTTL serpent.Duration `json:"certificate_ttl" typescript:",notnull"`
+	// Reject control-plane requests that do not present a client certificate.
+	RequireMTLS serpent.Bool `json:"require_mtls" typescript:",notnull"`
+}
+
 type PrebuildsConfig struct {
 	// ReconciliationInterval defines how often the workspace prebuilds state should be reconciled.
 	ReconciliationInterval serpent.Duration `json:"reconciliation_interval" typescript:",notnull"`
@@ -825,6 +1148,9 @@ const (
 	// annotationExternalProxies is used to mark options that are used by workspace
 	// proxies. This is used to filter out options that are not relevant.
 	annotationExternalProxies = "external_workspace_proxies"
+	// annotationHotReload is used to mark options that are applied without a
+	// restart when the deployment configuration is reloaded.
+	annotationHotReload = "hot_reload"
 )
 
 // IsWorkspaceProxies returns true if the cli option is used by workspace proxies.
@@ -834,6 +1160,14 @@ func IsWorkspaceProxies(opt serpent.Option) bool {
 	return b
 }
 
+// IsHotReloadableDeploymentOption returns true if changes of the option are
+// applied when the deployment configuration is reloaded. Other options require
+// a restart.
+func IsHotReloadableDeploymentOption(opt serpent.Option) bool {
+	b, _ := strconv.ParseBool(opt.Annotations[annotationHotReload])
+	return b
+}
+
 func IsSecretDeploymentOption(opt serpent.Option) bool {
 	return opt.Annotations.IsSet(annotationSecretKey)
 }
@@ -928,6 +1262,13 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
  Tailscale and WireGuard.`,
 			YAML: "derp",
 		}
+		deploymentGroupNetworkingWireguard = serpent.Group{
+			Parent: &deploymentGroupNetworking,
+			Name:   "WireGuard",
+			Description: `Tune the WireGuard tunnels between workspaces and clients. The defaults
+ work for most networks. Each setting can be overridden per template.`,
+			YAML: "wireguard",
+		}
 		deploymentGroupIntrospection = serpent.Group{
 			Name:        "Introspection",
 			Description: `Configure logging, tracing, and metrics exporting.`,
@@ -958,6 +1299,12 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Name:   "Health Check",
 			YAML:   "healthcheck",
 		}
+		deploymentGroupIntrospectionAlerting = serpent.Group{
+			Parent:      &deploymentGroupIntrospection,
+			Name:        "Alerting",
+			Description: "Send deployment health alerts to PagerDuty or Opsgenie.",
+			YAML:        "alerting",
+		}
 		deploymentGroupOAuth2 = serpent.Group{
 			Name:        "OAuth2",
 			Description: `Configure login and user-provisioning with GitHub via oAuth2.`,
@@ -972,6 +1319,21 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Name: "OIDC",
 			YAML: "oidc",
 		}
+		deploymentGroupSAML = serpent.Group{
+			Name:        "SAML",
+			Description: `Configure login and user-provisioning with a SAML 2.0 identity provider.`,
+			YAML:        "saml",
+		}
+		deploymentGroupLDAP = serpent.Group{
+			Name:        "LDAP",
+			Description: `Sync users and groups from an LDAP directory, such as Active Directory.`,
+			YAML:        "ldap",
+		}
+		deploymentGroupAuditStreaming = serpent.Group{
+			Name:        "Audit Streaming",
+			Description: `Stream audit logs to external destinations, such as a SIEM, as they are written.`,
+			YAML:        "auditStreaming",
+		}
 		deploymentGroupTelemetry = serpent.Group{
 			Name: "Telemetry",
 			YAML: "telemetry",
@@ -1048,6 +1410,16 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Parent: &deploymentGroupNotifications,
 			YAML:   "webhook",
 		}
+		deploymentGroupNotificationsSlack = serpent.Group{
+			Name:   "Slack",
+			Parent: &deploymentGroupNotifications,
+			YAML:   "slack",
+		}
+		deploymentGroupNotificationsTeams = serpent.Group{
+			Name:   "Microsoft Teams",
+			Parent: &deploymentGroupNotifications,
+			YAML:   "teams",
+		}
 		deploymentGroupPrebuilds = serpent.Group{
 			Name:        "Workspace Prebuilds",
 			YAML:        "workspace_prebuilds",
@@ -1058,6 +1430,21 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Parent: &deploymentGroupNotifications,
 			YAML:   "inbox",
 		}
+		deploymentGroupVault = serpent.Group{
+			Name:        "Vault",
+			Description: "Issue short-lived HashiCorp Vault tokens to workspaces.",
+			YAML:        "vault",
+		}
+		deploymentGroupInternalCA = serpent.Group{
+			Name:        "Internal CA",
+			Description: "Issue short-lived client certificates to agents, workspace proxies and provisioner daemons so their traffic to coderd uses mutual TLS.",
+			YAML:        "internalCA",
+		}
+		deploymentGroupNaming = serpent.Group{
+			Name:        "Naming",
+			Description: "Restrict the names of workspaces and users, e.g. to keep them DNS-safe or enforce naming conventions. The rules apply when a name is set, existing names are kept.",
+			YAML:        "naming",
+		}
 	)
 
 	httpAddress := serpent.Option{
@@ -1272,6 +1659,35 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			YAML:        "wildcardAccessURL",
 			Annotations: serpent.Annotations{}.Mark(annotationExternalProxies, "true"),
 		},
+		{
+			Name:        "Path App Access URL",
+			Description: "Serve path-based workspace apps from this URL instead of the access URL. It must use a different hostname than the access URL, but only needs a single DNS record and TLS certificate. Apps served from their own origin cannot read the dashboard session cookie or call the Coder API as the user, which makes this the recommended setup for deployments that cannot configure a --wildcard-access-url.",
+			Flag:        "path-app-access-url",
+			Env:         "CODER_PATH_APP_ACCESS_URL",
+			Value:       &c.PathAppAccessURL,
+			Group:       &deploymentGroupNetworking,
+			YAML:        "pathAppAccessURL",
+		},
+		{
+			Name:        "Path App Subdomains",
+			Description: "Serve every path-based workspace app from its own short-lived, signed subdomain of the --path-app-access-url, so that apps are isolated from each other as well as from the dashboard. Requires a wildcard DNS record and TLS certificate for the subdomains of the --path-app-access-url hostname only.",
+			Flag:        "path-app-subdomains",
+			Env:         "CODER_PATH_APP_SUBDOMAINS",
+			Default:     "false",
+			Value:       &c.PathAppSubdomains,
+			Group:       &deploymentGroupNetworking,
+			YAML:        "pathAppSubdomains",
+		},
+		{
+			Name:        "Port Share Links",
+			Description: "Allow users to create time-limited public links to the ports of their workspaces, optionally protected by a password and limited in bandwidth. Links can only be created for templates whose max port sharing level is public, and require a --wildcard-access-url.",
+			Flag:        "port-share-links",
+			Env:         "CODER_PORT_SHARE_LINKS",
+			Default:     "false",
+			Value:       &c.PortShareLinks,
+			Group:       &deploymentGroupNetworking,
+			YAML:        "portShareLinks",
+		},
 		{
 			Name:        "Docs URL",
 			Description: "Specifies the custom docs URL.",
@@ -1532,6 +1948,16 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Group:       &deploymentGroupNetworkingDERP,
 			YAML:        "forceWebSockets",
 		},
+		{
+			Name:        "DERP HTTPS Fallback",
+			Description: "Switch clients to an HTTPS-only transport when their network check finds that UDP is blocked and DERP over HTTPS is the only path to the workspace. Clients then stop trying direct connections and relay all traffic over WebSockets to the DERP servers, which works on networks that only allow HTTPS to Coder.",
+			Flag:        "derp-https-fallback",
+			Env:         "CODER_DERP_HTTPS_FALLBACK",
+			Default:     "true",
+			Value:       &c.DERP.Config.HTTPSFallback,
+			Group:       &deploymentGroupNetworkingDERP,
+			YAML:        "httpsFallback",
+		},
 		{
 			Name:        "DERP Config URL",
 			Description: "URL to fetch a DERP mapping on startup. See: https://tailscale.com/kb/1118/custom-derp-servers/.",
@@ -1541,6 +1967,16 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Group:       &deploymentGroupNetworkingDERP,
 			YAML:        "url",
 		},
+		{
+			Name:        "DERP Config URL Refresh Interval",
+			Description: "How often to re-fetch the DERP mapping from --derp-config-url. Changes are pushed to connected agents and clients without a restart. If unset, the mapping is only fetched on startup.",
+			Flag:        "derp-config-url-refresh-interval",
+			Env:         "CODER_DERP_CONFIG_URL_REFRESH_INTERVAL",
+			Value:       &c.DERP.Config.URLRefreshInterval,
+			Group:       &deploymentGroupNetworkingDERP,
+			YAML:        "urlRefreshInterval",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
 		{
 			Name:        "DERP Config Path",
 			Description: "Path to read a DERP mapping from. See: https://tailscale.com/kb/1118/custom-derp-servers/.",
@@ -1550,6 +1986,54 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Group:       &deploymentGroupNetworkingDERP,
 			YAML:        "configPath",
 		},
+		{
+			Name:        "DERP Config Region STUN Addresses",
+			Description: "Additional STUN servers to probe for specific regions in the DERP mapping, in the form `REGION_ID=HOST:PORT`. These are probed before the region's own nodes, so deployments that cannot reach public STUN servers can still establish direct connections using internal ones. Ignored when direct connections are blocked.",
+			Flag:        "derp-config-region-stun-addresses",
+			Env:         "CODER_DERP_CONFIG_REGION_STUN_ADDRESSES",
+			Value:       &c.DERP.Config.RegionSTUNAddresses,
+			Group:       &deploymentGroupNetworkingDERP,
+			YAML:        "regionSTUNAddresses",
+		},
+		{
+			Name:        "DERP Config Netcheck Interval",
+			Description: "How often agents and clients re-probe STUN servers to rediscover their public endpoints. Lower values help keep direct connections alive behind NATs that expire UDP mappings quickly. If unset, endpoints are re-probed every 20-26 seconds while connections are active.",
+			Flag:        "derp-config-netcheck-interval",
+			Env:         "CODER_DERP_CONFIG_NETCHECK_INTERVAL",
+			Value:       &c.DERP.Config.NetcheckInterval,
+			Group:       &deploymentGroupNetworkingDERP,
+			YAML:        "netcheckInterval",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "WireGuard MTU",
+			Description: "The MTU of the WireGuard tunnel between workspaces and clients. The default of 1280 is the smallest MTU that IPv6 allows, which leaves room for encapsulation on most overlay networks such as GRE or VXLAN. Raise it to improve throughput when the path MTU between workspaces and clients is known to be larger. Must be between 1280 and 65536.",
+			Flag:        "wireguard-mtu",
+			Env:         "CODER_WIREGUARD_MTU",
+			Value:       &c.Wireguard.MTU,
+			Group:       &deploymentGroupNetworkingWireguard,
+			YAML:        "mtu",
+		},
+		{
+			Name:        "WireGuard Keepalive Interval",
+			Description: "How often WireGuard sends keepalive packets on idle connections. Lower values keep connections alive through NATs and firewalls that expire UDP mappings quickly. If unset, keepalives are sent every 25 seconds.",
+			Flag:        "wireguard-keepalive-interval",
+			Env:         "CODER_WIREGUARD_KEEPALIVE_INTERVAL",
+			Value:       &c.Wireguard.KeepaliveInterval,
+			Group:       &deploymentGroupNetworkingWireguard,
+			YAML:        "keepaliveInterval",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "WireGuard Handshake Timeout",
+			Description: "How long a client waits for a workspace to be ready for a WireGuard handshake before attempting one anyway. If unset, clients wait 5 seconds.",
+			Flag:        "wireguard-handshake-timeout",
+			Env:         "CODER_WIREGUARD_HANDSHAKE_TIMEOUT",
+			Value:       &c.Wireguard.HandshakeTimeout,
+			Group:       &deploymentGroupNetworkingWireguard,
+			YAML:        "handshakeTimeout",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
 		// TODO: support Git Auth settings.
 		// Prometheus settings
 		{
@@ -1934,6 +2418,7 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Value:       &c.OIDC.GroupMapping,
 			Group:       &deploymentGroupOIDC,
 			YAML:        "groupMapping",
+			Annotations: serpent.Annotations{}.Mark(annotationHotReload, "true"),
 		},
 		{
 			Name:        "Enable OIDC Group Auto Create",
@@ -2035,6 +2520,394 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Group: &deploymentGroupOIDC,
 			YAML:  "dangerousSkipIssuerChecks",
 		},
+		// SAML settings.
+		{
+			Name:        "SAML IdP Metadata URL",
+			Description: "URL of the identity provider's SAML metadata. Setting this or saml-idp-metadata-file enables Login with SAML.",
+			Flag:        "saml-idp-metadata-url",
+			Env:         "CODER_SAML_IDP_METADATA_URL",
+			Value:       &c.SAML.IDPMetadataURL,
+			Group:       &deploymentGroupSAML,
+			YAML:        "idpMetadataURL",
+		},
+		{
+			Name:        "SAML IdP Metadata File",
+			Description: "Path to a file containing the identity provider's SAML metadata, for identity providers whose metadata is not reachable from the Coder server.",
+			Flag:        "saml-idp-metadata-file",
+			Env:         "CODER_SAML_IDP_METADATA_FILE",
+			Value:       &c.SAML.IDPMetadataFile,
+			Group:       &deploymentGroupSAML,
+			YAML:        "idpMetadataFile",
+		},
+		{
+			Name:        "SAML Entity ID",
+			Description: "Entity ID of Coder as a SAML service provider. Defaults to the URL of the service provider metadata, /api/v2/users/saml/metadata.",
+			Flag:        "saml-entity-id",
+			Env:         "CODER_SAML_ENTITY_ID",
+			Value:       &c.SAML.EntityID,
+			Group:       &deploymentGroupSAML,
+			YAML:        "entityID",
+		},
+		{
+			Name: "SAML Cert File",
+			Description: "Pem encoded certificate of the service provider. It is published in the service provider metadata " +
+				"so the identity provider can verify signed requests and encrypt assertions.",
+			Flag:  "saml-cert-file",
+			Env:   "CODER_SAML_CERT_FILE",
+			Value: &c.SAML.CertFile,
+			Group: &deploymentGroupSAML,
+			YAML:  "certFile",
+		},
+		{
+			Name: "SAML Key File",
+			Description: "Pem encoded RSA private key of the service provider, used to sign authentication and logout requests " +
+				"and to decrypt encrypted assertions. The private key that accompanies saml-cert-file.",
+			Flag:  "saml-key-file",
+			Env:   "CODER_SAML_KEY_FILE",
+			Value: &c.SAML.KeyFile,
+			Group: &deploymentGroupSAML,
+			YAML:  "keyFile",
+		},
+		{
+			Name:        "SAML Allow Signups",
+			Description: "Whether new users can sign up with SAML.",
+			Flag:        "saml-allow-signups",
+			Env:         "CODER_SAML_ALLOW_SIGNUPS",
+			Default:     "true",
+			Value:       &c.SAML.AllowSignups,
+			Group:       &deploymentGroupSAML,
+			YAML:        "allowSignups",
+		},
+		{
+			Name:        "SAML Email Domain",
+			Description: "Email domains that clients logging in with SAML must match.",
+			Flag:        "saml-email-domain",
+			Env:         "CODER_SAML_EMAIL_DOMAIN",
+			Value:       &c.SAML.EmailDomain,
+			Group:       &deploymentGroupSAML,
+			YAML:        "emailDomain",
+		},
+		{
+			Name:        "SAML Username Attribute",
+			Description: "SAML attribute to use as the username.",
+			Flag:        "saml-username-attribute",
+			Env:         "CODER_SAML_USERNAME_ATTRIBUTE",
+			Default:     "username",
+			Value:       &c.SAML.UsernameAttribute,
+			Group:       &deploymentGroupSAML,
+			YAML:        "usernameAttribute",
+		},
+		{
+			Name:        "SAML Name Attribute",
+			Description: "SAML attribute to use as the user's display name.",
+			Flag:        "saml-name-attribute",
+			Env:         "CODER_SAML_NAME_ATTRIBUTE",
+			Default:     "name",
+			Value:       &c.SAML.NameAttribute,
+			Group:       &deploymentGroupSAML,
+			YAML:        "nameAttribute",
+		},
+		{
+			Name:        "SAML Email Attribute",
+			Description: "SAML attribute to use as the user's email address. The NameID is used when the attribute is missing and the NameID is an email address.",
+			Flag:        "saml-email-attribute",
+			Env:         "CODER_SAML_EMAIL_ATTRIBUTE",
+			Default:     "email",
+			Value:       &c.SAML.EmailAttribute,
+			Group:       &deploymentGroupSAML,
+			YAML:        "emailAttribute",
+		},
+		{
+			Name:        "SAML sign in text",
+			Description: "The text to show on the SAML sign in button.",
+			Flag:        "saml-sign-in-text",
+			Env:         "CODER_SAML_SIGN_IN_TEXT",
+			Default:     "SAML",
+			Value:       &c.SAML.SignInText,
+			Group:       &deploymentGroupSAML,
+			YAML:        "signInText",
+		},
+		{
+			Name:        "SAML icon URL",
+			Description: "URL pointing to the icon to use on the SAML login button.",
+			Flag:        "saml-icon-url",
+			Env:         "CODER_SAML_ICON_URL",
+			Value:       &c.SAML.IconURL,
+			Group:       &deploymentGroupSAML,
+			YAML:        "iconURL",
+		},
+		// LDAP settings.
+		{
+			Name:        "LDAP URL",
+			Description: "URL of the LDAP server, such as ldaps://dc.example.com. Setting this enables the periodic sync of users and groups from the directory.",
+			Flag:        "ldap-url",
+			Env:         "CODER_LDAP_URL",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LDAP.URL,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "url",
+		},
+		{
+			Name:        "LDAP Bind DN",
+			Description: "DN of the account used to search the directory. It only needs read access to the users and groups.",
+			Flag:        "ldap-bind-dn",
+			Env:         "CODER_LDAP_BIND_DN",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LDAP.BindDN,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "bindDN",
+		},
+		{
+			Name:        "LDAP Bind Password",
+			Description: "Password of the account used to search the directory.",
+			Flag:        "ldap-bind-password",
+			Env:         "CODER_LDAP_BIND_PASSWORD",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
+			Value:       &c.LDAP.BindPassword,
+			Group:       &deploymentGroupLDAP,
+		},
+		{
+			Name:        "LDAP StartTLS",
+			Description: "Upgrade ldap:// connections to TLS with StartTLS before binding.",
+			Flag:        "ldap-start-tls",
+			Env:         "CODER_LDAP_START_TLS",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LDAP.StartTLS,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "startTLS",
+		},
+		{
+			Name:        "LDAP CA File",
+			Description: "Path to a PEM encoded CA certificate used to verify the LDAP server. The system roots are used when this is not set.",
+			Flag:        "ldap-ca-file",
+			Env:         "CODER_LDAP_CA_FILE",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LDAP.CAFile,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "caFile",
+		},
+		{
+			Name:        "LDAP User Base DN",
+			Description: "DN to search for users.",
+			Flag:        "ldap-user-base-dn",
+			Env:         "CODER_LDAP_USER_BASE_DN",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LDAP.UserBaseDN,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "userBaseDN",
+		},
+		{
+			Name: "LDAP User Filter",
+			Description: "Filter for the users to sync. Users that stop matching the filter are suspended, so the default " +
+				"excludes accounts that are disabled in Active Directory.",
+			Flag:        "ldap-user-filter",
+			Env:         "CODER_LDAP_USER_FILTER",
+			Default:     "(&(objectCategory=person)(objectClass=user)(!(userAccountControl:1.2.840.113556.1.4.803:=2)))",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LDAP.UserFilter,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "userFilter",
+		},
+		{
+			Name:        "LDAP Group Base DN",
+			Description: "DN to search for groups. Groups are not synced when this is not set.",
+			Flag:        "ldap-group-base-dn",
+			Env:         "CODER_LDAP_GROUP_BASE_DN",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LDAP.GroupBaseDN,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "groupBaseDN",
+		},
+		{
+			Name:        "LDAP Group Filter",
+			Description: "Filter for the groups to sync.",
+			Flag:        "ldap-group-filter",
+			Env:         "CODER_LDAP_GROUP_FILTER",
+			Default:     "(objectClass=group)",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LDAP.GroupFilter,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "groupFilter",
+		},
+		{
+			Name:        "LDAP ID Attribute",
+			Description: "Attribute that uniquely identifies a user, even when the user is renamed or moved. Use entryUUID for OpenLDAP.",
+			Flag:        "ldap-id-attribute",
+			Env:         "CODER_LDAP_ID_ATTRIBUTE",
+			Default:     "objectGUID",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LDAP.IDAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "idAttribute",
+		},
+		{
+			Name:        "LDAP Username Attribute",
+			Description: "Attribute to use as the username of new users.",
+			Flag:        "ldap-username-attribute",
+			Env:         "CODER_LDAP_USERNAME_ATTRIBUTE",
+			Default:     "sAMAccountName",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LDAP.UsernameAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "usernameAttribute",
+		},
+		{
+			Name:        "LDAP Email Attribute",
+			Description: "Attribute to use as the email address. Existing users with the same email address are linked instead of created. Users without an email address are skipped.",
+			Flag:        "ldap-email-attribute",
+			Env:         "CODER_LDAP_EMAIL_ATTRIBUTE",
+			Default:     "mail",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LDAP.EmailAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "emailAttribute",
+		},
+		{
+			Name:        "LDAP Name Attribute",
+			Description: "Attribute to use as the user's display name.",
+			Flag:        "ldap-name-attribute",
+			Env:         "CODER_LDAP_NAME_ATTRIBUTE",
+			Default:     "displayName",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LDAP.NameAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "nameAttribute",
+		},
+		{
+			Name:        "LDAP Group Name Attribute",
+			Description: "Attribute to use as the group name.",
+			Flag:        "ldap-group-name-attribute",
+			Env:         "CODER_LDAP_GROUP_NAME_ATTRIBUTE",
+			Default:     "cn",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LDAP.GroupNameAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "groupNameAttribute",
+		},
+		{
+			Name:        "LDAP Group Member Attribute",
+			Description: "Attribute of a group that lists the DNs of its members.",
+			Flag:        "ldap-group-member-attribute",
+			Env:         "CODER_LDAP_GROUP_MEMBER_ATTRIBUTE",
+			Default:     "member",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LDAP.GroupMemberAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "groupMemberAttribute",
+		},
+		{
+			Name:        "LDAP Login Type",
+			Description: "Login type of the users created by LDAP sync. Password users set their password with the reset password flow. Accepted values are password, oidc, github, saml and none.",
+			Flag:        "ldap-login-type",
+			Env:         "CODER_LDAP_LOGIN_TYPE",
+			Default:     string(LoginTypePassword),
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LDAP.LoginType,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "loginType",
+		},
+		{
+			Name:        "LDAP Sync Interval",
+			Description: "How often users and groups are synced from the directory.",
+			Flag:        "ldap-sync-interval",
+			Env:         "CODER_LDAP_SYNC_INTERVAL",
+			Default:     (15 * time.Minute).String(),
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationFormatDuration, "true"),
+			Value:       &c.LDAP.SyncInterval,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "syncInterval",
+		},
+		// Audit streaming settings.
+		{
+			Name:        "Audit Stream Splunk HEC URL",
+			Description: "URL of a Splunk HTTP Event Collector, such as https://splunk.example.com:8088/services/collector/event. Audit logs are streamed to it when set.",
+			Flag:        "audit-stream-splunk-hec-url",
+			Env:         "CODER_AUDIT_STREAM_SPLUNK_HEC_URL",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditStreaming.SplunkHECURL,
+			Group:       &deploymentGroupAuditStreaming,
+			YAML:        "splunkHECURL",
+		},
+		{
+			Name:        "Audit Stream Splunk HEC Token",
+			Description: "Token used to authenticate with the Splunk HTTP Event Collector.",
+			Flag:        "audit-stream-splunk-hec-token",
+			Env:         "CODER_AUDIT_STREAM_SPLUNK_HEC_TOKEN",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
+			Value:       &c.AuditStreaming.SplunkHECToken,
+			Group:       &deploymentGroupAuditStreaming,
+		},
+		{
+			Name:        "Audit Stream Datadog API Key",
+			Description: "Datadog API key. Audit logs are streamed to Datadog Logs when set.",
+			Flag:        "audit-stream-datadog-api-key",
+			Env:         "CODER_AUDIT_STREAM_DATADOG_API_KEY",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
+			Value:       &c.AuditStreaming.DatadogAPIKey,
+			Group:       &deploymentGroupAuditStreaming,
+		},
+		{
+			Name:        "Audit Stream Datadog Site",
+			Description: "Datadog site that audit logs are sent to, such as datadoghq.eu.",
+			Flag:        "audit-stream-datadog-site",
+			Env:         "CODER_AUDIT_STREAM_DATADOG_SITE",
+			Default:     "datadoghq.com",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditStreaming.DatadogSite,
+			Group:       &deploymentGroupAuditStreaming,
+			YAML:        "datadogSite",
+		},
+		{
+			Name:        "Audit Stream Webhook URL",
+			Description: "HTTPS endpoint that batches of audit logs are POSTed to as a JSON array.",
+			Flag:        "audit-stream-webhook-url",
+			Env:         "CODER_AUDIT_STREAM_WEBHOOK_URL",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditStreaming.WebhookURL,
+			Group:       &deploymentGroupAuditStreaming,
+			YAML:        "webhookURL",
+		},
+		{
+			Name:        "Audit Stream Webhook Headers",
+			Description: "Headers sent with every webhook request, in the form \"Name: value\". Use this to pass an authorization header.",
+			Flag:        "audit-stream-webhook-headers",
+			Env:         "CODER_AUDIT_STREAM_WEBHOOK_HEADERS",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
+			Value:       &c.AuditStreaming.WebhookHeaders,
+			Group:       &deploymentGroupAuditStreaming,
+		},
+		{
+			Name:        "Audit Stream Syslog Address",
+			Description: "Address of a syslog server that audit logs are sent to in RFC 5424 format, such as udp://syslog.example.com:514. The udp, tcp and tls schemes are supported.",
+			Flag:        "audit-stream-syslog-address",
+			Env:         "CODER_AUDIT_STREAM_SYSLOG_ADDRESS",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditStreaming.SyslogAddress,
+			Group:       &deploymentGroupAuditStreaming,
+			YAML:        "syslogAddress",
+		},
+		{
+			Name:        "Audit Stream Buffer Size",
+			Description: "Number of audit logs buffered for each destination. Audit logs are dropped when a destination falls this far behind.",
+			Flag:        "audit-stream-buffer-size",
+			Env:         "CODER_AUDIT_STREAM_BUFFER_SIZE",
+			Default:     "10000",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditStreaming.BufferSize,
+			Group:       &deploymentGroupAuditStreaming,
+			YAML:        "bufferSize",
+		},
+		{
+			Name:        "Audit Stream Max Attempts",
+			Description: "Number of times delivery of a batch of audit logs is attempted before it is dropped.",
+			Flag:        "audit-stream-max-attempts",
+			Env:         "CODER_AUDIT_STREAM_MAX_ATTEMPTS",
+			Default:     "5",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditStreaming.MaxAttempts,
+			Group:       &deploymentGroupAuditStreaming,
+			YAML:        "maxAttempts",
+		},
 		// Telemetry settings
 		telemetryEnable,
 		{
@@ -2052,15 +2925,23 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 		},
 		{
 			Name:        "Telemetry URL",
-			Description: "URL to send telemetry.",
+			Description: "URL to send telemetry to. Set this to the URL of an internal collector to receive telemetry for your own analytics instead of sending it to Coder.",
 			Flag:        "telemetry-url",
 			Env:         "CODER_TELEMETRY_URL",
-			Hidden:      true,
 			Default:     "https://telemetry.coder.com",
 			Value:       &c.Telemetry.URL,
 			Group:       &deploymentGroupTelemetry,
 			YAML:        "url",
 		},
+		{
+			Name:        "Telemetry Categories",
+			Description: fmt.Sprintf("The comma-separated categories of data that are reported by telemetry. All categories are reported if empty. Accepted values are %s.", strings.Join(slice.ToStrings(TelemetryCategories()), ", ")),
+			Flag:        "telemetry-categories",
+			Env:         "CODER_TELEMETRY_CATEGORIES",
+			Value:       &c.Telemetry.Categories,
+			Group:       &deploymentGroupTelemetry,
+			YAML:        "categories",
+		},
 		// Trace settings
 		{
 			Name:        "Trace Enable",
@@ -2177,6 +3058,87 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			YAML:        "forceCancelInterval",
 			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
 		},
+		{
+			Name:        "Max Builds Per User",
+			Description: "The maximum number of workspace builds of a user's workspaces that can run at once. Further builds are queued and started as running builds complete. Set to 0 to not limit builds.",
+			Flag:        "provisioner-max-builds-per-user",
+			Env:         "CODER_PROVISIONER_MAX_BUILDS_PER_USER",
+			Default:     "0",
+			Value:       &c.Provisioner.MaxBuildsPerUser,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "maxBuildsPerUser",
+		},
+		{
+			Name:        "Max Builds Per Template",
+			Description: "The maximum number of workspace builds of a template's workspaces that can run at once. Further builds are queued and started as running builds complete. Set to 0 to not limit builds.",
+			Flag:        "provisioner-max-builds-per-template",
+			Env:         "CODER_PROVISIONER_MAX_BUILDS_PER_TEMPLATE",
+			Default:     "0",
+			Value:       &c.Provisioner.MaxBuildsPerTemplate,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "maxBuildsPerTemplate",
+		},
+		{
+			Name:        "Max Concurrent Jobs",
+			Description: "The maximum number of provisioner jobs that can run at once across all organizations. Further jobs are queued and started as running jobs complete. Set to 0 to not limit jobs.",
+			Flag:        "provisioner-max-concurrent-jobs",
+			Env:         "CODER_PROVISIONER_MAX_CONCURRENT_JOBS",
+			Default:     "0",
+			Value:       &c.Provisioner.MaxConcurrentJobs,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "maxConcurrentJobs",
+		},
+		{
+			Name:        "Max Jobs Per Organization",
+			Description: "The maximum number of provisioner jobs of an organization that can run at once. Organizations can override it. Further jobs are queued and started as running jobs complete. Set to 0 to not limit jobs.",
+			Flag:        "provisioner-max-jobs-per-organization",
+			Env:         "CODER_PROVISIONER_MAX_JOBS_PER_ORGANIZATION",
+			Default:     "0",
+			Value:       &c.Provisioner.MaxJobsPerOrganization,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "maxJobsPerOrganization",
+		},
+		{
+			Name:        "Reserved Jobs Per Organization",
+			Description: "The number of provisioner jobs out of --provisioner-max-concurrent-jobs that are reserved for each organization, so that other organizations can't use up the shared capacity. Organizations can override it.",
+			Flag:        "provisioner-reserved-jobs-per-organization",
+			Env:         "CODER_PROVISIONER_RESERVED_JOBS_PER_ORGANIZATION",
+			Default:     "0",
+			Value:       &c.Provisioner.ReservedJobsPerOrganization,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "reservedJobsPerOrganization",
+		},
+		{
+			Name:        "Orphaned Job Timeout",
+			Description: "Time since the last heartbeat of the provisioner daemon running a job before the job is considered orphaned, for example because the daemon crashed. Orphaned jobs are handled according to --provisioner-orphaned-job-action. Set to 0 to disable the detection of orphaned jobs.",
+			Flag:        "provisioner-orphaned-job-timeout",
+			Env:         "CODER_PROVISIONER_ORPHANED_JOB_TIMEOUT",
+			Default:     (3 * time.Minute).String(),
+			Value:       &c.Provisioner.OrphanedJobTimeout,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "orphanedJobTimeout",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Orphaned Job Action",
+			Description: "What to do with jobs whose provisioner daemon stopped sending heartbeats. \"fail\" fails the job with an error that explains why, \"requeue\" returns the job to the queue so that another provisioner daemon runs it.",
+			Flag:        "provisioner-orphaned-job-action",
+			Env:         "CODER_PROVISIONER_ORPHANED_JOB_ACTION",
+			Default:     OrphanedJobActionFail,
+			Value:       serpent.EnumOf(&c.Provisioner.OrphanedJobAction, OrphanedJobActionFail, OrphanedJobActionRequeue),
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "orphanedJobAction",
+		},
+		{
+			Name:        "Orphaned Job Max Requeues",
+			Description: "The number of times an orphaned job is requeued before it is failed. Only used when --provisioner-orphaned-job-action is \"requeue\".",
+			Flag:        "provisioner-orphaned-job-max-requeues",
+			Env:         "CODER_PROVISIONER_ORPHANED_JOB_MAX_REQUEUES",
+			Default:     "3",
+			Value:       &c.Provisioner.OrphanedJobMaxRequeues,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "orphanedJobMaxRequeues",
+		},
 		{
 			Name:        "Provisioner Daemon Pre-shared Key (PSK)",
 			Description: "Pre-shared key to authenticate external provisioner daemons to Coder server.",
@@ -2186,6 +3148,15 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Group:       &deploymentGroupProvisioning,
 			Annotations: serpent.Annotations{}.Mark(annotationSecretKey, "true"),
 		},
+		{
+			Name:        "Template Policy Files",
+			Description: "Paths to Rego policy files, or directories containing them, which every imported template version is evaluated against. Template versions which violate a policy fail to import with the messages of the `data.coder.templates.deny` rule.",
+			Flag:        "template-policy-files",
+			Env:         "CODER_TEMPLATE_POLICY_FILES",
+			Value:       &c.Provisioner.TemplatePolicyFiles,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "templatePolicyFiles",
+		},
 		// RateLimit settings
 		{
 			Name:        "Disable All Rate Limits",
@@ -2206,8 +3177,9 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Flag:        "api-rate-limit",
 			Default:     "512",
 			Value:       &c.RateLimit.API,
+			YAML:        "apiRateLimit",
 			Hidden:      true,
-			Annotations: serpent.Annotations{}.Mark(annotationExternalProxies, "true"),
+			Annotations: serpent.Annotations{}.Mark(annotationExternalProxies, "true").Mark(annotationHotReload, "true"),
 		},
 		// Logging settings
 		{
@@ -2257,6 +3229,16 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			YAML:        "stackdriverPath",
 			Annotations: serpent.Annotations{}.Mark(annotationExternalProxies, "true"),
 		},
+		{
+			Name:        "Log Sinks",
+			Description: "A JSON list of additional log destinations, each with a type (file, syslog, journald or otlp), a minimum level and type-specific settings such as the path and rotation of files, or the address of a syslog server or OpenTelemetry collector.",
+			Flag:        "log-sinks",
+			Env:         "CODER_LOGGING_SINKS",
+			Value:       &c.Logging.Sinks,
+			Group:       &deploymentGroupIntrospectionLogging,
+			YAML:        "sinks",
+			Annotations: serpent.Annotations{}.Mark(annotationExternalProxies, "true"),
+		},
 		{
 			Name:        "Enable Terraform debug mode",
 			Description: "Allow administrators to enable Terraform debug output.",
@@ -2492,6 +3474,16 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Value:       &c.SSHKeygenAlgorithm,
 			YAML:        "sshKeygenAlgorithm",
 		},
+		{
+			Name:        "FIPS Mode",
+			Description: "Only use FIPS 140-3 approved cryptography. The server refuses to start if a setting requires an algorithm that is not approved, and TLS is restricted to approved cipher suites. Always enabled in FIPS builds.",
+			Flag:        "fips",
+			Env:         "CODER_FIPS",
+			Default:     "false",
+			Value:       &c.FIPS,
+			YAML:        "fips",
+			Annotations: serpent.Annotations{}.Mark(annotationExternalProxies, "true"),
+		},
 		{
 			Name:        "Metrics Cache Refresh Interval",
 			Description: "How frequently metrics are refreshed.",
@@ -2522,6 +3514,26 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Value:       &c.AgentFallbackTroubleshootingURL,
 			YAML:        "agentFallbackTroubleshootingURL",
 		},
+		{
+			Name:        "Agent Token Rotation Interval",
+			Description: "How often workspace agents exchange their token for a fresh one. Set to 0 to keep agent tokens for the life of a build.",
+			Flag:        "agent-token-rotation-interval",
+			Env:         "CODER_AGENT_TOKEN_ROTATION_INTERVAL",
+			Default:     "0",
+			Value:       &c.AgentTokenRotationInterval,
+			YAML:        "agentTokenRotationInterval",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Agent Token Rotation Grace Period",
+			Description: "How long a rotated agent token is still accepted, so requests in flight and processes that read the old token can finish.",
+			Flag:        "agent-token-rotation-grace-period",
+			Env:         "CODER_AGENT_TOKEN_ROTATION_GRACE_PERIOD",
+			Default:     (10 * time.Minute).String(),
+			Value:       &c.AgentTokenRotationGracePeriod,
+			YAML:        "agentTokenRotationGracePeriod",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
 		{
 			Name:        "Browser Only",
 			Description: "Whether Coder only allows connections to workspaces via the browser.",
@@ -2532,6 +3544,18 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Group:       &deploymentGroupNetworking,
 			YAML:        "browserOnly",
 		},
+		{
+			Name: "Tailnet Coordinator Sharding",
+			Description: "Partition workspace agents across replicas using consistent hashing, and redirect agents and clients to the replica that owns the agent. " +
+				"Connections between peers on the same replica are coordinated in memory instead of through the database. " +
+				"Requires high availability, and each replica's --derp-server-relay-url must be reachable by workspaces and clients.",
+			Flag:        "tailnet-coordinator-sharding",
+			Env:         "CODER_TAILNET_COORDINATOR_SHARDING",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.TailnetCoordinatorSharding,
+			Group:       &deploymentGroupNetworking,
+			YAML:        "tailnetCoordinatorSharding",
+		},
 		{
 			Name:        "SCIM API Key",
 			Description: "Enables SCIM and sets the authentication header for the built-in SCIM server. New users are automatically created with OIDC authentication.",
@@ -2540,6 +3564,24 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
 			Value:       &c.SCIMAPIKey,
 		},
+		{
+			Name:        "SCIM Groups Flatten Nested",
+			Description: "Add the members of nested SCIM groups to every group that contains them. Without this, groups pushed as members of another group are ignored.",
+			Flag:        "scim-groups-flatten-nested",
+			Env:         "CODER_SCIM_GROUPS_FLATTEN_NESTED",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.SCIMGroupsFlattenNested,
+			YAML:        "scimGroupsFlattenNested",
+		},
+		{
+			Name:        "SCIM Groups Dry Run",
+			Description: "Log the group and membership changes SCIM group requests would make without applying them. Use this to review how the identity provider's groups map to Coder before enforcing them.",
+			Flag:        "scim-groups-dry-run",
+			Env:         "CODER_SCIM_GROUPS_DRY_RUN",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.SCIMGroupsDryRun,
+			YAML:        "scimGroupsDryRun",
+		},
 		{
 			Name:        "External Token Encryption Keys",
 			Description: "Encrypt OIDC and Git authentication tokens with AES-256-GCM in the database. The value must be a comma-separated list of base64-encoded keys. Each key, when base64-decoded, must be exactly 32 bytes in length. The first key will be used to encrypt new values. Subsequent keys will be used as a fallback when decrypting. During normal operation it is recommended to only set one key unless you are in the process of rotating keys with the `coder server dbcrypt rotate` command.",
@@ -2548,6 +3590,14 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
 			Value:       &c.ExternalTokenEncryptionKeys,
 		},
+		{
+			Name:        "Review Workspaces Webhook Secret",
+			Description: "The secret used to verify GitHub and GitLab webhooks that delete the review workspaces of deleted branches. Branch deletion webhooks are rejected if this is not set.",
+			Flag:        "review-workspaces-webhook-secret",
+			Env:         "CODER_REVIEW_WORKSPACES_WEBHOOK_SECRET",
+			Annotations: serpent.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.ReviewWorkspacesWebhookSecret,
+		},
 		{
 			Name:        "Disable Path Apps",
 			Description: "Disable workspace apps that are not served from subdomains. Path-based apps can make requests to the Coder API and pose a security risk when the workspace serves malicious JavaScript. This is recommended for security purposes if a --wildcard-access-url is configured.",
@@ -2589,6 +3639,25 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Group: &deploymentGroupNetworkingHTTP,
 			YAML:  "disableSessionExpiryRefresh",
 		},
+		{
+			Name:        "Max Sessions Per User",
+			Description: "The maximum number of active login sessions per user. When a user logs in beyond it, their least recently used sessions are revoked. API tokens are not affected. Set to 0 to allow unlimited sessions.",
+			Flag:        "max-sessions-per-user",
+			Env:         "CODER_MAX_SESSIONS_PER_USER",
+			Default:     "0",
+			Value:       &c.Sessions.MaximumSessionsPerUser,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "maxSessionsPerUser",
+		},
+		{
+			Name:        "Network Zones",
+			Description: "A JSON list of network zones, each with a name, the roles and group_ids it applies to, and the cidrs they may make API requests from. Requests from a member of a zone that come from outside its cidrs are rejected and audited.",
+			Flag:        "network-zones",
+			Env:         "CODER_NETWORK_ZONES",
+			Value:       &c.NetworkZones,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "networkZones",
+		},
 		{
 			Name:        "Disable Password Authentication",
 			Description: "Disable password authentication. This is recommended for security purposes in production deployments that rely on an identity provider. Any user with the owner role will be able to sign in with their password regardless of this setting to avoid potential lock out. If you are locked out of your account, you can use the `coder server create-admin` command to create a new admin user directly in the database.",
@@ -2653,6 +3722,24 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Value:       &c.CLIUpgradeMessage,
 			Hidden:      false,
 		},
+		{
+			Name:        "Minimum Agent Version",
+			Description: "Reject connections from workspace agents older than this version, e.g. 2.20.0. Outdated agents receive an error that asks to restart the workspace to update the agent.",
+			Flag:        "min-agent-version",
+			Env:         "CODER_MIN_AGENT_VERSION",
+			YAML:        "minAgentVersion",
+			Group:       &deploymentGroupClient,
+			Value:       &c.MinAgentVersion,
+		},
+		{
+			Name:        "Minimum CLI Version",
+			Description: "Reject API requests from CLIs older than this version, e.g. 2.20.0. Outdated CLIs receive an error with the CLI upgrade message.",
+			Flag:        "min-cli-version",
+			Env:         "CODER_MIN_CLI_VERSION",
+			YAML:        "minCLIVersion",
+			Group:       &deploymentGroupClient,
+			Value:       &c.MinCLIVersion,
+		},
 		{
 			Name: "Write Config",
 			Description: `
@@ -2663,6 +3750,14 @@ Write out the current server config as YAML to stdout.`,
 			Value:       &c.WriteConfig,
 			Annotations: serpent.Annotations{}.Mark(annotationExternalProxies, "true"),
 		},
+		{
+			Name:        "Settings File",
+			Description: "Path to a YAML file declaring runtime-editable deployment settings, such as appearance and notification settings. The settings are applied at startup, and differences between the file and the current settings are reported as drift.",
+			Flag:        "settings-file",
+			Env:         "CODER_SETTINGS_FILE",
+			Group:       &deploymentGroupConfig,
+			Value:       &c.SettingsFile,
+		},
 		{
 			Name:        "Support Links",
 			Description: "Support links to display in the top right drop down menu.",
@@ -2702,6 +3797,28 @@ Write out the current server config as YAML to stdout.`,
 			YAML:        "proxyHealthInterval",
 			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
 		},
+		{
+			Name:        "Proxy Token Rotation Interval",
+			Description: "How often workspace proxies exchange their token for a fresh one. Proxies only rotate their token when it is read from a file. Set to 0 to keep proxy tokens until they are regenerated.",
+			Flag:        "proxy-token-rotation-interval",
+			Env:         "CODER_PROXY_TOKEN_ROTATION_INTERVAL",
+			Default:     "0",
+			Value:       &c.ProxyTokenRotationInterval,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "proxyTokenRotationInterval",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Proxy Token Rotation Grace Period",
+			Description: "How long a rotated workspace proxy token is still accepted, so replicas of the proxy that share the token can pick up the new one.",
+			Flag:        "proxy-token-rotation-grace-period",
+			Env:         "CODER_PROXY_TOKEN_ROTATION_GRACE_PERIOD",
+			Default:     time.Hour.String(),
+			Value:       &c.ProxyTokenRotationGracePeriod,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "proxyTokenRotationGracePeriod",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
 		{
 			Name:        "Default Quiet Hours Schedule",
 			Description: "The default daily cron schedule applied to users that haven't set a custom quiet hours schedule themselves. The quiet hours schedule determines when workspaces will be force stopped due to the template's autostop requirement, and will round the max deadline up to be within the user's quiet hours window (or default). The format is the same as the standard cron format, but the day-of-month, month and day-of-week must be *. Only one hour and minute can be specified (ranges or comma separated values are not supported).",
@@ -2732,6 +3849,16 @@ Write out the current server config as YAML to stdout.`,
 			Group:       &deploymentGroupClient,
 			YAML:        "webTerminalRenderer",
 		},
+		{
+			Name:        "Web Desktop Clipboard",
+			Description: "Which way the clipboard is synchronized in web desktop (VNC) sessions. Valid values are 'both', 'to-workspace', 'from-workspace', or 'none'.",
+			Flag:        "web-desktop-clipboard",
+			Env:         "CODER_WEB_DESKTOP_CLIPBOARD",
+			Default:     string(DesktopClipboardPolicyBoth),
+			Value:       serpent.EnumOf(&c.WebDesktopClipboard, DesktopClipboardPolicies...),
+			Group:       &deploymentGroupClient,
+			YAML:        "webDesktopClipboard",
+		},
 		{
 			Name:        "Allow Workspace Renames",
 			Description: "DEPRECATED: Allow users to rename their workspaces. Use only for temporary compatibility reasons, this will be removed in a future release.",
@@ -2741,6 +3868,76 @@ Write out the current server config as YAML to stdout.`,
 			Value:       &c.AllowWorkspaceRenames,
 			YAML:        "allowWorkspaceRenames",
 		},
+		{
+			Name:        "Max Workspace Schedule Pause",
+			Description: "The maximum duration users can pause the autostop, dormancy and auto-delete schedules of a workspace for, e.g. while on leave. Set to 0 to disallow pausing.",
+			Flag:        "max-workspace-schedule-pause",
+			Env:         "CODER_MAX_WORKSPACE_SCHEDULE_PAUSE",
+			Default:     (30 * 24 * time.Hour).String(),
+			Value:       &c.MaxWorkspaceSchedulePause,
+			YAML:        "maxWorkspaceSchedulePause",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Autostart Holidays",
+			Description: "Dates formatted as YYYY-MM-DD on which workspaces are not autostarted, in the time zone of each workspace's autostart schedule. Applies to all templates in addition to their own holidays.",
+			Flag:        "autostart-holidays",
+			Env:         "CODER_AUTOSTART_HOLIDAYS",
+			Value:       &c.AutostartHolidays,
+			YAML:        "autostartHolidays",
+		},
+		{
+			Name:        "Autostart Holiday Calendar URL",
+			Description: "URL of an iCalendar feed, such as a public holiday calendar, whose events are treated as autostart holidays for all templates. The feed is refreshed hourly.",
+			Flag:        "autostart-holiday-calendar-url",
+			Env:         "CODER_AUTOSTART_HOLIDAY_CALENDAR_URL",
+			Value:       &c.AutostartHolidayCalendarURL,
+			YAML:        "autostartHolidayCalendarURL",
+		},
+		{
+			Name:        "Workspace Activity CPU Threshold",
+			Description: "The number of CPU cores a workspace must be using for it to be considered active, which delays its autostop even without open connections. Set to 0 to disable.",
+			Flag:        "workspace-activity-cpu-threshold",
+			Env:         "CODER_WORKSPACE_ACTIVITY_CPU_THRESHOLD",
+			Default:     "0",
+			Value:       &c.WorkspaceActivityCPUThreshold,
+			YAML:        "workspaceActivityCPUThreshold",
+		},
+		{
+			Name:        "Workspace Activity Network Threshold",
+			Description: "The number of bytes per second a workspace must be sending and receiving for it to be considered active, which delays its autostop even without open connections. Set to 0 to disable.",
+			Flag:        "workspace-activity-network-threshold",
+			Env:         "CODER_WORKSPACE_ACTIVITY_NETWORK_THRESHOLD",
+			Default:     "0",
+			Value:       &c.WorkspaceActivityNetworkThreshold,
+			YAML:        "workspaceActivityNetworkThreshold",
+		},
+		{
+			Name:        "Dormant Workspace Archive URL",
+			Description: "The object storage to archive the dormant archive paths of templates to before dormant workspaces are auto-deleted, e.g. file:///var/lib/coder/archives or s3://bucket/prefix?region=us-east-1. S3 credentials are read from the default AWS credential chain, and a custom endpoint can be set with the endpoint query parameter. Archiving is disabled if unset.",
+			Flag:        "dormant-workspace-archive-url",
+			Env:         "CODER_DORMANT_WORKSPACE_ARCHIVE_URL",
+			Value:       &c.DormantWorkspaceArchiveURL,
+			YAML:        "dormantWorkspaceArchiveURL",
+		},
+		{
+			Name:        "Dormant Workspace Archive Retention",
+			Description: "How long archives of deleted dormant workspaces are kept before they are purged.",
+			Flag:        "dormant-workspace-archive-retention",
+			Env:         "CODER_DORMANT_WORKSPACE_ARCHIVE_RETENTION",
+			Default:     (30 * 24 * time.Hour).String(),
+			Value:       &c.DormantWorkspaceArchiveRetention,
+			YAML:        "dormantWorkspaceArchiveRetention",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "File Storage URL",
+			Description: "The object storage to keep the contents of uploaded files, such as template source archives, in instead of the database, e.g. s3://bucket/prefix, gs://bucket/prefix or azblob://account/container/prefix. Files with the same contents are stored once, and files that are no longer used are deleted. Files uploaded before this is set stay in the database.",
+			Flag:        "file-storage-url",
+			Env:         "CODER_FILE_STORAGE_URL",
+			Value:       &c.FileStorageURL,
+			YAML:        "fileStorageURL",
+		},
 		// Healthcheck Options
 		{
 			Name:        "Health Check Refresh",
@@ -2764,6 +3961,80 @@ Write out the current server config as YAML to stdout.`,
 			YAML:        "thresholdDatabase",
 			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
 		},
+		// Alerting Options
+		{
+			Name:        "Alerting: PagerDuty Routing Key",
+			Description: "The integration key of a PagerDuty service using the Events API v2. When set, deployment health alerts are sent to PagerDuty.",
+			Flag:        "alerting-pagerduty-routing-key",
+			Env:         "CODER_ALERTING_PAGERDUTY_ROUTING_KEY",
+			Value:       &c.Alerting.PagerDutyRoutingKey,
+			Group:       &deploymentGroupIntrospectionAlerting,
+			Annotations: serpent.Annotations{}.Mark(annotationSecretKey, "true"),
+		},
+		{
+			Name:        "Alerting: Opsgenie API Key",
+			Description: "The key of an Opsgenie API integration. When set, deployment health alerts are sent to Opsgenie.",
+			Flag:        "alerting-opsgenie-api-key",
+			Env:         "CODER_ALERTING_OPSGENIE_API_KEY",
+			Value:       &c.Alerting.OpsgenieAPIKey,
+			Group:       &deploymentGroupIntrospectionAlerting,
+			Annotations: serpent.Annotations{}.Mark(annotationSecretKey, "true"),
+		},
+		{
+			Name:        "Alerting: Opsgenie API URL",
+			Description: "The base URL of the Opsgenie API. Use https://api.eu.opsgenie.com for accounts hosted in the EU.",
+			Flag:        "alerting-opsgenie-api-url",
+			Env:         "CODER_ALERTING_OPSGENIE_API_URL",
+			Default:     "https://api.opsgenie.com",
+			Value:       &c.Alerting.OpsgenieAPIURL,
+			Group:       &deploymentGroupIntrospectionAlerting,
+			YAML:        "opsgenieAPIURL",
+		},
+		{
+			Name:        "Alerting: Interval",
+			Description: "How often the deployment health is evaluated for alerts.",
+			Flag:        "alerting-interval",
+			Env:         "CODER_ALERTING_INTERVAL",
+			Default:     time.Minute.String(),
+			Value:       &c.Alerting.Interval,
+			Group:       &deploymentGroupIntrospectionAlerting,
+			YAML:        "interval",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Alerting: Database Latency Threshold",
+			Description: "An alert is fired when the latency of the database exceeds this threshold.",
+			Flag:        "alerting-database-latency-threshold",
+			Env:         "CODER_ALERTING_DATABASE_LATENCY_THRESHOLD",
+			Default:     (100 * time.Millisecond).String(),
+			Value:       &c.Alerting.DatabaseLatencyThreshold,
+			Group:       &deploymentGroupIntrospectionAlerting,
+			YAML:        "databaseLatencyThreshold",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Alerting: Provisioner Queue Threshold",
+			Description: "An alert is fired when at least this many provisioner jobs are waiting for a provisioner daemon. Set to 0 to disable.",
+			Flag:        "alerting-provisioner-queue-threshold",
+			Env:         "CODER_ALERTING_PROVISIONER_QUEUE_THRESHOLD",
+			Default:     "20",
+			Value:       &c.Alerting.ProvisionerQueueThreshold,
+			Group:       &deploymentGroupIntrospectionAlerting,
+			YAML:        "provisionerQueueThreshold",
+		},
+		{
+			Name:        "Alerting: License Expiry Threshold",
+			Description: "An alert is fired when a license expires within this duration.",
+			Flag:        "alerting-license-expiry-threshold",
+			Env:         "CODER_ALERTING_LICENSE_EXPIRY_THRESHOLD",
+			Default:     (30 * 24 * time.Hour).String(),
+			Value:       &c.Alerting.LicenseExpiryThreshold,
+			Group:       &deploymentGroupIntrospectionAlerting,
+			YAML:        "licenseExpiryThreshold",
+			Annotations: serpent.Annotations{}.
+				Mark(annotationEnterpriseKey, "true").
+				Mark(annotationFormatDuration, "true"),
+		},
 		// Email options
 		emailFrom,
 		emailSmarthost,
@@ -2782,7 +4053,7 @@ Write out the current server config as YAML to stdout.`,
 		// Notifications Options
 		{
 			Name:        "Notifications: Method",
-			Description: "Which delivery method to use (available options: 'smtp', 'webhook').",
+			Description: "Which delivery method to use (available options: 'smtp', 'webhook', 'slack', 'teams').",
 			Flag:        "notifications-method",
 			Env:         "CODER_NOTIFICATIONS_METHOD",
 			Value:       &c.Notifications.Method,
@@ -2801,6 +4072,17 @@ Write out the current server config as YAML to stdout.`,
 			YAML:        "dispatchTimeout",
 			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
 		},
+		{
+			Name:        "Notifications: Quiet Hours Duration",
+			Description: "How long each user's quiet hours window lasts, starting from their quiet hours schedule. Notifications which are not high priority are held back until the window ends. Set to 0 to disable quiet hours routing.",
+			Flag:        "notifications-quiet-hours-duration",
+			Env:         "CODER_NOTIFICATIONS_QUIET_HOURS_DURATION",
+			Value:       &c.Notifications.QuietHoursDuration,
+			Default:     "0s",
+			Group:       &deploymentGroupNotifications,
+			YAML:        "quietHoursDuration",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
 		{
 			Name:        "Notifications: Email: From Address",
 			Description: "The sender's address to use.",
@@ -2950,6 +4232,34 @@ Write out the current server config as YAML to stdout.`,
 			Group:       &deploymentGroupNotificationsWebhook,
 			YAML:        "endpoint",
 		},
+		{
+			Name:        "Notifications: Slack: Bot Token",
+			Description: "The bot user OAuth token of the Slack app which sends notifications as direct messages. The app requires the chat:write, users:read and users:read.email scopes.",
+			Flag:        "notifications-slack-bot-token",
+			Env:         "CODER_NOTIFICATIONS_SLACK_BOT_TOKEN",
+			Value:       &c.Notifications.Slack.BotToken,
+			Group:       &deploymentGroupNotificationsSlack,
+			Annotations: serpent.Annotations{}.Mark(annotationSecretKey, "true"),
+		},
+		{
+			Name:        "Notifications: Slack: API URL",
+			Description: "The base URL of the Slack Web API. Only change this when using a different Slack environment, such as GovSlack.",
+			Flag:        "notifications-slack-api-url",
+			Env:         "CODER_NOTIFICATIONS_SLACK_API_URL",
+			Value:       &c.Notifications.Slack.APIURL,
+			Default:     "https://slack.com/api",
+			Group:       &deploymentGroupNotificationsSlack,
+			YAML:        "apiURL",
+		},
+		{
+			Name:        "Notifications: Microsoft Teams: Endpoint",
+			Description: "The incoming webhook URL of a Teams channel or workflow to which Adaptive Card notifications are sent.",
+			Flag:        "notifications-teams-endpoint",
+			Env:         "CODER_NOTIFICATIONS_TEAMS_ENDPOINT",
+			Value:       &c.Notifications.Teams.Endpoint,
+			Group:       &deploymentGroupNotificationsTeams,
+			YAML:        "endpoint",
+		},
 		{
 			Name:        "Notifications: Inbox: Enabled",
 			Description: "Enable Coder Inbox.",
@@ -3098,6 +4408,210 @@ Write out the current server config as YAML to stdout.`,
 			YAML:        "failure_hard_limit",
 			Hidden:      true,
 		},
+		// Vault Options
+		{
+			Name:        "Vault: Address",
+			Description: "The address of the HashiCorp Vault server. When set, workspaces can request Vault tokens scoped to their owner.",
+			Flag:        "vault-address",
+			Env:         "CODER_VAULT_ADDRESS",
+			Value:       &c.Vault.Address,
+			Group:       &deploymentGroupVault,
+			YAML:        "address",
+		},
+		{
+			Name:        "Vault: Namespace",
+			Description: "The Vault Enterprise namespace to send requests to.",
+			Flag:        "vault-namespace",
+			Env:         "CODER_VAULT_NAMESPACE",
+			Value:       &c.Vault.Namespace,
+			Group:       &deploymentGroupVault,
+			YAML:        "namespace",
+		},
+		{
+			Name:        "Vault: Auth Method",
+			Description: "The method Coder uses to authenticate to Vault. The token method is intended for development only.",
+			Flag:        "vault-auth-method",
+			Env:         "CODER_VAULT_AUTH_METHOD",
+			Default:     "approle",
+			Value:       serpent.EnumOf(&c.Vault.AuthMethod, "approle", "kubernetes", "token"),
+			Group:       &deploymentGroupVault,
+			YAML:        "authMethod",
+		},
+		{
+			Name:        "Vault: Auth Mount Path",
+			Description: "The path the Vault auth method is mounted at. Defaults to the name of the auth method.",
+			Flag:        "vault-auth-mount-path",
+			Env:         "CODER_VAULT_AUTH_MOUNT_PATH",
+			Value:       &c.Vault.AuthMountPath,
+			Group:       &deploymentGroupVault,
+			YAML:        "authMountPath",
+		},
+		{
+			Name:        "Vault: Token",
+			Description: "The Vault token used by the token auth method.",
+			Flag:        "vault-token",
+			Env:         "CODER_VAULT_TOKEN",
+			Value:       &c.Vault.Token,
+			Group:       &deploymentGroupVault,
+			Annotations: serpent.Annotations{}.Mark(annotationSecretKey, "true"),
+		},
+		{
+			Name:        "Vault: AppRole Role ID",
+			Description: "The role ID used by the approle auth method.",
+			Flag:        "vault-approle-role-id",
+			Env:         "CODER_VAULT_APPROLE_ROLE_ID",
+			Value:       &c.Vault.AppRoleRoleID,
+			Group:       &deploymentGroupVault,
+			YAML:        "approleRoleID",
+		},
+		{
+			Name:        "Vault: AppRole Secret ID",
+			Description: "The secret ID used by the approle auth method.",
+			Flag:        "vault-approle-secret-id",
+			Env:         "CODER_VAULT_APPROLE_SECRET_ID",
+			Value:       &c.Vault.AppRoleSecretID,
+			Group:       &deploymentGroupVault,
+			Annotations: serpent.Annotations{}.Mark(annotationSecretKey, "true"),
+		},
+		{
+			Name:        "Vault: Kubernetes Role",
+			Description: "The Vault role used by the kubernetes auth method.",
+			Flag:        "vault-kubernetes-role",
+			Env:         "CODER_VAULT_KUBERNETES_ROLE",
+			Value:       &c.Vault.KubernetesRole,
+			Group:       &deploymentGroupVault,
+			YAML:        "kubernetesRole",
+		},
+		{
+			Name:        "Vault: Kubernetes Token Path",
+			Description: "The path of the service account token used by the kubernetes auth method.",
+			Flag:        "vault-kubernetes-token-path",
+			Env:         "CODER_VAULT_KUBERNETES_TOKEN_PATH",
+			Default:     "/var/run/secrets/kubernetes.io/serviceaccount/token",
+			Value:       &c.Vault.KubernetesTokenPath,
+			Group:       &deploymentGroupVault,
+			YAML:        "kubernetesTokenPath",
+		},
+		{
+			Name:        "Vault: Token Role",
+			Description: "The Vault token role that workspace owner tokens are created from. The role must allow the policies in --vault-token-policies.",
+			Flag:        "vault-token-role",
+			Env:         "CODER_VAULT_TOKEN_ROLE",
+			Default:     "coder",
+			Value:       &c.Vault.TokenRole,
+			Group:       &deploymentGroupVault,
+			YAML:        "tokenRole",
+		},
+		{
+			Name:        "Vault: Token Policies",
+			Description: "The Vault policies attached to workspace owner tokens. Policies are Go templates rendered with the owner's .Username and .ID.",
+			Flag:        "vault-token-policies",
+			Env:         "CODER_VAULT_TOKEN_POLICIES",
+			Default:     "coder-{{.Username}}",
+			Value:       &c.Vault.TokenPolicies,
+			Group:       &deploymentGroupVault,
+			YAML:        "tokenPolicies",
+		},
+		{
+			Name:        "Vault: Token TTL",
+			Description: "The lifetime of workspace owner tokens. Tokens are renewed while workspaces keep requesting them.",
+			Flag:        "vault-token-ttl",
+			Env:         "CODER_VAULT_TOKEN_TTL",
+			Default:     time.Hour.String(),
+			Value:       &c.Vault.TokenTTL,
+			Group:       &deploymentGroupVault,
+			YAML:        "tokenTTL",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		// Internal CA Options
+		{
+			Name:        "Internal CA: Enable",
+			Description: "Issue short-lived client certificates to agents, workspace proxies and provisioner daemons. The CA is generated on first start, stored in the database and rotated automatically.",
+			Flag:        "internal-ca-enable",
+			Env:         "CODER_INTERNAL_CA_ENABLE",
+			Default:     "false",
+			Value:       &c.InternalCA.Enable,
+			Group:       &deploymentGroupInternalCA,
+			YAML:        "enable",
+		},
+		{
+			Name:        "Internal CA: Certificate TTL",
+			Description: "The lifetime of issued client certificates. Clients renew their certificate after two thirds of its lifetime.",
+			Flag:        "internal-ca-certificate-ttl",
+			Env:         "CODER_INTERNAL_CA_CERTIFICATE_TTL",
+			Default:     (24 * time.Hour).String(),
+			Value:       &c.InternalCA.CertificateTTL,
+			Group:       &deploymentGroupInternalCA,
+			YAML:        "certificateTTL",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Internal CA: Require mTLS",
+			Description: "Reject requests from agents, workspace proxies and provisioner daemons that do not present a client certificate issued by the internal CA. Requires TLS to be terminated by coderd.",
+			Flag:        "internal-ca-require-mtls",
+			Env:         "CODER_INTERNAL_CA_REQUIRE_MTLS",
+			Default:     "false",
+			Value:       &c.InternalCA.RequireMTLS,
+			Group:       &deploymentGroupInternalCA,
+			YAML:        "requireMTLS",
+		},
+		// Naming Options
+		{
+			Name:        "Naming: Workspace Name Pattern",
+			Description: "A regular expression workspace names must match, e.g. \"^[a-z][a-z0-9-]*$\".",
+			Flag:        "workspace-name-pattern",
+			Env:         "CODER_WORKSPACE_NAME_PATTERN",
+			Value:       &c.Naming.WorkspaceName.Pattern,
+			Group:       &deploymentGroupNaming,
+			YAML:        "workspaceNamePattern",
+		},
+		{
+			Name:        "Naming: Workspace Name Max Length",
+			Description: "The maximum length of workspace names. Workspace names can never be longer than 32 characters. Set to 0 to not restrict it further.",
+			Flag:        "workspace-name-max-length",
+			Env:         "CODER_WORKSPACE_NAME_MAX_LENGTH",
+			Default:     "0",
+			Value:       &c.Naming.WorkspaceName.MaxLength,
+			Group:       &deploymentGroupNaming,
+			YAML:        "workspaceNameMaxLength",
+		},
+		{
+			Name:        "Naming: Workspace Name Reserved Prefixes",
+			Description: "Prefixes workspace names cannot start with, compared case-insensitively.",
+			Flag:        "workspace-name-reserved-prefixes",
+			Env:         "CODER_WORKSPACE_NAME_RESERVED_PREFIXES",
+			Value:       &c.Naming.WorkspaceName.ReservedPrefixes,
+			Group:       &deploymentGroupNaming,
+			YAML:        "workspaceNameReservedPrefixes",
+		},
+		{
+			Name:        "Naming: Username Pattern",
+			Description: "A regular expression the usernames of new and renamed users must match. Users created by an identity provider are not affected.",
+			Flag:        "username-pattern",
+			Env:         "CODER_USERNAME_PATTERN",
+			Value:       &c.Naming.Username.Pattern,
+			Group:       &deploymentGroupNaming,
+			YAML:        "usernamePattern",
+		},
+		{
+			Name:        "Naming: Username Max Length",
+			Description: "The maximum length of the usernames of new and renamed users. Usernames can never be longer than 32 characters. Set to 0 to not restrict it further.",
+			Flag:        "username-max-length",
+			Env:         "CODER_USERNAME_MAX_LENGTH",
+			Default:     "0",
+			Value:       &c.Naming.Username.MaxLength,
+			Group:       &deploymentGroupNaming,
+			YAML:        "usernameMaxLength",
+		},
+		{
+			Name:        "Naming: Username Reserved Prefixes",
+			Description: "Prefixes the usernames of new and renamed users cannot start with, compared case-insensitively.",
+			Flag:        "username-reserved-prefixes",
+			Env:         "CODER_USERNAME_RESERVED_PREFIXES",
+			Value:       &c.Naming.Username.ReservedPrefixes,
+			Group:       &deploymentGroupNaming,
+			YAML:        "usernameReservedPrefixes",
+		},
 		{
 			Name:        "Hide AI Tasks",
 			Description: "Hide AI tasks from the dashboard.",
@@ -3108,6 +4622,16 @@ Write out the current server config as YAML to stdout.`,
 			Group:       &deploymentGroupClient,
 			YAML:        "hideAITasks",
 		},
+		{
+			Name:        "AI Tasks: Max Concurrent Per User",
+			Description: "The maximum number of AI tasks a user can run at once. Further tasks are queued and started in order as running tasks stop. Set to 0 to not limit tasks.",
+			Flag:        "ai-tasks-max-concurrent-per-user",
+			Env:         "CODER_AI_TASKS_MAX_CONCURRENT_PER_USER",
+			Default:     "0",
+			Value:       &c.AITasksMaxConcurrentPerUser,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "aiTasksMaxConcurrentPerUser",
+		},
 	}
 
 	return opts
@@ -3123,6 +4647,16 @@ type LinkConfig struct {
 	Icon   string `json:"icon" yaml:"icon" enums:"bug,chat,docs"`
 }
 
+// NetworkZone restricts the source addresses that members of the listed roles
+// or groups can make requests from. Roles are matched by name, or by
+// "name:organization_id" for organization roles.
+type NetworkZone struct {
+	Name     string   `json:"name" yaml:"name"`
+	Roles    []string `json:"roles,omitempty" yaml:"roles"`
+	GroupIDs []string `json:"group_ids,omitempty" yaml:"group_ids"`
+	CIDRs    []string `json:"cidrs" yaml:"cidrs"`
+}
+
 // DeploymentOptionsWithoutSecrets returns a copy of the OptionSet with secret values omitted.
 func DeploymentOptionsWithoutSecrets(set serpent.OptionSet) serpent.OptionSet {
 	cpy := make(serpent.OptionSet, 0, len(set))
@@ -3190,6 +4724,40 @@ func (c *Client) DeploymentConfig(ctx context.Context) (*DeploymentConfig, error
 	return resp, json.NewDecoder(res.Body).Decode(resp)
 }
 
+// NamingPolicyViolation is a workspace or user whose name violates the naming
+// policy of the deployment.
+type NamingPolicyViolation struct {
+	ID   uuid.UUID `json:"id" format:"uuid"`
+	Name string    `json:"name"`
+	// OwnerName is the username of the owner of a workspace.
+	OwnerName string `json:"owner_name,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// NamingPolicyViolations lists the names that were set before the naming
+// policy and violate it, so they can be renamed.
+type NamingPolicyViolations struct {
+	Workspaces []NamingPolicyViolation `json:"workspaces"`
+	Users      []NamingPolicyViolation `json:"users"`
+}
+
+// NamingPolicyViolations returns the workspaces and users whose names violate
+// the naming policy of the deployment.
+func (c *Client) NamingPolicyViolations(ctx context.Context) (NamingPolicyViolations, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/naming-violations", nil)
+	if err != nil {
+		return NamingPolicyViolations{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return NamingPolicyViolations{}, ReadBodyAsError(res)
+	}
+
+	var violations NamingPolicyViolations
+	return violations, json.NewDecoder(res.Body).Decode(&violations)
+}
+
 func (c *Client) DeploymentStats(ctx context.Context) (DeploymentStats, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/stats", nil)
 	if err != nil {
@@ -3227,9 +4795,9 @@ type UpdateAppearanceConfig struct {
 type ServiceBannerConfig = BannerConfig
 
 type BannerConfig struct {
-	Enabled         bool   `json:"enabled"`
-	Message         string `json:"message,omitempty"`
-	BackgroundColor string `json:"background_color,omitempty"`
+	Enabled         bool   `json:"enabled" yaml:"enabled"`
+	Message         string `json:"message,omitempty" yaml:"message,omitempty"`
+	BackgroundColor string `json:"background_color,omitempty" yaml:"background_color,omitempty"`
 }
 
 // Appearance returns the configuration that modifies the visual
@@ -3247,8 +4815,8 @@ func (c *Client) Appearance(ctx context.Context) (AppearanceConfig, error) {
 	return cfg, json.NewDecoder(res.Body).Decode(&cfg)
 }
 
-func (c *Client) UpdateAppearance(ctx context.Context, appearance UpdateAppearanceConfig) error {
-	res, err := c.Request(ctx, http.MethodPut, "/api/v2/appearance", appearance)
+func (c *Client) UpdateAppearance(ctx context.Context, appearance UpdateAppearanceConfig, opts ...RequestOption) error {
+	res, err := c.Request(ctx, http.MethodPut, "/api/v2/appearance", appearance, opts...)
 	if err != nil {
 		return err
 	}
@@ -42,6 +42,9 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 			yaml: true,
 			env:  true,
 		},
+		"Settings File": {
+			yaml: true,
+		},
 		// Dangerous values? Not sure we should help users
 		// persistent their configuration.
 		"DANGEROUS: Allow Path App Sharing": {
@@ -66,9 +69,24 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 		"SCIM API Key": {
 			yaml: true,
 		},
+		"LDAP Bind Password": {
+			yaml: true,
+		},
+		"Audit Stream Splunk HEC Token": {
+			yaml: true,
+		},
+		"Audit Stream Datadog API Key": {
+			yaml: true,
+		},
+		"Audit Stream Webhook Headers": {
+			yaml: true,
+		},
 		"External Token Encryption Keys": {
 			yaml: true,
 		},
+		"Review Workspaces Webhook Secret": {
+			yaml: true,
+		},
 		"External Auth Providers": {
 			// Technically External Auth Providers can be provided through the env,
 			// but bypassing serpent. See cli.ReadExternalAuthProvidersFromEnv.
@@ -84,6 +102,21 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 		"Notifications: Email Auth: Password": {
 			yaml: true,
 		},
+		"Notifications: Slack: Bot Token": {
+			yaml: true,
+		},
+		"Alerting: PagerDuty Routing Key": {
+			yaml: true,
+		},
+		"Alerting: Opsgenie API Key": {
+			yaml: true,
+		},
+		"Vault: Token": {
+			yaml: true,
+		},
+		"Vault: AppRole Secret ID": {
+			yaml: true,
+		},
 	}
 
 	set := (&codersdk.DeploymentValues{}).Options()
@@ -628,3 +661,45 @@ func TestNotificationsCanBeDisabled(t *testing.T) {
 		})
 	}
 }
+
+func TestNamePolicy(t *testing.T) {
+	t.Parallel()
+
+	dv := codersdk.DeploymentValues{}
+	opts := dv.Options()
+	require.NoError(t, opts.SetDefaults())
+	// The zero policy allows every name.
+	require.NoError(t, dv.Naming.WorkspaceName.Valid("Any-Name"))
+
+	err := opts.ParseEnv([]serpent.EnvVar{
+		{Name: "CODER_WORKSPACE_NAME_PATTERN", Value: "^[a-z][a-z0-9-]*$"},
+		{Name: "CODER_WORKSPACE_NAME_MAX_LENGTH", Value: "10"},
+		{Name: "CODER_WORKSPACE_NAME_RESERVED_PREFIXES", Value: "sys-,admin"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"dev", true},
+		{"dev-1", true},
+		{"Dev", false},
+		{"1dev", false},
+		{"development", false},
+		{"sys-dev", false},
+		{"SYS-dev", false},
+		{"administer", false},
+		{"system", true},
+	}
+	for _, tt := range tests {
+		err := dv.Naming.WorkspaceName.Valid(tt.name)
+		if tt.valid {
+			require.NoError(t, err, tt.name)
+		} else {
+			require.Error(t, err, tt.name)
+		}
+	}
+	// Usernames have a separate policy.
+	require.NoError(t, dv.Naming.Username.Valid("Sys-Admin"))
+}
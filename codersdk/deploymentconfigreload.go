@@ -0,0 +1,71 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeploymentConfigReload is a reload of the deployment configuration and how
+// the replicas applied it.
+type DeploymentConfigReload struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	RequestedAt time.Time `json:"requested_at" format:"date-time"`
+	// ReplicaID is the replica that read the configuration.
+	ReplicaID uuid.UUID `json:"replica_id" format:"uuid"`
+	// Options are the options that changed.
+	Options []DeploymentConfigReloadOption `json:"options"`
+}
+
+// DeploymentConfigReloadOption is the status of an option that changed when
+// the deployment configuration was reloaded.
+type DeploymentConfigReloadOption struct {
+	Name string `json:"name"`
+	// RestartRequired is true if the option is not hot-reloadable. The
+	// change takes effect when the replicas restart.
+	RestartRequired bool `json:"restart_required"`
+	// Replicas are the replicas that applied the change, or failed to.
+	// Replicas that have not reported yet are missing.
+	Replicas []DeploymentConfigReloadReplica `json:"replicas"`
+}
+
+type DeploymentConfigReloadReplica struct {
+	ReplicaID uuid.UUID `json:"replica_id" format:"uuid"`
+	// Error is set if the replica failed to apply the change.
+	Error string `json:"error,omitempty"`
+}
+
+// ReloadDeploymentConfig makes the replica that serves the request read its
+// configuration file again, and applies the changed hot-reloadable options on
+// all replicas. The replicas apply them asynchronously, see
+// DeploymentConfigReload.
+func (c *Client) ReloadDeploymentConfig(ctx context.Context) (DeploymentConfigReload, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/deployment/config/reload", nil)
+	if err != nil {
+		return DeploymentConfigReload{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusAccepted {
+		return DeploymentConfigReload{}, ReadBodyAsError(res)
+	}
+	var reload DeploymentConfigReload
+	return reload, json.NewDecoder(res.Body).Decode(&reload)
+}
+
+// DeploymentConfigReload returns the latest reload of the deployment
+// configuration.
+func (c *Client) DeploymentConfigReload(ctx context.Context) (DeploymentConfigReload, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/config/reload", nil)
+	if err != nil {
+		return DeploymentConfigReload{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return DeploymentConfigReload{}, ReadBodyAsError(res)
+	}
+	var reload DeploymentConfigReload
+	return reload, json.NewDecoder(res.Body).Decode(&reload)
+}
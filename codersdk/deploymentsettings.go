@@ -0,0 +1,63 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// DeploymentSettingsManifest declares runtime-editable deployment settings. It
+// is read from the file passed with --settings-file. Settings that are omitted
+// are not managed by the manifest and can be changed freely.
+type DeploymentSettingsManifest struct {
+	Appearance    *DeploymentSettingsAppearance    `json:"appearance,omitempty" yaml:"appearance,omitempty"`
+	Notifications *DeploymentSettingsNotifications `json:"notifications,omitempty" yaml:"notifications,omitempty"`
+	Prebuilds     *DeploymentSettingsPrebuilds     `json:"prebuilds,omitempty" yaml:"prebuilds,omitempty"`
+}
+
+type DeploymentSettingsAppearance struct {
+	ApplicationName     *string        `json:"application_name,omitempty" yaml:"application_name,omitempty"`
+	LogoURL             *string        `json:"logo_url,omitempty" yaml:"logo_url,omitempty"`
+	AnnouncementBanners []BannerConfig `json:"announcement_banners,omitempty" yaml:"announcement_banners,omitempty"`
+}
+
+type DeploymentSettingsNotifications struct {
+	NotifierPaused *bool `json:"notifier_paused,omitempty" yaml:"notifier_paused,omitempty"`
+}
+
+type DeploymentSettingsPrebuilds struct {
+	ReconciliationPaused *bool `json:"reconciliation_paused,omitempty" yaml:"reconciliation_paused,omitempty"`
+}
+
+// DeploymentSettingsDrift lists the settings whose current values differ from
+// the settings file.
+type DeploymentSettingsDrift struct {
+	// Path is the path of the settings file.
+	Path  string                   `json:"path"`
+	Drift []DeploymentSettingDrift `json:"drift"`
+}
+
+type DeploymentSettingDrift struct {
+	// Setting is the path of the setting in the settings file, e.g.
+	// "appearance.application_name".
+	Setting string `json:"setting"`
+	// Manifest is the JSON encoded value in the settings file.
+	Manifest string `json:"manifest"`
+	// Current is the JSON encoded current value.
+	Current string `json:"current"`
+}
+
+// DeploymentSettingsDrift returns the differences between the settings file
+// the deployment was started with and the current settings.
+func (c *Client) DeploymentSettingsDrift(ctx context.Context) (DeploymentSettingsDrift, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/settings/drift", nil)
+	if err != nil {
+		return DeploymentSettingsDrift{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return DeploymentSettingsDrift{}, ReadBodyAsError(res)
+	}
+	var drift DeploymentSettingsDrift
+	return drift, json.NewDecoder(res.Body).Decode(&drift)
+}
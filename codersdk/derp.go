@@ -0,0 +1,44 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// DERPSession is a client connected to the embedded DERP relay of the coderd
+// replica that served the request. Clients that stay connected to a relay
+// with significant traffic are likely unable to establish a direct
+// connection.
+type DERPSession struct {
+	ID uuid.UUID `json:"id" format:"uuid"`
+	// NodeKey is the public key of the connected tailnet node. It is empty if
+	// the client has not identified itself yet.
+	NodeKey       string    `json:"node_key"`
+	RemoteAddr    string    `json:"remote_addr"`
+	WebSocket     bool      `json:"websocket"`
+	ConnectedAt   time.Time `json:"connected_at" format:"date-time"`
+	BytesReceived int64     `json:"bytes_received"`
+	BytesSent     int64     `json:"bytes_sent"`
+}
+
+// DERPSessions returns the clients currently connected to the embedded DERP
+// relay of the replica that serves the request.
+func (c *Client) DERPSessions(ctx context.Context) ([]DERPSession, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/debug/derp/sessions", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var sessions []DERPSession
+	return sessions, json.NewDecoder(res.Body).Decode(&sessions)
+}
@@ -0,0 +1,72 @@
+package codersdk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Organizations, groups, templates and the appearance config have entity
+// tags, which are returned in the ETag header of their responses. Updates
+// and deletes that send one in the If-Match header fail with
+// 412 Precondition Failed if the resource changed since it was read, so
+// clients managing them as code, like the Terraform provider, do not
+// overwrite changes made by others.
+//
+// The tags are derived from the response, so clients can compute them from
+// a resource they already have with the ETag methods.
+
+// WithIfMatch makes an update or delete conditional on the resource still
+// having the entity tag.
+func WithIfMatch(etag string) RequestOption {
+	return func(r *http.Request) {
+		if etag == "" {
+			return
+		}
+		r.Header.Set("If-Match", etag)
+	}
+}
+
+// ETag returns the entity tag of the organization.
+func (o Organization) ETag() string {
+	return etag(o.ID, o.UpdatedAt.UTC())
+}
+
+// ETag returns the entity tag of the template.
+func (t Template) ETag() string {
+	return etag(t.ID, t.UpdatedAt.UTC())
+}
+
+// ETag returns the entity tag of the group. Groups have no update time, so
+// it covers the fields that can be updated and the members.
+func (g Group) ETag() string {
+	members := make([]uuid.UUID, 0, len(g.Members))
+	for _, member := range g.Members {
+		members = append(members, member.ID)
+	}
+	slices.SortFunc(members, func(a, b uuid.UUID) int {
+		return slices.Compare(a[:], b[:])
+	})
+	return etag(g.ID, g.Name, g.DisplayName, g.AvatarURL, g.QuotaAllowance, g.Source, members)
+}
+
+// ETag returns the entity tag of the appearance config. It covers the
+// fields that can be updated.
+func (a AppearanceConfig) ETag() string {
+	banners := a.AnnouncementBanners
+	if len(banners) == 0 {
+		banners = nil
+	}
+	return etag(a.ApplicationName, a.LogoURL, banners)
+}
+
+func etag(values ...any) string {
+	// Only values that always marshal are passed.
+	data, _ := json.Marshal(values)
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
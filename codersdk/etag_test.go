@@ -0,0 +1,61 @@
+package codersdk_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestETag(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SurvivesJSON", func(t *testing.T) {
+		t.Parallel()
+		// Clients compute tags from decoded responses, so they must match
+		// the tag the server computed, whatever the time zone.
+		org := codersdk.Organization{
+			MinimalOrganization: codersdk.MinimalOrganization{ID: uuid.New()},
+			UpdatedAt:           time.Date(2025, 1, 2, 3, 4, 5, 6000, time.FixedZone("CEST", 2*60*60)),
+		}
+		data, err := json.Marshal(org)
+		require.NoError(t, err)
+		var decoded codersdk.Organization
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		require.Equal(t, org.ETag(), decoded.ETag())
+
+		org.UpdatedAt = org.UpdatedAt.UTC()
+		require.Equal(t, decoded.ETag(), org.ETag())
+		org.UpdatedAt = org.UpdatedAt.Add(time.Microsecond)
+		require.NotEqual(t, decoded.ETag(), org.ETag())
+	})
+
+	t.Run("GroupMembers", func(t *testing.T) {
+		t.Parallel()
+		a := codersdk.ReducedUser{MinimalUser: codersdk.MinimalUser{ID: uuid.New()}}
+		b := codersdk.ReducedUser{MinimalUser: codersdk.MinimalUser{ID: uuid.New()}}
+		group := codersdk.Group{ID: uuid.New(), Name: "developers", Members: []codersdk.ReducedUser{a, b}}
+		reordered := group
+		reordered.Members = []codersdk.ReducedUser{b, a}
+		require.Equal(t, group.ETag(), reordered.ETag())
+
+		// Derived fields are not part of the tag.
+		reordered.TotalMemberCount = 10
+		reordered.OrganizationName = "other"
+		require.Equal(t, group.ETag(), reordered.ETag())
+
+		reordered.Members = []codersdk.ReducedUser{a}
+		require.NotEqual(t, group.ETag(), reordered.ETag())
+	})
+
+	t.Run("AppearanceBanners", func(t *testing.T) {
+		t.Parallel()
+		empty := codersdk.AppearanceConfig{ApplicationName: "Coder"}
+		emptyList := codersdk.AppearanceConfig{ApplicationName: "Coder", AnnouncementBanners: []codersdk.BannerConfig{}}
+		require.Equal(t, empty.ETag(), emptyList.ETag())
+	})
+}
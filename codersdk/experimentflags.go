@@ -0,0 +1,116 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// ExperimentFlag enables or disables an experiment at runtime, overriding
+// the experiments the deployment was started with (--experiments).
+type ExperimentFlag struct {
+	Experiment Experiment `json:"experiment"`
+	Enabled    bool       `json:"enabled"`
+	// OrganizationIDs and GroupIDs limit the flag to the members of any of
+	// the organizations or groups. A flag without targets applies to the
+	// whole deployment.
+	OrganizationIDs []uuid.UUID `json:"organization_ids,omitempty" format:"uuid"`
+	GroupIDs        []uuid.UUID `json:"group_ids,omitempty" format:"uuid"`
+}
+
+// Targeted returns whether the flag only applies to some organizations or
+// groups.
+func (f ExperimentFlag) Targeted() bool {
+	return len(f.OrganizationIDs) > 0 || len(f.GroupIDs) > 0
+}
+
+// ExperimentFlags are the experiment flags of the deployment. Targeted flags
+// take precedence over flags without targets, and are evaluated in order: the
+// first targeted flag matching an actor decides whether the experiment is
+// enabled for them.
+type ExperimentFlags struct {
+	Flags []ExperimentFlag `json:"flags"`
+}
+
+type ExperimentEvaluationSource string
+
+const (
+	// ExperimentEvaluationSourceDefault is used when no flag applies and the
+	// experiment was not enabled at startup.
+	ExperimentEvaluationSourceDefault ExperimentEvaluationSource = "default"
+	// ExperimentEvaluationSourceStartup is used when no flag applies and the
+	// experiment was enabled at startup.
+	ExperimentEvaluationSourceStartup ExperimentEvaluationSource = "startup"
+	// ExperimentEvaluationSourceFlag is used when a flag without targets
+	// applies.
+	ExperimentEvaluationSourceFlag ExperimentEvaluationSource = "flag"
+	// ExperimentEvaluationSourceTargetedFlag is used when a flag targeting an
+	// organization or group of the actor applies.
+	ExperimentEvaluationSourceTargetedFlag ExperimentEvaluationSource = "targeted_flag"
+)
+
+// ExperimentEvaluation is whether an experiment is enabled for an actor, and
+// why.
+type ExperimentEvaluation struct {
+	Experiment Experiment                 `json:"experiment"`
+	Enabled    bool                       `json:"enabled"`
+	Source     ExperimentEvaluationSource `json:"source"`
+	// OrganizationID or GroupID is the target a targeted flag matched on.
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty" format:"uuid"`
+	GroupID        *uuid.UUID `json:"group_id,omitempty" format:"uuid"`
+}
+
+// ExperimentEvaluationResponse is the evaluation of all known experiments for
+// the actor of the request.
+type ExperimentEvaluationResponse struct {
+	UserID          uuid.UUID              `json:"user_id" format:"uuid"`
+	OrganizationIDs []uuid.UUID            `json:"organization_ids" format:"uuid"`
+	GroupIDs        []uuid.UUID            `json:"group_ids" format:"uuid"`
+	Evaluations     []ExperimentEvaluation `json:"evaluations"`
+}
+
+// ExperimentFlags returns the experiment flags of the deployment.
+func (c *Client) ExperimentFlags(ctx context.Context) (ExperimentFlags, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/experiments/flags", nil)
+	if err != nil {
+		return ExperimentFlags{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ExperimentFlags{}, ReadBodyAsError(res)
+	}
+	var flags ExperimentFlags
+	return flags, json.NewDecoder(res.Body).Decode(&flags)
+}
+
+// UpdateExperimentFlags replaces the experiment flags of the deployment. The
+// flags apply to all replicas without a restart.
+func (c *Client) UpdateExperimentFlags(ctx context.Context, flags ExperimentFlags) (ExperimentFlags, error) {
+	res, err := c.Request(ctx, http.MethodPut, "/api/v2/experiments/flags", flags)
+	if err != nil {
+		return ExperimentFlags{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ExperimentFlags{}, ReadBodyAsError(res)
+	}
+	var updated ExperimentFlags
+	return updated, json.NewDecoder(res.Body).Decode(&updated)
+}
+
+// ExperimentEvaluation returns whether each known experiment is enabled for
+// the authenticated user, and why.
+func (c *Client) ExperimentEvaluation(ctx context.Context) (ExperimentEvaluationResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/experiments/evaluation", nil)
+	if err != nil {
+		return ExperimentEvaluationResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ExperimentEvaluationResponse{}, ReadBodyAsError(res)
+	}
+	var resp ExperimentEvaluationResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
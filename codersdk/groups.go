@@ -17,6 +17,8 @@ type GroupSource string
 const (
 	GroupSourceUser GroupSource = "user"
 	GroupSourceOIDC GroupSource = "oidc"
+	GroupSourceSCIM GroupSource = "scim"
+	GroupSourceLDAP GroupSource = "ldap"
 )
 
 type CreateGroupRequest struct {
@@ -156,10 +158,10 @@ type PatchGroupRequest struct {
 	QuotaAllowance *int     `json:"quota_allowance"`
 }
 
-func (c *Client) PatchGroup(ctx context.Context, group uuid.UUID, req PatchGroupRequest) (Group, error) {
+func (c *Client) PatchGroup(ctx context.Context, group uuid.UUID, req PatchGroupRequest, opts ...RequestOption) (Group, error) {
 	res, err := c.Request(ctx, http.MethodPatch,
 		fmt.Sprintf("/api/v2/groups/%s", group.String()),
-		req,
+		req, opts...,
 	)
 	if err != nil {
 		return Group{}, xerrors.Errorf("make request: %w", err)
@@ -173,10 +175,10 @@ func (c *Client) PatchGroup(ctx context.Context, group uuid.UUID, req PatchGroup
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
-func (c *Client) DeleteGroup(ctx context.Context, group uuid.UUID) error {
+func (c *Client) DeleteGroup(ctx context.Context, group uuid.UUID, opts ...RequestOption) error {
 	res, err := c.Request(ctx, http.MethodDelete,
 		fmt.Sprintf("/api/v2/groups/%s", group.String()),
-		nil,
+		nil, opts...,
 	)
 	if err != nil {
 		return xerrors.Errorf("make request: %w", err)
@@ -188,3 +190,54 @@ func (c *Client) DeleteGroup(ctx context.Context, group uuid.UUID) error {
 	}
 	return nil
 }
+
+// GroupQuotaAllowance is the allowance of a group in a quota dimension other
+// than the daily cost, such as cpu or memory. Templates declare the cost of a
+// resource in a dimension with a "cost:<dimension>" metadata item.
+type GroupQuotaAllowance struct {
+	Dimension string `json:"dimension"`
+	Allowance int64  `json:"allowance"`
+	// Consumed is the quota of the dimension consumed by the workspaces of
+	// the group members.
+	Consumed int64 `json:"consumed"`
+}
+
+type UpdateGroupQuotaAllowancesRequest struct {
+	// Allowances maps quota dimensions to the allowance of the group. It
+	// replaces every existing allowance of the group.
+	Allowances map[string]int64 `json:"allowances"`
+}
+
+func (c *Client) GroupQuotaAllowances(ctx context.Context, group uuid.UUID) ([]GroupQuotaAllowance, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/groups/%s/quota-allowances", group.String()),
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []GroupQuotaAllowance
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) UpdateGroupQuotaAllowances(ctx context.Context, group uuid.UUID, req UpdateGroupQuotaAllowancesRequest) ([]GroupQuotaAllowance, error) {
+	res, err := c.Request(ctx, http.MethodPut,
+		fmt.Sprintf("/api/v2/groups/%s/quota-allowances", group.String()),
+		req,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []GroupQuotaAllowance
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
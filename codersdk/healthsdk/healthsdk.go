@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 	"tailscale.com/derp"
 	"tailscale.com/net/netcheck"
@@ -96,6 +98,80 @@ func (c *HealthClient) PutHealthSettings(ctx context.Context, settings HealthSet
 	return nil
 }
 
+// HealthScores returns the health scores that the replicas recorded in
+// [start, end).
+func (c *HealthClient) HealthScores(ctx context.Context, start, end time.Time) ([]HealthScore, error) {
+	res, err := c.client.Request(ctx, http.MethodGet, "/api/v2/debug/health/scores", nil, func(r *http.Request) {
+		q := r.URL.Query()
+		if !start.IsZero() {
+			q.Set("start", start.Format(time.RFC3339))
+		}
+		if !end.IsZero() {
+			q.Set("end", end.Format(time.RFC3339))
+		}
+		r.URL.RawQuery = q.Encode()
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	var scores []HealthScore
+	return scores, json.NewDecoder(res.Body).Decode(&scores)
+}
+
+// HealthScore is the result of a periodic healthcheck of a replica. Scores
+// range from 0 (failing) to 100 (healthy).
+type HealthScore struct {
+	ReplicaID uuid.UUID `json:"replica_id" format:"uuid"`
+	Time      time.Time `json:"time" format:"date-time"`
+	// Score is the average score of all components.
+	Score              int   `json:"score"`
+	Database           int   `json:"database"`
+	DatabaseLatencyMS  int64 `json:"database_latency_ms"`
+	DERP               int   `json:"derp"`
+	ProvisionerDaemons int   `json:"provisioner_daemons"`
+	// Replicas is the share of replicas that can reach each other.
+	Replicas  int `json:"replicas"`
+	Websocket int `json:"websocket"`
+}
+
+// DebugDiagnostics captures runtime diagnostics of the replica that serves
+// the request. Capturing the CPU profile blocks for cpuDuration; a zero
+// duration skips it.
+func (c *HealthClient) DebugDiagnostics(ctx context.Context, cpuDuration time.Duration) (ReplicaDiagnostics, error) {
+	res, err := c.client.Request(ctx, http.MethodGet, "/api/v2/debug/diagnostics", nil, func(r *http.Request) {
+		q := r.URL.Query()
+		q.Set("cpu_seconds", strconv.Itoa(int(cpuDuration.Seconds())))
+		r.URL.RawQuery = q.Encode()
+	})
+	if err != nil {
+		return ReplicaDiagnostics{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReplicaDiagnostics{}, codersdk.ReadBodyAsError(res)
+	}
+	var diagnostics ReplicaDiagnostics
+	return diagnostics, json.NewDecoder(res.Body).Decode(&diagnostics)
+}
+
+// ReplicaDiagnostics are runtime diagnostics of a single replica for support
+// escalations.
+type ReplicaDiagnostics struct {
+	ReplicaID  uuid.UUID `json:"replica_id" format:"uuid"`
+	Hostname   string    `json:"hostname"`
+	CapturedAt time.Time `json:"captured_at" format:"date-time"`
+	// Profiles are pprof profiles by name, e.g. goroutine, heap and cpu.
+	Profiles map[string][]byte `json:"profiles"`
+	// Logs are the most recent logs of the replica.
+	Logs string `json:"logs"`
+	// Errors are the reasons why some diagnostics could not be captured.
+	Errors []string `json:"errors"`
+}
+
 // HealthcheckReport contains information about the health status of a Coder deployment.
 type HealthcheckReport struct {
 	// Time is the time the report was generated at.
@@ -19,6 +19,23 @@ type IDPSyncMapping[ResourceIdType uuid.UUID | string] struct {
 	Gets ResourceIdType
 }
 
+// IDPSyncRule transforms a claim value before it is mapped to a Coder
+// resource. Rules are applied in order, and only the first rule that matches
+// a value is applied to it. Values that no rule matches are left unchanged.
+type IDPSyncRule struct {
+	// Match is a regular expression the claim value must match for the rule
+	// to apply. If nil, the rule applies to every value.
+	Match *regexp.Regexp `json:"match"`
+	// Replacement replaces the claim value, and can reference the capture
+	// groups of Match like "$1" or "${name}". Matching many claim values to
+	// the same replacement maps them to the same resource. If empty, the
+	// claim value is kept.
+	Replacement string `json:"replacement,omitempty"`
+	// StripPrefix is removed from the start of the value, after the
+	// replacement.
+	StripPrefix string `json:"strip_prefix,omitempty"`
+}
+
 type GroupSyncSettings struct {
 	// Field is the name of the claim field that specifies what groups a user
 	// should be in. If empty, no groups will be synced.
@@ -38,6 +55,12 @@ type GroupSyncSettings struct {
 	// For legacy configurations, this config option has to remain.
 	// Deprecated: Use Mapping instead.
 	LegacyNameMapping map[string]string `json:"legacy_group_name_mapping,omitempty"`
+	// Rules transform the groups returned by the OIDC provider before they
+	// are filtered and mapped.
+	Rules []IDPSyncRule `json:"rules,omitempty"`
+	// DefaultGroups are the Coder group IDs given to users whose claims
+	// don't map to any group.
+	DefaultGroups []uuid.UUID `json:"default_groups,omitempty"`
 }
 
 func (c *Client) GroupIDPSyncSettings(ctx context.Context, orgID string) (GroupSyncSettings, error) {
@@ -114,6 +137,12 @@ type RoleSyncSettings struct {
 	Field string `json:"field"`
 	// Mapping is a map from OIDC groups to Coder organization roles.
 	Mapping map[string][]string `json:"mapping"`
+	// Rules transform the roles returned by the OIDC provider before they
+	// are mapped.
+	Rules []IDPSyncRule `json:"rules,omitempty"`
+	// DefaultRoles are the organization roles given to users whose claims
+	// don't map to any role.
+	DefaultRoles []string `json:"default_roles,omitempty"`
 }
 
 func (c *Client) RoleIDPSyncSettings(ctx context.Context, orgID string) (RoleSyncSettings, error) {
@@ -182,6 +211,55 @@ func (c *Client) PatchRoleIDPSyncMapping(ctx context.Context, orgID string, req
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+type IDPSyncPreviewRequest struct {
+	// Claims are the merged ID token and user info claims of a user, as
+	// they would be received on login.
+	Claims map[string]interface{} `json:"claims"`
+}
+
+type IDPSyncPreviewGroup struct {
+	ID             uuid.UUID `json:"id" format:"uuid"`
+	Name           string    `json:"name"`
+	QuotaAllowance int       `json:"quota_allowance"`
+}
+
+// IDPSyncPreviewResponse is how group and role sync would map the claims of
+// a user in an organization.
+type IDPSyncPreviewResponse struct {
+	// GroupSyncEnabled is false if group sync is not configured for the
+	// organization, in which case group memberships are left unchanged.
+	GroupSyncEnabled bool                  `json:"group_sync_enabled"`
+	Groups           []IDPSyncPreviewGroup `json:"groups"`
+	// MissingGroups are the group names the claims map to that don't exist.
+	// They are created on login if auto creating missing groups is enabled.
+	MissingGroups []string `json:"missing_groups"`
+	// QuotaBudget is the workspace quota the user gets from the groups.
+	QuotaBudget int `json:"quota_budget"`
+	// RoleSyncEnabled is false if role sync is not configured for the
+	// organization, in which case roles are left unchanged.
+	RoleSyncEnabled bool     `json:"role_sync_enabled"`
+	Roles           []string `json:"roles"`
+	// IgnoredRoles are the roles the claims map to that don't exist in the
+	// organization.
+	IgnoredRoles []string `json:"ignored_roles"`
+}
+
+// PreviewIDPSync returns how the group and role sync settings of the
+// organization map the given claims, without changing any user.
+func (c *Client) PreviewIDPSync(ctx context.Context, orgID string, req IDPSyncPreviewRequest) (IDPSyncPreviewResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/settings/idpsync/preview", orgID), req)
+	if err != nil {
+		return IDPSyncPreviewResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return IDPSyncPreviewResponse{}, ReadBodyAsError(res)
+	}
+	var resp IDPSyncPreviewResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 type OrganizationSyncSettings struct {
 	// Field selects the claim field to be used as the created user's
 	// organizations. If the field is the empty string, then no organization
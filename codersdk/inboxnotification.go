@@ -4,10 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/codersdk/wsjson"
+	"github.com/coder/websocket"
 )
 
 const (
@@ -35,14 +40,44 @@ type InboxNotificationAction struct {
 	URL   string `json:"url"`
 }
 
+// InboxNotificationEventKind is the kind of the messages sent by the inbox
+// notifications watch.
+type InboxNotificationEventKind string
+
+const (
+	// InboxNotificationEventKindNew is sent when a notification is created.
+	InboxNotificationEventKindNew InboxNotificationEventKind = "new"
+	// InboxNotificationEventKindReadStatus is sent when notifications are
+	// marked as read or unread, if the watch requested read status events.
+	InboxNotificationEventKindReadStatus InboxNotificationEventKind = "read_status"
+)
+
+// InboxNotificationReadStatusChange describes notifications that were marked
+// as read or unread.
+type InboxNotificationReadStatusChange struct {
+	// IDs of the notifications that changed. If empty, the change applies to
+	// all the notifications of the categories, or to all the notifications of
+	// the user if there are no categories.
+	IDs        []uuid.UUID            `json:"ids,omitempty" format:"uuid"`
+	Categories []NotificationCategory `json:"categories,omitempty"`
+	IsRead     bool                   `json:"is_read"`
+}
+
 type GetInboxNotificationResponse struct {
-	Notification InboxNotification `json:"notification"`
-	UnreadCount  int               `json:"unread_count"`
+	// Kind is set by the inbox notifications watch. Read status events are
+	// only sent to watches that requested them.
+	Kind         InboxNotificationEventKind `json:"kind,omitempty" enums:"new,read_status"`
+	Notification InboxNotification          `json:"notification"`
+	// ReadStatus is set for read status events.
+	ReadStatus  *InboxNotificationReadStatusChange `json:"read_status,omitempty"`
+	UnreadCount int                                `json:"unread_count"`
 }
 
 type ListInboxNotificationsRequest struct {
-	Targets        string `json:"targets,omitempty"`
-	Templates      string `json:"templates,omitempty"`
+	Targets   string `json:"targets,omitempty"`
+	Templates string `json:"templates,omitempty"`
+	// Categories is a comma-separated list of notification categories.
+	Categories     string `json:"categories,omitempty"`
 	ReadStatus     string `json:"read_status,omitempty"`
 	StartingBefore string `json:"starting_before,omitempty"`
 }
@@ -60,6 +95,9 @@ func ListInboxNotificationsRequestToQueryParams(req ListInboxNotificationsReques
 	if req.Templates != "" {
 		opts = append(opts, WithQueryParam("templates", req.Templates))
 	}
+	if req.Categories != "" {
+		opts = append(opts, WithQueryParam("categories", req.Categories))
+	}
 	if req.ReadStatus != "" {
 		opts = append(opts, WithQueryParam("read_status", req.ReadStatus))
 	}
@@ -134,3 +172,104 @@ func (c *Client) MarkAllInboxNotificationsAsRead(ctx context.Context) error {
 
 	return nil
 }
+
+type UpdateInboxNotificationsReadStatusRequest struct {
+	// IDs of the notifications to update.
+	IDs []uuid.UUID `json:"ids,omitempty" format:"uuid"`
+	// Categories restricts the update to the notifications of the categories.
+	// At least one ID or category is required.
+	Categories []NotificationCategory `json:"categories,omitempty"`
+	IsRead     bool                   `json:"is_read"`
+}
+
+type UpdateInboxNotificationsReadStatusResponse struct {
+	// UpdatedCount is the number of notifications whose read status changed.
+	UpdatedCount int `json:"updated_count"`
+	UnreadCount  int `json:"unread_count"`
+}
+
+// UpdateInboxNotificationsReadStatus marks several notifications as read or
+// unread at once.
+func (c *Client) UpdateInboxNotificationsReadStatus(ctx context.Context, req UpdateInboxNotificationsReadStatusRequest) (UpdateInboxNotificationsReadStatusResponse, error) {
+	res, err := c.Request(ctx, http.MethodPut, "/api/v2/notifications/inbox/read-status", req)
+	if err != nil {
+		return UpdateInboxNotificationsReadStatusResponse{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return UpdateInboxNotificationsReadStatusResponse{}, ReadBodyAsError(res)
+	}
+
+	var resp UpdateInboxNotificationsReadStatusResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+type InboxNotificationCategoryUnreadCount struct {
+	// Category is empty for notifications whose template has no category.
+	Category    NotificationCategory `json:"category" enums:"builds,dormancy,account,template_updates"`
+	UnreadCount int                  `json:"unread_count"`
+}
+
+type InboxNotificationUnreadCountsResponse struct {
+	UnreadCount int                                    `json:"unread_count"`
+	Categories  []InboxNotificationCategoryUnreadCount `json:"categories"`
+}
+
+// InboxNotificationUnreadCounts returns the number of unread notifications of
+// the user, in total and by category.
+func (c *Client) InboxNotificationUnreadCounts(ctx context.Context) (InboxNotificationUnreadCountsResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/notifications/inbox/unread-counts", nil)
+	if err != nil {
+		return InboxNotificationUnreadCountsResponse{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return InboxNotificationUnreadCountsResponse{}, ReadBodyAsError(res)
+	}
+
+	var resp InboxNotificationUnreadCountsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+type WatchInboxNotificationsRequest struct {
+	Targets    string `json:"targets,omitempty"`
+	Templates  string `json:"templates,omitempty"`
+	Categories string `json:"categories,omitempty"`
+	ReadStatus string `json:"read_status,omitempty"`
+	// Format of the title and content of the notifications, either markdown
+	// or plaintext.
+	Format string `json:"format,omitempty"`
+	// ReadStatusEvents requests messages when notifications are marked as
+	// read or unread, in addition to new notifications.
+	ReadStatusEvents bool `json:"read_status_events,omitempty"`
+}
+
+// WatchInboxNotifications streams the inbox notifications of the user as they
+// are created, and their read status changes if requested.
+func (c *Client) WatchInboxNotifications(ctx context.Context, req WatchInboxNotificationsRequest) (<-chan GetInboxNotificationResponse, io.Closer, error) {
+	q := url.Values{}
+	for k, v := range map[string]string{
+		"targets":     req.Targets,
+		"templates":   req.Templates,
+		"categories":  req.Categories,
+		"read_status": req.ReadStatus,
+		"format":      req.Format,
+	} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	if req.ReadStatusEvents {
+		q.Set("read_status_events", "true")
+	}
+	conn, err := c.Dial(ctx, "/api/v2/notifications/inbox/watch?"+q.Encode(), &websocket.DialOptions{
+		CompressionMode: websocket.CompressionDisabled,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	d := wsjson.NewDecoder[GetInboxNotificationResponse](conn, websocket.MessageText, c.logger)
+	return d.Chan(), d, nil
+}
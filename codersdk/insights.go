@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -283,6 +284,223 @@ func (c *Client) TemplateInsights(ctx context.Context, req TemplateInsightsReque
 	return result, json.NewDecoder(resp.Body).Decode(&result)
 }
 
+// TemplateBuildInsightsResponse is the response from the template build
+// insights endpoint.
+type TemplateBuildInsightsResponse struct {
+	TemplateID      uuid.UUID                             `json:"template_id" format:"uuid"`
+	StartTime       time.Time                             `json:"start_time" format:"date-time"`
+	EndTime         time.Time                             `json:"end_time" format:"date-time"`
+	IntervalReports []TemplateBuildInsightsIntervalReport `json:"interval_reports"`
+	StageDurations  []TemplateBuildStageDuration          `json:"stage_durations"`
+	FailureGroups   []TemplateBuildFailureGroup           `json:"failure_groups"`
+}
+
+// TemplateBuildInsightsIntervalReport is the number of builds of a template
+// that completed within a specific interval.
+type TemplateBuildInsightsIntervalReport struct {
+	StartTime       time.Time              `json:"start_time" format:"date-time"`
+	EndTime         time.Time              `json:"end_time" format:"date-time"`
+	Interval        InsightsReportInterval `json:"interval" example:"day"`
+	SucceededBuilds int64                  `json:"succeeded_builds" example:"18"`
+	FailedBuilds    int64                  `json:"failed_builds" example:"2"`
+	CanceledBuilds  int64                  `json:"canceled_builds" example:"1"`
+	// FailureRate is the share of failed builds among the builds that
+	// succeeded or failed, between 0 and 1. Canceled builds are not counted.
+	FailureRate float64 `json:"failure_rate" example:"0.1"`
+}
+
+// TemplateBuildStage is a stage of a workspace build.
+type TemplateBuildStage string
+
+// TemplateBuildStage enums.
+const (
+	// TemplateBuildStageTotal covers the whole provisioner job.
+	TemplateBuildStageTotal TemplateBuildStage = "total"
+	TemplateBuildStageInit  TemplateBuildStage = "init"
+	TemplateBuildStagePlan  TemplateBuildStage = "plan"
+	TemplateBuildStageGraph TemplateBuildStage = "graph"
+	TemplateBuildStageApply TemplateBuildStage = "apply"
+)
+
+// TemplateBuildStageDuration is the median and 95th percentile duration of a
+// stage of the successful builds of a template.
+type TemplateBuildStageDuration struct {
+	Stage      TemplateBuildStage `json:"stage" enums:"total,init,plan,graph,apply"`
+	Builds     int64              `json:"builds" example:"18"`
+	P50Seconds float64            `json:"p50_seconds" example:"42.5"`
+	P95Seconds float64            `json:"p95_seconds" example:"97.1"`
+}
+
+// TemplateBuildFailureGroup groups failed builds of a template by their error
+// message, ignoring variable parts such as IDs, numbers and quoted values.
+type TemplateBuildFailureGroup struct {
+	// Pattern is the error message with its variable parts replaced by
+	// placeholders.
+	Pattern string `json:"pattern"`
+	// Example is the most common error message in the group.
+	Example      string    `json:"example"`
+	Builds       int64     `json:"builds" example:"2"`
+	LastFailedAt time.Time `json:"last_failed_at" format:"date-time"`
+}
+
+type TemplateBuildInsightsRequest struct {
+	TemplateID uuid.UUID              `json:"template_id" format:"uuid"`
+	StartTime  time.Time              `json:"start_time" format:"date-time"`
+	EndTime    time.Time              `json:"end_time" format:"date-time"`
+	Interval   InsightsReportInterval `json:"interval" example:"day"`
+}
+
+func (c *Client) TemplateBuildInsights(ctx context.Context, req TemplateBuildInsightsRequest) (TemplateBuildInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("template_id", req.TemplateID.String())
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+	if req.Interval != "" {
+		qp.Add("interval", string(req.Interval))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/template-builds?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return TemplateBuildInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return TemplateBuildInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result TemplateBuildInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// WorkspaceUsageReportGroupBy defines how the workspace usage report is
+// aggregated.
+type WorkspaceUsageReportGroupBy string
+
+// WorkspaceUsageReportGroupBy enums.
+const (
+	WorkspaceUsageReportGroupByUser  WorkspaceUsageReportGroupBy = "user"
+	WorkspaceUsageReportGroupByGroup WorkspaceUsageReportGroupBy = "group"
+)
+
+func (g WorkspaceUsageReportGroupBy) Valid() bool {
+	switch g {
+	case WorkspaceUsageReportGroupByUser, WorkspaceUsageReportGroupByGroup:
+		return true
+	}
+	return false
+}
+
+// WorkspaceUsageReportFormat is the format of the workspace usage report.
+type WorkspaceUsageReportFormat string
+
+// WorkspaceUsageReportFormat enums.
+const (
+	WorkspaceUsageReportFormatJSON WorkspaceUsageReportFormat = "json"
+	WorkspaceUsageReportFormatCSV  WorkspaceUsageReportFormat = "csv"
+)
+
+func (f WorkspaceUsageReportFormat) Valid() bool {
+	switch f {
+	case WorkspaceUsageReportFormatJSON, WorkspaceUsageReportFormatCSV:
+		return true
+	}
+	return false
+}
+
+// WorkspaceUsageReportResponse is the response from the workspace usage
+// report endpoint. Only the entries of the requested grouping are set.
+type WorkspaceUsageReportResponse struct {
+	StartTime time.Time                   `json:"start_time" format:"date-time"`
+	EndTime   time.Time                   `json:"end_time" format:"date-time"`
+	GroupBy   WorkspaceUsageReportGroupBy `json:"group_by" enums:"user,group"`
+	Users     []UserWorkspaceUsage        `json:"users,omitempty"`
+	Groups    []GroupWorkspaceUsage       `json:"groups,omitempty"`
+}
+
+// UserWorkspaceUsage is the usage of the workspaces owned by a user. Cost is
+// the quota credits accrued by the daily cost of the workspaces while they
+// ran.
+type UserWorkspaceUsage struct {
+	UserID       uuid.UUID `json:"user_id" format:"uuid"`
+	Username     string    `json:"username"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	Workspaces   int64     `json:"workspaces" example:"2"`
+	RuntimeHours float64   `json:"runtime_hours" example:"37.5"`
+	Cost         float64   `json:"cost" example:"12.5"`
+	// DimensionHours maps quota dimensions, such as cpu or memory, to the cost
+	// of the workspaces in the dimension multiplied by the hours they ran.
+	DimensionHours      map[string]float64 `json:"dimension_hours"`
+	LastSeenAt          time.Time          `json:"last_seen_at" format:"date-time"`
+	LastWorkspaceUsedAt time.Time          `json:"last_workspace_used_at" format:"date-time"`
+}
+
+// GroupWorkspaceUsage is the usage of the workspaces owned by the members of
+// a group in the organization of the group.
+type GroupWorkspaceUsage struct {
+	GroupID          uuid.UUID `json:"group_id" format:"uuid"`
+	GroupName        string    `json:"group_name"`
+	GroupDisplayName string    `json:"group_display_name"`
+	OrganizationID   uuid.UUID `json:"organization_id" format:"uuid"`
+	OrganizationName string    `json:"organization_name"`
+	// Users is the number of members who owned a running workspace.
+	Users        int64   `json:"users" example:"4"`
+	Workspaces   int64   `json:"workspaces" example:"6"`
+	RuntimeHours float64 `json:"runtime_hours" example:"112.25"`
+	Cost         float64 `json:"cost" example:"40"`
+	// DimensionHours maps quota dimensions, such as cpu or memory, to the cost
+	// of the workspaces in the dimension multiplied by the hours they ran.
+	DimensionHours      map[string]float64 `json:"dimension_hours"`
+	LastWorkspaceUsedAt time.Time          `json:"last_workspace_used_at" format:"date-time"`
+}
+
+type WorkspaceUsageReportRequest struct {
+	StartTime time.Time                   `json:"start_time" format:"date-time"`
+	EndTime   time.Time                   `json:"end_time" format:"date-time"`
+	GroupBy   WorkspaceUsageReportGroupBy `json:"group_by"`
+}
+
+func (req WorkspaceUsageReportRequest) url(format WorkspaceUsageReportFormat) string {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+	if req.GroupBy != "" {
+		qp.Add("group_by", string(req.GroupBy))
+	}
+	qp.Add("format", string(format))
+	return fmt.Sprintf("/api/v2/insights/workspace-usage?%s", qp.Encode())
+}
+
+func (c *Client) WorkspaceUsageReport(ctx context.Context, req WorkspaceUsageReportRequest) (WorkspaceUsageReportResponse, error) {
+	resp, err := c.Request(ctx, http.MethodGet, req.url(WorkspaceUsageReportFormatJSON), nil)
+	if err != nil {
+		return WorkspaceUsageReportResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return WorkspaceUsageReportResponse{}, ReadBodyAsError(resp)
+	}
+	var result WorkspaceUsageReportResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// WorkspaceUsageReportCSV returns the workspace usage report as CSV, with a
+// header row followed by one row per user or group.
+func (c *Client) WorkspaceUsageReportCSV(ctx context.Context, req WorkspaceUsageReportRequest) ([]byte, error) {
+	resp, err := c.Request(ctx, http.MethodGet, req.url(WorkspaceUsageReportFormatCSV), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(resp)
+	}
+	return io.ReadAll(resp.Body)
+}
+
 type GetUserStatusCountsResponse struct {
 	StatusCounts map[UserStatus][]UserStatusChangeCount `json:"status_counts"`
 }
@@ -313,3 +531,52 @@ func (c *Client) GetUserStatusCounts(ctx context.Context, req GetUserStatusCount
 	var result GetUserStatusCountsResponse
 	return result, json.NewDecoder(resp.Body).Decode(&result)
 }
+
+// NetworkConnectionType is how a workspace connection is currently routed.
+type NetworkConnectionType string
+
+const (
+	NetworkConnectionTypeP2P  NetworkConnectionType = "p2p"
+	NetworkConnectionTypeDERP NetworkConnectionType = "derp"
+	// NetworkConnectionTypeUnknown is used for connections that have not
+	// reported a latency measurement yet.
+	NetworkConnectionTypeUnknown NetworkConnectionType = "unknown"
+)
+
+// ConnectionQualityResponse summarizes the network quality of active workspace
+// connections, based on the network telemetry reported by clients.
+type ConnectionQualityResponse struct {
+	GeneratedAt time.Time                     `json:"generated_at" format:"date-time"`
+	ClientTypes []ConnectionQualityClientType `json:"client_types"`
+}
+
+type ConnectionQualityClientType struct {
+	// ClientType is the kind of client reporting the connections, e.g. "cli".
+	ClientType         string `json:"client_type"`
+	Connections        int64  `json:"connections"`
+	P2PConnections     int64  `json:"p2p_connections"`
+	DERPConnections    int64  `json:"derp_connections"`
+	UnknownConnections int64  `json:"unknown_connections"`
+	// RelayFallbacks is the number of connections that fell back from P2P to
+	// a DERP relay across all replicas.
+	RelayFallbacks int64 `json:"relay_fallbacks"`
+	// HandshakeRetries is the number of WireGuard handshakes that clients
+	// had to retry across all replicas.
+	HandshakeRetries    int64   `json:"handshake_retries"`
+	P2PLatencyMedianMS  float64 `json:"p2p_latency_median_ms"`
+	DERPLatencyMedianMS float64 `json:"derp_latency_median_ms"`
+}
+
+func (c *Client) ConnectionQuality(ctx context.Context) (ConnectionQualityResponse, error) {
+	resp, err := c.Request(ctx, http.MethodGet, "/api/v2/insights/connection-quality", nil)
+	if err != nil {
+		return ConnectionQualityResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ConnectionQualityResponse{}, ReadBodyAsError(resp)
+	}
+	var result ConnectionQualityResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
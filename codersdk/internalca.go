@@ -0,0 +1,72 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IssueCertificateRequest requests a client certificate from the internal CA
+// of the deployment. Only the public key of the CSR is used; the identity in
+// the certificate is derived from the authenticated caller.
+type IssueCertificateRequest struct {
+	// CSR is a PEM encoded certificate signing request.
+	CSR string `json:"csr" validate:"required"`
+}
+
+// IssuedCertificate is a short-lived client certificate issued by the
+// internal CA.
+type IssuedCertificate struct {
+	// Certificate is the PEM encoded client certificate.
+	Certificate string `json:"certificate"`
+	// CABundle contains the PEM encoded certificates of every internal CA
+	// currently trusted by the deployment.
+	CABundle  string    `json:"ca_bundle"`
+	ExpiresAt time.Time `json:"expires_at" format:"date-time"`
+}
+
+// IssueProvisionerDaemonCertificateRequest requests a client certificate for
+// a provisioner daemon. The daemon authenticates the same way it does when it
+// connects to serve jobs.
+type IssueProvisionerDaemonCertificateRequest struct {
+	IssueCertificateRequest
+	// Organization is the organization the daemon serves. The default
+	// organization is used when it is not set.
+	Organization   uuid.UUID `json:"-" format:"uuid"`
+	PreSharedKey   string    `json:"-"`
+	ProvisionerKey string    `json:"-"`
+}
+
+// IssueProvisionerDaemonCertificate requests a client certificate for a
+// provisioner daemon from the internal CA.
+func (c *Client) IssueProvisionerDaemonCertificate(ctx context.Context, req IssueProvisionerDaemonCertificateRequest) (IssuedCertificate, error) {
+	orgParam := req.Organization.String()
+	if req.Organization == uuid.Nil {
+		orgParam = DefaultOrganization
+	}
+	res, err := c.Request(ctx, http.MethodPost,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerdaemons/certificate", orgParam),
+		req.IssueCertificateRequest,
+		func(r *http.Request) {
+			if req.ProvisionerKey != "" {
+				r.Header.Set(ProvisionerDaemonKey, req.ProvisionerKey)
+			}
+			if req.PreSharedKey != "" {
+				r.Header.Set(ProvisionerDaemonPSK, req.PreSharedKey)
+			}
+		},
+	)
+	if err != nil {
+		return IssuedCertificate{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return IssuedCertificate{}, ReadBodyAsError(res)
+	}
+	var cert IssuedCertificate
+	return cert, json.NewDecoder(res.Body).Decode(&cert)
+}
@@ -0,0 +1,62 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"golang.org/x/xerrors"
+)
+
+type LDAPSyncChangeType string
+
+const (
+	LDAPSyncChangeCreateUser        LDAPSyncChangeType = "create_user"
+	LDAPSyncChangeLinkUser          LDAPSyncChangeType = "link_user"
+	LDAPSyncChangeUpdateUser        LDAPSyncChangeType = "update_user"
+	LDAPSyncChangeActivateUser      LDAPSyncChangeType = "activate_user"
+	LDAPSyncChangeSuspendUser       LDAPSyncChangeType = "suspend_user"
+	LDAPSyncChangeCreateGroup       LDAPSyncChangeType = "create_group"
+	LDAPSyncChangeDeleteGroup       LDAPSyncChangeType = "delete_group"
+	LDAPSyncChangeAddGroupMember    LDAPSyncChangeType = "add_group_member"
+	LDAPSyncChangeRemoveGroupMember LDAPSyncChangeType = "remove_group_member"
+)
+
+// LDAPSyncChange is a change LDAP sync made, or would make in a dry run.
+type LDAPSyncChange struct {
+	Type LDAPSyncChangeType `json:"type"`
+	// Username is the Coder username of the user the change applies to. Users
+	// that are created use the username they will be created with.
+	Username string `json:"username,omitempty"`
+	// DN is the distinguished name of the directory entry behind the change.
+	DN    string `json:"dn,omitempty"`
+	Group string `json:"group,omitempty"`
+}
+
+type LDAPSyncRequest struct {
+	// DryRun returns the changes without applying them.
+	DryRun bool `json:"dry_run"`
+}
+
+type LDAPSyncResponse struct {
+	DryRun          bool             `json:"dry_run"`
+	DirectoryUsers  int              `json:"directory_users"`
+	DirectoryGroups int              `json:"directory_groups"`
+	Changes         []LDAPSyncChange `json:"changes"`
+}
+
+// SyncLDAP syncs users and groups from the LDAP directory immediately,
+// instead of waiting for the next periodic sync.
+func (c *Client) SyncLDAP(ctx context.Context, req LDAPSyncRequest) (LDAPSyncResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/ldap/sync", req)
+	if err != nil {
+		return LDAPSyncResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return LDAPSyncResponse{}, ReadBodyAsError(res)
+	}
+	var resp LDAPSyncResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
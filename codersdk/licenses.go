@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -134,3 +136,112 @@ func (c *Client) DeleteLicense(ctx context.Context, id int32) error {
 	}
 	return nil
 }
+
+// LicenseUsagePoint is the number of active users on a day.
+type LicenseUsagePoint struct {
+	Date        time.Time `json:"date" format:"date-time"`
+	ActiveUsers int64     `json:"active_users"`
+}
+
+// LicenseSeatReservation reserves licensed user seats for the members of a
+// group. Reservations do not block users from being created, they are used
+// to forecast when the seats run out.
+type LicenseSeatReservation struct {
+	GroupID        uuid.UUID `json:"group_id" format:"uuid"`
+	GroupName      string    `json:"group_name"`
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	Seats          int64     `json:"seats"`
+	// ActiveMembers is the number of active members of the group. They
+	// occupy the reserved seats first.
+	ActiveMembers int64 `json:"active_members"`
+}
+
+type UpsertLicenseSeatReservationRequest struct {
+	Seats int64 `json:"seats" validate:"required,min=1"`
+}
+
+type LicenseUsage struct {
+	// Seats is the number of licensed user seats. It is omitted if the
+	// license does not limit the number of users.
+	Seats       *int64 `json:"seats,omitempty"`
+	ActiveUsers int64  `json:"active_users"`
+	// UnfilledReservedSeats is the number of reserved seats that are not
+	// occupied by active members of the reserving groups.
+	UnfilledReservedSeats int64 `json:"unfilled_reserved_seats"`
+	// History is the number of active users on each day.
+	History []LicenseUsagePoint `json:"history"`
+	// Forecast projects the linear trend of the history into the future.
+	Forecast []LicenseUsagePoint `json:"forecast"`
+	// ExhaustedAt is the first day on which the forecasted active users and
+	// the unfilled reserved seats take up every licensed seat. It is omitted
+	// if the seats do not run out within the forecast.
+	ExhaustedAt  *time.Time               `json:"exhausted_at,omitempty" format:"date-time"`
+	Reservations []LicenseSeatReservation `json:"reservations"`
+}
+
+type LicenseUsageRequest struct {
+	// HistoryDays is the number of past days used for the trend. Defaults to
+	// 90.
+	HistoryDays int `json:"history_days,omitempty"`
+	// ForecastDays is the number of days to project. Defaults to 90.
+	ForecastDays int `json:"forecast_days,omitempty"`
+}
+
+func (c *Client) LicenseUsage(ctx context.Context, req LicenseUsageRequest) (LicenseUsage, error) {
+	qp := url.Values{}
+	if req.HistoryDays > 0 {
+		qp.Set("history_days", strconv.Itoa(req.HistoryDays))
+	}
+	if req.ForecastDays > 0 {
+		qp.Set("forecast_days", strconv.Itoa(req.ForecastDays))
+	}
+	reqURL := fmt.Sprintf("/api/v2/licenses/usage?%s", qp.Encode())
+	res, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return LicenseUsage{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return LicenseUsage{}, ReadBodyAsError(res)
+	}
+	var usage LicenseUsage
+	return usage, json.NewDecoder(res.Body).Decode(&usage)
+}
+
+func (c *Client) LicenseSeatReservations(ctx context.Context) ([]LicenseSeatReservation, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/licenses/reservations", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var reservations []LicenseSeatReservation
+	return reservations, json.NewDecoder(res.Body).Decode(&reservations)
+}
+
+func (c *Client) UpsertLicenseSeatReservation(ctx context.Context, groupID uuid.UUID, req UpsertLicenseSeatReservationRequest) (LicenseSeatReservation, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/licenses/reservations/%s", groupID), req)
+	if err != nil {
+		return LicenseSeatReservation{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return LicenseSeatReservation{}, ReadBodyAsError(res)
+	}
+	var reservation LicenseSeatReservation
+	return reservation, json.NewDecoder(res.Body).Decode(&reservation)
+}
+
+func (c *Client) DeleteLicenseSeatReservation(ctx context.Context, groupID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/licenses/reservations/%s", groupID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -15,6 +15,13 @@ var (
 
 	templateVersionName = regexp.MustCompile(`^[a-zA-Z0-9]+(?:[_.-]{1}[a-zA-Z0-9]+)*$`)
 	templateDisplayName = regexp.MustCompile(`^[^\s](.*[^\s])?$`)
+
+	workspaceLabelKey   = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9._/-]*[a-z0-9])?$`)
+	workspaceLabelValue = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9._-]*[a-z0-9])?$`)
+
+	secretName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+	domainName = regexp.MustCompile(`^(?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)*[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?$`)
 )
 
 // UsernameFrom returns a best-effort username from the provided string.
@@ -117,6 +124,72 @@ func GroupNameValid(str string) error {
 	return nil
 }
 
+// WorkspaceLabelKeyValid returns whether the input string is a valid
+// workspace label key.
+func WorkspaceLabelKeyValid(str string) error {
+	if len(str) > 63 {
+		return xerrors.New("must be <= 63 characters")
+	}
+	if len(str) < 1 {
+		return xerrors.New("must be >= 1 character")
+	}
+	matched := workspaceLabelKey.MatchString(str)
+	if !matched {
+		return xerrors.New("must be lowercase alphanumeric with hyphens, underscores, dots and slashes")
+	}
+	return nil
+}
+
+// WorkspaceLabelValueValid returns whether the input string is a valid
+// workspace label value. Values may be empty.
+func WorkspaceLabelValueValid(str string) error {
+	if len(str) == 0 {
+		return nil
+	}
+	if len(str) > 63 {
+		return xerrors.New("must be <= 63 characters")
+	}
+	matched := workspaceLabelValue.MatchString(str)
+	if !matched {
+		return xerrors.New("must be lowercase alphanumeric with hyphens, underscores and dots")
+	}
+	return nil
+}
+
+// SecretNameValid returns whether the input string is a valid secret
+// name. Secret names double as Terraform variable and environment
+// variable names, so they are restricted to identifier characters.
+func SecretNameValid(str string) error {
+	if len(str) > 64 {
+		return xerrors.New("must be <= 64 characters")
+	}
+	if len(str) < 1 {
+		return xerrors.New("must be >= 1 character")
+	}
+	matched := secretName.MatchString(str)
+	if !matched {
+		return xerrors.New("must start with a letter or underscore and contain only alphanumerics and underscores")
+	}
+	return nil
+}
+
+// EgressDomainValid returns whether the input string is a valid domain name
+// for an egress policy. Wildcards are not supported since agents resolve the
+// domain to allow the addresses it points to.
+func EgressDomainValid(str string) error {
+	if len(str) > 253 {
+		return xerrors.New("must be <= 253 characters")
+	}
+	if len(str) < 1 {
+		return xerrors.New("must be >= 1 character")
+	}
+	matched := domainName.MatchString(str)
+	if !matched {
+		return xerrors.New("must be a lowercase domain name without wildcards")
+	}
+	return nil
+}
+
 // NormalizeUserRealName normalizes a user name such that it will pass
 // validation by UserRealNameValid. This is done to avoid blocking
 // little  Bobby  Whitespace  from using Coder.
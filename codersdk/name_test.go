@@ -287,3 +287,91 @@ func TestGroupNameValid(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkspaceLabelValid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Key   string
+		Value string
+		Valid bool
+	}{
+		{"team", "platform", true},
+		{"cost-center", "eng-1234", true},
+		{"example.com/owner", "", true},
+		{"", "platform", false},
+		{"Team", "platform", false},
+		{"-team", "platform", false},
+		{"team", "Platform", false},
+		{"team", "plat/form", false},
+		{strings.Repeat("a", 63), strings.Repeat("b", 63), true},
+		{strings.Repeat("a", 64), "platform", false},
+		{"team", strings.Repeat("b", 64), false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Key+"="+testCase.Value, func(t *testing.T) {
+			t.Parallel()
+			err := codersdk.WorkspaceLabelKeyValid(testCase.Key)
+			if err == nil {
+				err = codersdk.WorkspaceLabelValueValid(testCase.Value)
+			}
+			assert.Equal(t, testCase.Valid, err == nil, "expected valid=%t but got error: %v", testCase.Valid, err)
+		})
+	}
+}
+
+func TestSecretNameValid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name  string
+		Valid bool
+	}{
+		{"GITHUB_TOKEN", true},
+		{"aws_access_key", true},
+		{"_private", true},
+		{"token2", true},
+		{"", false},
+		{"2token", false},
+		{"my-token", false},
+		{"my token", false},
+		{strings.Repeat("a", 64), true},
+		{strings.Repeat("a", 65), false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+			err := codersdk.SecretNameValid(testCase.Name)
+			assert.Equal(t, testCase.Valid, err == nil, "expected valid=%t but got error: %v", testCase.Valid, err)
+		})
+	}
+}
+
+func TestEgressDomainValid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name  string
+		Valid bool
+	}{
+		{"github.com", true},
+		{"registry.npmjs.org", true},
+		{"localhost", true},
+		{"x-1.example.com", true},
+		{"", false},
+		{"*.github.com", false},
+		{"GitHub.com", false},
+		{"-github.com", false},
+		{"github.com.", false},
+		{"git hub.com", false},
+		{"https://github.com", false},
+		{strings.Repeat("a", 64) + ".com", false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+			err := codersdk.EgressDomainValid(testCase.Name)
+			assert.Equal(t, testCase.Valid, err == nil, "expected valid=%t but got error: %v", testCase.Valid, err)
+		})
+	}
+}
@@ -26,6 +26,10 @@ type NotificationTemplate struct {
 	Method           string    `json:"method"`
 	Kind             string    `json:"kind"`
 	EnabledByDefault bool      `json:"enabled_by_default"`
+	Priority         string    `json:"priority" enums:"low,normal,high"`
+	// Category is empty for templates which can only be disabled
+	// individually.
+	Category NotificationCategory `json:"category,omitempty"`
 }
 
 type NotificationMethodsResponse struct {
@@ -39,6 +43,41 @@ type NotificationPreference struct {
 	UpdatedAt              time.Time `json:"updated_at" format:"date-time"`
 }
 
+// NotificationCategory groups notification templates which users can disable
+// together, on every or only some notification methods.
+type NotificationCategory string
+
+const (
+	NotificationCategoryBuilds          NotificationCategory = "builds"
+	NotificationCategoryDormancy        NotificationCategory = "dormancy"
+	NotificationCategoryAccount         NotificationCategory = "account"
+	NotificationCategoryTemplateUpdates NotificationCategory = "template_updates"
+)
+
+var NotificationCategories = []NotificationCategory{
+	NotificationCategoryBuilds,
+	NotificationCategoryDormancy,
+	NotificationCategoryAccount,
+	NotificationCategoryTemplateUpdates,
+}
+
+// NotificationCategoryPreference controls whether the notifications of a
+// category are delivered to a user on a notification method. Notifications
+// are delivered unless a preference disables them.
+type NotificationCategoryPreference struct {
+	Category  NotificationCategory `json:"category" enums:"builds,dormancy,account,template_updates"`
+	Method    string               `json:"method" enums:"smtp,webhook,inbox,slack,teams"`
+	Disabled  bool                 `json:"disabled"`
+	UpdatedAt time.Time            `json:"updated_at" format:"date-time"`
+}
+
+// UserNotificationSettings describes how notifications are delivered to a user.
+type UserNotificationSettings struct {
+	// Digest batches low priority notifications into a single daily
+	// notification, delivered at the end of the user's quiet hours.
+	Digest bool `json:"digest"`
+}
+
 // GetNotificationsSettings retrieves the notifications settings, which currently just describes whether all
 // notifications are paused from sending.
 func (c *Client) GetNotificationsSettings(ctx context.Context) (NotificationsSettings, error) {
@@ -168,6 +207,72 @@ func (c *Client) UpdateUserNotificationPreferences(ctx context.Context, userID u
 	return prefs, nil
 }
 
+// GetUserNotificationCategoryPreferences retrieves the notification category
+// preferences of a given user.
+func (c *Client) GetUserNotificationCategoryPreferences(ctx context.Context, user string) ([]NotificationCategoryPreference, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/notifications/preferences/categories", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var prefs []NotificationCategoryPreference
+	return prefs, json.NewDecoder(res.Body).Decode(&prefs)
+}
+
+// UpdateUserNotificationCategoryPreferences updates the notification category
+// preferences of a given user, and returns all of their category preferences.
+func (c *Client) UpdateUserNotificationCategoryPreferences(ctx context.Context, user string, req UpdateUserNotificationCategoryPreferences) ([]NotificationCategoryPreference, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/notifications/preferences/categories", user), req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var prefs []NotificationCategoryPreference
+	return prefs, json.NewDecoder(res.Body).Decode(&prefs)
+}
+
+// GetUserNotificationSettings retrieves the notification delivery settings of a given user.
+func (c *Client) GetUserNotificationSettings(ctx context.Context, userID uuid.UUID) (UserNotificationSettings, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/notifications/settings", userID.String()), nil)
+	if err != nil {
+		return UserNotificationSettings{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return UserNotificationSettings{}, ReadBodyAsError(res)
+	}
+
+	var settings UserNotificationSettings
+	return settings, json.NewDecoder(res.Body).Decode(&settings)
+}
+
+// UpdateUserNotificationSettings updates the notification delivery settings of a given user.
+func (c *Client) UpdateUserNotificationSettings(ctx context.Context, userID uuid.UUID, req UserNotificationSettings) (UserNotificationSettings, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/notifications/settings", userID.String()), req)
+	if err != nil {
+		return UserNotificationSettings{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return UserNotificationSettings{}, ReadBodyAsError(res)
+	}
+
+	var settings UserNotificationSettings
+	return settings, json.NewDecoder(res.Body).Decode(&settings)
+}
+
 // GetNotificationDispatchMethods the available and default notification dispatch methods.
 func (c *Client) GetNotificationDispatchMethods(ctx context.Context) (NotificationMethodsResponse, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/notifications/dispatch-methods", nil)
@@ -214,6 +319,16 @@ type UpdateUserNotificationPreferences struct {
 	TemplateDisabledMap map[string]bool `json:"template_disabled_map"`
 }
 
+type UpdateUserNotificationCategoryPreferences struct {
+	Preferences []UpdateNotificationCategoryPreference `json:"preferences" validate:"required"`
+}
+
+type UpdateNotificationCategoryPreference struct {
+	Category NotificationCategory `json:"category" validate:"required" enums:"builds,dormancy,account,template_updates"`
+	Method   string               `json:"method" validate:"required" enums:"smtp,webhook,inbox,slack,teams"`
+	Disabled bool                 `json:"disabled"`
+}
+
 type WebpushMessageAction struct {
 	Label string `json:"label"`
 	URL   string `json:"url"`
@@ -91,6 +91,26 @@ type PaginatedMembersResponse struct {
 	Count   int                              `json:"count"`
 }
 
+// CrossOrganizationMember is an organization member listed across all
+// organizations. The email is empty unless the caller can manage the member.
+type CrossOrganizationMember struct {
+	OrganizationMemberWithUserData `table:"m,recursive_inline"`
+	OrganizationName               string `table:"organization name" json:"organization_name"`
+	OrganizationDisplayName        string `table:"organization display name" json:"organization_display_name"`
+}
+
+type CrossOrganizationMembersRequest struct {
+	// SearchQuery supports "organization:", "role:" and free text matching
+	// the username, name or email.
+	SearchQuery string `json:"q,omitempty"`
+	Pagination
+}
+
+type CrossOrganizationMembersResponse struct {
+	Members []CrossOrganizationMember `json:"members"`
+	Count   int                       `json:"count"`
+}
+
 type CreateOrganizationRequest struct {
 	Name string `json:"name" validate:"required,organization_name"`
 	// DisplayName will default to the same value as `Name` if not provided.
@@ -233,6 +253,11 @@ type CreateWorkspaceRequest struct {
 	RichParameterValues     []WorkspaceBuildParameter `json:"rich_parameter_values,omitempty"`
 	AutomaticUpdates        AutomaticUpdates          `json:"automatic_updates,omitempty"`
 	TemplateVersionPresetID uuid.UUID                 `json:"template_version_preset_id,omitempty" format:"uuid"`
+	// Labels are free-form key/value pairs used to group workspaces.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Ephemeral workspaces are deleted instead of stopped, and only consume
+	// quota while they are running.
+	Ephemeral bool `json:"ephemeral,omitempty"`
 }
 
 func (c *Client) OrganizationByName(ctx context.Context, name string) (Organization, error) {
@@ -289,8 +314,8 @@ func (c *Client) CreateOrganization(ctx context.Context, req CreateOrganizationR
 
 // UpdateOrganization will update information about the corresponding organization, based on
 // the UUID/name provided as `orgID`.
-func (c *Client) UpdateOrganization(ctx context.Context, orgID string, req UpdateOrganizationRequest) (Organization, error) {
-	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/organizations/%s", orgID), req)
+func (c *Client) UpdateOrganization(ctx context.Context, orgID string, req UpdateOrganizationRequest, opts ...RequestOption) (Organization, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/organizations/%s", orgID), req, opts...)
 	if err != nil {
 		return Organization{}, xerrors.Errorf("execute request: %w", err)
 	}
@@ -306,8 +331,8 @@ func (c *Client) UpdateOrganization(ctx context.Context, orgID string, req Updat
 
 // DeleteOrganization will remove the corresponding organization from the deployment, based on
 // the UUID/name provided as `orgID`.
-func (c *Client) DeleteOrganization(ctx context.Context, orgID string) error {
-	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/organizations/%s", orgID), nil)
+func (c *Client) DeleteOrganization(ctx context.Context, orgID string, opts ...RequestOption) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/organizations/%s", orgID), nil, opts...)
 	if err != nil {
 		return xerrors.Errorf("execute request: %w", err)
 	}
@@ -442,6 +467,50 @@ func (c *Client) OrganizationProvisionerJob(ctx context.Context, organizationID,
 	return job, json.NewDecoder(res.Body).Decode(&job)
 }
 
+// SearchProvisionerJobLogsRequest is a full-text search over the logs of the
+// provisioner jobs in an organization. The query uses the same syntax as other
+// filters, e.g. `"connection refused" type:workspace_build template:docker`.
+type SearchProvisionerJobLogsRequest struct {
+	SearchQuery string `json:"q"`
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// ProvisionerJobLogMatch is a provisioner job log that matched a search, with
+// the workspace build or template version that its job belongs to.
+type ProvisionerJobLogMatch struct {
+	Log               ProvisionerJobLog  `json:"log"`
+	JobID             uuid.UUID          `json:"job_id" format:"uuid"`
+	JobType           ProvisionerJobType `json:"job_type"`
+	WorkspaceID       *uuid.UUID         `json:"workspace_id,omitempty" format:"uuid"`
+	WorkspaceBuildID  *uuid.UUID         `json:"workspace_build_id,omitempty" format:"uuid"`
+	TemplateID        *uuid.UUID         `json:"template_id,omitempty" format:"uuid"`
+	TemplateVersionID *uuid.UUID         `json:"template_version_id,omitempty" format:"uuid"`
+}
+
+// SearchProvisionerJobLogs returns the provisioner job logs of an
+// organization that match a search, from newest to oldest.
+func (c *Client) SearchProvisionerJobLogs(ctx context.Context, organizationID uuid.UUID, req SearchProvisionerJobLogsRequest) ([]ProvisionerJobLogMatch, error) {
+	qp := url.Values{}
+	qp.Set("q", req.SearchQuery)
+	if req.Limit > 0 {
+		qp.Set("limit", strconv.Itoa(req.Limit))
+	}
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerjobs/logs?%s", organizationID.String(), qp.Encode()),
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var matches []ProvisionerJobLogMatch
+	return matches, json.NewDecoder(res.Body).Decode(&matches)
+}
+
 func joinSlice[T ~string](s []T) string {
 	var ss []string
 	for _, v := range s {
@@ -0,0 +1,86 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// OrganizationSettingOverrides are the deployment settings an organization
+// overrides. Settings that are null inherit the deployment setting.
+type OrganizationSettingOverrides struct {
+	// DefaultTTLMillis is the default autostop of templates created in the
+	// organization without one.
+	DefaultTTLMillis *int64 `json:"default_ttl_ms"`
+	// DefaultQuietHoursSchedule is the quiet hours schedule of workspaces in
+	// the organization whose owner has not set their own schedule.
+	DefaultQuietHoursSchedule *string `json:"default_quiet_hours_schedule"`
+	// AllowedExternalAuthProviders are the IDs of the external auth providers
+	// templates in the organization may require. An empty list allows none.
+	AllowedExternalAuthProviders []string `json:"allowed_external_auth_providers"`
+	// DisabledNotificationTemplateIDs are notification templates that are
+	// never sent for workspaces and templates in the organization.
+	DisabledNotificationTemplateIDs []uuid.UUID `json:"disabled_notification_template_ids" format:"uuid"`
+	// ProvisionerMaxJobs is the number of provisioner jobs of the
+	// organization that can run at once. 0 doesn't limit jobs. Changing it
+	// requires permission to update the deployment config.
+	ProvisionerMaxJobs *int32 `json:"provisioner_max_jobs"`
+	// ProvisionerReservedJobs is the number of jobs out of the shared
+	// provisioner capacity reserved for the organization. Changing it
+	// requires permission to update the deployment config.
+	ProvisionerReservedJobs *int32 `json:"provisioner_reserved_jobs"`
+}
+
+// OrganizationSettingValues are the values of the deployment settings that
+// organizations can override.
+type OrganizationSettingValues struct {
+	DefaultTTLMillis                int64       `json:"default_ttl_ms"`
+	DefaultQuietHoursSchedule       string      `json:"default_quiet_hours_schedule"`
+	AllowedExternalAuthProviders    []string    `json:"allowed_external_auth_providers"`
+	DisabledNotificationTemplateIDs []uuid.UUID `json:"disabled_notification_template_ids" format:"uuid"`
+	ProvisionerMaxJobs              int32       `json:"provisioner_max_jobs"`
+	ProvisionerReservedJobs         int32       `json:"provisioner_reserved_jobs"`
+}
+
+// OrganizationSettings shows how the overrides of an organization are
+// combined with the deployment settings.
+type OrganizationSettings struct {
+	Overrides  OrganizationSettingOverrides `json:"overrides"`
+	Deployment OrganizationSettingValues    `json:"deployment"`
+	// Effective are the settings that apply to the organization: the
+	// overrides, or the deployment settings where nothing is overridden.
+	Effective OrganizationSettingValues `json:"effective"`
+}
+
+func (c *Client) OrganizationSettings(ctx context.Context, orgID uuid.UUID) (OrganizationSettings, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/settings/overrides", orgID), nil)
+	if err != nil {
+		return OrganizationSettings{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return OrganizationSettings{}, ReadBodyAsError(res)
+	}
+	var resp OrganizationSettings
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// PutOrganizationSettingOverrides replaces the overrides of the organization.
+func (c *Client) PutOrganizationSettingOverrides(ctx context.Context, orgID uuid.UUID, req OrganizationSettingOverrides) (OrganizationSettings, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/organizations/%s/settings/overrides", orgID), req)
+	if err != nil {
+		return OrganizationSettings{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return OrganizationSettings{}, ReadBodyAsError(res)
+	}
+	var resp OrganizationSettings
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
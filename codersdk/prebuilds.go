@@ -3,7 +3,11 @@ package codersdk
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 )
 
 type PrebuildsSettings struct {
@@ -42,3 +46,50 @@ func (c *Client) PutPrebuildsSettings(ctx context.Context, settings PrebuildsSet
 	}
 	return nil
 }
+
+// ClaimPrebuiltWorkspaceRequest claims a ready prebuilt workspace of a preset
+// for the caller, e.g. to run a CI job in it. The claimed workspace is
+// ephemeral: it is deleted when it is released, stopped or its deadline
+// passes, and the prebuilds reconciler replaces it in the pool.
+type ClaimPrebuiltWorkspaceRequest struct {
+	TemplateVersionPresetID uuid.UUID `json:"template_version_preset_id" validate:"required" format:"uuid"`
+	// Name is the name of the claimed workspace. A random name is used if
+	// it is empty.
+	Name string `json:"name,omitempty" validate:"omitempty,workspace_name"`
+	// TTLMillis is how long the workspace is held before it is deleted.
+	// Extend the deadline of the workspace to hold it longer. Defaults to
+	// one hour.
+	TTLMillis *int64 `json:"ttl_ms,omitempty"`
+}
+
+// ClaimPrebuiltWorkspace claims a ready prebuilt workspace of the preset. It
+// fails with 409 Conflict if none is ready, it never builds a new workspace.
+// Release the workspace with ReleasePrebuiltWorkspace when it is no longer
+// needed.
+func (c *Client) ClaimPrebuiltWorkspace(ctx context.Context, organizationID uuid.UUID, req ClaimPrebuiltWorkspaceRequest) (Workspace, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/prebuilds/claim", organizationID), req)
+	if err != nil {
+		return Workspace{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return Workspace{}, ReadBodyAsError(res)
+	}
+	var workspace Workspace
+	return workspace, json.NewDecoder(res.Body).Decode(&workspace)
+}
+
+// ReleasePrebuiltWorkspace queues a build that deletes a workspace claimed
+// with ClaimPrebuiltWorkspace.
+func (c *Client) ReleasePrebuiltWorkspace(ctx context.Context, organizationID, workspaceID uuid.UUID) (WorkspaceBuild, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/prebuilds/claim/%s/release", organizationID, workspaceID), nil)
+	if err != nil {
+		return WorkspaceBuild{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceBuild{}, ReadBodyAsError(res)
+	}
+	var build WorkspaceBuild
+	return build, json.NewDecoder(res.Body).Decode(&build)
+}
@@ -0,0 +1,203 @@
+package codersdk
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// RegionProbePlan tells clients which regions to measure their latency to
+// before reporting it with ReportRegionLatencies.
+type RegionProbePlan struct {
+	Probes []RegionProbe `json:"probes"`
+	// Samples is the number of requests to send to each probe URL. The
+	// lowest latency of the samples is the one to report, since the first
+	// request usually pays for the connection setup.
+	Samples int `json:"samples"`
+	// TimeoutMS bounds every probe request.
+	TimeoutMS int64 `json:"timeout_ms"`
+}
+
+// RegionProbe is a healthy region and the URL to measure latency against.
+type RegionProbe struct {
+	RegionID   uuid.UUID `json:"region_id" format:"uuid"`
+	RegionName string    `json:"region_name"`
+	URL        string    `json:"url"`
+}
+
+// RegionLatency is the latency a client measured to a region.
+type RegionLatency struct {
+	RegionID  uuid.UUID `json:"region_id" format:"uuid"`
+	LatencyMS float64   `json:"latency_ms"`
+	// MeasuredAt is set by the server when the latency is reported.
+	MeasuredAt time.Time `json:"measured_at,omitempty" format:"date-time"`
+}
+
+type ReportRegionLatenciesRequest struct {
+	Latencies []RegionLatency `json:"latencies"`
+}
+
+// ProxySelectionReason explains why a region was selected for a user.
+type ProxySelectionReason string
+
+const (
+	// ProxySelectionReasonPreference means the user pinned the region.
+	ProxySelectionReasonPreference ProxySelectionReason = "preference"
+	// ProxySelectionReasonLatency means the region had the lowest latency
+	// the user's client measured.
+	ProxySelectionReasonLatency ProxySelectionReason = "latency"
+	// ProxySelectionReasonHealth means there were no recent measurements,
+	// so the healthy region the server prefers was selected.
+	ProxySelectionReasonHealth ProxySelectionReason = "health"
+)
+
+// UserProxyPreference is the region a user's workspace app and terminal
+// traffic should go through.
+type UserProxyPreference struct {
+	// PreferredRegionID is the region the user pinned. It is used while it is
+	// healthy, regardless of latency.
+	PreferredRegionID *uuid.UUID `json:"preferred_region_id,omitempty" format:"uuid"`
+	// Latencies are the latencies last reported by the user's client.
+	Latencies []RegionLatency `json:"latencies"`
+	// SelectedRegionID is the region to use.
+	SelectedRegionID uuid.UUID            `json:"selected_region_id" format:"uuid"`
+	Reason           ProxySelectionReason `json:"reason" enums:"preference,latency,health"`
+}
+
+type UpdateUserProxyPreferenceRequest struct {
+	// PreferredRegionID pins a region. Null selects the region automatically.
+	PreferredRegionID *uuid.UUID `json:"preferred_region_id" format:"uuid"`
+}
+
+// RegionProbePlan returns the regions the client should measure its latency
+// to.
+func (c *Client) RegionProbePlan(ctx context.Context) (RegionProbePlan, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/regions/probe-plan", nil)
+	if err != nil {
+		return RegionProbePlan{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return RegionProbePlan{}, ReadBodyAsError(res)
+	}
+	var plan RegionProbePlan
+	return plan, json.NewDecoder(res.Body).Decode(&plan)
+}
+
+// UserProxyPreference returns the region selected for the user.
+func (c *Client) UserProxyPreference(ctx context.Context, user string) (UserProxyPreference, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/proxy-preference", user), nil)
+	if err != nil {
+		return UserProxyPreference{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserProxyPreference{}, ReadBodyAsError(res)
+	}
+	var resp UserProxyPreference
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UpdateUserProxyPreference pins or unpins the region for the user.
+func (c *Client) UpdateUserProxyPreference(ctx context.Context, user string, req UpdateUserProxyPreferenceRequest) (UserProxyPreference, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/proxy-preference", user), req)
+	if err != nil {
+		return UserProxyPreference{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserProxyPreference{}, ReadBodyAsError(res)
+	}
+	var resp UserProxyPreference
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// ReportRegionLatencies stores the latencies the client measured with a
+// RegionProbePlan, replacing the previous report.
+func (c *Client) ReportRegionLatencies(ctx context.Context, user string, req ReportRegionLatenciesRequest) (UserProxyPreference, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/proxy-preference/latencies", user), req)
+	if err != nil {
+		return UserProxyPreference{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserProxyPreference{}, ReadBodyAsError(res)
+	}
+	var resp UserProxyPreference
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// ProbeRegions measures the latency to every region of the plan. Regions that
+// could not be reached are left out.
+func ProbeRegions(ctx context.Context, client *http.Client, plan RegionProbePlan) []RegionLatency {
+	samples := max(plan.Samples, 1)
+	timeout := time.Duration(plan.TimeoutMS) * time.Millisecond
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		latencies = make([]RegionLatency, 0, len(plan.Probes))
+	)
+	for _, probe := range plan.Probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var best time.Duration
+			for range samples {
+				latency, err := probeRegion(ctx, client, probe.URL, timeout)
+				if err != nil {
+					continue
+				}
+				if best == 0 || latency < best {
+					best = latency
+				}
+			}
+			if best == 0 {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			latencies = append(latencies, RegionLatency{
+				RegionID:  probe.RegionID,
+				LatencyMS: float64(best) / float64(time.Millisecond),
+			})
+		}()
+	}
+	wg.Wait()
+	slices.SortFunc(latencies, func(a, b RegionLatency) int {
+		return cmp.Compare(a.LatencyMS, b.LatencyMS)
+	})
+	return latencies
+}
+
+func probeRegion(ctx context.Context, client *http.Client, url string, timeout time.Duration) (time.Duration, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return 0, xerrors.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return max(time.Since(start), time.Nanosecond), nil
+}
@@ -0,0 +1,41 @@
+package codersdk_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestProbeRegions(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(up.Close)
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(broken.Close)
+
+	upID := uuid.New()
+	latencies := codersdk.ProbeRegions(ctx, http.DefaultClient, codersdk.RegionProbePlan{
+		Probes: []codersdk.RegionProbe{
+			{RegionID: upID, RegionName: "up", URL: up.URL + "/latency-check"},
+			{RegionID: uuid.New(), RegionName: "broken", URL: broken.URL + "/latency-check"},
+		},
+		Samples:   2,
+		TimeoutMS: testutil.WaitShort.Milliseconds(),
+	})
+	require.Len(t, latencies, 1)
+	require.Equal(t, upID, latencies[0].RegionID)
+	require.Positive(t, latencies[0].LatencyMS)
+	require.True(t, latencies[0].MeasuredAt.IsZero())
+}
@@ -0,0 +1,66 @@
+package codersdk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+
+	"github.com/hashicorp/yamux"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk/drpcsdk"
+	"github.com/coder/coder/v2/publicapi/proto"
+	"github.com/coder/websocket"
+)
+
+// ConnectPublicAPI returns a client of the public DRPC API, which streams
+// workspaces, builds, agents and agent stats instead of paginating them. The
+// context is during dial, not during the lifetime of the client. Callers
+// should close the client's connection with DRPCConn().Close() after use.
+func (c *Client) ConnectPublicAPI(ctx context.Context) (proto.DRPCPublicAPIClient, error) {
+	rpcURL, err := c.URL.Parse("/api/v2/rpc")
+	if err != nil {
+		return nil, xerrors.Errorf("parse url: %w", err)
+	}
+	q := rpcURL.Query()
+	q.Add("version", proto.CurrentVersion.String())
+	rpcURL.RawQuery = q.Encode()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, xerrors.Errorf("create cookie jar: %w", err)
+	}
+	jar.SetCookies(rpcURL, []*http.Cookie{{
+		Name:  SessionTokenCookie,
+		Value: c.SessionToken(),
+	}})
+	httpClient := &http.Client{
+		Jar:       jar,
+		Transport: c.HTTPClient.Transport,
+	}
+	// nolint:bodyclose
+	conn, res, err := websocket.Dial(ctx, rpcURL.String(), &websocket.DialOptions{
+		HTTPClient: httpClient,
+	})
+	if err != nil {
+		if res == nil {
+			return nil, err
+		}
+		return nil, ReadBodyAsError(res)
+	}
+	// Set the read limit to 4 MiB -- about the limit for protobufs.
+	conn.SetReadLimit(1 << 22)
+
+	config := yamux.DefaultConfig()
+	config.LogOutput = io.Discard
+	// Use background context because caller should close the client.
+	_, wsNetConn := WebsocketNetConn(context.Background(), conn, websocket.MessageBinary)
+	session, err := yamux.Client(wsNetConn, config)
+	if err != nil {
+		_ = conn.Close(websocket.StatusGoingAway, "")
+		_ = wsNetConn.Close()
+		return nil, xerrors.Errorf("multiplex client: %w", err)
+	}
+	return proto.NewDRPCPublicAPIClient(drpcsdk.MultiplexedConn(session)), nil
+}
@@ -42,3 +42,44 @@ func (c *Client) Replicas(ctx context.Context) ([]Replica, error) {
 	var replicas []Replica
 	return replicas, json.NewDecoder(res.Body).Decode(&replicas)
 }
+
+// DefaultReplicaDrainTimeout is how long replicas drain before they shut down
+// when no drain timeout is requested.
+const DefaultReplicaDrainTimeout = 15 * time.Second
+
+type RestartReplicasRequest struct {
+	// Rolling restarts replicas one at a time. Each replica waits until the
+	// replicas before it have been replaced by healthy replicas. Otherwise
+	// all replicas restart at once.
+	Rolling bool `json:"rolling"`
+	// DrainTimeout is how long each replica reports itself as unhealthy on
+	// /healthz before it shuts down, so that load balancers stop sending it
+	// requests. Defaults to DefaultReplicaDrainTimeout.
+	DrainTimeout time.Duration `json:"drain_timeout"`
+}
+
+// ReplicasRestart is a restart of every healthy replica.
+type ReplicasRestart struct {
+	ID uuid.UUID `json:"id" format:"uuid"`
+	// Replicas are the IDs of the replicas that are restarted, in the order
+	// they restart in for rolling restarts.
+	Replicas []uuid.UUID `json:"replicas" format:"uuid"`
+	Rolling  bool        `json:"rolling"`
+}
+
+// RestartReplicas restarts every healthy replica. Replicas drain and shut
+// down, and are expected to be started again by their supervisor.
+func (c *Client) RestartReplicas(ctx context.Context, req RestartReplicasRequest) (ReplicasRestart, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/replicas/restart", req)
+	if err != nil {
+		return ReplicasRestart{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusAccepted {
+		return ReplicasRestart{}, ReadBodyAsError(res)
+	}
+
+	var restart ReplicasRestart
+	return restart, json.NewDecoder(res.Body).Decode(&restart)
+}
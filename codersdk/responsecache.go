@@ -0,0 +1,169 @@
+package codersdk
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// DefaultResponseCacheEntries is the number of responses a ResponseCache
+	// keeps when created with a non-positive size.
+	DefaultResponseCacheEntries = 256
+	// maxCachedResponseBytes is the largest response body that is cached.
+	maxCachedResponseBytes = 4 << 20
+)
+
+// ResponseCache caches the responses of GET requests that have an entity tag,
+// like template versions, users and the deployment config. A client with a
+// cache sends the tag of a cached response in the If-None-Match header, and
+// returns the cached response when the server replies 304 Not Modified, so
+// clients that poll, like IDE plugins, only download resources that changed.
+// Callers see a 200 OK response either way.
+// @typescript-ignore ResponseCache
+type ResponseCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	// lru holds *cachedResponse, most recently used first.
+	lru *list.List
+}
+
+type cachedResponse struct {
+	key    string
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+// NewResponseCache creates a cache of up to maxEntries responses. The least
+// recently used responses are evicted first.
+func NewResponseCache(maxEntries int) *ResponseCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultResponseCacheEntries
+	}
+	return &ResponseCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// prepare adds the If-None-Match header to the request if a response to it
+// is cached, and returns the key to pass to handle. Responses are cached per
+// session token, since they depend on the user. It returns an empty key for
+// requests that are not cached.
+func (c *ResponseCache) prepare(req *http.Request, sessionToken string) string {
+	if c == nil || req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" {
+		return ""
+	}
+	key := sessionToken + " " + req.URL.String()
+	if entry := c.get(key); entry != nil {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+	return key
+}
+
+// handle stores cacheable responses, and replaces 304 Not Modified responses
+// with the cached response.
+func (c *ResponseCache) handle(key string, resp *http.Response) (*http.Response, error) {
+	if key == "" {
+		return resp, nil
+	}
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		entry := c.get(key)
+		if entry == nil {
+			// The response was evicted since the request was prepared.
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+		cached := *resp
+		cached.StatusCode = http.StatusOK
+		cached.Status = "200 OK"
+		cached.Header = entry.header.Clone()
+		cached.Body = io.NopCloser(bytes.NewReader(entry.body))
+		cached.ContentLength = int64(len(entry.body))
+		return &cached, nil
+	case http.StatusOK:
+		etag := resp.Header.Get("ETag")
+		if etag == "" || strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+			return resp, nil
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedResponseBytes+1))
+		if err != nil {
+			_ = resp.Body.Close()
+			return nil, xerrors.Errorf("read response body: %w", err)
+		}
+		if len(body) > maxCachedResponseBytes {
+			// Too large to cache, hand the rest of the body to the caller.
+			resp.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		c.put(&cachedResponse{
+			key:    key,
+			etag:   etag,
+			header: resp.Header.Clone(),
+			body:   body,
+		})
+		return resp, nil
+	case http.StatusNotFound, http.StatusGone:
+		c.delete(key)
+	}
+	return resp, nil
+}
+
+func (c *ResponseCache) get(key string) *cachedResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(elem)
+	//nolint:forcetypeassert // Only *cachedResponse is stored.
+	return elem.Value.(*cachedResponse)
+}
+
+func (c *ResponseCache) put(entry *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[entry.key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		//nolint:forcetypeassert // Only *cachedResponse is stored.
+		delete(c.entries, oldest.Value.(*cachedResponse).key)
+	}
+}
+
+func (c *ResponseCache) delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of cached responses.
+func (c *ResponseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
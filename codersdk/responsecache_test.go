@@ -0,0 +1,103 @@
+package codersdk_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestResponseCache(t *testing.T) {
+	t.Parallel()
+
+	var (
+		body         atomic.Pointer[string]
+		fullResponse atomic.Int64
+	)
+	one := `{"name":"one"}`
+	body.Store(&one)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		current := *body.Load()
+		etag := `"` + current + `"`
+		if r.URL.Path == "/untagged" {
+			etag = ""
+		}
+		if etag != "" {
+			rw.Header().Set("ETag", etag)
+			if r.Header.Get("If-None-Match") == etag {
+				rw.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		fullResponse.Add(1)
+		_, _ = rw.Write([]byte(current))
+	}))
+	t.Cleanup(srv.Close)
+	serverURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	client := codersdk.New(serverURL)
+	client.ResponseCache = codersdk.NewResponseCache(0)
+	get := func(path string) string {
+		t.Helper()
+		res, err := client.Request(ctx, http.MethodGet, path, nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var out []byte
+		out, err = io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return string(out)
+	}
+
+	require.Equal(t, `{"name":"one"}`, get("/tagged"))
+	require.Equal(t, `{"name":"one"}`, get("/tagged"))
+	require.EqualValues(t, 1, fullResponse.Load(), "the second response is revalidated")
+
+	two := `{"name":"two"}`
+	body.Store(&two)
+	require.Equal(t, `{"name":"two"}`, get("/tagged"))
+	require.EqualValues(t, 2, fullResponse.Load())
+
+	// Responses without a tag are not cached.
+	get("/untagged")
+	get("/untagged")
+	require.EqualValues(t, 4, fullResponse.Load())
+	require.Equal(t, 1, client.ResponseCache.Len())
+
+	// Responses are cached per session token.
+	client.SetSessionToken("other")
+	get("/tagged")
+	require.EqualValues(t, 5, fullResponse.Load())
+	require.Equal(t, 2, client.ResponseCache.Len())
+}
+
+func TestResponseCacheEviction(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("ETag", `"tag"`)
+		_, _ = rw.Write([]byte(r.URL.Path))
+	}))
+	t.Cleanup(srv.Close)
+	serverURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	client := codersdk.New(serverURL)
+	client.ResponseCache = codersdk.NewResponseCache(2)
+	for _, path := range []string{"/a", "/b", "/c"} {
+		res, err := client.Request(ctx, http.MethodGet, path, nil)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+	}
+	require.Equal(t, 2, client.ResponseCache.Len())
+}
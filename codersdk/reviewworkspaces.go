@@ -0,0 +1,97 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// ReviewWorkspace is a workspace created by CI for a branch of a repository,
+// e.g. to review a pull request. It is deleted when the branch is deleted.
+type ReviewWorkspace struct {
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	// Repository is the lowercased full name of the repository, e.g.
+	// "coder/coder".
+	Repository         string          `json:"repository"`
+	Branch             string          `json:"branch"`
+	WorkspaceID        uuid.UUID       `json:"workspace_id" format:"uuid"`
+	WorkspaceName      string          `json:"workspace_name"`
+	WorkspaceOwnerName string          `json:"workspace_owner_name"`
+	LatestBuildID      uuid.UUID       `json:"latest_build_id" format:"uuid"`
+	Status             WorkspaceStatus `json:"status" enums:"pending,starting,running,stopping,stopped,failed,canceling,canceled,deleting,deleted"`
+	// StatusURL links to the workspace in the dashboard. It is suitable as
+	// the target URL of a pull request check.
+	StatusURL string    `json:"status_url"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt time.Time `json:"updated_at" format:"date-time"`
+}
+
+// UpsertReviewWorkspaceRequest creates the review workspace of a branch, or
+// rebuilds it with the given template version and parameters if it already
+// exists.
+type UpsertReviewWorkspaceRequest struct {
+	Repository string `json:"repository" validate:"required"`
+	Branch     string `json:"branch" validate:"required"`
+	// TemplateID specifies which template should be used for creating the workspace.
+	TemplateID uuid.UUID `json:"template_id,omitempty" validate:"required_without=TemplateVersionID,excluded_with=TemplateVersionID" format:"uuid"`
+	// TemplateVersionID can be used to specify a specific version of a template for creating the workspace.
+	TemplateVersionID       uuid.UUID                 `json:"template_version_id,omitempty" validate:"required_without=TemplateID,excluded_with=TemplateID" format:"uuid"`
+	TemplateVersionPresetID uuid.UUID                 `json:"template_version_preset_id,omitempty" format:"uuid"`
+	RichParameterValues     []WorkspaceBuildParameter `json:"rich_parameter_values,omitempty"`
+}
+
+// UpsertReviewWorkspace creates or rebuilds the review workspace of a branch.
+func (c *Client) UpsertReviewWorkspace(ctx context.Context, organizationID uuid.UUID, req UpsertReviewWorkspaceRequest) (ReviewWorkspace, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/organizations/%s/review-workspaces", organizationID), req)
+	if err != nil {
+		return ReviewWorkspace{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReviewWorkspace{}, ReadBodyAsError(res)
+	}
+	var reviewWorkspace ReviewWorkspace
+	return reviewWorkspace, json.NewDecoder(res.Body).Decode(&reviewWorkspace)
+}
+
+// ReviewWorkspace returns the review workspace of a branch.
+func (c *Client) ReviewWorkspace(ctx context.Context, organizationID uuid.UUID, repository, branch string) (ReviewWorkspace, error) {
+	res, err := c.Request(ctx, http.MethodGet, reviewWorkspaceURL(organizationID, repository, branch), nil)
+	if err != nil {
+		return ReviewWorkspace{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReviewWorkspace{}, ReadBodyAsError(res)
+	}
+	var reviewWorkspace ReviewWorkspace
+	return reviewWorkspace, json.NewDecoder(res.Body).Decode(&reviewWorkspace)
+}
+
+// DeleteReviewWorkspace starts a build that deletes the review workspace of a
+// branch.
+func (c *Client) DeleteReviewWorkspace(ctx context.Context, organizationID uuid.UUID, repository, branch string) (ReviewWorkspace, error) {
+	res, err := c.Request(ctx, http.MethodDelete, reviewWorkspaceURL(organizationID, repository, branch), nil)
+	if err != nil {
+		return ReviewWorkspace{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReviewWorkspace{}, ReadBodyAsError(res)
+	}
+	var reviewWorkspace ReviewWorkspace
+	return reviewWorkspace, json.NewDecoder(res.Body).Decode(&reviewWorkspace)
+}
+
+func reviewWorkspaceURL(organizationID uuid.UUID, repository, branch string) string {
+	qp := url.Values{}
+	qp.Set("repository", repository)
+	qp.Set("branch", branch)
+	return fmt.Sprintf("/api/v2/organizations/%s/review-workspaces?%s", organizationID, qp.Encode())
+}
@@ -3,6 +3,7 @@ package codersdk
 import (
 	"encoding/json"
 
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 	"tailscale.com/types/ptr"
 
@@ -226,3 +227,36 @@ func (r *ParameterResolver) findLastValue(p TemplateVersionParameter) *Workspace
 	}
 	return nil
 }
+
+// ParameterValidationRequest is sent to the parameter validation webhook of a
+// template when a workspace is created or its parameter values change.
+type ParameterValidationRequest struct {
+	OrganizationID    uuid.UUID                 `json:"organization_id" format:"uuid"`
+	TemplateID        uuid.UUID                 `json:"template_id" format:"uuid"`
+	TemplateName      string                    `json:"template_name"`
+	TemplateVersionID uuid.UUID                 `json:"template_version_id" format:"uuid"`
+	WorkspaceID       uuid.UUID                 `json:"workspace_id" format:"uuid"`
+	WorkspaceName     string                    `json:"workspace_name"`
+	Owner             ParameterValidationUser   `json:"owner"`
+	Initiator         ParameterValidationUser   `json:"initiator"`
+	Parameters        []WorkspaceBuildParameter `json:"parameters"`
+}
+
+// ParameterValidationUser identifies a user to a parameter validation webhook.
+type ParameterValidationUser struct {
+	ID       uuid.UUID `json:"id" format:"uuid"`
+	Username string    `json:"username"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+}
+
+// ParameterValidationResponse is returned by a parameter validation webhook.
+type ParameterValidationResponse struct {
+	// Valid is true if the parameter values are accepted.
+	Valid bool `json:"valid"`
+	// Message is shown to the user when the parameter values are rejected.
+	Message string `json:"message,omitempty"`
+	// Validations optionally explain which parameters were rejected. The field
+	// of each validation is the name of a parameter.
+	Validations []ValidationError `json:"validations,omitempty"`
+}
@@ -128,6 +128,38 @@ func (c *Client) UpdateOrganizationRole(ctx context.Context, role Role) (Role, e
 	return r, json.NewDecoder(res.Body).Decode(&r)
 }
 
+// SimulateCustomRoleRequest checks whether a custom organization role would
+// allow an action on a resource of the organization, without saving the role.
+type SimulateCustomRoleRequest struct {
+	Role         CustomRoleRequest `json:"role"`
+	ResourceType RBACResource      `json:"resource_type"`
+	Action       RBACAction        `json:"action"`
+}
+
+type SimulateCustomRoleResponse struct {
+	Allowed bool `json:"allowed"`
+	// MatchedPermission is the permission of the role that decided the result.
+	// It is omitted when no permission of the role applies, in which case the
+	// action is denied.
+	MatchedPermission *Permission `json:"matched_permission,omitempty"`
+}
+
+// SimulateOrganizationRole checks what a custom organization role would allow
+// before it is created or updated.
+func (c *Client) SimulateOrganizationRole(ctx context.Context, organizationID uuid.UUID, req SimulateCustomRoleRequest) (SimulateCustomRoleResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		fmt.Sprintf("/api/v2/organizations/%s/members/roles/simulate", organizationID.String()), req)
+	if err != nil {
+		return SimulateCustomRoleResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return SimulateCustomRoleResponse{}, ReadBodyAsError(res)
+	}
+	var r SimulateCustomRoleResponse
+	return r, json.NewDecoder(res.Body).Decode(&r)
+}
+
 // DeleteOrganizationRole will delete a custom organization role
 func (c *Client) DeleteOrganizationRole(ctx context.Context, organizationID uuid.UUID, roleName string) error {
 	res, err := c.Request(ctx, http.MethodDelete,
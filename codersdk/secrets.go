@@ -0,0 +1,177 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// UserSecret is a secret owned by a user. Secret values are write-only and
+// are never returned by the API; they are only made available to the
+// user's workspace agents.
+type UserSecret struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	UserID      uuid.UUID `json:"user_id" format:"uuid"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	// EnvName is the environment variable the secret is exposed as inside
+	// the user's workspaces. Secrets without an EnvName are not exposed.
+	EnvName   string    `json:"env_name"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt time.Time `json:"updated_at" format:"date-time"`
+}
+
+type CreateUserSecretRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description,omitempty"`
+	Value       string `json:"value" validate:"required"`
+	EnvName     string `json:"env_name,omitempty"`
+}
+
+// UpdateUserSecretRequest updates a user secret. Fields that are nil are
+// left unchanged.
+type UpdateUserSecretRequest struct {
+	Description *string `json:"description,omitempty"`
+	Value       *string `json:"value,omitempty"`
+	EnvName     *string `json:"env_name,omitempty"`
+}
+
+// TemplateSecret is a secret attached to a template. Its value is passed to
+// workspace builds as the Terraform variable of the same name. Secret values
+// are write-only and are never returned by the API.
+type TemplateSecret struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	TemplateID  uuid.UUID `json:"template_id" format:"uuid"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt   time.Time `json:"updated_at" format:"date-time"`
+}
+
+type CreateTemplateSecretRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description,omitempty"`
+	Value       string `json:"value" validate:"required"`
+}
+
+// UpdateTemplateSecretRequest updates a template secret. Fields that are nil
+// are left unchanged.
+type UpdateTemplateSecretRequest struct {
+	Description *string `json:"description,omitempty"`
+	Value       *string `json:"value,omitempty"`
+}
+
+// UserSecrets returns the secrets owned by a user.
+func (c *Client) UserSecrets(ctx context.Context, user string) ([]UserSecret, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/secrets", user), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var secrets []UserSecret
+	return secrets, json.NewDecoder(res.Body).Decode(&secrets)
+}
+
+// CreateUserSecret creates a secret owned by a user.
+func (c *Client) CreateUserSecret(ctx context.Context, user string, req CreateUserSecretRequest) (UserSecret, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/secrets", user), req)
+	if err != nil {
+		return UserSecret{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return UserSecret{}, ReadBodyAsError(res)
+	}
+	var secret UserSecret
+	return secret, json.NewDecoder(res.Body).Decode(&secret)
+}
+
+// UpdateUserSecret updates a secret owned by a user.
+func (c *Client) UpdateUserSecret(ctx context.Context, user string, name string, req UpdateUserSecretRequest) (UserSecret, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/users/%s/secrets/%s", user, name), req)
+	if err != nil {
+		return UserSecret{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserSecret{}, ReadBodyAsError(res)
+	}
+	var secret UserSecret
+	return secret, json.NewDecoder(res.Body).Decode(&secret)
+}
+
+// DeleteUserSecret deletes a secret owned by a user.
+func (c *Client) DeleteUserSecret(ctx context.Context, user string, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/secrets/%s", user, name), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// TemplateSecrets returns the secrets attached to a template.
+func (c *Client) TemplateSecrets(ctx context.Context, template uuid.UUID) ([]TemplateSecret, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/secrets", template), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var secrets []TemplateSecret
+	return secrets, json.NewDecoder(res.Body).Decode(&secrets)
+}
+
+// CreateTemplateSecret attaches a secret to a template.
+func (c *Client) CreateTemplateSecret(ctx context.Context, template uuid.UUID, req CreateTemplateSecretRequest) (TemplateSecret, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/secrets", template), req)
+	if err != nil {
+		return TemplateSecret{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TemplateSecret{}, ReadBodyAsError(res)
+	}
+	var secret TemplateSecret
+	return secret, json.NewDecoder(res.Body).Decode(&secret)
+}
+
+// UpdateTemplateSecret updates a secret attached to a template.
+func (c *Client) UpdateTemplateSecret(ctx context.Context, template uuid.UUID, name string, req UpdateTemplateSecretRequest) (TemplateSecret, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/templates/%s/secrets/%s", template, name), req)
+	if err != nil {
+		return TemplateSecret{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateSecret{}, ReadBodyAsError(res)
+	}
+	var secret TemplateSecret
+	return secret, json.NewDecoder(res.Body).Decode(&secret)
+}
+
+// DeleteTemplateSecret removes a secret from a template.
+func (c *Client) DeleteTemplateSecret(ctx context.Context, template uuid.UUID, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/secrets/%s", template, name), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
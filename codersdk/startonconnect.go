@@ -0,0 +1,151 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/xerrors"
+)
+
+// ErrStartOnConnectDisabled is returned when a stopped workspace is not
+// started on connect because the user or the template disabled it.
+var ErrStartOnConnectDisabled = xerrors.New("starting workspaces on connect is disabled")
+
+// UserConnectionSettings are the settings of a user that apply to clients
+// connecting to their workspaces, like coder ssh and IDE extensions.
+type UserConnectionSettings struct {
+	// StartWorkspaceOnConnect lets clients start stopped workspaces of the
+	// user when connecting to them. The template of the workspace must allow
+	// it too.
+	StartWorkspaceOnConnect bool `json:"start_workspace_on_connect"`
+}
+
+// UserConnectionSettings returns the connection settings of a user.
+func (c *Client) UserConnectionSettings(ctx context.Context, user string) (UserConnectionSettings, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/connection-settings", user), nil)
+	if err != nil {
+		return UserConnectionSettings{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserConnectionSettings{}, ReadBodyAsError(res)
+	}
+	var resp UserConnectionSettings
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UpdateUserConnectionSettings updates the connection settings of a user.
+func (c *Client) UpdateUserConnectionSettings(ctx context.Context, user string, req UserConnectionSettings) (UserConnectionSettings, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/connection-settings", user), req)
+	if err != nil {
+		return UserConnectionSettings{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserConnectionSettings{}, ReadBodyAsError(res)
+	}
+	var resp UserConnectionSettings
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// CheckStartOnConnect returns an error wrapping ErrStartOnConnectDisabled if
+// the template of the workspace or the connection settings of the calling
+// user do not allow starting the workspace on connect.
+func (c *Client) CheckStartOnConnect(ctx context.Context, workspace Workspace) error {
+	template, err := c.Template(ctx, workspace.TemplateID)
+	if err != nil {
+		return xerrors.Errorf("get template: %w", err)
+	}
+	if !template.AllowStartOnConnect {
+		return xerrors.Errorf("template %q: %w", template.Name, ErrStartOnConnectDisabled)
+	}
+	settings, err := c.UserConnectionSettings(ctx, Me)
+	if err != nil {
+		return xerrors.Errorf("get connection settings: %w", err)
+	}
+	if !settings.StartWorkspaceOnConnect {
+		return xerrors.Errorf("user connection settings: %w", ErrStartOnConnectDisabled)
+	}
+	return nil
+}
+
+// StartOnConnectOptions configure StartWorkspaceOnConnect.
+// @typescript-ignore StartOnConnectOptions
+type StartOnConnectOptions struct {
+	// Logs receives the logs of the start build while it runs.
+	Logs func(ProvisionerJobLog)
+}
+
+// StartWorkspaceOnConnect prepares a workspace for a connection. A stopped
+// workspace is started if CheckStartOnConnect allows it, with the template
+// version of its last build, or the active version if the template requires
+// it. It then waits for the latest build to complete and returns the updated
+// workspace. Running workspaces are returned as is.
+func (c *Client) StartWorkspaceOnConnect(ctx context.Context, workspace Workspace, opts StartOnConnectOptions) (Workspace, error) {
+	if workspace.LatestBuild.Transition != WorkspaceTransitionStart {
+		switch {
+		case workspace.LatestBuild.Transition == WorkspaceTransitionDelete:
+			return Workspace{}, xerrors.Errorf("workspace %q is deleted", workspace.Name)
+		case workspace.LatestBuild.Status != WorkspaceStatusStopped:
+			return Workspace{}, xerrors.Errorf("workspace %q must be stopped to be started, but it is %q", workspace.Name, workspace.LatestBuild.Status)
+		}
+		if err := c.CheckStartOnConnect(ctx, workspace); err != nil {
+			return Workspace{}, err
+		}
+
+		_, err := c.CreateWorkspaceBuild(ctx, workspace.ID, CreateWorkspaceBuildRequest{
+			Transition:        WorkspaceTransitionStart,
+			TemplateVersionID: workspace.LatestBuild.TemplateVersionID,
+		})
+		if cerr, ok := AsError(err); ok && cerr.StatusCode() == http.StatusForbidden {
+			// The template requires the active version.
+			_, err = c.CreateWorkspaceBuild(ctx, workspace.ID, CreateWorkspaceBuildRequest{
+				Transition:        WorkspaceTransitionStart,
+				TemplateVersionID: workspace.TemplateActiveVersionID,
+			})
+		}
+		if err != nil {
+			return Workspace{}, xerrors.Errorf("create workspace build: %w", err)
+		}
+		workspace, err = c.Workspace(ctx, workspace.ID)
+		if err != nil {
+			return Workspace{}, xerrors.Errorf("get workspace: %w", err)
+		}
+	}
+	if workspace.LatestBuild.Job.CompletedAt != nil {
+		return workspace, nil
+	}
+
+	logs, closer, err := c.WorkspaceBuildLogsAfter(ctx, workspace.LatestBuild.ID, 0)
+	if err != nil {
+		return Workspace{}, xerrors.Errorf("get build logs: %w", err)
+	}
+	defer closer.Close()
+	// The logs are closed once the build completes.
+	for log := range logs {
+		if opts.Logs != nil {
+			opts.Logs(log)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return Workspace{}, err
+	}
+
+	workspace, err = c.Workspace(ctx, workspace.ID)
+	if err != nil {
+		return Workspace{}, xerrors.Errorf("get workspace: %w", err)
+	}
+	switch workspace.LatestBuild.Job.Status {
+	case ProvisionerJobSucceeded:
+	case ProvisionerJobFailed:
+		return Workspace{}, xerrors.Errorf("start workspace %q: %s", workspace.Name, workspace.LatestBuild.Job.Error)
+	default:
+		return Workspace{}, xerrors.Errorf("start workspace %q: build is %s", workspace.Name, workspace.LatestBuild.Job.Status)
+	}
+	if workspace.LatestBuild.Transition != WorkspaceTransitionStart {
+		return Workspace{}, xerrors.Errorf("workspace %q transitioned to %q while starting", workspace.Name, workspace.LatestBuild.Transition)
+	}
+	return workspace, nil
+}
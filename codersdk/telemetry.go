@@ -0,0 +1,89 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// TelemetryCategory is a category of data that is reported by telemetry.
+type TelemetryCategory string
+
+const (
+	// TelemetryCategoryDeployment is information about the host running Coder
+	// and the deployment config, and external provisioners.
+	TelemetryCategoryDeployment TelemetryCategory = "deployment"
+	// TelemetryCategoryUsers is users, groups, organizations and API keys.
+	TelemetryCategoryUsers TelemetryCategory = "users"
+	// TelemetryCategoryTemplates is templates and template versions.
+	TelemetryCategoryTemplates TelemetryCategory = "templates"
+	// TelemetryCategoryWorkspaces is workspaces, their builds, resources,
+	// agents and apps, and provisioner jobs.
+	TelemetryCategoryWorkspaces TelemetryCategory = "workspaces"
+	// TelemetryCategoryUsage is workspace agent stats, CLI invocations and
+	// user connections to workspaces.
+	TelemetryCategoryUsage TelemetryCategory = "usage"
+	// TelemetryCategoryNetwork is network events and workspace proxies.
+	TelemetryCategoryNetwork TelemetryCategory = "network"
+	// TelemetryCategoryLicenses is licenses.
+	TelemetryCategoryLicenses TelemetryCategory = "licenses"
+)
+
+// TelemetryCategories returns all telemetry categories.
+func TelemetryCategories() []TelemetryCategory {
+	return []TelemetryCategory{
+		TelemetryCategoryDeployment,
+		TelemetryCategoryUsers,
+		TelemetryCategoryTemplates,
+		TelemetryCategoryWorkspaces,
+		TelemetryCategoryUsage,
+		TelemetryCategoryNetwork,
+		TelemetryCategoryLicenses,
+	}
+}
+
+type TelemetryPayloadKind string
+
+const (
+	TelemetryPayloadKindDeployment TelemetryPayloadKind = "deployment"
+	TelemetryPayloadKindSnapshot   TelemetryPayloadKind = "snapshot"
+)
+
+// TelemetryPayload is a payload that was sent to the telemetry endpoint.
+type TelemetryPayload struct {
+	Kind      TelemetryPayloadKind `json:"kind" enums:"deployment,snapshot"`
+	URL       string               `json:"url"`
+	CreatedAt time.Time            `json:"created_at" format:"date-time"`
+	// Data is the exact payload, as it was sent.
+	Data json.RawMessage `json:"data" swaggertype:"object"`
+	// StatusCode is the status code of the response. It is zero if the
+	// request failed.
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+}
+
+// TelemetryReport lists the telemetry payloads that were recently sent by the
+// replica that serves the request.
+type TelemetryReport struct {
+	Enabled bool   `json:"enabled"`
+	URL     string `json:"url"`
+	// Categories are the categories of data that are reported.
+	Categories []TelemetryCategory `json:"categories"`
+	// Payloads are the most recent payloads, newest first.
+	Payloads []TelemetryPayload `json:"payloads"`
+}
+
+// DeploymentTelemetry returns the telemetry payloads that were recently sent.
+func (c *Client) DeploymentTelemetry(ctx context.Context) (TelemetryReport, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/telemetry", nil)
+	if err != nil {
+		return TelemetryReport{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TelemetryReport{}, ReadBodyAsError(res)
+	}
+	var report TelemetryReport
+	return report, json.NewDecoder(res.Body).Decode(&report)
+}
@@ -0,0 +1,79 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateAppRestriction restricts a workspace app of a template to members
+// of some groups and users with some roles. Other users, including the owner
+// of the workspace, don't see the app in the workspace and can't connect to
+// it.
+type TemplateAppRestriction struct {
+	TemplateID uuid.UUID `json:"template_id" format:"uuid"`
+	AppSlug    string    `json:"app_slug"`
+	// GroupIDs are the groups whose members can access the app.
+	GroupIDs []uuid.UUID `json:"group_ids" format:"uuid"`
+	// Roles are the names of the site roles and roles of the template
+	// organization whose users can access the app.
+	Roles     []string  `json:"roles"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt time.Time `json:"updated_at" format:"date-time"`
+}
+
+// UpsertTemplateAppRestrictionRequest restricts a workspace app of a template
+// to members of the groups and users with the roles. At least one group or
+// role is required.
+type UpsertTemplateAppRestrictionRequest struct {
+	GroupIDs []uuid.UUID `json:"group_ids,omitempty" format:"uuid"`
+	Roles    []string    `json:"roles,omitempty"`
+}
+
+// TemplateAppRestrictions lists the restricted workspace apps of a template.
+func (c *Client) TemplateAppRestrictions(ctx context.Context, template uuid.UUID) ([]TemplateAppRestriction, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/app-restrictions", template), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var restrictions []TemplateAppRestriction
+	return restrictions, json.NewDecoder(res.Body).Decode(&restrictions)
+}
+
+// UpsertTemplateAppRestriction restricts a workspace app of a template, or
+// updates its restriction.
+func (c *Client) UpsertTemplateAppRestriction(ctx context.Context, template uuid.UUID, appSlug string, req UpsertTemplateAppRestrictionRequest) (TemplateAppRestriction, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/app-restrictions/%s", template, url.PathEscape(appSlug)), req)
+	if err != nil {
+		return TemplateAppRestriction{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateAppRestriction{}, ReadBodyAsError(res)
+	}
+	var restriction TemplateAppRestriction
+	return restriction, json.NewDecoder(res.Body).Decode(&restriction)
+}
+
+// DeleteTemplateAppRestriction deletes the restriction of a workspace app of
+// a template. The app is accessible according to its sharing level again.
+func (c *Client) DeleteTemplateAppRestriction(ctx context.Context, template uuid.UUID, appSlug string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/app-restrictions/%s", template, url.PathEscape(appSlug)), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
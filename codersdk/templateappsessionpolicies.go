@@ -0,0 +1,77 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateAppSessionPolicy limits how long after authenticating users can
+// access the workspace apps of a template. Once the time is up, users must
+// sign in again to keep using the apps.
+type TemplateAppSessionPolicy struct {
+	TemplateID uuid.UUID `json:"template_id" format:"uuid"`
+	// AppSlug is the app the policy applies to. The policy without an app
+	// slug applies to all apps, ports and terminals of the template without
+	// their own policy.
+	AppSlug                  string    `json:"app_slug,omitempty"`
+	MaxSessionDurationMillis int64     `json:"max_session_duration_ms"`
+	CreatedAt                time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt                time.Time `json:"updated_at" format:"date-time"`
+}
+
+// UpsertTemplateAppSessionPolicyRequest limits how long after authenticating
+// users can access an app of a template, or all of its apps if the app slug
+// is empty.
+type UpsertTemplateAppSessionPolicyRequest struct {
+	AppSlug                  string `json:"app_slug,omitempty"`
+	MaxSessionDurationMillis int64  `json:"max_session_duration_ms"`
+}
+
+// TemplateAppSessionPolicies lists the app session policies of a template.
+func (c *Client) TemplateAppSessionPolicies(ctx context.Context, template uuid.UUID) ([]TemplateAppSessionPolicy, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/app-session-policies", template), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var policies []TemplateAppSessionPolicy
+	return policies, json.NewDecoder(res.Body).Decode(&policies)
+}
+
+// UpsertTemplateAppSessionPolicy creates or updates an app session policy of
+// a template.
+func (c *Client) UpsertTemplateAppSessionPolicy(ctx context.Context, template uuid.UUID, req UpsertTemplateAppSessionPolicyRequest) (TemplateAppSessionPolicy, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/app-session-policies", template), req)
+	if err != nil {
+		return TemplateAppSessionPolicy{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateAppSessionPolicy{}, ReadBodyAsError(res)
+	}
+	var policy TemplateAppSessionPolicy
+	return policy, json.NewDecoder(res.Body).Decode(&policy)
+}
+
+// DeleteTemplateAppSessionPolicy deletes the session policy of an app of a
+// template, or the policy for all of its apps if the app slug is empty.
+func (c *Client) DeleteTemplateAppSessionPolicy(ctx context.Context, template uuid.UUID, appSlug string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/app-session-policies?app_slug=%s", template, url.QueryEscape(appSlug)), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateBundle is a self-contained template archive, including the modules
+// and providers the template requires, stored in the template registry of an
+// organization. Bundles let deployments without internet access import
+// templates.
+type TemplateBundle struct {
+	ID             uuid.UUID                `json:"id" format:"uuid"`
+	OrganizationID uuid.UUID                `json:"organization_id" format:"uuid"`
+	Name           string                   `json:"name"`
+	Version        string                   `json:"version"`
+	Hash           string                   `json:"hash"`
+	Size           int64                    `json:"size"`
+	Providers      []TemplateBundleProvider `json:"providers"`
+	Modules        []TemplateBundleModule   `json:"modules"`
+	CreatedBy      MinimalUser              `json:"created_by"`
+	CreatedAt      time.Time                `json:"created_at" format:"date-time"`
+}
+
+// TemplateBundleProvider is a provider mirrored in a template bundle.
+type TemplateBundleProvider struct {
+	// Source is the fully qualified address of the provider, e.g.
+	// registry.terraform.io/coder/coder.
+	Source    string   `json:"source"`
+	Version   string   `json:"version"`
+	Platforms []string `json:"platforms"`
+}
+
+// TemplateBundleModule is a module installed in a template bundle.
+type TemplateBundleModule struct {
+	Key     string `json:"key"`
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"`
+}
+
+// TemplateBundles lists the template bundles in the registry of an
+// organization.
+func (c *Client) TemplateBundles(ctx context.Context, organizationID uuid.UUID) ([]TemplateBundle, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/templatebundles", organizationID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var bundles []TemplateBundle
+	return bundles, json.NewDecoder(res.Body).Decode(&bundles)
+}
+
+// UploadTemplateBundle pushes a template bundle to the registry of an
+// organization. The name and version of the bundle are read from its
+// manifest.
+func (c *Client) UploadTemplateBundle(ctx context.Context, organizationID uuid.UUID, rd io.Reader) (TemplateBundle, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/templatebundles", organizationID), rd, func(r *http.Request) {
+		r.Header.Set("Content-Type", ContentTypeTar)
+	})
+	if err != nil {
+		return TemplateBundle{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TemplateBundle{}, ReadBodyAsError(res)
+	}
+	var bundle TemplateBundle
+	return bundle, json.NewDecoder(res.Body).Decode(&bundle)
+}
+
+// DownloadTemplateBundle pulls a template bundle from the registry of an
+// organization.
+func (c *Client) DownloadTemplateBundle(ctx context.Context, organizationID uuid.UUID, name, version string) ([]byte, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/templatebundles/%s/%s", organizationID, name, version), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	return io.ReadAll(res.Body)
+}
+
+// DeleteTemplateBundle deletes a template bundle from the registry of an
+// organization.
+func (c *Client) DeleteTemplateBundle(ctx context.Context, organizationID uuid.UUID, name, version string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/organizations/%s/templatebundles/%s/%s", organizationID, name, version), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
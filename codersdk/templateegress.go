@@ -0,0 +1,76 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// TemplateEgressPolicy lists the destinations the workspaces of a template
+// may connect to. While a template has an egress policy, workspace agents
+// block all other outbound connections from the workspace host.
+type TemplateEgressPolicy struct {
+	TemplateID uuid.UUID `json:"template_id" format:"uuid"`
+	// AllowedCIDRs are IP addresses and CIDR ranges, e.g. "10.0.0.0/8".
+	AllowedCIDRs []string `json:"allowed_cidrs"`
+	// AllowedDomains are domain names that agents resolve periodically to
+	// allow the addresses they point to, e.g. "github.com".
+	AllowedDomains []string  `json:"allowed_domains"`
+	CreatedAt      time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt      time.Time `json:"updated_at" format:"date-time"`
+}
+
+// UpdateTemplateEgressPolicyRequest replaces the egress policy of a template.
+type UpdateTemplateEgressPolicyRequest struct {
+	AllowedCIDRs   []string `json:"allowed_cidrs"`
+	AllowedDomains []string `json:"allowed_domains"`
+}
+
+// TemplateEgressPolicy returns the egress policy of a template. It returns a
+// not found error if the template does not restrict egress.
+func (c *Client) TemplateEgressPolicy(ctx context.Context, template uuid.UUID) (TemplateEgressPolicy, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/egress-policy", template), nil)
+	if err != nil {
+		return TemplateEgressPolicy{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateEgressPolicy{}, ReadBodyAsError(res)
+	}
+	var policy TemplateEgressPolicy
+	return policy, json.NewDecoder(res.Body).Decode(&policy)
+}
+
+// UpdateTemplateEgressPolicy sets the egress policy of a template. Running
+// workspaces pick up the change within a minute.
+func (c *Client) UpdateTemplateEgressPolicy(ctx context.Context, template uuid.UUID, req UpdateTemplateEgressPolicyRequest) (TemplateEgressPolicy, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/egress-policy", template), req)
+	if err != nil {
+		return TemplateEgressPolicy{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateEgressPolicy{}, ReadBodyAsError(res)
+	}
+	var policy TemplateEgressPolicy
+	return policy, json.NewDecoder(res.Body).Decode(&policy)
+}
+
+// DeleteTemplateEgressPolicy removes the egress policy of a template, which
+// lifts the egress restrictions of its workspaces.
+func (c *Client) DeleteTemplateEgressPolicy(ctx context.Context, template uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/egress-policy", template), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateParameterOptionSource is an API from which the options of a
+// template parameter are fetched when the parameter form is rendered, instead
+// of being defined in the template. Sources respond to GET requests with a
+// JSON array of options.
+type TemplateParameterOptionSource struct {
+	TemplateID    uuid.UUID `json:"template_id" format:"uuid"`
+	ParameterName string    `json:"parameter_name"`
+	URL           string    `json:"url"`
+	// AuthHeaderName is the name of the header coderd adds to requests to the
+	// source. The value of the header is never returned.
+	AuthHeaderName     string    `json:"auth_header_name,omitempty"`
+	HasAuthHeaderValue bool      `json:"has_auth_header_value"`
+	CacheTTLMillis     int64     `json:"cache_ttl_ms"`
+	CreatedAt          time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt          time.Time `json:"updated_at" format:"date-time"`
+}
+
+// UpsertTemplateParameterOptionSourceRequest configures the source of the
+// options of a template parameter.
+type UpsertTemplateParameterOptionSourceRequest struct {
+	URL            string `json:"url" validate:"required"`
+	AuthHeaderName string `json:"auth_header_name,omitempty"`
+	// AuthHeaderValue is the value of the auth header. If omitted when
+	// updating a source, the existing value is kept.
+	AuthHeaderValue *string `json:"auth_header_value,omitempty"`
+	// CacheTTLMillis is how long fetched options are cached. Defaults to 5
+	// minutes.
+	CacheTTLMillis int64 `json:"cache_ttl_ms,omitempty"`
+}
+
+// TemplateParameterOptions are the options of a template parameter fetched
+// from its source.
+type TemplateParameterOptions struct {
+	Options   []TemplateVersionParameterOption `json:"options"`
+	FetchedAt time.Time                        `json:"fetched_at" format:"date-time"`
+}
+
+// TemplateParameterOptionSources lists the option sources of the parameters
+// of a template.
+func (c *Client) TemplateParameterOptionSources(ctx context.Context, template uuid.UUID) ([]TemplateParameterOptionSource, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/parameter-option-sources", template), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var sources []TemplateParameterOptionSource
+	return sources, json.NewDecoder(res.Body).Decode(&sources)
+}
+
+// UpsertTemplateParameterOptionSource creates or updates the option source of
+// a template parameter.
+func (c *Client) UpsertTemplateParameterOptionSource(ctx context.Context, template uuid.UUID, parameterName string, req UpsertTemplateParameterOptionSourceRequest) (TemplateParameterOptionSource, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/parameter-option-sources/%s", template, url.PathEscape(parameterName)), req)
+	if err != nil {
+		return TemplateParameterOptionSource{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateParameterOptionSource{}, ReadBodyAsError(res)
+	}
+	var source TemplateParameterOptionSource
+	return source, json.NewDecoder(res.Body).Decode(&source)
+}
+
+// DeleteTemplateParameterOptionSource deletes the option source of a template
+// parameter. The parameter uses the options defined in the template again.
+func (c *Client) DeleteTemplateParameterOptionSource(ctx context.Context, template uuid.UUID, parameterName string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/parameter-option-sources/%s", template, url.PathEscape(parameterName)), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// TemplateParameterOptions fetches the options of a template parameter from
+// its source.
+func (c *Client) TemplateParameterOptions(ctx context.Context, template uuid.UUID, parameterName string) (TemplateParameterOptions, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/parameter-option-sources/%s/options", template, url.PathEscape(parameterName)), nil)
+	if err != nil {
+		return TemplateParameterOptions{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateParameterOptions{}, ReadBodyAsError(res)
+	}
+	var options TemplateParameterOptions
+	return options, json.NewDecoder(res.Body).Decode(&options)
+}
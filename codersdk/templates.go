@@ -63,6 +63,37 @@ type Template struct {
 	MaxPortShareLevel    WorkspaceAgentPortShareLevel `json:"max_port_share_level"`
 
 	UseClassicParameterFlow bool `json:"use_classic_parameter_flow"`
+
+	// WireguardMTU, WireguardKeepaliveIntervalMillis, and
+	// WireguardHandshakeTimeoutMillis override the deployment's WireGuard
+	// settings for workspaces created from this template. A value of 0 uses
+	// the deployment value.
+	WireguardMTU                     int32 `json:"wireguard_mtu"`
+	WireguardKeepaliveIntervalMillis int64 `json:"wireguard_keepalive_interval_ms"`
+	WireguardHandshakeTimeoutMillis  int64 `json:"wireguard_handshake_timeout_ms"`
+
+	// ActivationApprovalsRequired is the number of reviewers that must
+	// approve a template version before it becomes the active version. A
+	// value of 0 activates versions immediately.
+	ActivationApprovalsRequired int32 `json:"activation_approvals_required"`
+
+	// AutostartHolidays are dates formatted as YYYY-MM-DD on which
+	// workspaces are not autostarted, in addition to the deployment's
+	// holidays.
+	AutostartHolidays []string `json:"autostart_holidays"`
+	// AutostartHolidayCalendarURL is the URL of an iCalendar feed whose
+	// events are treated as autostart holidays.
+	AutostartHolidayCalendarURL string `json:"autostart_holiday_calendar_url"`
+	// DormantArchivePaths are the paths, relative to the home directory of
+	// the workspace agent unless absolute, that are archived before dormant
+	// workspaces are auto-deleted.
+	DormantArchivePaths []string `json:"dormant_archive_paths"`
+	// ParameterValidationURL is the URL of a webhook that validates the
+	// parameter values of workspace builds.
+	ParameterValidationURL string `json:"parameter_validation_url"`
+	// AllowStartOnConnect allows clients like coder ssh and IDE extensions to
+	// start stopped workspaces when connecting to them.
+	AllowStartOnConnect bool `json:"allow_start_on_connect"`
 }
 
 // WeekdaysToBitmap converts a list of weekdays to a bitmap in accordance with
@@ -258,6 +289,188 @@ type UpdateTemplateMeta struct {
 	// made the default.
 	// An "opt-out" is present in case the new feature breaks some existing templates.
 	UseClassicParameterFlow *bool `json:"use_classic_parameter_flow,omitempty"`
+	// WireguardMTU overrides the deployment WireGuard MTU for workspaces
+	// created from this template. It must be between 1280 and 65536. If
+	// passed 0, the deployment value is used.
+	WireguardMTU *int32 `json:"wireguard_mtu,omitempty"`
+	// WireguardKeepaliveIntervalMillis overrides the deployment WireGuard
+	// keepalive interval. If passed 0, the deployment value is used.
+	WireguardKeepaliveIntervalMillis *int64 `json:"wireguard_keepalive_interval_ms,omitempty"`
+	// WireguardHandshakeTimeoutMillis overrides the deployment WireGuard
+	// handshake timeout. If passed 0, the deployment value is used.
+	WireguardHandshakeTimeoutMillis *int64 `json:"wireguard_handshake_timeout_ms,omitempty"`
+	// ActivationApprovalsRequired is the number of reviewers that must
+	// approve a template version before it becomes the active version. If
+	// passed 0, versions are activated immediately.
+	ActivationApprovalsRequired *int32 `json:"activation_approvals_required,omitempty"`
+	// AutostartHolidays replaces the dates formatted as YYYY-MM-DD on which
+	// workspaces are not autostarted. If passed an empty list, the template
+	// holidays are cleared.
+	AutostartHolidays *[]string `json:"autostart_holidays,omitempty"`
+	// AutostartHolidayCalendarURL sets the URL of an iCalendar feed whose
+	// events are treated as autostart holidays. If passed an empty string,
+	// the calendar is removed.
+	AutostartHolidayCalendarURL *string `json:"autostart_holiday_calendar_url,omitempty"`
+	// DormantArchivePaths replaces the paths that are archived before dormant
+	// workspaces are auto-deleted. If passed an empty list, dormant
+	// workspaces are deleted without being archived.
+	DormantArchivePaths *[]string `json:"dormant_archive_paths,omitempty"`
+	// ParameterValidationURL sets the URL of a webhook that validates the
+	// parameter values of workspace builds. If passed an empty string, the
+	// webhook is removed.
+	ParameterValidationURL *string `json:"parameter_validation_url,omitempty"`
+	// AllowStartOnConnect allows clients like coder ssh and IDE extensions to
+	// start stopped workspaces when connecting to them.
+	AllowStartOnConnect *bool `json:"allow_start_on_connect,omitempty"`
+}
+
+// TransferTemplateRequest moves a template to another organization.
+type TransferTemplateRequest struct {
+	OrganizationID uuid.UUID `json:"organization_id" validate:"required" format:"uuid"`
+	// GroupMapping maps the groups in the template ACL to groups in the target
+	// organization. Groups that are not mapped are matched by name, and the
+	// "Everyone" group always maps to the "Everyone" group of the target
+	// organization.
+	GroupMapping map[string]uuid.UUID `json:"group_mapping,omitempty" example:"<source_group_id>:<target_group_id>"`
+	// ConfirmWorkspaceTransfer confirms that the workspaces of the template
+	// move with it, since workspaces are always in the organization of their
+	// template. It is required when the template has workspaces.
+	ConfirmWorkspaceTransfer bool `json:"confirm_workspace_transfer,omitempty"`
+}
+
+type TransferTemplateResponse struct {
+	TemplateID     uuid.UUID `json:"template_id" format:"uuid"`
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	// GroupMapping is the group each group in the template ACL was mapped to.
+	GroupMapping map[string]uuid.UUID `json:"group_mapping"`
+	// Workspaces is the number of workspaces that were moved.
+	Workspaces int `json:"workspaces"`
+}
+
+type TemplateVersionRolloutStatus string
+
+const (
+	TemplateVersionRolloutStatusInProgress      TemplateVersionRolloutStatus = "in_progress"
+	TemplateVersionRolloutStatusHalted          TemplateVersionRolloutStatus = "halted"
+	TemplateVersionRolloutStatusCompleted       TemplateVersionRolloutStatus = "completed"
+	TemplateVersionRolloutStatusCanceled        TemplateVersionRolloutStatus = "canceled"
+	TemplateVersionRolloutStatusPendingApproval TemplateVersionRolloutStatus = "pending_approval"
+)
+
+// TemplateVersionRollout is the gradual rollout of a template version to a
+// cohort of workspaces, before the version is made active for every
+// workspace.
+type TemplateVersionRollout struct {
+	TemplateID        uuid.UUID `json:"template_id" format:"uuid"`
+	TemplateVersionID uuid.UUID `json:"template_version_id" format:"uuid"`
+	CreatedByID       uuid.UUID `json:"created_by_id" format:"uuid"`
+	// Percentage of the workspaces of the template that are built with the
+	// rolled out version instead of the active version.
+	Percentage int32 `json:"percentage"`
+	// GroupIDs are the groups whose members' workspaces are built with the
+	// rolled out version, regardless of the percentage.
+	GroupIDs []uuid.UUID `json:"group_ids" format:"uuid"`
+	// FailureThresholdPercent is the percentage of failed builds of the rolled
+	// out version above which the rollout is halted.
+	FailureThresholdPercent int32 `json:"failure_threshold_percent"`
+	// MinimumBuilds is the number of finished builds of the rolled out version
+	// required before the failure threshold is evaluated.
+	MinimumBuilds int32                        `json:"minimum_builds"`
+	Status        TemplateVersionRolloutStatus `json:"status" enums:"in_progress,halted,completed,canceled,pending_approval"`
+	// TotalBuilds and FailedBuilds count the finished start builds of the
+	// rolled out version since the rollout was started.
+	TotalBuilds  int64     `json:"total_builds"`
+	FailedBuilds int64     `json:"failed_builds"`
+	CreatedAt    time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt    time.Time `json:"updated_at" format:"date-time"`
+}
+
+// UpdateTemplateActivationReviewersRequest designates the users that review
+// the activation requests of the template's versions. When no reviewers are
+// designated, users that can update the template review them.
+type UpdateTemplateActivationReviewersRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" format:"uuid"`
+}
+
+// CreateTemplateVersionRolloutRequest starts the rollout of a template
+// version. Starting a rollout replaces the previous rollout of the template.
+type CreateTemplateVersionRolloutRequest struct {
+	TemplateVersionID uuid.UUID `json:"template_version_id" validate:"required" format:"uuid"`
+	// Percentage of the workspaces of the template that are built with the
+	// rolled out version on their next build.
+	Percentage int32 `json:"percentage" validate:"min=0,max=100"`
+	// GroupIDs are the groups whose members' workspaces are built with the
+	// rolled out version on their next build, regardless of the percentage.
+	GroupIDs []uuid.UUID `json:"group_ids,omitempty" format:"uuid"`
+	// FailureThresholdPercent is the percentage of failed builds of the rolled
+	// out version above which the rollout is halted.
+	FailureThresholdPercent int32 `json:"failure_threshold_percent" validate:"min=0,max=100"`
+	// MinimumBuilds is the number of finished builds of the rolled out version
+	// required before the failure threshold is evaluated. Defaults to 5.
+	MinimumBuilds int32 `json:"minimum_builds,omitempty" validate:"min=0"`
+}
+
+// TemplateScheduleImpactRequest is a proposed change to the schedule of a
+// template. The fields match UpdateTemplateMeta, so the body of a template
+// update can be previewed before it is sent.
+type TemplateScheduleImpactRequest struct {
+	DefaultTTLMillis               int64 `json:"default_ttl_ms,omitempty"`
+	AllowUserAutostop              bool  `json:"allow_user_autostop,omitempty"`
+	FailureTTLMillis               int64 `json:"failure_ttl_ms,omitempty"`
+	TimeTilDormantMillis           int64 `json:"time_til_dormant_ms,omitempty"`
+	TimeTilDormantAutoDeleteMillis int64 `json:"time_til_dormant_autodelete_ms,omitempty"`
+	UpdateWorkspaceLastUsedAt      bool  `json:"update_workspace_last_used_at"`
+	UpdateWorkspaceDormantAt       bool  `json:"update_workspace_dormant_at"`
+}
+
+type TemplateScheduleImpactAction string
+
+const (
+	// TemplateScheduleImpactActionDormant changes when an inactive workspace
+	// becomes dormant.
+	TemplateScheduleImpactActionDormant TemplateScheduleImpactAction = "dormant"
+	// TemplateScheduleImpactActionDelete changes when a dormant workspace is
+	// deleted.
+	TemplateScheduleImpactActionDelete TemplateScheduleImpactAction = "delete"
+	// TemplateScheduleImpactActionFailedStop changes when a workspace whose
+	// last build failed is stopped.
+	TemplateScheduleImpactActionFailedStop TemplateScheduleImpactAction = "failed_stop"
+	// TemplateScheduleImpactActionAutostopTTL replaces the autostop TTL of a
+	// workspace with the default TTL of the template. It applies from the next
+	// build of the workspace.
+	TemplateScheduleImpactActionAutostopTTL TemplateScheduleImpactAction = "autostop_ttl"
+)
+
+// TemplateScheduleImpact is the effect of a proposed schedule change on the
+// existing workspaces of a template.
+type TemplateScheduleImpact struct {
+	Summary    []TemplateScheduleImpactSummary   `json:"summary"`
+	Workspaces []TemplateScheduleImpactWorkspace `json:"workspaces"`
+}
+
+// TemplateScheduleImpactSummary counts the workspaces affected by an action.
+type TemplateScheduleImpactSummary struct {
+	Action TemplateScheduleImpactAction `json:"action" enums:"dormant,delete,failed_stop,autostop_ttl"`
+	Count  int                          `json:"count"`
+	// Immediate is the number of workspaces that the action applies to as
+	// soon as the change is saved.
+	Immediate int `json:"immediate"`
+}
+
+// TemplateScheduleImpactWorkspace is an action whose time changes for a
+// workspace.
+type TemplateScheduleImpactWorkspace struct {
+	WorkspaceID   uuid.UUID                    `json:"workspace_id" format:"uuid"`
+	WorkspaceName string                       `json:"workspace_name"`
+	OwnerID       uuid.UUID                    `json:"owner_id" format:"uuid"`
+	OwnerName     string                       `json:"owner_name"`
+	Action        TemplateScheduleImpactAction `json:"action" enums:"dormant,delete,failed_stop,autostop_ttl"`
+	// CurrentAt is when the action happens with the current schedule, and At
+	// when it happens with the proposed schedule. A nil time means never.
+	CurrentAt *time.Time `json:"current_at,omitempty" format:"date-time"`
+	At        *time.Time `json:"at,omitempty" format:"date-time"`
+	// Immediate is true if the action applies as soon as the change is saved.
+	Immediate bool `json:"immediate"`
 }
 
 type TemplateExample struct {
@@ -322,8 +535,8 @@ func (c *Client) SetArchiveTemplateVersion(ctx context.Context, templateVersion
 	return nil
 }
 
-func (c *Client) DeleteTemplate(ctx context.Context, template uuid.UUID) error {
-	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s", template), nil)
+func (c *Client) DeleteTemplate(ctx context.Context, template uuid.UUID, opts ...RequestOption) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s", template), nil, opts...)
 	if err != nil {
 		return err
 	}
@@ -334,8 +547,8 @@ func (c *Client) DeleteTemplate(ctx context.Context, template uuid.UUID) error {
 	return nil
 }
 
-func (c *Client) UpdateTemplateMeta(ctx context.Context, templateID uuid.UUID, req UpdateTemplateMeta) (Template, error) {
-	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/templates/%s", templateID), req)
+func (c *Client) UpdateTemplateMeta(ctx context.Context, templateID uuid.UUID, req UpdateTemplateMeta, opts ...RequestOption) (Template, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/templates/%s", templateID), req, opts...)
 	if err != nil {
 		return Template{}, err
 	}
@@ -391,18 +604,126 @@ func (c *Client) TemplateACL(ctx context.Context, templateID uuid.UUID) (Templat
 
 // UpdateActiveTemplateVersion updates the active template version to the ID provided.
 // The template version must be attached to the template.
+// TransferTemplate moves a template, its versions and optionally its
+// workspaces to another organization.
+func (c *Client) TransferTemplate(ctx context.Context, templateID uuid.UUID, req TransferTemplateRequest) (TransferTemplateResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/transfer", templateID), req)
+	if err != nil {
+		return TransferTemplateResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TransferTemplateResponse{}, ReadBodyAsError(res)
+	}
+	var resp TransferTemplateResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 func (c *Client) UpdateActiveTemplateVersion(ctx context.Context, template uuid.UUID, req UpdateActiveTemplateVersion) error {
 	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/templates/%s/versions", template), req)
 	if err != nil {
 		return nil
 	}
 	defer res.Body.Close()
+	// The template requires approvals to activate versions, and an activation
+	// request was created.
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusAccepted {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// TemplateVersionRollout returns the latest rollout of a template version for
+// the template.
+func (c *Client) TemplateVersionRollout(ctx context.Context, template uuid.UUID) (TemplateVersionRollout, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/rollout", template), nil)
+	if err != nil {
+		return TemplateVersionRollout{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionRollout{}, ReadBodyAsError(res)
+	}
+	var rollout TemplateVersionRollout
+	return rollout, json.NewDecoder(res.Body).Decode(&rollout)
+}
+
+// StartTemplateVersionRollout starts rolling out a template version to a
+// cohort of the workspaces of the template. If the template requires approvals
+// to activate versions, the rollout is pending approval until the activation
+// of the version has been approved.
+func (c *Client) StartTemplateVersionRollout(ctx context.Context, template uuid.UUID, req CreateTemplateVersionRolloutRequest) (TemplateVersionRollout, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/rollout", template), req)
+	if err != nil {
+		return TemplateVersionRollout{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusAccepted {
+		return TemplateVersionRollout{}, ReadBodyAsError(res)
+	}
+	var rollout TemplateVersionRollout
+	return rollout, json.NewDecoder(res.Body).Decode(&rollout)
+}
+
+// CancelTemplateVersionRollout cancels the rollout of the template. Workspaces
+// are built with the active version of the template again.
+func (c *Client) CancelTemplateVersionRollout(ctx context.Context, template uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/rollout", template), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
 		return ReadBodyAsError(res)
 	}
 	return nil
 }
 
+// TemplateActivationReviewers returns the users designated to review the
+// activation requests of the template's versions.
+func (c *Client) TemplateActivationReviewers(ctx context.Context, template uuid.UUID) ([]MinimalUser, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/activation-reviewers", template), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var reviewers []MinimalUser
+	return reviewers, json.NewDecoder(res.Body).Decode(&reviewers)
+}
+
+// UpdateTemplateActivationReviewers replaces the users designated to review
+// the activation requests of the template's versions.
+func (c *Client) UpdateTemplateActivationReviewers(ctx context.Context, template uuid.UUID, req UpdateTemplateActivationReviewersRequest) ([]MinimalUser, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/activation-reviewers", template), req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var reviewers []MinimalUser
+	return reviewers, json.NewDecoder(res.Body).Decode(&reviewers)
+}
+
+// TemplateScheduleImpact previews which workspaces of the template are
+// affected by a schedule change, without changing the template.
+func (c *Client) TemplateScheduleImpact(ctx context.Context, template uuid.UUID, req TemplateScheduleImpactRequest) (TemplateScheduleImpact, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/schedule/impact", template), req)
+	if err != nil {
+		return TemplateScheduleImpact{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateScheduleImpact{}, ReadBodyAsError(res)
+	}
+	var impact TemplateScheduleImpact
+	return impact, json.NewDecoder(res.Body).Decode(&impact)
+}
+
 // TemplateVersionsByTemplateRequest defines the request parameters for
 // TemplateVersionsByTemplate.
 type TemplateVersionsByTemplateRequest struct {
@@ -303,3 +303,91 @@ func (c *Client) UpdateTemplateVersion(ctx context.Context, versionID uuid.UUID,
 	var version TemplateVersion
 	return version, json.NewDecoder(res.Body).Decode(&version)
 }
+
+type TemplateVersionActivationRequestStatus string
+
+const (
+	TemplateVersionActivationRequestStatusPending  TemplateVersionActivationRequestStatus = "pending"
+	TemplateVersionActivationRequestStatusApproved TemplateVersionActivationRequestStatus = "approved"
+	TemplateVersionActivationRequestStatusRejected TemplateVersionActivationRequestStatus = "rejected"
+	TemplateVersionActivationRequestStatusCanceled TemplateVersionActivationRequestStatus = "canceled"
+)
+
+// TemplateVersionActivationRequest is a request to make a template version the
+// active version of its template. It is created when the template requires
+// approvals to activate versions, and the version is activated once it has
+// been approved by enough reviewers.
+type TemplateVersionActivationRequest struct {
+	TemplateVersionID uuid.UUID                              `json:"template_version_id" format:"uuid"`
+	TemplateID        uuid.UUID                              `json:"template_id" format:"uuid"`
+	RequestedBy       MinimalUser                            `json:"requested_by"`
+	Status            TemplateVersionActivationRequestStatus `json:"status" enums:"pending,approved,rejected,canceled"`
+	ApprovalsRequired int32                                  `json:"approvals_required"`
+	Reviews           []TemplateVersionActivationReview      `json:"reviews"`
+	CreatedAt         time.Time                              `json:"created_at" format:"date-time"`
+	UpdatedAt         time.Time                              `json:"updated_at" format:"date-time"`
+}
+
+type TemplateVersionActivationReview struct {
+	Reviewer  MinimalUser `json:"reviewer"`
+	Approved  bool        `json:"approved"`
+	Comment   string      `json:"comment"`
+	CreatedAt time.Time   `json:"created_at" format:"date-time"`
+}
+
+type ReviewTemplateVersionActivationRequest struct {
+	Approved bool   `json:"approved"`
+	Comment  string `json:"comment,omitempty" validate:"max=1024"`
+}
+
+// TemplateVersionDiff is the unified diff of the files of a template version
+// against the files of the active version of its template.
+type TemplateVersionDiff struct {
+	ActiveVersionID uuid.UUID `json:"active_version_id" format:"uuid"`
+	Diff            string    `json:"diff"`
+}
+
+// TemplateVersionActivationRequest returns the request to activate the
+// template version along with its reviews.
+func (c *Client) TemplateVersionActivationRequest(ctx context.Context, version uuid.UUID) (TemplateVersionActivationRequest, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/activation-request", version), nil)
+	if err != nil {
+		return TemplateVersionActivationRequest{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionActivationRequest{}, ReadBodyAsError(res)
+	}
+	var request TemplateVersionActivationRequest
+	return request, json.NewDecoder(res.Body).Decode(&request)
+}
+
+// ReviewTemplateVersionActivationRequest approves or rejects the request to
+// activate the template version.
+func (c *Client) ReviewTemplateVersionActivationRequest(ctx context.Context, version uuid.UUID, req ReviewTemplateVersionActivationRequest) (TemplateVersionActivationRequest, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templateversions/%s/activation-request/reviews", version), req)
+	if err != nil {
+		return TemplateVersionActivationRequest{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionActivationRequest{}, ReadBodyAsError(res)
+	}
+	var request TemplateVersionActivationRequest
+	return request, json.NewDecoder(res.Body).Decode(&request)
+}
+
+// TemplateVersionDiff returns the changes of the template version compared to
+// the active version of its template.
+func (c *Client) TemplateVersionDiff(ctx context.Context, version uuid.UUID) (TemplateVersionDiff, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/diff", version), nil)
+	if err != nil {
+		return TemplateVersionDiff{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionDiff{}, ReadBodyAsError(res)
+	}
+	var diff TemplateVersionDiff
+	return diff, json.NewDecoder(res.Body).Decode(&diff)
+}
@@ -276,7 +276,14 @@ type LoginWithPasswordRequest struct {
 
 // LoginWithPasswordResponse contains a session token for the newly authenticated user.
 type LoginWithPasswordResponse struct {
-	SessionToken string `json:"session_token" validate:"required"`
+	// SessionToken is empty when MFA is set.
+	SessionToken string `json:"session_token"`
+	// MFA is set instead of SessionToken when the user must complete a second
+	// factor to log in.
+	MFA *LoginMFAChallenge `json:"mfa,omitempty"`
+	// RecoveryCodes are only returned when a login registered the first
+	// WebAuthn credential of the user.
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
 }
 
 // RequestOneTimePasscodeRequest enables callers to request a one-time-passcode to change their password.
@@ -304,6 +311,7 @@ type AuthMethods struct {
 	Password          AuthMethod       `json:"password"`
 	Github            GithubAuthMethod `json:"github"`
 	OIDC              OIDCAuthMethod   `json:"oidc"`
+	SAML              SAMLAuthMethod   `json:"saml"`
 }
 
 type AuthMethod struct {
@@ -325,6 +333,12 @@ type OIDCAuthMethod struct {
 	IconURL    string `json:"iconUrl"`
 }
 
+type SAMLAuthMethod struct {
+	AuthMethod
+	SignInText string `json:"signInText"`
+	IconURL    string `json:"iconUrl"`
+}
+
 type UserParameter struct {
 	Name  string `json:"name"`
 	Value string `json:"value"`
@@ -568,6 +582,28 @@ func (c *Client) OrganizationMembers(ctx context.Context, organizationID uuid.UU
 	return members, json.NewDecoder(res.Body).Decode(&members)
 }
 
+// CrossOrganizationMembers lists the members of all organizations the caller
+// can read members of.
+func (c *Client) CrossOrganizationMembers(ctx context.Context, req CrossOrganizationMembersRequest) (CrossOrganizationMembersResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/members", nil,
+		req.Pagination.asRequestOption(),
+		func(r *http.Request) {
+			q := r.URL.Query()
+			q.Set("q", req.SearchQuery)
+			r.URL.RawQuery = q.Encode()
+		},
+	)
+	if err != nil {
+		return CrossOrganizationMembersResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return CrossOrganizationMembersResponse{}, ReadBodyAsError(res)
+	}
+	var resp CrossOrganizationMembersResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // UpdateUserRoles grants the userID the specified roles.
 // Include ALL roles the user has.
 func (c *Client) UpdateUserRoles(ctx context.Context, user string, req UpdateRoles) (User, error) {
@@ -614,13 +650,16 @@ func (c *Client) UserRoles(ctx context.Context, user string) (UserRoles, error)
 
 // LoginWithPassword creates a session token authenticating with an email and password.
 // Call `SetSessionToken()` to apply the newly acquired token to the client.
+//
+// If the user must complete a second factor, no session token is returned and
+// MFA is set in the response instead.
 func (c *Client) LoginWithPassword(ctx context.Context, req LoginWithPasswordRequest) (LoginWithPasswordResponse, error) {
 	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/login", req)
 	if err != nil {
 		return LoginWithPasswordResponse{}, err
 	}
 	defer res.Body.Close()
-	if res.StatusCode != http.StatusCreated {
+	if res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusOK {
 		return LoginWithPasswordResponse{}, ReadBodyAsError(res)
 	}
 	var resp LoginWithPasswordResponse
@@ -0,0 +1,158 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// VariableSet is a named set of Terraform variable values that can be
+// attached to templates. The values are passed to the provisioner jobs of
+// every template the set is attached to.
+type VariableSet struct {
+	ID          uuid.UUID             `json:"id" format:"uuid"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Variables   []VariableSetVariable `json:"variables"`
+	CreatedAt   time.Time             `json:"created_at" format:"date-time"`
+	UpdatedAt   time.Time             `json:"updated_at" format:"date-time"`
+}
+
+// VariableSetVariable is a Terraform variable value of a variable set. The
+// values of secret variables are write-only and are never returned by the
+// API.
+type VariableSetVariable struct {
+	Name   string `json:"name" validate:"required"`
+	Value  string `json:"value,omitempty"`
+	Secret bool   `json:"secret,omitempty"`
+}
+
+type CreateVariableSetRequest struct {
+	Name        string                `json:"name" validate:"required,username"`
+	Description string                `json:"description,omitempty"`
+	Variables   []VariableSetVariable `json:"variables,omitempty"`
+}
+
+// UpdateVariableSetRequest updates a variable set. Variables are set, or
+// replaced if they already exist, and then the variables listed in
+// RemoveVariables are removed. Fields that are nil are left unchanged.
+type UpdateVariableSetRequest struct {
+	Description     *string               `json:"description,omitempty"`
+	Variables       []VariableSetVariable `json:"variables,omitempty"`
+	RemoveVariables []string              `json:"remove_variables,omitempty"`
+}
+
+// VariableSets returns the variable sets of the deployment.
+func (c *Client) VariableSets(ctx context.Context) ([]VariableSet, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/variable-sets", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var sets []VariableSet
+	return sets, json.NewDecoder(res.Body).Decode(&sets)
+}
+
+// VariableSet returns a variable set by name.
+func (c *Client) VariableSet(ctx context.Context, name string) (VariableSet, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/variable-sets/%s", name), nil)
+	if err != nil {
+		return VariableSet{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return VariableSet{}, ReadBodyAsError(res)
+	}
+	var set VariableSet
+	return set, json.NewDecoder(res.Body).Decode(&set)
+}
+
+// CreateVariableSet creates a variable set.
+func (c *Client) CreateVariableSet(ctx context.Context, req CreateVariableSetRequest) (VariableSet, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/variable-sets", req)
+	if err != nil {
+		return VariableSet{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return VariableSet{}, ReadBodyAsError(res)
+	}
+	var set VariableSet
+	return set, json.NewDecoder(res.Body).Decode(&set)
+}
+
+// UpdateVariableSet updates a variable set.
+func (c *Client) UpdateVariableSet(ctx context.Context, name string, req UpdateVariableSetRequest) (VariableSet, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/variable-sets/%s", name), req)
+	if err != nil {
+		return VariableSet{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return VariableSet{}, ReadBodyAsError(res)
+	}
+	var set VariableSet
+	return set, json.NewDecoder(res.Body).Decode(&set)
+}
+
+// DeleteVariableSet deletes a variable set and detaches it from all
+// templates.
+func (c *Client) DeleteVariableSet(ctx context.Context, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/variable-sets/%s", name), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// TemplateVariableSets returns the variable sets attached to a template.
+func (c *Client) TemplateVariableSets(ctx context.Context, template uuid.UUID) ([]VariableSet, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/variable-sets", template), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var sets []VariableSet
+	return sets, json.NewDecoder(res.Body).Decode(&sets)
+}
+
+// AttachTemplateVariableSet attaches a variable set to a template.
+func (c *Client) AttachTemplateVariableSet(ctx context.Context, template uuid.UUID, name string) error {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/variable-sets/%s", template, name), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// DetachTemplateVariableSet detaches a variable set from a template.
+func (c *Client) DetachTemplateVariableSet(ctx context.Context, template uuid.UUID, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/variable-sets/%s", template, name), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VersionSkewReport lists the workspace agents and CLIs whose version differs
+// from the version of the server.
+type VersionSkewReport struct {
+	ServerVersion string `json:"server_version"`
+	// MinAgentVersion is the minimum agent version the deployment enforces.
+	// It is empty if no minimum is enforced.
+	MinAgentVersion string `json:"min_agent_version"`
+	// MinCLIVersion is the minimum CLI version the deployment enforces. It is
+	// empty if no minimum is enforced.
+	MinCLIVersion string `json:"min_cli_version"`
+	// Agents are the agents of the latest builds of workspaces, as of the
+	// version they reported when they last started.
+	Agents []AgentVersionSkew `json:"agents"`
+	// CLIs are the CLI versions users made requests with in the last 30 days.
+	CLIs []CLIVersionSkew `json:"clis"`
+}
+
+type AgentVersionSkew struct {
+	WorkspaceID    uuid.UUID `json:"workspace_id" format:"uuid"`
+	WorkspaceName  string    `json:"workspace_name"`
+	WorkspaceOwner string    `json:"workspace_owner"`
+	AgentID        uuid.UUID `json:"agent_id" format:"uuid"`
+	AgentName      string    `json:"agent_name"`
+	Version        string    `json:"version"`
+	// Outdated is true if the agent is older than the minimum agent version,
+	// so it can't reconnect.
+	Outdated bool `json:"outdated"`
+}
+
+type CLIVersionSkew struct {
+	UserID     uuid.UUID `json:"user_id" format:"uuid"`
+	Username   string    `json:"username"`
+	Version    string    `json:"version"`
+	LastSeenAt time.Time `json:"last_seen_at" format:"date-time"`
+	// Outdated is true if the CLI is older than the minimum CLI version, so
+	// its requests are rejected.
+	Outdated bool `json:"outdated"`
+}
+
+// DeploymentVersionSkew returns the workspace agents and CLIs whose version
+// differs from the version of the server.
+func (c *Client) DeploymentVersionSkew(ctx context.Context) (VersionSkewReport, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/version-skew", nil)
+	if err != nil {
+		return VersionSkewReport{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return VersionSkewReport{}, ReadBodyAsError(res)
+	}
+	var report VersionSkewReport
+	return report, json.NewDecoder(res.Body).Decode(&report)
+}
@@ -0,0 +1,249 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// WebAuthnCredential is a security key or passkey registered as a second
+// factor for password logins.
+type WebAuthnCredential struct {
+	ID         uuid.UUID  `json:"id" format:"uuid"`
+	UserID     uuid.UUID  `json:"user_id" format:"uuid"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at" format:"date-time"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" format:"date-time"`
+}
+
+// WebAuthnCreationOptions are passed to navigator.credentials.create() to
+// register a credential. Binary values are base64url encoded.
+type WebAuthnCreationOptions struct {
+	ChallengeID uuid.UUID `json:"challenge_id" format:"uuid"`
+	Challenge   string    `json:"challenge"`
+	RPID        string    `json:"rp_id"`
+	RPName      string    `json:"rp_name"`
+	UserHandle  string    `json:"user_handle"`
+	Username    string    `json:"username"`
+	// Algorithms are the supported COSE algorithms, in order of preference.
+	Algorithms []int64 `json:"algorithms"`
+	// ExcludeCredentials are the IDs of the credentials the user already
+	// registered.
+	ExcludeCredentials []string `json:"exclude_credentials"`
+	TimeoutMS          int64    `json:"timeout_ms"`
+}
+
+// WebAuthnRequestOptions are passed to navigator.credentials.get() to sign a
+// login challenge. Binary values are base64url encoded.
+type WebAuthnRequestOptions struct {
+	ChallengeID      uuid.UUID `json:"challenge_id" format:"uuid"`
+	Challenge        string    `json:"challenge"`
+	RPID             string    `json:"rp_id"`
+	AllowCredentials []string  `json:"allow_credentials"`
+	TimeoutMS        int64     `json:"timeout_ms"`
+}
+
+// WebAuthnAttestation is the response of navigator.credentials.create().
+// Binary values are base64url encoded.
+type WebAuthnAttestation struct {
+	ClientDataJSON    string `json:"client_data_json" validate:"required"`
+	AttestationObject string `json:"attestation_object" validate:"required"`
+}
+
+// WebAuthnAssertion is the response of navigator.credentials.get(). Binary
+// values are base64url encoded.
+type WebAuthnAssertion struct {
+	CredentialID      string `json:"credential_id" validate:"required"`
+	ClientDataJSON    string `json:"client_data_json" validate:"required"`
+	AuthenticatorData string `json:"authenticator_data" validate:"required"`
+	Signature         string `json:"signature" validate:"required"`
+}
+
+type CreateWebAuthnCredentialRequest struct {
+	ChallengeID uuid.UUID           `json:"challenge_id" validate:"required" format:"uuid"`
+	Name        string              `json:"name" validate:"required"`
+	Attestation WebAuthnAttestation `json:"attestation"`
+}
+
+type CreateWebAuthnCredentialResponse struct {
+	Credential WebAuthnCredential `json:"credential"`
+	// RecoveryCodes are only returned when the first credential of the user is
+	// registered.
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// MFARecoveryCodesResponse contains single use codes that replace a WebAuthn
+// credential when logging in. They are only shown once.
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// LoginMFAChallenge is returned instead of a session token when a password
+// login requires a second factor. Exactly one of Assertion and Enrollment is
+// set.
+type LoginMFAChallenge struct {
+	// Assertion is set when the user must sign the challenge with one of their
+	// credentials, then call LoginWithWebAuthn.
+	Assertion *WebAuthnRequestOptions `json:"assertion,omitempty"`
+	// Enrollment is set when an organization of the user requires a second
+	// factor, and the user must register their first credential with
+	// LoginWithWebAuthnEnrollment.
+	Enrollment *WebAuthnCreationOptions `json:"enrollment,omitempty"`
+}
+
+// LoginWithWebAuthnRequest completes a password login with either a signed
+// assertion or a recovery code.
+type LoginWithWebAuthnRequest struct {
+	ChallengeID  uuid.UUID          `json:"challenge_id" validate:"required" format:"uuid"`
+	Assertion    *WebAuthnAssertion `json:"assertion,omitempty"`
+	RecoveryCode string             `json:"recovery_code,omitempty"`
+}
+
+// MFASettings are the second factor settings of an organization.
+type MFASettings struct {
+	// RequireWebAuthn requires members that log in with a password to use a
+	// WebAuthn credential as a second factor.
+	RequireWebAuthn bool `json:"require_webauthn"`
+}
+
+// WebAuthnCredentials returns the WebAuthn credentials of a user.
+func (c *Client) WebAuthnCredentials(ctx context.Context, user string) ([]WebAuthnCredential, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/webauthn/credentials", user), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []WebAuthnCredential
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// WebAuthnCreationOptions starts the registration of a WebAuthn credential
+// for the user.
+func (c *Client) WebAuthnCreationOptions(ctx context.Context, user string) (WebAuthnCreationOptions, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/webauthn/challenge", user), nil)
+	if err != nil {
+		return WebAuthnCreationOptions{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return WebAuthnCreationOptions{}, ReadBodyAsError(res)
+	}
+	var resp WebAuthnCreationOptions
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// CreateWebAuthnCredential completes the registration of a WebAuthn
+// credential for the user.
+func (c *Client) CreateWebAuthnCredential(ctx context.Context, user string, req CreateWebAuthnCredentialRequest) (CreateWebAuthnCredentialResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/webauthn/credentials", user), req)
+	if err != nil {
+		return CreateWebAuthnCredentialResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return CreateWebAuthnCredentialResponse{}, ReadBodyAsError(res)
+	}
+	var resp CreateWebAuthnCredentialResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) DeleteWebAuthnCredential(ctx context.Context, user string, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/webauthn/credentials/%s", user, id), nil)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// RegenerateMFARecoveryCodes replaces the recovery codes of the user.
+func (c *Client) RegenerateMFARecoveryCodes(ctx context.Context, user string) (MFARecoveryCodesResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/webauthn/recovery-codes", user), nil)
+	if err != nil {
+		return MFARecoveryCodesResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return MFARecoveryCodesResponse{}, ReadBodyAsError(res)
+	}
+	var resp MFARecoveryCodesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// LoginWithWebAuthn completes a password login that returned an assertion
+// challenge.
+// Call `SetSessionToken()` to apply the newly acquired token to the client.
+func (c *Client) LoginWithWebAuthn(ctx context.Context, req LoginWithWebAuthnRequest) (LoginWithPasswordResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/login/webauthn", req)
+	if err != nil {
+		return LoginWithPasswordResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return LoginWithPasswordResponse{}, ReadBodyAsError(res)
+	}
+	var resp LoginWithPasswordResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// LoginWithWebAuthnEnrollment completes a password login that returned an
+// enrollment challenge by registering the first credential of the user.
+// Call `SetSessionToken()` to apply the newly acquired token to the client.
+func (c *Client) LoginWithWebAuthnEnrollment(ctx context.Context, req CreateWebAuthnCredentialRequest) (LoginWithPasswordResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/login/webauthn/enroll", req)
+	if err != nil {
+		return LoginWithPasswordResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return LoginWithPasswordResponse{}, ReadBodyAsError(res)
+	}
+	var resp LoginWithPasswordResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) OrganizationMFASettings(ctx context.Context, orgID uuid.UUID) (MFASettings, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/mfa", orgID), nil)
+	if err != nil {
+		return MFASettings{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return MFASettings{}, ReadBodyAsError(res)
+	}
+	var resp MFASettings
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) PatchOrganizationMFASettings(ctx context.Context, orgID uuid.UUID, req MFASettings) (MFASettings, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/organizations/%s/mfa", orgID), req)
+	if err != nil {
+		return MFASettings{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return MFASettings{}, ReadBodyAsError(res)
+	}
+	var resp MFASettings
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
@@ -0,0 +1,45 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateWorkspaceAdoptionRequest requests a token that adopts a machine which
+// is not managed by a provisioner as a workspace.
+type CreateWorkspaceAdoptionRequest struct {
+	TemplateID uuid.UUID `json:"template_id" validate:"required" format:"uuid"`
+	// Name is the name of the workspace created for the machine.
+	Name string `json:"name" validate:"workspace_name,required"`
+	// TTLMillis is how long the token can be used. It defaults to one hour.
+	TTLMillis int64 `json:"ttl_ms,omitempty"`
+}
+
+// WorkspaceAdoptionToken is a one-time token that a machine exchanges for a
+// workspace and an agent token with `coder agent adopt`.
+type WorkspaceAdoptionToken struct {
+	ID uuid.UUID `json:"id" format:"uuid"`
+	// Token is only returned when the token is created.
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at" format:"date-time"`
+}
+
+// CreateWorkspaceAdoption creates a token that adopts a machine as a
+// workspace owned by the user.
+func (c *Client) CreateWorkspaceAdoption(ctx context.Context, user string, req CreateWorkspaceAdoptionRequest) (WorkspaceAdoptionToken, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/workspace-adoptions", user), req)
+	if err != nil {
+		return WorkspaceAdoptionToken{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceAdoptionToken{}, ReadBodyAsError(res)
+	}
+	var token WorkspaceAdoptionToken
+	return token, json.NewDecoder(res.Body).Decode(&token)
+}
@@ -0,0 +1,42 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// DefaultVNCPort is the port web desktop sessions connect to when no port is
+// specified.
+const DefaultVNCPort = 5900
+
+// DefaultRDPPort is the port RDP sessions connect to when no port is
+// specified.
+const DefaultRDPPort = 3389
+
+// WorkspaceAgentDesktop describes how web desktop sessions to a workspace
+// agent behave. The desktop itself is streamed by proxying the VNC server of
+// the workspace over a WebSocket at
+// /api/v2/workspaceagents/{workspaceagent}/desktop/vnc.
+type WorkspaceAgentDesktop struct {
+	// ClipboardPolicy is which way the clipboard is synchronized between the
+	// browser and the workspace.
+	ClipboardPolicy DesktopClipboardPolicy `json:"clipboard_policy" enums:"both,to-workspace,from-workspace,none"`
+}
+
+// WorkspaceAgentDesktop returns the web desktop settings of a workspace agent.
+func (c *Client) WorkspaceAgentDesktop(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentDesktop, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/desktop", agentID), nil)
+	if err != nil {
+		return WorkspaceAgentDesktop{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentDesktop{}, ReadBodyAsError(res)
+	}
+	var desktop WorkspaceAgentDesktop
+	return desktop, json.NewDecoder(res.Body).Decode(&desktop)
+}
@@ -0,0 +1,48 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceAgentVolume is the most recent filesystem usage the agent reported
+// for a volume.
+type WorkspaceAgentVolume struct {
+	Path        string    `json:"path"`
+	TotalBytes  int64     `json:"total_bytes"`
+	UsedBytes   int64     `json:"used_bytes"`
+	CollectedAt time.Time `json:"collected_at" format:"date-time"`
+	// Threshold is the usage percentage at which the template notifies the
+	// workspace owner. It is omitted if the template doesn't monitor the
+	// volume.
+	Threshold *int32 `json:"threshold,omitempty"`
+	// OverThreshold is true while the volume is in an alert state, i.e. its
+	// usage has recently been above the threshold.
+	OverThreshold bool `json:"over_threshold"`
+}
+
+// WorkspaceAgentDiskUsage lists the volumes a workspace agent reports usage
+// for. The agent always reports its home directory, along with every volume
+// the template monitors.
+type WorkspaceAgentDiskUsage struct {
+	Volumes []WorkspaceAgentVolume `json:"volumes"`
+}
+
+// WorkspaceAgentDiskUsage returns the filesystem usage of a workspace agent.
+func (c *Client) WorkspaceAgentDiskUsage(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentDiskUsage, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/disk-usage", agentID), nil)
+	if err != nil {
+		return WorkspaceAgentDiskUsage{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentDiskUsage{}, ReadBodyAsError(res)
+	}
+	var usage WorkspaceAgentDiskUsage
+	return usage, json.NewDecoder(res.Body).Decode(&usage)
+}
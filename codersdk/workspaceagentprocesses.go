@@ -0,0 +1,64 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceAgentProcessPriorityRule adjusts the priority of the processes in
+// a workspace whose name or command line match the pattern. Templates set
+// the rules with the CODER_PROC_PRIO_RULES environment variable of the agent.
+type WorkspaceAgentProcessPriorityRule struct {
+	// Pattern is a glob in which "*" matches any characters, e.g. "gopls" or
+	// "*tsserver.js*".
+	Pattern string `json:"pattern"`
+	// Nice is the niceness of the processes, from -20 to 19.
+	Nice *int `json:"nice,omitempty"`
+	// OOMScoreAdj is the oom_score_adj of the processes, from -1000 to 1000.
+	OOMScoreAdj *int `json:"oom_score_adj,omitempty"`
+}
+
+// WorkspaceAgentManagedProcess is a process whose priority the agent manages.
+type WorkspaceAgentManagedProcess struct {
+	PID     int32  `json:"pid"`
+	Name    string `json:"name"`
+	CmdLine string `json:"cmd_line"`
+	// Pattern is the pattern of the rule that applies to the process.
+	Pattern     string `json:"pattern"`
+	Nice        int    `json:"nice"`
+	OOMScoreAdj int    `json:"oom_score_adj"`
+	// Error is set if the priority of the process could not be adjusted.
+	Error string `json:"error,omitempty"`
+	// ManagedAt is when the agent found the process.
+	ManagedAt time.Time `json:"managed_at" format:"date-time"`
+}
+
+// WorkspaceAgentManagedProcessesResponse lists the process priority rules of
+// a workspace agent, and the processes they apply to.
+type WorkspaceAgentManagedProcessesResponse struct {
+	// Enabled is false if the template has no process priority rules, or the
+	// agent cannot apply them on its platform.
+	Enabled   bool                                `json:"enabled"`
+	Rules     []WorkspaceAgentProcessPriorityRule `json:"rules"`
+	Processes []WorkspaceAgentManagedProcess      `json:"processes"`
+}
+
+// WorkspaceAgentManagedProcesses returns the processes whose priority the
+// workspace agent manages.
+func (c *Client) WorkspaceAgentManagedProcesses(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentManagedProcessesResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/managed-processes", agentID), nil)
+	if err != nil {
+		return WorkspaceAgentManagedProcessesResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentManagedProcessesResponse{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceAgentManagedProcessesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
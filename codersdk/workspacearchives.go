@@ -0,0 +1,66 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceArchive is a snapshot of the dormant archive paths of a workspace
+// taken before it was auto-deleted.
+type WorkspaceArchive struct {
+	ID             uuid.UUID `json:"id" format:"uuid"`
+	WorkspaceID    uuid.UUID `json:"workspace_id" format:"uuid"`
+	WorkspaceName  string    `json:"workspace_name"`
+	OwnerID        uuid.UUID `json:"owner_id" format:"uuid"`
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	TemplateID     uuid.UUID `json:"template_id" format:"uuid"`
+	Paths          []string  `json:"paths"`
+	SizeBytes      int64     `json:"size_bytes"`
+	// Error is set if the workspace could not be archived. Failed archives
+	// cannot be restored.
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	// ExpiresAt is when the archive is purged.
+	ExpiresAt time.Time `json:"expires_at" format:"date-time"`
+}
+
+// RestoreWorkspaceArchiveRequest restores an archive into a running
+// workspace, overwriting existing files.
+type RestoreWorkspaceArchiveRequest struct {
+	ArchiveID uuid.UUID `json:"archive_id" validate:"required" format:"uuid"`
+	// AgentName is the agent to restore the archive with. It defaults to the
+	// first agent of the workspace.
+	AgentName string `json:"agent_name,omitempty"`
+}
+
+// WorkspaceArchives returns the archives of the workspaces owned by a user.
+func (c *Client) WorkspaceArchives(ctx context.Context, user string) ([]WorkspaceArchive, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/workspace-archives", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var archives []WorkspaceArchive
+	return archives, json.NewDecoder(res.Body).Decode(&archives)
+}
+
+// RestoreWorkspaceArchive restores an archive into a running workspace.
+func (c *Client) RestoreWorkspaceArchive(ctx context.Context, workspaceID uuid.UUID, req RestoreWorkspaceArchiveRequest) error {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/restore-archive", workspaceID), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
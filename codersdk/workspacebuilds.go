@@ -49,6 +49,10 @@ const (
 	// BuildReasonDormancy "dormancy" is used when a build to stop a workspace is triggered due to inactivity (dormancy).
 	// The initiator id/username in this case is the workspace owner and can be ignored.
 	BuildReasonDormancy BuildReason = "dormancy"
+	// BuildReasonAutoarchive "autoarchive" is used when a build to start a dormant workspace is triggered so
+	// that its files can be archived before it is auto-deleted.
+	// The initiator id/username in this case is the workspace owner and can be ignored.
+	BuildReasonAutoarchive BuildReason = "autoarchive"
 )
 
 // WorkspaceBuild is an at-point representation of a workspace state.
@@ -61,25 +65,37 @@ type WorkspaceBuild struct {
 	WorkspaceName    string    `json:"workspace_name"`
 	WorkspaceOwnerID uuid.UUID `json:"workspace_owner_id" format:"uuid"`
 	// WorkspaceOwnerName is the username of the owner of the workspace.
-	WorkspaceOwnerName      string               `json:"workspace_owner_name"`
-	WorkspaceOwnerAvatarURL string               `json:"workspace_owner_avatar_url,omitempty"`
-	TemplateVersionID       uuid.UUID            `json:"template_version_id" format:"uuid"`
-	TemplateVersionName     string               `json:"template_version_name"`
-	BuildNumber             int32                `json:"build_number"`
-	Transition              WorkspaceTransition  `json:"transition" enums:"start,stop,delete"`
-	InitiatorID             uuid.UUID            `json:"initiator_id" format:"uuid"`
-	InitiatorUsername       string               `json:"initiator_name"`
-	Job                     ProvisionerJob       `json:"job"`
-	Reason                  BuildReason          `db:"reason" json:"reason" enums:"initiator,autostart,autostop"`
-	Resources               []WorkspaceResource  `json:"resources"`
-	Deadline                NullTime             `json:"deadline,omitempty" format:"date-time"`
-	MaxDeadline             NullTime             `json:"max_deadline,omitempty" format:"date-time"`
-	Status                  WorkspaceStatus      `json:"status" enums:"pending,starting,running,stopping,stopped,failed,canceling,canceled,deleting,deleted"`
-	DailyCost               int32                `json:"daily_cost"`
-	MatchedProvisioners     *MatchedProvisioners `json:"matched_provisioners,omitempty"`
-	TemplateVersionPresetID *uuid.UUID           `json:"template_version_preset_id" format:"uuid"`
-	HasAITask               *bool                `json:"has_ai_task,omitempty"`
-	AITaskSidebarAppID      *uuid.UUID           `json:"ai_task_sidebar_app_id,omitempty" format:"uuid"`
+	WorkspaceOwnerName      string              `json:"workspace_owner_name"`
+	WorkspaceOwnerAvatarURL string              `json:"workspace_owner_avatar_url,omitempty"`
+	TemplateVersionID       uuid.UUID           `json:"template_version_id" format:"uuid"`
+	TemplateVersionName     string              `json:"template_version_name"`
+	BuildNumber             int32               `json:"build_number"`
+	Transition              WorkspaceTransition `json:"transition" enums:"start,stop,delete"`
+	InitiatorID             uuid.UUID           `json:"initiator_id" format:"uuid"`
+	InitiatorUsername       string              `json:"initiator_name"`
+	Job                     ProvisionerJob      `json:"job"`
+	Reason                  BuildReason         `db:"reason" json:"reason" enums:"initiator,autostart,autostop"`
+	// ReasonMessage is a free-form explanation of why the build was started,
+	// given by the initiator.
+	ReasonMessage           string                         `json:"reason_message,omitempty"`
+	InitiatorContext        WorkspaceBuildInitiatorContext `json:"initiator_context"`
+	Resources               []WorkspaceResource            `json:"resources"`
+	Deadline                NullTime                       `json:"deadline,omitempty" format:"date-time"`
+	MaxDeadline             NullTime                       `json:"max_deadline,omitempty" format:"date-time"`
+	Status                  WorkspaceStatus                `json:"status" enums:"pending,starting,running,stopping,stopped,failed,canceling,canceled,deleting,deleted"`
+	DailyCost               int32                          `json:"daily_cost"`
+	MatchedProvisioners     *MatchedProvisioners           `json:"matched_provisioners,omitempty"`
+	TemplateVersionPresetID *uuid.UUID                     `json:"template_version_preset_id" format:"uuid"`
+	HasAITask               *bool                          `json:"has_ai_task,omitempty"`
+	AITaskSidebarAppID      *uuid.UUID                     `json:"ai_task_sidebar_app_id,omitempty" format:"uuid"`
+}
+
+// WorkspaceBuildInitiatorContext describes how the initiator started a build.
+type WorkspaceBuildInitiatorContext struct {
+	// APIKeyName is the name of the API token the build was started with.
+	APIKeyName string `json:"api_key_name,omitempty"`
+	// CIJobURL is the URL of the CI job that started the build.
+	CIJobURL string `json:"ci_job_url,omitempty" format:"uri"`
 }
 
 // WorkspaceResource describes resources used to create a workspace, for instance:
@@ -269,3 +285,38 @@ func (c *Client) WorkspaceBuildTimings(ctx context.Context, build uuid.UUID) (Wo
 	var timings WorkspaceBuildTimings
 	return timings, json.NewDecoder(res.Body).Decode(&timings)
 }
+
+// WorkspaceBuildQueue describes where a workspace build is in the queue of
+// the provisioners that can run it.
+type WorkspaceBuildQueue struct {
+	Status ProvisionerJobStatus `json:"status" enums:"pending,running,succeeded,canceling,canceled,failed"`
+	// Position is the best position of the build across the queues of the
+	// matched provisioners, starting at 1. It is 0 once the build is no
+	// longer pending.
+	Position int `json:"position"`
+	// Size is the number of pending jobs in the queue of the build.
+	Size int `json:"size"`
+	// Tags are the provisioner tags the build must be matched with.
+	Tags                map[string]string   `json:"tags"`
+	MatchedProvisioners MatchedProvisioners `json:"matched_provisioners"`
+	// EstimatedStartAt is when the build is expected to start, based on the
+	// recent build durations of the template. It is null if the build is not
+	// pending, no provisioners match it, or the template has no recent builds.
+	EstimatedStartAt *time.Time `json:"estimated_start_at,omitempty" format:"date-time"`
+}
+
+// WorkspaceBuildQueue returns the queue position of a workspace build and an
+// estimate of when it starts.
+func (c *Client) WorkspaceBuildQueue(ctx context.Context, build uuid.UUID) (WorkspaceBuildQueue, error) {
+	path := fmt.Sprintf("/api/v2/workspacebuilds/%s/queue", build.String())
+	res, err := c.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return WorkspaceBuildQueue{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceBuildQueue{}, ReadBodyAsError(res)
+	}
+	var queue WorkspaceBuildQueue
+	return queue, json.NewDecoder(res.Body).Decode(&queue)
+}
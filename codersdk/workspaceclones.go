@@ -0,0 +1,56 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// CloneWorkspaceRequest creates a workspace for the caller from the template
+// version and parameter values of the latest build of another workspace.
+type CloneWorkspaceRequest struct {
+	Name string `json:"name" validate:"workspace_name,required"`
+}
+
+// CopyWorkspaceDataRequest copies the data paths declared by the template of
+// a running workspace from another running workspace, overwriting existing
+// files.
+type CopyWorkspaceDataRequest struct {
+	SourceWorkspaceID uuid.UUID `json:"source_workspace_id" validate:"required" format:"uuid"`
+	// AgentName is the agent to copy the data with, in both workspaces. It
+	// defaults to the first agent of the workspace.
+	AgentName string `json:"agent_name,omitempty"`
+}
+
+// CloneWorkspace creates a workspace for the caller with the template version
+// and parameter values of the latest build of the workspace. Data is not
+// copied, see CopyWorkspaceData.
+func (c *Client) CloneWorkspace(ctx context.Context, workspaceID uuid.UUID, req CloneWorkspaceRequest) (Workspace, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/clone", workspaceID), req)
+	if err != nil {
+		return Workspace{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return Workspace{}, ReadBodyAsError(res)
+	}
+	var workspace Workspace
+	return workspace, json.NewDecoder(res.Body).Decode(&workspace)
+}
+
+// CopyWorkspaceData copies the data paths declared by the template of the
+// workspace from the source workspace. Both workspaces must be running.
+func (c *Client) CopyWorkspaceData(ctx context.Context, workspaceID uuid.UUID, req CopyWorkspaceDataRequest) error {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/copy-data", workspaceID), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
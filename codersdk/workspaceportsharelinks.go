@@ -0,0 +1,93 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspacePortShareLink is a time-limited public link to a port of a
+// workspace. Anyone with the link can access the port until it expires or is
+// deleted, without signing in to Coder.
+type WorkspacePortShareLink struct {
+	ID          uuid.UUID                       `json:"id" format:"uuid"`
+	WorkspaceID uuid.UUID                       `json:"workspace_id" format:"uuid"`
+	AgentName   string                          `json:"agent_name"`
+	Port        int32                           `json:"port"`
+	Protocol    WorkspaceAgentPortShareProtocol `json:"protocol" enums:"http,https"`
+	// HasPassword is true if visitors must provide a password to use the link.
+	HasPassword bool `json:"has_password"`
+	// BandwidthLimitBytes is the number of bytes that can be transferred
+	// through the link, or 0 if it is not limited.
+	BandwidthLimitBytes int64     `json:"bandwidth_limit_bytes"`
+	BytesTransferred    int64     `json:"bytes_transferred"`
+	ExpiresAt           time.Time `json:"expires_at" format:"date-time"`
+	CreatedBy           uuid.UUID `json:"created_by" format:"uuid"`
+	CreatedAt           time.Time `json:"created_at" format:"date-time"`
+	// URL is the public URL of the link. It contains the secret of the link,
+	// so it is only returned when the link is created.
+	URL string `json:"url,omitempty"`
+}
+
+// CreateWorkspacePortShareLinkRequest creates a public link to a port of a
+// workspace.
+type CreateWorkspacePortShareLinkRequest struct {
+	AgentName string                          `json:"agent_name" validate:"required"`
+	Port      int32                           `json:"port" validate:"required"`
+	Protocol  WorkspaceAgentPortShareProtocol `json:"protocol" enums:"http,https"`
+	// ExpiresInMillis is how long the link can be used for. Links can't be
+	// valid for more than 7 days.
+	ExpiresInMillis int64 `json:"expires_in_ms" validate:"required"`
+	// Password optionally protects the link. Visitors provide it with HTTP
+	// basic authentication.
+	Password string `json:"password,omitempty"`
+	// BandwidthLimitBytes optionally limits the number of bytes that can be
+	// transferred through the link.
+	BandwidthLimitBytes int64 `json:"bandwidth_limit_bytes,omitempty"`
+}
+
+// CreateWorkspacePortShareLink creates a public link to a port of a
+// workspace. The URL of the link is only returned once.
+func (c *Client) CreateWorkspacePortShareLink(ctx context.Context, workspaceID uuid.UUID, req CreateWorkspacePortShareLinkRequest) (WorkspacePortShareLink, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/port-share-links", workspaceID), req)
+	if err != nil {
+		return WorkspacePortShareLink{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspacePortShareLink{}, ReadBodyAsError(res)
+	}
+	var link WorkspacePortShareLink
+	return link, json.NewDecoder(res.Body).Decode(&link)
+}
+
+// WorkspacePortShareLinks lists the port share links of a workspace.
+func (c *Client) WorkspacePortShareLinks(ctx context.Context, workspaceID uuid.UUID) ([]WorkspacePortShareLink, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/port-share-links", workspaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var links []WorkspacePortShareLink
+	return links, json.NewDecoder(res.Body).Decode(&links)
+}
+
+// DeleteWorkspacePortShareLink revokes a port share link of a workspace.
+func (c *Client) DeleteWorkspacePortShareLink(ctx context.Context, workspaceID, linkID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/port-share-links/%s", workspaceID, linkID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
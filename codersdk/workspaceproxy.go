@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/xerrors"
@@ -176,12 +179,116 @@ func (c *Client) WorkspaceProxyByID(ctx context.Context, id uuid.UUID) (Workspac
 	return c.WorkspaceProxyByName(ctx, id.String())
 }
 
+// WorkspaceProxyVersionSkew is how far the version of a workspace proxy is
+// from the version of the primary.
+type WorkspaceProxyVersionSkew string
+
+const (
+	WorkspaceProxyVersionSkewMatch WorkspaceProxyVersionSkew = "match"
+	// WorkspaceProxyVersionSkewPatch means only the patch versions differ,
+	// which is supported.
+	WorkspaceProxyVersionSkewPatch WorkspaceProxyVersionSkew = "patch"
+	WorkspaceProxyVersionSkewMinor WorkspaceProxyVersionSkew = "minor"
+	WorkspaceProxyVersionSkewMajor WorkspaceProxyVersionSkew = "major"
+	// WorkspaceProxyVersionSkewUnknown means the proxy has not registered
+	// yet, or one of the versions is not a release version.
+	WorkspaceProxyVersionSkewUnknown WorkspaceProxyVersionSkew = "unknown"
+)
+
+// WorkspaceProxyTokenStatus is the age of the token and the version of a
+// workspace proxy.
+type WorkspaceProxyTokenStatus struct {
+	ProxyID   uuid.UUID `json:"proxy_id" format:"uuid" table:"id"`
+	ProxyName string    `json:"proxy_name" table:"name,default_sort"`
+	// TokenIssuedAt is when the current token was created, either with the
+	// proxy or by its last rotation.
+	TokenIssuedAt time.Time `json:"token_issued_at" format:"date-time" table:"token issued at"`
+	// TokenAgeSeconds is the age of the current token.
+	TokenAgeSeconds int64 `json:"token_age_seconds" table:"token age seconds"`
+	// NextRotationAt is when the proxy rotates its token next. It is empty
+	// when token rotation is disabled.
+	NextRotationAt *time.Time `json:"next_rotation_at,omitempty" format:"date-time" table:"next rotation at"`
+	// PreviousTokenExpiresAt is set while the token the proxy had before its
+	// last rotation is still accepted.
+	PreviousTokenExpiresAt *time.Time                `json:"previous_token_expires_at,omitempty" format:"date-time" table:"previous token expires at"`
+	Version                string                    `json:"version" table:"version"`
+	VersionSkew            WorkspaceProxyVersionSkew `json:"version_skew" enums:"match,patch,minor,major,unknown" table:"version skew"`
+}
+
+type WorkspaceProxyTokensResponse struct {
+	// PrimaryVersion is the version the proxy versions are compared to.
+	PrimaryVersion string                      `json:"primary_version"`
+	Proxies        []WorkspaceProxyTokenStatus `json:"proxies"`
+}
+
+// WorkspaceProxyTokens returns the token age and version skew of every
+// workspace proxy.
+func (c *Client) WorkspaceProxyTokens(ctx context.Context) (WorkspaceProxyTokensResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		"/api/v2/workspaceproxies/tokens",
+		nil,
+	)
+	if err != nil {
+		return WorkspaceProxyTokensResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceProxyTokensResponse{}, ReadBodyAsError(res)
+	}
+
+	var resp WorkspaceProxyTokensResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 type RegionTypes interface {
 	Region | WorkspaceProxy
 }
 
 type RegionsResponse[R RegionTypes] struct {
 	Regions []R `json:"regions"`
+	// HealthTTLSeconds is how long the health of the regions stays valid.
+	// Clients should fetch the regions again after this long so that traffic
+	// moves to the failover region of a region that became unhealthy. Zero
+	// means the health never changes.
+	HealthTTLSeconds int64 `json:"health_ttl_seconds,omitempty"`
+}
+
+// RegionLatencyHintHeader lets clients report the latency they measured to
+// each region when fetching regions, as a comma-separated list of
+// "<region id>=<milliseconds>". The server uses the hints to pick the failover
+// region for unhealthy regions.
+const RegionLatencyHintHeader = "Coder-Region-Latencies"
+
+// FormatRegionLatencyHint encodes latencies for the RegionLatencyHintHeader.
+func FormatRegionLatencyHint(latencies map[uuid.UUID]time.Duration) string {
+	hints := make([]string, 0, len(latencies))
+	for id, latency := range latencies {
+		hints = append(hints, fmt.Sprintf("%s=%d", id, latency.Milliseconds()))
+	}
+	return strings.Join(hints, ",")
+}
+
+// ParseRegionLatencyHint decodes the RegionLatencyHintHeader. Malformed
+// entries are skipped since the hints are only advisory.
+func ParseRegionLatencyHint(header string) map[uuid.UUID]time.Duration {
+	latencies := make(map[uuid.UUID]time.Duration)
+	for _, hint := range strings.Split(header, ",") {
+		rawID, rawMS, ok := strings.Cut(strings.TrimSpace(hint), "=")
+		if !ok {
+			continue
+		}
+		id, err := uuid.Parse(rawID)
+		if err != nil {
+			continue
+		}
+		ms, err := strconv.ParseFloat(rawMS, 64)
+		if err != nil || ms < 0 || math.IsNaN(ms) || math.IsInf(ms, 0) {
+			continue
+		}
+		latencies[id] = time.Duration(ms * float64(time.Millisecond))
+	}
+	return latencies
 }
 
 type Region struct {
@@ -201,6 +308,10 @@ type Region struct {
 	// E.g. *--suffix.au.example.com
 	// Optional. Does not need to be on the same domain as PathAppURL.
 	WildcardHostname string `json:"wildcard_hostname" table:"wildcard hostname"`
+
+	// FailoverRegionID is only set when the region is unhealthy. It is the
+	// healthy region that app and terminal traffic should use instead.
+	FailoverRegionID *uuid.UUID `json:"failover_region_id,omitempty" format:"uuid"`
 }
 
 func (c *Client) Regions(ctx context.Context) ([]Region, error) {
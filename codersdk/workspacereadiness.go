@@ -0,0 +1,154 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+type WorkspaceReadinessConditionType string
+
+const (
+	// WorkspaceReadinessConditionAgent is satisfied when the agent is
+	// connected and its startup scripts have finished.
+	WorkspaceReadinessConditionAgent WorkspaceReadinessConditionType = "agent"
+	// WorkspaceReadinessConditionApp is satisfied when the healthcheck of the
+	// app passes. Apps without a healthcheck are ready when their agent is.
+	WorkspaceReadinessConditionApp WorkspaceReadinessConditionType = "app"
+	// WorkspaceReadinessConditionScript is satisfied when the script has run
+	// successfully on start.
+	WorkspaceReadinessConditionScript WorkspaceReadinessConditionType = "script"
+)
+
+// WorkspaceReadinessCondition is a state a workspace has to reach before it
+// is ready to use. Conditions are written as "agent[:<agent>]",
+// "app:[<agent>/]<slug>" or "script:[<agent>/]<display name>". If the agent
+// is omitted, the condition applies to every agent of the workspace.
+type WorkspaceReadinessCondition struct {
+	Type  WorkspaceReadinessConditionType `json:"type"`
+	Agent string                          `json:"agent,omitempty"`
+	Name  string                          `json:"name,omitempty"`
+}
+
+func (c WorkspaceReadinessCondition) String() string {
+	switch {
+	case c.Type == WorkspaceReadinessConditionAgent && c.Agent == "":
+		return string(c.Type)
+	case c.Type == WorkspaceReadinessConditionAgent:
+		return string(c.Type) + ":" + c.Agent
+	case c.Agent == "":
+		return string(c.Type) + ":" + c.Name
+	default:
+		return string(c.Type) + ":" + c.Agent + "/" + c.Name
+	}
+}
+
+// ParseWorkspaceReadinessCondition parses a condition in the format described
+// by WorkspaceReadinessCondition.
+func ParseWorkspaceReadinessCondition(s string) (WorkspaceReadinessCondition, error) {
+	typ, rest, _ := strings.Cut(strings.TrimSpace(s), ":")
+	cond := WorkspaceReadinessCondition{Type: WorkspaceReadinessConditionType(typ)}
+	switch cond.Type {
+	case WorkspaceReadinessConditionAgent:
+		cond.Agent = rest
+		return cond, nil
+	case WorkspaceReadinessConditionApp, WorkspaceReadinessConditionScript:
+		if agent, name, ok := strings.Cut(rest, "/"); ok {
+			cond.Agent, cond.Name = agent, name
+		} else {
+			cond.Name = rest
+		}
+		if cond.Name == "" {
+			return WorkspaceReadinessCondition{}, xerrors.Errorf("condition %q must name the %s", s, cond.Type)
+		}
+		return cond, nil
+	default:
+		return WorkspaceReadinessCondition{}, xerrors.Errorf("condition %q must start with one of agent, app or script", s)
+	}
+}
+
+type WorkspaceReadinessConditionStatus string
+
+const (
+	WorkspaceReadinessConditionStatusPending   WorkspaceReadinessConditionStatus = "pending"
+	WorkspaceReadinessConditionStatusSatisfied WorkspaceReadinessConditionStatus = "satisfied"
+	// WorkspaceReadinessConditionStatusFailed means the condition can't be
+	// satisfied without a new build of the workspace.
+	WorkspaceReadinessConditionStatusFailed WorkspaceReadinessConditionStatus = "failed"
+)
+
+type WorkspaceReadinessConditionState struct {
+	Condition string                            `json:"condition"`
+	Status    WorkspaceReadinessConditionStatus `json:"status" enums:"pending,satisfied,failed"`
+	Message   string                            `json:"message,omitempty"`
+}
+
+// WorkspaceReadiness is the state of the readiness conditions of a workspace.
+type WorkspaceReadiness struct {
+	// Ready is true if all conditions are satisfied.
+	Ready bool `json:"ready"`
+	// Failed is true if any condition failed.
+	Failed     bool                               `json:"failed"`
+	Conditions []WorkspaceReadinessConditionState `json:"conditions"`
+}
+
+type WorkspaceReadinessRequest struct {
+	Conditions []WorkspaceReadinessCondition
+	// Timeout is how long the server waits for the conditions to be
+	// satisfied before responding. The server default is used if zero.
+	Timeout time.Duration
+}
+
+// WorkspaceReadiness waits for the readiness conditions of a workspace to be
+// satisfied or to fail. The returned readiness is neither ready nor failed if
+// the timeout of the request is reached first.
+func (c *Client) WorkspaceReadiness(ctx context.Context, workspaceID uuid.UUID, req WorkspaceReadinessRequest) (WorkspaceReadiness, error) {
+	conditions := make([]string, 0, len(req.Conditions))
+	for _, cond := range req.Conditions {
+		conditions = append(conditions, cond.String())
+	}
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/readiness", workspaceID), nil, func(r *http.Request) {
+		q := r.URL.Query()
+		for _, cond := range conditions {
+			q.Add("condition", cond)
+		}
+		if req.Timeout > 0 {
+			q.Set("timeout", req.Timeout.String())
+		}
+		r.URL.RawQuery = q.Encode()
+	})
+	if err != nil {
+		return WorkspaceReadiness{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceReadiness{}, ReadBodyAsError(res)
+	}
+	var readiness WorkspaceReadiness
+	return readiness, json.NewDecoder(res.Body).Decode(&readiness)
+}
+
+// WaitForWorkspaceReadiness long-polls the readiness of a workspace until the
+// conditions are satisfied, a condition fails or the context is canceled.
+func (c *Client) WaitForWorkspaceReadiness(ctx context.Context, workspaceID uuid.UUID, conditions []WorkspaceReadinessCondition) (WorkspaceReadiness, error) {
+	for {
+		readiness, err := c.WorkspaceReadiness(ctx, workspaceID, WorkspaceReadinessRequest{
+			Conditions: conditions,
+		})
+		if err != nil {
+			return WorkspaceReadiness{}, err
+		}
+		if readiness.Ready || readiness.Failed {
+			return readiness, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return readiness, err
+		}
+	}
+}
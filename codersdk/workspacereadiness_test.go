@@ -0,0 +1,41 @@
+package codersdk_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestParseWorkspaceReadinessCondition(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		input    string
+		expected codersdk.WorkspaceReadinessCondition
+		err      bool
+	}{
+		{input: "agent", expected: codersdk.WorkspaceReadinessCondition{Type: codersdk.WorkspaceReadinessConditionAgent}},
+		{input: "agent:dev", expected: codersdk.WorkspaceReadinessCondition{Type: codersdk.WorkspaceReadinessConditionAgent, Agent: "dev"}},
+		{input: "app:code-server", expected: codersdk.WorkspaceReadinessCondition{Type: codersdk.WorkspaceReadinessConditionApp, Name: "code-server"}},
+		{input: "app:dev/code-server", expected: codersdk.WorkspaceReadinessCondition{Type: codersdk.WorkspaceReadinessConditionApp, Agent: "dev", Name: "code-server"}},
+		{input: "script:dev/Install dependencies", expected: codersdk.WorkspaceReadinessCondition{Type: codersdk.WorkspaceReadinessConditionScript, Agent: "dev", Name: "Install dependencies"}},
+		{input: "app", err: true},
+		{input: "script:dev/", err: true},
+		{input: "yes", err: true},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			cond, err := codersdk.ParseWorkspaceReadinessCondition(tc.input)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, cond)
+			require.Equal(t, tc.input, cond.String())
+		})
+	}
+}
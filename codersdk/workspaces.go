@@ -66,6 +66,13 @@ type Workspace struct {
 	AllowRenames     bool             `json:"allow_renames"`
 	Favorite         bool             `json:"favorite"`
 	NextStartAt      *time.Time       `json:"next_start_at" format:"date-time"`
+	// Labels are free-form key/value pairs used to group workspaces, e.g. by
+	// project or cost center. Workspaces can be searched by label with
+	// `label:<key>[=<value>]`.
+	Labels map[string]string `json:"labels"`
+	// Ephemeral workspaces are deleted instead of stopped, and only consume
+	// quota while they are running.
+	Ephemeral bool `json:"ephemeral"`
 }
 
 func (w Workspace) FullName() string {
@@ -110,6 +117,23 @@ type CreateWorkspaceBuildRequest struct {
 	LogLevel ProvisionerLogLevel `json:"log_level,omitempty" validate:"omitempty,oneof=debug"`
 	// TemplateVersionPresetID is the ID of the template version preset to use for the build.
 	TemplateVersionPresetID uuid.UUID `json:"template_version_preset_id,omitempty" format:"uuid"`
+	// ReasonMessage is a free-form explanation of why the build was started,
+	// shown in build listings and audit logs.
+	ReasonMessage string `json:"reason_message,omitempty" validate:"max=256"`
+	// CIJobURL is the URL of the CI job starting the build, if any.
+	CIJobURL string `json:"ci_job_url,omitempty" validate:"omitempty,url" format:"uri"`
+}
+
+// RollbackWorkspaceRequest rolls a workspace back to the template version and
+// parameter values of its last successful start build.
+type RollbackWorkspaceRequest struct {
+	// Log level changes the default logging verbosity of a provider ("info" if empty).
+	LogLevel ProvisionerLogLevel `json:"log_level,omitempty" validate:"omitempty,oneof=debug"`
+	// ReasonMessage is a free-form explanation of why the workspace is rolled
+	// back, shown in build listings and audit logs.
+	ReasonMessage string `json:"reason_message,omitempty" validate:"max=256"`
+	// CIJobURL is the URL of the CI job rolling back the workspace, if any.
+	CIJobURL string `json:"ci_job_url,omitempty" validate:"omitempty,url" format:"uri"`
 }
 
 type WorkspaceOptions struct {
@@ -191,6 +215,22 @@ func (c *Client) CreateWorkspaceBuild(ctx context.Context, workspace uuid.UUID,
 	return workspaceBuild, json.NewDecoder(res.Body).Decode(&workspaceBuild)
 }
 
+// RollbackWorkspace queues a build that starts the workspace with the template
+// version and parameter values of its last successful start build before the
+// latest build.
+func (c *Client) RollbackWorkspace(ctx context.Context, workspace uuid.UUID, request RollbackWorkspaceRequest) (WorkspaceBuild, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/rollback", workspace), request)
+	if err != nil {
+		return WorkspaceBuild{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceBuild{}, ReadBodyAsError(res)
+	}
+	var workspaceBuild WorkspaceBuild
+	return workspaceBuild, json.NewDecoder(res.Body).Decode(&workspaceBuild)
+}
+
 func (c *Client) WatchWorkspace(ctx context.Context, id uuid.UUID) (<-chan Workspace, error) {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
@@ -302,6 +342,25 @@ func (c *Client) UpdateWorkspaceTTL(ctx context.Context, id uuid.UUID, req Updat
 	return nil
 }
 
+type UpdateWorkspaceLabelsRequest struct {
+	// Labels replace all existing labels of the workspace.
+	Labels map[string]string `json:"labels"`
+}
+
+// UpdateWorkspaceLabels replaces the labels of a workspace.
+func (c *Client) UpdateWorkspaceLabels(ctx context.Context, id uuid.UUID, req UpdateWorkspaceLabelsRequest) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/labels", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return xerrors.Errorf("update workspace labels: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // PutExtendWorkspaceRequest is a request to extend the deadline of
 // the active workspace build.
 type PutExtendWorkspaceRequest struct {
@@ -322,6 +381,65 @@ func (c *Client) PutExtendWorkspace(ctx context.Context, id uuid.UUID, req PutEx
 	return nil
 }
 
+// WorkspaceSchedulePause is a bounded pause of the autostop, dormancy and
+// auto-delete schedules of a workspace, e.g. while its owner is on leave.
+type WorkspaceSchedulePause struct {
+	// PausedUntil is when the pause ends. It is nil if the schedules of the
+	// workspace are not paused.
+	PausedUntil *time.Time `json:"paused_until,omitempty" format:"date-time"`
+}
+
+// PutWorkspaceSchedulePauseRequest is a request to pause the schedules of a
+// workspace until the given time.
+type PutWorkspaceSchedulePauseRequest struct {
+	PausedUntil time.Time `json:"paused_until" validate:"required" format:"date-time"`
+}
+
+// WorkspaceSchedulePause returns the schedule pause of a workspace.
+func (c *Client) WorkspaceSchedulePause(ctx context.Context, id uuid.UUID) (WorkspaceSchedulePause, error) {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/schedule-pause", id.String())
+	res, err := c.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return WorkspaceSchedulePause{}, xerrors.Errorf("get workspace schedule pause: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceSchedulePause{}, ReadBodyAsError(res)
+	}
+	var pause WorkspaceSchedulePause
+	return pause, json.NewDecoder(res.Body).Decode(&pause)
+}
+
+// PutWorkspaceSchedulePause pauses the autostop, dormancy and auto-delete
+// schedules of a workspace until the given time.
+func (c *Client) PutWorkspaceSchedulePause(ctx context.Context, id uuid.UUID, req PutWorkspaceSchedulePauseRequest) (WorkspaceSchedulePause, error) {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/schedule-pause", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return WorkspaceSchedulePause{}, xerrors.Errorf("pause workspace schedule: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceSchedulePause{}, ReadBodyAsError(res)
+	}
+	var pause WorkspaceSchedulePause
+	return pause, json.NewDecoder(res.Body).Decode(&pause)
+}
+
+// DeleteWorkspaceSchedulePause resumes the schedules of a paused workspace.
+func (c *Client) DeleteWorkspaceSchedulePause(ctx context.Context, id uuid.UUID) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/schedule-pause", id.String())
+	res, err := c.Request(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return xerrors.Errorf("resume workspace schedule: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 type PostWorkspaceUsageRequest struct {
 	AgentID uuid.UUID    `json:"agent_id" format:"uuid"`
 	AppName UsageAppName `json:"app_name"`
@@ -575,6 +693,15 @@ func (c *Client) WorkspaceByOwnerAndName(ctx context.Context, owner string, name
 type WorkspaceQuota struct {
 	CreditsConsumed int `json:"credits_consumed"`
 	Budget          int `json:"budget"`
+	// Dimensions are the quota dimensions other than the daily cost that are
+	// enforced in the organization.
+	Dimensions []WorkspaceQuotaDimension `json:"dimensions,omitempty"`
+}
+
+type WorkspaceQuotaDimension struct {
+	Name     string `json:"name"`
+	Consumed int64  `json:"consumed"`
+	Budget   int64  `json:"budget"`
 }
 
 func (c *Client) WorkspaceQuota(ctx context.Context, organizationID string, userID string) (WorkspaceQuota, error) {
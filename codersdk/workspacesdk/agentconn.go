@@ -1,6 +1,7 @@
 package workspacesdk
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"encoding/json"
@@ -277,6 +278,24 @@ func (c *AgentConn) ListeningPorts(ctx context.Context) (codersdk.WorkspaceAgent
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// ManagedProcesses lists the processes whose priority the workspace agent
+// manages.
+func (c *AgentConn) ManagedProcesses(ctx context.Context) (codersdk.WorkspaceAgentManagedProcessesResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/managed-processes", nil)
+	if err != nil {
+		return codersdk.WorkspaceAgentManagedProcessesResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return codersdk.WorkspaceAgentManagedProcessesResponse{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var resp codersdk.WorkspaceAgentManagedProcessesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // Netcheck returns a network check report from the workspace agent.
 func (c *AgentConn) Netcheck(ctx context.Context) (healthsdk.AgentNetcheckReport, error) {
 	ctx, span := tracing.StartSpan(ctx)
@@ -407,6 +426,49 @@ func (c *AgentConn) RecreateDevcontainer(ctx context.Context, devcontainerID str
 	return m, nil
 }
 
+// AgentArchiveRequest is the request to archive paths in a workspace.
+type AgentArchiveRequest struct {
+	// Paths to archive. Relative paths are relative to the home directory of
+	// the agent. Paths that do not exist are skipped.
+	Paths []string `json:"paths"`
+}
+
+// Archive returns a gzipped tar archive of the given paths in the workspace.
+// The caller must close the archive.
+func (c *AgentConn) Archive(ctx context.Context, paths []string) (io.ReadCloser, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	body, err := json.Marshal(AgentArchiveRequest{Paths: paths})
+	if err != nil {
+		return nil, xerrors.Errorf("encode request: %w", err)
+	}
+	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/archive", bytes.NewReader(body))
+	if err != nil {
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
+// RestoreArchive extracts a gzipped tar archive returned by Archive into the
+// workspace, overwriting existing files.
+func (c *AgentConn) RestoreArchive(ctx context.Context, archive io.Reader) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/archive/restore", archive)
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // apiRequest makes a request to the workspace agent's HTTP API server.
 func (c *AgentConn) apiRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
 	ctx, span := tracing.StartSpan(ctx)
@@ -11,6 +11,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"tailscale.com/tailcfg"
 	"tailscale.com/wgengine/capture"
@@ -148,7 +149,14 @@ type AgentConnectionInfo struct {
 	DERPMap                  *tailcfg.DERPMap `json:"derp_map"`
 	DERPForceWebSockets      bool             `json:"derp_force_websockets"`
 	DisableDirectConnections bool             `json:"disable_direct_connections"`
-	HostnameSuffix           string           `json:"hostname_suffix,omitempty"`
+	HTTPSFallback            bool             `json:"https_fallback,omitempty"`
+	NetcheckInterval         time.Duration    `json:"netcheck_interval,omitempty"`
+	// WireguardMTU, WireguardKeepaliveInterval and WireguardHandshakeTimeout
+	// tune the tunnel to the agent. Zero values use the tailnet defaults.
+	WireguardMTU               int           `json:"wireguard_mtu,omitempty"`
+	WireguardKeepaliveInterval time.Duration `json:"wireguard_keepalive_interval,omitempty"`
+	WireguardHandshakeTimeout  time.Duration `json:"wireguard_handshake_timeout,omitempty"`
+	HostnameSuffix             string        `json:"hostname_suffix,omitempty"`
 }
 
 func (c *Client) AgentConnectionInfoGeneric(ctx context.Context) (AgentConnectionInfo, error) {
@@ -255,6 +263,11 @@ func (c *Client) DialAgent(dialCtx context.Context, agentID uuid.UUID, options *
 		DERPForceWebSockets: connInfo.DERPForceWebSockets,
 		Logger:              options.Logger,
 		BlockEndpoints:      c.client.DisableDirectConnections || options.BlockEndpoints,
+		HTTPSFallback:       connInfo.HTTPSFallback,
+		NetcheckInterval:    connInfo.NetcheckInterval,
+		MTU:                 connInfo.WireguardMTU,
+		KeepaliveInterval:   connInfo.WireguardKeepaliveInterval,
+		HandshakeTimeout:    connInfo.WireguardHandshakeTimeout,
 		CaptureHook:         options.CaptureHook,
 		ClientType:          proto.TelemetryEvent_CLI,
 		TelemetrySink:       telemetrySink,
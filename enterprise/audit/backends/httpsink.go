@@ -0,0 +1,153 @@
+package backends
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"golang.org/x/xerrors"
+)
+
+// DatadogLogsURL returns the logs intake endpoint of a Datadog site, such as
+// datadoghq.com.
+func DatadogLogsURL(site string) string {
+	return fmt.Sprintf("https://http-intake.logs.%s/api/v2/logs", site)
+}
+
+type splunkSink struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+// NewSplunkSink sends audit logs to a Splunk HTTP Event Collector.
+func NewSplunkSink(client *http.Client, url, token string) StreamSink {
+	return &splunkSink{client: client, url: url, token: token}
+}
+
+func (*splunkSink) Name() string {
+	return "splunk"
+}
+
+type splunkEvent struct {
+	Time       float64     `json:"time"`
+	Source     string      `json:"source"`
+	SourceType string      `json:"sourcetype"`
+	Event      StreamEvent `json:"event"`
+}
+
+func (s *splunkSink) Send(ctx context.Context, events []StreamEvent) error {
+	// The event collector accepts a batch as concatenated JSON objects.
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, ev := range events {
+		err := enc.Encode(splunkEvent{
+			Time:       float64(ev.Time.UnixMilli()) / 1000,
+			Source:     "coder",
+			SourceType: "coder:audit",
+			Event:      ev,
+		})
+		if err != nil {
+			return xerrors.Errorf("encode event: %w", err)
+		}
+	}
+	return postBatch(ctx, s.client, s.url, http.Header{
+		"Authorization": {"Splunk " + s.token},
+	}, &body)
+}
+
+type datadogSink struct {
+	client *http.Client
+	url    string
+	apiKey string
+}
+
+// NewDatadogSink sends audit logs to the Datadog logs intake at url. Use
+// DatadogLogsURL to get the url of a Datadog site.
+func NewDatadogSink(client *http.Client, url, apiKey string) StreamSink {
+	return &datadogSink{client: client, url: url, apiKey: apiKey}
+}
+
+func (*datadogSink) Name() string {
+	return "datadog"
+}
+
+type datadogLog struct {
+	Source  string      `json:"ddsource"`
+	Service string      `json:"service"`
+	Tags    string      `json:"ddtags"`
+	Message string      `json:"message"`
+	Audit   StreamEvent `json:"audit"`
+}
+
+func (s *datadogSink) Send(ctx context.Context, events []StreamEvent) error {
+	logs := make([]datadogLog, 0, len(events))
+	for _, ev := range events {
+		logs = append(logs, datadogLog{
+			Source:  "coder",
+			Service: "coder",
+			Tags:    fmt.Sprintf("resource_type:%s,action:%s", ev.ResourceType, ev.Action),
+			Message: fmt.Sprintf("%s %s %s", ev.Action, ev.ResourceType, ev.ResourceTarget),
+			Audit:   ev,
+		})
+	}
+	body, err := json.Marshal(logs)
+	if err != nil {
+		return xerrors.Errorf("encode logs: %w", err)
+	}
+	return postBatch(ctx, s.client, s.url, http.Header{
+		"DD-API-KEY": {s.apiKey},
+	}, bytes.NewReader(body))
+}
+
+type webhookSink struct {
+	client  *http.Client
+	url     string
+	headers http.Header
+}
+
+// NewWebhookSink POSTs batches of audit logs to url as a JSON array.
+func NewWebhookSink(client *http.Client, url string, headers http.Header) StreamSink {
+	return &webhookSink{client: client, url: url, headers: headers}
+}
+
+func (*webhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *webhookSink) Send(ctx context.Context, events []StreamEvent) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return xerrors.Errorf("encode events: %w", err)
+	}
+	return postBatch(ctx, s.client, s.url, s.headers, bytes.NewReader(body))
+}
+
+func postBatch(ctx context.Context, client *http.Client, url string, headers http.Header, body io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	for name, values := range headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return xerrors.Errorf("unexpected status code %d: %s", res.StatusCode, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return nil
+}
@@ -0,0 +1,316 @@
+package backends
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/audit"
+)
+
+const (
+	streamResultDelivered = "delivered"
+	streamResultDropped   = "dropped"
+	streamResultFailed    = "failed"
+
+	// streamCloseTimeout bounds how long Close waits for buffered audit logs
+	// to be delivered before giving up on them.
+	streamCloseTimeout = 10 * time.Second
+	// streamSendTimeout bounds a single delivery attempt.
+	streamSendTimeout = 10 * time.Second
+)
+
+// StreamEvent is the representation of an audit log that is sent to a
+// StreamSink.
+type StreamEvent struct {
+	ID               uuid.UUID             `json:"id"`
+	Time             time.Time             `json:"time"`
+	OrganizationID   uuid.UUID             `json:"organization_id"`
+	IP               string                `json:"ip,omitempty"`
+	UserAgent        string                `json:"user_agent,omitempty"`
+	ResourceType     database.ResourceType `json:"resource_type"`
+	ResourceID       uuid.UUID             `json:"resource_id"`
+	ResourceTarget   string                `json:"resource_target"`
+	Action           database.AuditAction  `json:"action"`
+	Diff             json.RawMessage       `json:"diff,omitempty"`
+	StatusCode       int32                 `json:"status_code"`
+	AdditionalFields json.RawMessage       `json:"additional_fields,omitempty"`
+	RequestID        uuid.UUID             `json:"request_id"`
+	User             *audit.Actor          `json:"user,omitempty"`
+}
+
+func newStreamEvent(alog database.AuditLog, details audit.BackendDetails) StreamEvent {
+	ev := StreamEvent{
+		ID:               alog.ID,
+		Time:             alog.Time,
+		OrganizationID:   alog.OrganizationID,
+		UserAgent:        alog.UserAgent.String,
+		ResourceType:     alog.ResourceType,
+		ResourceID:       alog.ResourceID,
+		ResourceTarget:   alog.ResourceTarget,
+		Action:           alog.Action,
+		StatusCode:       alog.StatusCode,
+		RequestID:        alog.RequestID,
+		Diff:             validJSON(alog.Diff),
+		AdditionalFields: validJSON(alog.AdditionalFields),
+	}
+	if alog.Ip.Valid {
+		ev.IP = alog.Ip.IPNet.IP.String()
+	}
+	// The auditor passes an empty actor when the user no longer exists.
+	if details.Actor != nil && details.Actor.ID != uuid.Nil {
+		actor := *details.Actor
+		ev.User = &actor
+	}
+	return ev
+}
+
+// validJSON returns nil for values that would make the event fail to
+// marshal.
+func validJSON(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 || !json.Valid(raw) {
+		return nil
+	}
+	return raw
+}
+
+// StreamSink delivers batches of audit logs to an external destination. Send
+// is never called concurrently.
+type StreamSink interface {
+	// Name identifies the sink in logs and metrics.
+	Name() string
+	Send(ctx context.Context, events []StreamEvent) error
+}
+
+// StreamMetrics are shared by every Stream so that sinks are reported as
+// labels of the same metrics.
+type StreamMetrics struct {
+	Events *prometheus.CounterVec
+	Queued *prometheus.GaugeVec
+}
+
+func NewStreamMetrics(reg prometheus.Registerer) *StreamMetrics {
+	m := &StreamMetrics{
+		Events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "audit_stream",
+			Name:      "events_total",
+			Help:      "The number of audit logs handled by each audit stream sink, by result.",
+		}, []string{"sink", "result"}),
+		Queued: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "audit_stream",
+			Name:      "queued_events",
+			Help:      "The number of audit logs waiting to be delivered to each audit stream sink.",
+		}, []string{"sink"}),
+	}
+	reg.MustRegister(m.Events, m.Queued)
+	return m
+}
+
+type StreamOptions struct {
+	// BufferSize is the number of audit logs held for delivery. Audit logs
+	// are dropped when the buffer is full.
+	BufferSize int
+	// MaxAttempts is the number of times a batch is sent before it is
+	// dropped.
+	MaxAttempts int
+	// BatchSize is the maximum number of audit logs sent at once.
+	BatchSize int
+	// FlushInterval is how long a partial batch waits before it is sent.
+	FlushInterval time.Duration
+	// RetryInterval is the delay before the first retry. It doubles on every
+	// failed attempt.
+	RetryInterval time.Duration
+}
+
+// Stream is an audit backend that delivers audit logs to a StreamSink in the
+// background. Export never blocks on the sink, so a slow or unavailable
+// destination can not slow down requests.
+type Stream struct {
+	log     slog.Logger
+	sink    StreamSink
+	metrics *StreamMetrics
+	opts    StreamOptions
+
+	queue     chan StreamEvent
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closing   chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+var _ audit.Backend = (*Stream)(nil)
+
+func NewStream(logger slog.Logger, sink StreamSink, metrics *StreamMetrics, opts StreamOptions) *Stream {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 10000
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Stream{
+		log:     logger.With(slog.F("sink", sink.Name())),
+		sink:    sink,
+		metrics: metrics,
+		opts:    opts,
+		queue:   make(chan StreamEvent, opts.BufferSize),
+		ctx:     ctx,
+		cancel:  cancel,
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (*Stream) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+func (s *Stream) Export(ctx context.Context, alog database.AuditLog, details audit.BackendDetails) error {
+	select {
+	case <-s.closing:
+		s.metrics.Events.WithLabelValues(s.sink.Name(), streamResultDropped).Inc()
+		return nil
+	default:
+	}
+
+	// The gauge is incremented first so that it never goes negative when the
+	// worker picks the event up immediately.
+	queued := s.metrics.Queued.WithLabelValues(s.sink.Name())
+	queued.Inc()
+	select {
+	case s.queue <- newStreamEvent(alog, details):
+	default:
+		// Failing the export would fail the request that is being audited,
+		// and the audit log is already stored in the database.
+		queued.Dec()
+		s.metrics.Events.WithLabelValues(s.sink.Name(), streamResultDropped).Inc()
+		s.log.Warn(ctx, "audit stream buffer is full, dropping audit log", slog.F("audit_log_id", alog.ID))
+	}
+	return nil
+}
+
+// Close delivers the buffered audit logs and stops the stream. Audit logs that
+// can not be delivered within a few seconds are dropped.
+func (s *Stream) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closing)
+		select {
+		case <-s.done:
+		case <-time.After(streamCloseTimeout):
+			s.cancel()
+			<-s.done
+		}
+		s.cancel()
+	})
+	if closer, ok := s.sink.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (s *Stream) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]StreamEvent, 0, s.opts.BatchSize)
+	add := func(ev StreamEvent) {
+		s.metrics.Queued.WithLabelValues(s.sink.Name()).Dec()
+		batch = append(batch, ev)
+		if len(batch) >= s.opts.BatchSize {
+			s.deliver(batch)
+			batch = batch[:0]
+		}
+	}
+	flush := func() {
+		if len(batch) > 0 {
+			s.deliver(batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case <-s.closing:
+			for {
+				select {
+				case ev := <-s.queue:
+					add(ev)
+				default:
+					flush()
+					return
+				}
+			}
+		case ev := <-s.queue:
+			add(ev)
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// deliver sends a batch to the sink, retrying with an exponential backoff.
+func (s *Stream) deliver(batch []StreamEvent) {
+	name := s.sink.Name()
+	delay := s.opts.RetryInterval
+	var err error
+	for attempt := 1; attempt <= s.opts.MaxAttempts; attempt++ {
+		err = s.send(batch)
+		if err == nil {
+			s.metrics.Events.WithLabelValues(name, streamResultDelivered).Add(float64(len(batch)))
+			return
+		}
+		if attempt == s.opts.MaxAttempts {
+			break
+		}
+		s.log.Debug(s.ctx, "send audit logs", slog.F("attempt", attempt), slog.Error(err))
+
+		t := time.NewTimer(delay)
+		select {
+		case <-s.ctx.Done():
+			t.Stop()
+			err = xerrors.Errorf("stream closed: %w", err)
+			s.metrics.Events.WithLabelValues(name, streamResultFailed).Add(float64(len(batch)))
+			s.log.Error(s.ctx, "failed to send audit logs", slog.F("count", len(batch)), slog.Error(err))
+			return
+		case <-t.C:
+		}
+		delay *= 2
+	}
+	s.metrics.Events.WithLabelValues(name, streamResultFailed).Add(float64(len(batch)))
+	s.log.Error(s.ctx, "failed to send audit logs",
+		slog.F("count", len(batch)),
+		slog.F("attempts", s.opts.MaxAttempts),
+		slog.Error(err),
+	)
+}
+
+func (s *Stream) send(batch []StreamEvent) error {
+	ctx, cancel := context.WithTimeout(s.ctx, streamSendTimeout)
+	defer cancel()
+	return s.sink.Send(ctx, batch)
+}
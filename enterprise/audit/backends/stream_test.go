@@ -0,0 +1,349 @@
+package backends_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	promtest "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/testutil"
+)
+
+type fakeStreamSink struct {
+	mu      sync.Mutex
+	fail    int
+	batches [][]backends.StreamEvent
+}
+
+func (*fakeStreamSink) Name() string {
+	return "fake"
+}
+
+func (s *fakeStreamSink) Send(_ context.Context, events []backends.StreamEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail > 0 {
+		s.fail--
+		return xerrors.New("unavailable")
+	}
+	s.batches = append(s.batches, append([]backends.StreamEvent(nil), events...))
+	return nil
+}
+
+func (s *fakeStreamSink) events() []backends.StreamEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []backends.StreamEvent
+	for _, batch := range s.batches {
+		events = append(events, batch...)
+	}
+	return events
+}
+
+func TestStream(t *testing.T) {
+	t.Parallel()
+
+	actor := &audit.Actor{ID: uuid.New(), Username: "alice", Email: "alice@example.com"}
+
+	t.Run("Batches", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		sink := &fakeStreamSink{}
+		metrics := backends.NewStreamMetrics(prometheus.NewRegistry())
+		stream := backends.NewStream(testutil.Logger(t), sink, metrics, backends.StreamOptions{
+			BatchSize:     2,
+			FlushInterval: testutil.IntervalFast,
+		})
+		defer stream.Close()
+
+		alogs := []uuid.UUID{}
+		for range 3 {
+			alog := audittest.RandomLog()
+			alogs = append(alogs, alog.ID)
+			require.NoError(t, stream.Export(ctx, alog, audit.BackendDetails{Actor: actor}))
+		}
+
+		require.Eventually(t, func() bool {
+			return len(sink.events()) == 3
+		}, testutil.WaitShort, testutil.IntervalFast)
+		for i, ev := range sink.events() {
+			require.Equal(t, alogs[i], ev.ID)
+			require.Equal(t, actor, ev.User)
+			require.Equal(t, "127.0.0.1", ev.IP)
+		}
+		require.EqualValues(t, 3, promtest.ToFloat64(metrics.Events.WithLabelValues("fake", "delivered")))
+		require.EqualValues(t, 0, promtest.ToFloat64(metrics.Queued.WithLabelValues("fake")))
+	})
+
+	t.Run("Retries", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		sink := &fakeStreamSink{fail: 2}
+		metrics := backends.NewStreamMetrics(prometheus.NewRegistry())
+		stream := backends.NewStream(testutil.Logger(t), sink, metrics, backends.StreamOptions{
+			MaxAttempts:   3,
+			FlushInterval: testutil.IntervalFast,
+			RetryInterval: time.Millisecond,
+		})
+		defer stream.Close()
+
+		require.NoError(t, stream.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+		require.Eventually(t, func() bool {
+			return len(sink.events()) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.EqualValues(t, 1, promtest.ToFloat64(metrics.Events.WithLabelValues("fake", "delivered")))
+	})
+
+	t.Run("GivesUp", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		sink := &fakeStreamSink{fail: 2}
+		metrics := backends.NewStreamMetrics(prometheus.NewRegistry())
+		stream := backends.NewStream(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug), sink, metrics, backends.StreamOptions{
+			MaxAttempts:   2,
+			FlushInterval: testutil.IntervalFast,
+			RetryInterval: time.Millisecond,
+		})
+		defer stream.Close()
+
+		require.NoError(t, stream.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+		require.Eventually(t, func() bool {
+			return promtest.ToFloat64(metrics.Events.WithLabelValues("fake", "failed")) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.Empty(t, sink.events())
+	})
+
+	t.Run("DropsWhenFull", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		sink := &fakeStreamSink{}
+		metrics := backends.NewStreamMetrics(prometheus.NewRegistry())
+		stream := backends.NewStream(testutil.Logger(t), sink, metrics, backends.StreamOptions{
+			BufferSize:    1,
+			FlushInterval: time.Hour,
+		})
+		defer stream.Close()
+
+		// The worker holds at most one event in its batch, so the rest are
+		// dropped no matter how quickly it drains the buffer.
+		for range 10 {
+			require.NoError(t, stream.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+		}
+		require.GreaterOrEqual(t, promtest.ToFloat64(metrics.Events.WithLabelValues("fake", "dropped")), float64(8))
+	})
+
+	t.Run("CloseFlushes", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		sink := &fakeStreamSink{}
+		metrics := backends.NewStreamMetrics(prometheus.NewRegistry())
+		stream := backends.NewStream(testutil.Logger(t), sink, metrics, backends.StreamOptions{
+			FlushInterval: time.Hour,
+		})
+
+		for range 5 {
+			require.NoError(t, stream.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+		}
+		require.NoError(t, stream.Close())
+		require.Len(t, sink.events(), 5)
+
+		// Audit logs exported after close are dropped.
+		require.NoError(t, stream.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+		require.EqualValues(t, 1, promtest.ToFloat64(metrics.Events.WithLabelValues("fake", "dropped")))
+	})
+}
+
+func TestStreamSinks(t *testing.T) {
+	t.Parallel()
+
+	event := backends.StreamEvent{
+		ID:             uuid.New(),
+		Time:           time.Unix(1257894000, 0).UTC(),
+		ResourceType:   "workspace",
+		ResourceTarget: "dev",
+		Action:         "create",
+		StatusCode:     http.StatusCreated,
+	}
+
+	t.Run("Splunk", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		var got []map[string]any
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Splunk secret", r.Header.Get("Authorization"))
+			dec := json.NewDecoder(r.Body)
+			for {
+				var ev map[string]any
+				if err := dec.Decode(&ev); err != nil {
+					assert.ErrorIs(t, err, io.EOF)
+					break
+				}
+				got = append(got, ev)
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		sink := backends.NewSplunkSink(srv.Client(), srv.URL, "secret")
+		require.NoError(t, sink.Send(ctx, []backends.StreamEvent{event, event}))
+		require.Len(t, got, 2)
+		require.Equal(t, "coder:audit", got[0]["sourcetype"])
+		require.EqualValues(t, 1257894000, got[0]["time"])
+		require.Equal(t, event.ID.String(), got[0]["event"].(map[string]any)["id"])
+	})
+
+	t.Run("Datadog", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		var got []map[string]any
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "secret", r.Header.Get("DD-API-KEY"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer srv.Close()
+
+		sink := backends.NewDatadogSink(srv.Client(), srv.URL, "secret")
+		require.NoError(t, sink.Send(ctx, []backends.StreamEvent{event}))
+		require.Len(t, got, 1)
+		require.Equal(t, "coder", got[0]["ddsource"])
+		require.Equal(t, "create workspace dev", got[0]["message"])
+		require.Equal(t, "https://http-intake.logs.datadoghq.eu/api/v2/logs", backends.DatadogLogsURL("datadoghq.eu"))
+	})
+
+	t.Run("Webhook", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		var got []backends.StreamEvent
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		sink := backends.NewWebhookSink(srv.Client(), srv.URL, http.Header{"Authorization": {"Bearer secret"}})
+		require.NoError(t, sink.Send(ctx, []backends.StreamEvent{event}))
+		require.Equal(t, []backends.StreamEvent{event}, got)
+	})
+
+	t.Run("WebhookError", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		sink := backends.NewWebhookSink(srv.Client(), srv.URL, nil)
+		err := sink.Send(ctx, []backends.StreamEvent{event})
+		require.ErrorContains(t, err, "503: overloaded")
+	})
+
+	t.Run("SyslogUDP", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer conn.Close()
+
+		sink, err := backends.NewSyslogSink("udp://"+conn.LocalAddr().String(), nil)
+		require.NoError(t, err)
+		defer sink.(io.Closer).Close()
+		require.NoError(t, sink.Send(ctx, []backends.StreamEvent{event}))
+
+		buf := make([]byte, 4096)
+		_ = conn.SetReadDeadline(time.Now().Add(testutil.WaitShort))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		msg := string(buf[:n])
+		require.True(t, strings.HasPrefix(msg, "<134>1 2009-11-10T23:00:00Z "), msg)
+		require.Contains(t, msg, " coder - audit - {")
+		require.Contains(t, msg, event.ID.String())
+	})
+
+	t.Run("SyslogTCP", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer ln.Close()
+
+		msgs := make(chan string, 2)
+		go func() {
+			conn, err := ln.Accept()
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close()
+			r := bufio.NewReader(conn)
+			for range 2 {
+				length, err := r.ReadString(' ')
+				if !assert.NoError(t, err) {
+					return
+				}
+				n, err := strconv.Atoi(strings.TrimSpace(length))
+				if !assert.NoError(t, err) {
+					return
+				}
+				buf := make([]byte, n)
+				_, err = io.ReadFull(r, buf)
+				if !assert.NoError(t, err) {
+					return
+				}
+				msgs <- string(buf)
+			}
+		}()
+
+		sink, err := backends.NewSyslogSink("tcp://"+ln.Addr().String(), nil)
+		require.NoError(t, err)
+		defer sink.(io.Closer).Close()
+		require.NoError(t, sink.Send(ctx, []backends.StreamEvent{event, event}))
+
+		for range 2 {
+			msg := testutil.RequireReceive(ctx, t, msgs)
+			require.True(t, strings.HasPrefix(msg, "<134>1 "), msg)
+			require.True(t, strings.HasSuffix(msg, "}"), msg)
+		}
+	})
+
+	t.Run("SyslogInvalidAddress", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := backends.NewSyslogSink("syslog.example.com:514", nil)
+		require.Error(t, err)
+		_, err = backends.NewSyslogSink("http://syslog.example.com", nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,127 @@
+package backends
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// syslogPriority is the local0 facility with the informational severity.
+const syslogPriority = 16*8 + 6
+
+type syslogSink struct {
+	network   string
+	address   string
+	tlsConfig *tls.Config
+	hostname  string
+
+	// conn is dialed on the first send and after a failed write. It is only
+	// used from Send, which is never called concurrently.
+	conn net.Conn
+}
+
+// NewSyslogSink sends audit logs in RFC 5424 format to a syslog server. The
+// address must have the udp, tcp or tls scheme, such as
+// udp://syslog.example.com:514. tlsConfig is only used with the tls scheme and
+// may be nil.
+func NewSyslogSink(address string, tlsConfig *tls.Config) (StreamSink, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, xerrors.Errorf("parse syslog address: %w", err)
+	}
+	switch u.Scheme {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, xerrors.Errorf("syslog address %q must have the udp, tcp or tls scheme", address)
+	}
+	if u.Host == "" {
+		return nil, xerrors.Errorf("syslog address %q has no host", address)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &syslogSink{
+		network:   u.Scheme,
+		address:   u.Host,
+		tlsConfig: tlsConfig,
+		hostname:  hostname,
+	}, nil
+}
+
+func (*syslogSink) Name() string {
+	return "syslog"
+}
+
+func (s *syslogSink) Send(ctx context.Context, events []StreamEvent) error {
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return xerrors.Errorf("dial %s: %w", s.address, err)
+		}
+		s.conn = conn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetWriteDeadline(deadline)
+	} else {
+		_ = s.conn.SetWriteDeadline(time.Time{})
+	}
+
+	for _, ev := range events {
+		msg, err := s.format(ev)
+		if err != nil {
+			return err
+		}
+		if s.network != "udp" {
+			// RFC 6587 octet counting, so that messages may contain newlines.
+			msg = append([]byte(fmt.Sprintf("%d ", len(msg))), msg...)
+		}
+		_, err = s.conn.Write(msg)
+		if err != nil {
+			// The whole batch is retried on a new connection.
+			_ = s.conn.Close()
+			s.conn = nil
+			return xerrors.Errorf("write message: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *syslogSink) dial(ctx context.Context) (net.Conn, error) {
+	if s.network == "tls" {
+		dialer := &tls.Dialer{Config: s.tlsConfig}
+		return dialer.DialContext(ctx, "tcp", s.address)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, s.network, s.address)
+}
+
+// format renders an event as an RFC 5424 message with the JSON encoded event
+// as the message body.
+func (s *syslogSink) format(ev StreamEvent) ([]byte, error) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return nil, xerrors.Errorf("encode event: %w", err)
+	}
+	header := fmt.Sprintf("<%d>1 %s %s coder - audit - ",
+		syslogPriority,
+		ev.Time.UTC().Format(time.RFC3339Nano),
+		s.hostname,
+	)
+	return append([]byte(header), body...), nil
+}
+
+func (s *syslogSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
@@ -18,17 +18,23 @@ import (
 // AuditableResources map (below) as our documentation - generated in scripts/auditdocgen/main.go -
 // depends upon it.
 var AuditActionMap = map[string][]codersdk.AuditAction{
-	"GitSSHKey":       {codersdk.AuditActionCreate},
-	"Template":        {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"TemplateVersion": {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
-	"User":            {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"Workspace":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"WorkspaceBuild":  {codersdk.AuditActionStart, codersdk.AuditActionStop},
-	"Group":           {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"APIKey":          {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
-	"License":         {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
-	"WorkspaceAgent":  {codersdk.AuditActionConnect, codersdk.AuditActionDisconnect},
-	"WorkspaceApp":    {codersdk.AuditActionOpen, codersdk.AuditActionClose},
+	"GitSSHKey":              {codersdk.AuditActionCreate},
+	"Template":               {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"TemplateVersion":        {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
+	"User":                   {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete, codersdk.AuditActionNetworkZoneViolation},
+	"Workspace":              {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"WorkspaceBuild":         {codersdk.AuditActionStart, codersdk.AuditActionStop},
+	"Group":                  {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"APIKey":                 {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"License":                {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"WorkspaceAgent":         {codersdk.AuditActionConnect, codersdk.AuditActionDisconnect, codersdk.AuditActionEgressViolation},
+	"WorkspaceApp":           {codersdk.AuditActionOpen, codersdk.AuditActionClose},
+	"UserSecret":             {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete, codersdk.AuditActionRead},
+	"TemplateSecret":         {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete, codersdk.AuditActionRead},
+	"TemplateEgressPolicy":   {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"WorkspacePortShareLink": {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"VariableSet":            {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"TemplateVersionRollout": {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
 }
 
 type Action string
@@ -117,6 +123,26 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"max_port_sharing_level":            ActionTrack,
 		"activity_bump":                     ActionTrack,
 		"use_classic_parameter_flow":        ActionTrack,
+		"wireguard_mtu":                     ActionTrack,
+		"wireguard_keepalive_interval":      ActionTrack,
+		"wireguard_handshake_timeout":       ActionTrack,
+		"activation_approvals_required":     ActionTrack,
+		"autostart_holidays":                ActionTrack,
+		"autostart_holiday_calendar_url":    ActionTrack,
+		"dormant_archive_paths":             ActionTrack,
+		"parameter_validation_url":          ActionTrack,
+		"allow_start_on_connect":            ActionTrack,
+	},
+	&database.AuditableTemplateVersionActivationRequest{}: {
+		"template_version_id":   ActionTrack,
+		"template_version_name": ActionTrack,
+		"template_id":           ActionTrack,
+		"requested_by":          ActionTrack,
+		"status":                ActionTrack,
+		"approvals_required":    ActionTrack,
+		"reviews":               ActionTrack,
+		"created_at":            ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+		"updated_at":            ActionIgnore, // Changes, but is implicit and not helpful in a diff.
 	},
 	&database.TemplateVersion{}: {
 		"id":                      ActionTrack,
@@ -174,6 +200,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"automatic_updates":  ActionTrack,
 		"favorite":           ActionTrack,
 		"next_start_at":      ActionTrack,
+		"ephemeral":          ActionTrack, // Set on creation and when a prebuild is claimed.
 	},
 	&database.WorkspaceBuild{}: {
 		"id":                         ActionIgnore,
@@ -196,6 +223,9 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"template_version_preset_id": ActionIgnore, // Never changes.
 		"has_ai_task":                ActionIgnore, // Never changes.
 		"ai_task_sidebar_app_id":     ActionIgnore, // Never changes.
+		"reason_message":             ActionTrack,
+		"initiator_api_key_name":     ActionTrack,
+		"initiator_ci_job_url":       ActionTrack,
 	},
 	&database.AuditableGroup{}: {
 		"id":              ActionTrack,
@@ -208,18 +238,21 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"source":          ActionIgnore,
 	},
 	&database.APIKey{}: {
-		"id":               ActionIgnore,
-		"hashed_secret":    ActionIgnore,
-		"user_id":          ActionTrack,
-		"last_used":        ActionTrack,
-		"expires_at":       ActionTrack,
-		"created_at":       ActionTrack,
-		"updated_at":       ActionIgnore,
-		"login_type":       ActionIgnore,
-		"lifetime_seconds": ActionIgnore,
-		"ip_address":       ActionIgnore,
-		"scope":            ActionIgnore,
-		"token_name":       ActionIgnore,
+		"id":                 ActionIgnore,
+		"hashed_secret":      ActionIgnore,
+		"user_id":            ActionTrack,
+		"last_used":          ActionTrack,
+		"expires_at":         ActionTrack,
+		"created_at":         ActionTrack,
+		"updated_at":         ActionIgnore,
+		"login_type":         ActionIgnore,
+		"lifetime_seconds":   ActionIgnore,
+		"ip_address":         ActionIgnore,
+		"scope":              ActionIgnore,
+		"token_name":         ActionIgnore,
+		"user_agent":         ActionIgnore,
+		"device_fingerprint": ActionIgnore,
+		"workspace_id":       ActionTrack,
 	},
 	&database.AuditOAuthConvertState{}: {
 		"created_at":      ActionTrack,
@@ -325,6 +358,8 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"method":             ActionTrack,
 		"kind":               ActionTrack,
 		"enabled_by_default": ActionTrack,
+		"priority":           ActionTrack,
+		"category":           ActionTrack,
 	},
 	&idpsync.OrganizationSyncSettings{}: {
 		"field":          ActionTrack,
@@ -336,12 +371,16 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"mapping":                    ActionTrack,
 		"regex_filter":               ActionTrack,
 		"auto_create_missing_groups": ActionTrack,
+		"rules":                      ActionTrack,
+		"default_groups":             ActionTrack,
 		// Configured in env vars
 		"legacy_group_name_mapping": ActionIgnore,
 	},
 	&idpsync.RoleSyncSettings{}: {
-		"field":   ActionTrack,
-		"mapping": ActionTrack,
+		"field":         ActionTrack,
+		"mapping":       ActionTrack,
+		"rules":         ActionTrack,
+		"default_roles": ActionTrack,
 	},
 	&database.WorkspaceAgent{}: {
 		"id":                         ActionIgnore,
@@ -400,6 +439,69 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"hidden":                ActionIgnore,
 		"open_in":               ActionIgnore,
 	},
+	&database.UserSecret{}: {
+		"id":           ActionIgnore,
+		"user_id":      ActionTrack,
+		"name":         ActionTrack,
+		"description":  ActionTrack,
+		"value":        ActionSecret, // Never expose secret values in diffs.
+		"value_key_id": ActionIgnore, // Changes when the value is re-encrypted.
+		"env_name":     ActionTrack,
+		"created_at":   ActionIgnore, // Never changes.
+		"updated_at":   ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
+	&database.TemplateSecret{}: {
+		"id":           ActionIgnore,
+		"template_id":  ActionTrack,
+		"name":         ActionTrack,
+		"description":  ActionTrack,
+		"value":        ActionSecret, // Never expose secret values in diffs.
+		"value_key_id": ActionIgnore, // Changes when the value is re-encrypted.
+		"created_at":   ActionIgnore, // Never changes.
+		"updated_at":   ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
+	&database.TemplateEgressPolicy{}: {
+		"template_id":     ActionTrack,
+		"allowed_cidrs":   ActionTrack,
+		"allowed_domains": ActionTrack,
+		"created_at":      ActionIgnore, // Never changes.
+		"updated_at":      ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
+	&database.TemplateVersionRollout{}: {
+		"template_id":               ActionTrack,
+		"template_version_id":       ActionTrack,
+		"created_by":                ActionTrack,
+		"percentage":                ActionTrack,
+		"group_ids":                 ActionTrack,
+		"failure_threshold_percent": ActionTrack,
+		"minimum_builds":            ActionTrack,
+		"status":                    ActionTrack,
+		"created_at":                ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+		"updated_at":                ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
+	&database.WorkspacePortShareLink{}: {
+		"id":                    ActionTrack,
+		"workspace_id":          ActionTrack,
+		"agent_name":            ActionTrack,
+		"port":                  ActionTrack,
+		"protocol":              ActionTrack,
+		"hashed_secret":         ActionSecret,
+		"hashed_password":       ActionSecret,
+		"bandwidth_limit_bytes": ActionTrack,
+		"bytes_transferred":     ActionIgnore, // Changes as the link is used.
+		"expires_at":            ActionTrack,
+		"created_by":            ActionTrack,
+		"created_at":            ActionIgnore, // Never changes.
+	},
+	&database.VariableSet{}: {
+		"id":               ActionTrack,
+		"name":             ActionTrack,
+		"description":      ActionTrack,
+		"variables":        ActionSecret, // Contains secret values.
+		"variables_key_id": ActionIgnore, // Changes when the variables are re-encrypted.
+		"created_at":       ActionIgnore, // Never changes.
+		"updated_at":       ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
 }
 
 // auditMap converts a map of struct pointers to a map of struct names as
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) ldap() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "ldap",
+		Short: "Manage LDAP sync",
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.ldapSync(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) ldapSync() *serpent.Command {
+	var dryRun bool
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]ldapSyncChangeRow{}, []string{"type", "username", "group", "dn"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "sync",
+		Short: "Sync users and groups from the LDAP directory now",
+		Long: "Users and groups are synced periodically. Use this to apply changes to the directory " +
+			"immediately, or with --dry-run to preview the changes a sync would make.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{
+			{
+				Flag:        "dry-run",
+				Description: "Show the changes without applying them.",
+				Value:       serpent.BoolOf(&dryRun),
+			},
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			resp, err := client.SyncLDAP(inv.Context(), codersdk.LDAPSyncRequest{
+				DryRun: dryRun,
+			})
+			if err != nil {
+				return xerrors.Errorf("sync ldap: %w", err)
+			}
+
+			verb := "Applied"
+			if resp.DryRun {
+				verb = "Would apply"
+			}
+			_, _ = fmt.Fprintf(inv.Stderr, "Found %d users and %d groups in the directory. %s %d changes.\n",
+				resp.DirectoryUsers, resp.DirectoryGroups, verb, len(resp.Changes))
+			if len(resp.Changes) == 0 {
+				return nil
+			}
+
+			rows := make([]ldapSyncChangeRow, 0, len(resp.Changes))
+			for _, change := range resp.Changes {
+				rows = append(rows, ldapSyncChangeRow{
+					Change:   change,
+					Type:     string(change.Type),
+					Username: change.Username,
+					Group:    change.Group,
+					DN:       change.DN,
+				})
+			}
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return xerrors.Errorf("display changes: %w", err)
+			}
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+			return nil
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+type ldapSyncChangeRow struct {
+	// For json output:
+	Change codersdk.LDAPSyncChange `table:"-"`
+
+	// For table output:
+	Type     string `json:"-" table:"type,nosort"`
+	Username string `json:"-" table:"username"`
+	Group    string `json:"-" table:"group"`
+	DN       string `json:"-" table:"dn"`
+}
@@ -8,9 +8,11 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/v2/cli"
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/cli/cliutil"
 	"github.com/coder/coder/v2/codersdk"
@@ -31,6 +33,7 @@ func (r *RootCmd) licenses() *serpent.Command {
 			r.licenseAdd(),
 			r.licensesList(),
 			r.licenseDelete(),
+			r.licensesUsage(),
 		},
 	}
 	return cmd
@@ -194,3 +197,129 @@ func (r *RootCmd) licenseDelete() *serpent.Command {
 	}
 	return cmd
 }
+
+func (r *RootCmd) licensesUsage() *serpent.Command {
+	var (
+		forecast     bool
+		historyDays  int64
+		forecastDays int64
+		formatter    = cliui.NewOutputFormatter(
+			cliui.ChangeFormatterData(cliui.TextFormat(), func(data any) (any, error) {
+				usage, ok := data.(codersdk.LicenseUsage)
+				if !ok {
+					return nil, xerrors.Errorf("expected codersdk.LicenseUsage, got %T", data)
+				}
+				return formatLicenseUsage(usage, forecast), nil
+			}),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "usage",
+		Short: "Show licensed seat usage, and forecast when the seats run out",
+		Long: cli.FormatExamples(
+			cli.Example{
+				Description: "Forecast when the seats run out based on the trend of the last 30 days",
+				Command:     "coder licenses usage --forecast --history-days 30",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			req := codersdk.LicenseUsageRequest{
+				HistoryDays: int(historyDays),
+			}
+			if forecast {
+				req.ForecastDays = int(forecastDays)
+			}
+			usage, err := client.LicenseUsage(inv.Context(), req)
+			if err != nil {
+				return err
+			}
+			if !forecast {
+				usage.Forecast = []codersdk.LicenseUsagePoint{}
+				usage.ExhaustedAt = nil
+			}
+
+			out, err := formatter.Format(inv.Context(), usage)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "forecast",
+			Description: "Project the trend of active users to forecast when the licensed seats run out.",
+			Value:       serpent.BoolOf(&forecast),
+		},
+		{
+			Flag:        "history-days",
+			Description: "Number of past days used for the trend.",
+			Default:     "90",
+			Value:       serpent.Int64Of(&historyDays),
+		},
+		{
+			Flag:        "forecast-days",
+			Description: "Number of days to forecast.",
+			Default:     "90",
+			Value:       serpent.Int64Of(&forecastDays),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func formatLicenseUsage(usage codersdk.LicenseUsage, forecast bool) string {
+	seats := "unlimited"
+	if usage.Seats != nil {
+		seats = strconv.FormatInt(*usage.Seats, 10)
+	}
+	rows := [][2]string{
+		{"Licensed seats", seats},
+		{"Active users", strconv.FormatInt(usage.ActiveUsers, 10)},
+		{"Unfilled reserved seats", strconv.FormatInt(usage.UnfilledReservedSeats, 10)},
+	}
+	if len(usage.History) > 1 {
+		first, last := usage.History[0], usage.History[len(usage.History)-1]
+		rows = append(rows, [2]string{
+			fmt.Sprintf("Active users %d days ago", int(last.Date.Sub(first.Date).Hours()/24)),
+			strconv.FormatInt(first.ActiveUsers, 10),
+		})
+	}
+	if forecast && len(usage.Forecast) > 0 {
+		last := usage.Forecast[len(usage.Forecast)-1]
+		rows = append(rows, [2]string{
+			fmt.Sprintf("Forecast active users on %s", last.Date.Format(time.DateOnly)),
+			strconv.FormatInt(last.ActiveUsers, 10),
+		})
+		switch {
+		case usage.Seats == nil:
+		case usage.ExhaustedAt != nil:
+			rows = append(rows, [2]string{"Seats run out on", usage.ExhaustedAt.Format(time.DateOnly)})
+		default:
+			rows = append(rows, [2]string{"Seats run out on", "not before " + last.Date.Format(time.DateOnly)})
+		}
+	}
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row[0]))
+	}
+	var sb strings.Builder
+	for _, row := range rows {
+		_, _ = fmt.Fprintf(&sb, "%-*s %s\n", width+1, row[0]+":", row[1])
+	}
+	if len(usage.Reservations) > 0 {
+		_, _ = fmt.Fprintln(&sb, "\nReservations:")
+		for _, reservation := range usage.Reservations {
+			_, _ = fmt.Fprintf(&sb, "  %s: %d of %d seats occupied\n", reservation.GroupName, min(reservation.ActiveMembers, reservation.Seats), reservation.Seats)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
 	"github.com/coder/coder/v2/pty/ptytest"
@@ -225,6 +226,38 @@ func TestLicensesDeleteReal(t *testing.T) {
 	})
 }
 
+func TestLicensesUsageFake(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Mainline", func(t *testing.T) {
+		t.Parallel()
+
+		inv := setupFakeLicenseServerTest(t, "licenses", "usage", "--history-days", "30")
+		stdout := new(bytes.Buffer)
+		inv.Stdout = stdout
+		clitest.Run(t, inv)
+
+		out := stdout.String()
+		assert.Contains(t, out, "Licensed seats:           100")
+		assert.Contains(t, out, "Active users 30 days ago: 65")
+		assert.Contains(t, out, "interns: 5 of 10 seats occupied")
+		assert.NotContains(t, out, "Seats run out")
+	})
+
+	t.Run("Forecast", func(t *testing.T) {
+		t.Parallel()
+
+		inv := setupFakeLicenseServerTest(t, "licenses", "usage", "--history-days", "30", "--forecast")
+		stdout := new(bytes.Buffer)
+		inv.Stdout = stdout
+		clitest.Run(t, inv)
+
+		out := stdout.String()
+		assert.Contains(t, out, "Forecast active users on 2025-04-01: 125")
+		assert.Contains(t, out, "Seats run out on:                    2025-02-10")
+	})
+}
+
 func setupFakeLicenseServerTest(t *testing.T, args ...string) *serpent.Invocation {
 	t.Helper()
 	s := httptest.NewServer(newFakeLicenseAPI(t))
@@ -256,6 +289,7 @@ func newFakeLicenseAPI(t *testing.T) http.Handler {
 	r.Get("/api/v2/buildinfo", a.noop)
 	r.Get("/api/v2/users/me", a.noop)
 	r.Delete("/api/v2/licenses/{id}", a.deleteLicense)
+	r.Get("/api/v2/licenses/usage", a.licenseUsage)
 	r.Get("/api/v2/entitlements", a.entitlements)
 	return r
 }
@@ -329,6 +363,33 @@ func (s *fakeLicenseAPI) deleteLicense(rw http.ResponseWriter, r *http.Request)
 	rw.WriteHeader(200)
 }
 
+func (s *fakeLicenseAPI) licenseUsage(rw http.ResponseWriter, r *http.Request) {
+	assert.Equal(s.t, "30", r.URL.Query().Get("history_days"))
+	forecastDays := r.URL.Query().Get("forecast_days")
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	exhausted := day.AddDate(0, 0, 40)
+	resp := codersdk.LicenseUsage{
+		Seats:                 ptr.Ref[int64](100),
+		ActiveUsers:           80,
+		UnfilledReservedSeats: 5,
+		History: []codersdk.LicenseUsagePoint{
+			{Date: day.AddDate(0, 0, -30), ActiveUsers: 65},
+			{Date: day, ActiveUsers: 80},
+		},
+		Forecast: []codersdk.LicenseUsagePoint{},
+		Reservations: []codersdk.LicenseSeatReservation{
+			{GroupName: "interns", Seats: 10, ActiveMembers: 5},
+		},
+	}
+	if forecastDays != "" {
+		assert.Equal(s.t, "90", forecastDays)
+		resp.Forecast = []codersdk.LicenseUsagePoint{{Date: day.AddDate(0, 0, 90), ActiveUsers: 125}}
+		resp.ExhaustedAt = &exhausted
+	}
+	rw.WriteHeader(200)
+	_ = json.NewEncoder(rw).Encode(resp)
+}
+
 func (*fakeLicenseAPI) entitlements(rw http.ResponseWriter, r *http.Request) {
 	features := make(map[codersdk.FeatureName]codersdk.Feature)
 	for _, f := range codersdk.FeatureNames {
@@ -15,6 +15,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
@@ -24,7 +25,9 @@ import (
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/cli/cliutil"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/clientcert"
 	"github.com/coder/coder/v2/codersdk/drpcsdk"
 	"github.com/coder/coder/v2/provisioner/terraform"
 	"github.com/coder/coder/v2/provisionerd"
@@ -51,6 +54,8 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 
 		prometheusEnable  bool
 		prometheusAddress string
+
+		traceEnable bool
 	)
 	orgContext := agpl.NewOrganizationContext()
 	client := new(codersdk.Client)
@@ -223,6 +228,49 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 			connector := provisionerd.LocalProvisioners{
 				string(database.ProvisionerTypeTerraform): proto.NewDRPCProvisionerClient(terraformClient),
 			}
+			// Present a client certificate from the internal CA of the
+			// deployment, if it has one enabled. The transport is rebuilt
+			// because the one set up by the client middleware cannot be
+			// modified.
+			certRenewer := clientcert.New(logger, func(ctx context.Context, req codersdk.IssueCertificateRequest) (codersdk.IssuedCertificate, error) {
+				return client.IssueProvisionerDaemonCertificate(ctx, codersdk.IssueProvisionerDaemonCertificateRequest{
+					IssueCertificateRequest: req,
+					Organization:            orgID,
+					PreSharedKey:            preSharedKey,
+					ProvisionerKey:          provisionerKey,
+				})
+			})
+			headerTransport, err := r.HeaderTransport(ctx, client.URL)
+			if err != nil {
+				return xerrors.Errorf("configure header transport: %w", err)
+			}
+			headerTransport.Transport = certRenewer.Transport(http.DefaultTransport)
+			client.HTTPClient = &http.Client{Transport: headerTransport}
+			go certRenewer.Run(ctx)
+
+			// Without a tracer provider the spans of jobs are dropped, so the
+			// trace of a build started in coderd has a gap while the job runs
+			// on this daemon.
+			var tracerProvider trace.TracerProvider
+			if traceEnable {
+				sdkTracerProvider, closeTracing, err := tracing.TracerProvider(ctx, "coderd.provisionerd", tracing.TracerOpts{
+					Default: true,
+				})
+				if err != nil {
+					logger.Warn(ctx, "start telemetry exporter", slog.Error(err))
+				} else {
+					defer func() {
+						// Use a background context because the main context
+						// is canceled by the time the daemon exits.
+						err := closeTracing(context.Background())
+						if err != nil {
+							logger.Warn(ctx, "close tracing", slog.Error(err))
+						}
+					}()
+					tracerProvider = sdkTracerProvider
+				}
+			}
+
 			srv := provisionerd.New(func(ctx context.Context) (provisionerdproto.DRPCProvisionerDaemonClient, error) {
 				return client.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
 					Name: name,
@@ -239,6 +287,7 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 				UpdateInterval:      500 * time.Millisecond,
 				Connector:           connector,
 				Metrics:             metrics,
+				TracerProvider:      tracerProvider,
 				ExternalProvisioner: true,
 			})
 
@@ -381,6 +430,13 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 			Value:       serpent.StringOf(&prometheusAddress),
 			Default:     "127.0.0.1:2112",
 		},
+		{
+			Flag:        "trace",
+			Env:         "CODER_PROVISIONER_DAEMON_TRACE_ENABLE",
+			Description: "Whether application tracing data is collected. It exports to a backend configured by environment variables. See: https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/protocol/exporter.md.",
+			Value:       serpent.BoolOf(&traceEnable),
+			Default:     "false",
+		},
 	}
 	orgContext.AttachOptions(cmd)
 
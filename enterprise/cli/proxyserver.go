@@ -56,9 +56,10 @@ func (r *RootCmd) proxyServer() *serpent.Command {
 			Name: "External Workspace Proxy",
 			YAML: "externalWorkspaceProxy",
 		}
-		proxySessionToken serpent.String
-		primaryAccessURL  serpent.URL
-		derpOnly          serpent.Bool
+		proxySessionToken     serpent.String
+		proxySessionTokenFile serpent.String
+		primaryAccessURL      serpent.URL
+		derpOnly              serpent.Bool
 	)
 	opts.Add(
 		// Options only for external workspace proxies
@@ -74,6 +75,16 @@ func (r *RootCmd) proxyServer() *serpent.Command {
 			Group:       &externalProxyOptionGroup,
 			Hidden:      false,
 		},
+		serpent.Option{
+			Name:        "Proxy Session Token File",
+			Description: "File that stores the authentication token of the workspace proxy. When the file is not empty its token is used instead of --proxy-session-token. Tokens rotated by the primary are written to the file, so replicas sharing it keep authenticating.",
+			Flag:        "proxy-session-token-file",
+			Env:         "CODER_PROXY_SESSION_TOKEN_FILE",
+			YAML:        "proxySessionTokenFile",
+			Value:       &proxySessionTokenFile,
+			Group:       &externalProxyOptionGroup,
+			Hidden:      false,
+		},
 
 		serpent.Option{
 			Name:        "Coderd (Primary) Access URL",
@@ -123,6 +134,11 @@ func (r *RootCmd) proxyServer() *serpent.Command {
 
 			go cli.DumpHandler(ctx, "workspace-proxy")
 
+			err := cli.ValidateFIPS(cfg)
+			if err != nil {
+				return err
+			}
+
 			cli.PrintLogo(inv, "Coder Workspace Proxy")
 			logger, logCloser, err := clilog.New(clilog.FromDeploymentValues(cfg)).Build(inv)
 			if err != nil {
@@ -267,6 +283,7 @@ func (r *RootCmd) proxyServer() *serpent.Command {
 				CookieConfig:           cfg.HTTPCookies,
 				DisablePathApps:        cfg.DisablePathApps.Value(),
 				ProxySessionToken:      proxySessionToken.Value(),
+				ProxySessionTokenFile:  proxySessionTokenFile.Value(),
 				AllowAllCors:           cfg.Dangerous.AllowAllCors.Value(),
 				DERPEnabled:            cfg.DERP.Server.Enable.Value(),
 				DERPOnly:               derpOnly.Value(),
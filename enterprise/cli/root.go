@@ -16,6 +16,7 @@ func (r *RootCmd) enterpriseOnly() []*serpent.Command {
 		r.features(),
 		r.licenses(),
 		r.groups(),
+		r.ldap(),
 		r.prebuilds(),
 		r.provisionerDaemons(),
 		r.provisionerd(),
@@ -8,13 +8,18 @@ import (
 	"encoding/base64"
 	"errors"
 	"io"
+	"net/http"
 	"net/url"
+	"strings"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/xerrors"
 	"tailscale.com/derp"
 	"tailscale.com/types/key"
 
+	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
 	"github.com/coder/coder/v2/enterprise/audit"
 	"github.com/coder/coder/v2/enterprise/audit/backends"
@@ -29,6 +34,53 @@ import (
 	agplcoderd "github.com/coder/coder/v2/coderd"
 )
 
+// auditStreams returns a stream for every configured audit streaming
+// destination.
+func auditStreams(logger slog.Logger, reg prometheus.Registerer, cfg codersdk.AuditStreamingConfig) ([]*backends.Stream, error) {
+	var sinks []backends.StreamSink
+	client := &http.Client{}
+	if u := cfg.SplunkHECURL.String(); u != "" {
+		if cfg.SplunkHECToken.Value() == "" {
+			return nil, xerrors.New("audit-stream-splunk-hec-token is required with audit-stream-splunk-hec-url")
+		}
+		sinks = append(sinks, backends.NewSplunkSink(client, u, cfg.SplunkHECToken.Value()))
+	}
+	if key := cfg.DatadogAPIKey.Value(); key != "" {
+		sinks = append(sinks, backends.NewDatadogSink(client, backends.DatadogLogsURL(cfg.DatadogSite.Value()), key))
+	}
+	if u := cfg.WebhookURL.String(); u != "" {
+		headers := http.Header{}
+		for _, header := range cfg.WebhookHeaders.Value() {
+			name, value, ok := strings.Cut(header, ":")
+			if !ok {
+				return nil, xerrors.Errorf("audit-stream-webhook-headers: %q must be in the form \"Name: value\"", header)
+			}
+			headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+		sinks = append(sinks, backends.NewWebhookSink(client, u, headers))
+	}
+	if addr := cfg.SyslogAddress.Value(); addr != "" {
+		sink, err := backends.NewSyslogSink(addr, nil)
+		if err != nil {
+			return nil, xerrors.Errorf("audit-stream-syslog-address: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+
+	metrics := backends.NewStreamMetrics(reg)
+	streams := make([]*backends.Stream, 0, len(sinks))
+	for _, sink := range sinks {
+		streams = append(streams, backends.NewStream(logger, sink, metrics, backends.StreamOptions{
+			BufferSize:  int(cfg.BufferSize.Value()),
+			MaxAttempts: int(cfg.MaxAttempts.Value()),
+		}))
+	}
+	return streams, nil
+}
+
 func (r *RootCmd) Server(_ func()) *serpent.Command {
 	cmd := r.RootCmd.Server(func(ctx context.Context, options *agplcoderd.Options) (*agplcoderd.API, io.Closer, error) {
 		if options.DeploymentValues.DERP.Server.RelayURL.String() != "" {
@@ -75,11 +127,21 @@ func (r *RootCmd) Server(_ func()) *serpent.Command {
 			options.DERPServer.SetMeshKey(meshKey)
 		}
 
+		streams, err := auditStreams(options.Logger.Named("audit_stream"), options.PrometheusRegistry, options.DeploymentValues.AuditStreaming)
+		if err != nil {
+			return nil, nil, err
+		}
+		auditBackends := []audit.Backend{
+			backends.NewPostgres(options.Database, true),
+			backends.NewSlog(options.Logger),
+		}
+		for _, stream := range streams {
+			auditBackends = append(auditBackends, stream)
+		}
 		options.Auditor = audit.NewAuditor(
 			options.Database,
 			audit.DefaultFilter,
-			backends.NewPostgres(options.Database, true),
-			backends.NewSlog(options.Logger),
+			auditBackends...,
 		)
 
 		options.TrialGenerator = trialer.New(options.Database, "https://v2-licensor.coder.com/trial", coderd.Keys)
@@ -97,6 +159,7 @@ func (r *RootCmd) Server(_ func()) *serpent.Command {
 			ProvisionerDaemonPSK:      options.DeploymentValues.Provisioner.DaemonPSK.Value(),
 
 			CheckInactiveUsersCancelFunc: dormancy.CheckInactiveUsers(ctx, options.Logger, quartz.NewReal(), options.Database, options.Auditor),
+			AuditStreams:                 streams,
 		}
 
 		if encKeys := options.DeploymentValues.ExternalTokenEncryptionKeys.Value(); len(encKeys) != 0 {
@@ -124,6 +187,7 @@ func (r *RootCmd) Server(_ func()) *serpent.Command {
 
 	cmd.AddSubcommands(
 		r.dbcryptCmd(),
+		r.serverRestartCmd(),
 	)
 	return cmd
 }
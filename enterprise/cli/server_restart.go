@@ -0,0 +1,125 @@
+//go:build !slim
+
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	agpl "github.com/coder/coder/v2/cli"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+// restartPollInterval is how often the replicas are fetched to follow the
+// progress of a restart.
+const restartPollInterval = 2 * time.Second
+
+func (r *RootCmd) serverRestartCmd() *serpent.Command {
+	var (
+		rolling      bool
+		drainTimeout time.Duration
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "restart",
+		Short: "Restart all replicas of the deployment.",
+		Long: "Each replica drains by failing its health check, so that load balancers stop sending it requests, " +
+			"and then shuts down gracefully. Replicas must be started again by their supervisor, e.g. Kubernetes or systemd.\n\n" +
+			agpl.FormatExamples(
+				agpl.Example{
+					Description: "Restart replicas one at a time",
+					Command:     "coder server restart --rolling",
+				},
+			),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{
+			{
+				Flag:        "rolling",
+				Description: "Restart replicas one at a time. Each replica waits until the replicas before it have been replaced by healthy replicas, so the deployment stays available. Otherwise all replicas restart at once.",
+				Value:       serpent.BoolOf(&rolling),
+			},
+			{
+				Flag:        "drain-timeout",
+				Description: "How long each replica fails its health check before it shuts down.",
+				Default:     codersdk.DefaultReplicaDrainTimeout.String(),
+				Value:       serpent.DurationOf(&drainTimeout),
+			},
+			cliui.SkipPromptOption(),
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+
+			replicas, err := client.Replicas(ctx)
+			if err != nil {
+				return xerrors.Errorf("get replicas: %w", err)
+			}
+			text := fmt.Sprintf("All %d replicas will restart at once, so the deployment will be unavailable briefly. Continue?", len(replicas))
+			if rolling {
+				text = fmt.Sprintf("%d replicas will restart one at a time. Continue?", len(replicas))
+			}
+			if _, err := cliui.Prompt(inv, cliui.PromptOptions{Text: text, IsConfirm: true}); err != nil {
+				return err
+			}
+
+			restart, err := client.RestartReplicas(ctx, codersdk.RestartReplicasRequest{
+				Rolling:      rolling,
+				DrainTimeout: drainTimeout,
+			})
+			if err != nil {
+				return xerrors.Errorf("restart replicas: %w", err)
+			}
+			hostnames := make(map[uuid.UUID]string, len(replicas))
+			for _, replica := range replicas {
+				hostnames[replica.ID] = replica.Hostname
+			}
+			cliui.Infof(inv.Stdout, "Restarting %d replicas, press ctrl+c to stop following the restart.", len(restart.Replicas))
+
+			// The restart is coordinated by the replicas, so following it only
+			// reports its progress. Requests can fail while the replica that
+			// serves them shuts down.
+			restarted := make(map[uuid.UUID]bool, len(restart.Replicas))
+			ticker := time.NewTicker(restartPollInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-ticker.C:
+				}
+				current, err := client.Replicas(ctx)
+				if err != nil {
+					continue
+				}
+				alive := make(map[uuid.UUID]bool, len(current))
+				healthy := 0
+				for _, replica := range current {
+					alive[replica.ID] = true
+					if replica.Error == "" {
+						healthy++
+					}
+				}
+				for _, id := range restart.Replicas {
+					if restarted[id] || alive[id] {
+						continue
+					}
+					restarted[id] = true
+					_, _ = fmt.Fprintf(inv.Stdout, "Replica %s shut down (%d/%d)\n", pretty.Sprint(cliui.DefaultStyles.Keyword, hostnames[id]), len(restarted), len(restart.Replicas))
+				}
+				if len(restarted) == len(restart.Replicas) && healthy >= len(restart.Replicas) {
+					cliui.Infof(inv.Stdout, "All replicas restarted and %d replicas are healthy.", healthy)
+					return nil
+				}
+			}
+		},
+	}
+	return cmd
+}
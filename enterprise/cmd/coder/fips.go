@@ -0,0 +1,7 @@
+//go:build fips
+
+// FIPS builds run the Go Cryptographic Module in FIPS 140-3 mode, which
+// restricts the standard library to approved algorithms.
+//go:debug fips140=on
+
+package main
@@ -38,6 +38,7 @@ func (api *API) appearance(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	httpapi.SetETag(rw, cfg.ETag())
 	httpapi.Write(r.Context(), rw, http.StatusOK, cfg)
 }
 
@@ -140,6 +141,7 @@ func validateHexColor(color string) error {
 // @Produce json
 // @Tags Enterprise
 // @Param request body codersdk.UpdateAppearanceConfig true "Update appearance request"
+// @Param If-Match header string false "Only apply the change if the resource still has this ETag"
 // @Success 200 {object} codersdk.UpdateAppearanceConfig
 // @Router /appearance [put]
 func (api *API) putAppearance(rw http.ResponseWriter, r *http.Request) {
@@ -157,6 +159,20 @@ func (api *API) putAppearance(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.Header.Get("If-Match") != "" {
+		current, err := (*api.AGPL.AppearanceFetcher.Load()).Fetch(ctx)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to fetch appearance config.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if !httpapi.IfMatch(ctx, rw, r, current.ETag()) {
+			return
+		}
+	}
+
 	for _, banner := range appearance.AnnouncementBanners {
 		if err := validateHexColor(banner.BackgroundColor); err != nil {
 			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -206,5 +222,10 @@ func (api *API) putAppearance(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	httpapi.SetETag(rw, codersdk.AppearanceConfig{
+		ApplicationName:     appearance.ApplicationName,
+		LogoURL:             appearance.LogoURL,
+		AnnouncementBanners: appearance.AnnouncementBanners,
+	}.ETag())
 	httpapi.Write(r.Context(), rw, http.StatusOK, appearance)
 }
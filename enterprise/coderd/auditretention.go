@@ -0,0 +1,272 @@
+package coderd
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get audit settings by organization
+// @ID get-audit-settings-by-organization
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {object} codersdk.AuditSettings
+// @Router /organizations/{organization}/settings/audit [get]
+func (api *API) organizationAuditSettings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	settings, err := api.Database.GetOrganizationAuditSettings(ctx, org.ID)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.AuditSettings{
+		RetentionDays: settings.RetentionDays,
+	})
+}
+
+// @Summary Update audit settings by organization
+// @ID update-audit-settings-by-organization
+// @Security CoderSessionToken
+// @Produce json
+// @Accept json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.AuditSettings true "New settings"
+// @Success 200 {object} codersdk.AuditSettings
+// @Router /organizations/{organization}/settings/audit [patch]
+func (api *API) patchOrganizationAuditSettings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	var req codersdk.AuditSettings
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.RetentionDays < 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Retention days must not be negative.",
+			Validations: []codersdk.ValidationError{
+				{Field: "retention_days", Detail: "must be zero or greater"},
+			},
+		})
+		return
+	}
+
+	settings, err := api.Database.UpsertOrganizationAuditSettings(ctx, database.UpsertOrganizationAuditSettingsParams{
+		OrganizationID: org.ID,
+		RetentionDays:  req.RetentionDays,
+		UpdatedAt:      dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.AuditSettings{
+		RetentionDays: settings.RetentionDays,
+	})
+}
+
+// @Summary Get audit legal holds by organization
+// @ID get-audit-legal-holds-by-organization
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.AuditLegalHold
+// @Router /organizations/{organization}/audit/legal-holds [get]
+func (api *API) auditLegalHolds(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	holds, err := api.Database.GetAuditLogLegalHoldsByOrganizationID(ctx, org.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	resp := make([]codersdk.AuditLegalHold, 0, len(holds))
+	for _, hold := range holds {
+		resp = append(resp, convertAuditLegalHold(hold))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Create audit legal hold
+// @ID create-audit-legal-hold
+// @Security CoderSessionToken
+// @Produce json
+// @Accept json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.CreateAuditLegalHoldRequest true "Legal hold"
+// @Success 201 {object} codersdk.AuditLegalHold
+// @Router /organizations/{organization}/audit/legal-holds [post]
+func (api *API) postAuditLegalHold(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+	apiKey := httpmw.APIKey(r)
+
+	// Authorize before the target is validated so that the existence of users
+	// and workspaces is not leaked.
+	if !api.Authorize(r, policy.ActionUpdate, rbac.ResourceOrganization.WithID(org.ID).InOrg(org.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.CreateAuditLegalHoldRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if (req.UserID == nil) == (req.WorkspaceID == nil) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Exactly one of user_id and workspace_id must be set.",
+		})
+		return
+	}
+
+	//nolint:gocritic // Holds may target users and workspaces the caller can not read.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	params := database.InsertAuditLogLegalHoldParams{
+		ID:             uuid.New(),
+		OrganizationID: org.ID,
+		Reason:         req.Reason,
+		CreatedBy:      apiKey.UserID,
+		CreatedAt:      dbtime.Now(),
+	}
+	if req.UserID != nil {
+		_, err := api.Database.GetUserByID(sysCtx, *req.UserID)
+		if httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "User does not exist.",
+				Validations: []codersdk.ValidationError{
+					{Field: "user_id", Detail: "user does not exist"},
+				},
+			})
+			return
+		}
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		params.UserID = uuid.NullUUID{UUID: *req.UserID, Valid: true}
+	}
+	if req.WorkspaceID != nil {
+		workspace, err := api.Database.GetWorkspaceByID(sysCtx, *req.WorkspaceID)
+		if err != nil && !httpapi.Is404Error(err) {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		if err != nil || workspace.OrganizationID != org.ID {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Workspace does not exist in this organization.",
+				Validations: []codersdk.ValidationError{
+					{Field: "workspace_id", Detail: "workspace does not exist in this organization"},
+				},
+			})
+			return
+		}
+		params.WorkspaceID = uuid.NullUUID{UUID: *req.WorkspaceID, Valid: true}
+	}
+
+	hold, err := api.Database.InsertAuditLogLegalHold(ctx, params)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertAuditLegalHold(hold))
+}
+
+// @Summary Delete audit legal hold
+// @ID delete-audit-legal-hold
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param legalhold path string true "Legal hold ID" format(uuid)
+// @Success 204
+// @Router /organizations/{organization}/audit/legal-holds/{legalhold} [delete]
+func (api *API) deleteAuditLegalHold(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	holdID, ok := httpmw.ParseUUIDParam(rw, r, "legalhold")
+	if !ok {
+		return
+	}
+
+	hold, err := api.Database.GetAuditLogLegalHoldByID(ctx, holdID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if hold.OrganizationID != org.ID {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	err = api.Database.DeleteAuditLogLegalHoldByID(ctx, hold.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func convertAuditLegalHold(hold database.AuditLogLegalHold) codersdk.AuditLegalHold {
+	sdk := codersdk.AuditLegalHold{
+		ID:             hold.ID,
+		OrganizationID: hold.OrganizationID,
+		Reason:         hold.Reason,
+		CreatedBy:      hold.CreatedBy,
+		CreatedAt:      hold.CreatedAt,
+	}
+	if hold.UserID.Valid {
+		sdk.UserID = &hold.UserID.UUID
+	}
+	if hold.WorkspaceID.Valid {
+		sdk.WorkspaceID = &hold.WorkspaceID.UUID
+	}
+	return sdk
+}
@@ -0,0 +1,185 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestOrganizationAuditSettings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		owner, user := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureAuditLog: 1,
+				},
+			},
+		})
+		orgAdmin, _ := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID, rbac.ScopedRoleOrgAdmin(user.OrganizationID))
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		settings, err := orgAdmin.OrganizationAuditSettings(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Zero(t, settings.RetentionDays)
+
+		settings, err = orgAdmin.PatchOrganizationAuditSettings(ctx, user.OrganizationID, codersdk.AuditSettings{RetentionDays: 90})
+		require.NoError(t, err)
+		require.EqualValues(t, 90, settings.RetentionDays)
+
+		settings, err = orgAdmin.OrganizationAuditSettings(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.EqualValues(t, 90, settings.RetentionDays)
+	})
+
+	t.Run("Negative", func(t *testing.T) {
+		t.Parallel()
+
+		owner, user := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureAuditLog: 1,
+				},
+			},
+		})
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := owner.PatchOrganizationAuditSettings(ctx, user.OrganizationID, codersdk.AuditSettings{RetentionDays: -1})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberCannotUpdate", func(t *testing.T) {
+		t.Parallel()
+
+		owner, user := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureAuditLog: 1,
+				},
+			},
+		})
+		member, _ := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.PatchOrganizationAuditSettings(ctx, user.OrganizationID, codersdk.AuditSettings{RetentionDays: 30})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
+func TestAuditLegalHolds(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		owner, db, user := coderdenttest.NewWithDatabase(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureAuditLog: 1,
+				},
+			},
+		})
+		_, member := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID)
+		ws := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        member.ID,
+		}).Do()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		userHold, err := owner.CreateAuditLegalHold(ctx, user.OrganizationID, codersdk.CreateAuditLegalHoldRequest{
+			UserID: ptr.Ref(member.ID),
+			Reason: "litigation",
+		})
+		require.NoError(t, err)
+		require.Equal(t, member.ID, *userHold.UserID)
+		require.Nil(t, userHold.WorkspaceID)
+		require.Equal(t, user.UserID, userHold.CreatedBy)
+
+		workspaceHold, err := owner.CreateAuditLegalHold(ctx, user.OrganizationID, codersdk.CreateAuditLegalHoldRequest{
+			WorkspaceID: ptr.Ref(ws.Workspace.ID),
+			Reason:      "investigation",
+		})
+		require.NoError(t, err)
+		require.Equal(t, ws.Workspace.ID, *workspaceHold.WorkspaceID)
+
+		holds, err := owner.AuditLegalHolds(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, holds, 2)
+
+		err = owner.DeleteAuditLegalHold(ctx, user.OrganizationID, userHold.ID)
+		require.NoError(t, err)
+
+		holds, err = owner.AuditLegalHolds(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, holds, 1)
+		require.Equal(t, workspaceHold.ID, holds[0].ID)
+	})
+
+	t.Run("InvalidTarget", func(t *testing.T) {
+		t.Parallel()
+
+		owner, user := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureAuditLog: 1,
+				},
+			},
+		})
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		for _, req := range []codersdk.CreateAuditLegalHoldRequest{
+			// Neither target.
+			{Reason: "none"},
+			// Both targets.
+			{UserID: ptr.Ref(user.UserID), WorkspaceID: ptr.Ref(uuid.New()), Reason: "both"},
+			// A workspace that does not exist.
+			{WorkspaceID: ptr.Ref(uuid.New()), Reason: "missing"},
+		} {
+			_, err := owner.CreateAuditLegalHold(ctx, user.OrganizationID, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), req.Reason)
+		}
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+
+		owner, user := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureAuditLog: 1,
+				},
+			},
+		})
+		member, memberUser := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.CreateAuditLegalHold(ctx, user.OrganizationID, codersdk.CreateAuditLegalHoldRequest{
+			UserID: ptr.Ref(memberUser.ID),
+			Reason: "self",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
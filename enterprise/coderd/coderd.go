@@ -13,15 +13,18 @@ import (
 	"time"
 
 	"github.com/coder/quartz"
+	"github.com/google/uuid"
 
 	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/coderd/appearance"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/entitlements"
 	"github.com/coder/coder/v2/coderd/idpsync"
+	"github.com/coder/coder/v2/coderd/internalca"
 	agplportsharing "github.com/coder/coder/v2/coderd/portsharing"
 	agplprebuilds "github.com/coder/coder/v2/coderd/prebuilds"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
 	"github.com/coder/coder/v2/enterprise/coderd/enidpsync"
 	"github.com/coder/coder/v2/enterprise/coderd/portsharing"
 
@@ -45,6 +48,7 @@ import (
 	agplschedule "github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/coderd/dbauthz"
+	"github.com/coder/coder/v2/enterprise/coderd/ldapsync"
 	"github.com/coder/coder/v2/enterprise/coderd/license"
 	"github.com/coder/coder/v2/enterprise/coderd/prebuilds"
 	"github.com/coder/coder/v2/enterprise/coderd/proxyhealth"
@@ -145,6 +149,14 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		}
 	}()
 
+	if options.Alerter != nil {
+		options.Alerter.RegisterCheck("license_expiry", license.ExpiryCheck(
+			options.Database,
+			options.LicenseKeys,
+			options.DeploymentValues.Alerting.LicenseExpiryThreshold.Value(),
+		))
+	}
+
 	api.AGPL.Options.ParseLicenseClaims = func(rawJWT string) (email string, trial bool, err error) {
 		c, err := license.ParseClaims(rawJWT, Keys)
 		if err != nil {
@@ -159,7 +171,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		if ok && api.Authorizer.Authorize(ctx, actor, policy.ActionRead, rbac.ResourceWorkspaceProxy) == nil {
 			return api.fetchWorkspaceProxies(ctx)
 		}
-		return api.fetchRegions(ctx)
+		return api.fetchRegions(ctx, nil)
 	}
 	api.tailnetService, err = tailnet.NewClientService(agpltailnet.ClientServiceOptions{
 		Logger:                  api.Logger.Named("tailnetclient"),
@@ -216,6 +228,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		r.Route("/replicas", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Get("/", api.replicas)
+			r.Post("/restart", api.postReplicasRestart)
 		})
 		r.Route("/licenses", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
@@ -223,6 +236,19 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Post("/", api.postLicense)
 			r.Get("/", api.licenses)
 			r.Delete("/{id}", api.deleteLicense)
+			r.Get("/usage", api.licenseUsage)
+			r.Route("/reservations", func(r chi.Router) {
+				r.Get("/", api.licenseSeatReservations)
+				r.Put("/{group}", api.putLicenseSeatReservation)
+				r.Delete("/{group}", api.deleteLicenseSeatReservation)
+			})
+		})
+		r.Route("/ldap", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.RequireFeatureMW(codersdk.FeatureSCIM),
+			)
+			r.Post("/sync", api.postLDAPSync)
 		})
 		r.Route("/applications/reconnecting-pty-signed-token", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
@@ -238,6 +264,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				)
 				r.Post("/", api.postWorkspaceProxy)
 				r.Get("/", api.workspaceProxies)
+				r.Get("/tokens", api.workspaceProxyTokens)
 			})
 			r.Route("/me", func(r chi.Router) {
 				r.Use(
@@ -246,12 +273,21 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 						Optional: false,
 					}),
 				)
-				r.Get("/coordinate", api.workspaceProxyCoordinate)
-				r.Post("/issue-signed-app-token", api.workspaceProxyIssueSignedAppToken)
-				r.Post("/app-stats", api.workspaceProxyReportAppStats)
-				r.Post("/register", api.workspaceProxyRegister)
-				r.Post("/deregister", api.workspaceProxyDeregister)
-				r.Get("/crypto-keys", api.workspaceProxyCryptoKeys)
+				// Proxies without a certificate must be able to request
+				// one when mTLS is enforced.
+				r.Post("/certificate", api.workspaceProxyIssueCertificate)
+				r.Group(func(r chi.Router) {
+					r.Use(api.AGPL.RequireClientCertificateMW(internalca.KindWorkspaceProxy, func(r *http.Request) uuid.UUID {
+						return httpmw.WorkspaceProxy(r).ID
+					}))
+					r.Get("/coordinate", api.workspaceProxyCoordinate)
+					r.Post("/issue-signed-app-token", api.workspaceProxyIssueSignedAppToken)
+					r.Post("/app-stats", api.workspaceProxyReportAppStats)
+					r.Post("/register", api.workspaceProxyRegister)
+					r.Post("/deregister", api.workspaceProxyDeregister)
+					r.Post("/token-rotation", api.workspaceProxyRotateToken)
+					r.Get("/crypto-keys", api.workspaceProxyCryptoKeys)
+				})
 			})
 			r.Route("/{workspaceproxy}", func(r chi.Router) {
 				r.Use(
@@ -290,6 +326,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				httpmw.ExtractOrganizationParam(api.Database),
 			)
 			r.Post("/organizations/{organization}/members/roles", api.postOrgRoles)
+			r.Post("/organizations/{organization}/members/roles/simulate", api.simulateOrgRole)
 			r.Put("/organizations/{organization}/members/roles", api.putOrgRoles)
 			r.Delete("/organizations/{organization}/members/roles/{roleName}", api.deleteOrgRole)
 		})
@@ -327,8 +364,29 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Patch("/idpsync/roles/config", api.patchRoleIDPSyncConfig)
 				r.Patch("/idpsync/roles/mapping", api.patchRoleIDPSyncMapping)
 
+				r.Post("/idpsync/preview", api.previewIDPSync)
+
 				r.Get("/idpsync/available-fields", api.organizationIDPSyncClaimFields)
 				r.Get("/idpsync/field-values", api.organizationIDPSyncClaimFieldValues)
+
+				r.With(api.RequireFeatureMW(codersdk.FeatureAuditLog)).Get("/audit", api.organizationAuditSettings)
+				r.With(api.RequireFeatureMW(codersdk.FeatureAuditLog)).Patch("/audit", api.patchOrganizationAuditSettings)
+
+				r.With(api.RequireFeatureMW(codersdk.FeatureMultipleOrganizations)).Get("/overrides", api.organizationSettings)
+				r.With(api.RequireFeatureMW(codersdk.FeatureMultipleOrganizations)).Put("/overrides", api.putOrganizationSettingOverrides)
+			})
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.RequireFeatureMW(codersdk.FeatureAuditLog),
+				httpmw.ExtractOrganizationParam(api.Database),
+			)
+			r.Route("/organizations/{organization}/audit/legal-holds", func(r chi.Router) {
+				r.Get("/", api.auditLegalHolds)
+				r.Post("/", api.postAuditLegalHold)
+				r.Delete("/{legalhold}", api.deleteAuditLegalHold)
 			})
 		})
 
@@ -395,21 +453,43 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		//
 		// We may in future decide to scope provisioner daemons to organizations, so we'll keep the API
 		// route as is.
+		provisionerDaemonAuthMiddleware := []func(http.Handler) http.Handler{
+			api.provisionerDaemonsEnabledMW,
+			apiKeyMiddlewareOptional,
+			httpmw.ExtractProvisionerDaemonAuthenticated(httpmw.ExtractProvisionerAuthConfig{
+				DB:       api.Database,
+				Optional: true,
+				PSK:      api.ProvisionerDaemonPSK,
+			}),
+			// Either a user auth or provisioner auth is required
+			// to move forward.
+			httpmw.RequireAPIKeyOrProvisionerDaemonAuth(),
+			httpmw.ExtractOrganizationParam(api.Database),
+		}
 		r.Route("/organizations/{organization}/provisionerdaemons/serve", func(r chi.Router) {
+			r.Use(provisionerDaemonAuthMiddleware...)
+			r.Use(api.AGPL.RequireClientCertificateMW(internalca.KindProvisionerDaemon, provisionerDaemonCertificateID))
+			r.Get("/", api.provisionerDaemonServe)
+		})
+		r.Route("/organizations/{organization}/provisionerdaemons/certificate", func(r chi.Router) {
+			r.Use(provisionerDaemonAuthMiddleware...)
+			r.Post("/", api.provisionerDaemonIssueCertificate)
+		})
+		r.Group(func(r chi.Router) {
 			r.Use(
-				api.provisionerDaemonsEnabledMW,
-				apiKeyMiddlewareOptional,
-				httpmw.ExtractProvisionerDaemonAuthenticated(httpmw.ExtractProvisionerAuthConfig{
-					DB:       api.Database,
-					Optional: true,
-					PSK:      api.ProvisionerDaemonPSK,
-				}),
-				// Either a user auth or provisioner auth is required
-				// to move forward.
-				httpmw.RequireAPIKeyOrProvisionerDaemonAuth(),
-				httpmw.ExtractOrganizationParam(api.Database),
+				apiKeyMiddleware,
+				api.RequireFeatureMW(codersdk.FeatureMultipleOrganizations),
+				httpmw.ExtractTemplateParam(api.Database),
 			)
-			r.Get("/", api.provisionerDaemonServe)
+			r.Post("/templates/{template}/transfer", api.transferTemplate)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.RequireFeatureMW(codersdk.FeatureAdvancedTemplateScheduling),
+				httpmw.ExtractTemplateParam(api.Database),
+			)
+			r.Post("/templates/{template}/schedule/impact", api.postTemplateScheduleImpact)
 		})
 		r.Route("/templates/{template}/acl", func(r chi.Router) {
 			r.Use(
@@ -434,6 +514,8 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Get("/", api.group)
 				r.Patch("/", api.patchGroup)
 				r.Delete("/", api.deleteGroup)
+				r.Get("/quota-allowances", api.groupQuotaAllowances)
+				r.Put("/quota-allowances", api.putGroupQuotaAllowances)
 			})
 		})
 		r.Route("/workspace-quota", func(r chi.Router) {
@@ -505,6 +587,15 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Patch("/{id}", api.scimPatchUser)
 				r.Put("/{id}", api.scimPutUser)
 			})
+			r.Post("/Groups", api.scimPostGroup)
+			r.Route("/Groups", func(r chi.Router) {
+				r.Get("/", api.scimGetGroups)
+				r.Post("/", api.scimPostGroup)
+				r.Get("/{id}", api.scimGetGroup)
+				r.Patch("/{id}", api.scimPatchGroup)
+				r.Put("/{id}", api.scimPutGroup)
+				r.Delete("/{id}", api.scimDeleteGroup)
+			})
 			r.NotFound(func(w http.ResponseWriter, r *http.Request) {
 				u := r.URL.String()
 				httpapi.Write(r.Context(), w, http.StatusNotFound, codersdk.Response{
@@ -540,6 +631,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		RegionID:       int32(options.DERPServerRegionID),
 		TLSConfig:      meshTLSConfig,
 		UpdateInterval: options.ReplicaSyncUpdateInterval,
+		Restart:        api.AGPL.Drain,
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("initialize replica: %w", err)
@@ -568,6 +660,11 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 	// Use proxy health to return the healthy workspace proxy hostnames.
 	f := api.ProxyHealth.ProxyHosts
 	api.AGPL.WorkspaceProxyHostsFn.Store(&f)
+	regionsFn := func(ctx context.Context) ([]codersdk.Region, error) {
+		regions, err := api.fetchRegions(ctx, nil)
+		return regions.Regions, err
+	}
+	api.AGPL.RegionsFn.Store(&regionsFn)
 
 	// Wire this up to healthcheck.
 	var fetchUpdater healthcheck.WorkspaceProxiesFetchUpdater = &workspaceProxiesFetchUpdater{
@@ -576,6 +673,32 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 	}
 	api.AGPL.WorkspaceProxiesFetchUpdater.Store(&fetchUpdater)
 
+	if options.DeploymentValues.LDAP.URL.String() != "" {
+		loginType := database.LoginType(options.DeploymentValues.LDAP.LoginType.String())
+		switch loginType {
+		case database.LoginTypePassword, database.LoginTypeOIDC, database.LoginTypeGithub, database.LoginTypeSaml, database.LoginTypeNone:
+		default:
+			return nil, xerrors.Errorf("invalid ldap login type %q", loginType)
+		}
+		directory, err := ldapsync.NewLDAPDirectory(options.DeploymentValues.LDAP)
+		if err != nil {
+			return nil, xerrors.Errorf("configure ldap sync: %w", err)
+		}
+		api.ldapSyncer = ldapsync.New(ldapsync.Options{
+			Logger:     options.Logger,
+			Database:   options.Database,
+			Auditor:    &api.AGPL.Auditor,
+			Directory:  directory,
+			IDPSync:    options.IDPSync,
+			CreateUser: api.AGPL.CreateUser,
+			LoginType:  loginType,
+		})
+		// LDAP sync shares the user provisioning entitlement with SCIM.
+		api.ldapSyncCancel = api.ldapSyncer.Start(ctx, quartz.NewReal(), options.DeploymentValues.LDAP.SyncInterval.Value(), func() bool {
+			return api.Entitlements.Enabled(codersdk.FeatureSCIM)
+		})
+	}
+
 	err = api.PrometheusRegistry.Register(api.licenseMetricsCollector)
 	if err != nil {
 		return nil, xerrors.Errorf("unable to register license metrics collector")
@@ -618,6 +741,9 @@ type Options struct {
 	ProvisionerDaemonPSK string
 
 	CheckInactiveUsersCancelFunc func()
+	// AuditStreams are closed after the API, so that the audit logs of the
+	// last requests are delivered.
+	AuditStreams []*backends.Stream
 }
 
 type API struct {
@@ -640,6 +766,10 @@ type API struct {
 
 	licenseMetricsCollector *license.MetricsCollector
 	tailnetService          *tailnet.ClientService
+
+	// ldapSyncer is nil unless LDAP sync is configured.
+	ldapSyncer     *ldapsync.Syncer
+	ldapSyncCancel func()
 }
 
 // writeEntitlementWarningsHeader writes the entitlement warnings to the response header
@@ -670,8 +800,15 @@ func (api *API) Close() error {
 	if api.Options.CheckInactiveUsersCancelFunc != nil {
 		api.Options.CheckInactiveUsersCancelFunc()
 	}
+	if api.ldapSyncCancel != nil {
+		api.ldapSyncCancel()
+	}
 
-	return api.AGPL.Close()
+	err := api.AGPL.Close()
+	for _, stream := range api.Options.AuditStreams {
+		_ = stream.Close()
+	}
+	return err
 }
 
 func (api *API) updateEntitlements(ctx context.Context) error {
@@ -696,7 +833,7 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 			len(agedReplicas), len(api.ExternalAuthConfigs), api.LicenseKeys, map[codersdk.FeatureName]bool{
 				codersdk.FeatureAuditLog:                   api.AuditLogging,
 				codersdk.FeatureBrowserOnly:                api.BrowserOnly,
-				codersdk.FeatureSCIM:                       len(api.SCIMAPIKey) != 0,
+				codersdk.FeatureSCIM:                       len(api.SCIMAPIKey) != 0 || api.DeploymentValues.LDAP.URL.String() != "",
 				codersdk.FeatureMultipleExternalAuth:       len(api.ExternalAuthConfigs) > 1,
 				codersdk.FeatureTemplateRBAC:               api.RBAC,
 				codersdk.FeatureExternalTokenEncryption:    len(api.ExternalTokenEncryption) > 0,
@@ -757,7 +894,7 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 		if initial, changed, enabled := featureChanged(codersdk.FeatureAdvancedTemplateScheduling); shouldUpdate(initial, changed, enabled) {
 			if enabled {
 				templateStore := schedule.NewEnterpriseTemplateScheduleStore(api.AGPL.UserQuietHoursScheduleStore, api.NotificationsEnqueuer, api.Logger.Named("template.schedule-store"), api.Clock)
-				templateStoreInterface := agplschedule.TemplateScheduleStore(templateStore)
+				templateStoreInterface := agplschedule.NewHolidayTemplateScheduleStore(templateStore, api.AGPL.AutostartHolidays)
 				api.AGPL.TemplateScheduleStore.Store(&templateStoreInterface)
 
 				if api.DefaultQuietHoursSchedule == "" {
@@ -771,7 +908,7 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 					api.AGPL.UserQuietHoursScheduleStore.Store(&quietHoursStore)
 				}
 			} else {
-				templateStore := agplschedule.NewAGPLTemplateScheduleStore()
+				templateStore := agplschedule.NewHolidayTemplateScheduleStore(agplschedule.NewAGPLTemplateScheduleStore(), api.AGPL.AutostartHolidays)
 				api.AGPL.TemplateScheduleStore.Store(&templateStore)
 				quietHoursStore := agplschedule.NewAGPLUserQuietHoursScheduleStore()
 				api.AGPL.UserQuietHoursScheduleStore.Store(&quietHoursStore)
@@ -780,17 +917,34 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 
 		if initial, changed, enabled := featureChanged(codersdk.FeatureHighAvailability); shouldUpdate(initial, changed, enabled) {
 			var coordinator agpltailnet.Coordinator
+			var redirect func(rw http.ResponseWriter, r *http.Request, agentID uuid.UUID) bool
 			if enabled {
+				var sharded *tailnet.ShardedCoordinator
 				haCoordinator, err := tailnet.NewPGCoord(api.ctx, api.Logger, api.Pubsub, api.Database)
 				if err != nil {
 					api.Logger.Error(ctx, "unable to set up high availability coordinator", slog.Error(err))
 					// If we try to setup the HA coordinator and it fails, nothing
 					// is actually changing.
+				} else if api.DeploymentValues.TailnetCoordinatorSharding.Value() {
+					sharded = tailnet.NewShardedCoordinator(api.Logger, api.replicaManager.ID(), haCoordinator)
+					sharded.SetReplicas(api.replicaManager.AllPrimary())
+					coordinator = sharded
+					redirect = func(rw http.ResponseWriter, r *http.Request, agentID uuid.UUID) bool {
+						u, ok := sharded.RedirectURL(r, agentID)
+						if !ok {
+							return false
+						}
+						http.Redirect(rw, r, u.String(), http.StatusTemporaryRedirect)
+						return true
+					}
 				} else {
 					coordinator = haCoordinator
 				}
 
 				api.replicaManager.SetCallback(func() {
+					if sharded != nil {
+						sharded.SetReplicas(api.replicaManager.AllPrimary())
+					}
 					// Only update DERP mesh if the built-in server is enabled.
 					if api.Options.DeploymentValues.DERP.Server.Enable {
 						addresses := make([]string, 0)
@@ -819,6 +973,7 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 
 			// Recheck changed in case the HA coordinator failed to set up.
 			if coordinator != nil {
+				api.AGPL.TailnetCoordinatorRedirect.Store(&redirect)
 				oldCoordinator := *api.AGPL.TailnetCoordinator.Swap(&coordinator)
 				err := oldCoordinator.Close()
 				if err != nil {
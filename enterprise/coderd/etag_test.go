@@ -0,0 +1,134 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestETags(t *testing.T) {
+	t.Parallel()
+
+	client, owner := coderdenttest.New(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		},
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureMultipleOrganizations: 1,
+				codersdk.FeatureTemplateRBAC:          1,
+				codersdk.FeatureAppearance:            1,
+			},
+		},
+	})
+
+	requirePreconditionFailed := func(t *testing.T, err error) {
+		t.Helper()
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusPreconditionFailed, apiErr.StatusCode())
+	}
+
+	t.Run("Organization", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		org := coderdenttest.CreateOrganization(t, client, coderdenttest.CreateOrganizationOptions{})
+
+		// The response carries the tag clients compute themselves.
+		res, err := client.Request(ctx, http.MethodGet, "/api/v2/organizations/"+org.Name, nil)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, org.ETag(), res.Header.Get("ETag"))
+
+		updated, err := client.UpdateOrganization(ctx, org.Name, codersdk.UpdateOrganizationRequest{
+			DisplayName: "First",
+		}, codersdk.WithIfMatch(org.ETag()))
+		require.NoError(t, err)
+
+		// The stale tag no longer matches.
+		_, err = client.UpdateOrganization(ctx, org.Name, codersdk.UpdateOrganizationRequest{
+			DisplayName: "Second",
+		}, codersdk.WithIfMatch(org.ETag()))
+		requirePreconditionFailed(t, err)
+		err = client.DeleteOrganization(ctx, org.Name, codersdk.WithIfMatch(org.ETag()))
+		requirePreconditionFailed(t, err)
+
+		err = client.DeleteOrganization(ctx, org.Name, codersdk.WithIfMatch(updated.ETag()))
+		require.NoError(t, err)
+	})
+
+	t.Run("Group", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		group, err := client.CreateGroup(ctx, owner.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "etag",
+		})
+		require.NoError(t, err)
+
+		// Import by name returns the same tag.
+		byName, err := client.GroupByOrgAndName(ctx, owner.OrganizationID, group.Name)
+		require.NoError(t, err)
+		require.Equal(t, group.ETag(), byName.ETag())
+
+		updated, err := client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{member.ID.String()},
+		}, codersdk.WithIfMatch(group.ETag()))
+		require.NoError(t, err)
+		require.NotEqual(t, group.ETag(), updated.ETag())
+
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			QuotaAllowance: ptr.Ref(10),
+		}, codersdk.WithIfMatch(group.ETag()))
+		requirePreconditionFailed(t, err)
+
+		err = client.DeleteGroup(ctx, group.ID, codersdk.WithIfMatch(updated.ETag()))
+		require.NoError(t, err)
+	})
+
+	t.Run("Template", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "first",
+		}, codersdk.WithIfMatch(template.ETag()))
+		require.NoError(t, err)
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "second",
+		}, codersdk.WithIfMatch(template.ETag()))
+		requirePreconditionFailed(t, err)
+
+		err = client.DeleteTemplate(ctx, template.ID, codersdk.WithIfMatch(updated.ETag()))
+		require.NoError(t, err)
+	})
+
+	t.Run("Appearance", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		appearance, err := client.Appearance(ctx)
+		require.NoError(t, err)
+
+		err = client.UpdateAppearance(ctx, codersdk.UpdateAppearanceConfig{
+			ApplicationName: "First",
+		}, codersdk.WithIfMatch(appearance.ETag()))
+		require.NoError(t, err)
+
+		err = client.UpdateAppearance(ctx, codersdk.UpdateAppearanceConfig{
+			ApplicationName: "Second",
+		}, codersdk.WithIfMatch(appearance.ETag()))
+		requirePreconditionFailed(t, err)
+	})
+}
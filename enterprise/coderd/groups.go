@@ -94,6 +94,7 @@ func (api *API) postGroupByOrganization(rw http.ResponseWriter, r *http.Request)
 // @Tags Enterprise
 // @Param group path string true "Group name"
 // @Param request body codersdk.PatchGroupRequest true "Patch group request"
+// @Param If-Match header string false "Only apply the change if the resource still has this ETag"
 // @Success 200 {object} codersdk.Group
 // @Router /groups/{group} [patch]
 func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
@@ -163,6 +164,9 @@ func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 	aReq.Old = group.Auditable(currentMembers)
+	if !httpapi.IfMatch(ctx, rw, r, db2sdk.Group(database.GetGroupsRow{Group: group}, currentMembers, len(currentMembers)).ETag()) {
+		return
+	}
 
 	for _, id := range users {
 		if _, err := uuid.Parse(id); err != nil {
@@ -314,11 +318,13 @@ func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.Group(database.GetGroupsRow{
+	resp := db2sdk.Group(database.GetGroupsRow{
 		Group:                   group,
 		OrganizationName:        org.Name,
 		OrganizationDisplayName: org.DisplayName,
-	}, patchedMembers, int(memberCount)))
+	}, patchedMembers, int(memberCount))
+	httpapi.SetETag(rw, resp.ETag())
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
 // @Summary Delete group by name
@@ -327,6 +333,7 @@ func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Tags Enterprise
 // @Param group path string true "Group name"
+// @Param If-Match header string false "Only apply the change if the resource still has this ETag"
 // @Success 200 {object} codersdk.Group
 // @Router /groups/{group} [delete]
 func (api *API) deleteGroup(rw http.ResponseWriter, r *http.Request) {
@@ -361,6 +368,9 @@ func (api *API) deleteGroup(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	aReq.Old = group.Auditable(groupMembers)
+	if !httpapi.IfMatch(ctx, rw, r, db2sdk.Group(database.GetGroupsRow{Group: group}, groupMembers, len(groupMembers)).ETag()) {
+		return
+	}
 
 	err := api.Database.DeleteGroupByID(ctx, group.ID)
 	if err != nil {
@@ -423,11 +433,13 @@ func (api *API) group(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.Group(database.GetGroupsRow{
+	resp := db2sdk.Group(database.GetGroupsRow{
 		Group:                   group,
 		OrganizationName:        org.Name,
 		OrganizationDisplayName: org.DisplayName,
-	}, users, int(memberCount)))
+	}, users, int(memberCount))
+	httpapi.SetETag(rw, resp.ETag())
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
 // @Summary Get groups by organization
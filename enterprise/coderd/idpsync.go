@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"slices"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 
 	"github.com/coder/coder/v2/coderd/audit"
@@ -15,6 +16,7 @@ import (
 	"github.com/coder/coder/v2/coderd/idpsync"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
 )
@@ -109,6 +111,8 @@ func (api *API) patchGroupIDPSyncSettings(rw http.ResponseWriter, r *http.Reques
 		RegexFilter:       req.RegexFilter,
 		AutoCreateMissing: req.AutoCreateMissing,
 		LegacyNameMapping: req.LegacyNameMapping,
+		Rules:             req.Rules,
+		DefaultGroups:     req.DefaultGroups,
 	})
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
@@ -128,6 +132,8 @@ func (api *API) patchGroupIDPSyncSettings(rw http.ResponseWriter, r *http.Reques
 		RegexFilter:       settings.RegexFilter,
 		AutoCreateMissing: settings.AutoCreateMissing,
 		LegacyNameMapping: settings.LegacyNameMapping,
+		Rules:             settings.Rules,
+		DefaultGroups:     settings.DefaultGroups,
 	})
 }
 
@@ -180,6 +186,8 @@ func (api *API) patchGroupIDPSyncConfig(rw http.ResponseWriter, r *http.Request)
 			AutoCreateMissing: req.AutoCreateMissing,
 			LegacyNameMapping: existing.LegacyNameMapping,
 			Mapping:           existing.Mapping,
+			Rules:             existing.Rules,
+			DefaultGroups:     existing.DefaultGroups,
 		}
 
 		err = api.IDPSync.UpdateGroupSyncSettings(sysCtx, org.ID, tx, settings)
@@ -200,6 +208,8 @@ func (api *API) patchGroupIDPSyncConfig(rw http.ResponseWriter, r *http.Request)
 		RegexFilter:       settings.RegexFilter,
 		AutoCreateMissing: settings.AutoCreateMissing,
 		LegacyNameMapping: settings.LegacyNameMapping,
+		Rules:             settings.Rules,
+		DefaultGroups:     settings.DefaultGroups,
 		Mapping:           settings.Mapping,
 	})
 }
@@ -254,6 +264,8 @@ func (api *API) patchGroupIDPSyncMapping(rw http.ResponseWriter, r *http.Request
 			AutoCreateMissing: existing.AutoCreateMissing,
 			LegacyNameMapping: existing.LegacyNameMapping,
 			Mapping:           newMapping,
+			Rules:             existing.Rules,
+			DefaultGroups:     existing.DefaultGroups,
 		}
 
 		err = api.IDPSync.UpdateGroupSyncSettings(sysCtx, org.ID, tx, settings)
@@ -274,6 +286,8 @@ func (api *API) patchGroupIDPSyncMapping(rw http.ResponseWriter, r *http.Request
 		RegexFilter:       settings.RegexFilter,
 		AutoCreateMissing: settings.AutoCreateMissing,
 		LegacyNameMapping: settings.LegacyNameMapping,
+		Rules:             settings.Rules,
+		DefaultGroups:     settings.DefaultGroups,
 		Mapping:           settings.Mapping,
 	})
 }
@@ -350,8 +364,10 @@ func (api *API) patchRoleIDPSyncSettings(rw http.ResponseWriter, r *http.Request
 	aReq.Old = *existing
 
 	err = api.IDPSync.UpdateRoleSyncSettings(sysCtx, org.ID, api.Database, idpsync.RoleSyncSettings{
-		Field:   req.Field,
-		Mapping: req.Mapping,
+		Field:        req.Field,
+		Mapping:      req.Mapping,
+		Rules:        req.Rules,
+		DefaultRoles: req.DefaultRoles,
 	})
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
@@ -366,8 +382,10 @@ func (api *API) patchRoleIDPSyncSettings(rw http.ResponseWriter, r *http.Request
 
 	aReq.New = *settings
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.RoleSyncSettings{
-		Field:   settings.Field,
-		Mapping: settings.Mapping,
+		Field:        settings.Field,
+		Mapping:      settings.Mapping,
+		Rules:        settings.Rules,
+		DefaultRoles: settings.DefaultRoles,
 	})
 }
 
@@ -415,8 +433,10 @@ func (api *API) patchRoleIDPSyncConfig(rw http.ResponseWriter, r *http.Request)
 		aReq.Old = *existing
 
 		settings = idpsync.RoleSyncSettings{
-			Field:   req.Field,
-			Mapping: existing.Mapping,
+			Field:        req.Field,
+			Mapping:      existing.Mapping,
+			Rules:        existing.Rules,
+			DefaultRoles: existing.DefaultRoles,
 		}
 
 		err = api.IDPSync.UpdateRoleSyncSettings(sysCtx, org.ID, tx, settings)
@@ -433,8 +453,10 @@ func (api *API) patchRoleIDPSyncConfig(rw http.ResponseWriter, r *http.Request)
 
 	aReq.New = settings
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.RoleSyncSettings{
-		Field:   settings.Field,
-		Mapping: settings.Mapping,
+		Field:        settings.Field,
+		Mapping:      settings.Mapping,
+		Rules:        settings.Rules,
+		DefaultRoles: settings.DefaultRoles,
 	})
 }
 
@@ -483,8 +505,10 @@ func (api *API) patchRoleIDPSyncMapping(rw http.ResponseWriter, r *http.Request)
 
 		newMapping := applyIDPSyncMappingDiff(existing.Mapping, req.Add, req.Remove)
 		settings = idpsync.RoleSyncSettings{
-			Field:   existing.Field,
-			Mapping: newMapping,
+			Field:        existing.Field,
+			Mapping:      newMapping,
+			Rules:        existing.Rules,
+			DefaultRoles: existing.DefaultRoles,
 		}
 
 		err = api.IDPSync.UpdateRoleSyncSettings(sysCtx, org.ID, tx, settings)
@@ -501,8 +525,10 @@ func (api *API) patchRoleIDPSyncMapping(rw http.ResponseWriter, r *http.Request)
 
 	aReq.New = settings
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.RoleSyncSettings{
-		Field:   settings.Field,
-		Mapping: settings.Mapping,
+		Field:        settings.Field,
+		Mapping:      settings.Mapping,
+		Rules:        settings.Rules,
+		DefaultRoles: settings.DefaultRoles,
 	})
 }
 
@@ -843,6 +869,143 @@ func (api *API) idpSyncClaimFieldValues(orgID uuid.UUID, rw http.ResponseWriter,
 	httpapi.Write(ctx, rw, http.StatusOK, fieldValues)
 }
 
+// @Summary Preview IdP Sync for claims
+// @ID preview-idp-sync-for-claims
+// @Security CoderSessionToken
+// @Produce json
+// @Accept json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.IDPSyncPreviewRequest true "Claims to map"
+// @Success 200 {object} codersdk.IDPSyncPreviewResponse
+// @Router /organizations/{organization}/settings/idpsync/preview [post]
+func (api *API) previewIDPSync(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceIdpsyncSettings.InOrg(org.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.IDPSyncPreviewRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	claims := jwt.MapClaims(req.Claims)
+
+	//nolint:gocritic // Requires system context to read runtime config
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	resp := codersdk.IDPSyncPreviewResponse{
+		Groups:        []codersdk.IDPSyncPreviewGroup{},
+		MissingGroups: []string{},
+		Roles:         []string{},
+		IgnoredRoles:  []string{},
+	}
+
+	groupSettings, err := api.IDPSync.GroupSyncSettings(sysCtx, org.ID, api.Database)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	resp.GroupSyncEnabled = api.IDPSync.GroupSyncEntitled() && groupSettings.Field != ""
+	if resp.GroupSyncEnabled {
+		expected, err := groupSettings.ParseClaims(org.ID, claims)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Failed to parse groups from the %q claim.", groupSettings.Field),
+				Detail:  err.Error(),
+			})
+			return
+		}
+		// The Everyone group is always implied.
+		groupIDs := []uuid.UUID{org.ID}
+		groupNames := make([]string, 0)
+		for _, g := range expected {
+			switch {
+			case g.GroupID != nil:
+				groupIDs = append(groupIDs, *g.GroupID)
+			case g.GroupName != nil:
+				groupNames = append(groupNames, *g.GroupName)
+			}
+		}
+
+		byID, err := api.Database.GetGroups(sysCtx, database.GetGroupsParams{
+			OrganizationID: org.ID,
+			GroupIds:       groupIDs,
+		})
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		groups := byID
+		if len(groupNames) > 0 {
+			byName, err := api.Database.GetGroups(sysCtx, database.GetGroupsParams{
+				OrganizationID: org.ID,
+				GroupNames:     groupNames,
+			})
+			if err != nil {
+				httpapi.InternalServerError(rw, err)
+				return
+			}
+			groups = append(groups, byName...)
+			for _, name := range groupNames {
+				if !slices.ContainsFunc(byName, func(g database.GetGroupsRow) bool { return g.Group.Name == name }) {
+					resp.MissingGroups = append(resp.MissingGroups, name)
+				}
+			}
+		}
+
+		seen := make(map[uuid.UUID]struct{}, len(groups))
+		for _, g := range groups {
+			if _, ok := seen[g.Group.ID]; ok {
+				continue
+			}
+			seen[g.Group.ID] = struct{}{}
+			resp.Groups = append(resp.Groups, codersdk.IDPSyncPreviewGroup{
+				ID:             g.Group.ID,
+				Name:           g.Group.Name,
+				QuotaAllowance: int(g.Group.QuotaAllowance),
+			})
+			resp.QuotaBudget += int(g.Group.QuotaAllowance)
+		}
+	}
+
+	roleSettings, err := api.IDPSync.RoleSyncSettings(sysCtx, org.ID, api.Database)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	resp.RoleSyncEnabled = api.IDPSync.RoleSyncEntitled() && roleSettings.Field != ""
+	if resp.RoleSyncEnabled {
+		expected, err := roleSettings.ParseClaims(org.ID, claims)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Failed to parse roles from the %q claim.", roleSettings.Field),
+				Detail:  err.Error(),
+			})
+			return
+		}
+		valid, err := rolestore.Expand(sysCtx, api.Database, expected)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		for _, role := range slice.Unique(expected) {
+			if role.Name == rbac.RoleOrgMember() {
+				continue
+			}
+			if slices.ContainsFunc(valid, func(v rbac.Role) bool { return v.Identifier.UniqueName() == role.UniqueName() }) {
+				resp.Roles = append(resp.Roles, role.Name)
+			} else {
+				resp.IgnoredRoles = append(resp.IgnoredRoles, role.Name)
+			}
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
 func applyIDPSyncMappingDiff[IDType uuid.UUID | string](
 	previous map[string][]IDType,
 	add, remove []codersdk.IDPSyncMapping[IDType],
@@ -775,3 +775,79 @@ func TestPatchOrganizationSyncMapping(t *testing.T) {
 		require.Equal(t, http.StatusForbidden, apiError.StatusCode())
 	})
 }
+
+func TestPreviewIDPSync(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		owner, user := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC:          1,
+					codersdk.FeatureUserRoleManagement:    1,
+					codersdk.FeatureCustomRoles:           1,
+					codersdk.FeatureMultipleOrganizations: 1,
+				},
+			},
+		})
+		orgAdmin, _ := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID, rbac.ScopedRoleOrgAdmin(user.OrganizationID))
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		eng, err := owner.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name:           "engineering",
+			QuotaAllowance: 10,
+		})
+		require.NoError(t, err)
+
+		_, err = orgAdmin.PatchGroupIDPSyncSettings(ctx, user.OrganizationID.String(), codersdk.GroupSyncSettings{
+			Field: "groups",
+			Rules: []codersdk.IDPSyncRule{
+				{Match: regexp.MustCompile(`^(frontend|backend)$`), Replacement: "engineering"},
+				{StripPrefix: "coder-"},
+			},
+		})
+		require.NoError(t, err)
+		_, err = orgAdmin.PatchRoleIDPSyncSettings(ctx, user.OrganizationID.String(), codersdk.RoleSyncSettings{
+			Field:        "roles",
+			DefaultRoles: []string{rbac.RoleOrgAuditor()},
+		})
+		require.NoError(t, err)
+
+		preview, err := orgAdmin.PreviewIDPSync(ctx, user.OrganizationID.String(), codersdk.IDPSyncPreviewRequest{
+			Claims: map[string]interface{}{
+				"groups": []string{"frontend", "backend", "coder-ops"},
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, preview.GroupSyncEnabled)
+		require.ElementsMatch(t, []uuid.UUID{user.OrganizationID, eng.ID}, []uuid.UUID{preview.Groups[0].ID, preview.Groups[1].ID})
+		require.Equal(t, []string{"ops"}, preview.MissingGroups)
+		require.Equal(t, 10, preview.QuotaBudget)
+		require.True(t, preview.RoleSyncEnabled)
+		require.Equal(t, []string{rbac.RoleOrgAuditor()}, preview.Roles)
+		require.Empty(t, preview.IgnoredRoles)
+	})
+
+	t.Run("NotAuthorized", func(t *testing.T) {
+		t.Parallel()
+
+		owner, user := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureCustomRoles:           1,
+					codersdk.FeatureMultipleOrganizations: 1,
+				},
+			},
+		})
+
+		member, _ := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.PreviewIDPSync(ctx, user.OrganizationID.String(), codersdk.IDPSyncPreviewRequest{})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusForbidden, apiError.StatusCode())
+	})
+}
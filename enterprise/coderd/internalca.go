@@ -0,0 +1,69 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/internalca"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// workspaceProxyIssueCertificate issues a short-lived client certificate to
+// the workspace proxy.
+//
+// @Summary Issue workspace proxy client certificate
+// @ID issue-workspace-proxy-client-certificate
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param request body codersdk.IssueCertificateRequest true "Certificate request"
+// @Success 200 {object} codersdk.IssuedCertificate
+// @Router /workspaceproxies/me/certificate [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceProxyIssueCertificate(rw http.ResponseWriter, r *http.Request) {
+	proxy := httpmw.WorkspaceProxy(r)
+	api.AGPL.IssueInternalCertificate(rw, r, internalca.Identity{
+		Kind: internalca.KindWorkspaceProxy,
+		ID:   proxy.ID,
+	})
+}
+
+// provisionerDaemonIssueCertificate issues a short-lived client certificate to
+// a provisioner daemon. The daemon authenticates the same way it does to serve
+// jobs.
+//
+// @Summary Issue provisioner daemon client certificate
+// @ID issue-provisioner-daemon-client-certificate
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.IssueCertificateRequest true "Certificate request"
+// @Success 200 {object} codersdk.IssuedCertificate
+// @Router /organizations/{organization}/provisionerdaemons/certificate [post]
+func (api *API) provisionerDaemonIssueCertificate(rw http.ResponseWriter, r *http.Request) {
+	api.AGPL.IssueInternalCertificate(rw, r, internalca.Identity{
+		Kind: internalca.KindProvisionerDaemon,
+		ID:   provisionerDaemonCertificateID(r),
+	})
+}
+
+// provisionerDaemonCertificateID returns the identity certificates are issued
+// to for an authenticated provisioner daemon request: the provisioner key,
+// the pre-shared key, or the user running the daemon.
+func provisionerDaemonCertificateID(r *http.Request) uuid.UUID {
+	if pk, ok := httpmw.ProvisionerKeyAuthOptional(r); ok {
+		return pk.ID
+	}
+	if httpmw.ProvisionerDaemonAuthenticated(r) {
+		return uuid.MustParse(codersdk.ProvisionerKeyIDPSK)
+	}
+	if apiKey, ok := httpmw.APIKeyOptional(r); ok {
+		return apiKey.UserID
+	}
+	return uuid.Nil
+}
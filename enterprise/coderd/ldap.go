@@ -0,0 +1,63 @@
+package coderd
+
+import (
+	"net/http"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/ldapsync"
+)
+
+// postLDAPSync syncs users and groups from the LDAP directory immediately.
+// With dry-run, the changes are returned without being applied, so the
+// filters and attribute mapping can be reviewed before they take effect.
+//
+// @Summary Sync users and groups from LDAP
+// @ID sync-users-and-groups-from-ldap
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param request body codersdk.LDAPSyncRequest true "Sync request"
+// @Success 200 {object} codersdk.LDAPSyncResponse
+// @Router /ldap/sync [post]
+func (api *API) postLDAPSync(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.AGPL.Authorize(r, policy.ActionUpdate, rbac.ResourceDeploymentConfig) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.LDAPSyncRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if api.ldapSyncer == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "LDAP sync is not configured.",
+			Detail:  "Set CODER_LDAP_URL to sync users and groups from an LDAP directory.",
+		})
+		return
+	}
+
+	resp, err := api.ldapSyncer.Sync(ctx, req.DryRun)
+	if xerrors.Is(err, ldapsync.ErrSyncInProgress) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "LDAP sync is already in progress, try again shortly.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to sync from LDAP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
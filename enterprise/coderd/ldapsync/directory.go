@@ -0,0 +1,205 @@
+package ldapsync
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-ldap/ldap/v3"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// searchPageSize is the page size of directory searches. Active Directory
+// returns at most 1000 entries per page by default.
+const searchPageSize = 500
+
+// Directory lists the users and groups to sync.
+type Directory interface {
+	Search(ctx context.Context) (Snapshot, error)
+}
+
+// Snapshot is the state of the directory at the time of a search.
+type Snapshot struct {
+	Users  []User
+	Groups []Group
+}
+
+// User is a directory user.
+type User struct {
+	DN string
+	// ExternalID identifies the user even when the DN changes.
+	ExternalID string
+	Username   string
+	Email      string
+	Name       string
+}
+
+// Group is a directory group.
+type Group struct {
+	DN   string
+	Name string
+	// MemberDNs are the DNs of the members. Members that are not users
+	// returned by the user search, such as nested groups, are ignored.
+	MemberDNs []string
+}
+
+// LDAPDirectory searches an LDAP server.
+type LDAPDirectory struct {
+	config    codersdk.LDAPConfig
+	tlsConfig *tls.Config
+}
+
+// NewLDAPDirectory validates the LDAP configuration and returns a directory
+// that connects to the server for every search.
+func NewLDAPDirectory(config codersdk.LDAPConfig) (*LDAPDirectory, error) {
+	u, err := url.Parse(config.URL.String())
+	if err != nil {
+		return nil, xerrors.Errorf("parse ldap url: %w", err)
+	}
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return nil, xerrors.Errorf("ldap url scheme must be ldap or ldaps, got %q", u.Scheme)
+	}
+	if config.StartTLS.Value() && u.Scheme == "ldaps" {
+		return nil, xerrors.New("ldap-start-tls can only be used with ldap:// urls")
+	}
+	if config.UserBaseDN.String() == "" {
+		return nil, xerrors.New("ldap-user-base-dn is required")
+	}
+	if config.IDAttribute.String() == "" || config.UsernameAttribute.String() == "" || config.EmailAttribute.String() == "" {
+		return nil, xerrors.New("ldap-id-attribute, ldap-username-attribute and ldap-email-attribute are required")
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: u.Hostname(),
+	}
+	if config.CAFile.String() != "" {
+		data, err := os.ReadFile(config.CAFile.String())
+		if err != nil {
+			return nil, xerrors.Errorf("read ldap ca file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, xerrors.Errorf("no certificates found in %q", config.CAFile.String())
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &LDAPDirectory{
+		config:    config,
+		tlsConfig: tlsConfig,
+	}, nil
+}
+
+func (d *LDAPDirectory) Search(ctx context.Context) (Snapshot, error) {
+	conn, err := ldap.DialURL(d.config.URL.String(), ldap.DialWithTLSConfig(d.tlsConfig))
+	if err != nil {
+		return Snapshot{}, xerrors.Errorf("dial ldap server: %w", err)
+	}
+	defer conn.Close()
+	// The client is not context aware, so close the connection to abort
+	// searches when the context is canceled.
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+
+	if d.config.StartTLS.Value() {
+		err = conn.StartTLS(d.tlsConfig)
+		if err != nil {
+			return Snapshot{}, xerrors.Errorf("start tls: %w", err)
+		}
+	}
+	if d.config.BindDN.String() != "" {
+		err = conn.Bind(d.config.BindDN.String(), d.config.BindPassword.String())
+		if err != nil {
+			return Snapshot{}, xerrors.Errorf("bind: %w", err)
+		}
+	}
+
+	var snapshot Snapshot
+	users, err := conn.SearchWithPaging(ldap.NewSearchRequest(
+		d.config.UserBaseDN.String(),
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		d.config.UserFilter.String(),
+		[]string{
+			d.config.IDAttribute.String(),
+			d.config.UsernameAttribute.String(),
+			d.config.EmailAttribute.String(),
+			d.config.NameAttribute.String(),
+		},
+		nil,
+	), searchPageSize)
+	if err != nil {
+		return Snapshot{}, xerrors.Errorf("search users: %w", err)
+	}
+	for _, entry := range users.Entries {
+		snapshot.Users = append(snapshot.Users, User{
+			DN:         entry.DN,
+			ExternalID: externalID(d.config.IDAttribute.String(), entry.GetRawAttributeValue(d.config.IDAttribute.String())),
+			Username:   entry.GetAttributeValue(d.config.UsernameAttribute.String()),
+			Email:      entry.GetAttributeValue(d.config.EmailAttribute.String()),
+			Name:       entry.GetAttributeValue(d.config.NameAttribute.String()),
+		})
+	}
+
+	if d.config.GroupBaseDN.String() == "" {
+		return snapshot, nil
+	}
+	groups, err := conn.SearchWithPaging(ldap.NewSearchRequest(
+		d.config.GroupBaseDN.String(),
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		d.config.GroupFilter.String(),
+		[]string{
+			d.config.GroupNameAttribute.String(),
+			d.config.GroupMemberAttribute.String(),
+		},
+		nil,
+	), searchPageSize)
+	if err != nil {
+		return Snapshot{}, xerrors.Errorf("search groups: %w", err)
+	}
+	for _, entry := range groups.Entries {
+		snapshot.Groups = append(snapshot.Groups, Group{
+			DN:        entry.DN,
+			Name:      entry.GetAttributeValue(d.config.GroupNameAttribute.String()),
+			MemberDNs: entry.GetAttributeValues(d.config.GroupMemberAttribute.String()),
+		})
+	}
+	return snapshot, nil
+}
+
+// externalID returns the value of the ID attribute as a string. Binary
+// values, like the objectGUID of Active Directory, are hex encoded.
+func externalID(attribute string, value []byte) string {
+	if strings.EqualFold(attribute, "objectGUID") || !utf8.Valid(value) {
+		return hex.EncodeToString(value)
+	}
+	return string(value)
+}
+
+// normalizeDN returns a DN that can be compared with other DNs. Directories
+// don't always use the same case and spacing in the member attribute as in
+// the DN of the member.
+func normalizeDN(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return strings.ToLower(dn)
+	}
+	parts := make([]string, 0, len(parsed.RDNs))
+	for _, rdn := range parsed.RDNs {
+		attrs := make([]string, 0, len(rdn.Attributes))
+		for _, attr := range rdn.Attributes {
+			attrs = append(attrs, strings.ToLower(attr.Type)+"="+strings.ToLower(attr.Value))
+		}
+		parts = append(parts, strings.Join(attrs, "+"))
+	}
+	return strings.Join(parts, ",")
+}
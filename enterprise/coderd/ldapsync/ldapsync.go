@@ -0,0 +1,677 @@
+// Package ldapsync provisions Coder users and groups from an LDAP directory,
+// such as Active Directory, for deployments without an identity provider
+// that supports SCIM.
+package ldapsync
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moby/moby/pkg/namesgenerator"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	agpl "github.com/coder/coder/v2/coderd"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/idpsync"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/scim"
+	"github.com/coder/quartz"
+)
+
+// ErrSyncInProgress is returned when another replica is syncing.
+var ErrSyncInProgress = xerrors.New("another ldap sync is in progress")
+
+// CreateUserFunc creates a Coder user, see coderd.API.CreateUser.
+type CreateUserFunc func(ctx context.Context, store database.Store, req agpl.CreateUserRequest) (database.User, error)
+
+type Options struct {
+	Logger     slog.Logger
+	Database   database.Store
+	Auditor    *atomic.Pointer[audit.Auditor]
+	Directory  Directory
+	IDPSync    idpsync.IDPSync
+	CreateUser CreateUserFunc
+	// LoginType is the login type of created users.
+	LoginType database.LoginType
+}
+
+// Syncer syncs users and groups from a directory.
+type Syncer struct {
+	logger     slog.Logger
+	db         database.Store
+	auditor    *atomic.Pointer[audit.Auditor]
+	directory  Directory
+	idpSync    idpsync.IDPSync
+	createUser CreateUserFunc
+	loginType  database.LoginType
+}
+
+func New(opts Options) *Syncer {
+	return &Syncer{
+		logger:     opts.Logger.Named("ldap_sync"),
+		db:         opts.Database,
+		auditor:    opts.Auditor,
+		directory:  opts.Directory,
+		idpSync:    opts.IDPSync,
+		createUser: opts.CreateUser,
+		loginType:  opts.LoginType,
+	}
+}
+
+// Start syncs periodically until the returned function is called. Syncs are
+// skipped while enabled returns false, so the license can be checked on
+// every run.
+func (s *Syncer) Start(ctx context.Context, clk quartz.Clock, interval time.Duration, enabled func() bool) func() {
+	ctx, cancelFunc := context.WithCancel(ctx)
+	tf := clk.TickerFunc(ctx, interval, func() error {
+		if !enabled() {
+			return nil
+		}
+		start := time.Now()
+		resp, err := s.Sync(ctx, false)
+		if xerrors.Is(err, ErrSyncInProgress) {
+			s.logger.Debug(ctx, "skipping ldap sync, another replica is syncing")
+			return nil
+		}
+		if err != nil {
+			s.logger.Error(ctx, "ldap sync failed", slog.Error(err))
+			return nil
+		}
+		s.logger.Debug(ctx, "ldap sync is done",
+			slog.F("directory_users", resp.DirectoryUsers),
+			slog.F("directory_groups", resp.DirectoryGroups),
+			slog.F("changes", len(resp.Changes)),
+			slog.F("execution_time", time.Since(start)),
+		)
+		return nil
+	})
+
+	return func() {
+		cancelFunc()
+		_ = tf.Wait()
+	}
+}
+
+// Sync searches the directory and brings Coder in line with it. With dryRun,
+// the changes are returned without being applied.
+func (s *Syncer) Sync(ctx context.Context, dryRun bool) (codersdk.LDAPSyncResponse, error) {
+	snapshot, err := s.directory.Search(ctx)
+	if err != nil {
+		return codersdk.LDAPSyncResponse{}, xerrors.Errorf("search directory: %w", err)
+	}
+
+	//nolint:gocritic // LDAP sync is a system job.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	resp := codersdk.LDAPSyncResponse{
+		DryRun:          dryRun,
+		DirectoryUsers:  len(snapshot.Users),
+		DirectoryGroups: len(snapshot.Groups),
+		Changes:         []codersdk.LDAPSyncChange{},
+	}
+	var audits []userAudit
+	err = s.db.InTx(func(tx database.Store) error {
+		// Replicas sync on the same interval, only one of them has to.
+		acquired, err := tx.TryAcquireLock(ctx, database.LockIDLDAPSync)
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		if !acquired {
+			return ErrSyncInProgress
+		}
+
+		st, err := loadState(ctx, tx, snapshot)
+		if err != nil {
+			return err
+		}
+		changes, err := plan(ctx, s.logger, snapshot, st)
+		if err != nil {
+			return err
+		}
+		for _, c := range changes {
+			resp.Changes = append(resp.Changes, c.LDAPSyncChange)
+		}
+		if dryRun {
+			return nil
+		}
+		audits, err = s.apply(ctx, tx, st, changes)
+		return err
+	}, nil)
+	if err != nil {
+		return codersdk.LDAPSyncResponse{}, err
+	}
+
+	for _, a := range audits {
+		audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.User]{
+			Audit:            *s.auditor.Load(),
+			Log:              s.logger,
+			UserID:           a.new.ID,
+			Action:           a.action,
+			Old:              a.old,
+			New:              a.new,
+			Status:           http.StatusOK,
+			AdditionalFields: audit.BackgroundTaskFieldsBytes(ctx, s.logger, audit.BackgroundSubsystemLDAPSync),
+		})
+	}
+	return resp, nil
+}
+
+// state is the part of Coder that LDAP sync manages.
+type state struct {
+	org database.Organization
+	// links maps the external ID of directory users to their Coder user.
+	links map[string]database.LDAPUser
+	// users holds the linked users, and the unlinked users that have the
+	// email address of a directory user.
+	users map[uuid.UUID]database.User
+	// emails maps lowercase email addresses to unlinked users.
+	emails map[string]uuid.UUID
+	// groups holds every group of the default organization by name.
+	groups map[string]database.Group
+	// members holds the members of groups synced from LDAP.
+	members map[uuid.UUID][]database.GroupMember
+}
+
+func loadState(ctx context.Context, tx database.Store, snapshot Snapshot) (*state, error) {
+	org, err := tx.GetDefaultOrganization(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("get default organization: %w", err)
+	}
+	st := &state{
+		org:     org,
+		links:   make(map[string]database.LDAPUser),
+		users:   make(map[uuid.UUID]database.User),
+		emails:  make(map[string]uuid.UUID),
+		groups:  make(map[string]database.Group),
+		members: make(map[uuid.UUID][]database.GroupMember),
+	}
+
+	links, err := tx.GetLDAPUsers(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("get ldap users: %w", err)
+	}
+	linkedIDs := make([]uuid.UUID, 0, len(links))
+	for _, link := range links {
+		st.links[link.ExternalID] = link
+		linkedIDs = append(linkedIDs, link.UserID)
+	}
+	if len(linkedIDs) > 0 {
+		users, err := tx.GetUsersByIDs(ctx, linkedIDs)
+		if err != nil {
+			return nil, xerrors.Errorf("get linked users: %w", err)
+		}
+		for _, user := range users {
+			if !user.Deleted {
+				st.users[user.ID] = user
+			}
+		}
+	}
+
+	for _, u := range snapshot.Users {
+		if _, ok := st.links[u.ExternalID]; ok || u.Email == "" {
+			continue
+		}
+		user, err := tx.GetUserByEmailOrUsername(ctx, database.GetUserByEmailOrUsernameParams{
+			Email: u.Email,
+		})
+		if xerrors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("get user by email: %w", err)
+		}
+		if _, ok := st.users[user.ID]; ok {
+			// Linked to another directory user.
+			continue
+		}
+		st.users[user.ID] = user
+		st.emails[strings.ToLower(u.Email)] = user.ID
+	}
+
+	groups, err := tx.GetGroups(ctx, database.GetGroupsParams{
+		OrganizationID: org.ID,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get groups: %w", err)
+	}
+	for _, row := range groups {
+		st.groups[row.Group.Name] = row.Group
+		if row.Group.Source != database.GroupSourceLdap {
+			continue
+		}
+		members, err := tx.GetGroupMembersByGroupID(ctx, database.GetGroupMembersByGroupIDParams{
+			GroupID: row.Group.ID,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("get members of group %q: %w", row.Group.Name, err)
+		}
+		st.members[row.Group.ID] = members
+	}
+	return st, nil
+}
+
+// change is a change to apply, with the data needed to apply it.
+type change struct {
+	codersdk.LDAPSyncChange
+	user User
+	// userID is unset for users that are created by the sync.
+	userID uuid.UUID
+	// groupID is unset for groups that are created by the sync.
+	groupID uuid.UUID
+	// groupDisplayName is the directory name of created groups.
+	groupDisplayName string
+}
+
+// plan returns the changes that bring Coder in line with the directory. Users
+// are changed before groups, so group members can refer to created users.
+func plan(ctx context.Context, logger slog.Logger, snapshot Snapshot, st *state) ([]change, error) {
+	var changes []change
+
+	type member struct {
+		id       uuid.UUID
+		username string
+	}
+	// members maps the normalized DN of the synced users to Coder users.
+	members := make(map[string]member)
+	seen := make(map[string]bool)
+	directoryUsers := slices.Clone(snapshot.Users)
+	slices.SortFunc(directoryUsers, func(a, b User) int {
+		return strings.Compare(a.DN, b.DN)
+	})
+	for _, u := range directoryUsers {
+		if u.ExternalID == "" {
+			logger.Warn(ctx, "skipping ldap user without an id", slog.F("dn", u.DN))
+			continue
+		}
+		if seen[u.ExternalID] {
+			continue
+		}
+		seen[u.ExternalID] = true
+
+		if link, ok := st.links[u.ExternalID]; ok {
+			user, ok := st.users[link.UserID]
+			if !ok {
+				// The user was deleted in Coder, which takes precedence.
+				continue
+			}
+			if link.Dn != u.DN || (u.Name != "" && codersdk.NormalizeRealUsername(u.Name) != user.Name) {
+				changes = append(changes, userChange(codersdk.LDAPSyncChangeUpdateUser, u, user))
+			}
+			if user.Status == database.UserStatusSuspended {
+				changes = append(changes, userChange(codersdk.LDAPSyncChangeActivateUser, u, user))
+			}
+			members[normalizeDN(u.DN)] = member{id: user.ID, username: user.Username}
+			continue
+		}
+
+		if u.Email == "" {
+			logger.Warn(ctx, "skipping ldap user without an email address", slog.F("dn", u.DN))
+			continue
+		}
+		if userID, ok := st.emails[strings.ToLower(u.Email)]; ok {
+			user := st.users[userID]
+			// Only link each user once, even if the directory has duplicate
+			// email addresses.
+			delete(st.emails, strings.ToLower(u.Email))
+			changes = append(changes, userChange(codersdk.LDAPSyncChangeLinkUser, u, user))
+			members[normalizeDN(u.DN)] = member{id: user.ID, username: user.Username}
+			continue
+		}
+
+		username := u.Username
+		if username == "" {
+			username = u.Email
+		}
+		if codersdk.NameValid(username) != nil {
+			username = codersdk.UsernameFrom(username)
+		}
+		changes = append(changes, change{
+			LDAPSyncChange: codersdk.LDAPSyncChange{
+				Type:     codersdk.LDAPSyncChangeCreateUser,
+				Username: username,
+				DN:       u.DN,
+			},
+			user: u,
+		})
+		members[normalizeDN(u.DN)] = member{username: username}
+	}
+
+	links := make([]database.LDAPUser, 0, len(st.links))
+	for _, link := range st.links {
+		links = append(links, link)
+	}
+	slices.SortFunc(links, func(a, b database.LDAPUser) int {
+		return strings.Compare(a.Dn, b.Dn)
+	})
+	suspended := 0
+	for _, link := range links {
+		user, ok := st.users[link.UserID]
+		if seen[link.ExternalID] || !ok || user.Status == database.UserStatusSuspended {
+			continue
+		}
+		changes = append(changes, userChange(codersdk.LDAPSyncChangeSuspendUser, User{DN: link.Dn, ExternalID: link.ExternalID}, user))
+		suspended++
+	}
+	// A misconfigured filter or base DN returns no users, which must not
+	// lock everyone out.
+	if len(snapshot.Users) == 0 && suspended > 0 {
+		return nil, xerrors.Errorf("the directory returned no users, refusing to suspend %d users", suspended)
+	}
+
+	synced := make(map[string]bool)
+	directoryGroups := slices.Clone(snapshot.Groups)
+	slices.SortFunc(directoryGroups, func(a, b Group) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, g := range directoryGroups {
+		name, err := scim.GroupName(g.Name)
+		if err != nil {
+			logger.Warn(ctx, "skipping ldap group", slog.F("dn", g.DN), slog.Error(err))
+			continue
+		}
+		if synced[name] {
+			logger.Warn(ctx, "skipping ldap group with a duplicate name", slog.F("dn", g.DN), slog.F("group", name))
+			continue
+		}
+		group, exists := st.groups[name]
+		if exists && group.Source != database.GroupSourceLdap {
+			logger.Warn(ctx, "skipping ldap group, a group that is not synced from ldap has the same name", slog.F("dn", g.DN), slog.F("group", name))
+			continue
+		}
+		synced[name] = true
+
+		want := make(map[string]member)
+		for _, dn := range g.MemberDNs {
+			if m, ok := members[normalizeDN(dn)]; ok {
+				want[m.username] = m
+			}
+		}
+		var current []database.GroupMember
+		if !exists {
+			changes = append(changes, change{
+				LDAPSyncChange: codersdk.LDAPSyncChange{
+					Type:  codersdk.LDAPSyncChangeCreateGroup,
+					DN:    g.DN,
+					Group: name,
+				},
+				groupDisplayName: g.Name,
+			})
+		} else {
+			current = st.members[group.ID]
+		}
+
+		have := make(map[uuid.UUID]bool)
+		for _, m := range current {
+			have[m.UserID] = true
+		}
+		usernames := make([]string, 0, len(want))
+		for username := range want {
+			usernames = append(usernames, username)
+		}
+		slices.Sort(usernames)
+		for _, username := range usernames {
+			m := want[username]
+			if m.id != uuid.Nil && have[m.id] {
+				continue
+			}
+			changes = append(changes, change{
+				LDAPSyncChange: codersdk.LDAPSyncChange{
+					Type:     codersdk.LDAPSyncChangeAddGroupMember,
+					Username: username,
+					Group:    name,
+				},
+				userID:  m.id,
+				groupID: group.ID,
+			})
+		}
+		for _, m := range current {
+			if _, ok := want[m.UserUsername]; ok {
+				continue
+			}
+			changes = append(changes, change{
+				LDAPSyncChange: codersdk.LDAPSyncChange{
+					Type:     codersdk.LDAPSyncChangeRemoveGroupMember,
+					Username: m.UserUsername,
+					Group:    name,
+				},
+				userID:  m.UserID,
+				groupID: group.ID,
+			})
+		}
+	}
+
+	names := make([]string, 0, len(st.groups))
+	for name := range st.groups {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		group := st.groups[name]
+		if group.Source != database.GroupSourceLdap || synced[name] {
+			continue
+		}
+		changes = append(changes, change{
+			LDAPSyncChange: codersdk.LDAPSyncChange{
+				Type:  codersdk.LDAPSyncChangeDeleteGroup,
+				Group: name,
+			},
+			groupID: group.ID,
+		})
+	}
+	return changes, nil
+}
+
+func userChange(typ codersdk.LDAPSyncChangeType, u User, user database.User) change {
+	return change{
+		LDAPSyncChange: codersdk.LDAPSyncChange{
+			Type:     typ,
+			Username: user.Username,
+			DN:       u.DN,
+		},
+		user:   u,
+		userID: user.ID,
+	}
+}
+
+type userAudit struct {
+	action   database.AuditAction
+	old, new database.User
+}
+
+func (s *Syncer) apply(ctx context.Context, tx database.Store, st *state, changes []change) ([]userAudit, error) {
+	var audits []userAudit
+	// created maps the planned username of created users to their ID, since
+	// the username may change when it is taken.
+	created := make(map[string]uuid.UUID)
+	groups := make(map[string]uuid.UUID)
+
+	for _, c := range changes {
+		switch c.Type {
+		case codersdk.LDAPSyncChangeCreateUser:
+			user, err := s.create(ctx, tx, st, c)
+			if err != nil {
+				return nil, err
+			}
+			created[c.Username] = user.ID
+			audits = append(audits, userAudit{action: database.AuditActionCreate, new: user})
+			s.logger.Info(ctx, "created user from ldap", slog.F("username", user.Username), slog.F("dn", c.DN))
+
+		case codersdk.LDAPSyncChangeLinkUser, codersdk.LDAPSyncChangeUpdateUser:
+			err := upsertLink(ctx, tx, c.userID, c.user)
+			if err != nil {
+				return nil, err
+			}
+			user := st.users[c.userID]
+			if c.user.Name != "" && codersdk.NormalizeRealUsername(c.user.Name) != user.Name {
+				updated, err := tx.UpdateUserProfile(ctx, database.UpdateUserProfileParams{
+					ID:        user.ID,
+					Email:     user.Email,
+					Username:  user.Username,
+					AvatarURL: user.AvatarURL,
+					Name:      codersdk.NormalizeRealUsername(c.user.Name),
+					UpdatedAt: dbtime.Now(),
+				})
+				if err != nil {
+					return nil, xerrors.Errorf("update user %q: %w", user.Username, err)
+				}
+				audits = append(audits, userAudit{action: database.AuditActionWrite, old: user, new: updated})
+				st.users[user.ID] = updated
+			}
+			s.logger.Info(ctx, "updated ldap user", slog.F("username", user.Username), slog.F("dn", c.DN))
+
+		case codersdk.LDAPSyncChangeActivateUser, codersdk.LDAPSyncChangeSuspendUser:
+			// Like SCIM, activated users are dormant until they log in.
+			status := database.UserStatusDormant
+			if c.Type == codersdk.LDAPSyncChangeSuspendUser {
+				status = database.UserStatusSuspended
+			}
+			user := st.users[c.userID]
+			updated, err := tx.UpdateUserStatus(ctx, database.UpdateUserStatusParams{
+				ID:        user.ID,
+				Status:    status,
+				UpdatedAt: dbtime.Now(),
+			})
+			if err != nil {
+				return nil, xerrors.Errorf("update status of user %q: %w", user.Username, err)
+			}
+			audits = append(audits, userAudit{action: database.AuditActionWrite, old: user, new: updated})
+			st.users[user.ID] = updated
+			s.logger.Info(ctx, "updated status of ldap user", slog.F("username", user.Username), slog.F("status", status))
+
+		case codersdk.LDAPSyncChangeCreateGroup:
+			inserted, err := tx.InsertMissingGroups(ctx, database.InsertMissingGroupsParams{
+				OrganizationID: st.org.ID,
+				Source:         database.GroupSourceLdap,
+				GroupNames:     []string{c.Group},
+			})
+			if err != nil {
+				return nil, xerrors.Errorf("insert group %q: %w", c.Group, err)
+			}
+			if len(inserted) == 0 {
+				return nil, xerrors.Errorf("a group named %q already exists", c.Group)
+			}
+			_, err = tx.UpdateGroupByID(ctx, database.UpdateGroupByIDParams{
+				ID:             inserted[0].ID,
+				Name:           inserted[0].Name,
+				DisplayName:    c.groupDisplayName,
+				AvatarURL:      inserted[0].AvatarURL,
+				QuotaAllowance: inserted[0].QuotaAllowance,
+			})
+			if err != nil {
+				return nil, xerrors.Errorf("set display name of group %q: %w", c.Group, err)
+			}
+			groups[c.Group] = inserted[0].ID
+			s.logger.Info(ctx, "created group from ldap", slog.F("group", c.Group), slog.F("dn", c.DN))
+
+		case codersdk.LDAPSyncChangeDeleteGroup:
+			err := tx.DeleteGroupByID(ctx, c.groupID)
+			if err != nil {
+				return nil, xerrors.Errorf("delete group %q: %w", c.Group, err)
+			}
+			s.logger.Info(ctx, "deleted group that is no longer in ldap", slog.F("group", c.Group))
+
+		case codersdk.LDAPSyncChangeAddGroupMember:
+			userID, groupID := c.userID, c.groupID
+			if userID == uuid.Nil {
+				userID = created[c.Username]
+			}
+			if groupID == uuid.Nil {
+				groupID = groups[c.Group]
+			}
+			_, err := tx.InsertUserGroupsByID(ctx, database.InsertUserGroupsByIDParams{
+				UserID:   userID,
+				GroupIds: []uuid.UUID{groupID},
+			})
+			if err != nil {
+				return nil, xerrors.Errorf("add %q to group %q: %w", c.Username, c.Group, err)
+			}
+
+		case codersdk.LDAPSyncChangeRemoveGroupMember:
+			err := tx.DeleteGroupMemberFromGroup(ctx, database.DeleteGroupMemberFromGroupParams{
+				UserID:  c.userID,
+				GroupID: c.groupID,
+			})
+			if err != nil {
+				return nil, xerrors.Errorf("remove %q from group %q: %w", c.Username, c.Group, err)
+			}
+		}
+	}
+	return audits, nil
+}
+
+// create creates a directory user. Like OIDC signups, a random suffix is
+// added to usernames that are taken.
+func (s *Syncer) create(ctx context.Context, tx database.Store, st *state, c change) (database.User, error) {
+	username := c.Username
+	for i := 0; ; i++ {
+		_, err := tx.GetUserByEmailOrUsername(ctx, database.GetUserByEmailOrUsernameParams{
+			Username: username,
+		})
+		if xerrors.Is(err, sql.ErrNoRows) {
+			break
+		}
+		if err != nil {
+			return database.User{}, xerrors.Errorf("get user by username: %w", err)
+		}
+		if i == 10 {
+			return database.User{}, xerrors.Errorf("exhausted alternatives for taken username %q", c.Username)
+		}
+		username = codersdk.UsernameFrom(fmt.Sprintf("%s-%s", c.Username, namesgenerator.GetRandomName(1)))
+	}
+
+	// Like SCIM, users are only added to the default organization when
+	// organization sync assigns it. Otherwise, organization sync corrects
+	// their organizations when they log in.
+	organizations := []uuid.UUID{}
+	orgSync, err := s.idpSync.OrganizationSyncSettings(ctx, tx)
+	if err != nil {
+		return database.User{}, xerrors.Errorf("get organization sync settings: %w", err)
+	}
+	if orgSync.AssignDefault {
+		organizations = append(organizations, st.org.ID)
+	}
+
+	user, err := s.createUser(ctx, tx, agpl.CreateUserRequest{
+		CreateUserRequestWithOrgs: codersdk.CreateUserRequestWithOrgs{
+			Username:        username,
+			Name:            codersdk.NormalizeRealUsername(c.user.Name),
+			Email:           c.user.Email,
+			OrganizationIDs: organizations,
+		},
+		LoginType: s.loginType,
+		// Syncs can create many users at once, so don't notify user admins.
+		SkipNotifications: true,
+	})
+	if err != nil {
+		return database.User{}, xerrors.Errorf("create user %q: %w", username, err)
+	}
+	err = upsertLink(ctx, tx, user.ID, c.user)
+	if err != nil {
+		return database.User{}, err
+	}
+	return user, nil
+}
+
+func upsertLink(ctx context.Context, tx database.Store, userID uuid.UUID, u User) error {
+	_, err := tx.UpsertLDAPUser(ctx, database.UpsertLDAPUserParams{
+		UserID:     userID,
+		ExternalID: u.ExternalID,
+		Dn:         u.DN,
+		UpdatedAt:  dbtime.Now(),
+	})
+	if err != nil {
+		return xerrors.Errorf("link user to %q: %w", u.DN, err)
+	}
+	return nil
+}
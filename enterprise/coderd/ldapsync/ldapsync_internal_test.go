@@ -0,0 +1,133 @@
+package ldapsync
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+
+	alice := database.User{ID: uuid.New(), Username: "alice", Name: "Alice", Email: "alice@example.com", Status: database.UserStatusActive}
+	bob := database.User{ID: uuid.New(), Username: "bob", Email: "bob@example.com", Status: database.UserStatusSuspended}
+	carol := database.User{ID: uuid.New(), Username: "carol", Email: "carol@example.com", Status: database.UserStatusActive}
+	dave := database.User{ID: uuid.New(), Username: "dave", Email: "Dave@example.com", Status: database.UserStatusActive}
+
+	newState := func() *state {
+		eng := database.Group{ID: uuid.New(), Name: "eng", Source: database.GroupSourceLdap}
+		old := database.Group{ID: uuid.New(), Name: "old", Source: database.GroupSourceLdap}
+		ops := database.Group{ID: uuid.New(), Name: "ops", Source: database.GroupSourceUser}
+		return &state{
+			links: map[string]database.LDAPUser{
+				"alice-id": {UserID: alice.ID, ExternalID: "alice-id", Dn: "CN=Alice,OU=Users,DC=example,DC=com"},
+				"bob-id":   {UserID: bob.ID, ExternalID: "bob-id", Dn: "CN=Bob,OU=Users,DC=example,DC=com"},
+				"carol-id": {UserID: carol.ID, ExternalID: "carol-id", Dn: "CN=Carol,OU=Users,DC=example,DC=com"},
+			},
+			users: map[uuid.UUID]database.User{
+				alice.ID: alice,
+				bob.ID:   bob,
+				carol.ID: carol,
+				dave.ID:  dave,
+			},
+			emails: map[string]uuid.UUID{
+				"dave@example.com": dave.ID,
+			},
+			groups: map[string]database.Group{
+				eng.Name: eng,
+				old.Name: old,
+				ops.Name: ops,
+			},
+			members: map[uuid.UUID][]database.GroupMember{
+				eng.ID: {
+					{UserID: alice.ID, UserUsername: alice.Username},
+					{UserID: carol.ID, UserUsername: carol.Username},
+				},
+			},
+		}
+	}
+
+	t.Run("Sync", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		changes, err := plan(ctx, slogtest.Make(t, nil), Snapshot{
+			Users: []User{
+				{DN: "CN=Alice,OU=Users,DC=example,DC=com", ExternalID: "alice-id", Username: "alice", Email: "alice@example.com", Name: "Alice"},
+				// Moved to another OU and reenabled.
+				{DN: "CN=Bob,OU=Staff,DC=example,DC=com", ExternalID: "bob-id", Username: "bob", Email: "bob@example.com"},
+				// Existing Coder user with the same email address.
+				{DN: "CN=Dave,OU=Users,DC=example,DC=com", ExternalID: "dave-id", Username: "dave", Email: "dave@example.com"},
+				{DN: "CN=Erin,OU=Users,DC=example,DC=com", ExternalID: "erin-id", Username: "Erin Smith", Email: "erin@example.com"},
+				// Skipped, there's no email address.
+				{DN: "CN=Frank,OU=Users,DC=example,DC=com", ExternalID: "frank-id", Username: "frank"},
+			},
+			Groups: []Group{{
+				DN:   "CN=Eng,OU=Groups,DC=example,DC=com",
+				Name: "eng",
+				MemberDNs: []string{
+					"cn=alice,ou=users,dc=example,dc=com",
+					"CN=Erin, OU=Users, DC=example, DC=com",
+					"CN=Nested,OU=Groups,DC=example,DC=com",
+				},
+			}, {
+				DN:        "CN=Platform Team,OU=Groups,DC=example,DC=com",
+				Name:      "Platform Team",
+				MemberDNs: []string{"CN=Dave,OU=Users,DC=example,DC=com"},
+			}, {
+				// Not synced, the group was created in Coder.
+				DN:        "CN=Ops,OU=Groups,DC=example,DC=com",
+				Name:      "ops",
+				MemberDNs: []string{"CN=Alice,OU=Users,DC=example,DC=com"},
+			}},
+		}, newState())
+		require.NoError(t, err)
+
+		summary := make([]codersdk.LDAPSyncChange, 0, len(changes))
+		for _, c := range changes {
+			summary = append(summary, codersdk.LDAPSyncChange{Type: c.Type, Username: c.Username, Group: c.Group})
+		}
+		require.Equal(t, []codersdk.LDAPSyncChange{
+			{Type: codersdk.LDAPSyncChangeUpdateUser, Username: "bob"},
+			{Type: codersdk.LDAPSyncChangeActivateUser, Username: "bob"},
+			{Type: codersdk.LDAPSyncChangeLinkUser, Username: "dave"},
+			{Type: codersdk.LDAPSyncChangeCreateUser, Username: "ErinSmith"},
+			{Type: codersdk.LDAPSyncChangeSuspendUser, Username: "carol"},
+			{Type: codersdk.LDAPSyncChangeCreateGroup, Group: "Platform-Team"},
+			{Type: codersdk.LDAPSyncChangeAddGroupMember, Username: "dave", Group: "Platform-Team"},
+			{Type: codersdk.LDAPSyncChangeAddGroupMember, Username: "ErinSmith", Group: "eng"},
+			{Type: codersdk.LDAPSyncChangeRemoveGroupMember, Username: "carol", Group: "eng"},
+			{Type: codersdk.LDAPSyncChangeDeleteGroup, Group: "old"},
+		}, summary)
+	})
+
+	t.Run("NoUsers", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		_, err := plan(ctx, slogtest.Make(t, nil), Snapshot{}, newState())
+		require.ErrorContains(t, err, "refusing to suspend 2 users")
+	})
+}
+
+func TestNormalizeDN(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "cn=alice,ou=users,dc=example,dc=com", normalizeDN("CN=Alice, OU=Users, DC=example, DC=com"))
+	require.Equal(t, normalizeDN("cn=Smith\\, John,dc=example"), normalizeDN("CN=smith\\, john,DC=Example"))
+}
+
+func TestExternalID(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "5a1c9a6b-6f0e-4b1d", externalID("entryUUID", []byte("5a1c9a6b-6f0e-4b1d")))
+	require.Equal(t, "0102ff", externalID("objectGUID", []byte{0x01, 0x02, 0xff}))
+	require.Equal(t, "ff", externalID("customID", []byte{0xff}))
+}
@@ -0,0 +1,61 @@
+package license
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/alerting"
+	"github.com/coder/coder/v2/coderd/database"
+)
+
+// ExpiryCheck returns an alerting check which fires for every license which
+// expires within the threshold, and escalates once a license has expired and
+// is in its grace period.
+func ExpiryCheck(db database.Store, keys map[string]ed25519.PublicKey, threshold time.Duration) alerting.Check {
+	return func(ctx context.Context, now time.Time) ([]alerting.Alert, error) {
+		licenses, err := db.GetUnexpiredLicenses(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("get unexpired licenses: %w", err)
+		}
+
+		var alerts []alerting.Alert
+		for _, l := range licenses {
+			claims, err := ParseClaims(l.JWT, keys)
+			if err != nil {
+				// Invalid licenses are not entitled to anything, so their
+				// expiry is irrelevant.
+				continue
+			}
+			expires := claims.LicenseExpires.Time
+			details := map[string]any{
+				"license_id":   l.ID,
+				"license_uuid": l.UUID.String(),
+				"expires_at":   expires,
+			}
+			switch {
+			case !now.Before(expires):
+				alerts = append(alerts, alerting.Alert{
+					Key:      strconv.Itoa(int(l.ID)),
+					Summary:  fmt.Sprintf("License %d has expired and is in its grace period until %s", l.ID, l.Exp.UTC().Format(time.DateOnly)),
+					Severity: alerting.SeverityError,
+					Details:  details,
+				})
+			case expires.Sub(now) <= threshold:
+				days := int(math.Ceil(expires.Sub(now).Hours() / 24))
+				alerts = append(alerts, alerting.Alert{
+					Key:      strconv.Itoa(int(l.ID)),
+					Summary:  fmt.Sprintf("License %d expires in %d days on %s", l.ID, days, expires.UTC().Format(time.DateOnly)),
+					Severity: alerting.SeverityWarning,
+					Details:  details,
+				})
+			}
+		}
+		return alerts, nil
+	}
+}
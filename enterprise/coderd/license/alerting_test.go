@@ -0,0 +1,53 @@
+package license_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/coder/coder/v2/coderd/alerting"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestExpiryCheck(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	now := time.Now()
+
+	healthy := &coderdenttest.LicenseOptions{
+		GraceAt:   now.Add(90 * 24 * time.Hour),
+		ExpiresAt: now.Add(120 * 24 * time.Hour),
+	}
+	expiring := &coderdenttest.LicenseOptions{
+		GraceAt:   now.Add(10*24*time.Hour - time.Hour),
+		ExpiresAt: now.Add(40 * 24 * time.Hour),
+	}
+	grace := (&coderdenttest.LicenseOptions{}).GracePeriod(now)
+
+	db := dbmock.NewMockStore(gomock.NewController(t))
+	db.EXPECT().GetUnexpiredLicenses(gomock.Any()).Return([]database.License{
+		{ID: 1, JWT: coderdenttest.GenerateLicense(t, *healthy), Exp: healthy.ExpiresAt},
+		{ID: 2, JWT: coderdenttest.GenerateLicense(t, *expiring), Exp: expiring.ExpiresAt},
+		{ID: 3, JWT: coderdenttest.GenerateLicense(t, *grace), Exp: grace.ExpiresAt},
+		{ID: 4, JWT: "invalid"},
+	}, nil)
+
+	alerts, err := license.ExpiryCheck(db, coderdenttest.Keys, 30*24*time.Hour)(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+
+	require.Equal(t, "2", alerts[0].Key)
+	require.Equal(t, alerting.SeverityWarning, alerts[0].Severity)
+	require.Contains(t, alerts[0].Summary, "expires in 10 days")
+
+	require.Equal(t, "3", alerts[1].Key)
+	require.Equal(t, alerting.SeverityError, alerts[1].Severity)
+	require.Contains(t, alerts[1].Summary, "grace period")
+}
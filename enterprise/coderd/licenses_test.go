@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
 	"github.com/coder/coder/v2/enterprise/coderd/license"
@@ -252,3 +253,87 @@ func TestDeleteLicense(t *testing.T) {
 		assert.Len(t, licenses, 0)
 	})
 }
+
+func TestLicenseUsage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Reservations", func(t *testing.T) {
+		t.Parallel()
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: (&coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			}).UserLimit(10),
+		})
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		group, err := client.CreateGroup(ctx, owner.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "interns",
+		})
+		require.NoError(t, err)
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{owner.UserID.String()},
+		})
+		require.NoError(t, err)
+
+		reservation, err := client.UpsertLicenseSeatReservation(ctx, group.ID, codersdk.UpsertLicenseSeatReservationRequest{
+			Seats: 9,
+		})
+		require.NoError(t, err)
+		require.Equal(t, group.ID, reservation.GroupID)
+		require.Equal(t, "interns", reservation.GroupName)
+		require.EqualValues(t, 9, reservation.Seats)
+		require.EqualValues(t, 1, reservation.ActiveMembers)
+
+		usage, err := client.LicenseUsage(ctx, codersdk.LicenseUsageRequest{HistoryDays: 7, ForecastDays: 7})
+		require.NoError(t, err)
+		require.NotNil(t, usage.Seats)
+		require.EqualValues(t, 10, *usage.Seats)
+		require.EqualValues(t, 1, usage.ActiveUsers)
+		require.EqualValues(t, 8, usage.UnfilledReservedSeats)
+		require.Len(t, usage.History, 8)
+		require.Len(t, usage.Forecast, 7)
+		require.Len(t, usage.Reservations, 1)
+
+		_, err = client.UpsertLicenseSeatReservation(ctx, group.ID, codersdk.UpsertLicenseSeatReservationRequest{
+			Seats: 10,
+		})
+		require.NoError(t, err)
+		// The owner and the nine unfilled reserved seats use every seat.
+		usage, err = client.LicenseUsage(ctx, codersdk.LicenseUsageRequest{})
+		require.NoError(t, err)
+		require.NotNil(t, usage.ExhaustedAt)
+
+		err = client.DeleteLicenseSeatReservation(ctx, group.ID)
+		require.NoError(t, err)
+		reservations, err := client.LicenseSeatReservations(ctx)
+		require.NoError(t, err)
+		require.Empty(t, reservations)
+	})
+
+	t.Run("UnknownGroup", func(t *testing.T) {
+		t.Parallel()
+		client, _ := coderdenttest.New(t, nil)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		_, err := client.UpsertLicenseSeatReservation(ctx, uuid.New(), codersdk.UpsertLicenseSeatReservationRequest{
+			Seats: 1,
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+		client, owner := coderdenttest.New(t, nil)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		_, err := member.LicenseUsage(ctx, codersdk.LicenseUsageRequest{})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+	})
+}
@@ -0,0 +1,312 @@
+package coderd
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	defaultLicenseUsageDays = 90
+	maxLicenseUsageDays     = 730
+)
+
+// @Summary Get license usage and seat forecast
+// @ID get-license-usage-and-seat-forecast
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param history_days query int false "Number of past days used for the trend"
+// @Param forecast_days query int false "Number of days to forecast"
+// @Success 200 {object} codersdk.LicenseUsage
+// @Router /licenses/usage [get]
+func (api *API) licenseUsage(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceLicense) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	parser := httpapi.NewQueryParamParser()
+	historyDays := parser.Int(r.URL.Query(), defaultLicenseUsageDays, "history_days")
+	forecastDays := parser.Int(r.URL.Query(), defaultLicenseUsageDays, "forecast_days")
+	parser.ErrorExcessParams(r.URL.Query())
+	if historyDays < 1 || historyDays > maxLicenseUsageDays {
+		parser.Errors = append(parser.Errors, codersdk.ValidationError{
+			Field:  "history_days",
+			Detail: xerrors.Errorf("must be between 1 and %d", maxLicenseUsageDays).Error(),
+		})
+	}
+	if forecastDays < 0 || forecastDays > maxLicenseUsageDays {
+		parser.Errors = append(parser.Errors, codersdk.ValidationError{
+			Field:  "forecast_days",
+			Detail: xerrors.Errorf("must be between 0 and %d", maxLicenseUsageDays).Error(),
+		})
+	}
+	if len(parser.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: parser.Errors,
+		})
+		return
+	}
+
+	// The caller may read licenses, which covers the user counts that the
+	// license is checked against.
+	//nolint:gocritic // License usage counts every user.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	activeUsers, err := api.Database.GetActiveUserCount(sysCtx, false)
+	if err != nil {
+		httpapi.InternalServerError(rw, xerrors.Errorf("get active user count: %w", err))
+		return
+	}
+
+	today := dbtime.StartOfDay(dbtime.Now())
+	counts, err := api.Database.GetUserStatusCounts(sysCtx, database.GetUserStatusCountsParams{
+		StartTime: today.AddDate(0, 0, -historyDays),
+		EndTime:   today,
+		Interval:  int32((24 * time.Hour).Seconds()),
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, xerrors.Errorf("get user status counts: %w", err))
+		return
+	}
+	history := activeUsersByDay(counts, today.AddDate(0, 0, -historyDays), today)
+
+	rows, err := api.Database.GetLicenseSeatReservations(ctx)
+	if err != nil {
+		httpapi.InternalServerError(rw, xerrors.Errorf("get seat reservations: %w", err))
+		return
+	}
+	reservations := make([]codersdk.LicenseSeatReservation, 0, len(rows))
+	var unfilled int64
+	for _, row := range rows {
+		reservation := convertLicenseSeatReservation(row)
+		unfilled += max(0, reservation.Seats-reservation.ActiveMembers)
+		reservations = append(reservations, reservation)
+	}
+
+	usage := codersdk.LicenseUsage{
+		ActiveUsers:           activeUsers,
+		UnfilledReservedSeats: unfilled,
+		History:               history,
+		Forecast:              forecastActiveUsers(history, forecastDays),
+		Reservations:          reservations,
+	}
+	if feature, ok := api.Entitlements.Feature(codersdk.FeatureUserLimit); ok && feature.Enabled && feature.Limit != nil {
+		seats := *feature.Limit
+		usage.Seats = &seats
+		if activeUsers+unfilled >= seats {
+			usage.ExhaustedAt = &today
+		} else {
+			for _, point := range usage.Forecast {
+				if point.ActiveUsers+unfilled >= seats {
+					date := point.Date
+					usage.ExhaustedAt = &date
+					break
+				}
+			}
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, usage)
+}
+
+// activeUsersByDay returns the number of active users on every day between
+// start and end. Days without any status counts had no active users.
+func activeUsersByDay(counts []database.GetUserStatusCountsRow, start, end time.Time) []codersdk.LicenseUsagePoint {
+	active := map[int64]int64{}
+	for _, row := range counts {
+		if row.Status == database.UserStatusActive {
+			active[row.Date.Unix()] = row.Count
+		}
+	}
+	var points []codersdk.LicenseUsagePoint
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		points = append(points, codersdk.LicenseUsagePoint{
+			Date:        day,
+			ActiveUsers: active[day.Unix()],
+		})
+	}
+	return points
+}
+
+// forecastActiveUsers projects the number of active users for the days after
+// the history with a least squares fit of the history.
+func forecastActiveUsers(history []codersdk.LicenseUsagePoint, days int) []codersdk.LicenseUsagePoint {
+	forecast := make([]codersdk.LicenseUsagePoint, 0, days)
+	if len(history) == 0 || days == 0 {
+		return forecast
+	}
+
+	n := float64(len(history))
+	var sumX, sumY, sumXY, sumXX float64
+	for i, point := range history {
+		x := float64(i)
+		y := float64(point.ActiveUsers)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	var slope float64
+	if denominator := n*sumXX - sumX*sumX; denominator != 0 {
+		slope = (n*sumXY - sumX*sumY) / denominator
+	}
+	intercept := (sumY - slope*sumX) / n
+
+	last := history[len(history)-1].Date
+	for i := 1; i <= days; i++ {
+		x := float64(len(history) - 1 + i)
+		forecast = append(forecast, codersdk.LicenseUsagePoint{
+			Date:        last.AddDate(0, 0, i),
+			ActiveUsers: int64(math.Max(0, math.Round(intercept+slope*x))),
+		})
+	}
+	return forecast
+}
+
+// @Summary Get license seat reservations
+// @ID get-license-seat-reservations
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Success 200 {array} codersdk.LicenseSeatReservation
+// @Router /licenses/reservations [get]
+func (api *API) licenseSeatReservations(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rows, err := api.Database.GetLicenseSeatReservations(ctx)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	reservations := make([]codersdk.LicenseSeatReservation, 0, len(rows))
+	for _, row := range rows {
+		reservations = append(reservations, convertLicenseSeatReservation(row))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, reservations)
+}
+
+// @Summary Reserve license seats for a group
+// @ID reserve-license-seats-for-a-group
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param group path string true "Group ID" format(uuid)
+// @Param request body codersdk.UpsertLicenseSeatReservationRequest true "Seat reservation"
+// @Success 200 {object} codersdk.LicenseSeatReservation
+// @Router /licenses/reservations/{group} [put]
+func (api *API) putLicenseSeatReservation(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	groupID, ok := httpmw.ParseUUIDParam(rw, r, "group")
+	if !ok {
+		return
+	}
+
+	var req codersdk.UpsertLicenseSeatReservationRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Seats > math.MaxInt32 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Too many seats.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "seats",
+				Detail: "must be at most 2147483647",
+			}},
+		})
+		return
+	}
+
+	group, err := api.Database.GetGroupByID(ctx, groupID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	now := dbtime.Now()
+	_, err = api.Database.UpsertLicenseSeatReservation(ctx, database.UpsertLicenseSeatReservationParams{
+		GroupID:   group.ID,
+		Seats:     int32(req.Seats),
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rows, err := api.Database.GetLicenseSeatReservations(ctx)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	for _, row := range rows {
+		if row.GroupID == group.ID {
+			httpapi.Write(ctx, rw, http.StatusOK, convertLicenseSeatReservation(row))
+			return
+		}
+	}
+	httpapi.InternalServerError(rw, xerrors.New("seat reservation not found after upsert"))
+}
+
+// @Summary Delete license seat reservation
+// @ID delete-license-seat-reservation
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param group path string true "Group ID" format(uuid)
+// @Success 204
+// @Router /licenses/reservations/{group} [delete]
+func (api *API) deleteLicenseSeatReservation(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	groupID, ok := httpmw.ParseUUIDParam(rw, r, "group")
+	if !ok {
+		return
+	}
+
+	err := api.Database.DeleteLicenseSeatReservation(ctx, groupID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func convertLicenseSeatReservation(row database.GetLicenseSeatReservationsRow) codersdk.LicenseSeatReservation {
+	return codersdk.LicenseSeatReservation{
+		GroupID:        row.GroupID,
+		GroupName:      row.GroupName,
+		OrganizationID: row.OrganizationID,
+		Seats:          int64(row.Seats),
+		ActiveMembers:  row.ActiveMembers,
+	}
+}
@@ -0,0 +1,53 @@
+package coderd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func Test_forecastActiveUsers(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := func(users ...int64) []codersdk.LicenseUsagePoint {
+		points := make([]codersdk.LicenseUsagePoint, 0, len(users))
+		for i, u := range users {
+			points = append(points, codersdk.LicenseUsagePoint{Date: start.AddDate(0, 0, i), ActiveUsers: u})
+		}
+		return points
+	}
+
+	t.Run("Linear", func(t *testing.T) {
+		t.Parallel()
+		forecast := forecastActiveUsers(history(10, 12, 14, 16), 3)
+		require.Len(t, forecast, 3)
+		require.Equal(t, start.AddDate(0, 0, 4), forecast[0].Date)
+		require.EqualValues(t, 18, forecast[0].ActiveUsers)
+		require.EqualValues(t, 22, forecast[2].ActiveUsers)
+	})
+
+	t.Run("Flat", func(t *testing.T) {
+		t.Parallel()
+		forecast := forecastActiveUsers(history(7), 2)
+		require.Len(t, forecast, 2)
+		require.EqualValues(t, 7, forecast[1].ActiveUsers)
+	})
+
+	t.Run("NeverNegative", func(t *testing.T) {
+		t.Parallel()
+		forecast := forecastActiveUsers(history(6, 4, 2, 0), 5)
+		for _, point := range forecast {
+			require.Zero(t, point.ActiveUsers)
+		}
+	})
+
+	t.Run("NoDays", func(t *testing.T) {
+		t.Parallel()
+		require.Empty(t, forecastActiveUsers(history(1, 2), 0))
+		require.Empty(t, forecastActiveUsers(nil, 10))
+	})
+}
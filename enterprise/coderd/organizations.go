@@ -26,6 +26,7 @@ import (
 // @Tags Organizations
 // @Param organization path string true "Organization ID or name"
 // @Param request body codersdk.UpdateOrganizationRequest true "Patch organization request"
+// @Param If-Match header string false "Only apply the change if the resource still has this ETag"
 // @Success 200 {object} codersdk.Organization
 // @Router /organizations/{organization} [patch]
 func (api *API) patchOrganization(rw http.ResponseWriter, r *http.Request) {
@@ -48,6 +49,9 @@ func (api *API) patchOrganization(rw http.ResponseWriter, r *http.Request) {
 	if !httpapi.Read(ctx, rw, r, &req) {
 		return
 	}
+	if !httpapi.IfMatch(ctx, rw, r, db2sdk.Organization(organization).ETag()) {
+		return
+	}
 
 	// "default" is a reserved name that always refers to the default org (much like the way we
 	// use "me" for users).
@@ -117,7 +121,9 @@ func (api *API) patchOrganization(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	aReq.New = organization
-	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.Organization(organization))
+	resp := db2sdk.Organization(organization)
+	httpapi.SetETag(rw, resp.ETag())
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
 // @Summary Delete organization
@@ -126,6 +132,7 @@ func (api *API) patchOrganization(rw http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Tags Organizations
 // @Param organization path string true "Organization ID or name"
+// @Param If-Match header string false "Only apply the change if the resource still has this ETag"
 // @Success 200 {object} codersdk.Response
 // @Router /organizations/{organization} [delete]
 func (api *API) deleteOrganization(rw http.ResponseWriter, r *http.Request) {
@@ -150,6 +157,9 @@ func (api *API) deleteOrganization(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	if !httpapi.IfMatch(ctx, rw, r, db2sdk.Organization(organization).ETag()) {
+		return
+	}
 
 	err := api.Database.InTx(func(tx database.Store) error {
 		err := tx.UpdateOrganizationDeletedByID(ctx, database.UpdateOrganizationDeletedByIDParams{
@@ -0,0 +1,221 @@
+package coderd
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/externalauth"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/schedule/cron"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get organization settings
+// @Description Returns the deployment settings overridden by the organization,
+// @Description the deployment settings, and the settings that apply to the
+// @Description organization.
+// @ID get-organization-settings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {object} codersdk.OrganizationSettings
+// @Router /organizations/{organization}/settings/overrides [get]
+func (api *API) organizationSettings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	overrides, err := api.Database.GetOrganizationSettingOverrides(ctx, org.ID)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertOrganizationSettings(overrides))
+}
+
+// @Summary Update organization setting overrides
+// @Description Replaces the deployment settings overridden by the
+// @Description organization. Settings that are null inherit the deployment
+// @Description setting.
+// @ID update-organization-setting-overrides
+// @Security CoderSessionToken
+// @Produce json
+// @Accept json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.OrganizationSettingOverrides true "Overrides"
+// @Success 200 {object} codersdk.OrganizationSettings
+// @Router /organizations/{organization}/settings/overrides [put]
+func (api *API) putOrganizationSettingOverrides(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	var req codersdk.OrganizationSettingOverrides
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	params := database.UpsertOrganizationSettingOverridesParams{
+		OrganizationID:                  org.ID,
+		AllowedExternalAuthProviders:    req.AllowedExternalAuthProviders,
+		DisabledNotificationTemplateIDs: []uuid.UUID{},
+		UpdatedAt:                       dbtime.Now(),
+	}
+	var validErrs []codersdk.ValidationError
+	if req.DefaultTTLMillis != nil {
+		if *req.DefaultTTLMillis < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "default_ttl_ms", Detail: "Must be a positive integer."})
+		}
+		params.DefaultTTL = sql.NullInt64{Int64: int64(time.Duration(*req.DefaultTTLMillis) * time.Millisecond), Valid: true}
+	}
+	if req.DefaultQuietHoursSchedule != nil {
+		sched, err := cron.Daily(*req.DefaultQuietHoursSchedule)
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "default_quiet_hours_schedule", Detail: err.Error()})
+		} else if strings.HasPrefix(sched.Time(), "cron(") {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "default_quiet_hours_schedule", Detail: "Must have a single time."})
+		}
+		params.DefaultQuietHoursSchedule = sql.NullString{String: *req.DefaultQuietHoursSchedule, Valid: true}
+	}
+	for _, id := range req.AllowedExternalAuthProviders {
+		if !slices.ContainsFunc(api.ExternalAuthConfigs, func(cfg *externalauth.Config) bool { return cfg.ID == id }) {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "allowed_external_auth_providers",
+				Detail: fmt.Sprintf("External auth provider %q is not configured.", id),
+			})
+		}
+	}
+	if len(req.DisabledNotificationTemplateIDs) > 0 {
+		templates, err := api.Database.GetNotificationTemplatesByKind(ctx, database.NotificationTemplateKindSystem)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		for _, id := range req.DisabledNotificationTemplateIDs {
+			if !slices.ContainsFunc(templates, func(tmpl database.NotificationTemplate) bool { return tmpl.ID == id }) {
+				validErrs = append(validErrs, codersdk.ValidationError{
+					Field:  "disabled_notification_template_ids",
+					Detail: fmt.Sprintf("Notification template %q does not exist.", id),
+				})
+			}
+		}
+		params.DisabledNotificationTemplateIDs = req.DisabledNotificationTemplateIDs
+	}
+	if req.ProvisionerMaxJobs != nil {
+		if *req.ProvisionerMaxJobs < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "provisioner_max_jobs", Detail: "Must be a positive integer."})
+		}
+		params.ProvisionerMaxJobs = sql.NullInt32{Int32: *req.ProvisionerMaxJobs, Valid: true}
+	}
+	if req.ProvisionerReservedJobs != nil {
+		if *req.ProvisionerReservedJobs < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "provisioner_reserved_jobs", Detail: "Must be a positive integer."})
+		}
+		params.ProvisionerReservedJobs = sql.NullInt32{Int32: *req.ProvisionerReservedJobs, Valid: true}
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid organization setting overrides.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	// Provisioner capacity is shared with the other organizations, so only
+	// deployment admins may change the limits and reservations.
+	current, err := api.Database.GetOrganizationSettingOverrides(ctx, org.ID)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if current.ProvisionerMaxJobs != params.ProvisionerMaxJobs || current.ProvisionerReservedJobs != params.ProvisionerReservedJobs {
+		if !api.Authorize(r, policy.ActionUpdate, rbac.ResourceDeploymentConfig) {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: "Only deployment admins can change the provisioner limits of an organization.",
+			})
+			return
+		}
+	}
+
+	overrides, err := api.Database.UpsertOrganizationSettingOverrides(ctx, params)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertOrganizationSettings(overrides))
+}
+
+func (api *API) convertOrganizationSettings(overrides database.OrganizationSettingOverride) codersdk.OrganizationSettings {
+	deployment := codersdk.OrganizationSettingValues{
+		DefaultQuietHoursSchedule:       api.DeploymentValues.UserQuietHoursSchedule.DefaultSchedule.String(),
+		AllowedExternalAuthProviders:    make([]string, 0, len(api.ExternalAuthConfigs)),
+		DisabledNotificationTemplateIDs: []uuid.UUID{},
+		ProvisionerMaxJobs:              int32(min(api.DeploymentValues.Provisioner.MaxJobsPerOrganization.Value(), math.MaxInt32)),      //nolint:gosec // Clamped to MaxInt32.
+		ProvisionerReservedJobs:         int32(min(api.DeploymentValues.Provisioner.ReservedJobsPerOrganization.Value(), math.MaxInt32)), //nolint:gosec // Clamped to MaxInt32.
+	}
+	for _, cfg := range api.ExternalAuthConfigs {
+		deployment.AllowedExternalAuthProviders = append(deployment.AllowedExternalAuthProviders, cfg.ID)
+	}
+
+	settings := codersdk.OrganizationSettings{
+		Overrides: codersdk.OrganizationSettingOverrides{
+			AllowedExternalAuthProviders:    overrides.AllowedExternalAuthProviders,
+			DisabledNotificationTemplateIDs: []uuid.UUID{},
+		},
+		Deployment: deployment,
+		Effective:  deployment,
+	}
+	if overrides.DefaultTTL.Valid {
+		ttl := time.Duration(overrides.DefaultTTL.Int64).Milliseconds()
+		settings.Overrides.DefaultTTLMillis = &ttl
+		settings.Effective.DefaultTTLMillis = ttl
+	}
+	if overrides.DefaultQuietHoursSchedule.Valid {
+		settings.Overrides.DefaultQuietHoursSchedule = &overrides.DefaultQuietHoursSchedule.String
+		settings.Effective.DefaultQuietHoursSchedule = overrides.DefaultQuietHoursSchedule.String
+	}
+	if overrides.AllowedExternalAuthProviders != nil {
+		settings.Effective.AllowedExternalAuthProviders = overrides.AllowedExternalAuthProviders
+	}
+	if len(overrides.DisabledNotificationTemplateIDs) > 0 {
+		settings.Overrides.DisabledNotificationTemplateIDs = overrides.DisabledNotificationTemplateIDs
+		settings.Effective.DisabledNotificationTemplateIDs = overrides.DisabledNotificationTemplateIDs
+	}
+	if overrides.ProvisionerMaxJobs.Valid {
+		settings.Overrides.ProvisionerMaxJobs = &overrides.ProvisionerMaxJobs.Int32
+		settings.Effective.ProvisionerMaxJobs = overrides.ProvisionerMaxJobs.Int32
+	}
+	if overrides.ProvisionerReservedJobs.Valid {
+		settings.Overrides.ProvisionerReservedJobs = &overrides.ProvisionerReservedJobs.Int32
+		settings.Effective.ProvisionerReservedJobs = overrides.ProvisionerReservedJobs.Int32
+	}
+	return settings
+}
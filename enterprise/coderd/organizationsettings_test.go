@@ -0,0 +1,156 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/externalauth"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestOrganizationSettings(t *testing.T) {
+	t.Parallel()
+
+	newClient := func(t *testing.T) (*codersdk.Client, codersdk.CreateFirstUserResponse) {
+		return coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+				ExternalAuthConfigs: []*externalauth.Config{
+					{ID: "github"},
+					{ID: "gitlab"},
+				},
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureMultipleOrganizations: 1,
+				},
+			},
+		})
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		owner, user := newClient(t)
+		orgAdmin, _ := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID, rbac.ScopedRoleOrgAdmin(user.OrganizationID))
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		settings, err := orgAdmin.OrganizationSettings(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Nil(t, settings.Overrides.DefaultTTLMillis)
+		require.Nil(t, settings.Overrides.AllowedExternalAuthProviders)
+		require.Equal(t, []string{"github", "gitlab"}, settings.Effective.AllowedExternalAuthProviders)
+		require.Equal(t, settings.Deployment, settings.Effective)
+
+		ttl := (8 * time.Hour).Milliseconds()
+		settings, err = orgAdmin.PutOrganizationSettingOverrides(ctx, user.OrganizationID, codersdk.OrganizationSettingOverrides{
+			DefaultTTLMillis:             &ttl,
+			AllowedExternalAuthProviders: []string{"github"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, ttl, settings.Effective.DefaultTTLMillis)
+		require.Zero(t, settings.Deployment.DefaultTTLMillis)
+		require.Equal(t, []string{"github"}, settings.Effective.AllowedExternalAuthProviders)
+		// Settings that are not overridden are inherited.
+		require.Nil(t, settings.Overrides.DefaultQuietHoursSchedule)
+		require.Equal(t, settings.Deployment.DefaultQuietHoursSchedule, settings.Effective.DefaultQuietHoursSchedule)
+
+		// Templates created without a default autostop use the default of
+		// the organization.
+		version := coderdtest.CreateTemplateVersion(t, owner, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, owner, version.ID)
+		template := coderdtest.CreateTemplate(t, owner, user.OrganizationID, version.ID)
+		require.Equal(t, ttl, template.DefaultTTLMillis)
+
+		version = coderdtest.CreateTemplateVersion(t, owner, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, owner, version.ID)
+		template = coderdtest.CreateTemplate(t, owner, user.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+			ctr.DefaultTTLMillis = ptr.Ref(time.Hour.Milliseconds())
+		})
+		require.Equal(t, time.Hour.Milliseconds(), template.DefaultTTLMillis)
+
+		// Removing the overrides inherits the deployment settings again.
+		settings, err = orgAdmin.PutOrganizationSettingOverrides(ctx, user.OrganizationID, codersdk.OrganizationSettingOverrides{})
+		require.NoError(t, err)
+		require.Equal(t, settings.Deployment, settings.Effective)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		owner, user := newClient(t)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := owner.PutOrganizationSettingOverrides(ctx, user.OrganizationID, codersdk.OrganizationSettingOverrides{
+			DefaultQuietHoursSchedule:    ptr.Ref("CRON_TZ=UTC 0 0,12 * * *"),
+			AllowedExternalAuthProviders: []string{"bitbucket"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 2)
+	})
+
+	t.Run("MemberCannotUpdate", func(t *testing.T) {
+		t.Parallel()
+
+		owner, user := newClient(t)
+		member, _ := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.PutOrganizationSettingOverrides(ctx, user.OrganizationID, codersdk.OrganizationSettingOverrides{
+			AllowedExternalAuthProviders: []string{},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+	t.Run("ProvisionerLimits", func(t *testing.T) {
+		t.Parallel()
+
+		owner, user := newClient(t)
+		orgAdmin, _ := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID, rbac.ScopedRoleOrgAdmin(user.OrganizationID))
+
+		// Organization admins can't change how much of the shared
+		// provisioner capacity their organization gets.
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := orgAdmin.PutOrganizationSettingOverrides(ctx, user.OrganizationID, codersdk.OrganizationSettingOverrides{
+			ProvisionerReservedJobs: ptr.Ref[int32](5),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		settings, err := owner.PutOrganizationSettingOverrides(ctx, user.OrganizationID, codersdk.OrganizationSettingOverrides{
+			ProvisionerMaxJobs:      ptr.Ref[int32](10),
+			ProvisionerReservedJobs: ptr.Ref[int32](5),
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 10, settings.Effective.ProvisionerMaxJobs)
+		require.EqualValues(t, 5, settings.Effective.ProvisionerReservedJobs)
+
+		// Organization admins can change other settings as long as they keep
+		// the provisioner limits.
+		overrides := settings.Overrides
+		overrides.AllowedExternalAuthProviders = []string{"github"}
+		settings, err = orgAdmin.PutOrganizationSettingOverrides(ctx, user.OrganizationID, overrides)
+		require.NoError(t, err)
+		require.EqualValues(t, 5, settings.Effective.ProvisionerReservedJobs)
+		require.Equal(t, []string{"github"}, settings.Effective.AllowedExternalAuthProviders)
+
+		_, err = owner.PutOrganizationSettingOverrides(ctx, user.OrganizationID, codersdk.OrganizationSettingOverrides{
+			ProvisionerMaxJobs: ptr.Ref[int32](-1),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
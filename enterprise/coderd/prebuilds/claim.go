@@ -27,10 +27,12 @@ func (c EnterpriseClaimer) Claim(
 	userID uuid.UUID,
 	name string,
 	presetID uuid.UUID,
+	ephemeral bool,
 ) (*uuid.UUID, error) {
 	result, err := c.store.ClaimPrebuiltWorkspace(ctx, database.ClaimPrebuiltWorkspaceParams{
 		NewUserID: userID,
 		NewName:   name,
+		Ephemeral: ephemeral,
 		PresetID:  presetID,
 	})
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"net/http"
 	"slices"
 	"strings"
 	"sync/atomic"
@@ -24,6 +25,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	agplprebuilds "github.com/coder/coder/v2/coderd/prebuilds"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
 	"github.com/coder/coder/v2/enterprise/coderd/license"
@@ -372,6 +374,136 @@ func TestClaimPrebuild(t *testing.T) {
 	}
 }
 
+func TestClaimAndReleasePrebuiltWorkspace(t *testing.T) {
+	t.Parallel()
+
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("This test requires postgres")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitSuperLong)
+	db, pubsub := dbtestutil.NewDB(t)
+	logger := testutil.Logger(t)
+	client, _, api, owner := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{
+			Database: db,
+			Pubsub:   pubsub,
+		},
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		},
+	})
+	orgID := owner.OrganizationID
+	provisionerCloser := coderdenttest.NewExternalProvisionerDaemon(t, client, orgID, map[string]string{
+		provisionersdk.TagScope: provisionersdk.ScopeOrganization,
+	})
+	defer provisionerCloser.Close()
+
+	cache := files.New(prometheus.NewRegistry(), &coderdtest.FakeAuthorizer{})
+	reconciler := prebuilds.NewStoreReconciler(db, pubsub, cache, codersdk.PrebuildsConfig{}, logger, quartz.NewMock(t), prometheus.NewRegistry(), newNoopEnqueuer())
+	var claimer agplprebuilds.Claimer = prebuilds.NewEnterpriseClaimer(db)
+	api.AGPL.PrebuildsClaimer.Store(&claimer)
+
+	version := coderdtest.CreateTemplateVersion(t, client, orgID, templateWithAgentAndPresetsWithPrebuilds(1))
+	_ = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	coderdtest.CreateTemplate(t, client, orgID, version.ID)
+	presets, err := client.TemplateVersionPresets(ctx, version.ID)
+	require.NoError(t, err)
+	preset := presets[0]
+
+	userClient, user := coderdtest.CreateAnotherUser(t, client, orgID, rbac.RoleMember())
+
+	reconcile := func() {
+		t.Helper()
+		state, err := reconciler.SnapshotState(ctx, db)
+		require.NoError(t, err)
+		ps, err := state.FilterByPreset(preset.ID)
+		require.NoError(t, err)
+		require.NoError(t, reconciler.ReconcilePreset(ctx, *ps))
+	}
+	// awaitReadyPrebuild waits for a running prebuilt workspace of the
+	// preset, and marks its agents ready so it can be claimed.
+	awaitReadyPrebuild := func() uuid.UUID {
+		t.Helper()
+		var prebuildID uuid.UUID
+		require.Eventually(t, func() bool {
+			rows, err := db.GetRunningPrebuiltWorkspaces(ctx)
+			if err != nil {
+				return false
+			}
+			for _, row := range rows {
+				if row.CurrentPresetID.UUID != preset.ID {
+					continue
+				}
+				agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, row.ID)
+				if err != nil {
+					return false
+				}
+				for _, agent := range agents {
+					err = db.UpdateWorkspaceAgentLifecycleStateByID(ctx, database.UpdateWorkspaceAgentLifecycleStateByIDParams{
+						ID:             agent.ID,
+						LifecycleState: database.WorkspaceAgentLifecycleStateReady,
+						StartedAt:      sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true},
+						ReadyAt:        sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true},
+					})
+					if err != nil {
+						return false
+					}
+				}
+				prebuildID = row.ID
+				return true
+			}
+			return false
+		}, testutil.WaitSuperLong, testutil.IntervalSlow)
+		return prebuildID
+	}
+
+	// Given: a ready prebuilt workspace in the pool.
+	reconcile()
+	prebuildID := awaitReadyPrebuild()
+
+	// When: a CI job claims it.
+	ttl := 30 * time.Minute
+	workspace, err := userClient.ClaimPrebuiltWorkspace(ctx, orgID, codersdk.ClaimPrebuiltWorkspaceRequest{
+		TemplateVersionPresetID: preset.ID,
+		TTLMillis:               ptr.Ref(ttl.Milliseconds()),
+	})
+	require.NoError(t, err)
+
+	// Then: the prebuilt workspace is transferred to the caller.
+	require.Equal(t, prebuildID, workspace.ID)
+	require.Equal(t, user.ID, workspace.OwnerID)
+	require.Equal(t, ttl.Milliseconds(), *workspace.TTLMillis)
+
+	// Then: the deadline of the claim build is set by the requested TTL
+	// instead of the TTL the workspace was prebuilt with.
+	build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, userClient, workspace.LatestBuild.ID)
+	require.Equal(t, codersdk.ProvisionerJobSucceeded, build.Job.Status)
+	require.NotNil(t, build.Job.CompletedAt)
+	require.True(t, build.Deadline.Valid)
+	require.WithinDuration(t, build.Job.CompletedAt.Add(ttl), build.Deadline.Time, time.Minute)
+
+	// When: the job is done and releases the workspace.
+	release, err := userClient.ReleasePrebuiltWorkspace(ctx, orgID, workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceTransitionDelete, release.Transition)
+	release = coderdtest.AwaitWorkspaceBuildJobCompleted(t, userClient, release.ID)
+	require.Equal(t, codersdk.ProvisionerJobSucceeded, release.Job.Status)
+
+	// Then: the workspace is deleted.
+	_, err = userClient.Workspace(ctx, workspace.ID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusGone, apiErr.StatusCode())
+
+	// Then: the reconciler refills the pool with a new prebuilt workspace.
+	reconcile()
+	replacementID := awaitReadyPrebuild()
+	require.NotEqual(t, workspace.ID, replacementID)
+}
+
 func templateWithAgentAndPresetsWithPrebuilds(desiredInstances int32) *echo.Responses {
 	return &echo.Responses{
 		Parse: echo.ParseComplete,
@@ -355,6 +355,7 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		api.DeploymentValues,
 		provisionerdserver.Options{
 			ExternalAuthConfigs: api.ExternalAuthConfigs,
+			TemplatePolicy:      api.TemplatePolicy,
 			OIDCConfig:          api.OIDCConfig,
 			Clock:               api.Clock,
 		},
@@ -186,6 +186,15 @@ func (p *ProxyHealth) HealthStatus() map[uuid.UUID]ProxyStatus {
 	return *ptr
 }
 
+// Interval returns how often proxy health is checked, which is also how long a
+// health status stays current.
+func (p *ProxyHealth) Interval() time.Duration {
+	if p == nil {
+		return 0
+	}
+	return p.interval
+}
+
 type ProxyStatus struct {
 	// ProxyStatus includes the value of the proxy at the time of checking. This is
 	// useful to know as it helps determine if the proxy checked has different values
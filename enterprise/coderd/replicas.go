@@ -3,6 +3,8 @@ package coderd
 import (
 	"net/http"
 
+	"cdr.dev/slog"
+
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/rbac"
@@ -44,3 +46,55 @@ func convertReplica(replica database.Replica) codersdk.Replica {
 		DatabaseLatency: replica.DatabaseLatency,
 	}
 }
+
+// @Summary Restart replicas
+// @Description Restarts every healthy replica. Replicas drain and shut down,
+// @Description and are expected to be started again by their supervisor.
+// @ID restart-replicas
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param request body codersdk.RestartReplicasRequest true "Restart replicas request"
+// @Success 202 {object} codersdk.ReplicasRestart
+// @Router /replicas/restart [post]
+func (api *API) postReplicasRestart(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.AGPL.Authorize(r, policy.ActionUpdate, rbac.ResourceDeploymentConfig) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.RestartReplicasRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.DrainTimeout < 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Drain timeout must not be negative.",
+		})
+		return
+	}
+	if req.DrainTimeout == 0 {
+		req.DrainTimeout = codersdk.DefaultReplicaDrainTimeout
+	}
+
+	restart, err := api.replicaManager.Restart(ctx, req.Rolling, req.DrainTimeout)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error restarting replicas.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.Logger.Info(ctx, "replicas restart requested",
+		slog.F("restart_id", restart.ID),
+		slog.F("rolling", restart.Rolling),
+		slog.F("replicas", len(restart.Replicas)),
+	)
+	httpapi.Write(ctx, rw, http.StatusAccepted, codersdk.ReplicasRestart{
+		ID:       restart.ID,
+		Replicas: restart.Replicas,
+		Rolling:  restart.Rolling,
+	})
+}
@@ -250,6 +250,115 @@ func (api *API) deleteOrgRole(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
 }
 
+// simulateOrgRole checks whether a custom role would allow an action on a
+// resource in the organization. The role is not saved.
+//
+// @Summary Simulate a custom organization role
+// @ID simulate-a-custom-organization-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.SimulateCustomRoleRequest true "Simulate role request"
+// @Tags Members
+// @Success 200 {object} codersdk.SimulateCustomRoleResponse
+// @Router /organizations/{organization}/members/roles/simulate [post]
+func (api *API) simulateOrgRole(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+	)
+
+	// Simulating a role is part of editing one, so require the same
+	// permission.
+	if !api.Authorize(r, policy.ActionCreate, rbac.ResourceAssignOrgRole.InOrg(organization.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.SimulateCustomRoleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if len(req.Role.SitePermissions) > 0 || len(req.Role.UserPermissions) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid request, organization roles may only contain organization permissions.",
+			Detail:  "organization scoped roles may not contain site wide or user permissions",
+		})
+		return
+	}
+	for _, perm := range req.Role.OrganizationPermissions {
+		if err := sdkPermissionToRBAC(perm).Valid(); err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid role permission.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+	sample := rbac.Permission{
+		ResourceType: string(req.ResourceType),
+		Action:       policy.Action(req.Action),
+	}
+	if err := sample.Valid(); err != nil || sample.ResourceType == policy.WildcardSymbol || sample.Action == policy.WildcardSymbol {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid resource type or action.",
+			Detail:  fmt.Sprintf("%q is not a valid action for the resource type %q", req.Action, req.ResourceType),
+		})
+		return
+	}
+
+	role := rbac.Role{
+		Identifier: rbac.RoleIdentifier{Name: req.Role.Name, OrganizationID: organization.ID},
+		Org: map[string][]rbac.Permission{
+			organization.ID.String(): db2sdk.List(req.Role.OrganizationPermissions, sdkPermissionToRBAC),
+		},
+	}
+	// The simulated subject only has the simulated role, so the result is not
+	// affected by the roles of the caller or the implied member role.
+	subject := rbac.Subject{
+		FriendlyName: "simulated",
+		Type:         rbac.SubjectTypeUser,
+		ID:           uuid.NewString(),
+		Roles:        rbac.Roles{role},
+		Scope:        rbac.ScopeAll,
+	}
+	object := rbac.Object{Type: sample.ResourceType}.InOrg(organization.ID)
+	err := api.AGPL.Authorizer.Authorize(ctx, subject, sample.Action, object)
+	if err != nil && !rbac.IsUnauthorizedError(err) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.SimulateCustomRoleResponse{
+		Allowed:           err == nil,
+		MatchedPermission: matchedPermission(req.Role.OrganizationPermissions, req.ResourceType, req.Action),
+	})
+}
+
+// matchedPermission returns the permission that decides whether the action is
+// allowed. As in the rbac policy, a matching negated permission takes
+// precedence over any other matching permission.
+func matchedPermission(permissions []codersdk.Permission, resource codersdk.RBACResource, action codersdk.RBACAction) *codersdk.Permission {
+	var allowed *codersdk.Permission
+	for i, perm := range permissions {
+		if perm.ResourceType != resource && perm.ResourceType != codersdk.ResourceWildcard {
+			continue
+		}
+		if perm.Action != action && perm.Action != policy.WildcardSymbol {
+			continue
+		}
+		if perm.Negate {
+			return &permissions[i]
+		}
+		if allowed == nil {
+			allowed = &permissions[i]
+		}
+	}
+	return allowed
+}
+
 func filterInvalidPermissions(permissions []codersdk.Permission) []codersdk.Permission {
 	// Filter out any invalid permissions
 	var validPermissions []codersdk.Permission
@@ -267,6 +376,14 @@ func filterInvalidPermissions(permissions []codersdk.Permission) []codersdk.Perm
 	return validPermissions
 }
 
+func sdkPermissionToRBAC(p codersdk.Permission) rbac.Permission {
+	return rbac.Permission{
+		Negate:       p.Negate,
+		ResourceType: string(p.ResourceType),
+		Action:       policy.Action(p.Action),
+	}
+}
+
 func sdkPermissionToDB(p codersdk.Permission) database.CustomRolePermission {
 	return database.CustomRolePermission{
 		Negate:       p.Negate,
@@ -565,3 +565,98 @@ func convertRoles(assignableRoles map[rbac.RoleIdentifier]bool) []codersdk.Assig
 	}
 	return converted
 }
+
+func TestSimulateOrganizationRole(t *testing.T) {
+	t.Parallel()
+
+	owner, first := coderdenttest.New(t, &coderdenttest.Options{
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureCustomRoles: 1,
+			},
+		},
+	})
+	member, _ := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+
+	role := codersdk.CustomRoleRequest{
+		Name: "template-reader",
+		OrganizationPermissions: []codersdk.Permission{
+			{ResourceType: codersdk.ResourceTemplate, Action: codersdk.ActionRead},
+			{ResourceType: codersdk.ResourceWildcard, Action: codersdk.ActionDelete, Negate: true},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		resource codersdk.RBACResource
+		action   codersdk.RBACAction
+		allowed  bool
+		matched  *codersdk.Permission
+	}{
+		{
+			name:     "Allowed",
+			resource: codersdk.ResourceTemplate,
+			action:   codersdk.ActionRead,
+			allowed:  true,
+			matched:  &role.OrganizationPermissions[0],
+		},
+		{
+			name:     "Negated",
+			resource: codersdk.ResourceTemplate,
+			action:   codersdk.ActionDelete,
+			allowed:  false,
+			matched:  &role.OrganizationPermissions[1],
+		},
+		{
+			name:     "NoMatch",
+			resource: codersdk.ResourceWorkspace,
+			action:   codersdk.ActionRead,
+			allowed:  false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.Context(t, testutil.WaitShort)
+
+			//nolint:gocritic // owner is required for this
+			res, err := owner.SimulateOrganizationRole(ctx, first.OrganizationID, codersdk.SimulateCustomRoleRequest{
+				Role:         role,
+				ResourceType: tc.resource,
+				Action:       tc.action,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.allowed, res.Allowed)
+			require.Equal(t, tc.matched, res.MatchedPermission)
+		})
+	}
+
+	t.Run("InvalidAction", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		//nolint:gocritic // owner is required for this
+		_, err := owner.SimulateOrganizationRole(ctx, first.OrganizationID, codersdk.SimulateCustomRoleRequest{
+			Role:         role,
+			ResourceType: codersdk.ResourceTemplate,
+			Action:       codersdk.ActionSSH,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		_, err := member.SimulateOrganizationRole(ctx, first.OrganizationID, codersdk.SimulateCustomRoleRequest{
+			Role:         role,
+			ResourceType: codersdk.ResourceTemplate,
+			Action:       codersdk.ActionRead,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
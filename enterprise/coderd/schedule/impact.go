@@ -0,0 +1,118 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/coder/coder/v2/coderd/database"
+	agpl "github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// TemplateScheduleImpact returns the workspaces whose lifecycle changes if
+// the schedule of their template is changed from current to proposed. It
+// mirrors the workspace updates made by Set, and the checks made by the
+// lifecycle executor on each tick.
+func TemplateScheduleImpact(now time.Time, current, proposed agpl.TemplateScheduleOptions, workspaces []database.GetWorkspacesRow) codersdk.TemplateScheduleImpact {
+	impact := codersdk.TemplateScheduleImpact{
+		Summary:    []codersdk.TemplateScheduleImpactSummary{},
+		Workspaces: []codersdk.TemplateScheduleImpactWorkspace{},
+	}
+	// summaries indexes the summary of each action in impact.Summary.
+	summaries := map[codersdk.TemplateScheduleImpactAction]int{}
+	record := func(workspace database.GetWorkspacesRow, action codersdk.TemplateScheduleImpactAction, currentAt, at *time.Time) {
+		immediate := at != nil && !at.After(now)
+		impact.Workspaces = append(impact.Workspaces, codersdk.TemplateScheduleImpactWorkspace{
+			WorkspaceID:   workspace.ID,
+			WorkspaceName: workspace.Name,
+			OwnerID:       workspace.OwnerID,
+			OwnerName:     workspace.OwnerUsername,
+			Action:        action,
+			CurrentAt:     currentAt,
+			At:            at,
+			Immediate:     immediate,
+		})
+		i, ok := summaries[action]
+		if !ok {
+			i = len(impact.Summary)
+			summaries[action] = i
+			impact.Summary = append(impact.Summary, codersdk.TemplateScheduleImpactSummary{Action: action})
+		}
+		impact.Summary[i].Count++
+		if immediate {
+			impact.Summary[i].Immediate++
+		}
+	}
+	// recordChange records the action if its time changes.
+	recordChange := func(workspace database.GetWorkspacesRow, action codersdk.TemplateScheduleImpactAction, currentAt, at *time.Time) {
+		if !timesEqual(currentAt, at) {
+			record(workspace, action, currentAt, at)
+		}
+	}
+
+	// The TTL of every workspace is replaced if user autostop is disabled, or
+	// if the default TTL changes while it is disabled.
+	resetTTL := !proposed.UserAutostopEnabled &&
+		(current.UserAutostopEnabled || proposed.DefaultTTL != current.DefaultTTL)
+
+	for _, workspace := range workspaces {
+		if workspace.DormantAt.Valid {
+			// Dormant workspaces are deleted once they breach the
+			// time_til_dormant_autodelete of the template.
+			dormantAt := workspace.DormantAt.Time
+			if proposed.UpdateWorkspaceDormantAt {
+				dormantAt = now
+			}
+			var currentAt *time.Time
+			if workspace.DeletingAt.Valid {
+				currentAt = &workspace.DeletingAt.Time
+			}
+			recordChange(workspace, codersdk.TemplateScheduleImpactActionDelete, currentAt, afterDuration(dormantAt, proposed.TimeTilDormantAutoDelete))
+		} else {
+			// Other workspaces become dormant once they have been inactive for
+			// the time_til_dormant of the template.
+			lastUsedAt := workspace.LastUsedAt
+			if proposed.UpdateWorkspaceLastUsedAt != nil {
+				lastUsedAt = now
+			}
+			recordChange(workspace, codersdk.TemplateScheduleImpactActionDormant,
+				afterDuration(workspace.LastUsedAt, current.TimeTilDormant),
+				afterDuration(lastUsedAt, proposed.TimeTilDormant))
+		}
+
+		if workspace.LatestBuildTransition == database.WorkspaceTransitionStart &&
+			workspace.LatestBuildStatus == database.ProvisionerJobStatusFailed &&
+			workspace.LatestBuildCompletedAt.Valid {
+			completedAt := workspace.LatestBuildCompletedAt.Time
+			recordChange(workspace, codersdk.TemplateScheduleImpactActionFailedStop,
+				afterDuration(completedAt, current.FailureTTL),
+				afterDuration(completedAt, proposed.FailureTTL))
+		}
+
+		if resetTTL {
+			var ttl int64
+			if workspace.Ttl.Valid {
+				ttl = workspace.Ttl.Int64
+			}
+			if ttl != int64(proposed.DefaultTTL) {
+				record(workspace, codersdk.TemplateScheduleImpactActionAutostopTTL, nil, nil)
+			}
+		}
+	}
+	return impact
+}
+
+// afterDuration returns t+d, or nil if the duration is disabled.
+func afterDuration(t time.Time, d time.Duration) *time.Time {
+	if d <= 0 {
+		return nil
+	}
+	at := t.Add(d)
+	return &at
+}
+
+func timesEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
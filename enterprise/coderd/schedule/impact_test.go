@@ -0,0 +1,178 @@
+package schedule_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	agplschedule "github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/schedule"
+)
+
+func TestTemplateScheduleImpact(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	workspace := func(name string, mutate func(*database.GetWorkspacesRow)) database.GetWorkspacesRow {
+		ws := database.GetWorkspacesRow{
+			ID:                    uuid.New(),
+			Name:                  name,
+			OwnerID:               uuid.New(),
+			OwnerUsername:         "owner",
+			LastUsedAt:            now.Add(-time.Hour),
+			LatestBuildTransition: database.WorkspaceTransitionStart,
+			LatestBuildStatus:     database.ProvisionerJobStatusSucceeded,
+		}
+		if mutate != nil {
+			mutate(&ws)
+		}
+		return ws
+	}
+	summary := func(impact codersdk.TemplateScheduleImpact, action codersdk.TemplateScheduleImpactAction) codersdk.TemplateScheduleImpactSummary {
+		for _, s := range impact.Summary {
+			if s.Action == action {
+				return s
+			}
+		}
+		return codersdk.TemplateScheduleImpactSummary{Action: action}
+	}
+
+	t.Run("Dormant", func(t *testing.T) {
+		t.Parallel()
+
+		workspaces := []database.GetWorkspacesRow{
+			workspace("inactive", func(ws *database.GetWorkspacesRow) {
+				ws.LastUsedAt = now.Add(-30 * 24 * time.Hour)
+			}),
+			workspace("active", nil),
+			workspace("dormant", func(ws *database.GetWorkspacesRow) {
+				ws.LastUsedAt = now.Add(-30 * 24 * time.Hour)
+				ws.DormantAt = sql.NullTime{Valid: true, Time: now.Add(-time.Hour)}
+			}),
+		}
+		impact := schedule.TemplateScheduleImpact(now, agplschedule.TemplateScheduleOptions{}, agplschedule.TemplateScheduleOptions{
+			TimeTilDormant: 7 * 24 * time.Hour,
+		}, workspaces)
+
+		require.Equal(t, codersdk.TemplateScheduleImpactSummary{
+			Action:    codersdk.TemplateScheduleImpactActionDormant,
+			Count:     2,
+			Immediate: 1,
+		}, summary(impact, codersdk.TemplateScheduleImpactActionDormant))
+		require.Len(t, impact.Workspaces, 2)
+		require.Equal(t, "inactive", impact.Workspaces[0].WorkspaceName)
+		require.True(t, impact.Workspaces[0].Immediate)
+		require.Nil(t, impact.Workspaces[0].CurrentAt)
+		require.Equal(t, "active", impact.Workspaces[1].WorkspaceName)
+		require.False(t, impact.Workspaces[1].Immediate)
+		require.Equal(t, now.Add(-time.Hour+7*24*time.Hour), *impact.Workspaces[1].At)
+	})
+
+	t.Run("UpdateLastUsedAt", func(t *testing.T) {
+		t.Parallel()
+
+		workspaces := []database.GetWorkspacesRow{
+			workspace("inactive", func(ws *database.GetWorkspacesRow) {
+				ws.LastUsedAt = now.Add(-30 * 24 * time.Hour)
+			}),
+		}
+		impact := schedule.TemplateScheduleImpact(now, agplschedule.TemplateScheduleOptions{}, agplschedule.TemplateScheduleOptions{
+			TimeTilDormant: 7 * 24 * time.Hour,
+			UpdateWorkspaceLastUsedAt: func(_ context.Context, _ database.Store, _ uuid.UUID, _ time.Time) error {
+				return nil
+			},
+		}, workspaces)
+
+		// Resetting the last used time gives the workspace a full period
+		// before it becomes dormant.
+		require.Len(t, impact.Workspaces, 1)
+		require.False(t, impact.Workspaces[0].Immediate)
+		require.Equal(t, now.Add(7*24*time.Hour), *impact.Workspaces[0].At)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+
+		dormantAt := now.Add(-10 * 24 * time.Hour)
+		workspaces := []database.GetWorkspacesRow{
+			workspace("dormant", func(ws *database.GetWorkspacesRow) {
+				ws.DormantAt = sql.NullTime{Valid: true, Time: dormantAt}
+				ws.DeletingAt = sql.NullTime{Valid: true, Time: dormantAt.Add(30 * 24 * time.Hour)}
+			}),
+		}
+		current := agplschedule.TemplateScheduleOptions{TimeTilDormantAutoDelete: 30 * 24 * time.Hour}
+
+		impact := schedule.TemplateScheduleImpact(now, current, agplschedule.TemplateScheduleOptions{
+			TimeTilDormantAutoDelete: 7 * 24 * time.Hour,
+		}, workspaces)
+		require.Len(t, impact.Workspaces, 1)
+		require.Equal(t, codersdk.TemplateScheduleImpactActionDelete, impact.Workspaces[0].Action)
+		require.True(t, impact.Workspaces[0].Immediate)
+
+		// Disabling the auto-delete means the workspace is never deleted.
+		impact = schedule.TemplateScheduleImpact(now, current, agplschedule.TemplateScheduleOptions{}, workspaces)
+		require.Len(t, impact.Workspaces, 1)
+		require.Nil(t, impact.Workspaces[0].At)
+		require.False(t, impact.Workspaces[0].Immediate)
+
+		// Unchanged settings have no impact.
+		impact = schedule.TemplateScheduleImpact(now, current, current, workspaces)
+		require.Empty(t, impact.Workspaces)
+		require.Empty(t, impact.Summary)
+	})
+
+	t.Run("FailedStop", func(t *testing.T) {
+		t.Parallel()
+
+		workspaces := []database.GetWorkspacesRow{
+			workspace("failed", func(ws *database.GetWorkspacesRow) {
+				ws.LatestBuildStatus = database.ProvisionerJobStatusFailed
+				ws.LatestBuildCompletedAt = sql.NullTime{Valid: true, Time: now.Add(-2 * time.Hour)}
+			}),
+			workspace("succeeded", nil),
+		}
+		impact := schedule.TemplateScheduleImpact(now, agplschedule.TemplateScheduleOptions{}, agplschedule.TemplateScheduleOptions{
+			FailureTTL: time.Hour,
+		}, workspaces)
+		require.Equal(t, codersdk.TemplateScheduleImpactSummary{
+			Action:    codersdk.TemplateScheduleImpactActionFailedStop,
+			Count:     1,
+			Immediate: 1,
+		}, summary(impact, codersdk.TemplateScheduleImpactActionFailedStop))
+	})
+
+	t.Run("AutostopTTL", func(t *testing.T) {
+		t.Parallel()
+
+		workspaces := []database.GetWorkspacesRow{
+			workspace("custom", func(ws *database.GetWorkspacesRow) {
+				ws.Ttl = sql.NullInt64{Valid: true, Int64: int64(4 * time.Hour)}
+			}),
+			workspace("default", func(ws *database.GetWorkspacesRow) {
+				ws.Ttl = sql.NullInt64{Valid: true, Int64: int64(8 * time.Hour)}
+			}),
+		}
+		current := agplschedule.TemplateScheduleOptions{UserAutostopEnabled: true, DefaultTTL: 8 * time.Hour}
+
+		impact := schedule.TemplateScheduleImpact(now, current, agplschedule.TemplateScheduleOptions{
+			UserAutostopEnabled: false,
+			DefaultTTL:          8 * time.Hour,
+		}, workspaces)
+		require.Len(t, impact.Workspaces, 1)
+		require.Equal(t, "custom", impact.Workspaces[0].WorkspaceName)
+		require.Equal(t, codersdk.TemplateScheduleImpactActionAutostopTTL, impact.Workspaces[0].Action)
+
+		// The TTL of workspaces is kept while users can change it.
+		impact = schedule.TemplateScheduleImpact(now, current, agplschedule.TemplateScheduleOptions{
+			UserAutostopEnabled: true,
+			DefaultTTL:          2 * time.Hour,
+		}, workspaces)
+		require.Empty(t, impact.Workspaces)
+	})
+}
@@ -0,0 +1,107 @@
+package scim
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+var groupNameReplace = regexp.MustCompile("[^a-zA-Z0-9]+")
+
+// GroupName returns the Coder group name for the display name of a SCIM
+// group. Display names that are not valid group names have every run of
+// invalid characters replaced with a hyphen, so "Platform Eng" becomes
+// "Platform-Eng".
+func GroupName(displayName string) (string, error) {
+	if codersdk.GroupNameValid(displayName) == nil {
+		return displayName, nil
+	}
+	name := strings.Trim(groupNameReplace.ReplaceAllString(displayName, "-"), "-")
+	if err := codersdk.GroupNameValid(name); err != nil {
+		return "", xerrors.Errorf("invalid group name %q: %w", displayName, err)
+	}
+	return name, nil
+}
+
+// GroupMembership is the membership an identity provider declared for the
+// SCIM groups of an organization.
+type GroupMembership struct {
+	// Users maps a group to the users assigned to it directly.
+	Users map[uuid.UUID][]uuid.UUID
+	// Groups maps a group to the groups assigned to it as members.
+	Groups map[uuid.UUID][]uuid.UUID
+}
+
+func NewGroupMembership() GroupMembership {
+	return GroupMembership{
+		Users:  make(map[uuid.UUID][]uuid.UUID),
+		Groups: make(map[uuid.UUID][]uuid.UUID),
+	}
+}
+
+// Members returns the users that should belong to the group. When flatten is
+// set, the users of nested groups are included at any depth. Cycles are
+// tolerated since identity providers do not always prevent them.
+func (m GroupMembership) Members(groupID uuid.UUID, flatten bool) []uuid.UUID {
+	if !flatten {
+		return unique(m.Users[groupID])
+	}
+
+	var members []uuid.UUID
+	visited := map[uuid.UUID]struct{}{}
+	queue := []uuid.UUID{groupID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[id]; ok {
+			continue
+		}
+		visited[id] = struct{}{}
+		members = append(members, m.Users[id]...)
+		queue = append(queue, m.Groups[id]...)
+	}
+	return unique(members)
+}
+
+// Ancestors returns every group that contains the group, directly or through
+// other nested groups. The group itself is only included if it is part of a
+// cycle.
+func (m GroupMembership) Ancestors(groupID uuid.UUID) []uuid.UUID {
+	parents := make(map[uuid.UUID][]uuid.UUID)
+	for parent, children := range m.Groups {
+		for _, child := range children {
+			parents[child] = append(parents[child], parent)
+		}
+	}
+
+	var ancestors []uuid.UUID
+	visited := map[uuid.UUID]struct{}{}
+	queue := slices.Clone(parents[groupID])
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[id]; ok {
+			continue
+		}
+		visited[id] = struct{}{}
+		ancestors = append(ancestors, id)
+		queue = append(queue, parents[id]...)
+	}
+	slices.SortFunc(ancestors, func(a, b uuid.UUID) int {
+		return slices.Compare(a[:], b[:])
+	})
+	return ancestors
+}
+
+func unique(ids []uuid.UUID) []uuid.UUID {
+	ids = slices.Clone(ids)
+	slices.SortFunc(ids, func(a, b uuid.UUID) int {
+		return slices.Compare(a[:], b[:])
+	})
+	return slices.Compact(ids)
+}
@@ -0,0 +1,90 @@
+package scim_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/enterprise/coderd/scim"
+)
+
+func TestGroupMembership(t *testing.T) {
+	t.Parallel()
+
+	var (
+		parent = uuid.New()
+		child  = uuid.New()
+		leaf   = uuid.New()
+		alice  = uuid.New()
+		bob    = uuid.New()
+		carol  = uuid.New()
+	)
+	membership := scim.NewGroupMembership()
+	membership.Users[parent] = []uuid.UUID{alice}
+	membership.Users[child] = []uuid.UUID{bob, alice}
+	membership.Users[leaf] = []uuid.UUID{carol}
+	membership.Groups[parent] = []uuid.UUID{child}
+	membership.Groups[child] = []uuid.UUID{leaf}
+
+	sorted := func(ids ...uuid.UUID) []uuid.UUID {
+		slices.SortFunc(ids, func(a, b uuid.UUID) int {
+			return slices.Compare(a[:], b[:])
+		})
+		return ids
+	}
+
+	t.Run("Direct", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, sorted(alice), membership.Members(parent, false))
+		require.Equal(t, sorted(alice, bob), membership.Members(child, false))
+	})
+
+	t.Run("Flatten", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, sorted(alice, bob, carol), membership.Members(parent, true))
+		require.Equal(t, sorted(alice, bob, carol), membership.Members(child, true))
+		require.Equal(t, sorted(carol), membership.Members(leaf, true))
+	})
+
+	t.Run("Ancestors", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, sorted(child, parent), membership.Ancestors(leaf))
+		require.Empty(t, membership.Ancestors(parent))
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		t.Parallel()
+		cycle := scim.NewGroupMembership()
+		cycle.Users[parent] = []uuid.UUID{alice}
+		cycle.Users[child] = []uuid.UUID{bob}
+		cycle.Groups[parent] = []uuid.UUID{child}
+		cycle.Groups[child] = []uuid.UUID{parent}
+		require.Equal(t, sorted(alice, bob), cycle.Members(parent, true))
+		require.Equal(t, sorted(child, parent), cycle.Ancestors(parent))
+	})
+}
+
+func TestGroupName(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		DisplayName string
+		Name        string
+		Error       bool
+	}{
+		{DisplayName: "engineering", Name: "engineering"},
+		{DisplayName: "Platform Eng", Name: "Platform-Eng"},
+		{DisplayName: " R&D / Ops ", Name: "R-D-Ops"},
+		{DisplayName: "!!!", Error: true},
+	} {
+		name, err := scim.GroupName(tc.DisplayName)
+		if tc.Error {
+			require.Error(t, err, tc.DisplayName)
+			continue
+		}
+		require.NoError(t, err, tc.DisplayName)
+		require.Equal(t, tc.Name, name)
+	}
+}
@@ -864,6 +864,180 @@ func TestScim(t *testing.T) {
 	})
 }
 
+//nolint:gocritic // SCIM authenticates via a special header and bypasses internal RBAC.
+func TestScimGroups(t *testing.T) {
+	t.Parallel()
+
+	scimAPIKey := []byte("hi")
+	setup := func(t *testing.T, mutate func(dv *codersdk.DeploymentValues)) (*codersdk.Client, codersdk.CreateFirstUserResponse) {
+		t.Helper()
+		dv := coderdtest.DeploymentValues(t)
+		if mutate != nil {
+			mutate(dv)
+		}
+		return coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				DeploymentValues: dv,
+			},
+			SCIMAPIKey: scimAPIKey,
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureSCIM:         1,
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+	}
+	// scimRequest sends a SCIM request and decodes the response into res.
+	scimRequest := func(ctx context.Context, t *testing.T, client *codersdk.Client, method, path string, body, res any) int {
+		t.Helper()
+		resp, err := client.Request(ctx, method, path, body, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if res != nil && resp.StatusCode < http.StatusMultipleChoices {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(res))
+		}
+		return resp.StatusCode
+	}
+	createUser := func(ctx context.Context, t *testing.T, client *codersdk.Client) string {
+		t.Helper()
+		var sUser coderd.SCIMUser
+		status := scimRequest(ctx, t, client, http.MethodPost, "/scim/v2/Users", makeScimUser(t), &sUser)
+		require.Equal(t, http.StatusOK, status)
+		return sUser.ID
+	}
+	groupMembers := func(ctx context.Context, t *testing.T, client *codersdk.Client, orgID uuid.UUID, name string) []string {
+		t.Helper()
+		group, err := client.GroupByOrgAndName(ctx, orgID, name)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.GroupSourceSCIM, group.Source)
+		members := []string{}
+		for _, member := range group.Members {
+			members = append(members, member.ID.String())
+		}
+		return members
+	}
+
+	t.Run("CreatePatchDelete", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, first := setup(t, nil)
+		alice := createUser(ctx, t, client)
+		bob := createUser(ctx, t, client)
+
+		var group coderd.SCIMGroup
+		status := scimRequest(ctx, t, client, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "Platform Eng",
+			Members:     []coderd.SCIMGroupMember{{Value: alice}},
+		}, &group)
+		require.Equal(t, http.StatusCreated, status)
+		require.Equal(t, "Platform Eng", group.DisplayName)
+		require.ElementsMatch(t, []string{alice}, groupMembers(ctx, t, client, first.OrganizationID, "Platform-Eng"))
+
+		var list coderd.SCIMGroupListResponse
+		status = scimRequest(ctx, t, client, http.MethodGet, `/scim/v2/Groups?filter=displayName+eq+"Platform+Eng"`, nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, list.Resources, 1)
+		require.Equal(t, group.ID, list.Resources[0].ID)
+
+		status = scimRequest(ctx, t, client, http.MethodPatch, "/scim/v2/Groups/"+group.ID, coderd.SCIMPatchGroupRequest{
+			Operations: []coderd.SCIMPatchOperation{
+				{Op: "add", Path: "members", Value: json.RawMessage(fmt.Sprintf(`[{"value":%q}]`, bob))},
+				{Op: "remove", Path: fmt.Sprintf(`members[value eq %q]`, alice)},
+				{Op: "replace", Path: "displayName", Value: json.RawMessage(`"Platform"`)},
+			},
+		}, &group)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "Platform", group.DisplayName)
+		require.ElementsMatch(t, []string{bob}, groupMembers(ctx, t, client, first.OrganizationID, "Platform"))
+
+		status = scimRequest(ctx, t, client, http.MethodDelete, "/scim/v2/Groups/"+group.ID, nil, nil)
+		require.Equal(t, http.StatusNoContent, status)
+		status = scimRequest(ctx, t, client, http.MethodGet, "/scim/v2/Groups/"+group.ID, nil, nil)
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("UnknownMember", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, _ := setup(t, nil)
+
+		status := scimRequest(ctx, t, client, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "ops",
+			Members:     []coderd.SCIMGroupMember{{Value: uuid.NewString()}},
+		}, nil)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("FlattenNested", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, first := setup(t, func(dv *codersdk.DeploymentValues) {
+			dv.SCIMGroupsFlattenNested = true
+		})
+		alice := createUser(ctx, t, client)
+		bob := createUser(ctx, t, client)
+
+		var child coderd.SCIMGroup
+		status := scimRequest(ctx, t, client, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "child",
+			Members:     []coderd.SCIMGroupMember{{Value: bob}},
+		}, &child)
+		require.Equal(t, http.StatusCreated, status)
+		var parent coderd.SCIMGroup
+		status = scimRequest(ctx, t, client, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "parent",
+			Members: []coderd.SCIMGroupMember{
+				{Value: alice},
+				{Value: child.ID, Type: "Group"},
+			},
+		}, &parent)
+		require.Equal(t, http.StatusCreated, status)
+		require.ElementsMatch(t, []string{alice, bob}, groupMembers(ctx, t, client, first.OrganizationID, "parent"))
+
+		// Members added to the nested group are added to the parent.
+		carol := createUser(ctx, t, client)
+		status = scimRequest(ctx, t, client, http.MethodPatch, "/scim/v2/Groups/"+child.ID, coderd.SCIMPatchGroupRequest{
+			Operations: []coderd.SCIMPatchOperation{
+				{Op: "add", Path: "members", Value: json.RawMessage(fmt.Sprintf(`[{"value":%q}]`, carol))},
+			},
+		}, nil)
+		require.Equal(t, http.StatusOK, status)
+		require.ElementsMatch(t, []string{alice, bob, carol}, groupMembers(ctx, t, client, first.OrganizationID, "parent"))
+
+		// Deleting the nested group removes its members from the parent.
+		status = scimRequest(ctx, t, client, http.MethodDelete, "/scim/v2/Groups/"+child.ID, nil, nil)
+		require.Equal(t, http.StatusNoContent, status)
+		require.ElementsMatch(t, []string{alice}, groupMembers(ctx, t, client, first.OrganizationID, "parent"))
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, first := setup(t, func(dv *codersdk.DeploymentValues) {
+			dv.SCIMGroupsDryRun = true
+		})
+		alice := createUser(ctx, t, client)
+
+		var group coderd.SCIMGroup
+		status := scimRequest(ctx, t, client, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "dry",
+			Members:     []coderd.SCIMGroupMember{{Value: alice}},
+		}, &group)
+		require.Equal(t, http.StatusCreated, status)
+		require.Len(t, group.Members, 1)
+
+		_, err := client.GroupByOrgAndName(ctx, first.OrganizationID, "dry")
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
+
 func TestScimError(t *testing.T) {
 	t.Parallel()
 
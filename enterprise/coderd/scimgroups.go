@@ -0,0 +1,889 @@
+package coderd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/imulab/go-scim/pkg/v2/handlerutil"
+	"github.com/imulab/go-scim/pkg/v2/spec"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/enterprise/coderd/scim"
+)
+
+const (
+	scimGroupSchema        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimListResponseSchema = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+
+	scimMemberTypeUser  = "User"
+	scimMemberTypeGroup = "Group"
+)
+
+var (
+	// errSCIMGroupsDryRun rolls back the transaction of a SCIM group request
+	// when dry-run is enabled, after every change has been logged.
+	errSCIMGroupsDryRun = xerrors.New("scim groups dry run")
+
+	scimDisplayNameFilter = regexp.MustCompile(`(?i)^displayName eq "(.*)"$`)
+	scimMemberValuePath   = regexp.MustCompile(`(?i)^members\[value eq "(.*)"\]$`)
+)
+
+// SCIMGroup is a SCIM group resource. Like SCIMUser, only the fields Coder
+// uses are included. Groups are created in the default organization.
+type SCIMGroup struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	DisplayName string            `json:"displayName"`
+	Members     []SCIMGroupMember `json:"members"`
+	Meta        struct {
+		ResourceType string `json:"resourceType"`
+	} `json:"meta"`
+}
+
+type SCIMGroupMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	// Type is either "User" or "Group". When it is empty, the member is a
+	// group if the value is the ID of a SCIM group, and a user otherwise.
+	Type string `json:"type,omitempty"`
+}
+
+type SCIMGroupListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    []SCIMGroup `json:"Resources"`
+}
+
+type SCIMPatchGroupRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations"`
+}
+
+type SCIMPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty" swaggertype:"object"`
+}
+
+// scimGetGroups returns the SCIM groups of the default organization. Only the
+// `displayName eq "..."` filter is supported, which is what identity providers
+// use to match existing groups.
+//
+// @Summary SCIM 2.0: Get groups
+// @ID scim-get-groups
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param filter query string false "Filter, only displayName eq is supported"
+// @Success 200 {object} coderd.SCIMGroupListResponse
+// @Router /scim/v2/Groups [get]
+func (api *API) scimGetGroups(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	var displayName *string
+	if filter := r.URL.Query().Get("filter"); filter != "" {
+		match := scimDisplayNameFilter.FindStringSubmatch(strings.TrimSpace(filter))
+		if match == nil {
+			_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidFilter.Type, xerrors.Errorf("unsupported filter %q", filter)))
+			return
+		}
+		displayName = &match[1]
+	}
+	excludeMembers := strings.Contains(strings.ToLower(r.URL.Query().Get("excludedAttributes")), "members")
+
+	//nolint:gocritic // SCIM operations are a system user
+	state, err := api.loadSCIMGroupState(dbauthz.AsSystemRestricted(ctx), api.Database)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+
+	resources := []SCIMGroup{}
+	for _, group := range state.sortedGroups() {
+		if displayName != nil && group.DisplayName != *displayName {
+			continue
+		}
+		resource := state.resource(group)
+		if excludeMembers {
+			resource.Members = nil
+		}
+		resources = append(resources, resource)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, SCIMGroupListResponse{
+		Schemas:      []string{scimListResponseSchema},
+		TotalResults: len(resources),
+		StartIndex:   1,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// @Summary SCIM 2.0: Get group by ID
+// @ID scim-get-group-by-id
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 200 {object} coderd.SCIMGroup
+// @Failure 404
+// @Router /scim/v2/Groups/{id} [get]
+func (api *API) scimGetGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	//nolint:gocritic // SCIM operations are a system user
+	state, err := api.loadSCIMGroupState(dbauthz.AsSystemRestricted(ctx), api.Database)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+	group, err := state.group(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, state.resource(group))
+}
+
+// scimPostGroup creates a group in the default organization with the members
+// the identity provider assigned to it.
+//
+// @Summary SCIM 2.0: Create new group
+// @ID scim-create-new-group
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param request body coderd.SCIMGroup true "New group"
+// @Success 201 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups [post]
+func (api *API) scimPostGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidRequest", err))
+		return
+	}
+
+	//nolint:gocritic // SCIM operations are a system user
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	org, err := api.Database.GetDefaultOrganization(sysCtx)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+
+	auditor := *api.AGPL.Auditor.Load()
+	aReq, commitAudit := audit.InitRequestWithCancel[database.AuditableGroup](rw, &audit.RequestParams{
+		Audit:            auditor,
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionCreate,
+		OrganizationID:   org.ID,
+		AdditionalFields: SCIMAuditAdditionalFields,
+	})
+	defer commitAudit(!api.DeploymentValues.SCIMGroupsDryRun.Value())
+
+	var resource SCIMGroup
+	err = api.scimGroupsTx(sysCtx, func(state *scimGroupState) error {
+		group, err := state.create(sysCtx, sGroup.DisplayName)
+		if err != nil {
+			return err
+		}
+		users, groups, err := state.resolveMembers(sysCtx, group.ID, sGroup.Members)
+		if err != nil {
+			return err
+		}
+		err = state.setMembers(sysCtx, group, users, groups)
+		if err != nil {
+			return err
+		}
+		members, err := state.tx.GetGroupMembersByGroupID(sysCtx, database.GetGroupMembersByGroupIDParams{
+			GroupID:       group.ID,
+			IncludeSystem: false,
+		})
+		if err != nil {
+			return xerrors.Errorf("get group members: %w", err)
+		}
+		aReq.New = group.Auditable(members)
+		resource = state.resource(group)
+		return nil
+	})
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, resource)
+}
+
+// scimPutGroup replaces the display name and members of a group.
+//
+// @Summary SCIM 2.0: Replace group
+// @ID scim-replace-group
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMGroup true "Replace group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [put]
+func (api *API) scimPutGroup(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidRequest", err))
+		return
+	}
+
+	api.scimUpdateGroup(rw, r, func(SCIMGroup) (SCIMGroup, error) {
+		return sGroup, nil
+	})
+}
+
+// scimPatchGroup supports changing the display name and adding, removing,
+// and replacing members.
+//
+// @Summary SCIM 2.0: Update group
+// @ID scim-update-group
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMPatchGroupRequest true "Update group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [patch]
+func (api *API) scimPatchGroup(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	var patch SCIMPatchGroupRequest
+	err := json.NewDecoder(r.Body).Decode(&patch)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidRequest", err))
+		return
+	}
+
+	api.scimUpdateGroup(rw, r, func(current SCIMGroup) (SCIMGroup, error) {
+		return applySCIMGroupPatch(current, patch.Operations)
+	})
+}
+
+// scimUpdateGroup applies the display name and members returned by update to
+// the group in the URL.
+func (api *API) scimUpdateGroup(rw http.ResponseWriter, r *http.Request, update func(current SCIMGroup) (SCIMGroup, error)) {
+	ctx := r.Context()
+
+	//nolint:gocritic // SCIM operations are a system user
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	org, err := api.Database.GetDefaultOrganization(sysCtx)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+
+	auditor := *api.AGPL.Auditor.Load()
+	aReq, commitAudit := audit.InitRequestWithCancel[database.AuditableGroup](rw, &audit.RequestParams{
+		Audit:            auditor,
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionWrite,
+		OrganizationID:   org.ID,
+		AdditionalFields: SCIMAuditAdditionalFields,
+	})
+	defer commitAudit(!api.DeploymentValues.SCIMGroupsDryRun.Value())
+
+	var resource SCIMGroup
+	err = api.scimGroupsTx(sysCtx, func(state *scimGroupState) error {
+		group, err := state.group(chi.URLParam(r, "id"))
+		if err != nil {
+			return err
+		}
+		oldMembers, err := state.tx.GetGroupMembersByGroupID(sysCtx, database.GetGroupMembersByGroupIDParams{
+			GroupID:       group.ID,
+			IncludeSystem: false,
+		})
+		if err != nil {
+			return xerrors.Errorf("get group members: %w", err)
+		}
+		aReq.Old = group.Auditable(oldMembers)
+
+		updated, err := update(state.resource(group))
+		if err != nil {
+			return err
+		}
+		if updated.DisplayName != "" && updated.DisplayName != group.DisplayName {
+			group, err = state.rename(sysCtx, group, updated.DisplayName)
+			if err != nil {
+				return err
+			}
+		}
+		users, groups, err := state.resolveMembers(sysCtx, group.ID, updated.Members)
+		if err != nil {
+			return err
+		}
+		err = state.setMembers(sysCtx, group, users, groups)
+		if err != nil {
+			return err
+		}
+
+		newMembers, err := state.tx.GetGroupMembersByGroupID(sysCtx, database.GetGroupMembersByGroupIDParams{
+			GroupID:       group.ID,
+			IncludeSystem: false,
+		})
+		if err != nil {
+			return xerrors.Errorf("get group members: %w", err)
+		}
+		aReq.New = group.Auditable(newMembers)
+		resource = state.resource(group)
+		return nil
+	})
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resource)
+}
+
+// scimDeleteGroup deletes a group. Groups that contained it lose its members
+// when nested groups are flattened.
+//
+// @Summary SCIM 2.0: Delete group
+// @ID scim-delete-group
+// @Security Authorization
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 204
+// @Router /scim/v2/Groups/{id} [delete]
+func (api *API) scimDeleteGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	//nolint:gocritic // SCIM operations are a system user
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	org, err := api.Database.GetDefaultOrganization(sysCtx)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err) // internal error
+		return
+	}
+
+	auditor := *api.AGPL.Auditor.Load()
+	aReq, commitAudit := audit.InitRequestWithCancel[database.AuditableGroup](rw, &audit.RequestParams{
+		Audit:            auditor,
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionDelete,
+		OrganizationID:   org.ID,
+		AdditionalFields: SCIMAuditAdditionalFields,
+	})
+	defer commitAudit(!api.DeploymentValues.SCIMGroupsDryRun.Value())
+
+	err = api.scimGroupsTx(sysCtx, func(state *scimGroupState) error {
+		group, err := state.group(chi.URLParam(r, "id"))
+		if err != nil {
+			return err
+		}
+		members, err := state.tx.GetGroupMembersByGroupID(sysCtx, database.GetGroupMembersByGroupIDParams{
+			GroupID:       group.ID,
+			IncludeSystem: false,
+		})
+		if err != nil {
+			return xerrors.Errorf("get group members: %w", err)
+		}
+		aReq.Old = group.Auditable(members)
+		return state.delete(sysCtx, group)
+	})
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// scimGroupsTx runs fn in a transaction with the SCIM groups of the default
+// organization loaded. With dry-run enabled the transaction is always rolled
+// back, so the changes fn makes are only logged.
+func (api *API) scimGroupsTx(ctx context.Context, fn func(state *scimGroupState) error) error {
+	dryRun := api.DeploymentValues.SCIMGroupsDryRun.Value()
+	err := api.Database.InTx(func(tx database.Store) error {
+		state, err := api.loadSCIMGroupState(ctx, tx)
+		if err != nil {
+			return err
+		}
+		state.logger = state.logger.With(slog.F("dry_run", dryRun))
+		err = fn(state)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			return errSCIMGroupsDryRun
+		}
+		return nil
+	}, nil)
+	if xerrors.Is(err, errSCIMGroupsDryRun) {
+		return nil
+	}
+	// The transaction wraps errors, but handlerutil.WriteError only
+	// recognizes a *scim.HTTPError at the top level.
+	var httpErr *scim.HTTPError
+	if xerrors.As(err, &httpErr) {
+		return httpErr
+	}
+	return err
+}
+
+// scimGroupState is the SCIM groups of the default organization and the
+// membership the identity provider declared for them.
+type scimGroupState struct {
+	tx         database.Store
+	logger     slog.Logger
+	flatten    bool
+	groups     map[uuid.UUID]database.Group
+	membership scim.GroupMembership
+	org        database.Organization
+}
+
+func (api *API) loadSCIMGroupState(ctx context.Context, db database.Store) (*scimGroupState, error) {
+	org, err := db.GetDefaultOrganization(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("get default organization: %w", err)
+	}
+	rows, err := db.GetGroups(ctx, database.GetGroupsParams{
+		OrganizationID: org.ID,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get groups: %w", err)
+	}
+	state := &scimGroupState{
+		tx:         db,
+		logger:     api.Logger.Named("scim_groups"),
+		flatten:    api.DeploymentValues.SCIMGroupsFlattenNested.Value(),
+		groups:     make(map[uuid.UUID]database.Group),
+		membership: scim.NewGroupMembership(),
+		org:        org,
+	}
+	for _, row := range rows {
+		if row.Group.Source == database.GroupSourceScim {
+			state.groups[row.Group.ID] = row.Group
+		}
+	}
+
+	users, err := db.GetSCIMGroupMembers(ctx, org.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("get scim group members: %w", err)
+	}
+	for _, member := range users {
+		state.membership.Users[member.GroupID] = append(state.membership.Users[member.GroupID], member.UserID)
+	}
+	nested, err := db.GetSCIMNestedGroups(ctx, org.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("get scim nested groups: %w", err)
+	}
+	for _, member := range nested {
+		state.membership.Groups[member.GroupID] = append(state.membership.Groups[member.GroupID], member.MemberGroupID)
+	}
+	return state, nil
+}
+
+func (s *scimGroupState) sortedGroups() []database.Group {
+	groups := make([]database.Group, 0, len(s.groups))
+	for _, group := range s.groups {
+		groups = append(groups, group)
+	}
+	slices.SortFunc(groups, func(a, b database.Group) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return groups
+}
+
+// group returns the SCIM group with the ID. Groups that were not created
+// through SCIM are not found, so they can't be changed by the identity
+// provider.
+func (s *scimGroupState) group(id string) (database.Group, error) {
+	groupID, err := uuid.Parse(id)
+	if err != nil {
+		return database.Group{}, scim.NewHTTPError(http.StatusBadRequest, "invalidId", xerrors.Errorf("id must be a uuid: %w", err))
+	}
+	group, ok := s.groups[groupID]
+	if !ok {
+		return database.Group{}, scim.NewHTTPError(http.StatusNotFound, spec.ErrNotFound.Type, xerrors.Errorf("group %q not found", id))
+	}
+	return group, nil
+}
+
+// resource returns the group as the identity provider declared it.
+func (s *scimGroupState) resource(group database.Group) SCIMGroup {
+	resource := SCIMGroup{
+		Schemas:     []string{scimGroupSchema},
+		ID:          group.ID.String(),
+		DisplayName: group.DisplayName,
+		Members:     []SCIMGroupMember{},
+	}
+	resource.Meta.ResourceType = "Group"
+	for _, userID := range s.membership.Members(group.ID, false) {
+		resource.Members = append(resource.Members, SCIMGroupMember{
+			Value: userID.String(),
+			Type:  scimMemberTypeUser,
+		})
+	}
+	for _, groupID := range s.membership.Groups[group.ID] {
+		resource.Members = append(resource.Members, SCIMGroupMember{
+			Value:   groupID.String(),
+			Display: s.groups[groupID].DisplayName,
+			Type:    scimMemberTypeGroup,
+		})
+	}
+	return resource
+}
+
+// resolveMembers splits the members of a group into users and nested groups.
+// Every member must be an existing user or SCIM group.
+func (s *scimGroupState) resolveMembers(ctx context.Context, groupID uuid.UUID, members []SCIMGroupMember) (users []uuid.UUID, groups []uuid.UUID, err error) {
+	for _, member := range members {
+		id, err := uuid.Parse(member.Value)
+		if err != nil {
+			return nil, nil, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, xerrors.Errorf("member %q must be a uuid: %w", member.Value, err))
+		}
+		_, isGroup := s.groups[id]
+		switch {
+		case strings.EqualFold(member.Type, scimMemberTypeGroup) || (member.Type == "" && isGroup):
+			if !isGroup {
+				return nil, nil, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, xerrors.Errorf("member group %q not found", member.Value))
+			}
+			if id == groupID {
+				return nil, nil, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, xerrors.New("a group cannot be a member of itself"))
+			}
+			groups = append(groups, id)
+		case strings.EqualFold(member.Type, scimMemberTypeUser) || member.Type == "":
+			users = append(users, id)
+		default:
+			return nil, nil, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, xerrors.Errorf("unsupported member type %q", member.Type))
+		}
+	}
+
+	users = slice.Unique(users)
+	if len(users) > 0 {
+		found, err := s.tx.GetUsersByIDs(ctx, users)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("get users: %w", err)
+		}
+		foundIDs := make([]uuid.UUID, 0, len(found))
+		for _, user := range found {
+			if !user.Deleted {
+				foundIDs = append(foundIDs, user.ID)
+			}
+		}
+		if _, missing := slice.SymmetricDifference(foundIDs, users); len(missing) > 0 {
+			return nil, nil, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, xerrors.Errorf("member users not found: %v", missing))
+		}
+	}
+	return users, slice.Unique(groups), nil
+}
+
+func (s *scimGroupState) create(ctx context.Context, displayName string) (database.Group, error) {
+	name, err := scim.GroupName(displayName)
+	if err != nil {
+		return database.Group{}, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, err)
+	}
+	s.logger.Info(ctx, "creating scim group", slog.F("group", name))
+	inserted, err := s.tx.InsertMissingGroups(ctx, database.InsertMissingGroupsParams{
+		OrganizationID: s.org.ID,
+		Source:         database.GroupSourceScim,
+		GroupNames:     []string{name},
+	})
+	if err != nil {
+		return database.Group{}, xerrors.Errorf("insert group: %w", err)
+	}
+	if len(inserted) == 0 {
+		return database.Group{}, scim.NewHTTPError(http.StatusConflict, spec.ErrUniqueness.Type, xerrors.Errorf("a group named %q already exists", name))
+	}
+	group, err := s.tx.UpdateGroupByID(ctx, database.UpdateGroupByIDParams{
+		ID:             inserted[0].ID,
+		Name:           inserted[0].Name,
+		DisplayName:    displayName,
+		AvatarURL:      inserted[0].AvatarURL,
+		QuotaAllowance: inserted[0].QuotaAllowance,
+	})
+	if err != nil {
+		return database.Group{}, xerrors.Errorf("set group display name: %w", err)
+	}
+	s.groups[group.ID] = group
+	return group, nil
+}
+
+func (s *scimGroupState) rename(ctx context.Context, group database.Group, displayName string) (database.Group, error) {
+	name, err := scim.GroupName(displayName)
+	if err != nil {
+		return database.Group{}, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, err)
+	}
+	s.logger.Info(ctx, "renaming scim group", slog.F("group", group.Name), slog.F("new_name", name))
+	renamed, err := s.tx.UpdateGroupByID(ctx, database.UpdateGroupByIDParams{
+		ID:             group.ID,
+		Name:           name,
+		DisplayName:    displayName,
+		AvatarURL:      group.AvatarURL,
+		QuotaAllowance: group.QuotaAllowance,
+	})
+	if database.IsUniqueViolation(err) {
+		return database.Group{}, scim.NewHTTPError(http.StatusConflict, spec.ErrUniqueness.Type, xerrors.Errorf("a group named %q already exists", name))
+	}
+	if err != nil {
+		return database.Group{}, xerrors.Errorf("update group: %w", err)
+	}
+	s.groups[renamed.ID] = renamed
+	return renamed, nil
+}
+
+// setMembers stores the members the identity provider declared for the group
+// and updates the members of every group affected by the change.
+func (s *scimGroupState) setMembers(ctx context.Context, group database.Group, users []uuid.UUID, groups []uuid.UUID) error {
+	logger := s.logger.With(slog.F("group", group.Name))
+
+	addUsers, removeUsers := slice.SymmetricDifference(s.membership.Users[group.ID], users)
+	if len(addUsers) > 0 {
+		err := s.tx.InsertSCIMGroupMembers(ctx, database.InsertSCIMGroupMembersParams{
+			GroupID: group.ID,
+			UserIds: addUsers,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert scim group members: %w", err)
+		}
+	}
+	if len(removeUsers) > 0 {
+		err := s.tx.DeleteSCIMGroupMembers(ctx, database.DeleteSCIMGroupMembersParams{
+			GroupID: group.ID,
+			UserIds: removeUsers,
+		})
+		if err != nil {
+			return xerrors.Errorf("delete scim group members: %w", err)
+		}
+	}
+	s.membership.Users[group.ID] = users
+
+	addGroups, removeGroups := slice.SymmetricDifference(s.membership.Groups[group.ID], groups)
+	for _, id := range addGroups {
+		logger.Info(ctx, "adding nested scim group", slog.F("member_group", s.groups[id].Name))
+	}
+	for _, id := range removeGroups {
+		logger.Info(ctx, "removing nested scim group", slog.F("member_group", s.groups[id].Name))
+	}
+	if len(addGroups) > 0 {
+		if !s.flatten {
+			logger.Warn(ctx, "nested scim groups are ignored unless --scim-groups-flatten-nested is set")
+		}
+		err := s.tx.InsertSCIMNestedGroups(ctx, database.InsertSCIMNestedGroupsParams{
+			GroupID:        group.ID,
+			MemberGroupIds: addGroups,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert scim nested groups: %w", err)
+		}
+	}
+	if len(removeGroups) > 0 {
+		err := s.tx.DeleteSCIMNestedGroups(ctx, database.DeleteSCIMNestedGroupsParams{
+			GroupID:        group.ID,
+			MemberGroupIds: removeGroups,
+		})
+		if err != nil {
+			return xerrors.Errorf("delete scim nested groups: %w", err)
+		}
+	}
+	s.membership.Groups[group.ID] = groups
+
+	affected := []uuid.UUID{group.ID}
+	if s.flatten {
+		affected = append(affected, s.membership.Ancestors(group.ID)...)
+	}
+	return s.reconcile(ctx, affected)
+}
+
+func (s *scimGroupState) delete(ctx context.Context, group database.Group) error {
+	s.logger.Info(ctx, "deleting scim group", slog.F("group", group.Name))
+	var ancestors []uuid.UUID
+	if s.flatten {
+		ancestors = s.membership.Ancestors(group.ID)
+	}
+
+	err := s.tx.DeleteGroupByID(ctx, group.ID)
+	if err != nil {
+		return xerrors.Errorf("delete group: %w", err)
+	}
+	// The database cascades the delete to the SCIM membership tables.
+	delete(s.groups, group.ID)
+	delete(s.membership.Users, group.ID)
+	delete(s.membership.Groups, group.ID)
+	for parent, children := range s.membership.Groups {
+		s.membership.Groups[parent] = slices.DeleteFunc(children, func(id uuid.UUID) bool {
+			return id == group.ID
+		})
+	}
+
+	ancestors = slices.DeleteFunc(ancestors, func(id uuid.UUID) bool {
+		return id == group.ID
+	})
+	return s.reconcile(ctx, ancestors)
+}
+
+// reconcile makes the members of each group match the membership the
+// identity provider declared.
+func (s *scimGroupState) reconcile(ctx context.Context, groupIDs []uuid.UUID) error {
+	for _, groupID := range groupIDs {
+		group := s.groups[groupID]
+		members, err := s.tx.GetGroupMembersByGroupID(ctx, database.GetGroupMembersByGroupIDParams{
+			GroupID:       groupID,
+			IncludeSystem: false,
+		})
+		if err != nil {
+			return xerrors.Errorf("get group members: %w", err)
+		}
+		current := make([]uuid.UUID, 0, len(members))
+		for _, member := range members {
+			current = append(current, member.UserID)
+		}
+
+		add, remove := slice.SymmetricDifference(current, s.membership.Members(groupID, s.flatten))
+		for _, userID := range add {
+			// Deleted users are hidden from the current members, so they may
+			// already be in the group.
+			inserted, err := s.tx.InsertUserGroupsByID(ctx, database.InsertUserGroupsByIDParams{
+				UserID:   userID,
+				GroupIds: []uuid.UUID{groupID},
+			})
+			if err != nil {
+				return xerrors.Errorf("insert group member: %w", err)
+			}
+			if len(inserted) > 0 {
+				s.logger.Info(ctx, "adding user to scim group", slog.F("group", group.Name), slog.F("user_id", userID))
+			}
+		}
+		for _, userID := range remove {
+			s.logger.Info(ctx, "removing user from scim group", slog.F("group", group.Name), slog.F("user_id", userID))
+			err := s.tx.DeleteGroupMemberFromGroup(ctx, database.DeleteGroupMemberFromGroupParams{
+				UserID:  userID,
+				GroupID: groupID,
+			})
+			if err != nil {
+				return xerrors.Errorf("delete group member: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// applySCIMGroupPatch applies PATCH operations to the display name and
+// members of a group.
+func applySCIMGroupPatch(group SCIMGroup, operations []SCIMPatchOperation) (SCIMGroup, error) {
+	invalid := func(format string, args ...any) error {
+		return scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidValue.Type, xerrors.Errorf(format, args...))
+	}
+	setMembers := func(op string, members []SCIMGroupMember) {
+		switch op {
+		case "replace":
+			group.Members = members
+		case "add":
+			for _, member := range members {
+				if !slices.ContainsFunc(group.Members, func(m SCIMGroupMember) bool { return m.Value == member.Value }) {
+					group.Members = append(group.Members, member)
+				}
+			}
+		case "remove":
+			group.Members = slices.DeleteFunc(group.Members, func(m SCIMGroupMember) bool {
+				return slices.ContainsFunc(members, func(member SCIMGroupMember) bool { return m.Value == member.Value })
+			})
+		}
+	}
+
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		if op != "add" && op != "remove" && op != "replace" {
+			return SCIMGroup{}, invalid("unsupported operation %q", operation.Op)
+		}
+
+		switch path := operation.Path; {
+		case path == "":
+			// Without a path, the value holds the attributes to change.
+			if op == "remove" {
+				return SCIMGroup{}, invalid("remove requires a path")
+			}
+			var value struct {
+				DisplayName *string            `json:"displayName"`
+				Members     *[]SCIMGroupMember `json:"members"`
+			}
+			if err := json.Unmarshal(operation.Value, &value); err != nil {
+				return SCIMGroup{}, invalid("invalid value: %w", err)
+			}
+			if value.DisplayName != nil {
+				group.DisplayName = *value.DisplayName
+			}
+			if value.Members != nil {
+				setMembers(op, *value.Members)
+			}
+		case strings.EqualFold(path, "displayName"):
+			if op == "remove" {
+				return SCIMGroup{}, invalid("displayName is required")
+			}
+			if err := json.Unmarshal(operation.Value, &group.DisplayName); err != nil {
+				return SCIMGroup{}, invalid("invalid displayName: %w", err)
+			}
+		case strings.EqualFold(path, "members"):
+			var members []SCIMGroupMember
+			if len(operation.Value) > 0 {
+				if err := json.Unmarshal(operation.Value, &members); err != nil {
+					return SCIMGroup{}, invalid("invalid members: %w", err)
+				}
+			} else if op == "remove" {
+				// Removing without a value removes every member.
+				members = group.Members
+			}
+			setMembers(op, members)
+		default:
+			match := scimMemberValuePath.FindStringSubmatch(path)
+			if match == nil || op != "remove" {
+				return SCIMGroup{}, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidPath.Type, xerrors.Errorf("unsupported path %q for %s", path, op))
+			}
+			setMembers(op, []SCIMGroupMember{{Value: match[1]}})
+		}
+	}
+	return group, nil
+}
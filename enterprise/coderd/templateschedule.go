@@ -0,0 +1,102 @@
+package coderd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/workspacestats"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/schedule"
+)
+
+// @Summary Preview the impact of a template schedule change
+// @ID preview-the-impact-of-a-template-schedule-change
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.TemplateScheduleImpactRequest true "Proposed template schedule"
+// @Success 200 {object} codersdk.TemplateScheduleImpact
+// @Router /templates/{template}/schedule/impact [post]
+func (api *API) postTemplateScheduleImpact(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	// Previewing a change requires the permission to make it.
+	if !api.Authorize(r, policy.ActionUpdate, template) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.TemplateScheduleImpactRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	// These match the validation of template updates.
+	const minTTL = 1000 * 60
+	var validErrs []codersdk.ValidationError
+	if req.DefaultTTLMillis < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "default_ttl_ms", Detail: "Must be a positive integer."})
+	}
+	if req.FailureTTLMillis < 0 || (req.FailureTTLMillis > 0 && req.FailureTTLMillis < minTTL) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "failure_ttl_ms", Detail: "Value must be at least one minute."})
+	}
+	if req.TimeTilDormantMillis < 0 || (req.TimeTilDormantMillis > 0 && req.TimeTilDormantMillis < minTTL) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "time_til_dormant_ms", Detail: "Value must be at least one minute."})
+	}
+	if req.TimeTilDormantAutoDeleteMillis < 0 || (req.TimeTilDormantAutoDeleteMillis > 0 && req.TimeTilDormantAutoDeleteMillis < minTTL) {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "time_til_dormant_autodelete_ms", Detail: "Value must be at least one minute."})
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to preview the template schedule.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	current, err := (*api.AGPL.TemplateScheduleStore.Load()).Get(ctx, api.Database, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template schedule options.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	proposed := current
+	proposed.DefaultTTL = time.Duration(req.DefaultTTLMillis) * time.Millisecond
+	proposed.UserAutostopEnabled = req.AllowUserAutostop
+	proposed.FailureTTL = time.Duration(req.FailureTTLMillis) * time.Millisecond
+	proposed.TimeTilDormant = time.Duration(req.TimeTilDormantMillis) * time.Millisecond
+	proposed.TimeTilDormantAutoDelete = time.Duration(req.TimeTilDormantAutoDeleteMillis) * time.Millisecond
+	if req.UpdateWorkspaceLastUsedAt {
+		proposed.UpdateWorkspaceLastUsedAt = workspacestats.UpdateTemplateWorkspacesLastUsedAt
+	}
+	proposed.UpdateWorkspaceDormantAt = req.UpdateWorkspaceDormantAt
+
+	// Only the workspaces that the caller can read are previewed, which are
+	// all of them for template admins.
+	workspaces, err := api.Database.GetWorkspaces(ctx, database.GetWorkspacesParams{
+		TemplateIDs: []uuid.UUID{template.ID},
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspaces.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, schedule.TemplateScheduleImpact(dbtime.Now(), current, proposed, workspaces))
+}
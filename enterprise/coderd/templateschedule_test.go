@@ -0,0 +1,88 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateScheduleImpact(t *testing.T) {
+	t.Parallel()
+
+	client, owner := coderdenttest.New(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		},
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureAdvancedTemplateScheduling: 1,
+			},
+		},
+	})
+	templateAdminClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, memberClient, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	t.Run("Dormancy", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		impact, err := templateAdminClient.TemplateScheduleImpact(ctx, template.ID, codersdk.TemplateScheduleImpactRequest{
+			TimeTilDormantMillis: time.Hour.Milliseconds(),
+		})
+		require.NoError(t, err)
+		require.Len(t, impact.Workspaces, 1)
+		require.Equal(t, workspace.ID, impact.Workspaces[0].WorkspaceID)
+		require.Equal(t, codersdk.TemplateScheduleImpactActionDormant, impact.Workspaces[0].Action)
+		require.Nil(t, impact.Workspaces[0].CurrentAt)
+		require.NotNil(t, impact.Workspaces[0].At)
+		require.False(t, impact.Workspaces[0].Immediate)
+		require.Equal(t, []codersdk.TemplateScheduleImpactSummary{{
+			Action: codersdk.TemplateScheduleImpactActionDormant,
+			Count:  1,
+		}}, impact.Summary)
+
+		// The template is not changed by the preview.
+		got, err := client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Zero(t, got.TimeTilDormantMillis)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := templateAdminClient.TemplateScheduleImpact(ctx, template.ID, codersdk.TemplateScheduleImpactRequest{
+			FailureTTLMillis: 10,
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+		require.Len(t, sdkErr.Validations, 1)
+		require.Equal(t, "failure_ttl_ms", sdkErr.Validations[0].Field)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := memberClient.TemplateScheduleImpact(ctx, template.ID, codersdk.TemplateScheduleImpactRequest{})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+	})
+}
@@ -0,0 +1,386 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Transfer template to another organization
+// @ID transfer-template-to-another-organization
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.TransferTemplateRequest true "Transfer template request"
+// @Success 200 {object} codersdk.TransferTemplateResponse
+// @Router /templates/{template}/transfer [post]
+func (api *API) transferTemplate(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		template          = httpmw.TemplateParam(r)
+		auditor           = api.AGPL.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Template](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: template.OrganizationID,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = template
+
+	var req codersdk.TransferTemplateRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.OrganizationID == template.OrganizationID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Template is already in this organization.",
+		})
+		return
+	}
+
+	organization, err := api.Database.GetOrganizationByID(ctx, req.OrganizationID)
+	if httpapi.Is404Error(err) || (err == nil && organization.Deleted) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Organization does not exist.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "organization_id",
+				Detail: fmt.Sprintf("Organization %q does not exist.", req.OrganizationID),
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if !api.Authorize(r, policy.ActionCreate, rbac.ResourceTemplate.InOrg(organization.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	// Validating the transfer requires reading templates, groups, members
+	// and workspaces that the caller might not be able to see, but the
+	// transfer itself is authorized below.
+	// nolint:gocritic
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+
+	_, err = api.Database.GetTemplateByOrganizationAndName(sysCtx, database.GetTemplateByOrganizationAndNameParams{
+		OrganizationID: organization.ID,
+		Name:           template.Name,
+	})
+	if err == nil {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Template with name %q already exists in the organization.", template.Name),
+		})
+		return
+	}
+	if !httpapi.Is404Error(err) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	groupACL, groupMapping, validErrs, err := remapTemplateGroupACL(sysCtx, api.Database, template, organization.ID, req.GroupMapping)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	workspaces, err := api.Database.GetWorkspacesByTemplateID(sysCtx, template.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, xerrors.Errorf("get workspaces: %w", err))
+		return
+	}
+	// Workspaces are always in the organization of their template, so they
+	// can not be left behind. Moving them must be confirmed, since they then
+	// count towards the quota of their owners in the target organization.
+	if len(workspaces) > 0 && !req.ConfirmWorkspaceTransfer {
+		validErrs = append(validErrs, codersdk.ValidationError{
+			Field:  "confirm_workspace_transfer",
+			Detail: fmt.Sprintf("The template has %d workspaces, which move with it. Set confirm_workspace_transfer to move them.", len(workspaces)),
+		})
+	}
+
+	// Users with access to the template, and the owners of the workspaces,
+	// must be members of the target organization.
+	userIDs := make([]uuid.UUID, 0, len(template.UserACL))
+	for id := range template.UserACL {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		userIDs = append(userIDs, userID)
+	}
+	ownerIDs := make([]uuid.UUID, 0)
+	for _, workspace := range workspaces {
+		if !slices.Contains(ownerIDs, workspace.OwnerID) {
+			ownerIDs = append(ownerIDs, workspace.OwnerID)
+		}
+	}
+	members, err := organizationMembers(sysCtx, api.Database, organization.ID, append(slices.Clone(userIDs), ownerIDs...))
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	for _, id := range sortedUUIDs(userIDs) {
+		if !members[id] {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "organization_id",
+				Detail: fmt.Sprintf("User %q has access to the template but is not a member of the organization.", id),
+			})
+		}
+	}
+	for _, id := range sortedUUIDs(ownerIDs) {
+		if !members[id] {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "confirm_workspace_transfer",
+				Detail: fmt.Sprintf("User %q owns a workspace of the template but is not a member of the organization.", id),
+			})
+		}
+	}
+
+	if len(workspaces) > 0 && api.AGPL.QuotaCommitter.Load() != nil {
+		quotaErrs, err := checkTransferQuota(sysCtx, api.Database, organization.ID, workspaces)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		validErrs = append(validErrs, quotaErrs...)
+	}
+
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Unable to transfer template.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	var updated database.Template
+	err = api.Database.InTx(func(tx database.Store) error {
+		now := dbtime.Now()
+		err := tx.UpdateTemplateOrganizationByID(ctx, database.UpdateTemplateOrganizationByIDParams{
+			ID:             template.ID,
+			OrganizationID: organization.ID,
+			UpdatedAt:      now,
+			GroupACL:       groupACL,
+			UserACL:        template.UserACL,
+		})
+		if err != nil {
+			return xerrors.Errorf("update template organization: %w", err)
+		}
+		if len(workspaces) > 0 {
+			err = tx.UpdateWorkspacesOrganizationByTemplateID(ctx, database.UpdateWorkspacesOrganizationByTemplateIDParams{
+				TemplateID:     template.ID,
+				OrganizationID: organization.ID,
+				UpdatedAt:      now,
+			})
+			if err != nil {
+				return xerrors.Errorf("update workspaces organization: %w", err)
+			}
+		}
+		updated, err = tx.GetTemplateByID(ctx, template.ID)
+		if err != nil {
+			return xerrors.Errorf("get updated template: %w", err)
+		}
+		return nil
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.New = updated
+
+	resp := codersdk.TransferTemplateResponse{
+		TemplateID:     template.ID,
+		OrganizationID: organization.ID,
+		GroupMapping:   groupMapping,
+		Workspaces:     len(workspaces),
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// remapTemplateGroupACL returns the group ACL of the template with every
+// group replaced by its counterpart in the target organization. Groups are
+// mapped explicitly by the request, or otherwise matched by name.
+func remapTemplateGroupACL(ctx context.Context, db database.Store, template database.Template, orgID uuid.UUID, requested map[string]uuid.UUID) (database.TemplateACL, map[string]uuid.UUID, []codersdk.ValidationError, error) {
+	explicit := make(map[uuid.UUID]uuid.UUID, len(requested))
+	var validErrs []codersdk.ValidationError
+	for source, target := range requested {
+		sourceID, err := uuid.Parse(source)
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "group_mapping",
+				Detail: fmt.Sprintf("Group ID %q must be a valid UUID.", source),
+			})
+			continue
+		}
+		explicit[sourceID] = target
+	}
+
+	acl := database.TemplateACL{}
+	mapping := map[string]uuid.UUID{}
+	sourceIDs := make([]uuid.UUID, 0, len(template.GroupACL))
+	for id := range template.GroupACL {
+		sourceID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, nil, nil, xerrors.Errorf("parse group id %q in template acl: %w", id, err)
+		}
+		sourceIDs = append(sourceIDs, sourceID)
+	}
+	for _, sourceID := range sortedUUIDs(sourceIDs) {
+		var targetID uuid.UUID
+		switch target, ok := explicit[sourceID]; {
+		case sourceID == template.OrganizationID:
+			// The "Everyone" group shares the ID of its organization.
+			targetID = orgID
+		case ok:
+			group, err := db.GetGroupByID(ctx, target)
+			if err != nil && !httpapi.Is404Error(err) {
+				return nil, nil, nil, xerrors.Errorf("get group %q: %w", target, err)
+			}
+			if err != nil || group.OrganizationID != orgID {
+				validErrs = append(validErrs, codersdk.ValidationError{
+					Field:  "group_mapping",
+					Detail: fmt.Sprintf("Group %q does not exist in the organization.", target),
+				})
+				continue
+			}
+			targetID = group.ID
+		default:
+			source, err := db.GetGroupByID(ctx, sourceID)
+			if err != nil {
+				return nil, nil, nil, xerrors.Errorf("get group %q: %w", sourceID, err)
+			}
+			group, err := db.GetGroupByOrgAndName(ctx, database.GetGroupByOrgAndNameParams{
+				OrganizationID: orgID,
+				Name:           source.Name,
+			})
+			if httpapi.Is404Error(err) {
+				validErrs = append(validErrs, codersdk.ValidationError{
+					Field:  "group_mapping",
+					Detail: fmt.Sprintf("Group %q (%s) has no group with the same name in the organization, and must be mapped.", source.Name, sourceID),
+				})
+				continue
+			}
+			if err != nil {
+				return nil, nil, nil, xerrors.Errorf("get group %q by name: %w", source.Name, err)
+			}
+			targetID = group.ID
+		}
+
+		mapping[sourceID.String()] = targetID
+		// Several groups may be mapped to the same group, which then gets
+		// the permissions of all of them.
+		actions := acl[targetID.String()]
+		for _, action := range template.GroupACL[sourceID.String()] {
+			if !slices.Contains(actions, action) {
+				actions = append(actions, action)
+			}
+		}
+		acl[targetID.String()] = actions
+	}
+	return acl, mapping, validErrs, nil
+}
+
+// checkTransferQuota returns a validation error for every workspace owner
+// whose quota in the target organization can not fit the workspaces.
+func checkTransferQuota(ctx context.Context, db database.Store, orgID uuid.UUID, workspaces []database.WorkspaceTable) ([]codersdk.ValidationError, error) {
+	if len(workspaces) == 0 {
+		return nil, nil
+	}
+	ids := make([]uuid.UUID, 0, len(workspaces))
+	ownerByWorkspace := make(map[uuid.UUID]uuid.UUID, len(workspaces))
+	for _, workspace := range workspaces {
+		ids = append(ids, workspace.ID)
+		ownerByWorkspace[workspace.ID] = workspace.OwnerID
+	}
+	builds, err := db.GetLatestWorkspaceBuildsByWorkspaceIDs(ctx, ids)
+	if err != nil {
+		return nil, xerrors.Errorf("get latest workspace builds: %w", err)
+	}
+	costs := map[uuid.UUID]int64{}
+	for _, build := range builds {
+		costs[ownerByWorkspace[build.WorkspaceID]] += int64(build.DailyCost)
+	}
+
+	owners := make([]uuid.UUID, 0, len(costs))
+	for owner := range costs {
+		owners = append(owners, owner)
+	}
+	var validErrs []codersdk.ValidationError
+	for _, owner := range sortedUUIDs(owners) {
+		cost := costs[owner]
+		if cost == 0 {
+			continue
+		}
+		consumed, err := db.GetQuotaConsumedForUser(ctx, database.GetQuotaConsumedForUserParams{
+			OwnerID:        owner,
+			OrganizationID: orgID,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("get quota consumed: %w", err)
+		}
+		allowance, err := db.GetQuotaAllowanceForUser(ctx, database.GetQuotaAllowanceForUserParams{
+			UserID:         owner,
+			OrganizationID: orgID,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("get quota allowance: %w", err)
+		}
+		if consumed+cost > allowance {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "confirm_workspace_transfer",
+				Detail: fmt.Sprintf("The workspaces of user %q cost %d, but only %d of their quota is left in the organization.", owner, cost, max(0, allowance-consumed)),
+			})
+		}
+	}
+	return validErrs, nil
+}
+
+// organizationMembers returns which of the users are members of the
+// organization.
+func organizationMembers(ctx context.Context, db database.Store, orgID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	members := map[uuid.UUID]bool{}
+	if len(userIDs) == 0 {
+		return members, nil
+	}
+	rows, err := db.GetOrganizationIDsByMemberIDs(ctx, userIDs)
+	if err != nil && !httpapi.Is404Error(err) {
+		return nil, xerrors.Errorf("get organization ids by member ids: %w", err)
+	}
+	for _, row := range rows {
+		members[row.UserID] = slices.Contains(row.OrganizationIDs, orgID)
+	}
+	return members, nil
+}
+
+func sortedUUIDs(ids []uuid.UUID) []uuid.UUID {
+	sorted := slices.Clone(ids)
+	slices.SortFunc(sorted, func(a, b uuid.UUID) int {
+		return slices.Compare(a[:], b[:])
+	})
+	return sorted
+}
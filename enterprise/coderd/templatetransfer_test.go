@@ -0,0 +1,219 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTransferTemplate(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.CreateFirstUserResponse, codersdk.Organization) {
+		t.Helper()
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureMultipleOrganizations: 1,
+					codersdk.FeatureTemplateRBAC:          1,
+				},
+			},
+		})
+		org := coderdenttest.CreateOrganization(t, client, coderdenttest.CreateOrganizationOptions{})
+		return client, owner, org
+	}
+
+	t.Run("Mainline", func(t *testing.T) {
+		t.Parallel()
+		client, owner, org := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		_, err := client.PostOrganizationMember(ctx, org.ID, member.ID.String())
+		require.NoError(t, err)
+
+		sourceGroup := coderdtest.CreateGroup(t, client, owner.OrganizationID, "developers")
+		targetGroup := coderdtest.CreateGroup(t, client, org.ID, "developers")
+
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			GroupPerms: map[string]codersdk.TemplateRole{
+				sourceGroup.ID.String(): codersdk.TemplateRoleAdmin,
+			},
+		})
+		require.NoError(t, err)
+
+		workspace := coderdtest.CreateWorkspace(t, memberClient, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		resp, err := client.TransferTemplate(ctx, template.ID, codersdk.TransferTemplateRequest{
+			OrganizationID:           org.ID,
+			ConfirmWorkspaceTransfer: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, resp.Workspaces)
+		require.Equal(t, map[string]uuid.UUID{
+			owner.OrganizationID.String(): org.ID,
+			sourceGroup.ID.String():       targetGroup.ID,
+		}, resp.GroupMapping)
+
+		template, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, org.ID, template.OrganizationID)
+
+		version, err = client.TemplateVersion(ctx, version.ID)
+		require.NoError(t, err)
+		require.Equal(t, org.ID, version.OrganizationID)
+
+		workspace, err = client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, org.ID, workspace.OrganizationID)
+
+		acl, err := client.TemplateACL(ctx, template.ID)
+		require.NoError(t, err)
+		groupIDs := make([]uuid.UUID, 0, len(acl.Groups))
+		for _, group := range acl.Groups {
+			groupIDs = append(groupIDs, group.ID)
+		}
+		require.ElementsMatch(t, []uuid.UUID{org.ID, targetGroup.ID}, groupIDs)
+	})
+
+	t.Run("UnconfirmedWorkspaces", func(t *testing.T) {
+		t.Parallel()
+		client, owner, org := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		_, err := client.PostOrganizationMember(ctx, org.ID, member.ID.String())
+		require.NoError(t, err)
+
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, memberClient, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		// The workspaces move with the template, which must be confirmed.
+		_, err = client.TransferTemplate(ctx, template.ID, codersdk.TransferTemplateRequest{
+			OrganizationID: org.ID,
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+		require.Len(t, sdkErr.Validations, 1)
+		require.Equal(t, "confirm_workspace_transfer", sdkErr.Validations[0].Field)
+
+		// Nothing was moved.
+		template, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, owner.OrganizationID, template.OrganizationID)
+		workspace, err = client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, owner.OrganizationID, workspace.OrganizationID)
+	})
+
+	t.Run("GroupMapping", func(t *testing.T) {
+		t.Parallel()
+		client, owner, org := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		sourceGroup := coderdtest.CreateGroup(t, client, owner.OrganizationID, "platform")
+		targetGroup := coderdtest.CreateGroup(t, client, org.ID, "infrastructure")
+
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			GroupPerms: map[string]codersdk.TemplateRole{
+				sourceGroup.ID.String(): codersdk.TemplateRoleUse,
+			},
+		})
+		require.NoError(t, err)
+
+		// There is no group with the same name in the organization.
+		_, err = client.TransferTemplate(ctx, template.ID, codersdk.TransferTemplateRequest{
+			OrganizationID: org.ID,
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+		require.Len(t, sdkErr.Validations, 1)
+		require.Equal(t, "group_mapping", sdkErr.Validations[0].Field)
+
+		resp, err := client.TransferTemplate(ctx, template.ID, codersdk.TransferTemplateRequest{
+			OrganizationID: org.ID,
+			GroupMapping: map[string]uuid.UUID{
+				sourceGroup.ID.String(): targetGroup.ID,
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, targetGroup.ID, resp.GroupMapping[sourceGroup.ID.String()])
+	})
+
+	t.Run("NameConflict", func(t *testing.T) {
+		t.Parallel()
+		client, owner, org := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		// Move another template to the organization, and give it the name of
+		// the first one.
+		otherVersion := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, otherVersion.ID)
+		other := coderdtest.CreateTemplate(t, client, owner.OrganizationID, otherVersion.ID)
+		_, err := client.TransferTemplate(ctx, other.ID, codersdk.TransferTemplateRequest{
+			OrganizationID: org.ID,
+		})
+		require.NoError(t, err)
+		_, err = client.UpdateTemplateMeta(ctx, other.ID, codersdk.UpdateTemplateMeta{
+			Name: template.Name,
+		})
+		require.NoError(t, err)
+
+		_, err = client.TransferTemplate(ctx, template.ID, codersdk.TransferTemplateRequest{
+			OrganizationID: org.ID,
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusConflict, sdkErr.StatusCode())
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+		client, owner, org := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		// A template admin of the source organization, who is only a member
+		// of the target organization, can not create templates there.
+		templateAdmin, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.ScopedRoleOrgTemplateAdmin(owner.OrganizationID))
+		_, err := client.PostOrganizationMember(ctx, org.ID, user.ID.String())
+		require.NoError(t, err)
+		_, err = templateAdmin.TransferTemplate(ctx, template.ID, codersdk.TransferTemplateRequest{
+			OrganizationID: org.ID,
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+	})
+}
@@ -1,6 +1,7 @@
 package coderd
 
 import (
+	"cmp"
 	"context"
 	"crypto/sha256"
 	"database/sql"
@@ -52,16 +53,28 @@ func (api *API) forceWorkspaceProxyHealthUpdate(ctx context.Context) {
 // NOTE: this doesn't need a swagger definition since AGPL already has one, and
 // this route overrides the AGPL one.
 func (api *API) regions(rw http.ResponseWriter, r *http.Request) {
-	regions, err := api.fetchRegions(r.Context())
+	ctx := r.Context()
+	latencies := codersdk.ParseRegionLatencyHint(r.Header.Get(codersdk.RegionLatencyHintHeader))
+	if len(latencies) == 0 {
+		// Fall back to the latencies the user's client last reported. They
+		// are only hints, so regions are still returned without them.
+		reported, err := api.AGPL.UserRegionLatencies(ctx, httpmw.APIKey(r).UserID)
+		if err != nil {
+			api.Logger.Debug(ctx, "get user region latencies", slog.Error(err))
+		} else {
+			latencies = reported
+		}
+	}
+	regions, err := api.fetchRegions(ctx, latencies)
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
 		return
 	}
 
-	httpapi.Write(r.Context(), rw, http.StatusOK, regions)
+	httpapi.Write(ctx, rw, http.StatusOK, regions)
 }
 
-func (api *API) fetchRegions(ctx context.Context) (codersdk.RegionsResponse[codersdk.Region], error) {
+func (api *API) fetchRegions(ctx context.Context, latencies map[uuid.UUID]time.Duration) (codersdk.RegionsResponse[codersdk.Region], error) {
 	//nolint:gocritic // this intentionally requests resources that users
 	// cannot usually access in order to give them a full list of available
 	// regions. Regions are just a data subset of proxies.
@@ -80,12 +93,73 @@ func (api *API) fetchRegions(ctx context.Context) (codersdk.RegionsResponse[code
 		// Append the inner region data.
 		regions = append(regions, proxies.Regions[i].Region)
 	}
+	failover := make([]*codersdk.Region, 0, len(regions))
+	for i := range regions {
+		failover = append(failover, &regions[i])
+	}
+	setFailoverRegions(failover, latencies)
 
 	return codersdk.RegionsResponse[codersdk.Region]{
-		Regions: regions,
+		Regions:          regions,
+		HealthTTLSeconds: proxies.HealthTTLSeconds,
 	}, nil
 }
 
+// setProxyFailoverRegions is setFailoverRegions for workspace proxies. DERP-only
+// and deleted proxies don't serve apps, so they never take part in failover.
+func setProxyFailoverRegions(proxies []codersdk.WorkspaceProxy, latencies map[uuid.UUID]time.Duration) {
+	regions := make([]*codersdk.Region, 0, len(proxies))
+	for i := range proxies {
+		if !proxies[i].Deleted && !proxies[i].DerpOnly {
+			regions = append(regions, &proxies[i].Region)
+		}
+	}
+	setFailoverRegions(regions, latencies)
+}
+
+// setFailoverRegions points every unhealthy region at the healthy region its
+// app and terminal traffic should move to. Regions the client reported a
+// latency for are preferred, fastest first. Without hints the primary region
+// wins since it is always healthy.
+func setFailoverRegions(regions []*codersdk.Region, latencies map[uuid.UUID]time.Duration) {
+	candidates := make([]*codersdk.Region, 0, len(regions))
+	for _, region := range regions {
+		region.FailoverRegionID = nil
+		if region.Healthy {
+			candidates = append(candidates, region)
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	best := slices.MinFunc(candidates, func(a, b *codersdk.Region) int {
+		aLatency, aHinted := latencies[a.ID]
+		bLatency, bHinted := latencies[b.ID]
+		if aHinted != bHinted {
+			if aHinted {
+				return -1
+			}
+			return 1
+		}
+		if c := cmp.Compare(aLatency, bLatency); c != 0 {
+			return c
+		}
+		if aPrimary, bPrimary := a.Name == "primary", b.Name == "primary"; aPrimary != bPrimary {
+			if aPrimary {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, region := range regions {
+		if !region.Healthy {
+			id := best.ID
+			region.FailoverRegionID = &id
+		}
+	}
+}
+
 // @Summary Update workspace proxy
 // @ID update-workspace-proxy
 // @Security CoderSessionToken
@@ -142,14 +216,41 @@ func (api *API) patchWorkspaceProxy(rw http.ResponseWriter, r *http.Request) {
 			return
 		}
 	} else {
-		updatedProxy, err = api.Database.UpdateWorkspaceProxy(ctx, database.UpdateWorkspaceProxyParams{
-			Name:        req.Name,
-			DisplayName: req.DisplayName,
-			Icon:        req.Icon,
-			ID:          proxy.ID,
-			// If hashedSecret is nil or empty, this will not update the secret.
-			TokenHashedSecret: hashedSecret,
-		})
+		err = api.Database.InTx(func(db database.Store) error {
+			if len(hashedSecret) > 0 {
+				// Record the regeneration as a rotation whose previous token
+				// has already expired, so neither the current token nor the
+				// one before the last rotation are accepted anymore.
+				now := dbtime.Now()
+				_, err := db.RotateWorkspaceProxyToken(ctx, database.RotateWorkspaceProxyTokenParams{
+					NewTokenHashedSecret:      hashedSecret,
+					RotatedAt:                 now,
+					ProxyID:                   proxy.ID,
+					PreviousTokenHashedSecret: proxy.TokenHashedSecret,
+					PreviousTokenExpiresAt:    now,
+				})
+				if err != nil {
+					return xerrors.Errorf("rotate workspace proxy token: %w", err)
+				}
+			}
+
+			var err error
+			updatedProxy, err = db.UpdateWorkspaceProxy(ctx, database.UpdateWorkspaceProxyParams{
+				Name:        req.Name,
+				DisplayName: req.DisplayName,
+				Icon:        req.Icon,
+				ID:          proxy.ID,
+				// If hashedSecret is nil or empty, this will not update the secret.
+				TokenHashedSecret: hashedSecret,
+			})
+			return err
+		}, nil)
+		if xerrors.Is(err, sql.ErrNoRows) && len(hashedSecret) > 0 {
+			httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+				Message: "The workspace proxy token changed while it was regenerated, try again.",
+			})
+			return
+		}
 		if httpapi.Is404Error(err) {
 			httpapi.ResourceNotFound(rw)
 			return
@@ -417,6 +518,7 @@ func validateProxyURL(u string) error {
 // @Security CoderSessionToken
 // @Produce json
 // @Tags Enterprise
+// @Param Coder-Region-Latencies header string false "Comma-separated region-id=milliseconds latency hints used to pick failover regions"
 // @Success 200 {array} codersdk.RegionsResponse[codersdk.WorkspaceProxy]
 // @Router /workspaceproxies [get]
 func (api *API) workspaceProxies(rw http.ResponseWriter, r *http.Request) {
@@ -432,6 +534,9 @@ func (api *API) workspaceProxies(rw http.ResponseWriter, r *http.Request) {
 		httpapi.InternalServerError(rw, err)
 		return
 	}
+	if hint := r.Header.Get(codersdk.RegionLatencyHintHeader); hint != "" {
+		setProxyFailoverRegions(proxies.Regions, codersdk.ParseRegionLatencyHint(hint))
+	}
 	httpapi.Write(ctx, rw, http.StatusOK, proxies)
 }
 
@@ -449,8 +554,11 @@ func (api *API) fetchWorkspaceProxies(ctx context.Context) (codersdk.RegionsResp
 	proxies = append([]database.WorkspaceProxy{primaryProxy}, proxies...)
 
 	statues := api.ProxyHealth.HealthStatus()
+	regions := convertProxies(proxies, statues)
+	setProxyFailoverRegions(regions, nil)
 	return codersdk.RegionsResponse[codersdk.WorkspaceProxy]{
-		Regions: convertProxies(proxies, statues),
+		Regions:          regions,
+		HealthTTLSeconds: int64(api.ProxyHealth.Interval().Seconds()),
 	}, nil
 }
 
@@ -604,7 +712,7 @@ func (api *API) workspaceProxyRegister(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	startingRegionID, _ := getProxyDERPStartingRegionID(api.Options.BaseDERPMap)
+	startingRegionID, _ := getProxyDERPStartingRegionID(api.AGPL.BaseDERPMap())
 	// #nosec G115 - Safe conversion as DERP region IDs are small integers expected to be within int32 range
 	regionID := int32(startingRegionID) + proxy.RegionID
 
@@ -709,12 +817,20 @@ func (api *API) workspaceProxyRegister(rw http.ResponseWriter, r *http.Request)
 		siblingsRes = append(siblingsRes, convertReplica(replica))
 	}
 
+	tokenRotation, err := api.workspaceProxyTokenRotation(ctx, proxy)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
 	httpapi.Write(ctx, rw, http.StatusCreated, wsproxysdk.RegisterWorkspaceProxyResponse{
 		DERPMeshKey:         api.DERPServer.MeshKey(),
 		DERPRegionID:        regionID,
 		DERPMap:             api.AGPL.DERPMap(),
 		DERPForceWebSockets: api.DeploymentValues.DERP.Config.ForceWebSockets.Value(),
 		SiblingReplicas:     siblingsRes,
+		TokenRotation:       tokenRotation,
+		WebDesktopClipboard: codersdk.DesktopClipboardPolicy(api.DeploymentValues.WebDesktopClipboard),
 	})
 
 	go api.forceWorkspaceProxyHealthUpdate(api.ctx)
@@ -874,12 +990,15 @@ func (api *API) reconnectingPTYSignedToken(rw http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// Assert the URL is a valid reconnecting-pty URL.
-	expectedPath := fmt.Sprintf("/api/v2/workspaceagents/%s/pty", req.AgentID.String())
-	if u.Path != expectedPath {
+	// Assert the URL is a valid reconnecting-pty URL, or a web desktop URL
+	// which is authorized the same way.
+	ptyPath := fmt.Sprintf("/api/v2/workspaceagents/%s/pty", req.AgentID.String())
+	desktopPath := fmt.Sprintf("/api/v2/workspaceagents/%s/desktop/vnc", req.AgentID.String())
+	rdpPath := fmt.Sprintf("/api/v2/workspaceagents/%s/desktop/rdp", req.AgentID.String())
+	if u.Path != ptyPath && u.Path != desktopPath && u.Path != rdpPath {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Invalid URL path.",
-			Detail:  "The provided URL is not a valid reconnecting PTY endpoint URL.",
+			Detail:  "The provided URL is not a valid reconnecting PTY or web desktop endpoint URL.",
 		})
 		return
 	}
@@ -2,8 +2,12 @@ package coderd
 
 import (
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
 )
 
 func Test_validateProxyURL(t *testing.T) {
@@ -59,3 +63,94 @@ func Test_validateProxyURL(t *testing.T) {
 		})
 	}
 }
+
+func Test_setFailoverRegions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		primaryID   = uuid.New()
+		healthyAID  = uuid.New()
+		healthyBID  = uuid.New()
+		unhealthyID = uuid.New()
+	)
+	newRegions := func() []*codersdk.Region {
+		return []*codersdk.Region{
+			{ID: primaryID, Name: "primary", Healthy: true},
+			{ID: healthyAID, Name: "a", Healthy: true},
+			{ID: healthyBID, Name: "b", Healthy: true},
+			{ID: unhealthyID, Name: "unhealthy", Healthy: false},
+		}
+	}
+
+	testcases := []struct {
+		Name      string
+		Latencies map[uuid.UUID]time.Duration
+		Expected  uuid.UUID
+	}{
+		{
+			Name:     "NoHints",
+			Expected: primaryID,
+		},
+		{
+			Name: "FastestHint",
+			Latencies: map[uuid.UUID]time.Duration{
+				primaryID:  80 * time.Millisecond,
+				healthyAID: 40 * time.Millisecond,
+				healthyBID: 20 * time.Millisecond,
+			},
+			Expected: healthyBID,
+		},
+		{
+			Name: "HintedBeatsPrimary",
+			Latencies: map[uuid.UUID]time.Duration{
+				healthyAID: 500 * time.Millisecond,
+			},
+			Expected: healthyAID,
+		},
+		{
+			Name: "IgnoresUnhealthyHint",
+			Latencies: map[uuid.UUID]time.Duration{
+				unhealthyID: time.Millisecond,
+				healthyAID:  40 * time.Millisecond,
+			},
+			Expected: healthyAID,
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+
+			regions := newRegions()
+			setFailoverRegions(regions, tt.Latencies)
+			for _, region := range regions {
+				if region.Healthy {
+					require.Nil(t, region.FailoverRegionID, region.Name)
+					continue
+				}
+				require.NotNil(t, region.FailoverRegionID, region.Name)
+				require.Equal(t, tt.Expected, *region.FailoverRegionID, region.Name)
+			}
+		})
+	}
+}
+
+func Test_workspaceProxyVersionSkew(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		Primary  string
+		Proxy    string
+		Expected codersdk.WorkspaceProxyVersionSkew
+	}{
+		{Primary: "v2.20.1", Proxy: "v2.20.1", Expected: codersdk.WorkspaceProxyVersionSkewMatch},
+		{Primary: "v2.20.1", Proxy: "v2.20.1+abcdef", Expected: codersdk.WorkspaceProxyVersionSkewMatch},
+		{Primary: "v2.20.1", Proxy: "v2.20.0", Expected: codersdk.WorkspaceProxyVersionSkewPatch},
+		{Primary: "v2.20.1", Proxy: "v2.19.4", Expected: codersdk.WorkspaceProxyVersionSkewMinor},
+		{Primary: "v2.20.1", Proxy: "v1.20.1", Expected: codersdk.WorkspaceProxyVersionSkewMajor},
+		{Primary: "v2.20.1", Proxy: "", Expected: codersdk.WorkspaceProxyVersionSkewUnknown},
+		{Primary: "v2.20.1", Proxy: "v0.0.0-devel+abcdef", Expected: codersdk.WorkspaceProxyVersionSkewUnknown},
+	} {
+		require.Equal(t, tc.Expected, workspaceProxyVersionSkew(tc.Primary, tc.Proxy), "primary %q proxy %q", tc.Primary, tc.Proxy)
+	}
+}
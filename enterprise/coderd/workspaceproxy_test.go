@@ -826,7 +826,7 @@ func TestReconnectingPTYSignedToken(t *testing.T) {
 		require.ErrorAs(t, err, &sdkErr)
 		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
 		require.Contains(t, sdkErr.Response.Message, "Invalid URL")
-		require.Contains(t, sdkErr.Response.Detail, "The provided URL is not a valid reconnecting PTY endpoint URL")
+		require.Contains(t, sdkErr.Response.Detail, "The provided URL is not a valid reconnecting PTY or web desktop endpoint URL")
 	})
 
 	t.Run("BadHostname", func(t *testing.T) {
@@ -896,6 +896,36 @@ func TestReconnectingPTYSignedToken(t *testing.T) {
 		// The token is validated in the apptest suite, so we don't need to
 		// validate it here.
 	})
+
+	t.Run("OKDesktop", func(t *testing.T) {
+		t.Parallel()
+
+		desktopURL := *u
+		desktopURL.Path = fmt.Sprintf("/api/v2/workspaceagents/%s/desktop/vnc", agentID.String())
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		res, err := client.IssueReconnectingPTYSignedToken(ctx, codersdk.IssueReconnectingPTYSignedTokenRequest{
+			URL:     desktopURL.String(),
+			AgentID: agentID,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, res.SignedToken)
+	})
+
+	t.Run("OKDesktopRDP", func(t *testing.T) {
+		t.Parallel()
+
+		rdpURL := *u
+		rdpURL.Path = fmt.Sprintf("/api/v2/workspaceagents/%s/desktop/rdp", agentID.String())
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		res, err := client.IssueReconnectingPTYSignedToken(ctx, codersdk.IssueReconnectingPTYSignedTokenRequest{
+			URL:     rdpURL.String(),
+			AgentID: agentID,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, res.SignedToken)
+	})
 }
 
 func TestGetCryptoKeys(t *testing.T) {
@@ -0,0 +1,211 @@
+package coderd
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/mod/semver"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/wsproxy/wsproxysdk"
+)
+
+// workspaceProxyTokenRotation returns when the proxy should rotate its token
+// next. Proxies that never rotated their token rotate it one interval after
+// they were created.
+func (api *API) workspaceProxyTokenRotation(ctx context.Context, proxy database.WorkspaceProxy) (wsproxysdk.TokenRotation, error) {
+	interval := api.DeploymentValues.ProxyTokenRotationInterval.Value()
+	if interval <= 0 {
+		return wsproxysdk.TokenRotation{}, nil
+	}
+
+	lastRotatedAt := proxy.CreatedAt
+	rotation, err := api.Database.GetWorkspaceProxyTokenRotationByProxyID(ctx, proxy.ID)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return wsproxysdk.TokenRotation{}, xerrors.Errorf("get workspace proxy token rotation: %w", err)
+	}
+	if err == nil {
+		lastRotatedAt = rotation.RotatedAt
+	}
+
+	return wsproxysdk.TokenRotation{
+		Enabled:        true,
+		NextRotationAt: lastRotatedAt.Add(interval),
+	}, nil
+}
+
+// workspaceProxyRotateToken replaces the token of the proxy. The token used
+// to make the request keeps working for the grace period so that other
+// replicas of the proxy can pick up the new token. Requests made with a token
+// that was already rotated are refused, otherwise a leaked token could be
+// rotated forever.
+//
+// @Summary Rotate workspace proxy token
+// @ID rotate-workspace-proxy-token
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Success 200 {object} wsproxysdk.RotateTokenResponse
+// @Router /workspaceproxies/me/token-rotation [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceProxyRotateToken(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		proxy = httpmw.WorkspaceProxy(r)
+	)
+
+	interval := api.DeploymentValues.ProxyTokenRotationInterval.Value()
+	if interval <= 0 {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Workspace proxy token rotation is not enabled.",
+		})
+		return
+	}
+
+	// The middleware accepts the previous token too, so check the request
+	// was made with the current one.
+	_, secret, _ := strings.Cut(r.Header.Get(httpmw.WorkspaceProxyAuthTokenHeader), ":")
+	hashedSecret := sha256.Sum256([]byte(secret))
+	if subtle.ConstantTimeCompare(proxy.TokenHashedSecret, hashedSecret[:]) != 1 {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The workspace proxy token has already been rotated.",
+		})
+		return
+	}
+
+	fullToken, newHashedSecret, err := generateWorkspaceProxyToken(proxy.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	now := dbtime.Now()
+	rotation, err := api.Database.RotateWorkspaceProxyToken(ctx, database.RotateWorkspaceProxyTokenParams{
+		NewTokenHashedSecret:      newHashedSecret,
+		RotatedAt:                 now,
+		ProxyID:                   proxy.ID,
+		PreviousTokenHashedSecret: proxy.TokenHashedSecret,
+		PreviousTokenExpiresAt:    now.Add(api.DeploymentValues.ProxyTokenRotationGracePeriod.Value()),
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The workspace proxy token has already been rotated.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, wsproxysdk.RotateTokenResponse{
+		ProxyToken:             fullToken,
+		PreviousTokenExpiresAt: rotation.PreviousTokenExpiresAt,
+		NextRotationAt:         rotation.RotatedAt.Add(interval),
+	})
+}
+
+// @Summary Get workspace proxy tokens
+// @Description Returns the age of the token and the version skew of every
+// @Description workspace proxy.
+// @ID get-workspace-proxy-tokens
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Success 200 {object} codersdk.WorkspaceProxyTokensResponse
+// @Router /workspaceproxies/tokens [get]
+func (api *API) workspaceProxyTokens(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Any user can read proxies to use them, but only admins manage their
+	// tokens.
+	if !api.Authorize(r, policy.ActionUpdate, rbac.ResourceWorkspaceProxy) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	proxies, err := api.Database.GetWorkspaceProxies(ctx)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	rotations, err := api.Database.GetWorkspaceProxyTokenRotations(ctx)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	rotationsByProxy := make(map[uuid.UUID]database.WorkspaceProxyTokenRotation, len(rotations))
+	for _, rotation := range rotations {
+		rotationsByProxy[rotation.ProxyID] = rotation
+	}
+
+	var (
+		now            = dbtime.Now()
+		interval       = api.DeploymentValues.ProxyTokenRotationInterval.Value()
+		primaryVersion = buildinfo.Version()
+		statuses       = make([]codersdk.WorkspaceProxyTokenStatus, 0, len(proxies))
+	)
+	for _, proxy := range proxies {
+		status := codersdk.WorkspaceProxyTokenStatus{
+			ProxyID:       proxy.ID,
+			ProxyName:     proxy.Name,
+			TokenIssuedAt: proxy.CreatedAt,
+			Version:       proxy.Version,
+			VersionSkew:   workspaceProxyVersionSkew(primaryVersion, proxy.Version),
+		}
+		if rotation, ok := rotationsByProxy[proxy.ID]; ok {
+			status.TokenIssuedAt = rotation.RotatedAt
+			if rotation.PreviousTokenExpiresAt.After(now) {
+				status.PreviousTokenExpiresAt = &rotation.PreviousTokenExpiresAt
+			}
+		}
+		status.TokenAgeSeconds = int64(now.Sub(status.TokenIssuedAt).Seconds())
+		if interval > 0 {
+			next := status.TokenIssuedAt.Add(interval)
+			status.NextRotationAt = &next
+		}
+		statuses = append(statuses, status)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceProxyTokensResponse{
+		PrimaryVersion: primaryVersion,
+		Proxies:        statuses,
+	})
+}
+
+// workspaceProxyVersionSkew compares the version of a proxy to the version of
+// the primary.
+func workspaceProxyVersionSkew(primary, proxy string) codersdk.WorkspaceProxyVersionSkew {
+	if !semver.IsValid(primary) || !semver.IsValid(proxy) ||
+		buildinfo.IsDevVersion(primary) || buildinfo.IsDevVersion(proxy) {
+		return codersdk.WorkspaceProxyVersionSkewUnknown
+	}
+	switch {
+	case semver.Major(primary) != semver.Major(proxy):
+		return codersdk.WorkspaceProxyVersionSkewMajor
+	case semver.MajorMinor(primary) != semver.MajorMinor(proxy):
+		return codersdk.WorkspaceProxyVersionSkewMinor
+	case semver.Compare(primary, proxy) != 0:
+		return codersdk.WorkspaceProxyVersionSkewPatch
+	default:
+		return codersdk.WorkspaceProxyVersionSkewMatch
+	}
+}
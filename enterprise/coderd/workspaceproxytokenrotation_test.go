@@ -0,0 +1,155 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/enterprise/wsproxy/wsproxysdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/serpent"
+)
+
+func TestWorkspaceProxyTokenRotation(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T, interval time.Duration) (*codersdk.Client, codersdk.CreateFirstUserResponse, codersdk.UpdateWorkspaceProxyResponse) {
+		dv := coderdtest.DeploymentValues(t)
+		dv.ProxyTokenRotationInterval = serpent.Duration(interval)
+		dv.ProxyTokenRotationGracePeriod = serpent.Duration(time.Hour)
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				DeploymentValues: dv,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureWorkspaceProxy: 1,
+				},
+			},
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		proxy, err := client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+			Name: "proxy",
+		})
+		require.NoError(t, err)
+		return client, owner, proxy
+	}
+
+	register := func(t *testing.T, proxyClient *wsproxysdk.Client) (wsproxysdk.RegisterWorkspaceProxyResponse, error) {
+		ctx := testutil.Context(t, testutil.WaitLong)
+		return proxyClient.RegisterWorkspaceProxy(ctx, wsproxysdk.RegisterWorkspaceProxyRequest{
+			AccessURL:   "https://proxy.coder.test",
+			DerpEnabled: true,
+			ReplicaID:   uuid.New(),
+			Version:     buildinfo.Version(),
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		client, _, proxy := setup(t, 0)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		proxyClient := wsproxysdk.New(client.URL)
+		_ = proxyClient.SetSessionToken(proxy.ProxyToken)
+		res, err := register(t, proxyClient)
+		require.NoError(t, err)
+		require.False(t, res.TokenRotation.Enabled)
+
+		_, err = proxyClient.RotateToken(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Rotate", func(t *testing.T) {
+		t.Parallel()
+		client, _, proxy := setup(t, time.Hour)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		proxyClient := wsproxysdk.New(client.URL)
+		_ = proxyClient.SetSessionToken(proxy.ProxyToken)
+		res, err := register(t, proxyClient)
+		require.NoError(t, err)
+		require.True(t, res.TokenRotation.Enabled)
+		require.WithinDuration(t, proxy.Proxy.CreatedAt.Add(time.Hour), res.TokenRotation.NextRotationAt, time.Second)
+
+		rotated, err := proxyClient.RotateToken(ctx)
+		require.NoError(t, err)
+		require.NotEqual(t, proxy.ProxyToken, rotated.ProxyToken)
+		require.True(t, rotated.PreviousTokenExpiresAt.After(time.Now()))
+
+		// Both tokens work during the grace period.
+		_, err = register(t, proxyClient)
+		require.NoError(t, err)
+		newClient := wsproxysdk.New(client.URL)
+		_ = newClient.SetSessionToken(rotated.ProxyToken)
+		res, err = register(t, newClient)
+		require.NoError(t, err)
+		require.WithinDuration(t, rotated.NextRotationAt, res.TokenRotation.NextRotationAt, time.Millisecond)
+
+		// The previous token cannot be rotated again.
+		_, err = proxyClient.RotateToken(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		tokens, err := client.WorkspaceProxyTokens(ctx)
+		require.NoError(t, err)
+		require.Len(t, tokens.Proxies, 1)
+		require.Equal(t, proxy.Proxy.ID, tokens.Proxies[0].ProxyID)
+		require.NotNil(t, tokens.Proxies[0].PreviousTokenExpiresAt)
+		require.NotNil(t, tokens.Proxies[0].NextRotationAt)
+		require.Equal(t, codersdk.WorkspaceProxyVersionSkewUnknown, tokens.Proxies[0].VersionSkew)
+	})
+
+	t.Run("RegenerateRevokesPrevious", func(t *testing.T) {
+		t.Parallel()
+		client, _, proxy := setup(t, time.Hour)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		proxyClient := wsproxysdk.New(client.URL)
+		_ = proxyClient.SetSessionToken(proxy.ProxyToken)
+		rotated, err := proxyClient.RotateToken(ctx)
+		require.NoError(t, err)
+
+		regenerated, err := client.PatchWorkspaceProxy(ctx, codersdk.PatchWorkspaceProxy{
+			ID:              proxy.Proxy.ID,
+			Name:            proxy.Proxy.Name,
+			RegenerateToken: true,
+		})
+		require.NoError(t, err)
+
+		for _, token := range []string{proxy.ProxyToken, rotated.ProxyToken} {
+			oldClient := wsproxysdk.New(client.URL)
+			_ = oldClient.SetSessionToken(token)
+			_, err = register(t, oldClient)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+		}
+		_ = proxyClient.SetSessionToken(regenerated.ProxyToken)
+		_, err = register(t, proxyClient)
+		require.NoError(t, err)
+	})
+
+	t.Run("TokensRequireAdmin", func(t *testing.T) {
+		t.Parallel()
+		client, owner, _ := setup(t, 0)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		_, err := member.WorkspaceProxyTokens(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
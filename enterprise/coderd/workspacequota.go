@@ -4,14 +4,20 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/codersdk"
@@ -42,9 +48,10 @@ func (c *committer) CommitQuota(
 	}
 
 	var (
-		consumed int64
-		budget   int64
-		permit   bool
+		consumed   int64
+		budget     int64
+		permit     bool
+		dimensions []*proto.QuotaDimension
 	)
 	err = c.Database.InTx(func(s database.Store) error {
 		var err error
@@ -67,6 +74,7 @@ func (c *committer) CommitQuota(
 		// If the new build will reduce overall quota consumption, then we
 		// allow it even if the user is over quota.
 		netIncrease := true
+		prevCosts := map[string]int64{}
 		prevBuild, err := s.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams{
 			WorkspaceID: workspace.ID,
 			BuildNumber: nextBuild.BuildNumber - 1,
@@ -79,10 +87,22 @@ func (c *committer) CommitQuota(
 				slog.F("next_cost", request.DailyCost),
 				slog.F("net_increase", netIncrease),
 			)
+			costs, err := s.GetWorkspaceBuildQuotaCosts(ctx, prevBuild.ID)
+			if err != nil {
+				return err
+			}
+			for _, cost := range costs {
+				prevCosts[cost.Dimension] = cost.Cost
+			}
 		} else if !errors.Is(err, sql.ErrNoRows) {
 			return err
 		}
 
+		dimensions, err = checkQuotaDimensions(ctx, s, workspace, request.Dimensions, prevCosts)
+		if err != nil {
+			return err
+		}
+
 		newConsumed := int64(request.DailyCost) + consumed
 		if newConsumed > budget && netIncrease {
 			c.Log.Debug(
@@ -93,6 +113,18 @@ func (c *committer) CommitQuota(
 			)
 			return nil
 		}
+		for _, dimension := range dimensions {
+			if dimension.Exceeded {
+				c.Log.Debug(
+					ctx, "over quota dimension, rejecting",
+					slog.F("dimension", dimension.Name),
+					slog.F("cost", dimension.Cost),
+					slog.F("consumed", dimension.Consumed),
+					slog.F("budget", dimension.Budget),
+				)
+				return nil
+			}
+		}
 
 		err = s.UpdateWorkspaceBuildCostByID(ctx, database.UpdateWorkspaceBuildCostByIDParams{
 			ID:        nextBuild.ID,
@@ -101,6 +133,22 @@ func (c *committer) CommitQuota(
 		if err != nil {
 			return err
 		}
+		if len(request.Dimensions) > 0 {
+			params := database.InsertWorkspaceBuildQuotaCostsParams{
+				WorkspaceBuildID: nextBuild.ID,
+			}
+			for _, dimension := range request.Dimensions {
+				params.Dimensions = append(params.Dimensions, dimension.Name)
+				params.Costs = append(params.Costs, dimension.Cost)
+			}
+			err = s.InsertWorkspaceBuildQuotaCosts(ctx, params)
+			if err != nil {
+				return err
+			}
+		}
+		for _, dimension := range dimensions {
+			dimension.Consumed += dimension.Cost
+		}
 		permit = true
 		consumed = newConsumed
 		return nil
@@ -117,10 +165,68 @@ func (c *committer) CommitQuota(
 		// #nosec G115 - Safe conversion as quota credits consumed value is expected to be within int32 range
 		CreditsConsumed: int32(consumed),
 		// #nosec G115 - Safe conversion as quota budget value is expected to be within int32 range
-		Budget: int32(budget),
+		Budget:     int32(budget),
+		Dimensions: dimensions,
 	}, nil
 }
 
+// checkQuotaDimensions compares the costs of a build with the allowances of
+// the workspace owner. Only dimensions with an allowance in the organization
+// are enforced, the others are returned with a budget of -1. Like the daily
+// cost, a dimension is only exceeded if the build costs more than the
+// previous build.
+func checkQuotaDimensions(ctx context.Context, db database.Store, workspace database.Workspace, costs []*proto.QuotaDimension, prevCosts map[string]int64) ([]*proto.QuotaDimension, error) {
+	allowances, err := db.GetQuotaDimensionAllowancesForUser(ctx, database.GetQuotaDimensionAllowancesForUserParams{
+		UserID:         workspace.OwnerID,
+		OrganizationID: workspace.OrganizationID,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get quota dimension allowances: %w", err)
+	}
+	consumed, err := db.GetQuotaDimensionsConsumedForUser(ctx, database.GetQuotaDimensionsConsumedForUserParams{
+		OwnerID:        workspace.OwnerID,
+		OrganizationID: workspace.OrganizationID,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get quota dimensions consumed: %w", err)
+	}
+
+	dimensions := map[string]*proto.QuotaDimension{}
+	for _, cost := range costs {
+		dimensions[cost.Name] = &proto.QuotaDimension{
+			Name:   cost.Name,
+			Cost:   cost.Cost,
+			Budget: -1,
+		}
+	}
+	for _, allowance := range allowances {
+		dimension, ok := dimensions[allowance.Dimension]
+		if !ok {
+			dimension = &proto.QuotaDimension{Name: allowance.Dimension}
+			dimensions[allowance.Dimension] = dimension
+		}
+		dimension.Budget = allowance.Allowance
+	}
+	for _, row := range consumed {
+		if dimension, ok := dimensions[row.Dimension]; ok {
+			dimension.Consumed = row.Consumed
+		}
+	}
+
+	result := make([]*proto.QuotaDimension, 0, len(dimensions))
+	for _, dimension := range dimensions {
+		if dimension.Budget >= 0 {
+			dimension.Exceeded = dimension.Consumed+dimension.Cost > dimension.Budget &&
+				dimension.Cost > prevCosts[dimension.Name]
+		}
+		result = append(result, dimension)
+	}
+	slices.SortFunc(result, func(a, b *proto.QuotaDimension) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result, nil
+}
+
 // @Summary Get workspace quota by user deprecated
 // @ID get-workspace-quota-by-user-deprecated
 // @Security CoderSessionToken
@@ -189,8 +295,188 @@ func (api *API) workspaceQuota(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var dimensions []codersdk.WorkspaceQuotaDimension
+	if licensed {
+		dimensions, err = api.workspaceQuotaDimensions(r.Context(), user.ID, organization.ID)
+		if err != nil {
+			httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to get quota dimensions",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
 	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.WorkspaceQuota{
 		CreditsConsumed: int(quotaConsumed),
 		Budget:          int(quotaAllowance),
+		Dimensions:      dimensions,
+	})
+}
+
+func (api *API) workspaceQuotaDimensions(ctx context.Context, userID, organizationID uuid.UUID) ([]codersdk.WorkspaceQuotaDimension, error) {
+	allowances, err := api.Database.GetQuotaDimensionAllowancesForUser(ctx, database.GetQuotaDimensionAllowancesForUserParams{
+		UserID:         userID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(allowances) == 0 {
+		return nil, nil
+	}
+	consumed, err := api.Database.GetQuotaDimensionsConsumedForUser(ctx, database.GetQuotaDimensionsConsumedForUserParams{
+		OwnerID:        userID,
+		OrganizationID: organizationID,
 	})
+	if err != nil {
+		return nil, err
+	}
+	consumedByDimension := make(map[string]int64, len(consumed))
+	for _, row := range consumed {
+		consumedByDimension[row.Dimension] = row.Consumed
+	}
+
+	dimensions := make([]codersdk.WorkspaceQuotaDimension, 0, len(allowances))
+	for _, allowance := range allowances {
+		dimensions = append(dimensions, codersdk.WorkspaceQuotaDimension{
+			Name:     allowance.Dimension,
+			Consumed: consumedByDimension[allowance.Dimension],
+			Budget:   allowance.Allowance,
+		})
+	}
+	return dimensions, nil
+}
+
+// quotaDimensionRegex matches the names of quota dimensions, e.g. cpu or
+// memory_gb.
+var quotaDimensionRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// @Summary Get group quota allowances
+// @ID get-group-quota-allowances
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param group path string true "Group id"
+// @Success 200 {array} codersdk.GroupQuotaAllowance
+// @Router /groups/{group}/quota-allowances [get]
+func (api *API) groupQuotaAllowances(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		group = httpmw.GroupParam(r)
+	)
+
+	allowances, err := api.convertGroupQuotaAllowances(ctx, group.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, allowances)
+}
+
+// @Summary Update group quota allowances
+// @ID update-group-quota-allowances
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param group path string true "Group id"
+// @Param request body codersdk.UpdateGroupQuotaAllowancesRequest true "Update group quota allowances request"
+// @Success 200 {array} codersdk.GroupQuotaAllowance
+// @Router /groups/{group}/quota-allowances [put]
+func (api *API) putGroupQuotaAllowances(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		group = httpmw.GroupParam(r)
+	)
+
+	var req codersdk.UpdateGroupQuotaAllowancesRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	params := database.InsertGroupQuotaAllowancesParams{
+		GroupID: group.ID,
+	}
+	var validations []codersdk.ValidationError
+	for dimension, allowance := range req.Allowances {
+		if !quotaDimensionRegex.MatchString(dimension) {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "allowances",
+				Detail: fmt.Sprintf("%q is not a valid quota dimension, it must be lowercase letters, digits, underscores or dashes", dimension),
+			})
+			continue
+		}
+		if allowance < 0 {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "allowances",
+				Detail: fmt.Sprintf("The %q allowance can not be negative", dimension),
+			})
+			continue
+		}
+		params.Dimensions = append(params.Dimensions, dimension)
+		params.Allowances = append(params.Allowances, allowance)
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid quota allowances.",
+			Validations: validations,
+		})
+		return
+	}
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		err := tx.DeleteGroupQuotaAllowances(ctx, group.ID)
+		if err != nil {
+			return xerrors.Errorf("delete allowances: %w", err)
+		}
+		if len(params.Dimensions) == 0 {
+			return nil
+		}
+		err = tx.InsertGroupQuotaAllowances(ctx, params)
+		if err != nil {
+			return xerrors.Errorf("insert allowances: %w", err)
+		}
+		return nil
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	allowances, err := api.convertGroupQuotaAllowances(ctx, group.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, allowances)
+}
+
+func (api *API) convertGroupQuotaAllowances(ctx context.Context, groupID uuid.UUID) ([]codersdk.GroupQuotaAllowance, error) {
+	allowances, err := api.Database.GetGroupQuotaAllowances(ctx, groupID)
+	if err != nil {
+		return nil, xerrors.Errorf("get allowances: %w", err)
+	}
+	consumed, err := api.Database.GetQuotaDimensionsConsumedForGroup(ctx, groupID)
+	if err != nil {
+		return nil, xerrors.Errorf("get consumed: %w", err)
+	}
+	consumedByDimension := make(map[string]int64, len(consumed))
+	for _, row := range consumed {
+		consumedByDimension[row.Dimension] = row.Consumed
+	}
+
+	result := make([]codersdk.GroupQuotaAllowance, 0, len(allowances))
+	for _, allowance := range allowances {
+		result = append(result, codersdk.GroupQuotaAllowance{
+			Dimension: allowance.Dimension,
+			Allowance: allowance.Allowance,
+			Consumed:  consumedByDimension[allowance.Dimension],
+		})
+	}
+	return result, nil
 }
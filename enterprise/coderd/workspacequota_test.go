@@ -266,6 +266,109 @@ func TestWorkspaceQuota(t *testing.T) {
 		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
 	})
 
+	t.Run("Dimensions", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		client, _, api, user := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		coderdtest.NewProvisionerDaemon(t, api.AGPL)
+
+		// The daily cost is not limited, only the cpu dimension.
+		_, err := client.PatchGroup(ctx, user.OrganizationID, codersdk.PatchGroupRequest{
+			QuotaAllowance: ptr.Ref(100),
+		})
+		require.NoError(t, err)
+		allowances, err := client.UpdateGroupQuotaAllowances(ctx, user.OrganizationID, codersdk.UpdateGroupQuotaAllowancesRequest{
+			Allowances: map[string]int64{"cpu": 4},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.GroupQuotaAllowance{{Dimension: "cpu", Allowance: 4}}, allowances)
+
+		resources := []*proto.Resource{{
+			Name:      "example",
+			Type:      "aws_instance",
+			DailyCost: 1,
+			Metadata: []*proto.Resource_Metadata{
+				{Key: "cost:cpu", Value: "2"},
+				{Key: "cost:memory", Value: "8"},
+			},
+		}}
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionPlan: []*proto.Response{{
+				Type: &proto.Response_Plan{Plan: &proto.PlanComplete{Resources: resources}},
+			}},
+			ProvisionApply: []*proto.Response{{
+				Type: &proto.Response_Apply{Apply: &proto.ApplyComplete{Resources: resources}},
+			}},
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		for i := 0; i < 2; i++ {
+			workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+			build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+			require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+		}
+
+		quota, err := client.WorkspaceQuota(ctx, user.OrganizationID.String(), codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, 2, quota.CreditsConsumed)
+		// Memory has no allowance, so it is not enforced.
+		require.Equal(t, []codersdk.WorkspaceQuotaDimension{{Name: "cpu", Consumed: 4, Budget: 4}}, quota.Dimensions)
+
+		// The next one exceeds the cpu allowance.
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
+		require.Contains(t, build.Job.Error, "insufficient quota: cpu")
+
+		allowances, err = client.GroupQuotaAllowances(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.GroupQuotaAllowance{{Dimension: "cpu", Allowance: 4, Consumed: 4}}, allowances)
+
+		// Removing the allowance stops enforcing the dimension.
+		allowances, err = client.UpdateGroupQuotaAllowances(ctx, user.OrganizationID, codersdk.UpdateGroupQuotaAllowancesRequest{})
+		require.NoError(t, err)
+		require.Empty(t, allowances)
+		build = coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStart)
+		build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+	})
+
+	t.Run("InvalidDimension", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateGroupQuotaAllowances(ctx, user.OrganizationID, codersdk.UpdateGroupQuotaAllowancesRequest{
+			Allowances: map[string]int64{"CPU cores": 4},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = client.UpdateGroupQuotaAllowances(ctx, user.OrganizationID, codersdk.UpdateGroupQuotaAllowancesRequest{
+			Allowances: map[string]int64{"cpu": -1},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
 	// Ensures allowance from everyone groups only counts if you are an org member.
 	// This was a bug where the group "Everyone" was being counted for all users,
 	// regardless of membership.
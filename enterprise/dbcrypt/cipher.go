@@ -3,10 +3,8 @@ package dbcrypt
 import (
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/rand"
 	"crypto/sha256"
 	"fmt"
-	"io"
 
 	"golang.org/x/xerrors"
 )
@@ -23,6 +21,10 @@ const (
 	cipherAES256GCM = "aes256gcm"
 )
 
+// gcmNonceSize is the size of the random nonce prepended to values encrypted
+// with AES-256-GCM.
+const gcmNonceSize = 12
+
 type Cipher interface {
 	Encrypt([]byte) ([]byte, error)
 	Decrypt([]byte) ([]byte, error)
@@ -52,7 +54,11 @@ func cipherAES256(key []byte) (*aes256, error) {
 	if err != nil {
 		return nil, err
 	}
-	aead, err := cipher.NewGCM(block)
+	// The nonce is generated by the AEAD rather than by us. This is the
+	// construction approved by the Go Cryptographic Module, so values can
+	// still be encrypted in FIPS 140-3 mode. The output is the same: the
+	// nonce followed by the sealed value.
+	aead, err := cipher.NewGCMWithRandomNonce(block)
 	if err != nil {
 		return nil, err
 	}
@@ -72,21 +78,14 @@ type aes256 struct {
 }
 
 func (a *aes256) Encrypt(plaintext []byte) ([]byte, error) {
-	nonce := make([]byte, a.aead.NonceSize())
-	_, err := io.ReadFull(rand.Reader, nonce)
-	if err != nil {
-		return nil, err
-	}
-	dst := make([]byte, len(nonce))
-	copy(dst, nonce)
-	return a.aead.Seal(dst, nonce, plaintext, nil), nil
+	return a.aead.Seal(nil, nil, plaintext, nil), nil
 }
 
 func (a *aes256) Decrypt(ciphertext []byte) ([]byte, error) {
-	if len(ciphertext) < a.aead.NonceSize() {
+	if len(ciphertext) < gcmNonceSize {
 		return nil, xerrors.Errorf("ciphertext too short")
 	}
-	decrypted, err := a.aead.Open(nil, ciphertext[:a.aead.NonceSize()], ciphertext[a.aead.NonceSize():], nil)
+	decrypted, err := a.aead.Open(nil, nil, ciphertext, nil)
 	if err != nil {
 		return nil, &DecryptFailedError{Inner: err}
 	}
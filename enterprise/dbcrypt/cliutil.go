@@ -73,6 +73,28 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 					return xerrors.Errorf("update external auth link user_id=%s provider_id=%s: %w", externalAuthLink.UserID, externalAuthLink.ProviderID, err)
 				}
 			}
+
+			userSecrets, err := cryptTx.GetUserSecretsByUserID(ctx, uid)
+			if err != nil {
+				return xerrors.Errorf("get secrets for user: %w", err)
+			}
+			for _, secret := range userSecrets {
+				if secret.ValueKeyID.String == ciphers[0].HexDigest() {
+					log.Debug(ctx, "skipping user secret", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+					continue
+				}
+				if _, err := cryptTx.UpdateUserSecret(ctx, database.UpdateUserSecretParams{
+					UserID:      uid,
+					Name:        secret.Name,
+					Description: secret.Description,
+					Value:       secret.Value,
+					ValueKeyID:  sql.NullString{}, // dbcrypt will update as required
+					EnvName:     secret.EnvName,
+					UpdatedAt:   secret.UpdatedAt,
+				}); err != nil {
+					return xerrors.Errorf("update user secret user_id=%s name=%s: %w", secret.UserID, secret.Name, err)
+				}
+			}
 			return nil
 		}, &database.TxOptions{
 			Isolation: sql.LevelRepeatableRead,
@@ -83,6 +105,72 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 		log.Debug(ctx, "encrypted user tokens", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
 	}
 
+	templates, err := db.GetTemplates(ctx)
+	if err != nil {
+		return xerrors.Errorf("get templates: %w", err)
+	}
+	log.Info(ctx, "encrypting template secrets", slog.F("template_count", len(templates)))
+	for idx, tpl := range templates {
+		err := cryptDB.InTx(func(cryptTx database.Store) error {
+			templateSecrets, err := cryptTx.GetTemplateSecretsByTemplateID(ctx, tpl.ID)
+			if err != nil {
+				return xerrors.Errorf("get secrets for template: %w", err)
+			}
+			for _, secret := range templateSecrets {
+				if secret.ValueKeyID.String == ciphers[0].HexDigest() {
+					log.Debug(ctx, "skipping template secret", slog.F("template_id", tpl.ID), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+					continue
+				}
+				if _, err := cryptTx.UpdateTemplateSecret(ctx, database.UpdateTemplateSecretParams{
+					TemplateID:  tpl.ID,
+					Name:        secret.Name,
+					Description: secret.Description,
+					Value:       secret.Value,
+					ValueKeyID:  sql.NullString{}, // dbcrypt will update as required
+					UpdatedAt:   secret.UpdatedAt,
+				}); err != nil {
+					return xerrors.Errorf("update template secret template_id=%s name=%s: %w", secret.TemplateID, secret.Name, err)
+				}
+			}
+			return nil
+		}, &database.TxOptions{
+			Isolation: sql.LevelRepeatableRead,
+		})
+		if err != nil {
+			return xerrors.Errorf("update template secrets: %w", err)
+		}
+		log.Debug(ctx, "encrypted template secrets", slog.F("template_id", tpl.ID), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+	}
+
+	log.Info(ctx, "encrypting variable sets")
+	err = cryptDB.InTx(func(cryptTx database.Store) error {
+		sets, err := cryptTx.GetVariableSets(ctx)
+		if err != nil {
+			return xerrors.Errorf("get variable sets: %w", err)
+		}
+		for _, set := range sets {
+			if set.VariablesKeyID.String == ciphers[0].HexDigest() {
+				log.Debug(ctx, "skipping variable set", slog.F("variable_set_id", set.ID), slog.F("cipher", ciphers[0].HexDigest()))
+				continue
+			}
+			if _, err := cryptTx.UpdateVariableSet(ctx, database.UpdateVariableSetParams{
+				ID:             set.ID,
+				Description:    set.Description,
+				Variables:      set.Variables,
+				VariablesKeyID: sql.NullString{}, // dbcrypt will update as required
+				UpdatedAt:      set.UpdatedAt,
+			}); err != nil {
+				return xerrors.Errorf("update variable set id=%s: %w", set.ID, err)
+			}
+		}
+		return nil
+	}, &database.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+	})
+	if err != nil {
+		return xerrors.Errorf("update variable sets: %w", err)
+	}
+
 	// Revoke old keys
 	for _, c := range ciphers[1:] {
 		if err := db.RevokeDBCryptKey(ctx, c.HexDigest()); err != nil {
@@ -163,6 +251,28 @@ func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciph
 					return xerrors.Errorf("update external auth link user_id=%s provider_id=%s: %w", externalAuthLink.UserID, externalAuthLink.ProviderID, err)
 				}
 			}
+
+			userSecrets, err := tx.GetUserSecretsByUserID(ctx, uid)
+			if err != nil {
+				return xerrors.Errorf("get secrets for user: %w", err)
+			}
+			for _, secret := range userSecrets {
+				if !secret.ValueKeyID.Valid {
+					log.Debug(ctx, "skipping user secret", slog.F("user_id", uid), slog.F("current", idx+1))
+					continue
+				}
+				if _, err := tx.UpdateUserSecret(ctx, database.UpdateUserSecretParams{
+					UserID:      uid,
+					Name:        secret.Name,
+					Description: secret.Description,
+					Value:       secret.Value,
+					ValueKeyID:  sql.NullString{}, // we explicitly want to clear the key id
+					EnvName:     secret.EnvName,
+					UpdatedAt:   secret.UpdatedAt,
+				}); err != nil {
+					return xerrors.Errorf("update user secret user_id=%s name=%s: %w", secret.UserID, secret.Name, err)
+				}
+			}
 			return nil
 		}, &database.TxOptions{
 			Isolation: sql.LevelRepeatableRead,
@@ -173,6 +283,72 @@ func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciph
 		log.Debug(ctx, "decrypted user tokens", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
 	}
 
+	templates, err := db.GetTemplates(ctx)
+	if err != nil {
+		return xerrors.Errorf("get templates: %w", err)
+	}
+	log.Info(ctx, "decrypting template secrets", slog.F("template_count", len(templates)))
+	for idx, tpl := range templates {
+		err := cryptDB.InTx(func(tx database.Store) error {
+			templateSecrets, err := tx.GetTemplateSecretsByTemplateID(ctx, tpl.ID)
+			if err != nil {
+				return xerrors.Errorf("get secrets for template: %w", err)
+			}
+			for _, secret := range templateSecrets {
+				if !secret.ValueKeyID.Valid {
+					log.Debug(ctx, "skipping template secret", slog.F("template_id", tpl.ID), slog.F("current", idx+1))
+					continue
+				}
+				if _, err := tx.UpdateTemplateSecret(ctx, database.UpdateTemplateSecretParams{
+					TemplateID:  tpl.ID,
+					Name:        secret.Name,
+					Description: secret.Description,
+					Value:       secret.Value,
+					ValueKeyID:  sql.NullString{}, // we explicitly want to clear the key id
+					UpdatedAt:   secret.UpdatedAt,
+				}); err != nil {
+					return xerrors.Errorf("update template secret template_id=%s name=%s: %w", secret.TemplateID, secret.Name, err)
+				}
+			}
+			return nil
+		}, &database.TxOptions{
+			Isolation: sql.LevelRepeatableRead,
+		})
+		if err != nil {
+			return xerrors.Errorf("update template secrets: %w", err)
+		}
+		log.Debug(ctx, "decrypted template secrets", slog.F("template_id", tpl.ID), slog.F("current", idx+1))
+	}
+
+	log.Info(ctx, "decrypting variable sets")
+	err = cryptDB.InTx(func(tx database.Store) error {
+		sets, err := tx.GetVariableSets(ctx)
+		if err != nil {
+			return xerrors.Errorf("get variable sets: %w", err)
+		}
+		for _, set := range sets {
+			if !set.VariablesKeyID.Valid {
+				log.Debug(ctx, "skipping variable set", slog.F("variable_set_id", set.ID))
+				continue
+			}
+			if _, err := tx.UpdateVariableSet(ctx, database.UpdateVariableSetParams{
+				ID:             set.ID,
+				Description:    set.Description,
+				Variables:      set.Variables,
+				VariablesKeyID: sql.NullString{}, // we explicitly want to clear the key id
+				UpdatedAt:      set.UpdatedAt,
+			}); err != nil {
+				return xerrors.Errorf("update variable set id=%s: %w", set.ID, err)
+			}
+		}
+		return nil
+	}, &database.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+	})
+	if err != nil {
+		return xerrors.Errorf("update variable sets: %w", err)
+	}
+
 	// Revoke _all_ keys
 	for _, c := range ciphers {
 		if err := db.RevokeDBCryptKey(ctx, c.HexDigest()); err != nil {
@@ -193,6 +369,12 @@ DELETE FROM user_links
 DELETE FROM external_auth_links
 	WHERE oauth_access_token_key_id IS NOT NULL
 	OR oauth_refresh_token_key_id IS NOT NULL;
+DELETE FROM user_secrets
+	WHERE value_key_id IS NOT NULL;
+DELETE FROM template_secrets
+	WHERE value_key_id IS NOT NULL;
+DELETE FROM variable_sets
+	WHERE variables_key_id IS NOT NULL;
 COMMIT;
 `
 
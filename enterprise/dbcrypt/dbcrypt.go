@@ -351,6 +351,175 @@ func (db *dbCrypt) GetCryptoKeysByFeature(ctx context.Context, feature database.
 	return keys, nil
 }
 
+func (db *dbCrypt) GetUserSecretsByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserSecret, error) {
+	secrets, err := db.Store.GetUserSecretsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets {
+		if err := db.decryptField(&secrets[i].Value, secrets[i].ValueKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return secrets, nil
+}
+
+func (db *dbCrypt) GetUserSecretByUserIDAndName(ctx context.Context, params database.GetUserSecretByUserIDAndNameParams) (database.UserSecret, error) {
+	secret, err := db.Store.GetUserSecretByUserIDAndName(ctx, params)
+	if err != nil {
+		return database.UserSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.UserSecret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) InsertUserSecret(ctx context.Context, params database.InsertUserSecretParams) (database.UserSecret, error) {
+	if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+		return database.UserSecret{}, err
+	}
+	secret, err := db.Store.InsertUserSecret(ctx, params)
+	if err != nil {
+		return database.UserSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.UserSecret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) UpdateUserSecret(ctx context.Context, params database.UpdateUserSecretParams) (database.UserSecret, error) {
+	if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+		return database.UserSecret{}, err
+	}
+	secret, err := db.Store.UpdateUserSecret(ctx, params)
+	if err != nil {
+		return database.UserSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.UserSecret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) GetTemplateSecretsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateSecret, error) {
+	secrets, err := db.Store.GetTemplateSecretsByTemplateID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets {
+		if err := db.decryptField(&secrets[i].Value, secrets[i].ValueKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return secrets, nil
+}
+
+func (db *dbCrypt) GetTemplateSecretByTemplateIDAndName(ctx context.Context, params database.GetTemplateSecretByTemplateIDAndNameParams) (database.TemplateSecret, error) {
+	secret, err := db.Store.GetTemplateSecretByTemplateIDAndName(ctx, params)
+	if err != nil {
+		return database.TemplateSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) InsertTemplateSecret(ctx context.Context, params database.InsertTemplateSecretParams) (database.TemplateSecret, error) {
+	if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	secret, err := db.Store.InsertTemplateSecret(ctx, params)
+	if err != nil {
+		return database.TemplateSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) UpdateTemplateSecret(ctx context.Context, params database.UpdateTemplateSecretParams) (database.TemplateSecret, error) {
+	if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	secret, err := db.Store.UpdateTemplateSecret(ctx, params)
+	if err != nil {
+		return database.TemplateSecret{}, err
+	}
+	if err := db.decryptField(&secret.Value, secret.ValueKeyID); err != nil {
+		return database.TemplateSecret{}, err
+	}
+	return secret, nil
+}
+
+func (db *dbCrypt) GetVariableSets(ctx context.Context) ([]database.VariableSet, error) {
+	sets, err := db.Store.GetVariableSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sets {
+		if err := db.decryptField(&sets[i].Variables, sets[i].VariablesKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return sets, nil
+}
+
+func (db *dbCrypt) GetVariableSetByName(ctx context.Context, name string) (database.VariableSet, error) {
+	set, err := db.Store.GetVariableSetByName(ctx, name)
+	if err != nil {
+		return database.VariableSet{}, err
+	}
+	if err := db.decryptField(&set.Variables, set.VariablesKeyID); err != nil {
+		return database.VariableSet{}, err
+	}
+	return set, nil
+}
+
+func (db *dbCrypt) GetVariableSetsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.VariableSet, error) {
+	sets, err := db.Store.GetVariableSetsByTemplateID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sets {
+		if err := db.decryptField(&sets[i].Variables, sets[i].VariablesKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return sets, nil
+}
+
+func (db *dbCrypt) InsertVariableSet(ctx context.Context, params database.InsertVariableSetParams) (database.VariableSet, error) {
+	if err := db.encryptField(&params.Variables, &params.VariablesKeyID); err != nil {
+		return database.VariableSet{}, err
+	}
+	set, err := db.Store.InsertVariableSet(ctx, params)
+	if err != nil {
+		return database.VariableSet{}, err
+	}
+	if err := db.decryptField(&set.Variables, set.VariablesKeyID); err != nil {
+		return database.VariableSet{}, err
+	}
+	return set, nil
+}
+
+func (db *dbCrypt) UpdateVariableSet(ctx context.Context, params database.UpdateVariableSetParams) (database.VariableSet, error) {
+	if err := db.encryptField(&params.Variables, &params.VariablesKeyID); err != nil {
+		return database.VariableSet{}, err
+	}
+	set, err := db.Store.UpdateVariableSet(ctx, params)
+	if err != nil {
+		return database.VariableSet{}, err
+	}
+	if err := db.decryptField(&set.Variables, set.VariablesKeyID); err != nil {
+		return database.VariableSet{}, err
+	}
+	return set, nil
+}
+
 func (db *dbCrypt) encryptField(field *string, digest *sql.NullString) error {
 	// If no cipher is loaded, then we can't encrypt anything!
 	if db.ciphers == nil || db.primaryCipherDigest == "" {
@@ -401,6 +401,84 @@ func TestExternalAuthLinks(t *testing.T) {
 	})
 }
 
+func TestUserSecrets(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("InsertUserSecret", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		user := dbgen.User(t, crypt, database.User{})
+		secret := dbgen.UserSecret(t, crypt, database.UserSecret{
+			UserID: user.ID,
+			Value:  "hunter2",
+		})
+		require.Equal(t, "hunter2", secret.Value)
+
+		secret, err := db.GetUserSecretByUserIDAndName(ctx, database.GetUserSecretByUserIDAndNameParams{
+			UserID: user.ID,
+			Name:   secret.Name,
+		})
+		require.NoError(t, err)
+		requireEncryptedEquals(t, ciphers[0], secret.Value, "hunter2")
+	})
+
+	t.Run("UpdateUserSecret", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		user := dbgen.User(t, crypt, database.User{})
+		secret := dbgen.UserSecret(t, crypt, database.UserSecret{UserID: user.ID})
+		updated, err := crypt.UpdateUserSecret(ctx, database.UpdateUserSecretParams{
+			UserID: user.ID,
+			Name:   secret.Name,
+			Value:  "hunter2",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "hunter2", updated.Value)
+
+		secret, err = db.GetUserSecretByUserIDAndName(ctx, database.GetUserSecretByUserIDAndNameParams{
+			UserID: user.ID,
+			Name:   secret.Name,
+		})
+		require.NoError(t, err)
+		requireEncryptedEquals(t, ciphers[0], secret.Value, "hunter2")
+	})
+
+	t.Run("GetUserSecretsByUserID", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("OK", func(t *testing.T) {
+			t.Parallel()
+			_, crypt, ciphers := setup(t)
+			user := dbgen.User(t, crypt, database.User{})
+			_ = dbgen.UserSecret(t, crypt, database.UserSecret{
+				UserID: user.ID,
+				Value:  "hunter2",
+			})
+			secrets, err := crypt.GetUserSecretsByUserID(ctx, user.ID)
+			require.NoError(t, err)
+			require.Len(t, secrets, 1)
+			require.Equal(t, "hunter2", secrets[0].Value)
+			require.Equal(t, ciphers[0].HexDigest(), secrets[0].ValueKeyID.String)
+		})
+
+		t.Run("DecryptErr", func(t *testing.T) {
+			t.Parallel()
+			db, crypt, ciphers := setup(t)
+			user := dbgen.User(t, db, database.User{})
+			_ = dbgen.UserSecret(t, db, database.UserSecret{
+				UserID:     user.ID,
+				Value:      fakeBase64RandomData(t, 32),
+				ValueKeyID: sql.NullString{String: ciphers[0].HexDigest(), Valid: true},
+			})
+			_, err := crypt.GetUserSecretsByUserID(ctx, user.ID)
+			require.Error(t, err, "expected an error")
+			var derr *DecryptFailedError
+			require.ErrorAs(t, err, &derr, "expected a decrypt error")
+		})
+	})
+}
+
 func TestCryptoKeys(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
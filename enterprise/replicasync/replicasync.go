@@ -35,6 +35,9 @@ type Options struct {
 	RelayAddress    string
 	RegionID        int32
 	TLSConfig       *tls.Config
+	// Restart is called when the replica should drain for the given timeout
+	// and shut down, so that it's restarted by its supervisor.
+	Restart func(drainTimeout time.Duration)
 }
 
 // New registers the replica with the database and periodically updates to
@@ -103,6 +106,10 @@ func New(ctx context.Context, logger slog.Logger, db database.Store, ps pubsub.P
 	if err != nil {
 		return nil, xerrors.Errorf("subscribe: %w", err)
 	}
+	err = manager.subscribeRestart(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("subscribe to restarts: %w", err)
+	}
 	manager.closeWait.Add(1)
 	go manager.loop(ctx)
 	return manager, nil
@@ -125,6 +132,9 @@ type Manager struct {
 	mutex    sync.Mutex
 	peers    []database.Replica
 	callback func()
+	// restarting is true while the replica waits for its turn in a restart,
+	// or drains.
+	restarting bool
 }
 
 func (m *Manager) ID() uuid.UUID {
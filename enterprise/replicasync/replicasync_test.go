@@ -269,6 +269,93 @@ func TestReplica(t *testing.T) {
 	})
 }
 
+func TestRestart(t *testing.T) {
+	t.Parallel()
+	t.Run("Rolling", func(t *testing.T) {
+		// Ensures that replicas restart one at a time, and only once the
+		// replicas before them have been replaced.
+		t.Parallel()
+		ctx, cancelCtx := context.WithCancel(context.Background())
+		defer cancelCtx()
+		db, pubsub := dbtestutil.NewDB(t)
+		dh := &derpyHandler{}
+		defer dh.requireOnlyDERPPaths(t)
+		srv := httptest.NewServer(dh)
+		defer srv.Close()
+
+		newReplica := func() (*replicasync.Manager, <-chan time.Duration) {
+			restarted := make(chan time.Duration, 1)
+			server, err := replicasync.New(ctx, testutil.Logger(t), db, pubsub, &replicasync.Options{
+				RelayAddress:   srv.URL,
+				UpdateInterval: 100 * time.Millisecond,
+				Restart: func(drainTimeout time.Duration) {
+					restarted <- drainTimeout
+				},
+			})
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = server.Close()
+			})
+			return server, restarted
+		}
+		first, firstRestarted := newReplica()
+		second, secondRestarted := newReplica()
+		require.Eventually(t, func() bool {
+			return len(first.AllPrimary()) == 2 && len(second.AllPrimary()) == 2
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		restart, err := second.Restart(ctx, true, time.Second)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{first.ID(), second.ID()}, restart.Replicas)
+
+		select {
+		case drainTimeout := <-firstRestarted:
+			require.Equal(t, time.Second, drainTimeout)
+		case <-time.After(testutil.WaitShort):
+			t.Fatal("first replica didn't restart")
+		}
+		select {
+		case <-secondRestarted:
+			t.Fatal("second replica restarted before the first was replaced")
+		case <-time.After(time.Second):
+		}
+
+		// Replace the first replica.
+		require.NoError(t, first.Close())
+		_, _ = newReplica()
+
+		select {
+		case <-secondRestarted:
+		case <-time.After(testutil.WaitShort):
+			t.Fatal("second replica didn't restart")
+		}
+	})
+	t.Run("AllAtOnce", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancelCtx := context.WithCancel(context.Background())
+		defer cancelCtx()
+		db, pubsub := dbtestutil.NewDB(t)
+		restarted := make(chan struct{}, 1)
+		server, err := replicasync.New(ctx, testutil.Logger(t), db, pubsub, &replicasync.Options{
+			RelayAddress: "http://169.254.169.254",
+			Restart: func(time.Duration) {
+				restarted <- struct{}{}
+			},
+		})
+		require.NoError(t, err)
+		defer server.Close()
+
+		restart, err := server.Restart(ctx, false, time.Second)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{server.ID()}, restart.Replicas)
+		select {
+		case <-restarted:
+		case <-time.After(testutil.WaitShort):
+			t.Fatal("replica didn't restart")
+		}
+	})
+}
+
 type derpyHandler struct {
 	atomic.Uint32
 }
@@ -0,0 +1,189 @@
+package replicasync
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+)
+
+// PubsubEventRestart is published to restart replicas.
+var PubsubEventRestart = "replica_restart"
+
+// restartTimeout is how long a replica waits for its turn in a rolling
+// restart before giving up.
+const restartTimeout = 30 * time.Minute
+
+// Restart is published to coordinate a restart of every primary replica.
+type Restart struct {
+	ID uuid.UUID `json:"id"`
+	// Replicas are the replicas to restart. In a rolling restart they are
+	// restarted one at a time in this order.
+	Replicas []uuid.UUID `json:"replicas"`
+	// Rolling restarts replicas one at a time. Each replica waits until the
+	// replicas before it have been replaced by healthy replicas.
+	Rolling bool `json:"rolling"`
+	// DrainTimeout is how long a replica reports itself as unhealthy before
+	// it shuts down, so that load balancers stop sending it requests.
+	DrainTimeout time.Duration `json:"drain_timeout"`
+}
+
+// Restart restarts every healthy primary replica, including this one. Replicas
+// drain and shut down on their own, and are expected to be started again by
+// their supervisor, e.g. Kubernetes or systemd.
+func (m *Manager) Restart(ctx context.Context, rolling bool, drainTimeout time.Duration) (Restart, error) {
+	replicas, err := m.healthyPrimaryReplicas(ctx)
+	if err != nil {
+		return Restart{}, err
+	}
+	// The oldest replicas are restarted first.
+	slices.SortFunc(replicas, func(a, b database.Replica) int {
+		if c := a.StartedAt.Compare(b.StartedAt); c != 0 {
+			return c
+		}
+		return slices.Compare(a.ID[:], b.ID[:])
+	})
+	restart := Restart{
+		ID:           uuid.New(),
+		Replicas:     make([]uuid.UUID, 0, len(replicas)),
+		Rolling:      rolling,
+		DrainTimeout: drainTimeout,
+	}
+	for _, replica := range replicas {
+		restart.Replicas = append(restart.Replicas, replica.ID)
+	}
+	data, err := json.Marshal(restart)
+	if err != nil {
+		return Restart{}, xerrors.Errorf("marshal restart: %w", err)
+	}
+	err = m.pubsub.Publish(PubsubEventRestart, data)
+	if err != nil {
+		return Restart{}, xerrors.Errorf("publish restart: %w", err)
+	}
+	return restart, nil
+}
+
+// subscribeRestart listens for restarts that include this replica.
+func (m *Manager) subscribeRestart(ctx context.Context) error {
+	cancelFunc, err := m.pubsub.Subscribe(PubsubEventRestart, func(_ context.Context, message []byte) {
+		var restart Restart
+		err := json.Unmarshal(message, &restart)
+		if err != nil {
+			m.logger.Warn(ctx, "unmarshal restart", slog.Error(err))
+			return
+		}
+		if !slices.Contains(restart.Replicas, m.id) {
+			return
+		}
+		m.mutex.Lock()
+		if m.restarting {
+			m.mutex.Unlock()
+			m.logger.Warn(ctx, "ignoring restart, replica is already restarting", slog.F("restart_id", restart.ID))
+			return
+		}
+		m.restarting = true
+		m.mutex.Unlock()
+		go m.restart(ctx, restart)
+	})
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		cancelFunc()
+	}()
+	return nil
+}
+
+func (m *Manager) restart(ctx context.Context, restart Restart) {
+	logger := m.logger.With(slog.F("restart_id", restart.ID), slog.F("rolling", restart.Rolling))
+	if restart.Rolling {
+		logger.Info(ctx, "waiting for turn in rolling restart")
+		err := m.waitForRestartTurn(ctx, restart)
+		if err != nil {
+			logger.Error(ctx, "rolling restart failed, this replica won't be restarted", slog.Error(err))
+			m.mutex.Lock()
+			m.restarting = false
+			m.mutex.Unlock()
+			return
+		}
+	}
+	logger.Info(ctx, "restarting replica", slog.F("drain_timeout", restart.DrainTimeout))
+	if m.options.Restart != nil {
+		m.options.Restart(restart.DrainTimeout)
+	}
+}
+
+// waitForRestartTurn waits until the replicas before this one in a rolling
+// restart have shut down, and as many healthy replicas as before the restart
+// are running again.
+func (m *Manager) waitForRestartTurn(ctx context.Context, restart Restart) error {
+	ctx, cancel := context.WithTimeout(ctx, restartTimeout)
+	defer cancel()
+	ticker := time.NewTicker(m.options.UpdateInterval)
+	defer ticker.Stop()
+	for {
+		ready, err := m.restartTurn(ctx, restart)
+		if err != nil && !xerrors.Is(err, context.Canceled) {
+			m.logger.Warn(ctx, "check rolling restart turn", slog.Error(err))
+		}
+		if ready {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Manager) restartTurn(ctx context.Context, restart Restart) (bool, error) {
+	replicas, err := m.healthyPrimaryReplicas(ctx)
+	if err != nil {
+		return false, err
+	}
+	if len(replicas) < len(restart.Replicas) {
+		return false, nil
+	}
+	for _, replica := range replicas {
+		if replica.Error != "" {
+			return false, nil
+		}
+	}
+	for _, id := range restart.Replicas {
+		if id == m.id {
+			return true, nil
+		}
+		alive := slices.ContainsFunc(replicas, func(replica database.Replica) bool {
+			return replica.ID == id
+		})
+		if alive {
+			return false, nil
+		}
+	}
+	return false, xerrors.New("replica isn't part of the restart")
+}
+
+func (m *Manager) healthyPrimaryReplicas(ctx context.Context) ([]database.Replica, error) {
+	// nolint:gocritic // Reading replicas is a system function
+	replicas, err := m.db.GetReplicasUpdatedAfter(dbauthz.AsSystemRestricted(ctx), m.updateInterval())
+	if err != nil {
+		return nil, xerrors.Errorf("get replicas: %w", err)
+	}
+	healthy := make([]database.Replica, 0, len(replicas))
+	for _, replica := range replicas {
+		if !replica.Primary || replica.StoppedAt.Valid {
+			continue
+		}
+		healthy = append(healthy, replica)
+	}
+	return healthy, nil
+}
@@ -0,0 +1,409 @@
+package tailnet
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	agpl "github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/tailnet/proto"
+)
+
+// ShardQueryParam is added to redirected coordination requests so that the
+// receiving replica serves them even if its view of the shard map differs,
+// preventing redirect loops while replica membership converges.
+const ShardQueryParam = "coordinator_shard"
+
+// ShardedCoordinator partitions agents across replicas. Each agent is owned by
+// exactly one replica, chosen by consistent hashing over the healthy primary
+// replicas reported by replicasync. Agents and clients that connect to the
+// wrong replica are redirected to the owner with RedirectURL, so that an agent
+// and its clients normally land on the same replica.
+//
+// Tunnels between peers connected to the same replica are coordinated entirely
+// in memory. Everything else, such as a client that connected before a
+// membership change or the server's own tailnet dialing an agent owned by
+// another replica, falls back to the high availability coordinator. Agents and
+// other tunnel destinations are always registered with the fallback so they
+// stay reachable from every replica.
+type ShardedCoordinator struct {
+	logger slog.Logger
+	self   uuid.UUID
+	local  agpl.Coordinator
+	ha     agpl.Coordinator
+	shards atomic.Pointer[ShardMap]
+}
+
+var _ agpl.Coordinator = &ShardedCoordinator{}
+
+// NewShardedCoordinator creates a coordinator for the replica with the given
+// ID. Cross-replica coordination is delegated to ha, which is closed along with
+// the returned coordinator.
+func NewShardedCoordinator(logger slog.Logger, self uuid.UUID, ha agpl.Coordinator) *ShardedCoordinator {
+	logger = logger.Named("shardcoord").With(slog.F("replica_id", self))
+	return &ShardedCoordinator{
+		logger: logger,
+		self:   self,
+		local:  agpl.NewCoordinator(logger),
+		ha:     ha,
+	}
+}
+
+// SetReplicas rebuilds the shard map from the current replica membership. It
+// should be called whenever replicasync reports a change.
+func (c *ShardedCoordinator) SetReplicas(replicas []database.Replica) {
+	c.shards.Store(NewShardMap(replicas))
+}
+
+// Owner returns the replica that owns the given agent, if any replica does.
+func (c *ShardedCoordinator) Owner(agentID uuid.UUID) (database.Replica, bool) {
+	return c.shards.Load().Owner(agentID)
+}
+
+// RedirectURL returns the URL on the owning replica that a coordination
+// request for the given agent should be sent to instead. It returns false if
+// the request should be served by this replica.
+func (c *ShardedCoordinator) RedirectURL(r *http.Request, agentID uuid.UUID) (*url.URL, bool) {
+	if r.URL.Query().Has(ShardQueryParam) {
+		return nil, false
+	}
+	owner, ok := c.Owner(agentID)
+	if !ok || owner.ID == c.self {
+		return nil, false
+	}
+	u, err := url.Parse(owner.RelayAddress)
+	if err != nil {
+		c.logger.Warn(r.Context(), "parse relay address of shard owner",
+			slog.F("owner_id", owner.ID), slog.F("relay_address", owner.RelayAddress), slog.Error(err))
+		return nil, false
+	}
+	u.Path = r.URL.Path
+	q := r.URL.Query()
+	q.Set(ShardQueryParam, owner.ID.String())
+	u.RawQuery = q.Encode()
+	return u, true
+}
+
+func (c *ShardedCoordinator) Node(id uuid.UUID) *agpl.Node {
+	if n := c.local.Node(id); n != nil {
+		return n
+	}
+	return c.ha.Node(id)
+}
+
+// ServeHTTPDebug serves the debug page of the fallback coordinator, or of the
+// in-memory shard if the "coordinator" query parameter is "local".
+func (c *ShardedCoordinator) ServeHTTPDebug(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("coordinator") == "local" {
+		c.local.ServeHTTPDebug(w, r)
+		return
+	}
+	c.ha.ServeHTTPDebug(w, r)
+}
+
+func (c *ShardedCoordinator) Close() error {
+	return errors.Join(c.local.Close(), c.ha.Close())
+}
+
+func (c *ShardedCoordinator) Coordinate(
+	ctx context.Context, id uuid.UUID, name string, a agpl.CoordinateeAuth,
+) (
+	chan<- *proto.CoordinateRequest, <-chan *proto.CoordinateResponse,
+) {
+	reqs := make(chan *proto.CoordinateRequest, agpl.RequestBufferSize)
+	resps := make(chan *proto.CoordinateResponse, agpl.ResponseBufferSize)
+	ctx, cancel := context.WithCancel(ctx)
+	p := &shardedPeer{
+		logger:     c.logger.With(slog.F("peer_id", id), slog.F("peer_name", name)),
+		ctx:        ctx,
+		cancel:     cancel,
+		coord:      c,
+		id:         id,
+		name:       name,
+		auth:       a,
+		reqs:       reqs,
+		resps:      resps,
+		tunnels:    make(map[uuid.UUID]bool),
+		lostLocal:  make(chan uuid.UUID, agpl.RequestBufferSize),
+		closed:     make(chan struct{}),
+		localPeers: make(map[uuid.UUID]struct{}),
+	}
+	localReqs, localResps := c.local.Coordinate(ctx, id, name, a)
+	p.localReqs = localReqs
+	p.forward(localResps, true)
+	if !isClientAuth(a) {
+		// Anything other than a client may be the destination of a tunnel
+		// from another replica, so it must be known to the fallback.
+		p.startHA()
+	}
+	go p.reqLoop()
+	return reqs, resps
+}
+
+// isClientAuth returns true if the peer only ever originates tunnels. Such
+// peers are only registered with the fallback coordinator once they tunnel to
+// a destination that isn't connected to this replica.
+func isClientAuth(a agpl.CoordinateeAuth) bool {
+	switch a.(type) {
+	case agpl.ClientCoordinateeAuth, agpl.ClientUserCoordinateeAuth:
+		return true
+	default:
+		return false
+	}
+}
+
+// shardedPeer splits a single peer's coordination stream between the
+// in-memory shard and the fallback coordinator.
+type shardedPeer struct {
+	logger slog.Logger
+	ctx    context.Context
+	cancel context.CancelFunc
+	coord  *ShardedCoordinator
+	id     uuid.UUID
+	name   string
+	auth   agpl.CoordinateeAuth
+	reqs   <-chan *proto.CoordinateRequest
+	resps  chan<- *proto.CoordinateResponse
+
+	// The following are only accessed by reqLoop.
+	localReqs chan<- *proto.CoordinateRequest
+	haReqs    chan<- *proto.CoordinateRequest
+	node      *proto.CoordinateRequest_UpdateSelf
+	// tunnels maps the destinations this peer has tunnels to onto whether the
+	// tunnel is coordinated by the in-memory shard.
+	tunnels map[uuid.UUID]bool
+
+	// lostLocal receives peers that disconnected from the in-memory shard.
+	lostLocal chan uuid.UUID
+	// closed is closed once either underlying coordinator closes its
+	// responses.
+	closed    chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+
+	mu sync.Mutex
+	// localPeers are the peers the in-memory shard has sent updates about.
+	localPeers map[uuid.UUID]struct{}
+}
+
+func (p *shardedPeer) startHA() {
+	reqs, resps := p.coord.ha.Coordinate(p.ctx, p.id, p.name, p.auth)
+	p.haReqs = reqs
+	p.forward(resps, false)
+}
+
+func (p *shardedPeer) forward(resps <-chan *proto.CoordinateResponse, local bool) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer p.closeOnce.Do(func() { close(p.closed) })
+		for resp := range resps {
+			if local {
+				p.observeLocal(resp)
+			}
+			if err := agpl.SendCtx(p.ctx, p.resps, resp); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+func (p *shardedPeer) observeLocal(resp *proto.CoordinateResponse) {
+	for _, update := range resp.GetPeerUpdates() {
+		id, err := uuid.FromBytes(update.GetId())
+		if err != nil {
+			continue
+		}
+		switch update.GetKind() {
+		case proto.CoordinateResponse_PeerUpdate_DISCONNECTED, proto.CoordinateResponse_PeerUpdate_LOST:
+			p.mu.Lock()
+			delete(p.localPeers, id)
+			p.mu.Unlock()
+			_ = agpl.SendCtx(p.ctx, p.lostLocal, id)
+		default:
+			p.mu.Lock()
+			p.localPeers[id] = struct{}{}
+			p.mu.Unlock()
+		}
+	}
+}
+
+func (p *shardedPeer) isLocalPeer(id uuid.UUID) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.localPeers[id]
+	return ok
+}
+
+func (p *shardedPeer) reqLoop() {
+	defer p.shutdown()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-p.closed:
+			return
+		case id := <-p.lostLocal:
+			if err := p.reroute(id); err != nil {
+				return
+			}
+		case req, ok := <-p.reqs:
+			if !ok {
+				return
+			}
+			if err := p.handleRequest(req); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// shutdown closes both underlying streams, which the coordinators treat as
+// the peer being lost unless it already disconnected gracefully, and waits for
+// their responses to be drained before closing our own.
+func (p *shardedPeer) shutdown() {
+	close(p.localReqs)
+	if p.haReqs != nil {
+		close(p.haReqs)
+	}
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	for waiting := true; waiting; {
+		select {
+		case <-done:
+			waiting = false
+		case <-p.lostLocal:
+			// Nobody reroutes anymore, but the forwarder must not block.
+		}
+	}
+	p.cancel()
+	close(p.resps)
+}
+
+func (p *shardedPeer) sendLocal(req *proto.CoordinateRequest) error {
+	return agpl.SendCtx(p.ctx, p.localReqs, req)
+}
+
+func (p *shardedPeer) sendHA(req *proto.CoordinateRequest) error {
+	if p.haReqs == nil {
+		p.startHA()
+		if p.node != nil {
+			err := agpl.SendCtx(p.ctx, p.haReqs, &proto.CoordinateRequest{UpdateSelf: p.node})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return agpl.SendCtx(p.ctx, p.haReqs, req)
+}
+
+func (p *shardedPeer) handleRequest(req *proto.CoordinateRequest) error {
+	if req.UpdateSelf != nil {
+		p.node = req.UpdateSelf
+		if err := p.sendLocal(&proto.CoordinateRequest{UpdateSelf: req.UpdateSelf}); err != nil {
+			return err
+		}
+		if p.haReqs != nil {
+			if err := agpl.SendCtx(p.ctx, p.haReqs, &proto.CoordinateRequest{UpdateSelf: req.UpdateSelf}); err != nil {
+				return err
+			}
+		}
+	}
+	if req.AddTunnel != nil {
+		out := &proto.CoordinateRequest{AddTunnel: req.AddTunnel}
+		dst, err := uuid.FromBytes(req.AddTunnel.Id)
+		if err != nil {
+			// Let the in-memory coordinator reject the request.
+			return p.sendLocal(out)
+		}
+		local := p.coord.local.Node(dst) != nil
+		p.tunnels[dst] = local
+		if local {
+			err = p.sendLocal(out)
+		} else {
+			err = p.sendHA(out)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if req.RemoveTunnel != nil {
+		out := &proto.CoordinateRequest{RemoveTunnel: req.RemoveTunnel}
+		dst, err := uuid.FromBytes(req.RemoveTunnel.Id)
+		if err != nil {
+			return p.sendLocal(out)
+		}
+		local, ok := p.tunnels[dst]
+		delete(p.tunnels, dst)
+		if !ok || local {
+			err = p.sendLocal(out)
+		} else {
+			err = p.sendHA(out)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if len(req.ReadyForHandshake) > 0 {
+		var local, ha []*proto.CoordinateRequest_ReadyForHandshake
+		for _, rfh := range req.ReadyForHandshake {
+			dst, err := uuid.FromBytes(rfh.Id)
+			if err == nil && (p.tunnels[dst] || p.isLocalPeer(dst)) {
+				local = append(local, rfh)
+			} else {
+				ha = append(ha, rfh)
+			}
+		}
+		if len(local) > 0 {
+			if err := p.sendLocal(&proto.CoordinateRequest{ReadyForHandshake: local}); err != nil {
+				return err
+			}
+		}
+		if len(ha) > 0 {
+			if err := p.sendHA(&proto.CoordinateRequest{ReadyForHandshake: ha}); err != nil {
+				return err
+			}
+		}
+	}
+	if req.Disconnect != nil {
+		if err := p.sendLocal(&proto.CoordinateRequest{Disconnect: req.Disconnect}); err != nil {
+			return err
+		}
+		if p.haReqs != nil {
+			if err := agpl.SendCtx(p.ctx, p.haReqs, &proto.CoordinateRequest{Disconnect: req.Disconnect}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// reroute moves a tunnel to the fallback coordinator after its destination
+// left the in-memory shard, so that the tunnel follows the destination to
+// whichever replica it reconnects to.
+func (p *shardedPeer) reroute(dst uuid.UUID) error {
+	if local, ok := p.tunnels[dst]; !ok || !local {
+		return nil
+	}
+	if p.coord.local.Node(dst) != nil {
+		// Already reconnected to this replica.
+		return nil
+	}
+	p.logger.Debug(p.ctx, "moving tunnel to fallback coordinator", slog.F("dst_id", dst))
+	p.tunnels[dst] = false
+	err := p.sendLocal(&proto.CoordinateRequest{RemoveTunnel: &proto.CoordinateRequest_Tunnel{Id: dst[:]}})
+	if err != nil {
+		return err
+	}
+	return p.sendHA(&proto.CoordinateRequest{AddTunnel: &proto.CoordinateRequest_Tunnel{Id: dst[:]}})
+}
@@ -0,0 +1,162 @@
+package tailnet_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/tailnet"
+	agpl "github.com/coder/coder/v2/tailnet"
+	agpltest "github.com/coder/coder/v2/tailnet/test"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestShardedCoordinator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Cases", func(t *testing.T) {
+		t.Parallel()
+		for name, test := range map[string]func(ctx context.Context, t *testing.T, coord agpl.CoordinatorV2){
+			"GracefulDisconnect":            agpltest.GracefulDisconnectTest,
+			"Lost":                          agpltest.LostTest,
+			"BidirectionalTunnels":          agpltest.BidirectionalTunnels,
+			"ReadyForHandshake":             agpltest.ReadyForHandshakeTest,
+			"ReadyForHandshakeNoPermission": agpltest.ReadyForHandshakeNoPermissionTest,
+		} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+				coord := tailnet.NewShardedCoordinator(logger, uuid.New(), agpl.NewCoordinator(logger))
+				defer coord.Close()
+				test(testutil.Context(t, testutil.WaitShort), t, coord)
+			})
+		}
+	})
+
+	t.Run("SameReplica", func(t *testing.T) {
+		t.Parallel()
+		logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ha := agpl.NewCoordinator(logger)
+		coord := tailnet.NewShardedCoordinator(logger, uuid.New(), ha)
+		defer coord.Close()
+
+		agent := agpltest.NewAgent(ctx, t, coord, "agent")
+		defer agent.Close(ctx)
+		agent.UpdateDERP(1)
+		require.Eventually(t, func() bool {
+			return coord.Node(agent.ID) != nil
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		client := agpltest.NewClient(ctx, t, coord, "client", agent.ID)
+		defer client.Close(ctx)
+		client.UpdateDERP(2)
+		client.AssertEventuallyHasDERP(agent.ID, 1)
+		agent.AssertEventuallyHasDERP(client.ID, 2)
+		// The agent is also known to the fallback, but the client never had
+		// to register with it.
+		require.NotNil(t, ha.Node(agent.ID))
+		require.Nil(t, ha.Node(client.ID))
+
+		client.Disconnect()
+		agent.AssertEventuallyDisconnected(client.ID)
+	})
+
+	t.Run("CrossReplica", func(t *testing.T) {
+		t.Parallel()
+		logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ha := agpl.NewCoordinator(logger)
+		coord1 := tailnet.NewShardedCoordinator(logger.Named("coord1"), uuid.New(), ha)
+		defer coord1.Close()
+		coord2 := tailnet.NewShardedCoordinator(logger.Named("coord2"), uuid.New(), ha)
+		defer coord2.Close()
+
+		agent := agpltest.NewAgent(ctx, t, coord1, "agent")
+		defer agent.Close(ctx)
+		agent.UpdateDERP(1)
+
+		client := agpltest.NewClient(ctx, t, coord2, "client", agent.ID)
+		defer client.Close(ctx)
+		client.UpdateDERP(2)
+		client.AssertEventuallyHasDERP(agent.ID, 1)
+		agent.AssertEventuallyHasDERP(client.ID, 2)
+
+		client.Disconnect()
+		agent.AssertEventuallyDisconnected(client.ID)
+	})
+
+	t.Run("AgentMoves", func(t *testing.T) {
+		t.Parallel()
+		logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ha := agpl.NewCoordinator(logger)
+		coord1 := tailnet.NewShardedCoordinator(logger.Named("coord1"), uuid.New(), ha)
+		defer coord1.Close()
+		coord2 := tailnet.NewShardedCoordinator(logger.Named("coord2"), uuid.New(), ha)
+		defer coord2.Close()
+
+		agent := agpltest.NewAgent(ctx, t, coord1, "agent")
+		agent.UpdateDERP(1)
+		require.Eventually(t, func() bool {
+			return coord1.Node(agent.ID) != nil
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		client := agpltest.NewClient(ctx, t, coord1, "client", agent.ID)
+		defer client.Close(ctx)
+		client.AssertEventuallyHasDERP(agent.ID, 1)
+
+		// The agent reconnects to another replica, e.g. after the shard map
+		// changed. The client's tunnel follows it.
+		agent.UngracefulDisconnect(ctx)
+		client.AssertEventuallyLost(agent.ID)
+		agent2 := agpltest.NewPeer(ctx, t, coord2, "agent",
+			agpltest.WithID(agent.ID), agpltest.WithAuth(agpl.AgentCoordinateeAuth{ID: agent.ID}))
+		defer agent2.Close(ctx)
+		agent2.UpdateDERP(3)
+		client.AssertEventuallyHasDERP(agent.ID, 3)
+	})
+}
+
+func TestShardedCoordinator_RedirectURL(t *testing.T) {
+	t.Parallel()
+	logger := slogtest.Make(t, nil)
+	self := database.Replica{ID: uuid.New(), RelayAddress: "http://10.0.0.1:8080"}
+	other := database.Replica{ID: uuid.New(), RelayAddress: "http://10.0.0.2:8080"}
+	coord := tailnet.NewShardedCoordinator(logger, self.ID, agpl.NewCoordinator(logger))
+	defer coord.Close()
+
+	// Without a shard map everything is served locally.
+	r := httptest.NewRequest(http.MethodGet, "/api/v2/workspaceagents/me/rpc?version=2.0", nil)
+	_, ok := coord.RedirectURL(r, uuid.New())
+	require.False(t, ok)
+
+	coord.SetReplicas([]database.Replica{self, other})
+	var agentID uuid.UUID
+	for {
+		agentID = uuid.New()
+		owner, ok := coord.Owner(agentID)
+		require.True(t, ok)
+		if owner.ID == other.ID {
+			break
+		}
+	}
+	u, ok := coord.RedirectURL(r, agentID)
+	require.True(t, ok)
+	require.Equal(t, "10.0.0.2:8080", u.Host)
+	require.Equal(t, "/api/v2/workspaceagents/me/rpc", u.Path)
+	require.Equal(t, "2.0", u.Query().Get("version"))
+	require.Equal(t, other.ID.String(), u.Query().Get(tailnet.ShardQueryParam))
+
+	// Requests that were already redirected are never redirected again.
+	r = httptest.NewRequest(http.MethodGet, u.RequestURI(), nil)
+	_, ok = coord.RedirectURL(r, agentID)
+	require.False(t, ok)
+}
@@ -0,0 +1,91 @@
+package tailnet
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+)
+
+// shardVirtualNodes is the number of points each replica owns on the hash
+// ring. More points spread agents more evenly at the cost of a larger ring.
+const shardVirtualNodes = 128
+
+type shardPoint struct {
+	hash    uint64
+	replica int
+}
+
+// ShardMap assigns agents to replicas using consistent hashing, so that adding
+// or removing a replica only moves the agents that hashed to it. A ShardMap is
+// immutable; build a new one whenever replica membership changes.
+type ShardMap struct {
+	replicas []database.Replica
+	points   []shardPoint
+}
+
+// NewShardMap builds a ShardMap from the given replicas. Replicas without a
+// relay address are skipped because nothing can be redirected to them.
+func NewShardMap(replicas []database.Replica) *ShardMap {
+	m := &ShardMap{}
+	for _, replica := range replicas {
+		if replica.RelayAddress == "" {
+			continue
+		}
+		m.replicas = append(m.replicas, replica)
+	}
+	// Sort so that every replica builds an identical ring regardless of the
+	// order replicasync returned its peers in.
+	slices.SortFunc(m.replicas, func(a, b database.Replica) int {
+		return slices.Compare(a.ID[:], b.ID[:])
+	})
+	m.points = make([]shardPoint, 0, len(m.replicas)*shardVirtualNodes)
+	for i, replica := range m.replicas {
+		for v := 0; v < shardVirtualNodes; v++ {
+			m.points = append(m.points, shardPoint{
+				hash:    shardHash(replica.ID, uint32(v)), // #nosec G115 - v is bounded by shardVirtualNodes
+				replica: i,
+			})
+		}
+	}
+	sort.Slice(m.points, func(i, j int) bool {
+		return m.points[i].hash < m.points[j].hash
+	})
+	return m
+}
+
+// Owner returns the replica responsible for coordinating the given agent. It
+// returns false if the map has no replicas.
+func (m *ShardMap) Owner(agentID uuid.UUID) (database.Replica, bool) {
+	if m == nil || len(m.points) == 0 {
+		return database.Replica{}, false
+	}
+	h := shardHash(agentID, 0)
+	i := sort.Search(len(m.points), func(i int) bool {
+		return m.points[i].hash >= h
+	})
+	if i == len(m.points) {
+		i = 0
+	}
+	return m.replicas[m.points[i].replica], true
+}
+
+// Replicas returns the replicas that own a shard, ordered by ID.
+func (m *ShardMap) Replicas() []database.Replica {
+	if m == nil {
+		return nil
+	}
+	return slices.Clone(m.replicas)
+}
+
+func shardHash(id uuid.UUID, salt uint32) uint64 {
+	var b [len(id) + 4]byte
+	copy(b[:], id[:])
+	binary.BigEndian.PutUint32(b[len(id):], salt)
+	sum := sha256.Sum256(b[:])
+	return binary.BigEndian.Uint64(sum[:8])
+}
@@ -0,0 +1,76 @@
+package tailnet_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/tailnet"
+)
+
+func TestShardMap(t *testing.T) {
+	t.Parallel()
+
+	replicas := func(n int) []database.Replica {
+		r := make([]database.Replica, n)
+		for i := range r {
+			r[i] = database.Replica{
+				ID:           uuid.New(),
+				RelayAddress: fmt.Sprintf("http://replica-%d:8080", i),
+			}
+		}
+		return r
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+		_, ok := tailnet.NewShardMap(nil).Owner(uuid.New())
+		require.False(t, ok)
+		// Replicas that can't be redirected to don't own a shard.
+		_, ok = tailnet.NewShardMap([]database.Replica{{ID: uuid.New()}}).Owner(uuid.New())
+		require.False(t, ok)
+	})
+
+	t.Run("Balanced", func(t *testing.T) {
+		t.Parallel()
+		m := tailnet.NewShardMap(replicas(4))
+		counts := make(map[uuid.UUID]int)
+		for i := 0; i < 4000; i++ {
+			owner, ok := m.Owner(uuid.New())
+			require.True(t, ok)
+			counts[owner.ID]++
+		}
+		require.Len(t, counts, 4)
+		for id, count := range counts {
+			assert.Greater(t, count, 600, "replica %s is underloaded", id)
+			assert.Less(t, count, 1400, "replica %s is overloaded", id)
+		}
+	})
+
+	t.Run("Stable", func(t *testing.T) {
+		t.Parallel()
+		before := replicas(3)
+		after := append(replicas(1), before...)
+		added := after[0]
+		m1 := tailnet.NewShardMap(before)
+		// Order of membership must not matter.
+		m2 := tailnet.NewShardMap([]database.Replica{after[3], after[0], after[2], after[1]})
+		moved := 0
+		for i := 0; i < 1000; i++ {
+			agentID := uuid.New()
+			o1, _ := m1.Owner(agentID)
+			o2, _ := m2.Owner(agentID)
+			if o1.ID != o2.ID {
+				// Agents only ever move to the new replica.
+				require.Equal(t, added.ID, o2.ID)
+				moved++
+			}
+		}
+		assert.Greater(t, moved, 0)
+		assert.Less(t, moved, 500)
+	})
+}
@@ -0,0 +1,107 @@
+package wsproxy
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/util/tokenfile"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/wsproxy/wsproxysdk"
+)
+
+// rotateTokenTimeout bounds the request that rotates the proxy token.
+const rotateTokenTimeout = 30 * time.Second
+
+// readTokenFile returns the token stored in the file. It returns an empty
+// string if the file does not exist yet.
+func readTokenFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if xerrors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// reloadSessionToken uses the token in the token file if it changed, so the
+// proxy picks up tokens rotated by its sibling replicas.
+func (s *Server) reloadSessionToken() {
+	if s.Options.ProxySessionTokenFile == "" {
+		return
+	}
+	token, err := readTokenFile(s.Options.ProxySessionTokenFile)
+	if err != nil {
+		s.Logger.Warn(s.ctx, "read proxy session token file", slog.Error(err))
+		return
+	}
+	if token == "" || token == s.SDKClient.SessionToken() {
+		return
+	}
+	_ = s.SDKClient.SetSessionToken(token)
+	s.Logger.Info(s.ctx, "loaded rotated proxy session token from file")
+}
+
+// rotateSessionTokenIfDue rotates the proxy token in the background when the
+// primary says it is due. Tokens are only rotated when they are stored in a
+// file, otherwise a restarted proxy would authenticate with a token that
+// expired.
+func (s *Server) rotateSessionTokenIfDue(rotation wsproxysdk.TokenRotation) {
+	if !rotation.Enabled {
+		return
+	}
+	if s.Options.ProxySessionTokenFile == "" {
+		s.tokenRotationWarnOnce.Do(func() {
+			s.Logger.Warn(s.ctx, "the primary rotates proxy tokens, but no proxy session token file is configured, so the token of this proxy is not rotated")
+		})
+		return
+	}
+	if time.Now().Before(rotation.NextRotationAt) {
+		return
+	}
+	if !s.tokenRotationMu.TryLock() {
+		return
+	}
+	go func() {
+		defer s.tokenRotationMu.Unlock()
+		err := s.rotateSessionToken()
+		if err != nil && s.ctx.Err() == nil {
+			s.Logger.Warn(s.ctx, "rotate proxy session token", slog.Error(err))
+		}
+	}()
+}
+
+func (s *Server) rotateSessionToken() error {
+	ctx, cancel := context.WithTimeout(s.ctx, rotateTokenTimeout)
+	defer cancel()
+
+	resp, err := s.SDKClient.RotateToken(ctx)
+	if err != nil {
+		var sdkErr *codersdk.Error
+		if xerrors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusConflict {
+			// A sibling replica rotated the token first.
+			s.reloadSessionToken()
+			return nil
+		}
+		return xerrors.Errorf("rotate token: %w", err)
+	}
+	// The file is written first, so a restart right after the rotation does
+	// not read a token that is about to expire.
+	err = tokenfile.Write(s.Options.ProxySessionTokenFile, resp.ProxyToken)
+	if err != nil {
+		return xerrors.Errorf("write token file: %w", err)
+	}
+	_ = s.SDKClient.SetSessionToken(resp.ProxyToken)
+	s.Logger.Info(s.ctx, "rotated proxy session token",
+		slog.F("previous_token_expires_at", resp.PreviousTokenExpiresAt),
+		slog.F("next_rotation_at", resp.NextRotationAt),
+	)
+	return nil
+}
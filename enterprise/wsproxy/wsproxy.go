@@ -22,7 +22,6 @@ import (
 	"golang.org/x/sync/singleflight"
 	"golang.org/x/xerrors"
 	"tailscale.com/derp"
-	"tailscale.com/derp/derphttp"
 	"tailscale.com/types/key"
 
 	"cdr.dev/slog"
@@ -36,11 +35,13 @@ import (
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/clientcert"
 	"github.com/coder/coder/v2/enterprise/derpmesh"
 	"github.com/coder/coder/v2/enterprise/replicasync"
 	"github.com/coder/coder/v2/enterprise/wsproxy/wsproxysdk"
 	"github.com/coder/coder/v2/site"
 	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/quartz"
 )
 
 type Options struct {
@@ -87,6 +88,11 @@ type Options struct {
 	ReplicaErrCallback func(replicas []codersdk.Replica, err string)
 
 	ProxySessionToken string
+	// ProxySessionTokenFile stores the token of the proxy. When the file is
+	// not empty its token is used instead of ProxySessionToken, and rotated
+	// tokens are written to it. Replicas that share the file pick up tokens
+	// rotated by their siblings.
+	ProxySessionTokenFile string
 	// AllowAllCors will set all CORs headers to '*'.
 	// By default, CORs is set to accept external requests
 	// from the dashboardURL. This should only be used in development.
@@ -144,6 +150,10 @@ type Server struct {
 	replicaErrMut           sync.Mutex
 	replicaErr              string
 
+	// Token rotation
+	tokenRotationMu       sync.Mutex
+	tokenRotationWarnOnce sync.Once
+
 	// Used for graceful shutdown. Required for the dialer.
 	ctx           context.Context
 	cancel        context.CancelFunc
@@ -163,8 +173,19 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 		return nil, err
 	}
 
+	token := opts.ProxySessionToken
+	if opts.ProxySessionTokenFile != "" {
+		fileToken, err := readTokenFile(opts.ProxySessionTokenFile)
+		if err != nil {
+			return nil, xerrors.Errorf("read proxy session token file: %w", err)
+		}
+		if fileToken != "" {
+			token = fileToken
+		}
+	}
+
 	client := wsproxysdk.New(opts.DashboardURL)
-	err := client.SetSessionToken(opts.ProxySessionToken)
+	err := client.SetSessionToken(token)
 	if err != nil {
 		return nil, xerrors.Errorf("set client token: %w", err)
 	}
@@ -174,6 +195,14 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 		client.SDKClient.HTTPClient = opts.HTTPClient
 	}
 
+	// Present a client certificate from the internal CA of the primary, if
+	// it has one enabled. The client is copied so a configured client is
+	// not modified.
+	certRenewer := clientcert.New(opts.Logger, client.IssueCertificate)
+	httpClient := *client.SDKClient.HTTPClient
+	httpClient.Transport = certRenewer.Transport(httpClient.Transport)
+	client.SDKClient.HTTPClient = &httpClient
+
 	info, err := client.SDKClient.BuildInfo(ctx)
 	if err != nil {
 		return nil, xerrors.Errorf("buildinfo: %w", errors.Join(
@@ -202,6 +231,10 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 
+	// The certificate is requested before any endpoint that requires it
+	// when mTLS is enforced.
+	certRenewer.Start(ctx)
+
 	encryptionCache, err := cryptokeys.NewEncryptionCache(ctx,
 		opts.Logger,
 		&ProxyFetcher{Client: client},
@@ -311,16 +344,21 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 			Logger:                   s.Logger.Named("proxy_token_provider"),
 		},
 
-		DisablePathApps: opts.DisablePathApps,
-		Cookies:         opts.CookieConfig,
+		DisablePathApps:        opts.DisablePathApps,
+		Cookies:                opts.CookieConfig,
+		DesktopClipboardPolicy: regResp.WebDesktopClipboard,
 
 		AgentProvider:            agentProvider,
 		StatsCollector:           workspaceapps.NewStatsCollector(opts.StatsCollectorOptions),
 		APIKeyEncryptionKeycache: encryptionCache,
 	}
 
-	derpHandler := derphttp.Handler(derpServer)
-	derpHandler, s.derpCloseFunc = tailnet.WithWebsocketSupport(derpServer, derpHandler)
+	derpServerMetrics := tailnet.NewDERPServerMetrics(derpServer, quartz.NewReal())
+	if err := s.PrometheusRegistry.Register(derpServerMetrics); err != nil {
+		s.Logger.Warn(ctx, "failed to register DERP server metrics", slog.Error(err))
+	}
+	derpHandler, derpCloseFunc := derpServerMetrics.Handler()
+	s.derpCloseFunc = derpCloseFunc
 
 	// The primary coderd dashboard needs to make some GET requests to
 	// the workspace proxies to check latency.
@@ -467,6 +505,8 @@ func (s *Server) Close() error {
 }
 
 func (s *Server) mutateRegister(req *wsproxysdk.RegisterWorkspaceProxyRequest) {
+	s.reloadSessionToken()
+
 	s.replicaErrMut.Lock()
 	defer s.replicaErrMut.Unlock()
 	req.ReplicaError = s.replicaErr
@@ -481,6 +521,7 @@ func (s *Server) handleRegister(res wsproxysdk.RegisterWorkspaceProxyResponse) e
 	s.derpMesh.SetAddresses(addresses, false)
 
 	go s.pingSiblingReplicas(res.SiblingReplicas)
+	s.rotateSessionTokenIfDue(res.TokenRotation)
 	return nil
 }
 
@@ -210,6 +210,19 @@ type RegisterWorkspaceProxyResponse struct {
 	// SiblingReplicas is a list of all other replicas of the proxy that have
 	// not timed out.
 	SiblingReplicas []codersdk.Replica `json:"sibling_replicas"`
+	// TokenRotation is when the proxy should rotate its token next.
+	TokenRotation TokenRotation `json:"token_rotation"`
+	// WebDesktopClipboard is the clipboard policy the proxy enforces on web
+	// desktop sessions.
+	WebDesktopClipboard codersdk.DesktopClipboardPolicy `json:"web_desktop_clipboard"`
+}
+
+// TokenRotation describes when the proxy should exchange its token for a
+// fresh one.
+type TokenRotation struct {
+	// Enabled is false when the deployment does not rotate proxy tokens.
+	Enabled        bool      `json:"enabled"`
+	NextRotationAt time.Time `json:"next_rotation_at" format:"date-time"`
 }
 
 func (c *Client) RegisterWorkspaceProxy(ctx context.Context, req RegisterWorkspaceProxyRequest) (RegisterWorkspaceProxyResponse, error) {
@@ -229,6 +242,35 @@ func (c *Client) RegisterWorkspaceProxy(ctx context.Context, req RegisterWorkspa
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+type RotateTokenResponse struct {
+	// ProxyToken is the new token of the proxy, in the same format as the
+	// token returned when the proxy was created.
+	ProxyToken string `json:"proxy_token"`
+	// PreviousTokenExpiresAt is when the token used to make the request
+	// stops being accepted.
+	PreviousTokenExpiresAt time.Time `json:"previous_token_expires_at" format:"date-time"`
+	NextRotationAt         time.Time `json:"next_rotation_at" format:"date-time"`
+}
+
+// RotateToken exchanges the session token of the client for a fresh one.
+// The caller is responsible for using and storing the returned token.
+func (c *Client) RotateToken(ctx context.Context) (RotateTokenResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		"/api/v2/workspaceproxies/me/token-rotation",
+		nil,
+	)
+	if err != nil {
+		return RotateTokenResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return RotateTokenResponse{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp RotateTokenResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 type DeregisterWorkspaceProxyRequest struct {
 	// ReplicaID is a unique identifier for the replica of the proxy that is
 	// deregistering. It should be generated by the client on startup and
@@ -261,6 +303,9 @@ type RegisterWorkspaceProxyLoopOpts struct {
 	Interval time.Duration
 	// MaxFailureCount is the maximum amount of attempts that the loop will
 	// retry registration before giving up. Defaults to 10 (for ~5 minutes).
+	// Attempts that fail because the server could not be reached or returned
+	// a 5xx status, such as while coderd restarts, are retried indefinitely
+	// and do not count towards this limit.
 	MaxFailureCount int
 	// AttemptTimeout is the maximum amount of time that the loop will wait for
 	// a response from the server before considering the attempt a failure.
@@ -352,6 +397,16 @@ func (l *RegisterWorkspaceProxyLoop) Start(ctx context.Context) (RegisterWorkspa
 			l.mutateFn(&l.opts.Request)
 			resp, err := l.register(l.closedCtx)
 			if err != nil {
+				if l.closedCtx.Err() == nil && isTransientRegisterError(err) {
+					l.opts.Logger.Warn(context.Background(),
+						"Coder primary is unavailable, retrying workspace proxy registration",
+						slog.F("timeout", l.opts.AttemptTimeout),
+						slog.F("interval", l.opts.Interval),
+						slog.Error(err),
+					)
+					continue
+				}
+
 				failedAttempts++
 				l.opts.Logger.Warn(context.Background(),
 					"failed to re-register workspace proxy with Coder primary",
@@ -397,6 +452,18 @@ func (l *RegisterWorkspaceProxyLoop) Start(ctx context.Context) (RegisterWorkspa
 	return originalRes, nil
 }
 
+// isTransientRegisterError returns whether a registration attempt failed
+// because coderd is unavailable rather than because it refused the proxy.
+func isTransientRegisterError(err error) bool {
+	var sdkErr *codersdk.Error
+	if !xerrors.As(err, &sdkErr) {
+		// The request never got a response, e.g. the connection was refused
+		// or timed out.
+		return true
+	}
+	return sdkErr.StatusCode() >= http.StatusInternalServerError
+}
+
 // RegisterNow asks the registration loop to register immediately. A timeout of
 // 2x the attempt timeout is used to wait for the response.
 func (l *RegisterWorkspaceProxyLoop) RegisterNow() (RegisterWorkspaceProxyResponse, error) {
@@ -506,19 +573,41 @@ func (c *Client) TailnetDialer() (*workspacesdk.WebsocketDialer, error) {
 	if err != nil {
 		return nil, xerrors.Errorf("parse url: %w", err)
 	}
-	coordinateHeaders := make(http.Header)
 	tokenHeader := codersdk.SessionTokenHeader
 	if c.SDKClient.SessionTokenHeader != "" {
 		tokenHeader = c.SDKClient.SessionTokenHeader
 	}
-	coordinateHeaders.Set(tokenHeader, c.SessionToken())
+	// The token is set on every dial instead of once, since it changes when
+	// the proxy rotates its token.
+	httpClient := *c.SDKClient.HTTPClient
+	httpClient.Transport = &sessionTokenTransport{
+		header:    tokenHeader,
+		token:     c.SessionToken,
+		transport: httpClient.Transport,
+	}
 
 	return workspacesdk.NewWebsocketDialer(logger, coordinateURL, &websocket.DialOptions{
-		HTTPClient: c.SDKClient.HTTPClient,
-		HTTPHeader: coordinateHeaders,
+		HTTPClient: &httpClient,
 	}), nil
 }
 
+// sessionTokenTransport sets the current session token of the client on
+// every request.
+type sessionTokenTransport struct {
+	header    string
+	token     func() string
+	transport http.RoundTripper
+}
+
+func (t *sessionTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(t.header, t.token())
+	if t.transport == nil {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	return t.transport.RoundTrip(req)
+}
+
 type CryptoKeysResponse struct {
 	CryptoKeys []codersdk.CryptoKey `json:"crypto_keys"`
 }
@@ -539,3 +628,21 @@ func (c *Client) CryptoKeys(ctx context.Context, feature codersdk.CryptoKeyFeatu
 	var resp CryptoKeysResponse
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
+
+// IssueCertificate requests a client certificate for the proxy from the
+// internal CA of the primary.
+func (c *Client) IssueCertificate(ctx context.Context, req codersdk.IssueCertificateRequest) (codersdk.IssuedCertificate, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		"/api/v2/workspaceproxies/me/certificate", req,
+	)
+	if err != nil {
+		return codersdk.IssuedCertificate{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return codersdk.IssuedCertificate{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp codersdk.IssuedCertificate
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
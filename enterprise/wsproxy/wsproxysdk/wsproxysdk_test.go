@@ -136,6 +136,96 @@ func Test_IssueSignedAppTokenHTML(t *testing.T) {
 	})
 }
 
+func Test_RegisterWorkspaceProxyLoop(t *testing.T) {
+	t.Parallel()
+
+	// newServer returns a server that answers registrations with the given
+	// status codes in order, and then with 201.
+	newServer := func(t *testing.T, statuses ...int) *wsproxysdk.Client {
+		var called int64
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v2/workspaceproxies/me/register" {
+				rw.WriteHeader(http.StatusNoContent)
+				return
+			}
+			n := atomic.AddInt64(&called, 1)
+			if n > 1 && int(n-2) < len(statuses) {
+				rw.WriteHeader(statuses[n-2])
+				return
+			}
+			rw.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(rw).Encode(wsproxysdk.RegisterWorkspaceProxyResponse{
+				DERPMeshKey:  "key",
+				DERPRegionID: 10000,
+			})
+		}))
+		t.Cleanup(srv.Close)
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		return wsproxysdk.New(u)
+	}
+
+	t.Run("RetriesUnavailable", func(t *testing.T) {
+		t.Parallel()
+
+		statuses := make([]int, 5)
+		for i := range statuses {
+			statuses[i] = http.StatusServiceUnavailable
+		}
+		client := newServer(t, statuses...)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		registered := make(chan struct{}, 1)
+		failed := make(chan error, 1)
+		loop, _, err := client.RegisterWorkspaceProxyLoop(ctx, wsproxysdk.RegisterWorkspaceProxyLoopOpts{
+			Logger:          testutil.Logger(t),
+			Interval:        testutil.IntervalFast,
+			MaxFailureCount: 1,
+			CallbackFn: func(wsproxysdk.RegisterWorkspaceProxyResponse) error {
+				select {
+				case registered <- struct{}{}:
+				default:
+				}
+				return nil
+			},
+			FailureFn: func(err error) {
+				failed <- err
+			},
+		})
+		require.NoError(t, err)
+		defer loop.Close()
+
+		testutil.TryReceive(ctx, t, registered)
+		select {
+		case err := <-failed:
+			t.Fatalf("loop failed: %v", err)
+		default:
+		}
+	})
+
+	t.Run("GivesUpUnauthorized", func(t *testing.T) {
+		t.Parallel()
+
+		client := newServer(t, http.StatusUnauthorized, http.StatusUnauthorized)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		failed := make(chan error, 1)
+		loop, _, err := client.RegisterWorkspaceProxyLoop(ctx, wsproxysdk.RegisterWorkspaceProxyLoopOpts{
+			Logger:          testutil.Logger(t),
+			Interval:        testutil.IntervalFast,
+			MaxFailureCount: 1,
+			FailureFn: func(err error) {
+				failed <- err
+			},
+		})
+		require.NoError(t, err)
+		defer loop.Close()
+
+		err = testutil.TryReceive(ctx, t, failed)
+		require.ErrorContains(t, err, "exceeded re-registration failure count")
+	})
+}
+
 type ResponseRecorder struct {
 	rw         *httptest.ResponseRecorder
 	wasWritten atomic.Bool
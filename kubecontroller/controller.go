@@ -0,0 +1,345 @@
+// Package kubecontroller reconciles Workspace custom resources in a
+// Kubernetes cluster against the Coder API, so workspaces can be managed
+// declaratively, e.g. with Argo CD.
+package kubecontroller
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/quartz"
+)
+
+// DefaultResyncInterval is how often all resources are reconciled, even if
+// none of them changed. Builds finish without changing the resource, so this
+// is also how long it takes for their result to show in the status.
+const DefaultResyncInterval = 30 * time.Second
+
+// Kube is the subset of the Kubernetes API the controller uses.
+type Kube interface {
+	List(ctx context.Context) ([]Workspace, string, error)
+	Watch(ctx context.Context, resourceVersion string, timeout time.Duration, fn func(WatchEvent)) error
+	SetFinalizers(ctx context.Context, ws Workspace, finalizers []string) (Workspace, error)
+	UpdateStatus(ctx context.Context, ws Workspace, status WorkspaceStatus) (Workspace, error)
+}
+
+type Options struct {
+	Logger slog.Logger
+	Clock  quartz.Clock
+	// Client is authenticated as the user that manages the workspaces. It
+	// needs permission to create workspaces for the owners in the resources.
+	Client         *codersdk.Client
+	Kube           Kube
+	ResyncInterval time.Duration
+}
+
+// Controller reconciles Workspace resources.
+type Controller struct {
+	logger slog.Logger
+	clock  quartz.Clock
+	client *codersdk.Client
+	kube   Kube
+	resync time.Duration
+}
+
+func New(opts Options) (*Controller, error) {
+	if opts.Client == nil {
+		return nil, xerrors.New("client is required")
+	}
+	if opts.Kube == nil {
+		return nil, xerrors.New("kube is required")
+	}
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+	if opts.ResyncInterval <= 0 {
+		opts.ResyncInterval = DefaultResyncInterval
+	}
+	return &Controller{
+		logger: opts.Logger,
+		clock:  opts.Clock,
+		client: opts.Client,
+		kube:   opts.Kube,
+		resync: opts.ResyncInterval,
+	}, nil
+}
+
+// Run reconciles all resources, then every resource that changes, until ctx
+// is canceled. Everything is reconciled again every resync interval.
+func (c *Controller) Run(ctx context.Context) error {
+	for {
+		err := c.runOnce(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			c.logger.Warn(ctx, "reconcile workspaces", slog.Error(err))
+			timer := c.clock.NewTimer(c.resync, "kubecontroller", "backoff")
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+func (c *Controller) runOnce(ctx context.Context) error {
+	items, resourceVersion, err := c.kube.List(ctx)
+	if err != nil {
+		return xerrors.Errorf("list workspaces: %w", err)
+	}
+	for _, item := range items {
+		c.Reconcile(ctx, item)
+	}
+	// The watch ends after the resync interval, so the next iteration
+	// lists and reconciles everything again.
+	return c.kube.Watch(ctx, resourceVersion, c.resync, func(event WatchEvent) {
+		switch event.Type {
+		case "ADDED", "MODIFIED":
+			c.Reconcile(ctx, event.Object)
+		}
+	})
+}
+
+// Reconcile brings the workspace in Coder in line with the resource. Errors
+// are logged and recorded in the status of the resource.
+func (c *Controller) Reconcile(ctx context.Context, ws Workspace) {
+	logger := c.logger.With(
+		slog.F("namespace", ws.Metadata.Namespace),
+		slog.F("name", ws.Metadata.Name),
+	)
+	status, err := c.reconcile(ctx, &ws)
+	if err != nil {
+		if IsConflict(err) {
+			// The resource changed, the watch delivers the new version.
+			return
+		}
+		logger.Warn(ctx, "reconcile workspace", slog.Error(err))
+		status.Message = err.Error()
+	}
+	if ws.Metadata.DeletionTimestamp != nil && !ws.HasFinalizer() {
+		// The resource is gone.
+		return
+	}
+	status.ObservedGeneration = ws.Metadata.Generation
+	if status == ws.Status {
+		return
+	}
+	_, err = c.kube.UpdateStatus(ctx, ws, status)
+	if err != nil && !IsNotFound(err) {
+		logger.Warn(ctx, "update workspace status", slog.Error(err))
+	}
+}
+
+func (c *Controller) reconcile(ctx context.Context, ws *Workspace) (WorkspaceStatus, error) {
+	status := ws.Status
+	status.Message = ""
+
+	if ws.Metadata.DeletionTimestamp == nil && !ws.HasFinalizer() {
+		updated, err := c.kube.SetFinalizers(ctx, *ws, append(slices.Clone(ws.Metadata.Finalizers), Finalizer))
+		if err != nil {
+			return status, xerrors.Errorf("add finalizer: %w", err)
+		}
+		*ws = updated
+	}
+
+	workspace, err := c.client.WorkspaceByOwnerAndName(ctx, ws.WorkspaceOwner(), ws.WorkspaceName(), codersdk.WorkspaceOptions{})
+	var existing *codersdk.Workspace
+	if err == nil {
+		existing = &workspace
+		status.WorkspaceID = workspace.ID.String()
+		status.Status = string(workspace.LatestBuild.Status)
+		status.TemplateVersion = workspace.LatestBuild.TemplateVersionName
+	} else if !isNotFound(err) {
+		return status, xerrors.Errorf("get workspace: %w", err)
+	}
+
+	var versionID uuid.UUID
+	if existing != nil && ws.Spec.TemplateVersion != "" && ws.Metadata.DeletionTimestamp == nil {
+		version, err := c.client.TemplateVersionByName(ctx, existing.TemplateID, ws.Spec.TemplateVersion)
+		if err != nil {
+			return status, xerrors.Errorf("get template version %q: %w", ws.Spec.TemplateVersion, err)
+		}
+		versionID = version.ID
+	}
+
+	switch action := nextAction(*ws, existing, versionID); action {
+	case actionNone:
+		if existing != nil && existing.LatestBuild.Job.Status == codersdk.ProvisionerJobFailed {
+			status.Message = existing.LatestBuild.Job.Error
+		}
+		return status, nil
+	case actionCreate:
+		created, err := c.create(ctx, *ws)
+		if err != nil {
+			return status, err
+		}
+		status.WorkspaceID = created.ID.String()
+		status.Status = string(created.LatestBuild.Status)
+		status.TemplateVersion = created.LatestBuild.TemplateVersionName
+		return status, nil
+	case actionRemoveFinalizer:
+		finalizers := slices.DeleteFunc(slices.Clone(ws.Metadata.Finalizers), func(f string) bool {
+			return f == Finalizer
+		})
+		updated, err := c.kube.SetFinalizers(ctx, *ws, finalizers)
+		if err != nil {
+			return status, xerrors.Errorf("remove finalizer: %w", err)
+		}
+		*ws = updated
+		status.Status = string(codersdk.WorkspaceStatusDeleted)
+		return status, nil
+	default:
+		transition := codersdk.WorkspaceTransition(action)
+		req := codersdk.CreateWorkspaceBuildRequest{
+			Transition: transition,
+		}
+		if transition == codersdk.WorkspaceTransitionStart {
+			req.TemplateVersionID = versionID
+			req.RichParameterValues = buildParameters(ws.Spec.Parameters)
+		}
+		build, err := c.client.CreateWorkspaceBuild(ctx, existing.ID, req)
+		if err != nil {
+			return status, xerrors.Errorf("%s workspace: %w", transition, err)
+		}
+		status.Status = string(build.Status)
+		status.TemplateVersion = build.TemplateVersionName
+		return status, nil
+	}
+}
+
+func (c *Controller) create(ctx context.Context, ws Workspace) (codersdk.Workspace, error) {
+	org, err := c.organization(ctx, ws.Spec.Organization)
+	if err != nil {
+		return codersdk.Workspace{}, err
+	}
+	template, err := c.client.TemplateByName(ctx, org.ID, ws.Spec.Template)
+	if err != nil {
+		return codersdk.Workspace{}, xerrors.Errorf("get template %q: %w", ws.Spec.Template, err)
+	}
+	req := codersdk.CreateWorkspaceRequest{
+		TemplateID:          template.ID,
+		Name:                ws.WorkspaceName(),
+		RichParameterValues: buildParameters(ws.Spec.Parameters),
+	}
+	if ws.Spec.TemplateVersion != "" {
+		version, err := c.client.TemplateVersionByName(ctx, template.ID, ws.Spec.TemplateVersion)
+		if err != nil {
+			return codersdk.Workspace{}, xerrors.Errorf("get template version %q: %w", ws.Spec.TemplateVersion, err)
+		}
+		req.TemplateID = uuid.Nil
+		req.TemplateVersionID = version.ID
+	}
+	workspace, err := c.client.CreateUserWorkspace(ctx, ws.WorkspaceOwner(), req)
+	if err != nil {
+		return codersdk.Workspace{}, xerrors.Errorf("create workspace: %w", err)
+	}
+	c.logger.Info(ctx, "created workspace",
+		slog.F("namespace", ws.Metadata.Namespace),
+		slog.F("name", ws.Metadata.Name),
+		slog.F("workspace_id", workspace.ID),
+	)
+	return workspace, nil
+}
+
+func (c *Controller) organization(ctx context.Context, name string) (codersdk.Organization, error) {
+	if name != "" {
+		org, err := c.client.OrganizationByName(ctx, name)
+		if err != nil {
+			return codersdk.Organization{}, xerrors.Errorf("get organization %q: %w", name, err)
+		}
+		return org, nil
+	}
+	orgs, err := c.client.Organizations(ctx)
+	if err != nil {
+		return codersdk.Organization{}, xerrors.Errorf("list organizations: %w", err)
+	}
+	for _, org := range orgs {
+		if org.IsDefault {
+			return org, nil
+		}
+	}
+	return codersdk.Organization{}, xerrors.New("no default organization, set spec.organization")
+}
+
+type action string
+
+const (
+	actionNone            action = ""
+	actionCreate          action = "create"
+	actionRemoveFinalizer action = "remove-finalizer"
+	actionStart                  = action(codersdk.WorkspaceTransitionStart)
+	actionStop                   = action(codersdk.WorkspaceTransitionStop)
+	actionDelete                 = action(codersdk.WorkspaceTransitionDelete)
+)
+
+// nextAction returns what needs to happen for the workspace to match the
+// resource. workspace is nil if it does not exist. versionID is the template
+// version the resource asks for, or uuid.Nil if it does not ask for one.
+func nextAction(ws Workspace, workspace *codersdk.Workspace, versionID uuid.UUID) action {
+	if ws.Metadata.DeletionTimestamp != nil {
+		if !ws.HasFinalizer() {
+			return actionNone
+		}
+		if workspace == nil {
+			return actionRemoveFinalizer
+		}
+	}
+	if workspace == nil {
+		return actionCreate
+	}
+
+	latest := workspace.LatestBuild
+	switch latest.Job.Status {
+	case codersdk.ProvisionerJobPending, codersdk.ProvisionerJobRunning, codersdk.ProvisionerJobCanceling:
+		// Wait for the build, the next resync looks again.
+		return actionNone
+	}
+
+	want := actionStop
+	switch {
+	case ws.Metadata.DeletionTimestamp != nil:
+		want = actionDelete
+	case ws.ShouldRun():
+		want = actionStart
+	}
+	if action(latest.Transition) != want {
+		return want
+	}
+	if want == actionStart && versionID != uuid.Nil && latest.TemplateVersionID != versionID {
+		return actionStart
+	}
+	// The latest build already has the wanted transition. If it failed it
+	// is not retried, which would build in a loop. Changing spec.running or
+	// spec.templateVersion builds again.
+	return actionNone
+}
+
+func buildParameters(values map[string]string) []codersdk.WorkspaceBuildParameter {
+	if len(values) == 0 {
+		return nil
+	}
+	params := make([]codersdk.WorkspaceBuildParameter, 0, len(values))
+	for name, value := range values {
+		params = append(params, codersdk.WorkspaceBuildParameter{Name: name, Value: value})
+	}
+	sort.Slice(params, func(i, j int) bool {
+		return params[i].Name < params[j].Name
+	})
+	return params
+}
+
+func isNotFound(err error) bool {
+	var sdkErr *codersdk.Error
+	return xerrors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound
+}
@@ -0,0 +1,213 @@
+package kubecontroller
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestNextAction(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	versionID := uuid.New()
+	resource := func(running bool, deleting bool, finalizer bool) Workspace {
+		ws := Workspace{
+			Metadata: ObjectMeta{Name: "dev"},
+			Spec:     WorkspaceSpec{Template: "docker", Running: &running},
+		}
+		if deleting {
+			ws.Metadata.DeletionTimestamp = &now
+		}
+		if finalizer {
+			ws.Metadata.Finalizers = []string{Finalizer}
+		}
+		return ws
+	}
+	workspace := func(transition codersdk.WorkspaceTransition, status codersdk.ProvisionerJobStatus) *codersdk.Workspace {
+		return &codersdk.Workspace{
+			LatestBuild: codersdk.WorkspaceBuild{
+				Transition:        transition,
+				TemplateVersionID: versionID,
+				Job:               codersdk.ProvisionerJob{Status: status},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		resource  Workspace
+		workspace *codersdk.Workspace
+		versionID uuid.UUID
+		expected  action
+	}{
+		{
+			name:     "Create",
+			resource: resource(true, false, true),
+			expected: actionCreate,
+		},
+		{
+			name:      "Running",
+			resource:  resource(true, false, true),
+			workspace: workspace(codersdk.WorkspaceTransitionStart, codersdk.ProvisionerJobSucceeded),
+			expected:  actionNone,
+		},
+		{
+			name:      "Stop",
+			resource:  resource(false, false, true),
+			workspace: workspace(codersdk.WorkspaceTransitionStart, codersdk.ProvisionerJobSucceeded),
+			expected:  actionStop,
+		},
+		{
+			name:      "Start",
+			resource:  resource(true, false, true),
+			workspace: workspace(codersdk.WorkspaceTransitionStop, codersdk.ProvisionerJobSucceeded),
+			expected:  actionStart,
+		},
+		{
+			name:      "WaitForBuild",
+			resource:  resource(false, false, true),
+			workspace: workspace(codersdk.WorkspaceTransitionStart, codersdk.ProvisionerJobRunning),
+			expected:  actionNone,
+		},
+		{
+			name:      "NoRetry",
+			resource:  resource(true, false, true),
+			workspace: workspace(codersdk.WorkspaceTransitionStart, codersdk.ProvisionerJobFailed),
+			expected:  actionNone,
+		},
+		{
+			name:      "Update",
+			resource:  resource(true, false, true),
+			workspace: workspace(codersdk.WorkspaceTransitionStart, codersdk.ProvisionerJobSucceeded),
+			versionID: uuid.New(),
+			expected:  actionStart,
+		},
+		{
+			name:      "SameVersion",
+			resource:  resource(true, false, true),
+			workspace: workspace(codersdk.WorkspaceTransitionStart, codersdk.ProvisionerJobSucceeded),
+			versionID: versionID,
+			expected:  actionNone,
+		},
+		{
+			name:      "Delete",
+			resource:  resource(true, true, true),
+			workspace: workspace(codersdk.WorkspaceTransitionStart, codersdk.ProvisionerJobSucceeded),
+			expected:  actionDelete,
+		},
+		{
+			name:      "Deleting",
+			resource:  resource(true, true, true),
+			workspace: workspace(codersdk.WorkspaceTransitionDelete, codersdk.ProvisionerJobPending),
+			expected:  actionNone,
+		},
+		{
+			name:     "Deleted",
+			resource: resource(true, true, true),
+			expected: actionRemoveFinalizer,
+		},
+		{
+			name:     "Gone",
+			resource: resource(true, true, false),
+			expected: actionNone,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, nextAction(tc.resource, tc.workspace, tc.versionID))
+		})
+	}
+}
+
+func TestKubeClient(t *testing.T) {
+	t.Parallel()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o600))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Query().Get("watch") == "true":
+			require.Equal(t, "/apis/coder.com/v1alpha1/namespaces/coder/workspaces", r.URL.Path)
+			require.Equal(t, "5", r.URL.Query().Get("resourceVersion"))
+			enc := json.NewEncoder(rw)
+			_ = enc.Encode(WatchEvent{Type: "MODIFIED", Object: Workspace{Metadata: ObjectMeta{Name: "dev", ResourceVersion: "6"}}})
+			_ = enc.Encode(WatchEvent{Type: "DELETED", Object: Workspace{Metadata: ObjectMeta{Name: "dev", ResourceVersion: "7"}}})
+		case r.Method == http.MethodGet:
+			require.Equal(t, "/apis/coder.com/v1alpha1/namespaces/coder/workspaces", r.URL.Path)
+			_, _ = io.WriteString(rw, `{"metadata":{"resourceVersion":"5"},"items":[{"metadata":{"name":"dev","namespace":"coder","resourceVersion":"4"},"spec":{"template":"docker"}}]}`)
+		case r.Method == http.MethodPatch && r.URL.Path == "/apis/coder.com/v1alpha1/namespaces/coder/workspaces/dev":
+			require.Equal(t, "application/merge-patch+json", r.Header.Get("Content-Type"))
+			var patch struct {
+				Metadata ObjectMeta `json:"metadata"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&patch))
+			if patch.Metadata.ResourceVersion != "4" {
+				rw.WriteHeader(http.StatusConflict)
+				_, _ = io.WriteString(rw, `{"message":"the object has been modified"}`)
+				return
+			}
+			_ = json.NewEncoder(rw).Encode(Workspace{Metadata: ObjectMeta{Name: "dev", Namespace: "coder", ResourceVersion: "8", Finalizers: patch.Metadata.Finalizers}})
+		case r.Method == http.MethodPatch && r.URL.Path == "/apis/coder.com/v1alpha1/namespaces/coder/workspaces/dev/status":
+			var patch Workspace
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&patch))
+			_ = json.NewEncoder(rw).Encode(Workspace{Metadata: ObjectMeta{Name: "dev"}, Status: patch.Status})
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewKubeClient(KubeConfig{
+		Host:      srv.URL,
+		TokenPath: tokenFile,
+		Namespace: "coder",
+	})
+	require.NoError(t, err)
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	items, resourceVersion, err := client.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "5", resourceVersion)
+	require.Len(t, items, 1)
+	require.Equal(t, "dev", items[0].WorkspaceName())
+	require.Equal(t, "me", items[0].WorkspaceOwner())
+	require.True(t, items[0].ShouldRun())
+
+	var events []WatchEvent
+	err = client.Watch(ctx, resourceVersion, time.Minute, func(event WatchEvent) {
+		events = append(events, event)
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, "MODIFIED", events[0].Type)
+	require.Equal(t, "DELETED", events[1].Type)
+
+	updated, err := client.SetFinalizers(ctx, items[0], []string{Finalizer})
+	require.NoError(t, err)
+	require.True(t, updated.HasFinalizer())
+
+	// The resource version changed, so the stale copy conflicts.
+	_, err = client.SetFinalizers(ctx, updated, nil)
+	require.True(t, IsConflict(err))
+
+	updated, err = client.UpdateStatus(ctx, updated, WorkspaceStatus{Status: "running"})
+	require.NoError(t, err)
+	require.Equal(t, "running", updated.Status.Status)
+}
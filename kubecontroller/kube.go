@@ -0,0 +1,368 @@
+package kubecontroller
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// Group and Version of the Workspace custom resource.
+	Group   = "coder.com"
+	Version = "v1alpha1"
+	// Finalizer keeps Workspace resources around until their workspace was
+	// deleted in Coder.
+	Finalizer = "coder.com/workspace"
+
+	// DefaultTokenPath, DefaultCAPath and DefaultNamespacePath are where
+	// Kubernetes mounts the service account of a pod.
+	DefaultTokenPath     = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	DefaultCAPath        = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+	DefaultNamespacePath = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// Workspace is the custom resource that declares a Coder workspace.
+type Workspace struct {
+	APIVersion string          `json:"apiVersion,omitempty"`
+	Kind       string          `json:"kind,omitempty"`
+	Metadata   ObjectMeta      `json:"metadata"`
+	Spec       WorkspaceSpec   `json:"spec"`
+	Status     WorkspaceStatus `json:"status,omitempty"`
+}
+
+// ObjectMeta is the subset of the Kubernetes object metadata the controller
+// uses.
+type ObjectMeta struct {
+	Name              string     `json:"name"`
+	Namespace         string     `json:"namespace,omitempty"`
+	UID               string     `json:"uid,omitempty"`
+	ResourceVersion   string     `json:"resourceVersion,omitempty"`
+	Generation        int64      `json:"generation,omitempty"`
+	DeletionTimestamp *time.Time `json:"deletionTimestamp,omitempty"`
+	Finalizers        []string   `json:"finalizers,omitempty"`
+}
+
+// WorkspaceSpec is the desired state of a workspace.
+type WorkspaceSpec struct {
+	// Owner is the username of the workspace owner. It defaults to the user
+	// the controller authenticates as.
+	Owner string `json:"owner,omitempty"`
+	// Organization is the name of the organization of the template. It
+	// defaults to the default organization.
+	Organization string `json:"organization,omitempty"`
+	Template     string `json:"template"`
+	// TemplateVersion is the name of the template version to build. It
+	// defaults to the active version.
+	TemplateVersion string `json:"templateVersion,omitempty"`
+	// Name is the name of the workspace. It defaults to the name of the
+	// resource.
+	Name string `json:"name,omitempty"`
+	// Running is whether the workspace should be started or stopped. It
+	// defaults to true.
+	Running    *bool             `json:"running,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// WorkspaceStatus is the observed state of a workspace.
+type WorkspaceStatus struct {
+	WorkspaceID        string `json:"workspaceID,omitempty"`
+	Status             string `json:"status,omitempty"`
+	TemplateVersion    string `json:"templateVersion,omitempty"`
+	ObservedGeneration int64  `json:"observedGeneration,omitempty"`
+	Message            string `json:"message,omitempty"`
+}
+
+// WorkspaceName returns the name of the workspace in Coder.
+func (w Workspace) WorkspaceName() string {
+	if w.Spec.Name != "" {
+		return w.Spec.Name
+	}
+	return w.Metadata.Name
+}
+
+// WorkspaceOwner returns the owner of the workspace in Coder.
+func (w Workspace) WorkspaceOwner() string {
+	if w.Spec.Owner != "" {
+		return w.Spec.Owner
+	}
+	return "me"
+}
+
+// ShouldRun returns whether the workspace should be running.
+func (w Workspace) ShouldRun() bool {
+	return w.Spec.Running == nil || *w.Spec.Running
+}
+
+// HasFinalizer returns whether the controller finalizer is set.
+func (w Workspace) HasFinalizer() bool {
+	for _, f := range w.Metadata.Finalizers {
+		if f == Finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// WatchEvent is an event of a watch of Workspace resources.
+type WatchEvent struct {
+	// Type is ADDED, MODIFIED, DELETED, BOOKMARK or ERROR.
+	Type   string    `json:"type"`
+	Object Workspace `json:"object"`
+}
+
+type workspaceList struct {
+	Metadata struct {
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+	Items []Workspace `json:"items"`
+}
+
+// KubeConfig configures how the controller talks to the Kubernetes API.
+type KubeConfig struct {
+	// Host is the base URL of the Kubernetes API.
+	Host string
+	// TokenPath is the file the bearer token is read from. It is read on
+	// every request, because Kubernetes rotates service account tokens.
+	TokenPath string
+	// CAPath is the CA bundle of the Kubernetes API. The system roots are
+	// used if it is empty.
+	CAPath string
+	// Namespace limits the controller to one namespace. All namespaces are
+	// watched if it is empty.
+	Namespace string
+}
+
+// InClusterConfig returns the config of the service account of the pod.
+func InClusterConfig() (KubeConfig, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return KubeConfig{}, xerrors.New("not running in a Kubernetes cluster, KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT are not set")
+	}
+	return KubeConfig{
+		Host:      "https://" + net.JoinHostPort(host, port),
+		TokenPath: DefaultTokenPath,
+		CAPath:    DefaultCAPath,
+	}, nil
+}
+
+// KubeClient reads and updates Workspace resources. It only implements the
+// few requests the controller needs, so the controller does not depend on
+// client-go.
+type KubeClient struct {
+	config KubeConfig
+	host   *url.URL
+	client *http.Client
+}
+
+func NewKubeClient(config KubeConfig) (*KubeClient, error) {
+	host, err := url.Parse(config.Host)
+	if err != nil {
+		return nil, xerrors.Errorf("parse kubernetes host: %w", err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.CAPath != "" {
+		ca, err := os.ReadFile(config.CAPath)
+		if err != nil {
+			return nil, xerrors.Errorf("read kubernetes ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, xerrors.Errorf("no certificates in %q", config.CAPath)
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	return &KubeClient{
+		config: config,
+		host:   host,
+		client: &http.Client{Transport: transport},
+	}, nil
+}
+
+func (c *KubeClient) resourcePath(namespace, name, subresource string) string {
+	path := fmt.Sprintf("/apis/%s/%s", Group, Version)
+	if namespace != "" {
+		path += "/namespaces/" + url.PathEscape(namespace)
+	}
+	path += "/workspaces"
+	if name != "" {
+		path += "/" + url.PathEscape(name)
+	}
+	if subresource != "" {
+		path += "/" + subresource
+	}
+	return path
+}
+
+func (c *KubeClient) request(ctx context.Context, method, path string, query url.Values, contentType string, body any) (*http.Response, error) {
+	u := c.host.JoinPath(path)
+	u.RawQuery = query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, xerrors.Errorf("marshal body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.config.TokenPath != "" {
+		token, err := os.ReadFile(c.config.TokenPath)
+		if err != nil {
+			return nil, xerrors.Errorf("read kubernetes token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		defer res.Body.Close()
+		return nil, readStatusError(res)
+	}
+	return res, nil
+}
+
+// StatusError is returned when the Kubernetes API responds with an error.
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("kubernetes api: %d: %s", e.Code, e.Message)
+}
+
+// IsConflict returns whether the error is a conflict, e.g. because the
+// resource changed since it was read.
+func IsConflict(err error) bool {
+	var statusErr *StatusError
+	return xerrors.As(err, &statusErr) && statusErr.Code == http.StatusConflict
+}
+
+// IsNotFound returns whether the error is because the resource does not
+// exist.
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return xerrors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound
+}
+
+func readStatusError(res *http.Response) error {
+	var status struct {
+		Message string `json:"message"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(res.Body, 64*1024))
+	if err := json.Unmarshal(data, &status); err != nil || status.Message == "" {
+		status.Message = strings.TrimSpace(string(data))
+	}
+	return &StatusError{Code: res.StatusCode, Message: status.Message}
+}
+
+// List returns the Workspace resources and the resource version to watch
+// them from.
+func (c *KubeClient) List(ctx context.Context) ([]Workspace, string, error) {
+	res, err := c.request(ctx, http.MethodGet, c.resourcePath(c.config.Namespace, "", ""), nil, "", nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+	var list workspaceList
+	err = json.NewDecoder(res.Body).Decode(&list)
+	if err != nil {
+		return nil, "", xerrors.Errorf("decode workspaces: %w", err)
+	}
+	return list.Items, list.Metadata.ResourceVersion, nil
+}
+
+// Watch calls fn for every change to Workspace resources after the resource
+// version until the watch times out or ctx is canceled.
+func (c *KubeClient) Watch(ctx context.Context, resourceVersion string, timeout time.Duration, fn func(WatchEvent)) error {
+	query := url.Values{}
+	query.Set("watch", "true")
+	query.Set("allowWatchBookmarks", "true")
+	query.Set("resourceVersion", resourceVersion)
+	query.Set("timeoutSeconds", fmt.Sprint(int(timeout.Seconds())))
+	res, err := c.request(ctx, http.MethodGet, c.resourcePath(c.config.Namespace, "", ""), query, "", nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var event WatchEvent
+		err := json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			return xerrors.Errorf("decode watch event: %w", err)
+		}
+		if event.Type == "ERROR" {
+			// The resource version is too old, the caller lists again.
+			return xerrors.New("watch expired")
+		}
+		fn(event)
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return xerrors.Errorf("read watch: %w", err)
+	}
+	return nil
+}
+
+// SetFinalizers replaces the finalizers of the resource. The update fails
+// with a conflict if the resource changed since it was read.
+func (c *KubeClient) SetFinalizers(ctx context.Context, ws Workspace, finalizers []string) (Workspace, error) {
+	if finalizers == nil {
+		// null removes the field, an empty list would not.
+		finalizers = []string{}
+	}
+	patch := map[string]any{
+		"metadata": map[string]any{
+			"finalizers":      finalizers,
+			"resourceVersion": ws.Metadata.ResourceVersion,
+		},
+	}
+	return c.patch(ctx, ws, "", patch)
+}
+
+// UpdateStatus replaces the status of the resource.
+func (c *KubeClient) UpdateStatus(ctx context.Context, ws Workspace, status WorkspaceStatus) (Workspace, error) {
+	return c.patch(ctx, ws, "status", map[string]any{"status": status})
+}
+
+func (c *KubeClient) patch(ctx context.Context, ws Workspace, subresource string, patch any) (Workspace, error) {
+	res, err := c.request(ctx, http.MethodPatch, c.resourcePath(ws.Metadata.Namespace, ws.Metadata.Name, subresource), nil, "application/merge-patch+json", patch)
+	if err != nil {
+		return Workspace{}, err
+	}
+	defer res.Body.Close()
+	var updated Workspace
+	err = json.NewDecoder(res.Body).Decode(&updated)
+	if err != nil {
+		return Workspace{}, xerrors.Errorf("decode workspace: %w", err)
+	}
+	return updated, nil
+}
@@ -44,9 +44,14 @@ import "github.com/coder/coder/v2/apiversion"
 //     -> `has_ai_tasks` in `CompleteJob.TemplateImport`
 //     -> `has_ai_tasks` and `ai_tasks` in `PlanComplete`
 //     -> new message types `AITaskSidebarApp` and `AITask`
+//
+// API v1.8:
+//   - Add new field named `dimensions` to `CommitQuotaRequest` and
+//     `CommitQuotaResponse`, with the new message type `QuotaDimension`, to
+//     enforce quotas on resource cost dimensions.
 const (
 	CurrentMajor = 1
-	CurrentMinor = 7
+	CurrentMinor = 8
 )
 
 // CurrentVersion is the current provisionerd API version.
@@ -612,6 +612,115 @@ func TestProvisionerd(t *testing.T) {
 		assert.True(t, didFail.Load(), "should fail the job")
 	})
 
+	t.Run("WorkspaceBuildQuotaDimensionExceeded", func(t *testing.T) {
+		t.Parallel()
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			close(done)
+		})
+		var (
+			didComplete   atomic.Bool
+			failedMessage atomic.Pointer[string]
+			committed     atomic.Pointer[proto.CommitQuotaRequest]
+			acq           = newAcquireOne(t, &proto.AcquiredJob{
+				JobId:       "test",
+				Provisioner: "someprovisioner",
+				TemplateSourceArchive: testutil.CreateTar(t, map[string]string{
+					"test.txt": "content",
+				}),
+				Type: &proto.AcquiredJob_WorkspaceBuild_{
+					WorkspaceBuild: &proto.AcquiredJob_WorkspaceBuild{
+						Metadata: &sdkproto.Metadata{},
+					},
+				},
+			})
+		)
+
+		closer := createProvisionerd(t, func(ctx context.Context) (proto.DRPCProvisionerDaemonClient, error) {
+			return createProvisionerDaemonClient(t, done, provisionerDaemonTestServer{
+				acquireJobWithCancel: acq.acquireWithCancel,
+				updateJob: func(ctx context.Context, update *proto.UpdateJobRequest) (*proto.UpdateJobResponse, error) {
+					return &proto.UpdateJobResponse{}, nil
+				},
+				completeJob: func(ctx context.Context, job *proto.CompletedJob) (*proto.Empty, error) {
+					didComplete.Store(true)
+					return &proto.Empty{}, nil
+				},
+				commitQuota: func(ctx context.Context, com *proto.CommitQuotaRequest) (*proto.CommitQuotaResponse, error) {
+					committed.Store(com)
+					dimensions := make([]*proto.QuotaDimension, 0, len(com.Dimensions))
+					for _, d := range com.Dimensions {
+						dimensions = append(dimensions, &proto.QuotaDimension{
+							Name:     d.Name,
+							Cost:     d.Cost,
+							Budget:   4,
+							Exceeded: d.Cost > 4,
+						})
+					}
+					return &proto.CommitQuotaResponse{
+						Ok:         false,
+						Budget:     -1,
+						Dimensions: dimensions,
+					}, nil
+				},
+				failJob: func(ctx context.Context, job *proto.FailedJob) (*proto.Empty, error) {
+					failedMessage.Store(&job.Error)
+					return &proto.Empty{}, nil
+				},
+			}), nil
+		}, provisionerd.LocalProvisioners{
+			"someprovisioner": createProvisionerClient(t, done, provisionerTestServer{
+				plan: func(
+					_ *provisionersdk.Session,
+					_ *sdkproto.PlanRequest,
+					_ <-chan struct{},
+				) *sdkproto.PlanComplete {
+					return &sdkproto.PlanComplete{
+						Resources: []*sdkproto.Resource{
+							{
+								Name: "a",
+								Metadata: []*sdkproto.Resource_Metadata{
+									{Key: "cost:cpu", Value: "4"},
+									{Key: "cost:memory", Value: "2"},
+									{Key: "region", Value: "eu"},
+								},
+							},
+							{
+								Name: "b",
+								Metadata: []*sdkproto.Resource_Metadata{
+									{Key: "cost:cpu", Value: "2"},
+								},
+							},
+						},
+					}
+				},
+				apply: func(
+					_ *provisionersdk.Session,
+					_ *sdkproto.ApplyRequest,
+					_ <-chan struct{},
+				) *sdkproto.ApplyComplete {
+					t.Error("should not apply when resources exceed quota")
+					return &sdkproto.ApplyComplete{}
+				},
+			}),
+		})
+		require.Condition(t, closedWithin(acq.complete, testutil.WaitShort))
+		require.NoError(t, closer.Close())
+		assert.False(t, didComplete.Load(), "should not complete the job")
+
+		com := committed.Load()
+		require.NotNil(t, com, "should commit quota")
+		require.Len(t, com.Dimensions, 2)
+		assert.Equal(t, "cpu", com.Dimensions[0].Name)
+		assert.EqualValues(t, 6, com.Dimensions[0].Cost)
+		assert.Equal(t, "memory", com.Dimensions[1].Name)
+		assert.EqualValues(t, 2, com.Dimensions[1].Cost)
+
+		msg := failedMessage.Load()
+		require.NotNil(t, msg, "should fail the job")
+		assert.Equal(t, "insufficient quota: cpu", *msg)
+	})
+
 	t.Run("WorkspaceBuildFailComplete", func(t *testing.T) {
 		t.Parallel()
 		done := make(chan struct{})
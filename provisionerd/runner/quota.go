@@ -1,11 +1,59 @@
 package runner
 
-import "github.com/coder/coder/v2/provisionersdk/proto"
+import (
+	"sort"
+	"strconv"
+	"strings"
 
-func sumDailyCost(resources []*proto.Resource) int {
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/provisionerd/proto"
+	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// quotaDimensionPrefix marks the resource metadata items that add to the cost
+// of a quota dimension, e.g. "cost:cpu".
+const quotaDimensionPrefix = "cost:"
+
+func sumDailyCost(resources []*sdkproto.Resource) int {
 	var sum int
 	for _, r := range resources {
 		sum += int(r.DailyCost)
 	}
 	return sum
 }
+
+// sumDimensionCosts sums the costs of every quota dimension declared in the
+// metadata of the resources. The dimensions are sorted by name.
+func sumDimensionCosts(resources []*sdkproto.Resource) ([]*proto.QuotaDimension, error) {
+	costs := map[string]int64{}
+	for _, r := range resources {
+		for _, item := range r.Metadata {
+			name, ok := strings.CutPrefix(item.Key, quotaDimensionPrefix)
+			if !ok {
+				continue
+			}
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				return nil, xerrors.Errorf("resource %q has a cost without a quota dimension", r.Name)
+			}
+			cost, err := strconv.ParseInt(strings.TrimSpace(item.Value), 10, 64)
+			if err != nil || cost < 0 {
+				return nil, xerrors.Errorf("resource %q has an invalid %q cost %q: must be a non-negative integer", r.Name, name, item.Value)
+			}
+			costs[name] += cost
+		}
+	}
+
+	dimensions := make([]*proto.QuotaDimension, 0, len(costs))
+	for name, cost := range costs {
+		dimensions = append(dimensions, &proto.QuotaDimension{
+			Name: name,
+			Cost: cost,
+		})
+	}
+	sort.Slice(dimensions, func(i, j int) bool {
+		return dimensions[i].Name < dimensions[j].Name
+	})
+	return dimensions, nil
+}
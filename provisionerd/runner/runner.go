@@ -934,21 +934,34 @@ func (r *Runner) buildWorkspace(ctx context.Context, stage string, req *sdkproto
 }
 
 func (r *Runner) commitQuota(ctx context.Context, resources []*sdkproto.Resource) *proto.FailedJob {
+	const stage = "Commit quota"
+
 	cost := sumDailyCost(resources)
+	dimensions, err := sumDimensionCosts(resources)
+	if err != nil {
+		r.queueLog(ctx, &proto.Log{
+			Source:    proto.LogSource_PROVISIONER,
+			Level:     sdkproto.LogLevel_ERROR,
+			CreatedAt: time.Now().UnixMilli(),
+			Output:    fmt.Sprintf("Failed to read quota costs: %s", err),
+			Stage:     stage,
+		})
+		return r.failedWorkspaceBuildf("read quota costs: %s", err)
+	}
 	r.logger.Debug(ctx, "committing quota",
 		slog.F("resources", resourceNames(resources)),
 		slog.F("cost", cost),
+		slog.F("dimensions", len(dimensions)),
 	)
-	if cost == 0 {
+	if cost == 0 && len(dimensions) == 0 {
 		return nil
 	}
 
-	const stage = "Commit quota"
-
 	resp, err := r.quotaCommitter.CommitQuota(ctx, &proto.CommitQuotaRequest{
 		JobId: r.job.JobId,
 		// #nosec G115 - Safe conversion as cost is expected to be within int32 range for provisioning costs
-		DailyCost: int32(cost),
+		DailyCost:  int32(cost),
+		Dimensions: dimensions,
 	})
 	if err != nil {
 		r.queueLog(ctx, &proto.Log{
@@ -960,11 +973,19 @@ func (r *Runner) commitQuota(ctx context.Context, resources []*sdkproto.Resource
 		})
 		return r.failedJobf("commit quota: %+v", err)
 	}
-	for _, line := range []string{
+	lines := []string{
 		fmt.Sprintf("Build cost       —   %v", cost),
 		fmt.Sprintf("Budget           —   %v", resp.Budget),
 		fmt.Sprintf("Credits consumed —   %v", resp.CreditsConsumed),
-	} {
+	}
+	var exceeded []string
+	for _, d := range resp.Dimensions {
+		lines = append(lines, fmt.Sprintf("%s: cost %d, consumed %d of %d", d.Name, d.Cost, d.Consumed, d.Budget))
+		if d.Exceeded {
+			exceeded = append(exceeded, d.Name)
+		}
+	}
+	for _, line := range lines {
 		r.queueLog(ctx, &proto.Log{
 			Source:    proto.LogSource_PROVISIONER,
 			Level:     sdkproto.LogLevel_INFO,
@@ -975,14 +996,20 @@ func (r *Runner) commitQuota(ctx context.Context, resources []*sdkproto.Resource
 	}
 
 	if !resp.Ok {
+		msg := "This build would exceed your quota. Failing."
+		reason := "insufficient quota"
+		if len(exceeded) > 0 {
+			msg = fmt.Sprintf("This build would exceed your %s quota. Failing.", strings.Join(exceeded, ", "))
+			reason = fmt.Sprintf("insufficient quota: %s", strings.Join(exceeded, ", "))
+		}
 		r.queueLog(ctx, &proto.Log{
 			Source:    proto.LogSource_PROVISIONER,
 			Level:     sdkproto.LogLevel_WARN,
 			CreatedAt: time.Now().UnixMilli(),
-			Output:    "This build would exceed your quota. Failing.",
+			Output:    msg,
 			Stage:     stage,
 		})
-		return r.failedWorkspaceBuildf("insufficient quota")
+		return r.failedWorkspaceBuildf("%s", reason)
 	}
 	return nil
 }
@@ -0,0 +1,371 @@
+package provisionersdk
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+)
+
+// A template bundle is a self-contained tar archive of a template that can be
+// imported by deployments without internet access. It contains a manifest,
+// the source of the template including the modules it uses, and a mirror of
+// the providers it requires.
+const (
+	// BundleFormatVersion is the version of the bundle format written by
+	// WriteBundle.
+	BundleFormatVersion = 1
+	// BundleManifestName is the name of the manifest in a bundle.
+	BundleManifestName = "bundle.json"
+
+	bundleTemplateDir  = "template/"
+	bundleProvidersDir = "providers/"
+	// bundleModulesDir is where `terraform get` installs the modules of a
+	// template. Bundles include it so that modules aren't downloaded again
+	// when the template is imported.
+	bundleModulesDir = ".terraform/modules"
+	// bundleManifestLimit is the maximum size of a bundle manifest.
+	bundleManifestLimit = 1 << 20
+)
+
+// BundleManifest describes the contents of a template bundle.
+type BundleManifest struct {
+	FormatVersion int              `json:"format_version"`
+	Name          string           `json:"name"`
+	Version       string           `json:"version"`
+	CreatedAt     time.Time        `json:"created_at"`
+	Providers     []BundleProvider `json:"providers"`
+	Modules       []BundleModule   `json:"modules"`
+}
+
+// BundleProvider is a provider mirrored in a template bundle.
+type BundleProvider struct {
+	// Source is the fully qualified address of the provider, e.g.
+	// registry.terraform.io/coder/coder.
+	Source    string   `json:"source"`
+	Version   string   `json:"version"`
+	Platforms []string `json:"platforms"`
+}
+
+// BundleModule is a module installed in a template bundle.
+type BundleModule struct {
+	Key     string `json:"key"`
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"`
+}
+
+// WriteBundle writes a template bundle of the template in templateDir and the
+// providers mirrored in providersDir, as written by
+// `terraform providers mirror`. providersDir may be empty to omit providers.
+func WriteBundle(w io.Writer, logger slog.Logger, manifest BundleManifest, templateDir, providersDir string) error {
+	manifest.FormatVersion = BundleFormatVersion
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("marshal manifest: %w", err)
+	}
+
+	tarWriter := tar.NewWriter(w)
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name:    BundleManifestName,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: manifest.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := tarWriter.Write(data); err != nil {
+		return err
+	}
+
+	// Archive the template the same way it's archived when pushed, so that
+	// the same files are ignored.
+	var template bytes.Buffer
+	err = Tar(&template, logger, templateDir, TemplateArchiveLimit)
+	if err != nil {
+		return xerrors.Errorf("archive template: %w", err)
+	}
+	err = copyTar(tarWriter, tar.NewReader(&template), func(name string) string {
+		return bundleTemplateDir + name
+	})
+	if err != nil {
+		return xerrors.Errorf("write template: %w", err)
+	}
+
+	modulesDir := filepath.Join(templateDir, filepath.FromSlash(bundleModulesDir))
+	if _, err := os.Stat(modulesDir); err == nil {
+		err = writeTarDir(tarWriter, modulesDir, bundleTemplateDir+bundleModulesDir+"/")
+		if err != nil {
+			return xerrors.Errorf("write modules: %w", err)
+		}
+	}
+
+	if providersDir != "" {
+		err = writeTarDir(tarWriter, providersDir, bundleProvidersDir)
+		if err != nil {
+			return xerrors.Errorf("write providers: %w", err)
+		}
+	}
+
+	return tarWriter.Close()
+}
+
+// ReadBundleManifest reads the manifest of a template bundle.
+func ReadBundleManifest(r io.Reader) (BundleManifest, error) {
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if xerrors.Is(err, io.EOF) {
+			return BundleManifest{}, xerrors.Errorf("bundle has no %s manifest", BundleManifestName)
+		}
+		if err != nil {
+			return BundleManifest{}, err
+		}
+		if header.Name != BundleManifestName {
+			continue
+		}
+
+		var manifest BundleManifest
+		err = json.NewDecoder(io.LimitReader(tarReader, bundleManifestLimit)).Decode(&manifest)
+		if err != nil {
+			return BundleManifest{}, xerrors.Errorf("decode manifest: %w", err)
+		}
+		if manifest.FormatVersion != BundleFormatVersion {
+			return BundleManifest{}, xerrors.Errorf("unsupported bundle format version %d", manifest.FormatVersion)
+		}
+		return manifest, nil
+	}
+}
+
+// BundleTemplateArchive writes the template of a bundle, including its
+// modules, as a template archive that can be uploaded to create a template
+// version.
+func BundleTemplateArchive(w io.Writer, r io.Reader) error {
+	tarWriter := tar.NewWriter(w)
+	err := copyTar(tarWriter, tar.NewReader(r), func(name string) string {
+		if !strings.HasPrefix(name, bundleTemplateDir) {
+			return ""
+		}
+		return strings.TrimPrefix(name, bundleTemplateDir)
+	})
+	if err != nil {
+		return err
+	}
+	return tarWriter.Close()
+}
+
+// ExtractBundleProviders extracts the providers of a bundle to directory. The
+// directory can be used as a filesystem mirror in the Terraform CLI
+// configuration of provisioners.
+func ExtractBundleProviders(directory string, r io.Reader) error {
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if xerrors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := strings.TrimPrefix(header.Name, bundleProvidersDir)
+		if name == header.Name || name == "" || strings.Contains(name, "..") {
+			continue
+		}
+		// #nosec G305 - The name is checked for path traversal above.
+		target := filepath.Join(directory, filepath.FromSlash(name))
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0o644)
+			if err != nil {
+				return err
+			}
+			// #nosec G110 - Provider archives are written by `terraform
+			// providers mirror` and are expected to be large.
+			_, err = io.Copy(file, tarReader)
+			_ = file.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ReadBundleProviders lists the providers in a directory written by
+// `terraform providers mirror`, which uses the packed layout:
+// HOSTNAME/NAMESPACE/TYPE/terraform-provider-TYPE_VERSION_TARGET.zip
+func ReadBundleProviders(directory string) ([]BundleProvider, error) {
+	providers := map[string]*BundleProvider{}
+	err := filepath.Walk(directory, func(file string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fileInfo.IsDir() || !strings.HasSuffix(fileInfo.Name(), ".zip") {
+			return nil
+		}
+		rel, err := filepath.Rel(directory, file)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) != 4 {
+			return nil
+		}
+		providerType := parts[2]
+		// e.g. 2.4.0_linux_amd64
+		name := strings.TrimSuffix(strings.TrimPrefix(parts[3], "terraform-provider-"+providerType+"_"), ".zip")
+		fields := strings.Split(name, "_")
+		if len(fields) != 3 {
+			return nil
+		}
+
+		source := path.Join(parts[0], parts[1], providerType)
+		key := source + "@" + fields[0]
+		provider, ok := providers[key]
+		if !ok {
+			provider = &BundleProvider{
+				Source:  source,
+				Version: fields[0],
+			}
+			providers[key] = provider
+		}
+		provider.Platforms = append(provider.Platforms, fields[1]+"_"+fields[2])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bundleProviders := make([]BundleProvider, 0, len(providers))
+	for _, provider := range providers {
+		slices.Sort(provider.Platforms)
+		bundleProviders = append(bundleProviders, *provider)
+	}
+	slices.SortFunc(bundleProviders, func(a, b BundleProvider) int {
+		if a.Source != b.Source {
+			return strings.Compare(a.Source, b.Source)
+		}
+		return strings.Compare(a.Version, b.Version)
+	})
+	return bundleProviders, nil
+}
+
+// ReadBundleModules lists the modules installed in the template in directory
+// by `terraform get`.
+func ReadBundleModules(directory string) ([]BundleModule, error) {
+	data, err := os.ReadFile(filepath.Join(directory, filepath.FromSlash(bundleModulesDir), "modules.json"))
+	if xerrors.Is(err, os.ErrNotExist) {
+		return []BundleModule{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var installed struct {
+		Modules []BundleModule `json:"Modules"`
+	}
+	err = json.Unmarshal(data, &installed)
+	if err != nil {
+		return nil, xerrors.Errorf("decode modules manifest: %w", err)
+	}
+
+	modules := make([]BundleModule, 0, len(installed.Modules))
+	for _, module := range installed.Modules {
+		// The root module is the template itself.
+		if module.Key == "" {
+			continue
+		}
+		modules = append(modules, module)
+	}
+	return modules, nil
+}
+
+// copyTar copies the entries of a tar archive to tarWriter, renaming them with
+// rename. Entries renamed to an empty name are skipped.
+func copyTar(tarWriter *tar.Writer, tarReader *tar.Reader, rename func(name string) string) error {
+	for {
+		header, err := tarReader.Next()
+		if xerrors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		header.Name = rename(header.Name)
+		if header.Name == "" {
+			continue
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		// #nosec G110 - The archive is written by WriteBundle.
+		if _, err := io.Copy(tarWriter, tarReader); err != nil {
+			return err
+		}
+	}
+}
+
+// writeTarDir writes the regular files and directories of directory to
+// tarWriter, prefixing their names with prefix. Hidden files are skipped.
+func writeTarDir(tarWriter *tar.Writer, directory, prefix string) error {
+	return filepath.Walk(directory, func(file string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(directory, file)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if strings.HasPrefix(fileInfo.Name(), ".") {
+			if fileInfo.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !fileInfo.IsDir() && !fileInfo.Mode().IsRegular() {
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(fileInfo, "")
+		if err != nil {
+			return err
+		}
+		header.Name = prefix + filepath.ToSlash(rel)
+		if fileInfo.IsDir() {
+			header.Name += "/"
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if fileInfo.IsDir() {
+			return nil
+		}
+
+		data, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer data.Close()
+		_, err = io.Copy(tarWriter, data)
+		return err
+	})
+}
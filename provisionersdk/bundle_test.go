@@ -0,0 +1,129 @@
+package provisionersdk_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/provisionersdk"
+)
+
+func TestBundle(t *testing.T) {
+	t.Parallel()
+
+	log := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+
+	templateDir := t.TempDir()
+	writeFile(t, filepath.Join(templateDir, "main.tf"), `module "code-server" {}`)
+	writeFile(t, filepath.Join(templateDir, ".terraform", "modules", "modules.json"),
+		`{"Modules":[{"Key":"","Source":"","Dir":"."},{"Key":"code-server","Source":"registry.coder.com/coder/code-server/coder","Version":"1.0.0","Dir":".terraform/modules/code-server"}]}`)
+	writeFile(t, filepath.Join(templateDir, ".terraform", "modules", "code-server", "main.tf"), `resource "coder_app" "code-server" {}`)
+	writeFile(t, filepath.Join(templateDir, ".terraform", "modules", "code-server", ".git", "HEAD"), "ref: refs/heads/main")
+	writeFile(t, filepath.Join(templateDir, ".terraform", "providers", "cache"), "")
+
+	providersDir := t.TempDir()
+	providerDir := filepath.Join(providersDir, "registry.terraform.io", "coder", "coder")
+	writeFile(t, filepath.Join(providerDir, "terraform-provider-coder_2.4.0_linux_amd64.zip"), "amd64")
+	writeFile(t, filepath.Join(providerDir, "terraform-provider-coder_2.4.0_linux_arm64.zip"), "arm64")
+	writeFile(t, filepath.Join(providerDir, "2.4.0.json"), "{}")
+
+	providers, err := provisionersdk.ReadBundleProviders(providersDir)
+	require.NoError(t, err)
+	require.Equal(t, []provisionersdk.BundleProvider{{
+		Source:    "registry.terraform.io/coder/coder",
+		Version:   "2.4.0",
+		Platforms: []string{"linux_amd64", "linux_arm64"},
+	}}, providers)
+
+	modules, err := provisionersdk.ReadBundleModules(templateDir)
+	require.NoError(t, err)
+	require.Equal(t, []provisionersdk.BundleModule{{
+		Key:     "code-server",
+		Source:  "registry.coder.com/coder/code-server/coder",
+		Version: "1.0.0",
+	}}, modules)
+
+	manifest := provisionersdk.BundleManifest{
+		Name:      "docker",
+		Version:   "v1",
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Providers: providers,
+		Modules:   modules,
+	}
+	var bundle bytes.Buffer
+	err = provisionersdk.WriteBundle(&bundle, log, manifest, templateDir, providersDir)
+	require.NoError(t, err)
+
+	t.Run("Manifest", func(t *testing.T) {
+		t.Parallel()
+		got, err := provisionersdk.ReadBundleManifest(bytes.NewReader(bundle.Bytes()))
+		require.NoError(t, err)
+		manifest.FormatVersion = provisionersdk.BundleFormatVersion
+		require.Equal(t, manifest, got)
+	})
+
+	t.Run("TemplateArchive", func(t *testing.T) {
+		t.Parallel()
+		var archive bytes.Buffer
+		err := provisionersdk.BundleTemplateArchive(&archive, bytes.NewReader(bundle.Bytes()))
+		require.NoError(t, err)
+
+		var names []string
+		tarReader := tar.NewReader(&archive)
+		for {
+			header, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			if header.Typeflag == tar.TypeReg {
+				names = append(names, header.Name)
+			}
+		}
+		// Only installed modules are included from the .terraform
+		// directory, without hidden files.
+		require.ElementsMatch(t, []string{
+			"main.tf",
+			".terraform/modules/modules.json",
+			".terraform/modules/code-server/main.tf",
+		}, names)
+	})
+
+	t.Run("Providers", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		err := provisionersdk.ExtractBundleProviders(dir, bytes.NewReader(bundle.Bytes()))
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(filepath.Join(dir, "registry.terraform.io", "coder", "coder", "terraform-provider-coder_2.4.0_linux_arm64.zip"))
+		require.NoError(t, err)
+		require.Equal(t, "arm64", string(data))
+		_, err = os.Stat(filepath.Join(dir, "main.tf"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("NoManifest", func(t *testing.T) {
+		t.Parallel()
+		var archive bytes.Buffer
+		err := provisionersdk.Tar(&archive, log, templateDir, provisionersdk.TemplateArchiveLimit)
+		require.NoError(t, err)
+		_, err = provisionersdk.ReadBundleManifest(&archive)
+		require.ErrorContains(t, err, "no bundle.json manifest")
+	})
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	err := os.MkdirAll(filepath.Dir(name), 0o755)
+	require.NoError(t, err)
+	err = os.WriteFile(name, []byte(content), 0o600)
+	require.NoError(t, err)
+}
@@ -0,0 +1,1065 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: publicapi/proto/publicapi.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Workspace is a workspace with a summary of its latest build.
+type Workspace struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                []byte                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	OwnerId           []byte                 `protobuf:"bytes,5,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	OwnerUsername     string                 `protobuf:"bytes,6,opt,name=owner_username,json=ownerUsername,proto3" json:"owner_username,omitempty"`
+	OrganizationId    []byte                 `protobuf:"bytes,7,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	OrganizationName  string                 `protobuf:"bytes,8,opt,name=organization_name,json=organizationName,proto3" json:"organization_name,omitempty"`
+	TemplateId        []byte                 `protobuf:"bytes,9,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	TemplateName      string                 `protobuf:"bytes,10,opt,name=template_name,json=templateName,proto3" json:"template_name,omitempty"`
+	TemplateVersionId []byte                 `protobuf:"bytes,11,opt,name=template_version_id,json=templateVersionId,proto3" json:"template_version_id,omitempty"`
+	LastUsedAt        *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	// dormant_at and deleting_at are unset unless the workspace is dormant.
+	DormantAt             *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=dormant_at,json=dormantAt,proto3" json:"dormant_at,omitempty"`
+	DeletingAt            *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=deleting_at,json=deletingAt,proto3" json:"deleting_at,omitempty"`
+	LatestBuildTransition string                 `protobuf:"bytes,15,opt,name=latest_build_transition,json=latestBuildTransition,proto3" json:"latest_build_transition,omitempty"`
+	LatestBuildStatus     string                 `protobuf:"bytes,16,opt,name=latest_build_status,json=latestBuildStatus,proto3" json:"latest_build_status,omitempty"`
+	// latest_build_completed_at is unset while the latest build is running.
+	LatestBuildCompletedAt *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=latest_build_completed_at,json=latestBuildCompletedAt,proto3" json:"latest_build_completed_at,omitempty"`
+	LatestBuildError       string                 `protobuf:"bytes,18,opt,name=latest_build_error,json=latestBuildError,proto3" json:"latest_build_error,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *Workspace) Reset() {
+	*x = Workspace{}
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Workspace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workspace) ProtoMessage() {}
+
+func (x *Workspace) ProtoReflect() protoreflect.Message {
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workspace.ProtoReflect.Descriptor instead.
+func (*Workspace) Descriptor() ([]byte, []int) {
+	return file_publicapi_proto_publicapi_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Workspace) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *Workspace) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Workspace) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Workspace) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Workspace) GetOwnerId() []byte {
+	if x != nil {
+		return x.OwnerId
+	}
+	return nil
+}
+
+func (x *Workspace) GetOwnerUsername() string {
+	if x != nil {
+		return x.OwnerUsername
+	}
+	return ""
+}
+
+func (x *Workspace) GetOrganizationId() []byte {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return nil
+}
+
+func (x *Workspace) GetOrganizationName() string {
+	if x != nil {
+		return x.OrganizationName
+	}
+	return ""
+}
+
+func (x *Workspace) GetTemplateId() []byte {
+	if x != nil {
+		return x.TemplateId
+	}
+	return nil
+}
+
+func (x *Workspace) GetTemplateName() string {
+	if x != nil {
+		return x.TemplateName
+	}
+	return ""
+}
+
+func (x *Workspace) GetTemplateVersionId() []byte {
+	if x != nil {
+		return x.TemplateVersionId
+	}
+	return nil
+}
+
+func (x *Workspace) GetLastUsedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return nil
+}
+
+func (x *Workspace) GetDormantAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DormantAt
+	}
+	return nil
+}
+
+func (x *Workspace) GetDeletingAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletingAt
+	}
+	return nil
+}
+
+func (x *Workspace) GetLatestBuildTransition() string {
+	if x != nil {
+		return x.LatestBuildTransition
+	}
+	return ""
+}
+
+func (x *Workspace) GetLatestBuildStatus() string {
+	if x != nil {
+		return x.LatestBuildStatus
+	}
+	return ""
+}
+
+func (x *Workspace) GetLatestBuildCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LatestBuildCompletedAt
+	}
+	return nil
+}
+
+func (x *Workspace) GetLatestBuildError() string {
+	if x != nil {
+		return x.LatestBuildError
+	}
+	return ""
+}
+
+// WorkspaceBuild is a build of a workspace. The status of the build is the
+// status of its provisioner job.
+type WorkspaceBuild struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                []byte                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	WorkspaceId       []byte                 `protobuf:"bytes,2,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	TemplateVersionId []byte                 `protobuf:"bytes,3,opt,name=template_version_id,json=templateVersionId,proto3" json:"template_version_id,omitempty"`
+	BuildNumber       int32                  `protobuf:"varint,4,opt,name=build_number,json=buildNumber,proto3" json:"build_number,omitempty"`
+	Transition        string                 `protobuf:"bytes,5,opt,name=transition,proto3" json:"transition,omitempty"`
+	Reason            string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	InitiatorId       []byte                 `protobuf:"bytes,7,opt,name=initiator_id,json=initiatorId,proto3" json:"initiator_id,omitempty"`
+	InitiatorUsername string                 `protobuf:"bytes,8,opt,name=initiator_username,json=initiatorUsername,proto3" json:"initiator_username,omitempty"`
+	JobId             []byte                 `protobuf:"bytes,9,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// deadline and max_deadline are unset when the workspace does not stop
+	// automatically.
+	Deadline      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	MaxDeadline   *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=max_deadline,json=maxDeadline,proto3" json:"max_deadline,omitempty"`
+	DailyCost     int32                  `protobuf:"varint,13,opt,name=daily_cost,json=dailyCost,proto3" json:"daily_cost,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkspaceBuild) Reset() {
+	*x = WorkspaceBuild{}
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkspaceBuild) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceBuild) ProtoMessage() {}
+
+func (x *WorkspaceBuild) ProtoReflect() protoreflect.Message {
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkspaceBuild.ProtoReflect.Descriptor instead.
+func (*WorkspaceBuild) Descriptor() ([]byte, []int) {
+	return file_publicapi_proto_publicapi_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *WorkspaceBuild) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *WorkspaceBuild) GetWorkspaceId() []byte {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return nil
+}
+
+func (x *WorkspaceBuild) GetTemplateVersionId() []byte {
+	if x != nil {
+		return x.TemplateVersionId
+	}
+	return nil
+}
+
+func (x *WorkspaceBuild) GetBuildNumber() int32 {
+	if x != nil {
+		return x.BuildNumber
+	}
+	return 0
+}
+
+func (x *WorkspaceBuild) GetTransition() string {
+	if x != nil {
+		return x.Transition
+	}
+	return ""
+}
+
+func (x *WorkspaceBuild) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *WorkspaceBuild) GetInitiatorId() []byte {
+	if x != nil {
+		return x.InitiatorId
+	}
+	return nil
+}
+
+func (x *WorkspaceBuild) GetInitiatorUsername() string {
+	if x != nil {
+		return x.InitiatorUsername
+	}
+	return ""
+}
+
+func (x *WorkspaceBuild) GetJobId() []byte {
+	if x != nil {
+		return x.JobId
+	}
+	return nil
+}
+
+func (x *WorkspaceBuild) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WorkspaceBuild) GetDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+func (x *WorkspaceBuild) GetMaxDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MaxDeadline
+	}
+	return nil
+}
+
+func (x *WorkspaceBuild) GetDailyCost() int32 {
+	if x != nil {
+		return x.DailyCost
+	}
+	return 0
+}
+
+// WorkspaceAgent is an agent of the latest build of a workspace.
+type WorkspaceAgent struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          []byte                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	WorkspaceId []byte                 `protobuf:"bytes,2,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	Name        string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// status is the connection status of the agent: connecting, connected,
+	// disconnected or timeout.
+	Status           string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	LifecycleState   string                 `protobuf:"bytes,5,opt,name=lifecycle_state,json=lifecycleState,proto3" json:"lifecycle_state,omitempty"`
+	Version          string                 `protobuf:"bytes,6,opt,name=version,proto3" json:"version,omitempty"`
+	ApiVersion       string                 `protobuf:"bytes,7,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	OperatingSystem  string                 `protobuf:"bytes,8,opt,name=operating_system,json=operatingSystem,proto3" json:"operating_system,omitempty"`
+	Architecture     string                 `protobuf:"bytes,9,opt,name=architecture,proto3" json:"architecture,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	FirstConnectedAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=first_connected_at,json=firstConnectedAt,proto3" json:"first_connected_at,omitempty"`
+	LastConnectedAt  *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=last_connected_at,json=lastConnectedAt,proto3" json:"last_connected_at,omitempty"`
+	DisconnectedAt   *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=disconnected_at,json=disconnectedAt,proto3" json:"disconnected_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *WorkspaceAgent) Reset() {
+	*x = WorkspaceAgent{}
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkspaceAgent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceAgent) ProtoMessage() {}
+
+func (x *WorkspaceAgent) ProtoReflect() protoreflect.Message {
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkspaceAgent.ProtoReflect.Descriptor instead.
+func (*WorkspaceAgent) Descriptor() ([]byte, []int) {
+	return file_publicapi_proto_publicapi_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *WorkspaceAgent) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *WorkspaceAgent) GetWorkspaceId() []byte {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return nil
+}
+
+func (x *WorkspaceAgent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WorkspaceAgent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WorkspaceAgent) GetLifecycleState() string {
+	if x != nil {
+		return x.LifecycleState
+	}
+	return ""
+}
+
+func (x *WorkspaceAgent) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *WorkspaceAgent) GetApiVersion() string {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return ""
+}
+
+func (x *WorkspaceAgent) GetOperatingSystem() string {
+	if x != nil {
+		return x.OperatingSystem
+	}
+	return ""
+}
+
+func (x *WorkspaceAgent) GetArchitecture() string {
+	if x != nil {
+		return x.Architecture
+	}
+	return ""
+}
+
+func (x *WorkspaceAgent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WorkspaceAgent) GetFirstConnectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstConnectedAt
+	}
+	return nil
+}
+
+func (x *WorkspaceAgent) GetLastConnectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastConnectedAt
+	}
+	return nil
+}
+
+func (x *WorkspaceAgent) GetDisconnectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DisconnectedAt
+	}
+	return nil
+}
+
+// WorkspaceAgentStats are the stats of an agent, aggregated since the time
+// requested.
+type WorkspaceAgentStats struct {
+	state                       protoimpl.MessageState `protogen:"open.v1"`
+	AgentId                     []byte                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	WorkspaceId                 []byte                 `protobuf:"bytes,2,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	TemplateId                  []byte                 `protobuf:"bytes,3,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	UserId                      []byte                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AggregatedFrom              *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=aggregated_from,json=aggregatedFrom,proto3" json:"aggregated_from,omitempty"`
+	RxBytes                     int64                  `protobuf:"varint,6,opt,name=rx_bytes,json=rxBytes,proto3" json:"rx_bytes,omitempty"`
+	TxBytes                     int64                  `protobuf:"varint,7,opt,name=tx_bytes,json=txBytes,proto3" json:"tx_bytes,omitempty"`
+	ConnectionLatencyP50Ms      float64                `protobuf:"fixed64,8,opt,name=connection_latency_p50_ms,json=connectionLatencyP50Ms,proto3" json:"connection_latency_p50_ms,omitempty"`
+	ConnectionLatencyP95Ms      float64                `protobuf:"fixed64,9,opt,name=connection_latency_p95_ms,json=connectionLatencyP95Ms,proto3" json:"connection_latency_p95_ms,omitempty"`
+	SessionCountVscode          int64                  `protobuf:"varint,10,opt,name=session_count_vscode,json=sessionCountVscode,proto3" json:"session_count_vscode,omitempty"`
+	SessionCountSsh             int64                  `protobuf:"varint,11,opt,name=session_count_ssh,json=sessionCountSsh,proto3" json:"session_count_ssh,omitempty"`
+	SessionCountJetbrains       int64                  `protobuf:"varint,12,opt,name=session_count_jetbrains,json=sessionCountJetbrains,proto3" json:"session_count_jetbrains,omitempty"`
+	SessionCountReconnectingPty int64                  `protobuf:"varint,13,opt,name=session_count_reconnecting_pty,json=sessionCountReconnectingPty,proto3" json:"session_count_reconnecting_pty,omitempty"`
+	unknownFields               protoimpl.UnknownFields
+	sizeCache                   protoimpl.SizeCache
+}
+
+func (x *WorkspaceAgentStats) Reset() {
+	*x = WorkspaceAgentStats{}
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkspaceAgentStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceAgentStats) ProtoMessage() {}
+
+func (x *WorkspaceAgentStats) ProtoReflect() protoreflect.Message {
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkspaceAgentStats.ProtoReflect.Descriptor instead.
+func (*WorkspaceAgentStats) Descriptor() ([]byte, []int) {
+	return file_publicapi_proto_publicapi_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WorkspaceAgentStats) GetAgentId() []byte {
+	if x != nil {
+		return x.AgentId
+	}
+	return nil
+}
+
+func (x *WorkspaceAgentStats) GetWorkspaceId() []byte {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return nil
+}
+
+func (x *WorkspaceAgentStats) GetTemplateId() []byte {
+	if x != nil {
+		return x.TemplateId
+	}
+	return nil
+}
+
+func (x *WorkspaceAgentStats) GetUserId() []byte {
+	if x != nil {
+		return x.UserId
+	}
+	return nil
+}
+
+func (x *WorkspaceAgentStats) GetAggregatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AggregatedFrom
+	}
+	return nil
+}
+
+func (x *WorkspaceAgentStats) GetRxBytes() int64 {
+	if x != nil {
+		return x.RxBytes
+	}
+	return 0
+}
+
+func (x *WorkspaceAgentStats) GetTxBytes() int64 {
+	if x != nil {
+		return x.TxBytes
+	}
+	return 0
+}
+
+func (x *WorkspaceAgentStats) GetConnectionLatencyP50Ms() float64 {
+	if x != nil {
+		return x.ConnectionLatencyP50Ms
+	}
+	return 0
+}
+
+func (x *WorkspaceAgentStats) GetConnectionLatencyP95Ms() float64 {
+	if x != nil {
+		return x.ConnectionLatencyP95Ms
+	}
+	return 0
+}
+
+func (x *WorkspaceAgentStats) GetSessionCountVscode() int64 {
+	if x != nil {
+		return x.SessionCountVscode
+	}
+	return 0
+}
+
+func (x *WorkspaceAgentStats) GetSessionCountSsh() int64 {
+	if x != nil {
+		return x.SessionCountSsh
+	}
+	return 0
+}
+
+func (x *WorkspaceAgentStats) GetSessionCountJetbrains() int64 {
+	if x != nil {
+		return x.SessionCountJetbrains
+	}
+	return 0
+}
+
+func (x *WorkspaceAgentStats) GetSessionCountReconnectingPty() int64 {
+	if x != nil {
+		return x.SessionCountReconnectingPty
+	}
+	return 0
+}
+
+type GetWorkspaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            []byte                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWorkspaceRequest) Reset() {
+	*x = GetWorkspaceRequest{}
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWorkspaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkspaceRequest) ProtoMessage() {}
+
+func (x *GetWorkspaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkspaceRequest.ProtoReflect.Descriptor instead.
+func (*GetWorkspaceRequest) Descriptor() ([]byte, []int) {
+	return file_publicapi_proto_publicapi_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetWorkspaceRequest) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+type ListWorkspacesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// query uses the same syntax as the q parameter of GET /api/v2/workspaces,
+	// e.g. "owner:me status:running".
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// page_size is the number of workspaces fetched from the database at a
+	// time. It defaults to 500, and is capped at 5000.
+	PageSize      int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkspacesRequest) Reset() {
+	*x = ListWorkspacesRequest{}
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkspacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkspacesRequest) ProtoMessage() {}
+
+func (x *ListWorkspacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkspacesRequest.ProtoReflect.Descriptor instead.
+func (*ListWorkspacesRequest) Descriptor() ([]byte, []int) {
+	return file_publicapi_proto_publicapi_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListWorkspacesRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ListWorkspacesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListWorkspaceBuildsRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	WorkspaceId []byte                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	// since only lists builds created after the time, if set.
+	Since         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkspaceBuildsRequest) Reset() {
+	*x = ListWorkspaceBuildsRequest{}
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkspaceBuildsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkspaceBuildsRequest) ProtoMessage() {}
+
+func (x *ListWorkspaceBuildsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkspaceBuildsRequest.ProtoReflect.Descriptor instead.
+func (*ListWorkspaceBuildsRequest) Descriptor() ([]byte, []int) {
+	return file_publicapi_proto_publicapi_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListWorkspaceBuildsRequest) GetWorkspaceId() []byte {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return nil
+}
+
+func (x *ListWorkspaceBuildsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+type ListWorkspaceAgentsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// workspace_ids are the workspaces to list the agents of. All workspaces
+	// the caller can read are used if empty.
+	WorkspaceIds  [][]byte `protobuf:"bytes,1,rep,name=workspace_ids,json=workspaceIds,proto3" json:"workspace_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkspaceAgentsRequest) Reset() {
+	*x = ListWorkspaceAgentsRequest{}
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkspaceAgentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkspaceAgentsRequest) ProtoMessage() {}
+
+func (x *ListWorkspaceAgentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkspaceAgentsRequest.ProtoReflect.Descriptor instead.
+func (*ListWorkspaceAgentsRequest) Descriptor() ([]byte, []int) {
+	return file_publicapi_proto_publicapi_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListWorkspaceAgentsRequest) GetWorkspaceIds() [][]byte {
+	if x != nil {
+		return x.WorkspaceIds
+	}
+	return nil
+}
+
+type ListWorkspaceAgentStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// created_after only aggregates stats reported after the time. It defaults
+	// to the last 15 minutes.
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkspaceAgentStatsRequest) Reset() {
+	*x = ListWorkspaceAgentStatsRequest{}
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkspaceAgentStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkspaceAgentStatsRequest) ProtoMessage() {}
+
+func (x *ListWorkspaceAgentStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_publicapi_proto_publicapi_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkspaceAgentStatsRequest.ProtoReflect.Descriptor instead.
+func (*ListWorkspaceAgentStatsRequest) Descriptor() ([]byte, []int) {
+	return file_publicapi_proto_publicapi_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListWorkspaceAgentStatsRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+var File_publicapi_proto_publicapi_proto protoreflect.FileDescriptor
+
+const file_publicapi_proto_publicapi_proto_rawDesc = "" +
+	"\n" +
+	"\x1fpublicapi/proto/publicapi.proto\x12\x12coder.publicapi.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd6\x06\n" +
+	"\tWorkspace\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\fR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x19\n" +
+	"\bowner_id\x18\x05 \x01(\fR\aownerId\x12%\n" +
+	"\x0eowner_username\x18\x06 \x01(\tR\rownerUsername\x12'\n" +
+	"\x0forganization_id\x18\a \x01(\fR\x0eorganizationId\x12+\n" +
+	"\x11organization_name\x18\b \x01(\tR\x10organizationName\x12\x1f\n" +
+	"\vtemplate_id\x18\t \x01(\fR\n" +
+	"templateId\x12#\n" +
+	"\rtemplate_name\x18\n" +
+	" \x01(\tR\ftemplateName\x12.\n" +
+	"\x13template_version_id\x18\v \x01(\fR\x11templateVersionId\x12<\n" +
+	"\flast_used_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUsedAt\x129\n" +
+	"\n" +
+	"dormant_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tdormantAt\x12;\n" +
+	"\vdeleting_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"deletingAt\x126\n" +
+	"\x17latest_build_transition\x18\x0f \x01(\tR\x15latestBuildTransition\x12.\n" +
+	"\x13latest_build_status\x18\x10 \x01(\tR\x11latestBuildStatus\x12U\n" +
+	"\x19latest_build_completed_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\x16latestBuildCompletedAt\x12,\n" +
+	"\x12latest_build_error\x18\x12 \x01(\tR\x10latestBuildError\"\x88\x04\n" +
+	"\x0eWorkspaceBuild\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\fR\x02id\x12!\n" +
+	"\fworkspace_id\x18\x02 \x01(\fR\vworkspaceId\x12.\n" +
+	"\x13template_version_id\x18\x03 \x01(\fR\x11templateVersionId\x12!\n" +
+	"\fbuild_number\x18\x04 \x01(\x05R\vbuildNumber\x12\x1e\n" +
+	"\n" +
+	"transition\x18\x05 \x01(\tR\n" +
+	"transition\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12!\n" +
+	"\finitiator_id\x18\a \x01(\fR\vinitiatorId\x12-\n" +
+	"\x12initiator_username\x18\b \x01(\tR\x11initiatorUsername\x12\x15\n" +
+	"\x06job_id\x18\t \x01(\fR\x05jobId\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x126\n" +
+	"\bdeadline\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\bdeadline\x12=\n" +
+	"\fmax_deadline\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\vmaxDeadline\x12\x1d\n" +
+	"\n" +
+	"daily_cost\x18\r \x01(\x05R\tdailyCost\"\xb4\x04\n" +
+	"\x0eWorkspaceAgent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\fR\x02id\x12!\n" +
+	"\fworkspace_id\x18\x02 \x01(\fR\vworkspaceId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12'\n" +
+	"\x0flifecycle_state\x18\x05 \x01(\tR\x0elifecycleState\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\tR\aversion\x12\x1f\n" +
+	"\vapi_version\x18\a \x01(\tR\n" +
+	"apiVersion\x12)\n" +
+	"\x10operating_system\x18\b \x01(\tR\x0foperatingSystem\x12\"\n" +
+	"\farchitecture\x18\t \x01(\tR\farchitecture\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12H\n" +
+	"\x12first_connected_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\x10firstConnectedAt\x12F\n" +
+	"\x11last_connected_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\x0flastConnectedAt\x12C\n" +
+	"\x0fdisconnected_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\x0edisconnectedAt\"\xd9\x04\n" +
+	"\x13WorkspaceAgentStats\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\fR\aagentId\x12!\n" +
+	"\fworkspace_id\x18\x02 \x01(\fR\vworkspaceId\x12\x1f\n" +
+	"\vtemplate_id\x18\x03 \x01(\fR\n" +
+	"templateId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\fR\x06userId\x12C\n" +
+	"\x0faggregated_from\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x0eaggregatedFrom\x12\x19\n" +
+	"\brx_bytes\x18\x06 \x01(\x03R\arxBytes\x12\x19\n" +
+	"\btx_bytes\x18\a \x01(\x03R\atxBytes\x129\n" +
+	"\x19connection_latency_p50_ms\x18\b \x01(\x01R\x16connectionLatencyP50Ms\x129\n" +
+	"\x19connection_latency_p95_ms\x18\t \x01(\x01R\x16connectionLatencyP95Ms\x120\n" +
+	"\x14session_count_vscode\x18\n" +
+	" \x01(\x03R\x12sessionCountVscode\x12*\n" +
+	"\x11session_count_ssh\x18\v \x01(\x03R\x0fsessionCountSsh\x126\n" +
+	"\x17session_count_jetbrains\x18\f \x01(\x03R\x15sessionCountJetbrains\x12C\n" +
+	"\x1esession_count_reconnecting_pty\x18\r \x01(\x03R\x1bsessionCountReconnectingPty\"%\n" +
+	"\x13GetWorkspaceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\fR\x02id\"J\n" +
+	"\x15ListWorkspacesRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\"q\n" +
+	"\x1aListWorkspaceBuildsRequest\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\fR\vworkspaceId\x120\n" +
+	"\x05since\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\"A\n" +
+	"\x1aListWorkspaceAgentsRequest\x12#\n" +
+	"\rworkspace_ids\x18\x01 \x03(\fR\fworkspaceIds\"a\n" +
+	"\x1eListWorkspaceAgentStatsRequest\x12?\n" +
+	"\rcreated_after\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter2\x95\x04\n" +
+	"\tPublicAPI\x12V\n" +
+	"\fGetWorkspace\x12'.coder.publicapi.v1.GetWorkspaceRequest\x1a\x1d.coder.publicapi.v1.Workspace\x12\\\n" +
+	"\x0eListWorkspaces\x12).coder.publicapi.v1.ListWorkspacesRequest\x1a\x1d.coder.publicapi.v1.Workspace0\x01\x12k\n" +
+	"\x13ListWorkspaceBuilds\x12..coder.publicapi.v1.ListWorkspaceBuildsRequest\x1a\".coder.publicapi.v1.WorkspaceBuild0\x01\x12k\n" +
+	"\x13ListWorkspaceAgents\x12..coder.publicapi.v1.ListWorkspaceAgentsRequest\x1a\".coder.publicapi.v1.WorkspaceAgent0\x01\x12x\n" +
+	"\x17ListWorkspaceAgentStats\x122.coder.publicapi.v1.ListWorkspaceAgentStatsRequest\x1a'.coder.publicapi.v1.WorkspaceAgentStats0\x01B+Z)github.com/coder/coder/v2/publicapi/protob\x06proto3"
+
+var (
+	file_publicapi_proto_publicapi_proto_rawDescOnce sync.Once
+	file_publicapi_proto_publicapi_proto_rawDescData []byte
+)
+
+func file_publicapi_proto_publicapi_proto_rawDescGZIP() []byte {
+	file_publicapi_proto_publicapi_proto_rawDescOnce.Do(func() {
+		file_publicapi_proto_publicapi_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_publicapi_proto_publicapi_proto_rawDesc), len(file_publicapi_proto_publicapi_proto_rawDesc)))
+	})
+	return file_publicapi_proto_publicapi_proto_rawDescData
+}
+
+var file_publicapi_proto_publicapi_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_publicapi_proto_publicapi_proto_goTypes = []any{
+	(*Workspace)(nil),                      // 0: coder.publicapi.v1.Workspace
+	(*WorkspaceBuild)(nil),                 // 1: coder.publicapi.v1.WorkspaceBuild
+	(*WorkspaceAgent)(nil),                 // 2: coder.publicapi.v1.WorkspaceAgent
+	(*WorkspaceAgentStats)(nil),            // 3: coder.publicapi.v1.WorkspaceAgentStats
+	(*GetWorkspaceRequest)(nil),            // 4: coder.publicapi.v1.GetWorkspaceRequest
+	(*ListWorkspacesRequest)(nil),          // 5: coder.publicapi.v1.ListWorkspacesRequest
+	(*ListWorkspaceBuildsRequest)(nil),     // 6: coder.publicapi.v1.ListWorkspaceBuildsRequest
+	(*ListWorkspaceAgentsRequest)(nil),     // 7: coder.publicapi.v1.ListWorkspaceAgentsRequest
+	(*ListWorkspaceAgentStatsRequest)(nil), // 8: coder.publicapi.v1.ListWorkspaceAgentStatsRequest
+	(*timestamppb.Timestamp)(nil),          // 9: google.protobuf.Timestamp
+}
+var file_publicapi_proto_publicapi_proto_depIdxs = []int32{
+	9,  // 0: coder.publicapi.v1.Workspace.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 1: coder.publicapi.v1.Workspace.updated_at:type_name -> google.protobuf.Timestamp
+	9,  // 2: coder.publicapi.v1.Workspace.last_used_at:type_name -> google.protobuf.Timestamp
+	9,  // 3: coder.publicapi.v1.Workspace.dormant_at:type_name -> google.protobuf.Timestamp
+	9,  // 4: coder.publicapi.v1.Workspace.deleting_at:type_name -> google.protobuf.Timestamp
+	9,  // 5: coder.publicapi.v1.Workspace.latest_build_completed_at:type_name -> google.protobuf.Timestamp
+	9,  // 6: coder.publicapi.v1.WorkspaceBuild.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 7: coder.publicapi.v1.WorkspaceBuild.deadline:type_name -> google.protobuf.Timestamp
+	9,  // 8: coder.publicapi.v1.WorkspaceBuild.max_deadline:type_name -> google.protobuf.Timestamp
+	9,  // 9: coder.publicapi.v1.WorkspaceAgent.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 10: coder.publicapi.v1.WorkspaceAgent.first_connected_at:type_name -> google.protobuf.Timestamp
+	9,  // 11: coder.publicapi.v1.WorkspaceAgent.last_connected_at:type_name -> google.protobuf.Timestamp
+	9,  // 12: coder.publicapi.v1.WorkspaceAgent.disconnected_at:type_name -> google.protobuf.Timestamp
+	9,  // 13: coder.publicapi.v1.WorkspaceAgentStats.aggregated_from:type_name -> google.protobuf.Timestamp
+	9,  // 14: coder.publicapi.v1.ListWorkspaceBuildsRequest.since:type_name -> google.protobuf.Timestamp
+	9,  // 15: coder.publicapi.v1.ListWorkspaceAgentStatsRequest.created_after:type_name -> google.protobuf.Timestamp
+	4,  // 16: coder.publicapi.v1.PublicAPI.GetWorkspace:input_type -> coder.publicapi.v1.GetWorkspaceRequest
+	5,  // 17: coder.publicapi.v1.PublicAPI.ListWorkspaces:input_type -> coder.publicapi.v1.ListWorkspacesRequest
+	6,  // 18: coder.publicapi.v1.PublicAPI.ListWorkspaceBuilds:input_type -> coder.publicapi.v1.ListWorkspaceBuildsRequest
+	7,  // 19: coder.publicapi.v1.PublicAPI.ListWorkspaceAgents:input_type -> coder.publicapi.v1.ListWorkspaceAgentsRequest
+	8,  // 20: coder.publicapi.v1.PublicAPI.ListWorkspaceAgentStats:input_type -> coder.publicapi.v1.ListWorkspaceAgentStatsRequest
+	0,  // 21: coder.publicapi.v1.PublicAPI.GetWorkspace:output_type -> coder.publicapi.v1.Workspace
+	0,  // 22: coder.publicapi.v1.PublicAPI.ListWorkspaces:output_type -> coder.publicapi.v1.Workspace
+	1,  // 23: coder.publicapi.v1.PublicAPI.ListWorkspaceBuilds:output_type -> coder.publicapi.v1.WorkspaceBuild
+	2,  // 24: coder.publicapi.v1.PublicAPI.ListWorkspaceAgents:output_type -> coder.publicapi.v1.WorkspaceAgent
+	3,  // 25: coder.publicapi.v1.PublicAPI.ListWorkspaceAgentStats:output_type -> coder.publicapi.v1.WorkspaceAgentStats
+	21, // [21:26] is the sub-list for method output_type
+	16, // [16:21] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_publicapi_proto_publicapi_proto_init() }
+func file_publicapi_proto_publicapi_proto_init() {
+	if File_publicapi_proto_publicapi_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_publicapi_proto_publicapi_proto_rawDesc), len(file_publicapi_proto_publicapi_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_publicapi_proto_publicapi_proto_goTypes,
+		DependencyIndexes: file_publicapi_proto_publicapi_proto_depIdxs,
+		MessageInfos:      file_publicapi_proto_publicapi_proto_msgTypes,
+	}.Build()
+	File_publicapi_proto_publicapi_proto = out.File
+	file_publicapi_proto_publicapi_proto_goTypes = nil
+	file_publicapi_proto_publicapi_proto_depIdxs = nil
+}
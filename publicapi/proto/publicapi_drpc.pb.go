@@ -0,0 +1,383 @@
+// Code generated by protoc-gen-go-drpc. DO NOT EDIT.
+// protoc-gen-go-drpc version: v0.0.33
+// source: publicapi/proto/publicapi.proto
+
+package proto
+
+import (
+	context "context"
+	errors "errors"
+	protojson "google.golang.org/protobuf/encoding/protojson"
+	proto "google.golang.org/protobuf/proto"
+	drpc "storj.io/drpc"
+	drpcerr "storj.io/drpc/drpcerr"
+)
+
+type drpcEncoding_File_publicapi_proto_publicapi_proto struct{}
+
+func (drpcEncoding_File_publicapi_proto_publicapi_proto) Marshal(msg drpc.Message) ([]byte, error) {
+	return proto.Marshal(msg.(proto.Message))
+}
+
+func (drpcEncoding_File_publicapi_proto_publicapi_proto) MarshalAppend(buf []byte, msg drpc.Message) ([]byte, error) {
+	return proto.MarshalOptions{}.MarshalAppend(buf, msg.(proto.Message))
+}
+
+func (drpcEncoding_File_publicapi_proto_publicapi_proto) Unmarshal(buf []byte, msg drpc.Message) error {
+	return proto.Unmarshal(buf, msg.(proto.Message))
+}
+
+func (drpcEncoding_File_publicapi_proto_publicapi_proto) JSONMarshal(msg drpc.Message) ([]byte, error) {
+	return protojson.Marshal(msg.(proto.Message))
+}
+
+func (drpcEncoding_File_publicapi_proto_publicapi_proto) JSONUnmarshal(buf []byte, msg drpc.Message) error {
+	return protojson.Unmarshal(buf, msg.(proto.Message))
+}
+
+type DRPCPublicAPIClient interface {
+	DRPCConn() drpc.Conn
+
+	GetWorkspace(ctx context.Context, in *GetWorkspaceRequest) (*Workspace, error)
+	ListWorkspaces(ctx context.Context, in *ListWorkspacesRequest) (DRPCPublicAPI_ListWorkspacesClient, error)
+	ListWorkspaceBuilds(ctx context.Context, in *ListWorkspaceBuildsRequest) (DRPCPublicAPI_ListWorkspaceBuildsClient, error)
+	ListWorkspaceAgents(ctx context.Context, in *ListWorkspaceAgentsRequest) (DRPCPublicAPI_ListWorkspaceAgentsClient, error)
+	ListWorkspaceAgentStats(ctx context.Context, in *ListWorkspaceAgentStatsRequest) (DRPCPublicAPI_ListWorkspaceAgentStatsClient, error)
+}
+
+type drpcPublicAPIClient struct {
+	cc drpc.Conn
+}
+
+func NewDRPCPublicAPIClient(cc drpc.Conn) DRPCPublicAPIClient {
+	return &drpcPublicAPIClient{cc}
+}
+
+func (c *drpcPublicAPIClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcPublicAPIClient) GetWorkspace(ctx context.Context, in *GetWorkspaceRequest) (*Workspace, error) {
+	out := new(Workspace)
+	err := c.cc.Invoke(ctx, "/coder.publicapi.v1.PublicAPI/GetWorkspace", drpcEncoding_File_publicapi_proto_publicapi_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcPublicAPIClient) ListWorkspaces(ctx context.Context, in *ListWorkspacesRequest) (DRPCPublicAPI_ListWorkspacesClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/coder.publicapi.v1.PublicAPI/ListWorkspaces", drpcEncoding_File_publicapi_proto_publicapi_proto{})
+	if err != nil {
+		return nil, err
+	}
+	x := &drpcPublicAPI_ListWorkspacesClient{stream}
+	if err := x.MsgSend(in, drpcEncoding_File_publicapi_proto_publicapi_proto{}); err != nil {
+		return nil, err
+	}
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DRPCPublicAPI_ListWorkspacesClient interface {
+	drpc.Stream
+	Recv() (*Workspace, error)
+}
+
+type drpcPublicAPI_ListWorkspacesClient struct {
+	drpc.Stream
+}
+
+func (x *drpcPublicAPI_ListWorkspacesClient) GetStream() drpc.Stream {
+	return x.Stream
+}
+
+func (x *drpcPublicAPI_ListWorkspacesClient) Recv() (*Workspace, error) {
+	m := new(Workspace)
+	if err := x.MsgRecv(m, drpcEncoding_File_publicapi_proto_publicapi_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcPublicAPI_ListWorkspacesClient) RecvMsg(m *Workspace) error {
+	return x.MsgRecv(m, drpcEncoding_File_publicapi_proto_publicapi_proto{})
+}
+
+func (c *drpcPublicAPIClient) ListWorkspaceBuilds(ctx context.Context, in *ListWorkspaceBuildsRequest) (DRPCPublicAPI_ListWorkspaceBuildsClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/coder.publicapi.v1.PublicAPI/ListWorkspaceBuilds", drpcEncoding_File_publicapi_proto_publicapi_proto{})
+	if err != nil {
+		return nil, err
+	}
+	x := &drpcPublicAPI_ListWorkspaceBuildsClient{stream}
+	if err := x.MsgSend(in, drpcEncoding_File_publicapi_proto_publicapi_proto{}); err != nil {
+		return nil, err
+	}
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DRPCPublicAPI_ListWorkspaceBuildsClient interface {
+	drpc.Stream
+	Recv() (*WorkspaceBuild, error)
+}
+
+type drpcPublicAPI_ListWorkspaceBuildsClient struct {
+	drpc.Stream
+}
+
+func (x *drpcPublicAPI_ListWorkspaceBuildsClient) GetStream() drpc.Stream {
+	return x.Stream
+}
+
+func (x *drpcPublicAPI_ListWorkspaceBuildsClient) Recv() (*WorkspaceBuild, error) {
+	m := new(WorkspaceBuild)
+	if err := x.MsgRecv(m, drpcEncoding_File_publicapi_proto_publicapi_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcPublicAPI_ListWorkspaceBuildsClient) RecvMsg(m *WorkspaceBuild) error {
+	return x.MsgRecv(m, drpcEncoding_File_publicapi_proto_publicapi_proto{})
+}
+
+func (c *drpcPublicAPIClient) ListWorkspaceAgents(ctx context.Context, in *ListWorkspaceAgentsRequest) (DRPCPublicAPI_ListWorkspaceAgentsClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/coder.publicapi.v1.PublicAPI/ListWorkspaceAgents", drpcEncoding_File_publicapi_proto_publicapi_proto{})
+	if err != nil {
+		return nil, err
+	}
+	x := &drpcPublicAPI_ListWorkspaceAgentsClient{stream}
+	if err := x.MsgSend(in, drpcEncoding_File_publicapi_proto_publicapi_proto{}); err != nil {
+		return nil, err
+	}
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DRPCPublicAPI_ListWorkspaceAgentsClient interface {
+	drpc.Stream
+	Recv() (*WorkspaceAgent, error)
+}
+
+type drpcPublicAPI_ListWorkspaceAgentsClient struct {
+	drpc.Stream
+}
+
+func (x *drpcPublicAPI_ListWorkspaceAgentsClient) GetStream() drpc.Stream {
+	return x.Stream
+}
+
+func (x *drpcPublicAPI_ListWorkspaceAgentsClient) Recv() (*WorkspaceAgent, error) {
+	m := new(WorkspaceAgent)
+	if err := x.MsgRecv(m, drpcEncoding_File_publicapi_proto_publicapi_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcPublicAPI_ListWorkspaceAgentsClient) RecvMsg(m *WorkspaceAgent) error {
+	return x.MsgRecv(m, drpcEncoding_File_publicapi_proto_publicapi_proto{})
+}
+
+func (c *drpcPublicAPIClient) ListWorkspaceAgentStats(ctx context.Context, in *ListWorkspaceAgentStatsRequest) (DRPCPublicAPI_ListWorkspaceAgentStatsClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/coder.publicapi.v1.PublicAPI/ListWorkspaceAgentStats", drpcEncoding_File_publicapi_proto_publicapi_proto{})
+	if err != nil {
+		return nil, err
+	}
+	x := &drpcPublicAPI_ListWorkspaceAgentStatsClient{stream}
+	if err := x.MsgSend(in, drpcEncoding_File_publicapi_proto_publicapi_proto{}); err != nil {
+		return nil, err
+	}
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DRPCPublicAPI_ListWorkspaceAgentStatsClient interface {
+	drpc.Stream
+	Recv() (*WorkspaceAgentStats, error)
+}
+
+type drpcPublicAPI_ListWorkspaceAgentStatsClient struct {
+	drpc.Stream
+}
+
+func (x *drpcPublicAPI_ListWorkspaceAgentStatsClient) GetStream() drpc.Stream {
+	return x.Stream
+}
+
+func (x *drpcPublicAPI_ListWorkspaceAgentStatsClient) Recv() (*WorkspaceAgentStats, error) {
+	m := new(WorkspaceAgentStats)
+	if err := x.MsgRecv(m, drpcEncoding_File_publicapi_proto_publicapi_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcPublicAPI_ListWorkspaceAgentStatsClient) RecvMsg(m *WorkspaceAgentStats) error {
+	return x.MsgRecv(m, drpcEncoding_File_publicapi_proto_publicapi_proto{})
+}
+
+type DRPCPublicAPIServer interface {
+	GetWorkspace(context.Context, *GetWorkspaceRequest) (*Workspace, error)
+	ListWorkspaces(*ListWorkspacesRequest, DRPCPublicAPI_ListWorkspacesStream) error
+	ListWorkspaceBuilds(*ListWorkspaceBuildsRequest, DRPCPublicAPI_ListWorkspaceBuildsStream) error
+	ListWorkspaceAgents(*ListWorkspaceAgentsRequest, DRPCPublicAPI_ListWorkspaceAgentsStream) error
+	ListWorkspaceAgentStats(*ListWorkspaceAgentStatsRequest, DRPCPublicAPI_ListWorkspaceAgentStatsStream) error
+}
+
+type DRPCPublicAPIUnimplementedServer struct{}
+
+func (s *DRPCPublicAPIUnimplementedServer) GetWorkspace(context.Context, *GetWorkspaceRequest) (*Workspace, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPublicAPIUnimplementedServer) ListWorkspaces(*ListWorkspacesRequest, DRPCPublicAPI_ListWorkspacesStream) error {
+	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPublicAPIUnimplementedServer) ListWorkspaceBuilds(*ListWorkspaceBuildsRequest, DRPCPublicAPI_ListWorkspaceBuildsStream) error {
+	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPublicAPIUnimplementedServer) ListWorkspaceAgents(*ListWorkspaceAgentsRequest, DRPCPublicAPI_ListWorkspaceAgentsStream) error {
+	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPublicAPIUnimplementedServer) ListWorkspaceAgentStats(*ListWorkspaceAgentStatsRequest, DRPCPublicAPI_ListWorkspaceAgentStatsStream) error {
+	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+type DRPCPublicAPIDescription struct{}
+
+func (DRPCPublicAPIDescription) NumMethods() int { return 5 }
+
+func (DRPCPublicAPIDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/coder.publicapi.v1.PublicAPI/GetWorkspace", drpcEncoding_File_publicapi_proto_publicapi_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPublicAPIServer).
+					GetWorkspace(
+						ctx,
+						in1.(*GetWorkspaceRequest),
+					)
+			}, DRPCPublicAPIServer.GetWorkspace, true
+	case 1:
+		return "/coder.publicapi.v1.PublicAPI/ListWorkspaces", drpcEncoding_File_publicapi_proto_publicapi_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCPublicAPIServer).
+					ListWorkspaces(
+						in1.(*ListWorkspacesRequest),
+						&drpcPublicAPI_ListWorkspacesStream{in2.(drpc.Stream)},
+					)
+			}, DRPCPublicAPIServer.ListWorkspaces, true
+	case 2:
+		return "/coder.publicapi.v1.PublicAPI/ListWorkspaceBuilds", drpcEncoding_File_publicapi_proto_publicapi_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCPublicAPIServer).
+					ListWorkspaceBuilds(
+						in1.(*ListWorkspaceBuildsRequest),
+						&drpcPublicAPI_ListWorkspaceBuildsStream{in2.(drpc.Stream)},
+					)
+			}, DRPCPublicAPIServer.ListWorkspaceBuilds, true
+	case 3:
+		return "/coder.publicapi.v1.PublicAPI/ListWorkspaceAgents", drpcEncoding_File_publicapi_proto_publicapi_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCPublicAPIServer).
+					ListWorkspaceAgents(
+						in1.(*ListWorkspaceAgentsRequest),
+						&drpcPublicAPI_ListWorkspaceAgentsStream{in2.(drpc.Stream)},
+					)
+			}, DRPCPublicAPIServer.ListWorkspaceAgents, true
+	case 4:
+		return "/coder.publicapi.v1.PublicAPI/ListWorkspaceAgentStats", drpcEncoding_File_publicapi_proto_publicapi_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCPublicAPIServer).
+					ListWorkspaceAgentStats(
+						in1.(*ListWorkspaceAgentStatsRequest),
+						&drpcPublicAPI_ListWorkspaceAgentStatsStream{in2.(drpc.Stream)},
+					)
+			}, DRPCPublicAPIServer.ListWorkspaceAgentStats, true
+	default:
+		return "", nil, nil, nil, false
+	}
+}
+
+func DRPCRegisterPublicAPI(mux drpc.Mux, impl DRPCPublicAPIServer) error {
+	return mux.Register(impl, DRPCPublicAPIDescription{})
+}
+
+type DRPCPublicAPI_GetWorkspaceStream interface {
+	drpc.Stream
+	SendAndClose(*Workspace) error
+}
+
+type drpcPublicAPI_GetWorkspaceStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPublicAPI_GetWorkspaceStream) SendAndClose(m *Workspace) error {
+	if err := x.MsgSend(m, drpcEncoding_File_publicapi_proto_publicapi_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCPublicAPI_ListWorkspacesStream interface {
+	drpc.Stream
+	Send(*Workspace) error
+}
+
+type drpcPublicAPI_ListWorkspacesStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPublicAPI_ListWorkspacesStream) Send(m *Workspace) error {
+	return x.MsgSend(m, drpcEncoding_File_publicapi_proto_publicapi_proto{})
+}
+
+type DRPCPublicAPI_ListWorkspaceBuildsStream interface {
+	drpc.Stream
+	Send(*WorkspaceBuild) error
+}
+
+type drpcPublicAPI_ListWorkspaceBuildsStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPublicAPI_ListWorkspaceBuildsStream) Send(m *WorkspaceBuild) error {
+	return x.MsgSend(m, drpcEncoding_File_publicapi_proto_publicapi_proto{})
+}
+
+type DRPCPublicAPI_ListWorkspaceAgentsStream interface {
+	drpc.Stream
+	Send(*WorkspaceAgent) error
+}
+
+type drpcPublicAPI_ListWorkspaceAgentsStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPublicAPI_ListWorkspaceAgentsStream) Send(m *WorkspaceAgent) error {
+	return x.MsgSend(m, drpcEncoding_File_publicapi_proto_publicapi_proto{})
+}
+
+type DRPCPublicAPI_ListWorkspaceAgentStatsStream interface {
+	drpc.Stream
+	Send(*WorkspaceAgentStats) error
+}
+
+type drpcPublicAPI_ListWorkspaceAgentStatsStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPublicAPI_ListWorkspaceAgentStatsStream) Send(m *WorkspaceAgentStats) error {
+	return x.MsgSend(m, drpcEncoding_File_publicapi_proto_publicapi_proto{})
+}
@@ -0,0 +1,17 @@
+package proto
+
+import "github.com/coder/coder/v2/apiversion"
+
+// Version history:
+//
+// API v1.0:
+//   - Initial release: GetWorkspace, ListWorkspaces, ListWorkspaceBuilds,
+//     ListWorkspaceAgents and ListWorkspaceAgentStats.
+const (
+	CurrentMajor = 1
+	CurrentMinor = 0
+)
+
+// CurrentVersion is the current public API version. Breaking changes to the
+// service must bump the major version.
+var CurrentVersion = apiversion.New(CurrentMajor, CurrentMinor)
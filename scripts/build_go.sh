@@ -2,7 +2,7 @@
 
 # This script builds a single Go binary of Coder with the given parameters.
 #
-# Usage: ./build_go.sh [--version 1.2.3-devel+abcdef] [--os linux] [--arch amd64] [--output path/to/output] [--slim] [--agpl] [--boringcrypto] [--dylib]
+# Usage: ./build_go.sh [--version 1.2.3-devel+abcdef] [--os linux] [--arch amd64] [--output path/to/output] [--slim] [--agpl] [--boringcrypto] [--fips] [--dylib]
 #
 # Defaults to linux:amd64 with slim disabled, but can be controlled with GOOS,
 # GOARCH and CODER_SLIM_BUILD=1. If no version is specified, defaults to the
@@ -29,6 +29,11 @@
 # If the --boringcrypto parameter is specified, builds use boringcrypto instead of
 # the standard go crypto libraries.
 #
+# If the --fips parameter is specified, the binary runs the Go Cryptographic
+# Module in FIPS 140-3 mode and Coder only allows FIPS approved settings. Set
+# GOFIPS140 to build against a validated version of the module. This cannot be
+# combined with --boringcrypto.
+#
 # If the --dylib parameter is specified, the Coder Desktop `.dylib` is built
 # instead of the standard binary. This is only supported on macOS arm64 & amd64.
 
@@ -46,13 +51,14 @@ sign_darwin="${CODER_SIGN_DARWIN:-0}"
 sign_windows="${CODER_SIGN_WINDOWS:-0}"
 sign_gpg="${CODER_SIGN_GPG:-0}"
 boringcrypto=${CODER_BUILD_BORINGCRYPTO:-0}
+fips=${CODER_BUILD_FIPS:-0}
 dylib=0
 windows_resources="${CODER_WINDOWS_RESOURCES:-0}"
 debug=0
 
 bin_ident="com.coder.cli"
 
-args="$(getopt -o "" -l version:,os:,arch:,output:,slim,agpl,sign-darwin,sign-windows,boringcrypto,dylib,windows-resources,debug -- "$@")"
+args="$(getopt -o "" -l version:,os:,arch:,output:,slim,agpl,sign-darwin,sign-windows,boringcrypto,fips,dylib,windows-resources,debug -- "$@")"
 eval set -- "$args"
 while true; do
 	case "$1" in
@@ -97,6 +103,10 @@ while true; do
 		boringcrypto=1
 		shift
 		;;
+	--fips)
+		fips=1
+		shift
+		;;
 	--dylib)
 		dylib=1
 		shift
@@ -119,6 +129,10 @@ while true; do
 	esac
 done
 
+if [[ "$boringcrypto" == 1 ]] && [[ "$fips" == 1 ]]; then
+	error "--boringcrypto and --fips cannot be used together"
+fi
+
 cdroot
 
 # Remove the "v" prefix.
@@ -152,11 +166,14 @@ fi
 # We use ts_omit_aws here because on Linux it prevents Tailscale from importing
 # github.com/aws/aws-sdk-go-v2/aws, which adds 7 MB to the binary.
 TS_EXTRA_SMALL="ts_omit_aws,ts_omit_bird,ts_omit_tap,ts_omit_kube"
+build_tags="embed,$TS_EXTRA_SMALL"
 if [[ "$slim" == 1 || "$dylib" == 1 ]]; then
-	build_args+=(-tags "slim,$TS_EXTRA_SMALL")
-else
-	build_args+=(-tags "embed,$TS_EXTRA_SMALL")
+	build_tags="slim,$TS_EXTRA_SMALL"
+fi
+if [[ "$fips" == 1 ]]; then
+	build_tags+=",fips"
 fi
+build_args+=(-tags "$build_tags")
 if [[ "$agpl" == 1 ]]; then
 	# We don't use a tag to control AGPL because we don't want code to depend on
 	# a flag to control AGPL vs. enterprise behavior.
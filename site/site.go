@@ -1130,6 +1130,12 @@ type RenderOAuthAllowData struct {
 	CancelURI   string
 	RedirectURI string
 	Username    string
+	// UserCode is shown when approving a device, so users can check it matches
+	// the code displayed on the device.
+	UserCode string
+	// PromptUserCode renders a form to enter the code displayed on a device
+	// instead of the consent form.
+	PromptUserCode bool
 }
 
 // RenderOAuthAllowPage renders the static page for a user to "Allow" an create
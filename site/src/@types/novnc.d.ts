@@ -0,0 +1,41 @@
+// noVNC doesn't ship type definitions, these cover the parts of the RFB API
+// that the web desktop uses.
+declare module "@novnc/novnc/core/rfb" {
+	interface RFBOptions {
+		shared?: boolean;
+		credentials?: { username?: string; password?: string; target?: string };
+		wsProtocols?: string[];
+	}
+
+	interface RFBEventMap {
+		connect: CustomEvent<Record<string, never>>;
+		disconnect: CustomEvent<{ clean: boolean }>;
+		credentialsrequired: CustomEvent<{ types: string[] }>;
+		securityfailure: CustomEvent<{ status: number; reason?: string }>;
+		clipboard: CustomEvent<{ text: string }>;
+		desktopname: CustomEvent<{ name: string }>;
+	}
+
+	export default class RFB extends EventTarget {
+		constructor(target: HTMLElement, url: string, options?: RFBOptions);
+
+		scaleViewport: boolean;
+		resizeSession: boolean;
+		focusOnClick: boolean;
+		background: string;
+
+		addEventListener<K extends keyof RFBEventMap>(
+			type: K,
+			listener: (event: RFBEventMap[K]) => void,
+		): void;
+		removeEventListener<K extends keyof RFBEventMap>(
+			type: K,
+			listener: (event: RFBEventMap[K]) => void,
+		): void;
+
+		disconnect(): void;
+		sendCredentials(credentials: { password: string }): void;
+		clipboardPasteFrom(text: string): void;
+		focus(): void;
+	}
+}
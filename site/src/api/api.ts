@@ -321,6 +321,24 @@ function normalizeGetTemplatesOptions(
 	return params;
 }
 
+/**
+ * Latencies measured by the browser, keyed by region ID. coderd uses them to
+ * pick which healthy region an unhealthy region fails over to.
+ */
+export type RegionLatencyHints = Readonly<Record<string, number>>;
+
+function regionLatencyHintHeaders(
+	latencies: RegionLatencyHints = {},
+): Record<string, string> {
+	const hint = Object.entries(latencies)
+		.map(([id, ms]) => `${id}=${Math.round(ms)}`)
+		.join(",");
+	if (hint === "") {
+		return {};
+	}
+	return { [TypesGen.RegionLatencyHintHeader]: hint };
+}
+
 type SearchParamOptions = TypesGen.Pagination & {
 	q?: string;
 };
@@ -431,6 +449,28 @@ class ApiMethods {
 		return response.data;
 	};
 
+	loginWithWebAuthn = async (
+		request: TypesGen.LoginWithWebAuthnRequest,
+	): Promise<TypesGen.LoginWithPasswordResponse> => {
+		const response = await this.axios.post<TypesGen.LoginWithPasswordResponse>(
+			"/api/v2/users/login/webauthn",
+			request,
+		);
+
+		return response.data;
+	};
+
+	loginWithWebAuthnEnrollment = async (
+		request: TypesGen.CreateWebAuthnCredentialRequest,
+	): Promise<TypesGen.LoginWithPasswordResponse> => {
+		const response = await this.axios.post<TypesGen.LoginWithPasswordResponse>(
+			"/api/v2/users/login/webauthn/enroll",
+			request,
+		);
+
+		return response.data;
+	};
+
 	convertToOAUTH = async (request: TypesGen.ConvertLoginRequest) => {
 		const response = await this.axios.post<TypesGen.OAuthConversionResponse>(
 			"/api/v2/users/me/convert-login",
@@ -1973,6 +2013,15 @@ class ApiMethods {
 		return response.data;
 	};
 
+	getWorkspaceAgentDesktop = async (
+		agentID: string,
+	): Promise<TypesGen.WorkspaceAgentDesktop> => {
+		const response = await this.axios.get(
+			`/api/v2/workspaceagents/${agentID}/desktop`,
+		);
+		return response.data;
+	};
+
 	getWorkspaceAgentSharedPorts = async (
 		workspaceID: string,
 	): Promise<TypesGen.WorkspaceAgentPortShares> => {
@@ -2035,23 +2084,24 @@ class ApiMethods {
 		return response.data;
 	};
 
-	getWorkspaceProxyRegions = async (): Promise<
-		TypesGen.RegionsResponse<TypesGen.Region>
-	> => {
-		const response =
-			await this.axios.get<TypesGen.RegionsResponse<TypesGen.Region>>(
-				"/api/v2/regions",
-			);
+	getWorkspaceProxyRegions = async (
+		latencies?: RegionLatencyHints,
+	): Promise<TypesGen.RegionsResponse<TypesGen.Region>> => {
+		const response = await this.axios.get<
+			TypesGen.RegionsResponse<TypesGen.Region>
+		>("/api/v2/regions", { headers: regionLatencyHintHeaders(latencies) });
 
 		return response.data;
 	};
 
-	getWorkspaceProxies = async (): Promise<
-		TypesGen.RegionsResponse<TypesGen.WorkspaceProxy>
-	> => {
+	getWorkspaceProxies = async (
+		latencies?: RegionLatencyHints,
+	): Promise<TypesGen.RegionsResponse<TypesGen.WorkspaceProxy>> => {
 		const response = await this.axios.get<
 			TypesGen.RegionsResponse<TypesGen.WorkspaceProxy>
-		>("/api/v2/workspaceproxies");
+		>("/api/v2/workspaceproxies", {
+			headers: regionLatencyHintHeaders(latencies),
+		});
 
 		return response.data;
 	};
@@ -2507,6 +2557,13 @@ class ApiMethods {
 		return res.data;
 	};
 
+	workspaceBuildQueue = async (workspaceBuildId: string) => {
+		const res = await this.axios.get<TypesGen.WorkspaceBuildQueue>(
+			`/api/v2/workspacebuilds/${workspaceBuildId}/queue`,
+		);
+		return res.data;
+	};
+
 	getProvisionerJobs = async (
 		orgId: string,
 		params: GetProvisionerJobsParams = {},
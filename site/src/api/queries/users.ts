@@ -1,8 +1,11 @@
 import { API } from "api/api";
 import type {
 	AuthorizationRequest,
+	AuthorizationResponse,
 	GenerateAPIKeyResponse,
 	GetUsersResponse,
+	LoginMFAChallenge,
+	LoginWithWebAuthnRequest,
 	RequestOneTimePasscodeRequest,
 	UpdateUserAppearanceSettingsRequest,
 	UpdateUserPasswordRequest,
@@ -155,6 +158,25 @@ export const hasFirstUser = (userMetadata: MetadataState<User>) => {
 	});
 };
 
+type LoginResult = {
+	user?: User;
+	permissions?: AuthorizationResponse;
+	// mfa is set instead of the user when the login requires a second factor.
+	mfa?: LoginMFAChallenge;
+};
+
+const setSignedInUser = (
+	queryClient: QueryClient,
+	authorization: AuthorizationRequest,
+	data: LoginResult,
+) => {
+	queryClient.setQueryData(["me"], data.user);
+	queryClient.setQueryData(
+		getAuthorizationKey(authorization),
+		data.permissions,
+	);
+};
+
 export const login = (
 	authorization: AuthorizationRequest,
 	queryClient: QueryClient,
@@ -162,12 +184,10 @@ export const login = (
 	return {
 		mutationFn: async (credentials: { email: string; password: string }) =>
 			loginFn({ ...credentials, authorization }),
-		onSuccess: async (data: Awaited<ReturnType<typeof loginFn>>) => {
-			queryClient.setQueryData(["me"], data.user);
-			queryClient.setQueryData(
-				getAuthorizationKey(authorization),
-				data.permissions,
-			);
+		onSuccess: async (data: LoginResult) => {
+			if (data.user) {
+				setSignedInUser(queryClient, authorization, data);
+			}
 		},
 	};
 };
@@ -180,8 +200,59 @@ const loginFn = async ({
 	email: string;
 	password: string;
 	authorization: AuthorizationRequest;
-}) => {
-	await API.login(email, password);
+}): Promise<LoginResult> => {
+	const response = await API.login(email, password);
+	if (response.mfa) {
+		return { mfa: response.mfa };
+	}
+	return fetchSignedInUser(authorization);
+};
+
+/**
+ * Completes a password login that requires a second factor with a security
+ * key or a recovery code.
+ */
+export const loginWithWebAuthn = (
+	authorization: AuthorizationRequest,
+	queryClient: QueryClient,
+) => {
+	return {
+		mutationFn: async (request: LoginWithWebAuthnRequest) => {
+			await API.loginWithWebAuthn(request);
+			return fetchSignedInUser(authorization);
+		},
+		onSuccess: async (data: LoginResult) => {
+			setSignedInUser(queryClient, authorization, data);
+		},
+	};
+};
+
+/**
+ * Completes a password login by registering the first security key of the
+ * user. The session is only picked up with completeLogin, so that the recovery
+ * codes can be shown first.
+ */
+export const loginWithWebAuthnEnrollment = () => {
+	return {
+		mutationFn: API.loginWithWebAuthnEnrollment,
+	};
+};
+
+export const completeLogin = (
+	authorization: AuthorizationRequest,
+	queryClient: QueryClient,
+) => {
+	return {
+		mutationFn: () => fetchSignedInUser(authorization),
+		onSuccess: async (data: LoginResult) => {
+			setSignedInUser(queryClient, authorization, data);
+		},
+	};
+};
+
+const fetchSignedInUser = async (
+	authorization: AuthorizationRequest,
+): Promise<LoginResult> => {
 	const [user, permissions] = await Promise.all([
 		API.getAuthenticatedUser(),
 		API.checkAuthorization(authorization),
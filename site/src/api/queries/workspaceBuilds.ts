@@ -68,3 +68,16 @@ export const workspaceBuildTimings = (workspaceBuildId: string) => {
 		queryFn: () => API.workspaceBuildTimings(workspaceBuildId),
 	};
 };
+
+export const workspaceBuildQueueKey = (workspaceBuildId: string) => [
+	"workspaceBuilds",
+	workspaceBuildId,
+	"queue",
+];
+
+export const workspaceBuildQueue = (workspaceBuildId: string) => {
+	return {
+		queryKey: workspaceBuildQueueKey(workspaceBuildId),
+		queryFn: () => API.workspaceBuildQueue(workspaceBuildId),
+	};
+};
@@ -9,6 +9,29 @@ export interface ACLAvailable {
 // From codersdk/aitasks.go
 export const AITaskPromptParameterName = "AI Prompt";
 
+// From codersdk/aitasks.go
+export interface AITaskQueueEntry {
+	readonly id: string;
+	readonly owner_id: string;
+	readonly organization_id: string;
+	readonly template_version_id: string;
+	readonly template_version_preset_id?: string;
+	readonly name: string;
+	readonly prompt: string;
+	readonly status: AITaskQueueEntryStatus;
+	readonly position: number;
+	readonly error?: string;
+	readonly created_at: string;
+}
+
+// From codersdk/aitasks.go
+export type AITaskQueueEntryStatus = "failed" | "queued";
+
+export const AITaskQueueEntryStatuses: AITaskQueueEntryStatus[] = [
+	"failed",
+	"queued",
+];
+
 // From codersdk/aitasks.go
 export interface AITasksPromptsResponse {
 	readonly prompts: Record<string, string>;
@@ -29,15 +52,27 @@ export interface APIKey {
 }
 
 // From codersdk/apikey.go
-export type APIKeyScope = "all" | "application_connect";
+export type APIKeyScope = "all" | "application_connect" | "workspace_connect";
 
-export const APIKeyScopes: APIKeyScope[] = ["all", "application_connect"];
+export const APIKeyScopes: APIKeyScope[] = [
+	"all",
+	"application_connect",
+	"workspace_connect",
+];
 
 // From codersdk/apikey.go
 export interface APIKeyWithOwner extends APIKey {
 	readonly username: string;
 }
 
+// From codersdk/apikey.go
+export interface APISession extends APIKey {
+	readonly ip_address: string;
+	readonly user_agent: string;
+	readonly device_fingerprint: string;
+	readonly current: boolean;
+}
+
 // From healthsdk/healthsdk.go
 export interface AccessURLReport extends BaseReport {
 	readonly healthy: boolean;
@@ -47,6 +82,43 @@ export interface AccessURLReport extends BaseReport {
 	readonly healthz_response: string;
 }
 
+// From codersdk/activity.go
+export interface ActivityEvent {
+	readonly id: string;
+	readonly time: string;
+	readonly type: ActivityEventType;
+	readonly organization_id: string;
+	readonly user_id: string;
+	readonly workspace_id?: string;
+	readonly resource_type: string;
+	readonly resource_id: string;
+	readonly resource_name: string;
+	readonly action: string;
+	readonly status?: string;
+}
+
+// From codersdk/activity.go
+export type ActivityEventType = "audit" | "build" | "lifecycle";
+
+export const ActivityEventTypes: ActivityEventType[] = [
+	"audit",
+	"build",
+	"lifecycle",
+];
+
+// From codersdk/activity.go
+export interface ActivityFeedFilter {
+	readonly types?: readonly ActivityEventType[];
+	readonly organization_id?: string;
+	readonly workspace_id?: string;
+	readonly user_id?: string;
+}
+
+// From codersdk/activity.go
+export interface ActivityFeedResponse {
+	readonly events: readonly ActivityEvent[];
+}
+
 // From codersdk/licenses.go
 export interface AddLicenseRequest {
 	readonly license: string;
@@ -89,6 +161,28 @@ export const AgentSubsystems: AgentSubsystem[] = [
 	"exectrace",
 ];
 
+// From codersdk/versionskew.go
+export interface AgentVersionSkew {
+	readonly workspace_id: string;
+	readonly workspace_name: string;
+	readonly workspace_owner: string;
+	readonly agent_id: string;
+	readonly agent_name: string;
+	readonly version: string;
+	readonly outdated: boolean;
+}
+
+// From codersdk/deployment.go
+export interface AlertingConfig {
+	readonly pagerduty_routing_key: string;
+	readonly opsgenie_api_key: string;
+	readonly opsgenie_api_url: string;
+	readonly interval: number;
+	readonly database_latency_threshold: number;
+	readonly provisioner_queue_threshold: number;
+	readonly license_expiry_threshold: number;
+}
+
 // From codersdk/deployment.go
 export interface AppHostResponse {
 	readonly host: string;
@@ -128,9 +222,12 @@ export type AuditAction =
 	| "create"
 	| "delete"
 	| "disconnect"
+	| "egress_violation"
 	| "login"
 	| "logout"
+	| "network_zone_violation"
 	| "open"
+	| "read"
 	| "register"
 	| "request_password_reset"
 	| "start"
@@ -143,9 +240,12 @@ export const AuditActions: AuditAction[] = [
 	"create",
 	"delete",
 	"disconnect",
+	"egress_violation",
 	"login",
 	"logout",
+	"network_zone_violation",
 	"open",
+	"read",
 	"register",
 	"request_password_reset",
 	"start",
@@ -165,6 +265,17 @@ export interface AuditDiffField {
 	readonly secret: boolean;
 }
 
+// From codersdk/auditretention.go
+export interface AuditLegalHold {
+	readonly id: string;
+	readonly organization_id: string;
+	readonly user_id?: string;
+	readonly workspace_id?: string;
+	readonly reason: string;
+	readonly created_by: string;
+	readonly created_at: string;
+}
+
 // From codersdk/audit.go
 export interface AuditLog {
 	readonly id: string;
@@ -199,6 +310,24 @@ export interface AuditLogsRequest extends Pagination {
 	readonly q?: string;
 }
 
+// From codersdk/auditretention.go
+export interface AuditSettings {
+	readonly retention_days: number;
+}
+
+// From codersdk/deployment.go
+export interface AuditStreamingConfig {
+	readonly splunk_hec_url: string;
+	readonly splunk_hec_token: string;
+	readonly datadog_api_key: string;
+	readonly datadog_site: string;
+	readonly webhook_url: string;
+	readonly webhook_headers: string;
+	readonly syslog_address: string;
+	readonly buffer_size: number;
+	readonly max_attempts: number;
+}
+
 // From codersdk/users.go
 export interface AuthMethod {
 	readonly enabled: boolean;
@@ -210,6 +339,7 @@ export interface AuthMethods {
 	readonly password: AuthMethod;
 	readonly github: GithubAuthMethod;
 	readonly oidc: OIDCAuthMethod;
+	readonly saml: SAMLAuthMethod;
 }
 
 // From codersdk/authorization.go
@@ -275,9 +405,15 @@ export interface BuildInfoResponse {
 }
 
 // From codersdk/workspacebuilds.go
-export type BuildReason = "autostart" | "autostop" | "dormancy" | "initiator";
+export type BuildReason =
+	| "autoarchive"
+	| "autostart"
+	| "autostop"
+	| "dormancy"
+	| "initiator";
 
 export const BuildReasons: BuildReason[] = [
+	"autoarchive",
 	"autostart",
 	"autostop",
 	"dormancy",
@@ -293,6 +429,15 @@ export const BypassRatelimitHeader = "X-Coder-Bypass-Ratelimit";
 // From codersdk/client.go
 export const CLITelemetryHeader = "Coder-CLI-Telemetry";
 
+// From codersdk/versionskew.go
+export interface CLIVersionSkew {
+	readonly user_id: string;
+	readonly username: string;
+	readonly version: string;
+	readonly last_seen_at: string;
+	readonly outdated: boolean;
+}
+
 // From codersdk/workspacebuilds.go
 export interface CancelWorkspaceBuildParams {
 	readonly expect_status?: CancelWorkspaceBuildStatus;
@@ -313,6 +458,18 @@ export interface ChangePasswordWithOneTimePasscodeRequest {
 	readonly one_time_passcode: string;
 }
 
+// From codersdk/prebuilds.go
+export interface ClaimPrebuiltWorkspaceRequest {
+	readonly template_version_preset_id: string;
+	readonly name?: string;
+	readonly ttl_ms?: number;
+}
+
+// From codersdk/workspaceclones.go
+export interface CloneWorkspaceRequest {
+	readonly name: string;
+}
+
 // From codersdk/client.go
 export const CoderDesktopTelemetryHeader = "Coder-Desktop-Telemetry";
 
@@ -322,6 +479,25 @@ export interface ConnectionLatency {
 	readonly p95: number;
 }
 
+// From codersdk/insights.go
+export interface ConnectionQualityClientType {
+	readonly client_type: string;
+	readonly connections: number;
+	readonly p2p_connections: number;
+	readonly derp_connections: number;
+	readonly unknown_connections: number;
+	readonly relay_fallbacks: number;
+	readonly handshake_retries: number;
+	readonly p2p_latency_median_ms: number;
+	readonly derp_latency_median_ms: number;
+}
+
+// From codersdk/insights.go
+export interface ConnectionQualityResponse {
+	readonly generated_at: string;
+	readonly client_types: readonly ConnectionQualityClientType[];
+}
+
 // From codersdk/files.go
 export const ContentTypeTar = "application/x-tar";
 
@@ -334,6 +510,33 @@ export interface ConvertLoginRequest {
 	readonly password: string;
 }
 
+// From codersdk/workspaceclones.go
+export interface CopyWorkspaceDataRequest {
+	readonly source_workspace_id: string;
+	readonly agent_name?: string;
+}
+
+// From codersdk/aitasks.go
+export interface CreateAITaskRequest {
+	readonly template_version_id: string;
+	readonly template_version_preset_id?: string;
+	readonly name: string;
+	readonly prompt: string;
+}
+
+// From codersdk/aitasks.go
+export interface CreateAITaskResponse {
+	readonly workspace?: Workspace;
+	readonly queue_entry?: AITaskQueueEntry;
+}
+
+// From codersdk/auditretention.go
+export interface CreateAuditLegalHoldRequest {
+	readonly user_id?: string;
+	readonly workspace_id?: string;
+	readonly reason: string;
+}
+
 // From codersdk/users.go
 export interface CreateFirstUserRequest {
 	readonly email: string;
@@ -411,6 +614,13 @@ export interface CreateTemplateRequest {
 	readonly template_use_classic_parameter_flow?: boolean;
 }
 
+// From codersdk/secrets.go
+export interface CreateTemplateSecretRequest {
+	readonly name: string;
+	readonly description?: string;
+	readonly value: string;
+}
+
 // From codersdk/templateversions.go
 export interface CreateTemplateVersionDryRunRequest {
 	readonly workspace_name: string;
@@ -431,6 +641,15 @@ export interface CreateTemplateVersionRequest {
 	readonly user_variable_values?: readonly VariableValue[];
 }
 
+// From codersdk/templates.go
+export interface CreateTemplateVersionRolloutRequest {
+	readonly template_version_id: string;
+	readonly percentage: number;
+	readonly group_ids?: readonly string[];
+	readonly failure_threshold_percent: number;
+	readonly minimum_builds?: number;
+}
+
 // From codersdk/audit.go
 export interface CreateTestAuditLogRequest {
 	readonly action?: AuditAction;
@@ -461,6 +680,41 @@ export interface CreateUserRequestWithOrgs {
 	readonly organization_ids: readonly string[];
 }
 
+// From codersdk/secrets.go
+export interface CreateUserSecretRequest {
+	readonly name: string;
+	readonly description?: string;
+	readonly value: string;
+	readonly env_name?: string;
+}
+
+// From codersdk/variablesets.go
+export interface CreateVariableSetRequest {
+	readonly name: string;
+	readonly description?: string;
+	readonly variables?: readonly VariableSetVariable[];
+}
+
+// From codersdk/webauthn.go
+export interface CreateWebAuthnCredentialRequest {
+	readonly challenge_id: string;
+	readonly name: string;
+	readonly attestation: WebAuthnAttestation;
+}
+
+// From codersdk/webauthn.go
+export interface CreateWebAuthnCredentialResponse {
+	readonly credential: WebAuthnCredential;
+	readonly recovery_codes?: readonly string[];
+}
+
+// From codersdk/workspaceadoptions.go
+export interface CreateWorkspaceAdoptionRequest {
+	readonly template_id: string;
+	readonly name: string;
+	readonly ttl_ms?: number;
+}
+
 // From codersdk/workspaces.go
 export interface CreateWorkspaceBuildRequest {
 	readonly template_version_id?: string;
@@ -471,6 +725,23 @@ export interface CreateWorkspaceBuildRequest {
 	readonly rich_parameter_values?: readonly WorkspaceBuildParameter[];
 	readonly log_level?: ProvisionerLogLevel;
 	readonly template_version_preset_id?: string;
+	readonly reason_message?: string;
+	readonly ci_job_url?: string;
+}
+
+// From codersdk/apikey.go
+export interface CreateWorkspaceCredentialRequest {
+	readonly lifetime: number;
+}
+
+// From codersdk/workspaceportsharelinks.go
+export interface CreateWorkspacePortShareLinkRequest {
+	readonly agent_name: string;
+	readonly port: number;
+	readonly protocol: WorkspaceAgentPortShareProtocol;
+	readonly expires_in_ms: number;
+	readonly password?: string;
+	readonly bandwidth_limit_bytes?: number;
 }
 
 // From codersdk/workspaceproxy.go
@@ -490,6 +761,25 @@ export interface CreateWorkspaceRequest {
 	readonly rich_parameter_values?: readonly WorkspaceBuildParameter[];
 	readonly automatic_updates?: AutomaticUpdates;
 	readonly template_version_preset_id?: string;
+	readonly labels?: Record<string, string>;
+	readonly ephemeral?: boolean;
+}
+
+// From codersdk/organizations.go
+export interface CrossOrganizationMember extends OrganizationMemberWithUserData {
+	readonly organization_name: string;
+	readonly organization_display_name: string;
+}
+
+// From codersdk/organizations.go
+export interface CrossOrganizationMembersRequest extends Pagination {
+	readonly q?: string;
+}
+
+// From codersdk/organizations.go
+export interface CrossOrganizationMembersResponse {
+	readonly members: readonly CrossOrganizationMember[];
+	readonly count: number;
 }
 
 // From codersdk/deployment.go
@@ -551,8 +841,12 @@ export interface DERP {
 export interface DERPConfig {
 	readonly block_direct: boolean;
 	readonly force_websockets: boolean;
+	readonly https_fallback: boolean;
 	readonly url: string;
+	readonly url_refresh_interval: number;
 	readonly path: string;
+	readonly region_stun_addresses: string;
+	readonly netcheck_interval: number;
 }
 
 // From healthsdk/healthsdk.go
@@ -607,6 +901,17 @@ export interface DERPServerConfig {
 	readonly relay_url: string;
 }
 
+// From codersdk/derp.go
+export interface DERPSession {
+	readonly id: string;
+	readonly node_key: string;
+	readonly remote_addr: string;
+	readonly websocket: boolean;
+	readonly connected_at: string;
+	readonly bytes_received: number;
+	readonly bytes_sent: number;
+}
+
 // From codersdk/deployment.go
 export interface DangerousConfig {
 	readonly allow_path_app_sharing: boolean;
@@ -626,6 +931,15 @@ export interface DatabaseReport extends BaseReport {
 	readonly threshold_ms: number;
 }
 
+// From codersdk/replicas.go
+export const DefaultReplicaDrainTimeout = 15000000000;
+
+// From codersdk/workspaceagentdesktop.go
+export const DefaultVNCPort = 5900;
+
+// From codersdk/apikey.go
+export const DefaultWorkspaceCredentialLifetime = 3600000000000;
+
 // From codersdk/notifications.go
 export interface DeleteWebpushSubscription {
 	readonly endpoint: string;
@@ -643,6 +957,64 @@ export interface DeploymentConfig {
 	readonly options?: SerpentOptionSet;
 }
 
+// From codersdk/deploymentconfigreload.go
+export interface DeploymentConfigReload {
+	readonly id: string;
+	readonly requested_at: string;
+	readonly replica_id: string;
+	readonly options: readonly DeploymentConfigReloadOption[];
+}
+
+// From codersdk/deploymentconfigreload.go
+export interface DeploymentConfigReloadOption {
+	readonly name: string;
+	readonly restart_required: boolean;
+	readonly replicas: readonly DeploymentConfigReloadReplica[];
+}
+
+// From codersdk/deploymentconfigreload.go
+export interface DeploymentConfigReloadReplica {
+	readonly replica_id: string;
+	readonly error?: string;
+}
+
+// From codersdk/deploymentsettings.go
+export interface DeploymentSettingDrift {
+	readonly setting: string;
+	readonly manifest: string;
+	readonly current: string;
+}
+
+// From codersdk/deploymentsettings.go
+export interface DeploymentSettingsAppearance {
+	readonly application_name?: string;
+	readonly logo_url?: string;
+	readonly announcement_banners?: readonly BannerConfig[];
+}
+
+// From codersdk/deploymentsettings.go
+export interface DeploymentSettingsDrift {
+	readonly path: string;
+	readonly drift: readonly DeploymentSettingDrift[];
+}
+
+// From codersdk/deploymentsettings.go
+export interface DeploymentSettingsManifest {
+	readonly appearance?: DeploymentSettingsAppearance;
+	readonly notifications?: DeploymentSettingsNotifications;
+	readonly prebuilds?: DeploymentSettingsPrebuilds;
+}
+
+// From codersdk/deploymentsettings.go
+export interface DeploymentSettingsNotifications {
+	readonly notifier_paused?: boolean;
+}
+
+// From codersdk/deploymentsettings.go
+export interface DeploymentSettingsPrebuilds {
+	readonly reconciliation_paused?: boolean;
+}
+
 // From codersdk/deployment.go
 export interface DeploymentStats {
 	readonly aggregated_from: string;
@@ -657,6 +1029,8 @@ export interface DeploymentValues {
 	readonly verbose?: boolean;
 	readonly access_url?: string;
 	readonly wildcard_access_url?: string;
+	readonly path_app_access_url?: string;
+	readonly path_app_subdomains?: boolean;
 	readonly docs_url?: string;
 	readonly redirect_to_access_url?: boolean;
 	readonly http_address?: string;
@@ -673,6 +1047,9 @@ export interface DeploymentValues {
 	readonly pg_auth?: string;
 	readonly oauth2?: OAuth2Config;
 	readonly oidc?: OIDCConfig;
+	readonly saml?: SAMLConfig;
+	readonly ldap?: LDAPConfig;
+	readonly audit_streaming?: AuditStreamingConfig;
 	readonly telemetry?: TelemetryConfig;
 	readonly tls?: TLSConfig;
 	readonly trace?: TraceConfig;
@@ -680,11 +1057,18 @@ export interface DeploymentValues {
 	readonly strict_transport_security?: number;
 	readonly strict_transport_security_options?: string;
 	readonly ssh_keygen_algorithm?: string;
+	readonly fips?: boolean;
 	readonly metrics_cache_refresh_interval?: number;
 	readonly agent_stat_refresh_interval?: number;
 	readonly agent_fallback_troubleshooting_url?: string;
+	readonly agent_token_rotation_interval?: number;
+	readonly agent_token_rotation_grace_period?: number;
 	readonly browser_only?: boolean;
+	readonly wireguard?: WireguardConfig;
+	readonly tailnet_coordinator_sharding?: boolean;
 	readonly scim_api_key?: string;
+	readonly scim_groups_flatten_nested?: boolean;
+	readonly scim_groups_dry_run?: boolean;
 	readonly external_token_encryption_keys?: string;
 	readonly provisioner?: ProvisionerConfig;
 	readonly rate_limit?: RateLimitConfig;
@@ -702,23 +1086,59 @@ export interface DeploymentValues {
 	readonly wgtunnel_host?: string;
 	readonly disable_owner_workspace_exec?: boolean;
 	readonly proxy_health_status_interval?: number;
+	readonly proxy_token_rotation_interval?: number;
+	readonly proxy_token_rotation_grace_period?: number;
 	readonly enable_terraform_debug_mode?: boolean;
 	readonly user_quiet_hours_schedule?: UserQuietHoursScheduleConfig;
 	readonly web_terminal_renderer?: string;
+	readonly web_desktop_clipboard?: string;
 	readonly allow_workspace_renames?: boolean;
+	readonly max_workspace_schedule_pause?: number;
+	readonly autostart_holidays?: string;
+	readonly autostart_holiday_calendar_url?: string;
+	readonly workspace_activity_cpu_threshold?: number;
+	readonly workspace_activity_network_threshold?: number;
+	readonly dormant_workspace_archive_url?: string;
+	readonly dormant_workspace_archive_retention?: number;
+	readonly file_storage_url?: string;
 	readonly healthcheck?: HealthcheckConfig;
 	readonly cli_upgrade_message?: string;
+	readonly min_agent_version?: string;
+	readonly min_cli_version?: string;
 	readonly terms_of_service_url?: string;
 	readonly notifications?: NotificationsConfig;
 	readonly additional_csp_policy?: string;
 	readonly workspace_hostname_suffix?: string;
 	readonly workspace_prebuilds?: PrebuildsConfig;
 	readonly hide_ai_tasks?: boolean;
+	readonly ai_tasks_max_concurrent_per_user?: number;
+	readonly port_share_links?: boolean;
+	readonly alerting: AlertingConfig;
+	readonly review_workspaces_webhook_secret?: string;
+	readonly vault: VaultConfig;
+	readonly internal_ca: InternalCAConfig;
+	readonly naming: NamingConfig;
+	readonly network_zones?: SerpentStruct<NetworkZone[]>;
+	readonly settings_file?: string;
 	readonly config?: string;
 	readonly write_config?: boolean;
 	readonly address?: string;
 }
 
+// From codersdk/deployment.go
+export type DesktopClipboardPolicy =
+	| "both"
+	| "from-workspace"
+	| "none"
+	| "to-workspace";
+
+export const DesktopClipboardPolicys: DesktopClipboardPolicy[] = [
+	"both",
+	"from-workspace",
+	"none",
+	"to-workspace",
+];
+
 // From codersdk/parameters.go
 export interface DiagnosticExtra {
 	readonly code: string;
@@ -823,6 +1243,50 @@ export const Experiments: Experiment[] = [
 	"workspace-usage",
 ];
 
+// From codersdk/experimentflags.go
+export interface ExperimentEvaluation {
+	readonly experiment: Experiment;
+	readonly enabled: boolean;
+	readonly source: ExperimentEvaluationSource;
+	readonly organization_id?: string;
+	readonly group_id?: string;
+}
+
+// From codersdk/experimentflags.go
+export interface ExperimentEvaluationResponse {
+	readonly user_id: string;
+	readonly organization_ids: readonly string[];
+	readonly group_ids: readonly string[];
+	readonly evaluations: readonly ExperimentEvaluation[];
+}
+
+// From codersdk/experimentflags.go
+export type ExperimentEvaluationSource =
+	| "default"
+	| "flag"
+	| "startup"
+	| "targeted_flag";
+
+export const ExperimentEvaluationSources: ExperimentEvaluationSource[] = [
+	"default",
+	"flag",
+	"startup",
+	"targeted_flag",
+];
+
+// From codersdk/experimentflags.go
+export interface ExperimentFlag {
+	readonly experiment: Experiment;
+	readonly enabled: boolean;
+	readonly organization_ids?: readonly string[];
+	readonly group_ids?: readonly string[];
+}
+
+// From codersdk/experimentflags.go
+export interface ExperimentFlags {
+	readonly flags: readonly ExperimentFlag[];
+}
+
 // From codersdk/externalauth.go
 export interface ExternalAuth {
 	readonly authenticated: boolean;
@@ -980,7 +1444,9 @@ export interface GenerateAPIKeyResponse {
 
 // From codersdk/inboxnotification.go
 export interface GetInboxNotificationResponse {
+	readonly kind?: InboxNotificationEventKind;
 	readonly notification: InboxNotification;
+	readonly read_status?: InboxNotificationReadStatusChange;
 	readonly unread_count: number;
 }
 
@@ -1037,9 +1503,16 @@ export interface GroupArguments {
 }
 
 // From codersdk/groups.go
-export type GroupSource = "oidc" | "user";
+export interface GroupQuotaAllowance {
+	readonly dimension: string;
+	readonly allowance: number;
+	readonly consumed: number;
+}
+
+// From codersdk/groups.go
+export type GroupSource = "ldap" | "oidc" | "scim" | "user";
 
-export const GroupSources: GroupSource[] = ["oidc", "user"];
+export const GroupSources: GroupSource[] = ["ldap", "oidc", "scim", "user"];
 
 // From codersdk/idpsync.go
 export interface GroupSyncSettings {
@@ -1048,6 +1521,23 @@ export interface GroupSyncSettings {
 	readonly regex_filter: string | null;
 	readonly auto_create_missing_groups: boolean;
 	readonly legacy_group_name_mapping?: Record<string, string>;
+	readonly rules?: readonly IDPSyncRule[];
+	readonly default_groups?: readonly string[];
+}
+
+// From codersdk/insights.go
+export interface GroupWorkspaceUsage {
+	readonly group_id: string;
+	readonly group_name: string;
+	readonly group_display_name: string;
+	readonly organization_id: string;
+	readonly organization_name: string;
+	readonly users: number;
+	readonly workspaces: number;
+	readonly runtime_hours: number;
+	readonly cost: number;
+	readonly dimension_hours: Record<string, number>;
+	readonly last_workspace_used_at: string;
 }
 
 // From codersdk/deployment.go
@@ -1113,6 +1603,19 @@ export interface HealthMessage {
 	readonly message: string;
 }
 
+// From healthsdk/healthsdk.go
+export interface HealthScore {
+	readonly replica_id: string;
+	readonly time: string;
+	readonly score: number;
+	readonly database: number;
+	readonly database_latency_ms: number;
+	readonly derp: number;
+	readonly provisioner_daemons: number;
+	readonly replicas: number;
+	readonly websocket: number;
+}
+
 // From healthsdk/healthsdk.go
 export type HealthSection =
 	| "AccessURL"
@@ -1174,6 +1677,36 @@ export interface IDPSyncMapping<ResourceIdType extends string | string> {
 	readonly Gets: ResourceIdType;
 }
 
+// From codersdk/idpsync.go
+export interface IDPSyncPreviewGroup {
+	readonly id: string;
+	readonly name: string;
+	readonly quota_allowance: number;
+}
+
+// From codersdk/idpsync.go
+export interface IDPSyncPreviewRequest {
+	readonly claims: Record<string, unknown>;
+}
+
+// From codersdk/idpsync.go
+export interface IDPSyncPreviewResponse {
+	readonly group_sync_enabled: boolean;
+	readonly groups: readonly IDPSyncPreviewGroup[];
+	readonly missing_groups: readonly string[];
+	readonly quota_budget: number;
+	readonly role_sync_enabled: boolean;
+	readonly roles: readonly string[];
+	readonly ignored_roles: readonly string[];
+}
+
+// From codersdk/idpsync.go
+export interface IDPSyncRule {
+	readonly match: string | null;
+	readonly replacement?: string;
+	readonly strip_prefix?: string;
+}
+
 // From codersdk/inboxnotification.go
 export interface InboxNotification {
 	readonly id: string;
@@ -1194,6 +1727,20 @@ export interface InboxNotificationAction {
 	readonly url: string;
 }
 
+// From codersdk/inboxnotification.go
+export interface InboxNotificationCategoryUnreadCount {
+	readonly category: NotificationCategory;
+	readonly unread_count: number;
+}
+
+// From codersdk/inboxnotification.go
+export type InboxNotificationEventKind = "new" | "read_status";
+
+export const InboxNotificationEventKinds: InboxNotificationEventKind[] = [
+	"new",
+	"read_status",
+];
+
 // From codersdk/inboxnotification.go
 export const InboxNotificationFallbackIconAccount = "DEFAULT_ICON_ACCOUNT";
 
@@ -1206,6 +1753,19 @@ export const InboxNotificationFallbackIconTemplate = "DEFAULT_ICON_TEMPLATE";
 // From codersdk/inboxnotification.go
 export const InboxNotificationFallbackIconWorkspace = "DEFAULT_ICON_WORKSPACE";
 
+// From codersdk/inboxnotification.go
+export interface InboxNotificationReadStatusChange {
+	readonly ids?: readonly string[];
+	readonly categories?: readonly NotificationCategory[];
+	readonly is_read: boolean;
+}
+
+// From codersdk/inboxnotification.go
+export interface InboxNotificationUnreadCountsResponse {
+	readonly unread_count: number;
+	readonly categories: readonly InboxNotificationCategoryUnreadCount[];
+}
+
 // From codersdk/insights.go
 export type InsightsReportInterval = "day" | "week";
 
@@ -1214,6 +1774,22 @@ export const InsightsReportIntervals: InsightsReportInterval[] = [
 	"week",
 ];
 
+// From codersdk/deployment.go
+export interface InternalCAConfig {
+	readonly enable: boolean;
+	readonly certificate_ttl: number;
+	readonly require_mtls: boolean;
+}
+
+// From codersdk/internalca.go
+export interface IssueCertificateRequest {
+	readonly csr: string;
+}
+
+// From codersdk/internalca.go
+export interface IssueProvisionerDaemonCertificateRequest
+	extends IssueCertificateRequest {}
+
 // From codersdk/workspaceagents.go
 export interface IssueReconnectingPTYSignedTokenRequest {
 	readonly url: string;
@@ -1225,12 +1801,85 @@ export interface IssueReconnectingPTYSignedTokenResponse {
 	readonly signed_token: string;
 }
 
+// From codersdk/internalca.go
+export interface IssuedCertificate {
+	readonly certificate: string;
+	readonly ca_bundle: string;
+	readonly expires_at: string;
+}
+
 // From codersdk/provisionerdaemons.go
 export type JobErrorCode = "REQUIRED_TEMPLATE_VARIABLES";
 
 export const JobErrorCodes: JobErrorCode[] = ["REQUIRED_TEMPLATE_VARIABLES"];
 
-// From codersdk/licenses.go
+// From codersdk/deployment.go
+export interface LDAPConfig {
+	readonly url: string;
+	readonly bind_dn: string;
+	readonly bind_password: string;
+	readonly start_tls: boolean;
+	readonly ca_file: string;
+	readonly user_base_dn: string;
+	readonly user_filter: string;
+	readonly group_base_dn: string;
+	readonly group_filter: string;
+	readonly id_attribute: string;
+	readonly username_attribute: string;
+	readonly email_attribute: string;
+	readonly name_attribute: string;
+	readonly group_name_attribute: string;
+	readonly group_member_attribute: string;
+	readonly login_type: string;
+	readonly sync_interval: number;
+}
+
+// From codersdk/ldap.go
+export interface LDAPSyncChange {
+	readonly type: LDAPSyncChangeType;
+	readonly username?: string;
+	readonly dn?: string;
+	readonly group?: string;
+}
+
+// From codersdk/ldap.go
+export type LDAPSyncChangeType =
+	| "activate_user"
+	| "add_group_member"
+	| "create_group"
+	| "create_user"
+	| "delete_group"
+	| "link_user"
+	| "remove_group_member"
+	| "suspend_user"
+	| "update_user";
+
+export const LDAPSyncChangeTypes: LDAPSyncChangeType[] = [
+	"activate_user",
+	"add_group_member",
+	"create_group",
+	"create_user",
+	"delete_group",
+	"link_user",
+	"remove_group_member",
+	"suspend_user",
+	"update_user",
+];
+
+// From codersdk/ldap.go
+export interface LDAPSyncRequest {
+	readonly dry_run: boolean;
+}
+
+// From codersdk/ldap.go
+export interface LDAPSyncResponse {
+	readonly dry_run: boolean;
+	readonly directory_users: number;
+	readonly directory_groups: number;
+	readonly changes: readonly LDAPSyncChange[];
+}
+
+// From codersdk/licenses.go
 export interface License {
 	readonly id: number;
 	readonly uuid: string;
@@ -1242,10 +1891,42 @@ export interface License {
 // From codersdk/licenses.go
 export const LicenseExpiryClaim = "license_expires";
 
+// From codersdk/licenses.go
+export interface LicenseSeatReservation {
+	readonly group_id: string;
+	readonly group_name: string;
+	readonly organization_id: string;
+	readonly seats: number;
+	readonly active_members: number;
+}
+
 // From codersdk/licenses.go
 export const LicenseTelemetryRequiredErrorText =
 	"License requires telemetry but telemetry is disabled";
 
+// From codersdk/licenses.go
+export interface LicenseUsage {
+	readonly seats?: number;
+	readonly active_users: number;
+	readonly unfilled_reserved_seats: number;
+	readonly history: readonly LicenseUsagePoint[];
+	readonly forecast: readonly LicenseUsagePoint[];
+	readonly exhausted_at?: string;
+	readonly reservations: readonly LicenseSeatReservation[];
+}
+
+// From codersdk/licenses.go
+export interface LicenseUsagePoint {
+	readonly date: string;
+	readonly active_users: number;
+}
+
+// From codersdk/licenses.go
+export interface LicenseUsageRequest {
+	readonly history_days?: number;
+	readonly forecast_days?: number;
+}
+
 // From codersdk/deployment.go
 export interface LinkConfig {
 	readonly name: string;
@@ -1257,6 +1938,7 @@ export interface LinkConfig {
 export interface ListInboxNotificationsRequest {
 	readonly targets?: string;
 	readonly templates?: string;
+	readonly categories?: string;
 	readonly read_status?: string;
 	readonly starting_before?: string;
 }
@@ -1284,6 +1966,29 @@ export const LogLevels: LogLevel[] = [
 	"warn",
 ];
 
+// From codersdk/deployment.go
+export interface LogSink {
+	readonly type: LogSinkType;
+	readonly level?: string;
+	readonly format?: string;
+	readonly path?: string;
+	readonly max_size_mb?: number;
+	readonly max_backups?: number;
+	readonly max_age_days?: number;
+	readonly address?: string;
+	readonly insecure?: boolean;
+}
+
+// From codersdk/deployment.go
+export type LogSinkType = "file" | "journald" | "otlp" | "syslog";
+
+export const LogSinkTypes: LogSinkType[] = [
+	"file",
+	"journald",
+	"otlp",
+	"syslog",
+];
+
 // From codersdk/provisionerdaemons.go
 export type LogSource = "provisioner" | "provisioner_daemon";
 
@@ -1295,16 +2000,31 @@ export interface LoggingConfig {
 	readonly human: string;
 	readonly json: string;
 	readonly stackdriver: string;
+	readonly sinks?: SerpentStruct<LogSink[]>;
+}
+
+// From codersdk/webauthn.go
+export interface LoginMFAChallenge {
+	readonly assertion?: WebAuthnRequestOptions;
+	readonly enrollment?: WebAuthnCreationOptions;
 }
 
 // From codersdk/apikey.go
-export type LoginType = "github" | "none" | "oidc" | "password" | "token" | "";
+export type LoginType =
+	| "github"
+	| "none"
+	| "oidc"
+	| "password"
+	| "saml"
+	| "token"
+	| "";
 
 export const LoginTypes: LoginType[] = [
 	"github",
 	"none",
 	"oidc",
 	"password",
+	"saml",
 	"token",
 	"",
 ];
@@ -1318,6 +2038,25 @@ export interface LoginWithPasswordRequest {
 // From codersdk/users.go
 export interface LoginWithPasswordResponse {
 	readonly session_token: string;
+	readonly mfa?: LoginMFAChallenge;
+	readonly recovery_codes?: readonly string[];
+}
+
+// From codersdk/webauthn.go
+export interface LoginWithWebAuthnRequest {
+	readonly challenge_id: string;
+	readonly assertion?: WebAuthnAssertion;
+	readonly recovery_code?: string;
+}
+
+// From codersdk/webauthn.go
+export interface MFARecoveryCodesResponse {
+	readonly recovery_codes: readonly string[];
+}
+
+// From codersdk/webauthn.go
+export interface MFASettings {
+	readonly require_webauthn: boolean;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -1327,6 +2066,9 @@ export interface MatchedProvisioners {
 	readonly most_recently_seen?: string;
 }
 
+// From codersdk/apikey.go
+export const MaxWorkspaceCredentialLifetime = 86400000000000;
+
 // From codersdk/organizations.go
 export interface MinimalOrganization {
 	readonly id: string;
@@ -1342,6 +2084,33 @@ export interface MinimalUser {
 	readonly avatar_url?: string;
 }
 
+// From codersdk/deployment.go
+export interface NamePolicy {
+	readonly pattern: string;
+	readonly max_length: number;
+	readonly reserved_prefixes: string;
+}
+
+// From codersdk/deployment.go
+export interface NamingConfig {
+	readonly workspace_name: NamePolicy;
+	readonly username: NamePolicy;
+}
+
+// From codersdk/deployment.go
+export interface NamingPolicyViolation {
+	readonly id: string;
+	readonly name: string;
+	readonly owner_name?: string;
+	readonly reason: string;
+}
+
+// From codersdk/deployment.go
+export interface NamingPolicyViolations {
+	readonly workspaces: readonly NamingPolicyViolation[];
+	readonly users: readonly NamingPolicyViolation[];
+}
+
 // From netcheck/netcheck.go
 export interface NetcheckReport {
 	readonly UDP: boolean;
@@ -1365,6 +2134,41 @@ export interface NetcheckReport {
 	readonly CaptivePortal: boolean | null;
 }
 
+// From codersdk/insights.go
+export type NetworkConnectionType = "derp" | "p2p" | "unknown";
+
+export const NetworkConnectionTypes: NetworkConnectionType[] = ["derp", "p2p", "unknown"];
+
+// From codersdk/deployment.go
+export interface NetworkZone {
+	readonly name: string;
+	readonly roles?: readonly string[];
+	readonly group_ids?: readonly string[];
+	readonly cidrs: readonly string[];
+}
+
+// From codersdk/notifications.go
+export type NotificationCategory =
+	| "account"
+	| "builds"
+	| "dormancy"
+	| "template_updates";
+
+export const NotificationCategorys: NotificationCategory[] = [
+	"account",
+	"builds",
+	"dormancy",
+	"template_updates",
+];
+
+// From codersdk/notifications.go
+export interface NotificationCategoryPreference {
+	readonly category: NotificationCategory;
+	readonly method: string;
+	readonly disabled: boolean;
+	readonly updated_at: string;
+}
+
 // From codersdk/notifications.go
 export interface NotificationMethodsResponse {
 	readonly available: readonly string[];
@@ -1389,6 +2193,8 @@ export interface NotificationTemplate {
 	readonly method: string;
 	readonly kind: string;
 	readonly enabled_by_default: boolean;
+	readonly priority: string;
+	readonly category?: NotificationCategory;
 }
 
 // From codersdk/deployment.go
@@ -1402,8 +2208,11 @@ export interface NotificationsConfig {
 	readonly fetch_interval: number;
 	readonly method: string;
 	readonly dispatch_timeout: number;
+	readonly quiet_hours_duration: number;
 	readonly email: NotificationsEmailConfig;
 	readonly webhook: NotificationsWebhookConfig;
+	readonly slack: NotificationsSlackConfig;
+	readonly teams: NotificationsTeamsConfig;
 	readonly inbox: NotificationsInboxConfig;
 }
 
@@ -1445,6 +2254,17 @@ export interface NotificationsSettings {
 	readonly notifier_paused: boolean;
 }
 
+// From codersdk/deployment.go
+export interface NotificationsSlackConfig {
+	readonly bot_token: string;
+	readonly api_url: string;
+}
+
+// From codersdk/deployment.go
+export interface NotificationsTeamsConfig {
+	readonly endpoint: string;
+}
+
 // From codersdk/deployment.go
 export interface NotificationsWebhookConfig {
 	readonly endpoint: string;
@@ -1474,6 +2294,9 @@ export interface OAuth2AuthorizationServerMetadata {
 	readonly code_challenge_methods_supported: readonly string[];
 	readonly scopes_supported?: readonly string[];
 	readonly token_endpoint_auth_methods_supported?: readonly string[];
+	readonly device_authorization_endpoint?: string;
+	readonly introspection_endpoint?: string;
+	readonly revocation_endpoint?: string;
 }
 
 // From codersdk/oauth2.go
@@ -1550,6 +2373,16 @@ export interface OAuth2Config {
 	readonly github: OAuth2GithubConfig;
 }
 
+// From codersdk/oauth2.go
+export interface OAuth2DeviceAuthorizationResponse {
+	readonly device_code: string;
+	readonly user_code: string;
+	readonly verification_uri: string;
+	readonly verification_uri_complete: string;
+	readonly expires_in: number;
+	readonly interval: number;
+}
+
 // From codersdk/oauth2.go
 export interface OAuth2DeviceFlowCallbackResponse {
 	readonly redirect_url: string;
@@ -1604,11 +2437,15 @@ export interface OAuth2ProviderAppSecretFull {
 }
 
 // From codersdk/oauth2.go
-export type OAuth2ProviderGrantType = "authorization_code" | "refresh_token";
+export type OAuth2ProviderGrantType =
+	| "authorization_code"
+	| "refresh_token"
+	| "urn:ietf:params:oauth:grant-type:device_code";
 
 export const OAuth2ProviderGrantTypes: OAuth2ProviderGrantType[] = [
 	"authorization_code",
 	"refresh_token",
+	"urn:ietf:params:oauth:grant-type:device_code",
 ];
 
 // From codersdk/oauth2.go
@@ -1624,6 +2461,19 @@ export const OAuth2RedirectCookie = "oauth_redirect";
 // From codersdk/client.go
 export const OAuth2StateCookie = "oauth_state";
 
+// From codersdk/oauth2.go
+export interface OAuth2TokenIntrospectionResponse {
+	readonly active: boolean;
+	readonly client_id?: string;
+	readonly username?: string;
+	readonly token_type?: string;
+	readonly exp?: number;
+	readonly iat?: number;
+	readonly sub?: string;
+	readonly aud?: string;
+	readonly iss?: string;
+}
+
 // From codersdk/users.go
 export interface OAuthConversionResponse {
 	readonly state_string: string;
@@ -1723,6 +2573,33 @@ export interface OrganizationProvisionerJobsOptions {
 	readonly Tags: Record<string, string>;
 }
 
+// From codersdk/organizationsettings.go
+export interface OrganizationSettingOverrides {
+	readonly default_ttl_ms: number | null;
+	readonly default_quiet_hours_schedule: string | null;
+	readonly allowed_external_auth_providers: readonly string[];
+	readonly disabled_notification_template_ids: readonly string[];
+	readonly provisioner_max_jobs: number | null;
+	readonly provisioner_reserved_jobs: number | null;
+}
+
+// From codersdk/organizationsettings.go
+export interface OrganizationSettingValues {
+	readonly default_ttl_ms: number;
+	readonly default_quiet_hours_schedule: string;
+	readonly allowed_external_auth_providers: readonly string[];
+	readonly disabled_notification_template_ids: readonly string[];
+	readonly provisioner_max_jobs: number;
+	readonly provisioner_reserved_jobs: number;
+}
+
+// From codersdk/organizationsettings.go
+export interface OrganizationSettings {
+	readonly overrides: OrganizationSettingOverrides;
+	readonly deployment: OrganizationSettingValues;
+	readonly effective: OrganizationSettingValues;
+}
+
 // From codersdk/idpsync.go
 export interface OrganizationSyncSettings {
 	readonly field: string;
@@ -1777,6 +2654,34 @@ export const ParameterFormTypes: ParameterFormType[] = [
 	"textarea",
 ];
 
+// From codersdk/richparameters.go
+export interface ParameterValidationRequest {
+	readonly organization_id: string;
+	readonly template_id: string;
+	readonly template_name: string;
+	readonly template_version_id: string;
+	readonly workspace_id: string;
+	readonly workspace_name: string;
+	readonly owner: ParameterValidationUser;
+	readonly initiator: ParameterValidationUser;
+	readonly parameters: readonly WorkspaceBuildParameter[];
+}
+
+// From codersdk/richparameters.go
+export interface ParameterValidationResponse {
+	readonly valid: boolean;
+	readonly message?: string;
+	readonly validations?: readonly ValidationError[];
+}
+
+// From codersdk/richparameters.go
+export interface ParameterValidationUser {
+	readonly id: string;
+	readonly username: string;
+	readonly name: string;
+	readonly email: string;
+}
+
 // From codersdk/idpsync.go
 export interface PatchGroupIDPSyncConfigRequest {
 	readonly field: string;
@@ -1848,6 +2753,12 @@ export interface Permission {
 	readonly action: RBACAction;
 }
 
+// From codersdk/client.go
+export const PortShareLinkCookie = "coder_port_share_link";
+
+// From codersdk/client.go
+export const PortShareLinkQueryParameter = "coder_port_share_link_9c2f1a";
+
 // From codersdk/oauth2.go
 export interface PostOAuth2ProviderAppRequest {
 	readonly name: string;
@@ -1965,6 +2876,15 @@ export interface ProvisionerConfig {
 	readonly daemon_poll_jitter: number;
 	readonly force_cancel_interval: number;
 	readonly daemon_psk: string;
+	readonly template_policy_files: string;
+	readonly max_builds_per_user: number;
+	readonly max_builds_per_template: number;
+	readonly max_concurrent_jobs: number;
+	readonly max_jobs_per_organization: number;
+	readonly reserved_jobs_per_organization: number;
+	readonly orphaned_job_timeout: number;
+	readonly orphaned_job_action: string;
+	readonly orphaned_job_max_requeues: number;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -2060,6 +2980,17 @@ export interface ProvisionerJobLog {
 	readonly output: string;
 }
 
+// From codersdk/organizations.go
+export interface ProvisionerJobLogMatch {
+	readonly log: ProvisionerJobLog;
+	readonly job_id: string;
+	readonly job_type: ProvisionerJobType;
+	readonly workspace_id?: string;
+	readonly workspace_build_id?: string;
+	readonly template_id?: string;
+	readonly template_version_id?: string;
+}
+
 // From codersdk/provisionerdaemons.go
 export interface ProvisionerJobMetadata {
 	readonly template_version_name: string;
@@ -2185,6 +3116,15 @@ export const ProxyHealthStatuses: ProxyHealthStatus[] = [
 	"unregistered",
 ];
 
+// From codersdk/proxypreference.go
+export type ProxySelectionReason = "health" | "latency" | "preference";
+
+export const ProxySelectionReasons: ProxySelectionReason[] = [
+	"health",
+	"latency",
+	"preference",
+];
+
 // From codersdk/workspaces.go
 export interface PutExtendWorkspaceRequest {
 	readonly deadline: string;
@@ -2197,6 +3137,11 @@ export interface PutOAuth2ProviderAppRequest {
 	readonly icon: string;
 }
 
+// From codersdk/workspaces.go
+export interface PutWorkspaceSchedulePauseRequest {
+	readonly paused_until: string;
+}
+
 // From codersdk/rbacresources_gen.go
 export type RBACAction =
 	| "application_connect"
@@ -2342,6 +3287,31 @@ export interface Region {
 	readonly healthy: boolean;
 	readonly path_app_url: string;
 	readonly wildcard_hostname: string;
+	readonly failover_region_id?: string;
+}
+
+// From codersdk/proxypreference.go
+export interface RegionLatency {
+	readonly region_id: string;
+	readonly latency_ms: number;
+	readonly measured_at?: string;
+}
+
+// From codersdk/workspaceproxy.go
+export const RegionLatencyHintHeader = "Coder-Region-Latencies";
+
+// From codersdk/proxypreference.go
+export interface RegionProbe {
+	readonly region_id: string;
+	readonly region_name: string;
+	readonly url: string;
+}
+
+// From codersdk/proxypreference.go
+export interface RegionProbePlan {
+	readonly probes: readonly RegionProbe[];
+	readonly samples: number;
+	readonly timeout_ms: number;
 }
 
 // From codersdk/workspaceproxy.go
@@ -2350,6 +3320,7 @@ export type RegionTypes = Region | WorkspaceProxy;
 // From codersdk/workspaceproxy.go
 export interface RegionsResponse<R extends RegionTypes> {
 	readonly regions: readonly R[];
+	readonly health_ttl_seconds?: number;
 }
 
 // From codersdk/replicas.go
@@ -2363,6 +3334,28 @@ export interface Replica {
 	readonly database_latency: number;
 }
 
+// From healthsdk/healthsdk.go
+export interface ReplicaDiagnostics {
+	readonly replica_id: string;
+	readonly hostname: string;
+	readonly captured_at: string;
+	readonly profiles: Record<string, string>;
+	readonly logs: string;
+	readonly errors: readonly string[];
+}
+
+// From codersdk/replicas.go
+export interface ReplicasRestart {
+	readonly id: string;
+	readonly replicas: readonly string[];
+	readonly rolling: boolean;
+}
+
+// From codersdk/proxypreference.go
+export interface ReportRegionLatenciesRequest {
+	readonly latencies: readonly RegionLatency[];
+}
+
 // From codersdk/users.go
 export interface RequestOneTimePasscodeRequest {
 	readonly email: string;
@@ -2373,6 +3366,18 @@ export interface ResolveAutostartResponse {
 	readonly parameter_mismatch: boolean;
 }
 
+// From codersdk/replicas.go
+export interface RestartReplicasRequest {
+	readonly rolling: boolean;
+	readonly drain_timeout: number;
+}
+
+// From codersdk/workspacearchives.go
+export interface RestoreWorkspaceArchiveRequest {
+	readonly archive_id: string;
+	readonly agent_name?: string;
+}
+
 // From codersdk/audit.go
 export type ResourceType =
 	| "api_key"
@@ -2393,12 +3398,19 @@ export type ResourceType =
 	| "organization_member"
 	| "prebuilds_settings"
 	| "template"
+	| "template_egress_policy"
+	| "template_secret"
 	| "template_version"
+	| "template_version_activation_request"
+	| "template_version_rollout"
 	| "user"
+	| "user_secret"
+	| "variable_set"
 	| "workspace"
 	| "workspace_agent"
 	| "workspace_app"
 	| "workspace_build"
+	| "workspace_port_share_link"
 	| "workspace_proxy";
 
 export const ResourceTypes: ResourceType[] = [
@@ -2420,12 +3432,19 @@ export const ResourceTypes: ResourceType[] = [
 	"organization_member",
 	"prebuilds_settings",
 	"template",
+	"template_egress_policy",
+	"template_secret",
 	"template_version",
+	"template_version_activation_request",
+	"template_version_rollout",
 	"user",
+	"user_secret",
+	"variable_set",
 	"workspace",
 	"workspace_agent",
 	"workspace_app",
 	"workspace_build",
+	"workspace_port_share_link",
 	"workspace_proxy",
 ];
 
@@ -2436,6 +3455,27 @@ export interface Response {
 	readonly validations?: readonly ValidationError[];
 }
 
+// From codersdk/templateversions.go
+export interface ReviewTemplateVersionActivationRequest {
+	readonly approved: boolean;
+	readonly comment?: string;
+}
+
+// From codersdk/reviewworkspaces.go
+export interface ReviewWorkspace {
+	readonly organization_id: string;
+	readonly repository: string;
+	readonly branch: string;
+	readonly workspace_id: string;
+	readonly workspace_name: string;
+	readonly workspace_owner_name: string;
+	readonly latest_build_id: string;
+	readonly status: WorkspaceStatus;
+	readonly status_url: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
 // From codersdk/roles.go
 export interface Role {
 	readonly name: string;
@@ -2478,6 +3518,8 @@ export const RoleOwner = "owner";
 export interface RoleSyncSettings {
 	readonly field: string;
 	readonly mapping: Record<string, string[]>;
+	readonly rules?: readonly IDPSyncRule[];
+	readonly default_roles?: readonly string[];
 }
 
 // From codersdk/rbacroles.go
@@ -2486,6 +3528,41 @@ export const RoleTemplateAdmin = "template-admin";
 // From codersdk/rbacroles.go
 export const RoleUserAdmin = "user-admin";
 
+// From codersdk/workspaces.go
+export interface RollbackWorkspaceRequest {
+	readonly log_level?: ProvisionerLogLevel;
+	readonly reason_message?: string;
+	readonly ci_job_url?: string;
+}
+
+// From codersdk/users.go
+export interface SAMLAuthMethod extends AuthMethod {
+	readonly signInText: string;
+	readonly iconUrl: string;
+}
+
+// From codersdk/deployment.go
+export interface SAMLConfig {
+	readonly idp_metadata_url: string;
+	readonly idp_metadata_file: string;
+	readonly entity_id: string;
+	readonly cert_file: string;
+	readonly key_file: string;
+	readonly allow_signups: boolean;
+	readonly email_domain: string;
+	readonly username_attribute: string;
+	readonly name_attribute: string;
+	readonly email_attribute: string;
+	readonly sign_in_text: string;
+	readonly icon_url: string;
+}
+
+// From codersdk/client.go
+export const SAMLRedirectCookie = "saml_redirect";
+
+// From codersdk/client.go
+export const SAMLRequestIDCookie = "saml_request_id";
+
 // From codersdk/deployment.go
 export interface SSHConfig {
 	readonly DeploymentName: string;
@@ -2506,6 +3583,12 @@ export interface STUNReport {
 	readonly Error: string | null;
 }
 
+// From codersdk/organizations.go
+export interface SearchProvisionerJobLogsRequest {
+	readonly q: string;
+	readonly limit?: number;
+}
+
 // From serpent/serpent.go
 export type SerpentAnnotations = Record<string, string>;
 
@@ -2590,6 +3673,7 @@ export interface SessionLifetime {
 	readonly default_token_lifetime?: number;
 	readonly max_token_lifetime?: number;
 	readonly max_admin_token_lifetime?: number;
+	readonly max_sessions_per_user?: number;
 }
 
 // From codersdk/client.go
@@ -2604,6 +3688,19 @@ export const SignedAppTokenCookie = "coder_signed_app_token";
 // From codersdk/client.go
 export const SignedAppTokenQueryParameter = "coder_signed_app_token_23db1dde";
 
+// From codersdk/roles.go
+export interface SimulateCustomRoleRequest {
+	readonly role: CustomRoleRequest;
+	readonly resource_type: RBACResource;
+	readonly action: RBACAction;
+}
+
+// From codersdk/roles.go
+export interface SimulateCustomRoleResponse {
+	readonly allowed: boolean;
+	readonly matched_permission?: Permission;
+}
+
 // From codersdk/roles.go
 export interface SlimRole {
 	readonly name: string;
@@ -2668,11 +3765,58 @@ export interface TailDERPRegion {
 	readonly Nodes: readonly TailDERPNode[];
 }
 
+// From codersdk/telemetry.go
+export type TelemetryCategory =
+	| "deployment"
+	| "licenses"
+	| "network"
+	| "templates"
+	| "usage"
+	| "users"
+	| "workspaces";
+
+export const TelemetryCategorys: TelemetryCategory[] = [
+	"deployment",
+	"licenses",
+	"network",
+	"templates",
+	"usage",
+	"users",
+	"workspaces",
+];
+
 // From codersdk/deployment.go
 export interface TelemetryConfig {
 	readonly enable: boolean;
 	readonly trace: boolean;
 	readonly url: string;
+	readonly categories: string;
+}
+
+// From codersdk/telemetry.go
+export interface TelemetryPayload {
+	readonly kind: TelemetryPayloadKind;
+	readonly url: string;
+	readonly created_at: string;
+	readonly data: Record<string, string>;
+	readonly status_code: number;
+	readonly error?: string;
+}
+
+// From codersdk/telemetry.go
+export type TelemetryPayloadKind = "deployment" | "snapshot";
+
+export const TelemetryPayloadKinds: TelemetryPayloadKind[] = [
+	"deployment",
+	"snapshot",
+];
+
+// From codersdk/telemetry.go
+export interface TelemetryReport {
+	readonly enabled: boolean;
+	readonly url: string;
+	readonly categories: readonly TelemetryCategory[];
+	readonly payloads: readonly TelemetryPayload[];
 }
 
 // From codersdk/templates.go
@@ -2709,6 +3853,15 @@ export interface Template {
 	readonly require_active_version: boolean;
 	readonly max_port_share_level: WorkspaceAgentPortShareLevel;
 	readonly use_classic_parameter_flow: boolean;
+	readonly wireguard_mtu: number;
+	readonly wireguard_keepalive_interval_ms: number;
+	readonly wireguard_handshake_timeout_ms: number;
+	readonly activation_approvals_required: number;
+	readonly autostart_holidays: readonly string[];
+	readonly autostart_holiday_calendar_url: string;
+	readonly dormant_archive_paths: readonly string[];
+	readonly parameter_validation_url: string;
+	readonly allow_start_on_connect: boolean;
 }
 
 // From codersdk/templates.go
@@ -2717,6 +3870,25 @@ export interface TemplateACL {
 	readonly group: readonly TemplateGroup[];
 }
 
+// From codersdk/templateapprestrictions.go
+export interface TemplateAppRestriction {
+	readonly template_id: string;
+	readonly app_slug: string;
+	readonly group_ids: readonly string[];
+	readonly roles: readonly string[];
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
+// From codersdk/templateappsessionpolicies.go
+export interface TemplateAppSessionPolicy {
+	readonly template_id: string;
+	readonly app_slug?: string;
+	readonly max_session_duration_ms: number;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
 // From codersdk/insights.go
 export interface TemplateAppUsage {
 	readonly template_ids: readonly string[];
@@ -2744,18 +3916,74 @@ export interface TemplateAutostopRequirement {
 	readonly weeks: number;
 }
 
-// From codersdk/templates.go
-export type TemplateBuildTimeStats = Record<
-	WorkspaceTransition,
-	TransitionStats
->;
-
 // From codersdk/insights.go
-export const TemplateBuiltinAppDisplayNameJetBrains = "JetBrains";
+export interface TemplateBuildFailureGroup {
+	readonly pattern: string;
+	readonly example: string;
+	readonly builds: number;
+	readonly last_failed_at: string;
+}
 
 // From codersdk/insights.go
-export const TemplateBuiltinAppDisplayNameSFTP = "SFTP";
-
+export interface TemplateBuildInsightsIntervalReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly interval: InsightsReportInterval;
+	readonly succeeded_builds: number;
+	readonly failed_builds: number;
+	readonly canceled_builds: number;
+	readonly failure_rate: number;
+}
+
+// From codersdk/insights.go
+export interface TemplateBuildInsightsRequest {
+	readonly template_id: string;
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly interval: InsightsReportInterval;
+}
+
+// From codersdk/insights.go
+export interface TemplateBuildInsightsResponse {
+	readonly template_id: string;
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly interval_reports: readonly TemplateBuildInsightsIntervalReport[];
+	readonly stage_durations: readonly TemplateBuildStageDuration[];
+	readonly failure_groups: readonly TemplateBuildFailureGroup[];
+}
+
+// From codersdk/insights.go
+export type TemplateBuildStage = "apply" | "graph" | "init" | "plan" | "total";
+
+export const TemplateBuildStages: TemplateBuildStage[] = [
+	"apply",
+	"graph",
+	"init",
+	"plan",
+	"total",
+];
+
+// From codersdk/insights.go
+export interface TemplateBuildStageDuration {
+	readonly stage: TemplateBuildStage;
+	readonly builds: number;
+	readonly p50_seconds: number;
+	readonly p95_seconds: number;
+}
+
+// From codersdk/templates.go
+export type TemplateBuildTimeStats = Record<
+	WorkspaceTransition,
+	TransitionStats
+>;
+
+// From codersdk/insights.go
+export const TemplateBuiltinAppDisplayNameJetBrains = "JetBrains";
+
+// From codersdk/insights.go
+export const TemplateBuiltinAppDisplayNameSFTP = "SFTP";
+
 // From codersdk/insights.go
 export const TemplateBuiltinAppDisplayNameSSH = "SSH";
 
@@ -2765,6 +3993,43 @@ export const TemplateBuiltinAppDisplayNameVSCode = "Visual Studio Code";
 // From codersdk/insights.go
 export const TemplateBuiltinAppDisplayNameWebTerminal = "Web Terminal";
 
+// From codersdk/templatebundles.go
+export interface TemplateBundle {
+	readonly id: string;
+	readonly organization_id: string;
+	readonly name: string;
+	readonly version: string;
+	readonly hash: string;
+	readonly size: number;
+	readonly providers: readonly TemplateBundleProvider[];
+	readonly modules: readonly TemplateBundleModule[];
+	readonly created_by: MinimalUser;
+	readonly created_at: string;
+}
+
+// From codersdk/templatebundles.go
+export interface TemplateBundleModule {
+	readonly key: string;
+	readonly source: string;
+	readonly version?: string;
+}
+
+// From codersdk/templatebundles.go
+export interface TemplateBundleProvider {
+	readonly source: string;
+	readonly version: string;
+	readonly platforms: readonly string[];
+}
+
+// From codersdk/templateegress.go
+export interface TemplateEgressPolicy {
+	readonly template_id: string;
+	readonly allowed_cidrs: readonly string[];
+	readonly allowed_domains: readonly string[];
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
 // From codersdk/templates.go
 export interface TemplateExample {
 	readonly id: string;
@@ -2828,6 +4093,24 @@ export const TemplateInsightsSections: TemplateInsightsSection[] = [
 	"report",
 ];
 
+// From codersdk/templateparameteroptions.go
+export interface TemplateParameterOptionSource {
+	readonly template_id: string;
+	readonly parameter_name: string;
+	readonly url: string;
+	readonly auth_header_name?: string;
+	readonly has_auth_header_value: boolean;
+	readonly cache_ttl_ms: number;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
+// From codersdk/templateparameteroptions.go
+export interface TemplateParameterOptions {
+	readonly options: readonly TemplateVersionParameterOption[];
+	readonly fetched_at: string;
+}
+
 // From codersdk/insights.go
 export interface TemplateParameterUsage {
 	readonly template_ids: readonly string[];
@@ -2850,6 +4133,66 @@ export type TemplateRole = "admin" | "" | "use";
 
 export const TemplateRoles: TemplateRole[] = ["admin", "", "use"];
 
+// From codersdk/templates.go
+export interface TemplateScheduleImpact {
+	readonly summary: readonly TemplateScheduleImpactSummary[];
+	readonly workspaces: readonly TemplateScheduleImpactWorkspace[];
+}
+
+// From codersdk/templates.go
+export type TemplateScheduleImpactAction =
+	| "autostop_ttl"
+	| "delete"
+	| "dormant"
+	| "failed_stop";
+
+export const TemplateScheduleImpactActions: TemplateScheduleImpactAction[] = [
+	"autostop_ttl",
+	"delete",
+	"dormant",
+	"failed_stop",
+];
+
+// From codersdk/templates.go
+export interface TemplateScheduleImpactRequest {
+	readonly default_ttl_ms?: number;
+	readonly allow_user_autostop?: boolean;
+	readonly failure_ttl_ms?: number;
+	readonly time_til_dormant_ms?: number;
+	readonly time_til_dormant_autodelete_ms?: number;
+	readonly update_workspace_last_used_at: boolean;
+	readonly update_workspace_dormant_at: boolean;
+}
+
+// From codersdk/templates.go
+export interface TemplateScheduleImpactSummary {
+	readonly action: TemplateScheduleImpactAction;
+	readonly count: number;
+	readonly immediate: number;
+}
+
+// From codersdk/templates.go
+export interface TemplateScheduleImpactWorkspace {
+	readonly workspace_id: string;
+	readonly workspace_name: string;
+	readonly owner_id: string;
+	readonly owner_name: string;
+	readonly action: TemplateScheduleImpactAction;
+	readonly current_at?: string;
+	readonly at?: string;
+	readonly immediate: boolean;
+}
+
+// From codersdk/secrets.go
+export interface TemplateSecret {
+	readonly id: string;
+	readonly template_id: string;
+	readonly name: string;
+	readonly description: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
 // From codersdk/templates.go
 export interface TemplateUser extends User {
 	readonly role: TemplateRole;
@@ -2872,6 +4215,42 @@ export interface TemplateVersion {
 	readonly matched_provisioners?: MatchedProvisioners;
 }
 
+// From codersdk/templateversions.go
+export interface TemplateVersionActivationRequest {
+	readonly template_version_id: string;
+	readonly template_id: string;
+	readonly requested_by: MinimalUser;
+	readonly status: TemplateVersionActivationRequestStatus;
+	readonly approvals_required: number;
+	readonly reviews: readonly TemplateVersionActivationReview[];
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
+// From codersdk/templateversions.go
+export type TemplateVersionActivationRequestStatus =
+	| "approved"
+	| "canceled"
+	| "pending"
+	| "rejected";
+
+export const TemplateVersionActivationRequestStatuses: TemplateVersionActivationRequestStatus[] =
+	["approved", "canceled", "pending", "rejected"];
+
+// From codersdk/templateversions.go
+export interface TemplateVersionActivationReview {
+	readonly reviewer: MinimalUser;
+	readonly approved: boolean;
+	readonly comment: string;
+	readonly created_at: string;
+}
+
+// From codersdk/templateversions.go
+export interface TemplateVersionDiff {
+	readonly active_version_id: string;
+	readonly diff: string;
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionExternalAuth {
 	readonly id: string;
@@ -2912,6 +4291,38 @@ export interface TemplateVersionParameterOption {
 	readonly icon: string;
 }
 
+// From codersdk/templates.go
+export interface TemplateVersionRollout {
+	readonly template_id: string;
+	readonly template_version_id: string;
+	readonly created_by_id: string;
+	readonly percentage: number;
+	readonly group_ids: readonly string[];
+	readonly failure_threshold_percent: number;
+	readonly minimum_builds: number;
+	readonly status: TemplateVersionRolloutStatus;
+	readonly total_builds: number;
+	readonly failed_builds: number;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
+// From codersdk/templates.go
+export type TemplateVersionRolloutStatus =
+	| "canceled"
+	| "completed"
+	| "halted"
+	| "in_progress"
+	| "pending_approval";
+
+export const TemplateVersionRolloutStatuses: TemplateVersionRolloutStatus[] = [
+	"canceled",
+	"completed",
+	"halted",
+	"in_progress",
+	"pending_approval",
+];
+
 // From codersdk/templateversions.go
 export interface TemplateVersionVariable {
 	readonly name: string;
@@ -2992,6 +4403,21 @@ export interface TraceConfig {
 	readonly data_dog: boolean;
 }
 
+// From codersdk/templates.go
+export interface TransferTemplateRequest {
+	readonly organization_id: string;
+	readonly group_mapping?: Record<string, string>;
+	readonly confirm_workspace_transfer?: boolean;
+}
+
+// From codersdk/templates.go
+export interface TransferTemplateResponse {
+	readonly template_id: string;
+	readonly organization_id: string;
+	readonly group_mapping: Record<string, string>;
+	readonly workspaces: number;
+}
+
 // From codersdk/templates.go
 export interface TransitionStats {
 	readonly P50: number | null;
@@ -3018,6 +4444,11 @@ export interface UpdateCheckResponse {
 	readonly url: string;
 }
 
+// From codersdk/groups.go
+export interface UpdateGroupQuotaAllowancesRequest {
+	readonly allowances: Record<string, number>;
+}
+
 // From healthsdk/healthsdk.go
 export interface UpdateHealthSettings {
 	readonly dismissed_healthchecks: readonly HealthSection[];
@@ -3034,6 +4465,26 @@ export interface UpdateInboxNotificationReadStatusResponse {
 	readonly unread_count: number;
 }
 
+// From codersdk/inboxnotification.go
+export interface UpdateInboxNotificationsReadStatusRequest {
+	readonly ids?: readonly string[];
+	readonly categories?: readonly NotificationCategory[];
+	readonly is_read: boolean;
+}
+
+// From codersdk/inboxnotification.go
+export interface UpdateInboxNotificationsReadStatusResponse {
+	readonly updated_count: number;
+	readonly unread_count: number;
+}
+
+// From codersdk/notifications.go
+export interface UpdateNotificationCategoryPreference {
+	readonly category: NotificationCategory;
+	readonly method: string;
+	readonly disabled: boolean;
+}
+
 // From codersdk/notifications.go
 export interface UpdateNotificationTemplateMethod {
 	readonly method?: string;
@@ -3058,6 +4509,17 @@ export interface UpdateTemplateACL {
 	readonly group_perms?: Record<string, TemplateRole>;
 }
 
+// From codersdk/templates.go
+export interface UpdateTemplateActivationReviewersRequest {
+	readonly user_ids: readonly string[];
+}
+
+// From codersdk/templateegress.go
+export interface UpdateTemplateEgressPolicyRequest {
+	readonly allowed_cidrs: readonly string[];
+	readonly allowed_domains: readonly string[];
+}
+
 // From codersdk/templates.go
 export interface UpdateTemplateMeta {
 	readonly name?: string;
@@ -3081,6 +4543,21 @@ export interface UpdateTemplateMeta {
 	readonly disable_everyone_group_access: boolean;
 	readonly max_port_share_level?: WorkspaceAgentPortShareLevel;
 	readonly use_classic_parameter_flow?: boolean;
+	readonly wireguard_mtu?: number;
+	readonly wireguard_keepalive_interval_ms?: number;
+	readonly wireguard_handshake_timeout_ms?: number;
+	readonly activation_approvals_required?: number;
+	readonly autostart_holidays?: readonly string[];
+	readonly autostart_holiday_calendar_url?: string;
+	readonly dormant_archive_paths?: readonly string[];
+	readonly parameter_validation_url?: string;
+	readonly allow_start_on_connect?: boolean;
+}
+
+// From codersdk/secrets.go
+export interface UpdateTemplateSecretRequest {
+	readonly description?: string;
+	readonly value?: string;
 }
 
 // From codersdk/users.go
@@ -3089,6 +4566,11 @@ export interface UpdateUserAppearanceSettingsRequest {
 	readonly terminal_font: TerminalFontName;
 }
 
+// From codersdk/notifications.go
+export interface UpdateUserNotificationCategoryPreferences {
+	readonly preferences: readonly UpdateNotificationCategoryPreference[];
+}
+
 // From codersdk/notifications.go
 export interface UpdateUserNotificationPreferences {
 	readonly template_disabled_map: Record<string, boolean>;
@@ -3106,11 +4588,30 @@ export interface UpdateUserProfileRequest {
 	readonly name: string;
 }
 
+// From codersdk/proxypreference.go
+export interface UpdateUserProxyPreferenceRequest {
+	readonly preferred_region_id: string | null;
+}
+
 // From codersdk/users.go
 export interface UpdateUserQuietHoursScheduleRequest {
 	readonly schedule: string;
 }
 
+// From codersdk/secrets.go
+export interface UpdateUserSecretRequest {
+	readonly description?: string;
+	readonly value?: string;
+	readonly env_name?: string;
+}
+
+// From codersdk/variablesets.go
+export interface UpdateVariableSetRequest {
+	readonly description?: string;
+	readonly variables?: readonly VariableSetVariable[];
+	readonly remove_variables?: readonly string[];
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceAutomaticUpdatesRequest {
 	readonly automatic_updates: AutomaticUpdates;
@@ -3126,6 +4627,11 @@ export interface UpdateWorkspaceDormancy {
 	readonly dormant: boolean;
 }
 
+// From codersdk/workspaces.go
+export interface UpdateWorkspaceLabelsRequest {
+	readonly labels: Record<string, string>;
+}
+
 // From codersdk/workspaceproxy.go
 export interface UpdateWorkspaceProxyResponse {
 	readonly proxy: WorkspaceProxy;
@@ -3147,6 +4653,41 @@ export interface UploadResponse {
 	readonly hash: string;
 }
 
+// From codersdk/licenses.go
+export interface UpsertLicenseSeatReservationRequest {
+	readonly seats: number;
+}
+
+// From codersdk/reviewworkspaces.go
+export interface UpsertReviewWorkspaceRequest {
+	readonly repository: string;
+	readonly branch: string;
+	readonly template_id?: string;
+	readonly template_version_id?: string;
+	readonly template_version_preset_id?: string;
+	readonly rich_parameter_values?: readonly WorkspaceBuildParameter[];
+}
+
+// From codersdk/templateapprestrictions.go
+export interface UpsertTemplateAppRestrictionRequest {
+	readonly group_ids?: readonly string[];
+	readonly roles?: readonly string[];
+}
+
+// From codersdk/templateappsessionpolicies.go
+export interface UpsertTemplateAppSessionPolicyRequest {
+	readonly app_slug?: string;
+	readonly max_session_duration_ms: number;
+}
+
+// From codersdk/templateparameteroptions.go
+export interface UpsertTemplateParameterOptionSourceRequest {
+	readonly url: string;
+	readonly auth_header_name?: string;
+	readonly auth_header_value?: string;
+	readonly cache_ttl_ms?: number;
+}
+
 // From codersdk/workspaceagentportshare.go
 export interface UpsertWorkspaceAgentPortShareRequest {
 	readonly agent_name: string;
@@ -3206,6 +4747,11 @@ export interface UserAppearanceSettings {
 	readonly terminal_font: TerminalFontName;
 }
 
+// From codersdk/startonconnect.go
+export interface UserConnectionSettings {
+	readonly start_workspace_on_connect: boolean;
+}
+
 // From codersdk/insights.go
 export interface UserLatency {
 	readonly template_ids: readonly string[];
@@ -3240,12 +4786,25 @@ export interface UserLoginType {
 	readonly login_type: LoginType;
 }
 
+// From codersdk/notifications.go
+export interface UserNotificationSettings {
+	readonly digest: boolean;
+}
+
 // From codersdk/users.go
 export interface UserParameter {
 	readonly name: string;
 	readonly value: string;
 }
 
+// From codersdk/proxypreference.go
+export interface UserProxyPreference {
+	readonly preferred_region_id?: string;
+	readonly latencies: readonly RegionLatency[];
+	readonly selected_region_id: string;
+	readonly reason: ProxySelectionReason;
+}
+
 // From codersdk/deployment.go
 export interface UserQuietHoursScheduleConfig {
 	readonly default_schedule: string;
@@ -3268,6 +4827,17 @@ export interface UserRoles {
 	readonly organization_roles: Record<string, string[]>;
 }
 
+// From codersdk/secrets.go
+export interface UserSecret {
+	readonly id: string;
+	readonly user_id: string;
+	readonly name: string;
+	readonly description: string;
+	readonly env_name: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
 // From codersdk/users.go
 export type UserStatus = "active" | "dormant" | "suspended";
 
@@ -3279,6 +4849,20 @@ export interface UserStatusChangeCount {
 
 export const UserStatuses: UserStatus[] = ["active", "dormant", "suspended"];
 
+// From codersdk/insights.go
+export interface UserWorkspaceUsage {
+	readonly user_id: string;
+	readonly username: string;
+	readonly name: string;
+	readonly email: string;
+	readonly workspaces: number;
+	readonly runtime_hours: number;
+	readonly cost: number;
+	readonly dimension_hours: Record<string, number>;
+	readonly last_seen_at: string;
+	readonly last_workspace_used_at: string;
+}
+
 // From codersdk/users.go
 export interface UsersRequest extends Pagination {
 	readonly q?: string;
@@ -3309,12 +4893,109 @@ export const ValidationMonotonicOrders: ValidationMonotonicOrder[] = [
 	"increasing",
 ];
 
+// From codersdk/variablesets.go
+export interface VariableSet {
+	readonly id: string;
+	readonly name: string;
+	readonly description: string;
+	readonly variables: readonly VariableSetVariable[];
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
+// From codersdk/variablesets.go
+export interface VariableSetVariable {
+	readonly name: string;
+	readonly value?: string;
+	readonly secret?: boolean;
+}
+
 // From codersdk/organizations.go
 export interface VariableValue {
 	readonly name: string;
 	readonly value: string;
 }
 
+// From codersdk/deployment.go
+export interface VaultConfig {
+	readonly address: string;
+	readonly namespace: string;
+	readonly auth_method: string;
+	readonly auth_mount_path: string;
+	readonly token: string;
+	readonly approle_role_id: string;
+	readonly approle_secret_id: string;
+	readonly kubernetes_role: string;
+	readonly kubernetes_token_path: string;
+	readonly token_role: string;
+	readonly token_policies: string;
+	readonly token_ttl: number;
+}
+
+// From codersdk/versionskew.go
+export interface VersionSkewReport {
+	readonly server_version: string;
+	readonly min_agent_version: string;
+	readonly min_cli_version: string;
+	readonly agents: readonly AgentVersionSkew[];
+	readonly clis: readonly CLIVersionSkew[];
+}
+
+// From codersdk/inboxnotification.go
+export interface WatchInboxNotificationsRequest {
+	readonly targets?: string;
+	readonly templates?: string;
+	readonly categories?: string;
+	readonly read_status?: string;
+	readonly format?: string;
+	readonly read_status_events?: boolean;
+}
+
+// From codersdk/webauthn.go
+export interface WebAuthnAssertion {
+	readonly credential_id: string;
+	readonly client_data_json: string;
+	readonly authenticator_data: string;
+	readonly signature: string;
+}
+
+// From codersdk/webauthn.go
+export interface WebAuthnAttestation {
+	readonly client_data_json: string;
+	readonly attestation_object: string;
+}
+
+// From codersdk/webauthn.go
+export interface WebAuthnCreationOptions {
+	readonly challenge_id: string;
+	readonly challenge: string;
+	readonly rp_id: string;
+	readonly rp_name: string;
+	readonly user_handle: string;
+	readonly username: string;
+	readonly algorithms: readonly number[];
+	readonly exclude_credentials: readonly string[];
+	readonly timeout_ms: number;
+}
+
+// From codersdk/webauthn.go
+export interface WebAuthnCredential {
+	readonly id: string;
+	readonly user_id: string;
+	readonly name: string;
+	readonly created_at: string;
+	readonly last_used_at?: string;
+}
+
+// From codersdk/webauthn.go
+export interface WebAuthnRequestOptions {
+	readonly challenge_id: string;
+	readonly challenge: string;
+	readonly rp_id: string;
+	readonly allow_credentials: readonly string[];
+	readonly timeout_ms: number;
+}
+
 // From codersdk/notifications.go
 export interface WebpushMessage {
 	readonly icon: string;
@@ -3343,6 +5024,13 @@ export interface WebsocketReport extends BaseReport {
 	readonly code: number;
 }
 
+// From codersdk/deployment.go
+export interface WireguardConfig {
+	readonly mtu: number;
+	readonly keepalive_interval: number;
+	readonly handshake_timeout: number;
+}
+
 // From codersdk/workspaces.go
 export interface Workspace {
 	readonly id: string;
@@ -3375,6 +5063,15 @@ export interface Workspace {
 	readonly allow_renames: boolean;
 	readonly favorite: boolean;
 	readonly next_start_at: string | null;
+	readonly labels: Record<string, string>;
+	readonly ephemeral: boolean;
+}
+
+// From codersdk/workspaceadoptions.go
+export interface WorkspaceAdoptionToken {
+	readonly id: string;
+	readonly token: string;
+	readonly expires_at: string;
 }
 
 // From codersdk/workspaceagents.go
@@ -3435,6 +5132,11 @@ export interface WorkspaceAgentContainerPort {
 	readonly host_port?: number;
 }
 
+// From codersdk/workspaceagentdesktop.go
+export interface WorkspaceAgentDesktop {
+	readonly clipboard_policy: DesktopClipboardPolicy;
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentDevcontainer {
 	readonly id: string;
@@ -3465,6 +5167,11 @@ export type WorkspaceAgentDevcontainerStatus =
 export const WorkspaceAgentDevcontainerStatuses: WorkspaceAgentDevcontainerStatus[] =
 	["error", "running", "starting", "stopped"];
 
+// From codersdk/workspaceagentdiskusage.go
+export interface WorkspaceAgentDiskUsage {
+	readonly volumes: readonly WorkspaceAgentVolume[];
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentHealth {
 	readonly healthy: boolean;
@@ -3532,6 +5239,25 @@ export interface WorkspaceAgentLogSource {
 	readonly icon: string;
 }
 
+// From codersdk/workspaceagentprocesses.go
+export interface WorkspaceAgentManagedProcess {
+	readonly pid: number;
+	readonly name: string;
+	readonly cmd_line: string;
+	readonly pattern: string;
+	readonly nice: number;
+	readonly oom_score_adj: number;
+	readonly error?: string;
+	readonly managed_at: string;
+}
+
+// From codersdk/workspaceagentprocesses.go
+export interface WorkspaceAgentManagedProcessesResponse {
+	readonly enabled: boolean;
+	readonly rules: readonly WorkspaceAgentProcessPriorityRule[];
+	readonly processes: readonly WorkspaceAgentManagedProcess[];
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentMetadata {
 	readonly result: WorkspaceAgentMetadataResult;
@@ -3589,6 +5315,13 @@ export interface WorkspaceAgentPortShares {
 	readonly shares: readonly WorkspaceAgentPortShare[];
 }
 
+// From codersdk/workspaceagentprocesses.go
+export interface WorkspaceAgentProcessPriorityRule {
+	readonly pattern: string;
+	readonly nice?: number;
+	readonly oom_score_adj?: number;
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentScript {
 	readonly id: string;
@@ -3623,6 +5356,16 @@ export const WorkspaceAgentStatuses: WorkspaceAgentStatus[] = [
 	"timeout",
 ];
 
+// From codersdk/workspaceagentdiskusage.go
+export interface WorkspaceAgentVolume {
+	readonly path: string;
+	readonly total_bytes: number;
+	readonly used_bytes: number;
+	readonly collected_at: string;
+	readonly threshold?: number;
+	readonly over_threshold: boolean;
+}
+
 // From codersdk/workspaceapps.go
 export interface WorkspaceApp {
 	readonly id: string;
@@ -3704,6 +5447,21 @@ export const WorkspaceAppStatusStates: WorkspaceAppStatusState[] = [
 	"working",
 ];
 
+// From codersdk/workspacearchives.go
+export interface WorkspaceArchive {
+	readonly id: string;
+	readonly workspace_id: string;
+	readonly workspace_name: string;
+	readonly owner_id: string;
+	readonly organization_id: string;
+	readonly template_id: string;
+	readonly paths: readonly string[];
+	readonly size_bytes: number;
+	readonly error?: string;
+	readonly created_at: string;
+	readonly expires_at: string;
+}
+
 // From codersdk/workspacebuilds.go
 export interface WorkspaceBuild {
 	readonly id: string;
@@ -3722,6 +5480,8 @@ export interface WorkspaceBuild {
 	readonly initiator_name: string;
 	readonly job: ProvisionerJob;
 	readonly reason: BuildReason;
+	readonly reason_message?: string;
+	readonly initiator_context: WorkspaceBuildInitiatorContext;
 	readonly resources: readonly WorkspaceResource[];
 	readonly deadline?: string;
 	readonly max_deadline?: string;
@@ -3733,12 +5493,28 @@ export interface WorkspaceBuild {
 	readonly ai_task_sidebar_app_id?: string;
 }
 
+// From codersdk/workspacebuilds.go
+export interface WorkspaceBuildInitiatorContext {
+	readonly api_key_name?: string;
+	readonly ci_job_url?: string;
+}
+
 // From codersdk/workspacebuilds.go
 export interface WorkspaceBuildParameter {
 	readonly name: string;
 	readonly value: string;
 }
 
+// From codersdk/workspacebuilds.go
+export interface WorkspaceBuildQueue {
+	readonly status: ProvisionerJobStatus;
+	readonly position: number;
+	readonly size: number;
+	readonly tags: Record<string, string>;
+	readonly matched_provisioners: MatchedProvisioners;
+	readonly estimated_start_at?: string;
+}
+
 // From codersdk/workspacebuilds.go
 export interface WorkspaceBuildTimings {
 	readonly provisioner_timings: readonly ProvisionerTiming[];
@@ -3757,6 +5533,13 @@ export interface WorkspaceConnectionLatencyMS {
 	readonly P95: number;
 }
 
+// From codersdk/apikey.go
+export interface WorkspaceCredential {
+	readonly key: string;
+	readonly workspace_id: string;
+	readonly expires_at: string;
+}
+
 // From codersdk/deployment.go
 export interface WorkspaceDeploymentStats {
 	readonly pending: number;
@@ -3785,6 +5568,22 @@ export interface WorkspaceOptions {
 	readonly include_deleted?: boolean;
 }
 
+// From codersdk/workspaceportsharelinks.go
+export interface WorkspacePortShareLink {
+	readonly id: string;
+	readonly workspace_id: string;
+	readonly agent_name: string;
+	readonly port: number;
+	readonly protocol: WorkspaceAgentPortShareProtocol;
+	readonly has_password: boolean;
+	readonly bandwidth_limit_bytes: number;
+	readonly bytes_transferred: number;
+	readonly expires_at: string;
+	readonly created_by: string;
+	readonly created_at: string;
+	readonly url?: string;
+}
+
 // From codersdk/workspaceproxy.go
 export interface WorkspaceProxy extends Region {
 	readonly derp_enabled: boolean;
@@ -3815,10 +5614,94 @@ export interface WorkspaceProxyStatus {
 	readonly checked_at: string;
 }
 
+// From codersdk/workspaceproxy.go
+export interface WorkspaceProxyTokenStatus {
+	readonly proxy_id: string;
+	readonly proxy_name: string;
+	readonly token_issued_at: string;
+	readonly token_age_seconds: number;
+	readonly next_rotation_at?: string;
+	readonly previous_token_expires_at?: string;
+	readonly version: string;
+	readonly version_skew: WorkspaceProxyVersionSkew;
+}
+
+// From codersdk/workspaceproxy.go
+export interface WorkspaceProxyTokensResponse {
+	readonly primary_version: string;
+	readonly proxies: readonly WorkspaceProxyTokenStatus[];
+}
+
+// From codersdk/workspaceproxy.go
+export type WorkspaceProxyVersionSkew =
+	| "major"
+	| "match"
+	| "minor"
+	| "patch"
+	| "unknown";
+
+export const WorkspaceProxyVersionSkews: WorkspaceProxyVersionSkew[] = [
+	"major",
+	"match",
+	"minor",
+	"patch",
+	"unknown",
+];
+
 // From codersdk/workspaces.go
 export interface WorkspaceQuota {
 	readonly credits_consumed: number;
 	readonly budget: number;
+	readonly dimensions?: readonly WorkspaceQuotaDimension[];
+}
+
+// From codersdk/workspaces.go
+export interface WorkspaceQuotaDimension {
+	readonly name: string;
+	readonly consumed: number;
+	readonly budget: number;
+}
+
+// From codersdk/workspacereadiness.go
+export interface WorkspaceReadiness {
+	readonly ready: boolean;
+	readonly failed: boolean;
+	readonly conditions: readonly WorkspaceReadinessConditionState[];
+}
+
+// From codersdk/workspacereadiness.go
+export interface WorkspaceReadinessCondition {
+	readonly type: WorkspaceReadinessConditionType;
+	readonly agent?: string;
+	readonly name?: string;
+}
+
+// From codersdk/workspacereadiness.go
+export interface WorkspaceReadinessConditionState {
+	readonly condition: string;
+	readonly status: WorkspaceReadinessConditionStatus;
+	readonly message?: string;
+}
+
+// From codersdk/workspacereadiness.go
+export type WorkspaceReadinessConditionStatus =
+	| "failed"
+	| "pending"
+	| "satisfied";
+
+export const WorkspaceReadinessConditionStatuses: WorkspaceReadinessConditionStatus[] =
+	["failed", "pending", "satisfied"];
+
+// From codersdk/workspacereadiness.go
+export type WorkspaceReadinessConditionType = "agent" | "app" | "script";
+
+export const WorkspaceReadinessConditionTypes: WorkspaceReadinessConditionType[] =
+	["agent", "app", "script"];
+
+// From codersdk/workspacereadiness.go
+export interface WorkspaceReadinessRequest {
+	readonly Conditions: readonly WorkspaceReadinessCondition[];
+	readonly Timeout: number;
 }
 
 // From codersdk/workspacebuilds.go
@@ -3843,6 +5726,11 @@ export interface WorkspaceResourceMetadata {
 	readonly sensitive: boolean;
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceSchedulePause {
+	readonly paused_until?: string;
+}
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceStatus =
 	| "canceled"
@@ -3878,6 +5766,38 @@ export const WorkspaceTransitions: WorkspaceTransition[] = [
 	"stop",
 ];
 
+// From codersdk/insights.go
+export type WorkspaceUsageReportFormat = "csv" | "json";
+
+export const WorkspaceUsageReportFormats: WorkspaceUsageReportFormat[] = [
+	"csv",
+	"json",
+];
+
+// From codersdk/insights.go
+export type WorkspaceUsageReportGroupBy = "group" | "user";
+
+export const WorkspaceUsageReportGroupBys: WorkspaceUsageReportGroupBy[] = [
+	"group",
+	"user",
+];
+
+// From codersdk/insights.go
+export interface WorkspaceUsageReportRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly group_by: WorkspaceUsageReportGroupBy;
+}
+
+// From codersdk/insights.go
+export interface WorkspaceUsageReportResponse {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly group_by: WorkspaceUsageReportGroupBy;
+	readonly users?: readonly UserWorkspaceUsage[];
+	readonly groups?: readonly GroupWorkspaceUsage[];
+}
+
 // From codersdk/workspaces.go
 export interface WorkspacesRequest extends Pagination {
 	readonly q?: string;
@@ -91,6 +91,37 @@ describe("ProxyContextGetURLs", () => {
 			"",
 			MockPrimaryWorkspaceProxy.wildcard_hostname,
 		],
+		// The failover region picked by coderd is used if the selected is unhealthy
+		[
+			"unhealthy selection with failover",
+			[
+				MockPrimaryWorkspaceProxy,
+				MockHealthyWildWorkspaceProxy,
+				{
+					...MockUnhealthyWildWorkspaceProxy,
+					failover_region_id: MockHealthyWildWorkspaceProxy.id,
+				},
+			],
+			{},
+			MockUnhealthyWildWorkspaceProxy,
+			MockHealthyWildWorkspaceProxy.path_app_url,
+			MockHealthyWildWorkspaceProxy.wildcard_hostname,
+		],
+		// An unhealthy failover region is ignored
+		[
+			"unhealthy selection with unhealthy failover",
+			[
+				MockPrimaryWorkspaceProxy,
+				{
+					...MockUnhealthyWildWorkspaceProxy,
+					failover_region_id: MockUnhealthyWildWorkspaceProxy.id,
+				},
+			],
+			{},
+			MockUnhealthyWildWorkspaceProxy,
+			"",
+			MockPrimaryWorkspaceProxy.wildcard_hostname,
+		],
 		// This should never happen, when there is no primary
 		["no primary", [MockHealthyWildWorkspaceProxy], {}, undefined, "", ""],
 		// Latency behavior
@@ -10,6 +10,7 @@ import {
 	useCallback,
 	useContext,
 	useEffect,
+	useRef,
 	useState,
 } from "react";
 import { useQuery } from "react-query";
@@ -24,7 +25,8 @@ export interface ProxyContextValue {
 	//   2. The default proxy auto selected because:
 	//    a. The user has not selected a proxy.
 	//    b. The user's selected proxy is not in the list of proxies.
-	//    c. The user's selected proxy is not healthy.
+	//    c. The user's selected proxy is not healthy. coderd names a healthy
+//       failover region for each unhealthy one, which is used if available.
 	//   3. undefined if there are no proxies.
 	//
 	// The values 'proxy.preferredPathAppURL' and 'proxy.preferredWildcardHostname' can
@@ -103,6 +105,14 @@ export const ProxyProvider: FC<PropsWithChildren> = ({ children }) => {
 	const { permissions } = useAuthenticated();
 	const { metadata } = useEmbeddedMetadata();
 
+	// The latest latencies are sent to coderd as hints when fetching the
+	// proxies so the failover regions it picks are close to this browser.
+	const latencyHintsRef = useRef<Record<string, number>>({});
+	// healthTTLRef is how long coderd says the health of the proxies is valid
+	// for. The proxies are refetched on that interval so traffic moves away
+	// from a proxy shortly after it becomes unhealthy.
+	const healthTTLRef = useRef(0);
+
 	const {
 		data: proxiesResp,
 		error: proxiesError,
@@ -117,9 +127,17 @@ export const ProxyProvider: FC<PropsWithChildren> = ({ children }) => {
 					? API.getWorkspaceProxies
 					: API.getWorkspaceProxyRegions;
 
-				const resp = await apiCall();
+				const resp = await apiCall(latencyHintsRef.current);
+				healthTTLRef.current = resp.health_ttl_seconds ?? 0;
 				return resp.regions;
 			},
+			refetchInterval: (query) => {
+				// There is nothing to fail over to with a single region.
+				if ((query.state.data?.length ?? 0) < 2) {
+					return false;
+				}
+				return (healthTTLRef.current || defaultHealthTTLSeconds) * 1000;
+			},
 		}),
 	);
 
@@ -131,6 +149,15 @@ export const ProxyProvider: FC<PropsWithChildren> = ({ children }) => {
 		loaded: latenciesLoaded,
 	} = useProxyLatency(proxiesResp);
 
+	useEffect(() => {
+		latencyHintsRef.current = Object.fromEntries(
+			Object.entries(proxyLatencies).map(([id, report]) => [
+				id,
+				report.latencyMS,
+			]),
+		);
+	}, [proxyLatencies]);
+
 	// updateProxy is a helper function that when called will
 	// update the proxy being used.
 	const updateProxy = useCallback(() => {
@@ -216,6 +243,10 @@ export const ProxyProvider: FC<PropsWithChildren> = ({ children }) => {
 	);
 };
 
+// defaultHealthTTLSeconds is how often the proxies are refetched when coderd
+// does not say how long their health is valid for.
+const defaultHealthTTLSeconds = 60;
+
 export const useProxy = (): ProxyContextValue => {
 	const context = useContext(ProxyContext);
 
@@ -248,6 +279,18 @@ export const getPreferredProxy = (
 		(proxy) => selectedProxy && proxy.id === selectedProxy.id,
 	);
 
+	// If the selected proxy is unhealthy, use the region coderd picked to take
+	// over its traffic.
+	if (selectedProxy && !selectedProxy.healthy) {
+		const failoverID = selectedProxy.failover_region_id;
+		const failover = proxies.find(
+			(proxy) => failoverID && proxy.id === failoverID && proxy.healthy,
+		);
+		if (failover) {
+			return computeUsableURLS(failover);
+		}
+	}
+
 	// If no proxy is selected, or the selected proxy is unhealthy default to the primary proxy.
 	if (!selectedProxy || !selectedProxy.healthy) {
 		// Default to the primary proxy
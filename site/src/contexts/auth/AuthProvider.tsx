@@ -1,13 +1,20 @@
 import { isApiError } from "api/errors";
 import { checkAuthorization } from "api/queries/authCheck";
 import {
+	completeLogin,
 	hasFirstUser,
 	login,
+	loginWithWebAuthn,
 	logout,
 	me,
 	updateProfile as updateProfileOptions,
 } from "api/queries/users";
-import type { UpdateUserProfileRequest, User } from "api/typesGenerated";
+import type {
+	LoginMFAChallenge,
+	LoginWithWebAuthnRequest,
+	UpdateUserProfileRequest,
+	User,
+} from "api/typesGenerated";
 import { displaySuccess } from "components/GlobalSnackbar/utils";
 import { useEmbeddedMetadata } from "hooks/useEmbeddedMetadata";
 import { type Permissions, permissionChecks } from "modules/permissions";
@@ -33,7 +40,17 @@ export type AuthContextValue = {
 	signInError: unknown;
 	updateProfileError: unknown;
 	signOut: () => void;
-	signIn: (email: string, password: string) => Promise<void>;
+	/**
+	 * signIn returns the second factor challenge when the user must complete
+	 * the login with signInWithWebAuthn, or by enrolling a security key and
+	 * calling completeSignIn.
+	 */
+	signIn: (
+		email: string,
+		password: string,
+	) => Promise<LoginMFAChallenge | undefined>;
+	signInWithWebAuthn: (request: LoginWithWebAuthnRequest) => Promise<void>;
+	completeSignIn: () => Promise<void>;
 	updateProfile: (data: UpdateUserProfileRequest) => void;
 };
 
@@ -58,6 +75,12 @@ export const AuthProvider: FC<PropsWithChildren> = ({ children }) => {
 	const loginMutation = useMutation(
 		login({ checks: permissionChecks }, queryClient),
 	);
+	const webAuthnLoginMutation = useMutation(
+		loginWithWebAuthn({ checks: permissionChecks }, queryClient),
+	);
+	const completeLoginMutation = useMutation(
+		completeLogin({ checks: permissionChecks }, queryClient),
+	);
 
 	const logoutMutation = useMutation(logout(queryClient));
 	const updateProfileMutation = useMutation({
@@ -80,7 +103,10 @@ export const AuthProvider: FC<PropsWithChildren> = ({ children }) => {
 	const isConfiguringTheFirstUser =
 		!hasFirstUserQuery.isLoading && !hasFirstUserQuery.data;
 	const isSignedIn = userQuery.isSuccess && userQuery.data !== undefined;
-	const isSigningIn = loginMutation.isPending;
+	const isSigningIn =
+		loginMutation.isPending ||
+		webAuthnLoginMutation.isPending ||
+		completeLoginMutation.isPending;
 	const isUpdatingProfile = updateProfileMutation.isPending;
 
 	const signOut = useCallback(() => {
@@ -89,11 +115,26 @@ export const AuthProvider: FC<PropsWithChildren> = ({ children }) => {
 
 	const signIn = useCallback(
 		async (email: string, password: string) => {
-			await loginMutation.mutateAsync({ email, password });
+			// Errors of a previous second factor don't apply to a new login.
+			webAuthnLoginMutation.reset();
+			completeLoginMutation.reset();
+			const { mfa } = await loginMutation.mutateAsync({ email, password });
+			return mfa;
+		},
+		[loginMutation, webAuthnLoginMutation, completeLoginMutation],
+	);
+
+	const signInWithWebAuthn = useCallback(
+		async (request: LoginWithWebAuthnRequest) => {
+			await webAuthnLoginMutation.mutateAsync(request);
 		},
-		[loginMutation],
+		[webAuthnLoginMutation],
 	);
 
+	const completeSignIn = useCallback(async () => {
+		await completeLoginMutation.mutateAsync();
+	}, [completeLoginMutation]);
+
 	const updateProfile = useCallback(
 		(req: UpdateUserProfileRequest) => {
 			updateProfileMutation.mutate(req);
@@ -113,10 +154,15 @@ export const AuthProvider: FC<PropsWithChildren> = ({ children }) => {
 				isUpdatingProfile,
 				signOut,
 				signIn,
+				signInWithWebAuthn,
+				completeSignIn,
 				updateProfile,
 				user: userQuery.data,
 				permissions: permissionsQuery.data as Permissions | undefined,
-				signInError: loginMutation.error,
+				signInError:
+					webAuthnLoginMutation.error ??
+					completeLoginMutation.error ??
+					loginMutation.error,
 				updateProfileError: updateProfileMutation.error,
 			}}
 		>
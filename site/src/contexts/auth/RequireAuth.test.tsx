@@ -50,6 +50,8 @@ const createAuthWrapper = (override: Partial<AuthContextValue>) => {
 		updateProfileError: undefined,
 		signOut: jest.fn(),
 		signIn: jest.fn(),
+		signInWithWebAuthn: jest.fn(),
+		completeSignIn: jest.fn(),
 		updateProfile: jest.fn(),
 		...override,
 	};
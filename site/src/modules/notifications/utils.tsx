@@ -1,19 +1,25 @@
 import { MailIcon } from "lucide-react";
+import { MessagesSquareIcon } from "lucide-react";
+import { SlackIcon } from "lucide-react";
 import { WebhookIcon } from "lucide-react";
 
 // TODO: This should be provided by the auto generated types from codersdk
-const notificationMethods = ["smtp", "webhook"] as const;
+const notificationMethods = ["smtp", "webhook", "slack", "teams"] as const;
 
 export type NotificationMethod = (typeof notificationMethods)[number];
 
 export const methodIcons: Record<NotificationMethod, typeof MailIcon> = {
 	smtp: MailIcon,
 	webhook: WebhookIcon,
+	slack: SlackIcon,
+	teams: MessagesSquareIcon,
 };
 
 export const methodLabels: Record<NotificationMethod, string> = {
 	smtp: "SMTP",
 	webhook: "Webhook",
+	slack: "Slack",
+	teams: "Microsoft Teams",
 };
 
 export const castNotificationMethod = (value: string) => {
@@ -36,14 +36,22 @@ export const WorkspaceBuildData = ({ build }: { build: WorkspaceBuild }) => {
 					<span css={{ fontWeight: 500 }}>
 						{getDisplayWorkspaceBuildInitiatedBy(build)}
 					</span>
+					{build.initiator_context.api_key_name && (
+						<> via {build.initiator_context.api_key_name}</>
+					)}
 				</div>
 				<div
 					css={{
 						fontSize: 12,
 						color: theme.palette.text.secondary,
+						textOverflow: "ellipsis",
+						overflow: "hidden",
+						whiteSpace: "nowrap",
 					}}
+					title={build.reason_message}
 				>
 					{createDayString(build.created_at)}
+					{build.reason_message && <> &middot; {build.reason_message}</>}
 				</div>
 			</div>
 		</div>
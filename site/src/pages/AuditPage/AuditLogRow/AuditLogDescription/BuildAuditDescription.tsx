@@ -11,6 +11,8 @@ export const BuildAuditDescription: FC<BuildAuditDescriptionProps> = ({
 	auditLog,
 }) => {
 	const workspaceName = auditLog.additional_fields?.workspace_name?.trim();
+	const reasonMessage =
+		auditLog.additional_fields?.build_reason_message?.trim();
 	// workspaces can be started/stopped/deleted by a user, or kicked off automatically by Coder
 	const user =
 		auditLog.additional_fields?.build_reason &&
@@ -43,6 +45,7 @@ export const BuildAuditDescription: FC<BuildAuditDescriptionProps> = ({
 			) : (
 				<strong>{workspaceName}</strong>
 			)}
+			{reasonMessage && <>: {reasonMessage}</>}
 		</span>
 	);
 };
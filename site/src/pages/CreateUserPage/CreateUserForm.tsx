@@ -31,6 +31,10 @@ export const authMethodLanguage = {
 		displayName: "OpenID Connect",
 		description: "Use an OpenID Connect provider for authentication",
 	},
+	saml: {
+		displayName: "SAML",
+		description: "Use a SAML identity provider for authentication",
+	},
 	github: {
 		displayName: "Github",
 		description: "Use Github OAuth for authentication",
@@ -117,6 +121,7 @@ export const CreateUserForm: FC<
 	const methods = [
 		authMethods?.password.enabled && "password",
 		authMethods?.oidc.enabled && "oidc",
+		authMethods?.saml.enabled && "saml",
 		authMethods?.github.enabled && "github",
 		"none",
 	].filter(Boolean) as Array<keyof typeof authMethodLanguage>;
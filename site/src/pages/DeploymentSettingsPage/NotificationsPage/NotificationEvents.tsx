@@ -60,6 +60,15 @@ export const NotificationEvents: FC<NotificationEventsProps> = ({
 		"hello",
 	]);
 
+	// Microsoft Teams
+	const hasTeamsNotifications = Object.values(templatesByGroup)
+		.flat()
+		.some((t) => t.method === "teams");
+	const teamsValues = deploymentConfig.notifications?.teams ?? {};
+	const isTeamsConfigured = requiredFieldsArePresent(teamsValues, [
+		"endpoint",
+	]);
+
 	return (
 		<Stack spacing={4}>
 			{hasWebhookNotifications && !isWebhookConfigured && (
@@ -102,6 +111,26 @@ export const NotificationEvents: FC<NotificationEventsProps> = ({
 				</Alert>
 			)}
 
+			{hasTeamsNotifications && !isTeamsConfigured && (
+				<Alert
+					severity="warning"
+					actions={
+						<Button
+							variant="text"
+							size="small"
+							component="a"
+							target="_blank"
+							rel="noreferrer"
+							href={docs("/admin/monitoring/notifications/teams")}
+						>
+							Read the docs
+						</Button>
+					}
+				>
+					Microsoft Teams notifications are enabled but not properly configured.
+				</Alert>
+			)}
+
 			{Object.entries(templatesByGroup).map(([group, templates]) => (
 				<Card
 					key={group}
@@ -0,0 +1,259 @@
+import type { Interpolation, Theme } from "@emotion/react";
+import TextField from "@mui/material/TextField";
+import RFB from "@novnc/novnc/core/rfb";
+import { API } from "api/api";
+import { workspaceByOwnerAndName } from "api/queries/workspaces";
+import type { DesktopClipboardPolicy } from "api/typesGenerated";
+import { Alert } from "components/Alert/Alert";
+import { Button } from "components/Button/Button";
+import { useProxy } from "contexts/ProxyContext";
+import { ThemeOverride } from "contexts/ThemeProvider";
+import { type FC, type FormEvent, useEffect, useRef, useState } from "react";
+import { Helmet } from "react-helmet-async";
+import { useQuery } from "react-query";
+import { useParams, useSearchParams } from "react-router-dom";
+import themes from "theme";
+import { desktopWebsocketUrl } from "utils/desktop";
+import { pageTitle } from "utils/page";
+import { getMatchingAgentOrFirst } from "utils/workspace";
+import type { ConnectionStatus } from "../TerminalPage/types";
+
+export const Language = {
+	workspaceErrorMessagePrefix: "Unable to fetch workspace: ",
+	noAgentMessage: "No agent found, is the workspace started?",
+	disconnectedMessage: "Disconnected from the desktop.",
+	securityFailureMessagePrefix: "Failed to authenticate with the VNC server: ",
+	passwordLabel: "VNC password",
+	connect: "Connect",
+	reconnect: "Reconnect",
+};
+
+const allowsToWorkspace = (policy: DesktopClipboardPolicy | undefined) =>
+	policy === "both" || policy === "to-workspace";
+
+const allowsFromWorkspace = (policy: DesktopClipboardPolicy | undefined) =>
+	policy === "both" || policy === "from-workspace";
+
+const DesktopPage: FC = () => {
+	const theme = themes.dark;
+	const { proxy } = useProxy();
+	const params = useParams() as { username: string; workspace: string };
+	const username = params.username.replace("@", "");
+	const [searchParams] = useSearchParams();
+	const port = Number(searchParams.get("port")) || undefined;
+	// The workspace name is in the format:
+	// <workspace name>[.<agent name>]
+	const workspaceNameParts = params.workspace?.split(".");
+	const workspace = useQuery(
+		workspaceByOwnerAndName(username, workspaceNameParts?.[0]),
+	);
+	const workspaceAgent = workspace.data
+		? getMatchingAgentOrFirst(workspace.data, workspaceNameParts?.[1])
+		: undefined;
+	const desktop = useQuery({
+		queryKey: ["workspaceAgent", workspaceAgent?.id, "desktop"],
+		queryFn: () => API.getWorkspaceAgentDesktop(workspaceAgent?.id ?? ""),
+		enabled: workspaceAgent !== undefined,
+	});
+	const clipboardPolicy = desktop.data?.clipboard_policy;
+
+	const screenRef = useRef<HTMLDivElement>(null);
+	const rfbRef = useRef<RFB>();
+	const [connectionStatus, setConnectionStatus] =
+		useState<ConnectionStatus>("initializing");
+	const [error, setError] = useState<string>();
+	const [needsPassword, setNeedsPassword] = useState(false);
+	const [password, setPassword] = useState("");
+	// Incremented to start a new session after a disconnect.
+	const [session, setSession] = useState(0);
+
+	// biome-ignore lint/correctness/useExhaustiveDependencies(session): reconnects when incremented
+	useEffect(() => {
+		if (!screenRef.current || !workspaceAgent || !desktop.data) {
+			return;
+		}
+		const screen = screenRef.current;
+
+		let rfb: RFB | undefined;
+		let disposed = false;
+		setConnectionStatus("initializing");
+		setError(undefined);
+
+		desktopWebsocketUrl(
+			// When on development mode we can bypass the proxy and connect directly.
+			process.env.NODE_ENV !== "development"
+				? proxy.preferredPathAppURL
+				: undefined,
+			workspaceAgent.id,
+			port,
+		)
+			.then((url) => {
+				if (disposed) {
+					return; // Unmounted while we waited for the async call.
+				}
+				rfb = new RFB(screen, url, { wsProtocols: ["binary"] });
+				rfbRef.current = rfb;
+				rfb.scaleViewport = true;
+				rfb.resizeSession = true;
+				rfb.background = theme.palette.background.default;
+				rfb.addEventListener("connect", () => {
+					setNeedsPassword(false);
+					setConnectionStatus("connected");
+					rfb?.focus();
+				});
+				rfb.addEventListener("disconnect", () => {
+					setConnectionStatus("disconnected");
+				});
+				rfb.addEventListener("credentialsrequired", () => {
+					setNeedsPassword(true);
+				});
+				rfb.addEventListener("securityfailure", (event) => {
+					setError(
+						Language.securityFailureMessagePrefix +
+							(event.detail.reason ?? event.detail.status),
+					);
+				});
+				// The proxy drops the cut text the policy doesn't allow, so
+				// this is only to avoid prompting for clipboard permissions
+				// that can't be used.
+				rfb.addEventListener("clipboard", (event) => {
+					if (allowsFromWorkspace(clipboardPolicy)) {
+						void navigator.clipboard?.writeText(event.detail.text);
+					}
+				});
+			})
+			.catch((error) => {
+				if (disposed) {
+					return;
+				}
+				console.error("Desktop connection failed:", error);
+				setConnectionStatus("disconnected");
+			});
+
+		// Send the clipboard of the browser when the desktop gets focus, since
+		// the canvas doesn't receive paste events.
+		const onFocus = () => {
+			if (!allowsToWorkspace(clipboardPolicy)) {
+				return;
+			}
+			navigator.clipboard
+				?.readText()
+				.then((text) => rfb?.clipboardPasteFrom(text))
+				.catch(() => {
+					// The user didn't grant access to the clipboard.
+				});
+		};
+		screen.addEventListener("focusin", onFocus);
+
+		return () => {
+			disposed = true;
+			screen.removeEventListener("focusin", onFocus);
+			rfb?.disconnect();
+			rfbRef.current = undefined;
+		};
+	}, [
+		clipboardPolicy,
+		desktop.data,
+		port,
+		proxy.preferredPathAppURL,
+		session,
+		theme.palette.background.default,
+		workspaceAgent,
+	]);
+
+	const onSubmitPassword = (event: FormEvent) => {
+		event.preventDefault();
+		rfbRef.current?.sendCredentials({ password });
+		setPassword("");
+	};
+
+	let message: string | undefined;
+	if (workspace.error instanceof Error) {
+		message = Language.workspaceErrorMessagePrefix + workspace.error.message;
+	} else if (workspace.data && !workspaceAgent) {
+		message = Language.noAgentMessage;
+	} else if (error) {
+		message = error;
+	} else if (connectionStatus === "disconnected") {
+		message = Language.disconnectedMessage;
+	}
+
+	return (
+		<ThemeOverride theme={theme}>
+			<Helmet>
+				<title>
+					{workspace.data
+						? pageTitle(
+								`Desktop · ${workspace.data.owner_name}/${workspace.data.name}`,
+							)
+						: ""}
+				</title>
+			</Helmet>
+			<div css={styles.page} data-status={connectionStatus}>
+				{message && (
+					<Alert
+						severity="error"
+						css={styles.alert}
+						actions={
+							connectionStatus === "disconnected" && (
+								<Button
+									size="sm"
+									variant="outline"
+									onClick={() => setSession((session) => session + 1)}
+								>
+									{Language.reconnect}
+								</Button>
+							)
+						}
+					>
+						{message}
+					</Alert>
+				)}
+				{needsPassword && connectionStatus !== "disconnected" && (
+					<form css={styles.password} onSubmit={onSubmitPassword}>
+						<TextField
+							id="vnc_password"
+							type="password"
+							size="small"
+							autoFocus
+							label={Language.passwordLabel}
+							value={password}
+							onChange={(event) => setPassword(event.target.value)}
+						/>
+						<Button type="submit" disabled={!password}>
+							{Language.connect}
+						</Button>
+					</form>
+				)}
+				<div css={styles.screen} ref={screenRef} data-testid="desktop" />
+			</div>
+		</ThemeOverride>
+	);
+};
+
+const styles = {
+	page: {
+		display: "flex",
+		flexDirection: "column",
+		height: "100vh",
+	},
+	alert: {
+		borderRadius: 0,
+		borderWidth: 0,
+		borderBottomWidth: 1,
+	},
+	password: (theme) => ({
+		display: "flex",
+		alignItems: "center",
+		gap: 8,
+		padding: 16,
+		borderBottom: `1px solid ${theme.palette.divider}`,
+	}),
+	screen: (theme) => ({
+		flex: 1,
+		overflow: "hidden",
+		backgroundColor: theme.palette.background.default,
+	}),
+} satisfies Record<string, Interpolation<Theme>>;
+
+export default DesktopPage;
@@ -6,4 +6,23 @@ export const Language = {
 	passwordSignIn: "Sign In",
 	githubSignIn: "GitHub",
 	oidcSignIn: "OpenID Connect",
+	samlSignIn: "SAML",
+	securityKeyTitle: "Use your security key",
+	securityKeyDescription:
+		"Your account is protected by a security key. Use it, or one of your recovery codes, to sign in.",
+	useSecurityKey: "Use security key",
+	recoveryCodeLabel: "Recovery code",
+	useRecoveryCode: "Use recovery code",
+	enrollSecurityKeyTitle: "Register a security key",
+	enrollSecurityKeyDescription:
+		"Your organization requires a security key to sign in with a password. Register one to continue.",
+	securityKeyNameLabel: "Security key name",
+	securityKeyNameDefault: "Security key",
+	enrollSecurityKey: "Register security key",
+	recoveryCodesTitle: "Save your recovery codes",
+	recoveryCodesDescription:
+		"Each code can be used once to sign in without your security key. They won't be shown again.",
+	continue: "Continue",
+	backToSignIn: "Back to sign in",
+	webAuthnUnsupported: "Your browser doesn't support security keys.",
 };
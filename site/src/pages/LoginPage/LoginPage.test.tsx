@@ -1,7 +1,8 @@
-import { fireEvent, screen } from "@testing-library/react";
+import { fireEvent, screen, waitFor } from "@testing-library/react";
 import userEvent from "@testing-library/user-event";
 import { http, HttpResponse } from "msw";
 import { createMemoryRouter } from "react-router-dom";
+import { MockLoginMFAAssertion } from "testHelpers/entities";
 import {
 	render,
 	renderWithRouter,
@@ -47,6 +48,50 @@ describe("LoginPage", () => {
 		expect(errorMessage).toBeDefined();
 	});
 
+	it("completes the sign in with a recovery code", async () => {
+		// Given
+		let webAuthnRequest: unknown;
+		server.use(
+			// Require a second factor
+			http.post("/api/v2/users/login", () => {
+				return HttpResponse.json({
+					session_token: "",
+					mfa: MockLoginMFAAssertion,
+				});
+			}),
+			http.post("/api/v2/users/login/webauthn", async ({ request }) => {
+				webAuthnRequest = await request.json();
+				return HttpResponse.json({ session_token: "token" }, { status: 201 });
+			}),
+		);
+
+		// When
+		render(<LoginPage />);
+		await waitForLoaderToBeRemoved();
+		await userEvent.type(
+			screen.getByLabelText(Language.emailLabel),
+			"test@coder.com",
+		);
+		await userEvent.type(
+			screen.getByLabelText(Language.passwordLabel),
+			"password",
+		);
+		fireEvent.click(await screen.findByText(Language.passwordSignIn));
+		await userEvent.type(
+			await screen.findByLabelText(Language.recoveryCodeLabel),
+			"7kq2-9xwp-4mzt",
+		);
+		fireEvent.click(screen.getByText(Language.useRecoveryCode));
+
+		// Then
+		await waitFor(() =>
+			expect(webAuthnRequest).toEqual({
+				challenge_id: MockLoginMFAAssertion.assertion?.challenge_id,
+				recovery_code: "7kq2-9xwp-4mzt",
+			}),
+		);
+	});
+
 	it("redirects to the setup page if there is no first user", async () => {
 		// Given
 		server.use(
@@ -1,14 +1,24 @@
 import { buildInfo } from "api/queries/buildInfo";
-import { authMethods } from "api/queries/users";
+import { authMethods, loginWithWebAuthnEnrollment } from "api/queries/users";
+import type {
+	LoginMFAChallenge,
+	LoginWithWebAuthnRequest,
+	WebAuthnAssertion,
+	WebAuthnAttestation,
+} from "api/typesGenerated";
 import { useAuthContext } from "contexts/auth/AuthProvider";
 import { useEmbeddedMetadata } from "hooks/useEmbeddedMetadata";
-import { type FC, useEffect } from "react";
+import { type FC, useEffect, useState } from "react";
 import { Helmet } from "react-helmet-async";
-import { useQuery } from "react-query";
+import { useMutation, useQuery } from "react-query";
 import { Navigate, useLocation, useNavigate } from "react-router-dom";
 import { getApplicationName } from "utils/appearance";
 import { retrieveRedirect } from "utils/redirect";
 import { sendDeploymentEvent } from "utils/telemetry";
+import {
+	createWebAuthnAttestation,
+	getWebAuthnAssertion,
+} from "utils/webauthn";
 import { LoginPageView } from "./LoginPageView";
 
 const LoginPage: FC = () => {
@@ -18,10 +28,18 @@ const LoginPage: FC = () => {
 		isSignedIn,
 		isConfiguringTheFirstUser,
 		signIn,
+		signInWithWebAuthn,
+		completeSignIn,
 		isSigningIn,
 		signInError,
 		user,
 	} = useAuthContext();
+	const enrollMutation = useMutation(loginWithWebAuthnEnrollment());
+	const [mfaChallenge, setMFAChallenge] = useState<LoginMFAChallenge>();
+	const [recoveryCodes, setRecoveryCodes] = useState<readonly string[]>();
+	// mfaError is an error of the browser while using a security key. The
+	// challenge can still be used after it, unlike after an error of the API.
+	const [mfaError, setMFAError] = useState<unknown>();
 	const authMethodsQuery = useQuery(authMethods());
 	const redirectTo = retrieveRedirect(location.search);
 	const applicationName = getApplicationName();
@@ -75,6 +93,84 @@ const LoginPage: FC = () => {
 		return <Navigate to="/setup" replace />;
 	}
 
+	// Challenges are single use, so the user must enter their password again
+	// after the API rejected a second factor.
+	const resetMFA = () => {
+		setMFAChallenge(undefined);
+		setRecoveryCodes(undefined);
+		setMFAError(undefined);
+	};
+
+	const signInWithSecondFactor = async (request: LoginWithWebAuthnRequest) => {
+		try {
+			await signInWithWebAuthn(request);
+			navigate("/");
+		} catch {
+			resetMFA();
+		}
+	};
+
+	const onUseSecurityKey = async () => {
+		if (!mfaChallenge?.assertion) {
+			return;
+		}
+		setMFAError(undefined);
+		let assertion: WebAuthnAssertion;
+		try {
+			assertion = await getWebAuthnAssertion(mfaChallenge.assertion);
+		} catch (error) {
+			setMFAError(error);
+			return;
+		}
+		await signInWithSecondFactor({
+			challenge_id: mfaChallenge.assertion.challenge_id,
+			assertion,
+		});
+	};
+
+	const onUseRecoveryCode = async (code: string) => {
+		if (!mfaChallenge?.assertion) {
+			return;
+		}
+		await signInWithSecondFactor({
+			challenge_id: mfaChallenge.assertion.challenge_id,
+			recovery_code: code,
+		});
+	};
+
+	const onEnroll = async (name: string) => {
+		if (!mfaChallenge?.enrollment) {
+			return;
+		}
+		setMFAError(undefined);
+		let attestation: WebAuthnAttestation;
+		try {
+			attestation = await createWebAuthnAttestation(mfaChallenge.enrollment);
+		} catch (error) {
+			setMFAError(error);
+			return;
+		}
+		try {
+			const response = await enrollMutation.mutateAsync({
+				challenge_id: mfaChallenge.enrollment.challenge_id,
+				name,
+				attestation,
+			});
+			setRecoveryCodes(response.recovery_codes ?? []);
+		} catch {
+			resetMFA();
+		}
+	};
+
+	const onContinue = async () => {
+		try {
+			await completeSignIn();
+			navigate("/");
+		} catch {
+			resetMFA();
+		}
+	};
+
 	return (
 		<>
 			<Helmet>
@@ -82,15 +178,32 @@ const LoginPage: FC = () => {
 			</Helmet>
 			<LoginPageView
 				authMethods={authMethodsQuery.data}
-				error={signInError ?? redirectError}
+				error={enrollMutation.error ?? signInError ?? redirectError}
 				isLoading={isLoading || authMethodsQuery.isLoading}
 				buildInfo={buildInfoQuery.data}
-				isSigningIn={isSigningIn}
+				isSigningIn={isSigningIn || enrollMutation.isPending}
 				onSignIn={async ({ email, password }) => {
-					await signIn(email, password);
+					enrollMutation.reset();
+					const challenge = await signIn(email, password);
+					if (challenge) {
+						setMFAChallenge(challenge);
+						return;
+					}
 					navigate("/");
 				}}
 				redirectTo={redirectTo}
+				mfa={
+					mfaChallenge && {
+						challenge: mfaChallenge,
+						recoveryCodes,
+						error: mfaError,
+						onUseSecurityKey,
+						onUseRecoveryCode,
+						onEnroll,
+						onContinue,
+						onCancel: resetMFA,
+					}
+				}
 			/>
 		</>
 	);
@@ -1,3 +1,4 @@
+import { action } from "@storybook/addon-actions";
 import type { Meta, StoryObj } from "@storybook/react";
 import {
 	MockAuthMethodsAll,
@@ -5,6 +6,9 @@ import {
 	MockAuthMethodsPasswordOnly,
 	MockAuthMethodsPasswordTermsOfService,
 	MockBuildInfo,
+	MockLoginMFAAssertion,
+	MockLoginMFAEnrollment,
+	MockMFARecoveryCodes,
 	mockApiError,
 } from "testHelpers/entities";
 import { LoginPageView } from "./LoginPageView";
@@ -75,3 +79,47 @@ export const SigningIn: Story = {
 		authMethods: MockAuthMethodsPasswordOnly,
 	},
 };
+
+const mfaActions = {
+	onUseSecurityKey: action("onUseSecurityKey"),
+	onUseRecoveryCode: action("onUseRecoveryCode"),
+	onEnroll: action("onEnroll"),
+	onContinue: action("onContinue"),
+	onCancel: action("onCancel"),
+};
+
+export const WithSecurityKey: Story = {
+	args: {
+		authMethods: MockAuthMethodsPasswordOnly,
+		mfa: { challenge: MockLoginMFAAssertion, ...mfaActions },
+	},
+};
+
+export const WithSecurityKeyError: Story = {
+	args: {
+		authMethods: MockAuthMethodsPasswordOnly,
+		mfa: {
+			challenge: MockLoginMFAAssertion,
+			error: new Error("The operation either timed out or was not allowed."),
+			...mfaActions,
+		},
+	},
+};
+
+export const WithSecurityKeyEnrollment: Story = {
+	args: {
+		authMethods: MockAuthMethodsPasswordOnly,
+		mfa: { challenge: MockLoginMFAEnrollment, ...mfaActions },
+	},
+};
+
+export const WithRecoveryCodes: Story = {
+	args: {
+		authMethods: MockAuthMethodsPasswordOnly,
+		mfa: {
+			challenge: MockLoginMFAEnrollment,
+			recoveryCodes: MockMFARecoveryCodes,
+			...mfaActions,
+		},
+	},
+};
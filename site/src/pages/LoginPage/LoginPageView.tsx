@@ -1,10 +1,15 @@
 import type { Interpolation, Theme } from "@emotion/react";
-import type { AuthMethods, BuildInfoResponse } from "api/typesGenerated";
+import type {
+	AuthMethods,
+	BuildInfoResponse,
+	LoginMFAChallenge,
+} from "api/typesGenerated";
 import { Button } from "components/Button/Button";
 import { CustomLogo } from "components/CustomLogo/CustomLogo";
 import { Loader } from "components/Loader/Loader";
 import { type FC, useState } from "react";
 import { useLocation } from "react-router-dom";
+import { MFASignInForm } from "./MFASignInForm";
 import { SignInForm } from "./SignInForm";
 import { TermsOfServiceLink } from "./TermsOfServiceLink";
 
@@ -16,6 +21,20 @@ interface LoginPageViewProps {
 	isSigningIn: boolean;
 	onSignIn: (credentials: { email: string; password: string }) => void;
 	redirectTo: string;
+	/**
+	 * mfa is set when the password was correct, but the user must complete a
+	 * second factor to sign in.
+	 */
+	mfa?: {
+		challenge: LoginMFAChallenge;
+		recoveryCodes?: readonly string[];
+		error?: unknown;
+		onUseSecurityKey: () => void;
+		onUseRecoveryCode: (code: string) => void;
+		onEnroll: (name: string) => void;
+		onContinue: () => void;
+		onCancel: () => void;
+	};
 }
 
 export const LoginPageView: FC<LoginPageViewProps> = ({
@@ -26,6 +45,7 @@ export const LoginPageView: FC<LoginPageViewProps> = ({
 	isSigningIn,
 	onSignIn,
 	redirectTo,
+	mfa,
 }) => {
 	const location = useLocation();
 	// This allows messages to be displayed at the top of the sign in form.
@@ -52,6 +72,8 @@ export const LoginPageView: FC<LoginPageViewProps> = ({
 							I agree
 						</Button>
 					</>
+				) : mfa ? (
+					<MFASignInForm {...mfa} isSigningIn={isSigningIn} />
 				) : (
 					<SignInForm
 						authMethods={authMethods}
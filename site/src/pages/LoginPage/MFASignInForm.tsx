@@ -0,0 +1,199 @@
+import type { Interpolation, Theme } from "@emotion/react";
+import Link from "@mui/material/Link";
+import TextField from "@mui/material/TextField";
+import type { LoginMFAChallenge } from "api/typesGenerated";
+import { Alert } from "components/Alert/Alert";
+import { ErrorAlert } from "components/Alert/ErrorAlert";
+import { Button } from "components/Button/Button";
+import { Spinner } from "components/Spinner/Spinner";
+import { Stack } from "components/Stack/Stack";
+import { type FC, type FormEvent, useState } from "react";
+import { MONOSPACE_FONT_FAMILY } from "theme/constants";
+import { isWebAuthnSupported } from "utils/webauthn";
+import { Language } from "./Language";
+
+interface MFASignInFormProps {
+	challenge: LoginMFAChallenge;
+	/**
+	 * recoveryCodes are set once the first security key of the user has been
+	 * registered, and must be shown before the user continues.
+	 */
+	recoveryCodes?: readonly string[];
+	isSigningIn: boolean;
+	error?: unknown;
+	onUseSecurityKey: () => void;
+	onUseRecoveryCode: (code: string) => void;
+	onEnroll: (name: string) => void;
+	onContinue: () => void;
+	onCancel: () => void;
+}
+
+export const MFASignInForm: FC<MFASignInFormProps> = ({
+	challenge,
+	recoveryCodes,
+	isSigningIn,
+	error,
+	onUseSecurityKey,
+	onUseRecoveryCode,
+	onEnroll,
+	onContinue,
+	onCancel,
+}) => {
+	const [recoveryCode, setRecoveryCode] = useState("");
+	const [keyName, setKeyName] = useState(Language.securityKeyNameDefault);
+
+	if (recoveryCodes) {
+		return (
+			<div css={styles.root}>
+				<h1 css={styles.title}>{Language.recoveryCodesTitle}</h1>
+				<p css={styles.description}>{Language.recoveryCodesDescription}</p>
+				<pre css={styles.codes}>{recoveryCodes.join("\n")}</pre>
+				<Button
+					size="lg"
+					className="w-full"
+					disabled={isSigningIn}
+					onClick={onContinue}
+				>
+					<Spinner loading={isSigningIn} />
+					{Language.continue}
+				</Button>
+			</div>
+		);
+	}
+
+	const onSubmitRecoveryCode = (event: FormEvent) => {
+		event.preventDefault();
+		onUseRecoveryCode(recoveryCode.trim());
+	};
+
+	const onSubmitEnrollment = (event: FormEvent) => {
+		event.preventDefault();
+		onEnroll(keyName.trim());
+	};
+
+	return (
+		<div css={styles.root}>
+			<h1 css={styles.title}>
+				{challenge.enrollment
+					? Language.enrollSecurityKeyTitle
+					: Language.securityKeyTitle}
+			</h1>
+
+			{Boolean(error) && (
+				<div css={styles.alert}>
+					<ErrorAlert error={error} />
+				</div>
+			)}
+
+			{!isWebAuthnSupported() && (
+				<div css={styles.alert}>
+					<Alert severity="warning">{Language.webAuthnUnsupported}</Alert>
+				</div>
+			)}
+
+			{challenge.enrollment ? (
+				<form onSubmit={onSubmitEnrollment}>
+					<Stack spacing={2.5}>
+						<p css={styles.description}>
+							{Language.enrollSecurityKeyDescription}
+						</p>
+						<TextField
+							id="security_key_name"
+							fullWidth
+							required
+							label={Language.securityKeyNameLabel}
+							value={keyName}
+							onChange={(event) => setKeyName(event.target.value)}
+						/>
+						<Button
+							size="lg"
+							className="w-full"
+							type="submit"
+							disabled={isSigningIn || !keyName.trim()}
+						>
+							<Spinner loading={isSigningIn} />
+							{Language.enrollSecurityKey}
+						</Button>
+					</Stack>
+				</form>
+			) : (
+				<Stack spacing={2.5}>
+					<p css={styles.description}>{Language.securityKeyDescription}</p>
+					<Button
+						size="lg"
+						className="w-full"
+						disabled={isSigningIn}
+						onClick={onUseSecurityKey}
+					>
+						<Spinner loading={isSigningIn} />
+						{Language.useSecurityKey}
+					</Button>
+					<form onSubmit={onSubmitRecoveryCode}>
+						<Stack spacing={2.5}>
+							<TextField
+								id="recovery_code"
+								fullWidth
+								autoComplete="one-time-code"
+								label={Language.recoveryCodeLabel}
+								value={recoveryCode}
+								onChange={(event) => setRecoveryCode(event.target.value)}
+							/>
+							<Button
+								size="lg"
+								variant="outline"
+								className="w-full"
+								type="submit"
+								disabled={isSigningIn || !recoveryCode.trim()}
+							>
+								{Language.useRecoveryCode}
+							</Button>
+						</Stack>
+					</form>
+				</Stack>
+			)}
+
+			<Link component="button" css={styles.cancel} onClick={onCancel}>
+				{Language.backToSignIn}
+			</Link>
+		</div>
+	);
+};
+
+const styles = {
+	root: {
+		width: "100%",
+	},
+	title: {
+		fontSize: 24,
+		fontWeight: 400,
+		margin: 0,
+		marginBottom: 24,
+		lineHeight: 1.2,
+	},
+	description: (theme) => ({
+		margin: 0,
+		marginBottom: 16,
+		fontSize: 14,
+		color: theme.palette.text.secondary,
+	}),
+	alert: {
+		marginBottom: 24,
+	},
+	codes: (theme) => ({
+		margin: 0,
+		marginBottom: 24,
+		padding: 16,
+		fontSize: 14,
+		lineHeight: 1.6,
+		textAlign: "left",
+		borderRadius: 8,
+		border: `1px solid ${theme.palette.divider}`,
+		fontFamily: MONOSPACE_FONT_FAMILY,
+	}),
+	cancel: {
+		marginTop: 16,
+		fontSize: 12,
+		fontWeight: 500,
+		lineHeight: "16px",
+	},
+} satisfies Record<string, Interpolation<Theme>>;
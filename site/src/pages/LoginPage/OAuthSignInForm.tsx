@@ -63,15 +63,42 @@ export const OAuthSignInForm: FC<OAuthSignInFormProps> = ({
 					{authMethods.oidc.signInText || Language.oidcSignIn}
 				</Button>
 			)}
+
+			{authMethods?.saml.enabled && (
+				<Button
+					component="a"
+					href={`/api/v2/users/saml/login?redirect=${encodeURIComponent(
+						redirectTo,
+					)}`}
+					variant="contained"
+					size="xlarge"
+					startIcon={
+						authMethods.saml.iconUrl ? (
+							<OidcIcon iconUrl={authMethods.saml.iconUrl} label="SAML" />
+						) : (
+							<KeyIcon css={iconStyles} />
+						)
+					}
+					disabled={isSigningIn}
+					fullWidth
+					type="submit"
+				>
+					{authMethods.saml.signInText || Language.samlSignIn}
+				</Button>
+			)}
 		</div>
 	);
 };
 
 type OidcIconProps = {
 	iconUrl: string;
+	label?: string;
 };
 
-const OidcIcon: FC<OidcIconProps> = ({ iconUrl }) => {
+const OidcIcon: FC<OidcIconProps> = ({
+	iconUrl,
+	label = "Open ID Connect",
+}) => {
 	const hookId = useId();
 	const oidcId = `${hookId}-oidc`;
 
@@ -82,7 +109,7 @@ const OidcIcon: FC<OidcIconProps> = ({ iconUrl }) => {
 		<>
 			<img alt="" src={iconUrl} css={iconStyles} aria-labelledby={oidcId} />
 			<div id={oidcId} css={{ ...visuallyHidden }}>
-				Open ID Connect
+				{label}
 			</div>
 		</>
 	);
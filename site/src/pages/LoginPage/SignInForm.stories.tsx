@@ -22,6 +22,7 @@ export const SigningIn: Story = {
 			password: { enabled: true },
 			github: { enabled: true, default_provider_configured: false },
 			oidc: { enabled: false, signInText: "", iconUrl: "" },
+			saml: { enabled: false, signInText: "", iconUrl: "" },
 		},
 	},
 };
@@ -46,6 +47,7 @@ export const WithGithub: Story = {
 			password: { enabled: true },
 			github: { enabled: true, default_provider_configured: false },
 			oidc: { enabled: false, signInText: "", iconUrl: "" },
+			saml: { enabled: false, signInText: "", iconUrl: "" },
 		},
 	},
 };
@@ -56,6 +58,7 @@ export const WithOIDC: Story = {
 			password: { enabled: true },
 			github: { enabled: false, default_provider_configured: false },
 			oidc: { enabled: true, signInText: "", iconUrl: "" },
+			saml: { enabled: false, signInText: "", iconUrl: "" },
 		},
 	},
 };
@@ -66,6 +69,7 @@ export const WithOIDCWithoutPassword: Story = {
 			password: { enabled: false },
 			github: { enabled: false, default_provider_configured: false },
 			oidc: { enabled: true, signInText: "", iconUrl: "" },
+			saml: { enabled: false, signInText: "", iconUrl: "" },
 		},
 	},
 };
@@ -76,6 +80,7 @@ export const WithoutAny: Story = {
 			password: { enabled: false },
 			github: { enabled: false, default_provider_configured: false },
 			oidc: { enabled: false, signInText: "", iconUrl: "" },
+			saml: { enabled: false, signInText: "", iconUrl: "" },
 		},
 	},
 };
@@ -86,6 +91,18 @@ export const WithGithubAndOIDC: Story = {
 			password: { enabled: true },
 			github: { enabled: true, default_provider_configured: false },
 			oidc: { enabled: true, signInText: "", iconUrl: "" },
+			saml: { enabled: false, signInText: "", iconUrl: "" },
+		},
+	},
+};
+
+export const WithSAML: Story = {
+	args: {
+		authMethods: {
+			password: { enabled: true },
+			github: { enabled: false, default_provider_configured: false },
+			oidc: { enabled: false, signInText: "", iconUrl: "" },
+			saml: { enabled: true, signInText: "Okta", iconUrl: "" },
 		},
 	},
 };
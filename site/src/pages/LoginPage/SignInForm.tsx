@@ -68,7 +68,9 @@ export const SignInForm: FC<SignInFormProps> = ({
 	onSubmit,
 }) => {
 	const oAuthEnabled = Boolean(
-		authMethods?.github.enabled || authMethods?.oidc.enabled,
+		authMethods?.github.enabled ||
+			authMethods?.oidc.enabled ||
+			authMethods?.saml.enabled,
 	);
 	const passwordEnabled = authMethods?.password.enabled ?? true;
 	const applicationName = getApplicationName();
@@ -284,6 +284,19 @@ const LoginType: FC<LoginTypeProps> = ({ authMethods, value }) => {
 					css={styles.icon}
 				/>
 			);
+	} else if (value === "saml") {
+		displayName =
+			authMethods.saml.signInText === "" ? "SAML" : authMethods.saml.signInText;
+		icon =
+			authMethods.saml.iconUrl === "" ? (
+				<ShieldOutlined css={styles.icon} />
+			) : (
+				<img
+					alt="SAML icon"
+					src={authMethods.saml.iconUrl}
+					css={styles.icon}
+				/>
+			);
 	}
 
 	return (
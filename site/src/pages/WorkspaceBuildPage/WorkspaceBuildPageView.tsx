@@ -134,6 +134,35 @@ export const WorkspaceBuildPageView: FC<WorkspaceBuildPageViewProps> = ({
 							</span>
 						}
 					/>
+					{build.reason_message && (
+						<StatsItem
+							css={styles.statsItem}
+							label="Reason"
+							value={build.reason_message}
+						/>
+					)}
+					{build.initiator_context.api_key_name && (
+						<StatsItem
+							css={styles.statsItem}
+							label="Token"
+							value={build.initiator_context.api_key_name}
+						/>
+					)}
+					{build.initiator_context.ci_job_url && (
+						<StatsItem
+							css={styles.statsItem}
+							label="CI job"
+							value={
+								<a
+									href={build.initiator_context.ci_job_url}
+									target="_blank"
+									rel="noreferrer"
+								>
+									View job
+								</a>
+							}
+						/>
+					)}
 				</Stats>
 			</FullWidthPageHeader>
 
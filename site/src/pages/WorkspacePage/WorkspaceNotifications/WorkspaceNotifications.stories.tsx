@@ -1,5 +1,6 @@
 import type { Meta, StoryObj } from "@storybook/react";
 import { expect, userEvent, waitFor, within } from "@storybook/test";
+import { workspaceBuildQueueKey } from "api/queries/workspaceBuilds";
 import { getWorkspaceResolveAutostartQueryKey } from "api/queries/workspaceQuota";
 import type { WorkspacePermissions } from "modules/workspaces/permissions";
 import {
@@ -204,6 +205,27 @@ export const PendingInQueue: Story = {
 			},
 		},
 	},
+	parameters: {
+		queries: [
+			{
+				key: getWorkspaceResolveAutostartQueryKey(MockOutdatedWorkspace.id),
+				data: {
+					parameter_mismatch: false,
+				},
+			},
+			{
+				key: workspaceBuildQueueKey(MockWorkspace.latest_build.id),
+				data: {
+					status: "pending",
+					position: 3,
+					size: 10,
+					tags: { scope: "organization", owner: "" },
+					matched_provisioners: { count: 2, available: 2 },
+					estimated_start_at: new Date(Date.now() + 5 * 60_000).toISOString(),
+				},
+			},
+		],
+	},
 
 	play: async ({ canvasElement, step }) => {
 		const screen = within(canvasElement);
@@ -213,6 +235,9 @@ export const PendingInQueue: Story = {
 			await waitFor(() =>
 				expect(screen.getByText(/build is pending/i)).toBeInTheDocument(),
 			);
+			await waitFor(() =>
+				expect(screen.getByText(/estimated start/i)).toBeInTheDocument(),
+			);
 		});
 	},
 };
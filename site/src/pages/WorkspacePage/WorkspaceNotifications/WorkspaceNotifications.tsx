@@ -1,4 +1,5 @@
 import type { Interpolation, Theme } from "@emotion/react";
+import { workspaceBuildQueue } from "api/queries/workspaceBuilds";
 import { workspaceResolveAutostart } from "api/queries/workspaceQuota";
 import type {
 	Template,
@@ -204,6 +205,13 @@ export const WorkspaceNotifications: FC<WorkspaceNotificationsProps> = ({
 		};
 	}, [workspace, now, showAlertPendingInQueue]);
 
+	const buildQueueQuery = useQuery({
+		...workspaceBuildQueue(workspace.latest_build.id),
+		enabled: showAlertPendingInQueue,
+		refetchInterval: 5_000,
+	});
+	const buildQueue = buildQueueQuery.data;
+
 	if (showAlertPendingInQueue) {
 		notifications.push({
 			title: "Workspace build is pending",
@@ -217,6 +225,32 @@ export const WorkspaceNotifications: FC<WorkspaceNotificationsProps> = ({
 						Position in queue:{" "}
 						<strong>{workspace.latest_build.job.queue_position}</strong>
 					</span>
+					{buildQueue && (
+						<>
+							<span css={{ display: "block" }}>
+								Available provisioners:{" "}
+								<strong>{buildQueue.matched_provisioners.available}</strong>
+							</span>
+							{buildQueue.estimated_start_at && (
+								<span css={{ display: "block" }}>
+									Estimated start:{" "}
+									<strong>
+										{dayjs(buildQueue.estimated_start_at).fromNow()}
+									</strong>
+								</span>
+							)}
+							{Object.keys(buildQueue.tags).length > 0 && (
+								<span css={{ display: "block" }}>
+									Provisioner tags:{" "}
+									<strong>
+										{Object.entries(buildQueue.tags)
+											.map(([key, value]) => `${key}=${value}`)
+											.join(", ")}
+									</strong>
+								</span>
+							)}
+						</>
+					)}
 				</>
 			),
 		});
@@ -86,6 +86,7 @@ const WorkspaceParametersExperimentRouter = lazy(
 		),
 );
 const TerminalPage = lazy(() => import("./pages/TerminalPage/TerminalPage"));
+const DesktopPage = lazy(() => import("./pages/DesktopPage/DesktopPage"));
 const TemplatePermissionsPage = lazy(
 	() =>
 		import(
@@ -585,6 +586,10 @@ export const router = createBrowserRouter(
 					path="/:username/:workspace/terminal"
 					element={<TerminalPage />}
 				/>
+				<Route
+					path="/:username/:workspace/desktop"
+					element={<DesktopPage />}
+				/>
 				<Route path="/cli-auth" element={<CliAuthPage />} />
 				<Route path="/icons" element={<IconsPage />} />
 				<Route path="/tasks/:username/:workspace" element={<TaskPage />} />
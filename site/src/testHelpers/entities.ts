@@ -827,6 +827,15 @@ export const MockTemplate: TypesGen.Template = {
 	deprecation_message: "",
 	max_port_share_level: "public",
 	use_classic_parameter_flow: true,
+	wireguard_mtu: 0,
+	wireguard_keepalive_interval_ms: 0,
+	wireguard_handshake_timeout_ms: 0,
+	activation_approvals_required: 0,
+	autostart_holidays: [],
+	autostart_holiday_calendar_url: "",
+	dormant_archive_paths: [],
+	parameter_validation_url: "",
+	allow_start_on_connect: true,
 };
 
 const MockTemplateVersionFiles: TemplateVersionFiles = {
@@ -1272,6 +1281,7 @@ export const MockWorkspaceBuild: TypesGen.WorkspaceBuild = {
 	workspace_id: "759f1d46-3174-453d-aa60-980a9c1442f3",
 	deadline: "2022-05-17T23:39:00.00Z",
 	reason: "initiator",
+	initiator_context: {},
 	resources: [MockWorkspaceResource],
 	status: "running",
 	daily_cost: 20,
@@ -1300,6 +1310,7 @@ const MockWorkspaceBuildAutostart: TypesGen.WorkspaceBuild = {
 	workspace_id: "759f1d46-3174-453d-aa60-980a9c1442f3",
 	deadline: "2022-05-17T23:39:00.00Z",
 	reason: "autostart",
+	initiator_context: {},
 	resources: [MockWorkspaceResource],
 	status: "running",
 	daily_cost: 20,
@@ -1324,6 +1335,7 @@ const MockWorkspaceBuildAutostop: TypesGen.WorkspaceBuild = {
 	workspace_id: "759f1d46-3174-453d-aa60-980a9c1442f3",
 	deadline: "2022-05-17T23:39:00.00Z",
 	reason: "autostop",
+	initiator_context: {},
 	resources: [MockWorkspaceResource],
 	status: "running",
 	daily_cost: 20,
@@ -1350,6 +1362,7 @@ export const MockFailedWorkspaceBuild = (
 	workspace_id: "759f1d46-3174-453d-aa60-980a9c1442f3",
 	deadline: "2022-05-17T23:39:00.00Z",
 	reason: "initiator",
+	initiator_context: {},
 	resources: [],
 	status: "failed",
 	daily_cost: 20,
@@ -1411,6 +1424,8 @@ export const MockWorkspace: TypesGen.Workspace = {
 	deleting_at: null,
 	dormant_at: null,
 	next_start_at: null,
+	labels: {},
+	ephemeral: false,
 };
 
 export const MockFavoriteWorkspace: TypesGen.Workspace = {
@@ -1739,6 +1754,7 @@ export const MockAuthMethodsPasswordOnly: TypesGen.AuthMethods = {
 	password: { enabled: true },
 	github: { enabled: false, default_provider_configured: true },
 	oidc: { enabled: false, signInText: "", iconUrl: "" },
+	saml: { enabled: false, signInText: "", iconUrl: "" },
 };
 
 export const MockAuthMethodsPasswordTermsOfService: TypesGen.AuthMethods = {
@@ -1746,6 +1762,7 @@ export const MockAuthMethodsPasswordTermsOfService: TypesGen.AuthMethods = {
 	password: { enabled: true },
 	github: { enabled: false, default_provider_configured: true },
 	oidc: { enabled: false, signInText: "", iconUrl: "" },
+	saml: { enabled: false, signInText: "", iconUrl: "" },
 };
 
 export const MockAuthMethodsExternal: TypesGen.AuthMethods = {
@@ -1756,6 +1773,7 @@ export const MockAuthMethodsExternal: TypesGen.AuthMethods = {
 		signInText: "Google",
 		iconUrl: "/icon/google.svg",
 	},
+	saml: { enabled: false, signInText: "", iconUrl: "" },
 };
 
 export const MockAuthMethodsAll: TypesGen.AuthMethods = {
@@ -1766,8 +1784,41 @@ export const MockAuthMethodsAll: TypesGen.AuthMethods = {
 		signInText: "Google",
 		iconUrl: "/icon/google.svg",
 	},
+	saml: { enabled: true, signInText: "", iconUrl: "" },
 };
 
+export const MockLoginMFAAssertion: TypesGen.LoginMFAChallenge = {
+	assertion: {
+		challenge_id: "6d2f9c4e-8b0e-4a36-9a0d-0b1b3c3f5a11",
+		challenge: "q2pVbOyN8y3l0w2x5FZQ4Yw7X0uY1mJjS6rZfWcH3kA",
+		rp_id: "coder.example.com",
+		allow_credentials: ["kL3n4M5o6P7q8R9s0T1u2V3w4X5y6Z7a"],
+		timeout_ms: 60000,
+	},
+};
+
+export const MockLoginMFAEnrollment: TypesGen.LoginMFAChallenge = {
+	enrollment: {
+		challenge_id: "0c7a1f2e-3d4b-4c5a-8e9f-a1b2c3d4e5f6",
+		challenge: "Zx8Yw7Vu6Ts5Rq4Po3Nm2Lk1Jh0Gf9Ed8Cb7Aa6Bc5D",
+		rp_id: "coder.example.com",
+		rp_name: "Coder",
+		user_handle: "AAECAwQFBgcICQoLDA0ODw",
+		username: "TestUser",
+		algorithms: [-7, -257],
+		exclude_credentials: [],
+		timeout_ms: 60000,
+	},
+};
+
+export const MockMFARecoveryCodes = [
+	"7kq2-9xwp-4mzt",
+	"h3vd-8rny-2bcf",
+	"p6ls-1gje-5wqa",
+	"t9uo-3kxe-7dhm",
+	"b2nc-6fpz-8ryv",
+];
+
 export const MockGitSSHKey: TypesGen.GitSSHKey = {
 	user_id: "1fa0200f-7331-4524-a364-35770666caa7",
 	created_at: "2022-05-16T14:30:34.148205897Z",
@@ -4225,6 +4276,7 @@ export const MockNotificationTemplates: TypesGen.NotificationTemplate[] = [
 		method: "webhook",
 		kind: "system",
 		enabled_by_default: true,
+		priority: "normal",
 	},
 	{
 		id: "f517da0b-cdc9-410f-ab89-a86107c420ed",
@@ -4238,6 +4290,7 @@ export const MockNotificationTemplates: TypesGen.NotificationTemplate[] = [
 		method: "smtp",
 		kind: "system",
 		enabled_by_default: true,
+		priority: "normal",
 	},
 	{
 		id: "f44d9314-ad03-4bc8-95d0-5cad491da6b6",
@@ -4251,6 +4304,7 @@ export const MockNotificationTemplates: TypesGen.NotificationTemplate[] = [
 		method: "",
 		kind: "system",
 		enabled_by_default: true,
+		priority: "normal",
 	},
 	{
 		id: "4e19c0ac-94e1-4532-9515-d1801aa283b2",
@@ -4264,6 +4318,7 @@ export const MockNotificationTemplates: TypesGen.NotificationTemplate[] = [
 		method: "",
 		kind: "system",
 		enabled_by_default: true,
+		priority: "normal",
 	},
 	{
 		id: "0ea69165-ec14-4314-91f1-69566ac3c5a0",
@@ -4277,6 +4332,7 @@ export const MockNotificationTemplates: TypesGen.NotificationTemplate[] = [
 		method: "smtp",
 		kind: "system",
 		enabled_by_default: true,
+		priority: "normal",
 	},
 	{
 		id: "c34a0c09-0704-4cac-bd1c-0c0146811c2b",
@@ -4290,6 +4346,7 @@ export const MockNotificationTemplates: TypesGen.NotificationTemplate[] = [
 		method: "smtp",
 		kind: "system",
 		enabled_by_default: true,
+		priority: "normal",
 	},
 	{
 		id: "51ce2fdf-c9ca-4be1-8d70-628674f9bc42",
@@ -4303,6 +4360,7 @@ export const MockNotificationTemplates: TypesGen.NotificationTemplate[] = [
 		method: "webhook",
 		kind: "system",
 		enabled_by_default: true,
+		priority: "normal",
 	},
 ];
 
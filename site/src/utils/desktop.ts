@@ -0,0 +1,37 @@
+import { API } from "api/api";
+
+export const desktopWebsocketUrl = async (
+	baseUrl: string | undefined,
+	agentId: string,
+	port: number | undefined,
+): Promise<string> => {
+	const query = new URLSearchParams();
+	if (port) {
+		query.set("port", port.toString());
+	}
+
+	const url = new URL(baseUrl || `${location.protocol}//${location.host}`);
+	url.protocol = url.protocol === "https:" ? "wss:" : "ws:";
+	if (!url.pathname.endsWith("/")) {
+		url.pathname += "/";
+	}
+	url.pathname += `api/v2/workspaceagents/${agentId}/desktop/vnc`;
+	url.search = `?${query.toString()}`;
+
+	// If the URL is just the primary API, we don't need a signed token to
+	// connect.
+	if (!baseUrl) {
+		return url.toString();
+	}
+
+	// The desktop is authorized like the terminal, so the same signed token
+	// works for workspace proxies.
+	const tokenRes = await API.issueReconnectingPTYSignedToken({
+		url: url.toString(),
+		agentID: agentId,
+	});
+	query.set("coder_signed_app_token_23db1dde", tokenRes.signed_token);
+	url.search = `?${query.toString()}`;
+
+	return url.toString();
+};
@@ -0,0 +1,15 @@
+import { decodeBase64URL, encodeBase64URL } from "./webauthn";
+
+describe("webauthn helper functions", () => {
+	it("round trips base64url values", () => {
+		const bytes = new Uint8Array([0xfb, 0xff, 0x00, 0x3e, 0x3f]);
+		const encoded = encodeBase64URL(bytes.buffer);
+		expect(encoded).toEqual("-_8APj8");
+		expect(new Uint8Array(decodeBase64URL(encoded))).toEqual(bytes);
+	});
+	it("decodes padded values", () => {
+		expect(new Uint8Array(decodeBase64URL("AQ=="))).toEqual(
+			new Uint8Array([1]),
+		);
+	});
+});
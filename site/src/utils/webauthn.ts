@@ -0,0 +1,108 @@
+import type {
+	WebAuthnAssertion,
+	WebAuthnAttestation,
+	WebAuthnCreationOptions,
+	WebAuthnRequestOptions,
+} from "api/typesGenerated";
+
+/**
+ * Decodes a base64url value, with or without padding, as the API encodes all
+ * binary WebAuthn values.
+ */
+export const decodeBase64URL = (value: string): ArrayBuffer => {
+	const base64 = value.replace(/-/g, "+").replace(/_/g, "/");
+	const binary = atob(base64.padEnd(Math.ceil(base64.length / 4) * 4, "="));
+	const bytes = new Uint8Array(binary.length);
+	for (let i = 0; i < binary.length; i++) {
+		bytes[i] = binary.charCodeAt(i);
+	}
+	return bytes.buffer;
+};
+
+/**
+ * Encodes a binary value as base64url without padding.
+ */
+export const encodeBase64URL = (value: ArrayBuffer): string => {
+	let binary = "";
+	for (const byte of new Uint8Array(value)) {
+		binary += String.fromCharCode(byte);
+	}
+	return btoa(binary)
+		.replace(/\+/g, "-")
+		.replace(/\//g, "_")
+		.replace(/=+$/, "");
+};
+
+export const isWebAuthnSupported = (): boolean =>
+	typeof window !== "undefined" && window.PublicKeyCredential !== undefined;
+
+/**
+ * Asks the browser to sign a login challenge with one of the security keys of
+ * the user.
+ */
+export const getWebAuthnAssertion = async (
+	options: WebAuthnRequestOptions,
+): Promise<WebAuthnAssertion> => {
+	const credential = await navigator.credentials.get({
+		publicKey: {
+			challenge: decodeBase64URL(options.challenge),
+			rpId: options.rp_id,
+			allowCredentials: options.allow_credentials.map((id) => ({
+				type: "public-key",
+				id: decodeBase64URL(id),
+			})),
+			timeout: options.timeout_ms,
+			userVerification: "discouraged",
+		},
+	});
+	if (!(credential instanceof PublicKeyCredential)) {
+		throw new Error("No security key was used.");
+	}
+
+	const response = credential.response as AuthenticatorAssertionResponse;
+	return {
+		credential_id: encodeBase64URL(credential.rawId),
+		client_data_json: encodeBase64URL(response.clientDataJSON),
+		authenticator_data: encodeBase64URL(response.authenticatorData),
+		signature: encodeBase64URL(response.signature),
+	};
+};
+
+/**
+ * Asks the browser to register a new security key for the user.
+ */
+export const createWebAuthnAttestation = async (
+	options: WebAuthnCreationOptions,
+): Promise<WebAuthnAttestation> => {
+	const credential = await navigator.credentials.create({
+		publicKey: {
+			challenge: decodeBase64URL(options.challenge),
+			rp: { id: options.rp_id, name: options.rp_name },
+			user: {
+				id: decodeBase64URL(options.user_handle),
+				name: options.username,
+				displayName: options.username,
+			},
+			pubKeyCredParams: options.algorithms.map((alg) => ({
+				type: "public-key",
+				alg,
+			})),
+			excludeCredentials: options.exclude_credentials.map((id) => ({
+				type: "public-key",
+				id: decodeBase64URL(id),
+			})),
+			timeout: options.timeout_ms,
+			attestation: "none",
+			authenticatorSelection: { userVerification: "discouraged" },
+		},
+	});
+	if (!(credential instanceof PublicKeyCredential)) {
+		throw new Error("No security key was registered.");
+	}
+
+	const response = credential.response as AuthenticatorAttestationResponse;
+	return {
+		client_data_json: encodeBase64URL(response.clientDataJSON),
+		attestation_object: encodeBase64URL(response.attestationObject),
+	};
+};
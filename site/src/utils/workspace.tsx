@@ -82,6 +82,7 @@ export const getDisplayWorkspaceBuildInitiatedBy = (
 		case "autostart":
 		case "autostop":
 		case "dormancy":
+		case "autoarchive":
 			return "Coder";
 	}
 	return undefined;
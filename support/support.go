@@ -8,7 +8,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/sync/errgroup"
@@ -36,6 +38,8 @@ type Bundle struct {
 	Agent      Agent      `json:"agent"`
 	Logs       []string   `json:"logs"`
 	CLILogs    []byte     `json:"cli_logs"`
+	// Replicas is only populated if profiles are included.
+	Replicas []Replica `json:"replicas"`
 }
 
 type Deployment struct {
@@ -46,6 +50,15 @@ type Deployment struct {
 	Licenses     []codersdk.License           `json:"licenses"`
 }
 
+// Replica is information captured from a single replica of the deployment.
+type Replica struct {
+	Replica          codersdk.Replica              `json:"replica"`
+	Diagnostics      *healthsdk.ReplicaDiagnostics `json:"diagnostics"`
+	HealthReport     *healthsdk.HealthcheckReport  `json:"health_report"`
+	CoordinatorDebug string                        `json:"coordinator_debug"`
+	TailnetDebug     string                        `json:"tailnet_debug"`
+}
+
 type Network struct {
 	ConnectionInfo   workspacesdk.AgentConnectionInfo
 	CoordinatorDebug string                     `json:"coordinator_debug"`
@@ -94,8 +107,14 @@ type Deps struct {
 	// AgentID is the optional agent ID against which to run connection tests.
 	// Defaults to the first agent of the workspace, if not specified.
 	AgentID uuid.UUID
+	// IncludeProfiles captures pprof profiles, recent logs and other runtime
+	// diagnostics from every replica of the deployment.
+	IncludeProfiles bool
 }
 
+// cpuProfileDuration is how long the CPU profile of each replica runs.
+const cpuProfileDuration = 10 * time.Second
+
 func DeploymentInfo(ctx context.Context, client *codersdk.Client, log slog.Logger) Deployment {
 	// Note: each goroutine assigns to a different struct field, hence no mutex.
 	var (
@@ -227,6 +246,136 @@ func NetworkInfo(ctx context.Context, client *codersdk.Client, log slog.Logger)
 	return n
 }
 
+// ReplicasInfo captures runtime diagnostics from every replica of the
+// deployment. The replica that serves client is captured first; other
+// replicas are reached through their relay address, which must be
+// reachable from the client.
+func ReplicasInfo(ctx context.Context, client *codersdk.Client, log slog.Logger) []Replica {
+	// The serving replica is captured before the others, because only one
+	// CPU profile can run per process and the client does not know which
+	// replica serves it.
+	self := replicaInfo(ctx, client, log)
+	if self.Diagnostics == nil {
+		return []Replica{self}
+	}
+	self.Replica = codersdk.Replica{
+		ID:       self.Diagnostics.ReplicaID,
+		Hostname: self.Diagnostics.Hostname,
+	}
+
+	replicas, err := client.Replicas(ctx)
+	if err != nil {
+		// Ignore 404 because AGPL doesn't have this endpoint
+		if cerr, ok := codersdk.AsError(err); !ok || cerr.StatusCode() != http.StatusNotFound {
+			log.Error(ctx, "fetch replicas", slog.Error(err))
+		}
+		return []Replica{self}
+	}
+
+	infos := make([]Replica, len(replicas))
+	foundSelf := false
+	var eg errgroup.Group
+	for i, replica := range replicas {
+		if replica.ID == self.Replica.ID {
+			self.Replica = replica
+			infos[i] = self
+			foundSelf = true
+			continue
+		}
+		infos[i].Replica = replica
+		relayURL, err := url.Parse(replica.RelayAddress)
+		if replica.RelayAddress == "" || err != nil {
+			log.Error(ctx, "replica has no usable relay address, use --url-override to capture it",
+				slog.F("replica_id", replica.ID),
+				slog.F("replica_hostname", replica.Hostname),
+				slog.F("relay_address", replica.RelayAddress),
+			)
+			continue
+		}
+		replicaClient := codersdk.New(relayURL)
+		replicaClient.HTTPClient = client.HTTPClient
+		replicaClient.SessionTokenHeader = client.SessionTokenHeader
+		replicaClient.SetSessionToken(client.SessionToken())
+		replicaClient.SetLogger(log)
+		eg.Go(func() error {
+			info := replicaInfo(ctx, replicaClient, log.With(slog.F("replica_id", replica.ID)))
+			info.Replica = replica
+			infos[i] = info
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	if !foundSelf {
+		infos = append(infos, self)
+	}
+	return infos
+}
+
+func replicaInfo(ctx context.Context, client *codersdk.Client, log slog.Logger) Replica {
+	var (
+		r  Replica
+		eg errgroup.Group
+	)
+
+	eg.Go(func() error {
+		diag, err := healthsdk.New(client).DebugDiagnostics(ctx, cpuProfileDuration)
+		if err != nil {
+			return xerrors.Errorf("fetch replica diagnostics: %w", err)
+		}
+		r.Diagnostics = &diag
+		return nil
+	})
+
+	eg.Go(func() error {
+		hr, err := healthsdk.New(client).DebugHealth(ctx)
+		if err != nil {
+			return xerrors.Errorf("fetch health report: %w", err)
+		}
+		r.HealthReport = &hr
+		return nil
+	})
+
+	eg.Go(func() error {
+		bs, err := debugPage(ctx, client, "/api/v2/debug/coordinator")
+		if err != nil {
+			return xerrors.Errorf("fetch coordinator debug page: %w", err)
+		}
+		r.CoordinatorDebug = bs
+		return nil
+	})
+
+	eg.Go(func() error {
+		bs, err := debugPage(ctx, client, "/api/v2/debug/tailnet")
+		if err != nil {
+			return xerrors.Errorf("fetch tailnet debug page: %w", err)
+		}
+		r.TailnetDebug = bs
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil {
+		log.Error(ctx, "fetch replica information", slog.Error(err))
+	}
+
+	return r
+}
+
+func debugPage(ctx context.Context, client *codersdk.Client, path string) (string, error) {
+	res, err := client.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", codersdk.ReadBodyAsError(res)
+	}
+	bs, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", xerrors.Errorf("read body: %w", err)
+	}
+	return string(bs), nil
+}
+
 func WorkspaceInfo(ctx context.Context, client *codersdk.Client, log slog.Logger, workspaceID uuid.UUID) Workspace {
 	var (
 		w  Workspace
@@ -486,6 +635,14 @@ func Run(ctx context.Context, d *Deps) (*Bundle, error) {
 			Action: codersdk.ActionRead,
 		},
 	}
+	if d.IncludeProfiles {
+		authChecks["Read DebugInfo"] = codersdk.AuthorizationCheck{
+			Object: codersdk.AuthorizationObject{
+				ResourceType: codersdk.ResourceDebugInfo,
+			},
+			Action: codersdk.ActionRead,
+		}
+	}
 
 	// Ensure we capture logs from the client.
 	var logw strings.Builder
@@ -526,6 +683,12 @@ func Run(ctx context.Context, d *Deps) (*Bundle, error) {
 		b.Agent = ai
 		return nil
 	})
+	if d.IncludeProfiles {
+		eg.Go(func() error {
+			b.Replicas = ReplicasInfo(ctx, d.Client, d.Log)
+			return nil
+		})
+	}
 
 	_ = eg.Wait()
 
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
@@ -122,6 +123,32 @@ func TestRun(t *testing.T) {
 		assertNotNilNotEmpty(t, bun.Logs, "bundle logs should be present")
 	})
 
+	t.Run("OK_IncludeProfiles", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, &coderdtest.Options{
+			Logger: ptr.Ref(slog.Make(sloghuman.Sink(io.Discard))),
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		bun, err := support.Run(ctx, &support.Deps{
+			Client:          client,
+			Log:             slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Named("bundle").Leveled(slog.LevelDebug),
+			IncludeProfiles: true,
+		})
+		require.NoError(t, err)
+		// AGPL deployments have a single replica.
+		require.Len(t, bun.Replicas, 1)
+		replica := bun.Replicas[0]
+		assertNotNilNotEmpty(t, replica.Diagnostics, "replica diagnostics should be present")
+		assert.NotEqual(t, uuid.Nil, replica.Replica.ID, "replica id should be present")
+		for _, name := range []string{"goroutine", "heap", "cpu"} {
+			assert.NotEmpty(t, replica.Diagnostics.Profiles[name], "%s profile should be present", name)
+		}
+		assertNotNilNotEmpty(t, replica.HealthReport, "replica health report should be present")
+		assertNotNilNotEmpty(t, replica.CoordinatorDebug, "replica coordinator debug should be present")
+		assertNotNilNotEmpty(t, replica.TailnetDebug, "replica tailnet debug should be present")
+	})
+
 	t.Run("NoAuth", func(t *testing.T) {
 		t.Parallel()
 		ctx := testutil.Context(t, testutil.WaitLong)
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"math"
 	"net/netip"
 	"slices"
 	"sync"
@@ -82,6 +83,11 @@ type configMaps struct {
 	logger         slog.Logger
 	blockEndpoints bool
 	matchDomain    dnsname.FQDN
+	// keepaliveInterval overrides the WireGuard persistent keepalive interval
+	// of peers that have keepalives enabled, if positive.
+	keepaliveInterval time.Duration
+	// handshakeTimeout overrides readyForHandshakeTimeout, if positive.
+	handshakeTimeout time.Duration
 
 	// for testing
 	clock quartz.Clock
@@ -174,10 +180,11 @@ func (c *configMaps) configLoop() {
 		if c.netmapDirty {
 			nm := c.netMapLocked()
 			hosts := c.hostsLocked()
+			keepalive := c.keepaliveInterval
 			actions = append(actions, func() {
 				c.logger.Debug(context.Background(), "updating engine network map", slog.F("network_map", nm))
 				c.engine.SetNetworkMap(nm)
-				c.reconfig(nm, hosts)
+				c.reconfig(nm, hosts, keepalive)
 			})
 		}
 		if c.filterDirty {
@@ -311,6 +318,28 @@ func (c *configMaps) setBlockEndpoints(blockEndpoints bool) {
 	c.Broadcast()
 }
 
+// setKeepaliveInterval sets the WireGuard persistent keepalive interval used
+// for peers that have keepalives enabled. Zero restores the default of 25
+// seconds.
+func (c *configMaps) setKeepaliveInterval(d time.Duration) {
+	c.L.Lock()
+	defer c.L.Unlock()
+	if c.keepaliveInterval != d {
+		c.netmapDirty = true
+	}
+	c.keepaliveInterval = d
+	c.Broadcast()
+}
+
+// setHandshakeTimeout sets how long to wait for a READY_FOR_HANDSHAKE from a
+// destination peer before programming it into wireguard anyway. Zero restores
+// the default of readyForHandshakeTimeout.
+func (c *configMaps) setHandshakeTimeout(d time.Duration) {
+	c.L.Lock()
+	defer c.L.Unlock()
+	c.handshakeTimeout = d
+}
+
 // getBlockEndpoints returns the value of the most recent setBlockEndpoints
 // call.
 func (c *configMaps) getBlockEndpoints() bool {
@@ -342,7 +371,7 @@ func (c *configMaps) derpMapLocked() *tailcfg.DERPMap {
 // reconfig computes the correct wireguard config and calls the engine.Reconfig
 // with the config we have.  It is not intended for this to be called outside of
 // the updateLoop()
-func (c *configMaps) reconfig(nm *netmap.NetworkMap, hosts map[dnsname.FQDN][]netip.Addr) {
+func (c *configMaps) reconfig(nm *netmap.NetworkMap, hosts map[dnsname.FQDN][]netip.Addr, keepalive time.Duration) {
 	dnsCfg := &dns.Config{}
 	if len(hosts) > 0 {
 		dnsCfg.Hosts = hosts
@@ -358,6 +387,15 @@ func (c *configMaps) reconfig(nm *netmap.NetworkMap, hosts map[dnsname.FQDN][]ne
 		c.logger.Critical(context.Background(), "update wireguard config failed", slog.Error(err))
 		return
 	}
+	if keepalive > 0 {
+		seconds := max(1, min(keepalive/time.Second, math.MaxUint16))
+		for i := range cfg.Peers {
+			// nmcfg only sets a keepalive for peers with KeepAlive enabled.
+			if cfg.Peers[i].PersistentKeepalive != 0 {
+				cfg.Peers[i].PersistentKeepalive = uint16(seconds) // #nosec G115 - clamped above
+			}
+		}
+	}
 
 	rc := &router.Config{
 		LocalAddrs: nm.Addresses,
@@ -747,7 +785,11 @@ func (l *peerLifecycle) setReadyForHandshakeTimer(c *configMaps) {
 	if l.readyForHandshakeTimer != nil {
 		l.readyForHandshakeTimer.Stop()
 	}
-	l.readyForHandshakeTimer = c.clock.AfterFunc(readyForHandshakeTimeout, func() {
+	timeout := readyForHandshakeTimeout
+	if c.handshakeTimeout > 0 {
+		timeout = c.handshakeTimeout
+	}
+	l.readyForHandshakeTimer = c.clock.AfterFunc(timeout, func() {
 		c.logger.Debug(context.Background(), "ready for handshake timeout", slog.F("peer_id", l.peerID))
 		c.peerReadyForHandshakeTimeout(l.peerID)
 	})
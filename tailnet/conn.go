@@ -63,6 +63,13 @@ const (
 // with the debug level enabled.
 const EnvMagicsockDebugLogging = "CODER_MAGICSOCK_DEBUG_LOGGING"
 
+const (
+	// MinMTU is the smallest MTU allowed for IPv6, and therefore for tailnet.
+	MinMTU = 1280
+	// MaxMTU is the largest MTU the netstack supports.
+	MaxMTU = 65536
+)
+
 func init() {
 	// Globally disable network namespacing. All networking happens in
 	// userspace.
@@ -98,8 +105,27 @@ type Options struct {
 	// BlockEndpoints specifies whether P2P endpoints are blocked.
 	// If so, only DERPs can establish connections.
 	BlockEndpoints bool
-	Logger         slog.Logger
-	ListenPort     uint16
+	// HTTPSFallback relays all traffic over WebSocket connections to DERP
+	// servers, without trying P2P endpoints, while netcheck finds that UDP is
+	// blocked and DERP over HTTPS is the only path out of the network.
+	HTTPSFallback bool
+	// NetcheckInterval, if positive, is how often endpoints are rediscovered
+	// via STUN, in addition to magicsock's own schedule. This is useful behind
+	// NATs that expire UDP mappings faster than magicsock re-probes them.
+	NetcheckInterval time.Duration
+	// MTU, if positive, overrides the MTU of the tailnet interface, which is
+	// 1280 by default. It must be at least 1280 since tailnet addresses are
+	// IPv6.
+	MTU int
+	// KeepaliveInterval, if positive, overrides the WireGuard persistent
+	// keepalive interval of 25 seconds.
+	KeepaliveInterval time.Duration
+	// HandshakeTimeout, if positive, overrides how long to wait for a
+	// destination peer to be ready for a WireGuard handshake before trying
+	// anyway. The default is 5 seconds.
+	HandshakeTimeout time.Duration
+	Logger           slog.Logger
+	ListenPort       uint16
 	// CaptureHook is a callback that captures Disco packets and packets sent
 	// into the tailnet tunnel.
 	CaptureHook capture.Callback
@@ -191,11 +217,25 @@ func NewConn(options *Options) (conn *Conn, err error) {
 		}
 	}()
 
+	if options.MTU > 0 {
+		if options.MTU < MinMTU || options.MTU > MaxMTU {
+			return nil, xerrors.Errorf("mtu %d must be between %d and %d", options.MTU, MinMTU, MaxMTU)
+		}
+		// The netstack reads the MTU from this knob when it's created. Like
+		// TS_DEBUG_TRIM_WIREGUARD, it's process-wide, which is fine because a
+		// process only runs a single tailnet in practice.
+		envknob.Setenv("TS_DEBUG_MTU", strconv.Itoa(options.MTU))
+	}
+
 	dialer := &tsdial.Dialer{
 		Logf: Logger(options.Logger.Named("net.tsdial")),
 	}
 	sys := new(tsd.System)
-	wireguardEngine, err := wgengine.NewUserspaceEngine(Logger(options.Logger.Named("net.wgengine")), wgengine.Config{
+	wireguardLogf := Logger(options.Logger.Named("net.wgengine"))
+	if telemetryStore != nil {
+		wireguardLogf = telemetryStore.wireguardLogf(wireguardLogf)
+	}
+	wireguardEngine, err := wgengine.NewUserspaceEngine(wireguardLogf, wgengine.Config{
 		NetMon:       options.WireguardMonitor,
 		Dialer:       dialer,
 		ListenPort:   options.ListenPort,
@@ -291,6 +331,8 @@ func NewConn(options *Options) (conn *Conn, err error) {
 		cfgMaps.setDERPMap(options.DERPMap)
 	}
 	cfgMaps.setBlockEndpoints(options.BlockEndpoints)
+	cfgMaps.setKeepaliveInterval(options.KeepaliveInterval)
+	cfgMaps.setHandshakeTimeout(options.HandshakeTimeout)
 
 	nodeUp := newNodeUpdater(
 		options.Logger,
@@ -331,6 +373,9 @@ func NewConn(options *Options) (conn *Conn, err error) {
 			_ = server.Close()
 		}
 	}()
+	if options.HTTPSFallback {
+		server.httpsFallback = newHTTPSFallback(options.Logger, server, options.BlockEndpoints, options.DERPForceWebSockets)
+	}
 	if server.telemetryStore != nil {
 		server.wireguardEngine.SetNetInfoCallback(func(ni *tailcfg.NetInfo) {
 			server.mutex.Lock()
@@ -338,6 +383,9 @@ func NewConn(options *Options) (conn *Conn, err error) {
 			server.mutex.Unlock()
 			server.telemetryStore.setNetInfo(ni)
 			nodeUp.setNetInfo(ni)
+			if server.httpsFallback != nil {
+				server.httpsFallback.setNetInfo(ni)
+			}
 			server.telemetryStore.pingPeer(server)
 		})
 		server.wireguardEngine.AddNetworkMapCallback(func(nm *netmap.NetworkMap) {
@@ -351,8 +399,14 @@ func NewConn(options *Options) (conn *Conn, err error) {
 			server.lastNetInfo = ni.Clone()
 			server.mutex.Unlock()
 			nodeUp.setNetInfo(ni)
+			if server.httpsFallback != nil {
+				server.httpsFallback.setNetInfo(ni)
+			}
 		})
 	}
+	if options.NetcheckInterval > 0 {
+		go server.watchNetcheck(options.NetcheckInterval)
+	}
 	server.wireguardEngine.SetStatusCallback(nodeUp.setStatus)
 	server.magicConn.SetDERPForcedWebsocketCallback(nodeUp.setDERPForcedWebsocket)
 
@@ -453,6 +507,8 @@ type Conn struct {
 
 	trafficStats *connstats.Statistics
 	lastNetInfo  *tailcfg.NetInfo
+	// httpsFallback is nil if the HTTPS-only fallback is disabled.
+	httpsFallback *httpsFallback
 }
 
 func (c *Conn) GetNetInfo() *tailcfg.NetInfo {
@@ -465,6 +521,12 @@ func (c *Conn) SetTunnelDestination(id uuid.UUID) {
 	c.configMaps.setTunnelDestination(id)
 }
 
+// UsingHTTPSFallback returns whether all traffic is currently relayed over
+// HTTPS because UDP is blocked.
+func (c *Conn) UsingHTTPSFallback() bool {
+	return c.httpsFallback != nil && c.httpsFallback.isActive()
+}
+
 func (c *Conn) GetBlockEndpoints() bool {
 	return c.configMaps.getBlockEndpoints() && c.nodeUpdater.getBlockEndpoints()
 }
@@ -919,6 +981,21 @@ func (c *Conn) watchConnChange() {
 	}
 }
 
+// watchNetcheck periodically asks magicsock to re-run STUN so that endpoints
+// are rediscovered at the configured interval.
+func (c *Conn) watchNetcheck(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.watchCtx.Done():
+			return
+		case <-ticker.C:
+		}
+		c.magicConn.ReSTUN("netcheck-interval")
+	}
+}
+
 // PeerDiagnostics is a checklist of human-readable conditions necessary to establish an encrypted
 // tunnel to a peer via a Conn
 type PeerDiagnostics struct {
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"strings"
 	"sync"
@@ -18,6 +19,13 @@ import (
 // passes them to the DERP server.
 // Taken from: https://github.com/tailscale/tailscale/blob/e3211ff88ba85435f70984cf67d9b353f3d650d8/cmd/derper/websocket.go#L21
 func WithWebsocketSupport(s *derp.Server, base http.Handler) (http.Handler, func()) {
+	return withWebsocketSupport(s, base, nil)
+}
+
+// withWebsocketSupport is WithWebsocketSupport with an optional wrapConn
+// function that is applied to every accepted WebSocket connection before it
+// is handed to the DERP server.
+func withWebsocketSupport(s *derp.Server, base http.Handler, wrapConn func(conn net.Conn, remoteAddr string) net.Conn) (http.Handler, func()) {
 	var mu sync.Mutex
 	var waitGroup sync.WaitGroup
 	ctx, cancelFunc := context.WithCancel(context.Background())
@@ -60,7 +68,10 @@ func WithWebsocketSupport(s *derp.Server, base http.Handler) (http.Handler, func
 				c.Close(websocket.StatusPolicyViolation, "client must speak the derp subprotocol")
 				return
 			}
-			wc := websocket.NetConn(ctx, c, websocket.MessageBinary)
+			var wc net.Conn = websocket.NetConn(ctx, c, websocket.MessageBinary)
+			if wrapConn != nil {
+				wc = wrapConn(wc, r.RemoteAddr)
+			}
 			brw := bufio.NewReadWriter(bufio.NewReader(wc), bufio.NewWriter(wc))
 			s.Accept(ctx, wc, brw, r.RemoteAddr)
 		}), func() {
@@ -9,11 +9,15 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/xerrors"
 	"tailscale.com/ipn/ipnstate"
 	"tailscale.com/tailcfg"
+
+	"cdr.dev/slog"
+	"github.com/coder/quartz"
 )
 
 const DisableSTUN = "disable"
@@ -53,6 +57,49 @@ func STUNRegions(baseRegionID int, stunAddrs []string) ([]*tailcfg.DERPRegion, e
 	return regions, nil
 }
 
+// AddRegionSTUNNodes adds STUN-only nodes to existing regions in the DERP map.
+// Each entry is in the form "<region ID>=<host:port>". The nodes are placed
+// ahead of the region's DERP nodes so netcheck probes them first, which lets
+// deployments that cannot reach the region's own STUN ports (e.g. air-gapped
+// networks) use an internal STUN server per region instead.
+func AddRegionSTUNNodes(derpMap *tailcfg.DERPMap, entries []string) error {
+	added := map[int][]*tailcfg.DERPNode{}
+	for _, entry := range entries {
+		rawRegionID, stunAddr, ok := strings.Cut(entry, "=")
+		if !ok {
+			return xerrors.Errorf("invalid region STUN address %q, expected <region ID>=<host:port>", entry)
+		}
+		regionID, err := strconv.Atoi(strings.TrimSpace(rawRegionID))
+		if err != nil {
+			return xerrors.Errorf("parse region ID for %q: %w", entry, err)
+		}
+		region, ok := derpMap.Regions[regionID]
+		if !ok || region == nil {
+			return xerrors.Errorf("region %d for STUN address %q does not exist in the DERP map", regionID, stunAddr)
+		}
+		host, rawPort, err := net.SplitHostPort(strings.TrimSpace(stunAddr))
+		if err != nil {
+			return xerrors.Errorf("split host port for %q: %w", stunAddr, err)
+		}
+		port, err := strconv.Atoi(rawPort)
+		if err != nil {
+			return xerrors.Errorf("parse port for %q: %w", stunAddr, err)
+		}
+		added[regionID] = append(added[regionID], &tailcfg.DERPNode{
+			Name:     fmt.Sprintf("%dstun%d", regionID, len(added[regionID])),
+			RegionID: regionID,
+			HostName: host,
+			STUNOnly: true,
+			STUNPort: port,
+		})
+	}
+	for regionID, nodes := range added {
+		region := derpMap.Regions[regionID]
+		region.Nodes = append(nodes, region.Nodes...)
+	}
+	return nil
+}
+
 // NewDERPMap constructs a DERPMap from a set of STUN addresses and optionally a remote
 // URL to fetch a mapping from e.g. https://controlplane.tailscale.com/derpmap/default.
 //
@@ -155,6 +202,69 @@ regionLoop:
 	return derpMap, nil
 }
 
+// DERPMapPoller periodically rebuilds a DERP map, e.g. by re-fetching it from
+// a remote URL, and stores the result if it differs from the current map.
+// Consumers read the latest map with DERPMap, and changes are propagated to
+// agents and clients by the coordinator's regular DERP map updates.
+type DERPMapPoller struct {
+	logger  slog.Logger
+	fetch   func(ctx context.Context) (*tailcfg.DERPMap, error)
+	current atomic.Pointer[tailcfg.DERPMap]
+
+	cancel context.CancelFunc
+	waiter quartz.Waiter
+}
+
+// NewDERPMapPoller starts polling fetch on the given interval. The initial map
+// is served until the first successful fetch that yields a different map.
+// Failed fetches are logged and the previous map is kept.
+func NewDERPMapPoller(ctx context.Context, logger slog.Logger, clock quartz.Clock, initial *tailcfg.DERPMap, interval time.Duration, fetch func(ctx context.Context) (*tailcfg.DERPMap, error)) *DERPMapPoller {
+	ctx, cancel := context.WithCancel(ctx)
+	p := &DERPMapPoller{
+		logger: logger,
+		fetch:  fetch,
+		cancel: cancel,
+	}
+	p.current.Store(initial)
+	p.waiter = clock.TickerFunc(ctx, interval, func() error {
+		p.poll(ctx)
+		return nil
+	}, "derpmap", "poll")
+	return p
+}
+
+func (p *DERPMapPoller) poll(ctx context.Context) {
+	derpMap, err := p.fetch(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			p.logger.Warn(ctx, "failed to refresh DERP map, keeping previous map", slog.Error(err))
+		}
+		return
+	}
+	if CompareDERPMaps(p.current.Load(), derpMap) {
+		return
+	}
+	p.logger.Info(ctx, "DERP map changed, pushing updated map to peers",
+		slog.F("region_count", len(derpMap.Regions)),
+	)
+	p.current.Store(derpMap)
+}
+
+// DERPMap returns the most recently fetched DERP map.
+func (p *DERPMapPoller) DERPMap() *tailcfg.DERPMap {
+	return p.current.Load()
+}
+
+// Close stops polling and waits for any in-flight fetch to complete.
+func (p *DERPMapPoller) Close() error {
+	p.cancel()
+	err := p.waiter.Wait()
+	if xerrors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
 func ExtractPreferredDERPName(pingResult *ipnstate.PingResult, node *Node, derpMap *tailcfg.DERPMap) string {
 	// Sometimes the preferred DERP doesn't match the one we're actually
 	// connected with. Perhaps because the agent prefers a different DERP and
@@ -7,13 +7,19 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
 	"tailscale.com/ipn/ipnstate"
 	"tailscale.com/tailcfg"
 
+	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
 )
 
 func TestNewDERPMap(t *testing.T) {
@@ -164,6 +170,109 @@ func TestNewDERPMap(t *testing.T) {
 	})
 }
 
+func TestAddRegionSTUNNodes(t *testing.T) {
+	t.Parallel()
+	newDERPMap := func() *tailcfg.DERPMap {
+		return &tailcfg.DERPMap{
+			Regions: map[int]*tailcfg.DERPRegion{
+				1: {
+					RegionID: 1,
+					Nodes:    []*tailcfg.DERPNode{{Name: "1a", RegionID: 1, HostName: "derp1.example.com"}},
+				},
+				2: {
+					RegionID: 2,
+					Nodes:    []*tailcfg.DERPNode{{Name: "2a", RegionID: 2, HostName: "derp2.example.com"}},
+				},
+			},
+		}
+	}
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		derpMap := newDERPMap()
+		err := tailnet.AddRegionSTUNNodes(derpMap, []string{
+			"1=stun1.internal:3478",
+			"1=stun2.internal:3479",
+		})
+		require.NoError(t, err)
+		nodes := derpMap.Regions[1].Nodes
+		require.Len(t, nodes, 3)
+		require.Equal(t, &tailcfg.DERPNode{
+			Name:     "1stun0",
+			RegionID: 1,
+			HostName: "stun1.internal",
+			STUNOnly: true,
+			STUNPort: 3478,
+		}, nodes[0])
+		require.Equal(t, "stun2.internal", nodes[1].HostName)
+		require.Equal(t, 3479, nodes[1].STUNPort)
+		require.Equal(t, "1a", nodes[2].Name)
+		require.Len(t, derpMap.Regions[2].Nodes, 1)
+	})
+	t.Run("UnknownRegion", func(t *testing.T) {
+		t.Parallel()
+		err := tailnet.AddRegionSTUNNodes(newDERPMap(), []string{"3=stun.internal:3478"})
+		require.ErrorContains(t, err, "does not exist")
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, entry := range []string{"stun.internal:3478", "a=stun.internal:3478", "1=stun.internal", "1=stun.internal:port"} {
+			require.Error(t, tailnet.AddRegionSTUNNodes(newDERPMap(), []string{entry}), entry)
+		}
+	})
+}
+
+func TestDERPMapPoller(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	mClock := quartz.NewMock(t)
+
+	makeMap := func(regionIDs ...int) *tailcfg.DERPMap {
+		derpMap := &tailcfg.DERPMap{Regions: map[int]*tailcfg.DERPRegion{}}
+		for _, id := range regionIDs {
+			derpMap.Regions[id] = &tailcfg.DERPRegion{
+				RegionID: id,
+				Nodes:    []*tailcfg.DERPNode{{Name: "node", RegionID: id}},
+			}
+		}
+		return derpMap
+	}
+
+	var (
+		next    atomic.Pointer[tailcfg.DERPMap]
+		failing atomic.Bool
+	)
+	initial := makeMap(1)
+	next.Store(initial)
+	poller := tailnet.NewDERPMapPoller(ctx, logger, mClock, initial, time.Minute, func(context.Context) (*tailcfg.DERPMap, error) {
+		if failing.Load() {
+			return nil, xerrors.New("remote unavailable")
+		}
+		return next.Load(), nil
+	})
+	defer poller.Close()
+	require.Same(t, initial, poller.DERPMap())
+
+	// An equivalent map does not replace the current one.
+	next.Store(makeMap(1))
+	mClock.Advance(time.Minute).MustWait(ctx)
+	require.Same(t, initial, poller.DERPMap())
+
+	// A changed map is picked up.
+	updated := makeMap(1, 2)
+	next.Store(updated)
+	mClock.Advance(time.Minute).MustWait(ctx)
+	require.Same(t, updated, poller.DERPMap())
+
+	// Failed fetches keep the previous map.
+	failing.Store(true)
+	mClock.Advance(time.Minute).MustWait(ctx)
+	require.Same(t, updated, poller.DERPMap())
+
+	require.NoError(t, poller.Close())
+}
+
 func TestExtractDERPLatency(t *testing.T) {
 	t.Parallel()
 
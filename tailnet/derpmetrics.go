@@ -0,0 +1,334 @@
+package tailnet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"expvar"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+	"tailscale.com/derp"
+	"tailscale.com/derp/derphttp"
+	"tailscale.com/metrics"
+	"tailscale.com/types/key"
+
+	"github.com/coder/quartz"
+)
+
+var (
+	derpActiveSessionsDesc = prometheus.NewDesc(
+		"coder_derp_server_active_sessions",
+		"The current number of clients connected to the embedded DERP server.",
+		nil, nil,
+	)
+	derpReceivedBytesDesc = prometheus.NewDesc(
+		"coder_derp_server_received_bytes_total",
+		"The total number of bytes received by the embedded DERP server.",
+		nil, nil,
+	)
+	derpSentBytesDesc = prometheus.NewDesc(
+		"coder_derp_server_sent_bytes_total",
+		"The total number of bytes sent by the embedded DERP server.",
+		nil, nil,
+	)
+	derpPacketsDroppedDesc = prometheus.NewDesc(
+		"coder_derp_server_packets_dropped_total",
+		"The total number of packets dropped by the embedded DERP server, by reason.",
+		[]string{"reason"}, nil,
+	)
+	derpSessionReceivedBytesDesc = prometheus.NewDesc(
+		"coder_derp_server_session_received_bytes_total",
+		"The number of bytes received from a currently connected DERP client.",
+		[]string{"node_key"}, nil,
+	)
+	derpSessionSentBytesDesc = prometheus.NewDesc(
+		"coder_derp_server_session_sent_bytes_total",
+		"The number of bytes sent to a currently connected DERP client.",
+		[]string{"node_key"}, nil,
+	)
+)
+
+// DERPSession describes a client connected to an embedded DERP server.
+type DERPSession struct {
+	ID            uuid.UUID
+	NodeKey       key.NodePublic
+	RemoteAddr    string
+	WebSocket     bool
+	ConnectedAt   time.Time
+	BytesReceived int64
+	BytesSent     int64
+}
+
+// DERPServerMetrics tracks per-client traffic on an embedded DERP server and
+// exports it, along with the server's own counters, as Prometheus metrics.
+// Per-client metrics are only emitted for currently connected clients so that
+// series do not accumulate as clients come and go.
+type DERPServerMetrics struct {
+	server    *derp.Server
+	clock     quartz.Clock
+	bytesRecv expvar.Var
+	bytesSent expvar.Var
+	dropped   *metrics.LabelMap
+
+	mu       sync.Mutex
+	sessions map[uuid.UUID]*derpSessionConn
+}
+
+var _ prometheus.Collector = &DERPServerMetrics{}
+
+func NewDERPServerMetrics(server *derp.Server, clock quartz.Clock) *DERPServerMetrics {
+	m := &DERPServerMetrics{
+		server:   server,
+		clock:    clock,
+		sessions: make(map[uuid.UUID]*derpSessionConn),
+	}
+	if vars, ok := server.ExpVar().(*metrics.Set); ok {
+		m.bytesRecv = vars.Get("bytes_received")
+		m.bytesSent = vars.Get("bytes_sent")
+		m.dropped, _ = vars.Get("counter_packets_dropped_reason").(*metrics.LabelMap)
+	}
+	return m
+}
+
+// Handler returns an http.Handler that serves DERP, including over
+// WebSockets, and records traffic for every connected client. It replaces
+// wrapping derphttp.Handler with WithWebsocketSupport.
+func (m *DERPServerMetrics) Handler() (http.Handler, func()) {
+	base := derphttp.Handler(m.server)
+	return withWebsocketSupport(m.server, http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if hijacker, ok := rw.(http.Hijacker); ok {
+			rw = &derpHijackTracker{ResponseWriter: rw, hijacker: hijacker, metrics: m}
+		}
+		base.ServeHTTP(rw, r)
+	}), func(conn net.Conn, remoteAddr string) net.Conn {
+		return m.track(conn, remoteAddr, true)
+	})
+}
+
+// Sessions returns a snapshot of the clients currently connected to the DERP
+// server, ordered by connection time.
+func (m *DERPServerMetrics) Sessions() []DERPSession {
+	m.mu.Lock()
+	sessions := make([]DERPSession, 0, len(m.sessions))
+	for _, conn := range m.sessions {
+		sessions = append(sessions, conn.snapshot())
+	}
+	m.mu.Unlock()
+
+	slices.SortFunc(sessions, func(a, b DERPSession) int {
+		return a.ConnectedAt.Compare(b.ConnectedAt)
+	})
+	return sessions
+}
+
+func (*DERPServerMetrics) Describe(descs chan<- *prometheus.Desc) {
+	descs <- derpActiveSessionsDesc
+	descs <- derpReceivedBytesDesc
+	descs <- derpSentBytesDesc
+	descs <- derpPacketsDroppedDesc
+	descs <- derpSessionReceivedBytesDesc
+	descs <- derpSessionSentBytesDesc
+}
+
+func (m *DERPServerMetrics) Collect(ch chan<- prometheus.Metric) {
+	if v, ok := m.bytesRecv.(*expvar.Int); ok {
+		ch <- prometheus.MustNewConstMetric(derpReceivedBytesDesc, prometheus.CounterValue, float64(v.Value()))
+	}
+	if v, ok := m.bytesSent.(*expvar.Int); ok {
+		ch <- prometheus.MustNewConstMetric(derpSentBytesDesc, prometheus.CounterValue, float64(v.Value()))
+	}
+	if m.dropped != nil {
+		m.dropped.Do(func(kv expvar.KeyValue) {
+			if v, ok := kv.Value.(*expvar.Int); ok {
+				ch <- prometheus.MustNewConstMetric(derpPacketsDroppedDesc, prometheus.CounterValue, float64(v.Value()), kv.Key)
+			}
+		})
+	}
+
+	sessions := m.Sessions()
+	ch <- prometheus.MustNewConstMetric(derpActiveSessionsDesc, prometheus.GaugeValue, float64(len(sessions)))
+	// A client may hold more than one connection, e.g. while reconnecting, so
+	// sum traffic by node key to avoid emitting duplicate series.
+	type traffic struct{ recv, sent int64 }
+	byKey := make(map[string]*traffic)
+	for _, session := range sessions {
+		if session.NodeKey.IsZero() {
+			// The client has not identified itself yet.
+			continue
+		}
+		nodeKey := session.NodeKey.ShortString()
+		t, ok := byKey[nodeKey]
+		if !ok {
+			t = &traffic{}
+			byKey[nodeKey] = t
+		}
+		t.recv += session.BytesReceived
+		t.sent += session.BytesSent
+	}
+	for nodeKey, t := range byKey {
+		ch <- prometheus.MustNewConstMetric(derpSessionReceivedBytesDesc, prometheus.CounterValue, float64(t.recv), nodeKey)
+		ch <- prometheus.MustNewConstMetric(derpSessionSentBytesDesc, prometheus.CounterValue, float64(t.sent), nodeKey)
+	}
+}
+
+func (m *DERPServerMetrics) track(conn net.Conn, remoteAddr string, websocket bool) *derpSessionConn {
+	session := &derpSessionConn{
+		Conn:        conn,
+		id:          uuid.New(),
+		remoteAddr:  remoteAddr,
+		websocket:   websocket,
+		connectedAt: m.clock.Now(),
+		metrics:     m,
+	}
+	m.mu.Lock()
+	m.sessions[session.id] = session
+	m.mu.Unlock()
+	return session
+}
+
+func (m *DERPServerMetrics) untrack(id uuid.UUID) {
+	m.mu.Lock()
+	delete(m.sessions, id)
+	m.mu.Unlock()
+}
+
+// derpHijackTracker wraps the connection hijacked by derphttp.Handler so that
+// traffic on plain (non-WebSocket) DERP connections is recorded.
+type derpHijackTracker struct {
+	http.ResponseWriter
+	hijacker http.Hijacker
+	metrics  *DERPServerMetrics
+}
+
+func (t *derpHijackTracker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := t.hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	// Reads must go through the original buffered reader since it may already
+	// hold bytes sent by the client.
+	session := t.metrics.track(&bufferedConn{Conn: conn, reader: brw.Reader}, conn.RemoteAddr().String(), false)
+	return session, bufio.NewReadWriter(bufio.NewReader(session), bufio.NewWriter(session)), nil
+}
+
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// The first frame sent by a DERP client is its ClientInfo frame, which starts
+// with the client's public node key in plaintext.
+const (
+	derpFrameHeaderLen   = 1 + 4
+	derpFrameClientInfo  = 0x02
+	derpClientKeyHeadLen = derpFrameHeaderLen + 32
+)
+
+type derpSessionConn struct {
+	net.Conn
+	id          uuid.UUID
+	remoteAddr  string
+	websocket   bool
+	connectedAt time.Time
+	metrics     *DERPServerMetrics
+
+	bytesReceived atomic.Int64
+	bytesSent     atomic.Int64
+	closeOnce     sync.Once
+
+	// head buffers the start of the client stream until the node key can be
+	// parsed. It's only accessed from Read, which the DERP server does not call
+	// concurrently.
+	head    []byte
+	nodeKey atomic.Pointer[key.NodePublic]
+}
+
+func (c *derpSessionConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		c.bytesReceived.Add(int64(n))
+		c.sniffNodeKey(p[:n])
+	}
+	return n, err
+}
+
+func (c *derpSessionConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.bytesSent.Add(int64(n))
+	return n, err
+}
+
+func (c *derpSessionConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.metrics.untrack(c.id)
+	})
+	return c.Conn.Close()
+}
+
+func (c *derpSessionConn) sniffNodeKey(p []byte) {
+	if c.head == nil && c.nodeKey.Load() != nil {
+		return
+	}
+	if len(c.head) >= derpClientKeyHeadLen {
+		return
+	}
+	c.head = append(c.head, p[:min(len(p), derpClientKeyHeadLen-len(c.head))]...)
+	if len(c.head) < derpClientKeyHeadLen {
+		return
+	}
+	nodeKey, err := parseDERPClientKey(c.head)
+	c.head = nil
+	if err != nil {
+		// Leave the session unidentified, the DERP server will reject the
+		// client anyways.
+		zero := key.NodePublic{}
+		c.nodeKey.Store(&zero)
+		return
+	}
+	c.nodeKey.Store(&nodeKey)
+}
+
+func parseDERPClientKey(head []byte) (key.NodePublic, error) {
+	if len(head) < derpClientKeyHeadLen {
+		return key.NodePublic{}, xerrors.New("short client info frame")
+	}
+	if head[0] != derpFrameClientInfo {
+		return key.NodePublic{}, xerrors.Errorf("unexpected frame type %#x", head[0])
+	}
+	if binary.BigEndian.Uint32(head[1:derpFrameHeaderLen]) < 32 {
+		return key.NodePublic{}, xerrors.New("client info frame too short for key")
+	}
+	var nodeKey key.NodePublic
+	err := nodeKey.ReadRawWithoutAllocating(bufio.NewReader(bytes.NewReader(head[derpFrameHeaderLen:derpClientKeyHeadLen])))
+	if err != nil {
+		return key.NodePublic{}, xerrors.Errorf("read node key: %w", err)
+	}
+	return nodeKey, nil
+}
+
+func (c *derpSessionConn) snapshot() DERPSession {
+	session := DERPSession{
+		ID:            c.id,
+		RemoteAddr:    c.remoteAddr,
+		WebSocket:     c.websocket,
+		ConnectedAt:   c.connectedAt,
+		BytesReceived: c.bytesReceived.Load(),
+		BytesSent:     c.bytesSent.Load(),
+	}
+	if nodeKey := c.nodeKey.Load(); nodeKey != nil {
+		session.NodeKey = *nodeKey
+	}
+	return session
+}
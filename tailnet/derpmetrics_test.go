@@ -0,0 +1,120 @@
+package tailnet_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tailscale.com/derp"
+	"tailscale.com/derp/derphttp"
+	"tailscale.com/types/key"
+
+	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestDERPServerMetrics(t *testing.T) {
+	t.Parallel()
+
+	for _, websockets := range []bool{false, true} {
+		name := "DERP"
+		if websockets {
+			name = "WebSockets"
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testutil.Context(t, testutil.WaitShort)
+			logger := testutil.Logger(t)
+			derpServer := derp.NewServer(key.NewNode(), tailnet.Logger(logger.Named("derp")))
+			t.Cleanup(func() { _ = derpServer.Close() })
+
+			derpMetrics := tailnet.NewDERPServerMetrics(derpServer, quartz.NewReal())
+			handler, closeFunc := derpMetrics.Handler()
+			srv := httptest.NewServer(handler)
+			t.Cleanup(func() {
+				srv.CloseClientConnections()
+				srv.Close()
+				closeFunc()
+			})
+
+			newClient := func(nodeKey key.NodePrivate) *derphttp.Client {
+				client, err := derphttp.NewClient(nodeKey, srv.URL, tailnet.Logger(logger.Named("client")))
+				require.NoError(t, err)
+				client.ForceWebsockets = websockets
+				t.Cleanup(func() { _ = client.Close() })
+				require.NoError(t, client.Connect(ctx))
+				return client
+			}
+			sender, receiver := key.NewNode(), key.NewNode()
+			senderClient := newClient(sender)
+			receiverClient := newClient(receiver)
+
+			payload := []byte("hello world")
+			received := make(chan []byte, 1)
+			go func() {
+				for {
+					msg, err := receiverClient.Recv()
+					if err != nil {
+						return
+					}
+					if pkt, ok := msg.(derp.ReceivedPacket); ok {
+						received <- pkt.Data
+						return
+					}
+				}
+			}()
+			// The receiver may not be registered with the server yet, so keep
+			// sending until the packet arrives.
+			var got []byte
+			require.Eventually(t, func() bool {
+				require.NoError(t, senderClient.Send(receiver.Public(), payload))
+				select {
+				case got = <-received:
+					return true
+				default:
+					return false
+				}
+			}, testutil.WaitShort, testutil.IntervalFast)
+			require.Equal(t, payload, got)
+
+			sessions := derpMetrics.Sessions()
+			require.Len(t, sessions, 2)
+			keys := map[key.NodePublic]tailnet.DERPSession{}
+			for _, session := range sessions {
+				assert.Equal(t, websockets, session.WebSocket)
+				assert.Positive(t, session.BytesReceived)
+				assert.Positive(t, session.BytesSent)
+				keys[session.NodeKey] = session
+			}
+			require.Contains(t, keys, sender.Public())
+			require.Contains(t, keys, receiver.Public())
+			// The sender sent the payload, so it must have received at least as
+			// many bytes as the payload on the sender's session.
+			assert.GreaterOrEqual(t, keys[sender.Public()].BytesReceived, int64(len(payload)))
+
+			registry := prometheus.NewRegistry()
+			require.NoError(t, registry.Register(derpMetrics))
+			families, err := registry.Gather()
+			require.NoError(t, err)
+			counts := map[string]int{}
+			for _, family := range families {
+				counts[family.GetName()] = len(family.GetMetric())
+			}
+			assert.Equal(t, 1, counts["coder_derp_server_active_sessions"])
+			assert.Equal(t, 1, counts["coder_derp_server_received_bytes_total"])
+			assert.Equal(t, 1, counts["coder_derp_server_sent_bytes_total"])
+			assert.Equal(t, 2, counts["coder_derp_server_session_received_bytes_total"])
+			assert.Equal(t, 2, counts["coder_derp_server_session_sent_bytes_total"])
+
+			// Sessions are removed once the client disconnects.
+			require.NoError(t, senderClient.Close())
+			require.Eventually(t, func() bool {
+				return len(derpMetrics.Sessions()) == 1
+			}, testutil.WaitShort, testutil.IntervalFast)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package tailnet
+
+import (
+	"context"
+	"sync"
+
+	"tailscale.com/tailcfg"
+
+	"cdr.dev/slog"
+)
+
+// httpsFallbackConn is the subset of Conn used by httpsFallback.
+type httpsFallbackConn interface {
+	SetBlockEndpoints(blockEndpoints bool)
+	SetDERPForceWebSockets(v bool)
+}
+
+// httpsFallback switches a connection to an HTTPS-only transport when netcheck
+// finds that UDP is blocked but DERP servers can be reached over HTTPS. While
+// the fallback is active, P2P endpoints are blocked and DERP is always spoken
+// over WebSockets, so all traffic is relayed over HTTPS connections. The
+// connection's own settings are restored once UDP works again.
+type httpsFallback struct {
+	logger slog.Logger
+	conn   httpsFallbackConn
+	// blockEndpoints and forceWebSockets are the settings of the connection
+	// when the fallback isn't active.
+	blockEndpoints  bool
+	forceWebSockets bool
+
+	mu     sync.Mutex
+	active bool
+}
+
+func newHTTPSFallback(logger slog.Logger, conn httpsFallbackConn, blockEndpoints, forceWebSockets bool) *httpsFallback {
+	return &httpsFallback{
+		logger:          logger,
+		conn:            conn,
+		blockEndpoints:  blockEndpoints,
+		forceWebSockets: forceWebSockets,
+	}
+}
+
+func (f *httpsFallback) setNetInfo(ni *tailcfg.NetInfo) {
+	if ni == nil {
+		return
+	}
+	udp, ok := ni.WorkingUDP.Get()
+	if !ok {
+		// netcheck didn't get far enough to tell whether UDP works.
+		return
+	}
+	httpsOnly := !udp && ni.PreferredDERP != 0
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if httpsOnly == f.active {
+		return
+	}
+	f.active = httpsOnly
+	if httpsOnly {
+		f.logger.Info(context.Background(), "UDP is blocked, relaying all traffic over HTTPS",
+			slog.F("preferred_derp", ni.PreferredDERP))
+		f.conn.SetBlockEndpoints(true)
+		f.conn.SetDERPForceWebSockets(true)
+		return
+	}
+	f.logger.Info(context.Background(), "UDP is available, no longer relaying all traffic over HTTPS")
+	f.conn.SetBlockEndpoints(f.blockEndpoints)
+	f.conn.SetDERPForceWebSockets(f.forceWebSockets)
+}
+
+func (f *httpsFallback) isActive() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active
+}
@@ -0,0 +1,72 @@
+package tailnet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"tailscale.com/tailcfg"
+
+	"github.com/coder/coder/v2/testutil"
+)
+
+type fakeHTTPSFallbackConn struct {
+	blockEndpoints  bool
+	forceWebSockets bool
+}
+
+func (c *fakeHTTPSFallbackConn) SetBlockEndpoints(blockEndpoints bool) {
+	c.blockEndpoints = blockEndpoints
+}
+
+func (c *fakeHTTPSFallbackConn) SetDERPForceWebSockets(v bool) {
+	c.forceWebSockets = v
+}
+
+func TestHTTPSFallback(t *testing.T) {
+	t.Parallel()
+
+	conn := &fakeHTTPSFallbackConn{}
+	uut := newHTTPSFallback(testutil.Logger(t), conn, false, false)
+
+	// UDP works.
+	uut.setNetInfo(&tailcfg.NetInfo{WorkingUDP: "true", PreferredDERP: 1})
+	require.False(t, uut.isActive())
+	require.False(t, conn.blockEndpoints)
+
+	// netcheck hasn't finished.
+	uut.setNetInfo(&tailcfg.NetInfo{})
+	require.False(t, uut.isActive())
+
+	// UDP is blocked and no DERP server could be reached either.
+	uut.setNetInfo(&tailcfg.NetInfo{WorkingUDP: "false"})
+	require.False(t, uut.isActive())
+
+	// UDP is blocked, but DERP servers can be reached over HTTPS.
+	uut.setNetInfo(&tailcfg.NetInfo{WorkingUDP: "false", PreferredDERP: 1})
+	require.True(t, uut.isActive())
+	require.True(t, conn.blockEndpoints)
+	require.True(t, conn.forceWebSockets)
+
+	// An incomplete report keeps the fallback active.
+	uut.setNetInfo(&tailcfg.NetInfo{})
+	require.True(t, uut.isActive())
+
+	// UDP works again.
+	uut.setNetInfo(&tailcfg.NetInfo{WorkingUDP: "true", PreferredDERP: 1})
+	require.False(t, uut.isActive())
+	require.False(t, conn.blockEndpoints)
+	require.False(t, conn.forceWebSockets)
+}
+
+func TestHTTPSFallback_RestoresSettings(t *testing.T) {
+	t.Parallel()
+
+	conn := &fakeHTTPSFallbackConn{blockEndpoints: true}
+	uut := newHTTPSFallback(testutil.Logger(t), conn, true, false)
+
+	uut.setNetInfo(&tailcfg.NetInfo{WorkingUDP: "false", PreferredDERP: 1})
+	require.True(t, conn.forceWebSockets)
+	uut.setNetInfo(&tailcfg.NetInfo{WorkingUDP: "true", PreferredDERP: 1})
+	require.True(t, conn.blockEndpoints)
+	require.False(t, conn.forceWebSockets)
+}
@@ -56,9 +56,16 @@ import (
 //   - Added support for DeleteSubAgent RPC on the Agent API.
 //   - Added support for ListSubAgents RPC on the Agent API.
 //   - Add ORGANIZATION SharingLevel
+//
+// API v2.7:
+//   - Added `NetcheckInterval` to the agent manifest. Older servers leave it
+//     unset, in which case agents keep the default netcheck schedule.
+//   - Added `WireguardMtu`, `WireguardKeepaliveInterval` and
+//     `WireguardHandshakeTimeout` to the agent manifest. Older servers leave
+//     them unset, in which case agents keep the WireGuard defaults.
 const (
 	CurrentMajor = 2
-	CurrentMinor = 6
+	CurrentMinor = 7
 )
 
 var CurrentVersion = apiversion.New(CurrentMajor, CurrentMinor)
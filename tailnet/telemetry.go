@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"net/netip"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	"tailscale.com/tailcfg"
+	tslogger "tailscale.com/types/logger"
 	"tailscale.com/types/netmap"
 
 	"github.com/coder/coder/v2/buildinfo"
@@ -25,6 +27,10 @@ const (
 	TelemetryApplicationVSCode    string = "vscode"
 )
 
+// wireguardHandshakeRetryLog is logged by wireguard-go every time it retries a
+// handshake that did not complete.
+const wireguardHandshakeRetryLog = "Handshake did not complete after"
+
 // Responsible for storing and anonymizing networking telemetry state.
 type TelemetryStore struct {
 	mu       sync.Mutex
@@ -46,6 +52,9 @@ type TelemetryStore struct {
 
 	p2pSetupTime time.Duration
 	lastDerpTime time.Time
+	// handshakeRetries counts the WireGuard handshakes that did not complete
+	// in time and were retried.
+	handshakeRetries uint32
 }
 
 func newTelemetryStore() (*TelemetryStore, error) {
@@ -65,14 +74,15 @@ func (b *TelemetryStore) newEvent() *proto.TelemetryEvent {
 	defer b.mu.Unlock()
 
 	out := &proto.TelemetryEvent{
-		Time:           timestamppb.Now(),
-		ClientVersion:  buildinfo.Version(),
-		DerpMap:        DERPMapToProto(b.cleanDerpMap),
-		LatestNetcheck: b.cleanNetCheck,
-		NodeIdSelf:     b.nodeIDSelf,
-		NodeIdRemote:   b.nodeIDRemote,
-		HomeDerp:       b.homeDerp,
-		Application:    b.application,
+		Time:             timestamppb.Now(),
+		ClientVersion:    buildinfo.Version(),
+		DerpMap:          DERPMapToProto(b.cleanDerpMap),
+		LatestNetcheck:   b.cleanNetCheck,
+		NodeIdSelf:       b.nodeIDSelf,
+		NodeIdRemote:     b.nodeIDRemote,
+		HomeDerp:         b.homeDerp,
+		Application:      b.application,
+		HandshakeRetries: b.handshakeRetries,
 	}
 	if b.p2pSetupTime > 0 {
 		out.P2PSetup = durationpb.New(b.p2pSetupTime)
@@ -89,6 +99,19 @@ func (b *TelemetryStore) markConnected(ip *netip.Addr, application string) {
 	b.application = application
 }
 
+// wireguardLogf wraps the logger of the WireGuard engine to count handshake
+// retries, which wireguard-go only reports through its logs.
+func (b *TelemetryStore) wireguardLogf(logf tslogger.Logf) tslogger.Logf {
+	return func(format string, args ...any) {
+		if strings.Contains(format, wireguardHandshakeRetryLog) {
+			b.mu.Lock()
+			b.handshakeRetries++
+			b.mu.Unlock()
+		}
+		logf(format, args...)
+	}
+}
+
 func (b *TelemetryStore) pingPeer(conn *Conn) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -78,6 +78,25 @@ func TestTelemetryStore(t *testing.T) {
 		require.Equal(t, nm.SelfNode.DERP, fmt.Sprintf("127.3.3.40:%d", e.HomeDerp))
 	})
 
+	t.Run("HandshakeRetries", func(t *testing.T) {
+		t.Parallel()
+
+		telemetry, err := newTelemetryStore()
+		require.NoError(t, err)
+
+		var logged []string
+		logf := telemetry.wireguardLogf(func(format string, args ...any) {
+			logged = append(logged, fmt.Sprintf(format, args...))
+		})
+		logf("wg: [v2] %s - Handshake did not complete after %d seconds, retrying (try %d)", "[abcde]", 5, 2)
+		logf("wg: [v2] %s - Sending handshake initiation", "[abcde]")
+		logf("wg: [v2] %s - Handshake did not complete after %d seconds, retrying (try %d)", "[abcde]", 5, 3)
+
+		// Every line still reaches the wrapped logger.
+		require.Len(t, logged, 3)
+		require.EqualValues(t, 2, telemetry.newEvent().HandshakeRetries)
+	})
+
 	t.Run("CleanIPs", func(t *testing.T) {
 		t.Parallel()
 
@@ -144,6 +144,11 @@ func (*client) NewConn(initCtx context.Context, serverURL *url.URL, token string
 		DERPForceWebSockets: connInfo.DERPForceWebSockets,
 		Logger:              options.Logger,
 		BlockEndpoints:      connInfo.DisableDirectConnections,
+		HTTPSFallback:       connInfo.HTTPSFallback,
+		NetcheckInterval:    connInfo.NetcheckInterval,
+		MTU:                 connInfo.WireguardMTU,
+		KeepaliveInterval:   connInfo.WireguardKeepaliveInterval,
+		HandshakeTimeout:    connInfo.WireguardHandshakeTimeout,
 		DNSConfigurator:     options.DNSConfigurator,
 		Router:              options.Router,
 		TUNDev:              options.TUNDevice,